@@ -0,0 +1,267 @@
+// Package apperrors provides typed application errors with stable machine-readable codes.
+//
+// Repositories and services return errors created by this package so that the
+// HTTP layer can classify them with errors.Is instead of matching on messages.
+package apperrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Error kinds. Every Error wraps exactly one of these, so callers can test
+// the class of a failure with errors.Is(err, apperrors.ErrNotFound).
+var (
+	// ErrNotFound indicates that the requested resource does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrConflict indicates that the request conflicts with the current state of a resource
+	ErrConflict = errors.New("conflict")
+	// ErrValidation indicates that the request is malformed or semantically invalid
+	ErrValidation = errors.New("validation failed")
+	// ErrUnauthorized indicates that the caller is not authenticated
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden indicates that the caller is authenticated but not allowed to perform the action
+	ErrForbidden = errors.New("forbidden")
+	// ErrGone indicates that the resource or API version has been permanently retired
+	ErrGone = errors.New("gone")
+	// ErrPreconditionFailed indicates that a conditional request no longer matches the resource, e.g. a stale If-Match
+	ErrPreconditionFailed = errors.New("precondition failed")
+	// ErrPreconditionRequired indicates that the request must be made conditional, e.g. with If-Match
+	ErrPreconditionRequired = errors.New("precondition required")
+	// ErrBadGateway indicates that an upstream service, e.g. a git provider, failed or returned an invalid response
+	ErrBadGateway = errors.New("bad gateway")
+	// ErrPayloadTooLarge indicates that the request body is larger than its route accepts
+	ErrPayloadTooLarge = errors.New("payload too large")
+	// ErrServiceUnavailable indicates that a dependency the request needs is unavailable, e.g. after a degraded startup
+	ErrServiceUnavailable = errors.New("service unavailable")
+)
+
+// Stable machine-readable error codes returned to API clients
+const (
+	CodeInternal                = "internal_error"
+	CodeNotFound                = "not_found"
+	CodeConflict                = "conflict"
+	CodeValidation              = "validation_failed"
+	CodeUnauthorized            = "unauthorized"
+	CodeForbidden               = "forbidden"
+	CodeGone                    = "gone"
+	CodePreconditionFailed      = "precondition_failed"
+	CodePreconditionRequired    = "precondition_required"
+	CodeBadGateway              = "bad_gateway"
+	CodePayloadTooLarge         = "payload_too_large"
+	CodeServiceUnavailable      = "service_unavailable"
+	CodeTimeout                 = "timeout"
+	CodeInvalidRequest          = "invalid_request"
+	CodeMissingValidatedRequest = "missing_validated_request"
+
+	CodeProjectNotFound         = "project_not_found"
+	CodeProjectExists           = "project_already_exists"
+	CodeProjectArchived         = "project_archived"
+	CodeProjectTemplateNotFound = "project_template_not_found"
+	CodeCodebaseNotFound        = "codebase_not_found"
+	CodeCodebaseExists          = "codebase_already_exists"
+	CodeIngestionBlocked        = "ingestion_blocked"
+	CodeCodebaseConfigNotFound  = "codebase_config_not_found"
+	CodeCodebaseConfigExists    = "codebase_config_already_exists"
+	CodeGitRefNotFound          = "git_ref_not_found"
+	CodeAgentNotFound           = "agent_not_found"
+	CodeAgentExists             = "agent_already_exists"
+	CodeAgentSyncUnsupported    = "agent_sync_unsupported"
+	CodeAgentSyncInProgress     = "agent_sync_in_progress"
+	CodeRetrievalUnsupported    = "retrieval_unsupported"
+	CodeAgentSetupNotFound      = "agent_setup_not_found"
+	CodeAgentSetupNotFailed     = "agent_setup_not_failed"
+	CodeTaskNotFound            = "task_not_found"
+	CodeTaskNotPending          = "task_not_pending"
+	CodeTaskNotStuck            = "task_not_stuck"
+	CodeTaskNotCompleted        = "task_not_completed"
+	CodeInvalidTaskTransition   = "invalid_task_transition"
+	CodeBatchNotFound           = "batch_not_found"
+	CodeCampaignNotFound        = "campaign_not_found"
+	CodeChannelNotFound         = "notification_channel_not_found"
+	CodeNotificationNotFound    = "notification_not_found"
+	CodeCommentNotFound         = "task_comment_not_found"
+	CodeTagKeyNotFound          = "tag_key_not_found"
+	CodeTagKeyExists            = "tag_key_already_exists"
+	CodeInvalidTagKey           = "invalid_tag_key"
+	CodeInvalidTag              = "invalid_tag"
+	CodeTagRestricted           = "tag_restricted"
+	CodeUserNotFound            = "user_not_found"
+	CodeUserExists              = "user_already_exists"
+	CodeLastOwner               = "last_owner"
+	CodeInvalidPassword         = "invalid_password"
+	CodeDeletionNotConfirmed    = "deletion_not_confirmed"
+	CodeInvalidMFACode          = "invalid_mfa_code"
+	CodeInvalidRecoveryCode     = "invalid_recovery_code"
+	CodeMFANotEnabled           = "mfa_not_enabled"
+	CodeUserCodeNotFound        = "user_code_not_found"
+	CodeAuthorizationPending    = "authorization_pending"
+	CodeSlowDown                = "slow_down"
+	CodeAccessDenied            = "access_denied"
+	CodeExpiredToken            = "expired_token"
+	CodeInvalidGrant            = "invalid_grant"
+	CodeRoleNotFound            = "role_not_found"
+	CodeRoleExists              = "role_already_exists"
+	CodeRoleInUse               = "role_in_use"
+	CodeBuiltInRole             = "built_in_role"
+	CodeUnknownPermission       = "unknown_permission"
+	CodeProjectMemberNotFound   = "project_member_not_found"
+	CodeProjectOwner            = "project_owner"
+	CodePermissionDenied        = "permission_denied"
+	CodeAuthenticationFailed    = "authentication_failed"
+	CodeInvalidToken            = "invalid_token"
+	CodeInvalidProvider         = "invalid_provider"
+	CodeInvalidEmbedding        = "invalid_embedding"
+	CodeInvalidRedaction        = "invalid_redaction_policy"
+	CodeEvalRunNotFound         = "eval_run_not_found"
+	CodeUnknownEvalCase         = "unknown_eval_case"
+	CodeUnknownPromptVersion    = "unknown_prompt_version"
+	CodeExperimentNotFound      = "experiment_not_found"
+	CodeExperimentOverlap       = "experiment_overlap"
+	CodeExperimentStopped       = "experiment_stopped"
+	CodeGitHubAppNotFound       = "github_app_not_found"
+	CodeInvalidSignature        = "invalid_signature"
+	CodeJiraSiteNotFound        = "jira_site_not_found"
+	CodeJiraIssueNotFound       = "jira_issue_not_found"
+	CodeClientTokenConflict     = "client_token_conflict"
+	CodeServiceAccountNotFound  = "service_account_not_found"
+	CodeServiceTokenNotFound    = "service_account_token_not_found"
+	CodeStoragePurgeNotFound    = "storage_purge_not_found"
+	CodeAPIVersionSunset        = "api_version_sunset"
+	CodeVersionMismatch         = "version_mismatch"
+	CodeTaskHasNoDiff           = "task_has_no_diff"
+	CodeInvalidModelAnswer      = "invalid_model_answer"
+	CodeUploadNotFound          = "upload_not_found"
+	CodeArchiveNotUploaded      = "archive_not_uploaded"
+	CodeInvalidArchive          = "invalid_archive"
+	CodeInvalidCheckout         = "invalid_checkout"
+	CodeFindingNotFound         = "finding_not_found"
+	CodeFindingExists           = "finding_already_exists"
+	CodeBaselineNotFound        = "baseline_not_found"
+	CodeJustificationRequired   = "justification_required"
+	CodeInvalidQualityGate      = "invalid_quality_gate"
+	CodeCodeOwnersNotFound      = "code_owners_not_found"
+)
+
+// Error is a classified application error
+type Error struct {
+	// Kind is one of the package-level sentinel errors
+	Kind error
+	// Code is a stable machine-readable identifier for the failure
+	Code string
+	// Message is a human-readable description of the failure
+	Message string
+	// Err is the underlying cause, if any
+	Err error
+}
+
+// Error implements the error interface. The message matches what the
+// equivalent fmt.Errorf call would have produced.
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+// Unwrap exposes both the kind and the underlying cause to errors.Is and errors.As
+func (e *Error) Unwrap() []error {
+	if e.Err != nil {
+		return []error{e.Kind, e.Err}
+	}
+	return []error{e.Kind}
+}
+
+// New creates an Error of the given kind
+func New(kind error, code string, format string, args ...any) error {
+	return &Error{Kind: kind, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// Wrap creates an Error of the given kind that wraps an underlying cause
+func Wrap(kind error, code string, err error, format string, args ...any) error {
+	return &Error{Kind: kind, Code: code, Message: fmt.Sprintf(format, args...), Err: err}
+}
+
+// NotFound creates an ErrNotFound error
+func NotFound(code string, format string, args ...any) error {
+	return New(ErrNotFound, code, format, args...)
+}
+
+// Conflict creates an ErrConflict error
+func Conflict(code string, format string, args ...any) error {
+	return New(ErrConflict, code, format, args...)
+}
+
+// Validation creates an ErrValidation error
+func Validation(code string, format string, args ...any) error {
+	return New(ErrValidation, code, format, args...)
+}
+
+// Unauthorized creates an ErrUnauthorized error
+func Unauthorized(code string, format string, args ...any) error {
+	return New(ErrUnauthorized, code, format, args...)
+}
+
+// Forbidden creates an ErrForbidden error
+func Forbidden(code string, format string, args ...any) error {
+	return New(ErrForbidden, code, format, args...)
+}
+
+// PreconditionFailed creates an ErrPreconditionFailed error
+func PreconditionFailed(code string, format string, args ...any) error {
+	return New(ErrPreconditionFailed, code, format, args...)
+}
+
+// BadGateway creates an ErrBadGateway error that wraps the upstream failure
+func BadGateway(code string, err error, format string, args ...any) error {
+	return Wrap(ErrBadGateway, code, err, format, args...)
+}
+
+// PayloadTooLarge creates an ErrPayloadTooLarge error for a body over the limit of its route, in bytes
+func PayloadTooLarge(limit int64) error {
+	return New(ErrPayloadTooLarge, CodePayloadTooLarge, "request body is larger than %d bytes", limit)
+}
+
+// ServiceUnavailable creates an ErrServiceUnavailable error
+func ServiceUnavailable(code string, format string, args ...any) error {
+	return New(ErrServiceUnavailable, code, format, args...)
+}
+
+// CodeOf returns the machine-readable code for err. Errors that were not
+// created by this package report CodeInternal, or CodeTimeout when a deadline passed.
+func CodeOf(err error) string {
+	var appErr *Error
+	if errors.As(err, &appErr) && appErr.Code != "" {
+		return appErr.Code
+	}
+
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return CodeNotFound
+	case errors.Is(err, ErrConflict):
+		return CodeConflict
+	case errors.Is(err, ErrValidation):
+		return CodeValidation
+	case errors.Is(err, ErrUnauthorized):
+		return CodeUnauthorized
+	case errors.Is(err, ErrForbidden):
+		return CodeForbidden
+	case errors.Is(err, ErrGone):
+		return CodeGone
+	case errors.Is(err, ErrPreconditionFailed):
+		return CodePreconditionFailed
+	case errors.Is(err, ErrPreconditionRequired):
+		return CodePreconditionRequired
+	case errors.Is(err, ErrBadGateway):
+		return CodeBadGateway
+	case errors.Is(err, ErrPayloadTooLarge):
+		return CodePayloadTooLarge
+	case errors.Is(err, ErrServiceUnavailable):
+		return CodeServiceUnavailable
+	case errors.Is(err, context.DeadlineExceeded):
+		return CodeTimeout
+	default:
+		return CodeInternal
+	}
+}
@@ -0,0 +1,46 @@
+package apperrors
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError_MessageAndKind(t *testing.T) {
+	err := NotFound(CodeTaskNotFound, "task not found: %s", "task-1")
+
+	assert.EqualError(t, err, "task not found: task-1")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NotErrorIs(t, err, ErrConflict)
+	assert.Equal(t, CodeTaskNotFound, CodeOf(err))
+}
+
+func TestWrap_PreservesCause(t *testing.T) {
+	err := Wrap(ErrUnauthorized, CodeInvalidToken, sql.ErrNoRows, "token validation failed")
+
+	assert.EqualError(t, err, "token validation failed: sql: no rows in result set")
+	assert.ErrorIs(t, err, ErrUnauthorized)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestCodeOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{name: "wrapped_typed_error", err: fmt.Errorf("failed to get agent: %w", NotFound(CodeAgentNotFound, "agent not found")), expected: CodeAgentNotFound},
+		{name: "bare_kind", err: fmt.Errorf("lookup: %w", ErrConflict), expected: CodeConflict},
+		{name: "empty_code_falls_back_to_kind", err: New(ErrValidation, "", "bad input"), expected: CodeValidation},
+		{name: "untyped_error", err: errors.New("boom"), expected: CodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, CodeOf(tt.err))
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
 	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
 	"github.com/kazemisoroush/code-refactoring-tool/api/models"
 	"github.com/kazemisoroush/code-refactoring-tool/api/services"
@@ -30,9 +31,9 @@ func NewAgentController(agentService services.AgentService) *AgentController {
 // @Produce json
 // @Param request body models.CreateAgentRequest true "Agent creation request"
 // @Success 201 {object} models.CreateAgentResponse "Agent created successfully"
-// @Failure 400 {object} models.ErrorResponse "Invalid request"
-// @Failure 500 {object} models.ErrorResponse "Internal server error"
-// @Router /agents [post]
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/agents [post]
 func (c *AgentController) CreateAgent(ctx *gin.Context) {
 	// Try to get the validated request from context first (new pattern)
 	request, exists := middleware.GetValidatedRequest[models.CreateAgentRequest](ctx)
@@ -40,12 +41,7 @@ func (c *AgentController) CreateAgent(ctx *gin.Context) {
 		// Fall back to manual binding for backward compatibility
 		var requestData models.CreateAgentRequest
 		if err := ctx.ShouldBindJSON(&requestData); err != nil {
-			errorResponse := models.ErrorResponse{
-				Code:    http.StatusBadRequest,
-				Message: "Invalid request body",
-				Details: err.Error(),
-			}
-			ctx.JSON(http.StatusBadRequest, errorResponse)
+			respondWithError(ctx, middleware.BodyError(err, "invalid request"))
 			return
 		}
 		request = requestData
@@ -54,12 +50,7 @@ func (c *AgentController) CreateAgent(ctx *gin.Context) {
 	// Call the service to create the agent
 	response, err := c.agentService.CreateAgent(ctx.Request.Context(), request)
 	if err != nil {
-		errorResponse := models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "Failed to create agent",
-			Details: err.Error(),
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse)
+		respondWithError(ctx, err)
 		return
 	}
 
@@ -71,12 +62,13 @@ func (c *AgentController) CreateAgent(ctx *gin.Context) {
 // @Description Retrieve agent information by agent ID
 // @Tags agents
 // @Produce json
-// @Param id path string true "Agent ID"
+// @Param agent_id path string true "Agent ID"
+// @Param fields query string false "Comma-separated JSON fields to return; dotted paths select nested fields"
 // @Success 200 {object} models.GetAgentResponse "Agent found"
-// @Failure 400 {object} models.ErrorResponse "Invalid agent ID"
-// @Failure 404 {object} models.ErrorResponse "Agent not found"
-// @Failure 500 {object} models.ErrorResponse "Internal server error"
-// @Router /agents/{id} [get]
+// @Failure 400 {object} models.ProblemDetails "Invalid agent ID"
+// @Failure 404 {object} models.ProblemDetails "Agent not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/agents/{agent_id} [get]
 func (c *AgentController) GetAgent(ctx *gin.Context) {
 	// Try to get the validated request from context first (new pattern)
 	request, exists := middleware.GetValidatedRequest[models.GetAgentRequest](ctx)
@@ -85,11 +77,7 @@ func (c *AgentController) GetAgent(ctx *gin.Context) {
 		// Fall back to manual parameter extraction for backward compatibility
 		agentID = ctx.Param("id")
 		if agentID == "" {
-			errorResponse := models.ErrorResponse{
-				Code:    http.StatusBadRequest,
-				Message: "Agent ID is required",
-			}
-			ctx.JSON(http.StatusBadRequest, errorResponse)
+			respondWithError(ctx, apperrors.Validation(apperrors.CodeInvalidRequest, "agent ID is required"))
 			return
 		}
 	} else {
@@ -98,24 +86,33 @@ func (c *AgentController) GetAgent(ctx *gin.Context) {
 
 	response, err := c.agentService.GetAgent(ctx.Request.Context(), agentID)
 	if err != nil {
-		var statusCode int
-		var message string
+		respondWithError(ctx, err)
+		return
+	}
 
-		// Check if it's a "not found" error
-		if err.Error() == "agent not found" {
-			statusCode = http.StatusNotFound
-			message = "Agent not found"
-		} else {
-			statusCode = http.StatusInternalServerError
-			message = "Failed to get agent"
-		}
+	respondWithFields(ctx, http.StatusOK, response)
+}
 
-		errorResponse := models.ErrorResponse{
-			Code:    statusCode,
-			Message: message,
-			Details: err.Error(),
-		}
-		ctx.JSON(statusCode, errorResponse)
+// RebuildAgent handles POST /agents/:agent_id/rebuild
+// @Summary Rebuild an agent
+// @Description Re-provision an agent's AI infrastructure using its current repository, branch and provider
+// @Tags agents
+// @Produce json
+// @Param agent_id path string true "Agent ID"
+// @Success 200 {object} models.UpdateAgentResponse "Agent rebuilt successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/agents/{agent_id}/rebuild [post]
+func (c *AgentController) RebuildAgent(ctx *gin.Context) {
+	agentID := ctx.Param("agent_id")
+	if agentID == "" {
+		respondWithError(ctx, apperrors.Validation(apperrors.CodeInvalidRequest, "agent ID is required"))
+		return
+	}
+
+	response, err := c.agentService.RebuildAgent(ctx.Request.Context(), agentID)
+	if err != nil {
+		respondWithError(ctx, err)
 		return
 	}
 
@@ -127,12 +124,12 @@ func (c *AgentController) GetAgent(ctx *gin.Context) {
 // @Description Delete an agent and its associated resources
 // @Tags agents
 // @Produce json
-// @Param id path string true "Agent ID"
+// @Param agent_id path string true "Agent ID"
 // @Success 200 {object} models.DeleteAgentResponse "Agent deleted successfully"
-// @Failure 400 {object} models.ErrorResponse "Invalid agent ID"
-// @Failure 404 {object} models.ErrorResponse "Agent not found"
-// @Failure 500 {object} models.ErrorResponse "Internal server error"
-// @Router /agents/{id} [delete]
+// @Failure 400 {object} models.ProblemDetails "Invalid agent ID"
+// @Failure 404 {object} models.ProblemDetails "Agent not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/agents/{agent_id} [delete]
 func (c *AgentController) DeleteAgent(ctx *gin.Context) {
 	// Try to get the validated request from context first (new pattern)
 	request, exists := middleware.GetValidatedRequest[models.DeleteAgentRequest](ctx)
@@ -141,11 +138,7 @@ func (c *AgentController) DeleteAgent(ctx *gin.Context) {
 		// Fall back to manual parameter extraction for backward compatibility
 		agentID = ctx.Param("id")
 		if agentID == "" {
-			errorResponse := models.ErrorResponse{
-				Code:    http.StatusBadRequest,
-				Message: "Agent ID is required",
-			}
-			ctx.JSON(http.StatusBadRequest, errorResponse)
+			respondWithError(ctx, apperrors.Validation(apperrors.CodeInvalidRequest, "agent ID is required"))
 			return
 		}
 	} else {
@@ -154,24 +147,7 @@ func (c *AgentController) DeleteAgent(ctx *gin.Context) {
 
 	response, err := c.agentService.DeleteAgent(ctx.Request.Context(), agentID)
 	if err != nil {
-		var statusCode int
-		var message string
-
-		// Check if it's a "not found" error
-		if err.Error() == "agent not found" {
-			statusCode = http.StatusNotFound
-			message = "Agent not found"
-		} else {
-			statusCode = http.StatusInternalServerError
-			message = "Failed to delete agent"
-		}
-
-		errorResponse := models.ErrorResponse{
-			Code:    statusCode,
-			Message: message,
-			Details: err.Error(),
-		}
-		ctx.JSON(statusCode, errorResponse)
+		respondWithError(ctx, err)
 		return
 	}
 
@@ -185,10 +161,11 @@ func (c *AgentController) DeleteAgent(ctx *gin.Context) {
 // @Produce json
 // @Param next_token query string false "Token for pagination"
 // @Param max_results query int false "Maximum number of results to return" minimum(1) maximum(100)
+// @Param fields query string false "Comma-separated JSON fields to return; dotted paths select nested fields"
 // @Success 200 {object} models.ListAgentsResponse "List of agents"
-// @Failure 400 {object} models.ErrorResponse "Invalid request parameters"
-// @Failure 500 {object} models.ErrorResponse "Internal server error"
-// @Router /agents [get]
+// @Failure 400 {object} models.ProblemDetails "Invalid request parameters"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/agents [get]
 func (c *AgentController) ListAgents(ctx *gin.Context) {
 	// Try to get the validated request from context first (new pattern)
 	request, exists := middleware.GetValidatedRequest[models.ListAgentsRequest](ctx)
@@ -196,12 +173,7 @@ func (c *AgentController) ListAgents(ctx *gin.Context) {
 		// Fall back to manual binding for backward compatibility
 		var requestData models.ListAgentsRequest
 		if err := ctx.ShouldBindQuery(&requestData); err != nil {
-			errorResponse := models.ErrorResponse{
-				Code:    http.StatusBadRequest,
-				Message: "Invalid query parameters",
-				Details: err.Error(),
-			}
-			ctx.JSON(http.StatusBadRequest, errorResponse)
+			respondWithError(ctx, middleware.BodyError(err, "invalid request"))
 			return
 		}
 		request = requestData
@@ -209,16 +181,11 @@ func (c *AgentController) ListAgents(ctx *gin.Context) {
 
 	response, err := c.agentService.ListAgents(ctx.Request.Context(), request)
 	if err != nil {
-		errorResponse := models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "Failed to list agents",
-			Details: err.Error(),
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse)
+		respondWithError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response)
+	respondWithListFields(ctx, http.StatusOK, response, "agents")
 }
 
 // UpdateAgent handles PUT /agents/:agent_id
@@ -230,19 +197,14 @@ func (c *AgentController) ListAgents(ctx *gin.Context) {
 // @Param agent_id path string true "Agent ID"
 // @Param request body models.UpdateAgentRequest true "Agent update request"
 // @Success 200 {object} models.UpdateAgentResponse "Agent updated successfully"
-// @Failure 400 {object} models.ErrorResponse "Invalid request"
-// @Failure 404 {object} models.ErrorResponse "Agent not found"
-// @Failure 500 {object} models.ErrorResponse "Internal server error"
-// @Router /agents/{agent_id} [put]
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 404 {object} models.ProblemDetails "Agent not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/agents/{agent_id} [put]
 func (c *AgentController) UpdateAgent(ctx *gin.Context) {
 	agentID := ctx.Param("agent_id")
 	if agentID == "" {
-		errorResponse := models.ErrorResponse{
-			Code:    http.StatusBadRequest,
-			Message: "Agent ID is required",
-			Details: "Agent ID must be provided in the URL path",
-		}
-		ctx.JSON(http.StatusBadRequest, errorResponse)
+		respondWithError(ctx, apperrors.Validation(apperrors.CodeInvalidRequest, "agent ID is required"))
 		return
 	}
 
@@ -252,12 +214,7 @@ func (c *AgentController) UpdateAgent(ctx *gin.Context) {
 		// Fall back to manual binding for backward compatibility
 		var requestData models.UpdateAgentRequest
 		if err := ctx.ShouldBindJSON(&requestData); err != nil {
-			errorResponse := models.ErrorResponse{
-				Code:    http.StatusBadRequest,
-				Message: "Invalid request body",
-				Details: err.Error(),
-			}
-			ctx.JSON(http.StatusBadRequest, errorResponse)
+			respondWithError(ctx, middleware.BodyError(err, "invalid request"))
 			return
 		}
 		request = requestData
@@ -268,12 +225,7 @@ func (c *AgentController) UpdateAgent(ctx *gin.Context) {
 
 	response, err := c.agentService.UpdateAgent(ctx.Request.Context(), request)
 	if err != nil {
-		errorResponse := models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "Failed to update agent",
-			Details: err.Error(),
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse)
+		respondWithError(ctx, err)
 		return
 	}
 
@@ -14,6 +14,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
 	"github.com/kazemisoroush/code-refactoring-tool/api/models"
 	servicesMocks "github.com/kazemisoroush/code-refactoring-tool/api/services/mocks"
 )
@@ -94,6 +95,7 @@ func TestAgentController_CreateAgent_InvalidRequest(t *testing.T) {
 
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
+	router.Use(middleware.NewErrorHandlerMiddleware().Handle())
 	router.POST("/agent/create", controller.CreateAgent)
 
 	req := httptest.NewRequest(http.MethodPost, "/agent/create", bytes.NewReader([]byte(invalidJSON)))
@@ -104,12 +106,13 @@ func TestAgentController_CreateAgent_InvalidRequest(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	var errorResponse models.ErrorResponse
+	var errorResponse models.ProblemDetails
 	err := json.Unmarshal(w.Body.Bytes(), &errorResponse)
 	require.NoError(t, err)
 
-	assert.Equal(t, http.StatusBadRequest, errorResponse.Code)
-	assert.Equal(t, "Invalid request body", errorResponse.Message)
+	assert.Equal(t, models.ProblemContentType, w.Header().Get("Content-Type"))
+	assert.Equal(t, http.StatusBadRequest, errorResponse.Status)
+	assert.Equal(t, "invalid_request", errorResponse.Code)
 }
 
 func TestAgentController_CreateAgent_ServiceError(t *testing.T) {
@@ -134,6 +137,7 @@ func TestAgentController_CreateAgent_ServiceError(t *testing.T) {
 
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
+	router.Use(middleware.NewErrorHandlerMiddleware().Handle())
 	router.POST("/agent/create", controller.CreateAgent)
 
 	req := httptest.NewRequest(http.MethodPost, "/agent/create", bytes.NewReader(reqBody))
@@ -144,12 +148,13 @@ func TestAgentController_CreateAgent_ServiceError(t *testing.T) {
 
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 
-	var errorResponse models.ErrorResponse
+	var errorResponse models.ProblemDetails
 	err = json.Unmarshal(w.Body.Bytes(), &errorResponse)
 	require.NoError(t, err)
 
-	assert.Equal(t, http.StatusInternalServerError, errorResponse.Code)
-	assert.Equal(t, "Failed to create agent", errorResponse.Message)
+	assert.Equal(t, http.StatusInternalServerError, errorResponse.Status)
+	assert.Equal(t, "internal_error", errorResponse.Code)
+	assert.Equal(t, "service error", errorResponse.Detail)
 }
 
 func TestAgentController_GetAgent_Success(t *testing.T) {
@@ -275,3 +280,33 @@ func TestAgentController_ListAgents_Success(t *testing.T) {
 	assert.Len(t, response.Agents, 1)
 	assert.Equal(t, expectedResponse.Agents[0].AgentID, response.Agents[0].AgentID)
 }
+
+func TestAgentController_RebuildAgent_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := servicesMocks.NewMockAgentService(ctrl)
+	controller := NewAgentController(mockService)
+
+	agentID := "agent-123"
+	mockService.EXPECT().
+		RebuildAgent(gomock.Any(), agentID).
+		Return(&models.UpdateAgentResponse{AgentID: agentID, KnowledgeBaseID: "kb-new"}, nil).
+		Times(1)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/agents/:agent_id/rebuild", controller.RebuildAgent)
+
+	req := httptest.NewRequest(http.MethodPost, "/agents/"+agentID+"/rebuild", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.UpdateAgentResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+	assert.Equal(t, "kb-new", response.KnowledgeBaseID)
+}
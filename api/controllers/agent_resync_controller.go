@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/services"
+)
+
+// AgentResyncController handles project agent resync settings HTTP requests
+type AgentResyncController struct {
+	agentResyncService services.AgentResyncService
+}
+
+// NewAgentResyncController creates a new AgentResyncController
+func NewAgentResyncController(agentResyncService services.AgentResyncService) *AgentResyncController {
+	return &AgentResyncController{
+		agentResyncService: agentResyncService,
+	}
+}
+
+// GetAgentResyncSettings handles GET /projects/:project_id/agent-resync
+// @Summary Get a project's agent resync settings
+// @Description Get whether new commits on the default branch of the project's codebases resync the agents built from them. Projects without settings are opted in.
+// @Tags projects
+// @Produce json
+// @Param project_id path string true "Project ID"
+// @Success 200 {object} models.AgentResyncSettings "Agent resync settings retrieved successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 404 {object} models.ProblemDetails "Project not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/projects/{project_id}/agent-resync [get]
+func (c *AgentResyncController) GetAgentResyncSettings(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.GetAgentResyncSettingsRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.agentResyncService.GetSettings(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, response)
+}
+
+// UpdateAgentResyncSettings handles PUT /projects/:project_id/agent-resync
+// @Summary Update a project's agent resync settings
+// @Description Opt a project in or out of resyncing its agents when their codebase's default branch changes. Commits pushed while opted out don't resync the agents later.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Param project_id path string true "Project ID"
+// @Param request body models.UpdateAgentResyncSettingsRequest true "Agent resync settings update request"
+// @Success 200 {object} models.AgentResyncSettings "Agent resync settings updated successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 404 {object} models.ProblemDetails "Project not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/projects/{project_id}/agent-resync [put]
+func (c *AgentResyncController) UpdateAgentResyncSettings(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.UpdateAgentResyncSettingsRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.agentResyncService.UpdateSettings(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, response)
+}
@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/services"
+)
+
+// AgentRetrievalController handles the HTTP requests debugging the context agents retrieve
+type AgentRetrievalController struct {
+	agentRetrievalService services.AgentRetrievalService
+}
+
+// NewAgentRetrievalController creates a new AgentRetrievalController
+func NewAgentRetrievalController(agentRetrievalService services.AgentRetrievalService) *AgentRetrievalController {
+	return &AgentRetrievalController{
+		agentRetrievalService: agentRetrievalService,
+	}
+}
+
+// RetrieveChunks handles POST /agents/:agent_id/retrieve
+// @Summary Retrieve an agent's context for a query
+// @Description Run only the retrieval step of an agent for a query and return the chunks of its repository it would be given as context, most relevant first, with their scores, source files and line ranges. Use it to debug wrong answers. Restricted to the members of the agent's project.
+// @Tags agents
+// @Accept json
+// @Produce json
+// @Param agent_id path string true "Agent ID"
+// @Param request body models.RetrieveAgentChunksRequest true "Query to retrieve context for"
+// @Success 200 {object} models.RetrieveAgentChunksResponse "Chunks retrieved successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request or agent without a knowledge base to retrieve from"
+// @Failure 401 {object} models.ProblemDetails "Authentication is required"
+// @Failure 403 {object} models.ProblemDetails "Caller can't read the agents of the project"
+// @Failure 404 {object} models.ProblemDetails "Agent not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/agents/{agent_id}/retrieve [post]
+func (c *AgentRetrievalController) RetrieveChunks(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.RetrieveAgentChunksRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+	request.UserID = middleware.GetUserID(ctx)
+
+	response, err := c.agentRetrievalService.RetrieveChunks(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, response)
+}
@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/services"
+)
+
+// AgentSetupController handles HTTP requests for the setups that provision agent infrastructure
+type AgentSetupController struct {
+	agentService services.AgentService
+}
+
+// NewAgentSetupController creates a new AgentSetupController
+func NewAgentSetupController(agentService services.AgentService) *AgentSetupController {
+	return &AgentSetupController{
+		agentService: agentService,
+	}
+}
+
+// ListAgentSetups handles GET /agent-setups
+// @Summary List agent setups
+// @Description List the setups that provisioned agent infrastructure when agents were created, updated or rebuilt, most recent first, with the progress of their steps.
+// @Tags agents
+// @Produce json
+// @Param status query string false "Only list setups in this status" Enums(running, completed, failed, rolled_back, rollback_failed)
+// @Success 200 {object} models.ListAgentSetupsResponse "Agent setups retrieved successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/agent-setups [get]
+func (c *AgentSetupController) ListAgentSetups(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.ListAgentSetupsRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.agentService.ListAgentSetups(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, response)
+}
+
+// GetAgentSetup handles GET /agent-setups/:setup_id
+// @Summary Get an agent setup
+// @Description Get an agent setup with the status, attempts and last error of each of its steps. Failed agent creations, updates and rebuilds report the ID of their setup.
+// @Tags agents
+// @Produce json
+// @Param setup_id path string true "Setup ID"
+// @Success 200 {object} models.AgentSetup "Agent setup retrieved successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 404 {object} models.ProblemDetails "Agent setup not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/agent-setups/{setup_id} [get]
+func (c *AgentSetupController) GetAgentSetup(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.GetAgentSetupRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.agentService.GetAgentSetup(ctx.Request.Context(), request.SetupID)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, response)
+}
+
+// ResumeAgentSetup handles POST /agent-setups/:setup_id/resume
+// @Summary Resume a failed agent setup
+// @Description Run a failed setup again, skipping the steps it completed and retrying the one that failed, then save the agent it provisions. Resources the completed steps created, such as knowledge bases, are reused.
+// @Tags agents
+// @Produce json
+// @Param setup_id path string true "Setup ID"
+// @Success 200 {object} models.AgentSetup "Agent setup completed"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 404 {object} models.ProblemDetails "Agent setup not found"
+// @Failure 409 {object} models.ProblemDetails "Agent setup didn't fail or its repository was blocked by the ingestion scan"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/agent-setups/{setup_id}/resume [post]
+func (c *AgentSetupController) ResumeAgentSetup(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.ResumeAgentSetupRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.agentService.ResumeAgentSetup(ctx.Request.Context(), request.SetupID)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, response)
+}
+
+// TearDownAgentSetup handles POST /agent-setups/:setup_id/teardown
+// @Summary Tear down a failed agent setup
+// @Description Delete the resources a failed setup created, such as its vector store, knowledge base and agent, in reverse order. The setup ends rolled back, or rollback_failed when a resource couldn't be deleted, and can no longer be resumed.
+// @Tags agents
+// @Produce json
+// @Param setup_id path string true "Setup ID"
+// @Success 200 {object} models.AgentSetup "Agent setup torn down"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 404 {object} models.ProblemDetails "Agent setup not found"
+// @Failure 409 {object} models.ProblemDetails "Agent setup didn't fail"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/agent-setups/{setup_id}/teardown [post]
+func (c *AgentSetupController) TearDownAgentSetup(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.TearDownAgentSetupRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.agentService.TearDownAgentSetup(ctx.Request.Context(), request.SetupID)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, response)
+}
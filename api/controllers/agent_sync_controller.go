@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/services"
+)
+
+// AgentSyncController handles agent knowledge base sync HTTP requests
+type AgentSyncController struct {
+	agentSyncService services.AgentSyncService
+}
+
+// NewAgentSyncController creates a new AgentSyncController
+func NewAgentSyncController(agentSyncService services.AgentSyncService) *AgentSyncController {
+	return &AgentSyncController{
+		agentSyncService: agentSyncService,
+	}
+}
+
+// GetSyncStatus handles GET /agents/:agent_id/sync-status
+// @Summary Get an agent's knowledge base sync status
+// @Description Get the last successful sync time of an agent's knowledge base and the document counts and failures of its recent syncs, most recent first. Poll it to follow the progress of a running sync.
+// @Tags agents
+// @Produce json
+// @Param agent_id path string true "Agent ID"
+// @Param limit query int false "Maximum number of recent syncs (default 10, max 50)"
+// @Success 200 {object} models.AgentSyncStatus "Sync status retrieved successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request or agent without a knowledge base to sync"
+// @Failure 404 {object} models.ProblemDetails "Agent not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/agents/{agent_id}/sync-status [get]
+func (c *AgentSyncController) GetSyncStatus(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.GetAgentSyncStatusRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.agentSyncService.GetSyncStatus(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, response)
+}
+
+// SyncAgent handles POST /agents/:agent_id/sync
+// @Summary Resync an agent's knowledge base
+// @Description Start ingesting an agent's repository into its knowledge base again. The sync runs in the background; follow its progress with the sync status endpoint.
+// @Tags agents
+// @Produce json
+// @Param agent_id path string true "Agent ID"
+// @Success 202 {object} models.AgentSyncStatus "Sync started"
+// @Failure 400 {object} models.ProblemDetails "Invalid request or agent without a knowledge base to sync"
+// @Failure 404 {object} models.ProblemDetails "Agent not found"
+// @Failure 409 {object} models.ProblemDetails "A sync is already running"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/agents/{agent_id}/sync [post]
+func (c *AgentSyncController) SyncAgent(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.SyncAgentRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.agentSyncService.SyncAgent(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusAccepted, response)
+}
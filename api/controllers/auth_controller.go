@@ -1,11 +1,13 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
-	"strings"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
 	"github.com/kazemisoroush/code-refactoring-tool/api/models"
 	"github.com/kazemisoroush/code-refactoring-tool/api/services"
 )
@@ -30,38 +32,21 @@ func NewAuthController(authService services.AuthService) *AuthController {
 // @Produce json
 // @Param request body models.SignUpRequest true "Sign up request"
 // @Success 201 {object} models.SignUpResponse
-// @Failure 400 {object} models.ErrorResponse
-// @Failure 409 {object} models.ErrorResponse
-// @Failure 500 {object} models.ErrorResponse
+// @Failure 400 {object} models.ProblemDetails
+// @Failure 409 {object} models.ProblemDetails
+// @Failure 500 {object} models.ProblemDetails
 // @Router /auth/signup [post]
 func (c *AuthController) SignUp(ctx *gin.Context) {
-	var req models.SignUpRequest
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Code:    http.StatusBadRequest,
-			Message: "Invalid request body",
-			Details: err.Error(),
-		})
+	// Get the validated request from context (set by validation middleware)
+	req, exists := middleware.GetValidatedRequest[models.SignUpRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
 		return
 	}
 
 	response, err := c.authService.SignUp(ctx.Request.Context(), &req)
 	if err != nil {
-		// Check if it's a user already exists error
-		if strings.Contains(err.Error(), "already exists") {
-			ctx.JSON(http.StatusConflict, models.ErrorResponse{
-				Code:    http.StatusConflict,
-				Message: "User already exists",
-				Details: err.Error(),
-			})
-			return
-		}
-
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "Failed to create user",
-			Details: err.Error(),
-		})
+		respondWithError(ctx, err)
 		return
 	}
 
@@ -76,38 +61,21 @@ func (c *AuthController) SignUp(ctx *gin.Context) {
 // @Produce json
 // @Param request body models.SignInRequest true "Sign in request"
 // @Success 200 {object} models.SignInResponse
-// @Failure 400 {object} models.ErrorResponse
-// @Failure 401 {object} models.ErrorResponse
-// @Failure 500 {object} models.ErrorResponse
+// @Failure 400 {object} models.ProblemDetails
+// @Failure 401 {object} models.ProblemDetails
+// @Failure 500 {object} models.ProblemDetails
 // @Router /auth/signin [post]
 func (c *AuthController) SignIn(ctx *gin.Context) {
-	var req models.SignInRequest
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Code:    http.StatusBadRequest,
-			Message: "Invalid request body",
-			Details: err.Error(),
-		})
+	// Get the validated request from context (set by validation middleware)
+	req, exists := middleware.GetValidatedRequest[models.SignInRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
 		return
 	}
 
 	response, err := c.authService.SignIn(ctx.Request.Context(), &req)
 	if err != nil {
-		// Check if it's an authentication failure
-		if strings.Contains(err.Error(), "authentication failed") || strings.Contains(err.Error(), "invalid credentials") {
-			ctx.JSON(http.StatusUnauthorized, models.ErrorResponse{
-				Code:    http.StatusUnauthorized,
-				Message: "Authentication failed",
-				Details: "Invalid username or password",
-			})
-			return
-		}
-
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "Failed to sign in",
-			Details: err.Error(),
-		})
+		respondWithError(ctx, err)
 		return
 	}
 
@@ -122,38 +90,21 @@ func (c *AuthController) SignIn(ctx *gin.Context) {
 // @Produce json
 // @Param request body models.RefreshTokenRequest true "Refresh token request"
 // @Success 200 {object} models.RefreshTokenResponse
-// @Failure 400 {object} models.ErrorResponse
-// @Failure 401 {object} models.ErrorResponse
-// @Failure 500 {object} models.ErrorResponse
+// @Failure 400 {object} models.ProblemDetails
+// @Failure 401 {object} models.ProblemDetails
+// @Failure 500 {object} models.ProblemDetails
 // @Router /auth/refresh [post]
 func (c *AuthController) RefreshToken(ctx *gin.Context) {
-	var req models.RefreshTokenRequest
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Code:    http.StatusBadRequest,
-			Message: "Invalid request body",
-			Details: err.Error(),
-		})
+	// Get the validated request from context (set by validation middleware)
+	req, exists := middleware.GetValidatedRequest[models.RefreshTokenRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
 		return
 	}
 
 	response, err := c.authService.RefreshToken(ctx.Request.Context(), &req)
 	if err != nil {
-		// Check if it's an invalid token error
-		if strings.Contains(err.Error(), "invalid token") || strings.Contains(err.Error(), "token refresh failed") {
-			ctx.JSON(http.StatusUnauthorized, models.ErrorResponse{
-				Code:    http.StatusUnauthorized,
-				Message: "Invalid refresh token",
-				Details: err.Error(),
-			})
-			return
-		}
-
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "Failed to refresh token",
-			Details: err.Error(),
-		})
+		respondWithError(ctx, err)
 		return
 	}
 
@@ -168,27 +119,20 @@ func (c *AuthController) RefreshToken(ctx *gin.Context) {
 // @Produce json
 // @Param request body models.SignOutRequest true "Sign out request"
 // @Success 200 {object} models.SuccessResponse
-// @Failure 400 {object} models.ErrorResponse
-// @Failure 500 {object} models.ErrorResponse
+// @Failure 400 {object} models.ProblemDetails
+// @Failure 500 {object} models.ProblemDetails
 // @Router /auth/signout [post]
 func (c *AuthController) SignOut(ctx *gin.Context) {
-	var req models.SignOutRequest
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Code:    http.StatusBadRequest,
-			Message: "Invalid request body",
-			Details: err.Error(),
-		})
+	// Get the validated request from context (set by validation middleware)
+	req, exists := middleware.GetValidatedRequest[models.SignOutRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
 		return
 	}
 
 	err := c.authService.SignOut(ctx.Request.Context(), &req)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "Failed to sign out",
-			Details: err.Error(),
-		})
+		respondWithError(ctx, err)
 		return
 	}
 
@@ -203,41 +147,29 @@ func (c *AuthController) SignOut(ctx *gin.Context) {
 // @Tags authentication
 // @Accept json
 // @Produce json
+// @Security ApiKeyAuth
 // @Param request body models.CreateUserRequest true "Create user request"
 // @Success 201 {object} models.CreateUserResponse
-// @Failure 400 {object} models.ErrorResponse
-// @Failure 403 {object} models.ErrorResponse
-// @Failure 409 {object} models.ErrorResponse
-// @Failure 500 {object} models.ErrorResponse
+// @Failure 400 {object} models.ProblemDetails
+// @Failure 401 {object} models.ProblemDetails
+// @Failure 403 {object} models.ProblemDetails
+// @Failure 409 {object} models.ProblemDetails
+// @Failure 500 {object} models.ProblemDetails
 // @Router /auth/users [post]
 func (c *AuthController) CreateUser(ctx *gin.Context) {
-	var req models.CreateUserRequest
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Code:    http.StatusBadRequest,
-			Message: "Invalid request body",
-			Details: err.Error(),
-		})
+	// Get the validated request from context (set by validation middleware)
+	req, exists := middleware.GetValidatedRequest[models.CreateUserRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
 		return
 	}
 
+	// Only owners grant or remove the owner role, so the role is checked against the caller
+	req.ActorID = middleware.GetUserID(ctx)
+
 	response, err := c.authService.CreateUser(ctx.Request.Context(), &req)
 	if err != nil {
-		// Check if it's a user already exists error
-		if strings.Contains(err.Error(), "already exists") {
-			ctx.JSON(http.StatusConflict, models.ErrorResponse{
-				Code:    http.StatusConflict,
-				Message: "User already exists",
-				Details: err.Error(),
-			})
-			return
-		}
-
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "Failed to create user",
-			Details: err.Error(),
-		})
+		respondWithError(ctx, err)
 		return
 	}
 
@@ -249,34 +181,30 @@ func (c *AuthController) CreateUser(ctx *gin.Context) {
 // @Description Get user information by user ID
 // @Tags authentication
 // @Produce json
+// @Security ApiKeyAuth
 // @Param id path string true "User ID"
+// @Param fields query string false "Comma-separated JSON fields to return; dotted paths select nested fields"
 // @Success 200 {object} models.GetUserResponse
-// @Failure 404 {object} models.ErrorResponse
-// @Failure 500 {object} models.ErrorResponse
+// @Failure 401 {object} models.ProblemDetails
+// @Failure 403 {object} models.ProblemDetails
+// @Failure 404 {object} models.ProblemDetails
+// @Failure 500 {object} models.ProblemDetails
 // @Router /auth/users/{id} [get]
 func (c *AuthController) GetUser(ctx *gin.Context) {
-	userID := ctx.Param("id")
+	// Get the validated request from context (set by validation middleware)
+	req, exists := middleware.GetValidatedRequest[models.GetUserRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
 
-	response, err := c.authService.GetUser(ctx.Request.Context(), userID)
+	response, err := c.authService.GetUser(ctx.Request.Context(), req.UserID)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			ctx.JSON(http.StatusNotFound, models.ErrorResponse{
-				Code:    http.StatusNotFound,
-				Message: "User not found",
-				Details: err.Error(),
-			})
-			return
-		}
-
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "Failed to get user",
-			Details: err.Error(),
-		})
+		respondWithError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response)
+	respondWithFields(ctx, http.StatusOK, response)
 }
 
 // UpdateUser handles updating a user
@@ -285,45 +213,30 @@ func (c *AuthController) GetUser(ctx *gin.Context) {
 // @Tags authentication
 // @Accept json
 // @Produce json
+// @Security ApiKeyAuth
 // @Param id path string true "User ID"
 // @Param request body models.UpdateUserRequest true "Update user request"
 // @Success 200 {object} models.UpdateUserResponse
-// @Failure 400 {object} models.ErrorResponse
-// @Failure 404 {object} models.ErrorResponse
-// @Failure 500 {object} models.ErrorResponse
+// @Failure 400 {object} models.ProblemDetails
+// @Failure 401 {object} models.ProblemDetails
+// @Failure 403 {object} models.ProblemDetails
+// @Failure 404 {object} models.ProblemDetails
+// @Failure 500 {object} models.ProblemDetails
 // @Router /auth/users/{id} [put]
 func (c *AuthController) UpdateUser(ctx *gin.Context) {
-	userID := ctx.Param("id")
-
-	var req models.UpdateUserRequest
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Code:    http.StatusBadRequest,
-			Message: "Invalid request body",
-			Details: err.Error(),
-		})
+	// Get the validated request from context (set by validation middleware)
+	req, exists := middleware.GetValidatedRequest[models.UpdateUserRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
 		return
 	}
 
-	// Set the user ID from the path parameter
-	req.UserID = userID
+	// Only owners grant or remove the owner role, so the role is checked against the caller
+	req.ActorID = middleware.GetUserID(ctx)
 
 	response, err := c.authService.UpdateUser(ctx.Request.Context(), &req)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "does not exist") {
-			ctx.JSON(http.StatusNotFound, models.ErrorResponse{
-				Code:    http.StatusNotFound,
-				Message: "User not found",
-				Details: err.Error(),
-			})
-			return
-		}
-
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "Failed to update user",
-			Details: err.Error(),
-		})
+		respondWithError(ctx, err)
 		return
 	}
 
@@ -334,30 +247,25 @@ func (c *AuthController) UpdateUser(ctx *gin.Context) {
 // @Summary Delete user
 // @Description Delete a user account
 // @Tags authentication
+// @Security ApiKeyAuth
 // @Param id path string true "User ID"
 // @Success 204
-// @Failure 404 {object} models.ErrorResponse
-// @Failure 500 {object} models.ErrorResponse
+// @Failure 401 {object} models.ProblemDetails
+// @Failure 403 {object} models.ProblemDetails
+// @Failure 404 {object} models.ProblemDetails
+// @Failure 500 {object} models.ProblemDetails
 // @Router /auth/users/{id} [delete]
 func (c *AuthController) DeleteUser(ctx *gin.Context) {
-	userID := ctx.Param("id")
+	// Get the validated request from context (set by validation middleware)
+	req, exists := middleware.GetValidatedRequest[models.DeleteUserRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
 
-	err := c.authService.DeleteUser(ctx.Request.Context(), userID)
+	err := c.authService.DeleteUser(ctx.Request.Context(), req.UserID)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "does not exist") {
-			ctx.JSON(http.StatusNotFound, models.ErrorResponse{
-				Code:    http.StatusNotFound,
-				Message: "User not found",
-				Details: err.Error(),
-			})
-			return
-		}
-
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "Failed to delete user",
-			Details: err.Error(),
-		})
+		respondWithError(ctx, err)
 		return
 	}
 
@@ -369,22 +277,23 @@ func (c *AuthController) DeleteUser(ctx *gin.Context) {
 // @Description List users with optional filtering
 // @Tags authentication
 // @Produce json
+// @Security ApiKeyAuth
 // @Param limit query int false "Maximum number of users to return" default(10)
 // @Param offset query int false "Number of users to skip" default(0)
 // @Param role query string false "Filter by user role"
 // @Param status query string false "Filter by user status"
+// @Param fields query string false "Comma-separated JSON fields to return; dotted paths select nested fields"
 // @Success 200 {object} models.ListUsersResponse
-// @Failure 400 {object} models.ErrorResponse
-// @Failure 500 {object} models.ErrorResponse
+// @Failure 400 {object} models.ProblemDetails
+// @Failure 401 {object} models.ProblemDetails
+// @Failure 403 {object} models.ProblemDetails
+// @Failure 500 {object} models.ProblemDetails
 // @Router /auth/users [get]
 func (c *AuthController) ListUsers(ctx *gin.Context) {
-	var req models.ListUsersRequest
-	if err := ctx.ShouldBindQuery(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Code:    http.StatusBadRequest,
-			Message: "Invalid query parameters",
-			Details: err.Error(),
-		})
+	// Get the validated request from context (set by validation middleware)
+	req, exists := middleware.GetValidatedRequest[models.ListUsersRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
 		return
 	}
 
@@ -395,15 +304,11 @@ func (c *AuthController) ListUsers(ctx *gin.Context) {
 
 	response, err := c.authService.ListUsers(ctx.Request.Context(), &req)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "Failed to list users",
-			Details: err.Error(),
-		})
+		respondWithError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response)
+	respondWithListFields(ctx, http.StatusOK, response, "users")
 }
 
 // ConfirmEmail handles email confirmation after signup
@@ -414,53 +319,21 @@ func (c *AuthController) ListUsers(ctx *gin.Context) {
 // @Produce json
 // @Param request body models.ConfirmEmailRequest true "Confirm email request"
 // @Success 200 {object} models.SuccessResponse
-// @Failure 400 {object} models.ErrorResponse
-// @Failure 404 {object} models.ErrorResponse
-// @Failure 500 {object} models.ErrorResponse
+// @Failure 400 {object} models.ProblemDetails
+// @Failure 404 {object} models.ProblemDetails
+// @Failure 500 {object} models.ProblemDetails
 // @Router /auth/confirm [post]
 func (c *AuthController) ConfirmEmail(ctx *gin.Context) {
-	// Get validated request from context (set by validation middleware)
-	validatedRequest, exists := ctx.Get("validatedRequest")
+	// Get the validated request from context (set by validation middleware)
+	req, exists := middleware.GetValidatedRequest[models.ConfirmEmailRequest](ctx)
 	if !exists {
-		// Fallback to direct binding if validation middleware is not used
-		var req models.ConfirmEmailRequest
-		if err := ctx.ShouldBindJSON(&req); err != nil {
-			ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
-				Code:    http.StatusBadRequest,
-				Message: "Invalid request body",
-				Details: err.Error(),
-			})
-			return
-		}
-		validatedRequest = req
-	}
-
-	req, ok := validatedRequest.(models.ConfirmEmailRequest)
-	if !ok {
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "Internal server error",
-			Details: "Invalid request type",
-		})
+		respondWithError(ctx, errMissingValidatedRequest)
 		return
 	}
 
 	err := c.authService.ConfirmEmail(ctx.Request.Context(), &req)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "does not exist") {
-			ctx.JSON(http.StatusNotFound, models.ErrorResponse{
-				Code:    http.StatusNotFound,
-				Message: "User not found",
-				Details: err.Error(),
-			})
-			return
-		}
-
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "Failed to confirm email",
-			Details: err.Error(),
-		})
+		respondWithError(ctx, err)
 		return
 	}
 
@@ -477,53 +350,21 @@ func (c *AuthController) ConfirmEmail(ctx *gin.Context) {
 // @Produce json
 // @Param request body models.ForgotPasswordRequest true "Forgot password request"
 // @Success 200 {object} models.SuccessResponse
-// @Failure 400 {object} models.ErrorResponse
-// @Failure 404 {object} models.ErrorResponse
-// @Failure 500 {object} models.ErrorResponse
+// @Failure 400 {object} models.ProblemDetails
+// @Failure 404 {object} models.ProblemDetails
+// @Failure 500 {object} models.ProblemDetails
 // @Router /auth/forgot-password [post]
 func (c *AuthController) ForgotPassword(ctx *gin.Context) {
-	// Get validated request from context (set by validation middleware)
-	validatedRequest, exists := ctx.Get("validatedRequest")
+	// Get the validated request from context (set by validation middleware)
+	req, exists := middleware.GetValidatedRequest[models.ForgotPasswordRequest](ctx)
 	if !exists {
-		// Fallback to direct binding if validation middleware is not used
-		var req models.ForgotPasswordRequest
-		if err := ctx.ShouldBindJSON(&req); err != nil {
-			ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
-				Code:    http.StatusBadRequest,
-				Message: "Invalid request body",
-				Details: err.Error(),
-			})
-			return
-		}
-		validatedRequest = req
-	}
-
-	req, ok := validatedRequest.(models.ForgotPasswordRequest)
-	if !ok {
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "Internal server error",
-			Details: "Invalid request type",
-		})
+		respondWithError(ctx, errMissingValidatedRequest)
 		return
 	}
 
 	err := c.authService.ForgotPassword(ctx.Request.Context(), &req)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "does not exist") {
-			ctx.JSON(http.StatusNotFound, models.ErrorResponse{
-				Code:    http.StatusNotFound,
-				Message: "User not found",
-				Details: err.Error(),
-			})
-			return
-		}
-
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "Failed to initiate password reset",
-			Details: err.Error(),
-		})
+		respondWithError(ctx, err)
 		return
 	}
 
@@ -540,53 +381,21 @@ func (c *AuthController) ForgotPassword(ctx *gin.Context) {
 // @Produce json
 // @Param request body models.ResetPasswordRequest true "Reset password request"
 // @Success 200 {object} models.SuccessResponse
-// @Failure 400 {object} models.ErrorResponse
-// @Failure 404 {object} models.ErrorResponse
-// @Failure 500 {object} models.ErrorResponse
+// @Failure 400 {object} models.ProblemDetails
+// @Failure 404 {object} models.ProblemDetails
+// @Failure 500 {object} models.ProblemDetails
 // @Router /auth/reset-password [post]
 func (c *AuthController) ResetPassword(ctx *gin.Context) {
-	// Get validated request from context (set by validation middleware)
-	validatedRequest, exists := ctx.Get("validatedRequest")
+	// Get the validated request from context (set by validation middleware)
+	req, exists := middleware.GetValidatedRequest[models.ResetPasswordRequest](ctx)
 	if !exists {
-		// Fallback to direct binding if validation middleware is not used
-		var req models.ResetPasswordRequest
-		if err := ctx.ShouldBindJSON(&req); err != nil {
-			ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
-				Code:    http.StatusBadRequest,
-				Message: "Invalid request body",
-				Details: err.Error(),
-			})
-			return
-		}
-		validatedRequest = req
-	}
-
-	req, ok := validatedRequest.(models.ResetPasswordRequest)
-	if !ok {
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "Internal server error",
-			Details: "Invalid request type",
-		})
+		respondWithError(ctx, errMissingValidatedRequest)
 		return
 	}
 
 	err := c.authService.ResetPassword(ctx.Request.Context(), &req)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "does not exist") {
-			ctx.JSON(http.StatusNotFound, models.ErrorResponse{
-				Code:    http.StatusNotFound,
-				Message: "User not found",
-				Details: err.Error(),
-			})
-			return
-		}
-
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "Failed to reset password",
-			Details: err.Error(),
-		})
+		respondWithError(ctx, err)
 		return
 	}
 
@@ -601,51 +410,276 @@ func (c *AuthController) ResetPassword(ctx *gin.Context) {
 // @Tags authentication
 // @Produce json
 // @Security ApiKeyAuth
+// @Param fields query string false "Comma-separated JSON fields to return; dotted paths select nested fields"
 // @Success 200 {object} models.APIUser
-// @Failure 401 {object} models.ErrorResponse
-// @Failure 500 {object} models.ErrorResponse
+// @Failure 401 {object} models.ProblemDetails
+// @Failure 500 {object} models.ProblemDetails
 // @Router /auth/me [get]
 func (c *AuthController) GetMe(ctx *gin.Context) {
 	// Get user ID from context (set by auth middleware)
 	userID, exists := ctx.Get("userID")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, models.ErrorResponse{
-			Code:    http.StatusUnauthorized,
-			Message: "User not authenticated",
-			Details: "User ID not found in context",
-		})
+		respondWithError(ctx, apperrors.Unauthorized(apperrors.CodeUnauthorized, "user ID not found in context"))
 		return
 	}
 
 	// Ensure userID is a string
 	userIDStr, ok := userID.(string)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "Internal server error",
-			Details: "Invalid user ID type",
-		})
+		respondWithError(ctx, errors.New("invalid user ID type"))
 		return
 	}
 
 	user, err := c.authService.GetUser(ctx.Request.Context(), userIDStr)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			ctx.JSON(http.StatusNotFound, models.ErrorResponse{
-				Code:    http.StatusNotFound,
-				Message: "User not found",
-				Details: err.Error(),
-			})
-			return
-		}
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, user)
+}
+
+// UpdateMe handles updating the current user's profile
+// @Summary Update current user
+// @Description Update the current authenticated user's profile. The role can only be changed by an admin.
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body models.UpdateProfileRequest true "Update profile request"
+// @Success 200 {object} models.UpdateUserResponse
+// @Failure 400 {object} models.ProblemDetails
+// @Failure 401 {object} models.ProblemDetails
+// @Failure 404 {object} models.ProblemDetails
+// @Failure 500 {object} models.ProblemDetails
+// @Router /auth/me [put]
+func (c *AuthController) UpdateMe(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	req, exists := middleware.GetValidatedRequest[models.UpdateProfileRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	// The caller can only update their own profile
+	req.AuthID = middleware.GetUserID(ctx)
+
+	response, err := c.authService.UpdateProfile(ctx.Request.Context(), &req)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
 
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "Failed to get user information",
-			Details: err.Error(),
-		})
+// ChangePassword handles changing the current user's password
+// @Summary Change password
+// @Description Change the current authenticated user's password, confirming the current one
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body models.ChangePasswordRequest true "Change password request"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ProblemDetails "Invalid request or new password not meeting the password policy"
+// @Failure 401 {object} models.ProblemDetails "Current password incorrect"
+// @Failure 500 {object} models.ProblemDetails
+// @Router /auth/me/password [post]
+func (c *AuthController) ChangePassword(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	req, exists := middleware.GetValidatedRequest[models.ChangePasswordRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	req.AccessToken = middleware.GetAccessToken(ctx)
+
+	if err := c.authService.ChangePassword(ctx.Request.Context(), &req); err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Password changed successfully",
+	})
+}
+
+// DeleteMe handles deleting the current user's account
+// @Summary Delete current user
+// @Description Delete the current authenticated user's account, confirmed by its email address. The last owner can't delete their account.
+// @Tags authentication
+// @Accept json
+// @Security ApiKeyAuth
+// @Param request body models.DeleteAccountRequest true "Delete account request"
+// @Success 204
+// @Failure 400 {object} models.ProblemDetails "Invalid request or email address not matching the account"
+// @Failure 401 {object} models.ProblemDetails
+// @Failure 404 {object} models.ProblemDetails
+// @Failure 409 {object} models.ProblemDetails "Caller is the last owner"
+// @Failure 500 {object} models.ProblemDetails
+// @Router /auth/me [delete]
+func (c *AuthController) DeleteMe(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	req, exists := middleware.GetValidatedRequest[models.DeleteAccountRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, user)
+	// The caller can only delete their own account
+	req.AuthID = middleware.GetUserID(ctx)
+
+	if err := c.authService.DeleteAccount(ctx.Request.Context(), &req); err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// SetupMFA handles enrolling a second factor for the current user
+// @Summary Set up MFA
+// @Description Enroll a second factor for the current authenticated user. TOTP returns the secret to add to an authenticator app and is enabled once a one-time password from the app is verified. SMS is enabled right away and returns the recovery codes.
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body models.MFASetupRequest true "MFA setup request"
+// @Success 200 {object} models.MFASetupResponse
+// @Failure 400 {object} models.ProblemDetails
+// @Failure 401 {object} models.ProblemDetails
+// @Failure 500 {object} models.ProblemDetails
+// @Router /auth/mfa/setup [post]
+func (c *AuthController) SetupMFA(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	req, exists := middleware.GetValidatedRequest[models.MFASetupRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	req.AuthID = middleware.GetUserID(ctx)
+	req.AccessToken = middleware.GetAccessToken(ctx)
+
+	response, err := c.authService.SetupMFA(ctx.Request.Context(), &req)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// VerifyMFA handles verifying the authenticator app the current user enrolled
+// @Summary Verify TOTP MFA
+// @Description Verify a one-time password from the authenticator app the current authenticated user enrolled, enabling TOTP. Returns the recovery codes, which are only shown once.
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body models.VerifyMFARequest true "MFA verification request"
+// @Success 200 {object} models.MFARecoveryCodesResponse
+// @Failure 400 {object} models.ProblemDetails "Invalid request or one-time password"
+// @Failure 401 {object} models.ProblemDetails
+// @Failure 500 {object} models.ProblemDetails
+// @Router /auth/mfa/verify [post]
+func (c *AuthController) VerifyMFA(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	req, exists := middleware.GetValidatedRequest[models.VerifyMFARequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	req.AuthID = middleware.GetUserID(ctx)
+	req.AccessToken = middleware.GetAccessToken(ctx)
+
+	response, err := c.authService.VerifyMFA(ctx.Request.Context(), &req)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// RegenerateRecoveryCodes handles replacing the current user's MFA recovery codes
+// @Summary Regenerate MFA recovery codes
+// @Description Replace the MFA recovery codes of the current authenticated user, revoking the previous ones. The new codes are only shown once.
+// @Tags authentication
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} models.MFARecoveryCodesResponse
+// @Failure 401 {object} models.ProblemDetails
+// @Failure 500 {object} models.ProblemDetails
+// @Router /auth/mfa/recovery-codes [post]
+func (c *AuthController) RegenerateRecoveryCodes(ctx *gin.Context) {
+	response, err := c.authService.RegenerateRecoveryCodes(ctx.Request.Context(), middleware.GetUserID(ctx))
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// RespondToMFAChallenge handles completing a sign in with a second factor
+// @Summary Answer an MFA challenge
+// @Description Complete the sign in of a user with MFA enabled with the one-time password of their second factor
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param request body models.MFAChallengeRequest true "MFA challenge response"
+// @Success 200 {object} models.SignInResponse
+// @Failure 400 {object} models.ProblemDetails "Invalid request or one-time password"
+// @Failure 401 {object} models.ProblemDetails "Session expired"
+// @Failure 500 {object} models.ProblemDetails
+// @Router /auth/mfa/challenge [post]
+func (c *AuthController) RespondToMFAChallenge(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	req, exists := middleware.GetValidatedRequest[models.MFAChallengeRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.authService.RespondToMFAChallenge(ctx.Request.Context(), &req)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// RecoverMFA handles signing in with a recovery code
+// @Summary Sign in with an MFA recovery code
+// @Description Sign a user who lost their second factor in with their password and a recovery code. MFA is disabled and the remaining recovery codes are revoked, so the user must enroll a new second factor.
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param request body models.MFARecoverRequest true "MFA recovery request"
+// @Success 200 {object} models.SignInResponse
+// @Failure 400 {object} models.ProblemDetails
+// @Failure 401 {object} models.ProblemDetails "Invalid password or recovery code"
+// @Failure 409 {object} models.ProblemDetails "MFA not enabled"
+// @Failure 500 {object} models.ProblemDetails
+// @Router /auth/mfa/recover [post]
+func (c *AuthController) RecoverMFA(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	req, exists := middleware.GetValidatedRequest[models.MFARecoverRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.authService.RecoverMFA(ctx.Request.Context(), &req)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
 	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
 	"github.com/kazemisoroush/code-refactoring-tool/api/models"
 	"github.com/kazemisoroush/code-refactoring-tool/api/services/mocks"
@@ -58,9 +59,9 @@ func TestAuthController_ConfirmEmail(t *testing.T) {
 			setupMock: func(mockAuthService *mocks.MockAuthService) {
 				mockAuthService.EXPECT().
 					ConfirmEmail(gomock.Any(), gomock.Any()).
-					Return(assert.AnError)
+					Return(apperrors.Wrap(apperrors.ErrNotFound, apperrors.CodeUserNotFound, assert.AnError, "user not found"))
 			},
-			expectedStatus: http.StatusInternalServerError,
+			expectedStatus: http.StatusNotFound,
 		},
 	}
 
@@ -76,6 +77,7 @@ func TestAuthController_ConfirmEmail(t *testing.T) {
 
 			gin.SetMode(gin.TestMode)
 			router := gin.New()
+			router.Use(middleware.NewErrorHandlerMiddleware().Handle())
 
 			// Use validation middleware like in the actual routes
 			router.POST("/auth/confirm", middleware.NewJSONValidationMiddleware[models.ConfirmEmailRequest]().Handle(), authController.ConfirmEmail)
@@ -140,6 +142,7 @@ func TestAuthController_ForgotPassword(t *testing.T) {
 
 			gin.SetMode(gin.TestMode)
 			router := gin.New()
+			router.Use(middleware.NewErrorHandlerMiddleware().Handle())
 
 			// Use validation middleware like in the actual routes
 			router.POST("/auth/forgot-password", middleware.NewJSONValidationMiddleware[models.ForgotPasswordRequest]().Handle(), authController.ForgotPassword)
@@ -209,16 +212,81 @@ func TestAuthController_GetMe(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setupMock()
 
+			router := gin.New()
+			router.Use(middleware.NewErrorHandlerMiddleware().Handle())
+			router.GET("/auth/me", func(c *gin.Context) {
+				if tt.userID != nil {
+					c.Set("userID", tt.userID)
+				}
+			}, authController.GetMe)
+
 			w := httptest.NewRecorder()
-			c, _ := gin.CreateTestContext(w)
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/auth/me", nil))
 
-			if tt.userID != nil {
-				c.Set("userID", tt.userID)
-			}
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
 
-			c.Request = httptest.NewRequest("GET", "/auth/me", nil)
+func TestAuthController_DeleteMe(t *testing.T) {
+	gin.SetMode(gin.TestMode)
 
-			authController.GetMe(c)
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		setupMock      func(*mocks.MockAuthService)
+		expectedStatus int
+	}{
+		{
+			name:        "successful account deletion",
+			requestBody: models.DeleteAccountRequest{ConfirmEmail: "test@example.com"},
+			setupMock: func(mockAuthService *mocks.MockAuthService) {
+				mockAuthService.EXPECT().
+					DeleteAccount(gomock.Any(), &models.DeleteAccountRequest{AuthID: "auth-123", ConfirmEmail: "test@example.com"}).
+					Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "missing confirmation",
+			requestBody:    map[string]interface{}{},
+			setupMock:      func(*mocks.MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "last owner",
+			requestBody: models.DeleteAccountRequest{ConfirmEmail: "test@example.com"},
+			setupMock: func(mockAuthService *mocks.MockAuthService) {
+				mockAuthService.EXPECT().
+					DeleteAccount(gomock.Any(), gomock.Any()).
+					Return(apperrors.Conflict(apperrors.CodeLastOwner, "last owner"))
+			},
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockAuthService := mocks.NewMockAuthService(ctrl)
+			authController := NewAuthController(mockAuthService)
+
+			tt.setupMock(mockAuthService)
+
+			router := gin.New()
+			router.Use(middleware.NewErrorHandlerMiddleware().Handle())
+			router.DELETE("/auth/me", func(c *gin.Context) {
+				c.Set(middleware.UserIDContextKey, "auth-123")
+			}, middleware.NewJSONValidationMiddleware[models.DeleteAccountRequest]().Handle(), authController.DeleteMe)
+
+			body, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest("DELETE", "/auth/me", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
 
 			assert.Equal(t, tt.expectedStatus, w.Code)
 		})
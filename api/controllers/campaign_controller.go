@@ -0,0 +1,81 @@
+// Package controllers provides HTTP request handlers for the campaign API
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/services"
+)
+
+// CampaignController handles campaign HTTP requests
+type CampaignController struct {
+	campaignService services.CampaignService
+}
+
+// NewCampaignController creates a new CampaignController
+func NewCampaignController(campaignService services.CampaignService) *CampaignController {
+	return &CampaignController{
+		campaignService: campaignService,
+	}
+}
+
+// CreateCampaign handles POST /campaigns
+// @Summary Start a campaign
+// @Description Apply one instruction across many codebases by running a child task per codebase with bounded parallelism
+// @Tags campaigns
+// @Accept json
+// @Produce json
+// @Param request body models.CreateCampaignRequest true "Campaign creation request"
+// @Success 201 {object} models.CreateCampaignResponse "Campaign started successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request, unknown agent or codebase"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/campaigns [post]
+func (c *CampaignController) CreateCampaign(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.CreateCampaignRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+	request.CreatedBy = middleware.GetUserID(ctx)
+
+	response, err := c.campaignService.CreateCampaign(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// GetCampaign handles GET /campaigns/:id
+// @Summary Get a campaign
+// @Description Retrieve a campaign's progress and the result and pull request of each codebase
+// @Tags campaigns
+// @Produce json
+// @Param id path string true "Campaign ID"
+// @Param fields query string false "Comma-separated JSON fields to return; dotted paths select nested fields"
+// @Success 200 {object} models.GetCampaignResponse "Campaign retrieved successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid campaign ID"
+// @Failure 404 {object} models.ProblemDetails "Campaign not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/campaigns/{id} [get]
+func (c *CampaignController) GetCampaign(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.GetCampaignRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.campaignService.GetCampaign(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, response)
+}
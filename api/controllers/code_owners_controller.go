@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/services"
+)
+
+// CodeOwnersController handles codebase file ownership HTTP requests
+type CodeOwnersController struct {
+	codeOwnersService services.CodeOwnersService
+}
+
+// NewCodeOwnersController creates a new CodeOwnersController
+func NewCodeOwnersController(codeOwnersService services.CodeOwnersService) *CodeOwnersController {
+	return &CodeOwnersController{
+		codeOwnersService: codeOwnersService,
+	}
+}
+
+// GetCodeOwners handles GET /codebases/:id/owners
+// @Summary Get the owners of a codebase's files
+// @Description Get the CODEOWNERS rules recorded by the codebase's latest ingestion scan or static analysis, read from .github/CODEOWNERS, CODEOWNERS, docs/CODEOWNERS or .gitlab/CODEOWNERS. Set path to resolve the owners of a file.
+// @Tags codebases
+// @Produce json
+// @Param id path string true "Codebase ID"
+// @Param path query string false "File to resolve the owners of, relative to the repository root"
+// @Success 200 {object} models.GetCodeOwnersResponse "Code owners retrieved successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 404 {object} models.ProblemDetails "Codebase not found or ownership not recorded yet"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/codebases/{id}/owners [get]
+func (c *CodeOwnersController) GetCodeOwners(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.GetCodeOwnersRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.codeOwnersService.GetOwners(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, response)
+}
@@ -30,31 +30,23 @@ func NewCodebaseConfigController(codebaseConfigService services.CodebaseConfigSe
 // @Produce json
 // @Param request body models.CreateCodebaseConfigRequest true "Codebase configuration creation request"
 // @Success 201 {object} models.CreateCodebaseConfigResponse "Codebase configuration created successfully"
-// @Failure 400 {object} models.ErrorResponse "Invalid request"
-// @Failure 500 {object} models.ErrorResponse "Internal server error"
-// @Router /codebase-configs [post]
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 403 {object} models.ProblemDetails "Caller may not modify a restricted tag"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/codebase-configs [post]
 func (c *CodebaseConfigController) CreateCodebaseConfig(ctx *gin.Context) {
 	// Get the validated request from context (set by validation middleware)
 	request, exists := middleware.GetValidatedRequest[models.CreateCodebaseConfigRequest](ctx)
 	if !exists {
-		errorResponse := models.ErrorResponse{
-			Code:    http.StatusBadRequest,
-			Message: "Missing validated request",
-			Details: "Validation middleware must be applied before this controller",
-		}
-		ctx.JSON(http.StatusBadRequest, errorResponse)
+		respondWithError(ctx, errMissingValidatedRequest)
 		return
 	}
+	request.UserID = middleware.GetUserID(ctx)
 
 	// Call the service to create the codebase configuration
 	response, err := c.codebaseConfigService.CreateCodebaseConfig(ctx.Request.Context(), request)
 	if err != nil {
-		errorResponse := models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "Failed to create codebase configuration",
-			Details: err.Error(),
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse)
+		respondWithError(ctx, err)
 		return
 	}
 
@@ -67,49 +59,30 @@ func (c *CodebaseConfigController) CreateCodebaseConfig(ctx *gin.Context) {
 // @Tags codebase-configs
 // @Produce json
 // @Param config_id path string true "Codebase Configuration ID"
+// @Param fields query string false "Comma-separated JSON fields to return; dotted paths select nested fields"
 // @Success 200 {object} models.GetCodebaseConfigResponse "Codebase configuration retrieved successfully"
-// @Failure 400 {object} models.ErrorResponse "Invalid configuration ID"
-// @Failure 404 {object} models.ErrorResponse "Codebase configuration not found"
-// @Failure 500 {object} models.ErrorResponse "Internal server error"
-// @Router /codebase-configs/{config_id} [get]
+// @Header 200 {string} ETag "Configuration version, to be sent as If-Match on update"
+// @Failure 400 {object} models.ProblemDetails "Invalid configuration ID"
+// @Failure 404 {object} models.ProblemDetails "Codebase configuration not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/codebase-configs/{config_id} [get]
 func (c *CodebaseConfigController) GetCodebaseConfig(ctx *gin.Context) {
 	// Get the validated request from context (set by validation middleware)
 	request, exists := middleware.GetValidatedRequest[models.GetCodebaseConfigRequest](ctx)
 	if !exists {
-		errorResponse := models.ErrorResponse{
-			Code:    http.StatusBadRequest,
-			Message: "Missing validated request",
-			Details: "Validation middleware must be applied before this controller",
-		}
-		ctx.JSON(http.StatusBadRequest, errorResponse)
+		respondWithError(ctx, errMissingValidatedRequest)
 		return
 	}
 
 	// Call the service to get the codebase configuration
 	response, err := c.codebaseConfigService.GetCodebaseConfig(ctx.Request.Context(), request.ConfigID)
 	if err != nil {
-		var statusCode int
-		var message string
-
-		// Check if it's a "not found" error
-		if err.Error() == "codebase configuration not found" {
-			statusCode = http.StatusNotFound
-			message = "Codebase configuration not found"
-		} else {
-			statusCode = http.StatusInternalServerError
-			message = "Failed to get codebase configuration"
-		}
-
-		errorResponse := models.ErrorResponse{
-			Code:    statusCode,
-			Message: message,
-			Details: err.Error(),
-		}
-		ctx.JSON(statusCode, errorResponse)
+		respondWithError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response)
+	setETag(ctx, response.Version)
+	respondWithFields(ctx, http.StatusOK, response)
 }
 
 // UpdateCodebaseConfig handles PUT /codebase-configs/:config_id
@@ -119,49 +92,41 @@ func (c *CodebaseConfigController) GetCodebaseConfig(ctx *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param config_id path string true "Codebase Configuration ID"
+// @Param If-Match header string true "ETag of the configuration version being updated"
 // @Param request body models.UpdateCodebaseConfigRequest true "Codebase configuration update request"
 // @Success 200 {object} models.UpdateCodebaseConfigResponse "Codebase configuration updated successfully"
-// @Failure 400 {object} models.ErrorResponse "Invalid request"
-// @Failure 404 {object} models.ErrorResponse "Codebase configuration not found"
-// @Failure 500 {object} models.ErrorResponse "Internal server error"
-// @Router /codebase-configs/{config_id} [put]
+// @Header 200 {string} ETag "New configuration version"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 403 {object} models.ProblemDetails "Caller may not modify a restricted tag"
+// @Failure 404 {object} models.ProblemDetails "Codebase configuration not found"
+// @Failure 412 {object} models.ProblemDetails "Configuration was modified since the If-Match version"
+// @Failure 428 {object} models.ProblemDetails "If-Match header missing"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/codebase-configs/{config_id} [put]
 func (c *CodebaseConfigController) UpdateCodebaseConfig(ctx *gin.Context) {
 	// Get the validated request from context (set by validation middleware)
 	request, exists := middleware.GetValidatedRequest[models.UpdateCodebaseConfigRequest](ctx)
 	if !exists {
-		errorResponse := models.ErrorResponse{
-			Code:    http.StatusBadRequest,
-			Message: "Missing validated request",
-			Details: "Validation middleware must be applied before this controller",
-		}
-		ctx.JSON(http.StatusBadRequest, errorResponse)
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+	request.UserID = middleware.GetUserID(ctx)
+
+	version, err := ifMatchVersion(ctx)
+	if err != nil {
+		respondWithError(ctx, err)
 		return
 	}
+	request.ExpectedVersion = version
 
 	// Call the service to update the codebase configuration
 	response, err := c.codebaseConfigService.UpdateCodebaseConfig(ctx.Request.Context(), request)
 	if err != nil {
-		var statusCode int
-		var message string
-
-		// Check if it's a "not found" error
-		if err.Error() == "codebase configuration not found" {
-			statusCode = http.StatusNotFound
-			message = "Codebase configuration not found"
-		} else {
-			statusCode = http.StatusInternalServerError
-			message = "Failed to update codebase configuration"
-		}
-
-		errorResponse := models.ErrorResponse{
-			Code:    statusCode,
-			Message: message,
-			Details: err.Error(),
-		}
-		ctx.JSON(statusCode, errorResponse)
+		respondWithError(ctx, err)
 		return
 	}
 
+	setETag(ctx, response.Version)
 	ctx.JSON(http.StatusOK, response)
 }
 
@@ -172,44 +137,22 @@ func (c *CodebaseConfigController) UpdateCodebaseConfig(ctx *gin.Context) {
 // @Produce json
 // @Param config_id path string true "Codebase Configuration ID"
 // @Success 200 {object} models.DeleteCodebaseConfigResponse "Codebase configuration deleted successfully"
-// @Failure 400 {object} models.ErrorResponse "Invalid configuration ID"
-// @Failure 404 {object} models.ErrorResponse "Codebase configuration not found"
-// @Failure 500 {object} models.ErrorResponse "Internal server error"
-// @Router /codebase-configs/{config_id} [delete]
+// @Failure 400 {object} models.ProblemDetails "Invalid configuration ID"
+// @Failure 404 {object} models.ProblemDetails "Codebase configuration not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/codebase-configs/{config_id} [delete]
 func (c *CodebaseConfigController) DeleteCodebaseConfig(ctx *gin.Context) {
 	// Get the validated request from context (set by validation middleware)
 	request, exists := middleware.GetValidatedRequest[models.DeleteCodebaseConfigRequest](ctx)
 	if !exists {
-		errorResponse := models.ErrorResponse{
-			Code:    http.StatusBadRequest,
-			Message: "Missing validated request",
-			Details: "Validation middleware must be applied before this controller",
-		}
-		ctx.JSON(http.StatusBadRequest, errorResponse)
+		respondWithError(ctx, errMissingValidatedRequest)
 		return
 	}
 
 	// Call the service to delete the codebase configuration
 	response, err := c.codebaseConfigService.DeleteCodebaseConfig(ctx.Request.Context(), request.ConfigID)
 	if err != nil {
-		var statusCode int
-		var message string
-
-		// Check if it's a "not found" error
-		if err.Error() == "codebase configuration not found" {
-			statusCode = http.StatusNotFound
-			message = "Codebase configuration not found"
-		} else {
-			statusCode = http.StatusInternalServerError
-			message = "Failed to delete codebase configuration"
-		}
-
-		errorResponse := models.ErrorResponse{
-			Code:    statusCode,
-			Message: message,
-			Details: err.Error(),
-		}
-		ctx.JSON(statusCode, errorResponse)
+		respondWithError(ctx, err)
 		return
 	}
 
@@ -223,36 +166,27 @@ func (c *CodebaseConfigController) DeleteCodebaseConfig(ctx *gin.Context) {
 // @Produce json
 // @Param next_token query string false "Token for pagination"
 // @Param max_results query int false "Maximum number of results to return" minimum(1) maximum(100)
-// @Param provider_filter query string false "Filter by provider (github, gitlab, bitbucket, custom)"
+// @Param provider_filter query string false "Filter by provider (github, gitlab, bitbucket, azure_devops, custom)"
 // @Param tag_filter query string false "Tag filter in format key:value"
+// @Param fields query string false "Comma-separated JSON fields to return; dotted paths select nested fields"
 // @Success 200 {object} models.ListCodebaseConfigsResponse "Codebase configurations retrieved successfully"
-// @Failure 400 {object} models.ErrorResponse "Invalid request parameters"
-// @Failure 500 {object} models.ErrorResponse "Internal server error"
-// @Router /codebase-configs [get]
+// @Failure 400 {object} models.ProblemDetails "Invalid request parameters"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/codebase-configs [get]
 func (c *CodebaseConfigController) ListCodebaseConfigs(ctx *gin.Context) {
 	// Get the validated request from context (set by validation middleware)
 	request, exists := middleware.GetValidatedRequest[models.ListCodebaseConfigsRequest](ctx)
 	if !exists {
-		errorResponse := models.ErrorResponse{
-			Code:    http.StatusBadRequest,
-			Message: "Missing validated request",
-			Details: "Validation middleware must be applied before this controller",
-		}
-		ctx.JSON(http.StatusBadRequest, errorResponse)
+		respondWithError(ctx, errMissingValidatedRequest)
 		return
 	}
 
 	// Call the service to list codebase configurations
 	response, err := c.codebaseConfigService.ListCodebaseConfigs(ctx.Request.Context(), request)
 	if err != nil {
-		errorResponse := models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "Failed to list codebase configurations",
-			Details: err.Error(),
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse)
+		respondWithError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response)
+	respondWithListFields(ctx, http.StatusOK, response, "configs")
 }
@@ -122,15 +122,17 @@ func TestCodebaseConfigController_UpdateCodebaseConfig_Success(t *testing.T) {
 	name := "Updated Config"
 	url := "https://github.com/test/updated-repo.git"
 	request := models.UpdateCodebaseConfigRequest{
-		ConfigID: configID,
-		Name:     &name,
-		URL:      &url,
-		Config:   &models.GitProviderConfig{},
+		ConfigID:        configID,
+		Name:            &name,
+		URL:             &url,
+		Config:          &models.GitProviderConfig{},
+		ExpectedVersion: 3,
 	}
 
 	expectedResponse := &models.UpdateCodebaseConfigResponse{
 		ConfigID:  configID,
 		UpdatedAt: "2025-08-10T10:30:00Z",
+		Version:   4,
 	}
 
 	mockService.EXPECT().UpdateCodebaseConfig(gomock.Any(), request).Return(expectedResponse, nil).Times(1)
@@ -146,11 +148,13 @@ func TestCodebaseConfigController_UpdateCodebaseConfig_Success(t *testing.T) {
 	require.NoError(t, err)
 	req := httptest.NewRequest(http.MethodPut, "/codebase-configs/"+configID, bytes.NewReader(reqBody))
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `"3"`)
 	w := httptest.NewRecorder()
 
 	router.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"4"`, w.Header().Get("ETag"))
 	var response models.UpdateCodebaseConfigResponse
 	err = json.Unmarshal(w.Body.Bytes(), &response)
 	require.NoError(t, err)
@@ -13,12 +13,29 @@ import (
 // CodebaseController handles codebase-related HTTP requests
 type CodebaseController struct {
 	codebaseService services.CodebaseService
+	browseService   services.CodebaseBrowseService
+	auditService    services.DependencyAuditService
+	metricsService  services.CodeMetricsService
+	scanService     services.IngestionScanService
+	uploadService   services.CodebaseUploadService
 }
 
 // NewCodebaseController creates a new CodebaseController
-func NewCodebaseController(codebaseService services.CodebaseService) *CodebaseController {
+func NewCodebaseController(
+	codebaseService services.CodebaseService,
+	browseService services.CodebaseBrowseService,
+	auditService services.DependencyAuditService,
+	metricsService services.CodeMetricsService,
+	scanService services.IngestionScanService,
+	uploadService services.CodebaseUploadService,
+) *CodebaseController {
 	return &CodebaseController{
 		codebaseService: codebaseService,
+		browseService:   browseService,
+		auditService:    auditService,
+		metricsService:  metricsService,
+		scanService:     scanService,
+		uploadService:   uploadService,
 	}
 }
 
@@ -31,86 +48,119 @@ func NewCodebaseController(codebaseService services.CodebaseService) *CodebaseCo
 // @Param project_id path string true "Project ID"
 // @Param request body models.CreateCodebaseRequest true "Codebase creation request"
 // @Success 201 {object} models.CreateCodebaseResponse "Codebase created successfully"
-// @Failure 400 {object} models.ErrorResponse "Invalid request"
-// @Failure 500 {object} models.ErrorResponse "Internal server error"
-// @Router /projects/{project_id}/codebases [post]
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 403 {object} models.ProblemDetails "Caller may not modify a restricted tag"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/projects/{project_id}/codebases [post]
 func (c *CodebaseController) CreateCodebase(ctx *gin.Context) {
 	// Get the validated request from context (set by validation middleware)
 	request, exists := middleware.GetValidatedRequest[models.CreateCodebaseRequest](ctx)
 	if !exists {
-		errorResponse := models.ErrorResponse{
-			Code:    http.StatusBadRequest,
-			Message: "Missing validated request",
-			Details: "Validation middleware must be applied before this controller",
-		}
-		ctx.JSON(http.StatusBadRequest, errorResponse)
+		respondWithError(ctx, errMissingValidatedRequest)
 		return
 	}
+	request.UserID = middleware.GetUserID(ctx)
 
 	// Call the service to create the codebase
 	response, err := c.codebaseService.CreateCodebase(ctx.Request.Context(), request)
 	if err != nil {
-		errorResponse := models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "Failed to create codebase",
-			Details: err.Error(),
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse)
+		respondWithError(ctx, err)
 		return
 	}
 
 	ctx.JSON(http.StatusCreated, response)
 }
 
+// CreateCodebaseUpload handles POST /projects/:project_id/codebases/uploads
+// @Summary Create a codebase from an uploaded archive
+// @Description Create a codebase for code that isn't hosted on a reachable git remote. The response holds a presigned URL the ZIP file or tarball is uploaded to with an HTTP PUT, sending the returned headers and exactly the requested size, before the URL expires. Once uploaded, the upload is completed to extract the archive and activate the codebase. The codebase and its archive are deleted when the codebase expires.
+// @Tags codebases
+// @Accept json
+// @Produce json
+// @Param project_id path string true "Project ID"
+// @Param request body models.CreateCodebaseUploadRequest true "Uploaded codebase creation request"
+// @Success 201 {object} models.CreateCodebaseUploadResponse "Codebase created, awaiting its archive"
+// @Failure 400 {object} models.ProblemDetails "Invalid request or unsupported archive extension"
+// @Failure 403 {object} models.ProblemDetails "Caller may not modify a restricted tag"
+// @Failure 413 {object} models.ProblemDetails "Archive larger than the size limit"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/projects/{project_id}/codebases/uploads [post]
+func (c *CodebaseController) CreateCodebaseUpload(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.CreateCodebaseUploadRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+	request.UserID = middleware.GetUserID(ctx)
+
+	response, err := c.uploadService.CreateCodebaseUpload(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// CompleteCodebaseUpload handles POST /codebases/:id/upload/complete
+// @Summary Complete the upload of a codebase's archive
+// @Description Check that the archive of an uploaded codebase was uploaded and can be extracted, then activate the codebase for analyses and agents. An archive that can't be extracted, or extracts to more than the size limit, is deleted. Completing an active codebase returns it unchanged.
+// @Tags codebases
+// @Produce json
+// @Param id path string true "Codebase ID"
+// @Success 200 {object} models.GetCodebaseResponse "Codebase activated"
+// @Failure 400 {object} models.ProblemDetails "Not an uploaded codebase, or an archive that can't be extracted"
+// @Failure 404 {object} models.ProblemDetails "Codebase not found"
+// @Failure 409 {object} models.ProblemDetails "Archive not uploaded yet"
+// @Failure 413 {object} models.ProblemDetails "Archive larger than the size limit"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/codebases/{id}/upload/complete [post]
+func (c *CodebaseController) CompleteCodebaseUpload(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.CompleteCodebaseUploadRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.uploadService.CompleteCodebaseUpload(ctx.Request.Context(), request.CodebaseID)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
 // GetCodebase handles GET /codebases/:id
 // @Summary Get a codebase by ID
 // @Description Retrieve a codebase by its unique identifier
 // @Tags codebases
 // @Produce json
 // @Param id path string true "Codebase ID"
+// @Param fields query string false "Comma-separated JSON fields to return; dotted paths select nested fields"
 // @Success 200 {object} models.GetCodebaseResponse "Codebase retrieved successfully"
-// @Failure 400 {object} models.ErrorResponse "Invalid codebase ID"
-// @Failure 404 {object} models.ErrorResponse "Codebase not found"
-// @Failure 500 {object} models.ErrorResponse "Internal server error"
-// @Router /codebases/{id} [get]
+// @Failure 400 {object} models.ProblemDetails "Invalid codebase ID"
+// @Failure 404 {object} models.ProblemDetails "Codebase not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/codebases/{id} [get]
 func (c *CodebaseController) GetCodebase(ctx *gin.Context) {
 	// Get the validated request from context (set by validation middleware)
 	request, exists := middleware.GetValidatedRequest[models.GetCodebaseRequest](ctx)
 	if !exists {
-		errorResponse := models.ErrorResponse{
-			Code:    http.StatusBadRequest,
-			Message: "Missing validated request",
-			Details: "Validation middleware must be applied before this controller",
-		}
-		ctx.JSON(http.StatusBadRequest, errorResponse)
+		respondWithError(ctx, errMissingValidatedRequest)
 		return
 	}
 
 	// Call the service to get the codebase
 	response, err := c.codebaseService.GetCodebase(ctx.Request.Context(), request.CodebaseID)
 	if err != nil {
-		var statusCode int
-		var message string
-
-		// Check if it's a "not found" error
-		if err.Error() == "codebase not found" {
-			statusCode = http.StatusNotFound
-			message = "Codebase not found"
-		} else {
-			statusCode = http.StatusInternalServerError
-			message = "Failed to get codebase"
-		}
-
-		errorResponse := models.ErrorResponse{
-			Code:    statusCode,
-			Message: message,
-			Details: err.Error(),
-		}
-		ctx.JSON(statusCode, errorResponse)
+		respondWithError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response)
+	respondWithFields(ctx, http.StatusOK, response)
 }
 
 // UpdateCodebase handles PUT /codebases/:id
@@ -122,44 +172,24 @@ func (c *CodebaseController) GetCodebase(ctx *gin.Context) {
 // @Param id path string true "Codebase ID"
 // @Param request body models.UpdateCodebaseRequest true "Codebase update request"
 // @Success 200 {object} models.UpdateCodebaseResponse "Codebase updated successfully"
-// @Failure 400 {object} models.ErrorResponse "Invalid request"
-// @Failure 404 {object} models.ErrorResponse "Codebase not found"
-// @Failure 500 {object} models.ErrorResponse "Internal server error"
-// @Router /codebases/{id} [put]
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 403 {object} models.ProblemDetails "Caller may not modify a restricted tag"
+// @Failure 404 {object} models.ProblemDetails "Codebase not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/codebases/{id} [put]
 func (c *CodebaseController) UpdateCodebase(ctx *gin.Context) {
 	// Get the validated request from context (set by validation middleware)
 	request, exists := middleware.GetValidatedRequest[models.UpdateCodebaseRequest](ctx)
 	if !exists {
-		errorResponse := models.ErrorResponse{
-			Code:    http.StatusBadRequest,
-			Message: "Missing validated request",
-			Details: "Validation middleware must be applied before this controller",
-		}
-		ctx.JSON(http.StatusBadRequest, errorResponse)
+		respondWithError(ctx, errMissingValidatedRequest)
 		return
 	}
+	request.UserID = middleware.GetUserID(ctx)
 
 	// Call the service to update the codebase
 	response, err := c.codebaseService.UpdateCodebase(ctx.Request.Context(), request)
 	if err != nil {
-		var statusCode int
-		var message string
-
-		// Check if it's a "not found" error
-		if err.Error() == "codebase not found" {
-			statusCode = http.StatusNotFound
-			message = "Codebase not found"
-		} else {
-			statusCode = http.StatusInternalServerError
-			message = "Failed to update codebase"
-		}
-
-		errorResponse := models.ErrorResponse{
-			Code:    statusCode,
-			Message: message,
-			Details: err.Error(),
-		}
-		ctx.JSON(statusCode, errorResponse)
+		respondWithError(ctx, err)
 		return
 	}
 
@@ -173,44 +203,22 @@ func (c *CodebaseController) UpdateCodebase(ctx *gin.Context) {
 // @Produce json
 // @Param id path string true "Codebase ID"
 // @Success 200 {object} models.DeleteCodebaseResponse "Codebase deleted successfully"
-// @Failure 400 {object} models.ErrorResponse "Invalid codebase ID"
-// @Failure 404 {object} models.ErrorResponse "Codebase not found"
-// @Failure 500 {object} models.ErrorResponse "Internal server error"
-// @Router /codebases/{id} [delete]
+// @Failure 400 {object} models.ProblemDetails "Invalid codebase ID"
+// @Failure 404 {object} models.ProblemDetails "Codebase not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/codebases/{id} [delete]
 func (c *CodebaseController) DeleteCodebase(ctx *gin.Context) {
 	// Get the validated request from context (set by validation middleware)
 	request, exists := middleware.GetValidatedRequest[models.DeleteCodebaseRequest](ctx)
 	if !exists {
-		errorResponse := models.ErrorResponse{
-			Code:    http.StatusBadRequest,
-			Message: "Missing validated request",
-			Details: "Validation middleware must be applied before this controller",
-		}
-		ctx.JSON(http.StatusBadRequest, errorResponse)
+		respondWithError(ctx, errMissingValidatedRequest)
 		return
 	}
 
 	// Call the service to delete the codebase
 	response, err := c.codebaseService.DeleteCodebase(ctx.Request.Context(), request.CodebaseID)
 	if err != nil {
-		var statusCode int
-		var message string
-
-		// Check if it's a "not found" error
-		if err.Error() == "codebase not found" {
-			statusCode = http.StatusNotFound
-			message = "Codebase not found"
-		} else {
-			statusCode = http.StatusInternalServerError
-			message = "Failed to delete codebase"
-		}
-
-		errorResponse := models.ErrorResponse{
-			Code:    statusCode,
-			Message: message,
-			Details: err.Error(),
-		}
-		ctx.JSON(statusCode, errorResponse)
+		respondWithError(ctx, err)
 		return
 	}
 
@@ -226,34 +234,228 @@ func (c *CodebaseController) DeleteCodebase(ctx *gin.Context) {
 // @Param tag_filter query string false "Tag filter in format key:value"
 // @Param next_token query string false "Token for pagination"
 // @Param max_results query int false "Maximum number of results to return" minimum(1) maximum(100)
+// @Param fields query string false "Comma-separated JSON fields to return; dotted paths select nested fields"
 // @Success 200 {object} models.ListCodebasesResponse "Codebases retrieved successfully"
-// @Failure 400 {object} models.ErrorResponse "Invalid request parameters"
-// @Failure 500 {object} models.ErrorResponse "Internal server error"
-// @Router /codebases [get]
+// @Failure 400 {object} models.ProblemDetails "Invalid request parameters"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/codebases [get]
 func (c *CodebaseController) ListCodebases(ctx *gin.Context) {
 	// Get the validated request from context (set by validation middleware)
 	request, exists := middleware.GetValidatedRequest[models.ListCodebasesRequest](ctx)
 	if !exists {
-		errorResponse := models.ErrorResponse{
-			Code:    http.StatusBadRequest,
-			Message: "Missing validated request",
-			Details: "Validation middleware must be applied before this controller",
-		}
-		ctx.JSON(http.StatusBadRequest, errorResponse)
+		respondWithError(ctx, errMissingValidatedRequest)
 		return
 	}
 
 	// Call the service to list codebases
 	response, err := c.codebaseService.ListCodebases(ctx.Request.Context(), request)
 	if err != nil {
-		errorResponse := models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "Failed to list codebases",
-			Details: err.Error(),
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse)
+		respondWithError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response)
+	respondWithListFields(ctx, http.StatusOK, response, "codebases")
+}
+
+// ListBranches handles GET /codebases/:id/branches
+// @Summary List the branches of a codebase
+// @Description List the branches of the codebase's repository through its git provider, using the stored credentials
+// @Tags codebases
+// @Produce json
+// @Param id path string true "Codebase ID"
+// @Success 200 {object} models.ListCodebaseBranchesResponse "Branches retrieved successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid codebase ID or unsupported provider"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "Codebase or repository not found"
+// @Failure 502 {object} models.ProblemDetails "Git provider request failed"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/codebases/{id}/branches [get]
+func (c *CodebaseController) ListBranches(ctx *gin.Context) {
+	request, exists := middleware.GetValidatedRequest[models.ListCodebaseBranchesRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.browseService.ListBranches(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, response)
+}
+
+// ListCommits handles GET /codebases/:id/commits
+// @Summary List the commits of a codebase
+// @Description List the latest commits of a branch, tag or commit of the codebase's repository through its git provider
+// @Tags codebases
+// @Produce json
+// @Param id path string true "Codebase ID"
+// @Param ref query string false "Branch, tag or commit SHA; defaults to the default branch"
+// @Param limit query int false "Maximum number of commits to return (1-100, default 30)"
+// @Success 200 {object} models.ListCodebaseCommitsResponse "Commits retrieved successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request or unsupported provider"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "Codebase, repository or ref not found"
+// @Failure 502 {object} models.ProblemDetails "Git provider request failed"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/codebases/{id}/commits [get]
+func (c *CodebaseController) ListCommits(ctx *gin.Context) {
+	request, exists := middleware.GetValidatedRequest[models.ListCodebaseCommitsRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.browseService.ListCommits(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, response)
+}
+
+// ListFiles handles GET /codebases/:id/files
+// @Summary List the files of a codebase
+// @Description List a directory of the codebase's repository at a branch, tag or commit through its git provider
+// @Tags codebases
+// @Produce json
+// @Param id path string true "Codebase ID"
+// @Param path query string false "Directory to list; defaults to the repository root"
+// @Param ref query string false "Branch, tag or commit SHA; defaults to the default branch"
+// @Success 200 {object} models.ListCodebaseFilesResponse "Files retrieved successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request or unsupported provider"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "Codebase, repository, ref or path not found"
+// @Failure 502 {object} models.ProblemDetails "Git provider request failed"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/codebases/{id}/files [get]
+func (c *CodebaseController) ListFiles(ctx *gin.Context) {
+	request, exists := middleware.GetValidatedRequest[models.ListCodebaseFilesRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.browseService.ListFiles(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, response)
+}
+
+// ListDependencyFindings handles GET /codebases/:id/dependency-findings
+// @Summary List the vulnerable dependencies of a codebase
+// @Description List the published vulnerabilities affecting the dependencies found by the codebase's latest dependency_audit task, most severe first
+// @Tags codebases
+// @Produce json
+// @Param id path string true "Codebase ID"
+// @Param severity query string false "Only list findings of this severity" Enums(critical, high, medium, low, unknown)
+// @Success 200 {object} models.ListDependencyFindingsResponse "Findings retrieved successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 404 {object} models.ProblemDetails "Codebase not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/codebases/{id}/dependency-findings [get]
+func (c *CodebaseController) ListDependencyFindings(ctx *gin.Context) {
+	request, exists := middleware.GetValidatedRequest[models.ListDependencyFindingsRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.auditService.ListFindings(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, response)
+}
+
+// GetMetrics handles GET /codebases/:id/metrics
+// @Summary Get the maintainability metrics history of a codebase
+// @Description Get the complexity, function length and package coupling snapshots that code_analysis tasks recorded per commit, oldest first, and how they changed over the period
+// @Tags codebases
+// @Produce json
+// @Param id path string true "Codebase ID"
+// @Param since query string false "First day of the history (YYYY-MM-DD); defaults to every snapshot"
+// @Success 200 {object} models.GetCodeMetricsResponse "Metrics retrieved successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 404 {object} models.ProblemDetails "Codebase not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/codebases/{id}/metrics [get]
+func (c *CodebaseController) GetMetrics(ctx *gin.Context) {
+	request, exists := middleware.GetValidatedRequest[models.GetCodeMetricsRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.metricsService.GetMetrics(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, response)
+}
+
+// ScanCodebase handles POST /codebases/:id/scan
+// @Summary Scan a codebase before it is ingested
+// @Description Scan the head of the codebase's default branch for committed secrets and incompatible licenses and replace its findings. High severity findings set the codebase to ingestion_blocked, with the reason in ingestion_error, until a scan comes back clean; agent tasks don't run against a blocked codebase
+// @Tags codebases
+// @Produce json
+// @Param id path string true "Codebase ID"
+// @Success 200 {object} models.ScanCodebaseResponse "Codebase scanned successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 404 {object} models.ProblemDetails "Codebase not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/codebases/{id}/scan [post]
+func (c *CodebaseController) ScanCodebase(ctx *gin.Context) {
+	request, exists := middleware.GetValidatedRequest[models.ScanCodebaseRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.scanService.ScanCodebase(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, response)
+}
+
+// ListScanFindings handles GET /codebases/:id/scan-findings
+// @Summary List the ingestion scan findings of a codebase
+// @Description List the committed secrets and incompatible licenses found by the codebase's latest ingestion scan, by file and line. Secrets themselves are never returned
+// @Tags codebases
+// @Produce json
+// @Param id path string true "Codebase ID"
+// @Param kind query string false "Only list findings of this kind" Enums(secret, license)
+// @Param severity query string false "Only list findings of this severity" Enums(high, medium)
+// @Success 200 {object} models.ListScanFindingsResponse "Findings retrieved successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 404 {object} models.ProblemDetails "Codebase not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/codebases/{id}/scan-findings [get]
+func (c *CodebaseController) ListScanFindings(ctx *gin.Context) {
+	request, exists := middleware.GetValidatedRequest[models.ListScanFindingsRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.scanService.ListFindings(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, response)
 }
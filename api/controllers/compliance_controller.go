@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/services"
+)
+
+// ComplianceController handles the HTTP requests exporting users, access grants and audit events
+type ComplianceController struct {
+	complianceService services.ComplianceService
+}
+
+// NewComplianceController creates a new ComplianceController
+func NewComplianceController(complianceService services.ComplianceService) *ComplianceController {
+	return &ComplianceController{
+		complianceService: complianceService,
+	}
+}
+
+// ExportUsers handles GET /admin/exports/users
+// @Summary Export users
+// @Description Stream every user created within the time range as newline-delimited JSON or CSV, oldest first. Requires the compliance:export permission.
+// @Tags compliance
+// @Produce application/x-ndjson
+// @Produce text/csv
+// @Param format query string false "Export encoding" Enums(json, csv) default(json)
+// @Param from query string false "Only include users created from this time on, in RFC 3339 format"
+// @Param to query string false "Only include users created before this time, in RFC 3339 format"
+// @Param fields query string false "Comma-separated fields to export; with CSV, the columns in order"
+// @Success 200 {object} models.APIUser "One user per line"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/admin/exports/users [get]
+func (c *ComplianceController) ExportUsers(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.ComplianceExportRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	respondWithFormatExport(ctx, request.Format, "users.csv", func(emit func(models.APIUser) error) error {
+		return c.complianceService.ExportUsers(ctx.Request.Context(), request, emit)
+	})
+}
+
+// ExportAccessGrants handles GET /admin/exports/access-grants
+// @Summary Export project access grants
+// @Description Stream the members of every project whose role last changed within the time range as newline-delimited JSON or CSV, oldest change first. Requires the compliance:export permission.
+// @Tags compliance
+// @Produce application/x-ndjson
+// @Produce text/csv
+// @Param format query string false "Export encoding" Enums(json, csv) default(json)
+// @Param from query string false "Only include grants changed from this time on, in RFC 3339 format"
+// @Param to query string false "Only include grants changed before this time, in RFC 3339 format"
+// @Param fields query string false "Comma-separated fields to export; with CSV, the columns in order"
+// @Success 200 {object} models.ProjectMember "One access grant per line"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/admin/exports/access-grants [get]
+func (c *ComplianceController) ExportAccessGrants(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.ComplianceExportRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	respondWithFormatExport(ctx, request.Format, "access-grants.csv", func(emit func(models.ProjectMember) error) error {
+		return c.complianceService.ExportAccessGrants(ctx.Request.Context(), request, emit)
+	})
+}
+
+// ExportAuditEvents handles GET /admin/exports/audit-events
+// @Summary Export audit events
+// @Description Stream the changes to roles, project members and project ownership that occurred within the time range as newline-delimited JSON or CSV, oldest first. Requires the audit:read permission.
+// @Tags compliance
+// @Produce application/x-ndjson
+// @Produce text/csv
+// @Param format query string false "Export encoding" Enums(json, csv) default(json)
+// @Param from query string false "Only include events from this time on, in RFC 3339 format"
+// @Param to query string false "Only include events before this time, in RFC 3339 format"
+// @Param fields query string false "Comma-separated fields to export; with CSV, the columns in order"
+// @Success 200 {object} models.AuditEvent "One audit event per line"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/admin/exports/audit-events [get]
+func (c *ComplianceController) ExportAuditEvents(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.ComplianceExportRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	respondWithFormatExport(ctx, request.Format, "audit-events.csv", func(emit func(models.AuditEvent) error) error {
+		return c.complianceService.ExportAuditEvents(ctx.Request.Context(), request, emit)
+	})
+}
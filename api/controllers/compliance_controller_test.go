@@ -0,0 +1,146 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	servicesMocks "github.com/kazemisoroush/code-refactoring-tool/api/services/mocks"
+)
+
+func setupComplianceRouter(controller *ComplianceController) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.NewErrorHandlerMiddleware().Handle())
+	router.GET("/admin/exports/users",
+		middleware.NewQueryValidationMiddleware[models.ComplianceExportRequest]().Handle(),
+		controller.ExportUsers,
+	)
+	router.GET("/admin/exports/audit-events",
+		middleware.NewQueryValidationMiddleware[models.ComplianceExportRequest]().Handle(),
+		controller.ExportAuditEvents,
+	)
+	return router
+}
+
+func TestComplianceController_ExportUsers_CSV(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := servicesMocks.NewMockComplianceService(ctrl)
+	router := setupComplianceRouter(NewComplianceController(mockService))
+
+	from := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	mockService.EXPECT().
+		ExportUsers(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, request models.ComplianceExportRequest, fn func(models.APIUser) error) error {
+			assert.Equal(t, models.ExportFormatCSV, request.Format)
+			assert.True(t, from.Equal(*request.From))
+			assert.Nil(t, request.To)
+			if err := fn(models.APIUser{UserID: "usr-1", Email: "ada@example.com", Role: models.RoleAdmin}); err != nil {
+				return err
+			}
+			return fn(models.APIUser{UserID: "usr-2", Email: "=HYPERLINK(\"x\")", Role: models.RoleViewer})
+		})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/exports/users?format=csv&from=2024-01-01T00:00:00Z&fields=email,role,user_id", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, models.CSVContentType, w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="users.csv"`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, "email,role,user_id\nada@example.com,admin,usr-1\n\"'=HYPERLINK(\"\"x\"\")\",viewer,usr-2\n", w.Body.String())
+}
+
+func TestComplianceController_ExportAuditEvents_JSON(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := servicesMocks.NewMockComplianceService(ctrl)
+	router := setupComplianceRouter(NewComplianceController(mockService))
+
+	mockService.EXPECT().
+		ExportAuditEvents(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ models.ComplianceExportRequest, fn func(models.AuditEvent) error) error {
+			return fn(models.AuditEvent{EventID: "audit-1", Action: models.AuditActionRoleDeleted, TargetType: "role", TargetID: "security-reviewer"})
+		})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/exports/audit-events?fields=event_id,action", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, models.NDJSONContentType, w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"event_id":"audit-1","action":"role.deleted"}`, w.Body.String())
+}
+
+func TestComplianceController_ExportUsers_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		target         string
+		expectService  bool
+		serviceErr     error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "unknown CSV column",
+			target:         "/admin/exports/users?format=csv&fields=password",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown format",
+			target:         "/admin/exports/users?format=xml",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "malformed time",
+			target:         "/admin/exports/users?from=yesterday",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "failure after streaming started",
+			target:         "/admin/exports/users?format=csv&fields=user_id",
+			expectService:  true,
+			serviceErr:     errors.New("connection reset"),
+			expectedStatus: http.StatusOK,
+			expectedBody:   "user_id\nusr-1\n#error,connection reset\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := servicesMocks.NewMockComplianceService(ctrl)
+			router := setupComplianceRouter(NewComplianceController(mockService))
+			if tt.expectService {
+				mockService.EXPECT().
+					ExportUsers(gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ models.ComplianceExportRequest, fn func(models.APIUser) error) error {
+						if err := fn(models.APIUser{UserID: "usr-1"}); err != nil {
+							return err
+						}
+						return tt.serviceErr
+					})
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
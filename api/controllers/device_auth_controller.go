@@ -0,0 +1,146 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/services"
+)
+
+// DeviceAuthController handles device authorization flow HTTP requests
+type DeviceAuthController struct {
+	deviceAuthService services.DeviceAuthService
+}
+
+// NewDeviceAuthController creates a new DeviceAuthController
+func NewDeviceAuthController(deviceAuthService services.DeviceAuthService) *DeviceAuthController {
+	return &DeviceAuthController{
+		deviceAuthService: deviceAuthService,
+	}
+}
+
+// StartDeviceAuth handles POST /auth/device/start
+// @Summary Start a device sign in
+// @Description Start signing in a device without a browser, such as the CLI. The device shows the user code and verification URI to the user, then polls /auth/device/token with the device code.
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param request body models.StartDeviceAuthRequest true "Device sign in request"
+// @Success 200 {object} models.StartDeviceAuthResponse
+// @Failure 400 {object} models.ProblemDetails
+// @Failure 500 {object} models.ProblemDetails
+// @Router /auth/device/start [post]
+func (c *DeviceAuthController) StartDeviceAuth(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.StartDeviceAuthRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.deviceAuthService.StartDeviceAuth(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// PollDeviceToken handles POST /auth/device/token
+// @Summary Poll for the tokens of a device sign in
+// @Description Return the tokens of an approved device sign in, only once. Until then, fails with the authorization_pending problem type, or slow_down when polled more often than the interval, after which the device waits 5 seconds longer between polls. Denied and expired sign ins fail with access_denied and expired_token.
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param request body models.DeviceTokenRequest true "Device token request"
+// @Success 200 {object} models.SignInResponse
+// @Failure 400 {object} models.ProblemDetails "Sign in pending, denied or expired, or device code unknown"
+// @Failure 500 {object} models.ProblemDetails
+// @Router /auth/device/token [post]
+func (c *DeviceAuthController) PollDeviceToken(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.DeviceTokenRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.deviceAuthService.PollDeviceToken(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// ApproveDevice handles POST /auth/device/approve
+// @Summary Approve a device sign in
+// @Description Approve the pending device sign in with the user code, signing the device in as the current authenticated user with tokens refreshed from their refresh token
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body models.ApproveDeviceRequest true "Device approval request"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ProblemDetails
+// @Failure 401 {object} models.ProblemDetails
+// @Failure 403 {object} models.ProblemDetails "Refresh token of another user"
+// @Failure 404 {object} models.ProblemDetails "No pending sign in with the user code"
+// @Failure 500 {object} models.ProblemDetails
+// @Router /auth/device/approve [post]
+func (c *DeviceAuthController) ApproveDevice(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.ApproveDeviceRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	// The caller can only sign devices in as themselves
+	request.AuthID = middleware.GetUserID(ctx)
+
+	if err := c.deviceAuthService.ApproveDevice(ctx.Request.Context(), request); err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Device signed in",
+	})
+}
+
+// DenyDevice handles POST /auth/device/deny
+// @Summary Deny a device sign in
+// @Description Deny the pending device sign in with the user code
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body models.DenyDeviceRequest true "Device denial request"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ProblemDetails
+// @Failure 401 {object} models.ProblemDetails
+// @Failure 404 {object} models.ProblemDetails "No pending sign in with the user code"
+// @Failure 500 {object} models.ProblemDetails
+// @Router /auth/device/deny [post]
+func (c *DeviceAuthController) DenyDevice(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.DenyDeviceRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	if err := c.deviceAuthService.DenyDevice(ctx.Request.Context(), request); err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Device sign in denied",
+	})
+}
@@ -0,0 +1,24 @@
+package controllers
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+)
+
+var (
+	// errMissingValidatedRequest is reported when a route is registered without its validation middleware
+	errMissingValidatedRequest = apperrors.Validation(apperrors.CodeMissingValidatedRequest, "validation middleware must be applied before this controller")
+	// errInvalidRequestType is reported when the validated request has an unexpected type
+	errInvalidRequestType = errors.New("invalid request type")
+	// errUnauthenticatedCaller is reported when an endpoint acting on behalf of the caller has no authenticated user
+	errUnauthenticatedCaller = apperrors.Unauthorized(apperrors.CodeUnauthorized, "this endpoint requires an authenticated user")
+)
+
+// respondWithError records err on the context and stops the handler chain.
+// The error handling middleware renders it as an RFC 7807 problem response.
+func respondWithError(ctx *gin.Context, err error) {
+	_ = ctx.Error(err)
+	ctx.Abort()
+}
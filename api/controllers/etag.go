@@ -0,0 +1,31 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// setETag reports the version of the returned resource in the ETag header
+func setETag(ctx *gin.Context, version int64) {
+	if version > 0 {
+		ctx.Header(models.ETagHeader, models.ETag(version))
+	}
+}
+
+// ifMatchVersion returns the resource version named by the If-Match header.
+// Updates must be conditional so concurrent writers cannot silently overwrite each other.
+func ifMatchVersion(ctx *gin.Context) (int64, error) {
+	header := ctx.GetHeader(models.IfMatchHeader)
+	if header == "" {
+		return 0, apperrors.New(apperrors.ErrPreconditionRequired, apperrors.CodePreconditionRequired,
+			"%s header is required; send the ETag returned by GET", models.IfMatchHeader)
+	}
+
+	version, err := models.ParseETag(header)
+	if err != nil {
+		return 0, apperrors.Wrap(apperrors.ErrValidation, apperrors.CodeInvalidRequest, err, "invalid %s header", models.IfMatchHeader)
+	}
+
+	return version, nil
+}
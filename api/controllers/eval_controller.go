@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/services"
+)
+
+// EvalController handles the HTTP requests evaluating the local agent against golden repositories
+type EvalController struct {
+	evalService services.EvalService
+}
+
+// NewEvalController creates a new EvalController
+func NewEvalController(evalService services.EvalService) *EvalController {
+	return &EvalController{
+		evalService: evalService,
+	}
+}
+
+// RunEval handles POST /admin/evals/run
+// @Summary Start an evaluation run
+// @Description Run the local agent with a model and prompt version against the golden repositories, then build, test and score each change against the fixture's expected outcome. The fixtures are evaluated in the background; follow the run with GET /admin/evals/runs/{id}.
+// @Tags evals
+// @Accept json
+// @Produce json
+// @Param request body models.RunEvalRequest true "Evaluation run request"
+// @Success 202 {object} models.EvalRun "Evaluation run started"
+// @Failure 400 {object} models.ProblemDetails "Invalid request, unknown fixture or prompt version"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/admin/evals/run [post]
+func (c *EvalController) RunEval(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.RunEvalRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+	request.StartedBy = middleware.GetUserID(ctx)
+
+	run, err := c.evalService.RunEval(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, run)
+}
+
+// GetEvalRun handles GET /admin/evals/runs/:id
+// @Summary Get an evaluation run
+// @Description Retrieve an evaluation run's progress, summary and the outcome of each fixture
+// @Tags evals
+// @Produce json
+// @Param id path string true "Run ID"
+// @Param fields query string false "Comma-separated JSON fields to return; dotted paths select nested fields"
+// @Success 200 {object} models.EvalRun "Evaluation run retrieved successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid run ID"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "Evaluation run not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/admin/evals/runs/{id} [get]
+func (c *EvalController) GetEvalRun(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.GetEvalRunRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	run, err := c.evalService.GetRun(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, run)
+}
+
+// ListEvalRuns handles GET /admin/evals/runs
+// @Summary List evaluation runs
+// @Description List the most recent evaluation runs with their summaries, without the outcome of each fixture
+// @Tags evals
+// @Produce json
+// @Param limit query int false "Maximum number of runs to return" default(20)
+// @Success 200 {object} models.ListEvalRunsResponse "Evaluation runs listed successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/admin/evals/runs [get]
+func (c *EvalController) ListEvalRuns(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.ListEvalRunsRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.evalService.ListRuns(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// CompareEvalRuns handles GET /admin/evals/compare
+// @Summary Compare evaluation runs
+// @Description Compare the compile and test pass rates and diff quality of a candidate run with a baseline over the fixtures both evaluated, listing the fixtures the candidate regressed on
+// @Tags evals
+// @Produce json
+// @Param baseline query string true "Baseline run ID"
+// @Param candidate query string true "Candidate run ID"
+// @Success 200 {object} models.EvalComparison "Evaluation runs compared successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "Evaluation run not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/admin/evals/compare [get]
+func (c *EvalController) CompareEvalRuns(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.CompareEvalRunsRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	comparison, err := c.evalService.CompareRuns(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, comparison)
+}
@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	servicesMocks "github.com/kazemisoroush/code-refactoring-tool/api/services/mocks"
+)
+
+func TestEvalController_RunEval(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := servicesMocks.NewMockEvalService(ctrl)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.NewErrorHandlerMiddleware().Handle())
+	router.POST("/admin/evals/run", func(c *gin.Context) {
+		c.Set(middleware.UserIDContextKey, "user-1")
+	}, middleware.NewJSONValidationMiddleware[models.RunEvalRequest]().Handle(), NewEvalController(mockService).RunEval)
+
+	mockService.EXPECT().RunEval(gomock.Any(), models.RunEvalRequest{
+		Model: "qwen2.5-coder:7b", PromptVersion: "v2", StartedBy: "user-1",
+	}).Return(&models.EvalRun{RunID: "eval-1", Status: models.EvalRunStatusRunning}, nil)
+
+	body, _ := json.Marshal(map[string]any{"model": "qwen2.5-coder:7b", "prompt_version": "v2"})
+	req := httptest.NewRequest(http.MethodPost, "/admin/evals/run", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	var response models.EvalRun
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "eval-1", response.RunID)
+}
+
+func TestEvalController_CompareEvalRuns(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := servicesMocks.NewMockEvalService(ctrl)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.NewErrorHandlerMiddleware().Handle())
+	router.GET("/admin/evals/compare",
+		middleware.NewQueryValidationMiddleware[models.CompareEvalRunsRequest]().Handle(),
+		NewEvalController(mockService).CompareEvalRuns)
+
+	mockService.EXPECT().CompareRuns(gomock.Any(), models.CompareEvalRunsRequest{Baseline: "eval-1", Candidate: "missing"}).
+		Return(nil, apperrors.NotFound(apperrors.CodeEvalRunNotFound, "eval run not found: missing"))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/evals/compare?baseline=eval-1&candidate=missing", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/evals/compare?baseline=eval-1", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code, "both runs are required")
+}
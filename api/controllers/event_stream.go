@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// respondWithEventStream streams the events passed by stream to the response as server-sent events, flushing each
+// one so the client sees the model's output as it's generated rather than once it's done. The value stream returns is
+// sent as the last, result event. A stream failing before its first event gets a problem response; one failing later
+// ends with an error event, since its status was already sent.
+func respondWithEventStream[T any](ctx *gin.Context, stream func(emit func(models.StreamEvent) error) (T, error)) {
+	started := false
+	emit := func(event models.StreamEvent) error {
+		if !started {
+			ctx.Header("Content-Type", models.EventStreamContentType)
+			ctx.Header("Cache-Control", "no-cache")
+			// Keeps proxies such as nginx from buffering the stream
+			ctx.Header("X-Accel-Buffering", "no")
+			ctx.Status(http.StatusOK)
+			started = true
+		}
+
+		data, err := json.Marshal(event.Data)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s event: %w", event.Event, err)
+		}
+		if _, err := fmt.Fprintf(ctx.Writer, "event: %s\ndata: %s\n\n", event.Event, data); err != nil {
+			return fmt.Errorf("failed to write %s event: %w", event.Event, err)
+		}
+		ctx.Writer.Flush()
+		return nil
+	}
+
+	result, err := stream(emit)
+	if err != nil && !started {
+		respondWithError(ctx, err)
+		return
+	}
+	if err != nil {
+		slog.WarnContext(ctx.Request.Context(), "stream failed after it started", "path", ctx.Request.URL.Path, "error", err)
+		err = emit(models.StreamEvent{Event: models.StreamEventError, Data: middleware.NewProblem(err, ctx.Request.URL.Path)})
+	} else {
+		err = emit(models.StreamEvent{Event: models.StreamEventResult, Data: result})
+	}
+	if err != nil {
+		slog.WarnContext(ctx.Request.Context(), "failed to end stream", "path", ctx.Request.URL.Path, "error", err)
+	}
+}
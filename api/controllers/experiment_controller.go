@@ -0,0 +1,160 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/services"
+)
+
+// ExperimentController handles the HTTP requests comparing a variant of the local agent with its control on live tasks
+type ExperimentController struct {
+	experimentService services.ExperimentService
+}
+
+// NewExperimentController creates a new ExperimentController
+func NewExperimentController(experimentService services.ExperimentService) *ExperimentController {
+	return &ExperimentController{
+		experimentService: experimentService,
+	}
+}
+
+// CreateExperiment handles POST /admin/experiments
+// @Summary Start an experiment
+// @Description Route a percentage of the live tasks of the given types to a variant model or prompt version of the local agent, the rest running on the configured control. Each task records the experiment and arm it ran on. A task type is covered by at most one running experiment.
+// @Tags experiments
+// @Accept json
+// @Produce json
+// @Param request body models.CreateExperimentRequest true "Experiment creation request"
+// @Success 201 {object} models.Experiment "Experiment started"
+// @Failure 400 {object} models.ProblemDetails "Invalid request, unknown prompt version or variant identical to the control"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 409 {object} models.ProblemDetails "A running experiment already covers one of the task types"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/admin/experiments [post]
+func (c *ExperimentController) CreateExperiment(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.CreateExperimentRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+	request.CreatedBy = middleware.GetUserID(ctx)
+
+	experiment, err := c.experimentService.CreateExperiment(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, experiment)
+}
+
+// GetExperiment handles GET /admin/experiments/:id
+// @Summary Get an experiment
+// @Description Retrieve an experiment's variant, traffic share and status
+// @Tags experiments
+// @Produce json
+// @Param id path string true "Experiment ID"
+// @Success 200 {object} models.Experiment "Experiment retrieved successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid experiment ID"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "Experiment not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/admin/experiments/{id} [get]
+func (c *ExperimentController) GetExperiment(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.GetExperimentRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	experiment, err := c.experimentService.GetExperiment(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, experiment)
+}
+
+// ListExperiments handles GET /admin/experiments
+// @Summary List experiments
+// @Description List the running and stopped experiments, newest first
+// @Tags experiments
+// @Produce json
+// @Success 200 {object} models.ListExperimentsResponse "Experiments listed successfully"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/admin/experiments [get]
+func (c *ExperimentController) ListExperiments(ctx *gin.Context) {
+	response, err := c.experimentService.ListExperiments(ctx.Request.Context())
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// StopExperiment handles POST /admin/experiments/:id/stop
+// @Summary Stop an experiment
+// @Description Stop assigning tasks to an experiment. The tasks assigned already keep their arm and stay in the report.
+// @Tags experiments
+// @Produce json
+// @Param id path string true "Experiment ID"
+// @Success 200 {object} models.Experiment "Experiment stopped"
+// @Failure 400 {object} models.ProblemDetails "Invalid experiment ID"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "Experiment not found"
+// @Failure 409 {object} models.ProblemDetails "Experiment is stopped already"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/admin/experiments/{id}/stop [post]
+func (c *ExperimentController) StopExperiment(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.StopExperimentRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	experiment, err := c.experimentService.StopExperiment(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, experiment)
+}
+
+// GetExperimentReport handles GET /admin/experiments/:id/report
+// @Summary Compare an experiment's variant with its control
+// @Description Compare the success rate, mean execution time and approval rate of the tasks that ran on the variant with those that ran on the control. Approvals are recorded with PUT /tasks/{id}.
+// @Tags experiments
+// @Produce json
+// @Param id path string true "Experiment ID"
+// @Success 200 {object} models.ExperimentReport "Experiment report retrieved successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid experiment ID"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "Experiment not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/admin/experiments/{id}/report [get]
+func (c *ExperimentController) GetExperimentReport(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.GetExperimentReportRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	report, err := c.experimentService.GetExperimentReport(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	servicesMocks "github.com/kazemisoroush/code-refactoring-tool/api/services/mocks"
+)
+
+func TestExperimentController_CreateExperiment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := servicesMocks.NewMockExperimentService(ctrl)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.NewErrorHandlerMiddleware().Handle())
+	router.POST("/admin/experiments", func(c *gin.Context) {
+		c.Set(middleware.UserIDContextKey, "user-1")
+	}, middleware.NewJSONValidationMiddleware[models.CreateExperimentRequest]().Handle(), NewExperimentController(mockService).CreateExperiment)
+
+	mockService.EXPECT().CreateExperiment(gomock.Any(), models.CreateExperimentRequest{
+		Name: "Qwen on refactorings", TaskTypes: []models.TaskType{models.TaskTypeRefactoring}, TrafficPercent: 20, VariantModel: "qwen2.5-coder:7b", CreatedBy: "user-1",
+	}).Return(&models.Experiment{ExperimentID: "exp-1", Status: models.ExperimentStatusRunning}, nil)
+
+	body, _ := json.Marshal(map[string]any{"name": "Qwen on refactorings", "task_types": []string{"refactoring"}, "traffic_percent": 20, "variant_model": "qwen2.5-coder:7b"})
+	req := httptest.NewRequest(http.MethodPost, "/admin/experiments", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var response models.Experiment
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "exp-1", response.ExperimentID)
+
+	body, _ = json.Marshal(map[string]any{"name": "Nothing changes", "traffic_percent": 20})
+	req = httptest.NewRequest(http.MethodPost, "/admin/experiments", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code, "a variant model or prompt version is required")
+}
+
+func TestExperimentController_StopExperiment_AlreadyStopped(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := servicesMocks.NewMockExperimentService(ctrl)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.NewErrorHandlerMiddleware().Handle())
+	router.POST("/admin/experiments/:id/stop",
+		middleware.NewURIValidationMiddleware[models.StopExperimentRequest]().Handle(),
+		NewExperimentController(mockService).StopExperiment)
+
+	mockService.EXPECT().StopExperiment(gomock.Any(), models.StopExperimentRequest{ExperimentID: "exp-1"}).
+		Return(nil, apperrors.Conflict(apperrors.CodeExperimentStopped, "experiment exp-1 is stopped already"))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/experiments/exp-1/stop", nil))
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
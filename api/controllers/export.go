@@ -0,0 +1,249 @@
+package controllers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// exportFlushInterval is the number of exported items written between flushes of the response
+const exportFlushInterval = 100
+
+// respondWithExport streams the items passed by export to the response as newline-delimited JSON, trimmed to the
+// fields requested with ?fields=. Items are written as export produces them, so a slow client slows down the
+// database read instead of buffering the export in memory. An export failing before its first item gets a problem
+// response; one failing later ends with a models.ExportError line, since its status was already sent.
+func respondWithExport[T any](ctx *gin.Context, export func(emit func(T) error) error) {
+	fields := parseFieldSet(ctx.Query(FieldsQueryParam))
+	encoder := json.NewEncoder(ctx.Writer)
+	written := 0
+
+	emit := func(item T) error {
+		if written == 0 {
+			ctx.Header("Content-Type", models.NDJSONContentType)
+			ctx.Status(http.StatusOK)
+		}
+
+		var value any = item
+		if len(fields) > 0 {
+			trimmed, err := trimFields(item, fields)
+			if err != nil {
+				return err
+			}
+			value = trimmed
+		}
+		if err := encoder.Encode(value); err != nil {
+			return fmt.Errorf("failed to write exported item: %w", err)
+		}
+
+		written++
+		if written%exportFlushInterval == 0 {
+			ctx.Writer.Flush()
+		}
+		return nil
+	}
+
+	err := export(emit)
+	if err != nil && written == 0 {
+		respondWithError(ctx, err)
+		return
+	}
+	if err != nil {
+		slog.ErrorContext(ctx.Request.Context(), "export failed after streaming started", "items", written, "error", err)
+		_ = encoder.Encode(models.ExportError{Error: middleware.NewProblem(err, ctx.Request.URL.Path)})
+	}
+	if written == 0 {
+		ctx.Header("Content-Type", models.NDJSONContentType)
+		ctx.Status(http.StatusOK)
+	}
+	ctx.Writer.Flush()
+}
+
+// respondWithFormatExport streams the items passed by export as newline-delimited JSON, or as CSV when format asks
+// for it
+func respondWithFormatExport[T any](ctx *gin.Context, format models.ExportFormat, filename string, export func(emit func(T) error) error) {
+	if format == models.ExportFormatCSV {
+		respondWithCSVExport(ctx, filename, export)
+		return
+	}
+	respondWithExport(ctx, export)
+}
+
+// respondWithCSVExport streams the items passed by export to the response as CSV, with a header row naming the
+// columns. The columns are the top-level JSON fields requested with ?fields=, in order, or all the fields of T.
+// Values that aren't strings, numbers or booleans are written as JSON. An export failing after its first item ends
+// with a row whose first cell is #error and second the problem detail, since its status was already sent.
+func respondWithCSVExport[T any](ctx *gin.Context, filename string, export func(emit func(T) error) error) {
+	columns, err := csvColumns[T](ctx.Query(FieldsQueryParam))
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	writer := csv.NewWriter(ctx.Writer)
+	written := 0
+
+	start := func() error {
+		ctx.Header("Content-Type", models.CSVContentType)
+		ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		ctx.Status(http.StatusOK)
+		return writer.Write(columns)
+	}
+
+	emit := func(item T) error {
+		if written == 0 {
+			if err := start(); err != nil {
+				return fmt.Errorf("failed to write export header: %w", err)
+			}
+		}
+
+		record, err := csvRecord(item, columns)
+		if err != nil {
+			return err
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write exported item: %w", err)
+		}
+
+		written++
+		if written%exportFlushInterval == 0 {
+			writer.Flush()
+			ctx.Writer.Flush()
+		}
+		return nil
+	}
+
+	err = export(emit)
+	if err != nil && written == 0 {
+		respondWithError(ctx, err)
+		return
+	}
+	if err != nil {
+		slog.ErrorContext(ctx.Request.Context(), "export failed after streaming started", "items", written, "error", err)
+		_ = writer.Write([]string{"#error", middleware.NewProblem(err, ctx.Request.URL.Path).Detail})
+	}
+	if written == 0 {
+		if err := start(); err != nil {
+			slog.ErrorContext(ctx.Request.Context(), "failed to write export header", "error", err)
+		}
+	}
+	writer.Flush()
+	ctx.Writer.Flush()
+}
+
+// csvColumns returns the CSV columns selected by a ?fields= value, or all the JSON fields of T when it's empty
+func csvColumns[T any](rawFields string) ([]string, error) {
+	available := jsonFieldNames(reflect.TypeFor[T]())
+
+	if strings.TrimSpace(rawFields) == "" {
+		return available, nil
+	}
+
+	var columns []string
+	for _, field := range strings.Split(rawFields, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if !slices.Contains(available, field) {
+			return nil, apperrors.Validation(apperrors.CodeValidation, "unknown column %q, expected one of %s", field, strings.Join(available, ", "))
+		}
+		if !slices.Contains(columns, field) {
+			columns = append(columns, field)
+		}
+	}
+	return columns, nil
+}
+
+// jsonFieldNames lists the names of the JSON fields of a struct type, in declaration order
+func jsonFieldNames(t reflect.Type) []string {
+	var names []string
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// csvRecord formats the selected columns of item's JSON representation as a CSV record
+func csvRecord(item any, columns []string) ([]string, error) {
+	encoded, err := json.Marshal(item)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode exported item: %w", err)
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil, fmt.Errorf("failed to encode exported item: %w", err)
+	}
+
+	record := make([]string, len(columns))
+	for i, column := range columns {
+		cell, err := csvCell(fields[column])
+		if err != nil {
+			return nil, err
+		}
+		record[i] = cell
+	}
+	return record, nil
+}
+
+// csvCell formats a JSON value as a CSV cell. Text that spreadsheets would evaluate as a formula is prefixed with a
+// quote, so opening an export can't run formulas planted in user-controlled values.
+func csvCell(value any) (string, error) {
+	switch typed := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		if typed != "" && strings.ContainsRune("=+-@\t\r", rune(typed[0])) {
+			return "'" + typed, nil
+		}
+		return typed, nil
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(typed), nil
+	default:
+		encoded, err := json.Marshal(typed)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode exported value: %w", err)
+		}
+		return string(encoded), nil
+	}
+}
+
+// trimFields keeps only the selected fields of item's JSON representation
+func trimFields(item any, fields fieldSet) (any, error) {
+	encoded, err := json.Marshal(item)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode exported item: %w", err)
+	}
+
+	var generic any
+	if err := json.Unmarshal(encoded, &generic); err != nil {
+		return nil, fmt.Errorf("failed to encode exported item: %w", err)
+	}
+
+	return fields.apply(generic), nil
+}
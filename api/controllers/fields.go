@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FieldsQueryParam is the query parameter selecting a sparse fieldset, e.g. ?fields=task_id,status,output.summary
+const FieldsQueryParam = "fields"
+
+// fieldSet is a parsed ?fields= selection. A nil child means the whole value is kept.
+type fieldSet map[string]fieldSet
+
+// parseFieldSet parses a comma separated list of dotted JSON field paths
+func parseFieldSet(raw string) fieldSet {
+	fields := fieldSet{}
+	for _, path := range strings.Split(raw, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+
+		node := fields
+		parts := strings.Split(path, ".")
+		for i, part := range parts {
+			child, exists := node[part]
+			if exists && child == nil {
+				// A parent path was already selected in full
+				break
+			}
+			if i == len(parts)-1 {
+				node[part] = nil
+				break
+			}
+			if !exists {
+				child = fieldSet{}
+				node[part] = child
+			}
+			node = child
+		}
+	}
+	return fields
+}
+
+// apply keeps only the selected fields of value, descending into arrays element by element
+func (f fieldSet) apply(value any) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		filtered := make(map[string]any, len(f))
+		for key, child := range f {
+			fieldValue, exists := typed[key]
+			if !exists {
+				continue
+			}
+			if child == nil {
+				filtered[key] = fieldValue
+			} else {
+				filtered[key] = child.apply(fieldValue)
+			}
+		}
+		return filtered
+	case []any:
+		filtered := make([]any, len(typed))
+		for i, item := range typed {
+			filtered[i] = f.apply(item)
+		}
+		return filtered
+	default:
+		return value
+	}
+}
+
+// respondWithFields writes response as JSON, trimmed to the fields requested with ?fields=
+func respondWithFields(ctx *gin.Context, statusCode int, response any) {
+	respondWithListFields(ctx, statusCode, response, "")
+}
+
+// respondWithListFields writes a list response as JSON. When ?fields= is set, the fieldset is
+// applied to each element of the listKey array while the envelope (pagination, counts) is kept.
+func respondWithListFields(ctx *gin.Context, statusCode int, response any, listKey string) {
+	raw := ctx.Query(FieldsQueryParam)
+	if raw == "" {
+		ctx.JSON(statusCode, response)
+		return
+	}
+
+	fields := parseFieldSet(raw)
+	if len(fields) == 0 {
+		ctx.JSON(statusCode, response)
+		return
+	}
+
+	encoded, err := json.Marshal(response)
+	if err != nil {
+		respondWithError(ctx, fmt.Errorf("failed to encode response: %w", err))
+		return
+	}
+
+	var generic any
+	if err := json.Unmarshal(encoded, &generic); err != nil {
+		respondWithError(ctx, fmt.Errorf("failed to encode response: %w", err))
+		return
+	}
+
+	envelope, isObject := generic.(map[string]any)
+	if listKey != "" && isObject {
+		if items, exists := envelope[listKey]; exists {
+			envelope[listKey] = fields.apply(items)
+		}
+		ctx.JSON(statusCode, envelope)
+		return
+	}
+
+	ctx.JSON(statusCode, fields.apply(generic))
+}
@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+func serveWithFields(t *testing.T, target string, handler gin.HandlerFunc) map[string]any {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/resource", handler)
+
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	return body
+}
+
+func TestParseFieldSet(t *testing.T) {
+	fields := parseFieldSet(" task_id, output.summary ,output.files,,status,output")
+
+	assert.Equal(t, fieldSet{"task_id": nil, "status": nil, "output": nil}, fields)
+	assert.Equal(t, fieldSet{"output": fieldSet{"summary": nil}}, parseFieldSet("output.summary"))
+}
+
+func TestRespondWithFields_SingleResource(t *testing.T) {
+	task := models.GetTaskResponse{Task: models.Task{
+		TaskID: "task-1",
+		Status: models.TaskStatusCompleted,
+		Output: map[string]any{"summary": "done", "diff": "large"},
+	}}
+
+	body := serveWithFields(t, "/resource?fields=task_id,output.summary", func(ctx *gin.Context) {
+		respondWithFields(ctx, http.StatusOK, task)
+	})
+
+	assert.Equal(t, map[string]any{
+		"task_id": "task-1",
+		"output":  map[string]any{"summary": "done"},
+	}, body)
+}
+
+func TestRespondWithListFields_KeepsEnvelope(t *testing.T) {
+	response := models.ListTasksResponse{
+		Tasks: []models.Task{
+			{TaskID: "task-1", Status: models.TaskStatusPending, Description: "long prompt"},
+			{TaskID: "task-2", Status: models.TaskStatusFailed, Description: "long prompt"},
+		},
+		TotalCount: 2,
+		Limit:      20,
+	}
+
+	body := serveWithFields(t, "/resource?fields=task_id,status", func(ctx *gin.Context) {
+		respondWithListFields(ctx, http.StatusOK, response, "tasks")
+	})
+
+	assert.EqualValues(t, 2, body["total_count"])
+	assert.EqualValues(t, 20, body["limit"])
+	assert.Equal(t, []any{
+		map[string]any{"task_id": "task-1", "status": "pending"},
+		map[string]any{"task_id": "task-2", "status": "failed"},
+	}, body["tasks"])
+}
+
+func TestRespondWithFields_NoSelectionReturnsFullBody(t *testing.T) {
+	project := models.GetProjectResponse{ProjectID: "proj-1", Name: "payments"}
+
+	body := serveWithFields(t, "/resource", func(ctx *gin.Context) {
+		respondWithFields(ctx, http.StatusOK, project)
+	})
+
+	assert.Equal(t, "proj-1", body["project_id"])
+	assert.Equal(t, "payments", body["name"])
+}
@@ -0,0 +1,237 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/services"
+)
+
+// FindingController handles analysis finding HTTP requests
+type FindingController struct {
+	findingService services.FindingService
+}
+
+// NewFindingController creates a new FindingController
+func NewFindingController(findingService services.FindingService) *FindingController {
+	return &FindingController{
+		findingService: findingService,
+	}
+}
+
+// ListFindings handles GET /codebases/:id/findings
+// @Summary List the findings of a codebase
+// @Description List the findings code_analysis tasks and users reported on a codebase, most recently seen first, with the number of findings in each status. Findings an analysis no longer reports are fixed automatically. Legacy findings suppressed by the codebase's baseline are left out unless include_baselined is set
+// @Tags findings
+// @Produce json
+// @Param id path string true "Codebase ID"
+// @Param status query string false "Only list findings in this status" Enums(open, fixed, ignored)
+// @Param source query string false "Only list findings of this analysis mode, or manual"
+// @Param rule_id query string false "Only list findings of this rule"
+// @Param file_path query string false "Only list findings in this file"
+// @Param owner query string false "Only list findings owned by this user, team or email, from the codebase's CODEOWNERS file"
+// @Param include_baselined query bool false "Also list the findings suppressed by the baseline"
+// @Param limit query int false "Maximum number of findings (1-500, default 50)"
+// @Param offset query int false "Number of findings to skip"
+// @Param fields query string false "Comma-separated JSON fields to return; dotted paths select nested fields"
+// @Success 200 {object} models.ListFindingsResponse "Findings retrieved successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "Codebase not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/codebases/{id}/findings [get]
+func (c *FindingController) ListFindings(ctx *gin.Context) {
+	request, exists := middleware.GetValidatedRequest[models.ListFindingsRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.findingService.ListFindings(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, response)
+}
+
+// CreateFinding handles POST /codebases/:id/findings
+// @Summary Report a finding on a codebase
+// @Description Report a finding by hand. Manual findings stay open until a user fixes, ignores or deletes them
+// @Tags findings
+// @Accept json
+// @Produce json
+// @Param id path string true "Codebase ID"
+// @Param request body models.CreateFindingRequest true "Finding"
+// @Success 201 {object} models.Finding "Finding reported successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "Codebase not found"
+// @Failure 409 {object} models.ProblemDetails "Codebase already has the finding"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/codebases/{id}/findings [post]
+func (c *FindingController) CreateFinding(ctx *gin.Context) {
+	request, exists := middleware.GetValidatedRequest[models.CreateFindingRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+	request.UserID = middleware.GetUserID(ctx)
+
+	finding, err := c.findingService.CreateFinding(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, finding)
+}
+
+// GetFinding handles GET /findings/:finding_id
+// @Summary Get a finding
+// @Tags findings
+// @Produce json
+// @Param finding_id path string true "Finding ID"
+// @Param fields query string false "Comma-separated JSON fields to return; dotted paths select nested fields"
+// @Success 200 {object} models.Finding "Finding retrieved successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid finding ID"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "Finding not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/findings/{finding_id} [get]
+func (c *FindingController) GetFinding(ctx *gin.Context) {
+	request, exists := middleware.GetValidatedRequest[models.GetFindingRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	finding, err := c.findingService.GetFinding(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, finding)
+}
+
+// UpdateFinding handles PUT /findings/:finding_id
+// @Summary Change the status of a finding
+// @Description Reopen, fix or ignore a finding. Ignoring a false positive requires a justification; later analyses leave ignored findings alone
+// @Tags findings
+// @Accept json
+// @Produce json
+// @Param finding_id path string true "Finding ID"
+// @Param request body models.UpdateFindingRequest true "New status"
+// @Success 200 {object} models.Finding "Finding updated successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request or missing justification"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "Finding not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/findings/{finding_id} [put]
+func (c *FindingController) UpdateFinding(ctx *gin.Context) {
+	request, exists := middleware.GetValidatedRequest[models.UpdateFindingRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+	request.UserID = middleware.GetUserID(ctx)
+
+	finding, err := c.findingService.UpdateFinding(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, finding)
+}
+
+// DeleteFinding handles DELETE /findings/:finding_id
+// @Summary Delete a finding
+// @Description Delete a finding. An analysis still reporting it opens it again
+// @Tags findings
+// @Param finding_id path string true "Finding ID"
+// @Success 204
+// @Failure 400 {object} models.ProblemDetails "Invalid finding ID"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "Finding not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/findings/{finding_id} [delete]
+func (c *FindingController) DeleteFinding(ctx *gin.Context) {
+	request, exists := middleware.GetValidatedRequest[models.DeleteFindingRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	if err := c.findingService.DeleteFinding(ctx.Request.Context(), request); err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// Rebaseline handles POST /codebases/:id/findings/baseline
+// @Summary Baseline the findings of a codebase
+// @Description Replace the baseline of a codebase with its open findings. Baselined findings are legacy ones, left out of finding listings, analysis task outputs and quality gates, so only findings new since the baseline are reported
+// @Tags findings
+// @Produce json
+// @Param id path string true "Codebase ID"
+// @Success 200 {object} models.FindingBaseline "Baseline taken successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "Codebase not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/codebases/{id}/findings/baseline [post]
+func (c *FindingController) Rebaseline(ctx *gin.Context) {
+	request, exists := middleware.GetValidatedRequest[models.RebaselineFindingsRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+	request.UserID = middleware.GetUserID(ctx)
+
+	baseline, err := c.findingService.Rebaseline(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, baseline)
+}
+
+// GetBaseline handles GET /codebases/:id/findings/baseline
+// @Summary Get the findings baseline of a codebase
+// @Description Get the baseline of a codebase and the legacy findings it suppresses, most recently seen first, with the number of suppressed findings in each status
+// @Tags findings
+// @Produce json
+// @Param id path string true "Codebase ID"
+// @Param status query string false "Only list suppressed findings in this status" Enums(open, fixed, ignored)
+// @Param limit query int false "Maximum number of findings (1-500, default 50)"
+// @Param offset query int false "Number of findings to skip"
+// @Param fields query string false "Comma-separated JSON fields to return; dotted paths select nested fields"
+// @Success 200 {object} models.FindingBaselineResponse "Baseline retrieved successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "Codebase not found or not baselined"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/codebases/{id}/findings/baseline [get]
+func (c *FindingController) GetBaseline(ctx *gin.Context) {
+	request, exists := middleware.GetValidatedRequest[models.GetFindingBaselineRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.findingService.GetBaseline(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, response)
+}
@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/services"
+)
+
+// GenerationController handles the HTTP requests generating text from changes with the configured model
+type GenerationController struct {
+	generationService services.GenerationService
+}
+
+// NewGenerationController creates a new GenerationController
+func NewGenerationController(generationService services.GenerationService) *GenerationController {
+	return &GenerationController{
+		generationService: generationService,
+	}
+}
+
+// GeneratePRDescription handles POST /generate/pr-description
+// @Summary Generate a pull request description
+// @Description Describe a unified diff, or the diff a task produced, as a pull request title and Markdown body with a changelog entry, using the configured model. No task is created, so CI bots can call it for any change. Diffs longer than the configured length are described from their beginning and flagged as truncated. Requests accepting text/event-stream get the model's answer as it's generated, as server-sent chunk events, with a retry event before each repair of an invalid answer and a last result event carrying the description, or error event carrying the problem details.
+// @Tags generation
+// @Accept json
+// @Produce json,text/event-stream
+// @Param request body models.GeneratePRDescriptionRequest true "Diff or task to describe"
+// @Success 200 {object} models.PRDescription "Description generated successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request, or the task has no diff"
+// @Failure 404 {object} models.ProblemDetails "Task not found"
+// @Failure 502 {object} models.ProblemDetails "The model failed or answered with an invalid description"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/generate/pr-description [post]
+func (c *GenerationController) GeneratePRDescription(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.GeneratePRDescriptionRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	if middleware.AcceptsEventStream(ctx) {
+		respondWithEventStream(ctx, func(emit func(models.StreamEvent) error) (*models.PRDescription, error) {
+			return c.generationService.StreamPRDescription(ctx.Request.Context(), request, emit)
+		})
+		return
+	}
+
+	description, err := c.generationService.GeneratePRDescription(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, description)
+}
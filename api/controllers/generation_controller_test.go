@@ -0,0 +1,146 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	servicesMocks "github.com/kazemisoroush/code-refactoring-tool/api/services/mocks"
+)
+
+func newGenerationRouter(mockService *servicesMocks.MockGenerationService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.NewErrorHandlerMiddleware().Handle())
+	router.POST("/generate/pr-description",
+		middleware.NewJSONValidationMiddleware[models.GeneratePRDescriptionRequest]().Handle(),
+		NewGenerationController(mockService).GeneratePRDescription)
+	return router
+}
+
+func postJSON(router *gin.Engine, path string, payload map[string]any) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(payload)
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGenerationController_GeneratePRDescription(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := servicesMocks.NewMockGenerationService(ctrl)
+	router := newGenerationRouter(mockService)
+
+	mockService.EXPECT().GeneratePRDescription(gomock.Any(), models.GeneratePRDescriptionRequest{Diff: "+func Div", Context: "Fixes #42"}).
+		Return(&models.PRDescription{Title: "Return an error when dividing by zero", Model: "qwen2.5-coder:7b"}, nil)
+
+	w := postJSON(router, "/generate/pr-description", map[string]any{"diff": "+func Div", "context": "Fixes #42"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response models.PRDescription
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Return an error when dividing by zero", response.Title)
+}
+
+func TestGenerationController_GeneratePRDescription_Errors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := servicesMocks.NewMockGenerationService(ctrl)
+	router := newGenerationRouter(mockService)
+
+	mockService.EXPECT().GeneratePRDescription(gomock.Any(), models.GeneratePRDescriptionRequest{TaskID: "task-1"}).
+		Return(nil, apperrors.BadGateway(apperrors.CodeInvalidModelAnswer, errors.New("$: invalid JSON"), "model answered with an invalid description"))
+
+	w := postJSON(router, "/generate/pr-description", map[string]any{"task_id": "task-1"})
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+
+	w = postJSON(router, "/generate/pr-description", map[string]any{})
+	assert.Equal(t, http.StatusBadRequest, w.Code, "a diff or task is required")
+
+	w = postJSON(router, "/generate/pr-description", map[string]any{"diff": "+func Div", "task_id": "task-1"})
+	assert.Equal(t, http.StatusBadRequest, w.Code, "a diff and a task are exclusive")
+}
+
+func TestGenerationController_GeneratePRDescription_Streams(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := servicesMocks.NewMockGenerationService(ctrl)
+	router := newGenerationRouter(mockService)
+
+	mockService.EXPECT().StreamPRDescription(gomock.Any(), models.GeneratePRDescriptionRequest{Diff: "+func Div"}, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ models.GeneratePRDescriptionRequest, emit func(models.StreamEvent) error) (*models.PRDescription, error) {
+			require.NoError(t, emit(models.StreamEvent{Event: models.StreamEventChunk, Data: models.StreamChunk{Text: `{"title":`}}))
+			require.NoError(t, emit(models.StreamEvent{Event: models.StreamEventChunk, Data: models.StreamChunk{Text: ` "Fix Div"}`}}))
+			return &models.PRDescription{Title: "Fix Div"}, nil
+		})
+
+	body, _ := json.Marshal(map[string]any{"diff": "+func Div"})
+	req := httptest.NewRequest(http.MethodPost, "/generate/pr-description", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, models.EventStreamContentType, w.Header().Get("Content-Type"))
+	assert.Equal(t, "event: chunk\ndata: {\"text\":\"{\\\"title\\\":\"}\n\n"+
+		"event: chunk\ndata: {\"text\":\" \\\"Fix Div\\\"}\"}\n\n"+
+		"event: result\ndata: "+`{"title":"Fix Div","body":"","changelog":{"type":"","summary":""},"model":""}`+"\n\n", w.Body.String())
+}
+
+func TestGenerationController_GeneratePRDescription_StreamErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := servicesMocks.NewMockGenerationService(ctrl)
+	router := newGenerationRouter(mockService)
+	stream := func() *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string]any{"task_id": "task-1"})
+		req := httptest.NewRequest(http.MethodPost, "/generate/pr-description", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "text/event-stream")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Failing before the first event gets a problem response
+	mockService.EXPECT().StreamPRDescription(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, apperrors.NotFound(apperrors.CodeTaskNotFound, "task task-1 not found"))
+	w := stream()
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, models.ProblemContentType, w.Header().Get("Content-Type"))
+
+	// Failing later ends the stream with an error event
+	mockService.EXPECT().StreamPRDescription(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ models.GeneratePRDescriptionRequest, emit func(models.StreamEvent) error) (*models.PRDescription, error) {
+			require.NoError(t, emit(models.StreamEvent{Event: models.StreamEventChunk, Data: models.StreamChunk{Text: "Here is"}}))
+			return nil, apperrors.BadGateway(apperrors.CodeInvalidModelAnswer, errors.New("$: invalid JSON"), "model answered with an invalid description")
+		})
+	w = stream()
+	assert.Equal(t, http.StatusOK, w.Code)
+	_, last, found := strings.Cut(w.Body.String(), "event: error\ndata: ")
+	require.True(t, found)
+	var problem models.ProblemDetails
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimSpace(last)), &problem))
+	assert.Equal(t, http.StatusBadGateway, problem.Status)
+	assert.Equal(t, apperrors.CodeInvalidModelAnswer, problem.Code)
+}
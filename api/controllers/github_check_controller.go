@@ -0,0 +1,170 @@
+package controllers
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/services"
+)
+
+// maxWebhookPayloadBytes is the largest webhook payload GitHub delivers
+const maxWebhookPayloadBytes = 25 << 20
+
+// GitHubCheckController handles the HTTP requests configuring GitHub Apps and the webhooks they deliver
+type GitHubCheckController struct {
+	checkService services.GitHubCheckService
+}
+
+// NewGitHubCheckController creates a new GitHubCheckController
+func NewGitHubCheckController(checkService services.GitHubCheckService) *GitHubCheckController {
+	return &GitHubCheckController{
+		checkService: checkService,
+	}
+}
+
+// PutGitHubApp handles PUT /admin/github-apps/:organization
+// @Summary Configure the GitHub App of an organization
+// @Description Store the credentials of the GitHub App installed on an organization's repositories and the task its pull requests run. Opened and updated pull requests of repositories cloned by a codebase of the project run the task on their head commit, whose findings are published as a check run failing at or above fail_on_severity. The private key and webhook secret are never returned.
+// @Tags github
+// @Accept json
+// @Produce json
+// @Param organization path string true "GitHub organization or user"
+// @Param request body models.PutGitHubAppConfigRequest true "GitHub App configuration"
+// @Success 200 {object} models.GitHubAppConfig "GitHub App configured"
+// @Failure 400 {object} models.ProblemDetails "Invalid request or private key"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/admin/github-apps/{organization} [put]
+func (c *GitHubCheckController) PutGitHubApp(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.PutGitHubAppConfigRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	appConfig, err := c.checkService.PutAppConfig(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, appConfig)
+}
+
+// GetGitHubApp handles GET /admin/github-apps/:organization
+// @Summary Get the GitHub App of an organization
+// @Description Retrieve the GitHub App configuration of an organization, without its private key and webhook secret
+// @Tags github
+// @Produce json
+// @Param organization path string true "GitHub organization or user"
+// @Success 200 {object} models.GitHubAppConfig "GitHub App configuration retrieved successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid organization"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "No GitHub App configured for the organization"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/admin/github-apps/{organization} [get]
+func (c *GitHubCheckController) GetGitHubApp(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.GetGitHubAppConfigRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	appConfig, err := c.checkService.GetAppConfig(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, appConfig)
+}
+
+// ListGitHubApps handles GET /admin/github-apps
+// @Summary List the GitHub Apps
+// @Description List the GitHub App configurations of the organizations, ordered by organization
+// @Tags github
+// @Produce json
+// @Success 200 {object} models.ListGitHubAppConfigsResponse "GitHub App configurations listed successfully"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/admin/github-apps [get]
+func (c *GitHubCheckController) ListGitHubApps(ctx *gin.Context) {
+	response, err := c.checkService.ListAppConfigs(ctx.Request.Context())
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// DeleteGitHubApp handles DELETE /admin/github-apps/:organization
+// @Summary Delete the GitHub App of an organization
+// @Description Delete the GitHub App configuration of an organization. Its pull requests no longer run tasks, and the check runs of its running tasks are left unfinished.
+// @Tags github
+// @Param organization path string true "GitHub organization or user"
+// @Success 204
+// @Failure 400 {object} models.ProblemDetails "Invalid organization"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "No GitHub App configured for the organization"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/admin/github-apps/{organization} [delete]
+func (c *GitHubCheckController) DeleteGitHubApp(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.DeleteGitHubAppConfigRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	if err := c.checkService.DeleteAppConfig(ctx.Request.Context(), request); err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// HandleWebhook handles POST /webhooks/github
+// @Summary Receive a GitHub App webhook
+// @Description Receive a webhook delivery of a configured GitHub App, authenticated by its X-Hub-Signature-256 signature rather than a bearer token. An opened, synchronized or reopened pull request runs the organization's task on its head commit and creates a queued check run, completed with the task's findings once it finishes. Other events are acknowledged and ignored.
+// @Tags github
+// @Accept json
+// @Produce json
+// @Param X-GitHub-Event header string true "Webhook event"
+// @Param X-Hub-Signature-256 header string true "HMAC-SHA256 signature of the payload with the webhook secret"
+// @Param payload body object true "Webhook payload"
+// @Success 200 {object} models.GitHubWebhookResponse "Delivery ignored"
+// @Success 202 {object} models.GitHubWebhookResponse "Task started on the pull request"
+// @Failure 400 {object} models.ProblemDetails "Invalid payload"
+// @Failure 401 {object} models.ProblemDetails "Signature doesn't match the payload, or no GitHub App is configured for the organization"
+// @Failure 404 {object} models.ProblemDetails "No codebase cloned from the repository"
+// @Failure 502 {object} models.ProblemDetails "Creating the check run failed"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /webhooks/github [post]
+func (c *GitHubCheckController) HandleWebhook(ctx *gin.Context) {
+	payload, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxWebhookPayloadBytes))
+	if err != nil {
+		respondWithError(ctx, apperrors.Validation(apperrors.CodeInvalidRequest, "failed to read webhook payload: %v", err))
+		return
+	}
+
+	response, err := c.checkService.HandleWebhook(ctx.Request.Context(),
+		ctx.GetHeader("X-GitHub-Event"), ctx.GetHeader("X-Hub-Signature-256"), payload)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	if response.TaskID == "" {
+		ctx.JSON(http.StatusOK, response)
+		return
+	}
+	ctx.JSON(http.StatusAccepted, response)
+}
@@ -0,0 +1,108 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	servicesMocks "github.com/kazemisoroush/code-refactoring-tool/api/services/mocks"
+)
+
+func TestGitHubCheckController_PutGitHubApp(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := servicesMocks.NewMockGitHubCheckService(ctrl)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.NewErrorHandlerMiddleware().Handle())
+	router.PUT("/admin/github-apps/:organization",
+		middleware.NewCombinedValidationMiddleware[models.PutGitHubAppConfigRequest]().Handle(),
+		NewGitHubCheckController(mockService).PutGitHubApp)
+
+	mockService.EXPECT().PutAppConfig(gomock.Any(), models.PutGitHubAppConfigRequest{
+		Organization: "acme", AppID: 42, PrivateKey: "key", WebhookSecret: "0123456789abcdef", ProjectID: "proj-12345", AgentID: "agent-1",
+	}).Return(&models.GitHubAppConfig{Organization: "acme", AppID: 42, PrivateKey: "key", WebhookSecret: "0123456789abcdef"}, nil)
+
+	body, _ := json.Marshal(map[string]any{"app_id": 42, "private_key": "key", "webhook_secret": "0123456789abcdef", "project_id": "proj-12345", "agent_id": "agent-1"})
+	req := httptest.NewRequest(http.MethodPut, "/admin/github-apps/acme", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "0123456789abcdef", "the webhook secret is never returned")
+
+	body, _ = json.Marshal(map[string]any{"app_id": 42, "private_key": "key", "webhook_secret": "short", "project_id": "proj-12345", "agent_id": "agent-1"})
+	req = httptest.NewRequest(http.MethodPut, "/admin/github-apps/acme", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code, "the webhook secret is at least 16 characters")
+}
+
+func TestGitHubCheckController_HandleWebhook(t *testing.T) {
+	tests := []struct {
+		name           string
+		response       *models.GitHubWebhookResponse
+		err            error
+		expectedStatus int
+	}{
+		{
+			name:           "task started",
+			response:       &models.GitHubWebhookResponse{TaskID: "task-1", CheckRunID: 4242},
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:           "ignored delivery",
+			response:       &models.GitHubWebhookResponse{Ignored: "event push"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "wrong signature",
+			err:            apperrors.Unauthorized(apperrors.CodeInvalidSignature, "webhook signature doesn't match the payload"),
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := servicesMocks.NewMockGitHubCheckService(ctrl)
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.Use(middleware.NewErrorHandlerMiddleware().Handle())
+			router.POST("/webhooks/github", NewGitHubCheckController(mockService).HandleWebhook)
+
+			payload := `{"action": "opened"}`
+			mockService.EXPECT().HandleWebhook(gomock.Any(), "pull_request", "sha256=abc", []byte(payload)).Return(tt.response, tt.err)
+
+			req := httptest.NewRequest(http.MethodPost, "/webhooks/github", strings.NewReader(payload))
+			req.Header.Set("X-GitHub-Event", "pull_request")
+			req.Header.Set("X-Hub-Signature-256", "sha256=abc")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.response != nil {
+				var response models.GitHubWebhookResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, *tt.response, response)
+			}
+		})
+	}
+}
@@ -28,7 +28,7 @@ func NewHealthController(healthService services.HealthService) *HealthController
 // @Tags health
 // @Produce json
 // @Success 200 {object} models.HealthCheckResponse "Service is healthy"
-// @Failure 500 {object} models.ErrorResponse "Service is unhealthy"
+// @Failure 500 {object} models.ProblemDetails "Service is unhealthy"
 // @Router /health [get]
 func (h *HealthController) HealthCheck(ctx *gin.Context) {
 	// Try to get the validated request from context first (new pattern)
@@ -48,12 +48,7 @@ func (h *HealthController) HealthCheck(ctx *gin.Context) {
 	// Call the service to get health status
 	response, err := h.healthService.GetHealthStatus(ctx.Request.Context(), request)
 	if err != nil {
-		errorResponse := models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "Failed to get health status",
-			Details: err.Error(),
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse)
+		respondWithError(ctx, err)
 		return
 	}
 
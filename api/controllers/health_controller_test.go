@@ -57,6 +57,27 @@ func TestHealthController_HealthCheck(t *testing.T) {
 	assert.Contains(t, response, "uptime")
 }
 
+func TestHealthController_HealthCheck_Degraded(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	healthService := services.NewDefaultHealthService("code-refactor-tool-api", "1.0.0", "cognito")
+	controller := NewHealthController(healthService)
+
+	router := gin.New()
+	router.GET("/health", controller.HealthCheck)
+
+	req := httptest.NewRequest("GET", "/health", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "degraded", response["status"])
+	assert.Equal(t, []interface{}{"cognito"}, response["unavailable"])
+}
+
 func TestHealthController_HealthCheck_WithMetrics(t *testing.T) {
 	// Setup
 	gin.SetMode(gin.TestMode)
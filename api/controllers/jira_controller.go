@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/services"
+)
+
+// JiraController handles the HTTP requests configuring the Jira sites tasks link their issues on
+type JiraController struct {
+	jiraService services.JiraService
+}
+
+// NewJiraController creates a new JiraController
+func NewJiraController(jiraService services.JiraService) *JiraController {
+	return &JiraController{
+		jiraService: jiraService,
+	}
+}
+
+// PutJiraSite handles PUT /admin/jira-sites/:organization
+// @Summary Configure the Jira site of an organization
+// @Description Store the Jira site of an organization, the account its issues are updated with and the keys of its projects. Tasks created with the issue_key of one of these projects are linked to the issue, which is commented on as the task progresses and moved through done_transition once it completes. A project key can only be listed by one organization. The API token is never returned.
+// @Tags jira
+// @Accept json
+// @Produce json
+// @Param organization path string true "Organization"
+// @Param request body models.PutJiraSiteRequest true "Jira site"
+// @Success 200 {object} models.JiraSite "Jira site configured"
+// @Failure 400 {object} models.ProblemDetails "Invalid request or project key"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 409 {object} models.ProblemDetails "A project key is listed by another organization"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/admin/jira-sites/{organization} [put]
+func (c *JiraController) PutJiraSite(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.PutJiraSiteRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	site, err := c.jiraService.PutSite(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, site)
+}
+
+// GetJiraSite handles GET /admin/jira-sites/:organization
+// @Summary Get the Jira site of an organization
+// @Description Retrieve the Jira site of an organization, without its API token
+// @Tags jira
+// @Produce json
+// @Param organization path string true "Organization"
+// @Success 200 {object} models.JiraSite "Jira site retrieved successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid organization"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "No Jira site configured for the organization"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/admin/jira-sites/{organization} [get]
+func (c *JiraController) GetJiraSite(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.GetJiraSiteRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	site, err := c.jiraService.GetSite(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, site)
+}
+
+// ListJiraSites handles GET /admin/jira-sites
+// @Summary List the Jira sites
+// @Description List the Jira sites of the organizations, ordered by organization
+// @Tags jira
+// @Produce json
+// @Success 200 {object} models.ListJiraSitesResponse "Jira sites listed successfully"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/admin/jira-sites [get]
+func (c *JiraController) ListJiraSites(ctx *gin.Context) {
+	response, err := c.jiraService.ListSites(ctx.Request.Context())
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// DeleteJiraSite handles DELETE /admin/jira-sites/:organization
+// @Summary Delete the Jira site of an organization
+// @Description Delete the Jira site of an organization. Tasks can no longer be linked to the issues of its projects, and the issues of linked tasks are no longer updated.
+// @Tags jira
+// @Param organization path string true "Organization"
+// @Success 204
+// @Failure 400 {object} models.ProblemDetails "Invalid organization"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "No Jira site configured for the organization"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/admin/jira-sites/{organization} [delete]
+func (c *JiraController) DeleteJiraSite(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.DeleteJiraSiteRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	if err := c.jiraService.DeleteSite(ctx.Request.Context(), request); err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
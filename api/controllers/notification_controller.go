@@ -0,0 +1,272 @@
+// Package controllers provides HTTP request handlers for notification channel and inbox API
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/services"
+)
+
+// NotificationController handles notification channel and inbox HTTP requests
+type NotificationController struct {
+	notificationService services.NotificationService
+}
+
+// NewNotificationController creates a new NotificationController
+func NewNotificationController(notificationService services.NotificationService) *NotificationController {
+	return &NotificationController{
+		notificationService: notificationService,
+	}
+}
+
+// CreateChannel handles POST /notifications/channels
+// @Summary Create a notification channel
+// @Description Subscribe the caller's email address, or a project's Slack channel, to task and agent events
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param request body models.CreateNotificationChannelRequest true "Notification channel creation request"
+// @Success 201 {object} models.NotificationChannelResponse "Notification channel created successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 401 {object} models.ProblemDetails "Not authenticated"
+// @Failure 404 {object} models.ProblemDetails "Project not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/notifications/channels [post]
+func (c *NotificationController) CreateChannel(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.CreateNotificationChannelRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	request.UserID = middleware.GetUserID(ctx)
+	if request.UserID == "" {
+		respondWithError(ctx, errUnauthenticatedCaller)
+		return
+	}
+	request.Email = middleware.GetUserEmail(ctx)
+
+	response, err := c.notificationService.CreateChannel(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// ListChannels handles GET /notifications/channels
+// @Summary List notification channels
+// @Description List the caller's notification channels, optionally only those of a project
+// @Tags notifications
+// @Produce json
+// @Param project_id query string false "Only list channels of this project"
+// @Param fields query string false "Comma-separated JSON fields to return; dotted paths select nested fields"
+// @Success 200 {object} models.ListNotificationChannelsResponse "Notification channels retrieved successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 401 {object} models.ProblemDetails "Not authenticated"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/notifications/channels [get]
+func (c *NotificationController) ListChannels(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.ListNotificationChannelsRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	request.UserID = middleware.GetUserID(ctx)
+	if request.UserID == "" {
+		respondWithError(ctx, errUnauthenticatedCaller)
+		return
+	}
+
+	response, err := c.notificationService.ListChannels(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, response)
+}
+
+// GetChannel handles GET /notifications/channels/:channel_id
+// @Summary Get a notification channel
+// @Description Retrieve one of the caller's notification channels; Slack credentials are never returned
+// @Tags notifications
+// @Produce json
+// @Param channel_id path string true "Notification Channel ID"
+// @Param fields query string false "Comma-separated JSON fields to return; dotted paths select nested fields"
+// @Success 200 {object} models.NotificationChannelResponse "Notification channel retrieved successfully"
+// @Failure 401 {object} models.ProblemDetails "Not authenticated"
+// @Failure 404 {object} models.ProblemDetails "Notification channel not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/notifications/channels/{channel_id} [get]
+func (c *NotificationController) GetChannel(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.GetNotificationChannelRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	request.UserID = middleware.GetUserID(ctx)
+	if request.UserID == "" {
+		respondWithError(ctx, errUnauthenticatedCaller)
+		return
+	}
+
+	response, err := c.notificationService.GetChannel(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, response)
+}
+
+// UpdateChannel handles PUT /notifications/channels/:channel_id
+// @Summary Update a notification channel
+// @Description Update the name, subscribed events, Slack destination or enabled state of one of the caller's channels
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param channel_id path string true "Notification Channel ID"
+// @Param request body models.UpdateNotificationChannelRequest true "Notification channel update request"
+// @Success 200 {object} models.NotificationChannelResponse "Notification channel updated successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 401 {object} models.ProblemDetails "Not authenticated"
+// @Failure 404 {object} models.ProblemDetails "Notification channel not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/notifications/channels/{channel_id} [put]
+func (c *NotificationController) UpdateChannel(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.UpdateNotificationChannelRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	request.UserID = middleware.GetUserID(ctx)
+	if request.UserID == "" {
+		respondWithError(ctx, errUnauthenticatedCaller)
+		return
+	}
+
+	response, err := c.notificationService.UpdateChannel(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// DeleteChannel handles DELETE /notifications/channels/:channel_id
+// @Summary Delete a notification channel
+// @Description Delete one of the caller's notification channels
+// @Tags notifications
+// @Produce json
+// @Param channel_id path string true "Notification Channel ID"
+// @Success 200 {object} models.DeleteNotificationChannelResponse "Notification channel deleted successfully"
+// @Failure 401 {object} models.ProblemDetails "Not authenticated"
+// @Failure 404 {object} models.ProblemDetails "Notification channel not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/notifications/channels/{channel_id} [delete]
+func (c *NotificationController) DeleteChannel(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.DeleteNotificationChannelRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	request.UserID = middleware.GetUserID(ctx)
+	if request.UserID == "" {
+		respondWithError(ctx, errUnauthenticatedCaller)
+		return
+	}
+
+	response, err := c.notificationService.DeleteChannel(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// ListNotifications handles GET /notifications
+// @Summary List inbox notifications
+// @Description List the caller's in-app notifications newest first, with the number of unread notifications
+// @Tags notifications
+// @Produce json
+// @Param unread_only query bool false "Only list unread notifications"
+// @Param max_results query int false "Maximum number of results to return"
+// @Param next_token query string false "Token for pagination"
+// @Param fields query string false "Comma-separated JSON fields to return; dotted paths select nested fields"
+// @Success 200 {object} models.ListNotificationsResponse "Notifications retrieved successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 401 {object} models.ProblemDetails "Not authenticated"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/notifications [get]
+func (c *NotificationController) ListNotifications(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.ListNotificationsRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	request.UserID = middleware.GetUserID(ctx)
+	if request.UserID == "" {
+		respondWithError(ctx, errUnauthenticatedCaller)
+		return
+	}
+
+	response, err := c.notificationService.ListNotifications(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, response)
+}
+
+// MarkNotificationRead handles POST /notifications/:notification_id/read
+// @Summary Mark an inbox notification read
+// @Description Mark one of the caller's in-app notifications read and return the remaining unread count
+// @Tags notifications
+// @Produce json
+// @Param notification_id path string true "Notification ID"
+// @Success 200 {object} models.MarkNotificationReadResponse "Notification marked read"
+// @Failure 401 {object} models.ProblemDetails "Not authenticated"
+// @Failure 404 {object} models.ProblemDetails "Notification not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/notifications/{notification_id}/read [post]
+func (c *NotificationController) MarkNotificationRead(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.MarkNotificationReadRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	request.UserID = middleware.GetUserID(ctx)
+	if request.UserID == "" {
+		respondWithError(ctx, errUnauthenticatedCaller)
+		return
+	}
+
+	response, err := c.notificationService.MarkNotificationRead(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
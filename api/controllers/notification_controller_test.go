@@ -0,0 +1,141 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	servicesMocks "github.com/kazemisoroush/code-refactoring-tool/api/services/mocks"
+)
+
+func setupNotificationRouter(controller *NotificationController, userID, email string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.NewErrorHandlerMiddleware().Handle())
+	router.Use(func(c *gin.Context) {
+		if userID != "" {
+			c.Set(middleware.UserIDContextKey, userID)
+			c.Set(middleware.EmailContextKey, email)
+		}
+		c.Next()
+	})
+	router.POST("/notifications/channels",
+		middleware.NewJSONValidationMiddleware[models.CreateNotificationChannelRequest]().Handle(),
+		controller.CreateChannel,
+	)
+	router.GET("/notifications/channels/:channel_id",
+		middleware.NewURIValidationMiddleware[models.GetNotificationChannelRequest]().Handle(),
+		controller.GetChannel,
+	)
+	router.POST("/notifications/:notification_id/read",
+		middleware.NewURIValidationMiddleware[models.MarkNotificationReadRequest]().Handle(),
+		controller.MarkNotificationRead,
+	)
+	return router
+}
+
+func TestNotificationController_CreateChannel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := servicesMocks.NewMockNotificationService(ctrl)
+	router := setupNotificationRouter(NewNotificationController(mockService), "user-1", "dev@example.com")
+
+	email := "dev@example.com"
+	mockService.EXPECT().
+		CreateChannel(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ any, request models.CreateNotificationChannelRequest) (*models.NotificationChannelResponse, error) {
+			assert.Equal(t, "user-1", request.UserID)
+			assert.Equal(t, "dev@example.com", request.Email)
+			return &models.NotificationChannelResponse{ChannelID: "chan-1", Type: models.NotificationChannelTypeEmail, Email: &email}, nil
+		})
+
+	body := `{"type":"email","name":"Me","events":["task.failed"]}`
+	req := httptest.NewRequest(http.MethodPost, "/notifications/channels", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var response models.NotificationChannelResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "chan-1", response.ChannelID)
+}
+
+func TestNotificationController_CreateChannel_InvalidEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	router := setupNotificationRouter(NewNotificationController(servicesMocks.NewMockNotificationService(ctrl)), "user-1", "dev@example.com")
+
+	body := `{"type":"email","name":"Me","events":["task.exploded"]}`
+	req := httptest.NewRequest(http.MethodPost, "/notifications/channels", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestNotificationController_GetChannel_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{name: "unauthenticated", expectedStatus: http.StatusUnauthorized},
+		{name: "not_found", userID: "user-1", serviceErr: apperrors.NotFound(apperrors.CodeChannelNotFound, "notification channel not found: chan-1"), expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := servicesMocks.NewMockNotificationService(ctrl)
+			router := setupNotificationRouter(NewNotificationController(mockService), tt.userID, "")
+
+			if tt.serviceErr != nil {
+				mockService.EXPECT().
+					GetChannel(gomock.Any(), models.GetNotificationChannelRequest{ChannelID: "chan-1", UserID: tt.userID}).
+					Return(nil, tt.serviceErr)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/notifications/channels/chan-1", nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestNotificationController_MarkNotificationRead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := servicesMocks.NewMockNotificationService(ctrl)
+	router := setupNotificationRouter(NewNotificationController(mockService), "user-1", "")
+
+	mockService.EXPECT().
+		MarkNotificationRead(gomock.Any(), models.MarkNotificationReadRequest{NotificationID: "notif-1", UserID: "user-1"}).
+		Return(&models.MarkNotificationReadResponse{Success: true, UnreadCount: 2}, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/notifications/notif-1/read", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response models.MarkNotificationReadResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 2, response.UnreadCount)
+}
@@ -30,31 +30,23 @@ func NewProjectController(projectService services.ProjectService) *ProjectContro
 // @Produce json
 // @Param request body models.CreateProjectRequest true "Project creation request"
 // @Success 201 {object} models.CreateProjectResponse "Project created successfully"
-// @Failure 400 {object} models.ErrorResponse "Invalid request"
-// @Failure 500 {object} models.ErrorResponse "Internal server error"
-// @Router /projects [post]
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 403 {object} models.ProblemDetails "Caller may not modify a restricted tag"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/projects [post]
 func (c *ProjectController) CreateProject(ctx *gin.Context) {
 	// Get the validated request from context (set by validation middleware)
 	request, exists := middleware.GetValidatedRequest[models.CreateProjectRequest](ctx)
 	if !exists {
-		errorResponse := models.ErrorResponse{
-			Code:    http.StatusBadRequest,
-			Message: "Missing validated request",
-			Details: "Validation middleware must be applied before this controller",
-		}
-		ctx.JSON(http.StatusBadRequest, errorResponse)
+		respondWithError(ctx, errMissingValidatedRequest)
 		return
 	}
+	request.UserID = middleware.GetUserID(ctx)
 
 	// Call the service to create the project
 	response, err := c.projectService.CreateProject(ctx.Request.Context(), request)
 	if err != nil {
-		errorResponse := models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "Failed to create project",
-			Details: err.Error(),
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse)
+		respondWithError(ctx, err)
 		return
 	}
 
@@ -66,50 +58,31 @@ func (c *ProjectController) CreateProject(ctx *gin.Context) {
 // @Description Retrieve a project by its unique identifier
 // @Tags projects
 // @Produce json
-// @Param id path string true "Project ID"
+// @Param project_id path string true "Project ID"
+// @Param fields query string false "Comma-separated JSON fields to return; dotted paths select nested fields"
 // @Success 200 {object} models.GetProjectResponse "Project retrieved successfully"
-// @Failure 400 {object} models.ErrorResponse "Invalid project ID"
-// @Failure 404 {object} models.ErrorResponse "Project not found"
-// @Failure 500 {object} models.ErrorResponse "Internal server error"
-// @Router /projects/{id} [get]
+// @Header 200 {string} ETag "Project version, to be sent as If-Match on update"
+// @Failure 400 {object} models.ProblemDetails "Invalid project ID"
+// @Failure 404 {object} models.ProblemDetails "Project not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/projects/{project_id} [get]
 func (c *ProjectController) GetProject(ctx *gin.Context) {
 	// Get the validated request from context (set by validation middleware)
 	request, exists := middleware.GetValidatedRequest[models.GetProjectRequest](ctx)
 	if !exists {
-		errorResponse := models.ErrorResponse{
-			Code:    http.StatusBadRequest,
-			Message: "Missing validated request",
-			Details: "Validation middleware must be applied before this controller",
-		}
-		ctx.JSON(http.StatusBadRequest, errorResponse)
+		respondWithError(ctx, errMissingValidatedRequest)
 		return
 	}
 
 	// Call the service to get the project
 	response, err := c.projectService.GetProject(ctx.Request.Context(), request.ProjectID)
 	if err != nil {
-		var statusCode int
-		var message string
-
-		// Check if it's a "not found" error
-		if err.Error() == "project not found" {
-			statusCode = http.StatusNotFound
-			message = "Project not found"
-		} else {
-			statusCode = http.StatusInternalServerError
-			message = "Failed to get project"
-		}
-
-		errorResponse := models.ErrorResponse{
-			Code:    statusCode,
-			Message: message,
-			Details: err.Error(),
-		}
-		ctx.JSON(statusCode, errorResponse)
+		respondWithError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response)
+	setETag(ctx, response.Version)
+	respondWithFields(ctx, http.StatusOK, response)
 }
 
 // UpdateProject handles PUT /projects/:id
@@ -118,50 +91,42 @@ func (c *ProjectController) GetProject(ctx *gin.Context) {
 // @Tags projects
 // @Accept json
 // @Produce json
-// @Param id path string true "Project ID"
+// @Param project_id path string true "Project ID"
+// @Param If-Match header string true "ETag of the project version being updated"
 // @Param request body models.UpdateProjectRequest true "Project update request"
 // @Success 200 {object} models.UpdateProjectResponse "Project updated successfully"
-// @Failure 400 {object} models.ErrorResponse "Invalid request"
-// @Failure 404 {object} models.ErrorResponse "Project not found"
-// @Failure 500 {object} models.ErrorResponse "Internal server error"
-// @Router /projects/{id} [put]
+// @Header 200 {string} ETag "New project version"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 403 {object} models.ProblemDetails "Caller may not modify a restricted tag"
+// @Failure 404 {object} models.ProblemDetails "Project not found"
+// @Failure 412 {object} models.ProblemDetails "Project was modified since the If-Match version"
+// @Failure 428 {object} models.ProblemDetails "If-Match header missing"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/projects/{project_id} [put]
 func (c *ProjectController) UpdateProject(ctx *gin.Context) {
 	// Get the validated request from context (set by validation middleware)
 	request, exists := middleware.GetValidatedRequest[models.UpdateProjectRequest](ctx)
 	if !exists {
-		errorResponse := models.ErrorResponse{
-			Code:    http.StatusBadRequest,
-			Message: "Missing validated request",
-			Details: "Validation middleware must be applied before this controller",
-		}
-		ctx.JSON(http.StatusBadRequest, errorResponse)
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+	request.UserID = middleware.GetUserID(ctx)
+
+	version, err := ifMatchVersion(ctx)
+	if err != nil {
+		respondWithError(ctx, err)
 		return
 	}
+	request.ExpectedVersion = version
 
 	// Call the service to update the project
 	response, err := c.projectService.UpdateProject(ctx.Request.Context(), request)
 	if err != nil {
-		var statusCode int
-		var message string
-
-		// Check if it's a "not found" error
-		if err.Error() == "project not found" {
-			statusCode = http.StatusNotFound
-			message = "Project not found"
-		} else {
-			statusCode = http.StatusInternalServerError
-			message = "Failed to update project"
-		}
-
-		errorResponse := models.ErrorResponse{
-			Code:    statusCode,
-			Message: message,
-			Details: err.Error(),
-		}
-		ctx.JSON(statusCode, errorResponse)
+		respondWithError(ctx, err)
 		return
 	}
 
+	setETag(ctx, response.Version)
 	ctx.JSON(http.StatusOK, response)
 }
 
@@ -170,46 +135,24 @@ func (c *ProjectController) UpdateProject(ctx *gin.Context) {
 // @Description Delete a project by its unique identifier
 // @Tags projects
 // @Produce json
-// @Param id path string true "Project ID"
+// @Param project_id path string true "Project ID"
 // @Success 200 {object} models.DeleteProjectResponse "Project deleted successfully"
-// @Failure 400 {object} models.ErrorResponse "Invalid project ID"
-// @Failure 404 {object} models.ErrorResponse "Project not found"
-// @Failure 500 {object} models.ErrorResponse "Internal server error"
-// @Router /projects/{id} [delete]
+// @Failure 400 {object} models.ProblemDetails "Invalid project ID"
+// @Failure 404 {object} models.ProblemDetails "Project not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/projects/{project_id} [delete]
 func (c *ProjectController) DeleteProject(ctx *gin.Context) {
 	// Get the validated request from context (set by validation middleware)
 	request, exists := middleware.GetValidatedRequest[models.DeleteProjectRequest](ctx)
 	if !exists {
-		errorResponse := models.ErrorResponse{
-			Code:    http.StatusBadRequest,
-			Message: "Missing validated request",
-			Details: "Validation middleware must be applied before this controller",
-		}
-		ctx.JSON(http.StatusBadRequest, errorResponse)
+		respondWithError(ctx, errMissingValidatedRequest)
 		return
 	}
 
 	// Call the service to delete the project
 	response, err := c.projectService.DeleteProject(ctx.Request.Context(), request.ProjectID)
 	if err != nil {
-		var statusCode int
-		var message string
-
-		// Check if it's a "not found" error
-		if err.Error() == "project not found" {
-			statusCode = http.StatusNotFound
-			message = "Project not found"
-		} else {
-			statusCode = http.StatusInternalServerError
-			message = "Failed to delete project"
-		}
-
-		errorResponse := models.ErrorResponse{
-			Code:    statusCode,
-			Message: message,
-			Details: err.Error(),
-		}
-		ctx.JSON(statusCode, errorResponse)
+		respondWithError(ctx, err)
 		return
 	}
 
@@ -224,34 +167,87 @@ func (c *ProjectController) DeleteProject(ctx *gin.Context) {
 // @Param next_token query string false "Token for pagination"
 // @Param max_results query int false "Maximum number of results to return" minimum(1) maximum(100)
 // @Param tag_filter query string false "Tag filter in format key:value"
+// @Param include_archived query bool false "List archived projects too"
+// @Param fields query string false "Comma-separated JSON fields to return; dotted paths select nested fields"
 // @Success 200 {object} models.ListProjectsResponse "Projects retrieved successfully"
-// @Failure 400 {object} models.ErrorResponse "Invalid request parameters"
-// @Failure 500 {object} models.ErrorResponse "Internal server error"
-// @Router /projects [get]
+// @Failure 400 {object} models.ProblemDetails "Invalid request parameters"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/projects [get]
 func (c *ProjectController) ListProjects(ctx *gin.Context) {
 	// Get the validated request from context (set by validation middleware)
 	request, exists := middleware.GetValidatedRequest[models.ListProjectsRequest](ctx)
 	if !exists {
-		errorResponse := models.ErrorResponse{
-			Code:    http.StatusBadRequest,
-			Message: "Missing validated request",
-			Details: "Validation middleware must be applied before this controller",
-		}
-		ctx.JSON(http.StatusBadRequest, errorResponse)
+		respondWithError(ctx, errMissingValidatedRequest)
 		return
 	}
 
 	// Call the service to list projects
 	response, err := c.projectService.ListProjects(ctx.Request.Context(), request)
 	if err != nil {
-		errorResponse := models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "Failed to list projects",
-			Details: err.Error(),
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse)
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithListFields(ctx, http.StatusOK, response, "projects")
+}
+
+// ArchiveProject handles POST /projects/:id/archive
+// @Summary Archive a project
+// @Description Archive a project so it rejects new tasks and agent syncs and is left out of default listings. Purging its artifacts deletes the project's agents along with their knowledge bases, vector stores and S3 data.
+// @Tags projects
+// @Produce json
+// @Param project_id path string true "Project ID"
+// @Param purge_artifacts query bool false "Delete the project's agents and their AI resources"
+// @Success 200 {object} models.ProjectLifecycleResponse "Project archived successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 404 {object} models.ProblemDetails "Project not found"
+// @Failure 412 {object} models.ProblemDetails "Project was modified concurrently"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/projects/{project_id}/archive [post]
+func (c *ProjectController) ArchiveProject(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.ArchiveProjectRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.projectService.ArchiveProject(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	setETag(ctx, response.Version)
+	ctx.JSON(http.StatusOK, response)
+}
+
+// UnarchiveProject handles POST /projects/:id/unarchive
+// @Summary Unarchive a project
+// @Description Make an archived project active again. Agents purged on archive aren't recreated.
+// @Tags projects
+// @Produce json
+// @Param project_id path string true "Project ID"
+// @Success 200 {object} models.ProjectLifecycleResponse "Project unarchived successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid project ID"
+// @Failure 404 {object} models.ProblemDetails "Project not found"
+// @Failure 412 {object} models.ProblemDetails "Project was modified concurrently"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/projects/{project_id}/unarchive [post]
+func (c *ProjectController) UnarchiveProject(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.UnarchiveProjectRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.projectService.UnarchiveProject(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
 		return
 	}
 
+	setETag(ctx, response.Version)
 	ctx.JSON(http.StatusOK, response)
 }
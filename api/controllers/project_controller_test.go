@@ -14,6 +14,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
 	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
 	"github.com/kazemisoroush/code-refactoring-tool/api/models"
 	servicesMocks "github.com/kazemisoroush/code-refactoring-tool/api/services/mocks"
@@ -97,6 +98,7 @@ func TestProjectController_CreateProject_InvalidRequest(t *testing.T) {
 
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
+	router.Use(middleware.NewErrorHandlerMiddleware().Handle())
 
 	// Use validation middleware with the controller
 	router.POST("/projects", middleware.NewJSONValidationMiddleware[models.CreateProjectRequest]().Handle(), controller.CreateProject)
@@ -109,13 +111,13 @@ func TestProjectController_CreateProject_InvalidRequest(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	var errorResponse models.ErrorResponse
+	var errorResponse models.ProblemDetails
 	err := json.Unmarshal(w.Body.Bytes(), &errorResponse)
 	require.NoError(t, err)
 
-	assert.Equal(t, http.StatusBadRequest, errorResponse.Code)
-	assert.Equal(t, "Validation failed", errorResponse.Message)
-	assert.Contains(t, errorResponse.Details, "Name is required")
+	assert.Equal(t, http.StatusBadRequest, errorResponse.Status)
+	assert.Equal(t, "validation_failed", errorResponse.Code)
+	assert.Contains(t, errorResponse.Detail, "Name is required")
 }
 
 func TestProjectController_CreateProject_ServiceError(t *testing.T) {
@@ -137,6 +139,7 @@ func TestProjectController_CreateProject_ServiceError(t *testing.T) {
 
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
+	router.Use(middleware.NewErrorHandlerMiddleware().Handle())
 
 	// Use validation middleware with the controller
 	router.POST("/projects", middleware.NewJSONValidationMiddleware[models.CreateProjectRequest]().Handle(), controller.CreateProject)
@@ -152,12 +155,12 @@ func TestProjectController_CreateProject_ServiceError(t *testing.T) {
 
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 
-	var errorResponse models.ErrorResponse
+	var errorResponse models.ProblemDetails
 	err = json.Unmarshal(w.Body.Bytes(), &errorResponse)
 	require.NoError(t, err)
 
-	assert.Equal(t, http.StatusInternalServerError, errorResponse.Code)
-	assert.Equal(t, "Failed to create project", errorResponse.Message)
+	assert.Equal(t, http.StatusInternalServerError, errorResponse.Status)
+	assert.Equal(t, "internal_error", errorResponse.Code)
 }
 
 func TestProjectController_GetProject_Success(t *testing.T) {
@@ -222,7 +225,7 @@ func TestProjectController_GetProject_NotFound(t *testing.T) {
 	projectID := "nonexistent-project"
 
 	// Mock service to return not found error
-	notFoundError := errors.New("project not found")
+	notFoundError := apperrors.NotFound(apperrors.CodeProjectNotFound, "project not found")
 	mockService.EXPECT().
 		GetProject(gomock.Any(), projectID).
 		Return(nil, notFoundError).
@@ -230,6 +233,7 @@ func TestProjectController_GetProject_NotFound(t *testing.T) {
 
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
+	router.Use(middleware.NewErrorHandlerMiddleware().Handle())
 
 	// Use validation middleware with the controller
 	router.GET("/projects/:project_id", middleware.NewURIValidationMiddleware[models.GetProjectRequest]().Handle(), controller.GetProject)
@@ -241,12 +245,13 @@ func TestProjectController_GetProject_NotFound(t *testing.T) {
 
 	assert.Equal(t, http.StatusNotFound, w.Code)
 
-	var errorResponse models.ErrorResponse
+	var errorResponse models.ProblemDetails
 	err := json.Unmarshal(w.Body.Bytes(), &errorResponse)
 	require.NoError(t, err)
 
-	assert.Equal(t, http.StatusNotFound, errorResponse.Code)
-	assert.Contains(t, errorResponse.Message, "Project not found")
+	assert.Equal(t, http.StatusNotFound, errorResponse.Status)
+	assert.Equal(t, "project_not_found", errorResponse.Code)
+	assert.Equal(t, "/projects/"+projectID, errorResponse.Instance)
 }
 
 func TestProjectController_GetProject_ServiceError(t *testing.T) {
@@ -257,7 +262,7 @@ func TestProjectController_GetProject_ServiceError(t *testing.T) {
 	controller := NewProjectController(mockService)
 
 	projectID := "proj-12345-abcde"
-	notFoundError := errors.New("project not found")
+	notFoundError := apperrors.NotFound(apperrors.CodeProjectNotFound, "project not found")
 
 	mockService.EXPECT().
 		GetProject(gomock.Any(), projectID).
@@ -266,6 +271,7 @@ func TestProjectController_GetProject_ServiceError(t *testing.T) {
 
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
+	router.Use(middleware.NewErrorHandlerMiddleware().Handle())
 
 	// Use validation middleware with the controller
 	router.GET("/projects/:project_id", middleware.NewURIValidationMiddleware[models.GetProjectRequest]().Handle(), controller.GetProject)
@@ -277,12 +283,13 @@ func TestProjectController_GetProject_ServiceError(t *testing.T) {
 
 	assert.Equal(t, http.StatusNotFound, w.Code)
 
-	var errorResponse models.ErrorResponse
+	var errorResponse models.ProblemDetails
 	err := json.Unmarshal(w.Body.Bytes(), &errorResponse)
 	require.NoError(t, err)
 
-	assert.Equal(t, http.StatusNotFound, errorResponse.Code)
-	assert.Equal(t, "Project not found", errorResponse.Message)
+	assert.Equal(t, http.StatusNotFound, errorResponse.Status)
+	assert.Equal(t, "Not Found", errorResponse.Title)
+	assert.Equal(t, "project not found", errorResponse.Detail)
 }
 
 func TestProjectController_UpdateProject_Success(t *testing.T) {
@@ -300,11 +307,13 @@ func TestProjectController_UpdateProject_Success(t *testing.T) {
 		Tags: map[string]string{
 			"env": "staging",
 		},
+		ExpectedVersion: 3,
 	}
 
 	expectedResponse := &models.UpdateProjectResponse{
 		ProjectID: projectID,
 		UpdatedAt: time.Now().UTC().Format(time.RFC3339),
+		Version:   4,
 	}
 
 	mockService.EXPECT().
@@ -323,11 +332,13 @@ func TestProjectController_UpdateProject_Success(t *testing.T) {
 
 	req := httptest.NewRequest(http.MethodPut, "/projects/"+projectID, bytes.NewReader(reqBody))
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `"3"`)
 	w := httptest.NewRecorder()
 
 	router.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"4"`, w.Header().Get("ETag"))
 
 	var response models.UpdateProjectResponse
 	err = json.Unmarshal(w.Body.Bytes(), &response)
@@ -337,6 +348,72 @@ func TestProjectController_UpdateProject_Success(t *testing.T) {
 	assert.Equal(t, expectedResponse.UpdatedAt, response.UpdatedAt)
 }
 
+func TestProjectController_UpdateProject_Preconditions(t *testing.T) {
+	tests := []struct {
+		name           string
+		ifMatch        string
+		serviceErr     error
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:           "missing_if_match",
+			expectedStatus: http.StatusPreconditionRequired,
+			expectedCode:   apperrors.CodePreconditionRequired,
+		},
+		{
+			name:           "malformed_if_match",
+			ifMatch:        "3",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   apperrors.CodeInvalidRequest,
+		},
+		{
+			name:           "stale_version",
+			ifMatch:        `"3"`,
+			serviceErr:     apperrors.PreconditionFailed(apperrors.CodeVersionMismatch, "project with ID proj-12345-abcde was modified since version 3"),
+			expectedStatus: http.StatusPreconditionFailed,
+			expectedCode:   apperrors.CodeVersionMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := servicesMocks.NewMockProjectService(ctrl)
+			controller := NewProjectController(mockService)
+
+			if tt.serviceErr != nil {
+				mockService.EXPECT().
+					UpdateProject(gomock.Any(), gomock.Any()).
+					Return(nil, tt.serviceErr).
+					Times(1)
+			}
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.Use(middleware.NewErrorHandlerMiddleware().Handle())
+			router.PUT("/projects/:project_id", middleware.NewCombinedValidationMiddleware[models.UpdateProjectRequest]().Handle(), controller.UpdateProject)
+
+			req := httptest.NewRequest(http.MethodPut, "/projects/proj-12345-abcde", bytes.NewReader([]byte(`{"name":"updated-project"}`)))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var problem models.ProblemDetails
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+			assert.Equal(t, tt.expectedCode, problem.Code)
+		})
+	}
+}
+
 func TestProjectController_DeleteProject_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -479,3 +556,30 @@ func TestProjectController_ListProjects_EmptyResult(t *testing.T) {
 	assert.Len(t, response.Projects, 0)
 	assert.Nil(t, response.NextToken)
 }
+
+func TestProjectController_ArchiveProject_PurgesArtifacts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := servicesMocks.NewMockProjectService(ctrl)
+	controller := NewProjectController(mockService)
+
+	mockService.EXPECT().
+		ArchiveProject(gomock.Any(), models.ArchiveProjectRequest{ProjectID: "proj-1", PurgeArtifacts: true}).
+		Return(&models.ProjectLifecycleResponse{ProjectID: "proj-1", Status: models.ProjectStatusArchived, Version: 4, PurgedAgentIDs: []string{"agent-1"}}, nil)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/projects/:project_id/archive", middleware.NewURIQueryValidationMiddleware[models.ArchiveProjectRequest]().Handle(), controller.ArchiveProject)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/projects/proj-1/archive?purge_artifacts=true", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"4"`, w.Header().Get("ETag"))
+
+	var response models.ProjectLifecycleResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, models.ProjectStatusArchived, response.Status)
+	assert.Equal(t, []string{"agent-1"}, response.PurgedAgentIDs)
+}
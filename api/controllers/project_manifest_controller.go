@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/services"
+)
+
+// ProjectManifestController handles project export and import HTTP requests
+type ProjectManifestController struct {
+	manifestService services.ProjectManifestService
+}
+
+// NewProjectManifestController creates a new ProjectManifestController
+func NewProjectManifestController(manifestService services.ProjectManifestService) *ProjectManifestController {
+	return &ProjectManifestController{
+		manifestService: manifestService,
+	}
+}
+
+// ExportProject handles GET /projects/:project_id/export
+// @Summary Export a project manifest
+// @Description Export a project, its codebases (with configuration references, never secrets), agents, schedules and webhooks as a declarative manifest
+// @Tags projects
+// @Produce json
+// @Produce application/x-yaml
+// @Param project_id path string true "Project ID"
+// @Param format query string false "Manifest format (default yaml)" Enums(yaml, json)
+// @Success 200 {object} models.ProjectManifest "Project exported successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 404 {object} models.ProblemDetails "Project not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/projects/{project_id}/export [get]
+func (c *ProjectManifestController) ExportProject(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.ExportProjectRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	format := ctx.DefaultQuery("format", "yaml")
+	if format != "yaml" && format != "json" {
+		respondWithError(ctx, apperrors.Validation(apperrors.CodeInvalidRequest, "format must be one of: yaml json"))
+		return
+	}
+
+	manifest, err := c.manifestService.ExportProject(ctx.Request.Context(), request.ProjectID)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	if format == "json" {
+		ctx.JSON(http.StatusOK, manifest)
+		return
+	}
+
+	ctx.YAML(http.StatusOK, manifest)
+}
+
+// ImportProject handles POST /projects/import
+// @Summary Import a project manifest
+// @Description Create a new project with its codebases, agents, schedules and webhooks from a manifest. Send YAML with Content-Type application/x-yaml or JSON with application/json.
+// @Tags projects
+// @Accept json
+// @Accept application/x-yaml
+// @Produce json
+// @Param request body models.ProjectManifest true "Project manifest"
+// @Success 201 {object} models.ImportProjectResponse "Project imported successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid manifest"
+// @Failure 403 {object} models.ProblemDetails "Caller may not modify a restricted tag"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/projects/import [post]
+func (c *ProjectManifestController) ImportProject(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.ProjectManifest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+	request.UserID = middleware.GetUserID(ctx)
+
+	response, err := c.manifestService.ImportProject(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	servicesMocks "github.com/kazemisoroush/code-refactoring-tool/api/services/mocks"
+)
+
+func setupProjectManifestRouter(controller *ProjectManifestController) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.NewErrorHandlerMiddleware().Handle())
+	router.GET("/projects/:project_id/export",
+		middleware.NewURIValidationMiddleware[models.ExportProjectRequest]().Handle(),
+		controller.ExportProject,
+	)
+	router.POST("/projects/import",
+		middleware.NewJSONValidationMiddleware[models.ProjectManifest]().Handle(),
+		controller.ImportProject,
+	)
+	return router
+}
+
+func testProjectManifest() *models.ProjectManifest {
+	return &models.ProjectManifest{
+		APIVersion: models.ProjectManifestAPIVersion,
+		Kind:       models.ProjectManifestKind,
+		Project:    models.ProjectManifestSpec{Name: "payments"},
+		Codebases: []models.CodebaseManifest{{
+			Name:      "payments",
+			Provider:  models.ProviderGitHub,
+			URL:       "https://github.com/acme/payments.git",
+			ConfigRef: "config-1",
+		}},
+	}
+}
+
+func TestProjectManifestController_ExportProject_YAML(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := servicesMocks.NewMockProjectManifestService(ctrl)
+	router := setupProjectManifestRouter(NewProjectManifestController(mockService))
+
+	mockService.EXPECT().ExportProject(gomock.Any(), "proj-1").Return(testProjectManifest(), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/projects/proj-1/export", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "yaml")
+
+	var manifest models.ProjectManifest
+	require.NoError(t, yaml.Unmarshal(w.Body.Bytes(), &manifest))
+	assert.Equal(t, "payments", manifest.Project.Name)
+	assert.Equal(t, "config-1", manifest.Codebases[0].ConfigRef)
+}
+
+func TestProjectManifestController_ExportProject_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := servicesMocks.NewMockProjectManifestService(ctrl)
+	router := setupProjectManifestRouter(NewProjectManifestController(mockService))
+
+	mockService.EXPECT().ExportProject(gomock.Any(), "proj-missing").Return(nil, apperrors.NotFound(apperrors.CodeProjectNotFound, "project not found"))
+
+	req := httptest.NewRequest(http.MethodGet, "/projects/proj-missing/export?format=json", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestProjectManifestController_ExportProject_InvalidFormat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	router := setupProjectManifestRouter(NewProjectManifestController(servicesMocks.NewMockProjectManifestService(ctrl)))
+
+	req := httptest.NewRequest(http.MethodGet, "/projects/proj-1/export?format=xml", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestProjectManifestController_ImportProject_YAML(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := servicesMocks.NewMockProjectManifestService(ctrl)
+	router := setupProjectManifestRouter(NewProjectManifestController(mockService))
+
+	mockService.EXPECT().
+		ImportProject(gomock.Any(), *testProjectManifest()).
+		Return(&models.ImportProjectResponse{ProjectID: "proj-2", CodebaseIDs: []string{"cb-1"}}, nil)
+
+	body, err := yaml.Marshal(testProjectManifest())
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/projects/import", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-yaml")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response models.ImportProjectResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "proj-2", response.ProjectID)
+}
+
+func TestProjectManifestController_ImportProject_UnknownConfig(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := servicesMocks.NewMockProjectManifestService(ctrl)
+	router := setupProjectManifestRouter(NewProjectManifestController(mockService))
+
+	mockService.EXPECT().
+		ImportProject(gomock.Any(), gomock.Any()).
+		Return(nil, apperrors.Validation(apperrors.CodeCodebaseConfigNotFound, "codebase configuration not found: config-1"))
+
+	body, err := json.Marshal(testProjectManifest())
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/projects/import", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
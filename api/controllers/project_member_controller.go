@@ -0,0 +1,146 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/services"
+)
+
+// ProjectMemberController handles project member and ownership HTTP requests
+type ProjectMemberController struct {
+	roleService services.RoleService
+}
+
+// NewProjectMemberController creates a new ProjectMemberController
+func NewProjectMemberController(roleService services.RoleService) *ProjectMemberController {
+	return &ProjectMemberController{
+		roleService: roleService,
+	}
+}
+
+// ListProjectMembers handles GET /projects/:project_id/members
+// @Summary List the members of a project
+// @Description List the users given access to the project and the role each holds within it. Requires the project:read permission within the project.
+// @Tags project-members
+// @Produce json
+// @Param project_id path string true "Project ID"
+// @Success 200 {object} models.ListProjectMembersResponse "Project members retrieved successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid project ID"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "Project not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/projects/{project_id}/members [get]
+func (c *ProjectMemberController) ListProjectMembers(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.ListProjectMembersRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.roleService.ListProjectMembers(ctx.Request.Context(), request.ProjectID)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// AddProjectMember handles POST /projects/:project_id/members
+// @Summary Add a member to a project
+// @Description Give a user access to the project with a role, or change the role of a member. The role replaces the user's own role within the project. The owner role is only given by transferring the ownership. Requires the member:manage permission within the project.
+// @Tags project-members
+// @Accept json
+// @Produce json
+// @Param project_id path string true "Project ID"
+// @Param request body models.AddProjectMemberRequest true "Project member request"
+// @Success 200 {object} models.ProjectMember "Project member added successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "Project, user or role not found"
+// @Failure 409 {object} models.ProblemDetails "User is the project owner"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/projects/{project_id}/members [post]
+func (c *ProjectMemberController) AddProjectMember(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.AddProjectMemberRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+	request.ActorID = middleware.GetUserID(ctx)
+
+	response, err := c.roleService.AddProjectMember(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// RemoveProjectMember handles DELETE /projects/:project_id/members/:user_id
+// @Summary Remove a member from a project
+// @Description Remove a member other than the owner from the project, so they hold their own role within it again. Requires the member:manage permission within the project.
+// @Tags project-members
+// @Param project_id path string true "Project ID"
+// @Param user_id path string true "User ID"
+// @Success 204
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "User is not a member of the project"
+// @Failure 409 {object} models.ProblemDetails "User is the project owner"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/projects/{project_id}/members/{user_id} [delete]
+func (c *ProjectMemberController) RemoveProjectMember(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.RemoveProjectMemberRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+	request.ActorID = middleware.GetUserID(ctx)
+
+	if err := c.roleService.RemoveProjectMember(ctx.Request.Context(), request); err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// TransferProjectOwnership handles POST /projects/:project_id/transfer-ownership
+// @Summary Transfer the ownership of a project
+// @Description Make an active user the owner of the project. The previous owner stays a member with the admin role. Requires the project:transfer permission within the project, which only owners hold by default.
+// @Tags project-members
+// @Accept json
+// @Produce json
+// @Param project_id path string true "Project ID"
+// @Param request body models.TransferProjectOwnershipRequest true "Ownership transfer request"
+// @Success 200 {object} models.ProjectMember "Ownership transferred successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request or inactive user"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "Project or user not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/projects/{project_id}/transfer-ownership [post]
+func (c *ProjectMemberController) TransferProjectOwnership(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.TransferProjectOwnershipRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+	request.ActorID = middleware.GetUserID(ctx)
+
+	response, err := c.roleService.TransferProjectOwnership(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/services"
+)
+
+// ProjectSummaryController handles project dashboard HTTP requests
+type ProjectSummaryController struct {
+	summaryService services.ProjectSummaryService
+}
+
+// NewProjectSummaryController creates a new ProjectSummaryController
+func NewProjectSummaryController(summaryService services.ProjectSummaryService) *ProjectSummaryController {
+	return &ProjectSummaryController{
+		summaryService: summaryService,
+	}
+}
+
+// GetProjectSummary handles GET /projects/:project_id/summary
+// @Summary Get a project dashboard summary
+// @Description Aggregate task counts by status and type over a window, codebase health, the last agent sync time and recent failures in a single call
+// @Tags projects
+// @Produce json
+// @Param project_id path string true "Project ID"
+// @Param window_days query int false "Days of task activity to aggregate (default 7, max 90)"
+// @Success 200 {object} models.GetProjectSummaryResponse "Project summary retrieved successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "Project not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/projects/{project_id}/summary [get]
+func (c *ProjectSummaryController) GetProjectSummary(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.GetProjectSummaryRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.summaryService.GetProjectSummary(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, response)
+}
@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	servicesMocks "github.com/kazemisoroush/code-refactoring-tool/api/services/mocks"
+)
+
+func setupProjectSummaryRouter(controller *ProjectSummaryController) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.NewErrorHandlerMiddleware().Handle())
+	router.GET("/projects/:project_id/summary",
+		middleware.NewURIQueryValidationMiddleware[models.GetProjectSummaryRequest]().Handle(),
+		controller.GetProjectSummary,
+	)
+	return router
+}
+
+func TestProjectSummaryController_GetProjectSummary(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := servicesMocks.NewMockProjectSummaryService(ctrl)
+	router := setupProjectSummaryRouter(NewProjectSummaryController(mockService))
+
+	mockService.EXPECT().
+		GetProjectSummary(gomock.Any(), models.GetProjectSummaryRequest{ProjectID: "proj-1", WindowDays: 30}).
+		Return(&models.GetProjectSummaryResponse{
+			ProjectID:      "proj-1",
+			Tasks:          models.TaskStatistics{Total: 3, ByStatus: map[string]int{"completed": 3}},
+			RecentFailures: []models.TaskFailure{},
+		}, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/projects/proj-1/summary?window_days=30", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response models.GetProjectSummaryResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 3, response.Tasks.Total)
+}
+
+func TestProjectSummaryController_GetProjectSummary_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		target         string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{
+			name:           "window_too_large",
+			target:         "/projects/proj-1/summary?window_days=365",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "project_not_found",
+			target:         "/projects/proj-missing/summary",
+			serviceErr:     apperrors.NotFound(apperrors.CodeProjectNotFound, "project not found"),
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := servicesMocks.NewMockProjectSummaryService(ctrl)
+			router := setupProjectSummaryRouter(NewProjectSummaryController(mockService))
+
+			if tt.serviceErr != nil {
+				mockService.EXPECT().GetProjectSummary(gomock.Any(), gomock.Any()).Return(nil, tt.serviceErr)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/services"
+)
+
+// ProjectTemplateController handles project template HTTP requests
+type ProjectTemplateController struct {
+	templateService services.ProjectTemplateService
+}
+
+// NewProjectTemplateController creates a new ProjectTemplateController
+func NewProjectTemplateController(templateService services.ProjectTemplateService) *ProjectTemplateController {
+	return &ProjectTemplateController{
+		templateService: templateService,
+	}
+}
+
+// ListProjectTemplates handles GET /project-templates
+// @Summary List project templates
+// @Description List built-in and custom project templates
+// @Tags project-templates
+// @Produce json
+// @Param fields query string false "Comma-separated JSON fields to return; dotted paths select nested fields"
+// @Success 200 {object} models.ListProjectTemplatesResponse "Templates retrieved successfully"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/project-templates [get]
+func (c *ProjectTemplateController) ListProjectTemplates(ctx *gin.Context) {
+	response, err := c.templateService.ListTemplates(ctx.Request.Context())
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithListFields(ctx, http.StatusOK, response, "templates")
+}
+
+// GetProjectTemplate handles GET /project-templates/:template_id
+// @Summary Get a project template
+// @Description Retrieve a built-in or custom project template by ID
+// @Tags project-templates
+// @Produce json
+// @Param template_id path string true "Template ID"
+// @Param fields query string false "Comma-separated JSON fields to return; dotted paths select nested fields"
+// @Success 200 {object} models.ProjectTemplate "Template retrieved successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid template ID"
+// @Failure 404 {object} models.ProblemDetails "Template not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/project-templates/{template_id} [get]
+func (c *ProjectTemplateController) GetProjectTemplate(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.GetProjectTemplateRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.templateService.GetTemplate(ctx.Request.Context(), request.TemplateID)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, response)
+}
+
+// CreateProjectTemplate handles POST /project-templates
+// @Summary Create a custom project template
+// @Description Define a reusable project template with default tags, codebase config skeletons, prompt templates, webhooks and scheduled analyses
+// @Tags project-templates
+// @Accept json
+// @Produce json
+// @Param request body models.CreateProjectTemplateRequest true "Project template creation request"
+// @Success 201 {object} models.CreateProjectTemplateResponse "Template created successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/project-templates [post]
+func (c *ProjectTemplateController) CreateProjectTemplate(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.CreateProjectTemplateRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.templateService.CreateTemplate(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// CreateProjectFromTemplate handles POST /projects/from-template
+// @Summary Bootstrap a project from a template
+// @Description Create a project using a template's default tags and language, returning the template's codebase config skeletons, prompt templates, webhooks and scheduled analyses
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Param request body models.CreateProjectFromTemplateRequest true "Project bootstrap request"
+// @Success 201 {object} models.CreateProjectFromTemplateResponse "Project created successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 403 {object} models.ProblemDetails "Caller may not modify a restricted tag"
+// @Failure 404 {object} models.ProblemDetails "Template not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/projects/from-template [post]
+func (c *ProjectTemplateController) CreateProjectFromTemplate(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.CreateProjectFromTemplateRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+	request.UserID = middleware.GetUserID(ctx)
+
+	response, err := c.templateService.CreateProjectFromTemplate(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	servicesMocks "github.com/kazemisoroush/code-refactoring-tool/api/services/mocks"
+)
+
+func setupProjectTemplateRouter(controller *ProjectTemplateController) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.NewErrorHandlerMiddleware().Handle())
+	router.POST("/projects/from-template",
+		middleware.NewJSONValidationMiddleware[models.CreateProjectFromTemplateRequest]().Handle(),
+		controller.CreateProjectFromTemplate,
+	)
+	return router
+}
+
+func TestProjectTemplateController_CreateProjectFromTemplate_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := servicesMocks.NewMockProjectTemplateService(ctrl)
+	router := setupProjectTemplateRouter(NewProjectTemplateController(mockService))
+
+	request := models.CreateProjectFromTemplateRequest{TemplateID: "go-microservice", Name: "payments"}
+	mockService.EXPECT().
+		CreateProjectFromTemplate(gomock.Any(), request).
+		Return(&models.CreateProjectFromTemplateResponse{ProjectID: "proj-1", TemplateID: "go-microservice"}, nil)
+
+	body, err := json.Marshal(request)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/projects/from-template", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response models.CreateProjectFromTemplateResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "proj-1", response.ProjectID)
+}
+
+func TestProjectTemplateController_CreateProjectFromTemplate_TemplateNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := servicesMocks.NewMockProjectTemplateService(ctrl)
+	router := setupProjectTemplateRouter(NewProjectTemplateController(mockService))
+
+	mockService.EXPECT().
+		CreateProjectFromTemplate(gomock.Any(), gomock.Any()).
+		Return(nil, apperrors.NotFound(apperrors.CodeProjectTemplateNotFound, "project template not found"))
+
+	req := httptest.NewRequest(http.MethodPost, "/projects/from-template",
+		bytes.NewReader([]byte(`{"template_id":"tmpl-missing","name":"x"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
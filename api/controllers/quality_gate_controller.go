@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/services"
+)
+
+// QualityGateController handles project severity rule and quality gate HTTP requests
+type QualityGateController struct {
+	qualityGateService services.QualityGateService
+}
+
+// NewQualityGateController creates a new QualityGateController
+func NewQualityGateController(qualityGateService services.QualityGateService) *QualityGateController {
+	return &QualityGateController{
+		qualityGateService: qualityGateService,
+	}
+}
+
+// GetQualityGatePolicy handles GET /projects/:project_id/quality-gates
+// @Summary Get a project's quality gate policy
+// @Description Get the rules setting the severity of the project's findings and the quality gates evaluated after each analysis. Projects without a policy use the default one, without rules or gates.
+// @Tags projects
+// @Produce json
+// @Param project_id path string true "Project ID"
+// @Success 200 {object} models.QualityGatePolicy "Quality gate policy retrieved successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "Project not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/projects/{project_id}/quality-gates [get]
+func (c *QualityGateController) GetQualityGatePolicy(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.GetQualityGatePolicyRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.qualityGateService.GetPolicy(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, response)
+}
+
+// UpdateQualityGatePolicy handles PUT /projects/:project_id/quality-gates
+// @Summary Update a project's quality gate policy
+// @Description Replace the severity rules and quality gates of a project. Gate results are recorded on each analysis task and fail the GitHub check runs of pull request tasks. Severity rules apply to the findings of the next analyses.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Param project_id path string true "Project ID"
+// @Param request body models.UpdateQualityGatePolicyRequest true "Quality gate policy update request"
+// @Success 200 {object} models.QualityGatePolicy "Quality gate policy updated successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request or gate"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "Project not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/projects/{project_id}/quality-gates [put]
+func (c *QualityGateController) UpdateQualityGatePolicy(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.UpdateQualityGatePolicyRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.qualityGateService.UpdatePolicy(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, response)
+}
@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/services"
+)
+
+// RedactionController handles project redaction policy and audit HTTP requests
+type RedactionController struct {
+	redactionService services.RedactionService
+}
+
+// NewRedactionController creates a new RedactionController
+func NewRedactionController(redactionService services.RedactionService) *RedactionController {
+	return &RedactionController{
+		redactionService: redactionService,
+	}
+}
+
+// GetRedactionPolicy handles GET /projects/:project_id/redaction-policy
+// @Summary Get a project's redaction policy
+// @Description Get the policy masking emails, credentials and custom patterns in the repository content of the project's agents before it is embedded. Projects without a policy use the default one.
+// @Tags projects
+// @Produce json
+// @Param project_id path string true "Project ID"
+// @Success 200 {object} models.RedactionPolicy "Redaction policy retrieved successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "Project not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/projects/{project_id}/redaction-policy [get]
+func (c *RedactionController) GetRedactionPolicy(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.GetRedactionPolicyRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.redactionService.GetPolicy(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, response)
+}
+
+// UpdateRedactionPolicy handles PUT /projects/:project_id/redaction-policy
+// @Summary Update a project's redaction policy
+// @Description Replace the custom patterns of a project's redaction policy and optionally switch email and credential masking. The policy applies from the next agent sync.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Param project_id path string true "Project ID"
+// @Param request body models.UpdateRedactionPolicyRequest true "Redaction policy update request"
+// @Success 200 {object} models.RedactionPolicy "Redaction policy updated successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request or pattern"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "Project not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/projects/{project_id}/redaction-policy [put]
+func (c *RedactionController) UpdateRedactionPolicy(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.UpdateRedactionPolicyRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.redactionService.UpdatePolicy(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, response)
+}
+
+// ListRedactionAudits handles GET /projects/:project_id/redaction-audits
+// @Summary List a project's redaction audits
+// @Description List the number of redactions per rule made in each sync of the project's agents, most recent first. Requests accepting application/x-ndjson export every audit, one per line, ignoring the limit.
+// @Tags projects
+// @Produce json
+// @Produce application/x-ndjson
+// @Param project_id path string true "Project ID"
+// @Param limit query int false "Maximum number of audits (default 50, max 100)"
+// @Success 200 {object} models.ListRedactionAuditsResponse "Redaction audits retrieved successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "Project not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/projects/{project_id}/redaction-audits [get]
+func (c *RedactionController) ListRedactionAudits(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.ListRedactionAuditsRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	if middleware.AcceptsNDJSON(ctx) {
+		respondWithExport(ctx, func(emit func(models.RedactionAudit) error) error {
+			return c.redactionService.ExportAudits(ctx.Request.Context(), request, emit)
+		})
+		return
+	}
+
+	response, err := c.redactionService.ListAudits(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, response)
+}
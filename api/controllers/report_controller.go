@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/services"
+)
+
+// ReportController handles reporting HTTP requests
+type ReportController struct {
+	reportService services.ReportService
+}
+
+// NewReportController creates a new ReportController
+func NewReportController(reportService services.ReportService) *ReportController {
+	return &ReportController{
+		reportService: reportService,
+	}
+}
+
+// GetTaskReport handles GET /reports/tasks
+// @Summary Get task metrics
+// @Description Report task success rate, mean duration, token usage and failure categories grouped by day, week or project. Metrics are read from rollups refreshed on a schedule, see refreshed_at.
+// @Tags reports
+// @Produce json
+// @Param project_id query string false "Restrict the report to a project"
+// @Param group_by query string false "Group by day, week or project (default day)"
+// @Param from query string false "First day of the report, YYYY-MM-DD (default 30 days before to)"
+// @Param to query string false "Last day of the report, YYYY-MM-DD (default today)"
+// @Success 200 {object} models.GetTaskReportResponse "Task report retrieved successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/reports/tasks [get]
+func (c *ReportController) GetTaskReport(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.GetTaskReportRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.reportService.GetTaskReport(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, response)
+}
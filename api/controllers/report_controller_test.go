@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	servicesMocks "github.com/kazemisoroush/code-refactoring-tool/api/services/mocks"
+)
+
+func setupReportRouter(controller *ReportController) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.NewErrorHandlerMiddleware().Handle())
+	router.GET("/reports/tasks",
+		middleware.NewQueryValidationMiddleware[models.GetTaskReportRequest]().Handle(),
+		controller.GetTaskReport,
+	)
+	return router
+}
+
+func TestReportController_GetTaskReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := servicesMocks.NewMockReportService(ctrl)
+	router := setupReportRouter(NewReportController(mockService))
+
+	mockService.EXPECT().
+		GetTaskReport(gomock.Any(), models.GetTaskReportRequest{ProjectID: "proj-1", GroupBy: models.ReportGroupByWeek, From: "2024-01-01"}).
+		Return(&models.GetTaskReportResponse{
+			GroupBy: models.ReportGroupByWeek,
+			Buckets: []models.TaskReportBucket{{Key: "2024-01-01", Total: 4, Completed: 3, Failed: 1, SuccessRate: 0.75}},
+		}, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/reports/tasks?project_id=proj-1&group_by=week&from=2024-01-01", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response models.GetTaskReportResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Buckets, 1)
+	assert.Equal(t, 0.75, response.Buckets[0].SuccessRate)
+}
+
+func TestReportController_GetTaskReport_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		target         string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{
+			name:           "invalid_group_by",
+			target:         "/reports/tasks?group_by=month",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid_date",
+			target:         "/reports/tasks?from=01/01/2024",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid_range",
+			target:         "/reports/tasks?from=2024-02-01&to=2024-01-01",
+			serviceErr:     apperrors.Validation(apperrors.CodeInvalidRequest, "from must not be after to"),
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := servicesMocks.NewMockReportService(ctrl)
+			router := setupReportRouter(NewReportController(mockService))
+
+			if tt.serviceErr != nil {
+				mockService.EXPECT().GetTaskReport(gomock.Any(), gomock.Any()).Return(nil, tt.serviceErr)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/services"
+)
+
+// RoleController handles role and permission HTTP requests
+type RoleController struct {
+	roleService services.RoleService
+}
+
+// NewRoleController creates a new RoleController
+func NewRoleController(roleService services.RoleService) *RoleController {
+	return &RoleController{
+		roleService: roleService,
+	}
+}
+
+// ListPermissions handles GET /permissions
+// @Summary List permissions
+// @Description List the permissions roles can grant. Requires the role:read permission.
+// @Tags roles
+// @Produce json
+// @Success 200 {object} models.ListPermissionsResponse "Permissions retrieved successfully"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/permissions [get]
+func (c *RoleController) ListPermissions(ctx *gin.Context) {
+	response, err := c.roleService.ListPermissions(ctx.Request.Context())
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// ListRoles handles GET /roles
+// @Summary List roles
+// @Description List the built-in and custom roles with the permissions they grant. Requires the role:read permission.
+// @Tags roles
+// @Produce json
+// @Success 200 {object} models.ListRolesResponse "Roles retrieved successfully"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/roles [get]
+func (c *RoleController) ListRoles(ctx *gin.Context) {
+	response, err := c.roleService.ListRoles(ctx.Request.Context())
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// GetRole handles GET /roles/:name
+// @Summary Get a role
+// @Description Retrieve a role with the permissions it grants. Requires the role:read permission.
+// @Tags roles
+// @Produce json
+// @Param name path string true "Role name"
+// @Success 200 {object} models.Role "Role retrieved successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid role name"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "Role not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/roles/{name} [get]
+func (c *RoleController) GetRole(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.GetRoleRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.roleService.GetRole(ctx.Request.Context(), request.Name)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// CreateRole handles POST /roles
+// @Summary Define a custom role
+// @Description Define a custom role granting a set of permissions, such as a security-reviewer role. Requires the role:manage permission.
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Param request body models.CreateRoleRequest true "Role creation request"
+// @Success 201 {object} models.Role "Role created successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request or unknown permission"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 409 {object} models.ProblemDetails "Role already exists"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/roles [post]
+func (c *RoleController) CreateRole(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.CreateRoleRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+	request.ActorID = middleware.GetUserID(ctx)
+
+	response, err := c.roleService.CreateRole(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// UpdateRole handles PUT /roles/:name
+// @Summary Update a role
+// @Description Replace the description and permissions of a role. The owner role always holds every permission and can't be changed. Requires the role:manage permission.
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Param name path string true "Role name"
+// @Param request body models.UpdateRoleRequest true "Role update request"
+// @Success 200 {object} models.Role "Role updated successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request, unknown permission or owner role"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "Role not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/roles/{name} [put]
+func (c *RoleController) UpdateRole(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.UpdateRoleRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+	request.ActorID = middleware.GetUserID(ctx)
+
+	response, err := c.roleService.UpdateRole(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// DeleteRole handles DELETE /roles/:name
+// @Summary Delete a custom role
+// @Description Delete a custom role no user holds, in their own right or within a project. Built-in roles can't be deleted. Requires the role:manage permission.
+// @Tags roles
+// @Param name path string true "Role name"
+// @Success 204
+// @Failure 400 {object} models.ProblemDetails "Invalid role name or built-in role"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "Role not found"
+// @Failure 409 {object} models.ProblemDetails "Role held by users"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/roles/{name} [delete]
+func (c *RoleController) DeleteRole(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.DeleteRoleRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+	request.ActorID = middleware.GetUserID(ctx)
+
+	if err := c.roleService.DeleteRole(ctx.Request.Context(), request); err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,217 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/services"
+)
+
+// ServiceAccountController handles the HTTP requests managing service accounts and their tokens
+type ServiceAccountController struct {
+	serviceAccountService services.ServiceAccountService
+}
+
+// NewServiceAccountController creates a new ServiceAccountController
+func NewServiceAccountController(serviceAccountService services.ServiceAccountService) *ServiceAccountController {
+	return &ServiceAccountController{
+		serviceAccountService: serviceAccountService,
+	}
+}
+
+// CreateServiceAccount handles POST /admin/service-accounts
+// @Summary Create a service account
+// @Description Create a service account for a non-human caller such as an infrastructure-as-code provider. The service account acts as a user with the given role, so it can be added to projects like any user, and authenticates with the tokens issued to it.
+// @Tags service-accounts
+// @Accept json
+// @Produce json
+// @Param request body models.CreateServiceAccountRequest true "Service account creation request"
+// @Success 201 {object} models.ServiceAccount "Service account created"
+// @Failure 400 {object} models.ProblemDetails "Invalid request or name"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "Role not found"
+// @Failure 409 {object} models.ProblemDetails "A service account with the name already exists"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/admin/service-accounts [post]
+func (c *ServiceAccountController) CreateServiceAccount(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.CreateServiceAccountRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+	request.CreatedBy = middleware.GetUserID(ctx)
+
+	account, err := c.serviceAccountService.CreateServiceAccount(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, account)
+}
+
+// GetServiceAccount handles GET /admin/service-accounts/:service_account_id
+// @Summary Get a service account
+// @Description Retrieve a service account and its role
+// @Tags service-accounts
+// @Produce json
+// @Param service_account_id path string true "Service account ID"
+// @Success 200 {object} models.ServiceAccount "Service account retrieved successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid service account ID"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "Service account not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/admin/service-accounts/{service_account_id} [get]
+func (c *ServiceAccountController) GetServiceAccount(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.GetServiceAccountRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	account, err := c.serviceAccountService.GetServiceAccount(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, account)
+}
+
+// ListServiceAccounts handles GET /admin/service-accounts
+// @Summary List the service accounts
+// @Description List the service accounts, ordered by name
+// @Tags service-accounts
+// @Produce json
+// @Success 200 {object} models.ListServiceAccountsResponse "Service accounts listed successfully"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/admin/service-accounts [get]
+func (c *ServiceAccountController) ListServiceAccounts(ctx *gin.Context) {
+	response, err := c.serviceAccountService.ListServiceAccounts(ctx.Request.Context())
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// DeleteServiceAccount handles DELETE /admin/service-accounts/:service_account_id
+// @Summary Delete a service account
+// @Description Delete a service account and the user it acts as. Its tokens stop working immediately.
+// @Tags service-accounts
+// @Param service_account_id path string true "Service account ID"
+// @Success 204
+// @Failure 400 {object} models.ProblemDetails "Invalid service account ID"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "Service account not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/admin/service-accounts/{service_account_id} [delete]
+func (c *ServiceAccountController) DeleteServiceAccount(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.DeleteServiceAccountRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	if err := c.serviceAccountService.DeleteServiceAccount(ctx.Request.Context(), request); err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// CreateServiceAccountToken handles POST /admin/service-accounts/:service_account_id/tokens
+// @Summary Issue a service account token
+// @Description Issue a token to a service account, sent as a bearer token like any access token. The token is only returned by this call; store it, as only its hash is kept.
+// @Tags service-accounts
+// @Accept json
+// @Produce json
+// @Param service_account_id path string true "Service account ID"
+// @Param request body models.CreateServiceAccountTokenRequest true "Token creation request"
+// @Success 201 {object} models.CreateServiceAccountTokenResponse "Token issued"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "Service account not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/admin/service-accounts/{service_account_id}/tokens [post]
+func (c *ServiceAccountController) CreateServiceAccountToken(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.CreateServiceAccountTokenRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.serviceAccountService.CreateToken(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// ListServiceAccountTokens handles GET /admin/service-accounts/:service_account_id/tokens
+// @Summary List the tokens of a service account
+// @Description List the tokens issued to a service account, newest first, including revoked and expired ones
+// @Tags service-accounts
+// @Produce json
+// @Param service_account_id path string true "Service account ID"
+// @Success 200 {object} models.ListServiceAccountTokensResponse "Tokens listed successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid service account ID"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "Service account not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/admin/service-accounts/{service_account_id}/tokens [get]
+func (c *ServiceAccountController) ListServiceAccountTokens(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.ListServiceAccountTokensRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.serviceAccountService.ListTokens(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// RevokeServiceAccountToken handles DELETE /admin/service-accounts/:service_account_id/tokens/:token_id
+// @Summary Revoke a service account token
+// @Description Revoke a token of a service account, which stops working immediately
+// @Tags service-accounts
+// @Param service_account_id path string true "Service account ID"
+// @Param token_id path string true "Token ID"
+// @Success 204
+// @Failure 400 {object} models.ProblemDetails "Invalid service account or token ID"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "Token not found or already revoked"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/admin/service-accounts/{service_account_id}/tokens/{token_id} [delete]
+func (c *ServiceAccountController) RevokeServiceAccountToken(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.RevokeServiceAccountTokenRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	if err := c.serviceAccountService.RevokeToken(ctx.Request.Context(), request); err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
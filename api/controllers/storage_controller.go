@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/services"
+)
+
+// StorageController handles the admin HTTP requests about the content agents uploaded to S3
+type StorageController struct {
+	storageService services.StorageLifecycleService
+}
+
+// NewStorageController creates a new StorageController
+func NewStorageController(storageService services.StorageLifecycleService) *StorageController {
+	return &StorageController{
+		storageService: storageService,
+	}
+}
+
+// ListStoragePurges handles GET /admin/storage/purges
+// @Summary List storage purges (Admin)
+// @Description List the most recent purges of the content deleted agents uploaded to S3, newest first, with the objects each deleted so far.
+// @Tags admin
+// @Produce json
+// @Param status query string false "Only list purges in this status" Enums(pending, running, completed, failed)
+// @Success 200 {object} models.ListStoragePurgesResponse "Storage purges retrieved successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 401 {object} models.ProblemDetails "Authentication required"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/admin/storage/purges [get]
+func (c *StorageController) ListStoragePurges(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.ListStoragePurgesRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.storageService.ListPurges(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, response)
+}
+
+// GetStoragePurge handles GET /admin/storage/purges/:purge_id
+// @Summary Get a storage purge (Admin)
+// @Description Retrieve the progress of a purge, such as the one deleting an agent's content returned when the agent was deleted
+// @Tags admin
+// @Produce json
+// @Param purge_id path string true "Purge ID"
+// @Success 200 {object} models.StoragePurge "Storage purge retrieved successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 401 {object} models.ProblemDetails "Authentication required"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "Storage purge not found"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/admin/storage/purges/{purge_id} [get]
+func (c *StorageController) GetStoragePurge(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.GetStoragePurgeRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	purge, err := c.storageService.GetPurge(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, purge)
+}
+
+// ListStorageOrphans handles GET /admin/storage/orphans
+// @Summary List orphaned storage prefixes (Admin)
+// @Description Reconcile the buckets of every allowed region with the agents, reporting the knowledge base prefixes no agent owns and no purge is deleting. They are left behind by setups that failed before saving their agent. The buckets are listed on every request.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} models.ListStorageOrphansResponse "Orphaned prefixes retrieved successfully"
+// @Failure 401 {object} models.ProblemDetails "Authentication required"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/admin/storage/orphans [get]
+func (c *StorageController) ListStorageOrphans(ctx *gin.Context) {
+	response, err := c.storageService.ListOrphans(ctx.Request.Context())
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, response)
+}
@@ -0,0 +1,156 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/services"
+)
+
+// TagController handles tag key registry HTTP requests
+type TagController struct {
+	tagService services.TagService
+}
+
+// NewTagController creates a new TagController
+func NewTagController(tagService services.TagService) *TagController {
+	return &TagController{
+		tagService: tagService,
+	}
+}
+
+// ListTagKeys handles GET /tag-keys
+// @Summary List registered tag keys
+// @Description List the tag keys registered with the rules the tags of projects, codebases and codebase configurations must follow
+// @Tags tags
+// @Produce json
+// @Success 200 {object} models.ListTagKeysResponse "Tag keys retrieved successfully"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/tag-keys [get]
+func (c *TagController) ListTagKeys(ctx *gin.Context) {
+	response, err := c.tagService.ListTagKeys(ctx.Request.Context())
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// GetTagKey handles GET /tag-keys/:key
+// @Summary Get a registered tag key
+// @Description Retrieve the rules of a registered tag key
+// @Tags tags
+// @Produce json
+// @Param key path string true "Tag key"
+// @Success 200 {object} models.TagKey "Tag key retrieved successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid tag key"
+// @Failure 404 {object} models.ProblemDetails "Tag key not registered"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/tag-keys/{key} [get]
+func (c *TagController) GetTagKey(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.GetTagKeyRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.tagService.GetTagKey(ctx.Request.Context(), request.Key)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// CreateTagKey handles POST /tag-keys
+// @Summary Register a tag key
+// @Description Register a tag key, optionally restricting its values to a list or a pattern and who may add, change or remove it. Requires the tag_key:manage permission.
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param request body models.CreateTagKeyRequest true "Tag key registration request"
+// @Success 201 {object} models.TagKey "Tag key registered successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 409 {object} models.ProblemDetails "Tag key already registered"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/tag-keys [post]
+func (c *TagController) CreateTagKey(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.CreateTagKeyRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.tagService.CreateTagKey(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// UpdateTagKey handles PUT /tag-keys/:key
+// @Summary Update a registered tag key
+// @Description Replace the rules of a registered tag key. Tags already set are kept until they are next changed. Requires the tag_key:manage permission.
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param key path string true "Tag key"
+// @Param request body models.UpdateTagKeyRequest true "Tag key update request"
+// @Success 200 {object} models.TagKey "Tag key updated successfully"
+// @Failure 400 {object} models.ProblemDetails "Invalid request"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "Tag key not registered"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/tag-keys/{key} [put]
+func (c *TagController) UpdateTagKey(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.UpdateTagKeyRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.tagService.UpdateTagKey(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// DeleteTagKey handles DELETE /tag-keys/:key
+// @Summary Unregister a tag key
+// @Description Unregister a tag key. Tags already set with it are kept. Requires the tag_key:manage permission.
+// @Tags tags
+// @Param key path string true "Tag key"
+// @Success 204
+// @Failure 400 {object} models.ProblemDetails "Invalid tag key"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "Tag key not registered"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/tag-keys/{key} [delete]
+func (c *TagController) DeleteTagKey(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.DeleteTagKeyRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	if err := c.tagService.DeleteTagKey(ctx.Request.Context(), request.Key); err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -4,19 +4,31 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
 	"github.com/kazemisoroush/code-refactoring-tool/api/models"
 	"github.com/kazemisoroush/code-refactoring-tool/api/services"
 )
 
-// TaskController handles HTTP requests for task operations
+// TaskController handles HTTP requests for task operations, task comments and feedback, and the LLM calls made for tasks
 type TaskController struct {
-	taskService services.TaskService
+	taskService     services.TaskService
+	commentService  services.TaskCommentService
+	feedbackService services.TaskFeedbackService
+	llmTraceService services.LLMTraceService
 }
 
 // NewTaskController creates a new task controller
-func NewTaskController(taskService services.TaskService) *TaskController {
+func NewTaskController(
+	taskService services.TaskService,
+	commentService services.TaskCommentService,
+	feedbackService services.TaskFeedbackService,
+	llmTraceService services.LLMTraceService,
+) *TaskController {
 	return &TaskController{
-		taskService: taskService,
+		taskService:     taskService,
+		commentService:  commentService,
+		feedbackService: feedbackService,
+		llmTraceService: llmTraceService,
 	}
 }
 
@@ -29,23 +41,23 @@ func NewTaskController(taskService services.TaskService) *TaskController {
 // @Param project_id path string true "Project ID"
 // @Param request body models.CreateTaskRequest true "Task creation request"
 // @Success 201 {object} models.CreateTaskResponse
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} models.ProblemDetails
+// @Failure 500 {object} models.ProblemDetails
 // @Router /api/v1/projects/{project_id}/tasks [post]
 func (c *TaskController) CreateTask(ctx *gin.Context) {
-	var req models.CreateTaskRequest
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	req, ok := bindTaskRequest[models.CreateTaskRequest](ctx)
+	if !ok {
 		return
 	}
 
 	// Get project ID from URL path
 	projectID := ctx.Param("project_id")
 	req.ProjectID = projectID
+	req.CreatedBy = middleware.GetUserID(ctx)
 
 	response, err := c.taskService.CreateTask(ctx.Request.Context(), &req)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(ctx, err)
 		return
 	}
 
@@ -54,24 +66,29 @@ func (c *TaskController) CreateTask(ctx *gin.Context) {
 
 // GetTask retrieves a task by ID
 // @Summary Get a task by ID
-// @Description Retrieve a task by its unique identifier
+// @Description Retrieve a task by its unique identifier. A queued task reports its queue_position, 1 for the task that runs next.
 // @Tags tasks
 // @Produce json
 // @Param id path string true "Task ID"
+// @Param fields query string false "Comma-separated JSON fields to return; dotted paths select nested fields"
 // @Success 200 {object} models.GetTaskResponse
-// @Failure 404 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 403 {object} models.ProblemDetails
+// @Failure 404 {object} models.ProblemDetails
+// @Failure 500 {object} models.ProblemDetails
 // @Router /api/v1/tasks/{id} [get]
 func (c *TaskController) GetTask(ctx *gin.Context) {
 	taskID := ctx.Param("id")
+	if !c.authorizeTask(ctx, taskID, models.PermissionTaskRead) {
+		return
+	}
 
 	response, err := c.taskService.GetTask(ctx.Request.Context(), taskID)
 	if err != nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		respondWithError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response)
+	respondWithFields(ctx, http.StatusOK, response)
 }
 
 // UpdateTask updates an existing task
@@ -83,23 +100,26 @@ func (c *TaskController) GetTask(ctx *gin.Context) {
 // @Param id path string true "Task ID"
 // @Param request body models.UpdateTaskRequest true "Task update request"
 // @Success 200 {object} models.UpdateTaskResponse
-// @Failure 400 {object} map[string]string
-// @Failure 404 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} models.ProblemDetails
+// @Failure 403 {object} models.ProblemDetails
+// @Failure 404 {object} models.ProblemDetails
+// @Failure 500 {object} models.ProblemDetails
 // @Router /api/v1/tasks/{id} [put]
 func (c *TaskController) UpdateTask(ctx *gin.Context) {
-	var req models.UpdateTaskRequest
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	req, ok := bindTaskRequest[models.UpdateTaskRequest](ctx)
+	if !ok {
 		return
 	}
 
 	// Get task ID from URL path
 	req.TaskID = ctx.Param("id")
+	if !c.authorizeTask(ctx, req.TaskID, models.PermissionTaskUpdate) {
+		return
+	}
 
 	response, err := c.taskService.UpdateTask(ctx.Request.Context(), &req)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(ctx, err)
 		return
 	}
 
@@ -112,15 +132,19 @@ func (c *TaskController) UpdateTask(ctx *gin.Context) {
 // @Tags tasks
 // @Param id path string true "Task ID"
 // @Success 204
-// @Failure 404 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 403 {object} models.ProblemDetails
+// @Failure 404 {object} models.ProblemDetails
+// @Failure 500 {object} models.ProblemDetails
 // @Router /api/v1/tasks/{id} [delete]
 func (c *TaskController) DeleteTask(ctx *gin.Context) {
 	taskID := ctx.Param("id")
+	if !c.authorizeTask(ctx, taskID, models.PermissionTaskDelete) {
+		return
+	}
 
 	err := c.taskService.DeleteTask(ctx.Request.Context(), taskID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(ctx, err)
 		return
 	}
 
@@ -129,41 +153,52 @@ func (c *TaskController) DeleteTask(ctx *gin.Context) {
 
 // ListTasks lists tasks for a project
 // @Summary List tasks for a project
-// @Description List all tasks for a specific project with optional filtering
+// @Description List all tasks for a specific project with optional filtering. Requests accepting application/x-ndjson export every matching task, one per line, ignoring limit and offset.
 // @Tags tasks
 // @Produce json
+// @Produce application/x-ndjson
 // @Param project_id path string true "Project ID"
-// @Param status query string false "Filter by task status" Enums(pending, in_progress, completed, failed, cancelled)
+// @Param status query string false "Filter by task status" Enums(pending, queued, in_progress, completed, failed, cancelled)
 // @Param type query string false "Filter by task type" Enums(code_analysis, refactoring, code_review, documentation, custom)
 // @Param agent_id query string false "Filter by agent ID"
+// @Param owner query string false "Filter by an owner of the files of the task's findings or changes, from the codebase's CODEOWNERS file"
+// @Param tag_filter query string false "Tag filter as tag_filter[key]=value; tasks must carry every tag"
 // @Param limit query int false "Number of results to return (default 20, max 100)"
 // @Param offset query int false "Number of results to skip (default 0)"
+// @Param fields query string false "Comma-separated JSON fields to return; dotted paths select nested fields"
 // @Success 200 {object} models.ListTasksResponse
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} models.ProblemDetails
+// @Failure 500 {object} models.ProblemDetails
 // @Router /api/v1/projects/{project_id}/tasks [get]
 func (c *TaskController) ListTasks(ctx *gin.Context) {
 	var req models.ListTasksRequest
 	if err := ctx.ShouldBindQuery(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondWithError(ctx, middleware.BodyError(err, "invalid request"))
 		return
 	}
 
 	// Get project ID from URL path
 	req.ProjectID = ctx.Param("project_id")
 
+	if middleware.AcceptsNDJSON(ctx) {
+		respondWithExport(ctx, func(emit func(models.Task) error) error {
+			return c.taskService.ExportTasks(ctx.Request.Context(), &req, emit)
+		})
+		return
+	}
+
 	response, err := c.taskService.ListTasks(ctx.Request.Context(), &req)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response)
+	respondWithListFields(ctx, http.StatusOK, response, "tasks")
 }
 
 // ExecuteTask executes a task immediately
 // @Summary Execute a task immediately
-// @Description Create and execute a task immediately (synchronously or asynchronously)
+// @Description Create and execute a task immediately, or queue it when async. Queued tasks run by priority, then fairly across projects.
 // @Tags tasks
 // @Accept json
 // @Produce json
@@ -171,22 +206,23 @@ func (c *TaskController) ListTasks(ctx *gin.Context) {
 // @Param request body models.ExecuteTaskRequest true "Task execution request"
 // @Success 200 {object} models.ExecuteTaskResponse "Synchronous execution completed"
 // @Success 202 {object} models.ExecuteTaskResponse "Asynchronous execution started"
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} models.ProblemDetails
+// @Failure 500 {object} models.ProblemDetails
+// @Failure 504 {object} models.ProblemDetails "Task exceeded its execution deadline"
 // @Router /api/v1/projects/{project_id}/tasks/execute [post]
 func (c *TaskController) ExecuteTask(ctx *gin.Context) {
-	var req models.ExecuteTaskRequest
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	req, ok := bindTaskRequest[models.ExecuteTaskRequest](ctx)
+	if !ok {
 		return
 	}
 
 	// Get project ID from URL path
 	req.ProjectID = ctx.Param("project_id")
+	req.CreatedBy = middleware.GetUserID(ctx)
 
 	response, err := c.taskService.ExecuteTask(ctx.Request.Context(), &req)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(ctx, err)
 		return
 	}
 
@@ -198,3 +234,401 @@ func (c *TaskController) ExecuteTask(ctx *gin.Context) {
 
 	ctx.JSON(statusCode, response)
 }
+
+// CreateTaskBatch creates several tasks in one request
+// @Summary Create a batch of tasks
+// @Description Validate up to 50 task specs and create them atomically. If any spec is invalid no task is created.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param request body models.CreateTaskBatchRequest true "Task batch creation request"
+// @Success 201 {object} models.CreateTaskBatchResponse "All tasks created"
+// @Failure 400 {object} models.CreateTaskBatchResponse "One or more task specs failed validation"
+// @Failure 403 {object} models.ProblemDetails
+// @Failure 500 {object} models.ProblemDetails
+// @Router /api/v1/tasks/batch [post]
+func (c *TaskController) CreateTaskBatch(ctx *gin.Context) {
+	request, exists := middleware.GetValidatedRequest[models.CreateTaskBatchRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	for i := range request.Tasks {
+		request.Tasks[i].CreatedBy = middleware.GetUserID(ctx)
+	}
+
+	response, err := c.taskService.CreateTaskBatch(ctx.Request.Context(), &request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	// Report per-item validation failures without creating anything
+	if !response.Created {
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// GetTaskBatch retrieves the aggregate progress of a task batch
+// @Summary Get task batch progress
+// @Description Retrieve status counts and overall progress for the tasks created by a batch request
+// @Tags tasks
+// @Produce json
+// @Param batchId path string true "Batch ID"
+// @Param fields query string false "Comma-separated JSON fields to return; dotted paths select nested fields"
+// @Success 200 {object} models.GetTaskBatchResponse
+// @Failure 403 {object} models.ProblemDetails
+// @Failure 404 {object} models.ProblemDetails
+// @Router /api/v1/tasks/batches/{batchId} [get]
+func (c *TaskController) GetTaskBatch(ctx *gin.Context) {
+	request, exists := middleware.GetValidatedRequest[models.GetTaskBatchRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.taskService.GetTaskBatch(ctx.Request.Context(), middleware.GetUserID(ctx), request.BatchID)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, response)
+}
+
+// CreateTaskComment comments on a task
+// @Summary Comment on a task
+// @Description Add a markdown comment to a task. Comments without parent_id start a review thread and may be anchored to a file and line range of the generated diff; replies set parent_id.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param request body models.CreateTaskCommentRequest true "Task comment creation request"
+// @Success 201 {object} models.TaskComment
+// @Failure 400 {object} models.ProblemDetails
+// @Failure 401 {object} models.ProblemDetails
+// @Failure 403 {object} models.ProblemDetails
+// @Failure 404 {object} models.ProblemDetails
+// @Failure 500 {object} models.ProblemDetails
+// @Router /api/v1/tasks/{id}/comments [post]
+func (c *TaskController) CreateTaskComment(ctx *gin.Context) {
+	request, exists := middleware.GetValidatedRequest[models.CreateTaskCommentRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	request.AuthorID = middleware.GetUserID(ctx)
+	if request.AuthorID == "" {
+		respondWithError(ctx, errUnauthenticatedCaller)
+		return
+	}
+	if !c.authorizeTask(ctx, request.TaskID, models.PermissionTaskRead) {
+		return
+	}
+
+	response, err := c.commentService.CreateComment(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// ListTaskComments lists a task's comments
+// @Summary List task comments
+// @Description List a task's comments and review thread replies, oldest first
+// @Tags tasks
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param fields query string false "Comma-separated JSON fields to return; dotted paths select nested fields"
+// @Success 200 {object} models.ListTaskCommentsResponse
+// @Failure 403 {object} models.ProblemDetails
+// @Failure 404 {object} models.ProblemDetails
+// @Failure 500 {object} models.ProblemDetails
+// @Router /api/v1/tasks/{id}/comments [get]
+func (c *TaskController) ListTaskComments(ctx *gin.Context) {
+	request, exists := middleware.GetValidatedRequest[models.ListTaskCommentsRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+	if !c.authorizeTask(ctx, request.TaskID, models.PermissionTaskRead) {
+		return
+	}
+
+	response, err := c.commentService.ListComments(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, response)
+}
+
+// SubmitTaskFeedback records the caller's feedback on a task's result
+// @Summary Give feedback on a task
+// @Description Rate the result of a completed task from 1 to 5 and record whether it was accepted, e.g. merged, or rejected. Each user gives a task a single feedback; submitting again replaces it. The outcome also approves or rejects the task, and feedback is counted in the task reports.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param request body models.SubmitTaskFeedbackRequest true "Task feedback"
+// @Success 200 {object} models.TaskFeedback
+// @Failure 400 {object} models.ProblemDetails
+// @Failure 401 {object} models.ProblemDetails
+// @Failure 403 {object} models.ProblemDetails
+// @Failure 404 {object} models.ProblemDetails
+// @Failure 409 {object} models.ProblemDetails "Task is not completed"
+// @Failure 500 {object} models.ProblemDetails
+// @Router /api/v1/tasks/{id}/feedback [post]
+func (c *TaskController) SubmitTaskFeedback(ctx *gin.Context) {
+	request, exists := middleware.GetValidatedRequest[models.SubmitTaskFeedbackRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	request.UserID = middleware.GetUserID(ctx)
+	if request.UserID == "" {
+		respondWithError(ctx, errUnauthenticatedCaller)
+		return
+	}
+	// The feedback's outcome approves or rejects the task
+	if !c.authorizeTask(ctx, request.TaskID, models.PermissionTaskUpdate) {
+		return
+	}
+
+	response, err := c.feedbackService.SubmitFeedback(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// ListTaskFeedback lists the feedback on a task
+// @Summary List task feedback
+// @Description List the users' ratings and outcomes of a task's result, oldest first
+// @Tags tasks
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param fields query string false "Comma-separated JSON fields to return; dotted paths select nested fields"
+// @Success 200 {object} models.ListTaskFeedbackResponse
+// @Failure 403 {object} models.ProblemDetails
+// @Failure 404 {object} models.ProblemDetails
+// @Failure 500 {object} models.ProblemDetails
+// @Router /api/v1/tasks/{id}/feedback [get]
+func (c *TaskController) ListTaskFeedback(ctx *gin.Context) {
+	request, exists := middleware.GetValidatedRequest[models.ListTaskFeedbackRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+	if !c.authorizeTask(ctx, request.TaskID, models.PermissionTaskRead) {
+		return
+	}
+
+	response, err := c.feedbackService.ListFeedback(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, response)
+}
+
+// GetTaskLLMTrace lists the LLM calls made for a task
+// @Summary Get the LLM trace of a task
+// @Description List the LLM calls made while executing a task, oldest first, with their context documents, latency and token counts. Prompts and responses are absent when the project's redaction policy disables LLM content logging.
+// @Tags tasks
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} models.GetLLMTraceResponse
+// @Failure 403 {object} models.ProblemDetails
+// @Failure 404 {object} models.ProblemDetails
+// @Failure 500 {object} models.ProblemDetails
+// @Router /api/v1/tasks/{id}/llm-trace [get]
+func (c *TaskController) GetTaskLLMTrace(ctx *gin.Context) {
+	request, exists := middleware.GetValidatedRequest[models.GetLLMTraceRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+	request.UserID = middleware.GetUserID(ctx)
+
+	response, err := c.llmTraceService.GetTrace(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// UpdateTaskComment edits a task comment
+// @Summary Edit a task comment
+// @Description Edit the body of one of the caller's comments
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param comment_id path string true "Comment ID"
+// @Param request body models.UpdateTaskCommentRequest true "Task comment update request"
+// @Success 200 {object} models.TaskComment
+// @Failure 400 {object} models.ProblemDetails
+// @Failure 401 {object} models.ProblemDetails
+// @Failure 403 {object} models.ProblemDetails
+// @Failure 404 {object} models.ProblemDetails
+// @Failure 500 {object} models.ProblemDetails
+// @Router /api/v1/tasks/{id}/comments/{comment_id} [put]
+func (c *TaskController) UpdateTaskComment(ctx *gin.Context) {
+	request, exists := middleware.GetValidatedRequest[models.UpdateTaskCommentRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	request.AuthorID = middleware.GetUserID(ctx)
+	if request.AuthorID == "" {
+		respondWithError(ctx, errUnauthenticatedCaller)
+		return
+	}
+	if !c.authorizeTask(ctx, request.TaskID, models.PermissionTaskRead) {
+		return
+	}
+
+	response, err := c.commentService.UpdateComment(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// DeleteTaskComment deletes a task comment
+// @Summary Delete a task comment
+// @Description Delete one of the caller's comments; deleting a thread's first comment deletes its replies
+// @Tags tasks
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param comment_id path string true "Comment ID"
+// @Success 200 {object} models.DeleteTaskCommentResponse
+// @Failure 401 {object} models.ProblemDetails
+// @Failure 403 {object} models.ProblemDetails
+// @Failure 404 {object} models.ProblemDetails
+// @Failure 500 {object} models.ProblemDetails
+// @Router /api/v1/tasks/{id}/comments/{comment_id} [delete]
+func (c *TaskController) DeleteTaskComment(ctx *gin.Context) {
+	request, exists := middleware.GetValidatedRequest[models.DeleteTaskCommentRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	request.AuthorID = middleware.GetUserID(ctx)
+	if request.AuthorID == "" {
+		respondWithError(ctx, errUnauthenticatedCaller)
+		return
+	}
+	if !c.authorizeTask(ctx, request.TaskID, models.PermissionTaskRead) {
+		return
+	}
+
+	response, err := c.commentService.DeleteComment(ctx.Request.Context(), request)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// ListStuckTasks lists the tasks whose executions stopped sending heartbeats
+// @Summary List stuck tasks (Admin)
+// @Description List the in_progress tasks without a heartbeat for longer than the stuck threshold, longest silent first. The janitor fails or requeues them on its next pass.
+// @Tags admin
+// @Produce json
+// @Param fields query string false "Comma-separated JSON fields to return; dotted paths select nested fields"
+// @Success 200 {object} models.ListStuckTasksResponse "Stuck tasks retrieved successfully"
+// @Failure 401 {object} models.ProblemDetails "Authentication required"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/admin/tasks/stuck [get]
+func (c *TaskController) ListStuckTasks(ctx *gin.Context) {
+	response, err := c.taskService.ListStuckTasks(ctx.Request.Context())
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, response)
+}
+
+// RequeueTask requeues a stuck task
+// @Summary Requeue a stuck task (Admin)
+// @Description Queue a stuck task again and run it in the background, resuming its execution after the last completed step
+// @Tags admin
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 202 {object} models.RequeueTaskResponse "Task requeued"
+// @Failure 401 {object} models.ProblemDetails "Authentication required"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 404 {object} models.ProblemDetails "Task not found"
+// @Failure 409 {object} models.ProblemDetails "Task is not stuck"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/admin/tasks/{id}/requeue [post]
+func (c *TaskController) RequeueTask(ctx *gin.Context) {
+	request, exists := middleware.GetValidatedRequest[models.RequeueTaskRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	response, err := c.taskService.RequeueTask(ctx.Request.Context(), request.TaskID)
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, response)
+}
+
+// authorizeTask checks that the caller holds a permission within the project of a task, responding with the error
+// when they don't. The global task routes aren't project-scoped, so the permission middleware can't evaluate them.
+func (c *TaskController) authorizeTask(ctx *gin.Context, taskID string, permission models.Permission) bool {
+	authID := middleware.GetUserID(ctx)
+	if authID == "" {
+		respondWithError(ctx, errUnauthenticatedCaller)
+		return false
+	}
+
+	if err := c.taskService.AuthorizeTask(ctx.Request.Context(), authID, taskID, permission); err != nil {
+		respondWithError(ctx, err)
+		return false
+	}
+
+	return true
+}
+
+// bindTaskRequest returns the request validated by the route's validation middleware, which consumed the body, or
+// binds it from the body when the route has none. It responds with the error and returns false when binding fails.
+func bindTaskRequest[T any](ctx *gin.Context) (T, bool) {
+	req, exists := middleware.GetValidatedRequest[T](ctx)
+	if exists {
+		return req, true
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondWithError(ctx, middleware.BodyError(err, "invalid request"))
+		return req, false
+	}
+	return req, true
+}
@@ -0,0 +1,466 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	servicesMocks "github.com/kazemisoroush/code-refactoring-tool/api/services/mocks"
+)
+
+func setupTaskCommentRouter(controller *TaskController, userID string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.NewErrorHandlerMiddleware().Handle())
+	router.Use(func(c *gin.Context) {
+		if userID != "" {
+			c.Set(middleware.UserIDContextKey, userID)
+		}
+		c.Next()
+	})
+	router.POST("/tasks/:id/comments",
+		middleware.NewCombinedValidationMiddleware[models.CreateTaskCommentRequest]().Handle(),
+		controller.CreateTaskComment,
+	)
+	router.DELETE("/tasks/:id/comments/:comment_id",
+		middleware.NewURIValidationMiddleware[models.DeleteTaskCommentRequest]().Handle(),
+		controller.DeleteTaskComment,
+	)
+	return router
+}
+
+func TestTaskController_CreateTaskComment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTaskService := servicesMocks.NewMockTaskService(ctrl)
+	mockService := servicesMocks.NewMockTaskCommentService(ctrl)
+	router := setupTaskCommentRouter(NewTaskController(mockTaskService, mockService, nil, nil), "user-1")
+
+	mockTaskService.EXPECT().AuthorizeTask(gomock.Any(), "user-1", "task-1", models.PermissionTaskRead).Return(nil)
+	mockService.EXPECT().
+		CreateComment(gomock.Any(), models.CreateTaskCommentRequest{
+			TaskID:   "task-1",
+			Body:     "Why was this inlined?",
+			Anchor:   &models.TaskCommentAnchor{FilePath: "main.go", StartLine: 12},
+			AuthorID: "user-1",
+		}).
+		Return(&models.TaskComment{CommentID: "comment-1", TaskID: "task-1", AuthorID: "user-1"}, nil)
+
+	body := `{"body":"Why was this inlined?","anchor":{"file_path":"main.go","start_line":12}}`
+	req := httptest.NewRequest(http.MethodPost, "/tasks/task-1/comments", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var response models.TaskComment
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "comment-1", response.CommentID)
+}
+
+func TestTaskController_CreateTaskComment_InvalidAnchor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	router := setupTaskCommentRouter(NewTaskController(servicesMocks.NewMockTaskService(ctrl), servicesMocks.NewMockTaskCommentService(ctrl), nil, nil), "user-1")
+
+	body := `{"body":"Why?","anchor":{"file_path":"main.go","start_line":12,"end_line":3}}`
+	req := httptest.NewRequest(http.MethodPost, "/tasks/task-1/comments", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestTaskController_DeleteTaskComment_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{name: "unauthenticated", expectedStatus: http.StatusUnauthorized},
+		{name: "not_author", userID: "user-2", serviceErr: apperrors.Forbidden(apperrors.CodeForbidden, "only the author can change comment comment-1"), expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockTaskService := servicesMocks.NewMockTaskService(ctrl)
+			mockService := servicesMocks.NewMockTaskCommentService(ctrl)
+			router := setupTaskCommentRouter(NewTaskController(mockTaskService, mockService, nil, nil), tt.userID)
+
+			if tt.serviceErr != nil {
+				mockTaskService.EXPECT().AuthorizeTask(gomock.Any(), tt.userID, "task-1", models.PermissionTaskRead).Return(nil)
+				mockService.EXPECT().
+					DeleteComment(gomock.Any(), models.DeleteTaskCommentRequest{TaskID: "task-1", CommentID: "comment-1", AuthorID: tt.userID}).
+					Return(nil, tt.serviceErr)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/tasks/task-1/comments/comment-1", nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestTaskController_GlobalTaskRoutes_Forbidden(t *testing.T) {
+	tests := []struct {
+		method     string
+		path       string
+		body       string
+		permission models.Permission
+	}{
+		{method: http.MethodGet, path: "/tasks/task-1", permission: models.PermissionTaskRead},
+		{method: http.MethodPut, path: "/tasks/task-1", body: `{"status":"cancelled"}`, permission: models.PermissionTaskUpdate},
+		{method: http.MethodDelete, path: "/tasks/task-1", permission: models.PermissionTaskDelete},
+		{method: http.MethodPost, path: "/tasks/task-1/comments", body: `{"body":"Why?"}`, permission: models.PermissionTaskRead},
+		{method: http.MethodGet, path: "/tasks/task-1/comments", permission: models.PermissionTaskRead},
+		{method: http.MethodPost, path: "/tasks/task-1/feedback", body: `{"rating":4,"outcome":"accepted"}`, permission: models.PermissionTaskUpdate},
+		{method: http.MethodGet, path: "/tasks/task-1/feedback", permission: models.PermissionTaskRead},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// The comment and feedback services have no expectations, so the request must stop at the authorization
+			mockService := servicesMocks.NewMockTaskService(ctrl)
+			controller := NewTaskController(mockService, servicesMocks.NewMockTaskCommentService(ctrl), servicesMocks.NewMockTaskFeedbackService(ctrl), nil)
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.Use(middleware.NewErrorHandlerMiddleware().Handle())
+			router.Use(func(c *gin.Context) {
+				c.Set(middleware.UserIDContextKey, "user-1")
+				c.Next()
+			})
+			router.GET("/tasks/:id", controller.GetTask)
+			router.PUT("/tasks/:id", middleware.NewCombinedValidationMiddleware[models.UpdateTaskRequest]().Handle(), controller.UpdateTask)
+			router.DELETE("/tasks/:id", controller.DeleteTask)
+			router.POST("/tasks/:id/comments", middleware.NewCombinedValidationMiddleware[models.CreateTaskCommentRequest]().Handle(), controller.CreateTaskComment)
+			router.GET("/tasks/:id/comments", middleware.NewURIValidationMiddleware[models.ListTaskCommentsRequest]().Handle(), controller.ListTaskComments)
+			router.POST("/tasks/:id/feedback", middleware.NewCombinedValidationMiddleware[models.SubmitTaskFeedbackRequest]().Handle(), controller.SubmitTaskFeedback)
+			router.GET("/tasks/:id/feedback", middleware.NewURIValidationMiddleware[models.ListTaskFeedbackRequest]().Handle(), controller.ListTaskFeedback)
+
+			mockService.EXPECT().
+				AuthorizeTask(gomock.Any(), "user-1", "task-1", tt.permission).
+				Return(apperrors.Forbidden(apperrors.CodeForbidden, "user-1 lacks %s", tt.permission))
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusForbidden, w.Code, w.Body.String())
+		})
+	}
+}
+
+func TestTaskController_CreateAndExecuteTask_AfterValidation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := servicesMocks.NewMockTaskService(ctrl)
+	controller := NewTaskController(mockService, nil, nil, nil)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserIDContextKey, "user-1")
+		c.Next()
+	})
+	router.POST("/projects/:project_id/tasks",
+		middleware.NewJSONValidationMiddleware[models.CreateTaskRequest]().Handle(),
+		controller.CreateTask,
+	)
+	router.POST("/projects/:project_id/tasks/execute",
+		middleware.NewJSONValidationMiddleware[models.ExecuteTaskRequest]().Handle(),
+		controller.ExecuteTask,
+	)
+
+	mockService.EXPECT().
+		CreateTask(gomock.Any(), &models.CreateTaskRequest{ProjectID: "proj-1", AgentID: "agent-1", Type: models.TaskTypeCustom, Title: "t", Description: "d", CreatedBy: "user-1"}).
+		Return(&models.CreateTaskResponse{TaskID: "task-1", Status: models.TaskStatusPending}, nil)
+	mockService.EXPECT().
+		ExecuteTask(gomock.Any(), &models.ExecuteTaskRequest{ProjectID: "proj-1", AgentID: "agent-1", Type: models.TaskTypeCustom, Title: "t", Description: "d", CreatedBy: "user-1"}).
+		Return(&models.ExecuteTaskResponse{TaskID: "task-2", Status: models.TaskStatusCompleted}, nil)
+
+	body := `{"project_id":"proj-1","agent_id":"agent-1","type":"custom","title":"t","description":"d"}`
+	for path, status := range map[string]int{"/projects/proj-1/tasks": http.StatusCreated, "/projects/proj-1/tasks/execute": http.StatusOK} {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, status, w.Code, w.Body.String())
+	}
+}
+
+func TestTaskController_UpdateTask_AfterValidation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := servicesMocks.NewMockTaskService(ctrl)
+	controller := NewTaskController(mockService, nil, nil, nil)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.NewErrorHandlerMiddleware().Handle())
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserIDContextKey, "user-1")
+		c.Next()
+	})
+	router.PUT("/tasks/:id",
+		middleware.NewCombinedValidationMiddleware[models.UpdateTaskRequest]().Handle(),
+		controller.UpdateTask,
+	)
+
+	// The body consumed by the validation middleware still reaches the service
+	status := models.TaskStatusCancelled
+	mockService.EXPECT().AuthorizeTask(gomock.Any(), "user-1", "task-1", models.PermissionTaskUpdate).Return(nil)
+	mockService.EXPECT().
+		UpdateTask(gomock.Any(), &models.UpdateTaskRequest{TaskID: "task-1", Status: &status, Metadata: map[string]string{"reason": "duplicate"}}).
+		Return(&models.UpdateTaskResponse{Task: models.Task{TaskID: "task-1", Status: status}}, nil)
+
+	req := httptest.NewRequest(http.MethodPut, "/tasks/task-1", strings.NewReader(`{"status":"cancelled","metadata":{"reason":"duplicate"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var response models.UpdateTaskResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, models.TaskStatusCancelled, response.Status)
+}
+
+func TestTaskController_ListTasks_ExportsNDJSON(t *testing.T) {
+	tests := []struct {
+		name          string
+		exported      []models.Task
+		exportErr     error
+		expectStatus  int
+		expectType    string
+		expectLines   []string
+		expectProblem string
+	}{
+		{
+			name:         "streams_every_task",
+			exported:     []models.Task{{TaskID: "task-1"}, {TaskID: "task-2"}},
+			expectStatus: http.StatusOK,
+			expectType:   models.NDJSONContentType,
+			expectLines:  []string{`{"task_id":"task-1"}`, `{"task_id":"task-2"}`},
+		},
+		{
+			name:         "empty_export",
+			expectStatus: http.StatusOK,
+			expectType:   models.NDJSONContentType,
+		},
+		{
+			name:          "fails_before_streaming",
+			exportErr:     apperrors.NotFound(apperrors.CodeProjectNotFound, "project not found"),
+			expectStatus:  http.StatusNotFound,
+			expectType:    models.ProblemContentType,
+			expectProblem: apperrors.CodeProjectNotFound,
+		},
+		{
+			name:          "fails_while_streaming",
+			exported:      []models.Task{{TaskID: "task-1"}},
+			exportErr:     errors.New("connection reset"),
+			expectStatus:  http.StatusOK,
+			expectType:    models.NDJSONContentType,
+			expectLines:   []string{`{"task_id":"task-1"}`},
+			expectProblem: apperrors.CodeInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := servicesMocks.NewMockTaskService(ctrl)
+			mockService.EXPECT().
+				ExportTasks(gomock.Any(), &models.ListTasksRequest{ProjectID: "proj-1"}, gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ *models.ListTasksRequest, fn func(models.Task) error) error {
+					for _, task := range tt.exported {
+						if err := fn(task); err != nil {
+							return err
+						}
+					}
+					return tt.exportErr
+				})
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.Use(middleware.NewErrorHandlerMiddleware().Handle())
+			router.GET("/projects/:project_id/tasks", NewTaskController(mockService, nil, nil, nil).ListTasks)
+
+			req := httptest.NewRequest(http.MethodGet, "/projects/proj-1/tasks?fields=task_id", nil)
+			req.Header.Set("Accept", models.NDJSONContentType)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectStatus, w.Code)
+			assert.Equal(t, tt.expectType, w.Header().Get("Content-Type"))
+
+			var lines []string
+			for _, line := range strings.Split(strings.TrimSpace(w.Body.String()), "\n") {
+				if line != "" {
+					lines = append(lines, line)
+				}
+			}
+			if tt.expectProblem == "" {
+				assert.Equal(t, tt.expectLines, lines)
+				return
+			}
+
+			require.NotEmpty(t, lines)
+			assert.ElementsMatch(t, tt.expectLines, lines[:len(lines)-1])
+			last := []byte(lines[len(lines)-1])
+			if tt.expectType == models.ProblemContentType {
+				var problem models.ProblemDetails
+				require.NoError(t, json.Unmarshal(last, &problem))
+				assert.Equal(t, tt.expectProblem, problem.Code)
+				return
+			}
+			var exportErr models.ExportError
+			require.NoError(t, json.Unmarshal(last, &exportErr))
+			assert.Equal(t, tt.expectProblem, exportErr.Error.Code)
+		})
+	}
+}
+
+func TestTaskController_RequeueTask(t *testing.T) {
+	tests := []struct {
+		name           string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{name: "requeued", expectedStatus: http.StatusAccepted},
+		{name: "not_stuck", serviceErr: apperrors.Conflict(apperrors.CodeTaskNotStuck, "task task-1 is completed and not stuck"), expectedStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := servicesMocks.NewMockTaskService(ctrl)
+			controller := NewTaskController(mockService, servicesMocks.NewMockTaskCommentService(ctrl), nil, nil)
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.Use(middleware.NewErrorHandlerMiddleware().Handle())
+			router.POST("/admin/tasks/:id/requeue",
+				middleware.NewURIValidationMiddleware[models.RequeueTaskRequest]().Handle(),
+				controller.RequeueTask,
+			)
+
+			if tt.serviceErr != nil {
+				mockService.EXPECT().RequeueTask(gomock.Any(), "task-1").Return(nil, tt.serviceErr)
+			} else {
+				mockService.EXPECT().RequeueTask(gomock.Any(), "task-1").
+					Return(&models.RequeueTaskResponse{TaskID: "task-1", Status: models.TaskStatusQueued}, nil)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/tasks/task-1/requeue", nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestTaskController_GetTaskLLMTrace(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := servicesMocks.NewMockLLMTraceService(ctrl)
+	controller := NewTaskController(servicesMocks.NewMockTaskService(ctrl), servicesMocks.NewMockTaskCommentService(ctrl), nil, mockService)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.NewErrorHandlerMiddleware().Handle())
+	router.GET("/tasks/:id/llm-trace",
+		middleware.NewURIValidationMiddleware[models.GetLLMTraceRequest]().Handle(),
+		controller.GetTaskLLMTrace,
+	)
+
+	mockService.EXPECT().
+		GetTrace(gomock.Any(), models.GetLLMTraceRequest{TaskID: "task-1"}).
+		Return(&models.GetLLMTraceResponse{
+			TaskID:       "task-1",
+			Interactions: []models.LLMInteraction{{InteractionID: "llm-1", TaskID: "task-1", Step: 1, PromptTokens: 120}},
+			PromptTokens: 120,
+		}, nil)
+	mockService.EXPECT().
+		GetTrace(gomock.Any(), models.GetLLMTraceRequest{TaskID: "missing"}).
+		Return(nil, apperrors.NotFound(apperrors.CodeTaskNotFound, "task not found: missing"))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tasks/task-1/llm-trace", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response models.GetLLMTraceResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Interactions, 1)
+	assert.Equal(t, 120, response.PromptTokens)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tasks/missing/llm-trace", nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestTaskController_SubmitTaskFeedback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTaskService := servicesMocks.NewMockTaskService(ctrl)
+	mockService := servicesMocks.NewMockTaskFeedbackService(ctrl)
+	controller := NewTaskController(mockTaskService, nil, mockService, nil)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.NewErrorHandlerMiddleware().Handle())
+	router.POST("/tasks/:id/feedback", func(c *gin.Context) {
+		c.Set(middleware.UserIDContextKey, "user-1")
+	}, middleware.NewCombinedValidationMiddleware[models.SubmitTaskFeedbackRequest]().Handle(), controller.SubmitTaskFeedback)
+
+	mockTaskService.EXPECT().AuthorizeTask(gomock.Any(), "user-1", "task-1", models.PermissionTaskUpdate).Return(nil)
+	mockService.EXPECT().
+		SubmitFeedback(gomock.Any(), models.SubmitTaskFeedbackRequest{
+			TaskID: "task-1", Rating: 4, Comment: "Merged as is", Outcome: models.TaskFeedbackOutcomeAccepted, UserID: "user-1",
+		}).
+		Return(&models.TaskFeedback{TaskID: "task-1", UserID: "user-1", Rating: 4, Outcome: models.TaskFeedbackOutcomeAccepted}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks/task-1/feedback", strings.NewReader(`{"rating":4,"comment":"Merged as is","outcome":"accepted"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/tasks/task-1/feedback", strings.NewReader(`{"rating":6,"outcome":"merged"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/services"
+)
+
+// UploadController handles the HTTP requests uploading large task inputs
+type UploadController struct {
+	uploadService services.UploadService
+}
+
+// NewUploadController creates a new UploadController
+func NewUploadController(uploadService services.UploadService) *UploadController {
+	return &UploadController{
+		uploadService: uploadService,
+	}
+}
+
+// UploadTaskInput handles POST /projects/:project_id/uploads
+// @Summary Upload a task input file
+// @Description Upload a file too large to send as JSON, such as a diff or an archive to analyze, as the multipart form field "file". The file is streamed to S3 as it's received, up to the body size limit of the route. Tasks of the project reference it by setting the upload_id field of their input, and their executors find its location in the upload field.
+// @Tags tasks
+// @Accept multipart/form-data
+// @Produce json
+// @Param project_id path string true "Project ID"
+// @Param file formData file true "File to upload"
+// @Success 201 {object} models.TaskInputUpload "File uploaded successfully"
+// @Failure 400 {object} models.ProblemDetails "Not a multipart form with a file"
+// @Failure 403 {object} models.ProblemDetails "Caller can't create tasks in the project"
+// @Failure 413 {object} models.ProblemDetails "File larger than the route accepts"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/projects/{project_id}/uploads [post]
+func (c *UploadController) UploadTaskInput(ctx *gin.Context) {
+	// Get the validated request from context (set by validation middleware)
+	request, exists := middleware.GetValidatedRequest[models.UploadTaskInputRequest](ctx)
+	if !exists {
+		respondWithError(ctx, errMissingValidatedRequest)
+		return
+	}
+
+	// Stream the file part of the form rather than parsing the whole form into memory or temporary files
+	form, err := ctx.Request.MultipartReader()
+	if err != nil {
+		respondWithError(ctx, apperrors.Wrap(apperrors.ErrValidation, apperrors.CodeInvalidRequest, err, "request must be a multipart/form-data upload"))
+		return
+	}
+	for {
+		part, err := form.NextPart()
+		if errors.Is(err, io.EOF) {
+			respondWithError(ctx, apperrors.Validation(apperrors.CodeInvalidRequest, "multipart form has no %s field", models.UploadFormField))
+			return
+		}
+		if err != nil {
+			respondWithError(ctx, middleware.BodyError(err, "invalid multipart form"))
+			return
+		}
+		if part.FormName() != models.UploadFormField {
+			continue
+		}
+
+		upload, err := c.uploadService.UploadTaskInput(ctx.Request.Context(), request.ProjectID, part.FileName(),
+			part.Header.Get("Content-Type"), middleware.GetUserID(ctx), uploadReader{part})
+		if err != nil {
+			respondWithError(ctx, err)
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, upload)
+		return
+	}
+}
+
+// uploadReader reads an uploaded file from the request body, failing with the error of the request when the body
+// can't be read, such as when it goes past its limit
+type uploadReader struct {
+	reader io.Reader
+}
+
+func (r uploadReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if err != nil && !errors.Is(err, io.EOF) {
+		err = middleware.BodyError(err, "failed to read uploaded file")
+	}
+	return n, err
+}
@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	servicesMocks "github.com/kazemisoroush/code-refactoring-tool/api/services/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+func newUploadRouter(mockService *servicesMocks.MockUploadService, maxBodySize int64) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.NewErrorHandlerMiddleware().Handle())
+	router.Use(middleware.NewBodyLimitMiddleware(config.HTTPConfig{MaxBodySize: maxBodySize}).Handle())
+	router.POST("/projects/:project_id/uploads",
+		middleware.NewURIValidationMiddleware[models.UploadTaskInputRequest]().Handle(),
+		NewUploadController(mockService).UploadTaskInput)
+	return router
+}
+
+func multipartBody(t *testing.T, field, filename, content string) (*bytes.Buffer, string) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	require.NoError(t, writer.WriteField("description", "nightly diff"))
+	part, err := writer.CreateFormFile(field, filename)
+	require.NoError(t, err)
+	_, err = part.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return body, writer.FormDataContentType()
+}
+
+func TestUploadController_UploadTaskInput(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := servicesMocks.NewMockUploadService(ctrl)
+	router := newUploadRouter(mockService, 0)
+
+	mockService.EXPECT().
+		UploadTaskInput(gomock.Any(), "proj-1", "change.diff", "application/octet-stream", "", gomock.Any()).
+		DoAndReturn(func(_ context.Context, projectID, filename, contentType, _ string, body io.Reader) (*models.TaskInputUpload, error) {
+			content, err := io.ReadAll(body)
+			require.NoError(t, err)
+			assert.Equal(t, "+func Div", string(content))
+			return &models.TaskInputUpload{UploadID: "upl-1", ProjectID: projectID, Filename: filename, Size: int64(len(content))}, nil
+		})
+
+	body, contentType := multipartBody(t, models.UploadFormField, "change.diff", "+func Div")
+	req := httptest.NewRequest(http.MethodPost, "/projects/proj-1/uploads", body)
+	req.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var response models.TaskInputUpload
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "upl-1", response.UploadID)
+	assert.Equal(t, int64(9), response.Size)
+}
+
+func TestUploadController_UploadTaskInput_Errors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := servicesMocks.NewMockUploadService(ctrl)
+	router := newUploadRouter(mockService, 0)
+
+	t.Run("not a multipart form", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/projects/proj-1/uploads", strings.NewReader(`{"diff":"+func Div"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("missing file field", func(t *testing.T) {
+		body, contentType := multipartBody(t, "attachment", "change.diff", "+func Div")
+		req := httptest.NewRequest(http.MethodPost, "/projects/proj-1/uploads", body)
+		req.Header.Set("Content-Type", contentType)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "no file field")
+	})
+}
+
+func TestUploadController_UploadTaskInput_TooLarge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := servicesMocks.NewMockUploadService(ctrl)
+	router := newUploadRouter(mockService, 512)
+
+	// The service fails with the error of the body going past its limit while streaming the file
+	mockService.EXPECT().
+		UploadTaskInput(gomock.Any(), "proj-1", "change.diff", gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _, _, _ string, body io.Reader) (*models.TaskInputUpload, error) {
+			_, err := io.ReadAll(body)
+			return nil, err
+		})
+
+	body, contentType := multipartBody(t, models.UploadFormField, "change.diff", strings.Repeat("+func Div\n", 100))
+	req := httptest.NewRequest(http.MethodPost, "/projects/proj-1/uploads", body)
+	req.Header.Set("Content-Type", contentType)
+	// A chunked request only goes past its limit while it's read
+	req.ContentLength = -1
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
@@ -0,0 +1,41 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/services"
+)
+
+// WorkspaceController handles the admin HTTP requests about the workspaces codebases are cloned into
+type WorkspaceController struct {
+	workspaceService services.WorkspaceService
+}
+
+// NewWorkspaceController creates a new WorkspaceController
+func NewWorkspaceController(workspaceService services.WorkspaceService) *WorkspaceController {
+	return &WorkspaceController{
+		workspaceService: workspaceService,
+	}
+}
+
+// GetWorkspaceUsage handles GET /admin/workspaces
+// @Summary Get workspace disk usage (Admin)
+// @Description Report the disk quota of the task runner serving the request and the workspaces using it, from least to most recently used. Idle workspaces are evicted in that order when the quota is reached.
+// @Tags admin
+// @Produce json
+// @Param fields query string false "Comma-separated JSON fields to return; dotted paths select nested fields"
+// @Success 200 {object} models.GetWorkspaceUsageResponse "Workspace usage retrieved successfully"
+// @Failure 401 {object} models.ProblemDetails "Authentication required"
+// @Failure 403 {object} models.ProblemDetails "Caller lacks the permission"
+// @Failure 500 {object} models.ProblemDetails "Internal server error"
+// @Router /api/v1/admin/workspaces [get]
+func (c *WorkspaceController) GetWorkspaceUsage(ctx *gin.Context) {
+	response, err := c.workspaceService.GetUsage(ctx.Request.Context())
+	if err != nil {
+		respondWithError(ctx, err)
+		return
+	}
+
+	respondWithFields(ctx, http.StatusOK, response)
+}
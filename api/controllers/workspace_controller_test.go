@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	servicesMocks "github.com/kazemisoroush/code-refactoring-tool/api/services/mocks"
+)
+
+func TestWorkspaceController_GetWorkspaceUsage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := servicesMocks.NewMockWorkspaceService(ctrl)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.NewErrorHandlerMiddleware().Handle())
+	router.GET("/admin/workspaces", NewWorkspaceController(mockService).GetWorkspaceUsage)
+
+	mockService.EXPECT().GetUsage(gomock.Any()).Return(&models.GetWorkspaceUsageResponse{
+		Host:       "runner-1",
+		QuotaBytes: 1000,
+		UsedBytes:  300,
+		IdleBytes:  300,
+		Workspaces: []models.Workspace{{WorkspaceID: "ws-1", SizeBytes: 300, Status: models.WorkspaceStatusIdle}},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/workspaces?fields=used_bytes,quota_bytes", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, map[string]any{"used_bytes": float64(300), "quota_bytes": float64(1000)}, response)
+}
@@ -2,33 +2,56 @@
 package middleware
 
 import (
-	"net/http"
+	"context"
+	"log/slog"
+	"slices"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
 	"github.com/kazemisoroush/code-refactoring-tool/api/models"
 	"github.com/kazemisoroush/code-refactoring-tool/pkg/auth"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/logging"
 )
 
+// Context keys under which the authentication middleware stores the caller's identity
+const (
+	UserIDContextKey      = "user_id"
+	EmailContextKey       = "email"
+	AccessTokenContextKey = "access_token"
+)
+
+// TokenValidator validates tokens issued by the API itself rather than the auth provider
+type TokenValidator interface {
+	ValidateToken(ctx context.Context, token string) (*auth.TokenClaims, error)
+}
+
 // AuthMiddleware provides provider-agnostic authentication middleware
 // This middleware delegates token validation to the configured AuthProvider,
 // allowing easy switching between Cognito, Auth0, Firebase, or any other provider
 // that implements the AuthProvider interface.
 type AuthMiddleware struct {
-	authProvider auth.AuthProvider
+	authProvider  auth.AuthProvider
+	serviceTokens TokenValidator
+	policy        AuthPolicy
 }
 
-// NewAuthMiddleware creates a new authentication middleware
-func NewAuthMiddleware(authProvider auth.AuthProvider) Middleware {
+// NewAuthMiddleware creates a new authentication middleware. Service account tokens, recognised by their prefix, are
+// validated by serviceTokens instead of the auth provider; a nil serviceTokens leaves them to the auth provider.
+// Requests to the paths config makes public are served without authentication.
+func NewAuthMiddleware(authProvider auth.AuthProvider, serviceTokens TokenValidator, config config.AuthConfig) Middleware {
 	return &AuthMiddleware{
-		authProvider: authProvider,
+		authProvider:  authProvider,
+		serviceTokens: serviceTokens,
+		policy:        NewAuthPolicy(config),
 	}
 }
 
 // Handle is the middleware function that validates JWT tokens
 func (m *AuthMiddleware) Handle() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Skip authentication for health check and swagger endpoints
+		// Skip authentication for the public endpoints, such as health checks and swagger
 		if m.isPublicEndpoint(c.Request.URL.Path) {
 			c.Next()
 			return
@@ -37,52 +60,99 @@ func (m *AuthMiddleware) Handle() gin.HandlerFunc {
 		// Extract token from Authorization header
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			m.respondWithError(c, http.StatusUnauthorized, "Authorization header is required")
+			AbortWithProblem(c, apperrors.Unauthorized(apperrors.CodeUnauthorized, "authorization header is required"))
 			return
 		}
 
 		// Check for Bearer token format
 		tokenParts := strings.Split(authHeader, " ")
 		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" || strings.TrimSpace(tokenParts[1]) == "" {
-			m.respondWithError(c, http.StatusUnauthorized, "Invalid authorization header format")
+			AbortWithProblem(c, apperrors.Unauthorized(apperrors.CodeUnauthorized, "invalid authorization header format"))
 			return
 		}
 
 		tokenString := strings.TrimSpace(tokenParts[1])
 
-		// Validate the token using the auth provider
-		claims, err := m.authProvider.ValidateToken(c.Request.Context(), tokenString)
+		// Validate the token using the auth provider, or the API itself for service account tokens
+		validator := TokenValidator(m.authProvider)
+		if m.serviceTokens != nil && strings.HasPrefix(tokenString, models.ServiceAccountTokenPrefix) {
+			validator = m.serviceTokens
+		}
+		claims, err := validator.ValidateToken(c.Request.Context(), tokenString)
 		if err != nil {
-			m.respondWithError(c, http.StatusUnauthorized, "Invalid token: "+err.Error())
+			AbortWithProblem(c, apperrors.Wrap(apperrors.ErrUnauthorized, apperrors.CodeUnauthorized, err, "invalid token"))
 			return
 		}
 
-		// Store user information in the context for downstream handlers
-		c.Set("user_id", claims.UserID)
+		// Store user information in the context for downstream handlers, and tag the request's log lines with the caller
+		c.Set(UserIDContextKey, claims.UserID)
 		c.Set("username", claims.Username)
-		c.Set("email", claims.Email)
+		c.Set(EmailContextKey, claims.Email)
+		c.Set(AccessTokenContextKey, tokenString)
+		c.Request = c.Request.WithContext(logging.WithAttrs(c.Request.Context(), slog.String(logging.UserIDKey, claims.UserID)))
 
 		c.Next()
 	}
 }
 
-// isPublicEndpoint checks if the given path is a public endpoint
+// isPublicEndpoint checks if the given path is served without authentication by the middleware's policy
 func (m *AuthMiddleware) isPublicEndpoint(path string) bool {
-	for _, publicPath := range PublicEndpoints {
-		if strings.HasPrefix(path, publicPath) {
-			return true
-		}
+	return m.policy.IsPublic(path)
+}
+
+// IsPublicEndpoint checks if the given path is one of PublicEndpoints or below one. Paths only match whole segments,
+// so /auth/mfa/recover doesn't make /auth/mfa/recovery-codes public.
+func IsPublicEndpoint(path string) bool {
+	return AuthPolicy{PublicPaths: PublicEndpoints}.IsPublic(path)
+}
+
+// AuthPolicy decides which paths are served without authentication. A path follows the longest public or protected
+// path it is or is below, so a route group can require authentication below a public path, or be public below a
+// protected one. Paths below neither, or below a public and a protected path of the same length, require it.
+type AuthPolicy struct {
+	PublicPaths    []string
+	ProtectedPaths []string
+}
+
+// NewAuthPolicy creates the policy serving PublicEndpoints and the configured public paths without authentication,
+// except below the configured protected paths
+func NewAuthPolicy(config config.AuthConfig) AuthPolicy {
+	return AuthPolicy{
+		PublicPaths:    append(slices.Clone(PublicEndpoints), config.PublicPaths...),
+		ProtectedPaths: slices.Clone(config.ProtectedPaths),
 	}
+}
 
-	return false
+// IsPublic reports whether the given path is served without authentication
+func (p AuthPolicy) IsPublic(path string) bool {
+	public := longestCoveringPath(p.PublicPaths, path)
+	return public >= 0 && public > longestCoveringPath(p.ProtectedPaths, path)
 }
 
-// respondWithError sends an error response
-func (m *AuthMiddleware) respondWithError(c *gin.Context, statusCode int, message string) {
-	errorResponse := models.ErrorResponse{
-		Code:    statusCode,
-		Message: message,
+// longestCoveringPath returns the length of the longest of paths that path is or is below, or -1 when there's none.
+// Paths only cover whole segments, and the empty path covers every path.
+func longestCoveringPath(paths []string, path string) int {
+	longest := -1
+	for _, prefix := range paths {
+		if (path == prefix || strings.HasPrefix(path, prefix+"/")) && len(prefix) > longest {
+			longest = len(prefix)
+		}
 	}
-	c.JSON(statusCode, errorResponse)
-	c.Abort()
+
+	return longest
+}
+
+// GetUserID returns the authenticated caller's user ID, or an empty string for public endpoints
+func GetUserID(c *gin.Context) string {
+	return c.GetString(UserIDContextKey)
+}
+
+// GetUserEmail returns the authenticated caller's email address, or an empty string if unknown
+func GetUserEmail(c *gin.Context) string {
+	return c.GetString(EmailContextKey)
+}
+
+// GetAccessToken returns the access token the caller authenticated with, or an empty string for public endpoints
+func GetAccessToken(c *gin.Context) string {
+	return c.GetString(AccessTokenContextKey)
 }
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -9,8 +10,10 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
 	"github.com/kazemisoroush/code-refactoring-tool/pkg/auth"
 	"github.com/kazemisoroush/code-refactoring-tool/pkg/auth/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -19,7 +22,7 @@ func TestNewAuthMiddleware(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockProvider := mocks.NewMockAuthProvider(ctrl)
-	middleware := NewAuthMiddleware(mockProvider)
+	middleware := NewAuthMiddleware(mockProvider, nil, config.AuthConfig{})
 
 	assert.NotNil(t, middleware)
 	authMiddleware, ok := middleware.(*AuthMiddleware)
@@ -32,7 +35,7 @@ func TestAuthMiddleware_Handle_PublicEndpoints(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockProvider := mocks.NewMockAuthProvider(ctrl)
-	middleware := NewAuthMiddleware(mockProvider)
+	middleware := NewAuthMiddleware(mockProvider, nil, config.AuthConfig{})
 
 	gin.SetMode(gin.TestMode)
 
@@ -71,7 +74,7 @@ func TestAuthMiddleware_Handle_MissingAuthorizationHeader(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockProvider := mocks.NewMockAuthProvider(ctrl)
-	middleware := NewAuthMiddleware(mockProvider)
+	middleware := NewAuthMiddleware(mockProvider, nil, config.AuthConfig{})
 
 	gin.SetMode(gin.TestMode)
 	w := httptest.NewRecorder()
@@ -93,7 +96,7 @@ func TestAuthMiddleware_Handle_InvalidAuthorizationFormat(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockProvider := mocks.NewMockAuthProvider(ctrl)
-	middleware := NewAuthMiddleware(mockProvider)
+	middleware := NewAuthMiddleware(mockProvider, nil, config.AuthConfig{})
 
 	gin.SetMode(gin.TestMode)
 
@@ -131,7 +134,7 @@ func TestAuthMiddleware_Handle_ValidToken(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockProvider := mocks.NewMockAuthProvider(ctrl)
-	middleware := NewAuthMiddleware(mockProvider)
+	middleware := NewAuthMiddleware(mockProvider, nil, config.AuthConfig{})
 
 	gin.SetMode(gin.TestMode)
 	w := httptest.NewRecorder()
@@ -177,7 +180,7 @@ func TestAuthMiddleware_Handle_InvalidToken(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockProvider := mocks.NewMockAuthProvider(ctrl)
-	middleware := NewAuthMiddleware(mockProvider)
+	middleware := NewAuthMiddleware(mockProvider, nil, config.AuthConfig{})
 
 	gin.SetMode(gin.TestMode)
 	w := httptest.NewRecorder()
@@ -198,12 +201,64 @@ func TestAuthMiddleware_Handle_InvalidToken(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 }
 
+// tokenValidatorFunc adapts a function to TokenValidator
+type tokenValidatorFunc func(ctx context.Context, token string) (*auth.TokenClaims, error)
+
+func (f tokenValidatorFunc) ValidateToken(ctx context.Context, token string) (*auth.TokenClaims, error) {
+	return f(ctx, token)
+}
+
+func TestAuthMiddleware_Handle_ServiceAccountToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// The auth provider never sees service account tokens
+	mockProvider := mocks.NewMockAuthProvider(ctrl)
+	serviceTokens := tokenValidatorFunc(func(_ context.Context, token string) (*auth.TokenClaims, error) {
+		if token != models.ServiceAccountTokenPrefix+"secret" {
+			return nil, errors.New("unknown token")
+		}
+		return &auth.TokenClaims{UserID: models.ServiceAccountAuthIDPrefix + "sa-1"}, nil
+	})
+	middleware := NewAuthMiddleware(mockProvider, serviceTokens, config.AuthConfig{})
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		token    string
+		expected int
+	}{
+		{"valid token", models.ServiceAccountTokenPrefix + "secret", http.StatusOK},
+		{"unknown token", models.ServiceAccountTokenPrefix + "other", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			_, router := gin.CreateTestContext(w)
+
+			router.Use(middleware.Handle())
+			router.GET("/api/projects", func(c *gin.Context) {
+				assert.Equal(t, models.ServiceAccountAuthIDPrefix+"sa-1", GetUserID(c))
+				c.JSON(http.StatusOK, gin.H{"message": "success"})
+			})
+
+			req := httptest.NewRequest("GET", "/api/projects", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+}
+
 func TestAuthMiddleware_IsPublicEndpoint(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockProvider := mocks.NewMockAuthProvider(ctrl)
-	middleware := NewAuthMiddleware(mockProvider).(*AuthMiddleware)
+	middleware := NewAuthMiddleware(mockProvider, nil, config.AuthConfig{}).(*AuthMiddleware)
 
 	tests := []struct {
 		path     string
@@ -220,6 +275,12 @@ func TestAuthMiddleware_IsPublicEndpoint(t *testing.T) {
 		{"/api/projects", false},
 		{"/api/agents", false},
 		{"/v1/health", false}, // doesn't start with /health
+		{"/healthz", false},   // only whole segments match
+		{"/auth/signin", true},
+		{"/auth/device/token", true},
+		{"/auth/mfa/recover", true},
+		{"/auth/mfa/recovery-codes", false},
+		{"/auth/me", false},
 	}
 
 	for _, tt := range tests {
@@ -229,3 +290,84 @@ func TestAuthMiddleware_IsPublicEndpoint(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthPolicy_IsPublic(t *testing.T) {
+	defaults := NewAuthPolicy(config.AuthConfig{})
+	configured := NewAuthPolicy(config.AuthConfig{
+		PublicPaths:    []string{"/status", "/api/v1/templates"},
+		ProtectedPaths: []string{"/swagger", "/api/v1/templates/private", "/auth/device/token"},
+	})
+	everything := NewAuthPolicy(config.AuthConfig{PublicPaths: []string{""}, ProtectedPaths: []string{"/admin"}})
+
+	tests := []struct {
+		name     string
+		policy   AuthPolicy
+		path     string
+		expected bool
+	}{
+		{"default health", defaults, "/health", true},
+		{"default swagger", defaults, "/swagger/index.html", true},
+		{"default sign in", defaults, "/auth/signin", true},
+		{"default profile", defaults, "/auth/me", false},
+		{"default api", defaults, "/api/v1/projects", false},
+		{"default root", defaults, "/", false},
+		{"configured keeps defaults", configured, "/health", true},
+		{"configured public path", configured, "/status", true},
+		{"configured public group", configured, "/api/v1/templates/go", true},
+		{"configured public group matches whole segments", configured, "/api/v1/templates-old", false},
+		{"configured protected default", configured, "/swagger/index.html", false},
+		{"configured protected group below public group", configured, "/api/v1/templates/private/go", false},
+		{"configured protected endpoint below public group", configured, "/auth/device/token", false},
+		{"configured public sibling of protected endpoint", configured, "/auth/device/start", true},
+		{"configured other api", configured, "/api/v1/projects", false},
+		{"everything public", everything, "/api/v1/projects", true},
+		{"everything public root", everything, "/", true},
+		{"everything public except protected group", everything, "/admin/workspaces", false},
+		{"same path public and protected", AuthPolicy{PublicPaths: []string{"/status"}, ProtectedPaths: []string{"/status"}}, "/status", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.policy.IsPublic(tt.path))
+		})
+	}
+}
+
+func TestAuthMiddleware_Handle_ConfiguredPaths(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProvider := mocks.NewMockAuthProvider(ctrl)
+	middleware := NewAuthMiddleware(mockProvider, nil, config.AuthConfig{
+		PublicPaths:    []string{"/status"},
+		ProtectedPaths: []string{"/swagger"},
+	})
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		path     string
+		expected int
+	}{
+		{"/status", http.StatusOK},
+		{"/health", http.StatusOK},
+		{"/swagger/index.html", http.StatusUnauthorized},
+		{"/api/v1/projects", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			_, router := gin.CreateTestContext(w)
+
+			router.Use(middleware.Handle())
+			router.GET(tt.path, func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+// Package middleware provides HTTP middleware components for the API
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// BodyLimitMiddleware limits the size of request bodies to the limit of their route. A request announcing a larger
+// body fails with 413 Request Entity Too Large before it's read, and reading past the limit of a body without a
+// length fails with an error that BodyError turns into the same response.
+type BodyLimitMiddleware struct {
+	config config.HTTPConfig
+}
+
+// NewBodyLimitMiddleware creates a new request body size middleware
+func NewBodyLimitMiddleware(config config.HTTPConfig) Middleware {
+	return &BodyLimitMiddleware{
+		config: config,
+	}
+}
+
+// Handle limits the body of the request to the limit of the matched route
+func (m *BodyLimitMiddleware) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := m.config.MaxBodySizeOf(c.Request.Method, c.FullPath())
+		if limit <= 0 || c.Request.Body == nil {
+			c.Next()
+			return
+		}
+		if c.Request.ContentLength > limit {
+			AbortWithProblem(c, apperrors.PayloadTooLarge(limit))
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		c.Next()
+	}
+}
+
+// BodyError returns the error of a request whose body couldn't be read or decoded: ErrPayloadTooLarge when the body
+// went past its limit, and ErrValidation with message otherwise
+func BodyError(err error, message string) error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return apperrors.PayloadTooLarge(tooLarge.Limit)
+	}
+	return apperrors.Wrap(apperrors.ErrValidation, apperrors.CodeInvalidRequest, err, "%s", message)
+}
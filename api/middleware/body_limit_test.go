@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// bodyLimitTestRequest is a request with a JSON body of its own
+type bodyLimitTestRequest struct {
+	Diff string `json:"diff" validate:"required"`
+}
+
+func TestBodyLimitMiddleware_LimitsRouteBodies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(NewErrorHandlerMiddleware().Handle())
+	router.Use(NewBodyLimitMiddleware(config.HTTPConfig{
+		MaxBodySize:       32,
+		RouteMaxBodySizes: config.RouteBodySizes{"POST /uploads": 1024},
+	}).Handle())
+
+	read := func(c *gin.Context) {
+		if _, err := io.ReadAll(c.Request.Body); err != nil {
+			_ = c.Error(BodyError(err, "invalid request body"))
+			return
+		}
+		c.Status(http.StatusOK)
+	}
+	router.POST("/tasks", NewJSONValidationMiddleware[bodyLimitTestRequest]().Handle(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	router.POST("/uploads", read)
+
+	postJSON := func(body string, contentLength int64) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/tasks", io.MultiReader(strings.NewReader(body)))
+		req.Header.Set("Content-Type", "application/json")
+		req.ContentLength = contentLength
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	largeBody := `{"diff":"` + strings.Repeat("+", 64) + `"}`
+
+	// A body within the limit of its route is read
+	w := postJSON(`{"diff":"+a"}`, -1)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// A body announcing a larger length is rejected before it's read
+	w = postJSON(largeBody, int64(len(largeBody)))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Contains(t, w.Body.String(), apperrors.CodePayloadTooLarge)
+
+	// A body without a length is cut off at the limit
+	w = postJSON(largeBody, -1)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+	// A route with its own limit accepts larger bodies, up to that limit
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/uploads", strings.NewReader(strings.Repeat("+", 512))))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req := httptest.NewRequest(http.MethodPost, "/uploads", io.MultiReader(strings.NewReader(strings.Repeat("+", 2048))))
+	req.ContentLength = -1
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
+
+func TestBodyLimitMiddleware_Disabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(NewBodyLimitMiddleware(config.HTTPConfig{}).Handle())
+	router.POST("/tasks", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, "%d", len(body))
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(strings.Repeat("+", 4096))))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "4096", w.Body.String())
+}
@@ -84,8 +84,8 @@ func TestCodebaseValidation(t *testing.T) {
 		err = json.Unmarshal(w.Body.Bytes(), &response)
 		require.NoError(t, err)
 
-		assert.Contains(t, response["message"].(string), "Validation failed")
-		assert.Contains(t, response["details"].(string), "Provider must be one of: github, gitlab, bitbucket, custom")
+		assert.Equal(t, "validation_failed", response["code"])
+		assert.Contains(t, response["detail"].(string), "Provider must be one of: github, gitlab, bitbucket, azure_devops, custom")
 	})
 
 	t.Run("CreateCodebaseRequest_InvalidURL", func(t *testing.T) {
@@ -118,8 +118,8 @@ func TestCodebaseValidation(t *testing.T) {
 		err = json.Unmarshal(w.Body.Bytes(), &response)
 		require.NoError(t, err)
 
-		assert.Contains(t, response["message"].(string), "Validation failed")
-		assert.Contains(t, response["details"].(string), "Url is invalid")
+		assert.Equal(t, "validation_failed", response["code"])
+		assert.Contains(t, response["detail"].(string), "Url is invalid")
 	})
 
 	t.Run("GetCodebaseRequest_ValidUUID", func(t *testing.T) {
@@ -160,8 +160,8 @@ func TestCodebaseValidation(t *testing.T) {
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		require.NoError(t, err)
 
-		assert.Contains(t, response["message"].(string), "Validation failed")
-		assert.Contains(t, response["details"].(string), "CodebaseId is invalid")
+		assert.Equal(t, "validation_failed", response["code"])
+		assert.Contains(t, response["detail"].(string), "CodebaseId is invalid")
 	})
 
 	t.Run("ListCodebasesRequest_ValidQuery", func(t *testing.T) {
@@ -202,7 +202,7 @@ func TestCodebaseValidation(t *testing.T) {
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		require.NoError(t, err)
 
-		assert.Contains(t, response["message"].(string), "Validation failed")
-		assert.Contains(t, response["details"].(string), "MaxResults must be at most 100")
+		assert.Equal(t, "validation_failed", response["code"])
+		assert.Contains(t, response["detail"].(string), "MaxResults must be at most 100")
 	})
 }
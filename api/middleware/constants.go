@@ -1,10 +1,23 @@
 // Package middleware provides HTTP middleware components for the API
 package middleware
 
-// PublicEndpoints defines the list of API endpoints that don't require authentication
+// PublicEndpoints defines the list of API endpoints that don't require authentication, along with the paths below them
 var PublicEndpoints = []string{
 	"/health",
 	"/swagger",
 	"/docs",
 	"/api-docs",
+	"/openapi.json",
+	"/auth/signup",
+	"/auth/signin",
+	"/auth/refresh",
+	"/auth/signout",
+	"/auth/confirm",
+	"/auth/forgot-password",
+	"/auth/reset-password",
+	"/auth/mfa/challenge",
+	"/auth/mfa/recover",
+	"/auth/device/start",
+	"/auth/device/token",
+	"/webhooks/github",
 }
@@ -0,0 +1,34 @@
+// Package middleware provides HTTP middleware components for the API
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/startup"
+)
+
+// DegradedMiddleware disables the routes of the dependencies that failed their startup check when the service runs
+// degraded. Requests to them fail with 503 Service Unavailable naming the dependency, while the other routes are
+// served as usual.
+type DegradedMiddleware struct {
+	report startup.Report
+}
+
+// NewDegradedMiddleware creates a new middleware disabling the routes of the failed dependencies of report
+func NewDegradedMiddleware(report startup.Report) Middleware {
+	return &DegradedMiddleware{
+		report: report,
+	}
+}
+
+// Handle rejects the request when the matched route depends on an unavailable dependency
+func (m *DegradedMiddleware) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if failure, ok := m.report.Unavailable(c.FullPath()); ok {
+			AbortWithProblem(c, apperrors.ServiceUnavailable(apperrors.CodeServiceUnavailable,
+				"%s is unavailable since the service started without it", failure.Name))
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/startup"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDegradedMiddleware_DisablesRoutesOfFailedDependencies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(NewDegradedMiddleware(startup.Report{Failures: []startup.Failure{
+		{Check: startup.Check{Name: "cognito", Routes: []string{"/auth"}}, Err: errors.New("user pool not found")},
+	}}).Handle())
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.POST("/auth/signin", ok)
+	router.GET("/api/v1/projects", ok)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/auth/signin", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	var problem models.ProblemDetails
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, apperrors.CodeServiceUnavailable, problem.Code)
+	assert.Contains(t, problem.Detail, "cognito")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/projects", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,104 @@
+// Package middleware provides HTTP middleware components for the API
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// ErrorHandlerMiddleware renders errors attached to the gin context as RFC 7807 problem details
+type ErrorHandlerMiddleware struct{}
+
+// NewErrorHandlerMiddleware creates a new error handling middleware
+func NewErrorHandlerMiddleware() Middleware {
+	return &ErrorHandlerMiddleware{}
+}
+
+// Handle runs the rest of the chain and, if a handler recorded an error with
+// ctx.Error without writing a response, writes the matching problem response
+func (m *ErrorHandlerMiddleware) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		WriteProblem(c, c.Errors.Last().Err)
+	}
+}
+
+// AbortWithProblem writes the problem response for err and stops the handler chain
+func AbortWithProblem(c *gin.Context, err error) {
+	WriteProblem(c, err)
+	c.Abort()
+}
+
+// WriteProblem writes err as an application/problem+json response
+func WriteProblem(c *gin.Context, err error) {
+	problem := NewProblem(err, c.Request.URL.Path)
+	if problem.Status == http.StatusInternalServerError {
+		slog.ErrorContext(c.Request.Context(), "request failed", "path", c.Request.URL.Path, "error", err)
+	}
+
+	body, marshalErr := json.Marshal(problem)
+	if marshalErr != nil {
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	c.Data(problem.Status, models.ProblemContentType, body)
+}
+
+// NewProblem maps err to its problem details representation
+func NewProblem(err error, instance string) models.ProblemDetails {
+	status := StatusForError(err)
+	code := apperrors.CodeOf(err)
+
+	return models.ProblemDetails{
+		Type:     models.ProblemTypeBaseURI + code,
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   err.Error(),
+		Instance: instance,
+		Code:     code,
+	}
+}
+
+// StatusForError returns the HTTP status code for the kind of err
+func StatusForError(err error) int {
+	switch {
+	case errors.Is(err, apperrors.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, apperrors.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, apperrors.ErrValidation):
+		return http.StatusBadRequest
+	case errors.Is(err, apperrors.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, apperrors.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, apperrors.ErrGone):
+		return http.StatusGone
+	case errors.Is(err, apperrors.ErrPreconditionFailed):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, apperrors.ErrPreconditionRequired):
+		return http.StatusPreconditionRequired
+	case errors.Is(err, apperrors.ErrBadGateway):
+		return http.StatusBadGateway
+	case errors.Is(err, apperrors.ErrPayloadTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, apperrors.ErrServiceUnavailable):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
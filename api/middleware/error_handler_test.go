@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorHandlerMiddleware_RendersProblemDetails(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:           "not_found",
+			err:            fmt.Errorf("failed to get project: %w", apperrors.NotFound(apperrors.CodeProjectNotFound, "project not found")),
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   apperrors.CodeProjectNotFound,
+		},
+		{
+			name:           "conflict",
+			err:            apperrors.Conflict(apperrors.CodeAgentExists, "agent with ID a-1 already exists"),
+			expectedStatus: http.StatusConflict,
+			expectedCode:   apperrors.CodeAgentExists,
+		},
+		{
+			name:           "validation",
+			err:            apperrors.Validation(apperrors.CodeInvalidProvider, "invalid provider: svn"),
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   apperrors.CodeInvalidProvider,
+		},
+		{
+			name:           "unauthorized",
+			err:            apperrors.Unauthorized(apperrors.CodeInvalidToken, "token expired"),
+			expectedStatus: http.StatusUnauthorized,
+			expectedCode:   apperrors.CodeInvalidToken,
+		},
+		{
+			name:           "forbidden",
+			err:            apperrors.Forbidden(apperrors.CodeForbidden, "admin role required"),
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   apperrors.CodeForbidden,
+		},
+		{
+			name:           "gone",
+			err:            apperrors.New(apperrors.ErrGone, apperrors.CodeAPIVersionSunset, "API v1 was retired"),
+			expectedStatus: http.StatusGone,
+			expectedCode:   apperrors.CodeAPIVersionSunset,
+		},
+		{
+			name:           "precondition_failed",
+			err:            apperrors.PreconditionFailed(apperrors.CodeVersionMismatch, "project proj-1 was modified"),
+			expectedStatus: http.StatusPreconditionFailed,
+			expectedCode:   apperrors.CodeVersionMismatch,
+		},
+		{
+			name:           "precondition_required",
+			err:            apperrors.New(apperrors.ErrPreconditionRequired, apperrors.CodePreconditionRequired, "If-Match header is required"),
+			expectedStatus: http.StatusPreconditionRequired,
+			expectedCode:   apperrors.CodePreconditionRequired,
+		},
+		{
+			name:           "bad_gateway",
+			err:            apperrors.BadGateway(apperrors.CodeBadGateway, errors.New("502 from api.github.com"), "failed to list branches"),
+			expectedStatus: http.StatusBadGateway,
+			expectedCode:   apperrors.CodeBadGateway,
+		},
+		{
+			name:           "payload_too_large",
+			err:            apperrors.PayloadTooLarge(1024),
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedCode:   apperrors.CodePayloadTooLarge,
+		},
+		{
+			name:           "service_unavailable",
+			err:            apperrors.ServiceUnavailable(apperrors.CodeServiceUnavailable, "cognito is unavailable"),
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedCode:   apperrors.CodeServiceUnavailable,
+		},
+		{
+			name:           "untyped",
+			err:            errors.New("connection refused"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedCode:   apperrors.CodeInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.Use(NewErrorHandlerMiddleware().Handle())
+			router.GET("/resource", func(c *gin.Context) {
+				_ = c.Error(tt.err)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/resource", nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, models.ProblemContentType, w.Header().Get("Content-Type"))
+
+			var problem models.ProblemDetails
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+			assert.Equal(t, tt.expectedStatus, problem.Status)
+			assert.Equal(t, tt.expectedCode, problem.Code)
+			assert.Equal(t, models.ProblemTypeBaseURI+tt.expectedCode, problem.Type)
+			assert.Equal(t, http.StatusText(tt.expectedStatus), problem.Title)
+			assert.Equal(t, tt.err.Error(), problem.Detail)
+			assert.Equal(t, "/resource", problem.Instance)
+		})
+	}
+}
+
+func TestErrorHandlerMiddleware_LeavesWrittenResponses(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(NewErrorHandlerMiddleware().Handle())
+	router.GET("/resource", func(c *gin.Context) {
+		_ = c.Error(errors.New("logged only"))
+		c.JSON(http.StatusAccepted, gin.H{"status": "accepted"})
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/resource", nil))
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.JSONEq(t, `{"status":"accepted"}`, w.Body.String())
+}
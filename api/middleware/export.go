@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"mime"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// AcceptsNDJSON reports whether the request asks for a list to be exported as newline-delimited JSON
+func AcceptsNDJSON(c *gin.Context) bool {
+	for _, accepted := range strings.Split(c.GetHeader("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err == nil && mediaType == models.NDJSONContentType {
+			return true
+		}
+	}
+	return false
+}
+
+// AcceptsEventStream reports whether the request asks for a model's output to be streamed as server-sent events
+func AcceptsEventStream(c *gin.Context) bool {
+	for _, accepted := range strings.Split(c.GetHeader("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err == nil && mediaType == models.EventStreamContentType {
+			return true
+		}
+	}
+	return false
+}
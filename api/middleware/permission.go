@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// PermissionEvaluator evaluates whether the user with an auth provider ID holds a permission, within a project when
+// projectID is set
+type PermissionEvaluator interface {
+	Authorize(ctx context.Context, authID, projectID string, permission models.Permission) error
+}
+
+// PermissionMiddleware restricts routes to the callers holding a permission. Routes with a project_id parameter are
+// evaluated within the project, so the roles of project members apply. It runs after the authentication middleware,
+// which identifies the caller.
+type PermissionMiddleware struct {
+	evaluator  PermissionEvaluator
+	permission models.Permission
+}
+
+// NewPermissionMiddleware creates a new middleware admitting the callers holding permission
+func NewPermissionMiddleware(evaluator PermissionEvaluator, permission models.Permission) Middleware {
+	return &PermissionMiddleware{
+		evaluator:  evaluator,
+		permission: permission,
+	}
+}
+
+// Handle rejects the callers without the permission
+func (m *PermissionMiddleware) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authID := GetUserID(c)
+		if authID == "" {
+			AbortWithProblem(c, apperrors.Unauthorized(apperrors.CodeUnauthorized, "authentication is required"))
+			return
+		}
+
+		if err := m.evaluator.Authorize(c.Request.Context(), authID, c.Param("project_id"), m.permission); err != nil {
+			AbortWithProblem(c, err)
+			return
+		}
+
+		c.Next()
+	}
+}
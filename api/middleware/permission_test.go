@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// stubPermissionEvaluator grants the permissions keyed by auth provider ID and project ID, an empty project ID
+// keying the permissions held outside projects
+type stubPermissionEvaluator map[string]map[string][]models.Permission
+
+func (s stubPermissionEvaluator) Authorize(_ context.Context, authID, projectID string, permission models.Permission) error {
+	for _, granted := range s[authID][projectID] {
+		if granted == permission {
+			return nil
+		}
+	}
+	return apperrors.Forbidden(apperrors.CodePermissionDenied, "permission %s denied", permission)
+}
+
+func TestPermissionMiddleware_Handle(t *testing.T) {
+	evaluator := stubPermissionEvaluator{
+		"auth-reviewer": {
+			"":       {models.PermissionProjectRead},
+			"proj-1": {models.PermissionProjectRead, models.PermissionTaskRead},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		authID         string
+		path           string
+		expectedStatus int
+	}{
+		{name: "admits_permission_within_project", authID: "auth-reviewer", path: "/projects/proj-1/tasks", expectedStatus: http.StatusOK},
+		{name: "rejects_permission_of_other_project", authID: "auth-reviewer", path: "/projects/proj-2/tasks", expectedStatus: http.StatusForbidden},
+		{name: "rejects_unauthenticated", authID: "", path: "/projects/proj-1/tasks", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				if tt.authID != "" {
+					c.Set(UserIDContextKey, tt.authID)
+				}
+			})
+			router.GET("/projects/:project_id/tasks", NewPermissionMiddleware(evaluator, models.PermissionTaskRead).Handle(), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
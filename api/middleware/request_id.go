@@ -0,0 +1,56 @@
+// Package middleware provides HTTP middleware components for the API
+package middleware
+
+import (
+	"log/slog"
+	"regexp"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/logging"
+)
+
+// RequestIDHeader is the header carrying the ID that correlates the log lines of a request
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDContextKey is the gin context key under which the request ID is stored
+const RequestIDContextKey = "request_id"
+
+// validRequestID matches the caller-supplied request IDs that are accepted, so logs can't be forged with arbitrary text
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestIDMiddleware tags every request with an ID, taken from the X-Request-ID header or generated, and logs the
+// request once it completes. Lines logged with the request context carry the ID.
+type RequestIDMiddleware struct{}
+
+// NewRequestIDMiddleware creates a new request ID middleware
+func NewRequestIDMiddleware() Middleware {
+	return &RequestIDMiddleware{}
+}
+
+// Handle stores the request ID in the request context and echoes it in the response
+func (m *RequestIDMiddleware) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = uuid.New().String()
+		}
+
+		c.Set(RequestIDContextKey, requestID)
+		c.Header(RequestIDHeader, requestID)
+		ctx := logging.WithAttrs(c.Request.Context(), slog.String(logging.RequestIDKey, requestID))
+		c.Request = c.Request.WithContext(ctx)
+
+		start := time.Now()
+		c.Next()
+
+		// Log with the final request context, which carries the caller once authenticated
+		slog.InfoContext(c.Request.Context(), "Request completed",
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"status", c.Writer.Status(),
+			"duration_ms", time.Since(start).Milliseconds(),
+		)
+	}
+}
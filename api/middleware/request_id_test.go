@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestIDMiddleware_TagsRequestLogLines(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      string
+		expectGiven bool
+	}{
+		{name: "accepts_caller_id", header: "req-123", expectGiven: true},
+		{name: "generates_missing_id", header: ""},
+		{name: "replaces_unsafe_id", header: "bad id\nforged=true"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			previous := slog.Default()
+			slog.SetDefault(slog.New(logging.NewContextHandler(slog.NewJSONHandler(&logs, nil))))
+			defer slog.SetDefault(previous)
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.Use(NewRequestIDMiddleware().Handle())
+			router.GET("/projects", func(c *gin.Context) {
+				slog.InfoContext(c.Request.Context(), "listing projects")
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/projects", nil)
+			if tt.header != "" {
+				req.Header.Set(RequestIDHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			requestID := w.Header().Get(RequestIDHeader)
+			require.NotEmpty(t, requestID)
+			if tt.expectGiven {
+				assert.Equal(t, tt.header, requestID)
+			} else {
+				assert.NotEqual(t, tt.header, requestID)
+			}
+
+			// Both the handler's line and the completed request line carry the ID
+			lines := bytes.Split(bytes.TrimSpace(logs.Bytes()), []byte("\n"))
+			require.Len(t, lines, 2)
+			for _, line := range lines {
+				var entry map[string]any
+				require.NoError(t, json.Unmarshal(line, &entry))
+				assert.Equal(t, requestID, entry[logging.RequestIDKey])
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+	"github.com/kelseyhightower/envconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeoutMiddleware_AppliesRouteDeadline(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(NewErrorHandlerMiddleware().Handle())
+	router.Use(NewTimeoutMiddleware(config.HTTPConfig{
+		RequestTimeout: 10 * time.Millisecond,
+		ExportTimeout:  time.Hour,
+		ExportRoutes:   []string{"GET /agents"},
+		RouteTimeouts:  config.RouteTimeouts{"POST /agents/:agent_id/rebuild": time.Hour},
+	}).Handle())
+
+	wait := func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			_ = c.Error(c.Request.Context().Err())
+		case <-time.After(50 * time.Millisecond):
+			deadline, _ := c.Request.Context().Deadline()
+			c.JSON(http.StatusOK, gin.H{"deadline_in": time.Until(deadline).String()})
+		}
+	}
+	router.GET("/agents", wait)
+	router.GET("/projects", wait)
+	router.POST("/agents/:agent_id/rebuild", wait)
+
+	// A route without its own deadline times out
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/projects", nil))
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	assert.Contains(t, w.Body.String(), apperrors.CodeTimeout)
+
+	// The route with a longer deadline completes
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/agents/agent-1/rebuild", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// An export of the list gets the export deadline
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/agents", nil)
+	req.Header.Set("Accept", "application/x-ndjson")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestTimeoutMiddleware_ExportDeadlineOnlyOnExportRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(NewErrorHandlerMiddleware().Handle())
+	router.Use(NewTimeoutMiddleware(config.HTTPConfig{
+		RequestTimeout: 10 * time.Millisecond,
+		ExportTimeout:  time.Hour,
+		ExportRoutes:   []string{"GET /projects/:project_id/tasks"},
+	}).Handle())
+
+	wait := func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			_ = c.Error(c.Request.Context().Err())
+		case <-time.After(50 * time.Millisecond):
+			c.Status(http.StatusOK)
+		}
+	}
+	router.GET("/projects/:project_id/tasks", wait)
+	router.POST("/projects/:project_id/tasks/execute", wait)
+
+	// Asking a route that doesn't export for NDJSON keeps its own deadline
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/projects/proj-1/tasks/execute", nil)
+	req.Header.Set("Accept", "application/x-ndjson")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+
+	// The export route gets the export deadline
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/projects/proj-1/tasks", nil)
+	req.Header.Set("Accept", "application/x-ndjson")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestTimeoutMiddleware_ComplianceExportGetsExportDeadline(t *testing.T) {
+	var cfg config.HTTPConfig
+	require.NoError(t, envconfig.Process("HTTP", &cfg))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(NewTimeoutMiddleware(cfg).Handle())
+
+	var deadlineIn time.Duration
+	router.GET("/api/v1/admin/exports/users", func(c *gin.Context) {
+		deadline, _ := c.Request.Context().Deadline()
+		deadlineIn = time.Until(deadline)
+		c.Status(http.StatusOK)
+	})
+
+	// Compliance exports pick their encoding with ?format= rather than the Accept header
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/admin/exports/users?format=csv", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Greater(t, deadlineIn, cfg.RequestTimeout)
+	assert.LessOrEqual(t, deadlineIn, cfg.ExportTimeout)
+}
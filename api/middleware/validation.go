@@ -3,15 +3,20 @@ package middleware
 import (
 	"fmt"
 	"reflect"
+	"regexp"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
 	"github.com/kazemisoroush/code-refactoring-tool/api/models"
 )
 
 var (
 	validate *validator.Validate
+
+	// roleNamePattern matches the names of roles, such as security-reviewer
+	roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9-]{0,49}$`)
 )
 
 func init() {
@@ -22,6 +27,7 @@ func init() {
 	_ = validate.RegisterValidation("config_id", validateConfigID)
 	_ = validate.RegisterValidation("provider", validateProvider)
 	_ = validate.RegisterValidation("tag_filter", validateTagFilter)
+	_ = validate.RegisterValidation("role_name", validateRoleName)
 
 	// Register custom tag name function for better error messages
 	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
@@ -59,7 +65,7 @@ type ValidationMiddleware struct {
 
 // ValidationConfig defines the configuration for validation middleware
 type ValidationConfig struct {
-	Type          string // "json", "uri", "query", "combined"
+	Type          string // "json", "uri", "query", "uri_query", "combined"
 	ValidatorFunc gin.HandlerFunc
 }
 
@@ -85,23 +91,11 @@ func NewJSONValidationMiddleware[T any]() Middleware {
 		ValidatorFunc: func(c *gin.Context) {
 			var request T
 			if err := c.ShouldBind(&request); err != nil {
-				errorResponse := models.ErrorResponse{
-					Code:    400,
-					Message: "Invalid request body",
-					Details: err.Error(),
-				}
-				c.JSON(400, errorResponse)
-				c.Abort()
+				AbortWithProblem(c, BodyError(err, "invalid request body"))
 				return
 			}
 			if err := validate.Struct(request); err != nil {
-				errorResponse := models.ErrorResponse{
-					Code:    400,
-					Message: "Validation failed",
-					Details: formatValidationError(err),
-				}
-				c.JSON(400, errorResponse)
-				c.Abort()
+				AbortWithProblem(c, apperrors.Validation(apperrors.CodeValidation, "%s", formatValidationError(err)))
 				return
 			}
 			c.Set("validatedRequest", request)
@@ -117,23 +111,11 @@ func NewURIValidationMiddleware[T any]() Middleware {
 		ValidatorFunc: func(c *gin.Context) {
 			var request T
 			if err := c.ShouldBindUri(&request); err != nil {
-				errorResponse := models.ErrorResponse{
-					Code:    400,
-					Message: "Invalid URI parameters",
-					Details: err.Error(),
-				}
-				c.JSON(400, errorResponse)
-				c.Abort()
+				AbortWithProblem(c, apperrors.Wrap(apperrors.ErrValidation, apperrors.CodeInvalidRequest, err, "invalid URI parameters"))
 				return
 			}
 			if err := validate.Struct(request); err != nil {
-				errorResponse := models.ErrorResponse{
-					Code:    400,
-					Message: "Validation failed",
-					Details: formatValidationError(err),
-				}
-				c.JSON(400, errorResponse)
-				c.Abort()
+				AbortWithProblem(c, apperrors.Validation(apperrors.CodeValidation, "%s", formatValidationError(err)))
 				return
 			}
 			c.Set("validatedRequest", request)
@@ -149,23 +131,36 @@ func NewQueryValidationMiddleware[T any]() Middleware {
 		ValidatorFunc: func(c *gin.Context) {
 			var request T
 			if err := c.ShouldBindQuery(&request); err != nil {
-				errorResponse := models.ErrorResponse{
-					Code:    400,
-					Message: "Invalid query parameters",
-					Details: err.Error(),
-				}
-				c.JSON(400, errorResponse)
-				c.Abort()
+				AbortWithProblem(c, apperrors.Wrap(apperrors.ErrValidation, apperrors.CodeInvalidRequest, err, "invalid query parameters"))
 				return
 			}
 			if err := validate.Struct(request); err != nil {
-				errorResponse := models.ErrorResponse{
-					Code:    400,
-					Message: "Validation failed",
-					Details: formatValidationError(err),
-				}
-				c.JSON(400, errorResponse)
-				c.Abort()
+				AbortWithProblem(c, apperrors.Validation(apperrors.CodeValidation, "%s", formatValidationError(err)))
+				return
+			}
+			c.Set("validatedRequest", request)
+			c.Next()
+		},
+	})
+}
+
+// NewURIQueryValidationMiddleware creates a validation middleware for type T that binds both
+// path parameters and query parameters, for GET routes on a specific resource
+func NewURIQueryValidationMiddleware[T any]() Middleware {
+	return NewValidationMiddleware(ValidationConfig{
+		Type: "uri_query",
+		ValidatorFunc: func(c *gin.Context) {
+			var request T
+			if err := c.ShouldBindUri(&request); err != nil {
+				AbortWithProblem(c, apperrors.Wrap(apperrors.ErrValidation, apperrors.CodeInvalidRequest, err, "invalid URI parameters"))
+				return
+			}
+			if err := c.ShouldBindQuery(&request); err != nil {
+				AbortWithProblem(c, apperrors.Wrap(apperrors.ErrValidation, apperrors.CodeInvalidRequest, err, "invalid query parameters"))
+				return
+			}
+			if err := validate.Struct(request); err != nil {
+				AbortWithProblem(c, apperrors.Validation(apperrors.CodeValidation, "%s", formatValidationError(err)))
 				return
 			}
 			c.Set("validatedRequest", request)
@@ -181,44 +176,20 @@ func NewCombinedValidationMiddleware[T any]() Middleware {
 		ValidatorFunc: func(c *gin.Context) {
 			var request T
 			if err := c.ShouldBindUri(&request); err != nil {
-				errorResponse := models.ErrorResponse{
-					Code:    400,
-					Message: "Invalid URI parameters",
-					Details: err.Error(),
-				}
-				c.JSON(400, errorResponse)
-				c.Abort()
+				AbortWithProblem(c, apperrors.Wrap(apperrors.ErrValidation, apperrors.CodeInvalidRequest, err, "invalid URI parameters"))
 				return
 			}
 			var jsonRequest T
 			if err := c.ShouldBindJSON(&jsonRequest); err != nil {
-				errorResponse := models.ErrorResponse{
-					Code:    400,
-					Message: "Invalid request body",
-					Details: err.Error(),
-				}
-				c.JSON(400, errorResponse)
-				c.Abort()
+				AbortWithProblem(c, BodyError(err, "invalid request body"))
 				return
 			}
 			if err := mergeStructs(&request, jsonRequest); err != nil {
-				errorResponse := models.ErrorResponse{
-					Code:    400,
-					Message: "Failed to merge request data",
-					Details: err.Error(),
-				}
-				c.JSON(400, errorResponse)
-				c.Abort()
+				AbortWithProblem(c, apperrors.Wrap(apperrors.ErrValidation, apperrors.CodeInvalidRequest, err, "failed to merge request data"))
 				return
 			}
 			if err := validate.Struct(request); err != nil {
-				errorResponse := models.ErrorResponse{
-					Code:    400,
-					Message: "Validation failed",
-					Details: formatValidationError(err),
-				}
-				c.JSON(400, errorResponse)
-				c.Abort()
+				AbortWithProblem(c, apperrors.Validation(apperrors.CodeValidation, "%s", formatValidationError(err)))
 				return
 			}
 			c.Set("validatedRequest", request)
@@ -259,6 +230,11 @@ func validateTagFilter(fl validator.FieldLevel) bool {
 	return len(parts) == 2 && parts[0] != "" && parts[1] != ""
 }
 
+// validateRoleName validates that a role name is lowercase letters, digits and dashes starting with a letter
+func validateRoleName(fl validator.FieldLevel) bool {
+	return roleNamePattern.MatchString(fl.Field().String())
+}
+
 // formatValidationError formats validation errors into human-readable messages
 func formatValidationError(err error) string {
 	var messages []string
@@ -310,9 +286,11 @@ func formatFieldError(fe validator.FieldError) string {
 	case "project_id":
 		return fmt.Sprintf("%s must start with 'proj-' followed by alphanumeric characters", field)
 	case "provider":
-		return fmt.Sprintf("%s must be one of: github, gitlab, bitbucket, custom", field)
+		return fmt.Sprintf("%s must be one of: github, gitlab, bitbucket, azure_devops, custom", field)
 	case "tag_filter":
 		return fmt.Sprintf("%s must be in format key:value", field)
+	case "role_name":
+		return fmt.Sprintf("%s must be at most 50 lowercase letters, digits and dashes starting with a letter", field)
 	case "dive":
 		return fmt.Sprintf("%s contains invalid values", field)
 	case "keys":
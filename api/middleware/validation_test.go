@@ -37,6 +37,11 @@ type TestListRequest struct {
 	TagFilter  map[string]string `form:"tag_filter,omitempty" validate:"omitempty,dive,keys,min=1,max=50,endkeys,min=1,max=100" example:"env:prod"`
 }
 
+type TestGetRequest struct {
+	ID         string `uri:"id" validate:"required,project_id" example:"proj-12345"`
+	WindowDays int    `form:"window_days" validate:"omitempty,min=1,max=90" example:"7"`
+}
+
 func TestValidateJSON_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -184,12 +189,12 @@ func TestValidateJSON_ValidationErrors(t *testing.T) {
 			err = json.Unmarshal(w.Body.Bytes(), &response)
 			require.NoError(t, err)
 
-			// Check if the error message is in message or details field
+			// Check if the error message is in title or detail field
 			errorMessage := ""
-			if msg, ok := response["message"].(string); ok {
+			if msg, ok := response["title"].(string); ok {
 				errorMessage += msg
 			}
-			if details, ok := response["details"].(string); ok {
+			if details, ok := response["detail"].(string); ok {
 				errorMessage += " " + details
 			}
 
@@ -270,12 +275,12 @@ func TestValidateURI_InvalidProjectID(t *testing.T) {
 				err := json.Unmarshal(w.Body.Bytes(), &response)
 				require.NoError(t, err)
 
-				// Check if the error message is in message or details field
+				// Check if the error message is in title or detail field
 				errorMessage := ""
-				if msg, ok := response["message"].(string); ok {
+				if msg, ok := response["title"].(string); ok {
 					errorMessage += msg
 				}
-				if details, ok := response["details"].(string); ok {
+				if details, ok := response["detail"].(string); ok {
 					errorMessage += details
 				}
 
@@ -403,12 +408,12 @@ func TestValidateQuery_ValidationErrors(t *testing.T) {
 			err := json.Unmarshal(w.Body.Bytes(), &response)
 			require.NoError(t, err)
 
-			// Check if the error message is in message or details field
+			// Check if the error message is in title or detail field
 			errorMessage := ""
-			if msg, ok := response["message"].(string); ok {
+			if msg, ok := response["title"].(string); ok {
 				errorMessage += msg
 			}
-			if details, ok := response["details"].(string); ok {
+			if details, ok := response["detail"].(string); ok {
 				errorMessage += " " + details
 			}
 
@@ -454,6 +459,43 @@ func TestValidateCombined_Success(t *testing.T) {
 	assert.Equal(t, "Updated Name", *req.Name)
 }
 
+func TestValidateURIQuery(t *testing.T) {
+	tests := []struct {
+		name               string
+		target             string
+		expectedAborted    bool
+		expectedWindowDays int
+	}{
+		{name: "with_query", target: "/test/proj-12345?window_days=30", expectedWindowDays: 30},
+		{name: "without_query", target: "/test/proj-12345", expectedWindowDays: 0},
+		{name: "out_of_range", target: "/test/proj-12345?window_days=365", expectedAborted: true},
+		{name: "not_a_number", target: "/test/proj-12345?window_days=week", expectedAborted: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("GET", tt.target, nil)
+			c.Params = []gin.Param{{Key: "id", Value: "proj-12345"}}
+
+			NewURIQueryValidationMiddleware[TestGetRequest]().Handle()(c)
+
+			assert.Equal(t, tt.expectedAborted, c.IsAborted())
+			if tt.expectedAborted {
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+				return
+			}
+
+			req, exists := GetValidatedRequest[TestGetRequest](c)
+			require.True(t, exists)
+			assert.Equal(t, "proj-12345", req.ID)
+			assert.Equal(t, tt.expectedWindowDays, req.WindowDays)
+		})
+	}
+}
+
 // Helper functions
 func intPtr(i int) *int {
 	return &i
@@ -0,0 +1,83 @@
+// Package middleware provides HTTP middleware components for the API
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+const (
+	// APIVersionContextKey is the gin context key holding the API version that matched the request
+	APIVersionContextKey = "api_version"
+	// APIVersionHeader reports the API version that served the request
+	APIVersionHeader = "API-Version"
+	// DeprecationHeader announces when the API version was or will be deprecated (RFC 9745)
+	DeprecationHeader = "Deprecation"
+	// SunsetHeader announces when the API version will stop responding (RFC 8594)
+	SunsetHeader = "Sunset"
+)
+
+// VersionMiddleware tags responses with their API version and applies the version's deprecation policy
+type VersionMiddleware struct {
+	version string
+	mu      sync.RWMutex
+	policy  config.APIVersionPolicy
+	now     func() time.Time
+}
+
+// NewVersionMiddleware creates a new version middleware for the given version and policy
+func NewVersionMiddleware(version string, policy config.APIVersionPolicy) *VersionMiddleware {
+	return &VersionMiddleware{
+		version: version,
+		policy:  policy,
+		now:     time.Now,
+	}
+}
+
+// SetPolicy replaces the deprecation policy applied to later requests, such as after the configuration is reloaded
+func (m *VersionMiddleware) SetPolicy(policy config.APIVersionPolicy) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.policy = policy
+}
+
+// Handle sets the version headers and rejects requests once the version's sunset date has passed
+func (m *VersionMiddleware) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		m.mu.RLock()
+		policy := m.policy
+		m.mu.RUnlock()
+
+		c.Set(APIVersionContextKey, m.version)
+		c.Header(APIVersionHeader, m.version)
+
+		if !policy.DeprecatedAt.IsZero() {
+			c.Header(DeprecationHeader, fmt.Sprintf("@%d", policy.DeprecatedAt.Unix()))
+		}
+		if !policy.SunsetAt.IsZero() {
+			c.Header(SunsetHeader, policy.SunsetAt.UTC().Format(http.TimeFormat))
+		}
+		if policy.Link != "" {
+			c.Header("Link", fmt.Sprintf("<%s>; rel=\"deprecation\"; type=\"text/html\"", policy.Link))
+		}
+
+		if !policy.SunsetAt.IsZero() && !m.now().Before(policy.SunsetAt) {
+			AbortWithProblem(c, apperrors.New(apperrors.ErrGone, apperrors.CodeAPIVersionSunset,
+				"API %s was retired on %s", m.version, policy.SunsetAt.UTC().Format(time.DateOnly)))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// GetAPIVersion returns the API version that matched the request, or an empty string for unversioned routes
+func GetAPIVersion(c *gin.Context) string {
+	return c.GetString(APIVersionContextKey)
+}
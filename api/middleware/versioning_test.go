@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveVersioned(t *testing.T, m Middleware) *httptest.ResponseRecorder {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/resource", m.Handle(), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"version": GetAPIVersion(c)})
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/resource", nil))
+	return w
+}
+
+func TestVersionMiddleware_ActiveVersion(t *testing.T) {
+	w := serveVersioned(t, NewVersionMiddleware("v1", config.APIVersionPolicy{}))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "v1", w.Header().Get(APIVersionHeader))
+	assert.Empty(t, w.Header().Get(DeprecationHeader))
+	assert.Empty(t, w.Header().Get(SunsetHeader))
+	assert.JSONEq(t, `{"version":"v1"}`, w.Body.String())
+}
+
+func TestVersionMiddleware_DeprecatedVersion(t *testing.T) {
+	deprecatedAt := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	sunsetAt := time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC)
+	m := &VersionMiddleware{
+		version: "v1",
+		policy: config.APIVersionPolicy{
+			DeprecatedAt: deprecatedAt,
+			SunsetAt:     sunsetAt,
+			Link:         "https://example.com/migrate-to-v2",
+		},
+		now: func() time.Time { return deprecatedAt.Add(24 * time.Hour) },
+	}
+
+	w := serveVersioned(t, m)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "@1735689600", w.Header().Get(DeprecationHeader))
+	assert.Equal(t, "Tue, 01 Jul 2025 00:00:00 GMT", w.Header().Get(SunsetHeader))
+	assert.Equal(t, `<https://example.com/migrate-to-v2>; rel="deprecation"; type="text/html"`, w.Header().Get("Link"))
+}
+
+func TestVersionMiddleware_AfterSunset(t *testing.T) {
+	sunsetAt := time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC)
+	m := &VersionMiddleware{
+		version: "v1",
+		policy:  config.APIVersionPolicy{SunsetAt: sunsetAt},
+		now:     func() time.Time { return sunsetAt },
+	}
+
+	w := serveVersioned(t, m)
+
+	assert.Equal(t, http.StatusGone, w.Code)
+	assert.Equal(t, "v1", w.Header().Get(APIVersionHeader))
+
+	var problem models.ProblemDetails
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, apperrors.CodeAPIVersionSunset, problem.Code)
+	assert.Equal(t, "API v1 was retired on 2025-07-01", problem.Detail)
+}
@@ -42,6 +42,14 @@ type CreateAgentRequest struct {
 	AgentName string `json:"agent_name,omitempty" validate:"omitempty,min=1" example:"my-code-analyzer"`
 	// AI provider to use for the agent
 	AIProvider AIProvider `json:"ai_provider,omitempty" validate:"omitempty,oneof=bedrock local openai" example:"bedrock"`
+	// Optional project owning the agent, whose redaction policy applies to the repository content
+	ProjectID string `json:"project_id,omitempty" validate:"omitempty,project_id" example:"proj-12345-abcde"`
+	// Optional AWS region a Bedrock agent is provisioned in, one of the allowed regions, defaults to the configured region
+	Region string `json:"region,omitempty" validate:"omitempty,max=32" example:"eu-west-1"`
+	// Optional Bedrock embedding model of the agent's knowledge base, defaults to the project's or the default model
+	EmbeddingModel string `json:"embedding_model,omitempty" validate:"omitempty,max=128" example:"amazon.titan-embed-text-v2:0"`
+	// Optional number of dimensions of the knowledge base's vectors, one the embedding model produces, defaults to the model's
+	EmbeddingDimensions int32 `json:"embedding_dimensions,omitempty" validate:"omitempty,min=1" example:"1024"`
 } //@name CreateAgentRequest
 
 // CreateAgentResponse represents the response when creating an agent
@@ -88,6 +96,14 @@ type GetAgentResponse struct {
 	CreatedAt time.Time `json:"created_at" example:"2024-01-15T10:30:00Z"`
 	// Timestamp when the agent was last updated
 	UpdatedAt time.Time `json:"updated_at" example:"2024-01-15T10:30:00Z"`
+	// Project owning the agent, if any
+	ProjectID string `json:"project_id,omitempty" example:"proj-12345-abcde"`
+	// AWS region of a Bedrock agent provisioned outside the configured region
+	Region string `json:"region,omitempty" example:"eu-west-1"`
+	// Bedrock embedding model of the agent's knowledge base, empty for agents embedding with the default model
+	EmbeddingModel string `json:"embedding_model,omitempty" example:"amazon.titan-embed-text-v2:0"`
+	// Number of dimensions of the knowledge base's vectors
+	EmbeddingDimensions int32 `json:"embedding_dimensions,omitempty" example:"1024"`
 } //@name GetAgentResponse
 
 // DeleteAgentRequest represents the request to delete an agent by ID
@@ -102,6 +118,8 @@ type DeleteAgentResponse struct {
 	AgentID string `json:"agent_id" example:"agent-12345"`
 	// Success indicator
 	Success bool `json:"success" example:"true"`
+	// Purge deleting the content the agent uploaded to S3 in the background, absent when it uploaded none
+	PurgeID string `json:"purge_id,omitempty" example:"purge-12345-abcde"`
 } //@name DeleteAgentResponse
 
 // ListAgentsRequest represents the request to list agents
@@ -172,16 +190,6 @@ type UpdateAgentResponse struct {
 	UpdatedAt time.Time `json:"updated_at" example:"2024-01-15T11:45:00Z"`
 } //@name UpdateAgentResponse
 
-// ErrorResponse represents an error response
-type ErrorResponse struct {
-	// Error code
-	Code int `json:"code" example:"400"`
-	// Error message
-	Message string `json:"message" example:"Invalid request parameters"`
-	// Optional error details
-	Details string `json:"details,omitempty" example:"repository_url is required"`
-} //@name ErrorResponse
-
 // SuccessResponse represents a success response
 type SuccessResponse struct {
 	// Success message
@@ -0,0 +1,30 @@
+// Package models provides data structures for resyncing agents when their codebases change
+package models
+
+import "time"
+
+// AgentResyncSettings controls whether a project's agents are resynced when their codebase's default branch changes
+type AgentResyncSettings struct {
+	// Project the settings apply to
+	ProjectID string `json:"project_id" db:"project_id" example:"proj-12345-abcde"`
+	// Whether new commits on a codebase's default branch resync the project's agents built from it
+	Enabled bool `json:"enabled" db:"enabled" example:"true"`
+	// Whether the project uses the default settings because none were configured
+	IsDefault bool `json:"is_default" example:"false"`
+	// Last update timestamp, absent for the default settings
+	UpdatedAt *time.Time `json:"updated_at,omitempty" db:"updated_at" example:"2024-01-15T10:30:00Z"`
+} //@name AgentResyncSettings
+
+// GetAgentResyncSettingsRequest represents the request to get the agent resync settings of a project
+type GetAgentResyncSettingsRequest struct {
+	// Project ID
+	ProjectID string `uri:"project_id" validate:"required,project_id" example:"proj-12345-abcde"`
+} //@name GetAgentResyncSettingsRequest
+
+// UpdateAgentResyncSettingsRequest represents the request to opt a project in or out of agent resyncs
+type UpdateAgentResyncSettingsRequest struct {
+	// Project ID
+	ProjectID string `uri:"project_id" validate:"required,project_id" example:"proj-12345-abcde"`
+	// Whether new commits resync the project's agents
+	Enabled *bool `json:"enabled" validate:"required" example:"false"`
+} //@name UpdateAgentResyncSettingsRequest
@@ -0,0 +1,42 @@
+// Package models provides data structures for debugging the context agents retrieve from their knowledge bases
+package models
+
+// RetrieveAgentChunksRequest represents the request to run only the retrieval step of an agent for a query
+type RetrieveAgentChunksRequest struct {
+	// Agent ID
+	AgentID string `uri:"agent_id" validate:"required" example:"agent-12345"`
+	// Query to retrieve context for, as the agent would be asked
+	Query string `json:"query" validate:"required,max=4000" example:"How are payment retries scheduled?"`
+	// Maximum number of chunks, defaults to 10
+	Limit int `json:"limit,omitempty" validate:"omitempty,min=1,max=100" example:"10"`
+	// Auth provider ID of the caller, set from the authenticated user
+	UserID string `json:"-"`
+} //@name RetrieveAgentChunksRequest
+
+// RetrievedChunk is a piece of an agent's repository retrieved as context for a query
+type RetrievedChunk struct {
+	// Position of the chunk in the ranking, starting at 1
+	Rank int `json:"rank" example:"1"`
+	// Relevance of the chunk to the query, higher being more relevant
+	Score float64 `json:"score" example:"0.82"`
+	// Path of the file the chunk was cut from, relative to the repository
+	SourcePath string `json:"source_path" example:"internal/payments/retry.go"`
+	// First line of the chunk in its file, absent when it couldn't be located
+	StartLine int `json:"start_line,omitempty" example:"42"`
+	// Last line of the chunk in its file, absent when it couldn't be located
+	EndLine int `json:"end_line,omitempty" example:"71"`
+	// Content of the chunk
+	Text string `json:"text" example:"func scheduleRetry(payment *Payment) time.Time {"`
+} //@name RetrievedChunk
+
+// RetrieveAgentChunksResponse lists the chunks an agent retrieves for a query, most relevant first
+type RetrieveAgentChunksResponse struct {
+	// Agent ID
+	AgentID string `json:"agent_id" example:"agent-12345"`
+	// Knowledge base the chunks were retrieved from
+	KnowledgeBaseID string `json:"knowledge_base_id" example:"kb-12345"`
+	// Query the chunks were retrieved for
+	Query string `json:"query" example:"How are payment retries scheduled?"`
+	// Retrieved chunks, most relevant first
+	Chunks []RetrievedChunk `json:"chunks"`
+} //@name RetrieveAgentChunksResponse
@@ -0,0 +1,80 @@
+// Package models provides data structures for the setups that provision agent infrastructure
+package models
+
+import "time"
+
+// AgentSetupOperation is what an agent setup was started for
+type AgentSetupOperation string
+
+const (
+	// AgentSetupOperationCreate means the setup provisions a new agent
+	AgentSetupOperationCreate AgentSetupOperation = "create"
+	// AgentSetupOperationRebuild means the setup re-provisions an existing agent, after an update or rebuild
+	AgentSetupOperationRebuild AgentSetupOperation = "rebuild"
+)
+
+// AgentSetupStep is the progress of one step of an agent setup
+type AgentSetupStep struct {
+	// Step name, such as clone, scan, build_rag or build_agent
+	Name string `json:"name" example:"build_rag"`
+	// Step status: pending, completed, failed or compensated
+	Status string `json:"status" example:"failed"`
+	// Number of times the step was attempted
+	Attempts int `json:"attempts" example:"3"`
+	// Error of the step's last failed attempt
+	Error string `json:"error,omitempty" example:"failed to create knowledge base: throttled"`
+} //@name AgentSetupStep
+
+// AgentSetup is one run of the workflow that provisions an agent's AI infrastructure
+type AgentSetup struct {
+	// Setup ID, reported when a setup fails
+	SetupID string `json:"setup_id" example:"3f1c2a9e-7b5d-4c1e-9f3a-2d6b8e0c4a11"`
+	// What the setup was started for: create or rebuild
+	Operation AgentSetupOperation `json:"operation" example:"create"`
+	// AI provider the setup provisions
+	AIProvider AIProvider `json:"ai_provider" example:"bedrock"`
+	// Project of the agent, absent for agents without a project
+	ProjectID string `json:"project_id,omitempty" example:"proj-12345"`
+	// Agent the setup rebuilds, or built once its agent step completed
+	AgentID string `json:"agent_id,omitempty" example:"agent-12345"`
+	// Setup status: running, completed, failed, rolled_back or rollback_failed
+	Status string `json:"status" example:"failed"`
+	// Error that failed the setup
+	Error string `json:"error,omitempty" example:"failed to create knowledge base: throttled"`
+	// Progress of every step, in the order they run
+	Steps []AgentSetupStep `json:"steps"`
+	// Setup start timestamp
+	CreatedAt time.Time `json:"created_at" example:"2024-01-15T10:30:00Z"`
+	// Last progress timestamp
+	UpdatedAt time.Time `json:"updated_at" example:"2024-01-15T10:34:00Z"`
+} //@name AgentSetup
+
+// ListAgentSetupsRequest represents the request to list agent setups
+type ListAgentSetupsRequest struct {
+	// Only list setups in this status
+	Status string `form:"status" validate:"omitempty,oneof=running completed failed rolled_back rollback_failed" example:"failed"`
+} //@name ListAgentSetupsRequest
+
+// ListAgentSetupsResponse represents the response for listing agent setups
+type ListAgentSetupsResponse struct {
+	// Setups, most recent first
+	Setups []AgentSetup `json:"setups"`
+} //@name ListAgentSetupsResponse
+
+// GetAgentSetupRequest represents the request to get an agent setup
+type GetAgentSetupRequest struct {
+	// Setup ID
+	SetupID string `uri:"setup_id" validate:"required" example:"3f1c2a9e-7b5d-4c1e-9f3a-2d6b8e0c4a11"`
+} //@name GetAgentSetupRequest
+
+// ResumeAgentSetupRequest represents the request to resume a failed agent setup
+type ResumeAgentSetupRequest struct {
+	// Setup ID
+	SetupID string `uri:"setup_id" validate:"required" example:"3f1c2a9e-7b5d-4c1e-9f3a-2d6b8e0c4a11"`
+} //@name ResumeAgentSetupRequest
+
+// TearDownAgentSetupRequest represents the request to tear down what a failed agent setup built
+type TearDownAgentSetupRequest struct {
+	// Setup ID
+	SetupID string `uri:"setup_id" validate:"required" example:"3f1c2a9e-7b5d-4c1e-9f3a-2d6b8e0c4a11"`
+} //@name TearDownAgentSetupRequest
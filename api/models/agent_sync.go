@@ -0,0 +1,54 @@
+// Package models provides data structures for syncing agent knowledge bases with their repositories
+package models
+
+import "time"
+
+// AgentSyncJob is one ingestion of an agent's repository into its knowledge base
+type AgentSyncJob struct {
+	// Ingestion job ID
+	JobID string `json:"job_id" example:"ABCDEF1234"`
+	// Job status: starting, in_progress, complete, failed, stopping or stopped
+	Status string `json:"status" example:"complete"`
+	// Number of documents found in the data source
+	DocumentsScanned int `json:"documents_scanned" example:"420"`
+	// Number of new and modified documents indexed
+	DocumentsIndexed int `json:"documents_indexed" example:"415"`
+	// Number of documents removed from the knowledge base
+	DocumentsDeleted int `json:"documents_deleted" example:"3"`
+	// Number of documents that couldn't be indexed
+	DocumentsFailed int `json:"documents_failed" example:"2"`
+	// Reasons reported for the failed documents or job
+	FailureReasons []string `json:"failure_reasons,omitempty"`
+	// Job start timestamp
+	StartedAt time.Time `json:"started_at" example:"2024-01-15T10:30:00Z"`
+	// Last progress timestamp, the completion time once the job has finished
+	UpdatedAt time.Time `json:"updated_at" example:"2024-01-15T10:34:00Z"`
+} //@name AgentSyncJob
+
+// AgentSyncStatus reports how up to date an agent's knowledge base is
+type AgentSyncStatus struct {
+	// Agent ID
+	AgentID string `json:"agent_id" example:"agent-12345"`
+	// Knowledge base synced with the agent's repository
+	KnowledgeBaseID string `json:"knowledge_base_id" example:"kb-12345"`
+	// Whether a sync is running
+	Syncing bool `json:"syncing" example:"false"`
+	// Completion time of the last successful sync, absent when the knowledge base never synced
+	LastSyncedAt *time.Time `json:"last_synced_at,omitempty" example:"2024-01-15T10:34:00Z"`
+	// Recent syncs, most recent first
+	Jobs []AgentSyncJob `json:"jobs"`
+} //@name AgentSyncStatus
+
+// GetAgentSyncStatusRequest represents the request to get the knowledge base sync status of an agent
+type GetAgentSyncStatusRequest struct {
+	// Agent ID
+	AgentID string `uri:"agent_id" validate:"required" example:"agent-12345"`
+	// Maximum number of recent syncs, defaults to 10
+	Limit int `form:"limit" validate:"omitempty,min=1,max=50" example:"10"`
+} //@name GetAgentSyncStatusRequest
+
+// SyncAgentRequest represents the request to resync the knowledge base of an agent
+type SyncAgentRequest struct {
+	// Agent ID
+	AgentID string `uri:"agent_id" validate:"required" example:"agent-12345"`
+} //@name SyncAgentRequest
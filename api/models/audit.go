@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// AuditAction identifies the kind of change an audit event records
+type AuditAction string
+
+// Audit actions recorded when access to the service changes
+const (
+	// AuditActionRoleCreated records a custom role being defined
+	AuditActionRoleCreated AuditAction = "role.created"
+	// AuditActionRoleUpdated records the permissions of a role being replaced
+	AuditActionRoleUpdated AuditAction = "role.updated"
+	// AuditActionRoleDeleted records a custom role being deleted
+	AuditActionRoleDeleted AuditAction = "role.deleted"
+	// AuditActionMemberAdded records a user being given access to a project, or their project role changing
+	AuditActionMemberAdded AuditAction = "member.added"
+	// AuditActionMemberRemoved records a user being removed from a project
+	AuditActionMemberRemoved AuditAction = "member.removed"
+	// AuditActionOwnershipTransferred records the ownership of a project moving to another user
+	AuditActionOwnershipTransferred AuditAction = "project.ownership_transferred"
+)
+
+// AuditEvent records who changed access to the service, and how
+type AuditEvent struct {
+	EventID string `json:"event_id" db:"event_id" example:"audit-12345"`
+	// Auth provider ID of the caller making the change
+	ActorID string      `json:"actor_id" db:"actor_id" example:"auth-123"`
+	Action  AuditAction `json:"action" db:"action" example:"member.added"`
+	// Kind and identifier of the changed resource, e.g. role and security-reviewer
+	TargetType string `json:"target_type" db:"target_type" example:"user"`
+	TargetID   string `json:"target_id" db:"target_id" example:"usr-123"`
+	// Project the change applies to, if any
+	ProjectID  string            `json:"project_id,omitempty" db:"project_id" example:"proj-1"`
+	Details    map[string]string `json:"details,omitempty" db:"details"`
+	OccurredAt time.Time         `json:"occurred_at" db:"occurred_at"`
+} //@name AuditEvent
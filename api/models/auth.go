@@ -29,13 +29,15 @@ type SignInRequest struct {
 	Password string `json:"password" validate:"required"`
 }
 
-// SignInResponse represents the response to a user authentication request
+// SignInResponse represents the response to a user authentication request. Users with MFA enabled get an MFA
+// challenge instead of tokens, which they answer at /auth/mfa/challenge.
 type SignInResponse struct {
-	AccessToken  string   `json:"access_token"`
-	RefreshToken string   `json:"refresh_token"`
-	TokenType    string   `json:"token_type"`
-	ExpiresIn    int      `json:"expires_in"`
-	User         *APIUser `json:"user"`
+	AccessToken  string        `json:"access_token,omitempty"`
+	RefreshToken string        `json:"refresh_token,omitempty"`
+	TokenType    string        `json:"token_type,omitempty"`
+	ExpiresIn    int           `json:"expires_in,omitempty"`
+	User         *APIUser      `json:"user,omitempty"`
+	MFAChallenge *MFAChallenge `json:"mfa_challenge,omitempty"`
 }
 
 // RefreshTokenRequest represents a token refresh request
@@ -62,7 +64,9 @@ type CreateUserRequest struct {
 	Email     string   `json:"email" validate:"required,email"`
 	FirstName *string  `json:"first_name,omitempty" validate:"omitempty,max=50"`
 	LastName  *string  `json:"last_name,omitempty" validate:"omitempty,max=50"`
-	Role      UserRole `json:"role,omitempty" validate:"omitempty,oneof=owner admin developer viewer"`
+	Role      UserRole `json:"role,omitempty" validate:"omitempty,role_name"`
+	// Auth provider ID of the caller, set from the access token
+	ActorID string `json:"-"`
 }
 
 // CreateUserResponse represents the response to a user creation request
@@ -70,6 +74,16 @@ type CreateUserResponse struct {
 	User *APIUser `json:"user"`
 }
 
+// GetUserRequest represents a request to get a user by ID
+type GetUserRequest struct {
+	UserID string `uri:"id" validate:"required"`
+}
+
+// DeleteUserRequest represents a request to delete a user by ID
+type DeleteUserRequest struct {
+	UserID string `uri:"id" validate:"required"`
+}
+
 // GetUserResponse represents the response to a get user request
 type GetUserResponse struct {
 	User *APIUser `json:"user"`
@@ -77,11 +91,38 @@ type GetUserResponse struct {
 
 // UpdateUserRequest represents a request to update user information
 type UpdateUserRequest struct {
-	UserID    string    `json:"user_id" validate:"required"`
+	UserID    string    `json:"-" uri:"id" validate:"required"`
 	Email     *string   `json:"email,omitempty" validate:"omitempty,email"`
 	FirstName *string   `json:"first_name,omitempty" validate:"omitempty,max=50"`
 	LastName  *string   `json:"last_name,omitempty" validate:"omitempty,max=50"`
-	Role      *UserRole `json:"role,omitempty" validate:"omitempty,oneof=owner admin developer viewer"`
+	Role      *UserRole `json:"role,omitempty" validate:"omitempty,role_name"`
+	// Auth provider ID of the caller, set from the access token
+	ActorID string `json:"-"`
+}
+
+// UpdateProfileRequest represents a request of the current user to update their own profile
+type UpdateProfileRequest struct {
+	// Auth provider ID of the caller, set from the access token
+	AuthID    string  `json:"-"`
+	Email     *string `json:"email,omitempty" validate:"omitempty,email"`
+	FirstName *string `json:"first_name,omitempty" validate:"omitempty,max=50"`
+	LastName  *string `json:"last_name,omitempty" validate:"omitempty,max=50"`
+}
+
+// ChangePasswordRequest represents a request of the current user to change their password
+type ChangePasswordRequest struct {
+	// Access token of the caller, set from the Authorization header
+	AccessToken     string `json:"-"`
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=8"`
+}
+
+// DeleteAccountRequest represents a request of the current user to delete their own account
+type DeleteAccountRequest struct {
+	// Auth provider ID of the caller, set from the access token
+	AuthID string `json:"-"`
+	// Email address of the account, confirming its deletion
+	ConfirmEmail string `json:"confirm_email" validate:"required,email"`
 }
 
 // UpdateUserResponse represents the response to a user update request
@@ -91,10 +132,10 @@ type UpdateUserResponse struct {
 
 // ListUsersRequest represents a request to list users
 type ListUsersRequest struct {
-	Limit  int         `json:"limit,omitempty" validate:"omitempty,min=1,max=100"`
-	Offset int         `json:"offset,omitempty" validate:"omitempty,min=0"`
-	Role   *UserRole   `json:"role,omitempty" validate:"omitempty,oneof=owner admin developer viewer"`
-	Status *UserStatus `json:"status,omitempty" validate:"omitempty,oneof=active inactive pending suspended"`
+	Limit  int         `json:"limit,omitempty" form:"limit" validate:"omitempty,min=1,max=100"`
+	Offset int         `json:"offset,omitempty" form:"offset" validate:"omitempty,min=0"`
+	Role   *UserRole   `json:"role,omitempty" form:"role" validate:"omitempty,role_name"`
+	Status *UserStatus `json:"status,omitempty" form:"status" validate:"omitempty,oneof=active inactive pending suspended"`
 }
 
 // ListUsersResponse represents the response to a list users request
@@ -159,3 +200,77 @@ type ResetPasswordRequest struct {
 	Code        string `json:"code" validate:"required"`
 	NewPassword string `json:"new_password" validate:"required,min=8"`
 }
+
+// MFAMethod represents a second factor a user can sign in with
+type MFAMethod string
+
+const (
+	// MFAMethodTOTP uses time-based one-time passwords from an authenticator app
+	MFAMethodTOTP MFAMethod = "totp"
+	// MFAMethodSMS uses one-time passwords sent by text message
+	MFAMethodSMS MFAMethod = "sms"
+)
+
+// MFAChallenge represents the second factor a user must provide to complete their sign in
+type MFAChallenge struct {
+	Method MFAMethod `json:"method" example:"totp"`
+	// Session to send back with the one-time password
+	Session string `json:"session"`
+}
+
+// MFAChallengeRequest represents the answer to the MFA challenge of a sign in
+type MFAChallengeRequest struct {
+	Username string    `json:"username" validate:"required"`
+	Method   MFAMethod `json:"method" validate:"required,oneof=totp sms" example:"totp"`
+	Session  string    `json:"session" validate:"required"`
+	Code     string    `json:"code" validate:"required,len=6,numeric" example:"123456"`
+}
+
+// MFASetupRequest represents a request of the current user to enroll a second factor
+type MFASetupRequest struct {
+	// Auth provider ID of the caller, set from the access token
+	AuthID string `json:"-"`
+	// Access token of the caller, set from the Authorization header
+	AccessToken string    `json:"-"`
+	Method      MFAMethod `json:"method" validate:"required,oneof=totp sms" example:"totp"`
+	// Phone number in E.164 format one-time passwords are sent to, required for SMS
+	PhoneNumber string `json:"phone_number,omitempty" validate:"required_if=Method sms,omitempty,e164" example:"+14155550100"`
+}
+
+// MFASetupResponse represents the response to an MFA enrollment. A TOTP enrollment returns the secret to add to an
+// authenticator app and is enabled once a one-time password from the app is verified at /auth/mfa/verify. An SMS
+// enrollment is enabled right away and returns the recovery codes.
+type MFASetupResponse struct {
+	Method MFAMethod `json:"method" example:"totp"`
+	// Secret of the authenticator app
+	SecretCode string `json:"secret_code,omitempty"`
+	// otpauth URI of the secret, to render as a QR code
+	OTPAuthURI    string   `json:"otpauth_uri,omitempty"`
+	Enabled       bool     `json:"enabled"`
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
+
+// VerifyMFARequest represents a request of the current user to verify a one-time password of the authenticator app
+// they enrolled, enabling TOTP
+type VerifyMFARequest struct {
+	// Auth provider ID of the caller, set from the access token
+	AuthID string `json:"-"`
+	// Access token of the caller, set from the Authorization header
+	AccessToken string `json:"-"`
+	Code        string `json:"code" validate:"required,len=6,numeric" example:"123456"`
+	DeviceName  string `json:"device_name,omitempty" validate:"omitempty,max=64" example:"Work phone"`
+}
+
+// MFARecoveryCodesResponse represents newly generated MFA recovery codes, each of which can be used once to sign in
+// without the second factor. They are only returned once.
+type MFARecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// MFARecoverRequest represents a request to sign in with a recovery code instead of the second factor, which
+// disables MFA so the user can enroll a new second factor
+type MFARecoverRequest struct {
+	Username     string `json:"username" validate:"required"`
+	Password     string `json:"password" validate:"required"`
+	RecoveryCode string `json:"recovery_code" validate:"required" example:"abcde-fghij"`
+}
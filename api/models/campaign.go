@@ -0,0 +1,126 @@
+// Package models provides data structures for campaigns that apply one instruction across many codebases
+package models
+
+import "time"
+
+const (
+	// MaxCampaignCodebases is the maximum number of codebases a single campaign targets
+	MaxCampaignCodebases = 100
+
+	// DefaultCampaignParallelism is the number of child tasks run at once when max_parallel is not set
+	DefaultCampaignParallelism = 4
+
+	// MaxCampaignParallelism is the maximum number of child tasks a campaign runs at once
+	MaxCampaignParallelism = 20
+)
+
+// CampaignStatus represents the aggregate status of a campaign's child tasks
+type CampaignStatus string
+
+const (
+	// CampaignStatusRunning indicates some child tasks have not finished
+	CampaignStatusRunning CampaignStatus = "running"
+
+	// CampaignStatusCompleted indicates every child task completed
+	CampaignStatusCompleted CampaignStatus = "completed"
+
+	// CampaignStatusPartiallyFailed indicates every child task finished and some of them failed or were cancelled
+	CampaignStatusPartiallyFailed CampaignStatus = "partially_failed"
+
+	// CampaignStatusFailed indicates every child task finished and none of them completed
+	CampaignStatusFailed CampaignStatus = "failed"
+)
+
+// Campaign applies one refactoring instruction across many codebases through a child task per codebase
+type Campaign struct {
+	CampaignID  string    `json:"campaign_id" db:"campaign_id"`
+	Name        string    `json:"name" db:"name"`
+	Instruction string    `json:"instruction" db:"instruction"` // Prompt given to every child task
+	Type        TaskType  `json:"type" db:"type"`
+	AgentID     string    `json:"agent_id" db:"agent_id"`
+	CodebaseIDs []string  `json:"codebase_ids" db:"codebase_ids"`
+	MaxParallel int       `json:"max_parallel" db:"max_parallel"` // Number of child tasks run at once
+	CreatedBy   *string   `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// CreateCampaignRequest represents the request to start a campaign
+type CreateCampaignRequest struct {
+	// Human-readable campaign name
+	Name string `json:"name" validate:"required,min=1,max=200" example:"Bump logging library"`
+	// Instruction applied to every codebase
+	Instruction string `json:"instruction" validate:"required,min=1,max=2000" example:"Bump the logging library to v2 and fix the call sites"`
+	// Type of the child tasks
+	Type TaskType `json:"type" validate:"required,oneof=code_analysis refactoring code_review documentation custom dependency_audit codemod coverage_gap" example:"refactoring"`
+	// Agent running the child tasks
+	AgentID string `json:"agent_id" validate:"required" example:"agent-12345"`
+	// Codebases the instruction is applied to, one child task each
+	CodebaseIDs []string `json:"codebase_ids" validate:"required,min=1,max=100,dive,required" example:"codebase-12345"`
+	// Number of child tasks run at once (default 4)
+	MaxParallel *int `json:"max_parallel,omitempty" validate:"omitempty,min=1,max=20" example:"4"`
+
+	// Authenticated caller, set by the controller
+	CreatedBy string `json:"-"`
+} //@name CreateCampaignRequest
+
+// CreateCampaignResponse represents the response when starting a campaign
+type CreateCampaignResponse struct {
+	// Unique identifier for the campaign
+	CampaignID string `json:"campaign_id" example:"campaign-12345-abcde"`
+	// Child task of each codebase, in request order
+	TaskIDs []string `json:"task_ids"`
+	// Creation timestamp
+	CreatedAt time.Time `json:"created_at" example:"2024-01-15T10:30:00Z"`
+} //@name CreateCampaignResponse
+
+// GetCampaignRequest represents the request to get the progress of a campaign
+type GetCampaignRequest struct {
+	// Unique identifier for the campaign
+	CampaignID string `uri:"id" validate:"required" example:"campaign-12345-abcde"`
+} //@name GetCampaignRequest
+
+// CampaignCodebaseResult is the outcome of a campaign's child task on one codebase
+type CampaignCodebaseResult struct {
+	// Codebase the child task ran against
+	CodebaseID string `json:"codebase_id" example:"codebase-12345"`
+	// Project of the codebase
+	ProjectID string `json:"project_id" example:"proj-12345-abcde"`
+	// Child task
+	TaskID string `json:"task_id" example:"task-12345-abcde"`
+	// Status of the child task
+	Status TaskStatus `json:"status" example:"completed"`
+	// Pull request opened by the child task
+	PullRequestURL *string `json:"pull_request_url,omitempty" example:"https://github.com/acme/payments/pull/42"`
+	// Error of a failed child task
+	ErrorMessage *string `json:"error_message,omitempty" example:"agent timed out"`
+} //@name CampaignCodebaseResult
+
+// GetCampaignResponse represents the progress and per-codebase results of a campaign
+type GetCampaignResponse struct {
+	// Unique identifier for the campaign
+	CampaignID string `json:"campaign_id" example:"campaign-12345-abcde"`
+	// Human-readable campaign name
+	Name string `json:"name" example:"Bump logging library"`
+	// Instruction applied to every codebase
+	Instruction string `json:"instruction" example:"Bump the logging library to v2 and fix the call sites"`
+	// Type of the child tasks
+	Type TaskType `json:"type" example:"refactoring"`
+	// Agent running the child tasks
+	AgentID string `json:"agent_id" example:"agent-12345"`
+	// Number of child tasks run at once
+	MaxParallel int `json:"max_parallel" example:"4"`
+	// Aggregate status of the child tasks
+	Status CampaignStatus `json:"status" example:"running"`
+	// Number of child tasks
+	TotalCount int `json:"total_count" example:"12"`
+	// Number of child tasks in each status
+	StatusCounts map[TaskStatus]int `json:"status_counts"`
+	// Percentage of child tasks in a terminal status
+	Progress float64 `json:"progress" example:"41.7"`
+	// Whether every child task finished
+	Done bool `json:"done" example:"false"`
+	// Result of each codebase
+	Results []CampaignCodebaseResult `json:"results"`
+	// Creation timestamp
+	CreatedAt time.Time `json:"created_at" example:"2024-01-15T10:30:00Z"`
+} //@name GetCampaignResponse
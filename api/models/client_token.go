@@ -0,0 +1,19 @@
+// Package models provides data structures for the client tokens making create requests idempotent
+package models
+
+import "time"
+
+// ClientToken records the client token of a create request, so that retrying the request returns the resource it
+// created instead of creating another
+type ClientToken struct {
+	// Kind of resource and caller the token is unique to, such as project:usr-123
+	Scope string `db:"scope"`
+	// Token chosen by the client
+	Token string `db:"client_token"`
+	// SHA-256 hash of the request, so that the token can't be reused for a different request
+	RequestHash string `db:"request_hash"`
+	// Resource created by the request, nil while it is in progress
+	ResourceID *string `db:"resource_id"`
+	// When the token was claimed
+	CreatedAt time.Time `db:"created_at"`
+}
@@ -0,0 +1,92 @@
+// Package models provides data structures for the maintainability metrics of codebases
+package models
+
+import "time"
+
+// CodeMetricsSnapshot holds the complexity, function length and package coupling of a codebase at one commit
+type CodeMetricsSnapshot struct {
+	// Unique identifier for the snapshot
+	SnapshotID string `json:"snapshot_id" db:"snapshot_id" example:"metrics-12345-abcde"`
+	// Measured codebase
+	CodebaseID string `json:"codebase_id" db:"codebase_id" example:"codebase-12345"`
+	// Analysis task that took the snapshot
+	TaskID string `json:"task_id" db:"task_id" example:"task-12345-abcde"`
+	// Measured commit
+	CommitSHA string `json:"commit_sha" db:"commit_sha" example:"9fceb02d0ae598e95dc970b74767f19372d61af8"`
+	// Number of Go files, tests excluded
+	Files int `json:"files" db:"files" example:"42"`
+	// Number of functions and methods
+	Functions int `json:"functions" db:"functions" example:"310"`
+	// Mean cyclomatic complexity of the functions
+	AverageComplexity float64 `json:"average_complexity" db:"average_complexity" example:"3.4"`
+	// Highest cyclomatic complexity of a function
+	MaxComplexity int `json:"max_complexity" db:"max_complexity" example:"27"`
+	// Mean function length, in lines
+	AverageFunctionLength float64 `json:"average_function_length" db:"average_function_length" example:"14.2"`
+	// Longest function, in lines
+	MaxFunctionLength int `json:"max_function_length" db:"max_function_length" example:"180"`
+	// Mean instability of the packages, from 0 (only imported) to 1 (only importing)
+	AverageInstability float64 `json:"average_instability" db:"average_instability" example:"0.48"`
+	// Percentage of functions with a complexity of at most 10 and at most 60 lines
+	MaintainabilityScore float64 `json:"maintainability_score" db:"maintainability_score" example:"91.3"`
+	// Coupling of each package
+	Packages []PackageCouplingMetrics `json:"packages" db:"packages"`
+	// Most complex functions
+	Hotspots []FunctionComplexity `json:"hotspots" db:"hotspots"`
+	// Measurement timestamp
+	MeasuredAt time.Time `json:"measured_at" db:"measured_at" example:"2024-01-15T10:30:00Z"`
+} //@name CodeMetricsSnapshot
+
+// PackageCouplingMetrics represents the imports between a package and the other packages of its module
+type PackageCouplingMetrics struct {
+	// Package directory, relative to the module root
+	Path string `json:"path" example:"internal/billing"`
+	// Number of packages importing this package
+	Afferent int `json:"afferent" example:"5"`
+	// Number of packages imported by this package
+	Efferent int `json:"efferent" example:"2"`
+	// Efferent / (afferent + efferent)
+	Instability float64 `json:"instability" example:"0.29"`
+} //@name PackageCouplingMetrics
+
+// FunctionComplexity represents the complexity and length of a function
+type FunctionComplexity struct {
+	// Function name, prefixed with its receiver type for methods
+	Name string `json:"name" example:"Invoice.Total"`
+	// File declaring the function
+	FilePath string `json:"file_path" example:"internal/billing/invoice.go"`
+	// Line of the declaration
+	Line int `json:"line" example:"42"`
+	// Cyclomatic complexity
+	Complexity int `json:"complexity" example:"27"`
+	// Length in lines
+	Length int `json:"length" example:"180"`
+} //@name FunctionComplexity
+
+// GetCodeMetricsRequest represents the request to get the metrics history of a codebase
+type GetCodeMetricsRequest struct {
+	// Measured codebase
+	CodebaseID string `uri:"id" validate:"required" example:"codebase-12345"`
+	// First day of the history, defaults to every snapshot
+	Since string `form:"since" validate:"omitempty,datetime=2006-01-02" example:"2024-01-01"`
+} //@name GetCodeMetricsRequest
+
+// CodeMetricsTrend compares the latest snapshot of the history with the first one
+type CodeMetricsTrend struct {
+	// Change of the maintainability score, positive when maintainability improved
+	MaintainabilityScore float64 `json:"maintainability_score" example:"4.2"`
+	// Change of the mean cyclomatic complexity, negative when complexity dropped
+	AverageComplexity float64 `json:"average_complexity" example:"-0.3"`
+	// Change of the mean function length
+	AverageFunctionLength float64 `json:"average_function_length" example:"-1.8"`
+	// Change of the mean package instability
+	AverageInstability float64 `json:"average_instability" example:"0.02"`
+} //@name CodeMetricsTrend
+
+// GetCodeMetricsResponse represents the metrics history of a codebase
+type GetCodeMetricsResponse struct {
+	// Snapshots, oldest first
+	Snapshots []CodeMetricsSnapshot `json:"snapshots"`
+	// Change between the first and latest snapshot, absent with fewer than two snapshots
+	Trend *CodeMetricsTrend `json:"trend,omitempty"`
+} //@name GetCodeMetricsResponse
@@ -0,0 +1,47 @@
+// Package models provides data structures for the ownership of codebase files parsed from CODEOWNERS files
+package models
+
+import "time"
+
+// TaskOutputOwnersKey is the task output field holding the owners of the files a task's findings and changes are in
+const TaskOutputOwnersKey = "owners"
+
+// CodeOwnerRule gives the owners of the files matching a pattern
+type CodeOwnerRule struct {
+	// Gitignore-style pattern
+	Pattern string `json:"pattern" example:"/billing/"`
+	// Users, teams and emails owning the matching files, empty for files left without owners
+	Owners []string `json:"owners" example:"@acme/billing"`
+	// GitLab section of the rule
+	Section string `json:"section,omitempty" example:"Backend"`
+} //@name CodeOwnerRule
+
+// CodeOwners is the ownership of a codebase's files, parsed from its CODEOWNERS file by the latest ingestion scan or
+// static analysis
+type CodeOwners struct {
+	// Codebase the ownership is of
+	CodebaseID string `json:"codebase_id" db:"codebase_id" example:"codebase-12345"`
+	// CODEOWNERS file the rules were parsed from, relative to the repository root, absent when the codebase has none
+	FilePath string `json:"file_path,omitempty" db:"file_path" example:".github/CODEOWNERS"`
+	// Commit the file was read at, absent when the checkout wasn't pinned to a commit
+	CommitSHA string `json:"commit_sha,omitempty" db:"commit_sha" example:"9fceb02d0ae598e95dc970b74767f19372d61af8"`
+	// Rules in file order; the last rule matching a file gives its owners
+	Rules []CodeOwnerRule `json:"rules" db:"rules"`
+	// When the file was last read
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at" example:"2024-01-15T10:30:00Z"`
+} //@name CodeOwners
+
+// GetCodeOwnersRequest represents the request to get the ownership of a codebase's files
+type GetCodeOwnersRequest struct {
+	// Codebase ID
+	CodebaseID string `uri:"id" validate:"required" example:"codebase-12345"`
+	// File to resolve the owners of, relative to the repository root
+	Path string `form:"path,omitempty" validate:"omitempty,max=1024" example:"billing/invoice.go"`
+} //@name GetCodeOwnersRequest
+
+// GetCodeOwnersResponse represents the ownership of a codebase's files
+type GetCodeOwnersResponse struct {
+	CodeOwners
+	// Owners of the requested path, absent unless a path was requested
+	PathOwners []string `json:"path_owners,omitempty" example:"@acme/billing"`
+} //@name GetCodeOwnersResponse
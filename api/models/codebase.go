@@ -10,16 +10,19 @@ type Provider string
 
 // Supported code repository providers
 const (
-	ProviderGitHub    Provider = "github"    // GitHub repository provider
-	ProviderGitLab    Provider = "gitlab"    // GitLab repository provider
-	ProviderBitbucket Provider = "bitbucket" // Bitbucket repository provider
-	ProviderCustom    Provider = "custom"    // Custom repository provider
+	ProviderGitHub      Provider = "github"       // GitHub repository provider
+	ProviderGitLab      Provider = "gitlab"       // GitLab repository provider
+	ProviderBitbucket   Provider = "bitbucket"    // Bitbucket repository provider
+	ProviderCustom      Provider = "custom"       // Custom repository provider
+	ProviderAzureDevOps Provider = "azure_devops" // Azure DevOps repository provider
+	ProviderUpload      Provider = "upload"       // Archive uploaded to S3, with no git provider
 )
 
-// IsValid checks if the provider is one of the supported values
+// IsValid checks if the provider is one of the supported git providers. Codebases of uploaded archives are created
+// with their own request rather than from a URL.
 func (p Provider) IsValid() bool {
 	switch p {
-	case ProviderGitHub, ProviderGitLab, ProviderBitbucket, ProviderCustom:
+	case ProviderGitHub, ProviderGitLab, ProviderBitbucket, ProviderAzureDevOps, ProviderCustom:
 		return true
 	default:
 		return false
@@ -31,7 +34,7 @@ func (p Provider) String() string {
 	return string(p)
 }
 
-// Codebase represents a Git-based repository attached to a Project
+// Codebase represents a Git-based repository, or an uploaded archive of one, attached to a Project
 type Codebase struct {
 	CodebaseID string   `json:"codebase_id" db:"codebase_id"`
 	ProjectID  string   `json:"project_id" db:"project_id"`
@@ -49,6 +52,23 @@ type Codebase struct {
 	UpdatedAt  time.Time         `json:"updated_at" db:"updated_at"`
 	Metadata   map[string]string `json:"metadata,omitempty" db:"metadata"`
 	Tags       map[string]string `json:"tags,omitempty" db:"tags"`
+
+	// Why the latest ingestion scan blocked the codebase, nil unless Status is ingestion_blocked
+	IngestionError *string `json:"ingestion_error,omitempty" db:"ingestion_error"`
+
+	// When an uploaded codebase is deleted along with its archive, nil for codebases on a git provider
+	ExpiresAt *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+
+	// How the repository is checked out for tasks, its full history and tree when nil
+	Checkout *CodebaseCheckout `json:"checkout,omitempty" db:"checkout"`
+}
+
+// CodebaseCheckout configures how much of a codebase's repository is checked out for tasks
+type CodebaseCheckout struct {
+	// Commits of history cloned, the full history when 0. Tasks pinned to an older commit clone the full history.
+	Depth int `json:"depth,omitempty" validate:"omitempty,min=1,max=100000"`
+	// Directories checked out, relative to the repository root; the whole tree when empty
+	SparsePaths []string `json:"sparse_paths,omitempty" validate:"omitempty,max=100,dive,min=1,max=1024"`
 }
 
 // CodebaseStatus represents the status of a codebase
@@ -63,6 +83,10 @@ const (
 	CodebaseStatusSyncFailed CodebaseStatus = "sync_failed"
 	// CodebaseStatusInactive indicates the codebase is inactive or archived
 	CodebaseStatusInactive CodebaseStatus = "inactive"
+	// CodebaseStatusIngestionBlocked indicates the latest ingestion scan found committed secrets or incompatible licenses
+	CodebaseStatusIngestionBlocked CodebaseStatus = "ingestion_blocked"
+	// CodebaseStatusAwaitingUpload indicates the archive of an uploaded codebase hasn't been uploaded yet
+	CodebaseStatusAwaitingUpload CodebaseStatus = "awaiting_upload"
 )
 
 // CreateCodebaseRequest represents the request to create a new codebase
@@ -73,6 +97,11 @@ type CreateCodebaseRequest struct {
 	URL       string            `json:"url" validate:"required,url,max=2048"`
 	ConfigID  string            `json:"config_id" validate:"required,config_id"`
 	Tags      map[string]string `json:"tags,omitempty" validate:"omitempty,dive,keys,min=1,max=64,endkeys,min=1,max=255"`
+	// How the repository is checked out for tasks, its full history and tree when omitted
+	Checkout *CodebaseCheckout `json:"checkout,omitempty"`
+	// Optional token unique to the request, retries carrying it return the codebase it created instead of creating another
+	ClientToken string `json:"client_token,omitempty" validate:"omitempty,max=64"`
+	UserID      string `json:"-"` // Authenticated caller, set by the controller
 }
 
 // CreateCodebaseResponse represents the response after creating a codebase
@@ -98,6 +127,12 @@ type GetCodebaseResponse struct {
 	UpdatedAt  string            `json:"updatedAt"`
 	Metadata   map[string]string `json:"metadata,omitempty"`
 	Tags       map[string]string `json:"tags,omitempty"`
+
+	Status         CodebaseStatus `json:"status"`
+	IngestionError *string        `json:"ingestionError,omitempty"` // Why the latest ingestion scan blocked the codebase
+	ExpiresAt      *string        `json:"expiresAt,omitempty"`      // When an uploaded codebase is deleted
+
+	Checkout *CodebaseCheckout `json:"checkout,omitempty"` // How the repository is checked out for tasks
 }
 
 // UpdateCodebaseRequest represents the request to update a codebase
@@ -107,6 +142,9 @@ type UpdateCodebaseRequest struct {
 	ConfigID   *string           `json:"config_id,omitempty" validate:"omitempty,config_id"`
 	Tags       map[string]string `json:"tags,omitempty" validate:"omitempty,dive,keys,min=1,max=64,endkeys,min=1,max=255"`
 	Metadata   map[string]string `json:"metadata,omitempty" validate:"omitempty,dive,keys,min=1,max=64,endkeys,min=1,max=255"`
+	// Replaces how the repository is checked out for tasks; an empty object checks out its full history and tree
+	Checkout *CodebaseCheckout `json:"checkout,omitempty"`
+	UserID   string            `json:"-"` // Authenticated caller, set by the controller
 }
 
 // UpdateCodebaseResponse represents the response after updating a codebase
@@ -150,3 +188,70 @@ type ListCodebasesResponse struct {
 	Codebases []CodebaseSummary `json:"codebases"`
 	NextToken *string           `json:"nextToken,omitempty"`
 }
+
+// DefaultCodebaseCommitsLimit is the number of commits listed when limit is not set
+const DefaultCodebaseCommitsLimit = 30
+
+// ListCodebaseBranchesRequest represents the request to list the branches of a codebase's repository
+type ListCodebaseBranchesRequest struct {
+	CodebaseID string `json:"codebaseId" validate:"required,uuid" uri:"id"`
+}
+
+// CodebaseBranch represents a branch of a codebase's repository
+type CodebaseBranch struct {
+	Name      string `json:"name"`
+	CommitSHA string `json:"commitSha"`
+	Protected bool   `json:"protected"`
+}
+
+// ListCodebaseBranchesResponse represents the response when listing the branches of a codebase
+type ListCodebaseBranchesResponse struct {
+	Branches      []CodebaseBranch `json:"branches"`
+	DefaultBranch string           `json:"defaultBranch,omitempty"`
+}
+
+// ListCodebaseCommitsRequest represents the request to list the commits of a codebase's repository
+type ListCodebaseCommitsRequest struct {
+	CodebaseID string `json:"codebaseId" validate:"required,uuid" uri:"id"`
+	Ref        string `json:"ref,omitempty" validate:"omitempty,max=255" form:"ref"` // Branch, tag or commit SHA; defaults to the default branch
+	Limit      *int   `json:"limit,omitempty" validate:"omitempty,min=1,max=100" form:"limit"`
+}
+
+// CodebaseCommit represents a commit of a codebase's repository
+type CodebaseCommit struct {
+	SHA        string `json:"sha"`
+	Message    string `json:"message"`
+	AuthorName string `json:"authorName"`
+	AuthoredAt string `json:"authoredAt"`
+	URL        string `json:"url,omitempty"`
+}
+
+// ListCodebaseCommitsResponse represents the response when listing the commits of a codebase
+type ListCodebaseCommitsResponse struct {
+	Commits []CodebaseCommit `json:"commits"`
+}
+
+// ListCodebaseFilesRequest represents the request to list a directory of a codebase's repository
+type ListCodebaseFilesRequest struct {
+	CodebaseID string `json:"codebaseId" validate:"required,uuid" uri:"id"`
+	Ref        string `json:"ref,omitempty" validate:"omitempty,max=255" form:"ref"`    // Branch, tag or commit SHA; defaults to the default branch
+	Path       string `json:"path,omitempty" validate:"omitempty,max=1024" form:"path"` // Directory to list; defaults to the repository root
+}
+
+// CodebaseFile represents an entry of a directory of a codebase's repository
+type CodebaseFile struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	Type string `json:"type"` // "file" or "dir"
+}
+
+// ListCodebaseFilesResponse represents the response when listing a directory of a codebase
+type ListCodebaseFilesResponse struct {
+	Files []CodebaseFile `json:"files"`
+}
+
+// CodebaseRevision is the branch and commit of a codebase's repository a task runs against
+type CodebaseRevision struct {
+	Branch    string // Empty when the task targets a commit without naming its branch
+	CommitSHA string // Full SHA of the commit
+}
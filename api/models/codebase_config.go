@@ -19,6 +19,9 @@ type GitProviderConfig struct {
 	// For Bitbucket
 	Bitbucket *BitbucketConfig `json:"bitbucket,omitempty" db:"bitbucket"`
 
+	// For Azure DevOps
+	AzureDevOps *AzureDevOpsConfig `json:"azure_devops,omitempty" db:"azure_devops"`
+
 	// For custom Git providers
 	Custom *CustomGitConfig `json:"custom,omitempty" db:"custom"`
 }
@@ -38,15 +41,21 @@ const (
 
 	// GitAuthTypeBasic Username/password
 	GitAuthTypeBasic GitAuthType = "basic"
+
+	// GitAuthTypeGitHubApp represents a GitHub App installation, whose short-lived tokens are exchanged with its private key
+	GitAuthTypeGitHubApp GitAuthType = "github_app"
 )
 
 // GitHubConfig represents GitHub-specific configuration
 type GitHubConfig struct {
-	Token         string `json:"token,omitempty" db:"token"`                   // PAT or OAuth token
-	Organization  string `json:"organization,omitempty" db:"organization"`     // GitHub org (if applicable)
-	Repository    string `json:"repository" db:"repository"`                   // Repository name
-	Owner         string `json:"owner" db:"owner"`                             // Repository owner
-	DefaultBranch string `json:"default_branch,omitempty" db:"default_branch"` // Default branch to use
+	Token          string `json:"token,omitempty" db:"token"`                     // PAT or OAuth token
+	AppID          int64  `json:"app_id,omitempty" db:"app_id"`                   // GitHub App ID, for github_app authentication
+	InstallationID int64  `json:"installation_id,omitempty" db:"installation_id"` // Installation of the GitHub App on the owner
+	PrivateKey     string `json:"private_key,omitempty" db:"private_key"`         // PEM encoded private key of the GitHub App
+	Organization   string `json:"organization,omitempty" db:"organization"`       // GitHub org (if applicable)
+	Repository     string `json:"repository" db:"repository"`                     // Repository name
+	Owner          string `json:"owner" db:"owner"`                               // Repository owner
+	DefaultBranch  string `json:"default_branch,omitempty" db:"default_branch"`   // Default branch to use
 }
 
 // GitLabConfig represents GitLab-specific configuration
@@ -67,6 +76,16 @@ type BitbucketConfig struct {
 	DefaultBranch string `json:"default_branch,omitempty" db:"default_branch"` // Default branch to use
 }
 
+// AzureDevOpsConfig represents Azure DevOps-specific configuration
+type AzureDevOpsConfig struct {
+	Token         string `json:"token,omitempty" db:"token"`                   // Personal access token
+	BaseURL       string `json:"base_url,omitempty" db:"base_url"`             // Collection URL, for Azure DevOps Server
+	Organization  string `json:"organization" db:"organization"`               // Azure DevOps organization (or collection)
+	Project       string `json:"project" db:"project"`                         // Azure DevOps project
+	Repository    string `json:"repository" db:"repository"`                   // Repository name
+	DefaultBranch string `json:"default_branch,omitempty" db:"default_branch"` // Default branch to use
+}
+
 // CustomGitConfig represents configuration for custom Git providers
 type CustomGitConfig struct {
 	BaseURL       string            `json:"base_url" db:"base_url"`                       // Git provider base URL
@@ -74,6 +93,7 @@ type CustomGitConfig struct {
 	Username      string            `json:"username,omitempty" db:"username"`             // Username for basic auth
 	Password      string            `json:"password,omitempty" db:"password"`             // Password for basic auth
 	SSHKey        string            `json:"ssh_key,omitempty" db:"ssh_key"`               // SSH private key
+	KnownHosts    string            `json:"known_hosts,omitempty" db:"known_hosts"`       // known_hosts entries trusted for SSH remotes
 	Headers       map[string]string `json:"headers,omitempty" db:"headers"`               // Custom headers
 	DefaultBranch string            `json:"default_branch,omitempty" db:"default_branch"` // Default branch to use
 }
@@ -107,6 +127,8 @@ type CreateCodebaseConfigRequest struct {
 	Config GitProviderConfig `json:"config" validate:"required"`
 	// Optional user-defined key-value tags
 	Tags map[string]string `json:"tags,omitempty" validate:"omitempty,max=10,dive,keys,min=1,max=50,endkeys,min=1,max=100" example:"env:prod,team:backend"`
+	// Authenticated caller, set by the controller
+	UserID string `json:"-"`
 } //@name CreateCodebaseConfigRequest
 
 // CreateCodebaseConfigResponse represents the response when creating a codebase configuration
@@ -139,6 +161,8 @@ type GetCodebaseConfigResponse struct {
 	CreatedAt string `json:"created_at" example:"2024-01-15T10:30:00Z"`
 	// Timestamp when the configuration was last updated
 	UpdatedAt string `json:"updated_at" example:"2024-01-15T10:30:00Z"`
+	// Version incremented on every update, also returned as the ETag header
+	Version int64 `json:"version" example:"3"`
 	// Optional user-defined key-value tags
 	Tags map[string]string `json:"tags,omitempty" example:"env:prod,team:backend"`
 	// Provider-specific configuration (sensitive data redacted)
@@ -155,6 +179,8 @@ type GitProviderConfigRedacted struct {
 	GitLab *GitLabConfigRedacted `json:"gitlab,omitempty"`
 	// For Bitbucket (with sensitive fields redacted)
 	Bitbucket *BitbucketConfigRedacted `json:"bitbucket,omitempty"`
+	// For Azure DevOps (with sensitive fields redacted)
+	AzureDevOps *AzureDevOpsConfigRedacted `json:"azure_devops,omitempty"`
 	// For custom Git providers (with sensitive fields redacted)
 	Custom *CustomGitConfigRedacted `json:"custom,omitempty"`
 }
@@ -167,6 +193,10 @@ type GitHubConfigRedacted struct {
 	DefaultBranch string `json:"default_branch,omitempty"`
 	// Token is redacted for security
 	HasToken bool `json:"has_token"`
+	// GitHub App installation, whose private key is redacted for security
+	AppID          int64 `json:"app_id,omitempty"`
+	InstallationID int64 `json:"installation_id,omitempty"`
+	HasPrivateKey  bool  `json:"has_private_key"`
 }
 
 // GitLabConfigRedacted represents GitLab-specific configuration with sensitive data redacted
@@ -189,10 +219,22 @@ type BitbucketConfigRedacted struct {
 	HasAppPassword bool `json:"has_app_password"`
 }
 
+// AzureDevOpsConfigRedacted represents Azure DevOps-specific configuration with sensitive data redacted
+type AzureDevOpsConfigRedacted struct {
+	BaseURL       string `json:"base_url,omitempty"`
+	Organization  string `json:"organization"`
+	Project       string `json:"project"`
+	Repository    string `json:"repository"`
+	DefaultBranch string `json:"default_branch,omitempty"`
+	// Token is redacted for security
+	HasToken bool `json:"has_token"`
+}
+
 // CustomGitConfigRedacted represents configuration for custom Git providers with sensitive data redacted
 type CustomGitConfigRedacted struct {
 	BaseURL       string            `json:"base_url"`
 	Headers       map[string]string `json:"headers,omitempty"`
+	KnownHosts    string            `json:"known_hosts,omitempty"`
 	DefaultBranch string            `json:"default_branch,omitempty"`
 	// Sensitive fields are redacted for security
 	HasToken    bool `json:"has_token"`
@@ -215,6 +257,10 @@ type UpdateCodebaseConfigRequest struct {
 	Config *GitProviderConfig `json:"config,omitempty"`
 	// Optional user-defined key-value tags
 	Tags map[string]string `json:"tags,omitempty" validate:"omitempty,max=10,dive,keys,min=1,max=50,endkeys,min=1,max=100" example:"env:staging,team:frontend"`
+	// Version the client last read, taken from the If-Match header
+	ExpectedVersion int64 `json:"-"`
+	// Authenticated caller, set by the controller
+	UserID string `json:"-"`
 } //@name UpdateCodebaseConfigRequest
 
 // UpdateCodebaseConfigResponse represents the response when updating a codebase configuration
@@ -223,6 +269,8 @@ type UpdateCodebaseConfigResponse struct {
 	ConfigID string `json:"config_id" example:"config-12345-abcde"`
 	// Timestamp when the configuration was last updated
 	UpdatedAt string `json:"updated_at" example:"2024-01-15T11:30:00Z"`
+	// Version of the configuration after the update
+	Version int64 `json:"version" example:"4"`
 } //@name UpdateCodebaseConfigResponse
 
 // DeleteCodebaseConfigRequest represents the request to delete a codebase configuration
@@ -0,0 +1,32 @@
+package models
+
+// CreateCodebaseUploadRequest represents the request to create a codebase from an archive uploaded to S3, for code
+// that isn't hosted on a reachable git remote
+type CreateCodebaseUploadRequest struct {
+	ProjectID string `json:"projectId" validate:"required,project_id" uri:"project_id"`
+	Name      string `json:"name" validate:"required,min=1,max=255"`
+	// Name of the archive, whose extension is one of .zip, .tar.gz, .tgz or .tar
+	Filename string `json:"filename" validate:"required,max=255"`
+	// Size of the archive in bytes, which the upload must match
+	Size   int64             `json:"size" validate:"required,min=1"`
+	Tags   map[string]string `json:"tags,omitempty" validate:"omitempty,dive,keys,min=1,max=64,endkeys,min=1,max=255"`
+	UserID string            `json:"-"` // Authenticated caller, set by the controller
+}
+
+// CreateCodebaseUploadResponse represents the response after creating an uploaded codebase, telling where to upload
+// its archive
+type CreateCodebaseUploadResponse struct {
+	CodebaseID string `json:"codebaseId"`
+	// Presigned URL the archive is uploaded to with an HTTP PUT
+	UploadURL string `json:"uploadUrl"`
+	// Headers the upload must send
+	UploadHeaders   map[string]string `json:"uploadHeaders"`
+	UploadExpiresAt string            `json:"uploadExpiresAt"` // When the upload URL expires
+	ExpiresAt       string            `json:"expiresAt"`       // When the codebase is deleted along with its archive
+	CreatedAt       string            `json:"createdAt"`
+}
+
+// CompleteCodebaseUploadRequest represents the request to check the uploaded archive of a codebase and activate it
+type CompleteCodebaseUploadRequest struct {
+	CodebaseID string `json:"codebaseId" validate:"required,uuid" uri:"id"`
+}
@@ -41,6 +41,10 @@ const (
 	PermissionProjectUpdate Permission = "project:update"
 	// PermissionProjectDelete allows deleting projects
 	PermissionProjectDelete Permission = "project:delete"
+	// PermissionProjectTransfer allows transferring the ownership of projects
+	PermissionProjectTransfer Permission = "project:transfer"
+	// PermissionMemberManage allows adding, changing and removing project members
+	PermissionMemberManage Permission = "member:manage"
 
 	// PermissionTaskCreate allows creating new tasks
 	PermissionTaskCreate Permission = "task:create"
@@ -70,6 +74,25 @@ const (
 	PermissionUserUpdate Permission = "user:update"
 	// PermissionUserDelete allows deleting users
 	PermissionUserDelete Permission = "user:delete"
+
+	// PermissionRoleRead allows reading roles and permissions
+	PermissionRoleRead Permission = "role:read"
+	// PermissionRoleManage allows changing roles
+	PermissionRoleManage Permission = "role:manage"
+
+	// PermissionAuditRead allows exporting the audit events
+	PermissionAuditRead Permission = "audit:read"
+	// PermissionComplianceExport allows exporting the users and project access grants
+	PermissionComplianceExport Permission = "compliance:export"
+	// PermissionTagKeyManage allows registering, changing and unregistering tag keys
+	PermissionTagKeyManage Permission = "tag_key:manage"
+	// PermissionIntegrationManage allows configuring GitHub Apps and Jira sites
+	PermissionIntegrationManage Permission = "integration:manage"
+	// PermissionServiceAccountManage allows managing service accounts and their tokens
+	PermissionServiceAccountManage Permission = "service_account:manage"
+	// PermissionOperationsManage allows operating the service: workspaces, stuck tasks, storage, evaluations and
+	// experiments
+	PermissionOperationsManage Permission = "operations:manage"
 )
 
 // AgentStatus represents the current status of an agent
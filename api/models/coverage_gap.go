@@ -0,0 +1,47 @@
+// Package models provides data structures for the coverage gaps coverage_gap tasks write tests for
+package models
+
+// TaskInputLanguageKey is the input field selecting the language whose coverage command a coverage_gap task runs
+const TaskInputLanguageKey = "language"
+
+// TaskOutputCoverageGapsKey is the output key holding the coverage gaps a coverage_gap task asked tests for
+const TaskOutputCoverageGapsKey = "coverage_gaps"
+
+// DefaultCoverageLanguage is the language of coverage_gap tasks whose input doesn't set one
+const DefaultCoverageLanguage = "go"
+
+// CoverageGap is a function with statements no test runs
+type CoverageGap struct {
+	// Path of the declaring file, relative to the repository root
+	FilePath string `json:"file_path" example:"api/services/task_service_impl.go"`
+	// Name of the function, qualified by its receiver type for methods
+	Function  string `json:"function" example:"TaskServiceImpl.CreateTask"`
+	StartLine int    `json:"start_line" example:"74"`
+	EndLine   int    `json:"end_line" example:"120"`
+	// Statements of the function, or lines for languages whose profiles don't count statements
+	Statements int `json:"statements" example:"24"`
+	// Statements no test runs
+	Uncovered int `json:"uncovered" example:"9"`
+	// Commits changing the file in the churn window
+	Commits int `json:"commits" example:"6"`
+} //@name CoverageGap
+
+// CoverageGapReport is the outcome of the coverage run of a coverage_gap task
+type CoverageGapReport struct {
+	Language string `json:"language" example:"go"`
+	// Commit the coverage was measured at
+	BaseCommit string `json:"base_commit" example:"4f2a9c1e8b7d6a5f4e3d2c1b0a9f8e7d6c5b4a39"`
+	// Base branch of the pull request, the branch the commit was checked out from
+	BaseBranch string `json:"base_branch" example:"main"`
+	// Uncovered functions ranked by uncovered statements weighed by churn, the first of them only
+	Gaps []CoverageGap `json:"gaps"`
+	// Uncovered functions found, including those left out of the gaps
+	UncoveredFunctions int `json:"uncovered_functions" example:"58"`
+} //@name CoverageGapReport
+
+// PullRequest is a pull request a task opened
+type PullRequest struct {
+	URL    string `json:"url" example:"https://github.com/acme/service/pull/42"`
+	Branch string `json:"branch" example:"coverage/task-123"`
+	Base   string `json:"base" example:"main"`
+} //@name PullRequest
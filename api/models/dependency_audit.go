@@ -0,0 +1,90 @@
+// Package models provides data structures for dependency audits and the vulnerabilities they find
+package models
+
+import "time"
+
+// TaskInputCreateUpgradeTaskKey is the input flag asking a dependency_audit task to create a refactoring task
+// upgrading the vulnerable dependencies it finds
+const TaskInputCreateUpgradeTaskKey = "create_upgrade_task"
+
+// TaskOutputUpgradeTaskIDKey is the output key holding the upgrade task created by a dependency_audit task
+const TaskOutputUpgradeTaskIDKey = "upgrade_task_id"
+
+// DependencySeverity is the normalized severity of a vulnerability
+type DependencySeverity string
+
+const (
+	// DependencySeverityCritical is a critical vulnerability
+	DependencySeverityCritical DependencySeverity = "critical"
+
+	// DependencySeverityHigh is a high severity vulnerability
+	DependencySeverityHigh DependencySeverity = "high"
+
+	// DependencySeverityMedium is a medium severity vulnerability
+	DependencySeverityMedium DependencySeverity = "medium"
+
+	// DependencySeverityLow is a low severity vulnerability
+	DependencySeverityLow DependencySeverity = "low"
+
+	// DependencySeverityUnknown is a vulnerability whose advisory doesn't rate it
+	DependencySeverityUnknown DependencySeverity = "unknown"
+)
+
+// DependencyFinding is a published vulnerability affecting a dependency declared by a codebase, as found by the
+// codebase's latest dependency audit
+type DependencyFinding struct {
+	// Unique identifier for the finding
+	FindingID string `json:"finding_id" db:"finding_id" example:"finding-12345-abcde"`
+	// Audited codebase
+	CodebaseID string `json:"codebase_id" db:"codebase_id" example:"codebase-12345"`
+	// dependency_audit task that found the vulnerability
+	TaskID string `json:"task_id" db:"task_id" example:"task-12345-abcde"`
+	// Package ecosystem, as named by OSV
+	Ecosystem string `json:"ecosystem" db:"ecosystem" example:"Go"`
+	// Affected package
+	Package string `json:"package" db:"package" example:"golang.org/x/net"`
+	// Declared version
+	Version string `json:"version" db:"version" example:"0.10.0"`
+	// Manifest declaring the package, relative to the repository root
+	Manifest string `json:"manifest" db:"manifest" example:"go.mod"`
+	// Advisory identifier
+	AdvisoryID string `json:"advisory_id" db:"advisory_id" example:"GO-2023-2102"`
+	// Other identifiers of the advisory, such as CVE IDs
+	Aliases []string `json:"aliases" db:"aliases"`
+	// Advisory summary
+	Summary string `json:"summary" db:"summary" example:"HTTP/2 rapid reset can cause excessive work in net/http"`
+	// Normalized severity
+	Severity DependencySeverity `json:"severity" db:"severity" example:"high"`
+	// Earliest version fixing the vulnerability, absent when no fix is published
+	FixedVersion *string `json:"fixed_version,omitempty" db:"fixed_version" example:"0.17.0"`
+	// Advisory page
+	URL string `json:"url" db:"url" example:"https://osv.dev/vulnerability/GO-2023-2102"`
+	// Audit timestamp
+	DetectedAt time.Time `json:"detected_at" db:"detected_at" example:"2024-01-15T10:30:00Z"`
+} //@name DependencyFinding
+
+// ListDependencyFindingsRequest represents the request to list the vulnerable dependencies of a codebase
+type ListDependencyFindingsRequest struct {
+	// Audited codebase
+	CodebaseID string `uri:"id" validate:"required" example:"codebase-12345"`
+	// Only list findings of this severity
+	Severity *DependencySeverity `form:"severity,omitempty" validate:"omitempty,oneof=critical high medium low unknown" example:"high"`
+} //@name ListDependencyFindingsRequest
+
+// ListDependencyFindingsResponse represents the vulnerable dependencies found by a codebase's latest audit
+type ListDependencyFindingsResponse struct {
+	// Findings, most severe first
+	Findings []DependencyFinding `json:"findings"`
+	// Number of findings of each severity, before the severity filter
+	SeverityCounts map[DependencySeverity]int `json:"severity_counts"`
+} //@name ListDependencyFindingsResponse
+
+// DependencyAuditResult is the outcome of a dependency_audit task
+type DependencyAuditResult struct {
+	// Number of dependencies declared by the codebase's manifests
+	DependencyCount int `json:"dependency_count"`
+	// Vulnerabilities affecting the dependencies
+	Findings []DependencyFinding `json:"findings"`
+	// Refactoring task upgrading the vulnerable dependencies, when one was asked for and a fix is published
+	UpgradeTaskID *string `json:"upgrade_task_id,omitempty"`
+}
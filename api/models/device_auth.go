@@ -0,0 +1,79 @@
+// Package models provides data structures for the device authorization flow
+package models
+
+import "time"
+
+// DeviceAuthorizationStatus represents the status of a device authorization flow sign in
+type DeviceAuthorizationStatus string
+
+const (
+	// DeviceAuthorizationStatusPending indicates the user code was not approved or denied yet
+	DeviceAuthorizationStatusPending DeviceAuthorizationStatus = "pending"
+	// DeviceAuthorizationStatusApproved indicates a signed in user approved the user code
+	DeviceAuthorizationStatusApproved DeviceAuthorizationStatus = "approved"
+	// DeviceAuthorizationStatusDenied indicates a signed in user denied the user code
+	DeviceAuthorizationStatusDenied DeviceAuthorizationStatus = "denied"
+	// DeviceAuthorizationStatusConsumed indicates the device received its tokens
+	DeviceAuthorizationStatusConsumed DeviceAuthorizationStatus = "consumed"
+)
+
+// DeviceAuthorization is a sign in of a device, such as the CLI, approved by a user signed in elsewhere
+type DeviceAuthorization struct {
+	// SHA-256 hash of the device code the device polls with
+	DeviceCodeHash string `json:"-" db:"device_code_hash"`
+	// Code the user enters to approve the sign in
+	UserCode   string                    `json:"user_code" db:"user_code" example:"BCDF-GHJK"`
+	ClientName string                    `json:"client_name,omitempty" db:"client_name" example:"refactor-cli"`
+	Status     DeviceAuthorizationStatus `json:"status" db:"status" example:"pending"`
+	// User who approved the sign in
+	UserID *string `json:"user_id,omitempty" db:"user_id"`
+	// Hex X25519 public key derived from the device code, which the approving user's refresh token is sealed to
+	TokenPublicKey string `json:"-" db:"token_public_key"`
+	// Refresh token of the approving user the device's tokens are issued from, sealed so only the device code opens it,
+	// and cleared once they are
+	SealedRefreshToken *string   `json:"-" db:"sealed_refresh_token"`
+	CreatedAt          time.Time `json:"created_at" db:"created_at"`
+	ExpiresAt          time.Time `json:"expires_at" db:"expires_at"`
+	// When the device last polled for its tokens
+	LastPolledAt *time.Time `json:"-" db:"last_polled_at"`
+} //@name DeviceAuthorization
+
+// StartDeviceAuthRequest represents the request of a device to start signing in
+type StartDeviceAuthRequest struct {
+	// Name of the device or client shown to the user approving the sign in
+	ClientName string `json:"client_name,omitempty" validate:"omitempty,max=100" example:"refactor-cli"`
+} //@name StartDeviceAuthRequest
+
+// StartDeviceAuthResponse represents the codes of a started device sign in. The device shows the user code and the
+// verification URI, then polls /auth/device/token with the device code every interval seconds.
+type StartDeviceAuthResponse struct {
+	DeviceCode string `json:"device_code"`
+	UserCode   string `json:"user_code" example:"BCDF-GHJK"`
+	// Page where a signed in user enters the user code
+	VerificationURI string `json:"verification_uri" example:"https://app.example.com/device"`
+	// Verification page with the user code filled in
+	VerificationURIComplete string `json:"verification_uri_complete" example:"https://app.example.com/device?user_code=BCDF-GHJK"`
+	// Seconds until the codes expire
+	ExpiresIn int `json:"expires_in" example:"600"`
+	// Minimum seconds between polls
+	Interval int `json:"interval" example:"5"`
+} //@name StartDeviceAuthResponse
+
+// DeviceTokenRequest represents a poll of a device for the tokens of its sign in
+type DeviceTokenRequest struct {
+	DeviceCode string `json:"device_code" validate:"required"`
+} //@name DeviceTokenRequest
+
+// ApproveDeviceRequest represents the request of a signed in user to approve a device sign in
+type ApproveDeviceRequest struct {
+	// Auth provider ID of the caller, set from the access token
+	AuthID   string `json:"-"`
+	UserCode string `json:"user_code" validate:"required,max=9" example:"BCDF-GHJK"`
+	// Refresh token of the caller the device's tokens are issued from
+	RefreshToken string `json:"refresh_token" validate:"required"`
+} //@name ApproveDeviceRequest
+
+// DenyDeviceRequest represents the request of a signed in user to deny a device sign in
+type DenyDeviceRequest struct {
+	UserCode string `json:"user_code" validate:"required,max=9" example:"BCDF-GHJK"`
+} //@name DenyDeviceRequest
@@ -0,0 +1,41 @@
+// Package models provides helpers for entity tags used in optimistic concurrency control
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ETagHeader carries the version of the returned resource
+	ETagHeader = "ETag"
+	// IfMatchHeader carries the version the client expects to modify
+	IfMatchHeader = "If-Match"
+)
+
+// ETag formats a resource version as a strong entity tag, e.g. "3"
+func ETag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// ParseETag parses an entity tag produced by ETag back into a resource version.
+// Weak tags are rejected because If-Match requires a strong comparison.
+func ParseETag(tag string) (int64, error) {
+	tag = strings.TrimSpace(tag)
+	if strings.HasPrefix(tag, "W/") {
+		return 0, fmt.Errorf("weak entity tag %s cannot be used for updates", tag)
+	}
+
+	unquoted, err := strconv.Unquote(tag)
+	if err != nil {
+		return 0, fmt.Errorf("malformed entity tag %s", tag)
+	}
+
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("unknown entity tag %s", tag)
+	}
+
+	return version, nil
+}
@@ -0,0 +1,181 @@
+// Package models provides data structures for the evaluation of refactoring runs against golden repositories
+package models
+
+import "time"
+
+// EvalRunStatus represents the progress of an evaluation run
+type EvalRunStatus string
+
+const (
+	// EvalRunStatusRunning indicates some fixtures have not been evaluated yet
+	EvalRunStatusRunning EvalRunStatus = "running"
+
+	// EvalRunStatusCompleted indicates every fixture was evaluated
+	EvalRunStatusCompleted EvalRunStatus = "completed"
+)
+
+// EvalRun executes the local agent against golden repositories with a model and prompt version, and scores whether
+// its changes compile, pass the tests and match the expected change
+type EvalRun struct {
+	// Unique identifier for the run
+	RunID string `json:"run_id" db:"run_id" example:"eval-12345-abcde"`
+	// Model the local agent was run with
+	Model string `json:"model" db:"model" example:"qwen2.5-coder:7b"`
+	// Version of the local agent's system prompt
+	PromptVersion string `json:"prompt_version" db:"prompt_version" example:"v2"`
+	// Progress of the run
+	Status EvalRunStatus `json:"status" db:"status" example:"completed"`
+	// Fixtures evaluated, in name order
+	Cases []string `json:"cases" db:"cases" example:"extract-function"`
+	// Outcome of each evaluated fixture
+	Results []EvalCaseResult `json:"results" db:"results"`
+	// Aggregate outcome, once the run completed
+	Summary *EvalSummary `json:"summary,omitempty" db:"summary"`
+	// User who started the run
+	StartedBy *string `json:"started_by,omitempty" db:"started_by" example:"user-12345"`
+	// Start timestamp
+	StartedAt time.Time `json:"started_at" db:"started_at" example:"2024-01-15T10:30:00Z"`
+	// Completion timestamp
+	CompletedAt *time.Time `json:"completed_at,omitempty" db:"completed_at" example:"2024-01-15T10:42:00Z"`
+} //@name EvalRun
+
+// EvalCaseResult is the outcome of a fixture in an evaluation run
+type EvalCaseResult struct {
+	// Fixture
+	Case string `json:"case" example:"extract-function"`
+	// Why the local agent stopped
+	StopReason string `json:"stop_reason,omitempty" example:"answered"`
+	// Whether the fixture compiled with the agent's change
+	Compiled bool `json:"compiled" example:"true"`
+	// Whether the fixture's tests passed with the agent's change
+	TestsPassed bool `json:"tests_passed" example:"true"`
+	// End of the output of the failed build or tests
+	Output string `json:"output,omitempty" example:"./stats.go:12:2: undefined: average"`
+	// Quality of the agent's change against the expected one
+	Diff EvalDiffQuality `json:"diff"`
+	// Time the fixture took
+	DurationMS int64 `json:"duration_ms" example:"48210"`
+	// Error that prevented evaluating the fixture
+	Error string `json:"error,omitempty" example:"local agent stopped: step budget exhausted before the model answered"`
+} //@name EvalCaseResult
+
+// EvalDiffQuality scores an agent's change against the expected change. Score is the mean of four checks from 0 to 1:
+// the F1 score of the changed files, the share of required patterns added, the absence of forbidden patterns and
+// staying within the changed lines budget.
+type EvalDiffQuality struct {
+	// Files the change touched
+	FilesChanged []string `json:"files_changed" example:"stats.go"`
+	// Added lines
+	LinesAdded int `json:"lines_added" example:"8"`
+	// Removed lines
+	LinesRemoved int `json:"lines_removed" example:"10"`
+	// Share of the changed files that were expected
+	FilePrecision float64 `json:"file_precision" example:"1"`
+	// Share of the expected files that were changed
+	FileRecall float64 `json:"file_recall" example:"1"`
+	// Required patterns found in added lines
+	PatternsMatched int `json:"patterns_matched" example:"1"`
+	// Required patterns of the fixture
+	PatternsRequired int `json:"patterns_required" example:"1"`
+	// Forbidden patterns found in added lines
+	ForbiddenMatched []string `json:"forbidden_matched,omitempty" example:"TODO"`
+	// Whether the change stayed within the changed lines budget
+	WithinLineBudget bool `json:"within_line_budget" example:"true"`
+	// Overall score from 0 to 1
+	Score float64 `json:"score" example:"0.92"`
+} //@name EvalDiffQuality
+
+// EvalSummary aggregates the outcomes of an evaluation run
+type EvalSummary struct {
+	// Evaluated fixtures
+	Cases int `json:"cases" example:"10"`
+	// Fixtures that compiled with the agent's change
+	Compiled int `json:"compiled" example:"9"`
+	// Fixtures whose tests passed with the agent's change
+	TestsPassed int `json:"tests_passed" example:"8"`
+	// Share of fixtures that compiled
+	CompileRate float64 `json:"compile_rate" example:"0.9"`
+	// Share of fixtures whose tests passed
+	TestPassRate float64 `json:"test_pass_rate" example:"0.8"`
+	// Mean diff quality score
+	MeanDiffScore float64 `json:"mean_diff_score" example:"0.74"`
+} //@name EvalSummary
+
+// RunEvalRequest represents the request to start an evaluation run
+type RunEvalRequest struct {
+	// Model to run the local agent with, defaults to the configured local model
+	Model string `json:"model,omitempty" validate:"omitempty,max=200" example:"qwen2.5-coder:7b"`
+	// Version of the local agent's system prompt, defaults to the configured version
+	PromptVersion string `json:"prompt_version,omitempty" validate:"omitempty,max=20" example:"v2"`
+	// Fixtures to evaluate, defaults to all of them
+	Cases []string `json:"cases,omitempty" validate:"omitempty,max=100,dive,required" example:"extract-function"`
+
+	// Authenticated caller, set by the controller
+	StartedBy string `json:"-"`
+} //@name RunEvalRequest
+
+// GetEvalRunRequest represents the request to get an evaluation run
+type GetEvalRunRequest struct {
+	// Unique identifier for the run
+	RunID string `uri:"id" validate:"required" example:"eval-12345-abcde"`
+} //@name GetEvalRunRequest
+
+// ListEvalRunsRequest represents the request to list the latest evaluation runs
+type ListEvalRunsRequest struct {
+	// Maximum number of runs to return (default 20)
+	Limit int `form:"limit" validate:"omitempty,min=1,max=100" example:"20"`
+} //@name ListEvalRunsRequest
+
+// ListEvalRunsResponse represents the response when listing evaluation runs
+type ListEvalRunsResponse struct {
+	// Runs, most recent first, without their per-fixture results
+	Runs []EvalRun `json:"runs"`
+} //@name ListEvalRunsResponse
+
+// CompareEvalRunsRequest represents the request to compare a candidate evaluation run with a baseline
+type CompareEvalRunsRequest struct {
+	// Run compared against, such as the last one with the current default model and prompt
+	Baseline string `form:"baseline" validate:"required" example:"eval-12345-abcde"`
+	// Run compared with the baseline
+	Candidate string `form:"candidate" validate:"required" example:"eval-67890-fghij"`
+} //@name CompareEvalRunsRequest
+
+// EvalComparison compares a candidate evaluation run with a baseline, fixture by fixture
+type EvalComparison struct {
+	// Baseline run, without its per-fixture results
+	Baseline EvalRun `json:"baseline"`
+	// Candidate run, without its per-fixture results
+	Candidate EvalRun `json:"candidate"`
+	// Change of the compile rate, over the fixtures both runs evaluated
+	CompileRateDelta float64 `json:"compile_rate_delta" example:"-0.1"`
+	// Change of the test pass rate, over the fixtures both runs evaluated
+	TestPassRateDelta float64 `json:"test_pass_rate_delta" example:"0"`
+	// Change of the mean diff quality score, over the fixtures both runs evaluated
+	DiffScoreDelta float64 `json:"diff_score_delta" example:"0.05"`
+	// Fixtures both runs evaluated, in name order
+	Cases []EvalCaseComparison `json:"cases"`
+	// Fixtures the candidate did worse on
+	Regressions []string `json:"regressions" example:"extract-function"`
+} //@name EvalComparison
+
+// EvalCaseComparison compares the outcomes of a fixture in two evaluation runs
+type EvalCaseComparison struct {
+	// Fixture
+	Case string `json:"case" example:"extract-function"`
+	// Outcome in the baseline run
+	Baseline EvalCaseOutcome `json:"baseline"`
+	// Outcome in the candidate run
+	Candidate EvalCaseOutcome `json:"candidate"`
+	// Whether the candidate stopped compiling or passing the tests, or scored lower
+	Regressed bool `json:"regressed" example:"false"`
+} //@name EvalCaseComparison
+
+// EvalCaseOutcome is the compared part of the outcome of a fixture
+type EvalCaseOutcome struct {
+	// Whether the fixture compiled
+	Compiled bool `json:"compiled" example:"true"`
+	// Whether the fixture's tests passed
+	TestsPassed bool `json:"tests_passed" example:"true"`
+	// Diff quality score
+	DiffScore float64 `json:"diff_score" example:"0.92"`
+} //@name EvalCaseOutcome
@@ -0,0 +1,41 @@
+// Package models provides data structures for the model output streamed to clients as server-sent events
+package models
+
+// EventStreamContentType is the media type of streamed model output. Endpoints prompting a model stream its output
+// as server-sent events to requests accepting it, instead of answering once the model is done.
+const EventStreamContentType = "text/event-stream"
+
+// Names of the server-sent events of streamed model output
+const (
+	// StreamEventChunk carries a StreamChunk of the text the model generated
+	StreamEventChunk = "chunk"
+
+	// StreamEventRetry carries a StreamRetry when the model's answer so far is discarded and the model is prompted
+	// again, such as to repair an invalid answer
+	StreamEventRetry = "retry"
+
+	// StreamEventResult carries the endpoint's response once the model is done, ending the stream
+	StreamEventResult = "result"
+
+	// StreamEventError carries the ProblemDetails of a request that failed after streaming started, ending the stream
+	StreamEventError = "error"
+)
+
+// StreamEvent is a server-sent event of streamed model output
+type StreamEvent struct {
+	// Name of the event
+	Event string
+	// Payload of the event, sent as JSON
+	Data any
+}
+
+// StreamChunk is a piece of the text a model generated, to be appended to the pieces before it
+type StreamChunk struct {
+	Text string `json:"text" example:"Return an error"`
+} //@name StreamChunk
+
+// StreamRetry tells the client to discard the text streamed so far, since the model is prompted again
+type StreamRetry struct {
+	// Why the model is prompted again
+	Reason string `json:"reason" example:"$.changelog.type: value must be one of added, changed, deprecated, removed, fixed, security"`
+} //@name StreamRetry
@@ -0,0 +1,128 @@
+// Package models provides data structures for experiments routing live tasks to a variant of the local agent
+package models
+
+import "time"
+
+// ExperimentStatus represents whether an experiment still assigns tasks
+type ExperimentStatus string
+
+const (
+	// ExperimentStatusRunning indicates the experiment assigns the eligible tasks to its arms
+	ExperimentStatusRunning ExperimentStatus = "running"
+
+	// ExperimentStatusStopped indicates the experiment no longer assigns tasks, its report stays available
+	ExperimentStatusStopped ExperimentStatus = "stopped"
+)
+
+// ExperimentArm is the side of an experiment a task was assigned to
+type ExperimentArm string
+
+const (
+	// ExperimentArmControl runs the task with the configured model and prompt version
+	ExperimentArmControl ExperimentArm = "control"
+
+	// ExperimentArmVariant runs the task with the experiment's model and prompt version
+	ExperimentArmVariant ExperimentArm = "variant"
+)
+
+// Experiment routes a share of the eligible tasks run by the local agent to a variant model or prompt version. The
+// other eligible tasks are the control, run as configured.
+type Experiment struct {
+	// Unique identifier for the experiment
+	ExperimentID string `json:"experiment_id" db:"experiment_id" example:"exp-12345-abcde"`
+	// Name of the experiment
+	Name string `json:"name" db:"name" example:"qwen2.5-coder on refactorings"`
+	// Task types eligible for the experiment
+	TaskTypes []TaskType `json:"task_types" db:"task_types" example:"refactoring"`
+	// Percentage of the eligible tasks assigned to the variant
+	TrafficPercent int `json:"traffic_percent" db:"traffic_percent" example:"20"`
+	// Model the variant runs with
+	VariantModel string `json:"variant_model" db:"variant_model" example:"qwen2.5-coder:7b"`
+	// Prompt version the variant runs with
+	VariantPromptVersion string `json:"variant_prompt_version" db:"variant_prompt_version" example:"v2"`
+	// Whether the experiment still assigns tasks
+	Status ExperimentStatus `json:"status" db:"status" example:"running"`
+	// User who created the experiment
+	CreatedBy *string `json:"created_by,omitempty" db:"created_by" example:"user-12345"`
+	// Creation timestamp
+	CreatedAt time.Time `json:"created_at" db:"created_at" example:"2024-01-15T10:30:00Z"`
+	// Stop timestamp
+	StoppedAt *time.Time `json:"stopped_at,omitempty" db:"stopped_at" example:"2024-01-22T10:30:00Z"`
+} //@name Experiment
+
+// CreateExperimentRequest represents the request to start an experiment
+type CreateExperimentRequest struct {
+	// Name of the experiment
+	Name string `json:"name" validate:"required,min=1,max=200" example:"qwen2.5-coder on refactorings"`
+	// Task types eligible for the experiment, defaults to every type the local agent runs
+	TaskTypes []TaskType `json:"task_types,omitempty" validate:"omitempty,max=5,dive,oneof=code_analysis refactoring code_review documentation custom" example:"refactoring"`
+	// Percentage of the eligible tasks assigned to the variant
+	TrafficPercent int `json:"traffic_percent" validate:"required,min=1,max=100" example:"20"`
+	// Model the variant runs with, defaults to the configured one
+	VariantModel string `json:"variant_model,omitempty" validate:"required_without=VariantPromptVersion,max=200" example:"qwen2.5-coder:7b"`
+	// Prompt version the variant runs with, defaults to the configured one
+	VariantPromptVersion string `json:"variant_prompt_version,omitempty" validate:"max=20" example:"v2"`
+
+	// Authenticated caller, set by the controller
+	CreatedBy string `json:"-"`
+} //@name CreateExperimentRequest
+
+// GetExperimentRequest represents the request to get an experiment
+type GetExperimentRequest struct {
+	// Unique identifier for the experiment
+	ExperimentID string `uri:"id" validate:"required" example:"exp-12345-abcde"`
+} //@name GetExperimentRequest
+
+// StopExperimentRequest represents the request to stop an experiment
+type StopExperimentRequest struct {
+	// Unique identifier for the experiment
+	ExperimentID string `uri:"id" validate:"required" example:"exp-12345-abcde"`
+} //@name StopExperimentRequest
+
+// GetExperimentReportRequest represents the request to compare an experiment's variant with its control
+type GetExperimentReportRequest struct {
+	// Unique identifier for the experiment
+	ExperimentID string `uri:"id" validate:"required" example:"exp-12345-abcde"`
+} //@name GetExperimentReportRequest
+
+// ListExperimentsResponse represents the response when listing experiments
+type ListExperimentsResponse struct {
+	// Experiments, most recent first
+	Experiments []Experiment `json:"experiments"`
+} //@name ListExperimentsResponse
+
+// ExperimentReport compares the tasks of an experiment's variant with its control
+type ExperimentReport struct {
+	// Experiment compared
+	Experiment Experiment `json:"experiment"`
+	// Outcome of the tasks run as configured
+	Control ExperimentArmReport `json:"control"`
+	// Outcome of the tasks run with the variant
+	Variant ExperimentArmReport `json:"variant"`
+	// Change of the success rate from the control to the variant
+	SuccessRateDelta float64 `json:"success_rate_delta" example:"0.05"`
+	// Change of the mean execution time from the control to the variant
+	MeanDurationDeltaMS int64 `json:"mean_duration_delta_ms" example:"-12000"`
+	// Change of the approval rate from the control to the variant
+	ApprovalRateDelta float64 `json:"approval_rate_delta" example:"0.1"`
+} //@name ExperimentReport
+
+// ExperimentArmReport aggregates the outcome of the tasks assigned to an experiment's arm
+type ExperimentArmReport struct {
+	// Tasks assigned to the arm
+	Tasks int `json:"tasks" example:"40"`
+	// Tasks that completed
+	Completed int `json:"completed" example:"36"`
+	// Tasks that failed
+	Failed int `json:"failed" example:"3"`
+	// Share of the finished tasks that completed
+	SuccessRate float64 `json:"success_rate" example:"0.923"`
+	// Mean time the executor took on the finished tasks
+	MeanDurationMS int64 `json:"mean_duration_ms" example:"48210"`
+	// Completed tasks whose result a user approved or rejected
+	Reviewed int `json:"reviewed" example:"20"`
+	// Completed tasks whose result a user approved
+	Approved int `json:"approved" example:"17"`
+	// Share of the reviewed tasks that were approved
+	ApprovalRate float64 `json:"approval_rate" example:"0.85"`
+} //@name ExperimentArmReport
@@ -0,0 +1,37 @@
+// Package models provides data structures for streamed list exports
+package models
+
+import "time"
+
+// NDJSONContentType is the media type of list exports, one JSON object per line. List endpoints that support
+// exports stream every matching item, ignoring pagination, to requests accepting it.
+const NDJSONContentType = "application/x-ndjson"
+
+// CSVContentType is the media type of exports requested as CSV
+const CSVContentType = "text/csv"
+
+// ExportFormat is the encoding of a compliance export
+type ExportFormat string
+
+const (
+	// ExportFormatJSON streams one JSON object per line
+	ExportFormatJSON ExportFormat = "json"
+	// ExportFormatCSV streams a header row followed by one row per item
+	ExportFormatCSV ExportFormat = "csv"
+)
+
+// ExportError is the last line of an export that failed after it started streaming, since its status was already sent
+type ExportError struct {
+	Error ProblemDetails `json:"error"`
+} //@name ExportError
+
+// ComplianceExportRequest represents the request to export users, access grants or audit events. The time range
+// applies to when users were created, grants last changed and events occurred.
+type ComplianceExportRequest struct {
+	// Encoding of the export, json by default
+	Format ExportFormat `form:"format" validate:"omitempty,oneof=json csv" example:"csv"`
+	// Only include items from this time on, in RFC 3339 format
+	From *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00" example:"2024-01-01T00:00:00Z"`
+	// Only include items before this time, in RFC 3339 format
+	To *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00" example:"2024-04-01T00:00:00Z"`
+} //@name ComplianceExportRequest
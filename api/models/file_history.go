@@ -0,0 +1,28 @@
+// Package models provides data structures for the git history of codebase files
+package models
+
+import "time"
+
+// MaxTaskOutputFileHistories is the maximum number of files listed under TaskOutputFileHistoryKey
+const MaxTaskOutputFileHistories = 10
+
+// FileHistory summarizes the recent commits changing a file of a codebase, telling the hot spots changed often and
+// recently from the files left alone
+type FileHistory struct {
+	// Path of the file, relative to the repository root
+	Path string `json:"path" db:"path" example:"billing/invoice.go"`
+	// Commits changing the file
+	Commits int `json:"commits" db:"commits" example:"14"`
+	// Lines the commits added
+	LinesAdded int `json:"lines_added" db:"lines_added" example:"220"`
+	// Lines the commits deleted
+	LinesDeleted int `json:"lines_deleted" db:"lines_deleted" example:"96"`
+	// Lines the commits added and deleted
+	Churn int `json:"churn" db:"churn" example:"316"`
+	// Authors of the commits, most recent first
+	Authors []string `json:"authors" db:"authors" example:"alice"`
+	// Oldest commit changing the file within the history read, which tells its age
+	FirstCommitAt time.Time `json:"first_commit_at" db:"first_commit_at" example:"2023-02-01T10:30:00Z"`
+	// Latest commit changing the file
+	LastCommitAt time.Time `json:"last_commit_at" db:"last_commit_at" example:"2024-01-10T10:30:00Z"`
+} //@name FileHistory
@@ -0,0 +1,202 @@
+// Package models provides data structures for the analysis findings of codebases and their lifecycle
+package models
+
+import "time"
+
+// FindingStatus is the lifecycle state of an analysis finding
+type FindingStatus string
+
+const (
+	// FindingStatusOpen is a finding the latest analysis still reports
+	FindingStatusOpen FindingStatus = "open"
+
+	// FindingStatusFixed is a finding a later analysis no longer reports, or one marked fixed by a user
+	FindingStatusFixed FindingStatus = "fixed"
+
+	// FindingStatusIgnored is a finding a user marked as a false positive or accepted, which later analyses leave alone
+	FindingStatusIgnored FindingStatus = "ignored"
+)
+
+// FindingSourceManual is the source of findings reported by users rather than an analysis, which analyses never close
+const FindingSourceManual = "manual"
+
+// Finding is a problem found in a codebase, tracked across analyses. A finding is identified by its codebase, rule
+// and fingerprint, so an analysis reporting it again updates it rather than adding a duplicate.
+type Finding struct {
+	// Unique identifier for the finding
+	FindingID string `json:"finding_id" db:"finding_id" example:"finding-12345-abcde"`
+	// Codebase the finding is in
+	CodebaseID string `json:"codebase_id" db:"codebase_id" example:"codebase-12345"`
+	// Analysis mode reporting the finding, or manual for findings reported by users
+	Source string `json:"source" db:"source" example:"dead_code"`
+	// Violated rule
+	RuleID string `json:"rule_id" db:"rule_id" example:"unused-function"`
+	// Identity of the finding within its rule, stable across analyses while the code it's about doesn't change
+	Fingerprint string `json:"fingerprint" db:"fingerprint" example:"4f9c2b1e0a7d3c65"`
+	// What the problem is
+	Message string `json:"message" db:"message" example:"function formatLegacyInvoice is unused"`
+	// File of the problem, relative to the repository root
+	FilePath string `json:"file_path,omitempty" db:"file_path" example:"billing/invoice.go"`
+	// First line of the problem, from 1
+	Line int `json:"line,omitempty" db:"line" example:"42"`
+	// Last line of the problem
+	EndLine int `json:"end_line,omitempty" db:"end_line" example:"58"`
+	// Owners of the files of the finding, from the codebase's CODEOWNERS file
+	Owners []string `json:"owners,omitempty" db:"owners" example:"@acme/billing"`
+	// How urgently the finding should be fixed, set by the severity rules of the codebase's project
+	Severity FindingSeverity `json:"severity" db:"severity" example:"medium"`
+	// Lifecycle state
+	Status FindingStatus `json:"status" db:"status" example:"open"`
+	// Whether the finding was open when its codebase was last baselined. Baselined findings are legacy ones, left out
+	// of listings, task outputs and quality gates by default.
+	Baselined bool `json:"baselined" db:"baselined" example:"false"`
+	// Why the finding was ignored
+	Justification *string `json:"justification,omitempty" db:"justification" example:"Called through reflection by the plugin loader"`
+	// User who last changed the status, absent for changes made by analyses
+	StatusChangedBy *string `json:"status_changed_by,omitempty" db:"status_changed_by" example:"user-12345"`
+	// Task whose analysis first reported the finding, absent for manual findings
+	FirstTaskID *string `json:"first_task_id,omitempty" db:"first_task_id" example:"task-12345-abcde"`
+	// Task whose analysis last reported the finding
+	LastTaskID *string `json:"last_task_id,omitempty" db:"last_task_id" example:"task-67890-fghij"`
+	// First report timestamp
+	FirstSeenAt time.Time `json:"first_seen_at" db:"first_seen_at" example:"2024-01-15T10:30:00Z"`
+	// Last report timestamp
+	LastSeenAt time.Time `json:"last_seen_at" db:"last_seen_at" example:"2024-01-22T10:30:00Z"`
+	// When the finding was fixed, absent unless it's fixed
+	FixedAt *time.Time `json:"fixed_at,omitempty" db:"fixed_at" example:"2024-01-29T10:30:00Z"`
+	// Last update timestamp
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at" example:"2024-01-22T10:30:00Z"`
+} //@name Finding
+
+// CreateFindingRequest represents the request to report a finding on a codebase by hand
+type CreateFindingRequest struct {
+	// Codebase the finding is in
+	CodebaseID string `uri:"id" validate:"required" example:"codebase-12345"`
+	// Violated rule
+	RuleID string `json:"rule_id" validate:"required,max=255" example:"missing-timeout"`
+	// What the problem is
+	Message string `json:"message" validate:"required,max=4096" example:"HTTP client has no timeout"`
+	// File of the problem, relative to the repository root
+	FilePath string `json:"file_path,omitempty" validate:"omitempty,max=1024" example:"payments/client.go"`
+	// First line of the problem, from 1
+	Line int `json:"line,omitempty" validate:"omitempty,min=1" example:"12"`
+	// Last line of the problem
+	EndLine int `json:"end_line,omitempty" validate:"omitempty,gtefield=Line" example:"14"`
+	// How urgently the finding should be fixed, defaults to the severity the project's rules give it
+	Severity FindingSeverity `json:"severity,omitempty" validate:"omitempty,oneof=low medium high critical" example:"high"`
+	// User reporting the finding
+	UserID string `json:"-"`
+} //@name CreateFindingRequest
+
+// GetFindingRequest represents the request to get a finding
+type GetFindingRequest struct {
+	// Finding ID
+	FindingID string `uri:"finding_id" validate:"required" example:"finding-12345-abcde"`
+} //@name GetFindingRequest
+
+// UpdateFindingRequest represents the request to change the status of a finding, such as ignoring a false positive
+type UpdateFindingRequest struct {
+	// Finding ID
+	FindingID string `uri:"finding_id" validate:"required" example:"finding-12345-abcde"`
+	// New status
+	Status FindingStatus `json:"status" validate:"required,oneof=open fixed ignored" example:"ignored"`
+	// Why the finding is ignored, required when ignoring it
+	Justification string `json:"justification,omitempty" validate:"max=2048" example:"Called through reflection by the plugin loader"`
+	// User changing the status
+	UserID string `json:"-"`
+} //@name UpdateFindingRequest
+
+// DeleteFindingRequest represents the request to delete a finding
+type DeleteFindingRequest struct {
+	// Finding ID
+	FindingID string `uri:"finding_id" validate:"required" example:"finding-12345-abcde"`
+} //@name DeleteFindingRequest
+
+// ListFindingsRequest represents the request to list the findings of a codebase
+type ListFindingsRequest struct {
+	// Codebase ID
+	CodebaseID string `uri:"id" validate:"required" example:"codebase-12345"`
+	// Only list findings in this status
+	Status *FindingStatus `form:"status,omitempty" validate:"omitempty,oneof=open fixed ignored" example:"open"`
+	// Only list findings of this source
+	Source string `form:"source,omitempty" validate:"omitempty,max=64" example:"dead_code"`
+	// Only list findings of this rule
+	RuleID string `form:"rule_id,omitempty" validate:"omitempty,max=255" example:"unused-function"`
+	// Only list findings in this file
+	FilePath string `form:"file_path,omitempty" validate:"omitempty,max=1024" example:"billing/invoice.go"`
+	// Only list findings owned by this user, team or email
+	Owner string `form:"owner,omitempty" validate:"omitempty,max=255" example:"@acme/billing"`
+	// Also list the findings suppressed by the codebase's baseline
+	IncludeBaselined bool `form:"include_baselined" example:"false"`
+	// Maximum number of findings, defaults to 50
+	Limit int `form:"limit" validate:"omitempty,min=1,max=500" example:"50"`
+	// Number of findings to skip
+	Offset int `form:"offset" validate:"omitempty,min=0" example:"0"`
+} //@name ListFindingsRequest
+
+// ListFindingsResponse represents the findings of a codebase, most recently seen first
+type ListFindingsResponse struct {
+	// Findings
+	Findings []Finding `json:"findings"`
+	// Number of findings in each status, before the filters other than the baseline
+	StatusCounts map[FindingStatus]int `json:"status_counts"`
+} //@name ListFindingsResponse
+
+// FindingBaseline is a snapshot of the open findings of a codebase, taken when adopting analyses on legacy code.
+// The findings open at the time are suppressed, so only the findings new since the baseline are reported.
+type FindingBaseline struct {
+	// Codebase the baseline is of
+	CodebaseID string `json:"codebase_id" db:"codebase_id" example:"codebase-12345"`
+	// Number of findings the baseline suppressed when it was taken
+	FindingCount int `json:"finding_count" db:"finding_count" example:"1342"`
+	// User who took the baseline
+	CreatedBy *string `json:"created_by,omitempty" db:"created_by" example:"user-12345"`
+	// When the baseline was taken
+	CreatedAt time.Time `json:"created_at" db:"created_at" example:"2024-01-15T10:30:00Z"`
+} //@name FindingBaseline
+
+// RebaselineFindingsRequest represents the request to replace the baseline of a codebase with its open findings
+type RebaselineFindingsRequest struct {
+	// Codebase ID
+	CodebaseID string `uri:"id" validate:"required" example:"codebase-12345"`
+	// User taking the baseline
+	UserID string `json:"-"`
+} //@name RebaselineFindingsRequest
+
+// GetFindingBaselineRequest represents the request to get the baseline of a codebase and the findings it suppresses
+type GetFindingBaselineRequest struct {
+	// Codebase ID
+	CodebaseID string `uri:"id" validate:"required" example:"codebase-12345"`
+	// Only list suppressed findings in this status
+	Status *FindingStatus `form:"status,omitempty" validate:"omitempty,oneof=open fixed ignored" example:"open"`
+	// Maximum number of findings, defaults to 50
+	Limit int `form:"limit" validate:"omitempty,min=1,max=500" example:"50"`
+	// Number of findings to skip
+	Offset int `form:"offset" validate:"omitempty,min=0" example:"0"`
+} //@name GetFindingBaselineRequest
+
+// FindingBaselineResponse represents the baseline of a codebase and the legacy findings it suppresses
+type FindingBaselineResponse struct {
+	// Baseline
+	Baseline FindingBaseline `json:"baseline"`
+	// Suppressed findings, most recently seen first
+	Findings []Finding `json:"findings"`
+	// Number of suppressed findings in each status
+	StatusCounts map[FindingStatus]int `json:"status_counts"`
+} //@name FindingBaselineResponse
+
+// FindingSyncResult counts the changes an analysis made to the findings of its codebase
+type FindingSyncResult struct {
+	// Findings reported for the first time
+	Opened int `json:"opened"`
+	// Fixed findings reported again
+	Reopened int `json:"reopened"`
+	// Open findings the analysis no longer reports
+	Fixed int `json:"fixed"`
+	// Opened and reopened findings per severity, leaving out the findings suppressed by the baseline
+	NewBySeverity map[FindingSeverity]int `json:"new_by_severity"`
+	// Reported findings suppressed by the baseline
+	Suppressed int `json:"suppressed"`
+	// Indexes of the reported issues suppressed by the baseline, in ascending order
+	SuppressedIssues []int `json:"-"`
+}
@@ -0,0 +1,57 @@
+// Package models provides data structures for the text generated from a change by the configured model
+package models
+
+// ChangelogType is the section of a changelog an entry belongs to, following Keep a Changelog
+type ChangelogType string
+
+const (
+	// ChangelogTypeAdded is a new feature
+	ChangelogTypeAdded ChangelogType = "added"
+
+	// ChangelogTypeChanged is a change in existing functionality
+	ChangelogTypeChanged ChangelogType = "changed"
+
+	// ChangelogTypeDeprecated is a feature that will be removed
+	ChangelogTypeDeprecated ChangelogType = "deprecated"
+
+	// ChangelogTypeRemoved is a feature that was removed
+	ChangelogTypeRemoved ChangelogType = "removed"
+
+	// ChangelogTypeFixed is a bug fix
+	ChangelogTypeFixed ChangelogType = "fixed"
+
+	// ChangelogTypeSecurity is a fix of a vulnerability
+	ChangelogTypeSecurity ChangelogType = "security"
+)
+
+// GeneratePRDescriptionRequest represents the request to describe a change as a pull request
+type GeneratePRDescriptionRequest struct {
+	// Unified diff of the change
+	Diff string `json:"diff,omitempty" validate:"required_without=TaskID,excluded_with=TaskID,max=1048576" example:"diff --git a/calc.go b/calc.go\n..."`
+	// Task whose change is described, instead of a diff
+	TaskID string `json:"task_id,omitempty" validate:"omitempty,max=100" example:"task-12345-abcde"`
+	// What the change is for, such as the issue it resolves
+	Context string `json:"context,omitempty" validate:"omitempty,max=4000" example:"Fixes the division by zero reported in #42"`
+} //@name GeneratePRDescriptionRequest
+
+// PRDescription is the text describing a change in a pull request, its commit and the changelog
+type PRDescription struct {
+	// Title of the pull request, also usable as the commit subject
+	Title string `json:"title" example:"Return an error when dividing by zero"`
+	// Body of the pull request, in Markdown
+	Body string `json:"body" example:"Div panicked when the divisor was zero. It now returns ErrDivisionByZero."`
+	// Changelog entry of the change
+	Changelog ChangelogEntry `json:"changelog"`
+	// Model that generated the description
+	Model string `json:"model" example:"qwen2.5-coder:7b"`
+	// Whether the diff was too large and only its beginning was described
+	Truncated bool `json:"truncated,omitempty" example:"false"`
+} //@name PRDescription
+
+// ChangelogEntry is an entry of a changelog
+type ChangelogEntry struct {
+	// Section of the changelog the entry belongs to
+	Type ChangelogType `json:"type" example:"fixed"`
+	// One-line summary of the change for its readers
+	Summary string `json:"summary" example:"Dividing by zero returns an error instead of panicking"`
+} //@name ChangelogEntry
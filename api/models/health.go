@@ -22,6 +22,8 @@ type HealthCheckResponse struct {
 	Timestamp time.Time `json:"timestamp" example:"2024-01-15T10:30:00Z"`
 	// Uptime in seconds (optional)
 	Uptime *int64 `json:"uptime,omitempty" example:"3600"`
+	// Dependencies that failed their startup check, whose features are disabled while the service runs degraded
+	Unavailable []string `json:"unavailable,omitempty" example:"cognito"`
 } //@name HealthCheckResponse
 
 // HealthStatus represents the possible health statuses
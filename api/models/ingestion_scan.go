@@ -0,0 +1,84 @@
+// Package models provides data structures for the secret and license scans run before codebases are ingested
+package models
+
+import "time"
+
+// ScanFindingKind is the kind of problem an ingestion scan finding reports
+type ScanFindingKind string
+
+const (
+	// ScanFindingKindSecret is a credential committed to the repository
+	ScanFindingKindSecret ScanFindingKind = "secret"
+
+	// ScanFindingKindLicense is a license that doesn't allow the code to be ingested
+	ScanFindingKindLicense ScanFindingKind = "license"
+)
+
+// ScanFindingSeverity is the severity of an ingestion scan finding. High severity findings block ingestion.
+type ScanFindingSeverity string
+
+const (
+	// ScanFindingSeverityHigh is a finding blocking ingestion
+	ScanFindingSeverityHigh ScanFindingSeverity = "high"
+
+	// ScanFindingSeverityMedium is a likely problem worth reviewing that doesn't block ingestion
+	ScanFindingSeverityMedium ScanFindingSeverity = "medium"
+)
+
+// ScanFinding is a committed secret or incompatible license found by a codebase's latest ingestion scan
+type ScanFinding struct {
+	// Unique identifier for the finding
+	FindingID string `json:"finding_id" db:"finding_id" example:"finding-12345-abcde"`
+	// Scanned codebase
+	CodebaseID string `json:"codebase_id" db:"codebase_id" example:"codebase-12345"`
+	// Kind of problem
+	Kind ScanFindingKind `json:"kind" db:"kind" example:"secret"`
+	// Secret rule or SPDX identifier of the license
+	Rule string `json:"rule" db:"rule" example:"aws-access-key-id"`
+	// Severity; high severity findings block ingestion
+	Severity ScanFindingSeverity `json:"severity" db:"severity" example:"high"`
+	// File, relative to the repository root
+	FilePath string `json:"file_path" db:"file_path" example:"config/prod.env"`
+	// Line of a secret, absent for licenses
+	Line *int `json:"line,omitempty" db:"line" example:"3"`
+	// Description of the finding; secrets themselves are never returned
+	Message string `json:"message" db:"message" example:"Committed AWS access key ID"`
+	// Scanned commit
+	CommitSHA string `json:"commit_sha" db:"commit_sha" example:"9fceb02d0ae598e95dc970b74767f19372d61af8"`
+	// Scan timestamp
+	DetectedAt time.Time `json:"detected_at" db:"detected_at" example:"2024-01-15T10:30:00Z"`
+} //@name ScanFinding
+
+// ScanCodebaseRequest represents the request to scan a codebase before it is ingested
+type ScanCodebaseRequest struct {
+	// Codebase to scan
+	CodebaseID string `uri:"id" validate:"required" example:"codebase-12345"`
+} //@name ScanCodebaseRequest
+
+// ScanCodebaseResponse represents the outcome of a codebase's ingestion scan
+type ScanCodebaseResponse struct {
+	// Scanned codebase
+	CodebaseID string `json:"codebase_id" example:"codebase-12345"`
+	// Codebase status after the scan, ingestion_blocked when any finding is high severity
+	Status CodebaseStatus `json:"status" example:"ingestion_blocked"`
+	// Why ingestion is blocked, absent when it isn't
+	IngestionError *string `json:"ingestion_error,omitempty" example:"ingestion blocked by 1 high severity findings: Committed AWS access key ID in config/prod.env:3"`
+	// Findings, by file and line
+	Findings []ScanFinding `json:"findings"`
+} //@name ScanCodebaseResponse
+
+// ListScanFindingsRequest represents the request to list the findings of a codebase's latest ingestion scan
+type ListScanFindingsRequest struct {
+	// Scanned codebase
+	CodebaseID string `uri:"id" validate:"required" example:"codebase-12345"`
+	// Only list findings of this kind
+	Kind *ScanFindingKind `form:"kind,omitempty" validate:"omitempty,oneof=secret license" example:"secret"`
+	// Only list findings of this severity
+	Severity *ScanFindingSeverity `form:"severity,omitempty" validate:"omitempty,oneof=high medium" example:"high"`
+} //@name ListScanFindingsRequest
+
+// ListScanFindingsResponse represents the findings of a codebase's latest ingestion scan
+type ListScanFindingsResponse struct {
+	// Findings, by file and line
+	Findings []ScanFinding `json:"findings"`
+} //@name ListScanFindingsResponse
@@ -0,0 +1,94 @@
+// Package models provides data structures for the Jira sites tasks link their issues on
+package models
+
+import "time"
+
+const (
+	// DefaultJiraDoneTransition is the transition, or status, issues are moved through when their task completes
+	DefaultJiraDoneTransition = "Done"
+)
+
+// JiraSite holds the Jira site of an organization and the account the issues of its projects are updated with. The
+// API token is never returned.
+type JiraSite struct {
+	// Organization the site belongs to
+	Organization string `json:"organization" db:"organization" example:"acme"`
+	// URL of the Jira site
+	SiteURL string `json:"site_url" db:"site_url" example:"https://acme.atlassian.net"`
+	// Email of the account calling Jira
+	Email string `json:"email" db:"email" example:"refactor-bot@acme.com"`
+	// API token of the account
+	APIToken string `json:"-" db:"api_token"`
+	// Keys of the Jira projects on the site, matched with the prefix of issue keys
+	ProjectKeys []string `json:"project_keys" db:"project_keys" example:"PAY,AUTH"`
+	// Transition, or status, issues are moved through when their task completes
+	DoneTransition string `json:"done_transition" db:"done_transition" example:"Done"`
+	// Creation timestamp
+	CreatedAt time.Time `json:"created_at" db:"created_at" example:"2024-01-15T10:30:00Z"`
+	// Last update timestamp
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at" example:"2024-01-15T10:30:00Z"`
+} //@name JiraSite
+
+// PutJiraSiteRequest represents the request to configure the Jira site of an organization
+type PutJiraSiteRequest struct {
+	// Organization the site belongs to
+	Organization string `uri:"organization" validate:"required,max=100" example:"acme"`
+	// URL of the Jira site
+	SiteURL string `json:"site_url" validate:"required,url,startswith=https://,max=255" example:"https://acme.atlassian.net"`
+	// Email of the account calling Jira
+	Email string `json:"email" validate:"required,email,max=255" example:"refactor-bot@acme.com"`
+	// API token of the account
+	APIToken string `json:"api_token" validate:"required,max=500" example:"ATATT3xFfGF0..."`
+	// Keys of the Jira projects on the site
+	ProjectKeys []string `json:"project_keys" validate:"required,min=1,max=50,dive,required,max=20" example:"PAY,AUTH"`
+	// Transition, or status, issues are moved through when their task completes, defaults to Done
+	DoneTransition string `json:"done_transition,omitempty" validate:"omitempty,max=100" example:"Resolve"`
+} //@name PutJiraSiteRequest
+
+// GetJiraSiteRequest represents the request to get the Jira site of an organization
+type GetJiraSiteRequest struct {
+	// Organization the site belongs to
+	Organization string `uri:"organization" validate:"required,max=100" example:"acme"`
+} //@name GetJiraSiteRequest
+
+// DeleteJiraSiteRequest represents the request to delete the Jira site of an organization
+type DeleteJiraSiteRequest struct {
+	// Organization the site belongs to
+	Organization string `uri:"organization" validate:"required,max=100" example:"acme"`
+} //@name DeleteJiraSiteRequest
+
+// ListJiraSitesResponse represents the response when listing the Jira sites
+type ListJiraSitesResponse struct {
+	// Sites ordered by organization
+	Sites []JiraSite `json:"sites"`
+} //@name ListJiraSitesResponse
+
+// JiraIssueLink links a task to the Jira issue it works on
+type JiraIssueLink struct {
+	// Task working on the issue
+	TaskID string `json:"-" db:"task_id"`
+	// Organization whose Jira site has the issue
+	Organization string `json:"organization" db:"organization" example:"acme"`
+	// Key of the issue
+	IssueKey string `json:"issue_key" db:"issue_key" example:"PAY-123"`
+	// Web URL of the issue
+	IssueURL string `json:"issue_url" db:"issue_url" example:"https://acme.atlassian.net/browse/PAY-123"`
+	// Task status last reported on the issue
+	SyncedStatus TaskStatus `json:"synced_status" db:"synced_status" example:"in_progress"`
+	// Creation timestamp
+	CreatedAt time.Time `json:"-" db:"created_at"`
+	// Time the issue was last updated
+	UpdatedAt time.Time `json:"-" db:"updated_at"`
+} //@name JiraIssueLink
+
+// JiraIssueUpdate is a linked issue whose task changed status since it was last reported
+type JiraIssueUpdate struct {
+	// Link of the task to the issue
+	Link JiraIssueLink
+	// Current status of the task
+	Status TaskStatus
+	// Title of the task
+	Title string
+	// Error message of the task, if it failed
+	ErrorMessage *string
+}
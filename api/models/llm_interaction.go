@@ -0,0 +1,77 @@
+// Package models provides data structures for the log of the LLM calls made while executing tasks
+package models
+
+import "time"
+
+// LLMInteraction is a call of an LLM made while executing a task. Its prompt and response are only kept when the
+// project's redaction policy allows logging LLM content, and are masked with the policy.
+type LLMInteraction struct {
+	// Unique identifier for the interaction
+	InteractionID string `json:"interaction_id" db:"interaction_id" example:"llm-12345-abcde"`
+	// Task whose execution called the LLM
+	TaskID string `json:"task_id" db:"task_id" example:"task-12345-abcde"`
+	// Project of the task
+	ProjectID string `json:"project_id" db:"project_id" example:"proj-12345-abcde"`
+	// Executor that called the LLM
+	Executor string `json:"executor" db:"executor" example:"local_agent"`
+	// Number of the call within the task's execution, from 1
+	Step int `json:"step" db:"step" example:"1"`
+	// Messages added to the chat since the previous call, absent when content isn't logged
+	Prompt []LLMMessage `json:"prompt,omitempty" db:"prompt"`
+	// Reply of the LLM, absent when content isn't logged
+	Response *LLMMessage `json:"response,omitempty" db:"response"`
+	// Files put in the chat by tools since the previous call
+	ContextDocIDs []string `json:"context_doc_ids" db:"context_doc_ids" example:"internal/payments/charge.go"`
+	// Time the LLM took to reply
+	LatencyMS int64 `json:"latency_ms" db:"latency_ms" example:"5230"`
+	// Tokens the LLM read, when reported
+	PromptTokens int `json:"prompt_tokens" db:"prompt_tokens" example:"1830"`
+	// Tokens the LLM wrote, when reported
+	ResponseTokens int `json:"response_tokens" db:"response_tokens" example:"212"`
+	// Whether the prompt and response were logged
+	ContentLogged bool `json:"content_logged" db:"content_logged" example:"true"`
+	// Error of a failed call
+	Error string `json:"error,omitempty" db:"error" example:"model not found"`
+	// Time of the call
+	CreatedAt time.Time `json:"created_at" db:"created_at" example:"2024-01-15T10:30:00Z"`
+} //@name LLMInteraction
+
+// LLMMessage is a message of a chat with an LLM
+type LLMMessage struct {
+	// Author of the message: system, user, assistant or tool
+	Role string `json:"role" example:"assistant"`
+	// Text of the message
+	Content string `json:"content,omitempty" example:"The charge function retries without backoff."`
+	// Tools the LLM called
+	ToolCalls []LLMToolCall `json:"tool_calls,omitempty"`
+	// Tool whose result the message carries
+	ToolName string `json:"tool_name,omitempty" example:"read_file"`
+} //@name LLMMessage
+
+// LLMToolCall is an LLM's call of a tool
+type LLMToolCall struct {
+	// Called tool
+	Name string `json:"name" example:"read_file"`
+	// Arguments of the call
+	Arguments map[string]any `json:"arguments"`
+} //@name LLMToolCall
+
+// GetLLMTraceRequest represents the request to get the LLM calls of a task
+type GetLLMTraceRequest struct {
+	// Task whose LLM calls are listed
+	TaskID string `uri:"id" validate:"required" example:"task-12345-abcde"`
+	// Auth provider ID of the caller, set from the authenticated user
+	UserID string `json:"-"`
+} //@name GetLLMTraceRequest
+
+// GetLLMTraceResponse represents the response when getting the LLM calls of a task
+type GetLLMTraceResponse struct {
+	// Task whose LLM calls are listed
+	TaskID string `json:"task_id" example:"task-12345-abcde"`
+	// LLM calls, oldest first
+	Interactions []LLMInteraction `json:"interactions"`
+	// Tokens read over all calls
+	PromptTokens int `json:"prompt_tokens" example:"9120"`
+	// Tokens written over all calls
+	ResponseTokens int `json:"response_tokens" example:"1044"`
+} //@name GetLLMTraceResponse
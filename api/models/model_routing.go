@@ -0,0 +1,46 @@
+// Package models provides data structures for routing tasks between a cheap and a strong model
+package models
+
+// TaskInputModelKey is the input field selecting the model a task runs with. Only TaskModelAuto is accepted.
+const TaskInputModelKey = "model"
+
+// TaskModelAuto lets the executor pick the model of a task, running simple tasks on a cheaper, faster model and
+// escalating to a stronger one when needed
+const TaskModelAuto = "auto"
+
+// TaskOutputModelRoutingKey is the output key holding the routing decisions of a task run on the auto model
+const TaskOutputModelRoutingKey = "model_routing"
+
+// ModelRoutingReason explains why a task was run on a model
+type ModelRoutingReason string
+
+const (
+	// ModelRoutingReasonDocumentation routes documentation tasks to the cheap model
+	ModelRoutingReasonDocumentation ModelRoutingReason = "documentation"
+
+	// ModelRoutingReasonSmallDiff routes tasks about a diff changing few lines to the cheap model
+	ModelRoutingReasonSmallDiff ModelRoutingReason = "small_diff"
+
+	// ModelRoutingReasonLargeDiff routes tasks about a diff changing many lines to the strong model
+	ModelRoutingReasonLargeDiff ModelRoutingReason = "large_diff"
+
+	// ModelRoutingReasonHighComplexity routes tasks about complex code, as last measured, to the strong model
+	ModelRoutingReasonHighComplexity ModelRoutingReason = "high_complexity"
+
+	// ModelRoutingReasonDefault routes tasks not known to be simple to the strong model
+	ModelRoutingReasonDefault ModelRoutingReason = "default"
+
+	// ModelRoutingReasonValidationFailed escalates a task to the strong model once the cheap model's answer failed
+	// validation
+	ModelRoutingReasonValidationFailed ModelRoutingReason = "validation_failed"
+)
+
+// ModelRoutingDecision records a model a task ran on and why, in the order the task ran on them
+type ModelRoutingDecision struct {
+	Model  string             `json:"model" example:"qwen2.5-coder:1.5b"`
+	Reason ModelRoutingReason `json:"reason" example:"small_diff"`
+	// What the decision was based on, such as the size of the diff
+	Detail string `json:"detail,omitempty" example:"diff changes 42 lines, at most 200 run on the cheap model"`
+	// Error the task failed with on the model, empty when it succeeded or is still running
+	Error string `json:"error,omitempty" example:"local agent stopped: model answer failed validation"`
+} //@name ModelRoutingDecision
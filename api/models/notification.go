@@ -0,0 +1,281 @@
+// Package models provides data structures for email and Slack notification channels and the in-app notification inbox
+package models
+
+import "time"
+
+// NotificationChannelType represents how a notification channel delivers messages
+type NotificationChannelType string
+
+const (
+	// NotificationChannelTypeEmail delivers notifications by email to the user who created the channel
+	NotificationChannelTypeEmail NotificationChannelType = "email"
+
+	// NotificationChannelTypeSlack delivers notifications to a project's Slack channel
+	NotificationChannelTypeSlack NotificationChannelType = "slack"
+)
+
+// NotificationEvent represents an event notification channels can subscribe to
+type NotificationEvent string
+
+const (
+	// NotificationEventTaskQueued is sent when a task is queued for execution
+	NotificationEventTaskQueued NotificationEvent = "task.queued"
+
+	// NotificationEventTaskStarted is sent when the execution of a task starts
+	NotificationEventTaskStarted NotificationEvent = "task.started"
+
+	// NotificationEventTaskCompleted is sent when a task completes
+	NotificationEventTaskCompleted NotificationEvent = "task.completed"
+
+	// NotificationEventTaskFailed is sent when a task fails
+	NotificationEventTaskFailed NotificationEvent = "task.failed"
+
+	// NotificationEventTaskCancelled is sent when a task is cancelled
+	NotificationEventTaskCancelled NotificationEvent = "task.cancelled"
+
+	// NotificationEventAgentProvisioningFailed is sent when creating or rebuilding an agent's infrastructure fails
+	NotificationEventAgentProvisioningFailed NotificationEvent = "agent.provisioning_failed"
+
+	// NotificationEventAccessGranted is recorded in the inbox of a user who was granted access to a project
+	NotificationEventAccessGranted NotificationEvent = "access.granted"
+
+	// NotificationEventInvitationAccepted is recorded in the inbox of a user whose invitation was accepted
+	NotificationEventInvitationAccepted NotificationEvent = "invitation.accepted"
+)
+
+const (
+	// DefaultNotificationsPageSize is the number of inbox notifications listed when max_results is not set
+	DefaultNotificationsPageSize = 20
+)
+
+// NotificationChannel is a user's subscription to events over email or Slack
+type NotificationChannel struct {
+	ChannelID string                  `json:"channel_id" db:"channel_id"`
+	UserID    string                  `json:"user_id" db:"user_id"`                 // User who created the channel
+	ProjectID *string                 `json:"project_id,omitempty" db:"project_id"` // Nil subscribes to every project and to agent events
+	Type      NotificationChannelType `json:"type" db:"type"`
+	Name      string                  `json:"name" db:"name"`
+	Events    []NotificationEvent     `json:"events" db:"events"`
+	Enabled   bool                    `json:"enabled" db:"enabled"`
+	Email     *string                 `json:"email,omitempty" db:"email"` // Recipient of email channels
+	Slack     *SlackChannelConfig     `json:"slack,omitempty" db:"slack"`
+	CreatedAt time.Time               `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time               `json:"updated_at" db:"updated_at"`
+}
+
+// SlackChannelConfig configures where a Slack channel posts, either an incoming webhook or a bot token and channel
+type SlackChannelConfig struct {
+	// Incoming webhook URL
+	WebhookURL string `json:"webhook_url,omitempty" validate:"omitempty,url,startswith=https://hooks.slack.com/" example:"https://hooks.slack.com/services/T000/B000/XXXX"`
+	// Bot token used with chat.postMessage
+	BotToken string `json:"bot_token,omitempty" validate:"omitempty,startswith=xoxb-" example:"xoxb-1234"`
+	// Channel the bot posts to
+	Channel string `json:"channel,omitempty" validate:"omitempty,max=80" example:"#refactoring-alerts"`
+} //@name SlackChannelConfig
+
+// Notification is a message about an event, delivered to every channel subscribed to it
+type Notification struct {
+	Event      NotificationEvent
+	ProjectID  string // Empty for events that don't belong to a project
+	UserID     string // User whose inbox records the notification; empty to only notify channels
+	ResourceID string // Task, project or user the event is about
+	Subject    string
+	Message    string
+	EventID    string // Outbox event delivering the notification, so a redelivery doesn't record it in the inbox twice
+}
+
+// OutboxEvent is a notification waiting in the outbox for delivery. It's recorded in the transaction of the change
+// that raised it, so a crash can't lose it, and delivered at least once.
+type OutboxEvent struct {
+	EventID       string
+	Notification  Notification
+	Attempts      int     // Delivery attempts, including the one in progress
+	LastError     *string // Error of the last failed attempt
+	CreatedAt     time.Time
+	NextAttemptAt time.Time
+	DeliveredAt   *time.Time
+}
+
+// CreateNotificationChannelRequest represents the request to create a notification channel
+type CreateNotificationChannelRequest struct {
+	// Delivery method
+	Type NotificationChannelType `json:"type" validate:"required,oneof=email slack" example:"slack"`
+	// Human-readable channel name
+	Name string `json:"name" validate:"required,min=1,max=100" example:"Refactoring alerts"`
+	// Project whose events are delivered; required for Slack, omit for email to receive every project's events
+	ProjectID *string `json:"project_id,omitempty" validate:"omitempty,project_id" example:"proj-12345-abcde"`
+	// Events to deliver
+	Events []NotificationEvent `json:"events" validate:"required,min=1,dive,oneof=task.queued task.started task.completed task.failed task.cancelled agent.provisioning_failed" example:"task.failed"`
+	// Slack destination, required for Slack channels
+	Slack *SlackChannelConfig `json:"slack,omitempty" validate:"omitempty"`
+	// Whether the channel delivers notifications (default true)
+	Enabled *bool `json:"enabled,omitempty" example:"true"`
+	// Optional token unique to the request, retries carrying it return the channel it created instead of creating another
+	ClientToken string `json:"client_token,omitempty" validate:"omitempty,max=64" example:"3f2b8c1e-9d4a-4e7b-a6f0-2c5d8e1b7a93"`
+
+	// Authenticated caller, set by the controller
+	UserID string `json:"-"`
+	Email  string `json:"-"`
+} //@name CreateNotificationChannelRequest
+
+// UpdateNotificationChannelRequest represents the request to update a notification channel
+type UpdateNotificationChannelRequest struct {
+	// Unique identifier for the channel
+	ChannelID string `uri:"channel_id" validate:"required" example:"chan-12345-abcde"`
+	// Human-readable channel name
+	Name *string `json:"name,omitempty" validate:"omitempty,min=1,max=100" example:"Refactoring alerts"`
+	// Events to deliver
+	Events []NotificationEvent `json:"events,omitempty" validate:"omitempty,min=1,dive,oneof=task.queued task.started task.completed task.failed task.cancelled agent.provisioning_failed" example:"task.failed"`
+	// Slack destination of Slack channels
+	Slack *SlackChannelConfig `json:"slack,omitempty" validate:"omitempty"`
+	// Whether the channel delivers notifications
+	Enabled *bool `json:"enabled,omitempty" example:"false"`
+
+	// Authenticated caller, set by the controller
+	UserID string `json:"-"`
+} //@name UpdateNotificationChannelRequest
+
+// GetNotificationChannelRequest represents the request to get a notification channel
+type GetNotificationChannelRequest struct {
+	// Unique identifier for the channel
+	ChannelID string `uri:"channel_id" validate:"required" example:"chan-12345-abcde"`
+
+	// Authenticated caller, set by the controller
+	UserID string `json:"-"`
+} //@name GetNotificationChannelRequest
+
+// DeleteNotificationChannelRequest represents the request to delete a notification channel
+type DeleteNotificationChannelRequest struct {
+	// Unique identifier for the channel
+	ChannelID string `uri:"channel_id" validate:"required" example:"chan-12345-abcde"`
+
+	// Authenticated caller, set by the controller
+	UserID string `json:"-"`
+} //@name DeleteNotificationChannelRequest
+
+// ListNotificationChannelsRequest represents the request to list the caller's notification channels
+type ListNotificationChannelsRequest struct {
+	// Only list channels of this project
+	ProjectID string `form:"project_id" validate:"omitempty,project_id" example:"proj-12345-abcde"`
+
+	// Authenticated caller, set by the controller
+	UserID string `json:"-"`
+} //@name ListNotificationChannelsRequest
+
+// NotificationChannelResponse describes a notification channel without its Slack credentials
+type NotificationChannelResponse struct {
+	// Unique identifier for the channel
+	ChannelID string `json:"channel_id" example:"chan-12345-abcde"`
+	// Delivery method
+	Type NotificationChannelType `json:"type" example:"slack"`
+	// Human-readable channel name
+	Name string `json:"name" example:"Refactoring alerts"`
+	// Project whose events are delivered, absent for every project
+	ProjectID *string `json:"project_id,omitempty" example:"proj-12345-abcde"`
+	// Events delivered
+	Events []NotificationEvent `json:"events" example:"task.failed"`
+	// Whether the channel delivers notifications
+	Enabled bool `json:"enabled" example:"true"`
+	// Recipient of email channels
+	Email *string `json:"email,omitempty" example:"dev@example.com"`
+	// Slack channel the bot posts to, absent for incoming webhooks
+	SlackChannel *string `json:"slack_channel,omitempty" example:"#refactoring-alerts"`
+	// Creation timestamp
+	CreatedAt time.Time `json:"created_at" example:"2024-01-15T10:30:00Z"`
+	// Last update timestamp
+	UpdatedAt time.Time `json:"updated_at" example:"2024-01-15T10:30:00Z"`
+} //@name NotificationChannelResponse
+
+// NewNotificationChannelResponse converts a channel to its response, leaving out the Slack webhook URL and bot token
+func NewNotificationChannelResponse(channel *NotificationChannel) *NotificationChannelResponse {
+	response := &NotificationChannelResponse{
+		ChannelID: channel.ChannelID,
+		Type:      channel.Type,
+		Name:      channel.Name,
+		ProjectID: channel.ProjectID,
+		Events:    channel.Events,
+		Enabled:   channel.Enabled,
+		Email:     channel.Email,
+		CreatedAt: channel.CreatedAt,
+		UpdatedAt: channel.UpdatedAt,
+	}
+	if channel.Slack != nil && channel.Slack.Channel != "" {
+		slackChannel := channel.Slack.Channel
+		response.SlackChannel = &slackChannel
+	}
+	return response
+}
+
+// ListNotificationChannelsResponse represents the response when listing notification channels
+type ListNotificationChannelsResponse struct {
+	// Notification channels
+	Channels []NotificationChannelResponse `json:"channels"`
+} //@name ListNotificationChannelsResponse
+
+// DeleteNotificationChannelResponse represents the response when deleting a notification channel
+type DeleteNotificationChannelResponse struct {
+	// Whether the deletion was successful
+	Success bool `json:"success" example:"true"`
+} //@name DeleteNotificationChannelResponse
+
+// UserNotification is an entry in a user's in-app notification inbox
+type UserNotification struct {
+	// Unique identifier for the notification
+	NotificationID string `json:"notification_id" db:"notification_id" example:"notif-12345-abcde"`
+	// User whose inbox holds the notification
+	UserID string `json:"-" db:"user_id"`
+	// Event the notification is about
+	Event NotificationEvent `json:"event" db:"event" example:"task.completed"`
+	// Short summary of the event
+	Title string `json:"title" db:"title" example:"Task completed: Refactor payments"`
+	// Details of the event
+	Message string `json:"message" db:"message" example:"Task task-123 in project proj-12345-abcde completed."`
+	// Project the event belongs to
+	ProjectID *string `json:"project_id,omitempty" db:"project_id" example:"proj-12345-abcde"`
+	// Task, project or user the event is about
+	ResourceID *string `json:"resource_id,omitempty" db:"resource_id" example:"task-123"`
+	// When the notification was marked read, absent while unread
+	ReadAt *time.Time `json:"read_at,omitempty" db:"read_at" example:"2024-01-15T11:00:00Z"`
+	// Creation timestamp
+	CreatedAt time.Time `json:"created_at" db:"created_at" example:"2024-01-15T10:30:00Z"`
+} //@name UserNotification
+
+// ListNotificationsRequest represents the request to list the caller's inbox notifications, newest first
+type ListNotificationsRequest struct {
+	// Only list unread notifications
+	UnreadOnly bool `form:"unread_only" example:"true"`
+	// Token for pagination
+	NextToken *string `form:"next_token,omitempty" validate:"omitempty,min=1" example:"notif-12345-abcde"`
+	// Maximum number of results to return
+	MaxResults *int `form:"max_results,omitempty" validate:"omitempty,min=1,max=100" example:"20"`
+
+	// Authenticated caller, set by the controller
+	UserID string `json:"-"`
+} //@name ListNotificationsRequest
+
+// ListNotificationsResponse represents the response when listing inbox notifications
+type ListNotificationsResponse struct {
+	// Notifications, newest first
+	Notifications []UserNotification `json:"notifications"`
+	// Number of unread notifications in the whole inbox
+	UnreadCount int `json:"unread_count" example:"3"`
+	// Token for next page of results
+	NextToken *string `json:"next_token,omitempty" example:"notif-67890-fghij"`
+} //@name ListNotificationsResponse
+
+// MarkNotificationReadRequest represents the request to mark an inbox notification as read
+type MarkNotificationReadRequest struct {
+	// Unique identifier for the notification
+	NotificationID string `uri:"notification_id" validate:"required" example:"notif-12345-abcde"`
+
+	// Authenticated caller, set by the controller
+	UserID string `json:"-"`
+} //@name MarkNotificationReadRequest
+
+// MarkNotificationReadResponse represents the response when marking an inbox notification as read
+type MarkNotificationReadResponse struct {
+	// Whether the notification was marked read
+	Success bool `json:"success" example:"true"`
+	// Number of unread notifications left in the inbox
+	UnreadCount int `json:"unread_count" example:"2"`
+} //@name MarkNotificationReadResponse
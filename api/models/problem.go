@@ -0,0 +1,24 @@
+// Package models provides data structures for RFC 7807 error responses
+package models
+
+// ProblemContentType is the media type of ProblemDetails responses
+const ProblemContentType = "application/problem+json"
+
+// ProblemTypeBaseURI prefixes the error code to form the problem type URI
+const ProblemTypeBaseURI = "https://refactor.tool/problems/"
+
+// ProblemDetails represents an RFC 7807 problem details error response
+type ProblemDetails struct {
+	// URI reference identifying the problem type
+	Type string `json:"type" example:"https://refactor.tool/problems/project_not_found"`
+	// Short, human-readable summary of the problem type
+	Title string `json:"title" example:"Not Found"`
+	// HTTP status code
+	Status int `json:"status" example:"404"`
+	// Human-readable explanation specific to this occurrence
+	Detail string `json:"detail,omitempty" example:"project not found"`
+	// URI reference identifying this occurrence of the problem
+	Instance string `json:"instance,omitempty" example:"/api/v1/projects/proj-12345-abcde"`
+	// Stable machine-readable error code
+	Code string `json:"code" example:"project_not_found"`
+} //@name ProblemDetails
@@ -53,6 +53,16 @@ type CreateProjectRequest struct {
 	Language *string `json:"language,omitempty" validate:"omitempty,oneof=go javascript typescript python java csharp rust cpp c ruby php kotlin swift scala other" example:"go"`
 	// Optional user-defined key-value tags
 	Tags map[string]string `json:"tags,omitempty" validate:"omitempty,max=10,dive,keys,min=1,max=50,endkeys,min=1,max=100" example:"env:prod,team:backend"`
+	// Optional backend storing the embeddings of the project's knowledge bases, the configured default when empty
+	VectorStore string `json:"vector_store,omitempty" validate:"omitempty,oneof=pgvector opensearch qdrant" example:"qdrant"`
+	// Optional Bedrock embedding model of the project's knowledge bases, the default model when empty
+	EmbeddingModel string `json:"embedding_model,omitempty" validate:"omitempty,max=128" example:"amazon.titan-embed-text-v2:0"`
+	// Optional number of dimensions of the vectors the embedding model produces, the model's default when zero
+	EmbeddingDimensions int32 `json:"embedding_dimensions,omitempty" validate:"omitempty,min=1" example:"1024"`
+	// Optional token unique to the request, retries carrying it return the project it created instead of creating another
+	ClientToken string `json:"client_token,omitempty" validate:"omitempty,max=64" example:"3f2b8c1e-9d4a-4e7b-a6f0-2c5d8e1b7a93"`
+	// Authenticated caller, set by the controller
+	UserID string `json:"-"`
 } //@name CreateProjectRequest
 
 // CreateProjectResponse represents the response when creating a project
@@ -79,14 +89,24 @@ type GetProjectResponse struct {
 	Description *string `json:"description,omitempty" example:"A sample project for code analysis"`
 	// Optional programming language
 	Language *string `json:"language,omitempty" example:"go"`
+	// Lifecycle status, archived projects reject new tasks and agent syncs
+	Status ProjectStatus `json:"status" example:"active"`
 	// Timestamp when the project was created
 	CreatedAt string `json:"created_at" example:"2024-01-15T10:30:00Z"`
 	// Timestamp when the project was last updated
 	UpdatedAt string `json:"updated_at" example:"2024-01-15T10:30:00Z"`
+	// Version incremented on every update, also returned as the ETag header
+	Version int64 `json:"version" example:"3"`
 	// Optional user-defined key-value tags
 	Tags map[string]string `json:"tags,omitempty" example:"env:prod,team:backend"`
 	// Optional metadata
 	Metadata map[string]string `json:"metadata,omitempty" example:"version:1.0.0"`
+	// Backend storing the embeddings of the project's knowledge bases, the configured default when empty
+	VectorStore string `json:"vector_store,omitempty" example:"qdrant"`
+	// Bedrock embedding model of the project's knowledge bases, the default model when empty
+	EmbeddingModel string `json:"embedding_model,omitempty" example:"amazon.titan-embed-text-v2:0"`
+	// Number of dimensions of the vectors the embedding model produces, the model's default when zero
+	EmbeddingDimensions int32 `json:"embedding_dimensions,omitempty" example:"1024"`
 } //@name GetProjectResponse
 
 // UpdateProjectRequest represents the request to update a project
@@ -103,6 +123,18 @@ type UpdateProjectRequest struct {
 	Tags map[string]string `json:"tags,omitempty" validate:"omitempty,max=10,dive,keys,min=1,max=50,endkeys,min=1,max=100" example:"env:staging,team:frontend"`
 	// Optional metadata
 	Metadata map[string]string `json:"metadata,omitempty" validate:"omitempty,max=20,dive,keys,min=1,max=100,endkeys,min=1,max=500" example:"version:1.1.0"`
+	// Optional backend storing the embeddings of the project's knowledge bases. Vectors already stored aren't moved,
+	// cmd/vectormigrate copies them to the new backend.
+	VectorStore *string `json:"vector_store,omitempty" validate:"omitempty,oneof=pgvector opensearch qdrant" example:"opensearch"`
+	// Optional Bedrock embedding model of the project's knowledge bases. Switching it re-embeds the project's Bedrock
+	// agents embedding with another model, rebuilding their knowledge bases.
+	EmbeddingModel *string `json:"embedding_model,omitempty" validate:"omitempty,max=128" example:"amazon.titan-embed-text-v2:0"`
+	// Optional number of dimensions of the vectors the embedding model produces, the model's default when zero
+	EmbeddingDimensions *int32 `json:"embedding_dimensions,omitempty" validate:"omitempty,min=0" example:"512"`
+	// Version the client last read, taken from the If-Match header
+	ExpectedVersion int64 `json:"-"`
+	// Authenticated caller, set by the controller
+	UserID string `json:"-"`
 } //@name UpdateProjectRequest
 
 // UpdateProjectResponse represents the response when updating a project
@@ -111,6 +143,10 @@ type UpdateProjectResponse struct {
 	ProjectID string `json:"project_id" example:"12345-abcde"`
 	// Timestamp when the project was last updated
 	UpdatedAt string `json:"updated_at" example:"2024-01-15T11:30:00Z"`
+	// Version of the project after the update
+	Version int64 `json:"version" example:"4"`
+	// Agents rebuilt to embed with the project's new embedding model
+	ReembeddedAgentIDs []string `json:"reembedded_agent_ids,omitempty" example:"agent-12345"`
 } //@name UpdateProjectResponse
 
 // DeleteProjectRequest represents the request to delete a project
@@ -133,6 +169,8 @@ type ListProjectsRequest struct {
 	MaxResults *int `form:"max_results,omitempty" validate:"omitempty,min=1,max=100" example:"50"`
 	// Optional tag filter - projects must match all provided tags
 	TagFilter map[string]string `form:"tag_filter,omitempty" validate:"omitempty,max=10,dive,keys,min=1,max=50,endkeys,min=1,max=100" example:"env:prod"`
+	// Whether archived projects are listed too
+	IncludeArchived bool `form:"include_archived,omitempty" example:"true"`
 } //@name ListProjectsRequest
 
 // ListProjectsResponse represents the response when listing projects
@@ -149,8 +187,38 @@ type ProjectSummary struct {
 	ProjectID string `json:"project_id" example:"12345-abcde"`
 	// Human-readable project name
 	Name string `json:"name" example:"my-project"`
+	// Lifecycle status of the project
+	Status ProjectStatus `json:"status" example:"active"`
 	// Timestamp when the project was created
 	CreatedAt string `json:"created_at" example:"2024-01-15T10:30:00Z"`
 	// Optional user-defined key-value tags
 	Tags map[string]string `json:"tags,omitempty" example:"env:prod,team:backend"`
 } //@name ProjectSummary
+
+// ArchiveProjectRequest represents the request to archive a project
+type ArchiveProjectRequest struct {
+	// Unique identifier for the project
+	ProjectID string `uri:"project_id" validate:"required,project_id" example:"12345-abcde"`
+	// Whether the knowledge bases, vector stores and S3 data of the project's agents are deleted along with the agents
+	PurgeArtifacts bool `form:"purge_artifacts,omitempty" example:"false"`
+} //@name ArchiveProjectRequest
+
+// UnarchiveProjectRequest represents the request to make an archived project active again
+type UnarchiveProjectRequest struct {
+	// Unique identifier for the project
+	ProjectID string `uri:"project_id" validate:"required,project_id" example:"12345-abcde"`
+} //@name UnarchiveProjectRequest
+
+// ProjectLifecycleResponse represents the response when archiving or unarchiving a project
+type ProjectLifecycleResponse struct {
+	// Unique identifier for the project
+	ProjectID string `json:"project_id" example:"12345-abcde"`
+	// Lifecycle status of the project after the change
+	Status ProjectStatus `json:"status" example:"archived"`
+	// Timestamp when the project was last updated
+	UpdatedAt string `json:"updated_at" example:"2024-01-15T11:30:00Z"`
+	// Version of the project after the change
+	Version int64 `json:"version" example:"4"`
+	// Agents deleted with their AI resources when the archive purged the project's artifacts
+	PurgedAgentIDs []string `json:"purged_agent_ids,omitempty" example:"agent-12345"`
+} //@name ProjectLifecycleResponse
@@ -0,0 +1,75 @@
+// Package models provides data structures for declarative project manifests
+package models
+
+import (
+	"time"
+)
+
+// ProjectManifestAPIVersion is the manifest schema version produced by export and accepted by import
+const ProjectManifestAPIVersion = "refactor.tool/v1"
+
+// ProjectManifestKind is the manifest kind for project exports
+const ProjectManifestKind = "Project"
+
+// ProjectAutomation holds the scheduled analyses and webhooks configured for a project
+type ProjectAutomation struct {
+	ProjectID         string                `json:"project_id" db:"project_id"`
+	ScheduledAnalyses []ScheduledAnalysis   `json:"scheduled_analyses,omitempty" db:"scheduled_analyses"`
+	Webhooks          []WebhookRegistration `json:"webhooks,omitempty" db:"webhooks"`
+	UpdatedAt         time.Time             `json:"updated_at" db:"updated_at"`
+}
+
+// ProjectManifest is a declarative description of a project and its resources.
+// Codebases reference their codebase configuration by ID; credentials are never included.
+type ProjectManifest struct {
+	APIVersion string                `json:"api_version" yaml:"api_version" validate:"required,eq=refactor.tool/v1" example:"refactor.tool/v1"`
+	Kind       string                `json:"kind" yaml:"kind" validate:"required,eq=Project" example:"Project"`
+	Project    ProjectManifestSpec   `json:"project" yaml:"project" validate:"required"`
+	Codebases  []CodebaseManifest    `json:"codebases,omitempty" yaml:"codebases,omitempty" validate:"omitempty,max=50,dive"`
+	Agents     []AgentManifest       `json:"agents,omitempty" yaml:"agents,omitempty" validate:"omitempty,max=20,dive"`
+	Schedules  []ScheduledAnalysis   `json:"schedules,omitempty" yaml:"schedules,omitempty" validate:"omitempty,max=20,dive"`
+	Webhooks   []WebhookRegistration `json:"webhooks,omitempty" yaml:"webhooks,omitempty" validate:"omitempty,max=10,dive"`
+	UserID     string                `json:"-" yaml:"-"` // Authenticated caller importing the manifest, set by the controller
+} //@name ProjectManifest
+
+// ProjectManifestSpec describes the project itself in a manifest
+type ProjectManifestSpec struct {
+	Name        string            `json:"name" yaml:"name" validate:"required,min=1,max=100" example:"payments-service"`
+	Description *string           `json:"description,omitempty" yaml:"description,omitempty" validate:"omitempty,max=500"`
+	Language    *string           `json:"language,omitempty" yaml:"language,omitempty" validate:"omitempty,oneof=go javascript typescript python java csharp rust cpp c ruby php kotlin swift scala other" example:"go"`
+	Tags        map[string]string `json:"tags,omitempty" yaml:"tags,omitempty" validate:"omitempty,max=10,dive,keys,min=1,max=50,endkeys,min=1,max=100"`
+	Metadata    map[string]string `json:"metadata,omitempty" yaml:"metadata,omitempty" validate:"omitempty,max=20,dive,keys,min=1,max=100,endkeys,min=1,max=500"`
+} //@name ProjectManifestSpec
+
+// CodebaseManifest describes a codebase in a manifest
+type CodebaseManifest struct {
+	Name     string   `json:"name" yaml:"name" validate:"required,min=1,max=255" example:"payments"`
+	Provider Provider `json:"provider" yaml:"provider" validate:"required,provider" example:"github"`
+	URL      string   `json:"url" yaml:"url" validate:"required,url,max=2048" example:"https://github.com/acme/payments.git"`
+	// ConfigRef is the ID of the codebase configuration holding the credentials
+	ConfigRef string            `json:"config_ref" yaml:"config_ref" validate:"required,config_id" example:"config-12345-abcde"`
+	Tags      map[string]string `json:"tags,omitempty" yaml:"tags,omitempty" validate:"omitempty,dive,keys,min=1,max=64,endkeys,min=1,max=255"`
+} //@name CodebaseManifest
+
+// AgentManifest describes an agent in a manifest
+type AgentManifest struct {
+	Name          string     `json:"name,omitempty" yaml:"name,omitempty" validate:"omitempty,min=1" example:"payments-analyzer"`
+	RepositoryURL string     `json:"repository_url" yaml:"repository_url" validate:"required,url" example:"https://github.com/acme/payments.git"`
+	Branch        string     `json:"branch,omitempty" yaml:"branch,omitempty" validate:"omitempty,min=1" example:"main"`
+	AIProvider    AIProvider `json:"ai_provider,omitempty" yaml:"ai_provider,omitempty" validate:"omitempty,oneof=bedrock local openai" example:"bedrock"`
+} //@name AgentManifest
+
+// ExportProjectRequest represents the request to export a project manifest
+type ExportProjectRequest struct {
+	ProjectID string `uri:"project_id" validate:"required,project_id" example:"proj-12345-abcde"`
+} //@name ExportProjectRequest
+
+// ImportProjectResponse represents the resources created by a manifest import
+type ImportProjectResponse struct {
+	ProjectID   string   `json:"project_id" example:"proj-12345-abcde"`
+	CodebaseIDs []string `json:"codebase_ids"`
+	AgentIDs    []string `json:"agent_ids"`
+	// Warnings lists resources that could not be recreated; the rest of the import still succeeded
+	Warnings  []string `json:"warnings,omitempty"`
+	CreatedAt string   `json:"created_at" example:"2024-01-15T10:30:00Z"`
+} //@name ImportProjectResponse
@@ -0,0 +1,67 @@
+// Package models provides data structures for the project summary dashboard
+package models
+
+// DefaultSummaryWindowDays is the task statistics window used when none is requested
+const DefaultSummaryWindowDays = 7
+
+// GetProjectSummaryRequest represents the request for a project's dashboard summary
+type GetProjectSummaryRequest struct {
+	// Unique identifier for the project
+	ProjectID string `uri:"project_id" validate:"required,project_id" example:"proj-12345-abcde"`
+	// Number of days of task activity to aggregate
+	WindowDays int `form:"window_days" validate:"omitempty,min=1,max=90" example:"7"`
+} //@name GetProjectSummaryRequest
+
+// GetProjectSummaryResponse aggregates everything a project dashboard needs in one response
+type GetProjectSummaryResponse struct {
+	// Unique identifier for the project
+	ProjectID string `json:"project_id" example:"proj-12345-abcde"`
+	// Start of the task statistics window
+	WindowStart string `json:"window_start" example:"2024-01-08T10:30:00Z"`
+	// End of the task statistics window
+	WindowEnd string `json:"window_end" example:"2024-01-15T10:30:00Z"`
+	// Task counts for tasks created within the window
+	Tasks TaskStatistics `json:"tasks"`
+	// Health of the project's codebases
+	Codebases CodebaseHealth `json:"codebases"`
+	// Most recent time one of the project's codebases was synced to its agent
+	LastAgentSyncAt *string `json:"last_agent_sync_at,omitempty" example:"2024-01-15T09:00:00Z"`
+	// Most recently failed tasks within the window, newest first
+	RecentFailures []TaskFailure `json:"recent_failures"`
+} //@name GetProjectSummaryResponse
+
+// TaskStatistics contains task counts grouped by status and by type
+type TaskStatistics struct {
+	// Total number of tasks
+	Total int `json:"total" example:"42"`
+	// Number of tasks per status
+	ByStatus map[string]int `json:"by_status" example:"completed:30,failed:2,pending:10"`
+	// Number of tasks per type
+	ByType map[string]int `json:"by_type" example:"code_review:12,refactoring:30"`
+} //@name TaskStatistics
+
+// CodebaseHealth contains codebase counts grouped by status
+type CodebaseHealth struct {
+	// Total number of codebases
+	Total int `json:"total" example:"3"`
+	// Number of codebases per status
+	ByStatus map[string]int `json:"by_status" example:"active:2,sync_failed:1"`
+	// Whether every codebase is active or syncing
+	Healthy bool `json:"healthy" example:"false"`
+} //@name CodebaseHealth
+
+// TaskFailure describes a failed task shown on the dashboard
+type TaskFailure struct {
+	// Unique identifier for the task
+	TaskID string `json:"task_id" example:"task-12345-abcde"`
+	// Agent that ran the task
+	AgentID string `json:"agent_id" example:"agent-12345"`
+	// Type of the task
+	Type TaskType `json:"type" example:"refactoring"`
+	// Title of the task
+	Title string `json:"title" example:"Refactor payment module"`
+	// Error reported by the task
+	ErrorMessage *string `json:"error_message,omitempty" example:"agent timed out"`
+	// Time the task failed
+	FailedAt string `json:"failed_at" example:"2024-01-15T08:00:00Z"`
+} //@name TaskFailure
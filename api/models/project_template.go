@@ -0,0 +1,112 @@
+// Package models provides data structures for project templates
+package models
+
+import (
+	"time"
+)
+
+// ProjectTemplate bundles the defaults used to bootstrap a new project
+type ProjectTemplate struct {
+	TemplateID        string                   `json:"template_id" db:"template_id"`
+	Name              string                   `json:"name" db:"name"`
+	Description       string                   `json:"description,omitempty" db:"description"`
+	Language          *string                  `json:"language,omitempty" db:"language"`
+	BuiltIn           bool                     `json:"built_in" db:"-"`
+	DefaultTags       map[string]string        `json:"default_tags,omitempty" db:"default_tags"`
+	CodebaseConfigs   []CodebaseConfigSkeleton `json:"codebase_configs,omitempty" db:"codebase_configs"`
+	PromptTemplates   []PromptTemplate         `json:"prompt_templates,omitempty" db:"prompt_templates"`
+	Webhooks          []WebhookRegistration    `json:"webhooks,omitempty" db:"webhooks"`
+	ScheduledAnalyses []ScheduledAnalysis      `json:"scheduled_analyses,omitempty" db:"scheduled_analyses"`
+	CreatedAt         time.Time                `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time                `json:"updated_at" db:"updated_at"`
+} //@name ProjectTemplate
+
+// CodebaseConfigSkeleton describes a codebase configuration to be completed after bootstrap
+type CodebaseConfigSkeleton struct {
+	Name          string            `json:"name" validate:"required,min=1,max=100" example:"service-repo"`
+	Provider      Provider          `json:"provider" validate:"required,provider" example:"github"`
+	Description   *string           `json:"description,omitempty" validate:"omitempty,max=500" example:"Main service repository"`
+	DefaultBranch string            `json:"default_branch,omitempty" validate:"omitempty,max=255" example:"main"`
+	Tags          map[string]string `json:"tags,omitempty" validate:"omitempty,max=10,dive,keys,min=1,max=50,endkeys,min=1,max=100"`
+} //@name CodebaseConfigSkeleton
+
+// PromptTemplate is a reusable task prompt shipped with a project template
+type PromptTemplate struct {
+	Name        string   `json:"name" validate:"required,min=1,max=100" example:"error-handling-review"`
+	TaskType    TaskType `json:"task_type" validate:"required,oneof=code_analysis refactoring code_review documentation custom dependency_audit codemod coverage_gap" example:"code_review"`
+	Title       string   `json:"title" validate:"required,min=1,max=200" example:"Review error handling"`
+	Description string   `json:"description" validate:"required,min=1,max=2000" example:"Review the code for swallowed errors and missing context"`
+} //@name PromptTemplate
+
+// WebhookRegistration describes a webhook to notify on project events
+type WebhookRegistration struct {
+	URL    string   `json:"url" yaml:"url" validate:"required,url,max=2048" example:"https://hooks.example.com/refactor"`
+	Events []string `json:"events" yaml:"events" validate:"required,min=1,dive,min=1,max=100" example:"task.completed,task.failed"`
+} //@name WebhookRegistration
+
+// ScheduledAnalysis describes a recurring task to run against the project
+type ScheduledAnalysis struct {
+	Name     string   `json:"name" yaml:"name" validate:"required,min=1,max=100" example:"nightly-lint"`
+	Schedule string   `json:"schedule" yaml:"schedule" validate:"required,min=1,max=100" example:"0 3 * * *"` // Cron expression
+	TaskType TaskType `json:"task_type" yaml:"task_type" validate:"required,oneof=code_analysis refactoring code_review documentation custom dependency_audit codemod coverage_gap" example:"code_analysis"`
+	Prompt   string   `json:"prompt" yaml:"prompt" validate:"required,min=1,max=2000" example:"Run a full static analysis and summarize new findings"`
+} //@name ScheduledAnalysis
+
+// CreateProjectTemplateRequest represents the request to define a custom project template
+type CreateProjectTemplateRequest struct {
+	Name              string                   `json:"name" validate:"required,min=1,max=100" example:"Internal Go service"`
+	Description       string                   `json:"description,omitempty" validate:"omitempty,max=500" example:"Defaults for our Go services"`
+	Language          *string                  `json:"language,omitempty" validate:"omitempty,oneof=go javascript typescript python java csharp rust cpp c ruby php kotlin swift scala other" example:"go"`
+	DefaultTags       map[string]string        `json:"default_tags,omitempty" validate:"omitempty,max=10,dive,keys,min=1,max=50,endkeys,min=1,max=100"`
+	CodebaseConfigs   []CodebaseConfigSkeleton `json:"codebase_configs,omitempty" validate:"omitempty,max=20,dive"`
+	PromptTemplates   []PromptTemplate         `json:"prompt_templates,omitempty" validate:"omitempty,max=50,dive"`
+	Webhooks          []WebhookRegistration    `json:"webhooks,omitempty" validate:"omitempty,max=10,dive"`
+	ScheduledAnalyses []ScheduledAnalysis      `json:"scheduled_analyses,omitempty" validate:"omitempty,max=20,dive"`
+} //@name CreateProjectTemplateRequest
+
+// CreateProjectTemplateResponse represents the response when creating a project template
+type CreateProjectTemplateResponse struct {
+	TemplateID string `json:"template_id" example:"tmpl-12345-abcde"`
+	CreatedAt  string `json:"created_at" example:"2024-01-15T10:30:00Z"`
+} //@name CreateProjectTemplateResponse
+
+// GetProjectTemplateRequest represents the request to get a project template
+type GetProjectTemplateRequest struct {
+	TemplateID string `uri:"template_id" validate:"required" example:"go-microservice"`
+} //@name GetProjectTemplateRequest
+
+// ListProjectTemplatesRequest represents the request to list project templates
+type ListProjectTemplatesRequest struct {
+	// No parameters needed for listing templates
+} //@name ListProjectTemplatesRequest
+
+// ListProjectTemplatesResponse represents the response when listing project templates
+type ListProjectTemplatesResponse struct {
+	Templates []ProjectTemplate `json:"templates"`
+} //@name ListProjectTemplatesResponse
+
+// CreateProjectFromTemplateRequest represents the request to bootstrap a project from a template
+type CreateProjectFromTemplateRequest struct {
+	// Built-in or custom template identifier
+	TemplateID string `json:"template_id" validate:"required,min=1,max=100" example:"go-microservice"`
+	// Human-readable project name
+	Name string `json:"name" validate:"required,min=1,max=100" example:"payments-service"`
+	// Optional project summary
+	Description *string `json:"description,omitempty" validate:"omitempty,max=500" example:"Payments microservice"`
+	// Optional tags, merged over the template's default tags
+	Tags map[string]string `json:"tags,omitempty" validate:"omitempty,max=10,dive,keys,min=1,max=50,endkeys,min=1,max=100" example:"team:payments"`
+	// Authenticated caller, set by the controller
+	UserID string `json:"-"`
+} //@name CreateProjectFromTemplateRequest
+
+// CreateProjectFromTemplateResponse represents the bootstrapped project and the template defaults applied to it
+type CreateProjectFromTemplateResponse struct {
+	ProjectID         string                   `json:"project_id" example:"proj-12345-abcde"`
+	TemplateID        string                   `json:"template_id" example:"go-microservice"`
+	CreatedAt         string                   `json:"created_at" example:"2024-01-15T10:30:00Z"`
+	Tags              map[string]string        `json:"tags,omitempty"`
+	CodebaseConfigs   []CodebaseConfigSkeleton `json:"codebase_configs,omitempty"`
+	PromptTemplates   []PromptTemplate         `json:"prompt_templates,omitempty"`
+	Webhooks          []WebhookRegistration    `json:"webhooks,omitempty"`
+	ScheduledAnalyses []ScheduledAnalysis      `json:"scheduled_analyses,omitempty"`
+} //@name CreateProjectFromTemplateResponse
@@ -0,0 +1,130 @@
+// Package models provides data structures for the severity policies and quality gates of projects
+package models
+
+import "time"
+
+// FindingSeverity is how urgently an analysis finding should be fixed
+type FindingSeverity string
+
+const (
+	// FindingSeverityLow is a finding worth fixing when the code is next touched, such as dead code
+	FindingSeverityLow FindingSeverity = "low"
+
+	// FindingSeverityMedium is a finding that makes the code harder to maintain, such as duplicated code
+	FindingSeverityMedium FindingSeverity = "medium"
+
+	// FindingSeverityHigh is a finding that should be fixed soon, such as an import cycle
+	FindingSeverityHigh FindingSeverity = "high"
+
+	// FindingSeverityCritical is a finding that must be fixed before merging, such as a build failure
+	FindingSeverityCritical FindingSeverity = "critical"
+)
+
+// QualityGateMetric is what a quality gate measures after an analysis
+type QualityGateMetric string
+
+const (
+	// QualityGateMetricNewFindings counts the findings the analysis opened or reopened
+	QualityGateMetricNewFindings QualityGateMetric = "new_findings"
+
+	// QualityGateMetricOpenFindings counts the open findings of the codebase after the analysis
+	QualityGateMetricOpenFindings QualityGateMetric = "open_findings"
+
+	// QualityGateMetricComplexityIncrease is how much the average cyclomatic complexity grew since the previous snapshot
+	QualityGateMetricComplexityIncrease QualityGateMetric = "complexity_increase"
+
+	// QualityGateMetricMaintainabilityDecrease is how much the maintainability score dropped since the previous snapshot
+	QualityGateMetricMaintainabilityDecrease QualityGateMetric = "maintainability_decrease"
+)
+
+// QualityGateOutcome is the result of evaluating a quality gate
+type QualityGateOutcome string
+
+const (
+	// QualityGateOutcomePassed means the measured value is within the threshold
+	QualityGateOutcomePassed QualityGateOutcome = "passed"
+
+	// QualityGateOutcomeFailed means the measured value is above the threshold
+	QualityGateOutcomeFailed QualityGateOutcome = "failed"
+
+	// QualityGateOutcomeSkipped means the analysis measured nothing the gate checks, such as the complexity of a
+	// codebase without an earlier snapshot
+	QualityGateOutcomeSkipped QualityGateOutcome = "skipped"
+)
+
+// SeverityRule sets the severity of the findings of a source and rule. Empty fields match any source or rule.
+type SeverityRule struct {
+	// Analysis mode reporting the findings, or manual
+	Source string `json:"source,omitempty" validate:"omitempty,max=64" example:"dead_code"`
+	// Violated rule
+	RuleID string `json:"rule_id,omitempty" validate:"omitempty,max=255" example:"unreachable-code"`
+	// Severity of the matching findings
+	Severity FindingSeverity `json:"severity" validate:"required,oneof=low medium high critical" example:"high"`
+} //@name SeverityRule
+
+// QualityGate is a condition the analyses of a project's codebases must meet
+type QualityGate struct {
+	// Name shown in gate results and check runs
+	Name string `json:"name" validate:"required,max=100" example:"No new critical findings"`
+	// What the gate measures
+	Metric QualityGateMetric `json:"metric" validate:"required,oneof=new_findings open_findings complexity_increase maintainability_decrease" example:"new_findings"`
+	// Lowest severity of the findings counted by finding metrics, defaults to low
+	MinSeverity FindingSeverity `json:"min_severity,omitempty" validate:"omitempty,oneof=low medium high critical" example:"critical"`
+	// Highest value passing the gate
+	Threshold float64 `json:"threshold" validate:"min=0" example:"0"`
+} //@name QualityGate
+
+// QualityGatePolicy holds the severity rules and quality gates of a project
+type QualityGatePolicy struct {
+	// Project the policy applies to
+	ProjectID string `json:"project_id" db:"project_id" example:"proj-12345-abcde"`
+	// Rules setting the severity of findings, the first matching rule wins; findings no rule matches get the default
+	// severity of their kind
+	SeverityRules []SeverityRule `json:"severity_rules" db:"severity_rules"`
+	// Gates evaluated after each analysis
+	Gates []QualityGate `json:"gates" db:"gates"`
+	// Whether the project uses the default policy, without rules or gates, because none was configured
+	IsDefault bool `json:"is_default" example:"false"`
+	// Last update timestamp, absent for the default policy
+	UpdatedAt *time.Time `json:"updated_at,omitempty" db:"updated_at" example:"2024-01-15T10:30:00Z"`
+} //@name QualityGatePolicy
+
+// GetQualityGatePolicyRequest represents the request to get the quality gate policy of a project
+type GetQualityGatePolicyRequest struct {
+	// Project ID
+	ProjectID string `uri:"project_id" validate:"required,project_id" example:"proj-12345-abcde"`
+} //@name GetQualityGatePolicyRequest
+
+// UpdateQualityGatePolicyRequest represents the request to replace the quality gate policy of a project
+type UpdateQualityGatePolicyRequest struct {
+	// Project ID
+	ProjectID string `uri:"project_id" validate:"required,project_id" example:"proj-12345-abcde"`
+	// Severity rules, replacing the current ones
+	SeverityRules []SeverityRule `json:"severity_rules" validate:"omitempty,max=100,dive"`
+	// Quality gates, replacing the current ones
+	Gates []QualityGate `json:"gates" validate:"omitempty,max=20,dive"`
+} //@name UpdateQualityGatePolicyRequest
+
+// QualityGateResult is the result of one quality gate after an analysis
+type QualityGateResult struct {
+	// Name of the gate
+	Name string `json:"name" example:"No new critical findings"`
+	// What the gate measured
+	Metric QualityGateMetric `json:"metric" example:"new_findings"`
+	// Highest value passing the gate
+	Threshold float64 `json:"threshold" example:"0"`
+	// Measured value, absent when the gate was skipped
+	Value *float64 `json:"value,omitempty" example:"2"`
+	// Whether the gate passed
+	Outcome QualityGateOutcome `json:"outcome" example:"failed"`
+	// Explanation of the outcome
+	Message string `json:"message" example:"2 new findings of critical severity, at most 0 allowed"`
+} //@name QualityGateResult
+
+// QualityGateReport holds the results of a project's quality gates after an analysis, recorded on the task
+type QualityGateReport struct {
+	// Whether no gate failed
+	Passed bool `json:"passed" example:"false"`
+	// Result of each gate, in the order of the policy
+	Gates []QualityGateResult `json:"gates"`
+} //@name QualityGateReport
@@ -0,0 +1,115 @@
+// Package models provides data structures for the redaction of repository content before it is embedded
+package models
+
+import "time"
+
+// RedactionOperation is the agent operation that synced a repository into a knowledge base
+type RedactionOperation string
+
+const (
+	// RedactionOperationCreate is the first sync of a new agent
+	RedactionOperationCreate RedactionOperation = "create"
+	// RedactionOperationUpdate is a sync caused by an agent configuration change
+	RedactionOperationUpdate RedactionOperation = "update"
+	// RedactionOperationRebuild is a sync re-provisioning an agent with its current configuration
+	RedactionOperationRebuild RedactionOperation = "rebuild"
+)
+
+// RedactionPattern is a named regular expression masked in repository content
+type RedactionPattern struct {
+	// Name counted in redaction audits and shown in masks such as [REDACTED:customer-id]
+	Name string `json:"name" validate:"required,max=64" example:"customer-id"`
+	// Regular expression in RE2 syntax
+	Expression string `json:"expression" validate:"required,max=1024" example:"CUST-[0-9]{6}"`
+} //@name RedactionPattern
+
+// NoiseFileFilter selects the noise files left out of a project's knowledge bases and task prompts. Files are
+// detected by their content, so renamed lockfiles and generated code without a conventional suffix are caught too.
+type NoiseFileFilter struct {
+	// Kinds of noise files left out: binary, minified, lockfile or generated
+	ExcludedKinds []string `json:"excluded_kinds" validate:"omitempty,max=4,dive,oneof=binary minified lockfile generated" example:"binary,minified,lockfile,generated"`
+	// Path patterns of files kept even when they look like noise, e.g. "api/*.pb.go"; a pattern without a slash
+	// matches file names in any directory, and one ending with a slash the files below a directory
+	IncludePaths []string `json:"include_paths" validate:"omitempty,max=100,dive,min=1,max=256" example:"proto/generated/"`
+	// Path patterns of files always left out, e.g. "fixtures/"
+	ExcludePaths []string `json:"exclude_paths" validate:"omitempty,max=100,dive,min=1,max=256" example:"testdata/"`
+} //@name NoiseFileFilter
+
+// RedactionPolicy selects the content masked before the repositories of a project's agents are embedded
+type RedactionPolicy struct {
+	// Project the policy applies to
+	ProjectID string `json:"project_id" db:"project_id" example:"proj-12345-abcde"`
+	// Whether email addresses are masked
+	MaskEmails bool `json:"mask_emails" db:"mask_emails" example:"true"`
+	// Whether credentials such as access keys, tokens and private keys are masked
+	MaskCredentials bool `json:"mask_credentials" db:"mask_credentials" example:"true"`
+	// Custom patterns masked in addition to emails and credentials
+	Patterns []RedactionPattern `json:"patterns" db:"patterns"`
+	// Whether the prompts and responses of the LLM calls made for the project's tasks are logged; only their
+	// metadata is logged when disabled, such as for sensitive codebases
+	LogLLMContent bool `json:"log_llm_content" db:"log_llm_content" example:"true"`
+	// Noise files left out of the project's knowledge bases and task prompts; all kinds by default
+	Files *NoiseFileFilter `json:"files" db:"files"`
+	// Whether the project uses the default policy because none was configured
+	IsDefault bool `json:"is_default" example:"false"`
+	// Last update timestamp, absent for the default policy
+	UpdatedAt *time.Time `json:"updated_at,omitempty" db:"updated_at" example:"2024-01-15T10:30:00Z"`
+} //@name RedactionPolicy
+
+// GetRedactionPolicyRequest represents the request to get the redaction policy of a project
+type GetRedactionPolicyRequest struct {
+	// Project ID
+	ProjectID string `uri:"project_id" validate:"required,project_id" example:"proj-12345-abcde"`
+} //@name GetRedactionPolicyRequest
+
+// UpdateRedactionPolicyRequest represents the request to replace the redaction policy of a project
+type UpdateRedactionPolicyRequest struct {
+	// Project ID
+	ProjectID string `uri:"project_id" validate:"required,project_id" example:"proj-12345-abcde"`
+	// Whether email addresses are masked, unchanged when omitted
+	MaskEmails *bool `json:"mask_emails,omitempty" example:"true"`
+	// Whether credentials are masked, unchanged when omitted
+	MaskCredentials *bool `json:"mask_credentials,omitempty" example:"true"`
+	// Whether LLM prompts and responses are logged, unchanged when omitted
+	LogLLMContent *bool `json:"log_llm_content,omitempty" example:"false"`
+	// Custom patterns, replacing the current ones
+	Patterns []RedactionPattern `json:"patterns" validate:"omitempty,max=50,dive"`
+	// Noise file filter, replacing the current one, unchanged when omitted
+	Files *NoiseFileFilter `json:"files,omitempty"`
+} //@name UpdateRedactionPolicyRequest
+
+// RedactionAudit counts the content masked during one sync of an agent's repository
+type RedactionAudit struct {
+	// Unique identifier for the audit
+	AuditID string `json:"audit_id" db:"audit_id" example:"redaction-12345-abcde"`
+	// Project whose policy was applied
+	ProjectID string `json:"project_id" db:"project_id" example:"proj-12345-abcde"`
+	// Synced agent
+	AgentID string `json:"agent_id" db:"agent_id" example:"agent-12345"`
+	// Operation that synced the repository
+	Operation RedactionOperation `json:"operation" db:"operation" example:"create"`
+	// Number of text files checked
+	FilesScanned int `json:"files_scanned" db:"files_scanned" example:"420"`
+	// Number of files with at least one redaction
+	FilesRedacted int `json:"files_redacted" db:"files_redacted" example:"7"`
+	// Total number of redactions
+	Redactions int `json:"redactions" db:"redactions" example:"12"`
+	// Redactions per rule: email, credential or a custom pattern name
+	Counts map[string]int `json:"counts" db:"counts"`
+	// Sync timestamp
+	SyncedAt time.Time `json:"synced_at" db:"synced_at" example:"2024-01-15T10:30:00Z"`
+} //@name RedactionAudit
+
+// ListRedactionAuditsRequest represents the request to list the redaction audits of a project
+type ListRedactionAuditsRequest struct {
+	// Project ID
+	ProjectID string `uri:"project_id" validate:"required,project_id" example:"proj-12345-abcde"`
+	// Maximum number of audits, defaults to 50
+	Limit int `form:"limit" validate:"omitempty,min=1,max=100" example:"50"`
+} //@name ListRedactionAuditsRequest
+
+// ListRedactionAuditsResponse represents the redaction audits of a project, most recent first
+type ListRedactionAuditsResponse struct {
+	// Redaction audits
+	Audits []RedactionAudit `json:"audits"`
+} //@name ListRedactionAuditsResponse
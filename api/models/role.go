@@ -0,0 +1,189 @@
+// Package models provides data structures for roles and permissions
+package models
+
+import "time"
+
+// Role is a role users hold, granting them its permissions. Besides the built-in roles, custom roles such as
+// "security-reviewer" can be defined.
+type Role struct {
+	// Role name
+	Name UserRole `json:"name" db:"name" example:"security-reviewer"`
+	// What the role is for
+	Description string `json:"description,omitempty" db:"description" example:"Reviews the findings of security scans"`
+	// Permissions the role grants
+	Permissions []Permission `json:"permissions" example:"project:read,task:read"`
+	// Whether the role is built in, so it can't be deleted
+	BuiltIn   bool      `json:"built_in" db:"built_in"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+} //@name Role
+
+// PermissionDefinition is a permission roles can grant
+type PermissionDefinition struct {
+	Name        Permission `json:"name" db:"name" example:"task:read"`
+	Description string     `json:"description" db:"description" example:"Read task information"`
+} //@name PermissionDefinition
+
+// ProjectMember gives a user access to a project with a role. A project has at most one member holding the owner role.
+type ProjectMember struct {
+	ProjectID string `json:"project_id" db:"project_id" example:"proj-1"`
+	UserID    string `json:"user_id" db:"user_id" example:"usr-123"`
+	// Role the user holds within the project instead of their own role
+	Role      UserRole  `json:"role" db:"role" example:"developer"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+} //@name ProjectMember
+
+// BuiltInPermissions are the permissions the service checks
+var BuiltInPermissions = []PermissionDefinition{
+	{Name: PermissionProjectCreate, Description: "Create projects"},
+	{Name: PermissionProjectRead, Description: "Read project information"},
+	{Name: PermissionProjectUpdate, Description: "Update, archive and unarchive projects"},
+	{Name: PermissionProjectDelete, Description: "Delete projects"},
+	{Name: PermissionProjectTransfer, Description: "Transfer the ownership of projects"},
+	{Name: PermissionMemberManage, Description: "Add, change and remove project members"},
+	{Name: PermissionTaskCreate, Description: "Create tasks"},
+	{Name: PermissionTaskExecute, Description: "Execute tasks"},
+	{Name: PermissionTaskRead, Description: "Read task information"},
+	{Name: PermissionTaskUpdate, Description: "Update tasks"},
+	{Name: PermissionTaskDelete, Description: "Delete tasks"},
+	{Name: PermissionAgentCreate, Description: "Create agents"},
+	{Name: PermissionAgentRead, Description: "Read agent information"},
+	{Name: PermissionAgentUpdate, Description: "Update agents"},
+	{Name: PermissionAgentDelete, Description: "Delete agents"},
+	{Name: PermissionUserCreate, Description: "Create users"},
+	{Name: PermissionUserRead, Description: "Read user information"},
+	{Name: PermissionUserUpdate, Description: "Update users"},
+	{Name: PermissionUserDelete, Description: "Delete users"},
+	{Name: PermissionRoleRead, Description: "Read roles and permissions"},
+	{Name: PermissionRoleManage, Description: "Change roles"},
+	{Name: PermissionAuditRead, Description: "Export audit events"},
+	{Name: PermissionComplianceExport, Description: "Export users and project access grants"},
+	{Name: PermissionTagKeyManage, Description: "Register, change and unregister tag keys"},
+	{Name: PermissionIntegrationManage, Description: "Configure GitHub Apps and Jira sites"},
+	{Name: PermissionServiceAccountManage, Description: "Manage service accounts and their tokens"},
+	{Name: PermissionOperationsManage, Description: "Operate workspaces, stuck tasks, storage, evaluations and experiments"},
+}
+
+// BuiltInRoles are the roles the service starts with. Their permissions are only the initial ones, as they can be
+// changed, except for the owner role, which always holds every permission.
+var BuiltInRoles = []Role{
+	{
+		Name:        RoleOwner,
+		Description: "Full access to everything",
+		BuiltIn:     true,
+	},
+	{
+		Name:        RoleAdmin,
+		Description: "Manage users and all projects",
+		BuiltIn:     true,
+		Permissions: []Permission{
+			PermissionProjectCreate, PermissionProjectRead, PermissionProjectUpdate, PermissionProjectDelete,
+			PermissionMemberManage,
+			PermissionTaskCreate, PermissionTaskExecute, PermissionTaskRead, PermissionTaskUpdate, PermissionTaskDelete,
+			PermissionAgentCreate, PermissionAgentRead, PermissionAgentUpdate, PermissionAgentDelete,
+			PermissionUserRead, PermissionUserUpdate,
+			PermissionRoleRead,
+			PermissionAuditRead, PermissionComplianceExport,
+			PermissionTagKeyManage, PermissionIntegrationManage, PermissionServiceAccountManage, PermissionOperationsManage,
+		},
+	},
+	{
+		Name:        RoleDeveloper,
+		Description: "Create and manage own projects",
+		BuiltIn:     true,
+		Permissions: []Permission{
+			PermissionProjectCreate, PermissionProjectRead, PermissionProjectUpdate,
+			PermissionTaskCreate, PermissionTaskExecute, PermissionTaskRead,
+			PermissionAgentCreate, PermissionAgentRead,
+		},
+	},
+	{
+		Name:        RoleViewer,
+		Description: "Read-only access",
+		BuiltIn:     true,
+		Permissions: []Permission{
+			PermissionProjectRead,
+			PermissionTaskRead,
+			PermissionAgentRead,
+		},
+	},
+}
+
+// CreateRoleRequest represents the request to define a custom role
+type CreateRoleRequest struct {
+	Name        UserRole     `json:"name" validate:"required,role_name" example:"security-reviewer"`
+	Description string       `json:"description,omitempty" validate:"omitempty,max=500" example:"Reviews the findings of security scans"`
+	Permissions []Permission `json:"permissions" validate:"required,min=1,max=100,dive,min=1,max=100" example:"project:read,task:read"`
+	// Authenticated caller, set by the controller
+	ActorID string `json:"-"`
+} //@name CreateRoleRequest
+
+// UpdateRoleRequest represents the request to replace the description and permissions of a role
+type UpdateRoleRequest struct {
+	Name        UserRole     `uri:"name" validate:"required,role_name" example:"security-reviewer"`
+	Description string       `json:"description,omitempty" validate:"omitempty,max=500" example:"Reviews the findings of security scans"`
+	Permissions []Permission `json:"permissions" validate:"max=100,dive,min=1,max=100" example:"project:read,task:read"`
+	// Authenticated caller, set by the controller
+	ActorID string `json:"-"`
+} //@name UpdateRoleRequest
+
+// GetRoleRequest represents the request to get a role
+type GetRoleRequest struct {
+	Name UserRole `uri:"name" validate:"required,role_name" example:"security-reviewer"`
+} //@name GetRoleRequest
+
+// DeleteRoleRequest represents the request to delete a custom role
+type DeleteRoleRequest struct {
+	Name UserRole `uri:"name" validate:"required,role_name" example:"security-reviewer"`
+	// Authenticated caller, set by the controller
+	ActorID string `json:"-"`
+} //@name DeleteRoleRequest
+
+// ListRolesResponse represents the response when listing roles
+type ListRolesResponse struct {
+	Roles []Role `json:"roles"`
+} //@name ListRolesResponse
+
+// ListPermissionsResponse represents the response when listing the permissions roles can grant
+type ListPermissionsResponse struct {
+	Permissions []PermissionDefinition `json:"permissions"`
+} //@name ListPermissionsResponse
+
+// ListProjectMembersRequest represents the request to list the members of a project
+type ListProjectMembersRequest struct {
+	ProjectID string `uri:"project_id" validate:"required,project_id" example:"proj-1"`
+} //@name ListProjectMembersRequest
+
+// AddProjectMemberRequest represents the request to add a member to a project, or change the role of a member
+type AddProjectMemberRequest struct {
+	ProjectID string `uri:"project_id" validate:"required,project_id" example:"proj-1"`
+	// User to give access to the project
+	UserID string `json:"user_id" validate:"required,min=1,max=255" example:"usr-123"`
+	// Role the user holds within the project. The owner role is only given by transferring the ownership.
+	Role UserRole `json:"role" validate:"required,role_name" example:"developer"`
+	// Authenticated caller, set by the controller
+	ActorID string `json:"-"`
+} //@name AddProjectMemberRequest
+
+// RemoveProjectMemberRequest represents the request to remove a member from a project
+type RemoveProjectMemberRequest struct {
+	ProjectID string `uri:"project_id" validate:"required,project_id" example:"proj-1"`
+	UserID    string `uri:"user_id" validate:"required,min=1,max=255" example:"usr-123"`
+	// Authenticated caller, set by the controller
+	ActorID string `json:"-"`
+} //@name RemoveProjectMemberRequest
+
+// TransferProjectOwnershipRequest represents the request to make another user the owner of a project
+type TransferProjectOwnershipRequest struct {
+	ProjectID string `uri:"project_id" validate:"required,project_id" example:"proj-1"`
+	// User becoming the owner. The previous owner stays a member with the admin role.
+	UserID string `json:"user_id" validate:"required,min=1,max=255" example:"usr-456"`
+	// Authenticated caller, set by the controller
+	ActorID string `json:"-"`
+} //@name TransferProjectOwnershipRequest
+
+// ListProjectMembersResponse represents the response when listing the members of a project
+type ListProjectMembersResponse struct {
+	Members []ProjectMember `json:"members"`
+} //@name ListProjectMembersResponse
@@ -0,0 +1,118 @@
+// Package models provides data structures for service accounts and their tokens
+package models
+
+import "time"
+
+const (
+	// ServiceAccountTokenPrefix starts every service account token, telling them apart from the access tokens of users
+	ServiceAccountTokenPrefix = "crt_sa_"
+
+	// ServiceAccountAuthIDPrefix starts the auth provider ID of the user a service account acts as
+	ServiceAccountAuthIDPrefix = "service-account:"
+)
+
+// ServiceAccount is a non-human caller, such as an infrastructure-as-code provider, authenticating with long-lived
+// tokens. It acts as a user holding its role, which can be made a member of projects like any other user.
+type ServiceAccount struct {
+	// Unique identifier of the service account
+	ServiceAccountID string `json:"service_account_id" db:"service_account_id" example:"sa-5f0c2d9e-8b1a-4c3e-9f7d-6a2b4c8e1d3f"`
+	// Unique name of the service account
+	Name string `json:"name" db:"name" example:"terraform"`
+	// Optional description of what the service account is for
+	Description *string `json:"description,omitempty" db:"description" example:"Manages projects from the platform repository"`
+	// Role of the service account
+	Role UserRole `json:"role" db:"-" example:"developer"`
+	// User the service account acts as, which project members refer to
+	UserID string `json:"user_id" db:"user_id" example:"usr-1705314600000000000"`
+	// Auth provider ID of the user who created the service account
+	CreatedBy string `json:"created_by" db:"created_by"`
+	// Creation timestamp
+	CreatedAt time.Time `json:"created_at" db:"created_at" example:"2024-01-15T10:30:00Z"`
+} //@name ServiceAccount
+
+// ServiceAccountToken is a bearer token of a service account. Only its SHA-256 hash is stored.
+type ServiceAccountToken struct {
+	// Unique identifier of the token
+	TokenID string `json:"token_id" db:"token_id" example:"sat-2c5d8e1b-7a93-4e7b-a6f0-3f2b8c1e9d4a"`
+	// Service account the token authenticates
+	ServiceAccountID string `json:"service_account_id" db:"service_account_id" example:"sa-5f0c2d9e-8b1a-4c3e-9f7d-6a2b4c8e1d3f"`
+	// Name telling the tokens of a service account apart
+	Name string `json:"name" db:"name" example:"ci"`
+	// SHA-256 hash of the token
+	TokenHash string `json:"-" db:"token_hash"`
+	// Creation timestamp
+	CreatedAt time.Time `json:"created_at" db:"created_at" example:"2024-01-15T10:30:00Z"`
+	// When the token stops authenticating, nil if it never expires
+	ExpiresAt *time.Time `json:"expires_at,omitempty" db:"expires_at" example:"2025-01-15T10:30:00Z"`
+	// When the token last authenticated a request, to the minute
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" db:"last_used_at" example:"2024-02-01T08:15:00Z"`
+	// When the token was revoked
+	RevokedAt *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+} //@name ServiceAccountToken
+
+// CreateServiceAccountRequest represents the request to create a service account
+type CreateServiceAccountRequest struct {
+	// Unique name of the service account, lowercase letters, digits and dashes
+	Name string `json:"name" validate:"required,min=3,max=50" example:"terraform"`
+	// Optional description of what the service account is for
+	Description *string `json:"description,omitempty" validate:"omitempty,max=500" example:"Manages projects from the platform repository"`
+	// Role of the service account, any built-in or custom role but owner
+	Role UserRole `json:"role" validate:"required,role_name" example:"developer"`
+	// Authenticated caller, set by the controller
+	CreatedBy string `json:"-"`
+} //@name CreateServiceAccountRequest
+
+// GetServiceAccountRequest represents the request to get a service account
+type GetServiceAccountRequest struct {
+	// Unique identifier of the service account
+	ServiceAccountID string `uri:"service_account_id" validate:"required,max=64" example:"sa-5f0c2d9e-8b1a-4c3e-9f7d-6a2b4c8e1d3f"`
+} //@name GetServiceAccountRequest
+
+// DeleteServiceAccountRequest represents the request to delete a service account and revoke its tokens
+type DeleteServiceAccountRequest struct {
+	// Unique identifier of the service account
+	ServiceAccountID string `uri:"service_account_id" validate:"required,max=64" example:"sa-5f0c2d9e-8b1a-4c3e-9f7d-6a2b4c8e1d3f"`
+} //@name DeleteServiceAccountRequest
+
+// ListServiceAccountsResponse represents the response when listing the service accounts
+type ListServiceAccountsResponse struct {
+	// Service accounts ordered by name
+	ServiceAccounts []ServiceAccount `json:"service_accounts"`
+} //@name ListServiceAccountsResponse
+
+// CreateServiceAccountTokenRequest represents the request to issue a token to a service account
+type CreateServiceAccountTokenRequest struct {
+	// Service account the token authenticates
+	ServiceAccountID string `uri:"service_account_id" validate:"required,max=64" example:"sa-5f0c2d9e-8b1a-4c3e-9f7d-6a2b4c8e1d3f"`
+	// Name telling the tokens of the service account apart
+	Name string `json:"name" validate:"required,min=1,max=100" example:"ci"`
+	// Days until the token expires, omit for a token that never expires
+	ExpiresInDays *int `json:"expires_in_days,omitempty" validate:"omitempty,min=1,max=730" example:"90"`
+} //@name CreateServiceAccountTokenRequest
+
+// CreateServiceAccountTokenResponse represents an issued service account token. The token is only ever returned here.
+type CreateServiceAccountTokenResponse struct {
+	ServiceAccountToken
+	// Bearer token to send in the Authorization header
+	Token string `json:"token" example:"crt_sa_q8Zt0b3YfW1mXc5rK2pLs9vJ4nH7dG6aE0uT1iO3yR8"`
+} //@name CreateServiceAccountTokenResponse
+
+// ListServiceAccountTokensRequest represents the request to list the tokens of a service account
+type ListServiceAccountTokensRequest struct {
+	// Unique identifier of the service account
+	ServiceAccountID string `uri:"service_account_id" validate:"required,max=64" example:"sa-5f0c2d9e-8b1a-4c3e-9f7d-6a2b4c8e1d3f"`
+} //@name ListServiceAccountTokensRequest
+
+// ListServiceAccountTokensResponse represents the response when listing the tokens of a service account
+type ListServiceAccountTokensResponse struct {
+	// Tokens ordered by creation, newest first
+	Tokens []ServiceAccountToken `json:"tokens"`
+} //@name ListServiceAccountTokensResponse
+
+// RevokeServiceAccountTokenRequest represents the request to revoke a token of a service account
+type RevokeServiceAccountTokenRequest struct {
+	// Unique identifier of the service account
+	ServiceAccountID string `uri:"service_account_id" validate:"required,max=64" example:"sa-5f0c2d9e-8b1a-4c3e-9f7d-6a2b4c8e1d3f"`
+	// Unique identifier of the token
+	TokenID string `uri:"token_id" validate:"required,max=64" example:"sat-2c5d8e1b-7a93-4e7b-a6f0-3f2b8c1e9d4a"`
+} //@name RevokeServiceAccountTokenRequest
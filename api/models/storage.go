@@ -0,0 +1,92 @@
+// Package models provides data structures for the purges of the content agents uploaded to object storage
+package models
+
+import "time"
+
+// StoragePurgeStatus is the progress of a storage purge
+type StoragePurgeStatus string
+
+const (
+	// StoragePurgeStatusPending is a purge waiting for the purge queue
+	StoragePurgeStatusPending StoragePurgeStatus = "pending"
+
+	// StoragePurgeStatusRunning is a purge deleting objects, retried after a failed attempt
+	StoragePurgeStatusRunning StoragePurgeStatus = "running"
+
+	// StoragePurgeStatusCompleted is a purge that deleted every object under its prefix
+	StoragePurgeStatusCompleted StoragePurgeStatus = "completed"
+
+	// StoragePurgeStatusFailed is a purge that gave up after its last attempt failed
+	StoragePurgeStatusFailed StoragePurgeStatus = "failed"
+)
+
+// StoragePurgeEntityAgent is the entity type of the purges of the content uploaded for the knowledge base of an agent
+const StoragePurgeEntityAgent = "agent"
+
+// StoragePurge is the deletion of every object under the key prefix of a deleted entity, run in the background
+type StoragePurge struct {
+	// Unique identifier for the purge
+	PurgeID string `json:"purge_id" db:"purge_id" example:"purge-12345-abcde"`
+	// Type of the deleted entity
+	EntityType string `json:"entity_type" db:"entity_type" example:"agent"`
+	// Deleted entity whose content is purged
+	EntityID string `json:"entity_id" db:"entity_id" example:"agent-12345"`
+	// AWS region of the bucket
+	Region string `json:"region" db:"region" example:"us-east-1"`
+	// Bucket the content is stored in
+	Bucket string `json:"bucket" db:"bucket" example:"code-refactoring-content"`
+	// Key prefix of the content
+	Prefix string `json:"prefix" db:"prefix" example:"knowledge-bases/KB12345678/"`
+	// Progress of the purge
+	Status StoragePurgeStatus `json:"status" db:"status" example:"running"`
+	// Objects deleted so far
+	ObjectsDeleted int64 `json:"objects_deleted" db:"objects_deleted" example:"1200"`
+	// Attempts made to purge the prefix
+	Attempts int `json:"attempts" db:"attempts" example:"1"`
+	// Error of the last failed attempt
+	LastError string `json:"last_error,omitempty" db:"last_error" example:"failed to delete objects: AccessDenied"`
+	// Creation timestamp
+	CreatedAt time.Time `json:"created_at" db:"created_at" example:"2024-01-15T10:30:00Z"`
+	// Last progress timestamp
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at" example:"2024-01-15T10:31:00Z"`
+	// When the purge completed or failed
+	CompletedAt *time.Time `json:"completed_at,omitempty" db:"completed_at" example:"2024-01-15T10:32:00Z"`
+} //@name StoragePurge
+
+// GetStoragePurgeRequest represents the request to get a storage purge
+type GetStoragePurgeRequest struct {
+	// Unique identifier of the purge
+	PurgeID string `uri:"purge_id" validate:"required,max=64" example:"purge-12345-abcde"`
+} //@name GetStoragePurgeRequest
+
+// ListStoragePurgesRequest represents the request to list storage purges
+type ListStoragePurgesRequest struct {
+	// Only list the purges with this status
+	Status string `form:"status" validate:"omitempty,oneof=pending running completed failed" example:"failed"`
+} //@name ListStoragePurgesRequest
+
+// ListStoragePurgesResponse represents the response of listing storage purges
+type ListStoragePurgesResponse struct {
+	// Purges, newest first
+	Purges []StoragePurge `json:"purges"`
+} //@name ListStoragePurgesResponse
+
+// StorageOrphan is a key prefix holding content of an entity that no longer exists
+type StorageOrphan struct {
+	// AWS region of the bucket
+	Region string `json:"region" example:"us-east-1"`
+	// Bucket the content is stored in
+	Bucket string `json:"bucket" example:"code-refactoring-content"`
+	// Key prefix of the content
+	Prefix string `json:"prefix" example:"knowledge-bases/KB12345678/"`
+} //@name StorageOrphan
+
+// ListStorageOrphansResponse reports the key prefixes left behind by deleted entities and failed setups
+type ListStorageOrphansResponse struct {
+	// Orphaned prefixes, by bucket and prefix
+	Orphans []StorageOrphan `json:"orphans"`
+	// Prefixes a purge is pending or running for, which are not reported as orphans
+	Purging int `json:"purging" example:"1"`
+	// When the buckets were listed
+	CheckedAt time.Time `json:"checked_at" example:"2024-01-15T10:30:00Z"`
+} //@name ListStorageOrphansResponse
@@ -0,0 +1,56 @@
+// Package models provides data structures for the tag key registry
+package models
+
+import "time"
+
+// TagKey is a registered tag key, governing the values the tags of projects, codebases and codebase configurations
+// may hold under it and who may set them
+type TagKey struct {
+	// Tag key
+	Key string `json:"key" db:"key" example:"cost-center"`
+	// What the tag records
+	Description string `json:"description,omitempty" db:"description" example:"Cost center billed for the resource"`
+	// Values the tag may hold; any value when empty
+	AllowedValues []string `json:"allowed_values,omitempty" db:"allowed_values" example:"cc-100,cc-200"`
+	// Regular expression the whole value must match; any value when empty
+	ValuePattern string `json:"value_pattern,omitempty" db:"value_pattern" example:"cc-[0-9]+"`
+	// Roles allowed to add, change or remove the tag; any caller when empty
+	EditorRoles []UserRole `json:"editor_roles,omitempty" db:"editor_roles" example:"owner,admin"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+} //@name TagKey
+
+// CreateTagKeyRequest represents the request to register a tag key
+type CreateTagKeyRequest struct {
+	Key           string     `json:"key" validate:"required,min=1,max=50" example:"cost-center"`
+	Description   string     `json:"description,omitempty" validate:"omitempty,max=500" example:"Cost center billed for the resource"`
+	AllowedValues []string   `json:"allowed_values,omitempty" validate:"omitempty,max=100,dive,min=1,max=100" example:"cc-100,cc-200"`
+	ValuePattern  string     `json:"value_pattern,omitempty" validate:"omitempty,max=255" example:"cc-[0-9]+"`
+	EditorRoles   []UserRole `json:"editor_roles,omitempty" validate:"omitempty,dive,role_name" example:"owner,admin"`
+} //@name CreateTagKeyRequest
+
+// UpdateTagKeyRequest represents the request to replace the rules of a registered tag key
+type UpdateTagKeyRequest struct {
+	Key           string     `uri:"key" validate:"required,min=1,max=50" example:"cost-center"`
+	Description   string     `json:"description,omitempty" validate:"omitempty,max=500" example:"Cost center billed for the resource"`
+	AllowedValues []string   `json:"allowed_values,omitempty" validate:"omitempty,max=100,dive,min=1,max=100" example:"cc-100,cc-200"`
+	ValuePattern  string     `json:"value_pattern,omitempty" validate:"omitempty,max=255" example:"cc-[0-9]+"`
+	EditorRoles   []UserRole `json:"editor_roles,omitempty" validate:"omitempty,dive,role_name" example:"owner,admin"`
+} //@name UpdateTagKeyRequest
+
+// GetTagKeyRequest represents the request to get a registered tag key
+type GetTagKeyRequest struct {
+	Key string `uri:"key" validate:"required,min=1,max=50" example:"cost-center"`
+} //@name GetTagKeyRequest
+
+// DeleteTagKeyRequest represents the request to unregister a tag key
+type DeleteTagKeyRequest struct {
+	Key string `uri:"key" validate:"required,min=1,max=50" example:"cost-center"`
+} //@name DeleteTagKeyRequest
+
+// ListTagKeysResponse represents the response when listing the registered tag keys
+type ListTagKeysResponse struct {
+	TagKeys []TagKey `json:"tag_keys"`
+	// Whether tags with unregistered keys are rejected
+	RequireRegisteredKeys bool `json:"require_registered_keys"`
+} //@name ListTagKeysResponse
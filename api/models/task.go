@@ -2,6 +2,7 @@
 package models
 
 import (
+	"slices"
 	"time"
 )
 
@@ -12,6 +13,9 @@ const (
 	// TaskStatusPending indicates the task is waiting to be processed
 	TaskStatusPending TaskStatus = "pending"
 
+	// TaskStatusQueued indicates the task was accepted for execution and waits for a worker to run it
+	TaskStatusQueued TaskStatus = "queued"
+
 	// TaskStatusInProgress indicates the task is currently being executed
 	TaskStatusInProgress TaskStatus = "in_progress"
 
@@ -25,6 +29,50 @@ const (
 	TaskStatusCancelled TaskStatus = "cancelled"
 )
 
+// taskTransitions lists the statuses a task may move to from each status. Pending tasks may be run right away, skipping
+// the queue, and stuck in_progress tasks are queued again. Terminal statuses are final.
+var taskTransitions = map[TaskStatus][]TaskStatus{
+	TaskStatusPending:    {TaskStatusQueued, TaskStatusInProgress, TaskStatusCancelled},
+	TaskStatusQueued:     {TaskStatusInProgress, TaskStatusCancelled},
+	TaskStatusInProgress: {TaskStatusQueued, TaskStatusCompleted, TaskStatusFailed, TaskStatusCancelled},
+}
+
+// IsTerminal reports whether the status is final and the task will not progress further
+func (s TaskStatus) IsTerminal() bool {
+	return s == TaskStatusCompleted || s == TaskStatusFailed || s == TaskStatusCancelled
+}
+
+// CanTransitionTo reports whether a task may move from the status to next
+func (s TaskStatus) CanTransitionTo(next TaskStatus) bool {
+	return slices.Contains(taskTransitions[s], next)
+}
+
+// TaskStatusesBefore returns the statuses a task may move to next from, in a stable order
+func TaskStatusesBefore(next TaskStatus) []TaskStatus {
+	var statuses []TaskStatus
+	for _, status := range []TaskStatus{TaskStatusPending, TaskStatusQueued, TaskStatusInProgress} {
+		if status.CanTransitionTo(next) {
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses
+}
+
+// TaskPriority orders the queued tasks the dispatcher runs next. Higher priority tasks run first, whichever project
+// they're in.
+type TaskPriority string
+
+const (
+	// TaskPriorityLow is for bulk work that may wait, such as the child tasks of a campaign
+	TaskPriorityLow TaskPriority = "low"
+
+	// TaskPriorityNormal is the priority of tasks created without one
+	TaskPriorityNormal TaskPriority = "normal"
+
+	// TaskPriorityHigh is for interactive tasks a user is waiting on
+	TaskPriorityHigh TaskPriority = "high"
+)
+
 // TaskType represents the type of task to execute
 type TaskType string
 
@@ -43,26 +91,70 @@ const (
 
 	// TaskTypeCustom represents a user-defined custom task
 	TaskTypeCustom TaskType = "custom"
+
+	// TaskTypeDependencyAudit represents an audit of a codebase's dependencies against known vulnerabilities
+	TaskTypeDependencyAudit TaskType = "dependency_audit"
+
+	// TaskTypeCodemod represents a deterministic refactoring of a Go codebase, selected by the codemod transform in its input
+	TaskTypeCodemod TaskType = "codemod"
+
+	// TaskTypeCoverageGap represents the generation of unit tests for the least covered, most changed functions of a
+	// codebase, opened as a pull request
+	TaskTypeCoverageGap TaskType = "coverage_gap"
 )
 
+// AnalysisMode selects a static analyzer that a code_analysis task runs on its codebase instead of prompting the agent
+type AnalysisMode string
+
+const (
+	// AnalysisModeDuplicateCode finds blocks of code duplicated within and across files
+	AnalysisModeDuplicateCode AnalysisMode = "duplicate_code"
+
+	// AnalysisModeDeadCode finds unreachable statements and unused functions
+	AnalysisModeDeadCode AnalysisMode = "dead_code"
+
+	// AnalysisModePackageStructure finds import cycles and god packages in the package graph of a Go module
+	AnalysisModePackageStructure AnalysisMode = "package_structure"
+
+	// AnalysisModeMetrics only records the complexity, function length and package coupling of the commit
+	AnalysisModeMetrics AnalysisMode = "metrics"
+)
+
+// MaxSeededRefactoringTasks is the maximum number of refactoring tasks a static analysis seeds from its findings
+const MaxSeededRefactoringTasks = 20
+
 // Task represents a user-initiated task/prompt execution against a project
 type Task struct {
-	TaskID       string            `json:"task_id" db:"task_id"`
-	ProjectID    string            `json:"project_id" db:"project_id"`
-	AgentID      string            `json:"agent_id" db:"agent_id"`                 // Which agent to use for this task
-	CodebaseID   *string           `json:"codebase_id,omitempty" db:"codebase_id"` // Optional: specific codebase, if nil uses all project codebases
-	Type         TaskType          `json:"type" db:"type"`
-	Status       TaskStatus        `json:"status" db:"status"`
-	Title        string            `json:"title" db:"title"`
-	Description  string            `json:"description" db:"description"` // User's prompt/instructions
-	Input        map[string]any    `json:"input,omitempty" db:"input"`   // Additional input parameters
-	Output       map[string]any    `json:"output,omitempty" db:"output"` // Task results
-	ErrorMessage *string           `json:"error_message,omitempty" db:"error_message"`
-	CreatedAt    time.Time         `json:"created_at" db:"created_at"`
-	UpdatedAt    time.Time         `json:"updated_at" db:"updated_at"`
-	CompletedAt  *time.Time        `json:"completed_at,omitempty" db:"completed_at"`
-	Metadata     map[string]string `json:"metadata,omitempty" db:"metadata"`
-	Tags         map[string]string `json:"tags,omitempty" db:"tags"`
+	TaskID        string            `json:"task_id" db:"task_id"`
+	ProjectID     string            `json:"project_id" db:"project_id"`
+	BatchID       *string           `json:"batch_id,omitempty" db:"batch_id"`       // Set when the task was created through the batch API
+	CampaignID    *string           `json:"campaign_id,omitempty" db:"campaign_id"` // Set for the child tasks of a campaign
+	CreatedBy     *string           `json:"created_by,omitempty" db:"created_by"`   // User who created the task, notified when it finishes
+	AgentID       string            `json:"agent_id" db:"agent_id"`                 // Which agent to use for this task
+	CodebaseID    *string           `json:"codebase_id,omitempty" db:"codebase_id"` // Optional: specific codebase, if nil uses all project codebases
+	Branch        *string           `json:"branch,omitempty" db:"branch"`           // Branch of the codebase the task runs against, nil for the default branch
+	CommitSHA     *string           `json:"commit_sha,omitempty" db:"commit_sha"`   // Commit the task runs against, pinned when the task is created
+	Type          TaskType          `json:"type" db:"type"`
+	AnalysisMode  *AnalysisMode     `json:"analysis_mode,omitempty" db:"analysis_mode"` // Static analyzer run by a code_analysis task
+	Status        TaskStatus        `json:"status" db:"status"`
+	Priority      TaskPriority      `json:"priority" db:"priority"`
+	Title         string            `json:"title" db:"title"`
+	Description   string            `json:"description" db:"description"` // User's prompt/instructions
+	Input         map[string]any    `json:"input,omitempty" db:"input"`   // Additional input parameters
+	Output        map[string]any    `json:"output,omitempty" db:"output"` // Task results
+	ErrorMessage  *string           `json:"error_message,omitempty" db:"error_message"`
+	CreatedAt     time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at" db:"updated_at"`
+	CompletedAt   *time.Time        `json:"completed_at,omitempty" db:"completed_at"`
+	QueuedAt      *time.Time        `json:"queued_at,omitempty" db:"queued_at"`           // When the task was last queued, ordering tasks of the same priority and project load
+	HeartbeatAt   *time.Time        `json:"heartbeat_at,omitempty" db:"heartbeat_at"`     // Last heartbeat of the execution running an in_progress task
+	Progress      int               `json:"progress" db:"progress"`                       // Percentage of the execution's steps completed, 0 to 100
+	ProgressStep  *string           `json:"progress_step,omitempty" db:"progress_step"`   // Step the execution is running, reported with the heartbeat
+	ExperimentID  *string           `json:"experiment_id,omitempty" db:"experiment_id"`   // Experiment the task was assigned to when it was executed
+	ExperimentArm *ExperimentArm    `json:"experiment_arm,omitempty" db:"experiment_arm"` // Arm of the experiment the task ran on
+	Approved      *bool             `json:"approved,omitempty" db:"approved"`             // Whether a user approved the result of the completed task, nil until reviewed
+	Metadata      map[string]string `json:"metadata,omitempty" db:"metadata"`
+	Tags          map[string]string `json:"tags,omitempty" db:"tags"`
 
 	// Enhanced execution context (populated when requested)
 	ExecutionContext TaskExecutionContext `json:"execution_context,omitempty" db:"-"`
@@ -71,6 +163,12 @@ type Task struct {
 	Project  *Project  `json:"project,omitempty" db:"-"`
 	Agent    *Agent    `json:"agent,omitempty" db:"-"`
 	Codebase *Codebase `json:"codebase,omitempty" db:"-"`
+
+	// Jira issue the task works on, if it was linked to one
+	Issue *JiraIssueLink `json:"issue,omitempty" db:"-"`
+
+	// Position of a queued task in the queue, 1 for the task the dispatcher runs next (populated when requested)
+	QueuePosition *int `json:"queue_position,omitempty" db:"-"`
 }
 
 // TaskExecutionContext holds essential context information about task execution
@@ -86,15 +184,23 @@ type TaskExecutionContext struct {
 
 // CreateTaskRequest represents the request to create a new task
 type CreateTaskRequest struct {
-	ProjectID   string            `json:"project_id" validate:"required,project_id" example:"proj-12345-abcde"`
-	AgentID     string            `json:"agent_id" validate:"required" example:"agent-12345"`
-	CodebaseID  *string           `json:"codebase_id,omitempty" validate:"omitempty" example:"codebase-12345"`
-	Type        TaskType          `json:"type" validate:"required,oneof=code_analysis refactoring code_review documentation custom" example:"refactoring"`
-	Title       string            `json:"title" validate:"required,min=1,max=200" example:"Refactor authentication module"`
-	Description string            `json:"description" validate:"required,min=1,max=2000" example:"Please refactor the user authentication module to use JWT tokens instead of sessions"`
-	Input       map[string]any    `json:"input,omitempty"`
-	Metadata    map[string]string `json:"metadata,omitempty" validate:"omitempty,max=10,dive,keys,min=1,max=50,endkeys,min=1,max=100"`
-	Tags        map[string]string `json:"tags,omitempty" validate:"omitempty,max=10,dive,keys,min=1,max=50,endkeys,min=1,max=100"`
+	ProjectID    string            `json:"project_id" validate:"required,project_id" example:"proj-12345-abcde"`
+	AgentID      string            `json:"agent_id" validate:"required" example:"agent-12345"`
+	CodebaseID   *string           `json:"codebase_id,omitempty" validate:"required_with=Branch CommitSHA AnalysisMode" example:"codebase-12345"`
+	Branch       string            `json:"branch,omitempty" validate:"omitempty,max=255" example:"feature/jwt-auth"`                                              // Defaults to the codebase's default branch
+	CommitSHA    string            `json:"commit_sha,omitempty" validate:"omitempty,hexadecimal,min=7,max=40" example:"9fceb02d0ae598e95dc970b74767f19372d61af8"` // Defaults to the head of the branch
+	Type         TaskType          `json:"type" validate:"required,oneof=code_analysis refactoring code_review documentation custom dependency_audit codemod coverage_gap" example:"refactoring"`
+	AnalysisMode AnalysisMode      `json:"analysis_mode,omitempty" validate:"omitempty,oneof=duplicate_code dead_code package_structure metrics" example:"duplicate_code"` // Only for code_analysis tasks
+	Priority     TaskPriority      `json:"priority,omitempty" validate:"omitempty,oneof=low normal high" example:"normal"`                                                 // Defaults to normal
+	Title        string            `json:"title" validate:"required,min=1,max=200" example:"Refactor authentication module"`
+	Description  string            `json:"description" validate:"required,min=1,max=2000" example:"Please refactor the user authentication module to use JWT tokens instead of sessions"`
+	Input        map[string]any    `json:"input,omitempty"`
+	Metadata     map[string]string `json:"metadata,omitempty" validate:"omitempty,max=10,dive,keys,min=1,max=50,endkeys,min=1,max=100"`
+	Tags         map[string]string `json:"tags,omitempty" validate:"omitempty,max=10,dive,keys,min=1,max=50,endkeys,min=1,max=100"`
+	IssueKey     string            `json:"issue_key,omitempty" validate:"omitempty,max=50" example:"PAY-123"` // Jira issue the task works on, updated as the task progresses
+
+	// Authenticated caller, set by the controller
+	CreatedBy string `json:"-"`
 } //@name CreateTaskRequest
 
 // CreateTaskResponse represents the response when creating a task
@@ -117,11 +223,14 @@ type GetTaskResponse struct {
 // ListTasksRequest represents the request to list tasks for a project
 type ListTasksRequest struct {
 	ProjectID string      `uri:"project_id" validate:"required,project_id" example:"proj-12345-abcde"`
-	Status    *TaskStatus `form:"status,omitempty" validate:"omitempty,oneof=pending in_progress completed failed cancelled" example:"completed"`
-	Type      *TaskType   `form:"type,omitempty" validate:"omitempty,oneof=code_analysis refactoring code_review documentation custom" example:"refactoring"`
+	Status    *TaskStatus `form:"status,omitempty" validate:"omitempty,oneof=pending queued in_progress completed failed cancelled" example:"completed"`
+	Type      *TaskType   `form:"type,omitempty" validate:"omitempty,oneof=code_analysis refactoring code_review documentation custom dependency_audit codemod coverage_gap" example:"refactoring"`
 	AgentID   *string     `form:"agent_id,omitempty" validate:"omitempty" example:"agent-12345"`
+	Owner     *string     `form:"owner,omitempty" validate:"omitempty,max=255" example:"@acme/billing"` // Only tasks whose findings or changes are in files of this owner
 	Limit     *int        `form:"limit,omitempty" validate:"omitempty,min=1,max=100" example:"20"`
 	Offset    *int        `form:"offset,omitempty" validate:"omitempty,min=0" example:"0"`
+	// Optional tag filter - tasks must match all provided tags
+	TagFilter map[string]string `form:"tag_filter,omitempty" validate:"omitempty,max=10,dive,keys,min=1,max=50,endkeys,min=1,max=100" example:"env:prod"`
 } //@name ListTasksRequest
 
 // ListTasksResponse represents the response when listing tasks
@@ -135,10 +244,11 @@ type ListTasksResponse struct {
 // UpdateTaskRequest represents the request to update a task
 type UpdateTaskRequest struct {
 	TaskID       string            `uri:"id" validate:"required" example:"task-12345-abcde"`
-	Status       *TaskStatus       `json:"status,omitempty" validate:"omitempty,oneof=pending in_progress completed failed cancelled"`
+	Status       *TaskStatus       `json:"status,omitempty" validate:"omitempty,oneof=pending queued in_progress completed failed cancelled"`
 	Output       map[string]any    `json:"output,omitempty"`
 	ErrorMessage *string           `json:"error_message,omitempty"`
 	Metadata     map[string]string `json:"metadata,omitempty" validate:"omitempty,max=10,dive,keys,min=1,max=50,endkeys,min=1,max=100"`
+	Approved     *bool             `json:"approved,omitempty" example:"true"` // Approves or rejects the result of a completed task
 } //@name UpdateTaskRequest
 
 // UpdateTaskResponse represents the response when updating a task
@@ -148,14 +258,21 @@ type UpdateTaskResponse struct {
 
 // ExecuteTaskRequest represents the request to execute a task immediately
 type ExecuteTaskRequest struct {
-	ProjectID   string         `json:"project_id" validate:"required,project_id" example:"proj-12345-abcde"`
-	AgentID     string         `json:"agent_id" validate:"required" example:"agent-12345"`
-	CodebaseID  *string        `json:"codebase_id,omitempty" validate:"omitempty" example:"codebase-12345"`
-	Type        TaskType       `json:"type" validate:"required,oneof=code_analysis refactoring code_review documentation custom" example:"refactoring"`
-	Title       string         `json:"title" validate:"required,min=1,max=200" example:"Quick code analysis"`
-	Description string         `json:"description" validate:"required,min=1,max=2000" example:"Analyze this function for potential improvements"`
-	Input       map[string]any `json:"input,omitempty"`
-	Async       bool           `json:"async" example:"false"` // If true, returns task ID; if false, waits for completion
+	ProjectID    string         `json:"project_id" validate:"required,project_id" example:"proj-12345-abcde"`
+	AgentID      string         `json:"agent_id" validate:"required" example:"agent-12345"`
+	CodebaseID   *string        `json:"codebase_id,omitempty" validate:"required_with=Branch CommitSHA AnalysisMode" example:"codebase-12345"`
+	Branch       string         `json:"branch,omitempty" validate:"omitempty,max=255" example:"feature/jwt-auth"`                                              // Defaults to the codebase's default branch
+	CommitSHA    string         `json:"commit_sha,omitempty" validate:"omitempty,hexadecimal,min=7,max=40" example:"9fceb02d0ae598e95dc970b74767f19372d61af8"` // Defaults to the head of the branch
+	Type         TaskType       `json:"type" validate:"required,oneof=code_analysis refactoring code_review documentation custom dependency_audit codemod coverage_gap" example:"refactoring"`
+	AnalysisMode AnalysisMode   `json:"analysis_mode,omitempty" validate:"omitempty,oneof=duplicate_code dead_code package_structure metrics" example:"duplicate_code"` // Only for code_analysis tasks
+	Priority     TaskPriority   `json:"priority,omitempty" validate:"omitempty,oneof=low normal high" example:"high"`                                                   // Defaults to normal
+	Title        string         `json:"title" validate:"required,min=1,max=200" example:"Quick code analysis"`
+	Description  string         `json:"description" validate:"required,min=1,max=2000" example:"Analyze this function for potential improvements"`
+	Input        map[string]any `json:"input,omitempty"`
+	Async        bool           `json:"async" example:"false"` // If true, returns task ID; if false, waits for completion
+
+	// Authenticated caller, set by the controller
+	CreatedBy string `json:"-"`
 } //@name ExecuteTaskRequest
 
 // ExecuteTaskResponse represents the response when executing a task
@@ -166,3 +283,62 @@ type ExecuteTaskResponse struct {
 	CreatedAt   time.Time      `json:"created_at" example:"2024-01-15T10:30:00Z"`
 	CompletedAt *time.Time     `json:"completed_at,omitempty" example:"2024-01-15T10:35:00Z"`
 } //@name ExecuteTaskResponse
+
+// ListStuckTasksResponse lists the in_progress tasks whose executions stopped sending heartbeats
+type ListStuckTasksResponse struct {
+	Tasks []Task `json:"tasks"`
+	// Tasks without a heartbeat since this time are stuck
+	HeartbeatBefore time.Time `json:"heartbeat_before" example:"2024-01-15T10:25:00Z"`
+} //@name ListStuckTasksResponse
+
+// RequeueTaskRequest represents the request to requeue a stuck task
+type RequeueTaskRequest struct {
+	TaskID string `uri:"id" validate:"required" example:"task-12345-abcde"`
+} //@name RequeueTaskRequest
+
+// RequeueTaskResponse represents the response when requeueing a stuck task. The task runs again in the background
+// from its last completed step.
+type RequeueTaskResponse struct {
+	TaskID string     `json:"task_id" example:"task-12345-abcde"`
+	Status TaskStatus `json:"status" example:"pending"`
+} //@name RequeueTaskResponse
+
+// MaxTaskBatchSize is the maximum number of task specs accepted by a single batch request
+const MaxTaskBatchSize = 50
+
+// CreateTaskBatchRequest represents the request to create several tasks at once
+type CreateTaskBatchRequest struct {
+	Tasks []CreateTaskRequest `json:"tasks" validate:"required,min=1,max=50,dive"`
+} //@name CreateTaskBatchRequest
+
+// TaskBatchItemResult represents the outcome of a single task spec in a batch
+type TaskBatchItemResult struct {
+	Index  int        `json:"index" example:"0"`
+	TaskID string     `json:"task_id,omitempty" example:"task-12345-abcde"`
+	Status TaskStatus `json:"status,omitempty" example:"pending"`
+	Error  *string    `json:"error,omitempty" example:"agent not found: agent-12345"`
+} //@name TaskBatchItemResult
+
+// CreateTaskBatchResponse represents the response when creating a batch of tasks.
+// When any spec fails validation no task is created and Created is false.
+type CreateTaskBatchResponse struct {
+	BatchID   string                `json:"batch_id,omitempty" example:"batch-12345-abcde"`
+	Created   bool                  `json:"created" example:"true"`
+	Results   []TaskBatchItemResult `json:"results"`
+	CreatedAt time.Time             `json:"created_at" example:"2024-01-15T10:30:00Z"`
+} //@name CreateTaskBatchResponse
+
+// GetTaskBatchRequest represents the request to get the progress of a batch
+type GetTaskBatchRequest struct {
+	BatchID string `uri:"batchId" validate:"required" example:"batch-12345-abcde"`
+} //@name GetTaskBatchRequest
+
+// GetTaskBatchResponse represents the aggregate progress of a batch of tasks
+type GetTaskBatchResponse struct {
+	BatchID      string             `json:"batch_id" example:"batch-12345-abcde"`
+	TotalCount   int                `json:"total_count" example:"50"`
+	StatusCounts map[TaskStatus]int `json:"status_counts"`
+	Progress     float64            `json:"progress" example:"42.5"` // Percentage of tasks in a terminal status
+	Done         bool               `json:"done" example:"false"`
+	TaskIDs      []string           `json:"task_ids"`
+} //@name GetTaskBatchResponse
@@ -0,0 +1,92 @@
+// Package models provides data structures for comments and review threads on tasks
+package models
+
+import "time"
+
+// TaskComment is a markdown comment on a task. Comments without a parent start a review thread and may be
+// anchored to a file and line range of the task's generated diff; replies belong to the thread of their parent.
+type TaskComment struct {
+	// Unique identifier for the comment
+	CommentID string `json:"comment_id" db:"comment_id" example:"comment-12345-abcde"`
+	// Task the comment belongs to
+	TaskID string `json:"task_id" db:"task_id" example:"task-12345-abcde"`
+	// Comment that started the thread, absent for thread-starting comments
+	ParentID *string `json:"parent_id,omitempty" db:"parent_id" example:"comment-67890-fghij"`
+	// User who wrote the comment
+	AuthorID string `json:"author_id" db:"author_id" example:"user-12345"`
+	// Markdown body
+	Body string `json:"body" db:"body" example:"This extraction changes the error handling, see line 42."`
+	// Location in the generated diff the thread is about
+	Anchor *TaskCommentAnchor `json:"anchor,omitempty" db:"anchor"`
+	// Creation timestamp
+	CreatedAt time.Time `json:"created_at" db:"created_at" example:"2024-01-15T10:30:00Z"`
+	// Last edit timestamp
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at" example:"2024-01-15T10:30:00Z"`
+} //@name TaskComment
+
+// TaskCommentAnchor points a review thread at a line range of a file in the task's generated diff
+type TaskCommentAnchor struct {
+	// File path within the diff
+	FilePath string `json:"file_path" validate:"required,max=1024" example:"internal/payments/charge.go"`
+	// First line of the range in the changed file
+	StartLine int `json:"start_line" validate:"required,min=1" example:"40"`
+	// Last line of the range, defaults to start_line
+	EndLine int `json:"end_line,omitempty" validate:"omitempty,gtefield=StartLine" example:"42"`
+} //@name TaskCommentAnchor
+
+// CreateTaskCommentRequest represents the request to comment on a task
+type CreateTaskCommentRequest struct {
+	// Task to comment on
+	TaskID string `uri:"id" validate:"required" example:"task-12345-abcde"`
+	// Markdown body
+	Body string `json:"body" validate:"required,min=1,max=10000" example:"This extraction changes the error handling, see line 42."`
+	// Comment whose thread the reply belongs to
+	ParentID *string `json:"parent_id,omitempty" validate:"omitempty,min=1" example:"comment-67890-fghij"`
+	// Location in the generated diff, only for thread-starting comments
+	Anchor *TaskCommentAnchor `json:"anchor,omitempty" validate:"omitempty"`
+
+	// Authenticated caller, set by the controller
+	AuthorID string `json:"-"`
+} //@name CreateTaskCommentRequest
+
+// ListTaskCommentsRequest represents the request to list a task's comments
+type ListTaskCommentsRequest struct {
+	// Task whose comments are listed
+	TaskID string `uri:"id" validate:"required" example:"task-12345-abcde"`
+} //@name ListTaskCommentsRequest
+
+// ListTaskCommentsResponse represents the response when listing a task's comments
+type ListTaskCommentsResponse struct {
+	// Comments, oldest first
+	Comments []TaskComment `json:"comments"`
+} //@name ListTaskCommentsResponse
+
+// UpdateTaskCommentRequest represents the request to edit a comment
+type UpdateTaskCommentRequest struct {
+	// Task the comment belongs to
+	TaskID string `uri:"id" validate:"required" example:"task-12345-abcde"`
+	// Comment to edit
+	CommentID string `uri:"comment_id" validate:"required" example:"comment-12345-abcde"`
+	// New markdown body
+	Body string `json:"body" validate:"required,min=1,max=10000" example:"Never mind, the caller handles it."`
+
+	// Authenticated caller, set by the controller
+	AuthorID string `json:"-"`
+} //@name UpdateTaskCommentRequest
+
+// DeleteTaskCommentRequest represents the request to delete a comment
+type DeleteTaskCommentRequest struct {
+	// Task the comment belongs to
+	TaskID string `uri:"id" validate:"required" example:"task-12345-abcde"`
+	// Comment to delete; deleting a thread-starting comment deletes its replies
+	CommentID string `uri:"comment_id" validate:"required" example:"comment-12345-abcde"`
+
+	// Authenticated caller, set by the controller
+	AuthorID string `json:"-"`
+} //@name DeleteTaskCommentRequest
+
+// DeleteTaskCommentResponse represents the response when deleting a comment
+type DeleteTaskCommentResponse struct {
+	// Whether the deletion was successful
+	Success bool `json:"success" example:"true"`
+} //@name DeleteTaskCommentResponse
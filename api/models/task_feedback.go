@@ -0,0 +1,61 @@
+// Package models provides data structures for user feedback on task results
+package models
+
+import "time"
+
+// TaskFeedbackOutcome is what a user did with the result of a task
+type TaskFeedbackOutcome string
+
+const (
+	// TaskFeedbackOutcomeAccepted means the result was merged or otherwise kept
+	TaskFeedbackOutcomeAccepted TaskFeedbackOutcome = "accepted"
+
+	// TaskFeedbackOutcomeRejected means the result was discarded
+	TaskFeedbackOutcomeRejected TaskFeedbackOutcome = "rejected"
+)
+
+// TaskFeedback is a user's rating and outcome of a completed task's result. Each user gives a task a single
+// feedback, which they may revise.
+type TaskFeedback struct {
+	// Task the feedback is about
+	TaskID string `json:"task_id" db:"task_id" example:"task-12345-abcde"`
+	// User who gave the feedback
+	UserID string `json:"user_id" db:"user_id" example:"user-12345"`
+	// Rating of the result, from 1 to 5
+	Rating int `json:"rating" db:"rating" example:"4"`
+	// Free-text feedback
+	Comment string `json:"comment,omitempty" db:"comment" example:"Good extraction, but the new function needed a better name."`
+	// Whether the result was accepted or rejected
+	Outcome TaskFeedbackOutcome `json:"outcome" db:"outcome" example:"accepted"`
+	// Timestamp of the first feedback
+	CreatedAt time.Time `json:"created_at" db:"created_at" example:"2024-01-15T10:30:00Z"`
+	// Timestamp of the last revision
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at" example:"2024-01-15T10:30:00Z"`
+} //@name TaskFeedback
+
+// SubmitTaskFeedbackRequest represents the request to give feedback on a task's result
+type SubmitTaskFeedbackRequest struct {
+	// Task the feedback is about
+	TaskID string `uri:"id" validate:"required" example:"task-12345-abcde"`
+	// Rating of the result, from 1 to 5
+	Rating int `json:"rating" validate:"required,min=1,max=5" example:"4"`
+	// Free-text feedback
+	Comment string `json:"comment,omitempty" validate:"max=5000" example:"Good extraction, but the new function needed a better name."`
+	// Whether the result was accepted or rejected
+	Outcome TaskFeedbackOutcome `json:"outcome" validate:"required,oneof=accepted rejected" example:"accepted"`
+
+	// Authenticated caller, set by the controller
+	UserID string `json:"-"`
+} //@name SubmitTaskFeedbackRequest
+
+// ListTaskFeedbackRequest represents the request to list the feedback on a task
+type ListTaskFeedbackRequest struct {
+	// Task whose feedback is listed
+	TaskID string `uri:"id" validate:"required" example:"task-12345-abcde"`
+} //@name ListTaskFeedbackRequest
+
+// ListTaskFeedbackResponse represents the response when listing the feedback on a task
+type ListTaskFeedbackResponse struct {
+	// Feedback, oldest first
+	Feedback []TaskFeedback `json:"feedback"`
+} //@name ListTaskFeedbackResponse
@@ -0,0 +1,118 @@
+// Package models provides data structures for task metrics reporting
+package models
+
+const (
+	// TaskOutputTokenUsageKey is the task output field holding the number of tokens the agent consumed
+	TaskOutputTokenUsageKey = "token_usage"
+
+	// TaskOutputFailureCategoryKey is the task output field holding the category of a failure
+	TaskOutputFailureCategoryKey = "failure_category"
+
+	// TaskOutputPullRequestURLKey is the task output field holding the URL of the pull request the task opened
+	TaskOutputPullRequestURLKey = "pull_request_url"
+
+	// TaskOutputFindingsKey is the task output field holding the findings of a static analysis
+	TaskOutputFindingsKey = "findings"
+
+	// TaskOutputSeededTaskIDsKey is the task output field holding the refactoring tasks a static analysis created
+	TaskOutputSeededTaskIDsKey = "seeded_task_ids"
+
+	// TaskOutputMetricsKey is the task output field holding the code metrics snapshot a static analysis recorded
+	TaskOutputMetricsKey = "metrics"
+
+	// TaskOutputPackageGraphKey is the task output field holding the package graph, as JSON and DOT, a package
+	// structure analysis built
+	TaskOutputPackageGraphKey = "package_graph"
+
+	// TaskOutputQualityGatesKey is the task output field holding the results of the project's quality gates after a
+	// static analysis
+	TaskOutputQualityGatesKey = "quality_gates"
+
+	// TaskOutputFileHistoryKey is the task output field holding the git history of the files a static analysis found
+	// changed the most
+	TaskOutputFileHistoryKey = "file_history"
+
+	// FailureCategoryUncategorized is reported for failed tasks without a failure category
+	FailureCategoryUncategorized = "uncategorized"
+
+	// DefaultTaskReportDays is the report range used when no start date is requested
+	DefaultTaskReportDays = 30
+
+	// MaxTaskReportDays is the longest range a single report may cover
+	MaxTaskReportDays = 366
+
+	// ReportDateLayout is the date format of report ranges and day and week buckets
+	ReportDateLayout = "2006-01-02"
+)
+
+// ReportGroupBy is the dimension task metrics are bucketed by
+type ReportGroupBy string
+
+const (
+	// ReportGroupByDay buckets metrics by UTC calendar day
+	ReportGroupByDay ReportGroupBy = "day"
+
+	// ReportGroupByWeek buckets metrics by ISO week, keyed by the Monday starting it
+	ReportGroupByWeek ReportGroupBy = "week"
+
+	// ReportGroupByProject buckets metrics by project
+	ReportGroupByProject ReportGroupBy = "project"
+)
+
+// GetTaskReportRequest represents the request for aggregated task metrics
+type GetTaskReportRequest struct {
+	// Restrict the report to a single project
+	ProjectID string `form:"project_id" validate:"omitempty,project_id" example:"proj-12345-abcde"`
+	// Dimension to group the metrics by
+	GroupBy ReportGroupBy `form:"group_by" validate:"omitempty,oneof=day week project" example:"day"`
+	// First day of the report, inclusive (defaults to 30 days before to)
+	From string `form:"from" validate:"omitempty,datetime=2006-01-02" example:"2024-01-01"`
+	// Last day of the report, inclusive (defaults to today)
+	To string `form:"to" validate:"omitempty,datetime=2006-01-02" example:"2024-01-31"`
+} //@name GetTaskReportRequest
+
+// GetTaskReportResponse contains task metrics grouped into buckets
+type GetTaskReportResponse struct {
+	// Dimension the metrics are grouped by
+	GroupBy ReportGroupBy `json:"group_by" example:"day"`
+	// First day of the report, inclusive
+	From string `json:"from" example:"2024-01-01"`
+	// Last day of the report, inclusive
+	To string `json:"to" example:"2024-01-31"`
+	// When the underlying rollups were last refreshed; tasks created afterwards are not included yet
+	RefreshedAt *string `json:"refreshed_at,omitempty" example:"2024-01-31T12:00:00Z"`
+	// Metrics per bucket, ordered by key
+	Buckets []TaskReportBucket `json:"buckets"`
+} //@name GetTaskReportResponse
+
+// TaskReportBucket contains the metrics of the tasks created within one day, week or project
+type TaskReportBucket struct {
+	// Day or week start date (YYYY-MM-DD), or project ID
+	Key string `json:"key" example:"2024-01-15"`
+	// Number of tasks created
+	Total int `json:"total" example:"40"`
+	// Number of completed tasks
+	Completed int `json:"completed" example:"30"`
+	// Number of failed tasks
+	Failed int `json:"failed" example:"6"`
+	// Number of cancelled tasks
+	Cancelled int `json:"cancelled" example:"4"`
+	// Completed tasks as a fraction of completed and failed tasks
+	SuccessRate float64 `json:"success_rate" example:"0.83"`
+	// Mean time from creation to completion of finished tasks, in milliseconds
+	MeanDurationMs int64 `json:"mean_duration_ms" example:"45000"`
+	// Tokens consumed by the agents
+	TokenUsage int64 `json:"token_usage" example:"120000"`
+	// Number of failed tasks per failure category
+	FailureCategories map[string]int `json:"failure_categories" example:"timeout:4,uncategorized:2"`
+	// Number of feedback given on the tasks
+	Feedback int `json:"feedback" example:"12"`
+	// Number of feedback accepting the result
+	Accepted int `json:"accepted" example:"9"`
+	// Number of feedback rejecting the result
+	Rejected int `json:"rejected" example:"3"`
+	// Accepting feedback as a fraction of all feedback
+	AcceptanceRate float64 `json:"acceptance_rate" example:"0.75"`
+	// Mean rating of the feedback, from 1 to 5
+	MeanRating float64 `json:"mean_rating" example:"3.9"`
+} //@name TaskReportBucket
@@ -0,0 +1,35 @@
+// Package models provides data structures for the large task inputs uploaded to S3 instead of sent as JSON
+package models
+
+import "time"
+
+// TaskInputUploadIDKey is the input field referencing an upload of the task's project as the task's input file
+const TaskInputUploadIDKey = "upload_id"
+
+// TaskInputUploadKey is the input field the task's upload is described in once the task is created, for executors to
+// read the file from
+const TaskInputUploadKey = "upload"
+
+// UploadFormField is the field of the multipart form holding the uploaded file
+const UploadFormField = "file"
+
+// UploadTaskInputRequest represents the request to upload a task input file to a project
+type UploadTaskInputRequest struct {
+	ProjectID string `uri:"project_id" validate:"required,project_id" example:"proj-12345-abcde"`
+} //@name UploadTaskInputRequest
+
+// TaskInputUpload is a file uploaded as the input of a project's tasks, such as a diff or an archive to analyze
+type TaskInputUpload struct {
+	// Identifier tasks reference the upload by, in their upload_id input
+	UploadID  string `json:"upload_id" example:"upl-12345-abcde"`
+	ProjectID string `json:"project_id" example:"proj-12345-abcde"`
+	// Name of the uploaded file
+	Filename    string `json:"filename" example:"change.diff"`
+	ContentType string `json:"content_type" example:"text/x-diff"`
+	// Size of the file in bytes
+	Size int64 `json:"size" example:"5242880"`
+	// Location of the file, such as s3://bucket/task-inputs/proj-12345-abcde/upl-12345-abcde
+	URI        string    `json:"uri" example:"s3://code-refactor-bucket/task-inputs/proj-12345-abcde/upl-12345-abcde"`
+	UploadedBy string    `json:"uploaded_by,omitempty" example:"user-12345"`
+	CreatedAt  time.Time `json:"created_at" example:"2024-01-15T10:30:00Z"`
+} //@name TaskInputUpload
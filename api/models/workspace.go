@@ -0,0 +1,58 @@
+// Package models provides data structures for the workspaces codebases are cloned into on task runners
+package models
+
+import "time"
+
+// WorkspaceStatus is whether a workspace is checked out by a running analysis
+type WorkspaceStatus string
+
+const (
+	// WorkspaceStatusInUse is a workspace an analysis is reading
+	WorkspaceStatusInUse WorkspaceStatus = "in_use"
+
+	// WorkspaceStatusIdle is a workspace kept on disk for later analyses of the same commit, evicted first when the
+	// disk quota is reached
+	WorkspaceStatusIdle WorkspaceStatus = "idle"
+)
+
+// Workspace is a directory a codebase is cloned into on a task runner
+type Workspace struct {
+	// Unique identifier for the workspace, also the name of its directory
+	WorkspaceID string `json:"workspace_id" db:"workspace_id" example:"ws-12345-abcde"`
+	// Task that last checked out the workspace, absent for ingestion scans
+	TaskID string `json:"task_id,omitempty" db:"task_id" example:"task-12345"`
+	// Cloned codebase
+	CodebaseID string `json:"codebase_id" db:"codebase_id" example:"codebase-12345"`
+	// Checked out commit
+	CommitSHA string `json:"commit_sha" db:"commit_sha" example:"9fceb02d0ae598e95dc970b74767f19372d61af8"`
+	// Task runner holding the workspace on its disk
+	Host string `json:"host" db:"host" example:"runner-1"`
+	// Directory of the workspace
+	Path string `json:"path" db:"path" example:"/var/lib/code-refactor/workspaces/ws-12345-abcde"`
+	// Size on disk, measured when the workspace was last released
+	SizeBytes int64 `json:"size_bytes" db:"size_bytes" example:"52428800"`
+	// Whether an analysis is reading the workspace
+	Status WorkspaceStatus `json:"status" db:"status" example:"idle"`
+	// Creation timestamp
+	CreatedAt time.Time `json:"created_at" db:"created_at" example:"2024-01-15T10:30:00Z"`
+	// When the workspace was last checked out or released
+	LastUsedAt time.Time `json:"last_used_at" db:"last_used_at" example:"2024-01-15T10:35:00Z"`
+} //@name Workspace
+
+// GetWorkspaceUsageResponse reports the disk used by the workspaces of the task runner serving the request
+type GetWorkspaceUsageResponse struct {
+	// Task runner the usage is reported for
+	Host string `json:"host" example:"runner-1"`
+	// Directory the workspaces are created in
+	Root string `json:"root" example:"/var/lib/code-refactor/workspaces"`
+	// Disk quota of the workspaces
+	QuotaBytes int64 `json:"quota_bytes" example:"10737418240"`
+	// Disk used by all workspaces
+	UsedBytes int64 `json:"used_bytes" example:"104857600"`
+	// Disk used by the workspaces analyses are reading
+	InUseBytes int64 `json:"in_use_bytes" example:"52428800"`
+	// Disk used by the idle workspaces, reclaimed on demand
+	IdleBytes int64 `json:"idle_bytes" example:"52428800"`
+	// Workspaces from least to most recently used
+	Workspaces []Workspace `json:"workspaces"`
+} //@name GetWorkspaceUsageResponse
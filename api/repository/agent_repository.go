@@ -6,22 +6,27 @@ import (
 	"time"
 
 	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
 )
 
 // AgentRecord represents the agent data stored in the database
 type AgentRecord struct {
-	AgentID         string    `json:"agent_id" db:"agent_id"`
-	AgentVersion    string    `json:"agent_version" db:"agent_version"`
-	KnowledgeBaseID string    `json:"knowledge_base_id" db:"knowledge_base_id"`
-	VectorStoreID   string    `json:"vector_store_id" db:"vector_store_id"`
-	RepositoryURL   string    `json:"repository_url" db:"repository_url"`
-	Branch          string    `json:"branch,omitempty" db:"branch"`
-	AgentName       string    `json:"agent_name,omitempty" db:"agent_name"`
-	Status          string    `json:"status" db:"status"`
-	AIProvider      string    `json:"ai_provider,omitempty" db:"ai_provider"`
-	AIConfigJSON    string    `json:"ai_config_json,omitempty" db:"ai_config_json"`
-	CreatedAt       time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+	AgentID             string    `json:"agent_id" db:"agent_id"`
+	ProjectID           string    `json:"project_id,omitempty" db:"project_id"`
+	AgentVersion        string    `json:"agent_version" db:"agent_version"`
+	KnowledgeBaseID     string    `json:"knowledge_base_id" db:"knowledge_base_id"`
+	VectorStoreID       string    `json:"vector_store_id" db:"vector_store_id"`
+	RepositoryURL       string    `json:"repository_url" db:"repository_url"`
+	Branch              string    `json:"branch,omitempty" db:"branch"`
+	AgentName           string    `json:"agent_name,omitempty" db:"agent_name"`
+	Status              string    `json:"status" db:"status"`
+	AIProvider          string    `json:"ai_provider,omitempty" db:"ai_provider"`
+	AIConfigJSON        string    `json:"ai_config_json,omitempty" db:"ai_config_json"`
+	Region              string    `json:"region,omitempty" db:"region"`
+	EmbeddingModel      string    `json:"embedding_model,omitempty" db:"embedding_model"`
+	EmbeddingDimensions int32     `json:"embedding_dimensions,omitempty" db:"embedding_dimensions"`
+	CreatedAt           time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at" db:"updated_at"`
 }
 
 // ToResponse converts AgentRecord to CreateAgentResponse
@@ -42,12 +47,14 @@ func NewAgentRecord(request models.CreateAgentRequest, agentID, agentVersion, kb
 
 	record := &AgentRecord{
 		AgentID:         agentID,
+		ProjectID:       request.ProjectID,
 		AgentVersion:    agentVersion,
 		KnowledgeBaseID: kbID,
 		VectorStoreID:   vectorStoreID,
 		RepositoryURL:   request.RepositoryURL,
 		Branch:          request.Branch,
 		AgentName:       request.AgentName,
+		Region:          request.Region,
 		Status:          string(models.AgentStatusReady),
 		CreatedAt:       now,
 		UpdatedAt:       now,
@@ -61,6 +68,18 @@ func NewAgentRecord(request models.CreateAgentRequest, agentID, agentVersion, kb
 	return record
 }
 
+// Embedding returns the embedding of the agent's knowledge base, zero for agents embedding with the default model or
+// their provider's configured model
+func (r *AgentRecord) Embedding() config.Embedding {
+	return config.Embedding{Model: r.EmbeddingModel, Dimensions: r.EmbeddingDimensions}
+}
+
+// SetEmbedding records the embedding of the agent's knowledge base
+func (r *AgentRecord) SetEmbedding(embedding config.Embedding) {
+	r.EmbeddingModel = embedding.Model
+	r.EmbeddingDimensions = embedding.Dimensions
+}
+
 // GetAIProvider returns the AI provider for the agent
 func (r *AgentRecord) GetAIProvider() models.AIProvider {
 	return models.AIProvider(r.AIProvider)
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// AgentResyncSettingsRepository defines the interface for per-project agent resync settings data operations
+//
+//go:generate mockgen -destination=./mocks/mock_agent_resync_settings_repository.go -mock_names=AgentResyncSettingsRepository=MockAgentResyncSettingsRepository -package=mocks . AgentResyncSettingsRepository
+type AgentResyncSettingsRepository interface {
+	// GetSettings retrieves the agent resync settings of a project, returning nil if none are stored
+	GetSettings(ctx context.Context, projectID string) (*models.AgentResyncSettings, error)
+
+	// SaveSettings creates or replaces the agent resync settings of a project
+	SaveSettings(ctx context.Context, settings *models.AgentResyncSettings) error
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// AuditEventRepository defines the interface for the audit log of changes to access
+//
+//go:generate mockgen -destination=./mocks/mock_audit_event_repository.go -mock_names=AuditEventRepository=MockAuditEventRepository -package=mocks . AuditEventRepository
+type AuditEventRepository interface {
+	// RecordEvent appends an event to the audit log
+	RecordEvent(ctx context.Context, event *models.AuditEvent) error
+
+	// StreamEvents passes the events that occurred within timeRange to fn, oldest first, without loading them all in
+	// memory. An error returned by fn stops the stream.
+	StreamEvents(ctx context.Context, timeRange TimeRange, fn func(models.AuditEvent) error) error
+}
+
+// TimeRange bounds a stream to the items from From, inclusive, until To, exclusive. A nil bound leaves that side open.
+type TimeRange struct {
+	From *time.Time
+	To   *time.Time
+}
+
+// whereClause builds the WHERE clause bounding column to the range, with its arguments
+func (t TimeRange) whereClause(column string) (string, []any) {
+	var conditions []string
+	var args []any
+	if t.From != nil {
+		args = append(args, *t.From)
+		conditions = append(conditions, fmt.Sprintf("%s >= $%d", column, len(args)))
+	}
+	if t.To != nil {
+		args = append(args, *t.To)
+		conditions = append(conditions, fmt.Sprintf("%s < $%d", column, len(args)))
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// Contains reports whether at falls within the range
+func (t TimeRange) Contains(at time.Time) bool {
+	return (t.From == nil || !at.Before(*t.From)) && (t.To == nil || at.Before(*t.To))
+}
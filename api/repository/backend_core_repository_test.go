@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+func TestRoleRepository_ProjectMembers(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos testRepositories, _ *memoryOutbox) {
+		ctx := context.Background()
+		repo := repos.roles
+
+		owner, err := repo.GetRole(ctx, models.RoleOwner)
+		require.NoError(t, err)
+		require.NotNil(t, owner)
+		assert.True(t, owner.BuiltIn)
+
+		require.NoError(t, repo.SetProjectMember(ctx, &models.ProjectMember{ProjectID: "proj-1", UserID: "alice", Role: models.RoleOwner}))
+		err = repo.SetProjectMember(ctx, &models.ProjectMember{ProjectID: "proj-1", UserID: "bob", Role: models.RoleOwner})
+		assert.ErrorIs(t, err, apperrors.ErrConflict)
+		err = repo.SetProjectMember(ctx, &models.ProjectMember{ProjectID: "proj-1", UserID: "bob", Role: "unknown"})
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+		transferredAt := time.Now().UTC()
+		require.NoError(t, repo.TransferProjectOwnership(ctx, "proj-1", "bob", transferredAt))
+		members, err := repo.ListProjectMembers(ctx, "proj-1")
+		require.NoError(t, err)
+		require.Len(t, members, 2)
+		assert.Equal(t, models.RoleAdmin, members[0].Role)
+		assert.Equal(t, models.RoleOwner, members[1].Role)
+
+		err = repo.DeleteRole(ctx, models.RoleAdmin)
+		assert.ErrorIs(t, err, apperrors.ErrConflict)
+		err = repo.CreateRole(ctx, &models.Role{Name: "auditor", Permissions: []models.Permission{"unknown:permission"}})
+		assert.ErrorIs(t, err, apperrors.ErrValidation)
+	})
+}
+
+func TestCodebaseConfigRepository_UpdateCodebaseConfig(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos testRepositories, _ *memoryOutbox) {
+		ctx := context.Background()
+		repo := repos.configs
+
+		config := &CodebaseConfigRecord{ConfigID: "config-1", Name: "first", Tags: map[string]string{"team": "billing"}}
+		require.NoError(t, repo.CreateCodebaseConfig(ctx, config))
+		assert.Equal(t, int64(1), config.Version)
+		assert.ErrorIs(t, repo.CreateCodebaseConfig(ctx, &CodebaseConfigRecord{ConfigID: "config-1"}), apperrors.ErrConflict)
+
+		config.Tags["team"] = "changed without saving"
+		stored, err := repo.GetCodebaseConfig(ctx, "config-1")
+		require.NoError(t, err)
+		assert.Equal(t, "billing", stored.Tags["team"])
+
+		stored.Name = "second"
+		require.NoError(t, repo.UpdateCodebaseConfig(ctx, stored))
+		assert.Equal(t, int64(2), stored.Version)
+
+		stale := &CodebaseConfigRecord{ConfigID: "config-1", Name: "stale", Version: 1}
+		assert.ErrorIs(t, repo.UpdateCodebaseConfig(ctx, stale), apperrors.ErrPreconditionFailed)
+	})
+}
+
+func TestWorkspaceRepository_ClaimIdleWorkspace(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos testRepositories, _ *memoryOutbox) {
+		ctx := context.Background()
+		repo := repos.workspace
+		now := time.Now()
+		for _, workspace := range []models.Workspace{
+			{WorkspaceID: "ws-old", Host: "runner-1", CodebaseID: "cb-1", CommitSHA: "abc", Status: models.WorkspaceStatusIdle, LastUsedAt: now.Add(-time.Hour)},
+			{WorkspaceID: "ws-new", Host: "runner-1", CodebaseID: "cb-1", CommitSHA: "abc", Status: models.WorkspaceStatusIdle, LastUsedAt: now},
+			{WorkspaceID: "ws-other", Host: "runner-2", CodebaseID: "cb-1", CommitSHA: "abc", Status: models.WorkspaceStatusIdle, LastUsedAt: now},
+		} {
+			require.NoError(t, repo.CreateWorkspace(ctx, &workspace))
+		}
+
+		claimed, err := repo.ClaimIdleWorkspace(ctx, "runner-1", "cb-1", "abc", "task-1")
+		require.NoError(t, err)
+		require.NotNil(t, claimed)
+		assert.Equal(t, "ws-new", claimed.WorkspaceID)
+		assert.Equal(t, models.WorkspaceStatusInUse, claimed.Status)
+
+		claimed, err = repo.ClaimIdleWorkspace(ctx, "runner-1", "cb-1", "abc", "task-2")
+		require.NoError(t, err)
+		require.NotNil(t, claimed)
+		assert.Equal(t, "ws-old", claimed.WorkspaceID)
+
+		claimed, err = repo.ClaimIdleWorkspace(ctx, "runner-1", "cb-1", "abc", "task-3")
+		require.NoError(t, err)
+		assert.Nil(t, claimed)
+	})
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+func TestProjectRepository_UpdateProject(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos testRepositories, _ *memoryOutbox) {
+		ctx := context.Background()
+		project := &ProjectRecord{ProjectID: "proj-1", Name: "first", Status: string(models.ProjectStatusActive), Tags: map[string]string{"team": "billing"}}
+		require.NoError(t, repos.projects.CreateProject(ctx, project))
+		assert.Equal(t, int64(1), project.Version)
+
+		err := repos.projects.CreateProject(ctx, &ProjectRecord{ProjectID: "proj-1", Name: "again", Status: string(models.ProjectStatusActive)})
+		assert.ErrorIs(t, err, apperrors.ErrConflict)
+
+		project.Tags["team"] = "changed without saving"
+		stored, err := repos.projects.GetProject(ctx, "proj-1")
+		require.NoError(t, err)
+		assert.Equal(t, "billing", stored.Tags["team"])
+
+		stored.Name = "second"
+		require.NoError(t, repos.projects.UpdateProject(ctx, stored))
+		assert.Equal(t, int64(2), stored.Version)
+
+		stale := &ProjectRecord{ProjectID: "proj-1", Name: "stale", Status: string(models.ProjectStatusActive), Version: 1}
+		err = repos.projects.UpdateProject(ctx, stale)
+		assert.ErrorIs(t, err, apperrors.ErrPreconditionFailed)
+
+		missing, err := repos.projects.GetProject(ctx, "proj-2")
+		require.NoError(t, err)
+		assert.Nil(t, missing)
+	})
+}
+
+func TestProjectRepository_ListProjects(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos testRepositories, _ *memoryOutbox) {
+		ctx := context.Background()
+		for _, projectID := range []string{"proj-c", "proj-a", "proj-b", "proj-d", "proj-e"} {
+			project := &ProjectRecord{ProjectID: projectID, Name: projectID, Status: string(models.ProjectStatusActive), Tags: map[string]string{"team": "billing"}}
+			switch projectID {
+			case "proj-d":
+				project.Tags["team"] = "search"
+			case "proj-e":
+				project.Status = string(models.ProjectStatusArchived)
+			}
+			require.NoError(t, repos.projects.CreateProject(ctx, project))
+		}
+
+		maxResults := 2
+		projects, nextToken, err := repos.projects.ListProjects(ctx, ListProjectsOptions{MaxResults: &maxResults, TagFilter: map[string]string{"team": "billing"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"proj-a", "proj-b"}, projectIDs(projects))
+		assert.Equal(t, "proj-b", nextToken)
+
+		projects, nextToken, err = repos.projects.ListProjects(ctx, ListProjectsOptions{MaxResults: &maxResults, NextToken: &nextToken, TagFilter: map[string]string{"team": "billing"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"proj-c"}, projectIDs(projects))
+		assert.Empty(t, nextToken)
+
+		projects, _, err = repos.projects.ListProjects(ctx, ListProjectsOptions{IncludeArchived: true, TagFilter: map[string]string{"team": "billing"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"proj-a", "proj-b", "proj-c", "proj-e"}, projectIDs(projects))
+	})
+}
+
+func TestCodebaseRepository_ListCodebases(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos testRepositories, _ *memoryOutbox) {
+		ctx := context.Background()
+		require.NoError(t, repos.projects.CreateProject(ctx, &ProjectRecord{ProjectID: "proj-1", Name: "first", Status: string(models.ProjectStatusActive)}))
+
+		err := repos.codebases.CreateCodebase(ctx, testCodebase(uuid.NewString(), "proj-2", time.Now()))
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+		now := time.Now().UTC()
+		codebaseIDs := []string{uuid.NewString(), uuid.NewString(), uuid.NewString()}
+		for i, codebaseID := range codebaseIDs {
+			require.NoError(t, repos.codebases.CreateCodebase(ctx, testCodebase(codebaseID, "proj-1", now.Add(time.Duration(i)*time.Minute))))
+		}
+
+		maxResults := 2
+		tagFilter := "env:prod"
+		codebases, nextToken, err := repos.codebases.ListCodebases(ctx, CodebaseFilter{MaxResults: &maxResults, TagFilter: &tagFilter})
+		require.NoError(t, err)
+		assert.Equal(t, []string{codebaseIDs[2], codebaseIDs[1]}, testCodebaseIDs(codebases))
+
+		codebases, nextToken, err = repos.codebases.ListCodebases(ctx, CodebaseFilter{MaxResults: &maxResults, NextToken: &nextToken, TagFilter: &tagFilter})
+		require.NoError(t, err)
+		assert.Equal(t, []string{codebaseIDs[0]}, testCodebaseIDs(codebases))
+		assert.Empty(t, nextToken)
+
+		_, err = repos.codebases.GetCodebase(ctx, uuid.NewString())
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	})
+}
+
+func TestUserRepository_CreateUser(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos testRepositories, _ *memoryOutbox) {
+		ctx := context.Background()
+		user, err := repos.users.CreateUser(ctx, &models.DBUser{Email: "ada@example.com", Username: "ada", AuthID: "auth-1", Role: "developer", Status: models.UserStatusActive})
+		require.NoError(t, err)
+		assert.NotEmpty(t, user.UserID)
+
+		_, err = repos.users.CreateUser(ctx, &models.DBUser{Email: "ada@example.com", Username: "lovelace", AuthID: "auth-2", Role: "developer", Status: models.UserStatusActive})
+		assert.ErrorIs(t, err, apperrors.ErrConflict)
+
+		found, err := repos.users.GetUserByAuthID(ctx, "auth-1")
+		require.NoError(t, err)
+		assert.Equal(t, user.UserID, found.UserID)
+
+		_, err = repos.users.GetUserByEmail(ctx, "grace@example.com")
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+		users, total, err := repos.users.ListUsers(ctx, &ListUsersFilter{Search: "ADA", Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, 1, total)
+		assert.Len(t, users, 1)
+	})
+}
+
+func TestAgentRepository_UpdateAgentStatus(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos testRepositories, _ *memoryOutbox) {
+		ctx := context.Background()
+		now := time.Now().UTC()
+		agent := &AgentRecord{AgentID: "agent-1", AgentVersion: "1", RepositoryURL: "https://github.com/org/repo", Status: string(models.AgentStatusPending), CreatedAt: now, UpdatedAt: now}
+		require.NoError(t, repos.agents.CreateAgent(ctx, agent))
+		assert.ErrorIs(t, repos.agents.CreateAgent(ctx, agent), apperrors.ErrConflict)
+
+		require.NoError(t, repos.agents.UpdateAgentStatus(ctx, "agent-1", models.AgentStatusReady))
+		stored, err := repos.agents.GetAgent(ctx, "agent-1")
+		require.NoError(t, err)
+		assert.Equal(t, string(models.AgentStatusReady), stored.Status)
+
+		assert.ErrorIs(t, repos.agents.UpdateAgentStatus(ctx, "agent-2", models.AgentStatusReady), apperrors.ErrNotFound)
+	})
+}
+
+// testCodebase returns a codebase of a project tagged env:prod
+func testCodebase(codebaseID, projectID string, createdAt time.Time) *models.Codebase {
+	return &models.Codebase{
+		CodebaseID: codebaseID,
+		ProjectID:  projectID,
+		Name:       "repo",
+		Provider:   models.ProviderGitHub,
+		URL:        "https://github.com/org/repo",
+		ConfigID:   "config-1",
+		Status:     models.CodebaseStatusActive,
+		Tags:       map[string]string{"env": "prod"},
+		CreatedAt:  createdAt,
+		UpdatedAt:  createdAt,
+	}
+}
+
+// projectIDs returns the IDs of projects
+func projectIDs(projects []*ProjectRecord) []string {
+	ids := make([]string, 0, len(projects))
+	for _, project := range projects {
+		ids = append(ids, project.ProjectID)
+	}
+	return ids
+}
+
+// testCodebaseIDs returns the IDs of codebases
+func testCodebaseIDs(codebases []*models.Codebase) []string {
+	ids := make([]string, 0, len(codebases))
+	for _, codebase := range codebases {
+		ids = append(ids, codebase.CodebaseID)
+	}
+	return ids
+}
@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// createTestTasks creates tasks in repo one at a time, so each is created after the one before
+func createTestTasks(t *testing.T, repo TaskRepository, tasks ...*models.Task) {
+	t.Helper()
+	for _, task := range tasks {
+		require.NoError(t, repo.Create(context.Background(), task))
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestTaskRepository_ListByProject(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos testRepositories, _ *memoryOutbox) {
+		ctx := context.Background()
+		codebaseID := "codebase-1"
+		createTestTasks(t, repos.tasks,
+			&models.Task{TaskID: "task-1", ProjectID: "proj-1", Type: models.TaskTypeRefactoring, Status: models.TaskStatusPending, Tags: map[string]string{"team": "billing"}},
+			&models.Task{TaskID: "task-2", ProjectID: "proj-1", Type: models.TaskTypeCodeReview, Status: models.TaskStatusPending, CodebaseID: &codebaseID, Tags: map[string]string{"team": "billing"}},
+			&models.Task{TaskID: "task-3", ProjectID: "proj-1", Type: models.TaskTypeRefactoring, Status: models.TaskStatusPending, Tags: map[string]string{"team": "billing"}, Output: map[string]any{"owners": []string{"@billing"}}},
+			&models.Task{TaskID: "task-4", ProjectID: "proj-2", Type: models.TaskTypeRefactoring, Status: models.TaskStatusPending, Tags: map[string]string{"team": "billing"}},
+		)
+
+		tasks, total, err := repos.tasks.ListByProject(ctx, "proj-1", TaskFilters{Tags: map[string]string{"team": "billing"}, Limit: 2})
+		require.NoError(t, err)
+		assert.Equal(t, 3, total)
+		assert.Equal(t, []string{"task-3", "task-2"}, taskIDs(tasks))
+
+		tasks, _, err = repos.tasks.ListByProject(ctx, "proj-1", TaskFilters{Limit: 2, Offset: 2})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"task-1"}, taskIDs(tasks))
+
+		taskType := models.TaskTypeRefactoring
+		tasks, total, err = repos.tasks.ListByProject(ctx, "proj-1", TaskFilters{Type: &taskType, Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, 2, total)
+		assert.Equal(t, []string{"task-3", "task-1"}, taskIDs(tasks))
+
+		owner := "@billing"
+		tasks, _, err = repos.tasks.ListByProject(ctx, "proj-1", TaskFilters{Owner: &owner, Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"task-3"}, taskIDs(tasks))
+
+		tasks, _, err = repos.tasks.ListByCodebase(ctx, codebaseID, TaskFilters{Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"task-2"}, taskIDs(tasks))
+	})
+}
+
+func TestTaskRepository_UpdateStatus(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos testRepositories, _ *memoryOutbox) {
+		ctx := context.Background()
+		createTestTasks(t, repos.tasks, &models.Task{TaskID: "task-1", ProjectID: "proj-1", Type: models.TaskTypeRefactoring, Status: models.TaskStatusPending})
+
+		require.NoError(t, repos.tasks.UpdateStatus(ctx, "task-1", models.TaskStatusQueued, nil))
+		require.NoError(t, repos.tasks.UpdateStatus(ctx, "task-1", models.TaskStatusCancelled, &models.Notification{Event: models.NotificationEventTaskFailed}))
+
+		task, err := repos.tasks.GetByID(ctx, "task-1")
+		require.NoError(t, err)
+		assert.Equal(t, models.TaskStatusCancelled, task.Status)
+		assert.NotNil(t, task.QueuedAt)
+		assert.NotNil(t, task.CompletedAt)
+
+		err = repos.tasks.UpdateStatus(ctx, "task-1", models.TaskStatusInProgress, nil)
+		assert.Equal(t, apperrors.CodeInvalidTaskTransition, apperrors.CodeOf(err))
+
+		err = repos.tasks.UpdateStatus(ctx, "task-2", models.TaskStatusQueued, nil)
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	})
+}
+
+func TestTaskRepository_UpdateStatusAndOutput(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos testRepositories, _ *memoryOutbox) {
+		ctx := context.Background()
+		createTestTasks(t, repos.tasks, &models.Task{TaskID: "task-1", ProjectID: "proj-1", Type: models.TaskTypeRefactoring, Status: models.TaskStatusPending})
+		require.NoError(t, repos.tasks.UpdateStatus(ctx, "task-1", models.TaskStatusQueued, nil))
+		require.NoError(t, repos.tasks.UpdateStatus(ctx, "task-1", models.TaskStatusInProgress, nil))
+		require.NoError(t, repos.tasks.Heartbeat(ctx, "task-1", 40, "analyzing"))
+
+		require.NoError(t, repos.tasks.UpdateStatusAndOutput(ctx, "task-1", models.TaskStatusCompleted, map[string]any{"summary": "done"}, nil, nil))
+
+		task, err := repos.tasks.GetByID(ctx, "task-1")
+		require.NoError(t, err)
+		assert.Equal(t, models.TaskStatusCompleted, task.Status)
+		assert.Equal(t, map[string]any{"summary": "done"}, task.Output)
+		assert.Equal(t, 100, task.Progress)
+		assert.Nil(t, task.ProgressStep)
+		assert.NotNil(t, task.CompletedAt)
+	})
+}
+
+func TestTaskRepository_UndoesChangeWhenNotificationFails(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos testRepositories, outbox *memoryOutbox) {
+		if _, ok := repos.tasks.(*PostgresTaskRepository); ok {
+			t.Skip("Postgres records notifications in the outbox table of its database")
+		}
+		ctx := context.Background()
+		createTestTasks(t, repos.tasks, &models.Task{TaskID: "task-1", ProjectID: "proj-1", Type: models.TaskTypeRefactoring, Status: models.TaskStatusPending})
+
+		require.NoError(t, repos.tasks.UpdateStatus(ctx, "task-1", models.TaskStatusQueued, &models.Notification{Event: models.NotificationEventTaskQueued}))
+		outbox.err = errors.New("outbox unavailable")
+		err := repos.tasks.UpdateStatus(ctx, "task-1", models.TaskStatusCancelled, &models.Notification{Event: models.NotificationEventTaskFailed})
+
+		require.Error(t, err)
+		assert.Len(t, outbox.notifications, 1)
+		task, err := repos.tasks.GetByID(ctx, "task-1")
+		require.NoError(t, err)
+		assert.Equal(t, models.TaskStatusQueued, task.Status)
+	})
+}
+
+func TestTaskRepository_QueueOrder(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos testRepositories, _ *memoryOutbox) {
+		ctx := context.Background()
+		createTestTasks(t, repos.tasks,
+			&models.Task{TaskID: "busy-running", ProjectID: "busy", Type: models.TaskTypeRefactoring, Status: models.TaskStatusPending},
+			&models.Task{TaskID: "busy-normal", ProjectID: "busy", Type: models.TaskTypeRefactoring, Status: models.TaskStatusPending},
+			&models.Task{TaskID: "idle-normal", ProjectID: "idle", Type: models.TaskTypeRefactoring, Status: models.TaskStatusPending},
+			&models.Task{TaskID: "idle-low", ProjectID: "idle", Type: models.TaskTypeRefactoring, Status: models.TaskStatusPending, Priority: models.TaskPriorityLow},
+			&models.Task{TaskID: "busy-high", ProjectID: "busy", Type: models.TaskTypeRefactoring, Status: models.TaskStatusPending, Priority: models.TaskPriorityHigh},
+		)
+		for _, taskID := range []string{"busy-running", "busy-normal", "idle-normal", "idle-low", "busy-high"} {
+			require.NoError(t, repos.tasks.UpdateStatus(ctx, taskID, models.TaskStatusQueued, nil))
+		}
+		require.NoError(t, repos.tasks.UpdateStatus(ctx, "busy-running", models.TaskStatusInProgress, nil))
+
+		next, err := repos.tasks.NextQueued(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "busy-high", next.TaskID)
+
+		for taskID, expected := range map[string]int{"busy-high": 1, "idle-normal": 2, "busy-normal": 3, "idle-low": 4, "busy-running": 0} {
+			position, err := repos.tasks.QueuePosition(ctx, taskID)
+			require.NoError(t, err)
+			assert.Equal(t, expected, position, taskID)
+		}
+	})
+}
+
+func TestTaskRepository_RecoverStuck(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos testRepositories, _ *memoryOutbox) {
+		ctx := context.Background()
+		createTestTasks(t, repos.tasks,
+			&models.Task{TaskID: "silent", ProjectID: "proj-1", Type: models.TaskTypeRefactoring, Status: models.TaskStatusPending},
+			&models.Task{TaskID: "alive", ProjectID: "proj-1", Type: models.TaskTypeRefactoring, Status: models.TaskStatusPending},
+		)
+		for _, taskID := range []string{"silent", "alive"} {
+			require.NoError(t, repos.tasks.UpdateStatus(ctx, taskID, models.TaskStatusQueued, nil))
+			require.NoError(t, repos.tasks.UpdateStatus(ctx, taskID, models.TaskStatusInProgress, nil))
+		}
+		time.Sleep(time.Millisecond)
+		cutoff := time.Now()
+		time.Sleep(time.Millisecond)
+		require.NoError(t, repos.tasks.Heartbeat(ctx, "alive", 50, "analyzing"))
+
+		stuckTasks, err := repos.tasks.ListStuck(ctx, cutoff, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"silent"}, taskIDs(stuckTasks))
+
+		recovered, err := repos.tasks.RecoverStuck(ctx, "alive", cutoff, models.TaskStatusQueued, nil, nil)
+		require.NoError(t, err)
+		assert.False(t, recovered)
+
+		recovered, err = repos.tasks.RecoverStuck(ctx, "silent", cutoff, models.TaskStatusQueued, nil, nil)
+		require.NoError(t, err)
+		assert.True(t, recovered)
+		position, err := repos.tasks.QueuePosition(ctx, "silent")
+		require.NoError(t, err)
+		assert.Equal(t, 1, position)
+	})
+}
+
+func TestTaskRepository_CreateBatch(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos testRepositories, _ *memoryOutbox) {
+		ctx := context.Background()
+		batchID := "batch-1"
+		createTestTasks(t, repos.tasks, &models.Task{TaskID: "task-1", ProjectID: "proj-1", Type: models.TaskTypeRefactoring, Status: models.TaskStatusPending})
+
+		err := repos.tasks.CreateBatch(ctx, []*models.Task{
+			{TaskID: "task-2", ProjectID: "proj-1", BatchID: &batchID, Type: models.TaskTypeRefactoring, Status: models.TaskStatusPending},
+			{TaskID: "task-1", ProjectID: "proj-1", BatchID: &batchID, Type: models.TaskTypeRefactoring, Status: models.TaskStatusPending},
+		})
+		require.Error(t, err)
+		tasks, err := repos.tasks.ListByBatch(ctx, batchID)
+		require.NoError(t, err)
+		assert.Empty(t, tasks)
+
+		require.NoError(t, repos.tasks.CreateBatch(ctx, []*models.Task{
+			{TaskID: "task-2", ProjectID: "proj-1", BatchID: &batchID, Type: models.TaskTypeRefactoring, Status: models.TaskStatusPending},
+			{TaskID: "task-3", ProjectID: "proj-1", BatchID: &batchID, Type: models.TaskTypeRefactoring, Status: models.TaskStatusPending},
+		}))
+		tasks, err = repos.tasks.ListByBatch(ctx, batchID)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"task-2", "task-3"}, taskIDs(tasks))
+	})
+}
+
+// taskIDs returns the IDs of tasks
+func taskIDs(tasks []models.Task) []string {
+	ids := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.TaskID)
+	}
+	return ids
+}
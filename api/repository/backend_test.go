@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// testRepositories are the repositories of a storage backend
+type testRepositories struct {
+	projects  ProjectRepository
+	codebases CodebaseRepository
+	tasks     TaskRepository
+	users     UserRepository
+	agents    AgentRepository
+	roles     RoleRepository
+	configs   CodebaseConfigRepository
+	workspace WorkspaceRepository
+}
+
+// testBackend opens the empty repositories of a storage backend. The repositories record notifications in outbox,
+// except the Postgres ones, which record them in the outbox table of their database.
+type testBackend struct {
+	name string
+	open func(t *testing.T, outbox NotificationOutboxRepository) testRepositories
+}
+
+// testBackends returns the storage backends the repository tests run against: memory and SQLite, and Postgres when
+// TEST_POSTGRES_HOST names a server whose database the tests may empty
+func testBackends(t *testing.T) []testBackend {
+	t.Helper()
+	backends := []testBackend{
+		{name: "memory", open: openMemoryTestBackend},
+		{name: "sqlite", open: openSQLiteTestBackend},
+	}
+	if os.Getenv("TEST_POSTGRES_HOST") != "" {
+		backends = append(backends, testBackend{name: "postgres", open: openPostgresTestBackend})
+	}
+	return backends
+}
+
+// forEachBackend runs test against the empty repositories of every storage backend
+func forEachBackend(t *testing.T, test func(t *testing.T, repos testRepositories, outbox *memoryOutbox)) {
+	for _, backend := range testBackends(t) {
+		t.Run(backend.name, func(t *testing.T) {
+			outbox := &memoryOutbox{}
+			test(t, backend.open(t, outbox), outbox)
+		})
+	}
+}
+
+func openMemoryTestBackend(_ *testing.T, outbox NotificationOutboxRepository) testRepositories {
+	projects := NewMemoryProjectRepository()
+	return testRepositories{
+		projects:  projects,
+		codebases: NewMemoryCodebaseRepository(projects),
+		tasks:     NewMemoryTaskRepository(outbox),
+		users:     NewMemoryUserRepository(),
+		agents:    NewMemoryAgentRepository(),
+		roles:     NewMemoryRoleRepository(),
+		configs:   NewMemoryCodebaseConfigRepository(),
+		workspace: NewMemoryWorkspaceRepository(),
+	}
+}
+
+func openSQLiteTestBackend(t *testing.T, outbox NotificationOutboxRepository) testRepositories {
+	db, err := OpenSQLite(context.Background(), ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	roles, err := NewSQLiteRoleRepository(context.Background(), db)
+	require.NoError(t, err)
+
+	return testRepositories{
+		projects:  NewSQLiteProjectRepository(db),
+		codebases: NewSQLiteCodebaseRepository(db),
+		tasks:     NewSQLiteTaskRepository(db, outbox),
+		users:     NewSQLiteUserRepository(db),
+		agents:    NewSQLiteAgentRepository(db),
+		roles:     roles,
+		configs:   NewSQLiteCodebaseConfigRepository(db),
+		workspace: NewSQLiteWorkspaceRepository(db),
+	}
+}
+
+func openPostgresTestBackend(t *testing.T, _ NotificationOutboxRepository) testRepositories {
+	port, err := strconv.Atoi(testEnv("TEST_POSTGRES_PORT", "5432"))
+	require.NoError(t, err)
+	config := PostgresConfig{
+		Host:     os.Getenv("TEST_POSTGRES_HOST"),
+		Port:     port,
+		Database: testEnv("TEST_POSTGRES_DATABASE", "postgres"),
+		Username: testEnv("TEST_POSTGRES_USERNAME", "postgres"),
+		Password: testEnv("TEST_POSTGRES_PASSWORD", "postgres"),
+		SSLMode:  "disable",
+	}
+
+	require.NoError(t, MigratePostgres(context.Background(), config))
+	var repos testRepositories
+	repos.projects, err = NewPostgresProjectRepository(config, conf.DefaultProjectsTableName)
+	require.NoError(t, err)
+	repos.codebases, err = NewPostgresCodebaseRepository(config, conf.DefaultCodebasesTableName)
+	require.NoError(t, err)
+	repos.tasks, err = NewPostgresTaskRepository(config, conf.DefaultTasksTableName, conf.DefaultNotificationOutboxTableName)
+	require.NoError(t, err)
+	repos.users, err = NewPostgresUserRepository(config, conf.DefaultUsersTableName)
+	require.NoError(t, err)
+	repos.agents, err = NewPostgresAgentRepository(config, conf.DefaultAgentsTableName)
+	require.NoError(t, err)
+	repos.roles, err = NewPostgresRoleRepository(config, conf.DefaultRolesTableName, conf.DefaultPermissionsTableName,
+		conf.DefaultRolePermissionsTableName, conf.DefaultProjectMembersTableName)
+	require.NoError(t, err)
+	repos.configs, err = NewPostgresCodebaseConfigRepository(config, conf.DefaultCodebaseConfigsTableName)
+	require.NoError(t, err)
+	repos.workspace, err = NewPostgresWorkspaceRepository(config, conf.DefaultWorkspacesTableName)
+	require.NoError(t, err)
+
+	db, err := sql.Open("postgres", fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode))
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+	_, err = db.Exec(fmt.Sprintf("TRUNCATE %s, %s, %s, %s, %s, %s, %s, %s, %s",
+		conf.DefaultProjectsTableName, conf.DefaultCodebasesTableName, conf.DefaultTasksTableName,
+		conf.DefaultUsersTableName, conf.DefaultAgentsTableName, conf.DefaultNotificationOutboxTableName,
+		conf.DefaultProjectMembersTableName, conf.DefaultCodebaseConfigsTableName, conf.DefaultWorkspacesTableName))
+	require.NoError(t, err)
+
+	return repos
+}
+
+// testEnv returns the environment variable key, fallback when it's unset
+func testEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// memoryOutbox is a NotificationOutboxRepository recording the enqueued notifications, failing with err when set
+type memoryOutbox struct {
+	NotificationOutboxRepository
+	notifications []models.Notification
+	err           error
+}
+
+func (o *memoryOutbox) Enqueue(_ context.Context, notification models.Notification) error {
+	if o.err != nil {
+		return o.err
+	}
+	o.notifications = append(o.notifications, notification)
+	return nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/cache"
+)
+
+// CachedCodebaseConfigRepository caches the codebase configurations read by ID in front of another
+// CodebaseConfigRepository, invalidating them on every write. Cached configurations include git provider credentials,
+// so the cache must be protected like the database.
+type CachedCodebaseConfigRepository struct {
+	CodebaseConfigRepository
+	cache cache.Cache
+	ttl   time.Duration
+}
+
+// NewCachedCodebaseConfigRepository creates a codebase configuration repository caching the reads of repo for ttl
+func NewCachedCodebaseConfigRepository(repo CodebaseConfigRepository, c cache.Cache, ttl time.Duration) CodebaseConfigRepository {
+	return &CachedCodebaseConfigRepository{
+		CodebaseConfigRepository: repo,
+		cache:                    c,
+		ttl:                      ttl,
+	}
+}
+
+// GetCodebaseConfig retrieves a codebase configuration by ID, from the cache when it holds it
+func (r *CachedCodebaseConfigRepository) GetCodebaseConfig(ctx context.Context, configID string) (*CodebaseConfigRecord, error) {
+	return readThrough(ctx, r.cache, codebaseConfigCacheKeyPrefix+configID, r.ttl, func() (*CodebaseConfigRecord, error) {
+		return r.CodebaseConfigRepository.GetCodebaseConfig(ctx, configID)
+	})
+}
+
+// UpdateCodebaseConfig updates a codebase configuration and invalidates its cached copy, even when the update fails
+// on a version conflict
+func (r *CachedCodebaseConfigRepository) UpdateCodebaseConfig(ctx context.Context, config *CodebaseConfigRecord) error {
+	defer invalidate(ctx, r.cache, codebaseConfigCacheKeyPrefix+config.ConfigID)
+	return r.CodebaseConfigRepository.UpdateCodebaseConfig(ctx, config)
+}
+
+// DeleteCodebaseConfig deletes a codebase configuration and invalidates its cached copy
+func (r *CachedCodebaseConfigRepository) DeleteCodebaseConfig(ctx context.Context, configID string) error {
+	defer invalidate(ctx, r.cache, codebaseConfigCacheKeyPrefix+configID)
+	return r.CodebaseConfigRepository.DeleteCodebaseConfig(ctx, configID)
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/cache"
+)
+
+// CachedProjectRepository caches the projects read by ID in front of another ProjectRepository, invalidating them
+// on every write
+type CachedProjectRepository struct {
+	ProjectRepository
+	cache cache.Cache
+	ttl   time.Duration
+}
+
+// NewCachedProjectRepository creates a project repository caching the reads of repo for ttl
+func NewCachedProjectRepository(repo ProjectRepository, c cache.Cache, ttl time.Duration) ProjectRepository {
+	return &CachedProjectRepository{
+		ProjectRepository: repo,
+		cache:             c,
+		ttl:               ttl,
+	}
+}
+
+// GetProject retrieves a project by ID, from the cache when it holds it
+func (r *CachedProjectRepository) GetProject(ctx context.Context, projectID string) (*ProjectRecord, error) {
+	return readThrough(ctx, r.cache, projectCacheKeyPrefix+projectID, r.ttl, func() (*ProjectRecord, error) {
+		return r.ProjectRepository.GetProject(ctx, projectID)
+	})
+}
+
+// UpdateProject updates a project and invalidates its cached copy. The copy is invalidated even when the update
+// fails, since a version conflict means it may be stale.
+func (r *CachedProjectRepository) UpdateProject(ctx context.Context, project *ProjectRecord) error {
+	defer invalidate(ctx, r.cache, projectCacheKeyPrefix+project.ProjectID)
+	return r.ProjectRepository.UpdateProject(ctx, project)
+}
+
+// DeleteProject deletes a project and invalidates its cached copy
+func (r *CachedProjectRepository) DeleteProject(ctx context.Context, projectID string) error {
+	defer invalidate(ctx, r.cache, projectCacheKeyPrefix+projectID)
+	return r.ProjectRepository.DeleteProject(ctx, projectID)
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/cache"
+)
+
+// Cache key prefixes of the records cached in front of the database
+const (
+	projectCacheKeyPrefix        = "project:"
+	codebaseConfigCacheKeyPrefix = "codebase_config:"
+	userCacheKeyPrefix           = "user:"
+	userAuthIDCacheKeyPrefix     = "user_auth_id:"
+)
+
+// readThrough returns the record cached under key, loading and caching it on a miss. The cache is best effort: when
+// it can't be reached or holds an unreadable entry the record is loaded from the database, and errors of load are
+// returned as they are. Missing records aren't cached.
+func readThrough[T any](ctx context.Context, c cache.Cache, key string, ttl time.Duration, load func() (*T, error)) (*T, error) {
+	data, found, err := c.Get(ctx, key)
+	if err != nil {
+		slog.DebugContext(ctx, "cache unavailable, reading from the database", "key", key, "error", err)
+	} else if found {
+		var record T
+		if err := json.Unmarshal(data, &record); err == nil {
+			return &record, nil
+		}
+		slog.DebugContext(ctx, "unreadable cache entry, reading from the database", "key", key, "error", err)
+	}
+
+	record, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err := json.Marshal(record); err == nil {
+		if err := c.Set(ctx, key, data, ttl); err != nil {
+			slog.DebugContext(ctx, "failed to cache record", "key", key, "error", err)
+		}
+	}
+
+	return record, nil
+}
+
+// invalidate removes the records cached under keys after a write. Records that can't be removed are served until
+// their TTL elapses.
+func invalidate(ctx context.Context, c cache.Cache, keys ...string) {
+	if err := c.Delete(ctx, keys...); err != nil {
+		slog.WarnContext(ctx, "failed to invalidate cached records", "keys", keys, "error", err)
+	}
+}
@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/cache/mocks"
+)
+
+// memoryCache is an in-memory cache.Cache ignoring TTLs
+type memoryCache map[string][]byte
+
+func (c memoryCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	value, ok := c[key]
+	return value, ok, nil
+}
+
+func (c memoryCache) Set(_ context.Context, key string, value []byte, _ time.Duration) error {
+	c[key] = value
+	return nil
+}
+
+func (c memoryCache) Delete(_ context.Context, keys ...string) error {
+	for _, key := range keys {
+		delete(c, key)
+	}
+	return nil
+}
+
+// countingProjectRepository serves one stored project, counting the reads that reach it
+type countingProjectRepository struct {
+	ProjectRepository
+	project *ProjectRecord
+	reads   int
+}
+
+func (r *countingProjectRepository) GetProject(_ context.Context, projectID string) (*ProjectRecord, error) {
+	r.reads++
+	if r.project == nil || r.project.ProjectID != projectID {
+		return nil, apperrors.NotFound(apperrors.CodeProjectNotFound, "project %s not found", projectID)
+	}
+	project := *r.project
+	return &project, nil
+}
+
+func (r *countingProjectRepository) UpdateProject(_ context.Context, project *ProjectRecord) error {
+	project.Version++
+	r.project = project
+	return nil
+}
+
+func TestCachedProjectRepository_ServesReadsFromCacheUntilUpdated(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingProjectRepository{project: &ProjectRecord{ProjectID: "proj-1", Name: "Original", Version: 1}}
+	repo := NewCachedProjectRepository(inner, memoryCache{}, time.Minute)
+
+	for range 3 {
+		project, err := repo.GetProject(ctx, "proj-1")
+		require.NoError(t, err)
+		assert.Equal(t, "Original", project.Name)
+	}
+	assert.Equal(t, 1, inner.reads)
+
+	require.NoError(t, repo.UpdateProject(ctx, &ProjectRecord{ProjectID: "proj-1", Name: "Renamed", Version: 1}))
+
+	project, err := repo.GetProject(ctx, "proj-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", project.Name)
+	assert.Equal(t, int64(2), project.Version)
+	assert.Equal(t, 2, inner.reads)
+}
+
+func TestCachedProjectRepository_DoesNotCacheMissingProjects(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingProjectRepository{}
+	repo := NewCachedProjectRepository(inner, memoryCache{}, time.Minute)
+
+	for range 2 {
+		_, err := repo.GetProject(ctx, "proj-1")
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	}
+	assert.Equal(t, 2, inner.reads)
+}
+
+func TestCachedProjectRepository_FallsBackToDatabaseWhenCacheUnavailable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	unavailable := errors.New("dial tcp: connection refused")
+	mockCache := mocks.NewMockCache(ctrl)
+	mockCache.EXPECT().Get(gomock.Any(), "project:proj-1").Return(nil, false, unavailable)
+	mockCache.EXPECT().Set(gomock.Any(), "project:proj-1", gomock.Any(), time.Minute).Return(unavailable)
+	mockCache.EXPECT().Delete(gomock.Any(), "project:proj-1").Return(unavailable)
+
+	ctx := context.Background()
+	inner := &countingProjectRepository{project: &ProjectRecord{ProjectID: "proj-1", Name: "Original"}}
+	repo := NewCachedProjectRepository(inner, mockCache, time.Minute)
+
+	project, err := repo.GetProject(ctx, "proj-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Original", project.Name)
+
+	require.NoError(t, repo.UpdateProject(ctx, &ProjectRecord{ProjectID: "proj-1", Name: "Renamed"}))
+}
+
+// stubUserRepository serves one stored user
+type stubUserRepository struct {
+	UserRepository
+	user *models.DBUser
+}
+
+func (r *stubUserRepository) GetUser(_ context.Context, userID string) (*models.DBUser, error) {
+	if r.user == nil || r.user.UserID != userID {
+		return nil, apperrors.NotFound(apperrors.CodeUserNotFound, "user %s not found", userID)
+	}
+	user := *r.user
+	return &user, nil
+}
+
+func (r *stubUserRepository) GetUserByAuthID(_ context.Context, authID string) (*models.DBUser, error) {
+	if r.user == nil || r.user.AuthID != authID {
+		return nil, apperrors.NotFound(apperrors.CodeUserNotFound, "user %s not found", authID)
+	}
+	user := *r.user
+	return &user, nil
+}
+
+func (r *stubUserRepository) UpdateUser(_ context.Context, user *models.DBUser) (*models.DBUser, error) {
+	r.user = user
+	return user, nil
+}
+
+func (r *stubUserRepository) DeleteUser(_ context.Context, _ string) error {
+	r.user = nil
+	return nil
+}
+
+func TestCachedUserRepository_InvalidatesPreviousAuthIDOnUpdate(t *testing.T) {
+	ctx := context.Background()
+	inner := &stubUserRepository{user: &models.DBUser{UserID: "user-1", AuthID: "auth-old", Role: models.RoleAdmin}}
+	c := memoryCache{}
+	repo := NewCachedUserRepository(inner, c, time.Minute)
+
+	_, err := repo.GetUserByAuthID(ctx, "auth-old")
+	require.NoError(t, err)
+	_, err = repo.GetUser(ctx, "user-1")
+	require.NoError(t, err)
+	require.Len(t, c, 2)
+
+	_, err = repo.UpdateUser(ctx, &models.DBUser{UserID: "user-1", AuthID: "auth-new", Role: models.RoleViewer})
+	require.NoError(t, err)
+	assert.Empty(t, c)
+
+	_, err = repo.GetUserByAuthID(ctx, "auth-old")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	user, err := repo.GetUserByAuthID(ctx, "auth-new")
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleViewer, user.Role)
+}
+
+func TestCachedUserRepository_InvalidatesOnDelete(t *testing.T) {
+	ctx := context.Background()
+	inner := &stubUserRepository{user: &models.DBUser{UserID: "user-1", AuthID: "auth-1"}}
+	c := memoryCache{}
+	repo := NewCachedUserRepository(inner, c, time.Minute)
+
+	_, err := repo.GetUserByAuthID(ctx, "auth-1")
+	require.NoError(t, err)
+
+	require.NoError(t, repo.DeleteUser(ctx, "user-1"))
+	assert.Empty(t, c)
+
+	_, err = repo.GetUserByAuthID(ctx, "auth-1")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/cache"
+)
+
+// CachedUserRepository caches the users read by ID and by auth provider ID, the lookup behind every token
+// validation, in front of another UserRepository. Both copies of a user are invalidated on every write.
+type CachedUserRepository struct {
+	UserRepository
+	cache cache.Cache
+	ttl   time.Duration
+}
+
+// NewCachedUserRepository creates a user repository caching the reads of repo for ttl
+func NewCachedUserRepository(repo UserRepository, c cache.Cache, ttl time.Duration) UserRepository {
+	return &CachedUserRepository{
+		UserRepository: repo,
+		cache:          c,
+		ttl:            ttl,
+	}
+}
+
+// GetUser retrieves a user by ID, from the cache when it holds it
+func (r *CachedUserRepository) GetUser(ctx context.Context, userID string) (*models.DBUser, error) {
+	return readThrough(ctx, r.cache, userCacheKeyPrefix+userID, r.ttl, func() (*models.DBUser, error) {
+		return r.UserRepository.GetUser(ctx, userID)
+	})
+}
+
+// GetUserByAuthID retrieves a user by auth provider ID, from the cache when it holds it
+func (r *CachedUserRepository) GetUserByAuthID(ctx context.Context, authID string) (*models.DBUser, error) {
+	return readThrough(ctx, r.cache, userAuthIDCacheKeyPrefix+authID, r.ttl, func() (*models.DBUser, error) {
+		return r.UserRepository.GetUserByAuthID(ctx, authID)
+	})
+}
+
+// UpdateUser updates a user and invalidates its cached copies, including the one under its previous auth ID
+func (r *CachedUserRepository) UpdateUser(ctx context.Context, user *models.DBUser) (*models.DBUser, error) {
+	defer invalidate(ctx, r.cache, r.cacheKeys(ctx, user.UserID, user.AuthID)...)
+	return r.UserRepository.UpdateUser(ctx, user)
+}
+
+// DeleteUser deletes a user and invalidates its cached copies
+func (r *CachedUserRepository) DeleteUser(ctx context.Context, userID string) error {
+	defer invalidate(ctx, r.cache, r.cacheKeys(ctx, userID)...)
+	return r.UserRepository.DeleteUser(ctx, userID)
+}
+
+// cacheKeys returns the keys a user may be cached under. It looks up the auth ID the user is stored with, so it must
+// be called before a write changes it.
+func (r *CachedUserRepository) cacheKeys(ctx context.Context, userID string, authIDs ...string) []string {
+	if existing, err := r.UserRepository.GetUser(ctx, userID); err == nil {
+		authIDs = append(authIDs, existing.AuthID)
+	}
+
+	keys := []string{userCacheKeyPrefix + userID}
+	for _, authID := range authIDs {
+		if authID != "" {
+			keys = append(keys, userAuthIDCacheKeyPrefix+authID)
+		}
+	}
+	return keys
+}
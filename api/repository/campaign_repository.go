@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// CampaignRepository defines the interface for campaign data operations
+//
+//go:generate mockgen -destination=./mocks/mock_campaign_repository.go -mock_names=CampaignRepository=MockCampaignRepository -package=mocks . CampaignRepository
+type CampaignRepository interface {
+	// CreateCampaign stores a new campaign
+	CreateCampaign(ctx context.Context, campaign *models.Campaign) error
+
+	// GetCampaign retrieves a campaign by ID
+	GetCampaign(ctx context.Context, campaignID string) (*models.Campaign, error)
+
+	// DeleteCampaign deletes a campaign
+	DeleteCampaign(ctx context.Context, campaignID string) error
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// ClientTokenRepository defines the interface for the client tokens making create requests idempotent
+//
+//go:generate mockgen -destination=./mocks/mock_client_token_repository.go -mock_names=ClientTokenRepository=MockClientTokenRepository -package=mocks . ClientTokenRepository
+type ClientTokenRepository interface {
+	// ClaimToken records token unless its scope already has it, returning the record of the existing token, or nil
+	// when token was recorded
+	ClaimToken(ctx context.Context, token *models.ClientToken) (*models.ClientToken, error)
+
+	// CompleteToken records the resource created by the request carrying a token
+	CompleteToken(ctx context.Context, scope, token, resourceID string) error
+
+	// ReleaseToken deletes a token, so that the request carrying it can be retried
+	ReleaseToken(ctx context.Context, scope, token string) error
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// CodeMetricsRepository defines the interface for the per-commit metrics snapshots of codebases
+//
+//go:generate mockgen -destination=./mocks/mock_code_metrics_repository.go -mock_names=CodeMetricsRepository=MockCodeMetricsRepository -package=mocks . CodeMetricsRepository
+type CodeMetricsRepository interface {
+	// SaveSnapshot stores a snapshot, replacing the one previously taken at the same commit of the codebase
+	SaveSnapshot(ctx context.Context, snapshot *models.CodeMetricsSnapshot) error
+
+	// ListSnapshots lists the snapshots of a codebase measured at or after since, oldest first
+	ListSnapshots(ctx context.Context, codebaseID string, since time.Time) ([]models.CodeMetricsSnapshot, error)
+}
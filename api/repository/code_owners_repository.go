@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// CodeOwnersRepository defines the interface for the CODEOWNERS rules of codebases
+//
+//go:generate mockgen -destination=./mocks/mock_code_owners_repository.go -mock_names=CodeOwnersRepository=MockCodeOwnersRepository -package=mocks . CodeOwnersRepository
+type CodeOwnersRepository interface {
+	// GetCodeOwners retrieves the ownership of a codebase's files, returning nil if none was recorded
+	GetCodeOwners(ctx context.Context, codebaseID string) (*models.CodeOwners, error)
+
+	// SaveCodeOwners creates or replaces the ownership of a codebase's files
+	SaveCodeOwners(ctx context.Context, owners *models.CodeOwners) error
+}
@@ -17,6 +17,7 @@ type CodebaseConfigRecord struct {
 	URL         string                   `json:"url" db:"url"`
 	CreatedAt   time.Time                `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time                `json:"updated_at" db:"updated_at"`
+	Version     int64                    `json:"version" db:"version"`
 	Tags        map[string]string        `json:"tags,omitempty" db:"tags"`
 	Config      models.GitProviderConfig `json:"config" db:"config"`
 }
@@ -31,6 +32,7 @@ func (r *CodebaseConfigRecord) ToGetCodebaseConfigResponse() *models.GetCodebase
 		URL:         r.URL,
 		CreatedAt:   r.CreatedAt.UTC().Format(time.RFC3339),
 		UpdatedAt:   r.UpdatedAt.UTC().Format(time.RFC3339),
+		Version:     r.Version,
 		Tags:        r.Tags,
 		Config:      r.redactSensitiveConfig(),
 	}
@@ -56,11 +58,14 @@ func (r *CodebaseConfigRecord) redactSensitiveConfig() models.GitProviderConfigR
 
 	if r.Config.GitHub != nil {
 		redacted.GitHub = &models.GitHubConfigRedacted{
-			Organization:  r.Config.GitHub.Organization,
-			Repository:    r.Config.GitHub.Repository,
-			Owner:         r.Config.GitHub.Owner,
-			DefaultBranch: r.Config.GitHub.DefaultBranch,
-			HasToken:      r.Config.GitHub.Token != "",
+			Organization:   r.Config.GitHub.Organization,
+			Repository:     r.Config.GitHub.Repository,
+			Owner:          r.Config.GitHub.Owner,
+			DefaultBranch:  r.Config.GitHub.DefaultBranch,
+			HasToken:       r.Config.GitHub.Token != "",
+			AppID:          r.Config.GitHub.AppID,
+			InstallationID: r.Config.GitHub.InstallationID,
+			HasPrivateKey:  r.Config.GitHub.PrivateKey != "",
 		}
 	}
 
@@ -84,10 +89,22 @@ func (r *CodebaseConfigRecord) redactSensitiveConfig() models.GitProviderConfigR
 		}
 	}
 
+	if r.Config.AzureDevOps != nil {
+		redacted.AzureDevOps = &models.AzureDevOpsConfigRedacted{
+			BaseURL:       r.Config.AzureDevOps.BaseURL,
+			Organization:  r.Config.AzureDevOps.Organization,
+			Project:       r.Config.AzureDevOps.Project,
+			Repository:    r.Config.AzureDevOps.Repository,
+			DefaultBranch: r.Config.AzureDevOps.DefaultBranch,
+			HasToken:      r.Config.AzureDevOps.Token != "",
+		}
+	}
+
 	if r.Config.Custom != nil {
 		redacted.Custom = &models.CustomGitConfigRedacted{
 			BaseURL:       r.Config.Custom.BaseURL,
 			Headers:       r.Config.Custom.Headers,
+			KnownHosts:    r.Config.Custom.KnownHosts,
 			DefaultBranch: r.Config.Custom.DefaultBranch,
 			HasToken:      r.Config.Custom.Token != "",
 			HasUsername:   r.Config.Custom.Username != "",
@@ -109,7 +126,8 @@ type CodebaseConfigRepository interface {
 	// GetCodebaseConfig retrieves a codebase configuration by ID
 	GetCodebaseConfig(ctx context.Context, configID string) (*CodebaseConfigRecord, error)
 
-	// UpdateCodebaseConfig updates an existing codebase configuration record
+	// UpdateCodebaseConfig updates an existing codebase configuration record if its stored version still equals
+	// config.Version, then increments config.Version. A stale version fails with apperrors.ErrPreconditionFailed.
 	UpdateCodebaseConfig(ctx context.Context, config *CodebaseConfigRecord) error
 
 	// DeleteCodebaseConfig deletes a codebase configuration by ID
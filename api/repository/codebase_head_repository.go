@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// CodebaseHead is the last seen commit of a codebase's default branch and the commit its agents were resynced to
+type CodebaseHead struct {
+	CodebaseID string    `json:"codebase_id" db:"codebase_id"`
+	CommitSHA  string    `json:"commit_sha" db:"commit_sha"`
+	ChangedAt  time.Time `json:"changed_at" db:"changed_at"` // When CommitSHA was first seen
+	SyncedSHA  string    `json:"synced_sha" db:"synced_sha"` // Commit the codebase's agents were last resynced to
+	CheckedAt  time.Time `json:"checked_at" db:"checked_at"` // Last time the default branch was polled
+}
+
+// Pending reports whether the default branch moved since the codebase's agents were last resynced
+func (h *CodebaseHead) Pending() bool {
+	return h.CommitSHA != h.SyncedSHA
+}
+
+// CodebaseHeadRepository defines the interface for tracking the default branch heads of codebases
+//
+//go:generate mockgen -destination=./mocks/mock_codebase_head_repository.go -mock_names=CodebaseHeadRepository=MockCodebaseHeadRepository -package=mocks . CodebaseHeadRepository
+type CodebaseHeadRepository interface {
+	// GetHead retrieves the tracked head of a codebase, returning nil if the codebase was never polled
+	GetHead(ctx context.Context, codebaseID string) (*CodebaseHead, error)
+
+	// SaveHead creates or replaces the tracked head of a codebase
+	SaveHead(ctx context.Context, head *CodebaseHead) error
+}
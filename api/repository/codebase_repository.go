@@ -3,6 +3,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/kazemisoroush/code-refactoring-tool/api/models"
 )
@@ -32,6 +33,16 @@ type CodebaseRepository interface {
 
 	// GetCodebasesByProject gets all codebases for a specific project
 	GetCodebasesByProject(ctx context.Context, projectID string) ([]*models.Codebase, error)
+
+	// CountByProject counts the project's codebases grouped by status
+	CountByProject(ctx context.Context, projectID string) ([]CodebaseStatusCount, error)
+}
+
+// CodebaseStatusCount is the number of codebases with a given status and their latest sync time
+type CodebaseStatusCount struct {
+	Status     models.CodebaseStatus
+	Count      int
+	LastSyncAt *time.Time
 }
 
 // CodebaseFilter defines filtering options for listing codebases
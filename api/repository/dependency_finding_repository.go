@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// DependencyFindingRepository defines the interface for the vulnerable dependency findings of codebases
+//
+//go:generate mockgen -destination=./mocks/mock_dependency_finding_repository.go -mock_names=DependencyFindingRepository=MockDependencyFindingRepository -package=mocks . DependencyFindingRepository
+type DependencyFindingRepository interface {
+	// ReplaceFindings replaces the findings of a codebase with those of its latest audit
+	ReplaceFindings(ctx context.Context, codebaseID string, findings []models.DependencyFinding) error
+
+	// ListFindings lists the findings of a codebase, most severe first
+	ListFindings(ctx context.Context, codebaseID string) ([]models.DependencyFinding, error)
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// DeviceAuthorizationRepository defines the interface for device authorization flow data operations
+//
+//go:generate mockgen -destination=./mocks/mock_device_authorization_repository.go -mock_names=DeviceAuthorizationRepository=MockDeviceAuthorizationRepository -package=mocks . DeviceAuthorizationRepository
+type DeviceAuthorizationRepository interface {
+	// CreateDeviceAuthorization stores a started device sign in, failing with a conflict if its user code is taken
+	CreateDeviceAuthorization(ctx context.Context, authorization *models.DeviceAuthorization) error
+
+	// GetPendingDeviceAuthorization returns the pending, unexpired sign in with the user code, or nil if there is none
+	GetPendingDeviceAuthorization(ctx context.Context, userCode string, now time.Time) (*models.DeviceAuthorization, error)
+
+	// ApproveDeviceAuthorization approves the pending, unexpired sign in with the user code, storing the sealed refresh
+	// token of the approving user, reporting false if there is none
+	ApproveDeviceAuthorization(ctx context.Context, userCode, userID, sealedRefreshToken string, now time.Time) (bool, error)
+
+	// DenyDeviceAuthorization denies the pending, unexpired sign in with the user code, reporting false if there is none
+	DenyDeviceAuthorization(ctx context.Context, userCode string, now time.Time) (bool, error)
+
+	// PollDeviceAuthorization records a poll of the sign in with the device code hash, returning it with the time of the
+	// previous poll as LastPolledAt, or nil if there is no such sign in
+	PollDeviceAuthorization(ctx context.Context, deviceCodeHash string, polledAt time.Time) (*models.DeviceAuthorization, error)
+
+	// ConsumeDeviceAuthorization marks the approved sign in with the device code hash consumed and returns its sealed
+	// refresh token, which is cleared, or an empty string if the sign in isn't approved or was consumed already
+	ConsumeDeviceAuthorization(ctx context.Context, deviceCodeHash string) (string, error)
+
+	// DeleteExpiredDeviceAuthorizations deletes the sign ins that expired before a time, returning how many were deleted
+	DeleteExpiredDeviceAuthorizations(ctx context.Context, before time.Time) (int64, error)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
 	"github.com/kazemisoroush/code-refactoring-tool/api/models"
 )
 
@@ -96,7 +97,7 @@ func (r *DynamoDBAgentRepository) GetAgent(ctx context.Context, agentID string)
 	}
 
 	if result.Item == nil {
-		return nil, fmt.Errorf("agent not found: %s", agentID)
+		return nil, apperrors.NotFound(apperrors.CodeAgentNotFound, "agent not found: %s", agentID)
 	}
 
 	// Use DynamoDB-specific struct for unmarshaling
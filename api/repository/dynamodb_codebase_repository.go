@@ -203,3 +203,30 @@ func (r *DynamoDBCodebaseRepository) GetCodebasesByProject(ctx context.Context,
 	}
 	return codebases, nil
 }
+
+// CountByProject counts the project's codebases grouped by status.
+// DynamoDB has no GROUP BY, so the project's codebases are aggregated in memory.
+func (r *DynamoDBCodebaseRepository) CountByProject(ctx context.Context, projectID string) ([]CodebaseStatusCount, error) {
+	codebases, err := r.GetCodebasesByProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	var counts []CodebaseStatusCount
+	indexByStatus := make(map[models.CodebaseStatus]int)
+	for _, codebase := range codebases {
+		index, ok := indexByStatus[codebase.Status]
+		if !ok {
+			index = len(counts)
+			indexByStatus[codebase.Status] = index
+			counts = append(counts, CodebaseStatusCount{Status: codebase.Status})
+		}
+
+		counts[index].Count++
+		if codebase.LastSyncAt != nil && (counts[index].LastSyncAt == nil || codebase.LastSyncAt.After(*counts[index].LastSyncAt)) {
+			counts[index].LastSyncAt = codebase.LastSyncAt
+		}
+	}
+
+	return counts, nil
+}
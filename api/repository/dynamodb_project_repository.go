@@ -5,11 +5,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
 )
 
 const (
@@ -56,7 +61,7 @@ func (r *DynamoDBProjectRepository) CreateProject(ctx context.Context, project *
 	if err != nil {
 		var conditionalCheckFailedException *types.ConditionalCheckFailedException
 		if errors.As(err, &conditionalCheckFailedException) {
-			return fmt.Errorf("project with ID %s already exists", project.ProjectID)
+			return apperrors.Conflict(apperrors.CodeProjectExists, "project with ID %s already exists", project.ProjectID)
 		}
 		return fmt.Errorf("failed to create project: %w", err)
 	}
@@ -112,7 +117,7 @@ func (r *DynamoDBProjectRepository) UpdateProject(ctx context.Context, project *
 	if err != nil {
 		var conditionalCheckFailedException *types.ConditionalCheckFailedException
 		if errors.As(err, &conditionalCheckFailedException) {
-			return fmt.Errorf("project with ID %s does not exist", project.ProjectID)
+			return apperrors.NotFound(apperrors.CodeProjectNotFound, "project with ID %s does not exist", project.ProjectID)
 		}
 		return fmt.Errorf("failed to update project: %w", err)
 	}
@@ -134,7 +139,7 @@ func (r *DynamoDBProjectRepository) DeleteProject(ctx context.Context, projectID
 	if err != nil {
 		var conditionalCheckFailedException *types.ConditionalCheckFailedException
 		if errors.As(err, &conditionalCheckFailedException) {
-			return fmt.Errorf("project with ID %s does not exist", projectID)
+			return apperrors.NotFound(apperrors.CodeProjectNotFound, "project with ID %s does not exist", projectID)
 		}
 		return fmt.Errorf("failed to delete project: %w", err)
 	}
@@ -182,9 +187,25 @@ func (r *DynamoDBProjectRepository) ListProjects(ctx context.Context, opts ListP
 	}
 
 	// Add filter expression for tags if provided
+	var filterExpressions []string
+	expressionAttributeNames := make(map[string]string)
+	expressionAttributeValues := make(map[string]types.AttributeValue)
 	if len(opts.TagFilter) > 0 {
-		filterExpression, expressionAttributeNames, expressionAttributeValues := buildTagFilterExpression(opts.TagFilter)
-		input.FilterExpression = aws.String(filterExpression)
+		tagExpression, tagNames, tagValues := buildTagFilterExpression(opts.TagFilter)
+		filterExpressions = append(filterExpressions, tagExpression)
+		maps.Copy(expressionAttributeNames, tagNames)
+		maps.Copy(expressionAttributeValues, tagValues)
+	}
+
+	// Archived projects are only listed when asked for
+	if !opts.IncludeArchived {
+		filterExpressions = append(filterExpressions, "#status <> :archived")
+		expressionAttributeNames["#status"] = "status"
+		expressionAttributeValues[":archived"] = &types.AttributeValueMemberS{Value: string(models.ProjectStatusArchived)}
+	}
+
+	if len(filterExpressions) > 0 {
+		input.FilterExpression = aws.String(strings.Join(filterExpressions, " AND "))
 		input.ExpressionAttributeNames = expressionAttributeNames
 		input.ExpressionAttributeValues = expressionAttributeValues
 	}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// EvalRunRepository defines the interface for the evaluation runs against golden repositories
+//
+//go:generate mockgen -destination=./mocks/mock_eval_run_repository.go -mock_names=EvalRunRepository=MockEvalRunRepository -package=mocks . EvalRunRepository
+type EvalRunRepository interface {
+	// CreateRun stores a new evaluation run
+	CreateRun(ctx context.Context, run *models.EvalRun) error
+
+	// UpdateRun stores the progress and results of an evaluation run
+	UpdateRun(ctx context.Context, run *models.EvalRun) error
+
+	// GetRun retrieves an evaluation run with its results
+	GetRun(ctx context.Context, runID string) (*models.EvalRun, error)
+
+	// ListRuns lists the most recent evaluation runs, newest first, without their per-fixture results
+	ListRuns(ctx context.Context, limit int) ([]models.EvalRun, error)
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// ExperimentRepository defines the interface for the experiments routing live tasks to a variant of the local agent
+//
+//go:generate mockgen -destination=./mocks/mock_experiment_repository.go -mock_names=ExperimentRepository=MockExperimentRepository -package=mocks . ExperimentRepository
+type ExperimentRepository interface {
+	// CreateExperiment stores a new experiment
+	CreateExperiment(ctx context.Context, experiment *models.Experiment) error
+
+	// GetExperiment retrieves an experiment
+	GetExperiment(ctx context.Context, experimentID string) (*models.Experiment, error)
+
+	// ListExperiments lists the experiments with the given status, every experiment when it is empty, newest first
+	ListExperiments(ctx context.Context, status models.ExperimentStatus) ([]models.Experiment, error)
+
+	// StopExperiment stops a running experiment. It reports false, changing nothing, when the experiment was stopped
+	// already.
+	StopExperiment(ctx context.Context, experimentID string, stoppedAt time.Time) (bool, error)
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// FileHistoryFilter narrows the file histories of a codebase listed
+type FileHistoryFilter struct {
+	Paths []string // Only the histories of these files, of every file when empty
+	Limit int      // Most histories listed, all of them when 0
+}
+
+// FileHistoryRepository defines the interface for the git history summaries of codebase files
+//
+//go:generate mockgen -destination=./mocks/mock_file_history_repository.go -mock_names=FileHistoryRepository=MockFileHistoryRepository -package=mocks . FileHistoryRepository
+type FileHistoryRepository interface {
+	// ReplaceHistory replaces the file histories of a codebase with the ones read at a commit
+	ReplaceHistory(ctx context.Context, codebaseID, commitSHA string, histories []models.FileHistory) error
+
+	// ListHistory lists the file histories of a codebase matching filter, highest churn first
+	ListHistory(ctx context.Context, codebaseID string, filter FileHistoryFilter) ([]models.FileHistory, error)
+}
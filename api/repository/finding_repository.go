@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// FindingFilter narrows the findings of a codebase listed
+type FindingFilter struct {
+	Status    *models.FindingStatus // Only findings in this status
+	Source    string                // Only findings of this source
+	RuleID    string                // Only findings of this rule
+	FilePath  string                // Only findings in this file
+	Baselined *bool                 // Only findings suppressed, or not, by the baseline
+	Owner     string                // Only findings owned by this user, team or email
+	Limit     int                   // Most findings listed, all of them when 0
+	Offset    int                   // Findings skipped
+}
+
+// FindingRepository defines the interface for the analysis findings of codebases
+//
+//go:generate mockgen -destination=./mocks/mock_finding_repository.go -mock_names=FindingRepository=MockFindingRepository -package=mocks . FindingRepository
+type FindingRepository interface {
+	// CreateFinding stores a new finding, failing with a conflict when its codebase already has it
+	CreateFinding(ctx context.Context, finding *models.Finding) error
+
+	// GetFinding retrieves a finding by ID
+	GetFinding(ctx context.Context, findingID string) (*models.Finding, error)
+
+	// UpdateFinding replaces the status of a finding
+	UpdateFinding(ctx context.Context, finding *models.Finding) error
+
+	// DeleteFinding deletes a finding
+	DeleteFinding(ctx context.Context, findingID string) error
+
+	// ListFindings lists the findings of a codebase matching filter, most recently seen first
+	ListFindings(ctx context.Context, codebaseID string, filter FindingFilter) ([]models.Finding, error)
+
+	// CountFindings counts the findings of a codebase in each status, only those suppressed, or not, by the baseline
+	// unless baselined is nil
+	CountFindings(ctx context.Context, codebaseID string, baselined *bool) (map[models.FindingStatus]int, error)
+
+	// SaveFindings creates or replaces findings in a single transaction, so an analysis is recorded whole or not at all.
+	// Whether existing findings are baselined is left unchanged.
+	SaveFindings(ctx context.Context, findings []models.Finding) error
+
+	// Rebaseline replaces the baseline of a codebase, suppressing exactly its open findings, and sets the number of
+	// findings suppressed on baseline
+	Rebaseline(ctx context.Context, baseline *models.FindingBaseline) error
+
+	// GetBaseline retrieves the baseline of a codebase, failing with not found when it has none
+	GetBaseline(ctx context.Context, codebaseID string) (*models.FindingBaseline, error)
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// GitHubAppConfigRepository defines the interface for the GitHub App configurations of organizations
+//
+//go:generate mockgen -destination=./mocks/mock_github_app_config_repository.go -mock_names=GitHubAppConfigRepository=MockGitHubAppConfigRepository -package=mocks . GitHubAppConfigRepository
+type GitHubAppConfigRepository interface {
+	// PutConfig stores the configuration of an organization, replacing the one it had. The creation time of a
+	// replaced configuration is kept, and config is updated with it.
+	PutConfig(ctx context.Context, config *models.GitHubAppConfig) error
+
+	// GetConfig gets the configuration of an organization
+	GetConfig(ctx context.Context, organization string) (*models.GitHubAppConfig, error)
+
+	// ListConfigs lists the configurations ordered by organization
+	ListConfigs(ctx context.Context) ([]models.GitHubAppConfig, error)
+
+	// DeleteConfig deletes the configuration of an organization
+	DeleteConfig(ctx context.Context, organization string) error
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// GitHubCheckRepository defines the interface for the check runs of the tasks pull requests trigger
+//
+//go:generate mockgen -destination=./mocks/mock_github_check_repository.go -mock_names=GitHubCheckRepository=MockGitHubCheckRepository -package=mocks . GitHubCheckRepository
+type GitHubCheckRepository interface {
+	// CreateCheck records the check run of a task
+	CreateCheck(ctx context.Context, check *models.GitHubCheck) error
+
+	// ListPublishable lists up to limit pending checks, oldest first, whose task finished or no longer exists
+	ListPublishable(ctx context.Context, limit int) ([]models.GitHubCheck, error)
+
+	// MarkPublished marks the check of a task published
+	MarkPublished(ctx context.Context, taskID string, publishedAt time.Time) error
+}
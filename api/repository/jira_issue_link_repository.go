@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// JiraIssueLinkRepository defines the interface for the links of tasks to the Jira issues they work on
+//
+//go:generate mockgen -destination=./mocks/mock_jira_issue_link_repository.go -mock_names=JiraIssueLinkRepository=MockJiraIssueLinkRepository -package=mocks . JiraIssueLinkRepository
+type JiraIssueLinkRepository interface {
+	// CreateLink links a task to an issue
+	CreateLink(ctx context.Context, link *models.JiraIssueLink) error
+
+	// ListLinks lists the links of the tasks, those without one are left out
+	ListLinks(ctx context.Context, taskIDs []string) ([]models.JiraIssueLink, error)
+
+	// ListUpdates lists up to limit links, least recently updated first, whose task changed status since it was last
+	// reported on the issue
+	ListUpdates(ctx context.Context, limit int) ([]models.JiraIssueUpdate, error)
+
+	// MarkSynced records the task status last reported on the issue of a task
+	MarkSynced(ctx context.Context, taskID string, status models.TaskStatus, syncedAt time.Time) error
+}
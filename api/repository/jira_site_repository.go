@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// JiraSiteRepository defines the interface for the Jira sites of organizations
+//
+//go:generate mockgen -destination=./mocks/mock_jira_site_repository.go -mock_names=JiraSiteRepository=MockJiraSiteRepository -package=mocks . JiraSiteRepository
+type JiraSiteRepository interface {
+	// PutSite stores the site of an organization, replacing the one it had. The creation time of a replaced site is
+	// kept, and site is updated with it.
+	PutSite(ctx context.Context, site *models.JiraSite) error
+
+	// GetSite gets the site of an organization
+	GetSite(ctx context.Context, organization string) (*models.JiraSite, error)
+
+	// GetSiteByProjectKey gets the site listing the Jira project key
+	GetSiteByProjectKey(ctx context.Context, projectKey string) (*models.JiraSite, error)
+
+	// ListSites lists the sites ordered by organization
+	ListSites(ctx context.Context) ([]models.JiraSite, error)
+
+	// DeleteSite deletes the site of an organization
+	DeleteSite(ctx context.Context, organization string) error
+}
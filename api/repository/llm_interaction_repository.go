@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// LLMInteractionRepository defines the interface for the log of the LLM calls made while executing tasks
+//
+//go:generate mockgen -destination=./mocks/mock_llm_interaction_repository.go -mock_names=LLMInteractionRepository=MockLLMInteractionRepository -package=mocks . LLMInteractionRepository
+type LLMInteractionRepository interface {
+	// CreateInteraction stores an LLM call
+	CreateInteraction(ctx context.Context, interaction *models.LLMInteraction) error
+
+	// ListInteractions lists the LLM calls made for a task, oldest first
+	ListInteractions(ctx context.Context, taskID string) ([]models.LLMInteraction, error)
+
+	// DeleteBefore deletes the LLM calls made before the given time, returning how many were deleted
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
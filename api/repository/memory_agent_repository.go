@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MemoryAgentRepository implements AgentRepository in memory
+type MemoryAgentRepository struct {
+	mu     sync.RWMutex
+	agents map[string]*AgentRecord
+}
+
+// NewMemoryAgentRepository creates a new empty in-memory agent repository
+func NewMemoryAgentRepository() AgentRepository {
+	return &MemoryAgentRepository{
+		agents: map[string]*AgentRecord{},
+	}
+}
+
+// cloneAgent returns a copy of agent
+func cloneAgent(agent *AgentRecord) *AgentRecord {
+	clone := *agent
+	return &clone
+}
+
+// CreateAgent stores a new agent record
+func (r *MemoryAgentRepository) CreateAgent(_ context.Context, agent *AgentRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.agents[agent.AgentID]; ok {
+		return apperrors.Conflict(apperrors.CodeAgentExists, "agent with ID %s already exists", agent.AgentID)
+	}
+	r.agents[agent.AgentID] = cloneAgent(agent)
+	return nil
+}
+
+// GetAgent retrieves an agent by ID
+func (r *MemoryAgentRepository) GetAgent(_ context.Context, agentID string) (*AgentRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	agent, ok := r.agents[agentID]
+	if !ok {
+		return nil, apperrors.NotFound(apperrors.CodeAgentNotFound, "agent not found: %s", agentID)
+	}
+	return cloneAgent(agent), nil
+}
+
+// UpdateAgent updates an existing agent record. Its AI provider and configuration, and its creation time, are kept.
+func (r *MemoryAgentRepository) UpdateAgent(_ context.Context, agent *AgentRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.agents[agent.AgentID]
+	if !ok {
+		return apperrors.NotFound(apperrors.CodeAgentNotFound, "agent not found: %s", agent.AgentID)
+	}
+
+	agent.UpdatedAt = time.Now().UTC()
+	updated := cloneAgent(agent)
+	updated.AIProvider = stored.AIProvider
+	updated.AIConfigJSON = stored.AIConfigJSON
+	updated.CreatedAt = stored.CreatedAt
+	r.agents[agent.AgentID] = updated
+	return nil
+}
+
+// DeleteAgent removes an agent record
+func (r *MemoryAgentRepository) DeleteAgent(_ context.Context, agentID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.agents[agentID]; !ok {
+		return apperrors.NotFound(apperrors.CodeAgentNotFound, "agent not found: %s", agentID)
+	}
+	delete(r.agents, agentID)
+	return nil
+}
+
+// ListAgents retrieves all agent records, newest first
+func (r *MemoryAgentRepository) ListAgents(_ context.Context) ([]*AgentRecord, error) {
+	r.mu.RLock()
+	agents := make([]*AgentRecord, 0, len(r.agents))
+	for _, agent := range r.agents {
+		agents = append(agents, cloneAgent(agent))
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(agents, func(a, b *AgentRecord) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.AgentID, b.AgentID)
+	})
+	return agents, nil
+}
+
+// UpdateAgentStatus updates only the status field
+func (r *MemoryAgentRepository) UpdateAgentStatus(_ context.Context, agentID string, status models.AgentStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	agent, ok := r.agents[agentID]
+	if !ok {
+		return apperrors.NotFound(apperrors.CodeAgentNotFound, "agent not found: %s", agentID)
+	}
+	agent.Status = string(status)
+	agent.UpdatedAt = time.Now().UTC()
+	return nil
+}
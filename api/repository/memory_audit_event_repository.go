@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MemoryAuditEventRepository implements AuditEventRepository in memory
+type MemoryAuditEventRepository struct {
+	mu     sync.RWMutex
+	events []models.AuditEvent
+}
+
+// NewMemoryAuditEventRepository creates a new empty in-memory audit event repository
+func NewMemoryAuditEventRepository() AuditEventRepository {
+	return &MemoryAuditEventRepository{}
+}
+
+// RecordEvent records an audit event
+func (r *MemoryAuditEventRepository) RecordEvent(_ context.Context, event *models.AuditEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	recorded := *event
+	recorded.Details = cloneMap(event.Details)
+	if recorded.Details == nil {
+		recorded.Details = map[string]string{}
+	}
+	r.events = append(r.events, recorded)
+	return nil
+}
+
+// StreamEvents passes the events that occurred within timeRange to fn, oldest first
+func (r *MemoryAuditEventRepository) StreamEvents(_ context.Context, timeRange TimeRange, fn func(models.AuditEvent) error) error {
+	r.mu.RLock()
+	var events []models.AuditEvent
+	for _, event := range r.events {
+		if timeRange.Contains(event.OccurredAt) {
+			event.Details = maps.Clone(event.Details)
+			events = append(events, event)
+		}
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(events, func(a, b models.AuditEvent) int {
+		return cmp.Or(a.OccurredAt.Compare(b.OccurredAt), cmp.Compare(a.EventID, b.EventID))
+	})
+	for _, event := range events {
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
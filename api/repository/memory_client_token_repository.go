@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// clientTokenKey identifies a client token within its scope
+type clientTokenKey struct {
+	scope string
+	token string
+}
+
+// MemoryClientTokenRepository implements ClientTokenRepository in memory
+type MemoryClientTokenRepository struct {
+	mu     sync.Mutex
+	tokens map[clientTokenKey]*models.ClientToken
+}
+
+// NewMemoryClientTokenRepository creates a new empty in-memory client token repository
+func NewMemoryClientTokenRepository() ClientTokenRepository {
+	return &MemoryClientTokenRepository{
+		tokens: map[clientTokenKey]*models.ClientToken{},
+	}
+}
+
+// ClaimToken records token unless its scope already has it, returning the record of the existing token, or nil
+// when token was recorded
+func (r *MemoryClientTokenRepository) ClaimToken(_ context.Context, token *models.ClientToken) (*models.ClientToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := clientTokenKey{token.Scope, token.Token}
+	if existing, ok := r.tokens[key]; ok {
+		claimed := *existing
+		claimed.ResourceID = clonePtr(existing.ResourceID)
+		return &claimed, nil
+	}
+	claimed := *token
+	claimed.ResourceID = clonePtr(token.ResourceID)
+	r.tokens[key] = &claimed
+	return nil, nil
+}
+
+// CompleteToken records the resource created by the request carrying a token
+func (r *MemoryClientTokenRepository) CompleteToken(_ context.Context, scope, token, resourceID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if claimed, ok := r.tokens[clientTokenKey{scope, token}]; ok {
+		claimed.ResourceID = &resourceID
+	}
+	return nil
+}
+
+// ReleaseToken deletes a token, so that the request carrying it can be retried
+func (r *MemoryClientTokenRepository) ReleaseToken(_ context.Context, scope, token string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.tokens, clientTokenKey{scope, token})
+	return nil
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+)
+
+// MemoryCodebaseConfigRepository implements CodebaseConfigRepository in memory
+type MemoryCodebaseConfigRepository struct {
+	mu      sync.RWMutex
+	configs map[string]*CodebaseConfigRecord
+}
+
+// NewMemoryCodebaseConfigRepository creates a new empty in-memory codebase configuration repository
+func NewMemoryCodebaseConfigRepository() CodebaseConfigRepository {
+	return &MemoryCodebaseConfigRepository{
+		configs: map[string]*CodebaseConfigRecord{},
+	}
+}
+
+// cloneCodebaseConfig returns a copy of config sharing no maps or pointers with it
+func cloneCodebaseConfig(config *CodebaseConfigRecord) *CodebaseConfigRecord {
+	clone := *config
+	clone.Description = clonePtr(config.Description)
+	clone.Tags = cloneMap(config.Tags)
+	clone.Config.GitHub = clonePtr(config.Config.GitHub)
+	clone.Config.GitLab = clonePtr(config.Config.GitLab)
+	clone.Config.Bitbucket = clonePtr(config.Config.Bitbucket)
+	clone.Config.AzureDevOps = clonePtr(config.Config.AzureDevOps)
+	clone.Config.Custom = clonePtr(config.Config.Custom)
+	if clone.Config.Custom != nil {
+		clone.Config.Custom.Headers = cloneMap(config.Config.Custom.Headers)
+	}
+	return &clone
+}
+
+// CreateCodebaseConfig creates a new codebase configuration record at version 1
+func (r *MemoryCodebaseConfigRepository) CreateCodebaseConfig(_ context.Context, config *CodebaseConfigRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.configs[config.ConfigID]; ok {
+		return apperrors.Conflict(apperrors.CodeCodebaseConfigExists, "codebase configuration with ID %s already exists", config.ConfigID)
+	}
+	config.Version = 1
+	r.configs[config.ConfigID] = cloneCodebaseConfig(config)
+	return nil
+}
+
+// GetCodebaseConfig retrieves a codebase configuration by ID
+func (r *MemoryCodebaseConfigRepository) GetCodebaseConfig(_ context.Context, configID string) (*CodebaseConfigRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	config, ok := r.configs[configID]
+	if !ok {
+		return nil, apperrors.NotFound(apperrors.CodeCodebaseConfigNotFound, "codebase configuration not found")
+	}
+	return cloneCodebaseConfig(config), nil
+}
+
+// UpdateCodebaseConfig updates an existing codebase configuration record if its stored version still equals
+// config.Version, then increments config.Version. Its creation time is kept.
+func (r *MemoryCodebaseConfigRepository) UpdateCodebaseConfig(_ context.Context, config *CodebaseConfigRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.configs[config.ConfigID]
+	if !ok {
+		return apperrors.NotFound(apperrors.CodeCodebaseConfigNotFound, "codebase configuration with ID %s does not exist", config.ConfigID)
+	}
+	if stored.Version != config.Version {
+		return apperrors.PreconditionFailed(apperrors.CodeVersionMismatch, "codebase configuration with ID %s was modified since version %d", config.ConfigID, config.Version)
+	}
+
+	config.Version++
+	updated := cloneCodebaseConfig(config)
+	updated.CreatedAt = stored.CreatedAt
+	r.configs[config.ConfigID] = updated
+	return nil
+}
+
+// DeleteCodebaseConfig deletes a codebase configuration by ID
+func (r *MemoryCodebaseConfigRepository) DeleteCodebaseConfig(_ context.Context, configID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.configs[configID]; !ok {
+		return apperrors.NotFound(apperrors.CodeCodebaseConfigNotFound, "codebase configuration with ID %s does not exist", configID)
+	}
+	delete(r.configs, configID)
+	return nil
+}
+
+// ListCodebaseConfigs retrieves codebase configurations ordered by ID, a page at a time when MaxResults is set. The
+// next token is the ID of the last configuration of a page followed by another.
+func (r *MemoryCodebaseConfigRepository) ListCodebaseConfigs(_ context.Context, opts ListCodebaseConfigsOptions) ([]*CodebaseConfigRecord, string, error) {
+	r.mu.RLock()
+	var configs []*CodebaseConfigRecord
+	for _, config := range r.configs {
+		if opts.ProviderFilter != nil && config.Provider != string(*opts.ProviderFilter) {
+			continue
+		}
+		if !hasTags(config.Tags, opts.TagFilter) {
+			continue
+		}
+		if opts.NextToken != nil && *opts.NextToken != "" && config.ConfigID <= *opts.NextToken {
+			continue
+		}
+		configs = append(configs, cloneCodebaseConfig(config))
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(configs, func(a, b *CodebaseConfigRecord) int {
+		return strings.Compare(a.ConfigID, b.ConfigID)
+	})
+
+	var nextToken string
+	if opts.MaxResults != nil && len(configs) > *opts.MaxResults {
+		configs = configs[:*opts.MaxResults]
+		nextToken = configs[len(configs)-1].ConfigID
+	}
+	return configs, nextToken, nil
+}
+
+// CodebaseConfigExists checks if a codebase configuration exists by ID
+func (r *MemoryCodebaseConfigRepository) CodebaseConfigExists(_ context.Context, configID string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.configs[configID]
+	return ok, nil
+}
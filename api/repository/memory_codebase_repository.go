@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MemoryCodebaseRepository implements CodebaseRepository in memory
+type MemoryCodebaseRepository struct {
+	mu        sync.RWMutex
+	codebases map[string]*models.Codebase
+	projects  ProjectRepository
+}
+
+// NewMemoryCodebaseRepository creates a new empty in-memory codebase repository. Codebases are only created in the
+// projects of projects, like the foreign key of the Postgres table enforces.
+func NewMemoryCodebaseRepository(projects ProjectRepository) CodebaseRepository {
+	return &MemoryCodebaseRepository{
+		codebases: map[string]*models.Codebase{},
+		projects:  projects,
+	}
+}
+
+// cloneCodebase returns a copy of codebase sharing no maps or pointers with it
+func cloneCodebase(codebase *models.Codebase) *models.Codebase {
+	clone := *codebase
+	clone.LastSyncAt = clonePtr(codebase.LastSyncAt)
+	clone.Metadata = cloneMap(codebase.Metadata)
+	clone.Tags = cloneMap(codebase.Tags)
+	clone.IngestionError = clonePtr(codebase.IngestionError)
+	clone.ExpiresAt = clonePtr(codebase.ExpiresAt)
+	clone.Checkout = clonePtr(codebase.Checkout)
+	return &clone
+}
+
+// CreateCodebase creates a new codebase record
+func (r *MemoryCodebaseRepository) CreateCodebase(ctx context.Context, codebase *models.Codebase) error {
+	exists, err := r.projects.ProjectExists(ctx, codebase.ProjectID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return apperrors.NotFound(apperrors.CodeProjectNotFound, "project with ID %s does not exist", codebase.ProjectID)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.codebases[codebase.CodebaseID]; ok {
+		return apperrors.Conflict(apperrors.CodeCodebaseExists, "codebase with ID %s already exists", codebase.CodebaseID)
+	}
+	r.codebases[codebase.CodebaseID] = cloneCodebase(codebase)
+	return nil
+}
+
+// GetCodebase retrieves a codebase by ID
+func (r *MemoryCodebaseRepository) GetCodebase(_ context.Context, codebaseID string) (*models.Codebase, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	codebase, ok := r.codebases[codebaseID]
+	if !ok {
+		return nil, apperrors.NotFound(apperrors.CodeCodebaseNotFound, "codebase not found")
+	}
+	return cloneCodebase(codebase), nil
+}
+
+// UpdateCodebase updates the mutable fields of an existing codebase. Its project, provider, URL, creation and expiry
+// are kept.
+func (r *MemoryCodebaseRepository) UpdateCodebase(_ context.Context, codebase *models.Codebase) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.codebases[codebase.CodebaseID]
+	if !ok {
+		return apperrors.NotFound(apperrors.CodeCodebaseNotFound, "codebase not found")
+	}
+
+	updated := cloneCodebase(codebase)
+	updated.ProjectID = stored.ProjectID
+	updated.Provider = stored.Provider
+	updated.URL = stored.URL
+	updated.CreatedAt = stored.CreatedAt
+	updated.ExpiresAt = stored.ExpiresAt
+	r.codebases[codebase.CodebaseID] = updated
+	return nil
+}
+
+// DeleteCodebase deletes a codebase by ID
+func (r *MemoryCodebaseRepository) DeleteCodebase(_ context.Context, codebaseID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.codebases[codebaseID]; !ok {
+		return apperrors.NotFound(apperrors.CodeCodebaseNotFound, "codebase not found")
+	}
+	delete(r.codebases, codebaseID)
+	return nil
+}
+
+// ListCodebases lists the codebases matching filter, newest first, 50 to a page unless filter sets another size. The
+// next token is the offset of the next page.
+func (r *MemoryCodebaseRepository) ListCodebases(_ context.Context, filter CodebaseFilter) ([]*models.Codebase, string, error) {
+	var tagKey, tagValue string
+	var hasTagFilter bool
+	if filter.TagFilter != nil {
+		tagKey, tagValue, hasTagFilter = strings.Cut(*filter.TagFilter, ":")
+	}
+
+	codebases := r.newestFirst(func(codebase *models.Codebase) bool {
+		if filter.ProjectID != nil && codebase.ProjectID != *filter.ProjectID {
+			return false
+		}
+		if filter.Provider != nil && codebase.Provider != *filter.Provider {
+			return false
+		}
+		if hasTagFilter {
+			if value, ok := codebase.Tags[tagKey]; !ok || value != tagValue {
+				return false
+			}
+		}
+		return true
+	})
+
+	maxResults := 50
+	if filter.MaxResults != nil {
+		maxResults = *filter.MaxResults
+	}
+	offset := 0
+	if filter.NextToken != nil {
+		if parsed, err := strconv.Atoi(*filter.NextToken); err == nil {
+			offset = parsed
+		}
+	}
+
+	remaining := page(codebases, offset, 0)
+	var nextToken string
+	if len(remaining) > maxResults {
+		remaining = remaining[:maxResults]
+		nextToken = strconv.Itoa(offset + maxResults)
+	}
+	return remaining, nextToken, nil
+}
+
+// CodebaseExists checks if a codebase exists
+func (r *MemoryCodebaseRepository) CodebaseExists(_ context.Context, codebaseID string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.codebases[codebaseID]
+	return ok, nil
+}
+
+// GetCodebasesByProject gets all codebases of a project, newest first
+func (r *MemoryCodebaseRepository) GetCodebasesByProject(_ context.Context, projectID string) ([]*models.Codebase, error) {
+	return r.newestFirst(func(codebase *models.Codebase) bool {
+		return codebase.ProjectID == projectID
+	}), nil
+}
+
+// CountByProject counts the project's codebases grouped by status, with the latest sync of each status
+func (r *MemoryCodebaseRepository) CountByProject(_ context.Context, projectID string) ([]CodebaseStatusCount, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var counts []CodebaseStatusCount
+	for _, codebase := range r.codebases {
+		if codebase.ProjectID != projectID {
+			continue
+		}
+		i := slices.IndexFunc(counts, func(count CodebaseStatusCount) bool { return count.Status == codebase.Status })
+		if i < 0 {
+			counts = append(counts, CodebaseStatusCount{Status: codebase.Status})
+			i = len(counts) - 1
+		}
+		counts[i].Count++
+		if codebase.LastSyncAt != nil && (counts[i].LastSyncAt == nil || codebase.LastSyncAt.After(*counts[i].LastSyncAt)) {
+			counts[i].LastSyncAt = clonePtr(codebase.LastSyncAt)
+		}
+	}
+	return counts, nil
+}
+
+// newestFirst returns copies of the codebases matching match, newest first
+func (r *MemoryCodebaseRepository) newestFirst(match func(*models.Codebase) bool) []*models.Codebase {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var codebases []*models.Codebase
+	for _, codebase := range r.codebases {
+		if match(codebase) {
+			codebases = append(codebases, cloneCodebase(codebase))
+		}
+	}
+	slices.SortFunc(codebases, func(a, b *models.Codebase) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.CodebaseID, b.CodebaseID)
+	})
+	return codebases
+}
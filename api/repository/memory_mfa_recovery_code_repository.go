@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryMFARecoveryCodeRepository implements MFARecoveryCodeRepository in memory
+type MemoryMFARecoveryCodeRepository struct {
+	mu sync.Mutex
+	// unused holds the hashes of the unused recovery codes of each user
+	unused map[string]map[string]bool
+}
+
+// NewMemoryMFARecoveryCodeRepository creates a new empty in-memory MFA recovery code repository
+func NewMemoryMFARecoveryCodeRepository() MFARecoveryCodeRepository {
+	return &MemoryMFARecoveryCodeRepository{
+		unused: map[string]map[string]bool{},
+	}
+}
+
+// ReplaceRecoveryCodes replaces the recovery codes of a user with new ones
+func (r *MemoryMFARecoveryCodeRepository) ReplaceRecoveryCodes(_ context.Context, userID string, codeHashes []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	codes := make(map[string]bool, len(codeHashes))
+	for _, codeHash := range codeHashes {
+		codes[codeHash] = true
+	}
+	r.unused[userID] = codes
+	return nil
+}
+
+// ConsumeRecoveryCode marks an unused recovery code of a user used
+func (r *MemoryMFARecoveryCodeRepository) ConsumeRecoveryCode(_ context.Context, userID, codeHash string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.unused[userID][codeHash] {
+		return false, nil
+	}
+	delete(r.unused[userID], codeHash)
+	return true, nil
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+)
+
+// MemoryProjectRepository implements ProjectRepository in memory
+type MemoryProjectRepository struct {
+	mu       sync.RWMutex
+	projects map[string]*ProjectRecord
+}
+
+// NewMemoryProjectRepository creates a new empty in-memory project repository
+func NewMemoryProjectRepository() ProjectRepository {
+	return &MemoryProjectRepository{
+		projects: map[string]*ProjectRecord{},
+	}
+}
+
+// cloneProject returns a copy of project sharing no maps or pointers with it
+func cloneProject(project *ProjectRecord) *ProjectRecord {
+	clone := *project
+	clone.Description = clonePtr(project.Description)
+	clone.Language = clonePtr(project.Language)
+	clone.Tags = cloneMap(project.Tags)
+	clone.Metadata = cloneMap(project.Metadata)
+	return &clone
+}
+
+// CreateProject creates a new project record at version 1
+func (r *MemoryProjectRepository) CreateProject(_ context.Context, project *ProjectRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.projects[project.ProjectID]; ok {
+		return apperrors.Conflict(apperrors.CodeProjectExists, "project with ID %s already exists", project.ProjectID)
+	}
+
+	project.Version = 1
+	r.projects[project.ProjectID] = cloneProject(project)
+	return nil
+}
+
+// GetProject retrieves a project by ID, nil when it doesn't exist
+func (r *MemoryProjectRepository) GetProject(_ context.Context, projectID string) (*ProjectRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	project, ok := r.projects[projectID]
+	if !ok {
+		return nil, nil
+	}
+	return cloneProject(project), nil
+}
+
+// UpdateProject updates a project if its stored version still equals project.Version, then increments it
+func (r *MemoryProjectRepository) UpdateProject(_ context.Context, project *ProjectRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.projects[project.ProjectID]
+	if !ok {
+		return apperrors.NotFound(apperrors.CodeProjectNotFound, "project with ID %s does not exist", project.ProjectID)
+	}
+	if stored.Version != project.Version {
+		return apperrors.PreconditionFailed(apperrors.CodeVersionMismatch, "project with ID %s was modified since version %d", project.ProjectID, project.Version)
+	}
+
+	updated := cloneProject(project)
+	updated.CreatedAt = stored.CreatedAt
+	updated.Version = stored.Version + 1
+	r.projects[project.ProjectID] = updated
+	project.Version = updated.Version
+	return nil
+}
+
+// DeleteProject deletes a project by ID
+func (r *MemoryProjectRepository) DeleteProject(_ context.Context, projectID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.projects[projectID]; !ok {
+		return apperrors.NotFound(apperrors.CodeProjectNotFound, "project with ID %s does not exist", projectID)
+	}
+	delete(r.projects, projectID)
+	return nil
+}
+
+// ListProjects lists the projects matching the tag filter in the order of their IDs, a page after the project whose ID
+// is the next token
+func (r *MemoryProjectRepository) ListProjects(_ context.Context, opts ListProjectsOptions) ([]*ProjectRecord, string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var projects []*ProjectRecord
+	for _, project := range r.projects {
+		if !hasTags(project.Tags, opts.TagFilter) {
+			continue
+		}
+		if !opts.IncludeArchived && project.Archived() {
+			continue
+		}
+		if opts.NextToken != nil && *opts.NextToken != "" && project.ProjectID <= *opts.NextToken {
+			continue
+		}
+		projects = append(projects, cloneProject(project))
+	}
+	slices.SortFunc(projects, func(a, b *ProjectRecord) int {
+		return strings.Compare(a.ProjectID, b.ProjectID)
+	})
+
+	var nextToken string
+	if opts.MaxResults != nil && len(projects) > *opts.MaxResults {
+		projects = projects[:*opts.MaxResults]
+		if len(projects) > 0 {
+			nextToken = projects[len(projects)-1].ProjectID
+		}
+	}
+	return projects, nextToken, nil
+}
+
+// ProjectExists checks if a project exists by ID
+func (r *MemoryProjectRepository) ProjectExists(_ context.Context, projectID string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.projects[projectID]
+	return ok, nil
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MemoryRedactionPolicyRepository implements RedactionPolicyRepository in memory
+type MemoryRedactionPolicyRepository struct {
+	mu       sync.RWMutex
+	policies map[string]*models.RedactionPolicy
+}
+
+// NewMemoryRedactionPolicyRepository creates a new empty in-memory redaction policy repository
+func NewMemoryRedactionPolicyRepository() RedactionPolicyRepository {
+	return &MemoryRedactionPolicyRepository{
+		policies: map[string]*models.RedactionPolicy{},
+	}
+}
+
+// cloneRedactionPolicy returns a copy of policy, its patterns empty rather than nil
+func cloneRedactionPolicy(policy *models.RedactionPolicy) *models.RedactionPolicy {
+	clone := *policy
+	clone.Patterns = append([]models.RedactionPattern{}, policy.Patterns...)
+	clone.UpdatedAt = clonePtr(policy.UpdatedAt)
+	if policy.Files != nil {
+		clone.Files = &models.NoiseFileFilter{
+			ExcludedKinds: slices.Clone(policy.Files.ExcludedKinds),
+			IncludePaths:  slices.Clone(policy.Files.IncludePaths),
+			ExcludePaths:  slices.Clone(policy.Files.ExcludePaths),
+		}
+	}
+	return &clone
+}
+
+// GetPolicy retrieves the redaction policy of a project, nil when none was saved
+func (r *MemoryRedactionPolicyRepository) GetPolicy(_ context.Context, projectID string) (*models.RedactionPolicy, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	policy, ok := r.policies[projectID]
+	if !ok {
+		return nil, nil
+	}
+	return cloneRedactionPolicy(policy), nil
+}
+
+// SavePolicy creates or replaces the redaction policy of a project
+func (r *MemoryRedactionPolicyRepository) SavePolicy(_ context.Context, policy *models.RedactionPolicy) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.policies[policy.ProjectID] = cloneRedactionPolicy(policy)
+	return nil
+}
+
+// MemoryRedactionAuditRepository implements RedactionAuditRepository in memory
+type MemoryRedactionAuditRepository struct {
+	mu     sync.RWMutex
+	audits []models.RedactionAudit
+}
+
+// NewMemoryRedactionAuditRepository creates a new empty in-memory redaction audit repository
+func NewMemoryRedactionAuditRepository() RedactionAuditRepository {
+	return &MemoryRedactionAuditRepository{}
+}
+
+// CreateAudit records the redactions made while syncing an agent
+func (r *MemoryRedactionAuditRepository) CreateAudit(_ context.Context, audit *models.RedactionAudit) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	created := *audit
+	created.Counts = cloneMap(audit.Counts)
+	if created.Counts == nil {
+		created.Counts = map[string]int{}
+	}
+	r.audits = append(r.audits, created)
+	return nil
+}
+
+// ListAudits lists the most recent audits of a project, newest first
+func (r *MemoryRedactionAuditRepository) ListAudits(_ context.Context, projectID string, limit int) ([]models.RedactionAudit, error) {
+	audits := r.projectAudits(projectID)
+	return append([]models.RedactionAudit{}, page(audits, 0, limit)...), nil
+}
+
+// StreamAudits passes every audit of a project to fn, newest first
+func (r *MemoryRedactionAuditRepository) StreamAudits(_ context.Context, projectID string, fn func(models.RedactionAudit) error) error {
+	for _, audit := range r.projectAudits(projectID) {
+		if err := fn(audit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// projectAudits returns copies of the audits of a project, newest first
+func (r *MemoryRedactionAuditRepository) projectAudits(projectID string) []models.RedactionAudit {
+	r.mu.RLock()
+	var audits []models.RedactionAudit
+	for _, audit := range r.audits {
+		if audit.ProjectID == projectID {
+			audit.Counts = cloneMap(audit.Counts)
+			audits = append(audits, audit)
+		}
+	}
+	r.mu.RUnlock()
+
+	slices.SortStableFunc(audits, func(a, b models.RedactionAudit) int {
+		return b.SyncedAt.Compare(a.SyncedAt)
+	})
+	return audits
+}
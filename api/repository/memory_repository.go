@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"maps"
+)
+
+// The memory repositories keep their records in maps guarded by a mutex. Records are copied in and out, so callers
+// never share a stored record, and they list records in the order and pages of their Postgres counterparts. Records
+// are lost when the service stops.
+
+// clonePtr returns a copy of the value p points to, nil when p is
+func clonePtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+// hasTags reports whether tags carry every key and value of filter
+func hasTags(tags, filter map[string]string) bool {
+	for key, value := range filter {
+		if got, ok := tags[key]; !ok || got != value {
+			return false
+		}
+	}
+	return true
+}
+
+// cloneMap returns a copy of m, nil when m is
+func cloneMap[M ~map[K]V, K comparable, V any](m M) M {
+	if m == nil {
+		return nil
+	}
+	return maps.Clone(m)
+}
+
+// page returns the items from offset, at most limit of them, or all of them when limit isn't positive
+func page[T any](items []T, offset, limit int) []T {
+	if offset >= len(items) {
+		return nil
+	}
+	items = items[max(offset, 0):]
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
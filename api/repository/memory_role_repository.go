@@ -0,0 +1,279 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// memberKey identifies a member of a project
+type memberKey struct {
+	projectID string
+	userID    string
+}
+
+// MemoryRoleRepository implements RoleRepository in memory
+type MemoryRoleRepository struct {
+	mu          sync.RWMutex
+	permissions map[models.Permission]models.PermissionDefinition
+	roles       map[models.UserRole]*models.Role
+	members     map[memberKey]*models.ProjectMember
+}
+
+// NewMemoryRoleRepository creates a new in-memory role repository holding the built-in permissions and roles
+func NewMemoryRoleRepository() RoleRepository {
+	r := &MemoryRoleRepository{
+		permissions: map[models.Permission]models.PermissionDefinition{},
+		roles:       map[models.UserRole]*models.Role{},
+		members:     map[memberKey]*models.ProjectMember{},
+	}
+	for _, permission := range models.BuiltInPermissions {
+		r.permissions[permission.Name] = permission
+	}
+	now := time.Now().UTC()
+	for _, role := range models.BuiltInRoles {
+		role.BuiltIn = true
+		role.CreatedAt = now
+		role.UpdatedAt = now
+		r.roles[role.Name] = cloneRole(&role)
+	}
+	return r
+}
+
+// cloneRole returns a copy of role with its permissions sorted
+func cloneRole(role *models.Role) *models.Role {
+	clone := *role
+	clone.Permissions = append([]models.Permission{}, role.Permissions...)
+	slices.Sort(clone.Permissions)
+	clone.Permissions = slices.Compact(clone.Permissions)
+	return &clone
+}
+
+// ListPermissions lists the permissions roles can grant ordered by name
+func (r *MemoryRoleRepository) ListPermissions(_ context.Context) ([]models.PermissionDefinition, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	permissions := slices.SortedFunc(maps.Values(r.permissions), func(a, b models.PermissionDefinition) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return permissions, nil
+}
+
+// CreateRole creates a role with its permissions
+func (r *MemoryRoleRepository) CreateRole(_ context.Context, role *models.Role) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.roles[role.Name]; ok {
+		return apperrors.Conflict(apperrors.CodeRoleExists, "role %s already exists", role.Name)
+	}
+	if err := r.checkPermissions(role); err != nil {
+		return err
+	}
+	r.roles[role.Name] = cloneRole(role)
+	return nil
+}
+
+// GetRole retrieves a role with its permissions
+func (r *MemoryRoleRepository) GetRole(_ context.Context, name models.UserRole) (*models.Role, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	role, ok := r.roles[name]
+	if !ok {
+		return nil, nil
+	}
+	return cloneRole(role), nil
+}
+
+// UpdateRole replaces the description and permissions of a role
+func (r *MemoryRoleRepository) UpdateRole(_ context.Context, role *models.Role) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.roles[role.Name]
+	if !ok {
+		return apperrors.NotFound(apperrors.CodeRoleNotFound, "role not found: %s", role.Name)
+	}
+	if err := r.checkPermissions(role); err != nil {
+		return err
+	}
+	updated := cloneRole(role)
+	updated.BuiltIn = stored.BuiltIn
+	updated.CreatedAt = stored.CreatedAt
+	r.roles[role.Name] = updated
+	return nil
+}
+
+// DeleteRole deletes a role along with the permissions it grants
+func (r *MemoryRoleRepository) DeleteRole(_ context.Context, name models.UserRole) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.roles[name]; !ok {
+		return apperrors.NotFound(apperrors.CodeRoleNotFound, "role not found: %s", name)
+	}
+	for _, member := range r.members {
+		if member.Role == name {
+			return apperrors.Conflict(apperrors.CodeRoleInUse, "role %s is held by project members", name)
+		}
+	}
+	delete(r.roles, name)
+	return nil
+}
+
+// ListRoles lists the roles with their permissions ordered by name
+func (r *MemoryRoleRepository) ListRoles(_ context.Context) ([]models.Role, error) {
+	r.mu.RLock()
+	roles := make([]models.Role, 0, len(r.roles))
+	for _, role := range r.roles {
+		roles = append(roles, *cloneRole(role))
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(roles, func(a, b models.Role) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return roles, nil
+}
+
+// SetProjectMember adds a member to a project or replaces the role of a member
+func (r *MemoryRoleRepository) SetProjectMember(_ context.Context, member *models.ProjectMember) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.roles[member.Role]; !ok {
+		return apperrors.NotFound(apperrors.CodeRoleNotFound, "role not found: %s", member.Role)
+	}
+	if member.Role == models.RoleOwner {
+		if owner := r.owner(member.ProjectID); owner != nil && owner.UserID != member.UserID {
+			return apperrors.Conflict(apperrors.CodeProjectOwner, "project %s already has an owner", member.ProjectID)
+		}
+	}
+
+	key := memberKey{member.ProjectID, member.UserID}
+	stored := clonePtr(member)
+	if existing, ok := r.members[key]; ok {
+		stored.CreatedAt = existing.CreatedAt
+	}
+	r.members[key] = stored
+	return nil
+}
+
+// GetProjectMember retrieves a member of a project
+func (r *MemoryRoleRepository) GetProjectMember(_ context.Context, projectID, userID string) (*models.ProjectMember, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return clonePtr(r.members[memberKey{projectID, userID}]), nil
+}
+
+// DeleteProjectMember removes a member from a project
+func (r *MemoryRoleRepository) DeleteProjectMember(_ context.Context, projectID, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := memberKey{projectID, userID}
+	if _, ok := r.members[key]; !ok {
+		return apperrors.NotFound(apperrors.CodeProjectMemberNotFound, "user %s is not a member of project %s", userID, projectID)
+	}
+	delete(r.members, key)
+	return nil
+}
+
+// ListProjectMembers lists the members of a project ordered by user
+func (r *MemoryRoleRepository) ListProjectMembers(_ context.Context, projectID string) ([]models.ProjectMember, error) {
+	r.mu.RLock()
+	members := []models.ProjectMember{}
+	for _, member := range r.members {
+		if member.ProjectID == projectID {
+			members = append(members, *member)
+		}
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(members, func(a, b models.ProjectMember) int {
+		return cmp.Compare(a.UserID, b.UserID)
+	})
+	return members, nil
+}
+
+// TransferProjectOwnership makes a user the owner of a project, demoting its previous owner to admin
+func (r *MemoryRoleRepository) TransferProjectOwnership(_ context.Context, projectID, userID string, transferredAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if owner := r.owner(projectID); owner != nil && owner.UserID != userID {
+		owner.Role = models.RoleAdmin
+		owner.UpdatedAt = transferredAt
+	}
+
+	key := memberKey{projectID, userID}
+	if member, ok := r.members[key]; ok {
+		member.Role = models.RoleOwner
+		member.UpdatedAt = transferredAt
+		return nil
+	}
+	r.members[key] = &models.ProjectMember{
+		ProjectID: projectID,
+		UserID:    userID,
+		Role:      models.RoleOwner,
+		CreatedAt: transferredAt,
+		UpdatedAt: transferredAt,
+	}
+	return nil
+}
+
+// StreamProjectMembers passes the members of every project whose role last changed within timeRange to fn, oldest
+// change first
+func (r *MemoryRoleRepository) StreamProjectMembers(_ context.Context, timeRange TimeRange, fn func(models.ProjectMember) error) error {
+	r.mu.RLock()
+	var members []models.ProjectMember
+	for _, member := range r.members {
+		if timeRange.Contains(member.UpdatedAt) {
+			members = append(members, *member)
+		}
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(members, func(a, b models.ProjectMember) int {
+		return cmp.Or(
+			a.UpdatedAt.Compare(b.UpdatedAt),
+			cmp.Compare(a.ProjectID, b.ProjectID),
+			cmp.Compare(a.UserID, b.UserID),
+		)
+	})
+	for _, member := range members {
+		if err := fn(member); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// owner returns the member owning a project, nil when it has none. The caller holds the lock.
+func (r *MemoryRoleRepository) owner(projectID string) *models.ProjectMember {
+	for _, member := range r.members {
+		if member.ProjectID == projectID && member.Role == models.RoleOwner {
+			return member
+		}
+	}
+	return nil
+}
+
+// checkPermissions rejects a role granting an unknown permission. The caller holds the lock.
+func (r *MemoryRoleRepository) checkPermissions(role *models.Role) error {
+	for _, permission := range role.Permissions {
+		if _, ok := r.permissions[permission]; !ok {
+			return apperrors.Validation(apperrors.CodeUnknownPermission, "role %s grants an unknown permission", role.Name)
+		}
+	}
+	return nil
+}
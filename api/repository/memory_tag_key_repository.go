@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MemoryTagKeyRepository implements TagKeyRepository in memory
+type MemoryTagKeyRepository struct {
+	mu      sync.RWMutex
+	tagKeys map[string]*models.TagKey
+}
+
+// NewMemoryTagKeyRepository creates a new empty in-memory tag key repository
+func NewMemoryTagKeyRepository() TagKeyRepository {
+	return &MemoryTagKeyRepository{
+		tagKeys: map[string]*models.TagKey{},
+	}
+}
+
+// cloneTagKey returns a copy of tagKey, its rules empty rather than nil
+func cloneTagKey(tagKey *models.TagKey) *models.TagKey {
+	clone := *tagKey
+	clone.AllowedValues = append([]string{}, tagKey.AllowedValues...)
+	clone.EditorRoles = append([]models.UserRole{}, tagKey.EditorRoles...)
+	return &clone
+}
+
+// CreateTagKey registers a new tag key
+func (r *MemoryTagKeyRepository) CreateTagKey(_ context.Context, tagKey *models.TagKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.tagKeys[tagKey.Key]; ok {
+		return apperrors.Conflict(apperrors.CodeTagKeyExists, "tag key %s is already registered", tagKey.Key)
+	}
+	r.tagKeys[tagKey.Key] = cloneTagKey(tagKey)
+	return nil
+}
+
+// GetTagKey retrieves a registered tag key
+func (r *MemoryTagKeyRepository) GetTagKey(_ context.Context, key string) (*models.TagKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tagKey, ok := r.tagKeys[key]
+	if !ok {
+		return nil, nil
+	}
+	return cloneTagKey(tagKey), nil
+}
+
+// UpdateTagKey replaces the rules of a registered tag key
+func (r *MemoryTagKeyRepository) UpdateTagKey(_ context.Context, tagKey *models.TagKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.tagKeys[tagKey.Key]
+	if !ok {
+		return apperrors.NotFound(apperrors.CodeTagKeyNotFound, "tag key not found: %s", tagKey.Key)
+	}
+	updated := cloneTagKey(tagKey)
+	updated.CreatedAt = stored.CreatedAt
+	r.tagKeys[tagKey.Key] = updated
+	return nil
+}
+
+// DeleteTagKey unregisters a tag key
+func (r *MemoryTagKeyRepository) DeleteTagKey(_ context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.tagKeys[key]; !ok {
+		return apperrors.NotFound(apperrors.CodeTagKeyNotFound, "tag key not found: %s", key)
+	}
+	delete(r.tagKeys, key)
+	return nil
+}
+
+// ListTagKeys lists the registered tag keys ordered by key
+func (r *MemoryTagKeyRepository) ListTagKeys(_ context.Context) ([]models.TagKey, error) {
+	r.mu.RLock()
+	tagKeys := make([]models.TagKey, 0, len(r.tagKeys))
+	for _, tagKey := range r.tagKeys {
+		tagKeys = append(tagKeys, *cloneTagKey(tagKey))
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(tagKeys, func(a, b models.TagKey) int {
+		return cmp.Compare(a.Key, b.Key)
+	})
+	return tagKeys, nil
+}
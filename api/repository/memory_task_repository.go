@@ -0,0 +1,556 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MemoryTaskRepository implements TaskRepository in memory. The notifications raised by task changes are enqueued in
+// the outbox while the change holds the lock, and the change is undone when they can't be, so a notification is
+// delivered if and only if its change is stored.
+type MemoryTaskRepository struct {
+	mu     sync.RWMutex
+	tasks  map[string]*models.Task
+	outbox NotificationOutboxRepository
+}
+
+// NewMemoryTaskRepository creates a new empty in-memory task repository recording notifications in outbox. The
+// notifications are dropped when outbox is nil, since no storage backend other than Postgres delivers them.
+func NewMemoryTaskRepository(outbox NotificationOutboxRepository) TaskRepository {
+	return &MemoryTaskRepository{
+		tasks:  map[string]*models.Task{},
+		outbox: outbox,
+	}
+}
+
+// cloneTask returns a copy of the stored fields of task, sharing no maps or pointers with it
+func cloneTask(task *models.Task) *models.Task {
+	return &models.Task{
+		TaskID:        task.TaskID,
+		ProjectID:     task.ProjectID,
+		BatchID:       clonePtr(task.BatchID),
+		CampaignID:    clonePtr(task.CampaignID),
+		CreatedBy:     clonePtr(task.CreatedBy),
+		AgentID:       task.AgentID,
+		CodebaseID:    clonePtr(task.CodebaseID),
+		Branch:        clonePtr(task.Branch),
+		CommitSHA:     clonePtr(task.CommitSHA),
+		Type:          task.Type,
+		AnalysisMode:  clonePtr(task.AnalysisMode),
+		Status:        task.Status,
+		Priority:      task.Priority,
+		Title:         task.Title,
+		Description:   task.Description,
+		Input:         cloneMap(task.Input),
+		Output:        cloneMap(task.Output),
+		ErrorMessage:  clonePtr(task.ErrorMessage),
+		CreatedAt:     task.CreatedAt,
+		UpdatedAt:     task.UpdatedAt,
+		CompletedAt:   clonePtr(task.CompletedAt),
+		QueuedAt:      clonePtr(task.QueuedAt),
+		HeartbeatAt:   clonePtr(task.HeartbeatAt),
+		Progress:      task.Progress,
+		ProgressStep:  clonePtr(task.ProgressStep),
+		ExperimentID:  clonePtr(task.ExperimentID),
+		ExperimentArm: clonePtr(task.ExperimentArm),
+		Approved:      clonePtr(task.Approved),
+		Metadata:      cloneMap(task.Metadata),
+		Tags:          cloneMap(task.Tags),
+	}
+}
+
+// newTask prepares a task for storage like the Postgres insert does, generating its ID when it has none
+func newTask(task *models.Task) *models.Task {
+	if task.TaskID == "" {
+		task.TaskID = "task-" + uuid.New().String()
+	}
+	now := time.Now()
+	task.CreatedAt = now
+	task.UpdatedAt = now
+	if task.Priority == "" {
+		task.Priority = models.TaskPriorityNormal
+	}
+
+	stored := cloneTask(task)
+	stored.QueuedAt = nil
+	stored.HeartbeatAt = nil
+	stored.Progress = 0
+	stored.ProgressStep = nil
+	stored.ExperimentID = nil
+	stored.ExperimentArm = nil
+	stored.Approved = nil
+	return stored
+}
+
+// Create creates a new task
+func (r *MemoryTaskRepository) Create(_ context.Context, task *models.Task) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.tasks[task.TaskID]; ok && task.TaskID != "" {
+		return fmt.Errorf("task %s already exists", task.TaskID)
+	}
+	stored := newTask(task)
+	r.tasks[stored.TaskID] = stored
+	return nil
+}
+
+// CreateBatch creates multiple tasks atomically; either all tasks are stored or none are
+func (r *MemoryTaskRepository) CreateBatch(_ context.Context, tasks []*models.Task) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := make([]*models.Task, 0, len(tasks))
+	ids := map[string]bool{}
+	for _, task := range tasks {
+		if _, ok := r.tasks[task.TaskID]; (ok || ids[task.TaskID]) && task.TaskID != "" {
+			return fmt.Errorf("failed to insert task %s: task %s already exists", task.Title, task.TaskID)
+		}
+		ids[task.TaskID] = true
+		stored = append(stored, newTask(task))
+	}
+	for _, task := range stored {
+		r.tasks[task.TaskID] = task
+	}
+	return nil
+}
+
+// GetByID retrieves a task by its ID
+func (r *MemoryTaskRepository) GetByID(_ context.Context, taskID string) (*models.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	task, ok := r.tasks[taskID]
+	if !ok {
+		return nil, apperrors.NotFound(apperrors.CodeTaskNotFound, "task not found: %s", taskID)
+	}
+	return cloneTask(task), nil
+}
+
+// Update updates an existing task and records the notification it raises. A task that moved on in the meantime to a
+// status that can't move to the task's is left unchanged. The fields set by the queue, the executions and the
+// experiments are kept.
+func (r *MemoryTaskRepository) Update(ctx context.Context, task *models.Task, notification *models.Notification) error {
+	task.UpdatedAt = time.Now()
+
+	return r.transition(ctx, task.TaskID, task.Status, true, notification, func(stored *models.Task) {
+		updated := cloneTask(task)
+		stored.ProjectID = updated.ProjectID
+		stored.AgentID = updated.AgentID
+		stored.CodebaseID = updated.CodebaseID
+		stored.Type = updated.Type
+		stored.Status = updated.Status
+		stored.Title = updated.Title
+		stored.Description = updated.Description
+		stored.Input = updated.Input
+		stored.Output = updated.Output
+		stored.ErrorMessage = updated.ErrorMessage
+		stored.UpdatedAt = updated.UpdatedAt
+		stored.CompletedAt = updated.CompletedAt
+		stored.Metadata = updated.Metadata
+		stored.Tags = updated.Tags
+		stored.Approved = updated.Approved
+	})
+}
+
+// Delete deletes a task by its ID
+func (r *MemoryTaskRepository) Delete(_ context.Context, taskID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.tasks[taskID]; !ok {
+		return apperrors.NotFound(apperrors.CodeTaskNotFound, "task not found: %s", taskID)
+	}
+	delete(r.tasks, taskID)
+	return nil
+}
+
+// ListByProject lists a page of the project's tasks matching filters, newest first, with the number of tasks
+// matching them
+func (r *MemoryTaskRepository) ListByProject(_ context.Context, projectID string, filters TaskFilters) ([]models.Task, int, error) {
+	tasks := r.matching(func(task *models.Task) bool {
+		return task.ProjectID == projectID && matchesTaskFilters(task, filters)
+	}, newestTaskFirst)
+	return page(tasks, filters.Offset, filters.Limit), len(tasks), nil
+}
+
+// StreamByProject passes every task of a project matching filters to fn, newest first. Pagination filters are ignored.
+func (r *MemoryTaskRepository) StreamByProject(_ context.Context, projectID string, filters TaskFilters, fn func(models.Task) error) error {
+	tasks := r.matching(func(task *models.Task) bool {
+		return task.ProjectID == projectID && matchesTaskFilters(task, filters)
+	}, newestTaskFirst)
+	for _, task := range tasks {
+		if err := fn(task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListByAgent lists tasks for a specific agent
+func (r *MemoryTaskRepository) ListByAgent(ctx context.Context, agentID string, filters TaskFilters) ([]models.Task, int, error) {
+	filters.AgentID = &agentID
+	return r.listWithFilters(ctx, filters)
+}
+
+// ListByCodebase lists tasks for a specific codebase
+func (r *MemoryTaskRepository) ListByCodebase(ctx context.Context, codebaseID string, filters TaskFilters) ([]models.Task, int, error) {
+	filters.CodebaseID = &codebaseID
+	return r.listWithFilters(ctx, filters)
+}
+
+// ListByBatch lists all tasks created as part of a batch, oldest first
+func (r *MemoryTaskRepository) ListByBatch(_ context.Context, batchID string) ([]models.Task, error) {
+	return r.matching(func(task *models.Task) bool {
+		return task.BatchID != nil && *task.BatchID == batchID
+	}, oldestTaskFirst), nil
+}
+
+// ListByCampaign lists the child tasks of a campaign, oldest first
+func (r *MemoryTaskRepository) ListByCampaign(_ context.Context, campaignID string) ([]models.Task, error) {
+	return r.matching(func(task *models.Task) bool {
+		return task.CampaignID != nil && *task.CampaignID == campaignID
+	}, oldestTaskFirst), nil
+}
+
+// ListByExperiment lists the tasks assigned to an experiment, oldest first
+func (r *MemoryTaskRepository) ListByExperiment(_ context.Context, experimentID string) ([]models.Task, error) {
+	return r.matching(func(task *models.Task) bool {
+		return task.ExperimentID != nil && *task.ExperimentID == experimentID
+	}, oldestTaskFirst), nil
+}
+
+// AssignExperiment records the experiment and arm a task was assigned to, unless it already was
+func (r *MemoryTaskRepository) AssignExperiment(_ context.Context, taskID, experimentID string, arm models.ExperimentArm) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if task, ok := r.tasks[taskID]; ok && task.ExperimentID == nil {
+		task.ExperimentID = &experimentID
+		task.ExperimentArm = &arm
+	}
+	return nil
+}
+
+// CountByProject counts the project's tasks created since the given time, grouped by status and type
+func (r *MemoryTaskRepository) CountByProject(_ context.Context, projectID string, since time.Time) ([]TaskCount, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var counts []TaskCount
+	for _, task := range r.tasks {
+		if task.ProjectID != projectID || task.CreatedAt.Before(since) {
+			continue
+		}
+		i := slices.IndexFunc(counts, func(count TaskCount) bool {
+			return count.Status == task.Status && count.Type == task.Type
+		})
+		if i < 0 {
+			counts = append(counts, TaskCount{Status: task.Status, Type: task.Type})
+			i = len(counts) - 1
+		}
+		counts[i].Count++
+	}
+	return counts, nil
+}
+
+// ListRecentFailures lists the project's most recently failed tasks updated since the given time
+func (r *MemoryTaskRepository) ListRecentFailures(_ context.Context, projectID string, since time.Time, limit int) ([]models.Task, error) {
+	tasks := r.matching(func(task *models.Task) bool {
+		return task.ProjectID == projectID && task.Status == models.TaskStatusFailed && !task.UpdatedAt.Before(since)
+	}, func(a, b models.Task) int {
+		return b.UpdatedAt.Compare(a.UpdatedAt)
+	})
+	return page(tasks, 0, limit), nil
+}
+
+// UpdateStatus moves a task to status, unless its status can't move to it, and records the notification the
+// transition raises. A task moved to queued goes to the back of the queue.
+func (r *MemoryTaskRepository) UpdateStatus(ctx context.Context, taskID string, status models.TaskStatus, notification *models.Notification) error {
+	now := time.Now()
+
+	return r.transition(ctx, taskID, status, false, notification, func(stored *models.Task) {
+		stored.Status = status
+		stored.UpdatedAt = now
+		stored.CompletedAt = nil
+		if status.IsTerminal() {
+			stored.CompletedAt = &now
+		}
+		if status == models.TaskStatusQueued {
+			stored.QueuedAt = &now
+		}
+	})
+}
+
+// UpdateStatusAndOutput moves a task to status with its output, like UpdateStatus, and records the notification the
+// transition raises. A completed task has made all its progress, while a failed one keeps the step it failed in.
+func (r *MemoryTaskRepository) UpdateStatusAndOutput(ctx context.Context, taskID string, status models.TaskStatus, output map[string]any, errorMessage *string, notification *models.Notification) error {
+	now := time.Now()
+
+	return r.transition(ctx, taskID, status, false, notification, func(stored *models.Task) {
+		stored.Status = status
+		stored.Output = cloneMap(output)
+		stored.ErrorMessage = clonePtr(errorMessage)
+		stored.UpdatedAt = now
+		stored.CompletedAt = nil
+		if status == models.TaskStatusCompleted || status == models.TaskStatusFailed {
+			stored.CompletedAt = &now
+		}
+		if status == models.TaskStatusCompleted {
+			stored.Progress = 100
+			stored.ProgressStep = nil
+		}
+	})
+}
+
+// NextQueued returns the queued task the dispatcher runs next, nil when no task is queued
+func (r *MemoryTaskRepository) NextQueued(_ context.Context) (*models.Task, error) {
+	queue := r.queue()
+	if len(queue) == 0 {
+		return nil, nil
+	}
+	return &queue[0], nil
+}
+
+// QueuePosition returns the position of a queued task in the order NextQueued runs them, 0 when it isn't queued
+func (r *MemoryTaskRepository) QueuePosition(_ context.Context, taskID string) (int, error) {
+	queue := r.queue()
+	return slices.IndexFunc(queue, func(task models.Task) bool { return task.TaskID == taskID }) + 1, nil
+}
+
+// Heartbeat records that the execution of an in_progress task is still running, and how far it got
+func (r *MemoryTaskRepository) Heartbeat(_ context.Context, taskID string, progress int, step string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	task, ok := r.tasks[taskID]
+	if !ok || task.Status != models.TaskStatusInProgress {
+		return nil
+	}
+	now := time.Now()
+	task.HeartbeatAt = &now
+	task.Progress = progress
+	task.ProgressStep = nil
+	if step != "" {
+		task.ProgressStep = &step
+	}
+	return nil
+}
+
+// ListStuck lists the in_progress tasks without a heartbeat since the given time, longest silent first
+func (r *MemoryTaskRepository) ListStuck(_ context.Context, heartbeatBefore time.Time, limit int) ([]models.Task, error) {
+	tasks := r.matching(func(task *models.Task) bool {
+		return stuck(task, heartbeatBefore)
+	}, func(a, b models.Task) int {
+		return lastSignOfLife(&a).Compare(lastSignOfLife(&b))
+	})
+	if tasks == nil {
+		tasks = []models.Task{}
+	}
+	return page(tasks, 0, limit), nil
+}
+
+// RecoverStuck moves a stuck task to status, and never a task whose execution is still heartbeating
+func (r *MemoryTaskRepository) RecoverStuck(ctx context.Context, taskID string, heartbeatBefore time.Time, status models.TaskStatus, errorMessage *string, notification *models.Notification) (bool, error) {
+	if !models.TaskStatusInProgress.CanTransitionTo(status) {
+		return false, apperrors.Conflict(apperrors.CodeInvalidTaskTransition, "stuck task %s can't move to %s", taskID, status)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	task, ok := r.tasks[taskID]
+	if !ok || !stuck(task, heartbeatBefore) {
+		return false, nil
+	}
+
+	now := time.Now()
+	err := r.change(ctx, task, notification, func(stored *models.Task) {
+		stored.Status = status
+		stored.ErrorMessage = clonePtr(errorMessage)
+		stored.UpdatedAt = now
+		stored.CompletedAt = nil
+		if status.IsTerminal() {
+			stored.CompletedAt = &now
+		}
+		stored.HeartbeatAt = nil
+		if status == models.TaskStatusQueued {
+			stored.QueuedAt = &now
+		}
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// transition applies a change moving a task to status when its current status can move to it, or already is status
+// when unchanged is allowed, like the conditional updates of the Postgres repository. A missing task is told from one
+// whose status can't move to status.
+func (r *MemoryTaskRepository) transition(ctx context.Context, taskID string, status models.TaskStatus, unchanged bool, notification *models.Notification, apply func(*models.Task)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	task, ok := r.tasks[taskID]
+	if !ok {
+		return apperrors.NotFound(apperrors.CodeTaskNotFound, "task not found: %s", taskID)
+	}
+	if !(unchanged && task.Status == status) && !task.Status.CanTransitionTo(status) {
+		return apperrors.Conflict(apperrors.CodeInvalidTaskTransition, "task %s is %s and can't move to %s", taskID, task.Status, status)
+	}
+	return r.change(ctx, task, notification, apply)
+}
+
+// change applies a change to a stored task and enqueues the notification it raises, undoing the change when the
+// notification can't be enqueued. The caller holds the lock.
+func (r *MemoryTaskRepository) change(ctx context.Context, task *models.Task, notification *models.Notification, apply func(*models.Task)) error {
+	before := cloneTask(task)
+	apply(task)
+	if notification == nil || r.outbox == nil {
+		return nil
+	}
+	if err := r.outbox.Enqueue(ctx, *notification); err != nil {
+		r.tasks[task.TaskID] = before
+		return err
+	}
+	return nil
+}
+
+// listWithFilters lists a page of the tasks matching filters, newest first, with the number of tasks matching them
+func (r *MemoryTaskRepository) listWithFilters(_ context.Context, filters TaskFilters) ([]models.Task, int, error) {
+	tasks := r.matching(func(task *models.Task) bool {
+		return matchesTaskFilters(task, filters)
+	}, newestTaskFirst)
+	return page(tasks, filters.Offset, filters.Limit), len(tasks), nil
+}
+
+// matching returns copies of the tasks matching match in the order of cmp
+func (r *MemoryTaskRepository) matching(match func(*models.Task) bool, cmp func(a, b models.Task) int) []models.Task {
+	r.mu.RLock()
+	var tasks []models.Task
+	for _, task := range r.tasks {
+		if match(task) {
+			tasks = append(tasks, *cloneTask(task))
+		}
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(tasks, func(a, b models.Task) int {
+		if c := cmp(a, b); c != 0 {
+			return c
+		}
+		return strings.Compare(a.TaskID, b.TaskID)
+	})
+	return tasks
+}
+
+// queue returns the queued tasks in the order the dispatcher runs them: higher priority first, then the tasks of
+// projects with fewer tasks in progress, then the longest queued
+func (r *MemoryTaskRepository) queue() []models.Task {
+	r.mu.RLock()
+	inProgress := map[string]int{}
+	for _, task := range r.tasks {
+		if task.Status == models.TaskStatusInProgress {
+			inProgress[task.ProjectID]++
+		}
+	}
+	r.mu.RUnlock()
+
+	return r.matching(func(task *models.Task) bool {
+		return task.Status == models.TaskStatusQueued
+	}, func(a, b models.Task) int {
+		if c := priorityRank(a.Priority) - priorityRank(b.Priority); c != 0 {
+			return c
+		}
+		if c := inProgress[a.ProjectID] - inProgress[b.ProjectID]; c != 0 {
+			return c
+		}
+		switch {
+		case a.QueuedAt == nil && b.QueuedAt == nil:
+			return 0
+		case a.QueuedAt == nil:
+			return 1
+		case b.QueuedAt == nil:
+			return -1
+		}
+		return a.QueuedAt.Compare(*b.QueuedAt)
+	})
+}
+
+// matchesTaskFilters reports whether a task matches the filters, leaving pagination aside
+func matchesTaskFilters(task *models.Task, filters TaskFilters) bool {
+	if filters.Status != nil && task.Status != *filters.Status {
+		return false
+	}
+	if filters.Type != nil && task.Type != *filters.Type {
+		return false
+	}
+	if filters.AgentID != nil && task.AgentID != *filters.AgentID {
+		return false
+	}
+	if filters.CodebaseID != nil && (task.CodebaseID == nil || *task.CodebaseID != *filters.CodebaseID) {
+		return false
+	}
+	if !hasTags(task.Tags, filters.Tags) {
+		return false
+	}
+	if filters.Owner != nil && !hasOwner(task.Output, *filters.Owner) {
+		return false
+	}
+	return true
+}
+
+// hasOwner reports whether owner is among the owners in a task's output
+func hasOwner(output map[string]any, owner string) bool {
+	switch owners := output["owners"].(type) {
+	case []string:
+		return slices.Contains(owners, owner)
+	case []any:
+		return slices.Contains(owners, any(owner))
+	}
+	return false
+}
+
+// priorityRank orders task priorities, highest first
+func priorityRank(priority models.TaskPriority) int {
+	switch priority {
+	case models.TaskPriorityHigh:
+		return 0
+	case models.TaskPriorityNormal:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// stuck reports whether a task is in_progress without a heartbeat since heartbeatBefore
+func stuck(task *models.Task, heartbeatBefore time.Time) bool {
+	return task.Status == models.TaskStatusInProgress && lastSignOfLife(task).Before(heartbeatBefore)
+}
+
+// lastSignOfLife returns the last heartbeat of a task, or when it was last updated if it never sent one
+func lastSignOfLife(task *models.Task) time.Time {
+	if task.HeartbeatAt != nil {
+		return *task.HeartbeatAt
+	}
+	return task.UpdatedAt
+}
+
+// newestTaskFirst orders tasks by creation, newest first
+func newestTaskFirst(a, b models.Task) int {
+	return b.CreatedAt.Compare(a.CreatedAt)
+}
+
+// oldestTaskFirst orders tasks by creation, oldest first
+func oldestTaskFirst(a, b models.Task) int {
+	return a.CreatedAt.Compare(b.CreatedAt)
+}
@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MemoryUserRepository implements UserRepository in memory. Emails, usernames and auth IDs are unique, like in the
+// Postgres table.
+type MemoryUserRepository struct {
+	mu    sync.RWMutex
+	users map[string]*models.DBUser
+}
+
+// NewMemoryUserRepository creates a new empty in-memory user repository
+func NewMemoryUserRepository() UserRepository {
+	return &MemoryUserRepository{
+		users: map[string]*models.DBUser{},
+	}
+}
+
+// cloneUser returns a copy of user sharing no pointers with it
+func cloneUser(user *models.DBUser) *models.DBUser {
+	clone := *user
+	clone.FirstName = clonePtr(user.FirstName)
+	clone.LastName = clonePtr(user.LastName)
+	return &clone
+}
+
+// CreateUser creates a new user, generating its ID when it has none
+func (r *MemoryUserRepository) CreateUser(_ context.Context, user *models.DBUser) (*models.DBUser, error) {
+	if user.UserID == "" {
+		user.UserID = generateUserID()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[user.UserID]; ok || r.taken(user) {
+		return nil, apperrors.Conflict(apperrors.CodeUserExists, "user with email '%s', username '%s', or auth_id '%s' already exists", user.Email, user.Username, user.AuthID)
+	}
+
+	now := time.Now().UTC()
+	user.CreatedAt = now
+	user.UpdatedAt = now
+	r.users[user.UserID] = cloneUser(user)
+	return user, nil
+}
+
+// GetUser retrieves a user by user ID, nil when it doesn't exist
+func (r *MemoryUserRepository) GetUser(_ context.Context, userID string) (*models.DBUser, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.users[userID]
+	if !ok {
+		return nil, nil
+	}
+	return cloneUser(user), nil
+}
+
+// GetUserByAuthID retrieves a user by auth provider ID
+func (r *MemoryUserRepository) GetUserByAuthID(_ context.Context, authID string) (*models.DBUser, error) {
+	if user := r.find(func(user *models.DBUser) bool { return user.AuthID == authID }); user != nil {
+		return user, nil
+	}
+	return nil, apperrors.NotFound(apperrors.CodeUserNotFound, "user with auth_id '%s' not found", authID)
+}
+
+// GetUserByEmail retrieves a user by email
+func (r *MemoryUserRepository) GetUserByEmail(_ context.Context, email string) (*models.DBUser, error) {
+	if user := r.find(func(user *models.DBUser) bool { return user.Email == email }); user != nil {
+		return user, nil
+	}
+	return nil, apperrors.NotFound(apperrors.CodeUserNotFound, "user with email '%s' not found", email)
+}
+
+// UpdateUser updates an existing user, keeping its creation time
+func (r *MemoryUserRepository) UpdateUser(_ context.Context, user *models.DBUser) (*models.DBUser, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.users[user.UserID]
+	if !ok {
+		return nil, apperrors.NotFound(apperrors.CodeUserNotFound, "user with ID '%s' does not exist", user.UserID)
+	}
+	if r.taken(user) {
+		return nil, apperrors.Conflict(apperrors.CodeUserExists, "user with email '%s', username '%s', or auth_id '%s' already exists", user.Email, user.Username, user.AuthID)
+	}
+
+	user.UpdatedAt = time.Now().UTC()
+	updated := cloneUser(user)
+	updated.CreatedAt = stored.CreatedAt
+	r.users[user.UserID] = updated
+	return user, nil
+}
+
+// DeleteUser deletes a user by user ID
+func (r *MemoryUserRepository) DeleteUser(_ context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[userID]; !ok {
+		return apperrors.NotFound(apperrors.CodeUserNotFound, "user with ID '%s' does not exist", userID)
+	}
+	delete(r.users, userID)
+	return nil
+}
+
+// ListUsers lists a page of the users matching filter, newest first, with the number of users matching it. Search
+// matches the email, username, first or last name containing it, ignoring case.
+func (r *MemoryUserRepository) ListUsers(_ context.Context, filter *ListUsersFilter) ([]*models.DBUser, int, error) {
+	if filter == nil {
+		filter = &ListUsersFilter{}
+	}
+	search := strings.ToLower(filter.Search)
+	contains := func(value *string) bool {
+		return value != nil && strings.Contains(strings.ToLower(*value), search)
+	}
+
+	r.mu.RLock()
+	var users []*models.DBUser
+	for _, user := range r.users {
+		if filter.Role != nil && user.Role != *filter.Role {
+			continue
+		}
+		if filter.Status != nil && user.Status != *filter.Status {
+			continue
+		}
+		if search != "" && !contains(&user.Email) && !contains(&user.Username) && !contains(user.FirstName) && !contains(user.LastName) {
+			continue
+		}
+		users = append(users, cloneUser(user))
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(users, func(a, b *models.DBUser) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.UserID, b.UserID)
+	})
+	return page(users, filter.Offset, filter.Limit), len(users), nil
+}
+
+// UserExists checks if a user exists by user ID
+func (r *MemoryUserRepository) UserExists(_ context.Context, userID string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.users[userID]
+	return ok, nil
+}
+
+// StreamUsers passes the users created within timeRange to fn, oldest first
+func (r *MemoryUserRepository) StreamUsers(_ context.Context, timeRange TimeRange, fn func(*models.DBUser) error) error {
+	r.mu.RLock()
+	var users []*models.DBUser
+	for _, user := range r.users {
+		if timeRange.Contains(user.CreatedAt) {
+			users = append(users, cloneUser(user))
+		}
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(users, func(a, b *models.DBUser) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.UserID, b.UserID)
+	})
+	for _, user := range users {
+		if err := fn(user); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// find returns a copy of the first user matching match, nil when none does
+func (r *MemoryUserRepository) find(match func(*models.DBUser) bool) *models.DBUser {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, user := range r.users {
+		if match(user) {
+			return cloneUser(user)
+		}
+	}
+	return nil
+}
+
+// taken reports whether another user already has the email, username or auth ID of user. The caller holds the lock.
+func (r *MemoryUserRepository) taken(user *models.DBUser) bool {
+	for _, other := range r.users {
+		if other.UserID == user.UserID {
+			continue
+		}
+		if other.Email == user.Email || other.Username == user.Username || other.AuthID == user.AuthID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MemoryWorkspaceRepository implements WorkspaceRepository in memory
+type MemoryWorkspaceRepository struct {
+	mu         sync.Mutex
+	workspaces map[string]*models.Workspace
+}
+
+// NewMemoryWorkspaceRepository creates a new empty in-memory workspace repository
+func NewMemoryWorkspaceRepository() WorkspaceRepository {
+	return &MemoryWorkspaceRepository{
+		workspaces: map[string]*models.Workspace{},
+	}
+}
+
+// CreateWorkspace records a workspace
+func (r *MemoryWorkspaceRepository) CreateWorkspace(_ context.Context, workspace *models.Workspace) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.workspaces[workspace.WorkspaceID]; ok {
+		return fmt.Errorf("failed to create workspace: workspace %s already exists", workspace.WorkspaceID)
+	}
+	r.workspaces[workspace.WorkspaceID] = clonePtr(workspace)
+	return nil
+}
+
+// ClaimIdleWorkspace claims the most recently used matching idle workspace, so two tasks never share a workspace
+func (r *MemoryWorkspaceRepository) ClaimIdleWorkspace(_ context.Context, host, codebaseID, commitSHA, taskID string) (*models.Workspace, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var claimed *models.Workspace
+	for _, workspace := range r.workspaces {
+		if workspace.Host != host || workspace.CodebaseID != codebaseID || workspace.CommitSHA != commitSHA || workspace.Status != models.WorkspaceStatusIdle {
+			continue
+		}
+		if claimed == nil || workspace.LastUsedAt.After(claimed.LastUsedAt) {
+			claimed = workspace
+		}
+	}
+	if claimed == nil {
+		return nil, nil
+	}
+
+	claimed.Status = models.WorkspaceStatusInUse
+	claimed.TaskID = taskID
+	claimed.LastUsedAt = time.Now()
+	return clonePtr(claimed), nil
+}
+
+// ReleaseWorkspace marks a workspace idle and records its size
+func (r *MemoryWorkspaceRepository) ReleaseWorkspace(_ context.Context, workspaceID string, sizeBytes int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if workspace, ok := r.workspaces[workspaceID]; ok {
+		workspace.Status = models.WorkspaceStatusIdle
+		workspace.SizeBytes = sizeBytes
+		workspace.LastUsedAt = time.Now()
+	}
+	return nil
+}
+
+// DeleteWorkspace deletes a workspace
+func (r *MemoryWorkspaceRepository) DeleteWorkspace(_ context.Context, workspaceID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.workspaces, workspaceID)
+	return nil
+}
+
+// ListWorkspaces lists the workspaces of a host from least to most recently used
+func (r *MemoryWorkspaceRepository) ListWorkspaces(_ context.Context, host string) ([]models.Workspace, error) {
+	r.mu.Lock()
+	workspaces := []models.Workspace{}
+	for _, workspace := range r.workspaces {
+		if workspace.Host == host {
+			workspaces = append(workspaces, *workspace)
+		}
+	}
+	r.mu.Unlock()
+
+	slices.SortFunc(workspaces, func(a, b models.Workspace) int {
+		return cmp.Or(a.LastUsedAt.Compare(b.LastUsedAt), cmp.Compare(a.WorkspaceID, b.WorkspaceID))
+	})
+	return workspaces, nil
+}
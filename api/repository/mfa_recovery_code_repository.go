@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+)
+
+// MFARecoveryCodeRepository defines the interface for the storage of the hashed MFA recovery codes of users
+//
+//go:generate mockgen -destination=./mocks/mock_mfa_recovery_code_repository.go -mock_names=MFARecoveryCodeRepository=MockMFARecoveryCodeRepository -package=mocks . MFARecoveryCodeRepository
+type MFARecoveryCodeRepository interface {
+	// ReplaceRecoveryCodes replaces the recovery codes of a user with the given hashes, or deletes them when there are none
+	ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error
+
+	// ConsumeRecoveryCode marks an unused recovery code of a user used, reporting false if the user has no such code
+	ConsumeRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error)
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Migration is a numbered change of a database schema, applied once
+type Migration struct {
+	Version     int
+	Description string
+	SQL         string
+}
+
+// Migrate applies the migrations the database hasn't applied yet, in the order of their versions, recording each in
+// the schema_migrations table in the same transaction as its change. The migrations must be sorted by version.
+func Migrate(ctx context.Context, db *sql.DB, migrations []Migration) error {
+	if _, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			description TEXT NOT NULL,
+			applied_at BIGINT NOT NULL
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	var applied int
+	if err := db.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&applied); err != nil {
+		return fmt.Errorf("failed to get schema version: %w", err)
+	}
+
+	for _, migration := range migrations {
+		if migration.Version <= applied {
+			continue
+		}
+		if err := applyMigration(ctx, db, migration); err != nil {
+			return fmt.Errorf("failed to apply migration %d (%s): %w", migration.Version, migration.Description, err)
+		}
+	}
+	return nil
+}
+
+// applyMigration applies a migration and records it in one transaction. The version is inlined rather than bound, so
+// the statement is the same in every SQL dialect.
+func applyMigration(ctx context.Context, db *sql.DB, migration Migration) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, migration.SQL); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(
+		"INSERT INTO schema_migrations (version, description, applied_at) VALUES (%d, '%s', %d)",
+		migration.Version, strings.ReplaceAll(migration.Description, "'", "''"), time.Now().Unix(),
+	)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
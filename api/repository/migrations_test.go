@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate_AppliesPendingMigrationsOnce(t *testing.T) {
+	ctx := context.Background()
+	db, err := OpenSQLite(ctx, ":memory:")
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	migrations := append(sqliteMigrations, Migration{
+		Version:     len(sqliteMigrations) + 1,
+		Description: "add the project's owner",
+		SQL:         "ALTER TABLE projects ADD COLUMN owner TEXT",
+	})
+	require.NoError(t, Migrate(ctx, db, migrations))
+	require.NoError(t, Migrate(ctx, db, migrations))
+
+	var applied int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM schema_migrations").Scan(&applied))
+	assert.Equal(t, len(migrations), applied)
+}
+
+func TestMigrate_RollsBackFailedMigration(t *testing.T) {
+	ctx := context.Background()
+	db, err := OpenSQLite(ctx, ":memory:")
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	err = Migrate(ctx, db, append(sqliteMigrations, Migration{
+		Version:     len(sqliteMigrations) + 1,
+		Description: "broken",
+		SQL:         "CREATE TABLE owners (owner_id TEXT); ALTER TABLE missing ADD COLUMN owner TEXT",
+	}))
+
+	require.Error(t, err)
+	var tables int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE name = 'owners'").Scan(&tables))
+	assert.Zero(t, tables)
+}
+
+func TestMigrate_GrantsAdminRolePermissionsOfAdminRoutes(t *testing.T) {
+	ctx := context.Background()
+	// Opened without OpenSQLite, which applies every migration
+	db, err := sql.Open("sqlite", "file::memory:?_pragma=foreign_keys(1)")
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+	db.SetMaxOpenConns(1)
+
+	// The admin role of a deployment seeded before the admin routes were authorized by permissions
+	require.NoError(t, Migrate(ctx, db, sqliteMigrations[:2]))
+	_, err = db.ExecContext(ctx, "INSERT INTO roles (name, built_in, created_at, updated_at) VALUES ('admin', 1, 0, 0), ('developer', 1, 0, 0)")
+	require.NoError(t, err)
+
+	require.NoError(t, Migrate(ctx, db, sqliteMigrations))
+
+	var adminGrants, developerGrants int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM role_permissions WHERE role = 'admin'").Scan(&adminGrants))
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM role_permissions WHERE role = 'developer'").Scan(&developerGrants))
+	assert.Equal(t, 6, adminGrants)
+	assert.Zero(t, developerGrants)
+}
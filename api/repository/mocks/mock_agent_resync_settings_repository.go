@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/repository (interfaces: AgentResyncSettingsRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockAgentResyncSettingsRepository is a mock of AgentResyncSettingsRepository interface.
+type MockAgentResyncSettingsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAgentResyncSettingsRepositoryMockRecorder
+}
+
+// MockAgentResyncSettingsRepositoryMockRecorder is the mock recorder for MockAgentResyncSettingsRepository.
+type MockAgentResyncSettingsRepositoryMockRecorder struct {
+	mock *MockAgentResyncSettingsRepository
+}
+
+// NewMockAgentResyncSettingsRepository creates a new mock instance.
+func NewMockAgentResyncSettingsRepository(ctrl *gomock.Controller) *MockAgentResyncSettingsRepository {
+	mock := &MockAgentResyncSettingsRepository{ctrl: ctrl}
+	mock.recorder = &MockAgentResyncSettingsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAgentResyncSettingsRepository) EXPECT() *MockAgentResyncSettingsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetSettings mocks base method.
+func (m *MockAgentResyncSettingsRepository) GetSettings(arg0 context.Context, arg1 string) (*models.AgentResyncSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSettings", arg0, arg1)
+	ret0, _ := ret[0].(*models.AgentResyncSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSettings indicates an expected call of GetSettings.
+func (mr *MockAgentResyncSettingsRepositoryMockRecorder) GetSettings(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSettings", reflect.TypeOf((*MockAgentResyncSettingsRepository)(nil).GetSettings), arg0, arg1)
+}
+
+// SaveSettings mocks base method.
+func (m *MockAgentResyncSettingsRepository) SaveSettings(arg0 context.Context, arg1 *models.AgentResyncSettings) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSettings", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveSettings indicates an expected call of SaveSettings.
+func (mr *MockAgentResyncSettingsRepositoryMockRecorder) SaveSettings(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSettings", reflect.TypeOf((*MockAgentResyncSettingsRepository)(nil).SaveSettings), arg0, arg1)
+}
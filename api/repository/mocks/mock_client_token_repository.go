@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/repository (interfaces: ClientTokenRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockClientTokenRepository is a mock of ClientTokenRepository interface.
+type MockClientTokenRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockClientTokenRepositoryMockRecorder
+}
+
+// MockClientTokenRepositoryMockRecorder is the mock recorder for MockClientTokenRepository.
+type MockClientTokenRepositoryMockRecorder struct {
+	mock *MockClientTokenRepository
+}
+
+// NewMockClientTokenRepository creates a new mock instance.
+func NewMockClientTokenRepository(ctrl *gomock.Controller) *MockClientTokenRepository {
+	mock := &MockClientTokenRepository{ctrl: ctrl}
+	mock.recorder = &MockClientTokenRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClientTokenRepository) EXPECT() *MockClientTokenRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimToken mocks base method.
+func (m *MockClientTokenRepository) ClaimToken(arg0 context.Context, arg1 *models.ClientToken) (*models.ClientToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimToken", arg0, arg1)
+	ret0, _ := ret[0].(*models.ClientToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimToken indicates an expected call of ClaimToken.
+func (mr *MockClientTokenRepositoryMockRecorder) ClaimToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimToken", reflect.TypeOf((*MockClientTokenRepository)(nil).ClaimToken), arg0, arg1)
+}
+
+// CompleteToken mocks base method.
+func (m *MockClientTokenRepository) CompleteToken(arg0 context.Context, arg1, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteToken", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteToken indicates an expected call of CompleteToken.
+func (mr *MockClientTokenRepositoryMockRecorder) CompleteToken(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteToken", reflect.TypeOf((*MockClientTokenRepository)(nil).CompleteToken), arg0, arg1, arg2, arg3)
+}
+
+// ReleaseToken mocks base method.
+func (m *MockClientTokenRepository) ReleaseToken(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseToken", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseToken indicates an expected call of ReleaseToken.
+func (mr *MockClientTokenRepositoryMockRecorder) ReleaseToken(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseToken", reflect.TypeOf((*MockClientTokenRepository)(nil).ReleaseToken), arg0, arg1, arg2)
+}
@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/repository (interfaces: CodeMetricsRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockCodeMetricsRepository is a mock of CodeMetricsRepository interface.
+type MockCodeMetricsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCodeMetricsRepositoryMockRecorder
+}
+
+// MockCodeMetricsRepositoryMockRecorder is the mock recorder for MockCodeMetricsRepository.
+type MockCodeMetricsRepositoryMockRecorder struct {
+	mock *MockCodeMetricsRepository
+}
+
+// NewMockCodeMetricsRepository creates a new mock instance.
+func NewMockCodeMetricsRepository(ctrl *gomock.Controller) *MockCodeMetricsRepository {
+	mock := &MockCodeMetricsRepository{ctrl: ctrl}
+	mock.recorder = &MockCodeMetricsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCodeMetricsRepository) EXPECT() *MockCodeMetricsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ListSnapshots mocks base method.
+func (m *MockCodeMetricsRepository) ListSnapshots(arg0 context.Context, arg1 string, arg2 time.Time) ([]models.CodeMetricsSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSnapshots", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.CodeMetricsSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSnapshots indicates an expected call of ListSnapshots.
+func (mr *MockCodeMetricsRepositoryMockRecorder) ListSnapshots(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSnapshots", reflect.TypeOf((*MockCodeMetricsRepository)(nil).ListSnapshots), arg0, arg1, arg2)
+}
+
+// SaveSnapshot mocks base method.
+func (m *MockCodeMetricsRepository) SaveSnapshot(arg0 context.Context, arg1 *models.CodeMetricsSnapshot) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSnapshot", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveSnapshot indicates an expected call of SaveSnapshot.
+func (mr *MockCodeMetricsRepositoryMockRecorder) SaveSnapshot(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSnapshot", reflect.TypeOf((*MockCodeMetricsRepository)(nil).SaveSnapshot), arg0, arg1)
+}
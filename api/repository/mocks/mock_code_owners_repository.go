@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/repository (interfaces: CodeOwnersRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockCodeOwnersRepository is a mock of CodeOwnersRepository interface.
+type MockCodeOwnersRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCodeOwnersRepositoryMockRecorder
+}
+
+// MockCodeOwnersRepositoryMockRecorder is the mock recorder for MockCodeOwnersRepository.
+type MockCodeOwnersRepositoryMockRecorder struct {
+	mock *MockCodeOwnersRepository
+}
+
+// NewMockCodeOwnersRepository creates a new mock instance.
+func NewMockCodeOwnersRepository(ctrl *gomock.Controller) *MockCodeOwnersRepository {
+	mock := &MockCodeOwnersRepository{ctrl: ctrl}
+	mock.recorder = &MockCodeOwnersRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCodeOwnersRepository) EXPECT() *MockCodeOwnersRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetCodeOwners mocks base method.
+func (m *MockCodeOwnersRepository) GetCodeOwners(arg0 context.Context, arg1 string) (*models.CodeOwners, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCodeOwners", arg0, arg1)
+	ret0, _ := ret[0].(*models.CodeOwners)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCodeOwners indicates an expected call of GetCodeOwners.
+func (mr *MockCodeOwnersRepositoryMockRecorder) GetCodeOwners(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCodeOwners", reflect.TypeOf((*MockCodeOwnersRepository)(nil).GetCodeOwners), arg0, arg1)
+}
+
+// SaveCodeOwners mocks base method.
+func (m *MockCodeOwnersRepository) SaveCodeOwners(arg0 context.Context, arg1 *models.CodeOwners) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveCodeOwners", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveCodeOwners indicates an expected call of SaveCodeOwners.
+func (mr *MockCodeOwnersRepositoryMockRecorder) SaveCodeOwners(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCodeOwners", reflect.TypeOf((*MockCodeOwnersRepository)(nil).SaveCodeOwners), arg0, arg1)
+}
@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/repository (interfaces: CodebaseHeadRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	repository "github.com/kazemisoroush/code-refactoring-tool/api/repository"
+)
+
+// MockCodebaseHeadRepository is a mock of CodebaseHeadRepository interface.
+type MockCodebaseHeadRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCodebaseHeadRepositoryMockRecorder
+}
+
+// MockCodebaseHeadRepositoryMockRecorder is the mock recorder for MockCodebaseHeadRepository.
+type MockCodebaseHeadRepositoryMockRecorder struct {
+	mock *MockCodebaseHeadRepository
+}
+
+// NewMockCodebaseHeadRepository creates a new mock instance.
+func NewMockCodebaseHeadRepository(ctrl *gomock.Controller) *MockCodebaseHeadRepository {
+	mock := &MockCodebaseHeadRepository{ctrl: ctrl}
+	mock.recorder = &MockCodebaseHeadRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCodebaseHeadRepository) EXPECT() *MockCodebaseHeadRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetHead mocks base method.
+func (m *MockCodebaseHeadRepository) GetHead(arg0 context.Context, arg1 string) (*repository.CodebaseHead, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHead", arg0, arg1)
+	ret0, _ := ret[0].(*repository.CodebaseHead)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHead indicates an expected call of GetHead.
+func (mr *MockCodebaseHeadRepositoryMockRecorder) GetHead(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHead", reflect.TypeOf((*MockCodebaseHeadRepository)(nil).GetHead), arg0, arg1)
+}
+
+// SaveHead mocks base method.
+func (m *MockCodebaseHeadRepository) SaveHead(arg0 context.Context, arg1 *repository.CodebaseHead) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveHead", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveHead indicates an expected call of SaveHead.
+func (mr *MockCodebaseHeadRepositoryMockRecorder) SaveHead(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveHead", reflect.TypeOf((*MockCodebaseHeadRepository)(nil).SaveHead), arg0, arg1)
+}
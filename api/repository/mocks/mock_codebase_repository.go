@@ -51,6 +51,21 @@ func (mr *MockCodebaseRepositoryMockRecorder) CodebaseExists(arg0, arg1 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CodebaseExists", reflect.TypeOf((*MockCodebaseRepository)(nil).CodebaseExists), arg0, arg1)
 }
 
+// CountByProject mocks base method.
+func (m *MockCodebaseRepository) CountByProject(arg0 context.Context, arg1 string) ([]repository.CodebaseStatusCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByProject", arg0, arg1)
+	ret0, _ := ret[0].([]repository.CodebaseStatusCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByProject indicates an expected call of CountByProject.
+func (mr *MockCodebaseRepositoryMockRecorder) CountByProject(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByProject", reflect.TypeOf((*MockCodebaseRepository)(nil).CountByProject), arg0, arg1)
+}
+
 // CreateCodebase mocks base method.
 func (m *MockCodebaseRepository) CreateCodebase(arg0 context.Context, arg1 *models.Codebase) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/repository (interfaces: DependencyFindingRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockDependencyFindingRepository is a mock of DependencyFindingRepository interface.
+type MockDependencyFindingRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDependencyFindingRepositoryMockRecorder
+}
+
+// MockDependencyFindingRepositoryMockRecorder is the mock recorder for MockDependencyFindingRepository.
+type MockDependencyFindingRepositoryMockRecorder struct {
+	mock *MockDependencyFindingRepository
+}
+
+// NewMockDependencyFindingRepository creates a new mock instance.
+func NewMockDependencyFindingRepository(ctrl *gomock.Controller) *MockDependencyFindingRepository {
+	mock := &MockDependencyFindingRepository{ctrl: ctrl}
+	mock.recorder = &MockDependencyFindingRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDependencyFindingRepository) EXPECT() *MockDependencyFindingRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ListFindings mocks base method.
+func (m *MockDependencyFindingRepository) ListFindings(arg0 context.Context, arg1 string) ([]models.DependencyFinding, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFindings", arg0, arg1)
+	ret0, _ := ret[0].([]models.DependencyFinding)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFindings indicates an expected call of ListFindings.
+func (mr *MockDependencyFindingRepositoryMockRecorder) ListFindings(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFindings", reflect.TypeOf((*MockDependencyFindingRepository)(nil).ListFindings), arg0, arg1)
+}
+
+// ReplaceFindings mocks base method.
+func (m *MockDependencyFindingRepository) ReplaceFindings(arg0 context.Context, arg1 string, arg2 []models.DependencyFinding) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceFindings", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceFindings indicates an expected call of ReplaceFindings.
+func (mr *MockDependencyFindingRepositoryMockRecorder) ReplaceFindings(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceFindings", reflect.TypeOf((*MockDependencyFindingRepository)(nil).ReplaceFindings), arg0, arg1, arg2)
+}
@@ -0,0 +1,141 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/repository (interfaces: DeviceAuthorizationRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockDeviceAuthorizationRepository is a mock of DeviceAuthorizationRepository interface.
+type MockDeviceAuthorizationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeviceAuthorizationRepositoryMockRecorder
+}
+
+// MockDeviceAuthorizationRepositoryMockRecorder is the mock recorder for MockDeviceAuthorizationRepository.
+type MockDeviceAuthorizationRepositoryMockRecorder struct {
+	mock *MockDeviceAuthorizationRepository
+}
+
+// NewMockDeviceAuthorizationRepository creates a new mock instance.
+func NewMockDeviceAuthorizationRepository(ctrl *gomock.Controller) *MockDeviceAuthorizationRepository {
+	mock := &MockDeviceAuthorizationRepository{ctrl: ctrl}
+	mock.recorder = &MockDeviceAuthorizationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeviceAuthorizationRepository) EXPECT() *MockDeviceAuthorizationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ApproveDeviceAuthorization mocks base method.
+func (m *MockDeviceAuthorizationRepository) ApproveDeviceAuthorization(arg0 context.Context, arg1, arg2, arg3 string, arg4 time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApproveDeviceAuthorization", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApproveDeviceAuthorization indicates an expected call of ApproveDeviceAuthorization.
+func (mr *MockDeviceAuthorizationRepositoryMockRecorder) ApproveDeviceAuthorization(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveDeviceAuthorization", reflect.TypeOf((*MockDeviceAuthorizationRepository)(nil).ApproveDeviceAuthorization), arg0, arg1, arg2, arg3, arg4)
+}
+
+// ConsumeDeviceAuthorization mocks base method.
+func (m *MockDeviceAuthorizationRepository) ConsumeDeviceAuthorization(arg0 context.Context, arg1 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeDeviceAuthorization", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeDeviceAuthorization indicates an expected call of ConsumeDeviceAuthorization.
+func (mr *MockDeviceAuthorizationRepositoryMockRecorder) ConsumeDeviceAuthorization(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeDeviceAuthorization", reflect.TypeOf((*MockDeviceAuthorizationRepository)(nil).ConsumeDeviceAuthorization), arg0, arg1)
+}
+
+// CreateDeviceAuthorization mocks base method.
+func (m *MockDeviceAuthorizationRepository) CreateDeviceAuthorization(arg0 context.Context, arg1 *models.DeviceAuthorization) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDeviceAuthorization", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateDeviceAuthorization indicates an expected call of CreateDeviceAuthorization.
+func (mr *MockDeviceAuthorizationRepositoryMockRecorder) CreateDeviceAuthorization(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDeviceAuthorization", reflect.TypeOf((*MockDeviceAuthorizationRepository)(nil).CreateDeviceAuthorization), arg0, arg1)
+}
+
+// DeleteExpiredDeviceAuthorizations mocks base method.
+func (m *MockDeviceAuthorizationRepository) DeleteExpiredDeviceAuthorizations(arg0 context.Context, arg1 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredDeviceAuthorizations", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredDeviceAuthorizations indicates an expected call of DeleteExpiredDeviceAuthorizations.
+func (mr *MockDeviceAuthorizationRepositoryMockRecorder) DeleteExpiredDeviceAuthorizations(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredDeviceAuthorizations", reflect.TypeOf((*MockDeviceAuthorizationRepository)(nil).DeleteExpiredDeviceAuthorizations), arg0, arg1)
+}
+
+// DenyDeviceAuthorization mocks base method.
+func (m *MockDeviceAuthorizationRepository) DenyDeviceAuthorization(arg0 context.Context, arg1 string, arg2 time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DenyDeviceAuthorization", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DenyDeviceAuthorization indicates an expected call of DenyDeviceAuthorization.
+func (mr *MockDeviceAuthorizationRepositoryMockRecorder) DenyDeviceAuthorization(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DenyDeviceAuthorization", reflect.TypeOf((*MockDeviceAuthorizationRepository)(nil).DenyDeviceAuthorization), arg0, arg1, arg2)
+}
+
+// GetPendingDeviceAuthorization mocks base method.
+func (m *MockDeviceAuthorizationRepository) GetPendingDeviceAuthorization(arg0 context.Context, arg1 string, arg2 time.Time) (*models.DeviceAuthorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingDeviceAuthorization", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.DeviceAuthorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingDeviceAuthorization indicates an expected call of GetPendingDeviceAuthorization.
+func (mr *MockDeviceAuthorizationRepositoryMockRecorder) GetPendingDeviceAuthorization(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingDeviceAuthorization", reflect.TypeOf((*MockDeviceAuthorizationRepository)(nil).GetPendingDeviceAuthorization), arg0, arg1, arg2)
+}
+
+// PollDeviceAuthorization mocks base method.
+func (m *MockDeviceAuthorizationRepository) PollDeviceAuthorization(arg0 context.Context, arg1 string, arg2 time.Time) (*models.DeviceAuthorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PollDeviceAuthorization", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.DeviceAuthorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PollDeviceAuthorization indicates an expected call of PollDeviceAuthorization.
+func (mr *MockDeviceAuthorizationRepositoryMockRecorder) PollDeviceAuthorization(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PollDeviceAuthorization", reflect.TypeOf((*MockDeviceAuthorizationRepository)(nil).PollDeviceAuthorization), arg0, arg1, arg2)
+}
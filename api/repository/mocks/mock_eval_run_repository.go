@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/repository (interfaces: EvalRunRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockEvalRunRepository is a mock of EvalRunRepository interface.
+type MockEvalRunRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockEvalRunRepositoryMockRecorder
+}
+
+// MockEvalRunRepositoryMockRecorder is the mock recorder for MockEvalRunRepository.
+type MockEvalRunRepositoryMockRecorder struct {
+	mock *MockEvalRunRepository
+}
+
+// NewMockEvalRunRepository creates a new mock instance.
+func NewMockEvalRunRepository(ctrl *gomock.Controller) *MockEvalRunRepository {
+	mock := &MockEvalRunRepository{ctrl: ctrl}
+	mock.recorder = &MockEvalRunRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEvalRunRepository) EXPECT() *MockEvalRunRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateRun mocks base method.
+func (m *MockEvalRunRepository) CreateRun(arg0 context.Context, arg1 *models.EvalRun) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRun", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRun indicates an expected call of CreateRun.
+func (mr *MockEvalRunRepositoryMockRecorder) CreateRun(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRun", reflect.TypeOf((*MockEvalRunRepository)(nil).CreateRun), arg0, arg1)
+}
+
+// GetRun mocks base method.
+func (m *MockEvalRunRepository) GetRun(arg0 context.Context, arg1 string) (*models.EvalRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRun", arg0, arg1)
+	ret0, _ := ret[0].(*models.EvalRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRun indicates an expected call of GetRun.
+func (mr *MockEvalRunRepositoryMockRecorder) GetRun(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRun", reflect.TypeOf((*MockEvalRunRepository)(nil).GetRun), arg0, arg1)
+}
+
+// ListRuns mocks base method.
+func (m *MockEvalRunRepository) ListRuns(arg0 context.Context, arg1 int) ([]models.EvalRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRuns", arg0, arg1)
+	ret0, _ := ret[0].([]models.EvalRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRuns indicates an expected call of ListRuns.
+func (mr *MockEvalRunRepositoryMockRecorder) ListRuns(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRuns", reflect.TypeOf((*MockEvalRunRepository)(nil).ListRuns), arg0, arg1)
+}
+
+// UpdateRun mocks base method.
+func (m *MockEvalRunRepository) UpdateRun(arg0 context.Context, arg1 *models.EvalRun) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRun", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRun indicates an expected call of UpdateRun.
+func (mr *MockEvalRunRepositoryMockRecorder) UpdateRun(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRun", reflect.TypeOf((*MockEvalRunRepository)(nil).UpdateRun), arg0, arg1)
+}
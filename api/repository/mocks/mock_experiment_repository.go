@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/repository (interfaces: ExperimentRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockExperimentRepository is a mock of ExperimentRepository interface.
+type MockExperimentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockExperimentRepositoryMockRecorder
+}
+
+// MockExperimentRepositoryMockRecorder is the mock recorder for MockExperimentRepository.
+type MockExperimentRepositoryMockRecorder struct {
+	mock *MockExperimentRepository
+}
+
+// NewMockExperimentRepository creates a new mock instance.
+func NewMockExperimentRepository(ctrl *gomock.Controller) *MockExperimentRepository {
+	mock := &MockExperimentRepository{ctrl: ctrl}
+	mock.recorder = &MockExperimentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExperimentRepository) EXPECT() *MockExperimentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateExperiment mocks base method.
+func (m *MockExperimentRepository) CreateExperiment(arg0 context.Context, arg1 *models.Experiment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateExperiment", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateExperiment indicates an expected call of CreateExperiment.
+func (mr *MockExperimentRepositoryMockRecorder) CreateExperiment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExperiment", reflect.TypeOf((*MockExperimentRepository)(nil).CreateExperiment), arg0, arg1)
+}
+
+// GetExperiment mocks base method.
+func (m *MockExperimentRepository) GetExperiment(arg0 context.Context, arg1 string) (*models.Experiment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExperiment", arg0, arg1)
+	ret0, _ := ret[0].(*models.Experiment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExperiment indicates an expected call of GetExperiment.
+func (mr *MockExperimentRepositoryMockRecorder) GetExperiment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExperiment", reflect.TypeOf((*MockExperimentRepository)(nil).GetExperiment), arg0, arg1)
+}
+
+// ListExperiments mocks base method.
+func (m *MockExperimentRepository) ListExperiments(arg0 context.Context, arg1 models.ExperimentStatus) ([]models.Experiment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExperiments", arg0, arg1)
+	ret0, _ := ret[0].([]models.Experiment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExperiments indicates an expected call of ListExperiments.
+func (mr *MockExperimentRepositoryMockRecorder) ListExperiments(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExperiments", reflect.TypeOf((*MockExperimentRepository)(nil).ListExperiments), arg0, arg1)
+}
+
+// StopExperiment mocks base method.
+func (m *MockExperimentRepository) StopExperiment(arg0 context.Context, arg1 string, arg2 time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StopExperiment", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StopExperiment indicates an expected call of StopExperiment.
+func (mr *MockExperimentRepositoryMockRecorder) StopExperiment(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopExperiment", reflect.TypeOf((*MockExperimentRepository)(nil).StopExperiment), arg0, arg1, arg2)
+}
@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/repository (interfaces: FileHistoryRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+	repository "github.com/kazemisoroush/code-refactoring-tool/api/repository"
+)
+
+// MockFileHistoryRepository is a mock of FileHistoryRepository interface.
+type MockFileHistoryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFileHistoryRepositoryMockRecorder
+}
+
+// MockFileHistoryRepositoryMockRecorder is the mock recorder for MockFileHistoryRepository.
+type MockFileHistoryRepositoryMockRecorder struct {
+	mock *MockFileHistoryRepository
+}
+
+// NewMockFileHistoryRepository creates a new mock instance.
+func NewMockFileHistoryRepository(ctrl *gomock.Controller) *MockFileHistoryRepository {
+	mock := &MockFileHistoryRepository{ctrl: ctrl}
+	mock.recorder = &MockFileHistoryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFileHistoryRepository) EXPECT() *MockFileHistoryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ListHistory mocks base method.
+func (m *MockFileHistoryRepository) ListHistory(arg0 context.Context, arg1 string, arg2 repository.FileHistoryFilter) ([]models.FileHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListHistory", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.FileHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListHistory indicates an expected call of ListHistory.
+func (mr *MockFileHistoryRepositoryMockRecorder) ListHistory(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHistory", reflect.TypeOf((*MockFileHistoryRepository)(nil).ListHistory), arg0, arg1, arg2)
+}
+
+// ReplaceHistory mocks base method.
+func (m *MockFileHistoryRepository) ReplaceHistory(arg0 context.Context, arg1, arg2 string, arg3 []models.FileHistory) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceHistory", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceHistory indicates an expected call of ReplaceHistory.
+func (mr *MockFileHistoryRepositoryMockRecorder) ReplaceHistory(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceHistory", reflect.TypeOf((*MockFileHistoryRepository)(nil).ReplaceHistory), arg0, arg1, arg2, arg3)
+}
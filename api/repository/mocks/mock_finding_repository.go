@@ -0,0 +1,167 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/repository (interfaces: FindingRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+	repository "github.com/kazemisoroush/code-refactoring-tool/api/repository"
+)
+
+// MockFindingRepository is a mock of FindingRepository interface.
+type MockFindingRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFindingRepositoryMockRecorder
+}
+
+// MockFindingRepositoryMockRecorder is the mock recorder for MockFindingRepository.
+type MockFindingRepositoryMockRecorder struct {
+	mock *MockFindingRepository
+}
+
+// NewMockFindingRepository creates a new mock instance.
+func NewMockFindingRepository(ctrl *gomock.Controller) *MockFindingRepository {
+	mock := &MockFindingRepository{ctrl: ctrl}
+	mock.recorder = &MockFindingRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFindingRepository) EXPECT() *MockFindingRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountFindings mocks base method.
+func (m *MockFindingRepository) CountFindings(arg0 context.Context, arg1 string, arg2 *bool) (map[models.FindingStatus]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountFindings", arg0, arg1, arg2)
+	ret0, _ := ret[0].(map[models.FindingStatus]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountFindings indicates an expected call of CountFindings.
+func (mr *MockFindingRepositoryMockRecorder) CountFindings(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountFindings", reflect.TypeOf((*MockFindingRepository)(nil).CountFindings), arg0, arg1, arg2)
+}
+
+// CreateFinding mocks base method.
+func (m *MockFindingRepository) CreateFinding(arg0 context.Context, arg1 *models.Finding) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFinding", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateFinding indicates an expected call of CreateFinding.
+func (mr *MockFindingRepositoryMockRecorder) CreateFinding(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFinding", reflect.TypeOf((*MockFindingRepository)(nil).CreateFinding), arg0, arg1)
+}
+
+// DeleteFinding mocks base method.
+func (m *MockFindingRepository) DeleteFinding(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFinding", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFinding indicates an expected call of DeleteFinding.
+func (mr *MockFindingRepositoryMockRecorder) DeleteFinding(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFinding", reflect.TypeOf((*MockFindingRepository)(nil).DeleteFinding), arg0, arg1)
+}
+
+// GetBaseline mocks base method.
+func (m *MockFindingRepository) GetBaseline(arg0 context.Context, arg1 string) (*models.FindingBaseline, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBaseline", arg0, arg1)
+	ret0, _ := ret[0].(*models.FindingBaseline)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBaseline indicates an expected call of GetBaseline.
+func (mr *MockFindingRepositoryMockRecorder) GetBaseline(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBaseline", reflect.TypeOf((*MockFindingRepository)(nil).GetBaseline), arg0, arg1)
+}
+
+// GetFinding mocks base method.
+func (m *MockFindingRepository) GetFinding(arg0 context.Context, arg1 string) (*models.Finding, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFinding", arg0, arg1)
+	ret0, _ := ret[0].(*models.Finding)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFinding indicates an expected call of GetFinding.
+func (mr *MockFindingRepositoryMockRecorder) GetFinding(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFinding", reflect.TypeOf((*MockFindingRepository)(nil).GetFinding), arg0, arg1)
+}
+
+// ListFindings mocks base method.
+func (m *MockFindingRepository) ListFindings(arg0 context.Context, arg1 string, arg2 repository.FindingFilter) ([]models.Finding, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFindings", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.Finding)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFindings indicates an expected call of ListFindings.
+func (mr *MockFindingRepositoryMockRecorder) ListFindings(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFindings", reflect.TypeOf((*MockFindingRepository)(nil).ListFindings), arg0, arg1, arg2)
+}
+
+// Rebaseline mocks base method.
+func (m *MockFindingRepository) Rebaseline(arg0 context.Context, arg1 *models.FindingBaseline) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rebaseline", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rebaseline indicates an expected call of Rebaseline.
+func (mr *MockFindingRepositoryMockRecorder) Rebaseline(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rebaseline", reflect.TypeOf((*MockFindingRepository)(nil).Rebaseline), arg0, arg1)
+}
+
+// SaveFindings mocks base method.
+func (m *MockFindingRepository) SaveFindings(arg0 context.Context, arg1 []models.Finding) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveFindings", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveFindings indicates an expected call of SaveFindings.
+func (mr *MockFindingRepositoryMockRecorder) SaveFindings(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveFindings", reflect.TypeOf((*MockFindingRepository)(nil).SaveFindings), arg0, arg1)
+}
+
+// UpdateFinding mocks base method.
+func (m *MockFindingRepository) UpdateFinding(arg0 context.Context, arg1 *models.Finding) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFinding", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateFinding indicates an expected call of UpdateFinding.
+func (mr *MockFindingRepositoryMockRecorder) UpdateFinding(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFinding", reflect.TypeOf((*MockFindingRepository)(nil).UpdateFinding), arg0, arg1)
+}
@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/repository (interfaces: GitHubAppConfigRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockGitHubAppConfigRepository is a mock of GitHubAppConfigRepository interface.
+type MockGitHubAppConfigRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockGitHubAppConfigRepositoryMockRecorder
+}
+
+// MockGitHubAppConfigRepositoryMockRecorder is the mock recorder for MockGitHubAppConfigRepository.
+type MockGitHubAppConfigRepositoryMockRecorder struct {
+	mock *MockGitHubAppConfigRepository
+}
+
+// NewMockGitHubAppConfigRepository creates a new mock instance.
+func NewMockGitHubAppConfigRepository(ctrl *gomock.Controller) *MockGitHubAppConfigRepository {
+	mock := &MockGitHubAppConfigRepository{ctrl: ctrl}
+	mock.recorder = &MockGitHubAppConfigRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGitHubAppConfigRepository) EXPECT() *MockGitHubAppConfigRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteConfig mocks base method.
+func (m *MockGitHubAppConfigRepository) DeleteConfig(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteConfig", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteConfig indicates an expected call of DeleteConfig.
+func (mr *MockGitHubAppConfigRepositoryMockRecorder) DeleteConfig(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteConfig", reflect.TypeOf((*MockGitHubAppConfigRepository)(nil).DeleteConfig), arg0, arg1)
+}
+
+// GetConfig mocks base method.
+func (m *MockGitHubAppConfigRepository) GetConfig(arg0 context.Context, arg1 string) (*models.GitHubAppConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfig", arg0, arg1)
+	ret0, _ := ret[0].(*models.GitHubAppConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfig indicates an expected call of GetConfig.
+func (mr *MockGitHubAppConfigRepositoryMockRecorder) GetConfig(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfig", reflect.TypeOf((*MockGitHubAppConfigRepository)(nil).GetConfig), arg0, arg1)
+}
+
+// ListConfigs mocks base method.
+func (m *MockGitHubAppConfigRepository) ListConfigs(arg0 context.Context) ([]models.GitHubAppConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListConfigs", arg0)
+	ret0, _ := ret[0].([]models.GitHubAppConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListConfigs indicates an expected call of ListConfigs.
+func (mr *MockGitHubAppConfigRepositoryMockRecorder) ListConfigs(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListConfigs", reflect.TypeOf((*MockGitHubAppConfigRepository)(nil).ListConfigs), arg0)
+}
+
+// PutConfig mocks base method.
+func (m *MockGitHubAppConfigRepository) PutConfig(arg0 context.Context, arg1 *models.GitHubAppConfig) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutConfig", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutConfig indicates an expected call of PutConfig.
+func (mr *MockGitHubAppConfigRepositoryMockRecorder) PutConfig(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutConfig", reflect.TypeOf((*MockGitHubAppConfigRepository)(nil).PutConfig), arg0, arg1)
+}
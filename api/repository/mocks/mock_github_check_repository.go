@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/repository (interfaces: GitHubCheckRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockGitHubCheckRepository is a mock of GitHubCheckRepository interface.
+type MockGitHubCheckRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockGitHubCheckRepositoryMockRecorder
+}
+
+// MockGitHubCheckRepositoryMockRecorder is the mock recorder for MockGitHubCheckRepository.
+type MockGitHubCheckRepositoryMockRecorder struct {
+	mock *MockGitHubCheckRepository
+}
+
+// NewMockGitHubCheckRepository creates a new mock instance.
+func NewMockGitHubCheckRepository(ctrl *gomock.Controller) *MockGitHubCheckRepository {
+	mock := &MockGitHubCheckRepository{ctrl: ctrl}
+	mock.recorder = &MockGitHubCheckRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGitHubCheckRepository) EXPECT() *MockGitHubCheckRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateCheck mocks base method.
+func (m *MockGitHubCheckRepository) CreateCheck(arg0 context.Context, arg1 *models.GitHubCheck) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCheck", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateCheck indicates an expected call of CreateCheck.
+func (mr *MockGitHubCheckRepositoryMockRecorder) CreateCheck(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCheck", reflect.TypeOf((*MockGitHubCheckRepository)(nil).CreateCheck), arg0, arg1)
+}
+
+// ListPublishable mocks base method.
+func (m *MockGitHubCheckRepository) ListPublishable(arg0 context.Context, arg1 int) ([]models.GitHubCheck, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPublishable", arg0, arg1)
+	ret0, _ := ret[0].([]models.GitHubCheck)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPublishable indicates an expected call of ListPublishable.
+func (mr *MockGitHubCheckRepositoryMockRecorder) ListPublishable(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPublishable", reflect.TypeOf((*MockGitHubCheckRepository)(nil).ListPublishable), arg0, arg1)
+}
+
+// MarkPublished mocks base method.
+func (m *MockGitHubCheckRepository) MarkPublished(arg0 context.Context, arg1 string, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkPublished", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkPublished indicates an expected call of MarkPublished.
+func (mr *MockGitHubCheckRepositoryMockRecorder) MarkPublished(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkPublished", reflect.TypeOf((*MockGitHubCheckRepository)(nil).MarkPublished), arg0, arg1, arg2)
+}
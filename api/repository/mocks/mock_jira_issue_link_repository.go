@@ -0,0 +1,95 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/repository (interfaces: JiraIssueLinkRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockJiraIssueLinkRepository is a mock of JiraIssueLinkRepository interface.
+type MockJiraIssueLinkRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockJiraIssueLinkRepositoryMockRecorder
+}
+
+// MockJiraIssueLinkRepositoryMockRecorder is the mock recorder for MockJiraIssueLinkRepository.
+type MockJiraIssueLinkRepositoryMockRecorder struct {
+	mock *MockJiraIssueLinkRepository
+}
+
+// NewMockJiraIssueLinkRepository creates a new mock instance.
+func NewMockJiraIssueLinkRepository(ctrl *gomock.Controller) *MockJiraIssueLinkRepository {
+	mock := &MockJiraIssueLinkRepository{ctrl: ctrl}
+	mock.recorder = &MockJiraIssueLinkRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJiraIssueLinkRepository) EXPECT() *MockJiraIssueLinkRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateLink mocks base method.
+func (m *MockJiraIssueLinkRepository) CreateLink(arg0 context.Context, arg1 *models.JiraIssueLink) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLink", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateLink indicates an expected call of CreateLink.
+func (mr *MockJiraIssueLinkRepositoryMockRecorder) CreateLink(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLink", reflect.TypeOf((*MockJiraIssueLinkRepository)(nil).CreateLink), arg0, arg1)
+}
+
+// ListLinks mocks base method.
+func (m *MockJiraIssueLinkRepository) ListLinks(arg0 context.Context, arg1 []string) ([]models.JiraIssueLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLinks", arg0, arg1)
+	ret0, _ := ret[0].([]models.JiraIssueLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLinks indicates an expected call of ListLinks.
+func (mr *MockJiraIssueLinkRepositoryMockRecorder) ListLinks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLinks", reflect.TypeOf((*MockJiraIssueLinkRepository)(nil).ListLinks), arg0, arg1)
+}
+
+// ListUpdates mocks base method.
+func (m *MockJiraIssueLinkRepository) ListUpdates(arg0 context.Context, arg1 int) ([]models.JiraIssueUpdate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUpdates", arg0, arg1)
+	ret0, _ := ret[0].([]models.JiraIssueUpdate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUpdates indicates an expected call of ListUpdates.
+func (mr *MockJiraIssueLinkRepositoryMockRecorder) ListUpdates(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUpdates", reflect.TypeOf((*MockJiraIssueLinkRepository)(nil).ListUpdates), arg0, arg1)
+}
+
+// MarkSynced mocks base method.
+func (m *MockJiraIssueLinkRepository) MarkSynced(arg0 context.Context, arg1 string, arg2 models.TaskStatus, arg3 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkSynced", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkSynced indicates an expected call of MarkSynced.
+func (mr *MockJiraIssueLinkRepositoryMockRecorder) MarkSynced(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkSynced", reflect.TypeOf((*MockJiraIssueLinkRepository)(nil).MarkSynced), arg0, arg1, arg2, arg3)
+}
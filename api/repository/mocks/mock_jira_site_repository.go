@@ -0,0 +1,109 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/repository (interfaces: JiraSiteRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockJiraSiteRepository is a mock of JiraSiteRepository interface.
+type MockJiraSiteRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockJiraSiteRepositoryMockRecorder
+}
+
+// MockJiraSiteRepositoryMockRecorder is the mock recorder for MockJiraSiteRepository.
+type MockJiraSiteRepositoryMockRecorder struct {
+	mock *MockJiraSiteRepository
+}
+
+// NewMockJiraSiteRepository creates a new mock instance.
+func NewMockJiraSiteRepository(ctrl *gomock.Controller) *MockJiraSiteRepository {
+	mock := &MockJiraSiteRepository{ctrl: ctrl}
+	mock.recorder = &MockJiraSiteRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJiraSiteRepository) EXPECT() *MockJiraSiteRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteSite mocks base method.
+func (m *MockJiraSiteRepository) DeleteSite(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSite", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSite indicates an expected call of DeleteSite.
+func (mr *MockJiraSiteRepositoryMockRecorder) DeleteSite(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSite", reflect.TypeOf((*MockJiraSiteRepository)(nil).DeleteSite), arg0, arg1)
+}
+
+// GetSite mocks base method.
+func (m *MockJiraSiteRepository) GetSite(arg0 context.Context, arg1 string) (*models.JiraSite, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSite", arg0, arg1)
+	ret0, _ := ret[0].(*models.JiraSite)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSite indicates an expected call of GetSite.
+func (mr *MockJiraSiteRepositoryMockRecorder) GetSite(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSite", reflect.TypeOf((*MockJiraSiteRepository)(nil).GetSite), arg0, arg1)
+}
+
+// GetSiteByProjectKey mocks base method.
+func (m *MockJiraSiteRepository) GetSiteByProjectKey(arg0 context.Context, arg1 string) (*models.JiraSite, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSiteByProjectKey", arg0, arg1)
+	ret0, _ := ret[0].(*models.JiraSite)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSiteByProjectKey indicates an expected call of GetSiteByProjectKey.
+func (mr *MockJiraSiteRepositoryMockRecorder) GetSiteByProjectKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSiteByProjectKey", reflect.TypeOf((*MockJiraSiteRepository)(nil).GetSiteByProjectKey), arg0, arg1)
+}
+
+// ListSites mocks base method.
+func (m *MockJiraSiteRepository) ListSites(arg0 context.Context) ([]models.JiraSite, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSites", arg0)
+	ret0, _ := ret[0].([]models.JiraSite)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSites indicates an expected call of ListSites.
+func (mr *MockJiraSiteRepositoryMockRecorder) ListSites(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSites", reflect.TypeOf((*MockJiraSiteRepository)(nil).ListSites), arg0)
+}
+
+// PutSite mocks base method.
+func (m *MockJiraSiteRepository) PutSite(arg0 context.Context, arg1 *models.JiraSite) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutSite", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutSite indicates an expected call of PutSite.
+func (mr *MockJiraSiteRepositoryMockRecorder) PutSite(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutSite", reflect.TypeOf((*MockJiraSiteRepository)(nil).PutSite), arg0, arg1)
+}
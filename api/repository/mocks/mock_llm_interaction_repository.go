@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/repository (interfaces: LLMInteractionRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockLLMInteractionRepository is a mock of LLMInteractionRepository interface.
+type MockLLMInteractionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLLMInteractionRepositoryMockRecorder
+}
+
+// MockLLMInteractionRepositoryMockRecorder is the mock recorder for MockLLMInteractionRepository.
+type MockLLMInteractionRepositoryMockRecorder struct {
+	mock *MockLLMInteractionRepository
+}
+
+// NewMockLLMInteractionRepository creates a new mock instance.
+func NewMockLLMInteractionRepository(ctrl *gomock.Controller) *MockLLMInteractionRepository {
+	mock := &MockLLMInteractionRepository{ctrl: ctrl}
+	mock.recorder = &MockLLMInteractionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLLMInteractionRepository) EXPECT() *MockLLMInteractionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateInteraction mocks base method.
+func (m *MockLLMInteractionRepository) CreateInteraction(arg0 context.Context, arg1 *models.LLMInteraction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInteraction", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateInteraction indicates an expected call of CreateInteraction.
+func (mr *MockLLMInteractionRepositoryMockRecorder) CreateInteraction(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInteraction", reflect.TypeOf((*MockLLMInteractionRepository)(nil).CreateInteraction), arg0, arg1)
+}
+
+// DeleteBefore mocks base method.
+func (m *MockLLMInteractionRepository) DeleteBefore(arg0 context.Context, arg1 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBefore", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteBefore indicates an expected call of DeleteBefore.
+func (mr *MockLLMInteractionRepositoryMockRecorder) DeleteBefore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBefore", reflect.TypeOf((*MockLLMInteractionRepository)(nil).DeleteBefore), arg0, arg1)
+}
+
+// ListInteractions mocks base method.
+func (m *MockLLMInteractionRepository) ListInteractions(arg0 context.Context, arg1 string) ([]models.LLMInteraction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInteractions", arg0, arg1)
+	ret0, _ := ret[0].([]models.LLMInteraction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInteractions indicates an expected call of ListInteractions.
+func (mr *MockLLMInteractionRepositoryMockRecorder) ListInteractions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInteractions", reflect.TypeOf((*MockLLMInteractionRepository)(nil).ListInteractions), arg0, arg1)
+}
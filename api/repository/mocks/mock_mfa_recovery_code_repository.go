@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/repository (interfaces: MFARecoveryCodeRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockMFARecoveryCodeRepository is a mock of MFARecoveryCodeRepository interface.
+type MockMFARecoveryCodeRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockMFARecoveryCodeRepositoryMockRecorder
+}
+
+// MockMFARecoveryCodeRepositoryMockRecorder is the mock recorder for MockMFARecoveryCodeRepository.
+type MockMFARecoveryCodeRepositoryMockRecorder struct {
+	mock *MockMFARecoveryCodeRepository
+}
+
+// NewMockMFARecoveryCodeRepository creates a new mock instance.
+func NewMockMFARecoveryCodeRepository(ctrl *gomock.Controller) *MockMFARecoveryCodeRepository {
+	mock := &MockMFARecoveryCodeRepository{ctrl: ctrl}
+	mock.recorder = &MockMFARecoveryCodeRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMFARecoveryCodeRepository) EXPECT() *MockMFARecoveryCodeRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ConsumeRecoveryCode mocks base method.
+func (m *MockMFARecoveryCodeRepository) ConsumeRecoveryCode(arg0 context.Context, arg1, arg2 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeRecoveryCode", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeRecoveryCode indicates an expected call of ConsumeRecoveryCode.
+func (mr *MockMFARecoveryCodeRepositoryMockRecorder) ConsumeRecoveryCode(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeRecoveryCode", reflect.TypeOf((*MockMFARecoveryCodeRepository)(nil).ConsumeRecoveryCode), arg0, arg1, arg2)
+}
+
+// ReplaceRecoveryCodes mocks base method.
+func (m *MockMFARecoveryCodeRepository) ReplaceRecoveryCodes(arg0 context.Context, arg1 string, arg2 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceRecoveryCodes", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceRecoveryCodes indicates an expected call of ReplaceRecoveryCodes.
+func (mr *MockMFARecoveryCodeRepositoryMockRecorder) ReplaceRecoveryCodes(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceRecoveryCodes", reflect.TypeOf((*MockMFARecoveryCodeRepository)(nil).ReplaceRecoveryCodes), arg0, arg1, arg2)
+}
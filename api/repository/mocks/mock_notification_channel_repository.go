@@ -0,0 +1,123 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/repository (interfaces: NotificationChannelRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockNotificationChannelRepository is a mock of NotificationChannelRepository interface.
+type MockNotificationChannelRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationChannelRepositoryMockRecorder
+}
+
+// MockNotificationChannelRepositoryMockRecorder is the mock recorder for MockNotificationChannelRepository.
+type MockNotificationChannelRepositoryMockRecorder struct {
+	mock *MockNotificationChannelRepository
+}
+
+// NewMockNotificationChannelRepository creates a new mock instance.
+func NewMockNotificationChannelRepository(ctrl *gomock.Controller) *MockNotificationChannelRepository {
+	mock := &MockNotificationChannelRepository{ctrl: ctrl}
+	mock.recorder = &MockNotificationChannelRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotificationChannelRepository) EXPECT() *MockNotificationChannelRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateChannel mocks base method.
+func (m *MockNotificationChannelRepository) CreateChannel(arg0 context.Context, arg1 *models.NotificationChannel) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateChannel", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateChannel indicates an expected call of CreateChannel.
+func (mr *MockNotificationChannelRepositoryMockRecorder) CreateChannel(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChannel", reflect.TypeOf((*MockNotificationChannelRepository)(nil).CreateChannel), arg0, arg1)
+}
+
+// DeleteChannel mocks base method.
+func (m *MockNotificationChannelRepository) DeleteChannel(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteChannel", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteChannel indicates an expected call of DeleteChannel.
+func (mr *MockNotificationChannelRepositoryMockRecorder) DeleteChannel(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteChannel", reflect.TypeOf((*MockNotificationChannelRepository)(nil).DeleteChannel), arg0, arg1)
+}
+
+// GetChannel mocks base method.
+func (m *MockNotificationChannelRepository) GetChannel(arg0 context.Context, arg1 string) (*models.NotificationChannel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChannel", arg0, arg1)
+	ret0, _ := ret[0].(*models.NotificationChannel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChannel indicates an expected call of GetChannel.
+func (mr *MockNotificationChannelRepositoryMockRecorder) GetChannel(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChannel", reflect.TypeOf((*MockNotificationChannelRepository)(nil).GetChannel), arg0, arg1)
+}
+
+// ListChannelsByUser mocks base method.
+func (m *MockNotificationChannelRepository) ListChannelsByUser(arg0 context.Context, arg1, arg2 string) ([]models.NotificationChannel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChannelsByUser", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.NotificationChannel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChannelsByUser indicates an expected call of ListChannelsByUser.
+func (mr *MockNotificationChannelRepositoryMockRecorder) ListChannelsByUser(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChannelsByUser", reflect.TypeOf((*MockNotificationChannelRepository)(nil).ListChannelsByUser), arg0, arg1, arg2)
+}
+
+// ListSubscribedChannels mocks base method.
+func (m *MockNotificationChannelRepository) ListSubscribedChannels(arg0 context.Context, arg1 models.NotificationEvent, arg2 string) ([]models.NotificationChannel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubscribedChannels", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.NotificationChannel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubscribedChannels indicates an expected call of ListSubscribedChannels.
+func (mr *MockNotificationChannelRepositoryMockRecorder) ListSubscribedChannels(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubscribedChannels", reflect.TypeOf((*MockNotificationChannelRepository)(nil).ListSubscribedChannels), arg0, arg1, arg2)
+}
+
+// UpdateChannel mocks base method.
+func (m *MockNotificationChannelRepository) UpdateChannel(arg0 context.Context, arg1 *models.NotificationChannel) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateChannel", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateChannel indicates an expected call of UpdateChannel.
+func (mr *MockNotificationChannelRepositoryMockRecorder) UpdateChannel(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateChannel", reflect.TypeOf((*MockNotificationChannelRepository)(nil).UpdateChannel), arg0, arg1)
+}
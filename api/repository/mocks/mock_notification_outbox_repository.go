@@ -0,0 +1,109 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/repository (interfaces: NotificationOutboxRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockNotificationOutboxRepository is a mock of NotificationOutboxRepository interface.
+type MockNotificationOutboxRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationOutboxRepositoryMockRecorder
+}
+
+// MockNotificationOutboxRepositoryMockRecorder is the mock recorder for MockNotificationOutboxRepository.
+type MockNotificationOutboxRepositoryMockRecorder struct {
+	mock *MockNotificationOutboxRepository
+}
+
+// NewMockNotificationOutboxRepository creates a new mock instance.
+func NewMockNotificationOutboxRepository(ctrl *gomock.Controller) *MockNotificationOutboxRepository {
+	mock := &MockNotificationOutboxRepository{ctrl: ctrl}
+	mock.recorder = &MockNotificationOutboxRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotificationOutboxRepository) EXPECT() *MockNotificationOutboxRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimDue mocks base method.
+func (m *MockNotificationOutboxRepository) ClaimDue(arg0 context.Context, arg1 int, arg2 time.Duration) ([]models.OutboxEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDue", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.OutboxEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDue indicates an expected call of ClaimDue.
+func (mr *MockNotificationOutboxRepositoryMockRecorder) ClaimDue(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDue", reflect.TypeOf((*MockNotificationOutboxRepository)(nil).ClaimDue), arg0, arg1, arg2)
+}
+
+// DeleteDelivered mocks base method.
+func (m *MockNotificationOutboxRepository) DeleteDelivered(arg0 context.Context, arg1 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDelivered", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteDelivered indicates an expected call of DeleteDelivered.
+func (mr *MockNotificationOutboxRepositoryMockRecorder) DeleteDelivered(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDelivered", reflect.TypeOf((*MockNotificationOutboxRepository)(nil).DeleteDelivered), arg0, arg1)
+}
+
+// Enqueue mocks base method.
+func (m *MockNotificationOutboxRepository) Enqueue(arg0 context.Context, arg1 models.Notification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enqueue", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Enqueue indicates an expected call of Enqueue.
+func (mr *MockNotificationOutboxRepositoryMockRecorder) Enqueue(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enqueue", reflect.TypeOf((*MockNotificationOutboxRepository)(nil).Enqueue), arg0, arg1)
+}
+
+// MarkDelivered mocks base method.
+func (m *MockNotificationOutboxRepository) MarkDelivered(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkDelivered", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkDelivered indicates an expected call of MarkDelivered.
+func (mr *MockNotificationOutboxRepositoryMockRecorder) MarkDelivered(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDelivered", reflect.TypeOf((*MockNotificationOutboxRepository)(nil).MarkDelivered), arg0, arg1)
+}
+
+// MarkFailed mocks base method.
+func (m *MockNotificationOutboxRepository) MarkFailed(arg0 context.Context, arg1, arg2 string, arg3 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkFailed", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkFailed indicates an expected call of MarkFailed.
+func (mr *MockNotificationOutboxRepositoryMockRecorder) MarkFailed(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkFailed", reflect.TypeOf((*MockNotificationOutboxRepository)(nil).MarkFailed), arg0, arg1, arg2, arg3)
+}
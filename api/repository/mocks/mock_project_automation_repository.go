@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/repository (interfaces: ProjectAutomationRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockProjectAutomationRepository is a mock of ProjectAutomationRepository interface.
+type MockProjectAutomationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockProjectAutomationRepositoryMockRecorder
+}
+
+// MockProjectAutomationRepositoryMockRecorder is the mock recorder for MockProjectAutomationRepository.
+type MockProjectAutomationRepositoryMockRecorder struct {
+	mock *MockProjectAutomationRepository
+}
+
+// NewMockProjectAutomationRepository creates a new mock instance.
+func NewMockProjectAutomationRepository(ctrl *gomock.Controller) *MockProjectAutomationRepository {
+	mock := &MockProjectAutomationRepository{ctrl: ctrl}
+	mock.recorder = &MockProjectAutomationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProjectAutomationRepository) EXPECT() *MockProjectAutomationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteAutomation mocks base method.
+func (m *MockProjectAutomationRepository) DeleteAutomation(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAutomation", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAutomation indicates an expected call of DeleteAutomation.
+func (mr *MockProjectAutomationRepositoryMockRecorder) DeleteAutomation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAutomation", reflect.TypeOf((*MockProjectAutomationRepository)(nil).DeleteAutomation), arg0, arg1)
+}
+
+// GetAutomation mocks base method.
+func (m *MockProjectAutomationRepository) GetAutomation(arg0 context.Context, arg1 string) (*models.ProjectAutomation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAutomation", arg0, arg1)
+	ret0, _ := ret[0].(*models.ProjectAutomation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAutomation indicates an expected call of GetAutomation.
+func (mr *MockProjectAutomationRepositoryMockRecorder) GetAutomation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAutomation", reflect.TypeOf((*MockProjectAutomationRepository)(nil).GetAutomation), arg0, arg1)
+}
+
+// SaveAutomation mocks base method.
+func (m *MockProjectAutomationRepository) SaveAutomation(arg0 context.Context, arg1 *models.ProjectAutomation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveAutomation", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveAutomation indicates an expected call of SaveAutomation.
+func (mr *MockProjectAutomationRepositoryMockRecorder) SaveAutomation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAutomation", reflect.TypeOf((*MockProjectAutomationRepository)(nil).SaveAutomation), arg0, arg1)
+}
@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/repository (interfaces: ProjectTemplateRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockProjectTemplateRepository is a mock of ProjectTemplateRepository interface.
+type MockProjectTemplateRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockProjectTemplateRepositoryMockRecorder
+}
+
+// MockProjectTemplateRepositoryMockRecorder is the mock recorder for MockProjectTemplateRepository.
+type MockProjectTemplateRepositoryMockRecorder struct {
+	mock *MockProjectTemplateRepository
+}
+
+// NewMockProjectTemplateRepository creates a new mock instance.
+func NewMockProjectTemplateRepository(ctrl *gomock.Controller) *MockProjectTemplateRepository {
+	mock := &MockProjectTemplateRepository{ctrl: ctrl}
+	mock.recorder = &MockProjectTemplateRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProjectTemplateRepository) EXPECT() *MockProjectTemplateRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateTemplate mocks base method.
+func (m *MockProjectTemplateRepository) CreateTemplate(arg0 context.Context, arg1 *models.ProjectTemplate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTemplate", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTemplate indicates an expected call of CreateTemplate.
+func (mr *MockProjectTemplateRepositoryMockRecorder) CreateTemplate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTemplate", reflect.TypeOf((*MockProjectTemplateRepository)(nil).CreateTemplate), arg0, arg1)
+}
+
+// DeleteTemplate mocks base method.
+func (m *MockProjectTemplateRepository) DeleteTemplate(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTemplate", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTemplate indicates an expected call of DeleteTemplate.
+func (mr *MockProjectTemplateRepositoryMockRecorder) DeleteTemplate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTemplate", reflect.TypeOf((*MockProjectTemplateRepository)(nil).DeleteTemplate), arg0, arg1)
+}
+
+// GetTemplate mocks base method.
+func (m *MockProjectTemplateRepository) GetTemplate(arg0 context.Context, arg1 string) (*models.ProjectTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplate", arg0, arg1)
+	ret0, _ := ret[0].(*models.ProjectTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplate indicates an expected call of GetTemplate.
+func (mr *MockProjectTemplateRepositoryMockRecorder) GetTemplate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplate", reflect.TypeOf((*MockProjectTemplateRepository)(nil).GetTemplate), arg0, arg1)
+}
+
+// ListTemplates mocks base method.
+func (m *MockProjectTemplateRepository) ListTemplates(arg0 context.Context) ([]*models.ProjectTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTemplates", arg0)
+	ret0, _ := ret[0].([]*models.ProjectTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTemplates indicates an expected call of ListTemplates.
+func (mr *MockProjectTemplateRepositoryMockRecorder) ListTemplates(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTemplates", reflect.TypeOf((*MockProjectTemplateRepository)(nil).ListTemplates), arg0)
+}
@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/repository (interfaces: QualityGatePolicyRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockQualityGatePolicyRepository is a mock of QualityGatePolicyRepository interface.
+type MockQualityGatePolicyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockQualityGatePolicyRepositoryMockRecorder
+}
+
+// MockQualityGatePolicyRepositoryMockRecorder is the mock recorder for MockQualityGatePolicyRepository.
+type MockQualityGatePolicyRepositoryMockRecorder struct {
+	mock *MockQualityGatePolicyRepository
+}
+
+// NewMockQualityGatePolicyRepository creates a new mock instance.
+func NewMockQualityGatePolicyRepository(ctrl *gomock.Controller) *MockQualityGatePolicyRepository {
+	mock := &MockQualityGatePolicyRepository{ctrl: ctrl}
+	mock.recorder = &MockQualityGatePolicyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQualityGatePolicyRepository) EXPECT() *MockQualityGatePolicyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetPolicy mocks base method.
+func (m *MockQualityGatePolicyRepository) GetPolicy(arg0 context.Context, arg1 string) (*models.QualityGatePolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPolicy", arg0, arg1)
+	ret0, _ := ret[0].(*models.QualityGatePolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPolicy indicates an expected call of GetPolicy.
+func (mr *MockQualityGatePolicyRepositoryMockRecorder) GetPolicy(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPolicy", reflect.TypeOf((*MockQualityGatePolicyRepository)(nil).GetPolicy), arg0, arg1)
+}
+
+// SavePolicy mocks base method.
+func (m *MockQualityGatePolicyRepository) SavePolicy(arg0 context.Context, arg1 *models.QualityGatePolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SavePolicy", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SavePolicy indicates an expected call of SavePolicy.
+func (mr *MockQualityGatePolicyRepositoryMockRecorder) SavePolicy(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePolicy", reflect.TypeOf((*MockQualityGatePolicyRepository)(nil).SavePolicy), arg0, arg1)
+}
@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/repository (interfaces: RedactionAuditRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockRedactionAuditRepository is a mock of RedactionAuditRepository interface.
+type MockRedactionAuditRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRedactionAuditRepositoryMockRecorder
+}
+
+// MockRedactionAuditRepositoryMockRecorder is the mock recorder for MockRedactionAuditRepository.
+type MockRedactionAuditRepositoryMockRecorder struct {
+	mock *MockRedactionAuditRepository
+}
+
+// NewMockRedactionAuditRepository creates a new mock instance.
+func NewMockRedactionAuditRepository(ctrl *gomock.Controller) *MockRedactionAuditRepository {
+	mock := &MockRedactionAuditRepository{ctrl: ctrl}
+	mock.recorder = &MockRedactionAuditRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRedactionAuditRepository) EXPECT() *MockRedactionAuditRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateAudit mocks base method.
+func (m *MockRedactionAuditRepository) CreateAudit(arg0 context.Context, arg1 *models.RedactionAudit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAudit", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAudit indicates an expected call of CreateAudit.
+func (mr *MockRedactionAuditRepositoryMockRecorder) CreateAudit(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAudit", reflect.TypeOf((*MockRedactionAuditRepository)(nil).CreateAudit), arg0, arg1)
+}
+
+// ListAudits mocks base method.
+func (m *MockRedactionAuditRepository) ListAudits(arg0 context.Context, arg1 string, arg2 int) ([]models.RedactionAudit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAudits", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.RedactionAudit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAudits indicates an expected call of ListAudits.
+func (mr *MockRedactionAuditRepositoryMockRecorder) ListAudits(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAudits", reflect.TypeOf((*MockRedactionAuditRepository)(nil).ListAudits), arg0, arg1, arg2)
+}
+
+// StreamAudits mocks base method.
+func (m *MockRedactionAuditRepository) StreamAudits(arg0 context.Context, arg1 string, arg2 func(models.RedactionAudit) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamAudits", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamAudits indicates an expected call of StreamAudits.
+func (mr *MockRedactionAuditRepositoryMockRecorder) StreamAudits(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamAudits", reflect.TypeOf((*MockRedactionAuditRepository)(nil).StreamAudits), arg0, arg1, arg2)
+}
@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/repository (interfaces: RedactionPolicyRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockRedactionPolicyRepository is a mock of RedactionPolicyRepository interface.
+type MockRedactionPolicyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRedactionPolicyRepositoryMockRecorder
+}
+
+// MockRedactionPolicyRepositoryMockRecorder is the mock recorder for MockRedactionPolicyRepository.
+type MockRedactionPolicyRepositoryMockRecorder struct {
+	mock *MockRedactionPolicyRepository
+}
+
+// NewMockRedactionPolicyRepository creates a new mock instance.
+func NewMockRedactionPolicyRepository(ctrl *gomock.Controller) *MockRedactionPolicyRepository {
+	mock := &MockRedactionPolicyRepository{ctrl: ctrl}
+	mock.recorder = &MockRedactionPolicyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRedactionPolicyRepository) EXPECT() *MockRedactionPolicyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetPolicy mocks base method.
+func (m *MockRedactionPolicyRepository) GetPolicy(arg0 context.Context, arg1 string) (*models.RedactionPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPolicy", arg0, arg1)
+	ret0, _ := ret[0].(*models.RedactionPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPolicy indicates an expected call of GetPolicy.
+func (mr *MockRedactionPolicyRepositoryMockRecorder) GetPolicy(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPolicy", reflect.TypeOf((*MockRedactionPolicyRepository)(nil).GetPolicy), arg0, arg1)
+}
+
+// SavePolicy mocks base method.
+func (m *MockRedactionPolicyRepository) SavePolicy(arg0 context.Context, arg1 *models.RedactionPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SavePolicy", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SavePolicy indicates an expected call of SavePolicy.
+func (mr *MockRedactionPolicyRepositoryMockRecorder) SavePolicy(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePolicy", reflect.TypeOf((*MockRedactionPolicyRepository)(nil).SavePolicy), arg0, arg1)
+}
@@ -0,0 +1,211 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/repository (interfaces: RoleRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+	repository "github.com/kazemisoroush/code-refactoring-tool/api/repository"
+)
+
+// MockRoleRepository is a mock of RoleRepository interface.
+type MockRoleRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoleRepositoryMockRecorder
+}
+
+// MockRoleRepositoryMockRecorder is the mock recorder for MockRoleRepository.
+type MockRoleRepositoryMockRecorder struct {
+	mock *MockRoleRepository
+}
+
+// NewMockRoleRepository creates a new mock instance.
+func NewMockRoleRepository(ctrl *gomock.Controller) *MockRoleRepository {
+	mock := &MockRoleRepository{ctrl: ctrl}
+	mock.recorder = &MockRoleRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoleRepository) EXPECT() *MockRoleRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateRole mocks base method.
+func (m *MockRoleRepository) CreateRole(arg0 context.Context, arg1 *models.Role) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRole", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRole indicates an expected call of CreateRole.
+func (mr *MockRoleRepositoryMockRecorder) CreateRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRole", reflect.TypeOf((*MockRoleRepository)(nil).CreateRole), arg0, arg1)
+}
+
+// DeleteProjectMember mocks base method.
+func (m *MockRoleRepository) DeleteProjectMember(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteProjectMember", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteProjectMember indicates an expected call of DeleteProjectMember.
+func (mr *MockRoleRepositoryMockRecorder) DeleteProjectMember(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProjectMember", reflect.TypeOf((*MockRoleRepository)(nil).DeleteProjectMember), arg0, arg1, arg2)
+}
+
+// DeleteRole mocks base method.
+func (m *MockRoleRepository) DeleteRole(arg0 context.Context, arg1 models.UserRole) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRole", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRole indicates an expected call of DeleteRole.
+func (mr *MockRoleRepositoryMockRecorder) DeleteRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRole", reflect.TypeOf((*MockRoleRepository)(nil).DeleteRole), arg0, arg1)
+}
+
+// GetProjectMember mocks base method.
+func (m *MockRoleRepository) GetProjectMember(arg0 context.Context, arg1, arg2 string) (*models.ProjectMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProjectMember", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.ProjectMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProjectMember indicates an expected call of GetProjectMember.
+func (mr *MockRoleRepositoryMockRecorder) GetProjectMember(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjectMember", reflect.TypeOf((*MockRoleRepository)(nil).GetProjectMember), arg0, arg1, arg2)
+}
+
+// GetRole mocks base method.
+func (m *MockRoleRepository) GetRole(arg0 context.Context, arg1 models.UserRole) (*models.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRole", arg0, arg1)
+	ret0, _ := ret[0].(*models.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRole indicates an expected call of GetRole.
+func (mr *MockRoleRepositoryMockRecorder) GetRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRole", reflect.TypeOf((*MockRoleRepository)(nil).GetRole), arg0, arg1)
+}
+
+// ListPermissions mocks base method.
+func (m *MockRoleRepository) ListPermissions(arg0 context.Context) ([]models.PermissionDefinition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPermissions", arg0)
+	ret0, _ := ret[0].([]models.PermissionDefinition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPermissions indicates an expected call of ListPermissions.
+func (mr *MockRoleRepositoryMockRecorder) ListPermissions(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPermissions", reflect.TypeOf((*MockRoleRepository)(nil).ListPermissions), arg0)
+}
+
+// ListProjectMembers mocks base method.
+func (m *MockRoleRepository) ListProjectMembers(arg0 context.Context, arg1 string) ([]models.ProjectMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListProjectMembers", arg0, arg1)
+	ret0, _ := ret[0].([]models.ProjectMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListProjectMembers indicates an expected call of ListProjectMembers.
+func (mr *MockRoleRepositoryMockRecorder) ListProjectMembers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProjectMembers", reflect.TypeOf((*MockRoleRepository)(nil).ListProjectMembers), arg0, arg1)
+}
+
+// ListRoles mocks base method.
+func (m *MockRoleRepository) ListRoles(arg0 context.Context) ([]models.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRoles", arg0)
+	ret0, _ := ret[0].([]models.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRoles indicates an expected call of ListRoles.
+func (mr *MockRoleRepositoryMockRecorder) ListRoles(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRoles", reflect.TypeOf((*MockRoleRepository)(nil).ListRoles), arg0)
+}
+
+// SetProjectMember mocks base method.
+func (m *MockRoleRepository) SetProjectMember(arg0 context.Context, arg1 *models.ProjectMember) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetProjectMember", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetProjectMember indicates an expected call of SetProjectMember.
+func (mr *MockRoleRepositoryMockRecorder) SetProjectMember(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetProjectMember", reflect.TypeOf((*MockRoleRepository)(nil).SetProjectMember), arg0, arg1)
+}
+
+// StreamProjectMembers mocks base method.
+func (m *MockRoleRepository) StreamProjectMembers(arg0 context.Context, arg1 repository.TimeRange, arg2 func(models.ProjectMember) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamProjectMembers", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamProjectMembers indicates an expected call of StreamProjectMembers.
+func (mr *MockRoleRepositoryMockRecorder) StreamProjectMembers(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamProjectMembers", reflect.TypeOf((*MockRoleRepository)(nil).StreamProjectMembers), arg0, arg1, arg2)
+}
+
+// TransferProjectOwnership mocks base method.
+func (m *MockRoleRepository) TransferProjectOwnership(arg0 context.Context, arg1, arg2 string, arg3 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferProjectOwnership", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TransferProjectOwnership indicates an expected call of TransferProjectOwnership.
+func (mr *MockRoleRepositoryMockRecorder) TransferProjectOwnership(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferProjectOwnership", reflect.TypeOf((*MockRoleRepository)(nil).TransferProjectOwnership), arg0, arg1, arg2, arg3)
+}
+
+// UpdateRole mocks base method.
+func (m *MockRoleRepository) UpdateRole(arg0 context.Context, arg1 *models.Role) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRole", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRole indicates an expected call of UpdateRole.
+func (mr *MockRoleRepositoryMockRecorder) UpdateRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*MockRoleRepository)(nil).UpdateRole), arg0, arg1)
+}
@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/repository (interfaces: ScanFindingRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockScanFindingRepository is a mock of ScanFindingRepository interface.
+type MockScanFindingRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockScanFindingRepositoryMockRecorder
+}
+
+// MockScanFindingRepositoryMockRecorder is the mock recorder for MockScanFindingRepository.
+type MockScanFindingRepositoryMockRecorder struct {
+	mock *MockScanFindingRepository
+}
+
+// NewMockScanFindingRepository creates a new mock instance.
+func NewMockScanFindingRepository(ctrl *gomock.Controller) *MockScanFindingRepository {
+	mock := &MockScanFindingRepository{ctrl: ctrl}
+	mock.recorder = &MockScanFindingRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScanFindingRepository) EXPECT() *MockScanFindingRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ListFindings mocks base method.
+func (m *MockScanFindingRepository) ListFindings(arg0 context.Context, arg1 string) ([]models.ScanFinding, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFindings", arg0, arg1)
+	ret0, _ := ret[0].([]models.ScanFinding)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFindings indicates an expected call of ListFindings.
+func (mr *MockScanFindingRepositoryMockRecorder) ListFindings(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFindings", reflect.TypeOf((*MockScanFindingRepository)(nil).ListFindings), arg0, arg1)
+}
+
+// ReplaceFindings mocks base method.
+func (m *MockScanFindingRepository) ReplaceFindings(arg0 context.Context, arg1 string, arg2 []models.ScanFinding) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceFindings", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceFindings indicates an expected call of ReplaceFindings.
+func (mr *MockScanFindingRepositoryMockRecorder) ReplaceFindings(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceFindings", reflect.TypeOf((*MockScanFindingRepository)(nil).ReplaceFindings), arg0, arg1, arg2)
+}
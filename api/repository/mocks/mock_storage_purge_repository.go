@@ -0,0 +1,110 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/repository (interfaces: StoragePurgeRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockStoragePurgeRepository is a mock of StoragePurgeRepository interface.
+type MockStoragePurgeRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockStoragePurgeRepositoryMockRecorder
+}
+
+// MockStoragePurgeRepositoryMockRecorder is the mock recorder for MockStoragePurgeRepository.
+type MockStoragePurgeRepositoryMockRecorder struct {
+	mock *MockStoragePurgeRepository
+}
+
+// NewMockStoragePurgeRepository creates a new mock instance.
+func NewMockStoragePurgeRepository(ctrl *gomock.Controller) *MockStoragePurgeRepository {
+	mock := &MockStoragePurgeRepository{ctrl: ctrl}
+	mock.recorder = &MockStoragePurgeRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStoragePurgeRepository) EXPECT() *MockStoragePurgeRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimDue mocks base method.
+func (m *MockStoragePurgeRepository) ClaimDue(arg0 context.Context, arg1 int, arg2 time.Duration) ([]models.StoragePurge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDue", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.StoragePurge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDue indicates an expected call of ClaimDue.
+func (mr *MockStoragePurgeRepositoryMockRecorder) ClaimDue(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDue", reflect.TypeOf((*MockStoragePurgeRepository)(nil).ClaimDue), arg0, arg1, arg2)
+}
+
+// CreatePurge mocks base method.
+func (m *MockStoragePurgeRepository) CreatePurge(arg0 context.Context, arg1 *models.StoragePurge) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePurge", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePurge indicates an expected call of CreatePurge.
+func (mr *MockStoragePurgeRepositoryMockRecorder) CreatePurge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePurge", reflect.TypeOf((*MockStoragePurgeRepository)(nil).CreatePurge), arg0, arg1)
+}
+
+// GetPurge mocks base method.
+func (m *MockStoragePurgeRepository) GetPurge(arg0 context.Context, arg1 string) (*models.StoragePurge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPurge", arg0, arg1)
+	ret0, _ := ret[0].(*models.StoragePurge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPurge indicates an expected call of GetPurge.
+func (mr *MockStoragePurgeRepositoryMockRecorder) GetPurge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPurge", reflect.TypeOf((*MockStoragePurgeRepository)(nil).GetPurge), arg0, arg1)
+}
+
+// ListPurges mocks base method.
+func (m *MockStoragePurgeRepository) ListPurges(arg0 context.Context, arg1 models.StoragePurgeStatus, arg2 int) ([]models.StoragePurge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPurges", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.StoragePurge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPurges indicates an expected call of ListPurges.
+func (mr *MockStoragePurgeRepositoryMockRecorder) ListPurges(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPurges", reflect.TypeOf((*MockStoragePurgeRepository)(nil).ListPurges), arg0, arg1, arg2)
+}
+
+// UpdatePurge mocks base method.
+func (m *MockStoragePurgeRepository) UpdatePurge(arg0 context.Context, arg1 *models.StoragePurge, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePurge", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePurge indicates an expected call of UpdatePurge.
+func (mr *MockStoragePurgeRepositoryMockRecorder) UpdatePurge(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePurge", reflect.TypeOf((*MockStoragePurgeRepository)(nil).UpdatePurge), arg0, arg1, arg2)
+}
@@ -0,0 +1,108 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/repository (interfaces: TagKeyRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockTagKeyRepository is a mock of TagKeyRepository interface.
+type MockTagKeyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTagKeyRepositoryMockRecorder
+}
+
+// MockTagKeyRepositoryMockRecorder is the mock recorder for MockTagKeyRepository.
+type MockTagKeyRepositoryMockRecorder struct {
+	mock *MockTagKeyRepository
+}
+
+// NewMockTagKeyRepository creates a new mock instance.
+func NewMockTagKeyRepository(ctrl *gomock.Controller) *MockTagKeyRepository {
+	mock := &MockTagKeyRepository{ctrl: ctrl}
+	mock.recorder = &MockTagKeyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTagKeyRepository) EXPECT() *MockTagKeyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateTagKey mocks base method.
+func (m *MockTagKeyRepository) CreateTagKey(arg0 context.Context, arg1 *models.TagKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTagKey", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTagKey indicates an expected call of CreateTagKey.
+func (mr *MockTagKeyRepositoryMockRecorder) CreateTagKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTagKey", reflect.TypeOf((*MockTagKeyRepository)(nil).CreateTagKey), arg0, arg1)
+}
+
+// DeleteTagKey mocks base method.
+func (m *MockTagKeyRepository) DeleteTagKey(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTagKey", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTagKey indicates an expected call of DeleteTagKey.
+func (mr *MockTagKeyRepositoryMockRecorder) DeleteTagKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTagKey", reflect.TypeOf((*MockTagKeyRepository)(nil).DeleteTagKey), arg0, arg1)
+}
+
+// GetTagKey mocks base method.
+func (m *MockTagKeyRepository) GetTagKey(arg0 context.Context, arg1 string) (*models.TagKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTagKey", arg0, arg1)
+	ret0, _ := ret[0].(*models.TagKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTagKey indicates an expected call of GetTagKey.
+func (mr *MockTagKeyRepositoryMockRecorder) GetTagKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagKey", reflect.TypeOf((*MockTagKeyRepository)(nil).GetTagKey), arg0, arg1)
+}
+
+// ListTagKeys mocks base method.
+func (m *MockTagKeyRepository) ListTagKeys(arg0 context.Context) ([]models.TagKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTagKeys", arg0)
+	ret0, _ := ret[0].([]models.TagKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTagKeys indicates an expected call of ListTagKeys.
+func (mr *MockTagKeyRepositoryMockRecorder) ListTagKeys(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTagKeys", reflect.TypeOf((*MockTagKeyRepository)(nil).ListTagKeys), arg0)
+}
+
+// UpdateTagKey mocks base method.
+func (m *MockTagKeyRepository) UpdateTagKey(arg0 context.Context, arg1 *models.TagKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTagKey", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTagKey indicates an expected call of UpdateTagKey.
+func (mr *MockTagKeyRepositoryMockRecorder) UpdateTagKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTagKey", reflect.TypeOf((*MockTagKeyRepository)(nil).UpdateTagKey), arg0, arg1)
+}
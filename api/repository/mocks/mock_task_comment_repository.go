@@ -0,0 +1,108 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/repository (interfaces: TaskCommentRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockTaskCommentRepository is a mock of TaskCommentRepository interface.
+type MockTaskCommentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaskCommentRepositoryMockRecorder
+}
+
+// MockTaskCommentRepositoryMockRecorder is the mock recorder for MockTaskCommentRepository.
+type MockTaskCommentRepositoryMockRecorder struct {
+	mock *MockTaskCommentRepository
+}
+
+// NewMockTaskCommentRepository creates a new mock instance.
+func NewMockTaskCommentRepository(ctrl *gomock.Controller) *MockTaskCommentRepository {
+	mock := &MockTaskCommentRepository{ctrl: ctrl}
+	mock.recorder = &MockTaskCommentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTaskCommentRepository) EXPECT() *MockTaskCommentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateComment mocks base method.
+func (m *MockTaskCommentRepository) CreateComment(arg0 context.Context, arg1 *models.TaskComment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateComment", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateComment indicates an expected call of CreateComment.
+func (mr *MockTaskCommentRepositoryMockRecorder) CreateComment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateComment", reflect.TypeOf((*MockTaskCommentRepository)(nil).CreateComment), arg0, arg1)
+}
+
+// DeleteComment mocks base method.
+func (m *MockTaskCommentRepository) DeleteComment(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteComment", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteComment indicates an expected call of DeleteComment.
+func (mr *MockTaskCommentRepositoryMockRecorder) DeleteComment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteComment", reflect.TypeOf((*MockTaskCommentRepository)(nil).DeleteComment), arg0, arg1)
+}
+
+// GetComment mocks base method.
+func (m *MockTaskCommentRepository) GetComment(arg0 context.Context, arg1 string) (*models.TaskComment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetComment", arg0, arg1)
+	ret0, _ := ret[0].(*models.TaskComment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetComment indicates an expected call of GetComment.
+func (mr *MockTaskCommentRepositoryMockRecorder) GetComment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetComment", reflect.TypeOf((*MockTaskCommentRepository)(nil).GetComment), arg0, arg1)
+}
+
+// ListComments mocks base method.
+func (m *MockTaskCommentRepository) ListComments(arg0 context.Context, arg1 string) ([]models.TaskComment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListComments", arg0, arg1)
+	ret0, _ := ret[0].([]models.TaskComment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListComments indicates an expected call of ListComments.
+func (mr *MockTaskCommentRepositoryMockRecorder) ListComments(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListComments", reflect.TypeOf((*MockTaskCommentRepository)(nil).ListComments), arg0, arg1)
+}
+
+// UpdateComment mocks base method.
+func (m *MockTaskCommentRepository) UpdateComment(arg0 context.Context, arg1 *models.TaskComment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateComment", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateComment indicates an expected call of UpdateComment.
+func (mr *MockTaskCommentRepositoryMockRecorder) UpdateComment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateComment", reflect.TypeOf((*MockTaskCommentRepository)(nil).UpdateComment), arg0, arg1)
+}
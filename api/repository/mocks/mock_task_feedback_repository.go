@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/repository (interfaces: TaskFeedbackRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockTaskFeedbackRepository is a mock of TaskFeedbackRepository interface.
+type MockTaskFeedbackRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaskFeedbackRepositoryMockRecorder
+}
+
+// MockTaskFeedbackRepositoryMockRecorder is the mock recorder for MockTaskFeedbackRepository.
+type MockTaskFeedbackRepositoryMockRecorder struct {
+	mock *MockTaskFeedbackRepository
+}
+
+// NewMockTaskFeedbackRepository creates a new mock instance.
+func NewMockTaskFeedbackRepository(ctrl *gomock.Controller) *MockTaskFeedbackRepository {
+	mock := &MockTaskFeedbackRepository{ctrl: ctrl}
+	mock.recorder = &MockTaskFeedbackRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTaskFeedbackRepository) EXPECT() *MockTaskFeedbackRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ListFeedback mocks base method.
+func (m *MockTaskFeedbackRepository) ListFeedback(arg0 context.Context, arg1 string) ([]models.TaskFeedback, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFeedback", arg0, arg1)
+	ret0, _ := ret[0].([]models.TaskFeedback)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFeedback indicates an expected call of ListFeedback.
+func (mr *MockTaskFeedbackRepositoryMockRecorder) ListFeedback(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFeedback", reflect.TypeOf((*MockTaskFeedbackRepository)(nil).ListFeedback), arg0, arg1)
+}
+
+// UpsertFeedback mocks base method.
+func (m *MockTaskFeedbackRepository) UpsertFeedback(arg0 context.Context, arg1 *models.TaskFeedback) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertFeedback", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertFeedback indicates an expected call of UpsertFeedback.
+func (mr *MockTaskFeedbackRepositoryMockRecorder) UpsertFeedback(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertFeedback", reflect.TypeOf((*MockTaskFeedbackRepository)(nil).UpsertFeedback), arg0, arg1)
+}
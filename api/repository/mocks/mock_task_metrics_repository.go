@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/repository (interfaces: TaskMetricsRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	repository "github.com/kazemisoroush/code-refactoring-tool/api/repository"
+)
+
+// MockTaskMetricsRepository is a mock of TaskMetricsRepository interface.
+type MockTaskMetricsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaskMetricsRepositoryMockRecorder
+}
+
+// MockTaskMetricsRepositoryMockRecorder is the mock recorder for MockTaskMetricsRepository.
+type MockTaskMetricsRepositoryMockRecorder struct {
+	mock *MockTaskMetricsRepository
+}
+
+// NewMockTaskMetricsRepository creates a new mock instance.
+func NewMockTaskMetricsRepository(ctrl *gomock.Controller) *MockTaskMetricsRepository {
+	mock := &MockTaskMetricsRepository{ctrl: ctrl}
+	mock.recorder = &MockTaskMetricsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTaskMetricsRepository) EXPECT() *MockTaskMetricsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ListRollups mocks base method.
+func (m *MockTaskMetricsRepository) ListRollups(arg0 context.Context, arg1 string, arg2, arg3 time.Time) ([]repository.TaskMetricsRollup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRollups", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]repository.TaskMetricsRollup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRollups indicates an expected call of ListRollups.
+func (mr *MockTaskMetricsRepositoryMockRecorder) ListRollups(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRollups", reflect.TypeOf((*MockTaskMetricsRepository)(nil).ListRollups), arg0, arg1, arg2, arg3)
+}
+
+// RefreshRollups mocks base method.
+func (m *MockTaskMetricsRepository) RefreshRollups(arg0 context.Context, arg1 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshRollups", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefreshRollups indicates an expected call of RefreshRollups.
+func (mr *MockTaskMetricsRepositoryMockRecorder) RefreshRollups(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshRollups", reflect.TypeOf((*MockTaskMetricsRepository)(nil).RefreshRollups), arg0, arg1)
+}
@@ -0,0 +1,362 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/repository (interfaces: TaskRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+	repository "github.com/kazemisoroush/code-refactoring-tool/api/repository"
+)
+
+// MockTaskRepository is a mock of TaskRepository interface.
+type MockTaskRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaskRepositoryMockRecorder
+}
+
+// MockTaskRepositoryMockRecorder is the mock recorder for MockTaskRepository.
+type MockTaskRepositoryMockRecorder struct {
+	mock *MockTaskRepository
+}
+
+// NewMockTaskRepository creates a new mock instance.
+func NewMockTaskRepository(ctrl *gomock.Controller) *MockTaskRepository {
+	mock := &MockTaskRepository{ctrl: ctrl}
+	mock.recorder = &MockTaskRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTaskRepository) EXPECT() *MockTaskRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AssignExperiment mocks base method.
+func (m *MockTaskRepository) AssignExperiment(arg0 context.Context, arg1, arg2 string, arg3 models.ExperimentArm) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignExperiment", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignExperiment indicates an expected call of AssignExperiment.
+func (mr *MockTaskRepositoryMockRecorder) AssignExperiment(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignExperiment", reflect.TypeOf((*MockTaskRepository)(nil).AssignExperiment), arg0, arg1, arg2, arg3)
+}
+
+// CountByProject mocks base method.
+func (m *MockTaskRepository) CountByProject(arg0 context.Context, arg1 string, arg2 time.Time) ([]repository.TaskCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByProject", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]repository.TaskCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByProject indicates an expected call of CountByProject.
+func (mr *MockTaskRepositoryMockRecorder) CountByProject(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByProject", reflect.TypeOf((*MockTaskRepository)(nil).CountByProject), arg0, arg1, arg2)
+}
+
+// Create mocks base method.
+func (m *MockTaskRepository) Create(arg0 context.Context, arg1 *models.Task) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTaskRepositoryMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTaskRepository)(nil).Create), arg0, arg1)
+}
+
+// CreateBatch mocks base method.
+func (m *MockTaskRepository) CreateBatch(arg0 context.Context, arg1 []*models.Task) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBatch", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateBatch indicates an expected call of CreateBatch.
+func (mr *MockTaskRepositoryMockRecorder) CreateBatch(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBatch", reflect.TypeOf((*MockTaskRepository)(nil).CreateBatch), arg0, arg1)
+}
+
+// Delete mocks base method.
+func (m *MockTaskRepository) Delete(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTaskRepositoryMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTaskRepository)(nil).Delete), arg0, arg1)
+}
+
+// GetByID mocks base method.
+func (m *MockTaskRepository) GetByID(arg0 context.Context, arg1 string) (*models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", arg0, arg1)
+	ret0, _ := ret[0].(*models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockTaskRepositoryMockRecorder) GetByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockTaskRepository)(nil).GetByID), arg0, arg1)
+}
+
+// Heartbeat mocks base method.
+func (m *MockTaskRepository) Heartbeat(arg0 context.Context, arg1 string, arg2 int, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Heartbeat", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Heartbeat indicates an expected call of Heartbeat.
+func (mr *MockTaskRepositoryMockRecorder) Heartbeat(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Heartbeat", reflect.TypeOf((*MockTaskRepository)(nil).Heartbeat), arg0, arg1, arg2, arg3)
+}
+
+// ListByAgent mocks base method.
+func (m *MockTaskRepository) ListByAgent(arg0 context.Context, arg1 string, arg2 repository.TaskFilters) ([]models.Task, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByAgent", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.Task)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListByAgent indicates an expected call of ListByAgent.
+func (mr *MockTaskRepositoryMockRecorder) ListByAgent(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByAgent", reflect.TypeOf((*MockTaskRepository)(nil).ListByAgent), arg0, arg1, arg2)
+}
+
+// ListByBatch mocks base method.
+func (m *MockTaskRepository) ListByBatch(arg0 context.Context, arg1 string) ([]models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByBatch", arg0, arg1)
+	ret0, _ := ret[0].([]models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByBatch indicates an expected call of ListByBatch.
+func (mr *MockTaskRepositoryMockRecorder) ListByBatch(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByBatch", reflect.TypeOf((*MockTaskRepository)(nil).ListByBatch), arg0, arg1)
+}
+
+// ListByCampaign mocks base method.
+func (m *MockTaskRepository) ListByCampaign(arg0 context.Context, arg1 string) ([]models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByCampaign", arg0, arg1)
+	ret0, _ := ret[0].([]models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByCampaign indicates an expected call of ListByCampaign.
+func (mr *MockTaskRepositoryMockRecorder) ListByCampaign(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByCampaign", reflect.TypeOf((*MockTaskRepository)(nil).ListByCampaign), arg0, arg1)
+}
+
+// ListByCodebase mocks base method.
+func (m *MockTaskRepository) ListByCodebase(arg0 context.Context, arg1 string, arg2 repository.TaskFilters) ([]models.Task, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByCodebase", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.Task)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListByCodebase indicates an expected call of ListByCodebase.
+func (mr *MockTaskRepositoryMockRecorder) ListByCodebase(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByCodebase", reflect.TypeOf((*MockTaskRepository)(nil).ListByCodebase), arg0, arg1, arg2)
+}
+
+// ListByExperiment mocks base method.
+func (m *MockTaskRepository) ListByExperiment(arg0 context.Context, arg1 string) ([]models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByExperiment", arg0, arg1)
+	ret0, _ := ret[0].([]models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByExperiment indicates an expected call of ListByExperiment.
+func (mr *MockTaskRepositoryMockRecorder) ListByExperiment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByExperiment", reflect.TypeOf((*MockTaskRepository)(nil).ListByExperiment), arg0, arg1)
+}
+
+// ListByProject mocks base method.
+func (m *MockTaskRepository) ListByProject(arg0 context.Context, arg1 string, arg2 repository.TaskFilters) ([]models.Task, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByProject", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.Task)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListByProject indicates an expected call of ListByProject.
+func (mr *MockTaskRepositoryMockRecorder) ListByProject(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByProject", reflect.TypeOf((*MockTaskRepository)(nil).ListByProject), arg0, arg1, arg2)
+}
+
+// ListRecentFailures mocks base method.
+func (m *MockTaskRepository) ListRecentFailures(arg0 context.Context, arg1 string, arg2 time.Time, arg3 int) ([]models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRecentFailures", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRecentFailures indicates an expected call of ListRecentFailures.
+func (mr *MockTaskRepositoryMockRecorder) ListRecentFailures(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRecentFailures", reflect.TypeOf((*MockTaskRepository)(nil).ListRecentFailures), arg0, arg1, arg2, arg3)
+}
+
+// ListStuck mocks base method.
+func (m *MockTaskRepository) ListStuck(arg0 context.Context, arg1 time.Time, arg2 int) ([]models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStuck", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStuck indicates an expected call of ListStuck.
+func (mr *MockTaskRepositoryMockRecorder) ListStuck(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStuck", reflect.TypeOf((*MockTaskRepository)(nil).ListStuck), arg0, arg1, arg2)
+}
+
+// NextQueued mocks base method.
+func (m *MockTaskRepository) NextQueued(arg0 context.Context) (*models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NextQueued", arg0)
+	ret0, _ := ret[0].(*models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextQueued indicates an expected call of NextQueued.
+func (mr *MockTaskRepositoryMockRecorder) NextQueued(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextQueued", reflect.TypeOf((*MockTaskRepository)(nil).NextQueued), arg0)
+}
+
+// QueuePosition mocks base method.
+func (m *MockTaskRepository) QueuePosition(arg0 context.Context, arg1 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueuePosition", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueuePosition indicates an expected call of QueuePosition.
+func (mr *MockTaskRepositoryMockRecorder) QueuePosition(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueuePosition", reflect.TypeOf((*MockTaskRepository)(nil).QueuePosition), arg0, arg1)
+}
+
+// RecoverStuck mocks base method.
+func (m *MockTaskRepository) RecoverStuck(arg0 context.Context, arg1 string, arg2 time.Time, arg3 models.TaskStatus, arg4 *string, arg5 *models.Notification) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecoverStuck", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecoverStuck indicates an expected call of RecoverStuck.
+func (mr *MockTaskRepositoryMockRecorder) RecoverStuck(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecoverStuck", reflect.TypeOf((*MockTaskRepository)(nil).RecoverStuck), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// StreamByProject mocks base method.
+func (m *MockTaskRepository) StreamByProject(arg0 context.Context, arg1 string, arg2 repository.TaskFilters, arg3 func(models.Task) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamByProject", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamByProject indicates an expected call of StreamByProject.
+func (mr *MockTaskRepositoryMockRecorder) StreamByProject(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamByProject", reflect.TypeOf((*MockTaskRepository)(nil).StreamByProject), arg0, arg1, arg2, arg3)
+}
+
+// Update mocks base method.
+func (m *MockTaskRepository) Update(arg0 context.Context, arg1 *models.Task, arg2 *models.Notification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockTaskRepositoryMockRecorder) Update(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTaskRepository)(nil).Update), arg0, arg1, arg2)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockTaskRepository) UpdateStatus(arg0 context.Context, arg1 string, arg2 models.TaskStatus, arg3 *models.Notification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockTaskRepositoryMockRecorder) UpdateStatus(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockTaskRepository)(nil).UpdateStatus), arg0, arg1, arg2, arg3)
+}
+
+// UpdateStatusAndOutput mocks base method.
+func (m *MockTaskRepository) UpdateStatusAndOutput(arg0 context.Context, arg1 string, arg2 models.TaskStatus, arg3 map[string]interface{}, arg4 *string, arg5 *models.Notification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatusAndOutput", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatusAndOutput indicates an expected call of UpdateStatusAndOutput.
+func (mr *MockTaskRepositoryMockRecorder) UpdateStatusAndOutput(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatusAndOutput", reflect.TypeOf((*MockTaskRepository)(nil).UpdateStatusAndOutput), arg0, arg1, arg2, arg3, arg4, arg5)
+}
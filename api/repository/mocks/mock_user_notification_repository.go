@@ -0,0 +1,97 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/repository (interfaces: UserNotificationRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+	repository "github.com/kazemisoroush/code-refactoring-tool/api/repository"
+)
+
+// MockUserNotificationRepository is a mock of UserNotificationRepository interface.
+type MockUserNotificationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserNotificationRepositoryMockRecorder
+}
+
+// MockUserNotificationRepositoryMockRecorder is the mock recorder for MockUserNotificationRepository.
+type MockUserNotificationRepositoryMockRecorder struct {
+	mock *MockUserNotificationRepository
+}
+
+// NewMockUserNotificationRepository creates a new mock instance.
+func NewMockUserNotificationRepository(ctrl *gomock.Controller) *MockUserNotificationRepository {
+	mock := &MockUserNotificationRepository{ctrl: ctrl}
+	mock.recorder = &MockUserNotificationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserNotificationRepository) EXPECT() *MockUserNotificationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountUnread mocks base method.
+func (m *MockUserNotificationRepository) CountUnread(arg0 context.Context, arg1 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnread", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnread indicates an expected call of CountUnread.
+func (mr *MockUserNotificationRepositoryMockRecorder) CountUnread(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnread", reflect.TypeOf((*MockUserNotificationRepository)(nil).CountUnread), arg0, arg1)
+}
+
+// CreateNotification mocks base method.
+func (m *MockUserNotificationRepository) CreateNotification(arg0 context.Context, arg1 *models.UserNotification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNotification", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateNotification indicates an expected call of CreateNotification.
+func (mr *MockUserNotificationRepositoryMockRecorder) CreateNotification(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNotification", reflect.TypeOf((*MockUserNotificationRepository)(nil).CreateNotification), arg0, arg1)
+}
+
+// ListNotifications mocks base method.
+func (m *MockUserNotificationRepository) ListNotifications(arg0 context.Context, arg1 string, arg2 repository.ListUserNotificationsOptions) ([]models.UserNotification, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNotifications", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.UserNotification)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListNotifications indicates an expected call of ListNotifications.
+func (mr *MockUserNotificationRepositoryMockRecorder) ListNotifications(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNotifications", reflect.TypeOf((*MockUserNotificationRepository)(nil).ListNotifications), arg0, arg1, arg2)
+}
+
+// MarkRead mocks base method.
+func (m *MockUserNotificationRepository) MarkRead(arg0 context.Context, arg1, arg2 string, arg3 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRead", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkRead indicates an expected call of MarkRead.
+func (mr *MockUserNotificationRepositoryMockRecorder) MarkRead(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRead", reflect.TypeOf((*MockUserNotificationRepository)(nil).MarkRead), arg0, arg1, arg2, arg3)
+}
@@ -110,35 +110,6 @@ func (mr *MockUserRepositoryMockRecorder) GetUserByEmail(ctx, email interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockUserRepository)(nil).GetUserByEmail), ctx, email)
 }
 
-// GrantProjectAccess mocks base method.
-func (m *MockUserRepository) GrantProjectAccess(ctx context.Context, userID, projectID, accessType string) error {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GrantProjectAccess", ctx, userID, projectID, accessType)
-	ret0, _ := ret[0].(error)
-	return ret0
-}
-
-// GrantProjectAccess indicates an expected call of GrantProjectAccess.
-func (mr *MockUserRepositoryMockRecorder) GrantProjectAccess(ctx, userID, projectID, accessType interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GrantProjectAccess", reflect.TypeOf((*MockUserRepository)(nil).GrantProjectAccess), ctx, userID, projectID, accessType)
-}
-
-// HasProjectAccess mocks base method.
-func (m *MockUserRepository) HasProjectAccess(ctx context.Context, userID, projectID, accessType string) (bool, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "HasProjectAccess", ctx, userID, projectID, accessType)
-	ret0, _ := ret[0].(bool)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
-
-// HasProjectAccess indicates an expected call of HasProjectAccess.
-func (mr *MockUserRepositoryMockRecorder) HasProjectAccess(ctx, userID, projectID, accessType interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasProjectAccess", reflect.TypeOf((*MockUserRepository)(nil).HasProjectAccess), ctx, userID, projectID, accessType)
-}
-
 // ListUsers mocks base method.
 func (m *MockUserRepository) ListUsers(ctx context.Context, filter *repository.ListUsersFilter) ([]*models.DBUser, int, error) {
 	m.ctrl.T.Helper()
@@ -155,18 +126,18 @@ func (mr *MockUserRepositoryMockRecorder) ListUsers(ctx, filter interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockUserRepository)(nil).ListUsers), ctx, filter)
 }
 
-// RevokeProjectAccess mocks base method.
-func (m *MockUserRepository) RevokeProjectAccess(ctx context.Context, userID, projectID string) error {
+// StreamUsers mocks base method.
+func (m *MockUserRepository) StreamUsers(ctx context.Context, timeRange repository.TimeRange, fn func(*models.DBUser) error) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RevokeProjectAccess", ctx, userID, projectID)
+	ret := m.ctrl.Call(m, "StreamUsers", ctx, timeRange, fn)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// RevokeProjectAccess indicates an expected call of RevokeProjectAccess.
-func (mr *MockUserRepositoryMockRecorder) RevokeProjectAccess(ctx, userID, projectID interface{}) *gomock.Call {
+// StreamUsers indicates an expected call of StreamUsers.
+func (mr *MockUserRepositoryMockRecorder) StreamUsers(ctx, timeRange, fn interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeProjectAccess", reflect.TypeOf((*MockUserRepository)(nil).RevokeProjectAccess), ctx, userID, projectID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamUsers", reflect.TypeOf((*MockUserRepository)(nil).StreamUsers), ctx, timeRange, fn)
 }
 
 // UpdateUser mocks base method.
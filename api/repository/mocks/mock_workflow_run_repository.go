@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/repository (interfaces: WorkflowRunRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	workflow "github.com/kazemisoroush/code-refactoring-tool/pkg/workflow"
+)
+
+// MockWorkflowRunRepository is a mock of WorkflowRunRepository interface.
+type MockWorkflowRunRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWorkflowRunRepositoryMockRecorder
+}
+
+// MockWorkflowRunRepositoryMockRecorder is the mock recorder for MockWorkflowRunRepository.
+type MockWorkflowRunRepositoryMockRecorder struct {
+	mock *MockWorkflowRunRepository
+}
+
+// NewMockWorkflowRunRepository creates a new mock instance.
+func NewMockWorkflowRunRepository(ctrl *gomock.Controller) *MockWorkflowRunRepository {
+	mock := &MockWorkflowRunRepository{ctrl: ctrl}
+	mock.recorder = &MockWorkflowRunRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWorkflowRunRepository) EXPECT() *MockWorkflowRunRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetRun mocks base method.
+func (m *MockWorkflowRunRepository) GetRun(arg0 context.Context, arg1 string) (*workflow.Run, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRun", arg0, arg1)
+	ret0, _ := ret[0].(*workflow.Run)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRun indicates an expected call of GetRun.
+func (mr *MockWorkflowRunRepositoryMockRecorder) GetRun(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRun", reflect.TypeOf((*MockWorkflowRunRepository)(nil).GetRun), arg0, arg1)
+}
+
+// ListRuns mocks base method.
+func (m *MockWorkflowRunRepository) ListRuns(arg0 context.Context, arg1 string, arg2 workflow.RunStatus) ([]*workflow.Run, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRuns", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*workflow.Run)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRuns indicates an expected call of ListRuns.
+func (mr *MockWorkflowRunRepositoryMockRecorder) ListRuns(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRuns", reflect.TypeOf((*MockWorkflowRunRepository)(nil).ListRuns), arg0, arg1, arg2)
+}
+
+// SaveRun mocks base method.
+func (m *MockWorkflowRunRepository) SaveRun(arg0 context.Context, arg1 *workflow.Run) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveRun", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveRun indicates an expected call of SaveRun.
+func (mr *MockWorkflowRunRepositoryMockRecorder) SaveRun(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRun", reflect.TypeOf((*MockWorkflowRunRepository)(nil).SaveRun), arg0, arg1)
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// NotificationChannelRepository defines the interface for email and Slack notification channel data operations
+//
+//go:generate mockgen -destination=./mocks/mock_notification_channel_repository.go -mock_names=NotificationChannelRepository=MockNotificationChannelRepository -package=mocks . NotificationChannelRepository
+type NotificationChannelRepository interface {
+	// CreateChannel stores a new notification channel
+	CreateChannel(ctx context.Context, channel *models.NotificationChannel) error
+
+	// GetChannel retrieves a notification channel by ID
+	GetChannel(ctx context.Context, channelID string) (*models.NotificationChannel, error)
+
+	// UpdateChannel replaces an existing notification channel
+	UpdateChannel(ctx context.Context, channel *models.NotificationChannel) error
+
+	// DeleteChannel deletes a notification channel by ID
+	DeleteChannel(ctx context.Context, channelID string) error
+
+	// ListChannelsByUser lists the channels a user created, optionally restricted to a project
+	ListChannelsByUser(ctx context.Context, userID, projectID string) ([]models.NotificationChannel, error)
+
+	// ListSubscribedChannels lists the enabled channels subscribed to the event that cover the project.
+	// Channels without a project cover every project; an empty projectID matches only those.
+	ListSubscribedChannels(ctx context.Context, event models.NotificationEvent, projectID string) ([]models.NotificationChannel, error)
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// NotificationOutboxRepository defines the interface for the outbox of notifications awaiting delivery
+//
+//go:generate mockgen -destination=./mocks/mock_notification_outbox_repository.go -mock_names=NotificationOutboxRepository=MockNotificationOutboxRepository -package=mocks . NotificationOutboxRepository
+type NotificationOutboxRepository interface {
+	// Enqueue records a notification for delivery, for events that aren't raised by a change of another entity
+	Enqueue(ctx context.Context, notification models.Notification) error
+
+	// ClaimDue claims up to limit undelivered events whose next attempt is due, oldest first, counting the attempt.
+	// Claimed events are hidden from other dispatchers for lease, after which an unfinished delivery is retried.
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]models.OutboxEvent, error)
+
+	// MarkDelivered records that an event was delivered
+	MarkDelivered(ctx context.Context, eventID string) error
+
+	// MarkFailed records a failed delivery attempt and when the event is retried
+	MarkFailed(ctx context.Context, eventID string, deliveryErr string, retryAt time.Time) error
+
+	// DeleteDelivered deletes the events delivered before the given time, returning how many were deleted
+	DeleteDelivered(ctx context.Context, before time.Time) (int64, error)
+}
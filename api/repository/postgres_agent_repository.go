@@ -10,6 +10,7 @@ import (
 
 	"github.com/lib/pq"
 
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
 	"github.com/kazemisoroush/code-refactoring-tool/api/models"
 	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
 )
@@ -22,6 +23,12 @@ type PostgresConfig struct {
 	Username string
 	Password string
 	SSLMode  string
+
+	// Read replica serving the queries of operations hinted read-only, none when ReplicaHost is empty. ReplicaPort
+	// defaults to Port, and the primary serves the read-only queries for ReplicaRetryInterval after the replica fails.
+	ReplicaHost          string
+	ReplicaPort          int
+	ReplicaRetryInterval time.Duration
 }
 
 // PostgresAgentRepository implements AgentRepository using PostgreSQL
@@ -56,12 +63,6 @@ func NewPostgresAgentRepository(config PostgresConfig, tableName string) (AgentR
 		tableName: tableName,
 	}
 
-	// Create table if it doesn't exist
-	if err := repo.CreateAgentsTable(context.Background()); err != nil {
-		_ = db.Close()
-		return nil, fmt.Errorf("failed to create agents table: %w", err)
-	}
-
 	return repo, nil
 }
 
@@ -91,8 +92,8 @@ func (r *PostgresAgentRepository) CreateAgent(ctx context.Context, agent *AgentR
 	query := fmt.Sprintf(`
 		INSERT INTO %s (
 			agent_id, agent_version, knowledge_base_id, vector_store_id,
-			repository_url, branch, agent_name, status, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			repository_url, branch, agent_name, status, created_at, updated_at, project_id, region, embedding_model, embedding_dimensions
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NULLIF($11, ''), NULLIF($12, ''), NULLIF($13, ''), NULLIF($14, 0))
 	`, r.tableName)
 
 	_, err := r.db.ExecContext(ctx, query,
@@ -106,11 +107,15 @@ func (r *PostgresAgentRepository) CreateAgent(ctx context.Context, agent *AgentR
 		agent.Status,
 		agent.CreatedAt,
 		agent.UpdatedAt,
+		agent.ProjectID,
+		agent.Region,
+		agent.EmbeddingModel,
+		agent.EmbeddingDimensions,
 	)
 	if err != nil {
 		// Check for unique constraint violation
 		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
-			return fmt.Errorf("agent with ID %s already exists", agent.AgentID)
+			return apperrors.Conflict(apperrors.CodeAgentExists, "agent with ID %s already exists", agent.AgentID)
 		}
 		return fmt.Errorf("failed to create agent in PostgreSQL: %w", err)
 	}
@@ -122,14 +127,15 @@ func (r *PostgresAgentRepository) CreateAgent(ctx context.Context, agent *AgentR
 func (r *PostgresAgentRepository) GetAgent(ctx context.Context, agentID string) (*AgentRecord, error) {
 	query := fmt.Sprintf(`
 		SELECT agent_id, agent_version, knowledge_base_id, vector_store_id,
-			   repository_url, branch, agent_name, status, created_at, updated_at
+			   repository_url, branch, agent_name, status, created_at, updated_at, project_id, region, embedding_model, embedding_dimensions
 		FROM %s WHERE agent_id = $1
 	`, r.tableName)
 
 	row := r.db.QueryRowContext(ctx, query, agentID)
 
 	var agent AgentRecord
-	var branch, agentName sql.NullString
+	var branch, agentName, projectID, region, embeddingModel sql.NullString
+	var embeddingDimensions sql.NullInt32
 
 	err := row.Scan(
 		&agent.AgentID,
@@ -142,10 +148,14 @@ func (r *PostgresAgentRepository) GetAgent(ctx context.Context, agentID string)
 		&agent.Status,
 		&agent.CreatedAt,
 		&agent.UpdatedAt,
+		&projectID,
+		&region,
+		&embeddingModel,
+		&embeddingDimensions,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("agent not found: %s", agentID)
+			return nil, apperrors.NotFound(apperrors.CodeAgentNotFound, "agent not found: %s", agentID)
 		}
 		return nil, fmt.Errorf("failed to get agent from PostgreSQL: %w", err)
 	}
@@ -157,6 +167,18 @@ func (r *PostgresAgentRepository) GetAgent(ctx context.Context, agentID string)
 	if agentName.Valid {
 		agent.AgentName = agentName.String
 	}
+	if projectID.Valid {
+		agent.ProjectID = projectID.String
+	}
+	if region.Valid {
+		agent.Region = region.String
+	}
+	if embeddingModel.Valid {
+		agent.EmbeddingModel = embeddingModel.String
+	}
+	if embeddingDimensions.Valid {
+		agent.EmbeddingDimensions = embeddingDimensions.Int32
+	}
 
 	return &agent, nil
 }
@@ -174,7 +196,11 @@ func (r *PostgresAgentRepository) UpdateAgent(ctx context.Context, agent *AgentR
 			branch = $6,
 			agent_name = $7,
 			status = $8,
-			updated_at = $9
+			updated_at = $9,
+			project_id = NULLIF($10, ''),
+			region = NULLIF($11, ''),
+			embedding_model = NULLIF($12, ''),
+			embedding_dimensions = NULLIF($13, 0)
 		WHERE agent_id = $1
 	`, r.tableName)
 
@@ -188,6 +214,10 @@ func (r *PostgresAgentRepository) UpdateAgent(ctx context.Context, agent *AgentR
 		agent.AgentName,
 		agent.Status,
 		agent.UpdatedAt,
+		agent.ProjectID,
+		agent.Region,
+		agent.EmbeddingModel,
+		agent.EmbeddingDimensions,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update agent in PostgreSQL: %w", err)
@@ -199,7 +229,7 @@ func (r *PostgresAgentRepository) UpdateAgent(ctx context.Context, agent *AgentR
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("agent not found: %s", agent.AgentID)
+		return apperrors.NotFound(apperrors.CodeAgentNotFound, "agent not found: %s", agent.AgentID)
 	}
 
 	return nil
@@ -220,7 +250,7 @@ func (r *PostgresAgentRepository) DeleteAgent(ctx context.Context, agentID strin
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("agent not found: %s", agentID)
+		return apperrors.NotFound(apperrors.CodeAgentNotFound, "agent not found: %s", agentID)
 	}
 
 	return nil
@@ -230,7 +260,7 @@ func (r *PostgresAgentRepository) DeleteAgent(ctx context.Context, agentID strin
 func (r *PostgresAgentRepository) ListAgents(ctx context.Context) ([]*AgentRecord, error) {
 	query := fmt.Sprintf(`
 		SELECT agent_id, agent_version, knowledge_base_id, vector_store_id,
-			   repository_url, branch, agent_name, status, created_at, updated_at
+			   repository_url, branch, agent_name, status, created_at, updated_at, project_id, region, embedding_model, embedding_dimensions
 		FROM %s ORDER BY created_at DESC
 	`, r.tableName)
 
@@ -247,7 +277,8 @@ func (r *PostgresAgentRepository) ListAgents(ctx context.Context) ([]*AgentRecor
 	var agents []*AgentRecord
 	for rows.Next() {
 		var agent AgentRecord
-		var branch, agentName sql.NullString
+		var branch, agentName, projectID, region, embeddingModel sql.NullString
+		var embeddingDimensions sql.NullInt32
 
 		err := rows.Scan(
 			&agent.AgentID,
@@ -260,6 +291,10 @@ func (r *PostgresAgentRepository) ListAgents(ctx context.Context) ([]*AgentRecor
 			&agent.Status,
 			&agent.CreatedAt,
 			&agent.UpdatedAt,
+			&projectID,
+			&region,
+			&embeddingModel,
+			&embeddingDimensions,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan agent record: %w", err)
@@ -272,6 +307,18 @@ func (r *PostgresAgentRepository) ListAgents(ctx context.Context) ([]*AgentRecor
 		if agentName.Valid {
 			agent.AgentName = agentName.String
 		}
+		if projectID.Valid {
+			agent.ProjectID = projectID.String
+		}
+		if region.Valid {
+			agent.Region = region.String
+		}
+		if embeddingModel.Valid {
+			agent.EmbeddingModel = embeddingModel.String
+		}
+		if embeddingDimensions.Valid {
+			agent.EmbeddingDimensions = embeddingDimensions.Int32
+		}
 
 		agents = append(agents, &agent)
 	}
@@ -300,52 +347,7 @@ func (r *PostgresAgentRepository) UpdateAgentStatus(ctx context.Context, agentID
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("agent not found: %s", agentID)
-	}
-
-	return nil
-}
-
-// CreateAgentsTable creates the agents table if it doesn't exist
-func (r *PostgresAgentRepository) CreateAgentsTable(ctx context.Context) error {
-	query := fmt.Sprintf(`
-		CREATE TABLE IF NOT EXISTS %s (
-			agent_id VARCHAR(255) PRIMARY KEY,
-			agent_version VARCHAR(255) NOT NULL,
-			knowledge_base_id VARCHAR(255) NOT NULL,
-			vector_store_id VARCHAR(255) NOT NULL,
-			repository_url TEXT NOT NULL,
-			branch VARCHAR(255),
-			agent_name VARCHAR(255),
-			status VARCHAR(50) NOT NULL,
-			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
-			updated_at TIMESTAMP WITH TIME ZONE NOT NULL
-		)
-	`, r.tableName)
-
-	_, err := r.db.ExecContext(ctx, query)
-	if err != nil {
-		return fmt.Errorf("failed to create agents table: %w", err)
-	}
-
-	// Create index on status for efficient filtering
-	indexQuery := fmt.Sprintf(`
-		CREATE INDEX IF NOT EXISTS idx_%s_status ON %s(status)
-	`, r.tableName, r.tableName)
-
-	_, err = r.db.ExecContext(ctx, indexQuery)
-	if err != nil {
-		return fmt.Errorf("failed to create status index: %w", err)
-	}
-
-	// Create index on created_at for efficient sorting
-	timeIndexQuery := fmt.Sprintf(`
-		CREATE INDEX IF NOT EXISTS idx_%s_created_at ON %s(created_at DESC)
-	`, r.tableName, r.tableName)
-
-	_, err = r.db.ExecContext(ctx, timeIndexQuery)
-	if err != nil {
-		return fmt.Errorf("failed to create created_at index: %w", err)
+		return apperrors.NotFound(apperrors.CodeAgentNotFound, "agent not found: %s", agentID)
 	}
 
 	return nil
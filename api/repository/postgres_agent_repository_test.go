@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
 )
 
 func TestNewPostgresAgentRepository(t *testing.T) {
@@ -119,6 +120,7 @@ func TestPostgresAgentRepository_CreateAgent(t *testing.T) {
 	now := time.Now().UTC()
 	agent := &AgentRecord{
 		AgentID:         "test-agent-id",
+		ProjectID:       "proj-123",
 		AgentVersion:    "v1.0.0",
 		KnowledgeBaseID: "kb-123",
 		VectorStoreID:   "vs-456",
@@ -143,6 +145,10 @@ func TestPostgresAgentRepository_CreateAgent(t *testing.T) {
 				agent.Status,
 				agent.CreatedAt,
 				agent.UpdatedAt,
+				agent.ProjectID,
+				agent.Region,
+				agent.EmbeddingModel,
+				agent.EmbeddingDimensions,
 			).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
@@ -165,6 +171,10 @@ func TestPostgresAgentRepository_CreateAgent(t *testing.T) {
 				agent.Status,
 				agent.CreatedAt,
 				agent.UpdatedAt,
+				agent.ProjectID,
+				agent.Region,
+				agent.EmbeddingModel,
+				agent.EmbeddingDimensions,
 			).
 			WillReturnError(pqErr)
 
@@ -189,10 +199,12 @@ func TestPostgresAgentRepository_GetAgent(t *testing.T) {
 	t.Run("successful retrieval", func(t *testing.T) {
 		rows := sqlmock.NewRows([]string{
 			"agent_id", "agent_version", "knowledge_base_id", "vector_store_id",
-			"repository_url", "branch", "agent_name", "status", "created_at", "updated_at",
+			"repository_url", "branch", "agent_name", "status", "created_at", "updated_at", "project_id", "region",
+			"embedding_model", "embedding_dimensions",
 		}).AddRow(
 			agentID, "v1.0.0", "kb-123", "vs-456",
-			"https://github.com/test/repo", "main", "Test Agent", "ready", now, now,
+			"https://github.com/test/repo", "main", "Test Agent", "ready", now, now, "proj-123", "eu-west-1",
+			"amazon.titan-embed-text-v2:0", 512,
 		)
 
 		mock.ExpectQuery(`SELECT .+ FROM agents WHERE agent_id`).
@@ -206,6 +218,9 @@ func TestPostgresAgentRepository_GetAgent(t *testing.T) {
 		assert.Equal(t, "v1.0.0", agent.AgentVersion)
 		assert.Equal(t, "main", agent.Branch)
 		assert.Equal(t, "Test Agent", agent.AgentName)
+		assert.Equal(t, "proj-123", agent.ProjectID)
+		assert.Equal(t, "eu-west-1", agent.Region)
+		assert.Equal(t, config.Embedding{Model: "amazon.titan-embed-text-v2:0", Dimensions: 512}, agent.Embedding())
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
@@ -224,10 +239,12 @@ func TestPostgresAgentRepository_GetAgent(t *testing.T) {
 	t.Run("with null optional fields", func(t *testing.T) {
 		rows := sqlmock.NewRows([]string{
 			"agent_id", "agent_version", "knowledge_base_id", "vector_store_id",
-			"repository_url", "branch", "agent_name", "status", "created_at", "updated_at",
+			"repository_url", "branch", "agent_name", "status", "created_at", "updated_at", "project_id", "region",
+			"embedding_model", "embedding_dimensions",
 		}).AddRow(
 			agentID, "v1.0.0", "kb-123", "vs-456",
-			"https://github.com/test/repo", nil, nil, "ready", now, now,
+			"https://github.com/test/repo", nil, nil, "ready", now, now, nil, nil,
+			nil, nil,
 		)
 
 		mock.ExpectQuery(`SELECT .+ FROM agents WHERE agent_id`).
@@ -239,6 +256,8 @@ func TestPostgresAgentRepository_GetAgent(t *testing.T) {
 		assert.NotNil(t, agent)
 		assert.Equal(t, "", agent.Branch)
 		assert.Equal(t, "", agent.AgentName)
+		assert.Equal(t, "", agent.ProjectID)
+		assert.True(t, agent.Embedding().IsZero())
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
@@ -277,6 +296,10 @@ func TestPostgresAgentRepository_UpdateAgent(t *testing.T) {
 				agent.AgentName,
 				agent.Status,
 				sqlmock.AnyArg(), // updated_at will be set to current time
+				agent.ProjectID,
+				agent.Region,
+				agent.EmbeddingModel,
+				agent.EmbeddingDimensions,
 			).
 			WillReturnResult(sqlmock.NewResult(0, 1))
 
@@ -297,6 +320,10 @@ func TestPostgresAgentRepository_UpdateAgent(t *testing.T) {
 				agent.AgentName,
 				agent.Status,
 				sqlmock.AnyArg(),
+				agent.ProjectID,
+				agent.Region,
+				agent.EmbeddingModel,
+				agent.EmbeddingDimensions,
 			).
 			WillReturnResult(sqlmock.NewResult(0, 0))
 
@@ -351,10 +378,11 @@ func TestPostgresAgentRepository_ListAgents(t *testing.T) {
 	t.Run("successful listing", func(t *testing.T) {
 		rows := sqlmock.NewRows([]string{
 			"agent_id", "agent_version", "knowledge_base_id", "vector_store_id",
-			"repository_url", "branch", "agent_name", "status", "created_at", "updated_at",
+			"repository_url", "branch", "agent_name", "status", "created_at", "updated_at", "project_id", "region",
+			"embedding_model", "embedding_dimensions",
 		}).
-			AddRow("agent-1", "v1.0.0", "kb-1", "vs-1", "https://github.com/test/repo1", "main", "Agent 1", "ready", now, now).
-			AddRow("agent-2", "v1.0.0", "kb-2", "vs-2", "https://github.com/test/repo2", nil, nil, "processing", now, now)
+			AddRow("agent-1", "v1.0.0", "kb-1", "vs-1", "https://github.com/test/repo1", "main", "Agent 1", "ready", now, now, "proj-123", "eu-west-1", nil, nil).
+			AddRow("agent-2", "v1.0.0", "kb-2", "vs-2", "https://github.com/test/repo2", nil, nil, "processing", now, now, nil, nil, "amazon.titan-embed-text-v2:0", 256)
 
 		mock.ExpectQuery(`SELECT .+ FROM agents ORDER BY created_at DESC`).
 			WillReturnRows(rows)
@@ -370,6 +398,7 @@ func TestPostgresAgentRepository_ListAgents(t *testing.T) {
 		assert.Equal(t, "agent-2", agents[1].AgentID)
 		assert.Equal(t, "", agents[1].Branch)
 		assert.Equal(t, "", agents[1].AgentName)
+		assert.Equal(t, int32(256), agents[1].EmbeddingDimensions)
 
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
@@ -377,7 +406,8 @@ func TestPostgresAgentRepository_ListAgents(t *testing.T) {
 	t.Run("empty result", func(t *testing.T) {
 		rows := sqlmock.NewRows([]string{
 			"agent_id", "agent_version", "knowledge_base_id", "vector_store_id",
-			"repository_url", "branch", "agent_name", "status", "created_at", "updated_at",
+			"repository_url", "branch", "agent_name", "status", "created_at", "updated_at", "project_id", "region",
+			"embedding_model", "embedding_dimensions",
 		})
 
 		mock.ExpectQuery(`SELECT .+ FROM agents ORDER BY created_at DESC`).
@@ -421,23 +451,3 @@ func TestPostgresAgentRepository_UpdateAgentStatus(t *testing.T) {
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
-
-func TestPostgresAgentRepository_CreateAgentsTable(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	require.NoError(t, err)
-	defer func() { _ = db.Close() }()
-
-	repo := NewPostgresAgentRepositoryWithDB(db, "agents").(*PostgresAgentRepository)
-	ctx := context.Background()
-
-	mock.ExpectExec(`CREATE TABLE IF NOT EXISTS agents`).
-		WillReturnResult(sqlmock.NewResult(0, 0))
-	mock.ExpectExec(`CREATE INDEX IF NOT EXISTS idx_agents_status`).
-		WillReturnResult(sqlmock.NewResult(0, 0))
-	mock.ExpectExec(`CREATE INDEX IF NOT EXISTS idx_agents_created_at`).
-		WillReturnResult(sqlmock.NewResult(0, 0))
-
-	err = repo.CreateAgentsTable(ctx)
-	assert.NoError(t, err)
-	assert.NoError(t, mock.ExpectationsWereMet())
-}
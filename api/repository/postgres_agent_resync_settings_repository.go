@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// PostgresAgentResyncSettingsRepository implements AgentResyncSettingsRepository using PostgreSQL
+type PostgresAgentResyncSettingsRepository struct {
+	db        *sql.DB
+	tableName string
+}
+
+// NewPostgresAgentResyncSettingsRepository creates a new PostgreSQL agent resync settings repository
+func NewPostgresAgentResyncSettingsRepository(config PostgresConfig, tableName string) (AgentResyncSettingsRepository, error) {
+	if tableName == "" {
+		tableName = conf.DefaultAgentResyncSettingsTableName
+	}
+
+	// Build connection string
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	repo := &PostgresAgentResyncSettingsRepository{
+		db:        db,
+		tableName: tableName,
+	}
+
+	return repo, nil
+}
+
+// NewPostgresAgentResyncSettingsRepositoryWithDB creates a new PostgreSQL agent resync settings repository with an existing DB connection
+func NewPostgresAgentResyncSettingsRepositoryWithDB(db *sql.DB, tableName string) AgentResyncSettingsRepository {
+	if tableName == "" {
+		tableName = conf.DefaultAgentResyncSettingsTableName
+	}
+
+	return &PostgresAgentResyncSettingsRepository{
+		db:        db,
+		tableName: tableName,
+	}
+}
+
+// GetSettings retrieves the agent resync settings of a project
+func (r *PostgresAgentResyncSettingsRepository) GetSettings(ctx context.Context, projectID string) (*models.AgentResyncSettings, error) {
+	query := fmt.Sprintf(`SELECT project_id, enabled, updated_at FROM %s WHERE project_id = $1`, r.tableName)
+
+	var settings models.AgentResyncSettings
+	err := r.db.QueryRowContext(ctx, query, projectID).Scan(&settings.ProjectID, &settings.Enabled, &settings.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get agent resync settings: %w", err)
+	}
+
+	return &settings, nil
+}
+
+// SaveSettings creates or replaces the agent resync settings of a project
+func (r *PostgresAgentResyncSettingsRepository) SaveSettings(ctx context.Context, settings *models.AgentResyncSettings) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (project_id, enabled, updated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (project_id) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			updated_at = EXCLUDED.updated_at
+	`, r.tableName)
+
+	_, err := r.db.ExecContext(ctx, query, settings.ProjectID, settings.Enabled, settings.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save agent resync settings: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// auditEventColumns lists the audit event columns in the order expected by scanAuditEvent
+const auditEventColumns = `event_id, actor_id, action, target_type, target_id, project_id, details, occurred_at`
+
+// PostgresAuditEventRepository implements AuditEventRepository using PostgreSQL
+type PostgresAuditEventRepository struct {
+	db        *sql.DB
+	reads     *readRouter
+	tableName string
+}
+
+// NewPostgresAuditEventRepository creates a new PostgreSQL audit event repository
+func NewPostgresAuditEventRepository(config PostgresConfig, tableName string) (AuditEventRepository, error) {
+	if tableName == "" {
+		tableName = conf.DefaultAuditEventsTableName
+	}
+
+	// Build connection string
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	replica, err := openReadReplica(config)
+	if err != nil {
+		return nil, err
+	}
+
+	repo := &PostgresAuditEventRepository{
+		db:        db,
+		reads:     newReadRouter(db, replica, config.ReplicaRetryInterval),
+		tableName: tableName,
+	}
+
+	return repo, nil
+}
+
+// NewPostgresAuditEventRepositoryWithDB creates a new PostgreSQL audit event repository with an existing DB connection
+func NewPostgresAuditEventRepositoryWithDB(db *sql.DB, tableName string) AuditEventRepository {
+	if tableName == "" {
+		tableName = conf.DefaultAuditEventsTableName
+	}
+
+	return &PostgresAuditEventRepository{
+		db:        db,
+		reads:     newReadRouter(db, nil, 0),
+		tableName: tableName,
+	}
+}
+
+// RecordEvent appends an event to the audit log
+func (r *PostgresAuditEventRepository) RecordEvent(ctx context.Context, event *models.AuditEvent) error {
+	details := event.Details
+	if details == nil {
+		details = map[string]string{}
+	}
+	detailsJSON, err := json.Marshal(details)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit event details: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (%s)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, r.tableName, auditEventColumns)
+
+	_, err = r.db.ExecContext(ctx, query,
+		event.EventID, event.ActorID, event.Action, event.TargetType, event.TargetID, event.ProjectID, detailsJSON,
+		event.OccurredAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record audit event: %w", err)
+	}
+
+	return nil
+}
+
+// StreamEvents passes the events that occurred within timeRange to fn, oldest first, reading them from the database
+// as fn consumes them. Operations hinted read-only are served by the read replica.
+func (r *PostgresAuditEventRepository) StreamEvents(ctx context.Context, timeRange TimeRange, fn func(models.AuditEvent) error) error {
+	where, args := timeRange.whereClause("occurred_at")
+	query := fmt.Sprintf(`SELECT %s FROM %s %s ORDER BY occurred_at, event_id`, auditEventColumns, r.tableName, where)
+
+	rows, err := r.reads.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to stream audit events: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close audit event rows", "error", closeErr)
+		}
+	}()
+
+	for rows.Next() {
+		event, err := scanAuditEvent(rows)
+		if err != nil {
+			return fmt.Errorf("failed to scan audit event: %w", err)
+		}
+		if err := fn(*event); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate audit events: %w", err)
+	}
+
+	return nil
+}
+
+// scanAuditEvent scans a single row selected with auditEventColumns
+func scanAuditEvent(row rowScanner) (*models.AuditEvent, error) {
+	var event models.AuditEvent
+	var detailsJSON []byte
+
+	err := row.Scan(
+		&event.EventID, &event.ActorID, &event.Action, &event.TargetType, &event.TargetID, &event.ProjectID,
+		&detailsJSON, &event.OccurredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(detailsJSON, &event.Details); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal audit event details: %w", err)
+	}
+
+	return &event, nil
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+func TestPostgresAuditEventRepository_RecordEvent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresAuditEventRepositoryWithDB(db, "audit_events")
+	occurredAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	mock.ExpectExec(`INSERT INTO audit_events`).
+		WithArgs("audit-1", "auth-1", models.AuditActionMemberAdded, "user", "usr-1", "proj-1",
+			[]byte(`{"role":"developer"}`), occurredAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.RecordEvent(context.Background(), &models.AuditEvent{
+		EventID: "audit-1", ActorID: "auth-1", Action: models.AuditActionMemberAdded, TargetType: "user",
+		TargetID: "usr-1", ProjectID: "proj-1", Details: map[string]string{"role": "developer"}, OccurredAt: occurredAt,
+	})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresAuditEventRepository_StreamEvents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresAuditEventRepositoryWithDB(db, "audit_events")
+	from := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)
+	occurredAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	columns := []string{"event_id", "actor_id", "action", "target_type", "target_id", "project_id", "details", "occurred_at"}
+	mock.ExpectQuery(`SELECT .+ FROM audit_events WHERE occurred_at >= \$1 AND occurred_at < \$2 ORDER BY occurred_at, event_id`).
+		WithArgs(from, to).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("audit-1", "auth-1", "role.created", "role", "security-reviewer", "", []byte(`{"permissions":"task:read"}`), occurredAt).
+			AddRow("audit-2", "auth-1", "role.deleted", "role", "security-reviewer", "", []byte(`{}`), occurredAt))
+
+	var events []models.AuditEvent
+	err = repo.StreamEvents(context.Background(), TimeRange{From: &from, To: &to}, func(event models.AuditEvent) error {
+		events = append(events, event)
+		return nil
+	})
+
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, models.AuditActionRoleCreated, events[0].Action)
+	assert.Equal(t, "task:read", events[0].Details["permissions"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresAuditEventRepository_StreamEvents_Unbounded(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresAuditEventRepositoryWithDB(db, "audit_events")
+
+	mock.ExpectQuery(`SELECT .+ FROM audit_events\s+ORDER BY occurred_at, event_id`).
+		WithoutArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"event_id"}))
+
+	err = repo.StreamEvents(context.Background(), TimeRange{}, func(models.AuditEvent) error { return nil })
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
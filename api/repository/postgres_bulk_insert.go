@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// DefaultBulkInsertBatchSize is the number of rows written per statement by the bulk inserts when none is configured
+const DefaultBulkInsertBatchSize = 500
+
+// maxBindParameters is the most bind parameters PostgreSQL accepts in a single statement
+const maxBindParameters = 65535
+
+// bulkInsert inserts rows into tableName with multi-row INSERT statements of at most batchSize rows, so a large
+// result set takes one round trip per batch rather than per row. Each row holds one value per column of columns, a
+// comma separated column list. Batches are capped to stay under the bind parameter limit of PostgreSQL.
+func bulkInsert(ctx context.Context, exec execer, tableName, columns string, rows [][]any, batchSize int) error {
+	return bulkUpsert(ctx, exec, tableName, columns, rows, batchSize, "")
+}
+
+// bulkUpsert is bulkInsert with an ON CONFLICT clause appended to every statement, such as one updating the rows that
+// already exist. A statement can't update a row twice, so the rows must not conflict with one another.
+func bulkUpsert(ctx context.Context, exec execer, tableName, columns string, rows [][]any, batchSize int, onConflict string) error {
+	columnCount := strings.Count(columns, ",") + 1
+	if batchSize <= 0 {
+		batchSize = DefaultBulkInsertBatchSize
+	}
+	batchSize = min(batchSize, maxBindParameters/columnCount)
+
+	for start := 0; start < len(rows); start += batchSize {
+		batch := rows[start:min(start+batchSize, len(rows))]
+
+		var query strings.Builder
+		fmt.Fprintf(&query, "INSERT INTO %s (%s) VALUES ", tableName, columns)
+		args := make([]any, 0, len(batch)*columnCount)
+		for i, row := range batch {
+			if len(row) != columnCount {
+				return fmt.Errorf("row has %d values for %d columns", len(row), columnCount)
+			}
+			if i > 0 {
+				query.WriteString(", ")
+			}
+			query.WriteString("(")
+			for j := range row {
+				if j > 0 {
+					query.WriteString(", ")
+				}
+				fmt.Fprintf(&query, "$%d", len(args)+j+1)
+			}
+			query.WriteString(")")
+			args = append(args, row...)
+		}
+		if onConflict != "" {
+			query.WriteString(" ")
+			query.WriteString(onConflict)
+		}
+
+		if _, err := exec.ExecContext(ctx, query.String(), args...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
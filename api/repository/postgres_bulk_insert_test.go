@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingExecer counts the statements it executes and their values
+type countingExecer struct {
+	statements int
+	values     int
+}
+
+func (e *countingExecer) ExecContext(_ context.Context, _ string, args ...any) (sql.Result, error) {
+	e.statements++
+	e.values += len(args)
+	return sqlmock.NewResult(0, 0), nil
+}
+
+func TestBulkInsert_SplitsRowsIntoBatches(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	rows := [][]any{{"a", 1}, {"b", 2}, {"c", 3}, {"d", 4}, {"e", 5}}
+
+	mock.ExpectExec(`^INSERT INTO items \(name, position\) VALUES \(\$1, \$2\), \(\$3, \$4\)$`).
+		WithArgs("a", 1, "b", 2).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`^INSERT INTO items \(name, position\) VALUES \(\$1, \$2\), \(\$3, \$4\)$`).
+		WithArgs("c", 3, "d", 4).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`^INSERT INTO items \(name, position\) VALUES \(\$1, \$2\)$`).
+		WithArgs("e", 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = bulkInsert(context.Background(), db, "items", "name, position", rows, 2)
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBulkInsert_CapsBatchesAtBindParameterLimit(t *testing.T) {
+	exec := &countingExecer{}
+	rows := make([][]any, 5000)
+	for i := range rows {
+		rows[i] = make([]any, 14)
+	}
+
+	err := bulkInsert(context.Background(), exec, "items", dependencyFindingColumns, rows, 10000)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, exec.statements) // 65535 parameters fit 4681 rows of 14 columns
+	assert.Equal(t, 5000*14, exec.values)
+}
+
+func TestBulkInsert_RejectsRowsMissingValues(t *testing.T) {
+	exec := &countingExecer{}
+
+	err := bulkInsert(context.Background(), exec, "items", "name, position", [][]any{{"a", 1}, {"b"}}, 0)
+
+	assert.ErrorContains(t, err, "row has 1 values for 2 columns")
+	assert.Zero(t, exec.statements)
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// campaignColumns lists the campaign columns in the order expected by scanCampaign
+const campaignColumns = `campaign_id, name, instruction, type, agent_id, codebase_ids, max_parallel, created_by, created_at`
+
+// PostgresCampaignRepository implements CampaignRepository using PostgreSQL
+type PostgresCampaignRepository struct {
+	db        *sql.DB
+	tableName string
+}
+
+// NewPostgresCampaignRepository creates a new PostgreSQL campaign repository
+func NewPostgresCampaignRepository(config PostgresConfig, tableName string) (CampaignRepository, error) {
+	if tableName == "" {
+		tableName = conf.DefaultCampaignsTableName
+	}
+
+	// Build connection string
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	repo := &PostgresCampaignRepository{
+		db:        db,
+		tableName: tableName,
+	}
+
+	return repo, nil
+}
+
+// NewPostgresCampaignRepositoryWithDB creates a new PostgreSQL campaign repository with an existing DB connection
+func NewPostgresCampaignRepositoryWithDB(db *sql.DB, tableName string) CampaignRepository {
+	if tableName == "" {
+		tableName = conf.DefaultCampaignsTableName
+	}
+
+	return &PostgresCampaignRepository{
+		db:        db,
+		tableName: tableName,
+	}
+}
+
+// CreateCampaign stores a new campaign
+func (r *PostgresCampaignRepository) CreateCampaign(ctx context.Context, campaign *models.Campaign) error {
+	codebaseIDsJSON, err := json.Marshal(campaign.CodebaseIDs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal campaign codebase IDs: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (%s)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, r.tableName, campaignColumns)
+
+	_, err = r.db.ExecContext(ctx, query,
+		campaign.CampaignID, campaign.Name, campaign.Instruction, campaign.Type, campaign.AgentID,
+		codebaseIDsJSON, campaign.MaxParallel, campaign.CreatedBy, campaign.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create campaign: %w", err)
+	}
+
+	return nil
+}
+
+// GetCampaign retrieves a campaign by ID
+func (r *PostgresCampaignRepository) GetCampaign(ctx context.Context, campaignID string) (*models.Campaign, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE campaign_id = $1`, campaignColumns, r.tableName)
+
+	campaign, err := scanCampaign(r.db.QueryRowContext(ctx, query, campaignID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.NotFound(apperrors.CodeCampaignNotFound, "campaign not found: %s", campaignID)
+		}
+		return nil, fmt.Errorf("failed to get campaign: %w", err)
+	}
+
+	return campaign, nil
+}
+
+// DeleteCampaign deletes a campaign
+func (r *PostgresCampaignRepository) DeleteCampaign(ctx context.Context, campaignID string) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE campaign_id = $1`, r.tableName)
+
+	result, err := r.db.ExecContext(ctx, query, campaignID)
+	if err != nil {
+		return fmt.Errorf("failed to delete campaign: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return apperrors.NotFound(apperrors.CodeCampaignNotFound, "campaign not found: %s", campaignID)
+	}
+
+	return nil
+}
+
+// scanCampaign scans a single row selected with campaignColumns
+func scanCampaign(row rowScanner) (*models.Campaign, error) {
+	var campaign models.Campaign
+	var codebaseIDsJSON []byte
+
+	err := row.Scan(
+		&campaign.CampaignID, &campaign.Name, &campaign.Instruction, &campaign.Type, &campaign.AgentID,
+		&codebaseIDsJSON, &campaign.MaxParallel, &campaign.CreatedBy, &campaign.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(codebaseIDsJSON, &campaign.CodebaseIDs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal codebase IDs JSON for campaign %s: %w", campaign.CampaignID, err)
+	}
+
+	return &campaign, nil
+}
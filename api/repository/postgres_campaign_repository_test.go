@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+func TestPostgresCampaignRepository_GetCampaign(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresCampaignRepositoryWithDB(db, "campaigns")
+	createdAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	columns := []string{"campaign_id", "name", "instruction", "type", "agent_id", "codebase_ids", "max_parallel", "created_by", "created_at"}
+	mock.ExpectQuery(`SELECT .+ FROM campaigns WHERE campaign_id = \$1`).
+		WithArgs("campaign-1").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("campaign-1", "Bump logging", "Bump the logging library", "refactoring", "agent-1", []byte(`["cb-1","cb-2"]`), 4, "user-1", createdAt))
+
+	campaign, err := repo.GetCampaign(context.Background(), "campaign-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cb-1", "cb-2"}, campaign.CodebaseIDs)
+	assert.Equal(t, models.TaskTypeRefactoring, campaign.Type)
+	assert.Equal(t, 4, campaign.MaxParallel)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresCampaignRepository_GetCampaign_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresCampaignRepositoryWithDB(db, "campaigns")
+
+	mock.ExpectQuery(`SELECT .+ FROM campaigns WHERE campaign_id = \$1`).
+		WithArgs("campaign-missing").
+		WillReturnRows(sqlmock.NewRows([]string{"campaign_id"}))
+
+	_, err = repo.GetCampaign(context.Background(), "campaign-missing")
+
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// PostgresClientTokenRepository implements ClientTokenRepository using PostgreSQL
+type PostgresClientTokenRepository struct {
+	db        *sql.DB
+	tableName string
+}
+
+// NewPostgresClientTokenRepository creates a new PostgreSQL client token repository
+func NewPostgresClientTokenRepository(config PostgresConfig, tableName string) (ClientTokenRepository, error) {
+	if tableName == "" {
+		tableName = conf.DefaultClientTokensTableName
+	}
+
+	// Build connection string
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	repo := &PostgresClientTokenRepository{
+		db:        db,
+		tableName: tableName,
+	}
+
+	return repo, nil
+}
+
+// NewPostgresClientTokenRepositoryWithDB creates a new PostgreSQL client token repository with an existing DB connection
+func NewPostgresClientTokenRepositoryWithDB(db *sql.DB, tableName string) ClientTokenRepository {
+	if tableName == "" {
+		tableName = conf.DefaultClientTokensTableName
+	}
+
+	return &PostgresClientTokenRepository{
+		db:        db,
+		tableName: tableName,
+	}
+}
+
+// ClaimToken records token unless its scope already has it, returning the record of the existing token
+func (r *PostgresClientTokenRepository) ClaimToken(ctx context.Context, token *models.ClientToken) (*models.ClientToken, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (scope, client_token, request_hash, resource_id, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (scope, client_token) DO NOTHING
+	`, r.tableName)
+
+	result, err := r.db.ExecContext(ctx, query, token.Scope, token.Token, token.RequestHash, token.ResourceID, token.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim client token: %w", err)
+	}
+	claimed, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if claimed > 0 {
+		return nil, nil
+	}
+
+	query = fmt.Sprintf(`
+		SELECT scope, client_token, request_hash, resource_id, created_at
+		FROM %s WHERE scope = $1 AND client_token = $2
+	`, r.tableName)
+
+	var existing models.ClientToken
+	err = r.db.QueryRowContext(ctx, query, token.Scope, token.Token).Scan(
+		&existing.Scope, &existing.Token, &existing.RequestHash, &existing.ResourceID, &existing.CreatedAt,
+	)
+	if err != nil {
+		// The token was released since it conflicted
+		if errors.Is(err, sql.ErrNoRows) {
+			return r.ClaimToken(ctx, token)
+		}
+		return nil, fmt.Errorf("failed to get client token: %w", err)
+	}
+
+	return &existing, nil
+}
+
+// CompleteToken records the resource created by the request carrying a token
+func (r *PostgresClientTokenRepository) CompleteToken(ctx context.Context, scope, token, resourceID string) error {
+	query := fmt.Sprintf(`UPDATE %s SET resource_id = $1 WHERE scope = $2 AND client_token = $3`, r.tableName)
+
+	if _, err := r.db.ExecContext(ctx, query, resourceID, scope, token); err != nil {
+		return fmt.Errorf("failed to complete client token: %w", err)
+	}
+
+	return nil
+}
+
+// ReleaseToken deletes a token, so that the request carrying it can be retried
+func (r *PostgresClientTokenRepository) ReleaseToken(ctx context.Context, scope, token string) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE scope = $1 AND client_token = $2`, r.tableName)
+
+	if _, err := r.db.ExecContext(ctx, query, scope, token); err != nil {
+		return fmt.Errorf("failed to release client token: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+func TestPostgresClientTokenRepository_ClaimToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresClientTokenRepositoryWithDB(db, "client_tokens")
+	createdAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+	token := &models.ClientToken{Scope: "project:usr-1", Token: "tf-1", RequestHash: "abc", CreatedAt: createdAt}
+
+	mock.ExpectExec(`INSERT INTO client_tokens .+ ON CONFLICT \(scope, client_token\) DO NOTHING`).
+		WithArgs("project:usr-1", "tf-1", "abc", nil, createdAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	existing, err := repo.ClaimToken(context.Background(), token)
+
+	require.NoError(t, err)
+	assert.Nil(t, existing)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresClientTokenRepository_ClaimToken_Existing(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresClientTokenRepositoryWithDB(db, "client_tokens")
+	createdAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+	token := &models.ClientToken{Scope: "project:usr-1", Token: "tf-1", RequestHash: "abc", CreatedAt: createdAt.Add(time.Hour)}
+
+	mock.ExpectExec(`INSERT INTO client_tokens`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT scope, client_token, request_hash, resource_id, created_at\s+FROM client_tokens WHERE scope = \$1 AND client_token = \$2`).
+		WithArgs("project:usr-1", "tf-1").
+		WillReturnRows(sqlmock.NewRows([]string{"scope", "client_token", "request_hash", "resource_id", "created_at"}).
+			AddRow("project:usr-1", "tf-1", "abc", "proj-1", createdAt))
+
+	existing, err := repo.ClaimToken(context.Background(), token)
+
+	require.NoError(t, err)
+	require.NotNil(t, existing)
+	require.NotNil(t, existing.ResourceID)
+	assert.Equal(t, "proj-1", *existing.ResourceID)
+	assert.Equal(t, createdAt, existing.CreatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// codeMetricsColumns lists the code metrics columns in the order expected by scanCodeMetricsSnapshot
+const codeMetricsColumns = `snapshot_id, codebase_id, task_id, commit_sha, files, functions, average_complexity, max_complexity,
+			   average_function_length, max_function_length, average_instability, maintainability_score,
+			   packages, hotspots, measured_at`
+
+// PostgresCodeMetricsRepository implements CodeMetricsRepository using PostgreSQL
+type PostgresCodeMetricsRepository struct {
+	db        *sql.DB
+	tableName string
+}
+
+// NewPostgresCodeMetricsRepository creates a new PostgreSQL code metrics repository
+func NewPostgresCodeMetricsRepository(config PostgresConfig, tableName string) (CodeMetricsRepository, error) {
+	if tableName == "" {
+		tableName = conf.DefaultCodeMetricsTableName
+	}
+
+	// Build connection string
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	repo := &PostgresCodeMetricsRepository{
+		db:        db,
+		tableName: tableName,
+	}
+
+	return repo, nil
+}
+
+// NewPostgresCodeMetricsRepositoryWithDB creates a new PostgreSQL code metrics repository with an existing DB connection
+func NewPostgresCodeMetricsRepositoryWithDB(db *sql.DB, tableName string) CodeMetricsRepository {
+	if tableName == "" {
+		tableName = conf.DefaultCodeMetricsTableName
+	}
+
+	return &PostgresCodeMetricsRepository{
+		db:        db,
+		tableName: tableName,
+	}
+}
+
+// SaveSnapshot stores a snapshot, replacing the one previously taken at the same commit of the codebase
+func (r *PostgresCodeMetricsRepository) SaveSnapshot(ctx context.Context, snapshot *models.CodeMetricsSnapshot) error {
+	packagesJSON, err := json.Marshal(snapshot.Packages)
+	if err != nil {
+		return fmt.Errorf("failed to marshal packages: %w", err)
+	}
+	hotspotsJSON, err := json.Marshal(snapshot.Hotspots)
+	if err != nil {
+		return fmt.Errorf("failed to marshal hotspots: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (%s)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		ON CONFLICT (codebase_id, commit_sha) DO UPDATE SET
+			snapshot_id = EXCLUDED.snapshot_id,
+			task_id = EXCLUDED.task_id,
+			files = EXCLUDED.files,
+			functions = EXCLUDED.functions,
+			average_complexity = EXCLUDED.average_complexity,
+			max_complexity = EXCLUDED.max_complexity,
+			average_function_length = EXCLUDED.average_function_length,
+			max_function_length = EXCLUDED.max_function_length,
+			average_instability = EXCLUDED.average_instability,
+			maintainability_score = EXCLUDED.maintainability_score,
+			packages = EXCLUDED.packages,
+			hotspots = EXCLUDED.hotspots,
+			measured_at = EXCLUDED.measured_at
+	`, r.tableName, codeMetricsColumns)
+
+	_, err = r.db.ExecContext(ctx, query,
+		snapshot.SnapshotID, snapshot.CodebaseID, snapshot.TaskID, snapshot.CommitSHA, snapshot.Files, snapshot.Functions,
+		snapshot.AverageComplexity, snapshot.MaxComplexity, snapshot.AverageFunctionLength, snapshot.MaxFunctionLength,
+		snapshot.AverageInstability, snapshot.MaintainabilityScore, packagesJSON, hotspotsJSON, snapshot.MeasuredAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save code metrics snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// ListSnapshots lists the snapshots of a codebase measured at or after since, oldest first
+func (r *PostgresCodeMetricsRepository) ListSnapshots(ctx context.Context, codebaseID string, since time.Time) ([]models.CodeMetricsSnapshot, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s
+		WHERE codebase_id = $1 AND measured_at >= $2
+		ORDER BY measured_at, snapshot_id
+	`, codeMetricsColumns, r.tableName)
+
+	rows, err := r.db.QueryContext(ctx, query, codebaseID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list code metrics snapshots: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close code metrics rows", "error", closeErr)
+		}
+	}()
+
+	snapshots := []models.CodeMetricsSnapshot{}
+	for rows.Next() {
+		snapshot, err := scanCodeMetricsSnapshot(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan code metrics snapshot: %w", err)
+		}
+		snapshots = append(snapshots, *snapshot)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate code metrics snapshots: %w", err)
+	}
+
+	return snapshots, nil
+}
+
+// scanCodeMetricsSnapshot scans a single row selected with codeMetricsColumns
+func scanCodeMetricsSnapshot(row rowScanner) (*models.CodeMetricsSnapshot, error) {
+	var snapshot models.CodeMetricsSnapshot
+	var packagesJSON, hotspotsJSON []byte
+
+	err := row.Scan(
+		&snapshot.SnapshotID, &snapshot.CodebaseID, &snapshot.TaskID, &snapshot.CommitSHA, &snapshot.Files, &snapshot.Functions,
+		&snapshot.AverageComplexity, &snapshot.MaxComplexity, &snapshot.AverageFunctionLength, &snapshot.MaxFunctionLength,
+		&snapshot.AverageInstability, &snapshot.MaintainabilityScore, &packagesJSON, &hotspotsJSON, &snapshot.MeasuredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(packagesJSON) > 0 {
+		if err := json.Unmarshal(packagesJSON, &snapshot.Packages); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal packages JSON for snapshot %s: %w", snapshot.SnapshotID, err)
+		}
+	}
+	if len(hotspotsJSON) > 0 {
+		if err := json.Unmarshal(hotspotsJSON, &snapshot.Hotspots); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal hotspots JSON for snapshot %s: %w", snapshot.SnapshotID, err)
+		}
+	}
+
+	return &snapshot, nil
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+func TestPostgresCodeMetricsRepository_SaveSnapshot(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresCodeMetricsRepositoryWithDB(db, "code_metrics")
+	measuredAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	mock.ExpectExec(`INSERT INTO code_metrics .+ ON CONFLICT \(codebase_id, commit_sha\) DO UPDATE SET`).
+		WithArgs("metrics-1", "codebase-1", "task-1", "abc123", 3, 12, 2.5, 9, 14.0, 40, 0.5, 91.67,
+			[]byte(`[{"path":"billing","afferent":1,"efferent":0,"instability":0}]`), []byte(`[]`), measuredAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.SaveSnapshot(context.Background(), &models.CodeMetricsSnapshot{
+		SnapshotID: "metrics-1", CodebaseID: "codebase-1", TaskID: "task-1", CommitSHA: "abc123",
+		Files: 3, Functions: 12, AverageComplexity: 2.5, MaxComplexity: 9, AverageFunctionLength: 14, MaxFunctionLength: 40,
+		AverageInstability: 0.5, MaintainabilityScore: 91.67,
+		Packages:   []models.PackageCouplingMetrics{{Path: "billing", Afferent: 1}},
+		Hotspots:   []models.FunctionComplexity{},
+		MeasuredAt: measuredAt,
+	})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresCodeMetricsRepository_ListSnapshots(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresCodeMetricsRepositoryWithDB(db, "code_metrics")
+	since := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	measuredAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	columns := []string{"snapshot_id", "codebase_id", "task_id", "commit_sha", "files", "functions", "average_complexity",
+		"max_complexity", "average_function_length", "max_function_length", "average_instability", "maintainability_score",
+		"packages", "hotspots", "measured_at"}
+	mock.ExpectQuery(`SELECT .+ FROM code_metrics\s+WHERE codebase_id = \$1 AND measured_at >= \$2\s+ORDER BY measured_at`).
+		WithArgs("codebase-1", since).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("metrics-1", "codebase-1", "task-1", "abc123", 3, 12, 2.5, 9, 14.0, 40, 0.5, 91.67,
+				[]byte(`[{"path":"billing","afferent":1,"efferent":0,"instability":0}]`),
+				[]byte(`[{"name":"Charge","file_path":"billing/charge.go","line":9,"complexity":9,"length":40}]`), measuredAt))
+
+	snapshots, err := repo.ListSnapshots(context.Background(), "codebase-1", since)
+
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+	assert.Equal(t, []models.PackageCouplingMetrics{{Path: "billing", Afferent: 1}}, snapshots[0].Packages)
+	assert.Equal(t, "Charge", snapshots[0].Hotspots[0].Name)
+	assert.Equal(t, 91.67, snapshots[0].MaintainabilityScore)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
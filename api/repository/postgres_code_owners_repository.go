@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// PostgresCodeOwnersRepository implements CodeOwnersRepository using PostgreSQL
+type PostgresCodeOwnersRepository struct {
+	db        *sql.DB
+	tableName string
+}
+
+// NewPostgresCodeOwnersRepository creates a new PostgreSQL code owners repository
+func NewPostgresCodeOwnersRepository(config PostgresConfig, tableName string) (CodeOwnersRepository, error) {
+	if tableName == "" {
+		tableName = conf.DefaultCodeOwnersTableName
+	}
+
+	// Build connection string
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	repo := &PostgresCodeOwnersRepository{
+		db:        db,
+		tableName: tableName,
+	}
+
+	return repo, nil
+}
+
+// NewPostgresCodeOwnersRepositoryWithDB creates a new PostgreSQL code owners repository with an existing DB connection
+func NewPostgresCodeOwnersRepositoryWithDB(db *sql.DB, tableName string) CodeOwnersRepository {
+	if tableName == "" {
+		tableName = conf.DefaultCodeOwnersTableName
+	}
+
+	return &PostgresCodeOwnersRepository{
+		db:        db,
+		tableName: tableName,
+	}
+}
+
+// GetCodeOwners retrieves the ownership of a codebase's files
+func (r *PostgresCodeOwnersRepository) GetCodeOwners(ctx context.Context, codebaseID string) (*models.CodeOwners, error) {
+	query := fmt.Sprintf(`SELECT codebase_id, file_path, commit_sha, rules, updated_at FROM %s WHERE codebase_id = $1`, r.tableName)
+
+	var owners models.CodeOwners
+	var rulesJSON []byte
+	err := r.db.QueryRowContext(ctx, query, codebaseID).Scan(&owners.CodebaseID, &owners.FilePath, &owners.CommitSHA, &rulesJSON, &owners.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get code owners: %w", err)
+	}
+
+	if err := json.Unmarshal(rulesJSON, &owners.Rules); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal code owner rules: %w", err)
+	}
+
+	return &owners, nil
+}
+
+// SaveCodeOwners creates or replaces the ownership of a codebase's files
+func (r *PostgresCodeOwnersRepository) SaveCodeOwners(ctx context.Context, owners *models.CodeOwners) error {
+	rules := owners.Rules
+	if rules == nil {
+		rules = []models.CodeOwnerRule{}
+	}
+	rulesJSON, err := json.Marshal(rules)
+	if err != nil {
+		return fmt.Errorf("failed to marshal code owner rules: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (codebase_id, file_path, commit_sha, rules, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (codebase_id) DO UPDATE SET
+			file_path = EXCLUDED.file_path,
+			commit_sha = EXCLUDED.commit_sha,
+			rules = EXCLUDED.rules,
+			updated_at = EXCLUDED.updated_at
+	`, r.tableName)
+
+	if _, err := r.db.ExecContext(ctx, query, owners.CodebaseID, owners.FilePath, owners.CommitSHA, rulesJSON, owners.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to save code owners: %w", err)
+	}
+
+	return nil
+}
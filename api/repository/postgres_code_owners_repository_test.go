@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+func TestPostgresCodeOwnersRepository_GetCodeOwners(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresCodeOwnersRepositoryWithDB(db, "code_owners")
+	updatedAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT codebase_id, file_path, commit_sha, rules, updated_at FROM code_owners WHERE codebase_id = \$1`).
+		WithArgs("codebase-1").
+		WillReturnRows(sqlmock.NewRows([]string{"codebase_id", "file_path", "commit_sha", "rules", "updated_at"}).
+			AddRow("codebase-1", ".github/CODEOWNERS", "abc123", []byte(`[{"pattern":"/billing/","owners":["@acme/billing"]}]`), updatedAt))
+
+	owners, err := repo.GetCodeOwners(context.Background(), "codebase-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, ".github/CODEOWNERS", owners.FilePath)
+	assert.Equal(t, []models.CodeOwnerRule{{Pattern: "/billing/", Owners: []string{"@acme/billing"}}}, owners.Rules)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresCodeOwnersRepository_GetCodeOwners_NotRecorded(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresCodeOwnersRepositoryWithDB(db, "code_owners")
+
+	mock.ExpectQuery(`SELECT .+ FROM code_owners`).
+		WithArgs("codebase-1").
+		WillReturnRows(sqlmock.NewRows([]string{"codebase_id", "file_path", "commit_sha", "rules", "updated_at"}))
+
+	owners, err := repo.GetCodeOwners(context.Background(), "codebase-1")
+
+	require.NoError(t, err)
+	assert.Nil(t, owners)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresCodeOwnersRepository_SaveCodeOwners_StoresEmptyRules(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresCodeOwnersRepositoryWithDB(db, "code_owners")
+	updatedAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	mock.ExpectExec(`INSERT INTO code_owners .+ ON CONFLICT \(codebase_id\) DO UPDATE`).
+		WithArgs("codebase-1", "", "abc123", []byte(`[]`), updatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.SaveCodeOwners(context.Background(), &models.CodeOwners{CodebaseID: "codebase-1", CommitSHA: "abc123", UpdatedAt: updatedAt})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
 	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
 	"github.com/lib/pq"
 )
@@ -43,11 +44,6 @@ func NewPostgresCodebaseConfigRepository(config PostgresConfig, tableName string
 		tableName: tableName,
 	}
 
-	// Create table if it doesn't exist
-	if err := repo.CreateTable(context.Background()); err != nil {
-		return nil, fmt.Errorf("failed to create table: %w", err)
-	}
-
 	return repo, nil
 }
 
@@ -88,8 +84,8 @@ func (r *PostgresCodebaseConfigRepository) CreateCodebaseConfig(ctx context.Cont
 	query := fmt.Sprintf(`
 		INSERT INTO %s (
 			config_id, name, description, provider, url,
-			created_at, updated_at, tags, config
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			created_at, updated_at, tags, config, version
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, 1)
 	`, r.tableName)
 
 	_, err = r.db.ExecContext(ctx, query,
@@ -106,11 +102,12 @@ func (r *PostgresCodebaseConfigRepository) CreateCodebaseConfig(ctx context.Cont
 	if err != nil {
 		// Check for unique constraint violation
 		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
-			return fmt.Errorf("codebase configuration with ID %s already exists", config.ConfigID)
+			return apperrors.Conflict(apperrors.CodeCodebaseConfigExists, "codebase configuration with ID %s already exists", config.ConfigID)
 		}
 		return fmt.Errorf("failed to create codebase configuration in PostgreSQL: %w", err)
 	}
 
+	config.Version = 1
 	return nil
 }
 
@@ -118,7 +115,7 @@ func (r *PostgresCodebaseConfigRepository) CreateCodebaseConfig(ctx context.Cont
 func (r *PostgresCodebaseConfigRepository) GetCodebaseConfig(ctx context.Context, configID string) (*CodebaseConfigRecord, error) {
 	query := fmt.Sprintf(`
 		SELECT config_id, name, description, provider, url,
-			   created_at, updated_at, tags, config
+			   created_at, updated_at, version, tags, config
 		FROM %s WHERE config_id = $1
 	`, r.tableName)
 
@@ -136,12 +133,13 @@ func (r *PostgresCodebaseConfigRepository) GetCodebaseConfig(ctx context.Context
 		&config.URL,
 		&config.CreatedAt,
 		&config.UpdatedAt,
+		&config.Version,
 		&tagsJSON,
 		&configJSON,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("codebase configuration not found")
+			return nil, apperrors.NotFound(apperrors.CodeCodebaseConfigNotFound, "codebase configuration not found")
 		}
 		return nil, fmt.Errorf("failed to get codebase configuration from PostgreSQL: %w", err)
 	}
@@ -179,14 +177,17 @@ func (r *PostgresCodebaseConfigRepository) UpdateCodebaseConfig(ctx context.Cont
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
+	// Compare-and-swap on the version so concurrent updates cannot overwrite each other
 	query := fmt.Sprintf(`
 		UPDATE %s SET 
 			name = $2, description = $3, provider = $4, url = $5,
-			updated_at = $6, tags = $7, config = $8
-		WHERE config_id = $1
+			updated_at = $6, tags = $7, config = $8, version = version + 1
+		WHERE config_id = $1 AND version = $9
+		RETURNING version
 	`, r.tableName)
 
-	result, err := r.db.ExecContext(ctx, query,
+	var version int64
+	err = r.db.QueryRowContext(ctx, query,
 		config.ConfigID,
 		config.Name,
 		config.Description,
@@ -195,20 +196,25 @@ func (r *PostgresCodebaseConfigRepository) UpdateCodebaseConfig(ctx context.Cont
 		config.UpdatedAt,
 		tagsJSON,
 		configJSON,
-	)
-	if err != nil {
-		return fmt.Errorf("failed to update codebase configuration in PostgreSQL: %w", err)
-	}
-
-	rowsAffected, err := result.RowsAffected()
+		config.Version,
+	).Scan(&version)
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
-	}
+		if err != sql.ErrNoRows {
+			return fmt.Errorf("failed to update codebase configuration in PostgreSQL: %w", err)
+		}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("codebase configuration with ID %s does not exist", config.ConfigID)
+		// No row matched: either the configuration is gone or its version moved on
+		exists, existsErr := r.CodebaseConfigExists(ctx, config.ConfigID)
+		if existsErr != nil {
+			return existsErr
+		}
+		if !exists {
+			return apperrors.NotFound(apperrors.CodeCodebaseConfigNotFound, "codebase configuration with ID %s does not exist", config.ConfigID)
+		}
+		return apperrors.PreconditionFailed(apperrors.CodeVersionMismatch, "codebase configuration with ID %s was modified since version %d", config.ConfigID, config.Version)
 	}
 
+	config.Version = version
 	return nil
 }
 
@@ -227,7 +233,7 @@ func (r *PostgresCodebaseConfigRepository) DeleteCodebaseConfig(ctx context.Cont
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("codebase configuration with ID %s does not exist", configID)
+		return apperrors.NotFound(apperrors.CodeCodebaseConfigNotFound, "codebase configuration with ID %s does not exist", configID)
 	}
 
 	return nil
@@ -254,7 +260,7 @@ func (r *PostgresCodebaseConfigRepository) ListCodebaseConfigs(ctx context.Conte
 	// Build the base query
 	query := fmt.Sprintf(`
 		SELECT config_id, name, description, provider, url,
-			   created_at, updated_at, tags, config
+			   created_at, updated_at, version, tags, config
 		FROM %s
 	`, r.tableName)
 
@@ -326,6 +332,7 @@ func (r *PostgresCodebaseConfigRepository) ListCodebaseConfigs(ctx context.Conte
 			&config.URL,
 			&config.CreatedAt,
 			&config.UpdatedAt,
+			&config.Version,
 			&tagsJSON,
 			&configJSON,
 		)
@@ -363,43 +370,3 @@ func (r *PostgresCodebaseConfigRepository) ListCodebaseConfigs(ctx context.Conte
 
 	return configs, nextToken, nil
 }
-
-// CreateTable creates the codebase_configs table if it doesn't exist
-func (r *PostgresCodebaseConfigRepository) CreateTable(ctx context.Context) error {
-	query := fmt.Sprintf(`
-		CREATE TABLE IF NOT EXISTS %s (
-			config_id VARCHAR(255) PRIMARY KEY,
-			name VARCHAR(255) NOT NULL,
-			description TEXT,
-			provider VARCHAR(50) NOT NULL,
-			url VARCHAR(2048) NOT NULL,
-			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
-			updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
-			tags JSONB DEFAULT '{}',
-			config JSONB NOT NULL
-		)
-	`, r.tableName)
-
-	_, err := r.db.ExecContext(ctx, query)
-	if err != nil {
-		return fmt.Errorf("failed to create codebase_configs table: %w", err)
-	}
-
-	// Create indexes for better performance
-	indexes := []string{
-		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_name ON %s (name)", r.tableName, r.tableName),
-		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_provider ON %s (provider)", r.tableName, r.tableName),
-		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_created_at ON %s (created_at)", r.tableName, r.tableName),
-		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_tags ON %s USING GIN (tags)", r.tableName, r.tableName),
-		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_url ON %s (url)", r.tableName, r.tableName),
-	}
-
-	for _, indexQuery := range indexes {
-		_, err := r.db.ExecContext(ctx, indexQuery)
-		if err != nil {
-			return fmt.Errorf("failed to create index: %w", err)
-		}
-	}
-
-	return nil
-}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// PostgresCodebaseHeadRepository implements CodebaseHeadRepository using PostgreSQL
+type PostgresCodebaseHeadRepository struct {
+	db        *sql.DB
+	tableName string
+}
+
+// NewPostgresCodebaseHeadRepository creates a new PostgreSQL codebase head repository
+func NewPostgresCodebaseHeadRepository(config PostgresConfig, tableName string) (CodebaseHeadRepository, error) {
+	if tableName == "" {
+		tableName = conf.DefaultCodebaseHeadsTableName
+	}
+
+	// Build connection string
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	repo := &PostgresCodebaseHeadRepository{
+		db:        db,
+		tableName: tableName,
+	}
+
+	return repo, nil
+}
+
+// NewPostgresCodebaseHeadRepositoryWithDB creates a new PostgreSQL codebase head repository with an existing DB connection
+func NewPostgresCodebaseHeadRepositoryWithDB(db *sql.DB, tableName string) CodebaseHeadRepository {
+	if tableName == "" {
+		tableName = conf.DefaultCodebaseHeadsTableName
+	}
+
+	return &PostgresCodebaseHeadRepository{
+		db:        db,
+		tableName: tableName,
+	}
+}
+
+// GetHead retrieves the tracked head of a codebase
+func (r *PostgresCodebaseHeadRepository) GetHead(ctx context.Context, codebaseID string) (*CodebaseHead, error) {
+	query := fmt.Sprintf(`SELECT codebase_id, commit_sha, changed_at, synced_sha, checked_at FROM %s WHERE codebase_id = $1`, r.tableName)
+
+	var head CodebaseHead
+	err := r.db.QueryRowContext(ctx, query, codebaseID).Scan(
+		&head.CodebaseID, &head.CommitSHA, &head.ChangedAt, &head.SyncedSHA, &head.CheckedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get codebase head: %w", err)
+	}
+
+	return &head, nil
+}
+
+// SaveHead creates or replaces the tracked head of a codebase
+func (r *PostgresCodebaseHeadRepository) SaveHead(ctx context.Context, head *CodebaseHead) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (codebase_id, commit_sha, changed_at, synced_sha, checked_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (codebase_id) DO UPDATE SET
+			commit_sha = EXCLUDED.commit_sha,
+			changed_at = EXCLUDED.changed_at,
+			synced_sha = EXCLUDED.synced_sha,
+			checked_at = EXCLUDED.checked_at
+	`, r.tableName)
+
+	_, err := r.db.ExecContext(ctx, query, head.CodebaseID, head.CommitSHA, head.ChangedAt, head.SyncedSHA, head.CheckedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save codebase head: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresCodebaseHeadRepository_SaveHead(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresCodebaseHeadRepositoryWithDB(db, "codebase_heads")
+	changedAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+	checkedAt := changedAt.Add(5 * time.Minute)
+
+	mock.ExpectExec(`INSERT INTO codebase_heads .+ ON CONFLICT \(codebase_id\) DO UPDATE`).
+		WithArgs("codebase-1", "def456", changedAt, "abc123", checkedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.SaveHead(context.Background(), &CodebaseHead{
+		CodebaseID: "codebase-1", CommitSHA: "def456", ChangedAt: changedAt, SyncedSHA: "abc123", CheckedAt: checkedAt,
+	})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresCodebaseHeadRepository_GetHead(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresCodebaseHeadRepositoryWithDB(db, "codebase_heads")
+	changedAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	columns := []string{"codebase_id", "commit_sha", "changed_at", "synced_sha", "checked_at"}
+	mock.ExpectQuery(`SELECT .+ FROM codebase_heads WHERE codebase_id = \$1`).
+		WithArgs("codebase-1").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("codebase-1", "def456", changedAt, "abc123", changedAt))
+
+	head, err := repo.GetHead(context.Background(), "codebase-1")
+
+	require.NoError(t, err)
+	require.NotNil(t, head)
+	assert.Equal(t, "def456", head.CommitSHA)
+	assert.True(t, head.Pending())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresCodebaseHeadRepository_GetHead_NeverPolled(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresCodebaseHeadRepositoryWithDB(db, "codebase_heads")
+
+	mock.ExpectQuery(`SELECT .+ FROM codebase_heads WHERE codebase_id = \$1`).
+		WithArgs("codebase-1").
+		WillReturnRows(sqlmock.NewRows([]string{"codebase_id"}))
+
+	head, err := repo.GetHead(context.Background(), "codebase-1")
+
+	require.NoError(t, err)
+	assert.Nil(t, head)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/lib/pq"
 
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
 	"github.com/kazemisoroush/code-refactoring-tool/api/models"
 	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
 )
@@ -46,47 +47,14 @@ func NewPostgresCodebaseRepository(config PostgresConfig, tableName string) (Cod
 		tableName: tableName,
 	}
 
-	// Create table if it doesn't exist
-	if err := repo.createTableIfNotExists(); err != nil {
-		_ = db.Close()
-		return nil, fmt.Errorf("failed to create table: %w", err)
-	}
-
 	return repo, nil
 }
 
-// createTableIfNotExists creates the codebases table if it doesn't exist
-func (r *PostgresCodebaseRepository) createTableIfNotExists() error {
-	query := fmt.Sprintf(`
-		CREATE TABLE IF NOT EXISTS %s (
-			codebase_id UUID PRIMARY KEY,
-			project_id VARCHAR(255) NOT NULL,
-			name VARCHAR(255) NOT NULL,
-			provider VARCHAR(50) NOT NULL,
-			url TEXT NOT NULL,
-			config_id VARCHAR(255) NOT NULL,
-			status VARCHAR(50) NOT NULL DEFAULT 'active',
-			last_sync_at TIMESTAMP WITH TIME ZONE,
-			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
-			updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
-			metadata JSONB,
-			tags JSONB,
-			CONSTRAINT fk_project FOREIGN KEY (project_id) REFERENCES projects(project_id) ON DELETE CASCADE
-		);
-		CREATE INDEX IF NOT EXISTS idx_codebases_project_id ON %s (project_id);
-		CREATE INDEX IF NOT EXISTS idx_codebases_provider ON %s (provider);
-		CREATE INDEX IF NOT EXISTS idx_codebases_created_at ON %s (created_at);
-	`, r.tableName, r.tableName, r.tableName, r.tableName)
-
-	_, err := r.db.Exec(query)
-	return err
-}
-
 // CreateCodebase creates a new codebase record
 func (r *PostgresCodebaseRepository) CreateCodebase(ctx context.Context, codebase *models.Codebase) error {
 	query := fmt.Sprintf(`
-		INSERT INTO %s (codebase_id, project_id, name, provider, url, config_id, status, last_sync_at, created_at, updated_at, metadata, tags)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		INSERT INTO %s (codebase_id, project_id, name, provider, url, config_id, status, last_sync_at, created_at, updated_at, metadata, tags, ingestion_error, expires_at, checkout)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 	`, r.tableName)
 
 	metadataJSON, err := json.Marshal(codebase.Metadata)
@@ -99,6 +67,11 @@ func (r *PostgresCodebaseRepository) CreateCodebase(ctx context.Context, codebas
 		return fmt.Errorf("failed to marshal tags: %w", err)
 	}
 
+	checkoutJSON, err := marshalCheckout(codebase.Checkout)
+	if err != nil {
+		return err
+	}
+
 	_, err = r.db.ExecContext(ctx, query,
 		codebase.CodebaseID,
 		codebase.ProjectID,
@@ -112,15 +85,18 @@ func (r *PostgresCodebaseRepository) CreateCodebase(ctx context.Context, codebas
 		codebase.UpdatedAt,
 		metadataJSON,
 		tagsJSON,
+		codebase.IngestionError,
+		codebase.ExpiresAt,
+		checkoutJSON,
 	)
 
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok {
 			switch pqErr.Code {
 			case "23505": // unique_violation
-				return fmt.Errorf("codebase with ID %s already exists", codebase.CodebaseID)
+				return apperrors.Conflict(apperrors.CodeCodebaseExists, "codebase with ID %s already exists", codebase.CodebaseID)
 			case "23503": // foreign_key_violation
-				return fmt.Errorf("project with ID %s does not exist", codebase.ProjectID)
+				return apperrors.NotFound(apperrors.CodeProjectNotFound, "project with ID %s does not exist", codebase.ProjectID)
 			}
 		}
 		return fmt.Errorf("failed to create codebase: %w", err)
@@ -132,13 +108,13 @@ func (r *PostgresCodebaseRepository) CreateCodebase(ctx context.Context, codebas
 // GetCodebase retrieves a codebase by ID
 func (r *PostgresCodebaseRepository) GetCodebase(ctx context.Context, codebaseID string) (*models.Codebase, error) {
 	query := fmt.Sprintf(`
-		SELECT codebase_id, project_id, name, provider, url, config_id, status, last_sync_at, created_at, updated_at, metadata, tags
+		SELECT codebase_id, project_id, name, provider, url, config_id, status, last_sync_at, created_at, updated_at, metadata, tags, ingestion_error, expires_at, checkout
 		FROM %s
 		WHERE codebase_id = $1
 	`, r.tableName)
 
 	var codebase models.Codebase
-	var metadataJSON, tagsJSON []byte
+	var metadataJSON, tagsJSON, checkoutJSON []byte
 
 	err := r.db.QueryRowContext(ctx, query, codebaseID).Scan(
 		&codebase.CodebaseID,
@@ -153,11 +129,14 @@ func (r *PostgresCodebaseRepository) GetCodebase(ctx context.Context, codebaseID
 		&codebase.UpdatedAt,
 		&metadataJSON,
 		&tagsJSON,
+		&codebase.IngestionError,
+		&codebase.ExpiresAt,
+		&checkoutJSON,
 	)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("codebase not found")
+			return nil, apperrors.NotFound(apperrors.CodeCodebaseNotFound, "codebase not found")
 		}
 		return nil, fmt.Errorf("failed to get codebase: %w", err)
 	}
@@ -173,6 +152,9 @@ func (r *PostgresCodebaseRepository) GetCodebase(ctx context.Context, codebaseID
 			return nil, fmt.Errorf("failed to unmarshal tags: %w", err)
 		}
 	}
+	if codebase.Checkout, err = unmarshalCheckout(checkoutJSON); err != nil {
+		return nil, err
+	}
 
 	return &codebase, nil
 }
@@ -181,7 +163,7 @@ func (r *PostgresCodebaseRepository) GetCodebase(ctx context.Context, codebaseID
 func (r *PostgresCodebaseRepository) UpdateCodebase(ctx context.Context, codebase *models.Codebase) error {
 	query := fmt.Sprintf(`
 		UPDATE %s
-		SET name = $2, config_id = $3, status = $4, last_sync_at = $5, updated_at = $6, metadata = $7, tags = $8
+		SET name = $2, config_id = $3, status = $4, last_sync_at = $5, updated_at = $6, metadata = $7, tags = $8, ingestion_error = $9, checkout = $10
 		WHERE codebase_id = $1
 	`, r.tableName)
 
@@ -195,6 +177,11 @@ func (r *PostgresCodebaseRepository) UpdateCodebase(ctx context.Context, codebas
 		return fmt.Errorf("failed to marshal tags: %w", err)
 	}
 
+	checkoutJSON, err := marshalCheckout(codebase.Checkout)
+	if err != nil {
+		return err
+	}
+
 	result, err := r.db.ExecContext(ctx, query,
 		codebase.CodebaseID,
 		codebase.Name,
@@ -204,6 +191,8 @@ func (r *PostgresCodebaseRepository) UpdateCodebase(ctx context.Context, codebas
 		codebase.UpdatedAt,
 		metadataJSON,
 		tagsJSON,
+		codebase.IngestionError,
+		checkoutJSON,
 	)
 
 	if err != nil {
@@ -216,7 +205,7 @@ func (r *PostgresCodebaseRepository) UpdateCodebase(ctx context.Context, codebas
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("codebase not found")
+		return apperrors.NotFound(apperrors.CodeCodebaseNotFound, "codebase not found")
 	}
 
 	return nil
@@ -237,7 +226,7 @@ func (r *PostgresCodebaseRepository) DeleteCodebase(ctx context.Context, codebas
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("codebase not found")
+		return apperrors.NotFound(apperrors.CodeCodebaseNotFound, "codebase not found")
 	}
 
 	return nil
@@ -251,7 +240,7 @@ func (r *PostgresCodebaseRepository) ListCodebases(ctx context.Context, filter C
 	argIndex := 1
 
 	baseQuery := fmt.Sprintf(`
-		SELECT codebase_id, project_id, name, provider, url, config_id, status, last_sync_at, created_at, updated_at, metadata, tags
+		SELECT codebase_id, project_id, name, provider, url, config_id, status, last_sync_at, created_at, updated_at, metadata, tags, ingestion_error, expires_at, checkout
 		FROM %s
 	`, r.tableName)
 
@@ -310,7 +299,7 @@ func (r *PostgresCodebaseRepository) ListCodebases(ctx context.Context, filter C
 
 	for rows.Next() {
 		var codebase models.Codebase
-		var metadataJSON, tagsJSON []byte
+		var metadataJSON, tagsJSON, checkoutJSON []byte
 
 		err := rows.Scan(
 			&codebase.CodebaseID,
@@ -325,6 +314,9 @@ func (r *PostgresCodebaseRepository) ListCodebases(ctx context.Context, filter C
 			&codebase.LastSyncAt,
 			&metadataJSON,
 			&tagsJSON,
+			&codebase.IngestionError,
+			&codebase.ExpiresAt,
+			&checkoutJSON,
 		)
 
 		if err != nil {
@@ -342,6 +334,9 @@ func (r *PostgresCodebaseRepository) ListCodebases(ctx context.Context, filter C
 				return nil, "", fmt.Errorf("failed to unmarshal tags: %w", err)
 			}
 		}
+		if codebase.Checkout, err = unmarshalCheckout(checkoutJSON); err != nil {
+			return nil, "", err
+		}
 
 		codebases = append(codebases, &codebase)
 	}
@@ -386,7 +381,7 @@ func (r *PostgresCodebaseRepository) CodebaseExists(ctx context.Context, codebas
 // GetCodebasesByProject gets all codebases for a specific project
 func (r *PostgresCodebaseRepository) GetCodebasesByProject(ctx context.Context, projectID string) ([]*models.Codebase, error) {
 	query := fmt.Sprintf(`
-		SELECT codebase_id, project_id, name, provider, url, config_id, status, created_at, updated_at, last_sync_at, metadata, tags
+		SELECT codebase_id, project_id, name, provider, url, config_id, status, created_at, updated_at, last_sync_at, metadata, tags, ingestion_error, expires_at, checkout
 		FROM %s
 		WHERE project_id = $1
 		ORDER BY created_at DESC
@@ -404,7 +399,7 @@ func (r *PostgresCodebaseRepository) GetCodebasesByProject(ctx context.Context,
 
 	for rows.Next() {
 		var codebase models.Codebase
-		var metadataJSON, tagsJSON []byte
+		var metadataJSON, tagsJSON, checkoutJSON []byte
 
 		err := rows.Scan(
 			&codebase.CodebaseID,
@@ -419,6 +414,9 @@ func (r *PostgresCodebaseRepository) GetCodebasesByProject(ctx context.Context,
 			&codebase.LastSyncAt,
 			&metadataJSON,
 			&tagsJSON,
+			&codebase.IngestionError,
+			&codebase.ExpiresAt,
+			&checkoutJSON,
 		)
 
 		if err != nil {
@@ -436,6 +434,9 @@ func (r *PostgresCodebaseRepository) GetCodebasesByProject(ctx context.Context,
 				return nil, fmt.Errorf("failed to unmarshal tags: %w", err)
 			}
 		}
+		if codebase.Checkout, err = unmarshalCheckout(checkoutJSON); err != nil {
+			return nil, err
+		}
 
 		codebases = append(codebases, &codebase)
 	}
@@ -446,3 +447,60 @@ func (r *PostgresCodebaseRepository) GetCodebasesByProject(ctx context.Context,
 
 	return codebases, nil
 }
+
+// CountByProject counts the project's codebases grouped by status
+func (r *PostgresCodebaseRepository) CountByProject(ctx context.Context, projectID string) ([]CodebaseStatusCount, error) {
+	query := fmt.Sprintf(`
+		SELECT status, COUNT(*), MAX(last_sync_at)
+		FROM %s
+		WHERE project_id = $1
+		GROUP BY status
+	`, r.tableName)
+
+	rows, err := r.db.QueryContext(ctx, query, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count codebases by project: %w", err)
+	}
+	defer func() {
+		_ = rows.Close() // Ignore close error as we're already handling the main error
+	}()
+
+	var counts []CodebaseStatusCount
+	for rows.Next() {
+		var count CodebaseStatusCount
+		if err := rows.Scan(&count.Status, &count.Count, &count.LastSyncAt); err != nil {
+			return nil, fmt.Errorf("failed to scan codebase count: %w", err)
+		}
+		counts = append(counts, count)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating codebase counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// marshalCheckout encodes the checkout options of a codebase, NULL when it has none
+func marshalCheckout(checkout *models.CodebaseCheckout) ([]byte, error) {
+	if checkout == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(checkout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal checkout: %w", err)
+	}
+	return data, nil
+}
+
+// unmarshalCheckout decodes the checkout options of a codebase, nil when NULL
+func unmarshalCheckout(data []byte) (*models.CodebaseCheckout, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	checkout := &models.CodebaseCheckout{}
+	if err := json.Unmarshal(data, checkout); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal checkout: %w", err)
+	}
+	return checkout, nil
+}
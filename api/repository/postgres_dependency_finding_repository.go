@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// dependencyFindingColumns lists the dependency finding columns in the order expected by scanDependencyFinding
+const dependencyFindingColumns = `finding_id, codebase_id, task_id, ecosystem, package, version, manifest, advisory_id,
+			   aliases, summary, severity, fixed_version, url, detected_at`
+
+// PostgresDependencyFindingRepository implements DependencyFindingRepository using PostgreSQL
+type PostgresDependencyFindingRepository struct {
+	db        *sql.DB
+	tableName string
+	batchSize int
+}
+
+// NewPostgresDependencyFindingRepository creates a new PostgreSQL dependency finding repository writing findings
+// batchSize rows per statement
+func NewPostgresDependencyFindingRepository(config PostgresConfig, tableName string, batchSize int) (DependencyFindingRepository, error) {
+	if tableName == "" {
+		tableName = conf.DefaultDependencyFindingsTableName
+	}
+
+	// Build connection string
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	repo := &PostgresDependencyFindingRepository{
+		db:        db,
+		tableName: tableName,
+		batchSize: batchSize,
+	}
+
+	return repo, nil
+}
+
+// NewPostgresDependencyFindingRepositoryWithDB creates a new PostgreSQL dependency finding repository with an existing DB connection
+func NewPostgresDependencyFindingRepositoryWithDB(db *sql.DB, tableName string, batchSize int) DependencyFindingRepository {
+	if tableName == "" {
+		tableName = conf.DefaultDependencyFindingsTableName
+	}
+
+	return &PostgresDependencyFindingRepository{
+		db:        db,
+		tableName: tableName,
+		batchSize: batchSize,
+	}
+}
+
+// ReplaceFindings deletes the previous findings of a codebase and stores the new ones in batches within a single
+// transaction, so listings never mix two audits
+func (r *PostgresDependencyFindingRepository) ReplaceFindings(ctx context.Context, codebaseID string, findings []models.DependencyFinding) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				slog.WarnContext(ctx, "failed to rollback dependency findings replacement", "error", rollbackErr)
+			}
+		}
+	}()
+
+	deleteQuery := fmt.Sprintf(`DELETE FROM %s WHERE codebase_id = $1`, r.tableName)
+	if _, err = tx.ExecContext(ctx, deleteQuery, codebaseID); err != nil {
+		return fmt.Errorf("failed to delete previous dependency findings: %w", err)
+	}
+
+	rows := make([][]any, 0, len(findings))
+	for _, finding := range findings {
+		var aliasesJSON []byte
+		aliasesJSON, err = json.Marshal(finding.Aliases)
+		if err != nil {
+			return fmt.Errorf("failed to marshal aliases: %w", err)
+		}
+
+		rows = append(rows, []any{
+			finding.FindingID, codebaseID, finding.TaskID, finding.Ecosystem, finding.Package, finding.Version,
+			finding.Manifest, finding.AdvisoryID, aliasesJSON, finding.Summary, finding.Severity, finding.FixedVersion,
+			finding.URL, finding.DetectedAt,
+		})
+	}
+	if err = bulkInsert(ctx, tx, r.tableName, dependencyFindingColumns, rows, r.batchSize); err != nil {
+		return fmt.Errorf("failed to create dependency findings: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit dependency findings: %w", err)
+	}
+
+	return nil
+}
+
+// ListFindings lists the findings of a codebase, most severe first
+func (r *PostgresDependencyFindingRepository) ListFindings(ctx context.Context, codebaseID string) ([]models.DependencyFinding, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s
+		WHERE codebase_id = $1
+		ORDER BY CASE severity WHEN 'critical' THEN 0 WHEN 'high' THEN 1 WHEN 'medium' THEN 2 WHEN 'low' THEN 3 ELSE 4 END,
+			package, advisory_id
+	`, dependencyFindingColumns, r.tableName)
+
+	rows, err := r.db.QueryContext(ctx, query, codebaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dependency findings: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close dependency finding rows", "error", closeErr)
+		}
+	}()
+
+	findings := []models.DependencyFinding{}
+	for rows.Next() {
+		finding, err := scanDependencyFinding(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan dependency finding: %w", err)
+		}
+		findings = append(findings, *finding)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate dependency findings: %w", err)
+	}
+
+	return findings, nil
+}
+
+// scanDependencyFinding scans a single row selected with dependencyFindingColumns
+func scanDependencyFinding(row rowScanner) (*models.DependencyFinding, error) {
+	var finding models.DependencyFinding
+	var aliasesJSON []byte
+
+	err := row.Scan(
+		&finding.FindingID, &finding.CodebaseID, &finding.TaskID, &finding.Ecosystem, &finding.Package, &finding.Version,
+		&finding.Manifest, &finding.AdvisoryID, &aliasesJSON, &finding.Summary, &finding.Severity, &finding.FixedVersion,
+		&finding.URL, &finding.DetectedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(aliasesJSON) > 0 {
+		if err := json.Unmarshal(aliasesJSON, &finding.Aliases); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal aliases JSON for finding %s: %w", finding.FindingID, err)
+		}
+	}
+
+	return &finding, nil
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+func TestPostgresDependencyFindingRepository_ReplaceFindings(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresDependencyFindingRepositoryWithDB(db, "dependency_findings", 0)
+	detectedAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+	fixed := "0.17.0"
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM dependency_findings WHERE codebase_id = \$1`).
+		WithArgs("codebase-1").
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectExec(`INSERT INTO dependency_findings`).
+		WithArgs("finding-1", "codebase-1", "task-1", "Go", "golang.org/x/net", "0.10.0", "go.mod", "GO-2023-2102",
+			[]byte(`["CVE-2023-44487"]`), "HTTP/2 rapid reset", models.DependencySeverityHigh, &fixed,
+			"https://osv.dev/vulnerability/GO-2023-2102", detectedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = repo.ReplaceFindings(context.Background(), "codebase-1", []models.DependencyFinding{{
+		FindingID:    "finding-1",
+		TaskID:       "task-1",
+		Ecosystem:    "Go",
+		Package:      "golang.org/x/net",
+		Version:      "0.10.0",
+		Manifest:     "go.mod",
+		AdvisoryID:   "GO-2023-2102",
+		Aliases:      []string{"CVE-2023-44487"},
+		Summary:      "HTTP/2 rapid reset",
+		Severity:     models.DependencySeverityHigh,
+		FixedVersion: &fixed,
+		URL:          "https://osv.dev/vulnerability/GO-2023-2102",
+		DetectedAt:   detectedAt,
+	}})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresDependencyFindingRepository_ReplaceFindings_RollsBack(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresDependencyFindingRepositoryWithDB(db, "dependency_findings", 0)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM dependency_findings`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO dependency_findings`).WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	err = repo.ReplaceFindings(context.Background(), "codebase-1", []models.DependencyFinding{{FindingID: "finding-1"}})
+
+	assert.ErrorContains(t, err, "connection reset")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresDependencyFindingRepository_ListFindings(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresDependencyFindingRepositoryWithDB(db, "dependency_findings", 0)
+	detectedAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	columns := []string{"finding_id", "codebase_id", "task_id", "ecosystem", "package", "version", "manifest", "advisory_id",
+		"aliases", "summary", "severity", "fixed_version", "url", "detected_at"}
+	mock.ExpectQuery(`SELECT .+ FROM dependency_findings\s+WHERE codebase_id = \$1\s+ORDER BY CASE severity`).
+		WithArgs("codebase-1").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("finding-1", "codebase-1", "task-1", "npm", "lodash", "4.17.15", "web/package.json", "GHSA-p6mc-m468-83gw",
+				[]byte(`["CVE-2020-8203"]`), "Prototype pollution", "critical", nil, "https://osv.dev/vulnerability/GHSA-p6mc-m468-83gw", detectedAt))
+
+	findings, err := repo.ListFindings(context.Background(), "codebase-1")
+
+	require.NoError(t, err)
+	require.Len(t, findings, 1)
+	assert.Equal(t, []string{"CVE-2020-8203"}, findings[0].Aliases)
+	assert.Equal(t, models.DependencySeverityCritical, findings[0].Severity)
+	assert.Nil(t, findings[0].FixedVersion)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
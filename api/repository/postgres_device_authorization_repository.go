@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// PostgresDeviceAuthorizationRepository implements DeviceAuthorizationRepository using PostgreSQL
+type PostgresDeviceAuthorizationRepository struct {
+	db        *sql.DB
+	tableName string
+}
+
+// NewPostgresDeviceAuthorizationRepository creates a new PostgreSQL device authorization repository
+func NewPostgresDeviceAuthorizationRepository(config PostgresConfig, tableName string) (DeviceAuthorizationRepository, error) {
+	if tableName == "" {
+		tableName = conf.DefaultDeviceAuthorizationsTableName
+	}
+
+	// Build connection string
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	repo := &PostgresDeviceAuthorizationRepository{
+		db:        db,
+		tableName: tableName,
+	}
+
+	return repo, nil
+}
+
+// NewPostgresDeviceAuthorizationRepositoryWithDB creates a new PostgreSQL device authorization repository with an
+// existing DB connection
+func NewPostgresDeviceAuthorizationRepositoryWithDB(db *sql.DB, tableName string) DeviceAuthorizationRepository {
+	if tableName == "" {
+		tableName = conf.DefaultDeviceAuthorizationsTableName
+	}
+
+	return &PostgresDeviceAuthorizationRepository{
+		db:        db,
+		tableName: tableName,
+	}
+}
+
+// CreateDeviceAuthorization stores a started device sign in
+func (r *PostgresDeviceAuthorizationRepository) CreateDeviceAuthorization(ctx context.Context, authorization *models.DeviceAuthorization) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (device_code_hash, user_code, client_name, status, token_public_key, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, r.tableName)
+
+	_, err := r.db.ExecContext(ctx, query,
+		authorization.DeviceCodeHash, authorization.UserCode, authorization.ClientName, authorization.Status,
+		authorization.TokenPublicKey, authorization.CreatedAt, authorization.ExpiresAt,
+	)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" { // unique_violation
+			return apperrors.Conflict(apperrors.CodeConflict, "user code %s is already in use", authorization.UserCode)
+		}
+		return fmt.Errorf("failed to create device authorization: %w", err)
+	}
+
+	return nil
+}
+
+// GetPendingDeviceAuthorization returns the pending, unexpired sign in with the user code
+func (r *PostgresDeviceAuthorizationRepository) GetPendingDeviceAuthorization(ctx context.Context, userCode string, now time.Time) (*models.DeviceAuthorization, error) {
+	query := fmt.Sprintf(`
+		SELECT device_code_hash, user_code, client_name, status, token_public_key, created_at, expires_at
+		FROM %s
+		WHERE user_code = $1 AND status = $2 AND expires_at > $3
+	`, r.tableName)
+
+	var authorization models.DeviceAuthorization
+	err := r.db.QueryRowContext(ctx, query, userCode, models.DeviceAuthorizationStatusPending, now).Scan(
+		&authorization.DeviceCodeHash, &authorization.UserCode, &authorization.ClientName, &authorization.Status,
+		&authorization.TokenPublicKey, &authorization.CreatedAt, &authorization.ExpiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get device authorization: %w", err)
+	}
+
+	return &authorization, nil
+}
+
+// ApproveDeviceAuthorization approves the pending, unexpired sign in with the user code
+func (r *PostgresDeviceAuthorizationRepository) ApproveDeviceAuthorization(ctx context.Context, userCode, userID, sealedRefreshToken string, now time.Time) (bool, error) {
+	query := fmt.Sprintf(`
+		UPDATE %s
+		SET status = $2, user_id = $3, sealed_refresh_token = $4
+		WHERE user_code = $1 AND status = $5 AND expires_at > $6
+	`, r.tableName)
+
+	return r.execAffected(ctx, "approve device authorization", query,
+		userCode, models.DeviceAuthorizationStatusApproved, userID, sealedRefreshToken, models.DeviceAuthorizationStatusPending, now,
+	)
+}
+
+// DenyDeviceAuthorization denies the pending, unexpired sign in with the user code
+func (r *PostgresDeviceAuthorizationRepository) DenyDeviceAuthorization(ctx context.Context, userCode string, now time.Time) (bool, error) {
+	query := fmt.Sprintf(`
+		UPDATE %s
+		SET status = $2
+		WHERE user_code = $1 AND status = $3 AND expires_at > $4
+	`, r.tableName)
+
+	return r.execAffected(ctx, "deny device authorization", query,
+		userCode, models.DeviceAuthorizationStatusDenied, models.DeviceAuthorizationStatusPending, now,
+	)
+}
+
+// PollDeviceAuthorization records a poll of the sign in with the device code hash. The row is locked while its previous
+// poll time is read, so concurrent polls each see the one before.
+func (r *PostgresDeviceAuthorizationRepository) PollDeviceAuthorization(ctx context.Context, deviceCodeHash string, polledAt time.Time) (*models.DeviceAuthorization, error) {
+	query := fmt.Sprintf(`
+		UPDATE %[1]s AS d
+		SET last_polled_at = $2
+		FROM (SELECT device_code_hash, last_polled_at FROM %[1]s WHERE device_code_hash = $1 FOR UPDATE) AS previous
+		WHERE d.device_code_hash = previous.device_code_hash
+		RETURNING d.device_code_hash, d.user_code, d.client_name, d.status, d.user_id, d.created_at, d.expires_at, previous.last_polled_at
+	`, r.tableName)
+
+	var authorization models.DeviceAuthorization
+	err := r.db.QueryRowContext(ctx, query, deviceCodeHash, polledAt).Scan(
+		&authorization.DeviceCodeHash, &authorization.UserCode, &authorization.ClientName, &authorization.Status,
+		&authorization.UserID, &authorization.CreatedAt, &authorization.ExpiresAt, &authorization.LastPolledAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to poll device authorization: %w", err)
+	}
+
+	return &authorization, nil
+}
+
+// ConsumeDeviceAuthorization marks the approved sign in with the device code hash consumed and returns its sealed
+// refresh token. The update is conditional, so concurrent polls can't both receive tokens.
+func (r *PostgresDeviceAuthorizationRepository) ConsumeDeviceAuthorization(ctx context.Context, deviceCodeHash string) (string, error) {
+	query := fmt.Sprintf(`
+		UPDATE %[1]s AS d
+		SET status = $2, sealed_refresh_token = NULL
+		FROM (SELECT device_code_hash, sealed_refresh_token FROM %[1]s WHERE device_code_hash = $1 AND status = $3 FOR UPDATE) AS approved
+		WHERE d.device_code_hash = approved.device_code_hash
+		RETURNING approved.sealed_refresh_token
+	`, r.tableName)
+
+	var sealedRefreshToken sql.NullString
+	err := r.db.QueryRowContext(ctx, query,
+		deviceCodeHash, models.DeviceAuthorizationStatusConsumed, models.DeviceAuthorizationStatusApproved,
+	).Scan(&sealedRefreshToken)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to consume device authorization: %w", err)
+	}
+
+	return sealedRefreshToken.String, nil
+}
+
+// DeleteExpiredDeviceAuthorizations deletes the sign ins that expired before a time
+func (r *PostgresDeviceAuthorizationRepository) DeleteExpiredDeviceAuthorizations(ctx context.Context, before time.Time) (int64, error) {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE expires_at < $1`, r.tableName)
+
+	result, err := r.db.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired device authorizations: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// execAffected runs an update, reporting whether it affected a row
+func (r *PostgresDeviceAuthorizationRepository) execAffected(ctx context.Context, operation, query string, args ...any) (bool, error) {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to %s: %w", operation, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+func TestPostgresDeviceAuthorizationRepository_PollDeviceAuthorization(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresDeviceAuthorizationRepositoryWithDB(db, "device_authorizations")
+	createdAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+	previousPoll := createdAt.Add(5 * time.Second)
+	polledAt := createdAt.Add(7 * time.Second)
+
+	mock.ExpectQuery(`UPDATE device_authorizations AS d\s+SET last_polled_at = \$2\s+FROM \(SELECT device_code_hash, last_polled_at FROM device_authorizations WHERE device_code_hash = \$1 FOR UPDATE\) AS previous`).
+		WithArgs("hash-1", polledAt).
+		WillReturnRows(sqlmock.NewRows([]string{"device_code_hash", "user_code", "client_name", "status", "user_id", "created_at", "expires_at", "last_polled_at"}).
+			AddRow("hash-1", "BCDF-GHJK", "refactor-cli", "pending", nil, createdAt, createdAt.Add(10*time.Minute), previousPoll))
+	mock.ExpectQuery(`UPDATE device_authorizations AS d`).
+		WithArgs("hash-2", polledAt).
+		WillReturnRows(sqlmock.NewRows([]string{"device_code_hash"}))
+
+	authorization, err := repo.PollDeviceAuthorization(context.Background(), "hash-1", polledAt)
+	require.NoError(t, err)
+	assert.Equal(t, models.DeviceAuthorizationStatusPending, authorization.Status)
+	// The time of the previous poll is returned
+	require.NotNil(t, authorization.LastPolledAt)
+	assert.Equal(t, previousPoll, *authorization.LastPolledAt)
+
+	authorization, err = repo.PollDeviceAuthorization(context.Background(), "hash-2", polledAt)
+	require.NoError(t, err)
+	assert.Nil(t, authorization)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresDeviceAuthorizationRepository_ConsumeDeviceAuthorization(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresDeviceAuthorizationRepositoryWithDB(db, "device_authorizations")
+
+	query := `UPDATE device_authorizations AS d\s+SET status = \$2, sealed_refresh_token = NULL`
+	mock.ExpectQuery(query).
+		WithArgs("hash-1", models.DeviceAuthorizationStatusConsumed, models.DeviceAuthorizationStatusApproved).
+		WillReturnRows(sqlmock.NewRows([]string{"sealed_refresh_token"}).AddRow("sealed-refresh-token"))
+	mock.ExpectQuery(query).
+		WithArgs("hash-1", models.DeviceAuthorizationStatusConsumed, models.DeviceAuthorizationStatusApproved).
+		WillReturnRows(sqlmock.NewRows([]string{"sealed_refresh_token"}))
+
+	sealedRefreshToken, err := repo.ConsumeDeviceAuthorization(context.Background(), "hash-1")
+	require.NoError(t, err)
+	assert.Equal(t, "sealed-refresh-token", sealedRefreshToken)
+
+	// The tokens of a sign in are only issued once
+	sealedRefreshToken, err = repo.ConsumeDeviceAuthorization(context.Background(), "hash-1")
+	require.NoError(t, err)
+	assert.Empty(t, sealedRefreshToken)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresDeviceAuthorizationRepository_GetPendingDeviceAuthorization(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresDeviceAuthorizationRepositoryWithDB(db, "device_authorizations")
+	createdAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+	now := createdAt.Add(time.Minute)
+
+	query := `SELECT device_code_hash, user_code, client_name, status, token_public_key, created_at, expires_at\s+FROM device_authorizations\s+WHERE user_code = \$1 AND status = \$2 AND expires_at > \$3`
+	mock.ExpectQuery(query).
+		WithArgs("BCDF-GHJK", models.DeviceAuthorizationStatusPending, now).
+		WillReturnRows(sqlmock.NewRows([]string{"device_code_hash", "user_code", "client_name", "status", "token_public_key", "created_at", "expires_at"}).
+			AddRow("hash-1", "BCDF-GHJK", "refactor-cli", "pending", "public-key", createdAt, createdAt.Add(10*time.Minute)))
+	mock.ExpectQuery(query).
+		WithArgs("BCDF-GHJK", models.DeviceAuthorizationStatusPending, now).
+		WillReturnRows(sqlmock.NewRows([]string{"device_code_hash"}))
+
+	authorization, err := repo.GetPendingDeviceAuthorization(context.Background(), "BCDF-GHJK", now)
+	require.NoError(t, err)
+	assert.Equal(t, "public-key", authorization.TokenPublicKey)
+
+	authorization, err = repo.GetPendingDeviceAuthorization(context.Background(), "BCDF-GHJK", now)
+	require.NoError(t, err)
+	assert.Nil(t, authorization)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresDeviceAuthorizationRepository_DeleteExpiredDeviceAuthorizations(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresDeviceAuthorizationRepositoryWithDB(db, "device_authorizations")
+	now := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	mock.ExpectExec(`DELETE FROM device_authorizations WHERE expires_at < \$1`).
+		WithArgs(now).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	deleted, err := repo.DeleteExpiredDeviceAuthorizations(context.Background(), now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
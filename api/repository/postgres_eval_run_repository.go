@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// evalRunColumns lists the evaluation run columns in the order expected by scanEvalRun
+const evalRunColumns = `run_id, model, prompt_version, status, cases, results, summary, started_by, started_at, completed_at`
+
+// PostgresEvalRunRepository implements EvalRunRepository using PostgreSQL
+type PostgresEvalRunRepository struct {
+	db        *sql.DB
+	tableName string
+}
+
+// NewPostgresEvalRunRepository creates a new PostgreSQL evaluation run repository
+func NewPostgresEvalRunRepository(config PostgresConfig, tableName string) (EvalRunRepository, error) {
+	if tableName == "" {
+		tableName = conf.DefaultEvalRunsTableName
+	}
+
+	// Build connection string
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	repo := &PostgresEvalRunRepository{
+		db:        db,
+		tableName: tableName,
+	}
+
+	return repo, nil
+}
+
+// NewPostgresEvalRunRepositoryWithDB creates a new PostgreSQL evaluation run repository with an existing DB connection
+func NewPostgresEvalRunRepositoryWithDB(db *sql.DB, tableName string) EvalRunRepository {
+	if tableName == "" {
+		tableName = conf.DefaultEvalRunsTableName
+	}
+
+	return &PostgresEvalRunRepository{
+		db:        db,
+		tableName: tableName,
+	}
+}
+
+// CreateRun stores a new evaluation run
+func (r *PostgresEvalRunRepository) CreateRun(ctx context.Context, run *models.EvalRun) error {
+	casesJSON, resultsJSON, summaryJSON, err := marshalEvalRun(run)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (%s)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`, r.tableName, evalRunColumns)
+
+	_, err = r.db.ExecContext(ctx, query,
+		run.RunID, run.Model, run.PromptVersion, run.Status, casesJSON, resultsJSON, summaryJSON,
+		run.StartedBy, run.StartedAt, run.CompletedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create eval run: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateRun stores the progress and results of an evaluation run
+func (r *PostgresEvalRunRepository) UpdateRun(ctx context.Context, run *models.EvalRun) error {
+	_, resultsJSON, summaryJSON, err := marshalEvalRun(run)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s SET status = $2, results = $3, summary = $4, completed_at = $5
+		WHERE run_id = $1
+	`, r.tableName)
+
+	result, err := r.db.ExecContext(ctx, query, run.RunID, run.Status, resultsJSON, summaryJSON, run.CompletedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update eval run: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return apperrors.NotFound(apperrors.CodeEvalRunNotFound, "eval run not found: %s", run.RunID)
+	}
+
+	return nil
+}
+
+// GetRun retrieves an evaluation run with its results
+func (r *PostgresEvalRunRepository) GetRun(ctx context.Context, runID string) (*models.EvalRun, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE run_id = $1`, evalRunColumns, r.tableName)
+
+	run, err := scanEvalRun(r.db.QueryRowContext(ctx, query, runID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.NotFound(apperrors.CodeEvalRunNotFound, "eval run not found: %s", runID)
+		}
+		return nil, fmt.Errorf("failed to get eval run: %w", err)
+	}
+
+	return run, nil
+}
+
+// ListRuns lists the most recent evaluation runs, newest first, without their per-fixture results
+func (r *PostgresEvalRunRepository) ListRuns(ctx context.Context, limit int) ([]models.EvalRun, error) {
+	query := fmt.Sprintf(`
+		SELECT run_id, model, prompt_version, status, cases, '[]'::jsonb, summary, started_by, started_at, completed_at
+		FROM %s
+		ORDER BY started_at DESC
+		LIMIT $1
+	`, r.tableName)
+
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list eval runs: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close eval run rows", "error", closeErr)
+		}
+	}()
+
+	runs := []models.EvalRun{}
+	for rows.Next() {
+		run, err := scanEvalRun(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan eval run: %w", err)
+		}
+		runs = append(runs, *run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate eval runs: %w", err)
+	}
+
+	return runs, nil
+}
+
+// marshalEvalRun encodes the JSON columns of a run; the summary is NULL until the run completes
+func marshalEvalRun(run *models.EvalRun) (casesJSON, resultsJSON, summaryJSON []byte, err error) {
+	cases := run.Cases
+	if cases == nil {
+		cases = []string{}
+	}
+	if casesJSON, err = json.Marshal(cases); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to marshal eval cases: %w", err)
+	}
+	results := run.Results
+	if results == nil {
+		results = []models.EvalCaseResult{}
+	}
+	if resultsJSON, err = json.Marshal(results); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to marshal eval results: %w", err)
+	}
+	if run.Summary != nil {
+		if summaryJSON, err = json.Marshal(run.Summary); err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to marshal eval summary: %w", err)
+		}
+	}
+	return casesJSON, resultsJSON, summaryJSON, nil
+}
+
+// scanEvalRun scans a single row selected with evalRunColumns
+func scanEvalRun(row rowScanner) (*models.EvalRun, error) {
+	var run models.EvalRun
+	var casesJSON, resultsJSON, summaryJSON []byte
+
+	err := row.Scan(
+		&run.RunID, &run.Model, &run.PromptVersion, &run.Status, &casesJSON, &resultsJSON, &summaryJSON,
+		&run.StartedBy, &run.StartedAt, &run.CompletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(casesJSON, &run.Cases); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cases of eval run %s: %w", run.RunID, err)
+	}
+	if err := json.Unmarshal(resultsJSON, &run.Results); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal results of eval run %s: %w", run.RunID, err)
+	}
+	if summaryJSON != nil {
+		if err := json.Unmarshal(summaryJSON, &run.Summary); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal summary of eval run %s: %w", run.RunID, err)
+		}
+	}
+
+	return &run, nil
+}
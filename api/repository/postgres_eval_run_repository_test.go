@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+func TestPostgresEvalRunRepository_CreateRun(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresEvalRunRepositoryWithDB(db, "eval_runs")
+	startedAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+	startedBy := "user-1"
+
+	mock.ExpectExec(`INSERT INTO eval_runs`).
+		WithArgs("eval-1", "qwen2.5-coder:7b", "v2", models.EvalRunStatusRunning, []byte(`["extract-function"]`),
+			[]byte(`[]`), []byte(nil), &startedBy, startedAt, (*time.Time)(nil)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.CreateRun(context.Background(), &models.EvalRun{
+		RunID: "eval-1", Model: "qwen2.5-coder:7b", PromptVersion: "v2", Status: models.EvalRunStatusRunning,
+		Cases: []string{"extract-function"}, StartedBy: &startedBy, StartedAt: startedAt,
+	})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresEvalRunRepository_GetRun(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresEvalRunRepositoryWithDB(db, "eval_runs")
+	startedAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	columns := []string{"run_id", "model", "prompt_version", "status", "cases", "results", "summary",
+		"started_by", "started_at", "completed_at"}
+	mock.ExpectQuery(`SELECT .+ FROM eval_runs WHERE run_id = \$1`).
+		WithArgs("eval-1").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("eval-1", "qwen2.5-coder:7b", "v1", "completed",
+			[]byte(`["extract-function"]`), []byte(`[{"case":"extract-function","compiled":true,"diff":{"score":0.5}}]`),
+			[]byte(`{"cases":1,"compiled":1,"compile_rate":1}`), nil, startedAt, startedAt))
+	mock.ExpectQuery(`SELECT .+ FROM eval_runs WHERE run_id = \$1`).
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+
+	run, err := repo.GetRun(context.Background(), "eval-1")
+
+	require.NoError(t, err)
+	require.Len(t, run.Results, 1)
+	assert.True(t, run.Results[0].Compiled)
+	assert.Equal(t, 0.5, run.Results[0].Diff.Score)
+	require.NotNil(t, run.Summary)
+	assert.Equal(t, 1.0, run.Summary.CompileRate)
+
+	_, err = repo.GetRun(context.Background(), "missing")
+
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	assert.Equal(t, apperrors.CodeEvalRunNotFound, apperrors.CodeOf(err))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresEvalRunRepository_ListRuns(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresEvalRunRepositoryWithDB(db, "eval_runs")
+	startedAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	columns := []string{"run_id", "model", "prompt_version", "status", "cases", "results", "summary",
+		"started_by", "started_at", "completed_at"}
+	mock.ExpectQuery(`SELECT .+ FROM eval_runs\s+ORDER BY started_at DESC\s+LIMIT \$1`).
+		WithArgs(20).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("eval-1", "qwen2.5-coder:7b", "v1", "running",
+			[]byte(`["extract-function"]`), []byte(`[]`), nil, nil, startedAt, nil))
+
+	runs, err := repo.ListRuns(context.Background(), 20)
+
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, models.EvalRunStatusRunning, runs[0].Status)
+	assert.Nil(t, runs[0].Summary)
+	assert.Empty(t, runs[0].Results)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
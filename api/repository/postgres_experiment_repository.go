@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// experimentColumns lists the experiment columns in the order expected by scanExperiment
+const experimentColumns = `experiment_id, name, task_types, traffic_percent, variant_model, variant_prompt_version, status, created_by, created_at, stopped_at`
+
+// PostgresExperimentRepository implements ExperimentRepository using PostgreSQL
+type PostgresExperimentRepository struct {
+	db        *sql.DB
+	tableName string
+}
+
+// NewPostgresExperimentRepository creates a new PostgreSQL experiment repository
+func NewPostgresExperimentRepository(config PostgresConfig, tableName string) (ExperimentRepository, error) {
+	if tableName == "" {
+		tableName = conf.DefaultExperimentsTableName
+	}
+
+	// Build connection string
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	repo := &PostgresExperimentRepository{
+		db:        db,
+		tableName: tableName,
+	}
+
+	return repo, nil
+}
+
+// NewPostgresExperimentRepositoryWithDB creates a new PostgreSQL experiment repository with an existing DB connection
+func NewPostgresExperimentRepositoryWithDB(db *sql.DB, tableName string) ExperimentRepository {
+	if tableName == "" {
+		tableName = conf.DefaultExperimentsTableName
+	}
+
+	return &PostgresExperimentRepository{
+		db:        db,
+		tableName: tableName,
+	}
+}
+
+// CreateExperiment stores a new experiment
+func (r *PostgresExperimentRepository) CreateExperiment(ctx context.Context, experiment *models.Experiment) error {
+	taskTypesJSON, err := json.Marshal(experiment.TaskTypes)
+	if err != nil {
+		return fmt.Errorf("failed to marshal experiment task types: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (%s)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`, r.tableName, experimentColumns)
+
+	_, err = r.db.ExecContext(ctx, query,
+		experiment.ExperimentID, experiment.Name, taskTypesJSON, experiment.TrafficPercent, experiment.VariantModel,
+		experiment.VariantPromptVersion, experiment.Status, experiment.CreatedBy, experiment.CreatedAt, experiment.StoppedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create experiment: %w", err)
+	}
+
+	return nil
+}
+
+// GetExperiment retrieves an experiment
+func (r *PostgresExperimentRepository) GetExperiment(ctx context.Context, experimentID string) (*models.Experiment, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE experiment_id = $1`, experimentColumns, r.tableName)
+
+	experiment, err := scanExperiment(r.db.QueryRowContext(ctx, query, experimentID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.NotFound(apperrors.CodeExperimentNotFound, "experiment not found: %s", experimentID)
+		}
+		return nil, fmt.Errorf("failed to get experiment: %w", err)
+	}
+
+	return experiment, nil
+}
+
+// ListExperiments lists the experiments with the given status, every experiment when it is empty, newest first
+func (r *PostgresExperimentRepository) ListExperiments(ctx context.Context, status models.ExperimentStatus) ([]models.Experiment, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		WHERE $1 = '' OR status = $1
+		ORDER BY created_at DESC
+	`, experimentColumns, r.tableName)
+
+	rows, err := r.db.QueryContext(ctx, query, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list experiments: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close experiment rows", "error", closeErr)
+		}
+	}()
+
+	experiments := []models.Experiment{}
+	for rows.Next() {
+		experiment, err := scanExperiment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan experiment: %w", err)
+		}
+		experiments = append(experiments, *experiment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate experiments: %w", err)
+	}
+
+	return experiments, nil
+}
+
+// StopExperiment stops a running experiment, reporting false when it was stopped already
+func (r *PostgresExperimentRepository) StopExperiment(ctx context.Context, experimentID string, stoppedAt time.Time) (bool, error) {
+	query := fmt.Sprintf(`
+		UPDATE %s SET status = $2, stopped_at = $3
+		WHERE experiment_id = $1 AND status = $4
+	`, r.tableName)
+
+	result, err := r.db.ExecContext(ctx, query, experimentID, models.ExperimentStatusStopped, stoppedAt, models.ExperimentStatusRunning)
+	if err != nil {
+		return false, fmt.Errorf("failed to stop experiment: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected > 0 {
+		return true, nil
+	}
+
+	// Nothing changed: the experiment doesn't exist, or was stopped already
+	if _, err := r.GetExperiment(ctx, experimentID); err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+// scanExperiment scans a single row selected with experimentColumns
+func scanExperiment(row rowScanner) (*models.Experiment, error) {
+	var experiment models.Experiment
+	var taskTypesJSON []byte
+
+	err := row.Scan(
+		&experiment.ExperimentID, &experiment.Name, &taskTypesJSON, &experiment.TrafficPercent, &experiment.VariantModel,
+		&experiment.VariantPromptVersion, &experiment.Status, &experiment.CreatedBy, &experiment.CreatedAt, &experiment.StoppedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(taskTypesJSON, &experiment.TaskTypes); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal task types of experiment %s: %w", experiment.ExperimentID, err)
+	}
+
+	return &experiment, nil
+}
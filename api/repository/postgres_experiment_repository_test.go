@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+var experimentTestColumns = []string{"experiment_id", "name", "task_types", "traffic_percent", "variant_model",
+	"variant_prompt_version", "status", "created_by", "created_at", "stopped_at"}
+
+func TestPostgresExperimentRepository_CreateExperiment(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresExperimentRepositoryWithDB(db, "experiments")
+	createdAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+	createdBy := "user-1"
+
+	mock.ExpectExec(`INSERT INTO experiments`).
+		WithArgs("exp-1", "qwen on refactorings", []byte(`["refactoring"]`), 20, "qwen2.5-coder:7b", "",
+			models.ExperimentStatusRunning, &createdBy, createdAt, (*time.Time)(nil)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.CreateExperiment(context.Background(), &models.Experiment{
+		ExperimentID: "exp-1", Name: "qwen on refactorings", TaskTypes: []models.TaskType{models.TaskTypeRefactoring},
+		TrafficPercent: 20, VariantModel: "qwen2.5-coder:7b", Status: models.ExperimentStatusRunning,
+		CreatedBy: &createdBy, CreatedAt: createdAt,
+	})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresExperimentRepository_ListExperiments(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresExperimentRepositoryWithDB(db, "experiments")
+	createdAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT .+ FROM experiments\s+WHERE \$1 = '' OR status = \$1\s+ORDER BY created_at DESC`).
+		WithArgs(models.ExperimentStatusRunning).
+		WillReturnRows(sqlmock.NewRows(experimentTestColumns).AddRow("exp-1", "prompt v2", []byte(`["refactoring","code_review"]`),
+			50, "", "v2", "running", nil, createdAt, nil))
+
+	experiments, err := repo.ListExperiments(context.Background(), models.ExperimentStatusRunning)
+
+	require.NoError(t, err)
+	require.Len(t, experiments, 1)
+	assert.Equal(t, []models.TaskType{models.TaskTypeRefactoring, models.TaskTypeCodeReview}, experiments[0].TaskTypes)
+	assert.Equal(t, "v2", experiments[0].VariantPromptVersion)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresExperimentRepository_StopExperiment(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresExperimentRepositoryWithDB(db, "experiments")
+	stoppedAt := time.Date(2024, time.January, 22, 10, 30, 0, 0, time.UTC)
+
+	mock.ExpectExec(`UPDATE experiments SET status = \$2, stopped_at = \$3\s+WHERE experiment_id = \$1 AND status = \$4`).
+		WithArgs("exp-1", models.ExperimentStatusStopped, stoppedAt, models.ExperimentStatusRunning).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE experiments`).
+		WithArgs("exp-1", models.ExperimentStatusStopped, stoppedAt, models.ExperimentStatusRunning).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT .+ FROM experiments WHERE experiment_id = \$1`).
+		WithArgs("exp-1").
+		WillReturnRows(sqlmock.NewRows(experimentTestColumns).AddRow("exp-1", "prompt v2", []byte(`[]`),
+			50, "", "v2", "stopped", nil, stoppedAt, stoppedAt))
+	mock.ExpectExec(`UPDATE experiments`).
+		WithArgs("missing", models.ExperimentStatusStopped, stoppedAt, models.ExperimentStatusRunning).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT .+ FROM experiments WHERE experiment_id = \$1`).
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+
+	stopped, err := repo.StopExperiment(context.Background(), "exp-1", stoppedAt)
+	require.NoError(t, err)
+	assert.True(t, stopped)
+
+	stopped, err = repo.StopExperiment(context.Background(), "exp-1", stoppedAt)
+	require.NoError(t, err)
+	assert.False(t, stopped, "an experiment stops once")
+
+	_, err = repo.StopExperiment(context.Background(), "missing", stoppedAt)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	assert.Equal(t, apperrors.CodeExperimentNotFound, apperrors.CodeOf(err))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
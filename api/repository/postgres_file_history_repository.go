@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"github.com/lib/pq"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// fileHistoryColumns lists the file history columns written by ReplaceHistory
+const fileHistoryColumns = `codebase_id, path, commit_sha, commits, lines_added, lines_deleted, authors, first_commit_at, last_commit_at`
+
+// PostgresFileHistoryRepository implements FileHistoryRepository using PostgreSQL
+type PostgresFileHistoryRepository struct {
+	db        *sql.DB
+	tableName string
+	batchSize int
+}
+
+// NewPostgresFileHistoryRepository creates a new PostgreSQL file history repository writing histories batchSize rows
+// per statement
+func NewPostgresFileHistoryRepository(config PostgresConfig, tableName string, batchSize int) (FileHistoryRepository, error) {
+	if tableName == "" {
+		tableName = conf.DefaultFileHistoriesTableName
+	}
+
+	// Build connection string
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	repo := &PostgresFileHistoryRepository{
+		db:        db,
+		tableName: tableName,
+		batchSize: batchSize,
+	}
+
+	return repo, nil
+}
+
+// NewPostgresFileHistoryRepositoryWithDB creates a new PostgreSQL file history repository with an existing DB connection
+func NewPostgresFileHistoryRepositoryWithDB(db *sql.DB, tableName string, batchSize int) FileHistoryRepository {
+	if tableName == "" {
+		tableName = conf.DefaultFileHistoriesTableName
+	}
+
+	return &PostgresFileHistoryRepository{
+		db:        db,
+		tableName: tableName,
+		batchSize: batchSize,
+	}
+}
+
+// ReplaceHistory deletes the previous file histories of a codebase and stores the new ones in batches within a single
+// transaction, so lookups never mix two readings of the history
+func (r *PostgresFileHistoryRepository) ReplaceHistory(ctx context.Context, codebaseID, commitSHA string, histories []models.FileHistory) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				slog.WarnContext(ctx, "failed to rollback file history replacement", "error", rollbackErr)
+			}
+		}
+	}()
+
+	deleteQuery := fmt.Sprintf(`DELETE FROM %s WHERE codebase_id = $1`, r.tableName)
+	if _, err = tx.ExecContext(ctx, deleteQuery, codebaseID); err != nil {
+		return fmt.Errorf("failed to delete previous file histories: %w", err)
+	}
+
+	rows := make([][]any, 0, len(histories))
+	for _, history := range histories {
+		rows = append(rows, []any{
+			codebaseID, history.Path, commitSHA, history.Commits, history.LinesAdded, history.LinesDeleted,
+			pq.Array(history.Authors), history.FirstCommitAt, history.LastCommitAt,
+		})
+	}
+	if err = bulkInsert(ctx, tx, r.tableName, fileHistoryColumns, rows, r.batchSize); err != nil {
+		return fmt.Errorf("failed to create file histories: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit file histories: %w", err)
+	}
+
+	return nil
+}
+
+// ListHistory lists the file histories of a codebase matching filter, highest churn first
+func (r *PostgresFileHistoryRepository) ListHistory(ctx context.Context, codebaseID string, filter FileHistoryFilter) ([]models.FileHistory, error) {
+	query := fmt.Sprintf(`
+		SELECT path, commits, lines_added, lines_deleted, churn, authors, first_commit_at, last_commit_at FROM %s
+		WHERE codebase_id = $1`, r.tableName)
+	args := []any{codebaseID}
+	if len(filter.Paths) > 0 {
+		args = append(args, pq.Array(filter.Paths))
+		query += fmt.Sprintf(" AND path = ANY($%d)", len(args))
+	}
+	query += " ORDER BY churn DESC, path"
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list file histories: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close file history rows", "error", closeErr)
+		}
+	}()
+
+	histories := []models.FileHistory{}
+	for rows.Next() {
+		var history models.FileHistory
+		if err := rows.Scan(&history.Path, &history.Commits, &history.LinesAdded, &history.LinesDeleted, &history.Churn,
+			pq.Array(&history.Authors), &history.FirstCommitAt, &history.LastCommitAt); err != nil {
+			return nil, fmt.Errorf("failed to scan file history: %w", err)
+		}
+		histories = append(histories, history)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate file histories: %w", err)
+	}
+
+	return histories, nil
+}
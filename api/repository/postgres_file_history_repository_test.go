@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+func TestPostgresFileHistoryRepository_ReplaceHistory(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresFileHistoryRepositoryWithDB(db, "file_histories", 0)
+	firstCommitAt := time.Date(2023, time.February, 1, 10, 30, 0, 0, time.UTC)
+	lastCommitAt := time.Date(2024, time.January, 10, 10, 30, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM file_histories WHERE codebase_id = \$1`).
+		WithArgs("codebase-1").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(`INSERT INTO file_histories`).
+		WithArgs("codebase-1", "billing/invoice.go", "abc123", 14, 220, 96, pq.Array([]string{"alice", "bob"}), firstCommitAt, lastCommitAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = repo.ReplaceHistory(context.Background(), "codebase-1", "abc123", []models.FileHistory{{
+		Path:          "billing/invoice.go",
+		Commits:       14,
+		LinesAdded:    220,
+		LinesDeleted:  96,
+		Authors:       []string{"alice", "bob"},
+		FirstCommitAt: firstCommitAt,
+		LastCommitAt:  lastCommitAt,
+	}})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresFileHistoryRepository_ListHistory_Paths(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresFileHistoryRepositoryWithDB(db, "file_histories", 0)
+	committedAt := time.Date(2024, time.January, 10, 10, 30, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT .+ FROM file_histories\s+WHERE codebase_id = \$1 AND path = ANY\(\$2\) ORDER BY churn DESC, path LIMIT \$3`).
+		WithArgs("codebase-1", pq.Array([]string{"billing/invoice.go", "README.md"}), 5).
+		WillReturnRows(sqlmock.NewRows([]string{"path", "commits", "lines_added", "lines_deleted", "churn", "authors", "first_commit_at", "last_commit_at"}).
+			AddRow("billing/invoice.go", 14, 220, 96, 316, "{alice,bob}", committedAt, committedAt))
+
+	histories, err := repo.ListHistory(context.Background(), "codebase-1", FileHistoryFilter{Paths: []string{"billing/invoice.go", "README.md"}, Limit: 5})
+
+	require.NoError(t, err)
+	require.Len(t, histories, 1)
+	assert.Equal(t, 316, histories[0].Churn)
+	assert.Equal(t, []string{"alice", "bob"}, histories[0].Authors)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,380 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+	"github.com/lib/pq"
+)
+
+// findingColumns lists the finding columns in the order expected by scanFinding
+const findingColumns = `finding_id, codebase_id, source, rule_id, fingerprint, message, file_path, line, end_line, owners, severity,
+			   status, baselined, justification, status_changed_by, first_task_id, last_task_id, first_seen_at, last_seen_at, fixed_at, updated_at`
+
+// findingBaselineColumns lists the finding baseline columns in the order GetBaseline scans them
+const findingBaselineColumns = `codebase_id, finding_count, created_by, created_at`
+
+// PostgresFindingRepository implements FindingRepository using PostgreSQL
+type PostgresFindingRepository struct {
+	db                 *sql.DB
+	tableName          string
+	baselinesTableName string
+	batchSize          int
+}
+
+// NewPostgresFindingRepository creates a new PostgreSQL finding repository writing findings batchSize rows per statement
+func NewPostgresFindingRepository(config PostgresConfig, tableName, baselinesTableName string, batchSize int) (FindingRepository, error) {
+
+	// Build connection string
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	repo := NewPostgresFindingRepositoryWithDB(db, tableName, baselinesTableName, batchSize).(*PostgresFindingRepository)
+
+	return repo, nil
+}
+
+// NewPostgresFindingRepositoryWithDB creates a new PostgreSQL finding repository with an existing DB connection
+func NewPostgresFindingRepositoryWithDB(db *sql.DB, tableName, baselinesTableName string, batchSize int) FindingRepository {
+	if tableName == "" {
+		tableName = conf.DefaultFindingsTableName
+	}
+	if baselinesTableName == "" {
+		baselinesTableName = conf.DefaultFindingBaselinesTableName
+	}
+
+	return &PostgresFindingRepository{
+		db:                 db,
+		tableName:          tableName,
+		baselinesTableName: baselinesTableName,
+		batchSize:          batchSize,
+	}
+}
+
+// CreateFinding stores a new finding
+func (r *PostgresFindingRepository) CreateFinding(ctx context.Context, finding *models.Finding) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (%s)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
+	`, r.tableName, findingColumns)
+
+	_, err := r.db.ExecContext(ctx, query, findingValues(finding)...)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" { // unique_violation
+			return apperrors.Conflict(apperrors.CodeFindingExists, "codebase %s already has finding %s of rule %s", finding.CodebaseID, finding.Fingerprint, finding.RuleID)
+		}
+		return fmt.Errorf("failed to create finding: %w", err)
+	}
+
+	return nil
+}
+
+// GetFinding retrieves a finding by ID
+func (r *PostgresFindingRepository) GetFinding(ctx context.Context, findingID string) (*models.Finding, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE finding_id = $1`, findingColumns, r.tableName)
+
+	finding, err := scanFinding(r.db.QueryRowContext(ctx, query, findingID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.NotFound(apperrors.CodeFindingNotFound, "finding not found: %s", findingID)
+		}
+		return nil, fmt.Errorf("failed to get finding: %w", err)
+	}
+
+	return finding, nil
+}
+
+// UpdateFinding replaces the status of a finding
+func (r *PostgresFindingRepository) UpdateFinding(ctx context.Context, finding *models.Finding) error {
+	query := fmt.Sprintf(`
+		UPDATE %s SET status = $2, justification = $3, status_changed_by = $4, fixed_at = $5, updated_at = $6
+		WHERE finding_id = $1
+	`, r.tableName)
+
+	result, err := r.db.ExecContext(ctx, query,
+		finding.FindingID, finding.Status, finding.Justification, finding.StatusChangedBy, finding.FixedAt, finding.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update finding: %w", err)
+	}
+
+	return checkFindingAffected(result, finding.FindingID)
+}
+
+// DeleteFinding deletes a finding
+func (r *PostgresFindingRepository) DeleteFinding(ctx context.Context, findingID string) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE finding_id = $1`, r.tableName)
+
+	result, err := r.db.ExecContext(ctx, query, findingID)
+	if err != nil {
+		return fmt.Errorf("failed to delete finding: %w", err)
+	}
+
+	return checkFindingAffected(result, findingID)
+}
+
+// ListFindings lists the findings of a codebase matching filter, most recently seen first
+func (r *PostgresFindingRepository) ListFindings(ctx context.Context, codebaseID string, filter FindingFilter) ([]models.Finding, error) {
+	conditions := []string{"codebase_id = $1"}
+	args := []any{codebaseID}
+	addCondition := func(column string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+	if filter.Status != nil {
+		addCondition("status", *filter.Status)
+	}
+	if filter.Source != "" {
+		addCondition("source", filter.Source)
+	}
+	if filter.RuleID != "" {
+		addCondition("rule_id", filter.RuleID)
+	}
+	if filter.FilePath != "" {
+		addCondition("file_path", filter.FilePath)
+	}
+	if filter.Baselined != nil {
+		addCondition("baselined", *filter.Baselined)
+	}
+	if filter.Owner != "" {
+		args = append(args, filter.Owner)
+		conditions = append(conditions, fmt.Sprintf("$%d = ANY(owners)", len(args)))
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE %s ORDER BY last_seen_at DESC, finding_id`,
+		findingColumns, r.tableName, strings.Join(conditions, " AND "))
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit, filter.Offset)
+		query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list findings: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close finding rows", "error", closeErr)
+		}
+	}()
+
+	findings := []models.Finding{}
+	for rows.Next() {
+		finding, err := scanFinding(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan finding: %w", err)
+		}
+		findings = append(findings, *finding)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate findings: %w", err)
+	}
+
+	return findings, nil
+}
+
+// CountFindings counts the findings of a codebase in each status, only those suppressed, or not, by the baseline unless
+// baselined is nil
+func (r *PostgresFindingRepository) CountFindings(ctx context.Context, codebaseID string, baselined *bool) (map[models.FindingStatus]int, error) {
+	query := fmt.Sprintf(`SELECT status, COUNT(*) FROM %s WHERE codebase_id = $1 AND ($2::BOOLEAN IS NULL OR baselined = $2) GROUP BY status`, r.tableName)
+
+	rows, err := r.db.QueryContext(ctx, query, codebaseID, baselined)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count findings: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close finding count rows", "error", closeErr)
+		}
+	}()
+
+	counts := make(map[models.FindingStatus]int)
+	for rows.Next() {
+		var status models.FindingStatus
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan finding count: %w", err)
+		}
+		counts[status] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate finding counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// SaveFindings creates or replaces findings in a single transaction, leaving whether existing findings are baselined
+// unchanged so a rebaseline during an analysis isn't undone. Findings are matched on their codebase, source, rule and
+// fingerprint rather than their ID, so a finding another analysis created concurrently is updated instead of
+// duplicated, keeping its ID. The findings are written with multi-row statements, the last of several findings with
+// the same identity winning.
+func (r *PostgresFindingRepository) SaveFindings(ctx context.Context, findings []models.Finding) (err error) {
+	if len(findings) == 0 {
+		return nil
+	}
+
+	// A statement can't update the same finding twice, so only the last of duplicates is written
+	positions := make(map[string]int, len(findings))
+	rows := make([][]any, 0, len(findings))
+	for i := range findings {
+		key := findings[i].CodebaseID + "|" + findings[i].Source + "|" + findings[i].RuleID + "|" + findings[i].Fingerprint
+		if position, ok := positions[key]; ok {
+			rows[position] = findingValues(&findings[i])
+			continue
+		}
+		positions[key] = len(rows)
+		rows = append(rows, findingValues(&findings[i]))
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				slog.WarnContext(ctx, "failed to rollback findings", "error", rollbackErr)
+			}
+		}
+	}()
+
+	const onConflict = `ON CONFLICT (codebase_id, source, rule_id, fingerprint) DO UPDATE SET
+			message = EXCLUDED.message,
+			file_path = EXCLUDED.file_path,
+			line = EXCLUDED.line,
+			end_line = EXCLUDED.end_line,
+			owners = EXCLUDED.owners,
+			severity = EXCLUDED.severity,
+			status = EXCLUDED.status,
+			justification = EXCLUDED.justification,
+			status_changed_by = EXCLUDED.status_changed_by,
+			last_task_id = EXCLUDED.last_task_id,
+			last_seen_at = EXCLUDED.last_seen_at,
+			fixed_at = EXCLUDED.fixed_at,
+			updated_at = EXCLUDED.updated_at`
+	if err = bulkUpsert(ctx, tx, r.tableName, findingColumns, rows, r.batchSize, onConflict); err != nil {
+		return fmt.Errorf("failed to save findings: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit findings: %w", err)
+	}
+
+	return nil
+}
+
+// Rebaseline suppresses exactly the open findings of a codebase and replaces its baseline in a single transaction
+func (r *PostgresFindingRepository) Rebaseline(ctx context.Context, baseline *models.FindingBaseline) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				slog.WarnContext(ctx, "failed to rollback baseline", "error", rollbackErr)
+			}
+		}
+	}()
+
+	query := fmt.Sprintf(`UPDATE %s SET baselined = (status = $2) WHERE codebase_id = $1`, r.tableName)
+	if _, err = tx.ExecContext(ctx, query, baseline.CodebaseID, models.FindingStatusOpen); err != nil {
+		return fmt.Errorf("failed to baseline findings: %w", err)
+	}
+
+	query = fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE codebase_id = $1 AND baselined`, r.tableName)
+	if err = tx.QueryRowContext(ctx, query, baseline.CodebaseID).Scan(&baseline.FindingCount); err != nil {
+		return fmt.Errorf("failed to count baselined findings: %w", err)
+	}
+
+	query = fmt.Sprintf(`
+		INSERT INTO %s (%s)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (codebase_id) DO UPDATE SET
+			finding_count = EXCLUDED.finding_count,
+			created_by = EXCLUDED.created_by,
+			created_at = EXCLUDED.created_at
+	`, r.baselinesTableName, findingBaselineColumns)
+	if _, err = tx.ExecContext(ctx, query, baseline.CodebaseID, baseline.FindingCount, baseline.CreatedBy, baseline.CreatedAt); err != nil {
+		return fmt.Errorf("failed to save baseline: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit baseline: %w", err)
+	}
+
+	return nil
+}
+
+// GetBaseline retrieves the baseline of a codebase
+func (r *PostgresFindingRepository) GetBaseline(ctx context.Context, codebaseID string) (*models.FindingBaseline, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE codebase_id = $1`, findingBaselineColumns, r.baselinesTableName)
+
+	var baseline models.FindingBaseline
+	err := r.db.QueryRowContext(ctx, query, codebaseID).Scan(&baseline.CodebaseID, &baseline.FindingCount, &baseline.CreatedBy, &baseline.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.NotFound(apperrors.CodeBaselineNotFound, "codebase %s has no findings baseline", codebaseID)
+		}
+		return nil, fmt.Errorf("failed to get baseline: %w", err)
+	}
+
+	return &baseline, nil
+}
+
+// findingValues returns the values of a finding in the order of findingColumns
+func findingValues(finding *models.Finding) []any {
+	return []any{
+		finding.FindingID, finding.CodebaseID, finding.Source, finding.RuleID, finding.Fingerprint, finding.Message,
+		finding.FilePath, finding.Line, finding.EndLine, pq.Array(finding.Owners), finding.Severity, finding.Status, finding.Baselined, finding.Justification, finding.StatusChangedBy,
+		finding.FirstTaskID, finding.LastTaskID, finding.FirstSeenAt, finding.LastSeenAt, finding.FixedAt, finding.UpdatedAt,
+	}
+}
+
+// checkFindingAffected reports a finding as not found when a statement affected no rows
+func checkFindingAffected(result sql.Result, findingID string) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return apperrors.NotFound(apperrors.CodeFindingNotFound, "finding not found: %s", findingID)
+	}
+
+	return nil
+}
+
+// scanFinding scans a single row selected with findingColumns
+func scanFinding(row rowScanner) (*models.Finding, error) {
+	var finding models.Finding
+
+	err := row.Scan(
+		&finding.FindingID, &finding.CodebaseID, &finding.Source, &finding.RuleID, &finding.Fingerprint, &finding.Message,
+		&finding.FilePath, &finding.Line, &finding.EndLine, pq.Array(&finding.Owners), &finding.Severity, &finding.Status, &finding.Baselined, &finding.Justification,
+		&finding.StatusChangedBy, &finding.FirstTaskID, &finding.LastTaskID, &finding.FirstSeenAt, &finding.LastSeenAt,
+		&finding.FixedAt, &finding.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &finding, nil
+}
@@ -0,0 +1,260 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+var findingTestColumns = []string{"finding_id", "codebase_id", "source", "rule_id", "fingerprint", "message", "file_path",
+	"line", "end_line", "owners", "severity", "status", "baselined", "justification", "status_changed_by", "first_task_id", "last_task_id", "first_seen_at",
+	"last_seen_at", "fixed_at", "updated_at"}
+
+func TestPostgresFindingRepository_CreateFinding_Conflict(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresFindingRepositoryWithDB(db, "findings", "finding_baselines", DefaultBulkInsertBatchSize)
+
+	mock.ExpectExec(`INSERT INTO findings`).WillReturnError(&pq.Error{Code: "23505"})
+
+	err = repo.CreateFinding(context.Background(), &models.Finding{FindingID: "finding-1", CodebaseID: "codebase-1"})
+
+	assert.True(t, errors.Is(err, apperrors.ErrConflict))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresFindingRepository_ListFindings(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresFindingRepositoryWithDB(db, "findings", "finding_baselines", DefaultBulkInsertBatchSize)
+	seenAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+	status := models.FindingStatusOpen
+	baselined := false
+
+	mock.ExpectQuery(`SELECT .+ FROM findings WHERE codebase_id = \$1 AND status = \$2 AND rule_id = \$3 AND baselined = \$4 AND \$5 = ANY\(owners\) ORDER BY last_seen_at DESC, finding_id LIMIT \$6 OFFSET \$7`).
+		WithArgs("codebase-1", status, "unused-function", false, "@acme/billing", 10, 20).
+		WillReturnRows(sqlmock.NewRows(findingTestColumns).
+			AddRow("finding-1", "codebase-1", "dead_code", "unused-function", "4f9c2b1e0a7d3c65", "function f is unused",
+				"billing/invoice.go", 42, 58, "{@acme/billing,@alice}", "low", "open", false, nil, nil, "task-1", "task-2", seenAt, seenAt, nil, seenAt))
+
+	findings, err := repo.ListFindings(context.Background(), "codebase-1", FindingFilter{
+		Status:    &status,
+		RuleID:    "unused-function",
+		Baselined: &baselined,
+		Owner:     "@acme/billing",
+		Limit:     10,
+		Offset:    20,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, findings, 1)
+	assert.Equal(t, models.FindingStatusOpen, findings[0].Status)
+	assert.Equal(t, models.FindingSeverityLow, findings[0].Severity)
+	assert.Equal(t, []string{"@acme/billing", "@alice"}, findings[0].Owners)
+	assert.Equal(t, "task-2", *findings[0].LastTaskID)
+	assert.Nil(t, findings[0].FixedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresFindingRepository_CountFindings(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresFindingRepositoryWithDB(db, "findings", "finding_baselines", DefaultBulkInsertBatchSize)
+
+	mock.ExpectQuery(`SELECT status, COUNT\(\*\) FROM findings WHERE codebase_id = \$1 AND .+ GROUP BY status`).
+		WithArgs("codebase-1", nil).
+		WillReturnRows(sqlmock.NewRows([]string{"status", "count"}).AddRow("open", 3).AddRow("ignored", 1))
+
+	counts, err := repo.CountFindings(context.Background(), "codebase-1", nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[models.FindingStatus]int{models.FindingStatusOpen: 3, models.FindingStatusIgnored: 1}, counts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresFindingRepository_UpdateFinding_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresFindingRepositoryWithDB(db, "findings", "finding_baselines", DefaultBulkInsertBatchSize)
+
+	mock.ExpectExec(`UPDATE findings SET status`).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.UpdateFinding(context.Background(), &models.Finding{FindingID: "finding-1", Status: models.FindingStatusFixed})
+
+	assert.True(t, errors.Is(err, apperrors.ErrNotFound))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresFindingRepository_SaveFindings_RollsBack(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresFindingRepositoryWithDB(db, "findings", "finding_baselines", DefaultBulkInsertBatchSize)
+
+	findings := make([]models.Finding, DefaultBulkInsertBatchSize+1)
+	for i := range findings {
+		findings[i].FindingID = fmt.Sprintf("finding-%d", i)
+		findings[i].Fingerprint = fmt.Sprintf("fingerprint-%d", i)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO findings .+ ON CONFLICT \(codebase_id, source, rule_id, fingerprint\) DO UPDATE`).WillReturnResult(sqlmock.NewResult(0, DefaultBulkInsertBatchSize))
+	mock.ExpectExec(`INSERT INTO findings`).WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	err = repo.SaveFindings(context.Background(), findings)
+
+	assert.ErrorContains(t, err, "connection reset")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresFindingRepository_SaveFindings_UpsertsInBatches(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresFindingRepositoryWithDB(db, "findings", "finding_baselines", DefaultBulkInsertBatchSize)
+	findings := make([]models.Finding, 2*DefaultBulkInsertBatchSize+1)
+	for i := range findings {
+		findings[i].FindingID = fmt.Sprintf("finding-%d", i)
+		findings[i].Fingerprint = fmt.Sprintf("fingerprint-%d", i)
+	}
+
+	// Each statement holds at most a batch of rows, each with 21 values, so its last parameter gives its row count
+	batch := fmt.Sprintf(`^INSERT INTO findings \(.+\) VALUES \(\$1, .+, \$%d\) ON CONFLICT \(codebase_id, source, rule_id, fingerprint\) DO UPDATE SET`, 21*DefaultBulkInsertBatchSize)
+	mock.ExpectBegin()
+	mock.ExpectExec(batch).WillReturnResult(sqlmock.NewResult(0, DefaultBulkInsertBatchSize))
+	mock.ExpectExec(batch).WillReturnResult(sqlmock.NewResult(0, DefaultBulkInsertBatchSize))
+	mock.ExpectExec(`^INSERT INTO findings \(.+\) VALUES \(\$1, [^(]+, \$21\) ON CONFLICT \(codebase_id, source, rule_id, fingerprint\) DO UPDATE SET`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = repo.SaveFindings(context.Background(), findings)
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresFindingRepository_SaveFindings_UsesBatchSize(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresFindingRepositoryWithDB(db, "findings", "finding_baselines", 2)
+	findings := make([]models.Finding, 3)
+	for i := range findings {
+		findings[i].FindingID = fmt.Sprintf("finding-%d", i)
+		findings[i].Fingerprint = fmt.Sprintf("fingerprint-%d", i)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`^INSERT INTO findings \(.+\) VALUES \(\$1, .+, \$21\), \(\$22, [^(]+, \$42\) ON CONFLICT`).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`^INSERT INTO findings \(.+\) VALUES \(\$1, [^(]+, \$21\) ON CONFLICT`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = repo.SaveFindings(context.Background(), findings)
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresFindingRepository_SaveFindings_WritesLastOfDuplicates(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresFindingRepositoryWithDB(db, "findings", "finding_baselines", DefaultBulkInsertBatchSize)
+	// A finding is identified by its codebase, source, rule and fingerprint, whatever its ID
+	findings := []models.Finding{
+		{FindingID: "finding-1", Fingerprint: "fingerprint-1", Message: "first"},
+		{FindingID: "finding-2", Fingerprint: "fingerprint-2", Message: "other"},
+		{FindingID: "finding-3", Fingerprint: "fingerprint-1", Message: "second"},
+	}
+
+	// The duplicate takes the place of the first one, the message being the sixth value of a row
+	args := make([]driver.Value, 42)
+	for i := range args {
+		args[i] = sqlmock.AnyArg()
+	}
+	args[0], args[5] = "finding-3", "second"
+	args[21], args[26] = "finding-2", "other"
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO findings .+ VALUES \(\$1, .+, \$21\), \(\$22, .+, \$42\) ON CONFLICT`).
+		WithArgs(args...).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	err = repo.SaveFindings(context.Background(), findings)
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresFindingRepository_Rebaseline(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresFindingRepositoryWithDB(db, "findings", "finding_baselines", DefaultBulkInsertBatchSize)
+	createdBy := "user-1"
+	baseline := &models.FindingBaseline{CodebaseID: "codebase-1", CreatedBy: &createdBy, CreatedAt: time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE findings SET baselined = \(status = \$2\) WHERE codebase_id = \$1`).
+		WithArgs("codebase-1", models.FindingStatusOpen).
+		WillReturnResult(sqlmock.NewResult(0, 5))
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM findings WHERE codebase_id = \$1 AND baselined`).
+		WithArgs("codebase-1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectExec(`INSERT INTO finding_baselines .+ ON CONFLICT \(codebase_id\) DO UPDATE`).
+		WithArgs("codebase-1", 3, &createdBy, baseline.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = repo.Rebaseline(context.Background(), baseline)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, baseline.FindingCount)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresFindingRepository_GetBaseline_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresFindingRepositoryWithDB(db, "findings", "finding_baselines", DefaultBulkInsertBatchSize)
+
+	mock.ExpectQuery(`SELECT .+ FROM finding_baselines WHERE codebase_id = \$1`).
+		WithArgs("codebase-1").
+		WillReturnRows(sqlmock.NewRows([]string{"codebase_id", "finding_count", "created_by", "created_at"}))
+
+	_, err = repo.GetBaseline(context.Background(), "codebase-1")
+
+	assert.True(t, errors.Is(err, apperrors.ErrNotFound))
+	assert.Equal(t, apperrors.CodeBaselineNotFound, apperrors.CodeOf(err))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
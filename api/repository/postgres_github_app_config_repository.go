@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// gitHubAppConfigColumns lists the GitHub App configuration columns in the order expected by scanGitHubAppConfig
+const gitHubAppConfigColumns = `organization, app_id, private_key, webhook_secret, project_id, agent_id, task_type,
+	fail_on_severity, created_at, updated_at`
+
+// PostgresGitHubAppConfigRepository implements GitHubAppConfigRepository using PostgreSQL
+type PostgresGitHubAppConfigRepository struct {
+	db        *sql.DB
+	tableName string
+}
+
+// NewPostgresGitHubAppConfigRepository creates a new PostgreSQL GitHub App configuration repository
+func NewPostgresGitHubAppConfigRepository(config PostgresConfig, tableName string) (GitHubAppConfigRepository, error) {
+	if tableName == "" {
+		tableName = conf.DefaultGitHubAppConfigsTableName
+	}
+
+	// Build connection string
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	repo := &PostgresGitHubAppConfigRepository{
+		db:        db,
+		tableName: tableName,
+	}
+
+	return repo, nil
+}
+
+// NewPostgresGitHubAppConfigRepositoryWithDB creates a new PostgreSQL GitHub App configuration repository with an existing DB connection
+func NewPostgresGitHubAppConfigRepositoryWithDB(db *sql.DB, tableName string) GitHubAppConfigRepository {
+	if tableName == "" {
+		tableName = conf.DefaultGitHubAppConfigsTableName
+	}
+
+	return &PostgresGitHubAppConfigRepository{
+		db:        db,
+		tableName: tableName,
+	}
+}
+
+// PutConfig stores the configuration of an organization, replacing the one it had
+func (r *PostgresGitHubAppConfigRepository) PutConfig(ctx context.Context, config *models.GitHubAppConfig) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (%s)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (organization) DO UPDATE SET
+			app_id = EXCLUDED.app_id,
+			private_key = EXCLUDED.private_key,
+			webhook_secret = EXCLUDED.webhook_secret,
+			project_id = EXCLUDED.project_id,
+			agent_id = EXCLUDED.agent_id,
+			task_type = EXCLUDED.task_type,
+			fail_on_severity = EXCLUDED.fail_on_severity,
+			updated_at = EXCLUDED.updated_at
+		RETURNING created_at
+	`, r.tableName, gitHubAppConfigColumns)
+
+	err := r.db.QueryRowContext(ctx, query,
+		config.Organization, config.AppID, config.PrivateKey, config.WebhookSecret, config.ProjectID, config.AgentID,
+		config.TaskType, config.FailOnSeverity, config.CreatedAt, config.UpdatedAt,
+	).Scan(&config.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to put GitHub App configuration: %w", err)
+	}
+
+	return nil
+}
+
+// GetConfig gets the configuration of an organization
+func (r *PostgresGitHubAppConfigRepository) GetConfig(ctx context.Context, organization string) (*models.GitHubAppConfig, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE organization = $1`, gitHubAppConfigColumns, r.tableName)
+
+	config, err := scanGitHubAppConfig(r.db.QueryRowContext(ctx, query, organization))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.NotFound(apperrors.CodeGitHubAppNotFound, "GitHub App not configured for organization: %s", organization)
+		}
+		return nil, fmt.Errorf("failed to get GitHub App configuration: %w", err)
+	}
+
+	return config, nil
+}
+
+// ListConfigs lists the configurations ordered by organization
+func (r *PostgresGitHubAppConfigRepository) ListConfigs(ctx context.Context) ([]models.GitHubAppConfig, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s ORDER BY organization`, gitHubAppConfigColumns, r.tableName)
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list GitHub App configurations: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close GitHub App configuration rows", "error", closeErr)
+		}
+	}()
+
+	configs := []models.GitHubAppConfig{}
+	for rows.Next() {
+		config, err := scanGitHubAppConfig(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan GitHub App configuration: %w", err)
+		}
+		configs = append(configs, *config)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate GitHub App configurations: %w", err)
+	}
+
+	return configs, nil
+}
+
+// DeleteConfig deletes the configuration of an organization
+func (r *PostgresGitHubAppConfigRepository) DeleteConfig(ctx context.Context, organization string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE organization = $1", r.tableName)
+
+	result, err := r.db.ExecContext(ctx, query, organization)
+	if err != nil {
+		return fmt.Errorf("failed to delete GitHub App configuration: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return apperrors.NotFound(apperrors.CodeGitHubAppNotFound, "GitHub App not configured for organization: %s", organization)
+	}
+
+	return nil
+}
+
+// scanGitHubAppConfig scans a single row selected with gitHubAppConfigColumns
+func scanGitHubAppConfig(row rowScanner) (*models.GitHubAppConfig, error) {
+	var config models.GitHubAppConfig
+
+	err := row.Scan(
+		&config.Organization, &config.AppID, &config.PrivateKey, &config.WebhookSecret, &config.ProjectID,
+		&config.AgentID, &config.TaskType, &config.FailOnSeverity, &config.CreatedAt, &config.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
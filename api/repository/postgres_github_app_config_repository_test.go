@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+func TestPostgresGitHubAppConfigRepository_PutConfig_KeepsCreationTime(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresGitHubAppConfigRepositoryWithDB(db, "github_app_configs")
+	createdAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+	now := createdAt.Add(time.Hour)
+	config := &models.GitHubAppConfig{
+		Organization: "acme", AppID: 42, PrivateKey: "key", WebhookSecret: "secret", ProjectID: "proj-1",
+		AgentID: "agent-1", TaskType: models.TaskTypeCodeReview, FailOnSeverity: models.CheckSeverityError,
+		CreatedAt: now, UpdatedAt: now,
+	}
+
+	mock.ExpectQuery(`INSERT INTO github_app_configs .+ ON CONFLICT \(organization\) DO UPDATE SET .+ RETURNING created_at`).
+		WithArgs("acme", int64(42), "key", "secret", "proj-1", "agent-1", models.TaskTypeCodeReview, models.CheckSeverityError, now, now).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(createdAt))
+
+	require.NoError(t, repo.PutConfig(context.Background(), config))
+
+	assert.Equal(t, createdAt, config.CreatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresGitHubAppConfigRepository_GetConfig_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresGitHubAppConfigRepositoryWithDB(db, "github_app_configs")
+
+	mock.ExpectQuery(`SELECT .+ FROM github_app_configs WHERE organization = \$1`).
+		WithArgs("acme").
+		WillReturnRows(sqlmock.NewRows([]string{"organization"}))
+
+	_, err = repo.GetConfig(context.Background(), "acme")
+
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	assert.Equal(t, apperrors.CodeGitHubAppNotFound, apperrors.CodeOf(err))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresGitHubAppConfigRepository_DeleteConfig_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresGitHubAppConfigRepositoryWithDB(db, "github_app_configs")
+
+	mock.ExpectExec(`DELETE FROM github_app_configs WHERE organization = \$1`).
+		WithArgs("acme").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.DeleteConfig(context.Background(), "acme")
+
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
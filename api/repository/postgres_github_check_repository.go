@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// gitHubCheckColumns lists the GitHub check columns in the order expected by scanGitHubCheck
+const gitHubCheckColumns = `task_id, organization, repository, installation_id, pull_request, head_sha, check_run_id,
+	status, created_at, published_at`
+
+// PostgresGitHubCheckRepository implements GitHubCheckRepository using PostgreSQL
+type PostgresGitHubCheckRepository struct {
+	db             *sql.DB
+	tableName      string
+	tasksTableName string
+}
+
+// NewPostgresGitHubCheckRepository creates a new PostgreSQL GitHub check repository
+func NewPostgresGitHubCheckRepository(config PostgresConfig, tableName, tasksTableName string) (GitHubCheckRepository, error) {
+	if tableName == "" {
+		tableName = conf.DefaultGitHubChecksTableName
+	}
+	if tasksTableName == "" {
+		tasksTableName = conf.DefaultTasksTableName
+	}
+
+	// Build connection string
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	repo := &PostgresGitHubCheckRepository{
+		db:             db,
+		tableName:      tableName,
+		tasksTableName: tasksTableName,
+	}
+
+	return repo, nil
+}
+
+// NewPostgresGitHubCheckRepositoryWithDB creates a new PostgreSQL GitHub check repository with an existing DB connection
+func NewPostgresGitHubCheckRepositoryWithDB(db *sql.DB, tableName, tasksTableName string) GitHubCheckRepository {
+	if tableName == "" {
+		tableName = conf.DefaultGitHubChecksTableName
+	}
+	if tasksTableName == "" {
+		tasksTableName = conf.DefaultTasksTableName
+	}
+
+	return &PostgresGitHubCheckRepository{
+		db:             db,
+		tableName:      tableName,
+		tasksTableName: tasksTableName,
+	}
+}
+
+// CreateCheck records the check run of a task
+func (r *PostgresGitHubCheckRepository) CreateCheck(ctx context.Context, check *models.GitHubCheck) error {
+	query := fmt.Sprintf(`INSERT INTO %s (%s) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		r.tableName, gitHubCheckColumns)
+
+	_, err := r.db.ExecContext(ctx, query,
+		check.TaskID, check.Organization, check.Repository, check.InstallationID, check.PullRequest, check.HeadSHA,
+		check.CheckRunID, check.Status, check.CreatedAt, check.PublishedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create GitHub check: %w", err)
+	}
+
+	return nil
+}
+
+// ListPublishable lists up to limit pending checks, oldest first, whose task finished or no longer exists
+func (r *PostgresGitHubCheckRepository) ListPublishable(ctx context.Context, limit int) ([]models.GitHubCheck, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s c
+		WHERE c.status = $1 AND NOT EXISTS (
+			SELECT 1 FROM %s t WHERE t.task_id = c.task_id AND t.status NOT IN ($2, $3, $4)
+		)
+		ORDER BY c.created_at
+		LIMIT $5
+	`, gitHubCheckColumns, r.tableName, r.tasksTableName)
+
+	rows, err := r.db.QueryContext(ctx, query, models.GitHubCheckStatusPending,
+		models.TaskStatusCompleted, models.TaskStatusFailed, models.TaskStatusCancelled, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list publishable GitHub checks: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close GitHub check rows", "error", closeErr)
+		}
+	}()
+
+	checks := []models.GitHubCheck{}
+	for rows.Next() {
+		check, err := scanGitHubCheck(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan GitHub check: %w", err)
+		}
+		checks = append(checks, *check)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate GitHub checks: %w", err)
+	}
+
+	return checks, nil
+}
+
+// MarkPublished marks the check of a task published
+func (r *PostgresGitHubCheckRepository) MarkPublished(ctx context.Context, taskID string, publishedAt time.Time) error {
+	query := fmt.Sprintf(`UPDATE %s SET status = $1, published_at = $2 WHERE task_id = $3`, r.tableName)
+
+	if _, err := r.db.ExecContext(ctx, query, models.GitHubCheckStatusPublished, publishedAt, taskID); err != nil {
+		return fmt.Errorf("failed to mark GitHub check published: %w", err)
+	}
+
+	return nil
+}
+
+// scanGitHubCheck scans a single row selected with gitHubCheckColumns
+func scanGitHubCheck(row rowScanner) (*models.GitHubCheck, error) {
+	var check models.GitHubCheck
+	var publishedAt sql.NullTime
+
+	err := row.Scan(
+		&check.TaskID, &check.Organization, &check.Repository, &check.InstallationID, &check.PullRequest,
+		&check.HeadSHA, &check.CheckRunID, &check.Status, &check.CreatedAt, &publishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if publishedAt.Valid {
+		check.PublishedAt = &publishedAt.Time
+	}
+
+	return &check, nil
+}
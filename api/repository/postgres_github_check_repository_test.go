@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+func TestPostgresGitHubCheckRepository_ListPublishable(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresGitHubCheckRepositoryWithDB(db, "github_checks", "tasks")
+	createdAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	columns := []string{"task_id", "organization", "repository", "installation_id", "pull_request", "head_sha",
+		"check_run_id", "status", "created_at", "published_at"}
+	mock.ExpectQuery(`SELECT .+ FROM github_checks c WHERE c.status = \$1 AND NOT EXISTS \(\s*SELECT 1 FROM tasks t .+\) ORDER BY c.created_at LIMIT \$5`).
+		WithArgs(models.GitHubCheckStatusPending, models.TaskStatusCompleted, models.TaskStatusFailed, models.TaskStatusCancelled, 10).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("task-1", "acme", "acme/widgets", int64(7), 12, "abc123", int64(4242), "pending", createdAt, nil))
+
+	checks, err := repo.ListPublishable(context.Background(), 10)
+
+	require.NoError(t, err)
+	require.Len(t, checks, 1)
+	assert.Equal(t, "acme/widgets", checks[0].Repository)
+	assert.Equal(t, int64(4242), checks[0].CheckRunID)
+	assert.Nil(t, checks[0].PublishedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresGitHubCheckRepository_MarkPublished(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresGitHubCheckRepositoryWithDB(db, "github_checks", "tasks")
+	publishedAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	mock.ExpectExec(`UPDATE github_checks SET status = \$1, published_at = \$2 WHERE task_id = \$3`).
+		WithArgs(models.GitHubCheckStatusPublished, publishedAt, "task-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.MarkPublished(context.Background(), "task-1", publishedAt))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// jiraIssueLinkColumns lists the Jira issue link columns in the order expected by scanJiraIssueLink
+const jiraIssueLinkColumns = `task_id, organization, issue_key, issue_url, synced_status, created_at, updated_at`
+
+// PostgresJiraIssueLinkRepository implements JiraIssueLinkRepository using PostgreSQL
+type PostgresJiraIssueLinkRepository struct {
+	db             *sql.DB
+	tableName      string
+	tasksTableName string
+}
+
+// NewPostgresJiraIssueLinkRepository creates a new PostgreSQL Jira issue link repository
+func NewPostgresJiraIssueLinkRepository(config PostgresConfig, tableName, tasksTableName string) (JiraIssueLinkRepository, error) {
+	if tableName == "" {
+		tableName = conf.DefaultJiraIssueLinksTableName
+	}
+	if tasksTableName == "" {
+		tasksTableName = conf.DefaultTasksTableName
+	}
+
+	// Build connection string
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	repo := &PostgresJiraIssueLinkRepository{
+		db:             db,
+		tableName:      tableName,
+		tasksTableName: tasksTableName,
+	}
+
+	return repo, nil
+}
+
+// NewPostgresJiraIssueLinkRepositoryWithDB creates a new PostgreSQL Jira issue link repository with an existing DB connection
+func NewPostgresJiraIssueLinkRepositoryWithDB(db *sql.DB, tableName, tasksTableName string) JiraIssueLinkRepository {
+	if tableName == "" {
+		tableName = conf.DefaultJiraIssueLinksTableName
+	}
+	if tasksTableName == "" {
+		tasksTableName = conf.DefaultTasksTableName
+	}
+
+	return &PostgresJiraIssueLinkRepository{
+		db:             db,
+		tableName:      tableName,
+		tasksTableName: tasksTableName,
+	}
+}
+
+// CreateLink links a task to an issue
+func (r *PostgresJiraIssueLinkRepository) CreateLink(ctx context.Context, link *models.JiraIssueLink) error {
+	query := fmt.Sprintf(`INSERT INTO %s (%s) VALUES ($1, $2, $3, $4, $5, $6, $7)`, r.tableName, jiraIssueLinkColumns)
+
+	_, err := r.db.ExecContext(ctx, query,
+		link.TaskID, link.Organization, link.IssueKey, link.IssueURL, link.SyncedStatus, link.CreatedAt, link.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create Jira issue link: %w", err)
+	}
+
+	return nil
+}
+
+// ListLinks lists the links of the tasks, those without one are left out
+func (r *PostgresJiraIssueLinkRepository) ListLinks(ctx context.Context, taskIDs []string) ([]models.JiraIssueLink, error) {
+	links := []models.JiraIssueLink{}
+	if len(taskIDs) == 0 {
+		return links, nil
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE task_id = ANY($1)`, jiraIssueLinkColumns, r.tableName)
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(taskIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Jira issue links: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close Jira issue link rows", "error", closeErr)
+		}
+	}()
+
+	for rows.Next() {
+		link, err := scanJiraIssueLink(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan Jira issue link: %w", err)
+		}
+		links = append(links, *link)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate Jira issue links: %w", err)
+	}
+
+	return links, nil
+}
+
+// ListUpdates lists up to limit links, least recently updated first, whose task changed status since it was last
+// reported on the issue
+func (r *PostgresJiraIssueLinkRepository) ListUpdates(ctx context.Context, limit int) ([]models.JiraIssueUpdate, error) {
+	query := fmt.Sprintf(`
+		SELECT l.task_id, l.organization, l.issue_key, l.issue_url, l.synced_status, l.created_at, l.updated_at,
+			t.status, t.title, t.error_message
+		FROM %s l
+		JOIN %s t ON t.task_id = l.task_id
+		WHERE t.status <> l.synced_status
+		ORDER BY l.updated_at
+		LIMIT $1
+	`, r.tableName, r.tasksTableName)
+
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Jira issue updates: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close Jira issue update rows", "error", closeErr)
+		}
+	}()
+
+	updates := []models.JiraIssueUpdate{}
+	for rows.Next() {
+		var update models.JiraIssueUpdate
+		link := &update.Link
+		err := rows.Scan(
+			&link.TaskID, &link.Organization, &link.IssueKey, &link.IssueURL, &link.SyncedStatus, &link.CreatedAt, &link.UpdatedAt,
+			&update.Status, &update.Title, &update.ErrorMessage,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan Jira issue update: %w", err)
+		}
+		updates = append(updates, update)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate Jira issue updates: %w", err)
+	}
+
+	return updates, nil
+}
+
+// MarkSynced records the task status last reported on the issue of a task
+func (r *PostgresJiraIssueLinkRepository) MarkSynced(ctx context.Context, taskID string, status models.TaskStatus, syncedAt time.Time) error {
+	query := fmt.Sprintf(`UPDATE %s SET synced_status = $1, updated_at = $2 WHERE task_id = $3`, r.tableName)
+
+	if _, err := r.db.ExecContext(ctx, query, status, syncedAt, taskID); err != nil {
+		return fmt.Errorf("failed to mark Jira issue link synced: %w", err)
+	}
+
+	return nil
+}
+
+// scanJiraIssueLink scans a single row selected with jiraIssueLinkColumns
+func scanJiraIssueLink(row rowScanner) (*models.JiraIssueLink, error) {
+	var link models.JiraIssueLink
+
+	err := row.Scan(
+		&link.TaskID, &link.Organization, &link.IssueKey, &link.IssueURL, &link.SyncedStatus, &link.CreatedAt, &link.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &link, nil
+}
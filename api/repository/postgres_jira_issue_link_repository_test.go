@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+func TestPostgresJiraIssueLinkRepository_ListLinks(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresJiraIssueLinkRepositoryWithDB(db, "jira_issue_links", "tasks")
+	createdAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	columns := []string{"task_id", "organization", "issue_key", "issue_url", "synced_status", "created_at", "updated_at"}
+	mock.ExpectQuery(`SELECT .+ FROM jira_issue_links WHERE task_id = ANY\(\$1\)`).
+		WithArgs(pq.Array([]string{"task-1", "task-2"})).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("task-1", "acme", "PAY-123", "https://acme.atlassian.net/browse/PAY-123", "pending", createdAt, createdAt))
+
+	links, err := repo.ListLinks(context.Background(), []string{"task-1", "task-2"})
+
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	assert.Equal(t, "PAY-123", links[0].IssueKey)
+	assert.Equal(t, models.TaskStatusPending, links[0].SyncedStatus)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresJiraIssueLinkRepository_ListLinks_NoTasks(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresJiraIssueLinkRepositoryWithDB(db, "jira_issue_links", "tasks")
+
+	links, err := repo.ListLinks(context.Background(), nil)
+
+	require.NoError(t, err)
+	assert.Empty(t, links)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresJiraIssueLinkRepository_ListUpdates(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresJiraIssueLinkRepositoryWithDB(db, "jira_issue_links", "tasks")
+	createdAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	columns := []string{"task_id", "organization", "issue_key", "issue_url", "synced_status", "created_at", "updated_at",
+		"status", "title", "error_message"}
+	mock.ExpectQuery(`SELECT .+ FROM jira_issue_links l\s+JOIN tasks t ON t.task_id = l.task_id\s+WHERE t.status <> l.synced_status\s+ORDER BY l.updated_at\s+LIMIT \$1`).
+		WithArgs(10).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("task-1", "acme", "PAY-123", "https://acme.atlassian.net/browse/PAY-123", "pending", createdAt, createdAt,
+				"completed", "Refactor payments", nil))
+
+	updates, err := repo.ListUpdates(context.Background(), 10)
+
+	require.NoError(t, err)
+	require.Len(t, updates, 1)
+	assert.Equal(t, "PAY-123", updates[0].Link.IssueKey)
+	assert.Equal(t, models.TaskStatusCompleted, updates[0].Status)
+	assert.Nil(t, updates[0].ErrorMessage)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresJiraIssueLinkRepository_MarkSynced(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresJiraIssueLinkRepositoryWithDB(db, "jira_issue_links", "tasks")
+	syncedAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	mock.ExpectExec(`UPDATE jira_issue_links SET synced_status = \$1, updated_at = \$2 WHERE task_id = \$3`).
+		WithArgs(models.TaskStatusCompleted, syncedAt, "task-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.MarkSynced(context.Background(), "task-1", models.TaskStatusCompleted, syncedAt))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// jiraSiteColumns lists the Jira site columns in the order expected by scanJiraSite
+const jiraSiteColumns = `organization, site_url, email, api_token, project_keys, done_transition, created_at, updated_at`
+
+// PostgresJiraSiteRepository implements JiraSiteRepository using PostgreSQL
+type PostgresJiraSiteRepository struct {
+	db        *sql.DB
+	tableName string
+}
+
+// NewPostgresJiraSiteRepository creates a new PostgreSQL Jira site repository
+func NewPostgresJiraSiteRepository(config PostgresConfig, tableName string) (JiraSiteRepository, error) {
+	if tableName == "" {
+		tableName = conf.DefaultJiraSitesTableName
+	}
+
+	// Build connection string
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	repo := &PostgresJiraSiteRepository{
+		db:        db,
+		tableName: tableName,
+	}
+
+	return repo, nil
+}
+
+// NewPostgresJiraSiteRepositoryWithDB creates a new PostgreSQL Jira site repository with an existing DB connection
+func NewPostgresJiraSiteRepositoryWithDB(db *sql.DB, tableName string) JiraSiteRepository {
+	if tableName == "" {
+		tableName = conf.DefaultJiraSitesTableName
+	}
+
+	return &PostgresJiraSiteRepository{
+		db:        db,
+		tableName: tableName,
+	}
+}
+
+// PutSite stores the site of an organization, replacing the one it had
+func (r *PostgresJiraSiteRepository) PutSite(ctx context.Context, site *models.JiraSite) error {
+	projectKeysJSON, err := json.Marshal(site.ProjectKeys)
+	if err != nil {
+		return fmt.Errorf("failed to marshal project keys: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (%s)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (organization) DO UPDATE SET
+			site_url = EXCLUDED.site_url,
+			email = EXCLUDED.email,
+			api_token = EXCLUDED.api_token,
+			project_keys = EXCLUDED.project_keys,
+			done_transition = EXCLUDED.done_transition,
+			updated_at = EXCLUDED.updated_at
+		RETURNING created_at
+	`, r.tableName, jiraSiteColumns)
+
+	err = r.db.QueryRowContext(ctx, query,
+		site.Organization, site.SiteURL, site.Email, site.APIToken, projectKeysJSON, site.DoneTransition,
+		site.CreatedAt, site.UpdatedAt,
+	).Scan(&site.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to put Jira site: %w", err)
+	}
+
+	return nil
+}
+
+// GetSite gets the site of an organization
+func (r *PostgresJiraSiteRepository) GetSite(ctx context.Context, organization string) (*models.JiraSite, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE organization = $1`, jiraSiteColumns, r.tableName)
+
+	site, err := scanJiraSite(r.db.QueryRowContext(ctx, query, organization))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.NotFound(apperrors.CodeJiraSiteNotFound, "Jira site not configured for organization: %s", organization)
+		}
+		return nil, fmt.Errorf("failed to get Jira site: %w", err)
+	}
+
+	return site, nil
+}
+
+// GetSiteByProjectKey gets the site listing the Jira project key
+func (r *PostgresJiraSiteRepository) GetSiteByProjectKey(ctx context.Context, projectKey string) (*models.JiraSite, error) {
+	projectKeyJSON, err := json.Marshal([]string{projectKey})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal project key: %w", err)
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE project_keys @> $1 ORDER BY organization LIMIT 1`, jiraSiteColumns, r.tableName)
+
+	site, err := scanJiraSite(r.db.QueryRowContext(ctx, query, projectKeyJSON))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.NotFound(apperrors.CodeJiraSiteNotFound, "no Jira site lists project key: %s", projectKey)
+		}
+		return nil, fmt.Errorf("failed to get Jira site: %w", err)
+	}
+
+	return site, nil
+}
+
+// ListSites lists the sites ordered by organization
+func (r *PostgresJiraSiteRepository) ListSites(ctx context.Context) ([]models.JiraSite, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s ORDER BY organization`, jiraSiteColumns, r.tableName)
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Jira sites: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close Jira site rows", "error", closeErr)
+		}
+	}()
+
+	sites := []models.JiraSite{}
+	for rows.Next() {
+		site, err := scanJiraSite(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan Jira site: %w", err)
+		}
+		sites = append(sites, *site)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate Jira sites: %w", err)
+	}
+
+	return sites, nil
+}
+
+// DeleteSite deletes the site of an organization
+func (r *PostgresJiraSiteRepository) DeleteSite(ctx context.Context, organization string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE organization = $1", r.tableName)
+
+	result, err := r.db.ExecContext(ctx, query, organization)
+	if err != nil {
+		return fmt.Errorf("failed to delete Jira site: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return apperrors.NotFound(apperrors.CodeJiraSiteNotFound, "Jira site not configured for organization: %s", organization)
+	}
+
+	return nil
+}
+
+// scanJiraSite scans a single row selected with jiraSiteColumns
+func scanJiraSite(row rowScanner) (*models.JiraSite, error) {
+	var site models.JiraSite
+	var projectKeysJSON []byte
+
+	err := row.Scan(
+		&site.Organization, &site.SiteURL, &site.Email, &site.APIToken, &projectKeysJSON, &site.DoneTransition,
+		&site.CreatedAt, &site.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(projectKeysJSON, &site.ProjectKeys); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal project keys of organization %s: %w", site.Organization, err)
+	}
+
+	return &site, nil
+}
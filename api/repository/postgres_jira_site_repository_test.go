@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+var jiraSiteTestColumns = []string{"organization", "site_url", "email", "api_token", "project_keys", "done_transition",
+	"created_at", "updated_at"}
+
+func TestPostgresJiraSiteRepository_PutSite(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresJiraSiteRepositoryWithDB(db, "jira_sites")
+	createdAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+	now := createdAt.Add(time.Hour)
+	site := &models.JiraSite{
+		Organization:   "acme",
+		SiteURL:        "https://acme.atlassian.net",
+		Email:          "bot@acme.com",
+		APIToken:       "token",
+		ProjectKeys:    []string{"PAY", "AUTH"},
+		DoneTransition: "Done",
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	mock.ExpectQuery(`INSERT INTO jira_sites .+ ON CONFLICT \(organization\) DO UPDATE SET .+ RETURNING created_at`).
+		WithArgs("acme", "https://acme.atlassian.net", "bot@acme.com", "token", []byte(`["PAY","AUTH"]`), "Done", now, now).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(createdAt))
+
+	require.NoError(t, repo.PutSite(context.Background(), site))
+	assert.Equal(t, createdAt, site.CreatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresJiraSiteRepository_GetSiteByProjectKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresJiraSiteRepositoryWithDB(db, "jira_sites")
+	createdAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT .+ FROM jira_sites WHERE project_keys @> \$1`).
+		WithArgs([]byte(`["PAY"]`)).
+		WillReturnRows(sqlmock.NewRows(jiraSiteTestColumns).
+			AddRow("acme", "https://acme.atlassian.net", "bot@acme.com", "token", []byte(`["PAY","AUTH"]`), "Done", createdAt, createdAt))
+
+	site, err := repo.GetSiteByProjectKey(context.Background(), "PAY")
+
+	require.NoError(t, err)
+	assert.Equal(t, "acme", site.Organization)
+	assert.Equal(t, []string{"PAY", "AUTH"}, site.ProjectKeys)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresJiraSiteRepository_GetSite_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresJiraSiteRepositoryWithDB(db, "jira_sites")
+
+	mock.ExpectQuery(`SELECT .+ FROM jira_sites WHERE organization = \$1`).
+		WithArgs("acme").
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetSite(context.Background(), "acme")
+
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
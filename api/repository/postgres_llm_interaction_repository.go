@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// llmInteractionColumns lists the LLM interaction columns in the order expected by scanLLMInteraction
+const llmInteractionColumns = `interaction_id, task_id, project_id, executor, step, prompt, response, context_doc_ids, latency_ms, prompt_tokens, response_tokens, content_logged, error, created_at`
+
+// PostgresLLMInteractionRepository implements LLMInteractionRepository using PostgreSQL
+type PostgresLLMInteractionRepository struct {
+	db        *sql.DB
+	tableName string
+}
+
+// NewPostgresLLMInteractionRepository creates a new PostgreSQL LLM interaction repository
+func NewPostgresLLMInteractionRepository(config PostgresConfig, tableName string) (LLMInteractionRepository, error) {
+	if tableName == "" {
+		tableName = conf.DefaultLLMInteractionsTableName
+	}
+
+	// Build connection string
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	repo := &PostgresLLMInteractionRepository{
+		db:        db,
+		tableName: tableName,
+	}
+
+	return repo, nil
+}
+
+// NewPostgresLLMInteractionRepositoryWithDB creates a new PostgreSQL LLM interaction repository with an existing DB connection
+func NewPostgresLLMInteractionRepositoryWithDB(db *sql.DB, tableName string) LLMInteractionRepository {
+	if tableName == "" {
+		tableName = conf.DefaultLLMInteractionsTableName
+	}
+
+	return &PostgresLLMInteractionRepository{
+		db:        db,
+		tableName: tableName,
+	}
+}
+
+// CreateInteraction stores an LLM call. The prompt and response are stored as NULL when they weren't logged.
+func (r *PostgresLLMInteractionRepository) CreateInteraction(ctx context.Context, interaction *models.LLMInteraction) error {
+	var promptJSON, responseJSON []byte
+	var err error
+	if interaction.Prompt != nil {
+		if promptJSON, err = json.Marshal(interaction.Prompt); err != nil {
+			return fmt.Errorf("failed to marshal LLM prompt: %w", err)
+		}
+	}
+	if interaction.Response != nil {
+		if responseJSON, err = json.Marshal(interaction.Response); err != nil {
+			return fmt.Errorf("failed to marshal LLM response: %w", err)
+		}
+	}
+	docs := interaction.ContextDocIDs
+	if docs == nil {
+		docs = []string{}
+	}
+	docsJSON, err := json.Marshal(docs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal LLM context documents: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (%s)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	`, r.tableName, llmInteractionColumns)
+
+	_, err = r.db.ExecContext(ctx, query,
+		interaction.InteractionID, interaction.TaskID, interaction.ProjectID, interaction.Executor, interaction.Step,
+		promptJSON, responseJSON, docsJSON, interaction.LatencyMS, interaction.PromptTokens, interaction.ResponseTokens,
+		interaction.ContentLogged, interaction.Error, interaction.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create LLM interaction: %w", err)
+	}
+
+	return nil
+}
+
+// ListInteractions lists the LLM calls made for a task, oldest first
+func (r *PostgresLLMInteractionRepository) ListInteractions(ctx context.Context, taskID string) ([]models.LLMInteraction, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s
+		WHERE task_id = $1
+		ORDER BY created_at, step
+	`, llmInteractionColumns, r.tableName)
+
+	rows, err := r.db.QueryContext(ctx, query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list LLM interactions: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close LLM interaction rows", "error", closeErr)
+		}
+	}()
+
+	interactions := []models.LLMInteraction{}
+	for rows.Next() {
+		interaction, err := scanLLMInteraction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan LLM interaction: %w", err)
+		}
+		interactions = append(interactions, *interaction)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate LLM interactions: %w", err)
+	}
+
+	return interactions, nil
+}
+
+// DeleteBefore deletes the LLM calls made before the given time
+func (r *PostgresLLMInteractionRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE created_at < $1`, r.tableName)
+
+	result, err := r.db.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete LLM interactions: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// scanLLMInteraction scans a single row selected with llmInteractionColumns
+func scanLLMInteraction(row rowScanner) (*models.LLMInteraction, error) {
+	var interaction models.LLMInteraction
+	var promptJSON, responseJSON, docsJSON []byte
+
+	err := row.Scan(
+		&interaction.InteractionID, &interaction.TaskID, &interaction.ProjectID, &interaction.Executor, &interaction.Step,
+		&promptJSON, &responseJSON, &docsJSON, &interaction.LatencyMS, &interaction.PromptTokens,
+		&interaction.ResponseTokens, &interaction.ContentLogged, &interaction.Error, &interaction.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if promptJSON != nil {
+		if err := json.Unmarshal(promptJSON, &interaction.Prompt); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal prompt of LLM interaction %s: %w", interaction.InteractionID, err)
+		}
+	}
+	if responseJSON != nil {
+		if err := json.Unmarshal(responseJSON, &interaction.Response); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal response of LLM interaction %s: %w", interaction.InteractionID, err)
+		}
+	}
+	if err := json.Unmarshal(docsJSON, &interaction.ContextDocIDs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal context documents of LLM interaction %s: %w", interaction.InteractionID, err)
+	}
+
+	return &interaction, nil
+}
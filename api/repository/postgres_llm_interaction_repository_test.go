@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+func TestPostgresLLMInteractionRepository_CreateInteraction_WithoutContent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresLLMInteractionRepositoryWithDB(db, "llm_interactions")
+	createdAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	mock.ExpectExec(`INSERT INTO llm_interactions`).
+		WithArgs("llm-1", "task-1", "proj-1", "local_agent", 2, []byte(nil), []byte(nil), []byte(`["main.go"]`),
+			int64(1500), 300, 20, false, "", createdAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.CreateInteraction(context.Background(), &models.LLMInteraction{
+		InteractionID: "llm-1", TaskID: "task-1", ProjectID: "proj-1", Executor: "local_agent", Step: 2,
+		ContextDocIDs: []string{"main.go"}, LatencyMS: 1500, PromptTokens: 300, ResponseTokens: 20,
+		CreatedAt: createdAt,
+	})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresLLMInteractionRepository_ListInteractions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresLLMInteractionRepositoryWithDB(db, "llm_interactions")
+	createdAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	columns := []string{"interaction_id", "task_id", "project_id", "executor", "step", "prompt", "response",
+		"context_doc_ids", "latency_ms", "prompt_tokens", "response_tokens", "content_logged", "error", "created_at"}
+	mock.ExpectQuery(`SELECT .+ FROM llm_interactions\s+WHERE task_id = \$1\s+ORDER BY created_at, step`).
+		WithArgs("task-1").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("llm-1", "task-1", "proj-1", "local_agent", 1, []byte(`[{"role":"user","content":"Fix it"}]`),
+				[]byte(`{"role":"assistant","content":"Done."}`), []byte(`[]`), 900, 120, 5, true, "", createdAt).
+			AddRow("llm-2", "task-1", "proj-1", "local_agent", 2, nil, nil, []byte(`["main.go"]`), 1100, 0, 0, false,
+				"model not found", createdAt))
+
+	interactions, err := repo.ListInteractions(context.Background(), "task-1")
+
+	require.NoError(t, err)
+	require.Len(t, interactions, 2)
+	assert.Equal(t, []models.LLMMessage{{Role: "user", Content: "Fix it"}}, interactions[0].Prompt)
+	assert.Equal(t, "Done.", interactions[0].Response.Content)
+	assert.Nil(t, interactions[1].Prompt)
+	assert.Nil(t, interactions[1].Response)
+	assert.Equal(t, []string{"main.go"}, interactions[1].ContextDocIDs)
+	assert.Equal(t, "model not found", interactions[1].Error)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresLLMInteractionRepository_DeleteBefore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresLLMInteractionRepositoryWithDB(db, "llm_interactions")
+	before := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	mock.ExpectExec(`DELETE FROM llm_interactions WHERE created_at < \$1`).
+		WithArgs(before).
+		WillReturnResult(sqlmock.NewResult(0, 7))
+
+	deleted, err := repo.DeleteBefore(context.Background(), before)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// mfaRecoveryCodeColumns are the columns written by ReplaceRecoveryCodes, in order
+const mfaRecoveryCodeColumns = `user_id, code_hash, created_at`
+
+// PostgresMFARecoveryCodeRepository implements MFARecoveryCodeRepository using PostgreSQL
+type PostgresMFARecoveryCodeRepository struct {
+	db        *sql.DB
+	tableName string
+}
+
+// NewPostgresMFARecoveryCodeRepository creates a new PostgreSQL MFA recovery code repository
+func NewPostgresMFARecoveryCodeRepository(config PostgresConfig, tableName string) (MFARecoveryCodeRepository, error) {
+	if tableName == "" {
+		tableName = conf.DefaultMFARecoveryCodesTableName
+	}
+
+	// Build connection string
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	repo := &PostgresMFARecoveryCodeRepository{
+		db:        db,
+		tableName: tableName,
+	}
+
+	return repo, nil
+}
+
+// NewPostgresMFARecoveryCodeRepositoryWithDB creates a new PostgreSQL MFA recovery code repository with an existing DB
+// connection
+func NewPostgresMFARecoveryCodeRepositoryWithDB(db *sql.DB, tableName string) MFARecoveryCodeRepository {
+	if tableName == "" {
+		tableName = conf.DefaultMFARecoveryCodesTableName
+	}
+
+	return &PostgresMFARecoveryCodeRepository{
+		db:        db,
+		tableName: tableName,
+	}
+}
+
+// ReplaceRecoveryCodes deletes the previous recovery codes of a user and stores the new ones within a single
+// transaction, so the previous codes stop working as soon as the new ones do
+func (r *PostgresMFARecoveryCodeRepository) ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				slog.WarnContext(ctx, "failed to rollback recovery codes replacement", "error", rollbackErr)
+			}
+		}
+	}()
+
+	deleteQuery := fmt.Sprintf(`DELETE FROM %s WHERE user_id = $1`, r.tableName)
+	if _, err = tx.ExecContext(ctx, deleteQuery, userID); err != nil {
+		return fmt.Errorf("failed to delete previous recovery codes: %w", err)
+	}
+
+	createdAt := time.Now()
+	rows := make([][]any, 0, len(codeHashes))
+	for _, codeHash := range codeHashes {
+		rows = append(rows, []any{userID, codeHash, createdAt})
+	}
+	if err = bulkInsert(ctx, tx, r.tableName, mfaRecoveryCodeColumns, rows, 0); err != nil {
+		return fmt.Errorf("failed to create recovery codes: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit recovery codes: %w", err)
+	}
+
+	return nil
+}
+
+// ConsumeRecoveryCode marks an unused recovery code of a user used. The update is conditional, so a code can't be
+// used twice by concurrent requests.
+func (r *PostgresMFARecoveryCodeRepository) ConsumeRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error) {
+	query := fmt.Sprintf(`
+		UPDATE %s
+		SET used_at = NOW()
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`, r.tableName)
+
+	result, err := r.db.ExecContext(ctx, query, userID, codeHash)
+	if err != nil {
+		return false, fmt.Errorf("failed to consume recovery code: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
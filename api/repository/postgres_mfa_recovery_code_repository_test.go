@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresMFARecoveryCodeRepository_ReplaceRecoveryCodes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresMFARecoveryCodeRepositoryWithDB(db, "mfa_recovery_codes")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM mfa_recovery_codes WHERE user_id = \$1`).
+		WithArgs("user-1").
+		WillReturnResult(sqlmock.NewResult(0, 10))
+	mock.ExpectExec(`INSERT INTO mfa_recovery_codes \(user_id, code_hash, created_at\) VALUES \(\$1, \$2, \$3\), \(\$4, \$5, \$6\)`).
+		WithArgs("user-1", "hash-1", sqlmock.AnyArg(), "user-1", "hash-2", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	err = repo.ReplaceRecoveryCodes(context.Background(), "user-1", []string{"hash-1", "hash-2"})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresMFARecoveryCodeRepository_ConsumeRecoveryCode(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresMFARecoveryCodeRepositoryWithDB(db, "mfa_recovery_codes")
+
+	query := `UPDATE mfa_recovery_codes\s+SET used_at = NOW\(\)\s+WHERE user_id = \$1 AND code_hash = \$2 AND used_at IS NULL`
+	mock.ExpectExec(query).WithArgs("user-1", "hash-1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(query).WithArgs("user-1", "hash-1").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	consumed, err := repo.ConsumeRecoveryCode(context.Background(), "user-1", "hash-1")
+	require.NoError(t, err)
+	assert.True(t, consumed)
+
+	// A used code can't be used again
+	consumed, err = repo.ConsumeRecoveryCode(context.Background(), "user-1", "hash-1")
+	require.NoError(t, err)
+	assert.False(t, consumed)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,1040 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// postgresMigrationLock is the key of the advisory lock held while migrating, so instances starting together migrate
+// the database one at a time. It spells "schema" in ASCII.
+const postgresMigrationLock = 0x736368656d61
+
+// MigratePostgres migrates the PostgreSQL database of config to the schema of the Postgres repositories, which don't
+// create their tables themselves. It runs before any of them is created.
+func MigratePostgres(ctx context.Context, config PostgresConfig) error {
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return fmt.Errorf("failed to open PostgreSQL connection: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	return migratePostgres(ctx, db)
+}
+
+// migratePostgres applies the Postgres migrations to db while holding the migration lock
+func migratePostgres(ctx context.Context, db *sql.DB) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to PostgreSQL database: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", postgresMigrationLock); err != nil {
+		return fmt.Errorf("failed to lock PostgreSQL schema: %w", err)
+	}
+	defer func() {
+		_, _ = conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", postgresMigrationLock)
+	}()
+
+	if err := Migrate(ctx, db, postgresMigrations); err != nil {
+		return fmt.Errorf("failed to migrate PostgreSQL database: %w", err)
+	}
+	return nil
+}
+
+// postgresMigrations is the schema of the Postgres repositories, with their default table names. The first migrations
+// are the schema the repositories created themselves before it was migrated, so they are idempotent: a database
+// created then adopts its migrations without change.
+var postgresMigrations = []Migration{
+	{
+		Version:     1,
+		Description: "create agents",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS agents (
+				agent_id VARCHAR(255) PRIMARY KEY,
+				agent_version VARCHAR(255) NOT NULL,
+				knowledge_base_id VARCHAR(255) NOT NULL,
+				vector_store_id VARCHAR(255) NOT NULL,
+				repository_url TEXT NOT NULL,
+				branch VARCHAR(255),
+				agent_name VARCHAR(255),
+				status VARCHAR(50) NOT NULL,
+				created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+				updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+			);
+
+			ALTER TABLE agents ADD COLUMN IF NOT EXISTS project_id VARCHAR(255);
+			ALTER TABLE agents ADD COLUMN IF NOT EXISTS region VARCHAR(32);
+
+			ALTER TABLE agents ADD COLUMN IF NOT EXISTS embedding_model VARCHAR(128),
+				ADD COLUMN IF NOT EXISTS embedding_dimensions INTEGER;
+
+			CREATE INDEX IF NOT EXISTS idx_agents_status ON agents(status);
+			CREATE INDEX IF NOT EXISTS idx_agents_created_at ON agents(created_at DESC);
+		`,
+	},
+	{
+		Version:     2,
+		Description: "create projects",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS projects (
+				project_id VARCHAR(255) PRIMARY KEY,
+				name VARCHAR(255) NOT NULL,
+				description TEXT,
+				language VARCHAR(50),
+				status VARCHAR(50) NOT NULL DEFAULT 'active',
+				created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+				updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+				version BIGINT NOT NULL DEFAULT 1,
+				tags JSONB DEFAULT '{}',
+				metadata JSONB DEFAULT '{}',
+				vector_store VARCHAR(32) NOT NULL DEFAULT '',
+				embedding_model VARCHAR(128) NOT NULL DEFAULT '',
+				embedding_dimensions INTEGER NOT NULL DEFAULT 0
+			);
+
+			ALTER TABLE projects ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+			ALTER TABLE projects ADD COLUMN IF NOT EXISTS vector_store VARCHAR(32) NOT NULL DEFAULT '';
+			ALTER TABLE projects ADD COLUMN IF NOT EXISTS embedding_model VARCHAR(128) NOT NULL DEFAULT '', ADD COLUMN IF NOT EXISTS embedding_dimensions INTEGER NOT NULL DEFAULT 0;
+			CREATE INDEX IF NOT EXISTS idx_projects_name ON projects (name);
+			CREATE INDEX IF NOT EXISTS idx_projects_status ON projects (status);
+			CREATE INDEX IF NOT EXISTS idx_projects_created_at ON projects (created_at);
+			CREATE INDEX IF NOT EXISTS idx_projects_tags ON projects USING GIN (tags);
+		`,
+	},
+	{
+		Version:     3,
+		Description: "create codebases",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS codebases (
+				codebase_id UUID PRIMARY KEY,
+				project_id VARCHAR(255) NOT NULL,
+				name VARCHAR(255) NOT NULL,
+				provider VARCHAR(50) NOT NULL,
+				url TEXT NOT NULL,
+				config_id VARCHAR(255) NOT NULL,
+				status VARCHAR(50) NOT NULL DEFAULT 'active',
+				last_sync_at TIMESTAMP WITH TIME ZONE,
+				created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+				updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+				metadata JSONB,
+				tags JSONB,
+				CONSTRAINT fk_project FOREIGN KEY (project_id) REFERENCES projects(project_id) ON DELETE CASCADE
+			);
+			CREATE INDEX IF NOT EXISTS idx_codebases_project_id ON codebases (project_id);
+			CREATE INDEX IF NOT EXISTS idx_codebases_provider ON codebases (provider);
+			CREATE INDEX IF NOT EXISTS idx_codebases_created_at ON codebases (created_at);
+			ALTER TABLE codebases ADD COLUMN IF NOT EXISTS ingestion_error TEXT;
+			ALTER TABLE codebases ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP WITH TIME ZONE;
+			ALTER TABLE codebases ADD COLUMN IF NOT EXISTS checkout JSONB;
+		`,
+	},
+	{
+		Version:     4,
+		Description: "create tasks",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS tasks (
+				task_id VARCHAR(255) PRIMARY KEY,
+				project_id VARCHAR(255) NOT NULL,
+				batch_id VARCHAR(255),
+				campaign_id VARCHAR(255),
+				created_by VARCHAR(255),
+				agent_id VARCHAR(255) NOT NULL,
+				codebase_id VARCHAR(255),
+				branch VARCHAR(255),
+				commit_sha VARCHAR(64),
+				type VARCHAR(50) NOT NULL,
+				analysis_mode VARCHAR(50),
+				status VARCHAR(50) NOT NULL DEFAULT 'pending',
+				title VARCHAR(500) NOT NULL,
+				description TEXT NOT NULL,
+				input JSONB,
+				output JSONB,
+				error_message TEXT,
+				created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+				updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+				completed_at TIMESTAMP WITH TIME ZONE,
+				metadata JSONB,
+				tags JSONB,
+
+				-- Indexes for performance
+				CONSTRAINT tasks_type_check CHECK (type IN ('code_analysis', 'refactoring', 'code_review', 'documentation', 'custom', 'dependency_audit', 'codemod', 'coverage_gap')),
+				CONSTRAINT tasks_status_check CHECK (status IN ('pending', 'queued', 'in_progress', 'completed', 'failed', 'cancelled'))
+			);
+
+			-- Columns added after the initial schema
+			ALTER TABLE tasks ADD COLUMN IF NOT EXISTS batch_id VARCHAR(255);
+			ALTER TABLE tasks ADD COLUMN IF NOT EXISTS created_by VARCHAR(255);
+			ALTER TABLE tasks ADD COLUMN IF NOT EXISTS branch VARCHAR(255);
+			ALTER TABLE tasks ADD COLUMN IF NOT EXISTS commit_sha VARCHAR(64);
+			ALTER TABLE tasks ADD COLUMN IF NOT EXISTS campaign_id VARCHAR(255);
+			ALTER TABLE tasks ADD COLUMN IF NOT EXISTS analysis_mode VARCHAR(50);
+			ALTER TABLE tasks ADD COLUMN IF NOT EXISTS heartbeat_at TIMESTAMP WITH TIME ZONE;
+			ALTER TABLE tasks ADD COLUMN IF NOT EXISTS progress INTEGER NOT NULL DEFAULT 0;
+			ALTER TABLE tasks ADD COLUMN IF NOT EXISTS progress_step VARCHAR(100);
+			ALTER TABLE tasks ADD COLUMN IF NOT EXISTS experiment_id VARCHAR(255);
+			ALTER TABLE tasks ADD COLUMN IF NOT EXISTS experiment_arm VARCHAR(20);
+			ALTER TABLE tasks ADD COLUMN IF NOT EXISTS approved BOOLEAN;
+			ALTER TABLE tasks ADD COLUMN IF NOT EXISTS priority VARCHAR(20) NOT NULL DEFAULT 'normal';
+			ALTER TABLE tasks ADD COLUMN IF NOT EXISTS queued_at TIMESTAMP WITH TIME ZONE;
+
+			-- Task types added after the initial schema
+			ALTER TABLE tasks DROP CONSTRAINT IF EXISTS tasks_type_check;
+			ALTER TABLE tasks ADD CONSTRAINT tasks_type_check CHECK (type IN ('code_analysis', 'refactoring', 'code_review', 'documentation', 'custom', 'dependency_audit', 'codemod', 'coverage_gap'));
+
+			-- Task statuses added after the initial schema
+			ALTER TABLE tasks DROP CONSTRAINT IF EXISTS tasks_status_check;
+			ALTER TABLE tasks ADD CONSTRAINT tasks_status_check CHECK (status IN ('pending', 'queued', 'in_progress', 'completed', 'failed', 'cancelled'));
+
+			-- Create indexes
+			CREATE INDEX IF NOT EXISTS idx_tasks_project_id ON tasks (project_id);
+			CREATE INDEX IF NOT EXISTS idx_tasks_batch_id ON tasks (batch_id);
+			CREATE INDEX IF NOT EXISTS idx_tasks_campaign_id ON tasks (campaign_id);
+			CREATE INDEX IF NOT EXISTS idx_tasks_experiment_id ON tasks (experiment_id);
+			CREATE INDEX IF NOT EXISTS idx_tasks_agent_id ON tasks (agent_id);
+			CREATE INDEX IF NOT EXISTS idx_tasks_codebase_id ON tasks (codebase_id);
+			CREATE INDEX IF NOT EXISTS idx_tasks_status ON tasks (status);
+			CREATE INDEX IF NOT EXISTS idx_tasks_type ON tasks (type);
+			CREATE INDEX IF NOT EXISTS idx_tasks_created_at ON tasks (created_at);
+			CREATE INDEX IF NOT EXISTS idx_tasks_project_status ON tasks (project_id, status);
+			CREATE INDEX IF NOT EXISTS idx_tasks_in_progress_heartbeat ON tasks (COALESCE(heartbeat_at, updated_at)) WHERE status = 'in_progress';
+			CREATE INDEX IF NOT EXISTS idx_tasks_queued ON tasks (priority, queued_at) WHERE status = 'queued';
+		`,
+	},
+	{
+		Version:     5,
+		Description: "create users",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS users (
+				user_id VARCHAR(255) PRIMARY KEY,
+				auth_id VARCHAR(255) UNIQUE NOT NULL,
+				email VARCHAR(255) UNIQUE NOT NULL,
+				username VARCHAR(255) UNIQUE NOT NULL,
+				first_name VARCHAR(255),
+				last_name VARCHAR(255),
+				role VARCHAR(50) NOT NULL DEFAULT 'developer',
+				status VARCHAR(50) NOT NULL DEFAULT 'active',
+				created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+				updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+				CONSTRAINT valid_status CHECK (status IN ('active', 'inactive', 'pending', 'suspended'))
+			);
+
+			ALTER TABLE users DROP CONSTRAINT IF EXISTS valid_role;
+			CREATE INDEX IF NOT EXISTS idx_users_auth_id ON users (auth_id);
+			CREATE INDEX IF NOT EXISTS idx_users_email ON users (email);
+			CREATE INDEX IF NOT EXISTS idx_users_username ON users (username);
+			CREATE INDEX IF NOT EXISTS idx_users_role ON users (role);
+			CREATE INDEX IF NOT EXISTS idx_users_status ON users (status);
+			CREATE INDEX IF NOT EXISTS idx_users_created_at ON users (created_at);
+		`,
+	},
+	{
+		Version:     6,
+		Description: "create MFA recovery codes",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS mfa_recovery_codes (
+				user_id VARCHAR(255) NOT NULL,
+				code_hash CHAR(64) NOT NULL,
+				created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+				used_at TIMESTAMP WITH TIME ZONE,
+				PRIMARY KEY (user_id, code_hash)
+			);
+		`,
+	},
+	{
+		Version:     7,
+		Description: "create device authorizations",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS device_authorizations (
+				device_code_hash CHAR(64) PRIMARY KEY,
+				user_code VARCHAR(9) NOT NULL UNIQUE,
+				client_name VARCHAR(100) NOT NULL DEFAULT '',
+				status VARCHAR(20) NOT NULL,
+				user_id VARCHAR(255),
+				refresh_token TEXT,
+				created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+				expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+				last_polled_at TIMESTAMP WITH TIME ZONE
+			);
+		`,
+	},
+	{
+		Version:     8,
+		Description: "create roles, permissions and project members",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS permissions (
+				name VARCHAR(100) PRIMARY KEY,
+				description TEXT NOT NULL DEFAULT ''
+			);
+
+			CREATE TABLE IF NOT EXISTS roles (
+				name VARCHAR(50) PRIMARY KEY,
+				description TEXT NOT NULL DEFAULT '',
+				built_in BOOLEAN NOT NULL DEFAULT FALSE,
+				created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+				updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+			);
+
+			CREATE TABLE IF NOT EXISTS role_permissions (
+				role VARCHAR(50) NOT NULL REFERENCES roles (name) ON DELETE CASCADE,
+				permission VARCHAR(100) NOT NULL REFERENCES permissions (name) ON DELETE CASCADE,
+				PRIMARY KEY (role, permission)
+			);
+
+			CREATE TABLE IF NOT EXISTS user_project_access (
+				project_id VARCHAR(255) NOT NULL,
+				user_id VARCHAR(255) NOT NULL,
+				role VARCHAR(50) NOT NULL REFERENCES roles (name),
+				created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+				updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+				PRIMARY KEY (project_id, user_id)
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_user_project_access_role ON user_project_access (role);
+			CREATE INDEX IF NOT EXISTS idx_user_project_access_user_id ON user_project_access (user_id);
+			CREATE UNIQUE INDEX IF NOT EXISTS idx_user_project_access_owner ON user_project_access (project_id) WHERE role = 'owner';
+		`,
+	},
+	{
+		Version:     9,
+		Description: "create audit events",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS audit_events (
+				event_id VARCHAR(255) PRIMARY KEY,
+				actor_id VARCHAR(255) NOT NULL DEFAULT '',
+				action VARCHAR(100) NOT NULL,
+				target_type VARCHAR(50) NOT NULL,
+				target_id VARCHAR(255) NOT NULL,
+				project_id VARCHAR(255) NOT NULL DEFAULT '',
+				details JSONB NOT NULL DEFAULT '{}',
+				occurred_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_audit_events_occurred_at ON audit_events (occurred_at);
+		`,
+	},
+	{
+		Version:     10,
+		Description: "create codebase configurations",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS codebase_configs (
+				config_id VARCHAR(255) PRIMARY KEY,
+				name VARCHAR(255) NOT NULL,
+				description TEXT,
+				provider VARCHAR(50) NOT NULL,
+				url VARCHAR(2048) NOT NULL,
+				created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+				updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+				version BIGINT NOT NULL DEFAULT 1,
+				tags JSONB DEFAULT '{}',
+				config JSONB NOT NULL
+			);
+
+			ALTER TABLE codebase_configs ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+			CREATE INDEX IF NOT EXISTS idx_codebase_configs_name ON codebase_configs (name);
+			CREATE INDEX IF NOT EXISTS idx_codebase_configs_provider ON codebase_configs (provider);
+			CREATE INDEX IF NOT EXISTS idx_codebase_configs_created_at ON codebase_configs (created_at);
+			CREATE INDEX IF NOT EXISTS idx_codebase_configs_tags ON codebase_configs USING GIN (tags);
+			CREATE INDEX IF NOT EXISTS idx_codebase_configs_url ON codebase_configs (url);
+		`,
+	},
+	{
+		Version:     11,
+		Description: "create project templates",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS project_templates (
+				template_id VARCHAR(255) PRIMARY KEY,
+				name VARCHAR(100) NOT NULL,
+				definition JSONB NOT NULL,
+				created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+				updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+			);
+		`,
+	},
+	{
+		Version:     12,
+		Description: "create tag keys",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS tag_keys (
+				key VARCHAR(50) PRIMARY KEY,
+				description TEXT NOT NULL DEFAULT '',
+				allowed_values JSONB NOT NULL DEFAULT '[]',
+				value_pattern VARCHAR(255) NOT NULL DEFAULT '',
+				editor_roles JSONB NOT NULL DEFAULT '[]',
+				created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+				updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+			);
+		`,
+	},
+	{
+		Version:     13,
+		Description: "create project automations",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS project_automations (
+				project_id VARCHAR(255) PRIMARY KEY,
+				scheduled_analyses JSONB NOT NULL DEFAULT '[]',
+				webhooks JSONB NOT NULL DEFAULT '[]',
+				updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+			);
+		`,
+	},
+	{
+		Version:     14,
+		Description: "create task metrics",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS task_metrics_daily (
+				project_id VARCHAR(255) NOT NULL,
+				day DATE NOT NULL,
+				total INTEGER NOT NULL DEFAULT 0,
+				completed INTEGER NOT NULL DEFAULT 0,
+				failed INTEGER NOT NULL DEFAULT 0,
+				cancelled INTEGER NOT NULL DEFAULT 0,
+				duration_ms_total BIGINT NOT NULL DEFAULT 0,
+				duration_count INTEGER NOT NULL DEFAULT 0,
+				token_usage BIGINT NOT NULL DEFAULT 0,
+				failure_categories JSONB NOT NULL DEFAULT '{}',
+				refreshed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+				PRIMARY KEY (project_id, day)
+			);
+
+			ALTER TABLE task_metrics_daily ADD COLUMN IF NOT EXISTS feedback INTEGER NOT NULL DEFAULT 0;
+			ALTER TABLE task_metrics_daily ADD COLUMN IF NOT EXISTS accepted INTEGER NOT NULL DEFAULT 0;
+			ALTER TABLE task_metrics_daily ADD COLUMN IF NOT EXISTS rejected INTEGER NOT NULL DEFAULT 0;
+			ALTER TABLE task_metrics_daily ADD COLUMN IF NOT EXISTS rating_total BIGINT NOT NULL DEFAULT 0;
+			CREATE INDEX IF NOT EXISTS idx_task_metrics_daily_day ON task_metrics_daily (day);
+		`,
+	},
+	{
+		Version:     15,
+		Description: "create campaigns",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS campaigns (
+				campaign_id VARCHAR(255) PRIMARY KEY,
+				name VARCHAR(200) NOT NULL,
+				instruction TEXT NOT NULL,
+				type VARCHAR(50) NOT NULL,
+				agent_id VARCHAR(255) NOT NULL,
+				codebase_ids JSONB NOT NULL,
+				max_parallel INTEGER NOT NULL,
+				created_by VARCHAR(255),
+				created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+			);
+		`,
+	},
+	{
+		Version:     16,
+		Description: "create notification channels",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS notification_channels (
+				channel_id VARCHAR(255) PRIMARY KEY,
+				user_id VARCHAR(255) NOT NULL,
+				project_id VARCHAR(255),
+				type VARCHAR(50) NOT NULL,
+				name VARCHAR(100) NOT NULL,
+				events JSONB NOT NULL DEFAULT '[]',
+				enabled BOOLEAN NOT NULL DEFAULT TRUE,
+				email VARCHAR(320),
+				slack JSONB,
+				created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+				updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+				CONSTRAINT notification_channels_type_check CHECK (type IN ('email', 'slack'))
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_notification_channels_user_id ON notification_channels (user_id);
+			CREATE INDEX IF NOT EXISTS idx_notification_channels_project_id ON notification_channels (project_id);
+		`,
+	},
+	{
+		Version:     17,
+		Description: "create notifications",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS notifications (
+				notification_id VARCHAR(255) PRIMARY KEY,
+				user_id VARCHAR(255) NOT NULL,
+				event VARCHAR(100) NOT NULL,
+				title VARCHAR(500) NOT NULL,
+				message TEXT NOT NULL,
+				project_id VARCHAR(255),
+				resource_id VARCHAR(255),
+				read_at TIMESTAMP WITH TIME ZONE,
+				created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_notifications_user_created ON notifications (user_id, created_at DESC, notification_id DESC);
+			CREATE INDEX IF NOT EXISTS idx_notifications_user_unread ON notifications (user_id) WHERE read_at IS NULL;
+		`,
+	},
+	{
+		Version:     18,
+		Description: "create notification outbox",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS notification_outbox (
+				event_id VARCHAR(255) PRIMARY KEY,
+				event VARCHAR(100) NOT NULL,
+				project_id VARCHAR(255) NOT NULL DEFAULT '',
+				user_id VARCHAR(255) NOT NULL DEFAULT '',
+				resource_id VARCHAR(255) NOT NULL DEFAULT '',
+				subject VARCHAR(500) NOT NULL,
+				message TEXT NOT NULL,
+				attempts INTEGER NOT NULL DEFAULT 0,
+				last_error TEXT,
+				created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+				next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+				delivered_at TIMESTAMP WITH TIME ZONE
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_notification_outbox_due ON notification_outbox (next_attempt_at) WHERE delivered_at IS NULL;
+			CREATE INDEX IF NOT EXISTS idx_notification_outbox_delivered_at ON notification_outbox (delivered_at) WHERE delivered_at IS NOT NULL;
+		`,
+	},
+	{
+		Version:     19,
+		Description: "create task feedback",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS task_feedback (
+				task_id VARCHAR(255) NOT NULL,
+				user_id VARCHAR(255) NOT NULL,
+				rating SMALLINT NOT NULL,
+				comment TEXT NOT NULL DEFAULT '',
+				outcome VARCHAR(20) NOT NULL,
+				created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+				updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+				PRIMARY KEY (task_id, user_id)
+			);
+		`,
+	},
+	{
+		Version:     20,
+		Description: "create task comments",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS task_comments (
+				comment_id VARCHAR(255) PRIMARY KEY,
+				task_id VARCHAR(255) NOT NULL,
+				parent_id VARCHAR(255) REFERENCES task_comments (comment_id) ON DELETE CASCADE,
+				author_id VARCHAR(255) NOT NULL,
+				body TEXT NOT NULL,
+				anchor JSONB,
+				created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+				updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_task_comments_task_id ON task_comments (task_id, created_at);
+			CREATE INDEX IF NOT EXISTS idx_task_comments_parent_id ON task_comments (parent_id);
+		`,
+	},
+	{
+		Version:     21,
+		Description: "create dependency findings",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS dependency_findings (
+				finding_id VARCHAR(255) PRIMARY KEY,
+				codebase_id VARCHAR(255) NOT NULL,
+				task_id VARCHAR(255) NOT NULL,
+				ecosystem VARCHAR(50) NOT NULL,
+				package VARCHAR(500) NOT NULL,
+				version VARCHAR(255) NOT NULL,
+				manifest VARCHAR(1024) NOT NULL,
+				advisory_id VARCHAR(255) NOT NULL,
+				aliases JSONB,
+				summary TEXT NOT NULL,
+				severity VARCHAR(20) NOT NULL,
+				fixed_version VARCHAR(255),
+				url VARCHAR(1024) NOT NULL,
+				detected_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+				CONSTRAINT dependency_findings_severity_check CHECK (severity IN ('critical', 'high', 'medium', 'low', 'unknown'))
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_dependency_findings_codebase_severity ON dependency_findings (codebase_id, severity);
+		`,
+	},
+	{
+		Version:     22,
+		Description: "create code metrics",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS code_metrics (
+				snapshot_id VARCHAR(255) PRIMARY KEY,
+				codebase_id VARCHAR(255) NOT NULL,
+				task_id VARCHAR(255) NOT NULL,
+				commit_sha VARCHAR(64) NOT NULL,
+				files INTEGER NOT NULL,
+				functions INTEGER NOT NULL,
+				average_complexity DOUBLE PRECISION NOT NULL,
+				max_complexity INTEGER NOT NULL,
+				average_function_length DOUBLE PRECISION NOT NULL,
+				max_function_length INTEGER NOT NULL,
+				average_instability DOUBLE PRECISION NOT NULL,
+				maintainability_score DOUBLE PRECISION NOT NULL,
+				packages JSONB,
+				hotspots JSONB,
+				measured_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+				UNIQUE (codebase_id, commit_sha)
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_code_metrics_codebase_measured_at ON code_metrics (codebase_id, measured_at);
+		`,
+	},
+	{
+		Version:     23,
+		Description: "create findings and their baselines",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS findings (
+				finding_id VARCHAR(255) PRIMARY KEY,
+				codebase_id VARCHAR(255) NOT NULL,
+				source VARCHAR(64) NOT NULL,
+				rule_id VARCHAR(255) NOT NULL,
+				fingerprint VARCHAR(64) NOT NULL,
+				message TEXT NOT NULL,
+				file_path VARCHAR(1024) NOT NULL DEFAULT '',
+				line INTEGER NOT NULL DEFAULT 0,
+				end_line INTEGER NOT NULL DEFAULT 0,
+				owners TEXT[] NOT NULL DEFAULT '{}',
+				severity VARCHAR(20) NOT NULL DEFAULT 'medium',
+				status VARCHAR(20) NOT NULL,
+				baselined BOOLEAN NOT NULL DEFAULT FALSE,
+				justification TEXT,
+				status_changed_by VARCHAR(255),
+				first_task_id VARCHAR(255),
+				last_task_id VARCHAR(255),
+				first_seen_at TIMESTAMP WITH TIME ZONE NOT NULL,
+				last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL,
+				fixed_at TIMESTAMP WITH TIME ZONE,
+				updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+				CONSTRAINT findings_status_check CHECK (status IN ('open', 'fixed', 'ignored')),
+				UNIQUE (codebase_id, rule_id, fingerprint)
+			);
+
+			ALTER TABLE findings ADD COLUMN IF NOT EXISTS severity VARCHAR(20) NOT NULL DEFAULT 'medium';
+			ALTER TABLE findings ADD COLUMN IF NOT EXISTS baselined BOOLEAN NOT NULL DEFAULT FALSE;
+			ALTER TABLE findings ADD COLUMN IF NOT EXISTS owners TEXT[] NOT NULL DEFAULT '{}';
+			CREATE INDEX IF NOT EXISTS idx_findings_codebase_status ON findings (codebase_id, status, last_seen_at DESC);
+
+			CREATE TABLE IF NOT EXISTS finding_baselines (
+				codebase_id VARCHAR(255) PRIMARY KEY,
+				finding_count INTEGER NOT NULL,
+				created_by VARCHAR(255),
+				created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+			);
+		`,
+	},
+	{
+		Version:     24,
+		Description: "create quality gate policies",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS quality_gate_policies (
+				project_id VARCHAR(255) PRIMARY KEY,
+				severity_rules JSONB NOT NULL DEFAULT '[]',
+				gates JSONB NOT NULL DEFAULT '[]',
+				updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+			);
+		`,
+	},
+	{
+		Version:     25,
+		Description: "create code owners",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS code_owners (
+				codebase_id VARCHAR(255) PRIMARY KEY,
+				file_path VARCHAR(255) NOT NULL DEFAULT '',
+				commit_sha VARCHAR(40) NOT NULL DEFAULT '',
+				rules JSONB NOT NULL DEFAULT '[]',
+				updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+			);
+		`,
+	},
+	{
+		Version:     26,
+		Description: "create file histories",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS file_histories (
+				codebase_id VARCHAR(255) NOT NULL,
+				path VARCHAR(1024) NOT NULL,
+				commit_sha VARCHAR(64) NOT NULL DEFAULT '',
+				commits INTEGER NOT NULL,
+				lines_added INTEGER NOT NULL,
+				lines_deleted INTEGER NOT NULL,
+				churn INTEGER GENERATED ALWAYS AS (lines_added + lines_deleted) STORED,
+				authors TEXT[] NOT NULL DEFAULT '{}',
+				first_commit_at TIMESTAMP WITH TIME ZONE NOT NULL,
+				last_commit_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+				PRIMARY KEY (codebase_id, path)
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_file_histories_codebase_churn ON file_histories (codebase_id, churn DESC);
+		`,
+	},
+	{
+		Version:     27,
+		Description: "create scan findings",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS scan_findings (
+				finding_id VARCHAR(255) PRIMARY KEY,
+				codebase_id VARCHAR(255) NOT NULL,
+				kind VARCHAR(20) NOT NULL,
+				rule VARCHAR(255) NOT NULL,
+				severity VARCHAR(20) NOT NULL,
+				file_path VARCHAR(1024) NOT NULL,
+				line INTEGER,
+				message TEXT NOT NULL,
+				commit_sha VARCHAR(64) NOT NULL,
+				detected_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+				CONSTRAINT scan_findings_kind_check CHECK (kind IN ('secret', 'license')),
+				CONSTRAINT scan_findings_severity_check CHECK (severity IN ('high', 'medium'))
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_scan_findings_codebase_id ON scan_findings (codebase_id);
+		`,
+	},
+	{
+		Version:     28,
+		Description: "create redaction policies",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS redaction_policies (
+				project_id VARCHAR(255) PRIMARY KEY,
+				mask_emails BOOLEAN NOT NULL,
+				mask_credentials BOOLEAN NOT NULL,
+				patterns JSONB NOT NULL DEFAULT '[]',
+				log_llm_content BOOLEAN NOT NULL DEFAULT TRUE,
+				files JSONB,
+				updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+			);
+
+			ALTER TABLE redaction_policies ADD COLUMN IF NOT EXISTS log_llm_content BOOLEAN NOT NULL DEFAULT TRUE;
+			ALTER TABLE redaction_policies ADD COLUMN IF NOT EXISTS files JSONB;
+		`,
+	},
+	{
+		Version:     29,
+		Description: "create redaction audits",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS redaction_audits (
+				audit_id VARCHAR(255) PRIMARY KEY,
+				project_id VARCHAR(255) NOT NULL,
+				agent_id VARCHAR(255) NOT NULL,
+				operation VARCHAR(20) NOT NULL,
+				files_scanned INTEGER NOT NULL,
+				files_redacted INTEGER NOT NULL,
+				redactions INTEGER NOT NULL,
+				counts JSONB NOT NULL DEFAULT '{}',
+				synced_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_redaction_audits_project_synced_at ON redaction_audits (project_id, synced_at DESC);
+		`,
+	},
+	{
+		Version:     30,
+		Description: "create agent resync settings",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS agent_resync_settings (
+				project_id VARCHAR(255) PRIMARY KEY,
+				enabled BOOLEAN NOT NULL,
+				updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+			);
+		`,
+	},
+	{
+		Version:     31,
+		Description: "create workspaces",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS workspaces (
+				workspace_id VARCHAR(255) PRIMARY KEY,
+				task_id VARCHAR(255) NOT NULL DEFAULT '',
+				codebase_id VARCHAR(255) NOT NULL,
+				commit_sha VARCHAR(64) NOT NULL,
+				host VARCHAR(255) NOT NULL,
+				path VARCHAR(1024) NOT NULL,
+				size_bytes BIGINT NOT NULL DEFAULT 0,
+				status VARCHAR(20) NOT NULL,
+				created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+				last_used_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_workspaces_host_last_used_at ON workspaces (host, last_used_at);
+			CREATE INDEX IF NOT EXISTS idx_workspaces_commit ON workspaces (host, codebase_id, commit_sha) WHERE status = 'idle';
+		`,
+	},
+	{
+		Version:     32,
+		Description: "create codebase heads",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS codebase_heads (
+				codebase_id VARCHAR(255) PRIMARY KEY,
+				commit_sha VARCHAR(64) NOT NULL,
+				changed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+				synced_sha VARCHAR(64) NOT NULL,
+				checked_at TIMESTAMP WITH TIME ZONE NOT NULL
+			);
+		`,
+	},
+	{
+		Version:     33,
+		Description: "create workflow runs",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS workflow_runs (
+				run_id VARCHAR(255) PRIMARY KEY,
+				workflow VARCHAR(100) NOT NULL,
+				status VARCHAR(50) NOT NULL,
+				steps JSONB NOT NULL,
+				run_values JSONB NOT NULL,
+				error TEXT NOT NULL DEFAULT '',
+				created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+				updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+			);
+			CREATE INDEX IF NOT EXISTS idx_workflow_runs_workflow_status ON workflow_runs(workflow, status);
+		`,
+	},
+	{
+		Version:     34,
+		Description: "create LLM interactions",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS llm_interactions (
+				interaction_id VARCHAR(255) PRIMARY KEY,
+				task_id VARCHAR(255) NOT NULL,
+				project_id VARCHAR(255) NOT NULL,
+				executor VARCHAR(255) NOT NULL,
+				step INTEGER NOT NULL,
+				prompt JSONB,
+				response JSONB,
+				context_doc_ids JSONB NOT NULL DEFAULT '[]',
+				latency_ms BIGINT NOT NULL,
+				prompt_tokens INTEGER NOT NULL DEFAULT 0,
+				response_tokens INTEGER NOT NULL DEFAULT 0,
+				content_logged BOOLEAN NOT NULL,
+				error TEXT NOT NULL DEFAULT '',
+				created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_llm_interactions_task_step ON llm_interactions (task_id, created_at, step);
+			CREATE INDEX IF NOT EXISTS idx_llm_interactions_created_at ON llm_interactions (created_at);
+		`,
+	},
+	{
+		Version:     35,
+		Description: "create eval runs",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS eval_runs (
+				run_id VARCHAR(255) PRIMARY KEY,
+				model VARCHAR(255) NOT NULL,
+				prompt_version VARCHAR(50) NOT NULL,
+				status VARCHAR(20) NOT NULL,
+				cases JSONB NOT NULL DEFAULT '[]',
+				results JSONB NOT NULL DEFAULT '[]',
+				summary JSONB,
+				started_by VARCHAR(255),
+				started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+				completed_at TIMESTAMP WITH TIME ZONE
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_eval_runs_started_at ON eval_runs (started_at DESC);
+		`,
+	},
+	{
+		Version:     36,
+		Description: "create experiments",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS experiments (
+				experiment_id VARCHAR(255) PRIMARY KEY,
+				name VARCHAR(200) NOT NULL,
+				task_types JSONB NOT NULL DEFAULT '[]',
+				traffic_percent INTEGER NOT NULL,
+				variant_model VARCHAR(200) NOT NULL DEFAULT '',
+				variant_prompt_version VARCHAR(20) NOT NULL DEFAULT '',
+				status VARCHAR(20) NOT NULL,
+				created_by VARCHAR(255),
+				created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+				stopped_at TIMESTAMP WITH TIME ZONE
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_experiments_status ON experiments (status);
+		`,
+	},
+	{
+		Version:     37,
+		Description: "create GitHub App configurations",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS github_app_configs (
+				organization VARCHAR(100) PRIMARY KEY,
+				app_id BIGINT NOT NULL,
+				private_key TEXT NOT NULL,
+				webhook_secret VARCHAR(255) NOT NULL,
+				project_id VARCHAR(255) NOT NULL,
+				agent_id VARCHAR(255) NOT NULL,
+				task_type VARCHAR(50) NOT NULL,
+				fail_on_severity VARCHAR(20) NOT NULL,
+				created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+				updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+			);
+		`,
+	},
+	{
+		Version:     38,
+		Description: "create GitHub checks",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS github_checks (
+				task_id VARCHAR(255) PRIMARY KEY,
+				organization VARCHAR(100) NOT NULL,
+				repository VARCHAR(255) NOT NULL,
+				installation_id BIGINT NOT NULL,
+				pull_request INTEGER NOT NULL,
+				head_sha VARCHAR(64) NOT NULL,
+				check_run_id BIGINT NOT NULL,
+				status VARCHAR(20) NOT NULL DEFAULT 'pending',
+				created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+				published_at TIMESTAMP WITH TIME ZONE
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_github_checks_pending ON github_checks (created_at) WHERE status = 'pending';
+		`,
+	},
+	{
+		Version:     39,
+		Description: "create Jira sites",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS jira_sites (
+				organization VARCHAR(100) PRIMARY KEY,
+				site_url VARCHAR(255) NOT NULL,
+				email VARCHAR(255) NOT NULL,
+				api_token VARCHAR(500) NOT NULL,
+				project_keys JSONB NOT NULL DEFAULT '[]',
+				done_transition VARCHAR(100) NOT NULL,
+				created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+				updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_jira_sites_project_keys ON jira_sites USING GIN (project_keys);
+		`,
+	},
+	{
+		Version:     40,
+		Description: "create Jira issue links",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS jira_issue_links (
+				task_id VARCHAR(255) PRIMARY KEY,
+				organization VARCHAR(100) NOT NULL,
+				issue_key VARCHAR(50) NOT NULL,
+				issue_url VARCHAR(500) NOT NULL,
+				synced_status VARCHAR(50) NOT NULL,
+				created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+				updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_jira_issue_links_issue_key ON jira_issue_links (issue_key);
+		`,
+	},
+	{
+		Version:     41,
+		Description: "create client tokens",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS client_tokens (
+				scope VARCHAR(300) NOT NULL,
+				client_token VARCHAR(64) NOT NULL,
+				request_hash VARCHAR(64) NOT NULL,
+				resource_id VARCHAR(255),
+				created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+				PRIMARY KEY (scope, client_token)
+			);
+		`,
+	},
+	{
+		Version:     42,
+		Description: "create service accounts and their tokens",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS service_accounts (
+				service_account_id VARCHAR(64) PRIMARY KEY,
+				name VARCHAR(50) UNIQUE NOT NULL,
+				description TEXT,
+				user_id VARCHAR(255) NOT NULL,
+				created_by VARCHAR(255) NOT NULL,
+				created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+			);
+
+			CREATE TABLE IF NOT EXISTS service_account_tokens (
+				token_id VARCHAR(64) PRIMARY KEY,
+				service_account_id VARCHAR(64) NOT NULL REFERENCES service_accounts (service_account_id) ON DELETE CASCADE,
+				name VARCHAR(100) NOT NULL,
+				token_hash VARCHAR(64) UNIQUE NOT NULL,
+				created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+				expires_at TIMESTAMP WITH TIME ZONE,
+				last_used_at TIMESTAMP WITH TIME ZONE,
+				revoked_at TIMESTAMP WITH TIME ZONE
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_service_account_tokens_service_account_id ON service_account_tokens (service_account_id);
+		`,
+	},
+	{
+		Version:     43,
+		Description: "create storage purges",
+		SQL: `
+			CREATE TABLE IF NOT EXISTS storage_purges (
+				purge_id VARCHAR(64) PRIMARY KEY,
+				entity_type VARCHAR(32) NOT NULL,
+				entity_id VARCHAR(255) NOT NULL,
+				region VARCHAR(32) NOT NULL,
+				bucket VARCHAR(255) NOT NULL,
+				prefix TEXT NOT NULL,
+				status VARCHAR(20) NOT NULL DEFAULT 'pending',
+				objects_deleted BIGINT NOT NULL DEFAULT 0,
+				attempts INTEGER NOT NULL DEFAULT 0,
+				last_error TEXT,
+				created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+				updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+				completed_at TIMESTAMP WITH TIME ZONE,
+				lease_until TIMESTAMP WITH TIME ZONE
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_storage_purges_active ON storage_purges (created_at) WHERE status IN ('pending', 'running');
+		`,
+	},
+	{
+		Version:     44,
+		Description: "grant the admin role the permissions replacing the admin routes' role check",
+		SQL: `
+			INSERT INTO permissions (name) VALUES
+				('audit:read'), ('compliance:export'), ('tag_key:manage'),
+				('integration:manage'), ('service_account:manage'), ('operations:manage')
+			ON CONFLICT (name) DO NOTHING;
+
+			INSERT INTO role_permissions (role, permission)
+			SELECT roles.name, permissions.name FROM roles, permissions
+			WHERE roles.name = 'admin' AND permissions.name IN (
+				'audit:read', 'compliance:export', 'tag_key:manage',
+				'integration:manage', 'service_account:manage', 'operations:manage'
+			)
+			ON CONFLICT (role, permission) DO NOTHING;
+		`,
+	},
+	{
+		Version:     45,
+		Description: "seal the refresh tokens of device authorizations and index their expiry",
+		SQL: `
+			-- Sign ins last minutes, so the ones holding plaintext refresh tokens are dropped rather than sealed
+			DELETE FROM device_authorizations;
+
+			ALTER TABLE device_authorizations RENAME COLUMN refresh_token TO sealed_refresh_token;
+			ALTER TABLE device_authorizations ADD COLUMN token_public_key CHAR(64) NOT NULL;
+
+			CREATE INDEX IF NOT EXISTS idx_device_authorizations_expires_at ON device_authorizations (expires_at);
+		`,
+	},
+	{
+		Version:     46,
+		Description: "identify findings by their codebase, source, rule and fingerprint",
+		SQL: `
+			-- Saving findings upserts on this index, so concurrent analyses update a finding rather than duplicate it
+			CREATE UNIQUE INDEX IF NOT EXISTS idx_findings_identity ON findings (codebase_id, source, rule_id, fingerprint);
+
+			ALTER TABLE findings DROP CONSTRAINT IF EXISTS findings_codebase_id_rule_id_fingerprint_key;
+		`,
+	},
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrations_VersionsAreSequential(t *testing.T) {
+	for name, migrations := range map[string][]Migration{"postgres": postgresMigrations, "sqlite": sqliteMigrations} {
+		for i, migration := range migrations {
+			assert.Equal(t, i+1, migration.Version, "%s migration %q", name, migration.Description)
+		}
+	}
+}
+
+func TestMigratePostgres_HoldsLockWhileMigrating(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	mock.ExpectExec(`SELECT pg_advisory_lock\(\$1\)`).WithArgs(postgresMigrationLock).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT COALESCE\(MAX\(version\), 0\) FROM schema_migrations`).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(len(postgresMigrations) - 1))
+	mock.ExpectBegin()
+	mock.ExpectExec("CREATE UNIQUE INDEX IF NOT EXISTS idx_findings_identity").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO schema_migrations").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec(`SELECT pg_advisory_unlock\(\$1\)`).WithArgs(postgresMigrationLock).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	require.NoError(t, migratePostgres(context.Background(), db))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
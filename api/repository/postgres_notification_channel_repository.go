@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// notificationChannelColumns lists the notification channel columns in the order expected by scanNotificationChannel
+const notificationChannelColumns = `channel_id, user_id, project_id, type, name, events, enabled, email, slack, created_at, updated_at`
+
+// PostgresNotificationChannelRepository implements NotificationChannelRepository using PostgreSQL
+type PostgresNotificationChannelRepository struct {
+	db        *sql.DB
+	tableName string
+}
+
+// NewPostgresNotificationChannelRepository creates a new PostgreSQL notification channel repository
+func NewPostgresNotificationChannelRepository(config PostgresConfig, tableName string) (NotificationChannelRepository, error) {
+	if tableName == "" {
+		tableName = conf.DefaultNotificationChannelsTableName
+	}
+
+	// Build connection string
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	repo := &PostgresNotificationChannelRepository{
+		db:        db,
+		tableName: tableName,
+	}
+
+	return repo, nil
+}
+
+// NewPostgresNotificationChannelRepositoryWithDB creates a new PostgreSQL notification channel repository with an existing DB connection
+func NewPostgresNotificationChannelRepositoryWithDB(db *sql.DB, tableName string) NotificationChannelRepository {
+	if tableName == "" {
+		tableName = conf.DefaultNotificationChannelsTableName
+	}
+
+	return &PostgresNotificationChannelRepository{
+		db:        db,
+		tableName: tableName,
+	}
+}
+
+// CreateChannel stores a new notification channel
+func (r *PostgresNotificationChannelRepository) CreateChannel(ctx context.Context, channel *models.NotificationChannel) error {
+	eventsJSON, slackJSON, err := marshalNotificationChannel(channel)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (%s)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`, r.tableName, notificationChannelColumns)
+
+	_, err = r.db.ExecContext(ctx, query,
+		channel.ChannelID, channel.UserID, channel.ProjectID, channel.Type, channel.Name,
+		eventsJSON, channel.Enabled, channel.Email, slackJSON, channel.CreatedAt, channel.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create notification channel: %w", err)
+	}
+
+	return nil
+}
+
+// GetChannel retrieves a notification channel by ID
+func (r *PostgresNotificationChannelRepository) GetChannel(ctx context.Context, channelID string) (*models.NotificationChannel, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE channel_id = $1`, notificationChannelColumns, r.tableName)
+
+	channel, err := scanNotificationChannel(r.db.QueryRowContext(ctx, query, channelID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.NotFound(apperrors.CodeChannelNotFound, "notification channel not found: %s", channelID)
+		}
+		return nil, fmt.Errorf("failed to get notification channel: %w", err)
+	}
+
+	return channel, nil
+}
+
+// UpdateChannel replaces the mutable fields of an existing notification channel
+func (r *PostgresNotificationChannelRepository) UpdateChannel(ctx context.Context, channel *models.NotificationChannel) error {
+	eventsJSON, slackJSON, err := marshalNotificationChannel(channel)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s
+		SET name = $2, events = $3, enabled = $4, slack = $5, updated_at = $6
+		WHERE channel_id = $1
+	`, r.tableName)
+
+	result, err := r.db.ExecContext(ctx, query,
+		channel.ChannelID, channel.Name, eventsJSON, channel.Enabled, slackJSON, channel.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update notification channel: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return apperrors.NotFound(apperrors.CodeChannelNotFound, "notification channel not found: %s", channel.ChannelID)
+	}
+
+	return nil
+}
+
+// DeleteChannel deletes a notification channel by ID
+func (r *PostgresNotificationChannelRepository) DeleteChannel(ctx context.Context, channelID string) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE channel_id = $1`, r.tableName)
+
+	result, err := r.db.ExecContext(ctx, query, channelID)
+	if err != nil {
+		return fmt.Errorf("failed to delete notification channel: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return apperrors.NotFound(apperrors.CodeChannelNotFound, "notification channel not found: %s", channelID)
+	}
+
+	return nil
+}
+
+// ListChannelsByUser lists the channels a user created, oldest first
+func (r *PostgresNotificationChannelRepository) ListChannelsByUser(ctx context.Context, userID, projectID string) ([]models.NotificationChannel, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE user_id = $1`, notificationChannelColumns, r.tableName)
+	args := []any{userID}
+
+	if projectID != "" {
+		query += " AND project_id = $2"
+		args = append(args, projectID)
+	}
+	query += " ORDER BY created_at ASC"
+
+	return r.queryChannels(ctx, "ListChannelsByUser", query, args...)
+}
+
+// ListSubscribedChannels lists the enabled channels whose events contain the event and that cover the project
+func (r *PostgresNotificationChannelRepository) ListSubscribedChannels(ctx context.Context, event models.NotificationEvent, projectID string) ([]models.NotificationChannel, error) {
+	eventJSON, err := json.Marshal([]models.NotificationEvent{event})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		WHERE enabled = TRUE AND events @> $1 AND (project_id IS NULL OR project_id = $2)
+	`, notificationChannelColumns, r.tableName)
+
+	return r.queryChannels(ctx, "ListSubscribedChannels", query, eventJSON, projectID)
+}
+
+// queryChannels runs a query selecting notificationChannelColumns and scans every row
+func (r *PostgresNotificationChannelRepository) queryChannels(ctx context.Context, operation, query string, args ...any) ([]models.NotificationChannel, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notification channels: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close rows in "+operation, "error", closeErr)
+		}
+	}()
+
+	var channels []models.NotificationChannel
+	for rows.Next() {
+		channel, err := scanNotificationChannel(rows)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, *channel)
+	}
+
+	return channels, rows.Err()
+}
+
+// marshalNotificationChannel encodes the JSONB columns of a channel
+func marshalNotificationChannel(channel *models.NotificationChannel) ([]byte, []byte, error) {
+	eventsJSON, err := json.Marshal(channel.Events)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal events: %w", err)
+	}
+
+	var slackJSON []byte
+	if channel.Slack != nil {
+		if slackJSON, err = json.Marshal(channel.Slack); err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal slack configuration: %w", err)
+		}
+	}
+
+	return eventsJSON, slackJSON, nil
+}
+
+// scanNotificationChannel scans a row selected with notificationChannelColumns
+func scanNotificationChannel(row rowScanner) (*models.NotificationChannel, error) {
+	var channel models.NotificationChannel
+	var eventsJSON, slackJSON []byte
+
+	if err := row.Scan(
+		&channel.ChannelID, &channel.UserID, &channel.ProjectID, &channel.Type, &channel.Name,
+		&eventsJSON, &channel.Enabled, &channel.Email, &slackJSON, &channel.CreatedAt, &channel.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(eventsJSON, &channel.Events); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal events: %w", err)
+	}
+	if len(slackJSON) > 0 {
+		channel.Slack = &models.SlackChannelConfig{}
+		if err := json.Unmarshal(slackJSON, channel.Slack); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal slack configuration: %w", err)
+		}
+	}
+
+	return &channel, nil
+}
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// outboxEventColumns lists the outbox event columns in the order expected by scanOutboxEvent
+const outboxEventColumns = `event_id, event, project_id, user_id, resource_id, subject, message, attempts, last_error, created_at, next_attempt_at, delivered_at`
+
+// PostgresNotificationOutboxRepository implements NotificationOutboxRepository using PostgreSQL
+type PostgresNotificationOutboxRepository struct {
+	db        *sql.DB
+	tableName string
+}
+
+// NewPostgresNotificationOutboxRepository creates a new PostgreSQL notification outbox repository
+func NewPostgresNotificationOutboxRepository(config PostgresConfig, tableName string) (NotificationOutboxRepository, error) {
+	if tableName == "" {
+		tableName = conf.DefaultNotificationOutboxTableName
+	}
+
+	// Build connection string
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	repo := &PostgresNotificationOutboxRepository{
+		db:        db,
+		tableName: tableName,
+	}
+
+	return repo, nil
+}
+
+// NewPostgresNotificationOutboxRepositoryWithDB creates a new PostgreSQL notification outbox repository with an existing DB connection
+func NewPostgresNotificationOutboxRepositoryWithDB(db *sql.DB, tableName string) NotificationOutboxRepository {
+	if tableName == "" {
+		tableName = conf.DefaultNotificationOutboxTableName
+	}
+
+	return &PostgresNotificationOutboxRepository{
+		db:        db,
+		tableName: tableName,
+	}
+}
+
+// insertOutboxEvent records a notification in the outbox using the given executor, so it can share the transaction
+// of the change that raised it
+func insertOutboxEvent(ctx context.Context, exec execer, tableName string, notification models.Notification) error {
+	now := time.Now()
+	query := fmt.Sprintf(`
+		INSERT INTO %s (event_id, event, project_id, user_id, resource_id, subject, message, created_at, next_attempt_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)
+	`, tableName)
+
+	_, err := exec.ExecContext(ctx, query,
+		"evt-"+uuid.New().String(), notification.Event, notification.ProjectID, notification.UserID, notification.ResourceID,
+		notification.Subject, notification.Message, now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record notification in outbox: %w", err)
+	}
+
+	return nil
+}
+
+// Enqueue records a notification for delivery
+func (r *PostgresNotificationOutboxRepository) Enqueue(ctx context.Context, notification models.Notification) error {
+	return insertOutboxEvent(ctx, r.db, r.tableName, notification)
+}
+
+// ClaimDue claims the due events in one statement. Rows locked by another dispatcher's claim are skipped rather than
+// waited for, and pushing their next attempt past the lease keeps the next claims from picking them up.
+func (r *PostgresNotificationOutboxRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]models.OutboxEvent, error) {
+	now := time.Now()
+	query := fmt.Sprintf(`
+		UPDATE %s SET attempts = attempts + 1, next_attempt_at = $2
+		WHERE event_id IN (
+			SELECT event_id FROM %s
+			WHERE delivered_at IS NULL AND next_attempt_at <= $1
+			ORDER BY created_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING %s
+	`, r.tableName, r.tableName, outboxEventColumns)
+
+	rows, err := r.db.QueryContext(ctx, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim outbox events: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close outbox event rows", "error", closeErr)
+		}
+	}()
+
+	events := []models.OutboxEvent{}
+	for rows.Next() {
+		event, err := scanOutboxEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan outbox event: %w", err)
+		}
+		events = append(events, *event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate outbox events: %w", err)
+	}
+
+	return events, nil
+}
+
+// MarkDelivered records that an event was delivered
+func (r *PostgresNotificationOutboxRepository) MarkDelivered(ctx context.Context, eventID string) error {
+	query := fmt.Sprintf(`UPDATE %s SET delivered_at = $2, last_error = NULL WHERE event_id = $1`, r.tableName)
+
+	if _, err := r.db.ExecContext(ctx, query, eventID, time.Now()); err != nil {
+		return fmt.Errorf("failed to mark outbox event delivered: %w", err)
+	}
+
+	return nil
+}
+
+// MarkFailed records a failed delivery attempt and when the event is retried
+func (r *PostgresNotificationOutboxRepository) MarkFailed(ctx context.Context, eventID string, deliveryErr string, retryAt time.Time) error {
+	query := fmt.Sprintf(`UPDATE %s SET last_error = $2, next_attempt_at = $3 WHERE event_id = $1`, r.tableName)
+
+	if _, err := r.db.ExecContext(ctx, query, eventID, deliveryErr, retryAt); err != nil {
+		return fmt.Errorf("failed to mark outbox event failed: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteDelivered deletes the events delivered before the given time
+func (r *PostgresNotificationOutboxRepository) DeleteDelivered(ctx context.Context, before time.Time) (int64, error) {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE delivered_at < $1`, r.tableName)
+
+	result, err := r.db.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete delivered outbox events: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// scanOutboxEvent scans a single row selected with outboxEventColumns
+func scanOutboxEvent(row rowScanner) (*models.OutboxEvent, error) {
+	var event models.OutboxEvent
+	err := row.Scan(
+		&event.EventID, &event.Notification.Event, &event.Notification.ProjectID, &event.Notification.UserID,
+		&event.Notification.ResourceID, &event.Notification.Subject, &event.Notification.Message,
+		&event.Attempts, &event.LastError, &event.CreatedAt, &event.NextAttemptAt, &event.DeliveredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	event.Notification.EventID = event.EventID
+	return &event, nil
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// PostgresProjectAutomationRepository implements ProjectAutomationRepository using PostgreSQL
+type PostgresProjectAutomationRepository struct {
+	db        *sql.DB
+	tableName string
+}
+
+// NewPostgresProjectAutomationRepository creates a new PostgreSQL project automation repository
+func NewPostgresProjectAutomationRepository(config PostgresConfig, tableName string) (ProjectAutomationRepository, error) {
+	if tableName == "" {
+		tableName = conf.DefaultProjectAutomationsTableName
+	}
+
+	// Build connection string
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	repo := &PostgresProjectAutomationRepository{
+		db:        db,
+		tableName: tableName,
+	}
+
+	return repo, nil
+}
+
+// NewPostgresProjectAutomationRepositoryWithDB creates a new PostgreSQL project automation repository with an existing DB connection
+func NewPostgresProjectAutomationRepositoryWithDB(db *sql.DB, tableName string) ProjectAutomationRepository {
+	if tableName == "" {
+		tableName = conf.DefaultProjectAutomationsTableName
+	}
+
+	return &PostgresProjectAutomationRepository{
+		db:        db,
+		tableName: tableName,
+	}
+}
+
+// GetAutomation retrieves the automation settings of a project
+func (r *PostgresProjectAutomationRepository) GetAutomation(ctx context.Context, projectID string) (*models.ProjectAutomation, error) {
+	query := fmt.Sprintf(`SELECT project_id, scheduled_analyses, webhooks, updated_at FROM %s WHERE project_id = $1`, r.tableName)
+
+	var automation models.ProjectAutomation
+	var schedulesJSON, webhooksJSON []byte
+	err := r.db.QueryRowContext(ctx, query, projectID).Scan(
+		&automation.ProjectID, &schedulesJSON, &webhooksJSON, &automation.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get project automation: %w", err)
+	}
+
+	if err := json.Unmarshal(schedulesJSON, &automation.ScheduledAnalyses); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal scheduled analyses: %w", err)
+	}
+	if err := json.Unmarshal(webhooksJSON, &automation.Webhooks); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal webhooks: %w", err)
+	}
+
+	return &automation, nil
+}
+
+// SaveAutomation creates or replaces the automation settings of a project
+func (r *PostgresProjectAutomationRepository) SaveAutomation(ctx context.Context, automation *models.ProjectAutomation) error {
+	schedules := automation.ScheduledAnalyses
+	if schedules == nil {
+		schedules = []models.ScheduledAnalysis{}
+	}
+	schedulesJSON, err := json.Marshal(schedules)
+	if err != nil {
+		return fmt.Errorf("failed to marshal scheduled analyses: %w", err)
+	}
+
+	webhooks := automation.Webhooks
+	if webhooks == nil {
+		webhooks = []models.WebhookRegistration{}
+	}
+	webhooksJSON, err := json.Marshal(webhooks)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhooks: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (project_id, scheduled_analyses, webhooks, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (project_id) DO UPDATE SET
+			scheduled_analyses = EXCLUDED.scheduled_analyses,
+			webhooks = EXCLUDED.webhooks,
+			updated_at = EXCLUDED.updated_at
+	`, r.tableName)
+
+	_, err = r.db.ExecContext(ctx, query,
+		automation.ProjectID, schedulesJSON, webhooksJSON, automation.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save project automation: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteAutomation deletes the automation settings of a project
+func (r *PostgresProjectAutomationRepository) DeleteAutomation(ctx context.Context, projectID string) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE project_id = $1`, r.tableName)
+
+	if _, err := r.db.ExecContext(ctx, query, projectID); err != nil {
+		return fmt.Errorf("failed to delete project automation: %w", err)
+	}
+
+	return nil
+}
@@ -7,6 +7,8 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
 	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
 	"github.com/lib/pq"
 )
@@ -43,12 +45,6 @@ func NewPostgresProjectRepository(config PostgresConfig, tableName string) (Proj
 		tableName: tableName,
 	}
 
-	// Create table if it doesn't exist
-	if err := repo.CreateTable(context.Background()); err != nil {
-		_ = db.Close()
-		return nil, fmt.Errorf("failed to create projects table: %w", err)
-	}
-
 	return repo, nil
 }
 
@@ -89,8 +85,8 @@ func (r *PostgresProjectRepository) CreateProject(ctx context.Context, project *
 	query := fmt.Sprintf(`
 		INSERT INTO %s (
 			project_id, name, description, language, status, 
-			created_at, updated_at, tags, metadata
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			created_at, updated_at, tags, metadata, vector_store, embedding_model, embedding_dimensions, version
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, 1)
 	`, r.tableName)
 
 	_, err = r.db.ExecContext(ctx, query,
@@ -103,15 +99,19 @@ func (r *PostgresProjectRepository) CreateProject(ctx context.Context, project *
 		project.UpdatedAt,
 		tagsJSON,
 		metadataJSON,
+		project.VectorStore,
+		project.EmbeddingModel,
+		project.EmbeddingDimensions,
 	)
 	if err != nil {
 		// Check for unique constraint violation
 		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
-			return fmt.Errorf("project with ID %s already exists", project.ProjectID)
+			return apperrors.Conflict(apperrors.CodeProjectExists, "project with ID %s already exists", project.ProjectID)
 		}
 		return fmt.Errorf("failed to create project in PostgreSQL: %w", err)
 	}
 
+	project.Version = 1
 	return nil
 }
 
@@ -119,7 +119,7 @@ func (r *PostgresProjectRepository) CreateProject(ctx context.Context, project *
 func (r *PostgresProjectRepository) GetProject(ctx context.Context, projectID string) (*ProjectRecord, error) {
 	query := fmt.Sprintf(`
 		SELECT project_id, name, description, language, status,
-			   created_at, updated_at, tags, metadata
+			   created_at, updated_at, version, tags, metadata, vector_store, embedding_model, embedding_dimensions
 		FROM %s WHERE project_id = $1
 	`, r.tableName)
 
@@ -137,8 +137,12 @@ func (r *PostgresProjectRepository) GetProject(ctx context.Context, projectID st
 		&project.Status,
 		&project.CreatedAt,
 		&project.UpdatedAt,
+		&project.Version,
 		&tagsJSON,
 		&metadataJSON,
+		&project.VectorStore,
+		&project.EmbeddingModel,
+		&project.EmbeddingDimensions,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -183,14 +187,18 @@ func (r *PostgresProjectRepository) UpdateProject(ctx context.Context, project *
 		return fmt.Errorf("failed to marshal metadata: %w", err)
 	}
 
+	// Compare-and-swap on the version so concurrent updates cannot overwrite each other
 	query := fmt.Sprintf(`
 		UPDATE %s SET 
 			name = $2, description = $3, language = $4, status = $5,
-			updated_at = $6, tags = $7, metadata = $8
-		WHERE project_id = $1
+			updated_at = $6, tags = $7, metadata = $8, vector_store = $10,
+			embedding_model = $11, embedding_dimensions = $12, version = version + 1
+		WHERE project_id = $1 AND version = $9
+		RETURNING version
 	`, r.tableName)
 
-	result, err := r.db.ExecContext(ctx, query,
+	var version int64
+	err = r.db.QueryRowContext(ctx, query,
 		project.ProjectID,
 		project.Name,
 		project.Description,
@@ -199,20 +207,28 @@ func (r *PostgresProjectRepository) UpdateProject(ctx context.Context, project *
 		project.UpdatedAt,
 		tagsJSON,
 		metadataJSON,
-	)
-	if err != nil {
-		return fmt.Errorf("failed to update project in PostgreSQL: %w", err)
-	}
-
-	rowsAffected, err := result.RowsAffected()
+		project.Version,
+		project.VectorStore,
+		project.EmbeddingModel,
+		project.EmbeddingDimensions,
+	).Scan(&version)
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
-	}
+		if err != sql.ErrNoRows {
+			return fmt.Errorf("failed to update project in PostgreSQL: %w", err)
+		}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("project with ID %s does not exist", project.ProjectID)
+		// No row matched: either the project is gone or its version moved on
+		exists, existsErr := r.ProjectExists(ctx, project.ProjectID)
+		if existsErr != nil {
+			return existsErr
+		}
+		if !exists {
+			return apperrors.NotFound(apperrors.CodeProjectNotFound, "project with ID %s does not exist", project.ProjectID)
+		}
+		return apperrors.PreconditionFailed(apperrors.CodeVersionMismatch, "project with ID %s was modified since version %d", project.ProjectID, project.Version)
 	}
 
+	project.Version = version
 	return nil
 }
 
@@ -231,7 +247,7 @@ func (r *PostgresProjectRepository) DeleteProject(ctx context.Context, projectID
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("project with ID %s does not exist", projectID)
+		return apperrors.NotFound(apperrors.CodeProjectNotFound, "project with ID %s does not exist", projectID)
 	}
 
 	return nil
@@ -258,7 +274,7 @@ func (r *PostgresProjectRepository) ListProjects(ctx context.Context, opts ListP
 	// Build the base query
 	query := fmt.Sprintf(`
 		SELECT project_id, name, description, language, status,
-			   created_at, updated_at, tags, metadata
+			   created_at, updated_at, version, tags, metadata, vector_store, embedding_model, embedding_dimensions
 		FROM %s
 	`, r.tableName)
 
@@ -282,6 +298,13 @@ func (r *PostgresProjectRepository) ListProjects(ctx context.Context, opts ListP
 		}
 	}
 
+	// Archived projects are only listed when asked for
+	if !opts.IncludeArchived {
+		conditions = append(conditions, fmt.Sprintf("status <> $%d", argIndex))
+		args = append(args, string(models.ProjectStatusArchived))
+		argIndex++
+	}
+
 	// Add pagination if provided
 	if opts.NextToken != nil && *opts.NextToken != "" {
 		condition := fmt.Sprintf("project_id > $%d", argIndex)
@@ -331,8 +354,12 @@ func (r *PostgresProjectRepository) ListProjects(ctx context.Context, opts ListP
 			&project.Status,
 			&project.CreatedAt,
 			&project.UpdatedAt,
+			&project.Version,
 			&tagsJSON,
 			&metadataJSON,
+			&project.VectorStore,
+			&project.EmbeddingModel,
+			&project.EmbeddingDimensions,
 		)
 		if err != nil {
 			return nil, "", fmt.Errorf("failed to scan project row: %w", err)
@@ -377,41 +404,3 @@ func (r *PostgresProjectRepository) ListProjects(ctx context.Context, opts ListP
 
 	return projects, nextToken, nil
 }
-
-// CreateTable creates the projects table if it doesn't exist
-func (r *PostgresProjectRepository) CreateTable(ctx context.Context) error {
-	query := fmt.Sprintf(`
-		CREATE TABLE IF NOT EXISTS %s (
-			project_id VARCHAR(255) PRIMARY KEY,
-			name VARCHAR(255) NOT NULL,
-			description TEXT,
-			language VARCHAR(50),
-			status VARCHAR(50) NOT NULL DEFAULT 'active',
-			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
-			updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
-			tags JSONB DEFAULT '{}',
-			metadata JSONB DEFAULT '{}'
-		)
-	`, r.tableName)
-
-	_, err := r.db.ExecContext(ctx, query)
-	if err != nil {
-		return fmt.Errorf("failed to create projects table: %w", err)
-	}
-
-	// Create indexes for better performance
-	indexes := []string{
-		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_name ON %s (name)", r.tableName, r.tableName),
-		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_status ON %s (status)", r.tableName, r.tableName),
-		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_created_at ON %s (created_at)", r.tableName, r.tableName),
-		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_tags ON %s USING GIN (tags)", r.tableName, r.tableName),
-	}
-
-	for _, indexQuery := range indexes {
-		if _, err := r.db.ExecContext(ctx, indexQuery); err != nil {
-			return fmt.Errorf("failed to create index: %w", err)
-		}
-	}
-
-	return nil
-}
@@ -11,7 +11,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
 	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
 )
 
 func TestNewPostgresProjectRepository(t *testing.T) {
@@ -65,11 +67,15 @@ func TestPostgresProjectRepository_CreateProject_Success(t *testing.T) {
 			project.UpdatedAt,
 			sqlmock.AnyArg(), // tags JSON
 			sqlmock.AnyArg(), // metadata JSON
+			project.VectorStore,
+			project.EmbeddingModel,
+			project.EmbeddingDimensions,
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	err = repo.CreateProject(context.Background(), project)
 	assert.NoError(t, err)
+	assert.Equal(t, int64(1), project.Version)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -108,6 +114,9 @@ func TestPostgresProjectRepository_CreateProject_DuplicateError(t *testing.T) {
 			project.UpdatedAt,
 			sqlmock.AnyArg(),
 			sqlmock.AnyArg(),
+			project.VectorStore,
+			project.EmbeddingModel,
+			project.EmbeddingDimensions,
 		).
 		WillReturnError(pqErr)
 
@@ -134,10 +143,12 @@ func TestPostgresProjectRepository_GetProject_Success(t *testing.T) {
 
 	rows := sqlmock.NewRows([]string{
 		"project_id", "name", "description", "language", "status",
-		"created_at", "updated_at", "tags", "metadata",
+		"created_at", "updated_at", "version", "tags", "metadata", "vector_store",
+		"embedding_model", "embedding_dimensions",
 	}).AddRow(
 		projectID, "test-project", description, language, "active",
-		createdAt, updatedAt, []byte(tagsJSON), []byte(metadataJSON),
+		createdAt, updatedAt, 2, []byte(tagsJSON), []byte(metadataJSON), "qdrant",
+		"amazon.titan-embed-text-v2:0", 512,
 	)
 
 	mock.ExpectQuery(`SELECT (.+) FROM projects WHERE project_id`).
@@ -155,6 +166,9 @@ func TestPostgresProjectRepository_GetProject_Success(t *testing.T) {
 	assert.Equal(t, "active", project.Status)
 	assert.Equal(t, "test", project.Tags["env"])
 	assert.Equal(t, "1.0.0", project.Metadata["version"])
+	assert.Equal(t, int64(2), project.Version)
+	assert.Equal(t, "qdrant", project.VectorStore)
+	assert.Equal(t, config.Embedding{Model: "amazon.titan-embed-text-v2:0", Dimensions: 512}, project.Embedding())
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -191,15 +205,19 @@ func TestPostgresProjectRepository_UpdateProject_Success(t *testing.T) {
 		Language:    stringPtr("python"),
 		Status:      string(models.ProjectStatusActive),
 		UpdatedAt:   time.Now().UTC(),
+		Version:     3,
 		Tags: map[string]string{
 			"env": "staging",
 		},
 		Metadata: map[string]string{
 			"version": "1.1.0",
 		},
+		VectorStore:         "opensearch",
+		EmbeddingModel:      "cohere.embed-english-v3",
+		EmbeddingDimensions: 1024,
 	}
 
-	mock.ExpectExec(`UPDATE projects SET`).
+	mock.ExpectQuery(`UPDATE projects SET (.+) WHERE project_id = \$1 AND version = \$9`).
 		WithArgs(
 			project.ProjectID,
 			project.Name,
@@ -209,11 +227,16 @@ func TestPostgresProjectRepository_UpdateProject_Success(t *testing.T) {
 			project.UpdatedAt,
 			sqlmock.AnyArg(), // tags JSON
 			sqlmock.AnyArg(), // metadata JSON
+			int64(3),
+			"opensearch",
+			"cohere.embed-english-v3",
+			int32(1024),
 		).
-		WillReturnResult(sqlmock.NewResult(0, 1))
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(4))
 
 	err = repo.UpdateProject(context.Background(), project)
 	assert.NoError(t, err)
+	assert.Equal(t, int64(4), project.Version)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -229,11 +252,12 @@ func TestPostgresProjectRepository_UpdateProject_NotFound(t *testing.T) {
 		Name:      "updated-project",
 		Status:    string(models.ProjectStatusActive),
 		UpdatedAt: time.Now().UTC(),
+		Version:   1,
 		Tags:      make(map[string]string),
 		Metadata:  make(map[string]string),
 	}
 
-	mock.ExpectExec(`UPDATE projects SET`).
+	mock.ExpectQuery(`UPDATE projects SET`).
 		WithArgs(
 			project.ProjectID,
 			project.Name,
@@ -243,15 +267,51 @@ func TestPostgresProjectRepository_UpdateProject_NotFound(t *testing.T) {
 			project.UpdatedAt,
 			sqlmock.AnyArg(),
 			sqlmock.AnyArg(),
+			int64(1),
+			"",
+			"",
+			int32(0),
 		).
-		WillReturnResult(sqlmock.NewResult(0, 0)) // No rows affected
+		WillReturnError(sql.ErrNoRows) // No row matched
+	mock.ExpectQuery(`SELECT 1 FROM projects WHERE project_id`).
+		WithArgs(project.ProjectID).
+		WillReturnError(sql.ErrNoRows)
 
 	err = repo.UpdateProject(context.Background(), project)
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
 	assert.Contains(t, err.Error(), "does not exist")
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestPostgresProjectRepository_UpdateProject_VersionMismatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresProjectRepositoryWithDB(db, "projects")
+
+	project := &ProjectRecord{
+		ProjectID: "proj-12345",
+		Name:      "updated-project",
+		Status:    string(models.ProjectStatusActive),
+		UpdatedAt: time.Now().UTC(),
+		Version:   2,
+	}
+
+	// A concurrent writer already moved the project past version 2
+	mock.ExpectQuery(`UPDATE projects SET`).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(`SELECT 1 FROM projects WHERE project_id`).
+		WithArgs(project.ProjectID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(1))
+
+	err = repo.UpdateProject(context.Background(), project)
+	assert.ErrorIs(t, err, apperrors.ErrPreconditionFailed)
+	assert.Equal(t, apperrors.CodeVersionMismatch, apperrors.CodeOf(err))
+	assert.Equal(t, int64(2), project.Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestPostgresProjectRepository_DeleteProject_Success(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	require.NoError(t, err)
@@ -345,15 +405,17 @@ func TestPostgresProjectRepository_ListProjects_Success(t *testing.T) {
 
 	rows := sqlmock.NewRows([]string{
 		"project_id", "name", "description", "language", "status",
-		"created_at", "updated_at", "tags", "metadata",
+		"created_at", "updated_at", "version", "tags", "metadata", "vector_store",
+		"embedding_model", "embedding_dimensions",
 	}).
 		AddRow("proj-12345", "project-1", "desc-1", "go", "active",
-			createdAt, updatedAt, []byte(`{"env":"test"}`), []byte(`{"version":"1.0.0"}`)).
+			createdAt, updatedAt, 1, []byte(`{"env":"test"}`), []byte(`{"version":"1.0.0"}`), "", "", 0).
 		AddRow("proj-67890", "project-2", "desc-2", "python", "active",
-			createdAt, updatedAt, []byte(`{"env":"prod"}`), []byte(`{"version":"2.0.0"}`))
+			createdAt, updatedAt, 1, []byte(`{"env":"prod"}`), []byte(`{"version":"2.0.0"}`), "", "", 0)
 
-	mock.ExpectQuery(`SELECT (.+) FROM projects ORDER BY project_id LIMIT`).
-		WithArgs(3). // maxResults + 1
+	// Archived projects are left out by default
+	mock.ExpectQuery(`SELECT (.+) FROM projects WHERE status <> \$1 ORDER BY project_id LIMIT`).
+		WithArgs("archived", 3). // maxResults + 1
 		WillReturnRows(rows)
 
 	projects, nextToken, err := repo.ListProjects(context.Background(), opts)
@@ -380,6 +442,7 @@ func TestPostgresProjectRepository_ListProjects_WithTagFilter(t *testing.T) {
 		TagFilter: map[string]string{
 			"env": "test",
 		},
+		IncludeArchived: true,
 	}
 
 	createdAt := time.Now().UTC()
@@ -387,10 +450,11 @@ func TestPostgresProjectRepository_ListProjects_WithTagFilter(t *testing.T) {
 
 	rows := sqlmock.NewRows([]string{
 		"project_id", "name", "description", "language", "status",
-		"created_at", "updated_at", "tags", "metadata",
+		"created_at", "updated_at", "version", "tags", "metadata", "vector_store",
+		"embedding_model", "embedding_dimensions",
 	}).
 		AddRow("proj-12345", "project-1", "desc-1", "go", "active",
-			createdAt, updatedAt, []byte(`{"env":"test"}`), []byte(`{"version":"1.0.0"}`))
+			createdAt, updatedAt, 1, []byte(`{"env":"test"}`), []byte(`{"version":"1.0.0"}`), "", "", 0)
 
 	mock.ExpectQuery(`SELECT (.+) FROM projects WHERE tags::jsonb @> (.+) ORDER BY project_id`).
 		WithArgs(`{"env":"test"}`).
@@ -407,32 +471,6 @@ func TestPostgresProjectRepository_ListProjects_WithTagFilter(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestPostgresProjectRepository_CreateTable_Success(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	require.NoError(t, err)
-	defer db.Close() //nolint:errcheck // Test cleanup
-
-	repo := NewPostgresProjectRepositoryWithDB(db, "projects")
-
-	// Expect table creation
-	mock.ExpectExec(`CREATE TABLE IF NOT EXISTS projects`).
-		WillReturnResult(sqlmock.NewResult(0, 0))
-
-	// Expect index creation
-	mock.ExpectExec(`CREATE INDEX IF NOT EXISTS idx_projects_name`).
-		WillReturnResult(sqlmock.NewResult(0, 0))
-	mock.ExpectExec(`CREATE INDEX IF NOT EXISTS idx_projects_status`).
-		WillReturnResult(sqlmock.NewResult(0, 0))
-	mock.ExpectExec(`CREATE INDEX IF NOT EXISTS idx_projects_created_at`).
-		WillReturnResult(sqlmock.NewResult(0, 0))
-	mock.ExpectExec(`CREATE INDEX IF NOT EXISTS idx_projects_tags`).
-		WillReturnResult(sqlmock.NewResult(0, 0))
-
-	err = repo.(*PostgresProjectRepository).CreateTable(context.Background())
-	assert.NoError(t, err)
-	assert.NoError(t, mock.ExpectationsWereMet())
-}
-
 // Helper functions for tests
 
 func stringPtr(s string) *string {
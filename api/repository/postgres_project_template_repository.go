@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// PostgresProjectTemplateRepository implements ProjectTemplateRepository using PostgreSQL.
+// The template body is stored as a single JSONB document.
+type PostgresProjectTemplateRepository struct {
+	db        *sql.DB
+	tableName string
+}
+
+// NewPostgresProjectTemplateRepository creates a new PostgreSQL project template repository
+func NewPostgresProjectTemplateRepository(config PostgresConfig, tableName string) (ProjectTemplateRepository, error) {
+	if tableName == "" {
+		tableName = conf.DefaultProjectTemplatesTableName
+	}
+
+	// Build connection string
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	repo := &PostgresProjectTemplateRepository{
+		db:        db,
+		tableName: tableName,
+	}
+
+	return repo, nil
+}
+
+// NewPostgresProjectTemplateRepositoryWithDB creates a new PostgreSQL project template repository with an existing DB connection
+func NewPostgresProjectTemplateRepositoryWithDB(db *sql.DB, tableName string) ProjectTemplateRepository {
+	if tableName == "" {
+		tableName = conf.DefaultProjectTemplatesTableName
+	}
+
+	return &PostgresProjectTemplateRepository{
+		db:        db,
+		tableName: tableName,
+	}
+}
+
+// CreateTemplate stores a new custom project template
+func (r *PostgresProjectTemplateRepository) CreateTemplate(ctx context.Context, template *models.ProjectTemplate) error {
+	definition, err := json.Marshal(template)
+	if err != nil {
+		return fmt.Errorf("failed to marshal template: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (template_id, name, definition, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, r.tableName)
+
+	_, err = r.db.ExecContext(ctx, query,
+		template.TemplateID, template.Name, definition, template.CreatedAt, template.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create project template: %w", err)
+	}
+
+	return nil
+}
+
+// GetTemplate retrieves a custom project template by ID
+func (r *PostgresProjectTemplateRepository) GetTemplate(ctx context.Context, templateID string) (*models.ProjectTemplate, error) {
+	query := fmt.Sprintf(`SELECT definition FROM %s WHERE template_id = $1`, r.tableName)
+
+	var definition []byte
+	err := r.db.QueryRowContext(ctx, query, templateID).Scan(&definition)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get project template: %w", err)
+	}
+
+	var template models.ProjectTemplate
+	if err := json.Unmarshal(definition, &template); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal project template %s: %w", templateID, err)
+	}
+
+	return &template, nil
+}
+
+// ListTemplates lists all custom project templates ordered by name
+func (r *PostgresProjectTemplateRepository) ListTemplates(ctx context.Context) ([]*models.ProjectTemplate, error) {
+	query := fmt.Sprintf(`SELECT template_id, definition FROM %s ORDER BY name ASC`, r.tableName)
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list project templates: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close rows in ListTemplates", "error", closeErr)
+		}
+	}()
+
+	var templates []*models.ProjectTemplate
+	for rows.Next() {
+		var templateID string
+		var definition []byte
+		if err := rows.Scan(&templateID, &definition); err != nil {
+			return nil, err
+		}
+
+		var template models.ProjectTemplate
+		if err := json.Unmarshal(definition, &template); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal project template %s: %w", templateID, err)
+		}
+		templates = append(templates, &template)
+	}
+
+	return templates, rows.Err()
+}
+
+// DeleteTemplate deletes a custom project template by ID
+func (r *PostgresProjectTemplateRepository) DeleteTemplate(ctx context.Context, templateID string) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE template_id = $1`, r.tableName)
+
+	result, err := r.db.ExecContext(ctx, query, templateID)
+	if err != nil {
+		return fmt.Errorf("failed to delete project template: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return apperrors.NotFound(apperrors.CodeProjectTemplateNotFound, "project template not found: %s", templateID)
+	}
+
+	return nil
+}
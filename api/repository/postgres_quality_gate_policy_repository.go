@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// PostgresQualityGatePolicyRepository implements QualityGatePolicyRepository using PostgreSQL
+type PostgresQualityGatePolicyRepository struct {
+	db        *sql.DB
+	tableName string
+}
+
+// NewPostgresQualityGatePolicyRepository creates a new PostgreSQL quality gate policy repository
+func NewPostgresQualityGatePolicyRepository(config PostgresConfig, tableName string) (QualityGatePolicyRepository, error) {
+	if tableName == "" {
+		tableName = conf.DefaultQualityGatePoliciesTableName
+	}
+
+	// Build connection string
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	repo := &PostgresQualityGatePolicyRepository{
+		db:        db,
+		tableName: tableName,
+	}
+
+	return repo, nil
+}
+
+// NewPostgresQualityGatePolicyRepositoryWithDB creates a new PostgreSQL quality gate policy repository with an existing DB connection
+func NewPostgresQualityGatePolicyRepositoryWithDB(db *sql.DB, tableName string) QualityGatePolicyRepository {
+	if tableName == "" {
+		tableName = conf.DefaultQualityGatePoliciesTableName
+	}
+
+	return &PostgresQualityGatePolicyRepository{
+		db:        db,
+		tableName: tableName,
+	}
+}
+
+// GetPolicy retrieves the quality gate policy of a project
+func (r *PostgresQualityGatePolicyRepository) GetPolicy(ctx context.Context, projectID string) (*models.QualityGatePolicy, error) {
+	query := fmt.Sprintf(`SELECT project_id, severity_rules, gates, updated_at FROM %s WHERE project_id = $1`, r.tableName)
+
+	var policy models.QualityGatePolicy
+	var rulesJSON, gatesJSON []byte
+	err := r.db.QueryRowContext(ctx, query, projectID).Scan(&policy.ProjectID, &rulesJSON, &gatesJSON, &policy.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get quality gate policy: %w", err)
+	}
+
+	if err := json.Unmarshal(rulesJSON, &policy.SeverityRules); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal severity rules: %w", err)
+	}
+	if err := json.Unmarshal(gatesJSON, &policy.Gates); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal quality gates: %w", err)
+	}
+
+	return &policy, nil
+}
+
+// SavePolicy creates or replaces the quality gate policy of a project
+func (r *PostgresQualityGatePolicyRepository) SavePolicy(ctx context.Context, policy *models.QualityGatePolicy) error {
+	rules := policy.SeverityRules
+	if rules == nil {
+		rules = []models.SeverityRule{}
+	}
+	rulesJSON, err := json.Marshal(rules)
+	if err != nil {
+		return fmt.Errorf("failed to marshal severity rules: %w", err)
+	}
+	gates := policy.Gates
+	if gates == nil {
+		gates = []models.QualityGate{}
+	}
+	gatesJSON, err := json.Marshal(gates)
+	if err != nil {
+		return fmt.Errorf("failed to marshal quality gates: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (project_id, severity_rules, gates, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (project_id) DO UPDATE SET
+			severity_rules = EXCLUDED.severity_rules,
+			gates = EXCLUDED.gates,
+			updated_at = EXCLUDED.updated_at
+	`, r.tableName)
+
+	if _, err := r.db.ExecContext(ctx, query, policy.ProjectID, rulesJSON, gatesJSON, policy.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to save quality gate policy: %w", err)
+	}
+
+	return nil
+}
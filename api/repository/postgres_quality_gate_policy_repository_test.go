@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+func TestPostgresQualityGatePolicyRepository_GetPolicy(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresQualityGatePolicyRepositoryWithDB(db, "quality_gate_policies")
+	updatedAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT project_id, severity_rules, gates, updated_at FROM quality_gate_policies WHERE project_id = \$1`).
+		WithArgs("proj-1").
+		WillReturnRows(sqlmock.NewRows([]string{"project_id", "severity_rules", "gates", "updated_at"}).
+			AddRow("proj-1", []byte(`[{"source":"dead_code","severity":"high"}]`),
+				[]byte(`[{"name":"No new critical findings","metric":"new_findings","min_severity":"critical","threshold":0}]`), updatedAt))
+
+	policy, err := repo.GetPolicy(context.Background(), "proj-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, []models.SeverityRule{{Source: "dead_code", Severity: models.FindingSeverityHigh}}, policy.SeverityRules)
+	require.Len(t, policy.Gates, 1)
+	assert.Equal(t, models.QualityGateMetricNewFindings, policy.Gates[0].Metric)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresQualityGatePolicyRepository_GetPolicy_NotConfigured(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresQualityGatePolicyRepositoryWithDB(db, "quality_gate_policies")
+
+	mock.ExpectQuery(`SELECT .+ FROM quality_gate_policies`).
+		WithArgs("proj-1").
+		WillReturnRows(sqlmock.NewRows([]string{"project_id", "severity_rules", "gates", "updated_at"}))
+
+	policy, err := repo.GetPolicy(context.Background(), "proj-1")
+
+	require.NoError(t, err)
+	assert.Nil(t, policy)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresQualityGatePolicyRepository_SavePolicy_StoresEmptyLists(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresQualityGatePolicyRepositoryWithDB(db, "quality_gate_policies")
+	updatedAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	mock.ExpectExec(`INSERT INTO quality_gate_policies .+ ON CONFLICT \(project_id\) DO UPDATE`).
+		WithArgs("proj-1", []byte(`[]`), []byte(`[]`), &updatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.SavePolicy(context.Background(), &models.QualityGatePolicy{ProjectID: "proj-1", UpdatedAt: &updatedAt})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
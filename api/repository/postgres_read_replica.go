@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// readOnlyKey is the context key of the hint that an operation only reads
+type readOnlyKey struct{}
+
+// WithReadOnly hints that the operation of ctx only reads and tolerates the replication lag of the read replica, such
+// as a report or a large listing. The repositories supporting it serve its queries from the replica when one is
+// configured and available.
+func WithReadOnly(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readOnlyKey{}, true)
+}
+
+// IsReadOnly reports whether ctx carries the read-only hint
+func IsReadOnly(ctx context.Context) bool {
+	readOnly, _ := ctx.Value(readOnlyKey{}).(bool)
+	return readOnly
+}
+
+// openReadReplica opens the read replica of config, nil when none is configured. It isn't pinged, so a replica that
+// is down doesn't keep the API from starting; its queries fall back to the primary instead.
+func openReadReplica(config PostgresConfig) (*sql.DB, error) {
+	if config.ReplicaHost == "" {
+		return nil, nil
+	}
+
+	port := config.ReplicaPort
+	if port == 0 {
+		port = config.Port
+	}
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.ReplicaHost, port, config.Username, config.Password, config.Database, config.SSLMode)
+
+	replica, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open read replica: %w", err)
+	}
+	return replica, nil
+}
+
+// readRouter sends the queries of operations hinted read-only to the read replica and every other query to the
+// primary. When a query fails on the replica and the replica doesn't answer a ping either, the query is run on the
+// primary, which serves the read-only queries until retryInterval has passed.
+type readRouter struct {
+	primary       *sql.DB
+	replica       *sql.DB
+	retryInterval time.Duration
+	now           func() time.Time
+
+	mu               sync.Mutex
+	unavailableUntil time.Time
+}
+
+// newReadRouter creates a router over primary and replica. A nil replica sends every query to the primary.
+func newReadRouter(primary, replica *sql.DB, retryInterval time.Duration) *readRouter {
+	return &readRouter{
+		primary:       primary,
+		replica:       replica,
+		retryInterval: retryInterval,
+		now:           time.Now,
+	}
+}
+
+// QueryContext runs a query on the replica when ctx is hinted read-only and the replica is available, and on the
+// primary otherwise
+func (r *readRouter) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	if r.useReplica(ctx) {
+		rows, err := r.replica.QueryContext(ctx, query, args...)
+		if err == nil || !r.replicaFailed(ctx, err) {
+			return rows, err
+		}
+	}
+	return r.primary.QueryContext(ctx, query, args...)
+}
+
+// QueryRowScan runs a query returning at most one row like QueryContext and scans the row into dest, returning
+// sql.ErrNoRows when there is none
+func (r *readRouter) QueryRowScan(ctx context.Context, query string, args []any, dest ...any) error {
+	rows, err := r.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close row", "error", closeErr)
+		}
+	}()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+	return rows.Scan(dest...)
+}
+
+// useReplica reports whether the queries of ctx go to the replica
+func (r *readRouter) useReplica(ctx context.Context) bool {
+	if r.replica == nil || !IsReadOnly(ctx) {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return !r.now().Before(r.unavailableUntil)
+}
+
+// replicaFailed reports whether a query failed because the replica is unavailable, marking it unavailable for the
+// retry interval. A replica that still answers pings returned an error the primary would return too.
+func (r *readRouter) replicaFailed(ctx context.Context, queryErr error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err := r.replica.PingContext(ctx); err == nil {
+		return false
+	}
+
+	r.mu.Lock()
+	r.unavailableUntil = r.now().Add(r.retryInterval)
+	r.mu.Unlock()
+
+	slog.WarnContext(ctx, "read replica unavailable, falling back to the primary", "retry_in", r.retryInterval, "error", queryErr)
+	return true
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadRouter_RoutesReadOnlyQueriesToReplica(t *testing.T) {
+	primary, primaryMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer primary.Close() //nolint:errcheck // Test cleanup
+	replica, replicaMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer replica.Close() //nolint:errcheck // Test cleanup
+
+	router := newReadRouter(primary, replica, time.Minute)
+
+	replicaMock.ExpectQuery(`SELECT COUNT\(\*\) FROM tasks`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(7))
+	primaryMock.ExpectQuery(`SELECT COUNT\(\*\) FROM tasks`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(8))
+
+	var readOnlyCount, count int
+	require.NoError(t, router.QueryRowScan(WithReadOnly(context.Background()), `SELECT COUNT(*) FROM tasks`, nil, &readOnlyCount))
+	require.NoError(t, router.QueryRowScan(context.Background(), `SELECT COUNT(*) FROM tasks`, nil, &count))
+
+	assert.Equal(t, 7, readOnlyCount)
+	assert.Equal(t, 8, count)
+	assert.NoError(t, primaryMock.ExpectationsWereMet())
+	assert.NoError(t, replicaMock.ExpectationsWereMet())
+}
+
+func TestReadRouter_FallsBackToPrimaryWhileReplicaIsUnavailable(t *testing.T) {
+	primary, primaryMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer primary.Close() //nolint:errcheck // Test cleanup
+	replica, replicaMock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(t, err)
+	defer replica.Close() //nolint:errcheck // Test cleanup
+
+	now := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+	router := newReadRouter(primary, replica, time.Minute)
+	router.now = func() time.Time { return now }
+	ctx := WithReadOnly(context.Background())
+
+	// The replica fails the query and the ping, so the primary serves it and the next one
+	replicaMock.ExpectQuery(`SELECT day FROM task_metrics`).WillReturnError(errors.New("connection refused"))
+	replicaMock.ExpectPing().WillReturnError(errors.New("connection refused"))
+	primaryMock.ExpectQuery(`SELECT day FROM task_metrics`).WillReturnRows(sqlmock.NewRows([]string{"day"}).AddRow("2024-01-15"))
+	primaryMock.ExpectQuery(`SELECT day FROM task_metrics`).WillReturnRows(sqlmock.NewRows([]string{"day"}).AddRow("2024-01-15"))
+
+	var day string
+	require.NoError(t, router.QueryRowScan(ctx, `SELECT day FROM task_metrics`, nil, &day))
+	require.NoError(t, router.QueryRowScan(ctx, `SELECT day FROM task_metrics`, nil, &day))
+	assert.NoError(t, primaryMock.ExpectationsWereMet())
+	assert.NoError(t, replicaMock.ExpectationsWereMet())
+
+	// After the retry interval the replica is tried again
+	now = now.Add(time.Minute)
+	replicaMock.ExpectQuery(`SELECT day FROM task_metrics`).WillReturnRows(sqlmock.NewRows([]string{"day"}).AddRow("2024-01-14"))
+
+	require.NoError(t, router.QueryRowScan(ctx, `SELECT day FROM task_metrics`, nil, &day))
+	assert.Equal(t, "2024-01-14", day)
+	assert.NoError(t, replicaMock.ExpectationsWereMet())
+}
+
+func TestReadRouter_ReturnsQueryErrorsOfAvailableReplica(t *testing.T) {
+	primary, primaryMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer primary.Close() //nolint:errcheck // Test cleanup
+	replica, replicaMock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(t, err)
+	defer replica.Close() //nolint:errcheck // Test cleanup
+
+	router := newReadRouter(primary, replica, time.Minute)
+
+	replicaMock.ExpectQuery(`SELECT missing FROM tasks`).WillReturnError(errors.New(`column "missing" does not exist`))
+	replicaMock.ExpectPing()
+
+	_, err = router.QueryContext(WithReadOnly(context.Background()), `SELECT missing FROM tasks`)
+
+	assert.ErrorContains(t, err, "does not exist")
+	assert.NoError(t, primaryMock.ExpectationsWereMet())
+	assert.NoError(t, replicaMock.ExpectationsWereMet())
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// redactionAuditColumns lists the redaction audit columns in the order expected by scanRedactionAudit
+const redactionAuditColumns = `audit_id, project_id, agent_id, operation, files_scanned, files_redacted, redactions, counts, synced_at`
+
+// PostgresRedactionAuditRepository implements RedactionAuditRepository using PostgreSQL
+type PostgresRedactionAuditRepository struct {
+	db        *sql.DB
+	tableName string
+}
+
+// NewPostgresRedactionAuditRepository creates a new PostgreSQL redaction audit repository
+func NewPostgresRedactionAuditRepository(config PostgresConfig, tableName string) (RedactionAuditRepository, error) {
+	if tableName == "" {
+		tableName = conf.DefaultRedactionAuditsTableName
+	}
+
+	// Build connection string
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	repo := &PostgresRedactionAuditRepository{
+		db:        db,
+		tableName: tableName,
+	}
+
+	return repo, nil
+}
+
+// NewPostgresRedactionAuditRepositoryWithDB creates a new PostgreSQL redaction audit repository with an existing DB connection
+func NewPostgresRedactionAuditRepositoryWithDB(db *sql.DB, tableName string) RedactionAuditRepository {
+	if tableName == "" {
+		tableName = conf.DefaultRedactionAuditsTableName
+	}
+
+	return &PostgresRedactionAuditRepository{
+		db:        db,
+		tableName: tableName,
+	}
+}
+
+// CreateAudit stores the redaction counts of a sync
+func (r *PostgresRedactionAuditRepository) CreateAudit(ctx context.Context, audit *models.RedactionAudit) error {
+	counts := audit.Counts
+	if counts == nil {
+		counts = map[string]int{}
+	}
+	countsJSON, err := json.Marshal(counts)
+	if err != nil {
+		return fmt.Errorf("failed to marshal redaction counts: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (%s)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, r.tableName, redactionAuditColumns)
+
+	_, err = r.db.ExecContext(ctx, query,
+		audit.AuditID, audit.ProjectID, audit.AgentID, audit.Operation, audit.FilesScanned, audit.FilesRedacted,
+		audit.Redactions, countsJSON, audit.SyncedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create redaction audit: %w", err)
+	}
+
+	return nil
+}
+
+// ListAudits lists the most recent audits of a project, newest first
+func (r *PostgresRedactionAuditRepository) ListAudits(ctx context.Context, projectID string, limit int) ([]models.RedactionAudit, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s
+		WHERE project_id = $1
+		ORDER BY synced_at DESC
+		LIMIT $2
+	`, redactionAuditColumns, r.tableName)
+
+	rows, err := r.db.QueryContext(ctx, query, projectID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list redaction audits: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close redaction audit rows", "error", closeErr)
+		}
+	}()
+
+	audits := []models.RedactionAudit{}
+	for rows.Next() {
+		audit, err := scanRedactionAudit(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan redaction audit: %w", err)
+		}
+		audits = append(audits, *audit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate redaction audits: %w", err)
+	}
+
+	return audits, nil
+}
+
+// StreamAudits passes every audit of a project to fn, newest first, reading them from the database as fn consumes
+// them
+func (r *PostgresRedactionAuditRepository) StreamAudits(ctx context.Context, projectID string, fn func(models.RedactionAudit) error) error {
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s
+		WHERE project_id = $1
+		ORDER BY synced_at DESC
+	`, redactionAuditColumns, r.tableName)
+
+	rows, err := r.db.QueryContext(ctx, query, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to stream redaction audits: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close redaction audit rows", "error", closeErr)
+		}
+	}()
+
+	for rows.Next() {
+		audit, err := scanRedactionAudit(rows)
+		if err != nil {
+			return fmt.Errorf("failed to scan redaction audit: %w", err)
+		}
+		if err := fn(*audit); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate redaction audits: %w", err)
+	}
+
+	return nil
+}
+
+// scanRedactionAudit scans a single row selected with redactionAuditColumns
+func scanRedactionAudit(row rowScanner) (*models.RedactionAudit, error) {
+	var audit models.RedactionAudit
+	var countsJSON []byte
+
+	err := row.Scan(
+		&audit.AuditID, &audit.ProjectID, &audit.AgentID, &audit.Operation, &audit.FilesScanned, &audit.FilesRedacted,
+		&audit.Redactions, &countsJSON, &audit.SyncedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(countsJSON, &audit.Counts); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal redaction counts for audit %s: %w", audit.AuditID, err)
+	}
+
+	return &audit, nil
+}
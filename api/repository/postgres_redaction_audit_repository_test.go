@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+func TestPostgresRedactionAuditRepository_CreateAudit(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresRedactionAuditRepositoryWithDB(db, "redaction_audits")
+	syncedAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	mock.ExpectExec(`INSERT INTO redaction_audits`).
+		WithArgs("redaction-1", "proj-1", "agent-1", models.RedactionOperationCreate, 40, 2, 3,
+			[]byte(`{"credential":1,"email":2}`), syncedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.CreateAudit(context.Background(), &models.RedactionAudit{
+		AuditID: "redaction-1", ProjectID: "proj-1", AgentID: "agent-1", Operation: models.RedactionOperationCreate,
+		FilesScanned: 40, FilesRedacted: 2, Redactions: 3,
+		Counts:   map[string]int{"email": 2, "credential": 1},
+		SyncedAt: syncedAt,
+	})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresRedactionAuditRepository_ListAudits(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresRedactionAuditRepositoryWithDB(db, "redaction_audits")
+	syncedAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	columns := []string{"audit_id", "project_id", "agent_id", "operation", "files_scanned", "files_redacted", "redactions",
+		"counts", "synced_at"}
+	mock.ExpectQuery(`SELECT .+ FROM redaction_audits\s+WHERE project_id = \$1\s+ORDER BY synced_at DESC\s+LIMIT \$2`).
+		WithArgs("proj-1", 50).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("redaction-1", "proj-1", "agent-1", "rebuild", 40, 1, 4, []byte(`{"customer-id":4}`), syncedAt))
+
+	audits, err := repo.ListAudits(context.Background(), "proj-1", 50)
+
+	require.NoError(t, err)
+	require.Len(t, audits, 1)
+	assert.Equal(t, models.RedactionOperationRebuild, audits[0].Operation)
+	assert.Equal(t, map[string]int{"customer-id": 4}, audits[0].Counts)
+	assert.Equal(t, 4, audits[0].Redactions)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresRedactionAuditRepository_StreamAudits_StopsWhenConsumerFails(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresRedactionAuditRepositoryWithDB(db, "redaction_audits")
+	syncedAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	columns := []string{"audit_id", "project_id", "agent_id", "operation", "files_scanned", "files_redacted", "redactions",
+		"counts", "synced_at"}
+	mock.ExpectQuery(`SELECT .+ FROM redaction_audits\s+WHERE project_id = \$1\s+ORDER BY synced_at DESC\s*$`).
+		WithArgs("proj-1").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("redaction-2", "proj-1", "agent-1", "sync", 40, 0, 0, []byte(`{}`), syncedAt).
+			AddRow("redaction-1", "proj-1", "agent-1", "rebuild", 40, 1, 4, []byte(`{"customer-id":4}`), syncedAt))
+
+	clientGone := errors.New("client went away")
+	var streamed []string
+	err = repo.StreamAudits(context.Background(), "proj-1", func(audit models.RedactionAudit) error {
+		streamed = append(streamed, audit.AuditID)
+		return clientGone
+	})
+
+	assert.ErrorIs(t, err, clientGone)
+	assert.Equal(t, []string{"redaction-2"}, streamed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// PostgresRedactionPolicyRepository implements RedactionPolicyRepository using PostgreSQL
+type PostgresRedactionPolicyRepository struct {
+	db        *sql.DB
+	tableName string
+}
+
+// NewPostgresRedactionPolicyRepository creates a new PostgreSQL redaction policy repository
+func NewPostgresRedactionPolicyRepository(config PostgresConfig, tableName string) (RedactionPolicyRepository, error) {
+	if tableName == "" {
+		tableName = conf.DefaultRedactionPoliciesTableName
+	}
+
+	// Build connection string
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	repo := &PostgresRedactionPolicyRepository{
+		db:        db,
+		tableName: tableName,
+	}
+
+	return repo, nil
+}
+
+// NewPostgresRedactionPolicyRepositoryWithDB creates a new PostgreSQL redaction policy repository with an existing DB connection
+func NewPostgresRedactionPolicyRepositoryWithDB(db *sql.DB, tableName string) RedactionPolicyRepository {
+	if tableName == "" {
+		tableName = conf.DefaultRedactionPoliciesTableName
+	}
+
+	return &PostgresRedactionPolicyRepository{
+		db:        db,
+		tableName: tableName,
+	}
+}
+
+// GetPolicy retrieves the redaction policy of a project
+func (r *PostgresRedactionPolicyRepository) GetPolicy(ctx context.Context, projectID string) (*models.RedactionPolicy, error) {
+	query := fmt.Sprintf(`SELECT project_id, mask_emails, mask_credentials, patterns, log_llm_content, files, updated_at FROM %s WHERE project_id = $1`, r.tableName)
+
+	var policy models.RedactionPolicy
+	var patternsJSON, filesJSON []byte
+	err := r.db.QueryRowContext(ctx, query, projectID).Scan(
+		&policy.ProjectID, &policy.MaskEmails, &policy.MaskCredentials, &patternsJSON, &policy.LogLLMContent, &filesJSON, &policy.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get redaction policy: %w", err)
+	}
+
+	if err := json.Unmarshal(patternsJSON, &policy.Patterns); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal redaction patterns: %w", err)
+	}
+	// A NULL filter was never configured and leaves the default applied
+	if filesJSON != nil {
+		if err := json.Unmarshal(filesJSON, &policy.Files); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal noise file filter: %w", err)
+		}
+	}
+
+	return &policy, nil
+}
+
+// SavePolicy creates or replaces the redaction policy of a project
+func (r *PostgresRedactionPolicyRepository) SavePolicy(ctx context.Context, policy *models.RedactionPolicy) error {
+	patterns := policy.Patterns
+	if patterns == nil {
+		patterns = []models.RedactionPattern{}
+	}
+	patternsJSON, err := json.Marshal(patterns)
+	if err != nil {
+		return fmt.Errorf("failed to marshal redaction patterns: %w", err)
+	}
+	var filesJSON any // NULL when no filter is configured
+	if policy.Files != nil {
+		files, err := json.Marshal(policy.Files)
+		if err != nil {
+			return fmt.Errorf("failed to marshal noise file filter: %w", err)
+		}
+		filesJSON = files
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (project_id, mask_emails, mask_credentials, patterns, log_llm_content, files, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (project_id) DO UPDATE SET
+			mask_emails = EXCLUDED.mask_emails,
+			mask_credentials = EXCLUDED.mask_credentials,
+			patterns = EXCLUDED.patterns,
+			log_llm_content = EXCLUDED.log_llm_content,
+			files = EXCLUDED.files,
+			updated_at = EXCLUDED.updated_at
+	`, r.tableName)
+
+	_, err = r.db.ExecContext(ctx, query,
+		policy.ProjectID, policy.MaskEmails, policy.MaskCredentials, patternsJSON, policy.LogLLMContent, filesJSON, policy.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save redaction policy: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,502 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// projectMemberColumns are the columns scanned by scanProjectMember, in order
+const projectMemberColumns = `project_id, user_id, role, created_at, updated_at`
+
+// PostgresRoleRepository implements RoleRepository using PostgreSQL. Roles, the permissions they can grant, the
+// permissions each role grants and the members of projects are kept in four tables.
+type PostgresRoleRepository struct {
+	db                       *sql.DB
+	tableName                string
+	permissionsTableName     string
+	rolePermissionsTableName string
+	projectMembersTableName  string
+}
+
+// NewPostgresRoleRepository creates a new PostgreSQL role repository, seeding the built-in permissions and roles
+func NewPostgresRoleRepository(config PostgresConfig, tableName, permissionsTableName, rolePermissionsTableName, projectMembersTableName string) (RoleRepository, error) {
+	// Build connection string
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	repo := NewPostgresRoleRepositoryWithDB(db, tableName, permissionsTableName, rolePermissionsTableName, projectMembersTableName).(*PostgresRoleRepository)
+
+	if err := repo.seedBuiltIns(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to seed built-in roles: %w", err)
+	}
+
+	return repo, nil
+}
+
+// NewPostgresRoleRepositoryWithDB creates a new PostgreSQL role repository with an existing DB connection
+func NewPostgresRoleRepositoryWithDB(db *sql.DB, tableName, permissionsTableName, rolePermissionsTableName, projectMembersTableName string) RoleRepository {
+	if tableName == "" {
+		tableName = conf.DefaultRolesTableName
+	}
+	if permissionsTableName == "" {
+		permissionsTableName = conf.DefaultPermissionsTableName
+	}
+	if rolePermissionsTableName == "" {
+		rolePermissionsTableName = conf.DefaultRolePermissionsTableName
+	}
+	if projectMembersTableName == "" {
+		projectMembersTableName = conf.DefaultProjectMembersTableName
+	}
+
+	return &PostgresRoleRepository{
+		db:                       db,
+		tableName:                tableName,
+		permissionsTableName:     permissionsTableName,
+		rolePermissionsTableName: rolePermissionsTableName,
+		projectMembersTableName:  projectMembersTableName,
+	}
+}
+
+// seedBuiltIns registers the built-in permissions and creates the built-in roles missing. The permissions of built-in
+// roles that exist already are left alone, so changes made to them are kept.
+func (r *PostgresRoleRepository) seedBuiltIns(ctx context.Context) error {
+	for _, permission := range models.BuiltInPermissions {
+		query := fmt.Sprintf(`
+			INSERT INTO %s (name, description) VALUES ($1, $2)
+			ON CONFLICT (name) DO UPDATE SET description = EXCLUDED.description
+		`, r.permissionsTableName)
+		if _, err := r.db.ExecContext(ctx, query, permission.Name, permission.Description); err != nil {
+			return fmt.Errorf("failed to register permission %s: %w", permission.Name, err)
+		}
+	}
+
+	for _, role := range models.BuiltInRoles {
+		query := fmt.Sprintf(`
+			INSERT INTO %s (name, description, built_in) VALUES ($1, $2, TRUE)
+			ON CONFLICT (name) DO NOTHING
+		`, r.tableName)
+		result, err := r.db.ExecContext(ctx, query, role.Name, role.Description)
+		if err != nil {
+			return fmt.Errorf("failed to create role %s: %w", role.Name, err)
+		}
+
+		created, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if created == 0 {
+			continue
+		}
+
+		if err := r.insertRolePermissions(ctx, r.db, role.Name, role.Permissions); err != nil {
+			return fmt.Errorf("failed to grant permissions to role %s: %w", role.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// ListPermissions lists the permissions roles can grant ordered by name
+func (r *PostgresRoleRepository) ListPermissions(ctx context.Context) ([]models.PermissionDefinition, error) {
+	query := fmt.Sprintf(`SELECT name, description FROM %s ORDER BY name`, r.permissionsTableName)
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list permissions: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close rows in ListPermissions", "error", closeErr)
+		}
+	}()
+
+	permissions := []models.PermissionDefinition{}
+	for rows.Next() {
+		var permission models.PermissionDefinition
+		if err := rows.Scan(&permission.Name, &permission.Description); err != nil {
+			return nil, fmt.Errorf("failed to scan permission: %w", err)
+		}
+		permissions = append(permissions, permission)
+	}
+
+	return permissions, rows.Err()
+}
+
+// CreateRole creates a role with its permissions
+func (r *PostgresRoleRepository) CreateRole(ctx context.Context, role *models.Role) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				slog.ErrorContext(ctx, "failed to rollback role creation", "error", rollbackErr)
+			}
+		}
+	}()
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (name, description, built_in, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, r.tableName)
+
+	if _, err = tx.ExecContext(ctx, query, role.Name, role.Description, role.BuiltIn, role.CreatedAt, role.UpdatedAt); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" { // unique_violation
+			return apperrors.Conflict(apperrors.CodeRoleExists, "role %s already exists", role.Name)
+		}
+		return fmt.Errorf("failed to create role: %w", err)
+	}
+
+	if err = r.insertRolePermissions(ctx, tx, role.Name, role.Permissions); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit role creation: %w", err)
+	}
+
+	return nil
+}
+
+// GetRole retrieves a role with its permissions
+func (r *PostgresRoleRepository) GetRole(ctx context.Context, name models.UserRole) (*models.Role, error) {
+	roles, err := r.queryRoles(ctx, "WHERE r.name = $1", name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get role: %w", err)
+	}
+	if len(roles) == 0 {
+		return nil, nil
+	}
+
+	return &roles[0], nil
+}
+
+// UpdateRole replaces the description and permissions of a role
+func (r *PostgresRoleRepository) UpdateRole(ctx context.Context, role *models.Role) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				slog.ErrorContext(ctx, "failed to rollback role update", "error", rollbackErr)
+			}
+		}
+	}()
+
+	query := fmt.Sprintf(`UPDATE %s SET description = $2, updated_at = $3 WHERE name = $1`, r.tableName)
+
+	result, err := tx.ExecContext(ctx, query, role.Name, role.Description, role.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update role: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return apperrors.NotFound(apperrors.CodeRoleNotFound, "role not found: %s", role.Name)
+	}
+
+	deleteQuery := fmt.Sprintf(`DELETE FROM %s WHERE role = $1`, r.rolePermissionsTableName)
+	if _, err = tx.ExecContext(ctx, deleteQuery, role.Name); err != nil {
+		return fmt.Errorf("failed to revoke role permissions: %w", err)
+	}
+
+	if err = r.insertRolePermissions(ctx, tx, role.Name, role.Permissions); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit role update: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteRole deletes a role along with the permissions it grants
+func (r *PostgresRoleRepository) DeleteRole(ctx context.Context, name models.UserRole) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE name = $1`, r.tableName)
+
+	result, err := r.db.ExecContext(ctx, query, name)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" { // foreign_key_violation
+			return apperrors.Conflict(apperrors.CodeRoleInUse, "role %s is held by project members", name)
+		}
+		return fmt.Errorf("failed to delete role: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return apperrors.NotFound(apperrors.CodeRoleNotFound, "role not found: %s", name)
+	}
+
+	return nil
+}
+
+// ListRoles lists the roles with their permissions ordered by name
+func (r *PostgresRoleRepository) ListRoles(ctx context.Context) ([]models.Role, error) {
+	roles, err := r.queryRoles(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list roles: %w", err)
+	}
+
+	return roles, nil
+}
+
+// SetProjectMember adds a member to a project or replaces the role of a member
+func (r *PostgresRoleRepository) SetProjectMember(ctx context.Context, member *models.ProjectMember) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (%s)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (project_id, user_id) DO UPDATE SET role = EXCLUDED.role, updated_at = EXCLUDED.updated_at
+	`, r.projectMembersTableName, projectMemberColumns)
+
+	_, err := r.db.ExecContext(ctx, query,
+		member.ProjectID, member.UserID, member.Role, member.CreatedAt, member.UpdatedAt,
+	)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) {
+			switch pqErr.Code {
+			case "23503": // foreign_key_violation
+				return apperrors.NotFound(apperrors.CodeRoleNotFound, "role not found: %s", member.Role)
+			case "23505": // unique_violation
+				return apperrors.Conflict(apperrors.CodeProjectOwner, "project %s already has an owner", member.ProjectID)
+			}
+		}
+		return fmt.Errorf("failed to set project member: %w", err)
+	}
+
+	return nil
+}
+
+// GetProjectMember retrieves a member of a project
+func (r *PostgresRoleRepository) GetProjectMember(ctx context.Context, projectID, userID string) (*models.ProjectMember, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE project_id = $1 AND user_id = $2`, projectMemberColumns, r.projectMembersTableName)
+
+	member, err := scanProjectMember(r.db.QueryRowContext(ctx, query, projectID, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get project member: %w", err)
+	}
+
+	return member, nil
+}
+
+// DeleteProjectMember removes a member from a project
+func (r *PostgresRoleRepository) DeleteProjectMember(ctx context.Context, projectID, userID string) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE project_id = $1 AND user_id = $2`, r.projectMembersTableName)
+
+	result, err := r.db.ExecContext(ctx, query, projectID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete project member: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return apperrors.NotFound(apperrors.CodeProjectMemberNotFound, "user %s is not a member of project %s", userID, projectID)
+	}
+
+	return nil
+}
+
+// ListProjectMembers lists the members of a project ordered by user
+func (r *PostgresRoleRepository) ListProjectMembers(ctx context.Context, projectID string) ([]models.ProjectMember, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE project_id = $1 ORDER BY user_id`, projectMemberColumns, r.projectMembersTableName)
+
+	rows, err := r.db.QueryContext(ctx, query, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list project members: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close rows in ListProjectMembers", "error", closeErr)
+		}
+	}()
+
+	members := []models.ProjectMember{}
+	for rows.Next() {
+		member, err := scanProjectMember(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan project member: %w", err)
+		}
+		members = append(members, *member)
+	}
+
+	return members, rows.Err()
+}
+
+// TransferProjectOwnership makes a user the owner of a project, demoting its previous owner to admin
+func (r *PostgresRoleRepository) TransferProjectOwnership(ctx context.Context, projectID, userID string, transferredAt time.Time) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				slog.ErrorContext(ctx, "failed to rollback ownership transfer", "error", rollbackErr)
+			}
+		}
+	}()
+
+	demote := fmt.Sprintf(`
+		UPDATE %s SET role = $1, updated_at = $2
+		WHERE project_id = $3 AND role = $4 AND user_id <> $5
+	`, r.projectMembersTableName)
+	if _, err = tx.ExecContext(ctx, demote, models.RoleAdmin, transferredAt, projectID, models.RoleOwner, userID); err != nil {
+		return fmt.Errorf("failed to demote previous project owner: %w", err)
+	}
+
+	promote := fmt.Sprintf(`
+		INSERT INTO %s (%s)
+		VALUES ($1, $2, $3, $4, $4)
+		ON CONFLICT (project_id, user_id) DO UPDATE SET role = EXCLUDED.role, updated_at = EXCLUDED.updated_at
+	`, r.projectMembersTableName, projectMemberColumns)
+	if _, err = tx.ExecContext(ctx, promote, projectID, userID, models.RoleOwner, transferredAt); err != nil {
+		return fmt.Errorf("failed to set project owner: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit ownership transfer: %w", err)
+	}
+
+	return nil
+}
+
+// StreamProjectMembers passes the members of every project whose role last changed within timeRange to fn, oldest
+// change first, reading them from the database as fn consumes them
+func (r *PostgresRoleRepository) StreamProjectMembers(ctx context.Context, timeRange TimeRange, fn func(models.ProjectMember) error) error {
+	where, args := timeRange.whereClause("updated_at")
+	query := fmt.Sprintf(`SELECT %s FROM %s %s ORDER BY updated_at, project_id, user_id`, projectMemberColumns, r.projectMembersTableName, where)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to stream project members: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close rows in StreamProjectMembers", "error", closeErr)
+		}
+	}()
+
+	for rows.Next() {
+		member, err := scanProjectMember(rows)
+		if err != nil {
+			return fmt.Errorf("failed to scan project member: %w", err)
+		}
+		if err := fn(*member); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate project members: %w", err)
+	}
+
+	return nil
+}
+
+// queryRoles selects the roles matching a WHERE clause on the roles aliased r, aggregating the permissions they grant
+func (r *PostgresRoleRepository) queryRoles(ctx context.Context, where string, args ...any) ([]models.Role, error) {
+	query := fmt.Sprintf(`
+		SELECT r.name, r.description, r.built_in, r.created_at, r.updated_at,
+			COALESCE(array_agg(rp.permission ORDER BY rp.permission) FILTER (WHERE rp.permission IS NOT NULL), '{}')
+		FROM %s r
+		LEFT JOIN %s rp ON rp.role = r.name
+		%s
+		GROUP BY r.name
+		ORDER BY r.name
+	`, r.tableName, r.rolePermissionsTableName, where)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close rows in queryRoles", "error", closeErr)
+		}
+	}()
+
+	roles := []models.Role{}
+	for rows.Next() {
+		var role models.Role
+		var permissions pq.StringArray
+		if err := rows.Scan(&role.Name, &role.Description, &role.BuiltIn, &role.CreatedAt, &role.UpdatedAt, &permissions); err != nil {
+			return nil, err
+		}
+
+		role.Permissions = make([]models.Permission, len(permissions))
+		for i, permission := range permissions {
+			role.Permissions[i] = models.Permission(permission)
+		}
+		roles = append(roles, role)
+	}
+
+	return roles, rows.Err()
+}
+
+// insertRolePermissions grants permissions to a role
+func (r *PostgresRoleRepository) insertRolePermissions(ctx context.Context, exec execer, name models.UserRole, permissions []models.Permission) error {
+	rows := make([][]any, len(permissions))
+	for i, permission := range permissions {
+		rows[i] = []any{name, permission}
+	}
+
+	if err := bulkInsert(ctx, exec, r.rolePermissionsTableName, "role, permission", rows, 0); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" { // foreign_key_violation
+			return apperrors.Validation(apperrors.CodeUnknownPermission, "role %s grants an unknown permission", name)
+		}
+		return fmt.Errorf("failed to grant role permissions: %w", err)
+	}
+
+	return nil
+}
+
+// scanProjectMember scans a row selected with projectMemberColumns
+func scanProjectMember(row rowScanner) (*models.ProjectMember, error) {
+	var member models.ProjectMember
+	if err := row.Scan(
+		&member.ProjectID, &member.UserID, &member.Role, &member.CreatedAt, &member.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	return &member, nil
+}
@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+func newTestRoleRepository(t *testing.T) (RoleRepository, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.Close() //nolint:errcheck,gosec // Test cleanup
+	})
+
+	return NewPostgresRoleRepositoryWithDB(db, "roles", "permissions", "role_permissions", "user_project_access"), mock
+}
+
+func TestPostgresRoleRepository_CreateRole(t *testing.T) {
+	repo, mock := newTestRoleRepository(t)
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO roles \(name, description, built_in, created_at, updated_at\)`).
+		WithArgs(models.UserRole("security-reviewer"), "Reviews scans", false, now, now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO role_permissions \(role, permission\) VALUES \(\$1, \$2\), \(\$3, \$4\)`).
+		WithArgs(models.UserRole("security-reviewer"), models.PermissionProjectRead, models.UserRole("security-reviewer"), models.PermissionTaskRead).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	err := repo.CreateRole(context.Background(), &models.Role{
+		Name:        "security-reviewer",
+		Description: "Reviews scans",
+		Permissions: []models.Permission{models.PermissionProjectRead, models.PermissionTaskRead},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresRoleRepository_CreateRole_UnknownPermission(t *testing.T) {
+	repo, mock := newTestRoleRepository(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO roles`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO role_permissions`).WillReturnError(&pq.Error{Code: "23503"})
+	mock.ExpectRollback()
+
+	err := repo.CreateRole(context.Background(), &models.Role{Name: "security-reviewer", Permissions: []models.Permission{"scan:read"}})
+
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	assert.Equal(t, apperrors.CodeUnknownPermission, apperrors.CodeOf(err))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresRoleRepository_GetRole(t *testing.T) {
+	repo, mock := newTestRoleRepository(t)
+	now := time.Now()
+
+	query := `SELECT r.name, r.description, r.built_in, r.created_at, r.updated_at,.+FROM roles r\s+LEFT JOIN role_permissions rp ON rp.role = r.name\s+WHERE r.name = \$1`
+	mock.ExpectQuery(query).
+		WithArgs(models.RoleViewer).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "description", "built_in", "created_at", "updated_at", "permissions"}).
+			AddRow("viewer", "Read-only access", true, now, now, "{project:read,task:read}"))
+	mock.ExpectQuery(query).
+		WithArgs(models.UserRole("missing")).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "description", "built_in", "created_at", "updated_at", "permissions"}))
+
+	role, err := repo.GetRole(context.Background(), models.RoleViewer)
+	require.NoError(t, err)
+	assert.True(t, role.BuiltIn)
+	assert.Equal(t, []models.Permission{models.PermissionProjectRead, models.PermissionTaskRead}, role.Permissions)
+
+	role, err = repo.GetRole(context.Background(), "missing")
+	require.NoError(t, err)
+	assert.Nil(t, role)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresRoleRepository_DeleteRole_HeldByProjectMembers(t *testing.T) {
+	repo, mock := newTestRoleRepository(t)
+
+	mock.ExpectExec(`DELETE FROM roles WHERE name = \$1`).
+		WithArgs(models.UserRole("security-reviewer")).
+		WillReturnError(&pq.Error{Code: "23503"})
+
+	err := repo.DeleteRole(context.Background(), "security-reviewer")
+
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+	assert.Equal(t, apperrors.CodeRoleInUse, apperrors.CodeOf(err))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresRoleRepository_SetProjectMember(t *testing.T) {
+	repo, mock := newTestRoleRepository(t)
+	now := time.Now()
+
+	mock.ExpectExec(`INSERT INTO user_project_access \(project_id, user_id, role, created_at, updated_at\).+ON CONFLICT \(project_id, user_id\) DO UPDATE SET role = EXCLUDED.role`).
+		WithArgs("proj-1", "usr-1", models.RoleViewer, now, now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.SetProjectMember(context.Background(), &models.ProjectMember{
+		ProjectID: "proj-1", UserID: "usr-1", Role: models.RoleViewer, CreatedAt: now, UpdatedAt: now,
+	})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresRoleRepository_SetProjectMember_SecondOwner(t *testing.T) {
+	repo, mock := newTestRoleRepository(t)
+	now := time.Now()
+
+	mock.ExpectExec(`INSERT INTO user_project_access`).
+		WithArgs("proj-1", "usr-2", models.RoleOwner, now, now).
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	err := repo.SetProjectMember(context.Background(), &models.ProjectMember{
+		ProjectID: "proj-1", UserID: "usr-2", Role: models.RoleOwner, CreatedAt: now, UpdatedAt: now,
+	})
+
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+	assert.Equal(t, apperrors.CodeProjectOwner, apperrors.CodeOf(err))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresRoleRepository_GetProjectMember(t *testing.T) {
+	repo, mock := newTestRoleRepository(t)
+	now := time.Now()
+
+	query := `SELECT project_id, user_id, role, created_at, updated_at FROM user_project_access WHERE project_id = \$1 AND user_id = \$2`
+	mock.ExpectQuery(query).
+		WithArgs("proj-1", "usr-1").
+		WillReturnRows(sqlmock.NewRows([]string{"project_id", "user_id", "role", "created_at", "updated_at"}).
+			AddRow("proj-1", "usr-1", "viewer", now, now))
+	mock.ExpectQuery(query).
+		WithArgs("proj-1", "usr-2").
+		WillReturnRows(sqlmock.NewRows([]string{"project_id", "user_id", "role", "created_at", "updated_at"}))
+
+	member, err := repo.GetProjectMember(context.Background(), "proj-1", "usr-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleViewer, member.Role)
+
+	member, err = repo.GetProjectMember(context.Background(), "proj-1", "usr-2")
+	require.NoError(t, err)
+	assert.Nil(t, member)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresRoleRepository_TransferProjectOwnership(t *testing.T) {
+	repo, mock := newTestRoleRepository(t)
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE user_project_access SET role = \$1, updated_at = \$2\s+WHERE project_id = \$3 AND role = \$4 AND user_id <> \$5`).
+		WithArgs(models.RoleAdmin, now, "proj-1", models.RoleOwner, "usr-2").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO user_project_access`).
+		WithArgs("proj-1", "usr-2", models.RoleOwner, now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repo.TransferProjectOwnership(context.Background(), "proj-1", "usr-2", now)
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
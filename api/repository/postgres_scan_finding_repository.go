@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// scanFindingColumns lists the scan finding columns in the order expected by scanScanFinding
+const scanFindingColumns = `finding_id, codebase_id, kind, rule, severity, file_path, line, message, commit_sha, detected_at`
+
+// PostgresScanFindingRepository implements ScanFindingRepository using PostgreSQL
+type PostgresScanFindingRepository struct {
+	db        *sql.DB
+	tableName string
+	batchSize int
+}
+
+// NewPostgresScanFindingRepository creates a new PostgreSQL scan finding repository writing findings batchSize rows
+// per statement
+func NewPostgresScanFindingRepository(config PostgresConfig, tableName string, batchSize int) (ScanFindingRepository, error) {
+	if tableName == "" {
+		tableName = conf.DefaultScanFindingsTableName
+	}
+
+	// Build connection string
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	repo := &PostgresScanFindingRepository{
+		db:        db,
+		tableName: tableName,
+		batchSize: batchSize,
+	}
+
+	return repo, nil
+}
+
+// NewPostgresScanFindingRepositoryWithDB creates a new PostgreSQL scan finding repository with an existing DB connection
+func NewPostgresScanFindingRepositoryWithDB(db *sql.DB, tableName string, batchSize int) ScanFindingRepository {
+	if tableName == "" {
+		tableName = conf.DefaultScanFindingsTableName
+	}
+
+	return &PostgresScanFindingRepository{
+		db:        db,
+		tableName: tableName,
+		batchSize: batchSize,
+	}
+}
+
+// ReplaceFindings deletes the previous findings of a codebase and stores the new ones in batches within a single
+// transaction, so listings never mix two scans
+func (r *PostgresScanFindingRepository) ReplaceFindings(ctx context.Context, codebaseID string, findings []models.ScanFinding) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				slog.WarnContext(ctx, "failed to rollback scan findings replacement", "error", rollbackErr)
+			}
+		}
+	}()
+
+	deleteQuery := fmt.Sprintf(`DELETE FROM %s WHERE codebase_id = $1`, r.tableName)
+	if _, err = tx.ExecContext(ctx, deleteQuery, codebaseID); err != nil {
+		return fmt.Errorf("failed to delete previous scan findings: %w", err)
+	}
+
+	rows := make([][]any, 0, len(findings))
+	for _, finding := range findings {
+		rows = append(rows, []any{
+			finding.FindingID, codebaseID, finding.Kind, finding.Rule, finding.Severity, finding.FilePath, finding.Line,
+			finding.Message, finding.CommitSHA, finding.DetectedAt,
+		})
+	}
+	if err = bulkInsert(ctx, tx, r.tableName, scanFindingColumns, rows, r.batchSize); err != nil {
+		return fmt.Errorf("failed to create scan findings: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit scan findings: %w", err)
+	}
+
+	return nil
+}
+
+// ListFindings lists the findings of a codebase by file and line
+func (r *PostgresScanFindingRepository) ListFindings(ctx context.Context, codebaseID string) ([]models.ScanFinding, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s
+		WHERE codebase_id = $1
+		ORDER BY file_path, line NULLS FIRST, rule
+	`, scanFindingColumns, r.tableName)
+
+	rows, err := r.db.QueryContext(ctx, query, codebaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scan findings: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close scan finding rows", "error", closeErr)
+		}
+	}()
+
+	findings := []models.ScanFinding{}
+	for rows.Next() {
+		finding, err := scanScanFinding(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan scan finding: %w", err)
+		}
+		findings = append(findings, *finding)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate scan findings: %w", err)
+	}
+
+	return findings, nil
+}
+
+// scanScanFinding scans a single row selected with scanFindingColumns
+func scanScanFinding(row rowScanner) (*models.ScanFinding, error) {
+	var finding models.ScanFinding
+
+	err := row.Scan(
+		&finding.FindingID, &finding.CodebaseID, &finding.Kind, &finding.Rule, &finding.Severity, &finding.FilePath,
+		&finding.Line, &finding.Message, &finding.CommitSHA, &finding.DetectedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &finding, nil
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+func TestPostgresScanFindingRepository_ReplaceFindings(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresScanFindingRepositoryWithDB(db, "scan_findings", 0)
+	detectedAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+	line := 3
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM scan_findings WHERE codebase_id = \$1`).
+		WithArgs("codebase-1").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`INSERT INTO scan_findings`).
+		WithArgs("finding-1", "codebase-1", models.ScanFindingKindSecret, "aws-access-key-id", models.ScanFindingSeverityHigh,
+			"config/prod.env", &line, "Committed AWS access key ID", "abc123", detectedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = repo.ReplaceFindings(context.Background(), "codebase-1", []models.ScanFinding{{
+		FindingID:  "finding-1",
+		Kind:       models.ScanFindingKindSecret,
+		Rule:       "aws-access-key-id",
+		Severity:   models.ScanFindingSeverityHigh,
+		FilePath:   "config/prod.env",
+		Line:       &line,
+		Message:    "Committed AWS access key ID",
+		CommitSHA:  "abc123",
+		DetectedAt: detectedAt,
+	}})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresScanFindingRepository_ReplaceFindings_WritesInBatches(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresScanFindingRepositoryWithDB(db, "scan_findings", 2)
+	detectedAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	findings := make([]models.ScanFinding, 3)
+	for i := range findings {
+		findings[i] = models.ScanFinding{
+			FindingID:  fmt.Sprintf("finding-%d", i+1),
+			Kind:       models.ScanFindingKindSecret,
+			Rule:       "generic-secret",
+			Severity:   models.ScanFindingSeverityMedium,
+			FilePath:   "app/settings.py",
+			Message:    "High-entropy value assigned to SECRET",
+			CommitSHA:  "abc123",
+			DetectedAt: detectedAt,
+		}
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM scan_findings WHERE codebase_id = \$1`).
+		WithArgs("codebase-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO scan_findings \(.+\) VALUES \(.+\), \(.+\)$`).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`INSERT INTO scan_findings \(.+\) VALUES \([^)]+\)$`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = repo.ReplaceFindings(context.Background(), "codebase-1", findings)
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresScanFindingRepository_ListFindings(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresScanFindingRepositoryWithDB(db, "scan_findings", 0)
+	detectedAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{"finding_id", "codebase_id", "kind", "rule", "severity", "file_path", "line", "message", "commit_sha", "detected_at"}).
+		AddRow("finding-1", "codebase-1", "license", "AGPL-3.0", "high", "LICENSE", nil, "AGPL-3.0 license is not allowed to be ingested", "abc123", detectedAt).
+		AddRow("finding-2", "codebase-1", "secret", "generic-secret", "medium", "app/settings.py", 2, "High-entropy value assigned to SECRET", "abc123", detectedAt)
+	mock.ExpectQuery(`SELECT .* FROM scan_findings\s+WHERE codebase_id = \$1\s+ORDER BY file_path, line NULLS FIRST, rule`).
+		WithArgs("codebase-1").
+		WillReturnRows(rows)
+
+	findings, err := repo.ListFindings(context.Background(), "codebase-1")
+
+	require.NoError(t, err)
+	require.Len(t, findings, 2)
+	assert.Equal(t, models.ScanFindingKindLicense, findings[0].Kind)
+	assert.Nil(t, findings[0].Line)
+	require.NotNil(t, findings[1].Line)
+	assert.Equal(t, 2, *findings[1].Line)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
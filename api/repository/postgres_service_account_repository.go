@@ -0,0 +1,271 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// Service account and token columns in the order expected by scanServiceAccount and scanServiceAccountToken
+const (
+	serviceAccountColumns      = `service_account_id, name, description, user_id, created_by, created_at`
+	serviceAccountTokenColumns = `token_id, service_account_id, name, token_hash, created_at, expires_at, last_used_at, revoked_at`
+)
+
+// PostgresServiceAccountRepository implements ServiceAccountRepository using PostgreSQL
+type PostgresServiceAccountRepository struct {
+	db              *sql.DB
+	tableName       string
+	tokensTableName string
+}
+
+// NewPostgresServiceAccountRepository creates a new PostgreSQL service account repository
+func NewPostgresServiceAccountRepository(config PostgresConfig, tableName, tokensTableName string) (ServiceAccountRepository, error) {
+	// Build connection string
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	repo := NewPostgresServiceAccountRepositoryWithDB(db, tableName, tokensTableName).(*PostgresServiceAccountRepository)
+
+	return repo, nil
+}
+
+// NewPostgresServiceAccountRepositoryWithDB creates a new PostgreSQL service account repository with an existing DB connection
+func NewPostgresServiceAccountRepositoryWithDB(db *sql.DB, tableName, tokensTableName string) ServiceAccountRepository {
+	if tableName == "" {
+		tableName = conf.DefaultServiceAccountsTableName
+	}
+	if tokensTableName == "" {
+		tokensTableName = conf.DefaultServiceAccountTokensTableName
+	}
+
+	return &PostgresServiceAccountRepository{
+		db:              db,
+		tableName:       tableName,
+		tokensTableName: tokensTableName,
+	}
+}
+
+// CreateAccount creates a service account, failing with a conflict when its name is taken
+func (r *PostgresServiceAccountRepository) CreateAccount(ctx context.Context, account *models.ServiceAccount) error {
+	query := fmt.Sprintf(`INSERT INTO %s (%s) VALUES ($1, $2, $3, $4, $5, $6)`, r.tableName, serviceAccountColumns)
+
+	_, err := r.db.ExecContext(ctx, query,
+		account.ServiceAccountID, account.Name, account.Description, account.UserID, account.CreatedBy, account.CreatedAt,
+	)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" { // unique_violation
+			return apperrors.Conflict(apperrors.CodeConflict, "service account %s already exists", account.Name)
+		}
+		return fmt.Errorf("failed to create service account: %w", err)
+	}
+
+	return nil
+}
+
+// GetAccount gets a service account by ID
+func (r *PostgresServiceAccountRepository) GetAccount(ctx context.Context, serviceAccountID string) (*models.ServiceAccount, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE service_account_id = $1`, serviceAccountColumns, r.tableName)
+
+	account, err := scanServiceAccount(r.db.QueryRowContext(ctx, query, serviceAccountID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.NotFound(apperrors.CodeServiceAccountNotFound, "service account not found: %s", serviceAccountID)
+		}
+		return nil, fmt.Errorf("failed to get service account: %w", err)
+	}
+
+	return account, nil
+}
+
+// ListAccounts lists the service accounts ordered by name
+func (r *PostgresServiceAccountRepository) ListAccounts(ctx context.Context) ([]models.ServiceAccount, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s ORDER BY name`, serviceAccountColumns, r.tableName)
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service accounts: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close service account rows", "error", closeErr)
+		}
+	}()
+
+	accounts := []models.ServiceAccount{}
+	for rows.Next() {
+		account, err := scanServiceAccount(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan service account: %w", err)
+		}
+		accounts = append(accounts, *account)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate service accounts: %w", err)
+	}
+
+	return accounts, nil
+}
+
+// DeleteAccount deletes a service account along with its tokens
+func (r *PostgresServiceAccountRepository) DeleteAccount(ctx context.Context, serviceAccountID string) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE service_account_id = $1`, r.tableName)
+
+	result, err := r.db.ExecContext(ctx, query, serviceAccountID)
+	if err != nil {
+		return fmt.Errorf("failed to delete service account: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return apperrors.NotFound(apperrors.CodeServiceAccountNotFound, "service account not found: %s", serviceAccountID)
+	}
+
+	return nil
+}
+
+// CreateToken creates a token of a service account
+func (r *PostgresServiceAccountRepository) CreateToken(ctx context.Context, token *models.ServiceAccountToken) error {
+	query := fmt.Sprintf(`INSERT INTO %s (%s) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`, r.tokensTableName, serviceAccountTokenColumns)
+
+	_, err := r.db.ExecContext(ctx, query,
+		token.TokenID, token.ServiceAccountID, token.Name, token.TokenHash, token.CreatedAt, token.ExpiresAt,
+		token.LastUsedAt, token.RevokedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create service account token: %w", err)
+	}
+
+	return nil
+}
+
+// ListTokens lists the tokens of a service account, newest first
+func (r *PostgresServiceAccountRepository) ListTokens(ctx context.Context, serviceAccountID string) ([]models.ServiceAccountToken, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE service_account_id = $1 ORDER BY created_at DESC`, serviceAccountTokenColumns, r.tokensTableName)
+
+	rows, err := r.db.QueryContext(ctx, query, serviceAccountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service account tokens: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close service account token rows", "error", closeErr)
+		}
+	}()
+
+	tokens := []models.ServiceAccountToken{}
+	for rows.Next() {
+		token, err := scanServiceAccountToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan service account token: %w", err)
+		}
+		tokens = append(tokens, *token)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate service account tokens: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// GetTokenByHash gets the token with a SHA-256 hash
+func (r *PostgresServiceAccountRepository) GetTokenByHash(ctx context.Context, tokenHash string) (*models.ServiceAccountToken, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE token_hash = $1`, serviceAccountTokenColumns, r.tokensTableName)
+
+	token, err := scanServiceAccountToken(r.db.QueryRowContext(ctx, query, tokenHash))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.NotFound(apperrors.CodeServiceTokenNotFound, "service account token not found")
+		}
+		return nil, fmt.Errorf("failed to get service account token: %w", err)
+	}
+
+	return token, nil
+}
+
+// RevokeToken revokes a token of a service account that isn't revoked yet
+func (r *PostgresServiceAccountRepository) RevokeToken(ctx context.Context, serviceAccountID, tokenID string, revokedAt time.Time) error {
+	query := fmt.Sprintf(`
+		UPDATE %s SET revoked_at = $1
+		WHERE token_id = $2 AND service_account_id = $3 AND revoked_at IS NULL
+	`, r.tokensTableName)
+
+	result, err := r.db.ExecContext(ctx, query, revokedAt, tokenID, serviceAccountID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke service account token: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return apperrors.NotFound(apperrors.CodeServiceTokenNotFound, "active service account token not found: %s", tokenID)
+	}
+
+	return nil
+}
+
+// TouchToken records when a token last authenticated a request
+func (r *PostgresServiceAccountRepository) TouchToken(ctx context.Context, tokenID string, usedAt time.Time) error {
+	query := fmt.Sprintf(`UPDATE %s SET last_used_at = $1 WHERE token_id = $2`, r.tokensTableName)
+
+	if _, err := r.db.ExecContext(ctx, query, usedAt, tokenID); err != nil {
+		return fmt.Errorf("failed to touch service account token: %w", err)
+	}
+
+	return nil
+}
+
+// scanServiceAccount scans a single row selected with serviceAccountColumns
+func scanServiceAccount(row rowScanner) (*models.ServiceAccount, error) {
+	var account models.ServiceAccount
+
+	err := row.Scan(
+		&account.ServiceAccountID, &account.Name, &account.Description, &account.UserID, &account.CreatedBy, &account.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+// scanServiceAccountToken scans a single row selected with serviceAccountTokenColumns
+func scanServiceAccountToken(row rowScanner) (*models.ServiceAccountToken, error) {
+	var token models.ServiceAccountToken
+
+	err := row.Scan(
+		&token.TokenID, &token.ServiceAccountID, &token.Name, &token.TokenHash, &token.CreatedAt, &token.ExpiresAt,
+		&token.LastUsedAt, &token.RevokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+func TestPostgresServiceAccountRepository_CreateAccount_NameTaken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresServiceAccountRepositoryWithDB(db, "service_accounts", "service_account_tokens")
+
+	mock.ExpectExec(`INSERT INTO service_accounts`).
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	err = repo.CreateAccount(context.Background(), &models.ServiceAccount{ServiceAccountID: "sa-1", Name: "terraform"})
+
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresServiceAccountRepository_GetTokenByHash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresServiceAccountRepositoryWithDB(db, "service_accounts", "service_account_tokens")
+	createdAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	columns := []string{"token_id", "service_account_id", "name", "token_hash", "created_at", "expires_at", "last_used_at", "revoked_at"}
+	mock.ExpectQuery(`SELECT .+ FROM service_account_tokens WHERE token_hash = \$1`).
+		WithArgs("abc").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("sat-1", "sa-1", "ci", "abc", createdAt, nil, nil, nil))
+
+	token, err := repo.GetTokenByHash(context.Background(), "abc")
+
+	require.NoError(t, err)
+	assert.Equal(t, "sa-1", token.ServiceAccountID)
+	assert.Nil(t, token.RevokedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresServiceAccountRepository_RevokeToken_AlreadyRevoked(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresServiceAccountRepositoryWithDB(db, "service_accounts", "service_account_tokens")
+	revokedAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	mock.ExpectExec(`UPDATE service_account_tokens SET revoked_at = \$1\s+WHERE token_id = \$2 AND service_account_id = \$3 AND revoked_at IS NULL`).
+		WithArgs(revokedAt, "sat-1", "sa-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.RevokeToken(context.Background(), "sa-1", "sat-1", revokedAt)
+
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
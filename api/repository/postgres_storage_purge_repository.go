@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// storagePurgeColumns lists the storage purge columns in the order expected by scanStoragePurge
+const storagePurgeColumns = `purge_id, entity_type, entity_id, region, bucket, prefix, status, objects_deleted, attempts,
+	last_error, created_at, updated_at, completed_at`
+
+// PostgresStoragePurgeRepository implements StoragePurgeRepository using PostgreSQL
+type PostgresStoragePurgeRepository struct {
+	db        *sql.DB
+	tableName string
+}
+
+// NewPostgresStoragePurgeRepository creates a new PostgreSQL storage purge repository
+func NewPostgresStoragePurgeRepository(config PostgresConfig, tableName string) (StoragePurgeRepository, error) {
+	if tableName == "" {
+		tableName = conf.DefaultStoragePurgesTableName
+	}
+
+	// Build connection string
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	repo := &PostgresStoragePurgeRepository{
+		db:        db,
+		tableName: tableName,
+	}
+
+	return repo, nil
+}
+
+// NewPostgresStoragePurgeRepositoryWithDB creates a new PostgreSQL storage purge repository with an existing DB
+// connection
+func NewPostgresStoragePurgeRepositoryWithDB(db *sql.DB, tableName string) StoragePurgeRepository {
+	if tableName == "" {
+		tableName = conf.DefaultStoragePurgesTableName
+	}
+
+	return &PostgresStoragePurgeRepository{
+		db:        db,
+		tableName: tableName,
+	}
+}
+
+// CreatePurge records a pending purge
+func (r *PostgresStoragePurgeRepository) CreatePurge(ctx context.Context, purge *models.StoragePurge) error {
+	query := fmt.Sprintf(`INSERT INTO %s (%s) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NULLIF($10, ''), $11, $12, $13)`,
+		r.tableName, storagePurgeColumns)
+
+	_, err := r.db.ExecContext(ctx, query,
+		purge.PurgeID, purge.EntityType, purge.EntityID, purge.Region, purge.Bucket, purge.Prefix, purge.Status,
+		purge.ObjectsDeleted, purge.Attempts, purge.LastError, purge.CreatedAt, purge.UpdatedAt, purge.CompletedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create storage purge: %w", err)
+	}
+
+	return nil
+}
+
+// GetPurge gets a purge by ID
+func (r *PostgresStoragePurgeRepository) GetPurge(ctx context.Context, purgeID string) (*models.StoragePurge, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE purge_id = $1`, storagePurgeColumns, r.tableName)
+
+	purge, err := scanStoragePurge(r.db.QueryRowContext(ctx, query, purgeID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.NotFound(apperrors.CodeStoragePurgeNotFound, "storage purge not found: %s", purgeID)
+		}
+		return nil, fmt.Errorf("failed to get storage purge: %w", err)
+	}
+
+	return purge, nil
+}
+
+// ListPurges lists up to limit purges, newest first, only those with status unless it's empty
+func (r *PostgresStoragePurgeRepository) ListPurges(ctx context.Context, status models.StoragePurgeStatus, limit int) ([]models.StoragePurge, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE ($1 = '' OR status = $1) ORDER BY created_at DESC LIMIT $2`,
+		storagePurgeColumns, r.tableName)
+
+	return r.queryPurges(ctx, "list", query, status, limit)
+}
+
+// ClaimDue claims the due purges in one statement. Rows locked by another poller's claim are skipped rather than
+// waited for, and the lease keeps the next claims from picking them up while they are purged.
+func (r *PostgresStoragePurgeRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]models.StoragePurge, error) {
+	now := time.Now()
+	query := fmt.Sprintf(`
+		UPDATE %s SET status = $3, attempts = attempts + 1, updated_at = $1, lease_until = $2
+		WHERE purge_id IN (
+			SELECT purge_id FROM %s
+			WHERE status IN ($4, $3) AND (lease_until IS NULL OR lease_until <= $1)
+			ORDER BY created_at
+			LIMIT $5
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING %s
+	`, r.tableName, r.tableName, storagePurgeColumns)
+
+	return r.queryPurges(ctx, "claim", query, now, now.Add(lease), models.StoragePurgeStatusRunning,
+		models.StoragePurgeStatusPending, limit)
+}
+
+// UpdatePurge records the status, progress and error of a purge, and extends its lease until leaseUntil
+func (r *PostgresStoragePurgeRepository) UpdatePurge(ctx context.Context, purge *models.StoragePurge, leaseUntil time.Time) error {
+	query := fmt.Sprintf(`
+		UPDATE %s SET status = $2, objects_deleted = $3, last_error = NULLIF($4, ''), updated_at = $5,
+			completed_at = $6, lease_until = $7
+		WHERE purge_id = $1
+	`, r.tableName)
+
+	_, err := r.db.ExecContext(ctx, query, purge.PurgeID, purge.Status, purge.ObjectsDeleted, purge.LastError,
+		purge.UpdatedAt, purge.CompletedAt, leaseUntil)
+	if err != nil {
+		return fmt.Errorf("failed to update storage purge: %w", err)
+	}
+
+	return nil
+}
+
+// queryPurges runs a query returning storage purge rows, naming the operation in its errors
+func (r *PostgresStoragePurgeRepository) queryPurges(ctx context.Context, operation, query string, args ...any) ([]models.StoragePurge, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to %s storage purges: %w", operation, err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close storage purge rows", "error", closeErr)
+		}
+	}()
+
+	purges := []models.StoragePurge{}
+	for rows.Next() {
+		purge, err := scanStoragePurge(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan storage purge: %w", err)
+		}
+		purges = append(purges, *purge)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate storage purges: %w", err)
+	}
+
+	return purges, nil
+}
+
+// scanStoragePurge scans a single row selected with storagePurgeColumns
+func scanStoragePurge(row rowScanner) (*models.StoragePurge, error) {
+	var purge models.StoragePurge
+	var lastError sql.NullString
+	var completedAt sql.NullTime
+
+	err := row.Scan(
+		&purge.PurgeID, &purge.EntityType, &purge.EntityID, &purge.Region, &purge.Bucket, &purge.Prefix, &purge.Status,
+		&purge.ObjectsDeleted, &purge.Attempts, &lastError, &purge.CreatedAt, &purge.UpdatedAt, &completedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	purge.LastError = lastError.String
+	if completedAt.Valid {
+		purge.CompletedAt = &completedAt.Time
+	}
+
+	return &purge, nil
+}
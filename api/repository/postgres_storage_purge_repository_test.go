@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+var storagePurgeTestColumns = []string{"purge_id", "entity_type", "entity_id", "region", "bucket", "prefix", "status",
+	"objects_deleted", "attempts", "last_error", "created_at", "updated_at", "completed_at"}
+
+func TestPostgresStoragePurgeRepository_ClaimDue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresStoragePurgeRepositoryWithDB(db, "storage_purges")
+	createdAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`UPDATE storage_purges SET status = \$3, attempts = attempts \+ 1, updated_at = \$1, lease_until = \$2 WHERE purge_id IN \(\s*SELECT purge_id FROM storage_purges WHERE status IN \(\$4, \$3\) AND \(lease_until IS NULL OR lease_until <= \$1\) ORDER BY created_at LIMIT \$5 FOR UPDATE SKIP LOCKED\s*\) RETURNING`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), models.StoragePurgeStatusRunning, models.StoragePurgeStatusPending, 10).
+		WillReturnRows(sqlmock.NewRows(storagePurgeTestColumns).
+			AddRow("purge-1", "agent", "agent-1", "us-east-1", "content", "knowledge-bases/KB1/", "running", int64(0), 1,
+				nil, createdAt, createdAt, nil))
+
+	purges, err := repo.ClaimDue(context.Background(), 10, time.Minute)
+
+	require.NoError(t, err)
+	require.Len(t, purges, 1)
+	assert.Equal(t, "knowledge-bases/KB1/", purges[0].Prefix)
+	assert.Equal(t, models.StoragePurgeStatusRunning, purges[0].Status)
+	assert.Empty(t, purges[0].LastError)
+	assert.Nil(t, purges[0].CompletedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresStoragePurgeRepository_UpdatePurge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresStoragePurgeRepositoryWithDB(db, "storage_purges")
+	now := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+	purge := &models.StoragePurge{PurgeID: "purge-1", Status: models.StoragePurgeStatusCompleted, ObjectsDeleted: 1200,
+		UpdatedAt: now, CompletedAt: &now}
+
+	mock.ExpectExec(`UPDATE storage_purges SET status = \$2, objects_deleted = \$3, last_error = NULLIF\(\$4, ''\), updated_at = \$5,\s+completed_at = \$6, lease_until = \$7\s+WHERE purge_id = \$1`).
+		WithArgs("purge-1", models.StoragePurgeStatusCompleted, int64(1200), "", now, &now, now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.UpdatePurge(context.Background(), purge, now))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresStoragePurgeRepository_GetPurge_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresStoragePurgeRepositoryWithDB(db, "storage_purges")
+
+	mock.ExpectQuery(`SELECT .+ FROM storage_purges WHERE purge_id = \$1`).
+		WithArgs("purge-missing").
+		WillReturnRows(sqlmock.NewRows(storagePurgeTestColumns))
+
+	_, err = repo.GetPurge(context.Background(), "purge-missing")
+
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	assert.Equal(t, apperrors.CodeStoragePurgeNotFound, apperrors.CodeOf(err))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
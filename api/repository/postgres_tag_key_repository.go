@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/lib/pq"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// tagKeyColumns are the columns scanned by scanTagKey, in order
+const tagKeyColumns = `key, description, allowed_values, value_pattern, editor_roles, created_at, updated_at`
+
+// PostgresTagKeyRepository implements TagKeyRepository using PostgreSQL
+type PostgresTagKeyRepository struct {
+	db        *sql.DB
+	tableName string
+}
+
+// NewPostgresTagKeyRepository creates a new PostgreSQL tag key repository
+func NewPostgresTagKeyRepository(config PostgresConfig, tableName string) (TagKeyRepository, error) {
+	if tableName == "" {
+		tableName = conf.DefaultTagKeysTableName
+	}
+
+	// Build connection string
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	repo := &PostgresTagKeyRepository{
+		db:        db,
+		tableName: tableName,
+	}
+
+	return repo, nil
+}
+
+// NewPostgresTagKeyRepositoryWithDB creates a new PostgreSQL tag key repository with an existing DB connection
+func NewPostgresTagKeyRepositoryWithDB(db *sql.DB, tableName string) TagKeyRepository {
+	if tableName == "" {
+		tableName = conf.DefaultTagKeysTableName
+	}
+
+	return &PostgresTagKeyRepository{
+		db:        db,
+		tableName: tableName,
+	}
+}
+
+// CreateTagKey registers a new tag key
+func (r *PostgresTagKeyRepository) CreateTagKey(ctx context.Context, tagKey *models.TagKey) error {
+	allowedValues, editorRoles, err := marshalTagKeyRules(tagKey)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (%s)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, r.tableName, tagKeyColumns)
+
+	_, err = r.db.ExecContext(ctx, query,
+		tagKey.Key, tagKey.Description, allowedValues, tagKey.ValuePattern, editorRoles, tagKey.CreatedAt, tagKey.UpdatedAt,
+	)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" { // unique_violation
+			return apperrors.Conflict(apperrors.CodeTagKeyExists, "tag key %s is already registered", tagKey.Key)
+		}
+		return fmt.Errorf("failed to create tag key: %w", err)
+	}
+
+	return nil
+}
+
+// GetTagKey retrieves a registered tag key
+func (r *PostgresTagKeyRepository) GetTagKey(ctx context.Context, key string) (*models.TagKey, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE key = $1`, tagKeyColumns, r.tableName)
+
+	tagKey, err := scanTagKey(r.db.QueryRowContext(ctx, query, key))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get tag key: %w", err)
+	}
+
+	return tagKey, nil
+}
+
+// UpdateTagKey replaces the rules of a registered tag key
+func (r *PostgresTagKeyRepository) UpdateTagKey(ctx context.Context, tagKey *models.TagKey) error {
+	allowedValues, editorRoles, err := marshalTagKeyRules(tagKey)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s
+		SET description = $2, allowed_values = $3, value_pattern = $4, editor_roles = $5, updated_at = $6
+		WHERE key = $1
+	`, r.tableName)
+
+	result, err := r.db.ExecContext(ctx, query,
+		tagKey.Key, tagKey.Description, allowedValues, tagKey.ValuePattern, editorRoles, tagKey.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update tag key: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return apperrors.NotFound(apperrors.CodeTagKeyNotFound, "tag key not found: %s", tagKey.Key)
+	}
+
+	return nil
+}
+
+// DeleteTagKey unregisters a tag key
+func (r *PostgresTagKeyRepository) DeleteTagKey(ctx context.Context, key string) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE key = $1`, r.tableName)
+
+	result, err := r.db.ExecContext(ctx, query, key)
+	if err != nil {
+		return fmt.Errorf("failed to delete tag key: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return apperrors.NotFound(apperrors.CodeTagKeyNotFound, "tag key not found: %s", key)
+	}
+
+	return nil
+}
+
+// ListTagKeys lists the registered tag keys ordered by key
+func (r *PostgresTagKeyRepository) ListTagKeys(ctx context.Context) ([]models.TagKey, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s ORDER BY key`, tagKeyColumns, r.tableName)
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tag keys: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close rows in ListTagKeys", "error", closeErr)
+		}
+	}()
+
+	tagKeys := []models.TagKey{}
+	for rows.Next() {
+		tagKey, err := scanTagKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tag key: %w", err)
+		}
+		tagKeys = append(tagKeys, *tagKey)
+	}
+
+	return tagKeys, rows.Err()
+}
+
+// marshalTagKeyRules encodes the allowed values and editor roles of a tag key as JSON arrays
+func marshalTagKeyRules(tagKey *models.TagKey) ([]byte, []byte, error) {
+	allowedValues, err := json.Marshal(append([]string{}, tagKey.AllowedValues...))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal allowed values: %w", err)
+	}
+
+	editorRoles, err := json.Marshal(append([]models.UserRole{}, tagKey.EditorRoles...))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal editor roles: %w", err)
+	}
+
+	return allowedValues, editorRoles, nil
+}
+
+// scanTagKey scans a row selected with tagKeyColumns
+func scanTagKey(row rowScanner) (*models.TagKey, error) {
+	var tagKey models.TagKey
+	var allowedValues, editorRoles []byte
+	if err := row.Scan(
+		&tagKey.Key, &tagKey.Description, &allowedValues, &tagKey.ValuePattern, &editorRoles, &tagKey.CreatedAt, &tagKey.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(allowedValues, &tagKey.AllowedValues); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal allowed values of tag key %s: %w", tagKey.Key, err)
+	}
+	if err := json.Unmarshal(editorRoles, &tagKey.EditorRoles); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal editor roles of tag key %s: %w", tagKey.Key, err)
+	}
+
+	return &tagKey, nil
+}
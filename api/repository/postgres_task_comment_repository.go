@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// taskCommentColumns lists the task comment columns in the order expected by scanTaskComment
+const taskCommentColumns = `comment_id, task_id, parent_id, author_id, body, anchor, created_at, updated_at`
+
+// PostgresTaskCommentRepository implements TaskCommentRepository using PostgreSQL
+type PostgresTaskCommentRepository struct {
+	db        *sql.DB
+	tableName string
+}
+
+// NewPostgresTaskCommentRepository creates a new PostgreSQL task comment repository
+func NewPostgresTaskCommentRepository(config PostgresConfig, tableName string) (TaskCommentRepository, error) {
+	if tableName == "" {
+		tableName = conf.DefaultTaskCommentsTableName
+	}
+
+	// Build connection string
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	repo := &PostgresTaskCommentRepository{
+		db:        db,
+		tableName: tableName,
+	}
+
+	return repo, nil
+}
+
+// NewPostgresTaskCommentRepositoryWithDB creates a new PostgreSQL task comment repository with an existing DB connection
+func NewPostgresTaskCommentRepositoryWithDB(db *sql.DB, tableName string) TaskCommentRepository {
+	if tableName == "" {
+		tableName = conf.DefaultTaskCommentsTableName
+	}
+
+	return &PostgresTaskCommentRepository{
+		db:        db,
+		tableName: tableName,
+	}
+}
+
+// CreateComment stores a new comment
+func (r *PostgresTaskCommentRepository) CreateComment(ctx context.Context, comment *models.TaskComment) error {
+	anchorJSON, err := marshalTaskCommentAnchor(comment.Anchor)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (%s)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, r.tableName, taskCommentColumns)
+
+	_, err = r.db.ExecContext(ctx, query,
+		comment.CommentID, comment.TaskID, comment.ParentID, comment.AuthorID, comment.Body, anchorJSON,
+		comment.CreatedAt, comment.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create task comment: %w", err)
+	}
+
+	return nil
+}
+
+// GetComment retrieves a comment by ID
+func (r *PostgresTaskCommentRepository) GetComment(ctx context.Context, commentID string) (*models.TaskComment, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE comment_id = $1`, taskCommentColumns, r.tableName)
+
+	comment, err := scanTaskComment(r.db.QueryRowContext(ctx, query, commentID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.NotFound(apperrors.CodeCommentNotFound, "task comment not found: %s", commentID)
+		}
+		return nil, fmt.Errorf("failed to get task comment: %w", err)
+	}
+
+	return comment, nil
+}
+
+// UpdateComment replaces the body of an existing comment
+func (r *PostgresTaskCommentRepository) UpdateComment(ctx context.Context, comment *models.TaskComment) error {
+	query := fmt.Sprintf(`UPDATE %s SET body = $2, updated_at = $3 WHERE comment_id = $1`, r.tableName)
+
+	result, err := r.db.ExecContext(ctx, query, comment.CommentID, comment.Body, comment.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update task comment: %w", err)
+	}
+
+	return checkTaskCommentAffected(result, comment.CommentID)
+}
+
+// DeleteComment deletes a comment; replies are deleted by the parent_id foreign key
+func (r *PostgresTaskCommentRepository) DeleteComment(ctx context.Context, commentID string) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE comment_id = $1`, r.tableName)
+
+	result, err := r.db.ExecContext(ctx, query, commentID)
+	if err != nil {
+		return fmt.Errorf("failed to delete task comment: %w", err)
+	}
+
+	return checkTaskCommentAffected(result, commentID)
+}
+
+// ListComments lists a task's comments oldest first
+func (r *PostgresTaskCommentRepository) ListComments(ctx context.Context, taskID string) ([]models.TaskComment, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE task_id = $1 ORDER BY created_at, comment_id`, taskCommentColumns, r.tableName)
+
+	rows, err := r.db.QueryContext(ctx, query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list task comments: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close task comment rows", "error", closeErr)
+		}
+	}()
+
+	comments := []models.TaskComment{}
+	for rows.Next() {
+		comment, err := scanTaskComment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan task comment: %w", err)
+		}
+		comments = append(comments, *comment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate task comments: %w", err)
+	}
+
+	return comments, nil
+}
+
+// checkTaskCommentAffected reports a comment as not found when a statement affected no rows
+func checkTaskCommentAffected(result sql.Result, commentID string) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return apperrors.NotFound(apperrors.CodeCommentNotFound, "task comment not found: %s", commentID)
+	}
+
+	return nil
+}
+
+// marshalTaskCommentAnchor encodes an anchor as JSON, or nil when the comment has none
+func marshalTaskCommentAnchor(anchor *models.TaskCommentAnchor) ([]byte, error) {
+	if anchor == nil {
+		return nil, nil
+	}
+
+	anchorJSON, err := json.Marshal(anchor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal comment anchor: %w", err)
+	}
+
+	return anchorJSON, nil
+}
+
+// scanTaskComment scans a single row selected with taskCommentColumns
+func scanTaskComment(row rowScanner) (*models.TaskComment, error) {
+	var comment models.TaskComment
+	var anchorJSON []byte
+
+	err := row.Scan(
+		&comment.CommentID, &comment.TaskID, &comment.ParentID, &comment.AuthorID, &comment.Body, &anchorJSON,
+		&comment.CreatedAt, &comment.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(anchorJSON) > 0 {
+		if err := json.Unmarshal(anchorJSON, &comment.Anchor); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal anchor JSON for comment %s: %w", comment.CommentID, err)
+		}
+	}
+
+	return &comment, nil
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+func TestPostgresTaskCommentRepository_ListComments(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresTaskCommentRepositoryWithDB(db, "task_comments")
+	createdAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	columns := []string{"comment_id", "task_id", "parent_id", "author_id", "body", "anchor", "created_at", "updated_at"}
+	mock.ExpectQuery(`SELECT .+ FROM task_comments WHERE task_id = \$1 ORDER BY created_at, comment_id`).
+		WithArgs("task-1").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("comment-1", "task-1", nil, "user-1", "Why?", []byte(`{"file_path":"main.go","start_line":12,"end_line":14}`), createdAt, createdAt).
+			AddRow("comment-2", "task-1", "comment-1", "user-2", "Because", nil, createdAt, createdAt))
+
+	comments, err := repo.ListComments(context.Background(), "task-1")
+
+	require.NoError(t, err)
+	require.Len(t, comments, 2)
+	assert.Equal(t, &models.TaskCommentAnchor{FilePath: "main.go", StartLine: 12, EndLine: 14}, comments[0].Anchor)
+	assert.Nil(t, comments[1].Anchor)
+	assert.Equal(t, "comment-1", *comments[1].ParentID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresTaskCommentRepository_DeleteComment_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresTaskCommentRepositoryWithDB(db, "task_comments")
+
+	mock.ExpectExec(`DELETE FROM task_comments WHERE comment_id = \$1`).
+		WithArgs("comment-missing").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.DeleteComment(context.Background(), "comment-missing")
+
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
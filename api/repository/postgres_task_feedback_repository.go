@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// taskFeedbackColumns lists the task feedback columns in the order expected by scanTaskFeedback
+const taskFeedbackColumns = `task_id, user_id, rating, comment, outcome, created_at, updated_at`
+
+// PostgresTaskFeedbackRepository implements TaskFeedbackRepository using PostgreSQL
+type PostgresTaskFeedbackRepository struct {
+	db        *sql.DB
+	tableName string
+}
+
+// NewPostgresTaskFeedbackRepository creates a new PostgreSQL task feedback repository
+func NewPostgresTaskFeedbackRepository(config PostgresConfig, tableName string) (TaskFeedbackRepository, error) {
+	if tableName == "" {
+		tableName = conf.DefaultTaskFeedbackTableName
+	}
+
+	// Build connection string
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	repo := &PostgresTaskFeedbackRepository{
+		db:        db,
+		tableName: tableName,
+	}
+
+	return repo, nil
+}
+
+// NewPostgresTaskFeedbackRepositoryWithDB creates a new PostgreSQL task feedback repository with an existing DB connection
+func NewPostgresTaskFeedbackRepositoryWithDB(db *sql.DB, tableName string) TaskFeedbackRepository {
+	if tableName == "" {
+		tableName = conf.DefaultTaskFeedbackTableName
+	}
+
+	return &PostgresTaskFeedbackRepository{
+		db:        db,
+		tableName: tableName,
+	}
+}
+
+// UpsertFeedback stores a user's feedback on a task, replacing the feedback they gave it before
+func (r *PostgresTaskFeedbackRepository) UpsertFeedback(ctx context.Context, feedback *models.TaskFeedback) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (%s)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (task_id, user_id) DO UPDATE SET
+			rating = EXCLUDED.rating,
+			comment = EXCLUDED.comment,
+			outcome = EXCLUDED.outcome,
+			updated_at = EXCLUDED.updated_at
+		RETURNING created_at
+	`, r.tableName, taskFeedbackColumns)
+
+	err := r.db.QueryRowContext(ctx, query,
+		feedback.TaskID, feedback.UserID, feedback.Rating, feedback.Comment, feedback.Outcome,
+		feedback.CreatedAt, feedback.UpdatedAt,
+	).Scan(&feedback.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to upsert task feedback: %w", err)
+	}
+
+	return nil
+}
+
+// ListFeedback lists the feedback on a task oldest first
+func (r *PostgresTaskFeedbackRepository) ListFeedback(ctx context.Context, taskID string) ([]models.TaskFeedback, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE task_id = $1 ORDER BY created_at, user_id`, taskFeedbackColumns, r.tableName)
+
+	rows, err := r.db.QueryContext(ctx, query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list task feedback: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close task feedback rows", "error", closeErr)
+		}
+	}()
+
+	feedback := []models.TaskFeedback{}
+	for rows.Next() {
+		item, err := scanTaskFeedback(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan task feedback: %w", err)
+		}
+		feedback = append(feedback, *item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate task feedback: %w", err)
+	}
+
+	return feedback, nil
+}
+
+// scanTaskFeedback scans a single row selected with taskFeedbackColumns
+func scanTaskFeedback(row rowScanner) (*models.TaskFeedback, error) {
+	var feedback models.TaskFeedback
+
+	err := row.Scan(
+		&feedback.TaskID, &feedback.UserID, &feedback.Rating, &feedback.Comment, &feedback.Outcome,
+		&feedback.CreatedAt, &feedback.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &feedback, nil
+}
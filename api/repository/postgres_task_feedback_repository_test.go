@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+func TestPostgresTaskFeedbackRepository_UpsertFeedback_KeepsCreationTime(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresTaskFeedbackRepositoryWithDB(db, "task_feedback")
+	createdAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+	now := createdAt.Add(time.Hour)
+	feedback := &models.TaskFeedback{
+		TaskID: "task-1", UserID: "user-1", Rating: 4, Outcome: models.TaskFeedbackOutcomeAccepted, CreatedAt: now, UpdatedAt: now,
+	}
+
+	mock.ExpectQuery(`INSERT INTO task_feedback .+ ON CONFLICT \(task_id, user_id\) DO UPDATE SET .+ RETURNING created_at`).
+		WithArgs("task-1", "user-1", 4, "", models.TaskFeedbackOutcomeAccepted, now, now).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(createdAt))
+
+	require.NoError(t, repo.UpsertFeedback(context.Background(), feedback))
+
+	assert.Equal(t, createdAt, feedback.CreatedAt)
+	assert.Equal(t, now, feedback.UpdatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresTaskFeedbackRepository_ListFeedback(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresTaskFeedbackRepositoryWithDB(db, "task_feedback")
+	createdAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	columns := []string{"task_id", "user_id", "rating", "comment", "outcome", "created_at", "updated_at"}
+	mock.ExpectQuery(`SELECT .+ FROM task_feedback WHERE task_id = \$1 ORDER BY created_at, user_id`).
+		WithArgs("task-1").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("task-1", "user-1", 5, "", "accepted", createdAt, createdAt).
+			AddRow("task-1", "user-2", 1, "Broke the build", "rejected", createdAt, createdAt))
+
+	feedback, err := repo.ListFeedback(context.Background(), "task-1")
+
+	require.NoError(t, err)
+	require.Len(t, feedback, 2)
+	assert.Equal(t, models.TaskFeedbackOutcomeRejected, feedback[1].Outcome)
+	assert.Equal(t, "Broke the build", feedback[1].Comment)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
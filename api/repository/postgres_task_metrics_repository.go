@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// PostgresTaskMetricsRepository implements TaskMetricsRepository with a daily rollup table aggregated from the tasks table
+type PostgresTaskMetricsRepository struct {
+	db                *sql.DB
+	reads             *readRouter
+	tableName         string
+	tasksTableName    string
+	feedbackTableName string
+}
+
+// NewPostgresTaskMetricsRepository creates a new PostgreSQL task metrics repository
+func NewPostgresTaskMetricsRepository(config PostgresConfig, tableName, tasksTableName, feedbackTableName string) (TaskMetricsRepository, error) {
+	if tableName == "" {
+		tableName = conf.DefaultTaskMetricsTableName
+	}
+	if tasksTableName == "" {
+		tasksTableName = conf.DefaultTasksTableName
+	}
+	if feedbackTableName == "" {
+		feedbackTableName = conf.DefaultTaskFeedbackTableName
+	}
+
+	// Build connection string
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	replica, err := openReadReplica(config)
+	if err != nil {
+		return nil, err
+	}
+
+	repo := &PostgresTaskMetricsRepository{
+		db:                db,
+		reads:             newReadRouter(db, replica, config.ReplicaRetryInterval),
+		tableName:         tableName,
+		tasksTableName:    tasksTableName,
+		feedbackTableName: feedbackTableName,
+	}
+
+	return repo, nil
+}
+
+// NewPostgresTaskMetricsRepositoryWithDB creates a new PostgreSQL task metrics repository with an existing DB connection
+func NewPostgresTaskMetricsRepositoryWithDB(db *sql.DB, tableName, tasksTableName, feedbackTableName string) TaskMetricsRepository {
+	if tableName == "" {
+		tableName = conf.DefaultTaskMetricsTableName
+	}
+	if tasksTableName == "" {
+		tasksTableName = conf.DefaultTasksTableName
+	}
+	if feedbackTableName == "" {
+		feedbackTableName = conf.DefaultTaskFeedbackTableName
+	}
+
+	return &PostgresTaskMetricsRepository{
+		db:                db,
+		reads:             newReadRouter(db, nil, 0),
+		tableName:         tableName,
+		tasksTableName:    tasksTableName,
+		feedbackTableName: feedbackTableName,
+	}
+}
+
+// RefreshRollups replaces the rollups of every UTC day from since onwards in a single transaction,
+// so reports never observe a partially refreshed day
+func (r *PostgresTaskMetricsRepository) RefreshRollups(ctx context.Context, since time.Time) (err error) {
+	day := since.UTC().Truncate(24 * time.Hour)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				slog.WarnContext(ctx, "failed to rollback task metrics refresh", "error", rollbackErr)
+			}
+		}
+	}()
+
+	deleteQuery := fmt.Sprintf(`DELETE FROM %s WHERE day >= $1`, r.tableName)
+	if _, err = tx.ExecContext(ctx, deleteQuery, day.Format(models.ReportDateLayout)); err != nil {
+		return fmt.Errorf("failed to delete stale rollups: %w", err)
+	}
+
+	// Durations are measured from creation to completion because tasks don't record a start time. Feedback counts
+	// towards the day its task was created.
+	insertQuery := fmt.Sprintf(`
+		WITH scoped AS (
+			SELECT task_id, project_id, (created_at AT TIME ZONE 'UTC')::date AS day, status, created_at, completed_at, output
+			FROM %[2]s
+			WHERE created_at >= $1
+		), failures AS (
+			SELECT project_id, day, jsonb_object_agg(category, failed) AS categories
+			FROM (
+				SELECT project_id, day, COALESCE(NULLIF(output->>'%[3]s', ''), '%[4]s') AS category, COUNT(*) AS failed
+				FROM scoped
+				WHERE status = 'failed'
+				GROUP BY project_id, day, category
+			) per_category
+			GROUP BY project_id, day
+		), feedback AS (
+			SELECT s.project_id, s.day,
+				COUNT(*) AS feedback,
+				COUNT(*) FILTER (WHERE fb.outcome = '%[7]s') AS accepted,
+				COUNT(*) FILTER (WHERE fb.outcome = '%[8]s') AS rejected,
+				SUM(fb.rating) AS rating_total
+			FROM scoped s
+			JOIN %[6]s fb ON fb.task_id = s.task_id
+			GROUP BY s.project_id, s.day
+		)
+		INSERT INTO %[1]s (
+			project_id, day, total, completed, failed, cancelled,
+			duration_ms_total, duration_count, token_usage, failure_categories,
+			feedback, accepted, rejected, rating_total, refreshed_at
+		)
+		SELECT
+			s.project_id,
+			s.day,
+			COUNT(*),
+			COUNT(*) FILTER (WHERE s.status = 'completed'),
+			COUNT(*) FILTER (WHERE s.status = 'failed'),
+			COUNT(*) FILTER (WHERE s.status = 'cancelled'),
+			COALESCE(SUM(EXTRACT(EPOCH FROM s.completed_at - s.created_at) * 1000), 0)::BIGINT,
+			COUNT(s.completed_at),
+			COALESCE(SUM(CASE WHEN s.output->>'%[5]s' ~ '^[0-9]+$' THEN (s.output->>'%[5]s')::BIGINT END), 0),
+			COALESCE(f.categories, '{}'::jsonb),
+			COALESCE(fb.feedback, 0),
+			COALESCE(fb.accepted, 0),
+			COALESCE(fb.rejected, 0),
+			COALESCE(fb.rating_total, 0),
+			NOW()
+		FROM scoped s
+		LEFT JOIN failures f ON f.project_id = s.project_id AND f.day = s.day
+		LEFT JOIN feedback fb ON fb.project_id = s.project_id AND fb.day = s.day
+		GROUP BY s.project_id, s.day, f.categories, fb.feedback, fb.accepted, fb.rejected, fb.rating_total
+	`, r.tableName, r.tasksTableName, models.TaskOutputFailureCategoryKey, models.FailureCategoryUncategorized, models.TaskOutputTokenUsageKey,
+		r.feedbackTableName, models.TaskFeedbackOutcomeAccepted, models.TaskFeedbackOutcomeRejected)
+
+	if _, err = tx.ExecContext(ctx, insertQuery, day); err != nil {
+		return fmt.Errorf("failed to aggregate task metrics: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit task metrics refresh: %w", err)
+	}
+
+	return nil
+}
+
+// ListRollups lists the daily rollups between from and to, inclusive, ordered by day and project. Operations hinted
+// read-only are served by the read replica.
+func (r *PostgresTaskMetricsRepository) ListRollups(ctx context.Context, projectID string, from, to time.Time) ([]TaskMetricsRollup, error) {
+	query := fmt.Sprintf(`
+		SELECT project_id, day, total, completed, failed, cancelled,
+			duration_ms_total, duration_count, token_usage, failure_categories,
+			feedback, accepted, rejected, rating_total, refreshed_at
+		FROM %s
+		WHERE day >= $1 AND day <= $2
+	`, r.tableName)
+	args := []any{from.Format(models.ReportDateLayout), to.Format(models.ReportDateLayout)}
+
+	if projectID != "" {
+		query += " AND project_id = $3"
+		args = append(args, projectID)
+	}
+	query += " ORDER BY day, project_id"
+
+	rows, err := r.reads.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close rows in ListRollups", "error", closeErr)
+		}
+	}()
+
+	var rollups []TaskMetricsRollup
+	for rows.Next() {
+		var rollup TaskMetricsRollup
+		var categoriesJSON []byte
+		if err := rows.Scan(
+			&rollup.ProjectID, &rollup.Day, &rollup.Total, &rollup.Completed, &rollup.Failed, &rollup.Cancelled,
+			&rollup.DurationMsTotal, &rollup.DurationCount, &rollup.TokenUsage, &categoriesJSON,
+			&rollup.Feedback, &rollup.Accepted, &rollup.Rejected, &rollup.RatingTotal, &rollup.RefreshedAt,
+		); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(categoriesJSON, &rollup.FailureCategories); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal failure categories: %w", err)
+		}
+		rollups = append(rollups, rollup)
+	}
+
+	return rollups, rows.Err()
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresTaskMetricsRepository_RefreshRollups(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresTaskMetricsRepositoryWithDB(db, "task_metrics_daily", "tasks", "task_feedback")
+	since := time.Date(2024, time.January, 14, 10, 30, 0, 0, time.UTC)
+	day := time.Date(2024, time.January, 14, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM task_metrics_daily WHERE day >= \$1`).
+		WithArgs("2024-01-14").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(`INSERT INTO task_metrics_daily .* FROM scoped s LEFT JOIN failures f .* LEFT JOIN feedback fb`).
+		WithArgs(day).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	require.NoError(t, repo.RefreshRollups(context.Background(), since))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresTaskMetricsRepository_RefreshRollups_RollsBack(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresTaskMetricsRepositoryWithDB(db, "task_metrics_daily", "tasks", "task_feedback")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM task_metrics_daily`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO task_metrics_daily`).WillReturnError(errors.New("statement timeout"))
+	mock.ExpectRollback()
+
+	err = repo.RefreshRollups(context.Background(), time.Now())
+
+	assert.ErrorContains(t, err, "statement timeout")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresTaskMetricsRepository_ListRollups(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresTaskMetricsRepositoryWithDB(db, "task_metrics_daily", "tasks", "task_feedback")
+	from := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC)
+	refreshedAt := time.Date(2024, time.January, 31, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT project_id, day, .* FROM task_metrics_daily WHERE day >= \$1 AND day <= \$2 AND project_id = \$3 ORDER BY day, project_id`).
+		WithArgs("2024-01-01", "2024-01-31", "proj-1").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"project_id", "day", "total", "completed", "failed", "cancelled",
+			"duration_ms_total", "duration_count", "token_usage", "failure_categories",
+			"feedback", "accepted", "rejected", "rating_total", "refreshed_at",
+		}).AddRow("proj-1", from, 4, 2, 1, 1, int64(90000), 3, int64(1200), []byte(`{"timeout":1}`), 2, 1, 1, int64(7), refreshedAt))
+
+	rollups, err := repo.ListRollups(context.Background(), "proj-1", from, to)
+
+	require.NoError(t, err)
+	assert.Equal(t, []TaskMetricsRollup{{
+		ProjectID:         "proj-1",
+		Day:               from,
+		Total:             4,
+		Completed:         2,
+		Failed:            1,
+		Cancelled:         1,
+		DurationMsTotal:   90000,
+		DurationCount:     3,
+		TokenUsage:        1200,
+		FailureCategories: map[string]int{"timeout": 1},
+		Feedback:          2,
+		Accepted:          1,
+		Rejected:          1,
+		RatingTotal:       7,
+		RefreshedAt:       refreshedAt,
+	}}, rollups)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
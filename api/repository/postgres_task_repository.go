@@ -4,28 +4,35 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"time"
 
 	"github.com/google/uuid"
-	_ "github.com/lib/pq" // PostgreSQL driver
+	"github.com/lib/pq"
 
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
 	"github.com/kazemisoroush/code-refactoring-tool/api/models"
 	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
 )
 
 // PostgresTaskRepository implements TaskRepository using PostgreSQL
 type PostgresTaskRepository struct {
-	db        *sql.DB
-	tableName string
+	db              *sql.DB
+	reads           *readRouter
+	tableName       string
+	outboxTableName string
 }
 
 // NewPostgresTaskRepository creates a new PostgreSQL task repository
-func NewPostgresTaskRepository(config PostgresConfig, tableName string) (TaskRepository, error) {
+func NewPostgresTaskRepository(config PostgresConfig, tableName, outboxTableName string) (TaskRepository, error) {
 	if tableName == "" {
 		tableName = conf.DefaultTasksTableName
 	}
+	if outboxTableName == "" {
+		outboxTableName = conf.DefaultNotificationOutboxTableName
+	}
 
 	// Build connection string
 	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
@@ -40,63 +47,96 @@ func NewPostgresTaskRepository(config PostgresConfig, tableName string) (TaskRep
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	repo := &PostgresTaskRepository{
-		db:        db,
-		tableName: tableName,
+	replica, err := openReadReplica(config)
+	if err != nil {
+		return nil, err
 	}
 
-	// Create table if it doesn't exist
-	if err := repo.createTableIfNotExists(); err != nil {
-		return nil, fmt.Errorf("failed to create table: %w", err)
+	repo := &PostgresTaskRepository{
+		db:              db,
+		reads:           newReadRouter(db, replica, config.ReplicaRetryInterval),
+		tableName:       tableName,
+		outboxTableName: outboxTableName,
 	}
 
 	return repo, nil
 }
 
-// createTableIfNotExists creates the tasks table if it doesn't exist
-func (r *PostgresTaskRepository) createTableIfNotExists() error {
-	query := fmt.Sprintf(`
-		CREATE TABLE IF NOT EXISTS %s (
-			task_id VARCHAR(255) PRIMARY KEY,
-			project_id VARCHAR(255) NOT NULL,
-			agent_id VARCHAR(255) NOT NULL,
-			codebase_id VARCHAR(255),
-			type VARCHAR(50) NOT NULL,
-			status VARCHAR(50) NOT NULL DEFAULT 'pending',
-			title VARCHAR(500) NOT NULL,
-			description TEXT NOT NULL,
-			input JSONB,
-			output JSONB,
-			error_message TEXT,
-			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
-			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
-			completed_at TIMESTAMP WITH TIME ZONE,
-			metadata JSONB,
-			tags JSONB,
-			
-			-- Indexes for performance
-			CONSTRAINT tasks_type_check CHECK (type IN ('code_analysis', 'refactoring', 'code_review', 'documentation', 'custom')),
-			CONSTRAINT tasks_status_check CHECK (status IN ('pending', 'in_progress', 'completed', 'failed', 'cancelled'))
-		);
-		
-		-- Create indexes
-		CREATE INDEX IF NOT EXISTS idx_%s_project_id ON %s (project_id);
-		CREATE INDEX IF NOT EXISTS idx_%s_agent_id ON %s (agent_id);
-		CREATE INDEX IF NOT EXISTS idx_%s_codebase_id ON %s (codebase_id);
-		CREATE INDEX IF NOT EXISTS idx_%s_status ON %s (status);
-		CREATE INDEX IF NOT EXISTS idx_%s_type ON %s (type);
-		CREATE INDEX IF NOT EXISTS idx_%s_created_at ON %s (created_at);
-		CREATE INDEX IF NOT EXISTS idx_%s_project_status ON %s (project_id, status);
-	`, r.tableName, r.tableName, r.tableName, r.tableName, r.tableName, r.tableName,
-		r.tableName, r.tableName, r.tableName, r.tableName, r.tableName, r.tableName,
-		r.tableName, r.tableName, r.tableName)
-
-	_, err := r.db.Exec(query)
-	return err
+// NewPostgresTaskRepositoryWithDB creates a new PostgreSQL task repository with an existing DB connection
+func NewPostgresTaskRepositoryWithDB(db *sql.DB, tableName, outboxTableName string) TaskRepository {
+	if tableName == "" {
+		tableName = conf.DefaultTasksTableName
+	}
+	if outboxTableName == "" {
+		outboxTableName = conf.DefaultNotificationOutboxTableName
+	}
+
+	return &PostgresTaskRepository{
+		db:              db,
+		reads:           newReadRouter(db, nil, 0),
+		tableName:       tableName,
+		outboxTableName: outboxTableName,
+	}
 }
 
-// Create creates a new task
-func (r *PostgresTaskRepository) Create(ctx context.Context, task *models.Task) error {
+// taskColumns lists the task columns in the order expected by scanTask
+const taskColumns = `task_id, project_id, batch_id, campaign_id, created_by, agent_id, codebase_id, branch, commit_sha, type, analysis_mode, status,
+			   title, description, input, output, error_message,
+			   created_at, updated_at, completed_at, heartbeat_at, progress, progress_step, metadata, tags,
+			   experiment_id, experiment_arm, approved, priority, queued_at`
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanTask scans a single task row selected with taskColumns
+func scanTask(row rowScanner) (*models.Task, error) {
+	var task models.Task
+	var inputJSON, outputJSON, metadataJSON, tagsJSON []byte
+
+	err := row.Scan(
+		&task.TaskID, &task.ProjectID, &task.BatchID, &task.CampaignID, &task.CreatedBy, &task.AgentID, &task.CodebaseID, &task.Branch, &task.CommitSHA, &task.Type, &task.AnalysisMode, &task.Status,
+		&task.Title, &task.Description, &inputJSON, &outputJSON, &task.ErrorMessage,
+		&task.CreatedAt, &task.UpdatedAt, &task.CompletedAt, &task.HeartbeatAt, &task.Progress, &task.ProgressStep, &metadataJSON, &tagsJSON,
+		&task.ExperimentID, &task.ExperimentArm, &task.Approved, &task.Priority, &task.QueuedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse JSON fields
+	if len(inputJSON) > 0 {
+		if err := json.Unmarshal(inputJSON, &task.Input); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal input JSON for task %s: %w", task.TaskID, err)
+		}
+	}
+	if len(outputJSON) > 0 {
+		if err := json.Unmarshal(outputJSON, &task.Output); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal output JSON for task %s: %w", task.TaskID, err)
+		}
+	}
+	if len(metadataJSON) > 0 {
+		if err := json.Unmarshal(metadataJSON, &task.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal metadata JSON for task %s: %w", task.TaskID, err)
+		}
+	}
+	if len(tagsJSON) > 0 {
+		if err := json.Unmarshal(tagsJSON, &task.Tags); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal tags JSON for task %s: %w", task.TaskID, err)
+		}
+	}
+
+	return &task, nil
+}
+
+// execer is satisfied by both *sql.DB and *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// insertTask inserts a single task using the given executor
+func (r *PostgresTaskRepository) insertTask(ctx context.Context, exec execer, task *models.Task) error {
 	// Generate UUID if not provided
 	if task.TaskID == "" {
 		task.TaskID = "task-" + uuid.New().String()
@@ -107,6 +147,10 @@ func (r *PostgresTaskRepository) Create(ctx context.Context, task *models.Task)
 	task.CreatedAt = now
 	task.UpdatedAt = now
 
+	if task.Priority == "" {
+		task.Priority = models.TaskPriorityNormal
+	}
+
 	// Convert maps to JSON
 	inputJSON, _ := json.Marshal(task.Input)
 	outputJSON, _ := json.Marshal(task.Output)
@@ -115,76 +159,78 @@ func (r *PostgresTaskRepository) Create(ctx context.Context, task *models.Task)
 
 	query := fmt.Sprintf(`
 		INSERT INTO %s (
-			task_id, project_id, agent_id, codebase_id, type, status, 
+			task_id, project_id, batch_id, campaign_id, created_by, agent_id, codebase_id, branch, commit_sha, type, analysis_mode, status, 
 			title, description, input, output, error_message,
-			created_at, updated_at, completed_at, metadata, tags
+			created_at, updated_at, completed_at, metadata, tags, priority
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23
 		)
 	`, r.tableName)
 
-	_, err := r.db.ExecContext(ctx, query,
-		task.TaskID, task.ProjectID, task.AgentID, task.CodebaseID, task.Type, task.Status,
+	_, err := exec.ExecContext(ctx, query,
+		task.TaskID, task.ProjectID, task.BatchID, task.CampaignID, task.CreatedBy, task.AgentID, task.CodebaseID, task.Branch, task.CommitSHA, task.Type, task.AnalysisMode, task.Status,
 		task.Title, task.Description, inputJSON, outputJSON, task.ErrorMessage,
-		task.CreatedAt, task.UpdatedAt, task.CompletedAt, metadataJSON, tagsJSON,
+		task.CreatedAt, task.UpdatedAt, task.CompletedAt, metadataJSON, tagsJSON, task.Priority,
 	)
 
 	return err
 }
 
+// Create creates a new task
+func (r *PostgresTaskRepository) Create(ctx context.Context, task *models.Task) error {
+	return r.insertTask(ctx, r.db, task)
+}
+
+// CreateBatch creates multiple tasks in a single transaction
+func (r *PostgresTaskRepository) CreateBatch(ctx context.Context, tasks []*models.Task) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				slog.WarnContext(ctx, "failed to rollback task batch", "error", rollbackErr)
+			}
+		}
+	}()
+
+	for _, task := range tasks {
+		if err = r.insertTask(ctx, tx, task); err != nil {
+			return fmt.Errorf("failed to insert task %s: %w", task.Title, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit task batch: %w", err)
+	}
+
+	return nil
+}
+
 // GetByID retrieves a task by its ID
 func (r *PostgresTaskRepository) GetByID(ctx context.Context, taskID string) (*models.Task, error) {
 	query := fmt.Sprintf(`
-		SELECT task_id, project_id, agent_id, codebase_id, type, status,
-			   title, description, input, output, error_message,
-			   created_at, updated_at, completed_at, metadata, tags
+		SELECT %s
 		FROM %s
 		WHERE task_id = $1
-	`, r.tableName)
-
-	var task models.Task
-	var inputJSON, outputJSON, metadataJSON, tagsJSON []byte
-
-	err := r.db.QueryRowContext(ctx, query, taskID).Scan(
-		&task.TaskID, &task.ProjectID, &task.AgentID, &task.CodebaseID, &task.Type, &task.Status,
-		&task.Title, &task.Description, &inputJSON, &outputJSON, &task.ErrorMessage,
-		&task.CreatedAt, &task.UpdatedAt, &task.CompletedAt, &metadataJSON, &tagsJSON,
-	)
+	`, taskColumns, r.tableName)
 
+	task, err := scanTask(r.db.QueryRowContext(ctx, query, taskID))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("task not found: %s", taskID)
+			return nil, apperrors.NotFound(apperrors.CodeTaskNotFound, "task not found: %s", taskID)
 		}
 		return nil, err
 	}
 
-	// Parse JSON fields
-	if len(inputJSON) > 0 {
-		if err := json.Unmarshal(inputJSON, &task.Input); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal input JSON: %w", err)
-		}
-	}
-	if len(outputJSON) > 0 {
-		if err := json.Unmarshal(outputJSON, &task.Output); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal output JSON: %w", err)
-		}
-	}
-	if len(metadataJSON) > 0 {
-		if err := json.Unmarshal(metadataJSON, &task.Metadata); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal metadata JSON: %w", err)
-		}
-	}
-	if len(tagsJSON) > 0 {
-		if err := json.Unmarshal(tagsJSON, &task.Tags); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal tags JSON: %w", err)
-		}
-	}
-
-	return &task, nil
+	return task, nil
 }
 
-// Update updates an existing task
-func (r *PostgresTaskRepository) Update(ctx context.Context, task *models.Task) error {
+// Update updates an existing task and records the notification it raises. A change of status is made in the same
+// conditional update, so a task that moved on in the meantime to a status that can't move to the task's is left
+// unchanged.
+func (r *PostgresTaskRepository) Update(ctx context.Context, task *models.Task, notification *models.Notification) error {
 	task.UpdatedAt = time.Now()
 
 	// Convert maps to JSON
@@ -197,30 +243,18 @@ func (r *PostgresTaskRepository) Update(ctx context.Context, task *models.Task)
 		UPDATE %s SET
 			project_id = $2, agent_id = $3, codebase_id = $4, type = $5, status = $6,
 			title = $7, description = $8, input = $9, output = $10, error_message = $11,
-			updated_at = $12, completed_at = $13, metadata = $14, tags = $15
-		WHERE task_id = $1
+			updated_at = $12, completed_at = $13, metadata = $14, tags = $15, approved = $16
+		WHERE task_id = $1 AND (status = $6 OR status = ANY($17))
 	`, r.tableName)
 
-	result, err := r.db.ExecContext(ctx, query,
-		task.TaskID, task.ProjectID, task.AgentID, task.CodebaseID, task.Type, task.Status,
-		task.Title, task.Description, inputJSON, outputJSON, task.ErrorMessage,
-		task.UpdatedAt, task.CompletedAt, metadataJSON, tagsJSON,
-	)
-
-	if err != nil {
-		return err
-	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return err
-	}
-
-	if rowsAffected == 0 {
-		return fmt.Errorf("task not found: %s", task.TaskID)
-	}
-
-	return nil
+	return r.withNotification(ctx, notification, func(exec execer) error {
+		result, err := exec.ExecContext(ctx, query,
+			task.TaskID, task.ProjectID, task.AgentID, task.CodebaseID, task.Type, task.Status,
+			task.Title, task.Description, inputJSON, outputJSON, task.ErrorMessage,
+			task.UpdatedAt, task.CompletedAt, metadataJSON, tagsJSON, task.Approved, statusesBefore(task.Status),
+		)
+		return r.taskTransitioned(ctx, result, err, task.TaskID, task.Status)
+	})
 }
 
 // Delete deletes a task by its ID
@@ -238,110 +272,124 @@ func (r *PostgresTaskRepository) Delete(ctx context.Context, taskID string) erro
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("task not found: %s", taskID)
+		return apperrors.NotFound(apperrors.CodeTaskNotFound, "task not found: %s", taskID)
 	}
 
 	return nil
 }
 
-// ListByProject lists tasks for a specific project with optional filters
+// ListByProject lists tasks for a specific project with optional filters. Operations hinted read-only are served by
+// the read replica.
 func (r *PostgresTaskRepository) ListByProject(ctx context.Context, projectID string, filters TaskFilters) ([]models.Task, int, error) {
-	whereClause := "WHERE project_id = $1"
-	args := []interface{}{projectID}
-	argIndex := 2
-
-	// Add filters
-	if filters.Status != nil {
-		whereClause += fmt.Sprintf(" AND status = $%d", argIndex)
-		args = append(args, *filters.Status)
-		argIndex++
-	}
-	if filters.Type != nil {
-		whereClause += fmt.Sprintf(" AND type = $%d", argIndex)
-		args = append(args, *filters.Type)
-		argIndex++
-	}
-	if filters.AgentID != nil {
-		whereClause += fmt.Sprintf(" AND agent_id = $%d", argIndex)
-		args = append(args, *filters.AgentID)
-		argIndex++
-	}
-	if filters.CodebaseID != nil {
-		whereClause += fmt.Sprintf(" AND codebase_id = $%d", argIndex)
-		args = append(args, *filters.CodebaseID)
-		argIndex++
-	}
+	whereClause, args := projectTaskFilter(projectID, filters)
+	argIndex := len(args) + 1
 
 	// Count query
 	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s %s", r.tableName, whereClause)
 	var totalCount int
-	err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&totalCount)
+	err := r.reads.QueryRowScan(ctx, countQuery, args, &totalCount)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	// Main query with pagination
 	query := fmt.Sprintf(`
-		SELECT task_id, project_id, agent_id, codebase_id, type, status,
-			   title, description, input, output, error_message,
-			   created_at, updated_at, completed_at, metadata, tags
+		SELECT %s
 		FROM %s %s
 		ORDER BY created_at DESC
 		LIMIT $%d OFFSET $%d
-	`, r.tableName, whereClause, argIndex, argIndex+1)
+	`, taskColumns, r.tableName, whereClause, argIndex, argIndex+1)
 
 	args = append(args, filters.Limit, filters.Offset)
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := r.reads.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, 0, err
 	}
 	defer func() {
 		if closeErr := rows.Close(); closeErr != nil {
-			slog.Warn("failed to close rows in ListByProject", "error", closeErr)
+			slog.WarnContext(ctx, "failed to close rows in ListByProject", "error", closeErr)
 		}
 	}()
 
 	var tasks []models.Task
 	for rows.Next() {
-		var task models.Task
-		var inputJSON, outputJSON, metadataJSON, tagsJSON []byte
-
-		err := rows.Scan(
-			&task.TaskID, &task.ProjectID, &task.AgentID, &task.CodebaseID, &task.Type, &task.Status,
-			&task.Title, &task.Description, &inputJSON, &outputJSON, &task.ErrorMessage,
-			&task.CreatedAt, &task.UpdatedAt, &task.CompletedAt, &metadataJSON, &tagsJSON,
-		)
+		task, err := scanTask(rows)
 		if err != nil {
 			return nil, 0, err
 		}
+		tasks = append(tasks, *task)
+	}
 
-		// Parse JSON fields with error handling
-		if len(inputJSON) > 0 {
-			if err := json.Unmarshal(inputJSON, &task.Input); err != nil {
-				return nil, 0, fmt.Errorf("failed to unmarshal input JSON for task %s: %w", task.TaskID, err)
-			}
-		}
-		if len(outputJSON) > 0 {
-			if err := json.Unmarshal(outputJSON, &task.Output); err != nil {
-				return nil, 0, fmt.Errorf("failed to unmarshal output JSON for task %s: %w", task.TaskID, err)
-			}
+	return tasks, totalCount, rows.Err()
+}
+
+// StreamByProject passes every task of a project matching filters to fn, newest first, reading them from the
+// database as fn consumes them. Pagination filters are ignored, and operations hinted read-only are served by the read
+// replica.
+func (r *PostgresTaskRepository) StreamByProject(ctx context.Context, projectID string, filters TaskFilters, fn func(models.Task) error) error {
+	whereClause, args := projectTaskFilter(projectID, filters)
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s %s
+		ORDER BY created_at DESC
+	`, taskColumns, r.tableName, whereClause)
+
+	rows, err := r.reads.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to stream tasks: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close rows in StreamByProject", "error", closeErr)
 		}
-		if len(metadataJSON) > 0 {
-			if err := json.Unmarshal(metadataJSON, &task.Metadata); err != nil {
-				return nil, 0, fmt.Errorf("failed to unmarshal metadata JSON for task %s: %w", task.TaskID, err)
-			}
+	}()
+
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return fmt.Errorf("failed to scan task: %w", err)
 		}
-		if len(tagsJSON) > 0 {
-			if err := json.Unmarshal(tagsJSON, &task.Tags); err != nil {
-				return nil, 0, fmt.Errorf("failed to unmarshal tags JSON for task %s: %w", task.TaskID, err)
-			}
+		if err := fn(*task); err != nil {
+			return err
 		}
+	}
+
+	return rows.Err()
+}
 
-		tasks = append(tasks, task)
+// projectTaskFilter builds the WHERE clause and arguments selecting the tasks of a project matching filters
+func projectTaskFilter(projectID string, filters TaskFilters) (string, []any) {
+	whereClause := "WHERE project_id = $1"
+	args := []any{projectID}
+
+	if filters.Status != nil {
+		args = append(args, *filters.Status)
+		whereClause += fmt.Sprintf(" AND status = $%d", len(args))
+	}
+	if filters.Type != nil {
+		args = append(args, *filters.Type)
+		whereClause += fmt.Sprintf(" AND type = $%d", len(args))
+	}
+	if filters.AgentID != nil {
+		args = append(args, *filters.AgentID)
+		whereClause += fmt.Sprintf(" AND agent_id = $%d", len(args))
+	}
+	if filters.CodebaseID != nil {
+		args = append(args, *filters.CodebaseID)
+		whereClause += fmt.Sprintf(" AND codebase_id = $%d", len(args))
+	}
+	if len(filters.Tags) > 0 {
+		tagsJSON, _ := json.Marshal(filters.Tags)
+		args = append(args, string(tagsJSON))
+		whereClause += fmt.Sprintf(" AND tags @> $%d::jsonb", len(args))
+	}
+	if filters.Owner != nil {
+		args = append(args, *filters.Owner)
+		whereClause += fmt.Sprintf(" AND output->'owners' ? $%d", len(args))
 	}
 
-	return tasks, totalCount, rows.Err()
+	return whereClause, args
 }
 
 // ListByAgent lists tasks for a specific agent
@@ -356,40 +404,200 @@ func (r *PostgresTaskRepository) ListByCodebase(ctx context.Context, codebaseID
 	return r.listWithFilters(ctx, filters)
 }
 
-// UpdateStatus updates only the status of a task
-func (r *PostgresTaskRepository) UpdateStatus(ctx context.Context, taskID string, status models.TaskStatus) error {
-	now := time.Now()
-	var completedAt *time.Time
+// ListByBatch lists all tasks created as part of a batch
+func (r *PostgresTaskRepository) ListByBatch(ctx context.Context, batchID string) ([]models.Task, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		WHERE batch_id = $1
+		ORDER BY created_at ASC
+	`, taskColumns, r.tableName)
 
-	// Set completed_at if status is completed
-	if status == models.TaskStatusCompleted {
-		completedAt = &now
+	rows, err := r.db.QueryContext(ctx, query, batchID)
+	if err != nil {
+		return nil, err
 	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close rows in ListByBatch", "error", closeErr)
+		}
+	}()
+
+	var tasks []models.Task
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, *task)
+	}
+
+	return tasks, rows.Err()
+}
 
+// ListByCampaign lists the child tasks of a campaign
+func (r *PostgresTaskRepository) ListByCampaign(ctx context.Context, campaignID string) ([]models.Task, error) {
 	query := fmt.Sprintf(`
-		UPDATE %s SET status = $2, updated_at = $3, completed_at = $4
-		WHERE task_id = $1
+		SELECT %s
+		FROM %s
+		WHERE campaign_id = $1
+		ORDER BY created_at ASC
+	`, taskColumns, r.tableName)
+
+	rows, err := r.db.QueryContext(ctx, query, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close rows in ListByCampaign", "error", closeErr)
+		}
+	}()
+
+	var tasks []models.Task
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, *task)
+	}
+
+	return tasks, rows.Err()
+}
+
+// ListByExperiment lists the tasks assigned to an experiment
+func (r *PostgresTaskRepository) ListByExperiment(ctx context.Context, experimentID string) ([]models.Task, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		WHERE experiment_id = $1
+		ORDER BY created_at ASC
+	`, taskColumns, r.tableName)
+
+	rows, err := r.db.QueryContext(ctx, query, experimentID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close rows in ListByExperiment", "error", closeErr)
+		}
+	}()
+
+	var tasks []models.Task
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, *task)
+	}
+
+	return tasks, rows.Err()
+}
+
+// AssignExperiment records the experiment and arm of a task that wasn't assigned yet
+func (r *PostgresTaskRepository) AssignExperiment(ctx context.Context, taskID, experimentID string, arm models.ExperimentArm) error {
+	query := fmt.Sprintf(`
+		UPDATE %s SET experiment_id = $2, experiment_arm = $3
+		WHERE task_id = $1 AND experiment_id IS NULL
+	`, r.tableName)
+
+	_, err := r.db.ExecContext(ctx, query, taskID, experimentID, arm)
+	return err
+}
+
+// CountByProject counts the project's tasks created since the given time, grouped by status and type
+func (r *PostgresTaskRepository) CountByProject(ctx context.Context, projectID string, since time.Time) ([]TaskCount, error) {
+	query := fmt.Sprintf(`
+		SELECT status, type, COUNT(*)
+		FROM %s
+		WHERE project_id = $1 AND created_at >= $2
+		GROUP BY status, type
 	`, r.tableName)
 
-	result, err := r.db.ExecContext(ctx, query, taskID, status, now, completedAt)
+	rows, err := r.db.QueryContext(ctx, query, projectID, since)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close rows in CountByProject", "error", closeErr)
+		}
+	}()
 
-	rowsAffected, err := result.RowsAffected()
+	var counts []TaskCount
+	for rows.Next() {
+		var count TaskCount
+		if err := rows.Scan(&count.Status, &count.Type, &count.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+
+	return counts, rows.Err()
+}
+
+// ListRecentFailures lists the project's most recently failed tasks updated since the given time
+func (r *PostgresTaskRepository) ListRecentFailures(ctx context.Context, projectID string, since time.Time, limit int) ([]models.Task, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		WHERE project_id = $1 AND status = $2 AND updated_at >= $3
+		ORDER BY updated_at DESC
+		LIMIT $4
+	`, taskColumns, r.tableName)
+
+	rows, err := r.db.QueryContext(ctx, query, projectID, models.TaskStatusFailed, since, limit)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close rows in ListRecentFailures", "error", closeErr)
+		}
+	}()
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("task not found: %s", taskID)
+	var tasks []models.Task
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, *task)
 	}
 
-	return nil
+	return tasks, rows.Err()
 }
 
-// UpdateStatusAndOutput updates the status and output of a task
-func (r *PostgresTaskRepository) UpdateStatusAndOutput(ctx context.Context, taskID string, status models.TaskStatus, output map[string]any, errorMessage *string) error {
+// UpdateStatus moves a task to status in a single conditional update, so a task whose status can't move to it, such
+// as a task cancelled in the meantime, is left unchanged, and records the notification the transition raises. A task
+// moved to queued goes to the back of the queue.
+func (r *PostgresTaskRepository) UpdateStatus(ctx context.Context, taskID string, status models.TaskStatus, notification *models.Notification) error {
+	now := time.Now()
+	var completedAt *time.Time
+
+	// Set completed_at if the task finished
+	if status.IsTerminal() {
+		completedAt = &now
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s SET status = $2, updated_at = $3, completed_at = $4,
+			queued_at = CASE WHEN $2 = 'queued' THEN $3 ELSE queued_at END
+		WHERE task_id = $1 AND status = ANY($5)
+	`, r.tableName)
+
+	return r.withNotification(ctx, notification, func(exec execer) error {
+		result, err := exec.ExecContext(ctx, query, taskID, status, now, completedAt, statusesBefore(status))
+		return r.taskTransitioned(ctx, result, err, taskID, status)
+	})
+}
+
+// UpdateStatusAndOutput moves a task to status with its output in a single conditional update, like UpdateStatus, and
+// records the notification the transition raises
+func (r *PostgresTaskRepository) UpdateStatusAndOutput(ctx context.Context, taskID string, status models.TaskStatus, output map[string]any, errorMessage *string, notification *models.Notification) error {
 	now := time.Now()
 	var completedAt *time.Time
 
@@ -400,12 +608,202 @@ func (r *PostgresTaskRepository) UpdateStatusAndOutput(ctx context.Context, task
 
 	outputJSON, _ := json.Marshal(output)
 
+	// A completed task has made all its progress, while a failed one keeps the step it failed in
 	query := fmt.Sprintf(`
-		UPDATE %s SET status = $2, output = $3, error_message = $4, updated_at = $5, completed_at = $6
-		WHERE task_id = $1
+		UPDATE %s SET status = $2, output = $3, error_message = $4, updated_at = $5, completed_at = $6,
+			progress = CASE WHEN $2 = 'completed' THEN 100 ELSE progress END,
+			progress_step = CASE WHEN $2 = 'completed' THEN NULL ELSE progress_step END
+		WHERE task_id = $1 AND status = ANY($7)
 	`, r.tableName)
 
-	result, err := r.db.ExecContext(ctx, query, taskID, status, outputJSON, errorMessage, now, completedAt)
+	return r.withNotification(ctx, notification, func(exec execer) error {
+		result, err := exec.ExecContext(ctx, query, taskID, status, outputJSON, errorMessage, now, completedAt, statusesBefore(status))
+		return r.taskTransitioned(ctx, result, err, taskID, status)
+	})
+}
+
+// Heartbeat records that the execution of an in_progress task is still running, and how far it got
+func (r *PostgresTaskRepository) Heartbeat(ctx context.Context, taskID string, progress int, step string) error {
+	query := fmt.Sprintf(`
+		UPDATE %s SET heartbeat_at = $3, progress = $4, progress_step = NULLIF($5, '')
+		WHERE task_id = $1 AND status = $2
+	`, r.tableName)
+
+	if _, err := r.db.ExecContext(ctx, query, taskID, models.TaskStatusInProgress, time.Now(), progress, step); err != nil {
+		return fmt.Errorf("failed to record task heartbeat: %w", err)
+	}
+
+	return nil
+}
+
+// stuckTaskCondition matches the in_progress tasks without a heartbeat since the time bound to the given parameter
+const stuckTaskCondition = `status = 'in_progress' AND COALESCE(heartbeat_at, updated_at) < $%d`
+
+// ListStuck lists the in_progress tasks without a heartbeat since the given time, longest silent first
+func (r *PostgresTaskRepository) ListStuck(ctx context.Context, heartbeatBefore time.Time, limit int) ([]models.Task, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		WHERE `+stuckTaskCondition+`
+		ORDER BY COALESCE(heartbeat_at, updated_at)
+		LIMIT $2
+	`, taskColumns, r.tableName, 1)
+
+	rows, err := r.db.QueryContext(ctx, query, heartbeatBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stuck tasks: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close rows in ListStuck", "error", closeErr)
+		}
+	}()
+
+	tasks := []models.Task{}
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, *task)
+	}
+
+	return tasks, rows.Err()
+}
+
+// queueOrder orders the queued tasks, aliased t, the way the dispatcher runs them: higher priority first, then the
+// tasks of projects with fewer tasks in progress, so one project's bulk work can't starve the others, then the
+// longest queued. The tasks table is bound to the format verb.
+const queueOrder = `CASE t.priority WHEN 'high' THEN 0 WHEN 'normal' THEN 1 ELSE 2 END,
+			(SELECT COUNT(*) FROM %s r WHERE r.project_id = t.project_id AND r.status = 'in_progress'),
+			t.queued_at, t.task_id`
+
+// NextQueued returns the queued task the dispatcher runs next, nil when no task is queued
+func (r *PostgresTaskRepository) NextQueued(ctx context.Context) (*models.Task, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s t
+		WHERE t.status = 'queued'
+		ORDER BY `+queueOrder+`
+		LIMIT 1
+	`, taskColumns, r.tableName, r.tableName)
+
+	task, err := scanTask(r.db.QueryRowContext(ctx, query))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get next queued task: %w", err)
+	}
+
+	return task, nil
+}
+
+// QueuePosition returns the position of a queued task in the order NextQueued runs them, 0 when it isn't queued
+func (r *PostgresTaskRepository) QueuePosition(ctx context.Context, taskID string) (int, error) {
+	query := fmt.Sprintf(`
+		SELECT position
+		FROM (
+			SELECT t.task_id, ROW_NUMBER() OVER (ORDER BY `+queueOrder+`) AS position
+			FROM %s t
+			WHERE t.status = 'queued'
+		) queue
+		WHERE task_id = $1
+	`, r.tableName, r.tableName)
+
+	var position int
+	err := r.db.QueryRowContext(ctx, query, taskID).Scan(&position)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get task queue position: %w", err)
+	}
+
+	return position, nil
+}
+
+// errTaskNotStuck rolls back the recovery of a task that sent a heartbeat or left in_progress in the meantime
+var errTaskNotStuck = errors.New("task is not stuck")
+
+// RecoverStuck moves a stuck task to status in a single conditional update, so a task whose execution is still
+// heartbeating is never changed
+func (r *PostgresTaskRepository) RecoverStuck(ctx context.Context, taskID string, heartbeatBefore time.Time, status models.TaskStatus, errorMessage *string, notification *models.Notification) (bool, error) {
+	if !models.TaskStatusInProgress.CanTransitionTo(status) {
+		return false, apperrors.Conflict(apperrors.CodeInvalidTaskTransition, "stuck task %s can't move to %s", taskID, status)
+	}
+
+	now := time.Now()
+	var completedAt *time.Time
+	if status.IsTerminal() {
+		completedAt = &now
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s SET status = $2, error_message = $3, updated_at = $4, completed_at = $5, heartbeat_at = NULL,
+			queued_at = CASE WHEN $2 = 'queued' THEN $4 ELSE queued_at END
+		WHERE task_id = $1 AND `+stuckTaskCondition+`
+	`, r.tableName, 6)
+
+	err := r.withNotification(ctx, notification, func(exec execer) error {
+		result, err := exec.ExecContext(ctx, query, taskID, status, errorMessage, now, completedAt, heartbeatBefore)
+		if err != nil {
+			return fmt.Errorf("failed to recover stuck task: %w", err)
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return errTaskNotStuck
+		}
+		return nil
+	})
+	if errors.Is(err, errTaskNotStuck) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// withNotification runs a task change, and records the notification it raises in the outbox in the same
+// transaction, so the notification is delivered if and only if the change is stored
+func (r *PostgresTaskRepository) withNotification(ctx context.Context, notification *models.Notification, change func(exec execer) error) (err error) {
+	if notification == nil {
+		return change(r.db)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				slog.WarnContext(ctx, "failed to rollback task change", "error", rollbackErr)
+			}
+		}
+	}()
+
+	if err = change(tx); err != nil {
+		return err
+	}
+	if err = insertOutboxEvent(ctx, tx, r.outboxTableName, *notification); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit task change: %w", err)
+	}
+
+	return nil
+}
+
+// taskUpdated checks the result of a task update, reporting a missing task as not found
+func taskUpdated(result sql.Result, err error, taskID string) error {
 	if err != nil {
 		return err
 	}
@@ -416,12 +814,48 @@ func (r *PostgresTaskRepository) UpdateStatusAndOutput(ctx context.Context, task
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("task not found: %s", taskID)
+		return apperrors.NotFound(apperrors.CodeTaskNotFound, "task not found: %s", taskID)
 	}
 
 	return nil
 }
 
+// taskTransitioned checks the result of a conditional update of a task's status, telling a missing task from one
+// whose current status can't move to status
+func (r *PostgresTaskRepository) taskTransitioned(ctx context.Context, result sql.Result, err error, taskID string, status models.TaskStatus) error {
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected > 0 {
+		return nil
+	}
+
+	var current models.TaskStatus
+	query := fmt.Sprintf(`SELECT status FROM %s WHERE task_id = $1`, r.tableName)
+	if err := r.db.QueryRowContext(ctx, query, taskID).Scan(&current); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperrors.NotFound(apperrors.CodeTaskNotFound, "task not found: %s", taskID)
+		}
+		return fmt.Errorf("failed to get task status: %w", err)
+	}
+
+	return apperrors.Conflict(apperrors.CodeInvalidTaskTransition, "task %s is %s and can't move to %s", taskID, current, status)
+}
+
+// statusesBefore returns the statuses a task may move to status from, as a query parameter
+func statusesBefore(status models.TaskStatus) any {
+	var statuses []string
+	for _, before := range models.TaskStatusesBefore(status) {
+		statuses = append(statuses, string(before))
+	}
+	return pq.Array(statuses)
+}
+
 // listWithFilters is a helper method for listing tasks with filters
 func (r *PostgresTaskRepository) listWithFilters(ctx context.Context, filters TaskFilters) ([]models.Task, int, error) {
 	whereClause := "WHERE 1=1"
@@ -449,74 +883,53 @@ func (r *PostgresTaskRepository) listWithFilters(ctx context.Context, filters Ta
 		args = append(args, *filters.CodebaseID)
 		argIndex++
 	}
+	if len(filters.Tags) > 0 {
+		tagsJSON, _ := json.Marshal(filters.Tags)
+		whereClause += fmt.Sprintf(" AND tags @> $%d::jsonb", argIndex)
+		args = append(args, string(tagsJSON))
+		argIndex++
+	}
+	if filters.Owner != nil {
+		whereClause += fmt.Sprintf(" AND output->'owners' ? $%d", argIndex)
+		args = append(args, *filters.Owner)
+		argIndex++
+	}
 
 	// Count query
 	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM %s %s`, r.tableName, whereClause)
 	var totalCount int
-	err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&totalCount)
+	err := r.reads.QueryRowScan(ctx, countQuery, args, &totalCount)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	// Main query with pagination
 	query := fmt.Sprintf(`
-		SELECT task_id, project_id, agent_id, codebase_id, type, status,
-			   title, description, input, output, error_message,
-			   created_at, updated_at, completed_at, metadata, tags
+		SELECT %s
 		FROM %s %s
 		ORDER BY created_at DESC
 		LIMIT $%d OFFSET $%d
-	`, r.tableName, whereClause, argIndex, argIndex+1)
+	`, taskColumns, r.tableName, whereClause, argIndex, argIndex+1)
 
 	args = append(args, filters.Limit, filters.Offset)
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := r.reads.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, 0, err
 	}
 	defer func() {
 		if closeErr := rows.Close(); closeErr != nil {
-			slog.Warn("failed to close rows in listWithFilters", "error", closeErr)
+			slog.WarnContext(ctx, "failed to close rows in listWithFilters", "error", closeErr)
 		}
 	}()
 
 	var tasks []models.Task
 	for rows.Next() {
-		var task models.Task
-		var inputJSON, outputJSON, metadataJSON, tagsJSON []byte
-
-		err := rows.Scan(
-			&task.TaskID, &task.ProjectID, &task.AgentID, &task.CodebaseID, &task.Type, &task.Status,
-			&task.Title, &task.Description, &inputJSON, &outputJSON, &task.ErrorMessage,
-			&task.CreatedAt, &task.UpdatedAt, &task.CompletedAt, &metadataJSON, &tagsJSON,
-		)
+		task, err := scanTask(rows)
 		if err != nil {
 			return nil, 0, err
 		}
-
-		// Parse JSON fields with error handling
-		if len(inputJSON) > 0 {
-			if err := json.Unmarshal(inputJSON, &task.Input); err != nil {
-				return nil, 0, fmt.Errorf("failed to unmarshal input JSON for task %s: %w", task.TaskID, err)
-			}
-		}
-		if len(outputJSON) > 0 {
-			if err := json.Unmarshal(outputJSON, &task.Output); err != nil {
-				return nil, 0, fmt.Errorf("failed to unmarshal output JSON for task %s: %w", task.TaskID, err)
-			}
-		}
-		if len(metadataJSON) > 0 {
-			if err := json.Unmarshal(metadataJSON, &task.Metadata); err != nil {
-				return nil, 0, fmt.Errorf("failed to unmarshal metadata JSON for task %s: %w", task.TaskID, err)
-			}
-		}
-		if len(tagsJSON) > 0 {
-			if err := json.Unmarshal(tagsJSON, &task.Tags); err != nil {
-				return nil, 0, fmt.Errorf("failed to unmarshal tags JSON for task %s: %w", task.TaskID, err)
-			}
-		}
-
-		tasks = append(tasks, task)
+		tasks = append(tasks, *task)
 	}
 
 	return tasks, totalCount, rows.Err()
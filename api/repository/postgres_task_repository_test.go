@@ -0,0 +1,269 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+func TestPostgresTaskRepository_CreateBatch_Commits(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresTaskRepositoryWithDB(db, "tasks", "notification_outbox")
+
+	batchID := "batch-1"
+	tasks := []*models.Task{
+		{TaskID: "task-1", ProjectID: "proj-1", BatchID: &batchID, AgentID: "agent-1", Type: models.TaskTypeRefactoring, Status: models.TaskStatusPending, Title: "one"},
+		{TaskID: "task-2", ProjectID: "proj-1", BatchID: &batchID, AgentID: "agent-1", Type: models.TaskTypeRefactoring, Status: models.TaskStatusPending, Title: "two"},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO tasks`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO tasks`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err = repo.CreateBatch(context.Background(), tasks)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresTaskRepository_CreateBatch_RollsBackOnError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresTaskRepositoryWithDB(db, "tasks", "notification_outbox")
+
+	tasks := []*models.Task{
+		{TaskID: "task-1", ProjectID: "proj-1", AgentID: "agent-1", Title: "one"},
+		{TaskID: "task-2", ProjectID: "proj-1", AgentID: "agent-1", Title: "two"},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO tasks`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO tasks`).WillReturnError(errors.New("constraint violation"))
+	mock.ExpectRollback()
+
+	err = repo.CreateBatch(context.Background(), tasks)
+	assert.ErrorContains(t, err, "constraint violation")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresTaskRepository_UpdateStatusAndOutput_RecordsNotificationInTransaction(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresTaskRepositoryWithDB(db, "tasks", "notification_outbox")
+	notification := &models.Notification{Event: models.NotificationEventTaskCompleted, ProjectID: "proj-1", ResourceID: "task-1", Subject: "Task completed: one", Message: "done"}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE tasks SET status`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO notification_outbox`).
+		WithArgs(sqlmock.AnyArg(), models.NotificationEventTaskCompleted, "proj-1", "", "task-1", "Task completed: one", "done", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err = repo.UpdateStatusAndOutput(context.Background(), "task-1", models.TaskStatusCompleted, map[string]any{"ok": true}, nil, notification)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresTaskRepository_UpdateStatusAndOutput_MissingTaskRecordsNoNotification(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresTaskRepositoryWithDB(db, "tasks", "notification_outbox")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE tasks SET status`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT status FROM tasks WHERE task_id = \$1`).WithArgs("task-1").WillReturnRows(sqlmock.NewRows([]string{"status"}))
+	mock.ExpectRollback()
+
+	err = repo.UpdateStatusAndOutput(context.Background(), "task-1", models.TaskStatusFailed, nil, nil, &models.Notification{Event: models.NotificationEventTaskFailed})
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresTaskRepository_UpdateStatus_RejectsIllegalTransition(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresTaskRepositoryWithDB(db, "tasks", "notification_outbox")
+
+	// The task was cancelled after it was read, so the execution can't start it
+	mock.ExpectExec(`UPDATE tasks SET status = \$2, updated_at = \$3, completed_at = \$4,\s+queued_at = CASE WHEN \$2 = 'queued' THEN \$3 ELSE queued_at END\s+WHERE task_id = \$1 AND status = ANY\(\$5\)`).
+		WithArgs("task-1", models.TaskStatusInProgress, sqlmock.AnyArg(), nil, pq.Array([]string{"pending", "queued"})).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT status FROM tasks WHERE task_id = \$1`).
+		WithArgs("task-1").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("cancelled"))
+
+	err = repo.UpdateStatus(context.Background(), "task-1", models.TaskStatusInProgress, nil)
+
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+	assert.Equal(t, apperrors.CodeInvalidTaskTransition, apperrors.CodeOf(err))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresTaskRepository_RecoverStuck_TaskNoLongerStuckRecordsNoNotification(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresTaskRepositoryWithDB(db, "tasks", "notification_outbox")
+	heartbeatBefore := time.Now().Add(-5 * time.Minute)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE tasks SET status = \$2, .+ heartbeat_at = NULL, .+ WHERE task_id = \$1 AND status = 'in_progress' AND COALESCE\(heartbeat_at, updated_at\) < \$6`).
+		WithArgs("task-1", models.TaskStatusFailed, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), heartbeatBefore).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	errorMsg := "stopped sending heartbeats"
+	recovered, err := repo.RecoverStuck(context.Background(), "task-1", heartbeatBefore, models.TaskStatusFailed, &errorMsg, &models.Notification{Event: models.NotificationEventTaskFailed})
+	require.NoError(t, err)
+	assert.False(t, recovered)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresTaskRepository_CountByProject(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresTaskRepositoryWithDB(db, "tasks", "notification_outbox")
+	since := time.Date(2024, time.January, 8, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT status, type, COUNT\(\*\) FROM tasks WHERE project_id = \$1 AND created_at >= \$2 GROUP BY status, type`).
+		WithArgs("proj-1", since).
+		WillReturnRows(sqlmock.NewRows([]string{"status", "type", "count"}).
+			AddRow("completed", "refactoring", 5).
+			AddRow("failed", "code_review", 1))
+
+	counts, err := repo.CountByProject(context.Background(), "proj-1", since)
+
+	require.NoError(t, err)
+	assert.Equal(t, []TaskCount{
+		{Status: models.TaskStatusCompleted, Type: models.TaskTypeRefactoring, Count: 5},
+		{Status: models.TaskStatusFailed, Type: models.TaskTypeCodeReview, Count: 1},
+	}, counts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresTaskRepository_ListByProject_FiltersByTags(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresTaskRepositoryWithDB(db, "tasks", "notification_outbox")
+	status := models.TaskStatusCompleted
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM tasks WHERE project_id = \$1 AND status = \$2 AND tags @> \$3::jsonb`).
+		WithArgs("proj-1", status, `{"env":"prod"}`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`SELECT .+ FROM tasks WHERE project_id = \$1 AND status = \$2 AND tags @> \$3::jsonb ORDER BY created_at DESC LIMIT \$4 OFFSET \$5`).
+		WithArgs("proj-1", status, `{"env":"prod"}`, 20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"task_id"}))
+
+	tasks, total, err := repo.ListByProject(context.Background(), "proj-1", TaskFilters{Status: &status, Tags: map[string]string{"env": "prod"}, Limit: 20})
+
+	require.NoError(t, err)
+	assert.Empty(t, tasks)
+	assert.Zero(t, total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresTaskRepository_ListByProject_FiltersByOwner(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresTaskRepositoryWithDB(db, "tasks", "notification_outbox")
+	owner := "@acme/billing"
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM tasks WHERE project_id = \$1 AND output->'owners' \? \$2`).
+		WithArgs("proj-1", owner).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`SELECT .+ FROM tasks WHERE project_id = \$1 AND output->'owners' \? \$2 ORDER BY created_at DESC LIMIT \$3 OFFSET \$4`).
+		WithArgs("proj-1", owner, 20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"task_id"}))
+
+	tasks, _, err := repo.ListByProject(context.Background(), "proj-1", TaskFilters{Owner: &owner, Limit: 20})
+
+	require.NoError(t, err)
+	assert.Empty(t, tasks)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresTaskRepository_AssignExperiment_KeepsFirstAssignment(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresTaskRepositoryWithDB(db, "tasks", "notification_outbox")
+
+	mock.ExpectExec(`UPDATE tasks SET experiment_id = \$2, experiment_arm = \$3\s+WHERE task_id = \$1 AND experiment_id IS NULL`).
+		WithArgs("task-1", "exp-1", models.ExperimentArmVariant).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.AssignExperiment(context.Background(), "task-1", "exp-1", models.ExperimentArmVariant)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresTaskRepository_NextQueued_OrdersByPriorityThenProjectLoad(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresTaskRepositoryWithDB(db, "tasks", "notification_outbox")
+
+	mock.ExpectQuery(`FROM tasks t\s+WHERE t.status = 'queued'\s+ORDER BY CASE t.priority WHEN 'high' THEN 0 WHEN 'normal' THEN 1 ELSE 2 END,\s+\(SELECT COUNT\(\*\) FROM tasks r WHERE r.project_id = t.project_id AND r.status = 'in_progress'\),\s+t.queued_at, t.task_id\s+LIMIT 1`).
+		WillReturnRows(sqlmock.NewRows([]string{"task_id"}))
+
+	task, err := repo.NextQueued(context.Background())
+
+	assert.NoError(t, err)
+	assert.Nil(t, task)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresTaskRepository_QueuePosition(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresTaskRepositoryWithDB(db, "tasks", "notification_outbox")
+
+	mock.ExpectQuery(`SELECT position\s+FROM \(\s+SELECT t.task_id, ROW_NUMBER\(\) OVER \(ORDER BY CASE t.priority`).
+		WithArgs("task-1").
+		WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow(2))
+	mock.ExpectQuery(`SELECT position`).
+		WithArgs("task-2").
+		WillReturnRows(sqlmock.NewRows([]string{"position"}))
+
+	position, err := repo.QueuePosition(context.Background(), "task-1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, position)
+
+	// A task that isn't queued has no position
+	position, err = repo.QueuePosition(context.Background(), "task-2")
+	require.NoError(t, err)
+	assert.Zero(t, position)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// userNotificationColumns lists the inbox notification columns in the order expected by scanUserNotification
+const userNotificationColumns = `notification_id, user_id, event, title, message, project_id, resource_id, read_at, created_at`
+
+// PostgresUserNotificationRepository implements UserNotificationRepository using PostgreSQL
+type PostgresUserNotificationRepository struct {
+	db        *sql.DB
+	tableName string
+}
+
+// NewPostgresUserNotificationRepository creates a new PostgreSQL notification inbox repository
+func NewPostgresUserNotificationRepository(config PostgresConfig, tableName string) (UserNotificationRepository, error) {
+	if tableName == "" {
+		tableName = conf.DefaultNotificationsTableName
+	}
+
+	// Build connection string
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	repo := &PostgresUserNotificationRepository{
+		db:        db,
+		tableName: tableName,
+	}
+
+	return repo, nil
+}
+
+// NewPostgresUserNotificationRepositoryWithDB creates a new PostgreSQL notification inbox repository with an existing DB connection
+func NewPostgresUserNotificationRepositoryWithDB(db *sql.DB, tableName string) UserNotificationRepository {
+	if tableName == "" {
+		tableName = conf.DefaultNotificationsTableName
+	}
+
+	return &PostgresUserNotificationRepository{
+		db:        db,
+		tableName: tableName,
+	}
+}
+
+// CreateNotification records a notification in a user's inbox. A notification whose ID is already recorded, from an
+// earlier delivery of the same event, is left as is.
+func (r *PostgresUserNotificationRepository) CreateNotification(ctx context.Context, notification *models.UserNotification) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (%s)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (notification_id) DO NOTHING
+	`, r.tableName, userNotificationColumns)
+
+	_, err := r.db.ExecContext(ctx, query,
+		notification.NotificationID, notification.UserID, notification.Event, notification.Title, notification.Message,
+		notification.ProjectID, notification.ResourceID, notification.ReadAt, notification.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	return nil
+}
+
+// ListNotifications lists a user's notifications newest first. The next token is the ID of the last notification
+// returned, and the following page continues after that notification's position.
+func (r *PostgresUserNotificationRepository) ListNotifications(ctx context.Context, userID string, opts ListUserNotificationsOptions) ([]models.UserNotification, string, error) {
+	conditions := []string{"user_id = $1"}
+	args := []any{userID}
+
+	if opts.UnreadOnly {
+		conditions = append(conditions, "read_at IS NULL")
+	}
+	if opts.NextToken != nil && *opts.NextToken != "" {
+		args = append(args, *opts.NextToken)
+		conditions = append(conditions, fmt.Sprintf(
+			"(created_at, notification_id) < (SELECT created_at, notification_id FROM %s WHERE notification_id = $%d)",
+			r.tableName, len(args)))
+	}
+	args = append(args, opts.MaxResults+1) // Get one extra to check if there are more results
+
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE %s ORDER BY created_at DESC, notification_id DESC LIMIT $%d`,
+		userNotificationColumns, r.tableName, strings.Join(conditions, " AND "), len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list notifications: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close notification rows", "error", closeErr)
+		}
+	}()
+
+	notifications := []models.UserNotification{}
+	for rows.Next() {
+		notification, err := scanUserNotification(rows)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to scan notification: %w", err)
+		}
+		notifications = append(notifications, *notification)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("failed to iterate notifications: %w", err)
+	}
+
+	var nextToken string
+	if len(notifications) > opts.MaxResults {
+		notifications = notifications[:opts.MaxResults]
+		nextToken = notifications[len(notifications)-1].NotificationID
+	}
+
+	return notifications, nextToken, nil
+}
+
+// CountUnread counts a user's unread notifications
+func (r *PostgresUserNotificationRepository) CountUnread(ctx context.Context, userID string) (int, error) {
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE user_id = $1 AND read_at IS NULL`, r.tableName)
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+
+	return count, nil
+}
+
+// MarkRead marks one of a user's notifications read. Notifications of other users are reported as not found.
+func (r *PostgresUserNotificationRepository) MarkRead(ctx context.Context, userID, notificationID string, readAt time.Time) error {
+	query := fmt.Sprintf(`
+		UPDATE %s SET read_at = COALESCE(read_at, $3)
+		WHERE notification_id = $1 AND user_id = $2
+	`, r.tableName)
+
+	result, err := r.db.ExecContext(ctx, query, notificationID, userID, readAt)
+	if err != nil {
+		return fmt.Errorf("failed to mark notification read: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return apperrors.NotFound(apperrors.CodeNotificationNotFound, "notification not found: %s", notificationID)
+	}
+
+	return nil
+}
+
+// scanUserNotification scans a single row selected with userNotificationColumns
+func scanUserNotification(row rowScanner) (*models.UserNotification, error) {
+	var notification models.UserNotification
+	err := row.Scan(
+		&notification.NotificationID, &notification.UserID, &notification.Event, &notification.Title, &notification.Message,
+		&notification.ProjectID, &notification.ResourceID, &notification.ReadAt, &notification.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &notification, nil
+}
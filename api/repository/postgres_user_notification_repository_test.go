@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+)
+
+func TestPostgresUserNotificationRepository_ListNotifications(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresUserNotificationRepositoryWithDB(db, "notifications")
+	createdAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+	nextToken := "notif-3"
+
+	columns := []string{"notification_id", "user_id", "event", "title", "message", "project_id", "resource_id", "read_at", "created_at"}
+	mock.ExpectQuery(`SELECT .+ FROM notifications WHERE user_id = \$1 AND read_at IS NULL AND \(created_at, notification_id\) < \(SELECT created_at, notification_id FROM notifications WHERE notification_id = \$2\) ORDER BY created_at DESC, notification_id DESC LIMIT \$3`).
+		WithArgs("user-1", nextToken, 3).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("notif-2", "user-1", "task.completed", "Task completed: one", "done", "proj-1", "task-1", nil, createdAt).
+			AddRow("notif-1", "user-1", "task.failed", "Task failed: two", "failed", "proj-1", "task-2", nil, createdAt).
+			AddRow("notif-0", "user-1", "task.failed", "Task failed: three", "failed", nil, nil, nil, createdAt))
+
+	notifications, token, err := repo.ListNotifications(context.Background(), "user-1", ListUserNotificationsOptions{
+		UnreadOnly: true,
+		NextToken:  &nextToken,
+		MaxResults: 2,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, notifications, 2)
+	assert.Equal(t, "notif-1", token)
+	assert.Equal(t, "task-1", *notifications[0].ResourceID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresUserNotificationRepository_MarkRead_OtherUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresUserNotificationRepositoryWithDB(db, "notifications")
+	readAt := time.Date(2024, time.January, 15, 11, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec(`UPDATE notifications SET read_at = COALESCE\(read_at, \$3\) WHERE notification_id = \$1 AND user_id = \$2`).
+		WithArgs("notif-1", "user-2", readAt).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.MarkRead(context.Background(), "user-2", "notif-1", readAt)
+
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -5,10 +5,12 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/lib/pq"
 
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
 	"github.com/kazemisoroush/code-refactoring-tool/api/models"
 )
 
@@ -37,11 +39,6 @@ func NewPostgresUserRepository(config PostgresConfig, tableName string) (UserRep
 		tableName: tableName,
 	}
 
-	// Create table if it doesn't exist
-	if err := repo.CreateTable(context.Background()); err != nil {
-		return nil, fmt.Errorf("failed to create users table: %w", err)
-	}
-
 	return repo, nil
 }
 
@@ -86,7 +83,7 @@ func (r *PostgresUserRepository) CreateUser(ctx context.Context, user *models.DB
 		var pqErr *pq.Error
 		if errors.As(err, &pqErr) && pqErr.Code == "23505" { // unique_violation
 			if pqErr.Constraint == "users_email_key" || pqErr.Constraint == "users_username_key" || pqErr.Constraint == "users_auth_id_key" {
-				return nil, fmt.Errorf("user with email '%s', username '%s', or auth_id '%s' already exists", user.Email, user.Username, user.AuthID)
+				return nil, apperrors.Conflict(apperrors.CodeUserExists, "user with email '%s', username '%s', or auth_id '%s' already exists", user.Email, user.Username, user.AuthID)
 			}
 		}
 		return nil, fmt.Errorf("failed to create user: %w", err)
@@ -155,7 +152,7 @@ func (r *PostgresUserRepository) GetUserByAuthID(ctx context.Context, authID str
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("user with auth_id '%s' not found", authID)
+			return nil, apperrors.NotFound(apperrors.CodeUserNotFound, "user with auth_id '%s' not found", authID)
 		}
 		return nil, fmt.Errorf("failed to get user by auth ID: %w", err)
 	}
@@ -189,7 +186,7 @@ func (r *PostgresUserRepository) GetUserByEmail(ctx context.Context, email strin
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("user with email '%s' not found", email)
+			return nil, apperrors.NotFound(apperrors.CodeUserNotFound, "user with email '%s' not found", email)
 		}
 		return nil, fmt.Errorf("failed to get user by email: %w", err)
 	}
@@ -222,7 +219,7 @@ func (r *PostgresUserRepository) UpdateUser(ctx context.Context, user *models.DB
 	if err != nil {
 		var pqErr *pq.Error
 		if errors.As(err, &pqErr) && pqErr.Code == "23505" { // unique_violation
-			return nil, fmt.Errorf("user with email '%s', username '%s', or auth_id '%s' already exists", user.Email, user.Username, user.AuthID)
+			return nil, apperrors.Conflict(apperrors.CodeUserExists, "user with email '%s', username '%s', or auth_id '%s' already exists", user.Email, user.Username, user.AuthID)
 		}
 		return nil, fmt.Errorf("failed to update user: %w", err)
 	}
@@ -233,7 +230,7 @@ func (r *PostgresUserRepository) UpdateUser(ctx context.Context, user *models.DB
 	}
 
 	if rowsAffected == 0 {
-		return nil, fmt.Errorf("user with ID '%s' does not exist", user.UserID)
+		return nil, apperrors.NotFound(apperrors.CodeUserNotFound, "user with ID '%s' does not exist", user.UserID)
 	}
 
 	return user, nil
@@ -254,7 +251,7 @@ func (r *PostgresUserRepository) DeleteUser(ctx context.Context, userID string)
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("user with ID '%s' does not exist", userID)
+		return apperrors.NotFound(apperrors.CodeUserNotFound, "user with ID '%s' does not exist", userID)
 	}
 
 	return nil
@@ -343,111 +340,69 @@ func (r *PostgresUserRepository) ListUsers(ctx context.Context, filter *ListUser
 	return users, total, nil
 }
 
-// UserExists checks if a user exists by user ID
-func (r *PostgresUserRepository) UserExists(ctx context.Context, userID string) (bool, error) {
-	query := fmt.Sprintf(`SELECT 1 FROM %s WHERE user_id = $1`, r.tableName)
-
-	var exists int
-	err := r.db.QueryRowContext(ctx, query, userID).Scan(&exists)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return false, nil
-		}
-		return false, fmt.Errorf("failed to check if user exists: %w", err)
-	}
-
-	return true, nil
-}
-
-// HasProjectAccess checks if a user has access to a project
-func (r *PostgresUserRepository) HasProjectAccess(ctx context.Context, userID, _ /* projectID */, accessType string) (bool, error) {
-	// For now, we'll implement a simple role-based access control
-	// In the future, this could be extended to support project-specific permissions
-	// TODO: Use projectID parameter when implementing project-specific access control
+// StreamUsers passes the users created within timeRange to fn, oldest first, reading them from the database as fn
+// consumes them
+func (r *PostgresUserRepository) StreamUsers(ctx context.Context, timeRange TimeRange, fn func(*models.DBUser) error) error {
+	where, args := timeRange.whereClause("created_at")
+	query := fmt.Sprintf(`
+		SELECT user_id, auth_id, email, username, first_name, last_name, role, status, created_at, updated_at
+		FROM %s
+		%s
+		ORDER BY created_at, user_id
+	`, r.tableName, where)
 
-	user, err := r.GetUser(ctx, userID)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return false, err
-	}
-
-	if user == nil {
-		return false, nil
-	}
-
-	// Owners and Admins have access to everything
-	if user.Role == models.RoleOwner || user.Role == models.RoleAdmin {
-		return true, nil
+		return fmt.Errorf("failed to stream users: %w", err)
 	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close user rows", "error", closeErr)
+		}
+	}()
 
-	// Developers have read/write access to projects
-	if user.Role == models.RoleDeveloper && (accessType == "read" || accessType == "write") {
-		return true, nil
+	for rows.Next() {
+		user := &models.DBUser{}
+		err := rows.Scan(
+			&user.UserID,
+			&user.AuthID,
+			&user.Email,
+			&user.Username,
+			&user.FirstName,
+			&user.LastName,
+			&user.Role,
+			&user.Status,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to scan user: %w", err)
+		}
+		if err := fn(user); err != nil {
+			return err
+		}
 	}
-
-	// Viewers have read-only access
-	if user.Role == models.RoleViewer && accessType == "read" {
-		return true, nil
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate users: %w", err)
 	}
 
-	return false, nil
-}
-
-// GrantProjectAccess grants a user access to a project (placeholder for future implementation)
-func (r *PostgresUserRepository) GrantProjectAccess(_ context.Context, _, _, _ string) error {
-	// This is a placeholder implementation
-	// In the future, this could create entries in a user_project_access table
-	return fmt.Errorf("project access management not yet implemented")
-}
-
-// RevokeProjectAccess revokes a user's access to a project (placeholder for future implementation)
-func (r *PostgresUserRepository) RevokeProjectAccess(_ context.Context, _, _ string) error {
-	// This is a placeholder implementation
-	// In the future, this could remove entries from a user_project_access table
-	return fmt.Errorf("project access management not yet implemented")
+	return nil
 }
 
-// CreateTable creates the users table with appropriate indexes
-func (r *PostgresUserRepository) CreateTable(ctx context.Context) error {
-	query := fmt.Sprintf(`
-		CREATE TABLE IF NOT EXISTS %s (
-			user_id VARCHAR(255) PRIMARY KEY,
-			auth_id VARCHAR(255) UNIQUE NOT NULL,
-			email VARCHAR(255) UNIQUE NOT NULL,
-			username VARCHAR(255) UNIQUE NOT NULL,
-			first_name VARCHAR(255),
-			last_name VARCHAR(255),
-			role VARCHAR(50) NOT NULL DEFAULT 'developer',
-			status VARCHAR(50) NOT NULL DEFAULT 'active',
-			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
-			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
-			CONSTRAINT valid_role CHECK (role IN ('owner', 'admin', 'developer', 'viewer')),
-			CONSTRAINT valid_status CHECK (status IN ('active', 'inactive', 'pending', 'suspended'))
-		)
-	`, r.tableName)
+// UserExists checks if a user exists by user ID
+func (r *PostgresUserRepository) UserExists(ctx context.Context, userID string) (bool, error) {
+	query := fmt.Sprintf(`SELECT 1 FROM %s WHERE user_id = $1`, r.tableName)
 
-	_, err := r.db.ExecContext(ctx, query)
+	var exists int
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(&exists)
 	if err != nil {
-		return fmt.Errorf("failed to create users table: %w", err)
-	}
-
-	// Create indexes
-	indexes := []string{
-		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_auth_id ON %s (auth_id)", r.tableName, r.tableName),
-		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_email ON %s (email)", r.tableName, r.tableName),
-		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_username ON %s (username)", r.tableName, r.tableName),
-		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_role ON %s (role)", r.tableName, r.tableName),
-		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_status ON %s (status)", r.tableName, r.tableName),
-		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_created_at ON %s (created_at)", r.tableName, r.tableName),
-	}
-
-	for _, indexQuery := range indexes {
-		_, err := r.db.ExecContext(ctx, indexQuery)
-		if err != nil {
-			return fmt.Errorf("failed to create index: %w", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
 		}
+		return false, fmt.Errorf("failed to check if user exists: %w", err)
 	}
 
-	return nil
+	return true, nil
 }
 
 // generateUserID generates a unique user ID (simplified implementation)
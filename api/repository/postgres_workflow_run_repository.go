@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/workflow"
+)
+
+// workflowRunColumns lists the columns scanWorkflowRun expects, in order
+const workflowRunColumns = "run_id, workflow, status, steps, run_values, error, created_at, updated_at"
+
+// PostgresWorkflowRunRepository implements WorkflowRunRepository using PostgreSQL
+type PostgresWorkflowRunRepository struct {
+	db        *sql.DB
+	tableName string
+}
+
+// NewPostgresWorkflowRunRepository creates a new PostgreSQL workflow run repository
+func NewPostgresWorkflowRunRepository(config PostgresConfig, tableName string) (WorkflowRunRepository, error) {
+	if tableName == "" {
+		tableName = conf.DefaultWorkflowRunsTableName
+	}
+
+	// Build connection string
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	repo := &PostgresWorkflowRunRepository{
+		db:        db,
+		tableName: tableName,
+	}
+
+	return repo, nil
+}
+
+// NewPostgresWorkflowRunRepositoryWithDB creates a new PostgreSQL workflow run repository with an existing DB connection
+func NewPostgresWorkflowRunRepositoryWithDB(db *sql.DB, tableName string) WorkflowRunRepository {
+	if tableName == "" {
+		tableName = conf.DefaultWorkflowRunsTableName
+	}
+
+	return &PostgresWorkflowRunRepository{
+		db:        db,
+		tableName: tableName,
+	}
+}
+
+// GetRun retrieves a run
+func (r *PostgresWorkflowRunRepository) GetRun(ctx context.Context, runID string) (*workflow.Run, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE run_id = $1`, workflowRunColumns, r.tableName)
+
+	run, err := scanWorkflowRun(r.db.QueryRowContext(ctx, query, runID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get workflow run: %w", err)
+	}
+
+	return run, nil
+}
+
+// SaveRun creates or replaces a run
+func (r *PostgresWorkflowRunRepository) SaveRun(ctx context.Context, run *workflow.Run) error {
+	stepsJSON, err := json.Marshal(run.Steps)
+	if err != nil {
+		return fmt.Errorf("failed to marshal steps: %w", err)
+	}
+	valuesJSON, err := json.Marshal(run.Values)
+	if err != nil {
+		return fmt.Errorf("failed to marshal values: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (%s)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (run_id) DO UPDATE SET
+			status = EXCLUDED.status,
+			steps = EXCLUDED.steps,
+			run_values = EXCLUDED.run_values,
+			error = EXCLUDED.error,
+			updated_at = EXCLUDED.updated_at
+	`, r.tableName, workflowRunColumns)
+
+	_, err = r.db.ExecContext(ctx, query,
+		run.RunID, run.Workflow, run.Status, stepsJSON, valuesJSON, run.Error, run.CreatedAt, run.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save workflow run: %w", err)
+	}
+
+	return nil
+}
+
+// ListRuns returns the runs of a workflow in the given status, or in any status when it's empty, oldest first
+func (r *PostgresWorkflowRunRepository) ListRuns(ctx context.Context, workflowName string, status workflow.RunStatus) ([]*workflow.Run, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE workflow = $1`, workflowRunColumns, r.tableName)
+	args := []any{workflowName}
+	if status != "" {
+		query += ` AND status = $2`
+		args = append(args, status)
+	}
+	query += ` ORDER BY created_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workflow runs: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close workflow run rows", "error", closeErr)
+		}
+	}()
+
+	runs := []*workflow.Run{}
+	for rows.Next() {
+		run, err := scanWorkflowRun(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan workflow run: %w", err)
+		}
+		runs = append(runs, run)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate workflow runs: %w", err)
+	}
+
+	return runs, nil
+}
+
+// scanWorkflowRun scans a single row selected with workflowRunColumns
+func scanWorkflowRun(row rowScanner) (*workflow.Run, error) {
+	var run workflow.Run
+	var stepsJSON, valuesJSON []byte
+
+	err := row.Scan(
+		&run.RunID, &run.Workflow, &run.Status, &stepsJSON, &valuesJSON, &run.Error, &run.CreatedAt, &run.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(stepsJSON, &run.Steps); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal steps JSON for workflow run %s: %w", run.RunID, err)
+	}
+	if err := json.Unmarshal(valuesJSON, &run.Values); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal values JSON for workflow run %s: %w", run.RunID, err)
+	}
+
+	return &run, nil
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/workflow"
+)
+
+func TestPostgresWorkflowRunRepository_SaveRun(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresWorkflowRunRepositoryWithDB(db, "workflow_runs")
+	createdAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	mock.ExpectExec(`INSERT INTO workflow_runs .+ ON CONFLICT \(run_id\) DO UPDATE`).
+		WithArgs("task-1", "task_execution", workflow.RunStatusRunning,
+			[]byte(`[{"name":"prepare","status":"completed","attempts":1}]`), []byte(`{"rag_id":"kb-1"}`), "",
+			createdAt, createdAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.SaveRun(context.Background(), &workflow.Run{
+		RunID:     "task-1",
+		Workflow:  "task_execution",
+		Status:    workflow.RunStatusRunning,
+		Steps:     []workflow.StepProgress{{Name: "prepare", Status: workflow.StepStatusCompleted, Attempts: 1}},
+		Values:    map[string]any{"rag_id": "kb-1"},
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresWorkflowRunRepository_ListRuns(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresWorkflowRunRepositoryWithDB(db, "workflow_runs")
+	createdAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	columns := []string{"run_id", "workflow", "status", "steps", "run_values", "error", "created_at", "updated_at"}
+	mock.ExpectQuery(`SELECT .+ FROM workflow_runs WHERE workflow = \$1 AND status = \$2 ORDER BY created_at ASC`).
+		WithArgs("task_execution", workflow.RunStatusRunning).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			"task-1", "task_execution", "running", []byte(`[{"name":"prepare","status":"completed","attempts":1}]`),
+			[]byte(`{"rag_id":"kb-1"}`), "", createdAt, createdAt,
+		))
+
+	runs, err := repo.ListRuns(context.Background(), "task_execution", workflow.RunStatusRunning)
+
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, workflow.StepStatusCompleted, runs[0].Step("prepare").Status)
+	assert.Equal(t, "kb-1", runs[0].String("rag_id"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresWorkflowRunRepository_GetRun_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresWorkflowRunRepositoryWithDB(db, "workflow_runs")
+
+	mock.ExpectQuery(`SELECT .+ FROM workflow_runs WHERE run_id = \$1`).
+		WithArgs("task-1").
+		WillReturnRows(sqlmock.NewRows([]string{"run_id"}))
+
+	run, err := repo.GetRun(context.Background(), "task-1")
+
+	require.NoError(t, err)
+	assert.Nil(t, run)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	conf "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// workspaceColumns lists the workspace columns in the order expected by scanWorkspace
+const workspaceColumns = `workspace_id, task_id, codebase_id, commit_sha, host, path, size_bytes, status, created_at, last_used_at`
+
+// PostgresWorkspaceRepository implements WorkspaceRepository using PostgreSQL
+type PostgresWorkspaceRepository struct {
+	db        *sql.DB
+	tableName string
+}
+
+// NewPostgresWorkspaceRepository creates a new PostgreSQL workspace repository
+func NewPostgresWorkspaceRepository(config PostgresConfig, tableName string) (WorkspaceRepository, error) {
+	if tableName == "" {
+		tableName = conf.DefaultWorkspacesTableName
+	}
+
+	// Build connection string
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	repo := &PostgresWorkspaceRepository{
+		db:        db,
+		tableName: tableName,
+	}
+
+	return repo, nil
+}
+
+// NewPostgresWorkspaceRepositoryWithDB creates a new PostgreSQL workspace repository with an existing DB connection
+func NewPostgresWorkspaceRepositoryWithDB(db *sql.DB, tableName string) WorkspaceRepository {
+	if tableName == "" {
+		tableName = conf.DefaultWorkspacesTableName
+	}
+
+	return &PostgresWorkspaceRepository{
+		db:        db,
+		tableName: tableName,
+	}
+}
+
+// CreateWorkspace records a workspace
+func (r *PostgresWorkspaceRepository) CreateWorkspace(ctx context.Context, workspace *models.Workspace) error {
+	query := fmt.Sprintf(`INSERT INTO %s (%s) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`, r.tableName, workspaceColumns)
+
+	_, err := r.db.ExecContext(ctx, query,
+		workspace.WorkspaceID, workspace.TaskID, workspace.CodebaseID, workspace.CommitSHA, workspace.Host,
+		workspace.Path, workspace.SizeBytes, workspace.Status, workspace.CreatedAt, workspace.LastUsedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create workspace: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimIdleWorkspace claims the most recently used matching idle workspace in one statement, skipping rows another
+// claim holds locked so two tasks never share a workspace
+func (r *PostgresWorkspaceRepository) ClaimIdleWorkspace(ctx context.Context, host, codebaseID, commitSHA, taskID string) (*models.Workspace, error) {
+	query := fmt.Sprintf(`
+		UPDATE %s SET status = $5, task_id = $6, last_used_at = $7
+		WHERE workspace_id = (
+			SELECT workspace_id FROM %s
+			WHERE host = $1 AND codebase_id = $2 AND commit_sha = $3 AND status = $4
+			ORDER BY last_used_at DESC
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING %s
+	`, r.tableName, r.tableName, workspaceColumns)
+
+	row := r.db.QueryRowContext(ctx, query,
+		host, codebaseID, commitSHA, models.WorkspaceStatusIdle, models.WorkspaceStatusInUse, taskID, time.Now(),
+	)
+	workspace, err := scanWorkspace(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to claim workspace: %w", err)
+	}
+
+	return workspace, nil
+}
+
+// ReleaseWorkspace marks a workspace idle and records its size
+func (r *PostgresWorkspaceRepository) ReleaseWorkspace(ctx context.Context, workspaceID string, sizeBytes int64) error {
+	query := fmt.Sprintf(`UPDATE %s SET status = $2, size_bytes = $3, last_used_at = $4 WHERE workspace_id = $1`, r.tableName)
+
+	if _, err := r.db.ExecContext(ctx, query, workspaceID, models.WorkspaceStatusIdle, sizeBytes, time.Now()); err != nil {
+		return fmt.Errorf("failed to release workspace: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteWorkspace deletes a workspace
+func (r *PostgresWorkspaceRepository) DeleteWorkspace(ctx context.Context, workspaceID string) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE workspace_id = $1`, r.tableName)
+
+	if _, err := r.db.ExecContext(ctx, query, workspaceID); err != nil {
+		return fmt.Errorf("failed to delete workspace: %w", err)
+	}
+
+	return nil
+}
+
+// ListWorkspaces lists the workspaces of a host from least to most recently used
+func (r *PostgresWorkspaceRepository) ListWorkspaces(ctx context.Context, host string) ([]models.Workspace, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE host = $1 ORDER BY last_used_at, workspace_id`, workspaceColumns, r.tableName)
+
+	rows, err := r.db.QueryContext(ctx, query, host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close workspace rows", "error", closeErr)
+		}
+	}()
+
+	workspaces := []models.Workspace{}
+	for rows.Next() {
+		workspace, err := scanWorkspace(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan workspace: %w", err)
+		}
+		workspaces = append(workspaces, *workspace)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate workspaces: %w", err)
+	}
+
+	return workspaces, nil
+}
+
+// scanWorkspace scans a single row selected with workspaceColumns
+func scanWorkspace(row rowScanner) (*models.Workspace, error) {
+	var workspace models.Workspace
+	err := row.Scan(
+		&workspace.WorkspaceID, &workspace.TaskID, &workspace.CodebaseID, &workspace.CommitSHA, &workspace.Host,
+		&workspace.Path, &workspace.SizeBytes, &workspace.Status, &workspace.CreatedAt, &workspace.LastUsedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &workspace, nil
+}
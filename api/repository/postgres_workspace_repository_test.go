@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+func TestPostgresWorkspaceRepository_ClaimIdleWorkspace(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresWorkspaceRepositoryWithDB(db, "workspaces")
+	usedAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	columns := []string{"workspace_id", "task_id", "codebase_id", "commit_sha", "host", "path", "size_bytes", "status", "created_at", "last_used_at"}
+	mock.ExpectQuery(`UPDATE workspaces SET status = \$5, task_id = \$6, .+ FOR UPDATE SKIP LOCKED`).
+		WithArgs("runner-1", "cb-1", "abc123", models.WorkspaceStatusIdle, models.WorkspaceStatusInUse, "task-1", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("ws-1", "task-1", "cb-1", "abc123", "runner-1", "/workspaces/ws-1", 1024, "in_use", usedAt, usedAt))
+
+	workspace, err := repo.ClaimIdleWorkspace(context.Background(), "runner-1", "cb-1", "abc123", "task-1")
+
+	require.NoError(t, err)
+	require.NotNil(t, workspace)
+	assert.Equal(t, "ws-1", workspace.WorkspaceID)
+	assert.Equal(t, models.WorkspaceStatusInUse, workspace.Status)
+	assert.Equal(t, int64(1024), workspace.SizeBytes)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresWorkspaceRepository_ClaimIdleWorkspace_NoneIdle(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	repo := NewPostgresWorkspaceRepositoryWithDB(db, "workspaces")
+
+	mock.ExpectQuery(`UPDATE workspaces SET status`).
+		WillReturnRows(sqlmock.NewRows([]string{"workspace_id"}))
+
+	workspace, err := repo.ClaimIdleWorkspace(context.Background(), "runner-1", "cb-1", "abc123", "task-1")
+
+	require.NoError(t, err)
+	assert.Nil(t, workspace)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
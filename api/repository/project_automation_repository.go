@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// ProjectAutomationRepository defines the interface for project schedule and webhook data operations
+//
+//go:generate mockgen -destination=./mocks/mock_project_automation_repository.go -mock_names=ProjectAutomationRepository=MockProjectAutomationRepository -package=mocks . ProjectAutomationRepository
+type ProjectAutomationRepository interface {
+	// GetAutomation retrieves the automation settings of a project, returning nil if none are stored
+	GetAutomation(ctx context.Context, projectID string) (*models.ProjectAutomation, error)
+
+	// SaveAutomation creates or replaces the automation settings of a project
+	SaveAutomation(ctx context.Context, automation *models.ProjectAutomation) error
+
+	// DeleteAutomation deletes the automation settings of a project
+	DeleteAutomation(ctx context.Context, projectID string) error
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
 )
 
 // ProjectRecord represents the project data stored in the database
@@ -17,21 +18,44 @@ type ProjectRecord struct {
 	Status      string            `json:"status" db:"status"`
 	CreatedAt   time.Time         `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time         `json:"updated_at" db:"updated_at"`
+	Version     int64             `json:"version" db:"version"`
 	Tags        map[string]string `json:"tags,omitempty" db:"tags"`
 	Metadata    map[string]string `json:"metadata,omitempty" db:"metadata"`
+	VectorStore string            `json:"vector_store,omitempty" db:"vector_store"` // Backend of the project's vectors, the configured default when empty
+
+	// Embedding of the project's Bedrock knowledge bases, the default model when empty
+	EmbeddingModel      string `json:"embedding_model,omitempty" db:"embedding_model"`
+	EmbeddingDimensions int32  `json:"embedding_dimensions,omitempty" db:"embedding_dimensions"`
+}
+
+// Embedding returns the embedding of the project's Bedrock knowledge bases, zero when they embed with the default
+// model
+func (r *ProjectRecord) Embedding() config.Embedding {
+	return config.Embedding{Model: r.EmbeddingModel, Dimensions: r.EmbeddingDimensions}
+}
+
+// SetEmbedding records the embedding of the project's Bedrock knowledge bases
+func (r *ProjectRecord) SetEmbedding(embedding config.Embedding) {
+	r.EmbeddingModel = embedding.Model
+	r.EmbeddingDimensions = embedding.Dimensions
 }
 
 // ToGetProjectResponse converts ProjectRecord to GetProjectResponse
 func (r *ProjectRecord) ToGetProjectResponse() *models.GetProjectResponse {
 	return &models.GetProjectResponse{
-		ProjectID:   r.ProjectID,
-		Name:        r.Name,
-		Description: r.Description,
-		Language:    r.Language,
-		CreatedAt:   r.CreatedAt.UTC().Format(time.RFC3339),
-		UpdatedAt:   r.UpdatedAt.UTC().Format(time.RFC3339),
-		Tags:        r.Tags,
-		Metadata:    r.Metadata,
+		ProjectID:           r.ProjectID,
+		Name:                r.Name,
+		Description:         r.Description,
+		Language:            r.Language,
+		Status:              models.ProjectStatus(r.Status),
+		CreatedAt:           r.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:           r.UpdatedAt.UTC().Format(time.RFC3339),
+		Version:             r.Version,
+		Tags:                r.Tags,
+		Metadata:            r.Metadata,
+		VectorStore:         r.VectorStore,
+		EmbeddingModel:      r.EmbeddingModel,
+		EmbeddingDimensions: r.EmbeddingDimensions,
 	}
 }
 
@@ -40,11 +64,17 @@ func (r *ProjectRecord) ToProjectSummary() models.ProjectSummary {
 	return models.ProjectSummary{
 		ProjectID: r.ProjectID,
 		Name:      r.Name,
+		Status:    models.ProjectStatus(r.Status),
 		CreatedAt: r.CreatedAt.UTC().Format(time.RFC3339),
 		Tags:      r.Tags,
 	}
 }
 
+// Archived reports whether the project is archived
+func (r *ProjectRecord) Archived() bool {
+	return r.Status == string(models.ProjectStatusArchived)
+}
+
 // ProjectRepository defines the interface for project data operations
 //
 //go:generate mockgen -destination=./mocks/mock_project_repository.go -mock_names=ProjectRepository=MockProjectRepository -package=mocks . ProjectRepository
@@ -55,7 +85,8 @@ type ProjectRepository interface {
 	// GetProject retrieves a project by ID
 	GetProject(ctx context.Context, projectID string) (*ProjectRecord, error)
 
-	// UpdateProject updates an existing project record
+	// UpdateProject updates an existing project record if its stored version still equals project.Version,
+	// then increments project.Version. A stale version fails with apperrors.ErrPreconditionFailed.
 	UpdateProject(ctx context.Context, project *ProjectRecord) error
 
 	// DeleteProject deletes a project by ID
@@ -76,4 +107,6 @@ type ListProjectsOptions struct {
 	MaxResults *int
 	// Tag filter - projects must match all provided tags
 	TagFilter map[string]string
+	// IncludeArchived lists archived projects too
+	IncludeArchived bool
 }
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// ProjectTemplateRepository defines the interface for custom project template data operations
+//
+//go:generate mockgen -destination=./mocks/mock_project_template_repository.go -mock_names=ProjectTemplateRepository=MockProjectTemplateRepository -package=mocks . ProjectTemplateRepository
+type ProjectTemplateRepository interface {
+	// CreateTemplate stores a new custom project template
+	CreateTemplate(ctx context.Context, template *models.ProjectTemplate) error
+
+	// GetTemplate retrieves a custom project template by ID, returning nil if it does not exist
+	GetTemplate(ctx context.Context, templateID string) (*models.ProjectTemplate, error)
+
+	// ListTemplates lists all custom project templates
+	ListTemplates(ctx context.Context) ([]*models.ProjectTemplate, error)
+
+	// DeleteTemplate deletes a custom project template by ID
+	DeleteTemplate(ctx context.Context, templateID string) error
+}
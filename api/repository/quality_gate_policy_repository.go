@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// QualityGatePolicyRepository defines the interface for per-project severity rules and quality gates data operations
+//
+//go:generate mockgen -destination=./mocks/mock_quality_gate_policy_repository.go -mock_names=QualityGatePolicyRepository=MockQualityGatePolicyRepository -package=mocks . QualityGatePolicyRepository
+type QualityGatePolicyRepository interface {
+	// GetPolicy retrieves the quality gate policy of a project, returning nil if none is stored
+	GetPolicy(ctx context.Context, projectID string) (*models.QualityGatePolicy, error)
+
+	// SavePolicy creates or replaces the quality gate policy of a project
+	SavePolicy(ctx context.Context, policy *models.QualityGatePolicy) error
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// RedactionAuditRepository defines the interface for the redaction counts recorded per agent sync
+//
+//go:generate mockgen -destination=./mocks/mock_redaction_audit_repository.go -mock_names=RedactionAuditRepository=MockRedactionAuditRepository -package=mocks . RedactionAuditRepository
+type RedactionAuditRepository interface {
+	// CreateAudit stores the redaction counts of a sync
+	CreateAudit(ctx context.Context, audit *models.RedactionAudit) error
+
+	// ListAudits lists the most recent audits of a project, newest first
+	ListAudits(ctx context.Context, projectID string, limit int) ([]models.RedactionAudit, error)
+
+	// StreamAudits passes every audit of a project to fn, newest first, without loading them all in memory. An error
+	// returned by fn stops the stream.
+	StreamAudits(ctx context.Context, projectID string, fn func(models.RedactionAudit) error) error
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// RedactionPolicyRepository defines the interface for per-project redaction policy data operations
+//
+//go:generate mockgen -destination=./mocks/mock_redaction_policy_repository.go -mock_names=RedactionPolicyRepository=MockRedactionPolicyRepository -package=mocks . RedactionPolicyRepository
+type RedactionPolicyRepository interface {
+	// GetPolicy retrieves the redaction policy of a project, returning nil if none is stored
+	GetPolicy(ctx context.Context, projectID string) (*models.RedactionPolicy, error)
+
+	// SavePolicy creates or replaces the redaction policy of a project
+	SavePolicy(ctx context.Context, policy *models.RedactionPolicy) error
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// RoleRepository defines the interface for role, permission and project member data operations
+//
+//go:generate mockgen -destination=./mocks/mock_role_repository.go -mock_names=RoleRepository=MockRoleRepository -package=mocks . RoleRepository
+type RoleRepository interface {
+	// ListPermissions lists the permissions roles can grant ordered by name
+	ListPermissions(ctx context.Context) ([]models.PermissionDefinition, error)
+
+	// CreateRole creates a role with its permissions, failing with a conflict if it already exists
+	CreateRole(ctx context.Context, role *models.Role) error
+
+	// GetRole retrieves a role with its permissions, returning nil if it doesn't exist
+	GetRole(ctx context.Context, name models.UserRole) (*models.Role, error)
+
+	// UpdateRole replaces the description and permissions of a role
+	UpdateRole(ctx context.Context, role *models.Role) error
+
+	// DeleteRole deletes a role, failing with a conflict while project members hold it
+	DeleteRole(ctx context.Context, name models.UserRole) error
+
+	// ListRoles lists the roles with their permissions ordered by name
+	ListRoles(ctx context.Context) ([]models.Role, error)
+
+	// SetProjectMember adds a member to a project or replaces the role of a member, failing with a conflict when it
+	// would give the project a second owner
+	SetProjectMember(ctx context.Context, member *models.ProjectMember) error
+
+	// GetProjectMember retrieves a member of a project, returning nil if the user isn't a member
+	GetProjectMember(ctx context.Context, projectID, userID string) (*models.ProjectMember, error)
+
+	// DeleteProjectMember removes a member from a project
+	DeleteProjectMember(ctx context.Context, projectID, userID string) error
+
+	// ListProjectMembers lists the members of a project ordered by user
+	ListProjectMembers(ctx context.Context, projectID string) ([]models.ProjectMember, error)
+
+	// TransferProjectOwnership makes a user the owner of a project in one transaction, demoting its previous owner to
+	// the admin role
+	TransferProjectOwnership(ctx context.Context, projectID, userID string, transferredAt time.Time) error
+
+	// StreamProjectMembers passes the members of every project whose role last changed within timeRange to fn, oldest
+	// change first, without loading them all in memory. An error returned by fn stops the stream.
+	StreamProjectMembers(ctx context.Context, timeRange TimeRange, fn func(models.ProjectMember) error) error
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// ScanFindingRepository defines the interface for the ingestion scan findings of codebases
+//
+//go:generate mockgen -destination=./mocks/mock_scan_finding_repository.go -mock_names=ScanFindingRepository=MockScanFindingRepository -package=mocks . ScanFindingRepository
+type ScanFindingRepository interface {
+	// ReplaceFindings replaces the findings of a codebase with those of its latest scan
+	ReplaceFindings(ctx context.Context, codebaseID string, findings []models.ScanFinding) error
+
+	// ListFindings lists the findings of a codebase by file and line
+	ListFindings(ctx context.Context, codebaseID string) ([]models.ScanFinding, error)
+}
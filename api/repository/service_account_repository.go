@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// ServiceAccountRepository defines the interface for service accounts and their tokens
+//
+//go:generate mockgen -destination=./mocks/mock_service_account_repository.go -mock_names=ServiceAccountRepository=MockServiceAccountRepository -package=mocks . ServiceAccountRepository
+type ServiceAccountRepository interface {
+	// CreateAccount creates a service account, failing with a conflict when its name is taken
+	CreateAccount(ctx context.Context, account *models.ServiceAccount) error
+
+	// GetAccount gets a service account by ID
+	GetAccount(ctx context.Context, serviceAccountID string) (*models.ServiceAccount, error)
+
+	// ListAccounts lists the service accounts ordered by name
+	ListAccounts(ctx context.Context) ([]models.ServiceAccount, error)
+
+	// DeleteAccount deletes a service account along with its tokens
+	DeleteAccount(ctx context.Context, serviceAccountID string) error
+
+	// CreateToken creates a token of a service account
+	CreateToken(ctx context.Context, token *models.ServiceAccountToken) error
+
+	// ListTokens lists the tokens of a service account, newest first
+	ListTokens(ctx context.Context, serviceAccountID string) ([]models.ServiceAccountToken, error)
+
+	// GetTokenByHash gets the token with a SHA-256 hash
+	GetTokenByHash(ctx context.Context, tokenHash string) (*models.ServiceAccountToken, error)
+
+	// RevokeToken revokes a token of a service account that isn't revoked yet
+	RevokeToken(ctx context.Context, serviceAccountID, tokenID string, revokedAt time.Time) error
+
+	// TouchToken records when a token last authenticated a request
+	TouchToken(ctx context.Context, tokenID string, usedAt time.Time) error
+}
@@ -0,0 +1,414 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// The SQLite repositories store the resources the core features need in a single database file, for deployments
+// running the API as a single binary. Timestamps are stored as Unix nanoseconds, so they order and compare
+// as integers, and maps as JSON text, queried with the JSON1 functions. Write transactions take the database lock
+// when they begin, so a transaction reading a record before updating it never races another writer.
+
+// sqliteMigrations is the schema of the SQLite repositories
+var sqliteMigrations = []Migration{
+	{
+		Version:     1,
+		Description: "create projects, codebases, tasks, users and agents",
+		SQL: `
+			CREATE TABLE projects (
+				project_id TEXT PRIMARY KEY,
+				name TEXT NOT NULL,
+				description TEXT,
+				language TEXT,
+				status TEXT NOT NULL DEFAULT 'active',
+				created_at INTEGER NOT NULL,
+				updated_at INTEGER NOT NULL,
+				version INTEGER NOT NULL DEFAULT 1,
+				tags TEXT,
+				metadata TEXT,
+				vector_store TEXT NOT NULL DEFAULT '',
+				embedding_model TEXT NOT NULL DEFAULT '',
+				embedding_dimensions INTEGER NOT NULL DEFAULT 0
+			);
+
+			CREATE TABLE codebases (
+				codebase_id TEXT PRIMARY KEY,
+				project_id TEXT NOT NULL REFERENCES projects (project_id) ON DELETE CASCADE,
+				name TEXT NOT NULL,
+				provider TEXT NOT NULL,
+				url TEXT NOT NULL,
+				config_id TEXT NOT NULL,
+				status TEXT NOT NULL DEFAULT 'active',
+				last_sync_at INTEGER,
+				created_at INTEGER NOT NULL,
+				updated_at INTEGER NOT NULL,
+				metadata TEXT,
+				tags TEXT,
+				ingestion_error TEXT,
+				expires_at INTEGER,
+				checkout TEXT
+			);
+			CREATE INDEX idx_codebases_project_id ON codebases (project_id);
+			CREATE INDEX idx_codebases_created_at ON codebases (created_at);
+
+			CREATE TABLE tasks (
+				task_id TEXT PRIMARY KEY,
+				project_id TEXT NOT NULL,
+				batch_id TEXT,
+				campaign_id TEXT,
+				created_by TEXT,
+				agent_id TEXT NOT NULL,
+				codebase_id TEXT,
+				branch TEXT,
+				commit_sha TEXT,
+				type TEXT NOT NULL,
+				analysis_mode TEXT,
+				status TEXT NOT NULL DEFAULT 'pending',
+				priority TEXT NOT NULL DEFAULT 'normal',
+				title TEXT NOT NULL,
+				description TEXT NOT NULL,
+				input TEXT,
+				output TEXT,
+				error_message TEXT,
+				created_at INTEGER NOT NULL,
+				updated_at INTEGER NOT NULL,
+				completed_at INTEGER,
+				queued_at INTEGER,
+				heartbeat_at INTEGER,
+				progress INTEGER NOT NULL DEFAULT 0,
+				progress_step TEXT,
+				experiment_id TEXT,
+				experiment_arm TEXT,
+				approved INTEGER,
+				metadata TEXT,
+				tags TEXT
+			);
+			CREATE INDEX idx_tasks_project_id ON tasks (project_id, created_at);
+			CREATE INDEX idx_tasks_status ON tasks (status);
+			CREATE INDEX idx_tasks_batch_id ON tasks (batch_id);
+			CREATE INDEX idx_tasks_campaign_id ON tasks (campaign_id);
+
+			CREATE TABLE users (
+				user_id TEXT PRIMARY KEY,
+				auth_id TEXT UNIQUE NOT NULL,
+				email TEXT UNIQUE NOT NULL,
+				username TEXT UNIQUE NOT NULL,
+				first_name TEXT,
+				last_name TEXT,
+				role TEXT NOT NULL DEFAULT 'developer',
+				status TEXT NOT NULL DEFAULT 'active',
+				created_at INTEGER NOT NULL,
+				updated_at INTEGER NOT NULL
+			);
+
+			CREATE TABLE agents (
+				agent_id TEXT PRIMARY KEY,
+				project_id TEXT,
+				agent_version TEXT NOT NULL,
+				knowledge_base_id TEXT NOT NULL,
+				vector_store_id TEXT NOT NULL,
+				repository_url TEXT NOT NULL,
+				branch TEXT NOT NULL DEFAULT '',
+				agent_name TEXT NOT NULL DEFAULT '',
+				status TEXT NOT NULL,
+				region TEXT NOT NULL DEFAULT '',
+				embedding_model TEXT NOT NULL DEFAULT '',
+				embedding_dimensions INTEGER NOT NULL DEFAULT 0,
+				created_at INTEGER NOT NULL,
+				updated_at INTEGER NOT NULL
+			);
+		`,
+	},
+	{
+		Version:     2,
+		Description: "create roles, audit events, codebase configurations, tag keys, redaction policies, workspaces, workflow runs, client tokens and MFA recovery codes",
+		SQL: `
+			CREATE TABLE permissions (
+				name TEXT PRIMARY KEY,
+				description TEXT NOT NULL DEFAULT ''
+			);
+
+			CREATE TABLE roles (
+				name TEXT PRIMARY KEY,
+				description TEXT NOT NULL DEFAULT '',
+				built_in INTEGER NOT NULL DEFAULT 0,
+				created_at INTEGER NOT NULL,
+				updated_at INTEGER NOT NULL
+			);
+
+			CREATE TABLE role_permissions (
+				role TEXT NOT NULL REFERENCES roles (name) ON DELETE CASCADE,
+				permission TEXT NOT NULL REFERENCES permissions (name) ON DELETE CASCADE,
+				PRIMARY KEY (role, permission)
+			);
+
+			CREATE TABLE project_members (
+				project_id TEXT NOT NULL,
+				user_id TEXT NOT NULL,
+				role TEXT NOT NULL REFERENCES roles (name),
+				created_at INTEGER NOT NULL,
+				updated_at INTEGER NOT NULL,
+				PRIMARY KEY (project_id, user_id)
+			);
+			CREATE INDEX idx_project_members_role ON project_members (role);
+			CREATE UNIQUE INDEX idx_project_members_owner ON project_members (project_id) WHERE role = 'owner';
+
+			CREATE TABLE audit_events (
+				event_id TEXT PRIMARY KEY,
+				actor_id TEXT NOT NULL DEFAULT '',
+				action TEXT NOT NULL,
+				target_type TEXT NOT NULL,
+				target_id TEXT NOT NULL,
+				project_id TEXT NOT NULL DEFAULT '',
+				details TEXT,
+				occurred_at INTEGER NOT NULL
+			);
+			CREATE INDEX idx_audit_events_occurred_at ON audit_events (occurred_at);
+
+			CREATE TABLE codebase_configs (
+				config_id TEXT PRIMARY KEY,
+				name TEXT NOT NULL,
+				description TEXT,
+				provider TEXT NOT NULL,
+				url TEXT NOT NULL,
+				created_at INTEGER NOT NULL,
+				updated_at INTEGER NOT NULL,
+				version INTEGER NOT NULL DEFAULT 1,
+				tags TEXT,
+				config TEXT NOT NULL
+			);
+
+			CREATE TABLE tag_keys (
+				key TEXT PRIMARY KEY,
+				description TEXT NOT NULL DEFAULT '',
+				allowed_values TEXT,
+				value_pattern TEXT NOT NULL DEFAULT '',
+				editor_roles TEXT,
+				created_at INTEGER NOT NULL,
+				updated_at INTEGER NOT NULL
+			);
+
+			CREATE TABLE redaction_policies (
+				project_id TEXT PRIMARY KEY,
+				mask_emails INTEGER NOT NULL,
+				mask_credentials INTEGER NOT NULL,
+				patterns TEXT,
+				log_llm_content INTEGER NOT NULL DEFAULT 1,
+				files TEXT,
+				updated_at INTEGER
+			);
+
+			CREATE TABLE redaction_audits (
+				audit_id TEXT PRIMARY KEY,
+				project_id TEXT NOT NULL,
+				agent_id TEXT NOT NULL,
+				operation TEXT NOT NULL,
+				files_scanned INTEGER NOT NULL,
+				files_redacted INTEGER NOT NULL,
+				redactions INTEGER NOT NULL,
+				counts TEXT,
+				synced_at INTEGER NOT NULL
+			);
+			CREATE INDEX idx_redaction_audits_project_synced_at ON redaction_audits (project_id, synced_at);
+
+			CREATE TABLE workspaces (
+				workspace_id TEXT PRIMARY KEY,
+				task_id TEXT NOT NULL DEFAULT '',
+				codebase_id TEXT NOT NULL,
+				commit_sha TEXT NOT NULL,
+				host TEXT NOT NULL,
+				path TEXT NOT NULL,
+				size_bytes INTEGER NOT NULL DEFAULT 0,
+				status TEXT NOT NULL,
+				created_at INTEGER NOT NULL,
+				last_used_at INTEGER NOT NULL
+			);
+			CREATE INDEX idx_workspaces_host_last_used_at ON workspaces (host, last_used_at);
+
+			CREATE TABLE workflow_runs (
+				run_id TEXT PRIMARY KEY,
+				workflow TEXT NOT NULL,
+				status TEXT NOT NULL,
+				steps TEXT,
+				run_values TEXT,
+				error TEXT NOT NULL DEFAULT '',
+				created_at INTEGER NOT NULL,
+				updated_at INTEGER NOT NULL
+			);
+			CREATE INDEX idx_workflow_runs_workflow_status ON workflow_runs (workflow, status);
+
+			CREATE TABLE client_tokens (
+				scope TEXT NOT NULL,
+				client_token TEXT NOT NULL,
+				request_hash TEXT NOT NULL,
+				resource_id TEXT,
+				created_at INTEGER NOT NULL,
+				PRIMARY KEY (scope, client_token)
+			);
+
+			CREATE TABLE mfa_recovery_codes (
+				user_id TEXT NOT NULL,
+				code_hash TEXT NOT NULL,
+				created_at INTEGER NOT NULL,
+				used_at INTEGER,
+				PRIMARY KEY (user_id, code_hash)
+			);
+		`,
+	},
+	{
+		Version:     3,
+		Description: "grant the admin role the permissions replacing the admin routes' role check",
+		SQL: `
+			INSERT INTO permissions (name) VALUES
+				('audit:read'), ('compliance:export'), ('tag_key:manage'),
+				('integration:manage'), ('service_account:manage'), ('operations:manage')
+			ON CONFLICT (name) DO NOTHING;
+
+			INSERT INTO role_permissions (role, permission)
+			SELECT roles.name, permissions.name FROM roles, permissions
+			WHERE roles.name = 'admin' AND permissions.name IN (
+				'audit:read', 'compliance:export', 'tag_key:manage',
+				'integration:manage', 'service_account:manage', 'operations:manage'
+			)
+			ON CONFLICT (role, permission) DO NOTHING;
+		`,
+	},
+}
+
+// OpenSQLite opens the SQLite database at path, creating it when it doesn't exist, and migrates it to the schema of
+// the SQLite repositories. The path ":memory:" opens a private in-memory database.
+func OpenSQLite(ctx context.Context, path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_txlock=immediate", path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite database: %w", err)
+	}
+
+	// Every connection to an in-memory database opens a database of its own
+	if path == ":memory:" {
+		db.SetMaxOpenConns(1)
+	}
+
+	if err := Migrate(ctx, db, sqliteMigrations); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to migrate SQLite database: %w", err)
+	}
+	return db, nil
+}
+
+// isSQLiteConstraint reports whether err is the violation of a unique or primary key constraint
+func isSQLiteConstraint(err error) bool {
+	var sqliteErr *sqlite.Error
+	return errors.As(err, &sqliteErr) &&
+		(sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE || sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY)
+}
+
+// isSQLiteForeignKey reports whether err is the violation of a foreign key constraint
+func isSQLiteForeignKey(err error) bool {
+	var sqliteErr *sqlite.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY
+}
+
+// sqliteTime returns the stored form of t, 0 for the zero time
+func sqliteTime(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+// sqliteNullTime returns the stored form of t, NULL when t is nil
+func sqliteNullTime(t *time.Time) sql.NullInt64 {
+	if t == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: sqliteTime(*t), Valid: true}
+}
+
+// fromSQLiteTime returns the time stored as n
+func fromSQLiteTime(n int64) time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n).UTC()
+}
+
+// fromSQLiteNullTime returns the time stored as n, nil when n is NULL
+func fromSQLiteNullTime(n sql.NullInt64) *time.Time {
+	if !n.Valid {
+		return nil
+	}
+	t := fromSQLiteTime(n.Int64)
+	return &t
+}
+
+// sqliteJSON returns the stored form of v, NULL when v is nil
+func sqliteJSON(v any) (sql.NullString, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	if string(data) == "null" {
+		return sql.NullString{}, nil
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+// fromSQLiteJSON decodes the JSON stored as data into v, leaving v unchanged when data is NULL
+func fromSQLiteJSON(data sql.NullString, v any) error {
+	if !data.Valid {
+		return nil
+	}
+	return json.Unmarshal([]byte(data.String), v)
+}
+
+// sqliteTagConditions returns the conditions requiring the JSON object of column to carry every key and value of
+// tags, with their arguments
+func sqliteTagConditions(column string, tags map[string]string) ([]string, []any) {
+	var conditions []string
+	var args []any
+	for key, value := range tags {
+		conditions = append(conditions, fmt.Sprintf("EXISTS (SELECT 1 FROM json_each(%s) WHERE json_each.key = ? AND json_each.value = ?)", column))
+		args = append(args, key, value)
+	}
+	return conditions, args
+}
+
+// sqliteTimeRangeClause returns the WHERE clause restricting the time stored in column to timeRange, with its
+// arguments
+func sqliteTimeRangeClause(column string, timeRange TimeRange) (string, []any) {
+	var conditions []string
+	var args []any
+	if timeRange.From != nil {
+		conditions = append(conditions, column+" >= ?")
+		args = append(args, sqliteTime(*timeRange.From))
+	}
+	if timeRange.To != nil {
+		conditions = append(conditions, column+" < ?")
+		args = append(args, sqliteTime(*timeRange.To))
+	}
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// requireRowAffected returns notFound when a statement affected no row
+func requireRowAffected(result sql.Result, notFound error) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return notFound
+	}
+	return nil
+}
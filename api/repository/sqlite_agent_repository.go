@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// sqliteAgentColumns are the columns of an agent, in the order scanAgent scans them
+const sqliteAgentColumns = `agent_id, agent_version, knowledge_base_id, vector_store_id, repository_url, branch, agent_name,
+	status, created_at, updated_at, project_id, region, embedding_model, embedding_dimensions`
+
+// SQLiteAgentRepository implements AgentRepository using SQLite. Like the Postgres repository, it doesn't store the
+// AI provider and configuration of agents.
+type SQLiteAgentRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteAgentRepository creates a new SQLite agent repository on a database opened with OpenSQLite
+func NewSQLiteAgentRepository(db *sql.DB) AgentRepository {
+	return &SQLiteAgentRepository{db: db}
+}
+
+// CreateAgent stores a new agent record
+func (r *SQLiteAgentRepository) CreateAgent(ctx context.Context, agent *AgentRecord) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO agents (agent_id, agent_version, knowledge_base_id, vector_store_id, repository_url, branch,
+			agent_name, status, created_at, updated_at, project_id, region, embedding_model, embedding_dimensions)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		agent.AgentID,
+		agent.AgentVersion,
+		agent.KnowledgeBaseID,
+		agent.VectorStoreID,
+		agent.RepositoryURL,
+		agent.Branch,
+		agent.AgentName,
+		agent.Status,
+		sqliteTime(agent.CreatedAt),
+		sqliteTime(agent.UpdatedAt),
+		agent.ProjectID,
+		agent.Region,
+		agent.EmbeddingModel,
+		agent.EmbeddingDimensions,
+	)
+	if err != nil {
+		if isSQLiteConstraint(err) {
+			return apperrors.Conflict(apperrors.CodeAgentExists, "agent with ID %s already exists", agent.AgentID)
+		}
+		return fmt.Errorf("failed to create agent: %w", err)
+	}
+	return nil
+}
+
+// GetAgent retrieves an agent by ID
+func (r *SQLiteAgentRepository) GetAgent(ctx context.Context, agentID string) (*AgentRecord, error) {
+	agent, err := scanAgent(r.db.QueryRowContext(ctx, "SELECT "+sqliteAgentColumns+" FROM agents WHERE agent_id = ?", agentID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperrors.NotFound(apperrors.CodeAgentNotFound, "agent not found: %s", agentID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent: %w", err)
+	}
+	return agent, nil
+}
+
+// UpdateAgent updates an existing agent record, keeping its creation time
+func (r *SQLiteAgentRepository) UpdateAgent(ctx context.Context, agent *AgentRecord) error {
+	agent.UpdatedAt = time.Now().UTC()
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE agents
+		SET agent_version = ?, knowledge_base_id = ?, vector_store_id = ?, repository_url = ?, branch = ?,
+			agent_name = ?, status = ?, updated_at = ?, project_id = ?, region = ?, embedding_model = ?,
+			embedding_dimensions = ?
+		WHERE agent_id = ?
+	`,
+		agent.AgentVersion,
+		agent.KnowledgeBaseID,
+		agent.VectorStoreID,
+		agent.RepositoryURL,
+		agent.Branch,
+		agent.AgentName,
+		agent.Status,
+		sqliteTime(agent.UpdatedAt),
+		agent.ProjectID,
+		agent.Region,
+		agent.EmbeddingModel,
+		agent.EmbeddingDimensions,
+		agent.AgentID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update agent: %w", err)
+	}
+	return requireRowAffected(result, apperrors.NotFound(apperrors.CodeAgentNotFound, "agent not found: %s", agent.AgentID))
+}
+
+// DeleteAgent removes an agent record
+func (r *SQLiteAgentRepository) DeleteAgent(ctx context.Context, agentID string) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM agents WHERE agent_id = ?", agentID)
+	if err != nil {
+		return fmt.Errorf("failed to delete agent: %w", err)
+	}
+	return requireRowAffected(result, apperrors.NotFound(apperrors.CodeAgentNotFound, "agent not found: %s", agentID))
+}
+
+// ListAgents retrieves all agent records, newest first
+func (r *SQLiteAgentRepository) ListAgents(ctx context.Context) ([]*AgentRecord, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+sqliteAgentColumns+" FROM agents ORDER BY created_at DESC, agent_id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agents: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var agents []*AgentRecord
+	for rows.Next() {
+		agent, err := scanAgent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan agent: %w", err)
+		}
+		agents = append(agents, agent)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list agents: %w", err)
+	}
+	return agents, nil
+}
+
+// UpdateAgentStatus updates only the status field
+func (r *SQLiteAgentRepository) UpdateAgentStatus(ctx context.Context, agentID string, status models.AgentStatus) error {
+	result, err := r.db.ExecContext(ctx, "UPDATE agents SET status = ?, updated_at = ? WHERE agent_id = ?",
+		string(status), sqliteTime(time.Now()), agentID)
+	if err != nil {
+		return fmt.Errorf("failed to update agent status: %w", err)
+	}
+	return requireRowAffected(result, apperrors.NotFound(apperrors.CodeAgentNotFound, "agent not found: %s", agentID))
+}
+
+// scanAgent scans an agent selected with sqliteAgentColumns
+func scanAgent(row interface{ Scan(...any) error }) (*AgentRecord, error) {
+	var agent AgentRecord
+	var createdAt, updatedAt int64
+	var projectID sql.NullString
+	if err := row.Scan(
+		&agent.AgentID,
+		&agent.AgentVersion,
+		&agent.KnowledgeBaseID,
+		&agent.VectorStoreID,
+		&agent.RepositoryURL,
+		&agent.Branch,
+		&agent.AgentName,
+		&agent.Status,
+		&createdAt,
+		&updatedAt,
+		&projectID,
+		&agent.Region,
+		&agent.EmbeddingModel,
+		&agent.EmbeddingDimensions,
+	); err != nil {
+		return nil, err
+	}
+	agent.ProjectID = projectID.String
+	agent.CreatedAt = fromSQLiteTime(createdAt)
+	agent.UpdatedAt = fromSQLiteTime(updatedAt)
+	return &agent, nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// SQLiteAuditEventRepository implements AuditEventRepository using SQLite
+type SQLiteAuditEventRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteAuditEventRepository creates a new SQLite audit event repository on a database opened with OpenSQLite
+func NewSQLiteAuditEventRepository(db *sql.DB) AuditEventRepository {
+	return &SQLiteAuditEventRepository{db: db}
+}
+
+// RecordEvent appends an event to the audit log
+func (r *SQLiteAuditEventRepository) RecordEvent(ctx context.Context, event *models.AuditEvent) error {
+	details := event.Details
+	if details == nil {
+		details = map[string]string{}
+	}
+	detailsJSON, err := sqliteJSON(details)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit event details: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, "INSERT INTO audit_events ("+auditEventColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		event.EventID, event.ActorID, event.Action, event.TargetType, event.TargetID, event.ProjectID, detailsJSON,
+		sqliteTime(event.OccurredAt),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record audit event: %w", err)
+	}
+	return nil
+}
+
+// StreamEvents passes the events that occurred within timeRange to fn, oldest first, reading them from the database
+// as fn consumes them
+func (r *SQLiteAuditEventRepository) StreamEvents(ctx context.Context, timeRange TimeRange, fn func(models.AuditEvent) error) error {
+	where, args := sqliteTimeRangeClause("occurred_at", timeRange)
+	rows, err := r.db.QueryContext(ctx, "SELECT "+auditEventColumns+" FROM audit_events "+where+" ORDER BY occurred_at, event_id", args...)
+	if err != nil {
+		return fmt.Errorf("failed to stream audit events: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close audit event rows", "error", closeErr)
+		}
+	}()
+
+	for rows.Next() {
+		var event models.AuditEvent
+		var details sql.NullString
+		var occurredAt int64
+		if err := rows.Scan(
+			&event.EventID, &event.ActorID, &event.Action, &event.TargetType, &event.TargetID, &event.ProjectID,
+			&details, &occurredAt,
+		); err != nil {
+			return fmt.Errorf("failed to scan audit event: %w", err)
+		}
+		event.OccurredAt = fromSQLiteTime(occurredAt)
+		if err := fromSQLiteJSON(details, &event.Details); err != nil {
+			return fmt.Errorf("failed to unmarshal audit event details: %w", err)
+		}
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate audit events: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// SQLiteClientTokenRepository implements ClientTokenRepository using SQLite
+type SQLiteClientTokenRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteClientTokenRepository creates a new SQLite client token repository on a database opened with OpenSQLite
+func NewSQLiteClientTokenRepository(db *sql.DB) ClientTokenRepository {
+	return &SQLiteClientTokenRepository{db: db}
+}
+
+// ClaimToken records token unless its scope already has it, returning the record of the existing token, or nil when
+// token was recorded
+func (r *SQLiteClientTokenRepository) ClaimToken(ctx context.Context, token *models.ClientToken) (*models.ClientToken, error) {
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO client_tokens (scope, client_token, request_hash, resource_id, created_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (scope, client_token) DO NOTHING
+	`, token.Scope, token.Token, token.RequestHash, token.ResourceID, sqliteTime(token.CreatedAt))
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim client token: %w", err)
+	}
+	claimed, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if claimed > 0 {
+		return nil, nil
+	}
+
+	var existing models.ClientToken
+	var createdAt int64
+	err = r.db.QueryRowContext(ctx, `
+		SELECT scope, client_token, request_hash, resource_id, created_at
+		FROM client_tokens WHERE scope = ? AND client_token = ?
+	`, token.Scope, token.Token).Scan(&existing.Scope, &existing.Token, &existing.RequestHash, &existing.ResourceID, &createdAt)
+	if err != nil {
+		// The token was released in the meantime, claim it again
+		if errors.Is(err, sql.ErrNoRows) {
+			return r.ClaimToken(ctx, token)
+		}
+		return nil, fmt.Errorf("failed to get client token: %w", err)
+	}
+	existing.CreatedAt = fromSQLiteTime(createdAt)
+	return &existing, nil
+}
+
+// CompleteToken records the resource created by the request carrying a token
+func (r *SQLiteClientTokenRepository) CompleteToken(ctx context.Context, scope, token, resourceID string) error {
+	if _, err := r.db.ExecContext(ctx, "UPDATE client_tokens SET resource_id = ? WHERE scope = ? AND client_token = ?", resourceID, scope, token); err != nil {
+		return fmt.Errorf("failed to complete client token: %w", err)
+	}
+	return nil
+}
+
+// ReleaseToken deletes a token, so that the request carrying it can be retried
+func (r *SQLiteClientTokenRepository) ReleaseToken(ctx context.Context, scope, token string) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM client_tokens WHERE scope = ? AND client_token = ?", scope, token); err != nil {
+		return fmt.Errorf("failed to release client token: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+)
+
+// sqliteCodebaseConfigColumns are the columns of a codebase configuration, in the order scanSQLiteCodebaseConfig
+// scans them
+const sqliteCodebaseConfigColumns = "config_id, name, description, provider, url, created_at, updated_at, version, tags, config"
+
+// SQLiteCodebaseConfigRepository implements CodebaseConfigRepository using SQLite
+type SQLiteCodebaseConfigRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteCodebaseConfigRepository creates a new SQLite codebase configuration repository on a database opened with
+// OpenSQLite
+func NewSQLiteCodebaseConfigRepository(db *sql.DB) CodebaseConfigRepository {
+	return &SQLiteCodebaseConfigRepository{db: db}
+}
+
+// CreateCodebaseConfig creates a new codebase configuration record
+func (r *SQLiteCodebaseConfigRepository) CreateCodebaseConfig(ctx context.Context, config *CodebaseConfigRecord) error {
+	tags, err := sqliteJSON(config.Tags)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tags: %w", err)
+	}
+	configJSON, err := sqliteJSON(config.Config)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, "INSERT INTO codebase_configs ("+sqliteCodebaseConfigColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, 1, ?, ?)",
+		config.ConfigID,
+		config.Name,
+		config.Description,
+		config.Provider,
+		config.URL,
+		sqliteTime(config.CreatedAt),
+		sqliteTime(config.UpdatedAt),
+		tags,
+		configJSON,
+	)
+	if err != nil {
+		if isSQLiteConstraint(err) {
+			return apperrors.Conflict(apperrors.CodeCodebaseConfigExists, "codebase configuration with ID %s already exists", config.ConfigID)
+		}
+		return fmt.Errorf("failed to create codebase configuration: %w", err)
+	}
+	config.Version = 1
+	return nil
+}
+
+// GetCodebaseConfig retrieves a codebase configuration by ID
+func (r *SQLiteCodebaseConfigRepository) GetCodebaseConfig(ctx context.Context, configID string) (*CodebaseConfigRecord, error) {
+	config, err := scanSQLiteCodebaseConfig(r.db.QueryRowContext(ctx, "SELECT "+sqliteCodebaseConfigColumns+" FROM codebase_configs WHERE config_id = ?", configID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperrors.NotFound(apperrors.CodeCodebaseConfigNotFound, "codebase configuration not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get codebase configuration: %w", err)
+	}
+	return config, nil
+}
+
+// UpdateCodebaseConfig updates an existing codebase configuration record if its stored version still equals
+// config.Version, then increments config.Version
+func (r *SQLiteCodebaseConfigRepository) UpdateCodebaseConfig(ctx context.Context, config *CodebaseConfigRecord) error {
+	tags, err := sqliteJSON(config.Tags)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tags: %w", err)
+	}
+	configJSON, err := sqliteJSON(config.Config)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	// Compare-and-swap on the version so concurrent updates cannot overwrite each other
+	var version int64
+	err = r.db.QueryRowContext(ctx, `
+		UPDATE codebase_configs
+		SET name = ?, description = ?, provider = ?, url = ?, updated_at = ?, tags = ?, config = ?, version = version + 1
+		WHERE config_id = ? AND version = ?
+		RETURNING version
+	`, config.Name, config.Description, config.Provider, config.URL, sqliteTime(config.UpdatedAt), tags, configJSON,
+		config.ConfigID, config.Version).Scan(&version)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("failed to update codebase configuration: %w", err)
+		}
+
+		// No row matched: either the configuration is gone or its version moved on
+		exists, existsErr := r.CodebaseConfigExists(ctx, config.ConfigID)
+		if existsErr != nil {
+			return existsErr
+		}
+		if !exists {
+			return apperrors.NotFound(apperrors.CodeCodebaseConfigNotFound, "codebase configuration with ID %s does not exist", config.ConfigID)
+		}
+		return apperrors.PreconditionFailed(apperrors.CodeVersionMismatch, "codebase configuration with ID %s was modified since version %d", config.ConfigID, config.Version)
+	}
+	config.Version = version
+	return nil
+}
+
+// DeleteCodebaseConfig deletes a codebase configuration by ID
+func (r *SQLiteCodebaseConfigRepository) DeleteCodebaseConfig(ctx context.Context, configID string) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM codebase_configs WHERE config_id = ?", configID)
+	if err != nil {
+		return fmt.Errorf("failed to delete codebase configuration: %w", err)
+	}
+	return requireRowAffected(result, apperrors.NotFound(apperrors.CodeCodebaseConfigNotFound, "codebase configuration with ID %s does not exist", configID))
+}
+
+// CodebaseConfigExists checks if a codebase configuration exists by ID
+func (r *SQLiteCodebaseConfigRepository) CodebaseConfigExists(ctx context.Context, configID string) (bool, error) {
+	var exists int
+	err := r.db.QueryRowContext(ctx, "SELECT 1 FROM codebase_configs WHERE config_id = ?", configID).Scan(&exists)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check if codebase configuration exists: %w", err)
+	}
+	return true, nil
+}
+
+// ListCodebaseConfigs retrieves codebase configurations with pagination and filtering
+func (r *SQLiteCodebaseConfigRepository) ListCodebaseConfigs(ctx context.Context, opts ListCodebaseConfigsOptions) ([]*CodebaseConfigRecord, string, error) {
+	conditions, args := sqliteTagConditions("tags", opts.TagFilter)
+	if opts.ProviderFilter != nil {
+		conditions = append(conditions, "provider = ?")
+		args = append(args, string(*opts.ProviderFilter))
+	}
+	if opts.NextToken != nil && *opts.NextToken != "" {
+		conditions = append(conditions, "config_id > ?")
+		args = append(args, *opts.NextToken)
+	}
+
+	query := "SELECT " + sqliteCodebaseConfigColumns + " FROM codebase_configs"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY config_id"
+	if opts.MaxResults != nil {
+		// One extra configuration tells whether there are more
+		query += " LIMIT ?"
+		args = append(args, *opts.MaxResults+1)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list codebase configurations: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close rows in ListCodebaseConfigs", "error", closeErr)
+		}
+	}()
+
+	var configs []*CodebaseConfigRecord
+	for rows.Next() {
+		config, err := scanSQLiteCodebaseConfig(rows)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to scan codebase configuration row: %w", err)
+		}
+		configs = append(configs, config)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("failed to iterate codebase configurations: %w", err)
+	}
+
+	var nextToken string
+	if opts.MaxResults != nil && len(configs) > *opts.MaxResults {
+		configs = configs[:*opts.MaxResults]
+		nextToken = configs[len(configs)-1].ConfigID
+	}
+	return configs, nextToken, nil
+}
+
+// scanSQLiteCodebaseConfig scans a row selected with sqliteCodebaseConfigColumns
+func scanSQLiteCodebaseConfig(row rowScanner) (*CodebaseConfigRecord, error) {
+	var config CodebaseConfigRecord
+	var description, tags, configJSON sql.NullString
+	var createdAt, updatedAt int64
+	if err := row.Scan(
+		&config.ConfigID,
+		&config.Name,
+		&description,
+		&config.Provider,
+		&config.URL,
+		&createdAt,
+		&updatedAt,
+		&config.Version,
+		&tags,
+		&configJSON,
+	); err != nil {
+		return nil, err
+	}
+
+	if description.Valid {
+		config.Description = &description.String
+	}
+	config.CreatedAt = fromSQLiteTime(createdAt)
+	config.UpdatedAt = fromSQLiteTime(updatedAt)
+	if err := fromSQLiteJSON(tags, &config.Tags); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tags: %w", err)
+	}
+	if err := fromSQLiteJSON(configJSON, &config.Config); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	return &config, nil
+}
@@ -0,0 +1,297 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// sqliteCodebaseColumns are the columns of a codebase, in the order scanCodebase scans them
+const sqliteCodebaseColumns = `codebase_id, project_id, name, provider, url, config_id, status, last_sync_at, created_at,
+	updated_at, metadata, tags, ingestion_error, expires_at, checkout`
+
+// SQLiteCodebaseRepository implements CodebaseRepository using SQLite
+type SQLiteCodebaseRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteCodebaseRepository creates a new SQLite codebase repository on a database opened with OpenSQLite
+func NewSQLiteCodebaseRepository(db *sql.DB) CodebaseRepository {
+	return &SQLiteCodebaseRepository{db: db}
+}
+
+// CreateCodebase creates a new codebase record
+func (r *SQLiteCodebaseRepository) CreateCodebase(ctx context.Context, codebase *models.Codebase) error {
+	metadata, err := sqliteJSON(codebase.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+	tags, err := sqliteJSON(codebase.Tags)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tags: %w", err)
+	}
+	checkout, err := sqliteJSON(codebase.Checkout)
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkout: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO codebases (codebase_id, project_id, name, provider, url, config_id, status, last_sync_at, created_at,
+			updated_at, metadata, tags, ingestion_error, expires_at, checkout)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		codebase.CodebaseID,
+		codebase.ProjectID,
+		codebase.Name,
+		codebase.Provider,
+		codebase.URL,
+		codebase.ConfigID,
+		codebase.Status,
+		sqliteNullTime(codebase.LastSyncAt),
+		sqliteTime(codebase.CreatedAt),
+		sqliteTime(codebase.UpdatedAt),
+		metadata,
+		tags,
+		codebase.IngestionError,
+		sqliteNullTime(codebase.ExpiresAt),
+		checkout,
+	)
+	if err != nil {
+		switch {
+		case isSQLiteConstraint(err):
+			return apperrors.Conflict(apperrors.CodeCodebaseExists, "codebase with ID %s already exists", codebase.CodebaseID)
+		case isSQLiteForeignKey(err):
+			return apperrors.NotFound(apperrors.CodeProjectNotFound, "project with ID %s does not exist", codebase.ProjectID)
+		}
+		return fmt.Errorf("failed to create codebase: %w", err)
+	}
+	return nil
+}
+
+// GetCodebase retrieves a codebase by ID
+func (r *SQLiteCodebaseRepository) GetCodebase(ctx context.Context, codebaseID string) (*models.Codebase, error) {
+	row := r.db.QueryRowContext(ctx, "SELECT "+sqliteCodebaseColumns+" FROM codebases WHERE codebase_id = ?", codebaseID)
+	codebase, err := scanCodebase(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperrors.NotFound(apperrors.CodeCodebaseNotFound, "codebase not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get codebase: %w", err)
+	}
+	return codebase, nil
+}
+
+// UpdateCodebase updates the mutable fields of an existing codebase. Its project, provider, URL, creation and expiry
+// are kept.
+func (r *SQLiteCodebaseRepository) UpdateCodebase(ctx context.Context, codebase *models.Codebase) error {
+	metadata, err := sqliteJSON(codebase.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+	tags, err := sqliteJSON(codebase.Tags)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tags: %w", err)
+	}
+	checkout, err := sqliteJSON(codebase.Checkout)
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkout: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE codebases
+		SET name = ?, config_id = ?, status = ?, last_sync_at = ?, updated_at = ?, metadata = ?, tags = ?,
+			ingestion_error = ?, checkout = ?
+		WHERE codebase_id = ?
+	`,
+		codebase.Name,
+		codebase.ConfigID,
+		codebase.Status,
+		sqliteNullTime(codebase.LastSyncAt),
+		sqliteTime(codebase.UpdatedAt),
+		metadata,
+		tags,
+		codebase.IngestionError,
+		checkout,
+		codebase.CodebaseID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update codebase: %w", err)
+	}
+	return requireRowAffected(result, apperrors.NotFound(apperrors.CodeCodebaseNotFound, "codebase not found"))
+}
+
+// DeleteCodebase deletes a codebase by ID
+func (r *SQLiteCodebaseRepository) DeleteCodebase(ctx context.Context, codebaseID string) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM codebases WHERE codebase_id = ?", codebaseID)
+	if err != nil {
+		return fmt.Errorf("failed to delete codebase: %w", err)
+	}
+	return requireRowAffected(result, apperrors.NotFound(apperrors.CodeCodebaseNotFound, "codebase not found"))
+}
+
+// ListCodebases lists the codebases matching filter, newest first, 50 to a page unless filter sets another size. The
+// next token is the offset of the next page.
+func (r *SQLiteCodebaseRepository) ListCodebases(ctx context.Context, filter CodebaseFilter) ([]*models.Codebase, string, error) {
+	var conditions []string
+	var args []any
+	if filter.ProjectID != nil {
+		conditions = append(conditions, "project_id = ?")
+		args = append(args, *filter.ProjectID)
+	}
+	if filter.Provider != nil {
+		conditions = append(conditions, "provider = ?")
+		args = append(args, *filter.Provider)
+	}
+	if filter.TagFilter != nil {
+		if key, value, ok := strings.Cut(*filter.TagFilter, ":"); ok {
+			tagConditions, tagArgs := sqliteTagConditions("tags", map[string]string{key: value})
+			conditions = append(conditions, tagConditions...)
+			args = append(args, tagArgs...)
+		}
+	}
+
+	maxResults := 50
+	if filter.MaxResults != nil {
+		maxResults = *filter.MaxResults
+	}
+	offset := 0
+	if filter.NextToken != nil {
+		if parsed, err := strconv.Atoi(*filter.NextToken); err == nil {
+			offset = parsed
+		}
+	}
+
+	query := "SELECT " + sqliteCodebaseColumns + " FROM codebases"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	// One more than a page tells whether another page follows
+	query += " ORDER BY created_at DESC, codebase_id LIMIT ? OFFSET ?"
+	args = append(args, maxResults+1, offset)
+
+	codebases, err := r.query(ctx, query, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list codebases: %w", err)
+	}
+
+	var nextToken string
+	if len(codebases) > maxResults {
+		codebases = codebases[:maxResults]
+		nextToken = strconv.Itoa(offset + maxResults)
+	}
+	return codebases, nextToken, nil
+}
+
+// CodebaseExists checks if a codebase exists
+func (r *SQLiteCodebaseRepository) CodebaseExists(ctx context.Context, codebaseID string) (bool, error) {
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM codebases WHERE codebase_id = ?)", codebaseID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check if codebase exists: %w", err)
+	}
+	return exists, nil
+}
+
+// GetCodebasesByProject gets all codebases of a project, newest first
+func (r *SQLiteCodebaseRepository) GetCodebasesByProject(ctx context.Context, projectID string) ([]*models.Codebase, error) {
+	codebases, err := r.query(ctx, "SELECT "+sqliteCodebaseColumns+" FROM codebases WHERE project_id = ? ORDER BY created_at DESC, codebase_id", projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get codebases by project: %w", err)
+	}
+	return codebases, nil
+}
+
+// CountByProject counts the project's codebases grouped by status, with the latest sync of each status
+func (r *SQLiteCodebaseRepository) CountByProject(ctx context.Context, projectID string) ([]CodebaseStatusCount, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT status, COUNT(*), MAX(last_sync_at)
+		FROM codebases
+		WHERE project_id = ?
+		GROUP BY status
+	`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count codebases by project: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var counts []CodebaseStatusCount
+	for rows.Next() {
+		var count CodebaseStatusCount
+		var lastSyncAt sql.NullInt64
+		if err := rows.Scan(&count.Status, &count.Count, &lastSyncAt); err != nil {
+			return nil, fmt.Errorf("failed to scan codebase count: %w", err)
+		}
+		count.LastSyncAt = fromSQLiteNullTime(lastSyncAt)
+		counts = append(counts, count)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to count codebases by project: %w", err)
+	}
+	return counts, nil
+}
+
+// query returns the codebases selected by a query of sqliteCodebaseColumns
+func (r *SQLiteCodebaseRepository) query(ctx context.Context, query string, args ...any) ([]*models.Codebase, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var codebases []*models.Codebase
+	for rows.Next() {
+		codebase, err := scanCodebase(rows)
+		if err != nil {
+			return nil, err
+		}
+		codebases = append(codebases, codebase)
+	}
+	return codebases, rows.Err()
+}
+
+// scanCodebase scans a codebase selected with sqliteCodebaseColumns
+func scanCodebase(row interface{ Scan(...any) error }) (*models.Codebase, error) {
+	var codebase models.Codebase
+	var createdAt, updatedAt int64
+	var lastSyncAt, expiresAt sql.NullInt64
+	var metadata, tags, checkout sql.NullString
+	if err := row.Scan(
+		&codebase.CodebaseID,
+		&codebase.ProjectID,
+		&codebase.Name,
+		&codebase.Provider,
+		&codebase.URL,
+		&codebase.ConfigID,
+		&codebase.Status,
+		&lastSyncAt,
+		&createdAt,
+		&updatedAt,
+		&metadata,
+		&tags,
+		&codebase.IngestionError,
+		&expiresAt,
+		&checkout,
+	); err != nil {
+		return nil, err
+	}
+
+	codebase.LastSyncAt = fromSQLiteNullTime(lastSyncAt)
+	codebase.CreatedAt = fromSQLiteTime(createdAt)
+	codebase.UpdatedAt = fromSQLiteTime(updatedAt)
+	codebase.ExpiresAt = fromSQLiteNullTime(expiresAt)
+	if err := fromSQLiteJSON(metadata, &codebase.Metadata); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+	}
+	if err := fromSQLiteJSON(tags, &codebase.Tags); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tags: %w", err)
+	}
+	if err := fromSQLiteJSON(checkout, &codebase.Checkout); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal checkout: %w", err)
+	}
+	return &codebase, nil
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// SQLiteMFARecoveryCodeRepository implements MFARecoveryCodeRepository using SQLite
+type SQLiteMFARecoveryCodeRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteMFARecoveryCodeRepository creates a new SQLite MFA recovery code repository on a database opened with
+// OpenSQLite
+func NewSQLiteMFARecoveryCodeRepository(db *sql.DB) MFARecoveryCodeRepository {
+	return &SQLiteMFARecoveryCodeRepository{db: db}
+}
+
+// ReplaceRecoveryCodes replaces the recovery codes of a user with the given hashes
+func (r *SQLiteMFARecoveryCodeRepository) ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM mfa_recovery_codes WHERE user_id = ?", userID); err != nil {
+		return fmt.Errorf("failed to delete previous recovery codes: %w", err)
+	}
+	createdAt := sqliteTime(time.Now().UTC())
+	for _, codeHash := range codeHashes {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO mfa_recovery_codes ("+mfaRecoveryCodeColumns+") VALUES (?, ?, ?)", userID, codeHash, createdAt); err != nil {
+			return fmt.Errorf("failed to create recovery codes: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit recovery codes: %w", err)
+	}
+	return nil
+}
+
+// ConsumeRecoveryCode marks an unused recovery code of a user as used, reporting whether there was one
+func (r *SQLiteMFARecoveryCodeRepository) ConsumeRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE mfa_recovery_codes SET used_at = ?
+		WHERE user_id = ? AND code_hash = ? AND used_at IS NULL
+	`, sqliteTime(time.Now().UTC()), userID, codeHash)
+	if err != nil {
+		return false, fmt.Errorf("failed to consume recovery code: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
@@ -0,0 +1,236 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// sqliteProjectColumns are the columns of a project, in the order scanProject scans them
+const sqliteProjectColumns = `project_id, name, description, language, status, created_at, updated_at, version, tags,
+	metadata, vector_store, embedding_model, embedding_dimensions`
+
+// SQLiteProjectRepository implements ProjectRepository using SQLite
+type SQLiteProjectRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteProjectRepository creates a new SQLite project repository on a database opened with OpenSQLite
+func NewSQLiteProjectRepository(db *sql.DB) ProjectRepository {
+	return &SQLiteProjectRepository{db: db}
+}
+
+// CreateProject creates a new project record at version 1
+func (r *SQLiteProjectRepository) CreateProject(ctx context.Context, project *ProjectRecord) error {
+	tags, err := sqliteJSON(project.Tags)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tags: %w", err)
+	}
+	metadata, err := sqliteJSON(project.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO projects (project_id, name, description, language, status, created_at, updated_at, version, tags,
+			metadata, vector_store, embedding_model, embedding_dimensions)
+		VALUES (?, ?, ?, ?, ?, ?, ?, 1, ?, ?, ?, ?, ?)
+	`,
+		project.ProjectID,
+		project.Name,
+		project.Description,
+		project.Language,
+		project.Status,
+		sqliteTime(project.CreatedAt),
+		sqliteTime(project.UpdatedAt),
+		tags,
+		metadata,
+		project.VectorStore,
+		project.EmbeddingModel,
+		project.EmbeddingDimensions,
+	)
+	if err != nil {
+		if isSQLiteConstraint(err) {
+			return apperrors.Conflict(apperrors.CodeProjectExists, "project with ID %s already exists", project.ProjectID)
+		}
+		return fmt.Errorf("failed to create project: %w", err)
+	}
+
+	project.Version = 1
+	return nil
+}
+
+// GetProject retrieves a project by ID, nil when it doesn't exist
+func (r *SQLiteProjectRepository) GetProject(ctx context.Context, projectID string) (*ProjectRecord, error) {
+	row := r.db.QueryRowContext(ctx, "SELECT "+sqliteProjectColumns+" FROM projects WHERE project_id = ?", projectID)
+	project, err := scanProject(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+	return project, nil
+}
+
+// UpdateProject updates a project if its stored version still equals project.Version, then increments it
+func (r *SQLiteProjectRepository) UpdateProject(ctx context.Context, project *ProjectRecord) error {
+	tags, err := sqliteJSON(project.Tags)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tags: %w", err)
+	}
+	metadata, err := sqliteJSON(project.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE projects
+		SET name = ?, description = ?, language = ?, status = ?, updated_at = ?, tags = ?, metadata = ?,
+			vector_store = ?, embedding_model = ?, embedding_dimensions = ?, version = version + 1
+		WHERE project_id = ? AND version = ?
+	`,
+		project.Name,
+		project.Description,
+		project.Language,
+		project.Status,
+		sqliteTime(project.UpdatedAt),
+		tags,
+		metadata,
+		project.VectorStore,
+		project.EmbeddingModel,
+		project.EmbeddingDimensions,
+		project.ProjectID,
+		project.Version,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update project: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		exists, err := r.ProjectExists(ctx, project.ProjectID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return apperrors.NotFound(apperrors.CodeProjectNotFound, "project with ID %s does not exist", project.ProjectID)
+		}
+		return apperrors.PreconditionFailed(apperrors.CodeVersionMismatch, "project with ID %s was modified since version %d", project.ProjectID, project.Version)
+	}
+
+	project.Version++
+	return nil
+}
+
+// DeleteProject deletes a project by ID, and its codebases with it
+func (r *SQLiteProjectRepository) DeleteProject(ctx context.Context, projectID string) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM projects WHERE project_id = ?", projectID)
+	if err != nil {
+		return fmt.Errorf("failed to delete project: %w", err)
+	}
+	return requireRowAffected(result, apperrors.NotFound(apperrors.CodeProjectNotFound, "project with ID %s does not exist", projectID))
+}
+
+// ListProjects lists the projects matching the tag filter in the order of their IDs, a page after the project whose ID
+// is the next token
+func (r *SQLiteProjectRepository) ListProjects(ctx context.Context, opts ListProjectsOptions) ([]*ProjectRecord, string, error) {
+	conditions, args := sqliteTagConditions("tags", opts.TagFilter)
+	if !opts.IncludeArchived {
+		conditions = append(conditions, "status <> ?")
+		args = append(args, string(models.ProjectStatusArchived))
+	}
+	if opts.NextToken != nil && *opts.NextToken != "" {
+		conditions = append(conditions, "project_id > ?")
+		args = append(args, *opts.NextToken)
+	}
+
+	query := "SELECT " + sqliteProjectColumns + " FROM projects"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY project_id"
+	if opts.MaxResults != nil {
+		// One more than a page tells whether another page follows
+		query += " LIMIT ?"
+		args = append(args, *opts.MaxResults+1)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list projects: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var projects []*ProjectRecord
+	for rows.Next() {
+		project, err := scanProject(rows)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to scan project: %w", err)
+		}
+		projects = append(projects, project)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("failed to list projects: %w", err)
+	}
+
+	var nextToken string
+	if opts.MaxResults != nil && len(projects) > *opts.MaxResults {
+		projects = projects[:*opts.MaxResults]
+		if len(projects) > 0 {
+			nextToken = projects[len(projects)-1].ProjectID
+		}
+	}
+	return projects, nextToken, nil
+}
+
+// ProjectExists checks if a project exists by ID
+func (r *SQLiteProjectRepository) ProjectExists(ctx context.Context, projectID string) (bool, error) {
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM projects WHERE project_id = ?)", projectID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check if project exists: %w", err)
+	}
+	return exists, nil
+}
+
+// scanProject scans a project selected with sqliteProjectColumns
+func scanProject(row interface{ Scan(...any) error }) (*ProjectRecord, error) {
+	var project ProjectRecord
+	var createdAt, updatedAt int64
+	var tags, metadata sql.NullString
+	if err := row.Scan(
+		&project.ProjectID,
+		&project.Name,
+		&project.Description,
+		&project.Language,
+		&project.Status,
+		&createdAt,
+		&updatedAt,
+		&project.Version,
+		&tags,
+		&metadata,
+		&project.VectorStore,
+		&project.EmbeddingModel,
+		&project.EmbeddingDimensions,
+	); err != nil {
+		return nil, err
+	}
+
+	project.CreatedAt = fromSQLiteTime(createdAt)
+	project.UpdatedAt = fromSQLiteTime(updatedAt)
+	if err := fromSQLiteJSON(tags, &project.Tags); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tags: %w", err)
+	}
+	if err := fromSQLiteJSON(metadata, &project.Metadata); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+	}
+	return &project, nil
+}
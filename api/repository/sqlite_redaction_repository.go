@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// SQLiteRedactionPolicyRepository implements RedactionPolicyRepository using SQLite
+type SQLiteRedactionPolicyRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteRedactionPolicyRepository creates a new SQLite redaction policy repository on a database opened with
+// OpenSQLite
+func NewSQLiteRedactionPolicyRepository(db *sql.DB) RedactionPolicyRepository {
+	return &SQLiteRedactionPolicyRepository{db: db}
+}
+
+// GetPolicy retrieves the redaction policy of a project
+func (r *SQLiteRedactionPolicyRepository) GetPolicy(ctx context.Context, projectID string) (*models.RedactionPolicy, error) {
+	var policy models.RedactionPolicy
+	var patterns, files sql.NullString
+	var updatedAt sql.NullInt64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT project_id, mask_emails, mask_credentials, patterns, log_llm_content, files, updated_at
+		FROM redaction_policies WHERE project_id = ?
+	`, projectID).Scan(
+		&policy.ProjectID, &policy.MaskEmails, &policy.MaskCredentials, &patterns, &policy.LogLLMContent, &files, &updatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get redaction policy: %w", err)
+	}
+	policy.UpdatedAt = fromSQLiteNullTime(updatedAt)
+	if err := fromSQLiteJSON(patterns, &policy.Patterns); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal redaction patterns: %w", err)
+	}
+	// A NULL filter was never configured and leaves the default applied
+	if err := fromSQLiteJSON(files, &policy.Files); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal noise file filter: %w", err)
+	}
+	return &policy, nil
+}
+
+// SavePolicy creates or replaces the redaction policy of a project
+func (r *SQLiteRedactionPolicyRepository) SavePolicy(ctx context.Context, policy *models.RedactionPolicy) error {
+	patterns := policy.Patterns
+	if patterns == nil {
+		patterns = []models.RedactionPattern{}
+	}
+	patternsJSON, err := sqliteJSON(patterns)
+	if err != nil {
+		return fmt.Errorf("failed to marshal redaction patterns: %w", err)
+	}
+	filesJSON, err := sqliteJSON(policy.Files)
+	if err != nil {
+		return fmt.Errorf("failed to marshal noise file filter: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO redaction_policies (project_id, mask_emails, mask_credentials, patterns, log_llm_content, files, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (project_id) DO UPDATE SET
+			mask_emails = excluded.mask_emails,
+			mask_credentials = excluded.mask_credentials,
+			patterns = excluded.patterns,
+			log_llm_content = excluded.log_llm_content,
+			files = excluded.files,
+			updated_at = excluded.updated_at
+	`, policy.ProjectID, policy.MaskEmails, policy.MaskCredentials, patternsJSON, policy.LogLLMContent, filesJSON, sqliteNullTime(policy.UpdatedAt))
+	if err != nil {
+		return fmt.Errorf("failed to save redaction policy: %w", err)
+	}
+	return nil
+}
+
+// SQLiteRedactionAuditRepository implements RedactionAuditRepository using SQLite
+type SQLiteRedactionAuditRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteRedactionAuditRepository creates a new SQLite redaction audit repository on a database opened with
+// OpenSQLite
+func NewSQLiteRedactionAuditRepository(db *sql.DB) RedactionAuditRepository {
+	return &SQLiteRedactionAuditRepository{db: db}
+}
+
+// CreateAudit stores the redaction counts of a sync
+func (r *SQLiteRedactionAuditRepository) CreateAudit(ctx context.Context, audit *models.RedactionAudit) error {
+	counts := audit.Counts
+	if counts == nil {
+		counts = map[string]int{}
+	}
+	countsJSON, err := sqliteJSON(counts)
+	if err != nil {
+		return fmt.Errorf("failed to marshal redaction counts: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, "INSERT INTO redaction_audits ("+redactionAuditColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		audit.AuditID, audit.ProjectID, audit.AgentID, audit.Operation, audit.FilesScanned, audit.FilesRedacted,
+		audit.Redactions, countsJSON, sqliteTime(audit.SyncedAt),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create redaction audit: %w", err)
+	}
+	return nil
+}
+
+// ListAudits lists the most recent audits of a project, newest first
+func (r *SQLiteRedactionAuditRepository) ListAudits(ctx context.Context, projectID string, limit int) ([]models.RedactionAudit, error) {
+	audits := []models.RedactionAudit{}
+	err := r.queryAudits(ctx, func(audit models.RedactionAudit) error {
+		audits = append(audits, audit)
+		return nil
+	}, "SELECT "+redactionAuditColumns+" FROM redaction_audits WHERE project_id = ? ORDER BY synced_at DESC LIMIT ?", projectID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list redaction audits: %w", err)
+	}
+	return audits, nil
+}
+
+// StreamAudits passes every audit of a project to fn, newest first, reading them from the database as fn consumes
+// them
+func (r *SQLiteRedactionAuditRepository) StreamAudits(ctx context.Context, projectID string, fn func(models.RedactionAudit) error) error {
+	err := r.queryAudits(ctx, fn, "SELECT "+redactionAuditColumns+" FROM redaction_audits WHERE project_id = ? ORDER BY synced_at DESC", projectID)
+	if err != nil {
+		return fmt.Errorf("failed to stream redaction audits: %w", err)
+	}
+	return nil
+}
+
+// queryAudits passes the audits selected by query to fn
+func (r *SQLiteRedactionAuditRepository) queryAudits(ctx context.Context, fn func(models.RedactionAudit) error, query string, args ...any) error {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close redaction audit rows", "error", closeErr)
+		}
+	}()
+
+	for rows.Next() {
+		var audit models.RedactionAudit
+		var counts sql.NullString
+		var syncedAt int64
+		if err := rows.Scan(
+			&audit.AuditID, &audit.ProjectID, &audit.AgentID, &audit.Operation, &audit.FilesScanned, &audit.FilesRedacted,
+			&audit.Redactions, &counts, &syncedAt,
+		); err != nil {
+			return fmt.Errorf("failed to scan redaction audit: %w", err)
+		}
+		audit.SyncedAt = fromSQLiteTime(syncedAt)
+		if err := fromSQLiteJSON(counts, &audit.Counts); err != nil {
+			return fmt.Errorf("failed to unmarshal redaction counts for audit %s: %w", audit.AuditID, err)
+		}
+		if err := fn(audit); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
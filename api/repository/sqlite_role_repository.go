@@ -0,0 +1,356 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// SQLiteRoleRepository implements RoleRepository using SQLite
+type SQLiteRoleRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteRoleRepository creates a new SQLite role repository on a database opened with OpenSQLite, seeding the
+// built-in permissions and roles
+func NewSQLiteRoleRepository(ctx context.Context, db *sql.DB) (RoleRepository, error) {
+	repo := &SQLiteRoleRepository{db: db}
+	if err := repo.seedBuiltIns(ctx); err != nil {
+		return nil, fmt.Errorf("failed to seed built-in roles: %w", err)
+	}
+	return repo, nil
+}
+
+// seedBuiltIns registers the built-in permissions and creates the built-in roles missing. The permissions of built-in
+// roles that exist already are left alone, so changes made to them are kept.
+func (r *SQLiteRoleRepository) seedBuiltIns(ctx context.Context) error {
+	for _, permission := range models.BuiltInPermissions {
+		_, err := r.db.ExecContext(ctx, `
+			INSERT INTO permissions (name, description) VALUES (?, ?)
+			ON CONFLICT (name) DO UPDATE SET description = excluded.description
+		`, permission.Name, permission.Description)
+		if err != nil {
+			return fmt.Errorf("failed to register permission %s: %w", permission.Name, err)
+		}
+	}
+
+	now := sqliteTime(time.Now().UTC())
+	for _, role := range models.BuiltInRoles {
+		result, err := r.db.ExecContext(ctx, `
+			INSERT INTO roles (name, description, built_in, created_at, updated_at) VALUES (?, ?, 1, ?, ?)
+			ON CONFLICT (name) DO NOTHING
+		`, role.Name, role.Description, now, now)
+		if err != nil {
+			return fmt.Errorf("failed to create role %s: %w", role.Name, err)
+		}
+		created, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if created == 0 {
+			continue
+		}
+		if err := insertSQLiteRolePermissions(ctx, r.db, role.Name, role.Permissions); err != nil {
+			return fmt.Errorf("failed to grant permissions to role %s: %w", role.Name, err)
+		}
+	}
+	return nil
+}
+
+// ListPermissions lists the permissions roles can grant ordered by name
+func (r *SQLiteRoleRepository) ListPermissions(ctx context.Context) ([]models.PermissionDefinition, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT name, description FROM permissions ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list permissions: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close rows in ListPermissions", "error", closeErr)
+		}
+	}()
+
+	permissions := []models.PermissionDefinition{}
+	for rows.Next() {
+		var permission models.PermissionDefinition
+		if err := rows.Scan(&permission.Name, &permission.Description); err != nil {
+			return nil, fmt.Errorf("failed to scan permission: %w", err)
+		}
+		permissions = append(permissions, permission)
+	}
+	return permissions, rows.Err()
+}
+
+// CreateRole creates a role with its permissions
+func (r *SQLiteRoleRepository) CreateRole(ctx context.Context, role *models.Role) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO roles (name, description, built_in, created_at, updated_at) VALUES (?, ?, ?, ?, ?)
+	`, role.Name, role.Description, role.BuiltIn, sqliteTime(role.CreatedAt), sqliteTime(role.UpdatedAt))
+	if err != nil {
+		if isSQLiteConstraint(err) {
+			return apperrors.Conflict(apperrors.CodeRoleExists, "role %s already exists", role.Name)
+		}
+		return fmt.Errorf("failed to create role: %w", err)
+	}
+	if err := insertSQLiteRolePermissions(ctx, tx, role.Name, role.Permissions); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit role creation: %w", err)
+	}
+	return nil
+}
+
+// GetRole retrieves a role with its permissions
+func (r *SQLiteRoleRepository) GetRole(ctx context.Context, name models.UserRole) (*models.Role, error) {
+	roles, err := r.queryRoles(ctx, "WHERE r.name = ?", name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get role: %w", err)
+	}
+	if len(roles) == 0 {
+		return nil, nil
+	}
+	return &roles[0], nil
+}
+
+// UpdateRole replaces the description and permissions of a role
+func (r *SQLiteRoleRepository) UpdateRole(ctx context.Context, role *models.Role) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	result, err := tx.ExecContext(ctx, "UPDATE roles SET description = ?, updated_at = ? WHERE name = ?",
+		role.Description, sqliteTime(role.UpdatedAt), role.Name)
+	if err != nil {
+		return fmt.Errorf("failed to update role: %w", err)
+	}
+	if err := requireRowAffected(result, apperrors.NotFound(apperrors.CodeRoleNotFound, "role not found: %s", role.Name)); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM role_permissions WHERE role = ?", role.Name); err != nil {
+		return fmt.Errorf("failed to revoke role permissions: %w", err)
+	}
+	if err := insertSQLiteRolePermissions(ctx, tx, role.Name, role.Permissions); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit role update: %w", err)
+	}
+	return nil
+}
+
+// DeleteRole deletes a role along with the permissions it grants
+func (r *SQLiteRoleRepository) DeleteRole(ctx context.Context, name models.UserRole) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM roles WHERE name = ?", name)
+	if err != nil {
+		if isSQLiteForeignKey(err) {
+			return apperrors.Conflict(apperrors.CodeRoleInUse, "role %s is held by project members", name)
+		}
+		return fmt.Errorf("failed to delete role: %w", err)
+	}
+	return requireRowAffected(result, apperrors.NotFound(apperrors.CodeRoleNotFound, "role not found: %s", name))
+}
+
+// ListRoles lists the roles with their permissions ordered by name
+func (r *SQLiteRoleRepository) ListRoles(ctx context.Context) ([]models.Role, error) {
+	roles, err := r.queryRoles(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list roles: %w", err)
+	}
+	return roles, nil
+}
+
+// SetProjectMember adds a member to a project or replaces the role of a member
+func (r *SQLiteRoleRepository) SetProjectMember(ctx context.Context, member *models.ProjectMember) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO project_members (`+projectMemberColumns+`) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (project_id, user_id) DO UPDATE SET role = excluded.role, updated_at = excluded.updated_at
+	`, member.ProjectID, member.UserID, member.Role, sqliteTime(member.CreatedAt), sqliteTime(member.UpdatedAt))
+	if err != nil {
+		if isSQLiteForeignKey(err) {
+			return apperrors.NotFound(apperrors.CodeRoleNotFound, "role not found: %s", member.Role)
+		}
+		if isSQLiteConstraint(err) {
+			return apperrors.Conflict(apperrors.CodeProjectOwner, "project %s already has an owner", member.ProjectID)
+		}
+		return fmt.Errorf("failed to set project member: %w", err)
+	}
+	return nil
+}
+
+// GetProjectMember retrieves a member of a project
+func (r *SQLiteRoleRepository) GetProjectMember(ctx context.Context, projectID, userID string) (*models.ProjectMember, error) {
+	member, err := scanSQLiteProjectMember(r.db.QueryRowContext(ctx,
+		"SELECT "+projectMemberColumns+" FROM project_members WHERE project_id = ? AND user_id = ?", projectID, userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project member: %w", err)
+	}
+	return member, nil
+}
+
+// DeleteProjectMember removes a member from a project
+func (r *SQLiteRoleRepository) DeleteProjectMember(ctx context.Context, projectID, userID string) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM project_members WHERE project_id = ? AND user_id = ?", projectID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete project member: %w", err)
+	}
+	return requireRowAffected(result, apperrors.NotFound(apperrors.CodeProjectMemberNotFound, "user %s is not a member of project %s", userID, projectID))
+}
+
+// ListProjectMembers lists the members of a project ordered by user
+func (r *SQLiteRoleRepository) ListProjectMembers(ctx context.Context, projectID string) ([]models.ProjectMember, error) {
+	members := []models.ProjectMember{}
+	err := r.queryProjectMembers(ctx, func(member models.ProjectMember) error {
+		members = append(members, member)
+		return nil
+	}, "SELECT "+projectMemberColumns+" FROM project_members WHERE project_id = ? ORDER BY user_id", projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list project members: %w", err)
+	}
+	return members, nil
+}
+
+// TransferProjectOwnership makes a user the owner of a project, demoting its previous owner to admin
+func (r *SQLiteRoleRepository) TransferProjectOwnership(ctx context.Context, projectID, userID string, transferredAt time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE project_members SET role = ?, updated_at = ?
+		WHERE project_id = ? AND role = ? AND user_id <> ?
+	`, models.RoleAdmin, sqliteTime(transferredAt), projectID, models.RoleOwner, userID)
+	if err != nil {
+		return fmt.Errorf("failed to demote previous project owner: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO project_members (`+projectMemberColumns+`) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (project_id, user_id) DO UPDATE SET role = excluded.role, updated_at = excluded.updated_at
+	`, projectID, userID, models.RoleOwner, sqliteTime(transferredAt), sqliteTime(transferredAt))
+	if err != nil {
+		return fmt.Errorf("failed to set project owner: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit ownership transfer: %w", err)
+	}
+	return nil
+}
+
+// StreamProjectMembers passes the members of every project whose role last changed within timeRange to fn, oldest
+// change first
+func (r *SQLiteRoleRepository) StreamProjectMembers(ctx context.Context, timeRange TimeRange, fn func(models.ProjectMember) error) error {
+	where, args := sqliteTimeRangeClause("updated_at", timeRange)
+	query := "SELECT " + projectMemberColumns + " FROM project_members " + where + " ORDER BY updated_at, project_id, user_id"
+	if err := r.queryProjectMembers(ctx, fn, query, args...); err != nil {
+		return fmt.Errorf("failed to stream project members: %w", err)
+	}
+	return nil
+}
+
+// queryProjectMembers passes the members selected by query to fn
+func (r *SQLiteRoleRepository) queryProjectMembers(ctx context.Context, fn func(models.ProjectMember) error, query string, args ...any) error {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close project member rows", "error", closeErr)
+		}
+	}()
+
+	for rows.Next() {
+		member, err := scanSQLiteProjectMember(rows)
+		if err != nil {
+			return fmt.Errorf("failed to scan project member: %w", err)
+		}
+		if err := fn(*member); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// queryRoles selects the roles matching a WHERE clause on the roles aliased r, with the permissions they grant
+func (r *SQLiteRoleRepository) queryRoles(ctx context.Context, where string, args ...any) ([]models.Role, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT r.name, r.description, r.built_in, r.created_at, r.updated_at,
+			(SELECT json_group_array(permission) FROM (
+				SELECT permission FROM role_permissions WHERE role = r.name ORDER BY permission
+			))
+		FROM roles r
+		`+where+`
+		ORDER BY r.name
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close rows in queryRoles", "error", closeErr)
+		}
+	}()
+
+	roles := []models.Role{}
+	for rows.Next() {
+		var role models.Role
+		var createdAt, updatedAt int64
+		var permissions sql.NullString
+		if err := rows.Scan(&role.Name, &role.Description, &role.BuiltIn, &createdAt, &updatedAt, &permissions); err != nil {
+			return nil, err
+		}
+		role.CreatedAt = fromSQLiteTime(createdAt)
+		role.UpdatedAt = fromSQLiteTime(updatedAt)
+		role.Permissions = []models.Permission{}
+		if err := fromSQLiteJSON(permissions, &role.Permissions); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal permissions of role %s: %w", role.Name, err)
+		}
+		roles = append(roles, role)
+	}
+	return roles, rows.Err()
+}
+
+// insertSQLiteRolePermissions grants permissions to a role
+func insertSQLiteRolePermissions(ctx context.Context, exec execer, name models.UserRole, permissions []models.Permission) error {
+	for _, permission := range permissions {
+		_, err := exec.ExecContext(ctx, "INSERT OR IGNORE INTO role_permissions (role, permission) VALUES (?, ?)", name, permission)
+		if err != nil {
+			if isSQLiteForeignKey(err) {
+				return apperrors.Validation(apperrors.CodeUnknownPermission, "role %s grants an unknown permission", name)
+			}
+			return fmt.Errorf("failed to grant role permissions: %w", err)
+		}
+	}
+	return nil
+}
+
+// scanSQLiteProjectMember scans a row selected with projectMemberColumns
+func scanSQLiteProjectMember(row rowScanner) (*models.ProjectMember, error) {
+	var member models.ProjectMember
+	var createdAt, updatedAt int64
+	if err := row.Scan(&member.ProjectID, &member.UserID, &member.Role, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+	member.CreatedAt = fromSQLiteTime(createdAt)
+	member.UpdatedAt = fromSQLiteTime(updatedAt)
+	return &member, nil
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// SQLiteTagKeyRepository implements TagKeyRepository using SQLite
+type SQLiteTagKeyRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteTagKeyRepository creates a new SQLite tag key repository on a database opened with OpenSQLite
+func NewSQLiteTagKeyRepository(db *sql.DB) TagKeyRepository {
+	return &SQLiteTagKeyRepository{db: db}
+}
+
+// CreateTagKey registers a new tag key
+func (r *SQLiteTagKeyRepository) CreateTagKey(ctx context.Context, tagKey *models.TagKey) error {
+	allowedValues, editorRoles, err := marshalTagKeyRules(tagKey)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, "INSERT INTO tag_keys ("+tagKeyColumns+") VALUES (?, ?, ?, ?, ?, ?, ?)",
+		tagKey.Key, tagKey.Description, string(allowedValues), tagKey.ValuePattern, string(editorRoles),
+		sqliteTime(tagKey.CreatedAt), sqliteTime(tagKey.UpdatedAt),
+	)
+	if err != nil {
+		if isSQLiteConstraint(err) {
+			return apperrors.Conflict(apperrors.CodeTagKeyExists, "tag key %s is already registered", tagKey.Key)
+		}
+		return fmt.Errorf("failed to create tag key: %w", err)
+	}
+	return nil
+}
+
+// GetTagKey retrieves a registered tag key
+func (r *SQLiteTagKeyRepository) GetTagKey(ctx context.Context, key string) (*models.TagKey, error) {
+	tagKey, err := scanSQLiteTagKey(r.db.QueryRowContext(ctx, "SELECT "+tagKeyColumns+" FROM tag_keys WHERE key = ?", key))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag key: %w", err)
+	}
+	return tagKey, nil
+}
+
+// UpdateTagKey replaces the rules of a registered tag key
+func (r *SQLiteTagKeyRepository) UpdateTagKey(ctx context.Context, tagKey *models.TagKey) error {
+	allowedValues, editorRoles, err := marshalTagKeyRules(tagKey)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE tag_keys
+		SET description = ?, allowed_values = ?, value_pattern = ?, editor_roles = ?, updated_at = ?
+		WHERE key = ?
+	`, tagKey.Description, string(allowedValues), tagKey.ValuePattern, string(editorRoles), sqliteTime(tagKey.UpdatedAt), tagKey.Key)
+	if err != nil {
+		return fmt.Errorf("failed to update tag key: %w", err)
+	}
+	return requireRowAffected(result, apperrors.NotFound(apperrors.CodeTagKeyNotFound, "tag key not found: %s", tagKey.Key))
+}
+
+// DeleteTagKey unregisters a tag key
+func (r *SQLiteTagKeyRepository) DeleteTagKey(ctx context.Context, key string) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM tag_keys WHERE key = ?", key)
+	if err != nil {
+		return fmt.Errorf("failed to delete tag key: %w", err)
+	}
+	return requireRowAffected(result, apperrors.NotFound(apperrors.CodeTagKeyNotFound, "tag key not found: %s", key))
+}
+
+// ListTagKeys lists the registered tag keys ordered by key
+func (r *SQLiteTagKeyRepository) ListTagKeys(ctx context.Context) ([]models.TagKey, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+tagKeyColumns+" FROM tag_keys ORDER BY key")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tag keys: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close rows in ListTagKeys", "error", closeErr)
+		}
+	}()
+
+	tagKeys := []models.TagKey{}
+	for rows.Next() {
+		tagKey, err := scanSQLiteTagKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tag key: %w", err)
+		}
+		tagKeys = append(tagKeys, *tagKey)
+	}
+	return tagKeys, rows.Err()
+}
+
+// scanSQLiteTagKey scans a row selected with tagKeyColumns
+func scanSQLiteTagKey(row rowScanner) (*models.TagKey, error) {
+	var tagKey models.TagKey
+	var allowedValues, editorRoles sql.NullString
+	var createdAt, updatedAt int64
+	if err := row.Scan(
+		&tagKey.Key, &tagKey.Description, &allowedValues, &tagKey.ValuePattern, &editorRoles, &createdAt, &updatedAt,
+	); err != nil {
+		return nil, err
+	}
+	tagKey.CreatedAt = fromSQLiteTime(createdAt)
+	tagKey.UpdatedAt = fromSQLiteTime(updatedAt)
+	if err := fromSQLiteJSON(allowedValues, &tagKey.AllowedValues); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal allowed values of tag key %s: %w", tagKey.Key, err)
+	}
+	if err := fromSQLiteJSON(editorRoles, &tagKey.EditorRoles); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal editor roles of tag key %s: %w", tagKey.Key, err)
+	}
+	return &tagKey, nil
+}
@@ -0,0 +1,650 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// sqliteTaskColumns are the columns of a task, in the order scanSQLiteTask scans them
+const sqliteTaskColumns = `task_id, project_id, batch_id, campaign_id, created_by, agent_id, codebase_id, branch, commit_sha,
+	type, analysis_mode, status, priority, title, description, input, output, error_message, created_at, updated_at,
+	completed_at, queued_at, heartbeat_at, progress, progress_step, experiment_id, experiment_arm, approved, metadata, tags`
+
+// sqliteQueueOrder orders queued tasks the way the dispatcher runs them: higher priority first, then the tasks of
+// projects with fewer tasks in progress, then the longest queued
+const sqliteQueueOrder = `
+	CASE priority WHEN 'high' THEN 0 WHEN 'normal' THEN 1 ELSE 2 END,
+	(SELECT COUNT(*) FROM tasks running WHERE running.project_id = queued.project_id AND running.status = 'in_progress'),
+	queued_at IS NULL, queued_at, task_id`
+
+// SQLiteTaskRepository implements TaskRepository using SQLite. The notifications raised by task changes are enqueued in
+// the outbox before the change commits, and the change is rolled back when they can't be, so a notification is
+// delivered if and only if its change is stored.
+type SQLiteTaskRepository struct {
+	db     *sql.DB
+	outbox NotificationOutboxRepository
+}
+
+// NewSQLiteTaskRepository creates a new SQLite task repository on a database opened with OpenSQLite, recording
+// notifications in outbox. The notifications are dropped when outbox is nil, since no storage backend other than
+// Postgres delivers them.
+func NewSQLiteTaskRepository(db *sql.DB, outbox NotificationOutboxRepository) TaskRepository {
+	return &SQLiteTaskRepository{
+		db:     db,
+		outbox: outbox,
+	}
+}
+
+// sqliteExecer executes statements on a database or in a transaction
+type sqliteExecer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// insertTask inserts a new task, generating its ID when it has none
+func (r *SQLiteTaskRepository) insertTask(ctx context.Context, exec sqliteExecer, task *models.Task) error {
+	if task.TaskID == "" {
+		task.TaskID = "task-" + uuid.New().String()
+	}
+	now := time.Now()
+	task.CreatedAt = now
+	task.UpdatedAt = now
+	if task.Priority == "" {
+		task.Priority = models.TaskPriorityNormal
+	}
+
+	input, err := sqliteJSON(task.Input)
+	if err != nil {
+		return fmt.Errorf("failed to marshal input: %w", err)
+	}
+	output, err := sqliteJSON(task.Output)
+	if err != nil {
+		return fmt.Errorf("failed to marshal output: %w", err)
+	}
+	metadata, err := sqliteJSON(task.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+	tags, err := sqliteJSON(task.Tags)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tags: %w", err)
+	}
+
+	_, err = exec.ExecContext(ctx, `
+		INSERT INTO tasks (task_id, project_id, batch_id, campaign_id, created_by, agent_id, codebase_id, branch,
+			commit_sha, type, analysis_mode, status, title, description, input, output, error_message, created_at,
+			updated_at, completed_at, metadata, tags, priority)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		task.TaskID, task.ProjectID, task.BatchID, task.CampaignID, task.CreatedBy, task.AgentID, task.CodebaseID,
+		task.Branch, task.CommitSHA, task.Type, task.AnalysisMode, task.Status, task.Title, task.Description, input,
+		output, task.ErrorMessage, sqliteTime(task.CreatedAt), sqliteTime(task.UpdatedAt),
+		sqliteNullTime(task.CompletedAt), metadata, tags, task.Priority,
+	)
+	return err
+}
+
+// Create creates a new task
+func (r *SQLiteTaskRepository) Create(ctx context.Context, task *models.Task) error {
+	if err := r.insertTask(ctx, r.db, task); err != nil {
+		return fmt.Errorf("failed to create task: %w", err)
+	}
+	return nil
+}
+
+// CreateBatch creates multiple tasks atomically; either all tasks are stored or none are
+func (r *SQLiteTaskRepository) CreateBatch(ctx context.Context, tasks []*models.Task) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, task := range tasks {
+		if err := r.insertTask(ctx, tx, task); err != nil {
+			return fmt.Errorf("failed to insert task %s: %w", task.Title, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit task batch: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a task by its ID
+func (r *SQLiteTaskRepository) GetByID(ctx context.Context, taskID string) (*models.Task, error) {
+	task, err := scanSQLiteTask(r.db.QueryRowContext(ctx, "SELECT "+sqliteTaskColumns+" FROM tasks WHERE task_id = ?", taskID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperrors.NotFound(apperrors.CodeTaskNotFound, "task not found: %s", taskID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+	return task, nil
+}
+
+// Update updates an existing task and records the notification it raises. A task that moved on in the meantime to a
+// status that can't move to the task's is left unchanged. The fields set by the queue, the executions and the
+// experiments are kept.
+func (r *SQLiteTaskRepository) Update(ctx context.Context, task *models.Task, notification *models.Notification) error {
+	task.UpdatedAt = time.Now()
+
+	input, err := sqliteJSON(task.Input)
+	if err != nil {
+		return fmt.Errorf("failed to marshal input: %w", err)
+	}
+	output, err := sqliteJSON(task.Output)
+	if err != nil {
+		return fmt.Errorf("failed to marshal output: %w", err)
+	}
+	metadata, err := sqliteJSON(task.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+	tags, err := sqliteJSON(task.Tags)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tags: %w", err)
+	}
+
+	return r.transition(ctx, task.TaskID, task.Status, true, notification, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE tasks
+			SET project_id = ?, agent_id = ?, codebase_id = ?, type = ?, status = ?, title = ?, description = ?,
+				input = ?, output = ?, error_message = ?, updated_at = ?, completed_at = ?, metadata = ?, tags = ?,
+				approved = ?
+			WHERE task_id = ?
+		`,
+			task.ProjectID, task.AgentID, task.CodebaseID, task.Type, task.Status, task.Title, task.Description,
+			input, output, task.ErrorMessage, sqliteTime(task.UpdatedAt), sqliteNullTime(task.CompletedAt), metadata,
+			tags, task.Approved, task.TaskID,
+		)
+		return err
+	})
+}
+
+// Delete deletes a task by its ID
+func (r *SQLiteTaskRepository) Delete(ctx context.Context, taskID string) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM tasks WHERE task_id = ?", taskID)
+	if err != nil {
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+	return requireRowAffected(result, apperrors.NotFound(apperrors.CodeTaskNotFound, "task not found: %s", taskID))
+}
+
+// ListByProject lists a page of the project's tasks matching filters, newest first, with the number of tasks
+// matching them
+func (r *SQLiteTaskRepository) ListByProject(ctx context.Context, projectID string, filters TaskFilters) ([]models.Task, int, error) {
+	where, args := sqliteTaskFilter(&projectID, filters)
+	return r.list(ctx, where, args, filters)
+}
+
+// StreamByProject passes every task of a project matching filters to fn, newest first, without loading them all in
+// memory. Pagination filters are ignored.
+func (r *SQLiteTaskRepository) StreamByProject(ctx context.Context, projectID string, filters TaskFilters, fn func(models.Task) error) error {
+	where, args := sqliteTaskFilter(&projectID, filters)
+	rows, err := r.db.QueryContext(ctx, "SELECT "+sqliteTaskColumns+" FROM tasks "+where+" ORDER BY created_at DESC, task_id", args...)
+	if err != nil {
+		return fmt.Errorf("failed to stream tasks: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		task, err := scanSQLiteTask(rows)
+		if err != nil {
+			return fmt.Errorf("failed to scan task: %w", err)
+		}
+		if err := fn(*task); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// ListByAgent lists tasks for a specific agent
+func (r *SQLiteTaskRepository) ListByAgent(ctx context.Context, agentID string, filters TaskFilters) ([]models.Task, int, error) {
+	filters.AgentID = &agentID
+	where, args := sqliteTaskFilter(nil, filters)
+	return r.list(ctx, where, args, filters)
+}
+
+// ListByCodebase lists tasks for a specific codebase
+func (r *SQLiteTaskRepository) ListByCodebase(ctx context.Context, codebaseID string, filters TaskFilters) ([]models.Task, int, error) {
+	filters.CodebaseID = &codebaseID
+	where, args := sqliteTaskFilter(nil, filters)
+	return r.list(ctx, where, args, filters)
+}
+
+// ListByBatch lists all tasks created as part of a batch, oldest first
+func (r *SQLiteTaskRepository) ListByBatch(ctx context.Context, batchID string) ([]models.Task, error) {
+	tasks, err := r.query(ctx, "SELECT "+sqliteTaskColumns+" FROM tasks WHERE batch_id = ? ORDER BY created_at, task_id", batchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks by batch: %w", err)
+	}
+	return tasks, nil
+}
+
+// ListByCampaign lists the child tasks of a campaign, oldest first
+func (r *SQLiteTaskRepository) ListByCampaign(ctx context.Context, campaignID string) ([]models.Task, error) {
+	tasks, err := r.query(ctx, "SELECT "+sqliteTaskColumns+" FROM tasks WHERE campaign_id = ? ORDER BY created_at, task_id", campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks by campaign: %w", err)
+	}
+	return tasks, nil
+}
+
+// ListByExperiment lists the tasks assigned to an experiment, oldest first
+func (r *SQLiteTaskRepository) ListByExperiment(ctx context.Context, experimentID string) ([]models.Task, error) {
+	tasks, err := r.query(ctx, "SELECT "+sqliteTaskColumns+" FROM tasks WHERE experiment_id = ? ORDER BY created_at, task_id", experimentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks by experiment: %w", err)
+	}
+	return tasks, nil
+}
+
+// AssignExperiment records the experiment and arm a task was assigned to, unless it already was
+func (r *SQLiteTaskRepository) AssignExperiment(ctx context.Context, taskID, experimentID string, arm models.ExperimentArm) error {
+	if _, err := r.db.ExecContext(ctx, `
+		UPDATE tasks SET experiment_id = ?, experiment_arm = ? WHERE task_id = ? AND experiment_id IS NULL
+	`, experimentID, arm, taskID); err != nil {
+		return fmt.Errorf("failed to assign experiment: %w", err)
+	}
+	return nil
+}
+
+// CountByProject counts the project's tasks created since the given time, grouped by status and type
+func (r *SQLiteTaskRepository) CountByProject(ctx context.Context, projectID string, since time.Time) ([]TaskCount, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT status, type, COUNT(*)
+		FROM tasks
+		WHERE project_id = ? AND created_at >= ?
+		GROUP BY status, type
+	`, projectID, sqliteTime(since))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tasks by project: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var counts []TaskCount
+	for rows.Next() {
+		var count TaskCount
+		if err := rows.Scan(&count.Status, &count.Type, &count.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan task count: %w", err)
+		}
+		counts = append(counts, count)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to count tasks by project: %w", err)
+	}
+	return counts, nil
+}
+
+// ListRecentFailures lists the project's most recently failed tasks updated since the given time
+func (r *SQLiteTaskRepository) ListRecentFailures(ctx context.Context, projectID string, since time.Time, limit int) ([]models.Task, error) {
+	tasks, err := r.query(ctx, `
+		SELECT `+sqliteTaskColumns+`
+		FROM tasks
+		WHERE project_id = ? AND status = ? AND updated_at >= ?
+		ORDER BY updated_at DESC, task_id
+		LIMIT ?
+	`, projectID, models.TaskStatusFailed, sqliteTime(since), sqliteLimit(limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recent failures: %w", err)
+	}
+	return tasks, nil
+}
+
+// UpdateStatus moves a task to status, unless its status can't move to it, and records the notification the
+// transition raises. A task moved to queued goes to the back of the queue.
+func (r *SQLiteTaskRepository) UpdateStatus(ctx context.Context, taskID string, status models.TaskStatus, notification *models.Notification) error {
+	now := time.Now()
+	var completedAt, queuedAt *time.Time
+	if status.IsTerminal() {
+		completedAt = &now
+	}
+	if status == models.TaskStatusQueued {
+		queuedAt = &now
+	}
+
+	return r.transition(ctx, taskID, status, false, notification, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE tasks
+			SET status = ?, updated_at = ?, completed_at = ?, queued_at = COALESCE(?, queued_at)
+			WHERE task_id = ?
+		`, status, sqliteTime(now), sqliteNullTime(completedAt), sqliteNullTime(queuedAt), taskID)
+		return err
+	})
+}
+
+// UpdateStatusAndOutput moves a task to status with its output, like UpdateStatus, and records the notification the
+// transition raises. A completed task has made all its progress, while a failed one keeps the step it failed in.
+func (r *SQLiteTaskRepository) UpdateStatusAndOutput(ctx context.Context, taskID string, status models.TaskStatus, output map[string]any, errorMessage *string, notification *models.Notification) error {
+	outputJSON, err := sqliteJSON(output)
+	if err != nil {
+		return fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	now := time.Now()
+	var completedAt *time.Time
+	if status == models.TaskStatusCompleted || status == models.TaskStatusFailed {
+		completedAt = &now
+	}
+	completed := status == models.TaskStatusCompleted
+
+	return r.transition(ctx, taskID, status, false, notification, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE tasks
+			SET status = ?, output = ?, error_message = ?, updated_at = ?, completed_at = ?,
+				progress = CASE WHEN ? THEN 100 ELSE progress END,
+				progress_step = CASE WHEN ? THEN NULL ELSE progress_step END
+			WHERE task_id = ?
+		`, status, outputJSON, errorMessage, sqliteTime(now), sqliteNullTime(completedAt), completed, completed, taskID)
+		return err
+	})
+}
+
+// NextQueued returns the queued task the dispatcher runs next, nil when no task is queued
+func (r *SQLiteTaskRepository) NextQueued(ctx context.Context) (*models.Task, error) {
+	task, err := scanSQLiteTask(r.db.QueryRowContext(ctx, `
+		SELECT `+sqliteTaskColumns+`
+		FROM tasks queued
+		WHERE status = ?
+		ORDER BY `+sqliteQueueOrder+`
+		LIMIT 1
+	`, models.TaskStatusQueued))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get next queued task: %w", err)
+	}
+	return task, nil
+}
+
+// QueuePosition returns the position of a queued task in the order NextQueued runs them, 0 when it isn't queued
+func (r *SQLiteTaskRepository) QueuePosition(ctx context.Context, taskID string) (int, error) {
+	var position int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT position
+		FROM (
+			SELECT task_id, ROW_NUMBER() OVER (ORDER BY `+sqliteQueueOrder+`) AS position
+			FROM tasks queued
+			WHERE status = ?
+		)
+		WHERE task_id = ?
+	`, models.TaskStatusQueued, taskID).Scan(&position)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get queue position: %w", err)
+	}
+	return position, nil
+}
+
+// Heartbeat records that the execution of an in_progress task is still running, and how far it got
+func (r *SQLiteTaskRepository) Heartbeat(ctx context.Context, taskID string, progress int, step string) error {
+	var progressStep *string
+	if step != "" {
+		progressStep = &step
+	}
+	if _, err := r.db.ExecContext(ctx, `
+		UPDATE tasks SET heartbeat_at = ?, progress = ?, progress_step = ? WHERE task_id = ? AND status = ?
+	`, sqliteTime(time.Now()), progress, progressStep, taskID, models.TaskStatusInProgress); err != nil {
+		return fmt.Errorf("failed to record heartbeat: %w", err)
+	}
+	return nil
+}
+
+// ListStuck lists the in_progress tasks without a heartbeat since the given time, longest silent first
+func (r *SQLiteTaskRepository) ListStuck(ctx context.Context, heartbeatBefore time.Time, limit int) ([]models.Task, error) {
+	tasks, err := r.query(ctx, `
+		SELECT `+sqliteTaskColumns+`
+		FROM tasks
+		WHERE status = ? AND COALESCE(heartbeat_at, updated_at) < ?
+		ORDER BY COALESCE(heartbeat_at, updated_at), task_id
+		LIMIT ?
+	`, models.TaskStatusInProgress, sqliteTime(heartbeatBefore), sqliteLimit(limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stuck tasks: %w", err)
+	}
+	if tasks == nil {
+		tasks = []models.Task{}
+	}
+	return tasks, nil
+}
+
+// RecoverStuck moves a stuck task to status, and never a task whose execution is still heartbeating
+func (r *SQLiteTaskRepository) RecoverStuck(ctx context.Context, taskID string, heartbeatBefore time.Time, status models.TaskStatus, errorMessage *string, notification *models.Notification) (bool, error) {
+	if !models.TaskStatusInProgress.CanTransitionTo(status) {
+		return false, apperrors.Conflict(apperrors.CodeInvalidTaskTransition, "stuck task %s can't move to %s", taskID, status)
+	}
+
+	now := time.Now()
+	var completedAt, queuedAt *time.Time
+	if status.IsTerminal() {
+		completedAt = &now
+	}
+	if status == models.TaskStatusQueued {
+		queuedAt = &now
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE tasks
+		SET status = ?, error_message = ?, updated_at = ?, completed_at = ?, heartbeat_at = NULL,
+			queued_at = COALESCE(?, queued_at)
+		WHERE task_id = ? AND status = ? AND COALESCE(heartbeat_at, updated_at) < ?
+	`, status, errorMessage, sqliteTime(now), sqliteNullTime(completedAt), sqliteNullTime(queuedAt), taskID,
+		models.TaskStatusInProgress, sqliteTime(heartbeatBefore))
+	if err != nil {
+		return false, fmt.Errorf("failed to recover stuck task: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return false, nil
+	}
+
+	if err := r.commit(ctx, tx, notification); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// transition applies a change moving a task to status in a transaction, when its current status can move to it, or
+// already is status when unchanged is allowed. A missing task is told from one whose status can't move to status.
+func (r *SQLiteTaskRepository) transition(ctx context.Context, taskID string, status models.TaskStatus, unchanged bool, notification *models.Notification, apply func(*sql.Tx) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var current models.TaskStatus
+	err = tx.QueryRowContext(ctx, "SELECT status FROM tasks WHERE task_id = ?", taskID).Scan(&current)
+	if errors.Is(err, sql.ErrNoRows) {
+		return apperrors.NotFound(apperrors.CodeTaskNotFound, "task not found: %s", taskID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get task status: %w", err)
+	}
+	if !(unchanged && current == status) && !current.CanTransitionTo(status) {
+		return apperrors.Conflict(apperrors.CodeInvalidTaskTransition, "task %s is %s and can't move to %s", taskID, current, status)
+	}
+
+	if err := apply(tx); err != nil {
+		return fmt.Errorf("failed to update task: %w", err)
+	}
+	return r.commit(ctx, tx, notification)
+}
+
+// commit enqueues the notification a change raises and commits the change, which is rolled back when the notification
+// can't be enqueued
+func (r *SQLiteTaskRepository) commit(ctx context.Context, tx *sql.Tx, notification *models.Notification) error {
+	if notification != nil && r.outbox != nil {
+		if err := r.outbox.Enqueue(ctx, *notification); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit task change: %w", err)
+	}
+	return nil
+}
+
+// list lists a page of the tasks selected by where, newest first, with the number of tasks it selects
+func (r *SQLiteTaskRepository) list(ctx context.Context, where string, args []any, filters TaskFilters) ([]models.Task, int, error) {
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM tasks "+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count tasks: %w", err)
+	}
+
+	tasks, err := r.query(ctx, "SELECT "+sqliteTaskColumns+" FROM tasks "+where+" ORDER BY created_at DESC, task_id LIMIT ? OFFSET ?",
+		append(args, sqliteLimit(filters.Limit), max(filters.Offset, 0))...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list tasks: %w", err)
+	}
+	return tasks, total, nil
+}
+
+// query returns the tasks selected by a query of sqliteTaskColumns
+func (r *SQLiteTaskRepository) query(ctx context.Context, query string, args ...any) ([]models.Task, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var tasks []models.Task
+	for rows.Next() {
+		task, err := scanSQLiteTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, *task)
+	}
+	return tasks, rows.Err()
+}
+
+// sqliteTaskFilter builds the WHERE clause and arguments selecting the tasks matching filters, of the project when
+// projectID isn't nil, leaving pagination aside
+func sqliteTaskFilter(projectID *string, filters TaskFilters) (string, []any) {
+	var conditions []string
+	var args []any
+	if projectID != nil {
+		conditions = append(conditions, "project_id = ?")
+		args = append(args, *projectID)
+	}
+	if filters.Status != nil {
+		conditions = append(conditions, "status = ?")
+		args = append(args, *filters.Status)
+	}
+	if filters.Type != nil {
+		conditions = append(conditions, "type = ?")
+		args = append(args, *filters.Type)
+	}
+	if filters.AgentID != nil {
+		conditions = append(conditions, "agent_id = ?")
+		args = append(args, *filters.AgentID)
+	}
+	if filters.CodebaseID != nil {
+		conditions = append(conditions, "codebase_id = ?")
+		args = append(args, *filters.CodebaseID)
+	}
+	tagConditions, tagArgs := sqliteTagConditions("tags", filters.Tags)
+	conditions = append(conditions, tagConditions...)
+	args = append(args, tagArgs...)
+	if filters.Owner != nil {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM json_each(output, '$.owners') WHERE json_each.value = ?)")
+		args = append(args, *filters.Owner)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// sqliteLimit returns the LIMIT of a page of limit rows, where a limit that isn't positive doesn't bound the page
+func sqliteLimit(limit int) int {
+	if limit <= 0 {
+		return -1
+	}
+	return limit
+}
+
+// scanSQLiteTask scans a task selected with sqliteTaskColumns
+func scanSQLiteTask(row interface{ Scan(...any) error }) (*models.Task, error) {
+	var task models.Task
+	var createdAt, updatedAt int64
+	var completedAt, queuedAt, heartbeatAt sql.NullInt64
+	var input, output, metadata, tags sql.NullString
+	if err := row.Scan(
+		&task.TaskID,
+		&task.ProjectID,
+		&task.BatchID,
+		&task.CampaignID,
+		&task.CreatedBy,
+		&task.AgentID,
+		&task.CodebaseID,
+		&task.Branch,
+		&task.CommitSHA,
+		&task.Type,
+		&task.AnalysisMode,
+		&task.Status,
+		&task.Priority,
+		&task.Title,
+		&task.Description,
+		&input,
+		&output,
+		&task.ErrorMessage,
+		&createdAt,
+		&updatedAt,
+		&completedAt,
+		&queuedAt,
+		&heartbeatAt,
+		&task.Progress,
+		&task.ProgressStep,
+		&task.ExperimentID,
+		&task.ExperimentArm,
+		&task.Approved,
+		&metadata,
+		&tags,
+	); err != nil {
+		return nil, err
+	}
+
+	task.CreatedAt = fromSQLiteTime(createdAt)
+	task.UpdatedAt = fromSQLiteTime(updatedAt)
+	task.CompletedAt = fromSQLiteNullTime(completedAt)
+	task.QueuedAt = fromSQLiteNullTime(queuedAt)
+	task.HeartbeatAt = fromSQLiteNullTime(heartbeatAt)
+	if err := fromSQLiteJSON(input, &task.Input); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal input: %w", err)
+	}
+	if err := fromSQLiteJSON(output, &task.Output); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal output: %w", err)
+	}
+	if err := fromSQLiteJSON(metadata, &task.Metadata); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+	}
+	if err := fromSQLiteJSON(tags, &task.Tags); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tags: %w", err)
+	}
+	return &task, nil
+}
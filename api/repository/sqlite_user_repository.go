@@ -0,0 +1,263 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// sqliteUserColumns are the columns of a user, in the order scanUser scans them
+const sqliteUserColumns = "user_id, auth_id, email, username, first_name, last_name, role, status, created_at, updated_at"
+
+// SQLiteUserRepository implements UserRepository using SQLite
+type SQLiteUserRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteUserRepository creates a new SQLite user repository on a database opened with OpenSQLite
+func NewSQLiteUserRepository(db *sql.DB) UserRepository {
+	return &SQLiteUserRepository{db: db}
+}
+
+// CreateUser creates a new user, generating its ID when it has none
+func (r *SQLiteUserRepository) CreateUser(ctx context.Context, user *models.DBUser) (*models.DBUser, error) {
+	if user.UserID == "" {
+		user.UserID = generateUserID()
+	}
+
+	now := time.Now().UTC()
+	user.CreatedAt = now
+	user.UpdatedAt = now
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO users (user_id, auth_id, email, username, first_name, last_name, role, status, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		user.UserID,
+		user.AuthID,
+		user.Email,
+		user.Username,
+		user.FirstName,
+		user.LastName,
+		string(user.Role),
+		string(user.Status),
+		sqliteTime(user.CreatedAt),
+		sqliteTime(user.UpdatedAt),
+	)
+	if err != nil {
+		if isSQLiteConstraint(err) {
+			return nil, apperrors.Conflict(apperrors.CodeUserExists, "user with email '%s', username '%s', or auth_id '%s' already exists", user.Email, user.Username, user.AuthID)
+		}
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+	return user, nil
+}
+
+// GetUser retrieves a user by user ID, nil when it doesn't exist
+func (r *SQLiteUserRepository) GetUser(ctx context.Context, userID string) (*models.DBUser, error) {
+	user, err := scanUser(r.db.QueryRowContext(ctx, "SELECT "+sqliteUserColumns+" FROM users WHERE user_id = ?", userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return user, nil
+}
+
+// GetUserByAuthID retrieves a user by auth provider ID
+func (r *SQLiteUserRepository) GetUserByAuthID(ctx context.Context, authID string) (*models.DBUser, error) {
+	user, err := scanUser(r.db.QueryRowContext(ctx, "SELECT "+sqliteUserColumns+" FROM users WHERE auth_id = ?", authID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperrors.NotFound(apperrors.CodeUserNotFound, "user with auth_id '%s' not found", authID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user by auth_id: %w", err)
+	}
+	return user, nil
+}
+
+// GetUserByEmail retrieves a user by email
+func (r *SQLiteUserRepository) GetUserByEmail(ctx context.Context, email string) (*models.DBUser, error) {
+	user, err := scanUser(r.db.QueryRowContext(ctx, "SELECT "+sqliteUserColumns+" FROM users WHERE email = ?", email))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperrors.NotFound(apperrors.CodeUserNotFound, "user with email '%s' not found", email)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user by email: %w", err)
+	}
+	return user, nil
+}
+
+// UpdateUser updates an existing user, keeping its creation time
+func (r *SQLiteUserRepository) UpdateUser(ctx context.Context, user *models.DBUser) (*models.DBUser, error) {
+	user.UpdatedAt = time.Now().UTC()
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE users
+		SET auth_id = ?, email = ?, username = ?, first_name = ?, last_name = ?, role = ?, status = ?, updated_at = ?
+		WHERE user_id = ?
+	`,
+		user.AuthID,
+		user.Email,
+		user.Username,
+		user.FirstName,
+		user.LastName,
+		string(user.Role),
+		string(user.Status),
+		sqliteTime(user.UpdatedAt),
+		user.UserID,
+	)
+	if err != nil {
+		if isSQLiteConstraint(err) {
+			return nil, apperrors.Conflict(apperrors.CodeUserExists, "user with email '%s', username '%s', or auth_id '%s' already exists", user.Email, user.Username, user.AuthID)
+		}
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+	if err := requireRowAffected(result, apperrors.NotFound(apperrors.CodeUserNotFound, "user with ID '%s' does not exist", user.UserID)); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// DeleteUser deletes a user by user ID
+func (r *SQLiteUserRepository) DeleteUser(ctx context.Context, userID string) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM users WHERE user_id = ?", userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	return requireRowAffected(result, apperrors.NotFound(apperrors.CodeUserNotFound, "user with ID '%s' does not exist", userID))
+}
+
+// ListUsers lists a page of the users matching filter, newest first, with the number of users matching it. Search
+// matches the email, username, first or last name containing it, ignoring case.
+func (r *SQLiteUserRepository) ListUsers(ctx context.Context, filter *ListUsersFilter) ([]*models.DBUser, int, error) {
+	if filter == nil {
+		filter = &ListUsersFilter{}
+	}
+
+	var conditions []string
+	var args []any
+	if filter.Role != nil {
+		conditions = append(conditions, "role = ?")
+		args = append(args, string(*filter.Role))
+	}
+	if filter.Status != nil {
+		conditions = append(conditions, "status = ?")
+		args = append(args, string(*filter.Status))
+	}
+	if filter.Search != "" {
+		// LIKE ignores the case of ASCII letters
+		search := "%" + filter.Search + "%"
+		conditions = append(conditions, "(email LIKE ? OR username LIKE ? OR first_name LIKE ? OR last_name LIKE ?)")
+		args = append(args, search, search, search, search)
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM users"+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	// A negative limit doesn't bound the page
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = -1
+	}
+	rows, err := r.db.QueryContext(ctx, "SELECT "+sqliteUserColumns+" FROM users"+where+" ORDER BY created_at DESC, user_id LIMIT ? OFFSET ?",
+		append(args, limit, max(filter.Offset, 0))...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list users: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var users []*models.DBUser
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to list users: %w", err)
+	}
+	return users, total, nil
+}
+
+// UserExists checks if a user exists by user ID
+func (r *SQLiteUserRepository) UserExists(ctx context.Context, userID string) (bool, error) {
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM users WHERE user_id = ?)", userID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check if user exists: %w", err)
+	}
+	return exists, nil
+}
+
+// StreamUsers passes the users created within timeRange to fn, oldest first, without loading them all in memory
+func (r *SQLiteUserRepository) StreamUsers(ctx context.Context, timeRange TimeRange, fn func(*models.DBUser) error) error {
+	var conditions []string
+	var args []any
+	if timeRange.From != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, sqliteTime(*timeRange.From))
+	}
+	if timeRange.To != nil {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, sqliteTime(*timeRange.To))
+	}
+	query := "SELECT " + sqliteUserColumns + " FROM users"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY created_at, user_id"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to stream users: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return fmt.Errorf("failed to scan user: %w", err)
+		}
+		if err := fn(user); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// scanUser scans a user selected with sqliteUserColumns
+func scanUser(row interface{ Scan(...any) error }) (*models.DBUser, error) {
+	var user models.DBUser
+	var createdAt, updatedAt int64
+	if err := row.Scan(
+		&user.UserID,
+		&user.AuthID,
+		&user.Email,
+		&user.Username,
+		&user.FirstName,
+		&user.LastName,
+		&user.Role,
+		&user.Status,
+		&createdAt,
+		&updatedAt,
+	); err != nil {
+		return nil, err
+	}
+	user.CreatedAt = fromSQLiteTime(createdAt)
+	user.UpdatedAt = fromSQLiteTime(updatedAt)
+	return &user, nil
+}
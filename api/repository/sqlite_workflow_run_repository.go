@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/workflow"
+)
+
+// SQLiteWorkflowRunRepository implements WorkflowRunRepository using SQLite
+type SQLiteWorkflowRunRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteWorkflowRunRepository creates a new SQLite workflow run repository on a database opened with OpenSQLite
+func NewSQLiteWorkflowRunRepository(db *sql.DB) WorkflowRunRepository {
+	return &SQLiteWorkflowRunRepository{db: db}
+}
+
+// GetRun retrieves a run
+func (r *SQLiteWorkflowRunRepository) GetRun(ctx context.Context, runID string) (*workflow.Run, error) {
+	run, err := scanSQLiteWorkflowRun(r.db.QueryRowContext(ctx, "SELECT "+workflowRunColumns+" FROM workflow_runs WHERE run_id = ?", runID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workflow run: %w", err)
+	}
+	return run, nil
+}
+
+// SaveRun creates or replaces a run
+func (r *SQLiteWorkflowRunRepository) SaveRun(ctx context.Context, run *workflow.Run) error {
+	stepsJSON, err := json.Marshal(run.Steps)
+	if err != nil {
+		return fmt.Errorf("failed to marshal steps: %w", err)
+	}
+	valuesJSON, err := json.Marshal(run.Values)
+	if err != nil {
+		return fmt.Errorf("failed to marshal values: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO workflow_runs (`+workflowRunColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (run_id) DO UPDATE SET
+			status = excluded.status,
+			steps = excluded.steps,
+			run_values = excluded.run_values,
+			error = excluded.error,
+			updated_at = excluded.updated_at
+	`, run.RunID, run.Workflow, run.Status, string(stepsJSON), string(valuesJSON), run.Error,
+		sqliteTime(run.CreatedAt), sqliteTime(run.UpdatedAt))
+	if err != nil {
+		return fmt.Errorf("failed to save workflow run: %w", err)
+	}
+	return nil
+}
+
+// ListRuns returns the runs of a workflow in the given status, or in any status when it's empty, oldest first
+func (r *SQLiteWorkflowRunRepository) ListRuns(ctx context.Context, workflowName string, status workflow.RunStatus) ([]*workflow.Run, error) {
+	query := "SELECT " + workflowRunColumns + " FROM workflow_runs WHERE workflow = ?"
+	args := []any{workflowName}
+	if status != "" {
+		query += " AND status = ?"
+		args = append(args, status)
+	}
+	query += " ORDER BY created_at ASC"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workflow runs: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close workflow run rows", "error", closeErr)
+		}
+	}()
+
+	runs := []*workflow.Run{}
+	for rows.Next() {
+		run, err := scanSQLiteWorkflowRun(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan workflow run: %w", err)
+		}
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate workflow runs: %w", err)
+	}
+	return runs, nil
+}
+
+// scanSQLiteWorkflowRun scans a row selected with workflowRunColumns
+func scanSQLiteWorkflowRun(row rowScanner) (*workflow.Run, error) {
+	var run workflow.Run
+	var steps, values sql.NullString
+	var createdAt, updatedAt int64
+	if err := row.Scan(&run.RunID, &run.Workflow, &run.Status, &steps, &values, &run.Error, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+	run.CreatedAt = fromSQLiteTime(createdAt)
+	run.UpdatedAt = fromSQLiteTime(updatedAt)
+	if err := fromSQLiteJSON(steps, &run.Steps); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal steps JSON for workflow run %s: %w", run.RunID, err)
+	}
+	if err := fromSQLiteJSON(values, &run.Values); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal values JSON for workflow run %s: %w", run.RunID, err)
+	}
+	return &run, nil
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// SQLiteWorkspaceRepository implements WorkspaceRepository using SQLite
+type SQLiteWorkspaceRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteWorkspaceRepository creates a new SQLite workspace repository on a database opened with OpenSQLite
+func NewSQLiteWorkspaceRepository(db *sql.DB) WorkspaceRepository {
+	return &SQLiteWorkspaceRepository{db: db}
+}
+
+// CreateWorkspace records a workspace
+func (r *SQLiteWorkspaceRepository) CreateWorkspace(ctx context.Context, workspace *models.Workspace) error {
+	_, err := r.db.ExecContext(ctx, "INSERT INTO workspaces ("+workspaceColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		workspace.WorkspaceID, workspace.TaskID, workspace.CodebaseID, workspace.CommitSHA, workspace.Host,
+		workspace.Path, workspace.SizeBytes, workspace.Status, sqliteTime(workspace.CreatedAt), sqliteTime(workspace.LastUsedAt),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create workspace: %w", err)
+	}
+	return nil
+}
+
+// ClaimIdleWorkspace claims the most recently used matching idle workspace in one statement, so two tasks never share
+// a workspace
+func (r *SQLiteWorkspaceRepository) ClaimIdleWorkspace(ctx context.Context, host, codebaseID, commitSHA, taskID string) (*models.Workspace, error) {
+	workspace, err := scanSQLiteWorkspace(r.db.QueryRowContext(ctx, `
+		UPDATE workspaces SET status = ?, task_id = ?, last_used_at = ?
+		WHERE workspace_id = (
+			SELECT workspace_id FROM workspaces
+			WHERE host = ? AND codebase_id = ? AND commit_sha = ? AND status = ?
+			ORDER BY last_used_at DESC
+			LIMIT 1
+		)
+		RETURNING `+workspaceColumns,
+		models.WorkspaceStatusInUse, taskID, sqliteTime(time.Now().UTC()), host, codebaseID, commitSHA, models.WorkspaceStatusIdle,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim workspace: %w", err)
+	}
+	return workspace, nil
+}
+
+// ReleaseWorkspace marks a workspace idle and records its size
+func (r *SQLiteWorkspaceRepository) ReleaseWorkspace(ctx context.Context, workspaceID string, sizeBytes int64) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE workspaces SET status = ?, size_bytes = ?, last_used_at = ? WHERE workspace_id = ?",
+		models.WorkspaceStatusIdle, sizeBytes, sqliteTime(time.Now().UTC()), workspaceID)
+	if err != nil {
+		return fmt.Errorf("failed to release workspace: %w", err)
+	}
+	return nil
+}
+
+// DeleteWorkspace deletes a workspace
+func (r *SQLiteWorkspaceRepository) DeleteWorkspace(ctx context.Context, workspaceID string) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM workspaces WHERE workspace_id = ?", workspaceID); err != nil {
+		return fmt.Errorf("failed to delete workspace: %w", err)
+	}
+	return nil
+}
+
+// ListWorkspaces lists the workspaces of a host from least to most recently used
+func (r *SQLiteWorkspaceRepository) ListWorkspaces(ctx context.Context, host string) ([]models.Workspace, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+workspaceColumns+" FROM workspaces WHERE host = ? ORDER BY last_used_at, workspace_id", host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close workspace rows", "error", closeErr)
+		}
+	}()
+
+	workspaces := []models.Workspace{}
+	for rows.Next() {
+		workspace, err := scanSQLiteWorkspace(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan workspace: %w", err)
+		}
+		workspaces = append(workspaces, *workspace)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate workspaces: %w", err)
+	}
+	return workspaces, nil
+}
+
+// scanSQLiteWorkspace scans a row selected with workspaceColumns
+func scanSQLiteWorkspace(row rowScanner) (*models.Workspace, error) {
+	var workspace models.Workspace
+	var createdAt, lastUsedAt int64
+	if err := row.Scan(
+		&workspace.WorkspaceID, &workspace.TaskID, &workspace.CodebaseID, &workspace.CommitSHA, &workspace.Host,
+		&workspace.Path, &workspace.SizeBytes, &workspace.Status, &createdAt, &lastUsedAt,
+	); err != nil {
+		return nil, err
+	}
+	workspace.CreatedAt = fromSQLiteTime(createdAt)
+	workspace.LastUsedAt = fromSQLiteTime(lastUsedAt)
+	return &workspace, nil
+}
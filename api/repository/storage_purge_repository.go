@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// StoragePurgeRepository defines the interface for the purges of the object storage content of deleted entities
+//
+//go:generate mockgen -destination=./mocks/mock_storage_purge_repository.go -mock_names=StoragePurgeRepository=MockStoragePurgeRepository -package=mocks . StoragePurgeRepository
+type StoragePurgeRepository interface {
+	// CreatePurge records a pending purge
+	CreatePurge(ctx context.Context, purge *models.StoragePurge) error
+
+	// GetPurge gets a purge by ID
+	GetPurge(ctx context.Context, purgeID string) (*models.StoragePurge, error)
+
+	// ListPurges lists up to limit purges, newest first, only those with status unless it's empty
+	ListPurges(ctx context.Context, status models.StoragePurgeStatus, limit int) ([]models.StoragePurge, error)
+
+	// ClaimDue claims up to limit pending purges, and running purges whose lease expired, as running for the lease
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]models.StoragePurge, error)
+
+	// UpdatePurge records the status, progress and error of a purge, and extends its lease until leaseUntil
+	UpdatePurge(ctx context.Context, purge *models.StoragePurge, leaseUntil time.Time) error
+}
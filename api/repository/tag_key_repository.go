@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// TagKeyRepository defines the interface for tag key registry data operations
+//
+//go:generate mockgen -destination=./mocks/mock_tag_key_repository.go -mock_names=TagKeyRepository=MockTagKeyRepository -package=mocks . TagKeyRepository
+type TagKeyRepository interface {
+	// CreateTagKey registers a new tag key, failing with a conflict if it is already registered
+	CreateTagKey(ctx context.Context, tagKey *models.TagKey) error
+
+	// GetTagKey retrieves a registered tag key, returning nil if it is not registered
+	GetTagKey(ctx context.Context, key string) (*models.TagKey, error)
+
+	// UpdateTagKey replaces the rules of a registered tag key
+	UpdateTagKey(ctx context.Context, tagKey *models.TagKey) error
+
+	// DeleteTagKey unregisters a tag key
+	DeleteTagKey(ctx context.Context, key string) error
+
+	// ListTagKeys lists the registered tag keys ordered by key
+	ListTagKeys(ctx context.Context) ([]models.TagKey, error)
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// TaskCommentRepository defines the interface for task comment data operations
+//
+//go:generate mockgen -destination=./mocks/mock_task_comment_repository.go -mock_names=TaskCommentRepository=MockTaskCommentRepository -package=mocks . TaskCommentRepository
+type TaskCommentRepository interface {
+	// CreateComment stores a new comment
+	CreateComment(ctx context.Context, comment *models.TaskComment) error
+
+	// GetComment retrieves a comment by ID
+	GetComment(ctx context.Context, commentID string) (*models.TaskComment, error)
+
+	// UpdateComment replaces the body of an existing comment
+	UpdateComment(ctx context.Context, comment *models.TaskComment) error
+
+	// DeleteComment deletes a comment and its replies
+	DeleteComment(ctx context.Context, commentID string) error
+
+	// ListComments lists a task's comments oldest first
+	ListComments(ctx context.Context, taskID string) ([]models.TaskComment, error)
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// TaskFeedbackRepository defines the interface for task feedback data operations
+//
+//go:generate mockgen -destination=./mocks/mock_task_feedback_repository.go -mock_names=TaskFeedbackRepository=MockTaskFeedbackRepository -package=mocks . TaskFeedbackRepository
+type TaskFeedbackRepository interface {
+	// UpsertFeedback stores a user's feedback on a task, replacing the feedback they gave it before. The creation
+	// time of replaced feedback is kept, and feedback is updated with it.
+	UpsertFeedback(ctx context.Context, feedback *models.TaskFeedback) error
+
+	// ListFeedback lists the feedback on a task oldest first
+	ListFeedback(ctx context.Context, taskID string) ([]models.TaskFeedback, error)
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// TaskMetricsRepository defines the interface for the pre-aggregated daily task metrics used by reports
+//
+//go:generate mockgen -destination=./mocks/mock_task_metrics_repository.go -mock_names=TaskMetricsRepository=MockTaskMetricsRepository -package=mocks . TaskMetricsRepository
+type TaskMetricsRepository interface {
+	// RefreshRollups recomputes the daily rollups of every day from since onwards from the tasks table
+	RefreshRollups(ctx context.Context, since time.Time) error
+
+	// ListRollups lists the daily rollups between from and to, inclusive, optionally restricted to a project
+	ListRollups(ctx context.Context, projectID string, from, to time.Time) ([]TaskMetricsRollup, error)
+}
+
+// TaskMetricsRollup contains the metrics of the tasks a project created on one UTC day
+type TaskMetricsRollup struct {
+	ProjectID         string
+	Day               time.Time
+	Total             int
+	Completed         int
+	Failed            int
+	Cancelled         int
+	DurationMsTotal   int64 // Sum of creation to completion times of finished tasks
+	DurationCount     int   // Number of finished tasks in DurationMsTotal
+	TokenUsage        int64
+	FailureCategories map[string]int
+	Feedback          int   // Number of feedback given on the tasks
+	Accepted          int   // Number of feedback accepting the result
+	Rejected          int   // Number of feedback rejecting the result
+	RatingTotal       int64 // Sum of the ratings of the feedback
+	RefreshedAt       time.Time
+}
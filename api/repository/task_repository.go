@@ -2,20 +2,27 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/kazemisoroush/code-refactoring-tool/api/models"
 )
 
 // TaskRepository defines the interface for task data access operations
+//
+//go:generate mockgen -destination=./mocks/mock_task_repository.go -mock_names=TaskRepository=MockTaskRepository -package=mocks . TaskRepository
 type TaskRepository interface {
 	// Create creates a new task
 	Create(ctx context.Context, task *models.Task) error
 
+	// CreateBatch creates multiple tasks atomically; either all tasks are stored or none are
+	CreateBatch(ctx context.Context, tasks []*models.Task) error
+
 	// GetByID retrieves a task by its ID
 	GetByID(ctx context.Context, taskID string) (*models.Task, error)
 
-	// Update updates an existing task
-	Update(ctx context.Context, task *models.Task) error
+	// Update updates an existing task, recording the notification it raises, if any, in the notification outbox in the
+	// same transaction. A change of status the task's current status can't make is rejected like by UpdateStatus.
+	Update(ctx context.Context, task *models.Task, notification *models.Notification) error
 
 	// Delete deletes a task by its ID
 	Delete(ctx context.Context, taskID string) error
@@ -23,17 +30,64 @@ type TaskRepository interface {
 	// ListByProject lists tasks for a specific project with optional filters
 	ListByProject(ctx context.Context, projectID string, filters TaskFilters) ([]models.Task, int, error)
 
+	// StreamByProject passes every task of a project matching filters to fn, newest first, without loading them all
+	// in memory. Pagination filters are ignored, and an error returned by fn stops the stream.
+	StreamByProject(ctx context.Context, projectID string, filters TaskFilters, fn func(models.Task) error) error
+
 	// ListByAgent lists tasks for a specific agent
 	ListByAgent(ctx context.Context, agentID string, filters TaskFilters) ([]models.Task, int, error)
 
 	// ListByCodebase lists tasks for a specific codebase
 	ListByCodebase(ctx context.Context, codebaseID string, filters TaskFilters) ([]models.Task, int, error)
 
-	// UpdateStatus updates only the status of a task
-	UpdateStatus(ctx context.Context, taskID string, status models.TaskStatus) error
+	// ListByBatch lists all tasks created as part of the given batch
+	ListByBatch(ctx context.Context, batchID string) ([]models.Task, error)
+
+	// ListByCampaign lists the child tasks of the given campaign
+	ListByCampaign(ctx context.Context, campaignID string) ([]models.Task, error)
+
+	// ListByExperiment lists the tasks assigned to the given experiment
+	ListByExperiment(ctx context.Context, experimentID string) ([]models.Task, error)
+
+	// AssignExperiment records the experiment and arm a task was assigned to. A task keeps its first assignment, so a
+	// resumed execution doesn't change it.
+	AssignExperiment(ctx context.Context, taskID, experimentID string, arm models.ExperimentArm) error
+
+	// CountByProject counts the project's tasks created since the given time, grouped by status and type
+	CountByProject(ctx context.Context, projectID string, since time.Time) ([]TaskCount, error)
+
+	// ListRecentFailures lists the project's most recently failed tasks updated since the given time
+	ListRecentFailures(ctx context.Context, projectID string, since time.Time, limit int) ([]models.Task, error)
+
+	// UpdateStatus moves a task to status, recording the notification the transition raises, if any, in the
+	// notification outbox in the same transaction. A task whose current status can't move to status is left unchanged
+	// with an error of code CodeInvalidTaskTransition.
+	UpdateStatus(ctx context.Context, taskID string, status models.TaskStatus, notification *models.Notification) error
 
-	// UpdateStatusAndOutput updates the status and output of a task
-	UpdateStatusAndOutput(ctx context.Context, taskID string, status models.TaskStatus, output map[string]any, errorMessage *string) error
+	// UpdateStatusAndOutput moves a task to status with its output, like UpdateStatus, recording the notification it
+	// raises, if any, in the notification outbox in the same transaction
+	UpdateStatusAndOutput(ctx context.Context, taskID string, status models.TaskStatus, output map[string]any, errorMessage *string, notification *models.Notification) error
+
+	// NextQueued returns the queued task to run next, nil when no task is queued. Higher priority tasks come first,
+	// then the tasks of projects with fewer tasks in progress, then the longest queued.
+	NextQueued(ctx context.Context) (*models.Task, error)
+
+	// QueuePosition returns the position of a queued task in the order NextQueued returns them, 1 for the next, and 0
+	// when the task isn't queued
+	QueuePosition(ctx context.Context, taskID string) (int, error)
+
+	// Heartbeat records that the execution of an in_progress task is still running, with the percentage of its steps
+	// completed and the step it is running
+	Heartbeat(ctx context.Context, taskID string, progress int, step string) error
+
+	// ListStuck lists the in_progress tasks without a heartbeat since the given time, longest silent first. Tasks that
+	// never sent a heartbeat count from when they were last updated.
+	ListStuck(ctx context.Context, heartbeatBefore time.Time, limit int) ([]models.Task, error)
+
+	// RecoverStuck moves an in_progress task without a heartbeat since the given time to status, recording the
+	// notification it raises, if any, in the same transaction. It reports false, changing nothing, when the task is
+	// no longer stuck.
+	RecoverStuck(ctx context.Context, taskID string, heartbeatBefore time.Time, status models.TaskStatus, errorMessage *string, notification *models.Notification) (bool, error)
 }
 
 // TaskFilters represents filters for task queries
@@ -42,6 +96,15 @@ type TaskFilters struct {
 	Type       *models.TaskType   `json:"type,omitempty"`
 	AgentID    *string            `json:"agent_id,omitempty"`
 	CodebaseID *string            `json:"codebase_id,omitempty"`
+	Tags       map[string]string  `json:"tags,omitempty"`  // Tasks must carry all of these tags
+	Owner      *string            `json:"owner,omitempty"` // Tasks must have this owner among the owners in their output
 	Limit      int                `json:"limit"`
 	Offset     int                `json:"offset"`
 }
+
+// TaskCount is the number of tasks with a given status and type
+type TaskCount struct {
+	Status models.TaskStatus
+	Type   models.TaskType
+	Count  int
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// ListUserNotificationsOptions holds the options for listing a user's inbox notifications
+type ListUserNotificationsOptions struct {
+	UnreadOnly bool
+	NextToken  *string // Notification ID of the last notification of the previous page
+	MaxResults int
+}
+
+// UserNotificationRepository defines the interface for in-app notification inbox data operations
+//
+//go:generate mockgen -destination=./mocks/mock_user_notification_repository.go -mock_names=UserNotificationRepository=MockUserNotificationRepository -package=mocks . UserNotificationRepository
+type UserNotificationRepository interface {
+	// CreateNotification records a notification in a user's inbox, once per notification ID
+	CreateNotification(ctx context.Context, notification *models.UserNotification) error
+
+	// ListNotifications lists a user's notifications newest first, returning the token of the next page if there is one
+	ListNotifications(ctx context.Context, userID string, opts ListUserNotificationsOptions) ([]models.UserNotification, string, error)
+
+	// CountUnread counts a user's unread notifications
+	CountUnread(ctx context.Context, userID string) (int, error)
+
+	// MarkRead marks one of a user's notifications read; notifications that are already read keep their read time
+	MarkRead(ctx context.Context, userID, notificationID string, readAt time.Time) error
+}
@@ -23,10 +23,9 @@ type UserRepository interface {
 	ListUsers(ctx context.Context, filter *ListUsersFilter) ([]*models.DBUser, int, error)
 	UserExists(ctx context.Context, userID string) (bool, error)
 
-	// Project access operations
-	HasProjectAccess(ctx context.Context, userID, projectID, accessType string) (bool, error)
-	GrantProjectAccess(ctx context.Context, userID, projectID, accessType string) error
-	RevokeProjectAccess(ctx context.Context, userID, projectID string) error
+	// StreamUsers passes the users created within timeRange to fn, oldest first, without loading them all in memory.
+	// An error returned by fn stops the stream.
+	StreamUsers(ctx context.Context, timeRange TimeRange, fn func(*models.DBUser) error) error
 }
 
 // ListUsersFilter defines filtering options for listing users
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/workflow"
+)
+
+// WorkflowRunRepository persists the progress of workflow runs. It implements workflow.RunStore.
+//
+//go:generate mockgen -destination=./mocks/mock_workflow_run_repository.go -mock_names=WorkflowRunRepository=MockWorkflowRunRepository -package=mocks . WorkflowRunRepository
+type WorkflowRunRepository interface {
+	// GetRun retrieves a run, returning nil if it was never saved
+	GetRun(ctx context.Context, runID string) (*workflow.Run, error)
+
+	// SaveRun creates or replaces a run
+	SaveRun(ctx context.Context, run *workflow.Run) error
+
+	// ListRuns returns the runs of a workflow in the given status, or in any status when it's empty, oldest first
+	ListRuns(ctx context.Context, workflowName string, status workflow.RunStatus) ([]*workflow.Run, error)
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// WorkspaceRepository defines the interface for the workspaces codebases are cloned into on task runners
+//
+//go:generate mockgen -destination=./mocks/mock_workspace_repository.go -mock_names=WorkspaceRepository=MockWorkspaceRepository -package=mocks . WorkspaceRepository
+type WorkspaceRepository interface {
+	// CreateWorkspace records a workspace
+	CreateWorkspace(ctx context.Context, workspace *models.Workspace) error
+
+	// ClaimIdleWorkspace marks an idle workspace of the host holding the commit of a codebase in use by a task,
+	// returning nil when there is none
+	ClaimIdleWorkspace(ctx context.Context, host, codebaseID, commitSHA, taskID string) (*models.Workspace, error)
+
+	// ReleaseWorkspace marks a workspace idle and records its size
+	ReleaseWorkspace(ctx context.Context, workspaceID string, sizeBytes int64) error
+
+	// DeleteWorkspace deletes a workspace
+	DeleteWorkspace(ctx context.Context, workspaceID string) error
+
+	// ListWorkspaces lists the workspaces of a host from least to most recently used
+	ListWorkspaces(ctx context.Context, host string) ([]models.Workspace, error)
+}
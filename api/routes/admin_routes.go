@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"github.com/kazemisoroush/code-refactoring-tool/api/controllers"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// SetupAdminRoutes configures the operator routes, admitting only the callers holding the operations:manage permission
+func SetupAdminRoutes(api *VersionedRouter, workspaceController *controllers.WorkspaceController, taskController *controllers.TaskController, permissions middleware.PermissionEvaluator) {
+	adminGroup := api.Group(APIVersionV1, "/admin")
+	adminGroup.Use(middleware.NewPermissionMiddleware(permissions, models.PermissionOperationsManage).Handle())
+	{
+		// GET the disk usage of this task runner's workspaces
+		adminGroup.GET("/workspaces", workspaceController.GetWorkspaceUsage)
+
+		// LIST the tasks whose executions stopped sending heartbeats
+		adminGroup.GET("/tasks/stuck", taskController.ListStuckTasks)
+
+		// REQUEUE a stuck task - validate URI parameters using struct tags
+		adminGroup.POST("/tasks/:id/requeue",
+			middleware.NewURIValidationMiddleware[models.RequeueTaskRequest]().Handle(),
+			taskController.RequeueTask,
+		)
+	}
+}
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/kazemisoroush/code-refactoring-tool/api/controllers"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// SetupAgentResyncRoutes configures the project agent resync settings routes
+func SetupAgentResyncRoutes(api *VersionedRouter, controller *controllers.AgentResyncController) {
+	projectGroup := api.Group(APIVersionV1, "/projects")
+	{
+		// GET a project's agent resync settings - validate URI parameters using struct tags
+		projectGroup.GET("/:project_id/agent-resync",
+			middleware.NewURIValidationMiddleware[models.GetAgentResyncSettingsRequest]().Handle(),
+			controller.GetAgentResyncSettings,
+		)
+
+		// UPDATE a project's agent resync settings - validate URI parameters and JSON body using struct tags
+		projectGroup.PUT("/:project_id/agent-resync",
+			middleware.NewCombinedValidationMiddleware[models.UpdateAgentResyncSettingsRequest]().Handle(),
+			controller.UpdateAgentResyncSettings,
+		)
+	}
+}
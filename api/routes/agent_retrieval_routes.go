@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/kazemisoroush/code-refactoring-tool/api/controllers"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// SetupAgentRetrievalRoutes configures the routes debugging the context agents retrieve
+func SetupAgentRetrievalRoutes(api *VersionedRouter, controller *controllers.AgentRetrievalController) {
+	agentGroup := api.Group(APIVersionV1, "/agents")
+	{
+		// RETRIEVE an agent's context for a query - validate URI parameters and JSON body using struct tags
+		agentGroup.POST("/:agent_id/retrieve",
+			middleware.NewCombinedValidationMiddleware[models.RetrieveAgentChunksRequest]().Handle(),
+			controller.RetrieveChunks,
+		)
+	}
+}
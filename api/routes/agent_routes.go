@@ -1,14 +1,12 @@
 package routes
 
 import (
-	"github.com/gin-gonic/gin"
-
 	"github.com/kazemisoroush/code-refactoring-tool/api/controllers"
 )
 
 // SetupAgentRoutes configures the agent routes with validation middleware
-func SetupAgentRoutes(router *gin.Engine, controller *controllers.AgentController) {
-	agentGroup := router.Group("/api/v1/agents")
+func SetupAgentRoutes(api *VersionedRouter, controller *controllers.AgentController) {
+	agentGroup := api.Group(APIVersionV1, "/agents")
 	{
 		// CREATE - create a new agent
 		agentGroup.POST("", controller.CreateAgent)
@@ -22,6 +20,9 @@ func SetupAgentRoutes(router *gin.Engine, controller *controllers.AgentControlle
 		// UPDATE - update an existing agent
 		agentGroup.PUT("/:agent_id", controller.UpdateAgent)
 
+		// REBUILD - re-provision the agent's AI infrastructure
+		agentGroup.POST("/:agent_id/rebuild", controller.RebuildAgent)
+
 		// DELETE - delete an agent
 		agentGroup.DELETE("/:agent_id", controller.DeleteAgent)
 	}
@@ -0,0 +1,37 @@
+package routes
+
+import (
+	"github.com/kazemisoroush/code-refactoring-tool/api/controllers"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// SetupAgentSetupRoutes configures the routes of the setups that provision agent infrastructure
+func SetupAgentSetupRoutes(api *VersionedRouter, controller *controllers.AgentSetupController) {
+	setupGroup := api.Group(APIVersionV1, "/agent-setups")
+	{
+		// LIST agent setups - validate query parameters using struct tags
+		setupGroup.GET("",
+			middleware.NewQueryValidationMiddleware[models.ListAgentSetupsRequest]().Handle(),
+			controller.ListAgentSetups,
+		)
+
+		// GET an agent setup - validate URI parameters using struct tags
+		setupGroup.GET("/:setup_id",
+			middleware.NewURIValidationMiddleware[models.GetAgentSetupRequest]().Handle(),
+			controller.GetAgentSetup,
+		)
+
+		// RESUME a failed agent setup - validate URI parameters using struct tags
+		setupGroup.POST("/:setup_id/resume",
+			middleware.NewURIValidationMiddleware[models.ResumeAgentSetupRequest]().Handle(),
+			controller.ResumeAgentSetup,
+		)
+
+		// TEAR DOWN a failed agent setup - validate URI parameters using struct tags
+		setupGroup.POST("/:setup_id/teardown",
+			middleware.NewURIValidationMiddleware[models.TearDownAgentSetupRequest]().Handle(),
+			controller.TearDownAgentSetup,
+		)
+	}
+}
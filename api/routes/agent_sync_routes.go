@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/kazemisoroush/code-refactoring-tool/api/controllers"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// SetupAgentSyncRoutes configures the agent knowledge base sync routes
+func SetupAgentSyncRoutes(api *VersionedRouter, controller *controllers.AgentSyncController) {
+	agentGroup := api.Group(APIVersionV1, "/agents")
+	{
+		// GET an agent's sync status - validate URI and query parameters using struct tags
+		agentGroup.GET("/:agent_id/sync-status",
+			middleware.NewURIQueryValidationMiddleware[models.GetAgentSyncStatusRequest]().Handle(),
+			controller.GetSyncStatus,
+		)
+
+		// SYNC an agent's knowledge base - validate URI parameters using struct tags
+		agentGroup.POST("/:agent_id/sync",
+			middleware.NewURIValidationMiddleware[models.SyncAgentRequest]().Handle(),
+			controller.SyncAgent,
+		)
+	}
+}
@@ -8,29 +8,78 @@ import (
 	"github.com/kazemisoroush/code-refactoring-tool/api/models"
 )
 
-// RegisterAuthRoutes registers authentication-related routes
-func RegisterAuthRoutes(router *gin.Engine, authController *controllers.AuthController, authMiddleware middleware.Middleware) {
+// SetupAuthRoutes configures the authentication routes. Signing up, signing in and recovering an account are public,
+// the /auth/me routes require authentication, and managing users requires the user:read, user:create, user:update and
+// user:delete permissions.
+func SetupAuthRoutes(router *gin.Engine, authController *controllers.AuthController, authMiddleware middleware.Middleware, permissions middleware.PermissionEvaluator) {
+	canRead := middleware.NewPermissionMiddleware(permissions, models.PermissionUserRead)
+	canCreate := middleware.NewPermissionMiddleware(permissions, models.PermissionUserCreate)
+	canUpdate := middleware.NewPermissionMiddleware(permissions, models.PermissionUserUpdate)
+	canDelete := middleware.NewPermissionMiddleware(permissions, models.PermissionUserDelete)
+
 	authGroup := router.Group("/auth")
 	{
-		// Public routes (no authentication required)
-		authGroup.POST("/signup", authController.SignUp)
-		authGroup.POST("/signin", authController.SignIn)
-		authGroup.POST("/refresh", authController.RefreshToken)
-		authGroup.POST("/signout", authController.SignOut)
+		// Public routes (no authentication required) - validate JSON body using struct tags
+		authGroup.POST("/signup", middleware.NewJSONValidationMiddleware[models.SignUpRequest]().Handle(), authController.SignUp)
+		authGroup.POST("/signin", middleware.NewJSONValidationMiddleware[models.SignInRequest]().Handle(), authController.SignIn)
+		authGroup.POST("/refresh", middleware.NewJSONValidationMiddleware[models.RefreshTokenRequest]().Handle(), authController.RefreshToken)
+		authGroup.POST("/signout", middleware.NewJSONValidationMiddleware[models.SignOutRequest]().Handle(), authController.SignOut)
 		authGroup.POST("/confirm", middleware.NewJSONValidationMiddleware[models.ConfirmEmailRequest]().Handle(), authController.ConfirmEmail)
 		authGroup.POST("/forgot-password", middleware.NewJSONValidationMiddleware[models.ForgotPasswordRequest]().Handle(), authController.ForgotPassword)
 		authGroup.POST("/reset-password", middleware.NewJSONValidationMiddleware[models.ResetPasswordRequest]().Handle(), authController.ResetPassword)
+		authGroup.POST("/mfa/challenge", middleware.NewJSONValidationMiddleware[models.MFAChallengeRequest]().Handle(), authController.RespondToMFAChallenge)
+		authGroup.POST("/mfa/recover", middleware.NewJSONValidationMiddleware[models.MFARecoverRequest]().Handle(), authController.RecoverMFA)
 
 		// Protected routes (authentication required)
 		protected := authGroup.Group("")
 		protected.Use(authMiddleware.Handle())
 		{
 			protected.GET("/me", authController.GetMe)
-			protected.GET("/users", authController.ListUsers)
-			protected.POST("/users", authController.CreateUser)
-			protected.GET("/users/:id", authController.GetUser)
-			protected.PUT("/users/:id", authController.UpdateUser)
-			protected.DELETE("/users/:id", authController.DeleteUser)
+			protected.PUT("/me", middleware.NewJSONValidationMiddleware[models.UpdateProfileRequest]().Handle(), authController.UpdateMe)
+			protected.DELETE("/me", middleware.NewJSONValidationMiddleware[models.DeleteAccountRequest]().Handle(), authController.DeleteMe)
+			protected.POST("/me/password", middleware.NewJSONValidationMiddleware[models.ChangePasswordRequest]().Handle(), authController.ChangePassword)
+			protected.POST("/mfa/setup", middleware.NewJSONValidationMiddleware[models.MFASetupRequest]().Handle(), authController.SetupMFA)
+			protected.POST("/mfa/verify", middleware.NewJSONValidationMiddleware[models.VerifyMFARequest]().Handle(), authController.VerifyMFA)
+			protected.POST("/mfa/recovery-codes", authController.RegenerateRecoveryCodes)
+		}
+
+		// User management routes, authorized by the user permissions
+		users := protected.Group("/users")
+		{
+			// LIST users - validate query parameters using struct tags
+			users.GET("",
+				canRead.Handle(),
+				middleware.NewQueryValidationMiddleware[models.ListUsersRequest]().Handle(),
+				authController.ListUsers,
+			)
+
+			// CREATE a user - validate JSON body using struct tags
+			users.POST("",
+				canCreate.Handle(),
+				middleware.NewJSONValidationMiddleware[models.CreateUserRequest]().Handle(),
+				authController.CreateUser,
+			)
+
+			// GET by ID - validate URI parameters using struct tags
+			users.GET("/:id",
+				canRead.Handle(),
+				middleware.NewURIValidationMiddleware[models.GetUserRequest]().Handle(),
+				authController.GetUser,
+			)
+
+			// UPDATE a user - validate URI parameters and JSON body using struct tags
+			users.PUT("/:id",
+				canUpdate.Handle(),
+				middleware.NewCombinedValidationMiddleware[models.UpdateUserRequest]().Handle(),
+				authController.UpdateUser,
+			)
+
+			// DELETE a user - validate URI parameters using struct tags
+			users.DELETE("/:id",
+				canDelete.Handle(),
+				middleware.NewURIValidationMiddleware[models.DeleteUserRequest]().Handle(),
+				authController.DeleteUser,
+			)
 		}
 	}
 }
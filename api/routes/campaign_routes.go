@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/kazemisoroush/code-refactoring-tool/api/controllers"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// SetupCampaignRoutes configures the campaign routes with generic validation middleware
+func SetupCampaignRoutes(api *VersionedRouter, controller *controllers.CampaignController) {
+	campaignGroup := api.Group(APIVersionV1, "/campaigns")
+	{
+		// CREATE - validate JSON body using struct tags
+		campaignGroup.POST("",
+			middleware.NewJSONValidationMiddleware[models.CreateCampaignRequest]().Handle(),
+			controller.CreateCampaign,
+		)
+
+		// GET by ID - validate URI parameters using struct tags
+		campaignGroup.GET("/:id",
+			middleware.NewURIValidationMiddleware[models.GetCampaignRequest]().Handle(),
+			controller.GetCampaign,
+		)
+	}
+}
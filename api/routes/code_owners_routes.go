@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/kazemisoroush/code-refactoring-tool/api/controllers"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// SetupCodeOwnersRoutes configures the codebase file ownership routes
+func SetupCodeOwnersRoutes(api *VersionedRouter, controller *controllers.CodeOwnersController) {
+	codebaseGroup := api.Group(APIVersionV1, "/codebases")
+	{
+		// GET the owners of a codebase's files - validate URI and query parameters using struct tags
+		codebaseGroup.GET("/:id/owners",
+			middleware.NewURIQueryValidationMiddleware[models.GetCodeOwnersRequest]().Handle(),
+			controller.GetCodeOwners,
+		)
+	}
+}
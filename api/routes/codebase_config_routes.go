@@ -1,15 +1,14 @@
 package routes
 
 import (
-	"github.com/gin-gonic/gin"
 	"github.com/kazemisoroush/code-refactoring-tool/api/controllers"
 	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
 	"github.com/kazemisoroush/code-refactoring-tool/api/models"
 )
 
 // SetupCodebaseConfigRoutes configures the codebase configuration routes with generic validation middleware
-func SetupCodebaseConfigRoutes(router *gin.Engine, controller *controllers.CodebaseConfigController) {
-	codebaseConfigGroup := router.Group("/api/v1/codebase-configs")
+func SetupCodebaseConfigRoutes(api *VersionedRouter, controller *controllers.CodebaseConfigController) {
+	codebaseConfigGroup := api.Group(APIVersionV1, "/codebase-configs")
 	{
 		// CREATE - validate JSON body using struct tags
 		// The middleware automatically validates based on the struct tags in CreateCodebaseConfigRequest
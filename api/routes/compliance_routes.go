@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"github.com/kazemisoroush/code-refactoring-tool/api/controllers"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// SetupComplianceRoutes configures the compliance export routes. Exporting the users and the project access grants
+// requires the compliance:export permission and exporting the audit events audit:read.
+func SetupComplianceRoutes(api *VersionedRouter, controller *controllers.ComplianceController, permissions middleware.PermissionEvaluator) {
+	canExport := middleware.NewPermissionMiddleware(permissions, models.PermissionComplianceExport)
+	canReadAudit := middleware.NewPermissionMiddleware(permissions, models.PermissionAuditRead)
+
+	exportGroup := api.Group(APIVersionV1, "/admin/exports")
+	{
+		// EXPORT the users - validate query parameters using struct tags
+		exportGroup.GET("/users",
+			canExport.Handle(),
+			middleware.NewQueryValidationMiddleware[models.ComplianceExportRequest]().Handle(),
+			controller.ExportUsers,
+		)
+
+		// EXPORT the project access grants - validate query parameters using struct tags
+		exportGroup.GET("/access-grants",
+			canExport.Handle(),
+			middleware.NewQueryValidationMiddleware[models.ComplianceExportRequest]().Handle(),
+			controller.ExportAccessGrants,
+		)
+
+		// EXPORT the audit events - validate query parameters using struct tags
+		exportGroup.GET("/audit-events",
+			canReadAudit.Handle(),
+			middleware.NewQueryValidationMiddleware[models.ComplianceExportRequest]().Handle(),
+			controller.ExportAuditEvents,
+		)
+	}
+}
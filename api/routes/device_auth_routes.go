@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/controllers"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// RegisterDeviceAuthRoutes registers the device authorization flow routes. Devices start signing in and poll for
+// their tokens without authentication, while the user approving or denying the sign in is authenticated.
+func RegisterDeviceAuthRoutes(router *gin.Engine, deviceAuthController *controllers.DeviceAuthController, authMiddleware middleware.Middleware) {
+	deviceGroup := router.Group("/auth/device")
+	{
+		// Public routes (no authentication required)
+		deviceGroup.POST("/start", middleware.NewJSONValidationMiddleware[models.StartDeviceAuthRequest]().Handle(), deviceAuthController.StartDeviceAuth)
+		deviceGroup.POST("/token", middleware.NewJSONValidationMiddleware[models.DeviceTokenRequest]().Handle(), deviceAuthController.PollDeviceToken)
+
+		// Protected routes (authentication required)
+		protected := deviceGroup.Group("")
+		protected.Use(authMiddleware.Handle())
+		{
+			protected.POST("/approve", middleware.NewJSONValidationMiddleware[models.ApproveDeviceRequest]().Handle(), deviceAuthController.ApproveDevice)
+			protected.POST("/deny", middleware.NewJSONValidationMiddleware[models.DenyDeviceRequest]().Handle(), deviceAuthController.DenyDevice)
+		}
+	}
+}
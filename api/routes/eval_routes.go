@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"github.com/kazemisoroush/code-refactoring-tool/api/controllers"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// SetupEvalRoutes configures the evaluation routes, admitting only the callers holding the operations:manage permission
+func SetupEvalRoutes(api *VersionedRouter, controller *controllers.EvalController, permissions middleware.PermissionEvaluator) {
+	evalGroup := api.Group(APIVersionV1, "/admin/evals")
+	evalGroup.Use(middleware.NewPermissionMiddleware(permissions, models.PermissionOperationsManage).Handle())
+	{
+		// RUN the local agent against the fixtures - validate JSON body using struct tags
+		evalGroup.POST("/run",
+			middleware.NewJSONValidationMiddleware[models.RunEvalRequest]().Handle(),
+			controller.RunEval,
+		)
+
+		// LIST the evaluation runs - validate query parameters using struct tags
+		evalGroup.GET("/runs",
+			middleware.NewQueryValidationMiddleware[models.ListEvalRunsRequest]().Handle(),
+			controller.ListEvalRuns,
+		)
+
+		// GET an evaluation run - validate URI parameters using struct tags
+		evalGroup.GET("/runs/:id",
+			middleware.NewURIValidationMiddleware[models.GetEvalRunRequest]().Handle(),
+			controller.GetEvalRun,
+		)
+
+		// COMPARE a candidate evaluation run with a baseline - validate query parameters using struct tags
+		evalGroup.GET("/compare",
+			middleware.NewQueryValidationMiddleware[models.CompareEvalRunsRequest]().Handle(),
+			controller.CompareEvalRuns,
+		)
+	}
+}
@@ -0,0 +1,42 @@
+package routes
+
+import (
+	"github.com/kazemisoroush/code-refactoring-tool/api/controllers"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// SetupExperimentRoutes configures the experiment routes, admitting only the callers holding the operations:manage
+// permission
+func SetupExperimentRoutes(api *VersionedRouter, controller *controllers.ExperimentController, permissions middleware.PermissionEvaluator) {
+	experimentGroup := api.Group(APIVersionV1, "/admin/experiments")
+	experimentGroup.Use(middleware.NewPermissionMiddleware(permissions, models.PermissionOperationsManage).Handle())
+	{
+		// CREATE an experiment - validate JSON body using struct tags
+		experimentGroup.POST("",
+			middleware.NewJSONValidationMiddleware[models.CreateExperimentRequest]().Handle(),
+			controller.CreateExperiment,
+		)
+
+		// LIST the experiments
+		experimentGroup.GET("", controller.ListExperiments)
+
+		// GET an experiment - validate URI parameters using struct tags
+		experimentGroup.GET("/:id",
+			middleware.NewURIValidationMiddleware[models.GetExperimentRequest]().Handle(),
+			controller.GetExperiment,
+		)
+
+		// STOP an experiment - validate URI parameters using struct tags
+		experimentGroup.POST("/:id/stop",
+			middleware.NewURIValidationMiddleware[models.StopExperimentRequest]().Handle(),
+			controller.StopExperiment,
+		)
+
+		// GET the report of an experiment - validate URI parameters using struct tags
+		experimentGroup.GET("/:id/report",
+			middleware.NewURIValidationMiddleware[models.GetExperimentReportRequest]().Handle(),
+			controller.GetExperimentReport,
+		)
+	}
+}
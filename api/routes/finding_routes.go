@@ -0,0 +1,70 @@
+package routes
+
+import (
+	"github.com/kazemisoroush/code-refactoring-tool/api/controllers"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// SetupFindingRoutes configures the analysis finding routes with generic validation middleware. Reading findings and
+// their baseline requires the project:read permission, and reporting, triaging, deleting or rebaselining them
+// project:update.
+func SetupFindingRoutes(api *VersionedRouter, controller *controllers.FindingController, permissions middleware.PermissionEvaluator) {
+	canRead := middleware.NewPermissionMiddleware(permissions, models.PermissionProjectRead)
+	canUpdate := middleware.NewPermissionMiddleware(permissions, models.PermissionProjectUpdate)
+
+	codebaseGroup := api.Group(APIVersionV1, "/codebases")
+	{
+		// LIST the findings of a codebase - validate URI and query parameters using struct tags
+		codebaseGroup.GET("/:id/findings",
+			canRead.Handle(),
+			middleware.NewURIQueryValidationMiddleware[models.ListFindingsRequest]().Handle(),
+			controller.ListFindings,
+		)
+
+		// REPORT a finding by hand - validate both URI and JSON using struct tags
+		codebaseGroup.POST("/:id/findings",
+			canUpdate.Handle(),
+			middleware.NewCombinedValidationMiddleware[models.CreateFindingRequest]().Handle(),
+			controller.CreateFinding,
+		)
+
+		// GET the baseline and its suppressed findings - validate URI and query parameters using struct tags
+		codebaseGroup.GET("/:id/findings/baseline",
+			canRead.Handle(),
+			middleware.NewURIQueryValidationMiddleware[models.GetFindingBaselineRequest]().Handle(),
+			controller.GetBaseline,
+		)
+
+		// REBASELINE the open findings - validate URI parameters using struct tags
+		codebaseGroup.POST("/:id/findings/baseline",
+			canUpdate.Handle(),
+			middleware.NewURIValidationMiddleware[models.RebaselineFindingsRequest]().Handle(),
+			controller.Rebaseline,
+		)
+	}
+
+	findingGroup := api.Group(APIVersionV1, "/findings")
+	{
+		// GET by ID - validate URI parameters using struct tags
+		findingGroup.GET("/:finding_id",
+			canRead.Handle(),
+			middleware.NewURIValidationMiddleware[models.GetFindingRequest]().Handle(),
+			controller.GetFinding,
+		)
+
+		// UPDATE the status - validate both URI and JSON using struct tags
+		findingGroup.PUT("/:finding_id",
+			canUpdate.Handle(),
+			middleware.NewCombinedValidationMiddleware[models.UpdateFindingRequest]().Handle(),
+			controller.UpdateFinding,
+		)
+
+		// DELETE - validate URI parameters using struct tags
+		findingGroup.DELETE("/:finding_id",
+			canUpdate.Handle(),
+			middleware.NewURIValidationMiddleware[models.DeleteFindingRequest]().Handle(),
+			controller.DeleteFinding,
+		)
+	}
+}
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/kazemisoroush/code-refactoring-tool/api/controllers"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// SetupGenerationRoutes configures the routes generating text from changes with the configured model
+func SetupGenerationRoutes(api *VersionedRouter, controller *controllers.GenerationController) {
+	generationGroup := api.Group(APIVersionV1, "/generate")
+	{
+		// GENERATE a pull request description of a diff or task - validate JSON body using struct tags
+		generationGroup.POST("/pr-description",
+			middleware.NewJSONValidationMiddleware[models.GeneratePRDescriptionRequest]().Handle(),
+			controller.GeneratePRDescription,
+		)
+	}
+}
@@ -0,0 +1,44 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/controllers"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// SetupGitHubAppRoutes configures the GitHub App configuration routes, admitting only the callers holding the
+// integration:manage permission
+func SetupGitHubAppRoutes(api *VersionedRouter, controller *controllers.GitHubCheckController, permissions middleware.PermissionEvaluator) {
+	appGroup := api.Group(APIVersionV1, "/admin/github-apps")
+	appGroup.Use(middleware.NewPermissionMiddleware(permissions, models.PermissionIntegrationManage).Handle())
+	{
+		// LIST the GitHub Apps
+		appGroup.GET("", controller.ListGitHubApps)
+
+		// PUT the GitHub App of an organization - validate URI parameters and JSON body using struct tags
+		appGroup.PUT("/:organization",
+			middleware.NewCombinedValidationMiddleware[models.PutGitHubAppConfigRequest]().Handle(),
+			controller.PutGitHubApp,
+		)
+
+		// GET the GitHub App of an organization - validate URI parameters using struct tags
+		appGroup.GET("/:organization",
+			middleware.NewURIValidationMiddleware[models.GetGitHubAppConfigRequest]().Handle(),
+			controller.GetGitHubApp,
+		)
+
+		// DELETE the GitHub App of an organization - validate URI parameters using struct tags
+		appGroup.DELETE("/:organization",
+			middleware.NewURIValidationMiddleware[models.DeleteGitHubAppConfigRequest]().Handle(),
+			controller.DeleteGitHubApp,
+		)
+	}
+}
+
+// RegisterGitHubWebhookRoutes registers the GitHub webhook route, which is public as deliveries are authenticated by
+// their signature
+func RegisterGitHubWebhookRoutes(router *gin.Engine, controller *controllers.GitHubCheckController) {
+	router.POST("/webhooks/github", controller.HandleWebhook)
+}
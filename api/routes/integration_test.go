@@ -79,6 +79,6 @@ func TestValidationIntegration(t *testing.T) {
 		var response map[string]interface{}
 		err = json.Unmarshal(w.Body.Bytes(), &response)
 		require.NoError(t, err)
-		assert.Contains(t, response["details"].(string), "Name is required")
+		assert.Contains(t, response["detail"].(string), "Name is required")
 	})
 }
@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"github.com/kazemisoroush/code-refactoring-tool/api/controllers"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// SetupJiraRoutes configures the Jira site routes, admitting only the callers holding the integration:manage permission
+func SetupJiraRoutes(api *VersionedRouter, controller *controllers.JiraController, permissions middleware.PermissionEvaluator) {
+	siteGroup := api.Group(APIVersionV1, "/admin/jira-sites")
+	siteGroup.Use(middleware.NewPermissionMiddleware(permissions, models.PermissionIntegrationManage).Handle())
+	{
+		// LIST the Jira sites
+		siteGroup.GET("", controller.ListJiraSites)
+
+		// PUT the Jira site of an organization - validate URI parameters and JSON body using struct tags
+		siteGroup.PUT("/:organization",
+			middleware.NewCombinedValidationMiddleware[models.PutJiraSiteRequest]().Handle(),
+			controller.PutJiraSite,
+		)
+
+		// GET the Jira site of an organization - validate URI parameters using struct tags
+		siteGroup.GET("/:organization",
+			middleware.NewURIValidationMiddleware[models.GetJiraSiteRequest]().Handle(),
+			controller.GetJiraSite,
+		)
+
+		// DELETE the Jira site of an organization - validate URI parameters using struct tags
+		siteGroup.DELETE("/:organization",
+			middleware.NewURIValidationMiddleware[models.DeleteJiraSiteRequest]().Handle(),
+			controller.DeleteJiraSite,
+		)
+	}
+}
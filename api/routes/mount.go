@@ -0,0 +1,149 @@
+package routes
+
+import (
+	"github.com/kazemisoroush/code-refactoring-tool/api/controllers"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+)
+
+// Controllers are the controllers serving the routes of the API
+type Controllers struct {
+	Project         *controllers.ProjectController
+	ProjectTemplate *controllers.ProjectTemplateController
+	ProjectManifest *controllers.ProjectManifestController
+	ProjectSummary  *controllers.ProjectSummaryController
+	ProjectMember   *controllers.ProjectMemberController
+	Redaction       *controllers.RedactionController
+	QualityGate     *controllers.QualityGateController
+	AgentResync     *controllers.AgentResyncController
+	Codebase        *controllers.CodebaseController
+	CodebaseConfig  *controllers.CodebaseConfigController
+	Finding         *controllers.FindingController
+	CodeOwners      *controllers.CodeOwnersController
+	Agent           *controllers.AgentController
+	AgentSync       *controllers.AgentSyncController
+	AgentRetrieval  *controllers.AgentRetrievalController
+	AgentSetup      *controllers.AgentSetupController
+	Task            *controllers.TaskController
+	Campaign        *controllers.CampaignController
+	Report          *controllers.ReportController
+	Generation      *controllers.GenerationController
+	Upload          *controllers.UploadController
+	Notification    *controllers.NotificationController
+	Role            *controllers.RoleController
+	Tag             *controllers.TagController
+	Workspace       *controllers.WorkspaceController
+	Compliance      *controllers.ComplianceController
+	Eval            *controllers.EvalController
+	Experiment      *controllers.ExperimentController
+	GitHubCheck     *controllers.GitHubCheckController
+	Jira            *controllers.JiraController
+	ServiceAccount  *controllers.ServiceAccountController
+	Storage         *controllers.StorageController
+	Auth            *controllers.AuthController
+	DeviceAuth      *controllers.DeviceAuthController
+	Health          *controllers.HealthController
+}
+
+// Mount registers every route of the API: the versioned routes under /api/{version}, and the authentication,
+// webhook, health and documentation routes at the root. Project, admin and user management routes are authorized by
+// permissions. The OpenAPI document must describe every route mounted here.
+func Mount(api *VersionedRouter, c Controllers, permissions middleware.PermissionEvaluator, authMiddleware middleware.Middleware) {
+	router := api.Engine()
+
+	// Setup project routes with validation middleware
+	SetupProjectRoutes(api, c.Project, permissions)
+
+	// Setup project template and bootstrap routes with validation middleware
+	SetupProjectTemplateRoutes(api, c.ProjectTemplate)
+
+	// Setup project export and import routes with validation middleware
+	SetupProjectManifestRoutes(api, c.ProjectManifest)
+
+	// Setup project dashboard summary routes with validation middleware
+	SetupProjectSummaryRoutes(api, c.ProjectSummary, permissions)
+
+	// Setup project redaction policy and audit routes with validation middleware
+	SetupRedactionRoutes(api, c.Redaction, permissions)
+
+	// Setup project severity rule and quality gate routes with validation middleware
+	SetupQualityGateRoutes(api, c.QualityGate, permissions)
+
+	// Setup project agent resync settings routes with validation middleware
+	SetupAgentResyncRoutes(api, c.AgentResync)
+
+	// Setup codebase routes with validation middleware
+	SetupCodebaseRoutes(api, c.Codebase, permissions)
+
+	// Setup codebase configuration routes with validation middleware
+	SetupCodebaseConfigRoutes(api, c.CodebaseConfig)
+
+	// Setup analysis finding routes with validation middleware
+	SetupFindingRoutes(api, c.Finding, permissions)
+
+	// Setup codebase file ownership routes with validation middleware
+	SetupCodeOwnersRoutes(api, c.CodeOwners)
+
+	// Setup agent routes with validation middleware
+	SetupAgentRoutes(api, c.Agent)
+
+	// Setup agent knowledge base sync routes with validation middleware
+	SetupAgentSyncRoutes(api, c.AgentSync)
+
+	// Setup agent retrieval debugging routes with validation middleware
+	SetupAgentRetrievalRoutes(api, c.AgentRetrieval)
+
+	// Setup agent setup routes to resume or tear down failed setups
+	SetupAgentSetupRoutes(api, c.AgentSetup)
+
+	// Setup task routes with validation middleware
+	SetupTaskRoutes(api, c.Task, permissions)
+
+	// Setup campaign routes with validation middleware
+	SetupCampaignRoutes(api, c.Campaign)
+
+	// Setup report routes with validation middleware
+	SetupReportRoutes(api, c.Report)
+
+	// Setup task input upload routes with validation middleware
+	SetupUploadRoutes(api, c.Upload, permissions)
+
+	// Setup pull request description generation routes with validation middleware
+	SetupGenerationRoutes(api, c.Generation)
+
+	// Setup notification inbox and channel routes with validation middleware
+	SetupNotificationRoutes(api, c.Notification)
+
+	// Setup role and permission routes
+	SetupRoleRoutes(api, c.Role, permissions)
+
+	// Setup project member and ownership transfer routes
+	SetupProjectMemberRoutes(api, c.ProjectMember, permissions)
+
+	// Setup tag key registry routes with validation middleware
+	SetupTagRoutes(api, c.Tag, permissions)
+
+	// Setup admin workspace and stuck task routes
+	SetupAdminRoutes(api, c.Workspace, c.Task, permissions)
+
+	// Setup compliance export routes
+	SetupComplianceRoutes(api, c.Compliance, permissions)
+	SetupEvalRoutes(api, c.Eval, permissions)
+	SetupExperimentRoutes(api, c.Experiment, permissions)
+	SetupGitHubAppRoutes(api, c.GitHubCheck, permissions)
+	SetupJiraRoutes(api, c.Jira, permissions)
+	SetupServiceAccountRoutes(api, c.ServiceAccount, permissions)
+	SetupStorageRoutes(api, c.Storage, permissions)
+
+	// Setup auth routes with validation middleware
+	SetupAuthRoutes(router, c.Auth, authMiddleware, permissions)
+	RegisterDeviceAuthRoutes(router, c.DeviceAuth, authMiddleware)
+
+	// Setup the GitHub webhook route, authenticated by the signature of its deliveries
+	RegisterGitHubWebhookRoutes(router, c.GitHubCheck)
+
+	// Setup health routes with validation middleware
+	SetupHealthRoutes(router, c.Health)
+
+	// Setup the Swagger UI and the OpenAPI document
+	SetupDocsRoutes(router)
+}
@@ -0,0 +1,58 @@
+package routes
+
+import (
+	"github.com/kazemisoroush/code-refactoring-tool/api/controllers"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// SetupNotificationRoutes configures the notification inbox and channel routes with generic validation middleware
+func SetupNotificationRoutes(api *VersionedRouter, controller *controllers.NotificationController) {
+	inboxGroup := api.Group(APIVersionV1, "/notifications")
+	{
+		// LIST - validate query parameters using struct tags
+		inboxGroup.GET("",
+			middleware.NewQueryValidationMiddleware[models.ListNotificationsRequest]().Handle(),
+			controller.ListNotifications,
+		)
+
+		// MARK READ - validate URI parameters using struct tags
+		inboxGroup.POST("/:notification_id/read",
+			middleware.NewURIValidationMiddleware[models.MarkNotificationReadRequest]().Handle(),
+			controller.MarkNotificationRead,
+		)
+	}
+
+	channelGroup := api.Group(APIVersionV1, "/notifications/channels")
+	{
+		// CREATE - validate JSON body using struct tags
+		channelGroup.POST("",
+			middleware.NewJSONValidationMiddleware[models.CreateNotificationChannelRequest]().Handle(),
+			controller.CreateChannel,
+		)
+
+		// LIST - validate query parameters using struct tags
+		channelGroup.GET("",
+			middleware.NewQueryValidationMiddleware[models.ListNotificationChannelsRequest]().Handle(),
+			controller.ListChannels,
+		)
+
+		// GET by ID - validate URI parameters using struct tags
+		channelGroup.GET("/:channel_id",
+			middleware.NewURIValidationMiddleware[models.GetNotificationChannelRequest]().Handle(),
+			controller.GetChannel,
+		)
+
+		// UPDATE - validate both URI and JSON using struct tags
+		channelGroup.PUT("/:channel_id",
+			middleware.NewCombinedValidationMiddleware[models.UpdateNotificationChannelRequest]().Handle(),
+			controller.UpdateChannel,
+		)
+
+		// DELETE - validate URI parameters using struct tags
+		channelGroup.DELETE("/:channel_id",
+			middleware.NewURIValidationMiddleware[models.DeleteNotificationChannelRequest]().Handle(),
+			controller.DeleteChannel,
+		)
+	}
+}
@@ -0,0 +1,86 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/docs"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// undocumentedRoutes are the mounted routes serving the documentation itself
+var undocumentedRoutes = map[string]bool{
+	"GET /swagger/*any": true,
+	"GET /openapi.json": true,
+}
+
+// ginParam matches the parameters of gin paths, which are written {name} in OpenAPI paths
+var ginParam = regexp.MustCompile(`:([A-Za-z0-9_]+)`)
+
+func mountedRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	Mount(NewVersionedRouter(router, nil), Controllers{}, nil,
+		middleware.NewAuthMiddleware(nil, nil, config.AuthConfig{}))
+	return router
+}
+
+func TestOpenAPI_DescribesEveryMountedRoute(t *testing.T) {
+	var document struct {
+		OpenAPI string                                `json:"openapi"`
+		Paths   map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(docs.OpenAPI, &document))
+	assert.Equal(t, "3.0.3", document.OpenAPI)
+
+	documented := map[string]bool{}
+	for path, operations := range document.Paths {
+		for method := range operations {
+			documented[strings.ToUpper(method)+" "+path] = true
+		}
+	}
+
+	mounted := map[string]bool{}
+	var missing []string
+	for _, route := range mountedRouter().Routes() {
+		key := route.Method + " " + route.Path
+		if undocumentedRoutes[key] {
+			continue
+		}
+		key = route.Method + " " + ginParam.ReplaceAllString(route.Path, "{$1}")
+		mounted[key] = true
+		if !documented[key] {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+	assert.Empty(t, missing, "mounted routes missing from docs/openapi.json; annotate their handlers and run make swagger")
+
+	var stale []string
+	for key := range documented {
+		if !mounted[key] {
+			stale = append(stale, key)
+		}
+	}
+	sort.Strings(stale)
+	assert.Empty(t, stale, "operations of docs/openapi.json that aren't mounted")
+}
+
+func TestOpenAPI_IsServed(t *testing.T) {
+	w := httptest.NewRecorder()
+	mountedRouter().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, docs.OpenAPI, w.Body.Bytes())
+}
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/kazemisoroush/code-refactoring-tool/api/controllers"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// SetupProjectManifestRoutes configures the project export and import routes
+func SetupProjectManifestRoutes(api *VersionedRouter, controller *controllers.ProjectManifestController) {
+	projectGroup := api.Group(APIVersionV1, "/projects")
+	{
+		// EXPORT a project manifest - validate URI parameters using struct tags
+		projectGroup.GET("/:project_id/export",
+			middleware.NewURIValidationMiddleware[models.ExportProjectRequest]().Handle(),
+			controller.ExportProject,
+		)
+
+		// IMPORT a project manifest - body may be JSON or YAML depending on Content-Type
+		projectGroup.POST("/import",
+			middleware.NewJSONValidationMiddleware[models.ProjectManifest]().Handle(),
+			controller.ImportProject,
+		)
+	}
+}
@@ -0,0 +1,45 @@
+package routes
+
+import (
+	"github.com/kazemisoroush/code-refactoring-tool/api/controllers"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// SetupProjectMemberRoutes configures the project member and ownership routes. Permissions are evaluated within the
+// project, so its owner and members with a suitable role can manage them.
+func SetupProjectMemberRoutes(api *VersionedRouter, controller *controllers.ProjectMemberController, permissions middleware.PermissionEvaluator) {
+	v1 := api.Version(APIVersionV1)
+	{
+		projectGroup := v1.Group("/projects/:project_id")
+		{
+			// LIST the members of a project - validate URI parameters using struct tags
+			projectGroup.GET("/members",
+				middleware.NewPermissionMiddleware(permissions, models.PermissionProjectRead).Handle(),
+				middleware.NewURIValidationMiddleware[models.ListProjectMembersRequest]().Handle(),
+				controller.ListProjectMembers,
+			)
+
+			// ADD a member or change their role - validate URI parameters and JSON body using struct tags
+			projectGroup.POST("/members",
+				middleware.NewPermissionMiddleware(permissions, models.PermissionMemberManage).Handle(),
+				middleware.NewCombinedValidationMiddleware[models.AddProjectMemberRequest]().Handle(),
+				controller.AddProjectMember,
+			)
+
+			// REMOVE a member - validate URI parameters using struct tags
+			projectGroup.DELETE("/members/:user_id",
+				middleware.NewPermissionMiddleware(permissions, models.PermissionMemberManage).Handle(),
+				middleware.NewURIValidationMiddleware[models.RemoveProjectMemberRequest]().Handle(),
+				controller.RemoveProjectMember,
+			)
+
+			// TRANSFER the ownership - validate URI parameters and JSON body using struct tags
+			projectGroup.POST("/transfer-ownership",
+				middleware.NewPermissionMiddleware(permissions, models.PermissionProjectTransfer).Handle(),
+				middleware.NewCombinedValidationMiddleware[models.TransferProjectOwnershipRequest]().Handle(),
+				controller.TransferProjectOwnership,
+			)
+		}
+	}
+}
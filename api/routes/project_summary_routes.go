@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/kazemisoroush/code-refactoring-tool/api/controllers"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// SetupProjectSummaryRoutes configures the project dashboard routes, which require the project:read permission within
+// the project
+func SetupProjectSummaryRoutes(api *VersionedRouter, controller *controllers.ProjectSummaryController, permissions middleware.PermissionEvaluator) {
+	projectGroup := api.Group(APIVersionV1, "/projects")
+	{
+		// GET a project dashboard summary - validate URI and query parameters using struct tags
+		projectGroup.GET("/:project_id/summary",
+			middleware.NewPermissionMiddleware(permissions, models.PermissionProjectRead).Handle(),
+			middleware.NewURIQueryValidationMiddleware[models.GetProjectSummaryRequest]().Handle(),
+			controller.GetProjectSummary,
+		)
+	}
+}
@@ -0,0 +1,37 @@
+package routes
+
+import (
+	"github.com/kazemisoroush/code-refactoring-tool/api/controllers"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// SetupProjectTemplateRoutes configures the project template and bootstrap routes
+func SetupProjectTemplateRoutes(api *VersionedRouter, controller *controllers.ProjectTemplateController) {
+	templateGroup := api.Group(APIVersionV1, "/project-templates")
+	{
+		// LIST built-in and custom templates
+		templateGroup.GET("",
+			middleware.NewQueryValidationMiddleware[models.ListProjectTemplatesRequest]().Handle(),
+			controller.ListProjectTemplates,
+		)
+
+		// CREATE a custom template - validate JSON body using struct tags
+		templateGroup.POST("",
+			middleware.NewJSONValidationMiddleware[models.CreateProjectTemplateRequest]().Handle(),
+			controller.CreateProjectTemplate,
+		)
+
+		// GET by ID - validate URI parameters using struct tags
+		templateGroup.GET("/:template_id",
+			middleware.NewURIValidationMiddleware[models.GetProjectTemplateRequest]().Handle(),
+			controller.GetProjectTemplate,
+		)
+	}
+
+	// BOOTSTRAP a project from a template - validate JSON body using struct tags
+	api.Version(APIVersionV1).POST("/projects/from-template",
+		middleware.NewJSONValidationMiddleware[models.CreateProjectFromTemplateRequest]().Handle(),
+		controller.CreateProjectFromTemplate,
+	)
+}
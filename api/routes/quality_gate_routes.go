@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/kazemisoroush/code-refactoring-tool/api/controllers"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// SetupQualityGateRoutes configures the project severity rule and quality gate routes. Reading the policy requires the
+// project:read permission within the project and changing it project:update.
+func SetupQualityGateRoutes(api *VersionedRouter, controller *controllers.QualityGateController, permissions middleware.PermissionEvaluator) {
+	projectGroup := api.Group(APIVersionV1, "/projects")
+	{
+		// GET a project's quality gate policy - validate URI parameters using struct tags
+		projectGroup.GET("/:project_id/quality-gates",
+			middleware.NewPermissionMiddleware(permissions, models.PermissionProjectRead).Handle(),
+			middleware.NewURIValidationMiddleware[models.GetQualityGatePolicyRequest]().Handle(),
+			controller.GetQualityGatePolicy,
+		)
+
+		// UPDATE a project's quality gate policy - validate URI parameters and JSON body using struct tags
+		projectGroup.PUT("/:project_id/quality-gates",
+			middleware.NewPermissionMiddleware(permissions, models.PermissionProjectUpdate).Handle(),
+			middleware.NewCombinedValidationMiddleware[models.UpdateQualityGatePolicyRequest]().Handle(),
+			controller.UpdateQualityGatePolicy,
+		)
+	}
+}
@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"github.com/kazemisoroush/code-refactoring-tool/api/controllers"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// SetupRedactionRoutes configures the project redaction policy and audit routes. Reading the policy and audits
+// requires the project:read permission within the project and changing the policy project:update.
+func SetupRedactionRoutes(api *VersionedRouter, controller *controllers.RedactionController, permissions middleware.PermissionEvaluator) {
+	canRead := middleware.NewPermissionMiddleware(permissions, models.PermissionProjectRead)
+	canUpdate := middleware.NewPermissionMiddleware(permissions, models.PermissionProjectUpdate)
+
+	projectGroup := api.Group(APIVersionV1, "/projects")
+	{
+		// GET a project's redaction policy - validate URI parameters using struct tags
+		projectGroup.GET("/:project_id/redaction-policy",
+			canRead.Handle(),
+			middleware.NewURIValidationMiddleware[models.GetRedactionPolicyRequest]().Handle(),
+			controller.GetRedactionPolicy,
+		)
+
+		// UPDATE a project's redaction policy - validate URI parameters and JSON body using struct tags
+		projectGroup.PUT("/:project_id/redaction-policy",
+			canUpdate.Handle(),
+			middleware.NewCombinedValidationMiddleware[models.UpdateRedactionPolicyRequest]().Handle(),
+			controller.UpdateRedactionPolicy,
+		)
+
+		// LIST a project's redaction audits - validate URI and query parameters using struct tags
+		projectGroup.GET("/:project_id/redaction-audits",
+			canRead.Handle(),
+			middleware.NewURIQueryValidationMiddleware[models.ListRedactionAuditsRequest]().Handle(),
+			controller.ListRedactionAudits,
+		)
+	}
+}
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/kazemisoroush/code-refactoring-tool/api/controllers"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// SetupReportRoutes configures the reporting routes
+func SetupReportRoutes(api *VersionedRouter, controller *controllers.ReportController) {
+	reportGroup := api.Group(APIVersionV1, "/reports")
+	{
+		// GET task metrics - validate query parameters using struct tags
+		reportGroup.GET("/tasks",
+			middleware.NewQueryValidationMiddleware[models.GetTaskReportRequest]().Handle(),
+			controller.GetTaskReport,
+		)
+	}
+}
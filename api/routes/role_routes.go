@@ -0,0 +1,54 @@
+package routes
+
+import (
+	"github.com/kazemisoroush/code-refactoring-tool/api/controllers"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// SetupRoleRoutes configures the role and permission routes. Reading them requires the role:read permission and
+// changing them role:manage.
+func SetupRoleRoutes(api *VersionedRouter, controller *controllers.RoleController, permissions middleware.PermissionEvaluator) {
+	canRead := middleware.NewPermissionMiddleware(permissions, models.PermissionRoleRead)
+	canManage := middleware.NewPermissionMiddleware(permissions, models.PermissionRoleManage)
+
+	v1 := api.Version(APIVersionV1)
+	{
+		// LIST the permissions roles can grant
+		v1.GET("/permissions", canRead.Handle(), controller.ListPermissions)
+
+		roleGroup := v1.Group("/roles")
+		{
+			// LIST the roles
+			roleGroup.GET("", canRead.Handle(), controller.ListRoles)
+
+			// GET by name - validate URI parameters using struct tags
+			roleGroup.GET("/:name",
+				canRead.Handle(),
+				middleware.NewURIValidationMiddleware[models.GetRoleRequest]().Handle(),
+				controller.GetRole,
+			)
+
+			// CREATE a custom role - validate JSON body using struct tags
+			roleGroup.POST("",
+				canManage.Handle(),
+				middleware.NewJSONValidationMiddleware[models.CreateRoleRequest]().Handle(),
+				controller.CreateRole,
+			)
+
+			// UPDATE the permissions of a role - validate URI parameters and JSON body using struct tags
+			roleGroup.PUT("/:name",
+				canManage.Handle(),
+				middleware.NewCombinedValidationMiddleware[models.UpdateRoleRequest]().Handle(),
+				controller.UpdateRole,
+			)
+
+			// DELETE a custom role - validate URI parameters using struct tags
+			roleGroup.DELETE("/:name",
+				canManage.Handle(),
+				middleware.NewURIValidationMiddleware[models.DeleteRoleRequest]().Handle(),
+				controller.DeleteRole,
+			)
+		}
+	}
+}
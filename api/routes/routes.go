@@ -9,12 +9,14 @@ import (
 
 // SetupProjectRoutes configures the project routes with generic validation middleware
 // This demonstrates the new annotation-based validation approach
-func SetupProjectRoutes(router *gin.Engine, controller *controllers.ProjectController) {
-	projectGroup := router.Group("/api/v1/projects")
+// Each route requires a project permission, evaluated by permissions within the project when it has one
+func SetupProjectRoutes(api *VersionedRouter, controller *controllers.ProjectController, permissions middleware.PermissionEvaluator) {
+	projectGroup := api.Group(APIVersionV1, "/projects")
 	{
 		// CREATE - validate JSON body using struct tags
 		// The middleware automatically validates based on the struct tags in CreateProjectRequest
 		projectGroup.POST("",
+			middleware.NewPermissionMiddleware(permissions, models.PermissionProjectCreate).Handle(),
 			middleware.NewJSONValidationMiddleware[models.CreateProjectRequest]().Handle(),
 			controller.CreateProject,
 		)
@@ -22,6 +24,7 @@ func SetupProjectRoutes(router *gin.Engine, controller *controllers.ProjectContr
 		// LIST - validate query parameters using struct tags
 		// The middleware automatically validates based on the struct tags in ListProjectsRequest
 		projectGroup.GET("",
+			middleware.NewPermissionMiddleware(permissions, models.PermissionProjectRead).Handle(),
 			middleware.NewQueryValidationMiddleware[models.ListProjectsRequest]().Handle(),
 			controller.ListProjects,
 		)
@@ -29,6 +32,7 @@ func SetupProjectRoutes(router *gin.Engine, controller *controllers.ProjectContr
 		// GET by ID - validate URI parameters using struct tags
 		// The middleware automatically validates based on the struct tags in GetProjectRequest
 		projectGroup.GET("/:project_id",
+			middleware.NewPermissionMiddleware(permissions, models.PermissionProjectRead).Handle(),
 			middleware.NewURIValidationMiddleware[models.GetProjectRequest]().Handle(),
 			controller.GetProject,
 		)
@@ -36,6 +40,7 @@ func SetupProjectRoutes(router *gin.Engine, controller *controllers.ProjectContr
 		// UPDATE - validate both URI and JSON using struct tags
 		// The middleware automatically validates based on the struct tags in UpdateProjectRequest
 		projectGroup.PUT("/:project_id",
+			middleware.NewPermissionMiddleware(permissions, models.PermissionProjectUpdate).Handle(),
 			middleware.NewCombinedValidationMiddleware[models.UpdateProjectRequest]().Handle(),
 			controller.UpdateProject,
 		)
@@ -43,29 +48,51 @@ func SetupProjectRoutes(router *gin.Engine, controller *controllers.ProjectContr
 		// DELETE - validate URI parameters using struct tags
 		// The middleware automatically validates based on the struct tags in DeleteProjectRequest
 		projectGroup.DELETE("/:project_id",
+			middleware.NewPermissionMiddleware(permissions, models.PermissionProjectDelete).Handle(),
 			middleware.NewURIValidationMiddleware[models.DeleteProjectRequest]().Handle(),
 			controller.DeleteProject,
 		)
+
+		// ARCHIVE - validate URI and query parameters using struct tags
+		projectGroup.POST("/:project_id/archive",
+			middleware.NewPermissionMiddleware(permissions, models.PermissionProjectUpdate).Handle(),
+			middleware.NewURIQueryValidationMiddleware[models.ArchiveProjectRequest]().Handle(),
+			controller.ArchiveProject,
+		)
+
+		// UNARCHIVE - validate URI parameters using struct tags
+		projectGroup.POST("/:project_id/unarchive",
+			middleware.NewPermissionMiddleware(permissions, models.PermissionProjectUpdate).Handle(),
+			middleware.NewURIValidationMiddleware[models.UnarchiveProjectRequest]().Handle(),
+			controller.UnarchiveProject,
+		)
 	}
 }
 
 // Example of how the same validation middleware can be extended for other entities
 // Just create your models with appropriate validation tags and use the generic middleware
 
-// SetupCodebaseRoutes configures the codebase routes with generic validation middleware
-func SetupCodebaseRoutes(router *gin.Engine, controller *controllers.CodebaseController) {
+// SetupCodebaseRoutes configures the codebase routes with generic validation middleware. Browsing the repository of a
+// codebase requires the project:read permission.
+func SetupCodebaseRoutes(api *VersionedRouter, controller *controllers.CodebaseController, permissions middleware.PermissionEvaluator) {
 	// Codebase routes nested under projects
-	projectCodebaseGroup := router.Group("/api/v1/projects/:project_id/codebases")
+	projectCodebaseGroup := api.Group(APIVersionV1, "/projects/:project_id/codebases")
 	{
 		// CREATE - validate combined URI (project_id) and JSON body using struct tags
 		projectCodebaseGroup.POST("",
 			middleware.NewCombinedValidationMiddleware[models.CreateCodebaseRequest]().Handle(),
 			controller.CreateCodebase,
 		)
+
+		// UPLOAD - create a codebase from an archive uploaded to a presigned URL
+		projectCodebaseGroup.POST("/uploads",
+			middleware.NewCombinedValidationMiddleware[models.CreateCodebaseUploadRequest]().Handle(),
+			controller.CreateCodebaseUpload,
+		)
 	}
 
 	// Direct codebase routes
-	codebaseGroup := router.Group("/api/v1/codebases")
+	codebaseGroup := api.Group(APIVersionV1, "/codebases")
 	{
 		// LIST - validate query parameters using struct tags
 		codebaseGroup.GET("",
@@ -90,6 +117,51 @@ func SetupCodebaseRoutes(router *gin.Engine, controller *controllers.CodebaseCon
 			middleware.NewURIValidationMiddleware[models.DeleteCodebaseRequest]().Handle(),
 			controller.DeleteCodebase,
 		)
+
+		// COMPLETE UPLOAD - check the uploaded archive and activate the codebase
+		codebaseGroup.POST("/:id/upload/complete",
+			middleware.NewURIValidationMiddleware[models.CompleteCodebaseUploadRequest]().Handle(),
+			controller.CompleteCodebaseUpload,
+		)
+
+		// BROWSE - read-only proxies to the codebase's git provider
+		codebaseGroup.GET("/:id/branches",
+			middleware.NewPermissionMiddleware(permissions, models.PermissionProjectRead).Handle(),
+			middleware.NewURIValidationMiddleware[models.ListCodebaseBranchesRequest]().Handle(),
+			controller.ListBranches,
+		)
+		codebaseGroup.GET("/:id/commits",
+			middleware.NewPermissionMiddleware(permissions, models.PermissionProjectRead).Handle(),
+			middleware.NewURIQueryValidationMiddleware[models.ListCodebaseCommitsRequest]().Handle(),
+			controller.ListCommits,
+		)
+		codebaseGroup.GET("/:id/files",
+			middleware.NewPermissionMiddleware(permissions, models.PermissionProjectRead).Handle(),
+			middleware.NewURIQueryValidationMiddleware[models.ListCodebaseFilesRequest]().Handle(),
+			controller.ListFiles,
+		)
+
+		// FINDINGS - vulnerable dependencies found by the latest dependency_audit task
+		codebaseGroup.GET("/:id/dependency-findings",
+			middleware.NewURIQueryValidationMiddleware[models.ListDependencyFindingsRequest]().Handle(),
+			controller.ListDependencyFindings,
+		)
+
+		// METRICS - per-commit maintainability snapshots recorded by code_analysis tasks
+		codebaseGroup.GET("/:id/metrics",
+			middleware.NewURIQueryValidationMiddleware[models.GetCodeMetricsRequest]().Handle(),
+			controller.GetMetrics,
+		)
+
+		// INGESTION SCAN - committed secrets and incompatible licenses blocking ingestion
+		codebaseGroup.POST("/:id/scan",
+			middleware.NewURIValidationMiddleware[models.ScanCodebaseRequest]().Handle(),
+			controller.ScanCodebase,
+		)
+		codebaseGroup.GET("/:id/scan-findings",
+			middleware.NewURIQueryValidationMiddleware[models.ListScanFindingsRequest]().Handle(),
+			controller.ListScanFindings,
+		)
 	}
 }
 
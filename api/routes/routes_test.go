@@ -4,7 +4,6 @@ package routes
 import (
 	"bytes"
 	"encoding/json"
-	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -14,6 +13,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
 	"github.com/kazemisoroush/code-refactoring-tool/api/controllers"
 	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
 	"github.com/kazemisoroush/code-refactoring-tool/api/models"
@@ -30,9 +30,17 @@ func TestProjectRoutesWithValidation(t *testing.T) {
 	mockService := mocks.NewMockProjectService(ctrl)
 	controller := controllers.NewProjectController(mockService)
 
-	// Setup router with validation middleware
+	// The caller holds every project permission
+	mockRoleService := mocks.NewMockRoleService(ctrl)
+	mockRoleService.EXPECT().Authorize(gomock.Any(), "auth-123", gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	// Setup router with error handling, an authenticated caller and validation middleware
 	router := gin.New()
-	SetupProjectRoutes(router, controller)
+	router.Use(middleware.NewErrorHandlerMiddleware().Handle())
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserIDContextKey, "auth-123")
+	})
+	SetupProjectRoutes(NewVersionedRouter(router, nil), controller, mockRoleService)
 
 	t.Run("CreateProject_ValidRequest", func(t *testing.T) {
 		// Set up mock expectation for successful validation and creation
@@ -88,8 +96,8 @@ func TestProjectRoutesWithValidation(t *testing.T) {
 		err = json.Unmarshal(w.Body.Bytes(), &response)
 		require.NoError(t, err)
 
-		assert.Contains(t, response["message"].(string), "Validation failed")
-		assert.Contains(t, response["details"].(string), "Name is required")
+		assert.Equal(t, "validation_failed", response["code"])
+		assert.Contains(t, response["detail"].(string), "Name is required")
 	})
 
 	t.Run("UpdateProject_ValidRequest", func(t *testing.T) {
@@ -113,6 +121,7 @@ func TestProjectRoutesWithValidation(t *testing.T) {
 
 		req := httptest.NewRequest("PUT", "/api/v1/projects/proj-12345", bytes.NewBuffer(jsonData))
 		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"1"`)
 		w := httptest.NewRecorder()
 
 		router.ServeHTTP(w, req)
@@ -133,10 +142,11 @@ func TestProjectRoutesWithValidation(t *testing.T) {
 		// Set up mock to return not found error
 		mockService.EXPECT().
 			UpdateProject(gomock.Any(), gomock.Any()).
-			Return(nil, errors.New("project not found"))
+			Return(nil, apperrors.NotFound(apperrors.CodeProjectNotFound, "project not found"))
 
 		req := httptest.NewRequest("PUT", "/api/v1/projects/nonexistent-id", bytes.NewBuffer(jsonData))
 		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"1"`)
 		w := httptest.NewRecorder()
 
 		router.ServeHTTP(w, req)
@@ -177,15 +187,15 @@ func TestProjectRoutesWithValidation(t *testing.T) {
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		require.NoError(t, err)
 
-		assert.Contains(t, response["message"].(string), "Validation failed")
-		assert.Contains(t, response["details"].(string), "MaxResults must be at most 100")
+		assert.Equal(t, "validation_failed", response["code"])
+		assert.Contains(t, response["detail"].(string), "MaxResults must be at most 100")
 	})
 
 	t.Run("GetProject_InvalidProjectID", func(t *testing.T) {
 		// Set up mock to return not found error
 		mockService.EXPECT().
 			GetProject(gomock.Any(), "invalid-id").
-			Return(nil, errors.New("project not found"))
+			Return(nil, apperrors.NotFound(apperrors.CodeProjectNotFound, "project not found"))
 
 		req := httptest.NewRequest("GET", "/api/v1/projects/invalid-id", nil)
 		w := httptest.NewRecorder()
@@ -197,6 +207,67 @@ func TestProjectRoutesWithValidation(t *testing.T) {
 	})
 }
 
+func TestProjectRoutesRequirePermissions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// The project service is never reached
+	controller := controllers.NewProjectController(mocks.NewMockProjectService(ctrl))
+	mockRoleService := mocks.NewMockRoleService(ctrl)
+	mockRoleService.EXPECT().
+		Authorize(gomock.Any(), "auth-123", "proj-12345", models.PermissionProjectDelete).
+		Return(apperrors.Forbidden(apperrors.CodePermissionDenied, "role viewer doesn't grant permission project:delete"))
+
+	router := gin.New()
+	router.Use(middleware.NewErrorHandlerMiddleware().Handle())
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserIDContextKey, "auth-123")
+	})
+	SetupProjectRoutes(NewVersionedRouter(router, nil), controller, mockRoleService)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/v1/projects/proj-12345", nil))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestProjectMemberRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRoleService := mocks.NewMockRoleService(ctrl)
+	mockRoleService.EXPECT().
+		Authorize(gomock.Any(), "auth-123", "proj-12345", models.PermissionProjectTransfer).
+		Return(apperrors.Forbidden(apperrors.CodePermissionDenied, "role admin doesn't grant permission project:transfer"))
+	mockRoleService.EXPECT().
+		Authorize(gomock.Any(), "auth-123", "proj-12345", models.PermissionMemberManage).
+		Return(nil)
+	mockRoleService.EXPECT().
+		AddProjectMember(gomock.Any(), models.AddProjectMemberRequest{ProjectID: "proj-12345", UserID: "usr-1", Role: models.RoleDeveloper, ActorID: "auth-123"}).
+		Return(&models.ProjectMember{ProjectID: "proj-12345", UserID: "usr-1", Role: models.RoleDeveloper}, nil)
+
+	router := gin.New()
+	router.Use(middleware.NewErrorHandlerMiddleware().Handle())
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserIDContextKey, "auth-123")
+	})
+	SetupProjectMemberRoutes(NewVersionedRouter(router, nil), controllers.NewProjectMemberController(mockRoleService), mockRoleService)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/projects/proj-12345/transfer-ownership", bytes.NewBufferString(`{"user_id":"usr-2"}`)))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/projects/proj-12345/members", bytes.NewBufferString(`{"user_id":"usr-1","role":"developer"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
 func TestValidationMiddlewareExtensibility(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -257,3 +328,229 @@ func TestValidationMiddlewareExtensibility(t *testing.T) {
 func stringPtr(s string) *string {
 	return &s
 }
+
+// authenticatedAs authenticates every request as the caller with the auth provider ID
+type authenticatedAs string
+
+func (a authenticatedAs) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(middleware.UserIDContextKey, string(a))
+	}
+}
+
+func TestAuthRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthService := mocks.NewMockAuthService(ctrl)
+	mockRoleService := mocks.NewMockRoleService(ctrl)
+	mockRoleService.EXPECT().Authorize(gomock.Any(), "auth-admin", "", gomock.Any()).Return(nil).AnyTimes()
+	mockRoleService.EXPECT().Authorize(gomock.Any(), "auth-viewer", "", models.PermissionUserRead).
+		Return(apperrors.Forbidden(apperrors.CodePermissionDenied, "role viewer doesn't grant permission user:read")).AnyTimes()
+
+	newRouter := func(authID string) *gin.Engine {
+		router := gin.New()
+		router.Use(middleware.NewErrorHandlerMiddleware().Handle())
+		SetupAuthRoutes(router, controllers.NewAuthController(mockAuthService), authenticatedAs(authID), mockRoleService)
+		return router
+	}
+	serve := func(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("SignIn_InvalidRequest", func(t *testing.T) {
+		// A sign in without a password fails validation before reaching the service
+		w := serve(newRouter(""), http.MethodPost, "/auth/signin", `{"username":"jane"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("SignIn_ValidRequest", func(t *testing.T) {
+		mockAuthService.EXPECT().
+			SignIn(gomock.Any(), &models.SignInRequest{Username: "jane", Password: "Secret123!"}).
+			Return(&models.SignInResponse{AccessToken: "access-token"}, nil)
+
+		w := serve(newRouter(""), http.MethodPost, "/auth/signin", `{"username":"jane","password":"Secret123!"}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Users_RequireAdmin", func(t *testing.T) {
+		w := serve(newRouter("auth-viewer"), http.MethodGet, "/auth/users", "")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("ListUsers_InvalidQuery", func(t *testing.T) {
+		w := serve(newRouter("auth-admin"), http.MethodGet, "/auth/users?limit=500", "")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("UpdateUser_BindsPathID", func(t *testing.T) {
+		email := "jane@example.com"
+		mockAuthService.EXPECT().
+			UpdateUser(gomock.Any(), &models.UpdateUserRequest{UserID: "usr-1", Email: &email, ActorID: "auth-admin"}).
+			Return(&models.UpdateUserResponse{User: &models.APIUser{UserID: "usr-1"}}, nil)
+
+		w := serve(newRouter("auth-admin"), http.MethodPut, "/auth/users/usr-1", `{"email":"jane@example.com"}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
+
+func TestCodebaseBrowseRoutesRequirePermissions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// The git provider is never reached. Codebase routes have no project, so the caller's own role is evaluated.
+	controller := controllers.NewCodebaseController(nil, mocks.NewMockCodebaseBrowseService(ctrl), nil, nil, nil, nil)
+	mockRoleService := mocks.NewMockRoleService(ctrl)
+	mockRoleService.EXPECT().
+		Authorize(gomock.Any(), "auth-123", "", models.PermissionProjectRead).
+		Return(apperrors.Forbidden(apperrors.CodePermissionDenied, "role guest doesn't grant permission project:read")).
+		Times(3)
+
+	router := gin.New()
+	router.Use(middleware.NewErrorHandlerMiddleware().Handle())
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserIDContextKey, "auth-123")
+	})
+	SetupCodebaseRoutes(NewVersionedRouter(router, nil), controller, mockRoleService)
+
+	for _, path := range []string{"branches", "commits", "files"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/codebases/5f0c2d9e-8b1a-4c3e-9f7d-6a2b4c8e1d3f/"+path, nil))
+
+		assert.Equal(t, http.StatusForbidden, w.Code, path)
+	}
+}
+
+func TestProjectSummaryRoutesRequirePermissions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// The summary service is never reached
+	controller := controllers.NewProjectSummaryController(mocks.NewMockProjectSummaryService(ctrl))
+	mockRoleService := mocks.NewMockRoleService(ctrl)
+	mockRoleService.EXPECT().
+		Authorize(gomock.Any(), "auth-123", "proj-12345", models.PermissionProjectRead).
+		Return(apperrors.Forbidden(apperrors.CodePermissionDenied, "caller isn't a member of project proj-12345"))
+
+	router := gin.New()
+	router.Use(middleware.NewErrorHandlerMiddleware().Handle())
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserIDContextKey, "auth-123")
+	})
+	SetupProjectSummaryRoutes(NewVersionedRouter(router, nil), controller, mockRoleService)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/projects/proj-12345/summary", nil))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestRedactionRoutesRequirePermissions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// A caller who can read the project can't change its redaction policy
+	mockRedactionService := mocks.NewMockRedactionService(ctrl)
+	mockRoleService := mocks.NewMockRoleService(ctrl)
+	mockRoleService.EXPECT().Authorize(gomock.Any(), "auth-123", "proj-12345", models.PermissionProjectRead).Return(nil)
+	mockRoleService.EXPECT().
+		Authorize(gomock.Any(), "auth-123", "proj-12345", models.PermissionProjectUpdate).
+		Return(apperrors.Forbidden(apperrors.CodePermissionDenied, "role viewer doesn't grant permission project:update"))
+	mockRedactionService.EXPECT().
+		GetPolicy(gomock.Any(), models.GetRedactionPolicyRequest{ProjectID: "proj-12345"}).
+		Return(&models.RedactionPolicy{ProjectID: "proj-12345"}, nil)
+
+	router := gin.New()
+	router.Use(middleware.NewErrorHandlerMiddleware().Handle())
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserIDContextKey, "auth-123")
+	})
+	SetupRedactionRoutes(NewVersionedRouter(router, nil), controllers.NewRedactionController(mockRedactionService), mockRoleService)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/projects/proj-12345/redaction-policy", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/projects/proj-12345/redaction-policy", bytes.NewBufferString(`{"mask_emails":false}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestQualityGateRoutesRequirePermissions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// The quality gate service is never reached
+	controller := controllers.NewQualityGateController(mocks.NewMockQualityGateService(ctrl))
+	mockRoleService := mocks.NewMockRoleService(ctrl)
+	mockRoleService.EXPECT().
+		Authorize(gomock.Any(), "auth-123", "proj-12345", models.PermissionProjectUpdate).
+		Return(apperrors.Forbidden(apperrors.CodePermissionDenied, "role viewer doesn't grant permission project:update"))
+
+	router := gin.New()
+	router.Use(middleware.NewErrorHandlerMiddleware().Handle())
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserIDContextKey, "auth-123")
+	})
+	SetupQualityGateRoutes(NewVersionedRouter(router, nil), controller, mockRoleService)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/projects/proj-12345/quality-gates", bytes.NewBufferString(`{"severity_rules":[]}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestFindingRoutesRequirePermissions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// The finding service is never reached. Findings have no project route parameter, so the caller's own role is
+	// evaluated.
+	controller := controllers.NewFindingController(mocks.NewMockFindingService(ctrl))
+	mockRoleService := mocks.NewMockRoleService(ctrl)
+	mockRoleService.EXPECT().
+		Authorize(gomock.Any(), "auth-123", "", models.PermissionProjectUpdate).
+		Return(apperrors.Forbidden(apperrors.CodePermissionDenied, "role viewer doesn't grant permission project:update")).
+		Times(3)
+
+	router := gin.New()
+	router.Use(middleware.NewErrorHandlerMiddleware().Handle())
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserIDContextKey, "auth-123")
+	})
+	SetupFindingRoutes(NewVersionedRouter(router, nil), controller, mockRoleService)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/findings/finding-1", bytes.NewBufferString(`{"status":"ignored","justification":"generated code"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/v1/findings/finding-1", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/codebases/5f0c2d9e-8b1a-4c3e-9f7d-6a2b4c8e1d3f/findings/baseline", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
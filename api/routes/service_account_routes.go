@@ -0,0 +1,54 @@
+package routes
+
+import (
+	"github.com/kazemisoroush/code-refactoring-tool/api/controllers"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// SetupServiceAccountRoutes configures the service account routes, admitting only the callers holding the
+// service_account:manage permission
+func SetupServiceAccountRoutes(api *VersionedRouter, controller *controllers.ServiceAccountController, permissions middleware.PermissionEvaluator) {
+	accountGroup := api.Group(APIVersionV1, "/admin/service-accounts")
+	accountGroup.Use(middleware.NewPermissionMiddleware(permissions, models.PermissionServiceAccountManage).Handle())
+	{
+		// CREATE a service account - validate JSON body using struct tags
+		accountGroup.POST("",
+			middleware.NewJSONValidationMiddleware[models.CreateServiceAccountRequest]().Handle(),
+			controller.CreateServiceAccount,
+		)
+
+		// LIST the service accounts
+		accountGroup.GET("", controller.ListServiceAccounts)
+
+		// GET a service account - validate URI parameters using struct tags
+		accountGroup.GET("/:service_account_id",
+			middleware.NewURIValidationMiddleware[models.GetServiceAccountRequest]().Handle(),
+			controller.GetServiceAccount,
+		)
+
+		// DELETE a service account - validate URI parameters using struct tags
+		accountGroup.DELETE("/:service_account_id",
+			middleware.NewURIValidationMiddleware[models.DeleteServiceAccountRequest]().Handle(),
+			controller.DeleteServiceAccount,
+		)
+
+		// ISSUE a token - validate URI parameters and JSON body using struct tags
+		accountGroup.POST("/:service_account_id/tokens",
+			middleware.NewCombinedValidationMiddleware[models.CreateServiceAccountTokenRequest]().Handle(),
+			controller.CreateServiceAccountToken,
+		)
+
+		// LIST the tokens - validate URI parameters using struct tags
+		accountGroup.GET("/:service_account_id/tokens",
+			middleware.NewURIValidationMiddleware[models.ListServiceAccountTokensRequest]().Handle(),
+			controller.ListServiceAccountTokens,
+		)
+
+		// REVOKE a token - validate URI parameters using struct tags
+		accountGroup.DELETE("/:service_account_id/tokens/:token_id",
+			middleware.NewURIValidationMiddleware[models.RevokeServiceAccountTokenRequest]().Handle(),
+			controller.RevokeServiceAccountToken,
+		)
+	}
+}
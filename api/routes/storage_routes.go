@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"github.com/kazemisoroush/code-refactoring-tool/api/controllers"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// SetupStorageRoutes configures the storage purge and reconciliation routes, admitting only the callers holding the
+// operations:manage permission
+func SetupStorageRoutes(api *VersionedRouter, controller *controllers.StorageController, permissions middleware.PermissionEvaluator) {
+	storageGroup := api.Group(APIVersionV1, "/admin/storage")
+	storageGroup.Use(middleware.NewPermissionMiddleware(permissions, models.PermissionOperationsManage).Handle())
+	{
+		// LIST storage purges - validate query parameters using struct tags
+		storageGroup.GET("/purges",
+			middleware.NewQueryValidationMiddleware[models.ListStoragePurgesRequest]().Handle(),
+			controller.ListStoragePurges,
+		)
+
+		// GET a storage purge - validate URI parameters using struct tags
+		storageGroup.GET("/purges/:purge_id",
+			middleware.NewURIValidationMiddleware[models.GetStoragePurgeRequest]().Handle(),
+			controller.GetStoragePurge,
+		)
+
+		// LIST the knowledge base prefixes no agent owns
+		storageGroup.GET("/orphans", controller.ListStorageOrphans)
+	}
+}
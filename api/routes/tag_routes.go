@@ -0,0 +1,46 @@
+package routes
+
+import (
+	"github.com/kazemisoroush/code-refactoring-tool/api/controllers"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// SetupTagRoutes configures the tag key registry routes. Every caller can read the registry, changing it requires the
+// tag_key:manage permission.
+func SetupTagRoutes(api *VersionedRouter, controller *controllers.TagController, permissions middleware.PermissionEvaluator) {
+	canManage := middleware.NewPermissionMiddleware(permissions, models.PermissionTagKeyManage)
+
+	tagKeyGroup := api.Group(APIVersionV1, "/tag-keys")
+	{
+		// LIST the registered tag keys
+		tagKeyGroup.GET("", controller.ListTagKeys)
+
+		// GET by key - validate URI parameters using struct tags
+		tagKeyGroup.GET("/:key",
+			middleware.NewURIValidationMiddleware[models.GetTagKeyRequest]().Handle(),
+			controller.GetTagKey,
+		)
+
+		// REGISTER a tag key - validate JSON body using struct tags
+		tagKeyGroup.POST("",
+			canManage.Handle(),
+			middleware.NewJSONValidationMiddleware[models.CreateTagKeyRequest]().Handle(),
+			controller.CreateTagKey,
+		)
+
+		// UPDATE the rules of a tag key - validate URI parameters and JSON body using struct tags
+		tagKeyGroup.PUT("/:key",
+			canManage.Handle(),
+			middleware.NewCombinedValidationMiddleware[models.UpdateTagKeyRequest]().Handle(),
+			controller.UpdateTagKey,
+		)
+
+		// UNREGISTER a tag key - validate URI parameters using struct tags
+		tagKeyGroup.DELETE("/:key",
+			canManage.Handle(),
+			middleware.NewURIValidationMiddleware[models.DeleteTagKeyRequest]().Handle(),
+			controller.DeleteTagKey,
+		)
+	}
+}
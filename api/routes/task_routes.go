@@ -1,34 +1,38 @@
 package routes
 
 import (
-	"github.com/gin-gonic/gin"
 	"github.com/kazemisoroush/code-refactoring-tool/api/controllers"
 	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
 	"github.com/kazemisoroush/code-refactoring-tool/api/models"
 )
 
-// SetupTaskRoutes sets up the task-related routes
-func SetupTaskRoutes(router *gin.Engine, taskController *controllers.TaskController) {
+// SetupTaskRoutes sets up the task-related routes. The project-scoped routes require a task permission within the
+// project, evaluated by permissions. The global task routes have no project in their path, so the task controller
+// authorizes them within the project of their tasks.
+func SetupTaskRoutes(api *VersionedRouter, taskController *controllers.TaskController, permissions middleware.PermissionEvaluator) {
 	// Task routes - project-scoped
-	v1 := router.Group("/api/v1")
+	v1 := api.Version(APIVersionV1)
 	{
 		// Project-scoped task routes
 		projects := v1.Group("/projects/:project_id")
 		{
 			// Create task for a project
 			projects.POST("/tasks",
+				middleware.NewPermissionMiddleware(permissions, models.PermissionTaskCreate).Handle(),
 				middleware.NewJSONValidationMiddleware[models.CreateTaskRequest]().Handle(),
 				taskController.CreateTask,
 			)
 
 			// List tasks for a project
 			projects.GET("/tasks",
+				middleware.NewPermissionMiddleware(permissions, models.PermissionTaskRead).Handle(),
 				middleware.NewQueryValidationMiddleware[models.ListTasksRequest]().Handle(),
 				taskController.ListTasks,
 			)
 
 			// Execute task immediately for a project
 			projects.POST("/tasks/execute",
+				middleware.NewPermissionMiddleware(permissions, models.PermissionTaskExecute).Handle(),
 				middleware.NewJSONValidationMiddleware[models.ExecuteTaskRequest]().Handle(),
 				taskController.ExecuteTask,
 			)
@@ -37,6 +41,18 @@ func SetupTaskRoutes(router *gin.Engine, taskController *controllers.TaskControl
 		// Global task routes (not project-scoped)
 		tasks := v1.Group("/tasks")
 		{
+			// Create several tasks atomically
+			tasks.POST("/batch",
+				middleware.NewJSONValidationMiddleware[models.CreateTaskBatchRequest]().Handle(),
+				taskController.CreateTaskBatch,
+			)
+
+			// Get aggregate progress of a task batch
+			tasks.GET("/batches/:batchId",
+				middleware.NewURIValidationMiddleware[models.GetTaskBatchRequest]().Handle(),
+				taskController.GetTaskBatch,
+			)
+
 			// Get specific task by ID
 			tasks.GET("/:id",
 				middleware.NewURIValidationMiddleware[models.GetTaskRequest]().Handle(),
@@ -45,12 +61,54 @@ func SetupTaskRoutes(router *gin.Engine, taskController *controllers.TaskControl
 
 			// Update task by ID
 			tasks.PUT("/:id",
-				middleware.NewJSONValidationMiddleware[models.UpdateTaskRequest]().Handle(),
+				middleware.NewCombinedValidationMiddleware[models.UpdateTaskRequest]().Handle(),
 				taskController.UpdateTask,
 			)
 
 			// Delete task by ID
 			tasks.DELETE("/:id", taskController.DeleteTask)
+
+			// Comment on a task, starting or replying to a review thread
+			tasks.POST("/:id/comments",
+				middleware.NewCombinedValidationMiddleware[models.CreateTaskCommentRequest]().Handle(),
+				taskController.CreateTaskComment,
+			)
+
+			// List a task's comments
+			tasks.GET("/:id/comments",
+				middleware.NewURIValidationMiddleware[models.ListTaskCommentsRequest]().Handle(),
+				taskController.ListTaskComments,
+			)
+
+			// Give feedback on a task's result
+			tasks.POST("/:id/feedback",
+				middleware.NewCombinedValidationMiddleware[models.SubmitTaskFeedbackRequest]().Handle(),
+				taskController.SubmitTaskFeedback,
+			)
+
+			// List the feedback on a task
+			tasks.GET("/:id/feedback",
+				middleware.NewURIValidationMiddleware[models.ListTaskFeedbackRequest]().Handle(),
+				taskController.ListTaskFeedback,
+			)
+
+			// List the LLM calls made while executing a task
+			tasks.GET("/:id/llm-trace",
+				middleware.NewURIValidationMiddleware[models.GetLLMTraceRequest]().Handle(),
+				taskController.GetTaskLLMTrace,
+			)
+
+			// Edit a task comment
+			tasks.PUT("/:id/comments/:comment_id",
+				middleware.NewCombinedValidationMiddleware[models.UpdateTaskCommentRequest]().Handle(),
+				taskController.UpdateTaskComment,
+			)
+
+			// Delete a task comment
+			tasks.DELETE("/:id/comments/:comment_id",
+				middleware.NewURIValidationMiddleware[models.DeleteTaskCommentRequest]().Handle(),
+				taskController.DeleteTaskComment,
+			)
 		}
 	}
 }
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/kazemisoroush/code-refactoring-tool/api/controllers"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// SetupUploadRoutes configures the routes uploading large task inputs. Uploading to a project requires the permission
+// to create its tasks, evaluated by permissions.
+func SetupUploadRoutes(api *VersionedRouter, controller *controllers.UploadController, permissions middleware.PermissionEvaluator) {
+	projects := api.Group(APIVersionV1, "/projects/:project_id")
+	{
+		// UPLOAD a task input file - validate URI parameters using struct tags
+		projects.POST("/uploads",
+			middleware.NewPermissionMiddleware(permissions, models.PermissionTaskCreate).Handle(),
+			middleware.NewURIValidationMiddleware[models.UploadTaskInputRequest]().Handle(),
+			controller.UploadTaskInput,
+		)
+	}
+}
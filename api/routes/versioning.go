@@ -0,0 +1,95 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// APIVersion identifies a major version of the REST API
+type APIVersion string
+
+const (
+	// APIVersionV1 is the current stable API
+	APIVersionV1 APIVersion = "v1"
+	// APIVersionV2 is the next major API, served alongside v1 during migrations
+	APIVersionV2 APIVersion = "v2"
+)
+
+// BasePath returns the URL prefix of the version
+func (v APIVersion) BasePath() string {
+	return "/api/" + string(v)
+}
+
+// VersionedRouter registers routes under per-version base paths and applies each version's
+// deprecation policy to every route registered through it
+type VersionedRouter struct {
+	engine      *gin.Engine
+	policies    map[APIVersion]config.APIVersionPolicy
+	groups      map[APIVersion]*gin.RouterGroup
+	middlewares map[APIVersion]*middleware.VersionMiddleware
+}
+
+// NewVersionedRouter creates a versioned router on top of engine.
+// Versions without a policy are served without deprecation headers.
+func NewVersionedRouter(engine *gin.Engine, policies map[APIVersion]config.APIVersionPolicy) *VersionedRouter {
+	return &VersionedRouter{
+		engine:      engine,
+		policies:    policies,
+		groups:      make(map[APIVersion]*gin.RouterGroup),
+		middlewares: make(map[APIVersion]*middleware.VersionMiddleware),
+	}
+}
+
+// NewVersionedRouterFromConfig creates a versioned router using the version policies from configuration
+func NewVersionedRouterFromConfig(engine *gin.Engine, cfg config.APIConfig) *VersionedRouter {
+	return NewVersionedRouter(engine, versionPolicies(cfg))
+}
+
+// SetPolicies applies reloaded version policies to the requests served by the versions already registered
+func (r *VersionedRouter) SetPolicies(cfg config.APIConfig) {
+	for version, policy := range versionPolicies(cfg) {
+		if versionMiddleware, ok := r.middlewares[version]; ok {
+			versionMiddleware.SetPolicy(policy)
+		}
+	}
+}
+
+// versionPolicies returns the deprecation policy of each version from configuration
+func versionPolicies(cfg config.APIConfig) map[APIVersion]config.APIVersionPolicy {
+	return map[APIVersion]config.APIVersionPolicy{
+		APIVersionV1: cfg.V1,
+		APIVersionV2: cfg.V2,
+	}
+}
+
+// Engine returns the underlying gin engine for unversioned routes
+func (r *VersionedRouter) Engine() *gin.Engine {
+	return r.engine
+}
+
+// Version returns the root route group of version
+func (r *VersionedRouter) Version(version APIVersion) *gin.RouterGroup {
+	if group, ok := r.groups[version]; ok {
+		return group
+	}
+
+	versionMiddleware := middleware.NewVersionMiddleware(string(version), r.policies[version])
+	group := r.engine.Group(version.BasePath(), versionMiddleware.Handle())
+	r.groups[version] = group
+	r.middlewares[version] = versionMiddleware
+	return group
+}
+
+// Group returns a route group at relativePath under version
+func (r *VersionedRouter) Group(version APIVersion, relativePath string) *gin.RouterGroup {
+	return r.Version(version).Group(relativePath)
+}
+
+// Register calls register with a group at relativePath for each version, so the same
+// handlers and services can serve several versions while clients migrate
+func (r *VersionedRouter) Register(relativePath string, register func(group *gin.RouterGroup), versions ...APIVersion) {
+	for _, version := range versions {
+		register(r.Group(version, relativePath))
+	}
+}
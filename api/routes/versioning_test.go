@@ -0,0 +1,77 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+func TestVersionedRouter_RegisterServesEveryVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	api := NewVersionedRouter(engine, map[APIVersion]config.APIVersionPolicy{
+		APIVersionV1: {DeprecatedAt: time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)},
+	})
+
+	// One handler backed by the same service serves both versions during the migration
+	api.Register("/widgets", func(group *gin.RouterGroup) {
+		group.GET("", func(c *gin.Context) {
+			c.String(http.StatusOK, middleware.GetAPIVersion(c))
+		})
+	}, APIVersionV1, APIVersionV2)
+
+	tests := []struct {
+		path               string
+		expectedVersion    string
+		expectsDeprecation bool
+	}{
+		{path: "/api/v1/widgets", expectedVersion: "v1", expectsDeprecation: true},
+		{path: "/api/v2/widgets", expectedVersion: "v2", expectsDeprecation: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.expectedVersion, w.Body.String())
+			assert.Equal(t, tt.expectedVersion, w.Header().Get(middleware.APIVersionHeader))
+			assert.Equal(t, tt.expectsDeprecation, w.Header().Get(middleware.DeprecationHeader) != "")
+		})
+	}
+}
+
+func TestVersionedRouter_VersionGroupIsShared(t *testing.T) {
+	api := NewVersionedRouter(gin.New(), nil)
+
+	assert.Same(t, api.Version(APIVersionV1), api.Version(APIVersionV1))
+	assert.Equal(t, "/api/v1/projects", api.Group(APIVersionV1, "/projects").BasePath())
+}
+
+func TestVersionedRouter_SetPoliciesAppliesToRegisteredVersions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	api := NewVersionedRouterFromConfig(engine, config.APIConfig{})
+	api.Group(APIVersionV1, "/widgets").GET("", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	// The sunset date was moved into the past by a configuration reload
+	api.SetPolicies(config.APIConfig{
+		V1: config.APIVersionPolicy{SunsetAt: time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC)},
+	})
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/widgets", nil))
+
+	assert.Equal(t, http.StatusGone, w.Code)
+	assert.NotEmpty(t, w.Header().Get(middleware.SunsetHeader))
+}
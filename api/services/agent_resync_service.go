@@ -0,0 +1,22 @@
+package services
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// AgentResyncService defines the interface for resyncing agents when the default branch of their codebase changes
+//
+//go:generate mockgen -destination=./mocks/mock_agent_resync_service.go -mock_names=AgentResyncService=MockAgentResyncService -package=mocks . AgentResyncService
+type AgentResyncService interface {
+	// GetSettings returns the agent resync settings of a project, or the default settings if none are configured
+	GetSettings(ctx context.Context, request models.GetAgentResyncSettingsRequest) (*models.AgentResyncSettings, error)
+
+	// UpdateSettings opts a project in or out of agent resyncs
+	UpdateSettings(ctx context.Context, request models.UpdateAgentResyncSettingsRequest) (*models.AgentResyncSettings, error)
+
+	// CheckCodebases polls the default branch of the codebases agents are built from and resyncs the agents of
+	// codebases whose branch stopped moving for the debounce period
+	CheckCodebases(ctx context.Context) error
+}
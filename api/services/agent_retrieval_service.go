@@ -0,0 +1,15 @@
+package services
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// AgentRetrievalService defines the interface for debugging the context agents retrieve from their knowledge bases
+//
+//go:generate mockgen -destination=./mocks/mock_agent_retrieval_service.go -mock_names=AgentRetrievalService=MockAgentRetrievalService -package=mocks . AgentRetrievalService
+type AgentRetrievalService interface {
+	// RetrieveChunks runs only the retrieval step of an agent for a query, returning the ranked chunks it would be given
+	RetrieveChunks(ctx context.Context, request models.RetrieveAgentChunksRequest) (*models.RetrieveAgentChunksResponse, error)
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 
 	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
 )
 
 // AgentService defines the interface for agent-related operations
@@ -20,9 +21,27 @@ type AgentService interface {
 	// UpdateAgent updates an existing agent
 	UpdateAgent(ctx context.Context, request models.UpdateAgentRequest) (*models.UpdateAgentResponse, error)
 
+	// RebuildAgent re-provisions an agent's AI infrastructure from its current repository settings
+	RebuildAgent(ctx context.Context, agentID string) (*models.UpdateAgentResponse, error)
+
+	// ReembedAgent rebuilds an agent's knowledge base to embed its repository with another model and dimensions
+	ReembedAgent(ctx context.Context, agentID string, embedding config.Embedding) (*models.UpdateAgentResponse, error)
+
 	// DeleteAgent deletes an agent by ID
 	DeleteAgent(ctx context.Context, agentID string) (*models.DeleteAgentResponse, error)
 
 	// ListAgents lists all agents with optional pagination
 	ListAgents(ctx context.Context, request models.ListAgentsRequest) (*models.ListAgentsResponse, error)
+
+	// ListAgentSetups lists the setups that provisioned agent infrastructure, most recent first
+	ListAgentSetups(ctx context.Context, request models.ListAgentSetupsRequest) (*models.ListAgentSetupsResponse, error)
+
+	// GetAgentSetup retrieves an agent setup with the progress of its steps
+	GetAgentSetup(ctx context.Context, setupID string) (*models.AgentSetup, error)
+
+	// ResumeAgentSetup retries a failed setup from its first incomplete step and saves the agent it provisions
+	ResumeAgentSetup(ctx context.Context, setupID string) (*models.AgentSetup, error)
+
+	// TearDownAgentSetup cleans up the resources a failed setup left behind, so it can't be resumed anymore
+	TearDownAgentSetup(ctx context.Context, setupID string) (*models.AgentSetup, error)
 }
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/workflow"
+)
+
+// Run values an agent setup is started with, so a failed setup can be resumed by its ID alone
+const (
+	setupInputOperation = "operation"
+	setupInputProjectID = "project_id"
+	setupInputRequest   = "request"
+	setupInputAgentID   = "rebuild_agent_id"
+)
+
+// agentSetupWorkflows are the setup workflows, in listing order, with the AI provider each provisions
+var agentSetupWorkflows = []struct {
+	name     string
+	provider models.AIProvider
+}{
+	{name: workflow.CreateBedrockSetupWorkflowName, provider: models.AIProviderBedrock},
+	{name: workflow.CreateLocalSetupWorkflowName, provider: models.AIProviderLocal},
+}
+
+// agentSetupProvider returns the AI provider a setup workflow provisions, false for other workflows.
+func agentSetupProvider(workflowName string) (models.AIProvider, bool) {
+	for _, setupWorkflow := range agentSetupWorkflows {
+		if setupWorkflow.name == workflowName {
+			return setupWorkflow.provider, true
+		}
+	}
+	return "", false
+}
+
+// rebuildSetup starts a setup that re-provisions an existing agent.
+func rebuildSetup(agent *repository.AgentRecord) workflow.Setup {
+	return workflow.Setup{
+		RunID: newSetupID(),
+		Input: map[string]any{
+			setupInputOperation: string(models.AgentSetupOperationRebuild),
+			setupInputProjectID: agent.ProjectID,
+			setupInputAgentID:   agent.AgentID,
+		},
+		RepositoryURL: agent.RepositoryURL,
+	}
+}
+
+// ListAgentSetups lists the setups that provisioned agent infrastructure, most recent first
+func (s *DefaultAgentService) ListAgentSetups(ctx context.Context, request models.ListAgentSetupsRequest) (*models.ListAgentSetupsResponse, error) {
+	response := &models.ListAgentSetupsResponse{Setups: []models.AgentSetup{}}
+	for _, setupWorkflow := range agentSetupWorkflows {
+		runs, err := s.workflowEngine.Runs(ctx, setupWorkflow.name, workflow.RunStatus(request.Status))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list agent setups: %w", err)
+		}
+		for _, run := range runs {
+			response.Setups = append(response.Setups, agentSetupFromRun(run, setupWorkflow.provider))
+		}
+	}
+
+	sort.SliceStable(response.Setups, func(i, j int) bool {
+		return response.Setups[i].CreatedAt.After(response.Setups[j].CreatedAt)
+	})
+	return response, nil
+}
+
+// GetAgentSetup retrieves an agent setup with the progress of its steps
+func (s *DefaultAgentService) GetAgentSetup(ctx context.Context, setupID string) (*models.AgentSetup, error) {
+	run, provider, err := s.getSetupRun(ctx, setupID)
+	if err != nil {
+		return nil, err
+	}
+
+	setup := agentSetupFromRun(run, provider)
+	return &setup, nil
+}
+
+// ResumeAgentSetup retries a failed setup from its first incomplete step and saves the agent it provisions. A failed
+// create saves a new agent, and a failed update or rebuild points the agent at the infrastructure it provisions.
+func (s *DefaultAgentService) ResumeAgentSetup(ctx context.Context, setupID string) (*models.AgentSetup, error) {
+	run, provider, err := s.getSetupRun(ctx, setupID)
+	if err != nil {
+		return nil, err
+	}
+	if run.Status != workflow.RunStatusFailed {
+		return nil, apperrors.Conflict(apperrors.CodeAgentSetupNotFailed, "agent setup %s is %s, only failed setups can be resumed", setupID, run.Status)
+	}
+
+	slog.InfoContext(ctx, "Resuming agent setup", "setup_id", setupID, "workflow", run.Workflow)
+
+	// The stored run keeps the values it was started with, so the setup needs no input
+	setup := workflow.Setup{RunID: run.RunID}
+	switch operation := models.AgentSetupOperation(run.String(setupInputOperation)); operation {
+	case models.AgentSetupOperationCreate:
+		var request models.CreateAgentRequest
+		if _, err := run.Decode(setupInputRequest, &request); err != nil {
+			return nil, fmt.Errorf("failed to read agent setup %s: %w", setupID, err)
+		}
+		request.AIProvider = provider
+		setup.RepositoryURL = request.RepositoryURL
+
+		if _, err := s.createAgent(ctx, setup, request); err != nil {
+			return nil, err
+		}
+	case models.AgentSetupOperationRebuild:
+		if err := s.resumeRebuild(ctx, setup, run.String(setupInputAgentID), provider); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("agent setup %s has unknown operation %q", setupID, operation)
+	}
+
+	return s.GetAgentSetup(ctx, setupID)
+}
+
+// TearDownAgentSetup cleans up the resources a failed setup left behind, so it can't be resumed anymore
+func (s *DefaultAgentService) TearDownAgentSetup(ctx context.Context, setupID string) (*models.AgentSetup, error) {
+	run, _, err := s.getSetupRun(ctx, setupID)
+	if err != nil {
+		return nil, err
+	}
+	if run.Status != workflow.RunStatusFailed {
+		return nil, apperrors.Conflict(apperrors.CodeAgentSetupNotFailed, "agent setup %s is %s, only failed setups can be torn down", setupID, run.Status)
+	}
+
+	slog.InfoContext(ctx, "Tearing down agent setup", "setup_id", setupID, "workflow", run.Workflow)
+	if err := s.infrastructureFactory.TearDownAgentSetup(ctx, setupID); err != nil {
+		return nil, fmt.Errorf("failed to tear down agent setup %s: %w", setupID, err)
+	}
+
+	return s.GetAgentSetup(ctx, setupID)
+}
+
+// resumeRebuild finishes provisioning the new infrastructure of an agent. Its old infrastructure was destroyed
+// before the setup failed, so unlike a rebuild nothing is destroyed first.
+func (s *DefaultAgentService) resumeRebuild(ctx context.Context, setup workflow.Setup, agentID string, provider models.AIProvider) error {
+	existingAgent, err := s.agentRepository.GetAgent(ctx, agentID)
+	if err != nil {
+		return fmt.Errorf("failed to get existing agent: %w", err)
+	}
+
+	policy, err := s.redactionService.ResolvePolicy(ctx, existingAgent.ProjectID)
+	if err != nil {
+		return fmt.Errorf("failed to resolve redaction policy: %w", err)
+	}
+
+	// An update that changed the agent's provider leaves its region and embedding behind
+	region := existingAgent.Region
+	embedding := existingAgent.Embedding()
+	if provider != models.AIProviderBedrock {
+		region = ""
+		embedding = config.Embedding{}
+	}
+
+	setup.RepositoryURL = existingAgent.RepositoryURL
+	infrastructureResult, err := s.infrastructureFactory.CreateAgentInfrastructure(ctx, setup, provider, region, embedding, policy)
+	if err != nil {
+		s.notifyProvisioningFailed(ctx, existingAgent.AgentID, "rebuild", err)
+		return infrastructureError("rebuild", err)
+	}
+
+	// An update that changed the agent's provider failed before saving it
+	rebuiltAgent := *existingAgent
+	rebuiltAgent.AIProvider = string(provider)
+	rebuiltAgent.Region = region
+	_, err = s.saveRebuiltAgent(ctx, &rebuiltAgent, infrastructureResult)
+	return err
+}
+
+// getSetupRun returns the workflow run of an agent setup and the AI provider it provisions.
+func (s *DefaultAgentService) getSetupRun(ctx context.Context, setupID string) (*workflow.Run, models.AIProvider, error) {
+	run, err := s.workflowEngine.Run(ctx, setupID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get agent setup: %w", err)
+	}
+	if run == nil {
+		return nil, "", apperrors.NotFound(apperrors.CodeAgentSetupNotFound, "agent setup not found: %s", setupID)
+	}
+
+	provider, ok := agentSetupProvider(run.Workflow)
+	if !ok {
+		return nil, "", apperrors.NotFound(apperrors.CodeAgentSetupNotFound, "agent setup not found: %s", setupID)
+	}
+	return run, provider, nil
+}
+
+// agentSetupFromRun reports the progress of a setup workflow run.
+func agentSetupFromRun(run *workflow.Run, provider models.AIProvider) models.AgentSetup {
+	operation := models.AgentSetupOperation(run.String(setupInputOperation))
+	agentID := run.String("agent_id")
+	if operation == models.AgentSetupOperationRebuild {
+		agentID = run.String(setupInputAgentID)
+	}
+
+	steps := make([]models.AgentSetupStep, 0, len(run.Steps))
+	for _, step := range run.Steps {
+		steps = append(steps, models.AgentSetupStep{
+			Name:     step.Name,
+			Status:   string(step.Status),
+			Attempts: step.Attempts,
+			Error:    step.Error,
+		})
+	}
+
+	return models.AgentSetup{
+		SetupID:    run.RunID,
+		Operation:  operation,
+		AIProvider: provider,
+		ProjectID:  run.String(setupInputProjectID),
+		AgentID:    agentID,
+		Status:     string(run.Status),
+		Error:      run.Error,
+		Steps:      steps,
+		CreatedAt:  run.CreatedAt,
+		UpdatedAt:  run.UpdatedAt,
+	}
+}
+
+// newSetupID generates the ID of a new agent setup.
+func newSetupID() string {
+	return uuid.New().String()
+}
@@ -0,0 +1,18 @@
+package services
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// AgentSyncService defines the interface for syncing agent knowledge bases with their repositories
+//
+//go:generate mockgen -destination=./mocks/mock_agent_sync_service.go -mock_names=AgentSyncService=MockAgentSyncService -package=mocks . AgentSyncService
+type AgentSyncService interface {
+	// GetSyncStatus reports the last sync time, document counts and failures of an agent's knowledge base
+	GetSyncStatus(ctx context.Context, request models.GetAgentSyncStatusRequest) (*models.AgentSyncStatus, error)
+
+	// SyncAgent starts resyncing an agent's knowledge base; its progress is reported by GetSyncStatus
+	SyncAgent(ctx context.Context, request models.SyncAgentRequest) (*models.AgentSyncStatus, error)
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/auth"
+)
+
+const (
+	// mfaIssuer names the service in authenticator apps
+	mfaIssuer = "CodeRefactorTool"
+
+	// recoveryCodeCount is the number of recovery codes generated at once
+	recoveryCodeCount = 10
+)
+
+// recoveryCodeEncoding encodes the random bytes of recovery codes in lowercase letters and digits that are easy to type
+var recoveryCodeEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// SetupMFA enrolls a second factor for the current user. TOTP is enabled once a one-time password from the
+// authenticator app is verified, while SMS is enabled right away, returning the recovery codes.
+func (s *AuthServiceImpl) SetupMFA(ctx context.Context, req *models.MFASetupRequest) (*models.MFASetupResponse, error) {
+	user, err := s.getCurrentUser(ctx, req.AuthID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Method == models.MFAMethodSMS {
+		if err := s.authProvider.EnableSMSMFA(ctx, req.AccessToken, req.PhoneNumber); err != nil {
+			return nil, wrapProviderError(err, "failed to enable SMS MFA")
+		}
+
+		recoveryCodes, err := s.replaceRecoveryCodes(ctx, user.UserID)
+		if err != nil {
+			return nil, err
+		}
+
+		return &models.MFASetupResponse{Method: models.MFAMethodSMS, Enabled: true, RecoveryCodes: recoveryCodes}, nil
+	}
+
+	setup, err := s.authProvider.SetupTOTP(ctx, req.AccessToken)
+	if err != nil {
+		return nil, wrapProviderError(err, "failed to set up TOTP MFA")
+	}
+
+	return &models.MFASetupResponse{
+		Method:     models.MFAMethodTOTP,
+		SecretCode: setup.SecretCode,
+		OTPAuthURI: otpAuthURI(user.Email, setup.SecretCode),
+	}, nil
+}
+
+// VerifyMFA verifies a one-time password from the authenticator app the current user enrolled, enabling TOTP, and
+// returns their recovery codes
+func (s *AuthServiceImpl) VerifyMFA(ctx context.Context, req *models.VerifyMFARequest) (*models.MFARecoveryCodesResponse, error) {
+	user, err := s.getCurrentUser(ctx, req.AuthID)
+	if err != nil {
+		return nil, err
+	}
+
+	verifyReq := &auth.VerifyTOTPRequest{
+		AccessToken: req.AccessToken,
+		Code:        req.Code,
+		DeviceName:  req.DeviceName,
+	}
+	if err := s.authProvider.VerifyTOTP(ctx, verifyReq); err != nil {
+		return nil, wrapProviderError(err, "failed to verify TOTP code")
+	}
+
+	recoveryCodes, err := s.replaceRecoveryCodes(ctx, user.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.MFARecoveryCodesResponse{RecoveryCodes: recoveryCodes}, nil
+}
+
+// RespondToMFAChallenge completes a sign in with the one-time password of the user's second factor
+func (s *AuthServiceImpl) RespondToMFAChallenge(ctx context.Context, req *models.MFAChallengeRequest) (*models.SignInResponse, error) {
+	challengeResp := &auth.MFAChallengeResponse{
+		Username: req.Username,
+		Method:   auth.MFAMethod(req.Method),
+		Session:  req.Session,
+		Code:     req.Code,
+	}
+
+	authResult, err := s.authProvider.RespondToMFAChallenge(ctx, challengeResp)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidCode) {
+			return nil, wrapProviderError(err, "MFA challenge failed")
+		}
+		return nil, apperrors.Wrap(apperrors.ErrUnauthorized, apperrors.CodeAuthenticationFailed, err, "MFA challenge failed")
+	}
+
+	return s.signedIn(ctx, authResult)
+}
+
+// RecoverMFA signs a user who lost their second factor in with their password and a recovery code. MFA is disabled and
+// the remaining recovery codes are revoked, so the user enrolls a new second factor.
+func (s *AuthServiceImpl) RecoverMFA(ctx context.Context, req *models.MFARecoverRequest) (*models.SignInResponse, error) {
+	signInReq := &auth.SignInRequest{
+		Username: req.Username,
+		Password: req.Password,
+	}
+
+	// The challenge is only issued once the password is verified
+	authResult, err := s.authProvider.SignIn(ctx, signInReq)
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrUnauthorized, apperrors.CodeAuthenticationFailed, err, "authentication failed")
+	}
+	if authResult.Challenge == nil {
+		return nil, apperrors.Conflict(apperrors.CodeMFANotEnabled, "MFA is not enabled, sign in without a recovery code")
+	}
+
+	// Assuming username is email, as the auth provider does
+	user, err := s.userRepo.GetUserByEmail(ctx, req.Username)
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrUnauthorized, apperrors.CodeInvalidRecoveryCode, err, "invalid recovery code")
+	}
+
+	consumed, err := s.recoveryCodeRepo.ConsumeRecoveryCode(ctx, user.UserID, hashRecoveryCode(req.RecoveryCode))
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		return nil, apperrors.Unauthorized(apperrors.CodeInvalidRecoveryCode, "invalid recovery code")
+	}
+
+	if err := s.authProvider.DisableMFA(ctx, user.AuthID); err != nil {
+		return nil, wrapProviderError(err, "failed to disable MFA")
+	}
+	if err := s.recoveryCodeRepo.ReplaceRecoveryCodes(ctx, user.UserID, nil); err != nil {
+		return nil, fmt.Errorf("failed to revoke recovery codes: %w", err)
+	}
+
+	authResult, err = s.authProvider.SignIn(ctx, signInReq)
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrUnauthorized, apperrors.CodeAuthenticationFailed, err, "authentication failed")
+	}
+
+	return s.signedIn(ctx, authResult)
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of the current user, revoking the previous ones
+func (s *AuthServiceImpl) RegenerateRecoveryCodes(ctx context.Context, authID string) (*models.MFARecoveryCodesResponse, error) {
+	user, err := s.getCurrentUser(ctx, authID)
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, err := s.replaceRecoveryCodes(ctx, user.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.MFARecoveryCodesResponse{RecoveryCodes: recoveryCodes}, nil
+}
+
+// replaceRecoveryCodes generates new recovery codes for a user and stores their hashes in place of the previous ones
+func (s *AuthServiceImpl) replaceRecoveryCodes(ctx context.Context, userID string) ([]string, error) {
+	recoveryCodes := make([]string, recoveryCodeCount)
+	codeHashes := make([]string, recoveryCodeCount)
+	for i := range recoveryCodes {
+		random := make([]byte, 7)
+		if _, err := rand.Read(random); err != nil {
+			return nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+		code := recoveryCodeEncoding.EncodeToString(random)[:10]
+		recoveryCodes[i] = code[:5] + "-" + code[5:]
+		codeHashes[i] = hashRecoveryCode(recoveryCodes[i])
+	}
+
+	if err := s.recoveryCodeRepo.ReplaceRecoveryCodes(ctx, userID, codeHashes); err != nil {
+		return nil, fmt.Errorf("failed to store recovery codes: %w", err)
+	}
+
+	return recoveryCodes, nil
+}
+
+// hashRecoveryCode hashes a recovery code, ignoring case, dashes and spaces so the code can be typed as it is read
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// otpAuthURI returns the otpauth URI authenticator apps read the TOTP secret of an account from
+func otpAuthURI(account, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", mfaIssuer)
+	return (&url.URL{Scheme: "otpauth", Host: "totp", Path: "/" + mfaIssuer + ":" + account, RawQuery: query.Encode()}).String()
+}
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/auth"
+	authMocks "github.com/kazemisoroush/code-refactoring-tool/pkg/auth/mocks"
+)
+
+func newTestMFAService(ctrl *gomock.Controller) (AuthService, *authMocks.MockAuthProvider, *mocks.MockUserRepository, *mocks.MockMFARecoveryCodeRepository) {
+	authProvider := authMocks.NewMockAuthProvider(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	recoveryCodeRepo := mocks.NewMockMFARecoveryCodeRepository(ctrl)
+	return NewAuthService(authProvider, userRepo, recoveryCodeRepo, nil), authProvider, userRepo, recoveryCodeRepo
+}
+
+func TestAuthServiceImpl_SignIn_MFAChallenge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, authProvider, _, _ := newTestMFAService(ctrl)
+	ctx := context.Background()
+
+	authProvider.EXPECT().
+		SignIn(ctx, &auth.SignInRequest{Username: "test@example.com", Password: "password123"}).
+		Return(&auth.AuthResult{Challenge: &auth.MFAChallenge{Method: auth.MFAMethodTOTP, Session: "session-1"}}, nil)
+
+	response, err := service.SignIn(ctx, &models.SignInRequest{Username: "test@example.com", Password: "password123"})
+
+	require.NoError(t, err)
+	assert.Empty(t, response.AccessToken)
+	assert.Equal(t, &models.MFAChallenge{Method: models.MFAMethodTOTP, Session: "session-1"}, response.MFAChallenge)
+}
+
+func TestAuthServiceImpl_RespondToMFAChallenge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, authProvider, userRepo, _ := newTestMFAService(ctrl)
+	ctx := context.Background()
+	req := &models.MFAChallengeRequest{Username: "test@example.com", Method: models.MFAMethodTOTP, Session: "session-1", Code: "123456"}
+	challengeResp := &auth.MFAChallengeResponse{Username: "test@example.com", Method: auth.MFAMethodTOTP, Session: "session-1", Code: "123456"}
+
+	t.Run("successful challenge response", func(t *testing.T) {
+		authProvider.EXPECT().RespondToMFAChallenge(ctx, challengeResp).Return(&auth.AuthResult{
+			AccessToken: "access-token",
+			User:        &auth.User{ID: "auth-123", Email: "test@example.com", Status: auth.UserStatusActive},
+		}, nil)
+		user := &models.DBUser{UserID: "user-123", AuthID: "auth-123", Email: "test@example.com"}
+		userRepo.EXPECT().GetUserByAuthID(ctx, "auth-123").Return(user, nil)
+		userRepo.EXPECT().UpdateUser(ctx, gomock.Any()).Return(user, nil)
+
+		response, err := service.RespondToMFAChallenge(ctx, req)
+
+		require.NoError(t, err)
+		assert.Equal(t, "access-token", response.AccessToken)
+		assert.Equal(t, "user-123", response.User.UserID)
+	})
+
+	t.Run("invalid one-time password", func(t *testing.T) {
+		authProvider.EXPECT().RespondToMFAChallenge(ctx, challengeResp).Return(nil, auth.ErrInvalidCode)
+
+		_, err := service.RespondToMFAChallenge(ctx, req)
+
+		assert.Equal(t, apperrors.CodeInvalidMFACode, apperrors.CodeOf(err))
+	})
+}
+
+func TestAuthServiceImpl_SetupMFA_TOTP(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, authProvider, userRepo, _ := newTestMFAService(ctrl)
+	ctx := context.Background()
+
+	userRepo.EXPECT().GetUserByAuthID(ctx, "auth-123").Return(&models.DBUser{UserID: "user-123", Email: "test@example.com"}, nil)
+	authProvider.EXPECT().SetupTOTP(ctx, "access-token").Return(&auth.TOTPSetup{SecretCode: "SECRET"}, nil)
+
+	response, err := service.SetupMFA(ctx, &models.MFASetupRequest{AuthID: "auth-123", AccessToken: "access-token", Method: models.MFAMethodTOTP})
+
+	require.NoError(t, err)
+	assert.False(t, response.Enabled)
+	assert.Equal(t, "SECRET", response.SecretCode)
+	assert.Equal(t, "otpauth://totp/CodeRefactorTool:test@example.com?issuer=CodeRefactorTool&secret=SECRET", response.OTPAuthURI)
+	// Recovery codes are only generated once TOTP is verified
+	assert.Empty(t, response.RecoveryCodes)
+}
+
+func TestAuthServiceImpl_SetupMFA_SMS(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, authProvider, userRepo, recoveryCodeRepo := newTestMFAService(ctrl)
+	ctx := context.Background()
+
+	userRepo.EXPECT().GetUserByAuthID(ctx, "auth-123").Return(&models.DBUser{UserID: "user-123", Email: "test@example.com"}, nil)
+	authProvider.EXPECT().EnableSMSMFA(ctx, "access-token", "+14155550100").Return(nil)
+	var storedHashes []string
+	recoveryCodeRepo.EXPECT().ReplaceRecoveryCodes(ctx, "user-123", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, codeHashes []string) error {
+		storedHashes = codeHashes
+		return nil
+	})
+
+	response, err := service.SetupMFA(ctx, &models.MFASetupRequest{
+		AuthID: "auth-123", AccessToken: "access-token", Method: models.MFAMethodSMS, PhoneNumber: "+14155550100",
+	})
+
+	require.NoError(t, err)
+	assert.True(t, response.Enabled)
+	require.Len(t, response.RecoveryCodes, recoveryCodeCount)
+	// Only the hashes of the codes are stored
+	for i, code := range response.RecoveryCodes {
+		assert.Regexp(t, `^[a-z2-7]{5}-[a-z2-7]{5}$`, code)
+		assert.Equal(t, hashRecoveryCode(code), storedHashes[i])
+	}
+}
+
+func TestAuthServiceImpl_RecoverMFA(t *testing.T) {
+	ctx := context.Background()
+	req := &models.MFARecoverRequest{Username: "test@example.com", Password: "password123", RecoveryCode: "ABCDE FGHIJ"}
+	signInReq := &auth.SignInRequest{Username: "test@example.com", Password: "password123"}
+	challenge := &auth.AuthResult{Challenge: &auth.MFAChallenge{Method: auth.MFAMethodTOTP, Session: "session-1"}}
+	user := &models.DBUser{UserID: "user-123", AuthID: "auth-123", Email: "test@example.com"}
+
+	t.Run("successful recovery", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, authProvider, userRepo, recoveryCodeRepo := newTestMFAService(ctrl)
+
+		gomock.InOrder(
+			authProvider.EXPECT().SignIn(ctx, signInReq).Return(challenge, nil),
+			recoveryCodeRepo.EXPECT().ConsumeRecoveryCode(ctx, "user-123", hashRecoveryCode("abcde-fghij")).Return(true, nil),
+			authProvider.EXPECT().DisableMFA(ctx, "auth-123").Return(nil),
+			recoveryCodeRepo.EXPECT().ReplaceRecoveryCodes(ctx, "user-123", nil).Return(nil),
+			authProvider.EXPECT().SignIn(ctx, signInReq).Return(&auth.AuthResult{
+				AccessToken: "access-token",
+				User:        &auth.User{ID: "auth-123", Email: "test@example.com", Status: auth.UserStatusActive},
+			}, nil),
+		)
+		userRepo.EXPECT().GetUserByEmail(ctx, "test@example.com").Return(user, nil)
+		userRepo.EXPECT().GetUserByAuthID(ctx, "auth-123").Return(user, nil)
+		userRepo.EXPECT().UpdateUser(ctx, gomock.Any()).Return(user, nil)
+
+		response, err := service.RecoverMFA(ctx, req)
+
+		require.NoError(t, err)
+		assert.Equal(t, "access-token", response.AccessToken)
+	})
+
+	t.Run("used or unknown recovery code", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, authProvider, userRepo, recoveryCodeRepo := newTestMFAService(ctrl)
+
+		authProvider.EXPECT().SignIn(ctx, signInReq).Return(challenge, nil)
+		userRepo.EXPECT().GetUserByEmail(ctx, "test@example.com").Return(user, nil)
+		recoveryCodeRepo.EXPECT().ConsumeRecoveryCode(ctx, "user-123", gomock.Any()).Return(false, nil)
+
+		_, err := service.RecoverMFA(ctx, req)
+
+		assert.ErrorIs(t, err, apperrors.ErrUnauthorized)
+		assert.Equal(t, apperrors.CodeInvalidRecoveryCode, apperrors.CodeOf(err))
+	})
+
+	t.Run("MFA not enabled", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, authProvider, _, _ := newTestMFAService(ctrl)
+
+		authProvider.EXPECT().SignIn(ctx, signInReq).Return(&auth.AuthResult{AccessToken: "access-token"}, nil)
+
+		_, err := service.RecoverMFA(ctx, req)
+
+		assert.Equal(t, apperrors.CodeMFANotEnabled, apperrors.CodeOf(err))
+	})
+}
@@ -3,8 +3,11 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
 	"github.com/kazemisoroush/code-refactoring-tool/api/models"
 	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
 	"github.com/kazemisoroush/code-refactoring-tool/pkg/auth"
@@ -34,19 +37,35 @@ type AuthService interface {
 	UpdateUser(ctx context.Context, req *models.UpdateUserRequest) (*models.UpdateUserResponse, error)
 	DeleteUser(ctx context.Context, userID string) error
 	ListUsers(ctx context.Context, req *models.ListUsersRequest) (*models.ListUsersResponse, error)
+
+	// Self-Service (operations of the current user on their own account)
+	UpdateProfile(ctx context.Context, req *models.UpdateProfileRequest) (*models.UpdateUserResponse, error)
+	ChangePassword(ctx context.Context, req *models.ChangePasswordRequest) error
+	DeleteAccount(ctx context.Context, req *models.DeleteAccountRequest) error
+
+	// Multi-Factor Authentication
+	SetupMFA(ctx context.Context, req *models.MFASetupRequest) (*models.MFASetupResponse, error)
+	VerifyMFA(ctx context.Context, req *models.VerifyMFARequest) (*models.MFARecoveryCodesResponse, error)
+	RespondToMFAChallenge(ctx context.Context, req *models.MFAChallengeRequest) (*models.SignInResponse, error)
+	RecoverMFA(ctx context.Context, req *models.MFARecoverRequest) (*models.SignInResponse, error)
+	RegenerateRecoveryCodes(ctx context.Context, authID string) (*models.MFARecoveryCodesResponse, error)
 }
 
 // AuthServiceImpl implements AuthService with dependency injection
 type AuthServiceImpl struct {
-	authProvider auth.AuthProvider
-	userRepo     repository.UserRepository
+	authProvider     auth.AuthProvider
+	userRepo         repository.UserRepository
+	recoveryCodeRepo repository.MFARecoveryCodeRepository
+	roleRepo         repository.RoleRepository
 }
 
 // NewAuthService creates a new AuthService with injected dependencies
-func NewAuthService(authProvider auth.AuthProvider, userRepo repository.UserRepository) AuthService {
+func NewAuthService(authProvider auth.AuthProvider, userRepo repository.UserRepository, recoveryCodeRepo repository.MFARecoveryCodeRepository, roleRepo repository.RoleRepository) AuthService {
 	return &AuthServiceImpl{
-		authProvider: authProvider,
-		userRepo:     userRepo,
+		authProvider:     authProvider,
+		userRepo:         userRepo,
+		recoveryCodeRepo: recoveryCodeRepo,
+		roleRepo:         roleRepo,
 	}
 }
 
@@ -60,9 +79,24 @@ func (s *AuthServiceImpl) SignIn(ctx context.Context, req *models.SignInRequest)
 
 	authResult, err := s.authProvider.SignIn(ctx, authReq)
 	if err != nil {
-		return nil, fmt.Errorf("authentication failed: %w", err)
+		return nil, apperrors.Wrap(apperrors.ErrUnauthorized, apperrors.CodeAuthenticationFailed, err, "authentication failed")
+	}
+
+	// Users with MFA enabled complete their sign in by answering the challenge
+	if authResult.Challenge != nil {
+		return &models.SignInResponse{
+			MFAChallenge: &models.MFAChallenge{
+				Method:  models.MFAMethod(authResult.Challenge.Method),
+				Session: authResult.Challenge.Session,
+			},
+		}, nil
 	}
 
+	return s.signedIn(ctx, authResult)
+}
+
+// signedIn syncs the user of a completed authentication to the database and returns their tokens
+func (s *AuthServiceImpl) signedIn(ctx context.Context, authResult *auth.AuthResult) (*models.SignInResponse, error) {
 	// Sync user to database (create if not exists, update if exists)
 	dbUser, err := s.syncUserToDatabase(ctx, authResult.User)
 	if err != nil {
@@ -83,13 +117,18 @@ func (s *AuthServiceImpl) ValidateToken(ctx context.Context, token string) (*mod
 	// Provider-agnostic token validation
 	claims, err := s.authProvider.ValidateToken(ctx, token)
 	if err != nil {
-		return nil, fmt.Errorf("token validation failed: %w", err)
+		return nil, apperrors.Wrap(apperrors.ErrUnauthorized, apperrors.CodeInvalidToken, err, "token validation failed")
 	}
 
 	// Get user from our database using the auth provider's user ID
 	user, err := s.userRepo.GetUserByAuthID(ctx, claims.UserID)
 	if err != nil {
-		return nil, fmt.Errorf("user not found in database: %w", err)
+		return nil, apperrors.Wrap(apperrors.ErrUnauthorized, apperrors.CodeUserNotFound, err, "user not found in database")
+	}
+
+	permissions, err := s.getUserPermissions(ctx, user.Role)
+	if err != nil {
+		return nil, err
 	}
 
 	return &models.UserContext{
@@ -98,7 +137,7 @@ func (s *AuthServiceImpl) ValidateToken(ctx context.Context, token string) (*mod
 		Email:       user.Email,
 		Username:    user.Username,
 		Role:        user.Role,
-		Permissions: s.getUserPermissions(user.Role),
+		Permissions: permissions,
 	}, nil
 }
 
@@ -115,7 +154,7 @@ func (s *AuthServiceImpl) SignUp(ctx context.Context, req *models.SignUpRequest)
 
 	authResult, err := s.authProvider.SignUp(ctx, authReq)
 	if err != nil {
-		return nil, fmt.Errorf("user creation failed: %w", err)
+		return nil, wrapProviderError(err, "user creation failed")
 	}
 
 	// Create user in our database
@@ -137,7 +176,7 @@ func (s *AuthServiceImpl) SignUp(ctx context.Context, req *models.SignUpRequest)
 func (s *AuthServiceImpl) RefreshToken(ctx context.Context, req *models.RefreshTokenRequest) (*models.RefreshTokenResponse, error) {
 	authResult, err := s.authProvider.RefreshToken(ctx, req.RefreshToken)
 	if err != nil {
-		return nil, fmt.Errorf("token refresh failed: %w", err)
+		return nil, apperrors.Wrap(apperrors.ErrUnauthorized, apperrors.CodeInvalidToken, err, "token refresh failed")
 	}
 
 	return &models.RefreshTokenResponse{
@@ -159,6 +198,12 @@ func (s *AuthServiceImpl) SignOut(ctx context.Context, req *models.SignOutReques
 
 // CreateUser creates a new user (admin operation)
 func (s *AuthServiceImpl) CreateUser(ctx context.Context, req *models.CreateUserRequest) (*models.CreateUserResponse, error) {
+	if req.Role != "" {
+		if err := s.checkRoleChange(ctx, req.ActorID, "", req.Role); err != nil {
+			return nil, err
+		}
+	}
+
 	// Create in auth provider first
 	authReq := &auth.CreateUserRequest{
 		Username:  req.Username,
@@ -169,7 +214,7 @@ func (s *AuthServiceImpl) CreateUser(ctx context.Context, req *models.CreateUser
 
 	authUser, err := s.authProvider.CreateUser(ctx, authReq)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create user in auth provider: %w", err)
+		return nil, wrapProviderError(err, "failed to create user in auth provider")
 	}
 
 	// Create in our database
@@ -178,6 +223,14 @@ func (s *AuthServiceImpl) CreateUser(ctx context.Context, req *models.CreateUser
 		return nil, fmt.Errorf("failed to sync user to database: %w", err)
 	}
 
+	// The role is only held in our database
+	if req.Role != "" && req.Role != dbUser.Role {
+		dbUser.Role = req.Role
+		if dbUser, err = s.userRepo.UpdateUser(ctx, dbUser); err != nil {
+			return nil, fmt.Errorf("failed to set user role: %w", err)
+		}
+	}
+
 	return &models.CreateUserResponse{
 		User: s.mapToAPIUser(dbUser),
 	}, nil
@@ -189,6 +242,9 @@ func (s *AuthServiceImpl) GetUser(ctx context.Context, userID string) (*models.G
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
+	if user == nil {
+		return nil, apperrors.NotFound(apperrors.CodeUserNotFound, "user not found: %s", userID)
+	}
 
 	return &models.GetUserResponse{
 		User: s.mapToAPIUser(user),
@@ -202,6 +258,15 @@ func (s *AuthServiceImpl) UpdateUser(ctx context.Context, req *models.UpdateUser
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
+	if user == nil {
+		return nil, apperrors.NotFound(apperrors.CodeUserNotFound, "user not found: %s", req.UserID)
+	}
+
+	if req.Role != nil && *req.Role != user.Role {
+		if err := s.checkRoleChange(ctx, req.ActorID, user.Role, *req.Role); err != nil {
+			return nil, err
+		}
+	}
 
 	// Update in auth provider
 	authReq := &auth.UpdateUserRequest{
@@ -212,7 +277,7 @@ func (s *AuthServiceImpl) UpdateUser(ctx context.Context, req *models.UpdateUser
 
 	authUser, err := s.authProvider.UpdateUser(ctx, user.AuthID, authReq)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update user in auth provider: %w", err)
+		return nil, wrapProviderError(err, "failed to update user in auth provider")
 	}
 
 	// Update in our database
@@ -221,6 +286,14 @@ func (s *AuthServiceImpl) UpdateUser(ctx context.Context, req *models.UpdateUser
 		return nil, fmt.Errorf("failed to sync user to database: %w", err)
 	}
 
+	// The role is only held in our database
+	if req.Role != nil && *req.Role != updatedUser.Role {
+		updatedUser.Role = *req.Role
+		if updatedUser, err = s.userRepo.UpdateUser(ctx, updatedUser); err != nil {
+			return nil, fmt.Errorf("failed to update user role: %w", err)
+		}
+	}
+
 	return &models.UpdateUserResponse{
 		User: s.mapToAPIUser(updatedUser),
 	}, nil
@@ -233,11 +306,14 @@ func (s *AuthServiceImpl) DeleteUser(ctx context.Context, userID string) error {
 	if err != nil {
 		return fmt.Errorf("failed to get user: %w", err)
 	}
+	if user == nil {
+		return apperrors.NotFound(apperrors.CodeUserNotFound, "user not found: %s", userID)
+	}
 
 	// Delete from auth provider
 	err = s.authProvider.DeleteUser(ctx, user.AuthID)
 	if err != nil {
-		return fmt.Errorf("failed to delete user from auth provider: %w", err)
+		return wrapProviderError(err, "failed to delete user from auth provider")
 	}
 
 	// Delete from our database
@@ -274,8 +350,135 @@ func (s *AuthServiceImpl) ListUsers(ctx context.Context, req *models.ListUsersRe
 	}, nil
 }
 
+// UpdateProfile updates the profile of the current user
+func (s *AuthServiceImpl) UpdateProfile(ctx context.Context, req *models.UpdateProfileRequest) (*models.UpdateUserResponse, error) {
+	user, err := s.getCurrentUser(ctx, req.AuthID)
+	if err != nil {
+		return nil, err
+	}
+
+	authReq := &auth.UpdateUserRequest{
+		Email:     req.Email,
+		FirstName: req.FirstName,
+		LastName:  req.LastName,
+	}
+
+	authUser, err := s.authProvider.UpdateUser(ctx, user.AuthID, authReq)
+	if err != nil {
+		return nil, wrapProviderError(err, "failed to update user in auth provider")
+	}
+
+	updatedUser, err := s.syncUserToDatabase(ctx, authUser)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sync user to database: %w", err)
+	}
+
+	return &models.UpdateUserResponse{
+		User: s.mapToAPIUser(updatedUser),
+	}, nil
+}
+
+// ChangePassword changes the password of the current user, who must provide their current password
+func (s *AuthServiceImpl) ChangePassword(ctx context.Context, req *models.ChangePasswordRequest) error {
+	changeReq := &auth.ChangePasswordRequest{
+		AccessToken:      req.AccessToken,
+		PreviousPassword: req.CurrentPassword,
+		ProposedPassword: req.NewPassword,
+	}
+
+	if err := s.authProvider.ChangePassword(ctx, changeReq); err != nil {
+		return wrapProviderError(err, "failed to change password")
+	}
+
+	return nil
+}
+
+// DeleteAccount deletes the account of the current user once they confirm it with its email address. The last owner
+// can't delete their account, so the deployment keeps someone able to manage users.
+func (s *AuthServiceImpl) DeleteAccount(ctx context.Context, req *models.DeleteAccountRequest) error {
+	user, err := s.getCurrentUser(ctx, req.AuthID)
+	if err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(req.ConfirmEmail, user.Email) {
+		return apperrors.Validation(apperrors.CodeDeletionNotConfirmed, "confirm_email does not match the email address of the account")
+	}
+
+	if user.Role == models.RoleOwner {
+		role := models.RoleOwner
+		_, owners, err := s.userRepo.ListUsers(ctx, &repository.ListUsersFilter{Limit: 1, Role: &role})
+		if err != nil {
+			return fmt.Errorf("failed to count owners: %w", err)
+		}
+		if owners <= 1 {
+			return apperrors.Conflict(apperrors.CodeLastOwner, "the last owner can't delete their account, make another user an owner first")
+		}
+	}
+
+	return s.DeleteUser(ctx, user.UserID)
+}
+
 // Private helper methods
 
+// checkRoleChange makes sure a user can be moved from one role to another by the actor. Roles are defined in the role
+// registry, so custom roles can be granted too. Only owners grant or remove the owner role, and the last owner keeps it
+// so the deployment keeps someone able to manage users.
+func (s *AuthServiceImpl) checkRoleChange(ctx context.Context, actorID string, from, to models.UserRole) error {
+	role, err := s.roleRepo.GetRole(ctx, to)
+	if err != nil {
+		return err
+	}
+	if role == nil {
+		return apperrors.NotFound(apperrors.CodeRoleNotFound, "role not found: %s", to)
+	}
+
+	if from != models.RoleOwner && to != models.RoleOwner {
+		return nil
+	}
+
+	actor, err := s.getCurrentUser(ctx, actorID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return apperrors.Forbidden(apperrors.CodeForbidden, "caller has no user account")
+		}
+		return err
+	}
+	if actor.Role != models.RoleOwner {
+		return apperrors.Forbidden(apperrors.CodeForbidden, "only an owner can grant or remove the owner role")
+	}
+
+	if from == models.RoleOwner {
+		owner := models.RoleOwner
+		_, owners, err := s.userRepo.ListUsers(ctx, &repository.ListUsersFilter{Limit: 1, Role: &owner})
+		if err != nil {
+			return fmt.Errorf("failed to count owners: %w", err)
+		}
+		if owners <= 1 {
+			return apperrors.Conflict(apperrors.CodeLastOwner, "the last owner can't lose the owner role, make another user an owner first")
+		}
+	}
+
+	return nil
+}
+
+// getCurrentUser returns the user signed in with the auth provider ID
+func (s *AuthServiceImpl) getCurrentUser(ctx context.Context, authID string) (*models.DBUser, error) {
+	if authID == "" {
+		return nil, apperrors.Unauthorized(apperrors.CodeUnauthorized, "user ID not found in context")
+	}
+
+	user, err := s.userRepo.GetUserByAuthID(ctx, authID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return user, nil
+}
+
 // syncUserToDatabase creates or updates a user in our database based on auth provider user
 func (s *AuthServiceImpl) syncUserToDatabase(ctx context.Context, authUser *auth.User) (*models.DBUser, error) {
 	// Try to get existing user
@@ -307,6 +510,11 @@ func (s *AuthServiceImpl) syncUserToDatabase(ctx context.Context, authUser *auth
 
 // mapToAPIUser converts database user to API user
 func (s *AuthServiceImpl) mapToAPIUser(dbUser *models.DBUser) *models.APIUser {
+	return newAPIUser(dbUser)
+}
+
+// newAPIUser converts a database user to the user returned by the API
+func newAPIUser(dbUser *models.DBUser) *models.APIUser {
 	return &models.APIUser{
 		UserID:    dbUser.UserID,
 		Email:     dbUser.Email,
@@ -336,46 +544,30 @@ func (s *AuthServiceImpl) mapAuthStatusToDBStatus(authStatus auth.UserStatus) mo
 	}
 }
 
-// getUserPermissions returns permissions based on user role
-func (s *AuthServiceImpl) getUserPermissions(role models.UserRole) []models.Permission {
-	switch role {
-	case models.RoleOwner:
-		return []models.Permission{
-			models.PermissionProjectCreate, models.PermissionProjectRead,
-			models.PermissionProjectUpdate, models.PermissionProjectDelete,
-			models.PermissionTaskCreate, models.PermissionTaskExecute,
-			models.PermissionTaskRead, models.PermissionTaskUpdate, models.PermissionTaskDelete,
-			models.PermissionAgentCreate, models.PermissionAgentRead,
-			models.PermissionAgentUpdate, models.PermissionAgentDelete,
-			models.PermissionUserCreate, models.PermissionUserRead,
-			models.PermissionUserUpdate, models.PermissionUserDelete,
+// getUserPermissions returns the permissions a role grants. The owner role holds every permission.
+func (s *AuthServiceImpl) getUserPermissions(ctx context.Context, roleName models.UserRole) ([]models.Permission, error) {
+	if roleName == models.RoleOwner {
+		definitions, err := s.roleRepo.ListPermissions(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list permissions: %w", err)
 		}
-	case models.RoleAdmin:
-		return []models.Permission{
-			models.PermissionProjectCreate, models.PermissionProjectRead,
-			models.PermissionProjectUpdate, models.PermissionProjectDelete,
-			models.PermissionTaskCreate, models.PermissionTaskExecute,
-			models.PermissionTaskRead, models.PermissionTaskUpdate, models.PermissionTaskDelete,
-			models.PermissionAgentCreate, models.PermissionAgentRead,
-			models.PermissionAgentUpdate, models.PermissionAgentDelete,
-			models.PermissionUserRead, models.PermissionUserUpdate,
-		}
-	case models.RoleDeveloper:
-		return []models.Permission{
-			models.PermissionProjectCreate, models.PermissionProjectRead,
-			models.PermissionProjectUpdate,
-			models.PermissionTaskCreate, models.PermissionTaskExecute, models.PermissionTaskRead,
-			models.PermissionAgentCreate, models.PermissionAgentRead,
-		}
-	case models.RoleViewer:
-		return []models.Permission{
-			models.PermissionProjectRead,
-			models.PermissionTaskRead,
-			models.PermissionAgentRead,
+
+		permissions := make([]models.Permission, len(definitions))
+		for i, definition := range definitions {
+			permissions[i] = definition.Name
 		}
-	default:
-		return []models.Permission{}
+		return permissions, nil
+	}
+
+	role, err := s.roleRepo.GetRole(ctx, roleName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get role: %w", err)
 	}
+	if role == nil {
+		return []models.Permission{}, nil
+	}
+
+	return role.Permissions, nil
 }
 
 // ConfirmEmail confirms user email with verification code
@@ -383,7 +575,7 @@ func (s *AuthServiceImpl) ConfirmEmail(ctx context.Context, req *models.ConfirmE
 	// For email confirmation, we need to find the user by email to get their username/ID
 	user, err := s.userRepo.GetUserByEmail(ctx, req.Email)
 	if err != nil {
-		return fmt.Errorf("user not found: %w", err)
+		return apperrors.Wrap(apperrors.ErrNotFound, apperrors.CodeUserNotFound, err, "user not found")
 	}
 
 	// Use the auth provider's ConfirmSignUp method with the user's auth ID
@@ -407,3 +599,21 @@ func (s *AuthServiceImpl) ResetPassword(ctx context.Context, req *models.ResetPa
 
 	return s.authProvider.ConfirmPasswordReset(ctx, resetReq)
 }
+
+// wrapProviderError classifies well-known auth provider errors so the API can map them to status codes
+func wrapProviderError(err error, message string) error {
+	switch {
+	case errors.Is(err, auth.ErrUserNotFound):
+		return apperrors.Wrap(apperrors.ErrNotFound, apperrors.CodeUserNotFound, err, "%s", message)
+	case errors.Is(err, auth.ErrUserAlreadyExists):
+		return apperrors.Wrap(apperrors.ErrConflict, apperrors.CodeUserExists, err, "%s", message)
+	case errors.Is(err, auth.ErrInvalidCredentials):
+		return apperrors.Wrap(apperrors.ErrUnauthorized, apperrors.CodeAuthenticationFailed, err, "%s", message)
+	case errors.Is(err, auth.ErrInvalidPassword):
+		return apperrors.Wrap(apperrors.ErrValidation, apperrors.CodeInvalidPassword, err, "%s", message)
+	case errors.Is(err, auth.ErrInvalidCode):
+		return apperrors.Wrap(apperrors.ErrValidation, apperrors.CodeInvalidMFACode, err, "%s", message)
+	default:
+		return fmt.Errorf("%s: %w", message, err)
+	}
+}
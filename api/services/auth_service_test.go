@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
 	"github.com/kazemisoroush/code-refactoring-tool/api/models"
 	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
 	"github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
@@ -29,7 +30,7 @@ func TestNewAuthService(t *testing.T) {
 	mockAuthProvider := authMocks.NewMockAuthProvider(ctrl)
 	mockUserRepo := mocks.NewMockUserRepository(ctrl)
 
-	service := NewAuthService(mockAuthProvider, mockUserRepo)
+	service := NewAuthService(mockAuthProvider, mockUserRepo, nil, nil)
 
 	assert.NotNil(t, service)
 	assert.IsType(t, &AuthServiceImpl{}, service)
@@ -41,7 +42,7 @@ func TestAuthServiceImpl_SignIn(t *testing.T) {
 
 	mockAuthProvider := authMocks.NewMockAuthProvider(ctrl)
 	mockUserRepo := mocks.NewMockUserRepository(ctrl)
-	service := NewAuthService(mockAuthProvider, mockUserRepo)
+	service := NewAuthService(mockAuthProvider, mockUserRepo, nil, nil)
 
 	ctx := context.Background()
 	now := time.Now()
@@ -235,7 +236,8 @@ func TestAuthServiceImpl_ValidateToken(t *testing.T) {
 
 	mockAuthProvider := authMocks.NewMockAuthProvider(ctrl)
 	mockUserRepo := mocks.NewMockUserRepository(ctrl)
-	service := NewAuthService(mockAuthProvider, mockUserRepo)
+	mockRoleRepo := mocks.NewMockRoleRepository(ctrl)
+	service := NewAuthService(mockAuthProvider, mockUserRepo, nil, mockRoleRepo)
 
 	ctx := context.Background()
 	now := time.Now()
@@ -270,6 +272,10 @@ func TestAuthServiceImpl_ValidateToken(t *testing.T) {
 			GetUserByAuthID(ctx, "auth-123").
 			Return(user, nil)
 
+		mockRoleRepo.EXPECT().
+			GetRole(ctx, models.RoleAdmin).
+			Return(&models.Role{Name: models.RoleAdmin, Permissions: []models.Permission{models.PermissionProjectRead}}, nil)
+
 		result, err := service.ValidateToken(ctx, token)
 
 		require.NoError(t, err)
@@ -324,7 +330,7 @@ func TestAuthServiceImpl_SignUp(t *testing.T) {
 
 	mockAuthProvider := authMocks.NewMockAuthProvider(ctrl)
 	mockUserRepo := mocks.NewMockUserRepository(ctrl)
-	service := NewAuthService(mockAuthProvider, mockUserRepo)
+	service := NewAuthService(mockAuthProvider, mockUserRepo, nil, nil)
 
 	ctx := context.Background()
 	now := time.Now()
@@ -421,7 +427,7 @@ func TestAuthServiceImpl_RefreshToken(t *testing.T) {
 
 	mockAuthProvider := authMocks.NewMockAuthProvider(ctrl)
 	mockUserRepo := mocks.NewMockUserRepository(ctrl)
-	service := NewAuthService(mockAuthProvider, mockUserRepo)
+	service := NewAuthService(mockAuthProvider, mockUserRepo, nil, nil)
 
 	ctx := context.Background()
 
@@ -473,7 +479,7 @@ func TestAuthServiceImpl_SignOut(t *testing.T) {
 
 	mockAuthProvider := authMocks.NewMockAuthProvider(ctrl)
 	mockUserRepo := mocks.NewMockUserRepository(ctrl)
-	service := NewAuthService(mockAuthProvider, mockUserRepo)
+	service := NewAuthService(mockAuthProvider, mockUserRepo, nil, nil)
 
 	ctx := context.Background()
 
@@ -513,7 +519,7 @@ func TestAuthServiceImpl_CreateUser(t *testing.T) {
 
 	mockAuthProvider := authMocks.NewMockAuthProvider(ctrl)
 	mockUserRepo := mocks.NewMockUserRepository(ctrl)
-	service := NewAuthService(mockAuthProvider, mockUserRepo)
+	service := NewAuthService(mockAuthProvider, mockUserRepo, nil, nil)
 
 	ctx := context.Background()
 	now := time.Now()
@@ -597,7 +603,7 @@ func TestAuthServiceImpl_GetUser(t *testing.T) {
 
 	mockAuthProvider := authMocks.NewMockAuthProvider(ctrl)
 	mockUserRepo := mocks.NewMockUserRepository(ctrl)
-	service := NewAuthService(mockAuthProvider, mockUserRepo)
+	service := NewAuthService(mockAuthProvider, mockUserRepo, nil, nil)
 
 	ctx := context.Background()
 	now := time.Now()
@@ -651,7 +657,7 @@ func TestAuthServiceImpl_UpdateUser(t *testing.T) {
 
 	mockAuthProvider := authMocks.NewMockAuthProvider(ctrl)
 	mockUserRepo := mocks.NewMockUserRepository(ctrl)
-	service := NewAuthService(mockAuthProvider, mockUserRepo)
+	service := NewAuthService(mockAuthProvider, mockUserRepo, nil, nil)
 
 	ctx := context.Background()
 	now := time.Now()
@@ -770,13 +776,209 @@ func TestAuthServiceImpl_UpdateUser(t *testing.T) {
 	})
 }
 
+func TestAuthServiceImpl_UpdateUser_Role(t *testing.T) {
+	ctx := context.Background()
+	securityReviewer := models.UserRole("security-reviewer")
+
+	t.Run("grants a custom role", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockAuthProvider := authMocks.NewMockAuthProvider(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockRoleRepo := mocks.NewMockRoleRepository(ctrl)
+		service := NewAuthService(mockAuthProvider, mockUserRepo, nil, mockRoleRepo)
+
+		existingUser := &models.DBUser{UserID: "user-123", AuthID: "auth-123", Role: models.RoleDeveloper}
+		mockUserRepo.EXPECT().GetUser(ctx, "user-123").Return(existingUser, nil)
+		mockRoleRepo.EXPECT().GetRole(ctx, securityReviewer).Return(&models.Role{Name: securityReviewer}, nil)
+		mockAuthProvider.EXPECT().UpdateUser(ctx, "auth-123", gomock.Any()).Return(&auth.User{ID: "auth-123", Status: auth.UserStatusActive}, nil)
+		mockUserRepo.EXPECT().GetUserByAuthID(ctx, "auth-123").Return(&models.DBUser{UserID: "user-123", AuthID: "auth-123", Role: models.RoleDeveloper}, nil)
+		gomock.InOrder(
+			mockUserRepo.EXPECT().UpdateUser(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, user *models.DBUser) (*models.DBUser, error) {
+				return user, nil
+			}),
+			mockUserRepo.EXPECT().UpdateUser(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, user *models.DBUser) (*models.DBUser, error) {
+				assert.Equal(t, securityReviewer, user.Role)
+				return user, nil
+			}),
+		)
+
+		result, err := service.UpdateUser(ctx, &models.UpdateUserRequest{UserID: "user-123", Role: &securityReviewer})
+
+		require.NoError(t, err)
+		assert.Equal(t, securityReviewer, result.User.Role)
+	})
+
+	t.Run("unknown role", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockRoleRepo := mocks.NewMockRoleRepository(ctrl)
+		service := NewAuthService(authMocks.NewMockAuthProvider(ctrl), mockUserRepo, nil, mockRoleRepo)
+
+		mockUserRepo.EXPECT().GetUser(ctx, "user-123").Return(&models.DBUser{UserID: "user-123", AuthID: "auth-123", Role: models.RoleDeveloper}, nil)
+		mockRoleRepo.EXPECT().GetRole(ctx, securityReviewer).Return(nil, nil)
+
+		_, err := service.UpdateUser(ctx, &models.UpdateUserRequest{UserID: "user-123", Role: &securityReviewer})
+
+		assert.Equal(t, apperrors.CodeRoleNotFound, apperrors.CodeOf(err))
+	})
+
+	ownerRole := models.RoleOwner
+	developerRole := models.RoleDeveloper
+
+	t.Run("admin can't grant the owner role", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockRoleRepo := mocks.NewMockRoleRepository(ctrl)
+		service := NewAuthService(authMocks.NewMockAuthProvider(ctrl), mockUserRepo, nil, mockRoleRepo)
+
+		mockUserRepo.EXPECT().GetUser(ctx, "user-123").Return(&models.DBUser{UserID: "user-123", AuthID: "auth-123", Role: models.RoleDeveloper}, nil)
+		mockRoleRepo.EXPECT().GetRole(ctx, models.RoleOwner).Return(&models.Role{Name: models.RoleOwner}, nil)
+		mockUserRepo.EXPECT().GetUserByAuthID(ctx, "auth-admin").Return(&models.DBUser{UserID: "user-admin", AuthID: "auth-admin", Role: models.RoleAdmin}, nil)
+
+		_, err := service.UpdateUser(ctx, &models.UpdateUserRequest{UserID: "user-123", Role: &ownerRole, ActorID: "auth-admin"})
+
+		assert.ErrorIs(t, err, apperrors.ErrForbidden)
+	})
+
+	t.Run("admin can't remove the owner role", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockRoleRepo := mocks.NewMockRoleRepository(ctrl)
+		service := NewAuthService(authMocks.NewMockAuthProvider(ctrl), mockUserRepo, nil, mockRoleRepo)
+
+		mockUserRepo.EXPECT().GetUser(ctx, "user-123").Return(&models.DBUser{UserID: "user-123", AuthID: "auth-123", Role: models.RoleOwner}, nil)
+		mockRoleRepo.EXPECT().GetRole(ctx, models.RoleDeveloper).Return(&models.Role{Name: models.RoleDeveloper}, nil)
+		mockUserRepo.EXPECT().GetUserByAuthID(ctx, "auth-admin").Return(&models.DBUser{UserID: "user-admin", AuthID: "auth-admin", Role: models.RoleAdmin}, nil)
+
+		_, err := service.UpdateUser(ctx, &models.UpdateUserRequest{UserID: "user-123", Role: &developerRole, ActorID: "auth-admin"})
+
+		assert.ErrorIs(t, err, apperrors.ErrForbidden)
+	})
+
+	t.Run("last owner keeps the owner role", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockRoleRepo := mocks.NewMockRoleRepository(ctrl)
+		service := NewAuthService(authMocks.NewMockAuthProvider(ctrl), mockUserRepo, nil, mockRoleRepo)
+
+		owner := &models.DBUser{UserID: "user-123", AuthID: "auth-123", Role: models.RoleOwner}
+		mockUserRepo.EXPECT().GetUser(ctx, "user-123").Return(owner, nil)
+		mockRoleRepo.EXPECT().GetRole(ctx, models.RoleDeveloper).Return(&models.Role{Name: models.RoleDeveloper}, nil)
+		mockUserRepo.EXPECT().GetUserByAuthID(ctx, "auth-123").Return(owner, nil)
+		mockUserRepo.EXPECT().
+			ListUsers(ctx, &repository.ListUsersFilter{Limit: 1, Role: &ownerRole}).
+			Return([]*models.DBUser{owner}, 1, nil)
+
+		_, err := service.UpdateUser(ctx, &models.UpdateUserRequest{UserID: "user-123", Role: &developerRole, ActorID: "auth-123"})
+
+		assert.Equal(t, apperrors.CodeLastOwner, apperrors.CodeOf(err))
+	})
+
+	t.Run("owner removes the owner role of another owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockAuthProvider := authMocks.NewMockAuthProvider(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockRoleRepo := mocks.NewMockRoleRepository(ctrl)
+		service := NewAuthService(mockAuthProvider, mockUserRepo, nil, mockRoleRepo)
+
+		mockUserRepo.EXPECT().GetUser(ctx, "user-123").Return(&models.DBUser{UserID: "user-123", AuthID: "auth-123", Role: models.RoleOwner}, nil)
+		mockRoleRepo.EXPECT().GetRole(ctx, models.RoleDeveloper).Return(&models.Role{Name: models.RoleDeveloper}, nil)
+		mockUserRepo.EXPECT().GetUserByAuthID(ctx, "auth-owner").Return(&models.DBUser{UserID: "user-owner", AuthID: "auth-owner", Role: models.RoleOwner}, nil)
+		mockUserRepo.EXPECT().
+			ListUsers(ctx, &repository.ListUsersFilter{Limit: 1, Role: &ownerRole}).
+			Return([]*models.DBUser{{UserID: "user-owner"}}, 2, nil)
+		mockAuthProvider.EXPECT().UpdateUser(ctx, "auth-123", gomock.Any()).Return(&auth.User{ID: "auth-123", Status: auth.UserStatusActive}, nil)
+		mockUserRepo.EXPECT().GetUserByAuthID(ctx, "auth-123").Return(&models.DBUser{UserID: "user-123", AuthID: "auth-123", Role: models.RoleOwner}, nil)
+		mockUserRepo.EXPECT().UpdateUser(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, user *models.DBUser) (*models.DBUser, error) {
+			return user, nil
+		}).Times(2)
+
+		result, err := service.UpdateUser(ctx, &models.UpdateUserRequest{UserID: "user-123", Role: &developerRole, ActorID: "auth-owner"})
+
+		require.NoError(t, err)
+		assert.Equal(t, models.RoleDeveloper, result.User.Role)
+	})
+}
+
+func TestAuthServiceImpl_CreateUser_Role(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("applies the role", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockAuthProvider := authMocks.NewMockAuthProvider(ctrl)
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockRoleRepo := mocks.NewMockRoleRepository(ctrl)
+		service := NewAuthService(mockAuthProvider, mockUserRepo, nil, mockRoleRepo)
+
+		mockRoleRepo.EXPECT().GetRole(ctx, models.RoleViewer).Return(&models.Role{Name: models.RoleViewer}, nil)
+		mockAuthProvider.EXPECT().CreateUser(ctx, gomock.Any()).Return(&auth.User{ID: "auth-789", Status: auth.UserStatusActive}, nil)
+		mockUserRepo.EXPECT().GetUserByAuthID(ctx, "auth-789").Return(nil, apperrors.ErrNotFound)
+		mockUserRepo.EXPECT().CreateUser(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, user *models.DBUser) (*models.DBUser, error) {
+			return user, nil
+		})
+		mockUserRepo.EXPECT().UpdateUser(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, user *models.DBUser) (*models.DBUser, error) {
+			assert.Equal(t, models.RoleViewer, user.Role)
+			return user, nil
+		})
+
+		result, err := service.CreateUser(ctx, &models.CreateUserRequest{Username: "viewer", Email: "viewer@example.com", Role: models.RoleViewer, ActorID: "auth-admin"})
+
+		require.NoError(t, err)
+		assert.Equal(t, models.RoleViewer, result.User.Role)
+	})
+
+	t.Run("admin can't create an owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUserRepo := mocks.NewMockUserRepository(ctrl)
+		mockRoleRepo := mocks.NewMockRoleRepository(ctrl)
+		service := NewAuthService(authMocks.NewMockAuthProvider(ctrl), mockUserRepo, nil, mockRoleRepo)
+
+		mockRoleRepo.EXPECT().GetRole(ctx, models.RoleOwner).Return(&models.Role{Name: models.RoleOwner}, nil)
+		mockUserRepo.EXPECT().GetUserByAuthID(ctx, "auth-admin").Return(&models.DBUser{UserID: "user-admin", AuthID: "auth-admin", Role: models.RoleAdmin}, nil)
+
+		_, err := service.CreateUser(ctx, &models.CreateUserRequest{Username: "owner", Email: "owner@example.com", Role: models.RoleOwner, ActorID: "auth-admin"})
+
+		assert.ErrorIs(t, err, apperrors.ErrForbidden)
+	})
+
+	t.Run("unknown role", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRoleRepo := mocks.NewMockRoleRepository(ctrl)
+		service := NewAuthService(authMocks.NewMockAuthProvider(ctrl), mocks.NewMockUserRepository(ctrl), nil, mockRoleRepo)
+
+		mockRoleRepo.EXPECT().GetRole(ctx, models.UserRole("security-reviewer")).Return(nil, nil)
+
+		_, err := service.CreateUser(ctx, &models.CreateUserRequest{Username: "reviewer", Email: "reviewer@example.com", Role: "security-reviewer", ActorID: "auth-admin"})
+
+		assert.Equal(t, apperrors.CodeRoleNotFound, apperrors.CodeOf(err))
+	})
+}
+
 func TestAuthServiceImpl_DeleteUser(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockAuthProvider := authMocks.NewMockAuthProvider(ctrl)
 	mockUserRepo := mocks.NewMockUserRepository(ctrl)
-	service := NewAuthService(mockAuthProvider, mockUserRepo)
+	service := NewAuthService(mockAuthProvider, mockUserRepo, nil, nil)
 
 	ctx := context.Background()
 
@@ -841,13 +1043,162 @@ func TestAuthServiceImpl_DeleteUser(t *testing.T) {
 	})
 }
 
+func TestAuthServiceImpl_UpdateProfile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthProvider := authMocks.NewMockAuthProvider(ctrl)
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	service := NewAuthService(mockAuthProvider, mockUserRepo, nil, nil)
+
+	ctx := context.Background()
+
+	user := &models.DBUser{
+		UserID: "user-123",
+		AuthID: "auth-123",
+		Email:  "test@example.com",
+		Role:   models.RoleViewer,
+		Status: models.UserStatusActive,
+	}
+
+	mockUserRepo.EXPECT().GetUserByAuthID(ctx, "auth-123").Return(user, nil).Times(2)
+	mockAuthProvider.EXPECT().
+		UpdateUser(ctx, "auth-123", &auth.UpdateUserRequest{FirstName: stringPtr("Updated")}).
+		Return(&auth.User{ID: "auth-123", Email: "test@example.com", FirstName: stringPtr("Updated"), Status: auth.UserStatusActive}, nil)
+	mockUserRepo.EXPECT().UpdateUser(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, updated *models.DBUser) (*models.DBUser, error) {
+		return updated, nil
+	})
+
+	response, err := service.UpdateProfile(ctx, &models.UpdateProfileRequest{AuthID: "auth-123", FirstName: stringPtr("Updated")})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Updated", *response.User.FirstName)
+	// The role is kept
+	assert.Equal(t, models.RoleViewer, response.User.Role)
+}
+
+func TestAuthServiceImpl_ChangePassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthProvider := authMocks.NewMockAuthProvider(ctrl)
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	service := NewAuthService(mockAuthProvider, mockUserRepo, nil, nil)
+
+	ctx := context.Background()
+	req := &models.ChangePasswordRequest{AccessToken: "access-token", CurrentPassword: "old-password", NewPassword: "new-password"}
+	changeReq := &auth.ChangePasswordRequest{AccessToken: "access-token", PreviousPassword: "old-password", ProposedPassword: "new-password"}
+
+	t.Run("successful password change", func(t *testing.T) {
+		mockAuthProvider.EXPECT().ChangePassword(ctx, changeReq).Return(nil)
+
+		assert.NoError(t, service.ChangePassword(ctx, req))
+	})
+
+	t.Run("incorrect current password", func(t *testing.T) {
+		mockAuthProvider.EXPECT().ChangePassword(ctx, changeReq).Return(auth.ErrInvalidCredentials)
+
+		err := service.ChangePassword(ctx, req)
+
+		assert.ErrorIs(t, err, apperrors.ErrUnauthorized)
+	})
+
+	t.Run("new password violates the password policy", func(t *testing.T) {
+		mockAuthProvider.EXPECT().ChangePassword(ctx, changeReq).Return(auth.ErrInvalidPassword)
+
+		err := service.ChangePassword(ctx, req)
+
+		assert.ErrorIs(t, err, apperrors.ErrValidation)
+		assert.Equal(t, apperrors.CodeInvalidPassword, apperrors.CodeOf(err))
+	})
+}
+
+func TestAuthServiceImpl_DeleteAccount(t *testing.T) {
+	ctx := context.Background()
+	ownerRole := models.RoleOwner
+
+	tests := []struct {
+		name         string
+		role         models.UserRole
+		confirmEmail string
+		setupMocks   func(*authMocks.MockAuthProvider, *mocks.MockUserRepository)
+		expectedCode string
+	}{
+		{
+			name:         "successful account deletion",
+			role:         models.RoleDeveloper,
+			confirmEmail: "Test@Example.com",
+			setupMocks: func(authProvider *authMocks.MockAuthProvider, userRepo *mocks.MockUserRepository) {
+				userRepo.EXPECT().GetUser(ctx, "user-123").Return(&models.DBUser{UserID: "user-123", AuthID: "auth-123"}, nil)
+				authProvider.EXPECT().DeleteUser(ctx, "auth-123").Return(nil)
+				userRepo.EXPECT().DeleteUser(ctx, "user-123").Return(nil)
+			},
+		},
+		{
+			name:         "email address not matching the account",
+			role:         models.RoleDeveloper,
+			confirmEmail: "other@example.com",
+			setupMocks:   func(*authMocks.MockAuthProvider, *mocks.MockUserRepository) {},
+			expectedCode: apperrors.CodeDeletionNotConfirmed,
+		},
+		{
+			name:         "last owner",
+			role:         models.RoleOwner,
+			confirmEmail: "test@example.com",
+			setupMocks: func(_ *authMocks.MockAuthProvider, userRepo *mocks.MockUserRepository) {
+				userRepo.EXPECT().
+					ListUsers(ctx, &repository.ListUsersFilter{Limit: 1, Role: &ownerRole}).
+					Return([]*models.DBUser{{UserID: "user-123"}}, 1, nil)
+			},
+			expectedCode: apperrors.CodeLastOwner,
+		},
+		{
+			name:         "owner with another owner",
+			role:         models.RoleOwner,
+			confirmEmail: "test@example.com",
+			setupMocks: func(authProvider *authMocks.MockAuthProvider, userRepo *mocks.MockUserRepository) {
+				userRepo.EXPECT().
+					ListUsers(ctx, &repository.ListUsersFilter{Limit: 1, Role: &ownerRole}).
+					Return([]*models.DBUser{{UserID: "user-456"}}, 2, nil)
+				userRepo.EXPECT().GetUser(ctx, "user-123").Return(&models.DBUser{UserID: "user-123", AuthID: "auth-123"}, nil)
+				authProvider.EXPECT().DeleteUser(ctx, "auth-123").Return(nil)
+				userRepo.EXPECT().DeleteUser(ctx, "user-123").Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockAuthProvider := authMocks.NewMockAuthProvider(ctrl)
+			mockUserRepo := mocks.NewMockUserRepository(ctrl)
+			service := NewAuthService(mockAuthProvider, mockUserRepo, nil, nil)
+
+			mockUserRepo.EXPECT().
+				GetUserByAuthID(ctx, "auth-123").
+				Return(&models.DBUser{UserID: "user-123", AuthID: "auth-123", Email: "test@example.com", Role: tt.role}, nil)
+			tt.setupMocks(mockAuthProvider, mockUserRepo)
+
+			err := service.DeleteAccount(ctx, &models.DeleteAccountRequest{AuthID: "auth-123", ConfirmEmail: tt.confirmEmail})
+
+			if tt.expectedCode == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tt.expectedCode, apperrors.CodeOf(err))
+			}
+		})
+	}
+}
+
 func TestAuthServiceImpl_ListUsers(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockAuthProvider := authMocks.NewMockAuthProvider(ctrl)
 	mockUserRepo := mocks.NewMockUserRepository(ctrl)
-	service := NewAuthService(mockAuthProvider, mockUserRepo)
+	service := NewAuthService(mockAuthProvider, mockUserRepo, nil, nil)
 
 	ctx := context.Background()
 	now := time.Now()
@@ -959,26 +1310,40 @@ func TestAuthServiceImpl_getUserPermissions(t *testing.T) {
 
 	mockAuthProvider := authMocks.NewMockAuthProvider(ctrl)
 	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockRoleRepo := mocks.NewMockRoleRepository(ctrl)
 	service := &AuthServiceImpl{
 		authProvider: mockAuthProvider,
 		userRepo:     mockUserRepo,
+		roleRepo:     mockRoleRepo,
 	}
 
+	// The role registry holds the built-in roles as seeded
+	mockRoleRepo.EXPECT().ListPermissions(gomock.Any()).Return(models.BuiltInPermissions, nil).AnyTimes()
+	mockRoleRepo.EXPECT().GetRole(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, name models.UserRole) (*models.Role, error) {
+		for _, role := range models.BuiltInRoles {
+			if role.Name == name {
+				return &role, nil
+			}
+		}
+		return nil, nil
+	}).AnyTimes()
+
 	tests := []struct {
 		name     string
 		role     models.UserRole
 		expected int // Number of expected permissions
 	}{
-		{"Owner", models.RoleOwner, 17},            // All permissions
-		{"Admin", models.RoleAdmin, 15},            // Most permissions except user create/delete
-		{"Developer", models.RoleDeveloper, 8},     // Basic development permissions
-		{"Viewer", models.RoleViewer, 3},           // Read-only permissions
-		{"Unknown", models.UserRole("unknown"), 0}, // No permissions
+		{"Owner", models.RoleOwner, len(models.BuiltInPermissions)}, // All permissions
+		{"Admin", models.RoleAdmin, 23},                             // Most permissions except user create/delete, ownership transfer and role changes
+		{"Developer", models.RoleDeveloper, 8},                      // Basic development permissions
+		{"Viewer", models.RoleViewer, 3},                            // Read-only permissions
+		{"Unknown", models.UserRole("unknown"), 0},                  // No permissions
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			permissions := service.getUserPermissions(tt.role)
+			permissions, err := service.getUserPermissions(context.Background(), tt.role)
+			require.NoError(t, err)
 			assert.Len(t, permissions, tt.expected)
 
 			// Verify specific permissions for each role
@@ -991,6 +1356,7 @@ func TestAuthServiceImpl_getUserPermissions(t *testing.T) {
 				assert.NotContains(t, permissions, models.PermissionUserCreate)
 				assert.NotContains(t, permissions, models.PermissionUserDelete)
 				assert.Contains(t, permissions, models.PermissionProjectDelete)
+				assert.Contains(t, permissions, models.PermissionComplianceExport)
 			case models.RoleDeveloper:
 				assert.Contains(t, permissions, models.PermissionProjectCreate)
 				assert.NotContains(t, permissions, models.PermissionProjectDelete)
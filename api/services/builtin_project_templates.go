@@ -0,0 +1,89 @@
+package services
+
+import (
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// Built-in project template identifiers
+const (
+	// BuiltinTemplateGoMicroservice bootstraps a single-repository Go service
+	BuiltinTemplateGoMicroservice = "go-microservice"
+	// BuiltinTemplatePythonMonorepo bootstraps a multi-package Python monorepo
+	BuiltinTemplatePythonMonorepo = "python-monorepo"
+)
+
+// builtinProjectTemplates returns the templates shipped with the service.
+// A fresh copy is returned on every call so callers may modify the result.
+func builtinProjectTemplates() []*models.ProjectTemplate {
+	goLanguage := "go"
+	pythonLanguage := "python"
+
+	return []*models.ProjectTemplate{
+		{
+			TemplateID:  BuiltinTemplateGoMicroservice,
+			Name:        "Go microservice",
+			Description: "A single Go service repository with linting, review and documentation defaults",
+			Language:    &goLanguage,
+			BuiltIn:     true,
+			DefaultTags: map[string]string{"language": "go", "kind": "microservice"},
+			CodebaseConfigs: []models.CodebaseConfigSkeleton{
+				{Name: "service", Provider: models.ProviderGitHub, DefaultBranch: "main"},
+			},
+			PromptTemplates: []models.PromptTemplate{
+				{
+					Name:        "error-handling-review",
+					TaskType:    models.TaskTypeCodeReview,
+					Title:       "Review error handling",
+					Description: "Find swallowed errors, missing error wrapping and panics in request paths",
+				},
+				{
+					Name:        "interface-extraction",
+					TaskType:    models.TaskTypeRefactoring,
+					Title:       "Extract interfaces for external dependencies",
+					Description: "Introduce small interfaces around external clients so they can be mocked in tests",
+				},
+			},
+			ScheduledAnalyses: []models.ScheduledAnalysis{
+				{
+					Name:     "nightly-analysis",
+					Schedule: "0 3 * * *",
+					TaskType: models.TaskTypeCodeAnalysis,
+					Prompt:   "Run a full static analysis and summarize new findings since the last run",
+				},
+			},
+		},
+		{
+			TemplateID:  BuiltinTemplatePythonMonorepo,
+			Name:        "Python monorepo",
+			Description: "A Python monorepo with several packages sharing tooling",
+			Language:    &pythonLanguage,
+			BuiltIn:     true,
+			DefaultTags: map[string]string{"language": "python", "kind": "monorepo"},
+			CodebaseConfigs: []models.CodebaseConfigSkeleton{
+				{Name: "monorepo", Provider: models.ProviderGitHub, DefaultBranch: "main"},
+			},
+			PromptTemplates: []models.PromptTemplate{
+				{
+					Name:        "type-hints",
+					TaskType:    models.TaskTypeRefactoring,
+					Title:       "Add type hints",
+					Description: "Add type hints to public functions and fix the resulting type checker errors",
+				},
+				{
+					Name:        "package-docs",
+					TaskType:    models.TaskTypeDocumentation,
+					Title:       "Document packages",
+					Description: "Write a README for each package describing its purpose and public API",
+				},
+			},
+			ScheduledAnalyses: []models.ScheduledAnalysis{
+				{
+					Name:     "weekly-review",
+					Schedule: "0 6 * * 1",
+					TaskType: models.TaskTypeCodeReview,
+					Prompt:   "Review code merged in the last week for cross-package coupling",
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,18 @@
+package services
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// CampaignService defines the interface for campaigns that apply one instruction across many codebases
+//
+//go:generate mockgen -destination=./mocks/mock_campaign_service.go -mock_names=CampaignService=MockCampaignService -package=mocks . CampaignService
+type CampaignService interface {
+	// CreateCampaign creates a child task per codebase and starts running them in the background
+	CreateCampaign(ctx context.Context, request models.CreateCampaignRequest) (*models.CreateCampaignResponse, error)
+
+	// GetCampaign retrieves a campaign's progress and per-codebase results
+	GetCampaign(ctx context.Context, request models.GetCampaignRequest) (*models.GetCampaignResponse, error)
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+)
+
+// clientTokenClaimTimeout is how long a create request holds its client token before a retry may take it over, so
+// that a request interrupted between claiming its token and creating its resource doesn't block retries forever
+const clientTokenClaimTimeout = time.Minute
+
+// Kinds of resources whose create requests accept a client token
+const (
+	clientTokenKindProject             = "project"
+	clientTokenKindCodebase            = "codebase"
+	clientTokenKindNotificationChannel = "notification_channel"
+)
+
+// clientTokenClaim is the claim of a create request on its client token. The zero value claims nothing, for requests
+// without a client token.
+type clientTokenClaim struct {
+	repo  repository.ClientTokenRepository
+	scope string
+	token string
+}
+
+// claimClientToken claims the client token of a create request of a kind of resource by a caller. request, with its
+// client token cleared, is hashed so that the token can't be reused for a different request. When an earlier request
+// carrying the token created its resource, the resource's ID is returned instead of a claim; otherwise the claim must
+// be finished once the resource is created.
+func claimClientToken(ctx context.Context, repo repository.ClientTokenRepository, kind, userID, clientToken string, request any) (*clientTokenClaim, string, error) {
+	if clientToken == "" {
+		return &clientTokenClaim{}, "", nil
+	}
+
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to hash request: %w", err)
+	}
+	hash := sha256.Sum256(payload)
+
+	claim := &clientTokenClaim{repo: repo, scope: kind + ":" + userID, token: clientToken}
+	record := &models.ClientToken{
+		Scope:       claim.scope,
+		Token:       clientToken,
+		RequestHash: hex.EncodeToString(hash[:]),
+		CreatedAt:   time.Now().UTC(),
+	}
+
+	existing, err := repo.ClaimToken(ctx, record)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to claim client token: %w", err)
+	}
+	if existing == nil {
+		return claim, "", nil
+	}
+	if existing.RequestHash != record.RequestHash {
+		return nil, "", apperrors.Conflict(apperrors.CodeClientTokenConflict, "client token %s was used with a different request", clientToken)
+	}
+	if existing.ResourceID != nil {
+		return nil, *existing.ResourceID, nil
+	}
+
+	// The earlier request is still in progress, or was interrupted and its claim can be taken over
+	if record.CreatedAt.Sub(existing.CreatedAt) < clientTokenClaimTimeout {
+		return nil, "", apperrors.Conflict(apperrors.CodeClientTokenConflict, "a request with client token %s is in progress", clientToken)
+	}
+	if err := repo.ReleaseToken(ctx, claim.scope, clientToken); err != nil {
+		return nil, "", fmt.Errorf("failed to release client token: %w", err)
+	}
+	return claimClientToken(ctx, repo, kind, userID, clientToken, request)
+}
+
+// finish records the resource the request created, or releases the token when creating it failed so that the request
+// can be retried. The resource exists whatever happens to the token, so failing to record it is only logged.
+func (c *clientTokenClaim) finish(ctx context.Context, resourceID string, createErr error) {
+	if c.token == "" {
+		return
+	}
+
+	if createErr != nil {
+		if err := c.repo.ReleaseToken(ctx, c.scope, c.token); err != nil {
+			slog.WarnContext(ctx, "failed to release client token", "scope", c.scope, "error", err)
+		}
+		return
+	}
+	if err := c.repo.CompleteToken(ctx, c.scope, c.token, resourceID); err != nil {
+		slog.WarnContext(ctx, "failed to complete client token", "scope", c.scope, "resource_id", resourceID, "error", err)
+	}
+}
@@ -0,0 +1,18 @@
+package services
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// CodeMetricsService defines the interface for the maintainability metrics history of codebases
+//
+//go:generate mockgen -destination=./mocks/mock_code_metrics_service.go -mock_names=CodeMetricsService=MockCodeMetricsService -package=mocks . CodeMetricsService
+type CodeMetricsService interface {
+	// RecordSnapshot measures the clone of an analysis task's codebase in dir and stores the snapshot of its commit
+	RecordSnapshot(ctx context.Context, task *models.TaskWithFullContext, dir string) (*models.CodeMetricsSnapshot, error)
+
+	// GetMetrics retrieves the snapshots of a codebase and how they changed over the period
+	GetMetrics(ctx context.Context, request models.GetCodeMetricsRequest) (*models.GetCodeMetricsResponse, error)
+}
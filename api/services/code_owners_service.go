@@ -0,0 +1,22 @@
+package services
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// CodeOwnersService defines the interface for the ownership of codebase files parsed from their CODEOWNERS files
+//
+//go:generate mockgen -destination=./mocks/mock_code_owners_service.go -mock_names=CodeOwnersService=MockCodeOwnersService -package=mocks . CodeOwnersService
+type CodeOwnersService interface {
+	// RecordOwners parses the CODEOWNERS file of a codebase checked out at dir and replaces its recorded ownership.
+	// A checkout without a CODEOWNERS file records that the codebase's files have no owners.
+	RecordOwners(ctx context.Context, codebaseID, dir, commitSHA string) (*models.CodeOwners, error)
+
+	// GetOwners returns the recorded ownership of a codebase's files, and the owners of the requested path if any
+	GetOwners(ctx context.Context, request models.GetCodeOwnersRequest) (*models.GetCodeOwnersResponse, error)
+
+	// ResolveOwners returns the sorted owners of any of the paths of a codebase, none if its ownership isn't recorded
+	ResolveOwners(ctx context.Context, codebaseID string, paths []string) ([]string, error)
+}
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// CodebaseBrowseService defines the interface for reading branches, commits and files of a codebase's repository
+// through its git provider, using the credentials of the codebase's configuration
+//
+//go:generate mockgen -destination=./mocks/mock_codebase_browse_service.go -mock_names=CodebaseBrowseService=MockCodebaseBrowseService -package=mocks . CodebaseBrowseService
+type CodebaseBrowseService interface {
+	// ListBranches lists the branches of the codebase's repository
+	ListBranches(ctx context.Context, request models.ListCodebaseBranchesRequest) (*models.ListCodebaseBranchesResponse, error)
+
+	// ListCommits lists the latest commits of a ref of the codebase's repository
+	ListCommits(ctx context.Context, request models.ListCodebaseCommitsRequest) (*models.ListCodebaseCommitsResponse, error)
+
+	// ListFiles lists a directory of the codebase's repository
+	ListFiles(ctx context.Context, request models.ListCodebaseFilesRequest) (*models.ListCodebaseFilesResponse, error)
+
+	// ResolveRevision checks that branch and commitSHA exist in the codebase's repository and resolves them to a full
+	// commit SHA. Without commitSHA the current head of branch is pinned.
+	ResolveRevision(ctx context.Context, codebaseID, branch, commitSHA string) (*models.CodebaseRevision, error)
+
+	// GetDefaultBranchHead resolves the current head of the codebase's default branch. Branch is empty when the
+	// codebase's configuration leaves the default branch to the git provider.
+	GetDefaultBranchHead(ctx context.Context, codebaseID string) (*models.CodebaseRevision, error)
+}
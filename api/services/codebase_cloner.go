@@ -0,0 +1,16 @@
+package services
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// CodebaseCloner checks out a codebase on local disk for the analyses that read its files
+//
+//go:generate mockgen -destination=./mocks/mock_codebase_cloner.go -mock_names=CodebaseCloner=MockCodebaseCloner -package=mocks . CodebaseCloner
+type CodebaseCloner interface {
+	// Clone checks out the codebase at commitSHA, or at the head of branch, for a task and returns its directory and a
+	// function releasing it. The task is empty for analyses not run by a task, such as ingestion scans.
+	Clone(ctx context.Context, taskID string, codebase *models.Codebase, branch, commitSHA string) (string, func(), error)
+}
@@ -3,12 +3,17 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
 	"github.com/kazemisoroush/code-refactoring-tool/api/models"
 	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/gitprovider"
+	"golang.org/x/crypto/ssh"
 )
 
 // CodebaseConfigService defines the interface for codebase configuration business logic
@@ -34,14 +39,18 @@ type CodebaseConfigService interface {
 // DefaultCodebaseConfigService implements CodebaseConfigService
 type DefaultCodebaseConfigService struct {
 	repository repository.CodebaseConfigRepository
+	tagService TagService
 }
 
-// NewDefaultCodebaseConfigService creates a new DefaultCodebaseConfigService
+// NewDefaultCodebaseConfigService creates a new DefaultCodebaseConfigService. The tag service governs the tags set on
+// configurations.
 func NewDefaultCodebaseConfigService(
 	repository repository.CodebaseConfigRepository,
+	tagService TagService,
 ) CodebaseConfigService {
 	return &DefaultCodebaseConfigService{
 		repository: repository,
+		tagService: tagService,
 	}
 }
 
@@ -49,12 +58,18 @@ func NewDefaultCodebaseConfigService(
 func (s *DefaultCodebaseConfigService) CreateCodebaseConfig(ctx context.Context, request models.CreateCodebaseConfigRequest) (*models.CreateCodebaseConfigResponse, error) {
 	// Validate the provider
 	if !request.Provider.IsValid() {
-		return nil, fmt.Errorf("invalid provider: %s", request.Provider)
+		return nil, apperrors.Validation(apperrors.CodeInvalidProvider, "invalid provider: %s", request.Provider)
 	}
 
 	// Validate provider-specific configuration
 	if err := s.validateProviderConfig(request.Provider, request.Config); err != nil {
-		return nil, fmt.Errorf("invalid provider configuration: %w", err)
+		return nil, apperrors.Wrap(apperrors.ErrValidation, apperrors.CodeInvalidProvider, err, "invalid provider configuration")
+	}
+
+	if len(request.Tags) > 0 {
+		if err := s.tagService.AuthorizeTags(ctx, request.UserID, nil, request.Tags); err != nil {
+			return nil, err
+		}
 	}
 
 	// Generate unique configuration ID
@@ -118,16 +133,24 @@ func (s *DefaultCodebaseConfigService) UpdateCodebaseConfig(ctx context.Context,
 		// Validate provider-specific configuration
 		provider := models.Provider(existing.Provider)
 		if err := s.validateProviderConfig(provider, *request.Config); err != nil {
-			return nil, fmt.Errorf("invalid provider configuration: %w", err)
+			return nil, apperrors.Wrap(apperrors.ErrValidation, apperrors.CodeInvalidProvider, err, "invalid provider configuration")
 		}
 		existing.Config = *request.Config
 	}
 	if request.Tags != nil {
+		if err := s.tagService.AuthorizeTags(ctx, request.UserID, existing.Tags, request.Tags); err != nil {
+			return nil, err
+		}
 		existing.Tags = request.Tags
 	}
 
 	existing.UpdatedAt = now
 
+	// Only apply the update on top of the version the client read
+	if request.ExpectedVersion != 0 {
+		existing.Version = request.ExpectedVersion
+	}
+
 	// Update in repository
 	if err := s.repository.UpdateCodebaseConfig(ctx, existing); err != nil {
 		return nil, fmt.Errorf("failed to update codebase configuration: %w", err)
@@ -136,6 +159,7 @@ func (s *DefaultCodebaseConfigService) UpdateCodebaseConfig(ctx context.Context,
 	return &models.UpdateCodebaseConfigResponse{
 		ConfigID:  request.ConfigID,
 		UpdatedAt: now.Format(time.RFC3339),
+		Version:   existing.Version,
 	}, nil
 }
 
@@ -147,7 +171,7 @@ func (s *DefaultCodebaseConfigService) DeleteCodebaseConfig(ctx context.Context,
 		return nil, fmt.Errorf("failed to check if codebase configuration exists: %w", err)
 	}
 	if !exists {
-		return nil, fmt.Errorf("codebase configuration not found")
+		return nil, apperrors.NotFound(apperrors.CodeCodebaseConfigNotFound, "codebase configuration not found")
 	}
 
 	// Delete the configuration
@@ -211,6 +235,8 @@ func (s *DefaultCodebaseConfigService) validateProviderFields(provider models.Pr
 		return s.validateGitLabConfig(config)
 	case models.ProviderBitbucket:
 		return s.validateBitbucketConfig(config)
+	case models.ProviderAzureDevOps:
+		return s.validateAzureDevOpsConfig(config)
 	case models.ProviderCustom:
 		return s.validateCustomConfig(config)
 	default:
@@ -260,6 +286,23 @@ func (s *DefaultCodebaseConfigService) validateBitbucketConfig(config models.Git
 	return nil
 }
 
+// validateAzureDevOpsConfig validates Azure DevOps-specific configuration
+func (s *DefaultCodebaseConfigService) validateAzureDevOpsConfig(config models.GitProviderConfig) error {
+	if config.AzureDevOps == nil {
+		return fmt.Errorf("azure DevOps configuration is required for Azure DevOps provider")
+	}
+	if config.AzureDevOps.Organization == "" {
+		return fmt.Errorf("azure DevOps organization is required")
+	}
+	if config.AzureDevOps.Project == "" {
+		return fmt.Errorf("azure DevOps project is required")
+	}
+	if config.AzureDevOps.Repository == "" {
+		return fmt.Errorf("azure DevOps repository is required")
+	}
+	return nil
+}
+
 // validateCustomConfig validates Custom provider configuration
 func (s *DefaultCodebaseConfigService) validateCustomConfig(config models.GitProviderConfig) error {
 	if config.Custom == nil {
@@ -280,6 +323,8 @@ func (s *DefaultCodebaseConfigService) validateAuthConfig(provider models.Provid
 		return s.validateBasicAuth(provider, config)
 	case models.GitAuthTypeSSH:
 		return s.validateSSHAuth(provider, config)
+	case models.GitAuthTypeGitHubApp:
+		return s.validateGitHubAppAuth(provider, config)
 	case models.GitAuthTypeOAuth:
 		return fmt.Errorf("OAuth authentication is not yet supported")
 	default:
@@ -302,6 +347,10 @@ func (s *DefaultCodebaseConfigService) validateTokenAuth(provider models.Provide
 		if config.Bitbucket.AppPassword == "" {
 			return fmt.Errorf("app password is required for Bitbucket token authentication")
 		}
+	case models.ProviderAzureDevOps:
+		if config.AzureDevOps.Token == "" {
+			return fmt.Errorf("personal access token is required for Azure DevOps token authentication")
+		}
 	case models.ProviderCustom:
 		if config.Custom.Token == "" {
 			return fmt.Errorf("token is required for custom token authentication")
@@ -329,6 +378,26 @@ func (s *DefaultCodebaseConfigService) validateSSHAuth(provider models.Provider,
 	if config.Custom.SSHKey == "" {
 		return fmt.Errorf("SSH key is required for SSH authentication")
 	}
+	if _, err := ssh.ParsePrivateKey([]byte(config.Custom.SSHKey)); err != nil {
+		return fmt.Errorf("SSH key is not a valid unencrypted private key: %w", err)
+	}
+	if err := validateKnownHosts(config.Custom.KnownHosts); err != nil {
+		return err
+	}
+	return nil
+}
+
+// validateGitHubAppAuth validates GitHub App installation authentication
+func (s *DefaultCodebaseConfigService) validateGitHubAppAuth(provider models.Provider, config models.GitProviderConfig) error {
+	if provider != models.ProviderGitHub {
+		return fmt.Errorf("GitHub App authentication is only supported for GitHub providers")
+	}
+	if config.GitHub.AppID <= 0 || config.GitHub.InstallationID <= 0 {
+		return fmt.Errorf("app ID and installation ID are required for GitHub App authentication")
+	}
+	if _, err := gitprovider.ParseRSAPrivateKey(config.GitHub.PrivateKey); err != nil {
+		return fmt.Errorf("private key for GitHub App authentication is invalid: %w", err)
+	}
 	return nil
 }
 
@@ -338,3 +407,19 @@ func generateCodebaseConfigID() string {
 	id := uuid.New().String()
 	return fmt.Sprintf("config-%s", id[:13])
 }
+
+// validateKnownHosts validates that each non-comment line of knownHosts is a known_hosts entry
+func validateKnownHosts(knownHosts string) error {
+	rest := []byte(knownHosts)
+	for len(rest) > 0 {
+		var err error
+		_, _, _, _, rest, err = ssh.ParseKnownHosts(rest)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("known hosts are invalid: %w", err)
+		}
+	}
+	return nil
+}
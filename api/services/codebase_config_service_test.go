@@ -1,17 +1,19 @@
 package services_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
 	"github.com/kazemisoroush/code-refactoring-tool/api/models"
 	"github.com/kazemisoroush/code-refactoring-tool/api/services"
 )
 
 func TestDefaultCodebaseConfigService_Basic(t *testing.T) {
 	// Basic test to ensure the service can be instantiated
-	service := services.NewDefaultCodebaseConfigService(nil)
+	service := services.NewDefaultCodebaseConfigService(nil, nil)
 	assert.NotNil(t, service)
 }
 
@@ -36,6 +38,11 @@ func TestProvider_IsValid(t *testing.T) {
 			provider: models.ProviderBitbucket,
 			expected: true,
 		},
+		{
+			name:     "valid azure devops provider",
+			provider: models.ProviderAzureDevOps,
+			expected: true,
+		},
 		{
 			name:     "valid custom provider",
 			provider: models.ProviderCustom,
@@ -55,3 +62,110 @@ func TestProvider_IsValid(t *testing.T) {
 		})
 	}
 }
+
+func TestDefaultCodebaseConfigService_CreateCodebaseConfig_GitHubAppRejected(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider models.Provider
+		config   models.GitProviderConfig
+	}{
+		{
+			name:     "missing installation",
+			provider: models.ProviderGitHub,
+			config: models.GitProviderConfig{AuthType: models.GitAuthTypeGitHubApp, GitHub: &models.GitHubConfig{
+				Owner: "acme", Repository: "payments", AppID: 7,
+			}},
+		},
+		{
+			name:     "invalid private key",
+			provider: models.ProviderGitHub,
+			config: models.GitProviderConfig{AuthType: models.GitAuthTypeGitHubApp, GitHub: &models.GitHubConfig{
+				Owner: "acme", Repository: "payments", AppID: 7, InstallationID: 42, PrivateKey: "not a key",
+			}},
+		},
+		{
+			name:     "not a GitHub provider",
+			provider: models.ProviderGitLab,
+			config: models.GitProviderConfig{AuthType: models.GitAuthTypeGitHubApp, GitLab: &models.GitLabConfig{
+				ProjectID: "42", Namespace: "acme",
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := services.NewDefaultCodebaseConfigService(nil, nil)
+
+			_, err := service.CreateCodebaseConfig(context.Background(), models.CreateCodebaseConfigRequest{
+				Name: "payments", Provider: tt.provider, Config: tt.config,
+			})
+
+			assert.ErrorIs(t, err, apperrors.ErrValidation)
+		})
+	}
+}
+
+func TestDefaultCodebaseConfigService_CreateCodebaseConfig_AzureDevOpsRejected(t *testing.T) {
+	tests := []struct {
+		name   string
+		config models.GitProviderConfig
+	}{
+		{
+			name:   "missing configuration",
+			config: models.GitProviderConfig{AuthType: models.GitAuthTypeToken},
+		},
+		{
+			name: "missing project",
+			config: models.GitProviderConfig{AuthType: models.GitAuthTypeToken, AzureDevOps: &models.AzureDevOpsConfig{
+				Organization: "acme", Repository: "payments", Token: "pat",
+			}},
+		},
+		{
+			name: "missing personal access token",
+			config: models.GitProviderConfig{AuthType: models.GitAuthTypeToken, AzureDevOps: &models.AzureDevOpsConfig{
+				Organization: "acme", Project: "billing", Repository: "payments",
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := services.NewDefaultCodebaseConfigService(nil, nil)
+
+			_, err := service.CreateCodebaseConfig(context.Background(), models.CreateCodebaseConfigRequest{
+				Name: "payments", Provider: models.ProviderAzureDevOps, Config: tt.config,
+			})
+
+			assert.ErrorIs(t, err, apperrors.ErrValidation)
+		})
+	}
+}
+
+func TestDefaultCodebaseConfigService_CreateCodebaseConfig_SSHRejected(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *models.CustomGitConfig
+	}{
+		{
+			name:   "missing key",
+			config: &models.CustomGitConfig{BaseURL: "ssh://git@git.acme.com"},
+		},
+		{
+			name:   "invalid key",
+			config: &models.CustomGitConfig{BaseURL: "ssh://git@git.acme.com", SSHKey: "not a key"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := services.NewDefaultCodebaseConfigService(nil, nil)
+
+			_, err := service.CreateCodebaseConfig(context.Background(), models.CreateCodebaseConfigRequest{
+				Name: "payments", Provider: models.ProviderCustom,
+				Config: models.GitProviderConfig{AuthType: models.GitAuthTypeSSH, Custom: tt.config},
+			})
+
+			assert.ErrorIs(t, err, apperrors.ErrValidation)
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package services
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// CodebaseUploadService manages the codebases uploaded as archives, for code that isn't hosted on a reachable git
+// remote
+//
+//go:generate mockgen -destination=./mocks/mock_codebase_upload_service.go -mock_names=CodebaseUploadService=MockCodebaseUploadService -package=mocks . CodebaseUploadService
+type CodebaseUploadService interface {
+	// CreateCodebaseUpload creates a codebase awaiting its archive and returns the presigned URL to upload it to
+	CreateCodebaseUpload(ctx context.Context, request models.CreateCodebaseUploadRequest) (*models.CreateCodebaseUploadResponse, error)
+
+	// CompleteCodebaseUpload checks that the archive of a codebase was uploaded and can be extracted, and activates
+	// the codebase
+	CompleteCodebaseUpload(ctx context.Context, codebaseID string) (*models.GetCodebaseResponse, error)
+
+	// DeleteExpiredCodebases deletes the uploaded codebases past their expiry along with their archives, and returns
+	// how many it deleted
+	DeleteExpiredCodebases(ctx context.Context) (int, error)
+}
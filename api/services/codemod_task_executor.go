@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"strings"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/codemod"
+)
+
+// CodemodTaskExecutorName names the executor running codemod tasks
+const CodemodTaskExecutorName = "codemod"
+
+// CodemodTaskExecutor executes codemod tasks by applying their deterministic transform to a checkout of the task's
+// codebase, without a model. The output records the transform, its parameters, the codemod version and the commit it
+// was applied to, so the change can be reproduced, along with the changes made and their diff, which is discarded
+// from the checkout afterwards.
+type CodemodTaskExecutor struct {
+	cloner CodebaseCloner
+}
+
+// NewCodemodTaskExecutor creates an executor applying codemods to checkouts made by the cloner
+func NewCodemodTaskExecutor(cloner CodebaseCloner) TaskExecutor {
+	return &CodemodTaskExecutor{cloner: cloner}
+}
+
+// Name implements TaskExecutor
+func (e *CodemodTaskExecutor) Name() string {
+	return CodemodTaskExecutorName
+}
+
+// Supports implements TaskExecutor
+func (e *CodemodTaskExecutor) Supports(taskType models.TaskType) bool {
+	return taskType == models.TaskTypeCodemod
+}
+
+// Execute implements TaskExecutor
+func (e *CodemodTaskExecutor) Execute(ctx context.Context, task *models.TaskWithFullContext) (map[string]any, error) {
+	if task.Codebase == nil {
+		return nil, fmt.Errorf("codemods require a codebase")
+	}
+	spec, err := codemod.ParseSpec(task.Input)
+	if err != nil {
+		return nil, err
+	}
+
+	var branch, commitSHA string
+	if task.Branch != nil {
+		branch = *task.Branch
+	}
+	if task.CommitSHA != nil {
+		commitSHA = *task.CommitSHA
+	}
+	dir, cleanup, err := e.cloner.Clone(ctx, task.TaskID, task.Codebase, branch, commitSHA)
+	if err != nil {
+		return nil, fmt.Errorf("failed to clone codebase: %w", err)
+	}
+	defer cleanup()
+
+	head, err := exec.CommandContext(ctx, "git", "-C", dir, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve checkout commit: %w", err)
+	}
+
+	output := map[string]any{
+		"execution_method": "codemod",
+		"codemod_version":  codemod.Version,
+		"transform":        spec.Transform,
+		"params":           spec.Params,
+		"base_commit":      strings.TrimSpace(string(head)),
+	}
+	result, applyErr := codemod.Apply(ctx, dir, *spec)
+
+	// Workspaces are reused by later tasks, so the edits are kept as a diff and undone, also when the codemod failed
+	diff, err := diffAndReset(context.WithoutCancel(ctx), dir)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to reset workspace after codemod", "error", err)
+	}
+	if applyErr != nil {
+		return output, fmt.Errorf("codemod failed: %w", applyErr)
+	}
+
+	output["files_changed"] = result.Files
+	output["changes"] = result.Changes
+	if diff != "" {
+		output["diff"] = truncateDiff(diff)
+	}
+	return output, nil
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	servicesMocks "github.com/kazemisoroush/code-refactoring-tool/api/services/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/codemod"
+)
+
+// newGoCheckout creates a repository with a committed Go module
+func newGoCheckout(t *testing.T) (string, string) {
+	dir := newGitCheckout(t)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/app\n\ngo 1.24\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app.go"), []byte("package app\n\nfunc Old() {}\n\nfunc Run() { Old() }\n"), 0o644))
+	for _, args := range [][]string{
+		{"add", "--all"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "module"},
+	} {
+		out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	head, err := exec.Command("git", "-C", dir, "rev-parse", "HEAD").Output()
+	require.NoError(t, err)
+	return dir, strings.TrimSpace(string(head))
+}
+
+func TestCodemodTaskExecutor_Execute(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir, head := newGoCheckout(t)
+	cloner := servicesMocks.NewMockCodebaseCloner(ctrl)
+	codebase := &models.Codebase{CodebaseID: "cb-1"}
+	task := &models.TaskWithFullContext{
+		Task: models.Task{TaskID: "task-1", Type: models.TaskTypeCodemod, Input: map[string]any{
+			"transform": "rename_symbol",
+			"params":    map[string]any{"package": "example.com/app", "symbol": "Old", "new_name": "New"},
+		}},
+		Codebase: codebase,
+	}
+	released := false
+	cloner.EXPECT().Clone(gomock.Any(), "task-1", codebase, "", "").Return(dir, func() { released = true }, nil)
+
+	output, err := NewCodemodTaskExecutor(cloner).Execute(context.Background(), task)
+
+	require.NoError(t, err)
+	assert.Equal(t, "codemod", output["execution_method"])
+	assert.Equal(t, codemod.Version, output["codemod_version"])
+	assert.Equal(t, "rename_symbol", output["transform"])
+	assert.Equal(t, head, output["base_commit"])
+	assert.Equal(t, []string{"app.go"}, output["files_changed"])
+	assert.Equal(t, []codemod.Change{
+		{File: "app.go", Line: 3, Message: "rename Old to New"},
+		{File: "app.go", Line: 5, Message: "rename Old to New"},
+	}, output["changes"])
+	assert.Contains(t, output["diff"], "+func Run() { New() }")
+	assert.True(t, released)
+
+	// The edits are undone, since the workspace is reused
+	content, err := os.ReadFile(filepath.Join(dir, "app.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "package app\n\nfunc Old() {}\n\nfunc Run() { Old() }\n", string(content))
+}
+
+func TestCodemodTaskExecutor_Execute_FailedTransformKeepsCheckoutClean(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir, _ := newGoCheckout(t)
+	cloner := servicesMocks.NewMockCodebaseCloner(ctrl)
+	task := &models.TaskWithFullContext{
+		Task: models.Task{TaskID: "task-1", Type: models.TaskTypeCodemod, Input: map[string]any{
+			"transform": "rename_symbol",
+			"params":    map[string]any{"package": "example.com/app", "symbol": "Missing", "new_name": "New"},
+		}},
+		Codebase: &models.Codebase{CodebaseID: "cb-1"},
+	}
+	cloner.EXPECT().Clone(gomock.Any(), "task-1", task.Codebase, "", "").Return(dir, func() {}, nil)
+
+	output, err := NewCodemodTaskExecutor(cloner).Execute(context.Background(), task)
+
+	assert.ErrorContains(t, err, "package example.com/app has no symbol Missing")
+	assert.Equal(t, "rename_symbol", output["transform"])
+	assert.NotContains(t, output, "diff")
+}
+
+func TestCodemodTaskExecutor_Supports(t *testing.T) {
+	executor := NewCodemodTaskExecutor(nil)
+
+	assert.True(t, executor.Supports(models.TaskTypeCodemod))
+	assert.False(t, executor.Supports(models.TaskTypeRefactoring))
+}
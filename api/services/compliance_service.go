@@ -0,0 +1,23 @@
+package services
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// ComplianceService defines the interface for exporting users, project access grants and the audit log for
+// compliance reviews
+//
+//go:generate mockgen -destination=./mocks/mock_compliance_service.go -mock_names=ComplianceService=MockComplianceService -package=mocks . ComplianceService
+type ComplianceService interface {
+	// ExportUsers passes every user created within the requested time range to fn, oldest first
+	ExportUsers(ctx context.Context, request models.ComplianceExportRequest, fn func(models.APIUser) error) error
+
+	// ExportAccessGrants passes every project member whose role last changed within the requested time range to fn,
+	// oldest change first
+	ExportAccessGrants(ctx context.Context, request models.ComplianceExportRequest, fn func(models.ProjectMember) error) error
+
+	// ExportAuditEvents passes every audit event that occurred within the requested time range to fn, oldest first
+	ExportAuditEvents(ctx context.Context, request models.ComplianceExportRequest, fn func(models.AuditEvent) error) error
+}
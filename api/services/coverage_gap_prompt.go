@@ -0,0 +1,35 @@
+package services
+
+import (
+	"strings"
+	"text/template"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// coverageGapTemplate asks the agent for unit tests of the ranked coverage gaps, leaving the code under test alone
+var coverageGapTemplate = template.Must(template.New("coverage_gap").Parse(`
+{{- with .Description}}{{.}}
+
+{{end -}}
+Write unit tests for these functions, which no test runs and whose files changed the most lately. The statements left uncovered are counted per function, lines for languages other than Go.
+{{- range .Report.Gaps}}
+- {{.Function}} in {{.FilePath}} lines {{.StartLine}}-{{.EndLine}}: {{.Uncovered}} of {{.Statements}} uncovered, {{.Commits}} commits
+{{- end}}
+
+Put the tests next to the existing tests of each function's package, following their layout and helpers. Cover the behaviour of the uncovered statements rather than their lines, and don't change the code under test. Run the tests and fix the ones that fail, then report each test file you wrote.
+`))
+
+// coverageGapPrompt renders the description of a coverage_gap task, followed by the gaps the agent writes tests for
+func coverageGapPrompt(description string, report *models.CoverageGapReport) string {
+	var prompt strings.Builder
+	data := struct {
+		Description string
+		Report      *models.CoverageGapReport
+	}{description, report}
+	if err := coverageGapTemplate.Execute(&prompt, data); err != nil {
+		// The template only reads fields of the report
+		return description
+	}
+	return prompt.String()
+}
@@ -0,0 +1,21 @@
+package services
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// CoverageGapService defines the interface for finding the functions of a codebase that need tests and proposing the
+// tests an agent wrote for them
+//
+//go:generate mockgen -destination=./mocks/mock_coverage_gap_service.go -mock_names=CoverageGapService=MockCoverageGapService -package=mocks . CoverageGapService
+type CoverageGapService interface {
+	// FindGaps measures the coverage of a coverage_gap task's codebase and ranks its uncovered functions by the churn
+	// of their files
+	FindGaps(ctx context.Context, task *models.TaskWithFullContext) (*models.CoverageGapReport, error)
+
+	// PublishTests commits the diff of the tests written for a report's gaps to a branch of the task and opens a pull
+	// request into the report's base branch
+	PublishTests(ctx context.Context, task *models.TaskWithFullContext, report *models.CoverageGapReport, diff string) (*models.PullRequest, error)
+}
@@ -0,0 +1,266 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+)
+
+// DefaultAgentResyncService is the default implementation of AgentResyncService
+type DefaultAgentResyncService struct {
+	settingsRepo  repository.AgentResyncSettingsRepository
+	headRepo      repository.CodebaseHeadRepository
+	projectRepo   repository.ProjectRepository
+	codebaseRepo  repository.CodebaseRepository
+	agentRepo     repository.AgentRepository
+	browseService CodebaseBrowseService
+	agentService  AgentService
+	debounce      time.Duration
+	now           func() time.Time
+}
+
+// NewDefaultAgentResyncService creates a new DefaultAgentResyncService. Agents are resynced once their codebase's
+// default branch hasn't moved for the debounce period, so a burst of pushes resyncs them once.
+func NewDefaultAgentResyncService(
+	settingsRepo repository.AgentResyncSettingsRepository,
+	headRepo repository.CodebaseHeadRepository,
+	projectRepo repository.ProjectRepository,
+	codebaseRepo repository.CodebaseRepository,
+	agentRepo repository.AgentRepository,
+	browseService CodebaseBrowseService,
+	agentService AgentService,
+	debounce time.Duration,
+) *DefaultAgentResyncService {
+	return &DefaultAgentResyncService{
+		settingsRepo:  settingsRepo,
+		headRepo:      headRepo,
+		projectRepo:   projectRepo,
+		codebaseRepo:  codebaseRepo,
+		agentRepo:     agentRepo,
+		browseService: browseService,
+		agentService:  agentService,
+		debounce:      debounce,
+		now:           time.Now,
+	}
+}
+
+// GetSettings returns the agent resync settings of a project, or the default settings if none are configured
+func (s *DefaultAgentResyncService) GetSettings(ctx context.Context, request models.GetAgentResyncSettingsRequest) (*models.AgentResyncSettings, error) {
+	if err := s.ensureProjectExists(ctx, request.ProjectID); err != nil {
+		return nil, err
+	}
+
+	return s.getSettings(ctx, request.ProjectID)
+}
+
+// UpdateSettings opts a project in or out of agent resyncs
+func (s *DefaultAgentResyncService) UpdateSettings(ctx context.Context, request models.UpdateAgentResyncSettingsRequest) (*models.AgentResyncSettings, error) {
+	if err := s.ensureProjectExists(ctx, request.ProjectID); err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	settings := &models.AgentResyncSettings{
+		ProjectID: request.ProjectID,
+		Enabled:   *request.Enabled,
+		UpdatedAt: &now,
+	}
+	if err := s.settingsRepo.SaveSettings(ctx, settings); err != nil {
+		return nil, fmt.Errorf("failed to save agent resync settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// CheckCodebases polls the default branch of the codebases agents are built from. Only agents of a project are
+// resynced: an agent belongs to a codebase of its project with the same repository URL. A codebase that fails to
+// be checked is logged and retried on the next call.
+func (s *DefaultAgentResyncService) CheckCodebases(ctx context.Context) error {
+	agents, err := s.agentRepo.ListAgents(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list agents: %w", err)
+	}
+
+	agentsByProject := make(map[string][]*repository.AgentRecord)
+	for _, agent := range agents {
+		if agent.ProjectID != "" {
+			agentsByProject[agent.ProjectID] = append(agentsByProject[agent.ProjectID], agent)
+		}
+	}
+
+	projectIDs := make([]string, 0, len(agentsByProject))
+	for projectID := range agentsByProject {
+		projectIDs = append(projectIDs, projectID)
+	}
+	sort.Strings(projectIDs)
+
+	for _, projectID := range projectIDs {
+		if err := s.checkProject(ctx, projectID, agentsByProject[projectID]); err != nil {
+			slog.WarnContext(ctx, "failed to check project codebases for agent resync", "project_id", projectID, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// RunCodebaseWatch checks the codebases every interval until the context is cancelled
+func (s *DefaultAgentResyncService) RunCodebaseWatch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.CheckCodebases(ctx); err != nil {
+				slog.ErrorContext(ctx, "failed to check codebases for agent resync", "error", err)
+			}
+		}
+	}
+}
+
+// checkProject checks the codebases of a project its agents are built from. Archived projects aren't checked.
+func (s *DefaultAgentResyncService) checkProject(ctx context.Context, projectID string, agents []*repository.AgentRecord) error {
+	project, err := s.projectRepo.GetProject(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to get project: %w", err)
+	}
+	if project != nil && project.Archived() {
+		return nil
+	}
+
+	settings, err := s.getSettings(ctx, projectID)
+	if err != nil {
+		return err
+	}
+
+	codebases, err := s.codebaseRepo.GetCodebasesByProject(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to get project codebases: %w", err)
+	}
+
+	for _, codebase := range codebases {
+		// Inactive codebases aren't watched and blocked ones can't be ingested; a change made meanwhile is picked
+		// up once they are active again
+		if codebase.Status == models.CodebaseStatusInactive || codebase.Status == models.CodebaseStatusIngestionBlocked {
+			continue
+		}
+
+		var attached []*repository.AgentRecord
+		for _, agent := range agents {
+			if sameRepository(agent.RepositoryURL, codebase.URL) {
+				attached = append(attached, agent)
+			}
+		}
+		if len(attached) == 0 {
+			continue
+		}
+
+		if err := s.checkCodebase(ctx, codebase, attached, settings.Enabled); err != nil {
+			slog.WarnContext(ctx, "failed to check codebase for agent resync", "codebase_id", codebase.CodebaseID, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// checkCodebase records the head of the codebase's default branch and resyncs the attached agents once the head
+// has been stable for the debounce period. Changes seen while the project is opted out are dropped.
+func (s *DefaultAgentResyncService) checkCodebase(ctx context.Context, codebase *models.Codebase, agents []*repository.AgentRecord, enabled bool) error {
+	revision, err := s.browseService.GetDefaultBranchHead(ctx, codebase.CodebaseID)
+	if err != nil {
+		return err
+	}
+
+	now := s.now().UTC()
+	head, err := s.headRepo.GetHead(ctx, codebase.CodebaseID)
+	if err != nil {
+		return err
+	}
+
+	// The first head seen is the baseline the agents were built from
+	if head == nil {
+		head = &repository.CodebaseHead{CodebaseID: codebase.CodebaseID, SyncedSHA: revision.CommitSHA}
+	}
+	if head.CommitSHA != revision.CommitSHA {
+		head.CommitSHA = revision.CommitSHA
+		head.ChangedAt = now
+	}
+	head.CheckedAt = now
+
+	if head.Pending() {
+		switch {
+		case !enabled:
+			head.SyncedSHA = head.CommitSHA
+		case now.Sub(head.ChangedAt) >= s.debounce:
+			s.resyncAgents(ctx, codebase, revision, agents)
+			head.SyncedSHA = head.CommitSHA
+		}
+	}
+
+	return s.headRepo.SaveHead(ctx, head)
+}
+
+// resyncAgents rebuilds the agents tracking the codebase's default branch from a fresh clone. A failed rebuild
+// notifies the agent provisioning channels and isn't retried until the branch moves again.
+func (s *DefaultAgentResyncService) resyncAgents(ctx context.Context, codebase *models.Codebase, revision *models.CodebaseRevision, agents []*repository.AgentRecord) {
+	for _, agent := range agents {
+		// Agents pinned to another branch don't follow the default branch
+		if agent.Branch != "" && agent.Branch != revision.Branch {
+			continue
+		}
+
+		slog.InfoContext(ctx, "Resyncing agent after codebase change", "agent_id", agent.AgentID,
+			"codebase_id", codebase.CodebaseID, "commit_sha", revision.CommitSHA)
+		if _, err := s.agentService.RebuildAgent(ctx, agent.AgentID); err != nil {
+			slog.ErrorContext(ctx, "failed to resync agent", "agent_id", agent.AgentID, "codebase_id", codebase.CodebaseID, "error", err)
+		}
+	}
+}
+
+// getSettings returns the stored settings of a project, defaulting to resyncs being enabled
+func (s *DefaultAgentResyncService) getSettings(ctx context.Context, projectID string) (*models.AgentResyncSettings, error) {
+	settings, err := s.settingsRepo.GetSettings(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent resync settings: %w", err)
+	}
+	if settings == nil {
+		return &models.AgentResyncSettings{ProjectID: projectID, Enabled: true, IsDefault: true}, nil
+	}
+
+	return settings, nil
+}
+
+// ensureProjectExists returns a not found error unless the project exists
+func (s *DefaultAgentResyncService) ensureProjectExists(ctx context.Context, projectID string) error {
+	exists, err := s.projectRepo.ProjectExists(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to check project existence: %w", err)
+	}
+	if !exists {
+		return apperrors.NotFound(apperrors.CodeProjectNotFound, "project not found")
+	}
+	return nil
+}
+
+// sameRepository reports whether two repository URLs point to the same repository, ignoring a .git suffix and the
+// case of the host and path
+func sameRepository(a, b string) bool {
+	normalize := func(rawURL string) string {
+		baseURL, path := parseRepositoryURL(rawURL)
+		if path == "" {
+			return strings.TrimSuffix(strings.TrimSuffix(rawURL, "/"), ".git")
+		}
+		return strings.ToLower(baseURL + "/" + path)
+	}
+
+	return normalize(a) == normalize(b)
+}
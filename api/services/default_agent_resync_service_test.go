@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+	serviceMocks "github.com/kazemisoroush/code-refactoring-tool/api/services/mocks"
+)
+
+type agentResyncServiceMocks struct {
+	settingsRepo  *repositoryMocks.MockAgentResyncSettingsRepository
+	headRepo      *repositoryMocks.MockCodebaseHeadRepository
+	projectRepo   *repositoryMocks.MockProjectRepository
+	codebaseRepo  *repositoryMocks.MockCodebaseRepository
+	agentRepo     *repositoryMocks.MockAgentRepository
+	browseService *serviceMocks.MockCodebaseBrowseService
+	agentService  *serviceMocks.MockAgentService
+}
+
+var agentResyncNow = time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+func newTestAgentResyncService(t *testing.T) (*DefaultAgentResyncService, agentResyncServiceMocks) {
+	ctrl := gomock.NewController(t)
+	m := agentResyncServiceMocks{
+		settingsRepo:  repositoryMocks.NewMockAgentResyncSettingsRepository(ctrl),
+		headRepo:      repositoryMocks.NewMockCodebaseHeadRepository(ctrl),
+		projectRepo:   repositoryMocks.NewMockProjectRepository(ctrl),
+		codebaseRepo:  repositoryMocks.NewMockCodebaseRepository(ctrl),
+		agentRepo:     repositoryMocks.NewMockAgentRepository(ctrl),
+		browseService: serviceMocks.NewMockCodebaseBrowseService(ctrl),
+		agentService:  serviceMocks.NewMockAgentService(ctrl),
+	}
+	service := NewDefaultAgentResyncService(m.settingsRepo, m.headRepo, m.projectRepo, m.codebaseRepo, m.agentRepo,
+		m.browseService, m.agentService, 10*time.Minute)
+	service.now = func() time.Time { return agentResyncNow }
+	return service, m
+}
+
+// expectWatchedCodebase sets up one project with a codebase and its agents, the default branch at commitSHA
+func expectWatchedCodebase(m agentResyncServiceMocks, enabled bool, commitSHA string, agents ...*repository.AgentRecord) {
+	m.agentRepo.EXPECT().ListAgents(gomock.Any()).Return(agents, nil)
+	m.projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{ProjectID: "proj-1", Status: string(models.ProjectStatusActive)}, nil)
+	if enabled {
+		m.settingsRepo.EXPECT().GetSettings(gomock.Any(), "proj-1").Return(nil, nil)
+	} else {
+		m.settingsRepo.EXPECT().GetSettings(gomock.Any(), "proj-1").Return(&models.AgentResyncSettings{ProjectID: "proj-1"}, nil)
+	}
+	m.codebaseRepo.EXPECT().GetCodebasesByProject(gomock.Any(), "proj-1").Return([]*models.Codebase{
+		{CodebaseID: "cb-1", ProjectID: "proj-1", URL: "https://github.com/acme/payments", Status: models.CodebaseStatusActive},
+		{CodebaseID: "cb-2", ProjectID: "proj-1", URL: "https://github.com/acme/ledger", Status: models.CodebaseStatusActive},
+	}, nil)
+	m.browseService.EXPECT().GetDefaultBranchHead(gomock.Any(), "cb-1").Return(&models.CodebaseRevision{Branch: "main", CommitSHA: commitSHA}, nil)
+}
+
+func TestDefaultAgentResyncService_CheckCodebases_RecordsBaseline(t *testing.T) {
+	service, m := newTestAgentResyncService(t)
+
+	expectWatchedCodebase(m, true, "abc123", &repository.AgentRecord{AgentID: "agent-1", ProjectID: "proj-1", RepositoryURL: "https://github.com/acme/payments.git"})
+	m.headRepo.EXPECT().GetHead(gomock.Any(), "cb-1").Return(nil, nil)
+	m.headRepo.EXPECT().SaveHead(gomock.Any(), &repository.CodebaseHead{
+		CodebaseID: "cb-1", CommitSHA: "abc123", ChangedAt: agentResyncNow, SyncedSHA: "abc123", CheckedAt: agentResyncNow,
+	}).Return(nil)
+
+	require.NoError(t, service.CheckCodebases(context.Background()))
+}
+
+func TestDefaultAgentResyncService_CheckCodebases_DebouncesNewCommits(t *testing.T) {
+	service, m := newTestAgentResyncService(t)
+
+	expectWatchedCodebase(m, true, "def456", &repository.AgentRecord{AgentID: "agent-1", ProjectID: "proj-1", RepositoryURL: "https://github.com/acme/payments"})
+	m.headRepo.EXPECT().GetHead(gomock.Any(), "cb-1").Return(&repository.CodebaseHead{
+		CodebaseID: "cb-1", CommitSHA: "abc123", ChangedAt: agentResyncNow.Add(-time.Hour), SyncedSHA: "abc123",
+	}, nil)
+	// The branch just moved, so the resync waits for it to settle
+	m.headRepo.EXPECT().SaveHead(gomock.Any(), &repository.CodebaseHead{
+		CodebaseID: "cb-1", CommitSHA: "def456", ChangedAt: agentResyncNow, SyncedSHA: "abc123", CheckedAt: agentResyncNow,
+	}).Return(nil)
+
+	require.NoError(t, service.CheckCodebases(context.Background()))
+}
+
+func TestDefaultAgentResyncService_CheckCodebases_ResyncsSettledChange(t *testing.T) {
+	service, m := newTestAgentResyncService(t)
+	changedAt := agentResyncNow.Add(-15 * time.Minute)
+
+	expectWatchedCodebase(m, true, "def456",
+		&repository.AgentRecord{AgentID: "agent-1", ProjectID: "proj-1", RepositoryURL: "https://github.com/acme/payments"},
+		&repository.AgentRecord{AgentID: "agent-2", ProjectID: "proj-1", RepositoryURL: "https://GitHub.com/acme/payments", Branch: "main"},
+		&repository.AgentRecord{AgentID: "agent-3", ProjectID: "proj-1", RepositoryURL: "https://github.com/acme/payments", Branch: "release"},
+		&repository.AgentRecord{AgentID: "agent-4", RepositoryURL: "https://github.com/acme/payments"},
+	)
+	m.headRepo.EXPECT().GetHead(gomock.Any(), "cb-1").Return(&repository.CodebaseHead{
+		CodebaseID: "cb-1", CommitSHA: "def456", ChangedAt: changedAt, SyncedSHA: "abc123",
+	}, nil)
+	m.agentService.EXPECT().RebuildAgent(gomock.Any(), "agent-1").Return(&models.UpdateAgentResponse{}, nil)
+	m.agentService.EXPECT().RebuildAgent(gomock.Any(), "agent-2").Return(&models.UpdateAgentResponse{}, nil)
+	m.headRepo.EXPECT().SaveHead(gomock.Any(), &repository.CodebaseHead{
+		CodebaseID: "cb-1", CommitSHA: "def456", ChangedAt: changedAt, SyncedSHA: "def456", CheckedAt: agentResyncNow,
+	}).Return(nil)
+
+	require.NoError(t, service.CheckCodebases(context.Background()))
+}
+
+func TestDefaultAgentResyncService_CheckCodebases_OptedOut(t *testing.T) {
+	service, m := newTestAgentResyncService(t)
+	changedAt := agentResyncNow.Add(-15 * time.Minute)
+
+	expectWatchedCodebase(m, false, "def456", &repository.AgentRecord{AgentID: "agent-1", ProjectID: "proj-1", RepositoryURL: "https://github.com/acme/payments"})
+	m.headRepo.EXPECT().GetHead(gomock.Any(), "cb-1").Return(&repository.CodebaseHead{
+		CodebaseID: "cb-1", CommitSHA: "def456", ChangedAt: changedAt, SyncedSHA: "abc123",
+	}, nil)
+	// The change is dropped without rebuilding the agent
+	m.headRepo.EXPECT().SaveHead(gomock.Any(), &repository.CodebaseHead{
+		CodebaseID: "cb-1", CommitSHA: "def456", ChangedAt: changedAt, SyncedSHA: "def456", CheckedAt: agentResyncNow,
+	}).Return(nil)
+
+	require.NoError(t, service.CheckCodebases(context.Background()))
+}
+
+func TestDefaultAgentResyncService_CheckCodebases_SkipsArchivedProjects(t *testing.T) {
+	service, m := newTestAgentResyncService(t)
+
+	m.agentRepo.EXPECT().ListAgents(gomock.Any()).Return([]*repository.AgentRecord{
+		{AgentID: "agent-1", ProjectID: "proj-1", RepositoryURL: "https://github.com/acme/payments"},
+	}, nil)
+	// Neither the codebases nor their heads are looked at
+	m.projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{ProjectID: "proj-1", Status: string(models.ProjectStatusArchived)}, nil)
+
+	require.NoError(t, service.CheckCodebases(context.Background()))
+}
+
+func TestDefaultAgentResyncService_UpdateSettings(t *testing.T) {
+	service, m := newTestAgentResyncService(t)
+	enabled := false
+
+	m.projectRepo.EXPECT().ProjectExists(gomock.Any(), "proj-1").Return(true, nil)
+	m.settingsRepo.EXPECT().SaveSettings(gomock.Any(), gomock.Any()).Return(nil)
+
+	settings, err := service.UpdateSettings(context.Background(), models.UpdateAgentResyncSettingsRequest{ProjectID: "proj-1", Enabled: &enabled})
+
+	require.NoError(t, err)
+	assert.False(t, settings.Enabled)
+	assert.False(t, settings.IsDefault)
+	require.NotNil(t, settings.UpdatedAt)
+	assert.Equal(t, agentResyncNow, *settings.UpdatedAt)
+}
+
+func TestDefaultAgentResyncService_GetSettings(t *testing.T) {
+	service, m := newTestAgentResyncService(t)
+
+	m.projectRepo.EXPECT().ProjectExists(gomock.Any(), "proj-1").Return(true, nil)
+	m.settingsRepo.EXPECT().GetSettings(gomock.Any(), "proj-1").Return(nil, nil)
+	m.projectRepo.EXPECT().ProjectExists(gomock.Any(), "proj-missing").Return(false, nil)
+
+	settings, err := service.GetSettings(context.Background(), models.GetAgentResyncSettingsRequest{ProjectID: "proj-1"})
+	require.NoError(t, err)
+	assert.True(t, settings.Enabled)
+	assert.True(t, settings.IsDefault)
+
+	_, err = service.GetSettings(context.Background(), models.GetAgentResyncSettingsRequest{ProjectID: "proj-missing"})
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
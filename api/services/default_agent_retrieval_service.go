@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/rag"
+)
+
+// defaultRetrievedChunksLimit is the number of chunks retrieved when no limit is requested
+const defaultRetrievedChunksLimit = 10
+
+// DefaultAgentRetrievalService is the default implementation of AgentRetrievalService
+type DefaultAgentRetrievalService struct {
+	agentRepository repository.AgentRepository
+	roleService     RoleService
+	retrievers      func(region string) rag.Retriever
+}
+
+// NewDefaultAgentRetrievalService creates a new DefaultAgentRetrievalService, retrieving from the knowledge bases of
+// agents with the retriever of their region. Callers must be allowed to read the agents of the agent's project.
+func NewDefaultAgentRetrievalService(agentRepo repository.AgentRepository, roleService RoleService, retrievers func(region string) rag.Retriever) *DefaultAgentRetrievalService {
+	return &DefaultAgentRetrievalService{
+		agentRepository: agentRepo,
+		roleService:     roleService,
+		retrievers:      retrievers,
+	}
+}
+
+// RetrieveChunks runs only the retrieval step of an agent for a query. Local agents aren't given retrieved context,
+// so they have nothing to retrieve.
+func (s *DefaultAgentRetrievalService) RetrieveChunks(ctx context.Context, request models.RetrieveAgentChunksRequest) (*models.RetrieveAgentChunksResponse, error) {
+	agent, err := s.agentRepository.GetAgent(ctx, request.AgentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent: %w", err)
+	}
+
+	// The retrieved chunks reveal the repository, so they are restricted to the members of the agent's project
+	if err := s.roleService.Authorize(ctx, request.UserID, agent.ProjectID, models.PermissionAgentRead); err != nil {
+		return nil, err
+	}
+
+	if agent.GetAIProvider() == models.AIProviderLocal || agent.KnowledgeBaseID == "" {
+		return nil, apperrors.Validation(apperrors.CodeRetrievalUnsupported, "agent %s has no knowledge base to retrieve from", agent.AgentID)
+	}
+
+	limit := request.Limit
+	if limit == 0 {
+		limit = defaultRetrievedChunksLimit
+	}
+
+	chunks, err := s.retrievers(agent.Region).Retrieve(ctx, agent.KnowledgeBaseID, request.Query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve chunks: %w", err)
+	}
+
+	response := &models.RetrieveAgentChunksResponse{
+		AgentID:         agent.AgentID,
+		KnowledgeBaseID: agent.KnowledgeBaseID,
+		Query:           request.Query,
+		Chunks:          make([]models.RetrievedChunk, 0, len(chunks)),
+	}
+	for i, chunk := range chunks {
+		response.Chunks = append(response.Chunks, models.RetrievedChunk{
+			Rank:       i + 1,
+			Score:      chunk.Score,
+			SourcePath: chunk.Source,
+			StartLine:  chunk.StartLine,
+			EndLine:    chunk.EndLine,
+			Text:       chunk.Text,
+		})
+	}
+
+	return response, nil
+}
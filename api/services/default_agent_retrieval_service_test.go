@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+	servicesMocks "github.com/kazemisoroush/code-refactoring-tool/api/services/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/rag"
+	ragMocks "github.com/kazemisoroush/code-refactoring-tool/pkg/ai/rag/mocks"
+)
+
+type agentRetrievalServiceMocks struct {
+	agentRepo   *repositoryMocks.MockAgentRepository
+	roleService *servicesMocks.MockRoleService
+	retriever   *ragMocks.MockRetriever
+	regions     *[]string
+}
+
+func newTestAgentRetrievalService(t *testing.T) (*DefaultAgentRetrievalService, agentRetrievalServiceMocks) {
+	ctrl := gomock.NewController(t)
+	m := agentRetrievalServiceMocks{
+		agentRepo:   repositoryMocks.NewMockAgentRepository(ctrl),
+		roleService: servicesMocks.NewMockRoleService(ctrl),
+		retriever:   ragMocks.NewMockRetriever(ctrl),
+		regions:     &[]string{},
+	}
+	retrievers := func(region string) rag.Retriever {
+		*m.regions = append(*m.regions, region)
+		return m.retriever
+	}
+	return NewDefaultAgentRetrievalService(m.agentRepo, m.roleService, retrievers), m
+}
+
+func TestDefaultAgentRetrievalService_RetrieveChunks(t *testing.T) {
+	service, m := newTestAgentRetrievalService(t)
+
+	m.agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{
+		AgentID:         "agent-1",
+		ProjectID:       "proj-1",
+		KnowledgeBaseID: "kb-1",
+		AIProvider:      string(models.AIProviderBedrock),
+		Region:          "eu-west-1",
+	}, nil)
+	m.roleService.EXPECT().Authorize(gomock.Any(), "auth-1", "proj-1", models.PermissionAgentRead).Return(nil)
+	m.retriever.EXPECT().Retrieve(gomock.Any(), "kb-1", "How are retries scheduled?", 10).Return([]rag.Chunk{
+		{Text: "func scheduleRetry() {}", Source: "internal/payments/retry.go", StartLine: 42, EndLine: 42, Score: 0.82},
+		{Text: "retries: 3", Source: "config.yaml", Score: 0.41},
+	}, nil)
+
+	response, err := service.RetrieveChunks(context.Background(), models.RetrieveAgentChunksRequest{
+		AgentID: "agent-1",
+		Query:   "How are retries scheduled?",
+		UserID:  "auth-1",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"eu-west-1"}, *m.regions, "chunks are retrieved in the agent's region")
+	assert.Equal(t, "kb-1", response.KnowledgeBaseID)
+	assert.Equal(t, []models.RetrievedChunk{
+		{Rank: 1, Score: 0.82, SourcePath: "internal/payments/retry.go", StartLine: 42, EndLine: 42, Text: "func scheduleRetry() {}"},
+		{Rank: 2, Score: 0.41, SourcePath: "config.yaml", Text: "retries: 3"},
+	}, response.Chunks)
+}
+
+func TestDefaultAgentRetrievalService_RetrieveChunks_NotProjectMember(t *testing.T) {
+	service, m := newTestAgentRetrievalService(t)
+
+	m.agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{
+		AgentID:         "agent-1",
+		ProjectID:       "proj-1",
+		KnowledgeBaseID: "kb-1",
+		AIProvider:      string(models.AIProviderBedrock),
+	}, nil)
+	m.roleService.EXPECT().
+		Authorize(gomock.Any(), "auth-2", "proj-1", models.PermissionAgentRead).
+		Return(apperrors.Forbidden(apperrors.CodePermissionDenied, "role viewer doesn't grant permission agent:read"))
+
+	_, err := service.RetrieveChunks(context.Background(), models.RetrieveAgentChunksRequest{AgentID: "agent-1", Query: "retries", UserID: "auth-2"})
+
+	assert.ErrorIs(t, err, apperrors.ErrForbidden)
+}
+
+func TestDefaultAgentRetrievalService_RetrieveChunks_LocalAgent(t *testing.T) {
+	service, m := newTestAgentRetrievalService(t)
+
+	m.agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{
+		AgentID:    "agent-1",
+		AIProvider: string(models.AIProviderLocal),
+	}, nil)
+	m.roleService.EXPECT().Authorize(gomock.Any(), "auth-1", "", models.PermissionAgentRead).Return(nil)
+
+	_, err := service.RetrieveChunks(context.Background(), models.RetrieveAgentChunksRequest{AgentID: "agent-1", Query: "retries", UserID: "auth-1", Limit: 5})
+
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	assert.Equal(t, apperrors.CodeRetrievalUnsupported, apperrors.CodeOf(err))
+}
@@ -3,36 +3,59 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"time"
 
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
 	"github.com/kazemisoroush/code-refactoring-tool/api/models"
 	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
 	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
 	"github.com/kazemisoroush/code-refactoring-tool/pkg/factory"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/scan"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/workflow"
 )
 
 // DefaultAgentService is the default implementation of AgentService
 type DefaultAgentService struct {
 	agentRepository       repository.AgentRepository
 	infrastructureFactory factory.AIInfrastructureFactory
+	notifier              Notifier
+	redactionService      RedactionService
+	workflowEngine        *workflow.Engine
+	storageLifecycle      StorageLifecycleService
+	projects              repository.ProjectRepository
 }
 
-// NewDefaultAgentService creates a new instance of DefaultAgentService
+// NewDefaultAgentService creates a new instance of DefaultAgentService. The workflow engine is the one the
+// infrastructure factory runs setups on, and is read to report and resume them. Provisioning failures aren't notified
+// when the notifier is nil. The storage lifecycle service purges the content of deleted agents, which is kept when
+// it's nil. The projects give the Bedrock agents created in them
+// their embedding model; agents embed with the default model when it's nil.
 func NewDefaultAgentService(
 	agentRepo repository.AgentRepository,
 	infraFactory factory.AIInfrastructureFactory,
+	notifier Notifier,
+	redactionService RedactionService,
+	workflowEngine *workflow.Engine,
+	storageLifecycle StorageLifecycleService,
+	projects repository.ProjectRepository,
 ) AgentService {
 	return &DefaultAgentService{
 		agentRepository:       agentRepo,
 		infrastructureFactory: infraFactory,
+		notifier:              notifier,
+		redactionService:      redactionService,
+		workflowEngine:        workflowEngine,
+		storageLifecycle:      storageLifecycle,
+		projects:              projects,
 	}
 }
 
 // CreateAgent creates a new agent with the given parameters
 func (s *DefaultAgentService) CreateAgent(ctx context.Context, request models.CreateAgentRequest) (*models.CreateAgentResponse, error) {
-	slog.Info("Creating agent", "repository_url", request.RepositoryURL, "branch", request.Branch)
+	slog.InfoContext(ctx, "Creating agent", "repository_url", request.RepositoryURL, "branch", request.Branch)
 
 	// Use provided AI provider or default to local
 	aiProvider := request.AIProvider
@@ -40,18 +63,69 @@ func (s *DefaultAgentService) CreateAgent(ctx context.Context, request models.Cr
 		aiProvider = models.AIProviderLocal
 	}
 
-	if err := s.infrastructureFactory.ValidateAgentConfig(aiProvider); err != nil {
-		return nil, fmt.Errorf("invalid AI provider: %w", err)
+	embedding, err := s.agentEmbedding(ctx, aiProvider, request)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.infrastructureFactory.ValidateAgentConfig(aiProvider, request.Region, embedding); err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrValidation, apperrors.CodeInvalidProvider, err, "invalid AI provider configuration")
+	}
+	request.AIProvider = aiProvider
+
+	// The setup stores the embedding inherited from the project, so resuming it embeds with the same model
+	request.EmbeddingModel = embedding.Model
+	request.EmbeddingDimensions = embedding.Dimensions
+
+	setup := workflow.Setup{
+		RunID: newSetupID(),
+		Input: map[string]any{
+			setupInputOperation: string(models.AgentSetupOperationCreate),
+			setupInputProjectID: request.ProjectID,
+			setupInputRequest:   request,
+		},
+		RepositoryURL: request.RepositoryURL,
+	}
+	return s.createAgent(ctx, setup, request)
+}
+
+// agentEmbedding returns the embedding of a new agent's knowledge base: the requested one, or else the embedding of
+// the project owning a Bedrock agent
+func (s *DefaultAgentService) agentEmbedding(ctx context.Context, provider models.AIProvider, request models.CreateAgentRequest) (config.Embedding, error) {
+	embedding := config.Embedding{Model: request.EmbeddingModel, Dimensions: request.EmbeddingDimensions}
+	if !embedding.IsZero() || provider != models.AIProviderBedrock || request.ProjectID == "" || s.projects == nil {
+		return embedding, nil
+	}
+
+	project, err := s.projects.GetProject(ctx, request.ProjectID)
+	if err != nil {
+		return config.Embedding{}, fmt.Errorf("failed to get project: %w", err)
+	}
+	if project == nil {
+		// Resolving the project's redaction policy rejects the missing project
+		return embedding, nil
+	}
+	return project.Embedding(), nil
+}
+
+// createAgent provisions the infrastructure of a new agent as the given setup and saves the agent
+func (s *DefaultAgentService) createAgent(ctx context.Context, setup workflow.Setup, request models.CreateAgentRequest) (*models.CreateAgentResponse, error) {
+	policy, err := s.redactionService.ResolvePolicy(ctx, request.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve redaction policy: %w", err)
 	}
 
 	// Create AI infrastructure
-	infraResult, err := s.infrastructureFactory.CreateAgentInfrastructure(ctx, aiProvider)
+	embedding := config.Embedding{Model: request.EmbeddingModel, Dimensions: request.EmbeddingDimensions}
+	infraResult, err := s.infrastructureFactory.CreateAgentInfrastructure(ctx, setup, request.AIProvider, request.Region, embedding, policy)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create AI infrastructure: %w", err)
+		s.notifyProvisioningFailed(ctx, request.AgentName, "create", err)
+		return nil, infrastructureError("create", err)
 	}
+	s.recordRedactions(ctx, request.ProjectID, infraResult.AgentID, models.RedactionOperationCreate, infraResult)
 
 	// Create agent record to store in database
 	agentRecord := repository.NewAgentRecord(request, infraResult.AgentID, infraResult.AgentVersion, infraResult.KnowledgeBaseID, infraResult.VectorStoreID)
+	agentRecord.SetEmbedding(infraResult.Embedding)
 	agentRecord.Status = string(infraResult.Status)
 	agentRecord.CreatedAt = time.Now()
 	agentRecord.UpdatedAt = time.Now()
@@ -70,18 +144,19 @@ func (s *DefaultAgentService) CreateAgent(ctx context.Context, request models.Cr
 	err = s.agentRepository.CreateAgent(ctx, agentRecord)
 	if err != nil {
 		// If saving fails, try to clean up the infrastructure
-		cleanupErr := s.infrastructureFactory.DestroyAgentInfrastructure(ctx, infraResult.AgentID)
+		cleanupErr := s.infrastructureFactory.DestroyAgentInfrastructure(ctx, infraResult.AgentID, request.Region)
 		if cleanupErr != nil {
-			slog.Error("Failed to cleanup infrastructure after database save failure",
+			slog.ErrorContext(ctx, "Failed to cleanup infrastructure after database save failure",
 				"agent_id", infraResult.AgentID, "cleanup_error", cleanupErr)
 		}
+		s.purgeContent(ctx, agentRecord)
 		return nil, fmt.Errorf("failed to save agent to database: %w", err)
 	}
 
 	// Update status to ready after successful creation
 	err = s.agentRepository.UpdateAgentStatus(ctx, infraResult.AgentID, models.AgentStatusReady)
 	if err != nil {
-		slog.Error("Failed to update agent status to ready", "agent_id", infraResult.AgentID, "error", err)
+		slog.ErrorContext(ctx, "Failed to update agent status to ready", "agent_id", infraResult.AgentID, "error", err)
 		// Don't fail the creation for this, just log it
 	}
 
@@ -94,7 +169,7 @@ func (s *DefaultAgentService) CreateAgent(ctx context.Context, request models.Cr
 		CreatedAt:       agentRecord.CreatedAt,
 	}
 
-	slog.Info("Agent created successfully", "agent_id", infraResult.AgentID)
+	slog.InfoContext(ctx, "Agent created successfully", "agent_id", infraResult.AgentID)
 	return response, nil
 }
 
@@ -106,16 +181,20 @@ func (s *DefaultAgentService) GetAgent(ctx context.Context, agentID string) (*mo
 	}
 
 	response := &models.GetAgentResponse{
-		AgentID:         agentRecord.AgentID,
-		AgentVersion:    agentRecord.AgentVersion,
-		KnowledgeBaseID: agentRecord.KnowledgeBaseID,
-		VectorStoreID:   agentRecord.VectorStoreID,
-		RepositoryURL:   agentRecord.RepositoryURL,
-		Branch:          agentRecord.Branch,
-		AgentName:       agentRecord.AgentName,
-		Status:          agentRecord.Status,
-		CreatedAt:       agentRecord.CreatedAt,
-		UpdatedAt:       agentRecord.UpdatedAt,
+		AgentID:             agentRecord.AgentID,
+		AgentVersion:        agentRecord.AgentVersion,
+		KnowledgeBaseID:     agentRecord.KnowledgeBaseID,
+		VectorStoreID:       agentRecord.VectorStoreID,
+		RepositoryURL:       agentRecord.RepositoryURL,
+		Branch:              agentRecord.Branch,
+		AgentName:           agentRecord.AgentName,
+		Status:              agentRecord.Status,
+		CreatedAt:           agentRecord.CreatedAt,
+		UpdatedAt:           agentRecord.UpdatedAt,
+		ProjectID:           agentRecord.ProjectID,
+		Region:              agentRecord.Region,
+		EmbeddingModel:      agentRecord.EmbeddingModel,
+		EmbeddingDimensions: agentRecord.EmbeddingDimensions,
 	}
 
 	return response, nil
@@ -123,7 +202,7 @@ func (s *DefaultAgentService) GetAgent(ctx context.Context, agentID string) (*mo
 
 // UpdateAgent updates an existing agent
 func (s *DefaultAgentService) UpdateAgent(ctx context.Context, request models.UpdateAgentRequest) (*models.UpdateAgentResponse, error) {
-	slog.Info("Updating agent", "agent_id", request.AgentID)
+	slog.InfoContext(ctx, "Updating agent", "agent_id", request.AgentID)
 
 	// Get existing agent to ensure it exists
 	existingAgent, err := s.agentRepository.GetAgent(ctx, request.AgentID)
@@ -137,7 +216,7 @@ func (s *DefaultAgentService) UpdateAgent(ctx context.Context, request models.Up
 	// If infrastructure changes are required, update the AI infrastructure
 	var infrastructureResult *factory.AIInfrastructureResult
 	if infrastructureChangeRequired {
-		slog.Info("Infrastructure changes detected, updating AI infrastructure", "agent_id", request.AgentID)
+		slog.InfoContext(ctx, "Infrastructure changes detected, updating AI infrastructure", "agent_id", request.AgentID)
 
 		// Use provided AI provider or keep existing agent's provider
 		var aiProvider models.AIProvider
@@ -147,22 +226,45 @@ func (s *DefaultAgentService) UpdateAgent(ctx context.Context, request models.Up
 			aiProvider = models.AIProvider(existingAgent.AIProvider)
 		}
 
-		infrastructureResult, err = s.infrastructureFactory.UpdateAgentInfrastructure(ctx, existingAgent.KnowledgeBaseID, aiProvider)
+		// Only Bedrock agents run in an AWS region and choose their embedding, so an agent switching provider leaves
+		// them behind
+		region := existingAgent.Region
+		embedding := existingAgent.Embedding()
+		if aiProvider != models.AIProviderBedrock {
+			region = ""
+			embedding = config.Embedding{}
+		}
+
+		policy, err := s.redactionService.ResolvePolicy(ctx, existingAgent.ProjectID)
 		if err != nil {
-			return nil, fmt.Errorf("failed to update AI infrastructure: %w", err)
+			return nil, fmt.Errorf("failed to resolve redaction policy: %w", err)
 		}
+
+		setup := rebuildSetup(existingAgent)
+		if request.RepositoryURL != nil {
+			setup.RepositoryURL = *request.RepositoryURL
+		}
+		infrastructureResult, err = s.infrastructureFactory.UpdateAgentInfrastructure(ctx, setup, existingAgent.KnowledgeBaseID, aiProvider, region, embedding, policy)
+		if err != nil {
+			s.notifyProvisioningFailed(ctx, existingAgent.AgentID, "update", err)
+			return nil, infrastructureError("update", err)
+		}
+		s.recordRedactions(ctx, existingAgent.ProjectID, existingAgent.AgentID, models.RedactionOperationUpdate, infrastructureResult)
 	}
 
 	// Create update record with current values as defaults
 	updateRecord := &repository.AgentRecord{
 		AgentID:       existingAgent.AgentID,
+		ProjectID:     existingAgent.ProjectID,
 		AgentName:     existingAgent.AgentName,
 		RepositoryURL: existingAgent.RepositoryURL,
 		Branch:        existingAgent.Branch,
 		Status:        existingAgent.Status,
 		AIProvider:    existingAgent.AIProvider,
 		AIConfigJSON:  existingAgent.AIConfigJSON,
+		Region:        existingAgent.Region,
 	}
+	updateRecord.SetEmbedding(existingAgent.Embedding())
 
 	// Apply updates if provided
 	if request.AgentName != nil {
@@ -176,6 +278,9 @@ func (s *DefaultAgentService) UpdateAgent(ctx context.Context, request models.Up
 	}
 	if request.AIProvider != nil {
 		updateRecord.AIProvider = string(*request.AIProvider)
+		if *request.AIProvider != models.AIProviderBedrock {
+			updateRecord.Region = ""
+		}
 	}
 
 	// If infrastructure was recreated, update the infrastructure IDs
@@ -184,6 +289,7 @@ func (s *DefaultAgentService) UpdateAgent(ctx context.Context, request models.Up
 		updateRecord.VectorStoreID = infrastructureResult.VectorStoreID
 		updateRecord.AgentVersion = infrastructureResult.AgentVersion
 		updateRecord.Status = string(infrastructureResult.Status)
+		updateRecord.SetEmbedding(infrastructureResult.Embedding)
 	}
 
 	// Update the agent in the repository
@@ -191,7 +297,7 @@ func (s *DefaultAgentService) UpdateAgent(ctx context.Context, request models.Up
 	if err != nil {
 		// If database update fails but we updated infrastructure, we should try to revert
 		if infrastructureResult != nil {
-			slog.Warn("Database update failed after updating infrastructure, attempting to revert",
+			slog.WarnContext(ctx, "Database update failed after updating infrastructure, attempting to revert",
 				"agent_id", request.AgentID, "new_knowledge_base_id", infrastructureResult.KnowledgeBaseID)
 			// Note: Reverting infrastructure updates is complex and may not always be possible
 			// In a production system, you might want to implement a compensation transaction
@@ -221,18 +327,92 @@ func (s *DefaultAgentService) UpdateAgent(ctx context.Context, request models.Up
 	return response, nil
 }
 
+// RebuildAgent re-provisions an agent's AI infrastructure without changing its configuration
+func (s *DefaultAgentService) RebuildAgent(ctx context.Context, agentID string) (*models.UpdateAgentResponse, error) {
+	slog.InfoContext(ctx, "Rebuilding agent", "agent_id", agentID)
+
+	existingAgent, err := s.agentRepository.GetAgent(ctx, agentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get existing agent: %w", err)
+	}
+
+	policy, err := s.redactionService.ResolvePolicy(ctx, existingAgent.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve redaction policy: %w", err)
+	}
+
+	setup := rebuildSetup(existingAgent)
+	infrastructureResult, err := s.infrastructureFactory.UpdateAgentInfrastructure(ctx, setup, existingAgent.KnowledgeBaseID, existingAgent.GetAIProvider(), existingAgent.Region, existingAgent.Embedding(), policy)
+	if err != nil {
+		s.notifyProvisioningFailed(ctx, existingAgent.AgentID, "rebuild", err)
+		return nil, infrastructureError("rebuild", err)
+	}
+
+	return s.saveRebuiltAgent(ctx, existingAgent, infrastructureResult)
+}
+
+// ReembedAgent rebuilds an agent's knowledge base to embed its repository with another model and dimensions
+func (s *DefaultAgentService) ReembedAgent(ctx context.Context, agentID string, embedding config.Embedding) (*models.UpdateAgentResponse, error) {
+	slog.InfoContext(ctx, "Re-embedding agent", "agent_id", agentID, "embedding_model", embedding.Model, "embedding_dimensions", embedding.Dimensions)
+
+	existingAgent, err := s.agentRepository.GetAgent(ctx, agentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get existing agent: %w", err)
+	}
+	if err := s.infrastructureFactory.ValidateAgentConfig(existingAgent.GetAIProvider(), existingAgent.Region, embedding); err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrValidation, apperrors.CodeInvalidEmbedding, err, "invalid embedding")
+	}
+
+	// The embedding is saved before the rebuild, so resuming a rebuild that failed embeds with it
+	existingAgent.SetEmbedding(embedding)
+	if err := s.agentRepository.UpdateAgent(ctx, existingAgent); err != nil {
+		return nil, fmt.Errorf("failed to update agent: %w", err)
+	}
+
+	return s.RebuildAgent(ctx, agentID)
+}
+
+// saveRebuiltAgent points an agent at the infrastructure a rebuild provisioned for it
+func (s *DefaultAgentService) saveRebuiltAgent(ctx context.Context, existingAgent *repository.AgentRecord, infrastructureResult *factory.AIInfrastructureResult) (*models.UpdateAgentResponse, error) {
+	s.recordRedactions(ctx, existingAgent.ProjectID, existingAgent.AgentID, models.RedactionOperationRebuild, infrastructureResult)
+
+	updateRecord := *existingAgent
+	updateRecord.KnowledgeBaseID = infrastructureResult.KnowledgeBaseID
+	updateRecord.VectorStoreID = infrastructureResult.VectorStoreID
+	updateRecord.AgentVersion = infrastructureResult.AgentVersion
+	updateRecord.Status = string(infrastructureResult.Status)
+	updateRecord.SetEmbedding(infrastructureResult.Embedding)
+
+	if err := s.agentRepository.UpdateAgent(ctx, &updateRecord); err != nil {
+		return nil, fmt.Errorf("failed to update agent: %w", err)
+	}
+
+	return &models.UpdateAgentResponse{
+		AgentID:         updateRecord.AgentID,
+		AgentVersion:    updateRecord.AgentVersion,
+		KnowledgeBaseID: updateRecord.KnowledgeBaseID,
+		VectorStoreID:   updateRecord.VectorStoreID,
+		RepositoryURL:   updateRecord.RepositoryURL,
+		Branch:          updateRecord.Branch,
+		AgentName:       updateRecord.AgentName,
+		Status:          updateRecord.Status,
+		CreatedAt:       updateRecord.CreatedAt,
+		UpdatedAt:       updateRecord.UpdatedAt,
+	}, nil
+}
+
 // DeleteAgent deletes an agent by ID
 func (s *DefaultAgentService) DeleteAgent(ctx context.Context, agentID string) (*models.DeleteAgentResponse, error) {
 	// Verify agent exists before attempting deletion
-	_, err := s.agentRepository.GetAgent(ctx, agentID)
+	agent, err := s.agentRepository.GetAgent(ctx, agentID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get agent for deletion: %w", err)
 	}
 
 	// Use the infrastructure factory to clean up AI resources
-	err = s.infrastructureFactory.DestroyAgentInfrastructure(ctx, agentID)
+	err = s.infrastructureFactory.DestroyAgentInfrastructure(ctx, agentID, agent.Region)
 	if err != nil {
-		slog.Error("Failed to destroy AI infrastructure", "agent_id", agentID, "error", err)
+		slog.ErrorContext(ctx, "Failed to destroy AI infrastructure", "agent_id", agentID, "error", err)
 		// Don't return error here - we still want to delete from DB
 	}
 
@@ -246,11 +426,29 @@ func (s *DefaultAgentService) DeleteAgent(ctx context.Context, agentID string) (
 		AgentID: agentID,
 		Success: true,
 	}
+	if purge := s.purgeContent(ctx, agent); purge != nil {
+		response.PurgeID = purge.PurgeID
+	}
 
-	slog.Info("Agent deleted successfully", "agent_id", agentID)
+	slog.InfoContext(ctx, "Agent deleted successfully", "agent_id", agentID)
 	return response, nil
 }
 
+// purgeContent schedules the purge of the content an agent uploaded to S3. A purge that fails to be scheduled is only
+// logged, as the reconciliation reports the content it leaves behind.
+func (s *DefaultAgentService) purgeContent(ctx context.Context, agent *repository.AgentRecord) *models.StoragePurge {
+	if s.storageLifecycle == nil {
+		return nil
+	}
+
+	purge, err := s.storageLifecycle.PurgeAgentContent(ctx, agent)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to schedule agent content purge", "agent_id", agent.AgentID, "error", err)
+		return nil
+	}
+	return purge
+}
+
 // RequiresInfrastructureUpdate determines if an agent update requires infrastructure changes
 func RequiresInfrastructureUpdate(request models.UpdateAgentRequest, existingAgent *repository.AgentRecord) bool {
 	// Check if repository URL is being changed
@@ -314,3 +512,42 @@ func (s *DefaultAgentService) ListAgents(ctx context.Context, request models.Lis
 
 	return response, nil
 }
+
+// notifyProvisioningFailed notifies the channels subscribed to agent provisioning failures.
+// Agents don't belong to a project, so only channels covering every project receive it.
+func (s *DefaultAgentService) notifyProvisioningFailed(ctx context.Context, agent, operation string, err error) {
+	if s.notifier == nil {
+		return
+	}
+	if agent == "" {
+		agent = "new agent"
+	}
+
+	s.notifier.Notify(ctx, models.Notification{
+		Event:   models.NotificationEventAgentProvisioningFailed,
+		Subject: fmt.Sprintf("Agent provisioning failed: %s", agent),
+		Message: fmt.Sprintf("Failed to %s the AI infrastructure of %s.\nError: %v", operation, agent, err),
+	})
+}
+
+// recordRedactions audits the content masked while syncing an agent. The sync already succeeded, so a failure to
+// record it is only logged.
+func (s *DefaultAgentService) recordRedactions(ctx context.Context, projectID, agentID string, operation models.RedactionOperation, result *factory.AIInfrastructureResult) {
+	slog.InfoContext(ctx, "Redacted agent repository content", "agent_id", agentID, "operation", operation,
+		"files_redacted", result.Redactions.FilesRedacted, "redactions", result.Redactions.Total(),
+		"noise_files_removed", result.Redactions.Noise.FilesRemoved, "noise_bytes_removed", result.Redactions.Noise.BytesRemoved)
+
+	if err := s.redactionService.RecordAudit(ctx, projectID, agentID, operation, result.Redactions); err != nil {
+		slog.WarnContext(ctx, "Failed to record redaction audit", "agent_id", agentID, "error", err)
+	}
+}
+
+// infrastructureError wraps a provisioning failure. A repository blocked by its ingestion scan is a conflict the
+// caller can resolve, so it isn't reported as an internal error.
+func infrastructureError(action string, err error) error {
+	var blocked *scan.BlockedError
+	if errors.As(err, &blocked) {
+		return apperrors.Wrap(apperrors.ErrConflict, apperrors.CodeIngestionBlocked, err, "failed to %s AI infrastructure", action)
+	}
+	return fmt.Errorf("failed to %s AI infrastructure: %w", action, err)
+}
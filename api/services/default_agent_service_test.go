@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -10,10 +11,17 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
 	"github.com/kazemisoroush/code-refactoring-tool/api/models"
 	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
 	repoMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+	servicesMocks "github.com/kazemisoroush/code-refactoring-tool/api/services/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/factory"
 	factoryMocks "github.com/kazemisoroush/code-refactoring-tool/pkg/factory/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/redact"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/scan"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/workflow"
 )
 
 func TestDefaultAgentService_GetAgent_Success(t *testing.T) {
@@ -23,6 +31,7 @@ func TestDefaultAgentService_GetAgent_Success(t *testing.T) {
 
 	mockAgentRepo := repoMocks.NewMockAgentRepository(ctrl)
 	mockInfraFactory := factoryMocks.NewMockAIInfrastructureFactory(ctrl)
+	mockRedaction := servicesMocks.NewMockRedactionService(ctrl)
 
 	agentID := "test-agent-id"
 	expectedRecord := &repository.AgentRecord{
@@ -40,7 +49,7 @@ func TestDefaultAgentService_GetAgent_Success(t *testing.T) {
 		GetAgent(gomock.Any(), agentID).
 		Return(expectedRecord, nil)
 
-	service := NewDefaultAgentService(mockAgentRepo, mockInfraFactory)
+	service := NewDefaultAgentService(mockAgentRepo, mockInfraFactory, servicesMocks.NewMockNotifier(ctrl), mockRedaction, nil, nil, nil)
 
 	// Act
 	result, err := service.GetAgent(context.Background(), agentID)
@@ -59,6 +68,7 @@ func TestDefaultAgentService_GetAgent_NotFound(t *testing.T) {
 
 	mockAgentRepo := repoMocks.NewMockAgentRepository(ctrl)
 	mockInfraFactory := factoryMocks.NewMockAIInfrastructureFactory(ctrl)
+	mockRedaction := servicesMocks.NewMockRedactionService(ctrl)
 
 	agentID := "non-existent-agent"
 	expectedError := errors.New("agent not found")
@@ -67,7 +77,7 @@ func TestDefaultAgentService_GetAgent_NotFound(t *testing.T) {
 		GetAgent(gomock.Any(), agentID).
 		Return(nil, expectedError)
 
-	service := NewDefaultAgentService(mockAgentRepo, mockInfraFactory)
+	service := NewDefaultAgentService(mockAgentRepo, mockInfraFactory, servicesMocks.NewMockNotifier(ctrl), mockRedaction, nil, nil, nil)
 
 	// Act
 	response, err := service.GetAgent(context.Background(), agentID)
@@ -85,6 +95,7 @@ func TestDefaultAgentService_ListAgents_Success(t *testing.T) {
 
 	mockAgentRepo := repoMocks.NewMockAgentRepository(ctrl)
 	mockInfraFactory := factoryMocks.NewMockAIInfrastructureFactory(ctrl)
+	mockRedaction := servicesMocks.NewMockRedactionService(ctrl)
 
 	agentRecords := []*repository.AgentRecord{
 		{
@@ -112,7 +123,7 @@ func TestDefaultAgentService_ListAgents_Success(t *testing.T) {
 		Return(agentRecords, nil).
 		Times(1)
 
-	service := NewDefaultAgentService(mockAgentRepo, mockInfraFactory)
+	service := NewDefaultAgentService(mockAgentRepo, mockInfraFactory, servicesMocks.NewMockNotifier(ctrl), mockRedaction, nil, nil, nil)
 
 	// Act
 	request := models.ListAgentsRequest{}
@@ -133,6 +144,7 @@ func TestDefaultAgentService_ListAgents_RepositoryError(t *testing.T) {
 
 	mockAgentRepo := repoMocks.NewMockAgentRepository(ctrl)
 	mockInfraFactory := factoryMocks.NewMockAIInfrastructureFactory(ctrl)
+	mockRedaction := servicesMocks.NewMockRedactionService(ctrl)
 
 	repoError := errors.New("DynamoDB error")
 
@@ -141,7 +153,7 @@ func TestDefaultAgentService_ListAgents_RepositoryError(t *testing.T) {
 		Return(nil, repoError).
 		Times(1)
 
-	service := NewDefaultAgentService(mockAgentRepo, mockInfraFactory)
+	service := NewDefaultAgentService(mockAgentRepo, mockInfraFactory, servicesMocks.NewMockNotifier(ctrl), mockRedaction, nil, nil, nil)
 
 	// Act
 	request := models.ListAgentsRequest{}
@@ -152,3 +164,535 @@ func TestDefaultAgentService_ListAgents_RepositoryError(t *testing.T) {
 	assert.Nil(t, response)
 	assert.Contains(t, err.Error(), "failed to list agents")
 }
+
+func TestDefaultAgentService_RebuildAgent_Success(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAgentRepo := repoMocks.NewMockAgentRepository(ctrl)
+	mockInfraFactory := factoryMocks.NewMockAIInfrastructureFactory(ctrl)
+	mockRedaction := servicesMocks.NewMockRedactionService(ctrl)
+
+	existing := &repository.AgentRecord{
+		AgentID:         "agent-1",
+		ProjectID:       "proj-1",
+		KnowledgeBaseID: "kb-old",
+		RepositoryURL:   "https://github.com/acme/payments.git",
+		AIProvider:      string(models.AIProviderBedrock),
+		Status:          string(models.AgentStatusReady),
+	}
+
+	redactions := redact.Report{FilesScanned: 12, FilesRedacted: 1, Counts: map[string]int{redact.RuleEmail: 2}}
+	mockAgentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(existing, nil)
+	mockRedaction.EXPECT().ResolvePolicy(gomock.Any(), "proj-1").Return(redact.DefaultPolicy(), nil)
+	mockInfraFactory.EXPECT().
+		UpdateAgentInfrastructure(gomock.Any(), gomock.Any(), "kb-old", models.AIProviderBedrock, "", config.Embedding{}, redact.DefaultPolicy()).
+		Return(&factory.AIInfrastructureResult{
+			KnowledgeBaseID: "kb-new",
+			VectorStoreID:   "vs-new",
+			AgentVersion:    "2",
+			Status:          models.AgentStatusReady,
+			Redactions:      redactions,
+		}, nil)
+	mockRedaction.EXPECT().RecordAudit(gomock.Any(), "proj-1", "agent-1", models.RedactionOperationRebuild, redactions).Return(nil)
+	mockAgentRepo.EXPECT().
+		UpdateAgent(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, record *repository.AgentRecord) error {
+			assert.Equal(t, "kb-new", record.KnowledgeBaseID)
+			assert.Equal(t, existing.RepositoryURL, record.RepositoryURL)
+			assert.Equal(t, "proj-1", record.ProjectID)
+			return nil
+		})
+
+	service := NewDefaultAgentService(mockAgentRepo, mockInfraFactory, servicesMocks.NewMockNotifier(ctrl), mockRedaction, nil, nil, nil)
+
+	// Act
+	response, err := service.RebuildAgent(context.Background(), "agent-1")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "kb-new", response.KnowledgeBaseID)
+	assert.Equal(t, "2", response.AgentVersion)
+}
+
+func TestDefaultAgentService_RebuildAgent_NotifiesProvisioningFailure(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAgentRepo := repoMocks.NewMockAgentRepository(ctrl)
+	mockInfraFactory := factoryMocks.NewMockAIInfrastructureFactory(ctrl)
+	mockRedaction := servicesMocks.NewMockRedactionService(ctrl)
+	mockNotifier := servicesMocks.NewMockNotifier(ctrl)
+
+	mockAgentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{
+		AgentID:         "agent-1",
+		KnowledgeBaseID: "kb-old",
+		AIProvider:      string(models.AIProviderBedrock),
+	}, nil)
+	mockRedaction.EXPECT().ResolvePolicy(gomock.Any(), "").Return(redact.DefaultPolicy(), nil)
+	mockInfraFactory.EXPECT().
+		UpdateAgentInfrastructure(gomock.Any(), gomock.Any(), "kb-old", models.AIProviderBedrock, "", config.Embedding{}, redact.DefaultPolicy()).
+		Return(nil, errors.New("quota exceeded"))
+	mockNotifier.EXPECT().
+		Notify(gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, notification models.Notification) {
+			assert.Equal(t, models.NotificationEventAgentProvisioningFailed, notification.Event)
+			assert.Empty(t, notification.ProjectID)
+			assert.Contains(t, notification.Message, "quota exceeded")
+		})
+
+	service := NewDefaultAgentService(mockAgentRepo, mockInfraFactory, mockNotifier, mockRedaction, nil, nil, nil)
+
+	// Act
+	_, err := service.RebuildAgent(context.Background(), "agent-1")
+
+	// Assert
+	assert.ErrorContains(t, err, "failed to rebuild AI infrastructure")
+}
+
+func TestDefaultAgentService_CreateAgent_IngestionBlocked(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAgentRepo := repoMocks.NewMockAgentRepository(ctrl)
+	mockInfraFactory := factoryMocks.NewMockAIInfrastructureFactory(ctrl)
+	mockRedaction := servicesMocks.NewMockRedactionService(ctrl)
+	mockNotifier := servicesMocks.NewMockNotifier(ctrl)
+
+	blocked := &scan.BlockedError{Findings: []scan.Finding{
+		{Kind: scan.KindSecret, Rule: "private-key", Severity: scan.SeverityHigh, FilePath: "deploy.pem", Line: 1, Message: "Committed private key"},
+	}}
+	mockInfraFactory.EXPECT().ValidateAgentConfig(models.AIProviderBedrock, "", config.Embedding{}).Return(nil)
+	mockRedaction.EXPECT().ResolvePolicy(gomock.Any(), "").Return(redact.DefaultPolicy(), nil)
+	mockInfraFactory.EXPECT().
+		CreateAgentInfrastructure(gomock.Any(), gomock.Any(), models.AIProviderBedrock, "", config.Embedding{}, redact.DefaultPolicy()).
+		Return(nil, fmt.Errorf("failed to run Bedrock setup workflow: %w", blocked))
+	mockNotifier.EXPECT().Notify(gomock.Any(), gomock.Any())
+
+	service := NewDefaultAgentService(mockAgentRepo, mockInfraFactory, mockNotifier, mockRedaction, nil, nil, nil)
+
+	// Act
+	_, err := service.CreateAgent(context.Background(), models.CreateAgentRequest{
+		RepositoryURL: "https://github.com/acme/payments",
+		AIProvider:    models.AIProviderBedrock,
+	})
+
+	// Assert
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+	assert.Equal(t, apperrors.CodeIngestionBlocked, apperrors.CodeOf(err))
+	assert.ErrorContains(t, err, "Committed private key in deploy.pem:1")
+}
+
+func TestDefaultAgentService_CreateAgent_AppliesProjectRedactionPolicy(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAgentRepo := repoMocks.NewMockAgentRepository(ctrl)
+	mockInfraFactory := factoryMocks.NewMockAIInfrastructureFactory(ctrl)
+	mockRedaction := servicesMocks.NewMockRedactionService(ctrl)
+
+	policy := redact.Policy{MaskCredentials: true, Patterns: []redact.Pattern{{Name: "customer-id", Expression: `CUST-[0-9]{6}`}}}
+	redactions := redact.Report{FilesScanned: 40, FilesRedacted: 3, Counts: map[string]int{"customer-id": 5}}
+
+	mockInfraFactory.EXPECT().ValidateAgentConfig(models.AIProviderLocal, "", config.Embedding{}).Return(nil)
+	mockRedaction.EXPECT().ResolvePolicy(gomock.Any(), "proj-1").Return(policy, nil)
+	mockInfraFactory.EXPECT().
+		CreateAgentInfrastructure(gomock.Any(), gomock.Any(), models.AIProviderLocal, "", config.Embedding{}, policy).
+		Return(&factory.AIInfrastructureResult{
+			AgentID:    "agent-12345678",
+			Status:     models.AgentStatusInitializing,
+			Redactions: redactions,
+		}, nil)
+	mockRedaction.EXPECT().
+		RecordAudit(gomock.Any(), "proj-1", "agent-12345678", models.RedactionOperationCreate, redactions).
+		Return(errors.New("database unavailable"))
+	mockAgentRepo.EXPECT().
+		CreateAgent(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, record *repository.AgentRecord) error {
+			assert.Equal(t, "proj-1", record.ProjectID)
+			return nil
+		})
+	mockAgentRepo.EXPECT().UpdateAgentStatus(gomock.Any(), "agent-12345678", models.AgentStatusReady).Return(nil)
+
+	service := NewDefaultAgentService(mockAgentRepo, mockInfraFactory, servicesMocks.NewMockNotifier(ctrl), mockRedaction, nil, nil, nil)
+
+	// Act
+	response, err := service.CreateAgent(context.Background(), models.CreateAgentRequest{
+		RepositoryURL: "https://github.com/acme/payments",
+		ProjectID:     "proj-1",
+	})
+
+	// Assert
+	require.NoError(t, err, "a failure to record the audit doesn't fail the sync")
+	assert.Equal(t, "agent-12345678", response.AgentID)
+}
+
+func TestDefaultAgentService_CreateAgent_Region(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAgentRepo := repoMocks.NewMockAgentRepository(ctrl)
+	mockInfraFactory := factoryMocks.NewMockAIInfrastructureFactory(ctrl)
+	mockRedaction := servicesMocks.NewMockRedactionService(ctrl)
+
+	mockInfraFactory.EXPECT().ValidateAgentConfig(models.AIProviderBedrock, "eu-west-1", config.Embedding{}).Return(nil)
+	mockRedaction.EXPECT().ResolvePolicy(gomock.Any(), "").Return(redact.DefaultPolicy(), nil)
+	mockInfraFactory.EXPECT().
+		CreateAgentInfrastructure(gomock.Any(), gomock.Any(), models.AIProviderBedrock, "eu-west-1", config.Embedding{}, redact.DefaultPolicy()).
+		Return(&factory.AIInfrastructureResult{AgentID: "agent-12345678", Status: models.AgentStatusInitializing}, nil)
+	mockRedaction.EXPECT().RecordAudit(gomock.Any(), "", "agent-12345678", models.RedactionOperationCreate, gomock.Any()).Return(nil)
+	mockAgentRepo.EXPECT().
+		CreateAgent(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, record *repository.AgentRecord) error {
+			assert.Equal(t, "eu-west-1", record.Region)
+			return nil
+		})
+	mockAgentRepo.EXPECT().UpdateAgentStatus(gomock.Any(), "agent-12345678", models.AgentStatusReady).Return(nil)
+
+	service := NewDefaultAgentService(mockAgentRepo, mockInfraFactory, servicesMocks.NewMockNotifier(ctrl), mockRedaction, nil, nil, nil)
+
+	// Act
+	_, err := service.CreateAgent(context.Background(), models.CreateAgentRequest{
+		RepositoryURL: "https://github.com/acme/payments",
+		AIProvider:    models.AIProviderBedrock,
+		Region:        "eu-west-1",
+	})
+
+	// Assert
+	require.NoError(t, err)
+}
+
+func TestDefaultAgentService_CreateAgent_RegionNotAllowed(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockInfraFactory := factoryMocks.NewMockAIInfrastructureFactory(ctrl)
+	mockInfraFactory.EXPECT().ValidateAgentConfig(models.AIProviderBedrock, "sa-east-1", config.Embedding{}).Return(errors.New("bedrock region sa-east-1 is not allowed"))
+
+	service := NewDefaultAgentService(repoMocks.NewMockAgentRepository(ctrl), mockInfraFactory, nil, nil, nil, nil, nil)
+
+	// Act
+	_, err := service.CreateAgent(context.Background(), models.CreateAgentRequest{
+		RepositoryURL: "https://github.com/acme/payments",
+		AIProvider:    models.AIProviderBedrock,
+		Region:        "sa-east-1",
+	})
+
+	// Assert
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+}
+
+func TestDefaultAgentService_CreateAgent_UnknownProject(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockInfraFactory := factoryMocks.NewMockAIInfrastructureFactory(ctrl)
+	mockRedaction := servicesMocks.NewMockRedactionService(ctrl)
+
+	mockInfraFactory.EXPECT().ValidateAgentConfig(models.AIProviderLocal, "", config.Embedding{}).Return(nil)
+	mockRedaction.EXPECT().
+		ResolvePolicy(gomock.Any(), "proj-missing").
+		Return(redact.Policy{}, apperrors.Validation(apperrors.CodeProjectNotFound, "project not found: proj-missing"))
+
+	service := NewDefaultAgentService(repoMocks.NewMockAgentRepository(ctrl), mockInfraFactory, servicesMocks.NewMockNotifier(ctrl), mockRedaction, nil, nil, nil)
+
+	// Act
+	_, err := service.CreateAgent(context.Background(), models.CreateAgentRequest{
+		RepositoryURL: "https://github.com/acme/payments",
+		ProjectID:     "proj-missing",
+	})
+
+	// Assert
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	assert.Equal(t, apperrors.CodeProjectNotFound, apperrors.CodeOf(err))
+}
+
+func TestDefaultAgentService_CreateAgent_InheritsProjectEmbedding(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAgentRepo := repoMocks.NewMockAgentRepository(ctrl)
+	mockProjectRepo := repoMocks.NewMockProjectRepository(ctrl)
+	mockInfraFactory := factoryMocks.NewMockAIInfrastructureFactory(ctrl)
+	mockRedaction := servicesMocks.NewMockRedactionService(ctrl)
+
+	embedding := config.Embedding{Model: "cohere.embed-english-v3", Dimensions: 512}
+	mockProjectRepo.EXPECT().
+		GetProject(gomock.Any(), "proj-1").
+		Return(&repository.ProjectRecord{ProjectID: "proj-1", EmbeddingModel: embedding.Model, EmbeddingDimensions: embedding.Dimensions}, nil)
+	mockInfraFactory.EXPECT().ValidateAgentConfig(models.AIProviderBedrock, "", embedding).Return(nil)
+	mockRedaction.EXPECT().ResolvePolicy(gomock.Any(), "proj-1").Return(redact.DefaultPolicy(), nil)
+	mockInfraFactory.EXPECT().
+		CreateAgentInfrastructure(gomock.Any(), gomock.Any(), models.AIProviderBedrock, "", embedding, redact.DefaultPolicy()).
+		Return(&factory.AIInfrastructureResult{AgentID: "agent-12345678", Status: models.AgentStatusInitializing, Embedding: embedding}, nil)
+	mockRedaction.EXPECT().RecordAudit(gomock.Any(), "proj-1", "agent-12345678", models.RedactionOperationCreate, gomock.Any()).Return(nil)
+	mockAgentRepo.EXPECT().
+		CreateAgent(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, record *repository.AgentRecord) error {
+			assert.Equal(t, embedding, record.Embedding())
+			return nil
+		})
+	mockAgentRepo.EXPECT().UpdateAgentStatus(gomock.Any(), "agent-12345678", models.AgentStatusReady).Return(nil)
+
+	service := NewDefaultAgentService(mockAgentRepo, mockInfraFactory, servicesMocks.NewMockNotifier(ctrl), mockRedaction, nil, nil, mockProjectRepo)
+
+	// Act
+	_, err := service.CreateAgent(context.Background(), models.CreateAgentRequest{
+		RepositoryURL: "https://github.com/acme/payments",
+		AIProvider:    models.AIProviderBedrock,
+		ProjectID:     "proj-1",
+	})
+
+	// Assert
+	require.NoError(t, err)
+}
+
+func TestDefaultAgentService_ReembedAgent_Success(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAgentRepo := repoMocks.NewMockAgentRepository(ctrl)
+	mockInfraFactory := factoryMocks.NewMockAIInfrastructureFactory(ctrl)
+	mockRedaction := servicesMocks.NewMockRedactionService(ctrl)
+
+	embedding := config.Embedding{Model: "amazon.titan-embed-text-v2:0", Dimensions: 256}
+	existing := &repository.AgentRecord{
+		AgentID:         "agent-1",
+		ProjectID:       "proj-1",
+		KnowledgeBaseID: "kb-old",
+		AIProvider:      string(models.AIProviderBedrock),
+		Status:          string(models.AgentStatusReady),
+	}
+
+	mockAgentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(existing, nil).Times(2)
+	mockInfraFactory.EXPECT().ValidateAgentConfig(models.AIProviderBedrock, "", embedding).Return(nil)
+	gomock.InOrder(
+		// The embedding is saved before the rebuild so a failed rebuild resumes with it
+		mockAgentRepo.EXPECT().
+			UpdateAgent(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, record *repository.AgentRecord) error {
+				assert.Equal(t, "kb-old", record.KnowledgeBaseID)
+				assert.Equal(t, embedding, record.Embedding())
+				return nil
+			}),
+		mockAgentRepo.EXPECT().
+			UpdateAgent(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, record *repository.AgentRecord) error {
+				assert.Equal(t, "kb-new", record.KnowledgeBaseID)
+				assert.Equal(t, embedding, record.Embedding())
+				return nil
+			}),
+	)
+	mockRedaction.EXPECT().ResolvePolicy(gomock.Any(), "proj-1").Return(redact.DefaultPolicy(), nil)
+	mockInfraFactory.EXPECT().
+		UpdateAgentInfrastructure(gomock.Any(), gomock.Any(), "kb-old", models.AIProviderBedrock, "", embedding, redact.DefaultPolicy()).
+		Return(&factory.AIInfrastructureResult{KnowledgeBaseID: "kb-new", Status: models.AgentStatusReady, Embedding: embedding}, nil)
+	mockRedaction.EXPECT().RecordAudit(gomock.Any(), "proj-1", "agent-1", models.RedactionOperationRebuild, gomock.Any()).Return(nil)
+
+	service := NewDefaultAgentService(mockAgentRepo, mockInfraFactory, servicesMocks.NewMockNotifier(ctrl), mockRedaction, nil, nil, nil)
+
+	// Act
+	response, err := service.ReembedAgent(context.Background(), "agent-1", embedding)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "kb-new", response.KnowledgeBaseID)
+}
+
+func TestDefaultAgentService_ReembedAgent_InvalidEmbedding(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAgentRepo := repoMocks.NewMockAgentRepository(ctrl)
+	mockInfraFactory := factoryMocks.NewMockAIInfrastructureFactory(ctrl)
+
+	embedding := config.Embedding{Model: "amazon.titan-embed-text-v1", Dimensions: 512}
+	mockAgentRepo.EXPECT().
+		GetAgent(gomock.Any(), "agent-1").
+		Return(&repository.AgentRecord{AgentID: "agent-1", AIProvider: string(models.AIProviderBedrock)}, nil)
+	mockInfraFactory.EXPECT().
+		ValidateAgentConfig(models.AIProviderBedrock, "", embedding).
+		Return(errors.New("embedding model amazon.titan-embed-text-v1 doesn't produce 512 dimensions"))
+
+	service := NewDefaultAgentService(mockAgentRepo, mockInfraFactory, nil, nil, nil, nil, nil)
+
+	// Act
+	_, err := service.ReembedAgent(context.Background(), "agent-1", embedding)
+
+	// Assert
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	assert.Equal(t, apperrors.CodeInvalidEmbedding, apperrors.CodeOf(err))
+}
+
+func TestDefaultAgentService_ResumeAgentSetup_SavesCreatedAgent(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAgentRepo := repoMocks.NewMockAgentRepository(ctrl)
+	mockInfraFactory := factoryMocks.NewMockAIInfrastructureFactory(ctrl)
+	mockRedaction := servicesMocks.NewMockRedactionService(ctrl)
+
+	// A persisted run sees the request it was started with decoded into JSON types
+	store := workflow.NewMemoryRunStore()
+	require.NoError(t, store.SaveRun(context.Background(), &workflow.Run{
+		RunID:    "setup-1",
+		Workflow: workflow.CreateLocalSetupWorkflowName,
+		Status:   workflow.RunStatusFailed,
+		Steps: []workflow.StepProgress{
+			{Name: "clone", Status: workflow.StepStatusCompleted, Attempts: 1},
+			{Name: "build_rag", Status: workflow.StepStatusCompleted, Attempts: 1},
+			{Name: "build_agent", Status: workflow.StepStatusFailed, Attempts: 3, Error: "ollama unavailable"},
+		},
+		Values: map[string]any{
+			"operation":  "create",
+			"project_id": "proj-1",
+			"request": map[string]any{
+				"repository_url": "https://github.com/acme/payments",
+				"agent_name":     "payments",
+				"ai_provider":    "local",
+				"project_id":     "proj-1",
+			},
+		},
+		Error: "ollama unavailable",
+	}))
+
+	mockRedaction.EXPECT().ResolvePolicy(gomock.Any(), "proj-1").Return(redact.DefaultPolicy(), nil)
+	mockInfraFactory.EXPECT().
+		CreateAgentInfrastructure(gomock.Any(), workflow.Setup{RunID: "setup-1", RepositoryURL: "https://github.com/acme/payments"}, models.AIProviderLocal, "", config.Embedding{}, redact.DefaultPolicy()).
+		DoAndReturn(func(ctx context.Context, setup workflow.Setup, _ models.AIProvider, _ string, _ config.Embedding, _ redact.Policy) (*factory.AIInfrastructureResult, error) {
+			run, err := store.GetRun(ctx, setup.RunID)
+			require.NoError(t, err)
+			run.Status = workflow.RunStatusCompleted
+			run.Step("build_agent").Status = workflow.StepStatusCompleted
+			run.Set("agent_id", "agent-12345678")
+			require.NoError(t, store.SaveRun(ctx, run))
+
+			return &factory.AIInfrastructureResult{AgentID: "agent-12345678", Status: models.AgentStatusInitializing}, nil
+		})
+	mockRedaction.EXPECT().RecordAudit(gomock.Any(), "proj-1", "agent-12345678", models.RedactionOperationCreate, gomock.Any()).Return(nil)
+	mockAgentRepo.EXPECT().
+		CreateAgent(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, record *repository.AgentRecord) error {
+			assert.Equal(t, "payments", record.AgentName)
+			assert.Equal(t, "https://github.com/acme/payments", record.RepositoryURL)
+			assert.Equal(t, "proj-1", record.ProjectID)
+			return nil
+		})
+	mockAgentRepo.EXPECT().UpdateAgentStatus(gomock.Any(), "agent-12345678", models.AgentStatusReady).Return(nil)
+
+	service := NewDefaultAgentService(mockAgentRepo, mockInfraFactory, servicesMocks.NewMockNotifier(ctrl), mockRedaction, workflow.NewEngine(store, 0), nil, nil)
+
+	// Act
+	setup, err := service.ResumeAgentSetup(context.Background(), "setup-1")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "completed", setup.Status)
+	assert.Equal(t, models.AgentSetupOperationCreate, setup.Operation)
+	assert.Equal(t, models.AIProviderLocal, setup.AIProvider)
+	assert.Equal(t, "agent-12345678", setup.AgentID)
+}
+
+func TestDefaultAgentService_ResumeAgentSetup_RejectsSetupThatDidNotFail(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := workflow.NewMemoryRunStore()
+	require.NoError(t, store.SaveRun(context.Background(), &workflow.Run{
+		RunID:    "setup-1",
+		Workflow: workflow.CreateBedrockSetupWorkflowName,
+		Status:   workflow.RunStatusRolledBack,
+	}))
+
+	service := NewDefaultAgentService(repoMocks.NewMockAgentRepository(ctrl), factoryMocks.NewMockAIInfrastructureFactory(ctrl),
+		servicesMocks.NewMockNotifier(ctrl), servicesMocks.NewMockRedactionService(ctrl), workflow.NewEngine(store, 0), nil, nil)
+
+	// Act
+	_, resumeErr := service.ResumeAgentSetup(context.Background(), "setup-1")
+	_, tearDownErr := service.TearDownAgentSetup(context.Background(), "setup-1")
+	_, missingErr := service.ResumeAgentSetup(context.Background(), "setup-missing")
+
+	// Assert
+	assert.ErrorIs(t, resumeErr, apperrors.ErrConflict)
+	assert.Equal(t, apperrors.CodeAgentSetupNotFailed, apperrors.CodeOf(resumeErr))
+	assert.Equal(t, apperrors.CodeAgentSetupNotFailed, apperrors.CodeOf(tearDownErr))
+	assert.ErrorIs(t, missingErr, apperrors.ErrNotFound)
+	assert.Equal(t, apperrors.CodeAgentSetupNotFound, apperrors.CodeOf(missingErr))
+}
+
+func TestDefaultAgentService_TearDownAgentSetup_RollsBackFailedSetup(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockInfraFactory := factoryMocks.NewMockAIInfrastructureFactory(ctrl)
+
+	store := workflow.NewMemoryRunStore()
+	require.NoError(t, store.SaveRun(context.Background(), &workflow.Run{
+		RunID:    "setup-1",
+		Workflow: workflow.CreateBedrockSetupWorkflowName,
+		Status:   workflow.RunStatusFailed,
+		Values:   map[string]any{"operation": "rebuild", "rebuild_agent_id": "agent-1"},
+	}))
+
+	mockInfraFactory.EXPECT().
+		TearDownAgentSetup(gomock.Any(), "setup-1").
+		DoAndReturn(func(ctx context.Context, setupID string) error {
+			run, err := store.GetRun(ctx, setupID)
+			require.NoError(t, err)
+			run.Status = workflow.RunStatusRolledBack
+			return store.SaveRun(ctx, run)
+		})
+
+	service := NewDefaultAgentService(repoMocks.NewMockAgentRepository(ctrl), mockInfraFactory,
+		servicesMocks.NewMockNotifier(ctrl), servicesMocks.NewMockRedactionService(ctrl), workflow.NewEngine(store, 0), nil, nil)
+
+	// Act
+	setup, err := service.TearDownAgentSetup(context.Background(), "setup-1")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "rolled_back", setup.Status)
+	assert.Equal(t, models.AgentSetupOperationRebuild, setup.Operation)
+	assert.Equal(t, models.AIProviderBedrock, setup.AIProvider)
+	assert.Equal(t, "agent-1", setup.AgentID)
+}
+
+func TestDefaultAgentService_DeleteAgent_SchedulesContentPurge(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAgentRepo := repoMocks.NewMockAgentRepository(ctrl)
+	mockInfraFactory := factoryMocks.NewMockAIInfrastructureFactory(ctrl)
+	mockStorage := servicesMocks.NewMockStorageLifecycleService(ctrl)
+
+	agent := &repository.AgentRecord{AgentID: "agent-1", KnowledgeBaseID: "KB1", AIProvider: "bedrock", Region: "eu-west-1"}
+	mockAgentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(agent, nil)
+	mockInfraFactory.EXPECT().DestroyAgentInfrastructure(gomock.Any(), "agent-1", "eu-west-1").Return(nil)
+	mockAgentRepo.EXPECT().DeleteAgent(gomock.Any(), "agent-1").Return(nil)
+	mockStorage.EXPECT().PurgeAgentContent(gomock.Any(), agent).Return(&models.StoragePurge{PurgeID: "purge-1"}, nil)
+
+	service := NewDefaultAgentService(mockAgentRepo, mockInfraFactory, servicesMocks.NewMockNotifier(ctrl),
+		servicesMocks.NewMockRedactionService(ctrl), nil, mockStorage, nil)
+
+	// Act
+	response, err := service.DeleteAgent(context.Background(), "agent-1")
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, response.Success)
+	assert.Equal(t, "purge-1", response.PurgeID)
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/storage"
+)
+
+// defaultAgentSyncJobsLimit is the number of recent syncs reported when no limit is requested
+const defaultAgentSyncJobsLimit = 10
+
+// DefaultAgentSyncService is the default implementation of AgentSyncService
+type DefaultAgentSyncService struct {
+	agentRepository   repository.AgentRepository
+	projectRepository repository.ProjectRepository
+	ingesters         func(region string) storage.Ingester
+}
+
+// NewDefaultAgentSyncService creates a new DefaultAgentSyncService, syncing the knowledge bases of agents with the
+// ingester of their region
+func NewDefaultAgentSyncService(agentRepo repository.AgentRepository, projectRepo repository.ProjectRepository, ingesters func(region string) storage.Ingester) *DefaultAgentSyncService {
+	return &DefaultAgentSyncService{
+		agentRepository:   agentRepo,
+		projectRepository: projectRepo,
+		ingesters:         ingesters,
+	}
+}
+
+// GetSyncStatus reports the last sync time, document counts and failures of an agent's knowledge base
+func (s *DefaultAgentSyncService) GetSyncStatus(ctx context.Context, request models.GetAgentSyncStatusRequest) (*models.AgentSyncStatus, error) {
+	agent, err := s.getSyncableAgent(ctx, request.AgentID)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := request.Limit
+	if limit == 0 {
+		limit = defaultAgentSyncJobsLimit
+	}
+
+	jobs, err := s.ingesters(agent.Region).ListIngestions(ctx, agent.KnowledgeBaseID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list knowledge base syncs: %w", err)
+	}
+
+	return newAgentSyncStatus(agent, jobs), nil
+}
+
+// SyncAgent starts resyncing an agent's knowledge base. A knowledge base syncs one job at a time, so it fails while
+// another sync is running, and agents of archived projects aren't synced.
+func (s *DefaultAgentSyncService) SyncAgent(ctx context.Context, request models.SyncAgentRequest) (*models.AgentSyncStatus, error) {
+	agent, err := s.getSyncableAgent(ctx, request.AgentID)
+	if err != nil {
+		return nil, err
+	}
+	if agent.ProjectID != "" {
+		if err := ensureProjectNotArchived(ctx, s.projectRepository, agent.ProjectID); err != nil {
+			return nil, err
+		}
+	}
+
+	ingester := s.ingesters(agent.Region)
+	jobs, err := ingester.ListIngestions(ctx, agent.KnowledgeBaseID, defaultAgentSyncJobsLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list knowledge base syncs: %w", err)
+	}
+	if len(jobs) > 0 && jobs[0].Status.Running() {
+		return nil, apperrors.Conflict(apperrors.CodeAgentSyncInProgress, "agent %s is already syncing: %s", agent.AgentID, jobs[0].JobID)
+	}
+
+	job, err := ingester.StartIngestion(ctx, agent.KnowledgeBaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start knowledge base sync: %w", err)
+	}
+
+	// The started job may not be listed yet, so it's reported ahead of the earlier ones
+	jobs = append([]storage.IngestionJob{*job}, jobs...)
+	if len(jobs) > defaultAgentSyncJobsLimit {
+		jobs = jobs[:defaultAgentSyncJobsLimit]
+	}
+
+	return newAgentSyncStatus(agent, jobs), nil
+}
+
+// getSyncableAgent returns an agent whose knowledge base ingests a data source. Local agents embed their repository
+// while they are provisioned, so they have nothing to sync.
+func (s *DefaultAgentSyncService) getSyncableAgent(ctx context.Context, agentID string) (*repository.AgentRecord, error) {
+	agent, err := s.agentRepository.GetAgent(ctx, agentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent: %w", err)
+	}
+
+	if agent.GetAIProvider() == models.AIProviderLocal || agent.KnowledgeBaseID == "" {
+		return nil, apperrors.Validation(apperrors.CodeAgentSyncUnsupported, "agent %s has no knowledge base to sync", agentID)
+	}
+
+	return agent, nil
+}
+
+// newAgentSyncStatus summarizes the recent syncs of an agent, most recent first
+func newAgentSyncStatus(agent *repository.AgentRecord, jobs []storage.IngestionJob) *models.AgentSyncStatus {
+	status := &models.AgentSyncStatus{
+		AgentID:         agent.AgentID,
+		KnowledgeBaseID: agent.KnowledgeBaseID,
+		Jobs:            make([]models.AgentSyncJob, 0, len(jobs)),
+	}
+
+	for _, job := range jobs {
+		if job.Status.Running() {
+			status.Syncing = true
+		}
+		if job.Status == storage.IngestionStatusComplete && status.LastSyncedAt == nil {
+			syncedAt := job.UpdatedAt
+			status.LastSyncedAt = &syncedAt
+		}
+
+		status.Jobs = append(status.Jobs, models.AgentSyncJob{
+			JobID:            job.JobID,
+			Status:           string(job.Status),
+			DocumentsScanned: job.DocumentsScanned,
+			DocumentsIndexed: job.DocumentsIndexed,
+			DocumentsDeleted: job.DocumentsDeleted,
+			DocumentsFailed:  job.DocumentsFailed,
+			FailureReasons:   job.FailureReasons,
+			StartedAt:        job.StartedAt,
+			UpdatedAt:        job.UpdatedAt,
+		})
+	}
+
+	return status
+}
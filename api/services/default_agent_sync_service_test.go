@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/storage"
+	storageMocks "github.com/kazemisoroush/code-refactoring-tool/pkg/ai/storage/mocks"
+)
+
+type agentSyncServiceMocks struct {
+	agentRepo   *repositoryMocks.MockAgentRepository
+	projectRepo *repositoryMocks.MockProjectRepository
+	ingester    *storageMocks.MockIngester
+	regions     *[]string
+}
+
+func newTestAgentSyncService(t *testing.T) (*DefaultAgentSyncService, agentSyncServiceMocks) {
+	ctrl := gomock.NewController(t)
+	m := agentSyncServiceMocks{
+		agentRepo:   repositoryMocks.NewMockAgentRepository(ctrl),
+		projectRepo: repositoryMocks.NewMockProjectRepository(ctrl),
+		ingester:    storageMocks.NewMockIngester(ctrl),
+		regions:     &[]string{},
+	}
+	ingesters := func(region string) storage.Ingester {
+		*m.regions = append(*m.regions, region)
+		return m.ingester
+	}
+	return NewDefaultAgentSyncService(m.agentRepo, m.projectRepo, ingesters), m
+}
+
+func TestDefaultAgentSyncService_GetSyncStatus(t *testing.T) {
+	service, m := newTestAgentSyncService(t)
+	completedAt := time.Date(2024, time.January, 15, 10, 34, 0, 0, time.UTC)
+
+	m.agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{
+		AgentID:         "agent-1",
+		KnowledgeBaseID: "kb-1",
+		AIProvider:      string(models.AIProviderBedrock),
+		Region:          "eu-west-1",
+	}, nil)
+	m.ingester.EXPECT().ListIngestions(gomock.Any(), "kb-1", 10).Return([]storage.IngestionJob{
+		{JobID: "job-3", Status: storage.IngestionStatusFailed, FailureReasons: []string{"access denied"}},
+		{JobID: "job-2", Status: storage.IngestionStatusComplete, DocumentsScanned: 420, DocumentsIndexed: 415, DocumentsFailed: 5, UpdatedAt: completedAt},
+		{JobID: "job-1", Status: storage.IngestionStatusComplete, UpdatedAt: completedAt.Add(-time.Hour)},
+	}, nil)
+
+	status, err := service.GetSyncStatus(context.Background(), models.GetAgentSyncStatusRequest{AgentID: "agent-1"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"eu-west-1"}, *m.regions, "the knowledge base is synced in its agent's region")
+	assert.Equal(t, "kb-1", status.KnowledgeBaseID)
+	assert.False(t, status.Syncing)
+	require.NotNil(t, status.LastSyncedAt)
+	assert.Equal(t, completedAt, *status.LastSyncedAt)
+	require.Len(t, status.Jobs, 3)
+	assert.Equal(t, "failed", status.Jobs[0].Status)
+	assert.Equal(t, []string{"access denied"}, status.Jobs[0].FailureReasons)
+	assert.Equal(t, 415, status.Jobs[1].DocumentsIndexed)
+	assert.Equal(t, 5, status.Jobs[1].DocumentsFailed)
+}
+
+func TestDefaultAgentSyncService_GetSyncStatus_NeverSynced(t *testing.T) {
+	service, m := newTestAgentSyncService(t)
+
+	m.agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{AgentID: "agent-1", KnowledgeBaseID: "kb-1"}, nil)
+	m.ingester.EXPECT().ListIngestions(gomock.Any(), "kb-1", 5).Return(nil, nil)
+
+	status, err := service.GetSyncStatus(context.Background(), models.GetAgentSyncStatusRequest{AgentID: "agent-1", Limit: 5})
+
+	require.NoError(t, err)
+	assert.Nil(t, status.LastSyncedAt)
+	assert.Empty(t, status.Jobs)
+}
+
+func TestDefaultAgentSyncService_GetSyncStatus_LocalAgent(t *testing.T) {
+	service, m := newTestAgentSyncService(t)
+
+	m.agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{
+		AgentID:         "agent-1",
+		KnowledgeBaseID: "collection-1",
+		AIProvider:      string(models.AIProviderLocal),
+	}, nil)
+
+	_, err := service.GetSyncStatus(context.Background(), models.GetAgentSyncStatusRequest{AgentID: "agent-1"})
+
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	assert.Equal(t, apperrors.CodeAgentSyncUnsupported, apperrors.CodeOf(err))
+}
+
+func TestDefaultAgentSyncService_SyncAgent(t *testing.T) {
+	service, m := newTestAgentSyncService(t)
+	completedAt := time.Date(2024, time.January, 15, 10, 34, 0, 0, time.UTC)
+
+	m.agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{AgentID: "agent-1", KnowledgeBaseID: "kb-1"}, nil)
+	m.ingester.EXPECT().ListIngestions(gomock.Any(), "kb-1", 10).Return([]storage.IngestionJob{
+		{JobID: "job-1", Status: storage.IngestionStatusComplete, UpdatedAt: completedAt},
+	}, nil)
+	m.ingester.EXPECT().StartIngestion(gomock.Any(), "kb-1").Return(&storage.IngestionJob{JobID: "job-2", Status: storage.IngestionStatusStarting}, nil)
+
+	status, err := service.SyncAgent(context.Background(), models.SyncAgentRequest{AgentID: "agent-1"})
+
+	require.NoError(t, err)
+	assert.True(t, status.Syncing)
+	require.NotNil(t, status.LastSyncedAt)
+	assert.Equal(t, completedAt, *status.LastSyncedAt)
+	require.Len(t, status.Jobs, 2)
+	assert.Equal(t, "job-2", status.Jobs[0].JobID)
+	assert.Equal(t, "starting", status.Jobs[0].Status)
+}
+
+func TestDefaultAgentSyncService_SyncAgent_AlreadySyncing(t *testing.T) {
+	service, m := newTestAgentSyncService(t)
+
+	m.agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{AgentID: "agent-1", KnowledgeBaseID: "kb-1"}, nil)
+	m.ingester.EXPECT().ListIngestions(gomock.Any(), "kb-1", 10).Return([]storage.IngestionJob{
+		{JobID: "job-1", Status: storage.IngestionStatusInProgress},
+	}, nil)
+
+	_, err := service.SyncAgent(context.Background(), models.SyncAgentRequest{AgentID: "agent-1"})
+
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+	assert.Equal(t, apperrors.CodeAgentSyncInProgress, apperrors.CodeOf(err))
+}
+
+func TestDefaultAgentSyncService_SyncAgent_ArchivedProject(t *testing.T) {
+	service, m := newTestAgentSyncService(t)
+
+	m.agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{AgentID: "agent-1", ProjectID: "proj-1", KnowledgeBaseID: "kb-1"}, nil)
+	m.projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{ProjectID: "proj-1", Status: string(models.ProjectStatusArchived)}, nil)
+
+	_, err := service.SyncAgent(context.Background(), models.SyncAgentRequest{AgentID: "agent-1"})
+
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+	assert.Equal(t, apperrors.CodeProjectArchived, apperrors.CodeOf(err))
+}
@@ -0,0 +1,257 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+)
+
+// campaignPollInterval is how often a running campaign checks on its child tasks
+const campaignPollInterval = 5 * time.Second
+
+// DefaultCampaignService is the default implementation of CampaignService.
+// Child tasks are created atomically up front and queued in the background, at most MaxParallel at a time.
+type DefaultCampaignService struct {
+	campaignRepo repository.CampaignRepository
+	taskRepo     repository.TaskRepository
+	projectRepo  repository.ProjectRepository
+	codebaseRepo repository.CodebaseRepository
+	agentRepo    repository.AgentRepository
+	taskService  TaskService
+
+	// running tracks the campaigns whose child tasks are still being run
+	running      sync.WaitGroup
+	pollInterval time.Duration
+}
+
+// NewDefaultCampaignService creates a new DefaultCampaignService
+func NewDefaultCampaignService(
+	campaignRepo repository.CampaignRepository,
+	taskRepo repository.TaskRepository,
+	projectRepo repository.ProjectRepository,
+	codebaseRepo repository.CodebaseRepository,
+	agentRepo repository.AgentRepository,
+	taskService TaskService,
+) *DefaultCampaignService {
+	return &DefaultCampaignService{
+		campaignRepo: campaignRepo,
+		taskRepo:     taskRepo,
+		projectRepo:  projectRepo,
+		codebaseRepo: codebaseRepo,
+		agentRepo:    agentRepo,
+		taskService:  taskService,
+		pollInterval: campaignPollInterval,
+	}
+}
+
+// CreateCampaign creates a child task per codebase and starts queueing them in the background
+func (s *DefaultCampaignService) CreateCampaign(ctx context.Context, request models.CreateCampaignRequest) (*models.CreateCampaignResponse, error) {
+	if _, err := s.agentRepo.GetAgent(ctx, request.AgentID); err != nil {
+		return nil, apperrors.Validation(apperrors.CodeAgentNotFound, "agent not found: %s", request.AgentID)
+	}
+
+	// Each codebase gets a single child task, in request order. Codebases of archived projects take no new tasks.
+	projectChecked := make(map[string]bool)
+	codebaseIDs := make([]string, 0, len(request.CodebaseIDs))
+	codebases := make(map[string]*models.Codebase, len(request.CodebaseIDs))
+	for _, codebaseID := range request.CodebaseIDs {
+		if _, seen := codebases[codebaseID]; seen {
+			continue
+		}
+		codebase, err := s.codebaseRepo.GetCodebase(ctx, codebaseID)
+		if err != nil {
+			return nil, apperrors.Validation(apperrors.CodeCodebaseNotFound, "codebase not found: %s", codebaseID)
+		}
+		if !projectChecked[codebase.ProjectID] {
+			if err := ensureProjectNotArchived(ctx, s.projectRepo, codebase.ProjectID); err != nil {
+				return nil, err
+			}
+			projectChecked[codebase.ProjectID] = true
+		}
+		codebases[codebaseID] = codebase
+		codebaseIDs = append(codebaseIDs, codebaseID)
+	}
+
+	maxParallel := models.DefaultCampaignParallelism
+	if request.MaxParallel != nil {
+		maxParallel = *request.MaxParallel
+	}
+
+	campaign := &models.Campaign{
+		CampaignID:  "campaign-" + uuid.New().String(),
+		Name:        request.Name,
+		Instruction: request.Instruction,
+		Type:        request.Type,
+		AgentID:     request.AgentID,
+		CodebaseIDs: codebaseIDs,
+		MaxParallel: maxParallel,
+		CreatedBy:   optionalString(request.CreatedBy),
+		CreatedAt:   time.Now().UTC(),
+	}
+	if err := s.campaignRepo.CreateCampaign(ctx, campaign); err != nil {
+		return nil, fmt.Errorf("failed to create campaign: %w", err)
+	}
+
+	tasks := make([]*models.Task, len(codebaseIDs))
+	for i, codebaseID := range codebaseIDs {
+		codebase := codebases[codebaseID]
+		tasks[i] = newTaskFromRequest(&models.CreateTaskRequest{
+			ProjectID:   codebase.ProjectID,
+			AgentID:     campaign.AgentID,
+			CodebaseID:  &codebase.CodebaseID,
+			Type:        campaign.Type,
+			Priority:    models.TaskPriorityLow,
+			Title:       fmt.Sprintf("%s: %s", campaign.Name, codebase.Name),
+			Description: campaign.Instruction,
+			CreatedBy:   request.CreatedBy,
+		})
+		tasks[i].CampaignID = &campaign.CampaignID
+	}
+
+	if err := s.taskRepo.CreateBatch(ctx, tasks); err != nil {
+		// Don't leave a campaign without child tasks behind
+		if deleteErr := s.campaignRepo.DeleteCampaign(ctx, campaign.CampaignID); deleteErr != nil {
+			slog.ErrorContext(ctx, "failed to delete campaign after creating its tasks failed", "campaign_id", campaign.CampaignID, "error", deleteErr)
+		}
+		return nil, fmt.Errorf("failed to create campaign tasks: %w", err)
+	}
+
+	taskIDs := make([]string, len(tasks))
+	for i, task := range tasks {
+		taskIDs[i] = task.TaskID
+	}
+
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+		s.run(context.WithoutCancel(ctx), campaign, taskIDs)
+	}()
+
+	return &models.CreateCampaignResponse{
+		CampaignID: campaign.CampaignID,
+		TaskIDs:    taskIDs,
+		CreatedAt:  campaign.CreatedAt,
+	}, nil
+}
+
+// run queues a campaign's child tasks for the dispatcher, keeping at most MaxParallel of them queued or in progress,
+// until they all finished. Child tasks are low priority, so the dispatcher runs other tasks first. A failing child task
+// doesn't stop the others.
+func (s *DefaultCampaignService) run(ctx context.Context, campaign *models.Campaign, taskIDs []string) {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	next := 0
+	for {
+		tasks, err := s.taskRepo.ListByCampaign(ctx, campaign.CampaignID)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to list campaign tasks", "campaign_id", campaign.CampaignID, "error", err)
+		} else {
+			active := 0
+			for _, task := range tasks {
+				if task.Status == models.TaskStatusQueued || task.Status == models.TaskStatusInProgress {
+					active++
+				}
+			}
+
+			for ; next < len(taskIDs) && active < campaign.MaxParallel; next++ {
+				// A child task cancelled in the meantime is skipped
+				if err := s.taskService.QueueTask(ctx, taskIDs[next]); err != nil {
+					slog.WarnContext(ctx, "failed to queue campaign task", "campaign_id", campaign.CampaignID, "task_id", taskIDs[next], "error", err)
+					continue
+				}
+				active++
+			}
+
+			if next == len(taskIDs) && active == 0 {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+
+	slog.InfoContext(ctx, "campaign finished", "campaign_id", campaign.CampaignID, "tasks", len(taskIDs))
+}
+
+// GetCampaign retrieves a campaign's progress and per-codebase results
+func (s *DefaultCampaignService) GetCampaign(ctx context.Context, request models.GetCampaignRequest) (*models.GetCampaignResponse, error) {
+	campaign, err := s.campaignRepo.GetCampaign(ctx, request.CampaignID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaign: %w", err)
+	}
+
+	tasks, err := s.taskRepo.ListByCampaign(ctx, campaign.CampaignID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list campaign tasks: %w", err)
+	}
+
+	response := &models.GetCampaignResponse{
+		CampaignID:   campaign.CampaignID,
+		Name:         campaign.Name,
+		Instruction:  campaign.Instruction,
+		Type:         campaign.Type,
+		AgentID:      campaign.AgentID,
+		MaxParallel:  campaign.MaxParallel,
+		TotalCount:   len(tasks),
+		StatusCounts: make(map[models.TaskStatus]int),
+		Results:      make([]models.CampaignCodebaseResult, 0, len(tasks)),
+		CreatedAt:    campaign.CreatedAt,
+	}
+
+	terminal := 0
+	for _, task := range tasks {
+		response.StatusCounts[task.Status]++
+		if task.Status.IsTerminal() {
+			terminal++
+		}
+
+		result := models.CampaignCodebaseResult{
+			ProjectID:    task.ProjectID,
+			TaskID:       task.TaskID,
+			Status:       task.Status,
+			ErrorMessage: task.ErrorMessage,
+		}
+		if task.CodebaseID != nil {
+			result.CodebaseID = *task.CodebaseID
+		}
+		if url, ok := task.Output[models.TaskOutputPullRequestURLKey].(string); ok && url != "" {
+			result.PullRequestURL = &url
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	if len(tasks) > 0 {
+		response.Progress = float64(terminal) * 100 / float64(len(tasks))
+	}
+	response.Done = terminal == len(tasks)
+	response.Status = campaignStatus(response)
+
+	return response, nil
+}
+
+// campaignStatus derives a campaign's aggregate status from the status counts of its child tasks
+func campaignStatus(response *models.GetCampaignResponse) models.CampaignStatus {
+	completed := response.StatusCounts[models.TaskStatusCompleted]
+	switch {
+	case !response.Done:
+		return models.CampaignStatusRunning
+	case completed == response.TotalCount:
+		return models.CampaignStatusCompleted
+	case completed == 0:
+		return models.CampaignStatusFailed
+	default:
+		return models.CampaignStatusPartiallyFailed
+	}
+}
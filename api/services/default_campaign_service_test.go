@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+	servicesMocks "github.com/kazemisoroush/code-refactoring-tool/api/services/mocks"
+)
+
+func TestDefaultCampaignService_CreateCampaign(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	campaignRepo := repositoryMocks.NewMockCampaignRepository(ctrl)
+	taskRepo := repositoryMocks.NewMockTaskRepository(ctrl)
+	codebaseRepo := repositoryMocks.NewMockCodebaseRepository(ctrl)
+	agentRepo := repositoryMocks.NewMockAgentRepository(ctrl)
+	taskService := servicesMocks.NewMockTaskService(ctrl)
+	projectRepo := repositoryMocks.NewMockProjectRepository(ctrl)
+	service := NewDefaultCampaignService(campaignRepo, taskRepo, projectRepo, codebaseRepo, agentRepo, taskService)
+	service.pollInterval = time.Millisecond
+
+	maxParallel := 1
+	request := models.CreateCampaignRequest{
+		Name:        "Bump logger",
+		Instruction: "Bump the logging library to v2",
+		Type:        models.TaskTypeRefactoring,
+		AgentID:     "agent-1",
+		CodebaseIDs: []string{"codebase-1", "codebase-2", "codebase-1"},
+		MaxParallel: &maxParallel,
+		CreatedBy:   "user-1",
+	}
+
+	agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{}, nil)
+	codebaseRepo.EXPECT().GetCodebase(gomock.Any(), "codebase-1").Return(&models.Codebase{CodebaseID: "codebase-1", ProjectID: "proj-1", Name: "payments"}, nil)
+	codebaseRepo.EXPECT().GetCodebase(gomock.Any(), "codebase-2").Return(&models.Codebase{CodebaseID: "codebase-2", ProjectID: "proj-2", Name: "billing"}, nil)
+	projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{ProjectID: "proj-1", Status: string(models.ProjectStatusActive)}, nil)
+	projectRepo.EXPECT().GetProject(gomock.Any(), "proj-2").Return(&repository.ProjectRecord{ProjectID: "proj-2", Status: string(models.ProjectStatusActive)}, nil)
+	campaignRepo.EXPECT().
+		CreateCampaign(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, campaign *models.Campaign) error {
+			assert.Equal(t, []string{"codebase-1", "codebase-2"}, campaign.CodebaseIDs)
+			assert.Equal(t, 1, campaign.MaxParallel)
+			return nil
+		})
+	var children []models.Task
+	taskRepo.EXPECT().
+		CreateBatch(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, tasks []*models.Task) error {
+			require.Len(t, tasks, 2)
+			assert.Equal(t, "proj-1", tasks[0].ProjectID)
+			assert.Equal(t, "Bump logger: billing", tasks[1].Title)
+			for _, task := range tasks {
+				require.NotNil(t, task.CampaignID)
+				assert.Equal(t, models.TaskStatusPending, task.Status)
+				assert.Equal(t, models.TaskPriorityLow, task.Priority)
+				children = append(children, *task)
+			}
+			return nil
+		})
+
+	// The second child task is only queued once the first finished, and the first failing doesn't stop it
+	statuses := [][]models.TaskStatus{
+		{models.TaskStatusPending, models.TaskStatusPending},
+		{models.TaskStatusInProgress, models.TaskStatusPending},
+		{models.TaskStatusFailed, models.TaskStatusPending},
+		{models.TaskStatusFailed, models.TaskStatusCompleted},
+	}
+	queuedBefore := []int{0, 1, 1, 2}
+	polls := 0
+	queued := []string{}
+	taskRepo.EXPECT().
+		ListByCampaign(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(context.Context, string) ([]models.Task, error) {
+			tasks := slices.Clone(children)
+			for i := range tasks {
+				tasks[i].Status = statuses[polls][i]
+			}
+			assert.Len(t, queued, queuedBefore[polls])
+			polls++
+			return tasks, nil
+		}).
+		Times(len(statuses))
+	taskService.EXPECT().
+		QueueTask(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, taskID string) error {
+			queued = append(queued, taskID)
+			return nil
+		}).
+		Times(2)
+
+	response, err := service.CreateCampaign(context.Background(), request)
+	require.NoError(t, err)
+	service.running.Wait()
+
+	assert.Equal(t, response.TaskIDs, queued)
+}
+
+func TestDefaultCampaignService_CreateCampaign_TaskCreationFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	campaignRepo := repositoryMocks.NewMockCampaignRepository(ctrl)
+	taskRepo := repositoryMocks.NewMockTaskRepository(ctrl)
+	codebaseRepo := repositoryMocks.NewMockCodebaseRepository(ctrl)
+	agentRepo := repositoryMocks.NewMockAgentRepository(ctrl)
+	projectRepo := repositoryMocks.NewMockProjectRepository(ctrl)
+	service := NewDefaultCampaignService(campaignRepo, taskRepo, projectRepo, codebaseRepo, agentRepo, servicesMocks.NewMockTaskService(ctrl))
+
+	agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{}, nil)
+	codebaseRepo.EXPECT().GetCodebase(gomock.Any(), "codebase-1").Return(&models.Codebase{CodebaseID: "codebase-1", ProjectID: "proj-1"}, nil)
+	projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{ProjectID: "proj-1"}, nil)
+	campaignRepo.EXPECT().CreateCampaign(gomock.Any(), gomock.Any()).Return(nil)
+	taskRepo.EXPECT().CreateBatch(gomock.Any(), gomock.Any()).Return(errors.New("connection reset"))
+	campaignRepo.EXPECT().DeleteCampaign(gomock.Any(), gomock.Any()).Return(nil)
+
+	_, err := service.CreateCampaign(context.Background(), models.CreateCampaignRequest{
+		Name: "Bump logger", Instruction: "Bump", Type: models.TaskTypeRefactoring, AgentID: "agent-1", CodebaseIDs: []string{"codebase-1"},
+	})
+
+	assert.ErrorContains(t, err, "connection reset")
+}
+
+func TestDefaultCampaignService_CreateCampaign_UnknownCodebase(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	codebaseRepo := repositoryMocks.NewMockCodebaseRepository(ctrl)
+	agentRepo := repositoryMocks.NewMockAgentRepository(ctrl)
+	service := NewDefaultCampaignService(repositoryMocks.NewMockCampaignRepository(ctrl), repositoryMocks.NewMockTaskRepository(ctrl), repositoryMocks.NewMockProjectRepository(ctrl), codebaseRepo, agentRepo, nil)
+
+	agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{}, nil)
+	codebaseRepo.EXPECT().GetCodebase(gomock.Any(), "codebase-9").Return(nil, apperrors.NotFound(apperrors.CodeCodebaseNotFound, "codebase not found: codebase-9"))
+
+	_, err := service.CreateCampaign(context.Background(), models.CreateCampaignRequest{
+		Name: "Bump logger", Instruction: "Bump", Type: models.TaskTypeRefactoring, AgentID: "agent-1", CodebaseIDs: []string{"codebase-9"},
+	})
+
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+}
+
+func TestDefaultCampaignService_CreateCampaign_ArchivedProject(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	projectRepo := repositoryMocks.NewMockProjectRepository(ctrl)
+	codebaseRepo := repositoryMocks.NewMockCodebaseRepository(ctrl)
+	agentRepo := repositoryMocks.NewMockAgentRepository(ctrl)
+	service := NewDefaultCampaignService(repositoryMocks.NewMockCampaignRepository(ctrl), repositoryMocks.NewMockTaskRepository(ctrl), projectRepo, codebaseRepo, agentRepo, nil)
+
+	agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{}, nil)
+	codebaseRepo.EXPECT().GetCodebase(gomock.Any(), "codebase-1").Return(&models.Codebase{CodebaseID: "codebase-1", ProjectID: "proj-1"}, nil)
+	projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{ProjectID: "proj-1", Status: string(models.ProjectStatusArchived)}, nil)
+
+	_, err := service.CreateCampaign(context.Background(), models.CreateCampaignRequest{
+		Name: "Bump logger", Instruction: "Bump", Type: models.TaskTypeRefactoring, AgentID: "agent-1", CodebaseIDs: []string{"codebase-1"},
+	})
+
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+}
+
+func TestDefaultCampaignService_GetCampaign(t *testing.T) {
+	codebase1, codebase2, codebase3 := "codebase-1", "codebase-2", "codebase-3"
+	failure := "agent timed out"
+
+	tests := []struct {
+		name           string
+		tasks          []models.Task
+		expectedStatus models.CampaignStatus
+		expectedDone   bool
+	}{
+		{
+			name: "running",
+			tasks: []models.Task{
+				{TaskID: "task-1", CodebaseID: &codebase1, Status: models.TaskStatusCompleted, Output: map[string]any{models.TaskOutputPullRequestURLKey: "https://github.com/acme/payments/pull/42"}},
+				{TaskID: "task-2", CodebaseID: &codebase2, Status: models.TaskStatusInProgress},
+				{TaskID: "task-3", CodebaseID: &codebase3, Status: models.TaskStatusPending},
+			},
+			expectedStatus: models.CampaignStatusRunning,
+		},
+		{
+			name: "partially_failed",
+			tasks: []models.Task{
+				{TaskID: "task-1", CodebaseID: &codebase1, Status: models.TaskStatusCompleted, Output: map[string]any{models.TaskOutputPullRequestURLKey: "https://github.com/acme/payments/pull/42"}},
+				{TaskID: "task-2", CodebaseID: &codebase2, Status: models.TaskStatusFailed, ErrorMessage: &failure},
+				{TaskID: "task-3", CodebaseID: &codebase3, Status: models.TaskStatusCompleted},
+			},
+			expectedStatus: models.CampaignStatusPartiallyFailed,
+			expectedDone:   true,
+		},
+		{
+			name: "failed",
+			tasks: []models.Task{
+				{TaskID: "task-1", CodebaseID: &codebase1, Status: models.TaskStatusFailed, ErrorMessage: &failure},
+				{TaskID: "task-2", CodebaseID: &codebase2, Status: models.TaskStatusCancelled},
+			},
+			expectedStatus: models.CampaignStatusFailed,
+			expectedDone:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			campaignRepo := repositoryMocks.NewMockCampaignRepository(ctrl)
+			taskRepo := repositoryMocks.NewMockTaskRepository(ctrl)
+			service := NewDefaultCampaignService(campaignRepo, taskRepo, repositoryMocks.NewMockProjectRepository(ctrl), repositoryMocks.NewMockCodebaseRepository(ctrl), repositoryMocks.NewMockAgentRepository(ctrl), nil)
+
+			campaignRepo.EXPECT().GetCampaign(gomock.Any(), "campaign-1").Return(&models.Campaign{CampaignID: "campaign-1", MaxParallel: 4}, nil)
+			taskRepo.EXPECT().ListByCampaign(gomock.Any(), "campaign-1").Return(tt.tasks, nil)
+
+			response, err := service.GetCampaign(context.Background(), models.GetCampaignRequest{CampaignID: "campaign-1"})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, response.Status)
+			assert.Equal(t, tt.expectedDone, response.Done)
+			assert.Equal(t, len(tt.tasks), response.TotalCount)
+			require.Len(t, response.Results, len(tt.tasks))
+			assert.Equal(t, "codebase-1", response.Results[0].CodebaseID)
+			if tt.tasks[0].Output != nil {
+				require.NotNil(t, response.Results[0].PullRequestURL)
+				assert.Equal(t, "https://github.com/acme/payments/pull/42", *response.Results[0].PullRequestURL)
+			}
+		})
+	}
+}
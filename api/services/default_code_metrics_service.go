@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/analyzer"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/codebase"
+)
+
+// DefaultCodeMetricsService is the default implementation of CodeMetricsService.
+// Snapshots are keyed by commit, so analysing the same commit again replaces its snapshot instead of adding one.
+type DefaultCodeMetricsService struct {
+	metricsRepo  repository.CodeMetricsRepository
+	codebaseRepo repository.CodebaseRepository
+}
+
+// NewDefaultCodeMetricsService creates a new DefaultCodeMetricsService
+func NewDefaultCodeMetricsService(metricsRepo repository.CodeMetricsRepository, codebaseRepo repository.CodebaseRepository) *DefaultCodeMetricsService {
+	return &DefaultCodeMetricsService{
+		metricsRepo:  metricsRepo,
+		codebaseRepo: codebaseRepo,
+	}
+}
+
+// RecordSnapshot measures the clone of an analysis task's codebase in dir and stores the snapshot of its commit.
+// Tasks not pinned to a commit are measured at the commit the clone checked out.
+func (s *DefaultCodeMetricsService) RecordSnapshot(ctx context.Context, task *models.TaskWithFullContext, dir string) (*models.CodeMetricsSnapshot, error) {
+	if task.CodebaseID == nil {
+		return nil, fmt.Errorf("code metrics require a codebase")
+	}
+
+	commitSHA := ""
+	if task.CommitSHA != nil {
+		commitSHA = *task.CommitSHA
+	} else {
+		head, err := codebase.HeadCommit(dir)
+		if err != nil {
+			return nil, err
+		}
+		commitSHA = head
+	}
+
+	metrics, err := analyzer.ComputeMetrics(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &models.CodeMetricsSnapshot{
+		SnapshotID:            "metrics-" + uuid.New().String(),
+		CodebaseID:            *task.CodebaseID,
+		TaskID:                task.TaskID,
+		CommitSHA:             commitSHA,
+		Files:                 metrics.Files,
+		Functions:             metrics.Functions,
+		AverageComplexity:     metrics.AverageComplexity,
+		MaxComplexity:         metrics.MaxComplexity,
+		AverageFunctionLength: metrics.AverageFunctionLength,
+		MaxFunctionLength:     metrics.MaxFunctionLength,
+		AverageInstability:    metrics.AverageInstability,
+		MaintainabilityScore:  metrics.MaintainabilityScore,
+		Packages:              make([]models.PackageCouplingMetrics, len(metrics.Packages)),
+		Hotspots:              make([]models.FunctionComplexity, len(metrics.Hotspots)),
+		MeasuredAt:            time.Now(),
+	}
+	for i, pkg := range metrics.Packages {
+		snapshot.Packages[i] = models.PackageCouplingMetrics(pkg)
+	}
+	for i, fn := range metrics.Hotspots {
+		snapshot.Hotspots[i] = models.FunctionComplexity(fn)
+	}
+
+	if err := s.metricsRepo.SaveSnapshot(ctx, snapshot); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// GetMetrics retrieves the snapshots of a codebase since the requested day and how they changed over the period
+func (s *DefaultCodeMetricsService) GetMetrics(ctx context.Context, request models.GetCodeMetricsRequest) (*models.GetCodeMetricsResponse, error) {
+	var since time.Time
+	if request.Since != "" {
+		parsed, err := time.Parse(models.ReportDateLayout, request.Since)
+		if err != nil {
+			return nil, apperrors.Validation(apperrors.CodeInvalidRequest, "invalid since date: %s", request.Since)
+		}
+		since = parsed
+	}
+
+	if _, err := s.codebaseRepo.GetCodebase(ctx, request.CodebaseID); err != nil {
+		return nil, fmt.Errorf("failed to get codebase: %w", err)
+	}
+
+	snapshots, err := s.metricsRepo.ListSnapshots(ctx, request.CodebaseID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.GetCodeMetricsResponse{Snapshots: snapshots}
+	if len(snapshots) >= 2 {
+		first, latest := snapshots[0], snapshots[len(snapshots)-1]
+		response.Trend = &models.CodeMetricsTrend{
+			MaintainabilityScore:  roundMetric(latest.MaintainabilityScore - first.MaintainabilityScore),
+			AverageComplexity:     roundMetric(latest.AverageComplexity - first.AverageComplexity),
+			AverageFunctionLength: roundMetric(latest.AverageFunctionLength - first.AverageFunctionLength),
+			AverageInstability:    roundMetric(latest.AverageInstability - first.AverageInstability),
+		}
+	}
+
+	return response, nil
+}
+
+// roundMetric rounds a metric change to two decimals, dropping floating point noise
+func roundMetric(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+)
+
+func TestCodeMetricsService_RecordSnapshot_UsesPinnedCommit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	metricsRepo := repositoryMocks.NewMockCodeMetricsRepository(ctrl)
+	service := NewDefaultCodeMetricsService(metricsRepo, repositoryMocks.NewMockCodebaseRepository(ctrl))
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {\n\tif true {\n\t}\n}\n"), 0o600))
+
+	codebaseID := "cb-1"
+	commitSHA := "9fceb02d0ae598e95dc970b74767f19372d61af8"
+	task := &models.TaskWithFullContext{
+		Task: models.Task{TaskID: "task-1", CodebaseID: &codebaseID, CommitSHA: &commitSHA},
+	}
+
+	metricsRepo.EXPECT().SaveSnapshot(gomock.Any(), gomock.Any()).Return(nil)
+
+	snapshot, err := service.RecordSnapshot(context.Background(), task, dir)
+
+	require.NoError(t, err)
+	assert.Equal(t, commitSHA, snapshot.CommitSHA)
+	assert.Equal(t, "task-1", snapshot.TaskID)
+	assert.Equal(t, 1, snapshot.Functions)
+	assert.Equal(t, 2, snapshot.MaxComplexity)
+	require.Len(t, snapshot.Hotspots, 1)
+	assert.Equal(t, "main", snapshot.Hotspots[0].Name)
+}
+
+func TestCodeMetricsService_GetMetrics_ComputesTrend(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	metricsRepo := repositoryMocks.NewMockCodeMetricsRepository(ctrl)
+	codebaseRepo := repositoryMocks.NewMockCodebaseRepository(ctrl)
+	service := NewDefaultCodeMetricsService(metricsRepo, codebaseRepo)
+
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	codebaseRepo.EXPECT().GetCodebase(gomock.Any(), "cb-1").Return(&models.Codebase{CodebaseID: "cb-1"}, nil)
+	metricsRepo.EXPECT().
+		ListSnapshots(gomock.Any(), "cb-1", since).
+		Return([]models.CodeMetricsSnapshot{
+			{CommitSHA: "a", MaintainabilityScore: 88.1, AverageComplexity: 3.7, AverageFunctionLength: 16, AverageInstability: 0.5},
+			{CommitSHA: "b", MaintainabilityScore: 90, AverageComplexity: 3.5, AverageFunctionLength: 15.2, AverageInstability: 0.5},
+			{CommitSHA: "c", MaintainabilityScore: 92.3, AverageComplexity: 3.4, AverageFunctionLength: 14.2, AverageInstability: 0.52},
+		}, nil)
+
+	response, err := service.GetMetrics(context.Background(), models.GetCodeMetricsRequest{CodebaseID: "cb-1", Since: "2024-01-01"})
+
+	require.NoError(t, err)
+	assert.Len(t, response.Snapshots, 3)
+	assert.Equal(t, &models.CodeMetricsTrend{
+		MaintainabilityScore:  4.2,
+		AverageComplexity:     -0.3,
+		AverageFunctionLength: -1.8,
+		AverageInstability:    0.02,
+	}, response.Trend)
+}
+
+func TestCodeMetricsService_GetMetrics_SingleSnapshotHasNoTrend(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	metricsRepo := repositoryMocks.NewMockCodeMetricsRepository(ctrl)
+	codebaseRepo := repositoryMocks.NewMockCodebaseRepository(ctrl)
+	service := NewDefaultCodeMetricsService(metricsRepo, codebaseRepo)
+
+	codebaseRepo.EXPECT().GetCodebase(gomock.Any(), "cb-1").Return(&models.Codebase{CodebaseID: "cb-1"}, nil)
+	metricsRepo.EXPECT().ListSnapshots(gomock.Any(), "cb-1", time.Time{}).Return([]models.CodeMetricsSnapshot{{CommitSHA: "a"}}, nil)
+
+	response, err := service.GetMetrics(context.Background(), models.GetCodeMetricsRequest{CodebaseID: "cb-1"})
+
+	require.NoError(t, err)
+	assert.Len(t, response.Snapshots, 1)
+	assert.Nil(t, response.Trend)
+}
+
+func TestCodeMetricsService_GetMetrics_CodebaseNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	codebaseRepo := repositoryMocks.NewMockCodebaseRepository(ctrl)
+	service := NewDefaultCodeMetricsService(repositoryMocks.NewMockCodeMetricsRepository(ctrl), codebaseRepo)
+
+	codebaseRepo.EXPECT().
+		GetCodebase(gomock.Any(), "missing").
+		Return(nil, apperrors.NotFound(apperrors.CodeCodebaseNotFound, "codebase not found"))
+
+	_, err := service.GetMetrics(context.Background(), models.GetCodeMetricsRequest{CodebaseID: "missing"})
+
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
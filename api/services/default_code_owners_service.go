@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/codeowners"
+)
+
+// DefaultCodeOwnersService is the default implementation of CodeOwnersService
+type DefaultCodeOwnersService struct {
+	ownersRepo   repository.CodeOwnersRepository
+	codebaseRepo repository.CodebaseRepository
+	now          func() time.Time
+}
+
+// NewDefaultCodeOwnersService creates a new DefaultCodeOwnersService
+func NewDefaultCodeOwnersService(
+	ownersRepo repository.CodeOwnersRepository,
+	codebaseRepo repository.CodebaseRepository,
+) *DefaultCodeOwnersService {
+	return &DefaultCodeOwnersService{
+		ownersRepo:   ownersRepo,
+		codebaseRepo: codebaseRepo,
+		now:          time.Now,
+	}
+}
+
+// RecordOwners parses the CODEOWNERS file of a codebase checked out at dir, looked up where GitHub and GitLab look it
+// up, and replaces its recorded ownership
+func (s *DefaultCodeOwnersService) RecordOwners(ctx context.Context, codebaseID, dir, commitSHA string) (*models.CodeOwners, error) {
+	file, path, err := codeowners.Find(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CODEOWNERS: %w", err)
+	}
+
+	owners := &models.CodeOwners{
+		CodebaseID: codebaseID,
+		FilePath:   path,
+		CommitSHA:  commitSHA,
+		Rules:      []models.CodeOwnerRule{},
+		UpdatedAt:  s.now().UTC(),
+	}
+	if file != nil {
+		for _, rule := range file.Rules() {
+			owners.Rules = append(owners.Rules, models.CodeOwnerRule{Pattern: rule.Pattern, Owners: rule.Owners, Section: rule.Section})
+		}
+	}
+
+	if err := s.ownersRepo.SaveCodeOwners(ctx, owners); err != nil {
+		return nil, err
+	}
+
+	return owners, nil
+}
+
+// GetOwners returns the recorded ownership of a codebase's files, failing with not found until an ingestion scan or
+// static analysis has recorded it
+func (s *DefaultCodeOwnersService) GetOwners(ctx context.Context, request models.GetCodeOwnersRequest) (*models.GetCodeOwnersResponse, error) {
+	if _, err := s.codebaseRepo.GetCodebase(ctx, request.CodebaseID); err != nil {
+		return nil, fmt.Errorf("failed to get codebase: %w", err)
+	}
+
+	owners, err := s.ownersRepo.GetCodeOwners(ctx, request.CodebaseID)
+	if err != nil {
+		return nil, err
+	}
+	if owners == nil {
+		return nil, apperrors.NotFound(apperrors.CodeCodeOwnersNotFound, "codebase %s has no recorded code owners", request.CodebaseID)
+	}
+
+	response := &models.GetCodeOwnersResponse{CodeOwners: *owners}
+	if request.Path != "" {
+		response.PathOwners = compileCodeOwners(owners).Owners(request.Path)
+	}
+
+	return response, nil
+}
+
+// ResolveOwners returns the sorted owners of any of the paths of a codebase
+func (s *DefaultCodeOwnersService) ResolveOwners(ctx context.Context, codebaseID string, paths []string) ([]string, error) {
+	file, err := loadCodeOwners(ctx, s.ownersRepo, codebaseID)
+	if err != nil || file == nil {
+		return nil, err
+	}
+
+	return file.OwnersOf(paths), nil
+}
+
+// loadCodeOwners returns the recorded CODEOWNERS rules of a codebase, nil if none are recorded
+func loadCodeOwners(ctx context.Context, ownersRepo repository.CodeOwnersRepository, codebaseID string) (*codeowners.File, error) {
+	owners, err := ownersRepo.GetCodeOwners(ctx, codebaseID)
+	if err != nil || owners == nil {
+		return nil, err
+	}
+
+	return compileCodeOwners(owners), nil
+}
+
+// compileCodeOwners compiles the stored rules of a codebase's ownership
+func compileCodeOwners(owners *models.CodeOwners) *codeowners.File {
+	rules := make([]codeowners.Rule, len(owners.Rules))
+	for i, rule := range owners.Rules {
+		rules[i] = codeowners.Rule{Pattern: rule.Pattern, Owners: rule.Owners, Section: rule.Section}
+	}
+	return codeowners.Compile(rules)
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+)
+
+var codeOwnersNow = time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+func newTestCodeOwnersService(ctrl *gomock.Controller) (*DefaultCodeOwnersService, *repositoryMocks.MockCodeOwnersRepository, *repositoryMocks.MockCodebaseRepository) {
+	ownersRepo := repositoryMocks.NewMockCodeOwnersRepository(ctrl)
+	codebaseRepo := repositoryMocks.NewMockCodebaseRepository(ctrl)
+	service := NewDefaultCodeOwnersService(ownersRepo, codebaseRepo)
+	service.now = func() time.Time { return codeOwnersNow }
+	return service, ownersRepo, codebaseRepo
+}
+
+func TestCodeOwnersService_RecordOwners(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service, ownersRepo, _ := newTestCodeOwnersService(ctrl)
+
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".gitlab"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".gitlab", "CODEOWNERS"), []byte("[Backend] @acme/backend\n/billing/\n*.md @acme/writers\n"), 0o600))
+
+	ownersRepo.EXPECT().SaveCodeOwners(gomock.Any(), &models.CodeOwners{
+		CodebaseID: "cb-1",
+		FilePath:   ".gitlab/CODEOWNERS",
+		CommitSHA:  "abc123",
+		Rules: []models.CodeOwnerRule{
+			{Pattern: "/billing/", Owners: []string{"@acme/backend"}, Section: "Backend"},
+			{Pattern: "*.md", Owners: []string{"@acme/writers"}, Section: "Backend"},
+		},
+		UpdatedAt: codeOwnersNow,
+	}).Return(nil)
+
+	_, err := service.RecordOwners(context.Background(), "cb-1", dir, "abc123")
+
+	require.NoError(t, err)
+}
+
+func TestCodeOwnersService_RecordOwners_NoFile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service, ownersRepo, _ := newTestCodeOwnersService(ctrl)
+
+	ownersRepo.EXPECT().SaveCodeOwners(gomock.Any(), gomock.Any()).Return(nil)
+
+	owners, err := service.RecordOwners(context.Background(), "cb-1", t.TempDir(), "")
+
+	require.NoError(t, err)
+	assert.Empty(t, owners.FilePath)
+	assert.Equal(t, []models.CodeOwnerRule{}, owners.Rules)
+}
+
+func TestCodeOwnersService_GetOwners_ResolvesPath(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service, ownersRepo, codebaseRepo := newTestCodeOwnersService(ctrl)
+
+	codebaseRepo.EXPECT().GetCodebase(gomock.Any(), "cb-1").Return(&models.Codebase{CodebaseID: "cb-1"}, nil)
+	ownersRepo.EXPECT().GetCodeOwners(gomock.Any(), "cb-1").Return(&models.CodeOwners{CodebaseID: "cb-1", Rules: []models.CodeOwnerRule{
+		{Pattern: "*", Owners: []string{"@acme/core"}},
+		{Pattern: "/billing/", Owners: []string{"@acme/billing"}},
+	}}, nil)
+
+	response, err := service.GetOwners(context.Background(), models.GetCodeOwnersRequest{CodebaseID: "cb-1", Path: "billing/invoice.go"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"@acme/billing"}, response.PathOwners)
+}
+
+func TestCodeOwnersService_GetOwners_NotRecorded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service, ownersRepo, codebaseRepo := newTestCodeOwnersService(ctrl)
+
+	codebaseRepo.EXPECT().GetCodebase(gomock.Any(), "cb-1").Return(&models.Codebase{CodebaseID: "cb-1"}, nil)
+	ownersRepo.EXPECT().GetCodeOwners(gomock.Any(), "cb-1").Return(nil, nil)
+
+	_, err := service.GetOwners(context.Background(), models.GetCodeOwnersRequest{CodebaseID: "cb-1"})
+
+	assert.True(t, errors.Is(err, apperrors.ErrNotFound))
+	assert.Equal(t, apperrors.CodeCodeOwnersNotFound, apperrors.CodeOf(err))
+}
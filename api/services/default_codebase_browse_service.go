@@ -0,0 +1,361 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/gitprovider"
+)
+
+// DefaultCodebaseBrowseService is the default implementation of CodebaseBrowseService.
+// Listings are cached per codebase for cacheTTL to keep clients from exhausting the provider's rate limits.
+type DefaultCodebaseBrowseService struct {
+	codebaseRepo repository.CodebaseRepository
+	configRepo   repository.CodebaseConfigRepository
+	browsers     map[models.Provider]gitprovider.Browser
+	appTokens    gitprovider.InstallationTokenSource
+	cacheTTL     time.Duration
+	now          func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]browseCacheEntry
+}
+
+// browseCacheEntry is a cached listing response
+type browseCacheEntry struct {
+	value     any
+	expiresAt time.Time
+}
+
+// NewDefaultCodebaseBrowseService creates a new DefaultCodebaseBrowseService browsing codebases of the providers in
+// browsers. Codebases authenticating as a GitHub App installation read with the tokens of appTokens.
+func NewDefaultCodebaseBrowseService(
+	codebaseRepo repository.CodebaseRepository,
+	configRepo repository.CodebaseConfigRepository,
+	browsers map[models.Provider]gitprovider.Browser,
+	appTokens gitprovider.InstallationTokenSource,
+	cacheTTL time.Duration,
+) *DefaultCodebaseBrowseService {
+	return &DefaultCodebaseBrowseService{
+		codebaseRepo: codebaseRepo,
+		configRepo:   configRepo,
+		browsers:     browsers,
+		appTokens:    appTokens,
+		cacheTTL:     cacheTTL,
+		now:          time.Now,
+		cache:        make(map[string]browseCacheEntry),
+	}
+}
+
+// ListBranches lists the branches of the codebase's repository
+func (s *DefaultCodebaseBrowseService) ListBranches(ctx context.Context, request models.ListCodebaseBranchesRequest) (*models.ListCodebaseBranchesResponse, error) {
+	key := fmt.Sprintf("%s|branches", request.CodebaseID)
+	if cached, ok := s.cached(key); ok {
+		return cached.(*models.ListCodebaseBranchesResponse), nil
+	}
+
+	target, err := s.resolve(ctx, request.CodebaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	branches, err := target.browser.ListBranches(ctx, target.repo)
+	if err != nil {
+		return nil, providerError(err, "failed to list branches of codebase %s", request.CodebaseID)
+	}
+
+	response := &models.ListCodebaseBranchesResponse{
+		Branches:      make([]models.CodebaseBranch, 0, len(branches)),
+		DefaultBranch: target.defaultBranch,
+	}
+	for _, branch := range branches {
+		response.Branches = append(response.Branches, models.CodebaseBranch{
+			Name:      branch.Name,
+			CommitSHA: branch.CommitSHA,
+			Protected: branch.Protected,
+		})
+	}
+
+	s.store(key, response)
+	return response, nil
+}
+
+// ListCommits lists the latest commits of a ref of the codebase's repository
+func (s *DefaultCodebaseBrowseService) ListCommits(ctx context.Context, request models.ListCodebaseCommitsRequest) (*models.ListCodebaseCommitsResponse, error) {
+	limit := models.DefaultCodebaseCommitsLimit
+	if request.Limit != nil {
+		limit = *request.Limit
+	}
+
+	key := fmt.Sprintf("%s|commits|%s|%d", request.CodebaseID, request.Ref, limit)
+	if cached, ok := s.cached(key); ok {
+		return cached.(*models.ListCodebaseCommitsResponse), nil
+	}
+
+	target, err := s.resolve(ctx, request.CodebaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	commits, err := target.browser.ListCommits(ctx, target.repo, target.ref(request.Ref), limit)
+	if err != nil {
+		return nil, providerError(err, "failed to list commits of codebase %s", request.CodebaseID)
+	}
+
+	response := &models.ListCodebaseCommitsResponse{Commits: make([]models.CodebaseCommit, 0, len(commits))}
+	for _, commit := range commits {
+		response.Commits = append(response.Commits, models.CodebaseCommit{
+			SHA:        commit.SHA,
+			Message:    commit.Message,
+			AuthorName: commit.AuthorName,
+			AuthoredAt: commit.AuthoredAt.UTC().Format(time.RFC3339),
+			URL:        commit.URL,
+		})
+	}
+
+	s.store(key, response)
+	return response, nil
+}
+
+// ListFiles lists a directory of the codebase's repository
+func (s *DefaultCodebaseBrowseService) ListFiles(ctx context.Context, request models.ListCodebaseFilesRequest) (*models.ListCodebaseFilesResponse, error) {
+	path := strings.Trim(request.Path, "/")
+
+	key := fmt.Sprintf("%s|files|%s|%s", request.CodebaseID, request.Ref, path)
+	if cached, ok := s.cached(key); ok {
+		return cached.(*models.ListCodebaseFilesResponse), nil
+	}
+
+	target, err := s.resolve(ctx, request.CodebaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := target.browser.ListFiles(ctx, target.repo, target.ref(request.Ref), path)
+	if err != nil {
+		return nil, providerError(err, "failed to list files of codebase %s", request.CodebaseID)
+	}
+
+	response := &models.ListCodebaseFilesResponse{Files: make([]models.CodebaseFile, 0, len(files))}
+	for _, file := range files {
+		response.Files = append(response.Files, models.CodebaseFile{
+			Name: file.Name,
+			Path: file.Path,
+			Type: string(file.Type),
+		})
+	}
+
+	s.store(key, response)
+	return response, nil
+}
+
+// ResolveRevision checks that branch and commitSHA exist in the codebase's repository and resolves them to a full commit SHA.
+// Revisions are never served from cache so that a task pins the branch head at the time it is created.
+func (s *DefaultCodebaseBrowseService) ResolveRevision(ctx context.Context, codebaseID, branch, commitSHA string) (*models.CodebaseRevision, error) {
+	target, err := s.resolve(ctx, codebaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	revision := &models.CodebaseRevision{Branch: branch}
+
+	if branch != "" {
+		head, err := target.browser.GetBranch(ctx, target.repo, branch)
+		if err != nil {
+			if errors.Is(err, gitprovider.ErrNotFound) {
+				return nil, apperrors.Validation(apperrors.CodeGitRefNotFound, "branch %s not found in codebase %s", branch, codebaseID)
+			}
+			return nil, providerError(err, "failed to get branch %s of codebase %s", branch, codebaseID)
+		}
+		revision.CommitSHA = head.CommitSHA
+	}
+
+	if commitSHA != "" {
+		// Also expands abbreviated SHAs
+		commit, err := target.browser.GetCommit(ctx, target.repo, commitSHA)
+		if err != nil {
+			if errors.Is(err, gitprovider.ErrNotFound) {
+				return nil, apperrors.Validation(apperrors.CodeGitRefNotFound, "commit %s not found in codebase %s", commitSHA, codebaseID)
+			}
+			return nil, providerError(err, "failed to get commit %s of codebase %s", commitSHA, codebaseID)
+		}
+		revision.CommitSHA = commit.SHA
+	}
+
+	return revision, nil
+}
+
+// GetDefaultBranchHead resolves the current head of the codebase's default branch. Like revisions, heads are never
+// served from cache.
+func (s *DefaultCodebaseBrowseService) GetDefaultBranchHead(ctx context.Context, codebaseID string) (*models.CodebaseRevision, error) {
+	target, err := s.resolve(ctx, codebaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	commits, err := target.browser.ListCommits(ctx, target.repo, target.defaultBranch, 1)
+	if err != nil {
+		return nil, providerError(err, "failed to get the default branch head of codebase %s", codebaseID)
+	}
+	if len(commits) == 0 {
+		return nil, apperrors.Validation(apperrors.CodeGitRefNotFound, "default branch of codebase %s has no commits", codebaseID)
+	}
+
+	return &models.CodebaseRevision{Branch: target.defaultBranch, CommitSHA: commits[0].SHA}, nil
+}
+
+// browseTarget is a codebase's repository resolved to the browser and credentials reading it
+type browseTarget struct {
+	browser       gitprovider.Browser
+	repo          gitprovider.Repository
+	defaultBranch string
+}
+
+// ref returns the requested ref, falling back to the configured default branch
+func (t *browseTarget) ref(requested string) string {
+	if requested != "" {
+		return requested
+	}
+	return t.defaultBranch
+}
+
+// resolve loads the codebase and its configuration and picks the browser of its provider
+func (s *DefaultCodebaseBrowseService) resolve(ctx context.Context, codebaseID string) (*browseTarget, error) {
+	codebase, err := s.codebaseRepo.GetCodebase(ctx, codebaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get codebase: %w", err)
+	}
+
+	browser, ok := s.browsers[codebase.Provider]
+	if !ok {
+		return nil, apperrors.Validation(apperrors.CodeInvalidProvider, "browsing %s codebases is not supported", codebase.Provider)
+	}
+
+	record, err := s.configRepo.GetCodebaseConfig(ctx, codebase.ConfigID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get codebase configuration: %w", err)
+	}
+
+	target := &browseTarget{browser: browser}
+	baseURL, path := parseRepositoryURL(codebase.URL)
+
+	switch codebase.Provider {
+	case models.ProviderGitHub:
+		if baseURL == "https://github.com" {
+			baseURL = ""
+		}
+		if github := record.Config.GitHub; github != nil {
+			if github.Owner != "" && github.Repository != "" {
+				path = github.Owner + "/" + github.Repository
+			}
+			target.repo.Token = github.Token
+			target.defaultBranch = github.DefaultBranch
+
+			if record.Config.AuthType == models.GitAuthTypeGitHubApp {
+				app := gitprovider.GitHubApp{AppID: github.AppID, InstallationID: github.InstallationID, PrivateKey: github.PrivateKey}
+				if baseURL != "" {
+					app.APIURL = gitprovider.GitHubEnterpriseAPIURL(baseURL)
+				}
+				token, err := s.appTokens.InstallationToken(ctx, app)
+				if err != nil {
+					return nil, providerError(err, "failed to authenticate as the GitHub App installation of codebase %s", codebaseID)
+				}
+				target.repo.Token = token
+			}
+		}
+	case models.ProviderGitLab:
+		if baseURL == "https://gitlab.com" {
+			baseURL = ""
+		}
+		if gitlab := record.Config.GitLab; gitlab != nil {
+			if gitlab.ProjectID != "" {
+				path = gitlab.ProjectID
+			}
+			if gitlab.BaseURL != "" {
+				baseURL = gitlab.BaseURL
+			}
+			target.repo.Token = gitlab.Token
+			target.defaultBranch = gitlab.DefaultBranch
+		}
+	case models.ProviderAzureDevOps:
+		if baseURL == gitprovider.AzureDevOpsURL {
+			baseURL = ""
+		}
+		// Web URLs are organization/project/_git/repository
+		path = strings.Replace(path, "/_git/", "/", 1)
+		if azure := record.Config.AzureDevOps; azure != nil {
+			if azure.Organization != "" && azure.Project != "" && azure.Repository != "" {
+				path = azure.Organization + "/" + azure.Project + "/" + azure.Repository
+			}
+			if azure.BaseURL != "" {
+				baseURL = azure.BaseURL
+			}
+			target.repo.Token = azure.Token
+			target.defaultBranch = azure.DefaultBranch
+		}
+	}
+
+	if path == "" {
+		return nil, apperrors.Validation(apperrors.CodeInvalidRequest, "codebase %s does not identify a repository", codebaseID)
+	}
+	target.repo.BaseURL = baseURL
+	target.repo.Path = path
+
+	return target, nil
+}
+
+// parseRepositoryURL splits a repository URL into the web URL of its host and the repository path
+func parseRepositoryURL(rawURL string) (string, string) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return "", ""
+	}
+	path := strings.TrimSuffix(strings.Trim(parsed.Path, "/"), ".git")
+	return parsed.Scheme + "://" + parsed.Host, path
+}
+
+// providerError maps a git provider failure to the error returned to clients
+func providerError(err error, format string, args ...any) error {
+	if errors.Is(err, gitprovider.ErrNotFound) {
+		return apperrors.NotFound(apperrors.CodeGitRefNotFound, "repository, ref or path not found on the git provider")
+	}
+	return apperrors.BadGateway(apperrors.CodeBadGateway, err, format, args...)
+}
+
+// cached returns the cached listing stored under key unless it expired
+func (s *DefaultCodebaseBrowseService) cached(key string) (any, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.cache[key]
+	if !ok || !s.now().Before(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.value, true
+}
+
+// store caches a listing under key, dropping the expired entries
+func (s *DefaultCodebaseBrowseService) store(key string, value any) {
+	if s.cacheTTL <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for cachedKey, entry := range s.cache {
+		if !now.Before(entry.expiresAt) {
+			delete(s.cache, cachedKey)
+		}
+	}
+	s.cache[key] = browseCacheEntry{value: value, expiresAt: now.Add(s.cacheTTL)}
+}
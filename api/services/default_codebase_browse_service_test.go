@@ -0,0 +1,273 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/gitprovider"
+	gitproviderMocks "github.com/kazemisoroush/code-refactoring-tool/pkg/gitprovider/mocks"
+)
+
+func TestDefaultCodebaseBrowseService_ListBranches_Cached(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	codebaseRepo := repositoryMocks.NewMockCodebaseRepository(ctrl)
+	configRepo := repositoryMocks.NewMockCodebaseConfigRepository(ctrl)
+	browser := gitproviderMocks.NewMockBrowser(ctrl)
+	service := NewDefaultCodebaseBrowseService(codebaseRepo, configRepo, map[models.Provider]gitprovider.Browser{models.ProviderGitHub: browser}, nil, time.Minute)
+
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	codebaseRepo.EXPECT().GetCodebase(gomock.Any(), "cb-1").Return(&models.Codebase{
+		CodebaseID: "cb-1", Provider: models.ProviderGitHub, URL: "https://github.com/acme/payments.git", ConfigID: "config-1",
+	}, nil).Times(2)
+	configRepo.EXPECT().GetCodebaseConfig(gomock.Any(), "config-1").Return(&repository.CodebaseConfigRecord{
+		Config: models.GitProviderConfig{GitHub: &models.GitHubConfig{Token: "ghp-token", DefaultBranch: "main"}},
+	}, nil).Times(2)
+	browser.EXPECT().
+		ListBranches(gomock.Any(), gitprovider.Repository{Path: "acme/payments", Token: "ghp-token"}).
+		Return([]gitprovider.Branch{{Name: "main", CommitSHA: "abc123"}}, nil).
+		Times(2)
+
+	request := models.ListCodebaseBranchesRequest{CodebaseID: "cb-1"}
+	first, err := service.ListBranches(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, "main", first.DefaultBranch)
+	assert.Equal(t, []models.CodebaseBranch{{Name: "main", CommitSHA: "abc123"}}, first.Branches)
+
+	// Served from cache until the TTL passes
+	second, err := service.ListBranches(context.Background(), request)
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+
+	now = now.Add(time.Minute)
+	_, err = service.ListBranches(context.Background(), request)
+	require.NoError(t, err)
+}
+
+func TestDefaultCodebaseBrowseService_ListBranches_GitHubApp(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	codebaseRepo := repositoryMocks.NewMockCodebaseRepository(ctrl)
+	configRepo := repositoryMocks.NewMockCodebaseConfigRepository(ctrl)
+	browser := gitproviderMocks.NewMockBrowser(ctrl)
+	appTokens := gitproviderMocks.NewMockInstallationTokenSource(ctrl)
+	service := NewDefaultCodebaseBrowseService(codebaseRepo, configRepo, map[models.Provider]gitprovider.Browser{models.ProviderGitHub: browser}, appTokens, time.Minute)
+
+	codebaseRepo.EXPECT().GetCodebase(gomock.Any(), "cb-1").Return(&models.Codebase{
+		CodebaseID: "cb-1", Provider: models.ProviderGitHub, URL: "https://github.example.com/acme/payments.git", ConfigID: "config-1",
+	}, nil)
+	configRepo.EXPECT().GetCodebaseConfig(gomock.Any(), "config-1").Return(&repository.CodebaseConfigRecord{
+		Config: models.GitProviderConfig{
+			AuthType: models.GitAuthTypeGitHubApp,
+			GitHub:   &models.GitHubConfig{AppID: 7, InstallationID: 42, PrivateKey: "key"},
+		},
+	}, nil)
+	appTokens.EXPECT().
+		InstallationToken(gomock.Any(), gitprovider.GitHubApp{APIURL: "https://github.example.com/api/v3", AppID: 7, InstallationID: 42, PrivateKey: "key"}).
+		Return("ghs-installation", nil)
+	browser.EXPECT().
+		ListBranches(gomock.Any(), gitprovider.Repository{BaseURL: "https://github.example.com", Path: "acme/payments", Token: "ghs-installation"}).
+		Return([]gitprovider.Branch{{Name: "main", CommitSHA: "abc123"}}, nil)
+
+	response, err := service.ListBranches(context.Background(), models.ListCodebaseBranchesRequest{CodebaseID: "cb-1"})
+
+	require.NoError(t, err)
+	assert.Len(t, response.Branches, 1)
+}
+
+func TestDefaultCodebaseBrowseService_ListCommits_GitLab(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	codebaseRepo := repositoryMocks.NewMockCodebaseRepository(ctrl)
+	configRepo := repositoryMocks.NewMockCodebaseConfigRepository(ctrl)
+	browser := gitproviderMocks.NewMockBrowser(ctrl)
+	service := NewDefaultCodebaseBrowseService(codebaseRepo, configRepo, map[models.Provider]gitprovider.Browser{models.ProviderGitLab: browser}, nil, time.Minute)
+
+	codebaseRepo.EXPECT().GetCodebase(gomock.Any(), "cb-1").Return(&models.Codebase{
+		CodebaseID: "cb-1", Provider: models.ProviderGitLab, URL: "https://gitlab.example.com/acme/payments", ConfigID: "config-1",
+	}, nil)
+	configRepo.EXPECT().GetCodebaseConfig(gomock.Any(), "config-1").Return(&repository.CodebaseConfigRecord{
+		Config: models.GitProviderConfig{GitLab: &models.GitLabConfig{Token: "glpat-token", ProjectID: "42", DefaultBranch: "develop"}},
+	}, nil)
+	browser.EXPECT().
+		ListCommits(gomock.Any(), gitprovider.Repository{BaseURL: "https://gitlab.example.com", Path: "42", Token: "glpat-token"}, "develop", models.DefaultCodebaseCommitsLimit).
+		Return([]gitprovider.Commit{{SHA: "abc123", AuthoredAt: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)}}, nil)
+
+	response, err := service.ListCommits(context.Background(), models.ListCodebaseCommitsRequest{CodebaseID: "cb-1"})
+
+	require.NoError(t, err)
+	require.Len(t, response.Commits, 1)
+	assert.Equal(t, "2024-01-15T10:30:00Z", response.Commits[0].AuthoredAt)
+}
+
+func TestDefaultCodebaseBrowseService_ListBranches_AzureDevOps(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	codebaseRepo := repositoryMocks.NewMockCodebaseRepository(ctrl)
+	configRepo := repositoryMocks.NewMockCodebaseConfigRepository(ctrl)
+	browser := gitproviderMocks.NewMockBrowser(ctrl)
+	service := NewDefaultCodebaseBrowseService(codebaseRepo, configRepo, map[models.Provider]gitprovider.Browser{models.ProviderAzureDevOps: browser}, nil, time.Minute)
+
+	codebaseRepo.EXPECT().GetCodebase(gomock.Any(), "cb-1").Return(&models.Codebase{
+		CodebaseID: "cb-1", Provider: models.ProviderAzureDevOps, URL: "https://dev.azure.com/acme/billing/_git/payments", ConfigID: "config-1",
+	}, nil)
+	configRepo.EXPECT().GetCodebaseConfig(gomock.Any(), "config-1").Return(&repository.CodebaseConfigRecord{
+		Config: models.GitProviderConfig{AzureDevOps: &models.AzureDevOpsConfig{Token: "pat-token"}},
+	}, nil)
+	// The repository path is taken from the web URL without its _git segment
+	browser.EXPECT().
+		ListBranches(gomock.Any(), gitprovider.Repository{Path: "acme/billing/payments", Token: "pat-token"}).
+		Return([]gitprovider.Branch{{Name: "main", CommitSHA: "abc123"}}, nil)
+
+	response, err := service.ListBranches(context.Background(), models.ListCodebaseBranchesRequest{CodebaseID: "cb-1"})
+
+	require.NoError(t, err)
+	assert.Len(t, response.Branches, 1)
+}
+
+func TestDefaultCodebaseBrowseService_ListFiles_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		provider    models.Provider
+		browserErr  error
+		expectedErr error
+	}{
+		{name: "unsupported_provider", provider: models.ProviderBitbucket, expectedErr: apperrors.ErrValidation},
+		{name: "not_found", provider: models.ProviderGitHub, browserErr: gitprovider.ErrNotFound, expectedErr: apperrors.ErrNotFound},
+		{name: "provider_failure", provider: models.ProviderGitHub, browserErr: errors.New("git provider returned status 500"), expectedErr: apperrors.ErrBadGateway},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			codebaseRepo := repositoryMocks.NewMockCodebaseRepository(ctrl)
+			configRepo := repositoryMocks.NewMockCodebaseConfigRepository(ctrl)
+			browser := gitproviderMocks.NewMockBrowser(ctrl)
+			service := NewDefaultCodebaseBrowseService(codebaseRepo, configRepo, map[models.Provider]gitprovider.Browser{models.ProviderGitHub: browser}, nil, time.Minute)
+
+			codebaseRepo.EXPECT().GetCodebase(gomock.Any(), "cb-1").Return(&models.Codebase{
+				CodebaseID: "cb-1", Provider: tt.provider, URL: "https://github.com/acme/payments", ConfigID: "config-1",
+			}, nil)
+			if tt.browserErr != nil {
+				configRepo.EXPECT().GetCodebaseConfig(gomock.Any(), "config-1").Return(&repository.CodebaseConfigRecord{}, nil)
+				browser.EXPECT().ListFiles(gomock.Any(), gitprovider.Repository{Path: "acme/payments"}, "main", "cmd").Return(nil, tt.browserErr)
+			}
+
+			_, err := service.ListFiles(context.Background(), models.ListCodebaseFilesRequest{CodebaseID: "cb-1", Ref: "main", Path: "/cmd/"})
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestDefaultCodebaseBrowseService_ResolveRevision(t *testing.T) {
+	fullSHA := "9fceb02d0ae598e95dc970b74767f19372d61af8"
+	tests := []struct {
+		name        string
+		branch      string
+		commitSHA   string
+		setup       func(browser *gitproviderMocks.MockBrowser)
+		expected    *models.CodebaseRevision
+		expectedErr error
+	}{
+		{
+			name:   "branch_head",
+			branch: "feature/jwt-auth",
+			setup: func(browser *gitproviderMocks.MockBrowser) {
+				browser.EXPECT().GetBranch(gomock.Any(), gomock.Any(), "feature/jwt-auth").Return(&gitprovider.Branch{Name: "feature/jwt-auth", CommitSHA: fullSHA}, nil)
+			},
+			expected: &models.CodebaseRevision{Branch: "feature/jwt-auth", CommitSHA: fullSHA},
+		},
+		{
+			name:      "abbreviated_commit",
+			commitSHA: "9fceb02",
+			setup: func(browser *gitproviderMocks.MockBrowser) {
+				browser.EXPECT().GetCommit(gomock.Any(), gomock.Any(), "9fceb02").Return(&gitprovider.Commit{SHA: fullSHA}, nil)
+			},
+			expected: &models.CodebaseRevision{CommitSHA: fullSHA},
+		},
+		{
+			name:   "unknown_branch",
+			branch: "missing",
+			setup: func(browser *gitproviderMocks.MockBrowser) {
+				browser.EXPECT().GetBranch(gomock.Any(), gomock.Any(), "missing").Return(nil, gitprovider.ErrNotFound)
+			},
+			expectedErr: apperrors.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			codebaseRepo := repositoryMocks.NewMockCodebaseRepository(ctrl)
+			configRepo := repositoryMocks.NewMockCodebaseConfigRepository(ctrl)
+			browser := gitproviderMocks.NewMockBrowser(ctrl)
+			service := NewDefaultCodebaseBrowseService(codebaseRepo, configRepo, map[models.Provider]gitprovider.Browser{models.ProviderGitHub: browser}, nil, time.Minute)
+
+			codebaseRepo.EXPECT().GetCodebase(gomock.Any(), "cb-1").Return(&models.Codebase{
+				CodebaseID: "cb-1", Provider: models.ProviderGitHub, URL: "https://github.com/acme/payments", ConfigID: "config-1",
+			}, nil)
+			configRepo.EXPECT().GetCodebaseConfig(gomock.Any(), "config-1").Return(&repository.CodebaseConfigRecord{}, nil)
+			tt.setup(browser)
+
+			revision, err := service.ResolveRevision(context.Background(), "cb-1", tt.branch, tt.commitSHA)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, revision)
+		})
+	}
+}
+
+func TestDefaultCodebaseBrowseService_GetDefaultBranchHead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	codebaseRepo := repositoryMocks.NewMockCodebaseRepository(ctrl)
+	configRepo := repositoryMocks.NewMockCodebaseConfigRepository(ctrl)
+	browser := gitproviderMocks.NewMockBrowser(ctrl)
+	service := NewDefaultCodebaseBrowseService(codebaseRepo, configRepo, map[models.Provider]gitprovider.Browser{models.ProviderGitHub: browser}, nil, time.Minute)
+
+	codebaseRepo.EXPECT().GetCodebase(gomock.Any(), "cb-1").Return(&models.Codebase{
+		CodebaseID: "cb-1", Provider: models.ProviderGitHub, URL: "https://github.com/acme/payments", ConfigID: "config-1",
+	}, nil).Times(2)
+	configRepo.EXPECT().GetCodebaseConfig(gomock.Any(), "config-1").Return(&repository.CodebaseConfigRecord{
+		Config: models.GitProviderConfig{GitHub: &models.GitHubConfig{DefaultBranch: "main"}},
+	}, nil).Times(2)
+	gomock.InOrder(
+		browser.EXPECT().ListCommits(gomock.Any(), gomock.Any(), "main", 1).Return([]gitprovider.Commit{{SHA: "abc123"}}, nil),
+		browser.EXPECT().ListCommits(gomock.Any(), gomock.Any(), "main", 1).Return([]gitprovider.Commit{{SHA: "def456"}}, nil),
+	)
+
+	first, err := service.GetDefaultBranchHead(context.Background(), "cb-1")
+	require.NoError(t, err)
+	assert.Equal(t, &models.CodebaseRevision{Branch: "main", CommitSHA: "abc123"}, first)
+
+	// Never served from cache, so a new commit is seen right away
+	second, err := service.GetDefaultBranchHead(context.Background(), "cb-1")
+	require.NoError(t, err)
+	assert.Equal(t, "def456", second.CommitSHA)
+}
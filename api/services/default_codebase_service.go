@@ -4,27 +4,81 @@ package services
 import (
 	"context"
 	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
 	"github.com/kazemisoroush/code-refactoring-tool/api/models"
 	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/objectstore"
 )
 
 // DefaultCodebaseService is the default implementation of CodebaseService
 type DefaultCodebaseService struct {
 	codebaseRepo repository.CodebaseRepository
+	tagService   TagService
+	clientTokens repository.ClientTokenRepository
+	archives     objectstore.ObjectStore
 }
 
-// NewDefaultCodebaseService creates a new DefaultCodebaseService
-func NewDefaultCodebaseService(codebaseRepo repository.CodebaseRepository) *DefaultCodebaseService {
+// NewDefaultCodebaseService creates a new DefaultCodebaseService. The tag service governs the tags set on codebases,
+// the client tokens make retries of create requests return the codebase they created, and the archives of uploaded
+// codebases are deleted from the archives store along with them.
+func NewDefaultCodebaseService(codebaseRepo repository.CodebaseRepository, tagService TagService, clientTokens repository.ClientTokenRepository, archives objectstore.ObjectStore) *DefaultCodebaseService {
 	return &DefaultCodebaseService{
 		codebaseRepo: codebaseRepo,
+		tagService:   tagService,
+		clientTokens: clientTokens,
+		archives:     archives,
 	}
 }
 
-// CreateCodebase creates a new codebase with the given parameters
+// CreateCodebase creates a new codebase with the given parameters. A request carrying the client token of an earlier
+// request returns the codebase that request created.
 func (s *DefaultCodebaseService) CreateCodebase(ctx context.Context, request models.CreateCodebaseRequest) (*models.CreateCodebaseResponse, error) {
+	hashed := request
+	hashed.ClientToken = ""
+	claim, codebaseID, err := claimClientToken(ctx, s.clientTokens, clientTokenKindCodebase, request.UserID, request.ClientToken, hashed)
+	if err != nil {
+		return nil, err
+	}
+	if codebaseID != "" {
+		codebase, err := s.codebaseRepo.GetCodebase(ctx, codebaseID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get codebase: %w", err)
+		}
+		return &models.CreateCodebaseResponse{
+			CodebaseID: codebaseID,
+			CreatedAt:  codebase.CreatedAt.Format(time.RFC3339),
+		}, nil
+	}
+
+	response, err := s.createCodebase(ctx, request)
+	if response != nil {
+		codebaseID = response.CodebaseID
+	}
+	claim.finish(ctx, codebaseID, err)
+
+	return response, err
+}
+
+// createCodebase creates a new codebase
+func (s *DefaultCodebaseService) createCodebase(ctx context.Context, request models.CreateCodebaseRequest) (*models.CreateCodebaseResponse, error) {
+	if len(request.Tags) > 0 {
+		if err := s.tagService.AuthorizeTags(ctx, request.UserID, nil, request.Tags); err != nil {
+			return nil, err
+		}
+	}
+
+	checkout, err := normalizeCheckout(request.Provider, request.Checkout)
+	if err != nil {
+		return nil, err
+	}
+
 	// Generate a unique codebase ID
 	codebaseID := uuid.New().String()
 
@@ -41,6 +95,7 @@ func (s *DefaultCodebaseService) CreateCodebase(ctx context.Context, request mod
 		CreatedAt:  now,
 		UpdatedAt:  now,
 		Tags:       request.Tags,
+		Checkout:   checkout,
 	}
 
 	// Initialize empty metadata if nil
@@ -69,7 +124,12 @@ func (s *DefaultCodebaseService) GetCodebase(ctx context.Context, codebaseID str
 		return nil, fmt.Errorf("failed to get codebase: %w", err)
 	}
 
-	return &models.GetCodebaseResponse{
+	return newGetCodebaseResponse(codebase), nil
+}
+
+// newGetCodebaseResponse converts a codebase to its response
+func newGetCodebaseResponse(codebase *models.Codebase) *models.GetCodebaseResponse {
+	response := &models.GetCodebaseResponse{
 		CodebaseID: codebase.CodebaseID,
 		ProjectID:  codebase.ProjectID,
 		Name:       codebase.Name,
@@ -80,7 +140,16 @@ func (s *DefaultCodebaseService) GetCodebase(ctx context.Context, codebaseID str
 		UpdatedAt:  codebase.UpdatedAt.Format(time.RFC3339),
 		Metadata:   codebase.Metadata,
 		Tags:       codebase.Tags,
-	}, nil
+
+		Status:         codebase.Status,
+		IngestionError: codebase.IngestionError,
+		Checkout:       codebase.Checkout,
+	}
+	if codebase.ExpiresAt != nil {
+		expiresAt := codebase.ExpiresAt.Format(time.RFC3339)
+		response.ExpiresAt = &expiresAt
+	}
+	return response
 }
 
 // UpdateCodebase updates an existing codebase
@@ -99,11 +168,20 @@ func (s *DefaultCodebaseService) UpdateCodebase(ctx context.Context, request mod
 		codebase.ConfigID = *request.ConfigID
 	}
 	if request.Tags != nil {
+		if err := s.tagService.AuthorizeTags(ctx, request.UserID, codebase.Tags, request.Tags); err != nil {
+			return nil, err
+		}
 		codebase.Tags = request.Tags
 	}
 	if request.Metadata != nil {
 		codebase.Metadata = request.Metadata
 	}
+	if request.Checkout != nil {
+		codebase.Checkout, err = normalizeCheckout(codebase.Provider, request.Checkout)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	// Update the timestamp
 	codebase.UpdatedAt = time.Now().UTC()
@@ -119,11 +197,18 @@ func (s *DefaultCodebaseService) UpdateCodebase(ctx context.Context, request mod
 	}, nil
 }
 
-// DeleteCodebase deletes a codebase by ID
+// DeleteCodebase deletes a codebase by ID, and the archive of an uploaded codebase
 func (s *DefaultCodebaseService) DeleteCodebase(ctx context.Context, codebaseID string) (*models.DeleteCodebaseResponse, error) {
+	codebase, err := s.codebaseRepo.GetCodebase(ctx, codebaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete codebase: %w", err)
+	}
 	if err := s.codebaseRepo.DeleteCodebase(ctx, codebaseID); err != nil {
 		return nil, fmt.Errorf("failed to delete codebase: %w", err)
 	}
+	if codebase.Provider == models.ProviderUpload {
+		deleteCodebaseArchive(ctx, s.archives, codebase)
+	}
 
 	return &models.DeleteCodebaseResponse{
 		Success: true,
@@ -172,3 +257,30 @@ func (s *DefaultCodebaseService) ListCodebases(ctx context.Context, request mode
 
 	return response, nil
 }
+
+// normalizeCheckout validates the checkout options of a codebase, cleaning its sparse paths. Options checking out the
+// full history and tree are dropped. Archives of uploaded codebases have no history to shorten and are extracted whole.
+func normalizeCheckout(provider models.Provider, checkout *models.CodebaseCheckout) (*models.CodebaseCheckout, error) {
+	if checkout == nil || (checkout.Depth == 0 && len(checkout.SparsePaths) == 0) {
+		return nil, nil
+	}
+	if provider == models.ProviderUpload {
+		return nil, apperrors.Validation(apperrors.CodeInvalidCheckout, "uploaded codebases are always checked out whole")
+	}
+
+	normalized := &models.CodebaseCheckout{Depth: checkout.Depth}
+	for _, sparsePath := range checkout.SparsePaths {
+		cleaned := path.Clean(strings.Trim(sparsePath, "/"))
+		if cleaned == "." {
+			// The repository root checks out the whole tree
+			return &models.CodebaseCheckout{Depth: checkout.Depth}, nil
+		}
+		if !filepath.IsLocal(filepath.FromSlash(cleaned)) {
+			return nil, apperrors.Validation(apperrors.CodeInvalidCheckout, "sparse path %q is outside of the repository", sparsePath)
+		}
+		if !slices.Contains(normalized.SparsePaths, cleaned) {
+			normalized.SparsePaths = append(normalized.SparsePaths, cleaned)
+		}
+	}
+	return normalized, nil
+}
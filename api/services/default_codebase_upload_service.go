@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/codebase"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/objectstore"
+)
+
+// codebaseSweepPageSize is how many uploaded codebases a sweep reads at a time
+const codebaseSweepPageSize = 100
+
+// archiveContentTypes are the content types archives are uploaded with, by extension
+var archiveContentTypes = map[string]string{
+	".zip":    "application/zip",
+	".tar.gz": "application/gzip",
+	".tgz":    "application/gzip",
+	".tar":    "application/x-tar",
+}
+
+// DefaultCodebaseUploadService implements CodebaseUploadService with archives uploaded to an object store through
+// presigned URLs, so they never pass through the API
+type DefaultCodebaseUploadService struct {
+	codebaseRepo repository.CodebaseRepository
+	tagService   TagService
+	archives     objectstore.ObjectStore
+	cloner       CodebaseCloner
+	cfg          config.CodebaseUploadsConfig
+}
+
+// NewDefaultCodebaseUploadService creates a new DefaultCodebaseUploadService. Completed uploads are extracted with the
+// cloner to check them.
+func NewDefaultCodebaseUploadService(codebaseRepo repository.CodebaseRepository, tagService TagService, archives objectstore.ObjectStore, cloner CodebaseCloner, cfg config.CodebaseUploadsConfig) *DefaultCodebaseUploadService {
+	return &DefaultCodebaseUploadService{
+		codebaseRepo: codebaseRepo,
+		tagService:   tagService,
+		archives:     archives,
+		cloner:       cloner,
+		cfg:          cfg,
+	}
+}
+
+// CreateCodebaseUpload creates a codebase whose archive is uploaded under the project's prefix, named after the
+// codebase. The presigned URL only accepts an archive of the requested size.
+func (s *DefaultCodebaseUploadService) CreateCodebaseUpload(ctx context.Context, request models.CreateCodebaseUploadRequest) (*models.CreateCodebaseUploadResponse, error) {
+	extension, ok := codebase.ArchiveExtension(request.Filename)
+	if !ok {
+		return nil, apperrors.Validation(apperrors.CodeInvalidArchive, "filename must end with one of %s", strings.Join(codebase.ArchiveExtensions, ", "))
+	}
+	if request.Size > s.cfg.MaxSize {
+		return nil, apperrors.PayloadTooLarge(s.cfg.MaxSize)
+	}
+	if len(request.Tags) > 0 {
+		if err := s.tagService.AuthorizeTags(ctx, request.UserID, nil, request.Tags); err != nil {
+			return nil, err
+		}
+	}
+
+	codebaseID := uuid.New().String()
+	key := s.cfg.Prefix + request.ProjectID + "/" + codebaseID + extension
+	contentType := archiveContentTypes[extension]
+	uploadURL, err := s.archives.PresignPut(ctx, key, contentType, request.Size, s.cfg.URLExpiry)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	expiresAt := now.Add(s.cfg.TTL)
+	cb := &models.Codebase{
+		CodebaseID: codebaseID,
+		ProjectID:  request.ProjectID,
+		Name:       request.Name,
+		Provider:   models.ProviderUpload,
+		URL:        s.archives.URI(key),
+		Status:     models.CodebaseStatusAwaitingUpload,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		Metadata:   map[string]string{},
+		Tags:       request.Tags,
+		ExpiresAt:  &expiresAt,
+	}
+	if cb.Tags == nil {
+		cb.Tags = map[string]string{}
+	}
+	if err := s.codebaseRepo.CreateCodebase(ctx, cb); err != nil {
+		return nil, fmt.Errorf("failed to create codebase: %w", err)
+	}
+
+	return &models.CreateCodebaseUploadResponse{
+		CodebaseID:      codebaseID,
+		UploadURL:       uploadURL,
+		UploadHeaders:   map[string]string{"Content-Type": contentType},
+		UploadExpiresAt: now.Add(s.cfg.URLExpiry).Format(time.RFC3339),
+		ExpiresAt:       expiresAt.Format(time.RFC3339),
+		CreatedAt:       now.Format(time.RFC3339),
+	}, nil
+}
+
+// CompleteCodebaseUpload extracts the uploaded archive into a workspace to check it, and activates the codebase. An
+// archive that can't be extracted is deleted, and the codebase keeps awaiting its upload. Completing a codebase
+// already active returns it as is.
+func (s *DefaultCodebaseUploadService) CompleteCodebaseUpload(ctx context.Context, codebaseID string) (*models.GetCodebaseResponse, error) {
+	cb, err := s.codebaseRepo.GetCodebase(ctx, codebaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get codebase: %w", err)
+	}
+	if cb.Provider != models.ProviderUpload {
+		return nil, apperrors.Validation(apperrors.CodeInvalidRequest, "codebase %s isn't uploaded, it's hosted on %s", codebaseID, cb.Provider)
+	}
+	if cb.Status != models.CodebaseStatusAwaitingUpload {
+		return newGetCodebaseResponse(cb), nil
+	}
+
+	_, key, _ := objectstore.ParseURI(cb.URL)
+	archive, err := s.archives.Head(ctx, key)
+	if errors.Is(err, objectstore.ErrNotFound) {
+		return nil, apperrors.Conflict(apperrors.CodeArchiveNotUploaded, "the archive of codebase %s hasn't been uploaded", codebaseID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if archive.Size > s.cfg.MaxSize {
+		deleteCodebaseArchive(ctx, s.archives, cb)
+		return nil, apperrors.PayloadTooLarge(s.cfg.MaxSize)
+	}
+
+	_, release, err := s.cloner.Clone(ctx, "", cb, "", "")
+	if errors.Is(err, codebase.ErrInvalidArchive) || errors.Is(err, codebase.ErrArchiveTooLarge) {
+		deleteCodebaseArchive(ctx, s.archives, cb)
+		return nil, apperrors.Wrap(apperrors.ErrValidation, apperrors.CodeInvalidArchive, err, "the archive of codebase %s can't be extracted", codebaseID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract archive: %w", err)
+	}
+	release()
+
+	now := time.Now().UTC()
+	cb.Status = models.CodebaseStatusActive
+	cb.LastSyncAt = &now
+	cb.UpdatedAt = now
+	if err := s.codebaseRepo.UpdateCodebase(ctx, cb); err != nil {
+		return nil, fmt.Errorf("failed to update codebase: %w", err)
+	}
+
+	slog.InfoContext(ctx, "codebase uploaded", "codebase_id", codebaseID, "size_bytes", archive.Size)
+	return newGetCodebaseResponse(cb), nil
+}
+
+// DeleteExpiredCodebases reads every uploaded codebase before deleting the expired ones, as deleting while paging
+// would shift the pages
+func (s *DefaultCodebaseUploadService) DeleteExpiredCodebases(ctx context.Context) (int, error) {
+	provider := models.ProviderUpload
+	pageSize := codebaseSweepPageSize
+	filter := repository.CodebaseFilter{Provider: &provider, MaxResults: &pageSize}
+
+	now := time.Now()
+	var expired []*models.Codebase
+	for {
+		codebases, nextToken, err := s.codebaseRepo.ListCodebases(ctx, filter)
+		if err != nil {
+			return 0, fmt.Errorf("failed to list uploaded codebases: %w", err)
+		}
+		for _, cb := range codebases {
+			if cb.ExpiresAt != nil && cb.ExpiresAt.Before(now) {
+				expired = append(expired, cb)
+			}
+		}
+		if nextToken == "" {
+			break
+		}
+		filter.NextToken = &nextToken
+	}
+
+	for _, cb := range expired {
+		if err := s.codebaseRepo.DeleteCodebase(ctx, cb.CodebaseID); err != nil && !errors.Is(err, apperrors.ErrNotFound) {
+			return 0, fmt.Errorf("failed to delete codebase %s: %w", cb.CodebaseID, err)
+		}
+		deleteCodebaseArchive(ctx, s.archives, cb)
+		slog.InfoContext(ctx, "deleted expired codebase", "codebase_id", cb.CodebaseID, "expires_at", cb.ExpiresAt)
+	}
+	return len(expired), nil
+}
+
+// RunExpirySweep deletes the expired codebases every interval until the context is cancelled
+func (s *DefaultCodebaseUploadService) RunExpirySweep(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.DeleteExpiredCodebases(ctx); err != nil {
+				slog.ErrorContext(ctx, "failed to delete expired codebases", "error", err)
+			}
+		}
+	}
+}
+
+// deleteCodebaseArchive deletes the archive of an uploaded codebase. Failures are only logged, as the codebase is
+// deleted or rejected either way.
+func deleteCodebaseArchive(ctx context.Context, archives objectstore.ObjectStore, cb *models.Codebase) {
+	_, key, ok := objectstore.ParseURI(cb.URL)
+	if !ok {
+		return
+	}
+	if err := archives.Delete(ctx, key); err != nil {
+		slog.WarnContext(ctx, "failed to delete codebase archive", "codebase_id", cb.CodebaseID, "error", err)
+	}
+}
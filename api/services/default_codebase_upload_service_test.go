@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+	servicesMocks "github.com/kazemisoroush/code-refactoring-tool/api/services/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/codebase"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/objectstore"
+	objectstoreMocks "github.com/kazemisoroush/code-refactoring-tool/pkg/objectstore/mocks"
+)
+
+type codebaseUploadMocks struct {
+	codebaseRepo *repositoryMocks.MockCodebaseRepository
+	archives     *objectstoreMocks.MockObjectStore
+	cloner       *servicesMocks.MockCodebaseCloner
+}
+
+func newTestCodebaseUploadService(t *testing.T) (*DefaultCodebaseUploadService, codebaseUploadMocks) {
+	ctrl := gomock.NewController(t)
+	mocks := codebaseUploadMocks{
+		codebaseRepo: repositoryMocks.NewMockCodebaseRepository(ctrl),
+		archives:     objectstoreMocks.NewMockObjectStore(ctrl),
+		cloner:       servicesMocks.NewMockCodebaseCloner(ctrl),
+	}
+	cfg := config.CodebaseUploadsConfig{Prefix: "codebase-uploads/", MaxSize: 1 << 20, URLExpiry: 15 * time.Minute, TTL: 24 * time.Hour}
+	service := NewDefaultCodebaseUploadService(mocks.codebaseRepo, servicesMocks.NewMockTagService(ctrl), mocks.archives, mocks.cloner, cfg)
+	return service, mocks
+}
+
+// awaitingUpload returns an uploaded codebase whose archive hasn't been checked yet
+func awaitingUpload() *models.Codebase {
+	return &models.Codebase{
+		CodebaseID: "cb-1",
+		ProjectID:  "proj-1",
+		Provider:   models.ProviderUpload,
+		URL:        "s3://bucket/codebase-uploads/proj-1/cb-1.zip",
+		Status:     models.CodebaseStatusAwaitingUpload,
+	}
+}
+
+func TestDefaultCodebaseUploadService_CreateCodebaseUpload(t *testing.T) {
+	service, mocks := newTestCodebaseUploadService(t)
+
+	var key string
+	mocks.archives.EXPECT().
+		PresignPut(gomock.Any(), gomock.Any(), "application/gzip", int64(4096), 15*time.Minute).
+		DoAndReturn(func(_ context.Context, k, _ string, _ int64, _ time.Duration) (string, error) {
+			key = k
+			return "https://bucket.s3.amazonaws.com/" + k + "?X-Amz-Signature=abc", nil
+		})
+	mocks.archives.EXPECT().URI(gomock.Any()).DoAndReturn(func(k string) string { return "s3://bucket/" + k })
+	var created *models.Codebase
+	mocks.codebaseRepo.EXPECT().CreateCodebase(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, cb *models.Codebase) error {
+		created = cb
+		return nil
+	})
+
+	response, err := service.CreateCodebaseUpload(context.Background(), models.CreateCodebaseUploadRequest{
+		ProjectID: "proj-1",
+		Name:      "payments",
+		Filename:  "payments-main.tar.gz",
+		Size:      4096,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "codebase-uploads/proj-1/"+response.CodebaseID+".tar.gz", key)
+	assert.Contains(t, response.UploadURL, key)
+	assert.Equal(t, map[string]string{"Content-Type": "application/gzip"}, response.UploadHeaders)
+	assert.Equal(t, models.ProviderUpload, created.Provider)
+	assert.Equal(t, models.CodebaseStatusAwaitingUpload, created.Status)
+	assert.Equal(t, "s3://bucket/"+key, created.URL)
+	require.NotNil(t, created.ExpiresAt)
+	assert.WithinDuration(t, time.Now().Add(24*time.Hour), *created.ExpiresAt, time.Minute)
+}
+
+func TestDefaultCodebaseUploadService_CreateCodebaseUpload_Rejected(t *testing.T) {
+	service, _ := newTestCodebaseUploadService(t)
+
+	_, err := service.CreateCodebaseUpload(context.Background(), models.CreateCodebaseUploadRequest{ProjectID: "proj-1", Filename: "payments.rar", Size: 4096})
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+
+	_, err = service.CreateCodebaseUpload(context.Background(), models.CreateCodebaseUploadRequest{ProjectID: "proj-1", Filename: "payments.zip", Size: 2 << 20})
+	assert.ErrorIs(t, err, apperrors.ErrPayloadTooLarge)
+}
+
+func TestDefaultCodebaseUploadService_CompleteCodebaseUpload(t *testing.T) {
+	service, mocks := newTestCodebaseUploadService(t)
+	cb := awaitingUpload()
+	mocks.codebaseRepo.EXPECT().GetCodebase(gomock.Any(), "cb-1").Return(cb, nil)
+	mocks.archives.EXPECT().Head(gomock.Any(), "codebase-uploads/proj-1/cb-1.zip").Return(&objectstore.Object{Size: 4096}, nil)
+	released := false
+	mocks.cloner.EXPECT().Clone(gomock.Any(), "", cb, "", "").Return("/workspaces/cb-1", func() { released = true }, nil)
+	mocks.codebaseRepo.EXPECT().UpdateCodebase(gomock.Any(), cb).Return(nil)
+
+	response, err := service.CompleteCodebaseUpload(context.Background(), "cb-1")
+
+	require.NoError(t, err)
+	assert.True(t, released)
+	assert.Equal(t, models.CodebaseStatusActive, response.Status)
+	assert.NotNil(t, cb.LastSyncAt)
+}
+
+func TestDefaultCodebaseUploadService_CompleteCodebaseUpload_NotUploaded(t *testing.T) {
+	service, mocks := newTestCodebaseUploadService(t)
+	mocks.codebaseRepo.EXPECT().GetCodebase(gomock.Any(), "cb-1").Return(awaitingUpload(), nil)
+	mocks.archives.EXPECT().Head(gomock.Any(), "codebase-uploads/proj-1/cb-1.zip").Return(nil, fmt.Errorf("object: %w", objectstore.ErrNotFound))
+
+	_, err := service.CompleteCodebaseUpload(context.Background(), "cb-1")
+
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+}
+
+func TestDefaultCodebaseUploadService_CompleteCodebaseUpload_InvalidArchive(t *testing.T) {
+	service, mocks := newTestCodebaseUploadService(t)
+	mocks.codebaseRepo.EXPECT().GetCodebase(gomock.Any(), "cb-1").Return(awaitingUpload(), nil)
+	mocks.archives.EXPECT().Head(gomock.Any(), "codebase-uploads/proj-1/cb-1.zip").Return(&objectstore.Object{Size: 4096}, nil)
+	mocks.cloner.EXPECT().Clone(gomock.Any(), "", gomock.Any(), "", "").Return("", nil, fmt.Errorf("failed to extract: %w", codebase.ErrInvalidArchive))
+	mocks.archives.EXPECT().Delete(gomock.Any(), "codebase-uploads/proj-1/cb-1.zip").Return(nil)
+
+	_, err := service.CompleteCodebaseUpload(context.Background(), "cb-1")
+
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+}
+
+func TestDefaultCodebaseUploadService_DeleteExpiredCodebases(t *testing.T) {
+	service, mocks := newTestCodebaseUploadService(t)
+	expired := awaitingUpload()
+	expiredAt := time.Now().Add(-time.Hour)
+	expired.ExpiresAt = &expiredAt
+	current := awaitingUpload()
+	current.CodebaseID = "cb-2"
+	expiresAt := time.Now().Add(time.Hour)
+	current.ExpiresAt = &expiresAt
+
+	mocks.codebaseRepo.EXPECT().ListCodebases(gomock.Any(), gomock.Any()).Return([]*models.Codebase{expired}, "100", nil)
+	mocks.codebaseRepo.EXPECT().ListCodebases(gomock.Any(), gomock.Any()).Return([]*models.Codebase{current}, "", nil)
+	mocks.codebaseRepo.EXPECT().DeleteCodebase(gomock.Any(), "cb-1").Return(nil)
+	mocks.archives.EXPECT().Delete(gomock.Any(), "codebase-uploads/proj-1/cb-1.zip").Return(nil)
+
+	deleted, err := service.DeleteExpiredCodebases(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+)
+
+// DefaultComplianceService is the default implementation of ComplianceService. Exports are streamed from the
+// database, so they don't hold a large organisation's data in memory.
+type DefaultComplianceService struct {
+	userRepo  repository.UserRepository
+	roleRepo  repository.RoleRepository
+	auditRepo repository.AuditEventRepository
+}
+
+// NewDefaultComplianceService creates a new DefaultComplianceService
+func NewDefaultComplianceService(userRepo repository.UserRepository, roleRepo repository.RoleRepository, auditRepo repository.AuditEventRepository) *DefaultComplianceService {
+	return &DefaultComplianceService{
+		userRepo:  userRepo,
+		roleRepo:  roleRepo,
+		auditRepo: auditRepo,
+	}
+}
+
+// ExportUsers passes every user created within the requested time range to fn, oldest first
+func (s *DefaultComplianceService) ExportUsers(ctx context.Context, request models.ComplianceExportRequest, fn func(models.APIUser) error) error {
+	timeRange, err := exportTimeRange(request)
+	if err != nil {
+		return err
+	}
+
+	err = s.userRepo.StreamUsers(ctx, timeRange, func(user *models.DBUser) error {
+		return fn(*newAPIUser(user))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to export users: %w", err)
+	}
+
+	return nil
+}
+
+// ExportAccessGrants passes every project member whose role last changed within the requested time range to fn,
+// oldest change first
+func (s *DefaultComplianceService) ExportAccessGrants(ctx context.Context, request models.ComplianceExportRequest, fn func(models.ProjectMember) error) error {
+	timeRange, err := exportTimeRange(request)
+	if err != nil {
+		return err
+	}
+
+	if err := s.roleRepo.StreamProjectMembers(ctx, timeRange, fn); err != nil {
+		return fmt.Errorf("failed to export access grants: %w", err)
+	}
+
+	return nil
+}
+
+// ExportAuditEvents passes every audit event that occurred within the requested time range to fn, oldest first
+func (s *DefaultComplianceService) ExportAuditEvents(ctx context.Context, request models.ComplianceExportRequest, fn func(models.AuditEvent) error) error {
+	timeRange, err := exportTimeRange(request)
+	if err != nil {
+		return err
+	}
+
+	if err := s.auditRepo.StreamEvents(repository.WithReadOnly(ctx), timeRange, fn); err != nil {
+		return fmt.Errorf("failed to export audit events: %w", err)
+	}
+
+	return nil
+}
+
+// exportTimeRange checks the time range of an export request
+func exportTimeRange(request models.ComplianceExportRequest) (repository.TimeRange, error) {
+	if request.From != nil && request.To != nil && !request.From.Before(*request.To) {
+		return repository.TimeRange{}, apperrors.Validation(apperrors.CodeValidation, "from must be before to")
+	}
+
+	return repository.TimeRange{From: request.From, To: request.To}, nil
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+)
+
+func TestDefaultComplianceService_ExportUsers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := repositoryMocks.NewMockUserRepository(ctrl)
+	service := NewDefaultComplianceService(userRepo, nil, nil)
+
+	from := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+	userRepo.EXPECT().
+		StreamUsers(gomock.Any(), repository.TimeRange{From: &from}, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ repository.TimeRange, fn func(*models.DBUser) error) error {
+			return fn(&models.DBUser{UserID: "usr-1", AuthID: "auth-1", Email: "ada@example.com", Role: models.RoleAdmin, CreatedAt: createdAt})
+		})
+
+	var users []models.APIUser
+	err := service.ExportUsers(context.Background(), models.ComplianceExportRequest{From: &from}, func(user models.APIUser) error {
+		users = append(users, user)
+		return nil
+	})
+
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	assert.Equal(t, "ada@example.com", users[0].Email)
+	assert.Equal(t, "2024-01-15T10:30:00Z", users[0].CreatedAt)
+}
+
+func TestDefaultComplianceService_ExportAuditEvents_InvalidRange(t *testing.T) {
+	service := NewDefaultComplianceService(nil, nil, nil)
+
+	from := time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	err := service.ExportAuditEvents(context.Background(), models.ComplianceExportRequest{From: &from, To: &to}, func(models.AuditEvent) error {
+		return nil
+	})
+
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+}
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/coverage"
+)
+
+// maxCoverageOutputLength bounds the output of a failed coverage run quoted in its error
+const maxCoverageOutputLength = 4 * 1024
+
+// DefaultCoverageGapService is the default implementation of CoverageGapService.
+// Coverage is measured by the command configured for the task's language on a clone of the codebase at the task's
+// pinned revision, and the tests are pushed from a separate clone to the git provider of the codebase.
+type DefaultCoverageGapService struct {
+	cloner CodebaseCloner
+	open   codebaseOpener
+	cfg    config.CoverageGapConfig
+}
+
+// NewDefaultCoverageGapService creates a new DefaultCoverageGapService pushing with the configured git credentials,
+// or the SSH key of the codebase configuration
+func NewDefaultCoverageGapService(cloner CodebaseCloner, configs repository.CodebaseConfigRepository, git config.GitConfig, cfg config.CoverageGapConfig) *DefaultCoverageGapService {
+	return &DefaultCoverageGapService{
+		cloner: cloner,
+		open:   gitCodebase(git, configs),
+		cfg:    cfg,
+	}
+}
+
+// FindGaps measures the coverage of a coverage_gap task's codebase and ranks its uncovered functions by the churn of
+// their files. A coverage run whose tests fail still reports the coverage of the tests that ran.
+func (s *DefaultCoverageGapService) FindGaps(ctx context.Context, task *models.TaskWithFullContext) (*models.CoverageGapReport, error) {
+	if task.Codebase == nil {
+		return nil, fmt.Errorf("coverage gap analysis requires a codebase")
+	}
+	if task.Branch == nil || task.CommitSHA == nil {
+		return nil, fmt.Errorf("coverage gap analysis requires a pinned branch and commit")
+	}
+	language := coverageLanguage(task.Input)
+	command, ok := s.cfg.Commands[language]
+	if !ok {
+		return nil, fmt.Errorf("no coverage command is configured for language %s", language)
+	}
+
+	dir, cleanup, err := s.cloner.Clone(ctx, task.TaskID, task.Codebase, *task.Branch, *task.CommitSHA)
+	if err != nil {
+		return nil, fmt.Errorf("failed to clone codebase: %w", err)
+	}
+	defer cleanup()
+
+	functions, err := s.measure(ctx, dir, language, command)
+	// Workspaces are reused by later tasks, so the files the coverage run left are removed
+	if _, resetErr := diffAndReset(context.WithoutCancel(ctx), dir); resetErr != nil {
+		slog.WarnContext(ctx, "failed to reset workspace after coverage run", "error", resetErr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	churn, err := coverage.Churn(ctx, dir, time.Now().Add(-s.cfg.ChurnWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.CoverageGapReport{
+		Language:           language,
+		BaseCommit:         *task.CommitSHA,
+		BaseBranch:         *task.Branch,
+		Gaps:               []models.CoverageGap{},
+		UncoveredFunctions: len(functions),
+	}
+	for _, function := range coverage.Rank(functions, churn, s.cfg.MaxGaps) {
+		report.Gaps = append(report.Gaps, models.CoverageGap{
+			FilePath:   function.File,
+			Function:   function.Name,
+			StartLine:  function.StartLine,
+			EndLine:    function.EndLine,
+			Statements: function.Statements,
+			Uncovered:  function.Uncovered,
+			Commits:    function.Commits,
+		})
+	}
+	return report, nil
+}
+
+// measure runs a coverage command in a checkout and maps its profile to the uncovered functions. The profile is
+// written outside of the checkout, so that it isn't taken for a change.
+func (s *DefaultCoverageGapService) measure(ctx context.Context, dir, language, command string) ([]coverage.Function, error) {
+	profile, err := os.CreateTemp("", "coverage-*.profile")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create coverage profile: %w", err)
+	}
+	_ = profile.Close()
+	defer func() { _ = os.Remove(profile.Name()) }()
+
+	runCtx, cancel := context.WithTimeout(ctx, s.cfg.CommandTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(runCtx, "sh", "-c", command)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "COVERAGE_PROFILE="+profile.Name())
+	output, runErr := cmd.CombinedOutput()
+
+	data, err := os.ReadFile(profile.Name())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read coverage profile: %w", err)
+	}
+	if len(data) == 0 {
+		if len(output) > maxCoverageOutputLength {
+			output = output[len(output)-maxCoverageOutputLength:]
+		}
+		return nil, fmt.Errorf("coverage command wrote no profile: %v: %s", runErr, output)
+	}
+	if runErr != nil {
+		slog.WarnContext(ctx, "coverage command failed, using the coverage of the tests that ran", "language", language, "error", runErr)
+	}
+
+	if language == models.DefaultCoverageLanguage {
+		return coverage.ParseGoProfile(dir, bytes.NewReader(data))
+	}
+	return coverage.ParseLCOV(dir, bytes.NewReader(data))
+}
+
+// PublishTests commits the diff of the tests written for a report's gaps to the branch coverage/<task ID>, cut from
+// the report's base commit, and opens a pull request into the report's base branch. Diffs truncated in the execution
+// output can't be applied and are refused.
+func (s *DefaultCoverageGapService) PublishTests(ctx context.Context, task *models.TaskWithFullContext, report *models.CoverageGapReport, diff string) (*models.PullRequest, error) {
+	if task.Codebase == nil {
+		return nil, fmt.Errorf("publishing tests requires a codebase")
+	}
+	if strings.HasSuffix(diff, truncatedDiffMarker) {
+		return nil, fmt.Errorf("the tests' diff is larger than %d bytes and was truncated", maxExecutionDiffLength)
+	}
+
+	dir, err := os.MkdirTemp("", "coverage-tests-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create checkout directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	repo, err := s.open(ctx, task.Codebase, filepath.Join(dir, workspaceRepoDir))
+	if err != nil {
+		return nil, err
+	}
+	if err := repo.CloneRevision(ctx, report.BaseBranch, report.BaseCommit); err != nil {
+		return nil, fmt.Errorf("failed to clone codebase: %w", err)
+	}
+
+	pr := &models.PullRequest{Branch: "coverage/" + task.TaskID, Base: report.BaseBranch}
+	// The branch is checked out before the tests are added, as checkouts refuse uncommitted changes
+	if err := repo.CheckoutBranch(pr.Branch); err != nil {
+		return nil, fmt.Errorf("failed to create branch %s: %w", pr.Branch, err)
+	}
+	apply := exec.CommandContext(ctx, "git", "-C", repo.GetPath(), "apply", "--whitespace=nowarn", "-")
+	apply.Stdin = strings.NewReader(diff)
+	if out, err := apply.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("failed to apply the tests' diff: %w: %s", err, out)
+	}
+	if err := repo.Commit(fmt.Sprintf("Add unit tests for %d uncovered functions", len(report.Gaps))); err != nil {
+		return nil, err
+	}
+	if err := repo.Push(ctx); err != nil {
+		return nil, fmt.Errorf("failed to push branch %s: %w", pr.Branch, err)
+	}
+
+	pr.URL, err = repo.UpsertPR(ctx, task.Title, coverageGapPullRequestBody(report), pr.Branch, pr.Base)
+	if err != nil {
+		return nil, err
+	}
+	return pr, nil
+}
+
+// coverageGapPullRequestBody lists the functions the tests of a pull request were written for
+func coverageGapPullRequestBody(report *models.CoverageGapReport) string {
+	var body strings.Builder
+	fmt.Fprintf(&body, "Unit tests for the uncovered functions that changed the most, measured at %s.\n\n", report.BaseCommit)
+	for _, gap := range report.Gaps {
+		fmt.Fprintf(&body, "- `%s` in %s: %d of %d uncovered, %d commits\n", gap.Function, gap.FilePath, gap.Uncovered, gap.Statements, gap.Commits)
+	}
+	return body.String()
+}
+
+// coverageLanguage returns the language a coverage_gap task's input selects
+func coverageLanguage(input map[string]any) string {
+	if language, ok := input[models.TaskInputLanguageKey].(string); ok && language != "" {
+		return language
+	}
+	return models.DefaultCoverageLanguage
+}
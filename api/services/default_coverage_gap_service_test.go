@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	servicesMocks "github.com/kazemisoroush/code-refactoring-tool/api/services/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/codebase"
+	codebaseMocks "github.com/kazemisoroush/code-refactoring-tool/pkg/codebase/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+func newTestCoverageGapService(cloner CodebaseCloner, repo codebase.Codebase) *DefaultCoverageGapService {
+	return &DefaultCoverageGapService{
+		cloner: cloner,
+		open: func(context.Context, *models.Codebase, string) (codebase.Codebase, error) {
+			return repo, nil
+		},
+		cfg: config.CoverageGapConfig{
+			Commands:       config.CoverageCommands{"go": "go test -coverprofile=$COVERAGE_PROFILE ./..."},
+			CommandTimeout: time.Minute,
+			ChurnWindow:    time.Hour,
+			MaxGaps:        10,
+		},
+	}
+}
+
+func TestCoverageGapService_FindGaps(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir, head := newGoCheckout(t)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app_test.go"), []byte("package app\n\nimport \"testing\"\n\nfunc TestOld(t *testing.T) { Old() }\n"), 0o644))
+	out, err := exec.Command("git", "-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com", "add", "--all").CombinedOutput()
+	require.NoError(t, err, string(out))
+	out, err = exec.Command("git", "-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "tests").CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	cloner := servicesMocks.NewMockCodebaseCloner(ctrl)
+	codebase := &models.Codebase{CodebaseID: "cb-1"}
+	branch := "main"
+	task := &models.TaskWithFullContext{
+		Task:     models.Task{TaskID: "task-1", Type: models.TaskTypeCoverageGap, Branch: &branch, CommitSHA: &head},
+		Codebase: codebase,
+	}
+	released := false
+	cloner.EXPECT().Clone(gomock.Any(), "task-1", codebase, "main", head).Return(dir, func() { released = true }, nil)
+
+	report, err := newTestCoverageGapService(cloner, nil).FindGaps(context.Background(), task)
+
+	require.NoError(t, err)
+	assert.Equal(t, &models.CoverageGapReport{
+		Language:   "go",
+		BaseCommit: head,
+		BaseBranch: "main",
+		Gaps: []models.CoverageGap{
+			{FilePath: "app.go", Function: "Run", StartLine: 5, EndLine: 5, Statements: 1, Uncovered: 1, Commits: 1},
+		},
+		UncoveredFunctions: 1,
+	}, report)
+	assert.True(t, released)
+	status, err := exec.Command("git", "-C", dir, "status", "--porcelain").Output()
+	require.NoError(t, err)
+	assert.Empty(t, string(status), "the coverage run leaves the workspace clean")
+}
+
+func TestCoverageGapService_FindGaps_UnknownLanguage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	branch, commitSHA := "main", "abc123"
+	task := &models.TaskWithFullContext{
+		Task: models.Task{
+			TaskID: "task-1", Type: models.TaskTypeCoverageGap, Branch: &branch, CommitSHA: &commitSHA,
+			Input: map[string]any{models.TaskInputLanguageKey: "cobol"},
+		},
+		Codebase: &models.Codebase{CodebaseID: "cb-1"},
+	}
+
+	_, err := newTestCoverageGapService(servicesMocks.NewMockCodebaseCloner(ctrl), nil).FindGaps(context.Background(), task)
+
+	assert.ErrorContains(t, err, "no coverage command is configured for language cobol")
+}
+
+func TestCoverageGapService_PublishTests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir := newGitCheckout(t)
+	repo := codebaseMocks.NewMockCodebase(ctrl)
+	repo.EXPECT().GetPath().Return(dir).AnyTimes()
+	task := &models.TaskWithFullContext{
+		Task:     models.Task{TaskID: "task-1", Type: models.TaskTypeCoverageGap, Title: "Cover the services"},
+		Codebase: &models.Codebase{CodebaseID: "cb-1"},
+	}
+	report := &models.CoverageGapReport{
+		BaseBranch: "main",
+		BaseCommit: "abc123",
+		Gaps:       []models.CoverageGap{{FilePath: "app.go", Function: "Run", StartLine: 5, EndLine: 5, Statements: 1, Uncovered: 1, Commits: 2}},
+	}
+	diff := "diff --git a/app_test.go b/app_test.go\nnew file mode 100644\n--- /dev/null\n+++ b/app_test.go\n@@ -0,0 +1 @@\n+package app\n"
+
+	gomock.InOrder(
+		repo.EXPECT().CloneRevision(gomock.Any(), "main", "abc123").Return(nil),
+		repo.EXPECT().CheckoutBranch("coverage/task-1").Return(nil),
+		repo.EXPECT().Commit("Add unit tests for 1 uncovered functions").DoAndReturn(func(string) error {
+			content, err := os.ReadFile(filepath.Join(dir, "app_test.go"))
+			require.NoError(t, err)
+			assert.Equal(t, "package app\n", string(content), "the diff is applied before the commit")
+			return nil
+		}),
+		repo.EXPECT().Push(gomock.Any()).Return(nil),
+		repo.EXPECT().
+			UpsertPR(gomock.Any(), "Cover the services", gomock.Any(), "coverage/task-1", "main").
+			DoAndReturn(func(_ context.Context, _, body, _, _ string) (string, error) {
+				assert.Contains(t, body, "- `Run` in app.go: 1 of 1 uncovered, 2 commits")
+				return "https://github.com/acme/app/pull/7", nil
+			}),
+	)
+
+	pr, err := newTestCoverageGapService(servicesMocks.NewMockCodebaseCloner(ctrl), repo).PublishTests(context.Background(), task, report, diff)
+
+	require.NoError(t, err)
+	assert.Equal(t, &models.PullRequest{URL: "https://github.com/acme/app/pull/7", Branch: "coverage/task-1", Base: "main"}, pr)
+}
+
+func TestCoverageGapService_PublishTests_RefusesTruncatedDiff(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	task := &models.TaskWithFullContext{
+		Task:     models.Task{TaskID: "task-1", Type: models.TaskTypeCoverageGap},
+		Codebase: &models.Codebase{CodebaseID: "cb-1"},
+	}
+	diff := truncateDiff(string(make([]byte, maxExecutionDiffLength+1)))
+
+	_, err := newTestCoverageGapService(servicesMocks.NewMockCodebaseCloner(ctrl), codebaseMocks.NewMockCodebase(ctrl)).
+		PublishTests(context.Background(), task, &models.CoverageGapReport{}, diff)
+
+	assert.ErrorContains(t, err, "was truncated")
+}
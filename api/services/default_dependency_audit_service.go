@@ -0,0 +1,232 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/dependency"
+)
+
+// DefaultDependencyAuditService is the default implementation of DependencyAuditService.
+// Manifests are read from a clone of the codebase at the task's pinned revision and checked against an advisory source.
+type DefaultDependencyAuditService struct {
+	findingRepo  repository.DependencyFindingRepository
+	taskRepo     repository.TaskRepository
+	codebaseRepo repository.CodebaseRepository
+	cloner       CodebaseCloner
+	advisories   dependency.AdvisorySource
+}
+
+// NewDefaultDependencyAuditService creates a new DefaultDependencyAuditService
+func NewDefaultDependencyAuditService(
+	findingRepo repository.DependencyFindingRepository,
+	taskRepo repository.TaskRepository,
+	codebaseRepo repository.CodebaseRepository,
+	cloner CodebaseCloner,
+	advisories dependency.AdvisorySource,
+) *DefaultDependencyAuditService {
+	return &DefaultDependencyAuditService{
+		findingRepo:  findingRepo,
+		taskRepo:     taskRepo,
+		codebaseRepo: codebaseRepo,
+		cloner:       cloner,
+		advisories:   advisories,
+	}
+}
+
+// AuditCodebase checks the dependencies of a dependency_audit task's codebase and replaces the codebase's findings.
+// When the task input sets create_upgrade_task, a pending refactoring task upgrading every fixable dependency is created.
+func (s *DefaultDependencyAuditService) AuditCodebase(ctx context.Context, task *models.TaskWithFullContext) (*models.DependencyAuditResult, error) {
+	if task.Codebase == nil {
+		return nil, fmt.Errorf("dependency audit requires a codebase")
+	}
+
+	var branch, commitSHA string
+	if task.Branch != nil {
+		branch = *task.Branch
+	}
+	if task.CommitSHA != nil {
+		commitSHA = *task.CommitSHA
+	}
+	dir, cleanup, err := s.cloner.Clone(ctx, task.TaskID, task.Codebase, branch, commitSHA)
+	if err != nil {
+		return nil, fmt.Errorf("failed to clone codebase: %w", err)
+	}
+	defer cleanup()
+
+	dependencies, err := dependency.ParseManifests(dir)
+	if err != nil {
+		return nil, err
+	}
+	vulnerabilities, err := s.advisories.Query(ctx, dependencies)
+	if err != nil {
+		return nil, err
+	}
+
+	detectedAt := time.Now()
+	findings := make([]models.DependencyFinding, len(vulnerabilities))
+	for i, vulnerability := range vulnerabilities {
+		findings[i] = models.DependencyFinding{
+			FindingID:    uuid.New().String(),
+			CodebaseID:   task.Codebase.CodebaseID,
+			TaskID:       task.TaskID,
+			Ecosystem:    string(vulnerability.Dependency.Ecosystem),
+			Package:      vulnerability.Dependency.Name,
+			Version:      vulnerability.Dependency.Version,
+			Manifest:     vulnerability.Dependency.Manifest,
+			AdvisoryID:   vulnerability.ID,
+			Aliases:      vulnerability.Aliases,
+			Summary:      vulnerability.Summary,
+			Severity:     models.DependencySeverity(vulnerability.Severity),
+			FixedVersion: optionalString(vulnerability.FixedVersion),
+			URL:          vulnerability.URL,
+			DetectedAt:   detectedAt,
+		}
+	}
+	sortDependencyFindings(findings)
+
+	if err := s.findingRepo.ReplaceFindings(ctx, task.Codebase.CodebaseID, findings); err != nil {
+		return nil, fmt.Errorf("failed to store dependency findings: %w", err)
+	}
+
+	result := &models.DependencyAuditResult{
+		DependencyCount: len(dependencies),
+		Findings:        findings,
+	}
+
+	if createUpgradeTask, _ := task.Input[models.TaskInputCreateUpgradeTaskKey].(bool); createUpgradeTask {
+		upgradeTaskID, err := s.createUpgradeTask(ctx, task, findings, branch, commitSHA)
+		if err != nil {
+			return nil, err
+		}
+		result.UpgradeTaskID = upgradeTaskID
+	}
+
+	return result, nil
+}
+
+// ListFindings lists the vulnerable dependencies found by a codebase's latest audit
+func (s *DefaultDependencyAuditService) ListFindings(ctx context.Context, request models.ListDependencyFindingsRequest) (*models.ListDependencyFindingsResponse, error) {
+	if _, err := s.codebaseRepo.GetCodebase(ctx, request.CodebaseID); err != nil {
+		return nil, fmt.Errorf("failed to get codebase: %w", err)
+	}
+
+	findings, err := s.findingRepo.ListFindings(ctx, request.CodebaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.ListDependencyFindingsResponse{
+		Findings:       []models.DependencyFinding{},
+		SeverityCounts: make(map[models.DependencySeverity]int),
+	}
+	for _, finding := range findings {
+		response.SeverityCounts[finding.Severity]++
+		if request.Severity == nil || finding.Severity == *request.Severity {
+			response.Findings = append(response.Findings, finding)
+		}
+	}
+
+	return response, nil
+}
+
+// createUpgradeTask creates a pending refactoring task upgrading each vulnerable package to the highest version fixing
+// its vulnerabilities. Packages without a published fix are left out; no task is created when none can be upgraded.
+func (s *DefaultDependencyAuditService) createUpgradeTask(ctx context.Context, task *models.TaskWithFullContext, findings []models.DependencyFinding, branch, commitSHA string) (*string, error) {
+	type upgrade struct {
+		Ecosystem  string   `json:"ecosystem"`
+		Package    string   `json:"package"`
+		Manifest   string   `json:"manifest"`
+		From       string   `json:"from"`
+		To         string   `json:"to"`
+		Advisories []string `json:"advisories"`
+	}
+
+	var upgrades []*upgrade
+	byPackage := make(map[string]*upgrade)
+	for _, finding := range findings {
+		if finding.FixedVersion == nil {
+			continue
+		}
+		key := finding.Manifest + "\x00" + finding.Package
+		u, ok := byPackage[key]
+		if !ok {
+			u = &upgrade{Ecosystem: finding.Ecosystem, Package: finding.Package, Manifest: finding.Manifest, From: finding.Version}
+			byPackage[key] = u
+			upgrades = append(upgrades, u)
+		}
+		if u.To == "" || dependency.CompareVersions(*finding.FixedVersion, u.To) > 0 {
+			u.To = *finding.FixedVersion
+		}
+		u.Advisories = append(u.Advisories, finding.AdvisoryID)
+	}
+	if len(upgrades) == 0 {
+		return nil, nil
+	}
+	sort.Slice(upgrades, func(i, j int) bool {
+		if upgrades[i].Manifest != upgrades[j].Manifest {
+			return upgrades[i].Manifest < upgrades[j].Manifest
+		}
+		return upgrades[i].Package < upgrades[j].Package
+	})
+
+	var description strings.Builder
+	description.WriteString("Upgrade the dependencies affected by published vulnerabilities and fix any call sites the upgrades break.\n")
+	for _, u := range upgrades {
+		fmt.Fprintf(&description, "- %s in %s: %s -> %s (%s)\n", u.Package, u.Manifest, u.From, u.To, strings.Join(u.Advisories, ", "))
+	}
+
+	createdBy := ""
+	if task.CreatedBy != nil {
+		createdBy = *task.CreatedBy
+	}
+	upgradeTask := newTaskFromRequest(&models.CreateTaskRequest{
+		ProjectID:   task.ProjectID,
+		AgentID:     task.AgentID,
+		CodebaseID:  task.CodebaseID,
+		Branch:      branch,
+		CommitSHA:   commitSHA,
+		Type:        models.TaskTypeRefactoring,
+		Title:       fmt.Sprintf("Upgrade %d vulnerable dependencies in %s", len(upgrades), task.Codebase.Name),
+		Description: description.String(),
+		Input: map[string]any{
+			"upgrades":       upgrades,
+			"source_task_id": task.TaskID,
+		},
+		CreatedBy: createdBy,
+	})
+	if err := s.taskRepo.Create(ctx, upgradeTask); err != nil {
+		return nil, fmt.Errorf("failed to create upgrade task: %w", err)
+	}
+
+	return &upgradeTask.TaskID, nil
+}
+
+// dependencySeverityRank orders severities from most to least severe
+var dependencySeverityRank = map[models.DependencySeverity]int{
+	models.DependencySeverityCritical: 0,
+	models.DependencySeverityHigh:     1,
+	models.DependencySeverityMedium:   2,
+	models.DependencySeverityLow:      3,
+	models.DependencySeverityUnknown:  4,
+}
+
+// sortDependencyFindings orders findings most severe first, then by package and advisory, as the repository lists them
+func sortDependencyFindings(findings []models.DependencyFinding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if dependencySeverityRank[a.Severity] != dependencySeverityRank[b.Severity] {
+			return dependencySeverityRank[a.Severity] < dependencySeverityRank[b.Severity]
+		}
+		if a.Package != b.Package {
+			return a.Package < b.Package
+		}
+		return a.AdvisoryID < b.AdvisoryID
+	})
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+	servicesMocks "github.com/kazemisoroush/code-refactoring-tool/api/services/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/dependency"
+	dependencyMocks "github.com/kazemisoroush/code-refactoring-tool/pkg/dependency/mocks"
+)
+
+func TestDependencyAuditService_AuditCodebase_CreatesUpgradeTask(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	findingRepo := repositoryMocks.NewMockDependencyFindingRepository(ctrl)
+	taskRepo := repositoryMocks.NewMockTaskRepository(ctrl)
+	cloner := servicesMocks.NewMockCodebaseCloner(ctrl)
+	advisories := dependencyMocks.NewMockAdvisorySource(ctrl)
+	service := NewDefaultDependencyAuditService(findingRepo, taskRepo, repositoryMocks.NewMockCodebaseRepository(ctrl), cloner, advisories)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "requirements.txt"), []byte("requests==2.19.0\nflask>=2.0\n"), 0o600))
+
+	codebaseID := "cb-1"
+	task := &models.TaskWithFullContext{
+		Task: models.Task{
+			TaskID: "task-1", ProjectID: "proj-1", AgentID: "agent-1", CodebaseID: &codebaseID,
+			Type:  models.TaskTypeDependencyAudit,
+			Input: map[string]any{models.TaskInputCreateUpgradeTaskKey: true},
+		},
+		Codebase: &models.Codebase{CodebaseID: codebaseID, Name: "payments"},
+	}
+	requests := dependency.Dependency{Ecosystem: dependency.EcosystemPyPI, Name: "requests", Version: "2.19.0", Manifest: "requirements.txt"}
+
+	cloner.EXPECT().Clone(gomock.Any(), task.TaskID, task.Codebase, "", "").Return(dir, func() {}, nil)
+	advisories.EXPECT().
+		Query(gomock.Any(), []dependency.Dependency{requests}).
+		Return([]dependency.Vulnerability{
+			{ID: "PYSEC-2018-28", Severity: dependency.SeverityMedium, FixedVersion: "2.20.0", Dependency: requests},
+			{ID: "GHSA-j8r2-6x86-q33q", Severity: dependency.SeverityHigh, FixedVersion: "2.31.0", Dependency: requests},
+		}, nil)
+	findingRepo.EXPECT().
+		ReplaceFindings(gomock.Any(), codebaseID, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, findings []models.DependencyFinding) error {
+			require.Len(t, findings, 2)
+			assert.Equal(t, models.DependencySeverityHigh, findings[0].Severity)
+			assert.Equal(t, "task-1", findings[0].TaskID)
+			return nil
+		})
+	taskRepo.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, upgrade *models.Task) error {
+			assert.Equal(t, models.TaskTypeRefactoring, upgrade.Type)
+			assert.Equal(t, "Upgrade 1 vulnerable dependencies in payments", upgrade.Title)
+			assert.Contains(t, upgrade.Description, "requests in requirements.txt: 2.19.0 -> 2.31.0")
+			assert.Equal(t, "task-1", upgrade.Input["source_task_id"])
+			return nil
+		})
+
+	result, err := service.AuditCodebase(context.Background(), task)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.DependencyCount)
+	assert.Len(t, result.Findings, 2)
+	assert.NotNil(t, result.UpgradeTaskID)
+}
+
+func TestDependencyAuditService_ListFindings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	findingRepo := repositoryMocks.NewMockDependencyFindingRepository(ctrl)
+	codebaseRepo := repositoryMocks.NewMockCodebaseRepository(ctrl)
+	service := NewDefaultDependencyAuditService(findingRepo, nil, codebaseRepo, nil, nil)
+	high := models.DependencySeverityHigh
+
+	codebaseRepo.EXPECT().GetCodebase(gomock.Any(), "cb-1").Return(&models.Codebase{CodebaseID: "cb-1"}, nil)
+	findingRepo.EXPECT().ListFindings(gomock.Any(), "cb-1").Return([]models.DependencyFinding{
+		{FindingID: "f-1", Severity: models.DependencySeverityCritical},
+		{FindingID: "f-2", Severity: models.DependencySeverityHigh},
+		{FindingID: "f-3", Severity: models.DependencySeverityHigh},
+	}, nil)
+
+	response, err := service.ListFindings(context.Background(), models.ListDependencyFindingsRequest{CodebaseID: "cb-1", Severity: &high})
+
+	require.NoError(t, err)
+	assert.Len(t, response.Findings, 2)
+	assert.Equal(t, map[models.DependencySeverity]int{models.DependencySeverityCritical: 1, models.DependencySeverityHigh: 2}, response.SeverityCounts)
+}
+
+func TestDependencyAuditService_ListFindings_CodebaseNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	codebaseRepo := repositoryMocks.NewMockCodebaseRepository(ctrl)
+	service := NewDefaultDependencyAuditService(nil, nil, codebaseRepo, nil, nil)
+
+	codebaseRepo.EXPECT().
+		GetCodebase(gomock.Any(), "cb-missing").
+		Return(nil, apperrors.NotFound(apperrors.CodeCodebaseNotFound, "codebase not found"))
+
+	_, err := service.ListFindings(context.Background(), models.ListDependencyFindingsRequest{CodebaseID: "cb-missing"})
+
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
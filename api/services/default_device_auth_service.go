@@ -0,0 +1,372 @@
+package services
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+const (
+	// userCodeAlphabet holds the characters of user codes, consonants only so codes can't spell words or be misread
+	userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+
+	// userCodeAttempts bounds the user codes generated when they collide with the codes of pending sign ins
+	userCodeAttempts = 5
+)
+
+// DefaultDeviceAuthService is the default implementation of DeviceAuthService. The device's tokens are refreshed
+// from the refresh token of the user approving the sign in, so they are the same tokens an interactive sign in issues.
+// That refresh token is sealed to a key derived from the device code, which is only stored hashed, so it can only be
+// read back by the device polling with the code.
+type DefaultDeviceAuthService struct {
+	authService    AuthService
+	deviceAuthRepo repository.DeviceAuthorizationRepository
+	config         config.DeviceAuthConfig
+}
+
+// NewDefaultDeviceAuthService creates a new DefaultDeviceAuthService
+func NewDefaultDeviceAuthService(authService AuthService, deviceAuthRepo repository.DeviceAuthorizationRepository, config config.DeviceAuthConfig) *DefaultDeviceAuthService {
+	return &DefaultDeviceAuthService{
+		authService:    authService,
+		deviceAuthRepo: deviceAuthRepo,
+		config:         config,
+	}
+}
+
+// StartDeviceAuth starts a device sign in
+func (s *DefaultDeviceAuthService) StartDeviceAuth(ctx context.Context, request models.StartDeviceAuthRequest) (*models.StartDeviceAuthResponse, error) {
+	deviceCode, err := randomDeviceCode()
+	if err != nil {
+		return nil, err
+	}
+	tokenKey, err := deviceTokenKey(deviceCode)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	authorization := &models.DeviceAuthorization{
+		DeviceCodeHash: hashDeviceCode(deviceCode),
+		ClientName:     request.ClientName,
+		Status:         models.DeviceAuthorizationStatusPending,
+		TokenPublicKey: hex.EncodeToString(tokenKey.PublicKey().Bytes()),
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(s.config.CodeTTL),
+	}
+
+	// User codes are short enough to collide now and then, in which case another one is drawn
+	for attempt := 1; ; attempt++ {
+		if authorization.UserCode, err = randomUserCode(); err != nil {
+			return nil, err
+		}
+		err = s.deviceAuthRepo.CreateDeviceAuthorization(ctx, authorization)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, apperrors.ErrConflict) || attempt == userCodeAttempts {
+			return nil, err
+		}
+	}
+
+	return &models.StartDeviceAuthResponse{
+		DeviceCode:              deviceCode,
+		UserCode:                authorization.UserCode,
+		VerificationURI:         s.config.VerificationURI,
+		VerificationURIComplete: s.config.VerificationURI + "?" + url.Values{"user_code": {authorization.UserCode}}.Encode(),
+		ExpiresIn:               int(s.config.CodeTTL.Seconds()),
+		Interval:                int(s.config.PollInterval.Seconds()),
+	}, nil
+}
+
+// ApproveDevice approves a pending device sign in. The refresh token is checked to belong to the caller, so users can
+// only sign devices in as themselves.
+func (s *DefaultDeviceAuthService) ApproveDevice(ctx context.Context, request models.ApproveDeviceRequest) error {
+	tokens, err := s.authService.RefreshToken(ctx, &models.RefreshTokenRequest{RefreshToken: request.RefreshToken})
+	if err != nil {
+		return err
+	}
+
+	user, err := s.authService.ValidateToken(ctx, tokens.AccessToken)
+	if err != nil {
+		return err
+	}
+	if request.AuthID == "" || user.AuthID != request.AuthID {
+		return apperrors.Forbidden(apperrors.CodeForbidden, "the refresh token belongs to another user")
+	}
+
+	userCode := normalizeUserCode(request.UserCode)
+	now := time.Now().UTC()
+	authorization, err := s.deviceAuthRepo.GetPendingDeviceAuthorization(ctx, userCode, now)
+	if err != nil {
+		return err
+	}
+	if authorization == nil {
+		return apperrors.NotFound(apperrors.CodeUserCodeNotFound, "no pending device sign in with user code %s", request.UserCode)
+	}
+
+	sealedRefreshToken, err := sealRefreshToken(authorization.TokenPublicKey, request.RefreshToken)
+	if err != nil {
+		return err
+	}
+
+	approved, err := s.deviceAuthRepo.ApproveDeviceAuthorization(ctx, userCode, user.UserID, sealedRefreshToken, now)
+	if err != nil {
+		return err
+	}
+	if !approved {
+		return apperrors.NotFound(apperrors.CodeUserCodeNotFound, "no pending device sign in with user code %s", request.UserCode)
+	}
+
+	return nil
+}
+
+// DenyDevice denies a pending device sign in
+func (s *DefaultDeviceAuthService) DenyDevice(ctx context.Context, request models.DenyDeviceRequest) error {
+	denied, err := s.deviceAuthRepo.DenyDeviceAuthorization(ctx, normalizeUserCode(request.UserCode), time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if !denied {
+		return apperrors.NotFound(apperrors.CodeUserCodeNotFound, "no pending device sign in with user code %s", request.UserCode)
+	}
+
+	return nil
+}
+
+// PollDeviceToken returns the tokens of an approved device sign in. Like the OAuth device authorization grant, a sign
+// in not approved yet fails with authorization_pending, or slow_down when polled more often than the interval.
+func (s *DefaultDeviceAuthService) PollDeviceToken(ctx context.Context, request models.DeviceTokenRequest) (*models.SignInResponse, error) {
+	now := time.Now().UTC()
+	deviceCodeHash := hashDeviceCode(request.DeviceCode)
+
+	authorization, err := s.deviceAuthRepo.PollDeviceAuthorization(ctx, deviceCodeHash, now)
+	if err != nil {
+		return nil, err
+	}
+	if authorization == nil {
+		return nil, apperrors.Validation(apperrors.CodeInvalidGrant, "unknown device code")
+	}
+
+	switch {
+	case authorization.Status == models.DeviceAuthorizationStatusConsumed:
+		return nil, apperrors.Validation(apperrors.CodeInvalidGrant, "the tokens of the device code were issued already")
+	case authorization.Status == models.DeviceAuthorizationStatusDenied:
+		return nil, apperrors.Validation(apperrors.CodeAccessDenied, "the device sign in was denied")
+	case !now.Before(authorization.ExpiresAt):
+		return nil, apperrors.Validation(apperrors.CodeExpiredToken, "the device code expired, start a new sign in")
+	case authorization.Status == models.DeviceAuthorizationStatusPending:
+		if authorization.LastPolledAt != nil && now.Sub(*authorization.LastPolledAt) < s.config.PollInterval {
+			return nil, apperrors.Validation(apperrors.CodeSlowDown, "polled more often than every %[1]s, wait %[1]s longer between polls", s.config.PollInterval)
+		}
+		return nil, apperrors.Validation(apperrors.CodeAuthorizationPending, "the device sign in is not approved yet")
+	}
+
+	sealedRefreshToken, err := s.deviceAuthRepo.ConsumeDeviceAuthorization(ctx, deviceCodeHash)
+	if err != nil {
+		return nil, err
+	}
+	if sealedRefreshToken == "" {
+		return nil, apperrors.Validation(apperrors.CodeInvalidGrant, "the tokens of the device code were issued already")
+	}
+	refreshToken, err := openRefreshToken(request.DeviceCode, sealedRefreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.authService.RefreshToken(ctx, &models.RefreshTokenRequest{RefreshToken: refreshToken})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.SignInResponse{
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		TokenType:    tokens.TokenType,
+		ExpiresIn:    tokens.ExpiresIn,
+	}
+	if authorization.UserID != nil {
+		user, err := s.authService.GetUser(ctx, *authorization.UserID)
+		if err != nil {
+			return nil, err
+		}
+		response.User = user.User
+	}
+
+	return response, nil
+}
+
+// PurgeExpired deletes the expired device sign ins
+func (s *DefaultDeviceAuthService) PurgeExpired(ctx context.Context) (int64, error) {
+	deleted, err := s.deviceAuthRepo.DeleteExpiredDeviceAuthorizations(ctx, time.Now().UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge device authorizations: %w", err)
+	}
+	return deleted, nil
+}
+
+// RunPurge deletes the expired device sign ins every interval until ctx is cancelled
+func (s *DefaultDeviceAuthService) RunPurge(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.PurgeExpired(ctx); err != nil {
+				slog.ErrorContext(ctx, "failed to purge device authorizations", "error", err)
+			}
+		}
+	}
+}
+
+// randomDeviceCode returns a device code too long to guess
+func randomDeviceCode() (string, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", fmt.Errorf("failed to generate device code: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(random), nil
+}
+
+// randomUserCode returns a user code of eight characters of userCodeAlphabet, formatted as XXXX-XXXX
+func randomUserCode() (string, error) {
+	random := make([]byte, 8)
+	if _, err := rand.Read(random); err != nil {
+		return "", fmt.Errorf("failed to generate user code: %w", err)
+	}
+
+	code := make([]byte, 0, 9)
+	for i, b := range random {
+		if i == 4 {
+			code = append(code, '-')
+		}
+		code = append(code, userCodeAlphabet[int(b)%len(userCodeAlphabet)])
+	}
+	return string(code), nil
+}
+
+// normalizeUserCode formats a user code as entered by a user as XXXX-XXXX
+func normalizeUserCode(userCode string) string {
+	code := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(userCode))
+	if len(code) != 8 {
+		return code
+	}
+	return code[:4] + "-" + code[4:]
+}
+
+// hashDeviceCode hashes a device code, which is only stored hashed
+func hashDeviceCode(deviceCode string) string {
+	sum := sha256.Sum256([]byte(deviceCode))
+	return hex.EncodeToString(sum[:])
+}
+
+// deviceTokenKey derives the X25519 key of a sign in from its device code
+func deviceTokenKey(deviceCode string) (*ecdh.PrivateKey, error) {
+	seed, err := hkdf.Key(sha256.New, []byte(deviceCode), nil, "device token key", 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive device token key: %w", err)
+	}
+	return ecdh.X25519().NewPrivateKey(seed)
+}
+
+// sealRefreshToken encrypts a refresh token to the hex X25519 public key of a sign in. The token is encrypted with
+// AES-GCM under a key agreed with an ephemeral key, whose public key prefixes the nonce and ciphertext.
+func sealRefreshToken(publicKeyHex, refreshToken string) (string, error) {
+	publicKeyBytes, err := hex.DecodeString(publicKeyHex)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode device token key: %w", err)
+	}
+	publicKey, err := ecdh.X25519().NewPublicKey(publicKeyBytes)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode device token key: %w", err)
+	}
+
+	ephemeralKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate ephemeral key: %w", err)
+	}
+	aead, err := refreshTokenCipher(ephemeralKey, publicKey)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := append(ephemeralKey.PublicKey().Bytes(), nonce...)
+	sealed = aead.Seal(sealed, nonce, []byte(refreshToken), nil)
+	return base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// openRefreshToken decrypts a refresh token sealed to the key of the device code
+func openRefreshToken(deviceCode, sealedRefreshToken string) (string, error) {
+	sealed, err := base64.RawURLEncoding.DecodeString(sealedRefreshToken)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode sealed refresh token: %w", err)
+	}
+
+	tokenKey, err := deviceTokenKey(deviceCode)
+	if err != nil {
+		return "", err
+	}
+	keySize := len(tokenKey.PublicKey().Bytes())
+	if len(sealed) < keySize {
+		return "", errors.New("sealed refresh token is too short")
+	}
+	ephemeralPublicKey, err := ecdh.X25519().NewPublicKey(sealed[:keySize])
+	if err != nil {
+		return "", fmt.Errorf("failed to decode ephemeral key: %w", err)
+	}
+	aead, err := refreshTokenCipher(tokenKey, ephemeralPublicKey)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < keySize+aead.NonceSize() {
+		return "", errors.New("sealed refresh token is too short")
+	}
+
+	nonce, ciphertext := sealed[keySize:keySize+aead.NonceSize()], sealed[keySize+aead.NonceSize():]
+	refreshToken, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to open sealed refresh token: %w", err)
+	}
+	return string(refreshToken), nil
+}
+
+// refreshTokenCipher returns the AES-GCM cipher of the key agreed between a private key and a public key
+func refreshTokenCipher(privateKey *ecdh.PrivateKey, publicKey *ecdh.PublicKey) (cipher.AEAD, error) {
+	shared, err := privateKey.ECDH(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to agree refresh token key: %w", err)
+	}
+	key, err := hkdf.Key(sha256.New, shared, nil, "device refresh token", 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive refresh token key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,288 @@
+package services
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+	serviceMocks "github.com/kazemisoroush/code-refactoring-tool/api/services/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+func newTestDeviceAuthService(ctrl *gomock.Controller) (*DefaultDeviceAuthService, *serviceMocks.MockAuthService, *repositoryMocks.MockDeviceAuthorizationRepository) {
+	authService := serviceMocks.NewMockAuthService(ctrl)
+	deviceAuthRepo := repositoryMocks.NewMockDeviceAuthorizationRepository(ctrl)
+	deviceAuthConfig := config.DeviceAuthConfig{
+		VerificationURI: "https://app.example.com/device",
+		CodeTTL:         10 * time.Minute,
+		PollInterval:    5 * time.Second,
+	}
+	return NewDefaultDeviceAuthService(authService, deviceAuthRepo, deviceAuthConfig), authService, deviceAuthRepo
+}
+
+func testTokenPublicKey(t *testing.T, deviceCode string) string {
+	t.Helper()
+	tokenKey, err := deviceTokenKey(deviceCode)
+	require.NoError(t, err)
+	return hex.EncodeToString(tokenKey.PublicKey().Bytes())
+}
+
+func TestDefaultDeviceAuthService_StartDeviceAuth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _, deviceAuthRepo := newTestDeviceAuthService(ctrl)
+	ctx := context.Background()
+
+	var stored *models.DeviceAuthorization
+	gomock.InOrder(
+		// The first user code collides with a pending sign in
+		deviceAuthRepo.EXPECT().CreateDeviceAuthorization(ctx, gomock.Any()).Return(apperrors.Conflict(apperrors.CodeConflict, "user code taken")),
+		deviceAuthRepo.EXPECT().CreateDeviceAuthorization(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, authorization *models.DeviceAuthorization) error {
+			stored = authorization
+			return nil
+		}),
+	)
+
+	response, err := service.StartDeviceAuth(ctx, models.StartDeviceAuthRequest{ClientName: "refactor-cli"})
+
+	require.NoError(t, err)
+	assert.Regexp(t, `^[BCDFGHJKLMNPQRSTVWXZ]{4}-[BCDFGHJKLMNPQRSTVWXZ]{4}$`, response.UserCode)
+	assert.Equal(t, "https://app.example.com/device", response.VerificationURI)
+	assert.Equal(t, "https://app.example.com/device?user_code="+response.UserCode, response.VerificationURIComplete)
+	assert.Equal(t, 600, response.ExpiresIn)
+	assert.Equal(t, 5, response.Interval)
+
+	// Only the hash of the device code is stored
+	require.NotNil(t, stored)
+	assert.Equal(t, hashDeviceCode(response.DeviceCode), stored.DeviceCodeHash)
+	assert.Equal(t, response.UserCode, stored.UserCode)
+	assert.Equal(t, models.DeviceAuthorizationStatusPending, stored.Status)
+	assert.Equal(t, "refactor-cli", stored.ClientName)
+	// Approved refresh tokens are sealed to the key of the device code
+	assert.Equal(t, testTokenPublicKey(t, response.DeviceCode), stored.TokenPublicKey)
+}
+
+func TestDefaultDeviceAuthService_ApproveDevice(t *testing.T) {
+	ctx := context.Background()
+	refreshed := &models.RefreshTokenResponse{AccessToken: "access-token", RefreshToken: "refresh-token"}
+
+	t.Run("approves as the caller", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, authService, deviceAuthRepo := newTestDeviceAuthService(ctrl)
+
+		authService.EXPECT().RefreshToken(ctx, &models.RefreshTokenRequest{RefreshToken: "refresh-token"}).Return(refreshed, nil)
+		authService.EXPECT().ValidateToken(ctx, "access-token").Return(&models.UserContext{UserID: "user-123", AuthID: "auth-123"}, nil)
+		// The user code is normalized as users type it
+		deviceAuthRepo.EXPECT().GetPendingDeviceAuthorization(ctx, "BCDF-GHJK", gomock.Any()).
+			Return(&models.DeviceAuthorization{UserCode: "BCDF-GHJK", TokenPublicKey: testTokenPublicKey(t, "device-code")}, nil)
+		var sealed string
+		deviceAuthRepo.EXPECT().ApproveDeviceAuthorization(ctx, "BCDF-GHJK", "user-123", gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _, _, sealedRefreshToken string, _ time.Time) (bool, error) {
+				sealed = sealedRefreshToken
+				return true, nil
+			})
+
+		err := service.ApproveDevice(ctx, models.ApproveDeviceRequest{AuthID: "auth-123", UserCode: "bcdf ghjk", RefreshToken: "refresh-token"})
+
+		require.NoError(t, err)
+		// Only the device code can open the stored refresh token
+		assert.NotContains(t, sealed, "refresh-token")
+		opened, err := openRefreshToken("device-code", sealed)
+		require.NoError(t, err)
+		assert.Equal(t, "refresh-token", opened)
+	})
+
+	t.Run("refresh token of another user", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, authService, _ := newTestDeviceAuthService(ctrl)
+
+		authService.EXPECT().RefreshToken(ctx, gomock.Any()).Return(refreshed, nil)
+		authService.EXPECT().ValidateToken(ctx, "access-token").Return(&models.UserContext{UserID: "user-456", AuthID: "auth-456"}, nil)
+
+		err := service.ApproveDevice(ctx, models.ApproveDeviceRequest{AuthID: "auth-123", UserCode: "BCDF-GHJK", RefreshToken: "refresh-token"})
+
+		assert.ErrorIs(t, err, apperrors.ErrForbidden)
+	})
+
+	t.Run("no pending sign in", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, authService, deviceAuthRepo := newTestDeviceAuthService(ctrl)
+
+		authService.EXPECT().RefreshToken(ctx, gomock.Any()).Return(refreshed, nil)
+		authService.EXPECT().ValidateToken(ctx, "access-token").Return(&models.UserContext{UserID: "user-123", AuthID: "auth-123"}, nil)
+		deviceAuthRepo.EXPECT().GetPendingDeviceAuthorization(ctx, "BCDF-GHJK", gomock.Any()).Return(nil, nil)
+
+		err := service.ApproveDevice(ctx, models.ApproveDeviceRequest{AuthID: "auth-123", UserCode: "BCDF-GHJK", RefreshToken: "refresh-token"})
+
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+		assert.Equal(t, apperrors.CodeUserCodeNotFound, apperrors.CodeOf(err))
+	})
+}
+
+func TestDefaultDeviceAuthService_PollDeviceToken(t *testing.T) {
+	ctx := context.Background()
+	request := models.DeviceTokenRequest{DeviceCode: "device-code"}
+	deviceCodeHash := hashDeviceCode("device-code")
+	expiresAt := time.Now().Add(5 * time.Minute)
+	longAgo := time.Now().Add(-time.Minute)
+	justNow := time.Now().Add(-time.Second)
+	userID := "user-123"
+
+	tests := []struct {
+		name          string
+		authorization *models.DeviceAuthorization
+		expectedCode  string
+	}{
+		{
+			name:          "unknown device code",
+			authorization: nil,
+			expectedCode:  apperrors.CodeInvalidGrant,
+		},
+		{
+			name:          "first poll of a pending sign in",
+			authorization: &models.DeviceAuthorization{Status: models.DeviceAuthorizationStatusPending, ExpiresAt: expiresAt},
+			expectedCode:  apperrors.CodeAuthorizationPending,
+		},
+		{
+			name:          "pending sign in polled at the interval",
+			authorization: &models.DeviceAuthorization{Status: models.DeviceAuthorizationStatusPending, ExpiresAt: expiresAt, LastPolledAt: &longAgo},
+			expectedCode:  apperrors.CodeAuthorizationPending,
+		},
+		{
+			name:          "pending sign in polled too often",
+			authorization: &models.DeviceAuthorization{Status: models.DeviceAuthorizationStatusPending, ExpiresAt: expiresAt, LastPolledAt: &justNow},
+			expectedCode:  apperrors.CodeSlowDown,
+		},
+		{
+			name:          "denied sign in",
+			authorization: &models.DeviceAuthorization{Status: models.DeviceAuthorizationStatusDenied, ExpiresAt: expiresAt},
+			expectedCode:  apperrors.CodeAccessDenied,
+		},
+		{
+			name:          "expired sign in",
+			authorization: &models.DeviceAuthorization{Status: models.DeviceAuthorizationStatusApproved, UserID: &userID, ExpiresAt: longAgo},
+			expectedCode:  apperrors.CodeExpiredToken,
+		},
+		{
+			name:          "tokens issued already",
+			authorization: &models.DeviceAuthorization{Status: models.DeviceAuthorizationStatusConsumed, UserID: &userID, ExpiresAt: expiresAt},
+			expectedCode:  apperrors.CodeInvalidGrant,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, _, deviceAuthRepo := newTestDeviceAuthService(ctrl)
+			deviceAuthRepo.EXPECT().PollDeviceAuthorization(ctx, deviceCodeHash, gomock.Any()).Return(tt.authorization, nil)
+
+			_, err := service.PollDeviceToken(ctx, request)
+
+			assert.ErrorIs(t, err, apperrors.ErrValidation)
+			assert.Equal(t, tt.expectedCode, apperrors.CodeOf(err))
+		})
+	}
+
+	t.Run("slow down names the interval", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, _, deviceAuthRepo := newTestDeviceAuthService(ctrl)
+		service.config.PollInterval = 7 * time.Second
+		deviceAuthRepo.EXPECT().PollDeviceAuthorization(ctx, deviceCodeHash, gomock.Any()).
+			Return(&models.DeviceAuthorization{Status: models.DeviceAuthorizationStatusPending, ExpiresAt: expiresAt, LastPolledAt: &justNow}, nil)
+
+		_, err := service.PollDeviceToken(ctx, request)
+
+		assert.Equal(t, apperrors.CodeSlowDown, apperrors.CodeOf(err))
+		assert.Contains(t, err.Error(), "wait 7s longer between polls")
+	})
+
+	t.Run("approved sign in", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, authService, deviceAuthRepo := newTestDeviceAuthService(ctrl)
+
+		deviceAuthRepo.EXPECT().PollDeviceAuthorization(ctx, deviceCodeHash, gomock.Any()).
+			Return(&models.DeviceAuthorization{Status: models.DeviceAuthorizationStatusApproved, UserID: &userID, ExpiresAt: expiresAt}, nil)
+		sealed, err := sealRefreshToken(testTokenPublicKey(t, "device-code"), "approver-refresh-token")
+		require.NoError(t, err)
+		deviceAuthRepo.EXPECT().ConsumeDeviceAuthorization(ctx, deviceCodeHash).Return(sealed, nil)
+		authService.EXPECT().RefreshToken(ctx, &models.RefreshTokenRequest{RefreshToken: "approver-refresh-token"}).
+			Return(&models.RefreshTokenResponse{AccessToken: "device-access-token", RefreshToken: "device-refresh-token", TokenType: "Bearer", ExpiresIn: 3600}, nil)
+		authService.EXPECT().GetUser(ctx, userID).Return(&models.GetUserResponse{User: &models.APIUser{UserID: userID}}, nil)
+
+		response, err := service.PollDeviceToken(ctx, request)
+
+		require.NoError(t, err)
+		assert.Equal(t, "device-access-token", response.AccessToken)
+		assert.Equal(t, "device-refresh-token", response.RefreshToken)
+		assert.Equal(t, userID, response.User.UserID)
+	})
+
+	t.Run("approved sign in consumed concurrently", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, _, deviceAuthRepo := newTestDeviceAuthService(ctrl)
+
+		deviceAuthRepo.EXPECT().PollDeviceAuthorization(ctx, deviceCodeHash, gomock.Any()).
+			Return(&models.DeviceAuthorization{Status: models.DeviceAuthorizationStatusApproved, UserID: &userID, ExpiresAt: expiresAt}, nil)
+		deviceAuthRepo.EXPECT().ConsumeDeviceAuthorization(ctx, deviceCodeHash).Return("", nil)
+
+		_, err := service.PollDeviceToken(ctx, request)
+
+		assert.Equal(t, apperrors.CodeInvalidGrant, apperrors.CodeOf(err))
+	})
+}
+
+func TestDefaultDeviceAuthService_PurgeExpired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _, deviceAuthRepo := newTestDeviceAuthService(ctrl)
+	ctx := context.Background()
+
+	deviceAuthRepo.EXPECT().DeleteExpiredDeviceAuthorizations(ctx, gomock.Any()).Return(int64(3), nil)
+
+	deleted, err := service.PurgeExpired(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+}
+
+func TestSealRefreshToken(t *testing.T) {
+	sealed, err := sealRefreshToken(testTokenPublicKey(t, "device-code"), "refresh-token")
+	require.NoError(t, err)
+
+	opened, err := openRefreshToken("device-code", sealed)
+	require.NoError(t, err)
+	assert.Equal(t, "refresh-token", opened)
+
+	_, err = openRefreshToken("other-device-code", sealed)
+	assert.Error(t, err)
+}
+
+func TestNormalizeUserCode(t *testing.T) {
+	assert.Equal(t, "BCDF-GHJK", normalizeUserCode("bcdfghjk"))
+	assert.Equal(t, "BCDF-GHJK", normalizeUserCode(" bcdf-GHJK"))
+	assert.Equal(t, "BCD", normalizeUserCode("bcd"))
+}
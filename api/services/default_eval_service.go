@@ -0,0 +1,387 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/agent"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/eval"
+)
+
+const (
+	// defaultEvalRunsLimit is the number of runs listed when the request sets no limit
+	defaultEvalRunsLimit = 20
+
+	// evalScoreTolerance is the drop of a fixture's diff score tolerated before it counts as a regression
+	evalScoreTolerance = 0.05
+)
+
+// DefaultEvalService is the default implementation of EvalService.
+// Each fixture is checked out twice: the local agent edits the first checkout, and its diff is applied to the second,
+// pristine one to be built, tested and scored.
+type DefaultEvalService struct {
+	runRepo  repository.EvalRunRepository
+	newModel func(model string) agent.ChatModel
+	cfg      config.EvalConfig
+	local    config.LocalAIConfig
+	now      func() time.Time
+
+	// running tracks the runs whose fixtures are still being evaluated
+	running sync.WaitGroup
+}
+
+// NewDefaultEvalService creates a new DefaultEvalService. newModel connects to the named model.
+func NewDefaultEvalService(
+	runRepo repository.EvalRunRepository,
+	newModel func(model string) agent.ChatModel,
+	cfg config.EvalConfig,
+	local config.LocalAIConfig,
+) *DefaultEvalService {
+	return &DefaultEvalService{
+		runRepo:  runRepo,
+		newModel: newModel,
+		cfg:      cfg,
+		local:    local,
+		now:      time.Now,
+	}
+}
+
+// RunEval starts evaluating the fixtures in the background and returns the running evaluation
+func (s *DefaultEvalService) RunEval(ctx context.Context, request models.RunEvalRequest) (*models.EvalRun, error) {
+	promptVersion := request.PromptVersion
+	if promptVersion == "" {
+		promptVersion = s.local.PromptVersion
+	}
+	if !slices.Contains(LocalAgentPromptVersions(), promptVersion) {
+		return nil, apperrors.Validation(apperrors.CodeUnknownPromptVersion, "unknown prompt version %q, expected one of %v", promptVersion, LocalAgentPromptVersions())
+	}
+	model := request.Model
+	if model == "" {
+		model = s.local.Model
+	}
+
+	cases, err := eval.LoadCases(s.cfg.FixturesDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load eval fixtures: %w", err)
+	}
+	if len(request.Cases) > 0 {
+		selected := make([]eval.Case, 0, len(request.Cases))
+		for _, c := range cases {
+			if slices.Contains(request.Cases, c.Name) {
+				selected = append(selected, c)
+			}
+		}
+		for _, name := range request.Cases {
+			if !slices.ContainsFunc(selected, func(c eval.Case) bool { return c.Name == name }) {
+				return nil, apperrors.Validation(apperrors.CodeUnknownEvalCase, "unknown eval case: %s", name)
+			}
+		}
+		cases = selected
+	}
+	if len(cases) == 0 {
+		return nil, apperrors.Validation(apperrors.CodeUnknownEvalCase, "no eval cases found in %s", s.cfg.FixturesDir)
+	}
+
+	run := &models.EvalRun{
+		RunID:         "eval-" + uuid.New().String(),
+		Model:         model,
+		PromptVersion: promptVersion,
+		Status:        models.EvalRunStatusRunning,
+		Cases:         make([]string, 0, len(cases)),
+		Results:       []models.EvalCaseResult{},
+		StartedAt:     s.now().UTC(),
+	}
+	for _, c := range cases {
+		run.Cases = append(run.Cases, c.Name)
+	}
+	if request.StartedBy != "" {
+		run.StartedBy = &request.StartedBy
+	}
+	if err := s.runRepo.CreateRun(ctx, run); err != nil {
+		return nil, fmt.Errorf("failed to create eval run: %w", err)
+	}
+
+	started := *run
+	started.Cases = slices.Clone(run.Cases)
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+		s.run(context.WithoutCancel(ctx), run, cases)
+	}()
+
+	return &started, nil
+}
+
+// run evaluates the fixtures one after the other, saving the run after each so its progress can be followed
+func (s *DefaultEvalService) run(ctx context.Context, run *models.EvalRun, cases []eval.Case) {
+	model := s.newModel(run.Model)
+	// Fixtures aren't ingested into a knowledge base, so the agent is given no retrieved chunks
+	taskContext := TaskContext{Model: run.Model}
+	for _, c := range cases {
+		run.Results = append(run.Results, s.evaluate(ctx, model, taskContext, run.PromptVersion, c))
+		if err := s.runRepo.UpdateRun(ctx, run); err != nil {
+			slog.WarnContext(ctx, "failed to save eval run progress", "run_id", run.RunID, "case", c.Name, "error", err)
+		}
+	}
+	s.finish(ctx, run)
+}
+
+// evaluate runs the local agent against a fixture, then builds, tests and scores its change
+func (s *DefaultEvalService) evaluate(ctx context.Context, model agent.ChatModel, taskContext TaskContext, promptVersion string, c eval.Case) (result models.EvalCaseResult) {
+	started := s.now()
+	result = models.EvalCaseResult{Case: c.Name}
+	defer func() {
+		result.DurationMS = s.now().Sub(started).Milliseconds()
+	}()
+
+	if s.cfg.CaseTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.cfg.CaseTimeout)
+		defer cancel()
+	}
+
+	workDir, err := os.MkdirTemp("", "eval-"+c.Name+"-")
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to create eval workspace: %v", err)
+		return result
+	}
+	defer func() {
+		if err := os.RemoveAll(workDir); err != nil {
+			slog.WarnContext(ctx, "failed to remove eval workspace", "dir", workDir, "error", err)
+		}
+	}()
+
+	agentDir := filepath.Join(workDir, "agent")
+	if err := c.Checkout(ctx, agentDir); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	executor, err := NewLocalAgentTaskExecutor(model, taskContext, fixtureCloner{dir: agentDir}, nil, promptVersion, s.local.MaxSteps, s.local.MaxRepairs, c.TestCommand)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	output, err := executor.Execute(ctx, &models.TaskWithFullContext{
+		Task: models.Task{
+			TaskID:      "eval-" + c.Name,
+			Type:        models.TaskType(c.TaskType),
+			Title:       c.Title,
+			Description: c.Description,
+		},
+		Codebase: &models.Codebase{Name: c.Name},
+	})
+	if err != nil {
+		// A stopped agent may still have left a change worth scoring
+		result.Error = err.Error()
+	}
+	if reason, ok := output["stop_reason"]; ok {
+		result.StopReason = fmt.Sprint(reason)
+	}
+	diff, _ := output["diff"].(string)
+
+	// The agent's change is verified on a pristine checkout, so nothing it left outside the diff counts
+	verifyDir := filepath.Join(workDir, "verify")
+	if err := c.Checkout(ctx, verifyDir); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if diff != "" {
+		if err := eval.ApplyDiff(ctx, verifyDir, diff); err != nil {
+			result.Error = err.Error()
+			return result
+		}
+	}
+
+	build, err := eval.RunCommand(ctx, verifyDir, c.BuildCommand)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Compiled = build.Passed
+	if !build.Passed {
+		result.Output = build.Output
+	} else {
+		tests, err := eval.RunCommand(ctx, verifyDir, c.TestCommand)
+		if err != nil {
+			result.Error = err.Error()
+			return result
+		}
+		result.TestsPassed = tests.Passed
+		if !tests.Passed {
+			result.Output = tests.Output
+		}
+	}
+
+	result.Diff = toEvalDiffQuality(eval.ScoreDiff(diff, c.Expected))
+	return result
+}
+
+// finish completes a run with its summary
+func (s *DefaultEvalService) finish(ctx context.Context, run *models.EvalRun) {
+	completedAt := s.now().UTC()
+	run.CompletedAt = &completedAt
+	run.Status = models.EvalRunStatusCompleted
+	run.Summary = summarizeEvalResults(run.Results)
+	if err := s.runRepo.UpdateRun(ctx, run); err != nil {
+		slog.ErrorContext(ctx, "failed to complete eval run", "run_id", run.RunID, "error", err)
+		return
+	}
+	slog.InfoContext(ctx, "eval run finished", "run_id", run.RunID, "status", run.Status, "cases", len(run.Results))
+}
+
+// GetRun retrieves an evaluation run with its per-fixture results
+func (s *DefaultEvalService) GetRun(ctx context.Context, request models.GetEvalRunRequest) (*models.EvalRun, error) {
+	run, err := s.runRepo.GetRun(ctx, request.RunID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get eval run: %w", err)
+	}
+	return run, nil
+}
+
+// ListRuns lists the most recent evaluation runs with their summaries
+func (s *DefaultEvalService) ListRuns(ctx context.Context, request models.ListEvalRunsRequest) (*models.ListEvalRunsResponse, error) {
+	limit := request.Limit
+	if limit <= 0 {
+		limit = defaultEvalRunsLimit
+	}
+	runs, err := s.runRepo.ListRuns(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list eval runs: %w", err)
+	}
+	return &models.ListEvalRunsResponse{Runs: runs}, nil
+}
+
+// CompareRuns compares a candidate evaluation run with a baseline over the fixtures both evaluated
+func (s *DefaultEvalService) CompareRuns(ctx context.Context, request models.CompareEvalRunsRequest) (*models.EvalComparison, error) {
+	baseline, err := s.runRepo.GetRun(ctx, request.Baseline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get baseline eval run: %w", err)
+	}
+	candidate, err := s.runRepo.GetRun(ctx, request.Candidate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get candidate eval run: %w", err)
+	}
+
+	baselineResults := make(map[string]models.EvalCaseResult, len(baseline.Results))
+	for _, result := range baseline.Results {
+		baselineResults[result.Case] = result
+	}
+
+	comparison := &models.EvalComparison{
+		Cases:       []models.EvalCaseComparison{},
+		Regressions: []string{},
+	}
+	var shared, baselineShared []models.EvalCaseResult
+	for _, result := range candidate.Results {
+		before, ok := baselineResults[result.Case]
+		if !ok {
+			continue
+		}
+		shared = append(shared, result)
+		baselineShared = append(baselineShared, before)
+
+		caseComparison := models.EvalCaseComparison{
+			Case:      result.Case,
+			Baseline:  toEvalCaseOutcome(before),
+			Candidate: toEvalCaseOutcome(result),
+		}
+		caseComparison.Regressed = (before.Compiled && !result.Compiled) ||
+			(before.TestsPassed && !result.TestsPassed) ||
+			before.Diff.Score-result.Diff.Score > evalScoreTolerance
+		if caseComparison.Regressed {
+			comparison.Regressions = append(comparison.Regressions, result.Case)
+		}
+		comparison.Cases = append(comparison.Cases, caseComparison)
+	}
+	slices.SortFunc(comparison.Cases, func(a, b models.EvalCaseComparison) int {
+		return strings.Compare(a.Case, b.Case)
+	})
+	slices.Sort(comparison.Regressions)
+
+	before := summarizeEvalResults(baselineShared)
+	after := summarizeEvalResults(shared)
+	comparison.CompileRateDelta = roundRate(after.CompileRate - before.CompileRate)
+	comparison.TestPassRateDelta = roundRate(after.TestPassRate - before.TestPassRate)
+	comparison.DiffScoreDelta = roundRate(after.MeanDiffScore - before.MeanDiffScore)
+
+	baseline.Results = nil
+	candidate.Results = nil
+	comparison.Baseline = *baseline
+	comparison.Candidate = *candidate
+	return comparison, nil
+}
+
+// summarizeEvalResults aggregates the outcomes of fixtures
+func summarizeEvalResults(results []models.EvalCaseResult) *models.EvalSummary {
+	summary := &models.EvalSummary{Cases: len(results)}
+	if len(results) == 0 {
+		return summary
+	}
+	var score float64
+	for _, result := range results {
+		if result.Compiled {
+			summary.Compiled++
+		}
+		if result.TestsPassed {
+			summary.TestsPassed++
+		}
+		score += result.Diff.Score
+	}
+	summary.CompileRate = roundRate(float64(summary.Compiled) / float64(len(results)))
+	summary.TestPassRate = roundRate(float64(summary.TestsPassed) / float64(len(results)))
+	summary.MeanDiffScore = roundRate(score / float64(len(results)))
+	return summary
+}
+
+// roundRate rounds a rate to three decimals, keeping reports readable
+func roundRate(rate float64) float64 {
+	return math.Round(rate*1000) / 1000
+}
+
+// toEvalCaseOutcome keeps the compared part of a fixture's outcome
+func toEvalCaseOutcome(result models.EvalCaseResult) models.EvalCaseOutcome {
+	return models.EvalCaseOutcome{
+		Compiled:    result.Compiled,
+		TestsPassed: result.TestsPassed,
+		DiffScore:   result.Diff.Score,
+	}
+}
+
+// toEvalDiffQuality converts a diff score to its API model
+func toEvalDiffQuality(quality eval.DiffQuality) models.EvalDiffQuality {
+	return models.EvalDiffQuality{
+		FilesChanged:     quality.FilesChanged,
+		LinesAdded:       quality.LinesAdded,
+		LinesRemoved:     quality.LinesRemoved,
+		FilePrecision:    quality.FilePrecision,
+		FileRecall:       quality.FileRecall,
+		PatternsMatched:  quality.PatternsMatched,
+		PatternsRequired: quality.PatternsRequired,
+		ForbiddenMatched: quality.ForbiddenMatched,
+		WithinLineBudget: quality.WithinLineBudget,
+		Score:            quality.Score,
+	}
+}
+
+// fixtureCloner hands the local agent a fixture's checkout instead of cloning a codebase
+type fixtureCloner struct {
+	dir string
+}
+
+// Clone implements CodebaseCloner
+func (c fixtureCloner) Clone(_ context.Context, _ string, _ *models.Codebase, _, _ string) (string, func(), error) {
+	return c.dir, func() {}, nil
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/agent"
+	agentMocks "github.com/kazemisoroush/code-refactoring-tool/pkg/ai/agent/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// newEvalFixtures creates a fixture whose notes must be rewritten, built by checking the rewrite landed
+func newEvalFixtures(t *testing.T) string {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "rewrite-notes", "repo"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "rewrite-notes", "case.json"), []byte(`{
+		"task_type": "documentation",
+		"title": "Rewrite notes",
+		"description": "Replace the old line of notes.txt",
+		"build_command": "grep -q new notes.txt",
+		"test_command": "true",
+		"expected": {"changed_files": ["notes.txt"], "required_patterns": ["^new$"], "max_changed_lines": 2}
+	}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "rewrite-notes", "repo", "notes.txt"), []byte("old\n"), 0o644))
+	return dir
+}
+
+func TestDefaultEvalService_RunEval(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	runRepo := repositoryMocks.NewMockEvalRunRepository(ctrl)
+	model := agentMocks.NewMockChatModel(ctrl)
+	var requestedModel string
+	service := NewDefaultEvalService(runRepo, func(name string) agent.ChatModel {
+		requestedModel = name
+		return model
+	}, config.EvalConfig{FixturesDir: newEvalFixtures(t), CaseTimeout: time.Minute}, config.LocalAIConfig{
+		Model: "codellama:7b-instruct", PromptVersion: DefaultLocalAgentPromptVersion, MaxSteps: 5, MaxRepairs: 1,
+	})
+
+	gomock.InOrder(
+		model.EXPECT().Chat(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, messages []agent.ChatMessage, _ []agent.ToolDefinition) (agent.ChatMessage, error) {
+				assert.Contains(t, messages[1].Content, "Replace the old line of notes.txt")
+				return agent.ChatMessage{ToolCalls: []agent.ToolCall{{Name: "apply_patch", Arguments: map[string]any{
+					"path": "notes.txt", "start_line": float64(1), "end_line": float64(1), "replacement": "new",
+				}}}}, nil
+			}),
+		model.EXPECT().Chat(gomock.Any(), gomock.Any(), gomock.Any()).Return(agent.ChatMessage{
+			Content: `{"summary":"Rewrote the notes.","documents":[{"file":"notes.txt","description":"Notes"}]}`,
+		}, nil),
+	)
+
+	var saved *models.EvalRun
+	runRepo.EXPECT().CreateRun(gomock.Any(), gomock.Any()).Return(nil)
+	runRepo.EXPECT().UpdateRun(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, run *models.EvalRun) error {
+		saved = run
+		return nil
+	}).Times(2)
+
+	run, err := service.RunEval(context.Background(), models.RunEvalRequest{PromptVersion: "v2", StartedBy: "user-1"})
+	require.NoError(t, err)
+	service.running.Wait()
+
+	assert.Equal(t, models.EvalRunStatusRunning, run.Status)
+	assert.Equal(t, "codellama:7b-instruct", run.Model, "the configured model is the default")
+	assert.Equal(t, "codellama:7b-instruct", requestedModel)
+	assert.Equal(t, []string{"rewrite-notes"}, run.Cases)
+	assert.Equal(t, "user-1", *run.StartedBy)
+
+	require.NotNil(t, saved)
+	assert.Equal(t, models.EvalRunStatusCompleted, saved.Status)
+	require.Len(t, saved.Results, 1)
+	result := saved.Results[0]
+	assert.Empty(t, result.Error)
+	assert.Equal(t, "answered", result.StopReason)
+	assert.True(t, result.Compiled)
+	assert.True(t, result.TestsPassed)
+	assert.Equal(t, []string{"notes.txt"}, result.Diff.FilesChanged)
+	assert.Equal(t, 1.0, result.Diff.Score)
+	assert.Equal(t, &models.EvalSummary{Cases: 1, Compiled: 1, TestsPassed: 1, CompileRate: 1, TestPassRate: 1, MeanDiffScore: 1}, saved.Summary)
+}
+
+func TestDefaultEvalService_RunEval_Validation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewDefaultEvalService(repositoryMocks.NewMockEvalRunRepository(ctrl), nil,
+		config.EvalConfig{FixturesDir: newEvalFixtures(t)}, config.LocalAIConfig{PromptVersion: DefaultLocalAgentPromptVersion})
+
+	_, err := service.RunEval(context.Background(), models.RunEvalRequest{PromptVersion: "v99"})
+	assert.Equal(t, apperrors.CodeUnknownPromptVersion, apperrors.CodeOf(err))
+
+	_, err = service.RunEval(context.Background(), models.RunEvalRequest{Cases: []string{"rewrite-notes", "missing"}})
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	assert.Equal(t, apperrors.CodeUnknownEvalCase, apperrors.CodeOf(err))
+}
+
+func TestDefaultEvalService_CompareRuns(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	runRepo := repositoryMocks.NewMockEvalRunRepository(ctrl)
+	service := NewDefaultEvalService(runRepo, nil, config.EvalConfig{}, config.LocalAIConfig{})
+
+	outcome := func(name string, compiled, passed bool, score float64) models.EvalCaseResult {
+		return models.EvalCaseResult{Case: name, Compiled: compiled, TestsPassed: passed, Diff: models.EvalDiffQuality{Score: score}}
+	}
+	runRepo.EXPECT().GetRun(gomock.Any(), "eval-base").Return(&models.EvalRun{RunID: "eval-base", PromptVersion: "v1", Results: []models.EvalCaseResult{
+		outcome("extract-function", true, true, 0.8),
+		outcome("remove-dead-code", true, false, 0.5),
+		outcome("rename-type", true, true, 1),
+	}}, nil)
+	runRepo.EXPECT().GetRun(gomock.Any(), "eval-cand").Return(&models.EvalRun{RunID: "eval-cand", PromptVersion: "v2", Results: []models.EvalCaseResult{
+		outcome("remove-dead-code", true, true, 0.75),
+		outcome("extract-function", false, false, 0.78),
+		outcome("split-package", true, true, 1),
+	}}, nil)
+
+	comparison, err := service.CompareRuns(context.Background(), models.CompareEvalRunsRequest{Baseline: "eval-base", Candidate: "eval-cand"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "v1", comparison.Baseline.PromptVersion)
+	assert.Nil(t, comparison.Candidate.Results, "per-fixture results are in the cases")
+	require.Len(t, comparison.Cases, 2, "only fixtures both runs evaluated are compared")
+	assert.Equal(t, "extract-function", comparison.Cases[0].Case)
+	assert.True(t, comparison.Cases[0].Regressed)
+	assert.False(t, comparison.Cases[1].Regressed)
+	assert.Equal(t, []string{"extract-function"}, comparison.Regressions)
+	assert.Equal(t, -0.5, comparison.CompileRateDelta)
+	assert.Equal(t, 0.0, comparison.TestPassRateDelta)
+	assert.Equal(t, 0.115, comparison.DiffScoreDelta)
+}
+
+func TestDefaultEvalService_CompareRuns_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	runRepo := repositoryMocks.NewMockEvalRunRepository(ctrl)
+	service := NewDefaultEvalService(runRepo, nil, config.EvalConfig{}, config.LocalAIConfig{})
+	runRepo.EXPECT().GetRun(gomock.Any(), "missing").Return(nil, apperrors.NotFound(apperrors.CodeEvalRunNotFound, "eval run not found: missing"))
+
+	_, err := service.CompareRuns(context.Background(), models.CompareEvalRunsRequest{Baseline: "missing", Candidate: "eval-cand"})
+
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// experimentTaskTypes are the task types the local agent runs, eligible for experiments by default
+var experimentTaskTypes = []models.TaskType{
+	models.TaskTypeCodeAnalysis,
+	models.TaskTypeRefactoring,
+	models.TaskTypeCodeReview,
+	models.TaskTypeDocumentation,
+	models.TaskTypeCustom,
+}
+
+// DefaultExperimentService is the default implementation of ExperimentService.
+// A task type is covered by at most one running experiment, so each eligible task has a single control and variant.
+type DefaultExperimentService struct {
+	experimentRepo repository.ExperimentRepository
+	taskRepo       repository.TaskRepository
+	local          config.LocalAIConfig
+	now            func() time.Time
+}
+
+// NewDefaultExperimentService creates a new DefaultExperimentService. The control runs with the local model and prompt
+// version of local.
+func NewDefaultExperimentService(
+	experimentRepo repository.ExperimentRepository,
+	taskRepo repository.TaskRepository,
+	local config.LocalAIConfig,
+) *DefaultExperimentService {
+	return &DefaultExperimentService{
+		experimentRepo: experimentRepo,
+		taskRepo:       taskRepo,
+		local:          local,
+		now:            time.Now,
+	}
+}
+
+// CreateExperiment starts assigning the eligible tasks to the experiment's control and variant
+func (s *DefaultExperimentService) CreateExperiment(ctx context.Context, request models.CreateExperimentRequest) (*models.Experiment, error) {
+	// The variant is stored in full, so reports still describe it once the configuration changes
+	experiment := &models.Experiment{
+		ExperimentID:         "exp-" + uuid.New().String(),
+		Name:                 request.Name,
+		TaskTypes:            request.TaskTypes,
+		TrafficPercent:       request.TrafficPercent,
+		VariantModel:         request.VariantModel,
+		VariantPromptVersion: request.VariantPromptVersion,
+		Status:               models.ExperimentStatusRunning,
+		CreatedAt:            s.now().UTC(),
+	}
+	if len(experiment.TaskTypes) == 0 {
+		experiment.TaskTypes = slices.Clone(experimentTaskTypes)
+	}
+	if experiment.VariantModel == "" {
+		experiment.VariantModel = s.local.Model
+	}
+	if experiment.VariantPromptVersion == "" {
+		experiment.VariantPromptVersion = s.local.PromptVersion
+	}
+	if !slices.Contains(LocalAgentPromptVersions(), experiment.VariantPromptVersion) {
+		return nil, apperrors.Validation(apperrors.CodeUnknownPromptVersion, "unknown prompt version %q, expected one of %v", experiment.VariantPromptVersion, LocalAgentPromptVersions())
+	}
+	if experiment.VariantModel == s.local.Model && experiment.VariantPromptVersion == s.local.PromptVersion {
+		return nil, apperrors.Validation(apperrors.CodeValidation, "the variant must change the model or the prompt version of the control")
+	}
+	if request.CreatedBy != "" {
+		experiment.CreatedBy = &request.CreatedBy
+	}
+
+	running, err := s.experimentRepo.ListExperiments(ctx, models.ExperimentStatusRunning)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list running experiments: %w", err)
+	}
+	for _, other := range running {
+		for _, taskType := range experiment.TaskTypes {
+			if slices.Contains(other.TaskTypes, taskType) {
+				return nil, apperrors.Conflict(apperrors.CodeExperimentOverlap, "experiment %s already covers %s tasks", other.ExperimentID, taskType)
+			}
+		}
+	}
+
+	if err := s.experimentRepo.CreateExperiment(ctx, experiment); err != nil {
+		return nil, fmt.Errorf("failed to create experiment: %w", err)
+	}
+	return experiment, nil
+}
+
+// GetExperiment retrieves an experiment
+func (s *DefaultExperimentService) GetExperiment(ctx context.Context, request models.GetExperimentRequest) (*models.Experiment, error) {
+	experiment, err := s.experimentRepo.GetExperiment(ctx, request.ExperimentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get experiment: %w", err)
+	}
+	return experiment, nil
+}
+
+// ListExperiments lists the experiments, newest first
+func (s *DefaultExperimentService) ListExperiments(ctx context.Context) (*models.ListExperimentsResponse, error) {
+	experiments, err := s.experimentRepo.ListExperiments(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list experiments: %w", err)
+	}
+	return &models.ListExperimentsResponse{Experiments: experiments}, nil
+}
+
+// StopExperiment stops assigning tasks to an experiment. Tasks assigned already keep their arm.
+func (s *DefaultExperimentService) StopExperiment(ctx context.Context, request models.StopExperimentRequest) (*models.Experiment, error) {
+	stopped, err := s.experimentRepo.StopExperiment(ctx, request.ExperimentID, s.now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to stop experiment: %w", err)
+	}
+	if !stopped {
+		return nil, apperrors.Conflict(apperrors.CodeExperimentStopped, "experiment %s is stopped already", request.ExperimentID)
+	}
+
+	experiment, err := s.experimentRepo.GetExperiment(ctx, request.ExperimentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get experiment: %w", err)
+	}
+	return experiment, nil
+}
+
+// GetExperimentReport compares the success rate, duration and approval rate of the experiment's variant with its
+// control
+func (s *DefaultExperimentService) GetExperimentReport(ctx context.Context, request models.GetExperimentReportRequest) (*models.ExperimentReport, error) {
+	experiment, err := s.experimentRepo.GetExperiment(ctx, request.ExperimentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get experiment: %w", err)
+	}
+
+	tasks, err := s.taskRepo.ListByExperiment(ctx, experiment.ExperimentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list experiment tasks: %w", err)
+	}
+
+	report := &models.ExperimentReport{
+		Experiment: *experiment,
+		Control:    summarizeExperimentArm(tasks, models.ExperimentArmControl),
+		Variant:    summarizeExperimentArm(tasks, models.ExperimentArmVariant),
+	}
+	report.SuccessRateDelta = roundRate(report.Variant.SuccessRate - report.Control.SuccessRate)
+	report.MeanDurationDeltaMS = report.Variant.MeanDurationMS - report.Control.MeanDurationMS
+	report.ApprovalRateDelta = roundRate(report.Variant.ApprovalRate - report.Control.ApprovalRate)
+	return report, nil
+}
+
+// summarizeExperimentArm aggregates the outcome of the tasks assigned to arm. Durations are the executor's, recorded
+// in the output of the tasks, so the time tasks waited before being run doesn't count.
+func summarizeExperimentArm(tasks []models.Task, arm models.ExperimentArm) models.ExperimentArmReport {
+	var report models.ExperimentArmReport
+	var durationTotal float64
+	var durations int
+	for _, task := range tasks {
+		if task.ExperimentArm == nil || *task.ExperimentArm != arm {
+			continue
+		}
+		report.Tasks++
+
+		switch task.Status {
+		case models.TaskStatusCompleted:
+			report.Completed++
+			if task.Approved != nil {
+				report.Reviewed++
+				if *task.Approved {
+					report.Approved++
+				}
+			}
+		case models.TaskStatusFailed:
+			report.Failed++
+		default:
+			continue
+		}
+		if duration, ok := task.Output[experimentDurationKey].(float64); ok {
+			durationTotal += duration
+			durations++
+		}
+	}
+
+	if finished := report.Completed + report.Failed; finished > 0 {
+		report.SuccessRate = roundRate(float64(report.Completed) / float64(finished))
+	}
+	if durations > 0 {
+		report.MeanDurationMS = int64(math.Round(durationTotal / float64(durations)))
+	}
+	if report.Reviewed > 0 {
+		report.ApprovalRate = roundRate(float64(report.Approved) / float64(report.Reviewed))
+	}
+	return report
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+var experimentTestLocalConfig = config.LocalAIConfig{Model: "codellama:7b-instruct", PromptVersion: "v1"}
+
+func TestDefaultExperimentService_CreateExperiment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	experimentRepo := repositoryMocks.NewMockExperimentRepository(ctrl)
+	service := NewDefaultExperimentService(experimentRepo, repositoryMocks.NewMockTaskRepository(ctrl), experimentTestLocalConfig)
+
+	experimentRepo.EXPECT().ListExperiments(gomock.Any(), models.ExperimentStatusRunning).Return([]models.Experiment{
+		{ExperimentID: "exp-docs", TaskTypes: []models.TaskType{models.TaskTypeDocumentation}},
+	}, nil)
+	experimentRepo.EXPECT().CreateExperiment(gomock.Any(), gomock.Any()).Return(nil)
+
+	experiment, err := service.CreateExperiment(context.Background(), models.CreateExperimentRequest{
+		Name:                 "Prompt v2 on refactorings",
+		TaskTypes:            []models.TaskType{models.TaskTypeRefactoring},
+		TrafficPercent:       20,
+		VariantPromptVersion: "v2",
+		CreatedBy:            "user-1",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.ExperimentStatusRunning, experiment.Status)
+	assert.Equal(t, "codellama:7b-instruct", experiment.VariantModel, "the variant keeps the configured model")
+	assert.Equal(t, "v2", experiment.VariantPromptVersion)
+	assert.Equal(t, "user-1", *experiment.CreatedBy)
+}
+
+func TestDefaultExperimentService_CreateExperiment_Rejected(t *testing.T) {
+	tests := []struct {
+		name         string
+		request      models.CreateExperimentRequest
+		running      []models.Experiment
+		expectedCode string
+	}{
+		{
+			name:         "same as control",
+			request:      models.CreateExperimentRequest{Name: "No change", TrafficPercent: 10, VariantModel: "codellama:7b-instruct"},
+			expectedCode: apperrors.CodeValidation,
+		},
+		{
+			name:         "unknown prompt version",
+			request:      models.CreateExperimentRequest{Name: "Prompt v9", TrafficPercent: 10, VariantPromptVersion: "v9"},
+			expectedCode: apperrors.CodeUnknownPromptVersion,
+		},
+		{
+			name:         "overlapping experiment",
+			request:      models.CreateExperimentRequest{Name: "Qwen everywhere", TrafficPercent: 10, VariantModel: "qwen2.5-coder:7b"},
+			running:      []models.Experiment{{ExperimentID: "exp-docs", TaskTypes: []models.TaskType{models.TaskTypeDocumentation}}},
+			expectedCode: apperrors.CodeExperimentOverlap,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			experimentRepo := repositoryMocks.NewMockExperimentRepository(ctrl)
+			service := NewDefaultExperimentService(experimentRepo, repositoryMocks.NewMockTaskRepository(ctrl), experimentTestLocalConfig)
+			if tt.running != nil {
+				experimentRepo.EXPECT().ListExperiments(gomock.Any(), models.ExperimentStatusRunning).Return(tt.running, nil)
+			}
+
+			_, err := service.CreateExperiment(context.Background(), tt.request)
+
+			assert.Equal(t, tt.expectedCode, apperrors.CodeOf(err))
+		})
+	}
+}
+
+func TestDefaultExperimentService_StopExperiment_AlreadyStopped(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	experimentRepo := repositoryMocks.NewMockExperimentRepository(ctrl)
+	service := NewDefaultExperimentService(experimentRepo, repositoryMocks.NewMockTaskRepository(ctrl), experimentTestLocalConfig)
+	experimentRepo.EXPECT().StopExperiment(gomock.Any(), "exp-1", gomock.Any()).Return(false, nil)
+
+	_, err := service.StopExperiment(context.Background(), models.StopExperimentRequest{ExperimentID: "exp-1"})
+
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+	assert.Equal(t, apperrors.CodeExperimentStopped, apperrors.CodeOf(err))
+}
+
+func TestDefaultExperimentService_GetExperimentReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	experimentRepo := repositoryMocks.NewMockExperimentRepository(ctrl)
+	taskRepo := repositoryMocks.NewMockTaskRepository(ctrl)
+	service := NewDefaultExperimentService(experimentRepo, taskRepo, experimentTestLocalConfig)
+
+	control, variant := models.ExperimentArmControl, models.ExperimentArmVariant
+	approved, rejected := true, false
+	task := func(arm *models.ExperimentArm, status models.TaskStatus, durationMS float64, approval *bool) models.Task {
+		return models.Task{ExperimentArm: arm, Status: status, Output: map[string]any{experimentDurationKey: durationMS}, Approved: approval}
+	}
+	experimentRepo.EXPECT().GetExperiment(gomock.Any(), "exp-1").Return(&models.Experiment{ExperimentID: "exp-1"}, nil)
+	taskRepo.EXPECT().ListByExperiment(gomock.Any(), "exp-1").Return([]models.Task{
+		task(&control, models.TaskStatusCompleted, 40000, &approved),
+		task(&control, models.TaskStatusCompleted, 50000, &rejected),
+		task(&control, models.TaskStatusFailed, 60000, nil),
+		task(&control, models.TaskStatusInProgress, 0, nil),
+		task(&variant, models.TaskStatusCompleted, 30000, &approved),
+		task(&variant, models.TaskStatusCompleted, 20000, nil),
+	}, nil)
+
+	report, err := service.GetExperimentReport(context.Background(), models.GetExperimentReportRequest{ExperimentID: "exp-1"})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.ExperimentArmReport{
+		Tasks: 4, Completed: 2, Failed: 1, SuccessRate: 0.667, MeanDurationMS: 50000, Reviewed: 2, Approved: 1, ApprovalRate: 0.5,
+	}, report.Control)
+	assert.Equal(t, models.ExperimentArmReport{
+		Tasks: 2, Completed: 2, SuccessRate: 1, MeanDurationMS: 25000, Reviewed: 1, Approved: 1, ApprovalRate: 1,
+	}, report.Variant)
+	assert.Equal(t, 0.333, report.SuccessRateDelta)
+	assert.Equal(t, int64(-25000), report.MeanDurationDeltaMS)
+	assert.Equal(t, 0.5, report.ApprovalRateDelta)
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/codebase"
+)
+
+// fileHistoryWindow is how far back the history of a codebase is read, so that files changed long ago don't count as
+// hot spots
+const fileHistoryWindow = 365 * 24 * time.Hour
+
+// DefaultFileHistoryService is the default implementation of FileHistoryService.
+// A codebase keeps the history read by its latest analysis, replacing the previous one.
+type DefaultFileHistoryService struct {
+	historyRepo repository.FileHistoryRepository
+}
+
+// NewDefaultFileHistoryService creates a new DefaultFileHistoryService
+func NewDefaultFileHistoryService(historyRepo repository.FileHistoryRepository) *DefaultFileHistoryService {
+	return &DefaultFileHistoryService{
+		historyRepo: historyRepo,
+	}
+}
+
+// RecordHistory reads the last year of history of the clone of an analysis task's codebase in dir and stores it,
+// returning the histories of its files, highest churn first
+func (s *DefaultFileHistoryService) RecordHistory(ctx context.Context, task *models.TaskWithFullContext, dir string) ([]models.FileHistory, error) {
+	if task.CodebaseID == nil {
+		return nil, fmt.Errorf("file history requires a codebase")
+	}
+
+	commitSHA, err := codebase.HeadCommit(dir)
+	if err != nil {
+		return nil, err
+	}
+	extracted, err := codebase.ExtractHistory(ctx, dir, time.Now().Add(-fileHistoryWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	histories := make([]models.FileHistory, len(extracted))
+	for i, history := range extracted {
+		histories[i] = models.FileHistory{
+			Path:          history.Path,
+			Commits:       history.Commits,
+			LinesAdded:    history.LinesAdded,
+			LinesDeleted:  history.LinesDeleted,
+			Churn:         history.Churn(),
+			Authors:       history.Authors,
+			FirstCommitAt: history.FirstCommitAt,
+			LastCommitAt:  history.LastCommitAt,
+		}
+	}
+
+	if err := s.historyRepo.ReplaceHistory(ctx, *task.CodebaseID, commitSHA, histories); err != nil {
+		return nil, err
+	}
+
+	return histories, nil
+}
+
+// LookupHistory retrieves the stored histories of files of a codebase, of up to limit files changed the most when
+// paths is empty
+func (s *DefaultFileHistoryService) LookupHistory(ctx context.Context, codebaseID string, paths []string, limit int) ([]models.FileHistory, error) {
+	return s.historyRepo.ListHistory(ctx, codebaseID, repository.FileHistoryFilter{Paths: paths, Limit: limit})
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+)
+
+func TestFileHistoryService_RecordHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	historyRepo := repositoryMocks.NewMockFileHistoryRepository(ctrl)
+	service := NewDefaultFileHistoryService(historyRepo)
+
+	dir, head := newGoCheckout(t)
+	codebaseID := "cb-1"
+	task := &models.TaskWithFullContext{Task: models.Task{TaskID: "task-1", CodebaseID: &codebaseID}}
+
+	var stored []models.FileHistory
+	historyRepo.EXPECT().ReplaceHistory(gomock.Any(), "cb-1", head, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _ string, histories []models.FileHistory) error {
+			stored = histories
+			return nil
+		})
+
+	histories, err := service.RecordHistory(context.Background(), task, dir)
+
+	require.NoError(t, err)
+	assert.Equal(t, stored, histories)
+	require.Len(t, histories, 3)
+	assert.Equal(t, "app.go", histories[0].Path)
+	assert.Equal(t, 1, histories[0].Commits)
+	assert.Equal(t, 5, histories[0].Churn)
+	assert.Equal(t, []string{"test"}, histories[0].Authors)
+}
+
+func TestFileHistoryService_RecordHistory_RequiresCodebase(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewDefaultFileHistoryService(repositoryMocks.NewMockFileHistoryRepository(ctrl))
+
+	_, err := service.RecordHistory(context.Background(), &models.TaskWithFullContext{Task: models.Task{TaskID: "task-1"}}, t.TempDir())
+
+	assert.Error(t, err)
+}
+
+func TestFileHistoryService_LookupHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	historyRepo := repositoryMocks.NewMockFileHistoryRepository(ctrl)
+	service := NewDefaultFileHistoryService(historyRepo)
+
+	expected := []models.FileHistory{{Path: "app.go", Commits: 3, Churn: 40}}
+	historyRepo.EXPECT().
+		ListHistory(gomock.Any(), "cb-1", repository.FileHistoryFilter{Paths: []string{"app.go"}, Limit: 5}).
+		Return(expected, nil)
+
+	histories, err := service.LookupHistory(context.Background(), "cb-1", []string{"app.go"}, 5)
+
+	require.NoError(t, err)
+	assert.Equal(t, expected, histories)
+}
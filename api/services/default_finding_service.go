@@ -0,0 +1,439 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	analyzermodels "github.com/kazemisoroush/code-refactoring-tool/pkg/analyzer/models"
+)
+
+// defaultFindingsLimit is how many findings are listed when the request doesn't say
+const defaultFindingsLimit = 50
+
+// digitRuns matches the numbers in finding messages, which change with unrelated edits such as line counts
+var digitRuns = regexp.MustCompile(`[0-9]+`)
+
+// defaultFindingSeverities are the severities of the kinds of findings no severity rule matches. Other kinds, such
+// as manual findings, are of medium severity.
+var defaultFindingSeverities = map[analyzermodels.IssueType]models.FindingSeverity{
+	analyzermodels.IssueTypeBuild:       models.FindingSeverityCritical,
+	analyzermodels.IssueTypeTest:        models.FindingSeverityHigh,
+	analyzermodels.IssueTypeImportCycle: models.FindingSeverityHigh,
+	analyzermodels.IssueTypeDeadCode:    models.FindingSeverityLow,
+	analyzermodels.IssueTypeCoverage:    models.FindingSeverityLow,
+}
+
+// findingSeverityRank orders the severities of findings
+var findingSeverityRank = map[models.FindingSeverity]int{
+	models.FindingSeverityLow:      0,
+	models.FindingSeverityMedium:   1,
+	models.FindingSeverityHigh:     2,
+	models.FindingSeverityCritical: 3,
+}
+
+// DefaultFindingService is the default implementation of FindingService
+type DefaultFindingService struct {
+	findingRepo  repository.FindingRepository
+	codebaseRepo repository.CodebaseRepository
+	policyRepo   repository.QualityGatePolicyRepository
+	ownersRepo   repository.CodeOwnersRepository
+	now          func() time.Time
+}
+
+// NewDefaultFindingService creates a new DefaultFindingService
+func NewDefaultFindingService(
+	findingRepo repository.FindingRepository,
+	codebaseRepo repository.CodebaseRepository,
+	policyRepo repository.QualityGatePolicyRepository,
+	ownersRepo repository.CodeOwnersRepository,
+) *DefaultFindingService {
+	return &DefaultFindingService{
+		findingRepo:  findingRepo,
+		codebaseRepo: codebaseRepo,
+		policyRepo:   policyRepo,
+		ownersRepo:   ownersRepo,
+		now:          time.Now,
+	}
+}
+
+// CreateFinding reports a finding on a codebase by hand, owned by the owners of its file. Manual findings stay open
+// until a user changes them.
+func (s *DefaultFindingService) CreateFinding(ctx context.Context, request models.CreateFindingRequest) (*models.Finding, error) {
+	codebase, err := s.codebaseRepo.GetCodebase(ctx, request.CodebaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get codebase: %w", err)
+	}
+
+	severity := request.Severity
+	if severity == "" {
+		rules, err := s.severityRules(ctx, codebase.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		severity = findingSeverity(rules, models.FindingSourceManual, request.RuleID, "")
+	}
+
+	owners, err := loadCodeOwners(ctx, s.ownersRepo, request.CodebaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	finding := &models.Finding{
+		FindingID:   fmt.Sprintf("finding-%s", uuid.New().String()),
+		CodebaseID:  request.CodebaseID,
+		Source:      models.FindingSourceManual,
+		RuleID:      request.RuleID,
+		Fingerprint: findingFingerprint(models.FindingSourceManual, request.RuleID, []string{request.FilePath}, request.Message, 0),
+		Message:     request.Message,
+		FilePath:    request.FilePath,
+		Line:        request.Line,
+		EndLine:     request.EndLine,
+		Severity:    severity,
+		Status:      models.FindingStatusOpen,
+		FirstSeenAt: now,
+		LastSeenAt:  now,
+		UpdatedAt:   now,
+	}
+	if owners != nil && request.FilePath != "" {
+		finding.Owners = owners.Owners(request.FilePath)
+	}
+	if request.UserID != "" {
+		finding.StatusChangedBy = &request.UserID
+	}
+
+	if err := s.findingRepo.CreateFinding(ctx, finding); err != nil {
+		return nil, err
+	}
+
+	return finding, nil
+}
+
+// GetFinding retrieves a finding by ID
+func (s *DefaultFindingService) GetFinding(ctx context.Context, request models.GetFindingRequest) (*models.Finding, error) {
+	return s.findingRepo.GetFinding(ctx, request.FindingID)
+}
+
+// UpdateFinding changes the status of a finding. Ignoring a finding requires a justification, which is kept only
+// while the finding stays ignored.
+func (s *DefaultFindingService) UpdateFinding(ctx context.Context, request models.UpdateFindingRequest) (*models.Finding, error) {
+	if request.Status == models.FindingStatusIgnored && request.Justification == "" {
+		return nil, apperrors.Validation(apperrors.CodeJustificationRequired, "ignoring a finding requires a justification")
+	}
+
+	finding, err := s.findingRepo.GetFinding(ctx, request.FindingID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	finding.Status = request.Status
+	finding.Justification = nil
+	finding.FixedAt = nil
+	switch request.Status {
+	case models.FindingStatusIgnored:
+		finding.Justification = &request.Justification
+	case models.FindingStatusFixed:
+		finding.FixedAt = &now
+	}
+	finding.StatusChangedBy = nil
+	if request.UserID != "" {
+		finding.StatusChangedBy = &request.UserID
+	}
+	finding.UpdatedAt = now
+
+	if err := s.findingRepo.UpdateFinding(ctx, finding); err != nil {
+		return nil, err
+	}
+
+	return finding, nil
+}
+
+// DeleteFinding deletes a finding. An analysis still reporting it opens it again.
+func (s *DefaultFindingService) DeleteFinding(ctx context.Context, request models.DeleteFindingRequest) error {
+	return s.findingRepo.DeleteFinding(ctx, request.FindingID)
+}
+
+// ListFindings lists the findings of a codebase, most recently seen first, with the number of findings in each status.
+// Findings suppressed by the baseline are left out unless the request includes them.
+func (s *DefaultFindingService) ListFindings(ctx context.Context, request models.ListFindingsRequest) (*models.ListFindingsResponse, error) {
+	if _, err := s.codebaseRepo.GetCodebase(ctx, request.CodebaseID); err != nil {
+		return nil, fmt.Errorf("failed to get codebase: %w", err)
+	}
+
+	var baselined *bool
+	if !request.IncludeBaselined {
+		notBaselined := false
+		baselined = &notBaselined
+	}
+	limit := request.Limit
+	if limit == 0 {
+		limit = defaultFindingsLimit
+	}
+	findings, err := s.findingRepo.ListFindings(ctx, request.CodebaseID, repository.FindingFilter{
+		Status:    request.Status,
+		Source:    request.Source,
+		RuleID:    request.RuleID,
+		FilePath:  request.FilePath,
+		Owner:     request.Owner,
+		Baselined: baselined,
+		Limit:     limit,
+		Offset:    request.Offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.findingRepo.CountFindings(ctx, request.CodebaseID, baselined)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ListFindingsResponse{Findings: findings, StatusCounts: counts}, nil
+}
+
+// Rebaseline replaces the baseline of a codebase with its open findings. Findings opened later are reported as new,
+// and findings no longer open lose their suppression, so they are reported again if they come back.
+func (s *DefaultFindingService) Rebaseline(ctx context.Context, request models.RebaselineFindingsRequest) (*models.FindingBaseline, error) {
+	if _, err := s.codebaseRepo.GetCodebase(ctx, request.CodebaseID); err != nil {
+		return nil, fmt.Errorf("failed to get codebase: %w", err)
+	}
+
+	baseline := &models.FindingBaseline{CodebaseID: request.CodebaseID, CreatedAt: s.now().UTC()}
+	if request.UserID != "" {
+		baseline.CreatedBy = &request.UserID
+	}
+	if err := s.findingRepo.Rebaseline(ctx, baseline); err != nil {
+		return nil, err
+	}
+
+	return baseline, nil
+}
+
+// GetBaseline returns the baseline of a codebase and the legacy findings it suppresses, most recently seen first
+func (s *DefaultFindingService) GetBaseline(ctx context.Context, request models.GetFindingBaselineRequest) (*models.FindingBaselineResponse, error) {
+	if _, err := s.codebaseRepo.GetCodebase(ctx, request.CodebaseID); err != nil {
+		return nil, fmt.Errorf("failed to get codebase: %w", err)
+	}
+
+	baseline, err := s.findingRepo.GetBaseline(ctx, request.CodebaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	baselined := true
+	limit := request.Limit
+	if limit == 0 {
+		limit = defaultFindingsLimit
+	}
+	findings, err := s.findingRepo.ListFindings(ctx, request.CodebaseID, repository.FindingFilter{
+		Status:    request.Status,
+		Baselined: &baselined,
+		Limit:     limit,
+		Offset:    request.Offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.findingRepo.CountFindings(ctx, request.CodebaseID, &baselined)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.FindingBaselineResponse{Baseline: *baseline, Findings: findings, StatusCounts: counts}, nil
+}
+
+// SyncFindings records the issues an analysis task reported as the findings of its codebase from source. Ignored
+// findings keep their status whether the task reports them or not. Issues of baselined findings are still recorded but
+// counted as suppressed rather than new, even when they reopen a fixed finding. Findings are owned by the owners of
+// their files in the codebase's recorded CODEOWNERS rules.
+func (s *DefaultFindingService) SyncFindings(ctx context.Context, task *models.TaskWithFullContext, source string, issues []analyzermodels.CodeIssue) (*models.FindingSyncResult, error) {
+	if task.CodebaseID == nil {
+		return nil, fmt.Errorf("task %s has no codebase", task.TaskID)
+	}
+	codebaseID := *task.CodebaseID
+
+	rules, err := s.severityRules(ctx, task.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	owners, err := loadCodeOwners(ctx, s.ownersRepo, codebaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.findingRepo.ListFindings(ctx, codebaseID, repository.FindingFilter{Source: source})
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]*models.Finding, len(existing))
+	for i := range existing {
+		known[existing[i].RuleID+"|"+existing[i].Fingerprint] = &existing[i]
+	}
+
+	now := s.now().UTC()
+	taskID := task.TaskID
+	result := &models.FindingSyncResult{NewBySeverity: map[models.FindingSeverity]int{}}
+	seen := make(map[string]bool, len(issues))
+	occurrences := make(map[string]int, len(issues))
+	var changed []models.Finding
+
+	for i, issue := range issues {
+		incoming := findingFromIssue(codebaseID, source, issue)
+		incoming.Severity = findingSeverity(rules, source, incoming.RuleID, issue.Type)
+		base := incoming.RuleID + "|" + incoming.Fingerprint
+		// Issues reported several times, such as the same message in one file, are told apart by their order
+		if occurrence := occurrences[base]; occurrence > 0 {
+			incoming.Fingerprint = findingFingerprint(source, incoming.RuleID, issueFiles(issue), issue.Message, occurrence)
+		}
+		occurrences[base]++
+		if owners != nil {
+			incoming.Owners = owners.OwnersOf(issueFiles(issue))
+		}
+		key := incoming.RuleID + "|" + incoming.Fingerprint
+		seen[key] = true
+
+		finding, ok := known[key]
+		if !ok {
+			incoming.FindingID = fmt.Sprintf("finding-%s", uuid.New().String())
+			incoming.Status = models.FindingStatusOpen
+			incoming.FirstTaskID = &taskID
+			incoming.LastTaskID = &taskID
+			incoming.FirstSeenAt = now
+			incoming.LastSeenAt = now
+			incoming.UpdatedAt = now
+			changed = append(changed, incoming)
+			result.Opened++
+			result.NewBySeverity[incoming.Severity]++
+			continue
+		}
+
+		finding.Message = incoming.Message
+		finding.FilePath = incoming.FilePath
+		finding.Line = incoming.Line
+		finding.EndLine = incoming.EndLine
+		finding.Owners = incoming.Owners
+		finding.Severity = incoming.Severity
+		finding.LastTaskID = &taskID
+		finding.LastSeenAt = now
+		finding.UpdatedAt = now
+		if finding.Baselined {
+			result.Suppressed++
+			result.SuppressedIssues = append(result.SuppressedIssues, i)
+		}
+		if finding.Status == models.FindingStatusFixed {
+			finding.Status = models.FindingStatusOpen
+			finding.FixedAt = nil
+			finding.StatusChangedBy = nil
+			result.Reopened++
+			if !finding.Baselined {
+				result.NewBySeverity[finding.Severity]++
+			}
+		}
+		changed = append(changed, *finding)
+	}
+
+	for key, finding := range known {
+		if seen[key] || finding.Status != models.FindingStatusOpen {
+			continue
+		}
+		finding.Status = models.FindingStatusFixed
+		finding.FixedAt = &now
+		finding.StatusChangedBy = nil
+		finding.UpdatedAt = now
+		changed = append(changed, *finding)
+		result.Fixed++
+	}
+
+	if err := s.findingRepo.SaveFindings(ctx, changed); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// severityRules returns the severity rules of a project, none when it hasn't configured a quality gate policy
+func (s *DefaultFindingService) severityRules(ctx context.Context, projectID string) ([]models.SeverityRule, error) {
+	policy, err := s.policyRepo.GetPolicy(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quality gate policy: %w", err)
+	}
+	if policy == nil {
+		return nil, nil
+	}
+	return policy.SeverityRules, nil
+}
+
+// findingSeverity returns the severity the first matching rule gives a finding, or the default severity of its kind
+func findingSeverity(rules []models.SeverityRule, source, ruleID string, issueType analyzermodels.IssueType) models.FindingSeverity {
+	for _, rule := range rules {
+		if (rule.Source == "" || rule.Source == source) && (rule.RuleID == "" || rule.RuleID == ruleID) {
+			return rule.Severity
+		}
+	}
+	if severity, ok := defaultFindingSeverities[issueType]; ok {
+		return severity
+	}
+	return models.FindingSeverityMedium
+}
+
+// findingFromIssue converts an analysis issue to a finding of codebase, before its lifecycle fields are set. Issues
+// without a rule are identified by their type.
+func findingFromIssue(codebaseID, source string, issue analyzermodels.CodeIssue) models.Finding {
+	ruleID := issue.RuleID
+	if ruleID == "" {
+		ruleID = string(issue.Type)
+	}
+
+	finding := models.Finding{
+		CodebaseID:  codebaseID,
+		Source:      source,
+		RuleID:      ruleID,
+		Fingerprint: findingFingerprint(source, ruleID, issueFiles(issue), issue.Message, 0),
+		Message:     issue.Message,
+		FilePath:    issue.FilePath,
+		Line:        issue.Line,
+		EndLine:     issue.EndLine,
+	}
+	if finding.FilePath == "" && len(issue.Locations) > 0 {
+		finding.FilePath = issue.Locations[0].FilePath
+		finding.Line = issue.Locations[0].StartLine
+		finding.EndLine = issue.Locations[0].EndLine
+	}
+
+	return finding
+}
+
+// issueFiles returns the files an issue is about, sorted
+func issueFiles(issue analyzermodels.CodeIssue) []string {
+	files := []string{issue.FilePath}
+	for _, location := range issue.Locations {
+		files = append(files, location.FilePath)
+	}
+	slices.Sort(files)
+	return slices.Compact(files)
+}
+
+// findingFingerprint identifies a finding within its rule. Line numbers and other numbers in the message are left out,
+// so the fingerprint survives edits elsewhere in the file; occurrence tells apart identical findings.
+func findingFingerprint(source, ruleID string, files []string, message string, occurrence int) string {
+	hash := sha256.New()
+	for _, part := range []string{source, ruleID, fmt.Sprint(files), digitRuns.ReplaceAllString(message, "#"), strconv.Itoa(occurrence)} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil)[:16])
+}
@@ -0,0 +1,294 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+	analyzermodels "github.com/kazemisoroush/code-refactoring-tool/pkg/analyzer/models"
+)
+
+var findingNow = time.Date(2024, time.January, 22, 10, 30, 0, 0, time.UTC)
+
+func newTestFindingService(ctrl *gomock.Controller) (*DefaultFindingService, *repositoryMocks.MockFindingRepository, *repositoryMocks.MockCodebaseRepository, *repositoryMocks.MockQualityGatePolicyRepository) {
+	findingRepo := repositoryMocks.NewMockFindingRepository(ctrl)
+	codebaseRepo := repositoryMocks.NewMockCodebaseRepository(ctrl)
+	policyRepo := repositoryMocks.NewMockQualityGatePolicyRepository(ctrl)
+	// Codebases have no recorded owners unless a test replaces the repository
+	ownersRepo := repositoryMocks.NewMockCodeOwnersRepository(ctrl)
+	ownersRepo.EXPECT().GetCodeOwners(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	service := NewDefaultFindingService(findingRepo, codebaseRepo, policyRepo, ownersRepo)
+	service.now = func() time.Time { return findingNow }
+	return service, findingRepo, codebaseRepo, policyRepo
+}
+
+func TestFindingService_SyncFindings_Lifecycle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service, findingRepo, _, policyRepo := newTestFindingService(ctrl)
+
+	codebaseID := "cb-1"
+	task := &models.TaskWithFullContext{Task: models.Task{TaskID: "task-2", ProjectID: "proj-1", CodebaseID: &codebaseID}}
+	still := analyzermodels.CodeIssue{RuleID: "unused-function", Message: "function f is unused (12 lines)", FilePath: "a.go", Line: 40}
+	returned := analyzermodels.CodeIssue{RuleID: "unused-function", Message: "function g is unused", FilePath: "b.go", Line: 3}
+	ignored := analyzermodels.CodeIssue{RuleID: "unused-function", Message: "function h is unused", FilePath: "c.go", Line: 7}
+	gone := analyzermodels.CodeIssue{RuleID: "unused-function", Message: "function k is unused", FilePath: "d.go", Line: 9}
+	fresh := analyzermodels.CodeIssue{Type: analyzermodels.IssueTypeLinter, Message: "shadowed err", FilePath: "e.go", Line: 1}
+
+	existingFinding := func(issue analyzermodels.CodeIssue, status models.FindingStatus) models.Finding {
+		finding := findingFromIssue(codebaseID, "dead_code", issue)
+		finding.FindingID = "finding-" + issue.FilePath
+		finding.Status = status
+		return finding
+	}
+	earlier := still
+	earlier.Message = "function f is unused (9 lines)"
+	earlier.Line = 12
+	firstSeen := existingFinding(earlier, models.FindingStatusOpen)
+	fixedAt := findingNow.Add(-time.Hour)
+	reopened := existingFinding(returned, models.FindingStatusFixed)
+	reopened.FixedAt = &fixedAt
+
+	policyRepo.EXPECT().GetPolicy(gomock.Any(), "proj-1").Return(&models.QualityGatePolicy{
+		SeverityRules: []models.SeverityRule{{RuleID: "linter", Severity: models.FindingSeverityCritical}},
+	}, nil)
+	findingRepo.EXPECT().
+		ListFindings(gomock.Any(), "cb-1", repository.FindingFilter{Source: "dead_code"}).
+		Return([]models.Finding{firstSeen, reopened, existingFinding(ignored, models.FindingStatusIgnored), existingFinding(gone, models.FindingStatusOpen)}, nil)
+
+	var saved []models.Finding
+	findingRepo.EXPECT().SaveFindings(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, findings []models.Finding) error {
+		saved = findings
+		return nil
+	})
+
+	result, err := service.SyncFindings(context.Background(), task, "dead_code", []analyzermodels.CodeIssue{still, returned, ignored, fresh})
+
+	require.NoError(t, err)
+	assert.Equal(t, &models.FindingSyncResult{
+		Opened:        1,
+		Reopened:      1,
+		Fixed:         1,
+		NewBySeverity: map[models.FindingSeverity]int{models.FindingSeverityCritical: 1, models.FindingSeverityMedium: 1},
+	}, result)
+
+	byPath := map[string]models.Finding{}
+	for _, finding := range saved {
+		byPath[finding.FilePath] = finding
+	}
+	require.Len(t, byPath, 5)
+	assert.Equal(t, "finding-a.go", byPath["a.go"].FindingID, "numbers in the message don't change the fingerprint")
+	assert.Equal(t, 40, byPath["a.go"].Line)
+	assert.Equal(t, models.FindingStatusOpen, byPath["b.go"].Status)
+	assert.Nil(t, byPath["b.go"].FixedAt)
+	assert.Equal(t, models.FindingStatusIgnored, byPath["c.go"].Status)
+	assert.Equal(t, models.FindingStatusFixed, byPath["d.go"].Status)
+	assert.Equal(t, findingNow, *byPath["d.go"].FixedAt)
+	assert.Equal(t, "linter", byPath["e.go"].RuleID, "issues without a rule are identified by their type")
+	assert.Equal(t, "task-2", *byPath["e.go"].FirstTaskID)
+	assert.Equal(t, models.FindingSeverityCritical, byPath["e.go"].Severity, "severity rules win over the default severities")
+}
+
+func TestFindingService_SyncFindings_TellsApartRepeatedIssues(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service, findingRepo, _, policyRepo := newTestFindingService(ctrl)
+
+	codebaseID := "cb-1"
+	task := &models.TaskWithFullContext{Task: models.Task{TaskID: "task-1", ProjectID: "proj-1", CodebaseID: &codebaseID}}
+	issue := analyzermodels.CodeIssue{RuleID: "errcheck", Message: "error not checked", FilePath: "a.go"}
+
+	policyRepo.EXPECT().GetPolicy(gomock.Any(), "proj-1").Return(nil, nil)
+	findingRepo.EXPECT().ListFindings(gomock.Any(), "cb-1", gomock.Any()).Return(nil, nil)
+	var saved []models.Finding
+	findingRepo.EXPECT().SaveFindings(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, findings []models.Finding) error {
+		saved = findings
+		return nil
+	})
+
+	result, err := service.SyncFindings(context.Background(), task, "lint", []analyzermodels.CodeIssue{issue, issue})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Opened)
+	require.Len(t, saved, 2)
+	assert.NotEqual(t, saved[0].Fingerprint, saved[1].Fingerprint)
+}
+
+func TestFindingService_SyncFindings_SuppressesBaselinedFindings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service, findingRepo, _, policyRepo := newTestFindingService(ctrl)
+
+	codebaseID := "cb-1"
+	task := &models.TaskWithFullContext{Task: models.Task{TaskID: "task-2", ProjectID: "proj-1", CodebaseID: &codebaseID}}
+	fresh := analyzermodels.CodeIssue{RuleID: "errcheck", Message: "error not checked", FilePath: "new.go"}
+	legacy := analyzermodels.CodeIssue{RuleID: "errcheck", Message: "error not checked", FilePath: "legacy.go"}
+	returned := analyzermodels.CodeIssue{RuleID: "errcheck", Message: "error not checked", FilePath: "returned.go"}
+
+	baselinedFinding := func(issue analyzermodels.CodeIssue, status models.FindingStatus) models.Finding {
+		finding := findingFromIssue(codebaseID, "lint", issue)
+		finding.FindingID = "finding-" + issue.FilePath
+		finding.Status = status
+		finding.Baselined = true
+		return finding
+	}
+
+	policyRepo.EXPECT().GetPolicy(gomock.Any(), "proj-1").Return(nil, nil)
+	findingRepo.EXPECT().ListFindings(gomock.Any(), "cb-1", gomock.Any()).Return([]models.Finding{
+		baselinedFinding(legacy, models.FindingStatusOpen),
+		baselinedFinding(returned, models.FindingStatusFixed),
+	}, nil)
+	findingRepo.EXPECT().SaveFindings(gomock.Any(), gomock.Len(3)).Return(nil)
+
+	result, err := service.SyncFindings(context.Background(), task, "lint", []analyzermodels.CodeIssue{legacy, fresh, returned})
+
+	require.NoError(t, err)
+	assert.Equal(t, &models.FindingSyncResult{
+		Opened:           1,
+		Reopened:         1,
+		NewBySeverity:    map[models.FindingSeverity]int{models.FindingSeverityMedium: 1},
+		Suppressed:       2,
+		SuppressedIssues: []int{0, 2},
+	}, result)
+}
+
+func TestFindingService_Rebaseline(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service, findingRepo, codebaseRepo, _ := newTestFindingService(ctrl)
+
+	codebaseRepo.EXPECT().GetCodebase(gomock.Any(), "cb-1").Return(&models.Codebase{CodebaseID: "cb-1"}, nil)
+	findingRepo.EXPECT().Rebaseline(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, baseline *models.FindingBaseline) error {
+		baseline.FindingCount = 1342
+		return nil
+	})
+
+	baseline, err := service.Rebaseline(context.Background(), models.RebaselineFindingsRequest{CodebaseID: "cb-1", UserID: "user-1"})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1342, baseline.FindingCount)
+	assert.Equal(t, "user-1", *baseline.CreatedBy)
+	assert.Equal(t, findingNow, baseline.CreatedAt)
+}
+
+func TestFindingService_GetBaseline_ListsSuppressedFindings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service, findingRepo, codebaseRepo, _ := newTestFindingService(ctrl)
+
+	baselined := true
+	codebaseRepo.EXPECT().GetCodebase(gomock.Any(), "cb-1").Return(&models.Codebase{CodebaseID: "cb-1"}, nil)
+	findingRepo.EXPECT().GetBaseline(gomock.Any(), "cb-1").Return(&models.FindingBaseline{CodebaseID: "cb-1", FindingCount: 2}, nil)
+	findingRepo.EXPECT().
+		ListFindings(gomock.Any(), "cb-1", repository.FindingFilter{Baselined: &baselined, Limit: 10}).
+		Return([]models.Finding{{FindingID: "finding-1", Baselined: true}}, nil)
+	findingRepo.EXPECT().CountFindings(gomock.Any(), "cb-1", &baselined).Return(map[models.FindingStatus]int{models.FindingStatusOpen: 1, models.FindingStatusFixed: 1}, nil)
+
+	response, err := service.GetBaseline(context.Background(), models.GetFindingBaselineRequest{CodebaseID: "cb-1", Limit: 10})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, response.Baseline.FindingCount)
+	assert.Len(t, response.Findings, 1)
+	assert.Equal(t, 1, response.StatusCounts[models.FindingStatusFixed])
+}
+
+func TestFindingService_UpdateFinding_IgnoreRequiresJustification(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service, _, _, _ := newTestFindingService(ctrl)
+
+	_, err := service.UpdateFinding(context.Background(), models.UpdateFindingRequest{
+		FindingID: "finding-1",
+		Status:    models.FindingStatusIgnored,
+	})
+
+	assert.True(t, errors.Is(err, apperrors.ErrValidation))
+	assert.Equal(t, apperrors.CodeJustificationRequired, apperrors.CodeOf(err))
+}
+
+func TestFindingService_UpdateFinding_Ignore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service, findingRepo, _, _ := newTestFindingService(ctrl)
+
+	findingRepo.EXPECT().GetFinding(gomock.Any(), "finding-1").Return(&models.Finding{FindingID: "finding-1", Status: models.FindingStatusOpen}, nil)
+	findingRepo.EXPECT().UpdateFinding(gomock.Any(), gomock.Any()).Return(nil)
+
+	finding, err := service.UpdateFinding(context.Background(), models.UpdateFindingRequest{
+		FindingID:     "finding-1",
+		Status:        models.FindingStatusIgnored,
+		Justification: "Called through reflection",
+		UserID:        "user-1",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.FindingStatusIgnored, finding.Status)
+	assert.Equal(t, "Called through reflection", *finding.Justification)
+	assert.Equal(t, "user-1", *finding.StatusChangedBy)
+	assert.Equal(t, findingNow, finding.UpdatedAt)
+}
+
+func TestFindingService_SyncFindings_SetsOwners(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service, findingRepo, _, policyRepo := newTestFindingService(ctrl)
+	ownersRepo := repositoryMocks.NewMockCodeOwnersRepository(ctrl)
+	service.ownersRepo = ownersRepo
+
+	codebaseID := "cb-1"
+	task := &models.TaskWithFullContext{Task: models.Task{TaskID: "task-1", ProjectID: "proj-1", CodebaseID: &codebaseID}}
+	cycle := analyzermodels.CodeIssue{RuleID: "import-cycle", Message: "import cycle", FilePath: "billing/invoice.go",
+		Locations: []analyzermodels.CodeLocation{{FilePath: "payments/client.go"}}}
+	unowned := analyzermodels.CodeIssue{RuleID: "errcheck", Message: "error not checked", FilePath: "tools/gen.go"}
+	known := findingFromIssue(codebaseID, "lint", unowned)
+	known.FindingID = "finding-1"
+	known.Status = models.FindingStatusOpen
+	known.Owners = []string{"@former-owner"}
+
+	ownersRepo.EXPECT().GetCodeOwners(gomock.Any(), "cb-1").Return(&models.CodeOwners{CodebaseID: "cb-1", Rules: []models.CodeOwnerRule{
+		{Pattern: "/billing/", Owners: []string{"@acme/billing"}},
+		{Pattern: "/payments/", Owners: []string{"@acme/payments", "@alice"}},
+	}}, nil)
+	policyRepo.EXPECT().GetPolicy(gomock.Any(), "proj-1").Return(nil, nil)
+	findingRepo.EXPECT().ListFindings(gomock.Any(), "cb-1", gomock.Any()).Return([]models.Finding{known}, nil)
+	var saved []models.Finding
+	findingRepo.EXPECT().SaveFindings(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, findings []models.Finding) error {
+		saved = findings
+		return nil
+	})
+
+	_, err := service.SyncFindings(context.Background(), task, "lint", []analyzermodels.CodeIssue{cycle, unowned})
+
+	require.NoError(t, err)
+	require.Len(t, saved, 2)
+	assert.Equal(t, []string{"@acme/billing", "@acme/payments", "@alice"}, saved[0].Owners, "findings are owned by the owners of all their files")
+	assert.Empty(t, saved[1].Owners, "ownership changes replace the owners of known findings")
+}
+
+func TestFindingService_ListFindings_DefaultsLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service, findingRepo, codebaseRepo, _ := newTestFindingService(ctrl)
+
+	baselined := false
+	codebaseRepo.EXPECT().GetCodebase(gomock.Any(), "cb-1").Return(&models.Codebase{CodebaseID: "cb-1"}, nil)
+	findingRepo.EXPECT().
+		ListFindings(gomock.Any(), "cb-1", repository.FindingFilter{Source: "lint", Baselined: &baselined, Limit: defaultFindingsLimit}).
+		Return([]models.Finding{{FindingID: "finding-1"}}, nil)
+	findingRepo.EXPECT().CountFindings(gomock.Any(), "cb-1", &baselined).Return(map[models.FindingStatus]int{models.FindingStatusOpen: 1}, nil)
+
+	response, err := service.ListFindings(context.Background(), models.ListFindingsRequest{CodebaseID: "cb-1", Source: "lint"})
+
+	require.NoError(t, err)
+	assert.Len(t, response.Findings, 1)
+	assert.Equal(t, 1, response.StatusCounts[models.FindingStatusOpen])
+}
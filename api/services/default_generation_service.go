@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/agent"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// errStreamClosed is returned once the model's answer can't be streamed any longer, such as when the client left
+var errStreamClosed = errors.New("failed to stream the answer")
+
+// DefaultGenerationService is the default implementation of GenerationService, prompting the configured model.
+// Answers not matching the expected schema are sent back to the model to repair, a configured number of times.
+type DefaultGenerationService struct {
+	taskRepo  repository.TaskRepository
+	model     agent.Agent
+	modelName string
+	cfg       config.GenerationConfig
+}
+
+// NewDefaultGenerationService creates a new DefaultGenerationService prompting model, reported as modelName
+func NewDefaultGenerationService(taskRepo repository.TaskRepository, model agent.Agent, modelName string, cfg config.GenerationConfig) *DefaultGenerationService {
+	return &DefaultGenerationService{
+		taskRepo:  taskRepo,
+		model:     model,
+		modelName: modelName,
+		cfg:       cfg,
+	}
+}
+
+// GeneratePRDescription describes a diff, or the diff of a task's output, as a pull request. A task's title and
+// description are what its change is for, unless the request says otherwise. Diffs longer than the configured length
+// are described from their beginning.
+func (s *DefaultGenerationService) GeneratePRDescription(ctx context.Context, req models.GeneratePRDescriptionRequest) (*models.PRDescription, error) {
+	return s.describe(ctx, req, nil)
+}
+
+// StreamPRDescription is GeneratePRDescription streaming the model's answers to emit as they're generated. Every
+// repair of an invalid answer is announced with a retry event before the repaired answer is streamed.
+func (s *DefaultGenerationService) StreamPRDescription(ctx context.Context, req models.GeneratePRDescriptionRequest, emit func(models.StreamEvent) error) (*models.PRDescription, error) {
+	return s.describe(ctx, req, emit)
+}
+
+// describe describes a change as a pull request, streaming the model's answers to emit unless it's nil
+func (s *DefaultGenerationService) describe(ctx context.Context, req models.GeneratePRDescriptionRequest, emit func(models.StreamEvent) error) (*models.PRDescription, error) {
+	diff, purpose := req.Diff, req.Context
+	if req.TaskID != "" {
+		task, err := s.taskRepo.GetByID(ctx, req.TaskID)
+		if err != nil {
+			return nil, err
+		}
+		diff, _ = task.Output["diff"].(string)
+		if diff == "" {
+			return nil, apperrors.Validation(apperrors.CodeTaskHasNoDiff, "task %s has no diff to describe", req.TaskID)
+		}
+		if purpose == "" {
+			purpose = strings.TrimSpace(task.Title + "\n\n" + task.Description)
+		}
+	}
+
+	truncated := strings.HasSuffix(diff, truncatedDiffMarker)
+	diff = strings.TrimSuffix(diff, truncatedDiffMarker)
+	if len(diff) > s.cfg.MaxDiffLength {
+		diff, truncated = diff[:s.cfg.MaxDiffLength], true
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	defer cancel()
+
+	var answer string
+	var invalid error
+	for repairs := 0; ; repairs++ {
+		var err error
+		answer, err = s.ask(ctx, prDescriptionPrompt(purpose, diff, truncated, answer, invalid), invalid, emit)
+		if errors.Is(err, errStreamClosed) {
+			return nil, err
+		}
+		if err != nil {
+			return nil, apperrors.BadGateway(apperrors.CodeBadGateway, err, "model %s failed to describe the change", s.modelName)
+		}
+
+		description, err := parsePRDescription(answer)
+		if err == nil {
+			description.Model = s.modelName
+			description.Truncated = truncated
+			return description, nil
+		}
+		if repairs >= s.cfg.MaxRepairs {
+			return nil, apperrors.BadGateway(apperrors.CodeInvalidModelAnswer, err, "model %s answered with an invalid description", s.modelName)
+		}
+		invalid = err
+	}
+}
+
+// ask prompts the model, streaming its answer to emit unless it's nil. A prompt repairing an invalid answer is
+// announced with a retry event first.
+func (s *DefaultGenerationService) ask(ctx context.Context, prompt string, invalid error, emit func(models.StreamEvent) error) (string, error) {
+	if emit == nil {
+		return s.model.Ask(ctx, prompt)
+	}
+
+	if invalid != nil {
+		if err := emit(models.StreamEvent{Event: models.StreamEventRetry, Data: models.StreamRetry{Reason: invalid.Error()}}); err != nil {
+			return "", fmt.Errorf("%w: %w", errStreamClosed, err)
+		}
+	}
+	return agent.AskStream(ctx, s.model, prompt, func(chunk string) error {
+		if err := emit(models.StreamEvent{Event: models.StreamEventChunk, Data: models.StreamChunk{Text: chunk}}); err != nil {
+			return fmt.Errorf("%w: %w", errStreamClosed, err)
+		}
+		return nil
+	})
+}
+
+// parsePRDescription validates a model's answer against prDescriptionSchema and decodes it
+func parsePRDescription(answer string) (*models.PRDescription, error) {
+	value, err := ValidateTaskAnswer(prDescriptionSchema, answer)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode answer: %w", err)
+	}
+	var description models.PRDescription
+	if err := json.Unmarshal(data, &description); err != nil {
+		return nil, fmt.Errorf("failed to decode answer: %w", err)
+	}
+	return &description, nil
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+	agentMocks "github.com/kazemisoroush/code-refactoring-tool/pkg/ai/agent/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+const testPRDescriptionAnswer = "```json\n" + `{
+  "title": "Return an error when dividing by zero",
+  "body": "Div panicked when the divisor was zero.",
+  "changelog": {"type": "fixed", "summary": "Dividing by zero returns an error"}
+}` + "\n```"
+
+type generationServiceMocks struct {
+	taskRepo *repositoryMocks.MockTaskRepository
+	model    *agentMocks.MockAgent
+}
+
+func newTestGenerationService(t *testing.T) (*DefaultGenerationService, generationServiceMocks) {
+	ctrl := gomock.NewController(t)
+	m := generationServiceMocks{
+		taskRepo: repositoryMocks.NewMockTaskRepository(ctrl),
+		model:    agentMocks.NewMockAgent(ctrl),
+	}
+	return NewDefaultGenerationService(m.taskRepo, m.model, "qwen2.5-coder:7b", config.GenerationConfig{
+		MaxDiffLength: 64,
+		MaxRepairs:    1,
+		Timeout:       time.Minute,
+	}), m
+}
+
+func TestGenerationService_GeneratePRDescription(t *testing.T) {
+	service, m := newTestGenerationService(t)
+
+	m.model.EXPECT().Ask(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, prompt string) (string, error) {
+		assert.Contains(t, prompt, "Fixes #42")
+		assert.Contains(t, prompt, "+func Div")
+		assert.NotContains(t, prompt, "only the beginning is shown")
+		return testPRDescriptionAnswer, nil
+	})
+
+	description, err := service.GeneratePRDescription(context.Background(), models.GeneratePRDescriptionRequest{
+		Diff:    "+func Div(a, b int) (int, error)",
+		Context: "Fixes #42",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, &models.PRDescription{
+		Title:     "Return an error when dividing by zero",
+		Body:      "Div panicked when the divisor was zero.",
+		Changelog: models.ChangelogEntry{Type: models.ChangelogTypeFixed, Summary: "Dividing by zero returns an error"},
+		Model:     "qwen2.5-coder:7b",
+	}, description)
+}
+
+func TestGenerationService_GeneratePRDescription_Task(t *testing.T) {
+	service, m := newTestGenerationService(t)
+
+	m.taskRepo.EXPECT().GetByID(gomock.Any(), "task-1").Return(&models.Task{
+		TaskID:      "task-1",
+		Title:       "Fix Div",
+		Description: "Return an error instead of panicking",
+		Output:      map[string]any{"diff": truncateDiff("+func Div(a, b int) (int, error)")},
+	}, nil)
+	m.model.EXPECT().Ask(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, prompt string) (string, error) {
+		assert.Contains(t, prompt, "Fix Div\n\nReturn an error instead of panicking")
+		return testPRDescriptionAnswer, nil
+	})
+
+	description, err := service.GeneratePRDescription(context.Background(), models.GeneratePRDescriptionRequest{TaskID: "task-1"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Return an error when dividing by zero", description.Title)
+	assert.False(t, description.Truncated)
+}
+
+func TestGenerationService_GeneratePRDescription_TruncatesDiff(t *testing.T) {
+	service, m := newTestGenerationService(t)
+
+	m.model.EXPECT().Ask(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, prompt string) (string, error) {
+		assert.Contains(t, prompt, "only the beginning is shown")
+		assert.NotContains(t, prompt, "left out")
+		return testPRDescriptionAnswer, nil
+	})
+
+	diff := "+" + string(make([]byte, 64)) + "left out"
+	description, err := service.GeneratePRDescription(context.Background(), models.GeneratePRDescriptionRequest{Diff: diff})
+
+	require.NoError(t, err)
+	assert.True(t, description.Truncated)
+}
+
+func TestGenerationService_GeneratePRDescription_RepairsInvalidAnswer(t *testing.T) {
+	service, m := newTestGenerationService(t)
+
+	gomock.InOrder(
+		m.model.EXPECT().Ask(gomock.Any(), gomock.Any()).Return(`{"title": "Fix Div"}`, nil),
+		m.model.EXPECT().Ask(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, prompt string) (string, error) {
+			assert.Contains(t, prompt, "Your previous answer was:\n{\"title\": \"Fix Div\"}")
+			assert.Contains(t, prompt, "Your answer is invalid: ")
+			return testPRDescriptionAnswer, nil
+		}),
+	)
+
+	description, err := service.GeneratePRDescription(context.Background(), models.GeneratePRDescriptionRequest{Diff: "+func Div"})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.ChangelogTypeFixed, description.Changelog.Type)
+}
+
+func TestGenerationService_GeneratePRDescription_InvalidAnswer(t *testing.T) {
+	service, m := newTestGenerationService(t)
+
+	m.model.EXPECT().Ask(gomock.Any(), gomock.Any()).Return("Here is your description", nil).Times(2)
+
+	_, err := service.GeneratePRDescription(context.Background(), models.GeneratePRDescriptionRequest{Diff: "+func Div"})
+
+	assert.ErrorIs(t, err, apperrors.ErrBadGateway)
+	assert.Equal(t, apperrors.CodeInvalidModelAnswer, apperrors.CodeOf(err))
+}
+
+func TestGenerationService_GeneratePRDescription_TaskWithoutDiff(t *testing.T) {
+	service, m := newTestGenerationService(t)
+
+	m.taskRepo.EXPECT().GetByID(gomock.Any(), "task-1").Return(&models.Task{TaskID: "task-1", Output: map[string]any{}}, nil)
+
+	_, err := service.GeneratePRDescription(context.Background(), models.GeneratePRDescriptionRequest{TaskID: "task-1"})
+
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	assert.Equal(t, apperrors.CodeTaskHasNoDiff, apperrors.CodeOf(err))
+}
+
+func TestGenerationService_StreamPRDescription_AnnouncesRepairs(t *testing.T) {
+	service, m := newTestGenerationService(t)
+
+	gomock.InOrder(
+		m.model.EXPECT().Ask(gomock.Any(), gomock.Any()).Return(`{"title": "Fix Div"}`, nil),
+		m.model.EXPECT().Ask(gomock.Any(), gomock.Any()).Return(testPRDescriptionAnswer, nil),
+	)
+
+	var events []models.StreamEvent
+	description, err := service.StreamPRDescription(context.Background(), models.GeneratePRDescriptionRequest{Diff: "+func Div"}, func(event models.StreamEvent) error {
+		events = append(events, event)
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Return an error when dividing by zero", description.Title)
+	require.Len(t, events, 3)
+	assert.Equal(t, models.StreamEvent{Event: models.StreamEventChunk, Data: models.StreamChunk{Text: `{"title": "Fix Div"}`}}, events[0])
+	assert.Equal(t, models.StreamEventRetry, events[1].Event)
+	assert.Equal(t, models.StreamEvent{Event: models.StreamEventChunk, Data: models.StreamChunk{Text: testPRDescriptionAnswer}}, events[2])
+}
+
+func TestGenerationService_StreamPRDescription_ClientGone(t *testing.T) {
+	service, m := newTestGenerationService(t)
+
+	m.model.EXPECT().Ask(gomock.Any(), gomock.Any()).Return(testPRDescriptionAnswer, nil)
+
+	_, err := service.StreamPRDescription(context.Background(), models.GeneratePRDescriptionRequest{Diff: "+func Div"}, func(models.StreamEvent) error {
+		return errors.New("broken pipe")
+	})
+
+	assert.ErrorIs(t, err, errStreamClosed)
+	assert.NotErrorIs(t, err, apperrors.ErrBadGateway, "the model didn't fail")
+}
@@ -0,0 +1,524 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	analyzermodels "github.com/kazemisoroush/code-refactoring-tool/pkg/analyzer/models"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/gitprovider"
+)
+
+// checkPublishBatchSize is the number of check runs published per batch
+const checkPublishBatchSize = 50
+
+// Limits of the tasks pull requests trigger, matching the validation of CreateTaskRequest
+const (
+	maxPullRequestTaskTitle       = 200
+	maxPullRequestTaskDescription = 2000
+)
+
+// pullRequestActions are the pull request actions that run a task on the pull request's head commit
+var pullRequestActions = map[string]bool{"opened": true, "synchronize": true, "reopened": true}
+
+// checkSeverityRank orders the severities of findings
+var checkSeverityRank = map[models.CheckSeverity]int{
+	models.CheckSeverityInfo:    0,
+	models.CheckSeverityWarning: 1,
+	models.CheckSeverityError:   2,
+}
+
+// checkAnnotationLevels maps the severities of findings to the levels of check run annotations
+var checkAnnotationLevels = map[models.CheckSeverity]string{
+	models.CheckSeverityInfo:    gitprovider.AnnotationLevelNotice,
+	models.CheckSeverityWarning: gitprovider.AnnotationLevelWarning,
+	models.CheckSeverityError:   gitprovider.AnnotationLevelFailure,
+}
+
+// DefaultGitHubCheckService is the default implementation of GitHubCheckService
+type DefaultGitHubCheckService struct {
+	appRepo      repository.GitHubAppConfigRepository
+	checkRepo    repository.GitHubCheckRepository
+	codebaseRepo repository.CodebaseRepository
+	taskService  TaskService
+	publisher    gitprovider.ChecksPublisher
+	config       config.GitHubChecksConfig
+	now          func() time.Time
+
+	// running tracks the tasks pull requests started
+	running sync.WaitGroup
+}
+
+// NewDefaultGitHubCheckService creates a new DefaultGitHubCheckService
+func NewDefaultGitHubCheckService(
+	appRepo repository.GitHubAppConfigRepository,
+	checkRepo repository.GitHubCheckRepository,
+	codebaseRepo repository.CodebaseRepository,
+	taskService TaskService,
+	publisher gitprovider.ChecksPublisher,
+	config config.GitHubChecksConfig,
+) *DefaultGitHubCheckService {
+	return &DefaultGitHubCheckService{
+		appRepo:      appRepo,
+		checkRepo:    checkRepo,
+		codebaseRepo: codebaseRepo,
+		taskService:  taskService,
+		publisher:    publisher,
+		config:       config,
+		now:          time.Now,
+	}
+}
+
+// PutAppConfig configures the GitHub App of an organization, replacing its configuration
+func (s *DefaultGitHubCheckService) PutAppConfig(ctx context.Context, request models.PutGitHubAppConfigRequest) (*models.GitHubAppConfig, error) {
+	if _, err := gitprovider.ParseRSAPrivateKey(request.PrivateKey); err != nil {
+		return nil, apperrors.Validation(apperrors.CodeInvalidRequest, "private_key is not a PEM encoded RSA key: %v", err)
+	}
+
+	taskType := request.TaskType
+	if taskType == "" {
+		taskType = models.TaskTypeCodeReview
+	}
+	failOnSeverity := request.FailOnSeverity
+	if failOnSeverity == "" {
+		failOnSeverity = models.CheckSeverityError
+	}
+
+	now := s.now().UTC()
+	appConfig := &models.GitHubAppConfig{
+		Organization:   request.Organization,
+		AppID:          request.AppID,
+		PrivateKey:     request.PrivateKey,
+		WebhookSecret:  request.WebhookSecret,
+		ProjectID:      request.ProjectID,
+		AgentID:        request.AgentID,
+		TaskType:       taskType,
+		FailOnSeverity: failOnSeverity,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := s.appRepo.PutConfig(ctx, appConfig); err != nil {
+		return nil, fmt.Errorf("failed to put GitHub App configuration: %w", err)
+	}
+
+	return appConfig, nil
+}
+
+// GetAppConfig gets the GitHub App configuration of an organization
+func (s *DefaultGitHubCheckService) GetAppConfig(ctx context.Context, request models.GetGitHubAppConfigRequest) (*models.GitHubAppConfig, error) {
+	appConfig, err := s.appRepo.GetConfig(ctx, request.Organization)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get GitHub App configuration: %w", err)
+	}
+
+	return appConfig, nil
+}
+
+// ListAppConfigs lists the GitHub App configurations ordered by organization
+func (s *DefaultGitHubCheckService) ListAppConfigs(ctx context.Context) (*models.ListGitHubAppConfigsResponse, error) {
+	configs, err := s.appRepo.ListConfigs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list GitHub App configurations: %w", err)
+	}
+
+	return &models.ListGitHubAppConfigsResponse{Configs: configs}, nil
+}
+
+// DeleteAppConfig deletes the GitHub App configuration of an organization
+func (s *DefaultGitHubCheckService) DeleteAppConfig(ctx context.Context, request models.DeleteGitHubAppConfigRequest) error {
+	if err := s.appRepo.DeleteConfig(ctx, request.Organization); err != nil {
+		return fmt.Errorf("failed to delete GitHub App configuration: %w", err)
+	}
+
+	return nil
+}
+
+// githubWebhookPayload holds the fields of the webhook payloads the service reads
+type githubWebhookPayload struct {
+	Action      string `json:"action"`
+	Number      int    `json:"number"`
+	PullRequest *struct {
+		Title   string `json:"title"`
+		Body    string `json:"body"`
+		HTMLURL string `json:"html_url"`
+		Head    struct {
+			SHA string `json:"sha"`
+		} `json:"head"`
+	} `json:"pull_request"`
+	Repository *struct {
+		FullName string `json:"full_name"`
+		Owner    struct {
+			Login string `json:"login"`
+		} `json:"owner"`
+	} `json:"repository"`
+	Organization *struct {
+		Login string `json:"login"`
+	} `json:"organization"`
+	Installation *struct {
+		ID int64 `json:"id"`
+	} `json:"installation"`
+}
+
+// HandleWebhook verifies the signature of a delivery with the webhook secret of the organization owning the
+// repository, then starts a task on the head commit of an opened or updated pull request. Deliveries for an
+// organization without a configuration are rejected like those with a wrong signature, so they can't tell which
+// organizations are configured.
+func (s *DefaultGitHubCheckService) HandleWebhook(ctx context.Context, event, signature string, payload []byte) (*models.GitHubWebhookResponse, error) {
+	var delivery githubWebhookPayload
+	if err := json.Unmarshal(payload, &delivery); err != nil {
+		return nil, apperrors.Validation(apperrors.CodeInvalidRequest, "webhook payload is not valid JSON: %v", err)
+	}
+
+	var organization string
+	switch {
+	case delivery.Repository != nil:
+		organization = delivery.Repository.Owner.Login
+	case delivery.Organization != nil:
+		organization = delivery.Organization.Login
+	default:
+		return nil, apperrors.Validation(apperrors.CodeInvalidRequest, "webhook payload names no repository or organization")
+	}
+
+	appConfig, err := s.appRepo.GetConfig(ctx, organization)
+	if err != nil && !errors.Is(err, apperrors.ErrNotFound) {
+		return nil, fmt.Errorf("failed to get GitHub App configuration: %w", err)
+	}
+	if err != nil || !validWebhookSignature(appConfig.WebhookSecret, signature, payload) {
+		return nil, apperrors.Unauthorized(apperrors.CodeInvalidSignature, "webhook signature doesn't match the payload")
+	}
+
+	if event != "pull_request" {
+		return &models.GitHubWebhookResponse{Ignored: fmt.Sprintf("event %s", event)}, nil
+	}
+	if !pullRequestActions[delivery.Action] {
+		return &models.GitHubWebhookResponse{Ignored: fmt.Sprintf("pull request action %s", delivery.Action)}, nil
+	}
+	if delivery.PullRequest == nil || delivery.Repository == nil || delivery.Installation == nil {
+		return nil, apperrors.Validation(apperrors.CodeInvalidRequest, "pull request payload misses its pull request, repository or installation")
+	}
+
+	codebase, err := s.findCodebase(ctx, appConfig.ProjectID, delivery.Repository.FullName)
+	if err != nil {
+		return nil, err
+	}
+
+	pullRequest := delivery.PullRequest
+	created, err := s.taskService.CreateTask(ctx, &models.CreateTaskRequest{
+		ProjectID:   appConfig.ProjectID,
+		AgentID:     appConfig.AgentID,
+		CodebaseID:  &codebase.CodebaseID,
+		CommitSHA:   pullRequest.Head.SHA,
+		Type:        appConfig.TaskType,
+		Title:       truncateRunes(fmt.Sprintf("%s#%d: %s", delivery.Repository.FullName, delivery.Number, pullRequest.Title), maxPullRequestTaskTitle),
+		Description: truncateRunes(pullRequestDescription(pullRequest.Title, pullRequest.Body), maxPullRequestTaskDescription),
+		Metadata:    map[string]string{"pull_request": truncateRunes(pullRequest.HTMLURL, 100)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pull request task: %w", err)
+	}
+
+	app := gitprovider.GitHubApp{AppID: appConfig.AppID, InstallationID: delivery.Installation.ID, PrivateKey: appConfig.PrivateKey}
+	checkRunID, err := s.publisher.CreateCheckRun(ctx, app, delivery.Repository.FullName, gitprovider.CheckRun{
+		Name:       s.config.CheckName,
+		HeadSHA:    pullRequest.Head.SHA,
+		ExternalID: created.TaskID,
+		DetailsURL: s.detailsURL(created.TaskID),
+		Status:     gitprovider.CheckStatusQueued,
+	})
+	if err != nil {
+		s.deleteTask(ctx, created.TaskID)
+		return nil, apperrors.BadGateway(apperrors.CodeBadGateway, err, "failed to create the check run of pull request %d", delivery.Number)
+	}
+
+	check := &models.GitHubCheck{
+		TaskID:         created.TaskID,
+		Organization:   organization,
+		Repository:     delivery.Repository.FullName,
+		InstallationID: delivery.Installation.ID,
+		PullRequest:    delivery.Number,
+		HeadSHA:        pullRequest.Head.SHA,
+		CheckRunID:     checkRunID,
+		Status:         models.GitHubCheckStatusPending,
+		CreatedAt:      s.now().UTC(),
+	}
+	if err := s.checkRepo.CreateCheck(ctx, check); err != nil {
+		s.deleteTask(ctx, created.TaskID)
+		return nil, fmt.Errorf("failed to record the check run: %w", err)
+	}
+
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+		// The task records its own failure, which is published to the check run
+		if _, err := s.taskService.RunTask(context.WithoutCancel(ctx), created.TaskID); err != nil {
+			slog.WarnContext(ctx, "pull request task failed", "task_id", created.TaskID, "error", err)
+		}
+	}()
+
+	return &models.GitHubWebhookResponse{TaskID: created.TaskID, CheckRunID: checkRunID}, nil
+}
+
+// findCodebase finds the GitHub codebase of the project cloned from the repository, owner/name
+func (s *DefaultGitHubCheckService) findCodebase(ctx context.Context, projectID, repository string) (*models.Codebase, error) {
+	codebases, err := s.codebaseRepo.GetCodebasesByProject(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list project codebases: %w", err)
+	}
+
+	for _, codebase := range codebases {
+		if codebase.Provider != models.ProviderGitHub {
+			continue
+		}
+		if _, path := parseRepositoryURL(codebase.URL); strings.EqualFold(path, repository) {
+			return codebase, nil
+		}
+	}
+
+	return nil, apperrors.NotFound(apperrors.CodeCodebaseNotFound, "no codebase of project %s is cloned from %s", projectID, repository)
+}
+
+// deleteTask deletes a task whose check run couldn't be set up, so it isn't left behind never to run
+func (s *DefaultGitHubCheckService) deleteTask(ctx context.Context, taskID string) {
+	if err := s.taskService.DeleteTask(ctx, taskID); err != nil {
+		slog.ErrorContext(ctx, "failed to delete pull request task after setting up its check run failed", "task_id", taskID, "error", err)
+	}
+}
+
+// detailsURL is the URL a check run links to for the details of its task
+func (s *DefaultGitHubCheckService) detailsURL(taskID string) string {
+	return strings.ReplaceAll(s.config.DetailsURL, "{task_id}", taskID)
+}
+
+// RunCheckPublishing publishes the results of finished tasks every interval until ctx is cancelled
+func (s *DefaultGitHubCheckService) RunCheckPublishing(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.PublishChecks(ctx); err != nil {
+				slog.ErrorContext(ctx, "failed to publish GitHub checks", "error", err)
+			}
+		}
+	}
+}
+
+// PublishChecks completes the check runs of finished tasks with their findings. A check run that fails to publish
+// is retried on the next call.
+func (s *DefaultGitHubCheckService) PublishChecks(ctx context.Context) (int, error) {
+	checks, err := s.checkRepo.ListPublishable(ctx, checkPublishBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list publishable GitHub checks: %w", err)
+	}
+
+	published := 0
+	for _, check := range checks {
+		if err := s.publishCheck(ctx, check); err != nil {
+			slog.WarnContext(ctx, "failed to publish GitHub check", "task_id", check.TaskID, "check_run_id", check.CheckRunID, "error", err)
+			continue
+		}
+		if err := s.checkRepo.MarkPublished(ctx, check.TaskID, s.now().UTC()); err != nil {
+			return published, fmt.Errorf("failed to mark GitHub check published: %w", err)
+		}
+		published++
+	}
+
+	return published, nil
+}
+
+// publishCheck completes the check run of a task with the task's conclusion and findings
+func (s *DefaultGitHubCheckService) publishCheck(ctx context.Context, check models.GitHubCheck) error {
+	appConfig, err := s.appRepo.GetConfig(ctx, check.Organization)
+	if err != nil {
+		return fmt.Errorf("failed to get GitHub App configuration: %w", err)
+	}
+
+	var task *models.Task
+	response, err := s.taskService.GetTask(ctx, check.TaskID)
+	switch {
+	case err == nil:
+		task = &response.Task
+	case !errors.Is(err, apperrors.ErrNotFound):
+		return fmt.Errorf("failed to get task: %w", err)
+	}
+
+	run := checkRunResult(task, appConfig.FailOnSeverity)
+	run.Name = s.config.CheckName
+	run.DetailsURL = s.detailsURL(check.TaskID)
+	run.Status = gitprovider.CheckStatusCompleted
+
+	app := gitprovider.GitHubApp{AppID: appConfig.AppID, InstallationID: check.InstallationID, PrivateKey: appConfig.PrivateKey}
+	if err := s.publisher.UpdateCheckRun(ctx, app, check.Repository, check.CheckRunID, run); err != nil {
+		return fmt.Errorf("failed to update check run: %w", err)
+	}
+
+	return nil
+}
+
+// checkFinding is a finding or review comment in the result of an agent
+type checkFinding struct {
+	File     string               `json:"file"`
+	Line     int                  `json:"line"`
+	Severity models.CheckSeverity `json:"severity"`
+	Message  string               `json:"message"`
+}
+
+// checkAgentResult is the result of a code_analysis or code_review agent
+type checkAgentResult struct {
+	Summary  string         `json:"summary"`
+	Findings []checkFinding `json:"findings"`
+	Comments []checkFinding `json:"comments"`
+}
+
+// checkRunResult builds the conclusion, report and annotations of the check run of a finished task, nil when the
+// task was deleted. The check fails when the task failed, found anything at or above failOnSeverity or failed a
+// quality gate of its project; static analysis findings count as warnings.
+func checkRunResult(task *models.Task, failOnSeverity models.CheckSeverity) gitprovider.CheckRun {
+	if task == nil {
+		return gitprovider.CheckRun{Conclusion: gitprovider.CheckConclusionCancelled, Title: "Task deleted", Summary: "The task was deleted before it finished."}
+	}
+
+	switch task.Status {
+	case models.TaskStatusCancelled:
+		return gitprovider.CheckRun{Conclusion: gitprovider.CheckConclusionCancelled, Title: "Task cancelled", Summary: "The task was cancelled before it finished."}
+	case models.TaskStatusFailed:
+		summary := "The task failed."
+		if task.ErrorMessage != nil {
+			summary = *task.ErrorMessage
+		}
+		return gitprovider.CheckRun{Conclusion: gitprovider.CheckConclusionFailure, Title: "Task failed", Summary: summary}
+	}
+
+	var result checkAgentResult
+	decodeTaskOutput(task.Output, taskResultKey, &result)
+	var issues []analyzermodels.CodeIssue
+	decodeTaskOutput(task.Output, models.TaskOutputFindingsKey, &issues)
+	var gates models.QualityGateReport
+	decodeTaskOutput(task.Output, models.TaskOutputQualityGatesKey, &gates)
+
+	var annotations []gitprovider.CheckAnnotation
+	failing := 0
+	for _, finding := range append(result.Findings, result.Comments...) {
+		if finding.File == "" {
+			continue
+		}
+		level, ok := checkAnnotationLevels[finding.Severity]
+		if !ok {
+			level = gitprovider.AnnotationLevelNotice
+		}
+		line := max(finding.Line, 1)
+		annotations = append(annotations, gitprovider.CheckAnnotation{
+			Path: finding.File, StartLine: line, EndLine: line, Level: level, Message: finding.Message,
+		})
+		if checkSeverityRank[finding.Severity] >= checkSeverityRank[failOnSeverity] {
+			failing++
+		}
+	}
+	for _, issue := range issues {
+		if issue.FilePath == "" {
+			continue
+		}
+		line := max(issue.Line, 1)
+		annotations = append(annotations, gitprovider.CheckAnnotation{
+			Path: issue.FilePath, StartLine: line, EndLine: max(issue.EndLine, line), Level: gitprovider.AnnotationLevelWarning,
+			Title: issue.RuleID, Message: issue.Message,
+		})
+		if checkSeverityRank[models.CheckSeverityWarning] >= checkSeverityRank[failOnSeverity] {
+			failing++
+		}
+	}
+
+	run := gitprovider.CheckRun{
+		Conclusion:  gitprovider.CheckConclusionSuccess,
+		Title:       fmt.Sprintf("%d findings", len(annotations)),
+		Summary:     result.Summary,
+		Annotations: annotations,
+	}
+	if failing > 0 {
+		run.Conclusion = gitprovider.CheckConclusionFailure
+		run.Title = fmt.Sprintf("%d findings, %d at or above %s", len(annotations), failing, failOnSeverity)
+	}
+	if run.Summary == "" {
+		run.Summary = "The task completed."
+	}
+	if len(gates.Gates) > 0 {
+		failed := 0
+		lines := []string{run.Summary, "", "Quality gates:"}
+		for _, gate := range gates.Gates {
+			if gate.Outcome == models.QualityGateOutcomeFailed {
+				failed++
+			}
+			lines = append(lines, fmt.Sprintf("- %s: %s (%s)", gate.Name, gate.Outcome, gate.Message))
+		}
+		run.Summary = strings.Join(lines, "\n")
+		if failed > 0 {
+			run.Conclusion = gitprovider.CheckConclusionFailure
+			run.Title = fmt.Sprintf("%s, %d quality gates failed", run.Title, failed)
+		}
+	}
+
+	return run
+}
+
+// decodeTaskOutput decodes a value of a task's output into out, which is left empty when the value is missing or
+// doesn't have out's shape
+func decodeTaskOutput(output map[string]any, key string, out any) {
+	value, ok := output[key]
+	if !ok {
+		return
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	_ = json.Unmarshal(encoded, out) //nolint:errcheck // A malformed value is treated as missing
+}
+
+// validWebhookSignature reports whether signature, the X-Hub-Signature-256 header of a delivery, is the HMAC-SHA256
+// of its payload with the webhook secret
+func validWebhookSignature(secret, signature string, payload []byte) bool {
+	digest, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return false
+	}
+	expected, err := hex.DecodeString(digest)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// pullRequestDescription is the description of the task on a pull request
+func pullRequestDescription(title, body string) string {
+	if strings.TrimSpace(body) == "" {
+		return title
+	}
+	return title + "\n\n" + body
+}
+
+// truncateRunes shortens text to at most limit runes
+func truncateRunes(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit])
+}
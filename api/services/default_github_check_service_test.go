@@ -0,0 +1,251 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+	servicesMocks "github.com/kazemisoroush/code-refactoring-tool/api/services/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/gitprovider"
+	gitproviderMocks "github.com/kazemisoroush/code-refactoring-tool/pkg/gitprovider/mocks"
+)
+
+const testWebhookSecret = "0123456789abcdef"
+
+var testGitHubChecksConfig = config.GitHubChecksConfig{
+	DetailsURL: "https://refactor.example.com/tasks/{task_id}",
+	CheckName:  "code-refactoring-tool",
+}
+
+const testPullRequestPayload = `{
+	"action": "opened",
+	"number": 12,
+	"pull_request": {"title": "Add retries", "body": "Retries failed uploads", "html_url": "https://github.com/acme/widgets/pull/12", "head": {"sha": "abc123"}},
+	"repository": {"full_name": "acme/widgets", "owner": {"login": "acme"}},
+	"installation": {"id": 7}
+}`
+
+func signWebhook(payload string) string {
+	mac := hmac.New(sha256.New, []byte(testWebhookSecret))
+	mac.Write([]byte(payload))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func testGitHubAppConfig() *models.GitHubAppConfig {
+	return &models.GitHubAppConfig{
+		Organization: "acme", AppID: 42, PrivateKey: "key", WebhookSecret: testWebhookSecret, ProjectID: "proj-1",
+		AgentID: "agent-1", TaskType: models.TaskTypeCodeReview, FailOnSeverity: models.CheckSeverityWarning,
+	}
+}
+
+func TestDefaultGitHubCheckService_HandleWebhook_StartsTaskAndCheckRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	appRepo := repositoryMocks.NewMockGitHubAppConfigRepository(ctrl)
+	checkRepo := repositoryMocks.NewMockGitHubCheckRepository(ctrl)
+	codebaseRepo := repositoryMocks.NewMockCodebaseRepository(ctrl)
+	taskService := servicesMocks.NewMockTaskService(ctrl)
+	publisher := gitproviderMocks.NewMockChecksPublisher(ctrl)
+	service := NewDefaultGitHubCheckService(appRepo, checkRepo, codebaseRepo, taskService, publisher, testGitHubChecksConfig)
+
+	appRepo.EXPECT().GetConfig(gomock.Any(), "acme").Return(testGitHubAppConfig(), nil)
+	codebaseRepo.EXPECT().GetCodebasesByProject(gomock.Any(), "proj-1").Return([]*models.Codebase{
+		{CodebaseID: "codebase-1", Provider: models.ProviderGitHub, URL: "https://github.com/acme/gadgets.git"},
+		{CodebaseID: "codebase-2", Provider: models.ProviderGitHub, URL: "https://github.com/Acme/Widgets.git"},
+	}, nil)
+	taskService.EXPECT().CreateTask(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, req *models.CreateTaskRequest) (*models.CreateTaskResponse, error) {
+		assert.Equal(t, "codebase-2", *req.CodebaseID)
+		assert.Equal(t, "abc123", req.CommitSHA)
+		assert.Equal(t, models.TaskTypeCodeReview, req.Type)
+		assert.Equal(t, "acme/widgets#12: Add retries", req.Title)
+		return &models.CreateTaskResponse{TaskID: "task-1"}, nil
+	})
+	publisher.EXPECT().CreateCheckRun(gomock.Any(), gitprovider.GitHubApp{AppID: 42, InstallationID: 7, PrivateKey: "key"}, "acme/widgets", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ gitprovider.GitHubApp, _ string, run gitprovider.CheckRun) (int64, error) {
+			assert.Equal(t, gitprovider.CheckStatusQueued, run.Status)
+			assert.Equal(t, "https://refactor.example.com/tasks/task-1", run.DetailsURL)
+			return 4242, nil
+		})
+	checkRepo.EXPECT().CreateCheck(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, check *models.GitHubCheck) error {
+		assert.Equal(t, int64(4242), check.CheckRunID)
+		assert.Equal(t, 12, check.PullRequest)
+		return nil
+	})
+	taskService.EXPECT().RunTask(gomock.Any(), "task-1").Return(&models.ExecuteTaskResponse{}, nil)
+
+	response, err := service.HandleWebhook(context.Background(), "pull_request", signWebhook(testPullRequestPayload), []byte(testPullRequestPayload))
+	service.running.Wait()
+
+	require.NoError(t, err)
+	assert.Equal(t, "task-1", response.TaskID)
+	assert.Equal(t, int64(4242), response.CheckRunID)
+}
+
+func TestDefaultGitHubCheckService_HandleWebhook_Rejected(t *testing.T) {
+	tests := []struct {
+		name            string
+		event           string
+		signature       string
+		payload         string
+		configErr       error
+		expectedIgnored string
+		expectedErrCode string
+	}{
+		{
+			name:            "wrong signature",
+			event:           "pull_request",
+			signature:       "sha256=" + hex.EncodeToString(make([]byte, sha256.Size)),
+			payload:         testPullRequestPayload,
+			expectedErrCode: apperrors.CodeInvalidSignature,
+		},
+		{
+			name:            "unknown organization",
+			event:           "pull_request",
+			payload:         testPullRequestPayload,
+			configErr:       apperrors.NotFound(apperrors.CodeGitHubAppNotFound, "organization acme has no GitHub App configuration"),
+			expectedErrCode: apperrors.CodeInvalidSignature,
+		},
+		{
+			name:            "other event",
+			event:           "push",
+			payload:         testPullRequestPayload,
+			expectedIgnored: "event push",
+		},
+		{
+			name:            "closed pull request",
+			event:           "pull_request",
+			payload:         `{"action": "closed", "repository": {"full_name": "acme/widgets", "owner": {"login": "acme"}}}`,
+			expectedIgnored: "pull request action closed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			appRepo := repositoryMocks.NewMockGitHubAppConfigRepository(ctrl)
+			service := NewDefaultGitHubCheckService(appRepo, nil, nil, nil, nil, testGitHubChecksConfig)
+			signature := tt.signature
+			if signature == "" {
+				signature = signWebhook(tt.payload)
+			}
+
+			if tt.configErr != nil {
+				appRepo.EXPECT().GetConfig(gomock.Any(), "acme").Return(nil, tt.configErr)
+			} else {
+				appRepo.EXPECT().GetConfig(gomock.Any(), "acme").Return(testGitHubAppConfig(), nil)
+			}
+
+			response, err := service.HandleWebhook(context.Background(), tt.event, signature, []byte(tt.payload))
+
+			if tt.expectedErrCode != "" {
+				assert.ErrorIs(t, err, apperrors.ErrUnauthorized)
+				assert.Equal(t, tt.expectedErrCode, apperrors.CodeOf(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedIgnored, response.Ignored)
+		})
+	}
+}
+
+func TestDefaultGitHubCheckService_PublishChecks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	appRepo := repositoryMocks.NewMockGitHubAppConfigRepository(ctrl)
+	checkRepo := repositoryMocks.NewMockGitHubCheckRepository(ctrl)
+	taskService := servicesMocks.NewMockTaskService(ctrl)
+	publisher := gitproviderMocks.NewMockChecksPublisher(ctrl)
+	service := NewDefaultGitHubCheckService(appRepo, checkRepo, nil, taskService, publisher, testGitHubChecksConfig)
+
+	check := models.GitHubCheck{TaskID: "task-1", Organization: "acme", Repository: "acme/widgets", InstallationID: 7, CheckRunID: 4242}
+	task := models.Task{
+		TaskID: "task-1",
+		Status: models.TaskStatusCompleted,
+		Output: map[string]any{taskResultKey: map[string]any{
+			"summary": "Two remarks",
+			"comments": []any{
+				map[string]any{"file": "upload.go", "line": float64(10), "severity": "info", "message": "Name the constant"},
+				map[string]any{"file": "upload.go", "line": float64(24), "severity": "warning", "message": "Retry never stops"},
+			},
+		}},
+	}
+
+	checkRepo.EXPECT().ListPublishable(gomock.Any(), checkPublishBatchSize).Return([]models.GitHubCheck{check}, nil)
+	appRepo.EXPECT().GetConfig(gomock.Any(), "acme").Return(testGitHubAppConfig(), nil)
+	taskService.EXPECT().GetTask(gomock.Any(), "task-1").Return(&models.GetTaskResponse{Task: task}, nil)
+	publisher.EXPECT().UpdateCheckRun(gomock.Any(), gomock.Any(), "acme/widgets", int64(4242), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ gitprovider.GitHubApp, _ string, _ int64, run gitprovider.CheckRun) error {
+			assert.Equal(t, gitprovider.CheckStatusCompleted, run.Status)
+			assert.Equal(t, gitprovider.CheckConclusionFailure, run.Conclusion, "the warning is at the configured threshold")
+			assert.Equal(t, "Two remarks", run.Summary)
+			require.Len(t, run.Annotations, 2)
+			assert.Equal(t, gitprovider.AnnotationLevelNotice, run.Annotations[0].Level)
+			assert.Equal(t, 24, run.Annotations[1].StartLine)
+			return nil
+		})
+	checkRepo.EXPECT().MarkPublished(gomock.Any(), "task-1", gomock.Any()).Return(nil)
+
+	published, err := service.PublishChecks(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, published)
+}
+
+func TestCheckRunResult_Conclusions(t *testing.T) {
+	errorMessage := "agent unreachable"
+
+	tests := []struct {
+		name       string
+		task       *models.Task
+		conclusion string
+	}{
+		{name: "deleted task", conclusion: gitprovider.CheckConclusionCancelled},
+		{name: "failed task", task: &models.Task{Status: models.TaskStatusFailed, ErrorMessage: &errorMessage}, conclusion: gitprovider.CheckConclusionFailure},
+		{name: "no findings", task: &models.Task{Status: models.TaskStatusCompleted}, conclusion: gitprovider.CheckConclusionSuccess},
+		{
+			name: "findings below the threshold",
+			task: &models.Task{Status: models.TaskStatusCompleted, Output: map[string]any{taskResultKey: map[string]any{
+				"findings": []any{map[string]any{"file": "main.go", "severity": "warning", "message": "Unused variable"}},
+			}}},
+			conclusion: gitprovider.CheckConclusionSuccess,
+		},
+		{
+			name: "failed quality gate",
+			task: &models.Task{Status: models.TaskStatusCompleted, Output: map[string]any{models.TaskOutputQualityGatesKey: map[string]any{
+				"passed": false,
+				"gates":  []any{map[string]any{"name": "No new critical findings", "outcome": "failed", "message": "1 new findings of critical severity or above, at most 0 allowed"}},
+			}}},
+			conclusion: gitprovider.CheckConclusionFailure,
+		},
+		{
+			name: "passed quality gate",
+			task: &models.Task{Status: models.TaskStatusCompleted, Output: map[string]any{models.TaskOutputQualityGatesKey: &models.QualityGateReport{
+				Passed: true,
+				Gates:  []models.QualityGateResult{{Name: "Complexity must not increase", Outcome: models.QualityGateOutcomePassed}},
+			}}},
+			conclusion: gitprovider.CheckConclusionSuccess,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			run := checkRunResult(tt.task, models.CheckSeverityError)
+
+			assert.Equal(t, tt.conclusion, run.Conclusion)
+		})
+	}
+}
@@ -13,14 +13,17 @@ type DefaultHealthService struct {
 	serviceName string
 	version     string
 	startTime   time.Time
+	unavailable []string
 }
 
-// NewDefaultHealthService creates a new instance of DefaultHealthService
-func NewDefaultHealthService(serviceName, version string) HealthService {
+// NewDefaultHealthService creates a new instance of DefaultHealthService. The service reports itself degraded when
+// it started without the unavailable dependencies.
+func NewDefaultHealthService(serviceName, version string, unavailable ...string) HealthService {
 	return &DefaultHealthService{
 		serviceName: serviceName,
 		version:     version,
 		startTime:   time.Now(),
+		unavailable: unavailable,
 	}
 }
 
@@ -29,12 +32,18 @@ func (s *DefaultHealthService) GetHealthStatus(_ context.Context, _ models.Healt
 	// Calculate uptime
 	uptime := int64(time.Since(s.startTime).Seconds())
 
+	status := models.HealthStatusHealthy
+	if len(s.unavailable) > 0 {
+		status = models.HealthStatusDegraded
+	}
+
 	response := &models.HealthCheckResponse{
-		Status:    string(models.HealthStatusHealthy),
-		Service:   s.serviceName,
-		Version:   s.version,
-		Timestamp: time.Now().UTC(),
-		Uptime:    &uptime,
+		Status:      string(status),
+		Service:     s.serviceName,
+		Version:     s.version,
+		Timestamp:   time.Now().UTC(),
+		Uptime:      &uptime,
+		Unavailable: s.unavailable,
 	}
 
 	return response, nil
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/codebase"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/scan"
+)
+
+// DefaultIngestionScanService is the default implementation of IngestionScanService.
+// A codebase whose latest scan found a high severity problem is ingestion_blocked until a scan comes back clean.
+type DefaultIngestionScanService struct {
+	findingRepo  repository.ScanFindingRepository
+	codebaseRepo repository.CodebaseRepository
+	cloner       CodebaseCloner
+	scanner      scan.ContentScanner
+	owners       CodeOwnersService
+}
+
+// NewDefaultIngestionScanService creates a new DefaultIngestionScanService
+func NewDefaultIngestionScanService(
+	findingRepo repository.ScanFindingRepository,
+	codebaseRepo repository.CodebaseRepository,
+	cloner CodebaseCloner,
+	scanner scan.ContentScanner,
+	owners CodeOwnersService,
+) *DefaultIngestionScanService {
+	return &DefaultIngestionScanService{
+		findingRepo:  findingRepo,
+		codebaseRepo: codebaseRepo,
+		cloner:       cloner,
+		scanner:      scanner,
+		owners:       owners,
+	}
+}
+
+// ScanCodebase scans the head of a codebase's default branch, replaces the codebase's findings and records on the
+// codebase whether its ingestion is blocked. The ownership of the codebase's files is recorded from its CODEOWNERS
+// file along the way.
+func (s *DefaultIngestionScanService) ScanCodebase(ctx context.Context, request models.ScanCodebaseRequest) (*models.ScanCodebaseResponse, error) {
+	cb, err := s.codebaseRepo.GetCodebase(ctx, request.CodebaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get codebase: %w", err)
+	}
+
+	dir, cleanup, err := s.cloner.Clone(ctx, "", cb, "", "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to clone codebase: %w", err)
+	}
+	defer cleanup()
+
+	commitSHA, err := codebase.HeadCommit(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ownership only routes findings and results, so failing to record it doesn't fail the scan
+	if _, err := s.owners.RecordOwners(ctx, cb.CodebaseID, dir, commitSHA); err != nil {
+		slog.WarnContext(ctx, "failed to record code owners", "codebase_id", cb.CodebaseID, "error", err)
+	}
+
+	results, err := scan.Check(ctx, s.scanner, dir)
+	var blocked *scan.BlockedError
+	if err != nil && !errors.As(err, &blocked) {
+		return nil, err
+	}
+
+	detectedAt := time.Now()
+	findings := make([]models.ScanFinding, len(results))
+	for i, result := range results {
+		findings[i] = models.ScanFinding{
+			FindingID:  uuid.New().String(),
+			CodebaseID: cb.CodebaseID,
+			Kind:       models.ScanFindingKind(result.Kind),
+			Rule:       result.Rule,
+			Severity:   models.ScanFindingSeverity(result.Severity),
+			FilePath:   result.FilePath,
+			Message:    result.Message,
+			CommitSHA:  commitSHA,
+			DetectedAt: detectedAt,
+		}
+		if result.Line > 0 {
+			line := result.Line
+			findings[i].Line = &line
+		}
+	}
+
+	if err := s.findingRepo.ReplaceFindings(ctx, cb.CodebaseID, findings); err != nil {
+		return nil, fmt.Errorf("failed to store scan findings: %w", err)
+	}
+
+	// A clean scan only lifts a block; other statuses are left to the codebase's owner
+	switch {
+	case blocked != nil:
+		message := blocked.Error()
+		cb.Status = models.CodebaseStatusIngestionBlocked
+		cb.IngestionError = &message
+	case cb.Status == models.CodebaseStatusIngestionBlocked:
+		cb.Status = models.CodebaseStatusActive
+		cb.IngestionError = nil
+	}
+	cb.UpdatedAt = detectedAt
+	if err := s.codebaseRepo.UpdateCodebase(ctx, cb); err != nil {
+		return nil, fmt.Errorf("failed to update codebase: %w", err)
+	}
+
+	return &models.ScanCodebaseResponse{
+		CodebaseID:     cb.CodebaseID,
+		Status:         cb.Status,
+		IngestionError: cb.IngestionError,
+		Findings:       findings,
+	}, nil
+}
+
+// ListFindings lists the findings of a codebase's latest scan
+func (s *DefaultIngestionScanService) ListFindings(ctx context.Context, request models.ListScanFindingsRequest) (*models.ListScanFindingsResponse, error) {
+	if _, err := s.codebaseRepo.GetCodebase(ctx, request.CodebaseID); err != nil {
+		return nil, fmt.Errorf("failed to get codebase: %w", err)
+	}
+
+	findings, err := s.findingRepo.ListFindings(ctx, request.CodebaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.ListScanFindingsResponse{Findings: []models.ScanFinding{}}
+	for _, finding := range findings {
+		if request.Kind != nil && finding.Kind != *request.Kind {
+			continue
+		}
+		if request.Severity != nil && finding.Severity != *request.Severity {
+			continue
+		}
+		response.Findings = append(response.Findings, finding)
+	}
+
+	return response, nil
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+	servicesMocks "github.com/kazemisoroush/code-refactoring-tool/api/services/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/scan"
+	scanMocks "github.com/kazemisoroush/code-refactoring-tool/pkg/scan/mocks"
+)
+
+// initRepository creates a git repository with an empty commit, standing in for a clone, and returns its directory
+// and head commit
+func initRepository(t *testing.T) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	hash, err := worktree.Commit("initial commit", &git.CommitOptions{
+		AllowEmptyCommits: true,
+		Author:            &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+	return dir, hash.String()
+}
+
+func TestIngestionScanService_ScanCodebase_BlocksIngestion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	findingRepo := repositoryMocks.NewMockScanFindingRepository(ctrl)
+	codebaseRepo := repositoryMocks.NewMockCodebaseRepository(ctrl)
+	cloner := servicesMocks.NewMockCodebaseCloner(ctrl)
+	scanner := scanMocks.NewMockContentScanner(ctrl)
+	owners := servicesMocks.NewMockCodeOwnersService(ctrl)
+	service := NewDefaultIngestionScanService(findingRepo, codebaseRepo, cloner, scanner, owners)
+
+	dir, commitSHA := initRepository(t)
+	codebase := &models.Codebase{CodebaseID: "cb-1", Status: models.CodebaseStatusActive}
+
+	codebaseRepo.EXPECT().GetCodebase(gomock.Any(), "cb-1").Return(codebase, nil)
+	cloner.EXPECT().Clone(gomock.Any(), "", codebase, "", "").Return(dir, func() {}, nil)
+	owners.EXPECT().RecordOwners(gomock.Any(), "cb-1", dir, commitSHA).Return(&models.CodeOwners{CodebaseID: "cb-1"}, nil)
+	scanner.EXPECT().Scan(gomock.Any(), dir).Return([]scan.Finding{
+		{Kind: scan.KindLicense, Rule: "AGPL-3.0", Severity: scan.SeverityHigh, FilePath: "LICENSE", Message: "AGPL-3.0 license is not allowed to be ingested"},
+		{Kind: scan.KindSecret, Rule: "generic-secret", Severity: scan.SeverityMedium, FilePath: "app.py", Line: 4, Message: "High-entropy value assigned to token"},
+	}, nil)
+	findingRepo.EXPECT().
+		ReplaceFindings(gomock.Any(), "cb-1", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, findings []models.ScanFinding) error {
+			require.Len(t, findings, 2)
+			assert.Nil(t, findings[0].Line)
+			assert.Equal(t, commitSHA, findings[0].CommitSHA)
+			require.NotNil(t, findings[1].Line)
+			assert.Equal(t, 4, *findings[1].Line)
+			return nil
+		})
+	codebaseRepo.EXPECT().
+		UpdateCodebase(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, updated *models.Codebase) error {
+			assert.Equal(t, models.CodebaseStatusIngestionBlocked, updated.Status)
+			require.NotNil(t, updated.IngestionError)
+			assert.Contains(t, *updated.IngestionError, "AGPL-3.0 license is not allowed to be ingested in LICENSE")
+			return nil
+		})
+
+	response, err := service.ScanCodebase(context.Background(), models.ScanCodebaseRequest{CodebaseID: "cb-1"})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.CodebaseStatusIngestionBlocked, response.Status)
+	assert.NotNil(t, response.IngestionError)
+	assert.Len(t, response.Findings, 2)
+}
+
+func TestIngestionScanService_ScanCodebase_CleanScanLiftsBlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	findingRepo := repositoryMocks.NewMockScanFindingRepository(ctrl)
+	codebaseRepo := repositoryMocks.NewMockCodebaseRepository(ctrl)
+	cloner := servicesMocks.NewMockCodebaseCloner(ctrl)
+	scanner := scanMocks.NewMockContentScanner(ctrl)
+	owners := servicesMocks.NewMockCodeOwnersService(ctrl)
+	service := NewDefaultIngestionScanService(findingRepo, codebaseRepo, cloner, scanner, owners)
+
+	dir, _ := initRepository(t)
+	previousError := "ingestion blocked by 1 high severity findings: Committed AWS access key ID in prod.env:2"
+	codebase := &models.Codebase{CodebaseID: "cb-1", Status: models.CodebaseStatusIngestionBlocked, IngestionError: &previousError}
+
+	codebaseRepo.EXPECT().GetCodebase(gomock.Any(), "cb-1").Return(codebase, nil)
+	cloner.EXPECT().Clone(gomock.Any(), "", codebase, "", "").Return(dir, func() {}, nil)
+	owners.EXPECT().RecordOwners(gomock.Any(), "cb-1", dir, gomock.Any()).Return(nil, errors.New("CODEOWNERS is larger than 3145728 bytes"))
+	scanner.EXPECT().Scan(gomock.Any(), dir).Return(nil, nil)
+	findingRepo.EXPECT().ReplaceFindings(gomock.Any(), "cb-1", []models.ScanFinding{}).Return(nil)
+	codebaseRepo.EXPECT().
+		UpdateCodebase(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, updated *models.Codebase) error {
+			assert.Equal(t, models.CodebaseStatusActive, updated.Status)
+			assert.Nil(t, updated.IngestionError)
+			return nil
+		})
+
+	response, err := service.ScanCodebase(context.Background(), models.ScanCodebaseRequest{CodebaseID: "cb-1"})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.CodebaseStatusActive, response.Status)
+	assert.Empty(t, response.Findings)
+}
+
+func TestIngestionScanService_ListFindings_Filters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	findingRepo := repositoryMocks.NewMockScanFindingRepository(ctrl)
+	codebaseRepo := repositoryMocks.NewMockCodebaseRepository(ctrl)
+	service := NewDefaultIngestionScanService(findingRepo, codebaseRepo, servicesMocks.NewMockCodebaseCloner(ctrl), scanMocks.NewMockContentScanner(ctrl),
+		servicesMocks.NewMockCodeOwnersService(ctrl))
+
+	codebaseRepo.EXPECT().GetCodebase(gomock.Any(), "cb-1").Return(&models.Codebase{CodebaseID: "cb-1"}, nil)
+	findingRepo.EXPECT().ListFindings(gomock.Any(), "cb-1").Return([]models.ScanFinding{
+		{FindingID: "f-1", Kind: models.ScanFindingKindLicense, Severity: models.ScanFindingSeverityHigh},
+		{FindingID: "f-2", Kind: models.ScanFindingKindSecret, Severity: models.ScanFindingSeverityHigh},
+		{FindingID: "f-3", Kind: models.ScanFindingKindSecret, Severity: models.ScanFindingSeverityMedium},
+	}, nil)
+
+	kind := models.ScanFindingKindSecret
+	severity := models.ScanFindingSeverityHigh
+	response, err := service.ListFindings(context.Background(), models.ListScanFindingsRequest{CodebaseID: "cb-1", Kind: &kind, Severity: &severity})
+
+	require.NoError(t, err)
+	require.Len(t, response.Findings, 1)
+	assert.Equal(t, "f-2", response.Findings[0].FindingID)
+}
@@ -0,0 +1,297 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/jira"
+)
+
+// jiraSyncBatchSize is the number of issues updated per batch
+const jiraSyncBatchSize = 50
+
+// maxJiraCommentError is the longest error message of a failed task quoted in an issue comment
+const maxJiraCommentError = 1000
+
+var (
+	// jiraProjectKeyPattern matches the key of a Jira project
+	jiraProjectKeyPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]+$`)
+	// jiraIssueKeyPattern matches the key of a Jira issue, the project key followed by the issue number
+	jiraIssueKeyPattern = regexp.MustCompile(`^([A-Z][A-Z0-9_]+)-[1-9][0-9]*$`)
+)
+
+// DefaultJiraService is the default implementation of JiraService
+type DefaultJiraService struct {
+	siteRepo repository.JiraSiteRepository
+	linkRepo repository.JiraIssueLinkRepository
+	tracker  jira.IssueTracker
+	now      func() time.Time
+}
+
+// NewDefaultJiraService creates a new DefaultJiraService
+func NewDefaultJiraService(
+	siteRepo repository.JiraSiteRepository,
+	linkRepo repository.JiraIssueLinkRepository,
+	tracker jira.IssueTracker,
+) *DefaultJiraService {
+	return &DefaultJiraService{
+		siteRepo: siteRepo,
+		linkRepo: linkRepo,
+		tracker:  tracker,
+		now:      time.Now,
+	}
+}
+
+// PutSite configures the Jira site of an organization, replacing its configuration. A project key can only be
+// listed by one organization, so that issue keys resolve to a single site.
+func (s *DefaultJiraService) PutSite(ctx context.Context, request models.PutJiraSiteRequest) (*models.JiraSite, error) {
+	projectKeys := make([]string, 0, len(request.ProjectKeys))
+	seen := make(map[string]bool, len(request.ProjectKeys))
+	for _, key := range request.ProjectKeys {
+		if !jiraProjectKeyPattern.MatchString(key) {
+			return nil, apperrors.Validation(apperrors.CodeInvalidRequest, "project key %q is not a Jira project key", key)
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		owner, err := s.siteRepo.GetSiteByProjectKey(ctx, key)
+		switch {
+		case err == nil && owner.Organization != request.Organization:
+			return nil, apperrors.Conflict(apperrors.CodeConflict, "project key %s is already listed by organization %s", key, owner.Organization)
+		case err != nil && !errors.Is(err, apperrors.ErrNotFound):
+			return nil, fmt.Errorf("failed to get Jira site: %w", err)
+		}
+		projectKeys = append(projectKeys, key)
+	}
+
+	doneTransition := request.DoneTransition
+	if doneTransition == "" {
+		doneTransition = models.DefaultJiraDoneTransition
+	}
+
+	now := s.now().UTC()
+	site := &models.JiraSite{
+		Organization:   request.Organization,
+		SiteURL:        strings.TrimSuffix(request.SiteURL, "/"),
+		Email:          request.Email,
+		APIToken:       request.APIToken,
+		ProjectKeys:    projectKeys,
+		DoneTransition: doneTransition,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := s.siteRepo.PutSite(ctx, site); err != nil {
+		return nil, fmt.Errorf("failed to put Jira site: %w", err)
+	}
+
+	return site, nil
+}
+
+// GetSite gets the Jira site of an organization
+func (s *DefaultJiraService) GetSite(ctx context.Context, request models.GetJiraSiteRequest) (*models.JiraSite, error) {
+	site, err := s.siteRepo.GetSite(ctx, request.Organization)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Jira site: %w", err)
+	}
+
+	return site, nil
+}
+
+// ListSites lists the Jira sites ordered by organization
+func (s *DefaultJiraService) ListSites(ctx context.Context) (*models.ListJiraSitesResponse, error) {
+	sites, err := s.siteRepo.ListSites(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Jira sites: %w", err)
+	}
+
+	return &models.ListJiraSitesResponse{Sites: sites}, nil
+}
+
+// DeleteSite deletes the Jira site of an organization
+func (s *DefaultJiraService) DeleteSite(ctx context.Context, request models.DeleteJiraSiteRequest) error {
+	if err := s.siteRepo.DeleteSite(ctx, request.Organization); err != nil {
+		return fmt.Errorf("failed to delete Jira site: %w", err)
+	}
+
+	return nil
+}
+
+// ResolveIssue finds the site listing the project of an issue key and checks the issue exists
+func (s *DefaultJiraService) ResolveIssue(ctx context.Context, issueKey string) (*models.JiraIssueLink, error) {
+	match := jiraIssueKeyPattern.FindStringSubmatch(issueKey)
+	if match == nil {
+		return nil, apperrors.Validation(apperrors.CodeInvalidRequest, "issue_key %q is not a Jira issue key", issueKey)
+	}
+
+	site, err := s.siteRepo.GetSiteByProjectKey(ctx, match[1])
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil, apperrors.Validation(apperrors.CodeJiraSiteNotFound, "no Jira site is configured for project %s", match[1])
+		}
+		return nil, fmt.Errorf("failed to get Jira site: %w", err)
+	}
+
+	if _, err := s.tracker.GetIssue(ctx, jiraSite(site), issueKey); err != nil {
+		if errors.Is(err, jira.ErrNotFound) {
+			return nil, apperrors.Validation(apperrors.CodeJiraIssueNotFound, "Jira issue %s not found", issueKey)
+		}
+		return nil, apperrors.BadGateway(apperrors.CodeBadGateway, err, "failed to get Jira issue %s", issueKey)
+	}
+
+	return &models.JiraIssueLink{
+		Organization: site.Organization,
+		IssueKey:     issueKey,
+		IssueURL:     site.SiteURL + "/browse/" + issueKey,
+	}, nil
+}
+
+// LinkIssue links a created task to the issue resolved by ResolveIssue. The issue is updated once the task starts.
+func (s *DefaultJiraService) LinkIssue(ctx context.Context, taskID string, link *models.JiraIssueLink) error {
+	now := s.now().UTC()
+	link.TaskID = taskID
+	link.SyncedStatus = models.TaskStatusPending
+	link.CreatedAt = now
+	link.UpdatedAt = now
+
+	if err := s.linkRepo.CreateLink(ctx, link); err != nil {
+		return fmt.Errorf("failed to link Jira issue: %w", err)
+	}
+
+	return nil
+}
+
+// AttachIssues sets the issue of the tasks linked to one
+func (s *DefaultJiraService) AttachIssues(ctx context.Context, tasks []models.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	taskIDs := make([]string, len(tasks))
+	for i, task := range tasks {
+		taskIDs[i] = task.TaskID
+	}
+	links, err := s.linkRepo.ListLinks(ctx, taskIDs)
+	if err != nil {
+		return fmt.Errorf("failed to list Jira issue links: %w", err)
+	}
+
+	byTask := make(map[string]*models.JiraIssueLink, len(links))
+	for i := range links {
+		byTask[links[i].TaskID] = &links[i]
+	}
+	for i := range tasks {
+		tasks[i].Issue = byTask[tasks[i].TaskID]
+	}
+
+	return nil
+}
+
+// SyncIssues comments on the issues whose task changed status and transitions those whose task completed. An issue
+// that fails to update is retried on the next call; a completion transition the issue doesn't offer is skipped.
+func (s *DefaultJiraService) SyncIssues(ctx context.Context) (int, error) {
+	updates, err := s.linkRepo.ListUpdates(ctx, jiraSyncBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list Jira issue updates: %w", err)
+	}
+
+	sites := make(map[string]*models.JiraSite)
+	synced := 0
+	for _, update := range updates {
+		if err := s.syncIssue(ctx, sites, update); err != nil {
+			slog.WarnContext(ctx, "failed to update Jira issue", "task_id", update.Link.TaskID, "issue_key", update.Link.IssueKey, "error", err)
+			continue
+		}
+		if err := s.linkRepo.MarkSynced(ctx, update.Link.TaskID, update.Status, s.now().UTC()); err != nil {
+			return synced, fmt.Errorf("failed to mark Jira issue synced: %w", err)
+		}
+		synced++
+	}
+
+	return synced, nil
+}
+
+// syncIssue reports the status of a task on its issue, moving the issue through the done transition of its site
+// when the task completed. Sites are cached in sites for the duration of a sync.
+func (s *DefaultJiraService) syncIssue(ctx context.Context, sites map[string]*models.JiraSite, update models.JiraIssueUpdate) error {
+	site, ok := sites[update.Link.Organization]
+	if !ok {
+		var err error
+		site, err = s.siteRepo.GetSite(ctx, update.Link.Organization)
+		if err != nil {
+			return fmt.Errorf("failed to get Jira site: %w", err)
+		}
+		sites[update.Link.Organization] = site
+	}
+
+	if err := s.tracker.AddComment(ctx, jiraSite(site), update.Link.IssueKey, jiraComment(update)); err != nil {
+		return fmt.Errorf("failed to comment on issue: %w", err)
+	}
+
+	if update.Status == models.TaskStatusCompleted {
+		err := s.tracker.TransitionIssue(ctx, jiraSite(site), update.Link.IssueKey, site.DoneTransition)
+		switch {
+		case errors.Is(err, jira.ErrTransitionNotFound):
+			slog.WarnContext(ctx, "Jira issue has no done transition", "issue_key", update.Link.IssueKey, "transition", site.DoneTransition)
+		case err != nil:
+			return fmt.Errorf("failed to transition issue: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// jiraComment is the comment reporting the status of a task on its issue
+func jiraComment(update models.JiraIssueUpdate) string {
+	switch update.Status {
+	case models.TaskStatusInProgress:
+		return fmt.Sprintf("Task %q (%s) started.", update.Title, update.Link.TaskID)
+	case models.TaskStatusCompleted:
+		return fmt.Sprintf("Task %q (%s) completed.", update.Title, update.Link.TaskID)
+	case models.TaskStatusFailed:
+		comment := fmt.Sprintf("Task %q (%s) failed.", update.Title, update.Link.TaskID)
+		if update.ErrorMessage != nil && *update.ErrorMessage != "" {
+			message := *update.ErrorMessage
+			if len(message) > maxJiraCommentError {
+				message = message[:maxJiraCommentError] + "..."
+			}
+			comment += "\n\n" + message
+		}
+		return comment
+	case models.TaskStatusCancelled:
+		return fmt.Sprintf("Task %q (%s) was cancelled.", update.Title, update.Link.TaskID)
+	default:
+		return fmt.Sprintf("Task %q (%s) is %s.", update.Title, update.Link.TaskID, update.Status)
+	}
+}
+
+// jiraSite is the site and credentials the issues of an organization are updated with
+func jiraSite(site *models.JiraSite) jira.Site {
+	return jira.Site{BaseURL: site.SiteURL, Email: site.Email, APIToken: site.APIToken}
+}
+
+// RunIssueSync updates the issues of tasks that changed status every interval until ctx is cancelled
+func (s *DefaultJiraService) RunIssueSync(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.SyncIssues(ctx); err != nil {
+				slog.ErrorContext(ctx, "failed to sync Jira issues", "error", err)
+			}
+		}
+	}
+}
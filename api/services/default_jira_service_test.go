@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/jira"
+	jiraMocks "github.com/kazemisoroush/code-refactoring-tool/pkg/jira/mocks"
+)
+
+func testJiraSite() *models.JiraSite {
+	return &models.JiraSite{
+		Organization: "acme", SiteURL: "https://acme.atlassian.net", Email: "bot@acme.com", APIToken: "token",
+		ProjectKeys: []string{"PAY"}, DoneTransition: "Done",
+	}
+}
+
+func TestDefaultJiraService_PutSite_ProjectKeyOfAnotherOrganization(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	siteRepo := repositoryMocks.NewMockJiraSiteRepository(ctrl)
+	service := NewDefaultJiraService(siteRepo, repositoryMocks.NewMockJiraIssueLinkRepository(ctrl), jiraMocks.NewMockIssueTracker(ctrl))
+
+	siteRepo.EXPECT().GetSiteByProjectKey(gomock.Any(), "PAY").Return(testJiraSite(), nil)
+
+	_, err := service.PutSite(context.Background(), models.PutJiraSiteRequest{
+		Organization: "globex", SiteURL: "https://globex.atlassian.net", Email: "bot@globex.com", APIToken: "token",
+		ProjectKeys: []string{"PAY"},
+	})
+
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+}
+
+func TestDefaultJiraService_PutSite_DefaultsDoneTransition(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	siteRepo := repositoryMocks.NewMockJiraSiteRepository(ctrl)
+	service := NewDefaultJiraService(siteRepo, repositoryMocks.NewMockJiraIssueLinkRepository(ctrl), jiraMocks.NewMockIssueTracker(ctrl))
+
+	siteRepo.EXPECT().GetSiteByProjectKey(gomock.Any(), "PAY").Return(testJiraSite(), nil)
+	siteRepo.EXPECT().GetSiteByProjectKey(gomock.Any(), "AUTH").Return(nil, apperrors.NotFound(apperrors.CodeJiraSiteNotFound, "not found"))
+	siteRepo.EXPECT().PutSite(gomock.Any(), gomock.Any()).Return(nil)
+
+	site, err := service.PutSite(context.Background(), models.PutJiraSiteRequest{
+		Organization: "acme", SiteURL: "https://acme.atlassian.net/", Email: "bot@acme.com", APIToken: "token",
+		ProjectKeys: []string{"PAY", "AUTH", "PAY"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://acme.atlassian.net", site.SiteURL)
+	assert.Equal(t, []string{"PAY", "AUTH"}, site.ProjectKeys)
+	assert.Equal(t, models.DefaultJiraDoneTransition, site.DoneTransition)
+}
+
+func TestDefaultJiraService_PutSite_InvalidProjectKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewDefaultJiraService(repositoryMocks.NewMockJiraSiteRepository(ctrl), repositoryMocks.NewMockJiraIssueLinkRepository(ctrl), jiraMocks.NewMockIssueTracker(ctrl))
+
+	_, err := service.PutSite(context.Background(), models.PutJiraSiteRequest{
+		Organization: "acme", SiteURL: "https://acme.atlassian.net", Email: "bot@acme.com", APIToken: "token",
+		ProjectKeys: []string{"pay"},
+	})
+
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+}
+
+func TestDefaultJiraService_ResolveIssue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	siteRepo := repositoryMocks.NewMockJiraSiteRepository(ctrl)
+	tracker := jiraMocks.NewMockIssueTracker(ctrl)
+	service := NewDefaultJiraService(siteRepo, repositoryMocks.NewMockJiraIssueLinkRepository(ctrl), tracker)
+
+	site := jira.Site{BaseURL: "https://acme.atlassian.net", Email: "bot@acme.com", APIToken: "token"}
+	siteRepo.EXPECT().GetSiteByProjectKey(gomock.Any(), "PAY").Return(testJiraSite(), nil)
+	tracker.EXPECT().GetIssue(gomock.Any(), site, "PAY-123").Return(&jira.Issue{Key: "PAY-123"}, nil)
+
+	link, err := service.ResolveIssue(context.Background(), "PAY-123")
+
+	require.NoError(t, err)
+	assert.Equal(t, "acme", link.Organization)
+	assert.Equal(t, "https://acme.atlassian.net/browse/PAY-123", link.IssueURL)
+}
+
+func TestDefaultJiraService_ResolveIssue_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		issueKey string
+		setup    func(siteRepo *repositoryMocks.MockJiraSiteRepository, tracker *jiraMocks.MockIssueTracker)
+		code     string
+	}{
+		{
+			name:     "malformed key",
+			issueKey: "PAY123",
+			setup:    func(*repositoryMocks.MockJiraSiteRepository, *jiraMocks.MockIssueTracker) {},
+			code:     apperrors.CodeInvalidRequest,
+		},
+		{
+			name:     "unconfigured project",
+			issueKey: "OPS-7",
+			setup: func(siteRepo *repositoryMocks.MockJiraSiteRepository, _ *jiraMocks.MockIssueTracker) {
+				siteRepo.EXPECT().GetSiteByProjectKey(gomock.Any(), "OPS").Return(nil, apperrors.NotFound(apperrors.CodeJiraSiteNotFound, "not found"))
+			},
+			code: apperrors.CodeJiraSiteNotFound,
+		},
+		{
+			name:     "missing issue",
+			issueKey: "PAY-404",
+			setup: func(siteRepo *repositoryMocks.MockJiraSiteRepository, tracker *jiraMocks.MockIssueTracker) {
+				siteRepo.EXPECT().GetSiteByProjectKey(gomock.Any(), "PAY").Return(testJiraSite(), nil)
+				tracker.EXPECT().GetIssue(gomock.Any(), gomock.Any(), "PAY-404").Return(nil, jira.ErrNotFound)
+			},
+			code: apperrors.CodeJiraIssueNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			siteRepo := repositoryMocks.NewMockJiraSiteRepository(ctrl)
+			tracker := jiraMocks.NewMockIssueTracker(ctrl)
+			service := NewDefaultJiraService(siteRepo, repositoryMocks.NewMockJiraIssueLinkRepository(ctrl), tracker)
+			tt.setup(siteRepo, tracker)
+
+			_, err := service.ResolveIssue(context.Background(), tt.issueKey)
+
+			assert.ErrorIs(t, err, apperrors.ErrValidation)
+			assert.Equal(t, tt.code, apperrors.CodeOf(err))
+		})
+	}
+}
+
+func TestDefaultJiraService_AttachIssues(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	linkRepo := repositoryMocks.NewMockJiraIssueLinkRepository(ctrl)
+	service := NewDefaultJiraService(repositoryMocks.NewMockJiraSiteRepository(ctrl), linkRepo, jiraMocks.NewMockIssueTracker(ctrl))
+
+	linkRepo.EXPECT().ListLinks(gomock.Any(), []string{"task-1", "task-2"}).
+		Return([]models.JiraIssueLink{{TaskID: "task-2", IssueKey: "PAY-123"}}, nil)
+
+	tasks := []models.Task{{TaskID: "task-1"}, {TaskID: "task-2"}}
+	require.NoError(t, service.AttachIssues(context.Background(), tasks))
+
+	assert.Nil(t, tasks[0].Issue)
+	require.NotNil(t, tasks[1].Issue)
+	assert.Equal(t, "PAY-123", tasks[1].Issue.IssueKey)
+}
+
+func TestDefaultJiraService_SyncIssues(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	siteRepo := repositoryMocks.NewMockJiraSiteRepository(ctrl)
+	linkRepo := repositoryMocks.NewMockJiraIssueLinkRepository(ctrl)
+	tracker := jiraMocks.NewMockIssueTracker(ctrl)
+	service := NewDefaultJiraService(siteRepo, linkRepo, tracker)
+	now := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	failure := "agent crashed"
+	linkRepo.EXPECT().ListUpdates(gomock.Any(), jiraSyncBatchSize).Return([]models.JiraIssueUpdate{
+		{Link: models.JiraIssueLink{TaskID: "task-1", Organization: "acme", IssueKey: "PAY-1"}, Status: models.TaskStatusCompleted, Title: "Refactor"},
+		{Link: models.JiraIssueLink{TaskID: "task-2", Organization: "acme", IssueKey: "PAY-2"}, Status: models.TaskStatusFailed, Title: "Review", ErrorMessage: &failure},
+		{Link: models.JiraIssueLink{TaskID: "task-3", Organization: "acme", IssueKey: "PAY-3"}, Status: models.TaskStatusInProgress, Title: "Analyze"},
+	}, nil)
+	siteRepo.EXPECT().GetSite(gomock.Any(), "acme").Return(testJiraSite(), nil).Times(1)
+
+	// The completed task's issue has no done transition, which isn't retried
+	tracker.EXPECT().AddComment(gomock.Any(), gomock.Any(), "PAY-1", `Task "Refactor" (task-1) completed.`).Return(nil)
+	tracker.EXPECT().TransitionIssue(gomock.Any(), gomock.Any(), "PAY-1", "Done").Return(jira.ErrTransitionNotFound)
+	linkRepo.EXPECT().MarkSynced(gomock.Any(), "task-1", models.TaskStatusCompleted, now).Return(nil)
+
+	tracker.EXPECT().AddComment(gomock.Any(), gomock.Any(), "PAY-2", "Task \"Review\" (task-2) failed.\n\nagent crashed").Return(nil)
+	linkRepo.EXPECT().MarkSynced(gomock.Any(), "task-2", models.TaskStatusFailed, now).Return(nil)
+
+	// A failed comment is retried on the next sync
+	tracker.EXPECT().AddComment(gomock.Any(), gomock.Any(), "PAY-3", gomock.Any()).Return(errors.New("503 Service Unavailable"))
+
+	synced, err := service.SyncIssues(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, synced)
+}
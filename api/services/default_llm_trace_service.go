@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/redact"
+)
+
+// DefaultLLMTraceService is the default implementation of LLMTraceService
+type DefaultLLMTraceService struct {
+	interactionRepo  repository.LLMInteractionRepository
+	taskRepo         repository.TaskRepository
+	roleService      RoleService
+	redactionService RedactionService
+	config           config.LLMLogConfig
+	now              func() time.Time
+}
+
+// NewDefaultLLMTraceService creates a new DefaultLLMTraceService. Nothing is recorded unless the log is enabled.
+// Callers must be allowed to read the tasks of the task's project to get its trace.
+func NewDefaultLLMTraceService(
+	interactionRepo repository.LLMInteractionRepository,
+	taskRepo repository.TaskRepository,
+	roleService RoleService,
+	redactionService RedactionService,
+	cfg config.LLMLogConfig,
+) *DefaultLLMTraceService {
+	return &DefaultLLMTraceService{
+		interactionRepo:  interactionRepo,
+		taskRepo:         taskRepo,
+		roleService:      roleService,
+		redactionService: redactionService,
+		config:           cfg,
+		now:              time.Now,
+	}
+}
+
+// RecordInteraction logs an LLM call made while executing a task
+func (s *DefaultLLMTraceService) RecordInteraction(ctx context.Context, interaction *models.LLMInteraction) error {
+	if !s.config.Enabled {
+		return nil
+	}
+
+	policy, err := s.redactionService.GetPolicy(ctx, models.GetRedactionPolicyRequest{ProjectID: interaction.ProjectID})
+	if err != nil {
+		return fmt.Errorf("failed to get redaction policy: %w", err)
+	}
+
+	interaction.ContentLogged = policy.LogLLMContent
+	if policy.LogLLMContent {
+		redactor, err := redact.NewRedactor(toRedactPolicy(policy))
+		if err != nil {
+			return fmt.Errorf("failed to create redactor: %w", err)
+		}
+		for i := range interaction.Prompt {
+			maskLLMMessage(redactor, &interaction.Prompt[i])
+		}
+		if interaction.Response != nil {
+			maskLLMMessage(redactor, interaction.Response)
+		}
+	} else {
+		interaction.Prompt = nil
+		interaction.Response = nil
+	}
+
+	interaction.InteractionID = "llm-" + uuid.New().String()
+	if interaction.CreatedAt.IsZero() {
+		interaction.CreatedAt = s.now().UTC()
+	}
+	if err := s.interactionRepo.CreateInteraction(ctx, interaction); err != nil {
+		return fmt.Errorf("failed to create LLM interaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetTrace lists the LLM calls made for a task, totalling their tokens
+func (s *DefaultLLMTraceService) GetTrace(ctx context.Context, request models.GetLLMTraceRequest) (*models.GetLLMTraceResponse, error) {
+	task, err := s.taskRepo.GetByID(ctx, request.TaskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+
+	// The prompts and responses reveal the repository, so they are restricted to the members of the task's project
+	if err := s.roleService.Authorize(ctx, request.UserID, task.ProjectID, models.PermissionTaskRead); err != nil {
+		return nil, err
+	}
+
+	interactions, err := s.interactionRepo.ListInteractions(ctx, request.TaskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list LLM interactions: %w", err)
+	}
+
+	response := &models.GetLLMTraceResponse{TaskID: request.TaskID, Interactions: interactions}
+	for _, interaction := range interactions {
+		response.PromptTokens += interaction.PromptTokens
+		response.ResponseTokens += interaction.ResponseTokens
+	}
+	return response, nil
+}
+
+// PurgeExpired deletes the LLM calls past the retention
+func (s *DefaultLLMTraceService) PurgeExpired(ctx context.Context) (int64, error) {
+	deleted, err := s.interactionRepo.DeleteBefore(ctx, s.now().Add(-s.config.Retention))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge LLM interactions: %w", err)
+	}
+	return deleted, nil
+}
+
+// RunPurge deletes the LLM calls past the retention every interval until ctx is cancelled
+func (s *DefaultLLMTraceService) RunPurge(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.PurgeExpired(ctx); err != nil {
+				slog.ErrorContext(ctx, "failed to purge LLM interactions", "error", err)
+			}
+		}
+	}
+}
+
+// maskLLMMessage masks the content of a message and the text arguments of its tool calls
+func maskLLMMessage(redactor *redact.Redactor, message *models.LLMMessage) {
+	message.Content = maskText(redactor, message.Content)
+	for _, call := range message.ToolCalls {
+		for name, value := range call.Arguments {
+			if text, ok := value.(string); ok {
+				call.Arguments[name] = maskText(redactor, text)
+			}
+		}
+	}
+}
+
+func maskText(redactor *redact.Redactor, text string) string {
+	masked, _ := redactor.Redact([]byte(text))
+	return string(masked)
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+	servicesMocks "github.com/kazemisoroush/code-refactoring-tool/api/services/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+type llmTraceServiceMocks struct {
+	interactionRepo  *repositoryMocks.MockLLMInteractionRepository
+	taskRepo         *repositoryMocks.MockTaskRepository
+	roleService      *servicesMocks.MockRoleService
+	redactionService *servicesMocks.MockRedactionService
+}
+
+func newTestLLMTraceService(t *testing.T, enabled bool) (*DefaultLLMTraceService, llmTraceServiceMocks) {
+	ctrl := gomock.NewController(t)
+	m := llmTraceServiceMocks{
+		interactionRepo:  repositoryMocks.NewMockLLMInteractionRepository(ctrl),
+		taskRepo:         repositoryMocks.NewMockTaskRepository(ctrl),
+		roleService:      servicesMocks.NewMockRoleService(ctrl),
+		redactionService: servicesMocks.NewMockRedactionService(ctrl),
+	}
+	service := NewDefaultLLMTraceService(m.interactionRepo, m.taskRepo, m.roleService, m.redactionService,
+		config.LLMLogConfig{Enabled: enabled, Retention: 24 * time.Hour})
+	now := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	return service, m
+}
+
+func newTestLLMInteraction() *models.LLMInteraction {
+	return &models.LLMInteraction{
+		TaskID:    "task-1",
+		ProjectID: "proj-1",
+		Executor:  LocalAgentTaskExecutorName,
+		Step:      1,
+		Prompt: []models.LLMMessage{
+			{Role: "user", Content: "Contact ops@example.com about CUST-123456"},
+			{Role: "assistant", ToolCalls: []models.LLMToolCall{{Name: "grep", Arguments: map[string]any{"pattern": "CUST-123456"}}}},
+		},
+		Response:      &models.LLMMessage{Role: "assistant", Content: "Done."},
+		ContextDocIDs: []string{"main.go"},
+		LatencyMS:     800,
+	}
+}
+
+func TestDefaultLLMTraceService_RecordInteraction_MasksContent(t *testing.T) {
+	service, m := newTestLLMTraceService(t, true)
+
+	m.redactionService.EXPECT().
+		GetPolicy(gomock.Any(), models.GetRedactionPolicyRequest{ProjectID: "proj-1"}).
+		Return(&models.RedactionPolicy{
+			ProjectID:     "proj-1",
+			MaskEmails:    true,
+			Patterns:      []models.RedactionPattern{{Name: "customer-id", Expression: `CUST-[0-9]{6}`}},
+			LogLLMContent: true,
+		}, nil)
+	m.interactionRepo.EXPECT().
+		CreateInteraction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, interaction *models.LLMInteraction) error {
+			assert.NotEmpty(t, interaction.InteractionID)
+			assert.True(t, interaction.ContentLogged)
+			assert.Equal(t, "Contact [REDACTED:email] about [REDACTED:customer-id]", interaction.Prompt[0].Content)
+			assert.Equal(t, "[REDACTED:customer-id]", interaction.Prompt[1].ToolCalls[0].Arguments["pattern"])
+			assert.Equal(t, "Done.", interaction.Response.Content)
+			assert.Equal(t, "2024-01-15T10:30:00Z", interaction.CreatedAt.Format(time.RFC3339))
+			return nil
+		})
+
+	require.NoError(t, service.RecordInteraction(context.Background(), newTestLLMInteraction()))
+}
+
+func TestDefaultLLMTraceService_RecordInteraction_MetadataOnly(t *testing.T) {
+	service, m := newTestLLMTraceService(t, true)
+
+	m.redactionService.EXPECT().
+		GetPolicy(gomock.Any(), gomock.Any()).
+		Return(&models.RedactionPolicy{ProjectID: "proj-1", LogLLMContent: false}, nil)
+	m.interactionRepo.EXPECT().
+		CreateInteraction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, interaction *models.LLMInteraction) error {
+			assert.False(t, interaction.ContentLogged)
+			assert.Nil(t, interaction.Prompt)
+			assert.Nil(t, interaction.Response)
+			assert.Equal(t, []string{"main.go"}, interaction.ContextDocIDs, "metadata is still logged")
+			assert.Equal(t, int64(800), interaction.LatencyMS)
+			return nil
+		})
+
+	require.NoError(t, service.RecordInteraction(context.Background(), newTestLLMInteraction()))
+}
+
+func TestDefaultLLMTraceService_RecordInteraction_Disabled(t *testing.T) {
+	service, _ := newTestLLMTraceService(t, false)
+
+	assert.NoError(t, service.RecordInteraction(context.Background(), newTestLLMInteraction()))
+}
+
+func TestDefaultLLMTraceService_GetTrace(t *testing.T) {
+	service, m := newTestLLMTraceService(t, true)
+
+	m.taskRepo.EXPECT().GetByID(gomock.Any(), "task-1").Return(&models.Task{TaskID: "task-1", ProjectID: "proj-1"}, nil)
+	m.roleService.EXPECT().Authorize(gomock.Any(), "auth-1", "proj-1", models.PermissionTaskRead).Return(nil)
+	m.interactionRepo.EXPECT().ListInteractions(gomock.Any(), "task-1").Return([]models.LLMInteraction{
+		{InteractionID: "llm-1", Step: 1, PromptTokens: 100, ResponseTokens: 10},
+		{InteractionID: "llm-2", Step: 2, PromptTokens: 250, ResponseTokens: 40},
+	}, nil)
+
+	response, err := service.GetTrace(context.Background(), models.GetLLMTraceRequest{TaskID: "task-1", UserID: "auth-1"})
+
+	require.NoError(t, err)
+	assert.Len(t, response.Interactions, 2)
+	assert.Equal(t, 350, response.PromptTokens)
+	assert.Equal(t, 50, response.ResponseTokens)
+}
+
+func TestDefaultLLMTraceService_GetTrace_TaskNotFound(t *testing.T) {
+	service, m := newTestLLMTraceService(t, true)
+
+	m.taskRepo.EXPECT().GetByID(gomock.Any(), "missing").
+		Return(nil, apperrors.NotFound(apperrors.CodeTaskNotFound, "task not found: missing"))
+
+	_, err := service.GetTrace(context.Background(), models.GetLLMTraceRequest{TaskID: "missing"})
+
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
+
+func TestDefaultLLMTraceService_GetTrace_Forbidden(t *testing.T) {
+	service, m := newTestLLMTraceService(t, true)
+
+	// The interactions aren't listed for a caller outside the task's project
+	m.taskRepo.EXPECT().GetByID(gomock.Any(), "task-1").Return(&models.Task{TaskID: "task-1", ProjectID: "proj-1"}, nil)
+	m.roleService.EXPECT().Authorize(gomock.Any(), "auth-2", "proj-1", models.PermissionTaskRead).
+		Return(apperrors.Forbidden(apperrors.CodeForbidden, "forbidden"))
+
+	_, err := service.GetTrace(context.Background(), models.GetLLMTraceRequest{TaskID: "task-1", UserID: "auth-2"})
+
+	assert.ErrorIs(t, err, apperrors.ErrForbidden)
+}
+
+func TestDefaultLLMTraceService_PurgeExpired(t *testing.T) {
+	service, m := newTestLLMTraceService(t, true)
+
+	m.interactionRepo.EXPECT().
+		DeleteBefore(gomock.Any(), time.Date(2024, time.January, 14, 10, 30, 0, 0, time.UTC)).
+		Return(int64(3), nil)
+
+	deleted, err := service.PurgeExpired(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+}
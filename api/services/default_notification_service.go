@@ -0,0 +1,447 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/notification"
+)
+
+// notificationDeliveryTimeout bounds the delivery of a single notification to all of its channels
+const notificationDeliveryTimeout = 30 * time.Second
+
+// Delivery of the notification outbox
+const (
+	outboxBatchSize       = 50
+	outboxLease           = 2 * notificationDeliveryTimeout // Longer than a delivery, so a claimed event isn't delivered twice at once
+	outboxRetryBackoff    = 30 * time.Second                // Wait before a failed event's first retry, doubled on every retry
+	outboxMaxRetryBackoff = time.Hour
+	outboxRetention       = 7 * 24 * time.Hour // How long delivered events are kept
+)
+
+// DefaultNotificationService is the default implementation of NotificationService, delivering notifications to
+// the in-app inbox as well as email and Slack channels
+type DefaultNotificationService struct {
+	channelRepo repository.NotificationChannelRepository
+	inboxRepo   repository.UserNotificationRepository
+	outboxRepo  repository.NotificationOutboxRepository
+	projectRepo repository.ProjectRepository
+	emailSender notification.EmailSender // Nil when email delivery is not configured
+	slackSender notification.SlackSender
+
+	// clientTokens make retries of create requests return the channel they created
+	clientTokens repository.ClientTokenRepository
+}
+
+// NewDefaultNotificationService creates a new DefaultNotificationService. A nil emailSender disables email delivery.
+func NewDefaultNotificationService(
+	channelRepo repository.NotificationChannelRepository,
+	inboxRepo repository.UserNotificationRepository,
+	outboxRepo repository.NotificationOutboxRepository,
+	projectRepo repository.ProjectRepository,
+	emailSender notification.EmailSender,
+	slackSender notification.SlackSender,
+	clientTokens repository.ClientTokenRepository,
+) *DefaultNotificationService {
+	return &DefaultNotificationService{
+		channelRepo:  channelRepo,
+		inboxRepo:    inboxRepo,
+		outboxRepo:   outboxRepo,
+		projectRepo:  projectRepo,
+		emailSender:  emailSender,
+		slackSender:  slackSender,
+		clientTokens: clientTokens,
+	}
+}
+
+// CreateChannel creates a notification channel owned by the caller. Email channels deliver to the caller's address.
+// A request carrying the client token of an earlier request returns the channel that request created.
+func (s *DefaultNotificationService) CreateChannel(ctx context.Context, request models.CreateNotificationChannelRequest) (*models.NotificationChannelResponse, error) {
+	hashed := request
+	hashed.ClientToken = ""
+	claim, channelID, err := claimClientToken(ctx, s.clientTokens, clientTokenKindNotificationChannel, request.UserID, request.ClientToken, hashed)
+	if err != nil {
+		return nil, err
+	}
+	if channelID != "" {
+		channel, err := s.getOwnedChannel(ctx, channelID, request.UserID)
+		if err != nil {
+			return nil, err
+		}
+		return models.NewNotificationChannelResponse(channel), nil
+	}
+
+	response, err := s.createChannel(ctx, request)
+	if response != nil {
+		channelID = response.ChannelID
+	}
+	claim.finish(ctx, channelID, err)
+
+	return response, err
+}
+
+// createChannel creates a notification channel owned by the caller
+func (s *DefaultNotificationService) createChannel(ctx context.Context, request models.CreateNotificationChannelRequest) (*models.NotificationChannelResponse, error) {
+	channel := &models.NotificationChannel{
+		ChannelID: fmt.Sprintf("chan-%s", uuid.New().String()[:13]),
+		UserID:    request.UserID,
+		ProjectID: request.ProjectID,
+		Type:      request.Type,
+		Name:      request.Name,
+		Events:    request.Events,
+		Enabled:   request.Enabled == nil || *request.Enabled,
+		Slack:     request.Slack,
+		CreatedAt: time.Now(),
+	}
+	channel.UpdatedAt = channel.CreatedAt
+
+	switch request.Type {
+	case models.NotificationChannelTypeEmail:
+		if request.Email == "" {
+			return nil, apperrors.Validation(apperrors.CodeInvalidRequest, "email channels require an authenticated user with an email address")
+		}
+		if request.Slack != nil {
+			return nil, apperrors.Validation(apperrors.CodeInvalidRequest, "email channels don't accept a slack configuration")
+		}
+		email := request.Email
+		channel.Email = &email
+	case models.NotificationChannelTypeSlack:
+		if request.ProjectID == nil {
+			return nil, apperrors.Validation(apperrors.CodeInvalidRequest, "slack channels require a project_id")
+		}
+		if err := validateSlackConfig(request.Slack); err != nil {
+			return nil, err
+		}
+	}
+
+	if request.ProjectID != nil {
+		exists, err := s.projectRepo.ProjectExists(ctx, *request.ProjectID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check project existence: %w", err)
+		}
+		if !exists {
+			return nil, apperrors.NotFound(apperrors.CodeProjectNotFound, "project not found: %s", *request.ProjectID)
+		}
+	}
+
+	if err := s.channelRepo.CreateChannel(ctx, channel); err != nil {
+		return nil, fmt.Errorf("failed to create notification channel: %w", err)
+	}
+
+	return models.NewNotificationChannelResponse(channel), nil
+}
+
+// GetChannel retrieves one of the caller's notification channels
+func (s *DefaultNotificationService) GetChannel(ctx context.Context, request models.GetNotificationChannelRequest) (*models.NotificationChannelResponse, error) {
+	channel, err := s.getOwnedChannel(ctx, request.ChannelID, request.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewNotificationChannelResponse(channel), nil
+}
+
+// UpdateChannel updates the name, events, Slack destination or enabled state of one of the caller's channels
+func (s *DefaultNotificationService) UpdateChannel(ctx context.Context, request models.UpdateNotificationChannelRequest) (*models.NotificationChannelResponse, error) {
+	channel, err := s.getOwnedChannel(ctx, request.ChannelID, request.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Name != nil {
+		channel.Name = *request.Name
+	}
+	if request.Events != nil {
+		channel.Events = request.Events
+	}
+	if request.Enabled != nil {
+		channel.Enabled = *request.Enabled
+	}
+	if request.Slack != nil {
+		if channel.Type != models.NotificationChannelTypeSlack {
+			return nil, apperrors.Validation(apperrors.CodeInvalidRequest, "only slack channels accept a slack configuration")
+		}
+		if err := validateSlackConfig(request.Slack); err != nil {
+			return nil, err
+		}
+		channel.Slack = request.Slack
+	}
+	channel.UpdatedAt = time.Now()
+
+	if err := s.channelRepo.UpdateChannel(ctx, channel); err != nil {
+		return nil, fmt.Errorf("failed to update notification channel: %w", err)
+	}
+
+	return models.NewNotificationChannelResponse(channel), nil
+}
+
+// DeleteChannel deletes one of the caller's notification channels
+func (s *DefaultNotificationService) DeleteChannel(ctx context.Context, request models.DeleteNotificationChannelRequest) (*models.DeleteNotificationChannelResponse, error) {
+	if _, err := s.getOwnedChannel(ctx, request.ChannelID, request.UserID); err != nil {
+		return nil, err
+	}
+
+	if err := s.channelRepo.DeleteChannel(ctx, request.ChannelID); err != nil {
+		return nil, fmt.Errorf("failed to delete notification channel: %w", err)
+	}
+
+	return &models.DeleteNotificationChannelResponse{Success: true}, nil
+}
+
+// ListChannels lists the caller's notification channels
+func (s *DefaultNotificationService) ListChannels(ctx context.Context, request models.ListNotificationChannelsRequest) (*models.ListNotificationChannelsResponse, error) {
+	channels, err := s.channelRepo.ListChannelsByUser(ctx, request.UserID, request.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notification channels: %w", err)
+	}
+
+	response := &models.ListNotificationChannelsResponse{
+		Channels: make([]models.NotificationChannelResponse, 0, len(channels)),
+	}
+	for i := range channels {
+		response.Channels = append(response.Channels, *models.NewNotificationChannelResponse(&channels[i]))
+	}
+
+	return response, nil
+}
+
+// ListNotifications lists the caller's inbox notifications newest first, along with the inbox's unread count
+func (s *DefaultNotificationService) ListNotifications(ctx context.Context, request models.ListNotificationsRequest) (*models.ListNotificationsResponse, error) {
+	opts := repository.ListUserNotificationsOptions{
+		UnreadOnly: request.UnreadOnly,
+		NextToken:  request.NextToken,
+		MaxResults: models.DefaultNotificationsPageSize,
+	}
+	if request.MaxResults != nil {
+		opts.MaxResults = *request.MaxResults
+	}
+
+	notifications, nextToken, err := s.inboxRepo.ListNotifications(ctx, request.UserID, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+
+	unreadCount, err := s.inboxRepo.CountUnread(ctx, request.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+
+	response := &models.ListNotificationsResponse{
+		Notifications: notifications,
+		UnreadCount:   unreadCount,
+	}
+	if nextToken != "" {
+		response.NextToken = &nextToken
+	}
+
+	return response, nil
+}
+
+// MarkNotificationRead marks one of the caller's inbox notifications read
+func (s *DefaultNotificationService) MarkNotificationRead(ctx context.Context, request models.MarkNotificationReadRequest) (*models.MarkNotificationReadResponse, error) {
+	if err := s.inboxRepo.MarkRead(ctx, request.UserID, request.NotificationID, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to mark notification read: %w", err)
+	}
+
+	unreadCount, err := s.inboxRepo.CountUnread(ctx, request.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+
+	return &models.MarkNotificationReadResponse{
+		Success:     true,
+		UnreadCount: unreadCount,
+	}, nil
+}
+
+// Notify records the notification in the outbox, which the dispatcher delivers from in the background so that slow
+// channels don't delay the request that raised it
+func (s *DefaultNotificationService) Notify(ctx context.Context, message models.Notification) {
+	if err := s.outboxRepo.Enqueue(context.WithoutCancel(ctx), message); err != nil {
+		slog.ErrorContext(ctx, "failed to record notification", "event", message.Event, "project_id", message.ProjectID, "error", err)
+	}
+}
+
+// RunOutboxDispatch delivers the notifications recorded in the outbox every interval until ctx is cancelled
+func (s *DefaultNotificationService) RunOutboxDispatch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.DispatchOutbox(ctx); err != nil {
+				slog.ErrorContext(ctx, "failed to dispatch notification outbox", "error", err)
+			}
+		}
+	}
+}
+
+// DispatchOutbox delivers the outbox events that are due, returning how many were delivered. An event whose
+// delivery fails is retried with exponential backoff until it's delivered, so every event is delivered at least once.
+func (s *DefaultNotificationService) DispatchOutbox(ctx context.Context) (int, error) {
+	if _, err := s.outboxRepo.DeleteDelivered(ctx, time.Now().Add(-outboxRetention)); err != nil {
+		slog.WarnContext(ctx, "failed to delete delivered outbox events", "error", err)
+	}
+
+	delivered := 0
+	for {
+		events, err := s.outboxRepo.ClaimDue(ctx, outboxBatchSize, outboxLease)
+		if err != nil {
+			return delivered, err
+		}
+
+		for _, event := range events {
+			if s.dispatch(ctx, event) {
+				delivered++
+			}
+		}
+		if len(events) < outboxBatchSize {
+			return delivered, nil
+		}
+	}
+}
+
+// dispatch delivers a claimed outbox event and records the outcome, reporting whether it was delivered
+func (s *DefaultNotificationService) dispatch(ctx context.Context, event models.OutboxEvent) bool {
+	deliveryCtx, cancel := context.WithTimeout(ctx, notificationDeliveryTimeout)
+	defer cancel()
+
+	if err := s.deliver(deliveryCtx, event.Notification); err != nil {
+		retryAt := time.Now().Add(outboxBackoff(event.Attempts))
+		slog.WarnContext(ctx, "failed to deliver notification, retrying", "event_id", event.EventID, "event", event.Notification.Event,
+			"attempts", event.Attempts, "retry_at", retryAt, "error", err)
+		if markErr := s.outboxRepo.MarkFailed(ctx, event.EventID, err.Error(), retryAt); markErr != nil {
+			slog.ErrorContext(ctx, "failed to record notification delivery failure", "event_id", event.EventID, "error", markErr)
+		}
+		return false
+	}
+
+	// An event that can't be marked is delivered again once its lease expires
+	if err := s.outboxRepo.MarkDelivered(ctx, event.EventID); err != nil {
+		slog.ErrorContext(ctx, "failed to mark notification delivered", "event_id", event.EventID, "error", err)
+	}
+	return true
+}
+
+// outboxBackoff returns the wait before retrying an event that failed its given number of attempts
+func outboxBackoff(attempts int) time.Duration {
+	backoff := outboxRetryBackoff
+	for i := 1; i < attempts && backoff < outboxMaxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, outboxMaxRetryBackoff)
+}
+
+// deliver records the notification in the user's inbox, if it has one, and sends it to every subscribed channel,
+// continuing past failures
+func (s *DefaultNotificationService) deliver(ctx context.Context, message models.Notification) error {
+	var errs []error
+	if message.UserID != "" {
+		if err := s.recordInInbox(ctx, message); err != nil {
+			errs = append(errs, fmt.Errorf("inbox of user %s: %w", message.UserID, err))
+		}
+	}
+
+	channels, err := s.channelRepo.ListSubscribedChannels(ctx, message.Event, message.ProjectID)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to list subscribed channels: %w", err))
+	}
+
+	for _, channel := range channels {
+		if err := s.send(ctx, channel, message); err != nil {
+			errs = append(errs, fmt.Errorf("channel %s: %w", channel.ChannelID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// recordInInbox stores the notification in the inbox of its user
+func (s *DefaultNotificationService) recordInInbox(ctx context.Context, message models.Notification) error {
+	// Redeliveries of an outbox event record the notification under the same ID, so the inbox keeps one copy
+	notificationID := fmt.Sprintf("notif-%s", uuid.New().String())
+	if message.EventID != "" {
+		notificationID = "notif-" + message.EventID
+	}
+
+	inboxNotification := &models.UserNotification{
+		NotificationID: notificationID,
+		UserID:         message.UserID,
+		Event:          message.Event,
+		Title:          message.Subject,
+		Message:        message.Message,
+		CreatedAt:      time.Now(),
+	}
+	if message.ProjectID != "" {
+		projectID := message.ProjectID
+		inboxNotification.ProjectID = &projectID
+	}
+	if message.ResourceID != "" {
+		resourceID := message.ResourceID
+		inboxNotification.ResourceID = &resourceID
+	}
+
+	return s.inboxRepo.CreateNotification(ctx, inboxNotification)
+}
+
+// send delivers a notification to a single channel
+func (s *DefaultNotificationService) send(ctx context.Context, channel models.NotificationChannel, message models.Notification) error {
+	switch channel.Type {
+	case models.NotificationChannelTypeEmail:
+		if s.emailSender == nil || channel.Email == nil {
+			slog.WarnContext(ctx, "skipping email notification, email delivery is not configured", "channel_id", channel.ChannelID)
+			return nil
+		}
+		return s.emailSender.SendEmail(ctx, *channel.Email, message.Subject, message.Message)
+	case models.NotificationChannelTypeSlack:
+		if channel.Slack == nil {
+			return errors.New("slack channel has no destination")
+		}
+		return s.slackSender.PostMessage(ctx, notification.SlackDestination{
+			WebhookURL: channel.Slack.WebhookURL,
+			BotToken:   channel.Slack.BotToken,
+			Channel:    channel.Slack.Channel,
+		}, fmt.Sprintf("*%s*\n%s", message.Subject, message.Message))
+	default:
+		return fmt.Errorf("unsupported channel type: %s", channel.Type)
+	}
+}
+
+// getOwnedChannel retrieves a channel, reporting channels of other users as not found
+func (s *DefaultNotificationService) getOwnedChannel(ctx context.Context, channelID, userID string) (*models.NotificationChannel, error) {
+	channel, err := s.channelRepo.GetChannel(ctx, channelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification channel: %w", err)
+	}
+	if channel.UserID != userID {
+		return nil, apperrors.NotFound(apperrors.CodeChannelNotFound, "notification channel not found: %s", channelID)
+	}
+
+	return channel, nil
+}
+
+// validateSlackConfig ensures a Slack configuration has exactly one of an incoming webhook or a bot token and channel
+func validateSlackConfig(config *models.SlackChannelConfig) error {
+	if config == nil {
+		return apperrors.Validation(apperrors.CodeInvalidRequest, "slack channels require a slack configuration")
+	}
+	if (config.WebhookURL == "") == (config.BotToken == "") {
+		return apperrors.Validation(apperrors.CodeInvalidRequest, "slack configuration requires exactly one of webhook_url or bot_token")
+	}
+	if config.BotToken != "" && config.Channel == "" {
+		return apperrors.Validation(apperrors.CodeInvalidRequest, "slack bot tokens require a channel")
+	}
+
+	return nil
+}
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/notification"
+	notificationMocks "github.com/kazemisoroush/code-refactoring-tool/pkg/notification/mocks"
+)
+
+func TestDefaultNotificationService_CreateChannel(t *testing.T) {
+	projectID := "proj-1"
+
+	tests := []struct {
+		name        string
+		request     models.CreateNotificationChannelRequest
+		expectsSave bool
+		expectedErr error
+	}{
+		{
+			name: "email_uses_caller_address",
+			request: models.CreateNotificationChannelRequest{
+				Type: models.NotificationChannelTypeEmail, Name: "Me", Events: []models.NotificationEvent{models.NotificationEventTaskFailed},
+				UserID: "user-1", Email: "dev@example.com",
+			},
+			expectsSave: true,
+		},
+		{
+			name: "slack_webhook",
+			request: models.CreateNotificationChannelRequest{
+				Type: models.NotificationChannelTypeSlack, Name: "Team", ProjectID: &projectID,
+				Events: []models.NotificationEvent{models.NotificationEventTaskCompleted},
+				Slack:  &models.SlackChannelConfig{WebhookURL: "https://hooks.slack.com/services/T/B/X"},
+				UserID: "user-1",
+			},
+			expectsSave: true,
+		},
+		{
+			name: "slack_without_project",
+			request: models.CreateNotificationChannelRequest{
+				Type: models.NotificationChannelTypeSlack, Name: "Team",
+				Events: []models.NotificationEvent{models.NotificationEventTaskCompleted},
+				Slack:  &models.SlackChannelConfig{WebhookURL: "https://hooks.slack.com/services/T/B/X"},
+			},
+			expectedErr: apperrors.ErrValidation,
+		},
+		{
+			name: "slack_with_webhook_and_token",
+			request: models.CreateNotificationChannelRequest{
+				Type: models.NotificationChannelTypeSlack, Name: "Team", ProjectID: &projectID,
+				Events: []models.NotificationEvent{models.NotificationEventTaskCompleted},
+				Slack:  &models.SlackChannelConfig{WebhookURL: "https://hooks.slack.com/services/T/B/X", BotToken: "xoxb-1", Channel: "#ops"},
+			},
+			expectedErr: apperrors.ErrValidation,
+		},
+		{
+			name: "email_without_address",
+			request: models.CreateNotificationChannelRequest{
+				Type: models.NotificationChannelTypeEmail, Name: "Me", Events: []models.NotificationEvent{models.NotificationEventTaskFailed},
+				UserID: "user-1",
+			},
+			expectedErr: apperrors.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			channelRepo := repositoryMocks.NewMockNotificationChannelRepository(ctrl)
+			projectRepo := repositoryMocks.NewMockProjectRepository(ctrl)
+			service := NewDefaultNotificationService(channelRepo, repositoryMocks.NewMockUserNotificationRepository(ctrl), repositoryMocks.NewMockNotificationOutboxRepository(ctrl), projectRepo, nil, notificationMocks.NewMockSlackSender(ctrl), nil)
+
+			if tt.expectsSave {
+				if tt.request.ProjectID != nil {
+					projectRepo.EXPECT().ProjectExists(gomock.Any(), *tt.request.ProjectID).Return(true, nil)
+				}
+				channelRepo.EXPECT().CreateChannel(gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			response, err := service.CreateChannel(context.Background(), tt.request)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, response.Enabled)
+			if tt.request.Type == models.NotificationChannelTypeEmail {
+				require.NotNil(t, response.Email)
+				assert.Equal(t, tt.request.Email, *response.Email)
+			}
+		})
+	}
+}
+
+func TestDefaultNotificationService_GetChannel_OtherUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	channelRepo := repositoryMocks.NewMockNotificationChannelRepository(ctrl)
+	service := NewDefaultNotificationService(channelRepo, repositoryMocks.NewMockUserNotificationRepository(ctrl), repositoryMocks.NewMockNotificationOutboxRepository(ctrl), repositoryMocks.NewMockProjectRepository(ctrl), nil, nil, nil)
+
+	channelRepo.EXPECT().GetChannel(gomock.Any(), "chan-1").Return(&models.NotificationChannel{ChannelID: "chan-1", UserID: "user-2"}, nil)
+
+	_, err := service.GetChannel(context.Background(), models.GetNotificationChannelRequest{ChannelID: "chan-1", UserID: "user-1"})
+
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
+
+func TestDefaultNotificationService_Deliver(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	channelRepo := repositoryMocks.NewMockNotificationChannelRepository(ctrl)
+	inboxRepo := repositoryMocks.NewMockUserNotificationRepository(ctrl)
+	emailSender := notificationMocks.NewMockEmailSender(ctrl)
+	slackSender := notificationMocks.NewMockSlackSender(ctrl)
+	service := NewDefaultNotificationService(channelRepo, inboxRepo, repositoryMocks.NewMockNotificationOutboxRepository(ctrl), repositoryMocks.NewMockProjectRepository(ctrl), emailSender, slackSender, nil)
+
+	email := "dev@example.com"
+	message := models.Notification{
+		Event:      models.NotificationEventTaskFailed,
+		ProjectID:  "proj-1",
+		UserID:     "user-1",
+		ResourceID: "task-1",
+		Subject:    "Task failed: Refactor payments",
+		Message:    "Task task-1 in project proj-1 failed.",
+	}
+
+	inboxRepo.EXPECT().
+		CreateNotification(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, inboxNotification *models.UserNotification) error {
+			assert.Equal(t, "user-1", inboxNotification.UserID)
+			assert.Equal(t, message.Subject, inboxNotification.Title)
+			require.NotNil(t, inboxNotification.ResourceID)
+			assert.Equal(t, "task-1", *inboxNotification.ResourceID)
+			assert.Nil(t, inboxNotification.ReadAt)
+			return nil
+		})
+
+	channelRepo.EXPECT().ListSubscribedChannels(gomock.Any(), models.NotificationEventTaskFailed, "proj-1").Return([]models.NotificationChannel{
+		{ChannelID: "chan-1", Type: models.NotificationChannelTypeEmail, Email: &email},
+		{ChannelID: "chan-2", Type: models.NotificationChannelTypeSlack, Slack: &models.SlackChannelConfig{BotToken: "xoxb-1", Channel: "#ops"}},
+		{ChannelID: "chan-3", Type: models.NotificationChannelTypeSlack, Slack: &models.SlackChannelConfig{WebhookURL: "https://hooks.slack.com/services/T/B/X"}},
+	}, nil)
+	emailSender.EXPECT().SendEmail(gomock.Any(), email, message.Subject, message.Message).Return(nil)
+	slackSender.EXPECT().
+		PostMessage(gomock.Any(), notification.SlackDestination{BotToken: "xoxb-1", Channel: "#ops"}, "*Task failed: Refactor payments*\nTask task-1 in project proj-1 failed.").
+		Return(errors.New("channel_not_found"))
+	slackSender.EXPECT().
+		PostMessage(gomock.Any(), notification.SlackDestination{WebhookURL: "https://hooks.slack.com/services/T/B/X"}, gomock.Any()).
+		Return(nil)
+
+	err := service.deliver(context.Background(), message)
+
+	// A failing channel doesn't stop delivery to the others
+	assert.ErrorContains(t, err, "channel chan-2: channel_not_found")
+}
+
+func TestDefaultNotificationService_DispatchOutbox(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	channelRepo := repositoryMocks.NewMockNotificationChannelRepository(ctrl)
+	inboxRepo := repositoryMocks.NewMockUserNotificationRepository(ctrl)
+	outboxRepo := repositoryMocks.NewMockNotificationOutboxRepository(ctrl)
+	slackSender := notificationMocks.NewMockSlackSender(ctrl)
+	service := NewDefaultNotificationService(channelRepo, inboxRepo, outboxRepo, repositoryMocks.NewMockProjectRepository(ctrl), nil, slackSender, nil)
+
+	webhook := models.NotificationChannel{ChannelID: "chan-1", Type: models.NotificationChannelTypeSlack, Slack: &models.SlackChannelConfig{WebhookURL: "https://hooks.slack.com/services/T/B/X"}}
+	delivered := models.OutboxEvent{EventID: "evt-1", Attempts: 1, Notification: models.Notification{
+		EventID: "evt-1", Event: models.NotificationEventTaskCompleted, ProjectID: "proj-1", UserID: "user-1", Subject: "Task completed: one",
+	}}
+	failing := models.OutboxEvent{EventID: "evt-2", Attempts: 3, Notification: models.Notification{
+		EventID: "evt-2", Event: models.NotificationEventTaskFailed, ProjectID: "proj-2", Subject: "Task failed: two",
+	}}
+
+	outboxRepo.EXPECT().DeleteDelivered(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+	outboxRepo.EXPECT().ClaimDue(gomock.Any(), outboxBatchSize, outboxLease).Return([]models.OutboxEvent{delivered, failing}, nil)
+
+	// The inbox entry is keyed by the event, so a redelivery doesn't duplicate it
+	inboxRepo.EXPECT().
+		CreateNotification(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, inboxNotification *models.UserNotification) error {
+			assert.Equal(t, "notif-evt-1", inboxNotification.NotificationID)
+			return nil
+		})
+	channelRepo.EXPECT().ListSubscribedChannels(gomock.Any(), models.NotificationEventTaskCompleted, "proj-1").Return([]models.NotificationChannel{webhook}, nil)
+	channelRepo.EXPECT().ListSubscribedChannels(gomock.Any(), models.NotificationEventTaskFailed, "proj-2").Return([]models.NotificationChannel{webhook}, nil)
+	slackSender.EXPECT().PostMessage(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	slackSender.EXPECT().PostMessage(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("webhook returned 500"))
+
+	outboxRepo.EXPECT().MarkDelivered(gomock.Any(), "evt-1").Return(nil)
+	outboxRepo.EXPECT().
+		MarkFailed(gomock.Any(), "evt-2", gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, deliveryErr string, retryAt time.Time) error {
+			assert.Contains(t, deliveryErr, "webhook returned 500")
+			// The third attempt waits four times the first backoff
+			assert.WithinDuration(t, time.Now().Add(4*outboxRetryBackoff), retryAt, 5*time.Second)
+			return nil
+		})
+
+	count, err := service.DispatchOutbox(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestOutboxBackoff_IsCapped(t *testing.T) {
+	assert.Equal(t, outboxRetryBackoff, outboxBackoff(1))
+	assert.Equal(t, 2*outboxRetryBackoff, outboxBackoff(2))
+	assert.Equal(t, outboxMaxRetryBackoff, outboxBackoff(50))
+}
+
+func TestDefaultNotificationService_ListNotifications(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	inboxRepo := repositoryMocks.NewMockUserNotificationRepository(ctrl)
+	service := NewDefaultNotificationService(repositoryMocks.NewMockNotificationChannelRepository(ctrl), inboxRepo, repositoryMocks.NewMockNotificationOutboxRepository(ctrl), repositoryMocks.NewMockProjectRepository(ctrl), nil, nil, nil)
+
+	inboxRepo.EXPECT().
+		ListNotifications(gomock.Any(), "user-1", repository.ListUserNotificationsOptions{UnreadOnly: true, MaxResults: models.DefaultNotificationsPageSize}).
+		Return([]models.UserNotification{{NotificationID: "notif-2"}, {NotificationID: "notif-1"}}, "notif-1", nil)
+	inboxRepo.EXPECT().CountUnread(gomock.Any(), "user-1").Return(5, nil)
+
+	response, err := service.ListNotifications(context.Background(), models.ListNotificationsRequest{UnreadOnly: true, UserID: "user-1"})
+
+	require.NoError(t, err)
+	assert.Len(t, response.Notifications, 2)
+	assert.Equal(t, 5, response.UnreadCount)
+	require.NotNil(t, response.NextToken)
+	assert.Equal(t, "notif-1", *response.NextToken)
+}
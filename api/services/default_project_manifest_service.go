@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+)
+
+// DefaultProjectManifestService is the default implementation of ProjectManifestService
+type DefaultProjectManifestService struct {
+	projectRepo        repository.ProjectRepository
+	codebaseRepo       repository.CodebaseRepository
+	codebaseConfigRepo repository.CodebaseConfigRepository
+	agentRepo          repository.AgentRepository
+	automationRepo     repository.ProjectAutomationRepository
+	agentService       AgentService
+	tagService         TagService
+}
+
+// NewDefaultProjectManifestService creates a new DefaultProjectManifestService. The tag service governs the tags set on
+// the imported project and codebases.
+func NewDefaultProjectManifestService(
+	projectRepo repository.ProjectRepository,
+	codebaseRepo repository.CodebaseRepository,
+	codebaseConfigRepo repository.CodebaseConfigRepository,
+	agentRepo repository.AgentRepository,
+	automationRepo repository.ProjectAutomationRepository,
+	agentService AgentService,
+	tagService TagService,
+) *DefaultProjectManifestService {
+	return &DefaultProjectManifestService{
+		projectRepo:        projectRepo,
+		codebaseRepo:       codebaseRepo,
+		codebaseConfigRepo: codebaseConfigRepo,
+		agentRepo:          agentRepo,
+		automationRepo:     automationRepo,
+		agentService:       agentService,
+		tagService:         tagService,
+	}
+}
+
+// ExportProject builds a manifest for a project. Codebases carry only a reference to their
+// configuration so credentials never leave the service. Agents are not linked to projects
+// directly, so the agents exported are those analyzing one of the project's codebase URLs.
+func (s *DefaultProjectManifestService) ExportProject(ctx context.Context, projectID string) (*models.ProjectManifest, error) {
+	projectRecord, err := s.projectRepo.GetProject(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	if projectRecord == nil {
+		return nil, apperrors.NotFound(apperrors.CodeProjectNotFound, "project not found")
+	}
+
+	manifest := &models.ProjectManifest{
+		APIVersion: models.ProjectManifestAPIVersion,
+		Kind:       models.ProjectManifestKind,
+		Project: models.ProjectManifestSpec{
+			Name:        projectRecord.Name,
+			Description: projectRecord.Description,
+			Language:    projectRecord.Language,
+			Tags:        projectRecord.Tags,
+			Metadata:    projectRecord.Metadata,
+		},
+	}
+
+	codebases, err := s.codebaseRepo.GetCodebasesByProject(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project codebases: %w", err)
+	}
+
+	codebaseURLs := make(map[string]bool, len(codebases))
+	for _, codebase := range codebases {
+		manifest.Codebases = append(manifest.Codebases, models.CodebaseManifest{
+			Name:      codebase.Name,
+			Provider:  codebase.Provider,
+			URL:       codebase.URL,
+			ConfigRef: codebase.ConfigID,
+			Tags:      codebase.Tags,
+		})
+		codebaseURLs[codebase.URL] = true
+	}
+
+	if len(codebaseURLs) > 0 {
+		agents, err := s.agentRepo.ListAgents(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list agents: %w", err)
+		}
+
+		for _, agent := range agents {
+			if !codebaseURLs[agent.RepositoryURL] {
+				continue
+			}
+			manifest.Agents = append(manifest.Agents, models.AgentManifest{
+				Name:          agent.AgentName,
+				RepositoryURL: agent.RepositoryURL,
+				Branch:        agent.Branch,
+				AIProvider:    agent.GetAIProvider(),
+			})
+		}
+	}
+
+	automation, err := s.automationRepo.GetAutomation(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project automation: %w", err)
+	}
+
+	if automation != nil {
+		manifest.Schedules = automation.ScheduledAnalyses
+		manifest.Webhooks = automation.Webhooks
+	}
+
+	return manifest, nil
+}
+
+// ImportProject creates a new project from a manifest. Codebase configuration references and
+// tags are verified before anything is written; agent provisioning failures are reported as
+// warnings because the project and its codebases are still usable without them.
+func (s *DefaultProjectManifestService) ImportProject(ctx context.Context, manifest models.ProjectManifest) (*models.ImportProjectResponse, error) {
+	for _, codebase := range manifest.Codebases {
+		exists, err := s.codebaseConfigRepo.CodebaseConfigExists(ctx, codebase.ConfigRef)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check codebase configuration %s: %w", codebase.ConfigRef, err)
+		}
+		if !exists {
+			return nil, apperrors.Validation(apperrors.CodeCodebaseConfigNotFound, "codebase configuration not found: %s", codebase.ConfigRef)
+		}
+		if len(codebase.Tags) > 0 {
+			if err := s.tagService.AuthorizeTags(ctx, manifest.UserID, nil, codebase.Tags); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(manifest.Project.Tags) > 0 {
+		if err := s.tagService.AuthorizeTags(ctx, manifest.UserID, nil, manifest.Project.Tags); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now().UTC()
+	projectRecord := &repository.ProjectRecord{
+		ProjectID:   generateProjectID(),
+		Name:        manifest.Project.Name,
+		Description: manifest.Project.Description,
+		Language:    manifest.Project.Language,
+		Status:      string(models.ProjectStatusActive),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		Tags:        manifest.Project.Tags,
+		Metadata:    manifest.Project.Metadata,
+	}
+
+	if projectRecord.Tags == nil {
+		projectRecord.Tags = make(map[string]string)
+	}
+	if projectRecord.Metadata == nil {
+		projectRecord.Metadata = make(map[string]string)
+	}
+
+	if err := s.projectRepo.CreateProject(ctx, projectRecord); err != nil {
+		return nil, fmt.Errorf("failed to create project: %w", err)
+	}
+
+	response := &models.ImportProjectResponse{
+		ProjectID:   projectRecord.ProjectID,
+		CodebaseIDs: []string{},
+		AgentIDs:    []string{},
+		CreatedAt:   now.Format(time.RFC3339),
+	}
+
+	for _, spec := range manifest.Codebases {
+		codebase := &models.Codebase{
+			CodebaseID: uuid.New().String(),
+			ProjectID:  projectRecord.ProjectID,
+			Name:       spec.Name,
+			Provider:   spec.Provider,
+			URL:        spec.URL,
+			ConfigID:   spec.ConfigRef,
+			Status:     models.CodebaseStatusActive,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+			Tags:       spec.Tags,
+			Metadata:   make(map[string]string),
+		}
+		if codebase.Tags == nil {
+			codebase.Tags = make(map[string]string)
+		}
+
+		if err := s.codebaseRepo.CreateCodebase(ctx, codebase); err != nil {
+			return nil, fmt.Errorf("failed to create codebase %s: %w", spec.Name, err)
+		}
+		response.CodebaseIDs = append(response.CodebaseIDs, codebase.CodebaseID)
+	}
+
+	if len(manifest.Schedules) > 0 || len(manifest.Webhooks) > 0 {
+		automation := &models.ProjectAutomation{
+			ProjectID:         projectRecord.ProjectID,
+			ScheduledAnalyses: manifest.Schedules,
+			Webhooks:          manifest.Webhooks,
+			UpdatedAt:         now,
+		}
+		if err := s.automationRepo.SaveAutomation(ctx, automation); err != nil {
+			return nil, fmt.Errorf("failed to save project automation: %w", err)
+		}
+	}
+
+	for _, spec := range manifest.Agents {
+		agent, err := s.agentService.CreateAgent(ctx, models.CreateAgentRequest{
+			RepositoryURL: spec.RepositoryURL,
+			Branch:        spec.Branch,
+			AgentName:     spec.Name,
+			AIProvider:    spec.AIProvider,
+			ProjectID:     projectRecord.ProjectID,
+		})
+		if err != nil {
+			response.Warnings = append(response.Warnings,
+				fmt.Sprintf("failed to create agent for %s: %v", spec.RepositoryURL, err))
+			continue
+		}
+		response.AgentIDs = append(response.AgentIDs, agent.AgentID)
+	}
+
+	return response, nil
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+	servicesMocks "github.com/kazemisoroush/code-refactoring-tool/api/services/mocks"
+)
+
+type manifestServiceMocks struct {
+	projectRepo        *repositoryMocks.MockProjectRepository
+	codebaseRepo       *repositoryMocks.MockCodebaseRepository
+	codebaseConfigRepo *repositoryMocks.MockCodebaseConfigRepository
+	agentRepo          *repositoryMocks.MockAgentRepository
+	automationRepo     *repositoryMocks.MockProjectAutomationRepository
+	agentService       *servicesMocks.MockAgentService
+	tagService         *servicesMocks.MockTagService
+}
+
+func newTestProjectManifestService(ctrl *gomock.Controller) (*DefaultProjectManifestService, *manifestServiceMocks) {
+	m := &manifestServiceMocks{
+		projectRepo:        repositoryMocks.NewMockProjectRepository(ctrl),
+		codebaseRepo:       repositoryMocks.NewMockCodebaseRepository(ctrl),
+		codebaseConfigRepo: repositoryMocks.NewMockCodebaseConfigRepository(ctrl),
+		agentRepo:          repositoryMocks.NewMockAgentRepository(ctrl),
+		automationRepo:     repositoryMocks.NewMockProjectAutomationRepository(ctrl),
+		agentService:       servicesMocks.NewMockAgentService(ctrl),
+		tagService:         servicesMocks.NewMockTagService(ctrl),
+	}
+	service := NewDefaultProjectManifestService(m.projectRepo, m.codebaseRepo, m.codebaseConfigRepo, m.agentRepo, m.automationRepo, m.agentService, m.tagService)
+	return service, m
+}
+
+func TestDefaultProjectManifestService_ExportProject(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newTestProjectManifestService(ctrl)
+	repoURL := "https://github.com/acme/payments.git"
+
+	m.projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{
+		ProjectID: "proj-1",
+		Name:      "payments",
+		Tags:      map[string]string{"team": "payments"},
+	}, nil)
+	m.codebaseRepo.EXPECT().GetCodebasesByProject(gomock.Any(), "proj-1").Return([]*models.Codebase{
+		{CodebaseID: "cb-1", Name: "payments", Provider: models.ProviderGitHub, URL: repoURL, ConfigID: "config-1"},
+	}, nil)
+	m.agentRepo.EXPECT().ListAgents(gomock.Any()).Return([]*repository.AgentRecord{
+		{AgentID: "agent-1", RepositoryURL: repoURL, Branch: "main", AIProvider: "bedrock"},
+		{AgentID: "agent-2", RepositoryURL: "https://github.com/acme/other.git"},
+	}, nil)
+	m.automationRepo.EXPECT().GetAutomation(gomock.Any(), "proj-1").Return(&models.ProjectAutomation{
+		ProjectID:         "proj-1",
+		ScheduledAnalyses: []models.ScheduledAnalysis{{Name: "nightly", Schedule: "0 3 * * *"}},
+	}, nil)
+
+	manifest, err := service.ExportProject(context.Background(), "proj-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, models.ProjectManifestAPIVersion, manifest.APIVersion)
+	assert.Equal(t, "payments", manifest.Project.Name)
+	require.Len(t, manifest.Codebases, 1)
+	assert.Equal(t, "config-1", manifest.Codebases[0].ConfigRef)
+	require.Len(t, manifest.Agents, 1)
+	assert.Equal(t, models.AIProviderBedrock, manifest.Agents[0].AIProvider)
+	assert.Len(t, manifest.Schedules, 1)
+}
+
+func TestDefaultProjectManifestService_ExportProject_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newTestProjectManifestService(ctrl)
+	m.projectRepo.EXPECT().GetProject(gomock.Any(), "proj-missing").Return(nil, nil)
+
+	_, err := service.ExportProject(context.Background(), "proj-missing")
+	assert.EqualError(t, err, "project not found")
+}
+
+func TestDefaultProjectManifestService_ImportProject(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newTestProjectManifestService(ctrl)
+	manifest := models.ProjectManifest{
+		APIVersion: models.ProjectManifestAPIVersion,
+		Kind:       models.ProjectManifestKind,
+		Project:    models.ProjectManifestSpec{Name: "payments"},
+		Codebases: []models.CodebaseManifest{
+			{Name: "payments", Provider: models.ProviderGitHub, URL: "https://github.com/acme/payments.git", ConfigRef: "config-1"},
+		},
+		Agents: []models.AgentManifest{
+			{RepositoryURL: "https://github.com/acme/payments.git"},
+			{RepositoryURL: "https://github.com/acme/broken.git"},
+		},
+		Webhooks: []models.WebhookRegistration{{URL: "https://hooks.example.com", Events: []string{"task.completed"}}},
+	}
+
+	var projectID string
+	m.codebaseConfigRepo.EXPECT().CodebaseConfigExists(gomock.Any(), "config-1").Return(true, nil)
+	m.projectRepo.EXPECT().
+		CreateProject(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, record *repository.ProjectRecord) error {
+			projectID = record.ProjectID
+			assert.Equal(t, "payments", record.Name)
+			return nil
+		})
+	m.codebaseRepo.EXPECT().
+		CreateCodebase(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, codebase *models.Codebase) error {
+			assert.Equal(t, projectID, codebase.ProjectID)
+			assert.Equal(t, "config-1", codebase.ConfigID)
+			return nil
+		})
+	m.automationRepo.EXPECT().
+		SaveAutomation(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, automation *models.ProjectAutomation) error {
+			assert.Equal(t, projectID, automation.ProjectID)
+			assert.Len(t, automation.Webhooks, 1)
+			return nil
+		})
+	m.agentService.EXPECT().
+		CreateAgent(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, request models.CreateAgentRequest) (*models.CreateAgentResponse, error) {
+			assert.Equal(t, projectID, request.ProjectID)
+			if request.RepositoryURL == "https://github.com/acme/broken.git" {
+				return nil, errors.New("provisioning failed")
+			}
+			assert.Equal(t, "https://github.com/acme/payments.git", request.RepositoryURL)
+			return &models.CreateAgentResponse{AgentID: "agent-1"}, nil
+		}).
+		Times(2)
+
+	response, err := service.ImportProject(context.Background(), manifest)
+
+	require.NoError(t, err)
+	assert.Equal(t, projectID, response.ProjectID)
+	assert.Len(t, response.CodebaseIDs, 1)
+	assert.Equal(t, []string{"agent-1"}, response.AgentIDs)
+	require.Len(t, response.Warnings, 1)
+	assert.Contains(t, response.Warnings[0], "broken.git")
+}
+
+func TestDefaultProjectManifestService_ImportProject_UnknownConfigRef(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newTestProjectManifestService(ctrl)
+	manifest := models.ProjectManifest{
+		Project: models.ProjectManifestSpec{Name: "payments"},
+		Codebases: []models.CodebaseManifest{
+			{Name: "payments", Provider: models.ProviderGitHub, URL: "https://github.com/acme/payments.git", ConfigRef: "config-missing"},
+		},
+	}
+
+	m.codebaseConfigRepo.EXPECT().CodebaseConfigExists(gomock.Any(), "config-missing").Return(false, nil)
+
+	_, err := service.ImportProject(context.Background(), manifest)
+	assert.EqualError(t, err, "codebase configuration not found: config-missing")
+}
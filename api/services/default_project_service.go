@@ -3,28 +3,86 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
 	"github.com/kazemisoroush/code-refactoring-tool/api/models"
 	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
 )
 
 // DefaultProjectService is the default implementation of ProjectService
 type DefaultProjectService struct {
-	projectRepo repository.ProjectRepository
+	projectRepo  repository.ProjectRepository
+	agentRepo    repository.AgentRepository
+	agentService AgentService
+	tagService   TagService
+	roleService  RoleService
+	clientTokens repository.ClientTokenRepository
 }
 
-// NewDefaultProjectService creates a new DefaultProjectService
-func NewDefaultProjectService(projectRepo repository.ProjectRepository) *DefaultProjectService {
+// NewDefaultProjectService creates a new DefaultProjectService. The agent service deletes the agents of a project
+// archived with its artifacts purged and re-embeds them when the project switches embedding models, the tag service governs the tags set on projects and the role service makes
+// the creator of a project its owner. The client tokens make retries of create requests return the project they
+// created.
+func NewDefaultProjectService(projectRepo repository.ProjectRepository, agentRepo repository.AgentRepository, agentService AgentService, tagService TagService, roleService RoleService, clientTokens repository.ClientTokenRepository) *DefaultProjectService {
 	return &DefaultProjectService{
-		projectRepo: projectRepo,
+		projectRepo:  projectRepo,
+		agentRepo:    agentRepo,
+		agentService: agentService,
+		tagService:   tagService,
+		roleService:  roleService,
+		clientTokens: clientTokens,
 	}
 }
 
-// CreateProject creates a new project with the given parameters
+// CreateProject creates a new project with the given parameters. A request carrying the client token of an earlier
+// request returns the project that request created.
 func (s *DefaultProjectService) CreateProject(ctx context.Context, request models.CreateProjectRequest) (*models.CreateProjectResponse, error) {
+	hashed := request
+	hashed.ClientToken = ""
+	claim, projectID, err := claimClientToken(ctx, s.clientTokens, clientTokenKindProject, request.UserID, request.ClientToken, hashed)
+	if err != nil {
+		return nil, err
+	}
+	if projectID != "" {
+		projectRecord, err := s.projectRepo.GetProject(ctx, projectID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get project: %w", err)
+		}
+		if projectRecord == nil {
+			return nil, apperrors.NotFound(apperrors.CodeProjectNotFound, "project created with client token %s was deleted", request.ClientToken)
+		}
+		return &models.CreateProjectResponse{
+			ProjectID: projectID,
+			CreatedAt: projectRecord.CreatedAt.Format(time.RFC3339),
+		}, nil
+	}
+
+	response, err := s.createProject(ctx, request)
+	if response != nil {
+		projectID = response.ProjectID
+	}
+	claim.finish(ctx, projectID, err)
+
+	return response, err
+}
+
+// createProject creates a new project, making its creator its owner
+func (s *DefaultProjectService) createProject(ctx context.Context, request models.CreateProjectRequest) (*models.CreateProjectResponse, error) {
+	if len(request.Tags) > 0 {
+		if err := s.tagService.AuthorizeTags(ctx, request.UserID, nil, request.Tags); err != nil {
+			return nil, err
+		}
+	}
+	embedding := config.Embedding{Model: request.EmbeddingModel, Dimensions: request.EmbeddingDimensions}
+	if err := validateEmbedding(embedding); err != nil {
+		return nil, err
+	}
+
 	// Generate a unique project ID
 	projectID := generateProjectID()
 
@@ -40,13 +98,21 @@ func (s *DefaultProjectService) CreateProject(ctx context.Context, request model
 		UpdatedAt:   now,
 		Tags:        request.Tags,
 		Metadata:    make(map[string]string),
+		VectorStore: request.VectorStore,
 	}
+	projectRecord.SetEmbedding(embedding)
 
 	// Store in repository
 	if err := s.projectRepo.CreateProject(ctx, projectRecord); err != nil {
 		return nil, fmt.Errorf("failed to create project: %w", err)
 	}
 
+	if request.UserID != "" {
+		if err := s.roleService.AssignProjectOwner(ctx, projectID, request.UserID); err != nil {
+			return nil, fmt.Errorf("failed to make the creator the project owner: %w", err)
+		}
+	}
+
 	return &models.CreateProjectResponse{
 		ProjectID: projectID,
 		CreatedAt: now.Format(time.RFC3339),
@@ -61,7 +127,7 @@ func (s *DefaultProjectService) GetProject(ctx context.Context, projectID string
 	}
 
 	if projectRecord == nil {
-		return nil, fmt.Errorf("project not found")
+		return nil, apperrors.NotFound(apperrors.CodeProjectNotFound, "project not found")
 	}
 
 	return projectRecord.ToGetProjectResponse(), nil
@@ -76,7 +142,7 @@ func (s *DefaultProjectService) UpdateProject(ctx context.Context, request model
 	}
 
 	if projectRecord == nil {
-		return nil, fmt.Errorf("project not found")
+		return nil, apperrors.NotFound(apperrors.CodeProjectNotFound, "project not found")
 	}
 
 	// Update fields if provided
@@ -90,24 +156,86 @@ func (s *DefaultProjectService) UpdateProject(ctx context.Context, request model
 		projectRecord.Language = request.Language
 	}
 	if request.Tags != nil {
+		if err := s.tagService.AuthorizeTags(ctx, request.UserID, projectRecord.Tags, request.Tags); err != nil {
+			return nil, err
+		}
 		projectRecord.Tags = request.Tags
 	}
 	if request.Metadata != nil {
 		projectRecord.Metadata = request.Metadata
 	}
+	if request.VectorStore != nil {
+		projectRecord.VectorStore = *request.VectorStore
+	}
+	embeddingChanged, err := applyEmbedding(projectRecord, request)
+	if err != nil {
+		return nil, err
+	}
 
 	// Update timestamp
 	projectRecord.UpdatedAt = time.Now().UTC()
 
+	// Only apply the update on top of the version the client read
+	if request.ExpectedVersion != 0 {
+		projectRecord.Version = request.ExpectedVersion
+	}
+
 	// Store in repository
 	if err := s.projectRepo.UpdateProject(ctx, projectRecord); err != nil {
 		return nil, fmt.Errorf("failed to update project: %w", err)
 	}
 
-	return &models.UpdateProjectResponse{
+	response := &models.UpdateProjectResponse{
 		ProjectID: request.ProjectID,
 		UpdatedAt: projectRecord.UpdatedAt.Format(time.RFC3339),
-	}, nil
+		Version:   projectRecord.Version,
+	}
+	if embeddingChanged {
+		response.ReembeddedAgentIDs, err = s.reembedAgents(ctx, projectRecord)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return response, nil
+}
+
+// applyEmbedding applies the embedding settings of an update to a project, reporting whether they changed. Switching
+// the model without giving dimensions switches to the model's default dimensions.
+func applyEmbedding(projectRecord *repository.ProjectRecord, request models.UpdateProjectRequest) (bool, error) {
+	if request.EmbeddingModel == nil && request.EmbeddingDimensions == nil {
+		return false, nil
+	}
+
+	embedding := projectRecord.Embedding()
+	if request.EmbeddingModel != nil {
+		embedding = config.Embedding{Model: *request.EmbeddingModel}
+	}
+	if request.EmbeddingDimensions != nil {
+		embedding.Dimensions = *request.EmbeddingDimensions
+	}
+	if err := validateEmbedding(embedding); err != nil {
+		return false, err
+	}
+
+	changed := !sameEmbedding(embedding, projectRecord.Embedding())
+	projectRecord.SetEmbedding(embedding)
+	return changed, nil
+}
+
+// validateEmbedding checks the embedding model of a project's knowledge bases produces vectors of its dimensions
+func validateEmbedding(embedding config.Embedding) error {
+	if _, err := config.ResolveEmbedding(embedding); err != nil {
+		return apperrors.Wrap(apperrors.ErrValidation, apperrors.CodeInvalidEmbedding, err, "invalid embedding")
+	}
+	return nil
+}
+
+// sameEmbedding reports whether two embeddings produce the same vectors once their defaults are filled in
+func sameEmbedding(a, b config.Embedding) bool {
+	resolvedA, errA := config.ResolveEmbedding(a)
+	resolvedB, errB := config.ResolveEmbedding(b)
+	return errA == nil && errB == nil && resolvedA == resolvedB
 }
 
 // DeleteProject deletes a project by ID
@@ -119,7 +247,7 @@ func (s *DefaultProjectService) DeleteProject(ctx context.Context, projectID str
 	}
 
 	if !exists {
-		return nil, fmt.Errorf("project not found")
+		return nil, apperrors.NotFound(apperrors.CodeProjectNotFound, "project not found")
 	}
 
 	// Delete project
@@ -135,9 +263,10 @@ func (s *DefaultProjectService) DeleteProject(ctx context.Context, projectID str
 // ListProjects lists projects with pagination and filtering
 func (s *DefaultProjectService) ListProjects(ctx context.Context, request models.ListProjectsRequest) (*models.ListProjectsResponse, error) {
 	opts := repository.ListProjectsOptions{
-		NextToken:  request.NextToken,
-		MaxResults: request.MaxResults,
-		TagFilter:  request.TagFilter,
+		NextToken:       request.NextToken,
+		MaxResults:      request.MaxResults,
+		TagFilter:       request.TagFilter,
+		IncludeArchived: request.IncludeArchived,
 	}
 
 	projectRecords, nextToken, err := s.projectRepo.ListProjects(ctx, opts)
@@ -162,6 +291,133 @@ func (s *DefaultProjectService) ListProjects(ctx context.Context, request models
 	return response, nil
 }
 
+// ArchiveProject archives a project, so it rejects new tasks and agent syncs. Purging its artifacts deletes the
+// project's agents along with their knowledge bases, vector stores and S3 data. Archiving an archived project only
+// purges its artifacts when asked to, so a purge that failed part way can be retried.
+func (s *DefaultProjectService) ArchiveProject(ctx context.Context, request models.ArchiveProjectRequest) (*models.ProjectLifecycleResponse, error) {
+	projectRecord, err := setProjectStatus(ctx, s.projectRepo, request.ProjectID, models.ProjectStatusArchived)
+	if err != nil {
+		return nil, err
+	}
+
+	response := newProjectLifecycleResponse(projectRecord)
+	if request.PurgeArtifacts {
+		response.PurgedAgentIDs, err = s.purgeAgents(ctx, request.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return response, nil
+}
+
+// UnarchiveProject makes an archived project active again. Agents purged on archive aren't recreated.
+func (s *DefaultProjectService) UnarchiveProject(ctx context.Context, request models.UnarchiveProjectRequest) (*models.ProjectLifecycleResponse, error) {
+	projectRecord, err := setProjectStatus(ctx, s.projectRepo, request.ProjectID, models.ProjectStatusActive)
+	if err != nil {
+		return nil, err
+	}
+
+	return newProjectLifecycleResponse(projectRecord), nil
+}
+
+// purgeAgents deletes the agents of a project with their AI resources, returning the IDs of the deleted agents. Every
+// agent is attempted even when deleting one fails.
+func (s *DefaultProjectService) purgeAgents(ctx context.Context, projectID string) ([]string, error) {
+	agents, err := s.agentRepo.ListAgents(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agents: %w", err)
+	}
+
+	purged := []string{}
+	var errs []error
+	for _, agent := range agents {
+		if agent.ProjectID != projectID {
+			continue
+		}
+		if _, err := s.agentService.DeleteAgent(ctx, agent.AgentID); err != nil {
+			errs = append(errs, fmt.Errorf("failed to purge agent %s: %w", agent.AgentID, err))
+			continue
+		}
+		purged = append(purged, agent.AgentID)
+	}
+
+	return purged, errors.Join(errs...)
+}
+
+// reembedAgents rebuilds the Bedrock agents of a project embedding with another model or dimensions than the
+// project's, returning the IDs of the re-embedded agents. Every agent is attempted even when re-embedding one fails.
+func (s *DefaultProjectService) reembedAgents(ctx context.Context, projectRecord *repository.ProjectRecord) ([]string, error) {
+	agents, err := s.agentRepo.ListAgents(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agents: %w", err)
+	}
+
+	embedding := projectRecord.Embedding()
+	reembedded := []string{}
+	var errs []error
+	for _, agent := range agents {
+		if agent.ProjectID != projectRecord.ProjectID || agent.GetAIProvider() != models.AIProviderBedrock {
+			continue
+		}
+		if sameEmbedding(agent.Embedding(), embedding) {
+			continue
+		}
+		if _, err := s.agentService.ReembedAgent(ctx, agent.AgentID, embedding); err != nil {
+			errs = append(errs, fmt.Errorf("failed to re-embed agent %s: %w", agent.AgentID, err))
+			continue
+		}
+		reembedded = append(reembedded, agent.AgentID)
+	}
+
+	return reembedded, errors.Join(errs...)
+}
+
+// setProjectStatus moves a project to the lifecycle status, leaving it unchanged if it already has the status
+func setProjectStatus(ctx context.Context, projectRepo repository.ProjectRepository, projectID string, status models.ProjectStatus) (*repository.ProjectRecord, error) {
+	projectRecord, err := projectRepo.GetProject(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+	if projectRecord == nil {
+		return nil, apperrors.NotFound(apperrors.CodeProjectNotFound, "project not found")
+	}
+	if projectRecord.Status == string(status) {
+		return projectRecord, nil
+	}
+
+	projectRecord.Status = string(status)
+	projectRecord.UpdatedAt = time.Now().UTC()
+	if err := projectRepo.UpdateProject(ctx, projectRecord); err != nil {
+		return nil, fmt.Errorf("failed to update project status: %w", err)
+	}
+
+	return projectRecord, nil
+}
+
+// ensureProjectNotArchived returns a conflict error if the project is archived. Missing projects are left to the
+// caller's own checks.
+func ensureProjectNotArchived(ctx context.Context, projectRepo repository.ProjectRepository, projectID string) error {
+	projectRecord, err := projectRepo.GetProject(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to get project: %w", err)
+	}
+	if projectRecord != nil && projectRecord.Archived() {
+		return apperrors.Conflict(apperrors.CodeProjectArchived, "project %s is archived", projectID)
+	}
+	return nil
+}
+
+// newProjectLifecycleResponse reports the lifecycle status of a project
+func newProjectLifecycleResponse(projectRecord *repository.ProjectRecord) *models.ProjectLifecycleResponse {
+	return &models.ProjectLifecycleResponse{
+		ProjectID: projectRecord.ProjectID,
+		Status:    models.ProjectStatus(projectRecord.Status),
+		UpdatedAt: projectRecord.UpdatedAt.UTC().Format(time.RFC3339),
+		Version:   projectRecord.Version,
+	}
+}
+
 // generateProjectID generates a unique project ID
 func generateProjectID() string {
 	// Generate UUID and format as AWS-style resource identifier
@@ -10,9 +10,12 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
 	"github.com/kazemisoroush/code-refactoring-tool/api/models"
 	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
 	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+	servicesMocks "github.com/kazemisoroush/code-refactoring-tool/api/services/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
 )
 
 func TestNewDefaultProjectService(t *testing.T) {
@@ -20,7 +23,7 @@ func TestNewDefaultProjectService(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := repositoryMocks.NewMockProjectRepository(ctrl)
-	service := NewDefaultProjectService(mockRepo)
+	service := NewDefaultProjectService(mockRepo, nil, nil, servicesMocks.NewMockTagService(ctrl), nil, nil)
 
 	assert.NotNil(t, service)
 	assert.Equal(t, mockRepo, service.projectRepo)
@@ -31,7 +34,9 @@ func TestDefaultProjectService_CreateProject_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := repositoryMocks.NewMockProjectRepository(ctrl)
-	service := NewDefaultProjectService(mockRepo)
+	tagService := servicesMocks.NewMockTagService(ctrl)
+	roleService := servicesMocks.NewMockRoleService(ctrl)
+	service := NewDefaultProjectService(mockRepo, nil, nil, tagService, roleService, nil)
 
 	description := "Test project description"
 	language := "go"
@@ -43,8 +48,11 @@ func TestDefaultProjectService_CreateProject_Success(t *testing.T) {
 			"env":  "test",
 			"team": "backend",
 		},
+		UserID: "user-1",
 	}
 
+	tagService.EXPECT().AuthorizeTags(gomock.Any(), "user-1", nil, request.Tags).Return(nil)
+
 	// Mock the repository call
 	mockRepo.EXPECT().
 		CreateProject(gomock.Any(), gomock.Any()).
@@ -60,6 +68,7 @@ func TestDefaultProjectService_CreateProject_Success(t *testing.T) {
 			return nil
 		}).
 		Times(1)
+	roleService.EXPECT().AssignProjectOwner(gomock.Any(), gomock.Any(), "user-1").Return(nil)
 
 	response, err := service.CreateProject(context.Background(), request)
 
@@ -75,7 +84,7 @@ func TestDefaultProjectService_CreateProject_RepositoryError(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := repositoryMocks.NewMockProjectRepository(ctrl)
-	service := NewDefaultProjectService(mockRepo)
+	service := NewDefaultProjectService(mockRepo, nil, nil, servicesMocks.NewMockTagService(ctrl), nil, nil)
 
 	request := models.CreateProjectRequest{
 		Name: "test-project",
@@ -94,12 +103,90 @@ func TestDefaultProjectService_CreateProject_RepositoryError(t *testing.T) {
 	assert.Contains(t, err.Error(), "failed to create project")
 }
 
+func TestDefaultProjectService_CreateProject_ClientTokenReplay(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repositoryMocks.NewMockProjectRepository(ctrl)
+	tagService := servicesMocks.NewMockTagService(ctrl)
+	roleService := servicesMocks.NewMockRoleService(ctrl)
+	clientTokens := repositoryMocks.NewMockClientTokenRepository(ctrl)
+	service := NewDefaultProjectService(mockRepo, nil, nil, tagService, roleService, clientTokens)
+
+	request := models.CreateProjectRequest{Name: "test-project", UserID: "user-1", ClientToken: "token-1"}
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	// The first request recorded the token, so the replay returns its project without creating another
+	var claimed *models.ClientToken
+	clientTokens.EXPECT().
+		ClaimToken(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, token *models.ClientToken) (*models.ClientToken, error) {
+			assert.Equal(t, "project:user-1", token.Scope)
+			claimed = token
+			return nil, nil
+		})
+	mockRepo.EXPECT().CreateProject(gomock.Any(), gomock.Any()).Return(nil)
+	roleService.EXPECT().AssignProjectOwner(gomock.Any(), gomock.Any(), "user-1").Return(nil)
+	clientTokens.EXPECT().CompleteToken(gomock.Any(), "project:user-1", "token-1", gomock.Any()).Return(nil)
+
+	first, err := service.CreateProject(context.Background(), request)
+	require.NoError(t, err)
+
+	resourceID := first.ProjectID
+	clientTokens.EXPECT().
+		ClaimToken(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, token *models.ClientToken) (*models.ClientToken, error) {
+			assert.Equal(t, claimed.RequestHash, token.RequestHash)
+			return &models.ClientToken{RequestHash: claimed.RequestHash, ResourceID: &resourceID, CreatedAt: claimed.CreatedAt}, nil
+		})
+	mockRepo.EXPECT().GetProject(gomock.Any(), resourceID).Return(&repository.ProjectRecord{ProjectID: resourceID, CreatedAt: createdAt}, nil)
+
+	replay, err := service.CreateProject(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, first.ProjectID, replay.ProjectID)
+	assert.Equal(t, createdAt.Format(time.RFC3339), replay.CreatedAt)
+}
+
+func TestDefaultProjectService_CreateProject_ClientTokenConflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	clientTokens := repositoryMocks.NewMockClientTokenRepository(ctrl)
+	service := NewDefaultProjectService(repositoryMocks.NewMockProjectRepository(ctrl), nil, nil, servicesMocks.NewMockTagService(ctrl), nil, clientTokens)
+
+	tests := []struct {
+		name     string
+		existing *models.ClientToken
+	}{
+		{"different request", &models.ClientToken{RequestHash: "other", CreatedAt: time.Now().UTC().Add(-time.Hour)}},
+		{"in progress", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientTokens.EXPECT().
+				ClaimToken(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, token *models.ClientToken) (*models.ClientToken, error) {
+					if tt.existing != nil {
+						return tt.existing, nil
+					}
+					return &models.ClientToken{RequestHash: token.RequestHash, CreatedAt: token.CreatedAt}, nil
+				})
+
+			_, err := service.CreateProject(context.Background(), models.CreateProjectRequest{Name: "test-project", UserID: "user-1", ClientToken: "token-1"})
+
+			assert.ErrorIs(t, err, apperrors.ErrConflict)
+			assert.Equal(t, apperrors.CodeClientTokenConflict, apperrors.CodeOf(err))
+		})
+	}
+}
+
 func TestDefaultProjectService_GetProject_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRepo := repositoryMocks.NewMockProjectRepository(ctrl)
-	service := NewDefaultProjectService(mockRepo)
+	service := NewDefaultProjectService(mockRepo, nil, nil, servicesMocks.NewMockTagService(ctrl), nil, nil)
 
 	projectID := "proj-12345-abcde"
 	description := "Test project"
@@ -142,7 +229,7 @@ func TestDefaultProjectService_GetProject_NotFound(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := repositoryMocks.NewMockProjectRepository(ctrl)
-	service := NewDefaultProjectService(mockRepo)
+	service := NewDefaultProjectService(mockRepo, nil, nil, servicesMocks.NewMockTagService(ctrl), nil, nil)
 
 	projectID := "nonexistent-project"
 
@@ -163,12 +250,14 @@ func TestDefaultProjectService_UpdateProject_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := repositoryMocks.NewMockProjectRepository(ctrl)
-	service := NewDefaultProjectService(mockRepo)
+	tagService := servicesMocks.NewMockTagService(ctrl)
+	service := NewDefaultProjectService(mockRepo, nil, nil, tagService, nil, nil)
 
 	projectID := "proj-12345-abcde"
 	originalName := "original-project"
 	updatedName := "updated-project"
 	description := "Updated description"
+	vectorStore := "qdrant"
 	now := time.Now().UTC()
 
 	// Existing project record
@@ -179,6 +268,7 @@ func TestDefaultProjectService_UpdateProject_Success(t *testing.T) {
 		Status:      string(models.ProjectStatusActive),
 		CreatedAt:   now,
 		UpdatedAt:   now,
+		Version:     2,
 		Tags:        make(map[string]string),
 		Metadata:    make(map[string]string),
 	}
@@ -190,6 +280,8 @@ func TestDefaultProjectService_UpdateProject_Success(t *testing.T) {
 		Tags: map[string]string{
 			"env": "staging",
 		},
+		VectorStore:     &vectorStore,
+		ExpectedVersion: 2,
 	}
 
 	// Mock getting the existing project
@@ -198,6 +290,9 @@ func TestDefaultProjectService_UpdateProject_Success(t *testing.T) {
 		Return(existingRecord, nil).
 		Times(1)
 
+	// The new tags are checked against the ones the project held
+	tagService.EXPECT().AuthorizeTags(gomock.Any(), "", map[string]string{}, request.Tags).Return(nil)
+
 	// Mock updating the project
 	mockRepo.EXPECT().
 		UpdateProject(gomock.Any(), gomock.Any()).
@@ -207,7 +302,10 @@ func TestDefaultProjectService_UpdateProject_Success(t *testing.T) {
 			assert.Equal(t, updatedName, record.Name)
 			assert.Equal(t, &description, record.Description)
 			assert.Equal(t, request.Tags, record.Tags)
+			assert.Equal(t, vectorStore, record.VectorStore)
 			assert.True(t, record.UpdatedAt.After(now))
+			assert.Equal(t, int64(2), record.Version)
+			record.Version++
 			return nil
 		}).
 		Times(1)
@@ -218,6 +316,28 @@ func TestDefaultProjectService_UpdateProject_Success(t *testing.T) {
 	require.NotNil(t, response)
 	assert.Equal(t, projectID, response.ProjectID)
 	assert.NotEmpty(t, response.UpdatedAt)
+	assert.Equal(t, int64(3), response.Version)
+}
+
+func TestDefaultProjectService_UpdateProject_RestrictedTag(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repositoryMocks.NewMockProjectRepository(ctrl)
+	tagService := servicesMocks.NewMockTagService(ctrl)
+	service := NewDefaultProjectService(mockRepo, nil, nil, tagService, nil, nil)
+
+	current := map[string]string{"cost-center": "cc-100"}
+	updated := map[string]string{"cost-center": "cc-200"}
+	mockRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{ProjectID: "proj-1", Tags: current}, nil)
+	tagService.EXPECT().
+		AuthorizeTags(gomock.Any(), "user-1", current, updated).
+		Return(apperrors.Forbidden(apperrors.CodeTagRestricted, "tag cost-center can only be modified by [owner admin]"))
+
+	// The project isn't updated
+	_, err := service.UpdateProject(context.Background(), models.UpdateProjectRequest{ProjectID: "proj-1", Tags: updated, UserID: "user-1"})
+
+	assert.ErrorIs(t, err, apperrors.ErrForbidden)
 }
 
 func TestDefaultProjectService_UpdateProject_NotFound(t *testing.T) {
@@ -225,7 +345,7 @@ func TestDefaultProjectService_UpdateProject_NotFound(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := repositoryMocks.NewMockProjectRepository(ctrl)
-	service := NewDefaultProjectService(mockRepo)
+	service := NewDefaultProjectService(mockRepo, nil, nil, servicesMocks.NewMockTagService(ctrl), nil, nil)
 
 	projectID := "nonexistent-project"
 	updatedName := "updated-project"
@@ -247,12 +367,60 @@ func TestDefaultProjectService_UpdateProject_NotFound(t *testing.T) {
 	assert.Contains(t, err.Error(), "project not found")
 }
 
+func TestDefaultProjectService_UpdateProject_ReembedsAgents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	projectRepo := repositoryMocks.NewMockProjectRepository(ctrl)
+	agentRepo := repositoryMocks.NewMockAgentRepository(ctrl)
+	agentService := servicesMocks.NewMockAgentService(ctrl)
+	service := NewDefaultProjectService(projectRepo, agentRepo, agentService, nil, nil, nil)
+
+	model := "amazon.titan-embed-text-v2:0"
+	embedding := config.Embedding{Model: model}
+
+	projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{ProjectID: "proj-1", Version: 1}, nil)
+	projectRepo.EXPECT().UpdateProject(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, record *repository.ProjectRecord) error {
+		assert.Equal(t, embedding, record.Embedding(), "switching the model without dimensions resets them to its default")
+		return nil
+	})
+	agentRepo.EXPECT().ListAgents(gomock.Any()).Return([]*repository.AgentRecord{
+		{AgentID: "agent-1", ProjectID: "proj-1", AIProvider: string(models.AIProviderBedrock)},
+		{AgentID: "agent-2", ProjectID: "proj-1", AIProvider: string(models.AIProviderBedrock), EmbeddingModel: model, EmbeddingDimensions: 1024},
+		{AgentID: "agent-3", ProjectID: "proj-1", AIProvider: string(models.AIProviderLocal)},
+		{AgentID: "agent-4", ProjectID: "proj-2", AIProvider: string(models.AIProviderBedrock)},
+	}, nil)
+	// Only the project's Bedrock agents embedding with another model are rebuilt
+	agentService.EXPECT().ReembedAgent(gomock.Any(), "agent-1", embedding).Return(&models.UpdateAgentResponse{AgentID: "agent-1"}, nil)
+
+	response, err := service.UpdateProject(context.Background(), models.UpdateProjectRequest{ProjectID: "proj-1", EmbeddingModel: &model})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"agent-1"}, response.ReembeddedAgentIDs)
+}
+
+func TestDefaultProjectService_UpdateProject_InvalidEmbedding(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	projectRepo := repositoryMocks.NewMockProjectRepository(ctrl)
+	service := NewDefaultProjectService(projectRepo, nil, nil, nil, nil, nil)
+
+	dimensions := int32(768)
+	projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{ProjectID: "proj-1", Version: 1}, nil)
+
+	_, err := service.UpdateProject(context.Background(), models.UpdateProjectRequest{ProjectID: "proj-1", EmbeddingDimensions: &dimensions})
+
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	assert.Equal(t, apperrors.CodeInvalidEmbedding, apperrors.CodeOf(err))
+}
+
 func TestDefaultProjectService_DeleteProject_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRepo := repositoryMocks.NewMockProjectRepository(ctrl)
-	service := NewDefaultProjectService(mockRepo)
+	service := NewDefaultProjectService(mockRepo, nil, nil, servicesMocks.NewMockTagService(ctrl), nil, nil)
 
 	projectID := "proj-12345-abcde"
 
@@ -280,7 +448,7 @@ func TestDefaultProjectService_DeleteProject_NotFound(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := repositoryMocks.NewMockProjectRepository(ctrl)
-	service := NewDefaultProjectService(mockRepo)
+	service := NewDefaultProjectService(mockRepo, nil, nil, servicesMocks.NewMockTagService(ctrl), nil, nil)
 
 	projectID := "nonexistent-project"
 
@@ -301,7 +469,7 @@ func TestDefaultProjectService_ListProjects_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := repositoryMocks.NewMockProjectRepository(ctrl)
-	service := NewDefaultProjectService(mockRepo)
+	service := NewDefaultProjectService(mockRepo, nil, nil, servicesMocks.NewMockTagService(ctrl), nil, nil)
 
 	maxResults := 10
 	nextToken := "next-token"
@@ -364,7 +532,7 @@ func TestDefaultProjectService_ListProjects_Empty(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := repositoryMocks.NewMockProjectRepository(ctrl)
-	service := NewDefaultProjectService(mockRepo)
+	service := NewDefaultProjectService(mockRepo, nil, nil, servicesMocks.NewMockTagService(ctrl), nil, nil)
 
 	request := models.ListProjectsRequest{}
 
@@ -380,3 +548,51 @@ func TestDefaultProjectService_ListProjects_Empty(t *testing.T) {
 	assert.Len(t, response.Projects, 0)
 	assert.Nil(t, response.NextToken)
 }
+
+func TestDefaultProjectService_ArchiveProject_PurgesArtifacts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	projectRepo := repositoryMocks.NewMockProjectRepository(ctrl)
+	agentRepo := repositoryMocks.NewMockAgentRepository(ctrl)
+	agentService := servicesMocks.NewMockAgentService(ctrl)
+	service := NewDefaultProjectService(projectRepo, agentRepo, agentService, nil, nil, nil)
+
+	projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{ProjectID: "proj-1", Status: string(models.ProjectStatusActive), Version: 3}, nil)
+	projectRepo.EXPECT().UpdateProject(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, record *repository.ProjectRecord) error {
+		assert.Equal(t, string(models.ProjectStatusArchived), record.Status)
+		assert.Equal(t, int64(3), record.Version)
+		record.Version = 4
+		return nil
+	})
+	agentRepo.EXPECT().ListAgents(gomock.Any()).Return([]*repository.AgentRecord{
+		{AgentID: "agent-1", ProjectID: "proj-1"},
+		{AgentID: "agent-2", ProjectID: "proj-2"},
+		{AgentID: "agent-3", ProjectID: "proj-1"},
+	}, nil)
+	// Every agent of the project is attempted even when one fails
+	agentService.EXPECT().DeleteAgent(gomock.Any(), "agent-1").Return(nil, errors.New("throttled"))
+	agentService.EXPECT().DeleteAgent(gomock.Any(), "agent-3").Return(&models.DeleteAgentResponse{AgentID: "agent-3", Success: true}, nil)
+
+	_, err := service.ArchiveProject(context.Background(), models.ArchiveProjectRequest{ProjectID: "proj-1", PurgeArtifacts: true})
+
+	assert.ErrorContains(t, err, "agent-1")
+	assert.ErrorContains(t, err, "throttled")
+}
+
+func TestDefaultProjectService_UnarchiveProject_AlreadyActive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	projectRepo := repositoryMocks.NewMockProjectRepository(ctrl)
+	service := NewDefaultProjectService(projectRepo, nil, nil, nil, nil, nil)
+
+	// An active project is left unchanged
+	projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{ProjectID: "proj-1", Status: string(models.ProjectStatusActive), Version: 3}, nil)
+
+	response, err := service.UnarchiveProject(context.Background(), models.UnarchiveProjectRequest{ProjectID: "proj-1"})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.ProjectStatusActive, response.Status)
+	assert.Equal(t, int64(3), response.Version)
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+)
+
+// recentFailuresLimit is the number of failed tasks included in a project summary
+const recentFailuresLimit = 5
+
+// DefaultProjectSummaryService is the default implementation of ProjectSummaryService
+type DefaultProjectSummaryService struct {
+	projectRepo  repository.ProjectRepository
+	codebaseRepo repository.CodebaseRepository
+	taskRepo     repository.TaskRepository
+	now          func() time.Time
+}
+
+// NewDefaultProjectSummaryService creates a new DefaultProjectSummaryService
+func NewDefaultProjectSummaryService(
+	projectRepo repository.ProjectRepository,
+	codebaseRepo repository.CodebaseRepository,
+	taskRepo repository.TaskRepository,
+) *DefaultProjectSummaryService {
+	return &DefaultProjectSummaryService{
+		projectRepo:  projectRepo,
+		codebaseRepo: codebaseRepo,
+		taskRepo:     taskRepo,
+		now:          time.Now,
+	}
+}
+
+// GetProjectSummary aggregates the project's statistics with grouped queries instead of listing every resource
+func (s *DefaultProjectSummaryService) GetProjectSummary(ctx context.Context, request models.GetProjectSummaryRequest) (*models.GetProjectSummaryResponse, error) {
+	exists, err := s.projectRepo.ProjectExists(ctx, request.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check project existence: %w", err)
+	}
+	if !exists {
+		return nil, apperrors.NotFound(apperrors.CodeProjectNotFound, "project not found")
+	}
+
+	windowDays := request.WindowDays
+	if windowDays == 0 {
+		windowDays = models.DefaultSummaryWindowDays
+	}
+	windowEnd := s.now().UTC()
+	windowStart := windowEnd.AddDate(0, 0, -windowDays)
+
+	taskCounts, err := s.taskRepo.CountByProject(ctx, request.ProjectID, windowStart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tasks: %w", err)
+	}
+
+	codebaseCounts, err := s.codebaseRepo.CountByProject(ctx, request.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count codebases: %w", err)
+	}
+
+	failures, err := s.taskRepo.ListRecentFailures(ctx, request.ProjectID, windowStart, recentFailuresLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recent failures: %w", err)
+	}
+
+	response := &models.GetProjectSummaryResponse{
+		ProjectID:      request.ProjectID,
+		WindowStart:    windowStart.Format(time.RFC3339),
+		WindowEnd:      windowEnd.Format(time.RFC3339),
+		Tasks:          summarizeTasks(taskCounts),
+		RecentFailures: make([]models.TaskFailure, 0, len(failures)),
+	}
+
+	var lastSyncAt *time.Time
+	response.Codebases, lastSyncAt = summarizeCodebases(codebaseCounts)
+	if lastSyncAt != nil {
+		formatted := lastSyncAt.UTC().Format(time.RFC3339)
+		response.LastAgentSyncAt = &formatted
+	}
+
+	for _, task := range failures {
+		response.RecentFailures = append(response.RecentFailures, models.TaskFailure{
+			TaskID:       task.TaskID,
+			AgentID:      task.AgentID,
+			Type:         task.Type,
+			Title:        task.Title,
+			ErrorMessage: task.ErrorMessage,
+			FailedAt:     task.UpdatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+
+	return response, nil
+}
+
+// summarizeTasks folds grouped task counts into per-status and per-type totals
+func summarizeTasks(counts []repository.TaskCount) models.TaskStatistics {
+	stats := models.TaskStatistics{
+		ByStatus: make(map[string]int),
+		ByType:   make(map[string]int),
+	}
+
+	for _, count := range counts {
+		stats.Total += count.Count
+		stats.ByStatus[string(count.Status)] += count.Count
+		stats.ByType[string(count.Type)] += count.Count
+	}
+
+	return stats
+}
+
+// summarizeCodebases folds grouped codebase counts into a health report and returns the latest sync time
+func summarizeCodebases(counts []repository.CodebaseStatusCount) (models.CodebaseHealth, *time.Time) {
+	health := models.CodebaseHealth{
+		ByStatus: make(map[string]int),
+		Healthy:  true,
+	}
+
+	var lastSyncAt *time.Time
+	for _, count := range counts {
+		health.Total += count.Count
+		health.ByStatus[string(count.Status)] += count.Count
+
+		if count.Status != models.CodebaseStatusActive && count.Status != models.CodebaseStatusSyncing {
+			health.Healthy = false
+		}
+		if count.LastSyncAt != nil && (lastSyncAt == nil || count.LastSyncAt.After(*lastSyncAt)) {
+			lastSyncAt = count.LastSyncAt
+		}
+	}
+
+	return health, lastSyncAt
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+)
+
+func TestDefaultProjectSummaryService_GetProjectSummary(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	projectRepo := repositoryMocks.NewMockProjectRepository(ctrl)
+	codebaseRepo := repositoryMocks.NewMockCodebaseRepository(ctrl)
+	taskRepo := repositoryMocks.NewMockTaskRepository(ctrl)
+	service := NewDefaultProjectSummaryService(projectRepo, codebaseRepo, taskRepo)
+
+	now := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	windowStart := now.AddDate(0, 0, -7)
+	lastSync := now.Add(-time.Hour)
+	olderSync := now.Add(-48 * time.Hour)
+	errorMessage := "agent timed out"
+
+	projectRepo.EXPECT().ProjectExists(gomock.Any(), "proj-1").Return(true, nil)
+	taskRepo.EXPECT().CountByProject(gomock.Any(), "proj-1", windowStart).Return([]repository.TaskCount{
+		{Status: models.TaskStatusCompleted, Type: models.TaskTypeRefactoring, Count: 5},
+		{Status: models.TaskStatusCompleted, Type: models.TaskTypeCodeReview, Count: 2},
+		{Status: models.TaskStatusFailed, Type: models.TaskTypeRefactoring, Count: 1},
+	}, nil)
+	codebaseRepo.EXPECT().CountByProject(gomock.Any(), "proj-1").Return([]repository.CodebaseStatusCount{
+		{Status: models.CodebaseStatusActive, Count: 2, LastSyncAt: &olderSync},
+		{Status: models.CodebaseStatusSyncFailed, Count: 1, LastSyncAt: &lastSync},
+	}, nil)
+	taskRepo.EXPECT().ListRecentFailures(gomock.Any(), "proj-1", windowStart, recentFailuresLimit).Return([]models.Task{
+		{TaskID: "task-9", AgentID: "agent-1", Type: models.TaskTypeRefactoring, Title: "Refactor payments", ErrorMessage: &errorMessage, UpdatedAt: lastSync},
+	}, nil)
+
+	summary, err := service.GetProjectSummary(context.Background(), models.GetProjectSummaryRequest{ProjectID: "proj-1"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "2024-01-08T10:30:00Z", summary.WindowStart)
+	assert.Equal(t, "2024-01-15T10:30:00Z", summary.WindowEnd)
+	assert.Equal(t, 8, summary.Tasks.Total)
+	assert.Equal(t, map[string]int{"completed": 7, "failed": 1}, summary.Tasks.ByStatus)
+	assert.Equal(t, map[string]int{"refactoring": 6, "code_review": 2}, summary.Tasks.ByType)
+	assert.Equal(t, 3, summary.Codebases.Total)
+	assert.False(t, summary.Codebases.Healthy)
+	require.NotNil(t, summary.LastAgentSyncAt)
+	assert.Equal(t, "2024-01-15T09:30:00Z", *summary.LastAgentSyncAt)
+	require.Len(t, summary.RecentFailures, 1)
+	assert.Equal(t, "task-9", summary.RecentFailures[0].TaskID)
+	assert.Equal(t, &errorMessage, summary.RecentFailures[0].ErrorMessage)
+}
+
+func TestDefaultProjectSummaryService_GetProjectSummary_EmptyProject(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	projectRepo := repositoryMocks.NewMockProjectRepository(ctrl)
+	codebaseRepo := repositoryMocks.NewMockCodebaseRepository(ctrl)
+	taskRepo := repositoryMocks.NewMockTaskRepository(ctrl)
+	service := NewDefaultProjectSummaryService(projectRepo, codebaseRepo, taskRepo)
+
+	projectRepo.EXPECT().ProjectExists(gomock.Any(), "proj-1").Return(true, nil)
+	taskRepo.EXPECT().CountByProject(gomock.Any(), "proj-1", gomock.Any()).Return(nil, nil)
+	codebaseRepo.EXPECT().CountByProject(gomock.Any(), "proj-1").Return(nil, nil)
+	taskRepo.EXPECT().ListRecentFailures(gomock.Any(), "proj-1", gomock.Any(), recentFailuresLimit).Return(nil, nil)
+
+	summary, err := service.GetProjectSummary(context.Background(), models.GetProjectSummaryRequest{ProjectID: "proj-1", WindowDays: 30})
+
+	require.NoError(t, err)
+	assert.Zero(t, summary.Tasks.Total)
+	assert.Empty(t, summary.Tasks.ByStatus)
+	assert.True(t, summary.Codebases.Healthy)
+	assert.Nil(t, summary.LastAgentSyncAt)
+	assert.NotNil(t, summary.RecentFailures)
+}
+
+func TestDefaultProjectSummaryService_GetProjectSummary_ProjectNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	projectRepo := repositoryMocks.NewMockProjectRepository(ctrl)
+	service := NewDefaultProjectSummaryService(projectRepo, repositoryMocks.NewMockCodebaseRepository(ctrl), repositoryMocks.NewMockTaskRepository(ctrl))
+
+	projectRepo.EXPECT().ProjectExists(gomock.Any(), "proj-missing").Return(false, nil)
+
+	_, err := service.GetProjectSummary(context.Background(), models.GetProjectSummaryRequest{ProjectID: "proj-missing"})
+
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
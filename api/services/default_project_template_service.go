@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+)
+
+// TemplateMetadataKey is the project metadata key recording which template bootstrapped the project
+const TemplateMetadataKey = "template_id"
+
+// DefaultProjectTemplateService is the default implementation of ProjectTemplateService
+type DefaultProjectTemplateService struct {
+	templateRepo   repository.ProjectTemplateRepository
+	projectRepo    repository.ProjectRepository
+	automationRepo repository.ProjectAutomationRepository
+	tagService     TagService
+}
+
+// NewDefaultProjectTemplateService creates a new DefaultProjectTemplateService. The tag service governs the tags set
+// on bootstrapped projects, template defaults included.
+func NewDefaultProjectTemplateService(
+	templateRepo repository.ProjectTemplateRepository,
+	projectRepo repository.ProjectRepository,
+	automationRepo repository.ProjectAutomationRepository,
+	tagService TagService,
+) *DefaultProjectTemplateService {
+	return &DefaultProjectTemplateService{
+		templateRepo:   templateRepo,
+		projectRepo:    projectRepo,
+		automationRepo: automationRepo,
+		tagService:     tagService,
+	}
+}
+
+// ListTemplates lists built-in templates followed by custom templates
+func (s *DefaultProjectTemplateService) ListTemplates(ctx context.Context) (*models.ListProjectTemplatesResponse, error) {
+	custom, err := s.templateRepo.ListTemplates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list project templates: %w", err)
+	}
+
+	templates := make([]models.ProjectTemplate, 0, len(custom)+2)
+	for _, template := range builtinProjectTemplates() {
+		templates = append(templates, *template)
+	}
+	for _, template := range custom {
+		templates = append(templates, *template)
+	}
+
+	return &models.ListProjectTemplatesResponse{
+		Templates: templates,
+	}, nil
+}
+
+// GetTemplate retrieves a template, checking built-in templates first
+func (s *DefaultProjectTemplateService) GetTemplate(ctx context.Context, templateID string) (*models.ProjectTemplate, error) {
+	for _, template := range builtinProjectTemplates() {
+		if template.TemplateID == templateID {
+			return template, nil
+		}
+	}
+
+	template, err := s.templateRepo.GetTemplate(ctx, templateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project template: %w", err)
+	}
+
+	if template == nil {
+		return nil, apperrors.NotFound(apperrors.CodeProjectTemplateNotFound, "project template not found")
+	}
+
+	return template, nil
+}
+
+// CreateTemplate stores a custom project template
+func (s *DefaultProjectTemplateService) CreateTemplate(ctx context.Context, request models.CreateProjectTemplateRequest) (*models.CreateProjectTemplateResponse, error) {
+	now := time.Now().UTC()
+	template := &models.ProjectTemplate{
+		TemplateID:        fmt.Sprintf("tmpl-%s", uuid.New().String()[:13]),
+		Name:              request.Name,
+		Description:       request.Description,
+		Language:          request.Language,
+		DefaultTags:       request.DefaultTags,
+		CodebaseConfigs:   request.CodebaseConfigs,
+		PromptTemplates:   request.PromptTemplates,
+		Webhooks:          request.Webhooks,
+		ScheduledAnalyses: request.ScheduledAnalyses,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+
+	if err := s.templateRepo.CreateTemplate(ctx, template); err != nil {
+		return nil, fmt.Errorf("failed to create project template: %w", err)
+	}
+
+	return &models.CreateProjectTemplateResponse{
+		TemplateID: template.TemplateID,
+		CreatedAt:  now.Format(time.RFC3339),
+	}, nil
+}
+
+// CreateProjectFromTemplate creates a project with the template's tags and language, stores the
+// template's schedules and webhooks, and returns the remaining defaults so the caller can complete the setup
+func (s *DefaultProjectTemplateService) CreateProjectFromTemplate(ctx context.Context, request models.CreateProjectFromTemplateRequest) (*models.CreateProjectFromTemplateResponse, error) {
+	template, err := s.GetTemplate(ctx, request.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Request tags take precedence over template defaults
+	tags := make(map[string]string, len(template.DefaultTags)+len(request.Tags))
+	maps.Copy(tags, template.DefaultTags)
+	maps.Copy(tags, request.Tags)
+	if len(tags) > 0 {
+		if err := s.tagService.AuthorizeTags(ctx, request.UserID, nil, tags); err != nil {
+			return nil, err
+		}
+	}
+
+	description := request.Description
+	if description == nil && template.Description != "" {
+		description = &template.Description
+	}
+
+	now := time.Now().UTC()
+	projectRecord := &repository.ProjectRecord{
+		ProjectID:   generateProjectID(),
+		Name:        request.Name,
+		Description: description,
+		Language:    template.Language,
+		Status:      string(models.ProjectStatusActive),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		Tags:        tags,
+		Metadata: map[string]string{
+			TemplateMetadataKey: template.TemplateID,
+		},
+	}
+
+	if err := s.projectRepo.CreateProject(ctx, projectRecord); err != nil {
+		return nil, fmt.Errorf("failed to create project: %w", err)
+	}
+
+	if len(template.ScheduledAnalyses) > 0 || len(template.Webhooks) > 0 {
+		automation := &models.ProjectAutomation{
+			ProjectID:         projectRecord.ProjectID,
+			ScheduledAnalyses: template.ScheduledAnalyses,
+			Webhooks:          template.Webhooks,
+			UpdatedAt:         now,
+		}
+		if err := s.automationRepo.SaveAutomation(ctx, automation); err != nil {
+			return nil, fmt.Errorf("failed to save project automation: %w", err)
+		}
+	}
+
+	return &models.CreateProjectFromTemplateResponse{
+		ProjectID:         projectRecord.ProjectID,
+		TemplateID:        template.TemplateID,
+		CreatedAt:         now.Format(time.RFC3339),
+		Tags:              tags,
+		CodebaseConfigs:   template.CodebaseConfigs,
+		PromptTemplates:   template.PromptTemplates,
+		Webhooks:          template.Webhooks,
+		ScheduledAnalyses: template.ScheduledAnalyses,
+	}, nil
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+	servicesMocks "github.com/kazemisoroush/code-refactoring-tool/api/services/mocks"
+)
+
+func TestDefaultProjectTemplateService_ListTemplates_IncludesBuiltins(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	templateRepo := repositoryMocks.NewMockProjectTemplateRepository(ctrl)
+	service := NewDefaultProjectTemplateService(templateRepo, repositoryMocks.NewMockProjectRepository(ctrl), repositoryMocks.NewMockProjectAutomationRepository(ctrl), servicesMocks.NewMockTagService(ctrl))
+
+	templateRepo.EXPECT().ListTemplates(gomock.Any()).Return([]*models.ProjectTemplate{
+		{TemplateID: "tmpl-custom", Name: "Custom"},
+	}, nil)
+
+	response, err := service.ListTemplates(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, response.Templates, 3)
+	assert.Equal(t, BuiltinTemplateGoMicroservice, response.Templates[0].TemplateID)
+	assert.True(t, response.Templates[0].BuiltIn)
+	assert.Equal(t, BuiltinTemplatePythonMonorepo, response.Templates[1].TemplateID)
+	assert.Equal(t, "tmpl-custom", response.Templates[2].TemplateID)
+	assert.False(t, response.Templates[2].BuiltIn)
+}
+
+func TestDefaultProjectTemplateService_GetTemplate_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	templateRepo := repositoryMocks.NewMockProjectTemplateRepository(ctrl)
+	service := NewDefaultProjectTemplateService(templateRepo, repositoryMocks.NewMockProjectRepository(ctrl), repositoryMocks.NewMockProjectAutomationRepository(ctrl), servicesMocks.NewMockTagService(ctrl))
+
+	templateRepo.EXPECT().GetTemplate(gomock.Any(), "tmpl-missing").Return(nil, nil)
+
+	_, err := service.GetTemplate(context.Background(), "tmpl-missing")
+	assert.EqualError(t, err, "project template not found")
+}
+
+func TestDefaultProjectTemplateService_CreateProjectFromTemplate_Builtin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	templateRepo := repositoryMocks.NewMockProjectTemplateRepository(ctrl)
+	projectRepo := repositoryMocks.NewMockProjectRepository(ctrl)
+	automationRepo := repositoryMocks.NewMockProjectAutomationRepository(ctrl)
+	tagService := servicesMocks.NewMockTagService(ctrl)
+	service := NewDefaultProjectTemplateService(templateRepo, projectRepo, automationRepo, tagService)
+
+	// The template's default tags are governed like the caller's own
+	tagService.EXPECT().
+		AuthorizeTags(gomock.Any(), "user-1", nil, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _, updated map[string]string) error {
+			assert.Equal(t, "payments-team", updated["kind"])
+			assert.Equal(t, "go", updated["language"])
+			return nil
+		})
+	projectRepo.EXPECT().
+		CreateProject(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, record *repository.ProjectRecord) error {
+			assert.Equal(t, "payments", record.Name)
+			require.NotNil(t, record.Language)
+			assert.Equal(t, "go", *record.Language)
+			assert.Equal(t, "payments-team", record.Tags["kind"])
+			assert.Equal(t, "go", record.Tags["language"])
+			assert.Equal(t, BuiltinTemplateGoMicroservice, record.Metadata[TemplateMetadataKey])
+			return nil
+		})
+	automationRepo.EXPECT().
+		SaveAutomation(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, automation *models.ProjectAutomation) error {
+			assert.NotEmpty(t, automation.ProjectID)
+			assert.NotEmpty(t, automation.ScheduledAnalyses)
+			return nil
+		})
+
+	response, err := service.CreateProjectFromTemplate(context.Background(), models.CreateProjectFromTemplateRequest{
+		TemplateID: BuiltinTemplateGoMicroservice,
+		Name:       "payments",
+		Tags:       map[string]string{"kind": "payments-team"},
+		UserID:     "user-1",
+	})
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, response.ProjectID)
+	assert.Equal(t, BuiltinTemplateGoMicroservice, response.TemplateID)
+	assert.NotEmpty(t, response.PromptTemplates)
+	assert.NotEmpty(t, response.ScheduledAnalyses)
+	assert.Len(t, response.CodebaseConfigs, 1)
+}
+
+func TestDefaultProjectTemplateService_CreateTemplate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	templateRepo := repositoryMocks.NewMockProjectTemplateRepository(ctrl)
+	service := NewDefaultProjectTemplateService(templateRepo, repositoryMocks.NewMockProjectRepository(ctrl), repositoryMocks.NewMockProjectAutomationRepository(ctrl), servicesMocks.NewMockTagService(ctrl))
+
+	templateRepo.EXPECT().
+		CreateTemplate(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, template *models.ProjectTemplate) error {
+			assert.Equal(t, "tmpl-", template.TemplateID[:5])
+			assert.Equal(t, "Internal", template.Name)
+			assert.False(t, template.BuiltIn)
+			return nil
+		})
+
+	response, err := service.CreateTemplate(context.Background(), models.CreateProjectTemplateRequest{Name: "Internal"})
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, response.TemplateID)
+}
@@ -0,0 +1,235 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+)
+
+// DefaultQualityGateService is the default implementation of QualityGateService
+type DefaultQualityGateService struct {
+	policyRepo  repository.QualityGatePolicyRepository
+	projectRepo repository.ProjectRepository
+	findingRepo repository.FindingRepository
+	metricsRepo repository.CodeMetricsRepository
+	now         func() time.Time
+}
+
+// NewDefaultQualityGateService creates a new DefaultQualityGateService
+func NewDefaultQualityGateService(
+	policyRepo repository.QualityGatePolicyRepository,
+	projectRepo repository.ProjectRepository,
+	findingRepo repository.FindingRepository,
+	metricsRepo repository.CodeMetricsRepository,
+) *DefaultQualityGateService {
+	return &DefaultQualityGateService{
+		policyRepo:  policyRepo,
+		projectRepo: projectRepo,
+		findingRepo: findingRepo,
+		metricsRepo: metricsRepo,
+		now:         time.Now,
+	}
+}
+
+// GetPolicy returns the quality gate policy of a project, or the default policy if none is configured
+func (s *DefaultQualityGateService) GetPolicy(ctx context.Context, request models.GetQualityGatePolicyRequest) (*models.QualityGatePolicy, error) {
+	if err := s.ensureProjectExists(ctx, request.ProjectID); err != nil {
+		return nil, err
+	}
+
+	return s.getPolicy(ctx, request.ProjectID)
+}
+
+// UpdatePolicy validates and replaces the severity rules and gates of a project. Severity rules apply to the findings
+// of the next analyses.
+func (s *DefaultQualityGateService) UpdatePolicy(ctx context.Context, request models.UpdateQualityGatePolicyRequest) (*models.QualityGatePolicy, error) {
+	if err := s.ensureProjectExists(ctx, request.ProjectID); err != nil {
+		return nil, err
+	}
+
+	gates := make([]models.QualityGate, len(request.Gates))
+	names := make(map[string]bool, len(request.Gates))
+	for i, gate := range request.Gates {
+		if names[gate.Name] {
+			return nil, apperrors.Validation(apperrors.CodeInvalidQualityGate, "duplicate quality gate name %q", gate.Name)
+		}
+		names[gate.Name] = true
+		if gate.MinSeverity == "" && isFindingMetric(gate.Metric) {
+			gate.MinSeverity = models.FindingSeverityLow
+		}
+		if gate.MinSeverity != "" && !isFindingMetric(gate.Metric) {
+			return nil, apperrors.Validation(apperrors.CodeInvalidQualityGate, "quality gate %q measures %s, which has no severity", gate.Name, gate.Metric)
+		}
+		gates[i] = gate
+	}
+	rules := request.SeverityRules
+	if rules == nil {
+		rules = []models.SeverityRule{}
+	}
+
+	updatedAt := s.now().UTC()
+	policy := &models.QualityGatePolicy{
+		ProjectID:     request.ProjectID,
+		SeverityRules: rules,
+		Gates:         gates,
+		UpdatedAt:     &updatedAt,
+	}
+	if err := s.policyRepo.SavePolicy(ctx, policy); err != nil {
+		return nil, fmt.Errorf("failed to save quality gate policy: %w", err)
+	}
+
+	return policy, nil
+}
+
+// EvaluateGates evaluates the quality gates of an analysis task's project. Gates measuring something the analysis
+// didn't, such as new findings after a metrics analysis, are skipped.
+func (s *DefaultQualityGateService) EvaluateGates(ctx context.Context, task *models.TaskWithFullContext, sync *models.FindingSyncResult, snapshot *models.CodeMetricsSnapshot) (*models.QualityGateReport, error) {
+	policy, err := s.getPolicy(ctx, task.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if len(policy.Gates) == 0 || task.CodebaseID == nil {
+		return nil, nil
+	}
+
+	// The open findings and the previous snapshot are loaded once, by the first gate needing them
+	var openFindings []models.Finding
+	var previous *models.CodeMetricsSnapshot
+	var loadedFindings, loadedPrevious bool
+
+	report := &models.QualityGateReport{Passed: true, Gates: make([]models.QualityGateResult, 0, len(policy.Gates))}
+	for _, gate := range policy.Gates {
+		result := models.QualityGateResult{Name: gate.Name, Metric: gate.Metric, Threshold: gate.Threshold}
+		var value float64
+		var measured string
+
+		switch gate.Metric {
+		case models.QualityGateMetricNewFindings:
+			if sync == nil {
+				result.Outcome = models.QualityGateOutcomeSkipped
+				result.Message = "the analysis reported no findings"
+				break
+			}
+			count := 0
+			for severity, n := range sync.NewBySeverity {
+				if findingSeverityRank[severity] >= findingSeverityRank[gate.MinSeverity] {
+					count += n
+				}
+			}
+			value = float64(count)
+			measured = fmt.Sprintf("%d new findings of %s severity or above", count, gate.MinSeverity)
+
+		case models.QualityGateMetricOpenFindings:
+			if !loadedFindings {
+				// Findings suppressed by the codebase's baseline are legacy ones the gates don't count
+				status := models.FindingStatusOpen
+				baselined := false
+				openFindings, err = s.findingRepo.ListFindings(ctx, *task.CodebaseID, repository.FindingFilter{Status: &status, Baselined: &baselined})
+				if err != nil {
+					return nil, fmt.Errorf("failed to list open findings: %w", err)
+				}
+				loadedFindings = true
+			}
+			count := 0
+			for _, finding := range openFindings {
+				if findingSeverityRank[finding.Severity] >= findingSeverityRank[gate.MinSeverity] {
+					count++
+				}
+			}
+			value = float64(count)
+			measured = fmt.Sprintf("%d open findings of %s severity or above", count, gate.MinSeverity)
+
+		case models.QualityGateMetricComplexityIncrease, models.QualityGateMetricMaintainabilityDecrease:
+			if snapshot != nil && !loadedPrevious {
+				previous, err = s.previousSnapshot(ctx, snapshot)
+				if err != nil {
+					return nil, err
+				}
+				loadedPrevious = true
+			}
+			if snapshot == nil || previous == nil {
+				result.Outcome = models.QualityGateOutcomeSkipped
+				result.Message = "no earlier metrics snapshot to compare with"
+				break
+			}
+			if gate.Metric == models.QualityGateMetricComplexityIncrease {
+				value = roundMetric(snapshot.AverageComplexity - previous.AverageComplexity)
+				measured = fmt.Sprintf("average complexity changed by %g since commit %s", value, previous.CommitSHA)
+			} else {
+				value = roundMetric(previous.MaintainabilityScore - snapshot.MaintainabilityScore)
+				measured = fmt.Sprintf("maintainability score dropped by %g since commit %s", value, previous.CommitSHA)
+			}
+		}
+
+		if result.Outcome == "" {
+			result.Value = &value
+			result.Outcome = models.QualityGateOutcomePassed
+			if value > gate.Threshold {
+				result.Outcome = models.QualityGateOutcomeFailed
+				report.Passed = false
+			}
+			result.Message = fmt.Sprintf("%s, at most %g allowed", measured, gate.Threshold)
+		}
+		report.Gates = append(report.Gates, result)
+	}
+
+	return report, nil
+}
+
+// previousSnapshot returns the latest snapshot of the codebase measured before snapshot, nil if there is none
+func (s *DefaultQualityGateService) previousSnapshot(ctx context.Context, snapshot *models.CodeMetricsSnapshot) (*models.CodeMetricsSnapshot, error) {
+	snapshots, err := s.metricsRepo.ListSnapshots(ctx, snapshot.CodebaseID, time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list code metrics snapshots: %w", err)
+	}
+
+	var previous *models.CodeMetricsSnapshot
+	for i := range snapshots {
+		candidate := &snapshots[i]
+		if candidate.SnapshotID == snapshot.SnapshotID || candidate.CommitSHA == snapshot.CommitSHA || candidate.MeasuredAt.After(snapshot.MeasuredAt) {
+			continue
+		}
+		previous = candidate
+	}
+
+	return previous, nil
+}
+
+// getPolicy returns the stored policy of a project, or the default policy without rules or gates
+func (s *DefaultQualityGateService) getPolicy(ctx context.Context, projectID string) (*models.QualityGatePolicy, error) {
+	policy, err := s.policyRepo.GetPolicy(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quality gate policy: %w", err)
+	}
+	if policy != nil {
+		return policy, nil
+	}
+
+	return &models.QualityGatePolicy{
+		ProjectID:     projectID,
+		SeverityRules: []models.SeverityRule{},
+		Gates:         []models.QualityGate{},
+		IsDefault:     true,
+	}, nil
+}
+
+// ensureProjectExists reports a missing project as not found
+func (s *DefaultQualityGateService) ensureProjectExists(ctx context.Context, projectID string) error {
+	exists, err := s.projectRepo.ProjectExists(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to check project existence: %w", err)
+	}
+	if !exists {
+		return apperrors.NotFound(apperrors.CodeProjectNotFound, "project not found")
+	}
+	return nil
+}
+
+// isFindingMetric reports whether a gate metric counts findings, which its minimum severity selects
+func isFindingMetric(metric models.QualityGateMetric) bool {
+	return metric == models.QualityGateMetricNewFindings || metric == models.QualityGateMetricOpenFindings
+}
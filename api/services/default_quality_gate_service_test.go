@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+)
+
+func newTestQualityGateService(ctrl *gomock.Controller) (*DefaultQualityGateService, *repositoryMocks.MockQualityGatePolicyRepository, *repositoryMocks.MockProjectRepository, *repositoryMocks.MockFindingRepository, *repositoryMocks.MockCodeMetricsRepository) {
+	policyRepo := repositoryMocks.NewMockQualityGatePolicyRepository(ctrl)
+	projectRepo := repositoryMocks.NewMockProjectRepository(ctrl)
+	findingRepo := repositoryMocks.NewMockFindingRepository(ctrl)
+	metricsRepo := repositoryMocks.NewMockCodeMetricsRepository(ctrl)
+	service := NewDefaultQualityGateService(policyRepo, projectRepo, findingRepo, metricsRepo)
+	service.now = func() time.Time { return time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC) }
+	return service, policyRepo, projectRepo, findingRepo, metricsRepo
+}
+
+func newQualityGateTask() *models.TaskWithFullContext {
+	codebaseID := "cb-1"
+	return &models.TaskWithFullContext{Task: models.Task{TaskID: "task-1", ProjectID: "proj-1", CodebaseID: &codebaseID}}
+}
+
+func TestQualityGateService_GetPolicy_Default(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, policyRepo, projectRepo, _, _ := newTestQualityGateService(ctrl)
+	projectRepo.EXPECT().ProjectExists(gomock.Any(), "proj-1").Return(true, nil)
+	policyRepo.EXPECT().GetPolicy(gomock.Any(), "proj-1").Return(nil, nil)
+
+	policy, err := service.GetPolicy(context.Background(), models.GetQualityGatePolicyRequest{ProjectID: "proj-1"})
+
+	require.NoError(t, err)
+	assert.True(t, policy.IsDefault)
+	assert.Empty(t, policy.Gates)
+	assert.NotNil(t, policy.SeverityRules)
+}
+
+func TestQualityGateService_UpdatePolicy_DefaultsMinSeverity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, policyRepo, projectRepo, _, _ := newTestQualityGateService(ctrl)
+	projectRepo.EXPECT().ProjectExists(gomock.Any(), "proj-1").Return(true, nil)
+	policyRepo.EXPECT().SavePolicy(gomock.Any(), gomock.Any()).Return(nil)
+
+	policy, err := service.UpdatePolicy(context.Background(), models.UpdateQualityGatePolicyRequest{
+		ProjectID: "proj-1",
+		Gates: []models.QualityGate{
+			{Name: "No new findings", Metric: models.QualityGateMetricNewFindings},
+			{Name: "Complexity", Metric: models.QualityGateMetricComplexityIncrease},
+		},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.FindingSeverityLow, policy.Gates[0].MinSeverity)
+	assert.Empty(t, policy.Gates[1].MinSeverity)
+	assert.Equal(t, []models.SeverityRule{}, policy.SeverityRules)
+	require.NotNil(t, policy.UpdatedAt)
+}
+
+func TestQualityGateService_UpdatePolicy_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		gates []models.QualityGate
+	}{
+		{
+			name: "duplicate name",
+			gates: []models.QualityGate{
+				{Name: "Gate", Metric: models.QualityGateMetricNewFindings},
+				{Name: "Gate", Metric: models.QualityGateMetricOpenFindings},
+			},
+		},
+		{
+			name:  "severity on a metric gate",
+			gates: []models.QualityGate{{Name: "Gate", Metric: models.QualityGateMetricMaintainabilityDecrease, MinSeverity: models.FindingSeverityHigh}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, _, projectRepo, _, _ := newTestQualityGateService(ctrl)
+			projectRepo.EXPECT().ProjectExists(gomock.Any(), "proj-1").Return(true, nil)
+
+			_, err := service.UpdatePolicy(context.Background(), models.UpdateQualityGatePolicyRequest{ProjectID: "proj-1", Gates: tt.gates})
+
+			require.Error(t, err)
+			assert.Equal(t, apperrors.CodeInvalidQualityGate, apperrors.CodeOf(err))
+		})
+	}
+}
+
+func TestQualityGateService_EvaluateGates_NewCriticalFindingFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, policyRepo, _, findingRepo, _ := newTestQualityGateService(ctrl)
+	policyRepo.EXPECT().GetPolicy(gomock.Any(), "proj-1").Return(&models.QualityGatePolicy{
+		ProjectID: "proj-1",
+		Gates: []models.QualityGate{
+			{Name: "No new critical findings", Metric: models.QualityGateMetricNewFindings, MinSeverity: models.FindingSeverityCritical},
+			{Name: "Few open high findings", Metric: models.QualityGateMetricOpenFindings, MinSeverity: models.FindingSeverityHigh, Threshold: 5},
+			{Name: "Complexity must not increase", Metric: models.QualityGateMetricComplexityIncrease},
+		},
+	}, nil)
+	findingRepo.EXPECT().ListFindings(gomock.Any(), "cb-1", gomock.Any()).Return([]models.Finding{
+		{Severity: models.FindingSeverityCritical},
+		{Severity: models.FindingSeverityHigh},
+		{Severity: models.FindingSeverityLow},
+	}, nil)
+	sync := &models.FindingSyncResult{Opened: 3, NewBySeverity: map[models.FindingSeverity]int{
+		models.FindingSeverityCritical: 1,
+		models.FindingSeverityMedium:   2,
+	}}
+
+	report, err := service.EvaluateGates(context.Background(), newQualityGateTask(), sync, nil)
+
+	require.NoError(t, err)
+	assert.False(t, report.Passed)
+	require.Len(t, report.Gates, 3)
+	assert.Equal(t, models.QualityGateOutcomeFailed, report.Gates[0].Outcome)
+	assert.Equal(t, 1.0, *report.Gates[0].Value)
+	assert.Equal(t, models.QualityGateOutcomePassed, report.Gates[1].Outcome)
+	assert.Equal(t, 2.0, *report.Gates[1].Value)
+	assert.Equal(t, models.QualityGateOutcomeSkipped, report.Gates[2].Outcome)
+	assert.Nil(t, report.Gates[2].Value)
+}
+
+func TestQualityGateService_EvaluateGates_ComparesWithPreviousSnapshot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, policyRepo, _, _, metricsRepo := newTestQualityGateService(ctrl)
+	measuredAt := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	snapshot := &models.CodeMetricsSnapshot{SnapshotID: "metrics-3", CodebaseID: "cb-1", CommitSHA: "c", MeasuredAt: measuredAt, AverageComplexity: 3.6, MaintainabilityScore: 90}
+
+	policyRepo.EXPECT().GetPolicy(gomock.Any(), "proj-1").Return(&models.QualityGatePolicy{
+		ProjectID: "proj-1",
+		Gates: []models.QualityGate{
+			{Name: "Complexity must not increase", Metric: models.QualityGateMetricComplexityIncrease},
+			{Name: "Maintainability", Metric: models.QualityGateMetricMaintainabilityDecrease, Threshold: 1},
+			{Name: "No new findings", Metric: models.QualityGateMetricNewFindings, MinSeverity: models.FindingSeverityLow},
+		},
+	}, nil)
+	metricsRepo.EXPECT().ListSnapshots(gomock.Any(), "cb-1", time.Time{}).Return([]models.CodeMetricsSnapshot{
+		{SnapshotID: "metrics-1", CommitSHA: "a", MeasuredAt: measuredAt.Add(-48 * time.Hour), AverageComplexity: 3.1, MaintainabilityScore: 93},
+		{SnapshotID: "metrics-2", CommitSHA: "b", MeasuredAt: measuredAt.Add(-24 * time.Hour), AverageComplexity: 3.4, MaintainabilityScore: 90.5},
+		*snapshot,
+	}, nil)
+
+	report, err := service.EvaluateGates(context.Background(), newQualityGateTask(), nil, snapshot)
+
+	require.NoError(t, err)
+	assert.False(t, report.Passed)
+	require.Len(t, report.Gates, 3)
+	assert.Equal(t, models.QualityGateOutcomeFailed, report.Gates[0].Outcome)
+	assert.Equal(t, 0.2, *report.Gates[0].Value)
+	assert.Contains(t, report.Gates[0].Message, "since commit b")
+	assert.Equal(t, models.QualityGateOutcomePassed, report.Gates[1].Outcome)
+	assert.Equal(t, 0.5, *report.Gates[1].Value)
+	assert.Equal(t, models.QualityGateOutcomeSkipped, report.Gates[2].Outcome)
+}
+
+func TestQualityGateService_EvaluateGates_NoGates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, policyRepo, _, _, _ := newTestQualityGateService(ctrl)
+	policyRepo.EXPECT().GetPolicy(gomock.Any(), "proj-1").Return(nil, nil)
+
+	report, err := service.EvaluateGates(context.Background(), newQualityGateTask(), &models.FindingSyncResult{Opened: 1}, nil)
+
+	require.NoError(t, err)
+	assert.Nil(t, report)
+}
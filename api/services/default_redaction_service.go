@@ -0,0 +1,255 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/noise"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/redact"
+)
+
+// defaultRedactionAuditsLimit is the number of audits listed when no limit is requested
+const defaultRedactionAuditsLimit = 50
+
+// DefaultRedactionService is the default implementation of RedactionService
+type DefaultRedactionService struct {
+	policyRepo  repository.RedactionPolicyRepository
+	auditRepo   repository.RedactionAuditRepository
+	projectRepo repository.ProjectRepository
+	now         func() time.Time
+}
+
+// NewDefaultRedactionService creates a new DefaultRedactionService
+func NewDefaultRedactionService(
+	policyRepo repository.RedactionPolicyRepository,
+	auditRepo repository.RedactionAuditRepository,
+	projectRepo repository.ProjectRepository,
+) *DefaultRedactionService {
+	return &DefaultRedactionService{
+		policyRepo:  policyRepo,
+		auditRepo:   auditRepo,
+		projectRepo: projectRepo,
+		now:         time.Now,
+	}
+}
+
+// GetPolicy returns the redaction policy of a project, or the default policy if none is configured
+func (s *DefaultRedactionService) GetPolicy(ctx context.Context, request models.GetRedactionPolicyRequest) (*models.RedactionPolicy, error) {
+	if err := s.ensureProjectExists(ctx, request.ProjectID); err != nil {
+		return nil, err
+	}
+
+	return s.getPolicy(ctx, request.ProjectID)
+}
+
+// UpdatePolicy validates and replaces the redaction policy of a project. Omitted switches and an omitted noise file
+// filter keep their current value.
+func (s *DefaultRedactionService) UpdatePolicy(ctx context.Context, request models.UpdateRedactionPolicyRequest) (*models.RedactionPolicy, error) {
+	if err := s.ensureProjectExists(ctx, request.ProjectID); err != nil {
+		return nil, err
+	}
+
+	policy, err := s.getPolicy(ctx, request.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.MaskEmails != nil {
+		policy.MaskEmails = *request.MaskEmails
+	}
+	if request.MaskCredentials != nil {
+		policy.MaskCredentials = *request.MaskCredentials
+	}
+	if request.LogLLMContent != nil {
+		policy.LogLLMContent = *request.LogLLMContent
+	}
+	policy.Patterns = request.Patterns
+	if policy.Patterns == nil {
+		policy.Patterns = []models.RedactionPattern{}
+	}
+	if request.Files != nil {
+		policy.Files = normalizeNoiseFileFilter(*request.Files)
+	}
+
+	if _, err := redact.NewRedactor(toRedactPolicy(policy)); err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrValidation, apperrors.CodeInvalidRedaction, err, "invalid redaction policy")
+	}
+
+	updatedAt := s.now().UTC()
+	policy.UpdatedAt = &updatedAt
+	policy.IsDefault = false
+	if err := s.policyRepo.SavePolicy(ctx, policy); err != nil {
+		return nil, fmt.Errorf("failed to save redaction policy: %w", err)
+	}
+
+	return policy, nil
+}
+
+// ListAudits lists the redaction counts of a project's most recent syncs
+func (s *DefaultRedactionService) ListAudits(ctx context.Context, request models.ListRedactionAuditsRequest) (*models.ListRedactionAuditsResponse, error) {
+	if err := s.ensureProjectExists(ctx, request.ProjectID); err != nil {
+		return nil, err
+	}
+
+	limit := request.Limit
+	if limit == 0 {
+		limit = defaultRedactionAuditsLimit
+	}
+
+	audits, err := s.auditRepo.ListAudits(ctx, request.ProjectID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list redaction audits: %w", err)
+	}
+
+	return &models.ListRedactionAuditsResponse{Audits: audits}, nil
+}
+
+// ExportAudits streams the redaction audits of a project to fn
+func (s *DefaultRedactionService) ExportAudits(ctx context.Context, request models.ListRedactionAuditsRequest, fn func(models.RedactionAudit) error) error {
+	if err := s.ensureProjectExists(ctx, request.ProjectID); err != nil {
+		return err
+	}
+
+	if err := s.auditRepo.StreamAudits(ctx, request.ProjectID, fn); err != nil {
+		return fmt.Errorf("failed to export redaction audits: %w", err)
+	}
+
+	return nil
+}
+
+// ResolvePolicy returns the policy applied when syncing an agent of the project
+func (s *DefaultRedactionService) ResolvePolicy(ctx context.Context, projectID string) (redact.Policy, error) {
+	if projectID == "" {
+		return redact.DefaultPolicy(), nil
+	}
+
+	exists, err := s.projectRepo.ProjectExists(ctx, projectID)
+	if err != nil {
+		return redact.Policy{}, fmt.Errorf("failed to check project existence: %w", err)
+	}
+	if !exists {
+		return redact.Policy{}, apperrors.Validation(apperrors.CodeProjectNotFound, "project not found: %s", projectID)
+	}
+
+	policy, err := s.getPolicy(ctx, projectID)
+	if err != nil {
+		return redact.Policy{}, err
+	}
+
+	return toRedactPolicy(policy), nil
+}
+
+// RecordAudit stores the redaction counts of an agent sync. Audits are listed per project, so syncs of agents
+// without a project aren't recorded.
+func (s *DefaultRedactionService) RecordAudit(ctx context.Context, projectID, agentID string, operation models.RedactionOperation, report redact.Report) error {
+	if projectID == "" {
+		return nil
+	}
+
+	counts := report.Counts
+	if counts == nil {
+		counts = map[string]int{}
+	}
+
+	audit := &models.RedactionAudit{
+		AuditID:       "redaction-" + uuid.New().String(),
+		ProjectID:     projectID,
+		AgentID:       agentID,
+		Operation:     operation,
+		FilesScanned:  report.FilesScanned,
+		FilesRedacted: report.FilesRedacted,
+		Redactions:    report.Total(),
+		Counts:        counts,
+		SyncedAt:      s.now().UTC(),
+	}
+	if err := s.auditRepo.CreateAudit(ctx, audit); err != nil {
+		return fmt.Errorf("failed to create redaction audit: %w", err)
+	}
+
+	return nil
+}
+
+// getPolicy returns the stored policy of a project, or the default policy
+func (s *DefaultRedactionService) getPolicy(ctx context.Context, projectID string) (*models.RedactionPolicy, error) {
+	policy, err := s.policyRepo.GetPolicy(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get redaction policy: %w", err)
+	}
+	if policy != nil {
+		if policy.Files == nil {
+			policy.Files = defaultNoiseFileFilter()
+		}
+		return policy, nil
+	}
+
+	defaults := redact.DefaultPolicy()
+	return &models.RedactionPolicy{
+		ProjectID:       projectID,
+		MaskEmails:      defaults.MaskEmails,
+		MaskCredentials: defaults.MaskCredentials,
+		Patterns:        []models.RedactionPattern{},
+		LogLLMContent:   true,
+		Files:           defaultNoiseFileFilter(),
+		IsDefault:       true,
+	}, nil
+}
+
+// defaultNoiseFileFilter returns the filter of projects that haven't configured one, leaving out every kind of noise
+// file
+func defaultNoiseFileFilter() *models.NoiseFileFilter {
+	defaults := noise.DefaultPolicy()
+	kinds := make([]string, len(defaults.Excluded))
+	for i, kind := range defaults.Excluded {
+		kinds[i] = string(kind)
+	}
+	return normalizeNoiseFileFilter(models.NoiseFileFilter{ExcludedKinds: kinds})
+}
+
+// normalizeNoiseFileFilter returns a copy of filter whose lists are never nil, so they're stored and returned as
+// empty lists
+func normalizeNoiseFileFilter(filter models.NoiseFileFilter) *models.NoiseFileFilter {
+	for _, list := range []*[]string{&filter.ExcludedKinds, &filter.IncludePaths, &filter.ExcludePaths} {
+		if *list == nil {
+			*list = []string{}
+		}
+	}
+	return &filter
+}
+
+// ensureProjectExists returns a not found error when the project doesn't exist
+func (s *DefaultRedactionService) ensureProjectExists(ctx context.Context, projectID string) error {
+	exists, err := s.projectRepo.ProjectExists(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to check project existence: %w", err)
+	}
+	if !exists {
+		return apperrors.NotFound(apperrors.CodeProjectNotFound, "project not found")
+	}
+	return nil
+}
+
+// toRedactPolicy converts a stored policy to the policy applied by the RAG builders
+func toRedactPolicy(policy *models.RedactionPolicy) redact.Policy {
+	patterns := make([]redact.Pattern, len(policy.Patterns))
+	for i, pattern := range policy.Patterns {
+		patterns[i] = redact.Pattern{Name: pattern.Name, Expression: pattern.Expression}
+	}
+	files := noise.DefaultPolicy()
+	if policy.Files != nil {
+		files = noise.Policy{Include: policy.Files.IncludePaths, Exclude: policy.Files.ExcludePaths}
+		for _, kind := range policy.Files.ExcludedKinds {
+			files.Excluded = append(files.Excluded, noise.Kind(kind))
+		}
+	}
+	return redact.Policy{
+		MaskEmails:      policy.MaskEmails,
+		MaskCredentials: policy.MaskCredentials,
+		Patterns:        patterns,
+		Files:           files,
+	}
+}
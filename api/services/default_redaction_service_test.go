@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/noise"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/redact"
+)
+
+type redactionServiceMocks struct {
+	policyRepo  *repositoryMocks.MockRedactionPolicyRepository
+	auditRepo   *repositoryMocks.MockRedactionAuditRepository
+	projectRepo *repositoryMocks.MockProjectRepository
+}
+
+func newTestRedactionService(t *testing.T) (*DefaultRedactionService, redactionServiceMocks) {
+	ctrl := gomock.NewController(t)
+	m := redactionServiceMocks{
+		policyRepo:  repositoryMocks.NewMockRedactionPolicyRepository(ctrl),
+		auditRepo:   repositoryMocks.NewMockRedactionAuditRepository(ctrl),
+		projectRepo: repositoryMocks.NewMockProjectRepository(ctrl),
+	}
+	service := NewDefaultRedactionService(m.policyRepo, m.auditRepo, m.projectRepo)
+	now := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	return service, m
+}
+
+func TestDefaultRedactionService_GetPolicy_DefaultsWhenNoneConfigured(t *testing.T) {
+	service, m := newTestRedactionService(t)
+
+	m.projectRepo.EXPECT().ProjectExists(gomock.Any(), "proj-1").Return(true, nil)
+	m.policyRepo.EXPECT().GetPolicy(gomock.Any(), "proj-1").Return(nil, nil)
+
+	policy, err := service.GetPolicy(context.Background(), models.GetRedactionPolicyRequest{ProjectID: "proj-1"})
+
+	require.NoError(t, err)
+	assert.True(t, policy.IsDefault)
+	assert.True(t, policy.MaskEmails)
+	assert.True(t, policy.MaskCredentials)
+	assert.True(t, policy.LogLLMContent)
+	assert.Empty(t, policy.Patterns)
+	assert.Equal(t, &models.NoiseFileFilter{
+		ExcludedKinds: []string{"binary", "minified", "lockfile", "generated"},
+		IncludePaths:  []string{},
+		ExcludePaths:  []string{},
+	}, policy.Files)
+	assert.Nil(t, policy.UpdatedAt)
+}
+
+func TestDefaultRedactionService_GetPolicy_ProjectNotFound(t *testing.T) {
+	service, m := newTestRedactionService(t)
+
+	m.projectRepo.EXPECT().ProjectExists(gomock.Any(), "proj-missing").Return(false, nil)
+
+	_, err := service.GetPolicy(context.Background(), models.GetRedactionPolicyRequest{ProjectID: "proj-missing"})
+
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	assert.Equal(t, apperrors.CodeProjectNotFound, apperrors.CodeOf(err))
+}
+
+func TestDefaultRedactionService_UpdatePolicy(t *testing.T) {
+	service, m := newTestRedactionService(t)
+	disabled := false
+	patterns := []models.RedactionPattern{{Name: "customer-id", Expression: `CUST-[0-9]{6}`}}
+
+	m.projectRepo.EXPECT().ProjectExists(gomock.Any(), "proj-1").Return(true, nil)
+	m.policyRepo.EXPECT().GetPolicy(gomock.Any(), "proj-1").Return(nil, nil)
+	m.policyRepo.EXPECT().
+		SavePolicy(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, policy *models.RedactionPolicy) error {
+			assert.False(t, policy.MaskEmails)
+			assert.True(t, policy.MaskCredentials, "omitted switches keep their value")
+			assert.False(t, policy.LogLLMContent)
+			assert.Equal(t, patterns, policy.Patterns)
+			assert.Equal(t, &models.NoiseFileFilter{
+				ExcludedKinds: []string{"lockfile"},
+				IncludePaths:  []string{"api/*.pb.go"},
+				ExcludePaths:  []string{},
+			}, policy.Files)
+			return nil
+		})
+
+	policy, err := service.UpdatePolicy(context.Background(), models.UpdateRedactionPolicyRequest{
+		ProjectID:     "proj-1",
+		MaskEmails:    &disabled,
+		LogLLMContent: &disabled,
+		Patterns:      patterns,
+		Files:         &models.NoiseFileFilter{ExcludedKinds: []string{"lockfile"}, IncludePaths: []string{"api/*.pb.go"}},
+	})
+
+	require.NoError(t, err)
+	assert.False(t, policy.IsDefault)
+	require.NotNil(t, policy.UpdatedAt)
+	assert.Equal(t, "2024-01-15T10:30:00Z", policy.UpdatedAt.Format(time.RFC3339))
+}
+
+func TestDefaultRedactionService_UpdatePolicy_InvalidPattern(t *testing.T) {
+	service, m := newTestRedactionService(t)
+
+	m.projectRepo.EXPECT().ProjectExists(gomock.Any(), "proj-1").Return(true, nil)
+	m.policyRepo.EXPECT().GetPolicy(gomock.Any(), "proj-1").Return(nil, nil)
+
+	_, err := service.UpdatePolicy(context.Background(), models.UpdateRedactionPolicyRequest{
+		ProjectID: "proj-1",
+		Patterns:  []models.RedactionPattern{{Name: "broken", Expression: `(unclosed`}},
+	})
+
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	assert.Equal(t, apperrors.CodeInvalidRedaction, apperrors.CodeOf(err))
+	assert.ErrorContains(t, err, `invalid redaction pattern "broken"`)
+}
+
+func TestDefaultRedactionService_UpdatePolicy_InvalidFilePattern(t *testing.T) {
+	service, m := newTestRedactionService(t)
+
+	m.projectRepo.EXPECT().ProjectExists(gomock.Any(), "proj-1").Return(true, nil)
+	m.policyRepo.EXPECT().GetPolicy(gomock.Any(), "proj-1").Return(nil, nil)
+
+	_, err := service.UpdatePolicy(context.Background(), models.UpdateRedactionPolicyRequest{
+		ProjectID: "proj-1",
+		Files:     &models.NoiseFileFilter{ExcludePaths: []string{"fixtures/[a-"}},
+	})
+
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	assert.Equal(t, apperrors.CodeInvalidRedaction, apperrors.CodeOf(err))
+	assert.ErrorContains(t, err, `invalid file pattern "fixtures/[a-"`)
+}
+
+func TestDefaultRedactionService_ResolvePolicy(t *testing.T) {
+	service, m := newTestRedactionService(t)
+
+	m.projectRepo.EXPECT().ProjectExists(gomock.Any(), "proj-1").Return(true, nil)
+	m.policyRepo.EXPECT().GetPolicy(gomock.Any(), "proj-1").Return(&models.RedactionPolicy{
+		ProjectID:       "proj-1",
+		MaskCredentials: true,
+		Patterns:        []models.RedactionPattern{{Name: "customer-id", Expression: `CUST-[0-9]{6}`}},
+		Files:           &models.NoiseFileFilter{ExcludedKinds: []string{"generated"}, ExcludePaths: []string{"fixtures/"}},
+	}, nil)
+
+	policy, err := service.ResolvePolicy(context.Background(), "proj-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, redact.Policy{
+		MaskCredentials: true,
+		Patterns:        []redact.Pattern{{Name: "customer-id", Expression: `CUST-[0-9]{6}`}},
+		Files:           noise.Policy{Excluded: []noise.Kind{noise.KindGenerated}, Exclude: []string{"fixtures/"}},
+	}, policy)
+}
+
+func TestDefaultRedactionService_ResolvePolicy_NoProject(t *testing.T) {
+	service, _ := newTestRedactionService(t)
+
+	policy, err := service.ResolvePolicy(context.Background(), "")
+
+	require.NoError(t, err)
+	assert.Equal(t, redact.DefaultPolicy(), policy)
+}
+
+func TestDefaultRedactionService_RecordAudit(t *testing.T) {
+	service, m := newTestRedactionService(t)
+
+	m.auditRepo.EXPECT().
+		CreateAudit(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, audit *models.RedactionAudit) error {
+			assert.Equal(t, "proj-1", audit.ProjectID)
+			assert.Equal(t, "agent-1", audit.AgentID)
+			assert.Equal(t, models.RedactionOperationUpdate, audit.Operation)
+			assert.Equal(t, 3, audit.Redactions)
+			assert.Equal(t, 2, audit.FilesRedacted)
+			assert.Equal(t, map[string]int{redact.RuleEmail: 2, redact.RuleCredential: 1}, audit.Counts)
+			return nil
+		})
+
+	err := service.RecordAudit(context.Background(), "proj-1", "agent-1", models.RedactionOperationUpdate, redact.Report{
+		FilesScanned:  30,
+		FilesRedacted: 2,
+		Counts:        map[string]int{redact.RuleEmail: 2, redact.RuleCredential: 1},
+	})
+
+	require.NoError(t, err)
+}
+
+func TestDefaultRedactionService_RecordAudit_SkipsAgentsWithoutProject(t *testing.T) {
+	service, _ := newTestRedactionService(t)
+
+	err := service.RecordAudit(context.Background(), "", "agent-1", models.RedactionOperationCreate, redact.Report{})
+
+	require.NoError(t, err)
+}
+
+func TestDefaultRedactionService_ListAudits_DefaultLimit(t *testing.T) {
+	service, m := newTestRedactionService(t)
+
+	m.projectRepo.EXPECT().ProjectExists(gomock.Any(), "proj-1").Return(true, nil)
+	m.auditRepo.EXPECT().ListAudits(gomock.Any(), "proj-1", defaultRedactionAuditsLimit).Return([]models.RedactionAudit{
+		{AuditID: "redaction-1", ProjectID: "proj-1", Redactions: 4},
+	}, nil)
+
+	response, err := service.ListAudits(context.Background(), models.ListRedactionAuditsRequest{ProjectID: "proj-1"})
+
+	require.NoError(t, err)
+	require.Len(t, response.Audits, 1)
+	assert.Equal(t, 4, response.Audits[0].Redactions)
+}
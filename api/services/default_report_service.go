@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+)
+
+// DefaultReportService is the default implementation of ReportService, reading from pre-aggregated daily rollups
+type DefaultReportService struct {
+	metricsRepo  repository.TaskMetricsRepository
+	lookbackDays int
+	now          func() time.Time
+}
+
+// NewDefaultReportService creates a new DefaultReportService that recomputes the last lookbackDays days on each refresh
+func NewDefaultReportService(metricsRepo repository.TaskMetricsRepository, lookbackDays int) *DefaultReportService {
+	return &DefaultReportService{
+		metricsRepo:  metricsRepo,
+		lookbackDays: lookbackDays,
+		now:          time.Now,
+	}
+}
+
+// GetTaskReport groups the daily rollups within the requested range into day, week or project buckets
+func (s *DefaultReportService) GetTaskReport(ctx context.Context, request models.GetTaskReportRequest) (*models.GetTaskReportResponse, error) {
+	from, to, err := s.reportRange(request)
+	if err != nil {
+		return nil, err
+	}
+
+	groupBy := request.GroupBy
+	if groupBy == "" {
+		groupBy = models.ReportGroupByDay
+	}
+
+	// Reports read rollups refreshed in the background, so the lag of the read replica doesn't matter
+	rollups, err := s.metricsRepo.ListRollups(repository.WithReadOnly(ctx), request.ProjectID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list task metrics: %w", err)
+	}
+
+	response := &models.GetTaskReportResponse{
+		GroupBy: groupBy,
+		From:    from.Format(models.ReportDateLayout),
+		To:      to.Format(models.ReportDateLayout),
+		Buckets: buildReportBuckets(rollups, groupBy),
+	}
+
+	var refreshedAt time.Time
+	for _, rollup := range rollups {
+		if rollup.RefreshedAt.After(refreshedAt) {
+			refreshedAt = rollup.RefreshedAt
+		}
+	}
+	if !refreshedAt.IsZero() {
+		formatted := refreshedAt.UTC().Format(time.RFC3339)
+		response.RefreshedAt = &formatted
+	}
+
+	return response, nil
+}
+
+// RefreshTaskMetrics recomputes the rollups of the last lookback days, which covers tasks finishing after the day they were created
+func (s *DefaultReportService) RefreshTaskMetrics(ctx context.Context) error {
+	return s.metricsRepo.RefreshRollups(ctx, s.now().UTC().AddDate(0, 0, -s.lookbackDays))
+}
+
+// RunTaskMetricsRefresh backfills the rollups of the longest report range, then refreshes recent rollups
+// every interval until the context is cancelled
+func (s *DefaultReportService) RunTaskMetricsRefresh(ctx context.Context, interval time.Duration) {
+	if err := s.metricsRepo.RefreshRollups(ctx, s.now().UTC().AddDate(0, 0, -models.MaxTaskReportDays)); err != nil {
+		slog.ErrorContext(ctx, "failed to backfill task metrics", "error", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.RefreshTaskMetrics(ctx); err != nil {
+				slog.ErrorContext(ctx, "failed to refresh task metrics", "error", err)
+			}
+		}
+	}
+}
+
+// reportRange resolves the requested report range, defaulting to the days leading up to today
+func (s *DefaultReportService) reportRange(request models.GetTaskReportRequest) (time.Time, time.Time, error) {
+	to := s.now().UTC().Truncate(24 * time.Hour)
+	if request.To != "" {
+		parsed, err := time.Parse(models.ReportDateLayout, request.To)
+		if err != nil {
+			return time.Time{}, time.Time{}, apperrors.Validation(apperrors.CodeInvalidRequest, "invalid to date: %s", request.To)
+		}
+		to = parsed
+	}
+
+	from := to.AddDate(0, 0, -(models.DefaultTaskReportDays - 1))
+	if request.From != "" {
+		parsed, err := time.Parse(models.ReportDateLayout, request.From)
+		if err != nil {
+			return time.Time{}, time.Time{}, apperrors.Validation(apperrors.CodeInvalidRequest, "invalid from date: %s", request.From)
+		}
+		from = parsed
+	}
+
+	if from.After(to) {
+		return time.Time{}, time.Time{}, apperrors.Validation(apperrors.CodeInvalidRequest, "from must not be after to")
+	}
+	if to.Sub(from) >= models.MaxTaskReportDays*24*time.Hour {
+		return time.Time{}, time.Time{}, apperrors.Validation(apperrors.CodeInvalidRequest, "report range must not exceed %d days", models.MaxTaskReportDays)
+	}
+
+	return from, to, nil
+}
+
+// reportAccumulator sums the rollups of one bucket
+type reportAccumulator struct {
+	bucket          models.TaskReportBucket
+	durationMsTotal int64
+	durationCount   int
+	ratingTotal     int64
+}
+
+// buildReportBuckets sums the daily rollups per bucket and derives the rates and means, ordered by bucket key
+func buildReportBuckets(rollups []repository.TaskMetricsRollup, groupBy models.ReportGroupBy) []models.TaskReportBucket {
+	accumulators := make(map[string]*reportAccumulator)
+	for _, rollup := range rollups {
+		key := reportBucketKey(rollup, groupBy)
+		acc, ok := accumulators[key]
+		if !ok {
+			acc = &reportAccumulator{bucket: models.TaskReportBucket{Key: key, FailureCategories: map[string]int{}}}
+			accumulators[key] = acc
+		}
+
+		acc.bucket.Total += rollup.Total
+		acc.bucket.Completed += rollup.Completed
+		acc.bucket.Failed += rollup.Failed
+		acc.bucket.Cancelled += rollup.Cancelled
+		acc.bucket.TokenUsage += rollup.TokenUsage
+		acc.durationMsTotal += rollup.DurationMsTotal
+		acc.durationCount += rollup.DurationCount
+		acc.bucket.Feedback += rollup.Feedback
+		acc.bucket.Accepted += rollup.Accepted
+		acc.bucket.Rejected += rollup.Rejected
+		acc.ratingTotal += rollup.RatingTotal
+		for category, count := range rollup.FailureCategories {
+			acc.bucket.FailureCategories[category] += count
+		}
+	}
+
+	buckets := make([]models.TaskReportBucket, 0, len(accumulators))
+	for _, acc := range accumulators {
+		if finished := acc.bucket.Completed + acc.bucket.Failed; finished > 0 {
+			acc.bucket.SuccessRate = float64(acc.bucket.Completed) / float64(finished)
+		}
+		if acc.durationCount > 0 {
+			acc.bucket.MeanDurationMs = acc.durationMsTotal / int64(acc.durationCount)
+		}
+		if acc.bucket.Feedback > 0 {
+			acc.bucket.AcceptanceRate = float64(acc.bucket.Accepted) / float64(acc.bucket.Feedback)
+			acc.bucket.MeanRating = float64(acc.ratingTotal) / float64(acc.bucket.Feedback)
+		}
+		buckets = append(buckets, acc.bucket)
+	}
+
+	sort.Slice(buckets, func(i, j int) bool {
+		return buckets[i].Key < buckets[j].Key
+	})
+
+	return buckets
+}
+
+// reportBucketKey returns the key of the bucket a rollup belongs to
+func reportBucketKey(rollup repository.TaskMetricsRollup, groupBy models.ReportGroupBy) string {
+	switch groupBy {
+	case models.ReportGroupByProject:
+		return rollup.ProjectID
+	case models.ReportGroupByWeek:
+		// ISO weeks start on Monday
+		offset := (int(rollup.Day.Weekday()) + 6) % 7
+		return rollup.Day.AddDate(0, 0, -offset).Format(models.ReportDateLayout)
+	default:
+		return rollup.Day.Format(models.ReportDateLayout)
+	}
+}
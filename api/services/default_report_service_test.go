@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+)
+
+func TestDefaultReportService_GetTaskReport(t *testing.T) {
+	monday := time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC)
+	refreshedAt := time.Date(2024, time.January, 31, 12, 0, 0, 0, time.UTC)
+	rollups := []repository.TaskMetricsRollup{
+		{
+			ProjectID: "proj-1", Day: monday, Total: 4, Completed: 3, Failed: 1,
+			DurationMsTotal: 40000, DurationCount: 4, TokenUsage: 1000, Feedback: 2, Accepted: 2, RatingTotal: 9,
+			FailureCategories: map[string]int{"timeout": 1}, RefreshedAt: refreshedAt.Add(-time.Hour),
+		},
+		{
+			ProjectID: "proj-2", Day: monday.AddDate(0, 0, 6), Total: 3, Completed: 1, Failed: 1, Cancelled: 1,
+			DurationMsTotal: 20000, DurationCount: 2, TokenUsage: 500, Feedback: 2, Accepted: 1, Rejected: 1, RatingTotal: 5,
+			FailureCategories: map[string]int{"timeout": 1}, RefreshedAt: refreshedAt,
+		},
+		{
+			ProjectID: "proj-1", Day: monday.AddDate(0, 0, 7), Total: 1, Failed: 1,
+			FailureCategories: map[string]int{"uncategorized": 1}, RefreshedAt: refreshedAt,
+		},
+	}
+
+	tests := []struct {
+		name            string
+		groupBy         models.ReportGroupBy
+		expectedBuckets []models.TaskReportBucket
+	}{
+		{
+			name:    "week",
+			groupBy: models.ReportGroupByWeek,
+			expectedBuckets: []models.TaskReportBucket{
+				{Key: "2024-01-15", Total: 7, Completed: 4, Failed: 2, Cancelled: 1, SuccessRate: 4.0 / 6.0, MeanDurationMs: 10000, TokenUsage: 1500, FailureCategories: map[string]int{"timeout": 2}, Feedback: 4, Accepted: 3, Rejected: 1, AcceptanceRate: 0.75, MeanRating: 3.5},
+				{Key: "2024-01-22", Total: 1, Failed: 1, FailureCategories: map[string]int{"uncategorized": 1}},
+			},
+		},
+		{
+			name:    "project",
+			groupBy: models.ReportGroupByProject,
+			expectedBuckets: []models.TaskReportBucket{
+				{Key: "proj-1", Total: 5, Completed: 3, Failed: 2, SuccessRate: 0.6, MeanDurationMs: 10000, TokenUsage: 1000, FailureCategories: map[string]int{"timeout": 1, "uncategorized": 1}, Feedback: 2, Accepted: 2, AcceptanceRate: 1, MeanRating: 4.5},
+				{Key: "proj-2", Total: 3, Completed: 1, Failed: 1, Cancelled: 1, SuccessRate: 0.5, MeanDurationMs: 10000, TokenUsage: 500, FailureCategories: map[string]int{"timeout": 1}, Feedback: 2, Accepted: 1, Rejected: 1, AcceptanceRate: 0.5, MeanRating: 2.5},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			metricsRepo := repositoryMocks.NewMockTaskMetricsRepository(ctrl)
+			service := NewDefaultReportService(metricsRepo, 2)
+
+			metricsRepo.EXPECT().
+				ListRollups(gomock.Any(), "", time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC)).
+				Return(rollups, nil)
+
+			report, err := service.GetTaskReport(context.Background(), models.GetTaskReportRequest{
+				GroupBy: tt.groupBy,
+				From:    "2024-01-01",
+				To:      "2024-01-31",
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.groupBy, report.GroupBy)
+			assert.Equal(t, tt.expectedBuckets, report.Buckets)
+			require.NotNil(t, report.RefreshedAt)
+			assert.Equal(t, "2024-01-31T12:00:00Z", *report.RefreshedAt)
+		})
+	}
+}
+
+func TestDefaultReportService_GetTaskReport_DefaultRange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	metricsRepo := repositoryMocks.NewMockTaskMetricsRepository(ctrl)
+	service := NewDefaultReportService(metricsRepo, 2)
+	service.now = func() time.Time { return time.Date(2024, time.January, 30, 18, 0, 0, 0, time.UTC) }
+
+	metricsRepo.EXPECT().
+		ListRollups(gomock.Any(), "proj-1", time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, time.January, 30, 0, 0, 0, 0, time.UTC)).
+		Return(nil, nil)
+
+	report, err := service.GetTaskReport(context.Background(), models.GetTaskReportRequest{ProjectID: "proj-1"})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.ReportGroupByDay, report.GroupBy)
+	assert.Equal(t, "2024-01-01", report.From)
+	assert.Equal(t, "2024-01-30", report.To)
+	assert.Empty(t, report.Buckets)
+	assert.Nil(t, report.RefreshedAt)
+}
+
+func TestDefaultReportService_GetTaskReport_InvalidRange(t *testing.T) {
+	tests := []struct {
+		name    string
+		request models.GetTaskReportRequest
+	}{
+		{name: "from_after_to", request: models.GetTaskReportRequest{From: "2024-02-01", To: "2024-01-01"}},
+		{name: "range_too_long", request: models.GetTaskReportRequest{From: "2022-01-01", To: "2024-01-01"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := NewDefaultReportService(repositoryMocks.NewMockTaskMetricsRepository(ctrl), 2)
+
+			_, err := service.GetTaskReport(context.Background(), tt.request)
+
+			assert.ErrorIs(t, err, apperrors.ErrValidation)
+		})
+	}
+}
+
+func TestDefaultReportService_RefreshTaskMetrics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	metricsRepo := repositoryMocks.NewMockTaskMetricsRepository(ctrl)
+	service := NewDefaultReportService(metricsRepo, 2)
+	now := time.Date(2024, time.January, 30, 18, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	metricsRepo.EXPECT().RefreshRollups(gomock.Any(), now.AddDate(0, 0, -2)).Return(nil)
+
+	assert.NoError(t, service.RefreshTaskMetrics(context.Background()))
+}
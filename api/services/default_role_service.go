@@ -0,0 +1,432 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+)
+
+// DefaultRoleService is the default implementation of RoleService. The owner role holds every permission regardless
+// of the permissions assigned to it, so changing roles can't lock everyone out.
+type DefaultRoleService struct {
+	roleRepo    repository.RoleRepository
+	userRepo    repository.UserRepository
+	projectRepo repository.ProjectRepository
+	auditRepo   repository.AuditEventRepository
+}
+
+// NewDefaultRoleService creates a new DefaultRoleService recording the changes to roles and project members in the
+// audit log
+func NewDefaultRoleService(roleRepo repository.RoleRepository, userRepo repository.UserRepository, projectRepo repository.ProjectRepository, auditRepo repository.AuditEventRepository) *DefaultRoleService {
+	return &DefaultRoleService{
+		roleRepo:    roleRepo,
+		userRepo:    userRepo,
+		projectRepo: projectRepo,
+		auditRepo:   auditRepo,
+	}
+}
+
+// ListPermissions lists the permissions roles can grant
+func (s *DefaultRoleService) ListPermissions(ctx context.Context) (*models.ListPermissionsResponse, error) {
+	permissions, err := s.roleRepo.ListPermissions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ListPermissionsResponse{Permissions: permissions}, nil
+}
+
+// CreateRole defines a custom role
+func (s *DefaultRoleService) CreateRole(ctx context.Context, request models.CreateRoleRequest) (*models.Role, error) {
+	permissions, err := s.checkPermissions(ctx, request.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	role := &models.Role{
+		Name:        request.Name,
+		Description: request.Description,
+		Permissions: permissions,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := s.roleRepo.CreateRole(ctx, role); err != nil {
+		return nil, err
+	}
+
+	s.recordEvent(ctx, &models.AuditEvent{
+		ActorID:    request.ActorID,
+		Action:     models.AuditActionRoleCreated,
+		TargetType: "role",
+		TargetID:   string(role.Name),
+		Details:    map[string]string{"permissions": joinPermissions(role.Permissions)},
+	})
+
+	return role, nil
+}
+
+// GetRole retrieves a role with its permissions
+func (s *DefaultRoleService) GetRole(ctx context.Context, name models.UserRole) (*models.Role, error) {
+	role, err := s.roleRepo.GetRole(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if role == nil {
+		return nil, apperrors.NotFound(apperrors.CodeRoleNotFound, "role not found: %s", name)
+	}
+
+	return role, nil
+}
+
+// UpdateRole replaces the description and permissions of a role. The permissions of the owner role can't be changed.
+func (s *DefaultRoleService) UpdateRole(ctx context.Context, request models.UpdateRoleRequest) (*models.Role, error) {
+	role, err := s.GetRole(ctx, request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if role.Name == models.RoleOwner {
+		return nil, apperrors.Validation(apperrors.CodeBuiltInRole, "the owner role always holds every permission and can't be changed")
+	}
+
+	permissions, err := s.checkPermissions(ctx, request.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	role.Description = request.Description
+	role.Permissions = permissions
+	role.UpdatedAt = time.Now().UTC()
+
+	if err := s.roleRepo.UpdateRole(ctx, role); err != nil {
+		return nil, err
+	}
+
+	s.recordEvent(ctx, &models.AuditEvent{
+		ActorID:    request.ActorID,
+		Action:     models.AuditActionRoleUpdated,
+		TargetType: "role",
+		TargetID:   string(role.Name),
+		Details:    map[string]string{"permissions": joinPermissions(role.Permissions)},
+	})
+
+	return role, nil
+}
+
+// DeleteRole deletes a custom role no user holds, in their own right or within a project
+func (s *DefaultRoleService) DeleteRole(ctx context.Context, request models.DeleteRoleRequest) error {
+	name := request.Name
+	role, err := s.GetRole(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	if role.BuiltIn {
+		return apperrors.Validation(apperrors.CodeBuiltInRole, "built-in role %s can't be deleted", name)
+	}
+
+	_, holders, err := s.userRepo.ListUsers(ctx, &repository.ListUsersFilter{Role: &name, Limit: 1})
+	if err != nil {
+		return fmt.Errorf("failed to count the users holding role %s: %w", name, err)
+	}
+	if holders > 0 {
+		return apperrors.Conflict(apperrors.CodeRoleInUse, "role %s is held by %d users", name, holders)
+	}
+
+	if err := s.roleRepo.DeleteRole(ctx, name); err != nil {
+		return err
+	}
+
+	s.recordEvent(ctx, &models.AuditEvent{
+		ActorID:    request.ActorID,
+		Action:     models.AuditActionRoleDeleted,
+		TargetType: "role",
+		TargetID:   string(name),
+	})
+
+	return nil
+}
+
+// ListRoles lists the roles with their permissions
+func (s *DefaultRoleService) ListRoles(ctx context.Context) (*models.ListRolesResponse, error) {
+	roles, err := s.roleRepo.ListRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ListRolesResponse{Roles: roles}, nil
+}
+
+// ListProjectMembers lists the members of a project
+func (s *DefaultRoleService) ListProjectMembers(ctx context.Context, projectID string) (*models.ListProjectMembersResponse, error) {
+	if err := s.checkProject(ctx, projectID); err != nil {
+		return nil, err
+	}
+
+	members, err := s.roleRepo.ListProjectMembers(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ListProjectMembersResponse{Members: members}, nil
+}
+
+// AddProjectMember adds a member to a project, or changes the role of a member. The owner role is only given, and
+// taken, by transferring the ownership of the project.
+func (s *DefaultRoleService) AddProjectMember(ctx context.Context, request models.AddProjectMemberRequest) (*models.ProjectMember, error) {
+	if request.Role == models.RoleOwner {
+		return nil, apperrors.Validation(apperrors.CodeProjectOwner, "the owner of a project is changed by transferring its ownership")
+	}
+
+	if err := s.checkProject(ctx, request.ProjectID); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetUser(ctx, request.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, apperrors.NotFound(apperrors.CodeUserNotFound, "user not found: %s", request.UserID)
+	}
+
+	if _, err := s.GetRole(ctx, request.Role); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.roleRepo.GetProjectMember(ctx, request.ProjectID, request.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.Role == models.RoleOwner {
+		return nil, apperrors.Conflict(apperrors.CodeProjectOwner, "the owner of a project is changed by transferring its ownership")
+	}
+
+	now := time.Now().UTC()
+	member := &models.ProjectMember{
+		ProjectID: request.ProjectID,
+		UserID:    request.UserID,
+		Role:      request.Role,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	details := map[string]string{"role": string(member.Role)}
+	if existing != nil {
+		member.CreatedAt = existing.CreatedAt
+		details["previous_role"] = string(existing.Role)
+	}
+
+	if err := s.roleRepo.SetProjectMember(ctx, member); err != nil {
+		return nil, err
+	}
+
+	s.recordEvent(ctx, &models.AuditEvent{
+		ActorID:    request.ActorID,
+		Action:     models.AuditActionMemberAdded,
+		TargetType: "user",
+		TargetID:   member.UserID,
+		ProjectID:  member.ProjectID,
+		Details:    details,
+	})
+
+	return member, nil
+}
+
+// RemoveProjectMember removes a member other than the owner from a project, so they hold their own role there again
+func (s *DefaultRoleService) RemoveProjectMember(ctx context.Context, request models.RemoveProjectMemberRequest) error {
+	projectID, userID := request.ProjectID, request.UserID
+	member, err := s.roleRepo.GetProjectMember(ctx, projectID, userID)
+	if err != nil {
+		return err
+	}
+	if member == nil {
+		return apperrors.NotFound(apperrors.CodeProjectMemberNotFound, "user %s is not a member of project %s", userID, projectID)
+	}
+	if member.Role == models.RoleOwner {
+		return apperrors.Conflict(apperrors.CodeProjectOwner, "the owner of a project can't be removed, transfer its ownership first")
+	}
+
+	if err := s.roleRepo.DeleteProjectMember(ctx, projectID, userID); err != nil {
+		return err
+	}
+
+	s.recordEvent(ctx, &models.AuditEvent{
+		ActorID:    request.ActorID,
+		Action:     models.AuditActionMemberRemoved,
+		TargetType: "user",
+		TargetID:   userID,
+		ProjectID:  projectID,
+		Details:    map[string]string{"role": string(member.Role)},
+	})
+
+	return nil
+}
+
+// TransferProjectOwnership makes an active user the owner of a project. The previous owner stays a member with the
+// admin role.
+func (s *DefaultRoleService) TransferProjectOwnership(ctx context.Context, request models.TransferProjectOwnershipRequest) (*models.ProjectMember, error) {
+	if err := s.checkProject(ctx, request.ProjectID); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetUser(ctx, request.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, apperrors.NotFound(apperrors.CodeUserNotFound, "user not found: %s", request.UserID)
+	}
+	if user.Status != models.UserStatusActive {
+		return nil, apperrors.Validation(apperrors.CodeProjectOwner, "user %s is not active", request.UserID)
+	}
+
+	if err := s.roleRepo.TransferProjectOwnership(ctx, request.ProjectID, request.UserID, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+
+	s.recordEvent(ctx, &models.AuditEvent{
+		ActorID:    request.ActorID,
+		Action:     models.AuditActionOwnershipTransferred,
+		TargetType: "user",
+		TargetID:   request.UserID,
+		ProjectID:  request.ProjectID,
+	})
+
+	return s.roleRepo.GetProjectMember(ctx, request.ProjectID, request.UserID)
+}
+
+// AssignProjectOwner makes the user with the auth provider ID the owner of a project they created
+func (s *DefaultRoleService) AssignProjectOwner(ctx context.Context, projectID, authID string) error {
+	user, err := s.userRepo.GetUserByAuthID(ctx, authID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	err = s.roleRepo.SetProjectMember(ctx, &models.ProjectMember{
+		ProjectID: projectID,
+		UserID:    user.UserID,
+		Role:      models.RoleOwner,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.recordEvent(ctx, &models.AuditEvent{
+		ActorID:    authID,
+		Action:     models.AuditActionMemberAdded,
+		TargetType: "user",
+		TargetID:   user.UserID,
+		ProjectID:  projectID,
+		Details:    map[string]string{"role": string(models.RoleOwner)},
+	})
+
+	return nil
+}
+
+// Authorize checks that an active user holds a permission through their role, or the role they hold as a member of the
+// project
+func (s *DefaultRoleService) Authorize(ctx context.Context, authID, projectID string, permission models.Permission) error {
+	user, err := s.userRepo.GetUserByAuthID(ctx, authID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return apperrors.Forbidden(apperrors.CodeForbidden, "caller has no user account")
+		}
+		return err
+	}
+	if user.Status != models.UserStatusActive {
+		return apperrors.Forbidden(apperrors.CodeForbidden, "caller's account is not active")
+	}
+
+	roleName := user.Role
+	if projectID != "" {
+		member, err := s.roleRepo.GetProjectMember(ctx, projectID, user.UserID)
+		if err != nil {
+			return err
+		}
+		if member != nil {
+			roleName = member.Role
+		}
+	}
+
+	if roleName == models.RoleOwner {
+		return nil
+	}
+
+	role, err := s.roleRepo.GetRole(ctx, roleName)
+	if err != nil {
+		return err
+	}
+	if role == nil || !slices.Contains(role.Permissions, permission) {
+		return apperrors.Forbidden(apperrors.CodePermissionDenied, "role %s doesn't grant permission %s", roleName, permission)
+	}
+
+	return nil
+}
+
+// recordEvent appends an event to the audit log. The change it records already happened, so a failure to record it is
+// only logged.
+func (s *DefaultRoleService) recordEvent(ctx context.Context, event *models.AuditEvent) {
+	event.EventID = "audit-" + uuid.New().String()
+	event.OccurredAt = time.Now().UTC()
+
+	if err := s.auditRepo.RecordEvent(ctx, event); err != nil {
+		slog.WarnContext(ctx, "Failed to record audit event", "action", event.Action, "target_id", event.TargetID, "error", err)
+	}
+}
+
+// joinPermissions lists permissions separated by commas
+func joinPermissions(permissions []models.Permission) string {
+	names := make([]string, len(permissions))
+	for i, permission := range permissions {
+		names[i] = string(permission)
+	}
+	return strings.Join(names, ",")
+}
+
+// checkProject checks that a project exists
+func (s *DefaultRoleService) checkProject(ctx context.Context, projectID string) error {
+	project, err := s.projectRepo.GetProject(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to get project: %w", err)
+	}
+	if project == nil {
+		return apperrors.NotFound(apperrors.CodeProjectNotFound, "project not found")
+	}
+
+	return nil
+}
+
+// checkPermissions checks that roles can grant each of permissions, returning them without duplicates
+func (s *DefaultRoleService) checkPermissions(ctx context.Context, permissions []models.Permission) ([]models.Permission, error) {
+	known, err := s.roleRepo.ListPermissions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	checked := make([]models.Permission, 0, len(permissions))
+	for _, permission := range permissions {
+		if !slices.ContainsFunc(known, func(definition models.PermissionDefinition) bool { return definition.Name == permission }) {
+			return nil, apperrors.Validation(apperrors.CodeUnknownPermission, "unknown permission %s", permission)
+		}
+		if !slices.Contains(checked, permission) {
+			checked = append(checked, permission)
+		}
+	}
+
+	return checked, nil
+}
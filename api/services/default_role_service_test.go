@@ -0,0 +1,369 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+)
+
+func newTestRoleService(ctrl *gomock.Controller) (*DefaultRoleService, *repositoryMocks.MockRoleRepository, *repositoryMocks.MockUserRepository, *repositoryMocks.MockProjectRepository) {
+	roleRepo := repositoryMocks.NewMockRoleRepository(ctrl)
+	userRepo := repositoryMocks.NewMockUserRepository(ctrl)
+	projectRepo := repositoryMocks.NewMockProjectRepository(ctrl)
+	auditRepo := repositoryMocks.NewMockAuditEventRepository(ctrl)
+	auditRepo.EXPECT().RecordEvent(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	return NewDefaultRoleService(roleRepo, userRepo, projectRepo, auditRepo), roleRepo, userRepo, projectRepo
+}
+
+func TestDefaultRoleService_CreateRole(t *testing.T) {
+	ctx := context.Background()
+	request := models.CreateRoleRequest{
+		Name:        "security-reviewer",
+		Description: "Reviews the findings of security scans",
+		Permissions: []models.Permission{models.PermissionProjectRead, models.PermissionTaskRead, models.PermissionProjectRead},
+	}
+
+	t.Run("creates role without duplicate permissions", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, roleRepo, _, _ := newTestRoleService(ctrl)
+		roleRepo.EXPECT().ListPermissions(ctx).Return(models.BuiltInPermissions, nil)
+		roleRepo.EXPECT().CreateRole(ctx, gomock.Any()).Return(nil)
+
+		role, err := service.CreateRole(ctx, request)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.UserRole("security-reviewer"), role.Name)
+		assert.False(t, role.BuiltIn)
+		assert.Equal(t, []models.Permission{models.PermissionProjectRead, models.PermissionTaskRead}, role.Permissions)
+	})
+
+	t.Run("unknown permission", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, roleRepo, _, _ := newTestRoleService(ctrl)
+		roleRepo.EXPECT().ListPermissions(ctx).Return(models.BuiltInPermissions, nil)
+
+		_, err := service.CreateRole(ctx, models.CreateRoleRequest{Name: "security-reviewer", Permissions: []models.Permission{"scan:approve"}})
+
+		assert.ErrorIs(t, err, apperrors.ErrValidation)
+		assert.Equal(t, apperrors.CodeUnknownPermission, apperrors.CodeOf(err))
+	})
+}
+
+func TestDefaultRoleService_UpdateRole_Owner(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, roleRepo, _, _ := newTestRoleService(ctrl)
+	roleRepo.EXPECT().GetRole(gomock.Any(), models.RoleOwner).Return(&models.Role{Name: models.RoleOwner, BuiltIn: true}, nil)
+
+	_, err := service.UpdateRole(context.Background(), models.UpdateRoleRequest{Name: models.RoleOwner, Permissions: []models.Permission{models.PermissionProjectRead}})
+
+	assert.Equal(t, apperrors.CodeBuiltInRole, apperrors.CodeOf(err))
+}
+
+func TestDefaultRoleService_DeleteRole(t *testing.T) {
+	ctx := context.Background()
+	securityReviewer := models.UserRole("security-reviewer")
+
+	t.Run("deletes role no user holds", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, roleRepo, userRepo, _ := newTestRoleService(ctrl)
+		roleRepo.EXPECT().GetRole(ctx, securityReviewer).Return(&models.Role{Name: securityReviewer}, nil)
+		userRepo.EXPECT().ListUsers(ctx, &repository.ListUsersFilter{Role: &securityReviewer, Limit: 1}).Return(nil, 0, nil)
+		roleRepo.EXPECT().DeleteRole(ctx, securityReviewer).Return(nil)
+
+		require.NoError(t, service.DeleteRole(ctx, models.DeleteRoleRequest{Name: securityReviewer}))
+	})
+
+	t.Run("role held by users", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, roleRepo, userRepo, _ := newTestRoleService(ctrl)
+		roleRepo.EXPECT().GetRole(ctx, securityReviewer).Return(&models.Role{Name: securityReviewer}, nil)
+		userRepo.EXPECT().ListUsers(ctx, gomock.Any()).Return([]*models.DBUser{{UserID: "user-1"}}, 2, nil)
+
+		err := service.DeleteRole(ctx, models.DeleteRoleRequest{Name: securityReviewer})
+
+		assert.ErrorIs(t, err, apperrors.ErrConflict)
+		assert.Equal(t, apperrors.CodeRoleInUse, apperrors.CodeOf(err))
+	})
+
+	t.Run("built-in role", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, roleRepo, _, _ := newTestRoleService(ctrl)
+		roleRepo.EXPECT().GetRole(ctx, models.RoleViewer).Return(&models.Role{Name: models.RoleViewer, BuiltIn: true}, nil)
+
+		err := service.DeleteRole(ctx, models.DeleteRoleRequest{Name: models.RoleViewer})
+
+		assert.Equal(t, apperrors.CodeBuiltInRole, apperrors.CodeOf(err))
+	})
+}
+
+func TestDefaultRoleService_AddProjectMember(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("adds member", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, roleRepo, userRepo, projectRepo := newTestRoleService(ctrl)
+		projectRepo.EXPECT().GetProject(ctx, "proj-1").Return(&repository.ProjectRecord{ProjectID: "proj-1"}, nil)
+		userRepo.EXPECT().GetUser(ctx, "user-1").Return(&models.DBUser{UserID: "user-1"}, nil)
+		roleRepo.EXPECT().GetRole(ctx, models.UserRole("security-reviewer")).Return(&models.Role{Name: "security-reviewer"}, nil)
+		roleRepo.EXPECT().GetProjectMember(ctx, "proj-1", "user-1").Return(nil, nil)
+		roleRepo.EXPECT().SetProjectMember(ctx, gomock.Any()).Return(nil)
+
+		member, err := service.AddProjectMember(ctx, models.AddProjectMemberRequest{ProjectID: "proj-1", UserID: "user-1", Role: "security-reviewer"})
+
+		require.NoError(t, err)
+		assert.Equal(t, models.UserRole("security-reviewer"), member.Role)
+	})
+
+	t.Run("owner role is only transferred", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, _, _, _ := newTestRoleService(ctrl)
+
+		_, err := service.AddProjectMember(ctx, models.AddProjectMemberRequest{ProjectID: "proj-1", UserID: "user-1", Role: models.RoleOwner})
+
+		assert.ErrorIs(t, err, apperrors.ErrValidation)
+		assert.Equal(t, apperrors.CodeProjectOwner, apperrors.CodeOf(err))
+	})
+
+	t.Run("owner can't be demoted", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, roleRepo, userRepo, projectRepo := newTestRoleService(ctrl)
+		projectRepo.EXPECT().GetProject(ctx, "proj-1").Return(&repository.ProjectRecord{ProjectID: "proj-1"}, nil)
+		userRepo.EXPECT().GetUser(ctx, "user-1").Return(&models.DBUser{UserID: "user-1"}, nil)
+		roleRepo.EXPECT().GetRole(ctx, models.RoleViewer).Return(&models.Role{Name: models.RoleViewer}, nil)
+		roleRepo.EXPECT().GetProjectMember(ctx, "proj-1", "user-1").Return(&models.ProjectMember{Role: models.RoleOwner}, nil)
+
+		_, err := service.AddProjectMember(ctx, models.AddProjectMemberRequest{ProjectID: "proj-1", UserID: "user-1", Role: models.RoleViewer})
+
+		assert.ErrorIs(t, err, apperrors.ErrConflict)
+		assert.Equal(t, apperrors.CodeProjectOwner, apperrors.CodeOf(err))
+	})
+}
+
+func TestDefaultRoleService_AddProjectMember_RecordsAuditEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	roleRepo := repositoryMocks.NewMockRoleRepository(ctrl)
+	userRepo := repositoryMocks.NewMockUserRepository(ctrl)
+	projectRepo := repositoryMocks.NewMockProjectRepository(ctrl)
+	auditRepo := repositoryMocks.NewMockAuditEventRepository(ctrl)
+	service := NewDefaultRoleService(roleRepo, userRepo, projectRepo, auditRepo)
+
+	projectRepo.EXPECT().GetProject(ctx, "proj-1").Return(&repository.ProjectRecord{ProjectID: "proj-1"}, nil)
+	userRepo.EXPECT().GetUser(ctx, "user-1").Return(&models.DBUser{UserID: "user-1"}, nil)
+	roleRepo.EXPECT().GetRole(ctx, models.RoleDeveloper).Return(&models.Role{Name: models.RoleDeveloper}, nil)
+	roleRepo.EXPECT().GetProjectMember(ctx, "proj-1", "user-1").Return(&models.ProjectMember{Role: models.RoleViewer}, nil)
+	roleRepo.EXPECT().SetProjectMember(ctx, gomock.Any()).Return(nil)
+	auditRepo.EXPECT().RecordEvent(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, event *models.AuditEvent) error {
+		assert.NotEmpty(t, event.EventID)
+		assert.Equal(t, "auth-admin", event.ActorID)
+		assert.Equal(t, models.AuditActionMemberAdded, event.Action)
+		assert.Equal(t, "user-1", event.TargetID)
+		assert.Equal(t, "proj-1", event.ProjectID)
+		assert.Equal(t, map[string]string{"role": "developer", "previous_role": "viewer"}, event.Details)
+		return nil
+	})
+
+	_, err := service.AddProjectMember(ctx, models.AddProjectMemberRequest{
+		ProjectID: "proj-1", UserID: "user-1", Role: models.RoleDeveloper, ActorID: "auth-admin",
+	})
+
+	require.NoError(t, err)
+}
+
+func TestDefaultRoleService_RemoveProjectMember(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("removes member", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, roleRepo, _, _ := newTestRoleService(ctrl)
+		roleRepo.EXPECT().GetProjectMember(ctx, "proj-1", "user-1").Return(&models.ProjectMember{Role: models.RoleViewer}, nil)
+		roleRepo.EXPECT().DeleteProjectMember(ctx, "proj-1", "user-1").Return(nil)
+
+		require.NoError(t, service.RemoveProjectMember(ctx, models.RemoveProjectMemberRequest{ProjectID: "proj-1", UserID: "user-1"}))
+	})
+
+	t.Run("owner can't be removed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, roleRepo, _, _ := newTestRoleService(ctrl)
+		roleRepo.EXPECT().GetProjectMember(ctx, "proj-1", "user-1").Return(&models.ProjectMember{Role: models.RoleOwner}, nil)
+
+		err := service.RemoveProjectMember(ctx, models.RemoveProjectMemberRequest{ProjectID: "proj-1", UserID: "user-1"})
+
+		assert.Equal(t, apperrors.CodeProjectOwner, apperrors.CodeOf(err))
+	})
+
+	t.Run("not a member", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, roleRepo, _, _ := newTestRoleService(ctrl)
+		roleRepo.EXPECT().GetProjectMember(ctx, "proj-1", "user-1").Return(nil, nil)
+
+		err := service.RemoveProjectMember(ctx, models.RemoveProjectMemberRequest{ProjectID: "proj-1", UserID: "user-1"})
+
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	})
+}
+
+func TestDefaultRoleService_TransferProjectOwnership(t *testing.T) {
+	ctx := context.Background()
+	request := models.TransferProjectOwnershipRequest{ProjectID: "proj-1", UserID: "user-2"}
+
+	t.Run("transfers ownership", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, roleRepo, userRepo, projectRepo := newTestRoleService(ctrl)
+		projectRepo.EXPECT().GetProject(ctx, "proj-1").Return(&repository.ProjectRecord{ProjectID: "proj-1"}, nil)
+		userRepo.EXPECT().GetUser(ctx, "user-2").Return(&models.DBUser{UserID: "user-2", Status: models.UserStatusActive}, nil)
+		roleRepo.EXPECT().TransferProjectOwnership(ctx, "proj-1", "user-2", gomock.Any()).Return(nil)
+		roleRepo.EXPECT().GetProjectMember(ctx, "proj-1", "user-2").Return(&models.ProjectMember{ProjectID: "proj-1", UserID: "user-2", Role: models.RoleOwner}, nil)
+
+		member, err := service.TransferProjectOwnership(ctx, request)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.RoleOwner, member.Role)
+	})
+
+	t.Run("inactive user", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, _, userRepo, projectRepo := newTestRoleService(ctrl)
+		projectRepo.EXPECT().GetProject(ctx, "proj-1").Return(&repository.ProjectRecord{ProjectID: "proj-1"}, nil)
+		userRepo.EXPECT().GetUser(ctx, "user-2").Return(&models.DBUser{UserID: "user-2", Status: models.UserStatusSuspended}, nil)
+
+		_, err := service.TransferProjectOwnership(ctx, request)
+
+		assert.ErrorIs(t, err, apperrors.ErrValidation)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, _, userRepo, projectRepo := newTestRoleService(ctrl)
+		projectRepo.EXPECT().GetProject(ctx, "proj-1").Return(&repository.ProjectRecord{ProjectID: "proj-1"}, nil)
+		userRepo.EXPECT().GetUser(ctx, "user-2").Return(nil, nil)
+
+		_, err := service.TransferProjectOwnership(ctx, request)
+
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	})
+}
+
+func TestDefaultRoleService_Authorize(t *testing.T) {
+	ctx := context.Background()
+	securityReviewer := &models.Role{Name: "security-reviewer", Permissions: []models.Permission{models.PermissionProjectRead, models.PermissionTaskRead}}
+	viewer := &models.Role{Name: models.RoleViewer, Permissions: []models.Permission{models.PermissionProjectRead}}
+
+	tests := []struct {
+		name         string
+		user         *models.DBUser
+		projectID    string
+		member       *models.ProjectMember
+		permission   models.Permission
+		expectedCode string
+	}{
+		{
+			name:       "custom role grants permission",
+			user:       &models.DBUser{UserID: "user-1", Role: "security-reviewer", Status: models.UserStatusActive},
+			permission: models.PermissionTaskRead,
+		},
+		{
+			name:         "role lacks permission",
+			user:         &models.DBUser{UserID: "user-1", Role: models.RoleViewer, Status: models.UserStatusActive},
+			permission:   models.PermissionTaskRead,
+			expectedCode: apperrors.CodePermissionDenied,
+		},
+		{
+			name:       "project member role overrides own role",
+			user:       &models.DBUser{UserID: "user-1", Role: models.RoleViewer, Status: models.UserStatusActive},
+			projectID:  "proj-1",
+			member:     &models.ProjectMember{ProjectID: "proj-1", UserID: "user-1", Role: "security-reviewer"},
+			permission: models.PermissionTaskRead,
+		},
+		{
+			name:         "project member role can restrict own role",
+			user:         &models.DBUser{UserID: "user-1", Role: "security-reviewer", Status: models.UserStatusActive},
+			projectID:    "proj-1",
+			member:       &models.ProjectMember{ProjectID: "proj-1", UserID: "user-1", Role: models.RoleViewer},
+			permission:   models.PermissionTaskRead,
+			expectedCode: apperrors.CodePermissionDenied,
+		},
+		{
+			name:       "owner holds every permission",
+			user:       &models.DBUser{UserID: "user-1", Role: models.RoleOwner, Status: models.UserStatusActive},
+			projectID:  "proj-1",
+			permission: models.PermissionRoleManage,
+		},
+		{
+			name:         "inactive user",
+			user:         &models.DBUser{UserID: "user-1", Role: models.RoleOwner, Status: models.UserStatusSuspended},
+			permission:   models.PermissionProjectRead,
+			expectedCode: apperrors.CodeForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, roleRepo, userRepo, _ := newTestRoleService(ctrl)
+			userRepo.EXPECT().GetUserByAuthID(ctx, "auth-1").Return(tt.user, nil)
+			if tt.projectID != "" {
+				roleRepo.EXPECT().GetProjectMember(ctx, tt.projectID, "user-1").Return(tt.member, nil).MaxTimes(1)
+			}
+			roleRepo.EXPECT().GetRole(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, name models.UserRole) (*models.Role, error) {
+				for _, role := range []*models.Role{securityReviewer, viewer} {
+					if role.Name == name {
+						return role, nil
+					}
+				}
+				return nil, nil
+			}).MaxTimes(1)
+
+			err := service.Authorize(ctx, "auth-1", tt.projectID, tt.permission)
+
+			if tt.expectedCode == "" {
+				require.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, apperrors.ErrForbidden)
+			assert.Equal(t, tt.expectedCode, apperrors.CodeOf(err))
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/auth"
+)
+
+// serviceAccountTokenTouchInterval is how stale the last use of a token gets before it's recorded again, so that
+// every request doesn't write to the database
+const serviceAccountTokenTouchInterval = time.Minute
+
+// serviceAccountEmailDomain is the reserved domain of the email addresses of the users service accounts act as
+const serviceAccountEmailDomain = "service-accounts.invalid"
+
+// serviceAccountNamePattern matches the names of service accounts
+var serviceAccountNamePattern = regexp.MustCompile(`^[a-z][a-z0-9-]*[a-z0-9]$`)
+
+// DefaultServiceAccountService is the default implementation of ServiceAccountService
+type DefaultServiceAccountService struct {
+	accountRepo repository.ServiceAccountRepository
+	userRepo    repository.UserRepository
+	roleService RoleService
+	now         func() time.Time
+}
+
+// NewDefaultServiceAccountService creates a new DefaultServiceAccountService. The user repository holds the users
+// service accounts act as, so that their roles and project memberships are governed like those of any user.
+func NewDefaultServiceAccountService(accountRepo repository.ServiceAccountRepository, userRepo repository.UserRepository, roleService RoleService) *DefaultServiceAccountService {
+	return &DefaultServiceAccountService{
+		accountRepo: accountRepo,
+		userRepo:    userRepo,
+		roleService: roleService,
+		now:         time.Now,
+	}
+}
+
+// CreateServiceAccount creates a service account and the user it acts as. Service accounts can hold any existing role
+// but owner.
+func (s *DefaultServiceAccountService) CreateServiceAccount(ctx context.Context, request models.CreateServiceAccountRequest) (*models.ServiceAccount, error) {
+	if !serviceAccountNamePattern.MatchString(request.Name) {
+		return nil, apperrors.Validation(apperrors.CodeInvalidRequest, "name %q must be lowercase letters, digits and dashes, starting with a letter", request.Name)
+	}
+	if request.Role == models.RoleOwner {
+		return nil, apperrors.Validation(apperrors.CodeInvalidRequest, "service accounts can't hold the owner role")
+	}
+	if _, err := s.roleService.GetRole(ctx, request.Role); err != nil {
+		return nil, err
+	}
+
+	serviceAccountID := "sa-" + uuid.New().String()
+	user, err := s.userRepo.CreateUser(ctx, &models.DBUser{
+		AuthID:   models.ServiceAccountAuthIDPrefix + serviceAccountID,
+		Email:    serviceAccountID + "@" + serviceAccountEmailDomain,
+		Username: "service-account-" + request.Name,
+		Role:     request.Role,
+		Status:   models.UserStatusActive,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create service account user: %w", err)
+	}
+
+	account := &models.ServiceAccount{
+		ServiceAccountID: serviceAccountID,
+		Name:             request.Name,
+		Description:      request.Description,
+		Role:             user.Role,
+		UserID:           user.UserID,
+		CreatedBy:        request.CreatedBy,
+		CreatedAt:        s.now().UTC(),
+	}
+	if err := s.accountRepo.CreateAccount(ctx, account); err != nil {
+		if deleteErr := s.userRepo.DeleteUser(ctx, user.UserID); deleteErr != nil {
+			slog.WarnContext(ctx, "failed to delete user of uncreated service account", "user_id", user.UserID, "error", deleteErr)
+		}
+		return nil, fmt.Errorf("failed to create service account: %w", err)
+	}
+
+	return account, nil
+}
+
+// GetServiceAccount gets a service account
+func (s *DefaultServiceAccountService) GetServiceAccount(ctx context.Context, request models.GetServiceAccountRequest) (*models.ServiceAccount, error) {
+	account, err := s.accountRepo.GetAccount(ctx, request.ServiceAccountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service account: %w", err)
+	}
+	if err := s.loadRole(ctx, account); err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// ListServiceAccounts lists the service accounts ordered by name
+func (s *DefaultServiceAccountService) ListServiceAccounts(ctx context.Context) (*models.ListServiceAccountsResponse, error) {
+	accounts, err := s.accountRepo.ListAccounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service accounts: %w", err)
+	}
+	for i := range accounts {
+		if err := s.loadRole(ctx, &accounts[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return &models.ListServiceAccountsResponse{ServiceAccounts: accounts}, nil
+}
+
+// loadRole sets the role of a service account, held by the user it acts as
+func (s *DefaultServiceAccountService) loadRole(ctx context.Context, account *models.ServiceAccount) error {
+	user, err := s.userRepo.GetUser(ctx, account.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get service account user: %w", err)
+	}
+	account.Role = user.Role
+
+	return nil
+}
+
+// DeleteServiceAccount deletes a service account, its tokens and the user it acts as
+func (s *DefaultServiceAccountService) DeleteServiceAccount(ctx context.Context, request models.DeleteServiceAccountRequest) error {
+	account, err := s.accountRepo.GetAccount(ctx, request.ServiceAccountID)
+	if err != nil {
+		return fmt.Errorf("failed to get service account: %w", err)
+	}
+
+	if err := s.accountRepo.DeleteAccount(ctx, account.ServiceAccountID); err != nil {
+		return fmt.Errorf("failed to delete service account: %w", err)
+	}
+	if err := s.userRepo.DeleteUser(ctx, account.UserID); err != nil && !errors.Is(err, apperrors.ErrNotFound) {
+		return fmt.Errorf("failed to delete service account user: %w", err)
+	}
+
+	return nil
+}
+
+// CreateToken issues a token to a service account, returned only this once
+func (s *DefaultServiceAccountService) CreateToken(ctx context.Context, request models.CreateServiceAccountTokenRequest) (*models.CreateServiceAccountTokenResponse, error) {
+	if _, err := s.accountRepo.GetAccount(ctx, request.ServiceAccountID); err != nil {
+		return nil, fmt.Errorf("failed to get service account: %w", err)
+	}
+
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return nil, fmt.Errorf("failed to generate service account token: %w", err)
+	}
+	secret := models.ServiceAccountTokenPrefix + base64.RawURLEncoding.EncodeToString(random)
+
+	now := s.now().UTC()
+	token := models.ServiceAccountToken{
+		TokenID:          "sat-" + uuid.New().String(),
+		ServiceAccountID: request.ServiceAccountID,
+		Name:             request.Name,
+		TokenHash:        hashServiceAccountToken(secret),
+		CreatedAt:        now,
+	}
+	if request.ExpiresInDays != nil {
+		expiresAt := now.AddDate(0, 0, *request.ExpiresInDays)
+		token.ExpiresAt = &expiresAt
+	}
+	if err := s.accountRepo.CreateToken(ctx, &token); err != nil {
+		return nil, fmt.Errorf("failed to create service account token: %w", err)
+	}
+
+	return &models.CreateServiceAccountTokenResponse{ServiceAccountToken: token, Token: secret}, nil
+}
+
+// ListTokens lists the tokens of a service account, newest first
+func (s *DefaultServiceAccountService) ListTokens(ctx context.Context, request models.ListServiceAccountTokensRequest) (*models.ListServiceAccountTokensResponse, error) {
+	if _, err := s.accountRepo.GetAccount(ctx, request.ServiceAccountID); err != nil {
+		return nil, fmt.Errorf("failed to get service account: %w", err)
+	}
+
+	tokens, err := s.accountRepo.ListTokens(ctx, request.ServiceAccountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service account tokens: %w", err)
+	}
+
+	return &models.ListServiceAccountTokensResponse{Tokens: tokens}, nil
+}
+
+// RevokeToken revokes a token of a service account
+func (s *DefaultServiceAccountService) RevokeToken(ctx context.Context, request models.RevokeServiceAccountTokenRequest) error {
+	if err := s.accountRepo.RevokeToken(ctx, request.ServiceAccountID, request.TokenID, s.now().UTC()); err != nil {
+		return fmt.Errorf("failed to revoke service account token: %w", err)
+	}
+
+	return nil
+}
+
+// ValidateToken validates a service account token that is neither revoked nor expired, returning the claims of the
+// user the service account acts as
+func (s *DefaultServiceAccountService) ValidateToken(ctx context.Context, secret string) (*auth.TokenClaims, error) {
+	if !strings.HasPrefix(secret, models.ServiceAccountTokenPrefix) {
+		return nil, apperrors.Unauthorized(apperrors.CodeInvalidToken, "not a service account token")
+	}
+
+	token, err := s.accountRepo.GetTokenByHash(ctx, hashServiceAccountToken(secret))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil, apperrors.Unauthorized(apperrors.CodeInvalidToken, "unknown service account token")
+		}
+		return nil, fmt.Errorf("failed to get service account token: %w", err)
+	}
+
+	now := s.now().UTC()
+	if token.RevokedAt != nil {
+		return nil, apperrors.Unauthorized(apperrors.CodeInvalidToken, "service account token %s was revoked", token.TokenID)
+	}
+	if token.ExpiresAt != nil && !now.Before(*token.ExpiresAt) {
+		return nil, apperrors.Unauthorized(apperrors.CodeInvalidToken, "service account token %s expired", token.TokenID)
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= serviceAccountTokenTouchInterval {
+		if err := s.accountRepo.TouchToken(ctx, token.TokenID, now); err != nil {
+			slog.WarnContext(ctx, "failed to record service account token use", "token_id", token.TokenID, "error", err)
+		}
+	}
+
+	claims := &auth.TokenClaims{
+		UserID:   models.ServiceAccountAuthIDPrefix + token.ServiceAccountID,
+		IssuedAt: token.CreatedAt,
+	}
+	if token.ExpiresAt != nil {
+		claims.ExpiresAt = *token.ExpiresAt
+	}
+
+	return claims, nil
+}
+
+// hashServiceAccountToken hashes a service account token, which is only stored hashed
+func hashServiceAccountToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+	serviceMocks "github.com/kazemisoroush/code-refactoring-tool/api/services/mocks"
+)
+
+func TestDefaultServiceAccountService_CreateServiceAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	accountRepo := repositoryMocks.NewMockServiceAccountRepository(ctrl)
+	userRepo := repositoryMocks.NewMockUserRepository(ctrl)
+	roleService := serviceMocks.NewMockRoleService(ctrl)
+	service := NewDefaultServiceAccountService(accountRepo, userRepo, roleService)
+
+	roleService.EXPECT().GetRole(gomock.Any(), models.RoleDeveloper).Return(&models.Role{Name: models.RoleDeveloper}, nil)
+	// The service account acts as a user with its role
+	userRepo.EXPECT().
+		CreateUser(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, user *models.DBUser) (*models.DBUser, error) {
+			assert.True(t, strings.HasPrefix(user.AuthID, models.ServiceAccountAuthIDPrefix+"sa-"))
+			assert.Equal(t, models.RoleDeveloper, user.Role)
+			assert.Equal(t, models.UserStatusActive, user.Status)
+			created := *user
+			created.UserID = "usr-1"
+			return &created, nil
+		})
+	accountRepo.EXPECT().
+		CreateAccount(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, account *models.ServiceAccount) error {
+			assert.Equal(t, "terraform", account.Name)
+			assert.Equal(t, "usr-1", account.UserID)
+			assert.Equal(t, "admin-1", account.CreatedBy)
+			return nil
+		})
+
+	account, err := service.CreateServiceAccount(context.Background(), models.CreateServiceAccountRequest{
+		Name:      "terraform",
+		Role:      models.RoleDeveloper,
+		CreatedBy: "admin-1",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleDeveloper, account.Role)
+}
+
+func TestDefaultServiceAccountService_CreateServiceAccount_DeletesUserOnConflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	accountRepo := repositoryMocks.NewMockServiceAccountRepository(ctrl)
+	userRepo := repositoryMocks.NewMockUserRepository(ctrl)
+	roleService := serviceMocks.NewMockRoleService(ctrl)
+	service := NewDefaultServiceAccountService(accountRepo, userRepo, roleService)
+
+	roleService.EXPECT().GetRole(gomock.Any(), models.RoleViewer).Return(&models.Role{Name: models.RoleViewer}, nil)
+	userRepo.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Return(&models.DBUser{UserID: "usr-1", Role: models.RoleViewer}, nil)
+	accountRepo.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).Return(apperrors.Conflict(apperrors.CodeConflict, "taken"))
+	userRepo.EXPECT().DeleteUser(gomock.Any(), "usr-1").Return(nil)
+
+	_, err := service.CreateServiceAccount(context.Background(), models.CreateServiceAccountRequest{Name: "terraform", Role: models.RoleViewer})
+
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+}
+
+func TestDefaultServiceAccountService_CreateServiceAccount_InvalidName(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewDefaultServiceAccountService(repositoryMocks.NewMockServiceAccountRepository(ctrl), repositoryMocks.NewMockUserRepository(ctrl), serviceMocks.NewMockRoleService(ctrl))
+
+	_, err := service.CreateServiceAccount(context.Background(), models.CreateServiceAccountRequest{Name: "Terraform Cloud", Role: models.RoleViewer})
+
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+}
+
+func TestDefaultServiceAccountService_CreateServiceAccount_CustomRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	accountRepo := repositoryMocks.NewMockServiceAccountRepository(ctrl)
+	userRepo := repositoryMocks.NewMockUserRepository(ctrl)
+	roleService := serviceMocks.NewMockRoleService(ctrl)
+	service := NewDefaultServiceAccountService(accountRepo, userRepo, roleService)
+
+	roleService.EXPECT().GetRole(gomock.Any(), models.UserRole("release-manager")).Return(&models.Role{Name: "release-manager"}, nil)
+	userRepo.EXPECT().
+		CreateUser(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, user *models.DBUser) (*models.DBUser, error) {
+			created := *user
+			created.UserID = "usr-1"
+			return &created, nil
+		})
+	accountRepo.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).Return(nil)
+
+	account, err := service.CreateServiceAccount(context.Background(), models.CreateServiceAccountRequest{Name: "release-bot", Role: "release-manager"})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.UserRole("release-manager"), account.Role)
+}
+
+func TestDefaultServiceAccountService_CreateServiceAccount_RejectsRole(t *testing.T) {
+	t.Run("owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := NewDefaultServiceAccountService(repositoryMocks.NewMockServiceAccountRepository(ctrl), repositoryMocks.NewMockUserRepository(ctrl), serviceMocks.NewMockRoleService(ctrl))
+
+		_, err := service.CreateServiceAccount(context.Background(), models.CreateServiceAccountRequest{Name: "terraform", Role: models.RoleOwner})
+
+		assert.ErrorIs(t, err, apperrors.ErrValidation)
+	})
+
+	t.Run("unknown role", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		roleService := serviceMocks.NewMockRoleService(ctrl)
+		service := NewDefaultServiceAccountService(repositoryMocks.NewMockServiceAccountRepository(ctrl), repositoryMocks.NewMockUserRepository(ctrl), roleService)
+
+		roleService.EXPECT().GetRole(gomock.Any(), models.UserRole("auditor")).Return(nil, apperrors.NotFound(apperrors.CodeRoleNotFound, "role not found: auditor"))
+
+		_, err := service.CreateServiceAccount(context.Background(), models.CreateServiceAccountRequest{Name: "terraform", Role: "auditor"})
+
+		assert.Equal(t, apperrors.CodeRoleNotFound, apperrors.CodeOf(err))
+	})
+}
+
+func TestDefaultServiceAccountService_CreateToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	accountRepo := repositoryMocks.NewMockServiceAccountRepository(ctrl)
+	service := NewDefaultServiceAccountService(accountRepo, repositoryMocks.NewMockUserRepository(ctrl), serviceMocks.NewMockRoleService(ctrl))
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	days := 30
+	var stored models.ServiceAccountToken
+	accountRepo.EXPECT().GetAccount(gomock.Any(), "sa-1").Return(&models.ServiceAccount{ServiceAccountID: "sa-1"}, nil)
+	accountRepo.EXPECT().
+		CreateToken(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, token *models.ServiceAccountToken) error {
+			stored = *token
+			return nil
+		})
+
+	response, err := service.CreateToken(context.Background(), models.CreateServiceAccountTokenRequest{
+		ServiceAccountID: "sa-1",
+		Name:             "ci",
+		ExpiresInDays:    &days,
+	})
+
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(response.Token, models.ServiceAccountTokenPrefix))
+	assert.Equal(t, now.AddDate(0, 0, 30), *response.ExpiresAt)
+
+	// Only the hash of the token is stored
+	assert.NotContains(t, stored.TokenHash, response.Token)
+	assert.Equal(t, hashServiceAccountToken(response.Token), stored.TokenHash)
+}
+
+func TestDefaultServiceAccountService_ValidateToken(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	secret := models.ServiceAccountTokenPrefix + "secret"
+	past := now.Add(-time.Hour)
+	recent := now.Add(-time.Second)
+
+	tests := []struct {
+		name    string
+		token   *models.ServiceAccountToken
+		err     error
+		touch   bool
+		wantErr bool
+	}{
+		{name: "valid", token: &models.ServiceAccountToken{TokenID: "sat-1", ServiceAccountID: "sa-1"}, touch: true},
+		{name: "recently used", token: &models.ServiceAccountToken{TokenID: "sat-1", ServiceAccountID: "sa-1", LastUsedAt: &recent}},
+		{name: "revoked", token: &models.ServiceAccountToken{TokenID: "sat-1", RevokedAt: &past}, wantErr: true},
+		{name: "expired", token: &models.ServiceAccountToken{TokenID: "sat-1", ExpiresAt: &past}, wantErr: true},
+		{name: "unknown", err: apperrors.NotFound(apperrors.CodeServiceTokenNotFound, "not found"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			accountRepo := repositoryMocks.NewMockServiceAccountRepository(ctrl)
+			service := NewDefaultServiceAccountService(accountRepo, repositoryMocks.NewMockUserRepository(ctrl), serviceMocks.NewMockRoleService(ctrl))
+			service.now = func() time.Time { return now }
+
+			accountRepo.EXPECT().GetTokenByHash(gomock.Any(), hashServiceAccountToken(secret)).Return(tt.token, tt.err)
+			if tt.touch {
+				accountRepo.EXPECT().TouchToken(gomock.Any(), "sat-1", now).Return(errors.New("ignored"))
+			}
+
+			claims, err := service.ValidateToken(context.Background(), secret)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, apperrors.ErrUnauthorized)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, models.ServiceAccountAuthIDPrefix+"sa-1", claims.UserID)
+		})
+	}
+}
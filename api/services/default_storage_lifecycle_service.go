@@ -0,0 +1,281 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/storage"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+const (
+	// storagePurgeBatchSize bounds the purges claimed at once
+	storagePurgeBatchSize = 10
+
+	// maxListedStoragePurges bounds the purges listed at once
+	maxListedStoragePurges = 100
+)
+
+// storageLocation is a bucket and the region it's accessed in
+type storageLocation struct {
+	region string
+	bucket string
+}
+
+// DataStoreProvider returns the data store of the content uploaded to a bucket in a region
+type DataStoreProvider func(region, bucketName string) storage.DataStore
+
+// DefaultStorageLifecycleService is the default implementation of StorageLifecycleService
+type DefaultStorageLifecycleService struct {
+	purgeRepo  repository.StoragePurgeRepository
+	agentRepo  repository.AgentRepository
+	dataStores DataStoreProvider
+	bedrock    config.BedrockAIConfig
+	config     config.StorageLifecycleConfig
+	now        func() time.Time
+}
+
+// NewDefaultStorageLifecycleService creates a new DefaultStorageLifecycleService. The buckets of the Bedrock
+// configuration are the ones purged and reconciled.
+func NewDefaultStorageLifecycleService(
+	purgeRepo repository.StoragePurgeRepository,
+	agentRepo repository.AgentRepository,
+	dataStores DataStoreProvider,
+	bedrock config.BedrockAIConfig,
+	config config.StorageLifecycleConfig,
+) *DefaultStorageLifecycleService {
+	return &DefaultStorageLifecycleService{
+		purgeRepo:  purgeRepo,
+		agentRepo:  agentRepo,
+		dataStores: dataStores,
+		bedrock:    bedrock,
+		config:     config,
+		now:        time.Now,
+	}
+}
+
+// PurgeAgentContent records a purge of the content uploaded for the knowledge base of an agent, run in the background.
+// Only Bedrock agents upload content to S3.
+func (s *DefaultStorageLifecycleService) PurgeAgentContent(ctx context.Context, agent *repository.AgentRecord) (*models.StoragePurge, error) {
+	if agent.GetAIProvider() != models.AIProviderBedrock || agent.KnowledgeBaseID == "" {
+		return nil, nil
+	}
+
+	region := agent.Region
+	if region == "" {
+		region = s.bedrock.Region
+	}
+	bucket := s.bedrock.BucketName(region)
+	if bucket == "" {
+		return nil, nil
+	}
+
+	now := s.now().UTC()
+	purge := &models.StoragePurge{
+		PurgeID:    "purge-" + uuid.New().String(),
+		EntityType: models.StoragePurgeEntityAgent,
+		EntityID:   agent.AgentID,
+		Region:     region,
+		Bucket:     bucket,
+		Prefix:     storage.KnowledgeBasePrefix(agent.KnowledgeBaseID),
+		Status:     models.StoragePurgeStatusPending,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := s.purgeRepo.CreatePurge(ctx, purge); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "Scheduled storage purge", "purge_id", purge.PurgeID, "agent_id", agent.AgentID,
+		"bucket", bucket, "prefix", purge.Prefix)
+	return purge, nil
+}
+
+// GetPurge gets a purge with its progress
+func (s *DefaultStorageLifecycleService) GetPurge(ctx context.Context, request models.GetStoragePurgeRequest) (*models.StoragePurge, error) {
+	return s.purgeRepo.GetPurge(ctx, request.PurgeID)
+}
+
+// ListPurges lists the most recent purges, newest first
+func (s *DefaultStorageLifecycleService) ListPurges(ctx context.Context, request models.ListStoragePurgesRequest) (*models.ListStoragePurgesResponse, error) {
+	purges, err := s.purgeRepo.ListPurges(ctx, models.StoragePurgeStatus(request.Status), maxListedStoragePurges)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ListStoragePurgesResponse{Purges: purges}, nil
+}
+
+// RunPurgeQueue runs the due purges every interval until ctx is cancelled
+func (s *DefaultStorageLifecycleService) RunPurgeQueue(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.RunPurges(ctx); err != nil {
+				slog.ErrorContext(ctx, "failed to run storage purges", "error", err)
+			}
+		}
+	}
+}
+
+// RunPurges claims the due purges and deletes their content, returning how many completed. A failed purge is retried
+// once its lease expires, until it runs out of attempts.
+func (s *DefaultStorageLifecycleService) RunPurges(ctx context.Context) (int, error) {
+	purges, err := s.purgeRepo.ClaimDue(ctx, storagePurgeBatchSize, s.config.PurgeLease)
+	if err != nil {
+		return 0, err
+	}
+
+	completed := 0
+	for i := range purges {
+		if s.runPurge(ctx, &purges[i]) {
+			completed++
+		}
+	}
+
+	return completed, nil
+}
+
+// runPurge deletes the content of a claimed purge, recording its progress after every batch of deleted objects so a
+// long purge keeps its lease. It reports whether the purge completed.
+func (s *DefaultStorageLifecycleService) runPurge(ctx context.Context, purge *models.StoragePurge) bool {
+	dataStore := s.dataStores(purge.Region, purge.Bucket)
+	err := dataStore.DeleteDirectory(ctx, purge.Prefix, func(deleted int) {
+		purge.ObjectsDeleted += int64(deleted)
+		purge.UpdatedAt = s.now().UTC()
+		if err := s.purgeRepo.UpdatePurge(ctx, purge, purge.UpdatedAt.Add(s.config.PurgeLease)); err != nil {
+			slog.WarnContext(ctx, "failed to record storage purge progress", "purge_id", purge.PurgeID, "error", err)
+		}
+	})
+
+	now := s.now().UTC()
+	purge.UpdatedAt = now
+	leaseUntil := now
+	switch {
+	case err == nil:
+		purge.Status = models.StoragePurgeStatusCompleted
+		purge.LastError = ""
+		purge.CompletedAt = &now
+		slog.InfoContext(ctx, "Storage purge completed", "purge_id", purge.PurgeID, "objects_deleted", purge.ObjectsDeleted)
+	case purge.Attempts >= s.config.MaxPurgeAttempts:
+		purge.Status = models.StoragePurgeStatusFailed
+		purge.LastError = err.Error()
+		purge.CompletedAt = &now
+		slog.ErrorContext(ctx, "Storage purge failed", "purge_id", purge.PurgeID, "attempts", purge.Attempts, "error", err)
+	default:
+		purge.LastError = err.Error()
+		leaseUntil = now.Add(s.config.PurgeLease)
+		slog.WarnContext(ctx, "Storage purge attempt failed, retrying after the lease", "purge_id", purge.PurgeID,
+			"attempts", purge.Attempts, "error", err)
+	}
+
+	if err := s.purgeRepo.UpdatePurge(ctx, purge, leaseUntil); err != nil {
+		slog.ErrorContext(ctx, "failed to record storage purge outcome", "purge_id", purge.PurgeID, "error", err)
+		return false
+	}
+	return purge.Status == models.StoragePurgeStatusCompleted
+}
+
+// ListOrphans lists the knowledge base prefixes of the buckets of every allowed region that no agent owns and no purge
+// is deleting. They are left behind by setups that failed before saving their agent, and by agents deleted before
+// their content was purged.
+func (s *DefaultStorageLifecycleService) ListOrphans(ctx context.Context) (*models.ListStorageOrphansResponse, error) {
+	checkedAt := s.now().UTC()
+
+	agents, err := s.agentRepo.ListAgents(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agents: %w", err)
+	}
+	owned := make(map[string]bool, len(agents))
+	for _, agent := range agents {
+		if agent.KnowledgeBaseID != "" {
+			owned[storage.KnowledgeBasePrefix(agent.KnowledgeBaseID)] = true
+		}
+	}
+
+	purging := make(map[string]bool)
+	for _, status := range []models.StoragePurgeStatus{models.StoragePurgeStatusPending, models.StoragePurgeStatusRunning} {
+		purges, err := s.purgeRepo.ListPurges(ctx, status, maxListedStoragePurges)
+		if err != nil {
+			return nil, err
+		}
+		for _, purge := range purges {
+			purging[purge.Bucket+"/"+purge.Prefix] = true
+		}
+	}
+
+	response := &models.ListStorageOrphansResponse{Orphans: []models.StorageOrphan{}, CheckedAt: checkedAt}
+	for _, location := range s.buckets() {
+		prefixes, err := s.dataStores(location.region, location.bucket).ListDirectories(ctx, storage.KnowledgeBasesPrefix)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list knowledge base prefixes of bucket %s: %w", location.bucket, err)
+		}
+		for _, prefix := range prefixes {
+			switch {
+			case owned[prefix]:
+			case purging[location.bucket+"/"+prefix]:
+				response.Purging++
+			default:
+				response.Orphans = append(response.Orphans, models.StorageOrphan{Region: location.region, Bucket: location.bucket, Prefix: prefix})
+			}
+		}
+	}
+
+	sort.Slice(response.Orphans, func(i, j int) bool {
+		if response.Orphans[i].Bucket != response.Orphans[j].Bucket {
+			return response.Orphans[i].Bucket < response.Orphans[j].Bucket
+		}
+		return response.Orphans[i].Prefix < response.Orphans[j].Prefix
+	})
+	return response, nil
+}
+
+// RunReconciliation logs the orphaned prefixes every interval until ctx is cancelled
+func (s *DefaultStorageLifecycleService) RunReconciliation(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := s.ListOrphans(ctx)
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to reconcile storage", "error", err)
+				continue
+			}
+			for _, orphan := range report.Orphans {
+				slog.WarnContext(ctx, "found orphaned storage prefix", "region", orphan.Region, "bucket", orphan.Bucket, "prefix", orphan.Prefix)
+			}
+			slog.InfoContext(ctx, "Reconciled storage", "orphans", len(report.Orphans), "purging", report.Purging)
+		}
+	}
+}
+
+// buckets returns the buckets of the regions agents may be provisioned in, each once, listed in the first region
+// using it. Regions without a bucket are skipped.
+func (s *DefaultStorageLifecycleService) buckets() []storageLocation {
+	var locations []storageLocation
+	seen := make(map[string]bool)
+	for _, region := range append([]string{s.bedrock.Region}, s.bedrock.AllowedRegions...) {
+		bucket := s.bedrock.BucketName(region)
+		if bucket == "" || seen[bucket] {
+			continue
+		}
+		seen[bucket] = true
+		locations = append(locations, storageLocation{region: region, bucket: bucket})
+	}
+	return locations
+}
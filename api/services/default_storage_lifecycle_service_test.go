@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	repoMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/storage"
+	storageMocks "github.com/kazemisoroush/code-refactoring-tool/pkg/ai/storage/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// newTestStorageLifecycleService creates a storage lifecycle service over a bucket in us-east-1 and its eu-west-1
+// replica, whose data stores are dataStore
+func newTestStorageLifecycleService(purgeRepo repository.StoragePurgeRepository, agentRepo repository.AgentRepository, dataStore storage.DataStore) *DefaultStorageLifecycleService {
+	bedrock := config.BedrockAIConfig{
+		Region:         "us-east-1",
+		S3BucketName:   "content",
+		AllowedRegions: []string{"eu-west-1", "ap-southeast-2"},
+		S3BucketNames:  map[string]string{"eu-west-1": "content-eu"},
+	}
+	lifecycle := config.StorageLifecycleConfig{PurgeLease: time.Minute, MaxPurgeAttempts: 3}
+	return NewDefaultStorageLifecycleService(purgeRepo, agentRepo, func(string, string) storage.DataStore { return dataStore }, bedrock, lifecycle)
+}
+
+func TestDefaultStorageLifecycleService_PurgeAgentContent(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	purgeRepo := repoMocks.NewMockStoragePurgeRepository(ctrl)
+	service := newTestStorageLifecycleService(purgeRepo, repoMocks.NewMockAgentRepository(ctrl), nil)
+
+	var created *models.StoragePurge
+	purgeRepo.EXPECT().CreatePurge(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, purge *models.StoragePurge) error {
+		created = purge
+		return nil
+	})
+
+	// Act
+	purge, err := service.PurgeAgentContent(context.Background(), &repository.AgentRecord{
+		AgentID: "agent-1", KnowledgeBaseID: "KB1", AIProvider: "bedrock", Region: "eu-west-1",
+	})
+	localPurge, localErr := service.PurgeAgentContent(context.Background(), &repository.AgentRecord{
+		AgentID: "agent-2", KnowledgeBaseID: "local-kb", AIProvider: "local",
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Same(t, created, purge)
+	assert.Equal(t, "agent-1", purge.EntityID)
+	assert.Equal(t, "content-eu", purge.Bucket)
+	assert.Equal(t, "knowledge-bases/KB1/", purge.Prefix)
+	assert.Equal(t, models.StoragePurgeStatusPending, purge.Status)
+	require.NoError(t, localErr)
+	assert.Nil(t, localPurge)
+}
+
+func TestDefaultStorageLifecycleService_RunPurges_RecordsProgress(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	purgeRepo := repoMocks.NewMockStoragePurgeRepository(ctrl)
+	dataStore := storageMocks.NewMockDataStore(ctrl)
+	service := newTestStorageLifecycleService(purgeRepo, repoMocks.NewMockAgentRepository(ctrl), dataStore)
+
+	purgeRepo.EXPECT().ClaimDue(gomock.Any(), storagePurgeBatchSize, time.Minute).Return([]models.StoragePurge{
+		{PurgeID: "purge-1", Region: "us-east-1", Bucket: "content", Prefix: "knowledge-bases/KB1/", Status: models.StoragePurgeStatusRunning, Attempts: 1},
+	}, nil)
+	dataStore.EXPECT().DeleteDirectory(gomock.Any(), "knowledge-bases/KB1/", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, progress func(int)) error {
+			progress(1000)
+			progress(200)
+			return nil
+		})
+
+	var recorded []int64
+	var final models.StoragePurge
+	purgeRepo.EXPECT().UpdatePurge(gomock.Any(), gomock.Any(), gomock.Any()).Times(3).
+		DoAndReturn(func(_ context.Context, purge *models.StoragePurge, _ time.Time) error {
+			recorded = append(recorded, purge.ObjectsDeleted)
+			final = *purge
+			return nil
+		})
+
+	// Act
+	completed, err := service.RunPurges(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 1, completed)
+	assert.Equal(t, []int64{1000, 1200, 1200}, recorded)
+	assert.Equal(t, models.StoragePurgeStatusCompleted, final.Status)
+	assert.NotNil(t, final.CompletedAt)
+}
+
+func TestDefaultStorageLifecycleService_RunPurges_FailsAfterLastAttempt(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	purgeRepo := repoMocks.NewMockStoragePurgeRepository(ctrl)
+	dataStore := storageMocks.NewMockDataStore(ctrl)
+	service := newTestStorageLifecycleService(purgeRepo, repoMocks.NewMockAgentRepository(ctrl), dataStore)
+
+	purgeRepo.EXPECT().ClaimDue(gomock.Any(), storagePurgeBatchSize, time.Minute).Return([]models.StoragePurge{
+		{PurgeID: "purge-1", Prefix: "knowledge-bases/KB1/", Status: models.StoragePurgeStatusRunning, Attempts: 1},
+		{PurgeID: "purge-2", Prefix: "knowledge-bases/KB2/", Status: models.StoragePurgeStatusRunning, Attempts: 3},
+	}, nil)
+	dataStore.EXPECT().DeleteDirectory(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("AccessDenied")).Times(2)
+
+	outcomes := map[string]models.StoragePurge{}
+	purgeRepo.EXPECT().UpdatePurge(gomock.Any(), gomock.Any(), gomock.Any()).Times(2).
+		DoAndReturn(func(_ context.Context, purge *models.StoragePurge, _ time.Time) error {
+			outcomes[purge.PurgeID] = *purge
+			return nil
+		})
+
+	// Act
+	completed, err := service.RunPurges(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Zero(t, completed)
+	assert.Equal(t, models.StoragePurgeStatusRunning, outcomes["purge-1"].Status)
+	assert.Nil(t, outcomes["purge-1"].CompletedAt)
+	assert.Equal(t, models.StoragePurgeStatusFailed, outcomes["purge-2"].Status)
+	assert.Equal(t, "AccessDenied", outcomes["purge-2"].LastError)
+}
+
+func TestDefaultStorageLifecycleService_ListOrphans(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	purgeRepo := repoMocks.NewMockStoragePurgeRepository(ctrl)
+	agentRepo := repoMocks.NewMockAgentRepository(ctrl)
+	dataStore := storageMocks.NewMockDataStore(ctrl)
+	service := newTestStorageLifecycleService(purgeRepo, agentRepo, dataStore)
+
+	agentRepo.EXPECT().ListAgents(gomock.Any()).Return([]*repository.AgentRecord{{AgentID: "agent-1", KnowledgeBaseID: "KB1"}}, nil)
+	purgeRepo.EXPECT().ListPurges(gomock.Any(), models.StoragePurgeStatusPending, maxListedStoragePurges).
+		Return([]models.StoragePurge{{PurgeID: "purge-1", Bucket: "content", Prefix: "knowledge-bases/KB2/"}}, nil)
+	purgeRepo.EXPECT().ListPurges(gomock.Any(), models.StoragePurgeStatusRunning, maxListedStoragePurges).Return([]models.StoragePurge{}, nil)
+
+	// ap-southeast-2 shares the bucket of us-east-1, which is listed once
+	dataStore.EXPECT().ListDirectories(gomock.Any(), "knowledge-bases/").
+		Return([]string{"knowledge-bases/KB1/", "knowledge-bases/KB2/", "knowledge-bases/KB3/"}, nil)
+	dataStore.EXPECT().ListDirectories(gomock.Any(), "knowledge-bases/").Return([]string{"knowledge-bases/KB2/"}, nil)
+
+	// Act
+	report, err := service.ListOrphans(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []models.StorageOrphan{
+		{Region: "us-east-1", Bucket: "content", Prefix: "knowledge-bases/KB3/"},
+		{Region: "eu-west-1", Bucket: "content-eu", Prefix: "knowledge-bases/KB2/"},
+	}, report.Orphans)
+	assert.Equal(t, 1, report.Purging)
+}
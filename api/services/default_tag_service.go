@@ -0,0 +1,227 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+)
+
+// DefaultTagService is the default implementation of TagService
+type DefaultTagService struct {
+	tagKeyRepo            repository.TagKeyRepository
+	userRepo              repository.UserRepository
+	requireRegisteredKeys bool
+}
+
+// NewDefaultTagService creates a new DefaultTagService. When requireRegisteredKeys is set, tags whose key isn't
+// registered are rejected.
+func NewDefaultTagService(tagKeyRepo repository.TagKeyRepository, userRepo repository.UserRepository, requireRegisteredKeys bool) *DefaultTagService {
+	return &DefaultTagService{
+		tagKeyRepo:            tagKeyRepo,
+		userRepo:              userRepo,
+		requireRegisteredKeys: requireRegisteredKeys,
+	}
+}
+
+// CreateTagKey registers a tag key
+func (s *DefaultTagService) CreateTagKey(ctx context.Context, request models.CreateTagKeyRequest) (*models.TagKey, error) {
+	now := time.Now().UTC()
+	tagKey := &models.TagKey{
+		Key:           request.Key,
+		Description:   request.Description,
+		AllowedValues: request.AllowedValues,
+		ValuePattern:  request.ValuePattern,
+		EditorRoles:   request.EditorRoles,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if _, err := compileValuePattern(tagKey.ValuePattern); err != nil {
+		return nil, err
+	}
+
+	if err := s.tagKeyRepo.CreateTagKey(ctx, tagKey); err != nil {
+		return nil, err
+	}
+
+	return tagKey, nil
+}
+
+// GetTagKey retrieves a registered tag key
+func (s *DefaultTagService) GetTagKey(ctx context.Context, key string) (*models.TagKey, error) {
+	tagKey, err := s.tagKeyRepo.GetTagKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if tagKey == nil {
+		return nil, apperrors.NotFound(apperrors.CodeTagKeyNotFound, "tag key not found: %s", key)
+	}
+
+	return tagKey, nil
+}
+
+// UpdateTagKey replaces the rules of a registered tag key
+func (s *DefaultTagService) UpdateTagKey(ctx context.Context, request models.UpdateTagKeyRequest) (*models.TagKey, error) {
+	tagKey, err := s.GetTagKey(ctx, request.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	tagKey.Description = request.Description
+	tagKey.AllowedValues = request.AllowedValues
+	tagKey.ValuePattern = request.ValuePattern
+	tagKey.EditorRoles = request.EditorRoles
+	tagKey.UpdatedAt = time.Now().UTC()
+	if _, err := compileValuePattern(tagKey.ValuePattern); err != nil {
+		return nil, err
+	}
+
+	if err := s.tagKeyRepo.UpdateTagKey(ctx, tagKey); err != nil {
+		return nil, err
+	}
+
+	return tagKey, nil
+}
+
+// DeleteTagKey unregisters a tag key
+func (s *DefaultTagService) DeleteTagKey(ctx context.Context, key string) error {
+	return s.tagKeyRepo.DeleteTagKey(ctx, key)
+}
+
+// ListTagKeys lists the registered tag keys
+func (s *DefaultTagService) ListTagKeys(ctx context.Context) (*models.ListTagKeysResponse, error) {
+	tagKeys, err := s.tagKeyRepo.ListTagKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ListTagKeysResponse{
+		TagKeys:               tagKeys,
+		RequireRegisteredKeys: s.requireRegisteredKeys,
+	}, nil
+}
+
+// AuthorizeTags validates the values of the tags added or changed, and checks that the user holds one of the editor
+// roles of every restricted tag added, changed or removed
+func (s *DefaultTagService) AuthorizeTags(ctx context.Context, userID string, current, updated map[string]string) error {
+	changed := changedTagKeys(current, updated)
+	if len(changed) == 0 {
+		return nil
+	}
+
+	tagKeys, err := s.tagKeyRepo.ListTagKeys(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list tag keys: %w", err)
+	}
+	registered := make(map[string]models.TagKey, len(tagKeys))
+	for _, tagKey := range tagKeys {
+		registered[tagKey.Key] = tagKey
+	}
+
+	var user *models.DBUser
+	for _, key := range changed {
+		value, set := updated[key]
+		tagKey, ok := registered[key]
+		if !ok {
+			if set && s.requireRegisteredKeys {
+				return apperrors.Validation(apperrors.CodeInvalidTag, "tag key %s is not registered", key)
+			}
+			continue
+		}
+
+		if set {
+			if err := validateTagValue(tagKey, value); err != nil {
+				return err
+			}
+		}
+
+		if len(tagKey.EditorRoles) > 0 {
+			if user == nil {
+				if user, err = s.lookupUser(ctx, userID, key); err != nil {
+					return err
+				}
+			}
+			if !slices.Contains(tagKey.EditorRoles, user.Role) {
+				return apperrors.Forbidden(apperrors.CodeTagRestricted, "tag %s can only be modified by %v", key, tagKey.EditorRoles)
+			}
+		}
+	}
+
+	return nil
+}
+
+// lookupUser looks up the active user modifying the restricted tag key
+func (s *DefaultTagService) lookupUser(ctx context.Context, userID, key string) (*models.DBUser, error) {
+	if userID == "" {
+		return nil, apperrors.Forbidden(apperrors.CodeTagRestricted, "tag %s can only be modified by an authenticated user", key)
+	}
+
+	user, err := s.userRepo.GetUserByAuthID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil, apperrors.Forbidden(apperrors.CodeTagRestricted, "tag %s can only be modified by a user with an account", key)
+		}
+		return nil, fmt.Errorf("failed to look up user: %w", err)
+	}
+	if user.Status != models.UserStatusActive {
+		return nil, apperrors.Forbidden(apperrors.CodeTagRestricted, "tag %s can only be modified by an active user", key)
+	}
+
+	return user, nil
+}
+
+// changedTagKeys returns the sorted keys of the tags added, changed or removed by replacing current with updated
+func changedTagKeys(current, updated map[string]string) []string {
+	var changed []string
+	for key, value := range updated {
+		if previous, ok := current[key]; !ok || previous != value {
+			changed = append(changed, key)
+		}
+	}
+	for key := range current {
+		if _, ok := updated[key]; !ok {
+			changed = append(changed, key)
+		}
+	}
+
+	slices.Sort(changed)
+	return changed
+}
+
+// validateTagValue checks a value against the allowed values and value pattern of its tag key
+func validateTagValue(tagKey models.TagKey, value string) error {
+	if len(tagKey.AllowedValues) > 0 && !slices.Contains(tagKey.AllowedValues, value) {
+		return apperrors.Validation(apperrors.CodeInvalidTag, "tag %s must be one of %v, got %q", tagKey.Key, tagKey.AllowedValues, value)
+	}
+
+	pattern, err := compileValuePattern(tagKey.ValuePattern)
+	if err != nil {
+		return err
+	}
+	if pattern != nil && !pattern.MatchString(value) {
+		return apperrors.Validation(apperrors.CodeInvalidTag, "tag %s must match %s, got %q", tagKey.Key, tagKey.ValuePattern, value)
+	}
+
+	return nil
+}
+
+// compileValuePattern compiles a value pattern matching whole values, returning nil for an empty pattern
+func compileValuePattern(valuePattern string) (*regexp.Regexp, error) {
+	if valuePattern == "" {
+		return nil, nil
+	}
+
+	pattern, err := regexp.Compile("^(?:" + valuePattern + ")$")
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrValidation, apperrors.CodeInvalidTagKey, err, "invalid value pattern %q", valuePattern)
+	}
+
+	return pattern, nil
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+)
+
+// registeredTagKeys are a cost center restricted to owners and admins and an environment with a fixed set of values
+var registeredTagKeys = []models.TagKey{
+	{Key: "cost-center", ValuePattern: "cc-[0-9]+", EditorRoles: []models.UserRole{models.RoleOwner, models.RoleAdmin}},
+	{Key: "env", AllowedValues: []string{"dev", "staging", "prod"}},
+}
+
+func TestDefaultTagService_AuthorizeTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  map[string]string
+		updated  map[string]string
+		role     models.UserRole
+		require  bool
+		wantKind error
+	}{
+		{name: "allowed value", updated: map[string]string{"env": "prod"}},
+		{name: "value not allowed", updated: map[string]string{"env": "qa"}, wantKind: apperrors.ErrValidation},
+		{name: "unregistered key", updated: map[string]string{"team": "payments"}},
+		{name: "unregistered key rejected", updated: map[string]string{"team": "payments"}, require: true, wantKind: apperrors.ErrValidation},
+		{name: "restricted tag set by admin", updated: map[string]string{"cost-center": "cc-100"}, role: models.RoleAdmin},
+		{name: "restricted tag set by developer", updated: map[string]string{"cost-center": "cc-100"}, role: models.RoleDeveloper, wantKind: apperrors.ErrForbidden},
+		{name: "restricted tag removed by developer", current: map[string]string{"cost-center": "cc-100"}, updated: map[string]string{}, role: models.RoleDeveloper, wantKind: apperrors.ErrForbidden},
+		{name: "value not matching pattern", updated: map[string]string{"cost-center": "marketing"}, role: models.RoleAdmin, wantKind: apperrors.ErrValidation},
+		// Tags held before a rule was registered are kept while other tags change
+		{name: "unchanged tags not checked", current: map[string]string{"cost-center": "legacy", "env": "qa"}, updated: map[string]string{"cost-center": "legacy", "env": "qa", "team": "payments"}, role: models.RoleDeveloper},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			tagKeyRepo := repositoryMocks.NewMockTagKeyRepository(ctrl)
+			userRepo := repositoryMocks.NewMockUserRepository(ctrl)
+			service := NewDefaultTagService(tagKeyRepo, userRepo, tt.require)
+
+			tagKeyRepo.EXPECT().ListTagKeys(gomock.Any()).Return(registeredTagKeys, nil).AnyTimes()
+			userRepo.EXPECT().GetUserByAuthID(gomock.Any(), "user-1").Return(&models.DBUser{Role: tt.role, Status: models.UserStatusActive}, nil).AnyTimes()
+
+			err := service.AuthorizeTags(context.Background(), "user-1", tt.current, tt.updated)
+
+			if tt.wantKind == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantKind)
+			}
+		})
+	}
+}
+
+func TestDefaultTagService_AuthorizeTags_RestrictedTagWithoutCaller(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tagKeyRepo := repositoryMocks.NewMockTagKeyRepository(ctrl)
+	service := NewDefaultTagService(tagKeyRepo, repositoryMocks.NewMockUserRepository(ctrl), false)
+
+	tagKeyRepo.EXPECT().ListTagKeys(gomock.Any()).Return(registeredTagKeys, nil)
+
+	err := service.AuthorizeTags(context.Background(), "", nil, map[string]string{"cost-center": "cc-100"})
+
+	assert.ErrorIs(t, err, apperrors.ErrForbidden)
+	assert.Equal(t, apperrors.CodeTagRestricted, apperrors.CodeOf(err))
+}
+
+func TestDefaultTagService_CreateTagKey_InvalidPattern(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewDefaultTagService(repositoryMocks.NewMockTagKeyRepository(ctrl), repositoryMocks.NewMockUserRepository(ctrl), false)
+
+	_, err := service.CreateTagKey(context.Background(), models.CreateTagKeyRequest{Key: "cost-center", ValuePattern: "cc-[0-9"})
+
+	require.Error(t, err)
+	assert.Equal(t, apperrors.CodeInvalidTagKey, apperrors.CodeOf(err))
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+)
+
+// DefaultTaskCommentService is the default implementation of TaskCommentService
+type DefaultTaskCommentService struct {
+	commentRepo repository.TaskCommentRepository
+	taskRepo    repository.TaskRepository
+}
+
+// NewDefaultTaskCommentService creates a new DefaultTaskCommentService
+func NewDefaultTaskCommentService(commentRepo repository.TaskCommentRepository, taskRepo repository.TaskRepository) *DefaultTaskCommentService {
+	return &DefaultTaskCommentService{
+		commentRepo: commentRepo,
+		taskRepo:    taskRepo,
+	}
+}
+
+// CreateComment comments on a task. Threads are one level deep: a reply to a reply joins the thread of the
+// comment that started it, and only thread-starting comments may be anchored to the diff.
+func (s *DefaultTaskCommentService) CreateComment(ctx context.Context, request models.CreateTaskCommentRequest) (*models.TaskComment, error) {
+	if _, err := s.taskRepo.GetByID(ctx, request.TaskID); err != nil {
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+
+	now := time.Now()
+	comment := &models.TaskComment{
+		CommentID: fmt.Sprintf("comment-%s", uuid.New().String()),
+		TaskID:    request.TaskID,
+		AuthorID:  request.AuthorID,
+		Body:      request.Body,
+		Anchor:    request.Anchor,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if request.ParentID != nil {
+		if request.Anchor != nil {
+			return nil, apperrors.Validation(apperrors.CodeInvalidRequest, "replies can't be anchored; the thread's first comment holds the anchor")
+		}
+		parent, err := s.getTaskComment(ctx, request.TaskID, *request.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		threadID := parent.CommentID
+		if parent.ParentID != nil {
+			threadID = *parent.ParentID
+		}
+		comment.ParentID = &threadID
+	}
+
+	if comment.Anchor != nil && comment.Anchor.EndLine == 0 {
+		comment.Anchor.EndLine = comment.Anchor.StartLine
+	}
+
+	if err := s.commentRepo.CreateComment(ctx, comment); err != nil {
+		return nil, fmt.Errorf("failed to create task comment: %w", err)
+	}
+
+	return comment, nil
+}
+
+// ListComments lists a task's comments oldest first
+func (s *DefaultTaskCommentService) ListComments(ctx context.Context, request models.ListTaskCommentsRequest) (*models.ListTaskCommentsResponse, error) {
+	if _, err := s.taskRepo.GetByID(ctx, request.TaskID); err != nil {
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+
+	comments, err := s.commentRepo.ListComments(ctx, request.TaskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list task comments: %w", err)
+	}
+
+	return &models.ListTaskCommentsResponse{Comments: comments}, nil
+}
+
+// UpdateComment edits the body of one of the caller's comments
+func (s *DefaultTaskCommentService) UpdateComment(ctx context.Context, request models.UpdateTaskCommentRequest) (*models.TaskComment, error) {
+	comment, err := s.getAuthoredComment(ctx, request.TaskID, request.CommentID, request.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+
+	comment.Body = request.Body
+	comment.UpdatedAt = time.Now()
+
+	if err := s.commentRepo.UpdateComment(ctx, comment); err != nil {
+		return nil, fmt.Errorf("failed to update task comment: %w", err)
+	}
+
+	return comment, nil
+}
+
+// DeleteComment deletes one of the caller's comments along with its replies
+func (s *DefaultTaskCommentService) DeleteComment(ctx context.Context, request models.DeleteTaskCommentRequest) (*models.DeleteTaskCommentResponse, error) {
+	if _, err := s.getAuthoredComment(ctx, request.TaskID, request.CommentID, request.AuthorID); err != nil {
+		return nil, err
+	}
+
+	if err := s.commentRepo.DeleteComment(ctx, request.CommentID); err != nil {
+		return nil, fmt.Errorf("failed to delete task comment: %w", err)
+	}
+
+	return &models.DeleteTaskCommentResponse{Success: true}, nil
+}
+
+// getTaskComment retrieves a comment, reporting comments of other tasks as not found
+func (s *DefaultTaskCommentService) getTaskComment(ctx context.Context, taskID, commentID string) (*models.TaskComment, error) {
+	comment, err := s.commentRepo.GetComment(ctx, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task comment: %w", err)
+	}
+	if comment.TaskID != taskID {
+		return nil, apperrors.NotFound(apperrors.CodeCommentNotFound, "task comment not found: %s", commentID)
+	}
+
+	return comment, nil
+}
+
+// getAuthoredComment retrieves a comment of the task that the caller wrote
+func (s *DefaultTaskCommentService) getAuthoredComment(ctx context.Context, taskID, commentID, authorID string) (*models.TaskComment, error) {
+	comment, err := s.getTaskComment(ctx, taskID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.AuthorID != authorID {
+		return nil, apperrors.Forbidden(apperrors.CodeForbidden, "only the author can change comment %s", commentID)
+	}
+
+	return comment, nil
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+)
+
+func TestDefaultTaskCommentService_CreateComment(t *testing.T) {
+	rootID := "comment-root"
+	replyID := "comment-reply"
+
+	tests := []struct {
+		name             string
+		request          models.CreateTaskCommentRequest
+		parent           *models.TaskComment
+		expectedParentID *string
+		expectedEndLine  int
+		expectedErr      error
+	}{
+		{
+			name: "anchored_thread",
+			request: models.CreateTaskCommentRequest{
+				TaskID: "task-1", Body: "Why?", AuthorID: "user-1",
+				Anchor: &models.TaskCommentAnchor{FilePath: "main.go", StartLine: 12},
+			},
+			expectedEndLine: 12,
+		},
+		{
+			name:             "reply_to_reply_joins_thread",
+			request:          models.CreateTaskCommentRequest{TaskID: "task-1", Body: "Agreed", ParentID: &replyID, AuthorID: "user-2"},
+			parent:           &models.TaskComment{CommentID: replyID, TaskID: "task-1", ParentID: &rootID},
+			expectedParentID: &rootID,
+		},
+		{
+			name:        "parent_of_other_task",
+			request:     models.CreateTaskCommentRequest{TaskID: "task-1", Body: "Agreed", ParentID: &rootID, AuthorID: "user-2"},
+			parent:      &models.TaskComment{CommentID: rootID, TaskID: "task-2"},
+			expectedErr: apperrors.ErrNotFound,
+		},
+		{
+			name: "anchored_reply",
+			request: models.CreateTaskCommentRequest{
+				TaskID: "task-1", Body: "Agreed", ParentID: &rootID, AuthorID: "user-2",
+				Anchor: &models.TaskCommentAnchor{FilePath: "main.go", StartLine: 12},
+			},
+			expectedErr: apperrors.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			commentRepo := repositoryMocks.NewMockTaskCommentRepository(ctrl)
+			taskRepo := repositoryMocks.NewMockTaskRepository(ctrl)
+			service := NewDefaultTaskCommentService(commentRepo, taskRepo)
+
+			taskRepo.EXPECT().GetByID(gomock.Any(), "task-1").Return(&models.Task{TaskID: "task-1"}, nil)
+			if tt.parent != nil {
+				commentRepo.EXPECT().GetComment(gomock.Any(), tt.parent.CommentID).Return(tt.parent, nil)
+			}
+			if tt.expectedErr == nil {
+				commentRepo.EXPECT().CreateComment(gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			comment, err := service.CreateComment(context.Background(), tt.request)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.request.AuthorID, comment.AuthorID)
+			assert.Equal(t, tt.expectedParentID, comment.ParentID)
+			if tt.expectedEndLine != 0 {
+				assert.Equal(t, tt.expectedEndLine, comment.Anchor.EndLine)
+			}
+		})
+	}
+}
+
+func TestDefaultTaskCommentService_UpdateComment_NotAuthor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	commentRepo := repositoryMocks.NewMockTaskCommentRepository(ctrl)
+	service := NewDefaultTaskCommentService(commentRepo, repositoryMocks.NewMockTaskRepository(ctrl))
+
+	commentRepo.EXPECT().GetComment(gomock.Any(), "comment-1").Return(&models.TaskComment{CommentID: "comment-1", TaskID: "task-1", AuthorID: "user-1"}, nil)
+
+	_, err := service.UpdateComment(context.Background(), models.UpdateTaskCommentRequest{
+		TaskID: "task-1", CommentID: "comment-1", Body: "edited", AuthorID: "user-2",
+	})
+
+	assert.ErrorIs(t, err, apperrors.ErrForbidden)
+}
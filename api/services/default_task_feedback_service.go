@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+)
+
+// DefaultTaskFeedbackService is the default implementation of TaskFeedbackService
+type DefaultTaskFeedbackService struct {
+	feedbackRepo repository.TaskFeedbackRepository
+	taskRepo     repository.TaskRepository
+	now          func() time.Time
+}
+
+// NewDefaultTaskFeedbackService creates a new DefaultTaskFeedbackService
+func NewDefaultTaskFeedbackService(feedbackRepo repository.TaskFeedbackRepository, taskRepo repository.TaskRepository) *DefaultTaskFeedbackService {
+	return &DefaultTaskFeedbackService{
+		feedbackRepo: feedbackRepo,
+		taskRepo:     taskRepo,
+		now:          time.Now,
+	}
+}
+
+// SubmitFeedback records the caller's feedback on a completed task. The latest outcome also approves or rejects the
+// task, so experiments compare the outcomes of their arms.
+func (s *DefaultTaskFeedbackService) SubmitFeedback(ctx context.Context, request models.SubmitTaskFeedbackRequest) (*models.TaskFeedback, error) {
+	task, err := s.taskRepo.GetByID(ctx, request.TaskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+	if task.Status != models.TaskStatusCompleted {
+		return nil, apperrors.Conflict(apperrors.CodeTaskNotCompleted, "task %s is %s, only completed tasks take feedback", request.TaskID, task.Status)
+	}
+
+	now := s.now().UTC()
+	feedback := &models.TaskFeedback{
+		TaskID:    request.TaskID,
+		UserID:    request.UserID,
+		Rating:    request.Rating,
+		Comment:   request.Comment,
+		Outcome:   request.Outcome,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.feedbackRepo.UpsertFeedback(ctx, feedback); err != nil {
+		return nil, fmt.Errorf("failed to submit task feedback: %w", err)
+	}
+
+	approved := request.Outcome == models.TaskFeedbackOutcomeAccepted
+	if task.Approved == nil || *task.Approved != approved {
+		task.Approved = &approved
+		task.UpdatedAt = now
+		if err := s.taskRepo.Update(ctx, task, nil); err != nil {
+			return nil, fmt.Errorf("failed to update task: %w", err)
+		}
+	}
+
+	return feedback, nil
+}
+
+// ListFeedback lists the feedback on a task oldest first
+func (s *DefaultTaskFeedbackService) ListFeedback(ctx context.Context, request models.ListTaskFeedbackRequest) (*models.ListTaskFeedbackResponse, error) {
+	if _, err := s.taskRepo.GetByID(ctx, request.TaskID); err != nil {
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+
+	feedback, err := s.feedbackRepo.ListFeedback(ctx, request.TaskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list task feedback: %w", err)
+	}
+
+	return &models.ListTaskFeedbackResponse{Feedback: feedback}, nil
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+)
+
+func TestDefaultTaskFeedbackService_SubmitFeedback(t *testing.T) {
+	accepted := true
+
+	tests := []struct {
+		name            string
+		task            *models.Task
+		outcome         models.TaskFeedbackOutcome
+		expectApproval  *bool
+		expectedErrCode string
+	}{
+		{
+			name:           "rejection",
+			task:           &models.Task{TaskID: "task-1", Status: models.TaskStatusCompleted, Approved: &accepted},
+			outcome:        models.TaskFeedbackOutcomeRejected,
+			expectApproval: new(bool),
+		},
+		{
+			name:    "same outcome as the task's approval",
+			task:    &models.Task{TaskID: "task-1", Status: models.TaskStatusCompleted, Approved: &accepted},
+			outcome: models.TaskFeedbackOutcomeAccepted,
+		},
+		{
+			name:            "unfinished task",
+			task:            &models.Task{TaskID: "task-1", Status: models.TaskStatusInProgress},
+			outcome:         models.TaskFeedbackOutcomeAccepted,
+			expectedErrCode: apperrors.CodeTaskNotCompleted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			feedbackRepo := repositoryMocks.NewMockTaskFeedbackRepository(ctrl)
+			taskRepo := repositoryMocks.NewMockTaskRepository(ctrl)
+			service := NewDefaultTaskFeedbackService(feedbackRepo, taskRepo)
+
+			taskRepo.EXPECT().GetByID(gomock.Any(), "task-1").Return(tt.task, nil)
+			if tt.expectedErrCode == "" {
+				feedbackRepo.EXPECT().UpsertFeedback(gomock.Any(), gomock.Any()).Return(nil)
+			}
+			if tt.expectApproval != nil {
+				taskRepo.EXPECT().Update(gomock.Any(), gomock.Any(), nil).DoAndReturn(func(_ context.Context, task *models.Task, _ *models.Notification) error {
+					assert.Equal(t, tt.expectApproval, task.Approved)
+					return nil
+				})
+			}
+
+			feedback, err := service.SubmitFeedback(context.Background(), models.SubmitTaskFeedbackRequest{
+				TaskID: "task-1", Rating: 2, Comment: "Broke the build", Outcome: tt.outcome, UserID: "user-1",
+			})
+
+			if tt.expectedErrCode != "" {
+				assert.ErrorIs(t, err, apperrors.ErrConflict)
+				assert.Equal(t, tt.expectedErrCode, apperrors.CodeOf(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "user-1", feedback.UserID)
+			assert.Equal(t, tt.outcome, feedback.Outcome)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/objectstore"
+)
+
+// Metadata of the uploaded objects, whose values are escaped since S3 only stores ASCII metadata
+const (
+	uploadFilenameMetadata   = "filename"
+	uploadUploadedByMetadata = "uploaded-by"
+)
+
+// defaultUploadContentType is the content type of uploads that don't declare one
+const defaultUploadContentType = "application/octet-stream"
+
+// DefaultUploadService is the default implementation of UploadService. Uploads are stored under the configured prefix,
+// keyed by their project and upload IDs, and described by their object's metadata, so that they need no table.
+type DefaultUploadService struct {
+	store objectstore.ObjectStore
+	cfg   config.UploadsConfig
+}
+
+// NewDefaultUploadService creates a new DefaultUploadService storing the uploads in store
+func NewDefaultUploadService(store objectstore.ObjectStore, cfg config.UploadsConfig) *DefaultUploadService {
+	return &DefaultUploadService{
+		store: store,
+		cfg:   cfg,
+	}
+}
+
+// UploadTaskInput streams a file to a new upload of the project. Only the base name of filename is kept.
+func (s *DefaultUploadService) UploadTaskInput(ctx context.Context, projectID, filename, contentType, uploadedBy string, body io.Reader) (*models.TaskInputUpload, error) {
+	uploadID := "upl-" + uuid.New().String()
+	if contentType == "" {
+		contentType = defaultUploadContentType
+	}
+	filename = path.Base(strings.ReplaceAll(filename, "\\", "/"))
+	if filename == "." || filename == "/" {
+		filename = uploadID
+	}
+
+	object, err := s.store.Put(ctx, s.key(projectID, uploadID), contentType, map[string]string{
+		uploadFilenameMetadata:   url.PathEscape(filename),
+		uploadUploadedByMetadata: url.PathEscape(uploadedBy),
+	}, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TaskInputUpload{
+		UploadID:    uploadID,
+		ProjectID:   projectID,
+		Filename:    filename,
+		ContentType: contentType,
+		Size:        object.Size,
+		URI:         object.URI,
+		UploadedBy:  uploadedBy,
+		CreatedAt:   time.Now(),
+	}, nil
+}
+
+// GetTaskInput describes an upload of the project from its object
+func (s *DefaultUploadService) GetTaskInput(ctx context.Context, projectID, uploadID string) (*models.TaskInputUpload, error) {
+	if !strings.HasPrefix(uploadID, "upl-") || strings.Contains(uploadID, "/") {
+		return nil, apperrors.NotFound(apperrors.CodeUploadNotFound, "upload %s not found in project %s", uploadID, projectID)
+	}
+
+	object, err := s.store.Head(ctx, s.key(projectID, uploadID))
+	if err != nil {
+		if errors.Is(err, objectstore.ErrNotFound) {
+			return nil, apperrors.NotFound(apperrors.CodeUploadNotFound, "upload %s not found in project %s", uploadID, projectID)
+		}
+		return nil, err
+	}
+
+	filename, _ := url.PathUnescape(object.Metadata[uploadFilenameMetadata])
+	uploadedBy, _ := url.PathUnescape(object.Metadata[uploadUploadedByMetadata])
+	return &models.TaskInputUpload{
+		UploadID:    uploadID,
+		ProjectID:   projectID,
+		Filename:    filename,
+		ContentType: object.ContentType,
+		Size:        object.Size,
+		URI:         object.URI,
+		UploadedBy:  uploadedBy,
+		CreatedAt:   object.LastModified,
+	}, nil
+}
+
+// key returns the key of the object of a project's upload
+func (s *DefaultUploadService) key(projectID, uploadID string) string {
+	return s.cfg.Prefix + projectID + "/" + uploadID
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/objectstore"
+	objectstoreMocks "github.com/kazemisoroush/code-refactoring-tool/pkg/objectstore/mocks"
+)
+
+func newTestUploadService(t *testing.T) (*DefaultUploadService, *objectstoreMocks.MockObjectStore) {
+	store := objectstoreMocks.NewMockObjectStore(gomock.NewController(t))
+	return NewDefaultUploadService(store, config.UploadsConfig{Prefix: "task-inputs/"}), store
+}
+
+func TestDefaultUploadService_UploadTaskInput(t *testing.T) {
+	service, store := newTestUploadService(t)
+
+	var key string
+	store.EXPECT().
+		Put(gomock.Any(), gomock.Any(), "text/x-diff", map[string]string{"filename": "change%20n%C2%B01.diff", "uploaded-by": "auth-123"}, gomock.Any()).
+		DoAndReturn(func(_ context.Context, k, _ string, _ map[string]string, body io.Reader) (*objectstore.Object, error) {
+			key = k
+			data, err := io.ReadAll(body)
+			require.NoError(t, err)
+			return &objectstore.Object{Key: k, URI: "s3://bucket/" + k, Size: int64(len(data))}, nil
+		})
+
+	upload, err := service.UploadTaskInput(context.Background(), "proj-1", `C:\work\change n°1.diff`, "text/x-diff", "auth-123", strings.NewReader("+func Div"))
+
+	require.NoError(t, err)
+	assert.Regexp(t, `^upl-`, upload.UploadID)
+	assert.Equal(t, "task-inputs/proj-1/"+upload.UploadID, key)
+	assert.Equal(t, "s3://bucket/"+key, upload.URI)
+	assert.Equal(t, "change n°1.diff", upload.Filename)
+	assert.Equal(t, int64(9), upload.Size)
+	assert.Equal(t, "auth-123", upload.UploadedBy)
+}
+
+func TestDefaultUploadService_UploadTaskInput_DefaultsContentType(t *testing.T) {
+	service, store := newTestUploadService(t)
+	store.EXPECT().
+		Put(gomock.Any(), gomock.Any(), "application/octet-stream", gomock.Any(), gomock.Any()).
+		Return(&objectstore.Object{Size: 3}, nil)
+
+	upload, err := service.UploadTaskInput(context.Background(), "proj-1", "", "", "", strings.NewReader("abc"))
+
+	require.NoError(t, err)
+	assert.Equal(t, "application/octet-stream", upload.ContentType)
+	assert.Equal(t, upload.UploadID, upload.Filename)
+}
+
+func TestDefaultUploadService_GetTaskInput(t *testing.T) {
+	service, store := newTestUploadService(t)
+	uploadedAt := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	store.EXPECT().Head(gomock.Any(), "task-inputs/proj-1/upl-1").Return(&objectstore.Object{
+		Key:          "task-inputs/proj-1/upl-1",
+		URI:          "s3://bucket/task-inputs/proj-1/upl-1",
+		ContentType:  "application/zip",
+		Size:         5 << 20,
+		Metadata:     map[string]string{"filename": "repo%20snapshot.zip", "uploaded-by": "auth-123"},
+		LastModified: uploadedAt,
+	}, nil)
+
+	upload, err := service.GetTaskInput(context.Background(), "proj-1", "upl-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, "repo snapshot.zip", upload.Filename)
+	assert.Equal(t, "application/zip", upload.ContentType)
+	assert.Equal(t, int64(5<<20), upload.Size)
+	assert.Equal(t, "s3://bucket/task-inputs/proj-1/upl-1", upload.URI)
+	assert.Equal(t, uploadedAt, upload.CreatedAt)
+}
+
+func TestDefaultUploadService_GetTaskInput_NotFound(t *testing.T) {
+	service, store := newTestUploadService(t)
+	store.EXPECT().Head(gomock.Any(), "task-inputs/proj-2/upl-1").Return(nil, fmt.Errorf("object task-inputs/proj-2/upl-1: %w", objectstore.ErrNotFound))
+
+	// Uploads of another project aren't found
+	_, err := service.GetTaskInput(context.Background(), "proj-2", "upl-1")
+	assert.Equal(t, apperrors.CodeUploadNotFound, apperrors.CodeOf(err))
+
+	// IDs that aren't upload IDs are never looked up
+	_, err = service.GetTaskInput(context.Background(), "proj-2", "upl-1/../../proj-1/upl-1")
+	assert.Equal(t, apperrors.CodeUploadNotFound, apperrors.CodeOf(err))
+}
@@ -0,0 +1,18 @@
+package services
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// DependencyAuditService defines the interface for auditing codebase dependencies against published vulnerabilities
+//
+//go:generate mockgen -destination=./mocks/mock_dependency_audit_service.go -mock_names=DependencyAuditService=MockDependencyAuditService -package=mocks . DependencyAuditService
+type DependencyAuditService interface {
+	// AuditCodebase checks the dependencies of a dependency_audit task's codebase and replaces the codebase's findings
+	AuditCodebase(ctx context.Context, task *models.TaskWithFullContext) (*models.DependencyAuditResult, error)
+
+	// ListFindings lists the vulnerable dependencies found by a codebase's latest audit
+	ListFindings(ctx context.Context, request models.ListDependencyFindingsRequest) (*models.ListDependencyFindingsResponse, error)
+}
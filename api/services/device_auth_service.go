@@ -0,0 +1,28 @@
+package services
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// DeviceAuthService defines the interface for the device authorization flow, which signs in devices without a
+// browser, such as the CLI, with the approval of a user signed in elsewhere
+//
+//go:generate mockgen -destination=./mocks/mock_device_auth_service.go -mock_names=DeviceAuthService=MockDeviceAuthService -package=mocks . DeviceAuthService
+type DeviceAuthService interface {
+	// StartDeviceAuth starts a device sign in, returning the user code to approve and the device code to poll with
+	StartDeviceAuth(ctx context.Context, request models.StartDeviceAuthRequest) (*models.StartDeviceAuthResponse, error)
+
+	// ApproveDevice approves a pending device sign in, issuing the device tokens of the approving user
+	ApproveDevice(ctx context.Context, request models.ApproveDeviceRequest) error
+
+	// DenyDevice denies a pending device sign in
+	DenyDevice(ctx context.Context, request models.DenyDeviceRequest) error
+
+	// PollDeviceToken returns the tokens of an approved device sign in once, or the reason they can't be issued yet
+	PollDeviceToken(ctx context.Context, request models.DeviceTokenRequest) (*models.SignInResponse, error)
+
+	// PurgeExpired deletes the expired device sign ins, returning how many were deleted
+	PurgeExpired(ctx context.Context) (int64, error)
+}
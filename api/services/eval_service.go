@@ -0,0 +1,24 @@
+package services
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// EvalService defines the interface for the evaluation runs of the local agent against golden repositories
+//
+//go:generate mockgen -destination=./mocks/mock_eval_service.go -mock_names=EvalService=MockEvalService -package=mocks . EvalService
+type EvalService interface {
+	// RunEval starts evaluating the fixtures in the background and returns the running evaluation
+	RunEval(ctx context.Context, request models.RunEvalRequest) (*models.EvalRun, error)
+
+	// GetRun retrieves an evaluation run with its per-fixture results
+	GetRun(ctx context.Context, request models.GetEvalRunRequest) (*models.EvalRun, error)
+
+	// ListRuns lists the most recent evaluation runs with their summaries
+	ListRuns(ctx context.Context, request models.ListEvalRunsRequest) (*models.ListEvalRunsResponse, error)
+
+	// CompareRuns compares a candidate evaluation run with a baseline, reporting the fixtures it regressed on
+	CompareRuns(ctx context.Context, request models.CompareEvalRunsRequest) (*models.EvalComparison, error)
+}
@@ -0,0 +1,28 @@
+package services
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// ExperimentService defines the interface for the experiments routing live tasks to a variant of the local agent
+//
+//go:generate mockgen -destination=./mocks/mock_experiment_service.go -mock_names=ExperimentService=MockExperimentService -package=mocks . ExperimentService
+type ExperimentService interface {
+	// CreateExperiment starts assigning the eligible tasks to the experiment's control and variant
+	CreateExperiment(ctx context.Context, request models.CreateExperimentRequest) (*models.Experiment, error)
+
+	// GetExperiment retrieves an experiment
+	GetExperiment(ctx context.Context, request models.GetExperimentRequest) (*models.Experiment, error)
+
+	// ListExperiments lists the experiments, newest first
+	ListExperiments(ctx context.Context) (*models.ListExperimentsResponse, error)
+
+	// StopExperiment stops assigning tasks to an experiment
+	StopExperiment(ctx context.Context, request models.StopExperimentRequest) (*models.Experiment, error)
+
+	// GetExperimentReport compares the success rate, duration and approval rate of the experiment's variant with its
+	// control
+	GetExperimentReport(ctx context.Context, request models.GetExperimentReportRequest) (*models.ExperimentReport, error)
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+)
+
+// Keys of the experiment assignment on the output of the tasks an experiment covers
+const (
+	experimentIDKey       = "experiment_id"
+	experimentArmKey      = "experiment_arm"
+	experimentDurationKey = "execution_ms"
+)
+
+// ExperimentTaskExecutor executes the tasks covered by a running experiment on its control or variant, and other tasks
+// on the control. It stands in for the control in the registry, under the control's name.
+type ExperimentTaskExecutor struct {
+	control        TaskExecutor
+	newVariant     func(model, promptVersion string) (TaskExecutor, error)
+	experimentRepo repository.ExperimentRepository
+	taskRepo       repository.TaskRepository
+}
+
+// NewExperimentTaskExecutor creates an executor assigning the tasks of running experiments. newVariant creates the
+// executor of a variant's model and prompt version.
+func NewExperimentTaskExecutor(
+	control TaskExecutor,
+	newVariant func(model, promptVersion string) (TaskExecutor, error),
+	experimentRepo repository.ExperimentRepository,
+	taskRepo repository.TaskRepository,
+) *ExperimentTaskExecutor {
+	return &ExperimentTaskExecutor{
+		control:        control,
+		newVariant:     newVariant,
+		experimentRepo: experimentRepo,
+		taskRepo:       taskRepo,
+	}
+}
+
+// Name implements TaskExecutor
+func (e *ExperimentTaskExecutor) Name() string {
+	return e.control.Name()
+}
+
+// Supports implements TaskExecutor
+func (e *ExperimentTaskExecutor) Supports(taskType models.TaskType) bool {
+	return e.control.Supports(taskType)
+}
+
+// Execute implements TaskExecutor. The output of a task an experiment covers records its arm and how long the
+// execution took, also when it fails.
+func (e *ExperimentTaskExecutor) Execute(ctx context.Context, task *models.TaskWithFullContext) (map[string]any, error) {
+	// Experiments never fail tasks: a task that can't be assigned runs on the control, outside the experiment
+	experiment, arm, err := e.assign(ctx, &task.Task)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to assign task to experiment", "task_id", task.TaskID, "error", err)
+		experiment = nil
+	}
+	if experiment == nil {
+		return e.control.Execute(ctx, task)
+	}
+
+	executor := e.control
+	if arm == models.ExperimentArmVariant {
+		executor, err = e.newVariant(experiment.VariantModel, experiment.VariantPromptVersion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create variant of experiment %s: %w", experiment.ExperimentID, err)
+		}
+	}
+
+	started := time.Now()
+	output, err := executor.Execute(ctx, task)
+	if output == nil {
+		output = map[string]any{}
+	}
+	output[experimentIDKey] = experiment.ExperimentID
+	output[experimentArmKey] = arm
+	output[experimentDurationKey] = time.Since(started).Milliseconds()
+	return output, err
+}
+
+// assign returns the experiment covering the task and its arm, recording them on the task. A resumed execution keeps
+// the arm it was assigned, even once the experiment stopped. It returns a nil experiment for a task none covers.
+func (e *ExperimentTaskExecutor) assign(ctx context.Context, task *models.Task) (*models.Experiment, models.ExperimentArm, error) {
+	if task.ExperimentID != nil && task.ExperimentArm != nil {
+		experiment, err := e.experimentRepo.GetExperiment(ctx, *task.ExperimentID)
+		if err != nil {
+			return nil, "", err
+		}
+		return experiment, *task.ExperimentArm, nil
+	}
+
+	running, err := e.experimentRepo.ListExperiments(ctx, models.ExperimentStatusRunning)
+	if err != nil {
+		return nil, "", err
+	}
+	for i := range running {
+		experiment := &running[i]
+		if !slices.Contains(experiment.TaskTypes, task.Type) {
+			continue
+		}
+		arm := experimentArm(experiment, task.TaskID)
+		if err := e.taskRepo.AssignExperiment(ctx, task.TaskID, experiment.ExperimentID, arm); err != nil {
+			return nil, "", err
+		}
+		return experiment, arm, nil
+	}
+	return nil, "", nil
+}
+
+// experimentArm assigns a task to an arm by hashing it with the experiment, so the variant gets its share of the
+// tasks and a task always gets the same arm
+func experimentArm(experiment *models.Experiment, taskID string) models.ExperimentArm {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(experiment.ExperimentID + "/" + taskID))
+	if int(hash.Sum32()%100) < experiment.TrafficPercent {
+		return models.ExperimentArmVariant
+	}
+	return models.ExperimentArmControl
+}
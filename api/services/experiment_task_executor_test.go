@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+	servicesMocks "github.com/kazemisoroush/code-refactoring-tool/api/services/mocks"
+)
+
+func TestExperimentTaskExecutor_Execute_AssignsVariant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	control := servicesMocks.NewMockTaskExecutor(ctrl)
+	variant := servicesMocks.NewMockTaskExecutor(ctrl)
+	experimentRepo := repositoryMocks.NewMockExperimentRepository(ctrl)
+	taskRepo := repositoryMocks.NewMockTaskRepository(ctrl)
+	executor := NewExperimentTaskExecutor(control, func(model, promptVersion string) (TaskExecutor, error) {
+		assert.Equal(t, "qwen2.5-coder:7b", model)
+		assert.Equal(t, "v2", promptVersion)
+		return variant, nil
+	}, experimentRepo, taskRepo)
+	task := &models.TaskWithFullContext{Task: models.Task{TaskID: "task-1", Type: models.TaskTypeRefactoring}}
+
+	experimentRepo.EXPECT().ListExperiments(gomock.Any(), models.ExperimentStatusRunning).Return([]models.Experiment{
+		{ExperimentID: "exp-docs", TaskTypes: []models.TaskType{models.TaskTypeDocumentation}, TrafficPercent: 100},
+		{ExperimentID: "exp-1", TaskTypes: []models.TaskType{models.TaskTypeRefactoring}, TrafficPercent: 100, VariantModel: "qwen2.5-coder:7b", VariantPromptVersion: "v2"},
+	}, nil)
+	taskRepo.EXPECT().AssignExperiment(gomock.Any(), "task-1", "exp-1", models.ExperimentArmVariant).Return(nil)
+	variant.EXPECT().Execute(gomock.Any(), task).Return(nil, errors.New("step budget exhausted"))
+
+	output, err := executor.Execute(context.Background(), task)
+
+	assert.EqualError(t, err, "step budget exhausted")
+	assert.Equal(t, "exp-1", output[experimentIDKey])
+	assert.Equal(t, models.ExperimentArmVariant, output[experimentArmKey])
+	assert.Contains(t, output, experimentDurationKey, "failed executions are timed too")
+}
+
+func TestExperimentTaskExecutor_Execute_ResumedTaskKeepsArm(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	control := servicesMocks.NewMockTaskExecutor(ctrl)
+	experimentRepo := repositoryMocks.NewMockExperimentRepository(ctrl)
+	executor := NewExperimentTaskExecutor(control, nil, experimentRepo, repositoryMocks.NewMockTaskRepository(ctrl))
+	experimentID, arm := "exp-1", models.ExperimentArmControl
+	task := &models.TaskWithFullContext{Task: models.Task{TaskID: "task-1", Type: models.TaskTypeRefactoring, ExperimentID: &experimentID, ExperimentArm: &arm}}
+
+	experimentRepo.EXPECT().GetExperiment(gomock.Any(), "exp-1").Return(&models.Experiment{ExperimentID: "exp-1", Status: models.ExperimentStatusStopped}, nil)
+	control.EXPECT().Execute(gomock.Any(), task).Return(map[string]any{"answer": "done"}, nil)
+
+	output, err := executor.Execute(context.Background(), task)
+
+	require.NoError(t, err)
+	assert.Equal(t, "done", output["answer"])
+	assert.Equal(t, models.ExperimentArmControl, output[experimentArmKey])
+}
+
+func TestExperimentTaskExecutor_Execute_UncoveredTaskRunsOnControl(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	control := servicesMocks.NewMockTaskExecutor(ctrl)
+	experimentRepo := repositoryMocks.NewMockExperimentRepository(ctrl)
+	executor := NewExperimentTaskExecutor(control, nil, experimentRepo, repositoryMocks.NewMockTaskRepository(ctrl))
+	task := &models.TaskWithFullContext{Task: models.Task{TaskID: "task-1", Type: models.TaskTypeCodeReview}}
+
+	experimentRepo.EXPECT().ListExperiments(gomock.Any(), models.ExperimentStatusRunning).Return(nil, errors.New("connection refused"))
+	control.EXPECT().Execute(gomock.Any(), task).Return(map[string]any{"answer": "done"}, nil)
+
+	output, err := executor.Execute(context.Background(), task)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"answer": "done"}, output, "tasks outside experiments are left as they are")
+}
+
+func TestExperimentArm_SplitsTrafficStably(t *testing.T) {
+	experiment := &models.Experiment{ExperimentID: "exp-1", TrafficPercent: 20}
+
+	variants := 0
+	for i := range 1000 {
+		taskID := fmt.Sprintf("task-%d", i)
+		arm := experimentArm(experiment, taskID)
+		assert.Equal(t, arm, experimentArm(experiment, taskID))
+		if arm == models.ExperimentArmVariant {
+			variants++
+		}
+	}
+
+	assert.InDelta(t, 200, variants, 50)
+}
@@ -0,0 +1,21 @@
+package services
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// FileHistoryService defines the interface for the git history of the files of codebases, telling the hot spots
+// changed often and recently from the files left alone
+//
+//go:generate mockgen -destination=./mocks/mock_file_history_service.go -mock_names=FileHistoryService=MockFileHistoryService -package=mocks . FileHistoryService
+type FileHistoryService interface {
+	// RecordHistory reads the history of the clone of an analysis task's codebase in dir and stores it, returning the
+	// histories of its files, highest churn first
+	RecordHistory(ctx context.Context, task *models.TaskWithFullContext, dir string) ([]models.FileHistory, error)
+
+	// LookupHistory retrieves the stored histories of files of a codebase, of up to limit files changed the most when
+	// paths is empty
+	LookupHistory(ctx context.Context, codebaseID string, paths []string, limit int) ([]models.FileHistory, error)
+}
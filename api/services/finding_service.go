@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	analyzermodels "github.com/kazemisoroush/code-refactoring-tool/pkg/analyzer/models"
+)
+
+// FindingService defines the interface for the analysis findings of codebases and their lifecycle
+//
+//go:generate mockgen -destination=./mocks/mock_finding_service.go -mock_names=FindingService=MockFindingService -package=mocks . FindingService
+type FindingService interface {
+	// CreateFinding reports a finding on a codebase by hand
+	CreateFinding(ctx context.Context, request models.CreateFindingRequest) (*models.Finding, error)
+
+	// GetFinding retrieves a finding by ID
+	GetFinding(ctx context.Context, request models.GetFindingRequest) (*models.Finding, error)
+
+	// UpdateFinding changes the status of a finding, such as ignoring a false positive
+	UpdateFinding(ctx context.Context, request models.UpdateFindingRequest) (*models.Finding, error)
+
+	// DeleteFinding deletes a finding
+	DeleteFinding(ctx context.Context, request models.DeleteFindingRequest) error
+
+	// ListFindings lists the findings of a codebase, most recently seen first
+	ListFindings(ctx context.Context, request models.ListFindingsRequest) (*models.ListFindingsResponse, error)
+
+	// Rebaseline replaces the baseline of a codebase with its open findings, which are then suppressed as legacy ones
+	Rebaseline(ctx context.Context, request models.RebaselineFindingsRequest) (*models.FindingBaseline, error)
+
+	// GetBaseline returns the baseline of a codebase and the legacy findings it suppresses
+	GetBaseline(ctx context.Context, request models.GetFindingBaselineRequest) (*models.FindingBaselineResponse, error)
+
+	// SyncFindings records the issues an analysis task reported as the findings of its codebase from source: issues seen
+	// before update their finding, new ones open a finding, and open findings of source the task no longer reports are
+	// fixed. Issues of findings suppressed by the baseline are reported in the result.
+	SyncFindings(ctx context.Context, task *models.TaskWithFullContext, source string, issues []analyzermodels.CodeIssue) (*models.FindingSyncResult, error)
+}
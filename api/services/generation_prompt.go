@@ -0,0 +1,65 @@
+package services
+
+import (
+	"strings"
+	"text/template"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/jsonschema"
+)
+
+// prDescriptionSchema is the answer the model gives when describing a change
+var prDescriptionSchema = objectOf(map[string]*jsonschema.Schema{
+	"title": stringOf("Title of the pull request in the imperative mood, at most 72 characters, usable as the commit subject"),
+	"body":  stringOf("Body of the pull request in Markdown: what the change does and why, then anything a reviewer should check"),
+	"changelog": objectOf(map[string]*jsonschema.Schema{
+		"type": {Type: "string", Description: "Section of the changelog the change belongs to", Enum: []any{
+			string(models.ChangelogTypeAdded), string(models.ChangelogTypeChanged), string(models.ChangelogTypeDeprecated),
+			string(models.ChangelogTypeRemoved), string(models.ChangelogTypeFixed), string(models.ChangelogTypeSecurity),
+		}},
+		"summary": stringOf("One-line summary of the change for the users of the project"),
+	}, "type", "summary"),
+}, "title", "body", "changelog")
+
+// prDescriptionTemplate asks the model to describe a diff, answering with JSON matching prDescriptionSchema
+var prDescriptionTemplate = template.Must(template.New("pr_description").Parse(`
+Describe the following change as a pull request. Write for a reviewer who hasn't seen the change: say what it does and why before how, and don't restate the list of files.
+{{- with .Context}}
+
+What the change is for:
+{{.}}
+{{- end}}
+
+Answer with only a JSON object matching this schema:
+{{.Schema}}
+
+Diff{{if .Truncated}}, of which only the beginning is shown{{end}}:
+{{.Diff}}
+{{- with .Answer}}
+
+Your previous answer was:
+{{.}}
+
+Your answer is invalid: {{$.Error}}
+Answer again with only the corrected answer, in the required format.
+{{- end}}
+`))
+
+// prDescriptionPrompt renders the prompt describing a diff. A previous answer and its validation error ask the model
+// to repair the answer.
+func prDescriptionPrompt(context, diff string, truncated bool, answer string, err error) string {
+	data := struct {
+		Context   string
+		Schema    string
+		Diff      string
+		Truncated bool
+		Answer    string
+		Error     error
+	}{context, prDescriptionSchema.String(), diff, truncated, answer, err}
+	var prompt strings.Builder
+	if err := prDescriptionTemplate.Execute(&prompt, data); err != nil {
+		// The template only reads strings
+		return diff
+	}
+	return strings.TrimSpace(prompt.String())
+}
@@ -0,0 +1,21 @@
+package services
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// GenerationService defines the interface for the text the configured model generates from a change, without
+// creating a task
+//
+//go:generate mockgen -destination=./mocks/mock_generation_service.go -mock_names=GenerationService=MockGenerationService -package=mocks . GenerationService
+type GenerationService interface {
+	// GeneratePRDescription describes a diff, or the diff of a task, as a pull request title and body with a
+	// changelog entry
+	GeneratePRDescription(ctx context.Context, req models.GeneratePRDescriptionRequest) (*models.PRDescription, error)
+
+	// StreamPRDescription is GeneratePRDescription passing the model's output to emit as chunk events while it's
+	// generated, and a retry event whenever the output so far is discarded
+	StreamPRDescription(ctx context.Context, req models.GeneratePRDescriptionRequest, emit func(models.StreamEvent) error) (*models.PRDescription, error)
+}
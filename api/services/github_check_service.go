@@ -0,0 +1,36 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// GitHubCheckService defines the interface for running tasks on pull requests and publishing their results as GitHub
+// check runs
+//
+//go:generate mockgen -destination=./mocks/mock_github_check_service.go -mock_names=GitHubCheckService=MockGitHubCheckService -package=mocks . GitHubCheckService
+type GitHubCheckService interface {
+	// PutAppConfig configures the GitHub App of an organization, replacing its configuration
+	PutAppConfig(ctx context.Context, request models.PutGitHubAppConfigRequest) (*models.GitHubAppConfig, error)
+
+	// GetAppConfig gets the GitHub App configuration of an organization
+	GetAppConfig(ctx context.Context, request models.GetGitHubAppConfigRequest) (*models.GitHubAppConfig, error)
+
+	// ListAppConfigs lists the GitHub App configurations ordered by organization
+	ListAppConfigs(ctx context.Context) (*models.ListGitHubAppConfigsResponse, error)
+
+	// DeleteAppConfig deletes the GitHub App configuration of an organization
+	DeleteAppConfig(ctx context.Context, request models.DeleteGitHubAppConfigRequest) error
+
+	// HandleWebhook handles a webhook delivery of a GitHub App, given its event, X-Hub-Signature-256 signature and
+	// payload. An opened or updated pull request starts a task and a check run its result is published to.
+	HandleWebhook(ctx context.Context, event, signature string, payload []byte) (*models.GitHubWebhookResponse, error)
+
+	// PublishChecks publishes the results of the finished tasks to their check runs, returning how many were published
+	PublishChecks(ctx context.Context) (int, error)
+
+	// RunCheckPublishing publishes the results of finished tasks every interval until ctx is cancelled
+	RunCheckPublishing(ctx context.Context, interval time.Duration)
+}
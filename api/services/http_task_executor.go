@@ -0,0 +1,86 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// maxTaskExecutorResponseSize bounds the output an external executor may return
+const maxTaskExecutorResponseSize = 10 << 20
+
+// HTTPTaskExecutor executes tasks on an external service. The task, with its project, agent and codebase, is posted
+// as JSON to the service's URL, which answers with a JSON object of the task's output. Executions are bounded by the
+// deadline of the task.
+type HTTPTaskExecutor struct {
+	name       string
+	url        string
+	taskTypes  []models.TaskType
+	httpClient *http.Client
+}
+
+// NewHTTPTaskExecutor creates an executor posting tasks of taskTypes to url
+func NewHTTPTaskExecutor(name, url string, taskTypes []models.TaskType) TaskExecutor {
+	return &HTTPTaskExecutor{
+		name:       name,
+		url:        url,
+		taskTypes:  taskTypes,
+		httpClient: &http.Client{},
+	}
+}
+
+// Name implements TaskExecutor
+func (e *HTTPTaskExecutor) Name() string {
+	return e.name
+}
+
+// Supports implements TaskExecutor
+func (e *HTTPTaskExecutor) Supports(taskType models.TaskType) bool {
+	return slices.Contains(e.taskTypes, taskType)
+}
+
+// Execute implements TaskExecutor
+func (e *HTTPTaskExecutor) Execute(ctx context.Context, task *models.TaskWithFullContext) (map[string]any, error) {
+	data, err := json.Marshal(task)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode task: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request to executor %s: %w", e.name, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("executor %s is unreachable: %w", e.name, err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close task executor response body", "executor", e.name, "error", closeErr)
+		}
+	}()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxTaskExecutorResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response of executor %s: %w", e.name, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("executor %s responded with %d: %s", e.name, resp.StatusCode, bytes.TrimSpace(body))
+	}
+
+	var output map[string]any
+	if err := json.Unmarshal(body, &output); err != nil {
+		return nil, fmt.Errorf("executor %s responded with invalid output: %w", e.name, err)
+	}
+	return output, nil
+}
@@ -0,0 +1,20 @@
+package services
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// IngestionScanService defines the interface for scanning codebases for committed secrets and incompatible licenses
+// before their content is ingested
+//
+//go:generate mockgen -destination=./mocks/mock_ingestion_scan_service.go -mock_names=IngestionScanService=MockIngestionScanService -package=mocks . IngestionScanService
+type IngestionScanService interface {
+	// ScanCodebase scans the head of a codebase's default branch, replaces the codebase's findings and blocks or
+	// unblocks its ingestion
+	ScanCodebase(ctx context.Context, request models.ScanCodebaseRequest) (*models.ScanCodebaseResponse, error)
+
+	// ListFindings lists the findings of a codebase's latest scan
+	ListFindings(ctx context.Context, request models.ListScanFindingsRequest) (*models.ListScanFindingsResponse, error)
+}
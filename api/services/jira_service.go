@@ -0,0 +1,43 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// JiraService defines the interface for linking tasks to Jira issues and keeping the issues updated as the tasks
+// progress
+//
+//go:generate mockgen -destination=./mocks/mock_jira_service.go -mock_names=JiraService=MockJiraService -package=mocks . JiraService
+type JiraService interface {
+	// PutSite configures the Jira site of an organization, replacing its configuration
+	PutSite(ctx context.Context, request models.PutJiraSiteRequest) (*models.JiraSite, error)
+
+	// GetSite gets the Jira site of an organization
+	GetSite(ctx context.Context, request models.GetJiraSiteRequest) (*models.JiraSite, error)
+
+	// ListSites lists the Jira sites ordered by organization
+	ListSites(ctx context.Context) (*models.ListJiraSitesResponse, error)
+
+	// DeleteSite deletes the Jira site of an organization
+	DeleteSite(ctx context.Context, request models.DeleteJiraSiteRequest) error
+
+	// ResolveIssue finds the site listing the project of an issue key and checks the issue exists, returning the
+	// link a task working on it would have
+	ResolveIssue(ctx context.Context, issueKey string) (*models.JiraIssueLink, error)
+
+	// LinkIssue links a created task to the issue resolved by ResolveIssue
+	LinkIssue(ctx context.Context, taskID string, link *models.JiraIssueLink) error
+
+	// AttachIssues sets the issue of the tasks linked to one
+	AttachIssues(ctx context.Context, tasks []models.Task) error
+
+	// SyncIssues comments on the issues whose task changed status and transitions those whose task completed,
+	// returning how many issues were updated
+	SyncIssues(ctx context.Context) (int, error)
+
+	// RunIssueSync updates the issues of tasks that changed status every interval until ctx is cancelled
+	RunIssueSync(ctx context.Context, interval time.Duration)
+}
@@ -0,0 +1,22 @@
+package services
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// LLMTraceService defines the interface for the log of the LLM calls made while executing tasks
+//
+//go:generate mockgen -destination=./mocks/mock_llm_trace_service.go -mock_names=LLMTraceService=MockLLMTraceService -package=mocks . LLMTraceService
+type LLMTraceService interface {
+	// RecordInteraction logs an LLM call made while executing a task. Only its metadata is logged when the project's
+	// redaction policy disables LLM content logging, and its content is masked with the policy otherwise.
+	RecordInteraction(ctx context.Context, interaction *models.LLMInteraction) error
+
+	// GetTrace lists the LLM calls made for a task, oldest first
+	GetTrace(ctx context.Context, request models.GetLLMTraceRequest) (*models.GetLLMTraceResponse, error)
+
+	// PurgeExpired deletes the LLM calls past the retention, returning how many were deleted
+	PurgeExpired(ctx context.Context) (int64, error)
+}
@@ -0,0 +1,236 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/agent"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/toolloop"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/patcher"
+)
+
+// LocalAgentTaskExecutorName names the executor running tasks with the local model in a tool-calling loop
+const LocalAgentTaskExecutorName = "local_agent"
+
+// maxExecutionDiffLength bounds the diff of the changes an execution made that is kept in its output
+const maxExecutionDiffLength = 64 * 1024
+
+// truncatedDiffMarker ends the diffs cut at maxExecutionDiffLength
+const truncatedDiffMarker = "\n... (diff truncated)"
+
+// DefaultLocalAgentPromptVersion is the version of the system prompt used unless another one is configured
+const DefaultLocalAgentPromptVersion = "v1"
+
+// localAgentSystemPrompts tell the local model how to work through its tools, by version. Versions are compared by
+// evaluation runs before one becomes the default, and are never changed once released, so that runs stay comparable.
+var localAgentSystemPrompts = map[string]string{
+	"v1": `You are a software engineer working on a checkout of a repository.
+Use the tools to read and search the code, edit it with apply_patch and check your changes with run_tests.
+Read a file again after patching it, since its line numbers change.
+When you are done, answer without calling a tool, summarising what you found or changed.`,
+	"v2": `You are a software engineer working on a checkout of a repository.
+Use the tools to read and search the code before changing it, and make the smallest change that does the task.
+Edit files with apply_patch, reading a file again after patching it, since its line numbers change.
+After your last edit, run run_tests and fix any failure your change caused.
+When you are done, answer without calling a tool, summarising what you found or changed.`,
+}
+
+// LocalAgentPromptVersions lists the versions of the local agent's system prompt
+func LocalAgentPromptVersions() []string {
+	return slices.Sorted(maps.Keys(localAgentSystemPrompts))
+}
+
+// localAgentAnswerFormat asks the model for an answer matching the schema of the task's type
+const localAgentAnswerFormat = `
+
+Your final answer must be only a JSON object, without any other text, matching this JSON schema:
+%s`
+
+// LocalAgentTaskExecutor executes tasks with a local model that reads, searches and edits a checkout of the task's
+// codebase and runs its tests. The task is described with the diff it's about and the chunks retrieved for it, packed
+// under the model's context budget. The model's answer must match the output schema of the task's type, and the model
+// is asked to repair invalid answers. The raw and validated answers are kept in the task output, with a trace of the
+// sections packed into the prompt, a trace of the model's steps and the diff of its edits, which are discarded from
+// the checkout afterwards. Every model call is logged to the LLM trace.
+type LocalAgentTaskExecutor struct {
+	model       agent.ChatModel
+	context     TaskContext
+	cloner      CodebaseCloner
+	patcher     patcher.Patcher
+	llmTrace    LLMTraceService
+	prompt      string
+	version     string
+	maxSteps    int
+	maxRepairs  int
+	testCommand []string
+}
+
+// NewLocalAgentTaskExecutor creates an executor prompting the model with a version of the system prompt and the task
+// packed as taskContext configures, allowing each task maxSteps model calls, of which at most maxRepairs repair
+// invalid answers. Model calls aren't traced when llmTrace is nil, such as in evaluation runs.
+func NewLocalAgentTaskExecutor(model agent.ChatModel, taskContext TaskContext, cloner CodebaseCloner, llmTrace LLMTraceService, promptVersion string, maxSteps, maxRepairs int, testCommand string) (TaskExecutor, error) {
+	prompt, ok := localAgentSystemPrompts[promptVersion]
+	if !ok {
+		return nil, fmt.Errorf("unknown local agent prompt version %q, expected one of %s", promptVersion, strings.Join(LocalAgentPromptVersions(), ", "))
+	}
+
+	return &LocalAgentTaskExecutor{
+		model:       model,
+		context:     taskContext,
+		cloner:      cloner,
+		patcher:     patcher.NewFilePatcher(),
+		llmTrace:    llmTrace,
+		prompt:      prompt,
+		version:     promptVersion,
+		maxSteps:    maxSteps,
+		maxRepairs:  maxRepairs,
+		testCommand: strings.Fields(testCommand),
+	}, nil
+}
+
+// Name implements TaskExecutor
+func (e *LocalAgentTaskExecutor) Name() string {
+	return LocalAgentTaskExecutorName
+}
+
+// Supports implements TaskExecutor. Dependency audits and codemods don't need a model.
+func (e *LocalAgentTaskExecutor) Supports(taskType models.TaskType) bool {
+	return taskType != models.TaskTypeDependencyAudit && taskType != models.TaskTypeCodemod
+}
+
+// Execute implements TaskExecutor. The output holds the trace also when the execution fails.
+func (e *LocalAgentTaskExecutor) Execute(ctx context.Context, task *models.TaskWithFullContext) (map[string]any, error) {
+	if task.Codebase == nil {
+		return nil, fmt.Errorf("the local agent requires a codebase")
+	}
+
+	var branch, commitSHA string
+	if task.Branch != nil {
+		branch = *task.Branch
+	}
+	if task.CommitSHA != nil {
+		commitSHA = *task.CommitSHA
+	}
+	dir, cleanup, err := e.cloner.Clone(ctx, task.TaskID, task.Codebase, branch, commitSHA)
+	if err != nil {
+		return nil, fmt.Errorf("failed to clone codebase: %w", err)
+	}
+	defer cleanup()
+
+	tools := toolloop.WorkspaceTools(dir, e.testCommand, e.patcher)
+	if e.context.History != nil {
+		tools = append(tools, toolloop.FileHistoryTool(func(ctx context.Context, path string) (string, error) {
+			return e.context.describeHistory(ctx, task, path)
+		}))
+	}
+	loop := toolloop.NewLoop(e.model, tools, e.maxSteps)
+	if e.llmTrace != nil {
+		loop.WithRecorder(func(ctx context.Context, interaction toolloop.Interaction) {
+			if err := e.llmTrace.RecordInteraction(ctx, newLLMInteraction(task, e.Name(), interaction)); err != nil {
+				slog.WarnContext(ctx, "failed to record LLM interaction", "task_id", task.TaskID, "error", err)
+			}
+		})
+	}
+	system := e.prompt
+	if schema := TaskOutputSchema(task.Type); schema != nil {
+		system += fmt.Sprintf(localAgentAnswerFormat, schema)
+		loop.WithValidator(func(answer string) (any, error) {
+			return ValidateTaskAnswer(schema, answer)
+		}, e.maxRepairs)
+	}
+	prompt, contextTrace := e.context.assemble(ctx, task, system)
+	result, loopErr := loop.Run(ctx, system, prompt)
+
+	output := map[string]any{
+		"execution_method": "local_tool_loop",
+		"prompt_version":   e.version,
+		"context":          contextTrace,
+		"answer":           result.Answer,
+		"repairs":          result.Repairs,
+		"stop_reason":      result.StopReason,
+		"model_calls":      result.ModelCalls,
+		"trace":            result.Trace,
+	}
+	if result.Parsed != nil {
+		output[taskResultKey] = result.Parsed
+	}
+	// Workspaces are reused by later tasks, so the edits are kept as a diff and undone
+	diff, err := diffAndReset(context.WithoutCancel(ctx), dir)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to reset workspace after local agent", "error", err)
+	}
+	if diff != "" {
+		output["diff"] = truncateDiff(diff)
+	}
+
+	if loopErr != nil {
+		return output, fmt.Errorf("local agent stopped: %w", loopErr)
+	}
+	return output, nil
+}
+
+// newLLMInteraction converts a model call of the tool-calling loop for the LLM trace
+func newLLMInteraction(task *models.TaskWithFullContext, executor string, interaction toolloop.Interaction) *models.LLMInteraction {
+	logged := &models.LLMInteraction{
+		TaskID:        task.TaskID,
+		ProjectID:     task.ProjectID,
+		Executor:      executor,
+		Step:          interaction.ModelCall,
+		Prompt:        make([]models.LLMMessage, 0, len(interaction.Prompt)),
+		ContextDocIDs: interaction.ContextDocs,
+		LatencyMS:     interaction.Latency.Milliseconds(),
+		Error:         interaction.Error,
+		CreatedAt:     interaction.StartedAt.UTC(),
+	}
+	for _, message := range interaction.Prompt {
+		logged.Prompt = append(logged.Prompt, toLLMMessage(message))
+	}
+	if interaction.Error == "" {
+		response := toLLMMessage(interaction.Response)
+		response.Role = agent.ChatRoleAssistant
+		logged.Response = &response
+	}
+	if usage := interaction.Response.Usage; usage != nil {
+		logged.PromptTokens = usage.PromptTokens
+		logged.ResponseTokens = usage.ResponseTokens
+	}
+	return logged
+}
+
+// toLLMMessage copies a chat message, so that masking it leaves the chat untouched
+func toLLMMessage(message agent.ChatMessage) models.LLMMessage {
+	converted := models.LLMMessage{Role: message.Role, Content: message.Content, ToolName: message.ToolName}
+	for _, call := range message.ToolCalls {
+		converted.ToolCalls = append(converted.ToolCalls, models.LLMToolCall{Name: call.Name, Arguments: maps.Clone(call.Arguments)})
+	}
+	return converted
+}
+
+// diffAndReset returns the diff of the changes made in a checkout and discards them
+func diffAndReset(ctx context.Context, dir string) (string, error) {
+	if out, err := exec.CommandContext(ctx, "git", "-C", dir, "add", "--all").CombinedOutput(); err != nil {
+		return "", fmt.Errorf("failed to stage changes: %w: %s", err, out)
+	}
+	diff, err := exec.CommandContext(ctx, "git", "-C", dir, "diff", "--cached").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to diff changes: %w", err)
+	}
+	if out, err := exec.CommandContext(ctx, "git", "-C", dir, "reset", "--hard", "--quiet").CombinedOutput(); err != nil {
+		return string(diff), fmt.Errorf("failed to discard changes: %w: %s", err, out)
+	}
+	return string(diff), nil
+}
+
+// truncateDiff bounds a diff kept in an execution's output to maxExecutionDiffLength
+func truncateDiff(diff string) string {
+	if len(diff) > maxExecutionDiffLength {
+		return diff[:maxExecutionDiffLength] + truncatedDiffMarker
+	}
+	return diff
+}
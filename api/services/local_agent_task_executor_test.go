@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	servicesMocks "github.com/kazemisoroush/code-refactoring-tool/api/services/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/agent"
+	agentMocks "github.com/kazemisoroush/code-refactoring-tool/pkg/ai/agent/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/toolloop"
+)
+
+// newGitCheckout creates a repository with one committed file
+func newGitCheckout(t *testing.T) string {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Demo\nOld text\n"), 0o644))
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "--all"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "initial"},
+	} {
+		out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	return dir
+}
+
+func TestLocalAgentTaskExecutor_Execute_KeepsTraceAndDiff(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir := newGitCheckout(t)
+	model := agentMocks.NewMockChatModel(ctrl)
+	cloner := servicesMocks.NewMockCodebaseCloner(ctrl)
+	codebase := &models.Codebase{CodebaseID: "cb-1"}
+	task := &models.TaskWithFullContext{
+		Task:     models.Task{TaskID: "task-1", Type: models.TaskTypeDocumentation, Title: "Fix docs", Description: "Update the README"},
+		Codebase: codebase,
+	}
+
+	answer := `{"summary":"Updated the README.","documents":[{"file":"README.md","description":"Project overview"}]}`
+	released := false
+	cloner.EXPECT().Clone(gomock.Any(), "task-1", codebase, "", "").Return(dir, func() { released = true }, nil)
+	gomock.InOrder(
+		model.EXPECT().Chat(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, messages []agent.ChatMessage, _ []agent.ToolDefinition) (agent.ChatMessage, error) {
+				assert.Contains(t, messages[1].Content, "Update the README")
+				return agent.ChatMessage{ToolCalls: []agent.ToolCall{{Name: "apply_patch", Arguments: map[string]any{
+					"path": "README.md", "start_line": float64(2), "end_line": float64(2), "replacement": "New text",
+				}}}}, nil
+			}),
+		// A prose answer is invalid, and the model is asked to repair it
+		model.EXPECT().Chat(gomock.Any(), gomock.Any(), gomock.Any()).Return(agent.ChatMessage{Content: "Updated the README."}, nil),
+		model.EXPECT().Chat(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, messages []agent.ChatMessage, _ []agent.ToolDefinition) (agent.ChatMessage, error) {
+				assert.Contains(t, messages[len(messages)-1].Content, "Your answer is invalid: $: invalid JSON")
+				return agent.ChatMessage{Content: "```json\n" + answer + "\n```"}, nil
+			}),
+	)
+
+	var interactions []*models.LLMInteraction
+	llmTrace := servicesMocks.NewMockLLMTraceService(ctrl)
+	llmTrace.EXPECT().RecordInteraction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, interaction *models.LLMInteraction) error {
+			interactions = append(interactions, interaction)
+			return nil
+		}).Times(3)
+
+	executor, err := NewLocalAgentTaskExecutor(model, TaskContext{}, cloner, llmTrace, DefaultLocalAgentPromptVersion, 5, 1, "true")
+	require.NoError(t, err)
+	output, err := executor.Execute(context.Background(), task)
+
+	require.NoError(t, err)
+	require.Len(t, interactions, 3, "every model call is recorded")
+	assert.Equal(t, "task-1", interactions[1].TaskID)
+	assert.Equal(t, LocalAgentTaskExecutorName, interactions[1].Executor)
+	assert.Equal(t, 2, interactions[1].Step)
+	assert.Equal(t, "apply_patch", interactions[1].Prompt[0].ToolCalls[0].Name)
+	assert.Equal(t, "Updated the README.", interactions[1].Response.Content)
+	assert.Equal(t, "```json\n"+answer+"\n```", output["answer"], "the raw answer is kept")
+	assert.Equal(t, map[string]any{
+		"summary":   "Updated the README.",
+		"documents": []any{map[string]any{"file": "README.md", "description": "Project overview"}},
+	}, output["result"])
+	assert.Equal(t, 1, output["repairs"])
+	assert.Equal(t, DefaultLocalAgentPromptVersion, output["prompt_version"])
+	assert.Equal(t, toolloop.StopReasonAnswered, output["stop_reason"])
+	assert.Len(t, output["trace"], 5)
+	assert.Contains(t, output["diff"], "+New text")
+	assert.True(t, released)
+
+	// The edits are undone, since the workspace is reused
+	content, err := os.ReadFile(filepath.Join(dir, "README.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# Demo\nOld text\n", string(content))
+}
+
+func TestLocalAgentTaskExecutor_Execute_StepBudgetKeepsTrace(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir := newGitCheckout(t)
+	model := agentMocks.NewMockChatModel(ctrl)
+	cloner := servicesMocks.NewMockCodebaseCloner(ctrl)
+	task := &models.TaskWithFullContext{
+		Task:     models.Task{TaskID: "task-1", Type: models.TaskTypeCodeReview},
+		Codebase: &models.Codebase{CodebaseID: "cb-1"},
+	}
+
+	cloner.EXPECT().Clone(gomock.Any(), "task-1", gomock.Any(), "", "").Return(dir, func() {}, nil)
+	model.EXPECT().Chat(gomock.Any(), gomock.Any(), gomock.Any()).Return(agent.ChatMessage{
+		ToolCalls: []agent.ToolCall{{Name: "grep", Arguments: map[string]any{"pattern": "Demo"}}},
+	}, nil)
+
+	llmTrace := servicesMocks.NewMockLLMTraceService(ctrl)
+	llmTrace.EXPECT().RecordInteraction(gomock.Any(), gomock.Any()).Return(errors.New("database unavailable"))
+
+	// A failure to record a model call doesn't fail the task
+	executor, err := NewLocalAgentTaskExecutor(model, TaskContext{}, cloner, llmTrace, DefaultLocalAgentPromptVersion, 1, 1, "true")
+	require.NoError(t, err)
+	output, err := executor.Execute(context.Background(), task)
+
+	require.ErrorIs(t, err, toolloop.ErrStepBudgetExhausted)
+	assert.Equal(t, toolloop.StopReasonStepBudget, output["stop_reason"])
+	assert.Len(t, output["trace"], 2)
+	assert.NotContains(t, output, "diff")
+}
+
+func TestLocalAgentTaskExecutor_Execute_InvalidAnswerAfterRepairs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir := newGitCheckout(t)
+	model := agentMocks.NewMockChatModel(ctrl)
+	cloner := servicesMocks.NewMockCodebaseCloner(ctrl)
+	task := &models.TaskWithFullContext{
+		Task:     models.Task{TaskID: "task-1", Type: models.TaskTypeCodeReview},
+		Codebase: &models.Codebase{CodebaseID: "cb-1"},
+	}
+
+	cloner.EXPECT().Clone(gomock.Any(), "task-1", gomock.Any(), "", "").Return(dir, func() {}, nil)
+	model.EXPECT().Chat(gomock.Any(), gomock.Any(), gomock.Any()).Return(agent.ChatMessage{
+		Content: `{"summary":"Looks fine","comments":[{"file":"README.md","severity":"critical","message":"Typo"}]}`,
+	}, nil).Times(2)
+
+	llmTrace := servicesMocks.NewMockLLMTraceService(ctrl)
+	llmTrace.EXPECT().RecordInteraction(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+	executor, err := NewLocalAgentTaskExecutor(model, TaskContext{}, cloner, llmTrace, DefaultLocalAgentPromptVersion, 5, 1, "true")
+	require.NoError(t, err)
+	output, err := executor.Execute(context.Background(), task)
+
+	require.ErrorIs(t, err, toolloop.ErrInvalidAnswer)
+	assert.Contains(t, err.Error(), `$.comments[0].severity: must be one of "info", "warning", "error"`)
+	assert.Equal(t, toolloop.StopReasonInvalidAnswer, output["stop_reason"])
+	assert.NotContains(t, output, "result", "only validated answers are parsed")
+}
+
+func TestLocalAgentTaskExecutor_Supports(t *testing.T) {
+	executor, err := NewLocalAgentTaskExecutor(nil, TaskContext{}, nil, nil, DefaultLocalAgentPromptVersion, 1, 0, "")
+	require.NoError(t, err)
+
+	assert.True(t, executor.Supports(models.TaskTypeRefactoring))
+	assert.False(t, executor.Supports(models.TaskTypeDependencyAudit))
+	assert.False(t, executor.Supports(models.TaskTypeCodemod))
+}
+
+func TestNewLocalAgentTaskExecutor_UnknownPromptVersion(t *testing.T) {
+	_, err := NewLocalAgentTaskExecutor(nil, TaskContext{}, nil, nil, "v0", 1, 0, "")
+
+	assert.ErrorContains(t, err, `unknown local agent prompt version "v0", expected one of v1, v2`)
+}
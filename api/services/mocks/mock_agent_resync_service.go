@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/services (interfaces: AgentResyncService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockAgentResyncService is a mock of AgentResyncService interface.
+type MockAgentResyncService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAgentResyncServiceMockRecorder
+}
+
+// MockAgentResyncServiceMockRecorder is the mock recorder for MockAgentResyncService.
+type MockAgentResyncServiceMockRecorder struct {
+	mock *MockAgentResyncService
+}
+
+// NewMockAgentResyncService creates a new mock instance.
+func NewMockAgentResyncService(ctrl *gomock.Controller) *MockAgentResyncService {
+	mock := &MockAgentResyncService{ctrl: ctrl}
+	mock.recorder = &MockAgentResyncServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAgentResyncService) EXPECT() *MockAgentResyncServiceMockRecorder {
+	return m.recorder
+}
+
+// CheckCodebases mocks base method.
+func (m *MockAgentResyncService) CheckCodebases(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckCodebases", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckCodebases indicates an expected call of CheckCodebases.
+func (mr *MockAgentResyncServiceMockRecorder) CheckCodebases(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckCodebases", reflect.TypeOf((*MockAgentResyncService)(nil).CheckCodebases), arg0)
+}
+
+// GetSettings mocks base method.
+func (m *MockAgentResyncService) GetSettings(arg0 context.Context, arg1 models.GetAgentResyncSettingsRequest) (*models.AgentResyncSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSettings", arg0, arg1)
+	ret0, _ := ret[0].(*models.AgentResyncSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSettings indicates an expected call of GetSettings.
+func (mr *MockAgentResyncServiceMockRecorder) GetSettings(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSettings", reflect.TypeOf((*MockAgentResyncService)(nil).GetSettings), arg0, arg1)
+}
+
+// UpdateSettings mocks base method.
+func (m *MockAgentResyncService) UpdateSettings(arg0 context.Context, arg1 models.UpdateAgentResyncSettingsRequest) (*models.AgentResyncSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSettings", arg0, arg1)
+	ret0, _ := ret[0].(*models.AgentResyncSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSettings indicates an expected call of UpdateSettings.
+func (mr *MockAgentResyncServiceMockRecorder) UpdateSettings(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSettings", reflect.TypeOf((*MockAgentResyncService)(nil).UpdateSettings), arg0, arg1)
+}
@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/services (interfaces: AgentRetrievalService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockAgentRetrievalService is a mock of AgentRetrievalService interface.
+type MockAgentRetrievalService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAgentRetrievalServiceMockRecorder
+}
+
+// MockAgentRetrievalServiceMockRecorder is the mock recorder for MockAgentRetrievalService.
+type MockAgentRetrievalServiceMockRecorder struct {
+	mock *MockAgentRetrievalService
+}
+
+// NewMockAgentRetrievalService creates a new mock instance.
+func NewMockAgentRetrievalService(ctrl *gomock.Controller) *MockAgentRetrievalService {
+	mock := &MockAgentRetrievalService{ctrl: ctrl}
+	mock.recorder = &MockAgentRetrievalServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAgentRetrievalService) EXPECT() *MockAgentRetrievalServiceMockRecorder {
+	return m.recorder
+}
+
+// RetrieveChunks mocks base method.
+func (m *MockAgentRetrievalService) RetrieveChunks(arg0 context.Context, arg1 models.RetrieveAgentChunksRequest) (*models.RetrieveAgentChunksResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveChunks", arg0, arg1)
+	ret0, _ := ret[0].(*models.RetrieveAgentChunksResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveChunks indicates an expected call of RetrieveChunks.
+func (mr *MockAgentRetrievalServiceMockRecorder) RetrieveChunks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveChunks", reflect.TypeOf((*MockAgentRetrievalService)(nil).RetrieveChunks), arg0, arg1)
+}
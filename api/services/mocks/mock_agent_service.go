@@ -10,6 +10,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+	config "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
 )
 
 // MockAgentService is a mock of AgentService interface.
@@ -80,6 +81,36 @@ func (mr *MockAgentServiceMockRecorder) GetAgent(arg0, arg1 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAgent", reflect.TypeOf((*MockAgentService)(nil).GetAgent), arg0, arg1)
 }
 
+// GetAgentSetup mocks base method.
+func (m *MockAgentService) GetAgentSetup(arg0 context.Context, arg1 string) (*models.AgentSetup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAgentSetup", arg0, arg1)
+	ret0, _ := ret[0].(*models.AgentSetup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAgentSetup indicates an expected call of GetAgentSetup.
+func (mr *MockAgentServiceMockRecorder) GetAgentSetup(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAgentSetup", reflect.TypeOf((*MockAgentService)(nil).GetAgentSetup), arg0, arg1)
+}
+
+// ListAgentSetups mocks base method.
+func (m *MockAgentService) ListAgentSetups(arg0 context.Context, arg1 models.ListAgentSetupsRequest) (*models.ListAgentSetupsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAgentSetups", arg0, arg1)
+	ret0, _ := ret[0].(*models.ListAgentSetupsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAgentSetups indicates an expected call of ListAgentSetups.
+func (mr *MockAgentServiceMockRecorder) ListAgentSetups(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAgentSetups", reflect.TypeOf((*MockAgentService)(nil).ListAgentSetups), arg0, arg1)
+}
+
 // ListAgents mocks base method.
 func (m *MockAgentService) ListAgents(arg0 context.Context, arg1 models.ListAgentsRequest) (*models.ListAgentsResponse, error) {
 	m.ctrl.T.Helper()
@@ -95,6 +126,66 @@ func (mr *MockAgentServiceMockRecorder) ListAgents(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAgents", reflect.TypeOf((*MockAgentService)(nil).ListAgents), arg0, arg1)
 }
 
+// RebuildAgent mocks base method.
+func (m *MockAgentService) RebuildAgent(arg0 context.Context, arg1 string) (*models.UpdateAgentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RebuildAgent", arg0, arg1)
+	ret0, _ := ret[0].(*models.UpdateAgentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RebuildAgent indicates an expected call of RebuildAgent.
+func (mr *MockAgentServiceMockRecorder) RebuildAgent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RebuildAgent", reflect.TypeOf((*MockAgentService)(nil).RebuildAgent), arg0, arg1)
+}
+
+// ReembedAgent mocks base method.
+func (m *MockAgentService) ReembedAgent(arg0 context.Context, arg1 string, arg2 config.Embedding) (*models.UpdateAgentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReembedAgent", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.UpdateAgentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReembedAgent indicates an expected call of ReembedAgent.
+func (mr *MockAgentServiceMockRecorder) ReembedAgent(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReembedAgent", reflect.TypeOf((*MockAgentService)(nil).ReembedAgent), arg0, arg1, arg2)
+}
+
+// ResumeAgentSetup mocks base method.
+func (m *MockAgentService) ResumeAgentSetup(arg0 context.Context, arg1 string) (*models.AgentSetup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResumeAgentSetup", arg0, arg1)
+	ret0, _ := ret[0].(*models.AgentSetup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResumeAgentSetup indicates an expected call of ResumeAgentSetup.
+func (mr *MockAgentServiceMockRecorder) ResumeAgentSetup(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeAgentSetup", reflect.TypeOf((*MockAgentService)(nil).ResumeAgentSetup), arg0, arg1)
+}
+
+// TearDownAgentSetup mocks base method.
+func (m *MockAgentService) TearDownAgentSetup(arg0 context.Context, arg1 string) (*models.AgentSetup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TearDownAgentSetup", arg0, arg1)
+	ret0, _ := ret[0].(*models.AgentSetup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TearDownAgentSetup indicates an expected call of TearDownAgentSetup.
+func (mr *MockAgentServiceMockRecorder) TearDownAgentSetup(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TearDownAgentSetup", reflect.TypeOf((*MockAgentService)(nil).TearDownAgentSetup), arg0, arg1)
+}
+
 // UpdateAgent mocks base method.
 func (m *MockAgentService) UpdateAgent(arg0 context.Context, arg1 models.UpdateAgentRequest) (*models.UpdateAgentResponse, error) {
 	m.ctrl.T.Helper()
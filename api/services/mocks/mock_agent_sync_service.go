@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/services (interfaces: AgentSyncService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockAgentSyncService is a mock of AgentSyncService interface.
+type MockAgentSyncService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAgentSyncServiceMockRecorder
+}
+
+// MockAgentSyncServiceMockRecorder is the mock recorder for MockAgentSyncService.
+type MockAgentSyncServiceMockRecorder struct {
+	mock *MockAgentSyncService
+}
+
+// NewMockAgentSyncService creates a new mock instance.
+func NewMockAgentSyncService(ctrl *gomock.Controller) *MockAgentSyncService {
+	mock := &MockAgentSyncService{ctrl: ctrl}
+	mock.recorder = &MockAgentSyncServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAgentSyncService) EXPECT() *MockAgentSyncServiceMockRecorder {
+	return m.recorder
+}
+
+// GetSyncStatus mocks base method.
+func (m *MockAgentSyncService) GetSyncStatus(arg0 context.Context, arg1 models.GetAgentSyncStatusRequest) (*models.AgentSyncStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSyncStatus", arg0, arg1)
+	ret0, _ := ret[0].(*models.AgentSyncStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSyncStatus indicates an expected call of GetSyncStatus.
+func (mr *MockAgentSyncServiceMockRecorder) GetSyncStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSyncStatus", reflect.TypeOf((*MockAgentSyncService)(nil).GetSyncStatus), arg0, arg1)
+}
+
+// SyncAgent mocks base method.
+func (m *MockAgentSyncService) SyncAgent(arg0 context.Context, arg1 models.SyncAgentRequest) (*models.AgentSyncStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncAgent", arg0, arg1)
+	ret0, _ := ret[0].(*models.AgentSyncStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SyncAgent indicates an expected call of SyncAgent.
+func (mr *MockAgentSyncServiceMockRecorder) SyncAgent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncAgent", reflect.TypeOf((*MockAgentSyncService)(nil).SyncAgent), arg0, arg1)
+}
@@ -35,6 +35,20 @@ func (m *MockAuthService) EXPECT() *MockAuthServiceMockRecorder {
 	return m.recorder
 }
 
+// ChangePassword mocks base method.
+func (m *MockAuthService) ChangePassword(ctx context.Context, req *models.ChangePasswordRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockAuthServiceMockRecorder) ChangePassword(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockAuthService)(nil).ChangePassword), ctx, req)
+}
+
 // ConfirmEmail mocks base method.
 func (m *MockAuthService) ConfirmEmail(ctx context.Context, req *models.ConfirmEmailRequest) error {
 	m.ctrl.T.Helper()
@@ -64,6 +78,20 @@ func (mr *MockAuthServiceMockRecorder) CreateUser(ctx, req interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockAuthService)(nil).CreateUser), ctx, req)
 }
 
+// DeleteAccount mocks base method.
+func (m *MockAuthService) DeleteAccount(ctx context.Context, req *models.DeleteAccountRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccount", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAccount indicates an expected call of DeleteAccount.
+func (mr *MockAuthServiceMockRecorder) DeleteAccount(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockAuthService)(nil).DeleteAccount), ctx, req)
+}
+
 // DeleteUser mocks base method.
 func (m *MockAuthService) DeleteUser(ctx context.Context, userID string) error {
 	m.ctrl.T.Helper()
@@ -122,6 +150,21 @@ func (mr *MockAuthServiceMockRecorder) ListUsers(ctx, req interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockAuthService)(nil).ListUsers), ctx, req)
 }
 
+// RecoverMFA mocks base method.
+func (m *MockAuthService) RecoverMFA(ctx context.Context, req *models.MFARecoverRequest) (*models.SignInResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecoverMFA", ctx, req)
+	ret0, _ := ret[0].(*models.SignInResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecoverMFA indicates an expected call of RecoverMFA.
+func (mr *MockAuthServiceMockRecorder) RecoverMFA(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecoverMFA", reflect.TypeOf((*MockAuthService)(nil).RecoverMFA), ctx, req)
+}
+
 // RefreshToken mocks base method.
 func (m *MockAuthService) RefreshToken(ctx context.Context, req *models.RefreshTokenRequest) (*models.RefreshTokenResponse, error) {
 	m.ctrl.T.Helper()
@@ -137,6 +180,21 @@ func (mr *MockAuthServiceMockRecorder) RefreshToken(ctx, req interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshToken", reflect.TypeOf((*MockAuthService)(nil).RefreshToken), ctx, req)
 }
 
+// RegenerateRecoveryCodes mocks base method.
+func (m *MockAuthService) RegenerateRecoveryCodes(ctx context.Context, authID string) (*models.MFARecoveryCodesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegenerateRecoveryCodes", ctx, authID)
+	ret0, _ := ret[0].(*models.MFARecoveryCodesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegenerateRecoveryCodes indicates an expected call of RegenerateRecoveryCodes.
+func (mr *MockAuthServiceMockRecorder) RegenerateRecoveryCodes(ctx, authID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegenerateRecoveryCodes", reflect.TypeOf((*MockAuthService)(nil).RegenerateRecoveryCodes), ctx, authID)
+}
+
 // ResetPassword mocks base method.
 func (m *MockAuthService) ResetPassword(ctx context.Context, req *models.ResetPasswordRequest) error {
 	m.ctrl.T.Helper()
@@ -151,6 +209,36 @@ func (mr *MockAuthServiceMockRecorder) ResetPassword(ctx, req interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockAuthService)(nil).ResetPassword), ctx, req)
 }
 
+// RespondToMFAChallenge mocks base method.
+func (m *MockAuthService) RespondToMFAChallenge(ctx context.Context, req *models.MFAChallengeRequest) (*models.SignInResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RespondToMFAChallenge", ctx, req)
+	ret0, _ := ret[0].(*models.SignInResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RespondToMFAChallenge indicates an expected call of RespondToMFAChallenge.
+func (mr *MockAuthServiceMockRecorder) RespondToMFAChallenge(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RespondToMFAChallenge", reflect.TypeOf((*MockAuthService)(nil).RespondToMFAChallenge), ctx, req)
+}
+
+// SetupMFA mocks base method.
+func (m *MockAuthService) SetupMFA(ctx context.Context, req *models.MFASetupRequest) (*models.MFASetupResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetupMFA", ctx, req)
+	ret0, _ := ret[0].(*models.MFASetupResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetupMFA indicates an expected call of SetupMFA.
+func (mr *MockAuthServiceMockRecorder) SetupMFA(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetupMFA", reflect.TypeOf((*MockAuthService)(nil).SetupMFA), ctx, req)
+}
+
 // SignIn mocks base method.
 func (m *MockAuthService) SignIn(ctx context.Context, req *models.SignInRequest) (*models.SignInResponse, error) {
 	m.ctrl.T.Helper()
@@ -195,6 +283,21 @@ func (mr *MockAuthServiceMockRecorder) SignUp(ctx, req interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignUp", reflect.TypeOf((*MockAuthService)(nil).SignUp), ctx, req)
 }
 
+// UpdateProfile mocks base method.
+func (m *MockAuthService) UpdateProfile(ctx context.Context, req *models.UpdateProfileRequest) (*models.UpdateUserResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProfile", ctx, req)
+	ret0, _ := ret[0].(*models.UpdateUserResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProfile indicates an expected call of UpdateProfile.
+func (mr *MockAuthServiceMockRecorder) UpdateProfile(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfile", reflect.TypeOf((*MockAuthService)(nil).UpdateProfile), ctx, req)
+}
+
 // UpdateUser mocks base method.
 func (m *MockAuthService) UpdateUser(ctx context.Context, req *models.UpdateUserRequest) (*models.UpdateUserResponse, error) {
 	m.ctrl.T.Helper()
@@ -224,3 +327,18 @@ func (mr *MockAuthServiceMockRecorder) ValidateToken(ctx, token interface{}) *go
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateToken", reflect.TypeOf((*MockAuthService)(nil).ValidateToken), ctx, token)
 }
+
+// VerifyMFA mocks base method.
+func (m *MockAuthService) VerifyMFA(ctx context.Context, req *models.VerifyMFARequest) (*models.MFARecoveryCodesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyMFA", ctx, req)
+	ret0, _ := ret[0].(*models.MFARecoveryCodesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyMFA indicates an expected call of VerifyMFA.
+func (mr *MockAuthServiceMockRecorder) VerifyMFA(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyMFA", reflect.TypeOf((*MockAuthService)(nil).VerifyMFA), ctx, req)
+}
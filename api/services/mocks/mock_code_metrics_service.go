@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/services (interfaces: CodeMetricsService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockCodeMetricsService is a mock of CodeMetricsService interface.
+type MockCodeMetricsService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCodeMetricsServiceMockRecorder
+}
+
+// MockCodeMetricsServiceMockRecorder is the mock recorder for MockCodeMetricsService.
+type MockCodeMetricsServiceMockRecorder struct {
+	mock *MockCodeMetricsService
+}
+
+// NewMockCodeMetricsService creates a new mock instance.
+func NewMockCodeMetricsService(ctrl *gomock.Controller) *MockCodeMetricsService {
+	mock := &MockCodeMetricsService{ctrl: ctrl}
+	mock.recorder = &MockCodeMetricsServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCodeMetricsService) EXPECT() *MockCodeMetricsServiceMockRecorder {
+	return m.recorder
+}
+
+// GetMetrics mocks base method.
+func (m *MockCodeMetricsService) GetMetrics(arg0 context.Context, arg1 models.GetCodeMetricsRequest) (*models.GetCodeMetricsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMetrics", arg0, arg1)
+	ret0, _ := ret[0].(*models.GetCodeMetricsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMetrics indicates an expected call of GetMetrics.
+func (mr *MockCodeMetricsServiceMockRecorder) GetMetrics(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetrics", reflect.TypeOf((*MockCodeMetricsService)(nil).GetMetrics), arg0, arg1)
+}
+
+// RecordSnapshot mocks base method.
+func (m *MockCodeMetricsService) RecordSnapshot(arg0 context.Context, arg1 *models.TaskWithFullContext, arg2 string) (*models.CodeMetricsSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordSnapshot", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.CodeMetricsSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordSnapshot indicates an expected call of RecordSnapshot.
+func (mr *MockCodeMetricsServiceMockRecorder) RecordSnapshot(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordSnapshot", reflect.TypeOf((*MockCodeMetricsService)(nil).RecordSnapshot), arg0, arg1, arg2)
+}
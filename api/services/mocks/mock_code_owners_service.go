@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/services (interfaces: CodeOwnersService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockCodeOwnersService is a mock of CodeOwnersService interface.
+type MockCodeOwnersService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCodeOwnersServiceMockRecorder
+}
+
+// MockCodeOwnersServiceMockRecorder is the mock recorder for MockCodeOwnersService.
+type MockCodeOwnersServiceMockRecorder struct {
+	mock *MockCodeOwnersService
+}
+
+// NewMockCodeOwnersService creates a new mock instance.
+func NewMockCodeOwnersService(ctrl *gomock.Controller) *MockCodeOwnersService {
+	mock := &MockCodeOwnersService{ctrl: ctrl}
+	mock.recorder = &MockCodeOwnersServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCodeOwnersService) EXPECT() *MockCodeOwnersServiceMockRecorder {
+	return m.recorder
+}
+
+// GetOwners mocks base method.
+func (m *MockCodeOwnersService) GetOwners(arg0 context.Context, arg1 models.GetCodeOwnersRequest) (*models.GetCodeOwnersResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOwners", arg0, arg1)
+	ret0, _ := ret[0].(*models.GetCodeOwnersResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOwners indicates an expected call of GetOwners.
+func (mr *MockCodeOwnersServiceMockRecorder) GetOwners(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOwners", reflect.TypeOf((*MockCodeOwnersService)(nil).GetOwners), arg0, arg1)
+}
+
+// RecordOwners mocks base method.
+func (m *MockCodeOwnersService) RecordOwners(arg0 context.Context, arg1, arg2, arg3 string) (*models.CodeOwners, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordOwners", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*models.CodeOwners)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordOwners indicates an expected call of RecordOwners.
+func (mr *MockCodeOwnersServiceMockRecorder) RecordOwners(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordOwners", reflect.TypeOf((*MockCodeOwnersService)(nil).RecordOwners), arg0, arg1, arg2, arg3)
+}
+
+// ResolveOwners mocks base method.
+func (m *MockCodeOwnersService) ResolveOwners(arg0 context.Context, arg1 string, arg2 []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveOwners", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveOwners indicates an expected call of ResolveOwners.
+func (mr *MockCodeOwnersServiceMockRecorder) ResolveOwners(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveOwners", reflect.TypeOf((*MockCodeOwnersService)(nil).ResolveOwners), arg0, arg1, arg2)
+}
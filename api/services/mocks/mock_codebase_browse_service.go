@@ -0,0 +1,111 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/services (interfaces: CodebaseBrowseService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockCodebaseBrowseService is a mock of CodebaseBrowseService interface.
+type MockCodebaseBrowseService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCodebaseBrowseServiceMockRecorder
+}
+
+// MockCodebaseBrowseServiceMockRecorder is the mock recorder for MockCodebaseBrowseService.
+type MockCodebaseBrowseServiceMockRecorder struct {
+	mock *MockCodebaseBrowseService
+}
+
+// NewMockCodebaseBrowseService creates a new mock instance.
+func NewMockCodebaseBrowseService(ctrl *gomock.Controller) *MockCodebaseBrowseService {
+	mock := &MockCodebaseBrowseService{ctrl: ctrl}
+	mock.recorder = &MockCodebaseBrowseServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCodebaseBrowseService) EXPECT() *MockCodebaseBrowseServiceMockRecorder {
+	return m.recorder
+}
+
+// GetDefaultBranchHead mocks base method.
+func (m *MockCodebaseBrowseService) GetDefaultBranchHead(arg0 context.Context, arg1 string) (*models.CodebaseRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDefaultBranchHead", arg0, arg1)
+	ret0, _ := ret[0].(*models.CodebaseRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDefaultBranchHead indicates an expected call of GetDefaultBranchHead.
+func (mr *MockCodebaseBrowseServiceMockRecorder) GetDefaultBranchHead(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDefaultBranchHead", reflect.TypeOf((*MockCodebaseBrowseService)(nil).GetDefaultBranchHead), arg0, arg1)
+}
+
+// ListBranches mocks base method.
+func (m *MockCodebaseBrowseService) ListBranches(arg0 context.Context, arg1 models.ListCodebaseBranchesRequest) (*models.ListCodebaseBranchesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBranches", arg0, arg1)
+	ret0, _ := ret[0].(*models.ListCodebaseBranchesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBranches indicates an expected call of ListBranches.
+func (mr *MockCodebaseBrowseServiceMockRecorder) ListBranches(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBranches", reflect.TypeOf((*MockCodebaseBrowseService)(nil).ListBranches), arg0, arg1)
+}
+
+// ListCommits mocks base method.
+func (m *MockCodebaseBrowseService) ListCommits(arg0 context.Context, arg1 models.ListCodebaseCommitsRequest) (*models.ListCodebaseCommitsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCommits", arg0, arg1)
+	ret0, _ := ret[0].(*models.ListCodebaseCommitsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCommits indicates an expected call of ListCommits.
+func (mr *MockCodebaseBrowseServiceMockRecorder) ListCommits(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCommits", reflect.TypeOf((*MockCodebaseBrowseService)(nil).ListCommits), arg0, arg1)
+}
+
+// ListFiles mocks base method.
+func (m *MockCodebaseBrowseService) ListFiles(arg0 context.Context, arg1 models.ListCodebaseFilesRequest) (*models.ListCodebaseFilesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFiles", arg0, arg1)
+	ret0, _ := ret[0].(*models.ListCodebaseFilesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFiles indicates an expected call of ListFiles.
+func (mr *MockCodebaseBrowseServiceMockRecorder) ListFiles(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFiles", reflect.TypeOf((*MockCodebaseBrowseService)(nil).ListFiles), arg0, arg1)
+}
+
+// ResolveRevision mocks base method.
+func (m *MockCodebaseBrowseService) ResolveRevision(arg0 context.Context, arg1, arg2, arg3 string) (*models.CodebaseRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveRevision", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*models.CodebaseRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveRevision indicates an expected call of ResolveRevision.
+func (mr *MockCodebaseBrowseServiceMockRecorder) ResolveRevision(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveRevision", reflect.TypeOf((*MockCodebaseBrowseService)(nil).ResolveRevision), arg0, arg1, arg2, arg3)
+}
@@ -0,0 +1,52 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/services (interfaces: CodebaseCloner)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockCodebaseCloner is a mock of CodebaseCloner interface.
+type MockCodebaseCloner struct {
+	ctrl     *gomock.Controller
+	recorder *MockCodebaseClonerMockRecorder
+}
+
+// MockCodebaseClonerMockRecorder is the mock recorder for MockCodebaseCloner.
+type MockCodebaseClonerMockRecorder struct {
+	mock *MockCodebaseCloner
+}
+
+// NewMockCodebaseCloner creates a new mock instance.
+func NewMockCodebaseCloner(ctrl *gomock.Controller) *MockCodebaseCloner {
+	mock := &MockCodebaseCloner{ctrl: ctrl}
+	mock.recorder = &MockCodebaseClonerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCodebaseCloner) EXPECT() *MockCodebaseClonerMockRecorder {
+	return m.recorder
+}
+
+// Clone mocks base method.
+func (m *MockCodebaseCloner) Clone(arg0 context.Context, arg1 string, arg2 *models.Codebase, arg3, arg4 string) (string, func(), error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Clone", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(func())
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Clone indicates an expected call of Clone.
+func (mr *MockCodebaseClonerMockRecorder) Clone(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Clone", reflect.TypeOf((*MockCodebaseCloner)(nil).Clone), arg0, arg1, arg2, arg3, arg4)
+}
@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/services (interfaces: CodebaseUploadService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockCodebaseUploadService is a mock of CodebaseUploadService interface.
+type MockCodebaseUploadService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCodebaseUploadServiceMockRecorder
+}
+
+// MockCodebaseUploadServiceMockRecorder is the mock recorder for MockCodebaseUploadService.
+type MockCodebaseUploadServiceMockRecorder struct {
+	mock *MockCodebaseUploadService
+}
+
+// NewMockCodebaseUploadService creates a new mock instance.
+func NewMockCodebaseUploadService(ctrl *gomock.Controller) *MockCodebaseUploadService {
+	mock := &MockCodebaseUploadService{ctrl: ctrl}
+	mock.recorder = &MockCodebaseUploadServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCodebaseUploadService) EXPECT() *MockCodebaseUploadServiceMockRecorder {
+	return m.recorder
+}
+
+// CompleteCodebaseUpload mocks base method.
+func (m *MockCodebaseUploadService) CompleteCodebaseUpload(arg0 context.Context, arg1 string) (*models.GetCodebaseResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteCodebaseUpload", arg0, arg1)
+	ret0, _ := ret[0].(*models.GetCodebaseResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteCodebaseUpload indicates an expected call of CompleteCodebaseUpload.
+func (mr *MockCodebaseUploadServiceMockRecorder) CompleteCodebaseUpload(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteCodebaseUpload", reflect.TypeOf((*MockCodebaseUploadService)(nil).CompleteCodebaseUpload), arg0, arg1)
+}
+
+// CreateCodebaseUpload mocks base method.
+func (m *MockCodebaseUploadService) CreateCodebaseUpload(arg0 context.Context, arg1 models.CreateCodebaseUploadRequest) (*models.CreateCodebaseUploadResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCodebaseUpload", arg0, arg1)
+	ret0, _ := ret[0].(*models.CreateCodebaseUploadResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCodebaseUpload indicates an expected call of CreateCodebaseUpload.
+func (mr *MockCodebaseUploadServiceMockRecorder) CreateCodebaseUpload(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCodebaseUpload", reflect.TypeOf((*MockCodebaseUploadService)(nil).CreateCodebaseUpload), arg0, arg1)
+}
+
+// DeleteExpiredCodebases mocks base method.
+func (m *MockCodebaseUploadService) DeleteExpiredCodebases(arg0 context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredCodebases", arg0)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredCodebases indicates an expected call of DeleteExpiredCodebases.
+func (mr *MockCodebaseUploadServiceMockRecorder) DeleteExpiredCodebases(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredCodebases", reflect.TypeOf((*MockCodebaseUploadService)(nil).DeleteExpiredCodebases), arg0)
+}
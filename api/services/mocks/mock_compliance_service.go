@@ -0,0 +1,78 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/services (interfaces: ComplianceService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockComplianceService is a mock of ComplianceService interface.
+type MockComplianceService struct {
+	ctrl     *gomock.Controller
+	recorder *MockComplianceServiceMockRecorder
+}
+
+// MockComplianceServiceMockRecorder is the mock recorder for MockComplianceService.
+type MockComplianceServiceMockRecorder struct {
+	mock *MockComplianceService
+}
+
+// NewMockComplianceService creates a new mock instance.
+func NewMockComplianceService(ctrl *gomock.Controller) *MockComplianceService {
+	mock := &MockComplianceService{ctrl: ctrl}
+	mock.recorder = &MockComplianceServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockComplianceService) EXPECT() *MockComplianceServiceMockRecorder {
+	return m.recorder
+}
+
+// ExportAccessGrants mocks base method.
+func (m *MockComplianceService) ExportAccessGrants(arg0 context.Context, arg1 models.ComplianceExportRequest, arg2 func(models.ProjectMember) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportAccessGrants", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportAccessGrants indicates an expected call of ExportAccessGrants.
+func (mr *MockComplianceServiceMockRecorder) ExportAccessGrants(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportAccessGrants", reflect.TypeOf((*MockComplianceService)(nil).ExportAccessGrants), arg0, arg1, arg2)
+}
+
+// ExportAuditEvents mocks base method.
+func (m *MockComplianceService) ExportAuditEvents(arg0 context.Context, arg1 models.ComplianceExportRequest, arg2 func(models.AuditEvent) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportAuditEvents", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportAuditEvents indicates an expected call of ExportAuditEvents.
+func (mr *MockComplianceServiceMockRecorder) ExportAuditEvents(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportAuditEvents", reflect.TypeOf((*MockComplianceService)(nil).ExportAuditEvents), arg0, arg1, arg2)
+}
+
+// ExportUsers mocks base method.
+func (m *MockComplianceService) ExportUsers(arg0 context.Context, arg1 models.ComplianceExportRequest, arg2 func(models.APIUser) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportUsers", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportUsers indicates an expected call of ExportUsers.
+func (mr *MockComplianceServiceMockRecorder) ExportUsers(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportUsers", reflect.TypeOf((*MockComplianceService)(nil).ExportUsers), arg0, arg1, arg2)
+}
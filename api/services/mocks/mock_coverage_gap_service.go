@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/services (interfaces: CoverageGapService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockCoverageGapService is a mock of CoverageGapService interface.
+type MockCoverageGapService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCoverageGapServiceMockRecorder
+}
+
+// MockCoverageGapServiceMockRecorder is the mock recorder for MockCoverageGapService.
+type MockCoverageGapServiceMockRecorder struct {
+	mock *MockCoverageGapService
+}
+
+// NewMockCoverageGapService creates a new mock instance.
+func NewMockCoverageGapService(ctrl *gomock.Controller) *MockCoverageGapService {
+	mock := &MockCoverageGapService{ctrl: ctrl}
+	mock.recorder = &MockCoverageGapServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCoverageGapService) EXPECT() *MockCoverageGapServiceMockRecorder {
+	return m.recorder
+}
+
+// FindGaps mocks base method.
+func (m *MockCoverageGapService) FindGaps(arg0 context.Context, arg1 *models.TaskWithFullContext) (*models.CoverageGapReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindGaps", arg0, arg1)
+	ret0, _ := ret[0].(*models.CoverageGapReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindGaps indicates an expected call of FindGaps.
+func (mr *MockCoverageGapServiceMockRecorder) FindGaps(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindGaps", reflect.TypeOf((*MockCoverageGapService)(nil).FindGaps), arg0, arg1)
+}
+
+// PublishTests mocks base method.
+func (m *MockCoverageGapService) PublishTests(arg0 context.Context, arg1 *models.TaskWithFullContext, arg2 *models.CoverageGapReport, arg3 string) (*models.PullRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishTests", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*models.PullRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishTests indicates an expected call of PublishTests.
+func (mr *MockCoverageGapServiceMockRecorder) PublishTests(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishTests", reflect.TypeOf((*MockCoverageGapService)(nil).PublishTests), arg0, arg1, arg2, arg3)
+}
@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/services (interfaces: DependencyAuditService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockDependencyAuditService is a mock of DependencyAuditService interface.
+type MockDependencyAuditService struct {
+	ctrl     *gomock.Controller
+	recorder *MockDependencyAuditServiceMockRecorder
+}
+
+// MockDependencyAuditServiceMockRecorder is the mock recorder for MockDependencyAuditService.
+type MockDependencyAuditServiceMockRecorder struct {
+	mock *MockDependencyAuditService
+}
+
+// NewMockDependencyAuditService creates a new mock instance.
+func NewMockDependencyAuditService(ctrl *gomock.Controller) *MockDependencyAuditService {
+	mock := &MockDependencyAuditService{ctrl: ctrl}
+	mock.recorder = &MockDependencyAuditServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDependencyAuditService) EXPECT() *MockDependencyAuditServiceMockRecorder {
+	return m.recorder
+}
+
+// AuditCodebase mocks base method.
+func (m *MockDependencyAuditService) AuditCodebase(arg0 context.Context, arg1 *models.TaskWithFullContext) (*models.DependencyAuditResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuditCodebase", arg0, arg1)
+	ret0, _ := ret[0].(*models.DependencyAuditResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuditCodebase indicates an expected call of AuditCodebase.
+func (mr *MockDependencyAuditServiceMockRecorder) AuditCodebase(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuditCodebase", reflect.TypeOf((*MockDependencyAuditService)(nil).AuditCodebase), arg0, arg1)
+}
+
+// ListFindings mocks base method.
+func (m *MockDependencyAuditService) ListFindings(arg0 context.Context, arg1 models.ListDependencyFindingsRequest) (*models.ListDependencyFindingsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFindings", arg0, arg1)
+	ret0, _ := ret[0].(*models.ListDependencyFindingsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFindings indicates an expected call of ListFindings.
+func (mr *MockDependencyAuditServiceMockRecorder) ListFindings(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFindings", reflect.TypeOf((*MockDependencyAuditService)(nil).ListFindings), arg0, arg1)
+}
@@ -0,0 +1,109 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/services (interfaces: DeviceAuthService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockDeviceAuthService is a mock of DeviceAuthService interface.
+type MockDeviceAuthService struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeviceAuthServiceMockRecorder
+}
+
+// MockDeviceAuthServiceMockRecorder is the mock recorder for MockDeviceAuthService.
+type MockDeviceAuthServiceMockRecorder struct {
+	mock *MockDeviceAuthService
+}
+
+// NewMockDeviceAuthService creates a new mock instance.
+func NewMockDeviceAuthService(ctrl *gomock.Controller) *MockDeviceAuthService {
+	mock := &MockDeviceAuthService{ctrl: ctrl}
+	mock.recorder = &MockDeviceAuthServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeviceAuthService) EXPECT() *MockDeviceAuthServiceMockRecorder {
+	return m.recorder
+}
+
+// ApproveDevice mocks base method.
+func (m *MockDeviceAuthService) ApproveDevice(arg0 context.Context, arg1 models.ApproveDeviceRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApproveDevice", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ApproveDevice indicates an expected call of ApproveDevice.
+func (mr *MockDeviceAuthServiceMockRecorder) ApproveDevice(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveDevice", reflect.TypeOf((*MockDeviceAuthService)(nil).ApproveDevice), arg0, arg1)
+}
+
+// DenyDevice mocks base method.
+func (m *MockDeviceAuthService) DenyDevice(arg0 context.Context, arg1 models.DenyDeviceRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DenyDevice", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DenyDevice indicates an expected call of DenyDevice.
+func (mr *MockDeviceAuthServiceMockRecorder) DenyDevice(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DenyDevice", reflect.TypeOf((*MockDeviceAuthService)(nil).DenyDevice), arg0, arg1)
+}
+
+// PollDeviceToken mocks base method.
+func (m *MockDeviceAuthService) PollDeviceToken(arg0 context.Context, arg1 models.DeviceTokenRequest) (*models.SignInResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PollDeviceToken", arg0, arg1)
+	ret0, _ := ret[0].(*models.SignInResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PollDeviceToken indicates an expected call of PollDeviceToken.
+func (mr *MockDeviceAuthServiceMockRecorder) PollDeviceToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PollDeviceToken", reflect.TypeOf((*MockDeviceAuthService)(nil).PollDeviceToken), arg0, arg1)
+}
+
+// PurgeExpired mocks base method.
+func (m *MockDeviceAuthService) PurgeExpired(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeExpired", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeExpired indicates an expected call of PurgeExpired.
+func (mr *MockDeviceAuthServiceMockRecorder) PurgeExpired(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeExpired", reflect.TypeOf((*MockDeviceAuthService)(nil).PurgeExpired), arg0)
+}
+
+// StartDeviceAuth mocks base method.
+func (m *MockDeviceAuthService) StartDeviceAuth(arg0 context.Context, arg1 models.StartDeviceAuthRequest) (*models.StartDeviceAuthResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartDeviceAuth", arg0, arg1)
+	ret0, _ := ret[0].(*models.StartDeviceAuthResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartDeviceAuth indicates an expected call of StartDeviceAuth.
+func (mr *MockDeviceAuthServiceMockRecorder) StartDeviceAuth(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartDeviceAuth", reflect.TypeOf((*MockDeviceAuthService)(nil).StartDeviceAuth), arg0, arg1)
+}
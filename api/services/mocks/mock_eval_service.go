@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/services (interfaces: EvalService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockEvalService is a mock of EvalService interface.
+type MockEvalService struct {
+	ctrl     *gomock.Controller
+	recorder *MockEvalServiceMockRecorder
+}
+
+// MockEvalServiceMockRecorder is the mock recorder for MockEvalService.
+type MockEvalServiceMockRecorder struct {
+	mock *MockEvalService
+}
+
+// NewMockEvalService creates a new mock instance.
+func NewMockEvalService(ctrl *gomock.Controller) *MockEvalService {
+	mock := &MockEvalService{ctrl: ctrl}
+	mock.recorder = &MockEvalServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEvalService) EXPECT() *MockEvalServiceMockRecorder {
+	return m.recorder
+}
+
+// CompareRuns mocks base method.
+func (m *MockEvalService) CompareRuns(arg0 context.Context, arg1 models.CompareEvalRunsRequest) (*models.EvalComparison, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompareRuns", arg0, arg1)
+	ret0, _ := ret[0].(*models.EvalComparison)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompareRuns indicates an expected call of CompareRuns.
+func (mr *MockEvalServiceMockRecorder) CompareRuns(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompareRuns", reflect.TypeOf((*MockEvalService)(nil).CompareRuns), arg0, arg1)
+}
+
+// GetRun mocks base method.
+func (m *MockEvalService) GetRun(arg0 context.Context, arg1 models.GetEvalRunRequest) (*models.EvalRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRun", arg0, arg1)
+	ret0, _ := ret[0].(*models.EvalRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRun indicates an expected call of GetRun.
+func (mr *MockEvalServiceMockRecorder) GetRun(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRun", reflect.TypeOf((*MockEvalService)(nil).GetRun), arg0, arg1)
+}
+
+// ListRuns mocks base method.
+func (m *MockEvalService) ListRuns(arg0 context.Context, arg1 models.ListEvalRunsRequest) (*models.ListEvalRunsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRuns", arg0, arg1)
+	ret0, _ := ret[0].(*models.ListEvalRunsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRuns indicates an expected call of ListRuns.
+func (mr *MockEvalServiceMockRecorder) ListRuns(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRuns", reflect.TypeOf((*MockEvalService)(nil).ListRuns), arg0, arg1)
+}
+
+// RunEval mocks base method.
+func (m *MockEvalService) RunEval(arg0 context.Context, arg1 models.RunEvalRequest) (*models.EvalRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunEval", arg0, arg1)
+	ret0, _ := ret[0].(*models.EvalRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunEval indicates an expected call of RunEval.
+func (mr *MockEvalServiceMockRecorder) RunEval(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunEval", reflect.TypeOf((*MockEvalService)(nil).RunEval), arg0, arg1)
+}
@@ -0,0 +1,111 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/services (interfaces: ExperimentService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockExperimentService is a mock of ExperimentService interface.
+type MockExperimentService struct {
+	ctrl     *gomock.Controller
+	recorder *MockExperimentServiceMockRecorder
+}
+
+// MockExperimentServiceMockRecorder is the mock recorder for MockExperimentService.
+type MockExperimentServiceMockRecorder struct {
+	mock *MockExperimentService
+}
+
+// NewMockExperimentService creates a new mock instance.
+func NewMockExperimentService(ctrl *gomock.Controller) *MockExperimentService {
+	mock := &MockExperimentService{ctrl: ctrl}
+	mock.recorder = &MockExperimentServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExperimentService) EXPECT() *MockExperimentServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateExperiment mocks base method.
+func (m *MockExperimentService) CreateExperiment(arg0 context.Context, arg1 models.CreateExperimentRequest) (*models.Experiment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateExperiment", arg0, arg1)
+	ret0, _ := ret[0].(*models.Experiment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateExperiment indicates an expected call of CreateExperiment.
+func (mr *MockExperimentServiceMockRecorder) CreateExperiment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExperiment", reflect.TypeOf((*MockExperimentService)(nil).CreateExperiment), arg0, arg1)
+}
+
+// GetExperiment mocks base method.
+func (m *MockExperimentService) GetExperiment(arg0 context.Context, arg1 models.GetExperimentRequest) (*models.Experiment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExperiment", arg0, arg1)
+	ret0, _ := ret[0].(*models.Experiment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExperiment indicates an expected call of GetExperiment.
+func (mr *MockExperimentServiceMockRecorder) GetExperiment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExperiment", reflect.TypeOf((*MockExperimentService)(nil).GetExperiment), arg0, arg1)
+}
+
+// GetExperimentReport mocks base method.
+func (m *MockExperimentService) GetExperimentReport(arg0 context.Context, arg1 models.GetExperimentReportRequest) (*models.ExperimentReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExperimentReport", arg0, arg1)
+	ret0, _ := ret[0].(*models.ExperimentReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExperimentReport indicates an expected call of GetExperimentReport.
+func (mr *MockExperimentServiceMockRecorder) GetExperimentReport(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExperimentReport", reflect.TypeOf((*MockExperimentService)(nil).GetExperimentReport), arg0, arg1)
+}
+
+// ListExperiments mocks base method.
+func (m *MockExperimentService) ListExperiments(arg0 context.Context) (*models.ListExperimentsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExperiments", arg0)
+	ret0, _ := ret[0].(*models.ListExperimentsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExperiments indicates an expected call of ListExperiments.
+func (mr *MockExperimentServiceMockRecorder) ListExperiments(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExperiments", reflect.TypeOf((*MockExperimentService)(nil).ListExperiments), arg0)
+}
+
+// StopExperiment mocks base method.
+func (m *MockExperimentService) StopExperiment(arg0 context.Context, arg1 models.StopExperimentRequest) (*models.Experiment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StopExperiment", arg0, arg1)
+	ret0, _ := ret[0].(*models.Experiment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StopExperiment indicates an expected call of StopExperiment.
+func (mr *MockExperimentServiceMockRecorder) StopExperiment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopExperiment", reflect.TypeOf((*MockExperimentService)(nil).StopExperiment), arg0, arg1)
+}
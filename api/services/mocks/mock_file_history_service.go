@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/services (interfaces: FileHistoryService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockFileHistoryService is a mock of FileHistoryService interface.
+type MockFileHistoryService struct {
+	ctrl     *gomock.Controller
+	recorder *MockFileHistoryServiceMockRecorder
+}
+
+// MockFileHistoryServiceMockRecorder is the mock recorder for MockFileHistoryService.
+type MockFileHistoryServiceMockRecorder struct {
+	mock *MockFileHistoryService
+}
+
+// NewMockFileHistoryService creates a new mock instance.
+func NewMockFileHistoryService(ctrl *gomock.Controller) *MockFileHistoryService {
+	mock := &MockFileHistoryService{ctrl: ctrl}
+	mock.recorder = &MockFileHistoryServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFileHistoryService) EXPECT() *MockFileHistoryServiceMockRecorder {
+	return m.recorder
+}
+
+// LookupHistory mocks base method.
+func (m *MockFileHistoryService) LookupHistory(arg0 context.Context, arg1 string, arg2 []string, arg3 int) ([]models.FileHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LookupHistory", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]models.FileHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LookupHistory indicates an expected call of LookupHistory.
+func (mr *MockFileHistoryServiceMockRecorder) LookupHistory(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LookupHistory", reflect.TypeOf((*MockFileHistoryService)(nil).LookupHistory), arg0, arg1, arg2, arg3)
+}
+
+// RecordHistory mocks base method.
+func (m *MockFileHistoryService) RecordHistory(arg0 context.Context, arg1 *models.TaskWithFullContext, arg2 string) ([]models.FileHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordHistory", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.FileHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordHistory indicates an expected call of RecordHistory.
+func (mr *MockFileHistoryServiceMockRecorder) RecordHistory(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordHistory", reflect.TypeOf((*MockFileHistoryService)(nil).RecordHistory), arg0, arg1, arg2)
+}
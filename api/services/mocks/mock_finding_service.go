@@ -0,0 +1,156 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/services (interfaces: FindingService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+	models0 "github.com/kazemisoroush/code-refactoring-tool/pkg/analyzer/models"
+)
+
+// MockFindingService is a mock of FindingService interface.
+type MockFindingService struct {
+	ctrl     *gomock.Controller
+	recorder *MockFindingServiceMockRecorder
+}
+
+// MockFindingServiceMockRecorder is the mock recorder for MockFindingService.
+type MockFindingServiceMockRecorder struct {
+	mock *MockFindingService
+}
+
+// NewMockFindingService creates a new mock instance.
+func NewMockFindingService(ctrl *gomock.Controller) *MockFindingService {
+	mock := &MockFindingService{ctrl: ctrl}
+	mock.recorder = &MockFindingServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFindingService) EXPECT() *MockFindingServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateFinding mocks base method.
+func (m *MockFindingService) CreateFinding(arg0 context.Context, arg1 models.CreateFindingRequest) (*models.Finding, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFinding", arg0, arg1)
+	ret0, _ := ret[0].(*models.Finding)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateFinding indicates an expected call of CreateFinding.
+func (mr *MockFindingServiceMockRecorder) CreateFinding(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFinding", reflect.TypeOf((*MockFindingService)(nil).CreateFinding), arg0, arg1)
+}
+
+// DeleteFinding mocks base method.
+func (m *MockFindingService) DeleteFinding(arg0 context.Context, arg1 models.DeleteFindingRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFinding", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFinding indicates an expected call of DeleteFinding.
+func (mr *MockFindingServiceMockRecorder) DeleteFinding(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFinding", reflect.TypeOf((*MockFindingService)(nil).DeleteFinding), arg0, arg1)
+}
+
+// GetBaseline mocks base method.
+func (m *MockFindingService) GetBaseline(arg0 context.Context, arg1 models.GetFindingBaselineRequest) (*models.FindingBaselineResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBaseline", arg0, arg1)
+	ret0, _ := ret[0].(*models.FindingBaselineResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBaseline indicates an expected call of GetBaseline.
+func (mr *MockFindingServiceMockRecorder) GetBaseline(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBaseline", reflect.TypeOf((*MockFindingService)(nil).GetBaseline), arg0, arg1)
+}
+
+// GetFinding mocks base method.
+func (m *MockFindingService) GetFinding(arg0 context.Context, arg1 models.GetFindingRequest) (*models.Finding, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFinding", arg0, arg1)
+	ret0, _ := ret[0].(*models.Finding)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFinding indicates an expected call of GetFinding.
+func (mr *MockFindingServiceMockRecorder) GetFinding(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFinding", reflect.TypeOf((*MockFindingService)(nil).GetFinding), arg0, arg1)
+}
+
+// ListFindings mocks base method.
+func (m *MockFindingService) ListFindings(arg0 context.Context, arg1 models.ListFindingsRequest) (*models.ListFindingsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFindings", arg0, arg1)
+	ret0, _ := ret[0].(*models.ListFindingsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFindings indicates an expected call of ListFindings.
+func (mr *MockFindingServiceMockRecorder) ListFindings(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFindings", reflect.TypeOf((*MockFindingService)(nil).ListFindings), arg0, arg1)
+}
+
+// Rebaseline mocks base method.
+func (m *MockFindingService) Rebaseline(arg0 context.Context, arg1 models.RebaselineFindingsRequest) (*models.FindingBaseline, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rebaseline", arg0, arg1)
+	ret0, _ := ret[0].(*models.FindingBaseline)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rebaseline indicates an expected call of Rebaseline.
+func (mr *MockFindingServiceMockRecorder) Rebaseline(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rebaseline", reflect.TypeOf((*MockFindingService)(nil).Rebaseline), arg0, arg1)
+}
+
+// SyncFindings mocks base method.
+func (m *MockFindingService) SyncFindings(arg0 context.Context, arg1 *models.TaskWithFullContext, arg2 string, arg3 []models0.CodeIssue) (*models.FindingSyncResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncFindings", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*models.FindingSyncResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SyncFindings indicates an expected call of SyncFindings.
+func (mr *MockFindingServiceMockRecorder) SyncFindings(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncFindings", reflect.TypeOf((*MockFindingService)(nil).SyncFindings), arg0, arg1, arg2, arg3)
+}
+
+// UpdateFinding mocks base method.
+func (m *MockFindingService) UpdateFinding(arg0 context.Context, arg1 models.UpdateFindingRequest) (*models.Finding, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFinding", arg0, arg1)
+	ret0, _ := ret[0].(*models.Finding)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateFinding indicates an expected call of UpdateFinding.
+func (mr *MockFindingServiceMockRecorder) UpdateFinding(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFinding", reflect.TypeOf((*MockFindingService)(nil).UpdateFinding), arg0, arg1)
+}
@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/services (interfaces: GenerationService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockGenerationService is a mock of GenerationService interface.
+type MockGenerationService struct {
+	ctrl     *gomock.Controller
+	recorder *MockGenerationServiceMockRecorder
+}
+
+// MockGenerationServiceMockRecorder is the mock recorder for MockGenerationService.
+type MockGenerationServiceMockRecorder struct {
+	mock *MockGenerationService
+}
+
+// NewMockGenerationService creates a new mock instance.
+func NewMockGenerationService(ctrl *gomock.Controller) *MockGenerationService {
+	mock := &MockGenerationService{ctrl: ctrl}
+	mock.recorder = &MockGenerationServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGenerationService) EXPECT() *MockGenerationServiceMockRecorder {
+	return m.recorder
+}
+
+// GeneratePRDescription mocks base method.
+func (m *MockGenerationService) GeneratePRDescription(arg0 context.Context, arg1 models.GeneratePRDescriptionRequest) (*models.PRDescription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GeneratePRDescription", arg0, arg1)
+	ret0, _ := ret[0].(*models.PRDescription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GeneratePRDescription indicates an expected call of GeneratePRDescription.
+func (mr *MockGenerationServiceMockRecorder) GeneratePRDescription(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GeneratePRDescription", reflect.TypeOf((*MockGenerationService)(nil).GeneratePRDescription), arg0, arg1)
+}
+
+// StreamPRDescription mocks base method.
+func (m *MockGenerationService) StreamPRDescription(arg0 context.Context, arg1 models.GeneratePRDescriptionRequest, arg2 func(models.StreamEvent) error) (*models.PRDescription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamPRDescription", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.PRDescription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StreamPRDescription indicates an expected call of StreamPRDescription.
+func (mr *MockGenerationServiceMockRecorder) StreamPRDescription(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamPRDescription", reflect.TypeOf((*MockGenerationService)(nil).StreamPRDescription), arg0, arg1, arg2)
+}
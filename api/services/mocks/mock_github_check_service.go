@@ -0,0 +1,138 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/services (interfaces: GitHubCheckService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockGitHubCheckService is a mock of GitHubCheckService interface.
+type MockGitHubCheckService struct {
+	ctrl     *gomock.Controller
+	recorder *MockGitHubCheckServiceMockRecorder
+}
+
+// MockGitHubCheckServiceMockRecorder is the mock recorder for MockGitHubCheckService.
+type MockGitHubCheckServiceMockRecorder struct {
+	mock *MockGitHubCheckService
+}
+
+// NewMockGitHubCheckService creates a new mock instance.
+func NewMockGitHubCheckService(ctrl *gomock.Controller) *MockGitHubCheckService {
+	mock := &MockGitHubCheckService{ctrl: ctrl}
+	mock.recorder = &MockGitHubCheckServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGitHubCheckService) EXPECT() *MockGitHubCheckServiceMockRecorder {
+	return m.recorder
+}
+
+// DeleteAppConfig mocks base method.
+func (m *MockGitHubCheckService) DeleteAppConfig(arg0 context.Context, arg1 models.DeleteGitHubAppConfigRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAppConfig", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAppConfig indicates an expected call of DeleteAppConfig.
+func (mr *MockGitHubCheckServiceMockRecorder) DeleteAppConfig(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAppConfig", reflect.TypeOf((*MockGitHubCheckService)(nil).DeleteAppConfig), arg0, arg1)
+}
+
+// GetAppConfig mocks base method.
+func (m *MockGitHubCheckService) GetAppConfig(arg0 context.Context, arg1 models.GetGitHubAppConfigRequest) (*models.GitHubAppConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAppConfig", arg0, arg1)
+	ret0, _ := ret[0].(*models.GitHubAppConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAppConfig indicates an expected call of GetAppConfig.
+func (mr *MockGitHubCheckServiceMockRecorder) GetAppConfig(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppConfig", reflect.TypeOf((*MockGitHubCheckService)(nil).GetAppConfig), arg0, arg1)
+}
+
+// HandleWebhook mocks base method.
+func (m *MockGitHubCheckService) HandleWebhook(arg0 context.Context, arg1, arg2 string, arg3 []byte) (*models.GitHubWebhookResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleWebhook", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*models.GitHubWebhookResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HandleWebhook indicates an expected call of HandleWebhook.
+func (mr *MockGitHubCheckServiceMockRecorder) HandleWebhook(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleWebhook", reflect.TypeOf((*MockGitHubCheckService)(nil).HandleWebhook), arg0, arg1, arg2, arg3)
+}
+
+// ListAppConfigs mocks base method.
+func (m *MockGitHubCheckService) ListAppConfigs(arg0 context.Context) (*models.ListGitHubAppConfigsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAppConfigs", arg0)
+	ret0, _ := ret[0].(*models.ListGitHubAppConfigsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAppConfigs indicates an expected call of ListAppConfigs.
+func (mr *MockGitHubCheckServiceMockRecorder) ListAppConfigs(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAppConfigs", reflect.TypeOf((*MockGitHubCheckService)(nil).ListAppConfigs), arg0)
+}
+
+// PublishChecks mocks base method.
+func (m *MockGitHubCheckService) PublishChecks(arg0 context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishChecks", arg0)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishChecks indicates an expected call of PublishChecks.
+func (mr *MockGitHubCheckServiceMockRecorder) PublishChecks(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishChecks", reflect.TypeOf((*MockGitHubCheckService)(nil).PublishChecks), arg0)
+}
+
+// PutAppConfig mocks base method.
+func (m *MockGitHubCheckService) PutAppConfig(arg0 context.Context, arg1 models.PutGitHubAppConfigRequest) (*models.GitHubAppConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutAppConfig", arg0, arg1)
+	ret0, _ := ret[0].(*models.GitHubAppConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutAppConfig indicates an expected call of PutAppConfig.
+func (mr *MockGitHubCheckServiceMockRecorder) PutAppConfig(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutAppConfig", reflect.TypeOf((*MockGitHubCheckService)(nil).PutAppConfig), arg0, arg1)
+}
+
+// RunCheckPublishing mocks base method.
+func (m *MockGitHubCheckService) RunCheckPublishing(arg0 context.Context, arg1 time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RunCheckPublishing", arg0, arg1)
+}
+
+// RunCheckPublishing indicates an expected call of RunCheckPublishing.
+func (mr *MockGitHubCheckServiceMockRecorder) RunCheckPublishing(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunCheckPublishing", reflect.TypeOf((*MockGitHubCheckService)(nil).RunCheckPublishing), arg0, arg1)
+}
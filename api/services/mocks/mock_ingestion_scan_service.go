@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/services (interfaces: IngestionScanService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockIngestionScanService is a mock of IngestionScanService interface.
+type MockIngestionScanService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIngestionScanServiceMockRecorder
+}
+
+// MockIngestionScanServiceMockRecorder is the mock recorder for MockIngestionScanService.
+type MockIngestionScanServiceMockRecorder struct {
+	mock *MockIngestionScanService
+}
+
+// NewMockIngestionScanService creates a new mock instance.
+func NewMockIngestionScanService(ctrl *gomock.Controller) *MockIngestionScanService {
+	mock := &MockIngestionScanService{ctrl: ctrl}
+	mock.recorder = &MockIngestionScanServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIngestionScanService) EXPECT() *MockIngestionScanServiceMockRecorder {
+	return m.recorder
+}
+
+// ListFindings mocks base method.
+func (m *MockIngestionScanService) ListFindings(arg0 context.Context, arg1 models.ListScanFindingsRequest) (*models.ListScanFindingsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFindings", arg0, arg1)
+	ret0, _ := ret[0].(*models.ListScanFindingsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFindings indicates an expected call of ListFindings.
+func (mr *MockIngestionScanServiceMockRecorder) ListFindings(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFindings", reflect.TypeOf((*MockIngestionScanService)(nil).ListFindings), arg0, arg1)
+}
+
+// ScanCodebase mocks base method.
+func (m *MockIngestionScanService) ScanCodebase(arg0 context.Context, arg1 models.ScanCodebaseRequest) (*models.ScanCodebaseResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScanCodebase", arg0, arg1)
+	ret0, _ := ret[0].(*models.ScanCodebaseResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScanCodebase indicates an expected call of ScanCodebase.
+func (mr *MockIngestionScanServiceMockRecorder) ScanCodebase(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScanCodebase", reflect.TypeOf((*MockIngestionScanService)(nil).ScanCodebase), arg0, arg1)
+}
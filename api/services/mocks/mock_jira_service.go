@@ -0,0 +1,166 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/services (interfaces: JiraService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockJiraService is a mock of JiraService interface.
+type MockJiraService struct {
+	ctrl     *gomock.Controller
+	recorder *MockJiraServiceMockRecorder
+}
+
+// MockJiraServiceMockRecorder is the mock recorder for MockJiraService.
+type MockJiraServiceMockRecorder struct {
+	mock *MockJiraService
+}
+
+// NewMockJiraService creates a new mock instance.
+func NewMockJiraService(ctrl *gomock.Controller) *MockJiraService {
+	mock := &MockJiraService{ctrl: ctrl}
+	mock.recorder = &MockJiraServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJiraService) EXPECT() *MockJiraServiceMockRecorder {
+	return m.recorder
+}
+
+// AttachIssues mocks base method.
+func (m *MockJiraService) AttachIssues(arg0 context.Context, arg1 []models.Task) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachIssues", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AttachIssues indicates an expected call of AttachIssues.
+func (mr *MockJiraServiceMockRecorder) AttachIssues(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachIssues", reflect.TypeOf((*MockJiraService)(nil).AttachIssues), arg0, arg1)
+}
+
+// DeleteSite mocks base method.
+func (m *MockJiraService) DeleteSite(arg0 context.Context, arg1 models.DeleteJiraSiteRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSite", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSite indicates an expected call of DeleteSite.
+func (mr *MockJiraServiceMockRecorder) DeleteSite(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSite", reflect.TypeOf((*MockJiraService)(nil).DeleteSite), arg0, arg1)
+}
+
+// GetSite mocks base method.
+func (m *MockJiraService) GetSite(arg0 context.Context, arg1 models.GetJiraSiteRequest) (*models.JiraSite, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSite", arg0, arg1)
+	ret0, _ := ret[0].(*models.JiraSite)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSite indicates an expected call of GetSite.
+func (mr *MockJiraServiceMockRecorder) GetSite(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSite", reflect.TypeOf((*MockJiraService)(nil).GetSite), arg0, arg1)
+}
+
+// LinkIssue mocks base method.
+func (m *MockJiraService) LinkIssue(arg0 context.Context, arg1 string, arg2 *models.JiraIssueLink) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LinkIssue", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LinkIssue indicates an expected call of LinkIssue.
+func (mr *MockJiraServiceMockRecorder) LinkIssue(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LinkIssue", reflect.TypeOf((*MockJiraService)(nil).LinkIssue), arg0, arg1, arg2)
+}
+
+// ListSites mocks base method.
+func (m *MockJiraService) ListSites(arg0 context.Context) (*models.ListJiraSitesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSites", arg0)
+	ret0, _ := ret[0].(*models.ListJiraSitesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSites indicates an expected call of ListSites.
+func (mr *MockJiraServiceMockRecorder) ListSites(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSites", reflect.TypeOf((*MockJiraService)(nil).ListSites), arg0)
+}
+
+// PutSite mocks base method.
+func (m *MockJiraService) PutSite(arg0 context.Context, arg1 models.PutJiraSiteRequest) (*models.JiraSite, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutSite", arg0, arg1)
+	ret0, _ := ret[0].(*models.JiraSite)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutSite indicates an expected call of PutSite.
+func (mr *MockJiraServiceMockRecorder) PutSite(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutSite", reflect.TypeOf((*MockJiraService)(nil).PutSite), arg0, arg1)
+}
+
+// ResolveIssue mocks base method.
+func (m *MockJiraService) ResolveIssue(arg0 context.Context, arg1 string) (*models.JiraIssueLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveIssue", arg0, arg1)
+	ret0, _ := ret[0].(*models.JiraIssueLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveIssue indicates an expected call of ResolveIssue.
+func (mr *MockJiraServiceMockRecorder) ResolveIssue(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveIssue", reflect.TypeOf((*MockJiraService)(nil).ResolveIssue), arg0, arg1)
+}
+
+// RunIssueSync mocks base method.
+func (m *MockJiraService) RunIssueSync(arg0 context.Context, arg1 time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RunIssueSync", arg0, arg1)
+}
+
+// RunIssueSync indicates an expected call of RunIssueSync.
+func (mr *MockJiraServiceMockRecorder) RunIssueSync(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunIssueSync", reflect.TypeOf((*MockJiraService)(nil).RunIssueSync), arg0, arg1)
+}
+
+// SyncIssues mocks base method.
+func (m *MockJiraService) SyncIssues(arg0 context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncIssues", arg0)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SyncIssues indicates an expected call of SyncIssues.
+func (mr *MockJiraServiceMockRecorder) SyncIssues(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncIssues", reflect.TypeOf((*MockJiraService)(nil).SyncIssues), arg0)
+}
@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/services (interfaces: ProjectManifestService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockProjectManifestService is a mock of ProjectManifestService interface.
+type MockProjectManifestService struct {
+	ctrl     *gomock.Controller
+	recorder *MockProjectManifestServiceMockRecorder
+}
+
+// MockProjectManifestServiceMockRecorder is the mock recorder for MockProjectManifestService.
+type MockProjectManifestServiceMockRecorder struct {
+	mock *MockProjectManifestService
+}
+
+// NewMockProjectManifestService creates a new mock instance.
+func NewMockProjectManifestService(ctrl *gomock.Controller) *MockProjectManifestService {
+	mock := &MockProjectManifestService{ctrl: ctrl}
+	mock.recorder = &MockProjectManifestServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProjectManifestService) EXPECT() *MockProjectManifestServiceMockRecorder {
+	return m.recorder
+}
+
+// ExportProject mocks base method.
+func (m *MockProjectManifestService) ExportProject(arg0 context.Context, arg1 string) (*models.ProjectManifest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportProject", arg0, arg1)
+	ret0, _ := ret[0].(*models.ProjectManifest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportProject indicates an expected call of ExportProject.
+func (mr *MockProjectManifestServiceMockRecorder) ExportProject(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportProject", reflect.TypeOf((*MockProjectManifestService)(nil).ExportProject), arg0, arg1)
+}
+
+// ImportProject mocks base method.
+func (m *MockProjectManifestService) ImportProject(arg0 context.Context, arg1 models.ProjectManifest) (*models.ImportProjectResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportProject", arg0, arg1)
+	ret0, _ := ret[0].(*models.ImportProjectResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportProject indicates an expected call of ImportProject.
+func (mr *MockProjectManifestServiceMockRecorder) ImportProject(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportProject", reflect.TypeOf((*MockProjectManifestService)(nil).ImportProject), arg0, arg1)
+}
@@ -35,6 +35,21 @@ func (m *MockProjectService) EXPECT() *MockProjectServiceMockRecorder {
 	return m.recorder
 }
 
+// ArchiveProject mocks base method.
+func (m *MockProjectService) ArchiveProject(arg0 context.Context, arg1 models.ArchiveProjectRequest) (*models.ProjectLifecycleResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArchiveProject", arg0, arg1)
+	ret0, _ := ret[0].(*models.ProjectLifecycleResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ArchiveProject indicates an expected call of ArchiveProject.
+func (mr *MockProjectServiceMockRecorder) ArchiveProject(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveProject", reflect.TypeOf((*MockProjectService)(nil).ArchiveProject), arg0, arg1)
+}
+
 // CreateProject mocks base method.
 func (m *MockProjectService) CreateProject(arg0 context.Context, arg1 models.CreateProjectRequest) (*models.CreateProjectResponse, error) {
 	m.ctrl.T.Helper()
@@ -95,6 +110,21 @@ func (mr *MockProjectServiceMockRecorder) ListProjects(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProjects", reflect.TypeOf((*MockProjectService)(nil).ListProjects), arg0, arg1)
 }
 
+// UnarchiveProject mocks base method.
+func (m *MockProjectService) UnarchiveProject(arg0 context.Context, arg1 models.UnarchiveProjectRequest) (*models.ProjectLifecycleResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnarchiveProject", arg0, arg1)
+	ret0, _ := ret[0].(*models.ProjectLifecycleResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnarchiveProject indicates an expected call of UnarchiveProject.
+func (mr *MockProjectServiceMockRecorder) UnarchiveProject(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnarchiveProject", reflect.TypeOf((*MockProjectService)(nil).UnarchiveProject), arg0, arg1)
+}
+
 // UpdateProject mocks base method.
 func (m *MockProjectService) UpdateProject(arg0 context.Context, arg1 models.UpdateProjectRequest) (*models.UpdateProjectResponse, error) {
 	m.ctrl.T.Helper()
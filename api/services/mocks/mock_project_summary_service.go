@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/services (interfaces: ProjectSummaryService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockProjectSummaryService is a mock of ProjectSummaryService interface.
+type MockProjectSummaryService struct {
+	ctrl     *gomock.Controller
+	recorder *MockProjectSummaryServiceMockRecorder
+}
+
+// MockProjectSummaryServiceMockRecorder is the mock recorder for MockProjectSummaryService.
+type MockProjectSummaryServiceMockRecorder struct {
+	mock *MockProjectSummaryService
+}
+
+// NewMockProjectSummaryService creates a new mock instance.
+func NewMockProjectSummaryService(ctrl *gomock.Controller) *MockProjectSummaryService {
+	mock := &MockProjectSummaryService{ctrl: ctrl}
+	mock.recorder = &MockProjectSummaryServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProjectSummaryService) EXPECT() *MockProjectSummaryServiceMockRecorder {
+	return m.recorder
+}
+
+// GetProjectSummary mocks base method.
+func (m *MockProjectSummaryService) GetProjectSummary(arg0 context.Context, arg1 models.GetProjectSummaryRequest) (*models.GetProjectSummaryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProjectSummary", arg0, arg1)
+	ret0, _ := ret[0].(*models.GetProjectSummaryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProjectSummary indicates an expected call of GetProjectSummary.
+func (mr *MockProjectSummaryServiceMockRecorder) GetProjectSummary(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjectSummary", reflect.TypeOf((*MockProjectSummaryService)(nil).GetProjectSummary), arg0, arg1)
+}
@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/services (interfaces: ProjectTemplateService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockProjectTemplateService is a mock of ProjectTemplateService interface.
+type MockProjectTemplateService struct {
+	ctrl     *gomock.Controller
+	recorder *MockProjectTemplateServiceMockRecorder
+}
+
+// MockProjectTemplateServiceMockRecorder is the mock recorder for MockProjectTemplateService.
+type MockProjectTemplateServiceMockRecorder struct {
+	mock *MockProjectTemplateService
+}
+
+// NewMockProjectTemplateService creates a new mock instance.
+func NewMockProjectTemplateService(ctrl *gomock.Controller) *MockProjectTemplateService {
+	mock := &MockProjectTemplateService{ctrl: ctrl}
+	mock.recorder = &MockProjectTemplateServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProjectTemplateService) EXPECT() *MockProjectTemplateServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateProjectFromTemplate mocks base method.
+func (m *MockProjectTemplateService) CreateProjectFromTemplate(arg0 context.Context, arg1 models.CreateProjectFromTemplateRequest) (*models.CreateProjectFromTemplateResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateProjectFromTemplate", arg0, arg1)
+	ret0, _ := ret[0].(*models.CreateProjectFromTemplateResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateProjectFromTemplate indicates an expected call of CreateProjectFromTemplate.
+func (mr *MockProjectTemplateServiceMockRecorder) CreateProjectFromTemplate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProjectFromTemplate", reflect.TypeOf((*MockProjectTemplateService)(nil).CreateProjectFromTemplate), arg0, arg1)
+}
+
+// CreateTemplate mocks base method.
+func (m *MockProjectTemplateService) CreateTemplate(arg0 context.Context, arg1 models.CreateProjectTemplateRequest) (*models.CreateProjectTemplateResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTemplate", arg0, arg1)
+	ret0, _ := ret[0].(*models.CreateProjectTemplateResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTemplate indicates an expected call of CreateTemplate.
+func (mr *MockProjectTemplateServiceMockRecorder) CreateTemplate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTemplate", reflect.TypeOf((*MockProjectTemplateService)(nil).CreateTemplate), arg0, arg1)
+}
+
+// GetTemplate mocks base method.
+func (m *MockProjectTemplateService) GetTemplate(arg0 context.Context, arg1 string) (*models.ProjectTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplate", arg0, arg1)
+	ret0, _ := ret[0].(*models.ProjectTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplate indicates an expected call of GetTemplate.
+func (mr *MockProjectTemplateServiceMockRecorder) GetTemplate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplate", reflect.TypeOf((*MockProjectTemplateService)(nil).GetTemplate), arg0, arg1)
+}
+
+// ListTemplates mocks base method.
+func (m *MockProjectTemplateService) ListTemplates(arg0 context.Context) (*models.ListProjectTemplatesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTemplates", arg0)
+	ret0, _ := ret[0].(*models.ListProjectTemplatesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTemplates indicates an expected call of ListTemplates.
+func (mr *MockProjectTemplateServiceMockRecorder) ListTemplates(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTemplates", reflect.TypeOf((*MockProjectTemplateService)(nil).ListTemplates), arg0)
+}
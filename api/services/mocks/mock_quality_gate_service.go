@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/services (interfaces: QualityGateService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockQualityGateService is a mock of QualityGateService interface.
+type MockQualityGateService struct {
+	ctrl     *gomock.Controller
+	recorder *MockQualityGateServiceMockRecorder
+}
+
+// MockQualityGateServiceMockRecorder is the mock recorder for MockQualityGateService.
+type MockQualityGateServiceMockRecorder struct {
+	mock *MockQualityGateService
+}
+
+// NewMockQualityGateService creates a new mock instance.
+func NewMockQualityGateService(ctrl *gomock.Controller) *MockQualityGateService {
+	mock := &MockQualityGateService{ctrl: ctrl}
+	mock.recorder = &MockQualityGateServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQualityGateService) EXPECT() *MockQualityGateServiceMockRecorder {
+	return m.recorder
+}
+
+// EvaluateGates mocks base method.
+func (m *MockQualityGateService) EvaluateGates(arg0 context.Context, arg1 *models.TaskWithFullContext, arg2 *models.FindingSyncResult, arg3 *models.CodeMetricsSnapshot) (*models.QualityGateReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EvaluateGates", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*models.QualityGateReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EvaluateGates indicates an expected call of EvaluateGates.
+func (mr *MockQualityGateServiceMockRecorder) EvaluateGates(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvaluateGates", reflect.TypeOf((*MockQualityGateService)(nil).EvaluateGates), arg0, arg1, arg2, arg3)
+}
+
+// GetPolicy mocks base method.
+func (m *MockQualityGateService) GetPolicy(arg0 context.Context, arg1 models.GetQualityGatePolicyRequest) (*models.QualityGatePolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPolicy", arg0, arg1)
+	ret0, _ := ret[0].(*models.QualityGatePolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPolicy indicates an expected call of GetPolicy.
+func (mr *MockQualityGateServiceMockRecorder) GetPolicy(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPolicy", reflect.TypeOf((*MockQualityGateService)(nil).GetPolicy), arg0, arg1)
+}
+
+// UpdatePolicy mocks base method.
+func (m *MockQualityGateService) UpdatePolicy(arg0 context.Context, arg1 models.UpdateQualityGatePolicyRequest) (*models.QualityGatePolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePolicy", arg0, arg1)
+	ret0, _ := ret[0].(*models.QualityGatePolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePolicy indicates an expected call of UpdatePolicy.
+func (mr *MockQualityGateServiceMockRecorder) UpdatePolicy(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePolicy", reflect.TypeOf((*MockQualityGateService)(nil).UpdatePolicy), arg0, arg1)
+}
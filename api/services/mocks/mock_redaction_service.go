@@ -0,0 +1,125 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/services (interfaces: RedactionService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+	redact "github.com/kazemisoroush/code-refactoring-tool/pkg/redact"
+)
+
+// MockRedactionService is a mock of RedactionService interface.
+type MockRedactionService struct {
+	ctrl     *gomock.Controller
+	recorder *MockRedactionServiceMockRecorder
+}
+
+// MockRedactionServiceMockRecorder is the mock recorder for MockRedactionService.
+type MockRedactionServiceMockRecorder struct {
+	mock *MockRedactionService
+}
+
+// NewMockRedactionService creates a new mock instance.
+func NewMockRedactionService(ctrl *gomock.Controller) *MockRedactionService {
+	mock := &MockRedactionService{ctrl: ctrl}
+	mock.recorder = &MockRedactionServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRedactionService) EXPECT() *MockRedactionServiceMockRecorder {
+	return m.recorder
+}
+
+// ExportAudits mocks base method.
+func (m *MockRedactionService) ExportAudits(arg0 context.Context, arg1 models.ListRedactionAuditsRequest, arg2 func(models.RedactionAudit) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportAudits", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportAudits indicates an expected call of ExportAudits.
+func (mr *MockRedactionServiceMockRecorder) ExportAudits(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportAudits", reflect.TypeOf((*MockRedactionService)(nil).ExportAudits), arg0, arg1, arg2)
+}
+
+// GetPolicy mocks base method.
+func (m *MockRedactionService) GetPolicy(arg0 context.Context, arg1 models.GetRedactionPolicyRequest) (*models.RedactionPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPolicy", arg0, arg1)
+	ret0, _ := ret[0].(*models.RedactionPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPolicy indicates an expected call of GetPolicy.
+func (mr *MockRedactionServiceMockRecorder) GetPolicy(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPolicy", reflect.TypeOf((*MockRedactionService)(nil).GetPolicy), arg0, arg1)
+}
+
+// ListAudits mocks base method.
+func (m *MockRedactionService) ListAudits(arg0 context.Context, arg1 models.ListRedactionAuditsRequest) (*models.ListRedactionAuditsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAudits", arg0, arg1)
+	ret0, _ := ret[0].(*models.ListRedactionAuditsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAudits indicates an expected call of ListAudits.
+func (mr *MockRedactionServiceMockRecorder) ListAudits(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAudits", reflect.TypeOf((*MockRedactionService)(nil).ListAudits), arg0, arg1)
+}
+
+// RecordAudit mocks base method.
+func (m *MockRedactionService) RecordAudit(arg0 context.Context, arg1, arg2 string, arg3 models.RedactionOperation, arg4 redact.Report) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordAudit", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordAudit indicates an expected call of RecordAudit.
+func (mr *MockRedactionServiceMockRecorder) RecordAudit(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordAudit", reflect.TypeOf((*MockRedactionService)(nil).RecordAudit), arg0, arg1, arg2, arg3, arg4)
+}
+
+// ResolvePolicy mocks base method.
+func (m *MockRedactionService) ResolvePolicy(arg0 context.Context, arg1 string) (redact.Policy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolvePolicy", arg0, arg1)
+	ret0, _ := ret[0].(redact.Policy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolvePolicy indicates an expected call of ResolvePolicy.
+func (mr *MockRedactionServiceMockRecorder) ResolvePolicy(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolvePolicy", reflect.TypeOf((*MockRedactionService)(nil).ResolvePolicy), arg0, arg1)
+}
+
+// UpdatePolicy mocks base method.
+func (m *MockRedactionService) UpdatePolicy(arg0 context.Context, arg1 models.UpdateRedactionPolicyRequest) (*models.RedactionPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePolicy", arg0, arg1)
+	ret0, _ := ret[0].(*models.RedactionPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePolicy indicates an expected call of UpdatePolicy.
+func (mr *MockRedactionServiceMockRecorder) UpdatePolicy(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePolicy", reflect.TypeOf((*MockRedactionService)(nil).UpdatePolicy), arg0, arg1)
+}
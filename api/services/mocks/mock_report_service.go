@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/services (interfaces: ReportService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockReportService is a mock of ReportService interface.
+type MockReportService struct {
+	ctrl     *gomock.Controller
+	recorder *MockReportServiceMockRecorder
+}
+
+// MockReportServiceMockRecorder is the mock recorder for MockReportService.
+type MockReportServiceMockRecorder struct {
+	mock *MockReportService
+}
+
+// NewMockReportService creates a new mock instance.
+func NewMockReportService(ctrl *gomock.Controller) *MockReportService {
+	mock := &MockReportService{ctrl: ctrl}
+	mock.recorder = &MockReportServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReportService) EXPECT() *MockReportServiceMockRecorder {
+	return m.recorder
+}
+
+// GetTaskReport mocks base method.
+func (m *MockReportService) GetTaskReport(arg0 context.Context, arg1 models.GetTaskReportRequest) (*models.GetTaskReportResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskReport", arg0, arg1)
+	ret0, _ := ret[0].(*models.GetTaskReportResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskReport indicates an expected call of GetTaskReport.
+func (mr *MockReportServiceMockRecorder) GetTaskReport(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskReport", reflect.TypeOf((*MockReportService)(nil).GetTaskReport), arg0, arg1)
+}
+
+// RefreshTaskMetrics mocks base method.
+func (m *MockReportService) RefreshTaskMetrics(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshTaskMetrics", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefreshTaskMetrics indicates an expected call of RefreshTaskMetrics.
+func (mr *MockReportServiceMockRecorder) RefreshTaskMetrics(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshTaskMetrics", reflect.TypeOf((*MockReportService)(nil).RefreshTaskMetrics), arg0)
+}
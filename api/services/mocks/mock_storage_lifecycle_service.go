@@ -0,0 +1,137 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/services (interfaces: StorageLifecycleService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+	repository "github.com/kazemisoroush/code-refactoring-tool/api/repository"
+)
+
+// MockStorageLifecycleService is a mock of StorageLifecycleService interface.
+type MockStorageLifecycleService struct {
+	ctrl     *gomock.Controller
+	recorder *MockStorageLifecycleServiceMockRecorder
+}
+
+// MockStorageLifecycleServiceMockRecorder is the mock recorder for MockStorageLifecycleService.
+type MockStorageLifecycleServiceMockRecorder struct {
+	mock *MockStorageLifecycleService
+}
+
+// NewMockStorageLifecycleService creates a new mock instance.
+func NewMockStorageLifecycleService(ctrl *gomock.Controller) *MockStorageLifecycleService {
+	mock := &MockStorageLifecycleService{ctrl: ctrl}
+	mock.recorder = &MockStorageLifecycleServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStorageLifecycleService) EXPECT() *MockStorageLifecycleServiceMockRecorder {
+	return m.recorder
+}
+
+// GetPurge mocks base method.
+func (m *MockStorageLifecycleService) GetPurge(arg0 context.Context, arg1 models.GetStoragePurgeRequest) (*models.StoragePurge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPurge", arg0, arg1)
+	ret0, _ := ret[0].(*models.StoragePurge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPurge indicates an expected call of GetPurge.
+func (mr *MockStorageLifecycleServiceMockRecorder) GetPurge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPurge", reflect.TypeOf((*MockStorageLifecycleService)(nil).GetPurge), arg0, arg1)
+}
+
+// ListOrphans mocks base method.
+func (m *MockStorageLifecycleService) ListOrphans(arg0 context.Context) (*models.ListStorageOrphansResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrphans", arg0)
+	ret0, _ := ret[0].(*models.ListStorageOrphansResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrphans indicates an expected call of ListOrphans.
+func (mr *MockStorageLifecycleServiceMockRecorder) ListOrphans(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrphans", reflect.TypeOf((*MockStorageLifecycleService)(nil).ListOrphans), arg0)
+}
+
+// ListPurges mocks base method.
+func (m *MockStorageLifecycleService) ListPurges(arg0 context.Context, arg1 models.ListStoragePurgesRequest) (*models.ListStoragePurgesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPurges", arg0, arg1)
+	ret0, _ := ret[0].(*models.ListStoragePurgesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPurges indicates an expected call of ListPurges.
+func (mr *MockStorageLifecycleServiceMockRecorder) ListPurges(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPurges", reflect.TypeOf((*MockStorageLifecycleService)(nil).ListPurges), arg0, arg1)
+}
+
+// PurgeAgentContent mocks base method.
+func (m *MockStorageLifecycleService) PurgeAgentContent(arg0 context.Context, arg1 *repository.AgentRecord) (*models.StoragePurge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeAgentContent", arg0, arg1)
+	ret0, _ := ret[0].(*models.StoragePurge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeAgentContent indicates an expected call of PurgeAgentContent.
+func (mr *MockStorageLifecycleServiceMockRecorder) PurgeAgentContent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeAgentContent", reflect.TypeOf((*MockStorageLifecycleService)(nil).PurgeAgentContent), arg0, arg1)
+}
+
+// RunPurgeQueue mocks base method.
+func (m *MockStorageLifecycleService) RunPurgeQueue(arg0 context.Context, arg1 time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RunPurgeQueue", arg0, arg1)
+}
+
+// RunPurgeQueue indicates an expected call of RunPurgeQueue.
+func (mr *MockStorageLifecycleServiceMockRecorder) RunPurgeQueue(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunPurgeQueue", reflect.TypeOf((*MockStorageLifecycleService)(nil).RunPurgeQueue), arg0, arg1)
+}
+
+// RunPurges mocks base method.
+func (m *MockStorageLifecycleService) RunPurges(arg0 context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunPurges", arg0)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunPurges indicates an expected call of RunPurges.
+func (mr *MockStorageLifecycleServiceMockRecorder) RunPurges(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunPurges", reflect.TypeOf((*MockStorageLifecycleService)(nil).RunPurges), arg0)
+}
+
+// RunReconciliation mocks base method.
+func (m *MockStorageLifecycleService) RunReconciliation(arg0 context.Context, arg1 time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RunReconciliation", arg0, arg1)
+}
+
+// RunReconciliation indicates an expected call of RunReconciliation.
+func (mr *MockStorageLifecycleServiceMockRecorder) RunReconciliation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunReconciliation", reflect.TypeOf((*MockStorageLifecycleService)(nil).RunReconciliation), arg0, arg1)
+}
@@ -0,0 +1,124 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/services (interfaces: TagService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockTagService is a mock of TagService interface.
+type MockTagService struct {
+	ctrl     *gomock.Controller
+	recorder *MockTagServiceMockRecorder
+}
+
+// MockTagServiceMockRecorder is the mock recorder for MockTagService.
+type MockTagServiceMockRecorder struct {
+	mock *MockTagService
+}
+
+// NewMockTagService creates a new mock instance.
+func NewMockTagService(ctrl *gomock.Controller) *MockTagService {
+	mock := &MockTagService{ctrl: ctrl}
+	mock.recorder = &MockTagServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTagService) EXPECT() *MockTagServiceMockRecorder {
+	return m.recorder
+}
+
+// AuthorizeTags mocks base method.
+func (m *MockTagService) AuthorizeTags(arg0 context.Context, arg1 string, arg2, arg3 map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthorizeTags", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AuthorizeTags indicates an expected call of AuthorizeTags.
+func (mr *MockTagServiceMockRecorder) AuthorizeTags(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthorizeTags", reflect.TypeOf((*MockTagService)(nil).AuthorizeTags), arg0, arg1, arg2, arg3)
+}
+
+// CreateTagKey mocks base method.
+func (m *MockTagService) CreateTagKey(arg0 context.Context, arg1 models.CreateTagKeyRequest) (*models.TagKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTagKey", arg0, arg1)
+	ret0, _ := ret[0].(*models.TagKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTagKey indicates an expected call of CreateTagKey.
+func (mr *MockTagServiceMockRecorder) CreateTagKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTagKey", reflect.TypeOf((*MockTagService)(nil).CreateTagKey), arg0, arg1)
+}
+
+// DeleteTagKey mocks base method.
+func (m *MockTagService) DeleteTagKey(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTagKey", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTagKey indicates an expected call of DeleteTagKey.
+func (mr *MockTagServiceMockRecorder) DeleteTagKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTagKey", reflect.TypeOf((*MockTagService)(nil).DeleteTagKey), arg0, arg1)
+}
+
+// GetTagKey mocks base method.
+func (m *MockTagService) GetTagKey(arg0 context.Context, arg1 string) (*models.TagKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTagKey", arg0, arg1)
+	ret0, _ := ret[0].(*models.TagKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTagKey indicates an expected call of GetTagKey.
+func (mr *MockTagServiceMockRecorder) GetTagKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagKey", reflect.TypeOf((*MockTagService)(nil).GetTagKey), arg0, arg1)
+}
+
+// ListTagKeys mocks base method.
+func (m *MockTagService) ListTagKeys(arg0 context.Context) (*models.ListTagKeysResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTagKeys", arg0)
+	ret0, _ := ret[0].(*models.ListTagKeysResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTagKeys indicates an expected call of ListTagKeys.
+func (mr *MockTagServiceMockRecorder) ListTagKeys(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTagKeys", reflect.TypeOf((*MockTagService)(nil).ListTagKeys), arg0)
+}
+
+// UpdateTagKey mocks base method.
+func (m *MockTagService) UpdateTagKey(arg0 context.Context, arg1 models.UpdateTagKeyRequest) (*models.TagKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTagKey", arg0, arg1)
+	ret0, _ := ret[0].(*models.TagKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTagKey indicates an expected call of UpdateTagKey.
+func (mr *MockTagServiceMockRecorder) UpdateTagKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTagKey", reflect.TypeOf((*MockTagService)(nil).UpdateTagKey), arg0, arg1)
+}
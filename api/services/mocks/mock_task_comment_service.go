@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/services (interfaces: TaskCommentService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockTaskCommentService is a mock of TaskCommentService interface.
+type MockTaskCommentService struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaskCommentServiceMockRecorder
+}
+
+// MockTaskCommentServiceMockRecorder is the mock recorder for MockTaskCommentService.
+type MockTaskCommentServiceMockRecorder struct {
+	mock *MockTaskCommentService
+}
+
+// NewMockTaskCommentService creates a new mock instance.
+func NewMockTaskCommentService(ctrl *gomock.Controller) *MockTaskCommentService {
+	mock := &MockTaskCommentService{ctrl: ctrl}
+	mock.recorder = &MockTaskCommentServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTaskCommentService) EXPECT() *MockTaskCommentServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateComment mocks base method.
+func (m *MockTaskCommentService) CreateComment(arg0 context.Context, arg1 models.CreateTaskCommentRequest) (*models.TaskComment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateComment", arg0, arg1)
+	ret0, _ := ret[0].(*models.TaskComment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateComment indicates an expected call of CreateComment.
+func (mr *MockTaskCommentServiceMockRecorder) CreateComment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateComment", reflect.TypeOf((*MockTaskCommentService)(nil).CreateComment), arg0, arg1)
+}
+
+// DeleteComment mocks base method.
+func (m *MockTaskCommentService) DeleteComment(arg0 context.Context, arg1 models.DeleteTaskCommentRequest) (*models.DeleteTaskCommentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteComment", arg0, arg1)
+	ret0, _ := ret[0].(*models.DeleteTaskCommentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteComment indicates an expected call of DeleteComment.
+func (mr *MockTaskCommentServiceMockRecorder) DeleteComment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteComment", reflect.TypeOf((*MockTaskCommentService)(nil).DeleteComment), arg0, arg1)
+}
+
+// ListComments mocks base method.
+func (m *MockTaskCommentService) ListComments(arg0 context.Context, arg1 models.ListTaskCommentsRequest) (*models.ListTaskCommentsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListComments", arg0, arg1)
+	ret0, _ := ret[0].(*models.ListTaskCommentsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListComments indicates an expected call of ListComments.
+func (mr *MockTaskCommentServiceMockRecorder) ListComments(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListComments", reflect.TypeOf((*MockTaskCommentService)(nil).ListComments), arg0, arg1)
+}
+
+// UpdateComment mocks base method.
+func (m *MockTaskCommentService) UpdateComment(arg0 context.Context, arg1 models.UpdateTaskCommentRequest) (*models.TaskComment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateComment", arg0, arg1)
+	ret0, _ := ret[0].(*models.TaskComment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateComment indicates an expected call of UpdateComment.
+func (mr *MockTaskCommentServiceMockRecorder) UpdateComment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateComment", reflect.TypeOf((*MockTaskCommentService)(nil).UpdateComment), arg0, arg1)
+}
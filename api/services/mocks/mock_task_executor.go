@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/services (interfaces: TaskExecutor)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockTaskExecutor is a mock of TaskExecutor interface.
+type MockTaskExecutor struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaskExecutorMockRecorder
+}
+
+// MockTaskExecutorMockRecorder is the mock recorder for MockTaskExecutor.
+type MockTaskExecutorMockRecorder struct {
+	mock *MockTaskExecutor
+}
+
+// NewMockTaskExecutor creates a new mock instance.
+func NewMockTaskExecutor(ctrl *gomock.Controller) *MockTaskExecutor {
+	mock := &MockTaskExecutor{ctrl: ctrl}
+	mock.recorder = &MockTaskExecutorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTaskExecutor) EXPECT() *MockTaskExecutorMockRecorder {
+	return m.recorder
+}
+
+// Execute mocks base method.
+func (m *MockTaskExecutor) Execute(arg0 context.Context, arg1 *models.TaskWithFullContext) (map[string]interface{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Execute", arg0, arg1)
+	ret0, _ := ret[0].(map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockTaskExecutorMockRecorder) Execute(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockTaskExecutor)(nil).Execute), arg0, arg1)
+}
+
+// Name mocks base method.
+func (m *MockTaskExecutor) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockTaskExecutorMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockTaskExecutor)(nil).Name))
+}
+
+// Supports mocks base method.
+func (m *MockTaskExecutor) Supports(arg0 models.TaskType) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Supports", arg0)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Supports indicates an expected call of Supports.
+func (mr *MockTaskExecutorMockRecorder) Supports(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Supports", reflect.TypeOf((*MockTaskExecutor)(nil).Supports), arg0)
+}
@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/services (interfaces: TaskFeedbackService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockTaskFeedbackService is a mock of TaskFeedbackService interface.
+type MockTaskFeedbackService struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaskFeedbackServiceMockRecorder
+}
+
+// MockTaskFeedbackServiceMockRecorder is the mock recorder for MockTaskFeedbackService.
+type MockTaskFeedbackServiceMockRecorder struct {
+	mock *MockTaskFeedbackService
+}
+
+// NewMockTaskFeedbackService creates a new mock instance.
+func NewMockTaskFeedbackService(ctrl *gomock.Controller) *MockTaskFeedbackService {
+	mock := &MockTaskFeedbackService{ctrl: ctrl}
+	mock.recorder = &MockTaskFeedbackServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTaskFeedbackService) EXPECT() *MockTaskFeedbackServiceMockRecorder {
+	return m.recorder
+}
+
+// ListFeedback mocks base method.
+func (m *MockTaskFeedbackService) ListFeedback(arg0 context.Context, arg1 models.ListTaskFeedbackRequest) (*models.ListTaskFeedbackResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFeedback", arg0, arg1)
+	ret0, _ := ret[0].(*models.ListTaskFeedbackResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFeedback indicates an expected call of ListFeedback.
+func (mr *MockTaskFeedbackServiceMockRecorder) ListFeedback(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFeedback", reflect.TypeOf((*MockTaskFeedbackService)(nil).ListFeedback), arg0, arg1)
+}
+
+// SubmitFeedback mocks base method.
+func (m *MockTaskFeedbackService) SubmitFeedback(arg0 context.Context, arg1 models.SubmitTaskFeedbackRequest) (*models.TaskFeedback, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubmitFeedback", arg0, arg1)
+	ret0, _ := ret[0].(*models.TaskFeedback)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubmitFeedback indicates an expected call of SubmitFeedback.
+func (mr *MockTaskFeedbackServiceMockRecorder) SubmitFeedback(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitFeedback", reflect.TypeOf((*MockTaskFeedbackService)(nil).SubmitFeedback), arg0, arg1)
+}
@@ -7,6 +7,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
@@ -35,6 +36,20 @@ func (m *MockTaskService) EXPECT() *MockTaskServiceMockRecorder {
 	return m.recorder
 }
 
+// AuthorizeTask mocks base method.
+func (m *MockTaskService) AuthorizeTask(arg0 context.Context, arg1, arg2 string, arg3 models.Permission) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthorizeTask", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AuthorizeTask indicates an expected call of AuthorizeTask.
+func (mr *MockTaskServiceMockRecorder) AuthorizeTask(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthorizeTask", reflect.TypeOf((*MockTaskService)(nil).AuthorizeTask), arg0, arg1, arg2, arg3)
+}
+
 // CreateTask mocks base method.
 func (m *MockTaskService) CreateTask(arg0 context.Context, arg1 *models.CreateTaskRequest) (*models.CreateTaskResponse, error) {
 	m.ctrl.T.Helper()
@@ -50,6 +65,21 @@ func (mr *MockTaskServiceMockRecorder) CreateTask(arg0, arg1 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTask", reflect.TypeOf((*MockTaskService)(nil).CreateTask), arg0, arg1)
 }
 
+// CreateTaskBatch mocks base method.
+func (m *MockTaskService) CreateTaskBatch(arg0 context.Context, arg1 *models.CreateTaskBatchRequest) (*models.CreateTaskBatchResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTaskBatch", arg0, arg1)
+	ret0, _ := ret[0].(*models.CreateTaskBatchResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTaskBatch indicates an expected call of CreateTaskBatch.
+func (mr *MockTaskServiceMockRecorder) CreateTaskBatch(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTaskBatch", reflect.TypeOf((*MockTaskService)(nil).CreateTaskBatch), arg0, arg1)
+}
+
 // DeleteTask mocks base method.
 func (m *MockTaskService) DeleteTask(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -79,6 +109,20 @@ func (mr *MockTaskServiceMockRecorder) ExecuteTask(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteTask", reflect.TypeOf((*MockTaskService)(nil).ExecuteTask), arg0, arg1)
 }
 
+// ExportTasks mocks base method.
+func (m *MockTaskService) ExportTasks(arg0 context.Context, arg1 *models.ListTasksRequest, arg2 func(models.Task) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportTasks", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportTasks indicates an expected call of ExportTasks.
+func (mr *MockTaskServiceMockRecorder) ExportTasks(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportTasks", reflect.TypeOf((*MockTaskService)(nil).ExportTasks), arg0, arg1, arg2)
+}
+
 // GetTask mocks base method.
 func (m *MockTaskService) GetTask(arg0 context.Context, arg1 string) (*models.GetTaskResponse, error) {
 	m.ctrl.T.Helper()
@@ -94,6 +138,36 @@ func (mr *MockTaskServiceMockRecorder) GetTask(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTask", reflect.TypeOf((*MockTaskService)(nil).GetTask), arg0, arg1)
 }
 
+// GetTaskBatch mocks base method.
+func (m *MockTaskService) GetTaskBatch(arg0 context.Context, arg1, arg2 string) (*models.GetTaskBatchResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskBatch", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.GetTaskBatchResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskBatch indicates an expected call of GetTaskBatch.
+func (mr *MockTaskServiceMockRecorder) GetTaskBatch(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskBatch", reflect.TypeOf((*MockTaskService)(nil).GetTaskBatch), arg0, arg1, arg2)
+}
+
+// ListStuckTasks mocks base method.
+func (m *MockTaskService) ListStuckTasks(arg0 context.Context) (*models.ListStuckTasksResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStuckTasks", arg0)
+	ret0, _ := ret[0].(*models.ListStuckTasksResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStuckTasks indicates an expected call of ListStuckTasks.
+func (mr *MockTaskServiceMockRecorder) ListStuckTasks(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStuckTasks", reflect.TypeOf((*MockTaskService)(nil).ListStuckTasks), arg0)
+}
+
 // ListTasks mocks base method.
 func (m *MockTaskService) ListTasks(arg0 context.Context, arg1 *models.ListTasksRequest) (*models.ListTasksResponse, error) {
 	m.ctrl.T.Helper()
@@ -109,6 +183,88 @@ func (mr *MockTaskServiceMockRecorder) ListTasks(arg0, arg1 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTasks", reflect.TypeOf((*MockTaskService)(nil).ListTasks), arg0, arg1)
 }
 
+// QueueTask mocks base method.
+func (m *MockTaskService) QueueTask(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueTask", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// QueueTask indicates an expected call of QueueTask.
+func (mr *MockTaskServiceMockRecorder) QueueTask(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueTask", reflect.TypeOf((*MockTaskService)(nil).QueueTask), arg0, arg1)
+}
+
+// RequeueTask mocks base method.
+func (m *MockTaskService) RequeueTask(arg0 context.Context, arg1 string) (*models.RequeueTaskResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequeueTask", arg0, arg1)
+	ret0, _ := ret[0].(*models.RequeueTaskResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequeueTask indicates an expected call of RequeueTask.
+func (mr *MockTaskServiceMockRecorder) RequeueTask(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequeueTask", reflect.TypeOf((*MockTaskService)(nil).RequeueTask), arg0, arg1)
+}
+
+// ResumeInterruptedTasks mocks base method.
+func (m *MockTaskService) ResumeInterruptedTasks(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResumeInterruptedTasks", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResumeInterruptedTasks indicates an expected call of ResumeInterruptedTasks.
+func (mr *MockTaskServiceMockRecorder) ResumeInterruptedTasks(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeInterruptedTasks", reflect.TypeOf((*MockTaskService)(nil).ResumeInterruptedTasks), arg0)
+}
+
+// RunStuckTaskJanitor mocks base method.
+func (m *MockTaskService) RunStuckTaskJanitor(arg0 context.Context, arg1 time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RunStuckTaskJanitor", arg0, arg1)
+}
+
+// RunStuckTaskJanitor indicates an expected call of RunStuckTaskJanitor.
+func (mr *MockTaskServiceMockRecorder) RunStuckTaskJanitor(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunStuckTaskJanitor", reflect.TypeOf((*MockTaskService)(nil).RunStuckTaskJanitor), arg0, arg1)
+}
+
+// RunTask mocks base method.
+func (m *MockTaskService) RunTask(arg0 context.Context, arg1 string) (*models.ExecuteTaskResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunTask", arg0, arg1)
+	ret0, _ := ret[0].(*models.ExecuteTaskResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunTask indicates an expected call of RunTask.
+func (mr *MockTaskServiceMockRecorder) RunTask(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunTask", reflect.TypeOf((*MockTaskService)(nil).RunTask), arg0, arg1)
+}
+
+// RunTaskDispatcher mocks base method.
+func (m *MockTaskService) RunTaskDispatcher(arg0 context.Context, arg1 time.Duration, arg2 int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RunTaskDispatcher", arg0, arg1, arg2)
+}
+
+// RunTaskDispatcher indicates an expected call of RunTaskDispatcher.
+func (mr *MockTaskServiceMockRecorder) RunTaskDispatcher(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunTaskDispatcher", reflect.TypeOf((*MockTaskService)(nil).RunTaskDispatcher), arg0, arg1, arg2)
+}
+
 // UpdateTask mocks base method.
 func (m *MockTaskService) UpdateTask(arg0 context.Context, arg1 *models.UpdateTaskRequest) (*models.UpdateTaskResponse, error) {
 	m.ctrl.T.Helper()
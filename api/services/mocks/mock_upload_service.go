@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/services (interfaces: UploadService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	io "io"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockUploadService is a mock of UploadService interface.
+type MockUploadService struct {
+	ctrl     *gomock.Controller
+	recorder *MockUploadServiceMockRecorder
+}
+
+// MockUploadServiceMockRecorder is the mock recorder for MockUploadService.
+type MockUploadServiceMockRecorder struct {
+	mock *MockUploadService
+}
+
+// NewMockUploadService creates a new mock instance.
+func NewMockUploadService(ctrl *gomock.Controller) *MockUploadService {
+	mock := &MockUploadService{ctrl: ctrl}
+	mock.recorder = &MockUploadServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUploadService) EXPECT() *MockUploadServiceMockRecorder {
+	return m.recorder
+}
+
+// GetTaskInput mocks base method.
+func (m *MockUploadService) GetTaskInput(arg0 context.Context, arg1, arg2 string) (*models.TaskInputUpload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskInput", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.TaskInputUpload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskInput indicates an expected call of GetTaskInput.
+func (mr *MockUploadServiceMockRecorder) GetTaskInput(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskInput", reflect.TypeOf((*MockUploadService)(nil).GetTaskInput), arg0, arg1, arg2)
+}
+
+// UploadTaskInput mocks base method.
+func (m *MockUploadService) UploadTaskInput(arg0 context.Context, arg1, arg2, arg3, arg4 string, arg5 io.Reader) (*models.TaskInputUpload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadTaskInput", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(*models.TaskInputUpload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadTaskInput indicates an expected call of UploadTaskInput.
+func (mr *MockUploadServiceMockRecorder) UploadTaskInput(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadTaskInput", reflect.TypeOf((*MockUploadService)(nil).UploadTaskInput), arg0, arg1, arg2, arg3, arg4, arg5)
+}
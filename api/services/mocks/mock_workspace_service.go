@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/api/services (interfaces: WorkspaceService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// MockWorkspaceService is a mock of WorkspaceService interface.
+type MockWorkspaceService struct {
+	ctrl     *gomock.Controller
+	recorder *MockWorkspaceServiceMockRecorder
+}
+
+// MockWorkspaceServiceMockRecorder is the mock recorder for MockWorkspaceService.
+type MockWorkspaceServiceMockRecorder struct {
+	mock *MockWorkspaceService
+}
+
+// NewMockWorkspaceService creates a new mock instance.
+func NewMockWorkspaceService(ctrl *gomock.Controller) *MockWorkspaceService {
+	mock := &MockWorkspaceService{ctrl: ctrl}
+	mock.recorder = &MockWorkspaceServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWorkspaceService) EXPECT() *MockWorkspaceServiceMockRecorder {
+	return m.recorder
+}
+
+// CollectGarbage mocks base method.
+func (m *MockWorkspaceService) CollectGarbage(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CollectGarbage", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CollectGarbage indicates an expected call of CollectGarbage.
+func (mr *MockWorkspaceServiceMockRecorder) CollectGarbage(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CollectGarbage", reflect.TypeOf((*MockWorkspaceService)(nil).CollectGarbage), arg0)
+}
+
+// GetUsage mocks base method.
+func (m *MockWorkspaceService) GetUsage(arg0 context.Context) (*models.GetWorkspaceUsageResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsage", arg0)
+	ret0, _ := ret[0].(*models.GetWorkspaceUsageResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsage indicates an expected call of GetUsage.
+func (mr *MockWorkspaceServiceMockRecorder) GetUsage(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsage", reflect.TypeOf((*MockWorkspaceService)(nil).GetUsage), arg0)
+}
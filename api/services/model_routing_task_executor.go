@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/toolloop"
+)
+
+// ModelRouting configures how the tasks run on the auto model are routed between a cheap and a strong model
+type ModelRouting struct {
+	CheapModel           string  // Model of the simple tasks
+	StrongModel          string  // Model of the other tasks, and of the simple ones the cheap model failed
+	MaxDiffLines         int     // Most lines a diff of a simple task changes
+	MaxComplexity        int     // Complexity of a function the diff changes from which the task isn't simple
+	MaxAverageComplexity float64 // Mean complexity of the codebase from which a task without a diff isn't simple
+}
+
+// ModelRoutingTaskExecutor picks the model of the tasks asking for the auto model, and passes other tasks on to the
+// next executor. Documentation tasks and tasks about a small diff run on the cheap model, unless the last metrics of
+// their codebase show the code they're about is complex. Other tasks run on the strong model, as do the simple ones
+// whose answer from the cheap model failed validation. The decisions are recorded in the task output. It stands in
+// for the next executor in the registry, under its name.
+type ModelRoutingTaskExecutor struct {
+	next        TaskExecutor
+	newExecutor func(model string) (TaskExecutor, error)
+	metricsRepo repository.CodeMetricsRepository
+	routing     ModelRouting
+}
+
+// NewModelRoutingTaskExecutor creates an executor routing the auto model's tasks as routing configures. newExecutor
+// creates the executor of a model.
+func NewModelRoutingTaskExecutor(
+	next TaskExecutor,
+	newExecutor func(model string) (TaskExecutor, error),
+	metricsRepo repository.CodeMetricsRepository,
+	routing ModelRouting,
+) *ModelRoutingTaskExecutor {
+	return &ModelRoutingTaskExecutor{
+		next:        next,
+		newExecutor: newExecutor,
+		metricsRepo: metricsRepo,
+		routing:     routing,
+	}
+}
+
+// Name implements TaskExecutor
+func (e *ModelRoutingTaskExecutor) Name() string {
+	return e.next.Name()
+}
+
+// Supports implements TaskExecutor
+func (e *ModelRoutingTaskExecutor) Supports(taskType models.TaskType) bool {
+	return e.next.Supports(taskType)
+}
+
+// Execute implements TaskExecutor. Tasks on the auto model are left out of experiments, whose arms pick the model.
+func (e *ModelRoutingTaskExecutor) Execute(ctx context.Context, task *models.TaskWithFullContext) (map[string]any, error) {
+	if model, _ := task.Input[models.TaskInputModelKey].(string); model != models.TaskModelAuto {
+		return e.next.Execute(ctx, task)
+	}
+
+	decision := e.route(ctx, task)
+	decisions := []models.ModelRoutingDecision{decision}
+	output, err := e.execute(ctx, task, decision.Model)
+	if err != nil && decision.Model != e.routing.StrongModel && errors.Is(err, toolloop.ErrInvalidAnswer) {
+		decisions[0].Error = err.Error()
+		slog.InfoContext(ctx, "escalating task to the strong model", "task_id", task.TaskID, "model", e.routing.StrongModel)
+		decisions = append(decisions, models.ModelRoutingDecision{
+			Model:  e.routing.StrongModel,
+			Reason: models.ModelRoutingReasonValidationFailed,
+			Detail: fmt.Sprintf("the answer of %s failed validation", e.routing.CheapModel),
+		})
+		output, err = e.execute(ctx, task, e.routing.StrongModel)
+	}
+	if err != nil {
+		decisions[len(decisions)-1].Error = err.Error()
+	}
+
+	if output == nil {
+		output = map[string]any{}
+	}
+	output[models.TaskOutputModelRoutingKey] = decisions
+	return output, err
+}
+
+// execute executes the task on the model's executor
+func (e *ModelRoutingTaskExecutor) execute(ctx context.Context, task *models.TaskWithFullContext, model string) (map[string]any, error) {
+	executor, err := e.newExecutor(model)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create executor of model %s: %w", model, err)
+	}
+	return executor.Execute(ctx, task)
+}
+
+// route picks the model a task runs on first
+func (e *ModelRoutingTaskExecutor) route(ctx context.Context, task *models.TaskWithFullContext) models.ModelRoutingDecision {
+	strong := func(reason models.ModelRoutingReason, detail string) models.ModelRoutingDecision {
+		return models.ModelRoutingDecision{Model: e.routing.StrongModel, Reason: reason, Detail: detail}
+	}
+	cheap := func(reason models.ModelRoutingReason, detail string) models.ModelRoutingDecision {
+		return models.ModelRoutingDecision{Model: e.routing.CheapModel, Reason: reason, Detail: detail}
+	}
+
+	diff, _ := task.Input[taskDiffInputKey].(string)
+	if detail, isComplex := e.complexity(ctx, task, diff); isComplex {
+		return strong(models.ModelRoutingReasonHighComplexity, detail)
+	}
+	if task.Type == models.TaskTypeDocumentation {
+		return cheap(models.ModelRoutingReasonDocumentation, "documentation tasks run on the cheap model")
+	}
+	if strings.TrimSpace(diff) == "" {
+		return strong(models.ModelRoutingReasonDefault, "tasks without a diff run on the strong model")
+	}
+
+	lines := diffChangedLines(diff)
+	detail := fmt.Sprintf("diff changes %d lines, at most %d run on the cheap model", lines, e.routing.MaxDiffLines)
+	if lines > e.routing.MaxDiffLines {
+		return strong(models.ModelRoutingReasonLargeDiff, detail)
+	}
+	return cheap(models.ModelRoutingReasonSmallDiff, detail)
+}
+
+// complexity reports whether the code a task is about is complex, as of the last metrics snapshot of its codebase:
+// any function of a file the diff changes, or the codebase on average for tasks without a diff. Code without metrics
+// isn't complex, and a failed lookup is logged.
+func (e *ModelRoutingTaskExecutor) complexity(ctx context.Context, task *models.TaskWithFullContext, diff string) (string, bool) {
+	if task.CodebaseID == nil || e.metricsRepo == nil {
+		return "", false
+	}
+	snapshots, err := e.metricsRepo.ListSnapshots(ctx, *task.CodebaseID, time.Time{})
+	if err != nil {
+		slog.WarnContext(ctx, "failed to get code metrics for model routing", "task_id", task.TaskID, "error", err)
+		return "", false
+	}
+	if len(snapshots) == 0 {
+		return "", false
+	}
+	latest := snapshots[len(snapshots)-1]
+
+	if strings.TrimSpace(diff) == "" {
+		if latest.AverageComplexity < e.routing.MaxAverageComplexity {
+			return "", false
+		}
+		return fmt.Sprintf("codebase has a mean complexity of %.1f, from %.1f tasks run on the strong model", latest.AverageComplexity, e.routing.MaxAverageComplexity), true
+	}
+
+	changed := map[string]bool{}
+	for _, section := range diffSections(diff) {
+		changed[section.ID] = true
+	}
+	for _, hotspot := range latest.Hotspots {
+		if changed[hotspot.FilePath] && hotspot.Complexity >= e.routing.MaxComplexity {
+			return fmt.Sprintf("%s in %s has a complexity of %d, from %d tasks run on the strong model", hotspot.Name, hotspot.FilePath, hotspot.Complexity, e.routing.MaxComplexity), true
+		}
+	}
+	return "", false
+}
+
+// diffChangedLines counts the lines a diff adds or removes
+func diffChangedLines(diff string) int {
+	lines := 0
+	for line := range strings.SplitSeq(diff, "\n") {
+		if strings.HasPrefix(line, "+++ ") || strings.HasPrefix(line, "--- ") {
+			continue
+		}
+		if strings.HasPrefix(line, "+") || strings.HasPrefix(line, "-") {
+			lines++
+		}
+	}
+	return lines
+}
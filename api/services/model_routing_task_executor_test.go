@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+	servicesMocks "github.com/kazemisoroush/code-refactoring-tool/api/services/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/toolloop"
+)
+
+var testModelRouting = ModelRouting{
+	CheapModel:           "qwen2.5-coder:1.5b",
+	StrongModel:          "qwen2.5-coder:32b",
+	MaxDiffLines:         10,
+	MaxComplexity:        15,
+	MaxAverageComplexity: 5,
+}
+
+// newTestModelRoutingExecutor creates a routing executor whose models run on the returned executors
+func newTestModelRoutingExecutor(ctrl *gomock.Controller) (*ModelRoutingTaskExecutor, *servicesMocks.MockTaskExecutor, *servicesMocks.MockTaskExecutor, *servicesMocks.MockTaskExecutor, *repositoryMocks.MockCodeMetricsRepository) {
+	next := servicesMocks.NewMockTaskExecutor(ctrl)
+	cheap := servicesMocks.NewMockTaskExecutor(ctrl)
+	strong := servicesMocks.NewMockTaskExecutor(ctrl)
+	metricsRepo := repositoryMocks.NewMockCodeMetricsRepository(ctrl)
+	executor := NewModelRoutingTaskExecutor(next, func(model string) (TaskExecutor, error) {
+		switch model {
+		case testModelRouting.CheapModel:
+			return cheap, nil
+		case testModelRouting.StrongModel:
+			return strong, nil
+		}
+		return nil, fmt.Errorf("unexpected model %s", model)
+	}, metricsRepo, testModelRouting)
+	return executor, next, cheap, strong, metricsRepo
+}
+
+// autoTask returns a task on the auto model about diff
+func autoTask(taskType models.TaskType, diff string) *models.TaskWithFullContext {
+	codebaseID := "codebase-1"
+	input := map[string]any{models.TaskInputModelKey: models.TaskModelAuto}
+	if diff != "" {
+		input[taskDiffInputKey] = diff
+	}
+	return &models.TaskWithFullContext{Task: models.Task{TaskID: "task-1", Type: taskType, CodebaseID: &codebaseID, Input: input}}
+}
+
+// testDiff returns a diff of file adding lines
+func testDiff(file string, lines int) string {
+	return fmt.Sprintf("diff --git a/%s b/%s\n--- a/%s\n+++ b/%s\n@@ -1 +1,%d @@\n%s", file, file, file, file, lines, strings.Repeat("+x\n", lines))
+}
+
+func TestModelRoutingTaskExecutor_Execute_SmallDiffRunsOnCheapModel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	executor, _, cheap, _, metricsRepo := newTestModelRoutingExecutor(ctrl)
+	task := autoTask(models.TaskTypeRefactoring, testDiff("billing/invoice.go", 3))
+
+	metricsRepo.EXPECT().ListSnapshots(gomock.Any(), "codebase-1", gomock.Any()).Return([]models.CodeMetricsSnapshot{
+		{Hotspots: []models.FunctionComplexity{{Name: "Invoice.Total", FilePath: "billing/invoice.go", Complexity: 27}}},
+		{Hotspots: []models.FunctionComplexity{{Name: "Invoice.Total", FilePath: "billing/invoice.go", Complexity: 8}}},
+	}, nil)
+	cheap.EXPECT().Execute(gomock.Any(), task).Return(map[string]any{"answer": "done"}, nil)
+
+	output, err := executor.Execute(context.Background(), task)
+
+	require.NoError(t, err)
+	assert.Equal(t, "done", output["answer"])
+	decisions := output[models.TaskOutputModelRoutingKey].([]models.ModelRoutingDecision)
+	require.Len(t, decisions, 1)
+	assert.Equal(t, testModelRouting.CheapModel, decisions[0].Model)
+	assert.Equal(t, models.ModelRoutingReasonSmallDiff, decisions[0].Reason)
+}
+
+func TestModelRoutingTaskExecutor_Execute_StrongModelCases(t *testing.T) {
+	tests := []struct {
+		name     string
+		task     *models.TaskWithFullContext
+		snapshot models.CodeMetricsSnapshot
+		reason   models.ModelRoutingReason
+	}{
+		{
+			name:   "large diff",
+			task:   autoTask(models.TaskTypeRefactoring, testDiff("billing/invoice.go", 11)),
+			reason: models.ModelRoutingReasonLargeDiff,
+		},
+		{
+			name:     "complex function in the diff",
+			task:     autoTask(models.TaskTypeDocumentation, testDiff("billing/invoice.go", 3)),
+			snapshot: models.CodeMetricsSnapshot{Hotspots: []models.FunctionComplexity{{Name: "Invoice.Total", FilePath: "billing/invoice.go", Complexity: 15}}},
+			reason:   models.ModelRoutingReasonHighComplexity,
+		},
+		{
+			name:     "complex codebase",
+			task:     autoTask(models.TaskTypeDocumentation, ""),
+			snapshot: models.CodeMetricsSnapshot{AverageComplexity: 6.2},
+			reason:   models.ModelRoutingReasonHighComplexity,
+		},
+		{
+			name:   "no diff",
+			task:   autoTask(models.TaskTypeCodeReview, ""),
+			reason: models.ModelRoutingReasonDefault,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			executor, _, _, strong, metricsRepo := newTestModelRoutingExecutor(ctrl)
+
+			metricsRepo.EXPECT().ListSnapshots(gomock.Any(), "codebase-1", gomock.Any()).Return([]models.CodeMetricsSnapshot{tt.snapshot}, nil)
+			strong.EXPECT().Execute(gomock.Any(), tt.task).Return(nil, nil)
+
+			output, err := executor.Execute(context.Background(), tt.task)
+
+			require.NoError(t, err)
+			decisions := output[models.TaskOutputModelRoutingKey].([]models.ModelRoutingDecision)
+			require.Len(t, decisions, 1)
+			assert.Equal(t, testModelRouting.StrongModel, decisions[0].Model)
+			assert.Equal(t, tt.reason, decisions[0].Reason)
+		})
+	}
+}
+
+func TestModelRoutingTaskExecutor_Execute_EscalatesInvalidAnswer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	executor, _, cheap, strong, metricsRepo := newTestModelRoutingExecutor(ctrl)
+	task := autoTask(models.TaskTypeDocumentation, "")
+
+	metricsRepo.EXPECT().ListSnapshots(gomock.Any(), "codebase-1", gomock.Any()).Return(nil, nil)
+	cheap.EXPECT().Execute(gomock.Any(), task).Return(map[string]any{"answer": "not json"}, fmt.Errorf("local agent stopped: %w", toolloop.ErrInvalidAnswer))
+	strong.EXPECT().Execute(gomock.Any(), task).Return(map[string]any{"answer": "{}"}, nil)
+
+	output, err := executor.Execute(context.Background(), task)
+
+	require.NoError(t, err)
+	assert.Equal(t, "{}", output["answer"])
+	assert.Equal(t, []models.ModelRoutingDecision{
+		{
+			Model:  testModelRouting.CheapModel,
+			Reason: models.ModelRoutingReasonDocumentation,
+			Detail: "documentation tasks run on the cheap model",
+			Error:  "local agent stopped: model answer failed validation",
+		},
+		{
+			Model:  testModelRouting.StrongModel,
+			Reason: models.ModelRoutingReasonValidationFailed,
+			Detail: "the answer of qwen2.5-coder:1.5b failed validation",
+		},
+	}, output[models.TaskOutputModelRoutingKey])
+}
+
+func TestModelRoutingTaskExecutor_Execute_OtherFailuresNotEscalated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	executor, _, cheap, _, metricsRepo := newTestModelRoutingExecutor(ctrl)
+	task := autoTask(models.TaskTypeDocumentation, "")
+
+	metricsRepo.EXPECT().ListSnapshots(gomock.Any(), "codebase-1", gomock.Any()).Return(nil, fmt.Errorf("connection refused"))
+	cheap.EXPECT().Execute(gomock.Any(), task).Return(nil, fmt.Errorf("local agent stopped: %w", toolloop.ErrStepBudgetExhausted))
+
+	output, err := executor.Execute(context.Background(), task)
+
+	require.ErrorIs(t, err, toolloop.ErrStepBudgetExhausted)
+	decisions := output[models.TaskOutputModelRoutingKey].([]models.ModelRoutingDecision)
+	require.Len(t, decisions, 1)
+	assert.Equal(t, "local agent stopped: step budget exhausted before the model answered", decisions[0].Error)
+}
+
+func TestModelRoutingTaskExecutor_Execute_PassesOtherTasksOn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	executor, next, _, _, _ := newTestModelRoutingExecutor(ctrl)
+	task := &models.TaskWithFullContext{Task: models.Task{TaskID: "task-1", Type: models.TaskTypeDocumentation}}
+
+	next.EXPECT().Execute(gomock.Any(), task).Return(map[string]any{"answer": "done"}, nil)
+
+	output, err := executor.Execute(context.Background(), task)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"answer": "done"}, output)
+}
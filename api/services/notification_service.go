@@ -0,0 +1,43 @@
+package services
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// Notifier delivers notifications about events to the channels subscribed to them and to the inbox of their user
+//
+//go:generate mockgen -destination=./mocks/mock_notifier.go -mock_names=Notifier=MockNotifier -package=mocks . Notifier
+type Notifier interface {
+	// Notify records the notification for delivery in the background; failures are logged, not returned
+	Notify(ctx context.Context, notification models.Notification)
+}
+
+// NotificationService defines the interface for managing email and Slack notification channels and the in-app inbox
+//
+//go:generate mockgen -destination=./mocks/mock_notification_service.go -mock_names=NotificationService=MockNotificationService -package=mocks . NotificationService
+type NotificationService interface {
+	Notifier
+
+	// CreateChannel creates a notification channel owned by the caller
+	CreateChannel(ctx context.Context, request models.CreateNotificationChannelRequest) (*models.NotificationChannelResponse, error)
+
+	// GetChannel retrieves one of the caller's notification channels
+	GetChannel(ctx context.Context, request models.GetNotificationChannelRequest) (*models.NotificationChannelResponse, error)
+
+	// UpdateChannel updates one of the caller's notification channels
+	UpdateChannel(ctx context.Context, request models.UpdateNotificationChannelRequest) (*models.NotificationChannelResponse, error)
+
+	// DeleteChannel deletes one of the caller's notification channels
+	DeleteChannel(ctx context.Context, request models.DeleteNotificationChannelRequest) (*models.DeleteNotificationChannelResponse, error)
+
+	// ListChannels lists the caller's notification channels
+	ListChannels(ctx context.Context, request models.ListNotificationChannelsRequest) (*models.ListNotificationChannelsResponse, error)
+
+	// ListNotifications lists the caller's inbox notifications with the inbox's unread count
+	ListNotifications(ctx context.Context, request models.ListNotificationsRequest) (*models.ListNotificationsResponse, error)
+
+	// MarkNotificationRead marks one of the caller's inbox notifications read
+	MarkNotificationRead(ctx context.Context, request models.MarkNotificationReadRequest) (*models.MarkNotificationReadResponse, error)
+}
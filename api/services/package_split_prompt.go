@@ -0,0 +1,44 @@
+package services
+
+import (
+	"strings"
+	"text/template"
+
+	analyzermodels "github.com/kazemisoroush/code-refactoring-tool/pkg/analyzer/models"
+)
+
+// packageSplitTemplate asks the agent for a concrete package split fixing an import cycle or a god package, given as
+// a list of moves before they are made
+var packageSplitTemplate = template.Must(template.New("package_split").Funcs(template.FuncMap{
+	"join": strings.Join,
+}).Parse(`{{.Message}}.
+{{- if eq .Type "import_cycle"}}
+The cycle is made by these imports:
+{{- range .Locations}}
+- {{.FilePath}} line {{.StartLine}}
+{{- end}}
+Break it by moving what one package uses of the other into it, by moving what both share into a new package, or by depending on an interface declared by the importing package.
+{{- else}}
+Split it into packages with one responsibility each.
+{{- if .Moves}} These files share a name prefix and may move together:
+{{- range .Moves}}
+- {{join .Files ", "}} to {{.Target}}{{if .Declarations}}, declaring {{join .Declarations ", "}}{{end}}
+{{- end}}
+{{- end}}
+{{- end}}
+{{- range .Suggestions}}
+{{.}}.
+{{- end}}
+
+First propose the split as a list of moves. Give each move's declarations or files, the package they move to and the imports that change, keeping the packages free of import cycles. Then make the moves so that the module still builds, and report each moved file as a change.
+`))
+
+// packageSplitPrompt renders the package split prompt of a package structure finding
+func packageSplitPrompt(finding analyzermodels.CodeIssue) string {
+	var prompt strings.Builder
+	if err := packageSplitTemplate.Execute(&prompt, finding); err != nil {
+		// The template only reads fields of the finding
+		return finding.Message + "."
+	}
+	return prompt.String()
+}
@@ -0,0 +1,18 @@
+package services
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// ProjectManifestService defines the interface for exporting and importing declarative project manifests
+//
+//go:generate mockgen -destination=./mocks/mock_project_manifest_service.go -mock_names=ProjectManifestService=MockProjectManifestService -package=mocks . ProjectManifestService
+type ProjectManifestService interface {
+	// ExportProject builds a manifest describing a project and its codebases, agents, schedules and webhooks
+	ExportProject(ctx context.Context, projectID string) (*models.ProjectManifest, error)
+
+	// ImportProject recreates a project and its resources from a manifest
+	ImportProject(ctx context.Context, manifest models.ProjectManifest) (*models.ImportProjectResponse, error)
+}
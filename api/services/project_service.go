@@ -25,4 +25,10 @@ type ProjectService interface {
 
 	// ListProjects lists projects with pagination and filtering
 	ListProjects(ctx context.Context, request models.ListProjectsRequest) (*models.ListProjectsResponse, error)
+
+	// ArchiveProject archives a project, optionally purging the AI resources of its agents
+	ArchiveProject(ctx context.Context, request models.ArchiveProjectRequest) (*models.ProjectLifecycleResponse, error)
+
+	// UnarchiveProject makes an archived project active again
+	UnarchiveProject(ctx context.Context, request models.UnarchiveProjectRequest) (*models.ProjectLifecycleResponse, error)
 }
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
 	"github.com/kazemisoroush/code-refactoring-tool/api/models"
 	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
 )
@@ -34,7 +35,7 @@ func NewProjectService(
 
 // CreateProject creates a new project
 func (s *ProjectServiceImpl) CreateProject(ctx context.Context, request models.CreateProjectRequest) (*models.CreateProjectResponse, error) {
-	slog.Info("Creating project", "name", request.Name)
+	slog.InfoContext(ctx, "Creating project", "name", request.Name)
 
 	// Generate simple project ID
 	projectID := s.generateProjectID()
@@ -64,7 +65,7 @@ func (s *ProjectServiceImpl) CreateProject(ctx context.Context, request models.C
 
 // GetProject retrieves a project
 func (s *ProjectServiceImpl) GetProject(ctx context.Context, projectID string) (*models.GetProjectResponse, error) {
-	slog.Info("Retrieving project", "project_id", projectID)
+	slog.InfoContext(ctx, "Retrieving project", "project_id", projectID)
 
 	// Retrieve project
 	project, err := s.projectRepo.GetProject(ctx, projectID)
@@ -78,6 +79,7 @@ func (s *ProjectServiceImpl) GetProject(ctx context.Context, projectID string) (
 		Name:        project.Name,
 		Description: project.Description,
 		Language:    project.Language,
+		Status:      models.ProjectStatus(project.Status),
 		CreatedAt:   project.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:   project.UpdatedAt.Format(time.RFC3339),
 		Tags:        project.Tags,
@@ -89,7 +91,7 @@ func (s *ProjectServiceImpl) GetProject(ctx context.Context, projectID string) (
 
 // UpdateProject updates a project
 func (s *ProjectServiceImpl) UpdateProject(ctx context.Context, request models.UpdateProjectRequest) (*models.UpdateProjectResponse, error) {
-	slog.Info("Updating project", "project_id", request.ProjectID)
+	slog.InfoContext(ctx, "Updating project", "project_id", request.ProjectID)
 
 	// Retrieve existing project
 	existingProject, err := s.projectRepo.GetProject(ctx, request.ProjectID)
@@ -109,12 +111,13 @@ func (s *ProjectServiceImpl) UpdateProject(ctx context.Context, request models.U
 	return &models.UpdateProjectResponse{
 		ProjectID: updatedProject.ProjectID,
 		UpdatedAt: updatedProject.UpdatedAt.Format(time.RFC3339),
+		Version:   updatedProject.Version,
 	}, nil
 }
 
 // DeleteProject deletes a project
 func (s *ProjectServiceImpl) DeleteProject(ctx context.Context, projectID string) (*models.DeleteProjectResponse, error) {
-	slog.Info("Deleting project", "project_id", projectID)
+	slog.InfoContext(ctx, "Deleting project", "project_id", projectID)
 
 	// Delete the project
 	if err := s.projectRepo.DeleteProject(ctx, projectID); err != nil {
@@ -128,13 +131,14 @@ func (s *ProjectServiceImpl) DeleteProject(ctx context.Context, projectID string
 
 // ListProjects lists projects
 func (s *ProjectServiceImpl) ListProjects(ctx context.Context, request models.ListProjectsRequest) (*models.ListProjectsResponse, error) {
-	slog.Info("Listing projects", "max_results", request.MaxResults)
+	slog.InfoContext(ctx, "Listing projects", "max_results", request.MaxResults)
 
 	// Build repository options
 	opts := repository.ListProjectsOptions{
-		NextToken:  request.NextToken,
-		MaxResults: request.MaxResults,
-		TagFilter:  request.TagFilter,
+		NextToken:       request.NextToken,
+		MaxResults:      request.MaxResults,
+		TagFilter:       request.TagFilter,
+		IncludeArchived: request.IncludeArchived,
 	}
 
 	// Retrieve projects
@@ -149,6 +153,7 @@ func (s *ProjectServiceImpl) ListProjects(ctx context.Context, request models.Li
 		summaries[i] = models.ProjectSummary{
 			ProjectID: project.ProjectID,
 			Name:      project.Name,
+			Status:    models.ProjectStatus(project.Status),
 			CreatedAt: project.CreatedAt.Format(time.RFC3339),
 			Tags:      project.Tags,
 		}
@@ -165,6 +170,34 @@ func (s *ProjectServiceImpl) ListProjects(ctx context.Context, request models.Li
 	return response, nil
 }
 
+// ArchiveProject archives a project. This service has no access to agents, so it can't purge their artifacts.
+func (s *ProjectServiceImpl) ArchiveProject(ctx context.Context, request models.ArchiveProjectRequest) (*models.ProjectLifecycleResponse, error) {
+	slog.InfoContext(ctx, "Archiving project", "project_id", request.ProjectID)
+
+	if request.PurgeArtifacts {
+		return nil, apperrors.Validation(apperrors.CodeValidation, "purging the artifacts of archived projects is not supported")
+	}
+
+	project, err := setProjectStatus(ctx, s.projectRepo, request.ProjectID, models.ProjectStatusArchived)
+	if err != nil {
+		return nil, err
+	}
+
+	return newProjectLifecycleResponse(project), nil
+}
+
+// UnarchiveProject makes an archived project active again
+func (s *ProjectServiceImpl) UnarchiveProject(ctx context.Context, request models.UnarchiveProjectRequest) (*models.ProjectLifecycleResponse, error) {
+	slog.InfoContext(ctx, "Unarchiving project", "project_id", request.ProjectID)
+
+	project, err := setProjectStatus(ctx, s.projectRepo, request.ProjectID, models.ProjectStatusActive)
+	if err != nil {
+		return nil, err
+	}
+
+	return newProjectLifecycleResponse(project), nil
+}
+
 // Helper Methods
 
 // generateProjectID creates a simple project ID
@@ -177,6 +210,11 @@ func (s *ProjectServiceImpl) buildProjectUpdate(existing *repository.ProjectReco
 	updated := *existing // Copy existing project
 	updated.UpdatedAt = time.Now().UTC()
 
+	// Only apply the update on top of the version the client read
+	if request.ExpectedVersion != 0 {
+		updated.Version = request.ExpectedVersion
+	}
+
 	// Apply selective updates
 	if request.Name != nil {
 		updated.Name = *request.Name
@@ -0,0 +1,15 @@
+package services
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// ProjectSummaryService defines the interface for building project dashboard summaries
+//
+//go:generate mockgen -destination=./mocks/mock_project_summary_service.go -mock_names=ProjectSummaryService=MockProjectSummaryService -package=mocks . ProjectSummaryService
+type ProjectSummaryService interface {
+	// GetProjectSummary aggregates task, codebase and failure statistics for a project
+	GetProjectSummary(ctx context.Context, request models.GetProjectSummaryRequest) (*models.GetProjectSummaryResponse, error)
+}
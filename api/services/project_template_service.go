@@ -0,0 +1,25 @@
+// Package services provides business logic for the API layer
+package services
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// ProjectTemplateService defines the interface for project template operations
+//
+//go:generate mockgen -destination=./mocks/mock_project_template_service.go -mock_names=ProjectTemplateService=MockProjectTemplateService -package=mocks . ProjectTemplateService
+type ProjectTemplateService interface {
+	// ListTemplates lists built-in and custom project templates
+	ListTemplates(ctx context.Context) (*models.ListProjectTemplatesResponse, error)
+
+	// GetTemplate retrieves a built-in or custom project template by ID
+	GetTemplate(ctx context.Context, templateID string) (*models.ProjectTemplate, error)
+
+	// CreateTemplate defines a new custom project template
+	CreateTemplate(ctx context.Context, request models.CreateProjectTemplateRequest) (*models.CreateProjectTemplateResponse, error)
+
+	// CreateProjectFromTemplate bootstraps a new project using a template's defaults
+	CreateProjectFromTemplate(ctx context.Context, request models.CreateProjectFromTemplateRequest) (*models.CreateProjectFromTemplateResponse, error)
+}
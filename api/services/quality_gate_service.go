@@ -0,0 +1,23 @@
+package services
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// QualityGateService defines the interface for the severity rules and quality gates of projects
+//
+//go:generate mockgen -destination=./mocks/mock_quality_gate_service.go -mock_names=QualityGateService=MockQualityGateService -package=mocks . QualityGateService
+type QualityGateService interface {
+	// GetPolicy returns the quality gate policy of a project, or the default policy if none is configured
+	GetPolicy(ctx context.Context, request models.GetQualityGatePolicyRequest) (*models.QualityGatePolicy, error)
+
+	// UpdatePolicy validates and replaces the quality gate policy of a project
+	UpdatePolicy(ctx context.Context, request models.UpdateQualityGatePolicyRequest) (*models.QualityGatePolicy, error)
+
+	// EvaluateGates evaluates the quality gates of an analysis task's project against the findings the task synced,
+	// nil for metrics analyses, and the metrics snapshot it recorded, nil if none. It returns nil when the project
+	// has no gates.
+	EvaluateGates(ctx context.Context, task *models.TaskWithFullContext, sync *models.FindingSyncResult, snapshot *models.CodeMetricsSnapshot) (*models.QualityGateReport, error)
+}
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/redact"
+)
+
+// RedactionService defines the interface for the per-project policies masking RAG content and their audits
+//
+//go:generate mockgen -destination=./mocks/mock_redaction_service.go -mock_names=RedactionService=MockRedactionService -package=mocks . RedactionService
+type RedactionService interface {
+	// GetPolicy returns the redaction policy of a project, or the default policy if none is configured
+	GetPolicy(ctx context.Context, request models.GetRedactionPolicyRequest) (*models.RedactionPolicy, error)
+
+	// UpdatePolicy validates and replaces the redaction policy of a project
+	UpdatePolicy(ctx context.Context, request models.UpdateRedactionPolicyRequest) (*models.RedactionPolicy, error)
+
+	// ListAudits lists the redaction counts of a project's most recent syncs
+	ListAudits(ctx context.Context, request models.ListRedactionAuditsRequest) (*models.ListRedactionAuditsResponse, error)
+
+	// ExportAudits passes every redaction audit of a project to fn, most recent first, ignoring the limit
+	ExportAudits(ctx context.Context, request models.ListRedactionAuditsRequest, fn func(models.RedactionAudit) error) error
+
+	// ResolvePolicy returns the policy applied when syncing an agent of the project; agents without a project use
+	// the default policy
+	ResolvePolicy(ctx context.Context, projectID string) (redact.Policy, error)
+
+	// RecordAudit stores the redaction counts of an agent sync
+	RecordAudit(ctx context.Context, projectID, agentID string, operation models.RedactionOperation, report redact.Report) error
+}
@@ -0,0 +1,18 @@
+package services
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// ReportService defines the interface for task metrics reporting
+//
+//go:generate mockgen -destination=./mocks/mock_report_service.go -mock_names=ReportService=MockReportService -package=mocks . ReportService
+type ReportService interface {
+	// GetTaskReport reports task success rate, duration, token usage and failure categories grouped by day, week or project
+	GetTaskReport(ctx context.Context, request models.GetTaskReportRequest) (*models.GetTaskReportResponse, error)
+
+	// RefreshTaskMetrics recomputes the recent task metrics rollups the reports are read from
+	RefreshTaskMetrics(ctx context.Context) error
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// RoleService defines the interface for roles, the permissions they grant and the members of projects, and
+// evaluates the permissions of callers against them
+//
+//go:generate mockgen -destination=./mocks/mock_role_service.go -mock_names=RoleService=MockRoleService -package=mocks . RoleService
+type RoleService interface {
+	// ListPermissions lists the permissions roles can grant
+	ListPermissions(ctx context.Context) (*models.ListPermissionsResponse, error)
+
+	// CreateRole defines a custom role
+	CreateRole(ctx context.Context, request models.CreateRoleRequest) (*models.Role, error)
+
+	// GetRole retrieves a role with its permissions
+	GetRole(ctx context.Context, name models.UserRole) (*models.Role, error)
+
+	// UpdateRole replaces the description and permissions of a role
+	UpdateRole(ctx context.Context, request models.UpdateRoleRequest) (*models.Role, error)
+
+	// DeleteRole deletes a custom role no user holds
+	DeleteRole(ctx context.Context, request models.DeleteRoleRequest) error
+
+	// ListRoles lists the roles with their permissions
+	ListRoles(ctx context.Context) (*models.ListRolesResponse, error)
+
+	// ListProjectMembers lists the members of a project
+	ListProjectMembers(ctx context.Context, projectID string) (*models.ListProjectMembersResponse, error)
+
+	// AddProjectMember adds a member to a project, or changes the role of a member other than the owner
+	AddProjectMember(ctx context.Context, request models.AddProjectMemberRequest) (*models.ProjectMember, error)
+
+	// RemoveProjectMember removes a member other than the owner from a project
+	RemoveProjectMember(ctx context.Context, request models.RemoveProjectMemberRequest) error
+
+	// TransferProjectOwnership makes another user the owner of a project, demoting the previous owner to admin
+	TransferProjectOwnership(ctx context.Context, request models.TransferProjectOwnershipRequest) (*models.ProjectMember, error)
+
+	// AssignProjectOwner makes the user with the auth provider ID the owner of a project they created
+	AssignProjectOwner(ctx context.Context, projectID, authID string) error
+
+	// Authorize checks that the user with the auth provider ID holds a permission, within a project when projectID is
+	// set. The role the user holds as a member of the project overrides their own role.
+	Authorize(ctx context.Context, authID, projectID string, permission models.Permission) error
+}
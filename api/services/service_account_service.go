@@ -0,0 +1,38 @@
+package services
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/auth"
+)
+
+// ServiceAccountService defines the interface for service accounts, the non-human callers authenticating with
+// long-lived tokens
+//
+//go:generate mockgen -destination=./mocks/mock_service_account_service.go -mock_names=ServiceAccountService=MockServiceAccountService -package=mocks . ServiceAccountService
+type ServiceAccountService interface {
+	// CreateServiceAccount creates a service account and the user it acts as
+	CreateServiceAccount(ctx context.Context, request models.CreateServiceAccountRequest) (*models.ServiceAccount, error)
+
+	// GetServiceAccount gets a service account
+	GetServiceAccount(ctx context.Context, request models.GetServiceAccountRequest) (*models.ServiceAccount, error)
+
+	// ListServiceAccounts lists the service accounts ordered by name
+	ListServiceAccounts(ctx context.Context) (*models.ListServiceAccountsResponse, error)
+
+	// DeleteServiceAccount deletes a service account, its tokens and the user it acts as
+	DeleteServiceAccount(ctx context.Context, request models.DeleteServiceAccountRequest) error
+
+	// CreateToken issues a token to a service account, returned only this once
+	CreateToken(ctx context.Context, request models.CreateServiceAccountTokenRequest) (*models.CreateServiceAccountTokenResponse, error)
+
+	// ListTokens lists the tokens of a service account, newest first
+	ListTokens(ctx context.Context, request models.ListServiceAccountTokensRequest) (*models.ListServiceAccountTokensResponse, error)
+
+	// RevokeToken revokes a token of a service account
+	RevokeToken(ctx context.Context, request models.RevokeServiceAccountTokenRequest) error
+
+	// ValidateToken validates a service account token, returning the claims of the user the service account acts as
+	ValidateToken(ctx context.Context, token string) (*auth.TokenClaims, error)
+}
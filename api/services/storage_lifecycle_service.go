@@ -0,0 +1,38 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+)
+
+// StorageLifecycleService defines the interface for purging the S3 content of deleted agents and reporting the content
+// no agent owns
+//
+//go:generate mockgen -destination=./mocks/mock_storage_lifecycle_service.go -mock_names=StorageLifecycleService=MockStorageLifecycleService -package=mocks . StorageLifecycleService
+type StorageLifecycleService interface {
+	// PurgeAgentContent records a purge of the content uploaded for the knowledge base of an agent, run in the
+	// background. It returns nil when the agent uploaded no content to S3.
+	PurgeAgentContent(ctx context.Context, agent *repository.AgentRecord) (*models.StoragePurge, error)
+
+	// GetPurge gets a purge with its progress
+	GetPurge(ctx context.Context, request models.GetStoragePurgeRequest) (*models.StoragePurge, error)
+
+	// ListPurges lists the most recent purges, newest first
+	ListPurges(ctx context.Context, request models.ListStoragePurgesRequest) (*models.ListStoragePurgesResponse, error)
+
+	// RunPurges claims the due purges and deletes their content, returning how many completed
+	RunPurges(ctx context.Context) (int, error)
+
+	// RunPurgeQueue runs the due purges every interval until ctx is cancelled
+	RunPurgeQueue(ctx context.Context, interval time.Duration)
+
+	// ListOrphans lists the knowledge base prefixes of the buckets of every allowed region that no agent owns and no
+	// purge is deleting
+	ListOrphans(ctx context.Context) (*models.ListStorageOrphansResponse, error)
+
+	// RunReconciliation logs the orphaned prefixes every interval until ctx is cancelled
+	RunReconciliation(ctx context.Context, interval time.Duration)
+}
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// TagService defines the interface for the tag key registry and the governance of the tags set on projects,
+// codebases and codebase configurations
+//
+//go:generate mockgen -destination=./mocks/mock_tag_service.go -mock_names=TagService=MockTagService -package=mocks . TagService
+type TagService interface {
+	// CreateTagKey registers a tag key with the rules its tags must follow
+	CreateTagKey(ctx context.Context, request models.CreateTagKeyRequest) (*models.TagKey, error)
+
+	// GetTagKey retrieves a registered tag key
+	GetTagKey(ctx context.Context, key string) (*models.TagKey, error)
+
+	// UpdateTagKey replaces the rules of a registered tag key
+	UpdateTagKey(ctx context.Context, request models.UpdateTagKeyRequest) (*models.TagKey, error)
+
+	// DeleteTagKey unregisters a tag key, leaving the tags already set with it in place
+	DeleteTagKey(ctx context.Context, key string) error
+
+	// ListTagKeys lists the registered tag keys
+	ListTagKeys(ctx context.Context) (*models.ListTagKeysResponse, error)
+
+	// AuthorizeTags checks the tags a user changes by replacing current with updated against the registry. Only the
+	// tags added, changed or removed are checked, so resources keep the tags they held before a rule was registered.
+	AuthorizeTags(ctx context.Context, userID string, current, updated map[string]string) error
+}
@@ -0,0 +1,24 @@
+package services
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// TaskCommentService defines the interface for comments and review threads on tasks
+//
+//go:generate mockgen -destination=./mocks/mock_task_comment_service.go -mock_names=TaskCommentService=MockTaskCommentService -package=mocks . TaskCommentService
+type TaskCommentService interface {
+	// CreateComment comments on a task, starting a review thread or replying to one
+	CreateComment(ctx context.Context, request models.CreateTaskCommentRequest) (*models.TaskComment, error)
+
+	// ListComments lists a task's comments oldest first
+	ListComments(ctx context.Context, request models.ListTaskCommentsRequest) (*models.ListTaskCommentsResponse, error)
+
+	// UpdateComment edits one of the caller's comments
+	UpdateComment(ctx context.Context, request models.UpdateTaskCommentRequest) (*models.TaskComment, error)
+
+	// DeleteComment deletes one of the caller's comments along with its replies
+	DeleteComment(ctx context.Context, request models.DeleteTaskCommentRequest) (*models.DeleteTaskCommentResponse, error)
+}
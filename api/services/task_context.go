@@ -0,0 +1,272 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/contextpack"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/rag"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/noise"
+)
+
+// taskDiffInputKey is the task input holding the diff a task is about, such as the changes of a pull request
+const taskDiffInputKey = "diff"
+
+// TaskContextRetriever retrieves up to limit chunks relevant to a query from the knowledge base of a task's agent
+type TaskContextRetriever func(ctx context.Context, task *models.TaskWithFullContext, query string, limit int) ([]rag.Chunk, error)
+
+// NewKnowledgeBaseContextRetriever creates a retriever of the chunks of the knowledge base of a task's agent, using
+// the retriever of the agent's region. Agents without a knowledge base, such as local agents, retrieve nothing.
+func NewKnowledgeBaseContextRetriever(agentRepo repository.AgentRepository, retrievers func(region string) rag.Retriever) TaskContextRetriever {
+	return func(ctx context.Context, task *models.TaskWithFullContext, query string, limit int) ([]rag.Chunk, error) {
+		if task.AgentID == "" {
+			return nil, nil
+		}
+		agent, err := agentRepo.GetAgent(ctx, task.AgentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get agent: %w", err)
+		}
+		if agent.GetAIProvider() == models.AIProviderLocal || agent.KnowledgeBaseID == "" {
+			return nil, nil
+		}
+		return retrievers(agent.Region).Retrieve(ctx, agent.KnowledgeBaseID, query, limit)
+	}
+}
+
+// TaskContextFilter resolves the filter of the noise files left out of a task's prompt
+type TaskContextFilter func(ctx context.Context, task *models.TaskWithFullContext) (*noise.Filter, error)
+
+// NewProjectContextFilter creates a filter resolver applying the noise file filter of the redaction policy of a task's
+// project, so that task prompts leave out the files its knowledge bases do
+func NewProjectContextFilter(redactionService RedactionService) TaskContextFilter {
+	return func(ctx context.Context, task *models.TaskWithFullContext) (*noise.Filter, error) {
+		policy, err := redactionService.ResolvePolicy(ctx, task.ProjectID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve redaction policy: %w", err)
+		}
+		return noise.NewFilter(policy.Files)
+	}
+}
+
+// TaskContextHistory looks up the git history of files of a task's codebase, of up to limit files changed the most
+// when paths is empty
+type TaskContextHistory func(ctx context.Context, task *models.TaskWithFullContext, paths []string, limit int) ([]models.FileHistory, error)
+
+// NewFileHistoryContext creates a lookup of the git history of the files of a task's codebase, as read by its latest
+// analysis. Tasks without a codebase have no history.
+func NewFileHistoryContext(historyService FileHistoryService) TaskContextHistory {
+	return func(ctx context.Context, task *models.TaskWithFullContext, paths []string, limit int) ([]models.FileHistory, error) {
+		if task.CodebaseID == nil {
+			return nil, nil
+		}
+		return historyService.LookupHistory(ctx, *task.CodebaseID, paths, limit)
+	}
+}
+
+// TaskContext configures how the prompt describing a task is packed under the context budget of the model given it
+type TaskContext struct {
+	Model     string                 // Model whose limits budget the prompt
+	Assembler *contextpack.Assembler // Packs the prompt, with the default tokenizer registry when nil
+	Retrieve  TaskContextRetriever   // Retrieves chunks of the task's knowledge base, none when nil
+	Chunks    int                    // Most chunks retrieved for a task
+	Filter    TaskContextFilter      // Resolves the noise files left out, every kind of them when nil
+	History   TaskContextHistory     // Looks up the git history of the task's files, left out when nil
+}
+
+// assemble packs the instructions of a task, the snippets of the diff it's about, the chunks retrieved for it and the
+// git history of their files into a prompt, reserving the tokens of the system prompt. Snippets and chunks of noise
+// files, such as lockfiles, are left out. The trace records the sections left out or truncated. A failed retrieval is
+// logged, and the task is described without chunks or history.
+func (c TaskContext) assemble(ctx context.Context, task *models.TaskWithFullContext, system string) (string, contextpack.Trace) {
+	assembler := c.Assembler
+	if assembler == nil {
+		assembler = contextpack.NewAssembler(contextpack.DefaultRegistry())
+	}
+	filter := c.filter(ctx, task)
+
+	sections := []contextpack.Section{{ID: "task", Kind: contextpack.KindInstructions, Text: taskInstructions(task), Required: true}}
+	if diff, ok := task.Input[taskDiffInputKey].(string); ok {
+		for _, section := range diffSections(diff) {
+			_, section.Noise = filter.Excluded(section.ID, diffContent(section.Text))
+			sections = append(sections, section)
+		}
+	}
+	if c.Retrieve != nil && c.Chunks > 0 {
+		chunks, err := c.Retrieve(ctx, task, task.Title+"\n"+task.Description, c.Chunks)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to retrieve task context", "task_id", task.TaskID, "error", err)
+		}
+		for _, chunk := range chunks {
+			_, isNoise := filter.Excluded(chunk.Source, []byte(chunk.Text))
+			sections = append(sections, contextpack.Section{ID: chunkID(chunk), Kind: contextpack.KindChunk, Text: chunk.Text, Score: chunk.Score, Noise: isNoise})
+		}
+	}
+	if section, ok := c.historySection(ctx, task, sections); ok {
+		sections = append(sections, section)
+	}
+
+	reserved := assembler.Limits(c.Model).Tokenizer.Count(system)
+	return assembler.Assemble(c.Model, reserved, sections)
+}
+
+// historySection summarizes the git history of the files of the diff snippets and chunks among sections, or of the
+// files changed the most when there are none, so the model can tell hot spots from files left alone
+func (c TaskContext) historySection(ctx context.Context, task *models.TaskWithFullContext, sections []contextpack.Section) (contextpack.Section, bool) {
+	if c.History == nil {
+		return contextpack.Section{}, false
+	}
+
+	var paths []string
+	for _, section := range sections {
+		path := ""
+		switch {
+		case section.Noise:
+		case section.Kind == contextpack.KindDiff && section.ID != "diff":
+			path = section.ID
+		case section.Kind == contextpack.KindChunk:
+			path, _, _ = strings.Cut(section.ID, ":")
+		}
+		if path != "" && !slices.Contains(paths, path) {
+			paths = append(paths, path)
+		}
+	}
+	histories, err := c.History(ctx, task, paths, models.MaxTaskOutputFileHistories)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to look up file history", "task_id", task.TaskID, "error", err)
+		return contextpack.Section{}, false
+	}
+	if len(histories) == 0 {
+		return contextpack.Section{}, false
+	}
+
+	heading := "Files of the codebase changed the most, where refactoring pays off first:\n"
+	if len(paths) > 0 {
+		heading = "Git history of the files of the diff and context, files changed often and recently are hot spots:\n"
+	}
+	return contextpack.Section{ID: "file_history", Kind: contextpack.KindHistory, Text: heading + describeFileHistories(histories, time.Now())}, true
+}
+
+// describeHistory describes the git history of a file of a task's codebase, or lists the files changed the most when
+// path is empty
+func (c TaskContext) describeHistory(ctx context.Context, task *models.TaskWithFullContext, path string) (string, error) {
+	var paths []string
+	if path != "" {
+		paths = []string{path}
+	}
+	histories, err := c.History(ctx, task, paths, models.MaxTaskOutputFileHistories)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up file history: %w", err)
+	}
+	if len(histories) == 0 {
+		if path != "" {
+			return fmt.Sprintf("no recent commits changed %s", path), nil
+		}
+		return "no file history is recorded for the codebase", nil
+	}
+	return describeFileHistories(histories, time.Now()), nil
+}
+
+// describeFileHistories describes the git history of files to the model, a line per file
+func describeFileHistories(histories []models.FileHistory, now time.Time) string {
+	var b strings.Builder
+	for _, history := range histories {
+		fmt.Fprintf(&b, "%s: %d commits changing %d lines (+%d -%d), last %d days ago, first %d days ago, by %s\n",
+			history.Path, history.Commits, history.Churn, history.LinesAdded, history.LinesDeleted,
+			daysSince(history.LastCommitAt, now), daysSince(history.FirstCommitAt, now), strings.Join(history.Authors, ", "))
+	}
+	return b.String()
+}
+
+// daysSince returns the whole days from t to now
+func daysSince(t, now time.Time) int {
+	return int(now.Sub(t).Hours() / 24)
+}
+
+// filter resolves the noise file filter of a task, falling back to leaving out every kind of noise file when it can't
+// be resolved
+func (c TaskContext) filter(ctx context.Context, task *models.TaskWithFullContext) *noise.Filter {
+	if c.Filter != nil {
+		filter, err := c.Filter(ctx, task)
+		if err == nil {
+			return filter
+		}
+		slog.WarnContext(ctx, "failed to resolve noise file filter, using the default", "task_id", task.TaskID, "error", err)
+	}
+	filter, _ := noise.NewFilter(noise.DefaultPolicy())
+	return filter
+}
+
+// taskInstructions describes a task to the model, leaving out the diff of its input, which is packed on its own
+func taskInstructions(task *models.TaskWithFullContext) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Task type: %s\nTitle: %s\n\n%s\n", task.Type, task.Title, task.Description)
+
+	input := maps.Clone(task.Input)
+	delete(input, taskDiffInputKey)
+	if len(input) > 0 {
+		if encoded, err := json.Marshal(input); err == nil {
+			fmt.Fprintf(&b, "\nInput: %s\n", encoded)
+		}
+	}
+	return b.String()
+}
+
+// diffSections splits a unified diff into a snippet per file, identified by the file's path
+func diffSections(diff string) []contextpack.Section {
+	if strings.TrimSpace(diff) == "" {
+		return nil
+	}
+
+	var sections []contextpack.Section
+	for _, line := range strings.SplitAfter(diff, "\n") {
+		if strings.HasPrefix(line, "diff --git ") || len(sections) == 0 {
+			sections = append(sections, contextpack.Section{ID: diffPath(line), Kind: contextpack.KindDiff})
+		}
+		sections[len(sections)-1].Text += line
+	}
+	return sections
+}
+
+// diffContent returns the lines a diff snippet adds, which for a new file are its content. Binary changes are returned
+// as binary content, since their snippet only names them.
+func diffContent(snippet string) []byte {
+	var b bytes.Buffer
+	for _, line := range strings.SplitAfter(snippet, "\n") {
+		switch {
+		case strings.HasPrefix(line, "Binary files ") || strings.HasPrefix(line, "GIT binary patch"):
+			return []byte{0}
+		case strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++ "):
+			b.WriteString(line[1:])
+		}
+	}
+	return b.Bytes()
+}
+
+// diffPath returns the path of the file a diff header line names, or "diff" for other lines
+func diffPath(line string) string {
+	header, ok := strings.CutPrefix(strings.TrimSpace(line), "diff --git ")
+	if !ok {
+		return "diff"
+	}
+	if _, path, ok := strings.Cut(header, " b/"); ok {
+		return path
+	}
+	return header
+}
+
+// chunkID identifies a chunk by the path and lines of its source
+func chunkID(chunk rag.Chunk) string {
+	if chunk.StartLine == 0 {
+		return chunk.Source
+	}
+	return fmt.Sprintf("%s:%d-%d", chunk.Source, chunk.StartLine, chunk.EndLine)
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/contextpack"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/rag"
+	ragMocks "github.com/kazemisoroush/code-refactoring-tool/pkg/ai/rag/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/noise"
+)
+
+const testTaskDiff = `diff --git a/retry.go b/retry.go
+--- a/retry.go
++++ b/retry.go
+@@ -1 +1 @@
+-const attempts = 3
++const attempts = 5
+diff --git a/config.yaml b/config.yaml
+--- a/config.yaml
++++ b/config.yaml
+@@ -1 +1 @@
+-retries: 3
++retries: 5
+`
+
+func newContextTask() *models.TaskWithFullContext {
+	return &models.TaskWithFullContext{Task: models.Task{
+		TaskID:      "task-1",
+		AgentID:     "agent-1",
+		Type:        models.TaskTypeCodeReview,
+		Title:       "Review retries",
+		Description: "Check the retry changes",
+		Input:       map[string]any{"diff": testTaskDiff, "focus": "retries"},
+	}}
+}
+
+func TestTaskContext_Assemble_PacksDiffAndChunks(t *testing.T) {
+	var query string
+	taskContext := TaskContext{
+		Model: "llama3",
+		Retrieve: func(_ context.Context, _ *models.TaskWithFullContext, q string, limit int) ([]rag.Chunk, error) {
+			query = q
+			assert.Equal(t, 5, limit)
+			return []rag.Chunk{{Text: "func scheduleRetry() {}", Source: "scheduler.go", StartLine: 10, EndLine: 12, Score: 0.7}}, nil
+		},
+		Chunks: 5,
+	}
+
+	prompt, trace := taskContext.assemble(context.Background(), newContextTask(), "system prompt")
+
+	assert.Equal(t, "Review retries\nCheck the retry changes", query)
+	assert.Contains(t, prompt, `Input: {"focus":"retries"}`)
+	assert.NotContains(t, prompt, `"diff"`, "the diff is packed on its own")
+	assert.Contains(t, prompt, "Diff of retry.go:")
+	assert.Contains(t, prompt, "Context from scheduler.go:10-12:")
+
+	var ids []string
+	for _, entry := range trace.Sections {
+		assert.True(t, entry.Included, entry.ID)
+		ids = append(ids, entry.ID)
+	}
+	assert.Equal(t, []string{"task", "config.yaml", "retry.go", "scheduler.go:10-12"}, ids)
+	assert.Equal(t, 8192-2048-contextpack.DefaultRegistry().Lookup("llama3").Tokenizer.Count("system prompt"), trace.Budget)
+}
+
+func TestTaskContext_Assemble_LeavesOutNoise(t *testing.T) {
+	task := newContextTask()
+	task.Input["diff"] = testTaskDiff + `diff --git a/go.sum b/go.sum
+--- a/go.sum
++++ b/go.sum
+@@ -1 +1,2 @@
++github.com/acme/lib v1.0.0 h1:abc=
+diff --git a/api/user.pb.go b/api/user.pb.go
+new file mode 100644
+--- /dev/null
++++ b/api/user.pb.go
+@@ -0,0 +1,2 @@
++// Code generated by protoc-gen-go. DO NOT EDIT.
++package api
+`
+	taskContext := TaskContext{
+		Model: "llama3",
+		Retrieve: func(context.Context, *models.TaskWithFullContext, string, int) ([]rag.Chunk, error) {
+			return []rag.Chunk{{Text: "lodash@^4.17.21:", Source: "web/yarn.lock", Score: 0.9}}, nil
+		},
+		Chunks: 5,
+		Filter: func(context.Context, *models.TaskWithFullContext) (*noise.Filter, error) {
+			return noise.NewFilter(noise.Policy{Excluded: noise.Kinds(), Include: []string{"api/"}})
+		},
+	}
+
+	prompt, trace := taskContext.assemble(context.Background(), task, "")
+
+	reasons := map[string]string{}
+	for _, entry := range trace.Sections {
+		reasons[entry.ID] = entry.Reason
+	}
+	assert.Equal(t, contextpack.ReasonNoise, reasons["go.sum"])
+	assert.Equal(t, contextpack.ReasonNoise, reasons["web/yarn.lock"])
+	assert.Empty(t, reasons["api/user.pb.go"], "include patterns keep generated files")
+	assert.NotContains(t, prompt, "lodash")
+	assert.Contains(t, prompt, "Diff of api/user.pb.go:")
+}
+
+func TestTaskContext_Assemble_PacksFileHistory(t *testing.T) {
+	now := time.Now()
+	taskContext := TaskContext{
+		Model: "llama3",
+		Retrieve: func(context.Context, *models.TaskWithFullContext, string, int) ([]rag.Chunk, error) {
+			return []rag.Chunk{{Text: "func scheduleRetry() {}", Source: "scheduler.go", StartLine: 10, EndLine: 12, Score: 0.7}}, nil
+		},
+		Chunks: 5,
+		History: func(_ context.Context, _ *models.TaskWithFullContext, paths []string, limit int) ([]models.FileHistory, error) {
+			assert.Equal(t, []string{"retry.go", "config.yaml", "scheduler.go"}, paths)
+			assert.Equal(t, models.MaxTaskOutputFileHistories, limit)
+			return []models.FileHistory{{
+				Path: "retry.go", Commits: 14, LinesAdded: 220, LinesDeleted: 96, Churn: 316, Authors: []string{"alice", "bob"},
+				FirstCommitAt: now.Add(-300 * 24 * time.Hour), LastCommitAt: now.Add(-2 * 24 * time.Hour),
+			}}, nil
+		},
+	}
+
+	prompt, trace := taskContext.assemble(context.Background(), newContextTask(), "")
+
+	assert.Contains(t, prompt, "retry.go: 14 commits changing 316 lines (+220 -96), last 2 days ago, first 300 days ago, by alice, bob")
+	var ids []string
+	for _, entry := range trace.Sections {
+		ids = append(ids, entry.ID)
+	}
+	assert.Equal(t, []string{"task", "config.yaml", "retry.go", "file_history", "scheduler.go:10-12"}, ids)
+}
+
+func TestTaskContext_DescribeHistory_NoHistory(t *testing.T) {
+	taskContext := TaskContext{
+		History: func(_ context.Context, _ *models.TaskWithFullContext, paths []string, _ int) ([]models.FileHistory, error) {
+			assert.Equal(t, []string{"retry.go"}, paths)
+			return nil, nil
+		},
+	}
+
+	description, err := taskContext.describeHistory(context.Background(), newContextTask(), "retry.go")
+
+	require.NoError(t, err)
+	assert.Equal(t, "no recent commits changed retry.go", description)
+}
+
+func TestTaskContext_Assemble_RetrievalFailure(t *testing.T) {
+	taskContext := TaskContext{
+		Model: "llama3",
+		Retrieve: func(context.Context, *models.TaskWithFullContext, string, int) ([]rag.Chunk, error) {
+			return nil, errors.New("throttled")
+		},
+		Chunks: 5,
+	}
+
+	prompt, trace := taskContext.assemble(context.Background(), newContextTask(), "")
+
+	assert.True(t, strings.HasPrefix(prompt, "Task type: code_review\nTitle: Review retries"))
+	assert.Len(t, trace.Sections, 3, "the task is described without chunks")
+}
+
+func TestDiffSections(t *testing.T) {
+	sections := diffSections(testTaskDiff)
+
+	require.Len(t, sections, 2)
+	assert.Equal(t, "retry.go", sections[0].ID)
+	assert.True(t, strings.HasPrefix(sections[0].Text, "diff --git a/retry.go"))
+	assert.Equal(t, "config.yaml", sections[1].ID)
+	assert.Equal(t, contextpack.KindDiff, sections[1].Kind)
+
+	assert.Nil(t, diffSections("  \n"))
+	assert.Equal(t, "diff", diffSections("+just a line\n")[0].ID)
+}
+
+func TestNewKnowledgeBaseContextRetriever(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	agentRepo := repositoryMocks.NewMockAgentRepository(ctrl)
+	retriever := ragMocks.NewMockRetriever(ctrl)
+	var regions []string
+	retrieve := NewKnowledgeBaseContextRetriever(agentRepo, func(region string) rag.Retriever {
+		regions = append(regions, region)
+		return retriever
+	})
+
+	agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{
+		AgentID:         "agent-1",
+		KnowledgeBaseID: "kb-1",
+		AIProvider:      string(models.AIProviderBedrock),
+		Region:          "eu-west-1",
+	}, nil)
+	retriever.EXPECT().Retrieve(gomock.Any(), "kb-1", "query", 5).Return([]rag.Chunk{{Text: "chunk"}}, nil)
+
+	chunks, err := retrieve(context.Background(), newContextTask(), "query", 5)
+
+	require.NoError(t, err)
+	assert.Len(t, chunks, 1)
+	assert.Equal(t, []string{"eu-west-1"}, regions)
+}
+
+func TestNewKnowledgeBaseContextRetriever_LocalAgent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	agentRepo := repositoryMocks.NewMockAgentRepository(ctrl)
+	retrieve := NewKnowledgeBaseContextRetriever(agentRepo, func(string) rag.Retriever {
+		t.Fatal("local agents have no knowledge base to retrieve from")
+		return nil
+	})
+
+	agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{
+		AgentID:    "agent-1",
+		AIProvider: string(models.AIProviderLocal),
+	}, nil)
+
+	chunks, err := retrieve(context.Background(), newContextTask(), "query", 5)
+
+	require.NoError(t, err)
+	assert.Empty(t, chunks)
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// QueueTask queues a pending task for the dispatcher to run, ahead of lower priority tasks and those of busier
+// projects
+func (s *TaskServiceImpl) QueueTask(ctx context.Context, taskID string) error {
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return fmt.Errorf("failed to get task: %w", err)
+	}
+
+	return s.queueTask(ctx, taskID, executeRequestFromTask(task))
+}
+
+// queueTask moves a task to queued and wakes the dispatcher up to run it
+func (s *TaskServiceImpl) queueTask(ctx context.Context, taskID string, req *models.ExecuteTaskRequest) error {
+	notification := taskTransitionNotification(executedTask(taskID, req, models.TaskStatusQueued))
+	if err := s.taskRepo.UpdateStatus(ctx, taskID, models.TaskStatusQueued, notification); err != nil {
+		return fmt.Errorf("failed to queue task: %w", err)
+	}
+
+	s.wakeDispatcher()
+	return nil
+}
+
+// wakeDispatcher tells an idle dispatcher a task was queued, without waiting for it
+func (s *TaskServiceImpl) wakeDispatcher() {
+	select {
+	case s.queued <- struct{}{}:
+	default:
+	}
+}
+
+// RunTaskDispatcher runs the queued tasks on at most workers executions at a time until the context is cancelled.
+// Whenever a worker is free it starts the task NextQueued returns, and while the queue is empty it looks again every
+// interval, or as soon as a task is queued in this process. Executions outlive the dispatcher, like the shutdown they
+// are interrupted by.
+func (s *TaskServiceImpl) RunTaskDispatcher(ctx context.Context, interval time.Duration, workers int) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	runCtx := context.WithoutCancel(ctx)
+	slots := make(chan struct{}, max(workers, 1))
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case slots <- struct{}{}:
+		}
+
+		task, err := s.startNextQueued(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to start queued task", "error", err)
+		}
+		if task != nil {
+			go func() {
+				defer func() { <-slots }()
+				execution := &taskExecution{service: s, taskID: task.TaskID, req: executeRequestFromTask(task)}
+				if _, err := s.runTaskExecution(runCtx, execution); err != nil {
+					slog.WarnContext(runCtx, "queued task failed", "task_id", task.TaskID, "error", err)
+				}
+			}()
+			continue
+		}
+
+		// The queue is empty, or couldn't be read
+		<-slots
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.queued:
+		}
+	}
+}
+
+// startNextQueued moves the task to run next to in_progress, returning nil when no task is queued. A task another
+// dispatcher started, or a user cancelled, since it was read is skipped for the next one.
+func (s *TaskServiceImpl) startNextQueued(ctx context.Context) (*models.Task, error) {
+	for {
+		task, err := s.taskRepo.NextQueued(ctx)
+		if err != nil || task == nil {
+			return nil, err
+		}
+
+		notification := taskTransitionNotification(executedTask(task.TaskID, executeRequestFromTask(task), models.TaskStatusInProgress))
+		err = s.taskRepo.UpdateStatus(ctx, task.TaskID, models.TaskStatusInProgress, notification)
+		if err == nil {
+			return task, nil
+		}
+		if !errors.Is(err, apperrors.ErrConflict) {
+			return nil, fmt.Errorf("failed to start task %s: %w", task.TaskID, err)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+func TestTaskService_QueueTask_WakesDispatcher(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, taskRepo, _, _ := newTestTaskService(ctrl)
+
+	taskRepo.EXPECT().GetByID(gomock.Any(), "task-1").Return(&models.Task{TaskID: "task-1", ProjectID: "proj-1", Status: models.TaskStatusPending, Priority: models.TaskPriorityLow}, nil)
+	taskRepo.EXPECT().
+		UpdateStatus(gomock.Any(), "task-1", models.TaskStatusQueued, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ models.TaskStatus, notification *models.Notification) error {
+			require.NotNil(t, notification)
+			assert.Equal(t, models.NotificationEventTaskQueued, notification.Event)
+			return nil
+		})
+
+	err := service.QueueTask(context.Background(), "task-1")
+
+	require.NoError(t, err)
+	assert.Len(t, service.queued, 1)
+}
+
+func TestTaskService_StartNextQueued_SkipsTaskStartedElsewhere(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, taskRepo, _, _ := newTestTaskService(ctrl)
+
+	// Another dispatcher starts task-1 between the read and the update
+	gomock.InOrder(
+		taskRepo.EXPECT().NextQueued(gomock.Any()).Return(&models.Task{TaskID: "task-1", Status: models.TaskStatusQueued}, nil),
+		taskRepo.EXPECT().UpdateStatus(gomock.Any(), "task-1", models.TaskStatusInProgress, gomock.Any()).
+			Return(apperrors.Conflict(apperrors.CodeInvalidTaskTransition, "task task-1 is in_progress and can't move to in_progress")),
+		taskRepo.EXPECT().NextQueued(gomock.Any()).Return(&models.Task{TaskID: "task-2", Status: models.TaskStatusQueued}, nil),
+		taskRepo.EXPECT().UpdateStatus(gomock.Any(), "task-2", models.TaskStatusInProgress, gomock.Any()).Return(nil),
+	)
+
+	task, err := service.startNextQueued(context.Background())
+
+	require.NoError(t, err)
+	require.NotNil(t, task)
+	assert.Equal(t, "task-2", task.TaskID)
+}
+
+func TestTaskService_StartNextQueued_EmptyQueue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, taskRepo, _, _ := newTestTaskService(ctrl)
+
+	taskRepo.EXPECT().NextQueued(gomock.Any()).Return(nil, nil)
+
+	task, err := service.startNextQueued(context.Background())
+
+	require.NoError(t, err)
+	assert.Nil(t, task)
+}
+
+func TestTaskService_GetTask_ReportsQueuePosition(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, taskRepo, _, _ := newTestTaskService(ctrl)
+
+	taskRepo.EXPECT().GetByID(gomock.Any(), "task-1").Return(&models.Task{TaskID: "task-1", Status: models.TaskStatusQueued}, nil)
+	taskRepo.EXPECT().QueuePosition(gomock.Any(), "task-1").Return(3, nil)
+
+	response, err := service.GetTask(context.Background(), "task-1")
+
+	require.NoError(t, err)
+	require.NotNil(t, response.QueuePosition)
+	assert.Equal(t, 3, *response.QueuePosition)
+}
@@ -0,0 +1,405 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/workflow"
+)
+
+// TaskExecutionWorkflowName names the workflow runs executing tasks. Each run is keyed by the ID of its task.
+const TaskExecutionWorkflowName = "task_execution"
+
+// Keys of the executor's name and output on task execution runs
+const (
+	taskOutputExecutorKey  = "executor"
+	taskOutputExecutionKey = "executor_output"
+)
+
+// taskFailure is a step error that fails the task with a message for its owner
+type taskFailure struct {
+	message string
+	err     error
+}
+
+func (f *taskFailure) Error() string { return f.err.Error() }
+
+func (f *taskFailure) Unwrap() error { return f.err }
+
+// taskTimeoutError cancels a task execution that ran past the execution deadline of its type
+type taskTimeoutError struct {
+	timeout time.Duration
+}
+
+func (e *taskTimeoutError) Error() string {
+	return fmt.Sprintf("task exceeded its execution deadline of %s", e.timeout)
+}
+
+func (e *taskTimeoutError) Unwrap() error { return context.DeadlineExceeded }
+
+// taskExecution declares the execution of one task as a workflow
+type taskExecution struct {
+	service *TaskServiceImpl
+	taskID  string
+	req     *models.ExecuteTaskRequest
+
+	// Loaded by the prepare step, which runs again when an interrupted execution resumes
+	task *models.TaskWithFullContext
+}
+
+// definition declares the steps of a task execution
+func (e *taskExecution) definition() workflow.Definition {
+	return workflow.Definition{
+		Name: TaskExecutionWorkflowName,
+		Steps: []workflow.Step{
+			{Name: "prepare", Volatile: true, Run: e.prepare},
+			{Name: "static_analysis", DependsOn: []string{"prepare"}, Run: e.staticAnalysis, Compensate: e.deleteSeededTasks},
+			{Name: "dependency_audit", DependsOn: []string{"prepare"}, Run: e.dependencyAudit, Compensate: e.deleteUpgradeTask},
+			{Name: "coverage_gap", DependsOn: []string{"prepare"}, Run: e.coverageGap},
+			{Name: "execute", DependsOn: []string{"prepare", "coverage_gap"}, Run: e.execute},
+			{Name: "publish_tests", DependsOn: []string{"coverage_gap", "execute"}, Run: e.publishTests},
+			{Name: "complete", DependsOn: []string{"static_analysis", "dependency_audit", "publish_tests"}, Run: e.complete},
+		},
+	}
+}
+
+// prepare loads the task with its context and checks its agent can execute it
+func (e *taskExecution) prepare(ctx context.Context, _ *workflow.Run) error {
+	task, err := e.service.loadTaskWithFullContext(ctx, e.taskID)
+	if err != nil {
+		return &taskFailure{fmt.Sprintf("failed to load context: %v", err), fmt.Errorf("failed to load task context: %w", err)}
+	}
+
+	// Verify the agent exists and is ready (instead of creating it dynamically)
+	if task.Agent == nil {
+		return &taskFailure{"task has no associated agent", fmt.Errorf("task must have an associated agent")}
+	}
+
+	// Check if agent is in ready state
+	if task.Agent.Status != models.AgentStatusReady {
+		return &taskFailure{
+			fmt.Sprintf("agent not ready: %s", task.Agent.Status),
+			fmt.Errorf("agent %s is not ready (status: %s)", task.Agent.AgentID, task.Agent.Status),
+		}
+	}
+
+	// Agents are handed the codebase's content, which a blocking ingestion scan keeps from them. Static analyses,
+	// dependency audits and codemods read the clone locally and still run.
+	if codebase := task.Codebase; codebase != nil && codebase.Status == models.CodebaseStatusIngestionBlocked &&
+		task.Task.AnalysisMode == nil && task.Task.Type != models.TaskTypeDependencyAudit && task.Task.Type != models.TaskTypeCodemod {
+		message := "codebase ingestion is blocked"
+		if codebase.IngestionError != nil {
+			message = *codebase.IngestionError
+		}
+		return &taskFailure{message, apperrors.Conflict(apperrors.CodeIngestionBlocked, "codebase %s is blocked from ingestion: %s", codebase.CodebaseID, message)}
+	}
+
+	e.task = task
+	return nil
+}
+
+// staticAnalysis snapshots the code metrics, reports the findings and the files changed the most and seeds a
+// refactoring task for each of the findings, then evaluates the project's quality gates
+func (e *taskExecution) staticAnalysis(ctx context.Context, run *workflow.Run) error {
+	if e.task.Task.AnalysisMode == nil {
+		return nil
+	}
+
+	analysis, err := e.service.runStaticAnalysis(ctx, e.task)
+	if err != nil {
+		return &taskFailure{fmt.Sprintf("static analysis failed: %v", err), fmt.Errorf("static analysis failed: %w", err)}
+	}
+	if analysis.snapshot != nil {
+		run.Set(models.TaskOutputMetricsKey, analysis.snapshot)
+	}
+	if *e.task.Task.AnalysisMode != models.AnalysisModeMetrics {
+		run.Set(models.TaskOutputFindingsKey, analysis.findings)
+		run.Set(models.TaskOutputSeededTaskIDsKey, analysis.seededTaskIDs)
+	}
+	if analysis.graph != nil {
+		run.Set(models.TaskOutputPackageGraphKey, analysis.graph)
+	}
+	if analysis.history != nil {
+		run.Set(models.TaskOutputFileHistoryKey, analysis.history)
+	}
+	if report := e.service.evaluateQualityGates(ctx, e.task, analysis); report != nil {
+		run.Set(models.TaskOutputQualityGatesKey, report)
+	}
+	return nil
+}
+
+// dependencyAudit stores the audit findings on the codebase and may ask for an upgrade task
+func (e *taskExecution) dependencyAudit(ctx context.Context, run *workflow.Run) error {
+	if e.task.Task.Type != models.TaskTypeDependencyAudit {
+		return nil
+	}
+
+	audit, err := e.service.auditor.AuditCodebase(ctx, e.task)
+	if err != nil {
+		return &taskFailure{fmt.Sprintf("dependency audit failed: %v", err), fmt.Errorf("dependency audit failed: %w", err)}
+	}
+	run.Set("dependency_count", audit.DependencyCount)
+	run.Set(models.TaskOutputFindingsKey, audit.Findings)
+	if audit.UpgradeTaskID != nil {
+		run.Set(models.TaskOutputUpgradeTaskIDKey, *audit.UpgradeTaskID)
+	}
+	return nil
+}
+
+// coverageGap measures the coverage of a coverage_gap task's codebase and keeps the gaps to write tests for
+func (e *taskExecution) coverageGap(ctx context.Context, run *workflow.Run) error {
+	if e.task.Task.Type != models.TaskTypeCoverageGap {
+		return nil
+	}
+
+	report, err := e.service.coverage.FindGaps(ctx, e.task)
+	if err != nil {
+		return &taskFailure{fmt.Sprintf("coverage gap analysis failed: %v", err), fmt.Errorf("coverage gap analysis failed: %w", err)}
+	}
+	run.Set(models.TaskOutputCoverageGapsKey, report)
+	return nil
+}
+
+// execute runs the task on the executor resolved for its type. Static analyses are done by their analyzer instead.
+func (e *taskExecution) execute(ctx context.Context, run *workflow.Run) error {
+	if e.task.Task.AnalysisMode != nil {
+		return nil
+	}
+
+	executor, err := e.service.executors.Resolve(e.task.Task.Type)
+	if err != nil {
+		return &taskFailure{err.Error(), fmt.Errorf("failed to resolve task executor: %w", err)}
+	}
+
+	// Coverage gap tasks describe the gaps to write tests for to the executor
+	task := e.task
+	if task.Task.Type == models.TaskTypeCoverageGap {
+		report, err := runCoverageGaps(run)
+		if err != nil {
+			return err
+		}
+		prompted := *task
+		prompted.Description = coverageGapPrompt(task.Description, report)
+		task = &prompted
+	}
+
+	// The output of a failed execution, such as the trace of its steps, is kept with the failed task
+	output, err := executor.Execute(ctx, task)
+	run.Set(taskOutputExecutorKey, executor.Name())
+	if output != nil {
+		run.Set(taskOutputExecutionKey, output)
+	}
+	if err != nil {
+		return &taskFailure{fmt.Sprintf("execution failed: %v", err), fmt.Errorf("executor %s failed: %w", executor.Name(), err)}
+	}
+	return nil
+}
+
+// publishTests opens a pull request with the tests the executor of a coverage_gap task wrote
+func (e *taskExecution) publishTests(ctx context.Context, run *workflow.Run) error {
+	if e.task.Task.Type != models.TaskTypeCoverageGap {
+		return nil
+	}
+
+	var output map[string]any
+	if _, err := run.Decode(taskOutputExecutionKey, &output); err != nil {
+		return fmt.Errorf("failed to read executor output: %w", err)
+	}
+	diff, _ := output["diff"].(string)
+	if diff == "" {
+		return &taskFailure{"the executor wrote no tests", fmt.Errorf("executor output of coverage_gap task has no diff")}
+	}
+	report, err := runCoverageGaps(run)
+	if err != nil {
+		return err
+	}
+
+	pr, err := e.service.coverage.PublishTests(ctx, e.task, report, diff)
+	if err != nil {
+		return &taskFailure{fmt.Sprintf("failed to open pull request: %v", err), fmt.Errorf("failed to publish tests: %w", err)}
+	}
+	run.Set(models.TaskOutputPullRequestURLKey, pr.URL)
+	run.Set("pull_request_branch", pr.Branch)
+	return nil
+}
+
+// runCoverageGaps reads back the coverage gaps of a run, whether they were set by this process or decoded from the
+// run store
+func runCoverageGaps(run *workflow.Run) (*models.CoverageGapReport, error) {
+	var report models.CoverageGapReport
+	ok, err := run.Decode(models.TaskOutputCoverageGapsKey, &report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read coverage gaps: %w", err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("run has no coverage gaps")
+	}
+	return &report, nil
+}
+
+// complete stores the results of the execution on the task and notifies its owner
+func (e *taskExecution) complete(ctx context.Context, run *workflow.Run) error {
+	agent := e.task.Agent
+	results := map[string]any{
+		"task_id":           e.taskID,
+		"agent_id":          agent.AgentID,
+		"agent_version":     agent.Version,
+		"agent_name":        agent.Name,
+		"ai_provider":       agent.AIProvider,
+		"prompt":            e.req.Description,
+		"task_type":         e.req.Type,
+		"executed_at":       time.Now().Format(time.RFC3339),
+		"knowledge_base_id": agent.KnowledgeBaseID,
+		"vector_store_id":   agent.VectorStoreID,
+	}
+	if e.task.Task.CommitSHA != nil {
+		results["commit_sha"] = *e.task.Task.CommitSHA
+	}
+	if e.task.Task.Branch != nil {
+		results["branch"] = *e.task.Task.Branch
+	}
+	if e.task.Task.AnalysisMode != nil {
+		results["analysis_mode"] = *e.task.Task.AnalysisMode
+	}
+
+	// Output of the executor, which can't replace the fields describing the task
+	var output map[string]any
+	if _, err := run.Decode(taskOutputExecutionKey, &output); err != nil {
+		return fmt.Errorf("failed to read executor output: %w", err)
+	}
+	for key, value := range output {
+		if _, ok := results[key]; !ok {
+			results[key] = value
+		}
+	}
+	if executor := run.String(taskOutputExecutorKey); executor != "" {
+		results[taskOutputExecutorKey] = executor
+	}
+
+	// Outputs of the analysis, audit and coverage steps, which a resumed execution reads back from the run
+	for _, key := range []string{
+		models.TaskOutputMetricsKey,
+		models.TaskOutputFindingsKey,
+		models.TaskOutputQualityGatesKey,
+		models.TaskOutputSeededTaskIDsKey,
+		models.TaskOutputPackageGraphKey,
+		models.TaskOutputFileHistoryKey,
+		"dependency_count",
+		models.TaskOutputUpgradeTaskIDKey,
+		models.TaskOutputCoverageGapsKey,
+		models.TaskOutputPullRequestURLKey,
+		"pull_request_branch",
+	} {
+		if value, ok := run.Values[key]; ok {
+			results[key] = value
+		}
+	}
+
+	// Owners of the files of the findings and the proposed changes, so the results reach the teams owning them
+	if e.task.Task.CodebaseID != nil {
+		if owners := e.service.taskOwners(ctx, *e.task.Task.CodebaseID, results); len(owners) > 0 {
+			results[models.TaskOutputOwnersKey] = owners
+		}
+	}
+
+	completed := executedTask(e.taskID, e.req, models.TaskStatusCompleted)
+	completed.Output = results
+	notification := taskTransitionNotification(completed)
+	if err := e.service.taskRepo.UpdateStatusAndOutput(ctx, e.taskID, models.TaskStatusCompleted, results, nil, notification); err != nil {
+		return fmt.Errorf("failed to update task results: %w", err)
+	}
+	run.Set("results", results)
+	return nil
+}
+
+// deleteSeededTasks removes the refactoring tasks seeded from the findings of an execution that didn't complete
+func (e *taskExecution) deleteSeededTasks(ctx context.Context, run *workflow.Run) error {
+	for _, taskID := range runStrings(run, models.TaskOutputSeededTaskIDsKey) {
+		if err := e.service.taskRepo.Delete(ctx, taskID); err != nil {
+			return fmt.Errorf("failed to delete seeded task %s: %w", taskID, err)
+		}
+	}
+	return nil
+}
+
+// deleteUpgradeTask removes the upgrade task an audit that didn't complete asked for
+func (e *taskExecution) deleteUpgradeTask(ctx context.Context, run *workflow.Run) error {
+	taskID := run.String(models.TaskOutputUpgradeTaskIDKey)
+	if taskID == "" {
+		return nil
+	}
+	if err := e.service.taskRepo.Delete(ctx, taskID); err != nil {
+		return fmt.Errorf("failed to delete upgrade task %s: %w", taskID, err)
+	}
+	return nil
+}
+
+// failedExecutionOutput returns the output the executor of a failed execution left, nil when it left none
+func failedExecutionOutput(run *workflow.Run) map[string]any {
+	if run == nil {
+		return nil
+	}
+	output, ok := run.Get(taskOutputExecutionKey).(map[string]any)
+	if !ok {
+		return nil
+	}
+	failed := map[string]any{taskOutputExecutorKey: run.String(taskOutputExecutorKey)}
+	for key, value := range output {
+		failed[key] = value
+	}
+	return failed
+}
+
+// runStrings returns a stored list of strings, whether it was set by this process or decoded from the run store
+func runStrings(run *workflow.Run, key string) []string {
+	switch values := run.Get(key).(type) {
+	case []string:
+		return values
+	case []any:
+		strs := make([]string, 0, len(values))
+		for _, value := range values {
+			if str, ok := value.(string); ok {
+				strs = append(strs, str)
+			}
+		}
+		return strs
+	default:
+		return nil
+	}
+}
+
+// ResumeInterruptedTasks resumes the task executions a crash interrupted. Executions of tasks that are no longer in
+// progress are rolled back instead.
+func (s *TaskServiceImpl) ResumeInterruptedTasks(ctx context.Context) error {
+	runs, err := s.engine.Interrupted(ctx, TaskExecutionWorkflowName)
+	if err != nil {
+		return err
+	}
+
+	for _, run := range runs {
+		task, err := s.taskRepo.GetByID(ctx, run.RunID)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to get interrupted task", "task_id", run.RunID, "error", err)
+			continue
+		}
+
+		execution := &taskExecution{service: s, taskID: task.TaskID, req: executeRequestFromTask(task)}
+		if task.Status != models.TaskStatusInProgress {
+			if _, err := s.engine.Rollback(ctx, execution.definition(), run.RunID); err != nil {
+				slog.ErrorContext(ctx, "failed to roll back interrupted task execution", "task_id", run.RunID, "error", err)
+			}
+			continue
+		}
+
+		slog.InfoContext(ctx, "resuming interrupted task execution", "task_id", run.RunID)
+		if _, err := s.runTaskExecution(ctx, execution); err != nil {
+			slog.ErrorContext(ctx, "failed to resume interrupted task execution", "task_id", run.RunID, "error", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// TaskExecutor executes tasks of the types it supports, such as with a local LLM, an external service or a
+// deterministic linter. Executors are registered on a TaskExecutorRegistry at startup.
+//
+//go:generate mockgen -destination=./mocks/mock_task_executor.go -mock_names=TaskExecutor=MockTaskExecutor -package=mocks . TaskExecutor
+type TaskExecutor interface {
+	// Name identifies the executor in the configuration and in the results of the tasks it executes
+	Name() string
+
+	// Supports reports whether the executor can execute tasks of taskType
+	Supports(taskType models.TaskType) bool
+
+	// Execute executes a prepared task and returns the output stored with the task's results
+	Execute(ctx context.Context, task *models.TaskWithFullContext) (map[string]any, error)
+}
+
+// TaskExecutorRegistry resolves the executor of each task. A task type routed to an executor by name runs on it, and
+// other types run on the first registered executor supporting them.
+type TaskExecutorRegistry struct {
+	executors []TaskExecutor
+	routes    map[models.TaskType]string
+}
+
+// NewTaskExecutorRegistry creates a registry routing task types to executors by name
+func NewTaskExecutorRegistry(routes map[string]string) *TaskExecutorRegistry {
+	registry := &TaskExecutorRegistry{routes: make(map[models.TaskType]string, len(routes))}
+	for taskType, name := range routes {
+		registry.routes[models.TaskType(taskType)] = name
+	}
+	return registry
+}
+
+// Register adds an executor, rejecting a second executor with the same name
+func (r *TaskExecutorRegistry) Register(executor TaskExecutor) error {
+	if r.executor(executor.Name()) != nil {
+		return fmt.Errorf("task executor %s is already registered", executor.Name())
+	}
+	r.executors = append(r.executors, executor)
+	return nil
+}
+
+// Validate checks every route names a registered executor supporting the routed task type
+func (r *TaskExecutorRegistry) Validate() error {
+	for taskType, name := range r.routes {
+		if _, err := r.Resolve(taskType); err != nil {
+			return fmt.Errorf("invalid route of task type %s to executor %s: %w", taskType, name, err)
+		}
+	}
+	return nil
+}
+
+// Resolve returns the executor of tasks of taskType
+func (r *TaskExecutorRegistry) Resolve(taskType models.TaskType) (TaskExecutor, error) {
+	if name, ok := r.routes[taskType]; ok {
+		executor := r.executor(name)
+		if executor == nil {
+			return nil, fmt.Errorf("task executor %s is not registered", name)
+		}
+		if !executor.Supports(taskType) {
+			return nil, fmt.Errorf("task executor %s does not support %s tasks", name, taskType)
+		}
+		return executor, nil
+	}
+
+	for _, executor := range r.executors {
+		if executor.Supports(taskType) {
+			return executor, nil
+		}
+	}
+	return nil, fmt.Errorf("no task executor supports %s tasks", taskType)
+}
+
+// executor returns the registered executor named name, nil when there's none
+func (r *TaskExecutorRegistry) executor(name string) TaskExecutor {
+	for _, executor := range r.executors {
+		if executor.Name() == name {
+			return executor
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskExecutorRegistry_Resolve(t *testing.T) {
+	registry := NewTaskExecutorRegistry(map[string]string{string(models.TaskTypeCodeReview): "linter"})
+	require.NoError(t, registry.Register(NewAgentTaskExecutor()))
+	require.NoError(t, registry.Register(NewHTTPTaskExecutor("linter", "http://localhost:8081", []models.TaskType{models.TaskTypeCodeReview})))
+	require.NoError(t, registry.Validate())
+
+	// Routed types run on their executor, and other types on the first executor supporting them
+	executor, err := registry.Resolve(models.TaskTypeCodeReview)
+	require.NoError(t, err)
+	assert.Equal(t, "linter", executor.Name())
+
+	executor, err = registry.Resolve(models.TaskTypeRefactoring)
+	require.NoError(t, err)
+	assert.Equal(t, AgentTaskExecutorName, executor.Name())
+}
+
+func TestTaskExecutorRegistry_RejectsDuplicateNames(t *testing.T) {
+	registry := NewTaskExecutorRegistry(nil)
+	require.NoError(t, registry.Register(NewAgentTaskExecutor()))
+
+	err := registry.Register(NewHTTPTaskExecutor(AgentTaskExecutorName, "http://localhost:8081", nil))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already registered")
+}
+
+func TestTaskExecutorRegistry_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		routes  map[string]string
+		wantErr string
+	}{
+		{"unknown executor", map[string]string{"documentation": "local-llm"}, "task executor local-llm is not registered"},
+		{"unsupported type", map[string]string{"documentation": "linter"}, "does not support documentation tasks"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewTaskExecutorRegistry(tt.routes)
+			require.NoError(t, registry.Register(NewHTTPTaskExecutor("linter", "http://localhost:8081", []models.TaskType{models.TaskTypeCodeReview})))
+
+			err := registry.Validate()
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestHTTPTaskExecutor_Execute(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+
+		var task models.TaskWithFullContext
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&task))
+		assert.Equal(t, "task-1", task.TaskID)
+		assert.Equal(t, "agent-1", task.Agent.AgentID)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"summary":"looks good","issues":0}`))
+	}))
+	defer server.Close()
+	executor := NewHTTPTaskExecutor("reviewer", server.URL, []models.TaskType{models.TaskTypeCodeReview})
+
+	output, err := executor.Execute(context.Background(), &models.TaskWithFullContext{
+		Task:  models.Task{TaskID: "task-1", Type: models.TaskTypeCodeReview},
+		Agent: &models.Agent{AgentID: "agent-1"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"summary": "looks good", "issues": float64(0)}, output)
+}
+
+func TestHTTPTaskExecutor_ExecuteFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "model is loading", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	executor := NewHTTPTaskExecutor("local-llm", server.URL, nil)
+
+	_, err := executor.Execute(context.Background(), &models.TaskWithFullContext{Task: models.Task{TaskID: "task-1"}})
+
+	require.Error(t, err)
+	assert.Equal(t, "executor local-llm responded with 503: model is loading", err.Error())
+}
@@ -0,0 +1,18 @@
+package services
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// TaskFeedbackService defines the interface for user feedback on task results
+//
+//go:generate mockgen -destination=./mocks/mock_task_feedback_service.go -mock_names=TaskFeedbackService=MockTaskFeedbackService -package=mocks . TaskFeedbackService
+type TaskFeedbackService interface {
+	// SubmitFeedback records the caller's rating and outcome of a completed task's result, replacing their earlier feedback
+	SubmitFeedback(ctx context.Context, request models.SubmitTaskFeedbackRequest) (*models.TaskFeedback, error)
+
+	// ListFeedback lists the feedback on a task oldest first
+	ListFeedback(ctx context.Context, request models.ListTaskFeedbackRequest) (*models.ListTaskFeedbackResponse, error)
+}
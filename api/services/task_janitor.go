@@ -0,0 +1,206 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// maxStuckTasks bounds the stuck tasks listed or recovered at once
+const maxStuckTasks = 100
+
+// taskHeartbeat records heartbeats of a running task execution with the progress it last reported
+type taskHeartbeat struct {
+	mu       sync.Mutex
+	progress int
+	step     string
+	report   chan struct{}
+	stop     func()
+}
+
+// startHeartbeat records a heartbeat of the task every heartbeat interval, and whenever its execution reports progress,
+// until the heartbeat is stopped, so the janitor can tell a running execution from one whose process died
+func (s *TaskServiceImpl) startHeartbeat(ctx context.Context, taskID string) *taskHeartbeat {
+	heartbeat := &taskHeartbeat{report: make(chan struct{}, 1), stop: func() {}}
+	interval := s.taskConfig.HeartbeatInterval
+	if interval <= 0 {
+		return heartbeat
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			heartbeat.mu.Lock()
+			progress, step := heartbeat.progress, heartbeat.step
+			heartbeat.mu.Unlock()
+			if err := s.taskRepo.Heartbeat(ctx, taskID, progress, step); err != nil && ctx.Err() == nil {
+				slog.WarnContext(ctx, "failed to record task heartbeat", "error", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-heartbeat.report:
+			}
+		}
+	}()
+
+	heartbeat.stop = func() {
+		cancel()
+		<-done
+	}
+	return heartbeat
+}
+
+// reportProgress records the step the execution is about to run and the percentage of its steps completed with the
+// next heartbeat, which is recorded right away
+func (h *taskHeartbeat) reportProgress(_ context.Context, step string, percent int) {
+	h.mu.Lock()
+	h.progress, h.step = percent, step
+	h.mu.Unlock()
+
+	select {
+	case h.report <- struct{}{}:
+	default:
+	}
+}
+
+// heartbeatBefore returns the time since which an in_progress task without a heartbeat is stuck
+func (s *TaskServiceImpl) heartbeatBefore() time.Time {
+	return time.Now().Add(-s.taskConfig.StuckThreshold)
+}
+
+// ListStuckTasks lists the in_progress tasks without a heartbeat for longer than the stuck threshold, longest silent
+// first
+func (s *TaskServiceImpl) ListStuckTasks(ctx context.Context) (*models.ListStuckTasksResponse, error) {
+	heartbeatBefore := s.heartbeatBefore()
+	tasks, err := s.taskRepo.ListStuck(ctx, heartbeatBefore, maxStuckTasks)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ListStuckTasksResponse{Tasks: tasks, HeartbeatBefore: heartbeatBefore}, nil
+}
+
+// RequeueTask queues a stuck task again for the dispatcher, which resumes its execution after the last
+func (s *TaskServiceImpl) RequeueTask(ctx context.Context, taskID string) (*models.RequeueTaskResponse, error) {
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	requeued, err := s.requeueStuckTask(ctx, task, s.heartbeatBefore())
+	if err != nil {
+		return nil, err
+	}
+	if !requeued {
+		return nil, apperrors.Conflict(apperrors.CodeTaskNotStuck, "task %s is %s and not stuck, only stuck tasks can be requeued", taskID, task.Status)
+	}
+
+	return &models.RequeueTaskResponse{TaskID: taskID, Status: models.TaskStatusQueued}, nil
+}
+
+// RecoverStuckTasks fails or requeues the stuck tasks, as configured by the stuck action
+func (s *TaskServiceImpl) RecoverStuckTasks(ctx context.Context) error {
+	heartbeatBefore := s.heartbeatBefore()
+	tasks, err := s.taskRepo.ListStuck(ctx, heartbeatBefore, maxStuckTasks)
+	if err != nil {
+		return err
+	}
+
+	for i := range tasks {
+		task := &tasks[i]
+		var recovered bool
+		if s.taskConfig.StuckAction == config.StuckActionRequeue {
+			recovered, err = s.requeueStuckTask(ctx, task, heartbeatBefore)
+		} else {
+			recovered, err = s.failStuckTask(ctx, task, heartbeatBefore)
+		}
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to recover stuck task", "task_id", task.TaskID, "error", err)
+			continue
+		}
+		if recovered {
+			slog.WarnContext(ctx, "recovered stuck task", "task_id", task.TaskID, "action", s.taskConfig.StuckAction)
+		}
+	}
+
+	return nil
+}
+
+// RunStuckTaskJanitor recovers the stuck tasks every interval until the context is cancelled
+func (s *TaskServiceImpl) RunStuckTaskJanitor(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.RecoverStuckTasks(ctx); err != nil {
+				slog.ErrorContext(ctx, "failed to recover stuck tasks", "error", err)
+			}
+		}
+	}
+}
+
+// failStuckTask fails a stuck task, notifying its owner, and rolls back its execution. It reports false when the task
+// sent a heartbeat or left in_progress in the meantime.
+func (s *TaskServiceImpl) failStuckTask(ctx context.Context, task *models.Task, heartbeatBefore time.Time) (bool, error) {
+	errorMsg := fmt.Sprintf("task execution stopped sending heartbeats for more than %s", s.taskConfig.StuckThreshold)
+	failed := *task
+	failed.Status = models.TaskStatusFailed
+	failed.ErrorMessage = &errorMsg
+
+	recovered, err := s.taskRepo.RecoverStuck(ctx, task.TaskID, heartbeatBefore, models.TaskStatusFailed, &errorMsg, taskTransitionNotification(&failed))
+	if err != nil || !recovered {
+		return recovered, err
+	}
+
+	// Like an interrupted execution of a task that is no longer in progress, its completed steps are compensated
+	execution := &taskExecution{service: s, taskID: task.TaskID, req: executeRequestFromTask(task)}
+	if run, err := s.engine.Run(ctx, task.TaskID); err != nil {
+		return true, err
+	} else if run != nil {
+		if _, err := s.engine.Rollback(ctx, execution.definition(), task.TaskID); err != nil {
+			return true, fmt.Errorf("failed to roll back stuck task execution: %w", err)
+		}
+	}
+	return true, nil
+}
+
+// requeueStuckTask queues a stuck task again for the dispatcher to run. Its execution is marked
+// interrupted first, so the new execution resumes after the last completed step instead of being rolled back as an
+// interrupted execution of a task that is no longer in progress. It reports false when the task sent a heartbeat or
+// left in_progress in the meantime.
+func (s *TaskServiceImpl) requeueStuckTask(ctx context.Context, task *models.Task, heartbeatBefore time.Time) (bool, error) {
+	queued := *task
+	queued.Status = models.TaskStatusQueued
+	recovered, err := s.taskRepo.RecoverStuck(ctx, task.TaskID, heartbeatBefore, models.TaskStatusQueued, nil, taskTransitionNotification(&queued))
+	if err != nil || !recovered {
+		return recovered, err
+	}
+
+	if run, err := s.engine.Run(ctx, task.TaskID); err != nil {
+		return true, err
+	} else if run != nil {
+		if _, err := s.engine.MarkInterrupted(ctx, task.TaskID); err != nil {
+			return true, err
+		}
+	}
+
+	s.wakeDispatcher()
+	return true, nil
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/workflow"
+)
+
+// saveSeededRun stores the execution of task-1 as interrupted after its static analysis seeded task-2
+func saveSeededRun(t *testing.T, store workflow.RunStore) {
+	t.Helper()
+	require.NoError(t, store.SaveRun(context.Background(), &workflow.Run{
+		RunID:    "task-1",
+		Workflow: TaskExecutionWorkflowName,
+		Status:   workflow.RunStatusRunning,
+		Steps: []workflow.StepProgress{
+			{Name: "prepare", Status: workflow.StepStatusCompleted, Attempts: 1},
+			{Name: "static_analysis", Status: workflow.StepStatusCompleted, Attempts: 1},
+			{Name: "dependency_audit", Status: workflow.StepStatusCompleted, Attempts: 1},
+			{Name: "complete", Status: workflow.StepStatusPending},
+		},
+		Values: map[string]any{models.TaskOutputSeededTaskIDsKey: []any{"task-2"}},
+	}))
+}
+
+func TestTaskService_RecoverStuckTasks_FailsAndRollsBack(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, taskRepo, _, _ := newTestTaskService(ctrl)
+	service.taskConfig = config.TaskConfig{StuckThreshold: 5 * time.Minute, StuckAction: config.StuckActionFail}
+	store := workflow.NewMemoryRunStore()
+	service.engine = workflow.NewEngine(store, 0)
+	saveSeededRun(t, store)
+
+	stuck := models.Task{TaskID: "task-1", ProjectID: "proj-1", Title: "Find duplicates", Status: models.TaskStatusInProgress}
+	taskRepo.EXPECT().ListStuck(gomock.Any(), gomock.Any(), maxStuckTasks).Return([]models.Task{stuck}, nil)
+	taskRepo.EXPECT().
+		RecoverStuck(gomock.Any(), "task-1", gomock.Any(), models.TaskStatusFailed, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, heartbeatBefore time.Time, _ models.TaskStatus, errorMessage *string, notification *models.Notification) (bool, error) {
+			assert.WithinDuration(t, time.Now().Add(-5*time.Minute), heartbeatBefore, time.Second)
+			require.NotNil(t, errorMessage)
+			assert.Contains(t, *errorMessage, "heartbeats")
+			require.NotNil(t, notification)
+			assert.Equal(t, models.NotificationEventTaskFailed, notification.Event)
+			return true, nil
+		})
+	// The tasks the interrupted execution seeded are compensated
+	taskRepo.EXPECT().Delete(gomock.Any(), "task-2").Return(nil)
+
+	require.NoError(t, service.RecoverStuckTasks(context.Background()))
+
+	run, err := store.GetRun(context.Background(), "task-1")
+	require.NoError(t, err)
+	assert.Equal(t, workflow.RunStatusRolledBack, run.Status)
+}
+
+func TestTaskService_RecoverStuckTasks_SkipsTasksThatResumedHeartbeats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, taskRepo, _, _ := newTestTaskService(ctrl)
+	service.taskConfig = config.TaskConfig{StuckThreshold: 5 * time.Minute, StuckAction: config.StuckActionFail}
+	store := workflow.NewMemoryRunStore()
+	service.engine = workflow.NewEngine(store, 0)
+	saveSeededRun(t, store)
+
+	taskRepo.EXPECT().ListStuck(gomock.Any(), gomock.Any(), maxStuckTasks).Return([]models.Task{{TaskID: "task-1", Status: models.TaskStatusInProgress}}, nil)
+	taskRepo.EXPECT().RecoverStuck(gomock.Any(), "task-1", gomock.Any(), models.TaskStatusFailed, gomock.Any(), gomock.Any()).Return(false, nil)
+
+	require.NoError(t, service.RecoverStuckTasks(context.Background()))
+
+	run, err := store.GetRun(context.Background(), "task-1")
+	require.NoError(t, err)
+	assert.Equal(t, workflow.RunStatusRunning, run.Status)
+}
+
+func TestTaskService_RequeueTask_ResumesExecution(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, taskRepo, projectRepo, agentRepo := newTestTaskService(ctrl)
+	codebaseRepo := service.codebaseRepo.(*repositoryMocks.MockCodebaseRepository)
+	service.taskConfig = config.TaskConfig{StuckThreshold: 5 * time.Minute}
+	store := workflow.NewMemoryRunStore()
+	service.engine = workflow.NewEngine(store, 0)
+	saveSeededRun(t, store)
+
+	codebaseID := "cb-1"
+	mode := models.AnalysisModeDuplicateCode
+	task := models.Task{
+		TaskID: "task-1", ProjectID: "proj-1", AgentID: "agent-1", CodebaseID: &codebaseID,
+		Type: models.TaskTypeCodeAnalysis, AnalysisMode: &mode, Status: models.TaskStatusInProgress,
+		Title: "Find duplicates", Description: "Find duplicated code",
+	}
+	queued := task
+	queued.Status = models.TaskStatusQueued
+
+	// The task is read as stuck, then as queued by the requeued execution the dispatcher starts
+	var reads atomic.Int32
+	taskRepo.EXPECT().GetByID(gomock.Any(), "task-1").DoAndReturn(func(context.Context, string) (*models.Task, error) {
+		if reads.Add(1) == 1 {
+			return &task, nil
+		}
+		return &queued, nil
+	}).Times(2)
+	taskRepo.EXPECT().RecoverStuck(gomock.Any(), "task-1", gomock.Any(), models.TaskStatusQueued, nil, gomock.Any()).Return(true, nil)
+	var dispatched atomic.Bool
+	taskRepo.EXPECT().NextQueued(gomock.Any()).DoAndReturn(func(context.Context) (*models.Task, error) {
+		if dispatched.CompareAndSwap(false, true) {
+			return &queued, nil
+		}
+		return nil, nil
+	}).AnyTimes()
+	taskRepo.EXPECT().UpdateStatus(gomock.Any(), "task-1", models.TaskStatusInProgress, gomock.Any()).Return(nil)
+	projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{ProjectID: "proj-1"}, nil)
+	agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{AgentID: "agent-1", Status: string(models.AgentStatusReady)}, nil)
+	codebaseRepo.EXPECT().GetCodebase(gomock.Any(), codebaseID).Return(&models.Codebase{CodebaseID: codebaseID}, nil)
+
+	// The analysis that completed before the task got stuck isn't rerun
+	completed := make(chan map[string]any, 1)
+	taskRepo.EXPECT().
+		UpdateStatusAndOutput(gomock.Any(), "task-1", models.TaskStatusCompleted, gomock.Any(), nil, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ models.TaskStatus, output map[string]any, _ *string, _ *models.Notification) error {
+			completed <- output
+			return nil
+		})
+
+	response, err := service.RequeueTask(context.Background(), "task-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, models.TaskStatusQueued, response.Status)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go service.RunTaskDispatcher(ctx, time.Hour, 1)
+
+	select {
+	case output := <-completed:
+		assert.Equal(t, []any{"task-2"}, output[models.TaskOutputSeededTaskIDsKey])
+	case <-time.After(5 * time.Second):
+		t.Fatal("requeued task did not run")
+	}
+}
+
+func TestTaskService_RequeueTask_NotStuck(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, taskRepo, _, _ := newTestTaskService(ctrl)
+	service.taskConfig = config.TaskConfig{StuckThreshold: 5 * time.Minute}
+
+	taskRepo.EXPECT().GetByID(gomock.Any(), "task-1").Return(&models.Task{TaskID: "task-1", Status: models.TaskStatusCompleted}, nil)
+	taskRepo.EXPECT().RecoverStuck(gomock.Any(), "task-1", gomock.Any(), models.TaskStatusQueued, nil, gomock.Any()).Return(false, nil)
+
+	_, err := service.RequeueTask(context.Background(), "task-1")
+
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+}
+
+func TestTaskService_StartHeartbeat_ReportsProgress(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, taskRepo, _, _ := newTestTaskService(ctrl)
+	service.taskConfig = config.TaskConfig{HeartbeatInterval: time.Hour}
+
+	beats := make(chan string, 10)
+	taskRepo.EXPECT().Heartbeat(gomock.Any(), "task-1", gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, _ string, progress int, step string) error {
+		beats <- fmt.Sprintf("%s %d", step, progress)
+		return nil
+	}).Times(2)
+
+	heartbeat := service.startHeartbeat(context.Background(), "task-1")
+	assert.Equal(t, " 0", <-beats)
+
+	// Progress is recorded right away rather than with the next periodic heartbeat
+	heartbeat.reportProgress(context.Background(), "static_analysis", 25)
+	assert.Equal(t, "static_analysis 25", <-beats)
+	heartbeat.stop()
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/jsonschema"
+)
+
+// taskResultKey keys the validated answer of an agent in the output of a task, next to the raw answer
+const taskResultKey = "result"
+
+// TaskOutputSchema returns the JSON schema the answers of agents to tasks of taskType must match, nil for task types
+// that aren't answered by an agent
+func TaskOutputSchema(taskType models.TaskType) *jsonschema.Schema {
+	switch taskType {
+	case models.TaskTypeCodeAnalysis:
+		return answerSchema("findings", "Problems found in the code", objectOf(map[string]*jsonschema.Schema{
+			"file":     stringOf("Path of the file, relative to the repository root"),
+			"line":     {Type: "integer", Description: "Line of the problem, from 1"},
+			"severity": severitySchema(),
+			"message":  stringOf("What the problem is and why it matters"),
+		}, "file", "severity", "message"))
+	case models.TaskTypeCodeReview:
+		return answerSchema("comments", "Review comments on the code", objectOf(map[string]*jsonschema.Schema{
+			"file":     stringOf("Path of the file, relative to the repository root"),
+			"line":     {Type: "integer", Description: "Line the comment is about, from 1"},
+			"severity": severitySchema(),
+			"message":  stringOf("The comment"),
+		}, "file", "severity", "message"))
+	case models.TaskTypeRefactoring:
+		return answerSchema("changes", "Changes made to the code", objectOf(map[string]*jsonschema.Schema{
+			"file":        stringOf("Path of the changed file, relative to the repository root"),
+			"description": stringOf("What was changed and why"),
+		}, "file", "description"))
+	case models.TaskTypeDocumentation:
+		return answerSchema("documents", "Documentation written or updated", objectOf(map[string]*jsonschema.Schema{
+			"file":        stringOf("Path of the document, relative to the repository root"),
+			"description": stringOf("What the document covers"),
+		}, "file", "description"))
+	case models.TaskTypeCoverageGap:
+		return answerSchema("tests", "Unit tests written", objectOf(map[string]*jsonschema.Schema{
+			"file":        stringOf("Path of the test file, relative to the repository root"),
+			"function":    stringOf("Function the tests cover"),
+			"description": stringOf("What the tests check"),
+		}, "file", "function", "description"))
+	case models.TaskTypeCustom:
+		return &jsonschema.Schema{
+			Type:       "object",
+			Properties: map[string]*jsonschema.Schema{"summary": stringOf("Summary of the outcome of the task")},
+			Required:   []string{"summary"},
+		}
+	default:
+		return nil
+	}
+}
+
+// ValidateTaskAnswer parses an agent's answer to a task as JSON, tolerating a surrounding Markdown code fence, and
+// validates it against the schema of the task's type
+func ValidateTaskAnswer(schema *jsonschema.Schema, answer string) (any, error) {
+	return schema.ValidateJSON([]byte(stripCodeFence(answer)))
+}
+
+// stripCodeFence removes the ```json fence models like to wrap JSON in
+func stripCodeFence(answer string) string {
+	answer = strings.TrimSpace(answer)
+	if !strings.HasPrefix(answer, "```") {
+		return answer
+	}
+	answer = strings.TrimPrefix(answer, "```")
+	if newline := strings.IndexByte(answer, '\n'); newline >= 0 {
+		answer = answer[newline+1:]
+	}
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(answer), "```"))
+}
+
+// answerSchema is an object with a summary and a list of items
+func answerSchema(list, description string, item *jsonschema.Schema) *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"summary": stringOf("Summary of the outcome of the task"),
+			list:      {Type: "array", Description: description, Items: item},
+		},
+		Required: []string{"summary", list},
+	}
+}
+
+func objectOf(properties map[string]*jsonschema.Schema, required ...string) *jsonschema.Schema {
+	return &jsonschema.Schema{Type: "object", Properties: properties, Required: required}
+}
+
+func stringOf(description string) *jsonschema.Schema {
+	return &jsonschema.Schema{Type: "string", Description: description}
+}
+
+func severitySchema() *jsonschema.Schema {
+	return &jsonschema.Schema{Type: "string", Enum: []any{"info", "warning", "error"}}
+}
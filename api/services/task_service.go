@@ -3,6 +3,7 @@ package services
 
 import (
 	"context"
+	"time"
 
 	"github.com/kazemisoroush/code-refactoring-tool/api/models"
 )
@@ -26,8 +27,43 @@ type TaskService interface {
 	// ListTasks lists tasks for a project with optional filters
 	ListTasks(ctx context.Context, req *models.ListTasksRequest) (*models.ListTasksResponse, error)
 
-	// ExecuteTask executes a task immediately (sync or async)
+	// ExportTasks passes every task of a project matching the filters of req to fn, newest first, ignoring pagination
+	ExportTasks(ctx context.Context, req *models.ListTasksRequest, fn func(models.Task) error) error
+
+	// ExecuteTask executes a task immediately, or queues it for the dispatcher when it's async
 	ExecuteTask(ctx context.Context, req *models.ExecuteTaskRequest) (*models.ExecuteTaskResponse, error)
+
+	// RunTask synchronously executes a previously created pending or queued task
+	RunTask(ctx context.Context, taskID string) (*models.ExecuteTaskResponse, error)
+
+	// QueueTask queues a previously created pending task for the dispatcher to run
+	QueueTask(ctx context.Context, taskID string) error
+
+	// RunTaskDispatcher runs the queued tasks, by priority and fairly across projects, on at most workers executions at
+	// a time, looking for queued tasks every interval until the context is cancelled
+	RunTaskDispatcher(ctx context.Context, interval time.Duration, workers int)
+
+	// ResumeInterruptedTasks resumes the task executions a crash interrupted
+	ResumeInterruptedTasks(ctx context.Context) error
+
+	// ListStuckTasks lists the in_progress tasks whose executions stopped sending heartbeats
+	ListStuckTasks(ctx context.Context) (*models.ListStuckTasksResponse, error)
+
+	// RequeueTask queues a stuck task again for the dispatcher to run
+	RequeueTask(ctx context.Context, taskID string) (*models.RequeueTaskResponse, error)
+
+	// RunStuckTaskJanitor fails or requeues the stuck tasks every interval until the context is cancelled
+	RunStuckTaskJanitor(ctx context.Context, interval time.Duration)
+
+	// CreateTaskBatch validates and creates several tasks atomically, provided their creator can create tasks in their
+	// projects
+	CreateTaskBatch(ctx context.Context, req *models.CreateTaskBatchRequest) (*models.CreateTaskBatchResponse, error)
+
+	// GetTaskBatch retrieves the aggregate progress of a batch of tasks for the user with the auth provider ID
+	GetTaskBatch(ctx context.Context, authID, batchID string) (*models.GetTaskBatchResponse, error)
+
+	// AuthorizeTask checks that the user with the auth provider ID holds a permission within the project of a task
+	AuthorizeTask(ctx context.Context, authID, taskID string, permission models.Permission) error
 }
 
 // NOTE: TaskServiceImpl provides full AI factory integration for task execution
@@ -3,21 +3,52 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
 	"github.com/kazemisoroush/code-refactoring-tool/api/models"
 	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/analyzer"
+	analyzermodels "github.com/kazemisoroush/code-refactoring-tool/pkg/analyzer/models"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/codemod"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/logging"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/workflow"
 )
 
-// TaskServiceImpl implements TaskService with dynamic AI capabilities
+// TaskServiceImpl implements TaskService with dynamic AI capabilities. The dependency audits, code metrics, findings,
+// quality gates, code owners, file histories and Jira issue links are optional: when their service is nil, such as
+// with a storage backend that doesn't store them, tasks needing them are refused and the others run without them.
 type TaskServiceImpl struct {
 	taskRepo     repository.TaskRepository
 	projectRepo  repository.ProjectRepository
 	agentRepo    repository.AgentRepository
 	codebaseRepo repository.CodebaseRepository
+	roleService  RoleService
+	browser      CodebaseBrowseService
+	cloner       CodebaseCloner
+	analyzers    map[models.AnalysisMode]analyzer.Analyzer
+	auditor      DependencyAuditService
+	coverage     CoverageGapService
+	metrics      CodeMetricsService
+	findings     FindingService
+	gates        QualityGateService
+	owners       CodeOwnersService
+	history      FileHistoryService
+	jira         JiraService
+	uploads      UploadService
+	executors    *TaskExecutorRegistry
+	engine       *workflow.Engine
+	taskConfig   config.TaskConfig
+
+	// queued wakes the dispatcher up when a task is queued
+	queued chan struct{}
 }
 
 // NewTaskService creates a new task service with dependency injection
@@ -26,12 +57,45 @@ func NewTaskService(
 	projectRepo repository.ProjectRepository,
 	agentRepo repository.AgentRepository,
 	codebaseRepo repository.CodebaseRepository,
+	roleService RoleService,
+	browser CodebaseBrowseService,
+	cloner CodebaseCloner,
+	analyzers map[models.AnalysisMode]analyzer.Analyzer,
+	auditor DependencyAuditService,
+	coverage CoverageGapService,
+	metrics CodeMetricsService,
+	findings FindingService,
+	gates QualityGateService,
+	owners CodeOwnersService,
+	history FileHistoryService,
+	jira JiraService,
+	uploads UploadService,
+	executors *TaskExecutorRegistry,
+	engine *workflow.Engine,
+	taskConfig config.TaskConfig,
 ) TaskService {
 	return &TaskServiceImpl{
 		taskRepo:     taskRepo,
 		projectRepo:  projectRepo,
 		agentRepo:    agentRepo,
 		codebaseRepo: codebaseRepo,
+		roleService:  roleService,
+		browser:      browser,
+		cloner:       cloner,
+		analyzers:    analyzers,
+		auditor:      auditor,
+		coverage:     coverage,
+		metrics:      metrics,
+		findings:     findings,
+		gates:        gates,
+		owners:       owners,
+		history:      history,
+		jira:         jira,
+		uploads:      uploads,
+		executors:    executors,
+		engine:       engine,
+		taskConfig:   taskConfig,
+		queued:       make(chan struct{}, 1),
 	}
 }
 
@@ -41,39 +105,43 @@ func (s *TaskServiceImpl) CreateTask(ctx context.Context, req *models.CreateTask
 	if err := s.validateResources(ctx, req.ProjectID, req.AgentID, req.CodebaseID); err != nil {
 		return nil, fmt.Errorf("resource validation failed: %w", err)
 	}
-
-	// Generate task ID
-	taskID := uuid.New().String()
-
-	// Create execution context - simplified for now
-	executionContext := &models.TaskExecutionContext{
-		AgentVersion: "latest",
-		AIProvider:   "bedrock", // Will be determined from agent config
+	if err := validateCodebaseScan(req); err != nil {
+		return nil, err
 	}
-
-	// Create task model
-	task := &models.Task{
-		TaskID:           taskID,
-		ProjectID:        req.ProjectID,
-		AgentID:          req.AgentID,
-		CodebaseID:       req.CodebaseID,
-		Type:             req.Type,
-		Status:           models.TaskStatusPending,
-		Title:            req.Title,
-		Description:      req.Description,
-		Input:            req.Input,
-		ExecutionContext: *executionContext,
-		Metadata:         req.Metadata,
-		Tags:             req.Tags,
-		CreatedAt:        time.Now(),
-		UpdatedAt:        time.Now(),
+	if err := validateTaskModel(req); err != nil {
+		return nil, err
+	}
+	if err := s.validateTaskFeatures(req); err != nil {
+		return nil, err
+	}
+	if err := s.pinRevision(ctx, req); err != nil {
+		return nil, fmt.Errorf("revision validation failed: %w", err)
+	}
+	if err := s.resolveUpload(ctx, req); err != nil {
+		return nil, fmt.Errorf("input validation failed: %w", err)
+	}
+	issue, err := s.resolveIssue(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("issue validation failed: %w", err)
 	}
 
+	task := newTaskFromRequest(req)
+
 	// Save task to repository
 	if err := s.taskRepo.Create(ctx, task); err != nil {
 		return nil, fmt.Errorf("failed to create task: %w", err)
 	}
 
+	// A task is only created along with the link to its issue
+	if issue != nil {
+		if err := s.jira.LinkIssue(ctx, task.TaskID, issue); err != nil {
+			if deleteErr := s.taskRepo.Delete(ctx, task.TaskID); deleteErr != nil {
+				slog.WarnContext(ctx, "failed to delete unlinked task", "task_id", task.TaskID, "error", deleteErr)
+			}
+			return nil, fmt.Errorf("failed to link task to its issue: %w", err)
+		}
+	}
+
 	return &models.CreateTaskResponse{
 		TaskID:    task.TaskID,
 		Status:    task.Status,
@@ -81,6 +149,168 @@ func (s *TaskServiceImpl) CreateTask(ctx context.Context, req *models.CreateTask
 	}, nil
 }
 
+// resolveIssue resolves the Jira issue a task is created for, nil when it names none
+func (s *TaskServiceImpl) resolveIssue(ctx context.Context, req *models.CreateTaskRequest) (*models.JiraIssueLink, error) {
+	if req.IssueKey == "" {
+		return nil, nil
+	}
+
+	return s.jira.ResolveIssue(ctx, req.IssueKey)
+}
+
+// attachIssues sets the Jira issue each task is linked to, leaving them unset when Jira issue links are disabled
+func (s *TaskServiceImpl) attachIssues(ctx context.Context, tasks []models.Task) error {
+	if s.jira == nil {
+		return nil
+	}
+	return s.jira.AttachIssues(ctx, tasks)
+}
+
+// CreateTaskBatch validates every task spec up front and creates all of them in a single transaction.
+// If any spec fails validation, nothing is created and the per-item errors are returned.
+func (s *TaskServiceImpl) CreateTaskBatch(ctx context.Context, req *models.CreateTaskBatchRequest) (*models.CreateTaskBatchResponse, error) {
+	if len(req.Tasks) == 0 {
+		return nil, fmt.Errorf("batch must contain at least one task")
+	}
+	if len(req.Tasks) > models.MaxTaskBatchSize {
+		return nil, fmt.Errorf("batch contains %d tasks, maximum is %d", len(req.Tasks), models.MaxTaskBatchSize)
+	}
+
+	// The batch route isn't project-scoped, so the creator must be allowed to create tasks in every project of the batch
+	authorized := make(map[string]bool)
+	for i := range req.Tasks {
+		spec := &req.Tasks[i]
+		if authorized[spec.ProjectID] {
+			continue
+		}
+		if err := s.roleService.Authorize(ctx, spec.CreatedBy, spec.ProjectID, models.PermissionTaskCreate); err != nil {
+			return nil, err
+		}
+		authorized[spec.ProjectID] = true
+	}
+
+	response := &models.CreateTaskBatchResponse{
+		Results:   make([]models.TaskBatchItemResult, len(req.Tasks)),
+		CreatedAt: time.Now(),
+	}
+
+	// Validate all specs before creating anything
+	valid := true
+	issues := make([]*models.JiraIssueLink, len(req.Tasks))
+	for i := range req.Tasks {
+		spec := &req.Tasks[i]
+		response.Results[i].Index = i
+		err := s.validateResources(ctx, spec.ProjectID, spec.AgentID, spec.CodebaseID)
+		if err == nil {
+			err = validateCodebaseScan(spec)
+		}
+		if err == nil {
+			err = validateTaskModel(spec)
+		}
+		if err == nil {
+			err = s.validateTaskFeatures(spec)
+		}
+		if err == nil {
+			err = s.pinRevision(ctx, spec)
+		}
+		if err == nil {
+			err = s.resolveUpload(ctx, spec)
+		}
+		if err == nil {
+			issues[i], err = s.resolveIssue(ctx, spec)
+		}
+		if err != nil {
+			errMsg := err.Error()
+			response.Results[i].Error = &errMsg
+			valid = false
+		}
+	}
+	if !valid {
+		return response, nil
+	}
+
+	batchID := "batch-" + uuid.New().String()
+	tasks := make([]*models.Task, len(req.Tasks))
+	for i := range req.Tasks {
+		tasks[i] = newTaskFromRequest(&req.Tasks[i])
+		tasks[i].BatchID = &batchID
+	}
+
+	if err := s.taskRepo.CreateBatch(ctx, tasks); err != nil {
+		return nil, fmt.Errorf("failed to create task batch: %w", err)
+	}
+
+	response.BatchID = batchID
+	response.Created = true
+	for i, task := range tasks {
+		response.Results[i].TaskID = task.TaskID
+		response.Results[i].Status = task.Status
+
+		// The batch is already created, a task failing to link keeps running without updating its issue
+		if issues[i] != nil {
+			if err := s.jira.LinkIssue(ctx, task.TaskID, issues[i]); err != nil {
+				slog.WarnContext(ctx, "failed to link task to its issue", "task_id", task.TaskID, "issue_key", issues[i].IssueKey, "error", err)
+			}
+		}
+	}
+
+	return response, nil
+}
+
+// GetTaskBatch aggregates the status of all tasks in a batch, provided the user can read the tasks of every project
+// of the batch
+func (s *TaskServiceImpl) GetTaskBatch(ctx context.Context, authID, batchID string) (*models.GetTaskBatchResponse, error) {
+	tasks, err := s.taskRepo.ListByBatch(ctx, batchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list batch tasks: %w", err)
+	}
+	if len(tasks) == 0 {
+		return nil, apperrors.NotFound(apperrors.CodeBatchNotFound, "batch not found: %s", batchID)
+	}
+
+	authorized := make(map[string]bool)
+	for _, task := range tasks {
+		if authorized[task.ProjectID] {
+			continue
+		}
+		if err := s.roleService.Authorize(ctx, authID, task.ProjectID, models.PermissionTaskRead); err != nil {
+			return nil, err
+		}
+		authorized[task.ProjectID] = true
+	}
+
+	response := &models.GetTaskBatchResponse{
+		BatchID:      batchID,
+		TotalCount:   len(tasks),
+		StatusCounts: make(map[models.TaskStatus]int),
+		TaskIDs:      make([]string, 0, len(tasks)),
+	}
+
+	terminal := 0
+	for _, task := range tasks {
+		response.StatusCounts[task.Status]++
+		response.TaskIDs = append(response.TaskIDs, task.TaskID)
+		if task.Status.IsTerminal() {
+			terminal++
+		}
+	}
+
+	response.Progress = float64(terminal) * 100 / float64(len(tasks))
+	response.Done = terminal == len(tasks)
+
+	return response, nil
+}
+
+// AuthorizeTask checks that the user with the auth provider ID holds a permission within the project of a task
+func (s *TaskServiceImpl) AuthorizeTask(ctx context.Context, authID, taskID string, permission models.Permission) error {
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return fmt.Errorf("failed to get task: %w", err)
+	}
+
+	return s.roleService.Authorize(ctx, authID, task.ProjectID, permission)
+}
+
 // GetTask retrieves a task by ID with optional context loading
 func (s *TaskServiceImpl) GetTask(ctx context.Context, taskID string) (*models.GetTaskResponse, error) {
 	task, err := s.taskRepo.GetByID(ctx, taskID)
@@ -88,8 +318,23 @@ func (s *TaskServiceImpl) GetTask(ctx context.Context, taskID string) (*models.G
 		return nil, fmt.Errorf("failed to get task: %w", err)
 	}
 
+	if task.Status == models.TaskStatusQueued {
+		position, err := s.taskRepo.QueuePosition(ctx, taskID)
+		if err != nil {
+			return nil, err
+		}
+		if position > 0 {
+			task.QueuePosition = &position
+		}
+	}
+
+	tasks := []models.Task{*task}
+	if err := s.attachIssues(ctx, tasks); err != nil {
+		return nil, fmt.Errorf("failed to get task issue: %w", err)
+	}
+
 	return &models.GetTaskResponse{
-		Task: *task,
+		Task: tasks[0],
 	}, nil
 }
 
@@ -101,8 +346,13 @@ func (s *TaskServiceImpl) UpdateTask(ctx context.Context, req *models.UpdateTask
 		return nil, fmt.Errorf("failed to get task: %w", err)
 	}
 
-	// Update fields if provided
-	if req.Status != nil {
+	previousStatus := task.Status
+
+	// Update fields if provided, moving the task only along the transitions of its status
+	if req.Status != nil && *req.Status != task.Status {
+		if !task.Status.CanTransitionTo(*req.Status) {
+			return nil, apperrors.Conflict(apperrors.CodeInvalidTaskTransition, "task %s is %s and can't move to %s", req.TaskID, task.Status, *req.Status)
+		}
 		task.Status = *req.Status
 	}
 	if req.Output != nil {
@@ -114,11 +364,22 @@ func (s *TaskServiceImpl) UpdateTask(ctx context.Context, req *models.UpdateTask
 	if req.Metadata != nil {
 		task.Metadata = req.Metadata
 	}
+	if req.Approved != nil {
+		// Only a result can be approved, and experiments compare the approval rates of their arms
+		if task.Status != models.TaskStatusCompleted {
+			return nil, apperrors.Conflict(apperrors.CodeTaskNotCompleted, "task %s is %s, only completed tasks can be approved", req.TaskID, task.Status)
+		}
+		task.Approved = req.Approved
+	}
 
 	task.UpdatedAt = time.Now()
 
-	// Save updated task
-	if err := s.taskRepo.Update(ctx, task); err != nil {
+	// Save updated task, notifying the transition if the update moved it
+	var notification *models.Notification
+	if task.Status != previousStatus {
+		notification = taskTransitionNotification(task)
+	}
+	if err := s.taskRepo.Update(ctx, task, notification); err != nil {
 		return nil, fmt.Errorf("failed to update task: %w", err)
 	}
 
@@ -150,14 +411,20 @@ func (s *TaskServiceImpl) ListTasks(ctx context.Context, req *models.ListTasksRe
 		Status:  req.Status,
 		Type:    req.Type,
 		AgentID: req.AgentID,
+		Owner:   req.Owner,
+		Tags:    req.TagFilter,
 		Limit:   limit,
 		Offset:  offset,
 	}
 
-	tasks, total, err := s.taskRepo.ListByProject(ctx, req.ProjectID, filters)
+	// Listings tolerate the lag of the read replica
+	tasks, total, err := s.taskRepo.ListByProject(repository.WithReadOnly(ctx), req.ProjectID, filters)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list tasks: %w", err)
 	}
+	if err := s.attachIssues(ctx, tasks); err != nil {
+		return nil, fmt.Errorf("failed to get task issues: %w", err)
+	}
 
 	return &models.ListTasksResponse{
 		Tasks:      tasks,
@@ -167,17 +434,39 @@ func (s *TaskServiceImpl) ListTasks(ctx context.Context, req *models.ListTasksRe
 	}, nil
 }
 
+// ExportTasks streams the tasks of a project matching the filters of req to fn
+func (s *TaskServiceImpl) ExportTasks(ctx context.Context, req *models.ListTasksRequest, fn func(models.Task) error) error {
+	filters := repository.TaskFilters{
+		Status:  req.Status,
+		Type:    req.Type,
+		AgentID: req.AgentID,
+		Owner:   req.Owner,
+		Tags:    req.TagFilter,
+	}
+
+	if err := s.taskRepo.StreamByProject(repository.WithReadOnly(ctx), req.ProjectID, filters, fn); err != nil {
+		return fmt.Errorf("failed to export tasks: %w", err)
+	}
+
+	return nil
+}
+
 // ExecuteTask executes a task with dynamic AI resource allocation
 func (s *TaskServiceImpl) ExecuteTask(ctx context.Context, req *models.ExecuteTaskRequest) (*models.ExecuteTaskResponse, error) {
 	// First, create the task
 	createReq := &models.CreateTaskRequest{
-		ProjectID:   req.ProjectID,
-		AgentID:     req.AgentID,
-		CodebaseID:  req.CodebaseID,
-		Type:        req.Type,
-		Title:       req.Title,
-		Description: req.Description,
-		Input:       req.Input,
+		ProjectID:    req.ProjectID,
+		AgentID:      req.AgentID,
+		CodebaseID:   req.CodebaseID,
+		Branch:       req.Branch,
+		CommitSHA:    req.CommitSHA,
+		Type:         req.Type,
+		AnalysisMode: req.AnalysisMode,
+		Priority:     req.Priority,
+		Title:        req.Title,
+		Description:  req.Description,
+		Input:        req.Input,
+		CreatedBy:    req.CreatedBy,
 	}
 
 	createResp, err := s.CreateTask(ctx, createReq)
@@ -185,11 +474,14 @@ func (s *TaskServiceImpl) ExecuteTask(ctx context.Context, req *models.ExecuteTa
 		return nil, fmt.Errorf("failed to create task: %w", err)
 	}
 
-	// If async requested, return immediately
+	// If async requested, queue the task for the dispatcher and return immediately
 	if req.Async {
+		if err := s.queueTask(ctx, createResp.TaskID, req); err != nil {
+			return nil, err
+		}
 		return &models.ExecuteTaskResponse{
 			TaskID:    createResp.TaskID,
-			Status:    models.TaskStatusPending,
+			Status:    models.TaskStatusQueued,
 			CreatedAt: createResp.CreatedAt,
 		}, nil
 	}
@@ -198,60 +490,71 @@ func (s *TaskServiceImpl) ExecuteTask(ctx context.Context, req *models.ExecuteTa
 	return s.executeTaskSync(ctx, createResp.TaskID, req)
 }
 
+// RunTask synchronously executes a previously created pending or queued task, such as a child task of a campaign
+func (s *TaskServiceImpl) RunTask(ctx context.Context, taskID string) (*models.ExecuteTaskResponse, error) {
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+	if !task.Status.CanTransitionTo(models.TaskStatusInProgress) {
+		return nil, apperrors.Conflict(apperrors.CodeTaskNotPending, "task %s is %s, only pending or queued tasks can be run", taskID, task.Status)
+	}
+
+	return s.executeTaskSync(ctx, taskID, executeRequestFromTask(task))
+}
+
 // Private helper methods
 
 // executeTaskSync performs synchronous task execution with dynamic AI resources
 func (s *TaskServiceImpl) executeTaskSync(ctx context.Context, taskID string, req *models.ExecuteTaskRequest) (*models.ExecuteTaskResponse, error) {
-	// Update task status to in_progress
-	if err := s.taskRepo.UpdateStatus(ctx, taskID, models.TaskStatusInProgress); err != nil {
+	// Update task status to in_progress, which fails when the task was cancelled or picked up in the meantime
+	notification := taskTransitionNotification(executedTask(taskID, req, models.TaskStatusInProgress))
+	if err := s.taskRepo.UpdateStatus(ctx, taskID, models.TaskStatusInProgress, notification); err != nil {
 		return nil, fmt.Errorf("failed to update task status: %w", err)
 	}
 
-	// Load task with full context
-	taskWithContext, err := s.loadTaskWithFullContext(ctx, taskID)
-	if err != nil {
-		s.updateTaskError(ctx, taskID, fmt.Sprintf("failed to load context: %v", err))
-		return nil, fmt.Errorf("failed to load task context: %w", err)
-	}
-
-	// Verify the agent exists and is ready (instead of creating it dynamically)
-	if taskWithContext.Agent == nil {
-		s.updateTaskError(ctx, taskID, "task has no associated agent")
-		return nil, fmt.Errorf("task must have an associated agent")
-	}
-
-	// Check if agent is in ready state
-	if taskWithContext.Agent.Status != models.AgentStatusReady {
-		s.updateTaskError(ctx, taskID, fmt.Sprintf("agent not ready: %s", taskWithContext.Agent.Status))
-		return nil, fmt.Errorf("agent %s is not ready (status: %s)", taskWithContext.Agent.AgentID, taskWithContext.Agent.Status)
-	}
+	return s.runTaskExecution(ctx, &taskExecution{service: s, taskID: taskID, req: req})
+}
 
-	// Execute task with the pre-existing agent
-	results := map[string]any{
-		"task_id":           taskID,
-		"agent_id":          taskWithContext.Agent.AgentID,
-		"agent_version":     taskWithContext.Agent.Version,
-		"agent_name":        taskWithContext.Agent.Name,
-		"ai_provider":       taskWithContext.Agent.AIProvider,
-		"execution_method":  "manual_agent",
-		"prompt":            req.Description,
-		"task_type":         req.Type,
-		"message":           "Task executed successfully with manually created agent",
-		"executed_at":       time.Now().Format(time.RFC3339),
-		"knowledge_base_id": taskWithContext.Agent.KnowledgeBaseID,
-		"vector_store_id":   taskWithContext.Agent.VectorStoreID,
+// runTaskExecution runs a task execution as the workflow run keyed by the task's ID, so an execution a crash
+// interrupted resumes after its last completed step
+func (s *TaskServiceImpl) runTaskExecution(ctx context.Context, execution *taskExecution) (*models.ExecuteTaskResponse, error) {
+	ctx = logging.WithAttrs(ctx, slog.String(logging.TaskIDKey, execution.taskID))
+	if timeout := s.taskConfig.Deadline(string(execution.req.Type)); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, timeout, &taskTimeoutError{timeout: timeout})
+		defer cancel()
 	}
 
-	// Update task with results
-	err = s.taskRepo.UpdateStatusAndOutput(ctx, taskID, models.TaskStatusCompleted, results, nil)
+	heartbeat := s.startHeartbeat(ctx, execution.taskID)
+	definition := execution.definition()
+	definition.Progress = heartbeat.reportProgress
+	run, err := s.engine.Execute(ctx, definition, execution.taskID)
+	heartbeat.stop()
 	if err != nil {
-		return nil, fmt.Errorf("failed to update task results: %w", err)
+		// Past its deadline, or its request's, the task fails as timed out whichever step was cut short
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			timeoutErr := context.Cause(ctx)
+			if !errors.As(timeoutErr, new(*taskTimeoutError)) {
+				timeoutErr = fmt.Errorf("task exceeded the deadline of its request: %w", timeoutErr)
+			}
+			s.updateTaskError(context.WithoutCancel(ctx), execution.taskID, execution.req, timeoutErr.Error(), failedExecutionOutput(run))
+			return nil, timeoutErr
+		}
+
+		var failure *taskFailure
+		if errors.As(err, &failure) {
+			s.updateTaskError(ctx, execution.taskID, execution.req, failure.message, failedExecutionOutput(run))
+			return nil, failure.err
+		}
+		return nil, err
 	}
 
+	results, _ := run.Get("results").(map[string]any)
 	completedAt := time.Now()
 
 	return &models.ExecuteTaskResponse{
-		TaskID:      taskID,
+		TaskID:      execution.taskID,
 		Status:      models.TaskStatusCompleted,
 		Output:      results,
 		CreatedAt:   time.Time{}, // Will be set from task
@@ -259,30 +562,468 @@ func (s *TaskServiceImpl) executeTaskSync(ctx context.Context, taskID string, re
 	}, nil
 }
 
-// validateResources validates that project, agent, and optionally codebase exist
+// executeRequestFromTask rebuilds the execution request of a previously created task
+func executeRequestFromTask(task *models.Task) *models.ExecuteTaskRequest {
+	req := &models.ExecuteTaskRequest{
+		ProjectID:   task.ProjectID,
+		AgentID:     task.AgentID,
+		CodebaseID:  task.CodebaseID,
+		Type:        task.Type,
+		Priority:    task.Priority,
+		Title:       task.Title,
+		Description: task.Description,
+		Input:       task.Input,
+	}
+	if task.CreatedBy != nil {
+		req.CreatedBy = *task.CreatedBy
+	}
+	return req
+}
+
+// newTaskFromRequest builds a pending task model from a creation request
+func newTaskFromRequest(req *models.CreateTaskRequest) *models.Task {
+	// Create execution context - simplified for now
+	executionContext := &models.TaskExecutionContext{
+		AgentVersion: "latest",
+		AIProvider:   "bedrock", // Will be determined from agent config
+	}
+
+	var analysisMode *models.AnalysisMode
+	if req.AnalysisMode != "" {
+		analysisMode = &req.AnalysisMode
+	}
+
+	priority := req.Priority
+	if priority == "" {
+		priority = models.TaskPriorityNormal
+	}
+
+	return &models.Task{
+		TaskID:           uuid.New().String(),
+		ProjectID:        req.ProjectID,
+		CreatedBy:        optionalString(req.CreatedBy),
+		AgentID:          req.AgentID,
+		CodebaseID:       req.CodebaseID,
+		Branch:           optionalString(req.Branch),
+		CommitSHA:        optionalString(req.CommitSHA),
+		Type:             req.Type,
+		AnalysisMode:     analysisMode,
+		Status:           models.TaskStatusPending,
+		Priority:         priority,
+		Title:            req.Title,
+		Description:      req.Description,
+		Input:            req.Input,
+		ExecutionContext: *executionContext,
+		Metadata:         req.Metadata,
+		Tags:             req.Tags,
+		CreatedAt:        time.Now(),
+		UpdatedAt:        time.Now(),
+	}
+}
+
+// validateResources validates that project, agent, and optionally codebase exist, and that the project isn't archived
 func (s *TaskServiceImpl) validateResources(ctx context.Context, projectID, agentID string, codebaseID *string) error {
-	// Validate project exists
-	if _, err := s.projectRepo.GetProject(ctx, projectID); err != nil {
-		return fmt.Errorf("project not found: %s", projectID)
+	// Validate project exists and takes new tasks
+	project, err := s.projectRepo.GetProject(ctx, projectID)
+	if err != nil {
+		return apperrors.Validation(apperrors.CodeProjectNotFound, "project not found: %s", projectID)
+	}
+	if project != nil && project.Archived() {
+		return apperrors.Conflict(apperrors.CodeProjectArchived, "project %s is archived and takes no new tasks", projectID)
 	}
 
 	// Validate agent exists (skip if agent repository not available)
 	if s.agentRepo != nil {
 		if _, err := s.agentRepo.GetAgent(ctx, agentID); err != nil {
-			return fmt.Errorf("agent not found: %s", agentID)
+			return apperrors.Validation(apperrors.CodeAgentNotFound, "agent not found: %s", agentID)
 		}
 	}
 
 	// Validate codebase if specified
 	if codebaseID != nil {
 		if _, err := s.codebaseRepo.GetCodebase(ctx, *codebaseID); err != nil {
-			return fmt.Errorf("codebase not found: %s", *codebaseID)
+			return apperrors.Validation(apperrors.CodeCodebaseNotFound, "codebase not found: %s", *codebaseID)
 		}
 	}
 
 	return nil
 }
 
+// pinRevision checks the requested branch and commit against the codebase's git provider and replaces them with the
+// resolved revision, pinning the current head of the branch when no commit is given
+func (s *TaskServiceImpl) pinRevision(ctx context.Context, req *models.CreateTaskRequest) error {
+	if req.Type == models.TaskTypeCoverageGap {
+		return s.pinCoverageBranch(ctx, req)
+	}
+	if req.Branch == "" && req.CommitSHA == "" {
+		return nil
+	}
+	if req.CodebaseID == nil {
+		return apperrors.Validation(apperrors.CodeInvalidRequest, "branch and commit_sha require codebase_id")
+	}
+
+	revision, err := s.browser.ResolveRevision(ctx, *req.CodebaseID, req.Branch, req.CommitSHA)
+	if err != nil {
+		return err
+	}
+
+	req.Branch = revision.Branch
+	req.CommitSHA = revision.CommitSHA
+	return nil
+}
+
+// pinCoverageBranch pins a coverage_gap task to the head of its branch, or of the codebase's default branch, which the
+// pull request with its tests is opened into
+func (s *TaskServiceImpl) pinCoverageBranch(ctx context.Context, req *models.CreateTaskRequest) error {
+	var revision *models.CodebaseRevision
+	var err error
+	if req.Branch == "" && req.CommitSHA == "" {
+		revision, err = s.browser.GetDefaultBranchHead(ctx, *req.CodebaseID)
+	} else {
+		revision, err = s.browser.ResolveRevision(ctx, *req.CodebaseID, req.Branch, req.CommitSHA)
+	}
+	if err != nil {
+		return err
+	}
+	if revision.Branch == "" {
+		return apperrors.Validation(apperrors.CodeInvalidRequest, "type %s requires a branch to open its pull request into", req.Type)
+	}
+
+	req.Branch = revision.Branch
+	req.CommitSHA = revision.CommitSHA
+	return nil
+}
+
+// resolveUpload describes the upload a task references in its input, for its executor to read the file from. The
+// upload must belong to the task's project.
+func (s *TaskServiceImpl) resolveUpload(ctx context.Context, req *models.CreateTaskRequest) error {
+	value, ok := req.Input[models.TaskInputUploadIDKey]
+	if !ok {
+		return nil
+	}
+	uploadID, ok := value.(string)
+	if !ok || uploadID == "" {
+		return apperrors.Validation(apperrors.CodeInvalidRequest, "input %s must be an upload ID", models.TaskInputUploadIDKey)
+	}
+
+	upload, err := s.uploads.GetTaskInput(ctx, req.ProjectID, uploadID)
+	if err != nil {
+		return err
+	}
+
+	req.Input[models.TaskInputUploadKey] = upload
+	return nil
+}
+
+// validateCodebaseScan checks that only code_analysis tasks against a codebase ask for a static analysis, that
+// dependency audits, codemods and coverage gap tasks target a codebase, that codemods select a valid transform and
+// that coverage gap tasks name their language as a string
+func validateCodebaseScan(req *models.CreateTaskRequest) error {
+	if (req.Type == models.TaskTypeDependencyAudit || req.Type == models.TaskTypeCodemod || req.Type == models.TaskTypeCoverageGap) && req.CodebaseID == nil {
+		return apperrors.Validation(apperrors.CodeInvalidRequest, "type %s requires codebase_id", req.Type)
+	}
+	if language, ok := req.Input[models.TaskInputLanguageKey]; ok && req.Type == models.TaskTypeCoverageGap {
+		if _, ok := language.(string); !ok {
+			return apperrors.Validation(apperrors.CodeInvalidRequest, "input %s must be a string", models.TaskInputLanguageKey)
+		}
+	}
+	if req.Type == models.TaskTypeCodemod {
+		if _, err := codemod.ParseSpec(req.Input); err != nil {
+			return apperrors.Validation(apperrors.CodeInvalidRequest, "%s", err.Error())
+		}
+	}
+	if req.AnalysisMode == "" {
+		return nil
+	}
+	if req.Type != models.TaskTypeCodeAnalysis {
+		return apperrors.Validation(apperrors.CodeInvalidRequest, "analysis_mode requires type %s", models.TaskTypeCodeAnalysis)
+	}
+	if req.CodebaseID == nil {
+		return apperrors.Validation(apperrors.CodeInvalidRequest, "analysis_mode requires codebase_id")
+	}
+	return nil
+}
+
+// validateTaskModel checks that a task selecting its model in its input asks for the auto model
+func validateTaskModel(req *models.CreateTaskRequest) error {
+	model, ok := req.Input[models.TaskInputModelKey]
+	if ok && model != models.TaskModelAuto {
+		return apperrors.Validation(apperrors.CodeInvalidRequest, "input %s must be %q", models.TaskInputModelKey, models.TaskModelAuto)
+	}
+	return nil
+}
+
+// validateTaskFeatures refuses the tasks needing an optional feature the service runs without
+func (s *TaskServiceImpl) validateTaskFeatures(req *models.CreateTaskRequest) error {
+	if req.Type == models.TaskTypeDependencyAudit && s.auditor == nil {
+		return apperrors.ServiceUnavailable(apperrors.CodeServiceUnavailable, "dependency audits are disabled")
+	}
+	if req.AnalysisMode == models.AnalysisModeMetrics && s.metrics == nil {
+		return apperrors.ServiceUnavailable(apperrors.CodeServiceUnavailable, "code metrics are disabled")
+	}
+	if req.IssueKey != "" && s.jira == nil {
+		return apperrors.ServiceUnavailable(apperrors.CodeServiceUnavailable, "Jira issue links are disabled")
+	}
+	return nil
+}
+
+// staticAnalysis is the outcome of a code_analysis task's static analysis
+type staticAnalysis struct {
+	findings      []analyzermodels.CodeIssue
+	seededTaskIDs []string
+	snapshot      *models.CodeMetricsSnapshot
+	sync          *models.FindingSyncResult    // Changes to the codebase's findings, nil when they weren't synced
+	graph         *analyzermodels.PackageGraph // Package graph of analyzers reporting one
+	history       []models.FileHistory         // Files changed the most, nil when the history wasn't recorded
+}
+
+// runStaticAnalysis snapshots the code metrics of a clone of the task's codebase at the pinned revision, then runs the
+// task's analyzer on it and creates a pending refactoring task for each of the first MaxSeededRefactoringTasks findings,
+// those in the files changed the most first. The metrics analysis mode only takes the snapshot.
+func (s *TaskServiceImpl) runStaticAnalysis(ctx context.Context, task *models.TaskWithFullContext) (*staticAnalysis, error) {
+	mode := *task.AnalysisMode
+	codeAnalyzer, ok := s.analyzers[mode]
+	if !ok && mode != models.AnalysisModeMetrics {
+		return nil, fmt.Errorf("no analyzer for analysis mode %s", mode)
+	}
+	if task.Codebase == nil {
+		return nil, fmt.Errorf("analysis mode %s requires a codebase", mode)
+	}
+
+	var branch, commitSHA string
+	if task.Branch != nil {
+		branch = *task.Branch
+	}
+	if task.CommitSHA != nil {
+		commitSHA = *task.CommitSHA
+	}
+	dir, cleanup, err := s.cloner.Clone(ctx, task.TaskID, task.Codebase, branch, commitSHA)
+	if err != nil {
+		return nil, fmt.Errorf("failed to clone codebase: %w", err)
+	}
+	defer cleanup()
+
+	analysis := &staticAnalysis{}
+	if s.metrics != nil {
+		analysis.snapshot, err = s.metrics.RecordSnapshot(ctx, task, dir)
+		if err != nil {
+			// Only the metrics analysis mode is about the snapshot, the others still report their findings
+			if mode == models.AnalysisModeMetrics {
+				return nil, fmt.Errorf("failed to record code metrics: %w", err)
+			}
+			slog.WarnContext(ctx, "failed to record code metrics", "error", err)
+		}
+	}
+	if mode == models.AnalysisModeMetrics {
+		return analysis, nil
+	}
+
+	result, err := codeAnalyzer.AnalyzeCode(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, fileErr := range result.Errors {
+		slog.WarnContext(ctx, "static analysis skipped a file", "analysis_mode", mode, "error", fileErr)
+	}
+	analysis.findings, err = codeAnalyzer.ExtractIssues(result)
+	if err != nil {
+		return nil, err
+	}
+	// Findings are owned by the owners of their files as of the analyzed revision
+	if s.owners != nil {
+		if _, err := s.owners.RecordOwners(ctx, *task.CodebaseID, dir, commitSHA); err != nil {
+			slog.WarnContext(ctx, "failed to record code owners", "error", err)
+		}
+	}
+	// Files changed often and recently are the hot spots refactoring pays off in first
+	var histories []models.FileHistory
+	if s.history != nil {
+		histories, err = s.history.RecordHistory(ctx, task, dir)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to record file history", "error", err)
+		} else {
+			analysis.history = histories[:min(len(histories), models.MaxTaskOutputFileHistories)]
+		}
+	}
+	// The task output still carries the findings, so tracking them across analyses failing doesn't fail the task
+	if s.findings != nil {
+		if sync, err := s.findings.SyncFindings(ctx, task, string(mode), analysis.findings); err != nil {
+			slog.WarnContext(ctx, "failed to sync findings", "analysis_mode", mode, "error", err)
+		} else {
+			slog.InfoContext(ctx, "synced findings", "analysis_mode", mode, "opened", sync.Opened, "reopened", sync.Reopened, "fixed", sync.Fixed, "suppressed", sync.Suppressed)
+			analysis.sync = sync
+			// Findings suppressed by the codebase's baseline are legacy ones, left out of the output and the seeded tasks
+			analysis.findings = withoutIssues(analysis.findings, sync.SuppressedIssues)
+		}
+	}
+	if graphAnalyzer, ok := codeAnalyzer.(analyzer.GraphAnalyzer); ok {
+		graph, err := graphAnalyzer.ExtractGraph(result)
+		if err != nil {
+			return nil, err
+		}
+		analysis.graph = &graph
+	}
+
+	seeded := hotSpotsFirst(analysis.findings, histories)
+	if len(seeded) > models.MaxSeededRefactoringTasks {
+		seeded = seeded[:models.MaxSeededRefactoringTasks]
+	}
+	if len(seeded) == 0 {
+		analysis.seededTaskIDs = []string{}
+		return analysis, nil
+	}
+
+	createdBy := ""
+	if task.CreatedBy != nil {
+		createdBy = *task.CreatedBy
+	}
+	tasks := make([]*models.Task, len(seeded))
+	taskIDs := make([]string, len(seeded))
+	for i, finding := range seeded {
+		tasks[i] = newTaskFromRequest(&models.CreateTaskRequest{
+			ProjectID:   task.ProjectID,
+			AgentID:     task.AgentID,
+			CodebaseID:  task.CodebaseID,
+			Branch:      branch,
+			CommitSHA:   commitSHA,
+			Type:        models.TaskTypeRefactoring,
+			Title:       seededTaskTitle(finding),
+			Description: seededTaskDescription(finding),
+			Input: map[string]any{
+				"finding":        finding,
+				"source_task_id": task.TaskID,
+			},
+			CreatedBy: createdBy,
+		})
+		taskIDs[i] = tasks[i].TaskID
+	}
+	if err := s.taskRepo.CreateBatch(ctx, tasks); err != nil {
+		return nil, fmt.Errorf("failed to create refactoring tasks: %w", err)
+	}
+
+	analysis.seededTaskIDs = taskIDs
+	return analysis, nil
+}
+
+// hotSpotsFirst returns a copy of issues ordered by the highest churn of their files, highest first, keeping the order
+// of issues with the same churn
+func hotSpotsFirst(issues []analyzermodels.CodeIssue, histories []models.FileHistory) []analyzermodels.CodeIssue {
+	churn := make(map[string]int, len(histories))
+	for _, history := range histories {
+		churn[history.Path] = history.Churn
+	}
+	issueChurn := func(issue analyzermodels.CodeIssue) int {
+		highest := 0
+		for _, file := range issueFiles(issue) {
+			highest = max(highest, churn[file])
+		}
+		return highest
+	}
+
+	ordered := slices.Clone(issues)
+	slices.SortStableFunc(ordered, func(a, b analyzermodels.CodeIssue) int {
+		return issueChurn(b) - issueChurn(a)
+	})
+	return ordered
+}
+
+// withoutIssues returns issues without the ones at the ascending indexes
+func withoutIssues(issues []analyzermodels.CodeIssue, indexes []int) []analyzermodels.CodeIssue {
+	if len(indexes) == 0 {
+		return issues
+	}
+
+	kept := make([]analyzermodels.CodeIssue, 0, len(issues)-len(indexes))
+	for i, issue := range issues {
+		if len(indexes) > 0 && indexes[0] == i {
+			indexes = indexes[1:]
+			continue
+		}
+		kept = append(kept, issue)
+	}
+	return kept
+}
+
+// evaluateQualityGates evaluates the quality gates of the project of an analysis task, returning nil when the project
+// has none. Gates that can't be evaluated are logged and left out of the task output rather than failing the task.
+func (s *TaskServiceImpl) evaluateQualityGates(ctx context.Context, task *models.TaskWithFullContext, analysis *staticAnalysis) *models.QualityGateReport {
+	if s.gates == nil {
+		return nil
+	}
+	report, err := s.gates.EvaluateGates(ctx, task, analysis.sync, analysis.snapshot)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to evaluate quality gates", "task_id", task.TaskID, "error", err)
+		return nil
+	}
+	if report != nil && !report.Passed {
+		slog.InfoContext(ctx, "quality gates failed", "task_id", task.TaskID, "project_id", task.ProjectID)
+	}
+	return report
+}
+
+// taskOwners returns the owners of the files of the findings and of the changes in a task's output, nil when there
+// are none. Owners that can't be resolved are logged and left out rather than failing the task.
+func (s *TaskServiceImpl) taskOwners(ctx context.Context, codebaseID string, output map[string]any) []string {
+	var paths []string
+	var findings []analyzermodels.CodeIssue
+	decodeTaskOutput(output, models.TaskOutputFindingsKey, &findings)
+	for _, finding := range findings {
+		paths = append(paths, issueFiles(finding)...)
+	}
+	diff, _ := output["diff"].(string)
+	for _, section := range diffSections(diff) {
+		if section.ID != "diff" {
+			paths = append(paths, section.ID)
+		}
+	}
+	if len(paths) == 0 || s.owners == nil {
+		return nil
+	}
+
+	owners, err := s.owners.ResolveOwners(ctx, codebaseID, paths)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to resolve code owners", "codebase_id", codebaseID, "error", err)
+		return nil
+	}
+	return owners
+}
+
+// seededTaskTitle names the refactoring task seeded from a finding
+func seededTaskTitle(finding analyzermodels.CodeIssue) string {
+	switch finding.Type {
+	case analyzermodels.IssueTypeDuplication:
+		return fmt.Sprintf("Consolidate duplicated code in %s", finding.ConsolidationTarget)
+	case analyzermodels.IssueTypeImportCycle:
+		return fmt.Sprintf("Break the import cycle between %s", strings.Join(finding.Packages, ", "))
+	case analyzermodels.IssueTypeGodPackage:
+		return fmt.Sprintf("Split package %s", finding.FilePath)
+	}
+	return fmt.Sprintf("Remove dead code in %s", finding.FilePath)
+}
+
+// seededTaskDescription instructs the agent to fix a finding at each of its locations. Package structure findings
+// get the package split prompt instead.
+func seededTaskDescription(finding analyzermodels.CodeIssue) string {
+	if finding.Type == analyzermodels.IssueTypeImportCycle || finding.Type == analyzermodels.IssueTypeGodPackage {
+		return packageSplitPrompt(finding)
+	}
+	var description strings.Builder
+	description.WriteString(finding.Message)
+	description.WriteString(".\n")
+	locations := finding.Locations
+	if len(locations) == 0 {
+		locations = []analyzermodels.CodeLocation{{FilePath: finding.FilePath, StartLine: finding.Line, EndLine: finding.EndLine}}
+	}
+	for _, location := range locations {
+		fmt.Fprintf(&description, "- %s lines %d-%d\n", location.FilePath, location.StartLine, location.EndLine)
+	}
+	for _, suggestion := range finding.Suggestions {
+		description.WriteString(suggestion)
+		description.WriteString(".\n")
+	}
+	return description.String()
+}
+
 // loadTaskWithFullContext loads a task with all related resources
 func (s *TaskServiceImpl) loadTaskWithFullContext(ctx context.Context, taskID string) (*models.TaskWithFullContext, error) {
 	// Get the task
@@ -362,10 +1103,80 @@ func (s *TaskServiceImpl) loadTaskWithFullContext(ctx context.Context, taskID st
 	return taskContext, nil
 }
 
-// updateTaskError updates a task with error status and message
-func (s *TaskServiceImpl) updateTaskError(ctx context.Context, taskID, errorMsg string) {
-	if err := s.taskRepo.UpdateStatusAndOutput(ctx, taskID, models.TaskStatusFailed, nil, &errorMsg); err != nil {
+// updateTaskError updates a task with error status, message and the output of its failed execution, if any, and
+// notifies its owner
+func (s *TaskServiceImpl) updateTaskError(ctx context.Context, taskID string, req *models.ExecuteTaskRequest, errorMsg string, output map[string]any) {
+	failed := executedTask(taskID, req, models.TaskStatusFailed)
+	failed.ErrorMessage = &errorMsg
+	notification := taskTransitionNotification(failed)
+	if err := s.taskRepo.UpdateStatusAndOutput(ctx, taskID, models.TaskStatusFailed, output, &errorMsg, notification); err != nil {
 		// Log error but don't fail since this is a cleanup operation
-		slog.Error("failed to update task error status", "task_id", taskID, "error", err)
+		slog.ErrorContext(ctx, "failed to update task error status", "error", err)
+	}
+}
+
+// executedTask returns the task executed for req as it is after moving to status, with the fields its transition
+// notification names
+func executedTask(taskID string, req *models.ExecuteTaskRequest, status models.TaskStatus) *models.Task {
+	return &models.Task{
+		TaskID:    taskID,
+		ProjectID: req.ProjectID,
+		CreatedBy: optionalString(req.CreatedBy),
+		Title:     req.Title,
+		Status:    status,
+	}
+}
+
+// taskTransitionEvents maps the statuses a task moves to onto the events the transitions raise, and what happened to
+// the task
+var taskTransitionEvents = map[models.TaskStatus]struct {
+	event   models.NotificationEvent
+	outcome string
+}{
+	models.TaskStatusQueued:     {models.NotificationEventTaskQueued, "queued"},
+	models.TaskStatusInProgress: {models.NotificationEventTaskStarted, "started"},
+	models.TaskStatusCompleted:  {models.NotificationEventTaskCompleted, "completed"},
+	models.TaskStatusFailed:     {models.NotificationEventTaskFailed, "failed"},
+	models.TaskStatusCancelled:  {models.NotificationEventTaskCancelled, "cancelled"},
+}
+
+// taskTransitionNotification returns the notification a task moving to its status raises, nil for statuses without
+// an event. The channels subscribed to the project are notified of every transition, the task's creator only when the
+// task finishes. The owners of the task's findings and changes are named, so channels can route it to them.
+func taskTransitionNotification(task *models.Task) *models.Notification {
+	transition, ok := taskTransitionEvents[task.Status]
+	if !ok {
+		return nil
+	}
+
+	message := fmt.Sprintf("Task %s in project %s %s.", task.TaskID, task.ProjectID, transition.outcome)
+	if task.ErrorMessage != nil {
+		message += "\nError: " + *task.ErrorMessage
+	}
+	var owners []string
+	decodeTaskOutput(task.Output, models.TaskOutputOwnersKey, &owners)
+	if len(owners) > 0 {
+		message += "\nOwners: " + strings.Join(owners, ", ")
+	}
+
+	notification := &models.Notification{
+		Event:      transition.event,
+		ProjectID:  task.ProjectID,
+		ResourceID: task.TaskID,
+		Subject:    fmt.Sprintf("Task %s: %s", transition.outcome, task.Title),
+		Message:    message,
+	}
+	if task.CreatedBy != nil && task.Status.IsTerminal() {
+		notification.UserID = *task.CreatedBy
+	}
+
+	return notification
+}
+
+// optionalString returns nil for an empty string
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
 	}
+	return &value
 }
@@ -0,0 +1,1325 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+	servicesMocks "github.com/kazemisoroush/code-refactoring-tool/api/services/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/analyzer"
+	analyzerMocks "github.com/kazemisoroush/code-refactoring-tool/pkg/analyzer/mocks"
+	analyzermodels "github.com/kazemisoroush/code-refactoring-tool/pkg/analyzer/models"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/workflow"
+)
+
+func newTestTaskService(ctrl *gomock.Controller) (*TaskServiceImpl, *repositoryMocks.MockTaskRepository, *repositoryMocks.MockProjectRepository, *repositoryMocks.MockAgentRepository) {
+	taskRepo := repositoryMocks.NewMockTaskRepository(ctrl)
+	projectRepo := repositoryMocks.NewMockProjectRepository(ctrl)
+	agentRepo := repositoryMocks.NewMockAgentRepository(ctrl)
+	codebaseRepo := repositoryMocks.NewMockCodebaseRepository(ctrl)
+	browser := servicesMocks.NewMockCodebaseBrowseService(ctrl)
+	cloner := servicesMocks.NewMockCodebaseCloner(ctrl)
+	analyzers := map[models.AnalysisMode]analyzer.Analyzer{
+		models.AnalysisModeDuplicateCode: analyzerMocks.NewMockAnalyzer(ctrl),
+	}
+
+	auditor := servicesMocks.NewMockDependencyAuditService(ctrl)
+	coverage := servicesMocks.NewMockCoverageGapService(ctrl)
+	metrics := servicesMocks.NewMockCodeMetricsService(ctrl)
+	findings := servicesMocks.NewMockFindingService(ctrl)
+	gates := servicesMocks.NewMockQualityGateService(ctrl)
+	// Codebases have no recorded owners unless a test replaces the service
+	owners := servicesMocks.NewMockCodeOwnersService(ctrl)
+	owners.EXPECT().RecordOwners(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.CodeOwners{}, nil).AnyTimes()
+	owners.EXPECT().ResolveOwners(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	// Codebases have no history unless a test replaces the service
+	history := servicesMocks.NewMockFileHistoryService(ctrl)
+	history.EXPECT().RecordHistory(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	jira := servicesMocks.NewMockJiraService(ctrl)
+	jira.EXPECT().AttachIssues(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	uploads := servicesMocks.NewMockUploadService(ctrl)
+	// Callers hold every permission unless a test replaces the service
+	roles := servicesMocks.NewMockRoleService(ctrl)
+	roles.EXPECT().Authorize(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	executors := NewTaskExecutorRegistry(nil)
+	_ = executors.Register(NewAgentTaskExecutor())
+
+	service := NewTaskService(taskRepo, projectRepo, agentRepo, codebaseRepo, roles, browser, cloner, analyzers, auditor, coverage, metrics, findings, gates, owners, history, jira, uploads, executors,
+		workflow.NewEngine(workflow.NewMemoryRunStore(), 0), config.TaskConfig{}).(*TaskServiceImpl)
+	return service, taskRepo, projectRepo, agentRepo
+}
+
+func TestTaskService_CreateTaskBatch_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, taskRepo, projectRepo, agentRepo := newTestTaskService(ctrl)
+
+	request := &models.CreateTaskBatchRequest{
+		Tasks: []models.CreateTaskRequest{
+			{ProjectID: "proj-1", AgentID: "agent-1", Type: models.TaskTypeRefactoring, Title: "First", Description: "Do the first thing"},
+			{ProjectID: "proj-2", AgentID: "agent-1", Type: models.TaskTypeCodeReview, Title: "Second", Description: "Do the second thing"},
+		},
+	}
+
+	projectRepo.EXPECT().GetProject(gomock.Any(), gomock.Any()).Return(&repository.ProjectRecord{}, nil).Times(2)
+	agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{}, nil).Times(2)
+	taskRepo.EXPECT().
+		CreateBatch(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, tasks []*models.Task) error {
+			require.Len(t, tasks, 2)
+			require.NotNil(t, tasks[0].BatchID)
+			assert.Equal(t, tasks[0].BatchID, tasks[1].BatchID)
+			assert.Equal(t, "First", tasks[0].Title)
+			assert.Equal(t, models.TaskStatusPending, tasks[1].Status)
+			return nil
+		})
+
+	response, err := service.CreateTaskBatch(context.Background(), request)
+
+	require.NoError(t, err)
+	assert.True(t, response.Created)
+	assert.NotEmpty(t, response.BatchID)
+	require.Len(t, response.Results, 2)
+	for i, result := range response.Results {
+		assert.Equal(t, i, result.Index)
+		assert.NotEmpty(t, result.TaskID)
+		assert.Nil(t, result.Error)
+	}
+}
+
+func TestTaskService_CreateTaskBatch_ValidationFailureCreatesNothing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _, projectRepo, agentRepo := newTestTaskService(ctrl)
+
+	request := &models.CreateTaskBatchRequest{
+		Tasks: []models.CreateTaskRequest{
+			{ProjectID: "proj-1", AgentID: "agent-1", Type: models.TaskTypeRefactoring, Title: "First", Description: "ok"},
+			{ProjectID: "proj-missing", AgentID: "agent-1", Type: models.TaskTypeRefactoring, Title: "Second", Description: "bad"},
+		},
+	}
+
+	projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{}, nil)
+	projectRepo.EXPECT().GetProject(gomock.Any(), "proj-missing").Return(nil, errors.New("project not found"))
+	agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{}, nil)
+
+	response, err := service.CreateTaskBatch(context.Background(), request)
+
+	require.NoError(t, err)
+	assert.False(t, response.Created)
+	assert.Empty(t, response.BatchID)
+	require.Len(t, response.Results, 2)
+	assert.Nil(t, response.Results[0].Error)
+	require.NotNil(t, response.Results[1].Error)
+	assert.Contains(t, *response.Results[1].Error, "proj-missing")
+}
+
+func TestTaskService_CreateTaskBatch_ForbiddenProjectCreatesNothing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _, _, _ := newTestTaskService(ctrl)
+	roles := servicesMocks.NewMockRoleService(ctrl)
+	service.roleService = roles
+
+	request := &models.CreateTaskBatchRequest{
+		Tasks: []models.CreateTaskRequest{
+			{ProjectID: "proj-1", AgentID: "agent-1", Type: models.TaskTypeRefactoring, Title: "First", Description: "ok", CreatedBy: "auth-1"},
+			{ProjectID: "proj-1", AgentID: "agent-1", Type: models.TaskTypeRefactoring, Title: "Second", Description: "ok", CreatedBy: "auth-1"},
+			{ProjectID: "proj-other", AgentID: "agent-2", Type: models.TaskTypeRefactoring, Title: "Third", Description: "ok", CreatedBy: "auth-1"},
+		},
+	}
+
+	// The project of several specs is authorized once, and no spec is validated or created
+	roles.EXPECT().Authorize(gomock.Any(), "auth-1", "proj-1", models.PermissionTaskCreate).Return(nil)
+	roles.EXPECT().Authorize(gomock.Any(), "auth-1", "proj-other", models.PermissionTaskCreate).
+		Return(apperrors.Forbidden(apperrors.CodeForbidden, "forbidden"))
+
+	response, err := service.CreateTaskBatch(context.Background(), request)
+
+	assert.ErrorIs(t, err, apperrors.ErrForbidden)
+	assert.Nil(t, response)
+}
+
+func TestTaskService_CreateTask_ArchivedProject(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _, projectRepo, _ := newTestTaskService(ctrl)
+
+	projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{ProjectID: "proj-1", Status: string(models.ProjectStatusArchived)}, nil)
+
+	_, err := service.CreateTask(context.Background(), &models.CreateTaskRequest{
+		ProjectID: "proj-1", AgentID: "agent-1", Type: models.TaskTypeRefactoring, Title: "First", Description: "ok",
+	})
+
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+	assert.Equal(t, apperrors.CodeProjectArchived, apperrors.CodeOf(err))
+}
+
+func TestTaskService_CreateTaskBatch_TooLarge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _, _, _ := newTestTaskService(ctrl)
+
+	request := &models.CreateTaskBatchRequest{
+		Tasks: make([]models.CreateTaskRequest, models.MaxTaskBatchSize+1),
+	}
+
+	_, err := service.CreateTaskBatch(context.Background(), request)
+	assert.Error(t, err)
+}
+
+func TestTaskService_GetTaskBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, taskRepo, _, _ := newTestTaskService(ctrl)
+
+	taskRepo.EXPECT().ListByBatch(gomock.Any(), "batch-1").Return([]models.Task{
+		{TaskID: "task-1", Status: models.TaskStatusCompleted},
+		{TaskID: "task-2", Status: models.TaskStatusFailed},
+		{TaskID: "task-3", Status: models.TaskStatusInProgress},
+		{TaskID: "task-4", Status: models.TaskStatusPending},
+	}, nil)
+
+	response, err := service.GetTaskBatch(context.Background(), "auth-1", "batch-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, response.TotalCount)
+	assert.Equal(t, 1, response.StatusCounts[models.TaskStatusCompleted])
+	assert.Equal(t, 1, response.StatusCounts[models.TaskStatusPending])
+	assert.InDelta(t, 50.0, response.Progress, 0.001)
+	assert.False(t, response.Done)
+	assert.Equal(t, []string{"task-1", "task-2", "task-3", "task-4"}, response.TaskIDs)
+}
+
+func TestTaskService_GetTaskBatch_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, taskRepo, _, _ := newTestTaskService(ctrl)
+
+	taskRepo.EXPECT().ListByBatch(gomock.Any(), "batch-missing").Return(nil, nil)
+
+	_, err := service.GetTaskBatch(context.Background(), "auth-1", "batch-missing")
+	assert.ErrorContains(t, err, "batch not found")
+}
+
+func TestTaskService_GetTaskBatch_Forbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, taskRepo, _, _ := newTestTaskService(ctrl)
+	roles := servicesMocks.NewMockRoleService(ctrl)
+	service.roleService = roles
+
+	taskRepo.EXPECT().ListByBatch(gomock.Any(), "batch-1").Return([]models.Task{
+		{TaskID: "task-1", ProjectID: "proj-1", Status: models.TaskStatusCompleted},
+		{TaskID: "task-2", ProjectID: "proj-2", Status: models.TaskStatusPending},
+	}, nil)
+	roles.EXPECT().Authorize(gomock.Any(), "auth-1", "proj-1", models.PermissionTaskRead).Return(nil)
+	roles.EXPECT().Authorize(gomock.Any(), "auth-1", "proj-2", models.PermissionTaskRead).
+		Return(apperrors.Forbidden(apperrors.CodeForbidden, "forbidden"))
+
+	_, err := service.GetTaskBatch(context.Background(), "auth-1", "batch-1")
+	assert.ErrorIs(t, err, apperrors.ErrForbidden)
+}
+
+func TestTaskService_AuthorizeTask(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, taskRepo, _, _ := newTestTaskService(ctrl)
+	roles := servicesMocks.NewMockRoleService(ctrl)
+	service.roleService = roles
+
+	taskRepo.EXPECT().GetByID(gomock.Any(), "task-1").Return(&models.Task{TaskID: "task-1", ProjectID: "proj-1"}, nil)
+	roles.EXPECT().Authorize(gomock.Any(), "auth-1", "proj-1", models.PermissionTaskDelete).
+		Return(apperrors.Forbidden(apperrors.CodeForbidden, "forbidden"))
+
+	err := service.AuthorizeTask(context.Background(), "auth-1", "task-1", models.PermissionTaskDelete)
+	assert.ErrorIs(t, err, apperrors.ErrForbidden)
+}
+
+func TestTaskService_AuthorizeTask_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, taskRepo, _, _ := newTestTaskService(ctrl)
+
+	taskRepo.EXPECT().GetByID(gomock.Any(), "task-missing").Return(nil, apperrors.NotFound(apperrors.CodeTaskNotFound, "task not found"))
+
+	err := service.AuthorizeTask(context.Background(), "auth-1", "task-missing", models.PermissionTaskRead)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
+
+func TestTaskService_UpdateTask_NotifiesOutcome(t *testing.T) {
+	errorMessage := "agent timed out"
+	createdBy := "user-1"
+	tests := []struct {
+		name          string
+		status        models.TaskStatus
+		expectedEvent models.NotificationEvent
+	}{
+		{name: "failed", status: models.TaskStatusFailed, expectedEvent: models.NotificationEventTaskFailed},
+		{name: "completed", status: models.TaskStatusCompleted, expectedEvent: models.NotificationEventTaskCompleted},
+		{name: "cancelled", status: models.TaskStatusCancelled, expectedEvent: models.NotificationEventTaskCancelled},
+		{name: "in_progress", status: models.TaskStatusInProgress},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, taskRepo, _, _ := newTestTaskService(ctrl)
+
+			taskRepo.EXPECT().GetByID(gomock.Any(), "task-1").Return(&models.Task{
+				TaskID:    "task-1",
+				ProjectID: "proj-1",
+				CreatedBy: &createdBy,
+				Title:     "Refactor payments",
+				Status:    models.TaskStatusInProgress,
+			}, nil)
+			taskRepo.EXPECT().
+				Update(gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ *models.Task, notification *models.Notification) error {
+					if tt.expectedEvent == "" {
+						assert.Nil(t, notification)
+						return nil
+					}
+					require.NotNil(t, notification)
+					assert.Equal(t, tt.expectedEvent, notification.Event)
+					assert.Equal(t, "proj-1", notification.ProjectID)
+					assert.Equal(t, createdBy, notification.UserID)
+					assert.Equal(t, "task-1", notification.ResourceID)
+					assert.Contains(t, notification.Subject, "Refactor payments")
+					return nil
+				})
+
+			_, err := service.UpdateTask(context.Background(), &models.UpdateTaskRequest{
+				TaskID:       "task-1",
+				Status:       &tt.status,
+				ErrorMessage: &errorMessage,
+			})
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestTaskService_UpdateTask_RejectsIllegalTransition(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, taskRepo, _, _ := newTestTaskService(ctrl)
+	status := models.TaskStatusInProgress
+
+	taskRepo.EXPECT().GetByID(gomock.Any(), "task-1").Return(&models.Task{TaskID: "task-1", Status: models.TaskStatusCompleted}, nil)
+
+	_, err := service.UpdateTask(context.Background(), &models.UpdateTaskRequest{TaskID: "task-1", Status: &status})
+
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+	assert.Equal(t, apperrors.CodeInvalidTaskTransition, apperrors.CodeOf(err))
+}
+
+func TestTaskService_UpdateTask_Approval(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, taskRepo, _, _ := newTestTaskService(ctrl)
+	approved := true
+
+	taskRepo.EXPECT().GetByID(gomock.Any(), "task-1").Return(&models.Task{TaskID: "task-1", Status: models.TaskStatusCompleted}, nil)
+	taskRepo.EXPECT().
+		Update(gomock.Any(), gomock.Any(), nil).
+		DoAndReturn(func(_ context.Context, task *models.Task, _ *models.Notification) error {
+			assert.Equal(t, &approved, task.Approved)
+			return nil
+		})
+	_, err := service.UpdateTask(context.Background(), &models.UpdateTaskRequest{TaskID: "task-1", Approved: &approved})
+	require.NoError(t, err)
+
+	taskRepo.EXPECT().GetByID(gomock.Any(), "task-2").Return(&models.Task{TaskID: "task-2", Status: models.TaskStatusFailed}, nil)
+	_, err = service.UpdateTask(context.Background(), &models.UpdateTaskRequest{TaskID: "task-2", Approved: &approved})
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+	assert.Equal(t, apperrors.CodeTaskNotCompleted, apperrors.CodeOf(err))
+}
+
+func TestTaskService_CreateTask_PinsRevision(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, taskRepo, projectRepo, agentRepo := newTestTaskService(ctrl)
+	codebaseRepo := service.codebaseRepo.(*repositoryMocks.MockCodebaseRepository)
+	browser := service.browser.(*servicesMocks.MockCodebaseBrowseService)
+
+	codebaseID := "cb-1"
+	projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{}, nil)
+	agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{}, nil)
+	codebaseRepo.EXPECT().GetCodebase(gomock.Any(), codebaseID).Return(&models.Codebase{CodebaseID: codebaseID}, nil)
+	browser.EXPECT().
+		ResolveRevision(gomock.Any(), codebaseID, "feature/jwt-auth", "").
+		Return(&models.CodebaseRevision{Branch: "feature/jwt-auth", CommitSHA: "9fceb02d0ae598e95dc970b74767f19372d61af8"}, nil)
+	taskRepo.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, task *models.Task) error {
+			require.NotNil(t, task.Branch)
+			assert.Equal(t, "feature/jwt-auth", *task.Branch)
+			require.NotNil(t, task.CommitSHA)
+			assert.Equal(t, "9fceb02d0ae598e95dc970b74767f19372d61af8", *task.CommitSHA)
+			return nil
+		})
+
+	_, err := service.CreateTask(context.Background(), &models.CreateTaskRequest{
+		ProjectID: "proj-1", AgentID: "agent-1", CodebaseID: &codebaseID, Branch: "feature/jwt-auth",
+		Type: models.TaskTypeRefactoring, Title: "Refactor", Description: "Refactor the auth module",
+	})
+
+	require.NoError(t, err)
+}
+
+func TestTaskService_CreateTask_LinksIssue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, taskRepo, projectRepo, agentRepo := newTestTaskService(ctrl)
+	jira := service.jira.(*servicesMocks.MockJiraService)
+
+	link := &models.JiraIssueLink{Organization: "acme", IssueKey: "PAY-123", IssueURL: "https://acme.atlassian.net/browse/PAY-123"}
+	projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{}, nil)
+	agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{}, nil)
+	jira.EXPECT().ResolveIssue(gomock.Any(), "PAY-123").Return(link, nil)
+	taskRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+	jira.EXPECT().LinkIssue(gomock.Any(), gomock.Any(), link).Return(nil)
+
+	response, err := service.CreateTask(context.Background(), &models.CreateTaskRequest{
+		ProjectID: "proj-1", AgentID: "agent-1", Type: models.TaskTypeRefactoring, Title: "Refactor", Description: "Refactor payments",
+		IssueKey: "PAY-123",
+	})
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, response.TaskID)
+}
+
+func TestTaskService_CreateTask_LinkFailureDeletesTask(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, taskRepo, projectRepo, agentRepo := newTestTaskService(ctrl)
+	jira := service.jira.(*servicesMocks.MockJiraService)
+
+	var taskID string
+	projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{}, nil)
+	agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{}, nil)
+	jira.EXPECT().ResolveIssue(gomock.Any(), "PAY-123").Return(&models.JiraIssueLink{IssueKey: "PAY-123"}, nil)
+	taskRepo.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, task *models.Task) error {
+			taskID = task.TaskID
+			return nil
+		})
+	jira.EXPECT().LinkIssue(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("connection refused"))
+	taskRepo.EXPECT().
+		Delete(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, id string) error {
+			assert.Equal(t, taskID, id)
+			return nil
+		})
+
+	_, err := service.CreateTask(context.Background(), &models.CreateTaskRequest{
+		ProjectID: "proj-1", AgentID: "agent-1", Type: models.TaskTypeRefactoring, Title: "Refactor", Description: "Refactor payments",
+		IssueKey: "PAY-123",
+	})
+
+	assert.Error(t, err)
+}
+
+func TestTaskService_CreateTask_IssueLinksDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _, projectRepo, agentRepo := newTestTaskService(ctrl)
+	service.jira = nil
+
+	projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{}, nil)
+	agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{}, nil)
+
+	_, err := service.CreateTask(context.Background(), &models.CreateTaskRequest{
+		ProjectID: "proj-1", AgentID: "agent-1", Type: models.TaskTypeRefactoring, Title: "Refactor", Description: "Refactor payments",
+		IssueKey: "PAY-123",
+	})
+
+	assert.ErrorIs(t, err, apperrors.ErrServiceUnavailable)
+}
+
+func TestTaskService_CreateTask_UnknownCommit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _, projectRepo, agentRepo := newTestTaskService(ctrl)
+	codebaseRepo := service.codebaseRepo.(*repositoryMocks.MockCodebaseRepository)
+	browser := service.browser.(*servicesMocks.MockCodebaseBrowseService)
+
+	codebaseID := "cb-1"
+	projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{}, nil)
+	agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{}, nil)
+	codebaseRepo.EXPECT().GetCodebase(gomock.Any(), codebaseID).Return(&models.Codebase{CodebaseID: codebaseID}, nil)
+	browser.EXPECT().
+		ResolveRevision(gomock.Any(), codebaseID, "", "deadbeef").
+		Return(nil, apperrors.Validation(apperrors.CodeGitRefNotFound, "commit deadbeef not found in codebase cb-1"))
+
+	_, err := service.CreateTask(context.Background(), &models.CreateTaskRequest{
+		ProjectID: "proj-1", AgentID: "agent-1", CodebaseID: &codebaseID, CommitSHA: "deadbeef",
+		Type: models.TaskTypeRefactoring, Title: "Refactor", Description: "Refactor the auth module",
+	})
+
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+}
+
+func TestTaskService_CreateTask_ResolvesUpload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, taskRepo, projectRepo, agentRepo := newTestTaskService(ctrl)
+	uploads := service.uploads.(*servicesMocks.MockUploadService)
+
+	upload := &models.TaskInputUpload{UploadID: "upl-1", ProjectID: "proj-1", URI: "s3://bucket/task-inputs/proj-1/upl-1"}
+	projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{}, nil)
+	agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{}, nil)
+	uploads.EXPECT().GetTaskInput(gomock.Any(), "proj-1", "upl-1").Return(upload, nil)
+	taskRepo.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, task *models.Task) error {
+			assert.Equal(t, upload, task.Input[models.TaskInputUploadKey])
+			return nil
+		})
+
+	_, err := service.CreateTask(context.Background(), &models.CreateTaskRequest{
+		ProjectID: "proj-1", AgentID: "agent-1", Type: models.TaskTypeCodeAnalysis, Title: "Analyze", Description: "Analyze the uploaded diff",
+		Input: map[string]any{models.TaskInputUploadIDKey: "upl-1"},
+	})
+
+	require.NoError(t, err)
+}
+
+func TestTaskService_CreateTask_RejectsInvalidUploadID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _, projectRepo, agentRepo := newTestTaskService(ctrl)
+
+	projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{}, nil)
+	agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{}, nil)
+
+	_, err := service.CreateTask(context.Background(), &models.CreateTaskRequest{
+		ProjectID: "proj-1", AgentID: "agent-1", Type: models.TaskTypeCodeAnalysis, Title: "Analyze", Description: "Analyze the uploaded diff",
+		Input: map[string]any{models.TaskInputUploadIDKey: 42},
+	})
+
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+}
+
+func TestTaskService_CreateTask_RejectsUnknownModel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _, projectRepo, agentRepo := newTestTaskService(ctrl)
+
+	projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{}, nil)
+	agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{}, nil)
+
+	_, err := service.CreateTask(context.Background(), &models.CreateTaskRequest{
+		ProjectID: "proj-1", AgentID: "agent-1", Type: models.TaskTypeDocumentation, Title: "Document", Description: "Document the billing package",
+		Input: map[string]any{models.TaskInputModelKey: "gpt-4"},
+	})
+
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+}
+
+func TestTaskService_RunTask_NotPending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, taskRepo, _, _ := newTestTaskService(ctrl)
+
+	taskRepo.EXPECT().GetByID(gomock.Any(), "task-1").Return(&models.Task{TaskID: "task-1", Status: models.TaskStatusCompleted}, nil)
+
+	_, err := service.RunTask(context.Background(), "task-1")
+
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+}
+
+func TestTaskService_ExecuteTask_AsyncQueuesTask(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, taskRepo, projectRepo, agentRepo := newTestTaskService(ctrl)
+
+	projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{}, nil)
+	agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{}, nil)
+	taskRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+	taskRepo.EXPECT().
+		UpdateStatus(gomock.Any(), gomock.Any(), models.TaskStatusQueued, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ models.TaskStatus, notification *models.Notification) error {
+			require.NotNil(t, notification)
+			assert.Equal(t, models.NotificationEventTaskQueued, notification.Event)
+			assert.Empty(t, notification.UserID, "only the outcome reaches the creator's inbox")
+			return nil
+		})
+
+	response, err := service.ExecuteTask(context.Background(), &models.ExecuteTaskRequest{
+		ProjectID: "proj-1", AgentID: "agent-1", Type: models.TaskTypeRefactoring, Title: "Refactor",
+		Description: "Refactor the auth module", CreatedBy: "user-1", Async: true,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.TaskStatusQueued, response.Status)
+}
+
+func TestTaskService_CreateTask_AnalysisModeRequiresCodeAnalysis(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _, projectRepo, agentRepo := newTestTaskService(ctrl)
+	codebaseRepo := service.codebaseRepo.(*repositoryMocks.MockCodebaseRepository)
+
+	codebaseID := "cb-1"
+	projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{}, nil)
+	agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{}, nil)
+	codebaseRepo.EXPECT().GetCodebase(gomock.Any(), codebaseID).Return(&models.Codebase{CodebaseID: codebaseID}, nil)
+
+	_, err := service.CreateTask(context.Background(), &models.CreateTaskRequest{
+		ProjectID: "proj-1", AgentID: "agent-1", CodebaseID: &codebaseID, AnalysisMode: models.AnalysisModeDeadCode,
+		Type: models.TaskTypeRefactoring, Title: "Refactor", Description: "Refactor the auth module",
+	})
+
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+}
+
+func TestTaskService_RunTask_StaticAnalysisSeedsRefactoringTasks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, taskRepo, projectRepo, agentRepo := newTestTaskService(ctrl)
+	codebaseRepo := service.codebaseRepo.(*repositoryMocks.MockCodebaseRepository)
+	cloner := service.cloner.(*servicesMocks.MockCodebaseCloner)
+	duplicates := service.analyzers[models.AnalysisModeDuplicateCode].(*analyzerMocks.MockAnalyzer)
+	metrics := service.metrics.(*servicesMocks.MockCodeMetricsService)
+	findings := service.findings.(*servicesMocks.MockFindingService)
+	gates := service.gates.(*servicesMocks.MockQualityGateService)
+	owners := servicesMocks.NewMockCodeOwnersService(ctrl)
+	service.owners = owners
+
+	codebaseID := "cb-1"
+	commitSHA := "9fceb02d0ae598e95dc970b74767f19372d61af8"
+	mode := models.AnalysisModeDuplicateCode
+	task := &models.Task{
+		TaskID: "task-1", ProjectID: "proj-1", AgentID: "agent-1", CodebaseID: &codebaseID, CommitSHA: &commitSHA,
+		Type: models.TaskTypeCodeAnalysis, AnalysisMode: &mode, Status: models.TaskStatusPending,
+		Title: "Find duplicates", Description: "Find duplicated code",
+	}
+	finding := analyzermodels.CodeIssue{
+		Type:    analyzermodels.IssueTypeDuplication,
+		Message: "Block of 10 lines (80 tokens) is duplicated in 2 places",
+		Locations: []analyzermodels.CodeLocation{
+			{FilePath: "billing/invoice.go", StartLine: 3, EndLine: 12},
+			{FilePath: "payments/charge.go", StartLine: 5, EndLine: 14},
+		},
+		Suggestions:         []string{"Extract the duplicated block into a shared package under . and call it from each location"},
+		ConsolidationTarget: ".",
+	}
+	cleanedUp := false
+
+	taskRepo.EXPECT().GetByID(gomock.Any(), "task-1").Return(task, nil).Times(2)
+	taskRepo.EXPECT().UpdateStatus(gomock.Any(), "task-1", models.TaskStatusInProgress, gomock.Any()).Return(nil)
+	projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{ProjectID: "proj-1"}, nil)
+	agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{AgentID: "agent-1", Status: string(models.AgentStatusReady)}, nil)
+	codebaseRepo.EXPECT().GetCodebase(gomock.Any(), codebaseID).Return(&models.Codebase{CodebaseID: codebaseID, URL: "https://github.com/acme/payments"}, nil)
+	cloner.EXPECT().
+		Clone(gomock.Any(), gomock.Any(), gomock.Any(), "", commitSHA).
+		Return("/tmp/clone", func() { cleanedUp = true }, nil)
+	metrics.EXPECT().RecordSnapshot(gomock.Any(), gomock.Any(), "/tmp/clone").Return(nil, errors.New("parse error"))
+	duplicates.EXPECT().AnalyzeCode("/tmp/clone").Return(analyzermodels.AnalysisResult{RawOutput: "[]"}, nil)
+	duplicates.EXPECT().ExtractIssues(gomock.Any()).Return([]analyzermodels.CodeIssue{finding}, nil)
+	owners.EXPECT().RecordOwners(gomock.Any(), codebaseID, "/tmp/clone", commitSHA).Return(&models.CodeOwners{}, nil)
+	owners.EXPECT().ResolveOwners(gomock.Any(), codebaseID, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, paths []string) ([]string, error) {
+			assert.Subset(t, paths, []string{"billing/invoice.go", "payments/charge.go"})
+			return []string{"@acme/billing", "@acme/payments"}, nil
+		})
+	sync := &models.FindingSyncResult{Opened: 1, NewBySeverity: map[models.FindingSeverity]int{models.FindingSeverityMedium: 1}}
+	findings.EXPECT().
+		SyncFindings(gomock.Any(), gomock.Any(), string(mode), []analyzermodels.CodeIssue{finding}).
+		Return(sync, nil)
+	gateReport := &models.QualityGateReport{Gates: []models.QualityGateResult{{Name: "No new findings", Outcome: models.QualityGateOutcomeFailed}}}
+	gates.EXPECT().EvaluateGates(gomock.Any(), gomock.Any(), sync, nil).Return(gateReport, nil)
+	taskRepo.EXPECT().
+		CreateBatch(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, tasks []*models.Task) error {
+			require.Len(t, tasks, 1)
+			assert.Equal(t, models.TaskTypeRefactoring, tasks[0].Type)
+			assert.Equal(t, "Consolidate duplicated code in .", tasks[0].Title)
+			assert.Contains(t, tasks[0].Description, "payments/charge.go lines 5-14")
+			require.NotNil(t, tasks[0].CommitSHA)
+			assert.Equal(t, commitSHA, *tasks[0].CommitSHA)
+			assert.Equal(t, "task-1", tasks[0].Input["source_task_id"])
+			return nil
+		})
+	taskRepo.EXPECT().
+		UpdateStatusAndOutput(gomock.Any(), "task-1", models.TaskStatusCompleted, gomock.Any(), nil, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ models.TaskStatus, output map[string]any, _ *string, notification *models.Notification) error {
+			assert.Equal(t, []analyzermodels.CodeIssue{finding}, output[models.TaskOutputFindingsKey])
+			assert.Len(t, output[models.TaskOutputSeededTaskIDsKey], 1)
+			assert.Equal(t, gateReport, output[models.TaskOutputQualityGatesKey])
+			assert.Equal(t, []string{"@acme/billing", "@acme/payments"}, output[models.TaskOutputOwnersKey])
+			require.NotNil(t, notification)
+			assert.Contains(t, notification.Message, "Owners: @acme/billing, @acme/payments")
+			return nil
+		})
+
+	_, err := service.RunTask(context.Background(), "task-1")
+
+	require.NoError(t, err)
+	assert.True(t, cleanedUp)
+}
+
+func TestTaskService_RunTask_PackageStructureSeedsSplitTasks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, taskRepo, projectRepo, agentRepo := newTestTaskService(ctrl)
+	codebaseRepo := service.codebaseRepo.(*repositoryMocks.MockCodebaseRepository)
+	cloner := service.cloner.(*servicesMocks.MockCodebaseCloner)
+	metrics := service.metrics.(*servicesMocks.MockCodeMetricsService)
+	findings := service.findings.(*servicesMocks.MockFindingService)
+	gates := service.gates.(*servicesMocks.MockQualityGateService)
+	service.analyzers[models.AnalysisModePackageStructure] = analyzer.NewPackageStructureAnalyzer(40, 2000)
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"go.mod":         "module example.com/app\n\ngo 1.24\n",
+		"api/api.go":     "package api\n\nimport \"example.com/app/store\"\n\nvar _ = store.Get\n",
+		"store/store.go": "package store\n\nimport \"example.com/app/api\"\n\nvar _ = api.Handle\n",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+
+	codebaseID := "cb-1"
+	mode := models.AnalysisModePackageStructure
+	task := &models.Task{
+		TaskID: "task-1", ProjectID: "proj-1", AgentID: "agent-1", CodebaseID: &codebaseID,
+		Type: models.TaskTypeCodeAnalysis, AnalysisMode: &mode, Status: models.TaskStatusPending,
+		Title: "Check packages", Description: "Find import cycles",
+	}
+
+	taskRepo.EXPECT().GetByID(gomock.Any(), "task-1").Return(task, nil).Times(2)
+	taskRepo.EXPECT().UpdateStatus(gomock.Any(), "task-1", models.TaskStatusInProgress, gomock.Any()).Return(nil)
+	projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{ProjectID: "proj-1"}, nil)
+	agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{AgentID: "agent-1", Status: string(models.AgentStatusReady)}, nil)
+	codebaseRepo.EXPECT().GetCodebase(gomock.Any(), codebaseID).Return(&models.Codebase{CodebaseID: codebaseID}, nil)
+	cloner.EXPECT().Clone(gomock.Any(), gomock.Any(), gomock.Any(), "", "").Return(dir, func() {}, nil)
+	metrics.EXPECT().RecordSnapshot(gomock.Any(), gomock.Any(), dir).Return(nil, nil)
+	findings.EXPECT().SyncFindings(gomock.Any(), gomock.Any(), string(mode), gomock.Len(1)).Return(&models.FindingSyncResult{Opened: 1}, nil)
+	gates.EXPECT().EvaluateGates(gomock.Any(), gomock.Any(), gomock.Any(), nil).Return(nil, nil)
+	taskRepo.EXPECT().
+		CreateBatch(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, tasks []*models.Task) error {
+			require.Len(t, tasks, 1)
+			assert.Equal(t, "Break the import cycle between api, store", tasks[0].Title)
+			assert.Equal(t, `Import cycle between packages api -> store -> api.
+The cycle is made by these imports:
+- api/api.go line 3
+- store/store.go line 3
+Break it by moving what one package uses of the other into it, by moving what both share into a new package, or by depending on an interface declared by the importing package.
+
+First propose the split as a list of moves. Give each move's declarations or files, the package they move to and the imports that change, keeping the packages free of import cycles. Then make the moves so that the module still builds, and report each moved file as a change.
+`, tasks[0].Description)
+			return nil
+		})
+	taskRepo.EXPECT().
+		UpdateStatusAndOutput(gomock.Any(), "task-1", models.TaskStatusCompleted, gomock.Any(), nil, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ models.TaskStatus, output map[string]any, _ *string, _ *models.Notification) error {
+			graph, ok := output[models.TaskOutputPackageGraphKey].(*analyzermodels.PackageGraph)
+			require.True(t, ok)
+			assert.Equal(t, []analyzermodels.PackageImport{{From: "api", To: "store"}, {From: "store", To: "api"}}, graph.Imports)
+			assert.Contains(t, graph.DOT, `"store" -> "api" [color=red];`)
+			return nil
+		})
+
+	_, err := service.RunTask(context.Background(), "task-1")
+
+	require.NoError(t, err)
+}
+
+func TestTaskService_RunTask_MetricsRecordsSnapshot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, taskRepo, projectRepo, agentRepo := newTestTaskService(ctrl)
+	codebaseRepo := service.codebaseRepo.(*repositoryMocks.MockCodebaseRepository)
+	cloner := service.cloner.(*servicesMocks.MockCodebaseCloner)
+	metrics := service.metrics.(*servicesMocks.MockCodeMetricsService)
+	gates := service.gates.(*servicesMocks.MockQualityGateService)
+
+	codebaseID := "cb-1"
+	mode := models.AnalysisModeMetrics
+	task := &models.Task{
+		TaskID: "task-1", ProjectID: "proj-1", AgentID: "agent-1", CodebaseID: &codebaseID,
+		Type: models.TaskTypeCodeAnalysis, AnalysisMode: &mode, Status: models.TaskStatusPending,
+		Title: "Measure", Description: "Measure maintainability",
+	}
+	snapshot := &models.CodeMetricsSnapshot{SnapshotID: "metrics-1", CodebaseID: codebaseID, CommitSHA: "abc123", MaintainabilityScore: 92.5}
+
+	taskRepo.EXPECT().GetByID(gomock.Any(), "task-1").Return(task, nil).Times(2)
+	taskRepo.EXPECT().UpdateStatus(gomock.Any(), "task-1", models.TaskStatusInProgress, gomock.Any()).Return(nil)
+	projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{ProjectID: "proj-1"}, nil)
+	agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{AgentID: "agent-1", Status: string(models.AgentStatusReady)}, nil)
+	codebaseRepo.EXPECT().GetCodebase(gomock.Any(), codebaseID).Return(&models.Codebase{CodebaseID: codebaseID}, nil)
+	cloner.EXPECT().Clone(gomock.Any(), gomock.Any(), gomock.Any(), "", "").Return("/tmp/clone", func() {}, nil)
+	metrics.EXPECT().RecordSnapshot(gomock.Any(), gomock.Any(), "/tmp/clone").Return(snapshot, nil)
+	gates.EXPECT().EvaluateGates(gomock.Any(), gomock.Any(), nil, snapshot).Return(nil, nil)
+	taskRepo.EXPECT().
+		UpdateStatusAndOutput(gomock.Any(), "task-1", models.TaskStatusCompleted, gomock.Any(), nil, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ models.TaskStatus, output map[string]any, _ *string, _ *models.Notification) error {
+			assert.Equal(t, snapshot, output[models.TaskOutputMetricsKey])
+			assert.NotContains(t, output, models.TaskOutputFindingsKey)
+			return nil
+		})
+
+	_, err := service.RunTask(context.Background(), "task-1")
+
+	require.NoError(t, err)
+}
+
+func TestTaskService_RunTask_IngestionBlockedCodebase(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, taskRepo, projectRepo, agentRepo := newTestTaskService(ctrl)
+	codebaseRepo := service.codebaseRepo.(*repositoryMocks.MockCodebaseRepository)
+
+	codebaseID := "cb-1"
+	ingestionError := "ingestion blocked by 1 high severity findings: Committed AWS access key ID in prod.env:2"
+	task := &models.Task{
+		TaskID: "task-1", ProjectID: "proj-1", AgentID: "agent-1", CodebaseID: &codebaseID,
+		Type: models.TaskTypeRefactoring, Status: models.TaskStatusPending,
+		Title: "Refactor", Description: "Refactor the billing package",
+	}
+
+	taskRepo.EXPECT().GetByID(gomock.Any(), "task-1").Return(task, nil).Times(2)
+	taskRepo.EXPECT().UpdateStatus(gomock.Any(), "task-1", models.TaskStatusInProgress, gomock.Any()).Return(nil)
+	projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{ProjectID: "proj-1"}, nil)
+	agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{AgentID: "agent-1", Status: string(models.AgentStatusReady)}, nil)
+	codebaseRepo.EXPECT().
+		GetCodebase(gomock.Any(), codebaseID).
+		Return(&models.Codebase{CodebaseID: codebaseID, Status: models.CodebaseStatusIngestionBlocked, IngestionError: &ingestionError}, nil)
+	taskRepo.EXPECT().UpdateStatusAndOutput(gomock.Any(), "task-1", models.TaskStatusFailed, gomock.Nil(), &ingestionError, gomock.Any()).Return(nil)
+
+	_, err := service.RunTask(context.Background(), "task-1")
+
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+	assert.Equal(t, apperrors.CodeIngestionBlocked, apperrors.CodeOf(err))
+}
+
+func TestTaskService_CreateTask_DependencyAuditRequiresCodebase(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _, projectRepo, agentRepo := newTestTaskService(ctrl)
+
+	projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{}, nil)
+	agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{}, nil)
+
+	_, err := service.CreateTask(context.Background(), &models.CreateTaskRequest{
+		ProjectID: "proj-1", AgentID: "agent-1", Type: models.TaskTypeDependencyAudit,
+		Title: "Audit dependencies", Description: "Check for vulnerable dependencies",
+	})
+
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+}
+
+func TestTaskService_CreateTask_CodemodRequiresValidTransform(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _, projectRepo, agentRepo := newTestTaskService(ctrl)
+	codebaseRepo := service.codebaseRepo.(*repositoryMocks.MockCodebaseRepository)
+
+	codebaseID := "cb-1"
+	projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{}, nil)
+	agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{}, nil)
+	codebaseRepo.EXPECT().GetCodebase(gomock.Any(), codebaseID).Return(&models.Codebase{CodebaseID: codebaseID}, nil)
+
+	_, err := service.CreateTask(context.Background(), &models.CreateTaskRequest{
+		ProjectID: "proj-1", AgentID: "agent-1", CodebaseID: &codebaseID, Type: models.TaskTypeCodemod,
+		Title: "Rename", Description: "Rename a symbol",
+		Input: map[string]any{"transform": "rename_symbol", "params": map[string]any{"symbol": "Old"}},
+	})
+
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	assert.ErrorContains(t, err, "transform rename_symbol requires the package param")
+}
+
+func TestTaskService_RunTask_DependencyAudit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, taskRepo, projectRepo, agentRepo := newTestTaskService(ctrl)
+	codebaseRepo := service.codebaseRepo.(*repositoryMocks.MockCodebaseRepository)
+	auditor := service.auditor.(*servicesMocks.MockDependencyAuditService)
+
+	codebaseID := "cb-1"
+	upgradeTaskID := "task-2"
+	task := &models.Task{
+		TaskID: "task-1", ProjectID: "proj-1", AgentID: "agent-1", CodebaseID: &codebaseID,
+		Type: models.TaskTypeDependencyAudit, Status: models.TaskStatusPending,
+		Title: "Audit dependencies", Description: "Check for vulnerable dependencies",
+	}
+	findings := []models.DependencyFinding{{FindingID: "f-1", Package: "lodash", Severity: models.DependencySeverityCritical}}
+
+	taskRepo.EXPECT().GetByID(gomock.Any(), "task-1").Return(task, nil).Times(2)
+	taskRepo.EXPECT().UpdateStatus(gomock.Any(), "task-1", models.TaskStatusInProgress, gomock.Any()).Return(nil)
+	projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{ProjectID: "proj-1"}, nil)
+	agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{AgentID: "agent-1", Status: string(models.AgentStatusReady)}, nil)
+	codebaseRepo.EXPECT().GetCodebase(gomock.Any(), codebaseID).Return(&models.Codebase{CodebaseID: codebaseID}, nil)
+	auditor.EXPECT().
+		AuditCodebase(gomock.Any(), gomock.Any()).
+		Return(&models.DependencyAuditResult{DependencyCount: 12, Findings: findings, UpgradeTaskID: &upgradeTaskID}, nil)
+	taskRepo.EXPECT().
+		UpdateStatusAndOutput(gomock.Any(), "task-1", models.TaskStatusCompleted, gomock.Any(), nil, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ models.TaskStatus, output map[string]any, _ *string, notification *models.Notification) error {
+			require.NotNil(t, notification, "the outcome is notified in the transaction that stores it")
+			assert.Equal(t, models.NotificationEventTaskCompleted, notification.Event)
+			assert.Equal(t, findings, output[models.TaskOutputFindingsKey])
+			assert.Equal(t, 12, output["dependency_count"])
+			assert.Equal(t, upgradeTaskID, output[models.TaskOutputUpgradeTaskIDKey])
+			return nil
+		})
+
+	_, err := service.RunTask(context.Background(), "task-1")
+
+	require.NoError(t, err)
+}
+
+func TestTaskService_CreateTask_CoverageGapPinsDefaultBranch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, taskRepo, projectRepo, agentRepo := newTestTaskService(ctrl)
+	codebaseRepo := service.codebaseRepo.(*repositoryMocks.MockCodebaseRepository)
+	browser := service.browser.(*servicesMocks.MockCodebaseBrowseService)
+
+	codebaseID := "cb-1"
+	projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{}, nil)
+	agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{}, nil)
+	codebaseRepo.EXPECT().GetCodebase(gomock.Any(), codebaseID).Return(&models.Codebase{CodebaseID: codebaseID}, nil)
+	browser.EXPECT().GetDefaultBranchHead(gomock.Any(), codebaseID).Return(&models.CodebaseRevision{Branch: "main", CommitSHA: "abc123"}, nil)
+	taskRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, task *models.Task) error {
+		require.NotNil(t, task.Branch)
+		assert.Equal(t, "main", *task.Branch)
+		assert.Equal(t, "abc123", *task.CommitSHA)
+		return nil
+	})
+
+	_, err := service.CreateTask(context.Background(), &models.CreateTaskRequest{
+		ProjectID: "proj-1", AgentID: "agent-1", CodebaseID: &codebaseID, Type: models.TaskTypeCoverageGap,
+		Title: "Cover the services", Description: "Prefer table tests",
+	})
+
+	require.NoError(t, err)
+}
+
+func TestTaskService_CreateTask_CoverageGapRequiresBranch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _, projectRepo, agentRepo := newTestTaskService(ctrl)
+	codebaseRepo := service.codebaseRepo.(*repositoryMocks.MockCodebaseRepository)
+	browser := service.browser.(*servicesMocks.MockCodebaseBrowseService)
+
+	codebaseID := "cb-1"
+	projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{}, nil)
+	agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{}, nil)
+	codebaseRepo.EXPECT().GetCodebase(gomock.Any(), codebaseID).Return(&models.Codebase{CodebaseID: codebaseID}, nil)
+	browser.EXPECT().ResolveRevision(gomock.Any(), codebaseID, "", "abc123").Return(&models.CodebaseRevision{CommitSHA: "abc123"}, nil)
+
+	_, err := service.CreateTask(context.Background(), &models.CreateTaskRequest{
+		ProjectID: "proj-1", AgentID: "agent-1", CodebaseID: &codebaseID, Type: models.TaskTypeCoverageGap,
+		Title: "Cover the services", Description: "Prefer table tests", CommitSHA: "abc123",
+	})
+
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	assert.ErrorContains(t, err, "requires a branch to open its pull request into")
+}
+
+func TestTaskService_RunTask_CoverageGapOpensPullRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, taskRepo, projectRepo, agentRepo := newTestTaskService(ctrl)
+	codebaseRepo := service.codebaseRepo.(*repositoryMocks.MockCodebaseRepository)
+	coverage := service.coverage.(*servicesMocks.MockCoverageGapService)
+	tester := servicesMocks.NewMockTaskExecutor(ctrl)
+	tester.EXPECT().Name().Return("tester").AnyTimes()
+	tester.EXPECT().Supports(gomock.Any()).Return(true).AnyTimes()
+	service.executors = NewTaskExecutorRegistry(nil)
+	require.NoError(t, service.executors.Register(tester))
+
+	codebaseID, branch, commitSHA := "cb-1", "main", "abc123"
+	task := &models.Task{
+		TaskID: "task-1", ProjectID: "proj-1", AgentID: "agent-1", CodebaseID: &codebaseID, Branch: &branch, CommitSHA: &commitSHA,
+		Type: models.TaskTypeCoverageGap, Status: models.TaskStatusPending,
+		Title: "Cover the services", Description: "Prefer table tests",
+	}
+	report := &models.CoverageGapReport{
+		Language: "go", BaseBranch: branch, BaseCommit: commitSHA, UncoveredFunctions: 3,
+		Gaps: []models.CoverageGap{{FilePath: "calc/calc.go", Function: "Calc.Div", StartLine: 9, EndLine: 14, Statements: 3, Uncovered: 1, Commits: 4}},
+	}
+	diff := "diff --git a/calc/calc_test.go b/calc/calc_test.go\n"
+
+	taskRepo.EXPECT().GetByID(gomock.Any(), "task-1").Return(task, nil).Times(2)
+	taskRepo.EXPECT().UpdateStatus(gomock.Any(), "task-1", models.TaskStatusInProgress, gomock.Any()).Return(nil)
+	projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{ProjectID: "proj-1"}, nil)
+	agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{AgentID: "agent-1", Status: string(models.AgentStatusReady)}, nil)
+	codebaseRepo.EXPECT().GetCodebase(gomock.Any(), codebaseID).Return(&models.Codebase{CodebaseID: codebaseID}, nil)
+	gomock.InOrder(
+		coverage.EXPECT().FindGaps(gomock.Any(), gomock.Any()).Return(report, nil),
+		tester.EXPECT().
+			Execute(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, task *models.TaskWithFullContext) (map[string]any, error) {
+				assert.True(t, strings.HasPrefix(task.Description, "Prefer table tests\n\n"), "the task's description comes first")
+				assert.Contains(t, task.Description, "- Calc.Div in calc/calc.go lines 9-14: 1 of 3 uncovered, 4 commits")
+				return map[string]any{"diff": diff}, nil
+			}),
+		coverage.EXPECT().
+			PublishTests(gomock.Any(), gomock.Any(), report, diff).
+			DoAndReturn(func(_ context.Context, task *models.TaskWithFullContext, _ *models.CoverageGapReport, _ string) (*models.PullRequest, error) {
+				assert.Equal(t, "Prefer table tests", task.Description, "the task itself is left as created")
+				return &models.PullRequest{URL: "https://github.com/acme/app/pull/7", Branch: "coverage/task-1", Base: branch}, nil
+			}),
+	)
+	taskRepo.EXPECT().
+		UpdateStatusAndOutput(gomock.Any(), "task-1", models.TaskStatusCompleted, gomock.Any(), nil, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ models.TaskStatus, output map[string]any, _ *string, _ *models.Notification) error {
+			assert.Equal(t, report, output[models.TaskOutputCoverageGapsKey])
+			assert.Equal(t, "https://github.com/acme/app/pull/7", output[models.TaskOutputPullRequestURLKey])
+			assert.Equal(t, "coverage/task-1", output["pull_request_branch"])
+			return nil
+		})
+
+	_, err := service.RunTask(context.Background(), "task-1")
+
+	require.NoError(t, err)
+}
+
+func TestTaskService_RunTask_CoverageGapWithoutTestsFailsTask(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, taskRepo, projectRepo, agentRepo := newTestTaskService(ctrl)
+	codebaseRepo := service.codebaseRepo.(*repositoryMocks.MockCodebaseRepository)
+	coverage := service.coverage.(*servicesMocks.MockCoverageGapService)
+
+	codebaseID, branch, commitSHA := "cb-1", "main", "abc123"
+	task := &models.Task{
+		TaskID: "task-1", ProjectID: "proj-1", AgentID: "agent-1", CodebaseID: &codebaseID, Branch: &branch, CommitSHA: &commitSHA,
+		Type: models.TaskTypeCoverageGap, Status: models.TaskStatusPending, Title: "Cover the services",
+	}
+
+	taskRepo.EXPECT().GetByID(gomock.Any(), "task-1").Return(task, nil).Times(2)
+	taskRepo.EXPECT().UpdateStatus(gomock.Any(), "task-1", models.TaskStatusInProgress, gomock.Any()).Return(nil)
+	projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{ProjectID: "proj-1"}, nil)
+	agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{AgentID: "agent-1", Status: string(models.AgentStatusReady)}, nil)
+	codebaseRepo.EXPECT().GetCodebase(gomock.Any(), codebaseID).Return(&models.Codebase{CodebaseID: codebaseID}, nil)
+	coverage.EXPECT().FindGaps(gomock.Any(), gomock.Any()).Return(&models.CoverageGapReport{BaseBranch: branch, BaseCommit: commitSHA}, nil)
+	taskRepo.EXPECT().
+		UpdateStatusAndOutput(gomock.Any(), "task-1", models.TaskStatusFailed, gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ models.TaskStatus, _ map[string]any, message *string, _ *models.Notification) error {
+			require.NotNil(t, message)
+			assert.Equal(t, "the executor wrote no tests", *message)
+			return nil
+		})
+
+	_, err := service.RunTask(context.Background(), "task-1")
+
+	require.Error(t, err)
+}
+
+func TestTaskService_RunTask_RoutesTaskToItsExecutor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, taskRepo, projectRepo, agentRepo := newTestTaskService(ctrl)
+	linter := servicesMocks.NewMockTaskExecutor(ctrl)
+	linter.EXPECT().Name().Return("linter").AnyTimes()
+	linter.EXPECT().Supports(models.TaskTypeCodeReview).Return(true).AnyTimes()
+	service.executors = NewTaskExecutorRegistry(map[string]string{string(models.TaskTypeCodeReview): "linter"})
+	require.NoError(t, service.executors.Register(NewAgentTaskExecutor()))
+	require.NoError(t, service.executors.Register(linter))
+
+	task := &models.Task{
+		TaskID: "task-1", ProjectID: "proj-1", AgentID: "agent-1",
+		Type: models.TaskTypeCodeReview, Status: models.TaskStatusPending,
+		Title: "Review", Description: "Review the handlers",
+	}
+
+	taskRepo.EXPECT().GetByID(gomock.Any(), "task-1").Return(task, nil).Times(2)
+	taskRepo.EXPECT().UpdateStatus(gomock.Any(), "task-1", models.TaskStatusInProgress, gomock.Any()).Return(nil)
+	projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{ProjectID: "proj-1"}, nil)
+	agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{AgentID: "agent-1", Status: string(models.AgentStatusReady)}, nil)
+	linter.EXPECT().
+		Execute(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, task *models.TaskWithFullContext) (map[string]any, error) {
+			assert.Equal(t, "task-1", task.TaskID)
+			return map[string]any{"issues": 3, "task_id": "overridden"}, nil
+		})
+	taskRepo.EXPECT().
+		UpdateStatusAndOutput(gomock.Any(), "task-1", models.TaskStatusCompleted, gomock.Any(), nil, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ models.TaskStatus, output map[string]any, _ *string, _ *models.Notification) error {
+			assert.Equal(t, "linter", output["executor"])
+			assert.Equal(t, float64(3), output["issues"], "outputs read back from the run are JSON values")
+			assert.Equal(t, "task-1", output["task_id"], "executors can't replace the fields describing the task")
+			assert.NotContains(t, output, "execution_method")
+			return nil
+		})
+
+	_, err := service.RunTask(context.Background(), "task-1")
+
+	require.NoError(t, err)
+}
+
+func TestTaskService_RunTask_ExecutorFailureFailsTask(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, taskRepo, projectRepo, agentRepo := newTestTaskService(ctrl)
+	external := servicesMocks.NewMockTaskExecutor(ctrl)
+	external.EXPECT().Name().Return("local-llm").AnyTimes()
+	external.EXPECT().Supports(gomock.Any()).Return(true).AnyTimes()
+	service.executors = NewTaskExecutorRegistry(nil)
+	require.NoError(t, service.executors.Register(external))
+
+	task := &models.Task{
+		TaskID: "task-1", ProjectID: "proj-1", AgentID: "agent-1",
+		Type: models.TaskTypeDocumentation, Status: models.TaskStatusPending, Title: "Document",
+	}
+
+	taskRepo.EXPECT().GetByID(gomock.Any(), "task-1").Return(task, nil).Times(2)
+	taskRepo.EXPECT().UpdateStatus(gomock.Any(), "task-1", models.TaskStatusInProgress, gomock.Any()).Return(nil)
+	projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{ProjectID: "proj-1"}, nil)
+	agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{AgentID: "agent-1", Status: string(models.AgentStatusReady)}, nil)
+	external.EXPECT().Execute(gomock.Any(), gomock.Any()).Return(map[string]any{"trace": []any{"step 1"}}, errors.New("model is loading"))
+	taskRepo.EXPECT().
+		UpdateStatusAndOutput(gomock.Any(), "task-1", models.TaskStatusFailed, gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ models.TaskStatus, output map[string]any, errorMessage *string, _ *models.Notification) error {
+			assert.Equal(t, "execution failed: model is loading", *errorMessage)
+			assert.Equal(t, map[string]any{"executor": "local-llm", "trace": []any{"step 1"}}, output, "the output of the failed execution is kept")
+			return nil
+		})
+
+	_, err := service.RunTask(context.Background(), "task-1")
+
+	require.Error(t, err)
+}
+
+func TestTaskService_RunTask_FailsTaskPastItsDeadline(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, taskRepo, projectRepo, agentRepo := newTestTaskService(ctrl)
+	service.taskConfig = config.TaskConfig{
+		Timeout:      time.Hour,
+		TypeTimeouts: map[string]time.Duration{string(models.TaskTypeDependencyAudit): 20 * time.Millisecond},
+	}
+	codebaseRepo := service.codebaseRepo.(*repositoryMocks.MockCodebaseRepository)
+	auditor := service.auditor.(*servicesMocks.MockDependencyAuditService)
+
+	codebaseID := "cb-1"
+	task := &models.Task{
+		TaskID: "task-1", ProjectID: "proj-1", AgentID: "agent-1", CodebaseID: &codebaseID,
+		Type: models.TaskTypeDependencyAudit, Status: models.TaskStatusPending, Title: "Audit dependencies",
+	}
+
+	taskRepo.EXPECT().GetByID(gomock.Any(), "task-1").Return(task, nil).Times(2)
+	taskRepo.EXPECT().UpdateStatus(gomock.Any(), "task-1", models.TaskStatusInProgress, gomock.Any()).Return(nil)
+	projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{ProjectID: "proj-1"}, nil)
+	agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{AgentID: "agent-1", Status: string(models.AgentStatusReady)}, nil)
+	codebaseRepo.EXPECT().GetCodebase(gomock.Any(), codebaseID).Return(&models.Codebase{CodebaseID: codebaseID}, nil)
+	auditor.EXPECT().
+		AuditCodebase(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ *models.TaskWithFullContext) (*models.DependencyAuditResult, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+	taskRepo.EXPECT().
+		UpdateStatusAndOutput(gomock.Any(), "task-1", models.TaskStatusFailed, gomock.Nil(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ string, _ models.TaskStatus, _ map[string]any, errorMessage *string, _ *models.Notification) error {
+			require.NoError(t, ctx.Err(), "the failure is recorded after the deadline")
+			assert.Equal(t, "task exceeded its execution deadline of 20ms", *errorMessage)
+			return nil
+		})
+
+	_, err := service.RunTask(context.Background(), "task-1")
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, apperrors.CodeTimeout, apperrors.CodeOf(err))
+}
+
+func TestTaskService_ResumeInterruptedTasks_SkipsCompletedSteps(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, taskRepo, projectRepo, agentRepo := newTestTaskService(ctrl)
+	codebaseRepo := service.codebaseRepo.(*repositoryMocks.MockCodebaseRepository)
+
+	store := workflow.NewMemoryRunStore()
+	service.engine = workflow.NewEngine(store, 0)
+
+	codebaseID := "cb-1"
+	mode := models.AnalysisModeDuplicateCode
+	task := &models.Task{
+		TaskID: "task-1", ProjectID: "proj-1", AgentID: "agent-1", CodebaseID: &codebaseID,
+		Type: models.TaskTypeCodeAnalysis, AnalysisMode: &mode, Status: models.TaskStatusInProgress,
+		Title: "Find duplicates", Description: "Find duplicated code",
+	}
+
+	// A crash interrupted the execution after its static analysis seeded a task, as read back from the run store
+	require.NoError(t, store.SaveRun(context.Background(), &workflow.Run{
+		RunID:    "task-1",
+		Workflow: TaskExecutionWorkflowName,
+		Status:   workflow.RunStatusRunning,
+		Steps: []workflow.StepProgress{
+			{Name: "prepare", Status: workflow.StepStatusCompleted, Attempts: 1},
+			{Name: "static_analysis", Status: workflow.StepStatusCompleted, Attempts: 1},
+			{Name: "dependency_audit", Status: workflow.StepStatusCompleted, Attempts: 1},
+			{Name: "complete", Status: workflow.StepStatusPending},
+		},
+		Values: map[string]any{
+			models.TaskOutputFindingsKey:      []any{},
+			models.TaskOutputSeededTaskIDsKey: []any{"task-2"},
+		},
+	}))
+
+	// The context is loaded again, but the analysis isn't rerun
+	taskRepo.EXPECT().GetByID(gomock.Any(), "task-1").Return(task, nil).Times(2)
+	projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{ProjectID: "proj-1"}, nil)
+	agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{AgentID: "agent-1", Status: string(models.AgentStatusReady)}, nil)
+	codebaseRepo.EXPECT().GetCodebase(gomock.Any(), codebaseID).Return(&models.Codebase{CodebaseID: codebaseID}, nil)
+	taskRepo.EXPECT().
+		UpdateStatusAndOutput(gomock.Any(), "task-1", models.TaskStatusCompleted, gomock.Any(), nil, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ models.TaskStatus, output map[string]any, _ *string, _ *models.Notification) error {
+			assert.Equal(t, []any{"task-2"}, output[models.TaskOutputSeededTaskIDsKey])
+			return nil
+		})
+
+	err := service.ResumeInterruptedTasks(context.Background())
+
+	require.NoError(t, err)
+	run, err := store.GetRun(context.Background(), "task-1")
+	require.NoError(t, err)
+	assert.Equal(t, workflow.RunStatusCompleted, run.Status)
+}
+
+func TestTaskService_RunTask_CompletionFailureDeletesSeededTasks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, taskRepo, projectRepo, agentRepo := newTestTaskService(ctrl)
+	codebaseRepo := service.codebaseRepo.(*repositoryMocks.MockCodebaseRepository)
+	cloner := service.cloner.(*servicesMocks.MockCodebaseCloner)
+	duplicates := service.analyzers[models.AnalysisModeDuplicateCode].(*analyzerMocks.MockAnalyzer)
+	metrics := service.metrics.(*servicesMocks.MockCodeMetricsService)
+	findings := service.findings.(*servicesMocks.MockFindingService)
+	gates := service.gates.(*servicesMocks.MockQualityGateService)
+
+	codebaseID := "cb-1"
+	mode := models.AnalysisModeDuplicateCode
+	task := &models.Task{
+		TaskID: "task-1", ProjectID: "proj-1", AgentID: "agent-1", CodebaseID: &codebaseID,
+		Type: models.TaskTypeCodeAnalysis, AnalysisMode: &mode, Status: models.TaskStatusPending,
+		Title: "Find duplicates", Description: "Find duplicated code",
+	}
+	finding := analyzermodels.CodeIssue{Type: analyzermodels.IssueTypeDeadCode, Message: "Unused function", FilePath: "billing/invoice.go"}
+	var seededTaskID string
+
+	taskRepo.EXPECT().GetByID(gomock.Any(), "task-1").Return(task, nil).Times(2)
+	taskRepo.EXPECT().UpdateStatus(gomock.Any(), "task-1", models.TaskStatusInProgress, gomock.Any()).Return(nil)
+	projectRepo.EXPECT().GetProject(gomock.Any(), "proj-1").Return(&repository.ProjectRecord{ProjectID: "proj-1"}, nil)
+	agentRepo.EXPECT().GetAgent(gomock.Any(), "agent-1").Return(&repository.AgentRecord{AgentID: "agent-1", Status: string(models.AgentStatusReady)}, nil)
+	codebaseRepo.EXPECT().GetCodebase(gomock.Any(), codebaseID).Return(&models.Codebase{CodebaseID: codebaseID}, nil)
+	cloner.EXPECT().Clone(gomock.Any(), gomock.Any(), gomock.Any(), "", "").Return("/tmp/clone", func() {}, nil)
+	metrics.EXPECT().RecordSnapshot(gomock.Any(), gomock.Any(), "/tmp/clone").Return(nil, nil)
+	duplicates.EXPECT().AnalyzeCode("/tmp/clone").Return(analyzermodels.AnalysisResult{}, nil)
+	duplicates.EXPECT().ExtractIssues(gomock.Any()).Return([]analyzermodels.CodeIssue{finding}, nil)
+	findings.EXPECT().SyncFindings(gomock.Any(), gomock.Any(), string(mode), gomock.Any()).Return(nil, errors.New("connection reset"))
+	gates.EXPECT().EvaluateGates(gomock.Any(), gomock.Any(), nil, nil).Return(nil, errors.New("connection reset"))
+	taskRepo.EXPECT().
+		CreateBatch(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, tasks []*models.Task) error {
+			seededTaskID = tasks[0].TaskID
+			return nil
+		})
+	taskRepo.EXPECT().
+		UpdateStatusAndOutput(gomock.Any(), "task-1", models.TaskStatusCompleted, gomock.Any(), nil, gomock.Any()).
+		Return(errors.New("connection reset"))
+
+	// The tasks seeded from the findings of the failed execution are rolled back
+	taskRepo.EXPECT().
+		Delete(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, taskID string) error {
+			assert.Equal(t, seededTaskID, taskID)
+			return nil
+		})
+
+	_, err := service.RunTask(context.Background(), "task-1")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to update task results")
+}
+
+func TestWithoutIssues(t *testing.T) {
+	issues := []analyzermodels.CodeIssue{{Message: "a"}, {Message: "b"}, {Message: "c"}, {Message: "d"}}
+
+	assert.Equal(t, issues, withoutIssues(issues, nil))
+	assert.Equal(t, []analyzermodels.CodeIssue{{Message: "b"}, {Message: "d"}}, withoutIssues(issues, []int{0, 2}))
+}
+
+func TestHotSpotsFirst(t *testing.T) {
+	issues := []analyzermodels.CodeIssue{
+		{Message: "a", FilePath: "cold.go"},
+		{Message: "b", FilePath: "hot.go"},
+		{Message: "c", FilePath: "unknown.go"},
+		{Message: "d", Locations: []analyzermodels.CodeLocation{{FilePath: "cold.go"}, {FilePath: "warm.go"}}},
+	}
+	histories := []models.FileHistory{{Path: "hot.go", Churn: 300}, {Path: "warm.go", Churn: 40}, {Path: "cold.go", Churn: 2}}
+
+	ordered := hotSpotsFirst(issues, histories)
+
+	var messages []string
+	for _, issue := range ordered {
+		messages = append(messages, issue.Message)
+	}
+	assert.Equal(t, []string{"b", "d", "a", "c"}, messages)
+	assert.Equal(t, "a", issues[0].Message, "the issues are left in their order")
+	assert.Equal(t, issues, hotSpotsFirst(issues, nil))
+}
@@ -0,0 +1,20 @@
+package services
+
+import (
+	"context"
+	"io"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// UploadService defines the interface for the large task inputs uploaded to object storage, which tasks reference
+// instead of carrying the content in their JSON input
+//
+//go:generate mockgen -destination=./mocks/mock_upload_service.go -mock_names=UploadService=MockUploadService -package=mocks . UploadService
+type UploadService interface {
+	// UploadTaskInput streams a file to the uploads of the project as it's read from body
+	UploadTaskInput(ctx context.Context, projectID, filename, contentType, uploadedBy string, body io.Reader) (*models.TaskInputUpload, error)
+
+	// GetTaskInput describes an upload of the project
+	GetTaskInput(ctx context.Context, projectID, uploadID string) (*models.TaskInputUpload, error)
+}
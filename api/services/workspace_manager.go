@@ -0,0 +1,415 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/codebase"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/objectstore"
+)
+
+// workspaceRepoDir is the directory of a workspace the codebase is cloned into
+const workspaceRepoDir = "repo"
+
+// checkoutFunc clones a codebase into dir at commitSHA, or at the head of branch, returning the repository directory
+// and the commit checked out
+type checkoutFunc func(ctx context.Context, cb *models.Codebase, dir, branch, commitSHA string) (string, string, error)
+
+// WorkspaceManager clones codebases into workspace directories under a root it owns, tracking them per task runner.
+// Released workspaces stay on disk for later analyses of the same commit. When the disk quota is reached the least
+// recently used idle workspaces are evicted, and clones are refused while the workspaces in use alone exceed it.
+type WorkspaceManager struct {
+	repo       repository.WorkspaceRepository
+	checkout   checkoutFunc
+	root       string
+	quotaBytes int64
+	idleTTL    time.Duration
+	host       string
+	startedAt  time.Time
+
+	// mu serializes the claims, evictions and garbage collections of this task runner's workspaces
+	mu sync.Mutex
+	// cloning holds the directories being cloned, which have no record yet
+	cloning map[string]bool
+}
+
+// NewWorkspaceManager creates a new WorkspaceManager cloning with the configured git credentials, or the SSH key of
+// the codebase configuration, and extracting the archives of uploaded codebases from the archives store, creating its
+// root and clone cache if needed
+func NewWorkspaceManager(repo repository.WorkspaceRepository, configs repository.CodebaseConfigRepository, archives objectstore.ObjectStore, git config.GitConfig, uploads config.CodebaseUploadsConfig, cfg config.WorkspaceConfig) (*WorkspaceManager, error) {
+	root := cfg.Root
+	if root == "" {
+		root = filepath.Join(os.TempDir(), "code-refactor-workspaces")
+	}
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create workspace root: %w", err)
+	}
+
+	git.CloneCacheDir = cfg.CloneCache
+	if git.CloneCacheDir == "" {
+		git.CloneCacheDir = filepath.Join(os.TempDir(), "code-refactor-clone-cache")
+	}
+	if err := os.MkdirAll(git.CloneCacheDir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create clone cache: %w", err)
+	}
+
+	host, err := os.Hostname()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve host name: %w", err)
+	}
+
+	return &WorkspaceManager{
+		repo:       repo,
+		checkout:   archiveCheckout(archives, uploads.MaxExtractedSize, gitCheckout(git, configs)),
+		root:       root,
+		quotaBytes: cfg.QuotaMB << 20,
+		idleTTL:    cfg.IdleTTL,
+		host:       host,
+		startedAt:  time.Now(),
+		cloning:    map[string]bool{},
+	}, nil
+}
+
+// codebaseOpener opens a codebase's repository at a local path, before it's cloned there
+type codebaseOpener func(ctx context.Context, cb *models.Codebase, localPath string) (codebase.Codebase, error)
+
+// gitCodebase opens the repositories of codebases with the given git credentials and the codebase's checkout options.
+// Custom codebases configured for SSH authentication use the configuration's SSH key instead.
+func gitCodebase(git config.GitConfig, configs repository.CodebaseConfigRepository) codebaseOpener {
+	return func(ctx context.Context, cb *models.Codebase, localPath string) (codebase.Codebase, error) {
+		git.CodebaseURL = cb.URL
+		if cb.Checkout != nil {
+			git.CloneDepth = cb.Checkout.Depth
+			git.SparsePaths = cb.Checkout.SparsePaths
+		}
+
+		switch cb.Provider {
+		case models.ProviderAzureDevOps:
+			git.Token = git.AzureDevOpsToken
+			return codebase.NewAzureDevOpsCodebaseAt(git, localPath), nil
+		case models.ProviderCustom:
+			record, err := configs.GetCodebaseConfig(ctx, cb.ConfigID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get codebase configuration: %w", err)
+			}
+			if custom := record.Config.Custom; record.Config.AuthType == models.GitAuthTypeSSH && custom != nil {
+				return codebase.NewSSHCodebaseAt(git, custom.SSHKey, custom.KnownHosts, localPath), nil
+			}
+			return codebase.NewGitHubCodebaseAt(git, localPath), nil
+		default:
+			return codebase.NewGitHubCodebaseAt(git, localPath), nil
+		}
+	}
+}
+
+// gitCheckout clones codebases with the repositories opened by gitCodebase
+func gitCheckout(git config.GitConfig, configs repository.CodebaseConfigRepository) checkoutFunc {
+	open := gitCodebase(git, configs)
+	return func(ctx context.Context, cb *models.Codebase, dir, branch, commitSHA string) (string, string, error) {
+		repo, err := open(ctx, cb, filepath.Join(dir, workspaceRepoDir))
+		if err != nil {
+			return "", "", err
+		}
+		if err := repo.CloneRevision(ctx, branch, commitSHA); err != nil {
+			return "", "", err
+		}
+
+		head, err := codebase.HeadCommit(repo.GetPath())
+		if err != nil {
+			return "", "", err
+		}
+		return repo.GetPath(), head, nil
+	}
+}
+
+// archiveCheckout extracts the archives of uploaded codebases, at most maxBytes of them, and checks out the other
+// codebases with checkout. Archives have no commits, so their workspaces aren't reused.
+func archiveCheckout(archives objectstore.ObjectStore, maxBytes int64, checkout checkoutFunc) checkoutFunc {
+	return func(ctx context.Context, cb *models.Codebase, dir, branch, commitSHA string) (string, string, error) {
+		if cb.Provider != models.ProviderUpload {
+			return checkout(ctx, cb, dir, branch, commitSHA)
+		}
+
+		repo, err := codebase.NewArchiveCodebaseAt(archives, cb.URL, maxBytes, filepath.Join(dir, workspaceRepoDir))
+		if err != nil {
+			return "", "", err
+		}
+		if err := repo.Clone(ctx); err != nil {
+			return "", "", err
+		}
+		return repo.GetPath(), "", nil
+	}
+}
+
+// Clone reuses an idle workspace holding commitSHA, or clones the codebase into a new workspace once the quota allows
+// it. The returned function releases the workspace for reuse.
+func (m *WorkspaceManager) Clone(ctx context.Context, taskID string, cb *models.Codebase, branch, commitSHA string) (string, func(), error) {
+	if commitSHA != "" {
+		workspace, err := m.claimIdle(ctx, taskID, cb.CodebaseID, commitSHA)
+		if err != nil {
+			return "", nil, err
+		}
+		if workspace != nil {
+			slog.DebugContext(ctx, "reusing workspace", "workspace_id", workspace.WorkspaceID, "codebase_id", cb.CodebaseID)
+			return filepath.Join(workspace.Path, workspaceRepoDir), m.release(ctx, workspace.WorkspaceID, workspace.Path), nil
+		}
+	}
+
+	workspaceID := "ws-" + uuid.New().String()
+	dir := filepath.Join(m.root, workspaceID)
+	if err := m.reserve(ctx, dir); err != nil {
+		return "", nil, err
+	}
+	defer m.unreserve(dir)
+
+	repoDir, head, err := m.checkout(ctx, cb, dir, branch, commitSHA)
+	if err != nil {
+		m.removeDir(ctx, dir)
+		return "", nil, err
+	}
+
+	now := time.Now()
+	workspace := &models.Workspace{
+		WorkspaceID: workspaceID,
+		TaskID:      taskID,
+		CodebaseID:  cb.CodebaseID,
+		CommitSHA:   head,
+		Host:        m.host,
+		Path:        dir,
+		Status:      models.WorkspaceStatusInUse,
+		CreatedAt:   now,
+		LastUsedAt:  now,
+	}
+	if err := m.repo.CreateWorkspace(ctx, workspace); err != nil {
+		m.removeDir(ctx, dir)
+		return "", nil, err
+	}
+
+	return repoDir, m.release(ctx, workspaceID, dir), nil
+}
+
+// claimIdle claims an idle workspace holding the commit, dropping the records of workspaces whose directory is gone
+func (m *WorkspaceManager) claimIdle(ctx context.Context, taskID, codebaseID, commitSHA string) (*models.Workspace, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for {
+		workspace, err := m.repo.ClaimIdleWorkspace(ctx, m.host, codebaseID, commitSHA, taskID)
+		if err != nil || workspace == nil {
+			return nil, err
+		}
+		if _, err := os.Stat(filepath.Join(workspace.Path, workspaceRepoDir)); err == nil {
+			return workspace, nil
+		}
+		if err := m.repo.DeleteWorkspace(ctx, workspace.WorkspaceID); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// reserve makes room for a new workspace, evicting the least recently used idle workspaces until the workspaces fit
+// the quota
+func (m *WorkspaceManager) reserve(ctx context.Context, dir string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	workspaces, err := m.repo.ListWorkspaces(ctx, m.host)
+	if err != nil {
+		return err
+	}
+
+	var used int64
+	for _, workspace := range workspaces {
+		used += workspace.SizeBytes
+	}
+	for _, workspace := range workspaces {
+		if used < m.quotaBytes {
+			break
+		}
+		if workspace.Status != models.WorkspaceStatusIdle {
+			continue
+		}
+		if err := m.remove(ctx, workspace); err != nil {
+			return err
+		}
+		slog.InfoContext(ctx, "evicted workspace", "workspace_id", workspace.WorkspaceID, "size_bytes", workspace.SizeBytes)
+		used -= workspace.SizeBytes
+	}
+	if used >= m.quotaBytes {
+		return fmt.Errorf("workspace disk quota of %d bytes is used by workspaces in use", m.quotaBytes)
+	}
+
+	m.cloning[dir] = true
+	return nil
+}
+
+// unreserve forgets a directory once its clone is recorded or removed
+func (m *WorkspaceManager) unreserve(dir string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.cloning, dir)
+}
+
+// release returns a function measuring a workspace and marking it idle. It outlives the request's deadline, and a
+// workspace that can't be released is removed rather than left in use.
+func (m *WorkspaceManager) release(ctx context.Context, workspaceID, dir string) func() {
+	ctx = context.WithoutCancel(ctx)
+	return func() {
+		size, err := dirSize(dir)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to measure workspace", "workspace_id", workspaceID, "error", err)
+		}
+		if err := m.repo.ReleaseWorkspace(ctx, workspaceID, size); err != nil {
+			slog.WarnContext(ctx, "failed to release workspace", "workspace_id", workspaceID, "error", err)
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			if err := m.remove(ctx, models.Workspace{WorkspaceID: workspaceID, Path: dir}); err != nil {
+				slog.WarnContext(ctx, "failed to remove workspace", "workspace_id", workspaceID, "error", err)
+			}
+		}
+	}
+}
+
+// remove deletes a workspace's directory, then its record
+func (m *WorkspaceManager) remove(ctx context.Context, workspace models.Workspace) error {
+	if err := os.RemoveAll(workspace.Path); err != nil {
+		return fmt.Errorf("failed to remove workspace directory: %w", err)
+	}
+	return m.repo.DeleteWorkspace(ctx, workspace.WorkspaceID)
+}
+
+// removeDir removes a directory that has no record
+func (m *WorkspaceManager) removeDir(ctx context.Context, dir string) {
+	if err := os.RemoveAll(dir); err != nil {
+		slog.WarnContext(ctx, "failed to remove workspace directory", "dir", dir, "error", err)
+	}
+}
+
+// GetUsage reports the disk used by the workspaces of this task runner
+func (m *WorkspaceManager) GetUsage(ctx context.Context) (*models.GetWorkspaceUsageResponse, error) {
+	workspaces, err := m.repo.ListWorkspaces(ctx, m.host)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.GetWorkspaceUsageResponse{
+		Host:       m.host,
+		Root:       m.root,
+		QuotaBytes: m.quotaBytes,
+		Workspaces: workspaces,
+	}
+	for _, workspace := range workspaces {
+		response.UsedBytes += workspace.SizeBytes
+		if workspace.Status == models.WorkspaceStatusInUse {
+			response.InUseBytes += workspace.SizeBytes
+		} else {
+			response.IdleBytes += workspace.SizeBytes
+		}
+	}
+
+	return response, nil
+}
+
+// CollectGarbage removes the workspace directories without a record, the records without a directory, the
+// workspaces left in use by a previous run and the workspaces idle for longer than their TTL
+func (m *WorkspaceManager) CollectGarbage(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	workspaces, err := m.repo.ListWorkspaces(ctx, m.host)
+	if err != nil {
+		return err
+	}
+
+	tracked := map[string]bool{}
+	expiredBefore := time.Now().Add(-m.idleTTL)
+	for _, workspace := range workspaces {
+		_, statErr := os.Stat(workspace.Path)
+		var reason string
+		switch {
+		case workspace.Status == models.WorkspaceStatusInUse && workspace.LastUsedAt.Before(m.startedAt):
+			reason = "left in use by a previous run"
+		case workspace.Status == models.WorkspaceStatusIdle && errors.Is(statErr, fs.ErrNotExist):
+			reason = "directory missing"
+		case workspace.Status == models.WorkspaceStatusIdle && workspace.LastUsedAt.Before(expiredBefore):
+			reason = "idle TTL expired"
+		default:
+			tracked[workspace.Path] = true
+			continue
+		}
+
+		if err := m.remove(ctx, workspace); err != nil {
+			return err
+		}
+		slog.InfoContext(ctx, "collected workspace", "workspace_id", workspace.WorkspaceID, "reason", reason)
+	}
+
+	entries, err := os.ReadDir(m.root)
+	if err != nil {
+		return fmt.Errorf("failed to read workspace root: %w", err)
+	}
+	for _, entry := range entries {
+		dir := filepath.Join(m.root, entry.Name())
+		if tracked[dir] || m.cloning[dir] {
+			continue
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("failed to remove orphaned workspace directory: %w", err)
+		}
+		slog.InfoContext(ctx, "collected orphaned workspace directory", "dir", dir)
+	}
+
+	return nil
+}
+
+// RunGarbageCollection collects the workspaces at startup, then every interval until the context is cancelled
+func (m *WorkspaceManager) RunGarbageCollection(ctx context.Context, interval time.Duration) {
+	if err := m.CollectGarbage(ctx); err != nil {
+		slog.ErrorContext(ctx, "failed to collect workspaces", "error", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.CollectGarbage(ctx); err != nil {
+				slog.ErrorContext(ctx, "failed to collect workspaces", "error", err)
+			}
+		}
+	}
+}
+
+// dirSize returns the size of the files under dir
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.Type().IsRegular() {
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/api/repository/mocks"
+)
+
+// newTestWorkspaceManager creates a workspace manager under a temporary root whose checkouts write a file of
+// cloneSize bytes, counting them in checkouts
+func newTestWorkspaceManager(t *testing.T, repo *repositoryMocks.MockWorkspaceRepository, quotaBytes int64, checkouts *int) *WorkspaceManager {
+	t.Helper()
+	return &WorkspaceManager{
+		repo: repo,
+		checkout: func(_ context.Context, _ *models.Codebase, dir, _, _ string) (string, string, error) {
+			*checkouts++
+			repoDir := filepath.Join(dir, workspaceRepoDir)
+			if err := os.MkdirAll(repoDir, 0o750); err != nil {
+				return "", "", err
+			}
+			return repoDir, "abc123", os.WriteFile(filepath.Join(repoDir, "main.go"), make([]byte, 10), 0o600)
+		},
+		root:       t.TempDir(),
+		quotaBytes: quotaBytes,
+		idleTTL:    time.Hour,
+		host:       "runner-1",
+		startedAt:  time.Now().Add(-time.Minute),
+		cloning:    map[string]bool{},
+	}
+}
+
+// addWorkspaceDir creates the directory of a workspace under the manager's root
+func addWorkspaceDir(t *testing.T, m *WorkspaceManager, workspace models.Workspace) models.Workspace {
+	t.Helper()
+	workspace.Path = filepath.Join(m.root, workspace.WorkspaceID)
+	require.NoError(t, os.MkdirAll(filepath.Join(workspace.Path, workspaceRepoDir), 0o750))
+	return workspace
+}
+
+func TestWorkspaceManager_Clone_ReusesIdleWorkspace(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := repositoryMocks.NewMockWorkspaceRepository(ctrl)
+	var checkouts int
+	manager := newTestWorkspaceManager(t, repo, 1<<20, &checkouts)
+	idle := addWorkspaceDir(t, manager, models.Workspace{WorkspaceID: "ws-1", CodebaseID: "cb-1", CommitSHA: "abc123"})
+
+	repo.EXPECT().ClaimIdleWorkspace(gomock.Any(), "runner-1", "cb-1", "abc123", "task-1").Return(&idle, nil)
+	repo.EXPECT().ReleaseWorkspace(gomock.Any(), "ws-1", int64(0)).Return(nil)
+
+	dir, release, err := manager.Clone(context.Background(), "task-1", &models.Codebase{CodebaseID: "cb-1"}, "", "abc123")
+	require.NoError(t, err)
+	release()
+
+	assert.Equal(t, filepath.Join(idle.Path, workspaceRepoDir), dir)
+	assert.Zero(t, checkouts)
+}
+
+func TestWorkspaceManager_Clone_EvictsLeastRecentlyUsedIdleWorkspaces(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := repositoryMocks.NewMockWorkspaceRepository(ctrl)
+	var checkouts int
+	manager := newTestWorkspaceManager(t, repo, 100, &checkouts)
+	oldest := addWorkspaceDir(t, manager, models.Workspace{WorkspaceID: "ws-1", SizeBytes: 60, Status: models.WorkspaceStatusIdle})
+	inUse := addWorkspaceDir(t, manager, models.Workspace{WorkspaceID: "ws-2", SizeBytes: 50, Status: models.WorkspaceStatusInUse})
+	newest := addWorkspaceDir(t, manager, models.Workspace{WorkspaceID: "ws-3", SizeBytes: 10, Status: models.WorkspaceStatusIdle})
+
+	repo.EXPECT().ListWorkspaces(gomock.Any(), "runner-1").Return([]models.Workspace{oldest, inUse, newest}, nil)
+	repo.EXPECT().DeleteWorkspace(gomock.Any(), "ws-1").Return(nil)
+	var created *models.Workspace
+	repo.EXPECT().CreateWorkspace(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, workspace *models.Workspace) error {
+		created = workspace
+		return nil
+	})
+
+	dir, release, err := manager.Clone(context.Background(), "task-1", &models.Codebase{CodebaseID: "cb-1"}, "main", "")
+	require.NoError(t, err)
+
+	require.NotNil(t, created)
+	assert.Equal(t, filepath.Join(created.Path, workspaceRepoDir), dir)
+	assert.Equal(t, "abc123", created.CommitSHA)
+	assert.Equal(t, models.WorkspaceStatusInUse, created.Status)
+	assert.Equal(t, 1, checkouts)
+	assert.NoDirExists(t, oldest.Path)
+	assert.DirExists(t, newest.Path)
+
+	// The clone's size is recorded once it is released
+	repo.EXPECT().ReleaseWorkspace(gomock.Any(), created.WorkspaceID, int64(10)).Return(nil)
+	release()
+}
+
+func TestWorkspaceManager_Clone_RefusesWhenWorkspacesInUseExceedQuota(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := repositoryMocks.NewMockWorkspaceRepository(ctrl)
+	var checkouts int
+	manager := newTestWorkspaceManager(t, repo, 100, &checkouts)
+	inUse := addWorkspaceDir(t, manager, models.Workspace{WorkspaceID: "ws-1", SizeBytes: 100, Status: models.WorkspaceStatusInUse})
+
+	repo.EXPECT().ClaimIdleWorkspace(gomock.Any(), "runner-1", "cb-1", "abc123", "task-1").Return(nil, nil)
+	repo.EXPECT().ListWorkspaces(gomock.Any(), "runner-1").Return([]models.Workspace{inUse}, nil)
+
+	_, _, err := manager.Clone(context.Background(), "task-1", &models.Codebase{CodebaseID: "cb-1"}, "", "abc123")
+
+	assert.ErrorContains(t, err, "quota")
+	assert.Zero(t, checkouts)
+	assert.DirExists(t, inUse.Path)
+}
+
+func TestWorkspaceManager_CollectGarbage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := repositoryMocks.NewMockWorkspaceRepository(ctrl)
+	var checkouts int
+	manager := newTestWorkspaceManager(t, repo, 1<<20, &checkouts)
+	now := time.Now()
+
+	interrupted := addWorkspaceDir(t, manager, models.Workspace{WorkspaceID: "ws-interrupted", Status: models.WorkspaceStatusInUse, LastUsedAt: manager.startedAt.Add(-time.Minute)})
+	running := addWorkspaceDir(t, manager, models.Workspace{WorkspaceID: "ws-running", Status: models.WorkspaceStatusInUse, LastUsedAt: now})
+	expired := addWorkspaceDir(t, manager, models.Workspace{WorkspaceID: "ws-expired", Status: models.WorkspaceStatusIdle, LastUsedAt: now.Add(-2 * time.Hour)})
+	fresh := addWorkspaceDir(t, manager, models.Workspace{WorkspaceID: "ws-fresh", Status: models.WorkspaceStatusIdle, LastUsedAt: now})
+	missing := models.Workspace{WorkspaceID: "ws-missing", Path: filepath.Join(manager.root, "ws-missing"), Status: models.WorkspaceStatusIdle, LastUsedAt: now}
+	orphan := addWorkspaceDir(t, manager, models.Workspace{WorkspaceID: "ws-orphan"})
+	cloning := addWorkspaceDir(t, manager, models.Workspace{WorkspaceID: "ws-cloning"})
+	manager.cloning[cloning.Path] = true
+
+	repo.EXPECT().ListWorkspaces(gomock.Any(), "runner-1").Return([]models.Workspace{interrupted, expired, missing, running, fresh}, nil)
+	repo.EXPECT().DeleteWorkspace(gomock.Any(), "ws-interrupted").Return(nil)
+	repo.EXPECT().DeleteWorkspace(gomock.Any(), "ws-expired").Return(nil)
+	repo.EXPECT().DeleteWorkspace(gomock.Any(), "ws-missing").Return(nil)
+
+	require.NoError(t, manager.CollectGarbage(context.Background()))
+
+	assert.NoDirExists(t, interrupted.Path)
+	assert.NoDirExists(t, expired.Path)
+	assert.NoDirExists(t, orphan.Path)
+	assert.DirExists(t, running.Path)
+	assert.DirExists(t, fresh.Path)
+	assert.DirExists(t, cloning.Path)
+}
+
+func TestWorkspaceManager_GetUsage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := repositoryMocks.NewMockWorkspaceRepository(ctrl)
+	var checkouts int
+	manager := newTestWorkspaceManager(t, repo, 1000, &checkouts)
+	workspaces := []models.Workspace{
+		{WorkspaceID: "ws-1", SizeBytes: 300, Status: models.WorkspaceStatusIdle},
+		{WorkspaceID: "ws-2", SizeBytes: 200, Status: models.WorkspaceStatusInUse},
+	}
+	repo.EXPECT().ListWorkspaces(gomock.Any(), "runner-1").Return(workspaces, nil)
+
+	usage, err := manager.GetUsage(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1000), usage.QuotaBytes)
+	assert.Equal(t, int64(500), usage.UsedBytes)
+	assert.Equal(t, int64(200), usage.InUseBytes)
+	assert.Equal(t, int64(300), usage.IdleBytes)
+	assert.Equal(t, workspaces, usage.Workspaces)
+}
@@ -0,0 +1,19 @@
+package services
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// WorkspaceService defines the interface for the workspaces codebases are cloned into on this task runner
+//
+//go:generate mockgen -destination=./mocks/mock_workspace_service.go -mock_names=WorkspaceService=MockWorkspaceService -package=mocks . WorkspaceService
+type WorkspaceService interface {
+	// GetUsage reports the disk used by the workspaces of this task runner
+	GetUsage(ctx context.Context) (*models.GetWorkspaceUsageResponse, error)
+
+	// CollectGarbage removes the workspace directories without a record, the records without a directory, the
+	// workspaces left in use by a previous run and the workspaces idle for longer than their TTL
+	CollectGarbage(ctx context.Context) error
+}
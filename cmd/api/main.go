@@ -2,27 +2,43 @@
 package main
 
 import (
+	"cmp"
 	"context"
 	"log/slog"
+	"maps"
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
 	"syscall"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/gin-gonic/gin"
-	swaggerFiles "github.com/swaggo/files"
-	ginSwagger "github.com/swaggo/gin-swagger"
 
 	"github.com/kazemisoroush/code-refactoring-tool/api/controllers"
 	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
 	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
 	"github.com/kazemisoroush/code-refactoring-tool/api/routes"
 	"github.com/kazemisoroush/code-refactoring-tool/api/services"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/agent"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/contextpack"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/storage"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/analyzer"
 	"github.com/kazemisoroush/code-refactoring-tool/pkg/auth"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/cache"
 	appconfig "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/dependency"
 	"github.com/kazemisoroush/code-refactoring-tool/pkg/factory"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/gitprovider"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/jira"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/notification"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/objectstore"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/resilience"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/scan"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/startup"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/workflow"
 )
 
 // @title Code Refactor Tool API
@@ -38,7 +54,12 @@ import (
 // @license.url http://www.apache.org/licenses/LICENSE-2.0.html
 
 // @host localhost:8080
-// @BasePath /api/v1
+// @BasePath /
+
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name Authorization
+// @description Cognito access token or service account token, sent as "Bearer <token>"
 
 func main() {
 	// Load configuration
@@ -48,9 +69,9 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Give outstanding requests a deadline for completion
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), time.Duration(cfg.TimeoutSeconds)*time.Second)
-	defer shutdownCancel()
+	// Bound the AWS calls made while starting up
+	startupCtx, startupCancel := context.WithTimeout(context.Background(), time.Duration(cfg.TimeoutSeconds)*time.Second)
+	defer startupCancel()
 
 	// Initialize Postgres config for repositories
 	postgresConfig := repository.PostgresConfig{
@@ -60,142 +81,664 @@ func main() {
 		Username: cfg.Postgres.Username,
 		Password: cfg.Postgres.Password,
 		SSLMode:  cfg.Postgres.SSLMode,
-	}
 
-	// Initialize agent repository
-	agentRepository, err := repository.NewPostgresAgentRepository(postgresConfig, appconfig.DefaultAgentsTableName)
-	if err != nil {
-		slog.Error("failed to initialize agent repository", "error", err)
+		ReplicaHost:          cfg.Postgres.ReplicaHost,
+		ReplicaPort:          cfg.Postgres.ReplicaPort,
+		ReplicaRetryInterval: cfg.Postgres.ReplicaRetryInterval,
 	}
 
-	// Initialize project repository
-	projectRepository, err := repository.NewPostgresProjectRepository(postgresConfig, appconfig.DefaultProjectsTableName)
-	if err != nil {
-		slog.Error("failed to initialize project repository", "error", err)
+	// Check every dependency before starting, reporting all failures at once. Degraded, the service starts without
+	// the dependencies other than Postgres that failed, with their routes disabled.
+	startupReport := startup.Run(startupCtx, startupChecks(&cfg, postgresConfig), cfg.Startup.CheckTimeout)
+	startupReport.Log()
+	if err := startupReport.Err(cfg.Startup.Degraded); err != nil {
+		slog.Error("failed to start", "error", err, "degraded", cfg.Startup.Degraded)
 		os.Exit(1)
 	}
+	if len(startupReport.Failures) > 0 {
+		slog.Warn("starting degraded", "unavailable", startupReport.Dependencies())
+	}
 
-	// Initialize codebase repository
-	codebaseRepository, err := repository.NewPostgresCodebaseRepository(postgresConfig, appconfig.DefaultCodebasesTableName)
+	// Choose the storage backend before building any repository, so Postgres is only connected to when it is used
+	repos, closeRepositories, err := newRepositories(startupCtx, &cfg, postgresConfig)
 	if err != nil {
-		slog.Error("failed to initialize codebase repository", "error", err)
+		slog.Error("failed to initialize repositories", "error", err, "storage_backend", cfg.StorageBackend)
 		os.Exit(1)
 	}
+	defer closeRepositories()
+
+	// Hot reads are cached in Redis when it is configured, falling back to Postgres whenever it can't be reached
+	if cfg.Cache.Enabled() {
+		redisCache := cache.NewRedisCache(cache.RedisOptions{
+			Address:  cfg.Cache.RedisAddress,
+			Password: cfg.Cache.RedisPassword,
+			DB:       cfg.Cache.RedisDB,
+			TLS:      cfg.Cache.RedisTLS,
+			Timeout:  cfg.Cache.Timeout,
+		})
+		if err := redisCache.Ping(startupCtx); err != nil {
+			slog.Warn("redis cache unreachable, reads fall back to the database until it is", "error", err)
+		}
+		repos.project = repository.NewCachedProjectRepository(repos.project, redisCache, cfg.Cache.ProjectTTL)
+		repos.codebaseConfig = repository.NewCachedCodebaseConfigRepository(repos.codebaseConfig, redisCache, cfg.Cache.CodebaseConfigTTL)
+		repos.user = repository.NewCachedUserRepository(repos.user, redisCache, cfg.Cache.UserTTL)
+	}
+
+	// Agent setups and task executions persist their progress so a restart can resume or roll them back
+	workflowEngine := workflow.NewEngine(repos.workflowRun, cfg.Workflow.RetryBackoff)
+
+	// Repository content is scanned for secrets and incompatible licenses before it is ingested
+	contentScanner := scan.NewScanner(cfg.IngestionScan.IncompatibleLicenses)
 
-	// Initialize task repository
-	taskRepository, err := repository.NewPostgresTaskRepository(postgresConfig, appconfig.DefaultTasksTableName)
+	// Initialize metrics middleware
+	metricsMiddleware, err := middleware.NewMetricsMiddleware(appconfig.MetricsConfig{
+		Namespace:   cfg.Metrics.Namespace,
+		Region:      cfg.Metrics.Region,
+		ServiceName: cfg.Metrics.ServiceName,
+		Enabled:     cfg.Metrics.Enabled,
+	})
 	if err != nil {
-		slog.Error("failed to initialize task repository", "error", err)
+		slog.Error("failed to initialize metrics middleware", "error", err)
 		os.Exit(1)
 	}
 
-	// Initialize user repository
-	userRepository, err := repository.NewPostgresUserRepository(postgresConfig, appconfig.DefaultUsersTableName)
-	if err != nil {
-		slog.Error("failed to initialize user repository", "error", err)
-		os.Exit(1)
+	// Calls to Cognito, Bedrock, S3 and the git providers are retried when they fail transiently, and failed fast
+	// while a circuit breaker is open; breaker state changes are reported as a metric of the dependency
+	resilienceRegistry := resilience.NewRegistry(resilience.Policy{
+		MaxAttempts:      cfg.Resilience.MaxAttempts,
+		BaseDelay:        cfg.Resilience.BaseDelay,
+		MaxDelay:         cfg.Resilience.MaxDelay,
+		FailureThreshold: cfg.Resilience.FailureThreshold,
+		OpenTimeout:      cfg.Resilience.OpenTimeout,
+	}, func(name string, state resilience.State) {
+		go func() {
+			if err := metricsMiddleware.SendCustomMetric("CircuitBreakerState", float64(state), "None", map[string]string{"Dependency": name}); err != nil {
+				slog.Warn("failed to send circuit breaker state metric", "dependency", name, "error", err)
+			}
+		}()
+	})
+
+	// Concurrent calls to each model are bounded, and fewer are let through while the model throttles them; how long
+	// calls wait for their turn is reported as a metric of the model
+	modelLimiters := resilience.NewLimiters(resilience.LimitPolicy{
+		DefaultCeiling: cfg.AI.Limits.DefaultCeiling,
+		Ceilings:       cfg.AI.Limits.Ceilings,
+		MaxQueue:       cfg.AI.Limits.MaxQueue,
+		MaxRetries:     cfg.AI.Limits.MaxRetries,
+		BaseDelay:      cfg.AI.Limits.BaseDelay,
+		MaxDelay:       cfg.AI.Limits.MaxDelay,
+	}, func(name string, wait time.Duration) {
+		go func() {
+			if err := metricsMiddleware.SendCustomMetric("ModelConcurrencyWait", float64(wait.Milliseconds()), "Milliseconds", map[string]string{"Model": name}); err != nil {
+				slog.Warn("failed to send model concurrency wait metric", "model", name, "error", err)
+			}
+		}()
+	})
+	newOllamaChatModel := func(model string) agent.ChatModel {
+		return agent.NewLimitedChatModel(agent.NewOllamaChatModel(cfg.AI.Local.OllamaURL, model), modelLimiters.Limiter("ollama", model, agent.IsOllamaThrottled))
 	}
 
-	// Initialize codebase configuration repository
-	codebaseConfigRepository, err := repository.NewPostgresCodebaseConfigRepository(postgresConfig, appconfig.DefaultCodebaseConfigsTableName)
+	// Repositories are uploaded to the data stores several files at a time; the throughput of each upload is reported
+	uploadPipeline := storage.NewUploadPipeline(cfg.AI.Upload, func(stats storage.UploadStats) {
+		go func() {
+			metrics := []struct {
+				name  string
+				value float64
+				unit  string
+			}{
+				{"UploadedFiles", float64(stats.Files), "Count"},
+				{"UploadedBytes", float64(stats.Bytes), "Bytes"},
+				{"UploadRetries", float64(stats.Retries), "Count"},
+				{"UploadDuration", float64(stats.Duration.Milliseconds()), "Milliseconds"},
+				{"UploadThroughput", stats.Throughput(), "Bytes/Second"},
+			}
+			for _, metric := range metrics {
+				if err := metricsMiddleware.SendCustomMetric(metric.name, metric.value, metric.unit, nil); err != nil {
+					slog.Warn("failed to send upload metric", "metric", metric.name, "error", err)
+				}
+			}
+		}()
+	})
+
+	// Bedrock agents are provisioned and synced with the clients of their region, shared across requests
+	regionalClients := factory.NewRegionalClients(cfg.AWSConfig, resilienceRegistry, uploadPipeline)
+	// Large task inputs and the archives of uploaded codebases are stored in the uploads bucket
+	uploadStore := objectstore.NewS3ObjectStore(cfg.AWSConfig, cfg.UploadsBucket())
+	// Local knowledge bases keep the content they ingest in the configured store, such as a directory when air-gapped
+	contentStore, err := storage.NewDataStore(cfg.AI.Local.DataStore, cfg.AWSConfig, uploadPipeline)
 	if err != nil {
-		slog.Error("failed to initialize codebase configuration repository", "error", err)
+		slog.Error("failed to create data store", "error", err)
 		os.Exit(1)
 	}
-
-	aiInfraFactory := factory.NewAIInfrastructureFactory(cfg.AWSConfig, cfg.AI, cfg.Git)
+	aiInfraFactory := factory.NewAIInfrastructureFactory(regionalClients, cfg.AI, cfg.Git, contentScanner, uploadStore, cfg.CodebaseUploads, contentStore, workflowEngine)
+
+	// Email notifications are only sent when a sender address is configured
+	var emailSender notification.EmailSender
+	if cfg.Notifications.EmailFrom != "" {
+		sesConfig, err := config.LoadDefaultConfig(startupCtx, config.WithRegion(cfg.Notifications.SESRegion))
+		if err != nil {
+			slog.Error("failed to load AWS config for SES", "error", err)
+			os.Exit(1)
+		}
+		emailSender = notification.NewSESEmailSender(sesConfig, cfg.Notifications.EmailFrom)
+	}
 
 	// Initialize services with full dependency injection
-	projectService := services.NewDefaultProjectService(projectRepository)
-	codebaseService := services.NewDefaultCodebaseService(codebaseRepository)
-	codebaseConfigService := services.NewDefaultCodebaseConfigService(codebaseConfigRepository)
-	healthService := services.NewDefaultHealthService("code-refactor-tool-api", "1.0.0")
+	// The tags set on projects, codebases and codebase configurations are checked against the tag key registry
+	tagService := services.NewDefaultTagService(repos.tagKey, repos.user, cfg.Tags.RequireRegisteredKeys)
+	codebaseService := services.NewDefaultCodebaseService(repos.codebase, tagService, repos.clientToken, uploadStore)
+	codebaseConfigService := services.NewDefaultCodebaseConfigService(repos.codebaseConfig, tagService)
+	// Installation tokens of GitHub Apps are cached until shortly before they expire, across codebases and check runs
+	gitHubAppTokens := gitprovider.NewGitHubAppTokens(gitprovider.GitHubAPIURL, cfg.CodebaseBrowsing.RequestTimeout)
+	codebaseBrowseService := services.NewDefaultCodebaseBrowseService(
+		repos.codebase,
+		repos.codebaseConfig,
+		map[models.Provider]gitprovider.Browser{
+			models.ProviderGitHub: gitprovider.NewResilientBrowser(gitprovider.NewGitHubBrowser(cfg.CodebaseBrowsing.RequestTimeout),
+				resilienceRegistry.Guard("github", gitprovider.IsTransient)),
+			models.ProviderGitLab: gitprovider.NewResilientBrowser(gitprovider.NewGitLabBrowser(cfg.CodebaseBrowsing.RequestTimeout),
+				resilienceRegistry.Guard("gitlab", gitprovider.IsTransient)),
+			models.ProviderAzureDevOps: gitprovider.NewResilientBrowser(gitprovider.NewAzureDevOpsBrowser(cfg.CodebaseBrowsing.RequestTimeout),
+				resilienceRegistry.Guard("azure_devops", gitprovider.IsTransient)),
+		},
+		gitHubAppTokens,
+		cfg.CodebaseBrowsing.CacheTTL,
+	)
+	projectTemplateService := services.NewDefaultProjectTemplateService(repos.projectTemplate, repos.project, repos.projectAutomation, tagService)
+	healthService := services.NewDefaultHealthService("code-refactor-tool-api", "1.0.0", startupReport.Dependencies()...)
+
+	notificationService := services.NewDefaultNotificationService(
+		repos.notificationChannel,
+		repos.userNotification,
+		repos.notificationOutbox,
+		repos.project,
+		emailSender,
+		notification.NewHTTPSlackSender(),
+		repos.clientToken,
+	)
+
+	// Repository content is redacted with its project's policy before it is embedded
+	redactionService := services.NewDefaultRedactionService(repos.redactionPolicy, repos.redactionAudit, repos.project)
+
+	// The S3 content of deleted agents is purged in the background, and content no agent owns is reported
+	storageLifecycleService := services.NewDefaultStorageLifecycleService(repos.storagePurge, repos.agent,
+		regionalClients.DataStore, cfg.AI.Bedrock, cfg.StorageLifecycle)
 
-	// Initialize agent service with infrastructure factory
+	// Without Postgres, provisioning failures aren't notified and the content of deleted agents isn't purged
+	var agentNotifier services.Notifier
+	var agentStorageLifecycle services.StorageLifecycleService
+	if repos.postgres {
+		agentNotifier = notificationService
+		agentStorageLifecycle = storageLifecycleService
+	}
+
+	// Initialize agent service with infrastructure factory, Bedrock agents embedding with their project's model
 	agentService := services.NewDefaultAgentService(
-		agentRepository,
+		repos.agent,
 		aiInfraFactory,
+		agentNotifier,
+		redactionService,
+		workflowEngine,
+		agentStorageLifecycle,
+		repos.project,
+	)
+
+	// Initialize role service evaluating the permissions of callers, within projects for the project routes
+	roleService := services.NewDefaultRoleService(repos.role, repos.user, repos.project, repos.auditEvent)
+
+	// Archiving a project with its artifacts purged deletes its agents along with their AI resources, switching its
+	// embedding model re-embeds them, and the creator of a project becomes its owner
+	projectService := services.NewDefaultProjectService(repos.project, repos.agent, agentService, tagService, roleService, repos.clientToken)
+
+	// Agent knowledge bases are resynced with Bedrock ingestion jobs
+	agentSyncService := services.NewDefaultAgentSyncService(repos.agent, repos.project, regionalClients.Ingester)
+	agentRetrievalService := services.NewDefaultAgentRetrievalService(repos.agent, roleService, regionalClients.Retriever)
+
+	// Agents are rebuilt from a fresh clone once new commits on their codebase's default branch settle
+	agentResyncService := services.NewDefaultAgentResyncService(
+		repos.agentResyncSettings,
+		repos.codebaseHead,
+		repos.project,
+		repos.codebase,
+		repos.agent,
+		codebaseBrowseService,
+		agentService,
+		cfg.AgentResync.Debounce,
 	)
 
+	// Codebases are cloned into workspaces kept within the disk quota and reused by analyses of the same commit
+	codebaseCloner, err := services.NewWorkspaceManager(repos.workspace, repos.codebaseConfig, uploadStore, cfg.Git, cfg.CodebaseUploads, cfg.Workspace)
+	if err != nil {
+		slog.Error("failed to initialize workspace manager", "error", err)
+		os.Exit(1)
+	}
+	codebaseUploadService := services.NewDefaultCodebaseUploadService(repos.codebase, tagService, uploadStore, codebaseCloner, cfg.CodebaseUploads)
+
+	dependencyAuditService := services.NewDefaultDependencyAuditService(
+		repos.dependencyFinding,
+		repos.task,
+		repos.codebase,
+		codebaseCloner,
+		dependency.NewOSVSource(cfg.DependencyAudit.AdvisoryURL, cfg.DependencyAudit.RequestTimeout),
+	)
+
+	// Coverage gap tasks push the tests agents write for them to a branch and open a pull request
+	coverageGapService := services.NewDefaultCoverageGapService(codebaseCloner, repos.codebaseConfig, cfg.Git, cfg.CoverageGap)
+
+	codeMetricsService := services.NewDefaultCodeMetricsService(repos.codeMetrics, repos.codebase)
+	// Findings, tasks and their notifications are routed to the owners of their files in the codebase's CODEOWNERS file
+	codeOwnersService := services.NewDefaultCodeOwnersService(repos.codeOwners, repos.codebase)
+	// Analyses record the churn, authors and age of files, so hot spots are refactored first
+	fileHistoryService := services.NewDefaultFileHistoryService(repos.fileHistory)
+	findingService := services.NewDefaultFindingService(repos.finding, repos.codebase, repos.qualityGatePolicy, repos.codeOwners)
+	qualityGateService := services.NewDefaultQualityGateService(repos.qualityGatePolicy, repos.project, repos.finding, repos.codeMetrics)
+
+	ingestionScanService := services.NewDefaultIngestionScanService(repos.scanFinding, repos.codebase, codebaseCloner, contentScanner, codeOwnersService)
+
+	// LLM calls made while executing tasks are logged when enabled, without their content for the projects whose
+	// redaction policy disables it
+	llmTraceService := services.NewDefaultLLMTraceService(repos.llmInteraction, repos.task, roleService, redactionService, cfg.LLMLog)
+
+	// Without Postgres, tasks run without the analyses, quality gates, Jira links and LLM call log only it stores
+	var (
+		taskAuditor  services.DependencyAuditService
+		taskMetrics  services.CodeMetricsService
+		taskFindings services.FindingService
+		taskGates    services.QualityGateService
+		taskOwners   services.CodeOwnersService
+		taskHistory  services.FileHistoryService
+		taskJira     services.JiraService
+		taskLLMTrace services.LLMTraceService
+	)
+	if repos.postgres {
+		taskAuditor = dependencyAuditService
+		taskMetrics = codeMetricsService
+		taskFindings = findingService
+		taskGates = qualityGateService
+		taskOwners = codeOwnersService
+		taskHistory = fileHistoryService
+		taskLLMTrace = llmTraceService
+	}
+
+	// Register the task executors, routing the configured task types to the external ones
+	taskExecutors := services.NewTaskExecutorRegistry(cfg.Task.ExecutorRoutes)
+	// Codemods come first, since the agent executors support every task type
+	executors := []services.TaskExecutor{services.NewCodemodTaskExecutor(codebaseCloner)}
+	if cfg.AI.Local.Enabled {
+		// Ahead of the agent executor, so the local model runs the task types it supports. Task prompts are packed under
+		// the budget of each model, with the chunks retrieved from the knowledge base of the task's agent.
+		contextRegistry := contextpack.DefaultRegistry()
+		contextAssembler := contextpack.NewAssembler(contextRegistry)
+		contextRetriever := services.NewKnowledgeBaseContextRetriever(repos.agent, regionalClients.Retriever)
+		contextFilter := services.NewProjectContextFilter(redactionService)
+		var contextHistory services.TaskContextHistory
+		if taskHistory != nil {
+			contextHistory = services.NewFileHistoryContext(taskHistory)
+		}
+		newLocalAgent := func(model, promptVersion string) (services.TaskExecutor, error) {
+			if window := cfg.AI.Local.ContextWindow; window > 0 {
+				contextRegistry.Register(model, contextpack.NewModelLimits(contextRegistry.Lookup(model).Tokenizer, window))
+			}
+			return services.NewLocalAgentTaskExecutor(
+				newOllamaChatModel(model),
+				services.TaskContext{
+					Model:     model,
+					Assembler: contextAssembler,
+					Retrieve:  contextRetriever,
+					Chunks:    cfg.AI.Local.ContextChunks,
+					Filter:    contextFilter,
+					History:   contextHistory,
+				},
+				codebaseCloner,
+				taskLLMTrace,
+				promptVersion,
+				cfg.AI.Local.MaxSteps,
+				cfg.AI.Local.MaxRepairs,
+				cfg.AI.Local.TestCommand,
+			)
+		}
+		localAgent, err := newLocalAgent(cfg.AI.Local.Model, cfg.AI.Local.PromptVersion)
+		if err != nil {
+			slog.Error("failed to initialize local agent", "error", err)
+			os.Exit(1)
+		}
+		// Running experiments route a share of the local agent's tasks to their variant, and tasks asking for the auto
+		// model run on the cheap or the strong model instead. Experiments are only stored in Postgres.
+		experimentAgent := localAgent
+		if repos.postgres {
+			experimentAgent = services.NewExperimentTaskExecutor(localAgent, newLocalAgent, repos.experiment, repos.task)
+		}
+		routing := services.ModelRouting{
+			CheapModel:           cmp.Or(cfg.AI.Local.Routing.CheapModel, cfg.AI.Local.Model),
+			StrongModel:          cmp.Or(cfg.AI.Local.Routing.StrongModel, cfg.AI.Local.Model),
+			MaxDiffLines:         cfg.AI.Local.Routing.MaxDiffLines,
+			MaxComplexity:        cfg.AI.Local.Routing.MaxComplexity,
+			MaxAverageComplexity: cfg.AI.Local.Routing.MaxAverageComplexity,
+		}
+		executors = append(executors, services.NewModelRoutingTaskExecutor(
+			experimentAgent,
+			func(model string) (services.TaskExecutor, error) {
+				return newLocalAgent(model, cfg.AI.Local.PromptVersion)
+			},
+			repos.codeMetrics,
+			routing,
+		))
+	}
+	executors = append(executors, services.NewAgentTaskExecutor())
+	for _, name := range slices.Sorted(maps.Keys(cfg.Task.Executors)) {
+		var taskTypes []models.TaskType
+		for taskType, executor := range cfg.Task.ExecutorRoutes {
+			if executor == name {
+				taskTypes = append(taskTypes, models.TaskType(taskType))
+			}
+		}
+		executors = append(executors, services.NewHTTPTaskExecutor(name, cfg.Task.Executors[name], taskTypes))
+	}
+	for _, executor := range executors {
+		if err := taskExecutors.Register(executor); err != nil {
+			slog.Error("failed to register task executor", "error", err)
+			os.Exit(1)
+		}
+	}
+	if err := taskExecutors.Validate(); err != nil {
+		slog.Error("invalid task executor configuration", "error", err)
+		os.Exit(1)
+	}
+
+	// Initialize the uploads of large task inputs, streamed to S3 and referenced by the tasks
+	uploadService := services.NewDefaultUploadService(uploadStore, cfg.Uploads)
+
+	jiraService := services.NewDefaultJiraService(repos.jiraSite, repos.jiraIssueLink, jira.NewHTTPIssueTracker(cfg.Jira.RequestTimeout))
+	if repos.postgres {
+		taskJira = jiraService
+	}
 	taskService := services.NewTaskService(
-		taskRepository,
-		projectRepository,
-		agentRepository,
-		codebaseRepository,
+		repos.task,
+		repos.project,
+		repos.agent,
+		repos.codebase,
+		roleService,
+		codebaseBrowseService,
+		codebaseCloner,
+		map[models.AnalysisMode]analyzer.Analyzer{
+			models.AnalysisModeDuplicateCode: analyzer.NewDuplicateCodeAnalyzer(cfg.CodeAnalysis.DuplicateMinTokens),
+			models.AnalysisModeDeadCode:      analyzer.NewDeadCodeAnalyzer(),
+			models.AnalysisModePackageStructure: analyzer.NewPackageStructureAnalyzer(
+				cfg.CodeAnalysis.GodPackageMinDeclarations,
+				cfg.CodeAnalysis.GodPackageMinLines,
+			),
+		},
+		taskAuditor,
+		coverageGapService,
+		taskMetrics,
+		taskFindings,
+		taskGates,
+		taskOwners,
+		taskHistory,
+		taskJira,
+		uploadService,
+		taskExecutors,
+		workflowEngine,
+		cfg.Task,
+	)
+
+	campaignService := services.NewDefaultCampaignService(
+		repos.campaign,
+		repos.task,
+		repos.project,
+		repos.codebase,
+		repos.agent,
+		taskService,
+	)
+
+	taskCommentService := services.NewDefaultTaskCommentService(repos.taskComment, repos.task)
+	taskFeedbackService := services.NewDefaultTaskFeedbackService(repos.taskFeedback, repos.task)
+	gitHubCheckService := services.NewDefaultGitHubCheckService(
+		repos.gitHubAppConfig,
+		repos.gitHubCheck,
+		repos.codebase,
+		taskService,
+		gitprovider.NewGitHubChecksClient(cfg.GitHubChecks.APIURL, gitHubAppTokens, cfg.GitHubChecks.RequestTimeout),
+		cfg.GitHubChecks,
+	)
+
+	projectManifestService := services.NewDefaultProjectManifestService(
+		repos.project,
+		repos.codebase,
+		repos.codebaseConfig,
+		repos.agent,
+		repos.projectAutomation,
+		agentService,
+		tagService,
 	)
 
+	projectSummaryService := services.NewDefaultProjectSummaryService(
+		repos.project,
+		repos.codebase,
+		repos.task,
+	)
+
+	reportService := services.NewDefaultReportService(repos.taskMetrics, cfg.Reports.LookbackDays)
+
+	refreshCtx, stopRefresh := context.WithCancel(context.Background())
+	defer stopRefresh()
+
+	// The background jobs of the features only Postgres stores run until shutdown
+	if repos.postgres {
+		// Refresh the task metrics rollups
+		go reportService.RunTaskMetricsRefresh(refreshCtx, cfg.Reports.RefreshInterval)
+
+		// Deliver the notifications recorded in the outbox
+		go notificationService.RunOutboxDispatch(refreshCtx, cfg.Notifications.OutboxPollInterval)
+
+		// Publish the results of pull request tasks to their GitHub check runs
+		go gitHubCheckService.RunCheckPublishing(refreshCtx, cfg.GitHubChecks.PollInterval)
+
+		// Report the status changes of tasks on their Jira issues
+		go jiraService.RunIssueSync(refreshCtx, cfg.Jira.SyncInterval)
+
+		// Purge the S3 content of deleted agents, and report the content no agent owns
+		go storageLifecycleService.RunPurgeQueue(refreshCtx, cfg.StorageLifecycle.PurgeInterval)
+		if cfg.StorageLifecycle.ReconcileInterval > 0 {
+			go storageLifecycleService.RunReconciliation(refreshCtx, cfg.StorageLifecycle.ReconcileInterval)
+		}
+
+		// Delete the LLM calls past their retention
+		if cfg.LLMLog.Enabled {
+			go llmTraceService.RunPurge(refreshCtx, cfg.LLMLog.PurgeInterval)
+		}
+
+		// Watch the codebases agents are built from
+		if cfg.AgentResync.PollInterval > 0 {
+			go agentResyncService.RunCodebaseWatch(refreshCtx, cfg.AgentResync.PollInterval)
+		}
+	}
+
+	// Remove orphaned and expired workspaces in the background until shutdown
+	go codebaseCloner.RunGarbageCollection(refreshCtx, cfg.Workspace.GCInterval)
+
+	// Delete the uploaded codebases past their expiry, with their archives, in the background until shutdown
+	go codebaseUploadService.RunExpirySweep(refreshCtx, cfg.CodebaseUploads.SweepInterval)
+
+	// Run the queued tasks in the background until shutdown
+	if cfg.Task.DispatchInterval > 0 {
+		go taskService.RunTaskDispatcher(refreshCtx, cfg.Task.DispatchInterval, cfg.Task.Workers)
+	}
+
+	// Fail or requeue the tasks whose executions stopped sending heartbeats in the background until shutdown
+	if cfg.Task.JanitorInterval > 0 {
+		go taskService.RunStuckTaskJanitor(refreshCtx, cfg.Task.JanitorInterval)
+	}
+
+	// Resume the task executions and roll back the agent setups the last shutdown interrupted
+	go func() {
+		if err := taskService.ResumeInterruptedTasks(refreshCtx); err != nil {
+			slog.Error("failed to resume interrupted tasks", "error", err)
+		}
+		if err := aiInfraFactory.RecoverInterruptedSetups(refreshCtx); err != nil {
+			slog.Error("failed to roll back interrupted agent setups", "error", err)
+		}
+	}()
+
 	projectController := controllers.NewProjectController(projectService)
-	codebaseController := controllers.NewCodebaseController(codebaseService)
+	tagController := controllers.NewTagController(tagService)
+	roleController := controllers.NewRoleController(roleService)
+	projectMemberController := controllers.NewProjectMemberController(roleService)
+	complianceController := controllers.NewComplianceController(services.NewDefaultComplianceService(repos.user, repos.role, repos.auditEvent))
+	evalController := controllers.NewEvalController(services.NewDefaultEvalService(repos.evalRun, newOllamaChatModel, cfg.Eval, cfg.AI.Local))
+	experimentController := controllers.NewExperimentController(services.NewDefaultExperimentService(repos.experiment, repos.task, cfg.AI.Local))
+
+	// Pull request descriptions are generated by the local model when it's enabled, or else the Bedrock foundation model
+	var generationModel agent.Agent
+	generationModelName := cfg.AI.Local.Model
+	if cfg.AI.Local.Enabled {
+		generationModel = agent.NewLimitedAgent(agent.NewOllamaAgent(cfg.AI.Local.OllamaURL, generationModelName),
+			modelLimiters.Limiter("ollama", generationModelName, agent.IsOllamaThrottled))
+	} else {
+		generationModelName = cfg.AI.Bedrock.FoundationModel
+		generationModel = agent.NewResilientAgent(agent.NewLimitedAgent(agent.NewAWSBedrockFMAgent(cfg.AWSConfig, generationModelName),
+			modelLimiters.Limiter("bedrock", generationModelName, resilience.AWSThrottled)), regionalClients.Guard("bedrock", ""))
+	}
+	generationController := controllers.NewGenerationController(services.NewDefaultGenerationService(repos.task, generationModel, generationModelName, cfg.Generation))
+	gitHubCheckController := controllers.NewGitHubCheckController(gitHubCheckService)
+	jiraController := controllers.NewJiraController(jiraService)
+	serviceAccountService := services.NewDefaultServiceAccountService(repos.serviceAccount, repos.user, roleService)
+	serviceAccountController := controllers.NewServiceAccountController(serviceAccountService)
+	storageController := controllers.NewStorageController(storageLifecycleService)
+	projectTemplateController := controllers.NewProjectTemplateController(projectTemplateService)
+	projectManifestController := controllers.NewProjectManifestController(projectManifestService)
+	projectSummaryController := controllers.NewProjectSummaryController(projectSummaryService)
+	redactionController := controllers.NewRedactionController(redactionService)
+	qualityGateController := controllers.NewQualityGateController(qualityGateService)
+	agentResyncController := controllers.NewAgentResyncController(agentResyncService)
+	codebaseController := controllers.NewCodebaseController(codebaseService, codebaseBrowseService, dependencyAuditService, codeMetricsService, ingestionScanService, codebaseUploadService)
 	codebaseConfigController := controllers.NewCodebaseConfigController(codebaseConfigService)
-	taskController := controllers.NewTaskController(taskService)
+	findingController := controllers.NewFindingController(findingService)
+	codeOwnersController := controllers.NewCodeOwnersController(codeOwnersService)
+	taskController := controllers.NewTaskController(taskService, taskCommentService, taskFeedbackService, llmTraceService)
+	campaignController := controllers.NewCampaignController(campaignService)
 	healthController := controllers.NewHealthController(healthService)
 	agentController := controllers.NewAgentController(agentService)
+	agentSyncController := controllers.NewAgentSyncController(agentSyncService)
+	agentRetrievalController := controllers.NewAgentRetrievalController(agentRetrievalService)
+	agentSetupController := controllers.NewAgentSetupController(agentService)
+	reportController := controllers.NewReportController(reportService)
+	notificationController := controllers.NewNotificationController(notificationService)
+	workspaceController := controllers.NewWorkspaceController(codebaseCloner)
 
 	// Initialize AWS config
-	awsConfig, err := config.LoadDefaultConfig(shutdownCtx, config.WithRegion(cfg.Cognito.Region))
+	awsConfig, err := config.LoadDefaultConfig(startupCtx, config.WithRegion(cfg.Cognito.Region))
 	if err != nil {
 		slog.Error("failed to load AWS config", "error", err)
 		os.Exit(1)
 	}
 
 	// Initialize Cognito provider and authentication middleware
-	cognitoProvider := auth.NewCognitoProvider(awsConfig, cfg.Cognito)
+	cognitoProvider := auth.NewResilientProvider(auth.NewCognitoProvider(awsConfig, cfg.Cognito), resilienceRegistry.Guard("cognito", resilience.AWSTransient))
 
 	// Initialize auth service with user repository and auth provider
-	authService := services.NewAuthService(cognitoProvider, userRepository)
+	authService := services.NewAuthService(cognitoProvider, repos.user, repos.mfaRecoveryCode, repos.role)
 
-	// Initialize auth controller
-	authController := controllers.NewAuthController(authService)
+	// Initialize device authorization flow service signing in CLIs
+	deviceAuthService := services.NewDefaultDeviceAuthService(authService, repos.deviceAuthorization, cfg.DeviceAuth)
 
-	authMiddleware := middleware.NewAuthMiddleware(cognitoProvider)
+	// Delete the expired device sign ins in the background until shutdown
+	if repos.postgres {
+		go deviceAuthService.RunPurge(refreshCtx, cfg.DeviceAuth.PurgeInterval)
+	}
 
-	// Initialize metrics middleware
-	metricsMiddleware, err := middleware.NewMetricsMiddleware(appconfig.MetricsConfig{
-		Namespace:   cfg.Metrics.Namespace,
-		Region:      cfg.Metrics.Region,
-		ServiceName: cfg.Metrics.ServiceName,
-		Enabled:     cfg.Metrics.Enabled,
-	})
-	if err != nil {
-		slog.Error("failed to initialize metrics middleware", "error", err)
-		os.Exit(1)
+	// Initialize auth controllers
+	authController := controllers.NewAuthController(authService)
+	deviceAuthController := controllers.NewDeviceAuthController(deviceAuthService)
+
+	// Service account tokens are only accepted when Postgres stores the service accounts
+	var serviceTokens middleware.TokenValidator
+	if repos.postgres {
+		serviceTokens = serviceAccountService
 	}
+	authMiddleware := middleware.NewAuthMiddleware(cognitoProvider, serviceTokens, cfg.Auth)
 
 	// Setup Gin router
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.New()
-	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
 
+	// Tag each request with an ID shared by all its log lines, and log it once it completes
+	router.Use(middleware.NewRequestIDMiddleware().Handle())
+
 	// Add metrics middleware (before auth to capture all requests)
 	router.Use(metricsMiddleware.Handle())
 
-	// Add authentication middleware
-	router.Use(authMiddleware.Handle())
+	// Render errors recorded by handlers as problem+json (inside metrics so the final status is recorded)
+	router.Use(middleware.NewErrorHandlerMiddleware().Handle())
 
-	// Setup project routes with validation middleware
-	routes.SetupProjectRoutes(router, projectController)
+	// Give each request the deadline of its route
+	router.Use(middleware.NewTimeoutMiddleware(cfg.HTTP).Handle())
 
-	// Setup codebase routes with validation middleware
-	routes.SetupCodebaseRoutes(router, codebaseController)
+	// Reject request bodies larger than their route accepts
+	router.Use(middleware.NewBodyLimitMiddleware(cfg.HTTP).Handle())
 
-	// Setup codebase configuration routes with validation middleware
-	routes.SetupCodebaseConfigRoutes(router, codebaseConfigController)
-
-	// Setup agent routes with validation middleware
-	routes.SetupAgentRoutes(router, agentController)
+	// Reject the requests to the routes of the dependencies the service started without, and without Postgres to
+	// those of the features only it stores
+	disabledReport := startupReport
+	if !repos.postgres {
+		disabledReport.Failures = append(slices.Clone(startupReport.Failures), postgresOnlyFailure(cfg.StorageBackend))
+	}
+	router.Use(middleware.NewDegradedMiddleware(disabledReport).Handle())
 
-	// Setup task routes with validation middleware - NEW!
-	routes.SetupTaskRoutes(router, taskController)
+	// Add authentication middleware
+	router.Use(authMiddleware.Handle())
 
-	// Setup auth routes with authentication middleware
-	routes.RegisterAuthRoutes(router, authController, authMiddleware)
+	// Register versioned API routes under /api/{version} with each version's deprecation policy
+	apiRouter := routes.NewVersionedRouterFromConfig(router, cfg.API)
 
-	// Setup health routes with validation middleware
-	routes.SetupHealthRoutes(router, healthController)
+	// Reload the configuration when its remote overrides change, applying the log level and version policies
+	if cfg.Remote.Enabled() && cfg.Remote.RefreshInterval > 0 {
+		configWatcher := appconfig.NewWatcher(cfg, nil)
+		configWatcher.Subscribe(func(reloaded appconfig.Config) {
+			apiRouter.SetPolicies(reloaded.API)
+		})
+		go configWatcher.Run(refreshCtx)
+	}
 
-	// Setup Swagger documentation
-	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	// Mount the versioned API routes and the authentication, webhook, health and documentation routes
+	routes.Mount(apiRouter, routes.Controllers{
+		Project:         projectController,
+		ProjectTemplate: projectTemplateController,
+		ProjectManifest: projectManifestController,
+		ProjectSummary:  projectSummaryController,
+		ProjectMember:   projectMemberController,
+		Redaction:       redactionController,
+		QualityGate:     qualityGateController,
+		AgentResync:     agentResyncController,
+		Codebase:        codebaseController,
+		CodebaseConfig:  codebaseConfigController,
+		Finding:         findingController,
+		CodeOwners:      codeOwnersController,
+		Agent:           agentController,
+		AgentSync:       agentSyncController,
+		AgentRetrieval:  agentRetrievalController,
+		AgentSetup:      agentSetupController,
+		Task:            taskController,
+		Campaign:        campaignController,
+		Report:          reportController,
+		Generation:      generationController,
+		Upload:          controllers.NewUploadController(uploadService),
+		Notification:    notificationController,
+		Role:            roleController,
+		Tag:             tagController,
+		Workspace:       workspaceController,
+		Compliance:      complianceController,
+		Eval:            evalController,
+		Experiment:      experimentController,
+		GitHubCheck:     gitHubCheckController,
+		Jira:            jiraController,
+		ServiceAccount:  serviceAccountController,
+		Storage:         storageController,
+		Auth:            authController,
+		DeviceAuth:      deviceAuthController,
+		Health:          healthController,
+	}, roleService, authMiddleware)
 
 	// Create HTTP server
 	srv := &http.Server{
@@ -217,6 +760,11 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	slog.Info("Shutting down server...")
+	stopRefresh()
+
+	// Give outstanding requests a deadline for completion
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), time.Duration(cfg.TimeoutSeconds)*time.Second)
+	defer shutdownCancel()
 
 	if err := srv.Shutdown(shutdownCtx); err != nil {
 		slog.Error("Server forced to shutdown", "error", err)
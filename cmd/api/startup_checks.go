@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	"github.com/kazemisoroush/code-refactoring-tool/api/routes"
+	appconfig "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/startup"
+)
+
+// startupChecks returns the checks of the dependencies the service needs: Postgres, without which it can't start,
+// and Cognito, the S3 buckets and the Bedrock roles, whose routes are disabled when it starts degraded without them.
+// Postgres isn't checked when the memory or SQLite backend replaces it, nor the Bedrock roles when the local AI stack
+// replaces Bedrock.
+func startupChecks(cfg *appconfig.Config, postgresConfig repository.PostgresConfig) []startup.Check {
+	var checks []startup.Check
+	if cfg.StorageBackend == appconfig.StorageBackendPostgres {
+		checks = append(checks, startup.Check{
+			Name:     "postgres",
+			Hint:     "check POSTGRES_HOST, POSTGRES_PORT, POSTGRES_USERNAME, POSTGRES_PASSWORD and POSTGRES_DATABASE, and that the database accepts connections from this host",
+			Required: true,
+			Run: func(ctx context.Context) error {
+				return repository.PingPostgres(ctx, postgresConfig)
+			},
+		})
+	}
+
+	checks = append(checks, []startup.Check{
+		{
+			Name:   "cognito",
+			Hint:   "check COGNITO_USER_POOL_ID, COGNITO_CLIENT_ID and COGNITO_REGION, and that the credentials may call cognito-idp:DescribeUserPoolClient",
+			Routes: []string{"/auth"},
+			Run: func(ctx context.Context) error {
+				return checkCognito(ctx, cfg.Cognito)
+			},
+		},
+		{
+			Name: "s3",
+			Hint: "check AI_BEDROCK_S3_BUCKET_NAME and UPLOADS_BUCKET name existing buckets, and that the credentials may call s3:ListBucket on them",
+			Routes: versionedRoutes(
+				"/projects/:project_id/uploads",
+				"/projects/:project_id/codebases/uploads",
+				"/agents",
+				"/agent-setups",
+				"/admin/storage",
+			),
+			Run: func(ctx context.Context) error {
+				return checkBuckets(ctx, cfg.AWSConfig, cfg.AI.Bedrock.S3BucketName, cfg.UploadsBucket())
+			},
+		},
+	}...)
+
+	if !cfg.AI.Local.Enabled {
+		checks = append(checks, startup.Check{
+			Name:   "bedrock_roles",
+			Hint:   "check AWS credentials are configured, and that AI_BEDROCK_KNOWLEDGE_BASE_SERVICE_ROLE_ARN and AI_BEDROCK_AGENT_SERVICE_ROLE_ARN are IAM role ARNs of the same account",
+			Routes: versionedRoutes("/agents", "/agent-setups"),
+			Run: func(ctx context.Context) error {
+				return checkBedrockRoles(ctx, cfg.AWSConfig, cfg.AI.Bedrock)
+			},
+		})
+	}
+	return checks
+}
+
+// versionedRoutes returns the paths under the base path of every API version
+func versionedRoutes(paths ...string) []string {
+	var versioned []string
+	for _, version := range []routes.APIVersion{routes.APIVersionV1, routes.APIVersionV2} {
+		for _, path := range paths {
+			versioned = append(versioned, version.BasePath()+path)
+		}
+	}
+	return versioned
+}
+
+// checkCognito checks the app client of the user pool exists
+func checkCognito(ctx context.Context, cognito appconfig.CognitoConfig) error {
+	awsConfig, err := config.LoadDefaultConfig(ctx, config.WithRegion(cognito.Region))
+	if err != nil {
+		return fmt.Errorf("failed to load AWS config for Cognito: %w", err)
+	}
+	_, err = cognitoidentityprovider.NewFromConfig(awsConfig).DescribeUserPoolClient(ctx, &cognitoidentityprovider.DescribeUserPoolClientInput{
+		UserPoolId: aws.String(cognito.UserPoolID),
+		ClientId:   aws.String(cognito.ClientID),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to describe client %s of user pool %s: %w", cognito.ClientID, cognito.UserPoolID, err)
+	}
+	return nil
+}
+
+// checkBuckets checks the buckets exist and may be accessed, skipping the unset ones
+func checkBuckets(ctx context.Context, awsConfig aws.Config, buckets ...string) error {
+	client := s3.NewFromConfig(awsConfig)
+	checked := map[string]bool{}
+	for _, bucket := range buckets {
+		if bucket == "" || checked[bucket] {
+			continue
+		}
+		checked[bucket] = true
+		if _, err := client.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: aws.String(bucket)}); err != nil {
+			return fmt.Errorf("failed to access bucket %s: %w", bucket, err)
+		}
+	}
+	return nil
+}
+
+// checkBedrockRoles checks the AWS credentials are valid, and that the service roles Bedrock assumes are IAM roles
+// of their account. Only Bedrock may assume the roles, so whether it can is only known once it tries.
+func checkBedrockRoles(ctx context.Context, awsConfig aws.Config, bedrock appconfig.BedrockAIConfig) error {
+	identity, err := sts.NewFromConfig(awsConfig).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return fmt.Errorf("failed to get caller identity: %w", err)
+	}
+	account := aws.ToString(identity.Account)
+
+	roles := []struct {
+		name string
+		arn  string
+	}{
+		{"knowledge base service role", bedrock.KnowledgeBaseServiceRoleARN},
+		{"agent service role", bedrock.AgentServiceRoleARN},
+	}
+	for _, role := range roles {
+		parsed, err := arn.Parse(role.arn)
+		if err != nil {
+			return fmt.Errorf("%s %q is not an ARN: %w", role.name, role.arn, err)
+		}
+		if parsed.Service != "iam" || !strings.HasPrefix(parsed.Resource, "role/") {
+			return fmt.Errorf("%s %s is not an IAM role", role.name, role.arn)
+		}
+		if parsed.AccountID != account {
+			return fmt.Errorf("%s %s is not in account %s of the credentials", role.name, role.arn, account)
+		}
+	}
+	return nil
+}
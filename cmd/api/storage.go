@@ -0,0 +1,338 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	appconfig "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/startup"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/workflow"
+)
+
+// repositories are the repositories of the storage backend. The memory and SQLite backends only store the resources
+// the core features need: projects, codebases and their configurations, tasks, users and their MFA recovery codes,
+// agents, roles, audit events, tag keys, redaction policies and audits, workspaces, workflow runs and client tokens.
+// The repositories of the other features, such as the device authorizations, are nil: their routes are disabled by
+// postgresOnlyFailure and their background jobs don't run, as the README's storage backend table lists.
+type repositories struct {
+	// postgres is whether every repository is stored, in Postgres
+	postgres bool
+
+	agent               repository.AgentRepository
+	project             repository.ProjectRepository
+	codebase            repository.CodebaseRepository
+	task                repository.TaskRepository
+	user                repository.UserRepository
+	mfaRecoveryCode     repository.MFARecoveryCodeRepository
+	deviceAuthorization repository.DeviceAuthorizationRepository
+	role                repository.RoleRepository
+	auditEvent          repository.AuditEventRepository
+	codebaseConfig      repository.CodebaseConfigRepository
+	projectTemplate     repository.ProjectTemplateRepository
+	tagKey              repository.TagKeyRepository
+	projectAutomation   repository.ProjectAutomationRepository
+	taskMetrics         repository.TaskMetricsRepository
+	campaign            repository.CampaignRepository
+	notificationChannel repository.NotificationChannelRepository
+	userNotification    repository.UserNotificationRepository
+	notificationOutbox  repository.NotificationOutboxRepository
+	taskFeedback        repository.TaskFeedbackRepository
+	taskComment         repository.TaskCommentRepository
+	dependencyFinding   repository.DependencyFindingRepository
+	codeMetrics         repository.CodeMetricsRepository
+	finding             repository.FindingRepository
+	qualityGatePolicy   repository.QualityGatePolicyRepository
+	codeOwners          repository.CodeOwnersRepository
+	fileHistory         repository.FileHistoryRepository
+	scanFinding         repository.ScanFindingRepository
+	redactionPolicy     repository.RedactionPolicyRepository
+	redactionAudit      repository.RedactionAuditRepository
+	agentResyncSettings repository.AgentResyncSettingsRepository
+	workspace           repository.WorkspaceRepository
+	codebaseHead        repository.CodebaseHeadRepository
+	workflowRun         repository.WorkflowRunRepository
+	llmInteraction      repository.LLMInteractionRepository
+	evalRun             repository.EvalRunRepository
+	experiment          repository.ExperimentRepository
+	gitHubAppConfig     repository.GitHubAppConfigRepository
+	gitHubCheck         repository.GitHubCheckRepository
+	jiraSite            repository.JiraSiteRepository
+	jiraIssueLink       repository.JiraIssueLinkRepository
+	clientToken         repository.ClientTokenRepository
+	serviceAccount      repository.ServiceAccountRepository
+	storagePurge        repository.StoragePurgeRepository
+}
+
+// newRepositories builds the repositories of the configured storage backend, choosing it before any repository is
+// built so Postgres is only connected to when it stores them. The returned function closes the SQLite database.
+func newRepositories(ctx context.Context, cfg *appconfig.Config, postgresConfig repository.PostgresConfig) (*repositories, func(), error) {
+	switch cfg.StorageBackend {
+	case appconfig.StorageBackendMemory:
+		slog.Warn("storing the core resources in memory, they are lost on restart, and the features only Postgres stores are disabled")
+		return newMemoryRepositories(), func() {}, nil
+	case appconfig.StorageBackendSQLite:
+		slog.Warn("storing the core resources in SQLite, the features only Postgres stores are disabled")
+		sqliteDB, err := repository.OpenSQLite(ctx, cfg.SQLite.Path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open SQLite database %s: %w", cfg.SQLite.Path, err)
+		}
+		repos, err := newSQLiteRepositories(ctx, sqliteDB)
+		if err != nil {
+			_ = sqliteDB.Close()
+			return nil, nil, err
+		}
+		return repos, func() { _ = sqliteDB.Close() }, nil
+	default:
+		repos, err := newPostgresRepositories(ctx, cfg, postgresConfig)
+		if err != nil {
+			return nil, nil, err
+		}
+		return repos, func() {}, nil
+	}
+}
+
+// newMemoryRepositories builds the repositories of the memory backend
+func newMemoryRepositories() *repositories {
+	projects := repository.NewMemoryProjectRepository()
+	return &repositories{
+		agent:           repository.NewMemoryAgentRepository(),
+		project:         projects,
+		codebase:        repository.NewMemoryCodebaseRepository(projects),
+		task:            repository.NewMemoryTaskRepository(nil),
+		user:            repository.NewMemoryUserRepository(),
+		mfaRecoveryCode: repository.NewMemoryMFARecoveryCodeRepository(),
+		role:            repository.NewMemoryRoleRepository(),
+		auditEvent:      repository.NewMemoryAuditEventRepository(),
+		codebaseConfig:  repository.NewMemoryCodebaseConfigRepository(),
+		tagKey:          repository.NewMemoryTagKeyRepository(),
+		redactionPolicy: repository.NewMemoryRedactionPolicyRepository(),
+		redactionAudit:  repository.NewMemoryRedactionAuditRepository(),
+		workspace:       repository.NewMemoryWorkspaceRepository(),
+		workflowRun:     workflow.NewMemoryRunStore(),
+		clientToken:     repository.NewMemoryClientTokenRepository(),
+	}
+}
+
+// newSQLiteRepositories builds the repositories of the SQLite backend, which stores the same resources as the memory
+// backend
+func newSQLiteRepositories(ctx context.Context, db *sql.DB) (*repositories, error) {
+	// Seeding the built-in roles and permissions
+	roles, err := repository.NewSQLiteRoleRepository(ctx, db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize role repository: %w", err)
+	}
+	return &repositories{
+		agent:           repository.NewSQLiteAgentRepository(db),
+		project:         repository.NewSQLiteProjectRepository(db),
+		codebase:        repository.NewSQLiteCodebaseRepository(db),
+		task:            repository.NewSQLiteTaskRepository(db, nil),
+		user:            repository.NewSQLiteUserRepository(db),
+		mfaRecoveryCode: repository.NewSQLiteMFARecoveryCodeRepository(db),
+		role:            roles,
+		auditEvent:      repository.NewSQLiteAuditEventRepository(db),
+		codebaseConfig:  repository.NewSQLiteCodebaseConfigRepository(db),
+		tagKey:          repository.NewSQLiteTagKeyRepository(db),
+		redactionPolicy: repository.NewSQLiteRedactionPolicyRepository(db),
+		redactionAudit:  repository.NewSQLiteRedactionAuditRepository(db),
+		workspace:       repository.NewSQLiteWorkspaceRepository(db),
+		workflowRun:     repository.NewSQLiteWorkflowRunRepository(db),
+		clientToken:     repository.NewSQLiteClientTokenRepository(db),
+	}, nil
+}
+
+// newPostgresRepositories builds every repository on Postgres
+func newPostgresRepositories(ctx context.Context, cfg *appconfig.Config, postgresConfig repository.PostgresConfig) (*repositories, error) {
+	// The schema is migrated before any repository is created
+	if err := repository.MigratePostgres(ctx, postgresConfig); err != nil {
+		return nil, err
+	}
+
+	repos := &repositories{postgres: true}
+	var err error
+
+	if repos.agent, err = repository.NewPostgresAgentRepository(postgresConfig, appconfig.DefaultAgentsTableName); err != nil {
+		return nil, fmt.Errorf("failed to initialize agent repository: %w", err)
+	}
+	if repos.project, err = repository.NewPostgresProjectRepository(postgresConfig, appconfig.DefaultProjectsTableName); err != nil {
+		return nil, fmt.Errorf("failed to initialize project repository: %w", err)
+	}
+	if repos.codebase, err = repository.NewPostgresCodebaseRepository(postgresConfig, appconfig.DefaultCodebasesTableName); err != nil {
+		return nil, fmt.Errorf("failed to initialize codebase repository: %w", err)
+	}
+	if repos.task, err = repository.NewPostgresTaskRepository(postgresConfig, appconfig.DefaultTasksTableName, appconfig.DefaultNotificationOutboxTableName); err != nil {
+		return nil, fmt.Errorf("failed to initialize task repository: %w", err)
+	}
+	if repos.user, err = repository.NewPostgresUserRepository(postgresConfig, appconfig.DefaultUsersTableName); err != nil {
+		return nil, fmt.Errorf("failed to initialize user repository: %w", err)
+	}
+	if repos.mfaRecoveryCode, err = repository.NewPostgresMFARecoveryCodeRepository(postgresConfig, appconfig.DefaultMFARecoveryCodesTableName); err != nil {
+		return nil, fmt.Errorf("failed to initialize MFA recovery code repository: %w", err)
+	}
+	if repos.deviceAuthorization, err = repository.NewPostgresDeviceAuthorizationRepository(postgresConfig, appconfig.DefaultDeviceAuthorizationsTableName); err != nil {
+		return nil, fmt.Errorf("failed to initialize device authorization repository: %w", err)
+	}
+	// Seeding the built-in roles and permissions
+	if repos.role, err = repository.NewPostgresRoleRepository(postgresConfig,
+		appconfig.DefaultRolesTableName, appconfig.DefaultPermissionsTableName,
+		appconfig.DefaultRolePermissionsTableName, appconfig.DefaultProjectMembersTableName); err != nil {
+		return nil, fmt.Errorf("failed to initialize role repository: %w", err)
+	}
+	// Recording the changes to access
+	if repos.auditEvent, err = repository.NewPostgresAuditEventRepository(postgresConfig, appconfig.DefaultAuditEventsTableName); err != nil {
+		return nil, fmt.Errorf("failed to initialize audit event repository: %w", err)
+	}
+	if repos.codebaseConfig, err = repository.NewPostgresCodebaseConfigRepository(postgresConfig, appconfig.DefaultCodebaseConfigsTableName); err != nil {
+		return nil, fmt.Errorf("failed to initialize codebase configuration repository: %w", err)
+	}
+	if repos.projectTemplate, err = repository.NewPostgresProjectTemplateRepository(postgresConfig, appconfig.DefaultProjectTemplatesTableName); err != nil {
+		return nil, fmt.Errorf("failed to initialize project template repository: %w", err)
+	}
+	if repos.tagKey, err = repository.NewPostgresTagKeyRepository(postgresConfig, appconfig.DefaultTagKeysTableName); err != nil {
+		return nil, fmt.Errorf("failed to initialize tag key repository: %w", err)
+	}
+	if repos.projectAutomation, err = repository.NewPostgresProjectAutomationRepository(postgresConfig, appconfig.DefaultProjectAutomationsTableName); err != nil {
+		return nil, fmt.Errorf("failed to initialize project automation repository: %w", err)
+	}
+	// Backing the reports, joining the tasks table
+	if repos.taskMetrics, err = repository.NewPostgresTaskMetricsRepository(postgresConfig, appconfig.DefaultTaskMetricsTableName, appconfig.DefaultTasksTableName, appconfig.DefaultTaskFeedbackTableName); err != nil {
+		return nil, fmt.Errorf("failed to initialize task metrics repository: %w", err)
+	}
+	if repos.campaign, err = repository.NewPostgresCampaignRepository(postgresConfig, appconfig.DefaultCampaignsTableName); err != nil {
+		return nil, fmt.Errorf("failed to initialize campaign repository: %w", err)
+	}
+	if repos.notificationChannel, err = repository.NewPostgresNotificationChannelRepository(postgresConfig, appconfig.DefaultNotificationChannelsTableName); err != nil {
+		return nil, fmt.Errorf("failed to initialize notification channel repository: %w", err)
+	}
+	if repos.userNotification, err = repository.NewPostgresUserNotificationRepository(postgresConfig, appconfig.DefaultNotificationsTableName); err != nil {
+		return nil, fmt.Errorf("failed to initialize notification inbox repository: %w", err)
+	}
+	// The outbox of notifications awaiting delivery
+	if repos.notificationOutbox, err = repository.NewPostgresNotificationOutboxRepository(postgresConfig, appconfig.DefaultNotificationOutboxTableName); err != nil {
+		return nil, fmt.Errorf("failed to initialize notification outbox repository: %w", err)
+	}
+	if repos.taskFeedback, err = repository.NewPostgresTaskFeedbackRepository(postgresConfig, appconfig.DefaultTaskFeedbackTableName); err != nil {
+		return nil, fmt.Errorf("failed to initialize task feedback repository: %w", err)
+	}
+	if repos.taskComment, err = repository.NewPostgresTaskCommentRepository(postgresConfig, appconfig.DefaultTaskCommentsTableName); err != nil {
+		return nil, fmt.Errorf("failed to initialize task comment repository: %w", err)
+	}
+	if repos.dependencyFinding, err = repository.NewPostgresDependencyFindingRepository(postgresConfig, appconfig.DefaultDependencyFindingsTableName, cfg.Postgres.BulkInsertBatchSize); err != nil {
+		return nil, fmt.Errorf("failed to initialize dependency finding repository: %w", err)
+	}
+	if repos.codeMetrics, err = repository.NewPostgresCodeMetricsRepository(postgresConfig, appconfig.DefaultCodeMetricsTableName); err != nil {
+		return nil, fmt.Errorf("failed to initialize code metrics repository: %w", err)
+	}
+	if repos.finding, err = repository.NewPostgresFindingRepository(postgresConfig, appconfig.DefaultFindingsTableName, appconfig.DefaultFindingBaselinesTableName, cfg.Postgres.BulkInsertBatchSize); err != nil {
+		return nil, fmt.Errorf("failed to initialize finding repository: %w", err)
+	}
+	if repos.qualityGatePolicy, err = repository.NewPostgresQualityGatePolicyRepository(postgresConfig, appconfig.DefaultQualityGatePoliciesTableName); err != nil {
+		return nil, fmt.Errorf("failed to initialize quality gate policy repository: %w", err)
+	}
+	if repos.codeOwners, err = repository.NewPostgresCodeOwnersRepository(postgresConfig, appconfig.DefaultCodeOwnersTableName); err != nil {
+		return nil, fmt.Errorf("failed to initialize code owners repository: %w", err)
+	}
+	if repos.fileHistory, err = repository.NewPostgresFileHistoryRepository(postgresConfig, appconfig.DefaultFileHistoriesTableName, cfg.Postgres.BulkInsertBatchSize); err != nil {
+		return nil, fmt.Errorf("failed to initialize file history repository: %w", err)
+	}
+	// Findings of the ingestion scans
+	if repos.scanFinding, err = repository.NewPostgresScanFindingRepository(postgresConfig, appconfig.DefaultScanFindingsTableName, cfg.Postgres.BulkInsertBatchSize); err != nil {
+		return nil, fmt.Errorf("failed to initialize scan finding repository: %w", err)
+	}
+	if repos.redactionPolicy, err = repository.NewPostgresRedactionPolicyRepository(postgresConfig, appconfig.DefaultRedactionPoliciesTableName); err != nil {
+		return nil, fmt.Errorf("failed to initialize redaction policy repository: %w", err)
+	}
+	if repos.redactionAudit, err = repository.NewPostgresRedactionAuditRepository(postgresConfig, appconfig.DefaultRedactionAuditsTableName); err != nil {
+		return nil, fmt.Errorf("failed to initialize redaction audit repository: %w", err)
+	}
+	if repos.agentResyncSettings, err = repository.NewPostgresAgentResyncSettingsRepository(postgresConfig, appconfig.DefaultAgentResyncSettingsTableName); err != nil {
+		return nil, fmt.Errorf("failed to initialize agent resync settings repository: %w", err)
+	}
+	// Tracking the codebase clones on this task runner's disk
+	if repos.workspace, err = repository.NewPostgresWorkspaceRepository(postgresConfig, appconfig.DefaultWorkspacesTableName); err != nil {
+		return nil, fmt.Errorf("failed to initialize workspace repository: %w", err)
+	}
+	if repos.codebaseHead, err = repository.NewPostgresCodebaseHeadRepository(postgresConfig, appconfig.DefaultCodebaseHeadsTableName); err != nil {
+		return nil, fmt.Errorf("failed to initialize codebase head repository: %w", err)
+	}
+	if repos.workflowRun, err = repository.NewPostgresWorkflowRunRepository(postgresConfig, appconfig.DefaultWorkflowRunsTableName); err != nil {
+		return nil, fmt.Errorf("failed to initialize workflow run repository: %w", err)
+	}
+	if repos.llmInteraction, err = repository.NewPostgresLLMInteractionRepository(postgresConfig, appconfig.DefaultLLMInteractionsTableName); err != nil {
+		return nil, fmt.Errorf("failed to initialize LLM interaction repository: %w", err)
+	}
+	if repos.evalRun, err = repository.NewPostgresEvalRunRepository(postgresConfig, appconfig.DefaultEvalRunsTableName); err != nil {
+		return nil, fmt.Errorf("failed to initialize eval run repository: %w", err)
+	}
+	if repos.experiment, err = repository.NewPostgresExperimentRepository(postgresConfig, appconfig.DefaultExperimentsTableName); err != nil {
+		return nil, fmt.Errorf("failed to initialize experiment repository: %w", err)
+	}
+	if repos.gitHubAppConfig, err = repository.NewPostgresGitHubAppConfigRepository(postgresConfig, appconfig.DefaultGitHubAppConfigsTableName); err != nil {
+		return nil, fmt.Errorf("failed to initialize GitHub App configuration repository: %w", err)
+	}
+	// Check runs join the tasks table
+	if repos.gitHubCheck, err = repository.NewPostgresGitHubCheckRepository(postgresConfig, appconfig.DefaultGitHubChecksTableName, appconfig.DefaultTasksTableName); err != nil {
+		return nil, fmt.Errorf("failed to initialize GitHub check repository: %w", err)
+	}
+	if repos.jiraSite, err = repository.NewPostgresJiraSiteRepository(postgresConfig, appconfig.DefaultJiraSitesTableName); err != nil {
+		return nil, fmt.Errorf("failed to initialize Jira site repository: %w", err)
+	}
+	// Issue links join the tasks table
+	if repos.jiraIssueLink, err = repository.NewPostgresJiraIssueLinkRepository(postgresConfig, appconfig.DefaultJiraIssueLinksTableName, appconfig.DefaultTasksTableName); err != nil {
+		return nil, fmt.Errorf("failed to initialize Jira issue link repository: %w", err)
+	}
+	if repos.clientToken, err = repository.NewPostgresClientTokenRepository(postgresConfig, appconfig.DefaultClientTokensTableName); err != nil {
+		return nil, fmt.Errorf("failed to initialize client token repository: %w", err)
+	}
+	if repos.serviceAccount, err = repository.NewPostgresServiceAccountRepository(postgresConfig, appconfig.DefaultServiceAccountsTableName, appconfig.DefaultServiceAccountTokensTableName); err != nil {
+		return nil, fmt.Errorf("failed to initialize service account repository: %w", err)
+	}
+	if repos.storagePurge, err = repository.NewPostgresStoragePurgeRepository(postgresConfig, appconfig.DefaultStoragePurgesTableName); err != nil {
+		return nil, fmt.Errorf("failed to initialize storage purge repository: %w", err)
+	}
+
+	return repos, nil
+}
+
+// postgresOnlyFailure disables the routes of the features only Postgres stores, like the failure of a dependency the
+// service started without. Device sign-in, GitHub checks and Jira links, notifications, campaigns and reports, the
+// codebase analyses and the admin features are among them.
+func postgresOnlyFailure(backend appconfig.StorageBackend) startup.Failure {
+	routes := versionedRoutes(
+		"/admin/evals",
+		"/admin/experiments",
+		"/admin/github-apps",
+		"/admin/jira-sites",
+		"/admin/service-accounts",
+		"/admin/storage",
+		"/notifications",
+		"/project-templates",
+		"/projects/from-template",
+		"/projects/import",
+		"/projects/:project_id/export",
+		"/projects/:project_id/quality-gates",
+		"/projects/:project_id/agent-resync",
+		"/reports",
+		"/campaigns",
+		"/findings",
+		"/codebases/:id/owners",
+		"/codebases/:id/findings",
+		"/codebases/:id/dependency-findings",
+		"/codebases/:id/metrics",
+		"/codebases/:id/scan",
+		"/codebases/:id/scan-findings",
+		"/tasks/:id/comments",
+		"/tasks/:id/feedback",
+		"/tasks/:id/llm-trace",
+	)
+	return startup.Failure{
+		Check: startup.Check{
+			Name:   "postgres",
+			Hint:   "set STORAGE_BACKEND=postgres to enable them",
+			Routes: append(routes, "/auth/device", "/webhooks/github"),
+		},
+		Err: fmt.Errorf("the %s storage backend doesn't store them", backend),
+	}
+}
@@ -0,0 +1,26 @@
+// Package main provides the entry point for the refactorctl admin CLI
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/cli"
+)
+
+func main() {
+	os.Exit(run())
+}
+
+// run executes the CLI and returns the process exit code
+func run() int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := cli.NewRootCommand().ExecuteContext(ctx); err != nil {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,50 @@
+// Package main converts the Swagger 2.0 document swag generates from the API's annotations to the OpenAPI 3 document
+// the API serves at /openapi.json
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/openapi"
+)
+
+// securityScheme is the security definition of cmd/api authenticating the callers of the routes that aren't public
+const securityScheme = "ApiKeyAuth"
+
+func main() {
+	in := flag.String("in", "docs/swagger.json", "Swagger 2.0 document to convert")
+	out := flag.String("out", "docs/openapi.json", "OpenAPI 3 document to write")
+	flag.Parse()
+
+	if err := run(*in, *out); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// run converts the Swagger 2.0 document in to the OpenAPI 3 document out
+func run(in, out string) error {
+	swagger, err := os.ReadFile(in)
+	if err != nil {
+		return fmt.Errorf("failed to read Swagger document: %w", err)
+	}
+	document, err := convert(swagger)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(out, document, 0o644); err != nil {
+		return fmt.Errorf("failed to write OpenAPI document: %w", err)
+	}
+	return nil
+}
+
+// convert converts a Swagger 2.0 document of the API, requiring authentication on the routes that aren't public
+func convert(swagger []byte) ([]byte, error) {
+	return openapi.Convert(swagger, openapi.Options{
+		Security: securityScheme,
+		Public:   middleware.IsPublicEndpoint,
+	})
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPIDocumentIsUpToDate(t *testing.T) {
+	swagger, err := os.ReadFile("../../docs/swagger.json")
+	require.NoError(t, err)
+	committed, err := os.ReadFile("../../docs/openapi.json")
+	require.NoError(t, err)
+
+	document, err := convert(swagger)
+
+	require.NoError(t, err)
+	assert.True(t, string(document) == string(committed), "docs/openapi.json is stale; run make swagger")
+}
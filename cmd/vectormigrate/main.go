@@ -0,0 +1,168 @@
+// Package main copies the vectors of knowledge bases from one vector store backend to another, and switches the
+// project they belong to over to the new backend
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/storage"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/cli"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/client"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// defaultBatchSize is how many vectors are copied at a time unless --batch-size says otherwise
+const defaultBatchSize = 500
+
+// collections collects the values of the repeatable --collection flag
+type collections []string
+
+// String implements flag.Value.
+func (c *collections) String() string {
+	return strings.Join(*c, ",")
+}
+
+// Set implements flag.Value.
+func (c *collections) Set(value string) error {
+	*c = append(*c, value)
+	return nil
+}
+
+// options are the flags of a migration
+type options struct {
+	projectID   string
+	from        string
+	to          string
+	collections []string
+	batchSize   int
+}
+
+// projects reads and updates the project a migration switches over, through the API
+type projects interface {
+	GetProject(ctx context.Context, projectID string) (*models.GetProjectResponse, error)
+	UpdateProject(ctx context.Context, request models.UpdateProjectRequest) (*models.UpdateProjectResponse, error)
+}
+
+func main() {
+	var opts options
+	var names collections
+	flag.StringVar(&opts.projectID, "project", "", "project switched over to the target backend once its vectors are copied")
+	flag.StringVar(&opts.from, "from", "", "backend the vectors are copied from, the project's backend when unset")
+	flag.StringVar(&opts.to, "to", "", "backend the vectors are copied to: pgvector, opensearch or qdrant")
+	flag.Var(&names, "collection", "collection to copy, repeatable")
+	flag.IntVar(&opts.batchSize, "batch-size", defaultBatchSize, "vectors copied at a time")
+	flag.Parse()
+	opts.collections = names
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, opts); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// run migrates with the vector stores of the service configuration, calling the API the CLI is configured for
+func run(ctx context.Context, opts options) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	serverURL := os.Getenv(cli.EnvServerURL)
+	if serverURL == "" {
+		serverURL = cli.DefaultServerURL
+	}
+	api := client.New(client.Config{BaseURL: serverURL, APIKey: os.Getenv(cli.EnvAPIKey)})
+
+	newStore := func(backend config.VectorStoreType) (storage.VectorStore, error) {
+		return storage.NewVectorStore(backend, cfg.VectorStore, cfg.Postgres, cfg.AWSConfig)
+	}
+	return migrate(ctx, opts, cfg.VectorStore, api, newStore, os.Stdout)
+}
+
+// migrate copies the collections to the target backend, then switches the project over to it. Vectors are left in
+// the source backend, so the project keeps working on it should the migration fail.
+func migrate(ctx context.Context, opts options, cfg config.VectorStoreConfig, api projects, newStore func(config.VectorStoreType) (storage.VectorStore, error), out io.Writer) error {
+	if len(opts.collections) == 0 {
+		return errors.New("at least one --collection is required")
+	}
+	var to config.VectorStoreType
+	if err := to.Decode(opts.to); err != nil {
+		return fmt.Errorf("invalid --to: %w", err)
+	}
+
+	var project *models.GetProjectResponse
+	if opts.projectID != "" {
+		var err error
+		project, err = api.GetProject(ctx, opts.projectID)
+		if err != nil {
+			return fmt.Errorf("failed to get project %s: %w", opts.projectID, err)
+		}
+	}
+	from, err := sourceBackend(opts.from, project, cfg)
+	if err != nil {
+		return err
+	}
+	if from == to {
+		return fmt.Errorf("the vectors are already stored in %s", to)
+	}
+
+	source, err := newStore(from)
+	if err != nil {
+		return fmt.Errorf("failed to create the %s vector store: %w", from, err)
+	}
+	target, err := newStore(to)
+	if err != nil {
+		return fmt.Errorf("failed to create the %s vector store: %w", to, err)
+	}
+
+	for _, collection := range opts.collections {
+		copied, err := storage.MigrateVectors(ctx, source, target, collection, opts.batchSize)
+		if err != nil {
+			return fmt.Errorf("failed to migrate collection %s: %w", collection, err)
+		}
+		_, _ = fmt.Fprintf(out, "copied %d vectors of %s from %s to %s\n", copied, collection, from, to)
+	}
+
+	if project == nil {
+		return nil
+	}
+	backend := string(to)
+	_, err = api.UpdateProject(ctx, models.UpdateProjectRequest{
+		ProjectID:       project.ProjectID,
+		VectorStore:     &backend,
+		ExpectedVersion: project.Version,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to switch project %s over to %s: %w", project.ProjectID, to, err)
+	}
+	_, _ = fmt.Fprintf(out, "project %s now stores its vectors in %s\n", project.ProjectID, to)
+	return nil
+}
+
+// sourceBackend resolves the backend vectors are copied from: the one named by --from, or else the project's
+func sourceBackend(from string, project *models.GetProjectResponse, cfg config.VectorStoreConfig) (config.VectorStoreType, error) {
+	var backend config.VectorStoreType
+	switch {
+	case from != "":
+		if err := backend.Decode(from); err != nil {
+			return "", fmt.Errorf("invalid --from: %w", err)
+		}
+	case project != nil:
+		backend = cfg.Backend(project.VectorStore)
+	default:
+		return "", errors.New("--from is required without --project")
+	}
+	return backend, nil
+}
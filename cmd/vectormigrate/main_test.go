@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/storage"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// memoryStore is a vector store keeping its records in memory, scanned one at a time
+type memoryStore struct {
+	records   map[string]map[string]storage.VectorRecord
+	upsertErr error
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{records: make(map[string]map[string]storage.VectorRecord)}
+}
+
+func (s *memoryStore) Upsert(_ context.Context, collection string, records []storage.VectorRecord) error {
+	if s.upsertErr != nil {
+		return s.upsertErr
+	}
+	if s.records[collection] == nil {
+		s.records[collection] = make(map[string]storage.VectorRecord)
+	}
+	for _, record := range records {
+		s.records[collection][record.ID] = record
+	}
+	return nil
+}
+
+func (s *memoryStore) Query(context.Context, string, []float32, int) ([]storage.VectorMatch, error) {
+	return nil, nil
+}
+
+func (s *memoryStore) DeleteBySource(context.Context, string, string) error {
+	return nil
+}
+
+func (s *memoryStore) Scan(_ context.Context, collection string, cursor string, _ int) ([]storage.VectorRecord, string, error) {
+	var ids []string
+	for id := range s.records[collection] {
+		if id > cursor {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, "", nil
+	}
+	sort.Strings(ids)
+	next := ""
+	if len(ids) > 1 {
+		next = ids[0]
+	}
+	return []storage.VectorRecord{s.records[collection][ids[0]]}, next, nil
+}
+
+// fakeProjects serves a project and records the updates made to it
+type fakeProjects struct {
+	project models.GetProjectResponse
+	updates []models.UpdateProjectRequest
+}
+
+func (p *fakeProjects) GetProject(_ context.Context, projectID string) (*models.GetProjectResponse, error) {
+	if projectID != p.project.ProjectID {
+		return nil, errors.New("project not found")
+	}
+	project := p.project
+	return &project, nil
+}
+
+func (p *fakeProjects) UpdateProject(_ context.Context, request models.UpdateProjectRequest) (*models.UpdateProjectResponse, error) {
+	p.updates = append(p.updates, request)
+	return &models.UpdateProjectResponse{ProjectID: request.ProjectID, Version: request.ExpectedVersion + 1}, nil
+}
+
+func newStores(stores map[config.VectorStoreType]*memoryStore) func(config.VectorStoreType) (storage.VectorStore, error) {
+	return func(backend config.VectorStoreType) (storage.VectorStore, error) {
+		return stores[backend], nil
+	}
+}
+
+func TestMigrate_SwitchesProjectOver(t *testing.T) {
+	qdrant := newMemoryStore()
+	opensearch := newMemoryStore()
+	records := []storage.VectorRecord{
+		{ID: "charge-1", Source: "pkg/charge.go", Embedding: []float32{1, 0}},
+		{ID: "refund-1", Source: "pkg/refund.go", Embedding: []float32{0, 1}},
+	}
+	require.NoError(t, qdrant.Upsert(context.Background(), "kb_payments", records))
+	api := &fakeProjects{project: models.GetProjectResponse{ProjectID: "proj-1", Version: 4}}
+	var out bytes.Buffer
+
+	err := migrate(context.Background(), options{projectID: "proj-1", to: "opensearch", collections: []string{"kb_payments"}, batchSize: 1},
+		config.VectorStoreConfig{Default: config.VectorStoreQdrant}, api,
+		newStores(map[config.VectorStoreType]*memoryStore{config.VectorStoreQdrant: qdrant, config.VectorStoreOpenSearch: opensearch}), &out)
+
+	require.NoError(t, err)
+	assert.Len(t, opensearch.records["kb_payments"], 2)
+	require.Len(t, api.updates, 1)
+	assert.Equal(t, "opensearch", *api.updates[0].VectorStore)
+	assert.Equal(t, int64(4), api.updates[0].ExpectedVersion)
+	assert.Contains(t, out.String(), "copied 2 vectors of kb_payments from qdrant to opensearch")
+}
+
+func TestMigrate_FailureKeepsProjectOnSource(t *testing.T) {
+	pgvector := newMemoryStore()
+	qdrant := newMemoryStore()
+	qdrant.upsertErr = errors.New("qdrant unavailable")
+	require.NoError(t, pgvector.Upsert(context.Background(), "kb_payments", []storage.VectorRecord{{ID: "charge-1", Embedding: []float32{1, 0}}}))
+	api := &fakeProjects{project: models.GetProjectResponse{ProjectID: "proj-1", VectorStore: "pgvector", Version: 2}}
+
+	err := migrate(context.Background(), options{projectID: "proj-1", to: "qdrant", collections: []string{"kb_payments"}, batchSize: 10},
+		config.VectorStoreConfig{Default: config.VectorStoreOpenSearch}, api,
+		newStores(map[config.VectorStoreType]*memoryStore{config.VectorStorePgvector: pgvector, config.VectorStoreQdrant: qdrant}), &bytes.Buffer{})
+
+	assert.ErrorContains(t, err, "qdrant unavailable")
+	assert.Empty(t, api.updates)
+}
+
+func TestMigrate_RejectsInvalidOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    options
+		message string
+	}{
+		{name: "no collection", opts: options{from: "pgvector", to: "qdrant"}, message: "--collection is required"},
+		{name: "unknown target", opts: options{from: "pgvector", to: "pinecone", collections: []string{"kb"}}, message: "invalid --to"},
+		{name: "no source", opts: options{to: "qdrant", collections: []string{"kb"}}, message: "--from is required without --project"},
+		{name: "same backend", opts: options{from: "qdrant", to: "qdrant", collections: []string{"kb"}}, message: "already stored in qdrant"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := migrate(context.Background(), tt.opts, config.VectorStoreConfig{Default: config.VectorStorePgvector}, &fakeProjects{},
+				newStores(nil), &bytes.Buffer{})
+
+			assert.ErrorContains(t, err, tt.message)
+		})
+	}
+}
@@ -0,0 +1,10 @@
+package docs
+
+import _ "embed"
+
+// OpenAPI is the OpenAPI 3 document of the API, converted from swagger.json. Run `make swagger` after changing the
+// annotations of the controllers.
+//
+//go:generate go run ../cmd/openapi -in swagger.json -out openapi.json
+//go:embed openapi.json
+var OpenAPI []byte
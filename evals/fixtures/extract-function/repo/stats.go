@@ -0,0 +1,32 @@
+// Package stats computes summary statistics
+package stats
+
+// Mean returns the average of values, 0 for no values
+func Mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+	}
+	return sum / float64(len(values))
+}
+
+// MeanOfPositive returns the average of the positive values, 0 when there are none
+func MeanOfPositive(values []float64) float64 {
+	var positive []float64
+	for _, value := range values {
+		if value > 0 {
+			positive = append(positive, value)
+		}
+	}
+	if len(positive) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, value := range positive {
+		sum += value
+	}
+	return sum / float64(len(positive))
+}
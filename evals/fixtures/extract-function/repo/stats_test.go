@@ -0,0 +1,21 @@
+package stats
+
+import "testing"
+
+func TestMean(t *testing.T) {
+	if got := Mean([]float64{1, 2, 3}); got != 2 {
+		t.Errorf("Mean = %v, want 2", got)
+	}
+	if got := Mean(nil); got != 0 {
+		t.Errorf("Mean(nil) = %v, want 0", got)
+	}
+}
+
+func TestMeanOfPositive(t *testing.T) {
+	if got := MeanOfPositive([]float64{-4, 2, 4}); got != 3 {
+		t.Errorf("MeanOfPositive = %v, want 3", got)
+	}
+	if got := MeanOfPositive([]float64{-1}); got != 0 {
+		t.Errorf("MeanOfPositive = %v, want 0", got)
+	}
+}
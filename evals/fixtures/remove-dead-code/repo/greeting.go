@@ -0,0 +1,17 @@
+// Package greeting greets people
+package greeting
+
+import "fmt"
+
+// Greeting returns the greeting of name, greeting the world when name is empty
+func Greeting(name string) string {
+	if name == "" {
+		return "Hello, world!"
+		name = "world"
+	}
+	return fmt.Sprintf("Hello, %s!", name)
+}
+
+func legacyGreeting(name string) string {
+	return "Hi " + name
+}
@@ -0,0 +1,12 @@
+package greeting
+
+import "testing"
+
+func TestGreeting(t *testing.T) {
+	if got := Greeting("Ada"); got != "Hello, Ada!" {
+		t.Errorf("Greeting = %q", got)
+	}
+	if got := Greeting(""); got != "Hello, world!" {
+		t.Errorf("Greeting = %q", got)
+	}
+}
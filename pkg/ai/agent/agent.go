@@ -12,3 +12,31 @@ type Agent interface {
 	// Ask for prompt from the agent.
 	Ask(ctx context.Context, prompt string) (string, error)
 }
+
+// StreamingAgent is an agent passing its answer on as the model generates it, rather than once it's complete.
+type StreamingAgent interface {
+	Agent
+
+	// AskStream for prompt from the agent, passing each piece of the answer to onChunk as it's generated. It returns
+	// the whole answer, and stops with the error of onChunk when it fails.
+	AskStream(ctx context.Context, prompt string, onChunk func(chunk string) error) (string, error)
+}
+
+// AskStream for prompt from agent, streaming the answer to onChunk when the agent supports it, or else passing the
+// whole answer as a single chunk.
+func AskStream(ctx context.Context, agent Agent, prompt string, onChunk func(chunk string) error) (string, error) {
+	if streaming, ok := agent.(StreamingAgent); ok {
+		return streaming.AskStream(ctx, prompt, onChunk)
+	}
+
+	answer, err := agent.Ask(ctx, prompt)
+	if err != nil {
+		return "", err
+	}
+	if answer != "" {
+		if err := onChunk(answer); err != nil {
+			return answer, err
+		}
+	}
+	return answer, nil
+}
@@ -5,9 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
 )
 
 // AWSBedrockAgent is an implementation of the Agent interface that uses the AWS Bedrock service.
@@ -27,16 +29,9 @@ func NewAWSBedrockFMAgent(cfg aws.Config, modelID string) Agent {
 
 // Ask sends a prompt to the agent and returns the response.
 func (a *AWSBedrockAgent) Ask(ctx context.Context, prompt string) (string, error) {
-	payload := map[string]interface{}{
-		"anthropic_version": "bedrock-2023-05-31",
-		"messages": []map[string]string{
-			{"role": "user", "content": prompt},
-		},
-		"max_tokens": 500,
-	}
-	body, err := json.Marshal(payload)
+	body, err := messagesPayload(prompt)
 	if err != nil {
-		return "", fmt.Errorf("error marshalling payload: %w", err)
+		return "", err
 	}
 
 	output, err := a.Client.InvokeModel(ctx, &bedrockruntime.InvokeModelInput{
@@ -72,3 +67,68 @@ func (a *AWSBedrockAgent) Ask(ctx context.Context, prompt string) (string, error
 
 	return "", fmt.Errorf("unexpected response format: %v", resp)
 }
+
+// AskStream implements the StreamingAgent interface by invoking the model with a response stream, whose chunks carry
+// the deltas of the answer's text.
+func (a *AWSBedrockAgent) AskStream(ctx context.Context, prompt string, onChunk func(chunk string) error) (string, error) {
+	body, err := messagesPayload(prompt)
+	if err != nil {
+		return "", err
+	}
+
+	output, err := a.Client.InvokeModelWithResponseStream(ctx, &bedrockruntime.InvokeModelWithResponseStreamInput{
+		ModelId:     &a.ModelID,
+		ContentType: aws.String("application/json"),
+		Accept:      aws.String("application/json"),
+		Body:        body,
+	})
+	if err != nil {
+		return "", fmt.Errorf("bedrock invoke error: %w", err)
+	}
+	stream := output.GetStream()
+	defer stream.Close() //nolint:errcheck // Closing only stops reading events
+
+	var answer strings.Builder
+	for event := range stream.Events() {
+		chunk, ok := event.(*types.ResponseStreamMemberChunk)
+		if !ok {
+			continue
+		}
+		var delta struct {
+			Type  string `json:"type"`
+			Delta struct {
+				Text string `json:"text"`
+			} `json:"delta"`
+		}
+		if err := json.Unmarshal(chunk.Value.Bytes, &delta); err != nil {
+			return answer.String(), fmt.Errorf("error parsing response chunk: %w", err)
+		}
+		if delta.Type != "content_block_delta" || delta.Delta.Text == "" {
+			continue
+		}
+		answer.WriteString(delta.Delta.Text)
+		if err := onChunk(delta.Delta.Text); err != nil {
+			return answer.String(), err
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return answer.String(), fmt.Errorf("bedrock stream error: %w", err)
+	}
+	return answer.String(), nil
+}
+
+// messagesPayload returns the body of a request prompting a model of the Messages API
+func messagesPayload(prompt string) ([]byte, error) {
+	payload := map[string]interface{}{
+		"anthropic_version": "bedrock-2023-05-31",
+		"messages": []map[string]string{
+			{"role": "user", "content": prompt},
+		},
+		"max_tokens": 500,
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling payload: %w", err)
+	}
+	return body, nil
+}
@@ -0,0 +1,51 @@
+package agent
+
+import (
+	"context"
+)
+
+// Roles of the messages of a chat
+const (
+	ChatRoleSystem    = "system"
+	ChatRoleUser      = "user"
+	ChatRoleAssistant = "assistant"
+	ChatRoleTool      = "tool"
+)
+
+// ChatMessage is a message of a chat. Assistant messages may call tools, and tool messages carry the result of a call.
+type ChatMessage struct {
+	Role      string     `json:"role"`
+	Content   string     `json:"content"`
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+	ToolName  string     `json:"tool_name,omitempty"`
+
+	// Usage counts the tokens of a model's reply, when the model reports them
+	Usage *TokenUsage `json:"usage,omitempty"`
+}
+
+// TokenUsage counts the tokens a model read and wrote to reply
+type TokenUsage struct {
+	PromptTokens   int `json:"prompt_tokens"`
+	ResponseTokens int `json:"response_tokens"`
+}
+
+// ToolCall is a model's request to invoke a tool with arguments
+type ToolCall struct {
+	Name      string         `json:"name"`
+	Arguments map[string]any `json:"arguments"`
+}
+
+// ToolDefinition describes a tool to the model. Parameters is the JSON schema of the tool's arguments.
+type ToolDefinition struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Parameters  map[string]any `json:"parameters"`
+}
+
+// ChatModel is an LLM answering chats and calling the tools it's offered.
+//
+//go:generate mockgen -destination=./mocks/mock_chat_model.go -mock_names=ChatModel=MockChatModel -package=mocks . ChatModel
+type ChatModel interface {
+	// Chat returns the model's next message in the chat, which may call some of the tools.
+	Chat(ctx context.Context, messages []ChatMessage, tools []ToolDefinition) (ChatMessage, error)
+}
@@ -0,0 +1,59 @@
+package agent
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/resilience"
+)
+
+// LimitedAgent bounds the concurrent prompts to another agent with the limiter of its model, retrying the prompts
+// the model throttles. Prompts have no side effects, so they're safe to retry.
+type LimitedAgent struct {
+	agent   Agent
+	limiter *resilience.Limiter
+}
+
+// NewLimitedAgent creates an agent bounding the concurrent prompts to agent with limiter
+func NewLimitedAgent(agent Agent, limiter *resilience.Limiter) Agent {
+	return &LimitedAgent{
+		agent:   agent,
+		limiter: limiter,
+	}
+}
+
+// Ask for prompt from the agent.
+func (a *LimitedAgent) Ask(ctx context.Context, prompt string) (string, error) {
+	return resilience.Limit(ctx, a.limiter, func(ctx context.Context) (string, error) {
+		return a.agent.Ask(ctx, prompt)
+	})
+}
+
+// AskStream implements the StreamingAgent interface. Streamed prompts aren't retried, since part of their answer may
+// already have been passed on.
+func (a *LimitedAgent) AskStream(ctx context.Context, prompt string, onChunk func(chunk string) error) (string, error) {
+	return resilience.LimitOnce(ctx, a.limiter, func(ctx context.Context) (string, error) {
+		return AskStream(ctx, a.agent, prompt, onChunk)
+	})
+}
+
+// LimitedChatModel bounds the concurrent chats with another model with the model's limiter, retrying the chats the
+// model throttles
+type LimitedChatModel struct {
+	model   ChatModel
+	limiter *resilience.Limiter
+}
+
+// NewLimitedChatModel creates a chat model bounding the concurrent chats with model with limiter
+func NewLimitedChatModel(model ChatModel, limiter *resilience.Limiter) ChatModel {
+	return &LimitedChatModel{
+		model:   model,
+		limiter: limiter,
+	}
+}
+
+// Chat implements the ChatModel interface.
+func (m *LimitedChatModel) Chat(ctx context.Context, messages []ChatMessage, tools []ToolDefinition) (ChatMessage, error) {
+	return resilience.Limit(ctx, m.limiter, func(ctx context.Context) (ChatMessage, error) {
+		return m.model.Chat(ctx, messages, tools)
+	})
+}
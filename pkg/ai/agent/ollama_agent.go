@@ -5,11 +5,35 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 )
 
+// OllamaStatusError is returned when Ollama answers a request with a status other than 200
+type OllamaStatusError struct {
+	StatusCode int
+	Message    string
+}
+
+// Error implements the error interface
+func (e *OllamaStatusError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("ollama returned status %d: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("ollama returned status %d", e.StatusCode)
+}
+
+// IsOllamaThrottled reports whether err is Ollama turning a request away under load: a 429, or a 503 once as many
+// requests as it queues are waiting
+func IsOllamaThrottled(err error) bool {
+	var statusErr *OllamaStatusError
+	return errors.As(err, &statusErr) &&
+		(statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode == http.StatusServiceUnavailable)
+}
+
 // OllamaAgent implements the Agent interface using Ollama for local LLM inference.
 type OllamaAgent struct {
 	baseURL    string
@@ -73,7 +97,7 @@ func (o *OllamaAgent) Ask(ctx context.Context, prompt string) (string, error) {
 	}()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("ollama returned status %d", resp.StatusCode)
+		return "", &OllamaStatusError{StatusCode: resp.StatusCode}
 	}
 
 	var ollamaResp OllamaResponse
@@ -87,3 +111,58 @@ func (o *OllamaAgent) Ask(ctx context.Context, prompt string) (string, error) {
 
 	return ollamaResp.Response, nil
 }
+
+// AskStream implements the StreamingAgent interface by asking Ollama to stream the answer, which it sends as one JSON
+// object per line.
+func (o *OllamaAgent) AskStream(ctx context.Context, prompt string, onChunk func(chunk string) error) (string, error) {
+	jsonData, err := json.Marshal(OllamaRequest{
+		Model:  o.model,
+		Prompt: prompt,
+		Stream: true,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", o.baseURL+"/api/generate", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := o.httpClient.Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request to Ollama: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			// Log the error but don't fail the request
+			fmt.Printf("Warning: failed to close response body: %v\n", closeErr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", &OllamaStatusError{StatusCode: resp.StatusCode}
+	}
+
+	var answer strings.Builder
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var ollamaResp OllamaResponse
+		if err := decoder.Decode(&ollamaResp); err != nil {
+			return answer.String(), fmt.Errorf("failed to decode response: %w", err)
+		}
+		if ollamaResp.Error != "" {
+			return answer.String(), fmt.Errorf("ollama error: %s", ollamaResp.Error)
+		}
+		if ollamaResp.Response != "" {
+			answer.WriteString(ollamaResp.Response)
+			if err := onChunk(ollamaResp.Response); err != nil {
+				return answer.String(), err
+			}
+		}
+		if ollamaResp.Done {
+			return answer.String(), nil
+		}
+	}
+}
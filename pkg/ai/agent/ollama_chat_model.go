@@ -0,0 +1,124 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+)
+
+// OllamaChatModel implements the ChatModel interface with Ollama's chat API and its tool calling.
+type OllamaChatModel struct {
+	baseURL    string
+	model      string
+	httpClient *http.Client
+}
+
+type ollamaChatRequest struct {
+	Model    string              `json:"model"`
+	Messages []ollamaChatMessage `json:"messages"`
+	Tools    []ollamaTool        `json:"tools,omitempty"`
+	Stream   bool                `json:"stream"`
+}
+
+type ollamaChatMessage struct {
+	Role      string           `json:"role"`
+	Content   string           `json:"content"`
+	ToolCalls []ollamaToolCall `json:"tool_calls,omitempty"`
+	ToolName  string           `json:"tool_name,omitempty"`
+}
+
+type ollamaToolCall struct {
+	Function struct {
+		Name      string         `json:"name"`
+		Arguments map[string]any `json:"arguments"`
+	} `json:"function"`
+}
+
+type ollamaTool struct {
+	Type     string         `json:"type"`
+	Function ToolDefinition `json:"function"`
+}
+
+type ollamaChatResponse struct {
+	Message         ollamaChatMessage `json:"message"`
+	PromptEvalCount int               `json:"prompt_eval_count,omitempty"`
+	EvalCount       int               `json:"eval_count,omitempty"`
+	Error           string            `json:"error,omitempty"`
+}
+
+// NewOllamaChatModel creates a new instance of OllamaChatModel. Calls are bounded by the deadline of their context,
+// since a local model may take minutes to answer.
+func NewOllamaChatModel(baseURL, model string) ChatModel {
+	return &OllamaChatModel{
+		baseURL:    baseURL,
+		model:      model,
+		httpClient: &http.Client{},
+	}
+}
+
+// Chat implements the ChatModel interface.
+func (o *OllamaChatModel) Chat(ctx context.Context, messages []ChatMessage, tools []ToolDefinition) (ChatMessage, error) {
+	req := ollamaChatRequest{Model: o.model, Stream: false}
+	for _, message := range messages {
+		wire := ollamaChatMessage{Role: message.Role, Content: message.Content, ToolName: message.ToolName}
+		for _, call := range message.ToolCalls {
+			var toolCall ollamaToolCall
+			toolCall.Function.Name = call.Name
+			toolCall.Function.Arguments = call.Arguments
+			wire.ToolCalls = append(wire.ToolCalls, toolCall)
+		}
+		req.Messages = append(req.Messages, wire)
+	}
+	for _, tool := range tools {
+		req.Tools = append(req.Tools, ollamaTool{Type: "function", Function: tool})
+	}
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return ChatMessage{}, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, o.baseURL+"/api/chat", bytes.NewReader(data))
+	if err != nil {
+		return ChatMessage{}, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := o.httpClient.Do(httpReq)
+	if err != nil {
+		return ChatMessage{}, fmt.Errorf("failed to send request to Ollama: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close Ollama response body", "error", closeErr)
+		}
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return ChatMessage{}, fmt.Errorf("failed to read Ollama response: %w", err)
+	}
+	var chatResp ollamaChatResponse
+	if err := json.Unmarshal(body, &chatResp); err != nil && resp.StatusCode == http.StatusOK {
+		return ChatMessage{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return ChatMessage{}, &OllamaStatusError{StatusCode: resp.StatusCode, Message: chatResp.Error}
+	}
+	if chatResp.Error != "" {
+		return ChatMessage{}, fmt.Errorf("ollama error: %s", chatResp.Error)
+	}
+
+	message := ChatMessage{Role: chatResp.Message.Role, Content: chatResp.Message.Content}
+	if chatResp.PromptEvalCount > 0 || chatResp.EvalCount > 0 {
+		message.Usage = &TokenUsage{PromptTokens: chatResp.PromptEvalCount, ResponseTokens: chatResp.EvalCount}
+	}
+	for _, call := range chatResp.Message.ToolCalls {
+		message.ToolCalls = append(message.ToolCalls, ToolCall{Name: call.Function.Name, Arguments: call.Function.Arguments})
+	}
+	return message, nil
+}
@@ -0,0 +1,37 @@
+package agent
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/resilience"
+)
+
+// ResilientAgent guards the calls to another agent with a circuit breaker, retrying the prompts that fail
+// transiently. Prompts have no side effects, so they're safe to retry.
+type ResilientAgent struct {
+	agent Agent
+	guard *resilience.Guard
+}
+
+// NewResilientAgent creates an agent guarding the calls to agent with guard
+func NewResilientAgent(agent Agent, guard *resilience.Guard) Agent {
+	return &ResilientAgent{
+		agent: agent,
+		guard: guard,
+	}
+}
+
+// Ask for prompt from the agent.
+func (a *ResilientAgent) Ask(ctx context.Context, prompt string) (string, error) {
+	return resilience.Call(ctx, a.guard, func(ctx context.Context) (string, error) {
+		return a.agent.Ask(ctx, prompt)
+	})
+}
+
+// AskStream implements the StreamingAgent interface. Streamed prompts aren't retried, since part of their answer may
+// already have been passed on.
+func (a *ResilientAgent) AskStream(ctx context.Context, prompt string, onChunk func(chunk string) error) (string, error) {
+	return resilience.CallOnce(ctx, a.guard, func(ctx context.Context) (string, error) {
+		return AskStream(ctx, a.agent, prompt, onChunk)
+	})
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/rag"
 	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/storage"
 	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/redact"
 )
 
 const (
@@ -20,31 +21,48 @@ const (
 
 // BedrockRAGBuilder is an implementation of RAGBuilder that uses AWS Bedrock for building the RAG pipeline.
 type BedrockRAGBuilder struct {
-	repoPath  string
-	dataStore storage.DataStore
-	storage   storage.Storage
-	rag       rag.RAG
+	repoPath   string
+	dataStore  storage.DataStore
+	dataSource storage.DataSource
+	storage    storage.Storage
+	dimensions int32
+	rag        rag.RAG
+	ingester   storage.Ingester
+	redactor   *redact.Redactor
+
+	// redactions counts the content masked by the last Build
+	redactions redact.Report
 }
 
-// NewBedrockRAGBuilder creates a new instance of BedrockRAGBuilder.
+// NewBedrockRAGBuilder creates a new instance of BedrockRAGBuilder. The storage holds vectors of the given dimensions,
+// those of the knowledge base's embedding model. The redactor masks the repository before it is uploaded; a nil
+// redactor uploads it unchanged.
 func NewBedrockRAGBuilder(
 	repoPath string,
 	dataStore storage.DataStore,
+	dataSource storage.DataSource,
 	storage storage.Storage,
+	dimensions int32,
 	rag rag.RAG,
+	ingester storage.Ingester,
+	redactor *redact.Redactor,
 ) RAGBuilder {
 	return &BedrockRAGBuilder{
-		repoPath:  repoPath,
-		dataStore: dataStore,
-		storage:   storage,
-		rag:       rag,
+		repoPath:   repoPath,
+		dataStore:  dataStore,
+		dataSource: dataSource,
+		storage:    storage,
+		dimensions: dimensions,
+		rag:        rag,
+		ingester:   ingester,
+		redactor:   redactor,
 	}
 }
 
 // Build implements RAGBuilder.
-func (b BedrockRAGBuilder) Build(ctx context.Context) (string, error) {
+func (b *BedrockRAGBuilder) Build(ctx context.Context) (string, error) {
 	// Invoke Ensure RDS Postgres Schema Lambda
-	err := b.storage.EnsureSchema(ctx, config.CodeRefactoringDatabaseName, b.getRDSTableName())
+	err := b.storage.EnsureSchema(ctx, config.CodeRefactoringDatabaseName, b.getRDSTableName(), b.dimensions)
 	if err != nil {
 		return "", fmt.Errorf("failed to ensure storage schema: %w", err)
 	}
@@ -55,31 +73,48 @@ func (b BedrockRAGBuilder) Build(ctx context.Context) (string, error) {
 		return "", fmt.Errorf("failed to create RAG pipeline: %w", err)
 	}
 
-	// Upload the codebase to S3 if not already done
-	err = b.dataStore.UploadDirectory(ctx, b.repoPath, b.repoPath)
+	// Mask personal data and credentials before the content leaves the service
+	b.redactions, err = b.redactor.RedactDirectory(ctx, b.repoPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to redact codebase: %w", err)
+	}
+
+	// Upload the codebase to S3 under the prefix of the knowledge base, which its data source reads and a purge deletes
+	err = b.dataStore.UploadDirectory(ctx, b.repoPath, storage.KnowledgeBasePrefix(kbID), uploadProgressLogger(ctx, kbID))
 	if err != nil {
 		return "", fmt.Errorf("failed to upload codebase to S3: %w", err)
 	}
 
 	// Create data source for the codebase in the RAG pipeline
-	_, err = b.dataStore.Create(ctx, kbID)
+	_, err = b.dataSource.Create(ctx, kbID)
 	if err != nil {
 		return "", fmt.Errorf("failed to create data source: %w", err)
 	}
 
+	// Index the uploaded codebase into the knowledge base
+	_, err = b.ingester.StartIngestion(ctx, kbID)
+	if err != nil {
+		return "", fmt.Errorf("failed to start knowledge base ingestion: %w", err)
+	}
+
 	return kbID, nil
 }
 
+// Redactions implements RedactionReporter.
+func (b *BedrockRAGBuilder) Redactions() redact.Report {
+	return b.redactions
+}
+
 // TearDown implements RAGBuilder.
-func (b BedrockRAGBuilder) TearDown(ctx context.Context, vectorStoreID string, ragID string) error {
+func (b *BedrockRAGBuilder) TearDown(ctx context.Context, vectorStoreID string, ragID string) error {
 	// Delete the data source from the RAG pipeline if it exists
-	err := b.dataStore.Delete(ctx, vectorStoreID, ragID)
+	err := b.dataSource.Delete(ctx, vectorStoreID, ragID)
 	if err != nil {
 		return fmt.Errorf("failed to delete data source: %w", err)
 	}
 
 	// Remove the codebase from S3 if needed
-	err = b.dataStore.DeleteDirectory(ctx, b.repoPath)
+	err = b.dataStore.DeleteDirectory(ctx, storage.KnowledgeBasePrefix(ragID), nil)
 	if err != nil {
 		return fmt.Errorf("failed to remove codebase from S3: %w", err)
 	}
@@ -95,7 +130,7 @@ func (b BedrockRAGBuilder) TearDown(ctx context.Context, vectorStoreID string, r
 
 // getRDSTableName returns the name of the RDS table used for vector storage.
 // It sanitizes the repo path to create a valid SQL identifier.
-func (b BedrockRAGBuilder) getRDSTableName() string {
+func (b *BedrockRAGBuilder) getRDSTableName() string {
 	// Get the base name from the repo path
 	baseName := filepath.Base(b.repoPath)
 
@@ -2,12 +2,17 @@ package builder_test
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/golang/mock/gomock"
 	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/builder"
 	mocks_rag "github.com/kazemisoroush/code-refactoring-tool/pkg/ai/rag/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/storage"
 	mocks_storage "github.com/kazemisoroush/code-refactoring-tool/pkg/ai/storage/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/redact"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -22,20 +27,28 @@ func TestBedrockRAGBuilder_Build(t *testing.T) {
 	repoPath := "test-repo-path"
 
 	dataStore := mocks_storage.NewMockDataStore(ctrl)
-	dataStore.EXPECT().UploadDirectory(ctx, "test-repo-path", "test-repo-path").Return(nil).Times(1)
-	dataStore.EXPECT().Create(ctx, gomock.Any()).Return("test-data-source-id", nil).Times(1)
+	dataStore.EXPECT().UploadDirectory(ctx, "test-repo-path", "knowledge-bases/test-kb-id/", gomock.Any()).Return(nil).Times(1)
+	dataSource := mocks_storage.NewMockDataSource(ctrl)
+	dataSource.EXPECT().Create(ctx, gomock.Any()).Return("test-data-source-id", nil).Times(1)
 
 	storage := mocks_storage.NewMockStorage(ctrl)
-	storage.EXPECT().EnsureSchema(ctx, "code_refactoring_db", "test_repo_path").Return(nil).Times(1)
+	storage.EXPECT().EnsureSchema(ctx, "code_refactoring_db", "test_repo_path", int32(1536)).Return(nil).Times(1)
 
 	rag := mocks_rag.NewMockRAG(ctrl)
 	rag.EXPECT().Create(ctx, gomock.Any()).Return("test-kb-id", nil).Times(1)
 
+	ingester := mocks_storage.NewMockIngester(ctrl)
+	ingester.EXPECT().StartIngestion(ctx, "test-kb-id").Return(nil, nil).Times(1)
+
 	builder := builder.NewBedrockRAGBuilder(
 		repoPath,
 		dataStore,
+		dataSource,
 		storage,
+		1536,
 		rag,
+		ingester,
+		nil,
 	)
 
 	// Act
@@ -46,6 +59,75 @@ func TestBedrockRAGBuilder_Build(t *testing.T) {
 	require.NotNil(t, ragMetadata, "RAG metadata should not be nil")
 }
 
+func TestBedrockRAGBuilder_Build_RedactsBeforeUpload(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repoPath := filepath.Join(t.TempDir(), "repo")
+	require.NoError(t, os.MkdirAll(repoPath, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(repoPath, "README.md"), []byte("Contact: team@example.com\n"), 0644))
+
+	dataStore := mocks_storage.NewMockDataStore(ctrl)
+	dataStore.EXPECT().UploadDirectory(ctx, repoPath, "knowledge-bases/test-kb-id/", gomock.Any()).DoAndReturn(func(_ context.Context, localPath, _ string, _ func(storage.UploadProgress)) error {
+		content, err := os.ReadFile(filepath.Join(localPath, "README.md"))
+		require.NoError(t, err)
+		assert.Equal(t, "Contact: [REDACTED:email]\n", string(content))
+		return nil
+	}).Times(1)
+	dataSource := mocks_storage.NewMockDataSource(ctrl)
+	dataSource.EXPECT().Create(ctx, gomock.Any()).Return("test-data-source-id", nil).Times(1)
+
+	storage := mocks_storage.NewMockStorage(ctrl)
+	storage.EXPECT().EnsureSchema(ctx, "code_refactoring_db", "repo", int32(1024)).Return(nil).Times(1)
+
+	rag := mocks_rag.NewMockRAG(ctrl)
+	rag.EXPECT().Create(ctx, gomock.Any()).Return("test-kb-id", nil).Times(1)
+
+	ingester := mocks_storage.NewMockIngester(ctrl)
+	ingester.EXPECT().StartIngestion(ctx, "test-kb-id").Return(nil, nil).Times(1)
+
+	redactor, err := redact.NewRedactor(redact.DefaultPolicy())
+	require.NoError(t, err)
+
+	ragBuilder := builder.NewBedrockRAGBuilder(repoPath, dataStore, dataSource, storage, 1024, rag, ingester, redactor)
+
+	// Act
+	_, err = ragBuilder.Build(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	reporter, ok := ragBuilder.(builder.RedactionReporter)
+	require.True(t, ok)
+	assert.Equal(t, map[string]int{redact.RuleEmail: 1}, reporter.Redactions().Counts)
+	assert.Equal(t, 1, reporter.Redactions().FilesRedacted)
+}
+
+func TestBedrockRAGBuilder_TearDown(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dataSource := mocks_storage.NewMockDataSource(ctrl)
+	dataSource.EXPECT().Delete(ctx, "test-vector-store-id", "test-kb-id").Return(nil).Times(1)
+	dataStore := mocks_storage.NewMockDataStore(ctrl)
+	dataStore.EXPECT().DeleteDirectory(ctx, "knowledge-bases/test-kb-id/", gomock.Nil()).Return(nil).Times(1)
+
+	rag := mocks_rag.NewMockRAG(ctrl)
+	rag.EXPECT().Delete(ctx, "test-vector-store-id").Return(nil).Times(1)
+
+	ragBuilder := builder.NewBedrockRAGBuilder("test-repo-path", dataStore, dataSource, mocks_storage.NewMockStorage(ctrl), 1536, rag,
+		mocks_storage.NewMockIngester(ctrl), nil)
+
+	// Act
+	err := ragBuilder.TearDown(ctx, "test-vector-store-id", "test-kb-id")
+
+	// Assert
+	require.NoError(t, err)
+}
+
 func TestBedrockRAGBuilder_TableNameSanitization(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -81,14 +163,20 @@ func TestBedrockRAGBuilder_TableNameSanitization(t *testing.T) {
 
 			// Create mocks (won't be used in this test)
 			dataStore := mocks_storage.NewMockDataStore(ctrl)
+			dataSource := mocks_storage.NewMockDataSource(ctrl)
 			storage := mocks_storage.NewMockStorage(ctrl)
 			rag := mocks_rag.NewMockRAG(ctrl)
+			ingester := mocks_storage.NewMockIngester(ctrl)
 
 			builder := builder.NewBedrockRAGBuilder(
 				tt.repoPath,
 				dataStore,
+				dataSource,
 				storage,
+				1536,
 				rag,
+				ingester,
+				nil,
 			)
 
 			// Use reflection to access the private method for testing
@@ -96,10 +184,11 @@ func TestBedrockRAGBuilder_TableNameSanitization(t *testing.T) {
 			// that EnsureSchema is called with the expected sanitized name
 			ctx := context.Background()
 
-			storage.EXPECT().EnsureSchema(ctx, "code_refactoring_db", tt.expectedName).Return(nil).Times(1)
-			dataStore.EXPECT().UploadDirectory(ctx, tt.repoPath, tt.repoPath).Return(nil).Times(1)
-			dataStore.EXPECT().Create(ctx, gomock.Any()).Return("test-data-source-id", nil).Times(1)
+			storage.EXPECT().EnsureSchema(ctx, "code_refactoring_db", tt.expectedName, int32(1536)).Return(nil).Times(1)
+			dataStore.EXPECT().UploadDirectory(ctx, tt.repoPath, "knowledge-bases/test-kb-id/", gomock.Any()).Return(nil).Times(1)
+			dataSource.EXPECT().Create(ctx, gomock.Any()).Return("test-data-source-id", nil).Times(1)
 			rag.EXPECT().Create(ctx, tt.expectedName).Return("test-kb-id", nil).Times(1)
+			ingester.EXPECT().StartIngestion(ctx, "test-kb-id").Return(nil, nil).Times(1)
 
 			_, err := builder.Build(ctx)
 			require.NoError(t, err)
@@ -9,6 +9,8 @@ import (
 	"strings"
 
 	"github.com/google/uuid"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/storage"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/redact"
 )
 
 // LocalRAGBuilder implements the RAGBuilder interface for local development.
@@ -16,14 +18,23 @@ type LocalRAGBuilder struct {
 	repoPath       string
 	chromaURL      string
 	embeddingModel string
+	dataStore      storage.DataStore
+	redactor       *redact.Redactor
+
+	// redactions counts the content masked by the last Build
+	redactions redact.Report
 }
 
-// NewLocalRAGBuilder creates a new instance of LocalRAGBuilder.
-func NewLocalRAGBuilder(repoPath, chromaURL, embeddingModel string) RAGBuilder {
+// NewLocalRAGBuilder creates a new instance of LocalRAGBuilder. The content embedded is kept in dataStore under the
+// prefix of its knowledge base. The redactor masks the repository before it is embedded; a nil redactor embeds it
+// unchanged.
+func NewLocalRAGBuilder(repoPath, chromaURL, embeddingModel string, dataStore storage.DataStore, redactor *redact.Redactor) RAGBuilder {
 	return &LocalRAGBuilder{
 		repoPath:       repoPath,
 		chromaURL:      chromaURL,
 		embeddingModel: embeddingModel,
+		dataStore:      dataStore,
+		redactor:       redactor,
 	}
 }
 
@@ -40,8 +51,21 @@ func (l *LocalRAGBuilder) Build(ctx context.Context) (string, error) {
 
 	fmt.Printf("Building local RAG pipeline for repository: %s\n", l.repoPath)
 
+	// Mask personal data and credentials before the content is embedded
+	var err error
+	l.redactions, err = l.redactor.RedactDirectory(ctx, l.repoPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to redact repository: %w", err)
+	}
+
+	// Keep the content embedded under the prefix of the knowledge base, which its teardown deletes
+	err = l.dataStore.UploadDirectory(ctx, l.repoPath, storage.KnowledgeBasePrefix(ragID), uploadProgressLogger(ctx, ragID))
+	if err != nil {
+		return "", fmt.Errorf("failed to upload repository to the data store: %w", err)
+	}
+
 	// Simulate scanning the repository
-	err := l.scanRepository(ctx)
+	err = l.scanRepository(ctx)
 	if err != nil {
 		return "", fmt.Errorf("failed to scan repository: %w", err)
 	}
@@ -51,13 +75,23 @@ func (l *LocalRAGBuilder) Build(ctx context.Context) (string, error) {
 	return ragID, nil
 }
 
+// Redactions implements the RedactionReporter interface.
+func (l *LocalRAGBuilder) Redactions() redact.Report {
+	return l.redactions
+}
+
 // TearDown implements the RAGBuilder interface by cleaning up local RAG resources.
-func (l *LocalRAGBuilder) TearDown(_ context.Context, vectorStoreID string, ragID string) error {
+func (l *LocalRAGBuilder) TearDown(ctx context.Context, vectorStoreID string, ragID string) error {
 	// In a real implementation, you would:
 	// 1. Delete the ChromaDB collection
 	// 2. Clean up any temporary files
 	// 3. Remove cached embeddings
 
+	// Remove the content embedded from the data store
+	if err := l.dataStore.DeleteDirectory(ctx, storage.KnowledgeBasePrefix(ragID), nil); err != nil {
+		return fmt.Errorf("failed to remove repository from the data store: %w", err)
+	}
+
 	fmt.Printf("Local RAG pipeline torn down - vector store: %s, RAG ID: %s\n", vectorStoreID, ragID)
 
 	return nil
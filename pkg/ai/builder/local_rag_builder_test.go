@@ -0,0 +1,60 @@
+package builder_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/builder"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/storage"
+	mocks_storage "github.com/kazemisoroush/code-refactoring-tool/pkg/ai/storage/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/redact"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalRAGBuilder_Build_UploadsRedactedContent(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repoPath := filepath.Join(t.TempDir(), "repo")
+	require.NoError(t, os.MkdirAll(repoPath, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(repoPath, "README.md"), []byte("Contact: team@example.com\n"), 0644))
+
+	redactor, err := redact.NewRedactor(redact.DefaultPolicy())
+	require.NoError(t, err)
+	dataStore := storage.NewFilesystemDataStore(t.TempDir(), nil)
+	ragBuilder := builder.NewLocalRAGBuilder(repoPath, "http://localhost:8000", "all-MiniLM-L6-v2", dataStore, redactor)
+
+	// Act
+	ragID, err := ragBuilder.Build(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	directories, err := dataStore.ListDirectories(ctx, storage.KnowledgeBasesPrefix)
+	require.NoError(t, err)
+	assert.Equal(t, []string{storage.KnowledgeBasePrefix(ragID)}, directories)
+
+	require.NoError(t, ragBuilder.TearDown(ctx, ragID, ragID))
+	directories, err = dataStore.ListDirectories(ctx, storage.KnowledgeBasesPrefix)
+	require.NoError(t, err)
+	assert.Empty(t, directories)
+}
+
+func TestLocalRAGBuilder_Build_UploadFails(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dataStore := mocks_storage.NewMockDataStore(ctrl)
+	dataStore.EXPECT().UploadDirectory(ctx, "test-repo-path", gomock.Any(), gomock.Any()).Return(assert.AnError).Times(1)
+	ragBuilder := builder.NewLocalRAGBuilder("test-repo-path", "http://localhost:8000", "all-MiniLM-L6-v2", dataStore, nil)
+
+	// Act
+	_, err := ragBuilder.Build(ctx)
+
+	// Assert
+	assert.ErrorIs(t, err, assert.AnError)
+}
@@ -3,6 +3,10 @@ package builder
 
 import (
 	"context"
+	"log/slog"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/storage"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/redact"
 )
 
 // RAGBuilder is responsible for building a RAG pipeline for a given code repository.
@@ -15,3 +19,24 @@ type RAGBuilder interface {
 	// TearDown cleans up any resources created during the RAG pipeline setup.
 	TearDown(ctx context.Context, vectorStoreID string, ragID string) error
 }
+
+// RedactionReporter is implemented by RAG builders that mask content before embedding it.
+type RedactionReporter interface {
+	// Redactions counts the content masked by the last Build.
+	Redactions() redact.Report
+}
+
+// uploadProgressLogger returns the progress of the upload of a knowledge base's content, logging each tenth of its
+// files uploaded
+func uploadProgressLogger(ctx context.Context, knowledgeBaseID string) func(storage.UploadProgress) {
+	logged := 0
+	return func(progress storage.UploadProgress) {
+		tenth := progress.Files * 10 / max(progress.TotalFiles, 1)
+		if tenth <= logged {
+			return
+		}
+		logged = tenth
+		slog.InfoContext(ctx, "Uploading knowledge base content", "knowledgeBaseID", knowledgeBaseID,
+			"files", progress.Files, "totalFiles", progress.TotalFiles, "bytes", progress.Bytes, "totalBytes", progress.TotalBytes)
+	}
+}
@@ -0,0 +1,227 @@
+package contextpack
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Kind names what a section of context holds
+type Kind string
+
+const (
+	// KindInstructions is the description of the task the model works on
+	KindInstructions Kind = "instructions"
+
+	// KindDiff is a snippet of a diff the task is about
+	KindDiff Kind = "diff"
+
+	// KindHistory summarizes the git history of the files of the other sections, such as how often they change
+	KindHistory Kind = "history"
+
+	// KindChunk is a chunk retrieved from the knowledge base of the task's agent
+	KindChunk Kind = "chunk"
+)
+
+// kindPriority orders the optional sections of different kinds: diffs are what a task is about, so they are packed
+// ahead of the history of their files and of the chunks retrieved around them
+var kindPriority = map[Kind]int{
+	KindInstructions: 0,
+	KindDiff:         1,
+	KindHistory:      2,
+	KindChunk:        3,
+}
+
+// minTruncatedTokens is the fewest tokens an optional section is cut to, below which it's left out instead
+const minTruncatedTokens = 64
+
+// truncatedMarker ends the sections cut to fit the budget
+const truncatedMarker = "\n... (truncated)"
+
+// Reasons a section was left out of the context
+const (
+	ReasonOverBudget = "over_budget"
+	ReasonDuplicate  = "duplicate"
+	ReasonEmpty      = "empty"
+	ReasonNoise      = "noise"
+)
+
+// Section is a piece of context offered to the model
+type Section struct {
+	ID       string  // Identifies the section in the trace, such as the path and lines of a chunk
+	Kind     Kind    // What the section holds
+	Text     string  // Content of the section
+	Score    float64 // Relevance of the section, ranking the sections of a kind
+	Required bool    // Whether the section is packed ahead of all others, truncated rather than left out
+	Noise    bool    // Whether the section holds a noise file, such as a lockfile, traced but never packed
+}
+
+// TraceEntry records whether a section was packed into the context
+type TraceEntry struct {
+	ID        string  `json:"id"`
+	Kind      Kind    `json:"kind"`
+	Score     float64 `json:"score,omitempty"`
+	Tokens    int     `json:"tokens"`              // Tokens of the section as packed, or of the whole section when left out
+	Included  bool    `json:"included"`            // Whether the section is in the context
+	Truncated bool    `json:"truncated,omitempty"` // Whether the section was cut to fit the budget
+	Reason    string  `json:"reason,omitempty"`    // Why the section was left out
+}
+
+// Trace records how a context was packed, listing its sections in the order they were considered
+type Trace struct {
+	Model         string       `json:"model"`
+	ContextWindow int          `json:"context_window"`
+	Budget        int          `json:"budget"`      // Tokens the sections could take
+	UsedTokens    int          `json:"used_tokens"` // Tokens the packed sections take
+	Sections      []TraceEntry `json:"sections"`
+}
+
+// Assembler packs sections of context under the token budget of a model
+type Assembler struct {
+	registry *TokenizerRegistry
+}
+
+// NewAssembler creates an assembler resolving the limits of models with registry
+func NewAssembler(registry *TokenizerRegistry) *Assembler {
+	return &Assembler{registry: registry}
+}
+
+// Limits returns the limits of a model
+func (a *Assembler) Limits(model string) ModelLimits {
+	return a.registry.Lookup(model)
+}
+
+// Assemble packs sections into the context of a model, whose budget is reduced by the tokens reserved for the rest of
+// the prompt, such as its system prompt. The rules are deterministic, so the same sections always pack the same way:
+//
+//   - Required sections come first, in their order. When they don't fit, the last ones are cut at a line boundary and
+//     no other section is packed.
+//   - Other sections are ranked by kind, diffs ahead of chunks, then by descending score and by ID. A section with the
+//     same text as one ranked ahead of it is left out.
+//   - Noise sections are left out, recording the tokens they would have taken.
+//   - Each section is packed whole if it fits, or cut at a line boundary to the tokens left when at least
+//     minTruncatedTokens are. Otherwise it's left out, and the next sections, which may be smaller, are still tried.
+func (a *Assembler) Assemble(model string, reserved int, sections []Section) (string, Trace) {
+	limits := a.registry.Lookup(model)
+	trace := Trace{
+		Model:         model,
+		ContextWindow: limits.ContextWindow,
+		Budget:        max(limits.Budget()-reserved, 0),
+		Sections:      make([]TraceEntry, 0, len(sections)),
+	}
+
+	packer := &packer{tokenizer: limits.Tokenizer, remaining: trace.Budget, seen: make(map[string]bool)}
+	for _, section := range rank(sections) {
+		trace.Sections = append(trace.Sections, packer.pack(section))
+	}
+	trace.UsedTokens = trace.Budget - packer.remaining
+
+	return strings.Join(packer.packed, "\n\n"), trace
+}
+
+// rank orders sections for packing, required sections first in their order
+func rank(sections []Section) []Section {
+	ranked := make([]Section, len(sections))
+	copy(ranked, sections)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.Required != b.Required {
+			return a.Required
+		}
+		if a.Required {
+			return false
+		}
+		if kindPriority[a.Kind] != kindPriority[b.Kind] {
+			return kindPriority[a.Kind] < kindPriority[b.Kind]
+		}
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.ID < b.ID
+	})
+	return ranked
+}
+
+// packer fills a budget with the rendered sections
+type packer struct {
+	tokenizer Tokenizer
+	remaining int
+	seen      map[string]bool
+	packed    []string
+}
+
+// pack packs a section if it fits, returning its trace entry
+func (p *packer) pack(section Section) TraceEntry {
+	entry := TraceEntry{ID: section.ID, Kind: section.Kind, Score: section.Score}
+
+	text := strings.TrimSpace(section.Text)
+	if text == "" {
+		entry.Reason = ReasonEmpty
+		return entry
+	}
+	if p.seen[text] {
+		entry.Reason = ReasonDuplicate
+		return entry
+	}
+	p.seen[text] = true
+
+	rendered := render(section.Kind, section.ID, text)
+	entry.Tokens = p.tokenizer.Count(rendered)
+	if section.Noise {
+		entry.Reason = ReasonNoise
+		return entry
+	}
+	if entry.Tokens > p.remaining {
+		if !section.Required && p.remaining < minTruncatedTokens {
+			entry.Reason = ReasonOverBudget
+			return entry
+		}
+		rendered = p.truncate(section.Kind, section.ID, text)
+		if rendered == "" {
+			entry.Reason = ReasonOverBudget
+			return entry
+		}
+		entry.Truncated = true
+		entry.Tokens = p.tokenizer.Count(rendered)
+	}
+
+	entry.Included = true
+	p.remaining -= entry.Tokens
+	p.packed = append(p.packed, rendered)
+	return entry
+}
+
+// truncate renders the most leading lines of a section that fit the tokens left, or nothing when no line does
+func (p *packer) truncate(kind Kind, id, text string) string {
+	lines := strings.Split(text, "\n")
+
+	// The rendered size grows with the lines kept, so the most lines that fit are found by bisection
+	fits := func(n int) bool {
+		return p.tokenizer.Count(render(kind, id, strings.Join(lines[:n], "\n")+truncatedMarker)) <= p.remaining
+	}
+	low, high := 0, len(lines)
+	for low < high {
+		mid := (low + high + 1) / 2
+		if fits(mid) {
+			low = mid
+		} else {
+			high = mid - 1
+		}
+	}
+	if low == 0 {
+		return ""
+	}
+	return render(kind, id, strings.Join(lines[:low], "\n")+truncatedMarker)
+}
+
+// render formats a section as it appears in the context
+func render(kind Kind, id, text string) string {
+	switch kind {
+	case KindDiff:
+		return fmt.Sprintf("Diff of %s:\n```diff\n%s\n```", id, text)
+	case KindChunk:
+		return fmt.Sprintf("Context from %s:\n```\n%s\n```", id, text)
+	default:
+		return text
+	}
+}
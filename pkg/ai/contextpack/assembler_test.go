@@ -0,0 +1,138 @@
+package contextpack
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// wordTokenizer counts every whitespace separated word as a token, so budgets are easy to reason about
+type wordTokenizer struct{}
+
+func (wordTokenizer) Count(text string) int {
+	return len(strings.Fields(text))
+}
+
+func newTestAssembler(window int) *Assembler {
+	registry := NewTokenizerRegistry(ModelLimits{Tokenizer: wordTokenizer{}, ContextWindow: window})
+	return NewAssembler(registry)
+}
+
+func words(n int) string {
+	return strings.TrimSpace(strings.Repeat("word\n", n))
+}
+
+func TestAssembler_Assemble_RanksAndPacks(t *testing.T) {
+	assembler := newTestAssembler(1000)
+
+	context, trace := assembler.Assemble("model", 0, []Section{
+		{ID: "b.go:1-2", Kind: KindChunk, Text: "chunk b", Score: 0.5},
+		{ID: "a.go:1-2", Kind: KindChunk, Text: "chunk a", Score: 0.5},
+		{ID: "c.go:1-2", Kind: KindChunk, Text: "chunk c", Score: 0.9},
+		{ID: "main.go", Kind: KindDiff, Text: "+added", Score: 0},
+		{ID: "task", Kind: KindInstructions, Text: "Fix the bug", Required: true},
+		{ID: "d.go:1-2", Kind: KindChunk, Text: "chunk c", Score: 0.1},
+	})
+
+	var order []string
+	for _, entry := range trace.Sections {
+		order = append(order, entry.ID)
+	}
+	assert.Equal(t, []string{"task", "main.go", "c.go:1-2", "a.go:1-2", "b.go:1-2", "d.go:1-2"}, order)
+	assert.Equal(t, ReasonDuplicate, trace.Sections[5].Reason)
+	assert.True(t, strings.HasPrefix(context, "Fix the bug\n\nDiff of main.go:"))
+	assert.Equal(t, 1000, trace.Budget)
+	assert.Positive(t, trace.UsedTokens)
+}
+
+func TestAssembler_Assemble_TruncatesAndExcludes(t *testing.T) {
+	assembler := newTestAssembler(300)
+
+	_, trace := assembler.Assemble("model", 50, []Section{
+		{ID: "task", Kind: KindInstructions, Text: words(20), Required: true},
+		{ID: "big.go", Kind: KindChunk, Text: words(500), Score: 0.9},
+		{ID: "medium.go", Kind: KindChunk, Text: words(150), Score: 0.8},
+		{ID: "small.go", Kind: KindChunk, Text: words(10), Score: 0.7},
+	})
+
+	require.Len(t, trace.Sections, 4)
+	assert.Equal(t, 250, trace.Budget, "the reserved tokens reduce the budget")
+
+	// The first chunk too big to fit is cut to the tokens left
+	big := trace.Sections[1]
+	assert.True(t, big.Included)
+	assert.True(t, big.Truncated)
+
+	// Too few tokens are left to cut the next chunks
+	assert.False(t, trace.Sections[2].Included)
+	assert.Equal(t, ReasonOverBudget, trace.Sections[2].Reason)
+	assert.Equal(t, ReasonOverBudget, trace.Sections[3].Reason)
+	assert.LessOrEqual(t, trace.UsedTokens, trace.Budget)
+}
+
+func TestAssembler_Assemble_LeavesOutNoise(t *testing.T) {
+	assembler := newTestAssembler(1000)
+
+	context, trace := assembler.Assemble("model", 0, []Section{
+		{ID: "task", Kind: KindInstructions, Text: "Fix the bug", Required: true},
+		{ID: "go.sum", Kind: KindDiff, Text: "+github.com/acme/lib v1.0.0 h1:abc=", Noise: true},
+		{ID: "main.go", Kind: KindDiff, Text: "+added"},
+	})
+
+	require.Len(t, trace.Sections, 3)
+	assert.False(t, trace.Sections[1].Included)
+	assert.Equal(t, ReasonNoise, trace.Sections[1].Reason)
+	assert.Positive(t, trace.Sections[1].Tokens, "the tokens saved are traced")
+	assert.True(t, trace.Sections[2].Included)
+	assert.NotContains(t, context, "go.sum")
+}
+
+func TestAssembler_Assemble_TruncatesRequiredSections(t *testing.T) {
+	assembler := newTestAssembler(100)
+
+	context, trace := assembler.Assemble("model", 0, []Section{
+		{ID: "task", Kind: KindInstructions, Text: words(200), Required: true},
+		{ID: "a.go", Kind: KindChunk, Text: "chunk", Score: 1},
+	})
+
+	assert.True(t, trace.Sections[0].Included)
+	assert.True(t, trace.Sections[0].Truncated)
+	assert.True(t, strings.HasSuffix(context, truncatedMarker))
+	assert.False(t, trace.Sections[1].Included)
+	assert.LessOrEqual(t, trace.UsedTokens, 100)
+}
+
+func TestAssembler_Assemble_Deterministic(t *testing.T) {
+	assembler := NewAssembler(DefaultRegistry())
+	sections := []Section{
+		{ID: "task", Kind: KindInstructions, Text: "Refactor the handler", Required: true},
+		{ID: "x.go:1-40", Kind: KindChunk, Text: strings.Repeat("func x() {}\n", 40), Score: 0.4},
+		{ID: "y.go:1-40", Kind: KindChunk, Text: strings.Repeat("func y() {}\n", 40), Score: 0.4},
+	}
+
+	first, firstTrace := assembler.Assemble("codellama:7b-instruct", 100, sections)
+	second, secondTrace := assembler.Assemble("codellama:7b-instruct", 100, sections)
+
+	assert.Equal(t, first, second)
+	assert.Equal(t, firstTrace, secondTrace)
+}
+
+func TestTokenizerRegistry_Lookup(t *testing.T) {
+	registry := DefaultRegistry()
+
+	assert.Equal(t, 131072, registry.Lookup("llama3.1:8b").ContextWindow, "the longest prefix wins")
+	assert.Equal(t, 8192, registry.Lookup("llama3:latest").ContextWindow)
+	assert.Equal(t, 200000, registry.Lookup("anthropic.claude-3-sonnet-20240229-v1:0").ContextWindow)
+	assert.Equal(t, 4096, registry.Lookup("unknown-model").ContextWindow)
+	assert.Equal(t, 16384-4096, registry.Lookup("codellama:7b-instruct").Budget())
+}
+
+func TestHeuristicTokenizer_Count(t *testing.T) {
+	tokenizer := HeuristicTokenizer{CharsPerToken: 4}
+
+	assert.Equal(t, 0, tokenizer.Count(""))
+	assert.Equal(t, 2, tokenizer.Count("handler"), "words are split into pieces")
+	assert.Equal(t, 8, tokenizer.Count("x := f(y)\n"), "symbols and line breaks are tokens of their own")
+}
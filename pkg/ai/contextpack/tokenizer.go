@@ -0,0 +1,139 @@
+// Package contextpack assembles the context a model is prompted with under the token budget of the model, packing
+// task instructions, diff snippets and retrieved chunks by a deterministic set of rules.
+package contextpack
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Tokenizer counts the tokens a model reads text as
+type Tokenizer interface {
+	// Count returns the number of tokens of text.
+	Count(text string) int
+}
+
+// HeuristicTokenizer estimates the tokens of text the way BPE tokenizers split code: words are split into pieces of
+// CharsPerToken characters, and every line break and other non-space character is a token of its own. It
+// overestimates prose slightly, which leaves the budget some slack.
+type HeuristicTokenizer struct {
+	CharsPerToken int
+}
+
+// Count implements Tokenizer.
+func (t HeuristicTokenizer) Count(text string) int {
+	charsPerToken := max(t.CharsPerToken, 1)
+	count, word := 0, 0
+	flush := func() {
+		count += (word + charsPerToken - 1) / charsPerToken
+		word = 0
+	}
+
+	for _, r := range text {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			word++
+		case r == '\n':
+			flush()
+			count++
+		case unicode.IsSpace(r):
+			flush()
+		default:
+			flush()
+			count++
+		}
+	}
+	flush()
+
+	return count
+}
+
+// ModelLimits are the token limits of a model and the tokenizer counting its tokens
+type ModelLimits struct {
+	Tokenizer      Tokenizer
+	ContextWindow  int // Tokens the model reads and writes in a call
+	ReservedOutput int // Tokens of the window kept for the model's reply
+}
+
+// NewModelLimits returns the limits of a model with a context window, reserving a quarter of it for the model's reply,
+// up to 4096 tokens
+func NewModelLimits(tokenizer Tokenizer, contextWindow int) ModelLimits {
+	return ModelLimits{Tokenizer: tokenizer, ContextWindow: contextWindow, ReservedOutput: min(4096, contextWindow/4)}
+}
+
+// Budget returns the tokens of the window left for the prompt
+func (l ModelLimits) Budget() int {
+	return max(l.ContextWindow-l.ReservedOutput, 0)
+}
+
+// TokenizerRegistry resolves the limits of models by the longest prefix of their ID registered, falling back to the
+// limits of unknown models. It's safe for concurrent use.
+type TokenizerRegistry struct {
+	mu       sync.RWMutex
+	models   map[string]ModelLimits
+	fallback ModelLimits
+}
+
+// NewTokenizerRegistry creates a registry resolving unknown models to fallback
+func NewTokenizerRegistry(fallback ModelLimits) *TokenizerRegistry {
+	return &TokenizerRegistry{
+		models:   make(map[string]ModelLimits),
+		fallback: fallback,
+	}
+}
+
+// Register sets the limits of the models whose ID starts with prefix
+func (r *TokenizerRegistry) Register(prefix string, limits ModelLimits) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.models[prefix] = limits
+}
+
+// Lookup returns the limits of a model
+func (r *TokenizerRegistry) Lookup(model string) ModelLimits {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	limits, matched := r.fallback, ""
+	for prefix, candidate := range r.models {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(matched) {
+			limits, matched = candidate, prefix
+		}
+	}
+	return limits
+}
+
+// DefaultRegistry returns a registry of the Bedrock foundation models and the Ollama models the service runs with.
+// Ollama models are registered with the window they were trained with, which their runtime may be configured below.
+func DefaultRegistry() *TokenizerRegistry {
+	code := HeuristicTokenizer{CharsPerToken: 4}
+	llama2 := HeuristicTokenizer{CharsPerToken: 3}
+	limits := NewModelLimits
+
+	registry := NewTokenizerRegistry(limits(code, 4096))
+
+	// Bedrock foundation models
+	registry.Register("anthropic.claude", limits(code, 100000))
+	registry.Register("anthropic.claude-3", limits(code, 200000))
+	registry.Register("mistral.", limits(code, 32000))
+	registry.Register("meta.llama2", limits(llama2, 4096))
+	registry.Register("cohere.command-r", limits(code, 128000))
+	registry.Register("ai21.j2", limits(code, 8191))
+	registry.Register("amazon.titan-text-express", limits(code, 8000))
+	registry.Register("amazon.titan-text-lite", limits(code, 4000))
+
+	// Ollama models
+	registry.Register("llama3", limits(code, 8192))
+	registry.Register("llama3.1", limits(code, 131072))
+	registry.Register("codellama", limits(llama2, 16384))
+	registry.Register("mistral", limits(code, 32768))
+	registry.Register("gemma", limits(code, 8192))
+	registry.Register("phi3", limits(code, 4096))
+	registry.Register("qwen", limits(code, 32768))
+	registry.Register("deepseek-coder", limits(code, 16384))
+	registry.Register("starcoder2", limits(code, 16384))
+
+	return registry
+}
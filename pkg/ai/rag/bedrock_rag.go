@@ -27,14 +27,17 @@ type BedrockRAG struct {
 	rdsCredentialsSecretARN string
 	RDSPostgresInstanceARN  string
 	rdsPostgresDatabaseName string
+	embedding               config.Embedding
 }
 
-// NewBedrockRAG creates a new instance of BedrockRAG with the provided AWS configuration and parameters.
+// NewBedrockRAG creates a new instance of BedrockRAG with the provided AWS configuration and parameters. The
+// knowledge bases it creates embed with the model and dimensions of the resolved embedding.
 func NewBedrockRAG(
 	awsConfig aws.Config,
 	repoPath string,
 	kbRoleARN string,
 	rdsPostgres config.RDSPostgres,
+	embedding config.Embedding,
 ) RAG {
 	return &BedrockRAG{
 		kbClient:                bedrockagent.NewFromConfig(awsConfig),
@@ -43,15 +46,16 @@ func NewBedrockRAG(
 		rdsCredentialsSecretARN: rdsPostgres.CredentialsSecretARN,
 		RDSPostgresInstanceARN:  rdsPostgres.InstanceARN,
 		rdsPostgresDatabaseName: rdsPostgres.DatabaseName,
+		embedding:               embedding,
 	}
 }
 
 // Create implements RAG.
 func (b *BedrockRAG) Create(ctx context.Context, tableName string) (string, error) {
 	// Get the embedding model configuration
-	modelConfig, exists := config.GetCurrentEmbeddingModelConfig()
+	modelConfig, exists := config.GetEmbeddingModelConfig(b.embedding.Model)
 	if !exists {
-		return "", fmt.Errorf("unsupported embedding model: %s", config.AWSBedrockRAGEmbeddingModel)
+		return "", fmt.Errorf("unsupported embedding model: %s", b.embedding.Model)
 	}
 
 	// Build the vector knowledge base configuration
@@ -60,9 +64,7 @@ func (b *BedrockRAG) Create(ctx context.Context, tableName string) (string, erro
 	}
 
 	// Add embedding configuration if the model supports it
-	if modelConfig.SupportsConfiguration && modelConfig.Configuration != nil {
-		vectorConfig.EmbeddingModelConfiguration = modelConfig.Configuration
-	}
+	vectorConfig.EmbeddingModelConfiguration = modelConfig.ConfigurationFor(b.embedding.Dimensions)
 
 	// Create Bedrock Knowledge Base
 	kbOutput, err := b.kbClient.CreateKnowledgeBase(ctx, &bedrockagent.CreateKnowledgeBaseInput{
@@ -0,0 +1,120 @@
+package rag
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentruntime"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentruntime/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/storage"
+)
+
+// maxSourceBytes bounds the bytes of a source file read to locate the lines of its chunks
+const maxSourceBytes = 4 << 20
+
+// BedrockRetriever implements Retriever with the retrieval of Bedrock knowledge bases. The lines of each chunk are
+// located by reading its file from the S3 data source of the knowledge base.
+type BedrockRetriever struct {
+	client   *bedrockagentruntime.Client
+	s3Client *s3.Client
+}
+
+// NewBedrockRetriever creates a new BedrockRetriever instance.
+func NewBedrockRetriever(awsConfig aws.Config) Retriever {
+	return &BedrockRetriever{
+		client:   bedrockagentruntime.NewFromConfig(awsConfig),
+		s3Client: s3.NewFromConfig(awsConfig),
+	}
+}
+
+// Retrieve implements Retriever.
+func (r *BedrockRetriever) Retrieve(ctx context.Context, knowledgeBaseID, query string, limit int) ([]Chunk, error) {
+	response, err := r.client.Retrieve(ctx, &bedrockagentruntime.RetrieveInput{
+		KnowledgeBaseId: aws.String(knowledgeBaseID),
+		RetrievalQuery:  &types.KnowledgeBaseQuery{Text: aws.String(query)},
+		RetrievalConfiguration: &types.KnowledgeBaseRetrievalConfiguration{
+			VectorSearchConfiguration: &types.KnowledgeBaseVectorSearchConfiguration{
+				NumberOfResults: aws.Int32(int32(limit)),
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve from knowledge base: %w", err)
+	}
+
+	// Several chunks are often cut from the same file, which is read once
+	sources := make(map[string]string)
+	chunks := make([]Chunk, 0, len(response.RetrievalResults))
+	for _, result := range response.RetrievalResults {
+		chunk := Chunk{Score: aws.ToFloat64(result.Score)}
+		if result.Content != nil {
+			chunk.Text = aws.ToString(result.Content.Text)
+		}
+
+		uri := s3URI(result.Location)
+		chunk.Source = sourcePath(uri, knowledgeBaseID)
+		if uri != "" {
+			content, ok := sources[uri]
+			if !ok {
+				content = r.readSource(ctx, uri)
+				sources[uri] = content
+			}
+			chunk.StartLine, chunk.EndLine = LineRange(content, chunk.Text)
+		}
+
+		chunks = append(chunks, chunk)
+	}
+
+	return chunks, nil
+}
+
+// readSource reads the file at an S3 URI, returning nothing when it can't be read, since the lines of its chunks are
+// only reported when they can be located
+func (r *BedrockRetriever) readSource(ctx context.Context, uri string) string {
+	bucket, key, ok := strings.Cut(strings.TrimPrefix(uri, "s3://"), "/")
+	if !ok {
+		return ""
+	}
+
+	object, err := r.s3Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return ""
+	}
+	defer func() {
+		_ = object.Body.Close()
+	}()
+
+	content, err := io.ReadAll(io.LimitReader(object.Body, maxSourceBytes))
+	if err != nil {
+		return ""
+	}
+	return string(content)
+}
+
+// s3URI returns the URI of the S3 object a chunk was cut from, or an empty one for other data sources
+func s3URI(location *types.RetrievalResultLocation) string {
+	if location == nil || location.S3Location == nil {
+		return ""
+	}
+	return aws.ToString(location.S3Location.Uri)
+}
+
+// sourcePath returns the path of the file at an S3 URI relative to the repository uploaded under the prefix of the
+// knowledge base, or the URI itself when it's outside of it
+func sourcePath(uri, knowledgeBaseID string) string {
+	_, key, ok := strings.Cut(strings.TrimPrefix(uri, "s3://"), "/")
+	if !ok {
+		return uri
+	}
+	if path, ok := strings.CutPrefix(key, storage.KnowledgeBasePrefix(knowledgeBaseID)); ok {
+		return path
+	}
+	return uri
+}
@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/pkg/ai/rag (interfaces: Retriever)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	rag "github.com/kazemisoroush/code-refactoring-tool/pkg/ai/rag"
+)
+
+// MockRetriever is a mock of Retriever interface.
+type MockRetriever struct {
+	ctrl     *gomock.Controller
+	recorder *MockRetrieverMockRecorder
+}
+
+// MockRetrieverMockRecorder is the mock recorder for MockRetriever.
+type MockRetrieverMockRecorder struct {
+	mock *MockRetriever
+}
+
+// NewMockRetriever creates a new mock instance.
+func NewMockRetriever(ctrl *gomock.Controller) *MockRetriever {
+	mock := &MockRetriever{ctrl: ctrl}
+	mock.recorder = &MockRetrieverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRetriever) EXPECT() *MockRetrieverMockRecorder {
+	return m.recorder
+}
+
+// Retrieve mocks base method.
+func (m *MockRetriever) Retrieve(arg0 context.Context, arg1, arg2 string, arg3 int) ([]rag.Chunk, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Retrieve", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]rag.Chunk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Retrieve indicates an expected call of Retrieve.
+func (mr *MockRetrieverMockRecorder) Retrieve(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Retrieve", reflect.TypeOf((*MockRetriever)(nil).Retrieve), arg0, arg1, arg2, arg3)
+}
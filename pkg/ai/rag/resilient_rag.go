@@ -0,0 +1,58 @@
+package rag
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/resilience"
+)
+
+// ResilientRAG guards the calls to another RAG with a circuit breaker, retrying the deletions that fail transiently.
+// Creations aren't retried, since a creation whose response was lost may have created the knowledge base.
+type ResilientRAG struct {
+	rag   RAG
+	guard *resilience.Guard
+}
+
+// NewResilientRAG creates a RAG guarding the calls to rag with guard
+func NewResilientRAG(rag RAG, guard *resilience.Guard) RAG {
+	return &ResilientRAG{
+		rag:   rag,
+		guard: guard,
+	}
+}
+
+// Create creates a RAG entry for table
+func (r *ResilientRAG) Create(ctx context.Context, table string) (string, error) {
+	return resilience.CallOnce(ctx, r.guard, func(ctx context.Context) (string, error) {
+		return r.rag.Create(ctx, table)
+	})
+}
+
+// Delete deletes the RAG entry id
+func (r *ResilientRAG) Delete(ctx context.Context, id string) error {
+	return r.guard.Do(ctx, func(ctx context.Context) error {
+		return r.rag.Delete(ctx, id)
+	})
+}
+
+// ResilientRetriever guards the calls to another Retriever with a circuit breaker, retrying the retrievals that fail
+// transiently
+type ResilientRetriever struct {
+	retriever Retriever
+	guard     *resilience.Guard
+}
+
+// NewResilientRetriever creates a Retriever guarding the calls to retriever with guard
+func NewResilientRetriever(retriever Retriever, guard *resilience.Guard) Retriever {
+	return &ResilientRetriever{
+		retriever: retriever,
+		guard:     guard,
+	}
+}
+
+// Retrieve returns up to limit chunks of a knowledge base, most relevant to query first
+func (r *ResilientRetriever) Retrieve(ctx context.Context, knowledgeBaseID, query string, limit int) ([]Chunk, error) {
+	return resilience.Call(ctx, r.guard, func(ctx context.Context) ([]Chunk, error) {
+		return r.retriever.Retrieve(ctx, knowledgeBaseID, query, limit)
+	})
+}
@@ -0,0 +1,40 @@
+package rag
+
+import (
+	"context"
+	"strings"
+)
+
+// Chunk is a piece of a knowledge base's content retrieved for a query
+type Chunk struct {
+	Text      string  // Content of the chunk
+	Source    string  // Path of the file the chunk was cut from, relative to the repository
+	StartLine int     // First line of the chunk in its file, zero when it couldn't be located
+	EndLine   int     // Last line of the chunk in its file, zero when it couldn't be located
+	Score     float64 // Relevance of the chunk to the query, higher being more relevant
+}
+
+// Retriever runs the retrieval step of a knowledge base on its own, returning the chunks an agent would be given as
+// context for a query.
+//
+//go:generate mockgen -destination=./mocks/mock_retriever.go -mock_names=Retriever=MockRetriever -package=mocks . Retriever
+type Retriever interface {
+	// Retrieve returns up to limit chunks of a knowledge base, most relevant to query first.
+	Retrieve(ctx context.Context, knowledgeBaseID, query string, limit int) ([]Chunk, error)
+}
+
+// LineRange locates text within content, returning its first and last lines, or zeros when content doesn't contain
+// it. Chunkers may trim the whitespace around a chunk, so the text is located without it.
+func LineRange(content, text string) (int, int) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return 0, 0
+	}
+	offset := strings.Index(content, text)
+	if offset < 0 {
+		return 0, 0
+	}
+
+	start := strings.Count(content[:offset], "\n") + 1
+	return start, start + strings.Count(text, "\n")
+}
@@ -0,0 +1,38 @@
+package rag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLineRange(t *testing.T) {
+	content := "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"hi\")\n}\n"
+
+	tests := []struct {
+		name      string
+		text      string
+		wantStart int
+		wantEnd   int
+	}{
+		{name: "first line", text: "package main", wantStart: 1, wantEnd: 1},
+		{name: "several lines", text: "func main() {\n\tfmt.Println(\"hi\")\n}", wantStart: 5, wantEnd: 7},
+		{name: "trimmed whitespace", text: "\n\nimport \"fmt\"\n\n", wantStart: 3, wantEnd: 3},
+		{name: "not found", text: "func other() {}"},
+		{name: "empty", text: "  \n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := LineRange(content, tt.text)
+			assert.Equal(t, tt.wantStart, start)
+			assert.Equal(t, tt.wantEnd, end)
+		})
+	}
+}
+
+func TestSourcePath(t *testing.T) {
+	assert.Equal(t, "pkg/api/handler.go", sourcePath("s3://content/knowledge-bases/KB1/pkg/api/handler.go", "KB1"))
+	assert.Equal(t, "s3://content/other/handler.go", sourcePath("s3://content/other/handler.go", "KB1"))
+	assert.Equal(t, "", sourcePath("", "KB1"))
+}
@@ -0,0 +1,156 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagent"
+	bedrocktypes "github.com/aws/aws-sdk-go-v2/service/bedrockagent/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+const (
+	// DataSourceName is the name of the data source used for the code refactoring tool.
+	DataSourceName = "code-refactoring-tool-data-source"
+
+	// DataSourceDescription is the description of the data source used for the code refactoring tool.
+	DataSourceDescription = "Data source for the code refactoring tool knowledge base. This data source is used to store the codebase and other relevant files for the RAG pipeline."
+)
+
+// BedrockDataSource implements DataSource with Bedrock knowledge base data sources reading the content uploaded to an
+// S3 bucket by an S3DataStore.
+type BedrockDataSource struct {
+	s3Client   *s3.Client
+	bucketName string
+	client     *bedrockagent.Client
+}
+
+// NewBedrockDataSource creates a new BedrockDataSource of the bucket with the provided name.
+func NewBedrockDataSource(awsConfig aws.Config, bucketName string) DataSource {
+	return &BedrockDataSource{
+		s3Client:   s3.NewFromConfig(awsConfig),
+		bucketName: bucketName,
+		client:     bedrockagent.NewFromConfig(awsConfig),
+	}
+}
+
+// Create checks if the S3 bucket exists and is accessible, and creates the data source of the knowledge base on the
+// content uploaded under its prefix.
+func (s BedrockDataSource) Create(ctx context.Context, ragID string) (string, error) {
+	// S3 does not require explicit creation of a bucket, but we can check if it exists.
+	_, err := s.s3Client.HeadBucket(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(s.bucketName),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to access bucket %s: %w", s.bucketName, err)
+	}
+
+	bucketARN := fmt.Sprintf("arn:aws:s3:::%s", s.bucketName)
+
+	// Create bedrock data store
+	response, err := s.client.CreateDataSource(ctx, &bedrockagent.CreateDataSourceInput{
+		Name:               aws.String(DataSourceName),
+		KnowledgeBaseId:    aws.String(ragID),
+		DataDeletionPolicy: bedrocktypes.DataDeletionPolicyDelete,
+		DataSourceConfiguration: &bedrocktypes.DataSourceConfiguration{
+			Type: bedrocktypes.DataSourceTypeS3,
+			S3Configuration: &bedrocktypes.S3DataSourceConfiguration{
+				BucketArn:         aws.String(bucketARN),
+				InclusionPrefixes: []string{KnowledgeBasePrefix(ragID)},
+			},
+		},
+		VectorIngestionConfiguration: &bedrocktypes.VectorIngestionConfiguration{
+			// TODO: Fix chunking here...
+			ChunkingConfiguration: &bedrocktypes.ChunkingConfiguration{
+				ChunkingStrategy: bedrocktypes.ChunkingStrategyHierarchical,
+				HierarchicalChunkingConfiguration: &bedrocktypes.HierarchicalChunkingConfiguration{
+					LevelConfigurations: []bedrocktypes.HierarchicalChunkingLevelConfiguration{
+						{
+							MaxTokens: aws.Int32(1000), // TODO: Use a more suitable value
+						},
+						{
+							MaxTokens: aws.Int32(500), // TODO: Use a more suitable value
+						},
+					},
+					OverlapTokens: aws.Int32(50), // TODO: Use a more suitable value
+				},
+			},
+
+			// // TODO: Do we need this?
+			// ContextEnrichmentConfiguration: &bedrocktypes.ContextEnrichmentConfiguration{
+			// 	Type: bedrocktypes.ContextEnrichmentTypeBedrockFoundationModel,
+			// 	BedrockFoundationModelConfiguration: &bedrocktypes.BedrockFoundationModelContextEnrichmentConfiguration{
+			// 		EnrichmentStrategyConfiguration: &bedrocktypes.EnrichmentStrategyConfiguration{
+			// 			Method: bedrocktypes.EnrichmentStrategyMethodChunkEntityExtraction,
+			// 		},
+			// 		ModelArn: aws.String(
+			// 			fmt.Sprintf(
+			// 				"arn:aws:bedrock:%s::foundation-model/%s",
+			// 				config.AWSRegion,
+			// 				config.AWSBedrockDataStoreEnrichmentModelARN,
+			// 			),
+			// 		),
+			// 	},
+			// },
+
+			// // TODO: This might be needed for code parsing
+			// {"time":"2025-07-20T08:23:18.444387949Z","level":"ERROR","msg":"workflow failed","error":"failed to build RAG pipeline: failed to create data source: failed to create data source: operation error Bedrock Agent: CreateDataSource, https response error StatusCode: 400, RequestID: 31f19628-fc06-4766-956c-4967e7fda73a, ValidationException: A custom transformation configuration cannot have the same s3 bucket for intermediate storage as the data source."}
+			// CustomTransformationConfiguration: &bedrocktypes.CustomTransformationConfiguration{
+			// 	IntermediateStorage: &bedrocktypes.IntermediateStorage{
+			// 		S3Location: &bedrocktypes.S3Location{
+			// 			Uri: aws.String(fmt.Sprintf("s3://%s/%s", s.bucketName, KnowledgeBasePrefix(ragID))),
+			// 		},
+			// 	},
+			// 	Transformations: []bedrocktypes.Transformation{
+			// 		{
+			// 			StepToApply: bedrocktypes.StepTypePostChunking,
+			// 			TransformationFunction: &bedrocktypes.TransformationFunction{
+			// 				TransformationLambdaConfiguration: &bedrocktypes.TransformationLambdaConfiguration{
+			// 					LambdaArn: aws.String("arn:aws:lambda:us-east-1:123456789012:function:MyTransformationFunction"), // TODO: Use a more suitable Lambda function
+			// 				},
+			// 			},
+			// 		},
+			// 	},
+			// },
+
+			// // TODO: Do we need this?
+			// ParsingConfiguration: &bedrocktypes.ParsingConfiguration{
+			// 	ParsingStrategy: bedrocktypes.ParsingStrategyBedrockFoundationModel,
+			// 	BedrockFoundationModelConfiguration: &bedrocktypes.BedrockFoundationModelConfiguration{
+			// 		ModelArn: aws.String(
+			// 			fmt.Sprintf(
+			// 				"arn:aws:bedrock:%s::foundation-model/%s",
+			// 				config.AWSRegion,
+			// 				config.AWSBedrockDataStoreParsingModelARN,
+			// 			),
+			// 		),
+			// 		// Code base could have images, so we use multimodal parsing
+			// 		ParsingModality: bedrocktypes.ParsingModalityMultimodal,
+			// 		ParsingPrompt: &bedrocktypes.ParsingPrompt{
+			// 			ParsingPromptText: aws.String("Extract code and comments from the provided files."), // TODO: Use a more suitable prompt
+			// 		},
+			// 	},
+			// },
+		},
+		Description: aws.String(DataSourceDescription),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create data source: %w", err)
+	}
+
+	return *response.DataSource.DataSourceId, nil
+}
+
+// Delete deletes the data source from the knowledge base.
+func (s BedrockDataSource) Delete(ctx context.Context, dataSourceID string, ragID string) error {
+	_, err := s.client.DeleteDataSource(ctx, &bedrockagent.DeleteDataSourceInput{
+		DataSourceId:    aws.String(dataSourceID),
+		KnowledgeBaseId: aws.String(ragID),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete data source: %w", err)
+	}
+
+	return nil
+}
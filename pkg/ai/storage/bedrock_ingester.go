@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagent"
+	bedrocktypes "github.com/aws/aws-sdk-go-v2/service/bedrockagent/types"
+)
+
+// BedrockIngester implements the Ingester interface with Bedrock knowledge base ingestion jobs.
+type BedrockIngester struct {
+	client *bedrockagent.Client
+}
+
+// NewBedrockIngester creates a new BedrockIngester instance.
+func NewBedrockIngester(awsConfig aws.Config) Ingester {
+	return &BedrockIngester{
+		client: bedrockagent.NewFromConfig(awsConfig),
+	}
+}
+
+// StartIngestion implements Ingester.
+func (i *BedrockIngester) StartIngestion(ctx context.Context, knowledgeBaseID string) (*IngestionJob, error) {
+	dataSourceID, err := i.dataSourceID(ctx, knowledgeBaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := i.client.StartIngestionJob(ctx, &bedrockagent.StartIngestionJobInput{
+		KnowledgeBaseId: aws.String(knowledgeBaseID),
+		DataSourceId:    aws.String(dataSourceID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start ingestion job: %w", err)
+	}
+
+	job := toIngestionJob(response.IngestionJob.IngestionJobId, response.IngestionJob.Status,
+		response.IngestionJob.Statistics, response.IngestionJob.StartedAt, response.IngestionJob.UpdatedAt)
+	job.FailureReasons = response.IngestionJob.FailureReasons
+	return &job, nil
+}
+
+// ListIngestions implements Ingester.
+func (i *BedrockIngester) ListIngestions(ctx context.Context, knowledgeBaseID string, limit int) ([]IngestionJob, error) {
+	dataSourceID, err := i.dataSourceID(ctx, knowledgeBaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := i.client.ListIngestionJobs(ctx, &bedrockagent.ListIngestionJobsInput{
+		KnowledgeBaseId: aws.String(knowledgeBaseID),
+		DataSourceId:    aws.String(dataSourceID),
+		MaxResults:      aws.Int32(int32(limit)),
+		SortBy: &bedrocktypes.IngestionJobSortBy{
+			Attribute: bedrocktypes.IngestionJobSortByAttributeStartedAt,
+			Order:     bedrocktypes.SortOrderDescending,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ingestion jobs: %w", err)
+	}
+
+	jobs := make([]IngestionJob, 0, len(response.IngestionJobSummaries))
+	for _, summary := range response.IngestionJobSummaries {
+		job := toIngestionJob(summary.IngestionJobId, summary.Status, summary.Statistics, summary.StartedAt, summary.UpdatedAt)
+
+		// Summaries leave out failure reasons, so they are fetched for the jobs that have any
+		if job.Status == IngestionStatusFailed || job.DocumentsFailed > 0 {
+			detail, err := i.client.GetIngestionJob(ctx, &bedrockagent.GetIngestionJobInput{
+				KnowledgeBaseId: aws.String(knowledgeBaseID),
+				DataSourceId:    aws.String(dataSourceID),
+				IngestionJobId:  summary.IngestionJobId,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to get ingestion job %s: %w", job.JobID, err)
+			}
+			job.FailureReasons = detail.IngestionJob.FailureReasons
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
+
+// dataSourceID returns the data source the knowledge base ingests. The RAG builder creates exactly one.
+func (i *BedrockIngester) dataSourceID(ctx context.Context, knowledgeBaseID string) (string, error) {
+	response, err := i.client.ListDataSources(ctx, &bedrockagent.ListDataSourcesInput{
+		KnowledgeBaseId: aws.String(knowledgeBaseID),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to list data sources: %w", err)
+	}
+	if len(response.DataSourceSummaries) == 0 {
+		return "", fmt.Errorf("knowledge base %s has no data source", knowledgeBaseID)
+	}
+
+	return aws.ToString(response.DataSourceSummaries[0].DataSourceId), nil
+}
+
+// toIngestionJob converts the fields shared by Bedrock ingestion jobs and their summaries.
+func toIngestionJob(
+	jobID *string,
+	status bedrocktypes.IngestionJobStatus,
+	statistics *bedrocktypes.IngestionJobStatistics,
+	startedAt, updatedAt *time.Time,
+) IngestionJob {
+	job := IngestionJob{
+		JobID:     aws.ToString(jobID),
+		Status:    IngestionStatus(strings.ToLower(string(status))),
+		StartedAt: aws.ToTime(startedAt),
+		UpdatedAt: aws.ToTime(updatedAt),
+	}
+	if statistics != nil {
+		job.DocumentsScanned = int(statistics.NumberOfDocumentsScanned)
+		job.DocumentsIndexed = int(statistics.NumberOfNewDocumentsIndexed + statistics.NumberOfModifiedDocumentsIndexed)
+		job.DocumentsDeleted = int(statistics.NumberOfDocumentsDeleted)
+		job.DocumentsFailed = int(statistics.NumberOfDocumentsFailed)
+	}
+	return job
+}
@@ -0,0 +1,15 @@
+package storage
+
+import "context"
+
+// DataSource interface defines methods for connecting a knowledge base to the content uploaded for it to a DataStore.
+//
+//go:generate mockgen -destination=./mocks/mock_data_source.go -mock_names=DataSource=MockDataSource -package=mocks . DataSource
+type DataSource interface {
+	// Create creates the data source of a knowledge base, reading the content uploaded under its prefix, and returns
+	// its ID.
+	Create(ctx context.Context, ragID string) (string, error)
+
+	// Delete removes the data source from its knowledge base.
+	Delete(ctx context.Context, dataSourceID string, ragID string) error
+}
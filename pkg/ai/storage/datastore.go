@@ -1,21 +1,58 @@
 // Package storage provides an interface for uploading and deleting directories in a storage system.
 package storage
 
-import "context"
+import (
+	"context"
+	"fmt"
 
-// DataStore interface defines methods for uploading and deleting directories in a storage system.
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// KnowledgeBasesPrefix is the key prefix the content of every knowledge base is uploaded under
+const KnowledgeBasesPrefix = "knowledge-bases/"
+
+// KnowledgeBasePrefix returns the key prefix the content of a knowledge base is uploaded under, so it can be purged
+// and told apart from the content of other knowledge bases
+func KnowledgeBasePrefix(knowledgeBaseID string) string {
+	return KnowledgeBasesPrefix + knowledgeBaseID + "/"
+}
+
+// DataStore interface defines methods for uploading and deleting directories in a storage system. Remote paths are
+// slash separated keys, and directories are the prefixes of the keys ending with a slash.
 //
 //go:generate mockgen -destination=./mocks/mock_datastore.go -mock_names=DataStore=MockDataStore -package=mocks . DataStore
 type DataStore interface {
-	// Create initializes the data store, preparing it for use.
-	Create(ctx context.Context, ragID string) (string, error)
+	// UploadDirectory uploads a local directory to a remote path in the storage system, several files at a time. A
+	// non-nil progress is called after each file is uploaded.
+	UploadDirectory(ctx context.Context, localPath, remotePath string, progress func(UploadProgress)) error
 
-	// Delete removes the data store, cleaning up any resources it holds.
-	Delete(ctx context.Context, dataSourceID string, ragID string) error
+	// DeleteDirectory deletes a remote directory in the storage system. A non-nil progress is called with the number
+	// of objects deleted after each batch.
+	DeleteDirectory(ctx context.Context, remotePath string, progress func(deleted int)) error
 
-	// UploadDirectory uploads a local directory to a remote path in the storage system.
-	UploadDirectory(ctx context.Context, localPath, remotePath string) error
+	// ListDirectories lists the directories directly under a remote path in the storage system, each ending with a
+	// slash. Directories without any object aren't listed.
+	ListDirectories(ctx context.Context, remotePath string) ([]string, error)
+}
 
-	// DeleteDirectory deletes a remote directory in the storage system.
-	DeleteDirectory(ctx context.Context, remotePath string) error
+// NewDataStore creates the data store of the configured type, uploading directories with uploads. S3 buckets are
+// accessed with awsConfig.
+func NewDataStore(cfg config.DataStoreConfig, awsConfig aws.Config, uploads *UploadPipeline) (DataStore, error) {
+	switch cfg.Type {
+	case config.DataStoreFilesystem:
+		return NewFilesystemDataStore(cfg.Root, uploads), nil
+	case config.DataStoreS3:
+		if cfg.Bucket == "" {
+			return nil, fmt.Errorf("data store %s requires a bucket", cfg.Type)
+		}
+		return NewS3DataStore(awsConfig, cfg.Bucket, uploads), nil
+	case config.DataStoreGCS:
+		if cfg.Bucket == "" {
+			return nil, fmt.Errorf("data store %s requires a bucket", cfg.Type)
+		}
+		return NewGCSDataStore(cfg.GCSEndpoint, cfg.Bucket, cfg.GCSCredentialsFile, uploads)
+	default:
+		return nil, fmt.Errorf("unknown data store type %q", cfg.Type)
+	}
 }
@@ -0,0 +1,353 @@
+package storage_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"encoding/xml"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/storage"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// fakePageSize is how many objects and prefixes the fake buckets list at a time, so listings take several pages
+const fakePageSize = 2
+
+// fakeBucket holds the objects of a fake S3 or GCS bucket
+type fakeBucket struct {
+	mu      sync.Mutex
+	objects map[string]string
+}
+
+func newFakeBucket() *fakeBucket {
+	return &fakeBucket{objects: make(map[string]string)}
+}
+
+func (b *fakeBucket) put(key, content string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.objects[key] = content
+}
+
+func (b *fakeBucket) get(key string) (string, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	content, ok := b.objects[key]
+	return content, ok
+}
+
+func (b *fakeBucket) delete(key string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.objects, key)
+}
+
+// list returns a page of the keys under prefix and, with a delimiter, the prefixes grouping the keys past it, and the
+// token of the next page. Like the tokens of S3 and GCS, tokens resume the listing after the last entry listed, so
+// deleting the objects listed doesn't skip any.
+func (b *fakeBucket) list(prefix, delimiter, token string) ([]string, []string, string) {
+	b.mu.Lock()
+	var entries []string
+	seen := map[string]bool{}
+	for key := range b.objects {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if i := strings.Index(key[len(prefix):], delimiter); delimiter != "" && i >= 0 {
+			key = key[:len(prefix)+i+len(delimiter)]
+		}
+		if !seen[key] {
+			seen[key] = true
+			entries = append(entries, key)
+		}
+	}
+	b.mu.Unlock()
+	sort.Strings(entries)
+
+	start := 0
+	if token != "" {
+		start = sort.SearchStrings(entries, token)
+		if start < len(entries) && entries[start] == token {
+			start++
+		}
+	}
+	end := min(start+fakePageSize, len(entries))
+	next := ""
+	if end < len(entries) {
+		next = entries[end-1]
+	}
+
+	var keys, prefixes []string
+	for _, entry := range entries[start:end] {
+		if delimiter != "" && strings.HasSuffix(entry, delimiter) {
+			prefixes = append(prefixes, entry)
+		} else {
+			keys = append(keys, entry)
+		}
+	}
+	return keys, prefixes, next
+}
+
+// newGCSDataStore returns a GCS data store of a fake JSON API, authorized with a service account key
+func newGCSDataStore(t *testing.T) (storage.DataStore, func(key string) (string, bool)) {
+	bucket := newFakeBucket()
+	tokenRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			tokenRequests++
+			assert.Equal(t, "urn:ietf:params:oauth:grant-type:jwt-bearer", r.FormValue("grant_type"))
+			assert.Len(t, strings.Split(r.FormValue("assertion"), "."), 3)
+			_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "gcs-token", "expires_in": 3600})
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer gcs-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/upload/storage/v1/b/content/o":
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			bucket.put(r.URL.Query().Get("name"), string(body))
+			_ = json.NewEncoder(w).Encode(map[string]string{"name": r.URL.Query().Get("name")})
+		case r.Method == http.MethodGet && r.URL.Path == "/storage/v1/b/content/o":
+			query := r.URL.Query()
+			keys, prefixes, next := bucket.list(query.Get("prefix"), query.Get("delimiter"), query.Get("pageToken"))
+			items := []map[string]string{}
+			for _, key := range keys {
+				items = append(items, map[string]string{"name": key})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"items": items, "prefixes": prefixes, "nextPageToken": next})
+		case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/storage/v1/b/content/o/"):
+			key := strings.TrimPrefix(r.URL.Path, "/storage/v1/b/content/o/")
+			if _, ok := bucket.get(key); !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			bucket.delete(key)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { assert.Equal(t, 1, tokenRequests, "access token should be cached") })
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	require.NoError(t, err)
+	key, err := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"client_email": "ingestion@acme.iam.gserviceaccount.com",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"token_uri":    server.URL + "/token",
+	})
+	require.NoError(t, err)
+	credentialsFile := filepath.Join(t.TempDir(), "key.json")
+	require.NoError(t, os.WriteFile(credentialsFile, key, 0o600))
+
+	dataStore, err := storage.NewGCSDataStore(server.URL, "content", credentialsFile, nil)
+	require.NoError(t, err)
+	return dataStore, bucket.get
+}
+
+// s3ListResult is the response of a ListObjectsV2 call
+type s3ListResult struct {
+	XMLName               xml.Name `xml:"ListBucketResult"`
+	IsTruncated           bool     `xml:"IsTruncated"`
+	NextContinuationToken string   `xml:"NextContinuationToken,omitempty"`
+	Contents              []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	CommonPrefixes []struct {
+		Prefix string `xml:"Prefix"`
+	} `xml:"CommonPrefixes"`
+}
+
+// newS3DataStore returns an S3 data store of a fake S3 endpoint, which every request is sent to
+func newS3DataStore(t *testing.T) (storage.DataStore, func(key string) (string, bool)) {
+	bucket := newFakeBucket()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Buckets are addressed by host or by the first segment of the path
+		key := strings.TrimPrefix(r.URL.Path, "/")
+		if !strings.HasPrefix(r.Host, "content.") {
+			key = strings.TrimPrefix(key, "content")
+			key = strings.TrimPrefix(key, "/")
+		}
+
+		query := r.URL.Query()
+		switch {
+		case r.Method == http.MethodPut:
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			bucket.put(key, string(body))
+		case r.Method == http.MethodGet && query.Get("list-type") == "2":
+			keys, prefixes, next := bucket.list(query.Get("prefix"), query.Get("delimiter"), query.Get("continuation-token"))
+			result := s3ListResult{IsTruncated: next != "", NextContinuationToken: next}
+			for _, key := range keys {
+				result.Contents = append(result.Contents, struct {
+					Key string `xml:"Key"`
+				}{key})
+			}
+			for _, prefix := range prefixes {
+				result.CommonPrefixes = append(result.CommonPrefixes, struct {
+					Prefix string `xml:"Prefix"`
+				}{prefix})
+			}
+			_ = xml.NewEncoder(w).Encode(result)
+		case r.Method == http.MethodPost && query.Has("delete"):
+			var request struct {
+				Objects []struct {
+					Key string `xml:"Key"`
+				} `xml:"Object"`
+			}
+			require.NoError(t, xml.NewDecoder(r.Body).Decode(&request))
+			for _, object := range request.Objects {
+				bucket.delete(object.Key)
+			}
+			_, _ = io.WriteString(w, "<DeleteResult></DeleteResult>")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	dialer := &net.Dialer{}
+	awsConfig := aws.Config{
+		Region:                     "us-east-1",
+		Credentials:                aws.AnonymousCredentials{},
+		BaseEndpoint:               aws.String("http://s3.test"),
+		RequestChecksumCalculation: aws.RequestChecksumCalculationWhenRequired,
+		HTTPClient: &http.Client{Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				return dialer.DialContext(ctx, network, server.Listener.Addr().String())
+			},
+		}},
+	}
+	return storage.NewS3DataStore(awsConfig, "content", nil), bucket.get
+}
+
+// newFilesystemDataStore returns a filesystem data store of a temporary directory
+func newFilesystemDataStore(t *testing.T) (storage.DataStore, func(key string) (string, bool)) {
+	root := t.TempDir()
+	return storage.NewFilesystemDataStore(root, nil), func(key string) (string, bool) {
+		content, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(key)))
+		return string(content), err == nil
+	}
+}
+
+// writeRepository writes the files, keyed by their slash separated path, to a temporary directory and returns it
+func writeRepository(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+	return dir
+}
+
+// TestDataStore_Parity runs the same scenarios against every DataStore implementation
+func TestDataStore_Parity(t *testing.T) {
+	implementations := map[string]func(t *testing.T) (storage.DataStore, func(key string) (string, bool)){
+		"filesystem": newFilesystemDataStore,
+		"gcs":        newGCSDataStore,
+		"s3":         newS3DataStore,
+	}
+
+	for name, newDataStore := range implementations {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			dataStore, read := newDataStore(t)
+
+			payments := writeRepository(t, map[string]string{
+				"go.mod":               "module payments",
+				"main.go":              "package main",
+				"pkg/charge.go":        "package pkg",
+				"pkg/refund/refund.go": "package refund",
+			})
+			billing := writeRepository(t, map[string]string{"README.md": "# billing"})
+			var progress []storage.UploadProgress
+			require.NoError(t, dataStore.UploadDirectory(ctx, payments, storage.KnowledgeBasePrefix("kb-1"), func(p storage.UploadProgress) {
+				progress = append(progress, p)
+			}))
+			require.Len(t, progress, 4)
+			assert.Equal(t, 4, progress[3].Files)
+			assert.Equal(t, progress[3].TotalBytes, progress[3].Bytes)
+			require.NoError(t, dataStore.UploadDirectory(ctx, billing, storage.KnowledgeBasePrefix("kb-2"), nil))
+			require.NoError(t, dataStore.UploadDirectory(ctx, billing, storage.KnowledgeBasePrefix("kb-3"), nil))
+
+			content, ok := read("knowledge-bases/kb-1/pkg/refund/refund.go")
+			assert.True(t, ok)
+			assert.Equal(t, "package refund", content)
+
+			directories, err := dataStore.ListDirectories(ctx, storage.KnowledgeBasesPrefix)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []string{"knowledge-bases/kb-1/", "knowledge-bases/kb-2/", "knowledge-bases/kb-3/"}, directories)
+
+			directories, err = dataStore.ListDirectories(ctx, storage.KnowledgeBasePrefix("kb-1"))
+			require.NoError(t, err)
+			assert.Equal(t, []string{"knowledge-bases/kb-1/pkg/"}, directories)
+
+			deleted := 0
+			require.NoError(t, dataStore.DeleteDirectory(ctx, storage.KnowledgeBasePrefix("kb-1"), func(n int) { deleted += n }))
+			assert.Equal(t, 4, deleted)
+			_, ok = read("knowledge-bases/kb-1/main.go")
+			assert.False(t, ok)
+
+			directories, err = dataStore.ListDirectories(ctx, storage.KnowledgeBasesPrefix)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []string{"knowledge-bases/kb-2/", "knowledge-bases/kb-3/"}, directories)
+
+			// Deleting and listing what doesn't exist does nothing
+			require.NoError(t, dataStore.DeleteDirectory(ctx, storage.KnowledgeBasePrefix("kb-1"), func(int) { t.Error("nothing should be deleted") }))
+			directories, err = dataStore.ListDirectories(ctx, storage.KnowledgeBasePrefix("kb-9"))
+			require.NoError(t, err)
+			assert.Empty(t, directories)
+		})
+	}
+}
+
+func TestFilesystemDataStore_RejectsKeysOutsideRoot(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "store")
+	dataStore := storage.NewFilesystemDataStore(root, nil)
+
+	err := dataStore.UploadDirectory(context.Background(), writeRepository(t, map[string]string{"a.go": "package a"}), "../escape/", nil)
+
+	assert.Error(t, err)
+	assert.NoFileExists(t, filepath.Join(filepath.Dir(root), "escape", "a.go"))
+}
+
+func TestNewDataStore(t *testing.T) {
+	dataStore, err := storage.NewDataStore(config.DataStoreConfig{Type: config.DataStoreFilesystem, Root: t.TempDir()}, aws.Config{}, nil)
+	require.NoError(t, err)
+	assert.IsType(t, &storage.FilesystemDataStore{}, dataStore)
+
+	_, err = storage.NewDataStore(config.DataStoreConfig{Type: config.DataStoreGCS}, aws.Config{}, nil)
+	assert.ErrorContains(t, err, "requires a bucket")
+
+	_, err = storage.NewDataStore(config.DataStoreConfig{Type: config.DataStoreGCS, Bucket: "content", GCSCredentialsFile: filepath.Join(t.TempDir(), "missing.json")}, aws.Config{}, nil)
+	assert.ErrorContains(t, err, "failed to read GCS credentials")
+
+	_, err = storage.NewDataStore(config.DataStoreConfig{Type: "azure"}, aws.Config{}, nil)
+	assert.Error(t, err)
+}
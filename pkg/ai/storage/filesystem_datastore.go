@@ -0,0 +1,156 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FilesystemDataStore implements DataStore with a local directory, for deployments without object storage such as
+// air-gapped ones. Keys are paths below the root directory.
+type FilesystemDataStore struct {
+	root    string
+	uploads *UploadPipeline
+}
+
+// NewFilesystemDataStore creates a new FilesystemDataStore storing its content below root, which is created on the
+// first upload. Directories are copied with uploads, or with the default pipeline when it's nil.
+func NewFilesystemDataStore(root string, uploads *UploadPipeline) DataStore {
+	if uploads == nil {
+		uploads = DefaultUploadPipeline()
+	}
+	return &FilesystemDataStore{root: root, uploads: uploads}
+}
+
+// UploadDirectory copies all files in a directory below the root under the given prefix.
+func (s FilesystemDataStore) UploadDirectory(ctx context.Context, localPath, remotePath string, progress func(UploadProgress)) error {
+	return s.uploads.upload(ctx, localPath, remotePath, s.put, progress)
+}
+
+// put copies a file below the root
+func (s FilesystemDataStore) put(_ context.Context, file uploadFile) error {
+	target, err := s.path(file.key)
+	if err != nil {
+		return permanent(err)
+	}
+	if err := copyFile(file.path, target); err != nil {
+		return fmt.Errorf("failed to store %s: %w", file.key, err)
+	}
+	return nil
+}
+
+// DeleteDirectory deletes the directory of a prefix below the root with all the files in it, reporting them as a
+// single batch.
+func (s FilesystemDataStore) DeleteDirectory(_ context.Context, prefix string, progress func(deleted int)) error {
+	dir, err := s.path(prefix)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := countFiles(dir)
+	if err != nil {
+		return fmt.Errorf("failed to list files under %s: %w", prefix, err)
+	}
+	if deleted == 0 {
+		return nil
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to delete files under %s: %w", prefix, err)
+	}
+	if progress != nil {
+		progress(deleted)
+	}
+
+	return nil
+}
+
+// ListDirectories lists the directories holding files directly under the directory of a prefix below the root, each
+// ending with a slash.
+func (s FilesystemDataStore) ListDirectories(_ context.Context, prefix string) ([]string, error) {
+	dir, err := s.path(prefix)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list directories under %s: %w", prefix, err)
+	}
+
+	var directories []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		// Object stores have no empty directories, so neither does this one
+		files, err := countFiles(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list directories under %s: %w", prefix, err)
+		}
+		if files > 0 {
+			directories = append(directories, prefix+entry.Name()+"/")
+		}
+	}
+
+	return directories, nil
+}
+
+// path returns the path below the root of a key, rejecting keys outside of it
+func (s FilesystemDataStore) path(key string) (string, error) {
+	key = strings.TrimSuffix(key, "/")
+	if key == "" {
+		return s.root, nil
+	}
+	if !filepath.IsLocal(filepath.FromSlash(key)) {
+		return "", fmt.Errorf("key %q is outside of the data store", key)
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
+
+// copyFile copies the file at source to target, creating the directories of target
+func copyFile(source, target string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+		return err
+	}
+
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o640)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// countFiles counts the files below dir, none when it doesn't exist
+func countFiles(dir string) (int, error) {
+	count := 0
+	err := filepath.WalkDir(dir, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.Type().IsRegular() {
+			count++
+		}
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	return count, err
+}
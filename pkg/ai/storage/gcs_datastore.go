@@ -0,0 +1,345 @@
+package storage
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// gcsScope is the OAuth scope of the access tokens GCSDataStore calls the JSON API with
+	gcsScope = "https://www.googleapis.com/auth/devstorage.read_write"
+
+	// gcsMetadataTokenURL hands out the access tokens of the service account a workload runs as on Google Cloud
+	gcsMetadataTokenURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+
+	// gcsDefaultTokenURL exchanges the assertions of service account keys not naming a token URI
+	gcsDefaultTokenURL = "https://oauth2.googleapis.com/token"
+
+	// gcsTokenRefreshMargin is how long before it expires a cached access token is replaced
+	gcsTokenRefreshMargin = 5 * time.Minute
+)
+
+// GCSDataStore implements DataStore with a Google Cloud Storage bucket, calling its JSON API. Requests are authorized
+// as the service account of a key file, or as the one the workload runs as on Google Cloud.
+type GCSDataStore struct {
+	httpClient *http.Client
+	endpoint   string
+	bucketName string
+	tokens     *gcsTokens
+	uploads    *UploadPipeline
+}
+
+// gcsServiceAccountKey is the part of a service account key file used to sign token requests
+type gcsServiceAccountKey struct {
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// gcsTokens hands out access tokens, cached until shortly before they expire
+type gcsTokens struct {
+	httpClient *http.Client
+	key        *gcsServiceAccountKey // Nil to get tokens from the metadata server
+	signer     *rsa.PrivateKey
+	now        func() time.Time
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// gcsObjects is a page of objects listed by the JSON API
+type gcsObjects struct {
+	Items []struct {
+		Name string `json:"name"`
+	} `json:"items"`
+	Prefixes      []string `json:"prefixes"`
+	NextPageToken string   `json:"nextPageToken"`
+}
+
+// NewGCSDataStore creates a new GCSDataStore of the bucket with the provided name, calling the JSON API at endpoint.
+// Requests are authorized with the service account key at credentialsFile, or with the metadata server when it's
+// empty. Directories are uploaded with uploads, or with the default pipeline when it's nil. It returns an error when
+// the key file can't be read.
+func NewGCSDataStore(endpoint, bucketName, credentialsFile string, uploads *UploadPipeline) (DataStore, error) {
+	if uploads == nil {
+		uploads = DefaultUploadPipeline()
+	}
+	// Uploads of large files take a while, so requests are only bounded by their context
+	httpClient := &http.Client{}
+	tokens := &gcsTokens{httpClient: httpClient, now: time.Now}
+	if credentialsFile != "" {
+		content, err := os.ReadFile(credentialsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read GCS credentials: %w", err)
+		}
+		key := &gcsServiceAccountKey{}
+		if err := json.Unmarshal(content, key); err != nil {
+			return nil, fmt.Errorf("failed to parse GCS credentials: %w", err)
+		}
+		signer, err := parseServiceAccountKey(key.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse GCS credentials: %w", err)
+		}
+		if key.TokenURI == "" {
+			key.TokenURI = gcsDefaultTokenURL
+		}
+		tokens.key = key
+		tokens.signer = signer
+	}
+
+	return &GCSDataStore{
+		httpClient: httpClient,
+		endpoint:   strings.TrimSuffix(endpoint, "/"),
+		bucketName: bucketName,
+		tokens:     tokens,
+		uploads:    uploads,
+	}, nil
+}
+
+// UploadDirectory uploads all files in a directory to the bucket under the given prefix.
+func (s GCSDataStore) UploadDirectory(ctx context.Context, localPath, remotePath string, progress func(UploadProgress)) error {
+	return s.uploads.upload(ctx, localPath, remotePath, s.put, progress)
+}
+
+// put uploads a file to the bucket in a single request
+func (s GCSDataStore) put(ctx context.Context, file uploadFile) error {
+	f, err := os.Open(file.path)
+	if err != nil {
+		return permanent(fmt.Errorf("failed to open file %s: %w", file.path, err))
+	}
+	defer func() { _ = f.Close() }()
+
+	query := url.Values{"uploadType": {"media"}, "name": {file.key}}
+	requestURL := fmt.Sprintf("%s/upload/storage/v1/b/%s/o?%s", s.endpoint, url.PathEscape(s.bucketName), query.Encode())
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, requestURL, f)
+	if err != nil {
+		return permanent(fmt.Errorf("failed to upload %s to GCS: %w", file.key, err))
+	}
+	request.ContentLength = file.size
+	request.Header.Set("Content-Type", "application/octet-stream")
+	if err := s.send(request, nil); err != nil {
+		return fmt.Errorf("failed to upload %s to GCS: %w", file.key, err)
+	}
+	return nil
+}
+
+// DeleteDirectory deletes all objects under a given prefix in the bucket, a page of listed objects at a time. The
+// JSON API deletes objects one by one, and objects already deleted are skipped.
+func (s GCSDataStore) DeleteDirectory(ctx context.Context, prefix string, progress func(deleted int)) error {
+	pageToken := ""
+	for {
+		page, err := s.list(ctx, prefix, "", pageToken)
+		if err != nil {
+			return fmt.Errorf("failed to list objects in bucket %s with prefix %s: %w", s.bucketName, prefix, err)
+		}
+
+		for _, object := range page.Items {
+			requestURL := fmt.Sprintf("%s/storage/v1/b/%s/o/%s", s.endpoint, url.PathEscape(s.bucketName), url.PathEscape(object.Name))
+			request, err := http.NewRequestWithContext(ctx, http.MethodDelete, requestURL, nil)
+			if err != nil {
+				return fmt.Errorf("failed to delete %s from bucket %s: %w", object.Name, s.bucketName, err)
+			}
+			err = s.send(request, nil)
+			var statusErr *gcsStatusError
+			if errors.As(err, &statusErr) && statusErr.status == http.StatusNotFound {
+				err = nil
+			}
+			if err != nil {
+				return fmt.Errorf("failed to delete %s from bucket %s: %w", object.Name, s.bucketName, err)
+			}
+		}
+		if progress != nil && len(page.Items) > 0 {
+			progress(len(page.Items))
+		}
+
+		if page.NextPageToken == "" {
+			return nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+// ListDirectories lists the prefixes directly under a given prefix in the bucket, each ending with a slash.
+func (s GCSDataStore) ListDirectories(ctx context.Context, prefix string) ([]string, error) {
+	var directories []string
+	pageToken := ""
+	for {
+		page, err := s.list(ctx, prefix, "/", pageToken)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list prefixes in bucket %s under %s: %w", s.bucketName, prefix, err)
+		}
+		directories = append(directories, page.Prefixes...)
+
+		if page.NextPageToken == "" {
+			return directories, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+// list lists a page of the objects under prefix, grouping the ones past delimiter into prefixes when it's set
+func (s GCSDataStore) list(ctx context.Context, prefix, delimiter, pageToken string) (*gcsObjects, error) {
+	query := url.Values{"prefix": {prefix}}
+	if delimiter != "" {
+		query.Set("delimiter", delimiter)
+	}
+	if pageToken != "" {
+		query.Set("pageToken", pageToken)
+	}
+	requestURL := fmt.Sprintf("%s/storage/v1/b/%s/o?%s", s.endpoint, url.PathEscape(s.bucketName), query.Encode())
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &gcsObjects{}
+	if err := s.send(request, page); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// gcsStatusError is a response of the JSON API with an unexpected status
+type gcsStatusError struct {
+	status int
+	body   string
+}
+
+func (e *gcsStatusError) Error() string {
+	return fmt.Sprintf("unexpected status %d: %s", e.status, e.body)
+}
+
+// send authorizes and sends a request, decoding the JSON response into out when it's not nil
+func (s GCSDataStore) send(request *http.Request, out any) error {
+	token, err := s.tokens.Token(request.Context())
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Authorization", "Bearer "+token)
+
+	response, err := s.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return &gcsStatusError{status: response.StatusCode, body: strings.TrimSpace(string(body))}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(response.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// Token returns the cached access token, or requests a new one
+func (t *gcsTokens) Token(ctx context.Context) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token != "" && t.now().Add(gcsTokenRefreshMargin).Before(t.expiresAt) {
+		return t.token, nil
+	}
+
+	var request *http.Request
+	var err error
+	if t.key == nil {
+		request, err = http.NewRequestWithContext(ctx, http.MethodGet, gcsMetadataTokenURL, nil)
+		if err != nil {
+			return "", fmt.Errorf("failed to get GCS access token: %w", err)
+		}
+		request.Header.Set("Metadata-Flavor", "Google")
+	} else {
+		assertion, err := t.assertion()
+		if err != nil {
+			return "", err
+		}
+		form := url.Values{"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"}, "assertion": {assertion}}
+		request, err = http.NewRequestWithContext(ctx, http.MethodPost, t.key.TokenURI, strings.NewReader(form.Encode()))
+		if err != nil {
+			return "", fmt.Errorf("failed to get GCS access token: %w", err)
+		}
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	response, err := t.httpClient.Do(request)
+	if err != nil {
+		return "", fmt.Errorf("failed to get GCS access token: %w", err)
+	}
+	defer func() { _ = response.Body.Close() }()
+	if response.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return "", fmt.Errorf("failed to get GCS access token: %w", &gcsStatusError{status: response.StatusCode, body: strings.TrimSpace(string(body))})
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("failed to decode GCS access token: %w", err)
+	}
+
+	t.token = token.AccessToken
+	t.expiresAt = t.now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	return t.token, nil
+}
+
+// assertion signs the RS256 JWT exchanged for an access token of the service account, valid for an hour
+func (t *gcsTokens) assertion() (string, error) {
+	now := t.now()
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]any{
+		"iss":   t.key.ClientEmail,
+		"scope": gcsScope,
+		"aud":   t.key.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, t.signer, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign GCS token request: %w", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// parseServiceAccountKey parses the PEM encoded PKCS#8 RSA key of a service account key file
+func parseServiceAccountKey(privateKey string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(privateKey))
+	if block == nil {
+		return nil, errors.New("private key is not PEM encoded")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not an RSA key")
+	}
+	return key, nil
+}
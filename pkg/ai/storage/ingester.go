@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// IngestionStatus is the state of a knowledge base ingestion job.
+type IngestionStatus string
+
+const (
+	// IngestionStatusStarting means the job was accepted but hasn't scanned the data source yet.
+	IngestionStatusStarting IngestionStatus = "starting"
+
+	// IngestionStatusInProgress means the job is scanning and indexing documents.
+	IngestionStatusInProgress IngestionStatus = "in_progress"
+
+	// IngestionStatusComplete means the knowledge base matches the data source.
+	IngestionStatusComplete IngestionStatus = "complete"
+
+	// IngestionStatusFailed means the job stopped on an error; see its failure reasons.
+	IngestionStatusFailed IngestionStatus = "failed"
+
+	// IngestionStatusStopping means the job was asked to stop and is winding down.
+	IngestionStatusStopping IngestionStatus = "stopping"
+
+	// IngestionStatusStopped means the job was stopped before it completed.
+	IngestionStatusStopped IngestionStatus = "stopped"
+)
+
+// Running reports whether a job in this state is still syncing the knowledge base.
+func (s IngestionStatus) Running() bool {
+	return s == IngestionStatusStarting || s == IngestionStatusInProgress || s == IngestionStatusStopping
+}
+
+// IngestionJob is one sync of a knowledge base with its data source.
+type IngestionJob struct {
+	JobID            string
+	Status           IngestionStatus
+	DocumentsScanned int
+	DocumentsIndexed int // New and modified documents
+	DocumentsDeleted int
+	DocumentsFailed  int
+	FailureReasons   []string
+	StartedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// Ingester syncs knowledge bases with the documents of their data source.
+//
+//go:generate mockgen -destination=./mocks/mock_ingester.go -mock_names=Ingester=MockIngester -package=mocks . Ingester
+type Ingester interface {
+	// StartIngestion starts syncing the knowledge base with its data source and returns the started job.
+	StartIngestion(ctx context.Context, knowledgeBaseID string) (*IngestionJob, error)
+
+	// ListIngestions returns up to limit ingestion jobs of the knowledge base, most recent first.
+	ListIngestions(ctx context.Context, knowledgeBaseID string, limit int) ([]IngestionJob, error)
+}
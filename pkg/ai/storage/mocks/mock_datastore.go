@@ -9,6 +9,7 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	storage "github.com/kazemisoroush/code-refactoring-tool/pkg/ai/storage"
 )
 
 // MockDataStore is a mock of DataStore interface.
@@ -34,59 +35,45 @@ func (m *MockDataStore) EXPECT() *MockDataStoreMockRecorder {
 	return m.recorder
 }
 
-// Create mocks base method.
-func (m *MockDataStore) Create(arg0 context.Context, arg1 string) (string, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", arg0, arg1)
-	ret0, _ := ret[0].(string)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
-
-// Create indicates an expected call of Create.
-func (mr *MockDataStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDataStore)(nil).Create), arg0, arg1)
-}
-
-// Delete mocks base method.
-func (m *MockDataStore) Delete(arg0 context.Context, arg1, arg2 string) error {
+// DeleteDirectory mocks base method.
+func (m *MockDataStore) DeleteDirectory(arg0 context.Context, arg1 string, arg2 func(int)) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "DeleteDirectory", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Delete indicates an expected call of Delete.
-func (mr *MockDataStoreMockRecorder) Delete(arg0, arg1, arg2 interface{}) *gomock.Call {
+// DeleteDirectory indicates an expected call of DeleteDirectory.
+func (mr *MockDataStoreMockRecorder) DeleteDirectory(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDataStore)(nil).Delete), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDirectory", reflect.TypeOf((*MockDataStore)(nil).DeleteDirectory), arg0, arg1, arg2)
 }
 
-// DeleteDirectory mocks base method.
-func (m *MockDataStore) DeleteDirectory(arg0 context.Context, arg1 string) error {
+// ListDirectories mocks base method.
+func (m *MockDataStore) ListDirectories(arg0 context.Context, arg1 string) ([]string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteDirectory", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "ListDirectories", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteDirectory indicates an expected call of DeleteDirectory.
-func (mr *MockDataStoreMockRecorder) DeleteDirectory(arg0, arg1 interface{}) *gomock.Call {
+// ListDirectories indicates an expected call of ListDirectories.
+func (mr *MockDataStoreMockRecorder) ListDirectories(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDirectory", reflect.TypeOf((*MockDataStore)(nil).DeleteDirectory), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDirectories", reflect.TypeOf((*MockDataStore)(nil).ListDirectories), arg0, arg1)
 }
 
 // UploadDirectory mocks base method.
-func (m *MockDataStore) UploadDirectory(arg0 context.Context, arg1, arg2 string) error {
+func (m *MockDataStore) UploadDirectory(arg0 context.Context, arg1, arg2 string, arg3 func(storage.UploadProgress)) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UploadDirectory", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "UploadDirectory", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UploadDirectory indicates an expected call of UploadDirectory.
-func (mr *MockDataStoreMockRecorder) UploadDirectory(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockDataStoreMockRecorder) UploadDirectory(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadDirectory", reflect.TypeOf((*MockDataStore)(nil).UploadDirectory), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadDirectory", reflect.TypeOf((*MockDataStore)(nil).UploadDirectory), arg0, arg1, arg2, arg3)
 }
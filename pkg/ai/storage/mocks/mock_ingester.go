@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/pkg/ai/storage (interfaces: Ingester)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	storage "github.com/kazemisoroush/code-refactoring-tool/pkg/ai/storage"
+)
+
+// MockIngester is a mock of Ingester interface.
+type MockIngester struct {
+	ctrl     *gomock.Controller
+	recorder *MockIngesterMockRecorder
+}
+
+// MockIngesterMockRecorder is the mock recorder for MockIngester.
+type MockIngesterMockRecorder struct {
+	mock *MockIngester
+}
+
+// NewMockIngester creates a new mock instance.
+func NewMockIngester(ctrl *gomock.Controller) *MockIngester {
+	mock := &MockIngester{ctrl: ctrl}
+	mock.recorder = &MockIngesterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIngester) EXPECT() *MockIngesterMockRecorder {
+	return m.recorder
+}
+
+// ListIngestions mocks base method.
+func (m *MockIngester) ListIngestions(arg0 context.Context, arg1 string, arg2 int) ([]storage.IngestionJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIngestions", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]storage.IngestionJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIngestions indicates an expected call of ListIngestions.
+func (mr *MockIngesterMockRecorder) ListIngestions(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIngestions", reflect.TypeOf((*MockIngester)(nil).ListIngestions), arg0, arg1, arg2)
+}
+
+// StartIngestion mocks base method.
+func (m *MockIngester) StartIngestion(arg0 context.Context, arg1 string) (*storage.IngestionJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartIngestion", arg0, arg1)
+	ret0, _ := ret[0].(*storage.IngestionJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartIngestion indicates an expected call of StartIngestion.
+func (mr *MockIngesterMockRecorder) StartIngestion(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartIngestion", reflect.TypeOf((*MockIngester)(nil).StartIngestion), arg0, arg1)
+}
@@ -35,15 +35,15 @@ func (m *MockStorage) EXPECT() *MockStorageMockRecorder {
 }
 
 // EnsureSchema mocks base method.
-func (m *MockStorage) EnsureSchema(arg0 context.Context, arg1, arg2 string) error {
+func (m *MockStorage) EnsureSchema(arg0 context.Context, arg1, arg2 string, arg3 int32) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "EnsureSchema", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "EnsureSchema", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // EnsureSchema indicates an expected call of EnsureSchema.
-func (mr *MockStorageMockRecorder) EnsureSchema(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) EnsureSchema(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureSchema", reflect.TypeOf((*MockStorage)(nil).EnsureSchema), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureSchema", reflect.TypeOf((*MockStorage)(nil).EnsureSchema), arg0, arg1, arg2, arg3)
 }
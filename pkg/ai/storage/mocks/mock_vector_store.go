@@ -0,0 +1,95 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/pkg/ai/storage (interfaces: VectorStore)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	storage "github.com/kazemisoroush/code-refactoring-tool/pkg/ai/storage"
+)
+
+// MockVectorStore is a mock of VectorStore interface.
+type MockVectorStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockVectorStoreMockRecorder
+}
+
+// MockVectorStoreMockRecorder is the mock recorder for MockVectorStore.
+type MockVectorStoreMockRecorder struct {
+	mock *MockVectorStore
+}
+
+// NewMockVectorStore creates a new mock instance.
+func NewMockVectorStore(ctrl *gomock.Controller) *MockVectorStore {
+	mock := &MockVectorStore{ctrl: ctrl}
+	mock.recorder = &MockVectorStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVectorStore) EXPECT() *MockVectorStoreMockRecorder {
+	return m.recorder
+}
+
+// DeleteBySource mocks base method.
+func (m *MockVectorStore) DeleteBySource(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBySource", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBySource indicates an expected call of DeleteBySource.
+func (mr *MockVectorStoreMockRecorder) DeleteBySource(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBySource", reflect.TypeOf((*MockVectorStore)(nil).DeleteBySource), arg0, arg1, arg2)
+}
+
+// Query mocks base method.
+func (m *MockVectorStore) Query(arg0 context.Context, arg1 string, arg2 []float32, arg3 int) ([]storage.VectorMatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Query", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]storage.VectorMatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Query indicates an expected call of Query.
+func (mr *MockVectorStoreMockRecorder) Query(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Query", reflect.TypeOf((*MockVectorStore)(nil).Query), arg0, arg1, arg2, arg3)
+}
+
+// Scan mocks base method.
+func (m *MockVectorStore) Scan(arg0 context.Context, arg1, arg2 string, arg3 int) ([]storage.VectorRecord, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Scan", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]storage.VectorRecord)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Scan indicates an expected call of Scan.
+func (mr *MockVectorStoreMockRecorder) Scan(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scan", reflect.TypeOf((*MockVectorStore)(nil).Scan), arg0, arg1, arg2, arg3)
+}
+
+// Upsert mocks base method.
+func (m *MockVectorStore) Upsert(arg0 context.Context, arg1 string, arg2 []storage.VectorRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockVectorStoreMockRecorder) Upsert(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockVectorStore)(nil).Upsert), arg0, arg1, arg2)
+}
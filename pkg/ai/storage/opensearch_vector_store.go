@@ -0,0 +1,397 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+const (
+	// openSearchService is the service requests to OpenSearch Serverless collections are signed for
+	openSearchService = "aoss"
+
+	// openSearchTimeout bounds the requests OpenSearchVectorStore makes
+	openSearchTimeout = 30 * time.Second
+
+	// openSearchPageSize is how many documents are read per request when looking up the documents to replace or
+	// delete
+	openSearchPageSize = 1000
+)
+
+// errOpenSearchNotFound is returned by requests to an index that doesn't exist
+var errOpenSearchNotFound = errors.New("opensearch index not found")
+
+// OpenSearchVectorStore implements VectorStore with an Amazon OpenSearch Serverless vector search collection, keeping
+// each collection in a k-NN index of its name. Vector search collections don't accept document IDs, so records are
+// identified by a vector_id field and replaced by deleting their documents. Writes become visible to queries after
+// the collection refreshes its indexes. Similarity is the cosine similarity of the embeddings.
+type OpenSearchVectorStore struct {
+	httpClient  *http.Client
+	endpoint    string
+	region      string
+	credentials aws.CredentialsProvider
+	signer      *v4.Signer
+
+	// created holds the indexes this store made sure exist
+	created sync.Map
+}
+
+// openSearchDocument is the document of a record
+type openSearchDocument struct {
+	VectorID  string            `json:"vector_id"`
+	Source    string            `json:"source"`
+	Text      string            `json:"text"`
+	Embedding []float32         `json:"embedding,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+}
+
+// openSearchHits is the response of a search
+type openSearchHits struct {
+	Hits struct {
+		Hits []struct {
+			ID     string             `json:"_id"`
+			Score  float64            `json:"_score"`
+			Source openSearchDocument `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+// openSearchBulkResponse is the response of a bulk request, with an item per action
+type openSearchBulkResponse struct {
+	Errors bool                                   `json:"errors"`
+	Items  []map[string]openSearchBulkItemOutcome `json:"items"`
+}
+
+// openSearchBulkItemOutcome is the outcome of an action of a bulk request
+type openSearchBulkItemOutcome struct {
+	Status int `json:"status"`
+	Error  *struct {
+		Type   string `json:"type"`
+		Reason string `json:"reason"`
+	} `json:"error"`
+}
+
+// NewOpenSearchVectorStore creates a new OpenSearchVectorStore of the collection at endpoint, signing requests for
+// region with the provided credentials
+func NewOpenSearchVectorStore(endpoint, region string, credentials aws.CredentialsProvider) VectorStore {
+	return &OpenSearchVectorStore{
+		httpClient:  &http.Client{Timeout: openSearchTimeout},
+		endpoint:    strings.TrimSuffix(endpoint, "/"),
+		region:      region,
+		credentials: credentials,
+		signer:      v4.NewSigner(),
+	}
+}
+
+// Upsert implements VectorStore.
+func (s *OpenSearchVectorStore) Upsert(ctx context.Context, collection string, records []VectorRecord) error {
+	if err := validateCollection(collection); err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	if err := s.createIndex(ctx, collection, len(records[0].Embedding)); err != nil {
+		return err
+	}
+
+	// The documents of the records written before are deleted in the request indexing their replacements
+	vectorIDs := make([]string, len(records))
+	for i, record := range records {
+		vectorIDs[i] = record.ID
+	}
+	replaced, err := s.documentIDs(ctx, collection, map[string]any{"terms": map[string]any{"vector_id": vectorIDs}})
+	if err != nil {
+		return fmt.Errorf("failed to find vectors to replace: %w", err)
+	}
+
+	var actions []any
+	for _, documentID := range replaced {
+		actions = append(actions, map[string]any{"delete": map[string]any{"_index": collection, "_id": documentID}})
+	}
+	for _, record := range records {
+		actions = append(actions, map[string]any{"index": map[string]any{"_index": collection}}, openSearchDocument{
+			VectorID:  record.ID,
+			Source:    record.Source,
+			Text:      record.Text,
+			Embedding: record.Embedding,
+			Metadata:  record.Metadata,
+		})
+	}
+
+	if err := s.bulk(ctx, actions); err != nil {
+		return fmt.Errorf("failed to upsert vectors: %w", err)
+	}
+	return nil
+}
+
+// Query implements VectorStore.
+func (s *OpenSearchVectorStore) Query(ctx context.Context, collection string, embedding []float32, limit int) ([]VectorMatch, error) {
+	if err := validateCollection(collection); err != nil {
+		return nil, err
+	}
+
+	body := map[string]any{
+		"size":    limit,
+		"query":   map[string]any{"knn": map[string]any{"embedding": map[string]any{"vector": embedding, "k": limit}}},
+		"_source": map[string]any{"excludes": []string{"embedding"}},
+	}
+	var hits openSearchHits
+	err := s.do(ctx, http.MethodPost, "/"+collection+"/_search", "application/json", body, &hits)
+	if errors.Is(err, errOpenSearchNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query vectors: %w", err)
+	}
+
+	matches := make([]VectorMatch, len(hits.Hits.Hits))
+	for i, hit := range hits.Hits.Hits {
+		matches[i] = VectorMatch{VectorRecord: hit.Source.record(), Score: hit.Score}
+	}
+	return matches, nil
+}
+
+// DeleteBySource implements VectorStore.
+func (s *OpenSearchVectorStore) DeleteBySource(ctx context.Context, collection string, source string) error {
+	if err := validateCollection(collection); err != nil {
+		return err
+	}
+
+	documentIDs, err := s.documentIDs(ctx, collection, map[string]any{"term": map[string]any{"source": source}})
+	if err != nil {
+		return fmt.Errorf("failed to find vectors of %s: %w", source, err)
+	}
+
+	for start := 0; start < len(documentIDs); start += openSearchPageSize {
+		end := min(start+openSearchPageSize, len(documentIDs))
+		actions := make([]any, 0, end-start)
+		for _, documentID := range documentIDs[start:end] {
+			actions = append(actions, map[string]any{"delete": map[string]any{"_index": collection, "_id": documentID}})
+		}
+		if err := s.bulk(ctx, actions); err != nil {
+			return fmt.Errorf("failed to delete vectors of %s: %w", source, err)
+		}
+	}
+	return nil
+}
+
+// Scan implements VectorStore. Records are read in the order of their IDs, the cursor being the last ID read.
+func (s *OpenSearchVectorStore) Scan(ctx context.Context, collection string, cursor string, limit int) ([]VectorRecord, string, error) {
+	if err := validateCollection(collection); err != nil {
+		return nil, "", err
+	}
+
+	body := map[string]any{
+		"size":  limit,
+		"query": map[string]any{"match_all": map[string]any{}},
+		"sort":  []any{map[string]any{"vector_id": "asc"}},
+	}
+	if cursor != "" {
+		body["search_after"] = []string{cursor}
+	}
+	var hits openSearchHits
+	err := s.do(ctx, http.MethodPost, "/"+collection+"/_search", "application/json", body, &hits)
+	if errors.Is(err, errOpenSearchNotFound) {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to scan vectors: %w", err)
+	}
+
+	records := make([]VectorRecord, len(hits.Hits.Hits))
+	for i, hit := range hits.Hits.Hits {
+		records[i] = hit.Source.record()
+	}
+
+	// A short page is the last one
+	if len(records) < limit {
+		return records, "", nil
+	}
+	return records, records[len(records)-1].ID, nil
+}
+
+// createIndex creates the k-NN index of a collection holding embeddings of the provided dimension, unless it exists
+func (s *OpenSearchVectorStore) createIndex(ctx context.Context, collection string, dimension int) error {
+	if _, ok := s.created.Load(collection); ok {
+		return nil
+	}
+
+	err := s.do(ctx, http.MethodHead, "/"+collection, "", nil, nil)
+	if errors.Is(err, errOpenSearchNotFound) {
+		body := map[string]any{
+			"settings": map[string]any{"index": map[string]any{"knn": true}},
+			"mappings": map[string]any{"properties": map[string]any{
+				"vector_id": map[string]any{"type": "keyword"},
+				"source":    map[string]any{"type": "keyword"},
+				"text":      map[string]any{"type": "text", "index": false},
+				"metadata":  map[string]any{"type": "object", "enabled": false},
+				"embedding": map[string]any{
+					"type":      "knn_vector",
+					"dimension": dimension,
+					"method":    map[string]any{"name": "hnsw", "engine": "nmslib", "space_type": "cosinesimil"},
+				},
+			}},
+		}
+		err = s.do(ctx, http.MethodPut, "/"+collection, "application/json", body, nil)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create collection %s: %w", collection, err)
+	}
+
+	s.created.Store(collection, struct{}{})
+	return nil
+}
+
+// documentIDs returns the IDs of the documents of a collection matching query, none when the collection doesn't exist
+func (s *OpenSearchVectorStore) documentIDs(ctx context.Context, collection string, query map[string]any) ([]string, error) {
+	var documentIDs []string
+	after := ""
+	for {
+		body := map[string]any{
+			"size":    openSearchPageSize,
+			"query":   query,
+			"sort":    []any{map[string]any{"vector_id": "asc"}},
+			"_source": []string{"vector_id"},
+		}
+		if after != "" {
+			body["search_after"] = []string{after}
+		}
+		var hits openSearchHits
+		err := s.do(ctx, http.MethodPost, "/"+collection+"/_search", "application/json", body, &hits)
+		if errors.Is(err, errOpenSearchNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, hit := range hits.Hits.Hits {
+			documentIDs = append(documentIDs, hit.ID)
+		}
+		if len(hits.Hits.Hits) < openSearchPageSize {
+			return documentIDs, nil
+		}
+		after = hits.Hits.Hits[len(hits.Hits.Hits)-1].Source.VectorID
+	}
+}
+
+// bulk sends actions, each followed by its document when it has one, in a bulk request, failing when any of them
+// fails
+func (s *OpenSearchVectorStore) bulk(ctx context.Context, actions []any) error {
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, action := range actions {
+		if err := encoder.Encode(action); err != nil {
+			return fmt.Errorf("failed to marshal bulk action: %w", err)
+		}
+	}
+
+	var response openSearchBulkResponse
+	if err := s.do(ctx, http.MethodPost, "/_bulk", "application/x-ndjson", body.Bytes(), &response); err != nil {
+		return err
+	}
+	if !response.Errors {
+		return nil
+	}
+	for _, item := range response.Items {
+		for action, outcome := range item {
+			// Deleting a document a concurrent writer deleted already is fine
+			if outcome.Error != nil && !(action == "delete" && outcome.Status == http.StatusNotFound) {
+				return fmt.Errorf("bulk %s failed with %s: %s", action, outcome.Error.Type, outcome.Error.Reason)
+			}
+		}
+	}
+	return nil
+}
+
+// do sends a signed request, with a JSON body unless body is raw bytes, decoding the JSON response into out unless
+// it's nil
+func (s *OpenSearchVectorStore) do(ctx context.Context, method, path, contentType string, body any, out any) error {
+	var payload []byte
+	switch value := body.(type) {
+	case nil:
+	case []byte:
+		payload = value
+	default:
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		payload = encoded
+	}
+
+	req, err := s.signedRequest(ctx, method, path, contentType, payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call OpenSearch: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return errOpenSearchNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("opensearch returned %s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode OpenSearch response: %w", err)
+	}
+	return nil
+}
+
+// signedRequest creates a request to the collection signed with Signature Version 4, which OpenSearch Serverless
+// requires to carry the hash of its payload
+func (s *OpenSearchVectorStore) signedRequest(ctx context.Context, method, path, contentType string, payload []byte) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, s.endpoint+path, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	hash := sha256.Sum256(payload)
+	payloadHash := hex.EncodeToString(hash[:])
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	credentials, err := s.credentials.Retrieve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+	}
+	if err := s.signer.SignHTTP(ctx, credentials, req, payloadHash, openSearchService, s.region, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to sign request: %w", err)
+	}
+	return req, nil
+}
+
+// record converts a document to the record it holds
+func (d openSearchDocument) record() VectorRecord {
+	return VectorRecord{
+		ID:        d.VectorID,
+		Source:    d.Source,
+		Text:      d.Text,
+		Embedding: d.Embedding,
+		Metadata:  d.Metadata,
+	}
+}
@@ -0,0 +1,260 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+	"github.com/lib/pq"
+)
+
+// pgUndefinedTable is the PostgreSQL error code of statements naming a table that doesn't exist
+const pgUndefinedTable = "42P01"
+
+// PgvectorVectorStore implements VectorStore with PostgreSQL and the pgvector extension, keeping each collection in
+// a table of its name. Similarity is the cosine similarity of the embeddings.
+type PgvectorVectorStore struct {
+	db *sql.DB
+
+	// created holds the collections whose table this store made sure exists
+	created sync.Map
+}
+
+// NewPgvectorVectorStore creates a new PgvectorVectorStore on an existing database connection
+func NewPgvectorVectorStore(db *sql.DB) VectorStore {
+	return &PgvectorVectorStore{db: db}
+}
+
+// OpenPgvectorVectorStore connects to a PostgreSQL database and creates a new PgvectorVectorStore on it
+func OpenPgvectorVectorStore(cfg config.PostgresConfig) (VectorStore, error) {
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		cfg.Host, cfg.Port, cfg.Username, cfg.Password, cfg.Database, cfg.SSLMode)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PostgreSQL connection: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to ping PostgreSQL database: %w", err)
+	}
+
+	return NewPgvectorVectorStore(db), nil
+}
+
+// Upsert implements VectorStore.
+func (s *PgvectorVectorStore) Upsert(ctx context.Context, collection string, records []VectorRecord) error {
+	if err := validateCollection(collection); err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	if err := s.createTable(ctx, collection, len(records[0].Embedding)); err != nil {
+		return err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (id, source, text, embedding, metadata) VALUES ($1, $2, $3, $4::vector, $5)
+		ON CONFLICT (id) DO UPDATE SET
+			source = EXCLUDED.source, text = EXCLUDED.text, embedding = EXCLUDED.embedding, metadata = EXCLUDED.metadata
+	`, collection)
+	for _, record := range records {
+		metadataJSON, err := json.Marshal(record.Metadata)
+		if err != nil {
+			return fmt.Errorf("failed to marshal metadata of vector %s: %w", record.ID, err)
+		}
+		if _, err := tx.ExecContext(ctx, query, record.ID, record.Source, record.Text, formatPgvector(record.Embedding), metadataJSON); err != nil {
+			return fmt.Errorf("failed to upsert vector %s: %w", record.ID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit vectors: %w", err)
+	}
+	return nil
+}
+
+// Query implements VectorStore.
+func (s *PgvectorVectorStore) Query(ctx context.Context, collection string, embedding []float32, limit int) ([]VectorMatch, error) {
+	if err := validateCollection(collection); err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, source, text, metadata, 1 - (embedding <=> $1::vector) AS score
+		FROM %s ORDER BY embedding <=> $1::vector LIMIT $2
+	`, collection)
+	rows, err := s.db.QueryContext(ctx, query, formatPgvector(embedding), limit)
+	if err != nil {
+		if isUndefinedTable(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to query vectors: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var matches []VectorMatch
+	for rows.Next() {
+		var match VectorMatch
+		var metadataJSON []byte
+		if err := rows.Scan(&match.ID, &match.Source, &match.Text, &metadataJSON, &match.Score); err != nil {
+			return nil, fmt.Errorf("failed to scan vector row: %w", err)
+		}
+		if err := json.Unmarshal(metadataJSON, &match.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal metadata of vector %s: %w", match.ID, err)
+		}
+		matches = append(matches, match)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over vector rows: %w", err)
+	}
+
+	return matches, nil
+}
+
+// DeleteBySource implements VectorStore.
+func (s *PgvectorVectorStore) DeleteBySource(ctx context.Context, collection string, source string) error {
+	if err := validateCollection(collection); err != nil {
+		return err
+	}
+
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE source = $1`, collection), source)
+	if err != nil && !isUndefinedTable(err) {
+		return fmt.Errorf("failed to delete vectors of %s: %w", source, err)
+	}
+	return nil
+}
+
+// Scan implements VectorStore. Records are read in the order of their IDs, the cursor being the last ID read.
+func (s *PgvectorVectorStore) Scan(ctx context.Context, collection string, cursor string, limit int) ([]VectorRecord, string, error) {
+	if err := validateCollection(collection); err != nil {
+		return nil, "", err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, source, text, embedding::text, metadata
+		FROM %s WHERE id > $1 ORDER BY id LIMIT $2
+	`, collection)
+	rows, err := s.db.QueryContext(ctx, query, cursor, limit)
+	if err != nil {
+		if isUndefinedTable(err) {
+			return nil, "", nil
+		}
+		return nil, "", fmt.Errorf("failed to scan vectors: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var records []VectorRecord
+	for rows.Next() {
+		record, err := scanPgvectorRecord(rows)
+		if err != nil {
+			return nil, "", err
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("failed to iterate over vector rows: %w", err)
+	}
+
+	// A short page is the last one
+	if len(records) < limit {
+		return records, "", nil
+	}
+	return records, records[len(records)-1].ID, nil
+}
+
+// createTable creates the table of a collection holding embeddings of the provided dimension, unless it exists
+func (s *PgvectorVectorStore) createTable(ctx context.Context, collection string, dimension int) error {
+	if _, ok := s.created.Load(collection); ok {
+		return nil
+	}
+
+	statements := []string{
+		`CREATE EXTENSION IF NOT EXISTS vector`,
+		fmt.Sprintf(`
+			CREATE TABLE IF NOT EXISTS %s (
+				id TEXT PRIMARY KEY,
+				source TEXT NOT NULL,
+				text TEXT NOT NULL,
+				embedding vector(%d) NOT NULL,
+				metadata JSONB NOT NULL DEFAULT '{}'
+			)
+		`, collection, dimension),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS idx_%s_source ON %s (source)`, collection, collection),
+	}
+	for _, statement := range statements {
+		if _, err := s.db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("failed to create collection %s: %w", collection, err)
+		}
+	}
+
+	s.created.Store(collection, struct{}{})
+	return nil
+}
+
+// scanPgvectorRecord reads a record from a row of id, source, text, embedding and metadata
+func scanPgvectorRecord(rows *sql.Rows) (VectorRecord, error) {
+	var record VectorRecord
+	var embedding string
+	var metadataJSON []byte
+	if err := rows.Scan(&record.ID, &record.Source, &record.Text, &embedding, &metadataJSON); err != nil {
+		return record, fmt.Errorf("failed to scan vector row: %w", err)
+	}
+
+	var err error
+	record.Embedding, err = parsePgvector(embedding)
+	if err != nil {
+		return record, fmt.Errorf("failed to parse embedding of vector %s: %w", record.ID, err)
+	}
+	if err := json.Unmarshal(metadataJSON, &record.Metadata); err != nil {
+		return record, fmt.Errorf("failed to unmarshal metadata of vector %s: %w", record.ID, err)
+	}
+	return record, nil
+}
+
+// formatPgvector formats an embedding as a pgvector literal, such as [0.1,0.2]
+func formatPgvector(embedding []float32) string {
+	values := make([]string, len(embedding))
+	for i, value := range embedding {
+		values[i] = strconv.FormatFloat(float64(value), 'g', -1, 32)
+	}
+	return "[" + strings.Join(values, ",") + "]"
+}
+
+// parsePgvector parses a pgvector literal into an embedding
+func parsePgvector(literal string) ([]float32, error) {
+	literal = strings.TrimSuffix(strings.TrimPrefix(literal, "["), "]")
+	if literal == "" {
+		return nil, nil
+	}
+
+	values := strings.Split(literal, ",")
+	embedding := make([]float32, len(values))
+	for i, value := range values {
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 32)
+		if err != nil {
+			return nil, err
+		}
+		embedding[i] = float32(parsed)
+	}
+	return embedding, nil
+}
+
+// isUndefinedTable reports whether a statement failed as its table doesn't exist
+func isUndefinedTable(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == pgUndefinedTable
+}
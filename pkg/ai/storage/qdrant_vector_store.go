@@ -0,0 +1,270 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// qdrantTimeout bounds the requests QdrantVectorStore makes
+const qdrantTimeout = 30 * time.Second
+
+// errQdrantNotFound is returned by requests to a collection that doesn't exist
+var errQdrantNotFound = errors.New("qdrant collection not found")
+
+// QdrantVectorStore implements VectorStore with a Qdrant cluster, calling its REST API. Qdrant only accepts UUIDs and
+// integers as point IDs, so points are identified by a UUID derived from the ID of their record, which is kept in
+// their payload. Similarity is the cosine similarity of the embeddings.
+type QdrantVectorStore struct {
+	httpClient *http.Client
+	baseURL    string
+	apiKey     string
+
+	// created holds the collections this store made sure exist
+	created sync.Map
+}
+
+// qdrantPayload is the payload of the point of a record
+type qdrantPayload struct {
+	ID       string            `json:"id"`
+	Source   string            `json:"source"`
+	Text     string            `json:"text"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// qdrantPoint is a point read or written through the REST API
+type qdrantPoint struct {
+	ID      string        `json:"id"`
+	Vector  []float32     `json:"vector,omitempty"`
+	Payload qdrantPayload `json:"payload"`
+	Score   float64       `json:"score,omitempty"`
+}
+
+// qdrantSourceFilter matches the points of the records cut from a source
+type qdrantSourceFilter struct {
+	Must []qdrantMatch `json:"must"`
+}
+
+// qdrantMatch is a condition on a payload field
+type qdrantMatch struct {
+	Key   string `json:"key"`
+	Match struct {
+		Value string `json:"value"`
+	} `json:"match"`
+}
+
+// NewQdrantVectorStore creates a new QdrantVectorStore calling the REST API at baseURL. Requests carry apiKey, unless
+// it's empty.
+func NewQdrantVectorStore(baseURL, apiKey string) VectorStore {
+	return &QdrantVectorStore{
+		httpClient: &http.Client{Timeout: qdrantTimeout},
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		apiKey:     apiKey,
+	}
+}
+
+// Upsert implements VectorStore.
+func (s *QdrantVectorStore) Upsert(ctx context.Context, collection string, records []VectorRecord) error {
+	if err := validateCollection(collection); err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	if err := s.createCollection(ctx, collection, len(records[0].Embedding)); err != nil {
+		return err
+	}
+
+	points := make([]qdrantPoint, len(records))
+	for i, record := range records {
+		points[i] = qdrantPoint{
+			ID:     qdrantPointID(record.ID),
+			Vector: record.Embedding,
+			Payload: qdrantPayload{
+				ID:       record.ID,
+				Source:   record.Source,
+				Text:     record.Text,
+				Metadata: record.Metadata,
+			},
+		}
+	}
+
+	body := map[string]any{"points": points}
+	if err := s.do(ctx, http.MethodPut, "/collections/"+collection+"/points?wait=true", body, nil); err != nil {
+		return fmt.Errorf("failed to upsert vectors: %w", err)
+	}
+	return nil
+}
+
+// Query implements VectorStore.
+func (s *QdrantVectorStore) Query(ctx context.Context, collection string, embedding []float32, limit int) ([]VectorMatch, error) {
+	if err := validateCollection(collection); err != nil {
+		return nil, err
+	}
+
+	body := map[string]any{"vector": embedding, "limit": limit, "with_payload": true}
+	var response struct {
+		Result []qdrantPoint `json:"result"`
+	}
+	err := s.do(ctx, http.MethodPost, "/collections/"+collection+"/points/search", body, &response)
+	if errors.Is(err, errQdrantNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query vectors: %w", err)
+	}
+
+	matches := make([]VectorMatch, len(response.Result))
+	for i, point := range response.Result {
+		matches[i] = VectorMatch{VectorRecord: point.record(), Score: point.Score}
+	}
+	return matches, nil
+}
+
+// DeleteBySource implements VectorStore.
+func (s *QdrantVectorStore) DeleteBySource(ctx context.Context, collection string, source string) error {
+	if err := validateCollection(collection); err != nil {
+		return err
+	}
+
+	body := map[string]any{"filter": newQdrantSourceFilter(source)}
+	err := s.do(ctx, http.MethodPost, "/collections/"+collection+"/points/delete?wait=true", body, nil)
+	if err != nil && !errors.Is(err, errQdrantNotFound) {
+		return fmt.Errorf("failed to delete vectors of %s: %w", source, err)
+	}
+	return nil
+}
+
+// Scan implements VectorStore. Records are read in the order of their point IDs, the cursor being the ID of the
+// first point of the next page.
+func (s *QdrantVectorStore) Scan(ctx context.Context, collection string, cursor string, limit int) ([]VectorRecord, string, error) {
+	if err := validateCollection(collection); err != nil {
+		return nil, "", err
+	}
+
+	body := map[string]any{"limit": limit, "with_payload": true, "with_vector": true}
+	if cursor != "" {
+		body["offset"] = cursor
+	}
+	var response struct {
+		Result struct {
+			Points         []qdrantPoint `json:"points"`
+			NextPageOffset *string       `json:"next_page_offset"`
+		} `json:"result"`
+	}
+	err := s.do(ctx, http.MethodPost, "/collections/"+collection+"/points/scroll", body, &response)
+	if errors.Is(err, errQdrantNotFound) {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to scan vectors: %w", err)
+	}
+
+	records := make([]VectorRecord, len(response.Result.Points))
+	for i, point := range response.Result.Points {
+		records[i] = point.record()
+	}
+	next := ""
+	if response.Result.NextPageOffset != nil {
+		next = *response.Result.NextPageOffset
+	}
+	return records, next, nil
+}
+
+// createCollection creates a collection holding embeddings of the provided dimension, with its points indexed by
+// source, unless it exists
+func (s *QdrantVectorStore) createCollection(ctx context.Context, collection string, dimension int) error {
+	if _, ok := s.created.Load(collection); ok {
+		return nil
+	}
+
+	err := s.do(ctx, http.MethodGet, "/collections/"+collection, nil, nil)
+	if errors.Is(err, errQdrantNotFound) {
+		body := map[string]any{"vectors": map[string]any{"size": dimension, "distance": "Cosine"}}
+		if err := s.do(ctx, http.MethodPut, "/collections/"+collection, body, nil); err != nil {
+			return fmt.Errorf("failed to create collection %s: %w", collection, err)
+		}
+		index := map[string]any{"field_name": "source", "field_schema": "keyword"}
+		err = s.do(ctx, http.MethodPut, "/collections/"+collection+"/index?wait=true", index, nil)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create collection %s: %w", collection, err)
+	}
+
+	s.created.Store(collection, struct{}{})
+	return nil
+}
+
+// do sends a request with a JSON body, decoding the JSON response into out unless it's nil
+func (s *QdrantVectorStore) do(ctx context.Context, method, path string, body any, out any) error {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, s.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.apiKey != "" {
+		req.Header.Set("api-key", s.apiKey)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call Qdrant: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return errQdrantNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("qdrant returned %s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode Qdrant response: %w", err)
+	}
+	return nil
+}
+
+// record converts a point to the record it holds
+func (p qdrantPoint) record() VectorRecord {
+	return VectorRecord{
+		ID:        p.Payload.ID,
+		Source:    p.Payload.Source,
+		Text:      p.Payload.Text,
+		Embedding: p.Vector,
+		Metadata:  p.Payload.Metadata,
+	}
+}
+
+// newQdrantSourceFilter creates a filter matching the points of the records cut from source
+func newQdrantSourceFilter(source string) qdrantSourceFilter {
+	match := qdrantMatch{Key: "source"}
+	match.Match.Value = source
+	return qdrantSourceFilter{Must: []qdrantMatch{match}}
+}
+
+// qdrantPointID derives the ID of the point of a record from the ID of the record
+func qdrantPointID(recordID string) string {
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(recordID)).String()
+}
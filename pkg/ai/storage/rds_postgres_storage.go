@@ -27,8 +27,9 @@ var response struct {
 }
 
 // NewRDSPostgresStorage initializes an RDSPostgresStorage using the given AWS config and Lambda ARN.
-// The Lambda must accept a JSON payload of the form { "table": "<table_name>" }
-// and ensure the schema is created in a Postgres database.
+// The Lambda must accept a JSON payload of the form { "database": "<database_name>", "table": "<table_name>",
+// "dimensions": <dimensions> } and ensure the schema is created in a Postgres database, with an embedding column of
+// the given dimensions.
 func NewRDSPostgresStorage(awsConfig aws.Config, lambdaARN string) Storage {
 	return &RDSPostgresStorage{
 		client:    lambda.NewFromConfig(awsConfig),
@@ -37,11 +38,13 @@ func NewRDSPostgresStorage(awsConfig aws.Config, lambdaARN string) Storage {
 }
 
 // EnsureSchema triggers the Lambda function to create the schema/table in the RDS Postgres database.
-// It sends the database name and table name in the request payload and parses the response to confirm success or capture errors.
-func (c *RDSPostgresStorage) EnsureSchema(ctx context.Context, databaseName string, tableName string) error {
-	payload := map[string]string{
-		"database": databaseName,
-		"table":    tableName,
+// It sends the database name, table name and vector dimensions in the request payload and parses the response to
+// confirm success or capture errors.
+func (c *RDSPostgresStorage) EnsureSchema(ctx context.Context, databaseName string, tableName string, dimensions int32) error {
+	payload := map[string]any{
+		"database":   databaseName,
+		"table":      tableName,
+		"dimensions": dimensions,
 	}
 	data, err := json.Marshal(payload)
 	if err != nil {
@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/resilience"
+)
+
+// ResilientDataStore guards the calls to another DataStore with a circuit breaker, retrying the deletions and listings
+// that fail transiently. Deletions skip what's already deleted, so they're safe to retry. Uploads retry the files that
+// failed themselves, so they aren't retried as a whole.
+type ResilientDataStore struct {
+	dataStore DataStore
+	guard     *resilience.Guard
+}
+
+// NewResilientDataStore creates a data store guarding the calls to dataStore with guard
+func NewResilientDataStore(dataStore DataStore, guard *resilience.Guard) DataStore {
+	return &ResilientDataStore{
+		dataStore: dataStore,
+		guard:     guard,
+	}
+}
+
+// UploadDirectory uploads a local directory to a remote path in the storage system
+func (d *ResilientDataStore) UploadDirectory(ctx context.Context, localPath, remotePath string, progress func(UploadProgress)) error {
+	return d.guard.DoOnce(ctx, func(ctx context.Context) error {
+		return d.dataStore.UploadDirectory(ctx, localPath, remotePath, progress)
+	})
+}
+
+// DeleteDirectory deletes a remote directory in the storage system
+func (d *ResilientDataStore) DeleteDirectory(ctx context.Context, remotePath string, progress func(deleted int)) error {
+	return d.guard.Do(ctx, func(ctx context.Context) error {
+		return d.dataStore.DeleteDirectory(ctx, remotePath, progress)
+	})
+}
+
+// ListDirectories lists the directories directly under a remote path in the storage system
+func (d *ResilientDataStore) ListDirectories(ctx context.Context, remotePath string) ([]string, error) {
+	return resilience.Call(ctx, d.guard, func(ctx context.Context) ([]string, error) {
+		return d.dataStore.ListDirectories(ctx, remotePath)
+	})
+}
+
+// ResilientDataSource guards the calls to another DataSource with a circuit breaker, retrying the deletions that fail
+// transiently. Creations aren't retried, since a creation whose response was lost may have succeeded.
+type ResilientDataSource struct {
+	dataSource DataSource
+	guard      *resilience.Guard
+}
+
+// NewResilientDataSource creates a data source guarding the calls to dataSource with guard
+func NewResilientDataSource(dataSource DataSource, guard *resilience.Guard) DataSource {
+	return &ResilientDataSource{
+		dataSource: dataSource,
+		guard:      guard,
+	}
+}
+
+// Create creates the data source of a knowledge base
+func (d *ResilientDataSource) Create(ctx context.Context, ragID string) (string, error) {
+	return resilience.CallOnce(ctx, d.guard, func(ctx context.Context) (string, error) {
+		return d.dataSource.Create(ctx, ragID)
+	})
+}
+
+// Delete removes the data source from its knowledge base
+func (d *ResilientDataSource) Delete(ctx context.Context, dataSourceID string, ragID string) error {
+	return d.guard.Do(ctx, func(ctx context.Context) error {
+		return d.dataSource.Delete(ctx, dataSourceID, ragID)
+	})
+}
@@ -5,219 +5,119 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"path/filepath"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
-	"github.com/aws/aws-sdk-go-v2/service/bedrockagent"
-	bedrocktypes "github.com/aws/aws-sdk-go-v2/service/bedrockagent/types"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
 
-const (
-	// DataSourceName is the name of the data source used for the code refactoring tool.
-	DataSourceName = "code-refactoring-tool-data-source"
-
-	// DataSourceDescription is the description of the data source used for the code refactoring tool.
-	DataSourceDescription = "Data source for the code refactoring tool knowledge base. This data source is used to store the codebase and other relevant files for the RAG pipeline."
-)
-
-// S3DataStore implements the Storage interface for AWS S3.
+// S3DataStore implements DataStore with an AWS S3 bucket.
 type S3DataStore struct {
 	s3Client   *s3.Client
-	repoName   string
+	uploader   *manager.Uploader
+	uploads    *UploadPipeline
 	bucketName string
-	client     *bedrockagent.Client
 }
 
-// NewS3DataStore creates a new S3Storage instance with the provided bucket name.
-func NewS3DataStore(awsConfig aws.Config, bucketName string, repoName string) DataStore {
+// NewS3DataStore creates a new S3DataStore of the bucket with the provided name, uploading directories with uploads,
+// or with the default pipeline when it's nil. Files larger than a part of the pipeline are uploaded in parts.
+func NewS3DataStore(awsConfig aws.Config, bucketName string, uploads *UploadPipeline) DataStore {
+	if uploads == nil {
+		uploads = DefaultUploadPipeline()
+	}
+	s3Client := s3.NewFromConfig(awsConfig)
 	return &S3DataStore{
-		s3Client:   s3.NewFromConfig(awsConfig),
-		repoName:   repoName,
+		s3Client: s3Client,
+		uploader: manager.NewUploader(s3Client, func(u *manager.Uploader) {
+			u.PartSize = uploads.PartSize()
+		}),
+		uploads:    uploads,
 		bucketName: bucketName,
-		client:     bedrockagent.NewFromConfig(awsConfig),
 	}
 }
 
-// Create checks if the S3 bucket exists and is accessible.
-func (s S3DataStore) Create(ctx context.Context, ragID string) (string, error) {
-	// S3 does not require explicit creation of a bucket, but we can check if it exists.
-	_, err := s.s3Client.HeadBucket(ctx, &s3.HeadBucketInput{
-		Bucket: aws.String(s.bucketName),
-	})
-	if err != nil {
-		return "", fmt.Errorf("failed to access bucket %s: %w", s.bucketName, err)
-	}
-
-	bucketARN := fmt.Sprintf("arn:aws:s3:::%s", s.bucketName)
-
-	// Create bedrock data store
-	response, err := s.client.CreateDataSource(ctx, &bedrockagent.CreateDataSourceInput{
-		Name:               aws.String(DataSourceName),
-		KnowledgeBaseId:    aws.String(ragID),
-		DataDeletionPolicy: bedrocktypes.DataDeletionPolicyDelete,
-		DataSourceConfiguration: &bedrocktypes.DataSourceConfiguration{
-			Type: bedrocktypes.DataSourceTypeS3,
-			S3Configuration: &bedrocktypes.S3DataSourceConfiguration{
-				BucketArn:         aws.String(bucketARN),
-				InclusionPrefixes: []string{s.repoName},
-			},
-		},
-		VectorIngestionConfiguration: &bedrocktypes.VectorIngestionConfiguration{
-			// TODO: Fix chunking here...
-			ChunkingConfiguration: &bedrocktypes.ChunkingConfiguration{
-				ChunkingStrategy: bedrocktypes.ChunkingStrategyHierarchical,
-				HierarchicalChunkingConfiguration: &bedrocktypes.HierarchicalChunkingConfiguration{
-					LevelConfigurations: []bedrocktypes.HierarchicalChunkingLevelConfiguration{
-						{
-							MaxTokens: aws.Int32(1000), // TODO: Use a more suitable value
-						},
-						{
-							MaxTokens: aws.Int32(500), // TODO: Use a more suitable value
-						},
-					},
-					OverlapTokens: aws.Int32(50), // TODO: Use a more suitable value
-				},
-			},
-
-			// // TODO: Do we need this?
-			// ContextEnrichmentConfiguration: &bedrocktypes.ContextEnrichmentConfiguration{
-			// 	Type: bedrocktypes.ContextEnrichmentTypeBedrockFoundationModel,
-			// 	BedrockFoundationModelConfiguration: &bedrocktypes.BedrockFoundationModelContextEnrichmentConfiguration{
-			// 		EnrichmentStrategyConfiguration: &bedrocktypes.EnrichmentStrategyConfiguration{
-			// 			Method: bedrocktypes.EnrichmentStrategyMethodChunkEntityExtraction,
-			// 		},
-			// 		ModelArn: aws.String(
-			// 			fmt.Sprintf(
-			// 				"arn:aws:bedrock:%s::foundation-model/%s",
-			// 				config.AWSRegion,
-			// 				config.AWSBedrockDataStoreEnrichmentModelARN,
-			// 			),
-			// 		),
-			// 	},
-			// },
-
-			// // TODO: This might be needed for code parsing
-			// {"time":"2025-07-20T08:23:18.444387949Z","level":"ERROR","msg":"workflow failed","error":"failed to build RAG pipeline: failed to create data source: failed to create data source: operation error Bedrock Agent: CreateDataSource, https response error StatusCode: 400, RequestID: 31f19628-fc06-4766-956c-4967e7fda73a, ValidationException: A custom transformation configuration cannot have the same s3 bucket for intermediate storage as the data source."}
-			// CustomTransformationConfiguration: &bedrocktypes.CustomTransformationConfiguration{
-			// 	IntermediateStorage: &bedrocktypes.IntermediateStorage{
-			// 		S3Location: &bedrocktypes.S3Location{
-			// 			Uri: aws.String(fmt.Sprintf("s3://%s/%s/", s.bucketName, s.repoName)),
-			// 		},
-			// 	},
-			// 	Transformations: []bedrocktypes.Transformation{
-			// 		{
-			// 			StepToApply: bedrocktypes.StepTypePostChunking,
-			// 			TransformationFunction: &bedrocktypes.TransformationFunction{
-			// 				TransformationLambdaConfiguration: &bedrocktypes.TransformationLambdaConfiguration{
-			// 					LambdaArn: aws.String("arn:aws:lambda:us-east-1:123456789012:function:MyTransformationFunction"), // TODO: Use a more suitable Lambda function
-			// 				},
-			// 			},
-			// 		},
-			// 	},
-			// },
+// UploadDirectory uploads all files in a directory to S3 under the given prefix.
+func (s S3DataStore) UploadDirectory(ctx context.Context, localPath, remotePath string, progress func(UploadProgress)) error {
+	return s.uploads.upload(ctx, localPath, remotePath, s.put, progress)
+}
 
-			// // TODO: Do we need this?
-			// ParsingConfiguration: &bedrocktypes.ParsingConfiguration{
-			// 	ParsingStrategy: bedrocktypes.ParsingStrategyBedrockFoundationModel,
-			// 	BedrockFoundationModelConfiguration: &bedrocktypes.BedrockFoundationModelConfiguration{
-			// 		ModelArn: aws.String(
-			// 			fmt.Sprintf(
-			// 				"arn:aws:bedrock:%s::foundation-model/%s",
-			// 				config.AWSRegion,
-			// 				config.AWSBedrockDataStoreParsingModelARN,
-			// 			),
-			// 		),
-			// 		// Code base could have images, so we use multimodal parsing
-			// 		ParsingModality: bedrocktypes.ParsingModalityMultimodal,
-			// 		ParsingPrompt: &bedrocktypes.ParsingPrompt{
-			// 			ParsingPromptText: aws.String("Extract code and comments from the provided files."), // TODO: Use a more suitable prompt
-			// 		},
-			// 	},
-			// },
-		},
-		Description: aws.String(DataSourceDescription),
-	})
+// put uploads a file to S3, in parts when it's larger than a part
+func (s S3DataStore) put(ctx context.Context, file uploadFile) error {
+	f, err := os.Open(file.path)
 	if err != nil {
-		return "", fmt.Errorf("failed to create data source: %w", err)
+		return permanent(fmt.Errorf("failed to open file %s: %w", file.path, err))
 	}
+	defer func() { _ = f.Close() }()
 
-	return *response.DataSource.DataSourceId, nil
-}
-
-// Delete deletes the data source from the knowledge base.
-func (s S3DataStore) Delete(ctx context.Context, dataSourceID string, ragID string) error {
-	_, err := s.client.DeleteDataSource(ctx, &bedrockagent.DeleteDataSourceInput{
-		DataSourceId:    aws.String(dataSourceID),
-		KnowledgeBaseId: aws.String(ragID),
+	_, err = s.uploader.Upload(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(file.key),
+		Body:   f,
 	})
 	if err != nil {
-		return fmt.Errorf("failed to delete data source: %w", err)
+		return fmt.Errorf("failed to upload %s to S3: %w", file.key, err)
 	}
-
 	return nil
 }
 
-// UploadDirectory uploads all files in a directory to S3 under the given prefix.
-func (s S3DataStore) UploadDirectory(ctx context.Context, localPath, remotePath string) error {
-	uploader := manager.NewUploader(s.s3Client)
-	return filepath.Walk(localPath, func(path string, info os.FileInfo, err error) error {
+// DeleteDirectory deletes all objects under a given prefix in the bucket, a page of at most 1000 objects at a time as
+// DeleteObjects allows.
+func (s S3DataStore) DeleteDirectory(ctx context.Context, prefix string, progress func(deleted int)) error {
+	paginator := s3.NewListObjectsV2Paginator(s.s3Client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucketName),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
 		if err != nil {
-			return fmt.Errorf("failed to walk path %s: %w", path, err)
-		}
-		if info.IsDir() {
-			return nil
+			return fmt.Errorf("failed to list objects in bucket %s with prefix %s: %w", s.bucketName, prefix, err)
 		}
-		relPath, err := filepath.Rel(localPath, path)
-		if err != nil {
-			return fmt.Errorf("failed to get relative path for %s: %w", path, err)
+		if len(page.Contents) == 0 {
+			continue
 		}
-		key := filepath.ToSlash(filepath.Join(remotePath, relPath))
-		f, err := os.Open(path)
-		if err != nil {
-			return fmt.Errorf("failed to open file %s: %w", path, err)
+
+		toDelete := make([]types.ObjectIdentifier, 0, len(page.Contents))
+		for _, obj := range page.Contents {
+			toDelete = append(toDelete, types.ObjectIdentifier{Key: obj.Key})
 		}
-		defer func() {
-			if cerr := f.Close(); cerr != nil {
-				fmt.Fprintf(os.Stderr, "failed to close file %s: %v\n", path, cerr)
-			}
-		}()
-		_, err = uploader.Upload(ctx, &s3.PutObjectInput{
+		output, err := s.s3Client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
 			Bucket: aws.String(s.bucketName),
-			Key:    aws.String(key),
-			Body:   f,
+			Delete: &types.Delete{Objects: toDelete, Quiet: aws.Bool(true)},
 		})
 		if err != nil {
-			return fmt.Errorf("failed to upload %s to S3: %w", key, err)
+			return fmt.Errorf("failed to delete objects in bucket %s with prefix %s: %w", s.bucketName, prefix, err)
 		}
-		return nil
-	})
+		if len(output.Errors) > 0 {
+			failed := output.Errors[0]
+			return fmt.Errorf("failed to delete %d objects in bucket %s with prefix %s, first %s: %s",
+				len(output.Errors), s.bucketName, prefix, aws.ToString(failed.Key), aws.ToString(failed.Message))
+		}
+		if progress != nil {
+			progress(len(toDelete))
+		}
+	}
+
+	return nil
 }
 
-// DeleteDirectory deletes all objects under a given prefix in the bucket.
-func (s S3DataStore) DeleteDirectory(ctx context.Context, prefix string) error {
-	var toDelete []types.ObjectIdentifier
+// ListDirectories lists the prefixes directly under a given prefix in the bucket, each ending with a slash.
+func (s S3DataStore) ListDirectories(ctx context.Context, prefix string) ([]string, error) {
+	var directories []string
 	paginator := s3.NewListObjectsV2Paginator(s.s3Client, &s3.ListObjectsV2Input{
-		Bucket: aws.String(s.bucketName),
-		Prefix: aws.String(prefix),
+		Bucket:    aws.String(s.bucketName),
+		Prefix:    aws.String(prefix),
+		Delimiter: aws.String("/"),
 	})
 	for paginator.HasMorePages() {
 		page, err := paginator.NextPage(ctx)
 		if err != nil {
-			return fmt.Errorf("failed to list objects in bucket %s with prefix %s: %w", s.bucketName, prefix, err)
+			return nil, fmt.Errorf("failed to list prefixes in bucket %s under %s: %w", s.bucketName, prefix, err)
 		}
-		for _, obj := range page.Contents {
-			toDelete = append(toDelete, types.ObjectIdentifier{Key: obj.Key})
+		for _, commonPrefix := range page.CommonPrefixes {
+			directories = append(directories, aws.ToString(commonPrefix.Prefix))
 		}
 	}
-	if len(toDelete) == 0 {
-		return nil
-	}
-	_, err := s.s3Client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
-		Bucket: aws.String(s.bucketName),
-		Delete: &types.Delete{Objects: toDelete},
-	})
-	return fmt.Errorf("failed to delete objects in bucket %s with prefix %s: %w", s.bucketName, prefix, err)
+
+	return directories, nil
 }
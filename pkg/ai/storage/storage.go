@@ -19,8 +19,9 @@ type Storage interface {
 	//   - ctx: Context for managing cancellation and timeouts.
 	//   - databaseName: Name of the database to create or verify.
 	//   - tableName: Name of the table or schema to create or verify.
+	//   - dimensions: Number of dimensions of the vectors stored in the table.
 	//
 	// Returns:
 	//   - error: Any error encountered while ensuring the schema.
-	EnsureSchema(ctx context.Context, databaseName string, tableName string) error
+	EnsureSchema(ctx context.Context, databaseName string, tableName string, dimensions int32) error
 }
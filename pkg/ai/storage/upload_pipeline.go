@@ -0,0 +1,281 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+const (
+	// defaultUploadWorkers is how many files are uploaded at the same time unless configured
+	defaultUploadWorkers = 16
+
+	// defaultUploadAttempts is how many rounds upload the files that failed unless configured
+	defaultUploadAttempts = 3
+
+	// minPartSize is the smallest part S3 accepts in a multipart upload, but for the last one
+	minPartSize = 5 * 1024 * 1024
+
+	// uploadRetryDelay is the delay before the first round retrying the files that failed, doubled for each later one
+	uploadRetryDelay = time.Second
+)
+
+// UploadProgress reports how much of a directory was uploaded
+type UploadProgress struct {
+	Files      int   // Files uploaded
+	TotalFiles int   // Files of the directory
+	Bytes      int64 // Bytes of the files uploaded
+	TotalBytes int64 // Bytes of the files of the directory
+}
+
+// UploadStats measures a completed upload of a directory
+type UploadStats struct {
+	Files    int           // Files uploaded
+	Bytes    int64         // Bytes of the files uploaded
+	Retries  int           // Uploads of files retried after failing
+	Duration time.Duration // Time from walking the directory to the last upload
+}
+
+// Throughput returns the bytes uploaded per second
+func (s UploadStats) Throughput() float64 {
+	if s.Duration <= 0 {
+		return 0
+	}
+	return float64(s.Bytes) / s.Duration.Seconds()
+}
+
+// UploadPipeline uploads the files of a directory with a bounded pool of workers. Files failing to upload are
+// retried in later rounds, so a partial failure only uploads the files it's missing again.
+type UploadPipeline struct {
+	workers  int
+	attempts int
+	partSize int64
+	observe  func(UploadStats)
+	sleep    func(ctx context.Context, d time.Duration) error
+}
+
+// NewUploadPipeline creates a pipeline configured by cfg, whose completed uploads are measured by observe when it's
+// non-nil
+func NewUploadPipeline(cfg config.UploadPipelineConfig, observe func(UploadStats)) *UploadPipeline {
+	workers := cfg.Workers
+	if workers < 1 {
+		workers = defaultUploadWorkers
+	}
+	attempts := cfg.Attempts
+	if attempts < 1 {
+		attempts = defaultUploadAttempts
+	}
+	return &UploadPipeline{
+		workers:  workers,
+		attempts: attempts,
+		partSize: max(int64(cfg.PartSizeMB)*1024*1024, minPartSize),
+		observe:  observe,
+		sleep:    sleep,
+	}
+}
+
+// DefaultUploadPipeline creates a pipeline with the default configuration, whose uploads aren't measured
+func DefaultUploadPipeline() *UploadPipeline {
+	return NewUploadPipeline(config.UploadPipelineConfig{}, nil)
+}
+
+// PartSize returns the size of the parts of multipart uploads
+func (p *UploadPipeline) PartSize() int64 {
+	return p.partSize
+}
+
+// uploadFile is a file of a directory to upload
+type uploadFile struct {
+	path string
+	key  string
+	size int64
+}
+
+// putFunc uploads the file at path to key. Errors wrapped with permanent aren't retried.
+type putFunc func(ctx context.Context, file uploadFile) error
+
+// permanentError marks the failures of an upload retrying can't fix, such as a key a data store rejects
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// permanent marks err as a failure retrying can't fix
+func permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// uploadFailure is a file that failed to upload in a round
+type uploadFailure struct {
+	file uploadFile
+	err  error
+}
+
+// upload uploads the regular files below localPath under remotePath with put. A non-nil progress is called after
+// each file is uploaded, from the worker that uploaded it but never concurrently.
+func (p *UploadPipeline) upload(ctx context.Context, localPath, remotePath string, put putFunc, progress func(UploadProgress)) error {
+	started := time.Now()
+	files, totalBytes, err := listUploadFiles(localPath, remotePath)
+	if err != nil {
+		return err
+	}
+
+	tracker := &uploadTracker{
+		progress: progress,
+		current:  UploadProgress{TotalFiles: len(files), TotalBytes: totalBytes},
+	}
+	stats := UploadStats{}
+	for attempt := 1; ; attempt++ {
+		failures := p.round(ctx, files, put, tracker)
+		if len(failures) == 0 {
+			break
+		}
+
+		first := failures[0]
+		var permanentErr *permanentError
+		if attempt >= p.attempts || ctx.Err() != nil || errors.As(first.err, &permanentErr) {
+			return fmt.Errorf("failed to upload %d of %d files, first %s: %w", len(failures), tracker.current.TotalFiles, first.file.key, first.err)
+		}
+
+		slog.WarnContext(ctx, "retrying failed uploads", "failed", len(failures), "attempt", attempt+1, "first_key", first.file.key, "error", first.err)
+		if err := p.sleep(ctx, uploadRetryDelay<<(attempt-1)); err != nil {
+			return fmt.Errorf("failed to upload %d files: %w", len(failures), err)
+		}
+		files = files[:0]
+		for _, failure := range failures {
+			files = append(files, failure.file)
+		}
+		stats.Retries += len(files)
+	}
+
+	stats.Files = tracker.current.Files
+	stats.Bytes = tracker.current.Bytes
+	stats.Duration = time.Since(started)
+	slog.InfoContext(ctx, "uploaded directory", "remote_path", remotePath, "files", stats.Files, "bytes", stats.Bytes,
+		"retries", stats.Retries, "duration", stats.Duration, "bytes_per_second", stats.Throughput())
+	if p.observe != nil {
+		p.observe(stats)
+	}
+
+	return nil
+}
+
+// round uploads files with the pool of workers, returning the ones that failed ordered by key. Files failing
+// permanently come first, so no more rounds are started for them.
+func (p *UploadPipeline) round(ctx context.Context, files []uploadFile, put putFunc, tracker *uploadTracker) []uploadFailure {
+	queue := make(chan uploadFile)
+	var (
+		mu       sync.Mutex
+		failures []uploadFailure
+		wg       sync.WaitGroup
+	)
+	for range min(p.workers, len(files)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for file := range queue {
+				err := ctx.Err()
+				if err == nil {
+					err = put(ctx, file)
+				}
+				if err != nil {
+					mu.Lock()
+					failures = append(failures, uploadFailure{file: file, err: err})
+					mu.Unlock()
+					continue
+				}
+				tracker.uploaded(file.size)
+			}
+		}()
+	}
+	for _, file := range files {
+		queue <- file
+	}
+	close(queue)
+	wg.Wait()
+
+	sort.Slice(failures, func(i, j int) bool {
+		var permanentErr *permanentError
+		iPermanent, jPermanent := errors.As(failures[i].err, &permanentErr), errors.As(failures[j].err, &permanentErr)
+		if iPermanent != jPermanent {
+			return iPermanent
+		}
+		return failures[i].file.key < failures[j].file.key
+	})
+	return failures
+}
+
+// uploadTracker counts the files uploaded and reports the progress
+type uploadTracker struct {
+	mu       sync.Mutex
+	progress func(UploadProgress)
+	current  UploadProgress
+}
+
+// uploaded counts an uploaded file of size bytes
+func (t *uploadTracker) uploaded(size int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.current.Files++
+	t.current.Bytes += size
+	if t.progress != nil {
+		t.progress(t.current)
+	}
+}
+
+// listUploadFiles lists the regular files below localPath with their keys under remotePath, and their total size
+func listUploadFiles(localPath, remotePath string) ([]uploadFile, int64, error) {
+	var (
+		files []uploadFile
+		total int64
+	)
+	err := filepath.Walk(localPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return fmt.Errorf("failed to walk path %s: %w", path, err)
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		relPath, err := filepath.Rel(localPath, path)
+		if err != nil {
+			return fmt.Errorf("failed to get relative path for %s: %w", path, err)
+		}
+		files = append(files, uploadFile{
+			path: path,
+			key:  filepath.ToSlash(filepath.Join(remotePath, relPath)),
+			size: info.Size(),
+		})
+		total += info.Size()
+		return nil
+	})
+	return files, total, err
+}
+
+// sleep waits for d, or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,139 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// newTestPipeline returns a pipeline that doesn't wait between rounds, recording the stats of its uploads
+func newTestPipeline(attempts int) (*UploadPipeline, *[]UploadStats) {
+	var observed []UploadStats
+	pipeline := NewUploadPipeline(config.UploadPipelineConfig{Workers: 4, Attempts: attempts}, func(stats UploadStats) {
+		observed = append(observed, stats)
+	})
+	pipeline.sleep = func(context.Context, time.Duration) error { return nil }
+	return pipeline, &observed
+}
+
+// writeFiles writes files of the given sizes, keyed by their name, to a temporary directory and returns it
+func writeFiles(t *testing.T, sizes map[string]int) string {
+	dir := t.TempDir()
+	for name, size := range sizes {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), make([]byte, size), 0o600))
+	}
+	return dir
+}
+
+// countingPut records the uploads of each key, failing those fail returns an error for
+type countingPut struct {
+	mu    sync.Mutex
+	calls map[string]int
+	fail  func(key string, call int) error
+}
+
+func (c *countingPut) put(_ context.Context, file uploadFile) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.calls == nil {
+		c.calls = make(map[string]int)
+	}
+	c.calls[file.key]++
+	if c.fail != nil {
+		return c.fail(file.key, c.calls[file.key])
+	}
+	return nil
+}
+
+func TestUploadPipeline_Upload_ReportsProgress(t *testing.T) {
+	pipeline, observed := newTestPipeline(3)
+	dir := writeFiles(t, map[string]int{"a.go": 10, "b.go": 20, "c.go": 30, "d.go": 40, "e.go": 50})
+	put := &countingPut{}
+
+	var reports []UploadProgress
+	err := pipeline.upload(context.Background(), dir, "kb/", put.put, func(progress UploadProgress) {
+		reports = append(reports, progress)
+	})
+
+	require.NoError(t, err)
+	assert.Len(t, put.calls, 5)
+	assert.Equal(t, 1, put.calls["kb/c.go"])
+	require.Len(t, reports, 5)
+	assert.Equal(t, UploadProgress{Files: 5, TotalFiles: 5, Bytes: 150, TotalBytes: 150}, reports[4])
+	require.Len(t, *observed, 1)
+	assert.Equal(t, 5, (*observed)[0].Files)
+	assert.Equal(t, int64(150), (*observed)[0].Bytes)
+	assert.Zero(t, (*observed)[0].Retries)
+}
+
+func TestUploadPipeline_Upload_RetriesFailedFiles(t *testing.T) {
+	pipeline, observed := newTestPipeline(3)
+	dir := writeFiles(t, map[string]int{"a.go": 1, "b.go": 1, "c.go": 1})
+	put := &countingPut{fail: func(key string, call int) error {
+		if key == "kb/b.go" && call < 3 {
+			return errors.New("connection reset")
+		}
+		return nil
+	}}
+
+	err := pipeline.upload(context.Background(), dir, "kb/", put.put, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"kb/a.go": 1, "kb/b.go": 3, "kb/c.go": 1}, put.calls, "only the failed file is uploaded again")
+	require.Len(t, *observed, 1)
+	assert.Equal(t, 3, (*observed)[0].Files)
+	assert.Equal(t, 2, (*observed)[0].Retries)
+}
+
+func TestUploadPipeline_Upload_GivesUpAfterAttempts(t *testing.T) {
+	pipeline, observed := newTestPipeline(2)
+	dir := writeFiles(t, map[string]int{"a.go": 1, "b.go": 1})
+	put := &countingPut{fail: func(key string, _ int) error {
+		if key == "kb/b.go" {
+			return errors.New("connection reset")
+		}
+		return nil
+	}}
+
+	err := pipeline.upload(context.Background(), dir, "kb/", put.put, nil)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to upload 1 of 2 files, first kb/b.go")
+	assert.Equal(t, 2, put.calls["kb/b.go"])
+	assert.Empty(t, *observed, "failed uploads aren't measured")
+}
+
+func TestUploadPipeline_Upload_PermanentFailure(t *testing.T) {
+	pipeline, _ := newTestPipeline(3)
+	dir := writeFiles(t, map[string]int{"a.go": 1, "b.go": 1})
+	put := &countingPut{fail: func(key string, _ int) error {
+		if key == "kb/a.go" {
+			return errors.New("connection reset")
+		}
+		return permanent(errors.New("key rejected"))
+	}}
+
+	err := pipeline.upload(context.Background(), dir, "kb/", put.put, nil)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "key rejected")
+	assert.Equal(t, map[string]int{"kb/a.go": 1, "kb/b.go": 1}, put.calls, "no round is retried once a file fails permanently")
+}
+
+func TestNewUploadPipeline_Defaults(t *testing.T) {
+	pipeline := NewUploadPipeline(config.UploadPipelineConfig{PartSizeMB: 1}, nil)
+
+	assert.Equal(t, defaultUploadWorkers, pipeline.workers)
+	assert.Equal(t, defaultUploadAttempts, pipeline.attempts)
+	assert.Equal(t, int64(minPartSize), pipeline.PartSize(), "parts are at least as large as S3 accepts")
+}
@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// VectorRecord is an embedded chunk of the content of a knowledge base
+type VectorRecord struct {
+	ID        string            // Unique within its collection
+	Source    string            // Content the chunk was cut from, such as the path of a file
+	Text      string            // Chunk embedded
+	Embedding []float32         // Embedding of the chunk, of the same length for all the records of a collection
+	Metadata  map[string]string // Attributes returned with the chunk
+}
+
+// VectorMatch is a record found by a query, with its similarity to the query. Backends measure similarity
+// differently, so scores are only comparable within a backend, higher being more similar.
+type VectorMatch struct {
+	VectorRecord
+	Score float64
+}
+
+// VectorStore interface defines methods for storing and searching the embeddings of knowledge bases. Records are kept
+// in collections, which are created by their first upsert. Querying, deleting from or scanning a collection that
+// doesn't exist finds nothing.
+//
+//go:generate mockgen -destination=./mocks/mock_vector_store.go -mock_names=VectorStore=MockVectorStore -package=mocks . VectorStore
+type VectorStore interface {
+	// Upsert writes records to a collection, replacing the records with the same IDs.
+	Upsert(ctx context.Context, collection string, records []VectorRecord) error
+
+	// Query returns up to limit records of a collection, most similar to embedding first, without their embeddings.
+	Query(ctx context.Context, collection string, embedding []float32, limit int) ([]VectorMatch, error)
+
+	// DeleteBySource deletes the records of a collection cut from source.
+	DeleteBySource(ctx context.Context, collection string, source string) error
+
+	// Scan returns up to limit records of a collection after cursor, with their embeddings, and the cursor of the
+	// next page. The first page is read with an empty cursor, and the cursor after the last page is empty.
+	Scan(ctx context.Context, collection string, cursor string, limit int) ([]VectorRecord, string, error)
+}
+
+// collectionNamePattern matches the collection names every backend accepts as a table, index or collection name
+var collectionNamePattern = regexp.MustCompile(`^[a-z_][a-z0-9_]{0,62}$`)
+
+// validateCollection rejects the collection names some backend can't store
+func validateCollection(collection string) error {
+	if !collectionNamePattern.MatchString(collection) {
+		return fmt.Errorf("invalid collection name %q, expected lowercase letters, digits and underscores", collection)
+	}
+	return nil
+}
+
+// NewVectorStore creates the vector store of a backend. The pgvector backend connects to the provided PostgreSQL
+// database, and requests to OpenSearch Serverless are signed with the AWS credentials.
+func NewVectorStore(backend config.VectorStoreType, cfg config.VectorStoreConfig, postgres config.PostgresConfig, awsConfig aws.Config) (VectorStore, error) {
+	switch backend {
+	case config.VectorStorePgvector:
+		return OpenPgvectorVectorStore(postgres)
+	case config.VectorStoreOpenSearch:
+		if cfg.OpenSearchEndpoint == "" {
+			return nil, fmt.Errorf("the opensearch vector store requires an endpoint")
+		}
+		return NewOpenSearchVectorStore(cfg.OpenSearchEndpoint, cfg.OpenSearchRegion, awsConfig.Credentials), nil
+	case config.VectorStoreQdrant:
+		return NewQdrantVectorStore(cfg.QdrantURL, cfg.QdrantAPIKey), nil
+	default:
+		return nil, fmt.Errorf("unsupported vector store %q", backend)
+	}
+}
+
+// MigrateVectors copies the records of a collection from one vector store to another, batchSize records at a time,
+// and returns how many it copied. Records already copied are replaced, so an interrupted migration can be rerun. The
+// records are left in the source store.
+func MigrateVectors(ctx context.Context, from, to VectorStore, collection string, batchSize int) (int, error) {
+	copied := 0
+	cursor := ""
+	for {
+		records, next, err := from.Scan(ctx, collection, cursor, batchSize)
+		if err != nil {
+			return copied, fmt.Errorf("failed to read vectors after %d: %w", copied, err)
+		}
+		if len(records) > 0 {
+			if err := to.Upsert(ctx, collection, records); err != nil {
+				return copied, fmt.Errorf("failed to write vectors after %d: %w", copied, err)
+			}
+			copied += len(records)
+		}
+		if next == "" {
+			return copied, nil
+		}
+		cursor = next
+	}
+}
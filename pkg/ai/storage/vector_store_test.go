@@ -0,0 +1,584 @@
+package storage_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/storage"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// fakeVectorPoint is a vector kept by a fake backend, with the fields of its record
+type fakeVectorPoint struct {
+	ID       string
+	Vector   []float32
+	Fields   map[string]any
+	Ordering string // Key the backend orders the vectors of a scan by
+}
+
+// fakeVectorCollections holds the collections of a fake vector backend
+type fakeVectorCollections struct {
+	mu          sync.Mutex
+	collections map[string]map[string]fakeVectorPoint
+}
+
+func newFakeVectorCollections() *fakeVectorCollections {
+	return &fakeVectorCollections{collections: make(map[string]map[string]fakeVectorPoint)}
+}
+
+func (c *fakeVectorCollections) exists(collection string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.collections[collection]
+	return ok
+}
+
+func (c *fakeVectorCollections) create(collection string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.collections[collection] = make(map[string]fakeVectorPoint)
+}
+
+func (c *fakeVectorCollections) put(collection string, point fakeVectorPoint) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.collections[collection][point.ID] = point
+}
+
+func (c *fakeVectorCollections) delete(collection, id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.collections[collection], id)
+}
+
+// matching returns the points of a collection whose field has one of values, all of them when field is empty,
+// ordered for scans
+func (c *fakeVectorCollections) matching(collection, field string, values ...string) []fakeVectorPoint {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var points []fakeVectorPoint
+	for _, point := range c.collections[collection] {
+		if field == "" || contains(values, fmt.Sprint(point.Fields[field])) {
+			points = append(points, point)
+		}
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].Ordering < points[j].Ordering })
+	return points
+}
+
+// nearest returns up to limit points of a collection, most similar to vector first, with their cosine similarity
+func (c *fakeVectorCollections) nearest(collection string, vector []float32, limit int) ([]fakeVectorPoint, []float64) {
+	points := c.matching(collection, "")
+	sort.SliceStable(points, func(i, j int) bool { return cosine(points[i].Vector, vector) > cosine(points[j].Vector, vector) })
+	points = points[:min(limit, len(points))]
+	scores := make([]float64, len(points))
+	for i, point := range points {
+		scores[i] = cosine(point.Vector, vector)
+	}
+	return points, scores
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+func cosine(a, b []float32) float64 {
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// newQdrantVectorStore returns a Qdrant vector store of a fake REST API
+func newQdrantVectorStore(t *testing.T) storage.VectorStore {
+	collections := newFakeVectorCollections()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /collections/{name}", func(w http.ResponseWriter, r *http.Request) {
+		if !collections.exists(r.PathValue("name")) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"result": map[string]any{}})
+	})
+	mux.HandleFunc("PUT /collections/{name}", func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Vectors struct {
+				Size     int    `json:"size"`
+				Distance string `json:"distance"`
+			} `json:"vectors"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		assert.Equal(t, 3, request.Vectors.Size)
+		assert.Equal(t, "Cosine", request.Vectors.Distance)
+		collections.create(r.PathValue("name"))
+		_ = json.NewEncoder(w).Encode(map[string]any{"result": true})
+	})
+	mux.HandleFunc("PUT /collections/{name}/index", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"result": map[string]any{}})
+	})
+	mux.HandleFunc("PUT /collections/{name}/points", func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Points []struct {
+				ID      string         `json:"id"`
+				Vector  []float32      `json:"vector"`
+				Payload map[string]any `json:"payload"`
+			} `json:"points"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		for _, point := range request.Points {
+			collections.put(r.PathValue("name"), fakeVectorPoint{ID: point.ID, Vector: point.Vector, Fields: point.Payload, Ordering: point.ID})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"result": map[string]any{"status": "completed"}})
+	})
+	mux.HandleFunc("POST /collections/{name}/points/search", func(w http.ResponseWriter, r *http.Request) {
+		if !collections.exists(r.PathValue("name")) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var request struct {
+			Vector []float32 `json:"vector"`
+			Limit  int       `json:"limit"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		points, scores := collections.nearest(r.PathValue("name"), request.Vector, request.Limit)
+		result := make([]map[string]any, len(points))
+		for i, point := range points {
+			result[i] = map[string]any{"id": point.ID, "score": scores[i], "payload": point.Fields}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"result": result})
+	})
+	mux.HandleFunc("POST /collections/{name}/points/delete", func(w http.ResponseWriter, r *http.Request) {
+		if !collections.exists(r.PathValue("name")) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var request struct {
+			Filter struct {
+				Must []struct {
+					Key   string `json:"key"`
+					Match struct {
+						Value string `json:"value"`
+					} `json:"match"`
+				} `json:"must"`
+			} `json:"filter"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		require.Len(t, request.Filter.Must, 1)
+		condition := request.Filter.Must[0]
+		for _, point := range collections.matching(r.PathValue("name"), condition.Key, condition.Match.Value) {
+			collections.delete(r.PathValue("name"), point.ID)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"result": map[string]any{"status": "completed"}})
+	})
+	mux.HandleFunc("POST /collections/{name}/points/scroll", func(w http.ResponseWriter, r *http.Request) {
+		if !collections.exists(r.PathValue("name")) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var request struct {
+			Limit  int    `json:"limit"`
+			Offset string `json:"offset"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		var page []map[string]any
+		var next any
+		for _, point := range collections.matching(r.PathValue("name"), "") {
+			if point.ID < request.Offset {
+				continue
+			}
+			if len(page) == request.Limit {
+				next = point.ID
+				break
+			}
+			page = append(page, map[string]any{"id": point.ID, "vector": point.Vector, "payload": point.Fields})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"result": map[string]any{"points": page, "next_page_offset": next}})
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("api-key") != "qdrant-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	return storage.NewQdrantVectorStore(server.URL, "qdrant-key")
+}
+
+// newOpenSearchVectorStore returns an OpenSearch vector store of a fake OpenSearch Serverless collection, which
+// generates the IDs of its documents
+func newOpenSearchVectorStore(t *testing.T) storage.VectorStore {
+	collections := newFakeVectorCollections()
+	documents := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("HEAD /{index}", func(w http.ResponseWriter, r *http.Request) {
+		if !collections.exists(r.PathValue("index")) {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	mux.HandleFunc("PUT /{index}", func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Mappings struct {
+				Properties struct {
+					Embedding struct {
+						Type      string `json:"type"`
+						Dimension int    `json:"dimension"`
+					} `json:"embedding"`
+				} `json:"properties"`
+			} `json:"mappings"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		assert.Equal(t, "knn_vector", request.Mappings.Properties.Embedding.Type)
+		assert.Equal(t, 3, request.Mappings.Properties.Embedding.Dimension)
+		collections.create(r.PathValue("index"))
+		_ = json.NewEncoder(w).Encode(map[string]any{"acknowledged": true})
+	})
+	mux.HandleFunc("POST /{index}/_search", func(w http.ResponseWriter, r *http.Request) {
+		index := r.PathValue("index")
+		if !collections.exists(index) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var request struct {
+			Size  int `json:"size"`
+			Query struct {
+				KNN *struct {
+					Embedding struct {
+						Vector []float32 `json:"vector"`
+						K      int       `json:"k"`
+					} `json:"embedding"`
+				} `json:"knn"`
+				Terms *struct {
+					VectorID []string `json:"vector_id"`
+				} `json:"terms"`
+				Term *struct {
+					Source string `json:"source"`
+				} `json:"term"`
+			} `json:"query"`
+			SearchAfter []string `json:"search_after"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+
+		var hits []map[string]any
+		if knn := request.Query.KNN; knn != nil {
+			points, scores := collections.nearest(index, knn.Embedding.Vector, knn.Embedding.K)
+			for i, point := range points {
+				fields := map[string]any{}
+				for key, value := range point.Fields {
+					if key != "embedding" {
+						fields[key] = value
+					}
+				}
+				hits = append(hits, map[string]any{"_id": point.ID, "_score": scores[i], "_source": fields})
+			}
+		} else {
+			var points []fakeVectorPoint
+			switch {
+			case request.Query.Terms != nil:
+				points = collections.matching(index, "vector_id", request.Query.Terms.VectorID...)
+			case request.Query.Term != nil:
+				points = collections.matching(index, "source", request.Query.Term.Source)
+			default:
+				points = collections.matching(index, "")
+			}
+			for _, point := range points {
+				if len(request.SearchAfter) == 1 && point.Ordering <= request.SearchAfter[0] {
+					continue
+				}
+				if len(hits) == request.Size {
+					break
+				}
+				hits = append(hits, map[string]any{"_id": point.ID, "_score": 1, "_source": point.Fields})
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"hits": map[string]any{"hits": hits}})
+	})
+	mux.HandleFunc("POST /_bulk", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/x-ndjson", r.Header.Get("Content-Type"))
+		scanner := bufio.NewScanner(r.Body)
+		scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+		var items []map[string]any
+		for scanner.Scan() {
+			var action map[string]struct {
+				Index string `json:"_index"`
+				ID    string `json:"_id"`
+			}
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &action))
+			if target, ok := action["delete"]; ok {
+				assert.NotEmpty(t, target.ID)
+				collections.delete(target.Index, target.ID)
+				items = append(items, map[string]any{"delete": map[string]any{"status": http.StatusOK}})
+				continue
+			}
+			target, ok := action["index"]
+			require.True(t, ok)
+			assert.Empty(t, target.ID, "vector search collections don't accept document IDs")
+			require.True(t, scanner.Scan())
+			var fields map[string]any
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &fields))
+			var document struct {
+				Embedding []float32 `json:"embedding"`
+			}
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &document))
+			documents++
+			collections.put(target.Index, fakeVectorPoint{
+				ID:       fmt.Sprintf("doc-%d", documents),
+				Vector:   document.Embedding,
+				Fields:   fields,
+				Ordering: fmt.Sprint(fields["vector_id"]),
+			})
+			items = append(items, map[string]any{"index": map[string]any{"status": http.StatusCreated}})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"errors": false, "items": items})
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization := r.Header.Get("Authorization")
+		if !strings.Contains(authorization, "Credential=AKID/") || !strings.Contains(authorization, "/us-west-2/aoss/aws4_request") ||
+			r.Header.Get("X-Amz-Content-Sha256") == "" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	credentials := aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+		return aws.Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"}, nil
+	})
+	return storage.NewOpenSearchVectorStore(server.URL, "us-west-2", credentials)
+}
+
+// vectorIDs returns the IDs of records
+func vectorIDs(records []storage.VectorRecord) []string {
+	ids := make([]string, len(records))
+	for i, record := range records {
+		ids[i] = record.ID
+	}
+	return ids
+}
+
+// scanAll reads all the records of a collection, two at a time
+func scanAll(t *testing.T, store storage.VectorStore, collection string) []storage.VectorRecord {
+	var records []storage.VectorRecord
+	cursor := ""
+	for {
+		page, next, err := store.Scan(context.Background(), collection, cursor, 2)
+		require.NoError(t, err)
+		records = append(records, page...)
+		if next == "" {
+			return records
+		}
+		cursor = next
+	}
+}
+
+func TestVectorStore_Parity(t *testing.T) {
+	implementations := map[string]func(t *testing.T) storage.VectorStore{
+		"opensearch": newOpenSearchVectorStore,
+		"qdrant":     newQdrantVectorStore,
+	}
+
+	for name, newVectorStore := range implementations {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newVectorStore(t)
+
+			require.NoError(t, store.Upsert(ctx, "kb_payments", []storage.VectorRecord{
+				{ID: "charge-1", Source: "pkg/charge.go", Text: "func Charge()", Embedding: []float32{1, 0, 0}, Metadata: map[string]string{"language": "go"}},
+				{ID: "charge-2", Source: "pkg/charge.go", Text: "func Capture()", Embedding: []float32{0.8, 0.2, 0}},
+				{ID: "refund-1", Source: "pkg/refund.go", Text: "func Refund()", Embedding: []float32{0, 1, 0}},
+			}))
+
+			matches, err := store.Query(ctx, "kb_payments", []float32{1, 0.1, 0}, 2)
+			require.NoError(t, err)
+			require.Len(t, matches, 2)
+			assert.Equal(t, "charge-1", matches[0].ID)
+			assert.Equal(t, "pkg/charge.go", matches[0].Source)
+			assert.Equal(t, "func Charge()", matches[0].Text)
+			assert.Equal(t, map[string]string{"language": "go"}, matches[0].Metadata)
+			assert.Empty(t, matches[0].Embedding)
+			assert.Equal(t, "charge-2", matches[1].ID)
+			assert.Greater(t, matches[0].Score, matches[1].Score)
+
+			// Upserting a record again replaces it
+			require.NoError(t, store.Upsert(ctx, "kb_payments", []storage.VectorRecord{
+				{ID: "charge-1", Source: "pkg/charge.go", Text: "func Charge(amount int)", Embedding: []float32{1, 0, 0}},
+			}))
+			records := scanAll(t, store, "kb_payments")
+			assert.ElementsMatch(t, []string{"charge-1", "charge-2", "refund-1"}, vectorIDs(records))
+			for _, record := range records {
+				if record.ID == "charge-1" {
+					assert.Equal(t, "func Charge(amount int)", record.Text)
+					assert.Equal(t, []float32{1, 0, 0}, record.Embedding)
+				}
+			}
+
+			require.NoError(t, store.DeleteBySource(ctx, "kb_payments", "pkg/charge.go"))
+			records = scanAll(t, store, "kb_payments")
+			assert.Equal(t, []string{"refund-1"}, vectorIDs(records))
+
+			// Collections that don't exist hold nothing
+			matches, err = store.Query(ctx, "kb_missing", []float32{1, 0, 0}, 2)
+			require.NoError(t, err)
+			assert.Empty(t, matches)
+			assert.NoError(t, store.DeleteBySource(ctx, "kb_missing", "pkg/charge.go"))
+			assert.Empty(t, scanAll(t, store, "kb_missing"))
+
+			assert.Error(t, store.Upsert(ctx, "KB-Payments", []storage.VectorRecord{{ID: "a", Embedding: []float32{1, 0, 0}}}))
+		})
+	}
+}
+
+func TestMigrateVectors(t *testing.T) {
+	ctx := context.Background()
+	from := newQdrantVectorStore(t)
+	to := newOpenSearchVectorStore(t)
+
+	var records []storage.VectorRecord
+	for i := range 5 {
+		records = append(records, storage.VectorRecord{
+			ID:        fmt.Sprintf("chunk-%d", i),
+			Source:    fmt.Sprintf("file-%d.go", i%2),
+			Text:      fmt.Sprintf("chunk %d", i),
+			Embedding: []float32{float32(i), 1, 0},
+		})
+	}
+	require.NoError(t, from.Upsert(ctx, "kb_payments", records))
+
+	copied, err := storage.MigrateVectors(ctx, from, to, "kb_payments", 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, 5, copied)
+	migrated := scanAll(t, to, "kb_payments")
+	assert.ElementsMatch(t, records, migrated)
+
+	// Rerunning the migration replaces the records it copied
+	copied, err = storage.MigrateVectors(ctx, from, to, "kb_payments", 2)
+	require.NoError(t, err)
+	assert.Equal(t, 5, copied)
+	assert.Len(t, scanAll(t, to, "kb_payments"), 5)
+}
+
+func TestPgvectorVectorStore_Upsert(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	store := storage.NewPgvectorVectorStore(db)
+
+	mock.ExpectExec(`CREATE EXTENSION IF NOT EXISTS vector`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`CREATE TABLE IF NOT EXISTS kb_payments \((.+)embedding vector\(3\)`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`CREATE INDEX IF NOT EXISTS idx_kb_payments_source ON kb_payments \(source\)`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO kb_payments (.+) ON CONFLICT \(id\) DO UPDATE`).
+		WithArgs("charge-1", "pkg/charge.go", "func Charge()", "[1,0.5,0]", []byte(`{"language":"go"}`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = store.Upsert(context.Background(), "kb_payments", []storage.VectorRecord{
+		{ID: "charge-1", Source: "pkg/charge.go", Text: "func Charge()", Embedding: []float32{1, 0.5, 0}, Metadata: map[string]string{"language": "go"}},
+	})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPgvectorVectorStore_QueryAndScan(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	store := storage.NewPgvectorVectorStore(db)
+
+	mock.ExpectQuery(`SELECT id, source, text, metadata, 1 - \(embedding <=> \$1::vector\) AS score FROM kb_payments ORDER BY embedding <=> \$1::vector LIMIT \$2`).
+		WithArgs("[1,0,0]", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "source", "text", "metadata", "score"}).
+			AddRow("charge-1", "pkg/charge.go", "func Charge()", []byte(`{"language":"go"}`), 0.98))
+	mock.ExpectQuery(`SELECT id, source, text, embedding::text, metadata FROM kb_payments WHERE id > \$1 ORDER BY id LIMIT \$2`).
+		WithArgs("", 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "source", "text", "embedding", "metadata"}).
+			AddRow("charge-1", "pkg/charge.go", "func Charge()", "[1,0.5,0]", []byte(`{}`)).
+			AddRow("charge-2", "pkg/charge.go", "func Capture()", "[0.8,0.2,0]", []byte(`{}`)))
+
+	matches, err := store.Query(context.Background(), "kb_payments", []float32{1, 0, 0}, 1)
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "charge-1", matches[0].ID)
+	assert.Equal(t, map[string]string{"language": "go"}, matches[0].Metadata)
+	assert.InDelta(t, 0.98, matches[0].Score, 1e-9)
+
+	records, next, err := store.Scan(context.Background(), "kb_payments", "", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"charge-1", "charge-2"}, vectorIDs(records))
+	assert.Equal(t, []float32{1, 0.5, 0}, records[0].Embedding)
+	assert.Equal(t, "charge-2", next)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPgvectorVectorStore_MissingCollection(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // Test cleanup
+
+	store := storage.NewPgvectorVectorStore(db)
+	undefinedTable := &pq.Error{Code: "42P01", Message: `relation "kb_missing" does not exist`}
+
+	mock.ExpectQuery(`SELECT (.+) FROM kb_missing`).WillReturnError(undefinedTable)
+	mock.ExpectExec(`DELETE FROM kb_missing WHERE source = \$1`).WithArgs("pkg/charge.go").WillReturnError(undefinedTable)
+	mock.ExpectQuery(`SELECT (.+) FROM kb_missing WHERE id > \$1`).WillReturnError(undefinedTable)
+
+	matches, err := store.Query(context.Background(), "kb_missing", []float32{1, 0, 0}, 1)
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+	require.NoError(t, store.DeleteBySource(context.Background(), "kb_missing", "pkg/charge.go"))
+	records, next, err := store.Scan(context.Background(), "kb_missing", "", 2)
+	require.NoError(t, err)
+	assert.Empty(t, records)
+	assert.Empty(t, next)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNewVectorStore(t *testing.T) {
+	cfg := config.VectorStoreConfig{QdrantURL: "http://localhost:6333", OpenSearchRegion: "us-east-1"}
+
+	store, err := storage.NewVectorStore(config.VectorStoreQdrant, cfg, config.PostgresConfig{}, aws.Config{})
+	require.NoError(t, err)
+	assert.IsType(t, &storage.QdrantVectorStore{}, store)
+
+	_, err = storage.NewVectorStore(config.VectorStoreOpenSearch, cfg, config.PostgresConfig{}, aws.Config{})
+	assert.ErrorContains(t, err, "requires an endpoint")
+
+	cfg.OpenSearchEndpoint = "https://abc123.us-east-1.aoss.amazonaws.com"
+	store, err = storage.NewVectorStore(config.VectorStoreOpenSearch, cfg, config.PostgresConfig{}, aws.Config{})
+	require.NoError(t, err)
+	assert.IsType(t, &storage.OpenSearchVectorStore{}, store)
+
+	_, err = storage.NewVectorStore("pinecone", cfg, config.PostgresConfig{}, aws.Config{})
+	assert.Error(t, err)
+}
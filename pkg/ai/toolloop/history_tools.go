@@ -0,0 +1,33 @@
+package toolloop
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/agent"
+)
+
+// FileHistoryTool returns the tool describing, with describe, the git history of a file of the repository, or of the
+// files changed the most when no path is given
+func FileHistoryTool(describe func(ctx context.Context, path string) (string, error)) Tool {
+	return Tool{
+		Definition: agent.ToolDefinition{
+			Name:        "file_history",
+			Description: "Describe how often and how recently a file of the repository changed, by how many lines and by whom. Without a path, list the files changed the most, the hot spots where refactoring pays off first.",
+			Parameters: objectSchema(map[string]any{
+				"path": stringSchema("Path of the file, relative to the repository root. Defaults to the files changed the most"),
+			}),
+		},
+		Run: func(ctx context.Context, arguments map[string]any) (string, error) {
+			path, _ := arguments["path"].(string)
+			if path == "" {
+				return describe(ctx, "")
+			}
+			if filepath.IsAbs(path) || !filepath.IsLocal(path) {
+				return "", fmt.Errorf("path %s must be relative to the repository root", path)
+			}
+			return describe(ctx, filepath.ToSlash(filepath.Clean(path)))
+		},
+	}
+}
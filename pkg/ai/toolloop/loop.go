@@ -0,0 +1,293 @@
+// Package toolloop runs a chat model in a loop of tool calls until it answers or runs out of steps.
+package toolloop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/agent"
+)
+
+// ErrStepBudgetExhausted is returned when the model keeps calling tools past the step budget
+var ErrStepBudgetExhausted = errors.New("step budget exhausted before the model answered")
+
+// ErrInvalidAnswer is returned when the model's answers still fail validation after the allowed repairs
+var ErrInvalidAnswer = errors.New("model answer failed validation")
+
+const (
+	// maxToolResultLength bounds the tool output handed back to the model
+	maxToolResultLength = 8000
+
+	// maxTraceLength bounds the texts kept in the trace, which is stored with the task
+	maxTraceLength = 2000
+)
+
+// Tool is a function the model may call
+type Tool struct {
+	Definition agent.ToolDefinition
+
+	// Run invokes the tool. Its error is handed back to the model, which may try again.
+	Run func(ctx context.Context, arguments map[string]any) (string, error)
+
+	// Documents lists the files a successful call put in the chat, optional
+	Documents func(arguments map[string]any, output string) []string
+}
+
+// StepKind tells model calls and tool calls apart in a trace
+type StepKind string
+
+const (
+	// StepKindModel is a call of the model
+	StepKindModel StepKind = "model"
+
+	// StepKindTool is a call of a tool the model asked for
+	StepKindTool StepKind = "tool"
+
+	// StepKindValidation is a check of the model's answer, which the model is asked to repair when it fails
+	StepKindValidation StepKind = "validation"
+)
+
+// StopReason tells why a loop stopped
+type StopReason string
+
+const (
+	// StopReasonAnswered means the model answered without calling tools
+	StopReasonAnswered StopReason = "answered"
+
+	// StopReasonStepBudget means the model used up the step budget
+	StopReasonStepBudget StopReason = "step_budget_exhausted"
+
+	// StopReasonInvalidAnswer means the model's answers failed validation after the allowed repairs
+	StopReasonInvalidAnswer StopReason = "invalid_answer"
+
+	// StopReasonError means a model call failed or the loop was cancelled
+	StopReasonError StopReason = "error"
+)
+
+// AnswerValidator checks the model's answer and returns its parsed form. The error is shown to the model, which is
+// asked to answer again.
+type AnswerValidator func(answer string) (any, error)
+
+// Step is an entry of the trace of a loop
+type Step struct {
+	Step       int            `json:"step"`
+	Kind       StepKind       `json:"kind"`
+	Tool       string         `json:"tool,omitempty"`
+	Arguments  map[string]any `json:"arguments,omitempty"`
+	Content    string         `json:"content,omitempty"`
+	Error      string         `json:"error,omitempty"`
+	StartedAt  time.Time      `json:"started_at"`
+	DurationMS int64          `json:"duration_ms"`
+}
+
+// Result is the outcome of a loop with the trace of its steps. Answer is the model's last answer as written, and Parsed
+// its form returned by the validator.
+type Result struct {
+	Answer     string     `json:"answer"`
+	Parsed     any        `json:"parsed,omitempty"`
+	Repairs    int        `json:"repairs"`
+	StopReason StopReason `json:"stop_reason"`
+	ModelCalls int        `json:"model_calls"`
+	Trace      []Step     `json:"trace"`
+}
+
+// Interaction is a call of the model as reported to a Recorder
+type Interaction struct {
+	ModelCall int
+
+	// Prompt holds the messages added to the chat since the previous call, the whole chat on the first call
+	Prompt   []agent.ChatMessage
+	Response agent.ChatMessage
+	Error    string
+
+	// ContextDocs lists the files the tools put in the chat since the previous call
+	ContextDocs []string
+
+	StartedAt time.Time
+	Latency   time.Duration
+}
+
+// Recorder is told about every call of the model, such as to log it
+type Recorder func(ctx context.Context, interaction Interaction)
+
+// Loop alternates between calls of a model and of the tools it asks for
+type Loop struct {
+	model    agent.ChatModel
+	tools    map[string]Tool
+	defs     []agent.ToolDefinition
+	maxSteps int
+
+	validate   AnswerValidator
+	maxRepairs int
+
+	record Recorder
+}
+
+// NewLoop creates a loop calling the model at most maxSteps times
+func NewLoop(model agent.ChatModel, tools []Tool, maxSteps int) *Loop {
+	loop := &Loop{model: model, tools: make(map[string]Tool, len(tools)), maxSteps: maxSteps}
+	for _, tool := range tools {
+		loop.tools[tool.Definition.Name] = tool
+		loop.defs = append(loop.defs, tool.Definition)
+	}
+	return loop
+}
+
+// WithValidator checks the model's answers, asking the model to repair an invalid answer at most maxRepairs times.
+// Repairs count towards the step budget.
+func (l *Loop) WithValidator(validate AnswerValidator, maxRepairs int) *Loop {
+	l.validate = validate
+	l.maxRepairs = maxRepairs
+	return l
+}
+
+// WithRecorder reports every call of the model to record
+func (l *Loop) WithRecorder(record Recorder) *Loop {
+	l.record = record
+	return l
+}
+
+// Run chats with the model until it answers without calling tools. The result, with the trace so far, is returned
+// also when the loop fails.
+func (l *Loop) Run(ctx context.Context, system, prompt string) (*Result, error) {
+	result := &Result{}
+	messages := []agent.ChatMessage{
+		{Role: agent.ChatRoleSystem, Content: system},
+		{Role: agent.ChatRoleUser, Content: prompt},
+	}
+
+	// Messages up to recorded were reported with an earlier call, as were the documents in docs before it
+	recorded := 0
+	var docs []string
+	for result.ModelCalls < l.maxSteps {
+		result.ModelCalls++
+		startedAt := time.Now()
+		reply, err := l.model.Chat(ctx, messages, l.defs)
+		latency := time.Since(startedAt)
+		step := Step{Kind: StepKindModel, Content: truncate(reply.Content, maxTraceLength), StartedAt: startedAt, DurationMS: latency.Milliseconds()}
+		if l.record != nil {
+			interaction := Interaction{
+				ModelCall:   result.ModelCalls,
+				Prompt:      messages[recorded:],
+				Response:    reply,
+				ContextDocs: docs,
+				StartedAt:   startedAt,
+				Latency:     latency,
+			}
+			if err != nil {
+				interaction.Error = err.Error()
+			}
+			l.record(ctx, interaction)
+			recorded = len(messages)
+			docs = nil
+		}
+		if err != nil {
+			step.Error = err.Error()
+			result.record(step)
+			result.StopReason = StopReasonError
+			return result, fmt.Errorf("model call %d failed: %w", result.ModelCalls, err)
+		}
+		result.record(step)
+
+		reply.Role = agent.ChatRoleAssistant
+		messages = append(messages, reply)
+		if len(reply.ToolCalls) == 0 {
+			result.Answer = reply.Content
+			if l.validate == nil {
+				result.StopReason = StopReasonAnswered
+				return result, nil
+			}
+
+			parsed, err := l.validate(reply.Content)
+			if err == nil {
+				result.Parsed = parsed
+				result.StopReason = StopReasonAnswered
+				return result, nil
+			}
+			result.record(Step{Kind: StepKindValidation, Error: err.Error(), StartedAt: time.Now()})
+			if result.Repairs == l.maxRepairs {
+				result.StopReason = StopReasonInvalidAnswer
+				return result, fmt.Errorf("%w: %v", ErrInvalidAnswer, err)
+			}
+			result.Repairs++
+			messages = append(messages, agent.ChatMessage{Role: agent.ChatRoleUser, Content: repairPrompt(err)})
+			continue
+		}
+
+		for _, call := range reply.ToolCalls {
+			content, callDocs := l.call(ctx, call, result)
+			docs = appendNew(docs, callDocs...)
+			messages = append(messages, agent.ChatMessage{Role: agent.ChatRoleTool, ToolName: call.Name, Content: content})
+		}
+		if err := ctx.Err(); err != nil {
+			result.StopReason = StopReasonError
+			return result, err
+		}
+	}
+
+	result.StopReason = StopReasonStepBudget
+	return result, ErrStepBudgetExhausted
+}
+
+// call invokes the tool the model asked for and returns the message handed back to the model, with the documents the
+// tool put in it
+func (l *Loop) call(ctx context.Context, call agent.ToolCall, result *Result) (string, []string) {
+	startedAt := time.Now()
+	step := Step{Kind: StepKindTool, Tool: call.Name, Arguments: call.Arguments, StartedAt: startedAt}
+
+	tool, ok := l.tools[call.Name]
+	var output string
+	var err error
+	if ok {
+		output, err = tool.Run(ctx, call.Arguments)
+	} else {
+		err = fmt.Errorf("unknown tool %q", call.Name)
+	}
+	step.DurationMS = time.Since(startedAt).Milliseconds()
+
+	if err != nil {
+		step.Error = err.Error()
+		result.record(step)
+		return "error: " + err.Error(), nil
+	}
+	step.Content = truncate(output, maxTraceLength)
+	result.record(step)
+
+	var docs []string
+	if tool.Documents != nil {
+		docs = tool.Documents(call.Arguments, output)
+	}
+	return truncate(output, maxToolResultLength), docs
+}
+
+// appendNew appends the values missing from list
+func appendNew(list []string, values ...string) []string {
+	for _, value := range values {
+		if !slices.Contains(list, value) {
+			list = append(list, value)
+		}
+	}
+	return list
+}
+
+// repairPrompt asks the model to fix an answer that failed validation
+func repairPrompt(err error) string {
+	return fmt.Sprintf("Your answer is invalid: %v\nAnswer again with only the corrected answer, in the required format.", err)
+}
+
+// record appends a step to the trace
+func (r *Result) record(step Step) {
+	step.Step = len(r.Trace) + 1
+	r.Trace = append(r.Trace, step)
+}
+
+// truncate shortens text to at most limit bytes, noting how much was cut
+func truncate(text string, limit int) string {
+	if len(text) <= limit {
+		return text
+	}
+	return fmt.Sprintf("%s\n... (%d more bytes)", text[:limit], len(text)-limit)
+}
@@ -0,0 +1,204 @@
+package toolloop_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/agent"
+	agentMocks "github.com/kazemisoroush/code-refactoring-tool/pkg/ai/agent/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/toolloop"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/patcher"
+)
+
+func TestLoop_Run_CallsToolsUntilTheModelAnswers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {\n\tprintln(\"hi\")\n}\n"), 0o644))
+	model := agentMocks.NewMockChatModel(ctrl)
+
+	gomock.InOrder(
+		model.EXPECT().Chat(gomock.Any(), gomock.Len(2), gomock.Len(4)).Return(agent.ChatMessage{
+			Role:      agent.ChatRoleAssistant,
+			ToolCalls: []agent.ToolCall{{Name: "grep", Arguments: map[string]any{"pattern": "println"}}},
+		}, nil),
+		model.EXPECT().Chat(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, messages []agent.ChatMessage, _ []agent.ToolDefinition) (agent.ChatMessage, error) {
+				// The tool's result is handed back to the model
+				last := messages[len(messages)-1]
+				assert.Equal(t, agent.ChatRoleTool, last.Role)
+				assert.Equal(t, "grep", last.ToolName)
+				assert.Equal(t, "main.go:4: \tprintln(\"hi\")", last.Content)
+				return agent.ChatMessage{
+					Role: agent.ChatRoleAssistant,
+					ToolCalls: []agent.ToolCall{{Name: "apply_patch", Arguments: map[string]any{
+						"path": "main.go", "start_line": float64(4), "end_line": float64(4), "replacement": "\tprintln(\"hello\")",
+					}}},
+				}, nil
+			}),
+		model.EXPECT().Chat(gomock.Any(), gomock.Any(), gomock.Any()).Return(agent.ChatMessage{
+			Role: agent.ChatRoleAssistant, Content: "Renamed the greeting.",
+		}, nil),
+	)
+
+	loop := toolloop.NewLoop(model, toolloop.WorkspaceTools(dir, nil, patcher.NewFilePatcher()), 5)
+	result, err := loop.Run(context.Background(), "You edit code.", "Say hello")
+
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed the greeting.", result.Answer)
+	assert.Equal(t, toolloop.StopReasonAnswered, result.StopReason)
+	assert.Equal(t, 3, result.ModelCalls)
+	require.Len(t, result.Trace, 5)
+	assert.Equal(t, toolloop.StepKindTool, result.Trace[1].Kind)
+	assert.Equal(t, "grep", result.Trace[1].Tool)
+	assert.Equal(t, "apply_patch", result.Trace[3].Tool)
+	assert.Equal(t, 5, result.Trace[4].Step)
+
+	content, err := os.ReadFile(filepath.Join(dir, "main.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `println("hello")`)
+}
+
+func TestLoop_Run_HandsToolErrorsBackToTheModel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	model := agentMocks.NewMockChatModel(ctrl)
+	gomock.InOrder(
+		model.EXPECT().Chat(gomock.Any(), gomock.Any(), gomock.Any()).Return(agent.ChatMessage{
+			ToolCalls: []agent.ToolCall{{Name: "read_file", Arguments: map[string]any{"path": "../etc/passwd"}}},
+		}, nil),
+		model.EXPECT().Chat(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, messages []agent.ChatMessage, _ []agent.ToolDefinition) (agent.ChatMessage, error) {
+				assert.Equal(t, "error: path ../etc/passwd must be relative to the repository root", messages[len(messages)-1].Content)
+				return agent.ChatMessage{Content: "I can't read that file."}, nil
+			}),
+	)
+
+	loop := toolloop.NewLoop(model, toolloop.WorkspaceTools(t.TempDir(), nil, patcher.NewFilePatcher()), 5)
+	result, err := loop.Run(context.Background(), "system", "prompt")
+
+	require.NoError(t, err)
+	assert.Contains(t, result.Trace[1].Error, "must be relative")
+}
+
+func TestLoop_Run_RecordsEveryModelCall(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\n// TODO: greet\n"), 0o644))
+	model := agentMocks.NewMockChatModel(ctrl)
+	gomock.InOrder(
+		model.EXPECT().Chat(gomock.Any(), gomock.Any(), gomock.Any()).Return(agent.ChatMessage{
+			ToolCalls: []agent.ToolCall{
+				{Name: "grep", Arguments: map[string]any{"pattern": "TODO"}},
+				{Name: "read_file", Arguments: map[string]any{"path": "./main.go"}},
+			},
+			Usage: &agent.TokenUsage{PromptTokens: 120, ResponseTokens: 15},
+		}, nil),
+		model.EXPECT().Chat(gomock.Any(), gomock.Any(), gomock.Any()).Return(agent.ChatMessage{Content: "Nothing to do."}, nil),
+	)
+
+	var interactions []toolloop.Interaction
+	loop := toolloop.NewLoop(model, toolloop.WorkspaceTools(dir, nil, patcher.NewFilePatcher()), 5).
+		WithRecorder(func(_ context.Context, interaction toolloop.Interaction) {
+			interactions = append(interactions, interaction)
+		})
+	_, err := loop.Run(context.Background(), "system", "prompt")
+
+	require.NoError(t, err)
+	require.Len(t, interactions, 2)
+	assert.Equal(t, 1, interactions[0].ModelCall)
+	assert.Len(t, interactions[0].Prompt, 2, "the first call is prompted with the whole chat")
+	assert.Empty(t, interactions[0].ContextDocs)
+	assert.Equal(t, 120, interactions[0].Response.Usage.PromptTokens)
+
+	// Later calls are prompted with the messages added since, and the documents the tools read
+	assert.Equal(t, 2, interactions[1].ModelCall)
+	require.Len(t, interactions[1].Prompt, 3)
+	assert.Equal(t, agent.ChatRoleAssistant, interactions[1].Prompt[0].Role)
+	assert.Equal(t, "read_file", interactions[1].Prompt[2].ToolName)
+	assert.Equal(t, []string{"main.go"}, interactions[1].ContextDocs, "the documents are listed once")
+	assert.Equal(t, "Nothing to do.", interactions[1].Response.Content)
+}
+
+func TestLoop_Run_StopsAtTheStepBudget(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	model := agentMocks.NewMockChatModel(ctrl)
+	model.EXPECT().Chat(gomock.Any(), gomock.Any(), gomock.Any()).Return(agent.ChatMessage{
+		ToolCalls: []agent.ToolCall{{Name: "grep", Arguments: map[string]any{"pattern": "TODO"}}},
+	}, nil).Times(2)
+
+	loop := toolloop.NewLoop(model, toolloop.WorkspaceTools(t.TempDir(), nil, patcher.NewFilePatcher()), 2)
+	result, err := loop.Run(context.Background(), "system", "prompt")
+
+	require.ErrorIs(t, err, toolloop.ErrStepBudgetExhausted)
+	assert.Equal(t, toolloop.StopReasonStepBudget, result.StopReason)
+	assert.Len(t, result.Trace, 4, "the trace is kept when the loop fails")
+}
+
+func TestLoop_Run_ModelFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	model := agentMocks.NewMockChatModel(ctrl)
+	model.EXPECT().Chat(gomock.Any(), gomock.Any(), gomock.Any()).Return(agent.ChatMessage{}, errors.New("model not found"))
+
+	loop := toolloop.NewLoop(model, nil, 3)
+	result, err := loop.Run(context.Background(), "system", "prompt")
+
+	require.Error(t, err)
+	assert.Equal(t, toolloop.StopReasonError, result.StopReason)
+	require.Len(t, result.Trace, 1)
+	assert.Equal(t, "model not found", result.Trace[0].Error)
+}
+
+func TestWorkspaceTools_ReadFileAndRunTests(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("one\ntwo\nthree\n"), 0o644))
+	tools := map[string]toolloop.Tool{}
+	for _, tool := range toolloop.WorkspaceTools(dir, []string{"sh", "-c", "echo FAIL: TestThing; exit 1"}, patcher.NewFilePatcher()) {
+		tools[tool.Definition.Name] = tool
+	}
+
+	content, err := tools["read_file"].Run(context.Background(), map[string]any{"path": "notes.txt", "start_line": float64(2), "end_line": float64(3)})
+	require.NoError(t, err)
+	assert.Equal(t, "2: two\n3: three\n", content)
+
+	// Failing tests are output for the model, not tool errors
+	output, err := tools["run_tests"].Run(context.Background(), nil)
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(output, "tests failed with exit status 1"))
+	assert.Contains(t, output, "FAIL: TestThing")
+}
+
+func TestFileHistoryTool(t *testing.T) {
+	var described []string
+	tool := toolloop.FileHistoryTool(func(_ context.Context, path string) (string, error) {
+		described = append(described, path)
+		return "history of " + path, nil
+	})
+
+	output, err := tool.Run(context.Background(), map[string]any{"path": "./billing/invoice.go"})
+	require.NoError(t, err)
+	assert.Equal(t, "history of billing/invoice.go", output)
+
+	_, err = tool.Run(context.Background(), map[string]any{})
+	require.NoError(t, err)
+
+	_, err = tool.Run(context.Background(), map[string]any{"path": "../secrets.txt"})
+	assert.Error(t, err, "paths outside of the repository are rejected")
+	assert.Equal(t, []string{"billing/invoice.go", ""}, described)
+}
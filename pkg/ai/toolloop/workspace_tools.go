@@ -0,0 +1,341 @@
+package toolloop
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/agent"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/patcher"
+	plannermodels "github.com/kazemisoroush/code-refactoring-tool/pkg/planner/models"
+)
+
+const (
+	// maxReadLines bounds the lines read_file returns at once
+	maxReadLines = 400
+
+	// maxGrepMatches bounds the matches grep returns
+	maxGrepMatches = 100
+
+	// maxTestOutputLength keeps the end of the test output, where failures are summarised
+	maxTestOutputLength = 6000
+)
+
+// WorkspaceTools returns the tools reading, searching and editing the files of a checkout in dir, and running its
+// tests with testCommand
+func WorkspaceTools(dir string, testCommand []string, filePatcher patcher.Patcher) []Tool {
+	return []Tool{
+		{
+			Definition: agent.ToolDefinition{
+				Name:        "read_file",
+				Description: fmt.Sprintf("Read a file of the repository with numbered lines, at most %d lines at once.", maxReadLines),
+				Parameters: objectSchema(map[string]any{
+					"path":       stringSchema("Path of the file, relative to the repository root"),
+					"start_line": integerSchema("First line to read, from 1"),
+					"end_line":   integerSchema("Last line to read"),
+				}, "path"),
+			},
+			Run: func(_ context.Context, arguments map[string]any) (string, error) {
+				return readFile(dir, arguments)
+			},
+			Documents: func(arguments map[string]any, _ string) []string {
+				path, _ := arguments["path"].(string)
+				return []string{filepath.ToSlash(filepath.Clean(path))}
+			},
+		},
+		{
+			Definition: agent.ToolDefinition{
+				Name:        "grep",
+				Description: fmt.Sprintf("Search the files of the repository for a regular expression, returning at most %d matching lines as path:line: text.", maxGrepMatches),
+				Parameters: objectSchema(map[string]any{
+					"pattern": stringSchema("Regular expression, in Go syntax"),
+					"path":    stringSchema("Directory or file to search, relative to the repository root. Defaults to the whole repository"),
+				}, "pattern"),
+			},
+			Run: func(ctx context.Context, arguments map[string]any) (string, error) {
+				return grep(ctx, dir, arguments)
+			},
+			Documents: func(_ map[string]any, output string) []string {
+				return matchedFiles(output)
+			},
+		},
+		{
+			Definition: agent.ToolDefinition{
+				Name:        "apply_patch",
+				Description: "Replace lines start_line to end_line, inclusive, of a file with the replacement text. Read the file first for its line numbers, which shift after each patch.",
+				Parameters: objectSchema(map[string]any{
+					"path":        stringSchema("Path of the file, relative to the repository root"),
+					"start_line":  integerSchema("First line to replace, from 1"),
+					"end_line":    integerSchema("Last line to replace"),
+					"replacement": stringSchema("Text replacing the lines, without a trailing newline"),
+				}, "path", "start_line", "end_line", "replacement"),
+			},
+			Run: func(_ context.Context, arguments map[string]any) (string, error) {
+				return applyPatch(dir, filePatcher, arguments)
+			},
+		},
+		{
+			Definition: agent.ToolDefinition{
+				Name:        "run_tests",
+				Description: "Run the test suite of the repository and return the end of its output and its exit status.",
+				Parameters:  objectSchema(map[string]any{}),
+			},
+			Run: func(ctx context.Context, _ map[string]any) (string, error) {
+				return runTests(ctx, dir, testCommand)
+			},
+		},
+	}
+}
+
+// readFile returns numbered lines of a file
+func readFile(dir string, arguments map[string]any) (string, error) {
+	path, err := stringArgument(arguments, "path")
+	if err != nil {
+		return "", err
+	}
+	full, err := resolve(dir, path)
+	if err != nil {
+		return "", err
+	}
+	start, err := integerArgument(arguments, "start_line", 1)
+	if err != nil {
+		return "", err
+	}
+	end, err := integerArgument(arguments, "end_line", start+maxReadLines-1)
+	if err != nil {
+		return "", err
+	}
+	if start < 1 || end < start {
+		return "", fmt.Errorf("invalid line range %d-%d", start, end)
+	}
+	end = min(end, start+maxReadLines-1)
+
+	file, err := os.Open(full)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var b strings.Builder
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if line > end {
+			fmt.Fprintf(&b, "... (file continues after line %d)\n", end)
+			break
+		}
+		if line >= start {
+			fmt.Fprintf(&b, "%d: %s\n", line, scanner.Text())
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if line < start {
+		return "", fmt.Errorf("%s has only %d lines", path, line)
+	}
+	return b.String(), nil
+}
+
+// grep returns the lines matching a pattern
+func grep(ctx context.Context, dir string, arguments map[string]any) (string, error) {
+	pattern, err := stringArgument(arguments, "pattern")
+	if err != nil {
+		return "", err
+	}
+	expression, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", fmt.Errorf("invalid pattern: %w", err)
+	}
+	root := dir
+	if path, _ := arguments["path"].(string); path != "" {
+		if root, err = resolve(dir, path); err != nil {
+			return "", err
+		}
+	}
+
+	var matches []string
+	errEnough := errors.New("enough matches")
+	err = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if entry.IsDir() {
+			if entry.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil || isBinary(data) {
+			return nil
+		}
+		relative, _ := filepath.Rel(dir, path)
+		for number, line := range strings.Split(string(data), "\n") {
+			if expression.MatchString(line) {
+				matches = append(matches, fmt.Sprintf("%s:%d: %s", filepath.ToSlash(relative), number+1, line))
+				if len(matches) == maxGrepMatches {
+					return errEnough
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errEnough) {
+		return "", fmt.Errorf("failed to search: %w", err)
+	}
+	if len(matches) == 0 {
+		return "no matches", nil
+	}
+	if errors.Is(err, errEnough) {
+		matches = append(matches, fmt.Sprintf("... (stopped after %d matches)", maxGrepMatches))
+	}
+	return strings.Join(matches, "\n"), nil
+}
+
+// matchedFiles lists the files of the matches returned by grep
+func matchedFiles(output string) []string {
+	var files []string
+	for _, line := range strings.Split(output, "\n") {
+		path, _, found := strings.Cut(line, ":")
+		if found && !strings.HasPrefix(line, "... ") {
+			files = appendNew(files, path)
+		}
+	}
+	return files
+}
+
+// applyPatch replaces a range of lines of a file
+func applyPatch(dir string, filePatcher patcher.Patcher, arguments map[string]any) (string, error) {
+	path, err := stringArgument(arguments, "path")
+	if err != nil {
+		return "", err
+	}
+	if _, err := resolve(dir, path); err != nil {
+		return "", err
+	}
+	start, err := integerArgument(arguments, "start_line", 0)
+	if err != nil {
+		return "", err
+	}
+	end, err := integerArgument(arguments, "end_line", 0)
+	if err != nil {
+		return "", err
+	}
+	replacement, ok := arguments["replacement"].(string)
+	if !ok {
+		return "", errors.New("replacement is required")
+	}
+
+	plan := plannermodels.Plan{Actions: []plannermodels.PlannedAction{{
+		FilePath: filepath.Clean(path),
+		Edits:    []plannermodels.EditRegion{{StartLine: start, EndLine: end, Replacement: strings.Split(replacement, "\n")}},
+	}}}
+	if err := filePatcher.Patch(dir, plan); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("replaced lines %d-%d of %s", start, end, path), nil
+}
+
+// runTests runs the test command, reporting failing tests as output rather than as an error
+func runTests(ctx context.Context, dir string, testCommand []string) (string, error) {
+	if len(testCommand) == 0 {
+		return "", errors.New("no test command is configured")
+	}
+
+	cmd := exec.CommandContext(ctx, testCommand[0], testCommand[1:]...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	status := "tests passed"
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return "", fmt.Errorf("failed to run tests: %w", err)
+		}
+		status = fmt.Sprintf("tests failed with exit status %d", exitErr.ExitCode())
+	}
+
+	text := string(output)
+	if len(text) > maxTestOutputLength {
+		text = "... (output truncated)\n" + text[len(text)-maxTestOutputLength:]
+	}
+	return text + "\n" + status, nil
+}
+
+// resolve returns the path of a file within dir, rejecting paths outside of it
+func resolve(dir, path string) (string, error) {
+	if filepath.IsAbs(path) || !filepath.IsLocal(path) {
+		return "", fmt.Errorf("path %s must be relative to the repository root", path)
+	}
+	return filepath.Join(dir, path), nil
+}
+
+// isBinary reports whether data looks like the content of a binary file
+func isBinary(data []byte) bool {
+	return strings.IndexByte(string(data[:min(len(data), 8000)]), 0) >= 0
+}
+
+// stringArgument returns a required string argument
+func stringArgument(arguments map[string]any, name string) (string, error) {
+	value, _ := arguments[name].(string)
+	if value == "" {
+		return "", fmt.Errorf("%s is required", name)
+	}
+	return value, nil
+}
+
+// integerArgument returns an integer argument, which JSON decodes as a number and some models send as a string
+func integerArgument(arguments map[string]any, name string, fallback int) (int, error) {
+	switch value := arguments[name].(type) {
+	case nil:
+		if fallback == 0 {
+			return 0, fmt.Errorf("%s is required", name)
+		}
+		return fallback, nil
+	case float64:
+		return int(value), nil
+	case int:
+		return value, nil
+	case string:
+		var number int
+		if _, err := fmt.Sscan(value, &number); err != nil {
+			return 0, fmt.Errorf("%s must be an integer", name)
+		}
+		return number, nil
+	default:
+		return 0, fmt.Errorf("%s must be an integer", name)
+	}
+}
+
+// objectSchema returns the JSON schema of an object with properties
+func objectSchema(properties map[string]any, required ...string) map[string]any {
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func stringSchema(description string) map[string]any {
+	return map[string]any{"type": "string", "description": description}
+}
+
+func integerSchema(description string) map[string]any {
+	return map[string]any{"type": "integer", "description": description}
+}
@@ -14,3 +14,11 @@ type Analyzer interface {
 	// ExtractIssues extracts code metrics from the analysis result
 	ExtractIssues(result models.AnalysisResult) ([]models.CodeIssue, error)
 }
+
+// GraphAnalyzer is an Analyzer that also reports the import graph between the packages it analyzed
+type GraphAnalyzer interface {
+	Analyzer
+
+	// ExtractGraph extracts the package graph from the analysis result
+	ExtractGraph(result models.AnalysisResult) (models.PackageGraph, error)
+}
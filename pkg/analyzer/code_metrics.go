@@ -0,0 +1,223 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/analyzer/models"
+	"golang.org/x/mod/modfile"
+)
+
+const (
+	// MaintainableComplexity is the highest cyclomatic complexity of a function counted as maintainable.
+	MaintainableComplexity = 10
+
+	// MaintainableFunctionLength is the longest function, in lines, counted as maintainable.
+	MaintainableFunctionLength = 60
+
+	// metricsHotspots is the number of most complex functions reported.
+	metricsHotspots = 10
+)
+
+// ComputeMetrics measures the cyclomatic complexity and length of every function of the Go code under root, and the
+// coupling between the packages of its module. Test files are left out, and so is coupling when root has no go.mod.
+func ComputeMetrics(root string) (models.CodeMetrics, error) {
+	paths, err := goSourceFiles(root)
+	if err != nil {
+		return models.CodeMetrics{}, fmt.Errorf("failed to list go files: %v", err)
+	}
+
+	metrics := models.CodeMetrics{Packages: []models.PackageCoupling{}, Hotspots: []models.FunctionMetrics{}}
+	var functions []models.FunctionMetrics
+	imports := make(map[string]map[string]bool)
+	fset := token.NewFileSet()
+	for _, rel := range paths {
+		if strings.HasSuffix(rel, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(root, rel), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return models.CodeMetrics{}, fmt.Errorf("failed to parse %s: %v", rel, err)
+		}
+		metrics.Files++
+
+		dir := path.Dir(rel)
+		if imports[dir] == nil {
+			imports[dir] = make(map[string]bool)
+		}
+		for _, spec := range file.Imports {
+			if importPath, err := strconv.Unquote(spec.Path.Value); err == nil {
+				imports[dir][importPath] = true
+			}
+		}
+
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil {
+				continue
+			}
+			functions = append(functions, models.FunctionMetrics{
+				Name:       functionName(fn),
+				FilePath:   rel,
+				Line:       fset.Position(fn.Pos()).Line,
+				Complexity: cyclomaticComplexity(fn.Body),
+				Length:     fset.Position(fn.End()).Line - fset.Position(fn.Pos()).Line + 1,
+			})
+		}
+	}
+
+	summarizeFunctions(&metrics, functions)
+
+	modulePath, err := readModulePath(root)
+	if err != nil {
+		return models.CodeMetrics{}, err
+	}
+	if modulePath != "" {
+		metrics.Packages = packageCoupling(modulePath, imports)
+		if len(metrics.Packages) > 0 {
+			total := 0.0
+			for _, pkg := range metrics.Packages {
+				total += pkg.Instability
+			}
+			metrics.AverageInstability = round2(total / float64(len(metrics.Packages)))
+		}
+	}
+
+	return metrics, nil
+}
+
+// summarizeFunctions fills in the function statistics and the most complex functions.
+func summarizeFunctions(metrics *models.CodeMetrics, functions []models.FunctionMetrics) {
+	metrics.Functions = len(functions)
+	if len(functions) == 0 {
+		metrics.MaintainabilityScore = 100
+		return
+	}
+
+	complexity, length, maintainable := 0, 0, 0
+	for _, fn := range functions {
+		complexity += fn.Complexity
+		length += fn.Length
+		metrics.MaxComplexity = max(metrics.MaxComplexity, fn.Complexity)
+		metrics.MaxFunctionLength = max(metrics.MaxFunctionLength, fn.Length)
+		if fn.Complexity <= MaintainableComplexity && fn.Length <= MaintainableFunctionLength {
+			maintainable++
+		}
+	}
+	metrics.AverageComplexity = round2(float64(complexity) / float64(len(functions)))
+	metrics.AverageFunctionLength = round2(float64(length) / float64(len(functions)))
+	metrics.MaintainabilityScore = round2(100 * float64(maintainable) / float64(len(functions)))
+
+	sorted := append([]models.FunctionMetrics(nil), functions...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Complexity > sorted[j].Complexity })
+	metrics.Hotspots = sorted[:min(metricsHotspots, len(sorted))]
+}
+
+// cyclomaticComplexity counts the independent paths through a function body: one, plus one per branch and short-circuit
+// operator. Function literals count towards the function declaring them.
+func cyclomaticComplexity(body *ast.BlockStmt) int {
+	complexity := 1
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			complexity++
+		case *ast.CaseClause:
+			if n.List != nil {
+				complexity++
+			}
+		case *ast.CommClause:
+			if n.Comm != nil {
+				complexity++
+			}
+		case *ast.BinaryExpr:
+			if n.Op == token.LAND || n.Op == token.LOR {
+				complexity++
+			}
+		}
+		return true
+	})
+	return complexity
+}
+
+// functionName names a function, prefixing methods with their receiver type.
+func functionName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	receiver := fn.Recv.List[0].Type
+	if star, ok := receiver.(*ast.StarExpr); ok {
+		receiver = star.X
+	}
+	switch r := receiver.(type) {
+	case *ast.IndexExpr:
+		receiver = r.X
+	case *ast.IndexListExpr:
+		receiver = r.X
+	}
+	if ident, ok := receiver.(*ast.Ident); ok {
+		return ident.Name + "." + fn.Name.Name
+	}
+	return fn.Name.Name
+}
+
+// readModulePath returns the module path declared by root's go.mod, or "" when there is none.
+func readModulePath(root string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read go.mod: %v", err)
+	}
+	return modfile.ModulePath(data), nil
+}
+
+// packageCoupling counts the imports between the module's own packages, keyed by their directory.
+func packageCoupling(modulePath string, imports map[string]map[string]bool) []models.PackageCoupling {
+	dirs := make(map[string]string, len(imports))
+	for dir := range imports {
+		importPath := modulePath
+		if dir != "." {
+			importPath = modulePath + "/" + dir
+		}
+		dirs[importPath] = dir
+	}
+
+	efferent := make(map[string]int)
+	afferent := make(map[string]int)
+	for dir, imported := range imports {
+		for importPath := range imported {
+			target, internal := dirs[importPath]
+			if !internal || target == dir {
+				continue
+			}
+			efferent[dir]++
+			afferent[target]++
+		}
+	}
+
+	packages := make([]models.PackageCoupling, 0, len(imports))
+	for dir := range imports {
+		coupling := models.PackageCoupling{Path: dir, Afferent: afferent[dir], Efferent: efferent[dir]}
+		if total := coupling.Afferent + coupling.Efferent; total > 0 {
+			coupling.Instability = round2(float64(coupling.Efferent) / float64(total))
+		}
+		packages = append(packages, coupling)
+	}
+	sort.Slice(packages, func(i, j int) bool { return packages[i].Path < packages[j].Path })
+	return packages
+}
+
+// round2 rounds to two decimals so stored snapshots compare cleanly.
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
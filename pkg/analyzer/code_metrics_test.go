@@ -0,0 +1,90 @@
+package analyzer_test
+
+import (
+	"testing"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/analyzer"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/analyzer/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeMetrics(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	writeGoFile(t, dir, "go.mod", "module example.com/shop\n\ngo 1.24\n")
+	writeGoFile(t, dir, "main.go", `package main
+
+import "example.com/shop/billing"
+
+func main() {
+	billing.Charge(1, true)
+}
+`)
+	writeGoFile(t, dir, "billing/billing.go", `package billing
+
+import "example.com/shop/store"
+
+type Invoice struct{}
+
+func (i *Invoice) Total() int { return 0 }
+
+func Charge(amount int, retry bool) error {
+	if amount < 0 || retry && amount == 0 {
+		return nil
+	}
+	for i := 0; i < 3; i++ {
+		switch i {
+		case 0:
+			store.Save()
+		default:
+		}
+	}
+	return nil
+}
+`)
+	writeGoFile(t, dir, "billing/billing_test.go", `package billing
+
+func helper() { if true {} }
+`)
+	writeGoFile(t, dir, "store/store.go", `package store
+
+func Save() {}
+`)
+
+	// Act
+	metrics, err := analyzer.ComputeMetrics(dir)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 3, metrics.Files)
+	assert.Equal(t, 4, metrics.Functions)
+	// Charge: 1 + if + || + && + for + case
+	assert.Equal(t, 6, metrics.MaxComplexity)
+	assert.Equal(t, models.FunctionMetrics{Name: "Charge", FilePath: "billing/billing.go", Line: 9, Complexity: 6, Length: 13}, metrics.Hotspots[0])
+	assert.Equal(t, "Invoice.Total", metrics.Hotspots[1].Name)
+	assert.Equal(t, 100.0, metrics.MaintainabilityScore)
+	assert.Equal(t, []models.PackageCoupling{
+		{Path: ".", Afferent: 0, Efferent: 1, Instability: 1},
+		{Path: "billing", Afferent: 1, Efferent: 1, Instability: 0.5},
+		{Path: "store", Afferent: 1, Efferent: 0, Instability: 0},
+	}, metrics.Packages)
+	assert.Equal(t, 0.5, metrics.AverageInstability)
+}
+
+func TestComputeMetrics_WithoutModule(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	writeGoFile(t, dir, "tool/tool.go", `package tool
+
+func Run() {}
+`)
+
+	// Act
+	metrics, err := analyzer.ComputeMetrics(dir)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 1, metrics.Functions)
+	assert.Empty(t, metrics.Packages)
+}
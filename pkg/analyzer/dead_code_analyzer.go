@@ -0,0 +1,205 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/analyzer/models"
+)
+
+// DeadCodeAnalyzer finds unreachable statements and unexported functions that are never referenced.
+// It works on syntax only, so it errs towards not reporting: any identifier with a function's name counts as a use.
+type DeadCodeAnalyzer struct{}
+
+// NewDeadCodeAnalyzer creates a dead code analyzer.
+func NewDeadCodeAnalyzer() Analyzer {
+	return DeadCodeAnalyzer{}
+}
+
+// parsedPackage holds the parsed files of a package.
+type parsedPackage struct {
+	files map[string]*ast.File
+}
+
+// AnalyzeCode parses every Go file under sourcePath and reports its dead code as JSON issues.
+func (d DeadCodeAnalyzer) AnalyzeCode(sourcePath string) (models.AnalysisResult, error) {
+	paths, err := goSourceFiles(sourcePath)
+	if err != nil {
+		return models.AnalysisResult{}, fmt.Errorf("failed to list go files: %v", err)
+	}
+
+	result := models.AnalysisResult{}
+	fset := token.NewFileSet()
+	packages := make(map[string]*parsedPackage)
+	for _, rel := range paths {
+		file, err := parser.ParseFile(fset, filepath.Join(sourcePath, rel), nil, parser.SkipObjectResolution)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", rel, err))
+			continue
+		}
+
+		key := path.Dir(rel) + ":" + file.Name.Name
+		if packages[key] == nil {
+			packages[key] = &parsedPackage{files: make(map[string]*ast.File)}
+		}
+		packages[key].files[rel] = file
+	}
+
+	keys := make([]string, 0, len(packages))
+	for key := range packages {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	issues := []models.CodeIssue{}
+	for _, key := range keys {
+		pkg := packages[key]
+		for _, rel := range sortedFileNames(pkg.files) {
+			issues = append(issues, unreachableCode(fset, rel, pkg.files[rel])...)
+		}
+		issues = append(issues, unusedFunctions(fset, pkg)...)
+	}
+
+	output, err := json.Marshal(issues)
+	if err != nil {
+		return result, fmt.Errorf("failed to encode dead code: %v", err)
+	}
+	result.RawOutput = string(output)
+
+	return result, nil
+}
+
+// ExtractIssues decodes the dead code reported by AnalyzeCode.
+func (d DeadCodeAnalyzer) ExtractIssues(result models.AnalysisResult) ([]models.CodeIssue, error) {
+	return decodeIssues(result)
+}
+
+// unreachableCode reports the statements following a return, panic, goto, break or continue in the same block.
+func unreachableCode(fset *token.FileSet, rel string, file *ast.File) []models.CodeIssue {
+	var issues []models.CodeIssue
+	check := func(stmts []ast.Stmt) {
+		for i, stmt := range stmts[:max(len(stmts)-1, 0)] {
+			if !isTerminating(stmt) {
+				continue
+			}
+			// A label makes the following statement reachable through goto
+			if _, labeled := stmts[i+1].(*ast.LabeledStmt); labeled {
+				continue
+			}
+
+			start := fset.Position(stmts[i+1].Pos()).Line
+			end := fset.Position(stmts[len(stmts)-1].End()).Line
+			issues = append(issues, models.CodeIssue{
+				Tool:        models.ToolNameDeadCode,
+				Type:        models.IssueTypeDeadCode,
+				RuleID:      "unreachable-code",
+				Message:     fmt.Sprintf("Unreachable code after the statement on line %d", fset.Position(stmt.Pos()).Line),
+				FilePath:    rel,
+				Line:        start,
+				EndLine:     end,
+				Suggestions: []string{"Remove the unreachable statements"},
+			})
+			return
+		}
+	}
+
+	ast.Inspect(file, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.BlockStmt:
+			check(n.List)
+		case *ast.CaseClause:
+			check(n.Body)
+		case *ast.CommClause:
+			check(n.Body)
+		}
+		return true
+	})
+	return issues
+}
+
+// isTerminating reports whether control never continues past the statement.
+func isTerminating(stmt ast.Stmt) bool {
+	switch s := stmt.(type) {
+	case *ast.ReturnStmt:
+		return true
+	case *ast.BranchStmt:
+		return s.Tok != token.FALLTHROUGH
+	case *ast.ExprStmt:
+		call, ok := s.X.(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		ident, ok := call.Fun.(*ast.Ident)
+		return ok && ident.Name == "panic"
+	}
+	return false
+}
+
+// unusedFunctions reports the unexported package-level functions no file of the package refers to.
+func unusedFunctions(fset *token.FileSet, pkg *parsedPackage) []models.CodeIssue {
+	type declaration struct {
+		rel  string
+		decl *ast.FuncDecl
+	}
+
+	declared := make(map[*ast.Ident]bool)
+	var candidates []declaration
+	for _, rel := range sortedFileNames(pkg.files) {
+		for _, decl := range pkg.files[rel].Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil || ast.IsExported(fn.Name.Name) {
+				continue
+			}
+			switch fn.Name.Name {
+			case "main", "init", "_":
+				continue
+			}
+			declared[fn.Name] = true
+			candidates = append(candidates, declaration{rel: rel, decl: fn})
+		}
+	}
+
+	used := make(map[string]bool)
+	for _, file := range pkg.files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			if ident, ok := node.(*ast.Ident); ok && !declared[ident] {
+				used[ident.Name] = true
+			}
+			return true
+		})
+	}
+
+	var issues []models.CodeIssue
+	for _, candidate := range candidates {
+		if used[candidate.decl.Name.Name] {
+			continue
+		}
+		issues = append(issues, models.CodeIssue{
+			Tool:        models.ToolNameDeadCode,
+			Type:        models.IssueTypeDeadCode,
+			RuleID:      "unused-function",
+			Message:     fmt.Sprintf("Function %s is never used", candidate.decl.Name.Name),
+			FilePath:    candidate.rel,
+			Line:        fset.Position(candidate.decl.Pos()).Line,
+			EndLine:     fset.Position(candidate.decl.End()).Line,
+			Suggestions: []string{fmt.Sprintf("Remove %s", candidate.decl.Name.Name)},
+		})
+	}
+	return issues
+}
+
+// sortedFileNames returns the names of the files in lexical order.
+func sortedFileNames(files map[string]*ast.File) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
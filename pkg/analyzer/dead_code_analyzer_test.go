@@ -0,0 +1,56 @@
+package analyzer_test
+
+import (
+	"testing"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/analyzer"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/analyzer/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeadCodeAnalyzer(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	writeGoFile(t, dir, "service/service.go", `package service
+
+func Run(n int) int {
+	if n < 0 {
+		panic("negative")
+		n = 0
+	}
+	return double(n)
+	println("done")
+}
+
+func double(n int) int {
+	return n * 2
+}
+
+func unused() {}
+
+func usedByTest() {}
+`)
+	writeGoFile(t, dir, "service/service_test.go", `package service
+
+func helper() { usedByTest() }
+`)
+
+	a := analyzer.NewDeadCodeAnalyzer()
+
+	// Act
+	result, err := a.AnalyzeCode(dir)
+	require.NoError(t, err)
+	issues, err := a.ExtractIssues(result)
+	require.NoError(t, err)
+
+	// Assert
+	rules := map[string][]int{}
+	for _, issue := range issues {
+		assert.Equal(t, models.IssueTypeDeadCode, issue.Type)
+		rules[issue.RuleID] = append(rules[issue.RuleID], issue.Line)
+	}
+	assert.Equal(t, []int{9, 6}, rules["unreachable-code"])
+	// helper is only declared in a test file and unused too
+	assert.Equal(t, []int{16, 3}, rules["unused-function"])
+}
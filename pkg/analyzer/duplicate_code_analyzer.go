@@ -0,0 +1,324 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"hash/fnv"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/analyzer/models"
+)
+
+// DefaultDuplicateMinTokens is the smallest number of tokens a block needs to be reported as duplicated.
+const DefaultDuplicateMinTokens = 75
+
+// DuplicateCodeAnalyzer finds blocks of Go code that are repeated within and across files.
+// Blocks are compared token by token with identifiers and literals normalized, so copies with renamed variables match.
+type DuplicateCodeAnalyzer struct {
+	minTokens int
+}
+
+// NewDuplicateCodeAnalyzer creates a duplicate code analyzer reporting blocks of at least minTokens tokens.
+func NewDuplicateCodeAnalyzer(minTokens int) Analyzer {
+	if minTokens <= 0 {
+		minTokens = DefaultDuplicateMinTokens
+	}
+	return DuplicateCodeAnalyzer{minTokens: minTokens}
+}
+
+// sourceToken is a normalized token and the line it starts on.
+type sourceToken struct {
+	text string
+	line int
+}
+
+// tokenizedBody holds the normalized tokens of a function body.
+type tokenizedBody struct {
+	path   string
+	tokens []sourceToken
+}
+
+// cloneOccurrence is the start of a duplicated block in a function body.
+type cloneOccurrence struct {
+	body  int
+	start int
+}
+
+// AnalyzeCode tokenizes every Go file under sourcePath and reports the duplicated blocks as JSON issues.
+func (d DuplicateCodeAnalyzer) AnalyzeCode(sourcePath string) (models.AnalysisResult, error) {
+	paths, err := goSourceFiles(sourcePath)
+	if err != nil {
+		return models.AnalysisResult{}, fmt.Errorf("failed to list go files: %v", err)
+	}
+
+	result := models.AnalysisResult{}
+	var bodies []tokenizedBody
+	for _, rel := range paths {
+		fileBodies, err := tokenizeFunctionBodies(filepath.Join(sourcePath, rel))
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", rel, err))
+			continue
+		}
+		for _, tokens := range fileBodies {
+			bodies = append(bodies, tokenizedBody{path: rel, tokens: tokens})
+		}
+	}
+
+	output, err := json.Marshal(d.findDuplicates(bodies))
+	if err != nil {
+		return result, fmt.Errorf("failed to encode duplicates: %v", err)
+	}
+	result.RawOutput = string(output)
+
+	return result, nil
+}
+
+// ExtractIssues decodes the duplicated blocks reported by AnalyzeCode.
+func (d DuplicateCodeAnalyzer) ExtractIssues(result models.AnalysisResult) ([]models.CodeIssue, error) {
+	return decodeIssues(result)
+}
+
+// findDuplicates reports each maximal duplicated block once, with every place it occurs.
+func (d DuplicateCodeAnalyzer) findDuplicates(bodies []tokenizedBody) []models.CodeIssue {
+	// Index every window of minTokens tokens by its hash
+	windows := make(map[uint64][]cloneOccurrence)
+	for b, body := range bodies {
+		for start := 0; start+d.minTokens <= len(body.tokens); start++ {
+			key := hashTokens(body.tokens[start : start+d.minTokens])
+			windows[key] = append(windows[key], cloneOccurrence{body: b, start: start})
+		}
+	}
+
+	// Windows inside an already reported block are not reported again
+	covered := make([][]bool, len(bodies))
+	for b, body := range bodies {
+		covered[b] = make([]bool, len(body.tokens))
+	}
+
+	issues := []models.CodeIssue{}
+	for b, body := range bodies {
+		for start := 0; start+d.minTokens <= len(body.tokens); start++ {
+			if covered[b][start] {
+				continue
+			}
+			key := hashTokens(body.tokens[start : start+d.minTokens])
+			occurrences := d.matchingOccurrences(bodies, covered, cloneOccurrence{body: b, start: start}, windows[key])
+			if len(occurrences) < 2 {
+				continue
+			}
+
+			length := d.extend(bodies, occurrences)
+			for _, occurrence := range occurrences {
+				for i := occurrence.start; i <= occurrence.start+length-d.minTokens; i++ {
+					covered[occurrence.body][i] = true
+				}
+			}
+			issues = append(issues, newDuplicateIssue(bodies, occurrences, length))
+		}
+	}
+
+	return issues
+}
+
+// matchingOccurrences keeps the uncovered candidates whose tokens equal those of first and that don't overlap each other.
+func (d DuplicateCodeAnalyzer) matchingOccurrences(bodies []tokenizedBody, covered [][]bool, first cloneOccurrence, candidates []cloneOccurrence) []cloneOccurrence {
+	want := bodies[first.body].tokens[first.start : first.start+d.minTokens]
+	occurrences := []cloneOccurrence{first}
+	for _, candidate := range candidates {
+		if covered[candidate.body][candidate.start] {
+			continue
+		}
+		last := occurrences[len(occurrences)-1]
+		if candidate.body == last.body && candidate.start < last.start+d.minTokens {
+			continue
+		}
+		if !equalTokens(want, bodies[candidate.body].tokens[candidate.start:candidate.start+d.minTokens]) {
+			continue
+		}
+		occurrences = append(occurrences, candidate)
+	}
+	return occurrences
+}
+
+// extend grows the duplicated block while every occurrence continues with the same token and no occurrences overlap.
+func (d DuplicateCodeAnalyzer) extend(bodies []tokenizedBody, occurrences []cloneOccurrence) int {
+	length := d.minTokens
+	for {
+		first := occurrences[0]
+		if first.start+length >= len(bodies[first.body].tokens) {
+			return length
+		}
+		next := bodies[first.body].tokens[first.start+length].text
+		for i, occurrence := range occurrences {
+			tokens := bodies[occurrence.body].tokens
+			if occurrence.start+length >= len(tokens) || tokens[occurrence.start+length].text != next {
+				return length
+			}
+			if i > 0 && occurrences[i-1].body == occurrence.body && occurrences[i-1].start+length+1 > occurrence.start {
+				return length
+			}
+		}
+		length++
+	}
+}
+
+// newDuplicateIssue describes a duplicated block and suggests the directory to consolidate it into.
+func newDuplicateIssue(bodies []tokenizedBody, occurrences []cloneOccurrence, length int) models.CodeIssue {
+	locations := make([]models.CodeLocation, len(occurrences))
+	dirs := make([]string, len(occurrences))
+	for i, occurrence := range occurrences {
+		tokens := bodies[occurrence.body].tokens
+		locations[i] = models.CodeLocation{
+			FilePath:  bodies[occurrence.body].path,
+			StartLine: tokens[occurrence.start].line,
+			EndLine:   tokens[occurrence.start+length-1].line,
+		}
+		dirs[i] = path.Dir(locations[i].FilePath)
+	}
+
+	target := commonDir(dirs)
+	suggestion := fmt.Sprintf("Extract the duplicated block into a shared function in %s", target)
+	if !allEqual(dirs) {
+		suggestion = fmt.Sprintf("Extract the duplicated block into a shared package under %s and call it from each location", target)
+	}
+
+	return models.CodeIssue{
+		Tool:                models.ToolNameDuplicateCode,
+		Type:                models.IssueTypeDuplication,
+		RuleID:              "duplicate-block",
+		Message:             fmt.Sprintf("Block of %d lines (%d tokens) is duplicated in %d places", locations[0].EndLine-locations[0].StartLine+1, length, len(locations)),
+		FilePath:            locations[0].FilePath,
+		Line:                locations[0].StartLine,
+		EndLine:             locations[0].EndLine,
+		Suggestions:         []string{suggestion},
+		Locations:           locations,
+		ConsolidationTarget: target,
+	}
+}
+
+// tokenizeFunctionBodies scans the bodies of a Go file's functions into normalized tokens. Identifiers and literals are
+// replaced by their kind, and comments and line-ending semicolons are dropped. Declarations outside functions, such as
+// long constant blocks, are left out because they repeat by nature.
+func tokenizeFunctionBodies(filename string) ([][]sourceToken, error) {
+	src, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	parsed, err := parser.ParseFile(fset, filename, src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	var bodies [][]sourceToken
+	for _, decl := range parsed.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Body != nil {
+			bodies = append(bodies, tokenizeRange(fset, src, fn.Body.Pos(), fn.Body.End()))
+		}
+	}
+	return bodies, nil
+}
+
+// tokenizeRange scans the source between two positions of an already parsed file into normalized tokens.
+func tokenizeRange(fset *token.FileSet, src []byte, from, to token.Pos) []sourceToken {
+	base := fset.File(from)
+	start, end := base.Offset(from), base.Offset(to)
+	firstLine := base.Line(from)
+
+	// The range is scanned as a file of its own, so lines are shifted back to those of the whole file
+	rangeSet := token.NewFileSet()
+	file := rangeSet.AddFile("", rangeSet.Base(), end-start)
+
+	var s scanner.Scanner
+	s.Init(file, src[start:end], nil, 0)
+
+	var tokens []sourceToken
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		// Automatically inserted semicolons would let blocks start at the end of the previous line
+		if tok == token.SEMICOLON && lit == "\n" {
+			continue
+		}
+
+		text := tok.String()
+		switch {
+		case tok == token.IDENT:
+			text = "ident"
+		case tok.IsLiteral():
+			text = "literal"
+		}
+		tokens = append(tokens, sourceToken{text: text, line: firstLine + rangeSet.Position(pos).Line - 1})
+	}
+	return tokens
+}
+
+// hashTokens hashes the text of a window of tokens.
+func hashTokens(tokens []sourceToken) uint64 {
+	h := fnv.New64a()
+	for _, t := range tokens {
+		_, _ = h.Write([]byte(t.text))
+		_, _ = h.Write([]byte{0})
+	}
+	return h.Sum64()
+}
+
+// equalTokens reports whether two windows hold the same tokens, guarding against hash collisions.
+func equalTokens(a, b []sourceToken) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].text != b[i].text {
+			return false
+		}
+	}
+	return true
+}
+
+// commonDir returns the deepest directory containing every given directory.
+func commonDir(dirs []string) string {
+	common := strings.Split(dirs[0], "/")
+	for _, dir := range dirs[1:] {
+		parts := strings.Split(dir, "/")
+		n := 0
+		for n < len(common) && n < len(parts) && common[n] == parts[n] {
+			n++
+		}
+		common = common[:n]
+	}
+	if len(common) == 0 {
+		return "."
+	}
+	return strings.Join(common, "/")
+}
+
+// allEqual reports whether every value is the same.
+func allEqual(values []string) bool {
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	return sorted[0] == sorted[len(sorted)-1]
+}
+
+// decodeIssues decodes issues that an in-process analyzer encoded as its raw JSON output.
+func decodeIssues(result models.AnalysisResult) ([]models.CodeIssue, error) {
+	if result.RawOutput == "" {
+		return nil, nil
+	}
+	var issues []models.CodeIssue
+	if err := json.Unmarshal([]byte(result.RawOutput), &issues); err != nil {
+		return nil, fmt.Errorf("error unmarshalling issues: %v", err)
+	}
+	return issues, nil
+}
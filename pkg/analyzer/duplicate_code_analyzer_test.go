@@ -0,0 +1,106 @@
+package analyzer_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/analyzer"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/analyzer/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDuplicateCodeAnalyzer_CrossFile(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	writeGoFile(t, dir, "billing/invoice.go", `package billing
+
+func total(items []int, tax int) int {
+	sum := 0
+	for _, item := range items {
+		if item < 0 {
+			continue
+		}
+		sum += item
+	}
+	return sum + sum*tax/100
+}
+`)
+	// The copy renames its identifiers and is preceded by another function
+	writeGoFile(t, dir, "payments/charge.go", `package payments
+
+func noop() {}
+
+func amount(lines []int, vat int) int {
+	acc := 0
+	for _, line := range lines {
+		if line < 0 {
+			continue
+		}
+		acc += line
+	}
+	return acc + acc*vat/100
+}
+`)
+	writeGoFile(t, dir, "payments/charge_mock.go", `// Code generated by MockGen. DO NOT EDIT.
+package payments
+
+func amountMock(lines []int, vat int) int {
+	acc := 0
+	for _, line := range lines {
+		if line < 0 {
+			continue
+		}
+		acc += line
+	}
+	return acc + acc*vat/100
+}
+`)
+
+	a := analyzer.NewDuplicateCodeAnalyzer(30)
+
+	// Act
+	result, err := a.AnalyzeCode(dir)
+	require.NoError(t, err)
+	issues, err := a.ExtractIssues(result)
+	require.NoError(t, err)
+
+	// Assert
+	require.Len(t, issues, 1)
+	assert.Equal(t, models.IssueTypeDuplication, issues[0].Type)
+	assert.Equal(t, []models.CodeLocation{
+		{FilePath: "billing/invoice.go", StartLine: 3, EndLine: 12},
+		{FilePath: "payments/charge.go", StartLine: 5, EndLine: 14},
+	}, issues[0].Locations)
+	assert.Equal(t, ".", issues[0].ConsolidationTarget)
+}
+
+func TestDuplicateCodeAnalyzer_NoDuplicates(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	writeGoFile(t, dir, "main.go", `package main
+
+func main() {
+	println("hello")
+}
+`)
+
+	a := analyzer.NewDuplicateCodeAnalyzer(analyzer.DefaultDuplicateMinTokens)
+
+	// Act
+	result, err := a.AnalyzeCode(dir)
+	require.NoError(t, err)
+	issues, err := a.ExtractIssues(result)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+}
+
+func writeGoFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
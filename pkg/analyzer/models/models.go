@@ -12,6 +12,15 @@ const (
 
 	// ToolNameGoTest tool name for go test tool.
 	ToolNameGoTest = "go test"
+
+	// ToolNameDuplicateCode tool name for the duplicate code detector.
+	ToolNameDuplicateCode = "duplicate-code"
+
+	// ToolNameDeadCode tool name for the dead code detector.
+	ToolNameDeadCode = "dead-code"
+
+	// ToolNamePackageStructure tool name for the package structure analyzer.
+	ToolNamePackageStructure = "package-structure"
 )
 
 // IssueType issue type string.
@@ -29,6 +38,18 @@ const (
 
 	// IssueTypeCoverage Test Coverage Issue Type.
 	IssueTypeCoverage IssueType = "coverage"
+
+	// IssueTypeDuplication Duplicated Code Issue Type.
+	IssueTypeDuplication IssueType = "duplication"
+
+	// IssueTypeDeadCode Dead Code Issue Type.
+	IssueTypeDeadCode IssueType = "dead_code"
+
+	// IssueTypeImportCycle Import Cycle Issue Type.
+	IssueTypeImportCycle IssueType = "import_cycle"
+
+	// IssueTypeGodPackage God Package Issue Type.
+	IssueTypeGodPackage IssueType = "god_package"
 )
 
 // CodeIssue represents a standardized structure for linter findings across different languages.
@@ -39,9 +60,29 @@ type CodeIssue struct {
 	Message       string    `json:"message"`                  // Description of the issue
 	FilePath      string    `json:"file_path,omitempty"`      // Path to the file containing the issue
 	Line          int       `json:"line,omitempty"`           // Line number where the issue occurs
+	EndLine       int       `json:"end_line,omitempty"`       // Last line of a multi-line issue (optional)
 	Column        int       `json:"column,omitempty"`         // Column number (optional)
 	SourceSnippet []string  `json:"source_snippet,omitempty"` // Code snippet related to the issue
 	Suggestions   []string  `json:"suggestions,omitempty"`    // Recommended fixes or improvements
+
+	Locations           []CodeLocation `json:"locations,omitempty"`            // Every location of an issue spanning several places, such as duplicated code
+	ConsolidationTarget string         `json:"consolidation_target,omitempty"` // Directory the duplicated code should be moved to
+	Packages            []string       `json:"packages,omitempty"`             // Package directories of a package structure issue, in the order of an import cycle
+	Moves               []PackageMove  `json:"moves,omitempty"`                // Candidate moves splitting a god package
+}
+
+// PackageMove represents files that could move out of a package together.
+type PackageMove struct {
+	Target       string   `json:"target"`       // Directory of the new package, relative to the module root
+	Files        []string `json:"files"`        // Files to move
+	Declarations []string `json:"declarations"` // Exported declarations of the files
+}
+
+// CodeLocation represents a range of lines in a file.
+type CodeLocation struct {
+	FilePath  string `json:"file_path"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
 }
 
 // AnalysisResult represents the result of a code analysis.
@@ -49,3 +90,56 @@ type AnalysisResult struct {
 	RawOutput string
 	Errors    []string
 }
+
+// CodeMetrics summarizes the complexity, function length and package coupling of a Go codebase.
+type CodeMetrics struct {
+	Files                 int               `json:"files"`                   // Number of hand-written Go files
+	Functions             int               `json:"functions"`               // Number of functions and methods with a body
+	AverageComplexity     float64           `json:"average_complexity"`      // Mean cyclomatic complexity of the functions
+	MaxComplexity         int               `json:"max_complexity"`          // Highest cyclomatic complexity of a function
+	AverageFunctionLength float64           `json:"average_function_length"` // Mean number of lines of a function
+	MaxFunctionLength     int               `json:"max_function_length"`     // Longest function, in lines
+	AverageInstability    float64           `json:"average_instability"`     // Mean instability of the packages, from 0 (stable) to 1
+	MaintainabilityScore  float64           `json:"maintainability_score"`   // Percentage of functions within the complexity and length limits
+	Packages              []PackageCoupling `json:"packages"`                // Coupling of each package
+	Hotspots              []FunctionMetrics `json:"hotspots"`                // Most complex functions
+}
+
+// PackageCoupling represents the imports between the packages of a module.
+type PackageCoupling struct {
+	Path        string  `json:"path"`        // Package directory, relative to the module root
+	Afferent    int     `json:"afferent"`    // Number of packages of the module importing this package
+	Efferent    int     `json:"efferent"`    // Number of packages of the module imported by this package
+	Instability float64 `json:"instability"` // Efferent / (Afferent + Efferent), 0 when the package is not coupled
+}
+
+// FunctionMetrics represents the complexity and length of a function.
+type FunctionMetrics struct {
+	Name       string `json:"name"`      // Function name, prefixed with its receiver type for methods
+	FilePath   string `json:"file_path"` // File declaring the function
+	Line       int    `json:"line"`      // Line of the declaration
+	Complexity int    `json:"complexity"`
+	Length     int    `json:"length"` // Number of lines from the signature to the closing brace
+}
+
+// PackageGraph represents the imports between the packages of a Go module.
+type PackageGraph struct {
+	Module   string          `json:"module"`   // Module path
+	Packages []PackageNode   `json:"packages"` // Packages of the module, by directory
+	Imports  []PackageImport `json:"imports"`  // Imports between the packages of the module
+	DOT      string          `json:"dot"`      // The graph in the Graphviz DOT language
+}
+
+// PackageNode represents a package of a module.
+type PackageNode struct {
+	Path         string `json:"path"`         // Package directory, relative to the module root
+	Files        int    `json:"files"`        // Number of hand-written Go files, tests excluded
+	Lines        int    `json:"lines"`        // Number of lines of the files
+	Declarations int    `json:"declarations"` // Number of top-level declarations of the files
+}
+
+// PackageImport represents an import of a package of a module by another one.
+type PackageImport struct {
+	From string `json:"from"` // Importing package directory
+	To   string `json:"to"`   // Imported package directory
+}
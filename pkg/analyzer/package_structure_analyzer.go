@@ -0,0 +1,429 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/analyzer/models"
+)
+
+// maxPackageMoves is the largest number of candidate moves reported for a god package.
+const maxPackageMoves = 10
+
+// PackageStructureAnalyzer builds the import graph between the packages of a Go module and reports its import cycles
+// and god packages, which have at least minDeclarations top-level declarations in at least minLines lines. The files
+// of a god package are grouped by the prefix of their name, such as task_service.go and task_repository.go, into
+// candidate moves to new packages. Test files are left out.
+type PackageStructureAnalyzer struct {
+	minDeclarations int
+	minLines        int
+}
+
+// NewPackageStructureAnalyzer creates a package structure analyzer.
+func NewPackageStructureAnalyzer(minDeclarations, minLines int) GraphAnalyzer {
+	return PackageStructureAnalyzer{minDeclarations: minDeclarations, minLines: minLines}
+}
+
+// packageStructureReport is the raw output of the package structure analyzer.
+type packageStructureReport struct {
+	Issues []models.CodeIssue  `json:"issues"`
+	Graph  models.PackageGraph `json:"graph"`
+}
+
+// structurePackage holds the size, imports and declarations of a package of the module.
+type structurePackage struct {
+	node    models.PackageNode
+	imports map[string][]models.CodeLocation // Import specs of each imported package of the module, by directory
+	exports map[string][]string              // Exported top-level declarations of each file
+}
+
+// AnalyzeCode parses the Go files of the module under sourcePath and reports its package structure as JSON.
+func (a PackageStructureAnalyzer) AnalyzeCode(sourcePath string) (models.AnalysisResult, error) {
+	modulePath, err := readModulePath(sourcePath)
+	if err != nil {
+		return models.AnalysisResult{}, err
+	}
+	if modulePath == "" {
+		return models.AnalysisResult{}, fmt.Errorf("package structure analysis requires a go.mod at the root of the codebase")
+	}
+	paths, err := goSourceFiles(sourcePath)
+	if err != nil {
+		return models.AnalysisResult{}, fmt.Errorf("failed to list go files: %v", err)
+	}
+
+	result := models.AnalysisResult{}
+	fset := token.NewFileSet()
+	packages := make(map[string]*structurePackage)
+	for _, rel := range paths {
+		if strings.HasSuffix(rel, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(sourcePath, rel), nil, parser.SkipObjectResolution)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", rel, err))
+			continue
+		}
+
+		dir := path.Dir(rel)
+		pkg := packages[dir]
+		if pkg == nil {
+			pkg = &structurePackage{
+				node:    models.PackageNode{Path: dir},
+				imports: make(map[string][]models.CodeLocation),
+				exports: make(map[string][]string),
+			}
+			packages[dir] = pkg
+		}
+		pkg.node.Files++
+		pkg.node.Lines += fset.File(file.Pos()).LineCount()
+
+		for _, spec := range file.Imports {
+			importPath, err := strconv.Unquote(spec.Path.Value)
+			if err != nil {
+				continue
+			}
+			target, internal := moduleDir(modulePath, importPath)
+			if !internal || target == dir {
+				continue
+			}
+			line := fset.Position(spec.Pos()).Line
+			pkg.imports[target] = append(pkg.imports[target], models.CodeLocation{FilePath: rel, StartLine: line, EndLine: line})
+		}
+
+		declarations, exported := topLevelDeclarations(file)
+		pkg.node.Declarations += declarations
+		pkg.exports[rel] = exported
+	}
+
+	graph := packageGraph(modulePath, packages)
+	issues := importCycles(packages, graph)
+	gods := make(map[string]bool)
+	for _, node := range graph.Packages {
+		if node.Declarations >= a.minDeclarations && node.Lines >= a.minLines {
+			gods[node.Path] = true
+			issues = append(issues, godPackage(packages[node.Path], graph))
+		}
+	}
+	graph.DOT = packageGraphDOT(graph, issues, gods)
+
+	output, err := json.Marshal(packageStructureReport{Issues: issues, Graph: graph})
+	if err != nil {
+		return result, fmt.Errorf("failed to encode package structure: %v", err)
+	}
+	result.RawOutput = string(output)
+
+	return result, nil
+}
+
+// ExtractIssues decodes the import cycles and god packages reported by AnalyzeCode.
+func (a PackageStructureAnalyzer) ExtractIssues(result models.AnalysisResult) ([]models.CodeIssue, error) {
+	report, err := decodePackageStructure(result)
+	if err != nil {
+		return nil, err
+	}
+	return report.Issues, nil
+}
+
+// ExtractGraph decodes the package graph reported by AnalyzeCode.
+func (a PackageStructureAnalyzer) ExtractGraph(result models.AnalysisResult) (models.PackageGraph, error) {
+	report, err := decodePackageStructure(result)
+	if err != nil {
+		return models.PackageGraph{}, err
+	}
+	return report.Graph, nil
+}
+
+// decodePackageStructure decodes the raw output of the package structure analyzer.
+func decodePackageStructure(result models.AnalysisResult) (packageStructureReport, error) {
+	var report packageStructureReport
+	if result.RawOutput == "" {
+		return report, nil
+	}
+	if err := json.Unmarshal([]byte(result.RawOutput), &report); err != nil {
+		return report, fmt.Errorf("error unmarshalling package structure: %v", err)
+	}
+	return report, nil
+}
+
+// moduleDir returns the directory of a package of the module, relative to the module root, and whether importPath
+// belongs to the module.
+func moduleDir(modulePath, importPath string) (string, bool) {
+	if importPath == modulePath {
+		return ".", true
+	}
+	if dir, ok := strings.CutPrefix(importPath, modulePath+"/"); ok {
+		return dir, true
+	}
+	return "", false
+}
+
+// topLevelDeclarations counts the top-level declarations of a file, methods included, and lists its exported
+// functions, types, variables and constants in lexical order.
+func topLevelDeclarations(file *ast.File) (int, []string) {
+	count := 0
+	exported := []string{}
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			count++
+			if decl.Recv == nil && decl.Name.IsExported() {
+				exported = append(exported, decl.Name.Name)
+			}
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					count++
+					if spec.Name.IsExported() {
+						exported = append(exported, spec.Name.Name)
+					}
+				case *ast.ValueSpec:
+					for _, name := range spec.Names {
+						count++
+						if name.IsExported() {
+							exported = append(exported, name.Name)
+						}
+					}
+				}
+			}
+		}
+	}
+	sort.Strings(exported)
+	return count, exported
+}
+
+// packageGraph lists the packages of the module and the imports between them in lexical order. Imports of
+// directories without Go files are left out.
+func packageGraph(modulePath string, packages map[string]*structurePackage) models.PackageGraph {
+	graph := models.PackageGraph{Module: modulePath, Packages: []models.PackageNode{}, Imports: []models.PackageImport{}}
+	for _, dir := range sortedKeys(packages) {
+		graph.Packages = append(graph.Packages, packages[dir].node)
+		for _, target := range sortedKeys(packages[dir].imports) {
+			if packages[target] != nil {
+				graph.Imports = append(graph.Imports, models.PackageImport{From: dir, To: target})
+			}
+		}
+	}
+	return graph
+}
+
+// importCycles reports an import cycle for each set of packages that import each other, naming the shortest cycle
+// through the first package of the set.
+func importCycles(packages map[string]*structurePackage, graph models.PackageGraph) []models.CodeIssue {
+	successors := make(map[string][]string)
+	for _, edge := range graph.Imports {
+		successors[edge.From] = append(successors[edge.From], edge.To)
+	}
+
+	issues := []models.CodeIssue{}
+	for _, component := range stronglyConnectedComponents(graph.Packages, successors) {
+		if len(component) < 2 {
+			continue
+		}
+		cycle := shortestCycle(component, successors)
+
+		issue := models.CodeIssue{
+			Tool:     models.ToolNamePackageStructure,
+			Type:     models.IssueTypeImportCycle,
+			RuleID:   "import-cycle",
+			Message:  fmt.Sprintf("Import cycle between packages %s -> %s", strings.Join(cycle, " -> "), cycle[0]),
+			Packages: cycle,
+		}
+		for i, from := range cycle {
+			to := cycle[(i+1)%len(cycle)]
+			issue.Locations = append(issue.Locations, packages[from].imports[to][0])
+		}
+		issue.FilePath = issue.Locations[0].FilePath
+		issue.Line = issue.Locations[0].StartLine
+		if len(component) > len(cycle) {
+			issue.Suggestions = append(issue.Suggestions, fmt.Sprintf("The cycle is part of %d packages importing each other: %s", len(component), strings.Join(component, ", ")))
+		}
+		issues = append(issues, issue)
+	}
+	return issues
+}
+
+// stronglyConnectedComponents returns the sets of packages reachable from each other with Tarjan's algorithm, each
+// sorted, in the order of their first package.
+func stronglyConnectedComponents(nodes []models.PackageNode, successors map[string][]string) [][]string {
+	index := make(map[string]int)
+	lowLink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var components [][]string
+
+	var visit func(node string)
+	visit = func(node string) {
+		index[node] = len(index)
+		lowLink[node] = index[node]
+		stack = append(stack, node)
+		onStack[node] = true
+
+		for _, next := range successors[node] {
+			if _, visited := index[next]; !visited {
+				visit(next)
+				lowLink[node] = min(lowLink[node], lowLink[next])
+			} else if onStack[next] {
+				lowLink[node] = min(lowLink[node], index[next])
+			}
+		}
+
+		if lowLink[node] == index[node] {
+			var component []string
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				component = append(component, top)
+				if top == node {
+					break
+				}
+			}
+			sort.Strings(component)
+			components = append(components, component)
+		}
+	}
+	for _, node := range nodes {
+		if _, visited := index[node.Path]; !visited {
+			visit(node.Path)
+		}
+	}
+
+	sort.Slice(components, func(i, j int) bool { return components[i][0] < components[j][0] })
+	return components
+}
+
+// shortestCycle returns the shortest import cycle from the first package of a component back to it, found by a
+// breadth-first search within the component.
+func shortestCycle(component []string, successors map[string][]string) []string {
+	start := component[0]
+	inComponent := make(map[string]bool, len(component))
+	for _, node := range component {
+		inComponent[node] = true
+	}
+
+	previous := map[string]string{}
+	queue := []string{start}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, next := range successors[node] {
+			if next == start {
+				cycle := []string{node}
+				for cycle[0] != start {
+					cycle = append([]string{previous[cycle[0]]}, cycle...)
+				}
+				return cycle
+			}
+			if _, seen := previous[next]; !seen && inComponent[next] {
+				previous[next] = node
+				queue = append(queue, next)
+			}
+		}
+	}
+	return component
+}
+
+// godPackage reports a package that has grown too large, with candidate moves of its files to new packages.
+func godPackage(pkg *structurePackage, graph models.PackageGraph) models.CodeIssue {
+	coupled := make(map[string]bool)
+	for _, edge := range graph.Imports {
+		if edge.From == pkg.node.Path {
+			coupled[edge.To] = true
+		}
+		if edge.To == pkg.node.Path {
+			coupled[edge.From] = true
+		}
+	}
+
+	return models.CodeIssue{
+		Tool:   models.ToolNamePackageStructure,
+		Type:   models.IssueTypeGodPackage,
+		RuleID: "god-package",
+		Message: fmt.Sprintf("Package %s has %d declarations in %d lines across %d files and is coupled with %d packages of the module",
+			pkg.node.Path, pkg.node.Declarations, pkg.node.Lines, pkg.node.Files, len(coupled)),
+		FilePath: pkg.node.Path,
+		Packages: []string{pkg.node.Path},
+		Moves:    packageMoves(pkg),
+	}
+}
+
+// packageMoves groups the files of a package sharing the prefix of their name before the first underscore into moves
+// to a new package named after the prefix, largest first. Groups of one file, or of every file, are left out.
+func packageMoves(pkg *structurePackage) []models.PackageMove {
+	groups := make(map[string][]string)
+	for _, file := range sortedKeys(pkg.exports) {
+		prefix, _, _ := strings.Cut(strings.TrimSuffix(path.Base(file), ".go"), "_")
+		groups[prefix] = append(groups[prefix], file)
+	}
+
+	moves := []models.PackageMove{}
+	for _, prefix := range sortedKeys(groups) {
+		files := groups[prefix]
+		if len(files) < 2 || len(files) == pkg.node.Files {
+			continue
+		}
+		move := models.PackageMove{Target: path.Join(pkg.node.Path, prefix), Files: files, Declarations: []string{}}
+		for _, file := range files {
+			move.Declarations = append(move.Declarations, pkg.exports[file]...)
+		}
+		sort.Strings(move.Declarations)
+		moves = append(moves, move)
+	}
+	sort.SliceStable(moves, func(i, j int) bool { return len(moves[i].Files) > len(moves[j].Files) })
+	if len(moves) > maxPackageMoves {
+		moves = moves[:maxPackageMoves]
+	}
+	return moves
+}
+
+// packageGraphDOT renders the package graph in the Graphviz DOT language, with god packages filled and the imports of
+// import cycles in red.
+func packageGraphDOT(graph models.PackageGraph, issues []models.CodeIssue, gods map[string]bool) string {
+	cyclic := make(map[models.PackageImport]bool)
+	for _, issue := range issues {
+		if issue.Type != models.IssueTypeImportCycle {
+			continue
+		}
+		for i, from := range issue.Packages {
+			cyclic[models.PackageImport{From: from, To: issue.Packages[(i+1)%len(issue.Packages)]}] = true
+		}
+	}
+
+	var dot strings.Builder
+	fmt.Fprintf(&dot, "digraph %s {\n", strconv.Quote(graph.Module))
+	for _, node := range graph.Packages {
+		attributes := fmt.Sprintf("label=%s", strconv.Quote(fmt.Sprintf("%s\n%d files, %d lines", node.Path, node.Files, node.Lines)))
+		if gods[node.Path] {
+			attributes += ", style=filled, fillcolor=orange"
+		}
+		fmt.Fprintf(&dot, "\t%s [%s];\n", strconv.Quote(node.Path), attributes)
+	}
+	for _, edge := range graph.Imports {
+		fmt.Fprintf(&dot, "\t%s -> %s", strconv.Quote(edge.From), strconv.Quote(edge.To))
+		if cyclic[edge] {
+			dot.WriteString(" [color=red]")
+		}
+		dot.WriteString(";\n")
+	}
+	dot.WriteString("}\n")
+	return dot.String()
+}
+
+// sortedKeys returns the keys of a map in lexical order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
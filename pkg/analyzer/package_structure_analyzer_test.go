@@ -0,0 +1,113 @@
+package analyzer_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/analyzer"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/analyzer/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackageStructureAnalyzer(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	writeGoFile(t, dir, "go.mod", "module example.com/app\n\ngo 1.24\n")
+	writeGoFile(t, dir, "main.go", `package main
+
+import "example.com/app/api"
+
+func main() { api.Serve() }
+`)
+	writeGoFile(t, dir, "api/api.go", `package api
+
+import (
+	"fmt"
+
+	"example.com/app/store"
+)
+
+func Serve() { fmt.Println(store.Get()) }
+
+func Handle() {}
+`)
+	writeGoFile(t, dir, "store/store.go", `package store
+
+import "example.com/app/api"
+
+func Get() string { api.Handle(); return "" }
+`)
+	writeGoFile(t, dir, "store/store_test.go", `package store
+
+import "example.com/app"
+`)
+	// A god package with two groups of files
+	for _, name := range []string{"task_service", "task_repository", "user_service", "user_repository", "helpers"} {
+		var content strings.Builder
+		content.WriteString("package services\n\n")
+		for i := range 3 {
+			fmt.Fprintf(&content, "func %s%d() {}\n\nfunc helper%s%d() {}\n\n", strings.ToUpper(name[:1])+strings.ReplaceAll(name[1:], "_", ""), i, strings.ReplaceAll(name, "_", ""), i)
+		}
+		writeGoFile(t, dir, "services/"+name+".go", content.String())
+	}
+
+	a := analyzer.NewPackageStructureAnalyzer(30, 50)
+
+	// Act
+	result, err := a.AnalyzeCode(dir)
+	require.NoError(t, err)
+	issues, err := a.ExtractIssues(result)
+	require.NoError(t, err)
+	graph, err := a.ExtractGraph(result)
+	require.NoError(t, err)
+
+	// Assert
+	require.Len(t, issues, 2)
+	cycle := issues[0]
+	assert.Equal(t, models.IssueTypeImportCycle, cycle.Type)
+	assert.Equal(t, "Import cycle between packages api -> store -> api", cycle.Message)
+	assert.Equal(t, []string{"api", "store"}, cycle.Packages)
+	assert.Equal(t, []models.CodeLocation{
+		{FilePath: "api/api.go", StartLine: 6, EndLine: 6},
+		{FilePath: "store/store.go", StartLine: 3, EndLine: 3},
+	}, cycle.Locations)
+
+	god := issues[1]
+	assert.Equal(t, models.IssueTypeGodPackage, god.Type)
+	assert.Equal(t, "services", god.FilePath)
+	assert.Equal(t, "Package services has 30 declarations in 70 lines across 5 files and is coupled with 0 packages of the module", god.Message)
+	assert.Equal(t, []models.PackageMove{
+		{
+			Target:       "services/task",
+			Files:        []string{"services/task_repository.go", "services/task_service.go"},
+			Declarations: []string{"Taskrepository0", "Taskrepository1", "Taskrepository2", "Taskservice0", "Taskservice1", "Taskservice2"},
+		},
+		{
+			Target:       "services/user",
+			Files:        []string{"services/user_repository.go", "services/user_service.go"},
+			Declarations: []string{"Userrepository0", "Userrepository1", "Userrepository2", "Userservice0", "Userservice1", "Userservice2"},
+		},
+	}, god.Moves)
+
+	assert.Equal(t, "example.com/app", graph.Module)
+	assert.Equal(t, []models.PackageImport{
+		{From: ".", To: "api"},
+		{From: "api", To: "store"},
+		{From: "store", To: "api"},
+	}, graph.Imports, "test files are left out")
+	assert.Len(t, graph.Packages, 4)
+	assert.Contains(t, graph.DOT, `"api" -> "store" [color=red];`)
+	assert.Contains(t, graph.DOT, `"." -> "api";`)
+	assert.Contains(t, graph.DOT, `"services" [label="services\n5 files, 70 lines", style=filled, fillcolor=orange];`)
+}
+
+func TestPackageStructureAnalyzer_RequiresGoMod(t *testing.T) {
+	dir := t.TempDir()
+	writeGoFile(t, dir, "main.go", "package main\n")
+
+	_, err := analyzer.NewPackageStructureAnalyzer(30, 50).AnalyzeCode(dir)
+
+	assert.ErrorContains(t, err, "requires a go.mod")
+}
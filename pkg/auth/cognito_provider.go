@@ -289,22 +289,59 @@ func (c *CognitoProvider) SignIn(ctx context.Context, req *SignInRequest) (*Auth
 		return nil, c.mapCognitoError(err)
 	}
 
-	if result.AuthenticationResult == nil {
+	// Users with MFA enabled get a challenge instead of tokens
+	if method, ok := mfaChallengeMethods[result.ChallengeName]; ok {
+		return &AuthResult{
+			Challenge: &MFAChallenge{Method: method, Session: aws.ToString(result.Session)},
+		}, nil
+	}
+
+	return c.authenticated(ctx, req.Username, result.AuthenticationResult)
+}
+
+// RespondToMFAChallenge completes an authentication with the one-time password of the user's second factor
+func (c *CognitoProvider) RespondToMFAChallenge(ctx context.Context, req *MFAChallengeResponse) (*AuthResult, error) {
+	challengeName, codeKey := types.ChallengeNameTypeSoftwareTokenMfa, "SOFTWARE_TOKEN_MFA_CODE"
+	if req.Method == MFAMethodSMS {
+		challengeName, codeKey = types.ChallengeNameTypeSmsMfa, "SMS_MFA_CODE"
+	}
+
+	input := &cognitoidentityprovider.RespondToAuthChallengeInput{
+		ClientId:      aws.String(c.config.ClientID),
+		ChallengeName: challengeName,
+		Session:       aws.String(req.Session),
+		ChallengeResponses: map[string]string{
+			"USERNAME": req.Username,
+			codeKey:    req.Code,
+		},
+	}
+
+	result, err := c.client.RespondToAuthChallenge(ctx, input)
+	if err != nil {
+		return nil, c.mapCognitoError(err)
+	}
+
+	return c.authenticated(ctx, req.Username, result.AuthenticationResult)
+}
+
+// authenticated returns the tokens of a completed authentication with the details of the user
+func (c *CognitoProvider) authenticated(ctx context.Context, username string, result *types.AuthenticationResultType) (*AuthResult, error) {
+	if result == nil {
 		return nil, errors.New("authentication failed")
 	}
 
 	// Get user details - assuming username is email for Cognito
-	user, err := c.GetUserByEmail(ctx, req.Username)
+	user, err := c.GetUserByEmail(ctx, username)
 	if err != nil {
 		return nil, err
 	}
 
 	return &AuthResult{
-		AccessToken:  aws.ToString(result.AuthenticationResult.AccessToken),
-		RefreshToken: aws.ToString(result.AuthenticationResult.RefreshToken),
-		IDToken:      aws.ToString(result.AuthenticationResult.IdToken),
-		ExpiresIn:    int(result.AuthenticationResult.ExpiresIn),
-		TokenType:    aws.ToString(result.AuthenticationResult.TokenType),
+		AccessToken:  aws.ToString(result.AccessToken),
+		RefreshToken: aws.ToString(result.RefreshToken),
+		IDToken:      aws.ToString(result.IdToken),
+		ExpiresIn:    int(result.ExpiresIn),
+		TokenType:    aws.ToString(result.TokenType),
 		User:         user,
 	}, nil
 }
@@ -400,8 +437,102 @@ func (c *CognitoProvider) ConfirmPasswordReset(ctx context.Context, req *Passwor
 	return c.mapCognitoError(err)
 }
 
+// ChangePassword changes the password of the user signed in with the access token
+func (c *CognitoProvider) ChangePassword(ctx context.Context, req *ChangePasswordRequest) error {
+	input := &cognitoidentityprovider.ChangePasswordInput{
+		AccessToken:      aws.String(req.AccessToken),
+		PreviousPassword: aws.String(req.PreviousPassword),
+		ProposedPassword: aws.String(req.ProposedPassword),
+	}
+
+	_, err := c.client.ChangePassword(ctx, input)
+	return c.mapCognitoError(err)
+}
+
+// SetupTOTP generates the secret of a new authenticator app for the user signed in with the access token
+func (c *CognitoProvider) SetupTOTP(ctx context.Context, accessToken string) (*TOTPSetup, error) {
+	input := &cognitoidentityprovider.AssociateSoftwareTokenInput{
+		AccessToken: aws.String(accessToken),
+	}
+
+	result, err := c.client.AssociateSoftwareToken(ctx, input)
+	if err != nil {
+		return nil, c.mapCognitoError(err)
+	}
+
+	return &TOTPSetup{SecretCode: aws.ToString(result.SecretCode)}, nil
+}
+
+// VerifyTOTP verifies a one-time password of the authenticator app set up last and makes TOTP the user's preferred
+// second factor
+func (c *CognitoProvider) VerifyTOTP(ctx context.Context, req *VerifyTOTPRequest) error {
+	input := &cognitoidentityprovider.VerifySoftwareTokenInput{
+		AccessToken: aws.String(req.AccessToken),
+		UserCode:    aws.String(req.Code),
+	}
+	if req.DeviceName != "" {
+		input.FriendlyDeviceName = aws.String(req.DeviceName)
+	}
+
+	result, err := c.client.VerifySoftwareToken(ctx, input)
+	if err != nil {
+		return c.mapCognitoError(err)
+	}
+	if result.Status != types.VerifySoftwareTokenResponseTypeSuccess {
+		return ErrInvalidCode
+	}
+
+	_, err = c.client.SetUserMFAPreference(ctx, &cognitoidentityprovider.SetUserMFAPreferenceInput{
+		AccessToken:              aws.String(req.AccessToken),
+		SoftwareTokenMfaSettings: &types.SoftwareTokenMfaSettingsType{Enabled: true, PreferredMfa: true},
+	})
+	return c.mapCognitoError(err)
+}
+
+// EnableSMSMFA sets the phone number of the user signed in with the access token and makes SMS their preferred second
+// factor
+func (c *CognitoProvider) EnableSMSMFA(ctx context.Context, accessToken, phoneNumber string) error {
+	_, err := c.client.UpdateUserAttributes(ctx, &cognitoidentityprovider.UpdateUserAttributesInput{
+		AccessToken: aws.String(accessToken),
+		UserAttributes: []types.AttributeType{
+			{
+				Name:  aws.String("phone_number"),
+				Value: aws.String(phoneNumber),
+			},
+		},
+	})
+	if err != nil {
+		return c.mapCognitoError(err)
+	}
+
+	_, err = c.client.SetUserMFAPreference(ctx, &cognitoidentityprovider.SetUserMFAPreferenceInput{
+		AccessToken:    aws.String(accessToken),
+		SMSMfaSettings: &types.SMSMfaSettingsType{Enabled: true, PreferredMfa: true},
+	})
+	return c.mapCognitoError(err)
+}
+
+// DisableMFA disables every second factor of a user
+func (c *CognitoProvider) DisableMFA(ctx context.Context, userID string) error {
+	input := &cognitoidentityprovider.AdminSetUserMFAPreferenceInput{
+		UserPoolId:               aws.String(c.config.UserPoolID),
+		Username:                 aws.String(userID),
+		SMSMfaSettings:           &types.SMSMfaSettingsType{Enabled: false},
+		SoftwareTokenMfaSettings: &types.SoftwareTokenMfaSettingsType{Enabled: false},
+	}
+
+	_, err := c.client.AdminSetUserMFAPreference(ctx, input)
+	return c.mapCognitoError(err)
+}
+
 // Helper methods
 
+// mfaChallengeMethods maps the Cognito MFA challenges to the second factors they ask for
+var mfaChallengeMethods = map[types.ChallengeNameType]MFAMethod{
+	types.ChallengeNameTypeSoftwareTokenMfa: MFAMethodTOTP,
+	types.ChallengeNameTypeSmsMfa:           MFAMethodSMS,
+}
+
 // mapCognitoUserToUser converts a Cognito user to our User model
 func (c *CognitoProvider) mapCognitoUserToUser(cognitoUser *types.UserType) *User {
 	user := &User{
@@ -448,6 +579,16 @@ func (c *CognitoProvider) mapCognitoError(err error) error {
 		return ErrUserAlreadyExists
 	}
 
+	var passwordErr *types.InvalidPasswordException
+	if errors.As(err, &passwordErr) {
+		return ErrInvalidPassword
+	}
+
+	var codeErr *types.CodeMismatchException
+	if errors.As(err, &codeErr) {
+		return ErrInvalidCode
+	}
+
 	return err
 }
 
@@ -478,4 +619,6 @@ var (
 	ErrUserNotFound       = errors.New("user not found")
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	ErrUserAlreadyExists  = errors.New("user already exists")
+	ErrInvalidPassword    = errors.New("password does not meet the password policy")
+	ErrInvalidCode        = errors.New("invalid verification code")
 )
@@ -44,6 +44,16 @@ func TestCognitoProvider_ErrorMapping(t *testing.T) {
 				cognitoError:  &types.UsernameExistsException{},
 				expectedError: ErrUserAlreadyExists,
 			},
+			{
+				name:          "password policy violated",
+				cognitoError:  &types.InvalidPasswordException{},
+				expectedError: ErrInvalidPassword,
+			},
+			{
+				name:          "verification code mismatch",
+				cognitoError:  &types.CodeMismatchException{},
+				expectedError: ErrInvalidCode,
+			},
 		}
 
 		for _, tt := range tests {
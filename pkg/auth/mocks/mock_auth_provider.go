@@ -35,6 +35,20 @@ func (m *MockAuthProvider) EXPECT() *MockAuthProviderMockRecorder {
 	return m.recorder
 }
 
+// ChangePassword mocks base method.
+func (m *MockAuthProvider) ChangePassword(ctx context.Context, req *auth.ChangePasswordRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockAuthProviderMockRecorder) ChangePassword(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockAuthProvider)(nil).ChangePassword), ctx, req)
+}
+
 // ConfirmPasswordReset mocks base method.
 func (m *MockAuthProvider) ConfirmPasswordReset(ctx context.Context, req *auth.PasswordResetRequest) error {
 	m.ctrl.T.Helper()
@@ -92,6 +106,34 @@ func (mr *MockAuthProviderMockRecorder) DeleteUser(ctx, userID interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockAuthProvider)(nil).DeleteUser), ctx, userID)
 }
 
+// DisableMFA mocks base method.
+func (m *MockAuthProvider) DisableMFA(ctx context.Context, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableMFA", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableMFA indicates an expected call of DisableMFA.
+func (mr *MockAuthProviderMockRecorder) DisableMFA(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableMFA", reflect.TypeOf((*MockAuthProvider)(nil).DisableMFA), ctx, userID)
+}
+
+// EnableSMSMFA mocks base method.
+func (m *MockAuthProvider) EnableSMSMFA(ctx context.Context, accessToken, phoneNumber string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableSMSMFA", ctx, accessToken, phoneNumber)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnableSMSMFA indicates an expected call of EnableSMSMFA.
+func (mr *MockAuthProviderMockRecorder) EnableSMSMFA(ctx, accessToken, phoneNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableSMSMFA", reflect.TypeOf((*MockAuthProvider)(nil).EnableSMSMFA), ctx, accessToken, phoneNumber)
+}
+
 // GetUser mocks base method.
 func (m *MockAuthProvider) GetUser(ctx context.Context, userID string) (*auth.User, error) {
 	m.ctrl.T.Helper()
@@ -166,6 +208,36 @@ func (mr *MockAuthProviderMockRecorder) ResetPassword(ctx, email interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockAuthProvider)(nil).ResetPassword), ctx, email)
 }
 
+// RespondToMFAChallenge mocks base method.
+func (m *MockAuthProvider) RespondToMFAChallenge(ctx context.Context, req *auth.MFAChallengeResponse) (*auth.AuthResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RespondToMFAChallenge", ctx, req)
+	ret0, _ := ret[0].(*auth.AuthResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RespondToMFAChallenge indicates an expected call of RespondToMFAChallenge.
+func (mr *MockAuthProviderMockRecorder) RespondToMFAChallenge(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RespondToMFAChallenge", reflect.TypeOf((*MockAuthProvider)(nil).RespondToMFAChallenge), ctx, req)
+}
+
+// SetupTOTP mocks base method.
+func (m *MockAuthProvider) SetupTOTP(ctx context.Context, accessToken string) (*auth.TOTPSetup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetupTOTP", ctx, accessToken)
+	ret0, _ := ret[0].(*auth.TOTPSetup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetupTOTP indicates an expected call of SetupTOTP.
+func (mr *MockAuthProviderMockRecorder) SetupTOTP(ctx, accessToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetupTOTP", reflect.TypeOf((*MockAuthProvider)(nil).SetupTOTP), ctx, accessToken)
+}
+
 // SignIn mocks base method.
 func (m *MockAuthProvider) SignIn(ctx context.Context, req *auth.SignInRequest) (*auth.AuthResult, error) {
 	m.ctrl.T.Helper()
@@ -239,3 +311,17 @@ func (mr *MockAuthProviderMockRecorder) ValidateToken(ctx, token interface{}) *g
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateToken", reflect.TypeOf((*MockAuthProvider)(nil).ValidateToken), ctx, token)
 }
+
+// VerifyTOTP mocks base method.
+func (m *MockAuthProvider) VerifyTOTP(ctx context.Context, req *auth.VerifyTOTPRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyTOTP", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyTOTP indicates an expected call of VerifyTOTP.
+func (mr *MockAuthProviderMockRecorder) VerifyTOTP(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyTOTP", reflect.TypeOf((*MockAuthProvider)(nil).VerifyTOTP), ctx, req)
+}
@@ -34,6 +34,14 @@ type AuthProvider interface {
 	// Password Management
 	ResetPassword(ctx context.Context, email string) error
 	ConfirmPasswordReset(ctx context.Context, req *PasswordResetRequest) error
+	ChangePassword(ctx context.Context, req *ChangePasswordRequest) error
+
+	// Multi-Factor Authentication
+	SetupTOTP(ctx context.Context, accessToken string) (*TOTPSetup, error)
+	VerifyTOTP(ctx context.Context, req *VerifyTOTPRequest) error
+	EnableSMSMFA(ctx context.Context, accessToken, phoneNumber string) error
+	RespondToMFAChallenge(ctx context.Context, req *MFAChallengeResponse) (*AuthResult, error)
+	DisableMFA(ctx context.Context, userID string) error
 }
 
 // User represents a generic user from any auth provider
@@ -63,16 +71,34 @@ const (
 	UserStatusSuspended UserStatus = "suspended"
 )
 
-// AuthResult contains authentication response data
+// MFAMethod represents a second factor a user can sign in with
+type MFAMethod string
+
+const (
+	// MFAMethodTOTP uses time-based one-time passwords from an authenticator app
+	MFAMethodTOTP MFAMethod = "totp"
+	// MFAMethodSMS uses one-time passwords sent by text message
+	MFAMethodSMS MFAMethod = "sms"
+)
+
+// AuthResult contains authentication response data. When the user must answer an MFA challenge to complete the
+// authentication, only Challenge is set.
 //
 //nolint:revive // AuthResult is a well-established name in the codebase
 type AuthResult struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
-	IDToken      string `json:"id_token,omitempty"`
-	TokenType    string `json:"token_type"`
-	ExpiresIn    int    `json:"expires_in"`
-	User         *User  `json:"user"`
+	AccessToken  string        `json:"access_token"`
+	RefreshToken string        `json:"refresh_token"`
+	IDToken      string        `json:"id_token,omitempty"`
+	TokenType    string        `json:"token_type"`
+	ExpiresIn    int           `json:"expires_in"`
+	User         *User         `json:"user"`
+	Challenge    *MFAChallenge `json:"challenge,omitempty"`
+}
+
+// MFAChallenge represents the second factor a user must provide to complete their authentication
+type MFAChallenge struct {
+	Method  MFAMethod `json:"method"`
+	Session string    `json:"session"`
 }
 
 // TokenClaims represents validated token claims
@@ -135,3 +161,31 @@ type PasswordResetRequest struct {
 	ConfirmationCode string `json:"confirmation_code"`
 	NewPassword      string `json:"new_password"`
 }
+
+// ChangePasswordRequest represents the request of a signed-in user to change their password
+type ChangePasswordRequest struct {
+	AccessToken      string `json:"access_token"`
+	PreviousPassword string `json:"previous_password"`
+	ProposedPassword string `json:"proposed_password"`
+}
+
+// TOTPSetup contains the secret an authenticator app generates one-time passwords from
+type TOTPSetup struct {
+	SecretCode string `json:"secret_code"`
+}
+
+// VerifyTOTPRequest represents the request to verify a one-time password from a newly set up authenticator app,
+// making TOTP the user's preferred second factor
+type VerifyTOTPRequest struct {
+	AccessToken string `json:"access_token"`
+	Code        string `json:"code"`
+	DeviceName  string `json:"device_name,omitempty"`
+}
+
+// MFAChallengeResponse represents the answer to an MFA challenge
+type MFAChallengeResponse struct {
+	Username string    `json:"username"`
+	Method   MFAMethod `json:"method"`
+	Session  string    `json:"session"`
+	Code     string    `json:"code"`
+}
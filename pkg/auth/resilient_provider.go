@@ -0,0 +1,163 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/resilience"
+)
+
+// ResilientProvider guards the calls to another AuthProvider with a circuit breaker. Reads, sign ins and updates
+// that set the same state again are retried when they fail transiently; calls consuming a code or session, sending
+// a message or creating something aren't, since their response may have been lost after they took effect.
+type ResilientProvider struct {
+	provider AuthProvider
+	guard    *resilience.Guard
+}
+
+// NewResilientProvider creates an auth provider guarding the calls to provider with guard
+func NewResilientProvider(provider AuthProvider, guard *resilience.Guard) AuthProvider {
+	return &ResilientProvider{
+		provider: provider,
+		guard:    guard,
+	}
+}
+
+// CreateUser creates a new user
+func (p *ResilientProvider) CreateUser(ctx context.Context, req *CreateUserRequest) (*User, error) {
+	return resilience.CallOnce(ctx, p.guard, func(ctx context.Context) (*User, error) {
+		return p.provider.CreateUser(ctx, req)
+	})
+}
+
+// GetUser retrieves a user by ID
+func (p *ResilientProvider) GetUser(ctx context.Context, userID string) (*User, error) {
+	return resilience.Call(ctx, p.guard, func(ctx context.Context) (*User, error) {
+		return p.provider.GetUser(ctx, userID)
+	})
+}
+
+// GetUserByEmail retrieves a user by email
+func (p *ResilientProvider) GetUserByEmail(ctx context.Context, email string) (*User, error) {
+	return resilience.Call(ctx, p.guard, func(ctx context.Context) (*User, error) {
+		return p.provider.GetUserByEmail(ctx, email)
+	})
+}
+
+// UpdateUser updates a user's attributes
+func (p *ResilientProvider) UpdateUser(ctx context.Context, userID string, req *UpdateUserRequest) (*User, error) {
+	return resilience.Call(ctx, p.guard, func(ctx context.Context) (*User, error) {
+		return p.provider.UpdateUser(ctx, userID, req)
+	})
+}
+
+// DeleteUser deletes a user
+func (p *ResilientProvider) DeleteUser(ctx context.Context, userID string) error {
+	return p.guard.DoOnce(ctx, func(ctx context.Context) error {
+		return p.provider.DeleteUser(ctx, userID)
+	})
+}
+
+// ListUsers lists users
+func (p *ResilientProvider) ListUsers(ctx context.Context, req *ListUsersRequest) (*ListUsersResponse, error) {
+	return resilience.Call(ctx, p.guard, func(ctx context.Context) (*ListUsersResponse, error) {
+		return p.provider.ListUsers(ctx, req)
+	})
+}
+
+// SignUp registers a new user
+func (p *ResilientProvider) SignUp(ctx context.Context, req *SignUpRequest) (*AuthResult, error) {
+	return resilience.CallOnce(ctx, p.guard, func(ctx context.Context) (*AuthResult, error) {
+		return p.provider.SignUp(ctx, req)
+	})
+}
+
+// SignIn authenticates a user
+func (p *ResilientProvider) SignIn(ctx context.Context, req *SignInRequest) (*AuthResult, error) {
+	return resilience.Call(ctx, p.guard, func(ctx context.Context) (*AuthResult, error) {
+		return p.provider.SignIn(ctx, req)
+	})
+}
+
+// RefreshToken issues new tokens from a refresh token
+func (p *ResilientProvider) RefreshToken(ctx context.Context, refreshToken string) (*AuthResult, error) {
+	return resilience.Call(ctx, p.guard, func(ctx context.Context) (*AuthResult, error) {
+		return p.provider.RefreshToken(ctx, refreshToken)
+	})
+}
+
+// SignOut signs a user out of all their sessions
+func (p *ResilientProvider) SignOut(ctx context.Context, accessToken string) error {
+	return p.guard.DoOnce(ctx, func(ctx context.Context) error {
+		return p.provider.SignOut(ctx, accessToken)
+	})
+}
+
+// ConfirmSignUp confirms a user's email address
+func (p *ResilientProvider) ConfirmSignUp(ctx context.Context, username, confirmationCode string) error {
+	return p.guard.DoOnce(ctx, func(ctx context.Context) error {
+		return p.provider.ConfirmSignUp(ctx, username, confirmationCode)
+	})
+}
+
+// ValidateToken validates an access token and returns its claims
+func (p *ResilientProvider) ValidateToken(ctx context.Context, token string) (*TokenClaims, error) {
+	return resilience.Call(ctx, p.guard, func(ctx context.Context) (*TokenClaims, error) {
+		return p.provider.ValidateToken(ctx, token)
+	})
+}
+
+// ResetPassword sends a password reset code to a user
+func (p *ResilientProvider) ResetPassword(ctx context.Context, email string) error {
+	return p.guard.DoOnce(ctx, func(ctx context.Context) error {
+		return p.provider.ResetPassword(ctx, email)
+	})
+}
+
+// ConfirmPasswordReset sets a new password with a reset code
+func (p *ResilientProvider) ConfirmPasswordReset(ctx context.Context, req *PasswordResetRequest) error {
+	return p.guard.DoOnce(ctx, func(ctx context.Context) error {
+		return p.provider.ConfirmPasswordReset(ctx, req)
+	})
+}
+
+// ChangePassword changes a signed in user's password
+func (p *ResilientProvider) ChangePassword(ctx context.Context, req *ChangePasswordRequest) error {
+	return p.guard.DoOnce(ctx, func(ctx context.Context) error {
+		return p.provider.ChangePassword(ctx, req)
+	})
+}
+
+// SetupTOTP starts associating an authenticator app with a user
+func (p *ResilientProvider) SetupTOTP(ctx context.Context, accessToken string) (*TOTPSetup, error) {
+	return resilience.CallOnce(ctx, p.guard, func(ctx context.Context) (*TOTPSetup, error) {
+		return p.provider.SetupTOTP(ctx, accessToken)
+	})
+}
+
+// VerifyTOTP verifies a code of the authenticator app being associated and enables it
+func (p *ResilientProvider) VerifyTOTP(ctx context.Context, req *VerifyTOTPRequest) error {
+	return p.guard.DoOnce(ctx, func(ctx context.Context) error {
+		return p.provider.VerifyTOTP(ctx, req)
+	})
+}
+
+// EnableSMSMFA enables text message MFA on a phone number
+func (p *ResilientProvider) EnableSMSMFA(ctx context.Context, accessToken, phoneNumber string) error {
+	return p.guard.DoOnce(ctx, func(ctx context.Context) error {
+		return p.provider.EnableSMSMFA(ctx, accessToken, phoneNumber)
+	})
+}
+
+// RespondToMFAChallenge completes a sign in with a second factor code
+func (p *ResilientProvider) RespondToMFAChallenge(ctx context.Context, req *MFAChallengeResponse) (*AuthResult, error) {
+	return resilience.CallOnce(ctx, p.guard, func(ctx context.Context) (*AuthResult, error) {
+		return p.provider.RespondToMFAChallenge(ctx, req)
+	})
+}
+
+// DisableMFA disables every second factor of a user
+func (p *ResilientProvider) DisableMFA(ctx context.Context, userID string) error {
+	return p.guard.Do(ctx, func(ctx context.Context) error {
+		return p.provider.DisableMFA(ctx, userID)
+	})
+}
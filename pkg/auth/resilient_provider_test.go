@@ -0,0 +1,41 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/smithy-go"
+	"github.com/golang/mock/gomock"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/auth"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/auth/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/resilience"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResilientProvider(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	inner := mocks.NewMockAuthProvider(ctrl)
+	guard := resilience.NewGuard("cognito", resilience.Policy{MaxAttempts: 3, FailureThreshold: 5, OpenTimeout: time.Minute}, resilience.AWSTransient, nil)
+	provider := auth.NewResilientProvider(inner, guard)
+	throttled := &smithy.GenericAPIError{Code: "TooManyRequestsException"}
+
+	// Token validations are retried when throttled
+	inner.EXPECT().ValidateToken(gomock.Any(), "token").Return(nil, throttled)
+	inner.EXPECT().ValidateToken(gomock.Any(), "token").Return(&auth.TokenClaims{UserID: "user-1"}, nil)
+	claims, err := provider.ValidateToken(context.Background(), "token")
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims.UserID)
+
+	// Sign ups aren't retried, nor are answers of Cognito
+	inner.EXPECT().SignUp(gomock.Any(), gomock.Any()).Return(nil, throttled)
+	_, err = provider.SignUp(context.Background(), &auth.SignUpRequest{Email: "dev@example.com"})
+	assert.ErrorIs(t, err, throttled)
+
+	inner.EXPECT().GetUser(gomock.Any(), "user-2").Return(nil, auth.ErrUserNotFound)
+	_, err = provider.GetUser(context.Background(), "user-2")
+	assert.ErrorIs(t, err, auth.ErrUserNotFound)
+}
@@ -0,0 +1,21 @@
+// Package cache caches hot reads in front of the database.
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Cache stores values under keys for a limited time
+//
+//go:generate mockgen -destination=./mocks/mock_cache.go -mock_names=Cache=MockCache -package=mocks . Cache
+type Cache interface {
+	// Get returns the value stored under key, reporting whether it was found
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+
+	// Set stores value under key until ttl elapses
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// Delete removes the values stored under keys
+	Delete(ctx context.Context, keys ...string) error
+}
@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisCache implements Cache using Redis
+type RedisCache struct {
+	client *redis.Client
+}
+
+// RedisOptions describes the Redis server a cache connects to
+type RedisOptions struct {
+	Address  string // host:port
+	Password string
+	DB       int
+	TLS      bool          // Encrypts the connection, required when the server enforces in-transit encryption
+	Timeout  time.Duration // Timeout of each command
+}
+
+// NewRedisCache creates a new Redis cache. Commands aren't retried and fail after the timeout rather than waiting on
+// an unreachable server, so callers can fall back to the database.
+func NewRedisCache(opts RedisOptions) *RedisCache {
+	clientOpts := &redis.Options{
+		Addr:         opts.Address,
+		Password:     opts.Password,
+		DB:           opts.DB,
+		DialTimeout:  opts.Timeout,
+		ReadTimeout:  opts.Timeout,
+		WriteTimeout: opts.Timeout,
+		MaxRetries:   -1,
+	}
+	if opts.TLS {
+		clientOpts.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	return NewRedisCacheWithClient(redis.NewClient(clientOpts))
+}
+
+// NewRedisCacheWithClient creates a new Redis cache with an existing client
+func NewRedisCacheWithClient(client *redis.Client) *RedisCache {
+	return &RedisCache{client: client}
+}
+
+// Ping checks that the server is reachable
+func (c *RedisCache) Ping(ctx context.Context) error {
+	if err := c.client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("failed to ping redis: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns the value stored under key, reporting whether it was found
+func (c *RedisCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := c.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get cached value: %w", err)
+	}
+
+	return value, true, nil
+}
+
+// Set stores value under key until ttl elapses
+func (c *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := c.client.Set(ctx, key, value, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set cached value: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes the values stored under keys
+func (c *RedisCache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	if err := c.client.Del(ctx, keys...).Err(); err != nil {
+		return fmt.Errorf("failed to delete cached values: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the connections to the server
+func (c *RedisCache) Close() error {
+	return c.client.Close()
+}
@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// newAgentsCommand creates the agents command group
+func newAgentsCommand(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "agents",
+		Short: "Manage agents",
+	}
+
+	cmd.AddCommand(newAgentsRebuildCommand(opts))
+
+	return cmd
+}
+
+// newAgentsRebuildCommand creates the agents rebuild command
+func newAgentsRebuildCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rebuild AGENT_ID",
+		Short: "Re-provision an agent's AI infrastructure",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := opts.printer(cmd)
+			if err != nil {
+				return err
+			}
+
+			response, err := opts.apiClient().RebuildAgent(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+
+			return printer.Print(response, []string{"AGENT ID", "VERSION", "KNOWLEDGE BASE", "STATUS", "UPDATED"}, [][]string{
+				{response.AgentID, response.AgentVersion, response.KnowledgeBaseID, response.Status, response.UpdatedAt.Format(time.RFC3339)},
+			})
+		},
+	}
+}
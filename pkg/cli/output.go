@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Supported output formats
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+)
+
+// Printer renders command results as an aligned table or as JSON
+type Printer struct {
+	out    io.Writer
+	format string
+}
+
+// NewPrinter creates a new Printer for the given format
+func NewPrinter(out io.Writer, format string) (*Printer, error) {
+	if format != OutputTable && format != OutputJSON {
+		return nil, fmt.Errorf("unsupported output format %q: must be %s or %s", format, OutputTable, OutputJSON)
+	}
+	return &Printer{out: out, format: format}, nil
+}
+
+// Print writes value as indented JSON in JSON mode, or writes the header and rows as a table otherwise
+func (p *Printer) Print(value any, header []string, rows [][]string) error {
+	if p.format == OutputJSON {
+		encoder := json.NewEncoder(p.out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	}
+
+	writer := tabwriter.NewWriter(p.out, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(writer, strings.Join(header, "\t")); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if _, err := fmt.Fprintln(writer, strings.Join(row, "\t")); err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// newProjectsCommand creates the projects command group
+func newProjectsCommand(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "projects",
+		Short: "Manage projects",
+	}
+
+	cmd.AddCommand(newProjectsListCommand(opts))
+
+	return cmd
+}
+
+// newProjectsListCommand creates the projects list command
+func newProjectsListCommand(opts *options) *cobra.Command {
+	var maxResults int
+	var nextToken string
+	var tagFilter map[string]string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List projects",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			printer, err := opts.printer(cmd)
+			if err != nil {
+				return err
+			}
+
+			request := models.ListProjectsRequest{TagFilter: tagFilter}
+			if maxResults > 0 {
+				request.MaxResults = &maxResults
+			}
+			if nextToken != "" {
+				request.NextToken = &nextToken
+			}
+
+			response, err := opts.apiClient().ListProjects(cmd.Context(), request)
+			if err != nil {
+				return err
+			}
+
+			rows := make([][]string, 0, len(response.Projects))
+			for _, project := range response.Projects {
+				rows = append(rows, []string{project.ProjectID, project.Name, project.CreatedAt, formatTags(project.Tags)})
+			}
+
+			return printer.Print(response, []string{"PROJECT ID", "NAME", "CREATED", "TAGS"}, rows)
+		},
+	}
+
+	cmd.Flags().IntVar(&maxResults, "max-results", 0, "maximum number of projects to return")
+	cmd.Flags().StringVar(&nextToken, "next-token", "", "pagination token from a previous call")
+	cmd.Flags().StringToStringVar(&tagFilter, "tag", nil, "only list projects with these tags (key=value, repeatable)")
+
+	return cmd
+}
+
+// formatTags renders tags as a stable comma separated key=value list
+func formatTags(tags map[string]string) string {
+	pairs := make([]string, 0, len(tags))
+	for key, value := range tags {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
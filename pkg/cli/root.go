@@ -0,0 +1,70 @@
+// Package cli implements the refactorctl admin command line interface on top of the client SDK
+package cli
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/client"
+)
+
+// Environment variables read by the CLI when flags are not given
+const (
+	EnvServerURL = "REFACTOR_API_URL"
+	EnvAPIKey    = "REFACTOR_API_KEY"
+)
+
+// DefaultServerURL is used when neither --server nor REFACTOR_API_URL is set
+const DefaultServerURL = "http://localhost:8080"
+
+// options holds the global flags shared by all subcommands
+type options struct {
+	serverURL string
+	apiKey    string
+	output    string
+}
+
+// apiClient builds an API client from the global flags
+func (o *options) apiClient() *client.Client {
+	return client.New(client.Config{
+		BaseURL: o.serverURL,
+		APIKey:  o.apiKey,
+	})
+}
+
+// printer builds an output printer for the command from the global flags
+func (o *options) printer(cmd *cobra.Command) (*Printer, error) {
+	return NewPrinter(cmd.OutOrStdout(), o.output)
+}
+
+// NewRootCommand creates the root command with all subcommands attached
+func NewRootCommand() *cobra.Command {
+	opts := &options{}
+
+	root := &cobra.Command{
+		Use:           "refactorctl",
+		Short:         "Operate the code refactoring service",
+		Long:          "refactorctl talks to the code refactoring service HTTP API so operators and CI scripts do not have to hand-craft requests.",
+		SilenceUsage:  true,
+		SilenceErrors: false,
+	}
+
+	serverURL := os.Getenv(EnvServerURL)
+	if serverURL == "" {
+		serverURL = DefaultServerURL
+	}
+
+	root.PersistentFlags().StringVar(&opts.serverURL, "server", serverURL, "service base URL (env "+EnvServerURL+")")
+	root.PersistentFlags().StringVar(&opts.apiKey, "api-key", os.Getenv(EnvAPIKey), "API key sent as a bearer token (env "+EnvAPIKey+")")
+	root.PersistentFlags().StringVarP(&opts.output, "output", "o", OutputTable, "output format: table or json")
+
+	root.AddCommand(
+		newProjectsCommand(opts),
+		newTasksCommand(opts),
+		newAgentsCommand(opts),
+		newUsersCommand(opts),
+	)
+
+	return root
+}
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+func executeCommand(t *testing.T, serverURL string, args ...string) (string, error) {
+	t.Helper()
+
+	root := NewRootCommand()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs(append([]string{"--server", serverURL, "--api-key", "secret"}, args...))
+
+	err := root.Execute()
+	return out.String(), err
+}
+
+func TestProjectsList_Table(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "prod", r.URL.Query().Get("tag_filter[env]"))
+		_ = json.NewEncoder(w).Encode(models.ListProjectsResponse{
+			Projects: []models.ProjectSummary{{ProjectID: "proj-1", Name: "payments", Tags: map[string]string{"env": "prod"}}},
+		})
+	}))
+	defer server.Close()
+
+	out, err := executeCommand(t, server.URL, "projects", "list", "--tag", "env=prod")
+
+	require.NoError(t, err)
+	assert.Contains(t, out, "PROJECT ID")
+	assert.Contains(t, out, "proj-1")
+	assert.Contains(t, out, "env=prod")
+}
+
+func TestTasksCreate_JSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v1/projects/proj-1/tasks", r.URL.Path)
+
+		var request models.CreateTaskRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		assert.Equal(t, "agent-1", request.AgentID)
+		assert.Equal(t, models.TaskTypeCodeReview, request.Type)
+
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(models.CreateTaskResponse{TaskID: "task-1", Status: models.TaskStatusPending})
+	}))
+	defer server.Close()
+
+	out, err := executeCommand(t, server.URL, "-o", "json", "tasks", "create",
+		"--project", "proj-1", "--agent", "agent-1", "--type", "code_review",
+		"--title", "Review", "--description", "Review error handling")
+
+	require.NoError(t, err)
+	var response models.CreateTaskResponse
+	require.NoError(t, json.Unmarshal([]byte(out), &response))
+	assert.Equal(t, "task-1", response.TaskID)
+}
+
+func TestTasksTail_StopsAtTerminalStatus(t *testing.T) {
+	statuses := []models.TaskStatus{models.TaskStatusPending, models.TaskStatusInProgress, models.TaskStatusFailed}
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		status := statuses[min(calls, len(statuses)-1)]
+		calls++
+		_ = json.NewEncoder(w).Encode(models.GetTaskResponse{Task: models.Task{TaskID: "task-1", Status: status, UpdatedAt: time.Now()}})
+	}))
+	defer server.Close()
+
+	out, err := executeCommand(t, server.URL, "tasks", "tail", "task-1", "--interval", "1ms")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed")
+	assert.Equal(t, 3, calls)
+	assert.Contains(t, out, "in_progress")
+}
+
+func TestUsersPromote_RejectsUnknownRole(t *testing.T) {
+	_, err := executeCommand(t, "http://127.0.0.1:0", "users", "promote", "user-1", "--role", "Root Admin")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid role")
+}
@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// DefaultTailInterval is how often tasks tail polls the task status
+const DefaultTailInterval = 2 * time.Second
+
+// newTasksCommand creates the tasks command group
+func newTasksCommand(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tasks",
+		Short: "Create and follow tasks",
+	}
+
+	cmd.AddCommand(
+		newTasksCreateCommand(opts),
+		newTasksTailCommand(opts),
+	)
+
+	return cmd
+}
+
+// newTasksCreateCommand creates the tasks create command
+func newTasksCreateCommand(opts *options) *cobra.Command {
+	var request models.CreateTaskRequest
+	var taskType string
+	var codebaseID string
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a task for a project",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			printer, err := opts.printer(cmd)
+			if err != nil {
+				return err
+			}
+
+			request.Type = models.TaskType(taskType)
+			if codebaseID != "" {
+				request.CodebaseID = &codebaseID
+			}
+
+			response, err := opts.apiClient().CreateTask(cmd.Context(), request)
+			if err != nil {
+				return err
+			}
+
+			return printer.Print(response, []string{"TASK ID", "STATUS", "CREATED"}, [][]string{
+				{response.TaskID, string(response.Status), response.CreatedAt.Format(time.RFC3339)},
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&request.ProjectID, "project", "", "project ID (required)")
+	cmd.Flags().StringVar(&request.AgentID, "agent", "", "agent ID (required)")
+	cmd.Flags().StringVar(&codebaseID, "codebase", "", "codebase ID; defaults to all project codebases")
+	cmd.Flags().StringVar(&taskType, "type", string(models.TaskTypeCodeAnalysis), "task type: code_analysis, refactoring, code_review, documentation, custom, dependency_audit, codemod or coverage_gap")
+	cmd.Flags().StringVar(&request.Title, "title", "", "task title (required)")
+	cmd.Flags().StringVar(&request.Description, "description", "", "task instructions (required)")
+	cmd.Flags().StringToStringVar(&request.Tags, "tag", nil, "task tag (key=value, repeatable)")
+	for _, name := range []string{"project", "agent", "title", "description"} {
+		_ = cmd.MarkFlagRequired(name)
+	}
+
+	return cmd
+}
+
+// newTasksTailCommand creates the tasks tail command
+func newTasksTailCommand(opts *options) *cobra.Command {
+	var interval time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "tail TASK_ID",
+		Short: "Follow a task until it finishes",
+		Long:  "Poll a task and print each status change until it completes, fails or is cancelled. Exits non-zero if the task does not complete.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := opts.printer(cmd)
+			if err != nil {
+				return err
+			}
+
+			if interval <= 0 {
+				return errors.New("--interval must be positive")
+			}
+
+			apiClient := opts.apiClient()
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+
+			var lastStatus models.TaskStatus
+			for {
+				task, err := apiClient.GetTask(cmd.Context(), args[0])
+				if err != nil {
+					return err
+				}
+
+				if task.Status != lastStatus {
+					lastStatus = task.Status
+					if err := printer.Print(task, []string{"TIME", "TASK ID", "STATUS"}, [][]string{
+						{task.UpdatedAt.Format(time.RFC3339), task.TaskID, string(task.Status)},
+					}); err != nil {
+						return err
+					}
+				}
+
+				if task.Status.IsTerminal() {
+					if task.Status != models.TaskStatusCompleted {
+						if task.ErrorMessage != nil {
+							return fmt.Errorf("task %s %s: %s", task.TaskID, task.Status, *task.ErrorMessage)
+						}
+						return fmt.Errorf("task %s %s", task.TaskID, task.Status)
+					}
+					return nil
+				}
+
+				select {
+				case <-cmd.Context().Done():
+					return cmd.Context().Err()
+				case <-ticker.C:
+				}
+			}
+		},
+	}
+
+	cmd.Flags().DurationVar(&interval, "interval", DefaultTailInterval, "polling interval")
+
+	return cmd
+}
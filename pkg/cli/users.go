@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// roleNamePattern matches the names of roles, such as security-reviewer
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9-]{0,49}$`)
+
+// newUsersCommand creates the users command group
+func newUsersCommand(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "users",
+		Short: "Manage users",
+	}
+
+	cmd.AddCommand(newUsersPromoteCommand(opts))
+
+	return cmd
+}
+
+// newUsersPromoteCommand creates the users promote command
+func newUsersPromoteCommand(opts *options) *cobra.Command {
+	var role string
+
+	cmd := &cobra.Command{
+		Use:   "promote USER_ID",
+		Short: "Change a user's role (admin by default)",
+		Long:  "Change a user's role (admin by default). The caller needs the user:update permission, and only owners grant or remove the owner role.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := opts.printer(cmd)
+			if err != nil {
+				return err
+			}
+
+			// Custom roles are defined on the server, so only the format of the role name is checked here
+			userRole := models.UserRole(role)
+			if !roleNamePattern.MatchString(role) {
+				return fmt.Errorf("invalid role %q: must be a built-in role such as owner, admin, developer or viewer, or a custom role name", role)
+			}
+
+			response, err := opts.apiClient().UpdateUser(cmd.Context(), models.UpdateUserRequest{UserID: args[0], Role: &userRole})
+			if err != nil {
+				return err
+			}
+
+			if response.User == nil {
+				return fmt.Errorf("service returned no user for %s", args[0])
+			}
+
+			return printer.Print(response, []string{"USER ID", "EMAIL", "ROLE"}, [][]string{
+				{response.User.UserID, response.User.Email, string(response.User.Role)},
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&role, "role", string(models.RoleAdmin), "role to grant: owner, admin, developer, viewer or a custom role")
+
+	return cmd
+}
@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/controllers"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/routes"
+	"github.com/kazemisoroush/code-refactoring-tool/api/services/mocks"
+)
+
+// bearerAuth authenticates the API key the CLI sends as the caller with authID
+type bearerAuth struct {
+	authID string
+}
+
+// Handle sets the caller of requests bearing the API key
+func (a bearerAuth) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ") == "secret" {
+			c.Set(middleware.UserIDContextKey, a.authID)
+		}
+	}
+}
+
+// newUsersServer serves the auth routes the users commands call, with the services mocked
+func newUsersServer(t *testing.T, authService *mocks.MockAuthService, roleService *mocks.MockRoleService) *httptest.Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(middleware.NewErrorHandlerMiddleware().Handle())
+	routes.SetupAuthRoutes(router, controllers.NewAuthController(authService), bearerAuth{authID: "auth-owner"}, roleService)
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestUsersPromote_ChangesRole(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		role models.UserRole
+	}{
+		{name: "admin by default", args: []string{"users", "promote", "user-1"}, role: models.RoleAdmin},
+		{name: "custom role", args: []string{"users", "promote", "user-1", "--role", "security-reviewer"}, role: "security-reviewer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			authService := mocks.NewMockAuthService(ctrl)
+			roleService := mocks.NewMockRoleService(ctrl)
+			roleService.EXPECT().Authorize(gomock.Any(), "auth-owner", "", models.PermissionUserUpdate).Return(nil)
+			// The role reaches the service for the user in the path, changed on behalf of the caller
+			role := tt.role
+			authService.EXPECT().
+				UpdateUser(gomock.Any(), &models.UpdateUserRequest{UserID: "user-1", Role: &role, ActorID: "auth-owner"}).
+				Return(&models.UpdateUserResponse{User: &models.APIUser{UserID: "user-1", Email: "jane@example.com", Role: tt.role}}, nil)
+
+			out, err := executeCommand(t, newUsersServer(t, authService, roleService).URL, tt.args...)
+
+			require.NoError(t, err)
+			assert.Contains(t, out, "jane@example.com")
+			assert.Contains(t, out, string(tt.role))
+		})
+	}
+}
+
+func TestUsersPromote_ReportsRefusal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	authService := mocks.NewMockAuthService(ctrl)
+	roleService := mocks.NewMockRoleService(ctrl)
+	roleService.EXPECT().Authorize(gomock.Any(), "auth-owner", "", models.PermissionUserUpdate).Return(nil)
+	owner := models.RoleOwner
+	authService.EXPECT().
+		UpdateUser(gomock.Any(), &models.UpdateUserRequest{UserID: "user-1", Role: &owner, ActorID: "auth-owner"}).
+		Return(nil, apperrors.Forbidden(apperrors.CodeForbidden, "only an owner can grant or remove the owner role"))
+
+	_, err := executeCommand(t, newUsersServer(t, authService, roleService).URL, "users", "promote", "user-1", "--role", "owner")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "403")
+	assert.Contains(t, err.Error(), "only an owner can grant or remove the owner role")
+}
@@ -0,0 +1,158 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// CreateAgent creates a new agent
+func (c *Client) CreateAgent(ctx context.Context, request models.CreateAgentRequest) (*models.CreateAgentResponse, error) {
+	var response models.CreateAgentResponse
+	if err := c.Do(ctx, http.MethodPost, "/api/v1/agents", nil, request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetAgent retrieves an agent by ID
+func (c *Client) GetAgent(ctx context.Context, agentID string) (*models.GetAgentResponse, error) {
+	var response models.GetAgentResponse
+	if err := c.Do(ctx, http.MethodGet, pathf("/api/v1/agents/%s", agentID), nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// UpdateAgent updates the agent identified by request.AgentID
+func (c *Client) UpdateAgent(ctx context.Context, request models.UpdateAgentRequest) (*models.UpdateAgentResponse, error) {
+	var response models.UpdateAgentResponse
+	if err := c.Do(ctx, http.MethodPut, pathf("/api/v1/agents/%s", request.AgentID), nil, request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// RebuildAgent re-provisions an agent's AI infrastructure
+func (c *Client) RebuildAgent(ctx context.Context, agentID string) (*models.UpdateAgentResponse, error) {
+	var response models.UpdateAgentResponse
+	if err := c.Do(ctx, http.MethodPost, pathf("/api/v1/agents/%s/rebuild", agentID), nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetAgentSyncStatus retrieves the knowledge base sync status of an agent; a zero request.Limit uses the server
+// default
+func (c *Client) GetAgentSyncStatus(ctx context.Context, request models.GetAgentSyncStatusRequest) (*models.AgentSyncStatus, error) {
+	query := url.Values{}
+	if request.Limit > 0 {
+		query.Set("limit", strconv.Itoa(request.Limit))
+	}
+
+	var response models.AgentSyncStatus
+	if err := c.Do(ctx, http.MethodGet, pathf("/api/v1/agents/%s/sync-status", request.AgentID), query, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// SyncAgent starts resyncing an agent's knowledge base; poll GetAgentSyncStatus to follow its progress
+func (c *Client) SyncAgent(ctx context.Context, agentID string) (*models.AgentSyncStatus, error) {
+	var response models.AgentSyncStatus
+	if err := c.Do(ctx, http.MethodPost, pathf("/api/v1/agents/%s/sync", agentID), nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// DeleteAgent deletes an agent by ID
+func (c *Client) DeleteAgent(ctx context.Context, agentID string) (*models.DeleteAgentResponse, error) {
+	var response models.DeleteAgentResponse
+	if err := c.Do(ctx, http.MethodDelete, pathf("/api/v1/agents/%s", agentID), nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListAgents retrieves a single page of agents
+func (c *Client) ListAgents(ctx context.Context, request models.ListAgentsRequest) (*models.ListAgentsResponse, error) {
+	query := url.Values{}
+	setString(query, "next_token", request.NextToken)
+	setInt(query, "max_results", request.MaxResults)
+
+	var response models.ListAgentsResponse
+	if err := c.Do(ctx, http.MethodGet, "/api/v1/agents", query, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// AllAgents iterates over every agent, fetching pages on demand
+func (c *Client) AllAgents(ctx context.Context, request models.ListAgentsRequest) iter.Seq2[models.AgentSummary, error] {
+	return func(yield func(models.AgentSummary, error) bool) {
+		for {
+			page, err := c.ListAgents(ctx, request)
+			if err != nil {
+				yield(models.AgentSummary{}, err)
+				return
+			}
+			for _, agent := range page.Agents {
+				if !yield(agent, nil) {
+					return
+				}
+			}
+			if page.NextToken == "" {
+				return
+			}
+			nextToken := page.NextToken
+			request.NextToken = &nextToken
+		}
+	}
+}
+
+// ListAgentSetups lists the setups that provisioned agent infrastructure, most recent first; an empty request.Status
+// lists setups in every status
+func (c *Client) ListAgentSetups(ctx context.Context, request models.ListAgentSetupsRequest) (*models.ListAgentSetupsResponse, error) {
+	query := url.Values{}
+	if request.Status != "" {
+		query.Set("status", request.Status)
+	}
+
+	var response models.ListAgentSetupsResponse
+	if err := c.Do(ctx, http.MethodGet, "/api/v1/agent-setups", query, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetAgentSetup retrieves an agent setup with the progress of its steps
+func (c *Client) GetAgentSetup(ctx context.Context, setupID string) (*models.AgentSetup, error) {
+	var response models.AgentSetup
+	if err := c.Do(ctx, http.MethodGet, pathf("/api/v1/agent-setups/%s", setupID), nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ResumeAgentSetup retries a failed agent setup from its first incomplete step
+func (c *Client) ResumeAgentSetup(ctx context.Context, setupID string) (*models.AgentSetup, error) {
+	var response models.AgentSetup
+	if err := c.Do(ctx, http.MethodPost, pathf("/api/v1/agent-setups/%s/resume", setupID), nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// TearDownAgentSetup deletes the resources a failed agent setup created
+func (c *Client) TearDownAgentSetup(ctx context.Context, setupID string) (*models.AgentSetup, error) {
+	var response models.AgentSetup
+	if err := c.Do(ctx, http.MethodPost, pathf("/api/v1/agent-setups/%s/teardown", setupID), nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
@@ -0,0 +1,26 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// CreateCampaign starts a campaign that runs one child task per codebase
+func (c *Client) CreateCampaign(ctx context.Context, request models.CreateCampaignRequest) (*models.CreateCampaignResponse, error) {
+	var response models.CreateCampaignResponse
+	if err := c.Do(ctx, http.MethodPost, "/api/v1/campaigns", nil, request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetCampaign retrieves a campaign's progress and the result of each codebase
+func (c *Client) GetCampaign(ctx context.Context, campaignID string) (*models.GetCampaignResponse, error) {
+	var response models.GetCampaignResponse
+	if err := c.Do(ctx, http.MethodGet, pathf("/api/v1/campaigns/%s", campaignID), nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
@@ -0,0 +1,366 @@
+// Package client provides a Go SDK for the code refactoring service REST API.
+// Request and response types are shared with the server through api/models.
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// Default client settings
+const (
+	// DefaultTimeout bounds a single HTTP attempt
+	DefaultTimeout = 30 * time.Second
+	// DefaultMaxRetries is the number of retries after the first attempt
+	DefaultMaxRetries = 3
+	// DefaultInitialBackoff is the delay before the first retry
+	DefaultInitialBackoff = 200 * time.Millisecond
+	// DefaultMaxBackoff caps the delay between retries
+	DefaultMaxBackoff = 5 * time.Second
+)
+
+// Config holds the settings for a Client
+type Config struct {
+	// BaseURL is the service root, e.g. http://localhost:8080
+	BaseURL string
+	// APIKey is sent as a bearer token when set
+	APIKey string
+	// HTTPClient overrides the default HTTP client
+	HTTPClient *http.Client
+	// MaxRetries is the number of retries for idempotent requests; negative disables retries
+	MaxRetries int
+	// InitialBackoff is the delay before the first retry, doubled on every attempt
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between retries
+	MaxBackoff time.Duration
+}
+
+// Client is a typed client for the service REST API
+type Client struct {
+	baseURL        string
+	apiKey         string
+	httpClient     *http.Client
+	maxRetries     int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+}
+
+// New creates a new Client, filling unset config fields with defaults
+func New(config Config) *Client {
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: DefaultTimeout}
+	}
+
+	maxRetries := config.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = DefaultMaxRetries
+	}
+	if maxRetries < 0 {
+		maxRetries = 0
+	}
+
+	initialBackoff := config.InitialBackoff
+	if initialBackoff <= 0 {
+		initialBackoff = DefaultInitialBackoff
+	}
+
+	maxBackoff := config.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultMaxBackoff
+	}
+
+	return &Client{
+		baseURL:        strings.TrimRight(config.BaseURL, "/"),
+		apiKey:         config.APIKey,
+		httpClient:     httpClient,
+		maxRetries:     maxRetries,
+		initialBackoff: initialBackoff,
+		maxBackoff:     maxBackoff,
+	}
+}
+
+// APIError is returned when the service responds with a non-2xx status
+type APIError struct {
+	StatusCode int
+	// Code is the stable machine-readable error code from the problem details body
+	Code    string
+	Message string
+	Details string
+	// Body is the raw response body
+	Body []byte
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	if e.Details != "" {
+		return fmt.Sprintf("api error (%d): %s: %s", e.StatusCode, e.Message, e.Details)
+	}
+	return fmt.Sprintf("api error (%d): %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound reports whether err is an APIError with status 404
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// IsPreconditionFailed reports whether err is an APIError with status 412, meaning the
+// resource changed since it was read and the update should be retried on a fresh copy
+func IsPreconditionFailed(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusPreconditionFailed
+}
+
+// Do sends a request with an optional JSON body and decodes a JSON response into out if non-nil.
+// GET, PUT and DELETE requests are retried with exponential backoff on transport errors and
+// on 429, 502, 503 and 504 responses; POST requests are never retried.
+func (c *Client) Do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	return c.do(ctx, method, path, query, nil, body, out)
+}
+
+// do is Do with additional request headers
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, header http.Header, body, out any) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+	}
+
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	retries := 0
+	if method != http.MethodPost {
+		retries = c.maxRetries
+	}
+
+	backoff := c.initialBackoff
+	for attempt := 0; ; attempt++ {
+		respBody, statusCode, retryAfter, err := c.send(ctx, method, target, header, payload)
+		if err == nil && !retryableStatus(statusCode) {
+			return decodeResponse(statusCode, respBody, out)
+		}
+
+		if attempt >= retries || ctx.Err() != nil {
+			if err != nil {
+				return err
+			}
+			return decodeResponse(statusCode, respBody, out)
+		}
+
+		delay := backoff
+		if retryAfter > 0 {
+			delay = retryAfter
+		}
+		delay = min(delay, c.maxBackoff)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		backoff = min(backoff*2, c.maxBackoff)
+	}
+}
+
+// send performs a single HTTP attempt and returns the body, status and any Retry-After delay
+func (c *Client) send(ctx context.Context, method, target string, header http.Header, payload []byte) ([]byte, int, time.Duration, error) {
+	var reader io.Reader
+	if payload != nil {
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to build request: %w", err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("%s %s failed: %w", method, req.URL.Path, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var retryAfter time.Duration
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		retryAfter = time.Duration(seconds) * time.Second
+	}
+
+	return respBody, resp.StatusCode, retryAfter, nil
+}
+
+// maxExportLineSize bounds a single item of a streamed export
+const maxExportLineSize = 4 << 20
+
+// stream sends a GET request for a newline-delimited JSON export and passes each item to yield until it returns
+// false. Exports are not retried, since their items may already have been consumed.
+func (c *Client) stream(ctx context.Context, path string, query url.Values, yield func(item []byte) bool) error {
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Accept", models.NDJSONContentType)
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("GET %s failed: %w", req.URL.Path, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+		return decodeResponse(resp.StatusCode, body, nil)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxExportLineSize)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		// An export failing partway through ends with the problem that stopped it
+		var exportErr struct {
+			Error *models.ProblemDetails `json:"error"`
+		}
+		if json.Unmarshal(line, &exportErr) == nil && exportErr.Error != nil && exportErr.Error.Status != 0 {
+			return &APIError{
+				StatusCode: exportErr.Error.Status,
+				Code:       exportErr.Error.Code,
+				Message:    exportErr.Error.Title,
+				Details:    exportErr.Error.Detail,
+				Body:       append([]byte(nil), line...),
+			}
+		}
+
+		if !yield(line) {
+			return nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read export: %w", err)
+	}
+
+	return nil
+}
+
+// retryableStatus reports whether a response status is worth retrying
+func retryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// decodeResponse converts an error status into an APIError or decodes a successful body into out
+func decodeResponse(statusCode int, body []byte, out any) error {
+	if statusCode < 200 || statusCode > 299 {
+		apiErr := &APIError{StatusCode: statusCode, Message: http.StatusText(statusCode), Body: body}
+		var problem models.ProblemDetails
+		if json.Unmarshal(body, &problem) == nil && problem.Title != "" {
+			apiErr.Code = problem.Code
+			apiErr.Message = problem.Title
+			apiErr.Details = problem.Detail
+		}
+		return apiErr
+	}
+
+	if out == nil || len(body) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
+
+// pathf builds a request path, escaping every argument as a path segment
+func pathf(format string, args ...string) string {
+	escaped := make([]any, len(args))
+	for i, arg := range args {
+		escaped[i] = url.PathEscape(arg)
+	}
+	return fmt.Sprintf(format, escaped...)
+}
+
+// ifMatch builds the If-Match header for a conditional update, or nil when version is unset
+func ifMatch(version int64) http.Header {
+	if version == 0 {
+		return nil
+	}
+	return http.Header{models.IfMatchHeader: {models.ETag(version)}}
+}
+
+// setString adds a query parameter when value is non-nil and non-empty
+func setString(query url.Values, key string, value *string) {
+	if value != nil && *value != "" {
+		query.Set(key, *value)
+	}
+}
+
+// setInt adds a query parameter when value is non-nil
+func setInt(query url.Values, key string, value *int) {
+	if value != nil {
+		query.Set(key, strconv.Itoa(*value))
+	}
+}
+
+// setMap adds key[name]=value query parameters for every map entry
+func setMap(query url.Values, key string, values map[string]string) {
+	for name, value := range values {
+		query.Set(key+"["+name+"]", value)
+	}
+}
@@ -0,0 +1,209 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+func newTestClient(serverURL string) *Client {
+	return New(Config{
+		BaseURL:        serverURL,
+		APIKey:         "secret",
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+	})
+}
+
+func TestClient_GetProject_SendsAPIKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		assert.Equal(t, "/api/v1/projects/proj-1", r.URL.Path)
+		_ = json.NewEncoder(w).Encode(models.GetProjectResponse{ProjectID: "proj-1", Name: "payments"})
+	}))
+	defer server.Close()
+
+	response, err := newTestClient(server.URL).GetProject(context.Background(), "proj-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, "payments", response.Name)
+}
+
+func TestClient_RetriesIdempotentRequests(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(models.GetTaskResponse{Task: models.Task{TaskID: "task-1"}})
+	}))
+	defer server.Close()
+
+	response, err := newTestClient(server.URL).GetTask(context.Background(), "task-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, "task-1", response.TaskID)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestClient_DoesNotRetryPost(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	_, err := newTestClient(server.URL).CreateTask(context.Background(), models.CreateTaskRequest{ProjectID: "proj-1"})
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestClient_DecodesProblemDetails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", models.ProblemContentType)
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(models.ProblemDetails{
+			Type:   models.ProblemTypeBaseURI + "project_not_found",
+			Title:  "Not Found",
+			Status: http.StatusNotFound,
+			Detail: "project not found",
+			Code:   "project_not_found",
+		})
+	}))
+	defer server.Close()
+
+	_, err := newTestClient(server.URL).GetProject(context.Background(), "proj-missing")
+
+	assert.True(t, IsNotFound(err))
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "project_not_found", apiErr.Code)
+	assert.Contains(t, err.Error(), "project not found")
+}
+
+func TestClient_UpdateProject_SendsIfMatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-Match") != `"3"` {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(models.UpdateProjectResponse{ProjectID: "proj-1", Version: 4})
+	}))
+	defer server.Close()
+	c := newTestClient(server.URL)
+
+	response, err := c.UpdateProject(context.Background(), models.UpdateProjectRequest{ProjectID: "proj-1", ExpectedVersion: 3})
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), response.Version)
+
+	_, err = c.UpdateProject(context.Background(), models.UpdateProjectRequest{ProjectID: "proj-1", ExpectedVersion: 2})
+	assert.True(t, IsPreconditionFailed(err))
+}
+
+func TestClient_AllProjects_FollowsNextToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("next_token") == "" {
+			next := "page-2"
+			_ = json.NewEncoder(w).Encode(models.ListProjectsResponse{
+				Projects:  []models.ProjectSummary{{ProjectID: "proj-1"}, {ProjectID: "proj-2"}},
+				NextToken: &next,
+			})
+			return
+		}
+		assert.Equal(t, "page-2", r.URL.Query().Get("next_token"))
+		_ = json.NewEncoder(w).Encode(models.ListProjectsResponse{
+			Projects: []models.ProjectSummary{{ProjectID: "proj-3"}},
+		})
+	}))
+	defer server.Close()
+
+	var ids []string
+	for project, err := range newTestClient(server.URL).AllProjects(context.Background(), models.ListProjectsRequest{}) {
+		require.NoError(t, err)
+		ids = append(ids, project.ProjectID)
+	}
+
+	assert.Equal(t, []string{"proj-1", "proj-2", "proj-3"}, ids)
+}
+
+func TestClient_AllTasks_AdvancesOffset(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "2", r.URL.Query().Get("limit"))
+		tasks := []models.Task{{TaskID: "task-1"}, {TaskID: "task-2"}}
+		if r.URL.Query().Get("offset") == "2" {
+			tasks = []models.Task{{TaskID: "task-3"}}
+		}
+		_ = json.NewEncoder(w).Encode(models.ListTasksResponse{Tasks: tasks, TotalCount: 3})
+	}))
+	defer server.Close()
+
+	limit := 2
+	var ids []string
+	for task, err := range newTestClient(server.URL).AllTasks(context.Background(), models.ListTasksRequest{ProjectID: "proj-1", Limit: &limit}) {
+		require.NoError(t, err)
+		ids = append(ids, task.TaskID)
+	}
+
+	assert.Equal(t, []string{"task-1", "task-2", "task-3"}, ids)
+}
+
+func TestClient_ExportTasks_StreamsNDJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, models.NDJSONContentType, r.Header.Get("Accept"))
+		assert.Equal(t, "completed", r.URL.Query().Get("status"))
+		assert.Empty(t, r.URL.Query().Get("limit"))
+		w.Header().Set("Content-Type", models.NDJSONContentType)
+		_, _ = w.Write([]byte(`{"task_id":"task-1"}` + "\n" + `{"task_id":"task-2"}` + "\n" +
+			`{"error":{"title":"Gateway Timeout","status":504,"code":"timeout"}}` + "\n"))
+	}))
+	defer server.Close()
+
+	status := models.TaskStatusCompleted
+	var ids []string
+	var exportErr error
+	for task, err := range newTestClient(server.URL).ExportTasks(context.Background(), models.ListTasksRequest{ProjectID: "proj-1", Status: &status}) {
+		if err != nil {
+			exportErr = err
+			break
+		}
+		ids = append(ids, task.TaskID)
+	}
+
+	assert.Equal(t, []string{"task-1", "task-2"}, ids)
+	var apiErr *APIError
+	require.ErrorAs(t, exportErr, &apiErr)
+	assert.Equal(t, http.StatusGatewayTimeout, apiErr.StatusCode)
+	assert.Equal(t, "timeout", apiErr.Code)
+}
+
+func TestClient_CreateTaskBatch_ReturnsItemResultsOnValidationFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		message := "agent not found"
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(models.CreateTaskBatchResponse{
+			Results: []models.TaskBatchItemResult{{Index: 0, Error: &message}},
+		})
+	}))
+	defer server.Close()
+
+	response, err := newTestClient(server.URL).CreateTaskBatch(context.Background(), models.CreateTaskBatchRequest{})
+
+	require.Error(t, err)
+	require.NotNil(t, response)
+	assert.False(t, response.Created)
+	require.Len(t, response.Results, 1)
+}
@@ -0,0 +1,86 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// CreateCodebaseConfig creates a new codebase configuration
+func (c *Client) CreateCodebaseConfig(ctx context.Context, request models.CreateCodebaseConfigRequest) (*models.CreateCodebaseConfigResponse, error) {
+	var response models.CreateCodebaseConfigResponse
+	if err := c.Do(ctx, http.MethodPost, "/api/v1/codebase-configs", nil, request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetCodebaseConfig retrieves a codebase configuration by ID; credentials are redacted by the service
+func (c *Client) GetCodebaseConfig(ctx context.Context, configID string) (*models.GetCodebaseConfigResponse, error) {
+	var response models.GetCodebaseConfigResponse
+	if err := c.Do(ctx, http.MethodGet, pathf("/api/v1/codebase-configs/%s", configID), nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// UpdateCodebaseConfig updates the configuration identified by request.ConfigID. request.ExpectedVersion must be
+// the Version from GetCodebaseConfig; the update fails with a 412 APIError if the configuration changed since.
+func (c *Client) UpdateCodebaseConfig(ctx context.Context, request models.UpdateCodebaseConfigRequest) (*models.UpdateCodebaseConfigResponse, error) {
+	var response models.UpdateCodebaseConfigResponse
+	if err := c.do(ctx, http.MethodPut, pathf("/api/v1/codebase-configs/%s", request.ConfigID), nil, ifMatch(request.ExpectedVersion), request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// DeleteCodebaseConfig deletes a codebase configuration by ID
+func (c *Client) DeleteCodebaseConfig(ctx context.Context, configID string) (*models.DeleteCodebaseConfigResponse, error) {
+	var response models.DeleteCodebaseConfigResponse
+	if err := c.Do(ctx, http.MethodDelete, pathf("/api/v1/codebase-configs/%s", configID), nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListCodebaseConfigs retrieves a single page of codebase configurations
+func (c *Client) ListCodebaseConfigs(ctx context.Context, request models.ListCodebaseConfigsRequest) (*models.ListCodebaseConfigsResponse, error) {
+	query := url.Values{}
+	setString(query, "next_token", request.NextToken)
+	setInt(query, "max_results", request.MaxResults)
+	if request.ProviderFilter != nil {
+		query.Set("provider_filter", request.ProviderFilter.String())
+	}
+	setMap(query, "tag_filter", request.TagFilter)
+
+	var response models.ListCodebaseConfigsResponse
+	if err := c.Do(ctx, http.MethodGet, "/api/v1/codebase-configs", query, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// AllCodebaseConfigs iterates over every codebase configuration matching request, fetching pages on demand
+func (c *Client) AllCodebaseConfigs(ctx context.Context, request models.ListCodebaseConfigsRequest) iter.Seq2[models.CodebaseConfigSummary, error] {
+	return func(yield func(models.CodebaseConfigSummary, error) bool) {
+		for {
+			page, err := c.ListCodebaseConfigs(ctx, request)
+			if err != nil {
+				yield(models.CodebaseConfigSummary{}, err)
+				return
+			}
+			for _, config := range page.Configs {
+				if !yield(config, nil) {
+					return
+				}
+			}
+			if page.NextToken == nil || *page.NextToken == "" {
+				return
+			}
+			request.NextToken = page.NextToken
+		}
+	}
+}
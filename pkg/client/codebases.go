@@ -0,0 +1,179 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// CreateCodebase attaches a codebase to the project identified by request.ProjectID
+func (c *Client) CreateCodebase(ctx context.Context, request models.CreateCodebaseRequest) (*models.CreateCodebaseResponse, error) {
+	var response models.CreateCodebaseResponse
+	if err := c.Do(ctx, http.MethodPost, pathf("/api/v1/projects/%s/codebases", request.ProjectID), nil, request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetCodebase retrieves a codebase by ID
+func (c *Client) GetCodebase(ctx context.Context, codebaseID string) (*models.GetCodebaseResponse, error) {
+	var response models.GetCodebaseResponse
+	if err := c.Do(ctx, http.MethodGet, pathf("/api/v1/codebases/%s", codebaseID), nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// UpdateCodebase updates the codebase identified by request.CodebaseID
+func (c *Client) UpdateCodebase(ctx context.Context, request models.UpdateCodebaseRequest) (*models.UpdateCodebaseResponse, error) {
+	var response models.UpdateCodebaseResponse
+	if err := c.Do(ctx, http.MethodPut, pathf("/api/v1/codebases/%s", request.CodebaseID), nil, request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// DeleteCodebase deletes a codebase by ID
+func (c *Client) DeleteCodebase(ctx context.Context, codebaseID string) (*models.DeleteCodebaseResponse, error) {
+	var response models.DeleteCodebaseResponse
+	if err := c.Do(ctx, http.MethodDelete, pathf("/api/v1/codebases/%s", codebaseID), nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListCodebases retrieves a single page of codebases
+func (c *Client) ListCodebases(ctx context.Context, request models.ListCodebasesRequest) (*models.ListCodebasesResponse, error) {
+	query := url.Values{}
+	setString(query, "project_id", request.ProjectID)
+	setString(query, "tag_filter", request.TagFilter)
+	setString(query, "next_token", request.NextToken)
+	setInt(query, "max_results", request.MaxResults)
+
+	var response models.ListCodebasesResponse
+	if err := c.Do(ctx, http.MethodGet, "/api/v1/codebases", query, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListCodebaseBranches lists the branches of a codebase's repository
+func (c *Client) ListCodebaseBranches(ctx context.Context, codebaseID string) (*models.ListCodebaseBranchesResponse, error) {
+	var response models.ListCodebaseBranchesResponse
+	if err := c.Do(ctx, http.MethodGet, pathf("/api/v1/codebases/%s/branches", codebaseID), nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListCodebaseCommits lists the latest commits of a ref of a codebase's repository
+func (c *Client) ListCodebaseCommits(ctx context.Context, request models.ListCodebaseCommitsRequest) (*models.ListCodebaseCommitsResponse, error) {
+	query := url.Values{}
+	if request.Ref != "" {
+		query.Set("ref", request.Ref)
+	}
+	setInt(query, "limit", request.Limit)
+
+	var response models.ListCodebaseCommitsResponse
+	if err := c.Do(ctx, http.MethodGet, pathf("/api/v1/codebases/%s/commits", request.CodebaseID), query, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListCodebaseFiles lists a directory of a codebase's repository
+func (c *Client) ListCodebaseFiles(ctx context.Context, request models.ListCodebaseFilesRequest) (*models.ListCodebaseFilesResponse, error) {
+	query := url.Values{}
+	if request.Ref != "" {
+		query.Set("ref", request.Ref)
+	}
+	if request.Path != "" {
+		query.Set("path", request.Path)
+	}
+
+	var response models.ListCodebaseFilesResponse
+	if err := c.Do(ctx, http.MethodGet, pathf("/api/v1/codebases/%s/files", request.CodebaseID), query, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListDependencyFindings retrieves the vulnerable dependencies found by a codebase's latest dependency audit
+func (c *Client) ListDependencyFindings(ctx context.Context, request models.ListDependencyFindingsRequest) (*models.ListDependencyFindingsResponse, error) {
+	query := url.Values{}
+	if request.Severity != nil {
+		query.Set("severity", string(*request.Severity))
+	}
+
+	var response models.ListDependencyFindingsResponse
+	if err := c.Do(ctx, http.MethodGet, pathf("/api/v1/codebases/%s/dependency-findings", request.CodebaseID), query, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetCodeMetrics retrieves the maintainability metrics snapshots of a codebase and their trend
+func (c *Client) GetCodeMetrics(ctx context.Context, request models.GetCodeMetricsRequest) (*models.GetCodeMetricsResponse, error) {
+	query := url.Values{}
+	if request.Since != "" {
+		query.Set("since", request.Since)
+	}
+
+	var response models.GetCodeMetricsResponse
+	if err := c.Do(ctx, http.MethodGet, pathf("/api/v1/codebases/%s/metrics", request.CodebaseID), query, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ScanCodebase scans a codebase for committed secrets and incompatible licenses, blocking its ingestion on high
+// severity findings
+func (c *Client) ScanCodebase(ctx context.Context, codebaseID string) (*models.ScanCodebaseResponse, error) {
+	var response models.ScanCodebaseResponse
+	if err := c.Do(ctx, http.MethodPost, pathf("/api/v1/codebases/%s/scan", codebaseID), nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListScanFindings retrieves the findings of a codebase's latest ingestion scan
+func (c *Client) ListScanFindings(ctx context.Context, request models.ListScanFindingsRequest) (*models.ListScanFindingsResponse, error) {
+	query := url.Values{}
+	if request.Kind != nil {
+		query.Set("kind", string(*request.Kind))
+	}
+	if request.Severity != nil {
+		query.Set("severity", string(*request.Severity))
+	}
+
+	var response models.ListScanFindingsResponse
+	if err := c.Do(ctx, http.MethodGet, pathf("/api/v1/codebases/%s/scan-findings", request.CodebaseID), query, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// AllCodebases iterates over every codebase matching request, fetching pages on demand
+func (c *Client) AllCodebases(ctx context.Context, request models.ListCodebasesRequest) iter.Seq2[models.CodebaseSummary, error] {
+	return func(yield func(models.CodebaseSummary, error) bool) {
+		for {
+			page, err := c.ListCodebases(ctx, request)
+			if err != nil {
+				yield(models.CodebaseSummary{}, err)
+				return
+			}
+			for _, codebase := range page.Codebases {
+				if !yield(codebase, nil) {
+					return
+				}
+			}
+			if page.NextToken == nil || *page.NextToken == "" {
+				return
+			}
+			request.NextToken = page.NextToken
+		}
+	}
+}
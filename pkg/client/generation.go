@@ -0,0 +1,18 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// GeneratePRDescription describes a diff, or the diff of a task, as a pull request title and body with a changelog
+// entry, without creating a task
+func (c *Client) GeneratePRDescription(ctx context.Context, request models.GeneratePRDescriptionRequest) (*models.PRDescription, error) {
+	var response models.PRDescription
+	if err := c.Do(ctx, http.MethodPost, "/api/v1/generate/pr-description", nil, request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
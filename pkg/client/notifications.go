@@ -0,0 +1,82 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// CreateNotificationChannel creates a notification channel owned by the authenticated user
+func (c *Client) CreateNotificationChannel(ctx context.Context, request models.CreateNotificationChannelRequest) (*models.NotificationChannelResponse, error) {
+	var response models.NotificationChannelResponse
+	if err := c.Do(ctx, http.MethodPost, "/api/v1/notifications/channels", nil, request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetNotificationChannel retrieves one of the authenticated user's notification channels by ID
+func (c *Client) GetNotificationChannel(ctx context.Context, channelID string) (*models.NotificationChannelResponse, error) {
+	var response models.NotificationChannelResponse
+	if err := c.Do(ctx, http.MethodGet, pathf("/api/v1/notifications/channels/%s", channelID), nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// UpdateNotificationChannel updates the channel identified by request.ChannelID
+func (c *Client) UpdateNotificationChannel(ctx context.Context, request models.UpdateNotificationChannelRequest) (*models.NotificationChannelResponse, error) {
+	var response models.NotificationChannelResponse
+	if err := c.Do(ctx, http.MethodPut, pathf("/api/v1/notifications/channels/%s", request.ChannelID), nil, request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// DeleteNotificationChannel deletes a notification channel by ID
+func (c *Client) DeleteNotificationChannel(ctx context.Context, channelID string) (*models.DeleteNotificationChannelResponse, error) {
+	var response models.DeleteNotificationChannelResponse
+	if err := c.Do(ctx, http.MethodDelete, pathf("/api/v1/notifications/channels/%s", channelID), nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListNotificationChannels lists the authenticated user's notification channels, optionally only those of a project
+func (c *Client) ListNotificationChannels(ctx context.Context, request models.ListNotificationChannelsRequest) (*models.ListNotificationChannelsResponse, error) {
+	query := url.Values{}
+	setString(query, "project_id", &request.ProjectID)
+
+	var response models.ListNotificationChannelsResponse
+	if err := c.Do(ctx, http.MethodGet, "/api/v1/notifications/channels", query, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListNotifications retrieves a page of the authenticated user's inbox notifications, newest first
+func (c *Client) ListNotifications(ctx context.Context, request models.ListNotificationsRequest) (*models.ListNotificationsResponse, error) {
+	query := url.Values{}
+	if request.UnreadOnly {
+		query.Set("unread_only", "true")
+	}
+	setString(query, "next_token", request.NextToken)
+	setInt(query, "max_results", request.MaxResults)
+
+	var response models.ListNotificationsResponse
+	if err := c.Do(ctx, http.MethodGet, "/api/v1/notifications", query, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// MarkNotificationRead marks one of the authenticated user's inbox notifications read
+func (c *Client) MarkNotificationRead(ctx context.Context, notificationID string) (*models.MarkNotificationReadResponse, error) {
+	var response models.MarkNotificationReadResponse
+	if err := c.Do(ctx, http.MethodPost, pathf("/api/v1/notifications/%s/read", notificationID), nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
@@ -0,0 +1,176 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// CreateProject creates a new project
+func (c *Client) CreateProject(ctx context.Context, request models.CreateProjectRequest) (*models.CreateProjectResponse, error) {
+	var response models.CreateProjectResponse
+	if err := c.Do(ctx, http.MethodPost, "/api/v1/projects", nil, request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetProject retrieves a project by ID
+func (c *Client) GetProject(ctx context.Context, projectID string) (*models.GetProjectResponse, error) {
+	var response models.GetProjectResponse
+	if err := c.Do(ctx, http.MethodGet, pathf("/api/v1/projects/%s", projectID), nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// UpdateProject updates the project identified by request.ProjectID. request.ExpectedVersion must be
+// the Version from GetProject; the update fails with a 412 APIError if the project changed since.
+func (c *Client) UpdateProject(ctx context.Context, request models.UpdateProjectRequest) (*models.UpdateProjectResponse, error) {
+	var response models.UpdateProjectResponse
+	if err := c.do(ctx, http.MethodPut, pathf("/api/v1/projects/%s", request.ProjectID), nil, ifMatch(request.ExpectedVersion), request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// DeleteProject deletes a project by ID
+func (c *Client) DeleteProject(ctx context.Context, projectID string) (*models.DeleteProjectResponse, error) {
+	var response models.DeleteProjectResponse
+	if err := c.Do(ctx, http.MethodDelete, pathf("/api/v1/projects/%s", projectID), nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ArchiveProject archives a project, rejecting new tasks and agent syncs until it is unarchived. With
+// request.PurgeArtifacts its agents are deleted along with their AI resources.
+func (c *Client) ArchiveProject(ctx context.Context, request models.ArchiveProjectRequest) (*models.ProjectLifecycleResponse, error) {
+	query := url.Values{}
+	if request.PurgeArtifacts {
+		query.Set("purge_artifacts", "true")
+	}
+
+	var response models.ProjectLifecycleResponse
+	if err := c.Do(ctx, http.MethodPost, pathf("/api/v1/projects/%s/archive", request.ProjectID), query, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// UnarchiveProject returns an archived project to active
+func (c *Client) UnarchiveProject(ctx context.Context, projectID string) (*models.ProjectLifecycleResponse, error) {
+	var response models.ProjectLifecycleResponse
+	if err := c.Do(ctx, http.MethodPost, pathf("/api/v1/projects/%s/unarchive", projectID), nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetProjectSummary retrieves the dashboard summary of a project; a zero request.WindowDays uses the server default
+func (c *Client) GetProjectSummary(ctx context.Context, request models.GetProjectSummaryRequest) (*models.GetProjectSummaryResponse, error) {
+	query := url.Values{}
+	if request.WindowDays > 0 {
+		query.Set("window_days", strconv.Itoa(request.WindowDays))
+	}
+
+	var response models.GetProjectSummaryResponse
+	if err := c.Do(ctx, http.MethodGet, pathf("/api/v1/projects/%s/summary", request.ProjectID), query, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetRedactionPolicy retrieves the redaction policy applied to the repository content of a project's agents
+func (c *Client) GetRedactionPolicy(ctx context.Context, projectID string) (*models.RedactionPolicy, error) {
+	var response models.RedactionPolicy
+	if err := c.Do(ctx, http.MethodGet, pathf("/api/v1/projects/%s/redaction-policy", projectID), nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// UpdateRedactionPolicy replaces the redaction policy of the project identified by request.ProjectID
+func (c *Client) UpdateRedactionPolicy(ctx context.Context, request models.UpdateRedactionPolicyRequest) (*models.RedactionPolicy, error) {
+	var response models.RedactionPolicy
+	if err := c.Do(ctx, http.MethodPut, pathf("/api/v1/projects/%s/redaction-policy", request.ProjectID), nil, request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListRedactionAudits retrieves the redaction counts of a project's most recent agent syncs; a zero request.Limit
+// uses the server default
+func (c *Client) ListRedactionAudits(ctx context.Context, request models.ListRedactionAuditsRequest) (*models.ListRedactionAuditsResponse, error) {
+	query := url.Values{}
+	if request.Limit > 0 {
+		query.Set("limit", strconv.Itoa(request.Limit))
+	}
+
+	var response models.ListRedactionAuditsResponse
+	if err := c.Do(ctx, http.MethodGet, pathf("/api/v1/projects/%s/redaction-audits", request.ProjectID), query, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetAgentResyncSettings retrieves whether new commits on a project's codebases resync the agents built from them
+func (c *Client) GetAgentResyncSettings(ctx context.Context, projectID string) (*models.AgentResyncSettings, error) {
+	var response models.AgentResyncSettings
+	if err := c.Do(ctx, http.MethodGet, pathf("/api/v1/projects/%s/agent-resync", projectID), nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// UpdateAgentResyncSettings opts the project identified by request.ProjectID in or out of agent resyncs
+func (c *Client) UpdateAgentResyncSettings(ctx context.Context, request models.UpdateAgentResyncSettingsRequest) (*models.AgentResyncSettings, error) {
+	var response models.AgentResyncSettings
+	if err := c.Do(ctx, http.MethodPut, pathf("/api/v1/projects/%s/agent-resync", request.ProjectID), nil, request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListProjects retrieves a single page of projects
+func (c *Client) ListProjects(ctx context.Context, request models.ListProjectsRequest) (*models.ListProjectsResponse, error) {
+	query := url.Values{}
+	setString(query, "next_token", request.NextToken)
+	setInt(query, "max_results", request.MaxResults)
+	setMap(query, "tag_filter", request.TagFilter)
+	if request.IncludeArchived {
+		query.Set("include_archived", "true")
+	}
+
+	var response models.ListProjectsResponse
+	if err := c.Do(ctx, http.MethodGet, "/api/v1/projects", query, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// AllProjects iterates over every project matching request, fetching pages on demand
+func (c *Client) AllProjects(ctx context.Context, request models.ListProjectsRequest) iter.Seq2[models.ProjectSummary, error] {
+	return func(yield func(models.ProjectSummary, error) bool) {
+		for {
+			page, err := c.ListProjects(ctx, request)
+			if err != nil {
+				yield(models.ProjectSummary{}, err)
+				return
+			}
+			for _, project := range page.Projects {
+				if !yield(project, nil) {
+					return
+				}
+			}
+			if page.NextToken == nil || *page.NextToken == "" {
+				return
+			}
+			request.NextToken = page.NextToken
+		}
+	}
+}
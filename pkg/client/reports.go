@@ -0,0 +1,24 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// GetTaskReport retrieves task metrics grouped by day, week or project; empty request fields use the server defaults
+func (c *Client) GetTaskReport(ctx context.Context, request models.GetTaskReportRequest) (*models.GetTaskReportResponse, error) {
+	query := url.Values{}
+	setString(query, "project_id", &request.ProjectID)
+	setString(query, "group_by", (*string)(&request.GroupBy))
+	setString(query, "from", &request.From)
+	setString(query, "to", &request.To)
+
+	var response models.GetTaskReportResponse
+	if err := c.Do(ctx, http.MethodGet, "/api/v1/reports/tasks", query, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
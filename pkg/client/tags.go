@@ -0,0 +1,49 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// CreateTagKey registers a tag key; changing the registry requires the tag_key:manage permission
+func (c *Client) CreateTagKey(ctx context.Context, request models.CreateTagKeyRequest) (*models.TagKey, error) {
+	var response models.TagKey
+	if err := c.Do(ctx, http.MethodPost, "/api/v1/tag-keys", nil, request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetTagKey retrieves a registered tag key
+func (c *Client) GetTagKey(ctx context.Context, key string) (*models.TagKey, error) {
+	var response models.TagKey
+	if err := c.Do(ctx, http.MethodGet, pathf("/api/v1/tag-keys/%s", key), nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// UpdateTagKey replaces the rules of the tag key identified by request.Key
+func (c *Client) UpdateTagKey(ctx context.Context, request models.UpdateTagKeyRequest) (*models.TagKey, error) {
+	var response models.TagKey
+	if err := c.Do(ctx, http.MethodPut, pathf("/api/v1/tag-keys/%s", request.Key), nil, request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// DeleteTagKey unregisters a tag key
+func (c *Client) DeleteTagKey(ctx context.Context, key string) error {
+	return c.Do(ctx, http.MethodDelete, pathf("/api/v1/tag-keys/%s", key), nil, nil, nil)
+}
+
+// ListTagKeys retrieves the registered tag keys
+func (c *Client) ListTagKeys(ctx context.Context) (*models.ListTagKeysResponse, error) {
+	var response models.ListTagKeysResponse
+	if err := c.Do(ctx, http.MethodGet, "/api/v1/tag-keys", nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
@@ -0,0 +1,200 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// defaultTaskPageSize is used by AllTasks when the request does not set a limit
+const defaultTaskPageSize = 100
+
+// CreateTask creates a task for the project identified by request.ProjectID
+func (c *Client) CreateTask(ctx context.Context, request models.CreateTaskRequest) (*models.CreateTaskResponse, error) {
+	var response models.CreateTaskResponse
+	if err := c.Do(ctx, http.MethodPost, pathf("/api/v1/projects/%s/tasks", request.ProjectID), nil, request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ExecuteTask creates and runs a task for the project identified by request.ProjectID
+func (c *Client) ExecuteTask(ctx context.Context, request models.ExecuteTaskRequest) (*models.ExecuteTaskResponse, error) {
+	var response models.ExecuteTaskResponse
+	if err := c.Do(ctx, http.MethodPost, pathf("/api/v1/projects/%s/tasks/execute", request.ProjectID), nil, request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetTask retrieves a task by ID
+func (c *Client) GetTask(ctx context.Context, taskID string) (*models.GetTaskResponse, error) {
+	var response models.GetTaskResponse
+	if err := c.Do(ctx, http.MethodGet, pathf("/api/v1/tasks/%s", taskID), nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// UpdateTask updates the task identified by request.TaskID
+func (c *Client) UpdateTask(ctx context.Context, request models.UpdateTaskRequest) (*models.UpdateTaskResponse, error) {
+	var response models.UpdateTaskResponse
+	if err := c.Do(ctx, http.MethodPut, pathf("/api/v1/tasks/%s", request.TaskID), nil, request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// DeleteTask deletes a task by ID
+func (c *Client) DeleteTask(ctx context.Context, taskID string) error {
+	return c.Do(ctx, http.MethodDelete, pathf("/api/v1/tasks/%s", taskID), nil, nil, nil)
+}
+
+// ListTasks retrieves a single page of tasks for the project identified by request.ProjectID
+func (c *Client) ListTasks(ctx context.Context, request models.ListTasksRequest) (*models.ListTasksResponse, error) {
+	query := taskFilterQuery(request)
+	setInt(query, "limit", request.Limit)
+	setInt(query, "offset", request.Offset)
+
+	var response models.ListTasksResponse
+	if err := c.Do(ctx, http.MethodGet, pathf("/api/v1/projects/%s/tasks", request.ProjectID), query, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// AllTasks iterates over every task matching request, advancing the offset page by page
+func (c *Client) AllTasks(ctx context.Context, request models.ListTasksRequest) iter.Seq2[models.Task, error] {
+	return func(yield func(models.Task, error) bool) {
+		limit := defaultTaskPageSize
+		if request.Limit != nil {
+			limit = *request.Limit
+		}
+		offset := 0
+		if request.Offset != nil {
+			offset = *request.Offset
+		}
+
+		for {
+			request.Limit = &limit
+			request.Offset = &offset
+
+			page, err := c.ListTasks(ctx, request)
+			if err != nil {
+				yield(models.Task{}, err)
+				return
+			}
+			for _, task := range page.Tasks {
+				if !yield(task, nil) {
+					return
+				}
+			}
+			offset += len(page.Tasks)
+			if len(page.Tasks) < limit || offset >= page.TotalCount {
+				return
+			}
+		}
+	}
+}
+
+// ExportTasks iterates over every task matching request in a single streamed response, ignoring its limit and
+// offset. The HTTP client timeout bounds the whole export, so large exports need a client with a longer one.
+func (c *Client) ExportTasks(ctx context.Context, request models.ListTasksRequest) iter.Seq2[models.Task, error] {
+	return func(yield func(models.Task, error) bool) {
+		var decodeErr error
+		err := c.stream(ctx, pathf("/api/v1/projects/%s/tasks", request.ProjectID), taskFilterQuery(request), func(item []byte) bool {
+			var task models.Task
+			if decodeErr = json.Unmarshal(item, &task); decodeErr != nil {
+				decodeErr = fmt.Errorf("failed to decode exported task: %w", decodeErr)
+				return false
+			}
+			return yield(task, nil)
+		})
+		if err == nil {
+			err = decodeErr
+		}
+		if err != nil {
+			yield(models.Task{}, err)
+		}
+	}
+}
+
+// taskFilterQuery builds the query parameters filtering the tasks listed by request
+func taskFilterQuery(request models.ListTasksRequest) url.Values {
+	query := url.Values{}
+	if request.Status != nil {
+		query.Set("status", string(*request.Status))
+	}
+	if request.Type != nil {
+		query.Set("type", string(*request.Type))
+	}
+	setString(query, "agent_id", request.AgentID)
+	setMap(query, "tag_filter", request.TagFilter)
+	return query
+}
+
+// CreateTaskBatch creates up to models.MaxTaskBatchSize tasks atomically. When any spec is
+// invalid the service creates nothing and the per-item results are returned alongside the error.
+func (c *Client) CreateTaskBatch(ctx context.Context, request models.CreateTaskBatchRequest) (*models.CreateTaskBatchResponse, error) {
+	var response models.CreateTaskBatchResponse
+	if err := c.Do(ctx, http.MethodPost, "/api/v1/tasks/batch", nil, request, &response); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest &&
+			json.Unmarshal(apiErr.Body, &response) == nil && len(response.Results) > 0 {
+			return &response, err
+		}
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetTaskBatch retrieves the aggregate progress of a task batch
+func (c *Client) GetTaskBatch(ctx context.Context, batchID string) (*models.GetTaskBatchResponse, error) {
+	var response models.GetTaskBatchResponse
+	if err := c.Do(ctx, http.MethodGet, pathf("/api/v1/tasks/batches/%s", batchID), nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// CreateTaskComment comments on the task identified by request.TaskID, starting a review thread or replying to one
+func (c *Client) CreateTaskComment(ctx context.Context, request models.CreateTaskCommentRequest) (*models.TaskComment, error) {
+	var response models.TaskComment
+	if err := c.Do(ctx, http.MethodPost, pathf("/api/v1/tasks/%s/comments", request.TaskID), nil, request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListTaskComments retrieves a task's comments, oldest first
+func (c *Client) ListTaskComments(ctx context.Context, taskID string) (*models.ListTaskCommentsResponse, error) {
+	var response models.ListTaskCommentsResponse
+	if err := c.Do(ctx, http.MethodGet, pathf("/api/v1/tasks/%s/comments", taskID), nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// UpdateTaskComment edits the body of the comment identified by request.TaskID and request.CommentID
+func (c *Client) UpdateTaskComment(ctx context.Context, request models.UpdateTaskCommentRequest) (*models.TaskComment, error) {
+	var response models.TaskComment
+	if err := c.Do(ctx, http.MethodPut, pathf("/api/v1/tasks/%s/comments/%s", request.TaskID, request.CommentID), nil, request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// DeleteTaskComment deletes a task comment along with its replies
+func (c *Client) DeleteTaskComment(ctx context.Context, taskID, commentID string) (*models.DeleteTaskCommentResponse, error) {
+	var response models.DeleteTaskCommentResponse
+	if err := c.Do(ctx, http.MethodDelete, pathf("/api/v1/tasks/%s/comments/%s", taskID, commentID), nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
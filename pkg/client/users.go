@@ -0,0 +1,45 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+// GetUser retrieves a user by ID
+func (c *Client) GetUser(ctx context.Context, userID string) (*models.GetUserResponse, error) {
+	var response models.GetUserResponse
+	if err := c.Do(ctx, http.MethodGet, pathf("/auth/users/%s", userID), nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// UpdateUser updates the user identified by request.UserID
+func (c *Client) UpdateUser(ctx context.Context, request models.UpdateUserRequest) (*models.UpdateUserResponse, error) {
+	var response models.UpdateUserResponse
+	if err := c.Do(ctx, http.MethodPut, pathf("/auth/users/%s", request.UserID), nil, request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// UpdateMe updates the profile of the authenticated user
+func (c *Client) UpdateMe(ctx context.Context, request models.UpdateProfileRequest) (*models.UpdateUserResponse, error) {
+	var response models.UpdateUserResponse
+	if err := c.Do(ctx, http.MethodPut, "/auth/me", nil, request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ChangePassword changes the password of the authenticated user
+func (c *Client) ChangePassword(ctx context.Context, request models.ChangePasswordRequest) error {
+	return c.Do(ctx, http.MethodPost, "/auth/me/password", nil, request, nil)
+}
+
+// DeleteMe deletes the account of the authenticated user, confirmed by its email address
+func (c *Client) DeleteMe(ctx context.Context, confirmEmail string) error {
+	return c.Do(ctx, http.MethodDelete, "/auth/me", nil, models.DeleteAccountRequest{ConfirmEmail: confirmEmail}, nil)
+}
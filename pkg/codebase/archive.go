@@ -0,0 +1,223 @@
+package codebase
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ArchiveExtensions are the file extensions of the archives a codebase can be uploaded as
+var ArchiveExtensions = []string{".zip", ".tar.gz", ".tgz", ".tar"}
+
+// ErrInvalidArchive is returned for archives that can't be read or hold entries outside their root
+var ErrInvalidArchive = errors.New("invalid archive")
+
+// ErrArchiveTooLarge is returned for archives whose files add up to more than the extraction limit
+var ErrArchiveTooLarge = errors.New("archive extracts to more than the size limit")
+
+// ArchiveExtension returns the archive extension name ends with, ignoring case, or false when it names no supported
+// archive
+func ArchiveExtension(name string) (string, bool) {
+	lower := strings.ToLower(name)
+	for _, extension := range ArchiveExtensions {
+		if strings.HasSuffix(lower, extension) {
+			return extension, true
+		}
+	}
+	return "", false
+}
+
+// ExtractArchive extracts the regular files and directories of the archive at archivePath, in the format of its
+// extension, into dir. Links and special files are skipped. When the archive wraps its content in a single top level
+// directory, as the source archives of git providers do, the content is extracted without it. Extraction stops with
+// ErrArchiveTooLarge once the files add up to more than maxBytes, unless maxBytes is 0.
+func ExtractArchive(archivePath, extension, dir string, maxBytes int64) error {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	extractor := &extractor{dir: dir, remaining: maxBytes, limited: maxBytes > 0}
+	var err error
+	switch extension {
+	case ".zip":
+		err = extractor.zip(archivePath)
+	case ".tar.gz", ".tgz":
+		err = extractor.tar(archivePath, true)
+	case ".tar":
+		err = extractor.tar(archivePath, false)
+	default:
+		return fmt.Errorf("%w: unsupported extension %q", ErrInvalidArchive, extension)
+	}
+	if err != nil {
+		return err
+	}
+
+	return unwrapTopLevelDir(dir)
+}
+
+// extractor writes the entries of an archive under dir, counting the bytes it may still write
+type extractor struct {
+	dir       string
+	remaining int64
+	limited   bool
+}
+
+// zip extracts a zip archive, which is read from its central directory at the end of the file
+func (e *extractor) zip(archivePath string) error {
+	reader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	for _, file := range reader.File {
+		mode := file.Mode()
+		switch {
+		case mode.IsDir():
+			if err := e.mkdir(file.Name); err != nil {
+				return err
+			}
+		case mode.IsRegular():
+			content, err := file.Open()
+			if err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+			}
+			err = e.write(file.Name, content)
+			_ = content.Close()
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// tar extracts a tar archive, gzip compressed when compressed is set
+func (e *extractor) tar(archivePath string, compressed bool) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var stream io.Reader = file
+	if compressed {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+		}
+		defer func() { _ = gz.Close() }()
+		stream = gz
+	}
+
+	reader := tar.NewReader(stream)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := e.mkdir(header.Name); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := e.write(header.Name, reader); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// path returns where the entry called name is extracted, refusing names that would escape the directory
+func (e *extractor) path(name string) (string, error) {
+	name = filepath.FromSlash(strings.TrimSuffix(name, "/"))
+	if !filepath.IsLocal(name) {
+		return "", fmt.Errorf("%w: entry %q is outside the archive root", ErrInvalidArchive, name)
+	}
+	return filepath.Join(e.dir, name), nil
+}
+
+// mkdir creates the directory entry called name
+func (e *extractor) mkdir(name string) error {
+	target, err := e.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(target, 0o750); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	return nil
+}
+
+// write writes the file entry called name with the content, within the bytes left to extract
+func (e *extractor) write(name string, content io.Reader) error {
+	target, err := e.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o640)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	if !e.limited {
+		if _, err := io.Copy(file, content); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+		}
+		return nil
+	}
+
+	// Copying one byte past the limit tells a file ending at the limit from one going past it
+	written, err := io.CopyN(file, content, e.remaining+1)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+	if written > e.remaining {
+		return ErrArchiveTooLarge
+	}
+	e.remaining -= written
+	return nil
+}
+
+// unwrapTopLevelDir moves the content of the only entry of dir up into dir when that entry is a directory
+func unwrapTopLevelDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read directory: %w", err)
+	}
+	if len(entries) != 1 || !entries[0].IsDir() {
+		return nil
+	}
+
+	// The wrapper is renamed first, as it may hold an entry of its own name
+	wrapper := filepath.Join(dir, "."+entries[0].Name()+".unwrap")
+	if err := os.Rename(filepath.Join(dir, entries[0].Name()), wrapper); err != nil {
+		return fmt.Errorf("failed to move %s: %w", entries[0].Name(), err)
+	}
+	content, err := os.ReadDir(wrapper)
+	if err != nil {
+		return fmt.Errorf("failed to read directory: %w", err)
+	}
+	for _, entry := range content {
+		if err := os.Rename(filepath.Join(wrapper, entry.Name()), filepath.Join(dir, entry.Name())); err != nil {
+			return fmt.Errorf("failed to move %s out of %s: %w", entry.Name(), entries[0].Name(), err)
+		}
+	}
+	return os.Remove(wrapper)
+}
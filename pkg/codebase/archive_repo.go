@@ -0,0 +1,127 @@
+package codebase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/objectstore"
+)
+
+// ErrNoRemote is returned for the git operations of codebases uploaded as archives, which have no remote to push to
+var ErrNoRemote = errors.New("codebases uploaded as archives have no git remote")
+
+// ArchiveCodebase represents a codebase uploaded as an archive, such as a ZIP file or a tarball, to an object store.
+// Cloning it downloads and extracts the archive. It has a single revision and no remote, so its git operations fail
+// with ErrNoRemote.
+type ArchiveCodebase struct {
+	store     objectstore.ObjectStore
+	key       string
+	extension string
+	maxBytes  int64
+	path      string
+}
+
+// NewArchiveCodebase creates a new codebase of the archive at uri in the store, extracting at most maxBytes. It
+// returns an error when uri doesn't locate an object of the store with a supported archive extension.
+func NewArchiveCodebase(store objectstore.ObjectStore, uri string, maxBytes int64) (Codebase, error) {
+	// The archive is extracted to a directory named after it, like git repositories are cloned to
+	_, key, _ := objectstore.ParseURI(uri)
+	extension, _ := ArchiveExtension(key)
+	return NewArchiveCodebaseAt(store, uri, maxBytes, path.Base(key[:len(key)-len(extension)]))
+}
+
+// NewArchiveCodebaseAt creates a new codebase of the archive at uri extracted to the given local path, returning an
+// error like NewArchiveCodebase
+func NewArchiveCodebaseAt(store objectstore.ObjectStore, uri string, maxBytes int64, localPath string) (Codebase, error) {
+	_, key, ok := objectstore.ParseURI(uri)
+	if !ok {
+		return nil, fmt.Errorf("%q is not the location of an archive", uri)
+	}
+	extension, ok := ArchiveExtension(key)
+	if !ok {
+		return nil, fmt.Errorf("%q has no supported archive extension", uri)
+	}
+	return &ArchiveCodebase{
+		store:     store,
+		key:       key,
+		extension: extension,
+		maxBytes:  maxBytes,
+		path:      localPath,
+	}, nil
+}
+
+// GetPath returns the local path the archive is extracted to
+func (a *ArchiveCodebase) GetPath() string {
+	return a.path
+}
+
+// Clone downloads the archive and extracts it
+func (a *ArchiveCodebase) Clone(ctx context.Context) error {
+	return a.CloneRevision(ctx, "", "")
+}
+
+// CloneRevision downloads the archive to a temporary file, which zip archives need to be read, and extracts it. An
+// archive has a single revision, so branch and commitSHA are ignored.
+func (a *ArchiveCodebase) CloneRevision(ctx context.Context, _, _ string) error {
+	content, err := a.store.Get(ctx, a.key)
+	if err != nil {
+		return fmt.Errorf("failed to download archive: %w", err)
+	}
+	defer func() { _ = content.Close() }()
+
+	archive, err := os.CreateTemp("", "codebase-*"+a.extension)
+	if err != nil {
+		return fmt.Errorf("failed to create archive file: %w", err)
+	}
+	defer func() {
+		_ = archive.Close()
+		_ = os.Remove(archive.Name())
+	}()
+	if _, err := io.Copy(archive, content); err != nil {
+		return fmt.Errorf("failed to download archive: %w", err)
+	}
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("failed to write archive file: %w", err)
+	}
+
+	return ExtractArchive(archive.Name(), a.extension, a.path, a.maxBytes)
+}
+
+// CheckoutBranch returns ErrNoRemote, as archives have no branches
+func (a *ArchiveCodebase) CheckoutBranch(_ string) error {
+	return ErrNoRemote
+}
+
+// Commit returns ErrNoRemote, as archives aren't git repositories
+func (a *ArchiveCodebase) Commit(_ string) error {
+	return ErrNoRemote
+}
+
+// Push returns ErrNoRemote
+func (a *ArchiveCodebase) Push(_ context.Context) error {
+	return ErrNoRemote
+}
+
+// UpsertPR returns ErrNoRemote
+func (a *ArchiveCodebase) UpsertPR(_ context.Context, _, _, _, _ string) (string, error) {
+	return "", ErrNoRemote
+}
+
+// CreatePR returns ErrNoRemote
+func (a *ArchiveCodebase) CreatePR(_ context.Context, _, _, _, _ string) (string, error) {
+	return "", ErrNoRemote
+}
+
+// UpdatePR returns ErrNoRemote
+func (a *ArchiveCodebase) UpdatePR(_ context.Context, _ int, _, _ string) error {
+	return ErrNoRemote
+}
+
+// Cleanup deletes the extracted archive from the filesystem
+func (a *ArchiveCodebase) Cleanup() error {
+	return removeRepository(a.path)
+}
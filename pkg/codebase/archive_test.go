@@ -0,0 +1,154 @@
+package codebase_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/codebase"
+	objectstoreMocks "github.com/kazemisoroush/code-refactoring-tool/pkg/objectstore/mocks"
+)
+
+// writeZip writes a zip archive of the files, keyed by their name, and returns its path
+func writeZip(t *testing.T, files map[string]string) string {
+	buffer := &bytes.Buffer{}
+	writer := zip.NewWriter(buffer)
+	for name, content := range files {
+		file, err := writer.Create(name)
+		require.NoError(t, err)
+		_, err = file.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+
+	archivePath := filepath.Join(t.TempDir(), "source.zip")
+	require.NoError(t, os.WriteFile(archivePath, buffer.Bytes(), 0o600))
+	return archivePath
+}
+
+// writeTarGz writes a gzip compressed tar archive of the files and a symbolic link, and returns its path
+func writeTarGz(t *testing.T, files map[string]string) string {
+	buffer := &bytes.Buffer{}
+	gz := gzip.NewWriter(buffer)
+	writer := tar.NewWriter(gz)
+	for name, content := range files {
+		require.NoError(t, writer.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(content))}))
+		_, err := writer.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.WriteHeader(&tar.Header{Name: "passwd", Typeflag: tar.TypeSymlink, Linkname: "/etc/passwd"}))
+	require.NoError(t, writer.Close())
+	require.NoError(t, gz.Close())
+
+	archivePath := filepath.Join(t.TempDir(), "source.tar.gz")
+	require.NoError(t, os.WriteFile(archivePath, buffer.Bytes(), 0o600))
+	return archivePath
+}
+
+func TestArchiveExtension(t *testing.T) {
+	for name, expected := range map[string]string{"src.zip": ".zip", "src.TAR.GZ": ".tar.gz", "src.tgz": ".tgz", "src.tar": ".tar"} {
+		extension, ok := codebase.ArchiveExtension(name)
+		assert.True(t, ok, name)
+		assert.Equal(t, expected, extension, name)
+	}
+
+	_, ok := codebase.ArchiveExtension("src.rar")
+	assert.False(t, ok)
+}
+
+func TestExtractArchive_UnwrapsTopLevelDirectory(t *testing.T) {
+	archivePath := writeZip(t, map[string]string{
+		"payments-main/go.mod":          "module payments",
+		"payments-main/pkg/charge.go":   "package pkg",
+		"payments-main/payments-main/x": "nested",
+	})
+	dir := filepath.Join(t.TempDir(), "repo")
+
+	err := codebase.ExtractArchive(archivePath, ".zip", dir, 0)
+
+	require.NoError(t, err)
+	content, err := os.ReadFile(filepath.Join(dir, "pkg", "charge.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "package pkg", string(content))
+	assert.FileExists(t, filepath.Join(dir, "go.mod"))
+	assert.FileExists(t, filepath.Join(dir, "payments-main", "x"))
+}
+
+func TestExtractArchive_TarGzSkipsLinks(t *testing.T) {
+	archivePath := writeTarGz(t, map[string]string{"main.go": "package main", "README.md": "# payments"})
+	dir := t.TempDir()
+
+	err := codebase.ExtractArchive(archivePath, ".tar.gz", dir, 0)
+
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(dir, "main.go"))
+	assert.FileExists(t, filepath.Join(dir, "README.md"))
+	assert.NoFileExists(t, filepath.Join(dir, "passwd"))
+}
+
+func TestExtractArchive_Rejected(t *testing.T) {
+	t.Run("entry outside the root", func(t *testing.T) {
+		archivePath := writeZip(t, map[string]string{"../../etc/cron.d/job": "* * * * * root sh"})
+
+		err := codebase.ExtractArchive(archivePath, ".zip", t.TempDir(), 0)
+
+		assert.ErrorIs(t, err, codebase.ErrInvalidArchive)
+	})
+
+	t.Run("larger than the limit", func(t *testing.T) {
+		archivePath := writeZip(t, map[string]string{"a.go": "package a", "b.go": "package b"})
+
+		err := codebase.ExtractArchive(archivePath, ".zip", t.TempDir(), 12)
+
+		assert.ErrorIs(t, err, codebase.ErrArchiveTooLarge)
+	})
+
+	t.Run("not an archive", func(t *testing.T) {
+		archivePath := filepath.Join(t.TempDir(), "source.zip")
+		require.NoError(t, os.WriteFile(archivePath, []byte("plain text"), 0o600))
+
+		err := codebase.ExtractArchive(archivePath, ".zip", t.TempDir(), 0)
+
+		assert.ErrorIs(t, err, codebase.ErrInvalidArchive)
+	})
+}
+
+func TestArchiveCodebase_Clone(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	archive, err := os.ReadFile(writeZip(t, map[string]string{"main.go": "package main"}))
+	require.NoError(t, err)
+	store := objectstoreMocks.NewMockObjectStore(ctrl)
+	store.EXPECT().Get(gomock.Any(), "codebase-uploads/proj-1/cb-1.zip").Return(io.NopCloser(bytes.NewReader(archive)), nil)
+
+	dir := filepath.Join(t.TempDir(), "repo")
+	repo, err := codebase.NewArchiveCodebaseAt(store, "s3://bucket/codebase-uploads/proj-1/cb-1.zip", 0, dir)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.Clone(context.Background()))
+	assert.FileExists(t, filepath.Join(dir, "main.go"))
+	assert.ErrorIs(t, repo.Push(context.Background()), codebase.ErrNoRemote)
+}
+
+func TestNewArchiveCodebase(t *testing.T) {
+	repo, err := codebase.NewArchiveCodebase(nil, "s3://bucket/codebase-uploads/proj-1/cb-1.tar.gz", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "cb-1", repo.GetPath())
+
+	_, err = codebase.NewArchiveCodebase(nil, "https://github.com/acme/payments", 0)
+	assert.Error(t, err)
+
+	_, err = codebase.NewArchiveCodebase(nil, "s3://bucket/codebase-uploads/proj-1/cb-1.rar", 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,247 @@
+package codebase
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	gitHttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// azureDevOpsAPIVersion is the version of the Azure DevOps REST API called
+const azureDevOpsAPIVersion = "7.1"
+
+// azureDevOpsRequestTimeout bounds each Azure DevOps REST API request
+const azureDevOpsRequestTimeout = 30 * time.Second
+
+// AzureDevOpsCodebase represents an Azure Repos codebase, addressed by its clone URL
+// https://dev.azure.com/{organization}/{project}/_git/{repository} and authenticated with a personal access token.
+type AzureDevOpsCodebase struct {
+	RepoURL    string
+	Token      string
+	Author     string
+	Email      string
+	repo       *git.Repository
+	path       string
+	clone      cloneOptions
+	httpClient *http.Client
+}
+
+// NewAzureDevOpsCodebase creates a new Azure DevOps codebase instance
+func NewAzureDevOpsCodebase(git config.GitConfig) Codebase {
+	return NewAzureDevOpsCodebaseAt(git, path.Base(strings.TrimSuffix(git.CodebaseURL, ".git")))
+}
+
+// NewAzureDevOpsCodebaseAt creates a new Azure DevOps codebase instance cloned to the given local path
+func NewAzureDevOpsCodebaseAt(git config.GitConfig, localPath string) Codebase {
+	return &AzureDevOpsCodebase{
+		RepoURL:    git.CodebaseURL,
+		Token:      git.Token,
+		Author:     git.Author,
+		Email:      git.Email,
+		path:       localPath,
+		clone:      newCloneOptions(git),
+		httpClient: &http.Client{Timeout: azureDevOpsRequestTimeout},
+	}
+}
+
+// GetPath returns the local path of the repository
+func (a *AzureDevOpsCodebase) GetPath() string {
+	return a.path
+}
+
+// Clone clones the repository to the local filesystem
+func (a *AzureDevOpsCodebase) Clone(ctx context.Context) error {
+	return a.CloneRevision(ctx, "", "")
+}
+
+// CloneRevision clones the repository to the local filesystem and checks out commitSHA, or the head of branch
+func (a *AzureDevOpsCodebase) CloneRevision(ctx context.Context, branch, commitSHA string) error {
+	repo, err := cloneRevision(ctx, a.RepoURL, a.path, a.auth(), branch, commitSHA, a.clone)
+	if err != nil {
+		return err
+	}
+	a.repo = repo
+	return nil
+}
+
+// CheckoutBranch creates a new branch and checks it out
+func (a *AzureDevOpsCodebase) CheckoutBranch(branchName string) error {
+	return checkoutNewBranch(a.repo, branchName)
+}
+
+// Commit stages and commits all changes with the provided message
+func (a *AzureDevOpsCodebase) Commit(message string) error {
+	return commitAll(a.repo, message, a.Author, a.Email)
+}
+
+// Push pushes commits to the remote repository
+func (a *AzureDevOpsCodebase) Push(ctx context.Context) error {
+	return a.repo.PushContext(ctx, &git.PushOptions{
+		RemoteURL:  a.RepoURL,
+		RemoteName: "origin",
+		Force:      true,
+		Auth:       a.auth(),
+	})
+}
+
+// azureDevOpsPullRequest is a pull request in Azure DevOps API responses
+type azureDevOpsPullRequest struct {
+	PullRequestID int `json:"pullRequestId"`
+}
+
+// CreatePR creates a new pull request and returns its web URL
+func (a *AzureDevOpsCodebase) CreatePR(ctx context.Context, title, description, sourceBranch, targetBranch string) (string, error) {
+	apiURL, webURL, err := a.repositoryURLs()
+	if err != nil {
+		return "", err
+	}
+
+	body := map[string]string{
+		"sourceRefName": plumbing.NewBranchReferenceName(sourceBranch).String(),
+		"targetRefName": plumbing.NewBranchReferenceName(targetBranch).String(),
+		"title":         title,
+		"description":   description,
+	}
+	var pr azureDevOpsPullRequest
+	if err := a.send(ctx, http.MethodPost, apiURL+"/pullrequests", nil, body, &pr); err != nil {
+		return "", fmt.Errorf("failed to create pull request: %w", err)
+	}
+	return fmt.Sprintf("%s/pullrequest/%d", webURL, pr.PullRequestID), nil
+}
+
+// UpsertPR creates a PR if no active one merges sourceBranch into targetBranch, otherwise updates the existing one
+func (a *AzureDevOpsCodebase) UpsertPR(ctx context.Context, title, description, sourceBranch, targetBranch string) (string, error) {
+	apiURL, _, err := a.repositoryURLs()
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{
+		"searchCriteria.sourceRefName": {plumbing.NewBranchReferenceName(sourceBranch).String()},
+		"searchCriteria.targetRefName": {plumbing.NewBranchReferenceName(targetBranch).String()},
+		"searchCriteria.status":        {"active"},
+	}
+	var prs struct {
+		Value []azureDevOpsPullRequest `json:"value"`
+	}
+	if err := a.send(ctx, http.MethodGet, apiURL+"/pullrequests", query, nil, &prs); err != nil {
+		return "", fmt.Errorf("failed to check existing PRs: %w", err)
+	}
+
+	if len(prs.Value) > 0 {
+		prNumber := prs.Value[0].PullRequestID
+		if err := a.UpdatePR(ctx, prNumber, title, description); err != nil {
+			return "", fmt.Errorf("failed to update existing PR: %w", err)
+		}
+		return fmt.Sprintf("PR #%d updated", prNumber), nil
+	}
+	return a.CreatePR(ctx, title, description, sourceBranch, targetBranch)
+}
+
+// UpdatePR updates an existing pull request's title and description
+func (a *AzureDevOpsCodebase) UpdatePR(ctx context.Context, prNumber int, title, description string) error {
+	apiURL, _, err := a.repositoryURLs()
+	if err != nil {
+		return err
+	}
+
+	body := map[string]string{"title": title, "description": description}
+	if err := a.send(ctx, http.MethodPatch, fmt.Sprintf("%s/pullrequests/%d", apiURL, prNumber), nil, body, nil); err != nil {
+		return fmt.Errorf("failed to update PR: %w", err)
+	}
+	return nil
+}
+
+// Cleanup deletes the repository from the filesystem.
+func (a *AzureDevOpsCodebase) Cleanup() error {
+	return removeRepository(a.path)
+}
+
+// auth authenticates git operations with the personal access token, which Azure DevOps accepts with any username
+func (a *AzureDevOpsCodebase) auth() transport.AuthMethod {
+	if a.Token == "" {
+		return nil
+	}
+	return &gitHttp.BasicAuth{Username: a.Author, Password: a.Token}
+}
+
+// repositoryURLs derives the REST API and web URLs of the repository from its clone URL. Everything before _git is
+// the organization (or Azure DevOps Server collection) and project.
+func (a *AzureDevOpsCodebase) repositoryURLs() (string, string, error) {
+	parsed, err := url.Parse(strings.TrimSuffix(a.RepoURL, ".git"))
+	if err != nil {
+		return "", "", fmt.Errorf("invalid Azure DevOps repo URL: %s", a.RepoURL)
+	}
+	project, repo, found := strings.Cut(strings.Trim(parsed.Path, "/"), "/_git/")
+	if !found || project == "" || repo == "" || strings.Contains(repo, "/") {
+		return "", "", fmt.Errorf("invalid Azure DevOps repo URL: %s", a.RepoURL)
+	}
+
+	// Clone URLs copied from Azure DevOps carry the organization as username
+	base := parsed.Scheme + "://" + parsed.Host + "/" + project
+	return base + "/_apis/git/repositories/" + repo, base + "/_git/" + repo, nil
+}
+
+// send calls the Azure DevOps REST API, encoding body and decoding the response into out when they are set
+func (a *AzureDevOpsCodebase) send(ctx context.Context, method, requestURL string, query url.Values, body, out any) error {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("api-version", azureDevOpsAPIVersion)
+
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, requestURL+"?"+query.Encode(), reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if a.Token != "" {
+		req.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(":"+a.Token)))
+	}
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			slog.WarnContext(ctx, "failed to close response body", "error", err)
+		}
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("azure DevOps returned status %d", resp.StatusCode)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package codebase_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/codebase"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAzureDevOpsCodebase_GetPath(t *testing.T) {
+	r := codebase.NewAzureDevOpsCodebase(config.GitConfig{CodebaseURL: "https://dev.azure.com/acme/billing/_git/payments"})
+
+	assert.Equal(t, "payments", r.GetPath())
+}
+
+func TestAzureDevOpsCodebase_UpsertPR_Create(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/acme/billing/_apis/git/repositories/payments/pullrequests", r.URL.Path)
+		assert.Equal(t, "7.1", r.URL.Query().Get("api-version"))
+		assert.Equal(t, "Basic OnBhdC10b2tlbg==", r.Header.Get("Authorization"))
+
+		switch r.Method {
+		case http.MethodGet:
+			assert.Equal(t, "refs/heads/refactor/jwt", r.URL.Query().Get("searchCriteria.sourceRefName"))
+			assert.Equal(t, "refs/heads/main", r.URL.Query().Get("searchCriteria.targetRefName"))
+			assert.Equal(t, "active", r.URL.Query().Get("searchCriteria.status"))
+			_, _ = w.Write([]byte(`{"value":[],"count":0}`))
+		case http.MethodPost:
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, map[string]string{
+				"sourceRefName": "refs/heads/refactor/jwt",
+				"targetRefName": "refs/heads/main",
+				"title":         "Refactor auth",
+				"description":   "Moves JWT parsing",
+			}, body)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"pullRequestId":17}`))
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+	}))
+	defer server.Close()
+
+	r := codebase.NewAzureDevOpsCodebase(config.GitConfig{CodebaseURL: server.URL + "/acme/billing/_git/payments", Token: "pat-token"})
+
+	prURL, err := r.UpsertPR(context.Background(), "Refactor auth", "Moves JWT parsing", "refactor/jwt", "main")
+
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/acme/billing/_git/payments/pullrequest/17", prURL)
+}
+
+func TestAzureDevOpsCodebase_UpsertPR_Update(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			_, _ = w.Write([]byte(`{"value":[{"pullRequestId":17}],"count":1}`))
+		case http.MethodPatch:
+			assert.Equal(t, "/acme/billing/_apis/git/repositories/payments/pullrequests/17", r.URL.Path)
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, map[string]string{"title": "Refactor auth", "description": "Moves JWT parsing"}, body)
+			_, _ = w.Write([]byte(`{"pullRequestId":17}`))
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+	}))
+	defer server.Close()
+
+	r := codebase.NewAzureDevOpsCodebase(config.GitConfig{CodebaseURL: server.URL + "/acme/billing/_git/payments", Token: "pat-token"})
+
+	result, err := r.UpsertPR(context.Background(), "Refactor auth", "Moves JWT parsing", "refactor/jwt", "main")
+
+	require.NoError(t, err)
+	assert.Equal(t, "PR #17 updated", result)
+}
+
+func TestAzureDevOpsCodebase_CreatePR_InvalidURL(t *testing.T) {
+	r := codebase.NewAzureDevOpsCodebase(config.GitConfig{CodebaseURL: "https://dev.azure.com/acme/payments"})
+
+	_, err := r.CreatePR(context.Background(), "Refactor auth", "", "refactor/jwt", "main")
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,123 @@
+package codebase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// mirrorRefSpecs fetch every branch and tag of a remote into a mirror, replacing the refs that moved
+var mirrorRefSpecs = []gitconfig.RefSpec{"+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*"}
+
+// mirrorLocks holds a lock per mirror directory. Updates take it exclusively, clones from the mirror share it.
+var mirrorLocks sync.Map
+
+// cloneOptions configure how a repository is cloned
+type cloneOptions struct {
+	depth       int      // Commits of history cloned, the full history when 0
+	sparsePaths []string // Directories checked out, the whole tree when empty
+	cacheDir    string   // Directory of the bare mirrors clones are fetched into, none when empty
+	submodules  bool     // Whether submodules are cloned recursively
+	lfsMaxSize  int64    // Most LFS content downloaded into a clone, none when 0
+
+	// Credentials of the HTTPS remotes nested in a repository, by host
+	tokens   map[string]string
+	username string
+}
+
+// newCloneOptions returns the clone options of a codebase's configuration
+func newCloneOptions(git config.GitConfig) cloneOptions {
+	return cloneOptions{
+		depth:       git.CloneDepth,
+		sparsePaths: git.SparsePaths,
+		cacheDir:    git.CloneCacheDir,
+		submodules:  git.Submodules,
+		lfsMaxSize:  git.LFSMaxSize,
+		tokens: map[string]string{
+			"github.com":    git.Token,
+			"dev.azure.com": git.AzureDevOpsToken,
+		},
+		username: git.Author,
+	}
+}
+
+// mirrorDir returns the directory of the mirror of repoURL in cacheDir, named by a hash of the URL
+func mirrorDir(cacheDir, repoURL string) string {
+	sum := sha256.Sum256([]byte(repoURL))
+	return filepath.Join(cacheDir, hex.EncodeToString(sum[:16])+".git")
+}
+
+// mirrorLock returns the lock of a mirror directory
+func mirrorLock(dir string) *sync.RWMutex {
+	lock, _ := mirrorLocks.LoadOrStore(dir, &sync.RWMutex{})
+	return lock.(*sync.RWMutex)
+}
+
+// updateMirror creates the bare mirror of repoURL in cacheDir, or fetches the refs that changed into it, and returns
+// its directory. Mirrors already holding commitSHA aren't fetched.
+func updateMirror(ctx context.Context, cacheDir, repoURL string, auth transport.AuthMethod, commitSHA string) (string, error) {
+	dir := mirrorDir(cacheDir, repoURL)
+	lock := mirrorLock(dir)
+	lock.Lock()
+	defer lock.Unlock()
+
+	repo, err := git.PlainOpen(dir)
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		_, err = git.PlainCloneContext(ctx, dir, true, &git.CloneOptions{URL: repoURL, Auth: auth, Mirror: true})
+		if err != nil {
+			_ = os.RemoveAll(dir)
+			return "", fmt.Errorf("failed to create mirror: %w", err)
+		}
+		return dir, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to open mirror: %w", err)
+	}
+
+	if commitSHA != "" {
+		if _, err := repo.CommitObject(plumbing.NewHash(commitSHA)); err == nil {
+			return dir, nil
+		}
+	}
+	err = repo.FetchContext(ctx, &git.FetchOptions{
+		RemoteName: git.DefaultRemoteName,
+		RemoteURL:  repoURL,
+		Auth:       auth,
+		RefSpecs:   mirrorRefSpecs,
+		Force:      true,
+		Prune:      true,
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return "", fmt.Errorf("failed to fetch into mirror: %w", err)
+	}
+	return dir, nil
+}
+
+// resetOrigin points the origin remote of a repository cloned from a mirror at the mirrored repository, so that
+// pushes and fetches reach it
+func resetOrigin(repo *git.Repository, repoURL string) error {
+	cfg, err := repo.Config()
+	if err != nil {
+		return fmt.Errorf("failed to read repository configuration: %w", err)
+	}
+	origin, ok := cfg.Remotes[git.DefaultRemoteName]
+	if !ok {
+		return nil
+	}
+	origin.URLs = []string{repoURL}
+	if err := repo.SetConfig(cfg); err != nil {
+		return fmt.Errorf("failed to set origin: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package codebase_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/codebase"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// runGit runs git in dir and returns its trimmed output
+func runGit(t *testing.T, dir string, args ...string) string {
+	cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	return strings.TrimSpace(string(out))
+}
+
+// commitFile writes a file of the origin repository and commits it, returning the commit's SHA
+func commitFile(t *testing.T, origin, name, content string) string {
+	path := filepath.Join(origin, filepath.FromSlash(name))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	runGit(t, origin, "add", "--all")
+	runGit(t, origin, "commit", "--quiet", "-m", "update "+name)
+	return runGit(t, origin, "rev-parse", "HEAD")
+}
+
+// newOriginRepository creates a repository on the main branch with three commits touching api/ and web/
+func newOriginRepository(t *testing.T) (string, []string) {
+	origin := t.TempDir()
+	runGit(t, origin, "init", "--quiet", "--initial-branch=main")
+	commits := []string{
+		commitFile(t, origin, "api/handler.go", "package api"),
+		commitFile(t, origin, "web/index.html", "<html></html>"),
+		commitFile(t, origin, "api/handler.go", "package api // v2"),
+	}
+	return origin, commits
+}
+
+func cloneRevision(t *testing.T, cfg config.GitConfig, branch, commitSHA string) string {
+	dir := filepath.Join(t.TempDir(), "repo")
+	repo := codebase.NewGitHubCodebaseAt(cfg, dir)
+	require.NoError(t, repo.CloneRevision(context.Background(), branch, commitSHA))
+	return dir
+}
+
+func TestCloneRevision_ClonesFromCache(t *testing.T) {
+	origin, commits := newOriginRepository(t)
+	cacheDir := t.TempDir()
+	cfg := config.GitConfig{CodebaseURL: origin, CloneCacheDir: cacheDir}
+
+	dir := cloneRevision(t, cfg, "main", "")
+
+	head, err := codebase.HeadCommit(dir)
+	require.NoError(t, err)
+	assert.Equal(t, commits[2], head)
+	mirrors, err := os.ReadDir(cacheDir)
+	require.NoError(t, err)
+	require.Len(t, mirrors, 1, "the repository is mirrored in the cache")
+
+	// The mirror is fetched into, so later clones see new commits
+	latest := commitFile(t, origin, "api/routes.go", "package api")
+	dir = cloneRevision(t, cfg, "main", "")
+	head, err = codebase.HeadCommit(dir)
+	require.NoError(t, err)
+	assert.Equal(t, latest, head)
+
+	// Clones from the mirror push to the mirrored repository
+	assert.Equal(t, origin, runGit(t, dir, "remote", "get-url", "origin"))
+}
+
+func TestCloneRevision_Shallow(t *testing.T) {
+	origin, commits := newOriginRepository(t)
+	cfg := config.GitConfig{CodebaseURL: "file://" + origin, CloneDepth: 1, CloneCacheDir: t.TempDir()}
+
+	dir := cloneRevision(t, cfg, "main", "")
+	assert.Equal(t, "1", runGit(t, dir, "rev-list", "--count", "HEAD"), "only the head commit is cloned")
+
+	// A commit beyond the depth clones the full history
+	dir = cloneRevision(t, cfg, "main", commits[0])
+	head, err := codebase.HeadCommit(dir)
+	require.NoError(t, err)
+	assert.Equal(t, commits[0], head)
+}
+
+func TestCloneRevision_Sparse(t *testing.T) {
+	origin, commits := newOriginRepository(t)
+	cfg := config.GitConfig{CodebaseURL: origin, SparsePaths: []string{"api"}}
+
+	dir := cloneRevision(t, cfg, "", commits[1])
+
+	assert.FileExists(t, filepath.Join(dir, "api", "handler.go"))
+	assert.NoFileExists(t, filepath.Join(dir, "web", "index.html"))
+	head, err := codebase.HeadCommit(dir)
+	require.NoError(t, err)
+	assert.Equal(t, commits[1], head)
+}
@@ -0,0 +1,149 @@
+package codebase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+)
+
+// cloneRevision clones the repository at repoURL to localPath and checks out commitSHA, or the head of branch. With a
+// cache directory the repository is fetched into its mirror there and cloned from it, falling back to cloning from the
+// remote when the mirror can't be updated. A shallow clone missing commitSHA is cloned again with its full history.
+// Submodules and LFS objects are then fetched from their own remotes.
+func cloneRevision(ctx context.Context, repoURL, localPath string, auth transport.AuthMethod, branch, commitSHA string, options cloneOptions) (*git.Repository, error) {
+	source, sourceAuth := repoURL, auth
+	if options.cacheDir != "" {
+		mirror, err := updateMirror(ctx, options.cacheDir, repoURL, auth, commitSHA)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to update clone cache, cloning from the remote", "error", err, "url", repoURL)
+		} else {
+			source, sourceAuth = mirror, nil
+			lock := mirrorLock(mirror)
+			lock.RLock()
+			defer lock.RUnlock()
+		}
+	}
+
+	repo, err := checkoutRevision(ctx, source, localPath, sourceAuth, branch, commitSHA, options.depth, options.sparsePaths)
+	if err != nil && options.depth > 0 && errors.Is(err, plumbing.ErrObjectNotFound) {
+		slog.InfoContext(ctx, "commit beyond the shallow clone, cloning the full history", "url", repoURL, "commit", commitSHA, "depth", options.depth)
+		if err := os.RemoveAll(localPath); err != nil {
+			return nil, fmt.Errorf("failed to remove shallow clone: %w", err)
+		}
+		repo, err = checkoutRevision(ctx, source, localPath, sourceAuth, branch, commitSHA, 0, options.sparsePaths)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if source != repoURL {
+		if err := resetOrigin(repo, repoURL); err != nil {
+			return nil, err
+		}
+	}
+	fetchNested(ctx, repo, localPath, repoURL, auth, options)
+	return repo, nil
+}
+
+// checkoutRevision clones the repository at source to localPath with depth commits of history, all of them when 0,
+// and checks out the sparse paths of commitSHA, or of the head of branch
+func checkoutRevision(ctx context.Context, source, localPath string, auth transport.AuthMethod, branch, commitSHA string, depth int, sparsePaths []string) (*git.Repository, error) {
+	options := &git.CloneOptions{
+		URL:        source,
+		Auth:       auth,
+		Depth:      depth,
+		NoCheckout: len(sparsePaths) > 0,
+		Progress:   os.Stdout,
+	}
+	if branch != "" {
+		options.ReferenceName = plumbing.NewBranchReferenceName(branch)
+		options.SingleBranch = true
+	}
+
+	repo, err := git.PlainCloneContext(ctx, localPath, false, options)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to clone repository", "error", err, "url", source, "path", localPath, "branch", branch)
+		return nil, fmt.Errorf("failed to clone repository: %w", err)
+	}
+
+	if commitSHA == "" && len(sparsePaths) == 0 {
+		return repo, nil
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get worktree: %w", err)
+	}
+	checkout := &git.CheckoutOptions{SparseCheckoutDirectories: sparsePaths}
+	if commitSHA != "" {
+		checkout.Hash = plumbing.NewHash(commitSHA)
+	} else {
+		head, err := repo.Head()
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve HEAD: %w", err)
+		}
+		checkout.Branch = head.Name()
+	}
+	if err := wt.Checkout(checkout); err != nil {
+		return nil, fmt.Errorf("failed to check out commit %s: %w", commitSHA, err)
+	}
+	return repo, nil
+}
+
+// checkoutNewBranch creates a new branch and checks it out
+func checkoutNewBranch(repo *git.Repository, branchName string) error {
+	wt, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("failed to get worktree: %w", err)
+	}
+	return wt.Checkout(&git.CheckoutOptions{
+		Branch: plumbing.NewBranchReferenceName(branchName),
+		Create: true,
+	})
+}
+
+// commitAll stages and commits all changes with the provided message. Smudged LFS objects that weren't changed are
+// committed as the pointer files they were checked out from.
+func commitAll(repo *git.Repository, message, author, email string) error {
+	wt, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("failed to get worktree: %w", err)
+	}
+	pointers, err := stagedLFSPointers(repo)
+	if err != nil {
+		return err
+	}
+	if _, err := wt.Add("."); err != nil {
+		return fmt.Errorf("failed to add changes: %w", err)
+	}
+	if err := restoreLFSPointers(repo, wt.Filesystem.Root(), pointers); err != nil {
+		return err
+	}
+	_, err = wt.Commit(message, &git.CommitOptions{
+		Author: &object.Signature{
+			Name:  author,
+			Email: email,
+			When:  time.Now(),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to commit changes: %w", err)
+	}
+	return nil
+}
+
+// removeRepository deletes the repository at localPath from the filesystem
+func removeRepository(localPath string) error {
+	if err := os.RemoveAll(localPath); err != nil {
+		return fmt.Errorf("failed to remove repository: %w", err)
+	}
+	return nil
+}
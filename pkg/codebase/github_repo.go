@@ -2,19 +2,15 @@
 package codebase
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"log/slog"
-	"os"
 	"os/exec"
 	"path"
 	"strings"
-	"time"
 
 	"github.com/go-git/go-git/v5"
-	"github.com/go-git/go-git/v5/plumbing"
-	"github.com/go-git/go-git/v5/plumbing/object"
 	gitHttp "github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
 )
@@ -27,6 +23,7 @@ type GitHubCodebase struct {
 	Email   string
 	repo    *git.Repository
 	path    string
+	clone   cloneOptions
 }
 
 // NewGitHubCodebase creates a new GitHub codebase instance
@@ -38,6 +35,19 @@ func NewGitHubCodebase(git config.GitConfig) Codebase {
 		Author:  git.Author,
 		Email:   git.Email,
 		path:    repoName,
+		clone:   newCloneOptions(git),
+	}
+}
+
+// NewGitHubCodebaseAt creates a new GitHub codebase instance cloned to the given local path
+func NewGitHubCodebaseAt(git config.GitConfig, localPath string) Codebase {
+	return &GitHubCodebase{
+		RepoURL: git.CodebaseURL,
+		Token:   git.Token,
+		Author:  git.Author,
+		Email:   git.Email,
+		path:    localPath,
+		clone:   newCloneOptions(git),
 	}
 }
 
@@ -48,51 +58,40 @@ func (g *GitHubCodebase) GetPath() string {
 
 // Clone clones the repository to the local filesystem
 func (g *GitHubCodebase) Clone(ctx context.Context) error {
-	repo, err := git.PlainCloneContext(ctx, g.path, false, &git.CloneOptions{
-		URL:      g.RepoURL,
-		Progress: os.Stdout,
-	})
+	return g.CloneRevision(ctx, "", "")
+}
+
+// CloneRevision clones the repository to the local filesystem and checks out commitSHA, or the head of branch
+func (g *GitHubCodebase) CloneRevision(ctx context.Context, branch, commitSHA string) error {
+	repo, err := cloneRevision(ctx, g.RepoURL, g.path, nil, branch, commitSHA, g.clone)
 	if err != nil {
-		slog.Error("failed to clone repository", "error", err, "url", g.RepoURL, "path", g.path)
-		return fmt.Errorf("failed to clone repository: %w", err)
+		return err
 	}
 	g.repo = repo
 	return nil
 }
 
-// CheckoutBranch creates a new branch and checks it out
-func (g *GitHubCodebase) CheckoutBranch(branchName string) error {
-	wt, err := g.repo.Worktree()
+// HeadCommit returns the SHA of the commit checked out in the repository at path
+func HeadCommit(path string) (string, error) {
+	repo, err := git.PlainOpen(path)
 	if err != nil {
-		return fmt.Errorf("failed to get worktree: %w", err)
+		return "", fmt.Errorf("failed to open repository: %w", err)
 	}
-	branchRef := plumbing.NewBranchReferenceName(branchName)
-	return wt.Checkout(&git.CheckoutOptions{
-		Branch: branchRef,
-		Create: true,
-	})
+	head, err := repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+	return head.Hash().String(), nil
+}
+
+// CheckoutBranch creates a new branch and checks it out
+func (g *GitHubCodebase) CheckoutBranch(branchName string) error {
+	return checkoutNewBranch(g.repo, branchName)
 }
 
 // Commit stages and commits all changes with the provided message
 func (g *GitHubCodebase) Commit(message string) error {
-	wt, err := g.repo.Worktree()
-	if err != nil {
-		return fmt.Errorf("failed to get worktree: %w", err)
-	}
-	if _, err := wt.Add("."); err != nil {
-		return fmt.Errorf("failed to add changes: %w", err)
-	}
-	_, err = wt.Commit(message, &git.CommitOptions{
-		Author: &object.Signature{
-			Name:  g.Author,
-			Email: g.Email,
-			When:  time.Now(),
-		},
-	})
-	if err != nil {
-		return fmt.Errorf("failed to commit changes: %w", err)
-	}
-	return nil
+	return commitAll(g.repo, message, g.Author, g.Email)
 }
 
 // Push pushes commits to the remote repository
@@ -108,7 +107,7 @@ func (g *GitHubCodebase) Push(ctx context.Context) error {
 	})
 }
 
-// CreatePR creates a new pull request
+// CreatePR creates a new pull request and returns its web URL
 func (g *GitHubCodebase) CreatePR(ctx context.Context, title, description, sourceBranch, targetBranch string) (string, error) {
 	owner, repo, err := g.getOwnerRepo()
 	if err != nil {
@@ -116,13 +115,23 @@ func (g *GitHubCodebase) CreatePR(ctx context.Context, title, description, sourc
 	}
 
 	createURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls", owner, repo)
-	cmd := fmt.Sprintf(`curl -X POST -H "Authorization: token %s" -H "Accept: application/vnd.github.v3+json" %s -d '{"title":"%s", "body":"%s", "head":"%s", "base":"%s"}'`,
-		g.Token, createURL, title, description, sourceBranch, targetBranch)
-	output, err := exec.CommandContext(ctx, "bash", "-c", cmd).Output()
+	body := map[string]string{"title": title, "body": description, "head": sourceBranch, "base": targetBranch}
+	output, err := g.send(ctx, "POST", createURL, body)
 	if err != nil {
 		return "", fmt.Errorf("failed to create pull request: %w", err)
 	}
-	return string(output), nil
+
+	var pr struct {
+		HTMLURL string `json:"html_url"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(output, &pr); err != nil {
+		return "", fmt.Errorf("failed to parse created pull request: %w", err)
+	}
+	if pr.HTMLURL == "" {
+		return "", fmt.Errorf("failed to create pull request: %s", pr.Message)
+	}
+	return pr.HTMLURL, nil
 }
 
 // UpsertPR creates a PR if it doesn't exist, otherwise updates the existing one.
@@ -175,22 +184,29 @@ func (g *GitHubCodebase) UpdatePR(ctx context.Context, prNumber int, title, desc
 	}
 
 	updateURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d", owner, repo, prNumber)
-	cmd := fmt.Sprintf(`curl -X PATCH -H "Authorization: token %s" -H "Accept: application/vnd.github.v3+json" %s -d '{"title":"%s", "body":"%s"}'`,
-		g.Token, updateURL, title, description)
-	_, err = exec.CommandContext(ctx, "bash", "-c", cmd).Output()
-	if err != nil {
+	if _, err := g.send(ctx, "PATCH", updateURL, map[string]string{"title": title, "body": description}); err != nil {
 		return fmt.Errorf("failed to update PR: %w", err)
 	}
 	return nil
 }
 
-// Cleanup deletes the repository from the filesystem.
-func (g *GitHubCodebase) Cleanup() error {
-	err := os.RemoveAll(g.path)
+// send sends a JSON body to the GitHub API, on curl's standard input so that neither the shell nor the command line
+// see it, and returns the response
+func (g *GitHubCodebase) send(ctx context.Context, method, url string, body any) ([]byte, error) {
+	payload, err := json.Marshal(body)
 	if err != nil {
-		return fmt.Errorf("failed to remove repository: %w", err)
+		return nil, err
 	}
-	return nil
+	cmd := fmt.Sprintf(`curl -s -X %s -H "Authorization: token %s" -H "Accept: application/vnd.github.v3+json" %s -d @-`,
+		method, g.Token, url)
+	command := exec.CommandContext(ctx, "bash", "-c", cmd)
+	command.Stdin = bytes.NewReader(payload)
+	return command.Output()
+}
+
+// Cleanup deletes the repository from the filesystem.
+func (g *GitHubCodebase) Cleanup() error {
+	return removeRepository(g.path)
 }
 
 // getOwnerRepo extracts owner and repo from the GitHub URL
@@ -0,0 +1,113 @@
+package codebase
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxHistoryAuthors bounds the recent authors kept for each file
+const maxHistoryAuthors = 5
+
+// historyCommitMarker starts the header line of each commit in the log read by ExtractHistory, written as %x00
+const historyCommitMarker = "\x00"
+
+// FileHistory summarizes the commits changing a file of a repository, telling the hot spots changed often and
+// recently from the files left alone
+type FileHistory struct {
+	Path          string    // Path of the file, relative to the repository root
+	Commits       int       // Commits changing the file
+	LinesAdded    int       // Lines the commits added, leaving out binary changes
+	LinesDeleted  int       // Lines the commits deleted, leaving out binary changes
+	Authors       []string  // Authors of the commits, most recent first, at most maxHistoryAuthors of them
+	FirstCommitAt time.Time // Oldest commit changing the file within the history read, which tells its age
+	LastCommitAt  time.Time // Latest commit changing the file
+}
+
+// Churn returns the lines the commits changing the file added and deleted
+func (h FileHistory) Churn() int {
+	return h.LinesAdded + h.LinesDeleted
+}
+
+// ExtractHistory reads the history of the repository checked out at dir since a time, all of it when since is zero,
+// and summarizes the commits changing each file that still exists, highest churn first. Merge commits are left out,
+// since they repeat the changes of the commits they merge. Shallow clones only have the history they cloned.
+func ExtractHistory(ctx context.Context, dir string, since time.Time) ([]FileHistory, error) {
+	args := []string{"-C", dir, "log", "--no-merges", "--no-renames", "--numstat", "--format=%x00%an%x1f%aI"}
+	if !since.IsZero() {
+		args = append(args, "--since="+since.UTC().Format(time.RFC3339))
+	}
+	out, err := exec.CommandContext(ctx, "git", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read git history: %w", err)
+	}
+	tracked, err := exec.CommandContext(ctx, "git", "-C", dir, "ls-files", "-z").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tracked files: %w", err)
+	}
+	exists := map[string]bool{}
+	for _, path := range strings.Split(string(tracked), "\x00") {
+		if path != "" {
+			exists[path] = true
+		}
+	}
+
+	histories := map[string]*FileHistory{}
+	var author string
+	var committedAt time.Time
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if header, ok := strings.CutPrefix(line, historyCommitMarker); ok {
+			name, date, _ := strings.Cut(header, "\x1f")
+			author = name
+			if committedAt, err = time.Parse(time.RFC3339, date); err != nil {
+				return nil, fmt.Errorf("invalid commit date %q: %w", date, err)
+			}
+			continue
+		}
+
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 || !exists[fields[2]] {
+			continue
+		}
+		history, ok := histories[fields[2]]
+		if !ok {
+			// The log lists the latest commits first
+			history = &FileHistory{Path: fields[2], LastCommitAt: committedAt}
+			histories[fields[2]] = history
+		}
+		history.Commits++
+		history.FirstCommitAt = committedAt
+		// Binary changes are counted as - and -
+		added, _ := strconv.Atoi(fields[0])
+		deleted, _ := strconv.Atoi(fields[1])
+		history.LinesAdded += added
+		history.LinesDeleted += deleted
+		if len(history.Authors) < maxHistoryAuthors && !slices.Contains(history.Authors, author) {
+			history.Authors = append(history.Authors, author)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to parse git history: %w", err)
+	}
+
+	result := make([]FileHistory, 0, len(histories))
+	for _, history := range histories {
+		result = append(result, *history)
+	}
+	slices.SortFunc(result, func(a, b FileHistory) int {
+		if a.Churn() != b.Churn() {
+			return b.Churn() - a.Churn()
+		}
+		return strings.Compare(a.Path, b.Path)
+	})
+	return result, nil
+}
@@ -0,0 +1,64 @@
+package codebase_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/codebase"
+)
+
+// commitAs writes a file and commits it by an author at a date
+func commitAs(t *testing.T, dir, author, date, name, content string) {
+	t.Setenv("GIT_COMMITTER_DATE", date)
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	runGit(t, dir, "add", "--all")
+	runGit(t, dir, "-c", "user.name="+author, "commit", "--quiet", "--date="+date, "-m", "update "+name)
+}
+
+func TestExtractHistory(t *testing.T) {
+	dir := t.TempDir()
+	runGit(t, dir, "init", "--quiet", "--initial-branch=main")
+	commitAs(t, dir, "alice", "2024-01-01T10:00:00Z", "billing/invoice.go", "a\n")
+	commitAs(t, dir, "alice", "2024-01-02T10:00:00Z", "README.md", "readme\n")
+	commitAs(t, dir, "bob", "2024-02-01T10:00:00Z", "billing/invoice.go", "a\nb\nc\n")
+	commitAs(t, dir, "carol", "2024-02-15T10:00:00Z", "billing/invoice.go", "c\n")
+	commitAs(t, dir, "bob", "2024-02-20T10:00:00Z", "old.go", "gone\n")
+	runGit(t, dir, "rm", "--quiet", "old.go")
+	runGit(t, dir, "commit", "--quiet", "-m", "remove old.go")
+
+	histories, err := codebase.ExtractHistory(context.Background(), dir, time.Time{})
+
+	require.NoError(t, err)
+	require.Len(t, histories, 2, "deleted files are left out")
+	invoice := histories[0]
+	assert.Equal(t, "billing/invoice.go", invoice.Path)
+	assert.Equal(t, 3, invoice.Commits)
+	assert.Equal(t, 3, invoice.LinesAdded)
+	assert.Equal(t, 2, invoice.LinesDeleted)
+	assert.Equal(t, []string{"carol", "bob", "alice"}, invoice.Authors)
+	assert.Equal(t, time.Date(2024, time.January, 1, 10, 0, 0, 0, time.UTC), invoice.FirstCommitAt.UTC())
+	assert.Equal(t, time.Date(2024, time.February, 15, 10, 0, 0, 0, time.UTC), invoice.LastCommitAt.UTC())
+	assert.Equal(t, "README.md", histories[1].Path)
+}
+
+func TestExtractHistory_Since(t *testing.T) {
+	dir := t.TempDir()
+	runGit(t, dir, "init", "--quiet", "--initial-branch=main")
+	commitAs(t, dir, "alice", "2024-01-01T10:00:00Z", "a.go", "a\n")
+	commitAs(t, dir, "bob", "2024-02-01T10:00:00Z", "a.go", "b\n")
+
+	histories, err := codebase.ExtractHistory(context.Background(), dir, time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC))
+
+	require.NoError(t, err)
+	require.Len(t, histories, 1)
+	assert.Equal(t, 1, histories[0].Commits)
+	assert.Equal(t, []string{"bob"}, histories[0].Authors)
+}
@@ -0,0 +1,429 @@
+package codebase
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	formatconfig "github.com/go-git/go-git/v5/plumbing/format/config"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	gitHttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+// lfsPointerVersion starts the pointer files LFS stores in place of large files
+const lfsPointerVersion = "version https://git-lfs.github.com/spec/v1"
+
+// lfsMaxPointerSize is the largest file read as a pointer file. Pointers are about 130 bytes.
+const lfsMaxPointerSize = 1024
+
+// lfsBatchSize is the number of objects requested per batch API call
+const lfsBatchSize = 100
+
+// lfsMediaType is the media type of the LFS batch API
+const lfsMediaType = "application/vnd.git-lfs+json"
+
+// lfsRequestTimeout bounds each LFS request, including the download of an object
+const lfsRequestTimeout = 10 * time.Minute
+
+// lfsHTTPClient calls the LFS batch API and downloads its objects
+var lfsHTTPClient = &http.Client{Timeout: lfsRequestTimeout}
+
+// lfsPointer is a pointer file checked out in place of the object it names
+type lfsPointer struct {
+	path string // Path of the pointer file
+	oid  string // SHA-256 of the object
+	size int64  // Size of the object
+}
+
+// lfsBatchRequest asks the batch API where to download objects from
+type lfsBatchRequest struct {
+	Operation string      `json:"operation"`
+	Transfers []string    `json:"transfers"`
+	Objects   []lfsObject `json:"objects"`
+}
+
+// lfsObject is an object of a batch request or response
+type lfsObject struct {
+	OID     string `json:"oid"`
+	Size    int64  `json:"size"`
+	Actions *struct {
+		Download *struct {
+			Href   string            `json:"href"`
+			Header map[string]string `json:"header"`
+		} `json:"download"`
+	} `json:"actions,omitempty"`
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// lfsBatchResponse is the response of the batch API
+type lfsBatchResponse struct {
+	Objects []lfsObject `json:"objects"`
+}
+
+// smudgeLFS replaces the LFS pointer files checked out in the repository at dir with their objects, downloaded from
+// the LFS server of remoteURL, or the one .lfsconfig names, authenticated by authFor. Objects that would take the
+// downloads past budget bytes are left as pointer files, and budget is reduced by the objects downloaded.
+func smudgeLFS(ctx context.Context, dir, remoteURL string, authFor func(endpoint string) transport.AuthMethod, budget *int64) error {
+	pointers, err := findLFSPointers(dir)
+	if err != nil || len(pointers) == 0 {
+		return err
+	}
+
+	endpoint, err := lfsEndpoint(dir, remoteURL)
+	if err != nil {
+		return err
+	}
+	if endpoint == "" {
+		slog.WarnContext(ctx, "no LFS server for remote, leaving pointer files", "url", remoteURL, "pointers", len(pointers))
+		return nil
+	}
+
+	var wanted []lfsPointer
+	for _, pointer := range pointers {
+		if pointer.size > *budget {
+			slog.WarnContext(ctx, "LFS object exceeds the size cap, leaving its pointer file", "path", pointer.path, "size", pointer.size)
+			continue
+		}
+		*budget -= pointer.size
+		wanted = append(wanted, pointer)
+	}
+
+	for start := 0; start < len(wanted); start += lfsBatchSize {
+		batch := wanted[start:min(start+lfsBatchSize, len(wanted))]
+		if err := downloadLFSBatch(ctx, endpoint, authFor(endpoint), batch); err != nil {
+			return err
+		}
+	}
+	slog.InfoContext(ctx, "smudged LFS pointer files", "url", remoteURL, "objects", len(wanted))
+	return nil
+}
+
+// findLFSPointers returns the pointer files under dir, skipping the repository's .git directory and submodules
+func findLFSPointers(dir string) ([]lfsPointer, error) {
+	var pointers []lfsPointer
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path == dir {
+				return nil
+			}
+			if entry.Name() == git.GitDirName {
+				return filepath.SkipDir
+			}
+			// Submodules are smudged with the credentials of their own remote
+			if _, err := os.Lstat(filepath.Join(path, git.GitDirName)); err == nil {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if info.Size() > lfsMaxPointerSize {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if pointer, ok := parseLFSPointer(data); ok {
+			pointer.path = path
+			pointers = append(pointers, pointer)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find LFS pointer files: %w", err)
+	}
+	return pointers, nil
+}
+
+// parseLFSPointer parses the contents of a pointer file
+func parseLFSPointer(data []byte) (lfsPointer, bool) {
+	if !bytes.HasPrefix(data, []byte(lfsPointerVersion+"\n")) {
+		return lfsPointer{}, false
+	}
+
+	var pointer lfsPointer
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value, _ := strings.Cut(scanner.Text(), " ")
+		switch key {
+		case "oid":
+			pointer.oid, _ = strings.CutPrefix(value, "sha256:")
+		case "size":
+			pointer.size, _ = strconv.ParseInt(value, 10, 64)
+		}
+	}
+	if len(pointer.oid) != sha256.Size*2 || pointer.size <= 0 {
+		return lfsPointer{}, false
+	}
+	return pointer, true
+}
+
+// lfsEndpoint returns the LFS server of a repository: the lfs.url of its .lfsconfig, or the one git-lfs derives from
+// remoteURL. Remotes that aren't reachable over HTTP have none.
+func lfsEndpoint(dir, remoteURL string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ".lfsconfig"))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("failed to read .lfsconfig: %w", err)
+	}
+	if err == nil {
+		cfg := formatconfig.New()
+		if err := formatconfig.NewDecoder(bytes.NewReader(data)).Decode(cfg); err != nil {
+			return "", fmt.Errorf("failed to parse .lfsconfig: %w", err)
+		}
+		if url := cfg.Section("lfs").Option("url"); url != "" {
+			return strings.TrimSuffix(url, "/"), nil
+		}
+	}
+
+	endpoint, err := transport.NewEndpoint(remoteURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid remote URL: %w", err)
+	}
+	switch endpoint.Protocol {
+	case "http", "https":
+	case "ssh":
+		// git-lfs serves SSH remotes over HTTPS from the same host
+		endpoint.Protocol, endpoint.Port = "https", 0
+	default:
+		return "", nil
+	}
+	endpoint.User, endpoint.Password = "", ""
+	url := strings.TrimSuffix(endpoint.String(), "/")
+	if !strings.HasSuffix(url, ".git") {
+		url += ".git"
+	}
+	return url + "/info/lfs", nil
+}
+
+// downloadLFSBatch downloads a batch of objects over their pointer files
+func downloadLFSBatch(ctx context.Context, endpoint string, auth transport.AuthMethod, pointers []lfsPointer) error {
+	request := lfsBatchRequest{Operation: "download", Transfers: []string{"basic"}}
+	for _, pointer := range pointers {
+		request.Objects = append(request.Objects, lfsObject{OID: pointer.oid, Size: pointer.size})
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to marshal LFS batch request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint+"/objects/batch", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create LFS batch request: %w", err)
+	}
+	req.Header.Set("Accept", lfsMediaType)
+	req.Header.Set("Content-Type", lfsMediaType)
+	switch auth := auth.(type) {
+	case *gitHttp.BasicAuth:
+		req.SetBasicAuth(auth.Username, auth.Password)
+	case *gitHttp.TokenAuth:
+		req.Header.Set("Authorization", "Bearer "+auth.Token)
+	}
+
+	resp, err := lfsHTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call LFS batch API: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("LFS batch API returned status %d", resp.StatusCode)
+	}
+	var batch lfsBatchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&batch); err != nil {
+		return fmt.Errorf("failed to decode LFS batch response: %w", err)
+	}
+
+	objects := map[string]lfsObject{}
+	for _, object := range batch.Objects {
+		objects[object.OID] = object
+	}
+	for _, pointer := range pointers {
+		object, ok := objects[pointer.oid]
+		switch {
+		case !ok:
+			return fmt.Errorf("LFS batch response is missing object %s", pointer.oid)
+		case object.Error != nil:
+			slog.WarnContext(ctx, "LFS object unavailable, leaving its pointer file", "path", pointer.path, "code", object.Error.Code, "message", object.Error.Message)
+			continue
+		case object.Actions == nil || object.Actions.Download == nil:
+			return fmt.Errorf("LFS batch response has no download of object %s", pointer.oid)
+		}
+		download := object.Actions.Download
+		if err := downloadLFSObject(ctx, download.Href, download.Header, pointer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// downloadLFSObject downloads an object from href and replaces its pointer file with it once its hash is verified.
+// The batch API's headers authenticate the download, rather than the remote's credentials.
+func downloadLFSObject(ctx context.Context, href string, header map[string]string, pointer lfsPointer) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, href, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create LFS download request: %w", err)
+	}
+	for key, value := range header {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := lfsHTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download LFS object %s: %w", pointer.oid, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("LFS download of object %s returned status %d", pointer.oid, resp.StatusCode)
+	}
+
+	info, err := os.Stat(pointer.path)
+	if err != nil {
+		return fmt.Errorf("failed to stat LFS pointer file: %w", err)
+	}
+	file, err := os.CreateTemp(filepath.Dir(pointer.path), ".lfs-*")
+	if err != nil {
+		return fmt.Errorf("failed to create LFS object file: %w", err)
+	}
+	defer func() {
+		_ = os.Remove(file.Name())
+	}()
+
+	hash := sha256.New()
+	written, err := io.Copy(io.MultiWriter(file, hash), io.LimitReader(resp.Body, pointer.size+1))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write LFS object %s: %w", pointer.oid, err)
+	}
+	if written != pointer.size || hex.EncodeToString(hash.Sum(nil)) != pointer.oid {
+		return fmt.Errorf("downloaded LFS object %s doesn't match its pointer", pointer.oid)
+	}
+
+	if err := os.Chmod(file.Name(), info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to set mode of LFS object file: %w", err)
+	}
+	if err := os.Rename(file.Name(), pointer.path); err != nil {
+		return fmt.Errorf("failed to replace LFS pointer file: %w", err)
+	}
+	return nil
+}
+
+// stagedLFSPointers returns the entries of the repository's index whose blob is a pointer file, by path
+func stagedLFSPointers(repo *git.Repository) (map[string]lfsPointerEntry, error) {
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read index: %w", err)
+	}
+
+	pointers := map[string]lfsPointerEntry{}
+	for _, entry := range idx.Entries {
+		if entry.Size > lfsMaxPointerSize {
+			continue
+		}
+		blob, err := repo.BlobObject(entry.Hash)
+		if err != nil || blob.Size > lfsMaxPointerSize {
+			// Submodule entries have no blob
+			continue
+		}
+		reader, err := blob.Reader()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read blob: %w", err)
+		}
+		data, err := io.ReadAll(reader)
+		_ = reader.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read blob: %w", err)
+		}
+		if pointer, ok := parseLFSPointer(data); ok {
+			pointers[entry.Name] = lfsPointerEntry{entry: *entry, oid: pointer.oid}
+		}
+	}
+	return pointers, nil
+}
+
+// lfsPointerEntry is an index entry staging a pointer file
+type lfsPointerEntry struct {
+	entry index.Entry
+	oid   string
+}
+
+// restoreLFSPointers stages the pointer files of smudged objects again in place of the objects, so that committing
+// all changes doesn't commit the content of unchanged LFS files. Objects that were changed stay staged.
+func restoreLFSPointers(repo *git.Repository, root string, pointers map[string]lfsPointerEntry) error {
+	if len(pointers) == 0 {
+		return nil
+	}
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return fmt.Errorf("failed to read index: %w", err)
+	}
+
+	for _, entry := range idx.Entries {
+		pointer, ok := pointers[entry.Name]
+		if !ok || entry.Hash == pointer.entry.Hash {
+			continue
+		}
+		oid, err := fileSHA256(filepath.Join(root, filepath.FromSlash(entry.Name)))
+		if err != nil {
+			return err
+		}
+		if oid == pointer.oid {
+			*entry = pointer.entry
+		}
+	}
+
+	if err := repo.Storer.SetIndex(idx); err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	return nil
+}
+
+// fileSHA256 returns the hex encoded SHA-256 of a file
+func fileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open file: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("failed to hash file: %w", err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,112 @@
+package codebase_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/codebase"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// newLFSServer serves the objects of the LFS batch API, keyed by their SHA-256
+func newLFSServer(t *testing.T, objects map[string]string) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == "/lfs/objects/batch" {
+			var request struct {
+				Objects []struct {
+					OID  string `json:"oid"`
+					Size int64  `json:"size"`
+				} `json:"objects"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+			var response []map[string]any
+			for _, object := range request.Objects {
+				response = append(response, map[string]any{
+					"oid":     object.OID,
+					"size":    object.Size,
+					"actions": map[string]any{"download": map[string]any{"href": server.URL + "/objects/" + object.OID}},
+				})
+			}
+			w.Header().Set("Content-Type", "application/vnd.git-lfs+json")
+			require.NoError(t, json.NewEncoder(w).Encode(map[string]any{"objects": response}))
+			return
+		}
+		content, ok := objects[strings.TrimPrefix(r.URL.Path, "/objects/")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(content))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// commitLFSObject commits the pointer file of content to the origin repository and returns the object's SHA-256
+func commitLFSObject(t *testing.T, origin, name, content string) string {
+	sum := sha256.Sum256([]byte(content))
+	oid := hex.EncodeToString(sum[:])
+	commitFile(t, origin, name, fmt.Sprintf("version https://git-lfs.github.com/spec/v1\noid sha256:%s\nsize %d\n", oid, len(content)))
+	return oid
+}
+
+func TestCloneRevision_SmudgesLFSObjects(t *testing.T) {
+	origin, _ := newOriginRepository(t)
+	model := strings.Repeat("weights", 1024)
+	sample := "sample data"
+	objects := map[string]string{}
+	server := newLFSServer(t, objects)
+	commitFile(t, origin, ".lfsconfig", "[lfs]\n\turl = "+server.URL+"/lfs\n")
+	objects[commitLFSObject(t, origin, "models/model.bin", model)] = model
+	objects[commitLFSObject(t, origin, "data/sample.csv", sample)] = sample
+
+	dir := cloneRevision(t, config.GitConfig{CodebaseURL: origin, LFSMaxSize: 1 << 20}, "main", "")
+
+	data, err := os.ReadFile(filepath.Join(dir, "models", "model.bin"))
+	require.NoError(t, err)
+	assert.Equal(t, model, string(data))
+	data, err = os.ReadFile(filepath.Join(dir, "data", "sample.csv"))
+	require.NoError(t, err)
+	assert.Equal(t, sample, string(data))
+
+	// Objects past the size cap are left as pointer files
+	dir = cloneRevision(t, config.GitConfig{CodebaseURL: origin, LFSMaxSize: 1024}, "main", "")
+
+	data, err = os.ReadFile(filepath.Join(dir, "models", "model.bin"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "version https://git-lfs.github.com/spec/v1"))
+	data, err = os.ReadFile(filepath.Join(dir, "data", "sample.csv"))
+	require.NoError(t, err)
+	assert.Equal(t, sample, string(data))
+}
+
+func TestCommit_KeepsLFSPointers(t *testing.T) {
+	origin, _ := newOriginRepository(t)
+	model := strings.Repeat("weights", 1024)
+	objects := map[string]string{}
+	server := newLFSServer(t, objects)
+	commitFile(t, origin, ".lfsconfig", "[lfs]\n\turl = "+server.URL+"/lfs\n")
+	objects[commitLFSObject(t, origin, "models/model.bin", model)] = model
+	pointer := runGit(t, origin, "show", "HEAD:models/model.bin")
+
+	dir := filepath.Join(t.TempDir(), "repo")
+	repo := codebase.NewGitHubCodebaseAt(config.GitConfig{CodebaseURL: origin, LFSMaxSize: 1 << 20}, dir)
+	require.NoError(t, repo.CloneRevision(t.Context(), "main", ""))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "api", "handler.go"), []byte("package api // v3"), 0o600))
+	require.NoError(t, repo.Commit("update handler"))
+
+	assert.Equal(t, pointer, runGit(t, dir, "show", "HEAD:models/model.bin"), "the smudged object is committed as its pointer")
+	assert.Equal(t, "package api // v3", runGit(t, dir, "show", "HEAD:api/handler.go"))
+}
@@ -76,6 +76,20 @@ func (mr *MockCodebaseMockRecorder) Clone(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Clone", reflect.TypeOf((*MockCodebase)(nil).Clone), arg0)
 }
 
+// CloneRevision mocks base method.
+func (m *MockCodebase) CloneRevision(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloneRevision", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloneRevision indicates an expected call of CloneRevision.
+func (mr *MockCodebaseMockRecorder) CloneRevision(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloneRevision", reflect.TypeOf((*MockCodebase)(nil).CloneRevision), arg0, arg1, arg2)
+}
+
 // Commit mocks base method.
 func (m *MockCodebase) Commit(arg0 string) error {
 	m.ctrl.T.Helper()
@@ -76,6 +76,20 @@ func (mr *MockRepositoryMockRecorder) Clone(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Clone", reflect.TypeOf((*MockRepository)(nil).Clone), arg0)
 }
 
+// CloneRevision mocks base method.
+func (m *MockRepository) CloneRevision(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloneRevision", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloneRevision indicates an expected call of CloneRevision.
+func (mr *MockRepositoryMockRecorder) CloneRevision(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloneRevision", reflect.TypeOf((*MockRepository)(nil).CloneRevision), arg0, arg1, arg2)
+}
+
 // Commit mocks base method.
 func (m *MockRepository) Commit(arg0 string) error {
 	m.ctrl.T.Helper()
@@ -9,6 +9,10 @@ type Codebase interface {
 	// Clone clones a git repository
 	Clone(ctx context.Context) error
 
+	// CloneRevision clones a git repository at a commit, or at the head of branch when commitSHA is empty.
+	// An empty branch clones the default branch.
+	CloneRevision(ctx context.Context, branch, commitSHA string) error
+
 	// GetPath returns the path to the repository
 	GetPath() string
 
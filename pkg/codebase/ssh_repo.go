@@ -0,0 +1,209 @@
+package codebase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+	"golang.org/x/crypto/ssh"
+)
+
+// defaultSSHUser is the user of remotes whose URL names none
+const defaultSSHUser = "git"
+
+// ErrPullRequestsUnsupported is returned for pull request operations on remotes without a provider API
+var ErrPullRequestsUnsupported = errors.New("pull requests are not supported by plain git remotes")
+
+// SSHCodebase represents a codebase on a plain git-over-SSH remote, such as a self-hosted git server. There's no
+// provider API, so pull requests degrade to pushing their source branch.
+type SSHCodebase struct {
+	RepoURL    string
+	PrivateKey string
+	// KnownHosts holds known_hosts entries trusted for the remote. When empty the KnownHostsFile is read instead.
+	KnownHosts     string
+	KnownHostsFile string
+	Author         string
+	Email          string
+	repo           *git.Repository
+	path           string
+	clone          cloneOptions
+}
+
+// NewSSHCodebase creates a new SSH codebase instance authenticating with the PEM encoded privateKey and verifying the
+// remote against knownHosts, or the configured known_hosts file when knownHosts is empty
+func NewSSHCodebase(git config.GitConfig, privateKey, knownHosts string) Codebase {
+	return NewSSHCodebaseAt(git, privateKey, knownHosts, path.Base(strings.TrimSuffix(git.CodebaseURL, ".git")))
+}
+
+// NewSSHCodebaseAt creates a new SSH codebase instance cloned to the given local path
+func NewSSHCodebaseAt(git config.GitConfig, privateKey, knownHosts, localPath string) Codebase {
+	return &SSHCodebase{
+		RepoURL:        git.CodebaseURL,
+		PrivateKey:     privateKey,
+		KnownHosts:     knownHosts,
+		KnownHostsFile: git.SSHKnownHostsFile,
+		Author:         git.Author,
+		Email:          git.Email,
+		path:           localPath,
+		clone:          newCloneOptions(git),
+	}
+}
+
+// GetPath returns the local path of the repository
+func (s *SSHCodebase) GetPath() string {
+	return s.path
+}
+
+// Clone clones the repository to the local filesystem
+func (s *SSHCodebase) Clone(ctx context.Context) error {
+	return s.CloneRevision(ctx, "", "")
+}
+
+// CloneRevision clones the repository to the local filesystem and checks out commitSHA, or the head of branch
+func (s *SSHCodebase) CloneRevision(ctx context.Context, branch, commitSHA string) error {
+	auth, err := s.auth()
+	if err != nil {
+		return err
+	}
+
+	repo, err := cloneRevision(ctx, s.RepoURL, s.path, auth, branch, commitSHA, s.clone)
+	if err != nil {
+		return err
+	}
+	s.repo = repo
+	return nil
+}
+
+// CheckoutBranch creates a new branch and checks it out
+func (s *SSHCodebase) CheckoutBranch(branchName string) error {
+	return checkoutNewBranch(s.repo, branchName)
+}
+
+// Commit stages and commits all changes with the provided message
+func (s *SSHCodebase) Commit(message string) error {
+	return commitAll(s.repo, message, s.Author, s.Email)
+}
+
+// Push pushes commits to the remote repository
+func (s *SSHCodebase) Push(ctx context.Context) error {
+	return s.push(ctx, nil)
+}
+
+// CreatePR pushes sourceBranch, since plain git remotes have no pull requests, and returns the branch name for a
+// reviewer to merge into targetBranch
+func (s *SSHCodebase) CreatePR(ctx context.Context, _, _, sourceBranch, _ string) (string, error) {
+	ref := plumbing.NewBranchReferenceName(sourceBranch)
+	refSpec := gitconfig.RefSpec(fmt.Sprintf("+%s:%s", ref, ref))
+	if err := s.push(ctx, []gitconfig.RefSpec{refSpec}); err != nil {
+		return "", fmt.Errorf("failed to push branch %s: %w", sourceBranch, err)
+	}
+	return sourceBranch, nil
+}
+
+// UpsertPR pushes sourceBranch like CreatePR. Pushing again updates the branch, so there's nothing else to update.
+func (s *SSHCodebase) UpsertPR(ctx context.Context, title, description, sourceBranch, targetBranch string) (string, error) {
+	return s.CreatePR(ctx, title, description, sourceBranch, targetBranch)
+}
+
+// UpdatePR returns ErrPullRequestsUnsupported, as plain git remotes have no pull requests to update
+func (s *SSHCodebase) UpdatePR(_ context.Context, _ int, _, _ string) error {
+	return ErrPullRequestsUnsupported
+}
+
+// Cleanup deletes the repository from the filesystem.
+func (s *SSHCodebase) Cleanup() error {
+	return removeRepository(s.path)
+}
+
+// push force pushes refSpecs, or the default refspecs when none are given
+func (s *SSHCodebase) push(ctx context.Context, refSpecs []gitconfig.RefSpec) error {
+	auth, err := s.auth()
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.PushContext(ctx, &git.PushOptions{
+		RemoteURL:  s.RepoURL,
+		RemoteName: "origin",
+		RefSpecs:   refSpecs,
+		Force:      true,
+		Auth:       auth,
+	})
+	if errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return nil
+	}
+	return err
+}
+
+// auth authenticates with the private key as the user of the remote URL, only trusting the remote's known host keys
+func (s *SSHCodebase) auth() (transport.AuthMethod, error) {
+	endpoint, err := transport.NewEndpoint(s.RepoURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SSH repo URL: %w", err)
+	}
+	if endpoint.Protocol != "ssh" {
+		return nil, fmt.Errorf("repo URL %s is not an SSH remote", s.RepoURL)
+	}
+	user := endpoint.User
+	if user == "" {
+		user = defaultSSHUser
+	}
+
+	auth, err := gitssh.NewPublicKeys(user, []byte(s.PrivateKey), "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SSH private key: %w", err)
+	}
+	auth.HostKeyCallback, err = s.hostKeyCallback()
+	if err != nil {
+		return nil, err
+	}
+	return auth, nil
+}
+
+// hostKeyCallback verifies host keys against KnownHosts, or the known_hosts files when it's empty. Unknown hosts are
+// rejected rather than trusted on first use.
+func (s *SSHCodebase) hostKeyCallback() (ssh.HostKeyCallback, error) {
+	if s.KnownHosts == "" {
+		// Without a file the SSH_KNOWN_HOSTS variable, ~/.ssh/known_hosts and /etc/ssh/ssh_known_hosts are read
+		var files []string
+		if s.KnownHostsFile != "" {
+			files = append(files, s.KnownHostsFile)
+		}
+		callback, err := gitssh.NewKnownHostsCallback(files...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load known hosts: %w", err)
+		}
+		return callback, nil
+	}
+
+	// The entries are read into memory, so the file is only needed while the callback is built
+	file, err := os.CreateTemp("", "known_hosts")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create known hosts file: %w", err)
+	}
+	defer func() {
+		_ = os.Remove(file.Name())
+	}()
+	if _, err := file.WriteString(s.KnownHosts); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("failed to write known hosts file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write known hosts file: %w", err)
+	}
+
+	callback, err := gitssh.NewKnownHostsCallback(file.Name())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse known hosts: %w", err)
+	}
+	return callback, nil
+}
@@ -0,0 +1,79 @@
+package codebase_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"path/filepath"
+	"testing"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/codebase"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func newTestSSHKey(t *testing.T) string {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	block, err := ssh.MarshalPrivateKey(key, "")
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(block))
+}
+
+func TestSSHCodebase_GetPath(t *testing.T) {
+	r := codebase.NewSSHCodebase(config.GitConfig{CodebaseURL: "git@git.acme.com:platform/payments.git"}, "", "")
+
+	assert.Equal(t, "payments", r.GetPath())
+}
+
+func TestSSHCodebase_CloneRevision_Rejected(t *testing.T) {
+	tests := []struct {
+		name       string
+		url        string
+		privateKey string
+		knownHosts string
+		errMessage string
+	}{
+		{
+			name:       "not an SSH remote",
+			url:        "https://git.acme.com/platform/payments.git",
+			privateKey: newTestSSHKey(t),
+			errMessage: "is not an SSH remote",
+		},
+		{
+			name:       "invalid private key",
+			url:        "ssh://git@git.acme.com/platform/payments.git",
+			privateKey: "not a key",
+			errMessage: "failed to parse SSH private key",
+		},
+		{
+			name:       "invalid known hosts",
+			url:        "git@git.acme.com:platform/payments.git",
+			privateKey: newTestSSHKey(t),
+			knownHosts: "git.acme.com not-a-key-type AAAA",
+			errMessage: "failed to parse known hosts",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := codebase.NewSSHCodebaseAt(config.GitConfig{CodebaseURL: tt.url}, tt.privateKey, tt.knownHosts, filepath.Join(t.TempDir(), "repo"))
+
+			err := r.CloneRevision(context.Background(), "", "")
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMessage)
+		})
+	}
+}
+
+func TestSSHCodebase_UpdatePR(t *testing.T) {
+	r := codebase.NewSSHCodebase(config.GitConfig{CodebaseURL: "git@git.acme.com:platform/payments.git"}, "", "")
+
+	err := r.UpdatePR(context.Background(), 1, "Refactor auth", "")
+
+	assert.ErrorIs(t, err, codebase.ErrPullRequestsUnsupported)
+}
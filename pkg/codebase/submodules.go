@@ -0,0 +1,122 @@
+package codebase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	gitHttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+)
+
+// maxSubmoduleDepth bounds how deeply nested submodules are cloned
+const maxSubmoduleDepth = 5
+
+// fetchNested checks out the submodules of a cloned repository recursively and smudges the LFS pointer files of the
+// repository and its submodules, with the credentials remoteAuth resolves for each remote. Nested content that can't
+// be fetched is logged and left out rather than failing the clone.
+func fetchNested(ctx context.Context, repo *git.Repository, dir, repoURL string, auth transport.AuthMethod, options cloneOptions) {
+	budget := options.lfsMaxSize
+	fetchNestedAt(ctx, repo, dir, repoURL, auth, options, &budget, 0)
+}
+
+// fetchNestedAt fetches the nested content of the repository at dir, depth submodules deep, from the LFS budget
+func fetchNestedAt(ctx context.Context, repo *git.Repository, dir, repoURL string, auth transport.AuthMethod, options cloneOptions, budget *int64, depth int) {
+	if *budget > 0 {
+		authFor := func(endpoint string) transport.AuthMethod {
+			return options.remoteAuth(endpoint, repoURL, auth)
+		}
+		if err := smudgeLFS(ctx, dir, repoURL, authFor, budget); err != nil {
+			slog.WarnContext(ctx, "failed to smudge LFS pointer files", "error", err, "url", repoURL)
+		}
+	}
+
+	// Sparse checkouts leave out the .gitmodules file the submodules are read from
+	if !options.submodules || len(options.sparsePaths) > 0 {
+		return
+	}
+	if depth >= maxSubmoduleDepth {
+		slog.WarnContext(ctx, "submodules nested too deeply, leaving them out", "url", repoURL, "depth", depth)
+		return
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		slog.WarnContext(ctx, "failed to get worktree", "error", err, "url", repoURL)
+		return
+	}
+	submodules, err := wt.Submodules()
+	if err != nil {
+		slog.WarnContext(ctx, "failed to read submodules", "error", err, "url", repoURL)
+		return
+	}
+	for _, submodule := range submodules {
+		cfg := submodule.Config()
+		subRepo, subURL, subAuth, err := updateSubmodule(ctx, submodule, repoURL, auth, options)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to clone submodule, leaving it out", "error", err, "url", repoURL, "submodule", cfg.Name)
+			continue
+		}
+		fetchNestedAt(ctx, subRepo, filepath.Join(dir, filepath.FromSlash(cfg.Path)), subURL, subAuth, options, budget, depth+1)
+	}
+}
+
+// updateSubmodule clones a submodule and checks out the commit its parent records, returning its repository, remote
+// URL and credentials. Relative submodule URLs are resolved against the parent's remote.
+func updateSubmodule(ctx context.Context, submodule *git.Submodule, parentURL string, parentAuth transport.AuthMethod, options cloneOptions) (*git.Repository, string, transport.AuthMethod, error) {
+	if err := submodule.Init(); err != nil && !errors.Is(err, git.ErrSubmoduleAlreadyInitialized) {
+		return nil, "", nil, fmt.Errorf("failed to initialize submodule: %w", err)
+	}
+	repo, err := submodule.Repository()
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to open submodule: %w", err)
+	}
+	remote, err := repo.Remote(git.DefaultRemoteName)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to read submodule remote: %w", err)
+	}
+	remoteURL := remote.Config().URLs[0]
+
+	auth := options.remoteAuth(remoteURL, parentURL, parentAuth)
+	if err := submodule.UpdateContext(ctx, &git.SubmoduleUpdateOptions{Auth: auth}); err != nil {
+		return nil, "", nil, fmt.Errorf("failed to update submodule: %w", err)
+	}
+	return repo, remoteURL, auth, nil
+}
+
+// remoteAuth resolves the credentials of a remote nested in the repository at parentURL. Remotes on the parent's host
+// use the parent's credentials, SSH remotes of SSH parents the parent's key, and HTTPS remotes the configured token of
+// their host. Other remotes are fetched anonymously.
+func (o cloneOptions) remoteAuth(remoteURL, parentURL string, parentAuth transport.AuthMethod) transport.AuthMethod {
+	remote, err := transport.NewEndpoint(remoteURL)
+	if err != nil {
+		return nil
+	}
+	isSSH := remote.Protocol == "ssh"
+	parentKeys, parentSSH := parentAuth.(*gitssh.PublicKeys)
+
+	if parent, err := transport.NewEndpoint(parentURL); err == nil && parentAuth != nil {
+		if parent.Host == remote.Host && parentSSH == isSSH {
+			return parentAuth
+		}
+	}
+	if isSSH {
+		if !parentSSH {
+			return nil
+		}
+		keys := *parentKeys
+		keys.User = remote.User
+		if keys.User == "" {
+			keys.User = defaultSSHUser
+		}
+		return &keys
+	}
+	if token := o.tokens[remote.Host]; token != "" && remote.Protocol == "https" {
+		return &gitHttp.BasicAuth{Username: o.username, Password: token}
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package codebase_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+func TestCloneRevision_ClonesSubmodules(t *testing.T) {
+	origin, _ := newOriginRepository(t)
+	lib, _ := newOriginRepository(t)
+	nested, _ := newOriginRepository(t)
+	runGit(t, lib, "-c", "protocol.file.allow=always", "submodule", "--quiet", "add", nested, "vendor/nested")
+	runGit(t, lib, "commit", "--quiet", "-m", "add nested")
+	runGit(t, origin, "-c", "protocol.file.allow=always", "submodule", "--quiet", "add", lib, "third_party/lib")
+	runGit(t, origin, "commit", "--quiet", "-m", "add lib")
+
+	dir := cloneRevision(t, config.GitConfig{CodebaseURL: origin, Submodules: true}, "main", "")
+
+	assert.FileExists(t, filepath.Join(dir, "third_party", "lib", "api", "handler.go"))
+	assert.FileExists(t, filepath.Join(dir, "third_party", "lib", "vendor", "nested", "web", "index.html"))
+
+	// Without submodules the nested code is left out
+	dir = cloneRevision(t, config.GitConfig{CodebaseURL: origin}, "main", "")
+
+	assert.NoFileExists(t, filepath.Join(dir, "third_party", "lib", "api", "handler.go"))
+}
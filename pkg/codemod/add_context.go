@@ -0,0 +1,176 @@
+package codemod
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// newAddContext creates the transform adding a leading ctx context.Context parameter to the function of package, or
+// to a method as Type.Method. Its calls pass the context of the function they're made in, or context.TODO() where
+// there's none. References that don't call the function, such as method values, fail the transform, and interfaces
+// the method implements aren't changed.
+func newAddContext(params map[string]string) (*transform, error) {
+	values, err := requireParams(TransformAddContext, params, "package", "function")
+	if err != nil {
+		return nil, err
+	}
+	pkgPath, symbol := values[0], values[1]
+	if _, _, err := splitSymbol(symbol); err != nil {
+		return nil, err
+	}
+
+	analyzer := &analysis.Analyzer{
+		Name: TransformAddContext,
+		Doc:  "adds a context.Context parameter to a function and passes a context at its calls",
+		Run: func(pass *analysis.Pass) (any, error) {
+			declared := false
+			for _, file := range pass.Files {
+				found, err := addContext(pass, file, pkgPath, symbol)
+				if err != nil {
+					return nil, err
+				}
+				declared = declared || found
+			}
+			if pass.Pkg.Path() == pkgPath && !declared {
+				return nil, fmt.Errorf("package %s has no function or method %s", pkgPath, symbol)
+			}
+			return nil, nil
+		},
+	}
+	return &transform{analyzer: analyzer, pkgPath: pkgPath}, nil
+}
+
+// addContext reports the edits of the function's declaration and calls in a file, and whether the file declares it
+func addContext(pass *analysis.Pass, file *ast.File, pkgPath, symbol string) (bool, error) {
+	// The context package is imported with the first edit referring to it
+	contextName := importName(file, pass.TypesInfo, "context")
+	var importEdits []analysis.TextEdit
+	if contextName == "" || contextName == "_" {
+		contextName = "context"
+		importEdits = addImports(file, "context")
+	}
+	report := func(pos ast.Node, message string, edit analysis.TextEdit, needsImport bool) {
+		edits := []analysis.TextEdit{edit}
+		if needsImport {
+			edits = append(edits, importEdits...)
+			importEdits = nil
+		}
+		pass.Report(analysis.Diagnostic{
+			Pos:            pos.Pos(),
+			Message:        message,
+			SuggestedFixes: []analysis.SuggestedFix{{Message: message, TextEdits: edits}},
+		})
+	}
+
+	declared := false
+	var stack []ast.Node
+	var err error
+	ast.Inspect(file, func(node ast.Node) bool {
+		if err != nil {
+			return false
+		}
+		if node == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		stack = append(stack, node)
+
+		ident, ok := node.(*ast.Ident)
+		if !ok || !isSymbol(objectOf(pass.TypesInfo, ident), pkgPath, symbol) {
+			return true
+		}
+
+		// The declaration takes the context as its first parameter
+		parent := stack[len(stack)-2]
+		if decl, ok := parent.(*ast.FuncDecl); ok && decl.Name == ident {
+			declared = true
+			params := decl.Type.Params
+			for _, field := range params.List {
+				for _, name := range field.Names {
+					if name.Name == "ctx" {
+						err = fmt.Errorf("%s already has a parameter named ctx", symbol)
+						return false
+					}
+				}
+			}
+			if len(params.List) > 0 && isContext(pass.TypesInfo.TypeOf(params.List[0].Type)) {
+				err = fmt.Errorf("%s already takes a context", symbol)
+				return false
+			}
+			param := "ctx " + contextName + ".Context"
+			if len(params.List) > 0 {
+				param += ", "
+			}
+			report(decl, fmt.Sprintf("add context parameter to %s", symbol),
+				analysis.TextEdit{Pos: params.Opening + 1, End: params.Opening + 1, NewText: []byte(param)}, true)
+			return true
+		}
+
+		// Calls pass the context in scope, or a new one
+		call, ok := callOf(pass, stack, ident)
+		if !ok {
+			err = fmt.Errorf("%s is referenced without being called at %s", symbol, position(pass.Fset, ident.Pos()))
+			return false
+		}
+		arg, needsImport := contextInScope(pass, stack), false
+		if arg == "" {
+			arg, needsImport = contextName+".TODO()", true
+		}
+		if len(call.Args) > 0 {
+			arg += ", "
+		}
+		report(call, fmt.Sprintf("pass a context to %s", symbol),
+			analysis.TextEdit{Pos: call.Lparen + 1, End: call.Lparen + 1, NewText: []byte(arg)}, needsImport)
+		return true
+	})
+	return declared, err
+}
+
+// callOf returns the call an identifier on top of the stack is the callee of
+func callOf(pass *analysis.Pass, stack []ast.Node, ident *ast.Ident) (*ast.CallExpr, bool) {
+	var callee ast.Expr = ident
+	i := len(stack) - 2
+	if selector, ok := stack[i].(*ast.SelectorExpr); ok && selector.Sel == ident {
+		// A method expression takes the receiver first, ahead of where the context would go
+		if selection := pass.TypesInfo.Selections[selector]; selection != nil && selection.Kind() == types.MethodExpr {
+			return nil, false
+		}
+		callee = selector
+		i--
+	}
+	call, ok := stack[i].(*ast.CallExpr)
+	if !ok || call.Fun != callee {
+		return nil, false
+	}
+	return call, true
+}
+
+// contextInScope returns the name of a context.Context parameter of the innermost function enclosing the top of the
+// stack that has one, or "" when none has
+func contextInScope(pass *analysis.Pass, stack []ast.Node) string {
+	for i := len(stack) - 1; i >= 0; i-- {
+		var funcType *ast.FuncType
+		switch fn := stack[i].(type) {
+		case *ast.FuncDecl:
+			funcType = fn.Type
+		case *ast.FuncLit:
+			funcType = fn.Type
+		default:
+			continue
+		}
+		for _, field := range funcType.Params.List {
+			if !isContext(pass.TypesInfo.TypeOf(field.Type)) {
+				continue
+			}
+			for _, name := range field.Names {
+				if name.Name != "_" {
+					return name.Name
+				}
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,117 @@
+// Package codemod rewrites Go modules with deterministic, parameterized transforms such as renaming a symbol, so
+// refactorings that don't need a model produce the same reviewable change on every run.
+package codemod
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Version identifies the behavior of the transforms. It's recorded with their output and bumped whenever a
+// transform changes the edits it makes, so a change can be reproduced with the version that made it.
+const Version = "1"
+
+// Names of the transforms
+const (
+	// TransformRenameSymbol renames a package-level symbol or a method and every reference to it
+	TransformRenameSymbol = "rename_symbol"
+
+	// TransformExtractInterface declares an interface of a type's exported methods next to the type
+	TransformExtractInterface = "extract_interface"
+
+	// TransformReplaceAPI replaces the references to a deprecated package-level symbol with another one
+	TransformReplaceAPI = "replace_deprecated_api"
+
+	// TransformAddContext adds a leading context.Context parameter to a function and passes a context at its calls
+	TransformAddContext = "add_context_parameter"
+)
+
+// Transforms lists the names of the transforms
+func Transforms() []string {
+	return []string{TransformAddContext, TransformExtractInterface, TransformRenameSymbol, TransformReplaceAPI}
+}
+
+// Spec selects a transform and its parameters. It's read from the input of codemod tasks.
+type Spec struct {
+	Transform string            `json:"transform"`
+	Params    map[string]string `json:"params"`
+}
+
+// ParseSpec reads a spec from a task's input and checks its transform and parameters
+func ParseSpec(input map[string]any) (*Spec, error) {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("invalid codemod input: %w", err)
+	}
+	var spec Spec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("invalid codemod input, expected a transform and string params: %w", err)
+	}
+	if _, err := spec.transform(); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// transform is a transform configured by a spec
+type transform struct {
+	analyzer *analysis.Analyzer
+
+	// Package that must be part of the module, such as the one declaring the renamed symbol; empty when any is fine
+	pkgPath string
+}
+
+// transform builds the transform the spec selects
+func (s Spec) transform() (*transform, error) {
+	switch s.Transform {
+	case TransformRenameSymbol:
+		return newRenameSymbol(s.Params)
+	case TransformExtractInterface:
+		return newExtractInterface(s.Params)
+	case TransformReplaceAPI:
+		return newReplaceAPI(s.Params)
+	case TransformAddContext:
+		return newAddContext(s.Params)
+	default:
+		return nil, fmt.Errorf("unknown codemod transform %q, expected one of %s", s.Transform, strings.Join(Transforms(), ", "))
+	}
+}
+
+// requireParams returns the values of the named parameters, failing when one is missing
+func requireParams(transform string, params map[string]string, names ...string) ([]string, error) {
+	values := make([]string, len(names))
+	for i, name := range names {
+		values[i] = strings.TrimSpace(params[name])
+		if values[i] == "" {
+			return nil, fmt.Errorf("transform %s requires the %s param", transform, name)
+		}
+	}
+	return values, nil
+}
+
+// splitSymbol splits a symbol of a package into its type and member for a method, such as Store.Get, or returns an
+// empty type for a package-level symbol
+func splitSymbol(symbol string) (typeName, name string, err error) {
+	parts := strings.Split(symbol, ".")
+	if len(parts) > 2 || slices.ContainsFunc(parts, func(part string) bool { return !token.IsIdentifier(part) }) {
+		return "", "", fmt.Errorf("invalid symbol %q, expected Name or Type.Method", symbol)
+	}
+	if len(parts) == 2 {
+		return parts[0], parts[1], nil
+	}
+	return "", parts[0], nil
+}
+
+// splitQualified splits a qualified symbol, such as io/ioutil.ReadFile, into its package path and name
+func splitQualified(qualified string) (pkgPath, name string, err error) {
+	i := strings.LastIndex(qualified, ".")
+	if i <= 0 || strings.Contains(qualified[i:], "/") || !token.IsIdentifier(qualified[i+1:]) {
+		return "", "", fmt.Errorf("invalid qualified symbol %q, expected import/path.Name", qualified)
+	}
+	return qualified[:i], qualified[i+1:], nil
+}
@@ -0,0 +1,264 @@
+package codemod
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeModule writes the files of a module named example.com/app to a temporary directory
+func writeModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	files["go.mod"] = "module example.com/app\n\ngo 1.24\n"
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	return dir
+}
+
+func readFile(t *testing.T, dir, name string) string {
+	t.Helper()
+	content, err := os.ReadFile(filepath.Join(dir, name))
+	require.NoError(t, err)
+	return string(content)
+}
+
+func TestParseSpec(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       map[string]any
+		expectedErr string
+	}{
+		{
+			name:  "rename_symbol",
+			input: map[string]any{"transform": "rename_symbol", "params": map[string]any{"package": "example.com/app/store", "symbol": "Store.Get", "new_name": "Fetch"}},
+		},
+		{
+			name:        "unknown_transform",
+			input:       map[string]any{"transform": "inline"},
+			expectedErr: `unknown codemod transform "inline"`,
+		},
+		{
+			name:        "missing_param",
+			input:       map[string]any{"transform": "replace_deprecated_api", "params": map[string]any{"old": "io/ioutil.ReadFile"}},
+			expectedErr: "requires the new param",
+		},
+		{
+			name:        "non_string_param",
+			input:       map[string]any{"transform": "rename_symbol", "params": map[string]any{"symbol": 1}},
+			expectedErr: "expected a transform and string params",
+		},
+		{
+			name:        "invalid_symbol",
+			input:       map[string]any{"transform": "add_context_parameter", "params": map[string]any{"package": "example.com/app", "function": "a.b.c"}},
+			expectedErr: `invalid symbol "a.b.c"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := ParseSpec(tt.input)
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.input["transform"], spec.Transform)
+		})
+	}
+}
+
+func TestApply_RenameSymbol(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"store/store.go": `package store
+
+type Store struct{}
+
+func (s *Store) Get(key string) string { return key }
+`,
+		"store/store_test.go": `package store
+
+import "testing"
+
+func TestGet(t *testing.T) {
+	if (&Store{}).Get("a") != "a" {
+		t.Fail()
+	}
+}
+`,
+		"main.go": `package main
+
+import "example.com/app/store"
+
+func main() {
+	s := &store.Store{}
+	_ = s.Get("key")
+}
+`,
+	})
+
+	result, err := Apply(context.Background(), dir, Spec{
+		Transform: TransformRenameSymbol,
+		Params:    map[string]string{"package": "example.com/app/store", "symbol": "Store.Get", "new_name": "Fetch"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"main.go", "store/store.go", "store/store_test.go"}, result.Files)
+	assert.Equal(t, []Change{
+		{File: "main.go", Line: 7, Message: "rename Store.Get to Fetch"},
+		{File: "store/store.go", Line: 5, Message: "rename Store.Get to Fetch"},
+		{File: "store/store_test.go", Line: 6, Message: "rename Store.Get to Fetch"},
+	}, result.Changes)
+	assert.Contains(t, readFile(t, dir, "store/store.go"), "func (s *Store) Fetch(key string) string")
+	assert.Contains(t, readFile(t, dir, "store/store_test.go"), `(&Store{}).Fetch("a")`)
+	assert.Contains(t, readFile(t, dir, "main.go"), `s.Fetch("key")`)
+}
+
+func TestApply_RenameSymbolConflict(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"app.go": "package app\n\nfunc Old() {}\n\nfunc New() {}\n",
+	})
+
+	_, err := Apply(context.Background(), dir, Spec{
+		Transform: TransformRenameSymbol,
+		Params:    map[string]string{"package": "example.com/app", "symbol": "Old", "new_name": "New"},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already declares New")
+	assert.Equal(t, "package app\n\nfunc Old() {}\n\nfunc New() {}\n", readFile(t, dir, "app.go"))
+}
+
+func TestApply_ExtractInterface(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"store/store.go": `package store
+
+import "context"
+
+// Store stores values
+type Store struct{}
+
+func (s *Store) Get(ctx context.Context, key string) (string, error) { return key, nil }
+
+func (s *Store) Put(key, value string) error { return nil }
+
+func (s *Store) reset() {}
+`,
+	})
+
+	result, err := Apply(context.Background(), dir, Spec{
+		Transform: TransformExtractInterface,
+		Params:    map[string]string{"package": "example.com/app/store", "type": "Store", "interface": "Getter", "methods": "Get"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"store/store.go"}, result.Files)
+	assert.Contains(t, readFile(t, dir, "store/store.go"), `// Store stores values
+type Store struct{}
+
+// Getter is the interface of the methods of Store
+type Getter interface {
+	Get(ctx context.Context, key string) (string, error)
+}
+
+var _ Getter = (*Store)(nil)
+`)
+}
+
+func TestApply_ReplaceAPI(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"read.go": `package app
+
+import (
+	"io/ioutil"
+	"strings"
+)
+
+func Read(name string) (string, error) {
+	data, err := ioutil.ReadFile(name)
+	return strings.TrimSpace(string(data)), err
+}
+`,
+	})
+
+	result, err := Apply(context.Background(), dir, Spec{
+		Transform: TransformReplaceAPI,
+		Params:    map[string]string{"old": "io/ioutil.ReadFile", "new": "os.ReadFile"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"read.go"}, result.Files)
+	content := readFile(t, dir, "read.go")
+	assert.Contains(t, content, "data, err := os.ReadFile(name)")
+	assert.Contains(t, content, "import (\n\t\"os\"\n\t\"strings\"\n)")
+}
+
+func TestApply_AddContext(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"app.go": `package app
+
+import "context"
+
+func Load(key string) string { return key }
+
+func Handle(ctx context.Context) string {
+	return Load("a")
+}
+`,
+		"cmd/main.go": `package main
+
+import "example.com/app"
+
+func main() {
+	_ = app.Load("b")
+}
+`,
+	})
+
+	result, err := Apply(context.Background(), dir, Spec{
+		Transform: TransformAddContext,
+		Params:    map[string]string{"package": "example.com/app", "function": "Load"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"app.go", "cmd/main.go"}, result.Files)
+	app := readFile(t, dir, "app.go")
+	assert.Contains(t, app, "func Load(ctx context.Context, key string) string")
+	assert.Contains(t, app, `return Load(ctx, "a")`)
+	main := readFile(t, dir, "cmd/main.go")
+	assert.Contains(t, main, `app.Load(context.TODO(), "b")`)
+	assert.Contains(t, main, "import (\n\t\"context\"\n\t\"example.com/app\"\n)")
+}
+
+func TestApply_AddContextMethodValue(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"app.go": "package app\n\nfunc Load() {}\n\nvar loader = Load\n",
+	})
+
+	_, err := Apply(context.Background(), dir, Spec{
+		Transform: TransformAddContext,
+		Params:    map[string]string{"package": "example.com/app", "function": "Load"},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "referenced without being called at app.go:5")
+}
+
+func TestApply_BrokenModule(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"app.go": "package app\n\nfunc Load() { undefined() }\n",
+	})
+
+	_, err := Apply(context.Background(), dir, Spec{
+		Transform: TransformRenameSymbol,
+		Params:    map[string]string{"package": "example.com/app", "symbol": "Load", "new_name": "Fetch"},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "module doesn't type check")
+}
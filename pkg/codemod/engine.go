@@ -0,0 +1,220 @@
+package codemod
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"go/format"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+)
+
+// loadMode loads the syntax and types of the module's packages and their dependencies, as the checker requires
+const loadMode = packages.LoadAllSyntax | packages.NeedModule
+
+// Result is the outcome of a codemod
+type Result struct {
+	Files   []string `json:"files"`   // Changed files, relative to the module directory, sorted
+	Changes []Change `json:"changes"` // Changes made, sorted by file and line
+}
+
+// Change is a change a transform made
+type Change struct {
+	File    string `json:"file"` // Relative to the module directory
+	Line    int    `json:"line"` // Line of the change before it was made, from 1
+	Message string `json:"message"`
+}
+
+// edit replaces the bytes from start to end of a file
+type edit struct {
+	file       string
+	start, end int
+	text       string
+}
+
+// Apply applies the transform selected by spec to the Go module checked out in dir, test files included, and
+// formats the changed files. The module must type check before the change and after it, so a transform that would
+// break the build fails instead; the files it changed are then left changed for the caller to discard. Files
+// excluded by the build constraints of the host aren't changed.
+func Apply(ctx context.Context, dir string, spec Spec) (*Result, error) {
+	t, err := spec.transform()
+	if err != nil {
+		return nil, err
+	}
+	if dir, err = filepath.Abs(dir); err == nil {
+		dir, err = filepath.EvalSymlinks(dir)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve module directory: %w", err)
+	}
+
+	pkgs, err := load(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	if t.pkgPath != "" && !slices.ContainsFunc(pkgs, func(pkg *packages.Package) bool { return pkg.PkgPath == t.pkgPath }) {
+		return nil, fmt.Errorf("package %s is not part of the module", t.pkgPath)
+	}
+
+	graph, err := checker.Analyze([]*analysis.Analyzer{t.analyzer}, pkgs, &checker.Options{Sequential: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to run transform %s: %w", spec.Transform, err)
+	}
+
+	// Packages compiled with and without their tests report the same edits of the files they share
+	var edits []edit
+	var changes []Change
+	for _, act := range graph.Roots {
+		if act.Err != nil {
+			return nil, fmt.Errorf("transform %s failed: %s", spec.Transform, strings.ReplaceAll(act.Err.Error(), dir+string(filepath.Separator), ""))
+		}
+		fset := act.Package.Fset
+		for _, diagnostic := range act.Diagnostics {
+			position := fset.Position(diagnostic.Pos)
+			file, err := relativePath(dir, position.Filename)
+			if err != nil {
+				return nil, err
+			}
+			changes = append(changes, Change{File: file, Line: position.Line, Message: diagnostic.Message})
+
+			for _, fix := range diagnostic.SuggestedFixes {
+				for _, textEdit := range fix.TextEdits {
+					start, end := fset.Position(textEdit.Pos), fset.Position(textEdit.End)
+					if end.Filename == "" {
+						end = start
+					}
+					edits = append(edits, edit{file: start.Filename, start: start.Offset, end: end.Offset, text: string(textEdit.NewText)})
+				}
+			}
+		}
+	}
+
+	files, err := applyEdits(dir, dedupe(edits))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) > 0 {
+		if _, err := load(ctx, dir); err != nil {
+			return nil, fmt.Errorf("transform %s broke the module: %w", spec.Transform, err)
+		}
+	}
+
+	changes = dedupe(changes)
+	slices.SortStableFunc(changes, func(a, b Change) int {
+		return cmp.Or(cmp.Compare(a.File, b.File), cmp.Compare(a.Line, b.Line))
+	})
+	return &Result{Files: files, Changes: changes}, nil
+}
+
+// load loads the packages of the module in dir with their tests, failing when one doesn't type check
+func load(ctx context.Context, dir string) ([]*packages.Package, error) {
+	loaded, err := packages.Load(&packages.Config{Context: ctx, Dir: dir, Mode: loadMode, Tests: true}, "./...")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load packages: %w", err)
+	}
+
+	// The generated main packages running the tests aren't part of the module
+	var pkgs []*packages.Package
+	var errs []string
+	for _, pkg := range loaded {
+		if strings.HasSuffix(pkg.ID, ".test") {
+			continue
+		}
+		for _, pkgErr := range pkg.Errors {
+			errs = append(errs, strings.ReplaceAll(pkgErr.Error(), dir+string(filepath.Separator), ""))
+		}
+		pkgs = append(pkgs, pkg)
+	}
+	if len(errs) > 0 {
+		errs = dedupe(errs)
+		return nil, fmt.Errorf("module doesn't type check: %s", strings.Join(errs[:min(len(errs), 5)], "; "))
+	}
+	if len(pkgs) == 0 {
+		return nil, fmt.Errorf("no Go packages found")
+	}
+	return pkgs, nil
+}
+
+// applyEdits applies the edits to the files of the module in dir, formats them and returns their paths relative to
+// dir. Edits of a file must not overlap, except for insertions at the same offset, which are made in order.
+func applyEdits(dir string, edits []edit) ([]string, error) {
+	byFile := map[string][]edit{}
+	for _, e := range edits {
+		byFile[e.file] = append(byFile[e.file], e)
+	}
+
+	var files []string
+	for _, file := range slices.Sorted(maps.Keys(byFile)) {
+		rel, err := relativePath(dir, file)
+		if err != nil {
+			return nil, err
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat %s: %w", rel, err)
+		}
+		src, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", rel, err)
+		}
+
+		fileEdits := byFile[file]
+		slices.SortStableFunc(fileEdits, func(a, b edit) int {
+			return cmp.Or(cmp.Compare(a.start, b.start), cmp.Compare(a.end, b.end))
+		})
+		var out bytes.Buffer
+		offset := 0
+		for _, e := range fileEdits {
+			if e.start < offset || e.end > len(src) {
+				return nil, fmt.Errorf("conflicting edits of %s at offset %d", rel, e.start)
+			}
+			out.Write(src[offset:e.start])
+			out.WriteString(e.text)
+			offset = e.end
+		}
+		out.Write(src[offset:])
+
+		formatted, err := format.Source(out.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("transform produced invalid Go in %s: %w", rel, err)
+		}
+		if bytes.Equal(formatted, src) {
+			continue
+		}
+		if err := os.WriteFile(file, formatted, info.Mode().Perm()); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", rel, err)
+		}
+		files = append(files, rel)
+	}
+	return files, nil
+}
+
+// relativePath returns the path of a file of the module in dir relative to dir, failing for files outside of it
+func relativePath(dir, file string) (string, error) {
+	rel, err := filepath.Rel(dir, file)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("file %s is outside of the module", file)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// dedupe removes the repeated values of a slice, keeping the first of each
+func dedupe[T comparable](values []T) []T {
+	seen := make(map[T]bool, len(values))
+	unique := values[:0]
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
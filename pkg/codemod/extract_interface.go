@@ -0,0 +1,144 @@
+package codemod
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// newExtractInterface creates the transform declaring the interface named interface of the exported methods of the
+// type of package, or of the comma-separated methods, right after the type. A compile-time assertion that the type
+// implements the interface follows it.
+func newExtractInterface(params map[string]string) (*transform, error) {
+	values, err := requireParams(TransformExtractInterface, params, "package", "type", "interface")
+	if err != nil {
+		return nil, err
+	}
+	pkgPath, typeName, ifaceName := values[0], values[1], values[2]
+	if !token.IsIdentifier(typeName) {
+		return nil, fmt.Errorf("invalid type %q", typeName)
+	}
+	if !token.IsIdentifier(ifaceName) {
+		return nil, fmt.Errorf("invalid interface %q", ifaceName)
+	}
+	var methods []string
+	if list := strings.TrimSpace(params["methods"]); list != "" {
+		for _, method := range strings.Split(list, ",") {
+			method = strings.TrimSpace(method)
+			if !token.IsIdentifier(method) || !token.IsExported(method) {
+				return nil, fmt.Errorf("invalid method %q, expected an exported method name", method)
+			}
+			methods = append(methods, method)
+		}
+	}
+
+	analyzer := &analysis.Analyzer{
+		Name: TransformExtractInterface,
+		Doc:  "declares an interface of a type's methods",
+		Run: func(pass *analysis.Pass) (any, error) {
+			if pass.Pkg.Path() != pkgPath {
+				return nil, nil
+			}
+			return nil, extractInterface(pass, typeName, ifaceName, methods)
+		},
+	}
+	return &transform{analyzer: analyzer, pkgPath: pkgPath}, nil
+}
+
+// extractInterface reports the declaration of the interface after the type's declaration
+func extractInterface(pass *analysis.Pass, typeName, ifaceName string, methods []string) error {
+	typeObj, ok := pass.Pkg.Scope().Lookup(typeName).(*types.TypeName)
+	if !ok {
+		return fmt.Errorf("package %s has no type %s", pass.Pkg.Path(), typeName)
+	}
+	named, ok := typeObj.Type().(*types.Named)
+	if !ok || named.TypeParams().Len() > 0 {
+		return fmt.Errorf("type %s must be a non-generic named type", typeName)
+	}
+	if _, isInterface := named.Underlying().(*types.Interface); isInterface {
+		return fmt.Errorf("type %s is already an interface", typeName)
+	}
+	if pass.Pkg.Scope().Lookup(ifaceName) != nil {
+		return fmt.Errorf("package %s already declares %s", pass.Pkg.Path(), ifaceName)
+	}
+
+	file, decl := declarationOf(pass, typeObj)
+	if decl == nil {
+		return fmt.Errorf("declaration of type %s not found", typeName)
+	}
+	if strings.HasSuffix(pass.Fset.Position(decl.Pos()).Filename, "_test.go") {
+		return fmt.Errorf("type %s is declared in a test file", typeName)
+	}
+
+	// Types of other packages are referred to by the names the type's file imports them by, adding missing imports
+	var missing []string
+	imported := map[string]string{}
+	qualifier := func(pkg *types.Package) string {
+		if pkg == pass.Pkg {
+			return ""
+		}
+		name, ok := imported[pkg.Path()]
+		if !ok {
+			name = importName(file, pass.TypesInfo, pkg.Path())
+			if name == "" {
+				name = pkg.Name()
+				missing = append(missing, pkg.Path())
+			}
+			imported[pkg.Path()] = name
+		}
+		return name
+	}
+
+	methodSet := types.NewMethodSet(types.NewPointer(named))
+	var lines []string
+	for i := range methodSet.Len() {
+		method := methodSet.At(i).Obj().(*types.Func)
+		if !method.Exported() || (methods != nil && !slices.Contains(methods, method.Name())) {
+			continue
+		}
+		// Methods declared by test files aren't part of the type outside of tests
+		if strings.HasSuffix(pass.Fset.Position(method.Pos()).Filename, "_test.go") {
+			continue
+		}
+		signature := types.TypeString(method.Signature(), qualifier)
+		lines = append(lines, "\t"+method.Name()+strings.TrimPrefix(signature, "func"))
+	}
+	if len(lines) == 0 {
+		return fmt.Errorf("type %s has none of the methods to extract", typeName)
+	}
+	if methods != nil && len(lines) != len(methods) {
+		return fmt.Errorf("type %s lacks some of the methods %s", typeName, strings.Join(methods, ", "))
+	}
+
+	declaration := fmt.Sprintf("\n\n// %s is the interface of the methods of %s\ntype %s interface {\n%s\n}\n\nvar _ %s = (*%s)(nil)",
+		ifaceName, typeName, ifaceName, strings.Join(lines, "\n"), ifaceName, typeName)
+	edits := append(addImports(file, missing...), analysis.TextEdit{Pos: decl.End(), End: decl.End(), NewText: []byte(declaration)})
+
+	message := fmt.Sprintf("extract interface %s of %s", ifaceName, typeName)
+	pass.Report(analysis.Diagnostic{
+		Pos:            decl.Pos(),
+		Message:        message,
+		SuggestedFixes: []analysis.SuggestedFix{{Message: message, TextEdits: edits}},
+	})
+	return nil
+}
+
+// declarationOf returns the file and the declaration declaring a package-level object
+func declarationOf(pass *analysis.Pass, obj types.Object) (*ast.File, *ast.GenDecl) {
+	for _, file := range pass.Files {
+		if obj.Pos() < file.FileStart || obj.Pos() >= file.FileEnd {
+			continue
+		}
+		for _, decl := range file.Decls {
+			if gen, ok := decl.(*ast.GenDecl); ok && gen.Pos() <= obj.Pos() && obj.Pos() < gen.End() {
+				return file, gen
+			}
+		}
+	}
+	return nil, nil
+}
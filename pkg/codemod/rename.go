@@ -0,0 +1,93 @@
+package codemod
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// newRenameSymbol creates the transform renaming the symbol of package to new_name. The symbol is a package-level
+// function, type, variable or constant, or a method as Type.Method. Methods of interfaces the renamed method
+// implements aren't renamed.
+func newRenameSymbol(params map[string]string) (*transform, error) {
+	values, err := requireParams(TransformRenameSymbol, params, "package", "symbol", "new_name")
+	if err != nil {
+		return nil, err
+	}
+	pkgPath, symbol, newName := values[0], values[1], values[2]
+
+	typeName, name, err := splitSymbol(symbol)
+	if err != nil {
+		return nil, err
+	}
+	if !token.IsIdentifier(newName) {
+		return nil, fmt.Errorf("invalid new_name %q", newName)
+	}
+	if newName == name {
+		return nil, fmt.Errorf("symbol %s is already named %s", symbol, newName)
+	}
+
+	analyzer := &analysis.Analyzer{
+		Name: TransformRenameSymbol,
+		Doc:  "renames a symbol and every reference to it",
+		Run: func(pass *analysis.Pass) (any, error) {
+			if pass.Pkg.Path() == pkgPath {
+				if err := checkRename(pass.Pkg, typeName, name, newName); err != nil {
+					return nil, err
+				}
+			}
+
+			message := fmt.Sprintf("rename %s to %s", symbol, newName)
+			for _, file := range pass.Files {
+				ast.Inspect(file, func(node ast.Node) bool {
+					ident, ok := node.(*ast.Ident)
+					if !ok || !isSymbol(objectOf(pass.TypesInfo, ident), pkgPath, symbol) {
+						return true
+					}
+					pass.Report(analysis.Diagnostic{
+						Pos:     ident.Pos(),
+						Message: message,
+						SuggestedFixes: []analysis.SuggestedFix{{
+							Message:   message,
+							TextEdits: []analysis.TextEdit{{Pos: ident.Pos(), End: ident.End(), NewText: []byte(newName)}},
+						}},
+					})
+					return true
+				})
+			}
+			return nil, nil
+		},
+	}
+	return &transform{analyzer: analyzer, pkgPath: pkgPath}, nil
+}
+
+// checkRename checks the symbol exists in its package and the new name is free
+func checkRename(pkg *types.Package, typeName, name, newName string) error {
+	if typeName == "" {
+		if pkg.Scope().Lookup(name) == nil {
+			return fmt.Errorf("package %s has no symbol %s", pkg.Path(), name)
+		}
+		if pkg.Scope().Lookup(newName) != nil {
+			return fmt.Errorf("package %s already declares %s", pkg.Path(), newName)
+		}
+		return nil
+	}
+
+	typeObj, ok := pkg.Scope().Lookup(typeName).(*types.TypeName)
+	if !ok {
+		return fmt.Errorf("package %s has no type %s", pkg.Path(), typeName)
+	}
+	pointer := types.NewPointer(typeObj.Type())
+	if obj, _, _ := types.LookupFieldOrMethod(pointer, false, pkg, name); obj == nil {
+		return fmt.Errorf("type %s has no method %s", typeName, name)
+	} else if _, isMethod := obj.(*types.Func); !isMethod {
+		return fmt.Errorf("%s.%s is not a method", typeName, name)
+	}
+	if obj, _, _ := types.LookupFieldOrMethod(pointer, false, pkg, newName); obj != nil {
+		return fmt.Errorf("type %s already has a field or method %s", typeName, newName)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package codemod
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strconv"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// newReplaceAPI creates the transform replacing the references to the package-level symbol old, such as
+// io/ioutil.ReadFile, with the symbol new, such as os.ReadFile. The new package is imported where needed and the old
+// one is no longer imported where nothing else refers to it. The new symbol must be a drop-in replacement.
+func newReplaceAPI(params map[string]string) (*transform, error) {
+	values, err := requireParams(TransformReplaceAPI, params, "old", "new")
+	if err != nil {
+		return nil, err
+	}
+	oldPath, oldName, err := splitQualified(values[0])
+	if err != nil {
+		return nil, err
+	}
+	newPath, newName, err := splitQualified(values[1])
+	if err != nil {
+		return nil, err
+	}
+	if oldPath == newPath && oldName == newName {
+		return nil, fmt.Errorf("old and new are both %s", values[0])
+	}
+
+	analyzer := &analysis.Analyzer{
+		Name: TransformReplaceAPI,
+		Doc:  "replaces the references to a deprecated symbol",
+		Run: func(pass *analysis.Pass) (any, error) {
+			// The deprecated package's own references are left alone
+			if pass.Pkg.Path() == oldPath {
+				return nil, nil
+			}
+			message := fmt.Sprintf("replace %s with %s", values[0], values[1])
+			for _, file := range pass.Files {
+				replaceAPI(pass, file, oldPath, oldName, newPath, newName, message)
+			}
+			return nil, nil
+		},
+	}
+	return &transform{analyzer: analyzer}, nil
+}
+
+// replaceAPI reports the replacements of the references to the old symbol in a file
+func replaceAPI(pass *analysis.Pass, file *ast.File, oldPath, oldName, newPath, newName, message string) {
+	var selectors []*ast.SelectorExpr
+	oldPkgUses := 0
+	ast.Inspect(file, func(node ast.Node) bool {
+		selector, ok := node.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		pkgIdent, ok := selector.X.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		pkgName, ok := pass.TypesInfo.Uses[pkgIdent].(*types.PkgName)
+		if !ok || pkgName.Imported().Path() != oldPath {
+			return true
+		}
+		oldPkgUses++
+		if isSymbol(pass.TypesInfo.Uses[selector.Sel], oldPath, oldName) {
+			selectors = append(selectors, selector)
+		}
+		return true
+	})
+	if len(selectors) == 0 {
+		return
+	}
+
+	// The new symbol is referred to unqualified in its own package, and by the name its package is imported by elsewhere
+	replacement := newName
+	importNew := false
+	if pass.Pkg.Path() != newPath {
+		name := importName(file, pass.TypesInfo, newPath)
+		if name == "" || name == "_" {
+			name, importNew = defaultImportName(newPath), true
+		}
+		if name != "." {
+			replacement = name + "." + newName
+		}
+	}
+
+	var importEdits []analysis.TextEdit
+	if oldPkgUses == len(selectors) {
+		for _, spec := range file.Imports {
+			if importPath(spec) != oldPath {
+				continue
+			}
+			// An unnamed old import turns into the new one
+			if importNew && spec.Name == nil {
+				importEdits = append(importEdits, analysis.TextEdit{Pos: spec.Path.Pos(), End: spec.Path.End(), NewText: []byte(strconv.Quote(newPath))})
+				importNew = false
+				continue
+			}
+			importEdits = append(importEdits, removeImport(pass.Fset, file, spec))
+		}
+	}
+	if importNew {
+		importEdits = append(importEdits, addImports(file, newPath)...)
+	}
+
+	for i, selector := range selectors {
+		edits := []analysis.TextEdit{{Pos: selector.Pos(), End: selector.End(), NewText: []byte(replacement)}}
+		// The imports change with the first replacement of the file
+		if i == 0 {
+			edits = append(edits, importEdits...)
+		}
+		pass.Report(analysis.Diagnostic{
+			Pos:            selector.Pos(),
+			Message:        message,
+			SuggestedFixes: []analysis.SuggestedFix{{Message: message, TextEdits: edits}},
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package codemod
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// symbolOf returns the name of a package-level object, or Type.Method for a method, and false for any other object
+func symbolOf(obj types.Object) (string, bool) {
+	if obj == nil || obj.Pkg() == nil {
+		return "", false
+	}
+
+	if fn, ok := obj.(*types.Func); ok {
+		fn = fn.Origin()
+		if recv := fn.Signature().Recv(); recv != nil {
+			named := namedOf(recv.Type())
+			if named == nil {
+				return "", false
+			}
+			return named.Obj().Name() + "." + fn.Name(), true
+		}
+	}
+	if obj.Parent() != obj.Pkg().Scope() {
+		return "", false
+	}
+	return obj.Name(), true
+}
+
+// isSymbol reports whether obj is the symbol of the package with pkgPath
+func isSymbol(obj types.Object, pkgPath, symbol string) bool {
+	name, ok := symbolOf(obj)
+	return ok && name == symbol && obj.Pkg().Path() == pkgPath
+}
+
+// namedOf returns the named type of a type or of a pointer to it, nil for any other type
+func namedOf(t types.Type) *types.Named {
+	if pointer, ok := t.(*types.Pointer); ok {
+		t = pointer.Elem()
+	}
+	named, _ := types.Unalias(t).(*types.Named)
+	return named
+}
+
+// objectOf returns the object an identifier refers to, preferring the type an embedded field refers to over the
+// field it declares
+func objectOf(info *types.Info, ident *ast.Ident) types.Object {
+	if obj := info.Uses[ident]; obj != nil {
+		return obj
+	}
+	return info.Defs[ident]
+}
+
+// isContext reports whether t is context.Context
+func isContext(t types.Type) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "context" && named.Obj().Name() == "Context"
+}
+
+// importName returns the name a file refers to the package with path by, or "" when the file doesn't import it
+func importName(file *ast.File, info *types.Info, path string) string {
+	for _, spec := range file.Imports {
+		if importPath(spec) != path {
+			continue
+		}
+		if spec.Name != nil {
+			return spec.Name.Name
+		}
+		if pkgName, ok := info.Implicits[spec].(*types.PkgName); ok {
+			return pkgName.Name()
+		}
+		return defaultImportName(path)
+	}
+	return ""
+}
+
+// importPath returns the path of an import
+func importPath(spec *ast.ImportSpec) string {
+	path, err := strconv.Unquote(spec.Path.Value)
+	if err != nil {
+		return ""
+	}
+	return path
+}
+
+// defaultImportName guesses the name of the package with path from its last element, skipping a major version
+func defaultImportName(path string) string {
+	elements := strings.Split(path, "/")
+	name := elements[len(elements)-1]
+	if len(elements) > 1 && len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		name = elements[len(elements)-2]
+	}
+	return strings.NewReplacer("-", "_", ".", "_").Replace(name)
+}
+
+// addImports returns the edits importing the paths into a file, grouping a lone unparenthesized import with them
+func addImports(file *ast.File, paths ...string) []analysis.TextEdit {
+	if len(paths) == 0 {
+		return nil
+	}
+	var specs strings.Builder
+	for _, path := range paths {
+		specs.WriteString("\n\t" + strconv.Quote(path))
+	}
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		if gen.Lparen.IsValid() {
+			return []analysis.TextEdit{{Pos: gen.Lparen + 1, End: gen.Lparen + 1, NewText: []byte(specs.String())}}
+		}
+		spec := gen.Specs[0]
+		return []analysis.TextEdit{
+			{Pos: spec.Pos(), End: spec.Pos(), NewText: []byte("(" + specs.String() + "\n\t")},
+			{Pos: spec.End(), End: spec.End(), NewText: []byte("\n)")},
+		}
+	}
+	text := "\n\nimport " + strings.TrimPrefix(specs.String(), "\n\t")
+	if len(paths) > 1 {
+		text = "\n\nimport (" + specs.String() + "\n)"
+	}
+	return []analysis.TextEdit{{Pos: file.Name.End(), End: file.Name.End(), NewText: []byte(text)}}
+}
+
+// removeImport returns the edit removing the lines of an import, along with its declaration when it's its only import
+func removeImport(fset *token.FileSet, file *ast.File, spec *ast.ImportSpec) analysis.TextEdit {
+	var node ast.Node = spec
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT && len(gen.Specs) == 1 && gen.Specs[0] == spec {
+			node = gen
+		}
+	}
+
+	tokFile := fset.File(node.Pos())
+	start := tokFile.LineStart(tokFile.Line(node.Pos()))
+	end := token.Pos(tokFile.Base() + tokFile.Size())
+	if line := tokFile.Line(node.End()); line < tokFile.LineCount() {
+		end = tokFile.LineStart(line + 1)
+	}
+	return analysis.TextEdit{Pos: start, End: end}
+}
+
+// position formats the position of a node for an error
+func position(fset *token.FileSet, pos token.Pos) string {
+	p := fset.Position(pos)
+	return fmt.Sprintf("%s:%d", p.Filename, p.Line)
+}
@@ -0,0 +1,237 @@
+// Package codeowners parses the CODEOWNERS files of GitHub and GitLab repositories and resolves the owners of paths.
+// Patterns follow the gitignore rules both hosts use: the last rule matching a path gives its owners. GitLab sections
+// are resolved independently and their owners combined, and rules without owners take the default owners of their
+// section.
+package codeowners
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Locations are the paths CODEOWNERS files are looked up at, relative to the repository root, in the order GitHub and
+// GitLab look them up
+var Locations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS", ".gitlab/CODEOWNERS"}
+
+// maxFileSize is the largest CODEOWNERS file read; GitHub ignores files above 3 MB
+const maxFileSize = 3 << 20
+
+// sectionHeader matches GitLab section headers, such as [Backend], ^[Docs][2] @acme/writers
+var sectionHeader = regexp.MustCompile(`^\^?\[([^\]]+)\](?:\[\d+\])?\s*(.*)$`)
+
+// Rule gives the owners of the paths matching a pattern
+type Rule struct {
+	// Gitignore-style pattern, as written in the file
+	Pattern string `json:"pattern"`
+	// Users, teams and emails owning the matching paths; empty for paths explicitly left without owners
+	Owners []string `json:"owners"`
+	// GitLab section of the rule, empty for the rules before any section and for GitHub files
+	Section string `json:"section,omitempty"`
+}
+
+// File is a parsed CODEOWNERS file
+type File struct {
+	rules    []Rule
+	patterns []*regexp.Regexp
+}
+
+// Parse parses the content of a CODEOWNERS file. Lines the hosts reject, such as negated patterns, are skipped, so a
+// single mistake doesn't leave the whole repository without owners.
+func Parse(content []byte) *File {
+	var rules []Rule
+	section := ""
+	var defaults []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxFileSize)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if match := sectionHeader.FindStringSubmatch(line); match != nil {
+			section = match[1]
+			defaults = ownerFields(match[2])
+			continue
+		}
+
+		fields := splitFields(line)
+		owners := ownerFields(strings.Join(fields[1:], " "))
+		if len(owners) == 0 {
+			owners = defaults
+		}
+		rules = append(rules, Rule{Pattern: fields[0], Owners: owners, Section: section})
+	}
+
+	return Compile(rules)
+}
+
+// Compile builds a file from parsed rules, such as rules stored after parsing. Rules with an invalid pattern are
+// skipped.
+func Compile(rules []Rule) *File {
+	file := &File{}
+	for _, rule := range rules {
+		pattern, err := compilePattern(rule.Pattern)
+		if err != nil {
+			continue
+		}
+		file.rules = append(file.rules, rule)
+		file.patterns = append(file.patterns, pattern)
+	}
+	return file
+}
+
+// Find parses the first CODEOWNERS file of a repository checked out at dir, returning its path relative to dir. It
+// returns nil and an empty path when the repository has none.
+func Find(dir string) (*File, string, error) {
+	for _, location := range Locations {
+		info, err := os.Stat(filepath.Join(dir, filepath.FromSlash(location)))
+		if errors.Is(err, fs.ErrNotExist) || (err == nil && info.IsDir()) {
+			continue
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to stat %s: %w", location, err)
+		}
+		if info.Size() > maxFileSize {
+			return nil, "", fmt.Errorf("%s is larger than %d bytes", location, maxFileSize)
+		}
+
+		content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(location)))
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read %s: %w", location, err)
+		}
+		return Parse(content), location, nil
+	}
+	return nil, "", nil
+}
+
+// Rules returns the rules of the file, in file order
+func (f *File) Rules() []Rule {
+	return slices.Clone(f.rules)
+}
+
+// Owners returns the owners of a slash-separated path relative to the repository root, in the order of their rules,
+// without duplicates. The last matching rule of each section gives the section's owners.
+func (f *File) Owners(name string) []string {
+	name = strings.TrimPrefix(name, "/")
+	matched := map[string]int{}
+	var sections []string
+	for i, pattern := range f.patterns {
+		if !pattern.MatchString(name) {
+			continue
+		}
+		section := f.rules[i].Section
+		if _, ok := matched[section]; !ok {
+			sections = append(sections, section)
+		}
+		matched[section] = i
+	}
+
+	var owners []string
+	for _, section := range sections {
+		for _, owner := range f.rules[matched[section]].Owners {
+			if !slices.Contains(owners, owner) {
+				owners = append(owners, owner)
+			}
+		}
+	}
+	return owners
+}
+
+// OwnersOf returns the owners of any of the paths, sorted. Empty paths are skipped.
+func (f *File) OwnersOf(names []string) []string {
+	var owners []string
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		for _, owner := range f.Owners(name) {
+			if !slices.Contains(owners, owner) {
+				owners = append(owners, owner)
+			}
+		}
+	}
+	slices.Sort(owners)
+	return owners
+}
+
+// splitFields splits a rule on whitespace, keeping escaped spaces in its pattern
+func splitFields(line string) []string {
+	escaped := strings.ReplaceAll(line, `\ `, "\x00")
+	fields := strings.Fields(escaped)
+	for i := range fields {
+		fields[i] = strings.ReplaceAll(fields[i], "\x00", " ")
+	}
+	return fields
+}
+
+// ownerFields returns the owners among whitespace-separated fields, @users, @org/teams and emails, up to a comment
+func ownerFields(text string) []string {
+	var owners []string
+	for _, field := range strings.Fields(text) {
+		if strings.HasPrefix(field, "#") {
+			break
+		}
+		if strings.Contains(field, "@") {
+			owners = append(owners, field)
+		}
+	}
+	return owners
+}
+
+// compilePattern converts a gitignore-style pattern to a regular expression matching the paths it covers, including
+// the paths under a matching directory. Patterns without a slash, other than a trailing one, match at any depth.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" || strings.HasPrefix(pattern, "!") {
+		return nil, fmt.Errorf("unsupported pattern %q", pattern)
+	}
+
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	if pattern == "" {
+		// A lone slash is the root directory, which contains every path
+		return regexp.Compile(`^`)
+	}
+
+	var expr strings.Builder
+	expr.WriteString("^")
+	if !anchored {
+		expr.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "/**") && i+3 == len(pattern):
+			expr.WriteString("/.*")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case c == '*':
+			expr.WriteString("[^/]*")
+		case c == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	if dirOnly {
+		expr.WriteString("/.*$")
+	} else {
+		expr.WriteString("(?:/.*)?$")
+	}
+
+	return regexp.Compile(expr.String())
+}
@@ -0,0 +1,94 @@
+package codeowners_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/codeowners"
+)
+
+const githubCodeowners = `# Default owners
+*       @acme/maintainers
+
+*.go    @acme/gophers
+/docs/  @acme/writers docs@acme.io
+billing/**/invoice*.go @alice
+/vendor/
+!generated.go @nobody
+scripts/deploy\ tool.sh @bob # deploys
+`
+
+func TestFile_Owners_GitHub(t *testing.T) {
+	file := codeowners.Parse([]byte(githubCodeowners))
+
+	tests := []struct {
+		path   string
+		owners []string
+	}{
+		{path: "README.md", owners: []string{"@acme/maintainers"}},
+		{path: "cmd/api/main.go", owners: []string{"@acme/gophers"}},
+		{path: "docs/guide/setup.md", owners: []string{"@acme/writers", "docs@acme.io"}},
+		{path: "api/docs/readme.md", owners: []string{"@acme/maintainers"}}, // anchored patterns only match at the root
+		{path: "billing/invoice.go", owners: []string{"@alice"}},
+		{path: "billing/eu/invoice_test.go", owners: []string{"@alice"}},
+		{path: "billing/charge.go", owners: []string{"@acme/gophers"}},
+		{path: "vendor/lib/lib.go", owners: nil},
+		{path: "scripts/deploy tool.sh", owners: []string{"@bob"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.owners, file.Owners(tt.path))
+		})
+	}
+	assert.Len(t, file.Rules(), 6, "negated patterns are skipped")
+}
+
+func TestFile_Owners_GitLabSections(t *testing.T) {
+	file := codeowners.Parse([]byte(`* @acme/maintainers
+
+[Backend] @acme/backend
+api/
+api/payments/ @carol
+
+^[Docs][2] @acme/writers
+*.md
+`))
+
+	assert.Equal(t, []string{"@acme/maintainers", "@acme/backend"}, file.Owners("api/tasks.go"))
+	assert.Equal(t, []string{"@acme/maintainers", "@carol", "@acme/writers"}, file.Owners("api/payments/README.md"))
+	assert.Equal(t, []string{"@acme/maintainers"}, file.Owners("web/app.ts"))
+	assert.Equal(t, "Backend", file.Rules()[1].Section)
+}
+
+func TestFile_OwnersOf(t *testing.T) {
+	file := codeowners.Compile([]codeowners.Rule{
+		{Pattern: "*.go", Owners: []string{"@gophers"}},
+		{Pattern: "/web/", Owners: []string{"@frontend", "@gophers"}},
+	})
+
+	assert.Equal(t, []string{"@frontend", "@gophers"}, file.OwnersOf([]string{"main.go", "web/app.ts", "README.md"}))
+	assert.Empty(t, file.OwnersOf([]string{"README.md"}))
+}
+
+func TestFind(t *testing.T) {
+	dir := t.TempDir()
+
+	file, location, err := codeowners.Find(dir)
+	require.NoError(t, err)
+	assert.Nil(t, file)
+	assert.Empty(t, location)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".gitlab"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".gitlab", "CODEOWNERS"), []byte("* @gitlab\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "CODEOWNERS"), []byte("* @root\n"), 0o600))
+
+	file, location, err = codeowners.Find(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "CODEOWNERS", location, "the root file comes before the GitLab one")
+	assert.Equal(t, []string{"@root"}, file.Owners("main.go"))
+}
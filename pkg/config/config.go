@@ -6,13 +6,17 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"net/url"
 	"os"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/logging"
 	"github.com/kelseyhightower/envconfig"
 )
 
@@ -25,9 +29,97 @@ type Config struct {
 	Cognito        CognitoConfig  `envconfig:"COGNITO"`
 	Metrics        MetricsConfig  `envconfig:"METRICS"`
 	Postgres       PostgresConfig `envconfig:"POSTGRES"`
+	StorageBackend StorageBackend `envconfig:"STORAGE_BACKEND" default:"postgres"`
+	SQLite         SQLiteConfig   `envconfig:"SQLITE"`
+	API            APIConfig      `envconfig:"API"`
+	Reports        ReportsConfig  `envconfig:"REPORTS"`
+
+	// CloudFormation stack the AWS resource settings are read from when they aren't set, one per environment
+	StackName string `envconfig:"STACK_NAME" default:"CodeRefactorInfra"`
+
+	// Remote configuration overrides
+	Remote RemoteConfig `envconfig:"CONFIG"`
+
+	// Notification channel configuration
+	Notifications NotificationsConfig `envconfig:"NOTIFICATIONS"`
+
+	// Git provider proxy behind the codebase browsing API
+	CodebaseBrowsing CodebaseBrowsingConfig `envconfig:"CODEBASE_BROWSING"`
+
+	// Static analyses run by code_analysis tasks
+	CodeAnalysis CodeAnalysisConfig `envconfig:"CODE_ANALYSIS"`
+
+	// Advisory source queried by dependency_audit tasks
+	DependencyAudit DependencyAuditConfig `envconfig:"DEPENDENCY_AUDIT"`
+
+	// Coverage runs of coverage_gap tasks
+	CoverageGap CoverageGapConfig `envconfig:"COVERAGE_GAP"`
+
+	// Pull request descriptions generated from diffs by the configured model
+	Generation GenerationConfig `envconfig:"GENERATION"`
+
+	// Secret and license scan run before repository content is ingested
+	IngestionScan IngestionScanConfig `envconfig:"INGESTION_SCAN"`
+
+	// Codebase watch resyncing agents after new commits
+	AgentResync AgentResyncConfig `envconfig:"AGENT_RESYNC"`
+
+	// Workflow engine configuration
+	Workflow WorkflowConfig `envconfig:"WORKFLOW"`
+
+	// Deadlines of API requests
+	HTTP HTTPConfig `envconfig:"HTTP"`
+
+	// Execution deadlines of tasks
+	Task TaskConfig `envconfig:"TASK"`
+
+	// Governance of the tags set on projects, codebases and codebase configurations
+	Tags TagsConfig `envconfig:"TAGS"`
+
+	// Large task inputs uploaded to S3 instead of sent as JSON
+	Uploads UploadsConfig `envconfig:"UPLOADS"`
+
+	// Codebases uploaded as archives rather than hosted on a git provider
+	CodebaseUploads CodebaseUploadsConfig `envconfig:"CODEBASE_UPLOADS"`
+
+	// Paths served without authentication, on top of the health, documentation and sign in endpoints
+	Auth AuthConfig `envconfig:"AUTH"`
+
+	// Device authorization flow signing in CLIs
+	DeviceAuth DeviceAuthConfig `envconfig:"DEVICE_AUTH"`
+
+	// Redis cache in front of hot reads
+	Cache CacheConfig `envconfig:"CACHE"`
+
+	// Directories codebases are cloned into for analyses
+	Workspace WorkspaceConfig `envconfig:"WORKSPACE"`
+
+	// Log of the LLM calls made while executing tasks
+	LLMLog LLMLogConfig `envconfig:"LLM_LOG"`
+
+	// Evaluation of the local agent against golden repositories
+	Eval EvalConfig `envconfig:"EVAL"`
+
+	// Check runs publishing the results of the tasks pull requests trigger
+	GitHubChecks GitHubChecksConfig `envconfig:"GITHUB_CHECKS"`
+
+	// Comments and transitions on the Jira issues tasks are linked to
+	Jira JiraConfig `envconfig:"JIRA"`
+
+	// Purges of the content deleted agents uploaded to S3, and reports of the content left behind
+	StorageLifecycle StorageLifecycleConfig `envconfig:"STORAGE_LIFECYCLE"`
+
+	// Retries and circuit breakers guarding the calls to Cognito, Bedrock, S3 and the git providers
+	Resilience ResilienceConfig `envconfig:"RESILIENCE"`
+
+	// Checks of the dependencies run before the service starts
+	Startup StartupConfig `envconfig:"STARTUP"`
 
 	// AI configuration (organized by provider)
 	AI AIConfig `envconfig:"AI"`
+
+	// Backends storing the embeddings of knowledge bases, selected per project
+	VectorStore VectorStoreConfig `envconfig:"VECTOR_STORE"`
 }
 
 // LocalAIConfig represents the configuration for local AI services
@@ -37,6 +129,141 @@ type LocalAIConfig struct {
 	Model          string `envconfig:"MODEL" default:"codellama:7b-instruct"`
 	ChromaURL      string `envconfig:"CHROMA_URL" default:"http://localhost:8000"`
 	EmbeddingModel string `envconfig:"EMBEDDING_MODEL" default:"all-MiniLM-L6-v2"`
+
+	// Tool-calling loop executing tasks with the local model
+	PromptVersion string `envconfig:"PROMPT_VERSION" default:"v1"`          // Version of the system prompt the model is given
+	MaxSteps      int    `envconfig:"MAX_STEPS" default:"20"`               // Model calls a task may make before it fails
+	MaxRepairs    int    `envconfig:"MAX_REPAIRS" default:"2"`              // Times the model is asked to fix an answer not matching the output schema
+	TestCommand   string `envconfig:"TEST_COMMAND" default:"go test ./..."` // Command the run_tests tool runs in the checkout
+
+	// Context the task prompt is packed with under the model's token budget
+	ContextWindow int `envconfig:"CONTEXT_WINDOW"`              // Tokens of the local models' window, overriding the registry's when set
+	ContextChunks int `envconfig:"CONTEXT_CHUNKS" default:"20"` // Chunks retrieved from the knowledge base of a task's agent, 0 to retrieve none
+
+	// Routing of the tasks asking for the auto model between a cheap and a strong model
+	Routing ModelRoutingConfig `envconfig:"ROUTING"`
+
+	// Store the content ingested into local knowledge bases is uploaded to
+	DataStore DataStoreConfig `envconfig:"DATA_STORE"`
+}
+
+// ModelRoutingConfig represents how the tasks asking for the auto model are routed. Documentation tasks and tasks about
+// a small diff run on the cheap model, unless the code they're about is complex, and other tasks on the strong model.
+// Tasks the cheap model answers invalidly are run again on the strong model.
+type ModelRoutingConfig struct {
+	CheapModel           string  `envconfig:"CHEAP_MODEL"`                        // Model of the simple tasks, defaults to the local model
+	StrongModel          string  `envconfig:"STRONG_MODEL"`                       // Model of the other tasks, defaults to the local model
+	MaxDiffLines         int     `envconfig:"MAX_DIFF_LINES" default:"200"`       // Most lines the diff of a simple task changes
+	MaxComplexity        int     `envconfig:"MAX_COMPLEXITY" default:"15"`        // Complexity of a changed function from which a task isn't simple
+	MaxAverageComplexity float64 `envconfig:"MAX_AVERAGE_COMPLEXITY" default:"5"` // Mean complexity of the codebase from which a task without a diff isn't simple
+}
+
+// DataStoreType names a kind of data store
+type DataStoreType string
+
+const (
+	// DataStoreFilesystem stores content in a local directory, for air-gapped deployments
+	DataStoreFilesystem DataStoreType = "filesystem"
+
+	// DataStoreS3 stores content in an AWS S3 bucket
+	DataStoreS3 DataStoreType = "s3"
+
+	// DataStoreGCS stores content in a Google Cloud Storage bucket
+	DataStoreGCS DataStoreType = "gcs"
+)
+
+// Decode parses a data store type, rejecting unknown ones
+func (t *DataStoreType) Decode(value string) error {
+	switch storeType := DataStoreType(value); storeType {
+	case DataStoreFilesystem, DataStoreS3, DataStoreGCS:
+		*t = storeType
+		return nil
+	default:
+		return fmt.Errorf("invalid data store type %q, expected filesystem, s3 or gcs", value)
+	}
+}
+
+// DataStoreConfig represents where the content ingested into knowledge bases is stored. Bedrock knowledge bases
+// always read theirs from S3.
+type DataStoreConfig struct {
+	Type               DataStoreType `envconfig:"TYPE" default:"filesystem"`                             // Kind of store
+	Root               string        `envconfig:"ROOT" default:"data"`                                   // Directory of the filesystem store
+	Bucket             string        `envconfig:"BUCKET"`                                                // Bucket of the s3 and gcs stores
+	GCSEndpoint        string        `envconfig:"GCS_ENDPOINT" default:"https://storage.googleapis.com"` // JSON API of the gcs store
+	GCSCredentialsFile string        `envconfig:"GCS_CREDENTIALS_FILE"`                                  // Service account key of the gcs store, the metadata server's account when unset
+}
+
+// StorageBackend names the backend storing projects, codebases, tasks, users and agents
+type StorageBackend string
+
+const (
+	// StorageBackendPostgres stores them in PostgreSQL
+	StorageBackendPostgres StorageBackend = "postgres"
+
+	// StorageBackendMemory keeps them in memory until the service stops, for demos and integration tests, along with
+	// the other core records. Postgres isn't used, and the features only it stores are disabled: device sign-in,
+	// project templates, reports and campaigns, notifications, task comments, feedback and LLM traces, findings and
+	// quality gates, dependency audits, code metrics and scans, agent resyncs, evals and experiments, GitHub checks,
+	// Jira links, service accounts and storage purges. The README lists their repositories.
+	StorageBackendMemory StorageBackend = "memory"
+
+	// StorageBackendSQLite stores them in a SQLite database file, for self-hosted deployments without a database server,
+	// along with the other core records. It disables the same features as StorageBackendMemory.
+	StorageBackendSQLite StorageBackend = "sqlite"
+)
+
+// Decode parses a storage backend, rejecting unknown ones
+func (b *StorageBackend) Decode(value string) error {
+	switch backend := StorageBackend(value); backend {
+	case StorageBackendPostgres, StorageBackendMemory, StorageBackendSQLite:
+		*b = backend
+		return nil
+	default:
+		return fmt.Errorf("invalid storage backend %q, expected postgres, memory or sqlite", value)
+	}
+}
+
+// VectorStoreType names a vector store backend
+type VectorStoreType string
+
+const (
+	// VectorStorePgvector stores vectors in PostgreSQL with the pgvector extension
+	VectorStorePgvector VectorStoreType = "pgvector"
+
+	// VectorStoreOpenSearch stores vectors in an Amazon OpenSearch Serverless collection
+	VectorStoreOpenSearch VectorStoreType = "opensearch"
+
+	// VectorStoreQdrant stores vectors in a Qdrant cluster
+	VectorStoreQdrant VectorStoreType = "qdrant"
+)
+
+// Decode parses a vector store type, rejecting unknown ones
+func (t *VectorStoreType) Decode(value string) error {
+	switch storeType := VectorStoreType(value); storeType {
+	case VectorStorePgvector, VectorStoreOpenSearch, VectorStoreQdrant:
+		*t = storeType
+		return nil
+	default:
+		return fmt.Errorf("invalid vector store type %q, expected pgvector, opensearch or qdrant", value)
+	}
+}
+
+// VectorStoreConfig represents the vector store backends. Projects use Default unless they select another backend,
+// and the pgvector backend connects with the Postgres settings.
+type VectorStoreConfig struct {
+	Default            VectorStoreType `envconfig:"DEFAULT" default:"pgvector"`                 // Backend of the projects not selecting one
+	OpenSearchEndpoint string          `envconfig:"OPENSEARCH_ENDPOINT"`                        // Endpoint of the OpenSearch Serverless collection
+	OpenSearchRegion   string          `envconfig:"OPENSEARCH_REGION" default:"us-east-1"`      // Region requests to the collection are signed for
+	QdrantURL          string          `envconfig:"QDRANT_URL" default:"http://localhost:6333"` // REST API of the Qdrant cluster
+	QdrantAPIKey       string          `envconfig:"QDRANT_API_KEY"`                             // API key of the Qdrant cluster, none when unset
+}
+
+// Backend returns the backend selected by a project, Default when it selects none
+func (c VectorStoreConfig) Backend(selected string) VectorStoreType {
+	if selected == "" {
+		return c.Default
+	}
+	return VectorStoreType(selected)
 }
 
 // BedrockAIConfig represents the configuration for AWS Bedrock AI services
@@ -47,6 +274,24 @@ type BedrockAIConfig struct {
 	FoundationModel             string      `envconfig:"FOUNDATION_MODEL" default:"amazon.titan-tg1-large"`
 	S3BucketName                string      `envconfig:"S3_BUCKET_NAME"`
 	RDSPostgres                 RDSPostgres `envconfig:"RDS_POSTGRES"`
+
+	// Regions agents may be provisioned in besides Region, and the bucket holding repository content in each of them,
+	// such as a cross-region replica of S3BucketName. Regions without their own bucket use S3BucketName.
+	AllowedRegions []string          `envconfig:"ALLOWED_REGIONS"`
+	S3BucketNames  map[string]string `envconfig:"S3_BUCKET_NAMES"`
+}
+
+// AllowsRegion reports whether agents may be provisioned in region, which is Region or one of AllowedRegions
+func (c BedrockAIConfig) AllowsRegion(region string) bool {
+	return region == c.Region || slices.Contains(c.AllowedRegions, region)
+}
+
+// BucketName returns the bucket holding repository content for the agents of region
+func (c BedrockAIConfig) BucketName(region string) string {
+	if bucketName, ok := c.S3BucketNames[region]; ok {
+		return bucketName
+	}
+	return c.S3BucketName
 }
 
 // AIConfig represents the overall AI configuration with provider-specific settings
@@ -57,6 +302,60 @@ type AIConfig struct {
 	// Provider-specific configurations
 	Local   LocalAIConfig   `envconfig:"LOCAL"`
 	Bedrock BedrockAIConfig `envconfig:"BEDROCK"`
+
+	// Uploads of the repositories knowledge bases ingest, by every data store
+	Upload UploadPipelineConfig `envconfig:"UPLOAD"`
+
+	// Concurrency limits of the calls to each provider's models
+	Limits ModelLimitConfig `envconfig:"LIMITS"`
+}
+
+// ModelLimitConfig represents the concurrency limits of the calls to the models. Calls past a model's limit wait in
+// turn, and the limit is lowered while the model throttles calls, then raised back up to its ceiling.
+type ModelLimitConfig struct {
+	DefaultCeiling int           `envconfig:"DEFAULT_CEILING" default:"8"` // Most concurrent calls to a model without a ceiling of its own, 0 disables limiting
+	Ceilings       ModelCeilings `envconfig:"CEILINGS"`                    // Ceilings by provider/model, such as bedrock/anthropic.claude-3-haiku-20240307-v1:0=4,ollama/llama3=2
+	MaxQueue       int           `envconfig:"MAX_QUEUE" default:"100"`     // Most calls waiting for a model, beyond which calls fail fast, 0 doesn't bound it
+	MaxRetries     int           `envconfig:"MAX_RETRIES" default:"5"`     // Retries of a call the model throttles
+	BaseDelay      time.Duration `envconfig:"BASE_DELAY" default:"1s"`     // Upper bound of the jittered delay before the first retry of a throttled call
+	MaxDelay       time.Duration `envconfig:"MAX_DELAY" default:"30s"`     // Upper bound of the jittered delay before any retry of a throttled call
+}
+
+// ModelCeilings maps provider/model names to the most concurrent calls to the model. It's read from comma separated
+// name=ceiling pairs, such as "bedrock/anthropic.claude-3-haiku-20240307-v1:0=4", since model IDs contain colons.
+type ModelCeilings map[string]int
+
+// Decode implements envconfig.Decoder
+func (c *ModelCeilings) Decode(value string) error {
+	ceilings := ModelCeilings{}
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		name, ceiling, ok := strings.Cut(pair, "=")
+		name = strings.TrimSpace(name)
+		provider, model, _ := strings.Cut(name, "/")
+		if !ok || provider == "" || model == "" {
+			return fmt.Errorf("invalid model ceiling %q, expected provider/model=ceiling", pair)
+		}
+		parsed, err := strconv.Atoi(strings.TrimSpace(ceiling))
+		if err != nil || parsed < 0 {
+			return fmt.Errorf("invalid ceiling of model %q, expected a non-negative number", name)
+		}
+		ceilings[name] = parsed
+	}
+
+	*c = ceilings
+	return nil
+}
+
+// UploadPipelineConfig configures how data stores upload the files of a repository
+type UploadPipelineConfig struct {
+	Workers    int `envconfig:"WORKERS" default:"16"`     // Files uploaded at the same time
+	PartSizeMB int `envconfig:"PART_SIZE_MB" default:"8"` // Size of the parts of S3 multipart uploads, which files larger than a part use; at least 5
+	Attempts   int `envconfig:"ATTEMPTS" default:"3"`     // Rounds uploading the files that failed, including the first one
 }
 
 // RDSPostgres represents the configuration for AWS RDS Postgres
@@ -82,6 +381,461 @@ type MetricsConfig struct {
 	Enabled     bool   `envconfig:"ENABLED" default:"true"`
 }
 
+// APIConfig represents the lifecycle configuration of the served REST API versions
+type APIConfig struct {
+	V1 APIVersionPolicy `envconfig:"V1"`
+	V2 APIVersionPolicy `envconfig:"V2"`
+}
+
+// APIVersionPolicy describes when an API version is deprecated and retired.
+// Zero times mean the version is not deprecated or has no sunset date.
+type APIVersionPolicy struct {
+	DeprecatedAt time.Time `envconfig:"DEPRECATED_AT"` // RFC 3339, may be in the future
+	SunsetAt     time.Time `envconfig:"SUNSET_AT"`     // RFC 3339, requests are rejected afterwards
+	Link         string    `envconfig:"DEPRECATION_LINK"`
+}
+
+// ReportsConfig represents the configuration of the task metrics rollups behind the reports API
+type ReportsConfig struct {
+	RefreshInterval time.Duration `envconfig:"REFRESH_INTERVAL" default:"15m"` // How often the rollups are recomputed
+	LookbackDays    int           `envconfig:"LOOKBACK_DAYS" default:"2"`      // Days recomputed on each refresh, covering late status changes
+}
+
+// NotificationsConfig represents the configuration of the email and Slack notification channels
+type NotificationsConfig struct {
+	EmailFrom string `envconfig:"EMAIL_FROM"` // Verified SES sender address, email channels are skipped when empty
+	SESRegion string `envconfig:"SES_REGION" default:"us-east-1"`
+
+	OutboxPollInterval time.Duration `envconfig:"OUTBOX_POLL_INTERVAL" default:"5s"` // How often the outbox is checked for notifications to deliver
+}
+
+// CacheConfig represents the configuration of the Redis cache in front of project, codebase configuration and user
+// reads. Reads fall back to the database whenever Redis can't be reached.
+type CacheConfig struct {
+	RedisAddress  string        `envconfig:"REDIS_ADDRESS"` // host:port of the Redis server, caching is disabled when empty
+	RedisPassword string        `envconfig:"REDIS_PASSWORD"`
+	RedisDB       int           `envconfig:"REDIS_DB" default:"0"`
+	RedisTLS      bool          `envconfig:"REDIS_TLS" default:"false"` // Required when the server enforces in-transit encryption
+	Timeout       time.Duration `envconfig:"TIMEOUT" default:"100ms"`   // Timeout of each Redis command before falling back to the database
+
+	ProjectTTL        time.Duration `envconfig:"PROJECT_TTL" default:"5m"`
+	CodebaseConfigTTL time.Duration `envconfig:"CODEBASE_CONFIG_TTL" default:"5m"`
+	UserTTL           time.Duration `envconfig:"USER_TTL" default:"1m"` // Kept short since the cached role and status gate every request
+}
+
+// Enabled reports whether a Redis server is configured
+func (c CacheConfig) Enabled() bool {
+	return c.RedisAddress != ""
+}
+
+// WorkspaceConfig represents the configuration of the directories codebases are cloned into for analyses. Released
+// workspaces are kept for later analyses of the same commit until the disk quota or their idle TTL is reached.
+type WorkspaceConfig struct {
+	Root       string        `envconfig:"ROOT"`                      // Directory holding the workspaces, a directory under the system temporary directory when empty
+	QuotaMB    int64         `envconfig:"QUOTA_MB" default:"10240"`  // Disk the workspaces of a task runner may use, new clones are refused past it
+	IdleTTL    time.Duration `envconfig:"IDLE_TTL" default:"1h"`     // How long a released workspace is kept for reuse
+	GCInterval time.Duration `envconfig:"GC_INTERVAL" default:"10m"` // How often orphaned and expired workspaces are removed
+	CloneCache string        `envconfig:"CLONE_CACHE"`               // Directory of the bare mirrors workspaces are cloned from, a directory under the system temporary directory when empty
+}
+
+// LLMLogConfig represents the configuration of the log of the LLM calls made while executing tasks. Projects whose
+// redaction policy disables LLM content logging only have the metadata of their calls logged.
+type LLMLogConfig struct {
+	Enabled       bool          `envconfig:"ENABLED" default:"false"`
+	Retention     time.Duration `envconfig:"RETENTION" default:"720h"`    // How long logged calls are kept
+	PurgeInterval time.Duration `envconfig:"PURGE_INTERVAL" default:"1h"` // How often calls past the retention are deleted
+}
+
+// EvalConfig represents the configuration of the evaluation runs of the local agent against golden repositories
+type EvalConfig struct {
+	FixturesDir string        `envconfig:"FIXTURES_DIR" default:"evals/fixtures"` // Directory of the fixtures, one subdirectory each
+	CaseTimeout time.Duration `envconfig:"CASE_TIMEOUT" default:"15m"`            // Time the agent, build and tests of a fixture may take
+}
+
+// GitHubChecksConfig represents the configuration of the GitHub check runs publishing the results of the tasks pull
+// request webhooks trigger. The GitHub App credentials are configured per organization through the API.
+type GitHubChecksConfig struct {
+	APIURL         string        `envconfig:"API_URL" default:"https://api.github.com"`                           // REST API of GitHub or a GitHub Enterprise Server
+	DetailsURL     string        `envconfig:"DETAILS_URL" default:"http://localhost:8080/api/v1/tasks/{task_id}"` // Link of the check runs, {task_id} is replaced by the task
+	CheckName      string        `envconfig:"CHECK_NAME" default:"code-refactoring-tool"`                         // Name of the check runs
+	PollInterval   time.Duration `envconfig:"POLL_INTERVAL" default:"15s"`                                        // How often finished tasks are published to their check runs
+	RequestTimeout time.Duration `envconfig:"REQUEST_TIMEOUT" default:"10s"`                                      // Timeout of each GitHub API request
+}
+
+// JiraConfig represents the configuration of the updates posted on the Jira issues tasks are linked to. The Jira
+// sites and their credentials are configured per organization through the API.
+type JiraConfig struct {
+	SyncInterval   time.Duration `envconfig:"SYNC_INTERVAL" default:"30s"`   // How often task status changes are posted on their issues
+	RequestTimeout time.Duration `envconfig:"REQUEST_TIMEOUT" default:"10s"` // Timeout of each Jira API request
+}
+
+// StorageLifecycleConfig represents the configuration of the purges of the S3 content of deleted agents and of the
+// reconciliation reporting content no agent owns
+type StorageLifecycleConfig struct {
+	PurgeInterval     time.Duration `envconfig:"PURGE_INTERVAL" default:"30s"`     // How often pending purges are claimed
+	PurgeLease        time.Duration `envconfig:"PURGE_LEASE" default:"5m"`         // How long a purge without progress stays claimed
+	MaxPurgeAttempts  int           `envconfig:"MAX_PURGE_ATTEMPTS" default:"5"`   // Attempts before a purge fails for good
+	ReconcileInterval time.Duration `envconfig:"RECONCILE_INTERVAL" default:"24h"` // How often orphaned prefixes are reported, zero disables it
+}
+
+// ResilienceConfig represents the configuration of the retries and circuit breakers guarding the calls to external
+// dependencies. Each dependency, and each region of a regional AWS service, has a circuit breaker of its own.
+type ResilienceConfig struct {
+	MaxAttempts      int           `envconfig:"MAX_ATTEMPTS" default:"3"`      // Attempts of a retried call, including the first one
+	BaseDelay        time.Duration `envconfig:"BASE_DELAY" default:"200ms"`    // Upper bound of the jittered delay before the first retry
+	MaxDelay         time.Duration `envconfig:"MAX_DELAY" default:"5s"`        // Upper bound of the jittered delay before any retry
+	FailureThreshold int           `envconfig:"FAILURE_THRESHOLD" default:"5"` // Consecutive transient failures opening a breaker, zero disables breakers
+	OpenTimeout      time.Duration `envconfig:"OPEN_TIMEOUT" default:"30s"`    // How long an open breaker fails calls fast before a trial call
+}
+
+// StartupConfig represents the checks of Postgres, Cognito, S3 and the Bedrock roles run before the service starts.
+// Every failed check is reported with a hint of how to fix it. Unless degraded, the service exits when any check
+// fails; degraded, it only exits without Postgres, and serves 503 Service Unavailable on the routes of the other
+// dependencies that failed.
+type StartupConfig struct {
+	Degraded     bool          `envconfig:"DEGRADED" default:"false"`    // Start without the dependencies other than Postgres that failed their check
+	CheckTimeout time.Duration `envconfig:"CHECK_TIMEOUT" default:"10s"` // Timeout of each check
+}
+
+// CodebaseBrowsingConfig represents the configuration of the git provider calls behind the codebase browsing API
+type CodebaseBrowsingConfig struct {
+	CacheTTL       time.Duration `envconfig:"CACHE_TTL" default:"2m"`        // How long branch, commit and file listings are served from cache
+	RequestTimeout time.Duration `envconfig:"REQUEST_TIMEOUT" default:"10s"` // Timeout of each git provider API request
+}
+
+// CodeAnalysisConfig represents the configuration of the static analyses run by code_analysis tasks
+type CodeAnalysisConfig struct {
+	DuplicateMinTokens        int `envconfig:"DUPLICATE_MIN_TOKENS" default:"75"`         // Smallest duplicated block reported, in tokens
+	GodPackageMinDeclarations int `envconfig:"GOD_PACKAGE_MIN_DECLARATIONS" default:"40"` // Fewest top-level declarations of a god package
+	GodPackageMinLines        int `envconfig:"GOD_PACKAGE_MIN_LINES" default:"2000"`      // Fewest lines of a god package
+}
+
+// DependencyAuditConfig represents the configuration of the advisory source queried by dependency_audit tasks
+type DependencyAuditConfig struct {
+	AdvisoryURL    string        `envconfig:"ADVISORY_URL" default:"https://api.osv.dev"` // Any advisory database serving the OSV API
+	RequestTimeout time.Duration `envconfig:"REQUEST_TIMEOUT" default:"30s"`
+}
+
+// IngestionScanConfig represents the configuration of the scan run before repository content is ingested
+type IngestionScanConfig struct {
+	IncompatibleLicenses []string `envconfig:"INCOMPATIBLE_LICENSES" default:"AGPL-3.0,SSPL-1.0,GPL-2.0,GPL-3.0"` // SPDX identifiers blocking ingestion
+}
+
+// AgentResyncConfig represents the configuration of the codebase watch resyncing agents after new commits
+type AgentResyncConfig struct {
+	PollInterval time.Duration `envconfig:"POLL_INTERVAL" default:"5m"` // How often default branches are polled, 0 disables the watch
+	Debounce     time.Duration `envconfig:"DEBOUNCE" default:"10m"`     // How long a branch must stop moving before its agents are resynced
+}
+
+// TagsConfig represents the governance of the tags set on projects, codebases and codebase configurations
+type TagsConfig struct {
+	RequireRegisteredKeys bool `envconfig:"REQUIRE_REGISTERED_KEYS" default:"false"` // Reject tags whose key isn't in the tag key registry
+}
+
+// UploadsConfig represents where the large task inputs uploaded through the API are stored. Their size is limited by
+// the body size of the upload route.
+type UploadsConfig struct {
+	Bucket string `envconfig:"BUCKET"`                        // Bucket the uploads are written to, the Bedrock bucket when unset
+	Prefix string `envconfig:"PREFIX" default:"task-inputs/"` // Key prefix of the uploads, followed by their project and upload IDs
+}
+
+// UploadsBucket returns the bucket the task input uploads are written to
+func (c *Config) UploadsBucket() string {
+	if c.Uploads.Bucket != "" {
+		return c.Uploads.Bucket
+	}
+	return c.AI.Bedrock.S3BucketName
+}
+
+// CodebaseUploadsConfig represents the codebases uploaded as ZIP files or tarballs, which are written to the uploads
+// bucket through presigned URLs and deleted along with their archive once they expire
+type CodebaseUploadsConfig struct {
+	Prefix           string        `envconfig:"PREFIX" default:"codebase-uploads/"`      // Key prefix of the archives, followed by their project and codebase IDs
+	MaxSize          int64         `envconfig:"MAX_SIZE" default:"524288000"`            // Largest archive in bytes
+	MaxExtractedSize int64         `envconfig:"MAX_EXTRACTED_SIZE" default:"2147483648"` // Largest size in bytes of the files of an archive, extractions past it fail
+	URLExpiry        time.Duration `envconfig:"URL_EXPIRY" default:"15m"`                // How long a presigned upload URL is valid
+	TTL              time.Duration `envconfig:"TTL" default:"168h"`                      // How long an uploaded codebase is kept after its creation
+	SweepInterval    time.Duration `envconfig:"SWEEP_INTERVAL" default:"1h"`             // How often expired codebases are deleted
+}
+
+// AuthConfig represents which API paths are served without authentication. The health, documentation and sign in
+// endpoints always are, unless a protected path covers them. Paths cover the paths below them, and the longest path
+// covering a request decides.
+type AuthConfig struct {
+	PublicPaths    PathPrefixes `envconfig:"PUBLIC_PATHS"`    // Paths served without authentication, such as /status
+	ProtectedPaths PathPrefixes `envconfig:"PROTECTED_PATHS"` // Paths requiring authentication even below a public path, such as /swagger
+}
+
+// PathPrefixes are API paths covering the paths below them. They're read from a comma separated list of paths
+// starting with a slash, and "/" covers every path.
+type PathPrefixes []string
+
+// Decode implements envconfig.Decoder
+func (p *PathPrefixes) Decode(value string) error {
+	var prefixes PathPrefixes
+	for _, path := range strings.Split(value, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		if !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("invalid path %q, expected a path starting with /", path)
+		}
+		prefixes = append(prefixes, strings.TrimRight(path, "/"))
+	}
+
+	*p = prefixes
+	return nil
+}
+
+// DeviceAuthConfig represents the device authorization flow signing in CLIs without a browser
+type DeviceAuthConfig struct {
+	VerificationURI string        `envconfig:"VERIFICATION_URI" default:"http://localhost:3000/device"` // Page where signed in users enter the user code shown by the CLI
+	CodeTTL         time.Duration `envconfig:"CODE_TTL" default:"10m"`                                  // How long a device code can be approved and polled
+	PollInterval    time.Duration `envconfig:"POLL_INTERVAL" default:"5s"`                              // Minimum time between polls of a device code
+	PurgeInterval   time.Duration `envconfig:"PURGE_INTERVAL" default:"1h"`                             // How often expired device codes are deleted
+}
+
+// remoteConfigPrefix is the prefix of the environment variables configuring the remote overrides
+const remoteConfigPrefix = "CONFIG"
+
+// RemoteConfig represents where configuration overrides are loaded from, so settings can change without a redeploy.
+// Overrides are keyed by the environment variable they replace and take precedence over the environment.
+type RemoteConfig struct {
+	ParameterPath   string        `envconfig:"PARAMETER_PATH"`                // Parameter Store path holding one parameter per variable, such as /code-refactor/prod
+	SecretID        string        `envconfig:"SECRET_ID"`                     // Secrets Manager secret holding a JSON object of variables
+	RefreshInterval time.Duration `envconfig:"REFRESH_INTERVAL" default:"1m"` // How often overrides are checked for changes, 0 loads them only at startup
+	Timeout         time.Duration `envconfig:"TIMEOUT" default:"30s"`         // Timeout of loading the overrides
+}
+
+// Enabled reports whether any remote overrides are configured
+func (c RemoteConfig) Enabled() bool {
+	return c.ParameterPath != "" || c.SecretID != ""
+}
+
+// WorkflowConfig represents the configuration of the engine running agent setups and task executions
+type WorkflowConfig struct {
+	RetryBackoff time.Duration `envconfig:"RETRY_BACKOFF" default:"2s"` // Wait before a failed step's first retry, doubled on every retry
+}
+
+// HTTPConfig represents the deadlines of API requests. A request past its deadline has its context cancelled.
+type HTTPConfig struct {
+	RequestTimeout time.Duration `envconfig:"REQUEST_TIMEOUT" default:"30s"` // Deadline of requests to routes without their own, 0 disables it
+	ExportTimeout  time.Duration `envconfig:"EXPORT_TIMEOUT" default:"10m"`  // Deadline of the requests to export routes, 0 disables it
+	// Routes streaming exports as newline-delimited JSON or CSV, by method and route pattern. Only they get the export deadline.
+	ExportRoutes []string `envconfig:"EXPORT_ROUTES" default:"GET /api/v1/projects/:project_id/tasks,GET /api/v1/projects/:project_id/redaction-audits,GET /api/v1/admin/exports/users,GET /api/v1/admin/exports/access-grants,GET /api/v1/admin/exports/audit-events"`
+	// Deadlines of the routes that provision infrastructure or run tasks synchronously, by method and route pattern
+	RouteTimeouts RouteTimeouts `envconfig:"ROUTE_TIMEOUTS" default:"POST /api/v1/agents=15m,PUT /api/v1/agents/:agent_id=15m,DELETE /api/v1/agents/:agent_id=15m,POST /api/v1/agents/:agent_id/rebuild=15m,POST /api/v1/agents/:agent_id/sync=15m,POST /api/v1/agent-setups/:setup_id/resume=15m,POST /api/v1/agent-setups/:setup_id/teardown=15m,POST /api/v1/projects/:project_id/tasks/execute=35m,POST /api/v1/codebases/:id/scan=10m,POST /api/v1/projects/:project_id/uploads=10m"`
+
+	MaxBodySize int64 `envconfig:"MAX_BODY_SIZE" default:"2097152"` // Largest body in bytes of requests to routes without their own, 0 disables the limit
+	// Largest bodies of the routes accepting uploads, by method and route pattern
+	RouteMaxBodySizes RouteBodySizes `envconfig:"ROUTE_MAX_BODY_SIZES" default:"POST /api/v1/projects/:project_id/uploads=104857600"`
+}
+
+// Timeout returns the deadline of requests to the route registered with method and pattern, 0 when they have none
+func (c HTTPConfig) Timeout(method, pattern string) time.Duration {
+	if timeout, ok := c.RouteTimeouts[method+" "+pattern]; ok {
+		return timeout
+	}
+	return c.RequestTimeout
+}
+
+// IsExportRoute reports whether the route registered with method and pattern streams exports
+func (c HTTPConfig) IsExportRoute(method, pattern string) bool {
+	for _, route := range c.ExportRoutes {
+		if strings.Join(strings.Fields(route), " ") == method+" "+pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// MaxBodySizeOf returns the largest body in bytes of requests to the route registered with method and pattern, 0 when
+// their bodies aren't limited
+func (c HTTPConfig) MaxBodySizeOf(method, pattern string) int64 {
+	if size, ok := c.RouteMaxBodySizes[method+" "+pattern]; ok {
+		return size
+	}
+	return c.MaxBodySize
+}
+
+// RouteTimeouts maps routes, as "METHOD /pattern", to their request deadlines. It's read from comma separated
+// route=duration pairs, such as "POST /api/v1/agents=15m", since route patterns contain colons.
+type RouteTimeouts map[string]time.Duration
+
+// Decode implements envconfig.Decoder
+func (r *RouteTimeouts) Decode(value string) error {
+	timeouts := RouteTimeouts{}
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		route, duration, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("invalid route timeout %q, expected METHOD /pattern=duration", pair)
+		}
+		timeout, err := time.ParseDuration(strings.TrimSpace(duration))
+		if err != nil {
+			return fmt.Errorf("invalid timeout of route %q: %w", route, err)
+		}
+		timeouts[strings.Join(strings.Fields(route), " ")] = timeout
+	}
+
+	*r = timeouts
+	return nil
+}
+
+// RouteBodySizes maps routes, as "METHOD /pattern", to the largest bodies in bytes of their requests. It's read from
+// comma separated route=bytes pairs, such as "POST /api/v1/projects/:project_id/uploads=104857600".
+type RouteBodySizes map[string]int64
+
+// Decode implements envconfig.Decoder
+func (r *RouteBodySizes) Decode(value string) error {
+	sizes := RouteBodySizes{}
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		route, bytes, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("invalid route body size %q, expected METHOD /pattern=bytes", pair)
+		}
+		size, err := strconv.ParseInt(strings.TrimSpace(bytes), 10, 64)
+		if err != nil || size < 0 {
+			return fmt.Errorf("invalid body size of route %q, expected a number of bytes", route)
+		}
+		sizes[strings.Join(strings.Fields(route), " ")] = size
+	}
+
+	*r = sizes
+	return nil
+}
+
+// TaskConfig represents the execution deadlines of tasks, the dispatcher running queued tasks and the detection of
+// stuck tasks. A task past its deadline is failed with a timeout reason. Executions heartbeat while they run, and an
+// in_progress task whose heartbeats stop is failed or requeued.
+type TaskConfig struct {
+	Timeout      time.Duration            `envconfig:"TIMEOUT" default:"30m"` // Deadline of task types without their own, 0 disables it
+	TypeTimeouts map[string]time.Duration `envconfig:"TYPE_TIMEOUTS"`         // Deadlines by task type, such as code_analysis:1h,dependency_audit:10m
+
+	HeartbeatInterval time.Duration `envconfig:"HEARTBEAT_INTERVAL" default:"30s"` // How often a running execution records a heartbeat, 0 disables heartbeats
+	StuckThreshold    time.Duration `envconfig:"STUCK_THRESHOLD" default:"5m"`     // How long an in_progress task may go without a heartbeat before it is stuck
+	StuckAction       StuckAction   `envconfig:"STUCK_ACTION" default:"fail"`      // What the janitor does with stuck tasks, fail or requeue
+	JanitorInterval   time.Duration `envconfig:"JANITOR_INTERVAL" default:"1m"`    // How often stuck tasks are looked for, 0 disables the janitor
+
+	Workers          int           `envconfig:"WORKERS" default:"4"`            // Most queued tasks the dispatcher runs at once
+	DispatchInterval time.Duration `envconfig:"DISPATCH_INTERVAL" default:"5s"` // How often an idle dispatcher looks for queued tasks, 0 disables the dispatcher
+
+	Executors      TaskExecutorEndpoints `envconfig:"EXECUTORS"`       // External executors by name, such as local-llm=http://localhost:8081/execute
+	ExecutorRoutes map[string]string     `envconfig:"EXECUTOR_ROUTES"` // Executors by task type, such as code_review:local-llm. Other types run on the built-in agent executor
+}
+
+// CoverageGapConfig represents the configuration of the coverage runs of coverage_gap tasks
+type CoverageGapConfig struct {
+	Commands       CoverageCommands `envconfig:"COMMANDS" default:"go=go test -coverprofile=$COVERAGE_PROFILE ./..."`
+	CommandTimeout time.Duration    `envconfig:"COMMAND_TIMEOUT" default:"10m"` // Deadline of a coverage run
+	ChurnWindow    time.Duration    `envconfig:"CHURN_WINDOW" default:"2160h"`  // How far back commits count towards churn
+	MaxGaps        int              `envconfig:"MAX_GAPS" default:"10"`         // Most functions a task asks tests for
+}
+
+// GenerationConfig represents the configuration of the text generated from diffs by the configured model
+type GenerationConfig struct {
+	MaxDiffLength int           `envconfig:"MAX_DIFF_LENGTH" default:"49152"` // Bytes of a diff shown to the model, the rest is left out
+	MaxRepairs    int           `envconfig:"MAX_REPAIRS" default:"1"`         // Times the model is asked to fix an answer not matching the schema
+	Timeout       time.Duration `envconfig:"TIMEOUT" default:"2m"`            // Deadline of a generation, repairs included
+}
+
+// CoverageCommands maps languages to the shell commands measuring the coverage of a checkout. A command writes its
+// profile to the path in $COVERAGE_PROFILE, as a Go cover profile for go and an LCOV tracefile for other languages.
+// It's read from semicolon separated language=command pairs, such as
+// "go=go test -coverprofile=$COVERAGE_PROFILE ./...;python=pytest --cov --cov-report=lcov:$COVERAGE_PROFILE", since
+// commands contain commas and colons.
+type CoverageCommands map[string]string
+
+// Decode implements envconfig.Decoder
+func (c *CoverageCommands) Decode(value string) error {
+	commands := CoverageCommands{}
+	for _, pair := range strings.Split(value, ";") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		language, command, ok := strings.Cut(pair, "=")
+		language, command = strings.TrimSpace(language), strings.TrimSpace(command)
+		if !ok || language == "" || command == "" {
+			return fmt.Errorf("invalid coverage command %q, expected language=command", pair)
+		}
+		commands[language] = command
+	}
+
+	*c = commands
+	return nil
+}
+
+// TaskExecutorEndpoints maps the names of external task executors to the URLs tasks are posted to. It's read from
+// comma separated name=url pairs, such as "local-llm=http://localhost:8081/execute", since URLs contain colons.
+type TaskExecutorEndpoints map[string]string
+
+// Decode implements envconfig.Decoder
+func (e *TaskExecutorEndpoints) Decode(value string) error {
+	endpoints := TaskExecutorEndpoints{}
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		name, endpoint, ok := strings.Cut(pair, "=")
+		name, endpoint = strings.TrimSpace(name), strings.TrimSpace(endpoint)
+		if !ok || name == "" || endpoint == "" {
+			return fmt.Errorf("invalid task executor %q, expected name=url", pair)
+		}
+		parsed, err := url.Parse(endpoint)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("invalid URL of task executor %q, expected an http or https URL", name)
+		}
+		endpoints[name] = endpoint
+	}
+
+	*e = endpoints
+	return nil
+}
+
+// StuckAction is what the janitor does with the tasks whose executions stopped sending heartbeats
+type StuckAction string
+
+const (
+	// StuckActionFail fails stuck tasks and rolls back their executions
+	StuckActionFail StuckAction = "fail"
+
+	// StuckActionRequeue queues stuck tasks again to run from their last completed step
+	StuckActionRequeue StuckAction = "requeue"
+)
+
+// Decode parses a stuck action, rejecting unknown ones
+func (a *StuckAction) Decode(value string) error {
+	switch action := StuckAction(value); action {
+	case StuckActionFail, StuckActionRequeue:
+		*a = action
+		return nil
+	default:
+		return fmt.Errorf("invalid stuck task action %q, expected fail or requeue", value)
+	}
+}
+
+// Deadline returns the execution deadline of tasks of taskType, 0 when they have none
+func (c TaskConfig) Deadline(taskType string) time.Duration {
+	if timeout, ok := c.TypeTimeouts[taskType]; ok {
+		return timeout
+	}
+	return c.Timeout
+}
+
 // PostgresConfig represents the configuration for PostgreSQL connection
 type PostgresConfig struct {
 	Host     string `envconfig:"HOST" default:"localhost"`
@@ -90,6 +844,18 @@ type PostgresConfig struct {
 	Username string `envconfig:"USERNAME" default:"postgres"`
 	Password string `envconfig:"PASSWORD"`
 	SSLMode  string `envconfig:"SSL_MODE" default:"disable"`
+
+	BulkInsertBatchSize int `envconfig:"BULK_INSERT_BATCH_SIZE" default:"500"` // Rows written per statement when storing scan and dependency findings
+
+	// Read replica serving reports, task listings and exports, with the credentials and database of the primary
+	ReplicaHost          string        `envconfig:"REPLICA_HOST"`                         // Endpoint of the replica, none when empty
+	ReplicaPort          int           `envconfig:"REPLICA_PORT"`                         // Port of the replica, Port when unset
+	ReplicaRetryInterval time.Duration `envconfig:"REPLICA_RETRY_INTERVAL" default:"30s"` // How long the primary serves them after the replica fails
+}
+
+// SQLiteConfig represents the configuration of the SQLite storage backend
+type SQLiteConfig struct {
+	Path string `envconfig:"PATH" default:"code-refactoring-tool.db"` // Database file, created and migrated at startup
 }
 
 // DatabaseSecret represents the structure of the secret stored in AWS Secrets Manager
@@ -108,6 +874,19 @@ type GitConfig struct {
 	Token       string `envconfig:"TOKEN" required:"true"`
 	Author      string `envconfig:"AUTHOR" default:"CodeRefactorBot"`
 	Email       string `envconfig:"EMAIL" default:"bot@example.com"`
+	// Personal access token for Azure DevOps codebases, which don't accept the GitHub token
+	AzureDevOpsToken string `envconfig:"AZURE_DEVOPS_TOKEN"`
+	// known_hosts file verifying SSH remotes whose codebase configuration has no known hosts
+	SSHKnownHostsFile string `envconfig:"SSH_KNOWN_HOSTS_FILE"`
+	// Whether the submodules of cloned repositories are cloned recursively
+	Submodules bool `envconfig:"SUBMODULES" default:"true"`
+	// Most LFS content downloaded into a clone in bytes, larger objects are left as pointer files. 0 leaves them all.
+	LFSMaxSize int64 `envconfig:"LFS_MAX_SIZE" default:"1073741824"`
+
+	// Per-codebase checkout options, set before a codebase is opened
+	CloneDepth    int      `ignored:"true"` // Commits of history cloned, the full history when 0
+	SparsePaths   []string `ignored:"true"` // Directories checked out, the whole tree when empty
+	CloneCacheDir string   `ignored:"true"` // Directory of the bare mirrors clones are fetched into, none when empty
 }
 
 // validateRepositoryURL ensures the RepoURL matches the expected GitHub URL pattern
@@ -124,21 +903,22 @@ func validateRepositoryURL(url string) error {
 	return nil
 }
 
+// logLevel is the level of the default logger, changed in place when the configuration is reloaded
+var logLevel = new(slog.LevelVar)
+
 // setupLogger configures slog with JSON output and the specified log level
 func setupLogger(level string) {
-	var logLevel slog.Level
-
 	switch strings.ToLower(level) {
 	case "debug":
-		logLevel = slog.LevelDebug
+		logLevel.Set(slog.LevelDebug)
 	case "info":
-		logLevel = slog.LevelInfo
+		logLevel.Set(slog.LevelInfo)
 	case "warn", "warning":
-		logLevel = slog.LevelWarn
+		logLevel.Set(slog.LevelWarn)
 	case "error":
-		logLevel = slog.LevelError
+		logLevel.Set(slog.LevelError)
 	default:
-		logLevel = slog.LevelInfo
+		logLevel.Set(slog.LevelInfo)
 	}
 
 	// Create JSON handler with specified log level
@@ -147,7 +927,7 @@ func setupLogger(level string) {
 	})
 
 	// Set the default logger
-	slog.SetDefault(slog.New(handler))
+	slog.SetDefault(slog.New(logging.NewContextHandler(handler)))
 }
 
 // LoadConfig loads and validates configuration from environment variables and AWS
@@ -159,10 +939,37 @@ func LoadConfig() (Config, error) {
 func LoadConfigWithDependencies(loader *Loader) (Config, error) {
 	var cfg Config
 
+	// Load AWS config
+	awsCfg, err := config.LoadDefaultConfig(context.Background())
+	if err != nil {
+		return cfg, fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+
+	// Use provided loader or create default one
+	if loader == nil {
+		loader = newDefaultLoader(awsCfg)
+	}
+
+	// Remote overrides replace environment variables, so they are applied before the environment is processed
+	var remote RemoteConfig
+	if err := envconfig.Process(remoteConfigPrefix, &remote); err != nil {
+		return cfg, fmt.Errorf("failed to load environment variables: %w", err)
+	}
+	if remote.Enabled() {
+		remoteCtx, remoteCancel := context.WithTimeout(context.Background(), remote.Timeout)
+		overrides, err := loader.LoadOverrides(remoteCtx, remote)
+		remoteCancel()
+		if err != nil {
+			return cfg, fmt.Errorf("failed to load remote configuration: %w", err)
+		}
+		applyOverrides(overrides)
+	}
+
 	// Load env vars
 	if err := envconfig.Process("", &cfg); err != nil {
 		return cfg, fmt.Errorf("failed to load environment variables: %w", err)
 	}
+	cfg.AWSConfig = awsCfg
 
 	// Setup structured logging as early as possible
 	setupLogger(cfg.LogLevel)
@@ -177,28 +984,14 @@ func LoadConfigWithDependencies(loader *Loader) (Config, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.TimeoutSeconds)*time.Second)
 	defer cancel()
 
-	// Load AWS config
-	awsCfg, err := config.LoadDefaultConfig(ctx)
-	if err != nil {
-		return cfg, fmt.Errorf("failed to load AWS configuration: %w", err)
-	}
-	cfg.AWSConfig = awsCfg
-
-	// Use provided loader or create default one
-	if loader == nil {
-		cfnClient := NewCloudFormationClient(awsCfg)
-		secretsClient := NewSecretsManagerClient(awsCfg)
-		loader = NewLoader(cfnClient, secretsClient)
-	}
-
 	// Only load AWS resources if not using local AI
 	if !cfg.AI.Local.Enabled {
 		// Load values from CloudFormation stack if not already set
 		if cfg.AI.Bedrock.KnowledgeBaseServiceRoleARN == "" || cfg.AI.Bedrock.AgentServiceRoleARN == "" ||
 			cfg.AI.Bedrock.S3BucketName == "" || cfg.AI.Bedrock.RDSPostgres.InstanceARN == "" || cfg.AI.Bedrock.RDSPostgres.SchemaEnsureLambdaARN == "" ||
 			cfg.AI.Bedrock.RDSPostgres.CredentialsSecretARN == "" {
-			if err := loader.LoadStackOutputs(ctx, "CodeRefactorInfra", &cfg); err != nil {
-				return cfg, fmt.Errorf("failed to load stack outputs: %w", err)
+			if err := loader.LoadStackOutputs(ctx, cfg.StackName, &cfg); err != nil {
+				return cfg, fmt.Errorf("failed to load outputs of stack %s: %w", cfg.StackName, err)
 			}
 		}
 
@@ -3,8 +3,10 @@ package config_test
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+	"github.com/kelseyhightower/envconfig"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -123,3 +125,264 @@ func TestLoadConfig_InvalidGitHubURL(t *testing.T) {
 	assert.Error(t, err, "LoadConfig should return an error for an invalid GitHub repository URL")
 	assert.Contains(t, err.Error(), "invalid GitHub repository URL format", "Error message should indicate invalid format")
 }
+
+func TestAPIConfig_ParsesVersionPolicy(t *testing.T) {
+	// Arrange: Deprecate v1 and leave v2 active
+	t.Setenv("API_V1_DEPRECATED_AT", "2026-01-01T00:00:00Z")
+	t.Setenv("API_V1_SUNSET_AT", "2026-07-01T00:00:00Z")
+	t.Setenv("API_V1_DEPRECATION_LINK", "https://example.com/migrate")
+
+	// Act: Process only the API section
+	var cfg config.APIConfig
+	err := envconfig.Process("API", &cfg)
+
+	// Assert: v1 carries the policy and v2 has none
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC), cfg.V1.DeprecatedAt.UTC())
+	assert.Equal(t, time.Date(2026, time.July, 1, 0, 0, 0, 0, time.UTC), cfg.V1.SunsetAt.UTC())
+	assert.Equal(t, "https://example.com/migrate", cfg.V1.Link)
+	assert.True(t, cfg.V2.DeprecatedAt.IsZero())
+	assert.True(t, cfg.V2.SunsetAt.IsZero())
+}
+
+func TestHTTPConfig_ParsesRouteTimeouts(t *testing.T) {
+	// Arrange: Override the deadline of a route whose pattern has a parameter
+	t.Setenv("HTTP_REQUEST_TIMEOUT", "5s")
+	t.Setenv("HTTP_ROUTE_TIMEOUTS", "POST /api/v1/projects/:project_id/tasks/execute=1h, GET  /api/v1/reports/tasks=0s")
+
+	// Act: Process only the HTTP and task sections
+	var httpCfg config.HTTPConfig
+	err := envconfig.Process("HTTP", &httpCfg)
+	require.NoError(t, err)
+
+	t.Setenv("TASK_TYPE_TIMEOUTS", "dependency_audit:10m")
+	var taskCfg config.TaskConfig
+	err = envconfig.Process("TASK", &taskCfg)
+	require.NoError(t, err)
+
+	// Assert: Routes and task types without their own deadline fall back to the default
+	assert.Equal(t, time.Hour, httpCfg.Timeout("POST", "/api/v1/projects/:project_id/tasks/execute"))
+	assert.Equal(t, time.Duration(0), httpCfg.Timeout("GET", "/api/v1/reports/tasks"))
+	assert.Equal(t, 5*time.Second, httpCfg.Timeout("GET", "/api/v1/projects"))
+	assert.Equal(t, 10*time.Minute, taskCfg.Deadline("dependency_audit"))
+	assert.Equal(t, 30*time.Minute, taskCfg.Deadline("code_analysis"))
+}
+
+func TestHTTPConfig_RejectsInvalidRouteTimeout(t *testing.T) {
+	t.Setenv("HTTP_ROUTE_TIMEOUTS", "POST /api/v1/agents")
+
+	var cfg config.HTTPConfig
+	err := envconfig.Process("HTTP", &cfg)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected METHOD /pattern=duration")
+}
+
+func TestHTTPConfig_ExportRoutes(t *testing.T) {
+	var cfg config.HTTPConfig
+	err := envconfig.Process("HTTP", &cfg)
+	require.NoError(t, err)
+
+	// Only the routes streaming exports are export routes
+	assert.True(t, cfg.IsExportRoute("GET", "/api/v1/projects/:project_id/tasks"))
+	assert.True(t, cfg.IsExportRoute("GET", "/api/v1/projects/:project_id/redaction-audits"))
+	assert.True(t, cfg.IsExportRoute("GET", "/api/v1/admin/exports/users"))
+	assert.True(t, cfg.IsExportRoute("GET", "/api/v1/admin/exports/access-grants"))
+	assert.True(t, cfg.IsExportRoute("GET", "/api/v1/admin/exports/audit-events"))
+	assert.False(t, cfg.IsExportRoute("POST", "/api/v1/projects/:project_id/tasks/execute"))
+	assert.False(t, cfg.IsExportRoute("GET", "/api/v1/projects"))
+}
+
+func TestHTTPConfig_ParsesRouteBodySizes(t *testing.T) {
+	// Arrange: Raise the limit of the upload route and lift it for another
+	t.Setenv("HTTP_MAX_BODY_SIZE", "1024")
+	t.Setenv("HTTP_ROUTE_MAX_BODY_SIZES", "POST /api/v1/projects/:project_id/uploads=1048576, PUT /api/v1/agents/:id=0")
+
+	// Act
+	var cfg config.HTTPConfig
+	err := envconfig.Process("HTTP", &cfg)
+	require.NoError(t, err)
+
+	// Assert: Routes without their own limit fall back to the default
+	assert.Equal(t, int64(1048576), cfg.MaxBodySizeOf("POST", "/api/v1/projects/:project_id/uploads"))
+	assert.Equal(t, int64(0), cfg.MaxBodySizeOf("PUT", "/api/v1/agents/:id"))
+	assert.Equal(t, int64(1024), cfg.MaxBodySizeOf("POST", "/api/v1/agents"))
+}
+
+func TestHTTPConfig_RejectsInvalidRouteBodySize(t *testing.T) {
+	t.Setenv("HTTP_ROUTE_MAX_BODY_SIZES", "POST /api/v1/projects/:project_id/uploads=100MB")
+
+	var cfg config.HTTPConfig
+	err := envconfig.Process("HTTP", &cfg)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected a number of bytes")
+}
+
+func TestAuthConfig_ParsesPaths(t *testing.T) {
+	t.Setenv("AUTH_PUBLIC_PATHS", "/status, /api/v1/templates/,/")
+	t.Setenv("AUTH_PROTECTED_PATHS", "/swagger")
+
+	var cfg config.AuthConfig
+	err := envconfig.Process("AUTH", &cfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, config.PathPrefixes{"/status", "/api/v1/templates", ""}, cfg.PublicPaths)
+	assert.Equal(t, config.PathPrefixes{"/swagger"}, cfg.ProtectedPaths)
+}
+
+func TestAuthConfig_RejectsRelativePath(t *testing.T) {
+	t.Setenv("AUTH_PUBLIC_PATHS", "status")
+
+	var cfg config.AuthConfig
+	err := envconfig.Process("AUTH", &cfg)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected a path starting with /")
+}
+
+func TestBedrockAIConfig_ParsesRegions(t *testing.T) {
+	t.Setenv("BEDROCK_REGION", "us-east-1")
+	t.Setenv("BEDROCK_S3_BUCKET_NAME", "content-us-east-1")
+	t.Setenv("BEDROCK_ALLOWED_REGIONS", "eu-west-1,ap-southeast-2")
+	t.Setenv("BEDROCK_S3_BUCKET_NAMES", "eu-west-1:content-eu-west-1")
+
+	var cfg config.BedrockAIConfig
+	err := envconfig.Process("BEDROCK", &cfg)
+
+	require.NoError(t, err)
+	assert.True(t, cfg.AllowsRegion("us-east-1"))
+	assert.True(t, cfg.AllowsRegion("ap-southeast-2"))
+	assert.False(t, cfg.AllowsRegion("us-west-2"))
+	assert.Equal(t, "content-eu-west-1", cfg.BucketName("eu-west-1"))
+	assert.Equal(t, "content-us-east-1", cfg.BucketName("ap-southeast-2"))
+}
+
+func TestTaskConfig_ParsesExecutors(t *testing.T) {
+	t.Setenv("TASK_EXECUTORS", "local-llm=http://localhost:8081/execute, linter=https://lint.internal/run")
+	t.Setenv("TASK_EXECUTOR_ROUTES", "code_review:linter,documentation:local-llm")
+
+	var cfg config.TaskConfig
+	err := envconfig.Process("TASK", &cfg)
+
+	require.NoError(t, err)
+	assert.Equal(t, config.TaskExecutorEndpoints{
+		"local-llm": "http://localhost:8081/execute",
+		"linter":    "https://lint.internal/run",
+	}, cfg.Executors)
+	assert.Equal(t, map[string]string{"code_review": "linter", "documentation": "local-llm"}, cfg.ExecutorRoutes)
+}
+
+func TestTaskConfig_RejectsInvalidExecutor(t *testing.T) {
+	t.Setenv("TASK_EXECUTORS", "local-llm=localhost:8081")
+
+	var cfg config.TaskConfig
+	err := envconfig.Process("TASK", &cfg)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected an http or https URL")
+}
+
+func TestCoverageGapConfig_ParsesCommands(t *testing.T) {
+	t.Setenv("COVERAGE_GAP_COMMANDS", "go=go test -coverprofile=$COVERAGE_PROFILE ./...; python=pytest --cov --cov-report=lcov:$COVERAGE_PROFILE")
+
+	var cfg config.CoverageGapConfig
+	err := envconfig.Process("COVERAGE_GAP", &cfg)
+
+	require.NoError(t, err)
+	assert.Equal(t, config.CoverageCommands{
+		"go":     "go test -coverprofile=$COVERAGE_PROFILE ./...",
+		"python": "pytest --cov --cov-report=lcov:$COVERAGE_PROFILE",
+	}, cfg.Commands)
+}
+
+func TestCoverageGapConfig_DefaultsToGo(t *testing.T) {
+	var cfg config.CoverageGapConfig
+	err := envconfig.Process("COVERAGE_GAP", &cfg)
+
+	require.NoError(t, err)
+	assert.Equal(t, config.CoverageCommands{"go": "go test -coverprofile=$COVERAGE_PROFILE ./..."}, cfg.Commands)
+}
+
+func TestLocalAIConfig_ParsesDataStore(t *testing.T) {
+	t.Setenv("AI_LOCAL_DATA_STORE_TYPE", "gcs")
+	t.Setenv("AI_LOCAL_DATA_STORE_BUCKET", "acme-knowledge-bases")
+
+	var cfg config.LocalAIConfig
+	err := envconfig.Process("AI_LOCAL", &cfg)
+
+	require.NoError(t, err)
+	assert.Equal(t, config.DataStoreGCS, cfg.DataStore.Type)
+	assert.Equal(t, "acme-knowledge-bases", cfg.DataStore.Bucket)
+	assert.Equal(t, "https://storage.googleapis.com", cfg.DataStore.GCSEndpoint)
+}
+
+func TestLocalAIConfig_RejectsUnknownDataStore(t *testing.T) {
+	t.Setenv("AI_LOCAL_DATA_STORE_TYPE", "azure")
+
+	var cfg config.LocalAIConfig
+	err := envconfig.Process("AI_LOCAL", &cfg)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected filesystem, s3 or gcs")
+}
+
+func TestVectorStoreConfig_Backend(t *testing.T) {
+	t.Setenv("VECTOR_STORE_DEFAULT", "qdrant")
+
+	var cfg config.VectorStoreConfig
+	err := envconfig.Process("VECTOR_STORE", &cfg)
+
+	require.NoError(t, err)
+	assert.Equal(t, config.VectorStoreQdrant, cfg.Backend(""))
+	assert.Equal(t, config.VectorStoreOpenSearch, cfg.Backend("opensearch"))
+	assert.Equal(t, "http://localhost:6333", cfg.QdrantURL)
+}
+
+func TestVectorStoreConfig_RejectsUnknownDefault(t *testing.T) {
+	t.Setenv("VECTOR_STORE_DEFAULT", "pinecone")
+
+	var cfg config.VectorStoreConfig
+	err := envconfig.Process("VECTOR_STORE", &cfg)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected pgvector, opensearch or qdrant")
+}
+
+func TestModelLimitConfig_ParsesCeilings(t *testing.T) {
+	t.Setenv("AI_LIMITS_CEILINGS", "bedrock/anthropic.claude-3-haiku-20240307-v1:0=4, ollama/llama3=2")
+
+	var cfg config.ModelLimitConfig
+	err := envconfig.Process("AI_LIMITS", &cfg)
+
+	require.NoError(t, err)
+	assert.Equal(t, config.ModelCeilings{
+		"bedrock/anthropic.claude-3-haiku-20240307-v1:0": 4,
+		"ollama/llama3": 2,
+	}, cfg.Ceilings)
+	assert.Equal(t, 8, cfg.DefaultCeiling)
+}
+
+func TestModelLimitConfig_RejectsInvalidCeiling(t *testing.T) {
+	t.Setenv("AI_LIMITS_CEILINGS", "llama3=2")
+
+	var cfg config.ModelLimitConfig
+	err := envconfig.Process("AI_LIMITS", &cfg)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected provider/model=ceiling")
+}
+
+func TestStorageBackend_Decode(t *testing.T) {
+	var backend config.StorageBackend
+
+	require.NoError(t, backend.Decode("memory"))
+	assert.Equal(t, config.StorageBackendMemory, backend)
+
+	require.NoError(t, backend.Decode("sqlite"))
+	assert.Equal(t, config.StorageBackendSQLite, backend)
+
+	err := backend.Decode("mysql")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected postgres, memory or sqlite")
+}
@@ -23,7 +23,7 @@ func TestLoadConfigWithMocks_Success(t *testing.T) {
 
 	mockCfnClient := mocks.NewMockCloudFormationClient(ctrl)
 	mockSecretsClient := mocks.NewMockSecretsManagerClient(ctrl)
-	loader := config.NewLoader(mockCfnClient, mockSecretsClient)
+	loader := config.NewLoader(mockCfnClient, mockSecretsClient, mocks.NewMockParameterStoreClient(ctrl))
 
 	// Set environment variables
 	expectedRepoURL := "https://github.com/example/repo.git"
@@ -125,7 +125,7 @@ func TestLoadConfigWithMocks_NoSecretARN(t *testing.T) {
 
 	mockCfnClient := mocks.NewMockCloudFormationClient(ctrl)
 	mockSecretsClient := mocks.NewMockSecretsManagerClient(ctrl)
-	loader := config.NewLoader(mockCfnClient, mockSecretsClient)
+	loader := config.NewLoader(mockCfnClient, mockSecretsClient, mocks.NewMockParameterStoreClient(ctrl))
 
 	// Set environment variables
 	err := os.Setenv("GIT_CODEBASE_URL", "https://github.com/example/repo.git")
@@ -191,6 +191,63 @@ func TestLoadConfigWithMocks_NoSecretARN(t *testing.T) {
 	assert.Equal(t, "testpassword123", cfg.Postgres.Password)
 }
 
+func TestLoadConfigWithMocks_ReadsOutputsOfEnvironmentStack(t *testing.T) {
+	// Setup
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCfnClient := mocks.NewMockCloudFormationClient(ctrl)
+	mockSecretsClient := mocks.NewMockSecretsManagerClient(ctrl)
+	loader := config.NewLoader(mockCfnClient, mockSecretsClient, mocks.NewMockParameterStoreClient(ctrl))
+
+	// Set environment variables
+	err := os.Setenv("STACK_NAME", "CodeRefactorInfra-staging")
+	require.NoError(t, err)
+	err = os.Setenv("GIT_TOKEN", "ghp_testtoken123")
+	require.NoError(t, err)
+	err = os.Setenv("COGNITO_USER_POOL_ID", "us-east-1_123456789")
+	require.NoError(t, err)
+	err = os.Setenv("COGNITO_CLIENT_ID", "1234567890abcdef")
+	require.NoError(t, err)
+	err = os.Setenv("POSTGRES_PASSWORD", "testpassword123")
+	require.NoError(t, err)
+
+	defer func() {
+		os.Unsetenv("STACK_NAME")           //nolint:errcheck
+		os.Unsetenv("GIT_TOKEN")            //nolint:errcheck
+		os.Unsetenv("COGNITO_USER_POOL_ID") //nolint:errcheck
+		os.Unsetenv("COGNITO_CLIENT_ID")    //nolint:errcheck
+		os.Unsetenv("POSTGRES_PASSWORD")    //nolint:errcheck
+	}()
+
+	mockCfnClient.EXPECT().
+		DescribeStacks(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ any, input *cfn.DescribeStacksInput, _ ...func(*cfn.Options)) (*cfn.DescribeStacksOutput, error) {
+			assert.Equal(t, "CodeRefactorInfra-staging", aws.ToString(input.StackName))
+			return &cfn.DescribeStacksOutput{
+				Stacks: []cfnTypes.Stack{
+					{
+						Outputs: []cfnTypes.Output{
+							{
+								OutputKey:   aws.String("BucketName"),
+								OutputValue: aws.String("staging-bucket"),
+							},
+						},
+					},
+				},
+			}, nil
+		}).
+		Times(1)
+
+	// Act
+	cfg, err := config.LoadConfigWithDependencies(loader)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "CodeRefactorInfra-staging", cfg.StackName)
+	assert.Equal(t, "staging-bucket", cfg.AI.Bedrock.S3BucketName)
+}
+
 func TestLoadConfigWithMocks_LocalAIEnabled_SkipsAWSCalls(t *testing.T) {
 	// Setup
 	ctrl := gomock.NewController(t)
@@ -198,7 +255,7 @@ func TestLoadConfigWithMocks_LocalAIEnabled_SkipsAWSCalls(t *testing.T) {
 
 	mockCfnClient := mocks.NewMockCloudFormationClient(ctrl)
 	mockSecretsClient := mocks.NewMockSecretsManagerClient(ctrl)
-	loader := config.NewLoader(mockCfnClient, mockSecretsClient)
+	loader := config.NewLoader(mockCfnClient, mockSecretsClient, mocks.NewMockParameterStoreClient(ctrl))
 
 	// Set environment variables with LocalAI enabled
 	expectedRepoURL := "https://github.com/example/repo.git"
@@ -62,6 +62,132 @@ const (
 	// DefaultUsersTableName is the default name for the users table
 	DefaultUsersTableName = "users"
 
+	// DefaultProjectTemplatesTableName is the default name for the custom project templates table
+	DefaultProjectTemplatesTableName = "project_templates"
+
+	// DefaultTagKeysTableName is the default name for the tag key registry table
+	DefaultTagKeysTableName = "tag_keys"
+
+	// DefaultProjectAutomationsTableName is the default name for the project schedules and webhooks table
+	DefaultProjectAutomationsTableName = "project_automations"
+
+	// DefaultTaskMetricsTableName is the default name for the daily task metrics rollup table
+	DefaultTaskMetricsTableName = "task_metrics_daily"
+
+	// DefaultNotificationChannelsTableName is the default name for the email and Slack notification channels table
+	DefaultNotificationChannelsTableName = "notification_channels"
+
+	// DefaultNotificationsTableName is the default name for the in-app notification inbox table
+	DefaultNotificationsTableName = "notifications"
+
+	// DefaultNotificationOutboxTableName is the default name for the table of notifications awaiting delivery
+	DefaultNotificationOutboxTableName = "notification_outbox"
+
+	// DefaultTaskCommentsTableName is the default name for the task comments table
+	DefaultTaskCommentsTableName = "task_comments"
+
+	// DefaultTaskFeedbackTableName is the default name for the table of user feedback on task results
+	DefaultTaskFeedbackTableName = "task_feedback"
+
+	// DefaultGitHubAppConfigsTableName is the default name for the table of the organizations' GitHub App credentials
+	DefaultGitHubAppConfigsTableName = "github_app_configs"
+
+	// DefaultGitHubChecksTableName is the default name for the table of the check runs of pull request tasks
+	DefaultGitHubChecksTableName = "github_checks"
+
+	// DefaultJiraSitesTableName is the default name for the table of the organizations' Jira sites
+	DefaultJiraSitesTableName = "jira_sites"
+
+	// DefaultJiraIssueLinksTableName is the default name for the table linking tasks to Jira issues
+	DefaultJiraIssueLinksTableName = "jira_issue_links"
+
+	// DefaultClientTokensTableName is the default name for the table of the client tokens of create requests
+	DefaultClientTokensTableName = "client_tokens"
+
+	// DefaultServiceAccountsTableName is the default name for the service accounts table
+	DefaultServiceAccountsTableName = "service_accounts"
+
+	// DefaultServiceAccountTokensTableName is the default name for the table of the tokens of service accounts
+	DefaultServiceAccountTokensTableName = "service_account_tokens"
+
+	// DefaultStoragePurgesTableName is the default name for the table of the purges of deleted entities' object storage
+	DefaultStoragePurgesTableName = "storage_purges"
+
+	// DefaultCampaignsTableName is the default name for the multi-codebase campaigns table
+	DefaultCampaignsTableName = "campaigns"
+
+	// DefaultDependencyFindingsTableName is the default name for the vulnerable dependency findings table
+	DefaultDependencyFindingsTableName = "dependency_findings"
+
+	// DefaultFindingsTableName is the default name for the analysis findings table
+	DefaultFindingsTableName = "findings"
+
+	// DefaultFindingBaselinesTableName is the default name for the table of the codebases' finding baselines
+	DefaultFindingBaselinesTableName = "finding_baselines"
+
+	// DefaultCodeOwnersTableName is the default name for the table of the codebases' CODEOWNERS rules
+	DefaultCodeOwnersTableName = "code_owners"
+
+	// DefaultQualityGatePoliciesTableName is the default name for the per-project severity rules and quality gates table
+	DefaultQualityGatePoliciesTableName = "quality_gate_policies"
+
+	// DefaultCodeMetricsTableName is the default name for the per-commit code metrics snapshots table
+	DefaultCodeMetricsTableName = "code_metrics"
+
+	// DefaultFileHistoriesTableName is the default name for the table of the git history summaries of codebase files
+	DefaultFileHistoriesTableName = "file_histories"
+
+	// DefaultScanFindingsTableName is the default name for the ingestion scan findings table
+	DefaultScanFindingsTableName = "scan_findings"
+
+	// DefaultRedactionPoliciesTableName is the default name for the per-project RAG content redaction policies table
+	DefaultRedactionPoliciesTableName = "redaction_policies"
+
+	// DefaultRedactionAuditsTableName is the default name for the per-sync redaction counts table
+	DefaultRedactionAuditsTableName = "redaction_audits"
+
+	// DefaultAgentResyncSettingsTableName is the default name for the per-project agent resync settings table
+	DefaultAgentResyncSettingsTableName = "agent_resync_settings"
+
+	// DefaultWorkspacesTableName is the default name for the table of codebase clones on task runner disks
+	DefaultWorkspacesTableName = "workspaces"
+
+	// DefaultCodebaseHeadsTableName is the default name for the polled codebase default branch heads table
+	DefaultCodebaseHeadsTableName = "codebase_heads"
+
+	// DefaultWorkflowRunsTableName is the default name for the workflow run progress table
+	DefaultWorkflowRunsTableName = "workflow_runs"
+
+	// DefaultMFARecoveryCodesTableName is the default name for the table of hashed MFA recovery codes
+	DefaultMFARecoveryCodesTableName = "mfa_recovery_codes"
+
+	// DefaultDeviceAuthorizationsTableName is the default name for the table of device authorization flow sign ins
+	DefaultDeviceAuthorizationsTableName = "device_authorizations"
+
+	// DefaultRolesTableName is the default name for the roles table
+	DefaultRolesTableName = "roles"
+
+	// DefaultPermissionsTableName is the default name for the table of the permissions roles can grant
+	DefaultPermissionsTableName = "permissions"
+
+	// DefaultRolePermissionsTableName is the default name for the table of the permissions each role grants
+	DefaultRolePermissionsTableName = "role_permissions"
+
+	// DefaultProjectMembersTableName is the default name for the table of project members and their roles
+	DefaultProjectMembersTableName = "user_project_access"
+
+	// DefaultAuditEventsTableName is the default name for the table of audit events
+	DefaultAuditEventsTableName = "audit_events"
+
+	// DefaultLLMInteractionsTableName is the default name for the table of the LLM calls made while executing tasks
+	DefaultLLMInteractionsTableName = "llm_interactions"
+
+	// DefaultEvalRunsTableName is the default name for the table of evaluation runs against golden repositories
+	DefaultEvalRunsTableName = "eval_runs"
+
+	// DefaultExperimentsTableName is the default name for the table of experiments routing live tasks to a variant
+	DefaultExperimentsTableName = "experiments"
+
 	// DefaultOllamaURL is the default URL for local Ollama server
 	DefaultOllamaURL = "http://localhost:11434"
 	// DefaultOllamaModel is the default Ollama model for local AI processing
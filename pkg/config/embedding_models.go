@@ -6,6 +6,7 @@
 // 2. An EmbeddingModelConfig struct with:
 //   - ModelID: the same model ID
 //   - SupportsConfiguration: true if the model supports custom dimensions/config
+//   - AllowedDimensions: the dimensions a knowledge base can choose from, nil for fixed dimensions
 //   - Configuration: nil if SupportsConfiguration is false, otherwise a valid config
 //
 // Example:
@@ -13,6 +14,7 @@
 //	"new.embedding.model": {
 //	    ModelID:               "new.embedding.model",
 //	    SupportsConfiguration: true,
+//	    AllowedDimensions:     []int32{512, 1024},
 //	    Configuration: &types.EmbeddingModelConfiguration{
 //	        BedrockEmbeddingModelConfiguration: &types.BedrockEmbeddingModelConfiguration{
 //	            Dimensions:        aws.Int32(1024),
@@ -23,6 +25,10 @@
 package config
 
 import (
+	"fmt"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/bedrockagent/types"
 )
 
@@ -37,6 +43,10 @@ type EmbeddingModelConfig struct {
 	// DefaultDimensions is the default number of dimensions for this model
 	DefaultDimensions int32
 
+	// AllowedDimensions are the numbers of dimensions a knowledge base can choose from, nil when the model only
+	// produces its default dimensions
+	AllowedDimensions []int32
+
 	// Configuration contains the optional embedding model configuration
 	Configuration *types.EmbeddingModelConfiguration
 }
@@ -57,12 +67,64 @@ func (e *EmbeddingModelConfig) GetDimensions() int32 {
 	return e.DefaultDimensions
 }
 
+// ConfigurationFor returns the embedding configuration producing vectors of the given dimensions, nil when the
+// model doesn't support configuration
+func (e *EmbeddingModelConfig) ConfigurationFor(dimensions int32) *types.EmbeddingModelConfiguration {
+	if !e.SupportsConfiguration {
+		return nil
+	}
+	dataType := types.EmbeddingDataTypeFloat32
+	if e.Configuration != nil && e.Configuration.BedrockEmbeddingModelConfiguration != nil {
+		dataType = e.Configuration.BedrockEmbeddingModelConfiguration.EmbeddingDataType
+	}
+	return &types.EmbeddingModelConfiguration{
+		BedrockEmbeddingModelConfiguration: &types.BedrockEmbeddingModelConfiguration{
+			Dimensions:        aws.Int32(dimensions),
+			EmbeddingDataType: dataType,
+		},
+	}
+}
+
+// Embedding is the embedding model of a knowledge base and the dimensions of the vectors it stores
+type Embedding struct {
+	// Model is the Bedrock model identifier, the default embedding model when empty
+	Model string
+
+	// Dimensions is the number of dimensions of the vectors, the model's default when zero
+	Dimensions int32
+}
+
+// IsZero reports whether the embedding is left to the defaults
+func (e Embedding) IsZero() bool {
+	return e.Model == "" && e.Dimensions == 0
+}
+
+// ResolveEmbedding fills in the defaults of an embedding and checks the model is supported and produces vectors of
+// its dimensions
+func ResolveEmbedding(embedding Embedding) (Embedding, error) {
+	if embedding.Model == "" {
+		embedding.Model = AWSBedrockRAGEmbeddingModel
+	}
+	model, exists := GetEmbeddingModelConfig(embedding.Model)
+	if !exists {
+		return Embedding{}, fmt.Errorf("unsupported embedding model: %s", embedding.Model)
+	}
+	if embedding.Dimensions == 0 {
+		embedding.Dimensions = model.GetDimensions()
+	}
+	if embedding.Dimensions != model.DefaultDimensions && !slices.Contains(model.AllowedDimensions, embedding.Dimensions) {
+		return Embedding{}, fmt.Errorf("embedding model %s doesn't produce %d dimensions", embedding.Model, embedding.Dimensions)
+	}
+	return embedding, nil
+}
+
 // EmbeddingModels contains all supported embedding models and their configurations
 var EmbeddingModels = map[string]*EmbeddingModelConfig{ // Amazon Titan Embed Text v1 - doesn't support configurable dimensions, 1536 default
 	"amazon.titan-embed-text-v1": {
 		ModelID:               "amazon.titan-embed-text-v1",
 		SupportsConfiguration: false,
 		DefaultDimensions:     1536,
+		AllowedDimensions:     nil,
 		Configuration:         nil,
 	},
 
@@ -71,6 +133,7 @@ var EmbeddingModels = map[string]*EmbeddingModelConfig{ // Amazon Titan Embed Te
 		ModelID:               "amazon.titan-embed-text-v2:0",
 		SupportsConfiguration: true,
 		DefaultDimensions:     1024,
+		AllowedDimensions:     []int32{256, 512, 1024},
 		Configuration: &types.EmbeddingModelConfiguration{
 			BedrockEmbeddingModelConfiguration: &types.BedrockEmbeddingModelConfiguration{
 				Dimensions:        &[]int32{256, 512, 1024}[2], // 1024 dimensions
@@ -84,6 +147,7 @@ var EmbeddingModels = map[string]*EmbeddingModelConfig{ // Amazon Titan Embed Te
 		ModelID:               "cohere.embed-english-v3",
 		SupportsConfiguration: true,
 		DefaultDimensions:     1024,
+		AllowedDimensions:     []int32{256, 512, 1024},
 		Configuration: &types.EmbeddingModelConfiguration{
 			BedrockEmbeddingModelConfiguration: &types.BedrockEmbeddingModelConfiguration{
 				Dimensions:        &[]int32{256, 512, 1024}[2], // 1024 dimensions
@@ -97,6 +161,7 @@ var EmbeddingModels = map[string]*EmbeddingModelConfig{ // Amazon Titan Embed Te
 		ModelID:               "cohere.embed-multilingual-v3",
 		SupportsConfiguration: true,
 		DefaultDimensions:     1024,
+		AllowedDimensions:     []int32{256, 512, 1024},
 		Configuration: &types.EmbeddingModelConfiguration{
 			BedrockEmbeddingModelConfiguration: &types.BedrockEmbeddingModelConfiguration{
 				Dimensions:        &[]int32{256, 512, 1024}[2], // 1024 dimensions
@@ -266,3 +266,78 @@ func TestEmbeddingModelConfig_GetDimensions(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveEmbedding(t *testing.T) {
+	tests := []struct {
+		name      string
+		embedding Embedding
+		expected  Embedding
+		expectErr bool
+	}{
+		{
+			name:      "defaults to the current model and its dimensions",
+			embedding: Embedding{},
+			expected:  Embedding{Model: AWSBedrockRAGEmbeddingModel, Dimensions: 1536},
+		},
+		{
+			name:      "configurable model defaults to its configured dimensions",
+			embedding: Embedding{Model: "amazon.titan-embed-text-v2:0"},
+			expected:  Embedding{Model: "amazon.titan-embed-text-v2:0", Dimensions: 1024},
+		},
+		{
+			name:      "configurable model with allowed dimensions",
+			embedding: Embedding{Model: "amazon.titan-embed-text-v2:0", Dimensions: 256},
+			expected:  Embedding{Model: "amazon.titan-embed-text-v2:0", Dimensions: 256},
+		},
+		{
+			name:      "configurable model with unsupported dimensions",
+			embedding: Embedding{Model: "amazon.titan-embed-text-v2:0", Dimensions: 1536},
+			expectErr: true,
+		},
+		{
+			name:      "fixed model with other dimensions",
+			embedding: Embedding{Model: "amazon.titan-embed-text-v1", Dimensions: 1024},
+			expectErr: true,
+		},
+		{
+			name:      "unsupported model",
+			embedding: Embedding{Model: "unknown.model"},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ResolveEmbedding(tt.embedding)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("ResolveEmbedding() expected an error, got %v", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveEmbedding() unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("ResolveEmbedding() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEmbeddingModelConfig_ConfigurationFor(t *testing.T) {
+	if config := EmbeddingModels["amazon.titan-embed-text-v1"].ConfigurationFor(1536); config != nil {
+		t.Errorf("ConfigurationFor() = %v, want nil for a model without configuration support", config)
+	}
+
+	config := EmbeddingModels["amazon.titan-embed-text-v2:0"].ConfigurationFor(512)
+	if config == nil || config.BedrockEmbeddingModelConfiguration == nil {
+		t.Fatal("ConfigurationFor() should return a Bedrock configuration")
+	}
+	if *config.BedrockEmbeddingModelConfiguration.Dimensions != 512 {
+		t.Errorf("ConfigurationFor() Dimensions = %v, want 512", *config.BedrockEmbeddingModelConfiguration.Dimensions)
+	}
+	if config.BedrockEmbeddingModelConfiguration.EmbeddingDataType != types.EmbeddingDataTypeFloat32 {
+		t.Errorf("ConfigurationFor() EmbeddingDataType = %v, want FLOAT32", config.BedrockEmbeddingModelConfiguration.EmbeddingDataType)
+	}
+}
@@ -4,25 +4,81 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"path"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	cfn "github.com/aws/aws-sdk-go-v2/service/cloudformation"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
 )
 
 // Loader handles loading configuration with dependency injection
 type Loader struct {
-	cfnClient     CloudFormationClient
-	secretsClient SecretsManagerClient
+	cfnClient       CloudFormationClient
+	secretsClient   SecretsManagerClient
+	parameterClient ParameterStoreClient
 }
 
 // NewLoader creates a new config loader with the provided clients
-func NewLoader(cfnClient CloudFormationClient, secretsClient SecretsManagerClient) *Loader {
+func NewLoader(cfnClient CloudFormationClient, secretsClient SecretsManagerClient, parameterClient ParameterStoreClient) *Loader {
 	return &Loader{
-		cfnClient:     cfnClient,
-		secretsClient: secretsClient,
+		cfnClient:       cfnClient,
+		secretsClient:   secretsClient,
+		parameterClient: parameterClient,
 	}
 }
 
+// newDefaultLoader creates a config loader with AWS SDK clients
+func newDefaultLoader(awsCfg aws.Config) *Loader {
+	return NewLoader(NewCloudFormationClient(awsCfg), NewSecretsManagerClient(awsCfg), NewParameterStoreClient(awsCfg))
+}
+
+// LoadOverrides loads the remote configuration overrides, keyed by the environment variable they replace. Parameters
+// under the Parameter Store path are named after the variable, such as /code-refactor/prod/LOG_LEVEL, and the secret
+// holds a JSON object of variables, which take precedence over the parameters.
+func (l *Loader) LoadOverrides(ctx context.Context, remote RemoteConfig) (map[string]string, error) {
+	overrides := map[string]string{}
+
+	if remote.ParameterPath != "" {
+		input := &ssm.GetParametersByPathInput{
+			Path:           aws.String(remote.ParameterPath),
+			WithDecryption: aws.Bool(true),
+		}
+		for {
+			resp, err := l.parameterClient.GetParametersByPath(ctx, input)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get parameters under %s: %w", remote.ParameterPath, err)
+			}
+			for _, parameter := range resp.Parameters {
+				overrides[path.Base(aws.ToString(parameter.Name))] = aws.ToString(parameter.Value)
+			}
+			if resp.NextToken == nil {
+				break
+			}
+			input.NextToken = resp.NextToken
+		}
+	}
+
+	if remote.SecretID != "" {
+		result, err := l.secretsClient.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+			SecretId: aws.String(remote.SecretID),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve configuration secret: %w", err)
+		}
+
+		var secret map[string]string
+		if err := json.Unmarshal([]byte(aws.ToString(result.SecretString)), &secret); err != nil {
+			return nil, fmt.Errorf("failed to parse configuration secret JSON: %w", err)
+		}
+		for name, value := range secret {
+			overrides[name] = value
+		}
+	}
+
+	return overrides, nil
+}
+
 // LoadStackOutputs loads configuration values from CloudFormation stack outputs
 func (l *Loader) LoadStackOutputs(ctx context.Context, stackName string, cfg *Config) error {
 	resp, err := l.cfnClient.DescribeStacks(ctx, &cfn.DescribeStacksInput{
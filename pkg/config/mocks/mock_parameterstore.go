@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/pkg/config (interfaces: ParameterStoreClient)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	ssm "github.com/aws/aws-sdk-go-v2/service/ssm"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockParameterStoreClient is a mock of ParameterStoreClient interface.
+type MockParameterStoreClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockParameterStoreClientMockRecorder
+}
+
+// MockParameterStoreClientMockRecorder is the mock recorder for MockParameterStoreClient.
+type MockParameterStoreClientMockRecorder struct {
+	mock *MockParameterStoreClient
+}
+
+// NewMockParameterStoreClient creates a new mock instance.
+func NewMockParameterStoreClient(ctrl *gomock.Controller) *MockParameterStoreClient {
+	mock := &MockParameterStoreClient{ctrl: ctrl}
+	mock.recorder = &MockParameterStoreClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockParameterStoreClient) EXPECT() *MockParameterStoreClientMockRecorder {
+	return m.recorder
+}
+
+// GetParametersByPath mocks base method.
+func (m *MockParameterStoreClient) GetParametersByPath(arg0 context.Context, arg1 *ssm.GetParametersByPathInput, arg2 ...func(*ssm.Options)) (*ssm.GetParametersByPathOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetParametersByPath", varargs...)
+	ret0, _ := ret[0].(*ssm.GetParametersByPathOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetParametersByPath indicates an expected call of GetParametersByPath.
+func (mr *MockParameterStoreClientMockRecorder) GetParametersByPath(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetParametersByPath", reflect.TypeOf((*MockParameterStoreClient)(nil).GetParametersByPath), varargs...)
+}
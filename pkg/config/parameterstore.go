@@ -0,0 +1,32 @@
+package config
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+// ParameterStoreClient interface for SSM Parameter Store operations
+//
+//go:generate mockgen -destination=./mocks/mock_parameterstore.go -mock_names=ParameterStoreClient=MockParameterStoreClient -package=mocks . ParameterStoreClient
+type ParameterStoreClient interface {
+	GetParametersByPath(ctx context.Context, params *ssm.GetParametersByPathInput, optFns ...func(*ssm.Options)) (*ssm.GetParametersByPathOutput, error)
+}
+
+// DefaultParameterStoreClient implements ParameterStoreClient using AWS SDK
+type DefaultParameterStoreClient struct {
+	client *ssm.Client
+}
+
+// NewParameterStoreClient creates a new Parameter Store client
+func NewParameterStoreClient(cfg aws.Config) ParameterStoreClient {
+	return &DefaultParameterStoreClient{
+		client: ssm.NewFromConfig(cfg),
+	}
+}
+
+// GetParametersByPath implements ParameterStoreClient
+func (c *DefaultParameterStoreClient) GetParametersByPath(ctx context.Context, params *ssm.GetParametersByPathInput, optFns ...func(*ssm.Options)) (*ssm.GetParametersByPathOutput, error) {
+	return c.client.GetParametersByPath(ctx, params, optFns...)
+}
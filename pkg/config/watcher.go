@@ -0,0 +1,152 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"sync"
+	"time"
+)
+
+// overlay tracks the environment variables replaced by remote overrides, keeping their original values so an
+// override that is removed restores the variable
+var overlay = struct {
+	sync.Mutex
+	originals map[string]*string
+	applied   map[string]string
+}{originals: map[string]*string{}, applied: map[string]string{}}
+
+// applyOverrides replaces environment variables with the remote overrides, restoring the ones no longer overridden.
+func applyOverrides(overrides map[string]string) {
+	overlay.Lock()
+	defer overlay.Unlock()
+
+	for name, original := range overlay.originals {
+		if _, ok := overrides[name]; ok {
+			continue
+		}
+		setEnv(name, original)
+		delete(overlay.originals, name)
+	}
+
+	for name, value := range overrides {
+		if _, ok := overlay.originals[name]; !ok {
+			if original, set := os.LookupEnv(name); set {
+				overlay.originals[name] = &original
+			} else {
+				overlay.originals[name] = nil
+			}
+		}
+		setEnv(name, &value)
+	}
+	overlay.applied = maps.Clone(overrides)
+}
+
+// appliedOverrides returns the remote overrides the environment holds.
+func appliedOverrides() map[string]string {
+	overlay.Lock()
+	defer overlay.Unlock()
+	return maps.Clone(overlay.applied)
+}
+
+// setEnv sets an environment variable, unsetting it when value is nil.
+func setEnv(name string, value *string) {
+	var err error
+	if value == nil {
+		err = os.Unsetenv(name)
+	} else {
+		err = os.Setenv(name, *value)
+	}
+	if err != nil {
+		slog.Warn("failed to apply configuration override", "name", name, "error", err)
+	}
+}
+
+// Watcher reloads the configuration when its remote overrides change and notifies the components that react to it.
+// Settings read once at startup, such as database connections, keep their startup values until a restart.
+type Watcher struct {
+	loader *Loader
+	remote RemoteConfig
+
+	mu          sync.RWMutex
+	current     Config
+	subscribers []func(cfg Config)
+}
+
+// NewWatcher creates a watcher of the remote overrides of cfg. A nil loader uses the AWS clients of cfg.
+func NewWatcher(cfg Config, loader *Loader) *Watcher {
+	if loader == nil {
+		loader = newDefaultLoader(cfg.AWSConfig)
+	}
+
+	return &Watcher{
+		loader:  loader,
+		remote:  cfg.Remote,
+		current: cfg,
+	}
+}
+
+// Current returns the configuration as of the last reload.
+func (w *Watcher) Current() Config {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.current
+}
+
+// Subscribe registers a function called with the reloaded configuration after every change. The log level is
+// applied on reload without a subscription.
+func (w *Watcher) Subscribe(fn func(cfg Config)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.subscribers = append(w.subscribers, fn)
+}
+
+// Run checks the remote overrides for changes every refresh interval until ctx is cancelled.
+func (w *Watcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.remote.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := w.Refresh(ctx); err != nil {
+				slog.ErrorContext(ctx, "failed to refresh configuration", "error", err)
+			}
+		}
+	}
+}
+
+// Refresh reloads the configuration if its remote overrides changed, reporting whether it did. When the reloaded
+// configuration is invalid, the current one is kept and subscribers aren't notified until the overrides change again.
+func (w *Watcher) Refresh(ctx context.Context) (bool, error) {
+	loadCtx, cancel := context.WithTimeout(ctx, w.remote.Timeout)
+	defer cancel()
+
+	overrides, err := w.loader.LoadOverrides(loadCtx, w.remote)
+	if err != nil {
+		return false, fmt.Errorf("failed to load remote configuration: %w", err)
+	}
+	if maps.Equal(overrides, appliedOverrides()) {
+		return false, nil
+	}
+
+	cfg, err := LoadConfigWithDependencies(w.loader)
+	if err != nil {
+		return false, fmt.Errorf("failed to reload configuration: %w", err)
+	}
+
+	w.mu.Lock()
+	w.current = cfg
+	subscribers := append([]func(cfg Config){}, w.subscribers...)
+	w.mu.Unlock()
+
+	slog.InfoContext(ctx, "Reloaded configuration", "overrides", len(overrides))
+	for _, subscriber := range subscribers {
+		subscriber(cfg)
+	}
+	return true, nil
+}
@@ -0,0 +1,141 @@
+package config_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmTypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config/mocks"
+)
+
+// parameters returns a Parameter Store page holding the given name and value pairs
+func parameters(nameValues ...string) *ssm.GetParametersByPathOutput {
+	output := &ssm.GetParametersByPathOutput{}
+	for i := 0; i < len(nameValues); i += 2 {
+		output.Parameters = append(output.Parameters, ssmTypes.Parameter{
+			Name:  aws.String(nameValues[i]),
+			Value: aws.String(nameValues[i+1]),
+		})
+	}
+	return output
+}
+
+func TestLoader_LoadOverrides_SecretTakesPrecedenceOverParameters(t *testing.T) {
+	// Setup
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSecretsClient := mocks.NewMockSecretsManagerClient(ctrl)
+	mockParameterClient := mocks.NewMockParameterStoreClient(ctrl)
+	loader := config.NewLoader(mocks.NewMockCloudFormationClient(ctrl), mockSecretsClient, mockParameterClient)
+
+	firstPage := parameters("/code-refactor/prod/LOG_LEVEL", "debug", "/code-refactor/prod/GIT_TOKEN", "ghp_from_parameter")
+	firstPage.NextToken = aws.String("page-2")
+	gomock.InOrder(
+		mockParameterClient.EXPECT().
+			GetParametersByPath(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input *ssm.GetParametersByPathInput, _ ...func(*ssm.Options)) (*ssm.GetParametersByPathOutput, error) {
+				assert.Equal(t, "/code-refactor/prod", aws.ToString(input.Path))
+				assert.True(t, aws.ToBool(input.WithDecryption))
+				assert.Nil(t, input.NextToken)
+				return firstPage, nil
+			}),
+		mockParameterClient.EXPECT().
+			GetParametersByPath(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input *ssm.GetParametersByPathInput, _ ...func(*ssm.Options)) (*ssm.GetParametersByPathOutput, error) {
+				assert.Equal(t, "page-2", aws.ToString(input.NextToken))
+				return parameters("/code-refactor/prod/REPORTS_LOOKBACK_DAYS", "5"), nil
+			}),
+	)
+	mockSecretsClient.EXPECT().
+		GetSecretValue(gomock.Any(), gomock.Any()).
+		Return(&secretsmanager.GetSecretValueOutput{SecretString: aws.String(`{"GIT_TOKEN":"ghp_from_secret"}`)}, nil)
+
+	// Act
+	overrides, err := loader.LoadOverrides(context.Background(), config.RemoteConfig{
+		ParameterPath: "/code-refactor/prod",
+		SecretID:      "code-refactor/prod/config",
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"LOG_LEVEL":             "debug",
+		"GIT_TOKEN":             "ghp_from_secret",
+		"REPORTS_LOOKBACK_DAYS": "5",
+	}, overrides)
+}
+
+func TestWatcher_Refresh_NotifiesSubscribersOfChangedOverrides(t *testing.T) {
+	// Setup
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockParameterClient := mocks.NewMockParameterStoreClient(ctrl)
+	loader := config.NewLoader(mocks.NewMockCloudFormationClient(ctrl), mocks.NewMockSecretsManagerClient(ctrl), mockParameterClient)
+
+	// Required settings come from the parameters, and the lookback days from the environment until overridden
+	require.NoError(t, os.Setenv("CONFIG_PARAMETER_PATH", "/code-refactor/dev"))
+	require.NoError(t, os.Setenv("AI_LOCAL_ENABLED", "true"))
+	require.NoError(t, os.Setenv("REPORTS_LOOKBACK_DAYS", "3"))
+	defer func() {
+		os.Unsetenv("CONFIG_PARAMETER_PATH") //nolint:errcheck
+		os.Unsetenv("AI_LOCAL_ENABLED")      //nolint:errcheck
+		os.Unsetenv("REPORTS_LOOKBACK_DAYS") //nolint:errcheck
+	}()
+
+	required := []string{
+		"/code-refactor/dev/GIT_TOKEN", "ghp_testtoken123",
+		"/code-refactor/dev/COGNITO_USER_POOL_ID", "us-east-1_123456789",
+		"/code-refactor/dev/COGNITO_CLIENT_ID", "1234567890abcdef",
+	}
+	initial := parameters(required...)
+	changed := parameters(append(required, "/code-refactor/dev/REPORTS_LOOKBACK_DAYS", "7")...)
+	gomock.InOrder(
+		// Startup
+		mockParameterClient.EXPECT().GetParametersByPath(gomock.Any(), gomock.Any()).Return(initial, nil),
+		// Unchanged refresh
+		mockParameterClient.EXPECT().GetParametersByPath(gomock.Any(), gomock.Any()).Return(initial, nil),
+		// Changed refresh, then its reload
+		mockParameterClient.EXPECT().GetParametersByPath(gomock.Any(), gomock.Any()).Return(changed, nil).Times(2),
+		// Removed override, then its reload
+		mockParameterClient.EXPECT().GetParametersByPath(gomock.Any(), gomock.Any()).Return(initial, nil).Times(2),
+	)
+
+	cfg, err := config.LoadConfigWithDependencies(loader)
+	require.NoError(t, err)
+	assert.Equal(t, "ghp_testtoken123", cfg.Git.Token)
+	assert.Equal(t, 3, cfg.Reports.LookbackDays)
+
+	watcher := config.NewWatcher(cfg, loader)
+	var notified []int
+	watcher.Subscribe(func(cfg config.Config) {
+		notified = append(notified, cfg.Reports.LookbackDays)
+	})
+
+	// Act
+	unchanged, unchangedErr := watcher.Refresh(context.Background())
+	reloaded, reloadErr := watcher.Refresh(context.Background())
+	reloadedDays := watcher.Current().Reports.LookbackDays
+	restored, restoreErr := watcher.Refresh(context.Background())
+
+	// Assert
+	require.NoError(t, unchangedErr)
+	require.NoError(t, reloadErr)
+	require.NoError(t, restoreErr)
+	assert.False(t, unchanged)
+	assert.True(t, reloaded)
+	assert.Equal(t, 7, reloadedDays)
+	assert.True(t, restored, "removing an override restores the environment variable")
+	assert.Equal(t, []int{7, 3}, notified)
+	assert.Equal(t, "3", os.Getenv("REPORTS_LOOKBACK_DAYS"))
+}
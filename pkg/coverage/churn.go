@@ -0,0 +1,30 @@
+package coverage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Churn counts the commits changing each file of the git checkout in dir since a time, by path relative to the
+// repository root. Merge commits are left out, since they repeat the changes of the commits they merge.
+func Churn(ctx context.Context, dir string, since time.Time) (map[string]int, error) {
+	out, err := exec.CommandContext(ctx, "git", "-C", dir, "log", "--no-merges", "--format=", "--name-only",
+		"--since="+since.UTC().Format(time.RFC3339)).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read git history: %w", err)
+	}
+
+	churn := map[string]int{}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		if file := strings.TrimSpace(scanner.Text()); file != "" {
+			churn[file]++
+		}
+	}
+	return churn, scanner.Err()
+}
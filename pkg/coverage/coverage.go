@@ -0,0 +1,47 @@
+// Package coverage maps the coverage profiles of test runs to the functions they leave uncovered and ranks those
+// functions by how often their files changed recently.
+package coverage
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Function is a function with statements no test runs
+type Function struct {
+	File       string // Path of the declaring file, relative to the repository root
+	Name       string // Name of the function, qualified by its receiver type for methods
+	StartLine  int
+	EndLine    int
+	Statements int // Statements of the function, or lines for profiles that don't count statements
+	Uncovered  int // Statements no test runs
+	Commits    int // Commits changing the function's file in the churn window
+}
+
+// Score weighs the uncovered statements of a function by the churn of its file, so that untested code changing
+// often ranks first
+func (f Function) Score() int {
+	return f.Uncovered * (f.Commits + 1)
+}
+
+// Rank sets the churn of each function's file, orders the functions by score and keeps the first limit of them. A
+// limit of zero keeps them all.
+func Rank(functions []Function, churn map[string]int, limit int) []Function {
+	ranked := slices.Clone(functions)
+	for i := range ranked {
+		ranked[i].Commits = churn[ranked[i].File]
+	}
+	slices.SortStableFunc(ranked, func(a, b Function) int {
+		if c := cmp.Compare(b.Score(), a.Score()); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.File, b.File); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.StartLine, b.StartLine)
+	})
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	return ranked
+}
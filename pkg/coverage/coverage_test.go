@@ -0,0 +1,133 @@
+package coverage
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestParseGoProfile(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "go.mod", "module example.com/app\n\ngo 1.24\n")
+	writeFile(t, dir, "calc/calc.go", `package calc
+
+type Calc struct{}
+
+func Add(a, b int) int {
+	return a + b
+}
+
+func (c *Calc) Div(a, b int) int {
+	if b == 0 {
+		return 0
+	}
+	return a / b
+}
+`)
+	profile := `mode: set
+example.com/app/calc/calc.go:5.24,7.2 1 1
+example.com/app/calc/calc.go:9.34,10.12 1 1
+example.com/app/calc/calc.go:10.12,12.3 1 0
+example.com/app/calc/calc.go:13.2,13.14 1 1
+example.com/other/lib.go:1.1,2.2 3 0
+`
+
+	functions, err := ParseGoProfile(dir, strings.NewReader(profile))
+
+	require.NoError(t, err)
+	assert.Equal(t, []Function{
+		{File: "calc/calc.go", Name: "Calc.Div", StartLine: 9, EndLine: 14, Statements: 3, Uncovered: 1},
+	}, functions, "covered functions and other modules are left out")
+}
+
+func TestParseGoProfile_RequiresGoMod(t *testing.T) {
+	_, err := ParseGoProfile(t.TempDir(), strings.NewReader("mode: set\n"))
+
+	assert.ErrorContains(t, err, "requires a go.mod")
+}
+
+func TestParseLCOV(t *testing.T) {
+	dir := t.TempDir()
+	profile := `TN:
+SF:` + filepath.Join(dir, "src/calc.js") + `
+FN:1,add
+FN:5,div
+FN:12,15,unused
+FNDA:3,add
+FNDA:1,div
+FNDA:0,unused
+DA:2,3
+DA:6,1
+DA:7,0
+DA:9,1
+end_of_record
+SF:/elsewhere/lib.js
+FN:1,lib
+FNDA:0,lib
+end_of_record
+`
+
+	functions, err := ParseLCOV(dir, strings.NewReader(profile))
+
+	require.NoError(t, err)
+	assert.Equal(t, []Function{
+		{File: "src/calc.js", Name: "div", StartLine: 5, EndLine: 9, Statements: 3, Uncovered: 1},
+		{File: "src/calc.js", Name: "unused", StartLine: 12, EndLine: 15, Statements: 0, Uncovered: 1},
+	}, functions)
+}
+
+func TestParseLCOV_InvalidRecord(t *testing.T) {
+	_, err := ParseLCOV(t.TempDir(), strings.NewReader("SF:a.js\nDA:x,1\n"))
+
+	assert.ErrorContains(t, err, "invalid DA record on line 2")
+}
+
+func TestRank(t *testing.T) {
+	functions := []Function{
+		{File: "a.go", Name: "A", StartLine: 1, Uncovered: 10},
+		{File: "b.go", Name: "B", StartLine: 1, Uncovered: 4},
+		{File: "c.go", Name: "C", StartLine: 1, Uncovered: 2},
+	}
+
+	ranked := Rank(functions, map[string]int{"b.go": 4}, 2)
+
+	assert.Equal(t, []Function{
+		{File: "b.go", Name: "B", StartLine: 1, Uncovered: 4, Commits: 4},
+		{File: "a.go", Name: "A", StartLine: 1, Uncovered: 10},
+	}, ranked)
+	assert.Zero(t, functions[1].Commits, "the functions given are left untouched")
+}
+
+func TestChurn(t *testing.T) {
+	dir := t.TempDir()
+	git := func(args ...string) {
+		out, err := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	git("init", "--quiet")
+	writeFile(t, dir, "a.go", "package a\n")
+	writeFile(t, dir, "b.go", "package a\n")
+	git("add", "--all")
+	git("commit", "--quiet", "-m", "first")
+	writeFile(t, dir, "a.go", "package a\n\n// A changed\n")
+	git("commit", "--quiet", "--all", "-m", "second")
+
+	churn, err := Churn(context.Background(), dir, time.Now().Add(-time.Hour))
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"a.go": 2, "b.go": 1}, churn)
+}
@@ -0,0 +1,105 @@
+package coverage
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/tools/cover"
+)
+
+// ParseGoProfile maps a Go cover profile of the module in dir to the functions it leaves uncovered. The profile names
+// files by import path, which are resolved within the module; files of other modules are left out.
+func ParseGoProfile(dir string, profile io.Reader) ([]Function, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return nil, fmt.Errorf("go coverage requires a go.mod: %w", err)
+	}
+	modulePath := modfile.ModulePath(data)
+	if modulePath == "" {
+		return nil, fmt.Errorf("go.mod declares no module path")
+	}
+
+	profiles, err := cover.ParseProfilesFromReader(profile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse cover profile: %w", err)
+	}
+
+	var functions []Function
+	fset := token.NewFileSet()
+	for _, p := range profiles {
+		file, ok := strings.CutPrefix(p.FileName, modulePath+"/")
+		if !ok {
+			continue
+		}
+		parsed, err := parser.ParseFile(fset, filepath.Join(dir, filepath.FromSlash(file)), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+
+		for _, decl := range parsed.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil {
+				continue
+			}
+			function := Function{
+				File:      file,
+				Name:      functionName(fn),
+				StartLine: fset.Position(fn.Pos()).Line,
+				EndLine:   fset.Position(fn.End()).Line,
+			}
+			start, end := fset.Position(fn.Body.Lbrace), fset.Position(fn.Body.Rbrace)
+			for _, block := range p.Blocks {
+				if !within(block.StartLine, block.StartCol, start, end) {
+					continue
+				}
+				function.Statements += block.NumStmt
+				if block.Count == 0 {
+					function.Uncovered += block.NumStmt
+				}
+			}
+			if function.Uncovered > 0 {
+				functions = append(functions, function)
+			}
+		}
+	}
+	return functions, nil
+}
+
+// functionName names a function, qualifying methods by their receiver type
+func functionName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	recv := fn.Recv.List[0].Type
+	if star, ok := recv.(*ast.StarExpr); ok {
+		recv = star.X
+	}
+	switch typ := recv.(type) {
+	case *ast.IndexExpr:
+		recv = typ.X
+	case *ast.IndexListExpr:
+		recv = typ.X
+	}
+	if ident, ok := recv.(*ast.Ident); ok {
+		return ident.Name + "." + fn.Name.Name
+	}
+	return fn.Name.Name
+}
+
+// within tells whether a position falls between the braces of a function body
+func within(line, col int, start, end token.Position) bool {
+	if line < start.Line || line > end.Line {
+		return false
+	}
+	if line == start.Line && col < start.Column {
+		return false
+	}
+	return line != end.Line || col <= end.Column
+}
@@ -0,0 +1,150 @@
+package coverage
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// lcovFunction is a function record of an LCOV source file
+type lcovFunction struct {
+	name      string
+	startLine int
+	endLine   int // Zero when the record doesn't give it
+	hits      int
+}
+
+// ParseLCOV maps an LCOV tracefile, as written by the coverage tools of most languages, to the functions it leaves
+// uncovered. Source files are resolved relative to dir; files outside of it are left out. LCOV counts lines rather
+// than statements, and a function without an end line ends before the next one.
+func ParseLCOV(dir string, profile io.Reader) ([]Function, error) {
+	var (
+		functions []Function
+		file      string
+		records   []lcovFunction
+		lines     = map[int]int{}
+	)
+	flush := func() {
+		if file != "" {
+			functions = append(functions, uncoveredFunctions(file, records, lines)...)
+		}
+		file, records, lines = "", nil, map[int]int{}
+	}
+
+	scanner := bufio.NewScanner(profile)
+	for number := 1; scanner.Scan(); number++ {
+		key, value, _ := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		switch key {
+		case "SF":
+			flush()
+			file = relativeSource(dir, value)
+		case "FN":
+			// FN:<start line>[,<end line>],<name>
+			fields := strings.Split(value, ",")
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("invalid FN record on line %d", number)
+			}
+			record := lcovFunction{name: fields[len(fields)-1]}
+			var err error
+			if record.startLine, err = strconv.Atoi(fields[0]); err != nil {
+				return nil, fmt.Errorf("invalid FN record on line %d: %w", number, err)
+			}
+			if len(fields) > 2 {
+				if record.endLine, err = strconv.Atoi(fields[1]); err != nil {
+					return nil, fmt.Errorf("invalid FN record on line %d: %w", number, err)
+				}
+			}
+			records = append(records, record)
+		case "FNDA":
+			// FNDA:<hits>,<name>
+			hits, name, ok := strings.Cut(value, ",")
+			count, err := strconv.Atoi(hits)
+			if !ok || err != nil {
+				return nil, fmt.Errorf("invalid FNDA record on line %d", number)
+			}
+			for i := range records {
+				if records[i].name == name {
+					records[i].hits += count
+				}
+			}
+		case "DA":
+			// DA:<line>,<hits>[,<checksum>]
+			fields := strings.Split(value, ",")
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("invalid DA record on line %d", number)
+			}
+			line, err := strconv.Atoi(fields[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid DA record on line %d: %w", number, err)
+			}
+			hits, err := strconv.Atoi(fields[1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid DA record on line %d: %w", number, err)
+			}
+			lines[line] += hits
+		case "end_of_record":
+			flush()
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read LCOV tracefile: %w", err)
+	}
+	flush()
+	return functions, nil
+}
+
+// uncoveredFunctions counts the lines of each function of a source file that no test runs
+func uncoveredFunctions(file string, records []lcovFunction, lines map[int]int) []Function {
+	if file == "" {
+		return nil
+	}
+	slices.SortFunc(records, func(a, b lcovFunction) int { return a.startLine - b.startLine })
+
+	var functions []Function
+	for i, record := range records {
+		end := record.endLine
+		if end == 0 {
+			end = record.startLine
+			for line := range lines {
+				if line > end && (i+1 == len(records) || line < records[i+1].startLine) {
+					end = line
+				}
+			}
+		}
+
+		function := Function{File: file, Name: record.name, StartLine: record.startLine, EndLine: end}
+		for line, hits := range lines {
+			if line < record.startLine || line > end {
+				continue
+			}
+			function.Statements++
+			if hits == 0 {
+				function.Uncovered++
+			}
+		}
+		// A function no test calls is uncovered, even when the tracefile gives no line of it
+		if record.hits == 0 && function.Uncovered == 0 {
+			function.Uncovered = max(function.Statements, 1)
+		}
+		if function.Uncovered > 0 {
+			functions = append(functions, function)
+		}
+	}
+	return functions
+}
+
+// relativeSource resolves a source file of a tracefile relative to dir, returning an empty path for files outside of it
+func relativeSource(dir, file string) string {
+	if !filepath.IsAbs(file) {
+		return filepath.ToSlash(filepath.Clean(file))
+	}
+	rel, err := filepath.Rel(dir, file)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,66 @@
+// Package dependency finds the third-party dependencies declared in a repository's manifests and the published
+// vulnerabilities affecting them.
+package dependency
+
+import "context"
+
+// Ecosystem is a package ecosystem, named as in the OSV schema
+type Ecosystem string
+
+const (
+	// EcosystemGo is the Go module ecosystem, declared in go.mod
+	EcosystemGo Ecosystem = "Go"
+
+	// EcosystemNPM is the npm ecosystem, declared in package.json
+	EcosystemNPM Ecosystem = "npm"
+
+	// EcosystemPyPI is the Python package index ecosystem, declared in requirements.txt
+	EcosystemPyPI Ecosystem = "PyPI"
+)
+
+// Severity is the normalized severity of a vulnerability
+type Severity string
+
+const (
+	// SeverityCritical is a critical vulnerability
+	SeverityCritical Severity = "critical"
+
+	// SeverityHigh is a high severity vulnerability
+	SeverityHigh Severity = "high"
+
+	// SeverityMedium is a medium severity vulnerability
+	SeverityMedium Severity = "medium"
+
+	// SeverityLow is a low severity vulnerability
+	SeverityLow Severity = "low"
+
+	// SeverityUnknown is a vulnerability whose advisory doesn't rate it
+	SeverityUnknown Severity = "unknown"
+)
+
+// Dependency is a package version declared in a manifest
+type Dependency struct {
+	Ecosystem Ecosystem
+	Name      string
+	Version   string
+	Manifest  string // Path of the declaring manifest, relative to the repository root
+}
+
+// Vulnerability is a published advisory affecting a dependency
+type Vulnerability struct {
+	ID           string
+	Aliases      []string // Other identifiers of the advisory, such as CVE IDs
+	Summary      string
+	Severity     Severity
+	FixedVersion string // Earliest version fixing the vulnerability, empty when no fix is published
+	URL          string
+	Dependency   Dependency
+}
+
+// AdvisorySource looks up the vulnerabilities affecting dependencies
+//
+//go:generate mockgen -destination=./mocks/mock_advisory_source.go -mock_names=AdvisorySource=MockAdvisorySource -package=mocks . AdvisorySource
+type AdvisorySource interface {
+	// Query returns the vulnerabilities affecting each of the dependencies
+	Query(ctx context.Context, dependencies []Dependency) ([]Vulnerability, error)
+}
@@ -0,0 +1,152 @@
+package dependency
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// manifestParsers parses each supported manifest file name
+var manifestParsers = map[string]func(data []byte) ([]Dependency, error){
+	"go.mod":           parseGoMod,
+	"package.json":     parsePackageJSON,
+	"requirements.txt": parseRequirements,
+}
+
+// ParseManifests finds the manifests under root and returns the dependencies they declare.
+// Vendored, node_modules, testdata and hidden directories are skipped.
+func ParseManifests(root string) ([]Dependency, error) {
+	var dependencies []Dependency
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := entry.Name()
+		if entry.IsDir() {
+			if path != root && (name == "vendor" || name == "node_modules" || name == "testdata" || strings.HasPrefix(name, ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		parse, ok := manifestParsers[name]
+		if !ok {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		declared, err := parse(data)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", rel, err)
+		}
+		for i := range declared {
+			declared[i].Manifest = filepath.ToSlash(rel)
+		}
+		dependencies = append(dependencies, declared...)
+		return nil
+	})
+	return dependencies, err
+}
+
+// parseGoMod returns the modules required by a go.mod, with replacements applied
+func parseGoMod(data []byte) ([]Dependency, error) {
+	file, err := modfile.Parse("go.mod", data, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	replaced := make(map[string]string)
+	for _, replace := range file.Replace {
+		// Local replacements have no version and can't be looked up
+		if replace.New.Version != "" && replace.Old.Version == "" {
+			replaced[replace.Old.Path] = replace.New.Version
+		}
+	}
+
+	dependencies := make([]Dependency, 0, len(file.Require))
+	for _, require := range file.Require {
+		version := require.Mod.Version
+		if replacement, ok := replaced[require.Mod.Path]; ok {
+			version = replacement
+		}
+		dependencies = append(dependencies, Dependency{
+			Ecosystem: EcosystemGo,
+			Name:      require.Mod.Path,
+			Version:   strings.TrimPrefix(version, "v"),
+		})
+	}
+	return dependencies, nil
+}
+
+// npmVersion matches the version of a package.json range pinned to, or starting at, a release
+var npmVersion = regexp.MustCompile(`^[~^=v]*(\d+\.\d+\.\d+[0-9A-Za-z.+-]*)$`)
+
+// parsePackageJSON returns the packages of a package.json's dependencies and devDependencies.
+// Ranges are audited at their lowest version; tags, URLs and complex ranges are skipped.
+func parsePackageJSON(data []byte) ([]Dependency, error) {
+	var manifest struct {
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, err
+	}
+
+	var dependencies []Dependency
+	for _, declared := range []map[string]string{manifest.Dependencies, manifest.DevDependencies} {
+		names := make([]string, 0, len(declared))
+		for name := range declared {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			match := npmVersion.FindStringSubmatch(strings.TrimSpace(declared[name]))
+			if match == nil {
+				continue
+			}
+			dependencies = append(dependencies, Dependency{Ecosystem: EcosystemNPM, Name: name, Version: match[1]})
+		}
+	}
+	return dependencies, nil
+}
+
+// pythonRequirement matches a requirement pinned with == or ===, ignoring extras and environment markers
+var pythonRequirement = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)(?:\[[^\]]*\])?\s*===?\s*([A-Za-z0-9.!+_-]+)`)
+
+// parseRequirements returns the pinned packages of a requirements.txt; unpinned requirements and options are skipped
+func parseRequirements(data []byte) ([]Dependency, error) {
+	var dependencies []Dependency
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if comment := strings.Index(line, "#"); comment >= 0 {
+			line = strings.TrimSpace(line[:comment])
+		}
+		if line == "" || strings.HasPrefix(line, "-") {
+			continue
+		}
+
+		match := pythonRequirement.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		dependencies = append(dependencies, Dependency{Ecosystem: EcosystemPyPI, Name: strings.ToLower(match[1]), Version: match[2]})
+	}
+	return dependencies, scanner.Err()
+}
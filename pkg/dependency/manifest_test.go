@@ -0,0 +1,64 @@
+package dependency
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseManifests(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": `module example.com/service
+
+go 1.24
+
+require (
+	github.com/gin-gonic/gin v1.9.0
+	golang.org/x/net v0.10.0 // indirect
+)
+
+replace golang.org/x/net => golang.org/x/net v0.17.0
+`,
+		"web/package.json": `{
+  "dependencies": {"lodash": "^4.17.15", "react": "latest"},
+  "devDependencies": {"jest": "29.0.0"}
+}`,
+		"worker/requirements.txt": `# pinned
+Jinja2==2.10 ; python_version >= "3.8"
+requests[socks]===2.19.1
+flask>=1.0
+-r base.txt
+`,
+		"web/node_modules/left-pad/package.json": `{"dependencies": {"ignored": "1.0.0"}}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	dependencies, err := ParseManifests(dir)
+
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []Dependency{
+		{Ecosystem: EcosystemGo, Name: "github.com/gin-gonic/gin", Version: "1.9.0", Manifest: "go.mod"},
+		{Ecosystem: EcosystemGo, Name: "golang.org/x/net", Version: "0.17.0", Manifest: "go.mod"},
+		{Ecosystem: EcosystemNPM, Name: "lodash", Version: "4.17.15", Manifest: "web/package.json"},
+		{Ecosystem: EcosystemNPM, Name: "jest", Version: "29.0.0", Manifest: "web/package.json"},
+		{Ecosystem: EcosystemPyPI, Name: "jinja2", Version: "2.10", Manifest: "worker/requirements.txt"},
+		{Ecosystem: EcosystemPyPI, Name: "requests", Version: "2.19.1", Manifest: "worker/requirements.txt"},
+	}, dependencies)
+}
+
+func TestParseManifests_InvalidManifest(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"dependencies":`), 0644))
+
+	_, err := ParseManifests(dir)
+
+	assert.ErrorContains(t, err, "failed to parse package.json")
+}
@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/pkg/dependency (interfaces: AdvisorySource)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	dependency "github.com/kazemisoroush/code-refactoring-tool/pkg/dependency"
+)
+
+// MockAdvisorySource is a mock of AdvisorySource interface.
+type MockAdvisorySource struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdvisorySourceMockRecorder
+}
+
+// MockAdvisorySourceMockRecorder is the mock recorder for MockAdvisorySource.
+type MockAdvisorySourceMockRecorder struct {
+	mock *MockAdvisorySource
+}
+
+// NewMockAdvisorySource creates a new mock instance.
+func NewMockAdvisorySource(ctrl *gomock.Controller) *MockAdvisorySource {
+	mock := &MockAdvisorySource{ctrl: ctrl}
+	mock.recorder = &MockAdvisorySourceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdvisorySource) EXPECT() *MockAdvisorySourceMockRecorder {
+	return m.recorder
+}
+
+// Query mocks base method.
+func (m *MockAdvisorySource) Query(arg0 context.Context, arg1 []dependency.Dependency) ([]dependency.Vulnerability, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Query", arg0, arg1)
+	ret0, _ := ret[0].([]dependency.Vulnerability)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Query indicates an expected call of Query.
+func (mr *MockAdvisorySourceMockRecorder) Query(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Query", reflect.TypeOf((*MockAdvisorySource)(nil).Query), arg0, arg1)
+}
@@ -0,0 +1,269 @@
+package dependency
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OSVAPIURL is the API of the public OSV database
+const OSVAPIURL = "https://api.osv.dev"
+
+// osvBatchSize is the maximum number of queries sent in one querybatch request
+const osvBatchSize = 1000
+
+// osvQuery looks up the vulnerabilities of one package version
+type osvQuery struct {
+	Package struct {
+		Name      string    `json:"name"`
+		Ecosystem Ecosystem `json:"ecosystem"`
+	} `json:"package"`
+	Version string `json:"version"`
+}
+
+// osvBatchResponse lists the IDs of the vulnerabilities matching each query, in query order
+type osvBatchResponse struct {
+	Results []struct {
+		Vulns []struct {
+			ID string `json:"id"`
+		} `json:"vulns"`
+	} `json:"results"`
+}
+
+// osvVulnerability is a vulnerability in the OSV schema
+type osvVulnerability struct {
+	ID               string   `json:"id"`
+	Summary          string   `json:"summary"`
+	Details          string   `json:"details"`
+	Aliases          []string `json:"aliases"`
+	DatabaseSpecific struct {
+		Severity string `json:"severity"`
+	} `json:"database_specific"`
+	Affected []struct {
+		Package struct {
+			Name      string    `json:"name"`
+			Ecosystem Ecosystem `json:"ecosystem"`
+		} `json:"package"`
+		Ranges []struct {
+			Events []struct {
+				Introduced string `json:"introduced"`
+				Fixed      string `json:"fixed"`
+			} `json:"events"`
+		} `json:"ranges"`
+	} `json:"affected"`
+}
+
+// OSVSource implements AdvisorySource over the OSV API, or any advisory database serving the same API
+type OSVSource struct {
+	httpClient *http.Client
+	apiURL     string
+}
+
+// NewOSVSource creates a new OSV advisory source calling apiURL, whose requests time out after timeout
+func NewOSVSource(apiURL string, timeout time.Duration) *OSVSource {
+	return &OSVSource{
+		httpClient: &http.Client{Timeout: timeout},
+		apiURL:     strings.TrimSuffix(apiURL, "/"),
+	}
+}
+
+// Query finds the vulnerability IDs of all dependencies in batches, then fetches each vulnerability once
+func (s *OSVSource) Query(ctx context.Context, dependencies []Dependency) ([]Vulnerability, error) {
+	details := make(map[string]*osvVulnerability)
+	var vulnerabilities []Vulnerability
+
+	for start := 0; start < len(dependencies); start += osvBatchSize {
+		batch := dependencies[start:min(start+osvBatchSize, len(dependencies))]
+
+		queries := make([]osvQuery, len(batch))
+		for i, dependency := range batch {
+			queries[i].Package.Name = dependency.Name
+			queries[i].Package.Ecosystem = dependency.Ecosystem
+			queries[i].Version = dependency.Version
+		}
+
+		var response osvBatchResponse
+		if err := s.do(ctx, http.MethodPost, "/v1/querybatch", map[string]any{"queries": queries}, &response); err != nil {
+			return nil, err
+		}
+		if len(response.Results) != len(batch) {
+			return nil, fmt.Errorf("advisory source returned %d results for %d queries", len(response.Results), len(batch))
+		}
+
+		for i, result := range response.Results {
+			for _, match := range result.Vulns {
+				vulnerability, ok := details[match.ID]
+				if !ok {
+					vulnerability = &osvVulnerability{}
+					if err := s.do(ctx, http.MethodGet, "/v1/vulns/"+url.PathEscape(match.ID), nil, vulnerability); err != nil {
+						return nil, err
+					}
+					details[match.ID] = vulnerability
+				}
+				vulnerabilities = append(vulnerabilities, vulnerability.toVulnerability(batch[i]))
+			}
+		}
+	}
+
+	return vulnerabilities, nil
+}
+
+// do sends a request to the advisory API and decodes the JSON response into out
+func (s *OSVSource) do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode advisory request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, s.apiURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create advisory request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call advisory source: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close advisory response body", "error", closeErr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("advisory source returned status %d: %s", resp.StatusCode, message)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode advisory response: %w", err)
+	}
+	return nil
+}
+
+// toVulnerability converts an OSV vulnerability affecting dependency
+func (v *osvVulnerability) toVulnerability(dependency Dependency) Vulnerability {
+	summary := v.Summary
+	if summary == "" {
+		summary, _, _ = strings.Cut(v.Details, "\n")
+	}
+
+	return Vulnerability{
+		ID:           v.ID,
+		Aliases:      v.Aliases,
+		Summary:      summary,
+		Severity:     parseSeverity(v.DatabaseSpecific.Severity),
+		FixedVersion: v.fixedVersion(dependency),
+		URL:          "https://osv.dev/vulnerability/" + v.ID,
+		Dependency:   dependency,
+	}
+}
+
+// fixedVersion returns the version closing the affected range the dependency's version falls in.
+// When no range can be matched, the highest fixed version of the package is returned.
+func (v *osvVulnerability) fixedVersion(dependency Dependency) string {
+	highest := ""
+	for _, affected := range v.Affected {
+		if affected.Package.Name != dependency.Name || affected.Package.Ecosystem != dependency.Ecosystem {
+			continue
+		}
+		for _, r := range affected.Ranges {
+			introduced := ""
+			for _, event := range r.Events {
+				if event.Introduced != "" {
+					introduced = event.Introduced
+				}
+				if event.Fixed == "" {
+					continue
+				}
+				if CompareVersions(dependency.Version, introduced) >= 0 && CompareVersions(dependency.Version, event.Fixed) < 0 {
+					return event.Fixed
+				}
+				if highest == "" || CompareVersions(event.Fixed, highest) > 0 {
+					highest = event.Fixed
+				}
+			}
+		}
+	}
+	return highest
+}
+
+// parseSeverity normalizes the severity ratings used by GitHub and other advisory databases
+func parseSeverity(rating string) Severity {
+	switch strings.ToUpper(rating) {
+	case "CRITICAL":
+		return SeverityCritical
+	case "HIGH":
+		return SeverityHigh
+	case "MODERATE", "MEDIUM":
+		return SeverityMedium
+	case "LOW":
+		return SeverityLow
+	default:
+		return SeverityUnknown
+	}
+}
+
+// CompareVersions compares dotted versions part by part, numerically where both parts are numbers.
+// It returns -1, 0 or 1; "0" and "" sort before every release, as OSV uses "0" for "every version".
+func CompareVersions(a, b string) int {
+	split := func(version string) []string {
+		return strings.FieldsFunc(strings.TrimPrefix(version, "v"), func(r rune) bool {
+			return r == '.' || r == '-' || r == '+'
+		})
+	}
+	partsA, partsB := split(a), split(b)
+
+	for i := 0; i < max(len(partsA), len(partsB)); i++ {
+		var partA, partB string
+		if i < len(partsA) {
+			partA = partsA[i]
+		}
+		if i < len(partsB) {
+			partB = partsB[i]
+		}
+
+		numberA, errA := strconv.Atoi(partA)
+		numberB, errB := strconv.Atoi(partB)
+		switch {
+		case partA == partB:
+			continue
+		case errA == nil && errB == nil:
+			if numberA < numberB {
+				return -1
+			}
+			return 1
+		case partA == "":
+			// A release sorts after its pre-releases, 1.0.0 > 1.0.0-rc1
+			if errB != nil {
+				return 1
+			}
+			return -1
+		case partB == "":
+			if errA != nil {
+				return -1
+			}
+			return 1
+		case partA < partB:
+			return -1
+		default:
+			return 1
+		}
+	}
+	return 0
+}
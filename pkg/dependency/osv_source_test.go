@@ -0,0 +1,91 @@
+package dependency
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOSVSource_Query(t *testing.T) {
+	vulnFetches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/querybatch":
+			var request struct {
+				Queries []osvQuery `json:"queries"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+			require.Len(t, request.Queries, 2)
+			assert.Equal(t, "jinja2", request.Queries[0].Package.Name)
+			assert.Equal(t, EcosystemPyPI, request.Queries[0].Package.Ecosystem)
+			assert.Equal(t, "2.10", request.Queries[0].Version)
+			_, _ = w.Write([]byte(`{"results":[{"vulns":[{"id":"GHSA-462w-v97r-4m45"}]},{}]}`))
+		case "/v1/vulns/GHSA-462w-v97r-4m45":
+			vulnFetches++
+			_, _ = w.Write([]byte(`{
+				"id":"GHSA-462w-v97r-4m45",
+				"summary":"Jinja2 sandbox escape",
+				"aliases":["CVE-2019-10906"],
+				"database_specific":{"severity":"HIGH"},
+				"affected":[{"package":{"name":"jinja2","ecosystem":"PyPI"},"ranges":[{"type":"ECOSYSTEM","events":[
+					{"introduced":"0"},{"fixed":"2.10.1"},{"introduced":"2.11.0"},{"fixed":"2.11.3"}
+				]}]}]
+			}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	source := NewOSVSource(server.URL+"/", 5*time.Second)
+	jinja := Dependency{Ecosystem: EcosystemPyPI, Name: "jinja2", Version: "2.10", Manifest: "requirements.txt"}
+
+	vulnerabilities, err := source.Query(context.Background(), []Dependency{jinja, {Ecosystem: EcosystemPyPI, Name: "flask", Version: "2.3.2"}})
+
+	require.NoError(t, err)
+	assert.Equal(t, []Vulnerability{{
+		ID:           "GHSA-462w-v97r-4m45",
+		Aliases:      []string{"CVE-2019-10906"},
+		Summary:      "Jinja2 sandbox escape",
+		Severity:     SeverityHigh,
+		FixedVersion: "2.10.1",
+		URL:          "https://osv.dev/vulnerability/GHSA-462w-v97r-4m45",
+		Dependency:   jinja,
+	}}, vulnerabilities)
+	assert.Equal(t, 1, vulnFetches)
+}
+
+func TestOSVSource_Query_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+
+	_, err := NewOSVSource(server.URL, 5*time.Second).Query(context.Background(), []Dependency{{Ecosystem: EcosystemGo, Name: "golang.org/x/net", Version: "0.10.0"}})
+
+	assert.ErrorContains(t, err, "status 503")
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{a: "2.10", b: "2.10.1", expected: -1},
+		{a: "2.10.1", b: "2.9", expected: 1},
+		{a: "1.0.0", b: "1.0.0-rc1", expected: 1},
+		{a: "v0.17.0", b: "0.17.0", expected: 0},
+		{a: "1.2.3", b: "0", expected: 1},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, CompareVersions(tt.a, tt.b), "%s vs %s", tt.a, tt.b)
+	}
+}
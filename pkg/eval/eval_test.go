@@ -0,0 +1,150 @@
+package eval_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/eval"
+)
+
+// writeFixture creates a fixture with a repository holding files
+func writeFixture(t *testing.T, dir, name, caseJSON string, files map[string]string) {
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, name, "repo"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name, "case.json"), []byte(caseJSON), 0o644))
+	for path, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name, "repo", path), []byte(content), 0o644))
+	}
+}
+
+func TestLoadCases(t *testing.T) {
+	dir := t.TempDir()
+	writeFixture(t, dir, "b-case", `{"task_type":"refactoring","description":"Rename","expected":{"changed_files":["a.txt"]}}`, nil)
+	writeFixture(t, dir, "a-case", `{"task_type":"refactoring","description":"Fix","test_command":"make test","expected":{}}`, nil)
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "not-a-fixture"), 0o755))
+
+	cases, err := eval.LoadCases(dir)
+
+	require.NoError(t, err)
+	require.Len(t, cases, 2)
+	assert.Equal(t, "a-case", cases[0].Name)
+	assert.Equal(t, filepath.Join(dir, "a-case", "repo"), cases[0].RepoDir)
+	assert.Equal(t, "go build ./...", cases[0].BuildCommand)
+	assert.Equal(t, "make test", cases[0].TestCommand)
+	assert.Equal(t, []string{"a.txt"}, cases[1].Expected.ChangedFiles)
+}
+
+func TestLoadCases_InvalidPattern(t *testing.T) {
+	dir := t.TempDir()
+	writeFixture(t, dir, "broken", `{"task_type":"refactoring","description":"Fix","expected":{"required_patterns":["(unclosed"]}}`, nil)
+
+	_, err := eval.LoadCases(dir)
+
+	assert.ErrorContains(t, err, `invalid expectation of fixture broken: invalid pattern "(unclosed"`)
+}
+
+func TestLoadCases_BundledFixtures(t *testing.T) {
+	cases, err := eval.LoadCases(filepath.Join("..", "..", "evals", "fixtures"))
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, cases)
+}
+
+func TestCase_CheckoutApplyDiffAndRunCommand(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	writeFixture(t, dir, "edit", `{"task_type":"refactoring","description":"Edit","expected":{}}`, map[string]string{"notes.txt": "one\ntwo\n"})
+	cases, err := eval.LoadCases(dir)
+	require.NoError(t, err)
+
+	checkout := t.TempDir()
+	require.NoError(t, cases[0].Checkout(context.Background(), checkout))
+
+	diff := "diff --git a/notes.txt b/notes.txt\n--- a/notes.txt\n+++ b/notes.txt\n@@ -1,2 +1,2 @@\n one\n-two\n+three\n"
+	require.NoError(t, eval.ApplyDiff(context.Background(), checkout, diff))
+	content, err := os.ReadFile(filepath.Join(checkout, "notes.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "one\nthree\n", string(content))
+
+	passed, err := eval.RunCommand(context.Background(), checkout, "grep -q three notes.txt")
+	require.NoError(t, err)
+	assert.True(t, passed.Passed)
+
+	failed, err := eval.RunCommand(context.Background(), checkout, "grep -q two notes.txt")
+	require.NoError(t, err, "a failing command is a result, not an error")
+	assert.False(t, failed.Passed)
+
+	_, err = eval.RunCommand(context.Background(), checkout, "no-such-command-for-eval")
+	assert.Error(t, err)
+}
+
+func TestScoreDiff(t *testing.T) {
+	diff := strings.Join([]string{
+		"diff --git a/stats.go b/stats.go",
+		"--- a/stats.go",
+		"+++ b/stats.go",
+		"@@ -1,3 +1,4 @@",
+		"-	sum := 0.0",
+		"+	return average(values)",
+		"+func average(values []float64) float64 {",
+		"diff --git a/other.go b/other.go",
+		"--- a/other.go",
+		"+++ b/other.go",
+		"@@ -1 +1 @@",
+		"+// TODO: tidy",
+	}, "\n")
+
+	tests := []struct {
+		name     string
+		expected eval.Expectation
+		want     eval.DiffQuality
+	}{
+		{
+			name:     "expected change",
+			expected: eval.Expectation{ChangedFiles: []string{"stats.go", "other.go"}, RequiredPatterns: []string{`func average\(`}},
+			want: eval.DiffQuality{
+				FilesChanged: []string{"stats.go", "other.go"}, LinesAdded: 3, LinesRemoved: 1,
+				FilePrecision: 1, FileRecall: 1, PatternsMatched: 1, PatternsRequired: 1, WithinLineBudget: true, Score: 1,
+			},
+		},
+		{
+			name: "unexpected file, forbidden pattern and over budget",
+			expected: eval.Expectation{
+				ChangedFiles:      []string{"stats.go"},
+				RequiredPatterns:  []string{`func average\(`, `func median\(`},
+				ForbiddenPatterns: []string{"TODO"},
+				MaxChangedLines:   2,
+			},
+			want: eval.DiffQuality{
+				FilesChanged: []string{"stats.go", "other.go"}, LinesAdded: 3, LinesRemoved: 1,
+				FilePrecision: 0.5, FileRecall: 1, PatternsMatched: 1, PatternsRequired: 2,
+				ForbiddenMatched: []string{"TODO"}, Score: (2.0/3 + 0.5) / 4,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := eval.ScoreDiff(diff, tt.expected)
+
+			assert.InDelta(t, tt.want.Score, got.Score, 1e-9)
+			got.Score = tt.want.Score
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestScoreDiff_EmptyDiff(t *testing.T) {
+	quality := eval.ScoreDiff("", eval.Expectation{ChangedFiles: []string{"stats.go"}})
+
+	assert.Zero(t, quality.Score)
+	assert.Empty(t, quality.FilesChanged)
+}
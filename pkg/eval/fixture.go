@@ -0,0 +1,189 @@
+// Package eval holds the golden repositories refactoring runs are evaluated against, and scores the outcome of a run
+// against a fixture's expectations.
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const (
+	// caseFile describes a fixture, next to its repository
+	caseFile = "case.json"
+
+	// repoDir holds the golden repository of a fixture
+	repoDir = "repo"
+
+	// defaultBuildCommand checks that a fixture's repository compiles
+	defaultBuildCommand = "go build ./..."
+
+	// defaultTestCommand runs a fixture's tests
+	defaultTestCommand = "go test ./..."
+
+	// maxCommandOutputLength keeps the end of a command's output, where failures are summarised
+	maxCommandOutputLength = 4000
+)
+
+// Case is a fixture: a golden repository, the task run against it and the expected outcome
+type Case struct {
+	Name         string      `json:"-"`
+	RepoDir      string      `json:"-"`
+	TaskType     string      `json:"task_type"`
+	Title        string      `json:"title"`
+	Description  string      `json:"description"`
+	BuildCommand string      `json:"build_command,omitempty"`
+	TestCommand  string      `json:"test_command,omitempty"`
+	Expected     Expectation `json:"expected"`
+}
+
+// Expectation is the outcome expected from a run of a fixture's task
+type Expectation struct {
+	// ChangedFiles lists the files a good change touches, relative to the repository root
+	ChangedFiles []string `json:"changed_files"`
+
+	// RequiredPatterns are regular expressions each matching an added line of a good change
+	RequiredPatterns []string `json:"required_patterns,omitempty"`
+
+	// ForbiddenPatterns are regular expressions no added line may match
+	ForbiddenPatterns []string `json:"forbidden_patterns,omitempty"`
+
+	// MaxChangedLines bounds the added and removed lines of a good change, unbounded when zero
+	MaxChangedLines int `json:"max_changed_lines,omitempty"`
+}
+
+// LoadCases reads the fixtures of dir, one per subdirectory holding a case.json and a repo directory, in name order
+func LoadCases(dir string) ([]Case, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixtures: %w", err)
+	}
+
+	var cases []Case
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name(), caseFile))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fixture %s: %w", entry.Name(), err)
+		}
+
+		var c Case
+		if err := json.Unmarshal(data, &c); err != nil {
+			return nil, fmt.Errorf("invalid fixture %s: %w", entry.Name(), err)
+		}
+		c.Name = entry.Name()
+		c.RepoDir = filepath.Join(dir, entry.Name(), repoDir)
+		if info, err := os.Stat(c.RepoDir); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("fixture %s has no %s directory", c.Name, repoDir)
+		}
+		if c.TaskType == "" || c.Description == "" {
+			return nil, fmt.Errorf("fixture %s needs a task_type and a description", c.Name)
+		}
+		if err := c.Expected.validate(); err != nil {
+			return nil, fmt.Errorf("invalid expectation of fixture %s: %w", c.Name, err)
+		}
+		if c.BuildCommand == "" {
+			c.BuildCommand = defaultBuildCommand
+		}
+		if c.TestCommand == "" {
+			c.TestCommand = defaultTestCommand
+		}
+		cases = append(cases, c)
+	}
+
+	slices.SortFunc(cases, func(a, b Case) int { return strings.Compare(a.Name, b.Name) })
+	return cases, nil
+}
+
+// Checkout copies the fixture's repository into dir and commits it, so that changes to it can be diffed
+func (c Case) Checkout(ctx context.Context, dir string) error {
+	err := filepath.WalkDir(c.RepoDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relative, err := filepath.Rel(c.RepoDir, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dir, relative)
+		if entry.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, 0o644)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to copy fixture %s: %w", c.Name, err)
+	}
+
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "--all"},
+		{"-c", "user.name=eval", "-c", "user.email=eval@localhost", "commit", "--quiet", "--allow-empty", "-m", c.Name},
+	} {
+		if out, err := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to commit fixture %s: %w: %s", c.Name, err, out)
+		}
+	}
+	return nil
+}
+
+// ApplyDiff applies a diff produced by git to a checkout
+func ApplyDiff(ctx context.Context, dir, diff string) error {
+	if strings.TrimSpace(diff) == "" {
+		return nil
+	}
+	cmd := exec.CommandContext(ctx, "git", "-C", dir, "apply", "--whitespace=nowarn", "-")
+	cmd.Stdin = strings.NewReader(diff)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to apply diff: %w: %s", err, out)
+	}
+	return nil
+}
+
+// CommandResult is the outcome of a build or test command
+type CommandResult struct {
+	Passed bool   `json:"passed"`
+	Output string `json:"output,omitempty"`
+}
+
+// RunCommand runs a command in dir, reporting a non-zero exit status as a failed result rather than as an error
+func RunCommand(ctx context.Context, dir, command string) (CommandResult, error) {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return CommandResult{}, errors.New("empty command")
+	}
+
+	cmd := exec.CommandContext(ctx, fields[0], fields[1:]...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	result := CommandResult{Passed: err == nil, Output: string(output)}
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return CommandResult{}, fmt.Errorf("failed to run %s: %w", fields[0], err)
+		}
+	}
+	if len(result.Output) > maxCommandOutputLength {
+		result.Output = "... (output truncated)\n" + result.Output[len(result.Output)-maxCommandOutputLength:]
+	}
+	return result, nil
+}
@@ -0,0 +1,115 @@
+package eval
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// DiffQuality scores a diff against an expectation. Score is the mean of four checks, each from 0 to 1: the F1 score
+// of the changed files against the expected ones, the share of required patterns matched, the absence of forbidden
+// patterns and staying within the changed lines budget. An empty diff scores 0.
+type DiffQuality struct {
+	FilesChanged     []string `json:"files_changed"`
+	LinesAdded       int      `json:"lines_added"`
+	LinesRemoved     int      `json:"lines_removed"`
+	FilePrecision    float64  `json:"file_precision"`
+	FileRecall       float64  `json:"file_recall"`
+	PatternsMatched  int      `json:"patterns_matched"`
+	PatternsRequired int      `json:"patterns_required"`
+	ForbiddenMatched []string `json:"forbidden_matched,omitempty"`
+	WithinLineBudget bool     `json:"within_line_budget"`
+	Score            float64  `json:"score"`
+}
+
+// validate checks that the patterns of an expectation compile
+func (e Expectation) validate() error {
+	for _, pattern := range append(slices.Clone(e.RequiredPatterns), e.ForbiddenPatterns...) {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	if e.MaxChangedLines < 0 {
+		return fmt.Errorf("max_changed_lines must not be negative")
+	}
+	return nil
+}
+
+// ScoreDiff scores a diff produced by git against the expectation
+func ScoreDiff(diff string, expected Expectation) DiffQuality {
+	quality := DiffQuality{FilesChanged: []string{}, PatternsRequired: len(expected.RequiredPatterns)}
+
+	var added []string
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			// diff --git a/path b/path, the new path naming renamed files
+			if index := strings.LastIndex(line, " b/"); index >= 0 {
+				quality.FilesChanged = append(quality.FilesChanged, line[index+len(" b/"):])
+			}
+		case strings.HasPrefix(line, "+++ "), strings.HasPrefix(line, "--- "):
+		case strings.HasPrefix(line, "+"):
+			quality.LinesAdded++
+			added = append(added, line[1:])
+		case strings.HasPrefix(line, "-"):
+			quality.LinesRemoved++
+		}
+	}
+	if len(quality.FilesChanged) == 0 {
+		return quality
+	}
+
+	expectedFiles := 0
+	for _, file := range quality.FilesChanged {
+		if slices.Contains(expected.ChangedFiles, file) {
+			expectedFiles++
+		}
+	}
+	quality.FilePrecision = float64(expectedFiles) / float64(len(quality.FilesChanged))
+	quality.FileRecall = 1
+	if len(expected.ChangedFiles) > 0 {
+		quality.FileRecall = float64(expectedFiles) / float64(len(expected.ChangedFiles))
+	}
+
+	for _, pattern := range expected.RequiredPatterns {
+		if matchesAny(pattern, added) {
+			quality.PatternsMatched++
+		}
+	}
+	for _, pattern := range expected.ForbiddenPatterns {
+		if matchesAny(pattern, added) {
+			quality.ForbiddenMatched = append(quality.ForbiddenMatched, pattern)
+		}
+	}
+	quality.WithinLineBudget = expected.MaxChangedLines == 0 || quality.LinesAdded+quality.LinesRemoved <= expected.MaxChangedLines
+
+	checks := []float64{f1(quality.FilePrecision, quality.FileRecall), 1, 1, 1}
+	if quality.PatternsRequired > 0 {
+		checks[1] = float64(quality.PatternsMatched) / float64(quality.PatternsRequired)
+	}
+	if len(quality.ForbiddenMatched) > 0 {
+		checks[2] = 0
+	}
+	if !quality.WithinLineBudget {
+		checks[3] = 0
+	}
+	for _, check := range checks {
+		quality.Score += check / float64(len(checks))
+	}
+	return quality
+}
+
+// matchesAny reports whether a pattern matches one of the lines. Invalid patterns, rejected when fixtures are loaded,
+// match nothing.
+func matchesAny(pattern string, lines []string) bool {
+	expression, err := regexp.Compile(pattern)
+	return err == nil && slices.ContainsFunc(lines, expression.MatchString)
+}
+
+func f1(precision, recall float64) float64 {
+	if precision+recall == 0 {
+		return 0
+	}
+	return 2 * precision * recall / (precision + recall)
+}
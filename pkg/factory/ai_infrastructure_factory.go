@@ -5,23 +5,39 @@ import (
 	"context"
 
 	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/redact"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/workflow"
 )
 
 // AIInfrastructureFactory creates AI infrastructure on-demand based on configuration
 //
 //go:generate mockgen -destination=./mocks/mock_ai_infrastructure_factory.go -mock_names=AIInfrastructureFactory=MockAIInfrastructureFactory -package=mocks . AIInfrastructureFactory
 type AIInfrastructureFactory interface {
-	// CreateAgentInfrastructure creates AI infrastructure for an agent
-	CreateAgentInfrastructure(ctx context.Context, provider models.AIProvider) (*AIInfrastructureResult, error)
+	// CreateAgentInfrastructure creates AI infrastructure for an agent as the given setup, in the AWS region of Bedrock
+	// agents (the default region when empty) and with the embedding of their knowledge base (the default model when
+	// zero), masking the repository content matched by the redaction policy before it is embedded. A failed setup
+	// keeps what it built, and creating the infrastructure again with its setup ID resumes it after its last completed
+	// step.
+	CreateAgentInfrastructure(ctx context.Context, setup workflow.Setup, provider models.AIProvider, region string, embedding config.Embedding, policy redact.Policy) (*AIInfrastructureResult, error)
 
-	// UpdateAgentInfrastructure updates existing AI infrastructure for an agent
-	UpdateAgentInfrastructure(ctx context.Context, infrastructureID string, provider models.AIProvider) (*AIInfrastructureResult, error)
+	// UpdateAgentInfrastructure updates existing AI infrastructure for an agent in region, creating the new
+	// infrastructure as the given setup with the embedding of its knowledge base
+	UpdateAgentInfrastructure(ctx context.Context, setup workflow.Setup, infrastructureID string, provider models.AIProvider, region string, embedding config.Embedding, policy redact.Policy) (*AIInfrastructureResult, error)
 
-	// ValidateAgentConfig validates an agent's AI provider configuration
-	ValidateAgentConfig(provider models.AIProvider) error
+	// ValidateAgentConfig validates an agent's AI provider configuration, the AWS region it's provisioned in, which
+	// must be allowed for Bedrock agents and empty for others, and the embedding of its knowledge base, whose model
+	// must produce its dimensions for Bedrock agents and which must be zero for others
+	ValidateAgentConfig(provider models.AIProvider, region string, embedding config.Embedding) error
 
-	// DestroyAgentInfrastructure cleans up AI infrastructure for an agent
-	DestroyAgentInfrastructure(ctx context.Context, infrastructureID string) error
+	// DestroyAgentInfrastructure cleans up AI infrastructure for an agent in region
+	DestroyAgentInfrastructure(ctx context.Context, infrastructureID string, region string) error
+
+	// TearDownAgentSetup tears down the resources a failed or interrupted setup built
+	TearDownAgentSetup(ctx context.Context, setupID string) error
+
+	// RecoverInterruptedSetups fails the setups a crash interrupted so they can be resumed or torn down
+	RecoverInterruptedSetups(ctx context.Context) error
 }
 
 // AIInfrastructureResult contains the result of creating AI infrastructure
@@ -38,11 +54,18 @@ type AIInfrastructureResult struct {
 	// VectorStoreID is the ID of the vector store (if created)
 	VectorStoreID string
 
+	// Embedding is the model and dimensions the knowledge base embeds with, zero when the provider embeds with its
+	// configured model
+	Embedding config.Embedding
+
 	// Status indicates the current status of the infrastructure
 	Status models.AgentStatus
 
 	// Metadata contains provider-specific metadata
 	Metadata map[string]interface{}
+
+	// Redactions counts the content masked before it was embedded
+	Redactions redact.Report
 }
 
 // AgentCreationRequest contains parameters for creating an agent
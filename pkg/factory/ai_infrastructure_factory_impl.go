@@ -2,59 +2,111 @@ package factory
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"strings"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/kazemisoroush/code-refactoring-tool/api/models"
 	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/builder"
 	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/rag"
 	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/storage"
 	"github.com/kazemisoroush/code-refactoring-tool/pkg/codebase"
 	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/objectstore"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/redact"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/scan"
 	"github.com/kazemisoroush/code-refactoring-tool/pkg/workflow"
 )
 
+// setupInputRegion is the run value recording the region a Bedrock setup builds in
+const setupInputRegion = "region"
+
 // DefaultAIInfrastructureFactory implements the AIInfrastructureFactory interface
 type DefaultAIInfrastructureFactory struct {
-	// AWS configuration for Bedrock
-	awsConfig aws.Config
+	// AWS configuration and clients of the regions Bedrock agents are provisioned in
+	clients *RegionalClients
 
 	// Base AI configuration from program config
 	aiConfig config.AIConfig
 
 	// Git configuration
 	gitConfig config.GitConfig
+
+	// Scans repositories before their content is uploaded
+	scanner scan.ContentScanner
+
+	// Archives of the codebases uploaded rather than hosted on a git provider, and the most they extract to
+	archives         objectstore.ObjectStore
+	maxExtractedSize int64
+
+	// Store the content ingested into local knowledge bases is uploaded to
+	contentStore storage.DataStore
+
+	// Runs the setup workflows, persisting their progress
+	engine *workflow.Engine
 }
 
 // NewAIInfrastructureFactory creates a new AI infrastructure factory
-func NewAIInfrastructureFactory(awsConfig aws.Config, aiConfig config.AIConfig, gitConfig config.GitConfig) AIInfrastructureFactory {
+func NewAIInfrastructureFactory(
+	clients *RegionalClients,
+	aiConfig config.AIConfig,
+	gitConfig config.GitConfig,
+	scanner scan.ContentScanner,
+	archives objectstore.ObjectStore,
+	uploads config.CodebaseUploadsConfig,
+	contentStore storage.DataStore,
+	engine *workflow.Engine,
+) AIInfrastructureFactory {
 	return &DefaultAIInfrastructureFactory{
-		awsConfig: awsConfig,
-		aiConfig:  aiConfig,
-		gitConfig: gitConfig,
+		clients:          clients,
+		aiConfig:         aiConfig,
+		gitConfig:        gitConfig,
+		scanner:          scanner,
+		archives:         archives,
+		maxExtractedSize: uploads.MaxExtractedSize,
+		contentStore:     contentStore,
+		engine:           engine,
 	}
 }
 
 // CreateAgentInfrastructure creates AI infrastructure for an agent
-func (f *DefaultAIInfrastructureFactory) CreateAgentInfrastructure(ctx context.Context, provider models.AIProvider) (*AIInfrastructureResult, error) {
+func (f *DefaultAIInfrastructureFactory) CreateAgentInfrastructure(ctx context.Context, setup workflow.Setup, provider models.AIProvider, region string, embedding config.Embedding, policy redact.Policy) (*AIInfrastructureResult, error) {
+	redactor, err := redact.NewRedactor(policy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redaction policy: %w", err)
+	}
+
 	switch provider {
 	case models.AIProviderBedrock:
-		return f.createBedrockInfrastructure(ctx)
+		resolved, err := config.ResolveEmbedding(embedding)
+		if err != nil {
+			return nil, fmt.Errorf("invalid embedding: %w", err)
+		}
+		return f.createBedrockInfrastructure(ctx, setup, f.bedrockRegion(region), resolved, redactor)
 	case models.AIProviderLocal:
-		return f.createLocalInfrastructure(ctx)
+		return f.createLocalInfrastructure(ctx, setup, redactor)
 	default:
 		return nil, fmt.Errorf("unsupported AI provider: %s", provider)
 	}
 }
 
 // ValidateAgentConfig validates an agent's AI provider configuration
-func (f *DefaultAIInfrastructureFactory) ValidateAgentConfig(provider models.AIProvider) error {
+func (f *DefaultAIInfrastructureFactory) ValidateAgentConfig(provider models.AIProvider, region string, embedding config.Embedding) error {
 	switch provider {
 	case models.AIProviderBedrock:
-		return f.validateBedrockConfig()
+		if _, err := config.ResolveEmbedding(embedding); err != nil {
+			return err
+		}
+		return f.validateBedrockConfig(region)
 	case models.AIProviderLocal:
+		if region != "" {
+			return fmt.Errorf("local agents don't run in an AWS region")
+		}
+		if !embedding.IsZero() {
+			return fmt.Errorf("local agents embed with the configured model %s", f.aiConfig.Local.EmbeddingModel)
+		}
 		return f.validateLocalConfig()
 	default:
 		return fmt.Errorf("unsupported AI provider: %s", provider)
@@ -62,8 +114,8 @@ func (f *DefaultAIInfrastructureFactory) ValidateAgentConfig(provider models.AIP
 }
 
 // DestroyAgentInfrastructure cleans up AI infrastructure for an agent
-func (f *DefaultAIInfrastructureFactory) DestroyAgentInfrastructure(ctx context.Context, infrastructureID string) error {
-	slog.Info("Destroying AI infrastructure", "infrastructure_id", infrastructureID)
+func (f *DefaultAIInfrastructureFactory) DestroyAgentInfrastructure(ctx context.Context, infrastructureID string, region string) error {
+	slog.InfoContext(ctx, "Destroying AI infrastructure", "infrastructure_id", infrastructureID, "region", region)
 
 	// In a real implementation, we would:
 	// 1. Query the database to get the infrastructure metadata (provider, resource IDs, etc.)
@@ -77,7 +129,7 @@ func (f *DefaultAIInfrastructureFactory) DestroyAgentInfrastructure(ctx context.
 
 	switch provider {
 	case models.AIProviderBedrock:
-		return f.teardownBedrockInfrastructure(ctx, infrastructureID)
+		return f.teardownBedrockInfrastructure(ctx, infrastructureID, f.bedrockRegion(region))
 	case models.AIProviderLocal:
 		return f.teardownLocalInfrastructure(ctx, infrastructureID)
 	default:
@@ -86,11 +138,11 @@ func (f *DefaultAIInfrastructureFactory) DestroyAgentInfrastructure(ctx context.
 }
 
 // UpdateAgentInfrastructure updates existing AI infrastructure for an agent
-func (f *DefaultAIInfrastructureFactory) UpdateAgentInfrastructure(ctx context.Context, infrastructureID string, provider models.AIProvider) (*AIInfrastructureResult, error) {
-	slog.Info("Updating AI infrastructure", "infrastructure_id", infrastructureID, "provider", provider)
+func (f *DefaultAIInfrastructureFactory) UpdateAgentInfrastructure(ctx context.Context, setup workflow.Setup, infrastructureID string, provider models.AIProvider, region string, embedding config.Embedding, policy redact.Policy) (*AIInfrastructureResult, error) {
+	slog.InfoContext(ctx, "Updating AI infrastructure", "infrastructure_id", infrastructureID, "provider", provider)
 
 	// Validate the configuration first
-	if err := f.ValidateAgentConfig(provider); err != nil {
+	if err := f.ValidateAgentConfig(provider, region, embedding); err != nil {
 		return nil, fmt.Errorf("invalid agent configuration: %w", err)
 	}
 
@@ -98,19 +150,19 @@ func (f *DefaultAIInfrastructureFactory) UpdateAgentInfrastructure(ctx context.C
 	// TODO: In the future, this can be optimized to do in-place updates where possible
 
 	// First, try to destroy existing infrastructure
-	if err := f.DestroyAgentInfrastructure(ctx, infrastructureID); err != nil {
-		slog.Warn("Failed to destroy existing infrastructure during update",
+	if err := f.DestroyAgentInfrastructure(ctx, infrastructureID, region); err != nil {
+		slog.WarnContext(ctx, "Failed to destroy existing infrastructure during update",
 			"infrastructure_id", infrastructureID, "error", err)
 		// Continue with creation anyway
 	}
 
 	// Create new infrastructure with updated configuration
-	result, err := f.CreateAgentInfrastructure(ctx, provider)
+	result, err := f.CreateAgentInfrastructure(ctx, setup, provider, region, embedding, policy)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create updated infrastructure: %w", err)
 	}
 
-	slog.Info("AI infrastructure updated successfully",
+	slog.InfoContext(ctx, "AI infrastructure updated successfully",
 		"old_infrastructure_id", infrastructureID,
 		"new_infrastructure_id", result.AgentID,
 		"provider", provider)
@@ -118,95 +170,68 @@ func (f *DefaultAIInfrastructureFactory) UpdateAgentInfrastructure(ctx context.C
 	return result, nil
 }
 
-// createBedrockInfrastructure creates AWS Bedrock infrastructure
-func (f *DefaultAIInfrastructureFactory) createBedrockInfrastructure(ctx context.Context) (*AIInfrastructureResult, error) {
+// createBedrockInfrastructure creates AWS Bedrock infrastructure in region, with a knowledge base of the resolved
+// embedding
+func (f *DefaultAIInfrastructureFactory) createBedrockInfrastructure(ctx context.Context, setup workflow.Setup, region string, embedding config.Embedding, redactor *redact.Redactor) (*AIInfrastructureResult, error) {
 	config := f.aiConfig.Bedrock
 
-	// Create repository instance
-	repo := codebase.NewGitHubCodebase(f.gitConfig)
-
-	// Create Bedrock dependencies
-	dataStore := storage.NewS3DataStore(f.awsConfig, config.S3BucketName, repo.GetPath())
-	storageImpl := storage.NewRDSPostgresStorage(f.awsConfig, "lambda-arn-placeholder") // TODO: Add Lambda ARN to config
-	ragImpl := rag.NewBedrockRAG(f.awsConfig, repo.GetPath(), config.KnowledgeBaseServiceRoleARN, config.RDSPostgres)
-
-	// Create Bedrock RAG builder
-	ragBuilder := builder.NewBedrockRAGBuilder(
-		repo.GetPath(),
-		dataStore,
-		storageImpl,
-		ragImpl,
-	)
-
-	// Create Bedrock agent builder
-	agentBuilder := builder.NewBedrockAgentBuilder(
-		f.awsConfig,
-		repo.GetPath(),
-		config.AgentServiceRoleARN,
-	)
+	// The region is stored with a new run, so that a failed setup is torn down where it was built
+	if setup.Input != nil {
+		setup.Input = maps.Clone(setup.Input)
+		setup.Input[setupInputRegion] = region
+	}
 
 	// Create and run workflow
-	wf, err := workflow.NewCreateBedrockSetupWorkflow(repo, ragBuilder, agentBuilder)
+	wf, ragBuilder, err := f.newBedrockSetupWorkflow(setup, region, embedding, redactor)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Bedrock setup workflow: %w", err)
 	}
 
 	err = wf.Run(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to run Bedrock setup workflow: %w", err)
+		return nil, fmt.Errorf("failed to run Bedrock setup workflow %s: %w", setup.RunID, err)
 	}
 
 	// Get resource IDs from workflow
-	setupWf := wf.(*workflow.CreateBedrockSetupWorkflow)
-	vectorStoreID, ragID, agentID, agentVersion := setupWf.GetResourceIDs()
+	vectorStoreID, ragID, agentID, agentVersion := wf.GetResourceIDs()
 
 	return &AIInfrastructureResult{
 		AgentID:         agentID,
 		AgentVersion:    agentVersion,
 		KnowledgeBaseID: ragID,
 		VectorStoreID:   vectorStoreID,
+		Embedding:       embedding,
 		Status:          models.AgentStatusInitializing,
 		Metadata: map[string]interface{}{
-			"provider":     "bedrock",
-			"region":       config.Region,
-			"model":        config.FoundationModel,
-			"service_role": config.AgentServiceRoleARN,
-			"s3_bucket":    config.S3BucketName,
+			"provider":             "bedrock",
+			"region":               region,
+			"model":                config.FoundationModel,
+			"embedding_model":      embedding.Model,
+			"embedding_dimensions": embedding.Dimensions,
+			"service_role":         config.AgentServiceRoleARN,
+			"s3_bucket":            config.BucketName(region),
 		},
+		Redactions: ragBuilder.(builder.RedactionReporter).Redactions(),
 	}, nil
 }
 
 // createLocalInfrastructure creates local Ollama + ChromaDB infrastructure
-func (f *DefaultAIInfrastructureFactory) createLocalInfrastructure(ctx context.Context) (*AIInfrastructureResult, error) {
+func (f *DefaultAIInfrastructureFactory) createLocalInfrastructure(ctx context.Context, setup workflow.Setup, redactor *redact.Redactor) (*AIInfrastructureResult, error) {
 	config := f.aiConfig.Local
 
-	// Create repository instance
-	repo := codebase.NewGitHubCodebase(f.gitConfig)
-
-	// Create RAG builder
-	ragBuilder := builder.NewLocalRAGBuilder(
-		repo.GetPath(),
-		config.ChromaURL,
-		config.EmbeddingModel,
-	)
-
-	// Create agent builder
-	agentBuilder := builder.NewLocalAgentBuilder(config.OllamaURL, config.Model)
-
 	// Create and run workflow
-	wf, err := workflow.NewCreateLocalSetupWorkflow(repo, ragBuilder, agentBuilder)
+	wf, ragBuilder, err := f.newLocalSetupWorkflow(setup, redactor)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create local setup workflow: %w", err)
 	}
 
 	err = wf.Run(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to run local setup workflow: %w", err)
+		return nil, fmt.Errorf("failed to run local setup workflow %s: %w", setup.RunID, err)
 	}
 
 	// Get resource IDs from workflow
-	setupWf := wf.(*workflow.CreateLocalSetupWorkflow)
-	vectorStoreID, ragID, agentID, agentVersion := setupWf.GetResourceIDs()
+	vectorStoreID, ragID, agentID, agentVersion := wf.GetResourceIDs()
 
 	return &AIInfrastructureResult{
 		AgentID:         agentID,
@@ -219,15 +244,152 @@ func (f *DefaultAIInfrastructureFactory) createLocalInfrastructure(ctx context.C
 			"ollama_url": config.OllamaURL,
 			"model":      config.Model,
 		},
+		Redactions: ragBuilder.(builder.RedactionReporter).Redactions(),
 	}, nil
 }
 
-// validateRepositoryURL validates the GitHub repository URL
-func (f *DefaultAIInfrastructureFactory) validateBedrockConfig() error {
+// newBedrockSetupWorkflow wires the Bedrock setup workflow in region and returns it with its RAG builder. A setup
+// only torn down needs no embedding.
+func (f *DefaultAIInfrastructureFactory) newBedrockSetupWorkflow(setup workflow.Setup, region string, embedding config.Embedding, redactor *redact.Redactor) (*workflow.CreateBedrockSetupWorkflow, builder.RAGBuilder, error) {
+	config := f.aiConfig.Bedrock
+	awsConfig := f.clients.Config(region)
+
+	repo, err := f.repository(setup)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Create Bedrock dependencies
+	dataStore := f.clients.DataStore(region, config.BucketName(region))
+	dataSource := f.clients.DataSource(region, config.BucketName(region))
+	storageImpl := storage.NewRDSPostgresStorage(awsConfig, "lambda-arn-placeholder") // TODO: Add Lambda ARN to config
+	ragImpl := rag.NewResilientRAG(rag.NewBedrockRAG(awsConfig, repo.GetPath(), config.KnowledgeBaseServiceRoleARN, config.RDSPostgres, embedding), f.clients.Guard("bedrock", region))
+	ingester := f.clients.Ingester(region)
+
+	// Create Bedrock RAG builder
+	ragBuilder := builder.NewBedrockRAGBuilder(
+		repo.GetPath(),
+		dataStore,
+		dataSource,
+		storageImpl,
+		embedding.Dimensions,
+		ragImpl,
+		ingester,
+		redactor,
+	)
+
+	// Create Bedrock agent builder
+	agentBuilder := builder.NewBedrockAgentBuilder(
+		awsConfig,
+		repo.GetPath(),
+		config.AgentServiceRoleARN,
+	)
+
+	wf, err := workflow.NewCreateBedrockSetupWorkflow(f.engine, setup, repo, f.scanner, ragBuilder, agentBuilder)
+	if err != nil {
+		return nil, nil, err
+	}
+	return wf.(*workflow.CreateBedrockSetupWorkflow), ragBuilder, nil
+}
+
+// newLocalSetupWorkflow wires the local setup workflow and returns it with its RAG builder
+func (f *DefaultAIInfrastructureFactory) newLocalSetupWorkflow(setup workflow.Setup, redactor *redact.Redactor) (*workflow.CreateLocalSetupWorkflow, builder.RAGBuilder, error) {
+	config := f.aiConfig.Local
+
+	repo, err := f.repository(setup)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Create RAG builder
+	ragBuilder := builder.NewLocalRAGBuilder(
+		repo.GetPath(),
+		config.ChromaURL,
+		config.EmbeddingModel,
+		f.contentStore,
+		redactor,
+	)
+
+	// Create agent builder
+	agentBuilder := builder.NewLocalAgentBuilder(config.OllamaURL, config.Model)
+
+	wf, err := workflow.NewCreateLocalSetupWorkflow(f.engine, setup, repo, ragBuilder, agentBuilder)
+	if err != nil {
+		return nil, nil, err
+	}
+	return wf.(*workflow.CreateLocalSetupWorkflow), ragBuilder, nil
+}
+
+// repository returns the repository a setup builds from: the uploaded archive its repository URL locates, or the
+// configured git repository
+func (f *DefaultAIInfrastructureFactory) repository(setup workflow.Setup) (codebase.Codebase, error) {
+	if _, _, ok := objectstore.ParseURI(setup.RepositoryURL); ok {
+		return codebase.NewArchiveCodebase(f.archives, setup.RepositoryURL, f.maxExtractedSize)
+	}
+	return codebase.NewGitHubCodebase(f.gitConfig), nil
+}
+
+// TearDownAgentSetup tears down the resources a failed or interrupted setup built
+func (f *DefaultAIInfrastructureFactory) TearDownAgentSetup(ctx context.Context, setupID string) error {
+	run, err := f.engine.Run(ctx, setupID)
+	if err != nil {
+		return err
+	}
+	if run == nil {
+		return fmt.Errorf("setup %s not found", setupID)
+	}
+
+	setup := workflow.Setup{RunID: setupID}
+	switch run.Workflow {
+	case workflow.CreateBedrockSetupWorkflowName:
+		wf, _, err := f.newBedrockSetupWorkflow(setup, f.bedrockRegion(run.String(setupInputRegion)), config.Embedding{}, nil)
+		if err != nil {
+			return err
+		}
+		return wf.Rollback(ctx)
+	case workflow.CreateLocalSetupWorkflowName:
+		wf, _, err := f.newLocalSetupWorkflow(setup, nil)
+		if err != nil {
+			return err
+		}
+		return wf.Rollback(ctx)
+	default:
+		return fmt.Errorf("workflow run %s is not an agent setup", setupID)
+	}
+}
+
+// RecoverInterruptedSetups fails the setups a crash interrupted, keeping what they built so they can be resumed or
+// torn down
+func (f *DefaultAIInfrastructureFactory) RecoverInterruptedSetups(ctx context.Context) error {
+	var errs []error
+	for _, workflowName := range []string{workflow.CreateBedrockSetupWorkflowName, workflow.CreateLocalSetupWorkflowName} {
+		runs, err := f.engine.Interrupted(ctx, workflowName)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		for _, run := range runs {
+			if _, err := f.engine.MarkInterrupted(ctx, run.RunID); err != nil {
+				errs = append(errs, fmt.Errorf("failed to mark %s run %s as interrupted: %w", workflowName, run.RunID, err))
+				continue
+			}
+			slog.InfoContext(ctx, "Marked interrupted setup as failed", "workflow", workflowName, "run_id", run.RunID)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateBedrockConfig validates the Bedrock configuration and the region of an agent
+func (f *DefaultAIInfrastructureFactory) validateBedrockConfig(region string) error {
 	config := f.aiConfig.Bedrock
 	if config.Region == "" {
 		return fmt.Errorf("bedrock region is required")
 	}
+	if region != "" && !config.AllowsRegion(region) {
+		return fmt.Errorf("bedrock region %s is not allowed", region)
+	}
 	if config.FoundationModel == "" {
 		return fmt.Errorf("bedrock foundation model is required")
 	}
@@ -237,6 +399,14 @@ func (f *DefaultAIInfrastructureFactory) validateBedrockConfig() error {
 	return nil
 }
 
+// bedrockRegion returns the region of a Bedrock agent, the configured region when it has none
+func (f *DefaultAIInfrastructureFactory) bedrockRegion(region string) string {
+	if region == "" {
+		return f.aiConfig.Bedrock.Region
+	}
+	return region
+}
+
 // validateLocalConfig validates the local AI configuration
 func (f *DefaultAIInfrastructureFactory) validateLocalConfig() error {
 	config := f.aiConfig.Local
@@ -263,21 +433,26 @@ func (f *DefaultAIInfrastructureFactory) determineProviderFromID(infrastructureI
 	return models.AIProviderBedrock
 }
 
-// teardownBedrockInfrastructure tears down Bedrock-specific infrastructure
-func (f *DefaultAIInfrastructureFactory) teardownBedrockInfrastructure(ctx context.Context, infrastructureID string) error {
+// teardownBedrockInfrastructure tears down Bedrock-specific infrastructure in region
+func (f *DefaultAIInfrastructureFactory) teardownBedrockInfrastructure(ctx context.Context, infrastructureID string, region string) error {
+	// A teardown creates no knowledge base, so it needs no embedding
+	var embedding config.Embedding
 	config := f.aiConfig.Bedrock
+	awsConfig := f.clients.Config(region)
 
 	// Create repository instance (needed for cleanup)
 	repo := codebase.NewGitHubCodebase(f.gitConfig)
 
 	// Create Bedrock dependencies for teardown
-	dataStore := storage.NewS3DataStore(f.awsConfig, config.S3BucketName, repo.GetPath())
-	storageImpl := storage.NewRDSPostgresStorage(f.awsConfig, "lambda-arn-placeholder")
-	ragImpl := rag.NewBedrockRAG(f.awsConfig, repo.GetPath(), config.KnowledgeBaseServiceRoleARN, config.RDSPostgres)
+	dataStore := f.clients.DataStore(region, config.BucketName(region))
+	dataSource := f.clients.DataSource(region, config.BucketName(region))
+	storageImpl := storage.NewRDSPostgresStorage(awsConfig, "lambda-arn-placeholder")
+	ragImpl := rag.NewResilientRAG(rag.NewBedrockRAG(awsConfig, repo.GetPath(), config.KnowledgeBaseServiceRoleARN, config.RDSPostgres, embedding), f.clients.Guard("bedrock", region))
+	ingester := f.clients.Ingester(region)
 
 	// Create Bedrock builders for teardown
-	ragBuilder := builder.NewBedrockRAGBuilder(repo.GetPath(), dataStore, storageImpl, ragImpl)
-	agentBuilder := builder.NewBedrockAgentBuilder(f.awsConfig, repo.GetPath(), config.AgentServiceRoleARN)
+	ragBuilder := builder.NewBedrockRAGBuilder(repo.GetPath(), dataStore, dataSource, storageImpl, embedding.Dimensions, ragImpl, ingester, nil)
+	agentBuilder := builder.NewBedrockAgentBuilder(awsConfig, repo.GetPath(), config.AgentServiceRoleARN)
 
 	// Create teardown workflow with resource IDs
 	// In a real implementation, these IDs would come from stored metadata
@@ -299,7 +474,7 @@ func (f *DefaultAIInfrastructureFactory) teardownBedrockInfrastructure(ctx conte
 		return fmt.Errorf("failed to run Bedrock teardown workflow: %w", err)
 	}
 
-	slog.Info("Bedrock infrastructure destroyed successfully", "infrastructure_id", infrastructureID)
+	slog.InfoContext(ctx, "Bedrock infrastructure destroyed successfully", "infrastructure_id", infrastructureID)
 	return nil
 }
 
@@ -311,7 +486,7 @@ func (f *DefaultAIInfrastructureFactory) teardownLocalInfrastructure(ctx context
 	repo := codebase.NewGitHubCodebase(f.gitConfig)
 
 	// Create local builders for teardown
-	ragBuilder := builder.NewLocalRAGBuilder(repo.GetPath(), config.ChromaURL, config.EmbeddingModel)
+	ragBuilder := builder.NewLocalRAGBuilder(repo.GetPath(), config.ChromaURL, config.EmbeddingModel, f.contentStore, nil)
 	agentBuilder := builder.NewLocalAgentBuilder(config.OllamaURL, config.Model)
 
 	// Create teardown workflow with resource IDs
@@ -334,6 +509,6 @@ func (f *DefaultAIInfrastructureFactory) teardownLocalInfrastructure(ctx context
 		return fmt.Errorf("failed to run local teardown workflow: %w", err)
 	}
 
-	slog.Info("Local infrastructure destroyed successfully", "infrastructure_id", infrastructureID)
+	slog.InfoContext(ctx, "Local infrastructure destroyed successfully", "infrastructure_id", infrastructureID)
 	return nil
 }
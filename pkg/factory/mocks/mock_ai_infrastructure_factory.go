@@ -10,7 +10,10 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	models "github.com/kazemisoroush/code-refactoring-tool/api/models"
+	config "github.com/kazemisoroush/code-refactoring-tool/pkg/config"
 	factory "github.com/kazemisoroush/code-refactoring-tool/pkg/factory"
+	redact "github.com/kazemisoroush/code-refactoring-tool/pkg/redact"
+	workflow "github.com/kazemisoroush/code-refactoring-tool/pkg/workflow"
 )
 
 // MockAIInfrastructureFactory is a mock of AIInfrastructureFactory interface.
@@ -37,59 +40,87 @@ func (m *MockAIInfrastructureFactory) EXPECT() *MockAIInfrastructureFactoryMockR
 }
 
 // CreateAgentInfrastructure mocks base method.
-func (m *MockAIInfrastructureFactory) CreateAgentInfrastructure(arg0 context.Context, arg1 models.AIProvider) (*factory.AIInfrastructureResult, error) {
+func (m *MockAIInfrastructureFactory) CreateAgentInfrastructure(arg0 context.Context, arg1 workflow.Setup, arg2 models.AIProvider, arg3 string, arg4 config.Embedding, arg5 redact.Policy) (*factory.AIInfrastructureResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateAgentInfrastructure", arg0, arg1)
+	ret := m.ctrl.Call(m, "CreateAgentInfrastructure", arg0, arg1, arg2, arg3, arg4, arg5)
 	ret0, _ := ret[0].(*factory.AIInfrastructureResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateAgentInfrastructure indicates an expected call of CreateAgentInfrastructure.
-func (mr *MockAIInfrastructureFactoryMockRecorder) CreateAgentInfrastructure(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockAIInfrastructureFactoryMockRecorder) CreateAgentInfrastructure(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAgentInfrastructure", reflect.TypeOf((*MockAIInfrastructureFactory)(nil).CreateAgentInfrastructure), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAgentInfrastructure", reflect.TypeOf((*MockAIInfrastructureFactory)(nil).CreateAgentInfrastructure), arg0, arg1, arg2, arg3, arg4, arg5)
 }
 
 // DestroyAgentInfrastructure mocks base method.
-func (m *MockAIInfrastructureFactory) DestroyAgentInfrastructure(arg0 context.Context, arg1 string) error {
+func (m *MockAIInfrastructureFactory) DestroyAgentInfrastructure(arg0 context.Context, arg1, arg2 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DestroyAgentInfrastructure", arg0, arg1)
+	ret := m.ctrl.Call(m, "DestroyAgentInfrastructure", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DestroyAgentInfrastructure indicates an expected call of DestroyAgentInfrastructure.
-func (mr *MockAIInfrastructureFactoryMockRecorder) DestroyAgentInfrastructure(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockAIInfrastructureFactoryMockRecorder) DestroyAgentInfrastructure(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DestroyAgentInfrastructure", reflect.TypeOf((*MockAIInfrastructureFactory)(nil).DestroyAgentInfrastructure), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DestroyAgentInfrastructure", reflect.TypeOf((*MockAIInfrastructureFactory)(nil).DestroyAgentInfrastructure), arg0, arg1, arg2)
+}
+
+// RecoverInterruptedSetups mocks base method.
+func (m *MockAIInfrastructureFactory) RecoverInterruptedSetups(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecoverInterruptedSetups", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecoverInterruptedSetups indicates an expected call of RecoverInterruptedSetups.
+func (mr *MockAIInfrastructureFactoryMockRecorder) RecoverInterruptedSetups(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecoverInterruptedSetups", reflect.TypeOf((*MockAIInfrastructureFactory)(nil).RecoverInterruptedSetups), arg0)
+}
+
+// TearDownAgentSetup mocks base method.
+func (m *MockAIInfrastructureFactory) TearDownAgentSetup(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TearDownAgentSetup", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TearDownAgentSetup indicates an expected call of TearDownAgentSetup.
+func (mr *MockAIInfrastructureFactoryMockRecorder) TearDownAgentSetup(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TearDownAgentSetup", reflect.TypeOf((*MockAIInfrastructureFactory)(nil).TearDownAgentSetup), arg0, arg1)
 }
 
 // UpdateAgentInfrastructure mocks base method.
-func (m *MockAIInfrastructureFactory) UpdateAgentInfrastructure(arg0 context.Context, arg1 string, arg2 models.AIProvider) (*factory.AIInfrastructureResult, error) {
+func (m *MockAIInfrastructureFactory) UpdateAgentInfrastructure(arg0 context.Context, arg1 workflow.Setup, arg2 string, arg3 models.AIProvider, arg4 string, arg5 config.Embedding, arg6 redact.Policy) (*factory.AIInfrastructureResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateAgentInfrastructure", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "UpdateAgentInfrastructure", arg0, arg1, arg2, arg3, arg4, arg5, arg6)
 	ret0, _ := ret[0].(*factory.AIInfrastructureResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateAgentInfrastructure indicates an expected call of UpdateAgentInfrastructure.
-func (mr *MockAIInfrastructureFactoryMockRecorder) UpdateAgentInfrastructure(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockAIInfrastructureFactoryMockRecorder) UpdateAgentInfrastructure(arg0, arg1, arg2, arg3, arg4, arg5, arg6 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAgentInfrastructure", reflect.TypeOf((*MockAIInfrastructureFactory)(nil).UpdateAgentInfrastructure), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAgentInfrastructure", reflect.TypeOf((*MockAIInfrastructureFactory)(nil).UpdateAgentInfrastructure), arg0, arg1, arg2, arg3, arg4, arg5, arg6)
 }
 
 // ValidateAgentConfig mocks base method.
-func (m *MockAIInfrastructureFactory) ValidateAgentConfig(arg0 models.AIProvider) error {
+func (m *MockAIInfrastructureFactory) ValidateAgentConfig(arg0 models.AIProvider, arg1 string, arg2 config.Embedding) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ValidateAgentConfig", arg0)
+	ret := m.ctrl.Call(m, "ValidateAgentConfig", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // ValidateAgentConfig indicates an expected call of ValidateAgentConfig.
-func (mr *MockAIInfrastructureFactoryMockRecorder) ValidateAgentConfig(arg0 interface{}) *gomock.Call {
+func (mr *MockAIInfrastructureFactoryMockRecorder) ValidateAgentConfig(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateAgentConfig", reflect.TypeOf((*MockAIInfrastructureFactory)(nil).ValidateAgentConfig), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateAgentConfig", reflect.TypeOf((*MockAIInfrastructureFactory)(nil).ValidateAgentConfig), arg0, arg1, arg2)
 }
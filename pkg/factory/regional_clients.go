@@ -0,0 +1,138 @@
+package factory
+
+import (
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/rag"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/storage"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/resilience"
+)
+
+// RegionalClients hands out the AWS configuration and clients of each region agents are provisioned in. They are
+// built on first use and cached, and share the credentials of the base configuration. The calls to each regional
+// service are guarded by a circuit breaker of their own, so an outage in one region doesn't fail the others.
+type RegionalClients struct {
+	base    aws.Config
+	guards  *resilience.Registry
+	uploads *storage.UploadPipeline
+
+	mu          sync.Mutex
+	configs     map[string]aws.Config
+	ingesters   map[string]storage.Ingester
+	retrievers  map[string]rag.Retriever
+	dataStores  map[string]storage.DataStore
+	dataSources map[string]storage.DataSource
+}
+
+// NewRegionalClients creates the regional clients of the base configuration, whose region is used for an empty one,
+// guarding their calls with the guards of the registry. Data stores upload directories with uploads, or with the
+// default pipeline when it's nil.
+func NewRegionalClients(base aws.Config, guards *resilience.Registry, uploads *storage.UploadPipeline) *RegionalClients {
+	return &RegionalClients{
+		base:        base,
+		guards:      guards,
+		uploads:     uploads,
+		configs:     make(map[string]aws.Config),
+		ingesters:   make(map[string]storage.Ingester),
+		retrievers:  make(map[string]rag.Retriever),
+		dataStores:  make(map[string]storage.DataStore),
+		dataSources: make(map[string]storage.DataSource),
+	}
+}
+
+// Config returns the AWS configuration of region
+func (c *RegionalClients) Config(region string) aws.Config {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.config(region)
+}
+
+// config returns the AWS configuration of region, the caller holding mu
+func (c *RegionalClients) config(region string) aws.Config {
+	region = c.region(region)
+	cfg, ok := c.configs[region]
+	if !ok {
+		cfg = c.base.Copy()
+		cfg.Region = region
+		c.configs[region] = cfg
+	}
+	return cfg
+}
+
+// Ingester returns the knowledge base ingester of region
+func (c *RegionalClients) Ingester(region string) storage.Ingester {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	region = c.region(region)
+	ingester, ok := c.ingesters[region]
+	if !ok {
+		ingester = storage.NewBedrockIngester(c.config(region))
+		c.ingesters[region] = ingester
+	}
+	return ingester
+}
+
+// Retriever returns the knowledge base retriever of region
+func (c *RegionalClients) Retriever(region string) rag.Retriever {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	region = c.region(region)
+	retriever, ok := c.retrievers[region]
+	if !ok {
+		retriever = rag.NewResilientRetriever(rag.NewBedrockRetriever(c.config(region)), c.guard("bedrock", region))
+		c.retrievers[region] = retriever
+	}
+	return retriever
+}
+
+// DataStore returns the data store of the content uploaded to bucketName in region
+func (c *RegionalClients) DataStore(region, bucketName string) storage.DataStore {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	region = c.region(region)
+	key := region + "/" + bucketName
+	dataStore, ok := c.dataStores[key]
+	if !ok {
+		dataStore = storage.NewResilientDataStore(storage.NewS3DataStore(c.config(region), bucketName, c.uploads), c.guard("s3", region))
+		c.dataStores[key] = dataStore
+	}
+	return dataStore
+}
+
+// DataSource returns the knowledge base data sources of the content uploaded to bucketName in region
+func (c *RegionalClients) DataSource(region, bucketName string) storage.DataSource {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	region = c.region(region)
+	key := region + "/" + bucketName
+	dataSource, ok := c.dataSources[key]
+	if !ok {
+		dataSource = storage.NewResilientDataSource(storage.NewBedrockDataSource(c.config(region), bucketName), c.guard("bedrock", region))
+		c.dataSources[key] = dataSource
+	}
+	return dataSource
+}
+
+// Guard returns the guard of the calls to service in region
+func (c *RegionalClients) Guard(service, region string) *resilience.Guard {
+	return c.guard(service, c.region(region))
+}
+
+// guard returns the guard of the calls to service in the resolved region
+func (c *RegionalClients) guard(service, region string) *resilience.Guard {
+	return c.guards.Guard(service+":"+region, resilience.AWSTransient)
+}
+
+// region returns region, or the region of the base configuration when it's empty
+func (c *RegionalClients) region(region string) string {
+	if region == "" {
+		return c.base.Region
+	}
+	return region
+}
@@ -0,0 +1,28 @@
+package factory
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/resilience"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegionalClients(t *testing.T) {
+	clients := NewRegionalClients(aws.Config{Region: "us-east-1"}, resilience.NewRegistry(resilience.Policy{MaxAttempts: 1}, nil), nil)
+
+	assert.Equal(t, "us-east-1", clients.Config("").Region)
+	assert.Equal(t, "eu-west-1", clients.Config("eu-west-1").Region)
+
+	// Clients are cached by region
+	assert.Same(t, clients.Ingester("eu-west-1"), clients.Ingester("eu-west-1"))
+	assert.NotSame(t, clients.Ingester("eu-west-1"), clients.Ingester("us-east-1"))
+	assert.Same(t, clients.Retriever(""), clients.Retriever("us-east-1"))
+	assert.Same(t, clients.DataStore("", "content"), clients.DataStore("us-east-1", "content"))
+	assert.NotSame(t, clients.DataStore("eu-west-1", "content"), clients.DataStore("eu-west-1", "content-eu"))
+
+	// Each regional service has a circuit breaker of its own
+	assert.Same(t, clients.Guard("bedrock", ""), clients.Guard("bedrock", "us-east-1"))
+	assert.NotSame(t, clients.Guard("bedrock", "us-east-1"), clients.Guard("bedrock", "eu-west-1"))
+	assert.NotSame(t, clients.Guard("bedrock", "us-east-1"), clients.Guard("s3", "us-east-1"))
+}
@@ -0,0 +1,251 @@
+package gitprovider
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// AzureDevOpsURL is the web and REST API URL of Azure DevOps Services
+const AzureDevOpsURL = "https://dev.azure.com"
+
+// azureDevOpsAPIVersion is the version of the Azure DevOps REST API called
+const azureDevOpsAPIVersion = "7.1"
+
+// fullCommitSHA matches a full commit SHA, which Azure DevOps requires to address a commit
+var fullCommitSHA = regexp.MustCompile(`^[0-9a-fA-F]{40}$`)
+
+// azureDevOpsCommit is a commit in Azure DevOps API responses
+type azureDevOpsCommit struct {
+	CommitID string `json:"commitId"`
+	Comment  string `json:"comment"`
+	Author   struct {
+		Name string    `json:"name"`
+		Date time.Time `json:"date"`
+	} `json:"author"`
+	RemoteURL string `json:"remoteUrl"`
+}
+
+// toCommit converts an Azure DevOps commit to a Commit
+func (c *azureDevOpsCommit) toCommit() *Commit {
+	return &Commit{
+		SHA:        c.CommitID,
+		Message:    c.Comment,
+		AuthorName: c.Author.Name,
+		AuthoredAt: c.Author.Date,
+		URL:        c.RemoteURL,
+	}
+}
+
+// azureDevOpsRef is a ref in Azure DevOps API responses
+type azureDevOpsRef struct {
+	Name     string `json:"name"`
+	ObjectID string `json:"objectId"`
+}
+
+// AzureDevOpsBrowser implements Browser over the Azure DevOps REST API. Repository paths are
+// organization/project/repository, and tokens are personal access tokens.
+type AzureDevOpsBrowser struct {
+	httpClient *http.Client
+	apiURL     string
+}
+
+// NewAzureDevOpsBrowser creates a new Azure DevOps browser whose requests time out after timeout
+func NewAzureDevOpsBrowser(timeout time.Duration) *AzureDevOpsBrowser {
+	return &AzureDevOpsBrowser{
+		httpClient: &http.Client{Timeout: timeout},
+		apiURL:     AzureDevOpsURL,
+	}
+}
+
+// ListBranches lists the repository's branches. Branch policies aren't read, so no branch is reported protected.
+func (b *AzureDevOpsBrowser) ListBranches(ctx context.Context, repo Repository) ([]Branch, error) {
+	refs, err := b.listRefs(ctx, repo, "heads/", maxBranches)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Branch, 0, len(refs))
+	for _, ref := range refs {
+		result = append(result, Branch{Name: strings.TrimPrefix(ref.Name, "refs/heads/"), CommitSHA: ref.ObjectID})
+	}
+	return result, nil
+}
+
+// GetBranch gets a branch of the repository by name
+func (b *AzureDevOpsBrowser) GetBranch(ctx context.Context, repo Repository, name string) (*Branch, error) {
+	// The filter matches every branch starting with the name
+	refs, err := b.listRefs(ctx, repo, "heads/"+name, maxBranches)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ref := range refs {
+		if ref.Name == "refs/heads/"+name {
+			return &Branch{Name: name, CommitSHA: ref.ObjectID}, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// GetCommit gets the commit a ref points to. Azure DevOps doesn't expand abbreviated SHAs, so ref is a branch, a tag
+// or a full SHA.
+func (b *AzureDevOpsBrowser) GetCommit(ctx context.Context, repo Repository, ref string) (*Commit, error) {
+	if fullCommitSHA.MatchString(ref) {
+		var commit azureDevOpsCommit
+		if err := b.get(ctx, repo, "/commits/"+ref, nil, &commit); err != nil {
+			return nil, err
+		}
+		return commit.toCommit(), nil
+	}
+
+	for _, versionType := range []string{"branch", "tag"} {
+		commits, err := b.listCommits(ctx, repo, ref, versionType, 1)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(commits) > 0 {
+			return commits[0].toCommit(), nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// ListCommits lists up to limit commits reachable from ref, newest first
+func (b *AzureDevOpsBrowser) ListCommits(ctx context.Context, repo Repository, ref string, limit int) ([]Commit, error) {
+	commits, err := b.listCommits(ctx, repo, ref, versionType(ref), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Commit, 0, len(commits))
+	for _, commit := range commits {
+		result = append(result, *commit.toCommit())
+	}
+	return result, nil
+}
+
+// ListFiles lists the entries of the directory at path on ref
+func (b *AzureDevOpsBrowser) ListFiles(ctx context.Context, repo Repository, ref, dir string) ([]File, error) {
+	var items struct {
+		Value []struct {
+			Path     string `json:"path"`
+			IsFolder bool   `json:"isFolder"`
+		} `json:"value"`
+	}
+
+	scopePath := "/" + strings.Trim(dir, "/")
+	query := url.Values{"scopePath": {scopePath}, "recursionLevel": {"OneLevel"}}
+	if ref != "" {
+		query.Set("versionDescriptor.version", ref)
+		query.Set("versionDescriptor.versionType", versionType(ref))
+	}
+	if err := b.get(ctx, repo, "/items", query, &items); err != nil {
+		return nil, err
+	}
+
+	result := make([]File, 0, len(items.Value))
+	for _, item := range items.Value {
+		// The listing starts with the directory itself
+		if item.Path == scopePath {
+			continue
+		}
+		fileType := FileTypeFile
+		if item.IsFolder {
+			fileType = FileTypeDir
+		}
+		itemPath := strings.TrimPrefix(item.Path, "/")
+		result = append(result, File{Name: path.Base(itemPath), Path: itemPath, Type: fileType})
+	}
+	return result, nil
+}
+
+// listRefs lists up to limit refs of the repository starting with filter
+func (b *AzureDevOpsBrowser) listRefs(ctx context.Context, repo Repository, filter string, limit int) ([]azureDevOpsRef, error) {
+	var refs struct {
+		Value []azureDevOpsRef `json:"value"`
+	}
+
+	query := url.Values{"filter": {filter}, "$top": {fmt.Sprint(limit)}}
+	if err := b.get(ctx, repo, "/refs", query, &refs); err != nil {
+		return nil, err
+	}
+	return refs.Value, nil
+}
+
+// listCommits lists up to limit commits reachable from version, a ref of versionType; an empty version uses the
+// default branch
+func (b *AzureDevOpsBrowser) listCommits(ctx context.Context, repo Repository, version, versionType string, limit int) ([]azureDevOpsCommit, error) {
+	var commits struct {
+		Value []azureDevOpsCommit `json:"value"`
+	}
+
+	query := url.Values{"searchCriteria.$top": {fmt.Sprint(limit)}}
+	if version != "" {
+		query.Set("searchCriteria.itemVersion.version", version)
+		query.Set("searchCriteria.itemVersion.versionType", versionType)
+	}
+	if err := b.get(ctx, repo, "/commits", query, &commits); err != nil {
+		return nil, err
+	}
+	return commits.Value, nil
+}
+
+// get requests a resource of the repository
+func (b *AzureDevOpsBrowser) get(ctx context.Context, repo Repository, resource string, query url.Values, out any) error {
+	requestURL, err := AzureDevOpsRepositoryAPIURL(b.apiURL, repo)
+	if err != nil {
+		return err
+	}
+
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("api-version", azureDevOpsAPIVersion)
+	requestURL += resource + "?" + query.Encode()
+
+	headers := map[string]string{}
+	if repo.Token != "" {
+		headers["Authorization"] = AzureDevOpsAuthorization(repo.Token)
+	}
+
+	return getJSON(ctx, b.httpClient, requestURL, headers, out)
+}
+
+// AzureDevOpsRepositoryAPIURL is the REST API URL of a repository, whose path is organization/project/repository.
+// Repositories of Azure DevOps Server are addressed under their BaseURL, those of Azure DevOps Services under apiURL.
+func AzureDevOpsRepositoryAPIURL(apiURL string, repo Repository) (string, error) {
+	segments := strings.Split(strings.Trim(repo.Path, "/"), "/")
+	if len(segments) != 3 {
+		return "", fmt.Errorf("azure DevOps repository path %q is not organization/project/repository", repo.Path)
+	}
+	if repo.BaseURL != "" {
+		apiURL = repo.BaseURL
+	}
+
+	return fmt.Sprintf("%s/%s/%s/_apis/git/repositories/%s", strings.TrimSuffix(apiURL, "/"),
+		url.PathEscape(segments[0]), url.PathEscape(segments[1]), url.PathEscape(segments[2])), nil
+}
+
+// AzureDevOpsAuthorization is the Authorization header authenticating with a personal access token
+func AzureDevOpsAuthorization(token string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(":"+token))
+}
+
+// versionType is the Azure DevOps version type of a ref, a full SHA or otherwise a branch
+func versionType(ref string) string {
+	if fullCommitSHA.MatchString(ref) {
+		return "commit"
+	}
+	return "branch"
+}
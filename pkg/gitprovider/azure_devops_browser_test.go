@@ -0,0 +1,104 @@
+package gitprovider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAzureDevOpsBrowser(t *testing.T, handler http.HandlerFunc) *AzureDevOpsBrowser {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	browser := NewAzureDevOpsBrowser(5 * time.Second)
+	browser.apiURL = server.URL
+	return browser
+}
+
+func TestAzureDevOpsBrowser_ListBranches(t *testing.T) {
+	browser := newTestAzureDevOpsBrowser(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/acme/payments/_apis/git/repositories/api/refs", r.URL.Path)
+		assert.Equal(t, "heads/", r.URL.Query().Get("filter"))
+		assert.Equal(t, "7.1", r.URL.Query().Get("api-version"))
+		// Personal access tokens are sent as the password of basic auth
+		assert.Equal(t, "Basic OnBhdC10b2tlbg==", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"value":[{"name":"refs/heads/main","objectId":"abc123"}]}`))
+	})
+
+	branches, err := browser.ListBranches(context.Background(), Repository{Path: "acme/payments/api", Token: "pat-token"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []Branch{{Name: "main", CommitSHA: "abc123"}}, branches)
+}
+
+func TestAzureDevOpsBrowser_GetBranch(t *testing.T) {
+	browser := newTestAzureDevOpsBrowser(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(t, r.URL.Query().Get("filter"), "heads/feature/jw")
+		// The filter is a prefix, so longer branch names are returned too
+		_, _ = w.Write([]byte(`{"value":[{"name":"refs/heads/feature/jwt-auth","objectId":"def456"},
+			{"name":"refs/heads/feature/jwt","objectId":"abc123"}]}`))
+	})
+	repo := Repository{Path: "acme/payments/api"}
+
+	branch, err := browser.GetBranch(context.Background(), repo, "feature/jwt")
+	require.NoError(t, err)
+	assert.Equal(t, &Branch{Name: "feature/jwt", CommitSHA: "abc123"}, branch)
+
+	_, err = browser.GetBranch(context.Background(), repo, "feature/jw")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestAzureDevOpsBrowser_GetCommit(t *testing.T) {
+	browser := newTestAzureDevOpsBrowser(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/acme/payments/_apis/git/repositories/api/commits", r.URL.Path)
+		assert.Equal(t, "v1.2.0", r.URL.Query().Get("searchCriteria.itemVersion.version"))
+		// The ref is resolved as a branch first, then as a tag
+		if r.URL.Query().Get("searchCriteria.itemVersion.versionType") == "branch" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		assert.Equal(t, "1", r.URL.Query().Get("searchCriteria.$top"))
+		_, _ = w.Write([]byte(`{"value":[{"commitId":"abc123","comment":"Release","author":{"name":"Dev",
+			"date":"2024-01-15T10:30:00Z"},"remoteUrl":"https://dev.azure.com/acme/payments/_git/api/commit/abc123"}]}`))
+	})
+
+	commit, err := browser.GetCommit(context.Background(), Repository{Path: "acme/payments/api"}, "v1.2.0")
+
+	require.NoError(t, err)
+	assert.Equal(t, &Commit{
+		SHA:        "abc123",
+		Message:    "Release",
+		AuthorName: "Dev",
+		AuthoredAt: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+		URL:        "https://dev.azure.com/acme/payments/_git/api/commit/abc123",
+	}, commit)
+}
+
+func TestAzureDevOpsBrowser_ListFiles(t *testing.T) {
+	browser := newTestAzureDevOpsBrowser(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/acme/payments/_apis/git/repositories/api/items", r.URL.Path)
+		assert.Equal(t, "/cmd", r.URL.Query().Get("scopePath"))
+		assert.Equal(t, "OneLevel", r.URL.Query().Get("recursionLevel"))
+		_, _ = w.Write([]byte(`{"value":[{"path":"/cmd","isFolder":true},{"path":"/cmd/api","isFolder":true},
+			{"path":"/cmd/main.go","isFolder":false}]}`))
+	})
+
+	files, err := browser.ListFiles(context.Background(), Repository{Path: "acme/payments/api"}, "", "cmd/")
+
+	require.NoError(t, err)
+	assert.Equal(t, []File{{Name: "api", Path: "cmd/api", Type: FileTypeDir}, {Name: "main.go", Path: "cmd/main.go", Type: FileTypeFile}}, files)
+}
+
+func TestAzureDevOpsRepositoryAPIURL(t *testing.T) {
+	apiURL, err := AzureDevOpsRepositoryAPIURL(AzureDevOpsURL, Repository{BaseURL: "https://tfs.acme.com/tfs", Path: "DefaultCollection/Payments/api"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://tfs.acme.com/tfs/DefaultCollection/Payments/_apis/git/repositories/api", apiURL)
+
+	_, err = AzureDevOpsRepositoryAPIURL(AzureDevOpsURL, Repository{Path: "acme/api"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,159 @@
+package gitprovider
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// installationTokenRefreshMargin is how long before it expires a cached installation token is replaced, so a token
+// handed out stays valid for the requests made with it
+const installationTokenRefreshMargin = 5 * time.Minute
+
+// GitHubApp identifies a GitHub App installation and the private key the app signs its requests with
+type GitHubApp struct {
+	APIURL         string // REST API the app is installed on, empty for the token source's default
+	AppID          int64
+	InstallationID int64
+	PrivateKey     string // PEM encoded RSA key, PKCS#1 as GitHub issues it or PKCS#8
+}
+
+// InstallationTokenSource hands out the access tokens of GitHub App installations
+//
+//go:generate mockgen -destination=./mocks/mock_installation_token_source.go -mock_names=InstallationTokenSource=MockInstallationTokenSource -package=mocks . InstallationTokenSource
+type InstallationTokenSource interface {
+	// InstallationToken returns an access token of the app's installation, valid for at least a few minutes
+	InstallationToken(ctx context.Context, app GitHubApp) (string, error)
+}
+
+// GitHubAppTokens implements InstallationTokenSource by exchanging a JWT signed with the app's key for a token of its
+// installation. Tokens last an hour and are cached per installation until shortly before they expire.
+type GitHubAppTokens struct {
+	httpClient *http.Client
+	apiURL     string
+	now        func() time.Time
+
+	mu    sync.Mutex
+	cache map[installationKey]installationToken
+}
+
+// installationKey identifies an installation across GitHub instances
+type installationKey struct {
+	apiURL         string
+	appID          int64
+	installationID int64
+}
+
+// installationToken is a cached installation access token
+type installationToken struct {
+	token     string
+	expiresAt time.Time
+}
+
+// NewGitHubAppTokens creates a new installation token source calling the REST API at apiURL for the apps that don't
+// name one, whose requests time out after timeout
+func NewGitHubAppTokens(apiURL string, timeout time.Duration) *GitHubAppTokens {
+	return &GitHubAppTokens{
+		httpClient: &http.Client{Timeout: timeout},
+		apiURL:     strings.TrimSuffix(apiURL, "/"),
+		now:        time.Now,
+		cache:      make(map[installationKey]installationToken),
+	}
+}
+
+// InstallationToken returns the cached token of the app's installation, or exchanges a new one
+func (t *GitHubAppTokens) InstallationToken(ctx context.Context, app GitHubApp) (string, error) {
+	key := installationKey{apiURL: strings.TrimSuffix(app.APIURL, "/"), appID: app.AppID, installationID: app.InstallationID}
+	if key.apiURL == "" {
+		key.apiURL = t.apiURL
+	}
+
+	t.mu.Lock()
+	cached, ok := t.cache[key]
+	t.mu.Unlock()
+	if ok && t.now().Add(installationTokenRefreshMargin).Before(cached.expiresAt) {
+		return cached.token, nil
+	}
+
+	jwt, err := t.appJWT(app)
+	if err != nil {
+		return "", err
+	}
+
+	headers := map[string]string{
+		"Accept":               "application/vnd.github+json",
+		"X-GitHub-Api-Version": "2022-11-28",
+		"Authorization":        "Bearer " + jwt,
+	}
+	var token struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	requestURL := fmt.Sprintf("%s/app/installations/%d/access_tokens", key.apiURL, app.InstallationID)
+	if err := sendJSON(ctx, t.httpClient, http.MethodPost, requestURL, headers, struct{}{}, &token); err != nil {
+		return "", fmt.Errorf("failed to get installation token: %w", err)
+	}
+
+	t.mu.Lock()
+	t.cache[key] = installationToken{token: token.Token, expiresAt: token.ExpiresAt}
+	t.mu.Unlock()
+
+	return token.Token, nil
+}
+
+// appJWT signs the short-lived RS256 JWT authenticating as the app. It is issued a minute in the past, allowing for
+// clock drift, and expires within the ten minutes GitHub allows.
+func (t *GitHubAppTokens) appJWT(app GitHubApp) (string, error) {
+	key, err := ParseRSAPrivateKey(app.PrivateKey)
+	if err != nil {
+		return "", err
+	}
+
+	now := t.now()
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]any{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": fmt.Sprint(app.AppID),
+	})
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign app JWT: %w", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// ParseRSAPrivateKey parses a PEM encoded RSA private key in PKCS#1 or PKCS#8 form
+func ParseRSAPrivateKey(privateKey string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(privateKey))
+	if block == nil {
+		return nil, errors.New("private key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not an RSA key")
+	}
+	return key, nil
+}
@@ -0,0 +1,72 @@
+package gitprovider
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitHubAppTokens_InstallationToken_CachesUntilExpiry(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	privateKey := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+
+	now := time.Date(2024, time.January, 15, 10, 0, 0, 0, time.UTC)
+	exchanges := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/app/installations/42/access_tokens", r.URL.Path)
+		exchanges++
+		_, _ = fmt.Fprintf(w, `{"token":"ghs-%d","expires_at":%q}`, exchanges, now.Add(time.Hour).Format(time.RFC3339))
+	}))
+	defer server.Close()
+
+	tokens := NewGitHubAppTokens(server.URL, 5*time.Second)
+	tokens.now = func() time.Time { return now }
+	app := GitHubApp{AppID: 7, InstallationID: 42, PrivateKey: privateKey}
+
+	token, err := tokens.InstallationToken(context.Background(), app)
+	require.NoError(t, err)
+	assert.Equal(t, "ghs-1", token)
+
+	now = now.Add(50 * time.Minute)
+	token, err = tokens.InstallationToken(context.Background(), app)
+	require.NoError(t, err)
+	assert.Equal(t, "ghs-1", token, "the cached token is valid for ten more minutes")
+
+	now = now.Add(6 * time.Minute)
+	token, err = tokens.InstallationToken(context.Background(), app)
+	require.NoError(t, err)
+	assert.Equal(t, "ghs-2", token, "a token about to expire is replaced")
+}
+
+func TestGitHubAppTokens_InstallationToken_InvalidKey(t *testing.T) {
+	tokens := NewGitHubAppTokens("https://api.github.com", 5*time.Second)
+
+	_, err := tokens.InstallationToken(context.Background(), GitHubApp{AppID: 7, InstallationID: 42, PrivateKey: "not a key"})
+
+	assert.Error(t, err)
+}
+
+func TestParseRSAPrivateKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	parsed, err := ParseRSAPrivateKey(string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8})))
+	require.NoError(t, err)
+	assert.True(t, key.Equal(parsed))
+
+	_, err = ParseRSAPrivateKey("not a key")
+	assert.Error(t, err)
+}
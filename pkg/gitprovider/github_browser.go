@@ -0,0 +1,193 @@
+package gitprovider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// GitHubAPIURL is the REST API of github.com
+const GitHubAPIURL = "https://api.github.com"
+
+// maxBranches is the number of branches listed, a single page of the providers' APIs
+const maxBranches = 100
+
+// githubBranch is a branch in GitHub API responses
+type githubBranch struct {
+	Name   string `json:"name"`
+	Commit struct {
+		SHA string `json:"sha"`
+	} `json:"commit"`
+	Protected bool `json:"protected"`
+}
+
+// githubCommit is a commit in GitHub API responses
+type githubCommit struct {
+	SHA     string `json:"sha"`
+	HTMLURL string `json:"html_url"`
+	Commit  struct {
+		Message string `json:"message"`
+		Author  struct {
+			Name string    `json:"name"`
+			Date time.Time `json:"date"`
+		} `json:"author"`
+	} `json:"commit"`
+}
+
+// toCommit converts a GitHub commit to a Commit
+func (c *githubCommit) toCommit() *Commit {
+	return &Commit{
+		SHA:        c.SHA,
+		Message:    c.Commit.Message,
+		AuthorName: c.Commit.Author.Name,
+		AuthoredAt: c.Commit.Author.Date,
+		URL:        c.HTMLURL,
+	}
+}
+
+// GitHubBrowser implements Browser over the GitHub REST API
+type GitHubBrowser struct {
+	httpClient *http.Client
+	apiURL     string
+}
+
+// NewGitHubBrowser creates a new GitHub browser whose requests time out after timeout
+func NewGitHubBrowser(timeout time.Duration) *GitHubBrowser {
+	return &GitHubBrowser{
+		httpClient: &http.Client{Timeout: timeout},
+		apiURL:     GitHubAPIURL,
+	}
+}
+
+// ListBranches lists the repository's branches
+func (b *GitHubBrowser) ListBranches(ctx context.Context, repo Repository) ([]Branch, error) {
+	var branches []githubBranch
+
+	query := url.Values{"per_page": {fmt.Sprint(maxBranches)}}
+	if err := b.get(ctx, repo, "/branches", query, &branches); err != nil {
+		return nil, err
+	}
+
+	result := make([]Branch, 0, len(branches))
+	for _, branch := range branches {
+		result = append(result, Branch{Name: branch.Name, CommitSHA: branch.Commit.SHA, Protected: branch.Protected})
+	}
+	return result, nil
+}
+
+// GetBranch gets a branch of the repository by name
+func (b *GitHubBrowser) GetBranch(ctx context.Context, repo Repository, name string) (*Branch, error) {
+	var branch githubBranch
+	if err := b.get(ctx, repo, "/branches/"+url.PathEscape(name), nil, &branch); err != nil {
+		return nil, err
+	}
+	return &Branch{Name: branch.Name, CommitSHA: branch.Commit.SHA, Protected: branch.Protected}, nil
+}
+
+// GetCommit gets the commit a ref points to
+func (b *GitHubBrowser) GetCommit(ctx context.Context, repo Repository, ref string) (*Commit, error) {
+	var commit githubCommit
+	if err := b.get(ctx, repo, "/commits/"+url.PathEscape(ref), nil, &commit); err != nil {
+		return nil, err
+	}
+	return commit.toCommit(), nil
+}
+
+// ListCommits lists up to limit commits reachable from ref, newest first
+func (b *GitHubBrowser) ListCommits(ctx context.Context, repo Repository, ref string, limit int) ([]Commit, error) {
+	var commits []githubCommit
+
+	query := url.Values{"per_page": {fmt.Sprint(limit)}}
+	if ref != "" {
+		query.Set("sha", ref)
+	}
+	if err := b.get(ctx, repo, "/commits", query, &commits); err != nil {
+		return nil, err
+	}
+
+	result := make([]Commit, 0, len(commits))
+	for _, commit := range commits {
+		result = append(result, *commit.toCommit())
+	}
+	return result, nil
+}
+
+// ListFiles lists the entries of the directory at path on ref
+func (b *GitHubBrowser) ListFiles(ctx context.Context, repo Repository, ref, path string) ([]File, error) {
+	type entry struct {
+		Name string `json:"name"`
+		Path string `json:"path"`
+		Type string `json:"type"`
+	}
+
+	query := url.Values{}
+	if ref != "" {
+		query.Set("ref", ref)
+	}
+
+	// The contents API answers with an array for directories and a single object for files
+	var raw json.RawMessage
+	if err := b.get(ctx, repo, "/contents/"+escapePath(path), query, &raw); err != nil {
+		return nil, err
+	}
+
+	var entries []entry
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		var single entry
+		if err := json.Unmarshal(raw, &single); err != nil {
+			return nil, fmt.Errorf("failed to decode git provider response: %w", err)
+		}
+		entries = []entry{single}
+	}
+
+	result := make([]File, 0, len(entries))
+	for _, entry := range entries {
+		fileType := FileTypeFile
+		if entry.Type == "dir" {
+			fileType = FileTypeDir
+		}
+		result = append(result, File{Name: entry.Name, Path: entry.Path, Type: fileType})
+	}
+	return result, nil
+}
+
+// get requests a resource of the repository
+func (b *GitHubBrowser) get(ctx context.Context, repo Repository, resource string, query url.Values, out any) error {
+	apiURL := b.apiURL
+	if repo.BaseURL != "" {
+		apiURL = GitHubEnterpriseAPIURL(repo.BaseURL)
+	}
+
+	requestURL := fmt.Sprintf("%s/repos/%s%s", apiURL, repo.Path, resource)
+	if encoded := query.Encode(); encoded != "" {
+		requestURL += "?" + encoded
+	}
+
+	headers := map[string]string{
+		"Accept":               "application/vnd.github+json",
+		"X-GitHub-Api-Version": "2022-11-28",
+	}
+	if repo.Token != "" {
+		headers["Authorization"] = "Bearer " + repo.Token
+	}
+
+	return getJSON(ctx, b.httpClient, requestURL, headers, out)
+}
+
+// GitHubEnterpriseAPIURL is the REST API of the GitHub Enterprise Server at baseURL, which serves it under /api/v3
+func GitHubEnterpriseAPIURL(baseURL string) string {
+	return strings.TrimSuffix(baseURL, "/") + "/api/v3"
+}
+
+// escapePath escapes each segment of a repository path, keeping the slashes between them
+func escapePath(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
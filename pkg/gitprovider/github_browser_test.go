@@ -0,0 +1,127 @@
+package gitprovider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestGitHubBrowser(t *testing.T, handler http.HandlerFunc) *GitHubBrowser {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	browser := NewGitHubBrowser(5 * time.Second)
+	browser.apiURL = server.URL
+	return browser
+}
+
+func TestGitHubBrowser_ListBranches(t *testing.T) {
+	browser := newTestGitHubBrowser(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/acme/payments/branches", r.URL.Path)
+		assert.Equal(t, "Bearer ghp-token", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`[{"name":"main","commit":{"sha":"abc123"},"protected":true}]`))
+	})
+
+	branches, err := browser.ListBranches(context.Background(), Repository{Path: "acme/payments", Token: "ghp-token"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []Branch{{Name: "main", CommitSHA: "abc123", Protected: true}}, branches)
+}
+
+func TestGitHubBrowser_ListCommits(t *testing.T) {
+	browser := newTestGitHubBrowser(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/acme/payments/commits", r.URL.Path)
+		assert.Equal(t, "develop", r.URL.Query().Get("sha"))
+		assert.Equal(t, "5", r.URL.Query().Get("per_page"))
+		_, _ = w.Write([]byte(`[{"sha":"abc123","html_url":"https://github.com/acme/payments/commit/abc123",
+			"commit":{"message":"Fix rounding","author":{"name":"Dev","date":"2024-01-15T10:30:00Z"}}}]`))
+	})
+
+	commits, err := browser.ListCommits(context.Background(), Repository{Path: "acme/payments"}, "develop", 5)
+
+	require.NoError(t, err)
+	require.Len(t, commits, 1)
+	assert.Equal(t, "abc123", commits[0].SHA)
+	assert.Equal(t, "Fix rounding", commits[0].Message)
+	assert.Equal(t, "Dev", commits[0].AuthorName)
+	assert.Equal(t, time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC), commits[0].AuthoredAt)
+}
+
+func TestGitHubBrowser_ListFiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		expected []File
+	}{
+		{
+			name:     "directory",
+			response: `[{"name":"main.go","path":"cmd/main.go","type":"file"},{"name":"api","path":"cmd/api","type":"dir"}]`,
+			expected: []File{{Name: "main.go", Path: "cmd/main.go", Type: FileTypeFile}, {Name: "api", Path: "cmd/api", Type: FileTypeDir}},
+		},
+		{
+			name:     "file",
+			response: `{"name":"main.go","path":"cmd/main.go","type":"file","content":"cGFja2FnZSBtYWlu"}`,
+			expected: []File{{Name: "main.go", Path: "cmd/main.go", Type: FileTypeFile}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			browser := newTestGitHubBrowser(t, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/repos/acme/payments/contents/cmd", r.URL.Path)
+				assert.Equal(t, "main", r.URL.Query().Get("ref"))
+				_, _ = w.Write([]byte(tt.response))
+			})
+
+			files, err := browser.ListFiles(context.Background(), Repository{Path: "acme/payments"}, "main", "/cmd/")
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, files)
+		})
+	}
+}
+
+func TestGitHubBrowser_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		expectedErr error
+	}{
+		{name: "not_found", status: http.StatusNotFound, expectedErr: ErrNotFound},
+		{name: "unauthorized", status: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			browser := newTestGitHubBrowser(t, func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tt.status)
+			})
+
+			_, err := browser.ListBranches(context.Background(), Repository{Path: "acme/payments"})
+
+			require.Error(t, err)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NotErrorIs(t, err, ErrNotFound)
+			}
+		})
+	}
+}
+
+func TestGitHubBrowser_GetCommit(t *testing.T) {
+	browser := newTestGitHubBrowser(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/acme/payments/commits/9fceb02", r.URL.Path)
+		_, _ = w.Write([]byte(`{"sha":"9fceb02d0ae598e95dc970b74767f19372d61af8","commit":{"message":"Fix rounding","author":{"name":"Dev","date":"2024-01-15T10:30:00Z"}}}`))
+	})
+
+	commit, err := browser.GetCommit(context.Background(), Repository{Path: "acme/payments"}, "9fceb02")
+
+	require.NoError(t, err)
+	assert.Equal(t, "9fceb02d0ae598e95dc970b74767f19372d61af8", commit.SHA)
+}
@@ -0,0 +1,228 @@
+package gitprovider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxCheckAnnotations is the number of annotations GitHub accepts per check run request
+const maxCheckAnnotations = 50
+
+// Statuses of a check run
+const (
+	CheckStatusQueued     = "queued"
+	CheckStatusInProgress = "in_progress"
+	CheckStatusCompleted  = "completed"
+)
+
+// Conclusions of a completed check run
+const (
+	CheckConclusionSuccess   = "success"
+	CheckConclusionFailure   = "failure"
+	CheckConclusionCancelled = "cancelled"
+)
+
+// Levels of a check run annotation
+const (
+	AnnotationLevelNotice  = "notice"
+	AnnotationLevelWarning = "warning"
+	AnnotationLevelFailure = "failure"
+)
+
+// CheckRun is the state of a check run on a commit
+type CheckRun struct {
+	Name        string
+	HeadSHA     string
+	ExternalID  string
+	DetailsURL  string
+	Status      string
+	Conclusion  string // Only for completed check runs
+	Title       string
+	Summary     string
+	Annotations []CheckAnnotation
+}
+
+// CheckAnnotation points a check run finding at lines of a file
+type CheckAnnotation struct {
+	Path      string
+	StartLine int
+	EndLine   int
+	Level     string
+	Title     string
+	Message   string
+}
+
+// ChecksPublisher publishes check runs on the commits of a repository as a GitHub App installation
+//
+//go:generate mockgen -destination=./mocks/mock_checks_publisher.go -mock_names=ChecksPublisher=MockChecksPublisher -package=mocks . ChecksPublisher
+type ChecksPublisher interface {
+	// CreateCheckRun creates a check run on the repository, owner/name, returning its ID
+	CreateCheckRun(ctx context.Context, app GitHubApp, repository string, run CheckRun) (int64, error)
+
+	// UpdateCheckRun updates a check run, adding its annotations to the ones it has
+	UpdateCheckRun(ctx context.Context, app GitHubApp, repository string, checkRunID int64, run CheckRun) error
+}
+
+// GitHubChecksClient implements ChecksPublisher over the GitHub REST API, authenticating with installation tokens
+type GitHubChecksClient struct {
+	httpClient *http.Client
+	apiURL     string
+	tokens     InstallationTokenSource
+}
+
+// NewGitHubChecksClient creates a new GitHub checks client calling the REST API at apiURL as the installations whose
+// tokens come from tokens. Its requests time out after timeout.
+func NewGitHubChecksClient(apiURL string, tokens InstallationTokenSource, timeout time.Duration) *GitHubChecksClient {
+	return &GitHubChecksClient{
+		httpClient: &http.Client{Timeout: timeout},
+		apiURL:     strings.TrimSuffix(apiURL, "/"),
+		tokens:     tokens,
+	}
+}
+
+// githubCheckRunRequest is the body of the check run requests
+type githubCheckRunRequest struct {
+	Name       string                `json:"name,omitempty"`
+	HeadSHA    string                `json:"head_sha,omitempty"`
+	ExternalID string                `json:"external_id,omitempty"`
+	DetailsURL string                `json:"details_url,omitempty"`
+	Status     string                `json:"status,omitempty"`
+	Conclusion string                `json:"conclusion,omitempty"`
+	Output     *githubCheckRunOutput `json:"output,omitempty"`
+}
+
+// githubCheckRunOutput is the report of a check run
+type githubCheckRunOutput struct {
+	Title       string                  `json:"title"`
+	Summary     string                  `json:"summary"`
+	Annotations []githubCheckAnnotation `json:"annotations,omitempty"`
+}
+
+// githubCheckAnnotation is an annotation in check run requests
+type githubCheckAnnotation struct {
+	Path            string `json:"path"`
+	StartLine       int    `json:"start_line"`
+	EndLine         int    `json:"end_line"`
+	AnnotationLevel string `json:"annotation_level"`
+	Title           string `json:"title,omitempty"`
+	Message         string `json:"message"`
+}
+
+// CreateCheckRun creates a check run. Annotations beyond the first batch GitHub accepts are added by updates.
+func (c *GitHubChecksClient) CreateCheckRun(ctx context.Context, app GitHubApp, repository string, run CheckRun) (int64, error) {
+	headers, err := c.installationHeaders(ctx, app)
+	if err != nil {
+		return 0, err
+	}
+
+	batches := annotationBatches(run.Annotations)
+	body := checkRunRequest(run, batches[0])
+	body.Name = run.Name
+	body.HeadSHA = run.HeadSHA
+
+	var created struct {
+		ID int64 `json:"id"`
+	}
+	if err := sendJSON(ctx, c.httpClient, http.MethodPost, fmt.Sprintf("%s/repos/%s/check-runs", c.apiURL, repository), headers, body, &created); err != nil {
+		return 0, fmt.Errorf("failed to create check run: %w", err)
+	}
+
+	if err := c.addAnnotations(ctx, headers, repository, created.ID, run, batches[1:]); err != nil {
+		return created.ID, err
+	}
+	return created.ID, nil
+}
+
+// UpdateCheckRun updates a check run. Annotations beyond the first batch GitHub accepts are added by more updates.
+func (c *GitHubChecksClient) UpdateCheckRun(ctx context.Context, app GitHubApp, repository string, checkRunID int64, run CheckRun) error {
+	headers, err := c.installationHeaders(ctx, app)
+	if err != nil {
+		return err
+	}
+
+	batches := annotationBatches(run.Annotations)
+	if err := c.patchCheckRun(ctx, headers, repository, checkRunID, checkRunRequest(run, batches[0])); err != nil {
+		return err
+	}
+	return c.addAnnotations(ctx, headers, repository, checkRunID, run, batches[1:])
+}
+
+// addAnnotations adds batches of annotations to a check run, repeating its report as GitHub requires
+func (c *GitHubChecksClient) addAnnotations(ctx context.Context, headers map[string]string, repository string, checkRunID int64, run CheckRun, batches [][]CheckAnnotation) error {
+	for _, batch := range batches {
+		body := githubCheckRunRequest{Output: checkRunRequest(run, batch).Output}
+		if err := c.patchCheckRun(ctx, headers, repository, checkRunID, body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// patchCheckRun sends an update of a check run
+func (c *GitHubChecksClient) patchCheckRun(ctx context.Context, headers map[string]string, repository string, checkRunID int64, body githubCheckRunRequest) error {
+	requestURL := fmt.Sprintf("%s/repos/%s/check-runs/%d", c.apiURL, repository, checkRunID)
+	if err := sendJSON(ctx, c.httpClient, http.MethodPatch, requestURL, headers, body, nil); err != nil {
+		return fmt.Errorf("failed to update check run %d: %w", checkRunID, err)
+	}
+	return nil
+}
+
+// installationHeaders are the headers of the requests made as the app's installation
+func (c *GitHubChecksClient) installationHeaders(ctx context.Context, app GitHubApp) (map[string]string, error) {
+	if app.APIURL == "" {
+		app.APIURL = c.apiURL
+	}
+	token, err := c.tokens.InstallationToken(ctx, app)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{
+		"Accept":               "application/vnd.github+json",
+		"X-GitHub-Api-Version": "2022-11-28",
+		"Authorization":        "Bearer " + token,
+	}, nil
+}
+
+// checkRunRequest is the update of a check run carrying a batch of its annotations
+func checkRunRequest(run CheckRun, annotations []CheckAnnotation) githubCheckRunRequest {
+	body := githubCheckRunRequest{
+		ExternalID: run.ExternalID,
+		DetailsURL: run.DetailsURL,
+		Status:     run.Status,
+		Conclusion: run.Conclusion,
+	}
+	if run.Title == "" {
+		return body
+	}
+
+	body.Output = &githubCheckRunOutput{Title: run.Title, Summary: run.Summary}
+	for _, annotation := range annotations {
+		body.Output.Annotations = append(body.Output.Annotations, githubCheckAnnotation{
+			Path:            annotation.Path,
+			StartLine:       annotation.StartLine,
+			EndLine:         annotation.EndLine,
+			AnnotationLevel: annotation.Level,
+			Title:           annotation.Title,
+			Message:         annotation.Message,
+		})
+	}
+	return body
+}
+
+// annotationBatches splits annotations into the batches GitHub accepts per request, always returning one batch
+func annotationBatches(annotations []CheckAnnotation) [][]CheckAnnotation {
+	batches := [][]CheckAnnotation{nil}
+	for start := 0; start < len(annotations); start += maxCheckAnnotations {
+		end := min(start+maxCheckAnnotations, len(annotations))
+		if start == 0 {
+			batches[0] = annotations[:end]
+			continue
+		}
+		batches = append(batches, annotations[start:end])
+	}
+	return batches
+}
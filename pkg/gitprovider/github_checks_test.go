@@ -0,0 +1,74 @@
+package gitprovider
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitHubChecksClient_CreateCheckRun(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	privateKey := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+
+	var requests []string
+	var annotationCounts []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.URL.Path {
+		case "/app/installations/42/access_tokens":
+			// The app authenticates with a JWT signed with its key
+			parts := strings.Split(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "), ".")
+			require.Len(t, parts, 3)
+			signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+			require.NoError(t, err)
+			digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+			assert.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature))
+			claims, _ := base64.RawURLEncoding.DecodeString(parts[1])
+			assert.Contains(t, string(claims), `"iss":"7"`)
+			_, _ = w.Write([]byte(`{"token":"ghs-installation"}`))
+		default:
+			assert.Equal(t, "Bearer ghs-installation", r.Header.Get("Authorization"))
+			var body githubCheckRunRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			annotationCounts = append(annotationCounts, len(body.Output.Annotations))
+			_, _ = w.Write([]byte(`{"id":99}`))
+		}
+	}))
+	defer server.Close()
+
+	annotations := make([]CheckAnnotation, 60)
+	for i := range annotations {
+		annotations[i] = CheckAnnotation{Path: "main.go", StartLine: i + 1, EndLine: i + 1, Level: AnnotationLevelWarning, Message: fmt.Sprint("finding ", i)}
+	}
+	client := NewGitHubChecksClient(server.URL+"/", NewGitHubAppTokens(server.URL, 5*time.Second), 5*time.Second)
+
+	id, err := client.CreateCheckRun(context.Background(), GitHubApp{AppID: 7, InstallationID: 42, PrivateKey: privateKey}, "acme/payments", CheckRun{
+		Name: "code-refactoring-tool", HeadSHA: "abc123", Status: CheckStatusCompleted, Conclusion: CheckConclusionFailure,
+		Title: "60 findings", Summary: "Review of pull request #12", Annotations: annotations,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(99), id)
+	assert.Equal(t, []string{
+		"POST /app/installations/42/access_tokens",
+		"POST /repos/acme/payments/check-runs",
+		"PATCH /repos/acme/payments/check-runs/99",
+	}, requests)
+	assert.Equal(t, []int{50, 10}, annotationCounts, "GitHub accepts 50 annotations per request")
+}
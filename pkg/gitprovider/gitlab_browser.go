@@ -0,0 +1,160 @@
+package gitprovider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// GitLabAPIURL is the REST API of gitlab.com
+const GitLabAPIURL = "https://gitlab.com/api/v4"
+
+// gitlabBranch is a branch in GitLab API responses
+type gitlabBranch struct {
+	Name      string `json:"name"`
+	Protected bool   `json:"protected"`
+	Commit    struct {
+		ID string `json:"id"`
+	} `json:"commit"`
+}
+
+// gitlabCommit is a commit in GitLab API responses
+type gitlabCommit struct {
+	ID           string    `json:"id"`
+	Message      string    `json:"message"`
+	AuthorName   string    `json:"author_name"`
+	AuthoredDate time.Time `json:"authored_date"`
+	WebURL       string    `json:"web_url"`
+}
+
+// toCommit converts a GitLab commit to a Commit
+func (c *gitlabCommit) toCommit() *Commit {
+	return &Commit{
+		SHA:        c.ID,
+		Message:    c.Message,
+		AuthorName: c.AuthorName,
+		AuthoredAt: c.AuthoredDate,
+		URL:        c.WebURL,
+	}
+}
+
+// GitLabBrowser implements Browser over the GitLab REST API
+type GitLabBrowser struct {
+	httpClient *http.Client
+	apiURL     string
+}
+
+// NewGitLabBrowser creates a new GitLab browser whose requests time out after timeout
+func NewGitLabBrowser(timeout time.Duration) *GitLabBrowser {
+	return &GitLabBrowser{
+		httpClient: &http.Client{Timeout: timeout},
+		apiURL:     GitLabAPIURL,
+	}
+}
+
+// ListBranches lists the repository's branches
+func (b *GitLabBrowser) ListBranches(ctx context.Context, repo Repository) ([]Branch, error) {
+	var branches []gitlabBranch
+
+	query := url.Values{"per_page": {fmt.Sprint(maxBranches)}}
+	if err := b.get(ctx, repo, "/repository/branches", query, &branches); err != nil {
+		return nil, err
+	}
+
+	result := make([]Branch, 0, len(branches))
+	for _, branch := range branches {
+		result = append(result, Branch{Name: branch.Name, CommitSHA: branch.Commit.ID, Protected: branch.Protected})
+	}
+	return result, nil
+}
+
+// GetBranch gets a branch of the repository by name
+func (b *GitLabBrowser) GetBranch(ctx context.Context, repo Repository, name string) (*Branch, error) {
+	var branch gitlabBranch
+	if err := b.get(ctx, repo, "/repository/branches/"+url.PathEscape(name), nil, &branch); err != nil {
+		return nil, err
+	}
+	return &Branch{Name: branch.Name, CommitSHA: branch.Commit.ID, Protected: branch.Protected}, nil
+}
+
+// GetCommit gets the commit a ref points to
+func (b *GitLabBrowser) GetCommit(ctx context.Context, repo Repository, ref string) (*Commit, error) {
+	var commit gitlabCommit
+	if err := b.get(ctx, repo, "/repository/commits/"+url.PathEscape(ref), nil, &commit); err != nil {
+		return nil, err
+	}
+	return commit.toCommit(), nil
+}
+
+// ListCommits lists up to limit commits reachable from ref, newest first
+func (b *GitLabBrowser) ListCommits(ctx context.Context, repo Repository, ref string, limit int) ([]Commit, error) {
+	var commits []gitlabCommit
+
+	query := url.Values{"per_page": {fmt.Sprint(limit)}}
+	if ref != "" {
+		query.Set("ref_name", ref)
+	}
+	if err := b.get(ctx, repo, "/repository/commits", query, &commits); err != nil {
+		return nil, err
+	}
+
+	result := make([]Commit, 0, len(commits))
+	for _, commit := range commits {
+		result = append(result, *commit.toCommit())
+	}
+	return result, nil
+}
+
+// ListFiles lists the entries of the directory at path on ref
+func (b *GitLabBrowser) ListFiles(ctx context.Context, repo Repository, ref, path string) ([]File, error) {
+	var entries []struct {
+		Name string `json:"name"`
+		Path string `json:"path"`
+		Type string `json:"type"`
+	}
+
+	query := url.Values{"per_page": {"100"}}
+	if ref != "" {
+		query.Set("ref", ref)
+	}
+	if path = strings.Trim(path, "/"); path != "" {
+		query.Set("path", path)
+	}
+	if err := b.get(ctx, repo, "/repository/tree", query, &entries); err != nil {
+		return nil, err
+	}
+
+	result := make([]File, 0, len(entries))
+	for _, entry := range entries {
+		fileType := FileTypeFile
+		if entry.Type == "tree" {
+			fileType = FileTypeDir
+		}
+		result = append(result, File{Name: entry.Name, Path: entry.Path, Type: fileType})
+	}
+	return result, nil
+}
+
+// get requests a resource of the repository
+func (b *GitLabBrowser) get(ctx context.Context, repo Repository, resource string, query url.Values, out any) error {
+	apiURL := b.apiURL
+	if repo.BaseURL != "" {
+		apiURL = strings.TrimSuffix(repo.BaseURL, "/") + "/api/v4"
+	}
+
+	// Projects are addressed by ID or by their URL-encoded full path
+	requestURL := fmt.Sprintf("%s/projects/%s%s", apiURL, url.PathEscape(repo.Path), resource)
+	if encoded := query.Encode(); encoded != "" {
+		requestURL += "?" + encoded
+	}
+
+	headers := map[string]string{}
+	if repo.Token != "" {
+		headers["PRIVATE-TOKEN"] = repo.Token
+	}
+
+	return getJSON(ctx, b.httpClient, requestURL, headers, out)
+}
@@ -0,0 +1,77 @@
+package gitprovider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestGitLabBrowser(t *testing.T, handler http.HandlerFunc) *GitLabBrowser {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	browser := NewGitLabBrowser(5 * time.Second)
+	browser.apiURL = server.URL
+	return browser
+}
+
+func TestGitLabBrowser_ListBranches(t *testing.T) {
+	browser := newTestGitLabBrowser(t, func(w http.ResponseWriter, r *http.Request) {
+		// Namespaced project paths are sent URL-encoded
+		assert.Equal(t, "/projects/acme%2Fpayments/repository/branches", r.URL.EscapedPath())
+		assert.Equal(t, "glpat-token", r.Header.Get("PRIVATE-TOKEN"))
+		_, _ = w.Write([]byte(`[{"name":"main","protected":true,"commit":{"id":"abc123"}}]`))
+	})
+
+	branches, err := browser.ListBranches(context.Background(), Repository{Path: "acme/payments", Token: "glpat-token"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []Branch{{Name: "main", CommitSHA: "abc123", Protected: true}}, branches)
+}
+
+func TestGitLabBrowser_ListCommits(t *testing.T) {
+	browser := newTestGitLabBrowser(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/projects/42/repository/commits", r.URL.Path)
+		assert.Equal(t, "develop", r.URL.Query().Get("ref_name"))
+		_, _ = w.Write([]byte(`[{"id":"abc123","message":"Fix rounding","author_name":"Dev",
+			"authored_date":"2024-01-15T10:30:00Z","web_url":"https://gitlab.com/acme/payments/-/commit/abc123"}]`))
+	})
+
+	commits, err := browser.ListCommits(context.Background(), Repository{Path: "42"}, "develop", 20)
+
+	require.NoError(t, err)
+	require.Len(t, commits, 1)
+	assert.Equal(t, "abc123", commits[0].SHA)
+	assert.Equal(t, "https://gitlab.com/acme/payments/-/commit/abc123", commits[0].URL)
+}
+
+func TestGitLabBrowser_ListFiles(t *testing.T) {
+	browser := newTestGitLabBrowser(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/projects/42/repository/tree", r.URL.Path)
+		assert.Equal(t, "cmd", r.URL.Query().Get("path"))
+		_, _ = w.Write([]byte(`[{"name":"api","path":"cmd/api","type":"tree"},{"name":"main.go","path":"cmd/main.go","type":"blob"}]`))
+	})
+
+	files, err := browser.ListFiles(context.Background(), Repository{Path: "42"}, "", "cmd/")
+
+	require.NoError(t, err)
+	assert.Equal(t, []File{{Name: "api", Path: "cmd/api", Type: FileTypeDir}, {Name: "main.go", Path: "cmd/main.go", Type: FileTypeFile}}, files)
+}
+
+func TestGitLabBrowser_GetBranch(t *testing.T) {
+	browser := newTestGitLabBrowser(t, func(w http.ResponseWriter, r *http.Request) {
+		// Branch names containing slashes are sent URL-encoded
+		assert.Equal(t, "/projects/42/repository/branches/feature%2Fjwt-auth", r.URL.EscapedPath())
+		_, _ = w.Write([]byte(`{"name":"feature/jwt-auth","protected":false,"commit":{"id":"abc123"}}`))
+	})
+
+	branch, err := browser.GetBranch(context.Background(), Repository{Path: "42"}, "feature/jwt-auth")
+
+	require.NoError(t, err)
+	assert.Equal(t, &Branch{Name: "feature/jwt-auth", CommitSHA: "abc123"}, branch)
+}
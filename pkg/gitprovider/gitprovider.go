@@ -0,0 +1,181 @@
+// Package gitprovider reads branches, commits and files of hosted repositories through the git providers' REST APIs.
+package gitprovider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ErrNotFound is returned when the repository, ref or path does not exist or the credentials cannot see it
+var ErrNotFound = errors.New("not found on git provider")
+
+// StatusError is returned when a git provider answers a request with an unexpected status
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+// Error implements the error interface
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("git provider returned status %d: %s", e.StatusCode, e.Body)
+}
+
+// IsTransient reports whether err is a transient failure of a git provider: a connection error, a rate limited
+// request or a 5xx response
+func IsTransient(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	var urlErr *url.Error
+	return errors.As(err, &urlErr) && !errors.Is(err, context.Canceled)
+}
+
+// Browser lists the contents of a hosted repository
+//
+//go:generate mockgen -destination=./mocks/mock_browser.go -mock_names=Browser=MockBrowser -package=mocks . Browser
+type Browser interface {
+	// ListBranches lists the repository's branches
+	ListBranches(ctx context.Context, repo Repository) ([]Branch, error)
+
+	// GetBranch gets a branch of the repository by name
+	GetBranch(ctx context.Context, repo Repository, name string) (*Branch, error)
+
+	// GetCommit gets the commit a ref points to; ref may be a branch, a tag or a full or abbreviated SHA
+	GetCommit(ctx context.Context, repo Repository, ref string) (*Commit, error)
+
+	// ListCommits lists up to limit commits reachable from ref, newest first; an empty ref uses the default branch
+	ListCommits(ctx context.Context, repo Repository, ref string, limit int) ([]Commit, error)
+
+	// ListFiles lists the entries of the directory at path on ref; an empty path lists the repository root
+	ListFiles(ctx context.Context, repo Repository, ref, path string) ([]File, error)
+}
+
+// Repository identifies a hosted repository and the credentials used to read it
+type Repository struct {
+	BaseURL string // Web URL of a self-hosted provider instance, empty for the public service
+	Path    string // owner/name on GitHub, numeric project ID or namespace/name on GitLab
+	Token   string // Access token, empty for public repositories
+}
+
+// Branch is a branch of a repository
+type Branch struct {
+	Name      string
+	CommitSHA string
+	Protected bool
+}
+
+// Commit is a commit of a repository
+type Commit struct {
+	SHA        string
+	Message    string
+	AuthorName string
+	AuthoredAt time.Time
+	URL        string
+}
+
+// FileType is the kind of a repository directory entry
+type FileType string
+
+const (
+	// FileTypeFile is a regular file
+	FileTypeFile FileType = "file"
+
+	// FileTypeDir is a directory
+	FileTypeDir FileType = "dir"
+)
+
+// File is an entry of a repository directory
+type File struct {
+	Name string
+	Path string
+	Type FileType
+}
+
+// getJSON sends an authenticated GET request and decodes the JSON response into out
+func getJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create git provider request: %w", err)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call git provider: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close git provider response body", "error", closeErr)
+		}
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &StatusError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode git provider response: %w", err)
+	}
+
+	return nil
+}
+
+// sendJSON sends an authenticated request with a JSON body and decodes the JSON response into out, unless out is nil
+func sendJSON(ctx context.Context, client *http.Client, method, url string, headers map[string]string, body, out any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode git provider request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create git provider request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call git provider: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close git provider response body", "error", closeErr)
+		}
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &StatusError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode git provider response: %w", err)
+	}
+
+	return nil
+}
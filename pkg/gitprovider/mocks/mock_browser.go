@@ -0,0 +1,111 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/pkg/gitprovider (interfaces: Browser)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	gitprovider "github.com/kazemisoroush/code-refactoring-tool/pkg/gitprovider"
+)
+
+// MockBrowser is a mock of Browser interface.
+type MockBrowser struct {
+	ctrl     *gomock.Controller
+	recorder *MockBrowserMockRecorder
+}
+
+// MockBrowserMockRecorder is the mock recorder for MockBrowser.
+type MockBrowserMockRecorder struct {
+	mock *MockBrowser
+}
+
+// NewMockBrowser creates a new mock instance.
+func NewMockBrowser(ctrl *gomock.Controller) *MockBrowser {
+	mock := &MockBrowser{ctrl: ctrl}
+	mock.recorder = &MockBrowserMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBrowser) EXPECT() *MockBrowserMockRecorder {
+	return m.recorder
+}
+
+// GetBranch mocks base method.
+func (m *MockBrowser) GetBranch(arg0 context.Context, arg1 gitprovider.Repository, arg2 string) (*gitprovider.Branch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBranch", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*gitprovider.Branch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBranch indicates an expected call of GetBranch.
+func (mr *MockBrowserMockRecorder) GetBranch(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBranch", reflect.TypeOf((*MockBrowser)(nil).GetBranch), arg0, arg1, arg2)
+}
+
+// GetCommit mocks base method.
+func (m *MockBrowser) GetCommit(arg0 context.Context, arg1 gitprovider.Repository, arg2 string) (*gitprovider.Commit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCommit", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*gitprovider.Commit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCommit indicates an expected call of GetCommit.
+func (mr *MockBrowserMockRecorder) GetCommit(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommit", reflect.TypeOf((*MockBrowser)(nil).GetCommit), arg0, arg1, arg2)
+}
+
+// ListBranches mocks base method.
+func (m *MockBrowser) ListBranches(arg0 context.Context, arg1 gitprovider.Repository) ([]gitprovider.Branch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBranches", arg0, arg1)
+	ret0, _ := ret[0].([]gitprovider.Branch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBranches indicates an expected call of ListBranches.
+func (mr *MockBrowserMockRecorder) ListBranches(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBranches", reflect.TypeOf((*MockBrowser)(nil).ListBranches), arg0, arg1)
+}
+
+// ListCommits mocks base method.
+func (m *MockBrowser) ListCommits(arg0 context.Context, arg1 gitprovider.Repository, arg2 string, arg3 int) ([]gitprovider.Commit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCommits", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]gitprovider.Commit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCommits indicates an expected call of ListCommits.
+func (mr *MockBrowserMockRecorder) ListCommits(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCommits", reflect.TypeOf((*MockBrowser)(nil).ListCommits), arg0, arg1, arg2, arg3)
+}
+
+// ListFiles mocks base method.
+func (m *MockBrowser) ListFiles(arg0 context.Context, arg1 gitprovider.Repository, arg2, arg3 string) ([]gitprovider.File, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFiles", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]gitprovider.File)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFiles indicates an expected call of ListFiles.
+func (mr *MockBrowserMockRecorder) ListFiles(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFiles", reflect.TypeOf((*MockBrowser)(nil).ListFiles), arg0, arg1, arg2, arg3)
+}
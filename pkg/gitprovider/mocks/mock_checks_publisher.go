@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/pkg/gitprovider (interfaces: ChecksPublisher)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	gitprovider "github.com/kazemisoroush/code-refactoring-tool/pkg/gitprovider"
+)
+
+// MockChecksPublisher is a mock of ChecksPublisher interface.
+type MockChecksPublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockChecksPublisherMockRecorder
+}
+
+// MockChecksPublisherMockRecorder is the mock recorder for MockChecksPublisher.
+type MockChecksPublisherMockRecorder struct {
+	mock *MockChecksPublisher
+}
+
+// NewMockChecksPublisher creates a new mock instance.
+func NewMockChecksPublisher(ctrl *gomock.Controller) *MockChecksPublisher {
+	mock := &MockChecksPublisher{ctrl: ctrl}
+	mock.recorder = &MockChecksPublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChecksPublisher) EXPECT() *MockChecksPublisherMockRecorder {
+	return m.recorder
+}
+
+// CreateCheckRun mocks base method.
+func (m *MockChecksPublisher) CreateCheckRun(arg0 context.Context, arg1 gitprovider.GitHubApp, arg2 string, arg3 gitprovider.CheckRun) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCheckRun", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCheckRun indicates an expected call of CreateCheckRun.
+func (mr *MockChecksPublisherMockRecorder) CreateCheckRun(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCheckRun", reflect.TypeOf((*MockChecksPublisher)(nil).CreateCheckRun), arg0, arg1, arg2, arg3)
+}
+
+// UpdateCheckRun mocks base method.
+func (m *MockChecksPublisher) UpdateCheckRun(arg0 context.Context, arg1 gitprovider.GitHubApp, arg2 string, arg3 int64, arg4 gitprovider.CheckRun) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCheckRun", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCheckRun indicates an expected call of UpdateCheckRun.
+func (mr *MockChecksPublisherMockRecorder) UpdateCheckRun(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCheckRun", reflect.TypeOf((*MockChecksPublisher)(nil).UpdateCheckRun), arg0, arg1, arg2, arg3, arg4)
+}
@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/pkg/gitprovider (interfaces: InstallationTokenSource)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	gitprovider "github.com/kazemisoroush/code-refactoring-tool/pkg/gitprovider"
+)
+
+// MockInstallationTokenSource is a mock of InstallationTokenSource interface.
+type MockInstallationTokenSource struct {
+	ctrl     *gomock.Controller
+	recorder *MockInstallationTokenSourceMockRecorder
+}
+
+// MockInstallationTokenSourceMockRecorder is the mock recorder for MockInstallationTokenSource.
+type MockInstallationTokenSourceMockRecorder struct {
+	mock *MockInstallationTokenSource
+}
+
+// NewMockInstallationTokenSource creates a new mock instance.
+func NewMockInstallationTokenSource(ctrl *gomock.Controller) *MockInstallationTokenSource {
+	mock := &MockInstallationTokenSource{ctrl: ctrl}
+	mock.recorder = &MockInstallationTokenSourceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInstallationTokenSource) EXPECT() *MockInstallationTokenSourceMockRecorder {
+	return m.recorder
+}
+
+// InstallationToken mocks base method.
+func (m *MockInstallationTokenSource) InstallationToken(arg0 context.Context, arg1 gitprovider.GitHubApp) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InstallationToken", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InstallationToken indicates an expected call of InstallationToken.
+func (mr *MockInstallationTokenSourceMockRecorder) InstallationToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstallationToken", reflect.TypeOf((*MockInstallationTokenSource)(nil).InstallationToken), arg0, arg1)
+}
@@ -0,0 +1,57 @@
+package gitprovider
+
+import (
+	"context"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/resilience"
+)
+
+// ResilientBrowser guards the reads of another Browser with the circuit breaker of its git provider, retrying the
+// ones that fail transiently
+type ResilientBrowser struct {
+	browser Browser
+	guard   *resilience.Guard
+}
+
+// NewResilientBrowser creates a browser guarding the reads of browser with guard
+func NewResilientBrowser(browser Browser, guard *resilience.Guard) Browser {
+	return &ResilientBrowser{
+		browser: browser,
+		guard:   guard,
+	}
+}
+
+// ListBranches lists the repository's branches
+func (b *ResilientBrowser) ListBranches(ctx context.Context, repo Repository) ([]Branch, error) {
+	return resilience.Call(ctx, b.guard, func(ctx context.Context) ([]Branch, error) {
+		return b.browser.ListBranches(ctx, repo)
+	})
+}
+
+// GetBranch gets a branch of the repository by name
+func (b *ResilientBrowser) GetBranch(ctx context.Context, repo Repository, name string) (*Branch, error) {
+	return resilience.Call(ctx, b.guard, func(ctx context.Context) (*Branch, error) {
+		return b.browser.GetBranch(ctx, repo, name)
+	})
+}
+
+// GetCommit gets the commit a ref points to
+func (b *ResilientBrowser) GetCommit(ctx context.Context, repo Repository, ref string) (*Commit, error) {
+	return resilience.Call(ctx, b.guard, func(ctx context.Context) (*Commit, error) {
+		return b.browser.GetCommit(ctx, repo, ref)
+	})
+}
+
+// ListCommits lists up to limit commits reachable from ref, newest first
+func (b *ResilientBrowser) ListCommits(ctx context.Context, repo Repository, ref string, limit int) ([]Commit, error) {
+	return resilience.Call(ctx, b.guard, func(ctx context.Context) ([]Commit, error) {
+		return b.browser.ListCommits(ctx, repo, ref, limit)
+	})
+}
+
+// ListFiles lists the entries of the directory at path on ref
+func (b *ResilientBrowser) ListFiles(ctx context.Context, repo Repository, ref, path string) ([]File, error) {
+	return resilience.Call(ctx, b.guard, func(ctx context.Context) ([]File, error) {
+		return b.browser.ListFiles(ctx, repo, ref, path)
+	})
+}
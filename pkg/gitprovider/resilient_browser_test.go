@@ -0,0 +1,55 @@
+package gitprovider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/resilience"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "server_error", err: &StatusError{StatusCode: http.StatusBadGateway}, expected: true},
+		{name: "rate_limited", err: &StatusError{StatusCode: http.StatusTooManyRequests}, expected: true},
+		{name: "connection_error", err: &url.Error{Op: "Get", URL: "https://api.github.com", Err: errors.New("connection refused")}, expected: true},
+		{name: "unauthorized", err: &StatusError{StatusCode: http.StatusUnauthorized}},
+		{name: "not_found", err: ErrNotFound},
+		{name: "cancelled", err: &url.Error{Op: "Get", URL: "https://api.github.com", Err: context.Canceled}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsTransient(tt.err))
+		})
+	}
+}
+
+func TestResilientBrowser_RetriesServerErrors(t *testing.T) {
+	calls := 0
+	github := newTestGitHubBrowser(t, func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`[{"name":"main","commit":{"sha":"abc123"}}]`))
+	})
+	guard := resilience.NewGuard("github", resilience.Policy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}, IsTransient, nil)
+	browser := NewResilientBrowser(github, guard)
+
+	branches, err := browser.ListBranches(context.Background(), Repository{Path: "acme/payments"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []Branch{{Name: "main", CommitSHA: "abc123"}}, branches)
+	assert.Equal(t, 2, calls)
+}
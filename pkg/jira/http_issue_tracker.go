@@ -0,0 +1,126 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// HTTPIssueTracker implements IssueTracker over the Jira REST API, authenticating with API tokens
+type HTTPIssueTracker struct {
+	httpClient *http.Client
+}
+
+// NewHTTPIssueTracker creates a new Jira issue tracker whose requests time out after timeout
+func NewHTTPIssueTracker(timeout time.Duration) *HTTPIssueTracker {
+	return &HTTPIssueTracker{
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// GetIssue gets an issue by key
+func (t *HTTPIssueTracker) GetIssue(ctx context.Context, site Site, key string) (*Issue, error) {
+	var issue struct {
+		Key    string `json:"key"`
+		Fields struct {
+			Summary string `json:"summary"`
+			Status  struct {
+				Name string `json:"name"`
+			} `json:"status"`
+		} `json:"fields"`
+	}
+	if err := t.send(ctx, site, http.MethodGet, issuePath(key)+"?fields=summary,status", nil, &issue); err != nil {
+		return nil, err
+	}
+
+	return &Issue{Key: issue.Key, Summary: issue.Fields.Summary, Status: issue.Fields.Status.Name}, nil
+}
+
+// AddComment adds a plain text comment to an issue. Version 2 of the API takes plain text instead of the Atlassian
+// document format.
+func (t *HTTPIssueTracker) AddComment(ctx context.Context, site Site, key, body string) error {
+	return t.send(ctx, site, http.MethodPost, issuePath(key)+"/comment", map[string]string{"body": body}, nil)
+}
+
+// TransitionIssue moves an issue through the available transition named transition, or leading to the status named
+// transition, ignoring case
+func (t *HTTPIssueTracker) TransitionIssue(ctx context.Context, site Site, key, transition string) error {
+	var transitions struct {
+		Transitions []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+			To   struct {
+				Name string `json:"name"`
+			} `json:"to"`
+		} `json:"transitions"`
+	}
+	if err := t.send(ctx, site, http.MethodGet, issuePath(key)+"/transitions", nil, &transitions); err != nil {
+		return err
+	}
+
+	for _, available := range transitions.Transitions {
+		if strings.EqualFold(available.Name, transition) || strings.EqualFold(available.To.Name, transition) {
+			body := map[string]any{"transition": map[string]string{"id": available.ID}}
+			return t.send(ctx, site, http.MethodPost, issuePath(key)+"/transitions", body, nil)
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrTransitionNotFound, transition)
+}
+
+// issuePath is the API path of an issue
+func issuePath(key string) string {
+	return "/rest/api/2/issue/" + url.PathEscape(key)
+}
+
+// send calls the API of the site, encoding body and decoding the response into out when they are set
+func (t *HTTPIssueTracker) send(ctx context.Context, site Site, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode jira request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(site.BaseURL, "/")+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create jira request: %w", err)
+	}
+	req.SetBasicAuth(site.Email, site.APIToken)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("jira request failed: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close jira response body", "error", closeErr)
+		}
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("jira returned status %d", resp.StatusCode)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode jira response: %w", err)
+	}
+	return nil
+}
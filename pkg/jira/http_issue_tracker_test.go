@@ -0,0 +1,105 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPIssueTracker_GetIssue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rest/api/2/issue/PAY-123", r.URL.Path)
+		email, token, ok := r.BasicAuth()
+		require.True(t, ok)
+		assert.Equal(t, "bot@acme.com", email)
+		assert.Equal(t, "api-token", token)
+		_, _ = w.Write([]byte(`{"key":"PAY-123","fields":{"summary":"Refactor auth","status":{"name":"In Progress"}}}`))
+	}))
+	defer server.Close()
+
+	site := Site{BaseURL: server.URL, Email: "bot@acme.com", APIToken: "api-token"}
+	issue, err := NewHTTPIssueTracker(5*time.Second).GetIssue(context.Background(), site, "PAY-123")
+
+	require.NoError(t, err)
+	assert.Equal(t, &Issue{Key: "PAY-123", Summary: "Refactor auth", Status: "In Progress"}, issue)
+}
+
+func TestHTTPIssueTracker_GetIssue_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	_, err := NewHTTPIssueTracker(5*time.Second).GetIssue(context.Background(), Site{BaseURL: server.URL}, "PAY-404")
+
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestHTTPIssueTracker_AddComment(t *testing.T) {
+	var payload map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/rest/api/2/issue/PAY-123/comment", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"10001"}`))
+	}))
+	defer server.Close()
+
+	err := NewHTTPIssueTracker(5*time.Second).AddComment(context.Background(), Site{BaseURL: server.URL}, "PAY-123", "Task started")
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"body": "Task started"}, payload)
+}
+
+func TestHTTPIssueTracker_TransitionIssue(t *testing.T) {
+	tests := []struct {
+		name        string
+		transition  string
+		expectedID  string
+		expectedErr error
+	}{
+		{name: "by transition name", transition: "resolve", expectedID: "31"},
+		{name: "by target status", transition: "Done", expectedID: "31"},
+		{name: "unavailable", transition: "Closed", expectedErr: ErrTransitionNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var transitionID string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/rest/api/2/issue/PAY-123/transitions", r.URL.Path)
+				if r.Method == http.MethodGet {
+					_, _ = w.Write([]byte(`{"transitions":[{"id":"21","name":"Start","to":{"name":"In Progress"}},
+						{"id":"31","name":"Resolve","to":{"name":"Done"}}]}`))
+					return
+				}
+				var payload struct {
+					Transition struct {
+						ID string `json:"id"`
+					} `json:"transition"`
+				}
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+				transitionID = payload.Transition.ID
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+
+			err := NewHTTPIssueTracker(5*time.Second).TransitionIssue(context.Background(), Site{BaseURL: server.URL}, "PAY-123", tt.transition)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Empty(t, transitionID)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedID, transitionID)
+		})
+	}
+}
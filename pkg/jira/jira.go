@@ -0,0 +1,42 @@
+// Package jira comments on and transitions Jira issues over the Jira REST API.
+package jira
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound is returned when the issue doesn't exist or the credentials can't see it
+var ErrNotFound = errors.New("jira issue not found")
+
+// ErrTransitionNotFound is returned when none of the issue's available transitions has the requested name
+var ErrTransitionNotFound = errors.New("jira transition not available")
+
+// Site is a Jira site and the credentials of the account calling it
+type Site struct {
+	BaseURL  string // Such as https://acme.atlassian.net
+	Email    string
+	APIToken string
+}
+
+// Issue is a Jira issue
+type Issue struct {
+	Key     string
+	Summary string
+	Status  string
+}
+
+// IssueTracker reads, comments on and transitions Jira issues
+//
+//go:generate mockgen -destination=./mocks/mock_issue_tracker.go -mock_names=IssueTracker=MockIssueTracker -package=mocks . IssueTracker
+type IssueTracker interface {
+	// GetIssue gets an issue by key
+	GetIssue(ctx context.Context, site Site, key string) (*Issue, error)
+
+	// AddComment adds a plain text comment to an issue
+	AddComment(ctx context.Context, site Site, key, body string) error
+
+	// TransitionIssue moves an issue through the available transition named transition, or leading to the status
+	// named transition
+	TransitionIssue(ctx context.Context, site Site, key, transition string) error
+}
@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/pkg/jira (interfaces: IssueTracker)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	jira "github.com/kazemisoroush/code-refactoring-tool/pkg/jira"
+)
+
+// MockIssueTracker is a mock of IssueTracker interface.
+type MockIssueTracker struct {
+	ctrl     *gomock.Controller
+	recorder *MockIssueTrackerMockRecorder
+}
+
+// MockIssueTrackerMockRecorder is the mock recorder for MockIssueTracker.
+type MockIssueTrackerMockRecorder struct {
+	mock *MockIssueTracker
+}
+
+// NewMockIssueTracker creates a new mock instance.
+func NewMockIssueTracker(ctrl *gomock.Controller) *MockIssueTracker {
+	mock := &MockIssueTracker{ctrl: ctrl}
+	mock.recorder = &MockIssueTrackerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIssueTracker) EXPECT() *MockIssueTrackerMockRecorder {
+	return m.recorder
+}
+
+// AddComment mocks base method.
+func (m *MockIssueTracker) AddComment(arg0 context.Context, arg1 jira.Site, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddComment", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddComment indicates an expected call of AddComment.
+func (mr *MockIssueTrackerMockRecorder) AddComment(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddComment", reflect.TypeOf((*MockIssueTracker)(nil).AddComment), arg0, arg1, arg2, arg3)
+}
+
+// GetIssue mocks base method.
+func (m *MockIssueTracker) GetIssue(arg0 context.Context, arg1 jira.Site, arg2 string) (*jira.Issue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIssue", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*jira.Issue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIssue indicates an expected call of GetIssue.
+func (mr *MockIssueTrackerMockRecorder) GetIssue(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIssue", reflect.TypeOf((*MockIssueTracker)(nil).GetIssue), arg0, arg1, arg2)
+}
+
+// TransitionIssue mocks base method.
+func (m *MockIssueTracker) TransitionIssue(arg0 context.Context, arg1 jira.Site, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransitionIssue", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TransitionIssue indicates an expected call of TransitionIssue.
+func (mr *MockIssueTrackerMockRecorder) TransitionIssue(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransitionIssue", reflect.TypeOf((*MockIssueTracker)(nil).TransitionIssue), arg0, arg1, arg2, arg3)
+}
@@ -0,0 +1,165 @@
+// Package jsonschema validates decoded JSON values against a subset of JSON Schema: types, object properties,
+// required properties, additional properties, array items and enums.
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Schema is a JSON schema of the supported subset
+type Schema struct {
+	Type                 string             `json:"type,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+}
+
+// ValidationError lists every violation of a schema by a value
+type ValidationError struct {
+	Violations []string
+}
+
+func (e *ValidationError) Error() string {
+	return strings.Join(e.Violations, "; ")
+}
+
+// String returns the schema as indented JSON, such as to show it to a model
+func (s *Schema) String() string {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("invalid schema: %v", err)
+	}
+	return string(data)
+}
+
+// Validate checks a value decoded from JSON against the schema, returning a *ValidationError listing every violation
+func (s *Schema) Validate(value any) error {
+	var violations []string
+	s.validate("$", value, &violations)
+	if len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+	return nil
+}
+
+// ValidateJSON decodes data and validates it, returning the decoded value
+func (s *Schema) ValidateJSON(data []byte) (any, error) {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, &ValidationError{Violations: []string{fmt.Sprintf("$: invalid JSON: %v", err)}}
+	}
+	return value, s.Validate(value)
+}
+
+func (s *Schema) validate(path string, value any, violations *[]string) {
+	if s.Type != "" && !hasType(value, s.Type) {
+		*violations = append(*violations, fmt.Sprintf("%s: expected %s, got %s", path, s.Type, typeOf(value)))
+		return
+	}
+	if len(s.Enum) > 0 && !slices.ContainsFunc(s.Enum, func(allowed any) bool { return equal(allowed, value) }) {
+		*violations = append(*violations, fmt.Sprintf("%s: must be one of %s", path, enumList(s.Enum)))
+	}
+
+	switch value := value.(type) {
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := value[name]; !ok {
+				*violations = append(*violations, fmt.Sprintf("%s: missing required property %s", path, name))
+			}
+		}
+		names := make([]string, 0, len(value))
+		for name := range value {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			property, ok := s.Properties[name]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					*violations = append(*violations, fmt.Sprintf("%s: unexpected property %s", path, name))
+				}
+				continue
+			}
+			property.validate(path+"."+name, value[name], violations)
+		}
+	case []any:
+		if s.Items != nil {
+			for i, item := range value {
+				s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, violations)
+			}
+		}
+	}
+}
+
+// hasType reports whether a decoded JSON value is of a schema type
+func hasType(value any, schemaType string) bool {
+	switch schemaType {
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		number, ok := value.(float64)
+		return ok && number == math.Trunc(number)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	default:
+		return false
+	}
+}
+
+// typeOf names the JSON type of a decoded value
+func typeOf(value any) string {
+	switch value.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// equal compares an enum value with a decoded value, whose numbers are float64
+func equal(allowed, value any) bool {
+	if number, ok := allowed.(int); ok {
+		allowed = float64(number)
+	}
+	return allowed == value
+}
+
+func enumList(values []any) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		data, _ := json.Marshal(value)
+		quoted[i] = string(data)
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,58 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/jsonschema"
+)
+
+func reviewSchema() *jsonschema.Schema {
+	closed := false
+	return &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"summary": {Type: "string"},
+			"comments": {Type: "array", Items: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"line":     {Type: "integer"},
+					"severity": {Type: "string", Enum: []any{"info", "warning", "error"}},
+				},
+				Required:             []string{"severity"},
+				AdditionalProperties: &closed,
+			}},
+		},
+		Required: []string{"summary", "comments"},
+	}
+}
+
+func TestSchema_ValidateJSON_Valid(t *testing.T) {
+	value, err := reviewSchema().ValidateJSON([]byte(`{"summary":"ok","comments":[{"line":3,"severity":"info"}],"extra":true}`))
+
+	require.NoError(t, err)
+	assert.Equal(t, "ok", value.(map[string]any)["summary"])
+}
+
+func TestSchema_ValidateJSON_ListsEveryViolation(t *testing.T) {
+	_, err := reviewSchema().ValidateJSON([]byte(`{"comments":[{"line":1.5,"severity":"fatal","file":"a.go"},"oops"]}`))
+
+	var validationErr *jsonschema.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []string{
+		"$: missing required property summary",
+		"$.comments[0]: unexpected property file",
+		"$.comments[0].line: expected integer, got number",
+		`$.comments[0].severity: must be one of "info", "warning", "error"`,
+		"$.comments[1]: expected object, got string",
+	}, validationErr.Violations)
+}
+
+func TestSchema_ValidateJSON_InvalidJSON(t *testing.T) {
+	_, err := reviewSchema().ValidateJSON([]byte(`Here is my review`))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "$: invalid JSON")
+}
@@ -0,0 +1,93 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Attribute keys shared by the log lines of one request or task, so they can be correlated
+const (
+	RequestIDKey = "request_id"
+	UserIDKey    = "user_id"
+	TaskIDKey    = "task_id"
+	RunIDKey     = "run_id"
+)
+
+type attrsContextKey struct{}
+
+// WithAttrs returns a context whose log lines carry attrs in addition to the ones ctx already carries. An attribute
+// with a key ctx already carries replaces it.
+func WithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	existing := Attrs(ctx)
+	merged := make([]slog.Attr, 0, len(existing)+len(attrs))
+	for _, attr := range existing {
+		if !hasKey(attrs, attr.Key) {
+			merged = append(merged, attr)
+		}
+	}
+	merged = append(merged, attrs...)
+	return context.WithValue(ctx, attrsContextKey{}, merged)
+}
+
+// Attrs returns the log attributes ctx carries.
+func Attrs(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	attrs, _ := ctx.Value(attrsContextKey{}).([]slog.Attr)
+	return attrs
+}
+
+// RequestID returns the ID of the request ctx belongs to, empty outside a request.
+func RequestID(ctx context.Context) string {
+	for _, attr := range Attrs(ctx) {
+		if attr.Key == RequestIDKey {
+			return attr.Value.String()
+		}
+	}
+	return ""
+}
+
+func hasKey(attrs []slog.Attr, key string) bool {
+	for _, attr := range attrs {
+		if attr.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// ContextHandler adds the attributes carried by the context of each record, so every line logged with one of the
+// slog Context functions is tagged with the request, user and task it belongs to
+type ContextHandler struct {
+	next slog.Handler
+}
+
+// NewContextHandler wraps next with the attributes of each record's context
+func NewContextHandler(next slog.Handler) *ContextHandler {
+	return &ContextHandler{next: next}
+}
+
+// Enabled reports whether next handles records at level
+func (h *ContextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle adds the context attributes to record and passes it to next
+func (h *ContextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if attrs := Attrs(ctx); len(attrs) > 0 {
+		record = record.Clone()
+		record.AddAttrs(attrs...)
+	}
+	return h.next.Handle(ctx, record)
+}
+
+// WithAttrs returns a handler whose records carry attrs
+func (h *ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &ContextHandler{next: h.next.WithAttrs(attrs)}
+}
+
+// WithGroup returns a handler that nests record attributes in group
+func (h *ContextHandler) WithGroup(name string) slog.Handler {
+	return &ContextHandler{next: h.next.WithGroup(name)}
+}
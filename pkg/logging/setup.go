@@ -30,6 +30,6 @@ func SetupLogger(level string) {
 	})
 
 	// Set as default logger
-	logger := slog.New(handler)
+	logger := slog.New(NewContextHandler(handler))
 	slog.SetDefault(logger)
 }
@@ -0,0 +1,343 @@
+// Package noise detects repository files that add noise rather than signal to knowledge bases and prompts: binaries,
+// minified bundles, lockfiles and generated code. Files are recognized by their content, with their name as a hint,
+// so renamed lockfiles and generated code without a conventional suffix are still caught.
+package noise
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Kind is a kind of noise file
+type Kind string
+
+const (
+	// KindBinary is a file that isn't text, such as an image or a compiled artifact
+	KindBinary Kind = "binary"
+
+	// KindMinified is a minified bundle or source map, whose code is packed onto a few very long lines
+	KindMinified Kind = "minified"
+
+	// KindLockfile is a dependency lockfile, such as package-lock.json or go.sum
+	KindLockfile Kind = "lockfile"
+
+	// KindGenerated is code written by a generator, such as protobuf output or mocks
+	KindGenerated Kind = "generated"
+
+	// KindExcludedPath is a file matching an exclude pattern of the policy, whatever its content
+	KindExcludedPath Kind = "excluded_path"
+)
+
+const (
+	// sniffSize is how much of a file is read for binary content and generated code markers, like git's 8000 bytes
+	sniffSize = 8000
+
+	// minMinifiedSize is the smallest file checked for minification; short files are cheap whatever their lines
+	minMinifiedSize = 2048
+
+	// minifiedLineLength is the average line length above which a file is taken for minified
+	minifiedLineLength = 300
+)
+
+// Kinds returns the kinds of noise files detected by content
+func Kinds() []Kind {
+	return []Kind{KindBinary, KindMinified, KindLockfile, KindGenerated}
+}
+
+// lockfileNames are the names of the lockfiles of common package managers
+var lockfileNames = map[string]bool{
+	"package-lock.json":   true,
+	"npm-shrinkwrap.json": true,
+	"yarn.lock":           true,
+	"pnpm-lock.yaml":      true,
+	"bun.lockb":           true,
+	"go.sum":              true,
+	"go.work.sum":         true,
+	"Cargo.lock":          true,
+	"Gemfile.lock":        true,
+	"poetry.lock":         true,
+	"Pipfile.lock":        true,
+	"uv.lock":             true,
+	"composer.lock":       true,
+	"packages.lock.json":  true,
+	"pubspec.lock":        true,
+	"Podfile.lock":        true,
+	"mix.lock":            true,
+	"flake.lock":          true,
+}
+
+// lockfileMarkers start the content of lockfiles whatever their name
+var lockfileMarkers = [][]byte{
+	[]byte("# THIS IS AN AUTOGENERATED FILE. DO NOT EDIT THIS FILE DIRECTLY."), // yarn
+	[]byte("# This file is automatically @generated by Cargo."),
+	[]byte("# This file is automatically @generated by Poetry"),
+	[]byte("lockfileVersion:"), // pnpm
+}
+
+// minifiedSuffixes name minified bundles and source maps
+var minifiedSuffixes = []string{".min.js", ".min.mjs", ".min.css", ".js.map", ".css.map"}
+
+// generatedSuffixes name the output of common code generators
+var generatedSuffixes = []string{
+	".pb.go", ".pb.gw.go", "_pb2.py", "_pb2_grpc.py", "_pb2.pyi", ".pb.cc", ".pb.h", "_pb.js", "_pb.d.ts",
+	".g.dart", ".freezed.dart", ".designer.cs", ".g.cs",
+}
+
+// generatedMarkers are the comments generators leave near the top of their output. Go's convention is a line
+// matching "^// Code generated .* DO NOT EDIT\.$", which also covers mockgen, stringer and protoc-gen-go.
+var generatedMarkers = [][]byte{
+	[]byte("DO NOT EDIT"),
+	[]byte("@generated"),
+	[]byte("<auto-generated"),
+	[]byte("Generated by the protocol buffer compiler"),
+	[]byte("This file was automatically generated"),
+	[]byte("This file is automatically generated"),
+	[]byte("Autogenerated by Thrift"),
+}
+
+// Detect returns the kind of noise a file is, given its slash-separated path and content. Content may be only the
+// beginning of the file, but minified bundles are recognized more reliably from all of it.
+func Detect(name string, content []byte) (Kind, bool) {
+	base := path.Base(name)
+	head := content[:min(sniffSize, len(content))]
+
+	if bytes.IndexByte(head, 0) >= 0 {
+		return KindBinary, true
+	}
+	if lockfileNames[base] || hasAnyPrefix(head, lockfileMarkers) || isNPMLockfile(head) {
+		return KindLockfile, true
+	}
+	if hasAnySuffix(base, generatedSuffixes) || isGenerated(head) {
+		return KindGenerated, true
+	}
+	if hasAnySuffix(base, minifiedSuffixes) || isMinified(content) {
+		return KindMinified, true
+	}
+	return "", false
+}
+
+// isNPMLockfile reports whether content is an npm lockfile, a JSON object with a lockfileVersion near its start
+func isNPMLockfile(head []byte) bool {
+	trimmed := bytes.TrimSpace(head)
+	return bytes.HasPrefix(trimmed, []byte("{")) && bytes.Contains(head[:min(512, len(head))], []byte(`"lockfileVersion"`))
+}
+
+// isGenerated reports whether a generator marker appears in the leading comments of a file. Only the first lines are
+// checked, so that code mentioning the markers, like this file, isn't mistaken for generated.
+func isGenerated(head []byte) bool {
+	lines := bytes.SplitN(head, []byte("\n"), 11)
+	for _, line := range lines[:min(10, len(lines))] {
+		if !isComment(line) {
+			continue
+		}
+		for _, marker := range generatedMarkers {
+			if bytes.Contains(line, marker) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isComment reports whether a line is a comment in one of the common comment syntaxes
+func isComment(line []byte) bool {
+	line = bytes.TrimSpace(line)
+	for _, prefix := range []string{"//", "#", "/*", "*", "<!--", "--", ";", "'"} {
+		if bytes.HasPrefix(line, []byte(prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isMinified reports whether content packs its text onto a few very long lines
+func isMinified(content []byte) bool {
+	if len(content) < minMinifiedSize {
+		return false
+	}
+	lines := bytes.Count(content, []byte("\n")) + 1
+	return len(content)/lines > minifiedLineLength
+}
+
+// hasAnyPrefix reports whether content starts with any of the prefixes
+func hasAnyPrefix(content []byte, prefixes [][]byte) bool {
+	for _, prefix := range prefixes {
+		if bytes.HasPrefix(content, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAnySuffix reports whether name ends with any of the suffixes
+func hasAnySuffix(name string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Policy selects the noise files left out
+type Policy struct {
+	Excluded []Kind   // Kinds of noise files left out
+	Include  []string // Patterns of files kept even when they look like noise
+	Exclude  []string // Patterns of files always left out
+}
+
+// DefaultPolicy leaves out every kind of noise file, and has no patterns
+func DefaultPolicy() Policy {
+	return Policy{Excluded: Kinds()}
+}
+
+// Report counts the noise files removed from a directory
+type Report struct {
+	FilesRemoved int
+	BytesRemoved int64
+	Counts       map[Kind]int // Files removed per kind
+}
+
+// Filter decides which files a policy leaves out
+type Filter struct {
+	excluded map[Kind]bool
+	include  []string
+	exclude  []string
+}
+
+// NewFilter validates a policy. Patterns match slash-separated paths relative to the repository root: a pattern
+// without a slash matches the name of a file in any directory, one ending with a slash matches the files below a
+// directory, and others match the whole path. '*' doesn't cross directories.
+func NewFilter(policy Policy) (*Filter, error) {
+	filter := &Filter{excluded: map[Kind]bool{}, include: policy.Include, exclude: policy.Exclude}
+	for _, kind := range policy.Excluded {
+		if !slices.Contains(Kinds(), kind) {
+			return nil, fmt.Errorf("unknown noise file kind %q", kind)
+		}
+		filter.excluded[kind] = true
+	}
+	for _, pattern := range slices.Concat(policy.Include, policy.Exclude) {
+		if strings.TrimSuffix(pattern, "/") == "" {
+			return nil, fmt.Errorf("empty file pattern")
+		}
+		if _, err := path.Match(strings.TrimSuffix(pattern, "/"), ""); err != nil {
+			return nil, fmt.Errorf("invalid file pattern %q: %w", pattern, err)
+		}
+	}
+	return filter, nil
+}
+
+// Enabled reports whether the filter leaves out anything
+func (f *Filter) Enabled() bool {
+	return f != nil && (len(f.excluded) > 0 || len(f.exclude) > 0)
+}
+
+// Excluded returns the kind of noise a file is, given its slash-separated path relative to the repository root, when
+// the policy leaves it out. Include patterns win over exclude patterns.
+func (f *Filter) Excluded(name string, content []byte) (Kind, bool) {
+	if !f.Enabled() || matchAny(f.include, name) {
+		return "", false
+	}
+	if matchAny(f.exclude, name) {
+		return KindExcludedPath, true
+	}
+	kind, ok := Detect(name, content)
+	if !ok || !f.excluded[kind] {
+		return "", false
+	}
+	return kind, true
+}
+
+// PruneDirectory removes the files under root the policy leaves out, skipping the .git directory
+func (f *Filter) PruneDirectory(ctx context.Context, root string) (Report, error) {
+	report := Report{Counts: make(map[Kind]int)}
+	if !f.Enabled() {
+		return report, nil
+	}
+
+	err := filepath.WalkDir(root, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if entry.IsDir() {
+			if entry.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, file)
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		kind, excluded := f.Excluded(filepath.ToSlash(rel), content)
+		if !excluded {
+			return nil
+		}
+		if err := os.Remove(file); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", file, err)
+		}
+		report.FilesRemoved++
+		report.BytesRemoved += int64(len(content))
+		report.Counts[kind]++
+		return nil
+	})
+	if err != nil {
+		return report, fmt.Errorf("failed to prune %s: %w", root, err)
+	}
+
+	return report, nil
+}
+
+// matchAny reports whether a slash-separated path matches any of the patterns
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if dir, ok := strings.CutSuffix(pattern, "/"); ok {
+			if strings.HasPrefix(name, dir+"/") || matchDir(dir, name) {
+				return true
+			}
+			continue
+		}
+		target := name
+		if !strings.Contains(pattern, "/") {
+			target = path.Base(name)
+		}
+		if matched, _ := path.Match(pattern, target); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// matchDir reports whether a directory pattern matches one of the directories of a path. Patterns without a slash
+// match a directory at any depth.
+func matchDir(pattern, name string) bool {
+	dirs := strings.Split(path.Dir(name), "/")
+	for i := range dirs {
+		candidate := strings.Join(dirs[:i+1], "/")
+		if !strings.Contains(pattern, "/") {
+			candidate = dirs[i]
+		}
+		if matched, _ := path.Match(pattern, candidate); matched {
+			return true
+		}
+	}
+	return false
+}
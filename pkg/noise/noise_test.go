@@ -0,0 +1,144 @@
+package noise_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/noise"
+)
+
+func TestDetect(t *testing.T) {
+	minified := "!function(e){" + strings.Repeat("var a=1,b=2;", 400) + "}();"
+
+	tests := []struct {
+		name    string
+		path    string
+		content string
+		kind    noise.Kind
+	}{
+		{name: "binary content", path: "assets/logo", content: "\x89PNG\r\n\x1a\n\x00\x00", kind: noise.KindBinary},
+		{name: "lockfile name", path: "web/yarn.lock", content: "lodash@^4.17.21:\n", kind: noise.KindLockfile},
+		{name: "renamed npm lockfile", path: "deps.json", content: "{\n  \"name\": \"web\",\n  \"lockfileVersion\": 3,\n", kind: noise.KindLockfile},
+		{name: "cargo lockfile header", path: "deps.txt", content: "# This file is automatically @generated by Cargo.\nversion = 3\n", kind: noise.KindLockfile},
+		{name: "protobuf suffix", path: "api/user.pb.go", content: "package api\n", kind: noise.KindGenerated},
+		{name: "go generated header", path: "mocks/store.go", content: "// Code generated by MockGen. DO NOT EDIT.\npackage mocks\n", kind: noise.KindGenerated},
+		{name: "python protobuf header", path: "user.py", content: "# -*- coding: utf-8 -*-\n# Generated by the protocol buffer compiler.  DO NOT EDIT!\n", kind: noise.KindGenerated},
+		{name: "minified name", path: "static/app.min.js", content: "var a=1;\n", kind: noise.KindMinified},
+		{name: "minified content", path: "static/app.js", content: minified, kind: noise.KindMinified},
+		{name: "source code", path: "main.go", content: "package main\n\nfunc main() {}\n", kind: ""},
+		{name: "marker outside comments", path: "lint.go", content: "package lint\n\nconst marker = \"DO NOT EDIT\"\n", kind: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, ok := noise.Detect(tt.path, []byte(tt.content))
+
+			assert.Equal(t, tt.kind, kind)
+			assert.Equal(t, tt.kind != "", ok)
+		})
+	}
+}
+
+func TestFilter_Excluded(t *testing.T) {
+	filter, err := noise.NewFilter(noise.Policy{
+		Excluded: []noise.Kind{noise.KindGenerated, noise.KindLockfile},
+		Include:  []string{"api/*.pb.go", "vendored/"},
+		Exclude:  []string{"fixtures/", "*.snap"},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		path     string
+		excluded bool
+		kind     noise.Kind
+	}{
+		{path: "internal/user.pb.go", excluded: true, kind: noise.KindGenerated},
+		{path: "api/user.pb.go", excluded: false},
+		{path: "vendored/go.sum", excluded: false},
+		{path: "go.sum", excluded: true, kind: noise.KindLockfile},
+		{path: "app.min.js", excluded: false},
+		{path: "test/fixtures/data.json", excluded: true, kind: noise.KindExcludedPath},
+		{path: "ui/__snapshots__/button.snap", excluded: true, kind: noise.KindExcludedPath},
+		{path: "main.go", excluded: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			kind, excluded := filter.Excluded(tt.path, []byte("package x\n"))
+
+			assert.Equal(t, tt.excluded, excluded)
+			assert.Equal(t, tt.kind, kind)
+		})
+	}
+}
+
+func TestNewFilter_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy noise.Policy
+		errMsg string
+	}{
+		{name: "unknown kind", policy: noise.Policy{Excluded: []noise.Kind{"images"}}, errMsg: `unknown noise file kind "images"`},
+		{name: "malformed pattern", policy: noise.Policy{Include: []string{"[a-"}}, errMsg: `invalid file pattern "[a-"`},
+		{name: "empty pattern", policy: noise.Policy{Exclude: []string{"/"}}, errMsg: "empty file pattern"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := noise.NewFilter(tt.policy)
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestFilter_PruneDirectory(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.go":              "package main\n",
+		"package-lock.json":    "{\"lockfileVersion\": 3}\n",
+		"gen/user.pb.go":       "package gen\n",
+		"assets/logo.png":      "\x89PNG\x00",
+		".git/objects/pack/1":  "\x00\x01",
+		"web/dist/app.min.css": "a{b:c}",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+
+	filter, err := noise.NewFilter(noise.DefaultPolicy())
+	require.NoError(t, err)
+
+	report, err := filter.PruneDirectory(context.Background(), dir)
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, report.FilesRemoved)
+	assert.Equal(t, map[noise.Kind]int{
+		noise.KindLockfile:  1,
+		noise.KindGenerated: 1,
+		noise.KindBinary:    1,
+		noise.KindMinified:  1,
+	}, report.Counts)
+	assert.FileExists(t, filepath.Join(dir, "main.go"))
+	assert.FileExists(t, filepath.Join(dir, ".git", "objects", "pack", "1"), "the .git directory is skipped")
+	assert.NoFileExists(t, filepath.Join(dir, "package-lock.json"))
+}
+
+func TestFilter_Disabled(t *testing.T) {
+	var filter *noise.Filter
+	_, excluded := filter.Excluded("go.sum", nil)
+	assert.False(t, excluded)
+
+	filter, err := noise.NewFilter(noise.Policy{})
+	require.NoError(t, err)
+	assert.False(t, filter.Enabled())
+}
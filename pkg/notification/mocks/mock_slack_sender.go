@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/pkg/notification (interfaces: SlackSender)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	notification "github.com/kazemisoroush/code-refactoring-tool/pkg/notification"
+)
+
+// MockSlackSender is a mock of SlackSender interface.
+type MockSlackSender struct {
+	ctrl     *gomock.Controller
+	recorder *MockSlackSenderMockRecorder
+}
+
+// MockSlackSenderMockRecorder is the mock recorder for MockSlackSender.
+type MockSlackSenderMockRecorder struct {
+	mock *MockSlackSender
+}
+
+// NewMockSlackSender creates a new mock instance.
+func NewMockSlackSender(ctrl *gomock.Controller) *MockSlackSender {
+	mock := &MockSlackSender{ctrl: ctrl}
+	mock.recorder = &MockSlackSenderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSlackSender) EXPECT() *MockSlackSenderMockRecorder {
+	return m.recorder
+}
+
+// PostMessage mocks base method.
+func (m *MockSlackSender) PostMessage(arg0 context.Context, arg1 notification.SlackDestination, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostMessage", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostMessage indicates an expected call of PostMessage.
+func (mr *MockSlackSenderMockRecorder) PostMessage(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostMessage", reflect.TypeOf((*MockSlackSender)(nil).PostMessage), arg0, arg1, arg2)
+}
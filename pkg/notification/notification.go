@@ -0,0 +1,27 @@
+// Package notification delivers notifications to users and teams over email and Slack.
+package notification
+
+import "context"
+
+// EmailSender sends plain text emails
+//
+//go:generate mockgen -destination=./mocks/mock_email_sender.go -mock_names=EmailSender=MockEmailSender -package=mocks . EmailSender
+type EmailSender interface {
+	// SendEmail sends an email with the given subject and plain text body to a single recipient
+	SendEmail(ctx context.Context, to, subject, body string) error
+}
+
+// SlackSender posts messages to Slack
+//
+//go:generate mockgen -destination=./mocks/mock_slack_sender.go -mock_names=SlackSender=MockSlackSender -package=mocks . SlackSender
+type SlackSender interface {
+	// PostMessage posts a message to the destination's incoming webhook, or to its channel using its bot token
+	PostMessage(ctx context.Context, destination SlackDestination, text string) error
+}
+
+// SlackDestination identifies where a Slack message is posted. Either WebhookURL or BotToken and Channel are set.
+type SlackDestination struct {
+	WebhookURL string
+	BotToken   string
+	Channel    string
+}
@@ -0,0 +1,57 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2/types"
+)
+
+// SESClient is the subset of the SES v2 API used to send emails
+type SESClient interface {
+	SendEmail(ctx context.Context, params *sesv2.SendEmailInput, optFns ...func(*sesv2.Options)) (*sesv2.SendEmailOutput, error)
+}
+
+// SESEmailSender implements EmailSender using Amazon SES
+type SESEmailSender struct {
+	client SESClient
+	from   string
+}
+
+// NewSESEmailSender creates a new SES email sender sending from the given verified address
+func NewSESEmailSender(awsConfig aws.Config, from string) *SESEmailSender {
+	return NewSESEmailSenderWithClient(sesv2.NewFromConfig(awsConfig), from)
+}
+
+// NewSESEmailSenderWithClient creates a new SES email sender with an existing client
+func NewSESEmailSenderWithClient(client SESClient, from string) *SESEmailSender {
+	return &SESEmailSender{
+		client: client,
+		from:   from,
+	}
+}
+
+// SendEmail sends a plain text email through SES
+func (s *SESEmailSender) SendEmail(ctx context.Context, to, subject, body string) error {
+	_, err := s.client.SendEmail(ctx, &sesv2.SendEmailInput{
+		FromEmailAddress: aws.String(s.from),
+		Destination: &types.Destination{
+			ToAddresses: []string{to},
+		},
+		Content: &types.EmailContent{
+			Simple: &types.Message{
+				Subject: &types.Content{Data: aws.String(subject)},
+				Body: &types.Body{
+					Text: &types.Content{Data: aws.String(body)},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
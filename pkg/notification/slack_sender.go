@@ -0,0 +1,102 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// SlackPostMessageURL is the Slack Web API method used to post messages with a bot token
+const SlackPostMessageURL = "https://slack.com/api/chat.postMessage"
+
+// HTTPSlackSender implements SlackSender over Slack incoming webhooks and the Web API
+type HTTPSlackSender struct {
+	httpClient     *http.Client
+	postMessageURL string
+}
+
+// NewHTTPSlackSender creates a new Slack sender
+func NewHTTPSlackSender() *HTTPSlackSender {
+	return &HTTPSlackSender{
+		httpClient:     &http.Client{Timeout: 10 * time.Second},
+		postMessageURL: SlackPostMessageURL,
+	}
+}
+
+// PostMessage posts a message through the destination's incoming webhook, or with chat.postMessage when a bot token is set
+func (s *HTTPSlackSender) PostMessage(ctx context.Context, destination SlackDestination, text string) error {
+	if destination.WebhookURL != "" {
+		// Incoming webhooks are bound to a channel and answer with a plain "ok"
+		_, err := s.post(ctx, destination.WebhookURL, "", map[string]string{"text": text})
+		return err
+	}
+
+	if destination.BotToken == "" || destination.Channel == "" {
+		return errors.New("slack destination requires a webhook URL or a bot token and channel")
+	}
+
+	body, err := s.post(ctx, s.postMessageURL, destination.BotToken, map[string]string{
+		"channel": destination.Channel,
+		"text":    text,
+	})
+	if err != nil {
+		return err
+	}
+
+	// The Web API reports failures in the body of a 200 response
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("failed to decode slack response: %w", err)
+	}
+	if !result.OK {
+		return fmt.Errorf("slack rejected message: %s", result.Error)
+	}
+
+	return nil
+}
+
+// post sends a JSON payload and returns the response body of a successful request
+func (s *HTTPSlackSender) post(ctx context.Context, url, token string, payload any) ([]byte, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode slack message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create slack request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to post slack message: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			slog.WarnContext(ctx, "failed to close slack response body", "error", closeErr)
+		}
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read slack response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("slack returned status %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+
+	return body, nil
+}
@@ -0,0 +1,73 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPSlackSender_PostMessage_Webhook(t *testing.T) {
+	var payload map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	err := NewHTTPSlackSender().PostMessage(context.Background(), SlackDestination{WebhookURL: server.URL}, "Task failed")
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"text": "Task failed"}, payload)
+}
+
+func TestHTTPSlackSender_PostMessage_BotToken(t *testing.T) {
+	tests := []struct {
+		name        string
+		response    string
+		expectedErr string
+	}{
+		{name: "ok", response: `{"ok":true}`},
+		{name: "rejected", response: `{"ok":false,"error":"channel_not_found"}`, expectedErr: "channel_not_found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload map[string]string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "Bearer xoxb-token", r.Header.Get("Authorization"))
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			sender := NewHTTPSlackSender()
+			sender.postMessageURL = server.URL
+
+			err := sender.PostMessage(context.Background(), SlackDestination{BotToken: "xoxb-token", Channel: "#alerts"}, "Task failed")
+
+			assert.Equal(t, map[string]string{"channel": "#alerts", "text": "Task failed"}, payload)
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestHTTPSlackSender_PostMessage_WebhookError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	err := NewHTTPSlackSender().PostMessage(context.Background(), SlackDestination{WebhookURL: server.URL}, "Task failed")
+
+	assert.ErrorContains(t, err, "slack returned status 403: invalid_token")
+}
@@ -0,0 +1,65 @@
+// Package objectstore stores the files uploaded through the API, such as large task inputs, as objects in S3.
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+// uriScheme is the scheme of the locations of objects
+const uriScheme = "s3://"
+
+// ErrNotFound is returned for objects that don't exist
+var ErrNotFound = errors.New("object not found")
+
+// Object describes a stored object
+type Object struct {
+	Key          string
+	URI          string // Location of the object, such as s3://bucket/key
+	ContentType  string
+	Size         int64
+	Metadata     map[string]string
+	LastModified time.Time
+}
+
+// ObjectStore writes and describes objects
+//
+//go:generate mockgen -destination=./mocks/mock_object_store.go -mock_names=ObjectStore=MockObjectStore -package=mocks . ObjectStore
+type ObjectStore interface {
+	// Put streams body to the object at key, in parts so that its size needn't be known up front, and returns the
+	// stored object. The object is only stored when body is read to its end without error.
+	Put(ctx context.Context, key, contentType string, metadata map[string]string, body io.Reader) (*Object, error)
+
+	// Head describes the object at key, or returns ErrNotFound
+	Head(ctx context.Context, key string) (*Object, error)
+
+	// Get opens the content of the object at key, or returns ErrNotFound. The caller closes it.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete deletes the object at key, succeeding when it doesn't exist
+	Delete(ctx context.Context, key string) error
+
+	// PresignPut returns a URL a client uploads the object at key to with an HTTP PUT before it expires. The upload
+	// must have the given content type and size.
+	PresignPut(ctx context.Context, key, contentType string, size int64, expires time.Duration) (string, error)
+
+	// URI returns the location of the object at key, such as s3://bucket/key
+	URI(key string) string
+}
+
+// ParseURI splits the location of an object, such as s3://bucket/key, into its bucket and key. It returns false for
+// other strings, such as the URLs of git repositories.
+func ParseURI(uri string) (string, string, bool) {
+	location, ok := strings.CutPrefix(uri, uriScheme)
+	if !ok {
+		return "", "", false
+	}
+	bucket, key, ok := strings.Cut(location, "/")
+	if !ok || bucket == "" || key == "" {
+		return "", "", false
+	}
+	return bucket, key, true
+}
@@ -0,0 +1,135 @@
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// S3ObjectStore implements ObjectStore with the objects of an S3 bucket
+type S3ObjectStore struct {
+	client     *s3.Client
+	bucketName string
+}
+
+// NewS3ObjectStore creates a new ObjectStore storing objects in the bucket
+func NewS3ObjectStore(awsConfig aws.Config, bucketName string) ObjectStore {
+	return &S3ObjectStore{
+		client:     s3.NewFromConfig(awsConfig),
+		bucketName: bucketName,
+	}
+}
+
+// Put streams body to the object at key with a multipart upload, which is aborted when reading body fails
+func (s *S3ObjectStore) Put(ctx context.Context, key, contentType string, metadata map[string]string, body io.Reader) (*Object, error) {
+	counter := &countingReader{reader: body}
+	_, err := manager.NewUploader(s.client).Upload(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucketName),
+		Key:         aws.String(key),
+		Body:        counter,
+		ContentType: aws.String(contentType),
+		Metadata:    metadata,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload %s to S3: %w", key, err)
+	}
+
+	return &Object{
+		Key:         key,
+		URI:         s.URI(key),
+		ContentType: contentType,
+		Size:        counter.read,
+		Metadata:    metadata,
+	}, nil
+}
+
+// Head describes the object at key
+func (s *S3ObjectStore) Head(ctx context.Context, key string) (*Object, error) {
+	output, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var notFound *types.NotFound
+		if errors.As(err, &notFound) {
+			return nil, fmt.Errorf("object %s: %w", key, ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to describe %s in S3: %w", key, err)
+	}
+
+	return &Object{
+		Key:          key,
+		URI:          s.URI(key),
+		ContentType:  aws.ToString(output.ContentType),
+		Size:         aws.ToInt64(output.ContentLength),
+		Metadata:     output.Metadata,
+		LastModified: aws.ToTime(output.LastModified),
+	}, nil
+}
+
+// Get opens the content of the object at key
+func (s *S3ObjectStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	output, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var noSuchKey *types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return nil, fmt.Errorf("object %s: %w", key, ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get %s from S3: %w", key, err)
+	}
+	return output.Body, nil
+}
+
+// Delete deletes the object at key. S3 deletes missing objects without error.
+func (s *S3ObjectStore) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete %s from S3: %w", key, err)
+	}
+	return nil
+}
+
+// PresignPut presigns a PutObject request whose signature covers the content type and length, so S3 rejects uploads
+// of another size
+func (s *S3ObjectStore) PresignPut(ctx context.Context, key, contentType string, size int64, expires time.Duration) (string, error) {
+	request, err := s3.NewPresignClient(s.client).PresignPutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(s.bucketName),
+		Key:           aws.String(key),
+		ContentType:   aws.String(contentType),
+		ContentLength: aws.Int64(size),
+	}, s3.WithPresignExpires(expires))
+	if err != nil {
+		return "", fmt.Errorf("failed to presign upload of %s: %w", key, err)
+	}
+	return request.URL, nil
+}
+
+// URI returns the location of the object at key
+func (s *S3ObjectStore) URI(key string) string {
+	return uriScheme + s.bucketName + "/" + key
+}
+
+// countingReader counts the bytes read from a reader
+type countingReader struct {
+	reader io.Reader
+	read   int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.read += int64(n)
+	return n, err
+}
@@ -0,0 +1,305 @@
+// Package openapi converts the Swagger 2.0 documents generated from the API's annotations to OpenAPI 3 documents,
+// requiring a security scheme on every operation that isn't public.
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Version is the OpenAPI version of the converted documents
+const Version = "3.0.3"
+
+// methods are the operations of a Swagger 2.0 path item
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch"}
+
+// parameterKeywords are the Swagger 2.0 keywords of a non-body parameter that describe its schema in OpenAPI 3
+var parameterKeywords = []string{
+	"type", "format", "items", "default", "enum", "minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum",
+	"minLength", "maxLength", "pattern", "minItems", "maxItems", "uniqueItems", "multipleOf",
+}
+
+// Options control the security of the converted operations
+type Options struct {
+	// Security is the scheme of the document's security definitions required by the operations that don't declare
+	// their own security
+	Security string
+	// Public reports whether a path is served without authentication, so that its operations require no security
+	Public func(path string) bool
+}
+
+// Convert converts a Swagger 2.0 document to an OpenAPI 3 document, returned as indented JSON. Paths are prefixed
+// with the document's base path and the host becomes the server. Parameters and schemas the conversion doesn't
+// support are an error, rather than left out of the document.
+func Convert(swagger []byte, opts Options) ([]byte, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(swagger, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode Swagger document: %w", err)
+	}
+	if version, _ := doc["swagger"].(string); version != "2.0" {
+		return nil, fmt.Errorf("unsupported Swagger version %q", version)
+	}
+
+	basePath := strings.TrimSuffix(stringOf(doc["basePath"]), "/")
+	out := map[string]any{
+		"openapi": Version,
+		"info":    doc["info"],
+		"servers": servers(doc),
+		"paths":   map[string]any{},
+	}
+	if tags, ok := doc["tags"]; ok {
+		out["tags"] = tags
+	}
+
+	components := map[string]any{}
+	if definitions, ok := doc["definitions"].(map[string]any); ok {
+		components["schemas"] = rewriteRefs(definitions)
+	}
+	if definitions, ok := doc["securityDefinitions"].(map[string]any); ok {
+		schemes, err := securitySchemes(definitions)
+		if err != nil {
+			return nil, err
+		}
+		components["securitySchemes"] = schemes
+	}
+	if opts.Security != "" {
+		schemes, _ := components["securitySchemes"].(map[string]any)
+		if _, ok := schemes[opts.Security]; !ok {
+			return nil, fmt.Errorf("security scheme %s is not defined", opts.Security)
+		}
+		out["security"] = []any{map[string]any{opts.Security: []any{}}}
+	}
+	if len(components) > 0 {
+		out["components"] = components
+	}
+
+	consumes := stringsOf(doc["consumes"])
+	produces := stringsOf(doc["produces"])
+	paths, _ := doc["paths"].(map[string]any)
+	for path, item := range paths {
+		fullPath := basePath + path
+		converted := map[string]any{}
+		for _, method := range methods {
+			operation, ok := item.(map[string]any)[method].(map[string]any)
+			if !ok {
+				continue
+			}
+			op, err := convertOperation(operation, consumes, produces)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", strings.ToUpper(method), fullPath, err)
+			}
+			if _, ok := op["security"]; !ok && opts.Public != nil && opts.Public(fullPath) {
+				op["security"] = []any{}
+			}
+			converted[method] = op
+		}
+		out["paths"].(map[string]any)[fullPath] = converted
+	}
+
+	data, err := json.MarshalIndent(out, "", "    ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode OpenAPI document: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// servers returns the server of a Swagger 2.0 document's host, whose paths include the base path
+func servers(doc map[string]any) []any {
+	host := stringOf(doc["host"])
+	if host == "" {
+		return []any{map[string]any{"url": "/"}}
+	}
+	scheme := "http"
+	if schemes := stringsOf(doc["schemes"]); len(schemes) > 0 {
+		scheme = schemes[0]
+	}
+	return []any{map[string]any{"url": scheme + "://" + host}}
+}
+
+// convertOperation converts an operation, moving its body or form parameters to the request body and the schemas of
+// its responses to their content
+func convertOperation(operation map[string]any, consumes, produces []string) (map[string]any, error) {
+	op := map[string]any{}
+	for _, key := range []string{"summary", "description", "operationId", "tags", "deprecated", "security"} {
+		if value, ok := operation[key]; ok {
+			op[key] = value
+		}
+	}
+	if list := stringsOf(operation["consumes"]); len(list) > 0 {
+		consumes = list
+	}
+	if list := stringsOf(operation["produces"]); len(list) > 0 {
+		produces = list
+	}
+	if len(consumes) == 0 {
+		consumes = []string{"application/json"}
+	}
+	if len(produces) == 0 {
+		produces = []string{"application/json"}
+	}
+
+	var parameters []any
+	form := map[string]any{}
+	var formRequired []any
+	list, _ := operation["parameters"].([]any)
+	for _, value := range list {
+		parameter, _ := value.(map[string]any)
+		name := stringOf(parameter["name"])
+		switch in := stringOf(parameter["in"]); in {
+		case "body":
+			body := map[string]any{"content": content(consumes, rewriteRefs(parameter["schema"]))}
+			if description, ok := parameter["description"]; ok {
+				body["description"] = description
+			}
+			if required, _ := parameter["required"].(bool); required {
+				body["required"] = true
+			}
+			op["requestBody"] = body
+		case "formData":
+			schema := keywordSchema(parameter).(map[string]any)
+			if schema["type"] == "file" {
+				schema["type"], schema["format"] = "string", "binary"
+			}
+			if description, ok := parameter["description"]; ok {
+				schema["description"] = description
+			}
+			form[name] = schema
+			if required, _ := parameter["required"].(bool); required {
+				formRequired = append(formRequired, name)
+			}
+		case "path", "query", "header":
+			converted := map[string]any{"name": name, "in": in}
+			if description, ok := parameter["description"]; ok {
+				converted["description"] = description
+			}
+			if required, _ := parameter["required"].(bool); required || in == "path" {
+				converted["required"] = true
+			}
+			converted["schema"] = keywordSchema(parameter)
+			parameters = append(parameters, converted)
+		default:
+			return nil, fmt.Errorf("unsupported %s parameter %s", in, name)
+		}
+	}
+	if len(parameters) > 0 {
+		op["parameters"] = parameters
+	}
+	if len(form) > 0 {
+		schema := map[string]any{"type": "object", "properties": form}
+		if len(formRequired) > 0 {
+			schema["required"] = formRequired
+		}
+		op["requestBody"] = map[string]any{"content": content(consumes, schema), "required": len(formRequired) > 0}
+	}
+
+	responses := map[string]any{}
+	declared, _ := operation["responses"].(map[string]any)
+	for status, value := range declared {
+		response, _ := value.(map[string]any)
+		converted := map[string]any{"description": stringOf(response["description"])}
+		if schema, ok := response["schema"]; ok {
+			converted["content"] = content(produces, rewriteRefs(schema))
+		}
+		if headers, ok := response["headers"].(map[string]any); ok {
+			convertedHeaders := map[string]any{}
+			for name, value := range headers {
+				header, _ := value.(map[string]any)
+				convertedHeader := map[string]any{"schema": keywordSchema(header)}
+				if description, ok := header["description"]; ok {
+					convertedHeader["description"] = description
+				}
+				convertedHeaders[name] = convertedHeader
+			}
+			converted["headers"] = convertedHeaders
+		}
+		responses[status] = converted
+	}
+	op["responses"] = responses
+	return op, nil
+}
+
+// keywordSchema returns the schema of a parameter or header, described by its keywords in Swagger 2.0
+func keywordSchema(fields map[string]any) any {
+	schema := map[string]any{}
+	for _, keyword := range parameterKeywords {
+		if value, ok := fields[keyword]; ok {
+			schema[keyword] = value
+		}
+	}
+	return rewriteRefs(schema)
+}
+
+// content returns the media types of a request or response with schema
+func content(mediaTypes []string, schema any) map[string]any {
+	content := map[string]any{}
+	for _, mediaType := range mediaTypes {
+		content[mediaType] = map[string]any{"schema": schema}
+	}
+	return content
+}
+
+// securitySchemes converts the security definitions of a Swagger 2.0 document
+func securitySchemes(definitions map[string]any) (map[string]any, error) {
+	schemes := map[string]any{}
+	for name, value := range definitions {
+		definition, _ := value.(map[string]any)
+		scheme := map[string]any{}
+		switch kind := stringOf(definition["type"]); kind {
+		case "apiKey":
+			scheme["type"] = "apiKey"
+			scheme["name"] = definition["name"]
+			scheme["in"] = definition["in"]
+		case "basic":
+			scheme["type"] = "http"
+			scheme["scheme"] = "basic"
+		default:
+			return nil, fmt.Errorf("unsupported %s security definition %s", kind, name)
+		}
+		if description, ok := definition["description"]; ok {
+			scheme["description"] = description
+		}
+		schemes[name] = scheme
+	}
+	return schemes, nil
+}
+
+// rewriteRefs returns a copy of a schema whose references to definitions refer to the component schemas instead
+func rewriteRefs(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		rewritten := make(map[string]any, len(value))
+		for key, item := range value {
+			if ref, ok := item.(string); ok && key == "$ref" {
+				rewritten[key] = strings.Replace(ref, "#/definitions/", "#/components/schemas/", 1)
+				continue
+			}
+			rewritten[key] = rewriteRefs(item)
+		}
+		return rewritten
+	case []any:
+		rewritten := make([]any, len(value))
+		for i, item := range value {
+			rewritten[i] = rewriteRefs(item)
+		}
+		return rewritten
+	default:
+		return value
+	}
+}
+
+func stringOf(value any) string {
+	s, _ := value.(string)
+	return s
+}
+
+func stringsOf(value any) []string {
+	list, _ := value.([]any)
+	strs := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			strs = append(strs, s)
+		}
+	}
+	return strs
+}
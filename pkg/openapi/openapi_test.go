@@ -0,0 +1,171 @@
+package openapi
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSwagger = `{
+	"swagger": "2.0",
+	"info": {"title": "Test API", "version": "1.0"},
+	"host": "localhost:8080",
+	"basePath": "/",
+	"paths": {
+		"/api/v1/projects/{project_id}": {
+			"put": {
+				"consumes": ["application/json"],
+				"produces": ["application/json"],
+				"parameters": [
+					{"type": "string", "description": "Project ID", "name": "project_id", "in": "path", "required": true},
+					{"type": "string", "name": "If-Match", "in": "header"},
+					{"description": "Update", "name": "request", "in": "body", "required": true, "schema": {"$ref": "#/definitions/UpdateProjectRequest"}}
+				],
+				"responses": {
+					"200": {"description": "OK", "schema": {"$ref": "#/definitions/Project"}, "headers": {"ETag": {"type": "string", "description": "Version"}}},
+					"404": {"description": "Not Found"}
+				}
+			}
+		},
+		"/health": {
+			"get": {
+				"parameters": [{"type": "integer", "default": 20, "maximum": 100, "name": "limit", "in": "query"}],
+				"responses": {"200": {"description": "OK"}}
+			}
+		}
+	},
+	"definitions": {
+		"Project": {"type": "object", "properties": {"tags": {"type": "array", "items": {"$ref": "#/definitions/Tag"}}}},
+		"Tag": {"type": "string"},
+		"UpdateProjectRequest": {"type": "object"}
+	},
+	"securityDefinitions": {"ApiKeyAuth": {"type": "apiKey", "name": "Authorization", "in": "header"}}
+}`
+
+func TestConvert(t *testing.T) {
+	data, err := Convert([]byte(testSwagger), Options{
+		Security: "ApiKeyAuth",
+		Public:   func(path string) bool { return path == "/health" },
+	})
+	require.NoError(t, err)
+
+	var document map[string]any
+	require.NoError(t, json.Unmarshal(data, &document))
+
+	var expected map[string]any
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"openapi": "3.0.3",
+		"info": {"title": "Test API", "version": "1.0"},
+		"servers": [{"url": "http://localhost:8080"}],
+		"security": [{"ApiKeyAuth": []}],
+		"paths": {
+			"/api/v1/projects/{project_id}": {
+				"put": {
+					"parameters": [
+						{"name": "project_id", "in": "path", "description": "Project ID", "required": true, "schema": {"type": "string"}},
+						{"name": "If-Match", "in": "header", "schema": {"type": "string"}}
+					],
+					"requestBody": {
+						"description": "Update",
+						"required": true,
+						"content": {"application/json": {"schema": {"$ref": "#/components/schemas/UpdateProjectRequest"}}}
+					},
+					"responses": {
+						"200": {
+							"description": "OK",
+							"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Project"}}},
+							"headers": {"ETag": {"description": "Version", "schema": {"type": "string"}}}
+						},
+						"404": {"description": "Not Found"}
+					}
+				}
+			},
+			"/health": {
+				"get": {
+					"parameters": [{"name": "limit", "in": "query", "schema": {"type": "integer", "default": 20, "maximum": 100}}],
+					"responses": {"200": {"description": "OK"}},
+					"security": []
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"Project": {"type": "object", "properties": {"tags": {"type": "array", "items": {"$ref": "#/components/schemas/Tag"}}}},
+				"Tag": {"type": "string"},
+				"UpdateProjectRequest": {"type": "object"}
+			},
+			"securitySchemes": {"ApiKeyAuth": {"type": "apiKey", "name": "Authorization", "in": "header"}}
+		}
+	}`), &expected))
+	assert.Equal(t, expected, document)
+}
+
+func TestConvert_PrefixesBasePath(t *testing.T) {
+	swagger := strings.Replace(testSwagger, `"basePath": "/"`, `"basePath": "/api/v1/"`, 1)
+
+	data, err := Convert([]byte(swagger), Options{})
+
+	require.NoError(t, err)
+	var document struct {
+		Paths map[string]any `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(data, &document))
+	assert.Contains(t, document.Paths, "/api/v1/health")
+}
+
+func TestConvert_Errors(t *testing.T) {
+	_, err := Convert([]byte(`{"swagger": "2.0"}`), Options{Security: "ApiKeyAuth"})
+	assert.ErrorContains(t, err, "security scheme ApiKeyAuth is not defined")
+
+	cookie := strings.Replace(testSwagger, `"in": "query"`, `"in": "cookie"`, 1)
+	_, err = Convert([]byte(cookie), Options{})
+	assert.ErrorContains(t, err, "GET /health: unsupported cookie parameter limit")
+
+	_, err = Convert([]byte(`{"openapi": "3.0.3"}`), Options{})
+	assert.ErrorContains(t, err, "unsupported Swagger version")
+}
+
+func TestConvert_FormData(t *testing.T) {
+	swagger := `{
+		"swagger": "2.0",
+		"info": {"title": "Test API", "version": "1.0"},
+		"paths": {
+			"/uploads": {
+				"post": {
+					"consumes": ["multipart/form-data"],
+					"parameters": [
+						{"type": "file", "description": "File to upload", "name": "file", "in": "formData", "required": true},
+						{"type": "string", "name": "note", "in": "formData"}
+					],
+					"responses": {"201": {"description": "Created"}}
+				}
+			}
+		}
+	}`
+
+	data, err := Convert([]byte(swagger), Options{})
+	require.NoError(t, err)
+
+	var document struct {
+		Paths map[string]map[string]map[string]any `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(data, &document))
+
+	var expected map[string]any
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"required": true,
+		"content": {"multipart/form-data": {"schema": {
+			"type": "object",
+			"properties": {
+				"file": {"type": "string", "format": "binary", "description": "File to upload"},
+				"note": {"type": "string"}
+			},
+			"required": ["file"]
+		}}}
+	}`), &expected))
+	assert.Equal(t, expected, document.Paths["/uploads"]["post"]["requestBody"])
+	assert.NotContains(t, document.Paths["/uploads"]["post"], "parameters")
+}
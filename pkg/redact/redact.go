@@ -0,0 +1,226 @@
+// Package redact masks personal data and credentials in repository content before it is embedded or uploaded to a
+// knowledge base, and removes the noise files its policy leaves out.
+package redact
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/noise"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/scan"
+)
+
+const (
+	// RuleEmail counts the masked email addresses
+	RuleEmail = "email"
+
+	// RuleCredential counts the masked credentials, such as access keys, tokens and private keys
+	RuleCredential = "credential"
+)
+
+// maxFileSize is the largest file redacted; larger files are generated or data files rather than code
+const maxFileSize = 1 << 20
+
+// skippedDirs are directories never redacted
+var skippedDirs = map[string]bool{".git": true, "node_modules": true, "vendor": true}
+
+// patternNamePattern restricts custom pattern names to identifiers that read well in masks and audit counts
+var patternNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// emailPattern matches email addresses
+var emailPattern = regexp.MustCompile(`\b[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}\b`)
+
+// privateKeyPattern matches whole private key blocks, where the scanner only reports their header
+var privateKeyPattern = regexp.MustCompile(`(?s)-----BEGIN [A-Z ]*PRIVATE KEY(?: BLOCK)?-----.*?-----END [A-Z ]*PRIVATE KEY(?: BLOCK)?-----`)
+
+// Pattern is a named regular expression whose matches are masked
+type Pattern struct {
+	Name       string
+	Expression string // RE2 syntax, so a pattern can't backtrack catastrophically
+}
+
+// Policy selects the content masked before embedding
+type Policy struct {
+	MaskEmails      bool
+	MaskCredentials bool
+	Patterns        []Pattern
+	Files           noise.Policy // Noise files removed before masking
+}
+
+// DefaultPolicy masks emails and credentials, has no custom patterns and removes every kind of noise file
+func DefaultPolicy() Policy {
+	return Policy{MaskEmails: true, MaskCredentials: true, Files: noise.DefaultPolicy()}
+}
+
+// Report counts the redactions made in a directory
+type Report struct {
+	FilesScanned  int
+	FilesRedacted int
+	Counts        map[string]int // Redactions per rule: RuleEmail, RuleCredential or a custom pattern name
+	Noise         noise.Report   // Noise files removed before masking
+}
+
+// Total returns the number of redactions across all rules
+func (r Report) Total() int {
+	total := 0
+	for _, count := range r.Counts {
+		total += count
+	}
+	return total
+}
+
+// rule masks the matches of its patterns, counting them under its name
+type rule struct {
+	name     string
+	patterns []*regexp.Regexp
+}
+
+// Redactor masks the content matched by a policy
+type Redactor struct {
+	rules []rule
+	files *noise.Filter
+}
+
+// NewRedactor compiles a policy. It fails when a custom pattern is unnamed, reuses a name, doesn't compile or
+// matches the empty string, or when the noise file filter is invalid.
+func NewRedactor(policy Policy) (*Redactor, error) {
+	files, err := noise.NewFilter(policy.Files)
+	if err != nil {
+		return nil, err
+	}
+	redactor := &Redactor{files: files}
+	if policy.MaskCredentials {
+		patterns := append([]*regexp.Regexp{privateKeyPattern}, scan.CredentialPatterns()...)
+		redactor.rules = append(redactor.rules, rule{name: RuleCredential, patterns: patterns})
+	}
+	if policy.MaskEmails {
+		redactor.rules = append(redactor.rules, rule{name: RuleEmail, patterns: []*regexp.Regexp{emailPattern}})
+	}
+
+	names := map[string]bool{RuleEmail: true, RuleCredential: true}
+	for _, pattern := range policy.Patterns {
+		if !patternNamePattern.MatchString(pattern.Name) {
+			return nil, fmt.Errorf("invalid redaction pattern name %q: use lowercase letters, digits, '-' and '_'", pattern.Name)
+		}
+		if names[pattern.Name] {
+			return nil, fmt.Errorf("duplicate redaction pattern name %q", pattern.Name)
+		}
+		names[pattern.Name] = true
+
+		compiled, err := regexp.Compile(pattern.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", pattern.Name, err)
+		}
+		if compiled.MatchString("") {
+			return nil, fmt.Errorf("redaction pattern %q matches empty text", pattern.Name)
+		}
+		redactor.rules = append(redactor.rules, rule{name: pattern.Name, patterns: []*regexp.Regexp{compiled}})
+	}
+
+	return redactor, nil
+}
+
+// Enabled reports whether the redactor masks anything
+func (r *Redactor) Enabled() bool {
+	return r != nil && len(r.rules) > 0
+}
+
+// Redact masks content, replacing each match with [REDACTED:<rule>], and returns the masked content with the number
+// of redactions per rule
+func (r *Redactor) Redact(content []byte) ([]byte, map[string]int) {
+	counts := make(map[string]int)
+	if !r.Enabled() {
+		return content, counts
+	}
+
+	for _, rule := range r.rules {
+		mask := []byte(fmt.Sprintf("[REDACTED:%s]", rule.name))
+		for _, pattern := range rule.patterns {
+			content = pattern.ReplaceAllFunc(content, func([]byte) []byte {
+				counts[rule.name]++
+				return mask
+			})
+		}
+	}
+	return content, counts
+}
+
+// RedactDirectory removes the noise files under root and masks the remaining text files in place. Binary files, files
+// over 1 MiB and dependency directories aren't masked.
+func (r *Redactor) RedactDirectory(ctx context.Context, root string) (Report, error) {
+	report := Report{Counts: make(map[string]int)}
+	if r == nil {
+		return report, nil
+	}
+
+	var err error
+	if report.Noise, err = r.files.PruneDirectory(ctx, root); err != nil {
+		return report, err
+	}
+	if !r.Enabled() {
+		return report, nil
+	}
+
+	err = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if entry.IsDir() {
+			if skippedDirs[entry.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if info.Size() > maxFileSize {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if isBinary(content) {
+			return nil
+		}
+		report.FilesScanned++
+
+		redacted, counts := r.Redact(content)
+		if len(counts) == 0 {
+			return nil
+		}
+		if err := os.WriteFile(path, redacted, info.Mode().Perm()); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		report.FilesRedacted++
+		for name, count := range counts {
+			report.Counts[name] += count
+		}
+		return nil
+	})
+	if err != nil {
+		return report, fmt.Errorf("failed to redact %s: %w", root, err)
+	}
+
+	return report, nil
+}
+
+// isBinary reports whether content looks binary, like git does: a NUL byte in the first 8000 bytes
+func isBinary(content []byte) bool {
+	return bytes.IndexByte(content[:min(8000, len(content))], 0) >= 0
+}
@@ -0,0 +1,23 @@
+package resilience
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+)
+
+// awsRetryables tells apart the AWS errors the SDK itself retries
+var awsRetryables = retry.IsErrorRetryables(retry.DefaultRetryables)
+
+// AWSTransient reports whether err is a transient failure of an AWS service: a connection error, a throttled
+// request or a 5xx response. Errors of requests the SDK gave up retrying are retried again after a longer backoff.
+func AWSTransient(err error) bool {
+	return awsRetryables.IsErrorRetryable(err) == aws.TrueTernary
+}
+
+// awsThrottles tells apart the AWS errors of throttled requests
+var awsThrottles = retry.IsErrorThrottles(retry.DefaultThrottles)
+
+// AWSThrottled reports whether err is an AWS service throttling a request, such as Bedrock past the quota of a model
+func AWSThrottled(err error) bool {
+	return awsThrottles.IsErrorThrottle(err) == aws.TrueTernary
+}
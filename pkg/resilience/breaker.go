@@ -0,0 +1,192 @@
+package resilience
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without calling the dependency while its circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// State is the state of a circuit breaker. Its value is the one reported in the breaker state metric.
+type State int
+
+const (
+	// StateClosed lets every call through
+	StateClosed State = iota
+
+	// StateHalfOpen lets a single trial call through to find out whether the dependency recovered
+	StateHalfOpen
+
+	// StateOpen fails every call fast until the open timeout elapses
+	StateOpen
+)
+
+// String returns the name of the state
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateHalfOpen:
+		return "half_open"
+	case StateOpen:
+		return "open"
+	default:
+		return fmt.Sprintf("State(%d)", int(s))
+	}
+}
+
+// StateObserver is notified of every state change of the circuit breaker of the named dependency
+type StateObserver func(name string, state State)
+
+// outcome is the outcome of a call let through a circuit breaker
+type outcome int
+
+const (
+	// outcomeSuccess is a call the dependency answered, successfully or with a permanent error
+	outcomeSuccess outcome = iota
+
+	// outcomeFailure is a call that failed transiently
+	outcomeFailure
+
+	// outcomeAbandoned is a call whose caller gave up, which tells nothing about the dependency
+	outcomeAbandoned
+)
+
+// CircuitBreaker stops calling a dependency after consecutive transient failures, so callers fail fast instead of
+// piling up on it while it's down. After the open timeout a single trial call is let through; it closes the breaker
+// when it succeeds and opens it again when it fails.
+type CircuitBreaker struct {
+	name        string
+	threshold   int
+	openTimeout time.Duration
+	observe     StateObserver
+	now         func() time.Time
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	trial    bool
+}
+
+// NewCircuitBreaker creates the closed circuit breaker of the named dependency, opening after threshold consecutive
+// transient failures. A threshold of 0 disables it. A nil observe isn't notified.
+func NewCircuitBreaker(name string, threshold int, openTimeout time.Duration, observe StateObserver) *CircuitBreaker {
+	return &CircuitBreaker{
+		name:        name,
+		threshold:   threshold,
+		openTimeout: openTimeout,
+		observe:     observe,
+		now:         time.Now,
+	}
+}
+
+// Name returns the name of the dependency the breaker guards
+func (b *CircuitBreaker) Name() string {
+	return b.name
+}
+
+// State returns the current state of the breaker
+func (b *CircuitBreaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// allow reports whether a call may go through, moving an open breaker whose timeout elapsed to half-open. Every
+// allowed call must be followed by a call to done.
+func (b *CircuitBreaker) allow() error {
+	if b.threshold <= 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	changed := false
+	defer func() {
+		state := b.state
+		b.mu.Unlock()
+		if changed {
+			b.notify(state)
+		}
+	}()
+
+	switch b.state {
+	case StateOpen:
+		if b.now().Sub(b.openedAt) < b.openTimeout {
+			return fmt.Errorf("%s: %w", b.name, ErrCircuitOpen)
+		}
+		b.state = StateHalfOpen
+		b.trial = true
+		changed = true
+		return nil
+	case StateHalfOpen:
+		if b.trial {
+			return fmt.Errorf("%s: %w", b.name, ErrCircuitOpen)
+		}
+		b.trial = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// done records the outcome of an allowed call
+func (b *CircuitBreaker) done(result outcome) {
+	if b.threshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	previous := b.state
+	switch {
+	case result == outcomeAbandoned:
+		if b.state == StateHalfOpen {
+			b.trial = false
+		}
+	case result == outcomeSuccess:
+		if b.state != StateOpen {
+			b.state = StateClosed
+			b.failures = 0
+			b.trial = false
+		}
+	case b.state == StateHalfOpen:
+		b.open()
+	case b.state == StateClosed:
+		b.failures++
+		if b.failures >= b.threshold {
+			b.open()
+		}
+	}
+	state := b.state
+	b.mu.Unlock()
+
+	if state != previous {
+		b.notify(state)
+	}
+}
+
+// open opens the breaker, the caller holding mu
+func (b *CircuitBreaker) open() {
+	b.state = StateOpen
+	b.openedAt = b.now()
+	b.failures = 0
+	b.trial = false
+}
+
+// notify logs a state change and passes it to the observer
+func (b *CircuitBreaker) notify(state State) {
+	if state == StateOpen {
+		slog.Warn("circuit breaker opened, failing calls fast", "dependency", b.name, "open_timeout", b.openTimeout)
+	} else {
+		slog.Info("circuit breaker changed state", "dependency", b.name, "state", state.String())
+	}
+
+	if b.observe != nil {
+		b.observe(b.name, state)
+	}
+}
@@ -0,0 +1,62 @@
+package resilience
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var states []State
+	breaker := NewCircuitBreaker("s3", 2, time.Minute, func(name string, state State) {
+		assert.Equal(t, "s3", name)
+		states = append(states, state)
+	})
+	breaker.now = func() time.Time { return now }
+
+	// A success resets the consecutive failures
+	require.NoError(t, breaker.allow())
+	breaker.done(outcomeFailure)
+	require.NoError(t, breaker.allow())
+	breaker.done(outcomeSuccess)
+	require.NoError(t, breaker.allow())
+	breaker.done(outcomeFailure)
+	assert.Equal(t, StateClosed, breaker.State())
+
+	// Consecutive failures open it, failing calls fast
+	require.NoError(t, breaker.allow())
+	breaker.done(outcomeFailure)
+	assert.Equal(t, StateOpen, breaker.State())
+	assert.ErrorIs(t, breaker.allow(), ErrCircuitOpen)
+
+	// After the timeout a single trial call is let through; an abandoned one lets another try
+	now = now.Add(time.Minute)
+	require.NoError(t, breaker.allow())
+	assert.Equal(t, StateHalfOpen, breaker.State())
+	assert.ErrorIs(t, breaker.allow(), ErrCircuitOpen)
+	breaker.done(outcomeAbandoned)
+	require.NoError(t, breaker.allow())
+
+	// A failed trial opens it again, a successful one closes it
+	breaker.done(outcomeFailure)
+	assert.Equal(t, StateOpen, breaker.State())
+	now = now.Add(time.Minute)
+	require.NoError(t, breaker.allow())
+	breaker.done(outcomeSuccess)
+	assert.Equal(t, StateClosed, breaker.State())
+
+	assert.Equal(t, []State{StateOpen, StateHalfOpen, StateOpen, StateHalfOpen, StateClosed}, states)
+}
+
+func TestCircuitBreaker_Disabled(t *testing.T) {
+	breaker := NewCircuitBreaker("s3", 0, time.Minute, nil)
+
+	for range 10 {
+		require.NoError(t, breaker.allow())
+		breaker.done(outcomeFailure)
+	}
+	assert.Equal(t, StateClosed, breaker.State())
+}
@@ -0,0 +1,262 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// ErrLimiterQueueFull is returned without calling the model when too many calls already wait for it
+var ErrLimiterQueueFull = errors.New("too many calls waiting for the model")
+
+// LimitPolicy configures the concurrency limiters of the models
+type LimitPolicy struct {
+	DefaultCeiling int            // Most concurrent calls to a model without a ceiling of its own; 0 disables limiting
+	Ceilings       map[string]int // Most concurrent calls by provider/model
+	MaxQueue       int            // Most calls waiting for a model, beyond which calls fail fast; 0 doesn't bound the queue
+	MaxRetries     int            // Retries of a throttled call
+	BaseDelay      time.Duration  // Upper bound of the jittered delay before the first retry of a throttled call
+	MaxDelay       time.Duration  // Upper bound of the jittered delay before any retry of a throttled call
+}
+
+// Throttled reports whether err is a dependency turning a call away because it's under too much load
+type Throttled func(err error) bool
+
+// WaitObserver is notified of how long every call to the named model waited for the limiter to let it through
+type WaitObserver func(name string, wait time.Duration)
+
+// Limiter bounds the concurrent calls to a model. Calls past its limit wait in turn for a call to finish. The limit
+// adapts to the model's load: it's halved whenever a call is throttled and grows back by one after a limit's worth of
+// calls succeed, up to the ceiling. Throttled calls are retried with jittered backoff.
+type Limiter struct {
+	name      string
+	ceiling   int
+	policy    LimitPolicy
+	throttled Throttled
+	observe   WaitObserver
+	sleep     func(ctx context.Context, d time.Duration) error
+
+	mu        sync.Mutex
+	limit     int
+	active    int
+	successes int
+	waiters   []chan struct{}
+}
+
+// NewLimiter creates the limiter of the named model, letting through at most ceiling calls at once. A ceiling of 0
+// disables it. A nil observe isn't notified.
+func NewLimiter(name string, ceiling int, policy LimitPolicy, throttled Throttled, observe WaitObserver) *Limiter {
+	return &Limiter{
+		name:      name,
+		ceiling:   ceiling,
+		policy:    policy,
+		throttled: throttled,
+		observe:   observe,
+		sleep:     sleep,
+		limit:     ceiling,
+	}
+}
+
+// Limit returns the number of calls the limiter currently lets through at once
+func (l *Limiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+// Do calls fn once the limiter lets it through, retrying it while it's throttled. Only idempotent calls may be
+// retried. ErrLimiterQueueFull is returned without calling fn when the queue is full.
+func (l *Limiter) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if l == nil || l.ceiling <= 0 {
+		return fn(ctx)
+	}
+
+	for attempt := 1; ; attempt++ {
+		if err := l.acquire(ctx); err != nil {
+			return err
+		}
+
+		err := fn(ctx)
+		throttled := err != nil && ctx.Err() == nil && l.throttled(err)
+		l.release(err == nil, throttled)
+		if !throttled || attempt > l.policy.MaxRetries {
+			return err
+		}
+
+		if l.sleep(ctx, l.backoff(attempt)) != nil {
+			return err
+		}
+	}
+}
+
+// DoOnce calls fn once the limiter lets it through, without retrying it, for calls that aren't idempotent. A throttled
+// call still lowers the limit.
+func (l *Limiter) DoOnce(ctx context.Context, fn func(ctx context.Context) error) error {
+	if l == nil || l.ceiling <= 0 {
+		return fn(ctx)
+	}
+
+	if err := l.acquire(ctx); err != nil {
+		return err
+	}
+	err := fn(ctx)
+	l.release(err == nil, err != nil && ctx.Err() == nil && l.throttled(err))
+	return err
+}
+
+// Limit is Limiter.Do for a call returning a value
+func Limit[T any](ctx context.Context, l *Limiter, fn func(ctx context.Context) (T, error)) (T, error) {
+	var value T
+	err := l.Do(ctx, func(ctx context.Context) error {
+		var err error
+		value, err = fn(ctx)
+		return err
+	})
+	return value, err
+}
+
+// LimitOnce is Limiter.DoOnce for a call returning a value
+func LimitOnce[T any](ctx context.Context, l *Limiter, fn func(ctx context.Context) (T, error)) (T, error) {
+	var value T
+	err := l.DoOnce(ctx, func(ctx context.Context) error {
+		var err error
+		value, err = fn(ctx)
+		return err
+	})
+	return value, err
+}
+
+// acquire waits for the limiter to let a call through, in the order calls arrived
+func (l *Limiter) acquire(ctx context.Context) error {
+	start := time.Now()
+	l.mu.Lock()
+	if l.active < l.limit && len(l.waiters) == 0 {
+		l.active++
+		l.mu.Unlock()
+		l.observeWait(start)
+		return nil
+	}
+	if l.policy.MaxQueue > 0 && len(l.waiters) >= l.policy.MaxQueue {
+		l.mu.Unlock()
+		return ErrLimiterQueueFull
+	}
+
+	turn := make(chan struct{})
+	l.waiters = append(l.waiters, turn)
+	l.mu.Unlock()
+
+	select {
+	case <-turn:
+		l.observeWait(start)
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		for i, waiter := range l.waiters {
+			if waiter == turn {
+				l.waiters = append(l.waiters[:i], l.waiters[i+1:]...)
+				return ctx.Err()
+			}
+		}
+		// The call was let through as its context was done, so its turn goes to the next one
+		l.active--
+		l.admit()
+		return ctx.Err()
+	}
+}
+
+// release ends a call, adapting the limit to whether it succeeded or was throttled, and lets waiting calls through
+func (l *Limiter) release(succeeded, throttled bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.active--
+	switch {
+	case throttled:
+		if limit := max(l.limit/2, 1); limit != l.limit {
+			slog.Warn("model throttled, lowering its concurrency limit", "model", l.name, "limit", limit)
+			l.limit = limit
+		}
+		l.successes = 0
+	case succeeded && l.limit < l.ceiling:
+		l.successes++
+		if l.successes >= l.limit {
+			l.limit++
+			l.successes = 0
+		}
+	}
+	l.admit()
+}
+
+// admit lets waiting calls through while the limit allows, with the mutex held
+func (l *Limiter) admit() {
+	for l.active < l.limit && len(l.waiters) > 0 {
+		l.active++
+		close(l.waiters[0])
+		l.waiters = l.waiters[1:]
+	}
+}
+
+// observeWait reports how long a call waited since start
+func (l *Limiter) observeWait(start time.Time) {
+	if l.observe != nil {
+		l.observe(l.name, time.Since(start))
+	}
+}
+
+// backoff returns the delay before retrying a call throttled attempt times, drawn uniformly up to an exponentially
+// growing bound
+func (l *Limiter) backoff(attempt int) time.Duration {
+	bound := l.policy.BaseDelay
+	for i := 1; i < attempt && bound < l.policy.MaxDelay; i++ {
+		bound *= 2
+	}
+	bound = min(bound, l.policy.MaxDelay)
+	if bound <= 0 {
+		return 0
+	}
+	return rand.N(bound)
+}
+
+// Limiters hands out the limiter of each model, sharing a policy and a wait observer. Limiters are created on first
+// use and cached by provider and model, so every client of a model shares its limit.
+type Limiters struct {
+	policy  LimitPolicy
+	observe WaitObserver
+
+	mu       sync.Mutex
+	limiters map[string]*Limiter
+}
+
+// NewLimiters creates the limiters of the models following policy, whose wait times are passed to observe unless it's
+// nil
+func NewLimiters(policy LimitPolicy, observe WaitObserver) *Limiters {
+	return &Limiters{
+		policy:   policy,
+		observe:  observe,
+		limiters: make(map[string]*Limiter),
+	}
+}
+
+// Limiter returns the limiter of the provider's model, created with throttled on first use. The ceiling of
+// provider/model applies, or else the default ceiling.
+func (r *Limiters) Limiter(provider, model string, throttled Throttled) *Limiter {
+	name := provider + "/" + model
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	limiter, ok := r.limiters[name]
+	if !ok {
+		ceiling, ok := r.policy.Ceilings[name]
+		if !ok {
+			ceiling = r.policy.DefaultCeiling
+		}
+		limiter = NewLimiter(name, ceiling, r.policy, throttled, r.observe)
+		r.limiters[name] = limiter
+	}
+	return limiter
+}
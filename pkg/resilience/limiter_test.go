@@ -0,0 +1,205 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errThrottled = errors.New("throttled")
+
+func isThrottled(err error) bool {
+	return errors.Is(err, errThrottled)
+}
+
+// newTestLimiter creates a limiter that doesn't wait between retries
+func newTestLimiter(ceiling int, policy LimitPolicy, observe WaitObserver) *Limiter {
+	limiter := NewLimiter("bedrock/claude", ceiling, policy, isThrottled, observe)
+	limiter.sleep = func(context.Context, time.Duration) error { return nil }
+	return limiter
+}
+
+// holdCalls starts n calls through limiter that block until release is closed, returning once the limiter let
+// through as many as it will
+func holdCalls(t *testing.T, limiter *Limiter, n int, release <-chan struct{}) (*sync.WaitGroup, *atomic.Int32) {
+	t.Helper()
+	var wg sync.WaitGroup
+	var active, peak atomic.Int32
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = limiter.Do(context.Background(), func(context.Context) error {
+				current := active.Add(1)
+				for {
+					previous := peak.Load()
+					if current <= previous || peak.CompareAndSwap(previous, current) {
+						break
+					}
+				}
+				<-release
+				active.Add(-1)
+				return nil
+			})
+		}()
+	}
+	require.Eventually(t, func() bool { return active.Load() == int32(min(n, limiter.Limit())) }, time.Second, time.Millisecond)
+	return &wg, &peak
+}
+
+func TestLimiter_BoundsConcurrentCalls(t *testing.T) {
+	var waits atomic.Int32
+	limiter := newTestLimiter(2, LimitPolicy{}, func(name string, _ time.Duration) {
+		assert.Equal(t, "bedrock/claude", name)
+		waits.Add(1)
+	})
+
+	release := make(chan struct{})
+	wg, peak := holdCalls(t, limiter, 5, release)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(2), peak.Load())
+	assert.Equal(t, int32(5), waits.Load(), "every call's wait is observed")
+}
+
+func TestLimiter_ThrottlingLowersLimitAndRetries(t *testing.T) {
+	limiter := newTestLimiter(4, LimitPolicy{MaxRetries: 3}, nil)
+
+	calls := 0
+	err := limiter.Do(context.Background(), func(context.Context) error {
+		calls++
+		if calls == 1 {
+			return errThrottled
+		}
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, 2, limiter.Limit())
+
+	// The limit grows back by one after a limit's worth of successful calls, the retried call included
+	succeed := func(context.Context) error { return nil }
+	require.NoError(t, limiter.Do(context.Background(), succeed))
+	assert.Equal(t, 3, limiter.Limit())
+	for range 3 {
+		require.NoError(t, limiter.Do(context.Background(), succeed))
+	}
+	assert.Equal(t, 4, limiter.Limit(), "up to the ceiling")
+}
+
+func TestLimiter_RetriesExhausted(t *testing.T) {
+	limiter := newTestLimiter(4, LimitPolicy{MaxRetries: 2}, nil)
+
+	calls := 0
+	err := limiter.Do(context.Background(), func(context.Context) error {
+		calls++
+		return errThrottled
+	})
+
+	assert.ErrorIs(t, err, errThrottled)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, 1, limiter.Limit())
+}
+
+func TestLimiter_OtherErrorsNotRetried(t *testing.T) {
+	limiter := newTestLimiter(4, LimitPolicy{MaxRetries: 2}, nil)
+	errPermanent := errors.New("invalid model")
+
+	calls := 0
+	err := limiter.Do(context.Background(), func(context.Context) error {
+		calls++
+		return errPermanent
+	})
+
+	assert.ErrorIs(t, err, errPermanent)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 4, limiter.Limit())
+}
+
+func TestLimiter_QueueFull(t *testing.T) {
+	limiter := newTestLimiter(1, LimitPolicy{MaxQueue: 1}, nil)
+
+	release := make(chan struct{})
+	wg, _ := holdCalls(t, limiter, 2, release)
+	require.Eventually(t, func() bool {
+		limiter.mu.Lock()
+		defer limiter.mu.Unlock()
+		return len(limiter.waiters) == 1
+	}, time.Second, time.Millisecond)
+
+	err := limiter.Do(context.Background(), func(context.Context) error {
+		t.Fatal("call past a full queue must not be made")
+		return nil
+	})
+
+	assert.ErrorIs(t, err, ErrLimiterQueueFull)
+	close(release)
+	wg.Wait()
+}
+
+func TestLimiter_CancelledCallLeavesQueue(t *testing.T) {
+	limiter := newTestLimiter(1, LimitPolicy{}, nil)
+
+	release := make(chan struct{})
+	wg, _ := holdCalls(t, limiter, 1, release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := limiter.Do(ctx, func(context.Context) error { return nil })
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(release)
+	wg.Wait()
+
+	// The cancelled call's turn isn't kept
+	called := false
+	require.NoError(t, limiter.Do(context.Background(), func(context.Context) error {
+		called = true
+		return nil
+	}))
+	assert.True(t, called)
+}
+
+func TestLimiters_Limiter(t *testing.T) {
+	limiters := NewLimiters(LimitPolicy{DefaultCeiling: 8, Ceilings: map[string]int{"ollama/llama3": 2}}, nil)
+
+	llama := limiters.Limiter("ollama", "llama3", isThrottled)
+	assert.Same(t, llama, limiters.Limiter("ollama", "llama3", isThrottled), "clients of a model share its limiter")
+	assert.Equal(t, 2, llama.Limit())
+	assert.Equal(t, 8, limiters.Limiter("bedrock", "claude", isThrottled).Limit())
+}
+
+func TestLimiter_ZeroCeilingDisablesLimiting(t *testing.T) {
+	limiter := newTestLimiter(0, LimitPolicy{MaxRetries: 3}, nil)
+
+	calls := 0
+	err := limiter.Do(context.Background(), func(context.Context) error {
+		calls++
+		return errThrottled
+	})
+
+	assert.ErrorIs(t, err, errThrottled)
+	assert.Equal(t, 1, calls)
+}
+
+func TestLimiter_DoOnceDoesNotRetry(t *testing.T) {
+	limiter := newTestLimiter(4, LimitPolicy{MaxRetries: 3}, nil)
+
+	calls := 0
+	err := limiter.DoOnce(context.Background(), func(context.Context) error {
+		calls++
+		return errThrottled
+	})
+
+	assert.ErrorIs(t, err, errThrottled)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 2, limiter.Limit(), "a throttled call still lowers the limit")
+}
@@ -0,0 +1,175 @@
+// Package resilience guards the calls to external dependencies with retries and circuit breakers, so transient
+// failures are retried with jittered backoff and a dependency that is down is failed fast instead of called.
+package resilience
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// Policy configures the retries and the circuit breaker guarding the calls to a dependency
+type Policy struct {
+	MaxAttempts      int           // Attempts of a retried call, including the first one; 1 disables retries
+	BaseDelay        time.Duration // Upper bound of the jittered delay before the first retry, doubled for each later one
+	MaxDelay         time.Duration // Upper bound of the jittered delay before any retry
+	FailureThreshold int           // Consecutive transient failures opening the circuit breaker; 0 disables it
+	OpenTimeout      time.Duration // How long an open circuit breaker fails calls fast before letting a trial call through
+}
+
+// Transient reports whether err is a transient failure of a dependency, worth retrying and counted by its circuit
+// breaker. Other errors are answers of the dependency and are returned as they are.
+type Transient func(err error) bool
+
+// Guard guards the calls to a dependency with its circuit breaker, retrying the calls that fail transiently
+type Guard struct {
+	breaker   *CircuitBreaker
+	policy    Policy
+	transient Transient
+	sleep     func(ctx context.Context, d time.Duration) error
+}
+
+// NewGuard creates the guard of the named dependency, whose transient failures are told apart by transient
+func NewGuard(name string, policy Policy, transient Transient, observe StateObserver) *Guard {
+	return &Guard{
+		breaker:   NewCircuitBreaker(name, policy.FailureThreshold, policy.OpenTimeout, observe),
+		policy:    policy,
+		transient: transient,
+		sleep:     sleep,
+	}
+}
+
+// Breaker returns the circuit breaker of the guard
+func (g *Guard) Breaker() *CircuitBreaker {
+	return g.breaker
+}
+
+// Do calls fn, retrying it with jittered exponential backoff while it fails transiently. Only idempotent calls may
+// be retried. ErrCircuitOpen is returned without calling fn while the circuit breaker is open.
+func (g *Guard) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	for attempt := 1; ; attempt++ {
+		err := g.call(ctx, fn)
+		if err == nil || attempt >= g.policy.MaxAttempts || !g.retryable(ctx, err) {
+			return err
+		}
+
+		if g.sleep(ctx, g.backoff(attempt)) != nil {
+			return err
+		}
+	}
+}
+
+// DoOnce calls fn without retrying it, for calls that aren't idempotent. ErrCircuitOpen is returned without calling
+// fn while the circuit breaker is open.
+func (g *Guard) DoOnce(ctx context.Context, fn func(ctx context.Context) error) error {
+	return g.call(ctx, fn)
+}
+
+// Call is Do for a call returning a value
+func Call[T any](ctx context.Context, g *Guard, fn func(ctx context.Context) (T, error)) (T, error) {
+	var value T
+	err := g.Do(ctx, func(ctx context.Context) error {
+		var err error
+		value, err = fn(ctx)
+		return err
+	})
+	return value, err
+}
+
+// CallOnce is DoOnce for a call returning a value
+func CallOnce[T any](ctx context.Context, g *Guard, fn func(ctx context.Context) (T, error)) (T, error) {
+	var value T
+	err := g.DoOnce(ctx, func(ctx context.Context) error {
+		var err error
+		value, err = fn(ctx)
+		return err
+	})
+	return value, err
+}
+
+// call makes a single call through the circuit breaker and records its outcome
+func (g *Guard) call(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := g.breaker.allow(); err != nil {
+		return err
+	}
+
+	err := fn(ctx)
+	switch {
+	case err == nil:
+		g.breaker.done(outcomeSuccess)
+	case ctx.Err() != nil:
+		g.breaker.done(outcomeAbandoned)
+	case g.transient(err):
+		g.breaker.done(outcomeFailure)
+	default:
+		g.breaker.done(outcomeSuccess)
+	}
+	return err
+}
+
+// retryable reports whether a call failing with err is worth retrying
+func (g *Guard) retryable(ctx context.Context, err error) bool {
+	return ctx.Err() == nil && !errors.Is(err, ErrCircuitOpen) && g.transient(err)
+}
+
+// backoff returns the delay before retrying a call failed attempt times, drawn uniformly up to an exponentially
+// growing bound so callers failing together don't retry together
+func (g *Guard) backoff(attempt int) time.Duration {
+	bound := g.policy.BaseDelay
+	for i := 1; i < attempt && bound < g.policy.MaxDelay; i++ {
+		bound *= 2
+	}
+	bound = min(bound, g.policy.MaxDelay)
+	if bound <= 0 {
+		return 0
+	}
+	return rand.N(bound)
+}
+
+// sleep waits for d, or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Registry hands out the guard of each dependency, sharing a policy and a state observer. Guards are created on
+// first use and cached by name, so every client of a dependency shares its circuit breaker.
+type Registry struct {
+	policy  Policy
+	observe StateObserver
+
+	mu     sync.Mutex
+	guards map[string]*Guard
+}
+
+// NewRegistry creates a registry of guards following policy, whose circuit breaker state changes are passed to
+// observe unless it's nil
+func NewRegistry(policy Policy, observe StateObserver) *Registry {
+	return &Registry{
+		policy:  policy,
+		observe: observe,
+		guards:  make(map[string]*Guard),
+	}
+}
+
+// Guard returns the guard of the named dependency, created with transient on first use
+func (r *Registry) Guard(name string, transient Transient) *Guard {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	guard, ok := r.guards[name]
+	if !ok {
+		guard = NewGuard(name, r.policy, transient, r.observe)
+		r.guards[name] = guard
+	}
+	return guard
+}
@@ -0,0 +1,153 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errTransient = errors.New("transient")
+
+func isTransient(err error) bool {
+	return errors.Is(err, errTransient)
+}
+
+// newTestGuard creates a guard that doesn't wait between retries
+func newTestGuard(policy Policy) *Guard {
+	guard := NewGuard("cognito", policy, isTransient, nil)
+	guard.sleep = func(context.Context, time.Duration) error { return nil }
+	return guard
+}
+
+func TestGuard_Do(t *testing.T) {
+	errPermanent := errors.New("permanent")
+
+	tests := []struct {
+		name          string
+		errs          []error
+		expectedCalls int
+		expectedErr   error
+	}{
+		{name: "success", errs: []error{nil}, expectedCalls: 1},
+		{name: "transient_then_success", errs: []error{errTransient, errTransient, nil}, expectedCalls: 3},
+		{name: "attempts_exhausted", errs: []error{errTransient, errTransient, errTransient}, expectedCalls: 3, expectedErr: errTransient},
+		{name: "permanent_not_retried", errs: []error{errPermanent}, expectedCalls: 1, expectedErr: errPermanent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			guard := newTestGuard(Policy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Second})
+
+			calls := 0
+			err := guard.Do(context.Background(), func(context.Context) error {
+				calls++
+				return tt.errs[calls-1]
+			})
+
+			assert.Equal(t, tt.expectedCalls, calls)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGuard_OpenBreakerFailsFast(t *testing.T) {
+	guard := newTestGuard(Policy{MaxAttempts: 3, FailureThreshold: 2, OpenTimeout: time.Minute})
+
+	calls := 0
+	fail := func(context.Context) error {
+		calls++
+		return errTransient
+	}
+
+	// The retries of the first call open the breaker, which stops them
+	err := guard.Do(context.Background(), fail)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, StateOpen, guard.Breaker().State())
+
+	err = guard.DoOnce(context.Background(), fail)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, 2, calls)
+}
+
+func TestGuard_CancelledCallNotCounted(t *testing.T) {
+	guard := newTestGuard(Policy{MaxAttempts: 3, FailureThreshold: 1, OpenTimeout: time.Minute})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := guard.Do(ctx, func(context.Context) error {
+		calls++
+		cancel()
+		return errTransient
+	})
+
+	assert.ErrorIs(t, err, errTransient)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, StateClosed, guard.Breaker().State())
+}
+
+func TestCall(t *testing.T) {
+	guard := newTestGuard(Policy{MaxAttempts: 2})
+
+	calls := 0
+	value, err := Call(context.Background(), guard, func(context.Context) (string, error) {
+		calls++
+		if calls == 1 {
+			return "", errTransient
+		}
+		return "value", nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "value", value)
+
+	_, err = CallOnce(context.Background(), guard, func(context.Context) (string, error) {
+		return "", errTransient
+	})
+	assert.ErrorIs(t, err, errTransient)
+}
+
+func TestGuard_Backoff(t *testing.T) {
+	guard := NewGuard("s3", Policy{BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}, isTransient, nil)
+
+	for range 20 {
+		assert.Less(t, guard.backoff(1), 100*time.Millisecond)
+		assert.Less(t, guard.backoff(2), 200*time.Millisecond)
+		assert.Less(t, guard.backoff(10), 300*time.Millisecond)
+	}
+	assert.Zero(t, NewGuard("s3", Policy{}, isTransient, nil).backoff(1))
+}
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry(Policy{MaxAttempts: 1}, nil)
+
+	assert.Same(t, registry.Guard("s3:us-east-1", AWSTransient), registry.Guard("s3:us-east-1", AWSTransient))
+	assert.NotSame(t, registry.Guard("s3:us-east-1", AWSTransient), registry.Guard("s3:eu-west-1", AWSTransient))
+}
+
+func TestAWSTransient(t *testing.T) {
+	responseError := func(status int, err error) error {
+		return &awshttp.ResponseError{ResponseError: &smithyhttp.ResponseError{
+			Response: &smithyhttp.Response{Response: &http.Response{StatusCode: status}},
+			Err:      err,
+		}}
+	}
+
+	assert.True(t, AWSTransient(&smithy.GenericAPIError{Code: "ThrottlingException"}))
+	assert.True(t, AWSTransient(responseError(http.StatusServiceUnavailable, errors.New("unavailable"))))
+	assert.False(t, AWSTransient(responseError(http.StatusBadRequest, &smithy.GenericAPIError{Code: "InvalidParameterException"})))
+	assert.False(t, AWSTransient(errors.New("user not found")))
+	assert.False(t, AWSTransient(context.Canceled))
+}
@@ -0,0 +1,61 @@
+package scan
+
+import (
+	"bytes"
+	"path"
+	"strings"
+)
+
+// licenseFileNames are the base names, without extension, of files holding a license text
+var licenseFileNames = map[string]bool{"LICENSE": true, "LICENCE": true, "COPYING": true}
+
+// licenseMarkers identify a license by a phrase of its text, most specific first
+var licenseMarkers = []struct {
+	spdxID string
+	phrase string
+}{
+	{"AGPL-3.0", "GNU AFFERO GENERAL PUBLIC LICENSE"},
+	{"SSPL-1.0", "SERVER SIDE PUBLIC LICENSE"},
+	{"LGPL-3.0", "GNU LESSER GENERAL PUBLIC LICENSE VERSION 3"},
+	{"LGPL-2.1", "GNU LESSER GENERAL PUBLIC LICENSE VERSION 2.1"},
+	{"GPL-3.0", "GNU GENERAL PUBLIC LICENSE VERSION 3"},
+	{"GPL-2.0", "GNU GENERAL PUBLIC LICENSE VERSION 2"},
+	{"MPL-2.0", "MOZILLA PUBLIC LICENSE VERSION 2.0"},
+	{"BUSL-1.1", "BUSINESS SOURCE LICENSE 1.1"},
+	{"Apache-2.0", "APACHE LICENSE VERSION 2.0"},
+	{"MIT", "PERMISSION IS HEREBY GRANTED, FREE OF CHARGE"},
+	{"BSD-3-Clause", "NEITHER THE NAME OF"},
+	{"BSD-2-Clause", "REDISTRIBUTION AND USE IN SOURCE AND BINARY FORMS"},
+}
+
+// isLicenseFile reports whether a file name, such as LICENSE.md or COPYING, holds a license text
+func isLicenseFile(name string) bool {
+	base := strings.ToUpper(strings.TrimSuffix(name, path.Ext(name)))
+	return licenseFileNames[base]
+}
+
+// licenseHeadLines is the number of non-empty lines searched for a license's title. License bodies mention other
+// licenses, e.g. the GPL refers to the Affero GPL, so only the head identifies the license.
+const licenseHeadLines = 30
+
+// identifyLicense returns the SPDX identifier of a license text, or "" when it isn't recognized. Whitespace,
+// line breaks included, is normalized so that wrapped and indented texts match.
+func identifyLicense(content []byte) string {
+	var head []string
+	for _, line := range bytes.Split(content, []byte("\n")) {
+		if fields := strings.Fields(string(line)); len(fields) > 0 {
+			head = append(head, strings.ToUpper(strings.Join(fields, " ")))
+			if len(head) == licenseHeadLines {
+				break
+			}
+		}
+	}
+	text := strings.Join(head, " ")
+
+	for _, marker := range licenseMarkers {
+		if strings.Contains(text, marker.phrase) {
+			return marker.spdxID
+		}
+	}
+	return ""
+}
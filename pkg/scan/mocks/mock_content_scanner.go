@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/pkg/scan (interfaces: ContentScanner)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	scan "github.com/kazemisoroush/code-refactoring-tool/pkg/scan"
+)
+
+// MockContentScanner is a mock of ContentScanner interface.
+type MockContentScanner struct {
+	ctrl     *gomock.Controller
+	recorder *MockContentScannerMockRecorder
+}
+
+// MockContentScannerMockRecorder is the mock recorder for MockContentScanner.
+type MockContentScannerMockRecorder struct {
+	mock *MockContentScanner
+}
+
+// NewMockContentScanner creates a new mock instance.
+func NewMockContentScanner(ctrl *gomock.Controller) *MockContentScanner {
+	mock := &MockContentScanner{ctrl: ctrl}
+	mock.recorder = &MockContentScannerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockContentScanner) EXPECT() *MockContentScannerMockRecorder {
+	return m.recorder
+}
+
+// Scan mocks base method.
+func (m *MockContentScanner) Scan(arg0 context.Context, arg1 string) ([]scan.Finding, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Scan", arg0, arg1)
+	ret0, _ := ret[0].([]scan.Finding)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Scan indicates an expected call of Scan.
+func (mr *MockContentScannerMockRecorder) Scan(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scan", reflect.TypeOf((*MockContentScanner)(nil).Scan), arg0, arg1)
+}
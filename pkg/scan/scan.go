@@ -0,0 +1,192 @@
+// Package scan flags committed secrets and incompatible licenses in a repository before its content leaves the
+// service, e.g. before it is uploaded to a knowledge base.
+package scan
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Kind is the kind of problem a finding reports
+type Kind string
+
+const (
+	// KindSecret is a credential committed to the repository
+	KindSecret Kind = "secret"
+
+	// KindLicense is a license incompatible with shipping the code to third-party services
+	KindLicense Kind = "license"
+)
+
+// Severity is the severity of a finding. High severity findings block ingestion.
+type Severity string
+
+const (
+	// SeverityHigh is a finding that blocks ingestion
+	SeverityHigh Severity = "high"
+
+	// SeverityMedium is a likely problem worth reviewing, such as a high-entropy string assigned to a secret-looking name
+	SeverityMedium Severity = "medium"
+)
+
+// maxFileSize is the largest file scanned; larger files are generated or data files rather than code
+const maxFileSize = 1 << 20
+
+// skippedDirs are directories never scanned
+var skippedDirs = map[string]bool{".git": true, "node_modules": true, "vendor": true}
+
+// Finding is a problem found in a file
+type Finding struct {
+	Kind     Kind
+	Rule     string // Secret rule or SPDX identifier of the license
+	Severity Severity
+	FilePath string // Relative to the repository root
+	Line     int    // Line of a secret, 0 for licenses
+	Message  string // Describes the finding without revealing the secret
+}
+
+// ContentScanner finds the problems in a repository's content
+//
+//go:generate mockgen -destination=./mocks/mock_content_scanner.go -mock_names=ContentScanner=MockContentScanner -package=mocks . ContentScanner
+type ContentScanner interface {
+	// Scan scans the files under root
+	Scan(ctx context.Context, root string) ([]Finding, error)
+}
+
+// Scanner is a ContentScanner matching secrets with regular expressions and an entropy check, and licenses by the
+// text of license files
+type Scanner struct {
+	incompatibleLicenses map[string]bool
+}
+
+// NewScanner creates a Scanner reporting the given SPDX license identifiers as incompatible
+func NewScanner(incompatibleLicenses []string) *Scanner {
+	incompatible := make(map[string]bool, len(incompatibleLicenses))
+	for _, license := range incompatibleLicenses {
+		if license = strings.TrimSpace(license); license != "" {
+			incompatible[strings.ToUpper(license)] = true
+		}
+	}
+	return &Scanner{incompatibleLicenses: incompatible}
+}
+
+// Scan implements ContentScanner. Binary files, files over 1 MiB and dependency directories are skipped.
+// Findings are ordered by file and line.
+func (s *Scanner) Scan(ctx context.Context, root string) ([]Finding, error) {
+	var findings []Finding
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if skippedDirs[entry.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if info.Size() > maxFileSize {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if isBinary(content) {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		findings = append(findings, findSecrets(rel, content)...)
+		if isLicenseFile(entry.Name()) {
+			if license := identifyLicense(content); license != "" && s.incompatibleLicenses[strings.ToUpper(license)] {
+				findings = append(findings, Finding{
+					Kind:     KindLicense,
+					Rule:     license,
+					Severity: SeverityHigh,
+					FilePath: rel,
+					Message:  fmt.Sprintf("%s license is not allowed to be ingested", license),
+				})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan repository: %w", err)
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].FilePath != findings[j].FilePath {
+			return findings[i].FilePath < findings[j].FilePath
+		}
+		return findings[i].Line < findings[j].Line
+	})
+	return findings, nil
+}
+
+// BlockedError reports the high severity findings that blocked the ingestion of a repository
+type BlockedError struct {
+	Findings []Finding
+}
+
+// Error implements the error interface, naming the first few findings
+func (e *BlockedError) Error() string {
+	const listed = 3
+	descriptions := make([]string, 0, listed)
+	for _, finding := range e.Findings[:min(listed, len(e.Findings))] {
+		location := finding.FilePath
+		if finding.Line > 0 {
+			location = fmt.Sprintf("%s:%d", finding.FilePath, finding.Line)
+		}
+		descriptions = append(descriptions, fmt.Sprintf("%s in %s", finding.Message, location))
+	}
+	if len(e.Findings) > listed {
+		descriptions = append(descriptions, fmt.Sprintf("and %d more", len(e.Findings)-listed))
+	}
+	return fmt.Sprintf("ingestion blocked by %d high severity findings: %s", len(e.Findings), strings.Join(descriptions, "; "))
+}
+
+// Check scans the repository under root and returns every finding. The error is a *BlockedError when any finding is
+// high severity, so callers can still record the findings of a blocked repository.
+func Check(ctx context.Context, scanner ContentScanner, root string) ([]Finding, error) {
+	findings, err := scanner.Scan(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+
+	var blocking []Finding
+	for _, finding := range findings {
+		if finding.Severity == SeverityHigh {
+			blocking = append(blocking, finding)
+		}
+	}
+	if len(blocking) > 0 {
+		return findings, &BlockedError{Findings: blocking}
+	}
+	return findings, nil
+}
+
+// isBinary reports whether content looks binary, like git does: a NUL byte in the first 8000 bytes
+func isBinary(content []byte) bool {
+	return bytes.IndexByte(content[:min(8000, len(content))], 0) >= 0
+}
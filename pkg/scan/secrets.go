@@ -0,0 +1,103 @@
+package scan
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"math"
+	"regexp"
+)
+
+// secretRule matches one kind of credential
+type secretRule struct {
+	name        string
+	description string
+	pattern     *regexp.Regexp
+}
+
+// secretRules match credentials with a recognizable format; every match is high severity
+var secretRules = []secretRule{
+	{"aws-access-key-id", "AWS access key ID", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"aws-secret-access-key", "AWS secret access key", regexp.MustCompile(`(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?[A-Za-z0-9/+]{40}\b`)},
+	{"github-token", "GitHub token", regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36}|github_pat_[A-Za-z0-9_]{82})\b`)},
+	{"gitlab-token", "GitLab personal access token", regexp.MustCompile(`\bglpat-[A-Za-z0-9_\-]{20}\b`)},
+	{"slack-token", "Slack token", regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}\b`)},
+	{"stripe-secret-key", "Stripe secret key", regexp.MustCompile(`\b[sr]k_live_[A-Za-z0-9]{24,}\b`)},
+	{"google-api-key", "Google API key", regexp.MustCompile(`\bAIza[0-9A-Za-z_\-]{35}\b`)},
+	{"private-key", "private key", regexp.MustCompile(`-----BEGIN (?:RSA |EC |DSA |OPENSSH |PGP )?PRIVATE KEY(?: BLOCK)?-----`)},
+}
+
+// CredentialPatterns returns the patterns of the credentials with a recognizable format, for callers masking
+// credentials rather than reporting them. The private key pattern only matches the header line of a key.
+func CredentialPatterns() []*regexp.Regexp {
+	patterns := make([]*regexp.Regexp, len(secretRules))
+	for i, rule := range secretRules {
+		patterns[i] = rule.pattern
+	}
+	return patterns
+}
+
+// genericSecretPattern matches a quoted literal assigned to a secret-looking name; the literal is the second group
+var genericSecretPattern = regexp.MustCompile(`(?i)(secret|token|passw(?:or)?d|api_?key|access_?key|credential)[a-z_]*["']?\s*[:=]+\s*["']([^"'\s]{16,})["']`)
+
+// minSecretEntropy is the Shannon entropy, in bits per character, above which a generic literal is reported. Random
+// keys score above 4, words and placeholders such as "your-api-key-here" below.
+const minSecretEntropy = 3.5
+
+// findSecrets reports the credentials committed in a file, one finding per rule and line
+func findSecrets(path string, content []byte) []Finding {
+	var findings []Finding
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxFileSize)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+
+		matched := false
+		for _, rule := range secretRules {
+			if !rule.pattern.MatchString(text) {
+				continue
+			}
+			matched = true
+			findings = append(findings, Finding{
+				Kind:     KindSecret,
+				Rule:     rule.name,
+				Severity: SeverityHigh,
+				FilePath: path,
+				Line:     line,
+				Message:  fmt.Sprintf("Committed %s", rule.description),
+			})
+		}
+		if matched {
+			continue
+		}
+
+		if match := genericSecretPattern.FindStringSubmatch(text); match != nil && shannonEntropy(match[2]) >= minSecretEntropy {
+			findings = append(findings, Finding{
+				Kind:     KindSecret,
+				Rule:     "generic-secret",
+				Severity: SeverityMedium,
+				FilePath: path,
+				Line:     line,
+				Message:  fmt.Sprintf("High-entropy value assigned to %s", match[1]),
+			})
+		}
+	}
+	return findings
+}
+
+// shannonEntropy returns the entropy of value in bits per character
+func shannonEntropy(value string) float64 {
+	counts := make(map[rune]int)
+	total := 0
+	for _, r := range value {
+		counts[r]++
+		total++
+	}
+
+	entropy := 0.0
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
@@ -0,0 +1,108 @@
+// Package startup checks the dependencies of the service when it starts, so every unavailable dependency is reported
+// at once with a hint of how to fix it, instead of the service exiting on the first one it fails to reach.
+package startup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrDependenciesUnavailable is returned when the service can't start because of the dependencies it failed to reach
+var ErrDependenciesUnavailable = errors.New("dependencies unavailable")
+
+// Check is a dependency of the service checked at startup
+type Check struct {
+	Name     string                          // Name of the dependency, e.g. "postgres"
+	Hint     string                          // How to fix the dependency when its check fails
+	Required bool                            // Whether the service can't start without the dependency, even degraded
+	Routes   []string                        // Route prefixes disabled while the dependency is unavailable
+	Run      func(ctx context.Context) error // Checks the dependency is available
+}
+
+// Failure is a check that failed
+type Failure struct {
+	Check
+	Err error
+}
+
+// Report lists the checks that failed at startup, in the order they were given
+type Report struct {
+	Failures []Failure
+}
+
+// Run runs the checks concurrently, each within timeout, and reports those that failed. A timeout of 0 leaves the
+// checks bounded by ctx only.
+func Run(ctx context.Context, checks []Check, timeout time.Duration) Report {
+	errs := make([]error, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checkCtx := ctx
+			if timeout > 0 {
+				var cancel context.CancelFunc
+				checkCtx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+			errs[i] = check.Run(checkCtx)
+		}()
+	}
+	wg.Wait()
+
+	var report Report
+	for i, err := range errs {
+		if err != nil {
+			report.Failures = append(report.Failures, Failure{Check: checks[i], Err: err})
+		}
+	}
+	return report
+}
+
+// Log logs every failure with its hint
+func (r Report) Log() {
+	for _, failure := range r.Failures {
+		slog.Error("startup check failed", "dependency", failure.Name, "required", failure.Required, "error", failure.Err, "hint", failure.Hint)
+	}
+}
+
+// Err returns an error wrapping ErrDependenciesUnavailable and naming the failed dependencies when the service can't
+// start. Unless degraded, any failure stops the service; otherwise only the failures of required dependencies do.
+func (r Report) Err(degraded bool) error {
+	var names []string
+	for _, failure := range r.Failures {
+		if !degraded || failure.Required {
+			names = append(names, failure.Name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrDependenciesUnavailable, strings.Join(names, ", "))
+}
+
+// Unavailable returns the failure disabling route, if any
+func (r Report) Unavailable(route string) (Failure, bool) {
+	for _, failure := range r.Failures {
+		for _, prefix := range failure.Routes {
+			if route == prefix || strings.HasPrefix(route, strings.TrimSuffix(prefix, "/")+"/") {
+				return failure, true
+			}
+		}
+	}
+	return Failure{}, false
+}
+
+// Dependencies returns the names of the failed dependencies
+func (r Report) Dependencies() []string {
+	names := make([]string, 0, len(r.Failures))
+	for _, failure := range r.Failures {
+		names = append(names, failure.Name)
+	}
+	return names
+}
@@ -0,0 +1,74 @@
+package startup
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun_ReportsEveryFailureInOrder(t *testing.T) {
+	errRefused := errors.New("connection refused")
+	checks := []Check{
+		{Name: "postgres", Required: true, Run: func(context.Context) error { return errRefused }},
+		{Name: "cognito", Run: func(context.Context) error { return nil }},
+		{Name: "s3", Run: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}},
+	}
+
+	report := Run(context.Background(), checks, 10*time.Millisecond)
+
+	assert.Equal(t, []string{"postgres", "s3"}, report.Dependencies())
+	assert.ErrorIs(t, report.Failures[0].Err, errRefused)
+	assert.ErrorIs(t, report.Failures[1].Err, context.DeadlineExceeded)
+}
+
+func TestReport_Err(t *testing.T) {
+	tests := []struct {
+		name        string
+		failures    []Failure
+		degraded    bool
+		expectedErr string
+	}{
+		{name: "no failures"},
+		{name: "optional failure", failures: []Failure{{Check: Check{Name: "s3"}}}, expectedErr: "dependencies unavailable: s3"},
+		{name: "optional failure degraded", failures: []Failure{{Check: Check{Name: "s3"}}}, degraded: true},
+		{
+			name:        "required failure degraded",
+			failures:    []Failure{{Check: Check{Name: "postgres", Required: true}}, {Check: Check{Name: "s3"}}},
+			degraded:    true,
+			expectedErr: "dependencies unavailable: postgres",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Report{Failures: tt.failures}.Err(tt.degraded)
+
+			if tt.expectedErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrDependenciesUnavailable)
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestReport_Unavailable(t *testing.T) {
+	report := Report{Failures: []Failure{{Check: Check{Name: "cognito", Routes: []string{"/auth"}}}}}
+
+	failure, ok := report.Unavailable("/auth/signin")
+	require.True(t, ok)
+	assert.Equal(t, "cognito", failure.Name)
+
+	_, ok = report.Unavailable("/authors")
+	assert.False(t, ok)
+	_, ok = report.Unavailable("/api/v1/projects")
+	assert.False(t, ok)
+}
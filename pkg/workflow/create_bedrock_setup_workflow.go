@@ -8,11 +8,18 @@ import (
 
 	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/builder"
 	"github.com/kazemisoroush/code-refactoring-tool/pkg/codebase"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/scan"
 )
 
+// CreateBedrockSetupWorkflowName names the runs of the Bedrock setup workflow.
+const CreateBedrockSetupWorkflowName = "create_bedrock_setup"
+
 // CreateBedrockSetupWorkflow represents a workflow for setting up Bedrock AI resources
 type CreateBedrockSetupWorkflow struct {
+	engine       *Engine
+	setup        Setup
 	repository   codebase.Codebase
+	scanner      scan.ContentScanner
 	ragBuilder   builder.RAGBuilder
 	agentBuilder builder.AgentBuilder
 
@@ -25,61 +32,85 @@ type CreateBedrockSetupWorkflow struct {
 
 // NewCreateBedrockSetupWorkflow creates a new CreateBedrockSetupWorkflow instance
 func NewCreateBedrockSetupWorkflow(
+	engine *Engine,
+	setup Setup,
 	repo codebase.Codebase,
+	scanner scan.ContentScanner,
 	ragBuilder builder.RAGBuilder,
 	agentBuilder builder.AgentBuilder,
 ) (Workflow, error) {
 	return &CreateBedrockSetupWorkflow{
+		engine:       engine,
+		setup:        setup,
 		repository:   repo,
+		scanner:      scanner,
 		ragBuilder:   ragBuilder,
 		agentBuilder: agentBuilder,
 	}, nil
 }
 
-// Run executes the Bedrock setup workflow to provision AI resources
+// Run executes the Bedrock setup workflow to provision AI resources. A failed setup keeps what it built, so running
+// it again resumes after its last completed step.
 func (s *CreateBedrockSetupWorkflow) Run(ctx context.Context) error {
-	slog.Info("Running Bedrock setup workflow")
+	slog.InfoContext(ctx, "Running Bedrock setup workflow", "run_id", s.setup.RunID)
 
 	defer func() {
 		err := s.repository.Cleanup()
 		if err != nil {
-			slog.Error("failed to cleanup repository", "error", err)
+			slog.ErrorContext(ctx, "failed to cleanup repository", "error", err)
 		}
 	}()
 
-	// 1. Clone the repository
-	slog.Info("Cloning repository for Bedrock setup")
-	err := s.repository.Clone(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to clone repository: %w", err)
-	}
-	slog.Info("Repository cloned successfully")
-
-	// 2. Build the RAG pipeline using Bedrock
-	slog.Info("Building Bedrock RAG pipeline")
-	ragID, err := s.ragBuilder.Build(ctx)
+	run, err := s.engine.Execute(ctx, s.definition(), s.setup.RunID)
 	if err != nil {
-		return fmt.Errorf("failed to build Bedrock RAG pipeline: %w", err)
+		return err
 	}
-	s.ragID = ragID
-	s.vectorStoreID = ragID // In Bedrock, the KB ID serves as both RAG ID and vector store ID
-	slog.Info("Bedrock RAG pipeline built successfully", "ragID", ragID)
 
-	// 3. Build the Bedrock agent
-	slog.Info("Building Bedrock agent", "ragID", ragID)
-	agentID, agentVersion, err := s.agentBuilder.Build(ctx, ragID)
-	if err != nil {
-		return fmt.Errorf("failed to build Bedrock agent: %w", err)
-	}
-	s.agentID = agentID
-	s.agentVersion = agentVersion
-	slog.Info("Bedrock agent built successfully", "agentID", agentID, "version", agentVersion)
+	s.ragID = run.String("rag_id")
+	s.vectorStoreID = s.ragID // In Bedrock, the KB ID serves as both RAG ID and vector store ID
+	s.agentID = run.String("agent_id")
+	s.agentVersion = run.String("agent_version")
 
-	slog.Info("Bedrock setup workflow completed successfully")
+	slog.InfoContext(ctx, "Bedrock setup workflow completed successfully")
 	return nil
 }
 
+// Rollback tears down the resources a failed or interrupted run of the workflow created
+func (s *CreateBedrockSetupWorkflow) Rollback(ctx context.Context) error {
+	_, err := s.engine.Rollback(ctx, s.definition(), s.setup.RunID)
+	return err
+}
+
 // GetResourceIDs returns the resource IDs created during Bedrock setup
 func (s *CreateBedrockSetupWorkflow) GetResourceIDs() (vectorStoreID, ragID, agentID, agentVersion string) {
 	return s.vectorStoreID, s.ragID, s.agentID, s.agentVersion
 }
+
+// definition declares the steps of the Bedrock setup
+func (s *CreateBedrockSetupWorkflow) definition() Definition {
+	return Definition{
+		Name:      CreateBedrockSetupWorkflowName,
+		Input:     s.setup.Input,
+		Resumable: true,
+		Steps: []Step{
+			cloneStep(s.repository),
+			{
+				// The clone is scanned before any of it is uploaded to S3
+				Name:      "scan",
+				DependsOn: []string{"clone"},
+				Volatile:  true,
+				Run: func(ctx context.Context, _ *Run) error {
+					slog.InfoContext(ctx, "Scanning repository for secrets and incompatible licenses")
+					findings, err := scan.Check(ctx, s.scanner, s.repository.GetPath())
+					if err != nil {
+						return fmt.Errorf("failed to scan repository: %w", err)
+					}
+					slog.InfoContext(ctx, "Repository scanned successfully", "findings", len(findings))
+					return nil
+				},
+			},
+			buildRAGStep("Bedrock", "scan", s.ragBuilder),
+			buildAgentStep("Bedrock", s.agentBuilder),
+		},
+	}
+}
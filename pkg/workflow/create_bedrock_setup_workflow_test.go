@@ -12,6 +12,8 @@ import (
 
 	builderMocks "github.com/kazemisoroush/code-refactoring-tool/pkg/ai/builder/mocks"
 	repositoryMocks "github.com/kazemisoroush/code-refactoring-tool/pkg/codebase/mocks"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/scan"
+	scanMocks "github.com/kazemisoroush/code-refactoring-tool/pkg/scan/mocks"
 	"github.com/kazemisoroush/code-refactoring-tool/pkg/workflow"
 )
 
@@ -21,11 +23,12 @@ func TestNewCreateBedrockSetupWorkflow(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := repositoryMocks.NewMockRepository(ctrl)
+	mockScanner := scanMocks.NewMockContentScanner(ctrl)
 	mockRAGBuilder := builderMocks.NewMockRAGBuilder(ctrl)
 	mockAgentBuilder := builderMocks.NewMockAgentBuilder(ctrl)
 
 	// Act
-	wf, err := workflow.NewCreateBedrockSetupWorkflow(mockRepo, mockRAGBuilder, mockAgentBuilder)
+	wf, err := workflow.NewCreateBedrockSetupWorkflow(newTestEngine(), workflow.Setup{RunID: "setup-1"}, mockRepo, mockScanner, mockRAGBuilder, mockAgentBuilder)
 
 	// Assert
 	require.NoError(t, err)
@@ -38,6 +41,7 @@ func TestCreateBedrockSetupWorkflow_Run_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := repositoryMocks.NewMockRepository(ctrl)
+	mockScanner := scanMocks.NewMockContentScanner(ctrl)
 	mockRAGBuilder := builderMocks.NewMockRAGBuilder(ctrl)
 	mockAgentBuilder := builderMocks.NewMockAgentBuilder(ctrl)
 
@@ -56,6 +60,16 @@ func TestCreateBedrockSetupWorkflow_Run_Success(t *testing.T) {
 		Return(nil).
 		Times(1)
 
+	mockRepo.EXPECT().
+		GetPath().
+		Return("/tmp/repo").
+		Times(1)
+
+	mockScanner.EXPECT().
+		Scan(gomock.Any(), "/tmp/repo").
+		Return(nil, nil).
+		Times(1)
+
 	mockRAGBuilder.EXPECT().
 		Build(gomock.Any()).
 		Return(ragID, nil).
@@ -66,7 +80,7 @@ func TestCreateBedrockSetupWorkflow_Run_Success(t *testing.T) {
 		Return(agentID, agentVersion, nil).
 		Times(1)
 
-	wf, err := workflow.NewCreateBedrockSetupWorkflow(mockRepo, mockRAGBuilder, mockAgentBuilder)
+	wf, err := workflow.NewCreateBedrockSetupWorkflow(newTestEngine(), workflow.Setup{RunID: "setup-1"}, mockRepo, mockScanner, mockRAGBuilder, mockAgentBuilder)
 	require.NoError(t, err)
 
 	// Act
@@ -91,6 +105,7 @@ func TestCreateBedrockSetupWorkflow_Run_RAGBuildError(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := repositoryMocks.NewMockRepository(ctrl)
+	mockScanner := scanMocks.NewMockContentScanner(ctrl)
 	mockRAGBuilder := builderMocks.NewMockRAGBuilder(ctrl)
 	mockAgentBuilder := builderMocks.NewMockAgentBuilder(ctrl)
 
@@ -107,6 +122,16 @@ func TestCreateBedrockSetupWorkflow_Run_RAGBuildError(t *testing.T) {
 		Return(nil).
 		Times(1)
 
+	mockRepo.EXPECT().
+		GetPath().
+		Return("/tmp/repo").
+		Times(1)
+
+	mockScanner.EXPECT().
+		Scan(gomock.Any(), "/tmp/repo").
+		Return(nil, nil).
+		Times(1)
+
 	mockRAGBuilder.EXPECT().
 		Build(gomock.Any()).
 		Return("", ragError).
@@ -117,7 +142,7 @@ func TestCreateBedrockSetupWorkflow_Run_RAGBuildError(t *testing.T) {
 		Build(gomock.Any(), gomock.Any()).
 		Times(0)
 
-	wf, err := workflow.NewCreateBedrockSetupWorkflow(mockRepo, mockRAGBuilder, mockAgentBuilder)
+	wf, err := workflow.NewCreateBedrockSetupWorkflow(newTestEngine(), workflow.Setup{RunID: "setup-1"}, mockRepo, mockScanner, mockRAGBuilder, mockAgentBuilder)
 	require.NoError(t, err)
 
 	// Act
@@ -127,3 +152,37 @@ func TestCreateBedrockSetupWorkflow_Run_RAGBuildError(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to build Bedrock RAG pipeline")
 }
+
+func TestCreateBedrockSetupWorkflow_Run_BlockedByScan(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repositoryMocks.NewMockRepository(ctrl)
+	mockScanner := scanMocks.NewMockContentScanner(ctrl)
+	mockRAGBuilder := builderMocks.NewMockRAGBuilder(ctrl)
+	mockAgentBuilder := builderMocks.NewMockAgentBuilder(ctrl)
+
+	mockRepo.EXPECT().Cleanup().Return(nil).Times(1)
+	mockRepo.EXPECT().Clone(gomock.Any()).Return(nil).Times(1)
+	mockRepo.EXPECT().GetPath().Return("/tmp/repo").Times(1)
+	mockScanner.EXPECT().
+		Scan(gomock.Any(), "/tmp/repo").
+		Return([]scan.Finding{{Kind: scan.KindSecret, Rule: "aws-access-key-id", Severity: scan.SeverityHigh, FilePath: "prod.env", Line: 2, Message: "Committed AWS access key ID"}}, nil).
+		Times(1)
+
+	// Nothing is uploaded when the scan blocks ingestion
+	mockRAGBuilder.EXPECT().Build(gomock.Any()).Times(0)
+	mockAgentBuilder.EXPECT().Build(gomock.Any(), gomock.Any()).Times(0)
+
+	wf, err := workflow.NewCreateBedrockSetupWorkflow(newTestEngine(), workflow.Setup{RunID: "setup-1"}, mockRepo, mockScanner, mockRAGBuilder, mockAgentBuilder)
+	require.NoError(t, err)
+
+	// Act
+	err = wf.Run(context.Background())
+
+	// Assert
+	var blocked *scan.BlockedError
+	require.True(t, errors.As(err, &blocked))
+	assert.Len(t, blocked.Findings, 1)
+}
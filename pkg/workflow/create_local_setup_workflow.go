@@ -3,15 +3,19 @@ package workflow
 
 import (
 	"context"
-	"fmt"
 	"log/slog"
 
 	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/builder"
 	"github.com/kazemisoroush/code-refactoring-tool/pkg/codebase"
 )
 
+// CreateLocalSetupWorkflowName names the runs of the local setup workflow.
+const CreateLocalSetupWorkflowName = "create_local_setup"
+
 // CreateLocalSetupWorkflow represents a workflow for setting up local AI resources
 type CreateLocalSetupWorkflow struct {
+	engine       *Engine
+	setup        Setup
 	repository   codebase.Codebase
 	ragBuilder   builder.RAGBuilder
 	agentBuilder builder.AgentBuilder
@@ -25,61 +29,68 @@ type CreateLocalSetupWorkflow struct {
 
 // NewCreateLocalSetupWorkflow creates a new CreateLocalSetupWorkflow instance
 func NewCreateLocalSetupWorkflow(
+	engine *Engine,
+	setup Setup,
 	repo codebase.Codebase,
 	ragBuilder builder.RAGBuilder,
 	agentBuilder builder.AgentBuilder,
 ) (Workflow, error) {
 	return &CreateLocalSetupWorkflow{
+		engine:       engine,
+		setup:        setup,
 		repository:   repo,
 		ragBuilder:   ragBuilder,
 		agentBuilder: agentBuilder,
 	}, nil
 }
 
-// Run executes the local setup workflow to provision AI resources
+// Run executes the local setup workflow to provision AI resources. A failed setup keeps what it built, so running
+// it again resumes after its last completed step.
 func (s *CreateLocalSetupWorkflow) Run(ctx context.Context) error {
-	slog.Info("Running local setup workflow")
+	slog.InfoContext(ctx, "Running local setup workflow", "run_id", s.setup.RunID)
 
 	defer func() {
 		err := s.repository.Cleanup()
 		if err != nil {
-			slog.Error("failed to cleanup repository", "error", err)
+			slog.ErrorContext(ctx, "failed to cleanup repository", "error", err)
 		}
 	}()
 
-	// 1. Clone the repository
-	slog.Info("Cloning repository for local setup")
-	err := s.repository.Clone(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to clone repository: %w", err)
-	}
-	slog.Info("Repository cloned successfully")
-
-	// 2. Build the local RAG pipeline
-	slog.Info("Building local RAG pipeline")
-	ragID, err := s.ragBuilder.Build(ctx)
+	run, err := s.engine.Execute(ctx, s.definition(), s.setup.RunID)
 	if err != nil {
-		return fmt.Errorf("failed to build local RAG pipeline: %w", err)
+		return err
 	}
-	s.ragID = ragID
-	s.vectorStoreID = ragID // For local setup, use the same ID for both
-	slog.Info("Local RAG pipeline built successfully", "ragID", ragID)
 
-	// 3. Build the local agent
-	slog.Info("Building local agent", "ragID", ragID)
-	agentID, agentVersion, err := s.agentBuilder.Build(ctx, ragID)
-	if err != nil {
-		return fmt.Errorf("failed to build local agent: %w", err)
-	}
-	s.agentID = agentID
-	s.agentVersion = agentVersion
-	slog.Info("Local agent built successfully", "agentID", agentID, "version", agentVersion)
+	s.ragID = run.String("rag_id")
+	s.vectorStoreID = s.ragID // For local setup, use the same ID for both
+	s.agentID = run.String("agent_id")
+	s.agentVersion = run.String("agent_version")
 
-	slog.Info("Local setup workflow completed successfully")
+	slog.InfoContext(ctx, "Local setup workflow completed successfully")
 	return nil
 }
 
+// Rollback tears down the resources a failed or interrupted run of the workflow created
+func (s *CreateLocalSetupWorkflow) Rollback(ctx context.Context) error {
+	_, err := s.engine.Rollback(ctx, s.definition(), s.setup.RunID)
+	return err
+}
+
 // GetResourceIDs returns the resource IDs created during local setup
 func (s *CreateLocalSetupWorkflow) GetResourceIDs() (vectorStoreID, ragID, agentID, agentVersion string) {
 	return s.vectorStoreID, s.ragID, s.agentID, s.agentVersion
 }
+
+// definition declares the steps of the local setup
+func (s *CreateLocalSetupWorkflow) definition() Definition {
+	return Definition{
+		Name:      CreateLocalSetupWorkflowName,
+		Input:     s.setup.Input,
+		Resumable: true,
+		Steps: []Step{
+			cloneStep(s.repository),
+			buildRAGStep("local", "clone", s.ragBuilder),
+			buildAgentStep("local", s.agentBuilder),
+		},
+	}
+}
@@ -25,7 +25,7 @@ func TestNewCreateLocalSetupWorkflow(t *testing.T) {
 	mockAgentBuilder := builderMocks.NewMockAgentBuilder(ctrl)
 
 	// Act
-	wf, err := workflow.NewCreateLocalSetupWorkflow(mockRepo, mockRAGBuilder, mockAgentBuilder)
+	wf, err := workflow.NewCreateLocalSetupWorkflow(newTestEngine(), workflow.Setup{RunID: "setup-1"}, mockRepo, mockRAGBuilder, mockAgentBuilder)
 
 	// Assert
 	require.NoError(t, err)
@@ -66,7 +66,7 @@ func TestCreateLocalSetupWorkflow_Run_Success(t *testing.T) {
 		Return(agentID, agentVersion, nil).
 		Times(1)
 
-	wf, err := workflow.NewCreateLocalSetupWorkflow(mockRepo, mockRAGBuilder, mockAgentBuilder)
+	wf, err := workflow.NewCreateLocalSetupWorkflow(newTestEngine(), workflow.Setup{RunID: "setup-1"}, mockRepo, mockRAGBuilder, mockAgentBuilder)
 	require.NoError(t, err)
 
 	// Act
@@ -102,10 +102,11 @@ func TestCreateLocalSetupWorkflow_Run_RepositoryCloneError(t *testing.T) {
 		Return(nil).
 		Times(1)
 
+	// A failed clone is retried twice before the setup gives up
 	mockRepo.EXPECT().
 		Clone(gomock.Any()).
 		Return(repoError).
-		Times(1)
+		Times(3)
 
 	// RAG and Agent builders should not be called since repo clone fails
 	mockRAGBuilder.EXPECT().
@@ -116,7 +117,7 @@ func TestCreateLocalSetupWorkflow_Run_RepositoryCloneError(t *testing.T) {
 		Build(gomock.Any(), gomock.Any()).
 		Times(0)
 
-	wf, err := workflow.NewCreateLocalSetupWorkflow(mockRepo, mockRAGBuilder, mockAgentBuilder)
+	wf, err := workflow.NewCreateLocalSetupWorkflow(newTestEngine(), workflow.Setup{RunID: "setup-1"}, mockRepo, mockRAGBuilder, mockAgentBuilder)
 	require.NoError(t, err)
 
 	// Act
@@ -160,7 +161,12 @@ func TestCreateLocalSetupWorkflow_Run_AgentBuildError(t *testing.T) {
 		Return("", "", agentError).
 		Times(1)
 
-	wf, err := workflow.NewCreateLocalSetupWorkflow(mockRepo, mockRAGBuilder, mockAgentBuilder)
+	// The RAG pipeline is kept for the setup to resume
+	mockRAGBuilder.EXPECT().
+		TearDown(gomock.Any(), gomock.Any(), gomock.Any()).
+		Times(0)
+
+	wf, err := workflow.NewCreateLocalSetupWorkflow(newTestEngine(), workflow.Setup{RunID: "setup-1"}, mockRepo, mockRAGBuilder, mockAgentBuilder)
 	require.NoError(t, err)
 
 	// Act
@@ -170,3 +176,69 @@ func TestCreateLocalSetupWorkflow_Run_AgentBuildError(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to build local agent")
 }
+
+func TestCreateLocalSetupWorkflow_Run_ResumesFailedSetup(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repositoryMocks.NewMockRepository(ctrl)
+	mockRAGBuilder := builderMocks.NewMockRAGBuilder(ctrl)
+	mockAgentBuilder := builderMocks.NewMockAgentBuilder(ctrl)
+	engine := newTestEngine()
+
+	ragID := "local-rag-id"
+
+	// The repository is cloned for both runs, the RAG pipeline is only built by the first
+	mockRepo.EXPECT().Cleanup().Return(nil).Times(2)
+	mockRepo.EXPECT().Clone(gomock.Any()).Return(nil).Times(2)
+	mockRAGBuilder.EXPECT().Build(gomock.Any()).Return(ragID, nil).Times(1)
+	gomock.InOrder(
+		mockAgentBuilder.EXPECT().Build(gomock.Any(), ragID).Return("", "", errors.New("ollama unavailable")),
+		mockAgentBuilder.EXPECT().Build(gomock.Any(), ragID).Return("local-agent-id", "v1", nil),
+	)
+
+	wf, err := workflow.NewCreateLocalSetupWorkflow(engine, workflow.Setup{RunID: "setup-1"}, mockRepo, mockRAGBuilder, mockAgentBuilder)
+	require.NoError(t, err)
+	require.Error(t, wf.Run(context.Background()))
+
+	// Act
+	err = wf.Run(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	_, returnedRAGID, agentID, _ := wf.(*workflow.CreateLocalSetupWorkflow).GetResourceIDs()
+	assert.Equal(t, ragID, returnedRAGID)
+	assert.Equal(t, "local-agent-id", agentID)
+}
+
+func TestCreateLocalSetupWorkflow_Rollback_TearsDownFailedSetup(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repositoryMocks.NewMockRepository(ctrl)
+	mockRAGBuilder := builderMocks.NewMockRAGBuilder(ctrl)
+	mockAgentBuilder := builderMocks.NewMockAgentBuilder(ctrl)
+
+	ragID := "local-rag-id"
+
+	mockRepo.EXPECT().Cleanup().Return(nil)
+	mockRepo.EXPECT().Clone(gomock.Any()).Return(nil)
+	mockRAGBuilder.EXPECT().Build(gomock.Any()).Return(ragID, nil)
+	mockAgentBuilder.EXPECT().Build(gomock.Any(), ragID).Return("", "", errors.New("ollama unavailable"))
+
+	// Only the RAG pipeline was built, so only it is torn down
+	mockRAGBuilder.EXPECT().TearDown(gomock.Any(), ragID, ragID).Return(nil)
+	mockAgentBuilder.EXPECT().TearDown(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	wf, err := workflow.NewCreateLocalSetupWorkflow(newTestEngine(), workflow.Setup{RunID: "setup-1"}, mockRepo, mockRAGBuilder, mockAgentBuilder)
+	require.NoError(t, err)
+	require.Error(t, wf.Run(context.Background()))
+
+	// Act
+	err = wf.(*workflow.CreateLocalSetupWorkflow).Rollback(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+}
@@ -0,0 +1,369 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/logging"
+)
+
+// Step is a named unit of work in a workflow definition.
+type Step struct {
+	// Name identifies the step within its definition and in the persisted progress of runs.
+	Name string
+
+	// DependsOn names the steps that must complete before this one runs.
+	DependsOn []string
+
+	// Run does the step's work. It may store outputs on the run for later steps and compensations.
+	Run func(ctx context.Context, run *Run) error
+
+	// Compensate undoes the work of the completed step when a later step fails. Steps without side effects leave it nil.
+	Compensate func(ctx context.Context, run *Run) error
+
+	// Retries is how many more times a failing step is attempted before the run fails.
+	Retries int
+
+	// Volatile steps are run again when an interrupted run resumes, even if they completed, because their work doesn't
+	// survive a restart, such as cloning a repository to local disk.
+	Volatile bool
+}
+
+// Definition declares a workflow as a set of steps.
+type Definition struct {
+	Name  string
+	Steps []Step
+
+	// Input is stored on a new run before its first step, so a resumed run sees the values it was started with.
+	Input map[string]any
+
+	// Resumable runs keep their completed steps when a step fails, instead of compensating them, so executing the run
+	// again only retries what failed. Rollback cleans up a failed run that won't be resumed.
+	Resumable bool
+
+	// Progress, when set, is called before each step runs with the step's name and the percentage of the definition's
+	// steps the run completed so far.
+	Progress func(ctx context.Context, step string, percent int)
+}
+
+// order returns the steps so each comes after the steps it depends on, otherwise keeping their declared order.
+func (d Definition) order() ([]Step, error) {
+	steps := make(map[string]Step, len(d.Steps))
+	for _, step := range d.Steps {
+		if step.Name == "" || step.Run == nil {
+			return nil, fmt.Errorf("workflow %s has a step without a name or run function", d.Name)
+		}
+		if _, ok := steps[step.Name]; ok {
+			return nil, fmt.Errorf("workflow %s declares step %s twice", d.Name, step.Name)
+		}
+		steps[step.Name] = step
+	}
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	marks := make(map[string]int, len(d.Steps))
+	ordered := make([]Step, 0, len(d.Steps))
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch marks[name] {
+		case visiting:
+			return fmt.Errorf("workflow %s has a dependency cycle through step %s", d.Name, name)
+		case visited:
+			return nil
+		}
+
+		marks[name] = visiting
+		for _, dependency := range steps[name].DependsOn {
+			if _, ok := steps[dependency]; !ok {
+				return fmt.Errorf("step %s of workflow %s depends on unknown step %s", name, d.Name, dependency)
+			}
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+		marks[name] = visited
+		ordered = append(ordered, steps[name])
+		return nil
+	}
+
+	for _, step := range d.Steps {
+		if err := visit(step.Name); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// Engine executes workflow definitions, persisting the progress of each run so it can be resumed after a crash.
+type Engine struct {
+	store        RunStore
+	retryBackoff time.Duration
+	now          func() time.Time
+}
+
+// NewEngine creates a new Engine instance. Failed steps are retried after retryBackoff, doubled on every attempt.
+func NewEngine(store RunStore, retryBackoff time.Duration) *Engine {
+	return &Engine{
+		store:        store,
+		retryBackoff: retryBackoff,
+		now:          time.Now,
+	}
+}
+
+// Execute runs a definition as the given run. A new run, or one that was rolled back, starts from its first step. A run
+// that failed or was interrupted resumes after its last completed step, re-running its volatile steps, and a completed
+// run is returned as is. When a step fails for good, the step's error is returned, and the completed steps are
+// compensated in reverse order unless the definition is resumable.
+func (e *Engine) Execute(ctx context.Context, definition Definition, runID string) (*Run, error) {
+	ctx = logging.WithAttrs(ctx, slog.String("workflow", definition.Name), slog.String(logging.RunIDKey, runID))
+
+	steps, err := definition.order()
+	if err != nil {
+		return nil, err
+	}
+
+	run, err := e.load(ctx, definition, runID)
+	if err != nil {
+		return nil, err
+	}
+	switch run.Status {
+	case RunStatusCompleted:
+		return run, nil
+	case RunStatusRollbackFailed:
+		return run, fmt.Errorf("workflow run %s already finished as %s: %s", runID, run.Status, run.Error)
+	case RunStatusFailed:
+		run.Status = RunStatusRunning
+		run.Error = ""
+	}
+
+	for _, step := range steps {
+		progress := run.Step(step.Name)
+		if progress.Status == StepStatusCompleted && !step.Volatile {
+			continue
+		}
+
+		if definition.Progress != nil {
+			definition.Progress(ctx, step.Name, completedPercent(run, steps, step.Name))
+		}
+		stepErr := e.runStep(ctx, run, step, progress)
+		if stepErr == nil {
+			continue
+		}
+
+		run.Error = stepErr.Error()
+		if definition.Resumable {
+			run.Status = RunStatusFailed
+		} else {
+			run.Status = RunStatusRolledBack
+			if err := e.compensate(context.WithoutCancel(ctx), run, steps); err != nil {
+				run.Status = RunStatusRollbackFailed
+				slog.ErrorContext(ctx, "failed to roll back workflow run", "error", err)
+			}
+		}
+		if err := e.save(context.WithoutCancel(ctx), run); err != nil {
+			slog.ErrorContext(ctx, "failed to save workflow run", "error", err)
+		}
+		return run, stepErr
+	}
+
+	run.Status = RunStatusCompleted
+	if err := e.save(ctx, run); err != nil {
+		return run, err
+	}
+	return run, nil
+}
+
+// Rollback compensates the completed steps of a run that failed or was interrupted and won't be resumed. Runs that
+// already finished are left alone.
+func (e *Engine) Rollback(ctx context.Context, definition Definition, runID string) (*Run, error) {
+	ctx = logging.WithAttrs(ctx, slog.String("workflow", definition.Name), slog.String(logging.RunIDKey, runID))
+
+	steps, err := definition.order()
+	if err != nil {
+		return nil, err
+	}
+
+	run, err := e.store.GetRun(ctx, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workflow run: %w", err)
+	}
+	if run == nil {
+		return nil, fmt.Errorf("workflow run %s not found", runID)
+	}
+	if run.Status.Finished() {
+		return run, nil
+	}
+
+	if run.Error == "" {
+		run.Error = "interrupted"
+	}
+	run.Status = RunStatusRolledBack
+	compensateErr := e.compensate(ctx, run, steps)
+	if compensateErr != nil {
+		run.Status = RunStatusRollbackFailed
+	}
+	if err := e.save(ctx, run); err != nil {
+		return run, err
+	}
+	return run, compensateErr
+}
+
+// Interrupted returns the runs of a workflow that are still running, oldest first. Outside of a running Execute call,
+// these are the runs a crash interrupted.
+func (e *Engine) Interrupted(ctx context.Context, workflow string) ([]*Run, error) {
+	return e.Runs(ctx, workflow, RunStatusRunning)
+}
+
+// Runs returns the runs of a workflow in the given status, or in any status when it's empty, oldest first.
+func (e *Engine) Runs(ctx context.Context, workflow string, status RunStatus) ([]*Run, error) {
+	runs, err := e.store.ListRuns(ctx, workflow, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workflow runs: %w", err)
+	}
+	return runs, nil
+}
+
+// Run returns a run, nil when it doesn't exist.
+func (e *Engine) Run(ctx context.Context, runID string) (*Run, error) {
+	run, err := e.store.GetRun(ctx, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workflow run: %w", err)
+	}
+	return run, nil
+}
+
+// MarkInterrupted fails a run a crash interrupted, keeping its completed steps so it can be resumed or rolled back
+// later. Runs that aren't running are left alone.
+func (e *Engine) MarkInterrupted(ctx context.Context, runID string) (*Run, error) {
+	run, err := e.Run(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+	if run == nil {
+		return nil, fmt.Errorf("workflow run %s not found", runID)
+	}
+	if run.Status != RunStatusRunning {
+		return run, nil
+	}
+
+	run.Status = RunStatusFailed
+	run.Error = "interrupted"
+	if err := e.save(ctx, run); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// load returns the stored run, or starts a new one, with progress for every step of the definition.
+func (e *Engine) load(ctx context.Context, definition Definition, runID string) (*Run, error) {
+	run, err := e.store.GetRun(ctx, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workflow run: %w", err)
+	}
+	// Nothing of a rolled back run is left, so it starts over
+	if run == nil || run.Status == RunStatusRolledBack {
+		run = &Run{
+			RunID:     runID,
+			Workflow:  definition.Name,
+			Status:    RunStatusRunning,
+			Values:    map[string]any{},
+			CreatedAt: e.now(),
+		}
+		for key, value := range definition.Input {
+			run.Set(key, value)
+		}
+	}
+	if run.Workflow != definition.Name {
+		return nil, fmt.Errorf("workflow run %s belongs to workflow %s, not %s", runID, run.Workflow, definition.Name)
+	}
+
+	for _, step := range definition.Steps {
+		if run.Step(step.Name) == nil {
+			run.Steps = append(run.Steps, StepProgress{Name: step.Name, Status: StepStatusPending})
+		}
+	}
+
+	if err := e.save(ctx, run); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// runStep attempts a step until it completes or runs out of retries, saving the run after every attempt.
+func (e *Engine) runStep(ctx context.Context, run *Run, step Step, progress *StepProgress) error {
+	backoff := e.retryBackoff
+	for attempt := 0; ; attempt++ {
+		progress.Attempts++
+		err := step.Run(ctx, run)
+		if err == nil {
+			progress.Status = StepStatusCompleted
+			progress.Error = ""
+			return e.save(ctx, run)
+		}
+
+		progress.Status = StepStatusFailed
+		progress.Error = err.Error()
+		if saveErr := e.save(ctx, run); saveErr != nil {
+			slog.ErrorContext(ctx, "failed to save workflow run", "error", saveErr)
+		}
+		if attempt >= step.Retries || ctx.Err() != nil {
+			return err
+		}
+
+		slog.WarnContext(ctx, "workflow step failed, retrying", "step", step.Name, "attempt", progress.Attempts, "error", err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// completedPercent returns the percentage of the steps, other than the one about to run, that the run completed.
+func completedPercent(run *Run, steps []Step, current string) int {
+	completed := 0
+	for _, step := range steps {
+		if step.Name != current && run.Step(step.Name).Status == StepStatusCompleted {
+			completed++
+		}
+	}
+	return completed * 100 / len(steps)
+}
+
+// compensate rolls back the completed steps of a run in reverse order, attempting every compensation even when one
+// fails.
+func (e *Engine) compensate(ctx context.Context, run *Run, steps []Step) error {
+	var errs []error
+	for i := len(steps) - 1; i >= 0; i-- {
+		step := steps[i]
+		// Runs started before a step was added to the definition don't know it and never ran it
+		progress := run.Step(step.Name)
+		if progress == nil || progress.Status != StepStatusCompleted || step.Compensate == nil {
+			continue
+		}
+
+		slog.InfoContext(ctx, "compensating workflow step", "step", step.Name)
+		if err := step.Compensate(ctx, run); err != nil {
+			errs = append(errs, fmt.Errorf("failed to compensate step %s: %w", step.Name, err))
+			continue
+		}
+		progress.Status = StepStatusCompensated
+	}
+	return errors.Join(errs...)
+}
+
+// save persists the run.
+func (e *Engine) save(ctx context.Context, run *Run) error {
+	run.UpdatedAt = e.now()
+	if err := e.store.SaveRun(ctx, run); err != nil {
+		return fmt.Errorf("failed to save workflow run: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,278 @@
+package workflow_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/workflow"
+)
+
+func newTestEngine() *workflow.Engine {
+	return workflow.NewEngine(workflow.NewMemoryRunStore(), 0)
+}
+
+// recordingStep returns a step that appends its name to calls and fails while failures is positive
+func recordingStep(name string, calls *[]string, failures *int, dependsOn ...string) workflow.Step {
+	return workflow.Step{
+		Name:      name,
+		DependsOn: dependsOn,
+		Run: func(_ context.Context, run *workflow.Run) error {
+			*calls = append(*calls, name)
+			if failures != nil && *failures > 0 {
+				*failures--
+				return errors.New(name + " failed")
+			}
+			run.Set(name, "done")
+			return nil
+		},
+		Compensate: func(_ context.Context, _ *workflow.Run) error {
+			*calls = append(*calls, "undo "+name)
+			return nil
+		},
+	}
+}
+
+func TestEngine_Execute_RunsStepsInDependencyOrder(t *testing.T) {
+	var calls []string
+	definition := workflow.Definition{
+		Name: "test",
+		Steps: []workflow.Step{
+			recordingStep("publish", &calls, nil, "build", "test"),
+			recordingStep("test", &calls, nil, "build"),
+			recordingStep("build", &calls, nil),
+		},
+	}
+
+	run, err := newTestEngine().Execute(context.Background(), definition, "run-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"build", "test", "publish"}, calls)
+	assert.Equal(t, workflow.RunStatusCompleted, run.Status)
+	assert.Equal(t, "done", run.String("publish"))
+}
+
+func TestEngine_Execute_RejectsDependencyCycles(t *testing.T) {
+	var calls []string
+	definition := workflow.Definition{
+		Name: "test",
+		Steps: []workflow.Step{
+			recordingStep("a", &calls, nil, "b"),
+			recordingStep("b", &calls, nil, "a"),
+		},
+	}
+
+	_, err := newTestEngine().Execute(context.Background(), definition, "run-1")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "dependency cycle")
+	assert.Empty(t, calls)
+}
+
+func TestEngine_Execute_RetriesFailedSteps(t *testing.T) {
+	var calls []string
+	failures := 2
+	step := recordingStep("flaky", &calls, &failures)
+	step.Retries = 2
+
+	run, err := newTestEngine().Execute(context.Background(), workflow.Definition{Name: "test", Steps: []workflow.Step{step}}, "run-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"flaky", "flaky", "flaky"}, calls)
+	assert.Equal(t, 3, run.Step("flaky").Attempts)
+}
+
+func TestEngine_Execute_CompensatesCompletedStepsInReverse(t *testing.T) {
+	var calls []string
+	failures := 1
+	definition := workflow.Definition{
+		Name: "test",
+		Steps: []workflow.Step{
+			recordingStep("first", &calls, nil),
+			recordingStep("second", &calls, nil, "first"),
+			recordingStep("third", &calls, &failures, "second"),
+		},
+	}
+
+	run, err := newTestEngine().Execute(context.Background(), definition, "run-1")
+
+	require.EqualError(t, err, "third failed")
+	assert.Equal(t, []string{"first", "second", "third", "undo second", "undo first"}, calls)
+	assert.Equal(t, workflow.RunStatusRolledBack, run.Status)
+	assert.Equal(t, workflow.StepStatusCompensated, run.Step("first").Status)
+	assert.Equal(t, workflow.StepStatusFailed, run.Step("third").Status)
+}
+
+func TestEngine_Execute_ResumesInterruptedRun(t *testing.T) {
+	store := workflow.NewMemoryRunStore()
+	engine := workflow.NewEngine(store, 0)
+
+	// A crash interrupted the run after its first two steps completed
+	require.NoError(t, store.SaveRun(context.Background(), &workflow.Run{
+		RunID:    "run-1",
+		Workflow: "test",
+		Status:   workflow.RunStatusRunning,
+		Steps: []workflow.StepProgress{
+			{Name: "clone", Status: workflow.StepStatusCompleted, Attempts: 1},
+			{Name: "build", Status: workflow.StepStatusCompleted, Attempts: 1},
+			{Name: "publish", Status: workflow.StepStatusPending},
+		},
+		Values: map[string]any{"build": "done"},
+	}))
+
+	var calls []string
+	clone := recordingStep("clone", &calls, nil)
+	clone.Volatile = true
+	definition := workflow.Definition{
+		Name: "test",
+		Steps: []workflow.Step{
+			clone,
+			recordingStep("build", &calls, nil, "clone"),
+			recordingStep("publish", &calls, nil, "build"),
+		},
+	}
+
+	interrupted, err := engine.Interrupted(context.Background(), "test")
+	require.NoError(t, err)
+	require.Len(t, interrupted, 1)
+
+	run, err := engine.Execute(context.Background(), definition, interrupted[0].RunID)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"clone", "publish"}, calls)
+	assert.Equal(t, workflow.RunStatusCompleted, run.Status)
+
+	interrupted, err = engine.Interrupted(context.Background(), "test")
+	require.NoError(t, err)
+	assert.Empty(t, interrupted)
+}
+
+func TestEngine_Execute_ReportsProgressOfResumedRun(t *testing.T) {
+	store := workflow.NewMemoryRunStore()
+	engine := workflow.NewEngine(store, 0)
+
+	require.NoError(t, store.SaveRun(context.Background(), &workflow.Run{
+		RunID:    "run-1",
+		Workflow: "test",
+		Status:   workflow.RunStatusRunning,
+		Steps: []workflow.StepProgress{
+			{Name: "clone", Status: workflow.StepStatusCompleted, Attempts: 1},
+			{Name: "build", Status: workflow.StepStatusCompleted, Attempts: 1},
+			{Name: "test", Status: workflow.StepStatusPending},
+			{Name: "publish", Status: workflow.StepStatusPending},
+		},
+	}))
+
+	var calls, reports []string
+	clone := recordingStep("clone", &calls, nil)
+	clone.Volatile = true
+	definition := workflow.Definition{
+		Name: "test",
+		Steps: []workflow.Step{
+			clone,
+			recordingStep("build", &calls, nil, "clone"),
+			recordingStep("test", &calls, nil, "build"),
+			recordingStep("publish", &calls, nil, "test"),
+		},
+		Progress: func(_ context.Context, step string, percent int) {
+			reports = append(reports, fmt.Sprintf("%s %d", step, percent))
+		},
+	}
+
+	_, err := engine.Execute(context.Background(), definition, "run-1")
+
+	require.NoError(t, err)
+	// The volatile clone runs again, but the completed build still counts
+	assert.Equal(t, []string{"clone 25", "test 50", "publish 75"}, reports)
+}
+
+func TestEngine_Rollback_CompensatesInterruptedRun(t *testing.T) {
+	store := workflow.NewMemoryRunStore()
+	engine := workflow.NewEngine(store, 0)
+
+	require.NoError(t, store.SaveRun(context.Background(), &workflow.Run{
+		RunID:    "run-1",
+		Workflow: "test",
+		Status:   workflow.RunStatusRunning,
+		Steps: []workflow.StepProgress{
+			{Name: "build", Status: workflow.StepStatusCompleted, Attempts: 1},
+			{Name: "publish", Status: workflow.StepStatusPending},
+		},
+	}))
+
+	var calls []string
+	definition := workflow.Definition{
+		Name: "test",
+		Steps: []workflow.Step{
+			recordingStep("build", &calls, nil),
+			recordingStep("publish", &calls, nil, "build"),
+		},
+	}
+
+	run, err := engine.Rollback(context.Background(), definition, "run-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"undo build"}, calls)
+	assert.Equal(t, workflow.RunStatusRolledBack, run.Status)
+}
+
+func TestEngine_Rollback_SkipsStepsAddedAfterRunStarted(t *testing.T) {
+	store := workflow.NewMemoryRunStore()
+	engine := workflow.NewEngine(store, 0)
+
+	// The run was interrupted before the definition gained its verify step
+	require.NoError(t, store.SaveRun(context.Background(), &workflow.Run{
+		RunID:    "run-1",
+		Workflow: "test",
+		Status:   workflow.RunStatusRunning,
+		Steps: []workflow.StepProgress{
+			{Name: "build", Status: workflow.StepStatusCompleted, Attempts: 1},
+		},
+	}))
+
+	var calls []string
+	definition := workflow.Definition{
+		Name: "test",
+		Steps: []workflow.Step{
+			recordingStep("build", &calls, nil),
+			recordingStep("verify", &calls, nil, "build"),
+		},
+	}
+
+	run, err := engine.Rollback(context.Background(), definition, "run-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"undo build"}, calls)
+	assert.Equal(t, workflow.RunStatusRolledBack, run.Status)
+}
+
+func TestEngine_Execute_ResumableRunKeepsCompletedSteps(t *testing.T) {
+	engine := newTestEngine()
+	var calls []string
+	failures := 1
+	definition := workflow.Definition{
+		Name:      "test",
+		Input:     map[string]any{"request": "build it"},
+		Resumable: true,
+		Steps: []workflow.Step{
+			recordingStep("build", &calls, nil),
+			recordingStep("publish", &calls, &failures, "build"),
+		},
+	}
+
+	run, err := engine.Execute(context.Background(), definition, "run-1")
+	require.EqualError(t, err, "publish failed")
+	assert.Equal(t, workflow.RunStatusFailed, run.Status)
+	assert.Equal(t, workflow.StepStatusCompleted, run.Step("build").Status)
+
+	run, err = engine.Execute(context.Background(), definition, "run-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"build", "publish", "publish"}, calls)
+	assert.Equal(t, workflow.RunStatusCompleted, run.Status)
+	assert.Equal(t, "build it", run.String("request"))
+}
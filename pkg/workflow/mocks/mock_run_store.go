@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/kazemisoroush/code-refactoring-tool/pkg/workflow (interfaces: RunStore)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	workflow "github.com/kazemisoroush/code-refactoring-tool/pkg/workflow"
+)
+
+// MockRunStore is a mock of RunStore interface.
+type MockRunStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockRunStoreMockRecorder
+}
+
+// MockRunStoreMockRecorder is the mock recorder for MockRunStore.
+type MockRunStoreMockRecorder struct {
+	mock *MockRunStore
+}
+
+// NewMockRunStore creates a new mock instance.
+func NewMockRunStore(ctrl *gomock.Controller) *MockRunStore {
+	mock := &MockRunStore{ctrl: ctrl}
+	mock.recorder = &MockRunStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRunStore) EXPECT() *MockRunStoreMockRecorder {
+	return m.recorder
+}
+
+// GetRun mocks base method.
+func (m *MockRunStore) GetRun(arg0 context.Context, arg1 string) (*workflow.Run, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRun", arg0, arg1)
+	ret0, _ := ret[0].(*workflow.Run)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRun indicates an expected call of GetRun.
+func (mr *MockRunStoreMockRecorder) GetRun(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRun", reflect.TypeOf((*MockRunStore)(nil).GetRun), arg0, arg1)
+}
+
+// ListRuns mocks base method.
+func (m *MockRunStore) ListRuns(arg0 context.Context, arg1 string, arg2 workflow.RunStatus) ([]*workflow.Run, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRuns", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*workflow.Run)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRuns indicates an expected call of ListRuns.
+func (mr *MockRunStoreMockRecorder) ListRuns(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRuns", reflect.TypeOf((*MockRunStore)(nil).ListRuns), arg0, arg1, arg2)
+}
+
+// SaveRun mocks base method.
+func (m *MockRunStore) SaveRun(arg0 context.Context, arg1 *workflow.Run) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveRun", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveRun indicates an expected call of SaveRun.
+func (mr *MockRunStoreMockRecorder) SaveRun(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRun", reflect.TypeOf((*MockRunStore)(nil).SaveRun), arg0, arg1)
+}
@@ -0,0 +1,201 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// RunStatus is the state of a workflow run.
+type RunStatus string
+
+const (
+	// RunStatusRunning means the run hasn't finished, either because it's executing or because it was interrupted.
+	RunStatusRunning RunStatus = "running"
+
+	// RunStatusCompleted means every step of the run completed.
+	RunStatusCompleted RunStatus = "completed"
+
+	// RunStatusFailed means a step of a resumable run failed, or the run was interrupted, and its completed steps were
+	// kept. Executing the run again resumes it.
+	RunStatusFailed RunStatus = "failed"
+
+	// RunStatusRolledBack means a step failed and the completed steps before it were compensated.
+	RunStatusRolledBack RunStatus = "rolled_back"
+
+	// RunStatusRollbackFailed means compensating the completed steps failed, so resources may be left behind.
+	RunStatusRollbackFailed RunStatus = "rollback_failed"
+)
+
+// Finished reports whether a run in this state can no longer be executed or rolled back.
+func (s RunStatus) Finished() bool {
+	return s == RunStatusCompleted || s == RunStatusRolledBack || s == RunStatusRollbackFailed
+}
+
+// StepStatus is the state of a step within a workflow run.
+type StepStatus string
+
+const (
+	// StepStatusPending means the step hasn't completed yet.
+	StepStatusPending StepStatus = "pending"
+
+	// StepStatusCompleted means the step completed.
+	StepStatusCompleted StepStatus = "completed"
+
+	// StepStatusFailed means the step failed on its last attempt.
+	StepStatusFailed StepStatus = "failed"
+
+	// StepStatusCompensated means the step completed and was then rolled back.
+	StepStatusCompensated StepStatus = "compensated"
+)
+
+// StepProgress records how far a step of a run got.
+type StepProgress struct {
+	Name     string     `json:"name"`
+	Status   StepStatus `json:"status"`
+	Attempts int        `json:"attempts"`
+	Error    string     `json:"error,omitempty"`
+}
+
+// Run is the persisted progress of one execution of a workflow definition.
+type Run struct {
+	RunID    string         `json:"run_id"`
+	Workflow string         `json:"workflow"`
+	Status   RunStatus      `json:"status"`
+	Steps    []StepProgress `json:"steps"`
+	// Values are the outputs steps hand to later steps and compensations. They are persisted as JSON, so a resumed
+	// run sees them decoded into JSON types.
+	Values    map[string]any `json:"values"`
+	Error     string         `json:"error,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// Set stores a value for later steps and compensations.
+func (r *Run) Set(key string, value any) {
+	if r.Values == nil {
+		r.Values = map[string]any{}
+	}
+	r.Values[key] = value
+}
+
+// Get returns a stored value, nil when no step stored it.
+func (r *Run) Get(key string) any {
+	return r.Values[key]
+}
+
+// String returns a stored string value, empty when no step stored it.
+func (r *Run) String(key string) string {
+	value, _ := r.Values[key].(string)
+	return value
+}
+
+// Decode copies a stored value into target, which must be a pointer, whether the value is still the Go value a step
+// stored or the JSON types a resumed run sees. It reports whether a value was stored.
+func (r *Run) Decode(key string, target any) (bool, error) {
+	value, ok := r.Values[key]
+	if !ok || value == nil {
+		return false, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode run value %s: %w", key, err)
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return false, fmt.Errorf("failed to decode run value %s: %w", key, err)
+	}
+	return true, nil
+}
+
+// Step returns the progress of the named step, nil when the run doesn't know the step.
+func (r *Run) Step(name string) *StepProgress {
+	for i := range r.Steps {
+		if r.Steps[i].Name == name {
+			return &r.Steps[i]
+		}
+	}
+	return nil
+}
+
+// RunStore persists the progress of workflow runs so interrupted runs can be resumed or rolled back.
+//
+//go:generate mockgen -destination=./mocks/mock_run_store.go -mock_names=RunStore=MockRunStore -package=mocks . RunStore
+type RunStore interface {
+	// GetRun retrieves a run, returning nil if it was never saved.
+	GetRun(ctx context.Context, runID string) (*Run, error)
+
+	// SaveRun creates or replaces a run.
+	SaveRun(ctx context.Context, run *Run) error
+
+	// ListRuns returns the runs of a workflow in the given status, or in any status when it's empty, oldest first.
+	ListRuns(ctx context.Context, workflow string, status RunStatus) ([]*Run, error)
+}
+
+// MemoryRunStore implements RunStore in memory. Its runs don't survive a restart.
+type MemoryRunStore struct {
+	mu   sync.Mutex
+	runs map[string]*Run
+}
+
+// NewMemoryRunStore creates a new MemoryRunStore instance.
+func NewMemoryRunStore() *MemoryRunStore {
+	return &MemoryRunStore{runs: map[string]*Run{}}
+}
+
+// GetRun implements RunStore.
+func (s *MemoryRunStore) GetRun(_ context.Context, runID string) (*Run, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	run, ok := s.runs[runID]
+	if !ok {
+		return nil, nil
+	}
+	return cloneRun(run), nil
+}
+
+// SaveRun implements RunStore.
+func (s *MemoryRunStore) SaveRun(_ context.Context, run *Run) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.runs[run.RunID] = cloneRun(run)
+	return nil
+}
+
+// ListRuns implements RunStore.
+func (s *MemoryRunStore) ListRuns(_ context.Context, workflow string, status RunStatus) ([]*Run, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	runs := []*Run{}
+	for _, run := range s.runs {
+		if run.Workflow == workflow && (status == "" || run.Status == status) {
+			runs = append(runs, cloneRun(run))
+		}
+	}
+	sortRuns(runs)
+	return runs, nil
+}
+
+// cloneRun copies a run so callers can't change the stored one. Values are copied shallowly.
+func cloneRun(run *Run) *Run {
+	clone := *run
+	clone.Steps = append([]StepProgress(nil), run.Steps...)
+	clone.Values = make(map[string]any, len(run.Values))
+	for key, value := range run.Values {
+		clone.Values[key] = value
+	}
+	return &clone
+}
+
+// sortRuns orders runs oldest first.
+func sortRuns(runs []*Run) {
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].CreatedAt.Before(runs[j].CreatedAt)
+	})
+}
@@ -0,0 +1,91 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/ai/builder"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/codebase"
+)
+
+// Setup identifies a run of a setup workflow.
+type Setup struct {
+	// RunID names the run. Running a setup with the ID of a failed run resumes that run.
+	RunID string
+
+	// Input is stored with a new run, such as the request the setup serves.
+	Input map[string]any
+
+	// RepositoryURL is the repository the setup builds from. The location of an uploaded archive, such as
+	// s3://bucket/key, builds from the archive, and other URLs from the configured git repository.
+	RepositoryURL string
+}
+
+// cloneRetries is how many more times a failed clone is attempted, as clones mostly fail on the network.
+const cloneRetries = 2
+
+// cloneStep clones the repository the setup builds from. The clone lives on local disk, so a resumed run clones again.
+func cloneStep(repository codebase.Codebase) Step {
+	return Step{
+		Name:     "clone",
+		Retries:  cloneRetries,
+		Volatile: true,
+		Run: func(ctx context.Context, _ *Run) error {
+			slog.InfoContext(ctx, "Cloning repository for setup")
+			if err := repository.Clone(ctx); err != nil {
+				return fmt.Errorf("failed to clone repository: %w", err)
+			}
+			slog.InfoContext(ctx, "Repository cloned successfully")
+			return nil
+		},
+	}
+}
+
+// buildRAGStep builds the RAG pipeline once the step it depends on completed, and tears it down on rollback.
+func buildRAGStep(provider, dependsOn string, ragBuilder builder.RAGBuilder) Step {
+	return Step{
+		Name:      "build_rag",
+		DependsOn: []string{dependsOn},
+		Run: func(ctx context.Context, run *Run) error {
+			slog.InfoContext(ctx, "Building RAG pipeline", "provider", provider)
+			ragID, err := ragBuilder.Build(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to build %s RAG pipeline: %w", provider, err)
+			}
+			run.Set("rag_id", ragID)
+			slog.InfoContext(ctx, "RAG pipeline built successfully", "provider", provider, "ragID", ragID)
+			return nil
+		},
+		Compensate: func(ctx context.Context, run *Run) error {
+			ragID := run.String("rag_id")
+			slog.InfoContext(ctx, "Tearing down RAG pipeline", "provider", provider, "ragID", ragID)
+			return ragBuilder.TearDown(ctx, ragID, ragID)
+		},
+	}
+}
+
+// buildAgentStep builds the agent on the RAG pipeline, and tears it down on rollback.
+func buildAgentStep(provider string, agentBuilder builder.AgentBuilder) Step {
+	return Step{
+		Name:      "build_agent",
+		DependsOn: []string{"build_rag"},
+		Run: func(ctx context.Context, run *Run) error {
+			ragID := run.String("rag_id")
+			slog.InfoContext(ctx, "Building agent", "provider", provider, "ragID", ragID)
+			agentID, agentVersion, err := agentBuilder.Build(ctx, ragID)
+			if err != nil {
+				return fmt.Errorf("failed to build %s agent: %w", provider, err)
+			}
+			run.Set("agent_id", agentID)
+			run.Set("agent_version", agentVersion)
+			slog.InfoContext(ctx, "Agent built successfully", "provider", provider, "agentID", agentID, "version", agentVersion)
+			return nil
+		},
+		Compensate: func(ctx context.Context, run *Run) error {
+			agentID := run.String("agent_id")
+			slog.InfoContext(ctx, "Tearing down agent", "provider", provider, "agentID", agentID)
+			return agentBuilder.TearDown(ctx, agentID, run.String("agent_version"), run.String("rag_id"))
+		},
+	}
+}
@@ -64,12 +64,12 @@ func (t *TeardownBedrockSetupWorkflow) SetResourceIDs(vectorStoreID, ragID, agen
 
 // Run implements Workflow for tearing down Bedrock resources.
 func (t *TeardownBedrockSetupWorkflow) Run(ctx context.Context) error {
-	slog.Info("Running Bedrock teardown workflow")
+	slog.InfoContext(ctx, "Running Bedrock teardown workflow")
 
 	defer func() {
 		err := t.repository.Cleanup()
 		if err != nil {
-			slog.Error("failed to cleanup repository", "error", err)
+			slog.ErrorContext(ctx, "failed to cleanup repository", "error", err)
 		}
 	}()
 
@@ -78,36 +78,36 @@ func (t *TeardownBedrockSetupWorkflow) Run(ctx context.Context) error {
 
 	// 1. Tear down the Bedrock agent if we have the IDs
 	if t.agentID != "" && t.ragID != "" {
-		slog.Info("Tearing down Bedrock agent", "agentID", t.agentID)
+		slog.InfoContext(ctx, "Tearing down Bedrock agent", "agentID", t.agentID)
 		if err := t.agentBuilder.TearDown(ctx, t.agentID, t.agentVersion, t.ragID); err != nil {
 			teardownErrors = append(teardownErrors, fmt.Errorf("failed to tear down Bedrock agent: %w", err))
-			slog.Error("Failed to tear down Bedrock agent", "error", err)
+			slog.ErrorContext(ctx, "Failed to tear down Bedrock agent", "error", err)
 		} else {
-			slog.Info("Bedrock agent torn down successfully")
+			slog.InfoContext(ctx, "Bedrock agent torn down successfully")
 		}
 	}
 
 	// 2. Tear down the Bedrock RAG pipeline if we have the IDs
 	if t.vectorStoreID != "" && t.ragID != "" {
-		slog.Info("Tearing down Bedrock RAG pipeline", "ragID", t.ragID)
+		slog.InfoContext(ctx, "Tearing down Bedrock RAG pipeline", "ragID", t.ragID)
 		if err := t.ragBuilder.TearDown(ctx, t.vectorStoreID, t.ragID); err != nil {
 			teardownErrors = append(teardownErrors, fmt.Errorf("failed to tear down Bedrock RAG pipeline: %w", err))
-			slog.Error("Failed to tear down Bedrock RAG pipeline", "error", err)
+			slog.ErrorContext(ctx, "Failed to tear down Bedrock RAG pipeline", "error", err)
 		} else {
-			slog.Info("Bedrock RAG pipeline torn down successfully")
+			slog.InfoContext(ctx, "Bedrock RAG pipeline torn down successfully")
 		}
 	}
 
 	// Report any teardown errors
 	if len(teardownErrors) > 0 {
-		slog.Error("Bedrock teardown completed with errors", "errorCount", len(teardownErrors))
+		slog.ErrorContext(ctx, "Bedrock teardown completed with errors", "errorCount", len(teardownErrors))
 		// Return the first error, but log all errors
 		for i, err := range teardownErrors {
-			slog.Error("Bedrock teardown error", "index", i+1, "error", err)
+			slog.ErrorContext(ctx, "Bedrock teardown error", "index", i+1, "error", err)
 		}
 		return teardownErrors[0]
 	}
 
-	slog.Info("Bedrock teardown workflow completed successfully")
+	slog.InfoContext(ctx, "Bedrock teardown workflow completed successfully")
 	return nil
 }
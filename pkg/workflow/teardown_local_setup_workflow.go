@@ -64,12 +64,12 @@ func (t *TeardownLocalSetupWorkflow) SetResourceIDs(vectorStoreID, ragID, agentI
 
 // Run implements Workflow for tearing down local resources.
 func (t *TeardownLocalSetupWorkflow) Run(ctx context.Context) error {
-	slog.Info("Running local teardown workflow")
+	slog.InfoContext(ctx, "Running local teardown workflow")
 
 	defer func() {
 		err := t.repository.Cleanup()
 		if err != nil {
-			slog.Error("failed to cleanup repository", "error", err)
+			slog.ErrorContext(ctx, "failed to cleanup repository", "error", err)
 		}
 	}()
 
@@ -78,36 +78,36 @@ func (t *TeardownLocalSetupWorkflow) Run(ctx context.Context) error {
 
 	// 1. Tear down the local agent if we have the IDs
 	if t.agentID != "" && t.ragID != "" {
-		slog.Info("Tearing down local agent", "agentID", t.agentID)
+		slog.InfoContext(ctx, "Tearing down local agent", "agentID", t.agentID)
 		if err := t.agentBuilder.TearDown(ctx, t.agentID, t.agentVersion, t.ragID); err != nil {
 			teardownErrors = append(teardownErrors, fmt.Errorf("failed to tear down local agent: %w", err))
-			slog.Error("Failed to tear down local agent", "error", err)
+			slog.ErrorContext(ctx, "Failed to tear down local agent", "error", err)
 		} else {
-			slog.Info("Local agent torn down successfully")
+			slog.InfoContext(ctx, "Local agent torn down successfully")
 		}
 	}
 
 	// 2. Tear down the local RAG pipeline if we have the IDs
 	if t.vectorStoreID != "" && t.ragID != "" {
-		slog.Info("Tearing down local RAG pipeline", "ragID", t.ragID)
+		slog.InfoContext(ctx, "Tearing down local RAG pipeline", "ragID", t.ragID)
 		if err := t.ragBuilder.TearDown(ctx, t.vectorStoreID, t.ragID); err != nil {
 			teardownErrors = append(teardownErrors, fmt.Errorf("failed to tear down local RAG pipeline: %w", err))
-			slog.Error("Failed to tear down local RAG pipeline", "error", err)
+			slog.ErrorContext(ctx, "Failed to tear down local RAG pipeline", "error", err)
 		} else {
-			slog.Info("Local RAG pipeline torn down successfully")
+			slog.InfoContext(ctx, "Local RAG pipeline torn down successfully")
 		}
 	}
 
 	// Report any teardown errors
 	if len(teardownErrors) > 0 {
-		slog.Error("Local teardown completed with errors", "errorCount", len(teardownErrors))
+		slog.ErrorContext(ctx, "Local teardown completed with errors", "errorCount", len(teardownErrors))
 		// Return the first error, but log all errors
 		for i, err := range teardownErrors {
-			slog.Error("Local teardown error", "index", i+1, "error", err)
+			slog.ErrorContext(ctx, "Local teardown error", "index", i+1, "error", err)
 		}
 		return teardownErrors[0]
 	}
 
-	slog.Info("Local teardown workflow completed successfully")
+	slog.InfoContext(ctx, "Local teardown workflow completed successfully")
 	return nil
 }
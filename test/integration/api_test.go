@@ -0,0 +1,400 @@
+//go:build integration
+
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/apperrors"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+)
+
+func TestProjectCodebaseTask_HappyPath(t *testing.T) {
+	projectID := createProject(t, "payments")
+	status, body := call(t, developerToken, http.MethodGet, "/api/v1/projects/"+projectID, nil)
+	require.Equal(t, http.StatusOK, status, string(body))
+	assert.Equal(t, "payments", decode[models.GetProjectResponse](t, body).Name)
+
+	codebaseID := createCodebase(t, projectID)
+	status, body = call(t, developerToken, http.MethodGet, "/api/v1/codebases/"+codebaseID, nil)
+	require.Equal(t, http.StatusOK, status, string(body))
+	assert.Equal(t, projectID, decode[models.GetCodebaseResponse](t, body).ProjectID)
+
+	upload := uploadTaskInput(t, projectID, "change.diff", "--- a/main.go\n+++ b/main.go\n")
+	assert.Equal(t, "s3://"+uploadsBucket+"/task-inputs/"+projectID+"/"+upload.UploadID, upload.URI)
+
+	status, body = call(t, developerToken, http.MethodPost, "/api/v1/projects/"+projectID+"/tasks", models.CreateTaskRequest{
+		ProjectID:   projectID,
+		AgentID:     readyAgentID,
+		CodebaseID:  &codebaseID,
+		Type:        models.TaskTypeRefactoring,
+		Title:       "Apply the diff",
+		Description: "Apply the uploaded diff",
+		Input:       map[string]any{models.TaskInputUploadIDKey: upload.UploadID},
+	})
+	require.Equal(t, http.StatusCreated, status, string(body))
+	created := decode[models.CreateTaskResponse](t, body)
+	assert.Equal(t, models.TaskStatusPending, created.Status)
+
+	status, body = call(t, developerToken, http.MethodPost, "/api/v1/projects/"+projectID+"/tasks/execute", models.ExecuteTaskRequest{
+		ProjectID:   projectID,
+		AgentID:     readyAgentID,
+		CodebaseID:  &codebaseID,
+		Type:        models.TaskTypeCodeReview,
+		Title:       "Review the codebase",
+		Description: "Review the codebase",
+	})
+	require.Equal(t, http.StatusOK, status, string(body))
+	executed := decode[models.ExecuteTaskResponse](t, body)
+	assert.Equal(t, models.TaskStatusCompleted, executed.Status)
+	assert.Equal(t, "executed Review the codebase", executed.Output["summary"])
+	assert.Equal(t, "fake", executed.Output["executor"])
+
+	status, body = call(t, developerToken, http.MethodGet, "/api/v1/tasks/"+executed.TaskID, nil)
+	require.Equal(t, http.StatusOK, status, string(body))
+	task := decode[models.GetTaskResponse](t, body)
+	assert.Equal(t, models.TaskStatusCompleted, task.Status)
+	assert.Equal(t, "executed Review the codebase", task.Output["summary"])
+
+	status, body = call(t, developerToken, http.MethodGet, "/api/v1/projects/"+projectID+"/tasks", nil)
+	require.Equal(t, http.StatusOK, status, string(body))
+	tasks := decode[models.ListTasksResponse](t, body)
+	assert.Equal(t, 2, tasks.TotalCount)
+	assert.ElementsMatch(t, []string{created.TaskID, executed.TaskID}, taskIDs(tasks.Tasks))
+}
+
+func TestTask_ExecutesWithUploadedInput(t *testing.T) {
+	projectID := createProject(t, "uploads")
+	upload := uploadTaskInput(t, projectID, "requirements.txt", "requests==2.31.0\n")
+
+	status, body := call(t, developerToken, http.MethodPost, "/api/v1/projects/"+projectID+"/tasks/execute", models.ExecuteTaskRequest{
+		ProjectID:   projectID,
+		AgentID:     readyAgentID,
+		Type:        models.TaskTypeCustom,
+		Title:       "Pin the requirements",
+		Description: "Pin the uploaded requirements",
+		Input:       map[string]any{models.TaskInputUploadIDKey: upload.UploadID},
+	})
+
+	require.Equal(t, http.StatusOK, status, string(body))
+	executed := decode[models.ExecuteTaskResponse](t, body)
+	given, ok := executed.Output[models.TaskInputUploadKey].(map[string]any)
+	require.True(t, ok, "executor wasn't given the upload: %v", executed.Output)
+	assert.Equal(t, "requirements.txt", given["filename"])
+	assert.Equal(t, upload.URI, given["uri"])
+	assert.EqualValues(t, len("requests==2.31.0\n"), given["size"])
+}
+
+func TestProject_Errors(t *testing.T) {
+	projectID := createProject(t, "errors")
+
+	tests := []struct {
+		name   string
+		token  string
+		method string
+		path   string
+		body   any
+		status int
+		code   string
+	}{
+		{
+			name:   "unauthenticated",
+			method: http.MethodGet,
+			path:   "/api/v1/projects/" + projectID,
+			status: http.StatusUnauthorized,
+			code:   apperrors.CodeUnauthorized,
+		},
+		{
+			name:   "viewer creating a project",
+			token:  viewerToken,
+			method: http.MethodPost,
+			path:   "/api/v1/projects",
+			body:   models.CreateProjectRequest{Name: "not allowed"},
+			status: http.StatusForbidden,
+			code:   apperrors.CodePermissionDenied,
+		},
+		{
+			name:   "project without a name",
+			token:  developerToken,
+			method: http.MethodPost,
+			path:   "/api/v1/projects",
+			body:   map[string]any{"description": "no name"},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "unknown project",
+			token:  developerToken,
+			method: http.MethodGet,
+			path:   "/api/v1/projects/proj-" + uuid.NewString(),
+			status: http.StatusNotFound,
+			code:   apperrors.CodeProjectNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := call(t, tt.token, tt.method, tt.path, tt.body)
+			assertProblem(t, tt.status, tt.code, status, body)
+		})
+	}
+}
+
+func TestCodebase_Errors(t *testing.T) {
+	projectID := createProject(t, "codebase-errors")
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   any
+		status int
+		code   string
+	}{
+		{
+			name:   "codebase of an unknown project",
+			method: http.MethodPost,
+			path:   "/api/v1/projects/proj-" + uuid.NewString() + "/codebases",
+			body:   codebaseRequest(),
+			status: http.StatusNotFound,
+			code:   apperrors.CodeProjectNotFound,
+		},
+		{
+			name:   "unsupported provider",
+			method: http.MethodPost,
+			path:   "/api/v1/projects/" + projectID + "/codebases",
+			body:   map[string]any{"name": "repo", "provider": "sourceforge", "url": "https://sourceforge.net/p/repo", "config_id": "config-1"},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "unknown codebase",
+			method: http.MethodGet,
+			path:   "/api/v1/codebases/" + uuid.NewString(),
+			status: http.StatusNotFound,
+			code:   apperrors.CodeCodebaseNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := call(t, developerToken, tt.method, tt.path, tt.body)
+			assertProblem(t, tt.status, tt.code, status, body)
+		})
+	}
+}
+
+func TestTask_Errors(t *testing.T) {
+	projectID := createProject(t, "task-errors")
+	unknownCodebaseID := uuid.NewString()
+
+	tests := []struct {
+		name    string
+		token   string
+		request models.CreateTaskRequest
+		status  int
+		code    string
+	}{
+		{
+			name:    "unknown codebase",
+			token:   developerToken,
+			request: taskRequest(projectID, readyAgentID, &unknownCodebaseID, nil),
+			status:  http.StatusBadRequest,
+			code:    apperrors.CodeCodebaseNotFound,
+		},
+		{
+			name:    "unknown upload",
+			token:   developerToken,
+			request: taskRequest(projectID, readyAgentID, nil, map[string]any{models.TaskInputUploadIDKey: "upl-" + uuid.NewString()}),
+			status:  http.StatusNotFound,
+			code:    apperrors.CodeUploadNotFound,
+		},
+		{
+			name:    "viewer creating a task",
+			token:   viewerToken,
+			request: taskRequest(projectID, readyAgentID, nil, nil),
+			status:  http.StatusForbidden,
+			code:    apperrors.CodePermissionDenied,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := call(t, tt.token, http.MethodPost, "/api/v1/projects/"+projectID+"/tasks", tt.request)
+			assertProblem(t, tt.status, tt.code, status, body)
+		})
+	}
+
+	status, body := call(t, developerToken, http.MethodGet, "/api/v1/projects/"+projectID+"/tasks", nil)
+	require.Equal(t, http.StatusOK, status, string(body))
+	assert.Zero(t, decode[models.ListTasksResponse](t, body).TotalCount)
+}
+
+func TestTask_FailedExecutions(t *testing.T) {
+	tests := []struct {
+		name    string
+		agentID string
+		input   map[string]any
+		message string
+	}{
+		{
+			name:    "executor failing",
+			agentID: readyAgentID,
+			input:   map[string]any{failInputKey: true},
+			message: "execution failed: the model gave up",
+		},
+		{
+			name:    "agent not ready",
+			agentID: pendingAgentID,
+			message: "agent not ready: " + string(models.AgentStatusPending),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectID := createProject(t, "failures")
+			request := taskRequest(projectID, tt.agentID, nil, tt.input)
+
+			status, body := call(t, developerToken, http.MethodPost, "/api/v1/projects/"+projectID+"/tasks/execute", models.ExecuteTaskRequest{
+				ProjectID:   request.ProjectID,
+				AgentID:     request.AgentID,
+				Type:        request.Type,
+				Title:       request.Title,
+				Description: request.Description,
+				Input:       request.Input,
+			})
+			assert.Equal(t, http.StatusInternalServerError, status, string(body))
+
+			status, body = call(t, developerToken, http.MethodGet, "/api/v1/projects/"+projectID+"/tasks", nil)
+			require.Equal(t, http.StatusOK, status, string(body))
+			tasks := decode[models.ListTasksResponse](t, body).Tasks
+			require.Len(t, tasks, 1)
+			assert.Equal(t, models.TaskStatusFailed, tasks[0].Status)
+			require.NotNil(t, tasks[0].ErrorMessage)
+			assert.Equal(t, tt.message, *tasks[0].ErrorMessage)
+		})
+	}
+}
+
+// call sends a request with a JSON body, when body is set, to the API as the user of token, or anonymously when
+// token is empty, and returns the status and body of the response
+func call(t *testing.T, token, method, path string, body any) (int, []byte) {
+	t.Helper()
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		require.NoError(t, err)
+		reader = bytes.NewReader(payload)
+	}
+
+	request, err := http.NewRequest(method, server.URL+path, reader)
+	require.NoError(t, err)
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	return send(t, token, request)
+}
+
+// send sends a request as the user of token and returns the status and body of the response
+func send(t *testing.T, token string, request *http.Request) (int, []byte) {
+	t.Helper()
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	response, err := server.Client().Do(request)
+	require.NoError(t, err)
+	defer func() { _ = response.Body.Close() }()
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	return response.StatusCode, body
+}
+
+// decode unmarshals a JSON response body
+func decode[T any](t *testing.T, body []byte) T {
+	t.Helper()
+	var value T
+	require.NoError(t, json.Unmarshal(body, &value), string(body))
+	return value
+}
+
+// assertProblem asserts a response is a problem with a status and, when code is set, an error code
+func assertProblem(t *testing.T, wantStatus int, wantCode string, status int, body []byte) {
+	t.Helper()
+	require.Equal(t, wantStatus, status, string(body))
+	problem := decode[models.ProblemDetails](t, body)
+	assert.Equal(t, wantStatus, problem.Status)
+	if wantCode != "" {
+		assert.Equal(t, wantCode, problem.Code)
+	}
+}
+
+// createProject creates a project owned by the developer
+func createProject(t *testing.T, name string) string {
+	t.Helper()
+	status, body := call(t, developerToken, http.MethodPost, "/api/v1/projects", models.CreateProjectRequest{Name: name})
+	require.Equal(t, http.StatusCreated, status, string(body))
+	return decode[models.CreateProjectResponse](t, body).ProjectID
+}
+
+// createCodebase creates a GitHub codebase of a project
+func createCodebase(t *testing.T, projectID string) string {
+	t.Helper()
+	status, body := call(t, developerToken, http.MethodPost, "/api/v1/projects/"+projectID+"/codebases", codebaseRequest())
+	require.Equal(t, http.StatusCreated, status, string(body))
+	return decode[models.CreateCodebaseResponse](t, body).CodebaseID
+}
+
+// codebaseRequest returns the body creating a GitHub codebase
+func codebaseRequest() map[string]any {
+	return map[string]any{
+		"name":      "repo",
+		"provider":  models.ProviderGitHub,
+		"url":       "https://github.com/org/repo",
+		"config_id": "config-1",
+	}
+}
+
+// uploadTaskInput uploads a file for the tasks of a project
+func uploadTaskInput(t *testing.T, projectID, filename, content string) models.TaskInputUpload {
+	t.Helper()
+	var form bytes.Buffer
+	writer := multipart.NewWriter(&form)
+	part, err := writer.CreateFormFile(models.UploadFormField, filename)
+	require.NoError(t, err)
+	_, err = io.WriteString(part, content)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	request, err := http.NewRequest(http.MethodPost, server.URL+"/api/v1/projects/"+projectID+"/uploads", &form)
+	require.NoError(t, err)
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+	status, body := send(t, developerToken, request)
+	require.Equal(t, http.StatusCreated, status, string(body))
+	return decode[models.TaskInputUpload](t, body)
+}
+
+// taskRequest returns the request creating a refactoring task of a project
+func taskRequest(projectID, agentID string, codebaseID *string, input map[string]any) models.CreateTaskRequest {
+	return models.CreateTaskRequest{
+		ProjectID:   projectID,
+		AgentID:     agentID,
+		CodebaseID:  codebaseID,
+		Type:        models.TaskTypeRefactoring,
+		Title:       "Extract the retry loop",
+		Description: "Extract the retry loop into a helper",
+		Input:       input,
+	}
+}
+
+// taskIDs returns the IDs of tasks
+func taskIDs(tasks []models.Task) []string {
+	ids := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.TaskID)
+	}
+	return ids
+}
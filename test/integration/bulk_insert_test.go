@@ -0,0 +1,48 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// BenchmarkScanFindingBulkInsert replaces the 2,000 scan findings of a codebase in the Postgres container, writing
+// them one row per statement and in batches. Run it with
+//
+//	go test -tags integration -run '^$' -bench ScanFindingBulkInsert ./test/integration/...
+func BenchmarkScanFindingBulkInsert(b *testing.B) {
+	ctx := context.Background()
+	findings := make([]models.ScanFinding, 2000)
+	for i := range findings {
+		line := i + 1
+		findings[i] = models.ScanFinding{
+			FindingID:  fmt.Sprintf("finding-bench-%d", i),
+			Kind:       models.ScanFindingKindSecret,
+			Rule:       "aws-access-key-id",
+			Severity:   models.ScanFindingSeverityHigh,
+			FilePath:   "config/prod.env",
+			Line:       &line,
+			Message:    "Committed AWS access key ID",
+			CommitSHA:  "abc123",
+			DetectedAt: time.Now(),
+		}
+	}
+
+	for _, batchSize := range []int{1, 100, repository.DefaultBulkInsertBatchSize} {
+		b.Run(fmt.Sprintf("batch_size_%d", batchSize), func(b *testing.B) {
+			repo := repository.NewPostgresScanFindingRepositoryWithDB(database, config.DefaultScanFindingsTableName, batchSize)
+			for b.Loop() {
+				if err := repo.ReplaceFindings(ctx, "codebase-bench", findings); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
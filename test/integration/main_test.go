@@ -0,0 +1,232 @@
+//go:build integration
+
+// Package integration runs the API end to end: the Gin router of the service, with its real repositories on a
+// Postgres container and its S3 and Secrets Manager clients on a localstack container, both started with dockertest.
+// Tasks run on a fake executor in place of the AI models.
+//
+// The suite needs a Docker daemon and runs with
+//
+//	go test -tags integration ./test/integration/...
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/ory/dockertest/v3"
+	"github.com/ory/dockertest/v3/docker"
+
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+)
+
+// Containers the suite runs against
+const (
+	postgresImage   = "postgres"
+	postgresTag     = "16-alpine"
+	localstackImage = "localstack/localstack"
+	localstackTag   = "3"
+)
+
+// Postgres database and credentials, which the suite stores in a Secrets Manager secret and reads back like the
+// service does
+const (
+	postgresDatabase = "code_refactoring_db"
+	postgresUsername = "postgres"
+	postgresPassword = "integration"
+)
+
+// uploadsBucket is the localstack bucket task inputs are uploaded to
+const uploadsBucket = "code-refactor-integration"
+
+// containerExpiry is how long Docker keeps the containers of a suite that didn't get to remove them
+const containerExpiry = 10 * time.Minute
+
+// server is the API the tests send requests to, started by TestMain
+var server *testServer
+
+// database is the Postgres database of the API, for the benchmarks writing to it directly
+var database *sql.DB
+
+func TestMain(m *testing.M) {
+	code, err := run(m)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "integration:", err)
+		os.Exit(1)
+	}
+	os.Exit(code)
+}
+
+// run starts the containers and the API, runs the tests, then removes the containers
+func run(m *testing.M) (int, error) {
+	ctx := context.Background()
+
+	pool, err := dockertest.NewPool("")
+	if err != nil {
+		return 0, fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+	pool.MaxWait = 2 * time.Minute
+
+	postgres, err := startPostgres(pool)
+	if postgres != nil {
+		defer purge(pool, postgres)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	localstack, awsConfig, err := startLocalstack(ctx, pool)
+	if localstack != nil {
+		defer purge(pool, localstack)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	cfg, err := loadConfig(ctx, awsConfig, postgres)
+	if err != nil {
+		return 0, err
+	}
+
+	database, err = sql.Open("postgres", postgresDSN(postgres))
+	if err != nil {
+		return 0, fmt.Errorf("failed to open Postgres: %w", err)
+	}
+	defer func() { _ = database.Close() }()
+
+	server, err = newTestServer(ctx, cfg)
+	if err != nil {
+		return 0, err
+	}
+	defer server.Close()
+
+	return m.Run(), nil
+}
+
+// startPostgres starts a Postgres container and waits until it accepts connections
+func startPostgres(pool *dockertest.Pool) (*dockertest.Resource, error) {
+	resource, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Repository: postgresImage,
+		Tag:        postgresTag,
+		Env: []string{
+			"POSTGRES_DB=" + postgresDatabase,
+			"POSTGRES_USER=" + postgresUsername,
+			"POSTGRES_PASSWORD=" + postgresPassword,
+		},
+	}, autoRemove)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start Postgres: %w", err)
+	}
+	_ = resource.Expire(uint(containerExpiry.Seconds()))
+
+	err = pool.Retry(func() error {
+		db, err := sql.Open("postgres", postgresDSN(resource))
+		if err != nil {
+			return err
+		}
+		defer func() { _ = db.Close() }()
+		return db.Ping()
+	})
+	if err != nil {
+		return resource, fmt.Errorf("postgres didn't accept connections: %w", err)
+	}
+	return resource, nil
+}
+
+// postgresDSN is the connection string of the database of a Postgres container
+func postgresDSN(resource *dockertest.Resource) string {
+	return fmt.Sprintf("host=127.0.0.1 port=%s user=%s password=%s dbname=%s sslmode=disable",
+		resource.GetPort("5432/tcp"), postgresUsername, postgresPassword, postgresDatabase)
+}
+
+// startLocalstack starts a localstack container serving S3 and Secrets Manager, waits until it creates the uploads
+// bucket, and returns the AWS configuration of its endpoint
+func startLocalstack(ctx context.Context, pool *dockertest.Pool) (*dockertest.Resource, aws.Config, error) {
+	resource, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Repository: localstackImage,
+		Tag:        localstackTag,
+		Env:        []string{"SERVICES=s3,secretsmanager"},
+	}, autoRemove)
+	if err != nil {
+		return nil, aws.Config{}, fmt.Errorf("failed to start localstack: %w", err)
+	}
+	_ = resource.Expire(uint(containerExpiry.Seconds()))
+
+	// An IP address, rather than localhost, makes S3 address buckets by path
+	awsConfig := aws.Config{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String("http://127.0.0.1:" + resource.GetPort("4566/tcp")),
+		Credentials: aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: "test", SecretAccessKey: "test"}, nil
+		}),
+	}
+	err = pool.Retry(func() error {
+		_, err := s3.NewFromConfig(awsConfig).CreateBucket(ctx, &s3.CreateBucketInput{Bucket: aws.String(uploadsBucket)})
+		return err
+	})
+	if err != nil {
+		return resource, aws.Config{}, fmt.Errorf("localstack didn't create the uploads bucket: %w", err)
+	}
+	return resource, awsConfig, nil
+}
+
+// loadConfig configures the service for the containers. The database credentials are stored in a Secrets Manager
+// secret and loaded from it, like the service loads those of its RDS database.
+func loadConfig(ctx context.Context, awsConfig aws.Config, postgres *dockertest.Resource) (config.Config, error) {
+	var cfg config.Config
+	if err := processDefaults(&cfg); err != nil {
+		return cfg, err
+	}
+	cfg.AWSConfig = awsConfig
+	cfg.Uploads.Bucket = uploadsBucket
+	cfg.Postgres.SSLMode = "disable"
+
+	var port int
+	if _, err := fmt.Sscan(postgres.GetPort("5432/tcp"), &port); err != nil {
+		return cfg, fmt.Errorf("invalid Postgres port: %w", err)
+	}
+	secret, err := json.Marshal(config.DatabaseSecret{
+		Username: postgresUsername,
+		Password: postgresPassword,
+		Engine:   "postgres",
+		Host:     "127.0.0.1",
+		Port:     port,
+		DbName:   postgresDatabase,
+	})
+	if err != nil {
+		return cfg, err
+	}
+	created, err := secretsmanager.NewFromConfig(awsConfig).CreateSecret(ctx, &secretsmanager.CreateSecretInput{
+		Name:         aws.String("code-refactor-integration/database"),
+		SecretString: aws.String(string(secret)),
+	})
+	if err != nil {
+		return cfg, fmt.Errorf("failed to store the database credentials: %w", err)
+	}
+
+	loader := config.NewLoader(nil, config.NewSecretsManagerClient(awsConfig), nil)
+	if err := loader.LoadDatabaseCredentials(ctx, aws.ToString(created.ARN), &cfg); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// autoRemove has Docker remove the containers once they stop
+func autoRemove(hostConfig *docker.HostConfig) {
+	hostConfig.AutoRemove = true
+	hostConfig.RestartPolicy = docker.RestartPolicy{Name: "no"}
+}
+
+// purge removes a container, reporting failures since the container then runs until its expiry
+func purge(pool *dockertest.Pool, resource *dockertest.Resource) {
+	if err := pool.Purge(resource); err != nil {
+		fmt.Fprintf(os.Stderr, "integration: failed to remove container %s: %v\n", resource.Container.Name, err)
+	}
+}
@@ -0,0 +1,243 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kelseyhightower/envconfig"
+
+	"github.com/kazemisoroush/code-refactoring-tool/api/controllers"
+	"github.com/kazemisoroush/code-refactoring-tool/api/middleware"
+	"github.com/kazemisoroush/code-refactoring-tool/api/models"
+	"github.com/kazemisoroush/code-refactoring-tool/api/repository"
+	"github.com/kazemisoroush/code-refactoring-tool/api/routes"
+	"github.com/kazemisoroush/code-refactoring-tool/api/services"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/auth"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/config"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/objectstore"
+	"github.com/kazemisoroush/code-refactoring-tool/pkg/workflow"
+)
+
+// Bearer tokens of the seeded users, a developer and a viewer
+const (
+	developerToken = "developer-token"
+	viewerToken    = "viewer-token"
+)
+
+// Seeded agents, one ready to execute tasks and one still being set up
+const (
+	readyAgentID   = "agent-integration-ready"
+	pendingAgentID = "agent-integration-pending"
+)
+
+// failInputKey is the task input making the fake executor fail the task
+const failInputKey = "fail"
+
+// testServer serves the API over HTTP
+type testServer struct {
+	*httptest.Server
+}
+
+// newTestServer builds the router of the service like cmd/api does, on the Postgres repositories and the S3 object
+// store of cfg, and seeds the users and agents the tests act with. The routes of every controller are mounted; those
+// outside projects, codebases, tasks and uploads have no controller behind them.
+func newTestServer(ctx context.Context, cfg config.Config) (*testServer, error) {
+	postgresConfig := repository.PostgresConfig{
+		Host:     cfg.Postgres.Host,
+		Port:     cfg.Postgres.Port,
+		Database: cfg.Postgres.Database,
+		Username: cfg.Postgres.Username,
+		Password: cfg.Postgres.Password,
+		SSLMode:  cfg.Postgres.SSLMode,
+	}
+
+	if err := repository.MigratePostgres(ctx, postgresConfig); err != nil {
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	}
+	projectRepository, err := repository.NewPostgresProjectRepository(postgresConfig, config.DefaultProjectsTableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize project repository: %w", err)
+	}
+	codebaseRepository, err := repository.NewPostgresCodebaseRepository(postgresConfig, config.DefaultCodebasesTableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize codebase repository: %w", err)
+	}
+	taskRepository, err := repository.NewPostgresTaskRepository(postgresConfig, config.DefaultTasksTableName, config.DefaultNotificationOutboxTableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize task repository: %w", err)
+	}
+	userRepository, err := repository.NewPostgresUserRepository(postgresConfig, config.DefaultUsersTableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize user repository: %w", err)
+	}
+	agentRepository, err := repository.NewPostgresAgentRepository(postgresConfig, config.DefaultAgentsTableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize agent repository: %w", err)
+	}
+	roleRepository, err := repository.NewPostgresRoleRepository(postgresConfig,
+		config.DefaultRolesTableName, config.DefaultPermissionsTableName,
+		config.DefaultRolePermissionsTableName, config.DefaultProjectMembersTableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize role repository: %w", err)
+	}
+	auditEventRepository, err := repository.NewPostgresAuditEventRepository(postgresConfig, config.DefaultAuditEventsTableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize audit event repository: %w", err)
+	}
+	tagKeyRepository, err := repository.NewPostgresTagKeyRepository(postgresConfig, config.DefaultTagKeysTableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize tag key repository: %w", err)
+	}
+	clientTokenRepository, err := repository.NewPostgresClientTokenRepository(postgresConfig, config.DefaultClientTokensTableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize client token repository: %w", err)
+	}
+	workflowRunRepository, err := repository.NewPostgresWorkflowRunRepository(postgresConfig, config.DefaultWorkflowRunsTableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize workflow run repository: %w", err)
+	}
+
+	if err := seed(ctx, userRepository, agentRepository); err != nil {
+		return nil, err
+	}
+
+	uploadStore := objectstore.NewS3ObjectStore(cfg.AWSConfig, cfg.UploadsBucket())
+	tagService := services.NewDefaultTagService(tagKeyRepository, userRepository, cfg.Tags.RequireRegisteredKeys)
+	roleService := services.NewDefaultRoleService(roleRepository, userRepository, projectRepository, auditEventRepository)
+	projectService := services.NewDefaultProjectService(projectRepository, agentRepository, nil, tagService, roleService, clientTokenRepository)
+	codebaseService := services.NewDefaultCodebaseService(codebaseRepository, tagService, clientTokenRepository, uploadStore)
+	uploadService := services.NewDefaultUploadService(uploadStore, cfg.Uploads)
+
+	// Every task type runs on the fake executor
+	taskExecutors := services.NewTaskExecutorRegistry(nil)
+	if err := taskExecutors.Register(fakeExecutor{}); err != nil {
+		return nil, err
+	}
+	taskService := services.NewTaskService(
+		taskRepository,
+		projectRepository,
+		agentRepository,
+		codebaseRepository,
+		roleService,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		uploadService,
+		taskExecutors,
+		workflow.NewEngine(workflowRunRepository, cfg.Workflow.RetryBackoff),
+		cfg.Task,
+	)
+
+	authMiddleware := middleware.NewAuthMiddleware(fakeTokenValidator{}, nil, cfg.Auth)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.Use(middleware.NewRequestIDMiddleware().Handle())
+	router.Use(middleware.NewErrorHandlerMiddleware().Handle())
+	router.Use(middleware.NewTimeoutMiddleware(cfg.HTTP).Handle())
+	router.Use(middleware.NewBodyLimitMiddleware(cfg.HTTP).Handle())
+	router.Use(authMiddleware.Handle())
+
+	routes.Mount(routes.NewVersionedRouterFromConfig(router, cfg.API), routes.Controllers{
+		Project:  controllers.NewProjectController(projectService),
+		Codebase: controllers.NewCodebaseController(codebaseService, nil, nil, nil, nil, nil),
+		Task:     controllers.NewTaskController(taskService, nil, nil, nil),
+		Upload:   controllers.NewUploadController(uploadService),
+	}, roleService, authMiddleware)
+
+	return &testServer{Server: httptest.NewServer(router)}, nil
+}
+
+// processDefaults sets the defaults of the settings the router reads, taking the environment into account
+func processDefaults(cfg *config.Config) error {
+	for prefix, spec := range map[string]any{
+		"API":      &cfg.API,
+		"HTTP":     &cfg.HTTP,
+		"TASK":     &cfg.Task,
+		"TAGS":     &cfg.Tags,
+		"UPLOADS":  &cfg.Uploads,
+		"WORKFLOW": &cfg.Workflow,
+		"AUTH":     &cfg.Auth,
+	} {
+		if err := envconfig.Process(prefix, spec); err != nil {
+			return fmt.Errorf("failed to load %s settings: %w", prefix, err)
+		}
+	}
+	return nil
+}
+
+// seed creates the users the bearer tokens authenticate and the agents tasks are executed by
+func seed(ctx context.Context, users repository.UserRepository, agents repository.AgentRepository) error {
+	for token, role := range map[string]models.UserRole{developerToken: models.RoleDeveloper, viewerToken: models.RoleViewer} {
+		_, err := users.CreateUser(ctx, &models.DBUser{
+			AuthID:   authID(token),
+			Email:    string(role) + "@example.com",
+			Username: string(role),
+			Role:     role,
+			Status:   models.UserStatusActive,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to seed user %s: %w", role, err)
+		}
+	}
+
+	now := time.Now().UTC()
+	for agentID, status := range map[string]models.AgentStatus{readyAgentID: models.AgentStatusReady, pendingAgentID: models.AgentStatusPending} {
+		err := agents.CreateAgent(ctx, &repository.AgentRecord{
+			AgentID:       agentID,
+			AgentVersion:  "1",
+			AgentName:     agentID,
+			RepositoryURL: "https://github.com/org/repo",
+			Status:        string(status),
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to seed agent %s: %w", agentID, err)
+		}
+	}
+	return nil
+}
+
+// authID returns the auth provider ID of the user a bearer token authenticates
+func authID(token string) string {
+	return "auth-" + token
+}
+
+// fakeTokenValidator stands in for Cognito, authenticating the bearer tokens of the seeded users
+type fakeTokenValidator struct {
+	auth.AuthProvider
+}
+
+func (fakeTokenValidator) ValidateToken(_ context.Context, token string) (*auth.TokenClaims, error) {
+	if token != developerToken && token != viewerToken {
+		return nil, errors.New("unknown token")
+	}
+	now := time.Now()
+	return &auth.TokenClaims{UserID: authID(token), Username: token, IssuedAt: now, ExpiresAt: now.Add(time.Hour)}, nil
+}
+
+// fakeExecutor stands in for the AI executors, answering every task with a summary of it along with the upload it
+// was given. Tasks whose input sets failInputKey fail instead.
+type fakeExecutor struct{}
+
+func (fakeExecutor) Name() string { return "fake" }
+
+func (fakeExecutor) Supports(models.TaskType) bool { return true }
+
+func (fakeExecutor) Execute(_ context.Context, task *models.TaskWithFullContext) (map[string]any, error) {
+	if fail, _ := task.Input[failInputKey].(bool); fail {
+		return map[string]any{"steps": 1}, errors.New("the model gave up")
+	}
+
+	output := map[string]any{"summary": "executed " + task.Title}
+	if upload, ok := task.Input[models.TaskInputUploadKey]; ok {
+		output[models.TaskInputUploadKey] = upload
+	}
+	return output, nil
+}